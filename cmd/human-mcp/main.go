@@ -9,6 +9,7 @@ import (
 
 	"github.com/barun-bash/human/internal/cli"
 	"github.com/barun-bash/human/internal/mcp"
+	"github.com/barun-bash/human/internal/version"
 )
 
 //go:embed embedded/LANGUAGE_SPEC.md
@@ -18,6 +19,11 @@ var languageSpec string
 var examplesFS embed.FS
 
 func main() {
+	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-v") {
+		fmt.Printf("human-mcp %s\n", version.Info())
+		return
+	}
+
 	// Disable ANSI colors — MCP uses stdio for JSON-RPC, not terminal output
 	cli.ColorEnabled = false
 
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/barun-bash/human/internal/selfcheck"
+)
+
+// TestEmbeddedExamplesCompile checks the examples served as MCP resources,
+// as embedded by `make mcp-embed`.
+func TestEmbeddedExamplesCompile(t *testing.T) {
+	examples, err := fs.Sub(examplesFS, "embedded/examples")
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, count, err := selfcheck.CheckExamples(examples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Error(p)
+	}
+	if count == 0 {
+		t.Error("no examples embedded")
+	}
+}
@@ -5,34 +5,50 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"log"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/barun-bash/human/internal/build"
 	"github.com/barun-bash/human/internal/cli"
 	"github.com/barun-bash/human/internal/cmdutil"
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/codegen/hosting"
+	"github.com/barun-bash/human/internal/codegen/sdk"
 	"github.com/barun-bash/human/internal/config"
 	"github.com/barun-bash/human/internal/editor"
 	"github.com/barun-bash/human/internal/figma"
 	"github.com/barun-bash/human/internal/fixer"
+	"github.com/barun-bash/human/internal/fixtures"
 	"github.com/barun-bash/human/internal/git"
-	"github.com/barun-bash/human/internal/openapi"
 	"github.com/barun-bash/human/internal/ir"
-	"github.com/barun-bash/human/internal/parser"
+	"github.com/barun-bash/human/internal/lint"
 	"github.com/barun-bash/human/internal/llm"
-	"github.com/barun-bash/human/internal/plugin"
 	_ "github.com/barun-bash/human/internal/llm/providers" // register providers
+	"github.com/barun-bash/human/internal/mock"
+	"github.com/barun-bash/human/internal/openapi"
+	"github.com/barun-bash/human/internal/parser"
+	"github.com/barun-bash/human/internal/plugin"
+	"github.com/barun-bash/human/internal/quality"
 	"github.com/barun-bash/human/internal/repl"
+	"github.com/barun-bash/human/internal/replay"
+	"github.com/barun-bash/human/internal/serve"
+	"github.com/barun-bash/human/internal/simulate"
 	"github.com/barun-bash/human/internal/version"
 )
 
 func main() {
-	// Parse global --no-color flag before command dispatch
+	// Parse global flags before command dispatch. Commands read os.Args,
+	// so the flags are dropped from it too.
 	args := filterGlobalFlags(os.Args[1:])
+	os.Args = append(os.Args[:1], args...)
+	cmdutil.TrustPrompt = cmdutil.TerminalPrompt(os.Stdin, os.Stdout)
 
 	if len(args) < 1 {
 		r := repl.New(version.Version)
@@ -47,8 +63,16 @@ func main() {
 		printUsage()
 	case "check":
 		cmdCheck()
+	case "lint":
+		cmdLint()
 	case "build":
 		cmdBuild()
+	case "diff":
+		cmdDiff()
+	case "compare":
+		cmdCompare()
+	case "simulate":
+		cmdSimulate()
 	case "init":
 		cmdInit()
 	case "run":
@@ -61,6 +85,8 @@ func main() {
 		cmdDeploy()
 	case "eject":
 		cmdEject()
+	case "verify":
+		cmdVerify()
 	case "ask":
 		cmdAsk()
 	case "suggest":
@@ -83,31 +109,77 @@ func main() {
 		cmdStorybook()
 	case "explain":
 		cmdExplainCLI()
+	case "learn":
+		cmdLearn()
+	case "repl":
+		repl.New(version.Version).RunSnippets()
 	case "syntax":
 		cmdSyntaxCLI()
+	case "snippet":
+		cmdSnippetCLI()
 	case "fix":
 		cmdFixCLI()
 	case "doctor":
-		cmdutil.RunDoctor(os.Stdout)
+		if cmdutil.RunDoctor(os.Stdout) > 0 {
+			os.Exit(1)
+		}
+	case "selfcheck":
+		cmdSelfcheck()
 	case "split":
 		cmdSplit()
 	case "plugin":
 		cmdPlugin()
+	case "mock":
+		cmdMock()
+	case "serve":
+		cmdServe()
+	case "sdk":
+		cmdSDK()
+	case "replay":
+		cmdReplay()
+	case "self-update":
+		cmdSelfUpdate()
+	case "trust":
+		cmdTrust()
+	case "db":
+		cmdDB()
+	case "seed":
+		cmdSeed()
+	case "migrate":
+		cmdMigrate()
 	default:
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Unknown command: %s", args[0])))
+		if cmd, err := cmdutil.LookupProjectCommand(".", args[0]); err == nil && cmd != nil {
+			if err := cmdutil.RunProjectCommand(".", args[0], cmd, args[1:], os.Stdout); err != nil {
+				cli.Errorln(err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+		cli.Errorln(fmt.Sprintf("Unknown command: %s", args[0]))
 		fmt.Fprintln(os.Stderr)
 		printUsage()
 		os.Exit(1)
 	}
 }
 
-// filterGlobalFlags strips --no-color from the args list and applies it.
+// filterGlobalFlags strips --no-color, --verbose, --quiet, --offline, and
+// --clean-env from the args list and applies them.
 func filterGlobalFlags(args []string) []string {
 	var filtered []string
 	for _, arg := range args {
-		if arg == "--no-color" {
+		switch arg {
+		case "--no-color":
 			cli.ColorEnabled = false
-		} else {
+		case "--verbose":
+			cli.LogLevel = cli.LevelVerbose
+		case "--quiet", "-q":
+			cli.LogLevel = cli.LevelQuiet
+			cli.ColorEnabled = false
+		case "--offline":
+			config.OfflineMode = true
+		case "--clean-env":
+			cmdutil.CleanEnvMode = true
+		default:
 			filtered = append(filtered, arg)
 		}
 	}
@@ -125,7 +197,7 @@ func cmdCheck() {
 
 	result, err := cmdutil.ParseAndAnalyze(file)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
@@ -134,7 +206,53 @@ func cmdCheck() {
 		os.Exit(1)
 	}
 
-	fmt.Println(cli.Success(cmdutil.CheckSummary(result.Prog, file)))
+	cli.Println(cli.Success(cmdutil.CheckSummary(result.Prog, file)))
+}
+
+// ── lint ──
+
+func cmdLint() {
+	const usage = "Usage: human lint [--rules] <file.human | directory>"
+	listRules := false
+	var file string
+	for _, arg := range os.Args[2:] {
+		switch arg {
+		case "--rules":
+			listRules = true
+		default:
+			if !strings.HasPrefix(arg, "-") {
+				file = arg
+			}
+		}
+	}
+
+	if listRules {
+		dir := "."
+		if file != "" {
+			dir = file
+		}
+		cfg, err := lint.LoadConfig(dir)
+		if err != nil {
+			cli.Errorln(err.Error())
+			os.Exit(1)
+		}
+		cmdutil.PrintLintRules(os.Stdout, cfg)
+		return
+	}
+	if file == "" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	findings, _, err := cmdutil.Lint(file)
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	cmdutil.PrintLint(os.Stdout, findings)
+	if lint.HasErrors(findings) {
+		os.Exit(1)
+	}
 }
 
 // ── build ──
@@ -143,37 +261,77 @@ func cmdBuild() {
 	// Parse flags
 	inspect := false
 	watch := false
+	serve := false
 	timing := false
+	noCache := false
+	diagramsOnly := false
+	envName := ""
 	var file string
-	for _, arg := range os.Args[2:] {
-		switch arg {
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
 		case "--inspect":
 			inspect = true
+		case "--no-cache":
+			noCache = true
+		case "--diagrams":
+			diagramsOnly = true
 		case "--watch", "-w":
 			watch = true
+		case "--serve":
+			serve = true
 		case "--timing":
 			timing = true
+		case "--ci":
+			cmdutil.CIBuild = true
+		case "--env", "-e":
+			if i+1 < len(args) {
+				i++
+				envName = args[i]
+			} else {
+				cli.Errorln("--env requires a value (e.g. --env production)")
+				os.Exit(1)
+			}
 		default:
-			if !strings.HasPrefix(arg, "-") {
-				file = arg
+			if !strings.HasPrefix(args[i], "-") {
+				file = args[i]
 			}
 		}
 	}
 
 	if file == "" {
-		fmt.Fprintln(os.Stderr, "Usage: human build [--inspect] [--watch] [--timing] <file.human | directory>")
+		fmt.Fprintln(os.Stderr, "Usage: human build [--inspect] [--watch [--serve]] [--env <name>] [--timing] [--no-cache] [--ci] [--diagrams] <file.human | directory>")
+		os.Exit(1)
+	}
+	if serve && !watch {
+		cli.Errorln("--serve restarts the dev server on each rebuild, so it needs --watch")
 		os.Exit(1)
 	}
 
+	if noCache {
+		if err := build.ClearCache(build.CacheDir); err != nil {
+			cli.Errorln(err.Error())
+			os.Exit(1)
+		}
+	}
+
 	if watch {
-		cmdBuildWatch(file)
+		cmdBuildWatch(file, envName, serve)
+		return
+	}
+
+	if diagramsOnly {
+		if err := cmdutil.BuildDiagrams(file); err != nil {
+			cli.Errorln(err.Error())
+			os.Exit(1)
+		}
 		return
 	}
 
 	if inspect {
-		result, err := cmdutil.ParseAndAnalyze(file)
+		result, err := cmdutil.ParseAndAnalyzeEnv(file, envName)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+			cli.Errorln(err.Error())
 			os.Exit(1)
 		}
 		if cmdutil.PrintDiagnostics(result.Errs) {
@@ -182,7 +340,7 @@ func cmdBuild() {
 		}
 		yaml, err := ir.ToYAML(result.App)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Serialization error: %v", err)))
+			cli.Errorln(fmt.Sprintf("Serialization error: %v", err))
 			os.Exit(1)
 		}
 		fmt.Print(yaml)
@@ -190,29 +348,41 @@ func cmdBuild() {
 	}
 
 	if timing {
-		_, results, _, bt, err := cmdutil.FullBuild(file)
+		_, results, _, bt, err := fullBuild(file, envName)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+			cli.Errorln(err.Error())
 			os.Exit(1)
 		}
 		cmdutil.PrintBuildSummaryTiming(results, filepath.Join(".human", "output"), bt)
 	} else {
-		if _, _, _, _, err := cmdutil.FullBuild(file); err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		if _, _, _, _, err := fullBuild(file, envName); err != nil {
+			cli.Errorln(err.Error())
 			os.Exit(1)
 		}
 	}
 }
 
+// fullBuild runs a full build for the environment called env, drawing a
+// progress box on interactive terminals at the normal output level.
+func fullBuild(file, env string) (*ir.Application, []build.Result, *quality.Result, *build.BuildTiming, error) {
+	if cli.LogLevel == cli.LevelNormal && cli.IsTTY(os.Stdout) {
+		return cmdutil.FullBuildWithProgressBox(file, env, os.Stdout)
+	}
+	return cmdutil.FullBuild(file, env)
+}
+
 // ── init ──
 
 func cmdInit() {
 	name := ""
 	multi := false
+	yes := false
 	for _, arg := range os.Args[2:] {
 		switch arg {
 		case "--multi", "-m":
 			multi = true
+		case "--yes", "-y":
+			yes = true
 		default:
 			if !strings.HasPrefix(arg, "-") && name == "" {
 				name = arg
@@ -220,9 +390,9 @@ func cmdInit() {
 		}
 	}
 
-	outPath, err := cmdutil.InitProject(name, multi, os.Stdin, os.Stdout)
+	outPath, err := cmdutil.InitProject(name, multi, yes, os.Stdin, os.Stdout)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
@@ -230,7 +400,7 @@ func cmdInit() {
 	if multi {
 		checkTarget = filepath.Dir(outPath)
 	}
-	fmt.Println(cli.Success(fmt.Sprintf("Created %s — run 'human check %s' to validate, 'human build %s' to compile", outPath, checkTarget, checkTarget)))
+	cli.Println(cli.Success(fmt.Sprintf("Created %s — run 'human check %s' to validate, 'human build %s' to compile", outPath, checkTarget, checkTarget)))
 }
 
 // ── split ──
@@ -249,7 +419,7 @@ func cmdSplit() {
 				i++
 				output = args[i]
 			} else {
-				fmt.Fprintln(os.Stderr, cli.Error("--output requires a directory path"))
+				cli.Errorln("--output requires a directory path")
 				os.Exit(1)
 			}
 		default:
@@ -266,24 +436,24 @@ func cmdSplit() {
 
 	source, err := os.ReadFile(file)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Error reading %s: %v", file, err)))
+		cli.Errorln(fmt.Sprintf("Error reading %s: %v", file, err))
 		os.Exit(1)
 	}
 
 	prog, err := parser.Parse(string(source))
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Parse error: %v", err)))
+		cli.Errorln(fmt.Sprintf("Parse error: %v", err))
 		os.Exit(1)
 	}
 
 	if prog.App == nil {
-		fmt.Fprintln(os.Stderr, cli.Error("No app declaration found. Cannot split a file without an app declaration."))
+		cli.Errorln("No app declaration found. Cannot split a file without an app declaration.")
 		os.Exit(1)
 	}
 
 	if dryRun {
 		files := cmdutil.SplitProgram(prog)
-		fmt.Println(cli.Info("Dry run — would create:"))
+		cli.Println(cli.Info("Dry run — would create:"))
 		for name, content := range files {
 			lines := strings.Count(content, "\n")
 			fmt.Printf("  %-25s %d lines\n", name, lines)
@@ -298,11 +468,11 @@ func cmdSplit() {
 
 	created, err := cmdutil.SplitToDir(prog, output)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Split failed: %v", err)))
+		cli.Errorln(fmt.Sprintf("Split failed: %v", err))
 		os.Exit(1)
 	}
 
-	fmt.Println(cli.Success(fmt.Sprintf("Split into %d files in %s/", len(created), output)))
+	cli.Println(cli.Success(fmt.Sprintf("Split into %d files in %s/", len(created), output)))
 	for _, f := range created {
 		fmt.Printf("  %s\n", filepath.Base(f))
 	}
@@ -311,48 +481,184 @@ func cmdSplit() {
 // ── run ──
 
 func cmdRun() {
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--chaos":
+			os.Setenv(ir.ChaosEnv, "1")
+		case "--chaos-rate":
+			if i+1 >= len(args) {
+				cli.Errorln("--chaos-rate requires a value (e.g. --chaos-rate 0.3)")
+				os.Exit(1)
+			}
+			i++
+			rate, err := strconv.ParseFloat(args[i], 64)
+			if err != nil || rate <= 0 || rate > 1 {
+				cli.Errorln("--chaos-rate must be a number between 0 and 1")
+				os.Exit(1)
+			}
+			os.Setenv(ir.ChaosEnv, "1")
+			os.Setenv(ir.ChaosRateEnv, args[i])
+		case "--record":
+			// The backends run from their own directory, so it's absolute
+			dir, err := filepath.Abs(debugDir)
+			if err != nil {
+				cli.Errorln(err.Error())
+				os.Exit(1)
+			}
+			os.Setenv(ir.RecordEnv, dir)
+		default:
+			cli.Errorln(fmt.Sprintf("Unknown flag: %s", args[i]))
+			fmt.Fprintln(os.Stderr, "Usage: human run [--chaos] [--chaos-rate <0-1>] [--record]")
+			os.Exit(1)
+		}
+	}
+
+	requireTrust("run the generated app")
 	outputDir := filepath.Join(".human", "output")
+	// The generated backends read chaos mode from the environment, which
+	// is passed on to them (HUMAN_* survives --clean-env).
+	if os.Getenv(ir.ChaosEnv) == "1" {
+		cli.Println(cli.Warn("Chaos mode: API requests will fail the way your error handlers expect."))
+	}
+	if os.Getenv(ir.RecordEnv) != "" {
+		cli.Println(cli.Info(fmt.Sprintf("Recording API requests to %s/ — send them again with 'human replay'.", debugDir)))
+	}
 
 	startSh := filepath.Join(outputDir, "start.sh")
 	pkgJSON := filepath.Join(outputDir, "package.json")
 
 	if _, err := os.Stat(startSh); err == nil {
-		fmt.Println(cli.Info("Starting application via start.sh..."))
+		cli.Println(cli.Info("Starting application via start.sh..."))
 		if err := cmdutil.RunCommand(outputDir, "bash", "start.sh"); err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Run failed: %v", err)))
+			cli.Errorln(fmt.Sprintf("Run failed: %v", err))
 			os.Exit(1)
 		}
 		return
 	}
 
 	if _, err := os.Stat(pkgJSON); err == nil {
-		fmt.Println(cli.Info("Starting application via npm run dev..."))
+		cli.Println(cli.Info("Starting application via npm run dev..."))
 		if err := cmdutil.RunCommand(outputDir, "npm", "run", "dev"); err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Run failed: %v", err)))
+			cli.Errorln(fmt.Sprintf("Run failed: %v", err))
 			os.Exit(1)
 		}
 		return
 	}
 
-	fmt.Fprintln(os.Stderr, cli.Error("No build found. Run 'human build <file>' first."))
+	cli.Errorln("No build found. Run 'human build <file>' first.")
 	os.Exit(1)
 }
 
+// debugDir is where `human run --record` records API requests, and where
+// `human replay` replays them from by default.
+var debugDir = filepath.Join(".human", "debug")
+
+// ── replay ──
+
+func cmdReplay() {
+	host := ""
+	var path string
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--host":
+			if i+1 >= len(args) {
+				cli.Errorln("--host requires a value (e.g. --host http://localhost:3001)")
+				os.Exit(1)
+			}
+			i++
+			host = args[i]
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				cli.Errorln(fmt.Sprintf("Unknown flag: %s", args[i]))
+				fmt.Fprintln(os.Stderr, "Usage: human replay [--host <url>] [file.http | directory]")
+				os.Exit(1)
+			}
+			path = args[i]
+		}
+	}
+	if path == "" {
+		path = debugDir
+	}
+
+	requests, err := replay.Load(path)
+	if err != nil {
+		if os.IsNotExist(err) && path == debugDir {
+			cli.Errorln("No recorded requests. Run 'human run --record' and use the app first.")
+		} else {
+			cli.Errorln(err.Error())
+		}
+		os.Exit(1)
+	}
+	if len(requests) == 0 {
+		cli.Errorln(fmt.Sprintf("No requests found in %s", path))
+		os.Exit(1)
+	}
+
+	target := host
+	if target == "" {
+		target = "the recorded host"
+	}
+	cli.Println(cli.Info(fmt.Sprintf("Replaying %d request(s) against %s", len(requests), target)))
+	client := &http.Client{Timeout: 30 * time.Second}
+	differed := 0
+	for _, req := range requests {
+		res := replay.Send(client, req, host)
+		line := fmt.Sprintf("%s %s", req.Method, req.URL)
+		switch {
+		case res.Err != nil:
+			differed++
+			fmt.Printf("  %s %s: %v\n", cli.Error("✗"), line, res.Err)
+		case !res.Matches():
+			differed++
+			if res.Status != req.Status {
+				fmt.Printf("  %s %s  %d → %d\n", cli.Error("✗"), line, req.Status, res.Status)
+			} else {
+				fmt.Printf("  %s %s  %d\n", cli.Error("✗"), line, res.Status)
+			}
+			for _, d := range res.Diffs {
+				fmt.Printf("      %s\n", d)
+			}
+		default:
+			fmt.Printf("  %s %s  %d\n", cli.Success("✓"), line, res.Status)
+		}
+	}
+
+	fmt.Println()
+	if differed > 0 {
+		cli.Println(cli.Error(fmt.Sprintf("%d of %d request(s) got a different response", differed, len(requests))))
+		os.Exit(1)
+	}
+	cli.Println(cli.Success(fmt.Sprintf("All %d request(s) got the responses they were recorded with", len(requests))))
+}
+
 // ── test ──
 
 func cmdTest() {
+	run := cmdutil.RunTests
+	for _, arg := range os.Args[2:] {
+		switch arg {
+		case "--e2e":
+			run = cmdutil.RunE2ETests
+		case "--contract":
+			run = cmdutil.RunContractTests
+		default:
+			cli.Errorln(fmt.Sprintf("Unknown flag: %s", arg))
+			fmt.Fprintln(os.Stderr, "Usage: human test [--e2e | --contract]")
+			os.Exit(1)
+		}
+	}
+
 	outputDir, err := cmdutil.RequireOutputDir()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
+	requireTrust("run the generated tests")
 
-	fmt.Println(cli.Info("Running tests..."))
-	if err := cmdutil.RunCommandSilent(outputDir, "npm", "test"); err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			os.Exit(exitErr.ExitCode())
-		}
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Test failed: %v", err)))
+	if err := run(outputDir); err != nil {
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 }
@@ -362,18 +668,193 @@ func cmdTest() {
 func cmdAudit() {
 	outputDir, err := cmdutil.RequireOutputDir()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
 	reportPath := filepath.Join(outputDir, "security-report.md")
 	report, err := os.ReadFile(reportPath)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error("No security report found. Run 'human build <file>' to generate one."))
+		cli.Errorln("No security report found. Run 'human build <file>' to generate one.")
 		os.Exit(1)
 	}
 
 	cmdutil.PrintAuditReport(string(report))
+
+	// Check the current .human file against its compliance profile, which
+	// may have changed since the report was generated.
+	file := ""
+	if len(os.Args) > 2 {
+		file = os.Args[2]
+	} else {
+		// The glob also matches the .human output directory.
+		matches, _ := filepath.Glob("*.human")
+		var files []string
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && !info.IsDir() {
+				files = append(files, m)
+			}
+		}
+		if len(files) != 1 {
+			return
+		}
+		file = files[0]
+	}
+	result, err := cmdutil.ParseAndAnalyze(file)
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	checks := quality.CheckCompliance(result.App)
+	if checks == nil {
+		return
+	}
+	cmdutil.PrintComplianceChecks(ir.Compliance(result.App).Name, checks)
+	if quality.ComplianceFailed(checks) {
+		os.Exit(1)
+	}
+}
+
+// ── diff ──
+
+func cmdDiff() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "Usage: human diff <file.human | directory>")
+		os.Exit(1)
+	}
+	file := os.Args[2]
+
+	result, err := cmdutil.ParseAndAnalyze(file)
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	if cmdutil.PrintDiagnostics(result.Errs) {
+		fmt.Fprintf(os.Stderr, "\n%s\n", cli.Error(fmt.Sprintf("%d error(s) found", len(result.Errs.Errors()))))
+		os.Exit(1)
+	}
+
+	preview, err := cmdutil.PreviewBuild(file, result.App)
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	cmdutil.PrintBuildPreview(preview)
+}
+
+// ── compare ──
+
+func cmdCompare() {
+	checklist := false
+	var paths []string
+	for _, arg := range os.Args[2:] {
+		switch arg {
+		case "--checklist":
+			checklist = true
+		default:
+			if !strings.HasPrefix(arg, "-") {
+				paths = append(paths, arg)
+			}
+		}
+	}
+	if len(paths) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: human compare [--checklist] <a.human> <b.human>")
+		os.Exit(1)
+	}
+
+	comparison, err := cmdutil.Compare(paths[0], paths[1])
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	cmdutil.PrintComparison(comparison, checklist)
+}
+
+// ── simulate ──
+
+func cmdSimulate() {
+	const usage = "Usage: human simulate [file.human | directory] \"<workflow trigger>\""
+	var file, event string
+	switch args := os.Args[2:]; len(args) {
+	case 1:
+		event = args[0]
+	case 2:
+		file, event = args[0], args[1]
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	// Auto-detect .human file if not provided
+	if file == "" {
+		var files []string
+		matches, _ := filepath.Glob("*.human")
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && !info.IsDir() {
+				files = append(files, m)
+			}
+		}
+		if len(files) != 1 {
+			cli.Errorln("Specify the .human file with the workflow to simulate.")
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(1)
+		}
+		file = files[0]
+	}
+
+	result, err := cmdutil.ParseAndAnalyze(file)
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	if cmdutil.PrintDiagnostics(result.Errs) {
+		fmt.Fprintf(os.Stderr, "\n%s\n", cli.Error(fmt.Sprintf("%d error(s) found", len(result.Errs.Errors()))))
+		os.Exit(1)
+	}
+
+	wf, err := simulate.Find(result.App, event)
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	if cmdutil.PrintSimulation(simulate.Run(result.App, wf)) {
+		os.Exit(1)
+	}
+}
+
+// ── verify ──
+
+func cmdVerify() {
+	integrity := false
+	for _, arg := range os.Args[2:] {
+		if arg == "--integrity" {
+			integrity = true
+		}
+	}
+	if !integrity {
+		fmt.Fprintln(os.Stderr, "Usage: human verify --integrity")
+		os.Exit(1)
+	}
+
+	outputDir, err := cmdutil.RequireOutputDir()
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	cfg, err := config.Load(".")
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	report, err := cmdutil.VerifyIntegrity(outputDir, cfg.Signing)
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	cmdutil.PrintIntegrityReport(report)
+	if !report.OK() {
+		os.Exit(1)
+	}
 }
 
 // ── eject ──
@@ -381,7 +862,7 @@ func cmdAudit() {
 func cmdEject() {
 	outputDir, err := cmdutil.RequireOutputDir()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
@@ -392,7 +873,7 @@ func cmdEject() {
 	}
 
 	if _, err := os.Stat(target); err == nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Directory %q already exists. Choose a different path or remove it first.", target)))
+		cli.Errorln(fmt.Sprintf("Directory %q already exists. Choose a different path or remove it first.", target))
 		os.Exit(1)
 	}
 
@@ -421,11 +902,14 @@ func cmdEject() {
 	})
 
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Eject failed: %v", err)))
+		cli.Errorln(fmt.Sprintf("Eject failed: %v", err))
 		os.Exit(1)
 	}
 
-	fmt.Println(cli.Success(fmt.Sprintf("Ejected to %s/ — this is now a standalone project. No Human dependency required.", target)))
+	cli.Println(cli.Success(fmt.Sprintf("Ejected to %s/ — this is now a standalone project. No Human dependency required.", target)))
+	if _, err := os.Stat(filepath.Join(target, cmdutil.ManifestFile)); err == nil {
+		fmt.Printf("  %s records the sha256 of each file as the build generated it.\n", cmdutil.ManifestFile)
+	}
 }
 
 // stripGeneratedComments removes "Generated by Human compiler" lines from file content.
@@ -447,6 +931,8 @@ func stripGeneratedComments(content string) string {
 func cmdDeploy() {
 	// Parse flags
 	dryRun := false
+	estimate := false
+	checkDrift := false
 	envName := ""
 	var file string
 	args := os.Args[2:]
@@ -454,12 +940,16 @@ func cmdDeploy() {
 		switch args[i] {
 		case "--dry-run":
 			dryRun = true
+		case "--estimate":
+			estimate = true
+		case "--check-drift":
+			checkDrift = true
 		case "--env", "-e":
 			if i+1 < len(args) {
 				i++
 				envName = args[i]
 			} else {
-				fmt.Fprintln(os.Stderr, cli.Error("--env requires a value (e.g. --env staging)"))
+				cli.Errorln("--env requires a value (e.g. --env staging)")
 				os.Exit(1)
 			}
 		default:
@@ -475,32 +965,33 @@ func cmdDeploy() {
 		if len(matches) == 1 {
 			file = matches[0]
 		} else if len(matches) > 1 {
-			fmt.Fprintln(os.Stderr, cli.Error("Multiple .human files found. Specify which one to deploy."))
-			fmt.Fprintln(os.Stderr, "Usage: human deploy [--dry-run] [--env <name>] <file.human>")
+			cli.Errorln("Multiple .human files found. Specify which one to deploy.")
+			fmt.Fprintln(os.Stderr, "Usage: human deploy [--dry-run | --estimate | --check-drift] [--env <name>] <file.human>")
 			os.Exit(1)
 		} else {
-			fmt.Fprintln(os.Stderr, cli.Error("No .human file found. Specify a file to deploy."))
-			fmt.Fprintln(os.Stderr, "Usage: human deploy [--dry-run] [--env <name>] <file.human>")
+			cli.Errorln("No .human file found. Specify a file to deploy.")
+			fmt.Fprintln(os.Stderr, "Usage: human deploy [--dry-run | --estimate | --check-drift] [--env <name>] <file.human>")
 			os.Exit(1)
 		}
 	}
 
-	outputDir := filepath.Join(".human", "output")
-
-	// Build the project
-	fmt.Println(cli.Info("Building before deploy..."))
-	if err := runBuild(file); err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Build failed: %v", err)))
-		os.Exit(1)
+	// Estimating reads the generated Terraform without running project code.
+	switch {
+	case checkDrift:
+		requireTrust("check it for drift")
+	case !estimate:
+		requireTrust("deploy it")
 	}
+	outputDir := filepath.Join(".human", "output")
 
-	// Load the IR to read config
-	result, err := cmdutil.ParseAndAnalyze(file)
+	// Build the project for the environment it deploys to, so the
+	// frontends call its URL and the backends accept requests from it.
+	cli.Println(cli.Info("Building before deploy..."))
+	app, err := cmdutil.BuildForDeploy(file, envName)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(fmt.Sprintf("Build failed: %v", err))
 		os.Exit(1)
 	}
-	app := result.App
 
 	// Determine deploy target
 	deployTarget := ""
@@ -508,7 +999,7 @@ func cmdDeploy() {
 		deployTarget = strings.ToLower(app.Config.Deploy)
 	}
 	if deployTarget == "" {
-		fmt.Fprintln(os.Stderr, cli.Error("No deployment target configured. Add 'deploy to Docker' in your build block."))
+		cli.Errorln("No deployment target configured. Add 'deploy to Docker' in your build block.")
 		os.Exit(1)
 	}
 
@@ -518,7 +1009,7 @@ func cmdDeploy() {
 		for _, env := range app.Environments {
 			if strings.EqualFold(env.Name, envName) {
 				found = true
-				fmt.Println(cli.Info(fmt.Sprintf("Environment: %s", env.Name)))
+				cli.Println(cli.Info(fmt.Sprintf("Environment: %s", env.Name)))
 				for k, v := range env.Config {
 					fmt.Printf("  %s: %s\n", k, v)
 				}
@@ -534,48 +1025,127 @@ func cmdDeploy() {
 			if len(available) > 0 {
 				msg += fmt.Sprintf(" Available: %s", strings.Join(available, ", "))
 			}
-			fmt.Fprintln(os.Stderr, cli.Error(msg))
+			cli.Errorln(msg)
 			os.Exit(1)
 		}
 	}
 
-	// Deploy based on target
-	switch {
+	if estimate {
+		estimateDeployCost(app, outputDir, deployTarget, envName)
+		return
+	}
+	if checkDrift {
+		checkDeployDrift(outputDir, deployTarget, envName)
+		return
+	}
+
+	hook := cmdutil.HookContext{
+		Hook:        cmdutil.HookPreDeploy,
+		Source:      file,
+		IRPath:      cmdutil.IntentPath(file),
+		OutputDir:   outputDir,
+		Environment: envName,
+		Target:      deployTarget,
+		DryRun:      dryRun,
+	}
+	if err := cmdutil.RunHooks(".", hook); err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+
+	// Deploy based on target
+	switch {
 	case strings.Contains(deployTarget, "aws"), strings.Contains(deployTarget, "gcp"), strings.Contains(deployTarget, "terraform"):
 		deployTerraform(app, outputDir, envName, dryRun)
 	case strings.Contains(deployTarget, "docker"):
 		if err := cmdutil.DeployDocker(app, outputDir, dryRun); err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+			cli.Errorln(err.Error())
+			os.Exit(1)
+		}
+	case hosting.Platform(app) != "":
+		if err := cmdutil.DeployHosting(app, outputDir, dryRun); err != nil {
+			cli.Errorln(err.Error())
 			os.Exit(1)
 		}
 	default:
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Unsupported deploy target: %s. Supported: Docker, AWS, GCP", app.Config.Deploy)))
+		cli.Errorln(fmt.Sprintf("Unsupported deploy target: %s. Supported: Docker, AWS, GCP, Vercel, Netlify, Fly.io", app.Config.Deploy))
+		os.Exit(1)
+	}
+}
+
+// estimateDeployCost prints the monthly cost of each environment's cloud
+// infrastructure without deploying it.
+func estimateDeployCost(app *ir.Application, outputDir, deployTarget, envName string) {
+	if hosting.Platform(app) != "" {
+		cli.Println(cli.Info(fmt.Sprintf("%s bills by its own plans, not by provisioned infrastructure, so there is nothing to estimate.", app.Config.Deploy)))
+		return
+	}
+	if !strings.Contains(deployTarget, "aws") && !strings.Contains(deployTarget, "gcp") && !strings.Contains(deployTarget, "terraform") {
+		cli.Println(cli.Info(fmt.Sprintf("%s deploys run on your own servers, so there is no cloud bill to estimate.", app.Config.Deploy)))
+		return
+	}
+	estimates, err := cmdutil.EstimateCosts(app, filepath.Join(outputDir, "terraform"), envName)
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	for i, e := range estimates {
+		if i > 0 {
+			fmt.Println()
+		}
+		cmdutil.PrintCostEstimate(e)
+	}
+	fmt.Println()
+	cli.Println(cli.Info("Estimates only — run without --estimate to deploy."))
+}
+
+// checkDeployDrift reports whether the live infrastructure matches the
+// generated configuration, exiting with status 2 when it doesn't so CI
+// can fail on drift.
+func checkDeployDrift(outputDir, deployTarget, envName string) {
+	var report *cmdutil.DriftReport
+	var err error
+	switch {
+	case strings.Contains(deployTarget, "aws"), strings.Contains(deployTarget, "gcp"), strings.Contains(deployTarget, "terraform"):
+		report, err = cmdutil.CheckTerraformDrift(filepath.Join(outputDir, "terraform"), envName)
+	case strings.Contains(deployTarget, "docker"):
+		report, err = cmdutil.CheckDockerDrift(outputDir)
+	default:
+		err = fmt.Errorf("Drift checks support Docker, AWS, and GCP deploys, not %s", deployTarget)
+	}
+	if err != nil {
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
+	cmdutil.PrintDriftReport(report)
+	if report.Drifted() {
+		cli.Println(cli.Info("Run 'human deploy' to bring it back in line."))
+		os.Exit(2)
+	}
 }
 
 func deployTerraform(app *ir.Application, outputDir, envName string, dryRun bool) {
 	tfDir := filepath.Join(outputDir, "terraform")
 	if _, err := os.Stat(tfDir); os.IsNotExist(err) {
-		fmt.Fprintln(os.Stderr, cli.Error("Terraform files not found. Run 'human build <file>' first."))
+		cli.Errorln("Terraform files not found. Run 'human build <file>' first.")
 		os.Exit(1)
 	}
 
 	// Check terraform is installed
 	if _, err := exec.LookPath("terraform"); err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error("terraform not found in PATH. Install Terraform to deploy."))
+		cli.Errorln("terraform not found in PATH. Install Terraform to deploy.")
 		os.Exit(1)
 	}
 
 	// Init
-	fmt.Println(cli.Info("Step 1/3: terraform init"))
 	if !dryRun {
-		if err := cmdutil.RunCommandSilent(tfDir, "terraform", "init"); err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("terraform init failed: %v", err)))
+		if err := cmdutil.RunInSection("Step 1/3: terraform init", tfDir, "terraform", "init"); err != nil {
+			cli.Errorln(fmt.Sprintf("terraform init failed: %v", err))
 			os.Exit(1)
 		}
 	} else {
-		fmt.Println(cli.Info("  (dry-run — skipped)"))
+		cli.Println(cli.Info("Step 1/3: terraform init"))
+		cli.Println(cli.Info("  (dry-run — skipped)"))
 	}
 
 	// Plan
@@ -586,20 +1156,21 @@ func deployTerraform(app *ir.Application, outputDir, envName string, dryRun bool
 			planArgs = append(planArgs, "-var-file="+tfvars)
 		}
 	}
-	fmt.Println(cli.Info(fmt.Sprintf("Step 2/3: terraform %s", strings.Join(planArgs, " "))))
+	planTitle := fmt.Sprintf("Step 2/3: terraform %s", strings.Join(planArgs, " "))
 	if !dryRun {
-		if err := cmdutil.RunCommandSilent(tfDir, "terraform", planArgs...); err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("terraform plan failed: %v", err)))
+		if err := cmdutil.RunInSection(planTitle, tfDir, "terraform", planArgs...); err != nil {
+			cli.Errorln(fmt.Sprintf("terraform plan failed: %v", err))
 			os.Exit(1)
 		}
 	} else {
-		fmt.Println(cli.Info("  (dry-run — showing plan only)"))
+		cli.Println(cli.Info(planTitle))
+		cli.Println(cli.Info("  (dry-run — showing plan only)"))
 		_ = cmdutil.RunCommandSilent(tfDir, "terraform", planArgs...)
 	}
 
 	// Apply (only if not dry-run)
 	if dryRun {
-		fmt.Println(cli.Success("Dry run complete — run without --dry-run to apply changes."))
+		cli.Println(cli.Success("Dry run complete — run without --dry-run to apply changes."))
 		return
 	}
 
@@ -610,9 +1181,9 @@ func deployTerraform(app *ir.Application, outputDir, envName string, dryRun bool
 			applyArgs = append(applyArgs, "-var-file="+tfvars)
 		}
 	}
-	fmt.Println(cli.Info(fmt.Sprintf("Step 3/3: terraform %s", strings.Join(applyArgs, " "))))
-	if err := cmdutil.RunCommandSilent(tfDir, "terraform", applyArgs...); err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("terraform apply failed: %v", err)))
+	applyTitle := fmt.Sprintf("Step 3/3: terraform %s", strings.Join(applyArgs, " "))
+	if err := cmdutil.RunInSection(applyTitle, tfDir, "terraform", applyArgs...); err != nil {
+		cli.Errorln(fmt.Sprintf("terraform apply failed: %v", err))
 		os.Exit(1)
 	}
 
@@ -620,117 +1191,79 @@ func deployTerraform(app *ir.Application, outputDir, envName string, dryRun bool
 	if app.Config != nil {
 		target = app.Config.Deploy
 	}
-	fmt.Println(cli.Success(fmt.Sprintf("Deployed %s via Terraform to %s.", app.Name, target)))
+	cli.Println(cli.Success(fmt.Sprintf("Deployed %s via Terraform to %s.", app.Name, target)))
 }
 
 // ── build --watch ──
 
-func cmdBuildWatch(file string) {
-	// Discover all project files to watch.
-	result, err := cmdutil.ParseAndAnalyze(file)
+func cmdBuildWatch(file, env string, serve bool) {
+	watcher, err := cmdutil.NewWatcher()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
-	watchFiles := result.SourceFiles
+	defer watcher.Close()
 
-	if len(watchFiles) > 1 {
-		fmt.Println(cli.Info(fmt.Sprintf("Watching %d files for changes... (Ctrl+C to stop)", len(watchFiles))))
-	} else {
-		fmt.Println(cli.Info(fmt.Sprintf("Watching %s for changes... (Ctrl+C to stop)", file)))
+	var server *cmdutil.DevServer
+	if serve {
+		requireTrust("run the generated app")
+		server = &cmdutil.DevServer{Dir: filepath.Join(".human", "output")}
+		defer server.Stop()
 	}
 
 	// Catch interrupt to exit cleanly
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt)
 
-	lastMod := time.Time{}
-
+	// Build once, then after each change. The watched files are refreshed
+	// after every build, so newly imported files and designs are seen.
+	var watched []string
+	changed := ""
 	for {
-		select {
-		case <-sigCh:
-			fmt.Println("\n" + cli.Info("Watch stopped."))
-			return
-		default:
+		now := time.Now().Format("15:04:05")
+		if changed != "" {
+			fmt.Printf("\n%s %s (%s changed)\n", cli.Info(now), cli.Info("Building..."), filepath.Base(changed))
+		} else {
+			fmt.Printf("%s %s\n", cli.Info(now), cli.Info("Building..."))
 		}
 
-		// Check all watched files for modifications.
-		var latestMod time.Time
-		var changedFile string
-		for _, wf := range watchFiles {
-			info, err := os.Stat(wf)
-			if err != nil {
-				continue
-			}
-			if info.ModTime().After(latestMod) {
-				latestMod = info.ModTime()
-				changedFile = wf
-			}
+		rb, err := cmdutil.RebuildQuietly(file, env)
+		if rb != nil {
+			watched = rb.WatchFiles()
 		}
-
-		if latestMod.After(lastMod) {
-			lastMod = latestMod
-
-			// Small debounce — editors often write multiple times
-			time.Sleep(100 * time.Millisecond)
-
-			now := time.Now().Format("15:04:05")
-			if len(watchFiles) > 1 && changedFile != "" {
-				fmt.Printf("\n%s %s (%s changed)\n", cli.Info(now), cli.Info("Building..."), filepath.Base(changedFile))
-			} else {
-				fmt.Printf("\n%s %s\n", cli.Info(now), cli.Info("Building..."))
+		if err != nil {
+			cli.Errorln(fmt.Sprintf("Build failed: %v", err))
+		} else {
+			cmdutil.PrintRebuild(rb)
+			cli.Println(cli.Success(fmt.Sprintf("%s Rebuilt successfully", now)))
+			if server != nil {
+				if err := server.Restart(); err != nil {
+					cli.Errorln(err.Error())
+				}
 			}
+		}
 
-			if err := runBuild(file); err != nil {
-				fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Build failed: %v", err)))
+		if len(watched) == 0 {
+			watched = []string{file}
+		}
+		if err := watcher.Watch(watched); err != nil {
+			cli.Errorln(err.Error())
+			os.Exit(1)
+		}
+		if changed == "" {
+			if len(watched) > 1 {
+				cli.Println(cli.Info(fmt.Sprintf("Watching %d files for changes... (Ctrl+C to stop)", len(watched))))
 			} else {
-				fmt.Println(cli.Success(fmt.Sprintf("%s Rebuilt successfully", now)))
+				cli.Println(cli.Info(fmt.Sprintf("Watching %s for changes... (Ctrl+C to stop)", file)))
 			}
 		}
 
-		time.Sleep(500 * time.Millisecond)
-	}
-}
-
-// runBuild executes the full build pipeline for watch mode and deploy,
-// returning any error instead of calling os.Exit.
-func runBuild(file string) error {
-	result, err := cmdutil.ParseAndAnalyze(file)
-	if err != nil {
-		return err
-	}
-
-	if cmdutil.PrintDiagnostics(result.Errs) {
-		return fmt.Errorf("%d error(s) found", len(result.Errs.Errors()))
-	}
-
-	yaml, err := ir.ToYAML(result.App)
-	if err != nil {
-		return fmt.Errorf("serialization error: %w", err)
-	}
-
-	outDir := filepath.Join(".human", "intent")
-	if err := os.MkdirAll(outDir, 0755); err != nil {
-		return err
-	}
-
-	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
-	outFile := filepath.Join(outDir, base+".yaml")
-	if err := os.WriteFile(outFile, []byte(yaml), 0644); err != nil {
-		return err
-	}
-
-	// Run all code generators
-	outputDir := filepath.Join(".human", "output")
-	results, qResult, _, genErr := build.RunGenerators(result.App, outputDir)
-	if genErr != nil {
-		return genErr
+		var ok bool
+		if changed, ok = watcher.Next(sigCh); !ok {
+			fmt.Println("\n" + cli.Info("Watch stopped."))
+			return
+		}
 	}
-	// Suppress unused warnings — watch/deploy don't print summaries
-	_ = results
-	_ = qResult
-
-	return nil
 }
 
 // ── LLM Commands ──
@@ -745,7 +1278,7 @@ func loadLLMConnector() (*llm.Connector, *config.LLMConfig) {
 
 	cfg, err := config.Load(cwd)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Config error: %v", err)))
+		cli.Errorln(fmt.Sprintf("Config error: %v", err))
 		os.Exit(1)
 	}
 
@@ -761,7 +1294,7 @@ func loadLLMConnector() (*llm.Connector, *config.LLMConfig) {
 
 	provider, err := llm.NewProvider(cfg.LLM)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
@@ -792,7 +1325,7 @@ func detectProviderFromEnv() *config.LLMConfig {
 // promptProviderSetup interactively asks the user which LLM provider to use
 // and saves the choice to .human/config.json.
 func promptProviderSetup(projectDir string) *config.LLMConfig {
-	fmt.Println(cli.Info("No LLM provider configured."))
+	cli.Println(cli.Info("No LLM provider configured."))
 	fmt.Println()
 
 	scanner := bufio.NewScanner(os.Stdin)
@@ -803,7 +1336,7 @@ func promptProviderSetup(projectDir string) *config.LLMConfig {
 	// Resolve API key.
 	key, err := config.ResolveAPIKey(choice)
 	if err != nil && choice != "ollama" {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 	llmCfg.APIKey = key
@@ -811,9 +1344,9 @@ func promptProviderSetup(projectDir string) *config.LLMConfig {
 	// Save the choice.
 	cfg := &config.Config{LLM: llmCfg}
 	if err := config.Save(projectDir, cfg); err != nil {
-		fmt.Fprintln(os.Stderr, cli.Warn(fmt.Sprintf("Could not save config: %v", err)))
+		cli.Warnln(fmt.Sprintf("Could not save config: %v", err))
 	} else {
-		fmt.Println(cli.Success(fmt.Sprintf("Saved LLM config to .human/config.json (provider: %s, model: %s)", llmCfg.Provider, llmCfg.Model)))
+		cli.Println(cli.Success(fmt.Sprintf("Saved LLM config to .human/config.json (provider: %s, model: %s)", llmCfg.Provider, llmCfg.Model)))
 	}
 
 	return llmCfg
@@ -834,20 +1367,20 @@ func cmdAsk() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	fmt.Println(cli.Info("Generating .human code..."))
+	cli.Println(cli.Info("Generating .human code..."))
 	fmt.Println()
 
 	// Stream the response.
 	ch, err := connector.AskStream(ctx, query)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
 	var fullText strings.Builder
 	for chunk := range ch {
 		if chunk.Err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(chunk.Err.Error()))
+			cli.Errorln(chunk.Err.Error())
 			os.Exit(1)
 		}
 		fmt.Print(chunk.Delta)
@@ -864,10 +1397,10 @@ func cmdAsk() {
 	code, valid, parseErr := llm.ExtractAndValidate(fullText.String())
 	_ = code // code is displayed via streaming already
 	if valid {
-		fmt.Println(cli.Success("Generated code is valid .human syntax."))
+		cli.Println(cli.Success("Generated code is valid .human syntax."))
 	} else {
-		fmt.Println(cli.Warn(fmt.Sprintf("Generated code has syntax issues: %s", parseErr)))
-		fmt.Println(cli.Info("The code may need manual adjustments."))
+		cli.Println(cli.Warn(fmt.Sprintf("Generated code has syntax issues: %s", parseErr)))
+		cli.Println(cli.Info("The code may need manual adjustments."))
 	}
 }
 
@@ -881,7 +1414,7 @@ func cmdSuggest() {
 
 	source, err := os.ReadFile(file)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Error reading %s: %v", file, err)))
+		cli.Errorln(fmt.Sprintf("Error reading %s: %v", file, err))
 		os.Exit(1)
 	}
 
@@ -890,12 +1423,12 @@ func cmdSuggest() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	fmt.Println(cli.Info(fmt.Sprintf("Analyzing %s...", file)))
+	cli.Println(cli.Info(fmt.Sprintf("Analyzing %s...", file)))
 	fmt.Println()
 
 	result, err := connector.Suggest(ctx, string(source))
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
@@ -942,7 +1475,7 @@ func cmdEdit() {
 
 	source, err := os.ReadFile(file)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Error reading %s: %v", file, err)))
+		cli.Errorln(fmt.Sprintf("Error reading %s: %v", file, err))
 		os.Exit(1)
 	}
 
@@ -956,9 +1489,15 @@ func cmdEdit() {
 	var totalInput, totalOutput int
 
 	scanner := bufio.NewScanner(os.Stdin)
+	readLine := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		return strings.TrimSpace(scanner.Text()), true
+	}
 
-	fmt.Println(cli.Info(fmt.Sprintf("Editing %s with %s (%s)", file, llmCfg.Provider, llmCfg.Model)))
-	fmt.Println(cli.Info("Type your edit instructions, 'save' to write changes, 'quit' to exit."))
+	cli.Println(cli.Info(fmt.Sprintf("Editing %s with %s (%s)", file, llmCfg.Provider, llmCfg.Model)))
+	cli.Println(cli.Info("Type your edit instructions, 'save' to write changes, 'quit' to exit."))
 	fmt.Println()
 
 	for {
@@ -978,9 +1517,9 @@ func cmdEdit() {
 			return
 		case "save", "s":
 			if err := os.WriteFile(file, []byte(currentSource), 0644); err != nil {
-				fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Error writing %s: %v", file, err)))
+				cli.Errorln(fmt.Sprintf("Error writing %s: %v", file, err))
 			} else {
-				fmt.Println(cli.Success(fmt.Sprintf("Saved %s", file)))
+				cli.Println(cli.Success(fmt.Sprintf("Saved %s", file)))
 			}
 			continue
 		case "show":
@@ -990,11 +1529,11 @@ func cmdEdit() {
 			continue
 		}
 
-		fmt.Println(cli.Info("Editing..."))
+		cli.Println(cli.Info("Editing..."))
 
 		result, err := connector.Edit(ctx, currentSource, instruction, history)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+			cli.Errorln(err.Error())
 			continue
 		}
 
@@ -1002,31 +1541,25 @@ func cmdEdit() {
 		totalOutput += result.Usage.OutputTokens
 
 		fmt.Println()
-		fmt.Println(result.Code)
+		if result.Valid {
+			cli.Println(cli.Success("Valid .human syntax."))
+		} else {
+			cli.Println(cli.Warn(fmt.Sprintf("Syntax issue: %s", result.ParseError)))
+		}
 		fmt.Println()
 
-		if result.Valid {
-			fmt.Println(cli.Success("Valid .human syntax."))
+		// Show the diff and accept it whole or hunk by hunk.
+		if newSource, ok := cli.ReviewDiff(os.Stdout, readLine, "Accept?", currentSource, result.Code); ok {
+			currentSource = newSource
+			cli.Println(cli.Success("Change applied."))
+
+			// Add to history.
+			history = append(history,
+				llm.Message{Role: llm.RoleUser, Content: instruction},
+				llm.Message{Role: llm.RoleAssistant, Content: result.RawResponse},
+			)
 		} else {
-			fmt.Println(cli.Warn(fmt.Sprintf("Syntax issue: %s", result.ParseError)))
-		}
-
-		// Ask to accept.
-		fmt.Print("Accept? (y/n): ")
-		if scanner.Scan() {
-			answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
-			if answer == "y" || answer == "yes" {
-				currentSource = result.Code
-				fmt.Println(cli.Success("Change applied."))
-
-				// Add to history.
-				history = append(history,
-					llm.Message{Role: llm.RoleUser, Content: instruction},
-					llm.Message{Role: llm.RoleAssistant, Content: result.RawResponse},
-				)
-			} else {
-				fmt.Println(cli.Info("Change discarded."))
-			}
+			cli.Println(cli.Info("Change discarded."))
 		}
 		fmt.Println()
 	}
@@ -1049,13 +1582,13 @@ func cmdConvert() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	fmt.Println(cli.Info("Converting to .human code..."))
-	fmt.Println(cli.Info("(Design file import is planned for a future release.)"))
+	cli.Println(cli.Info("Converting to .human code..."))
+	cli.Println(cli.Info("(Design file import is planned for a future release.)"))
 	fmt.Println()
 
 	result, err := connector.Ask(ctx, query)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
@@ -1063,9 +1596,9 @@ func cmdConvert() {
 	fmt.Println()
 
 	if result.Valid {
-		fmt.Println(cli.Success("Generated code is valid .human syntax."))
+		cli.Println(cli.Success("Generated code is valid .human syntax."))
 	} else {
-		fmt.Println(cli.Warn(fmt.Sprintf("Syntax issue: %s", result.ParseError)))
+		cli.Println(cli.Warn(fmt.Sprintf("Syntax issue: %s", result.ParseError)))
 	}
 
 	fmt.Fprintf(os.Stderr, "%s\n",
@@ -1075,38 +1608,89 @@ func cmdConvert() {
 // ── storybook ──
 
 func cmdStorybook() {
+	requireTrust("start Storybook")
 	outputDir := filepath.Join(".human", "output")
 
 	// Find the frontend directory that has a .storybook config.
 	for _, fw := range []string{"react", "vue", "angular", "svelte"} {
 		sbDir := filepath.Join(outputDir, fw, ".storybook")
 		if _, err := os.Stat(sbDir); err == nil {
-			fmt.Println(cli.Info(fmt.Sprintf("Starting Storybook in %s/%s...", outputDir, fw)))
-			cmd := exec.Command("npx", "storybook", "dev", "-p", "6006")
-			cmd.Dir = filepath.Join(outputDir, fw)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			cmd.Stdin = os.Stdin
-			if err := cmd.Run(); err != nil {
-				fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Storybook failed: %v", err)))
+			cli.Println(cli.Info(fmt.Sprintf("Starting Storybook in %s/%s...", outputDir, fw)))
+			if err := cmdutil.RunCommand(filepath.Join(outputDir, fw), "npx", "storybook", "dev", "-p", "6006"); err != nil {
+				cli.Errorln(fmt.Sprintf("Storybook failed: %v", err))
 				os.Exit(1)
 			}
 			return
 		}
 	}
 
-	fmt.Fprintln(os.Stderr, cli.Error("No Storybook found. Run 'human build <file>' first."))
+	cli.Errorln("No Storybook found. Run 'human build <file>' first.")
 	os.Exit(1)
 }
 
 // ── explain ──
 
 func cmdExplainCLI() {
-	topic := ""
-	if len(os.Args) >= 3 {
-		topic = strings.Join(os.Args[2:], " ")
+	markdown := false
+	outputPath := ""
+	var words []string
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--markdown", "--md":
+			markdown = true
+		case "--output", "-o":
+			if i+1 < len(args) {
+				i++
+				outputPath = args[i]
+			} else {
+				cli.Errorln("--output requires a file")
+				os.Exit(1)
+			}
+		default:
+			words = append(words, args[i])
+		}
+	}
+
+	// A .human file or project directory gets an architecture overview;
+	// anything else is a syntax topic.
+	if len(words) == 1 && isExplainTarget(words[0]) {
+		if err := cmdutil.RunExplainApp(os.Stdout, words[0], markdown, outputPath); err != nil {
+			cli.Errorln(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+	if markdown || outputPath != "" {
+		cli.Errorln("--markdown and --output need a .human file or project directory")
+		os.Exit(1)
+	}
+	cmdutil.RunExplain(os.Stdout, strings.Join(words, " "))
+}
+
+// isExplainTarget reports whether arg names a .human file or a directory,
+// rather than a syntax topic.
+func isExplainTarget(arg string) bool {
+	if strings.HasSuffix(arg, ".human") {
+		return true
+	}
+	info, err := os.Stat(arg)
+	return err == nil && info.IsDir()
+}
+
+// ── learn ──
+
+func cmdLearn() {
+	path := "app.human"
+	for _, arg := range os.Args[2:] {
+		if !strings.HasPrefix(arg, "-") {
+			path = arg
+		}
+	}
+	if err := cmdutil.RunLearn(path, os.Stdin, os.Stdout); err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
 	}
-	cmdutil.RunExplain(os.Stdout, topic)
 }
 
 // ── syntax ──
@@ -1127,6 +1711,34 @@ func cmdSyntaxCLI() {
 	cmdutil.RunSyntax(os.Stdout, section, search)
 }
 
+// ── snippet ──
+
+func cmdSnippetCLI() {
+	query := strings.Join(filterGlobalFlags(os.Args[2:]), " ")
+	if err := cmdutil.RunSnippet(os.Stdout, query); err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+}
+
+// ── selfcheck ──
+
+func cmdSelfcheck() {
+	args := filterGlobalFlags(os.Args[2:])
+	if len(args) > 1 || len(args) == 1 && strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "Usage: human selfcheck [examples-dir]")
+		os.Exit(1)
+	}
+	dir := "examples"
+	if len(args) == 1 {
+		dir = args[0]
+	}
+	if err := cmdutil.RunSelfcheck(os.Stdout, dir); err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+}
+
 // ── fix ──
 
 func cmdFixCLI() {
@@ -1163,7 +1775,7 @@ func cmdFixCLI() {
 
 	result, err := fixer.Analyze([]string{file})
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
@@ -1180,12 +1792,12 @@ func cmdFixCLI() {
 		answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
 		if answer == "y" || answer == "yes" {
 			if err := fixer.ApplyAll(result.Fixes); err != nil {
-				fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+				cli.Errorln(err.Error())
 				os.Exit(1)
 			}
-			fmt.Println(cli.Success(fmt.Sprintf("Applied %d fix(es). Backup saved as %s.bak", len(result.Fixes), file)))
+			cli.Println(cli.Success(fmt.Sprintf("Applied %d fix(es). Backup saved as %s.bak", len(result.Fixes), file)))
 		} else {
-			fmt.Println(cli.Info("No changes made."))
+			cli.Println(cli.Info("No changes made."))
 		}
 	}
 }
@@ -1223,17 +1835,17 @@ func cmdEditDispatch() {
 	// Open TUI editor.
 	ed, err := editor.Open(file)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
 	if err := ed.Run(); err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
 	if ed.NeedBuild() {
-		fmt.Println(cli.Info("Building..."))
+		cli.Println(cli.Info("Building..."))
 		editor.RunExternal(os.Args[0], "build", file)
 	}
 }
@@ -1252,21 +1864,21 @@ func cmdHow() {
 
 	connector, llmCfg := loadLLMConnector()
 
-	fmt.Println(cli.Info(fmt.Sprintf("Asking %s (%s)...", llmCfg.Provider, llmCfg.Model)))
+	cli.Println(cli.Info(fmt.Sprintf("Asking %s (%s)...", llmCfg.Provider, llmCfg.Model)))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
 	ch, err := connector.HowStream(ctx, question)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
 	sw := cli.NewStreamWriter(os.Stdout)
 	for chunk := range ch {
 		if chunk.Err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(chunk.Err.Error()))
+			cli.Errorln(chunk.Err.Error())
 			os.Exit(1)
 		}
 		if chunk.Delta != "" {
@@ -1340,24 +1952,24 @@ func cmdImportOpenAPI() {
 		os.Exit(1)
 	}
 
-	fmt.Println(cli.Info(fmt.Sprintf("Parsing OpenAPI spec: %s", source)))
+	cli.Println(cli.Info(fmt.Sprintf("Parsing OpenAPI spec: %s", source)))
 
 	spec, err := openapi.Parse(source)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
-	fmt.Println(cli.Info(fmt.Sprintf("Spec: %s v%s", spec.Info.Title, spec.Info.Version)))
+	cli.Println(cli.Info(fmt.Sprintf("Spec: %s v%s", spec.Info.Title, spec.Info.Version)))
 
 	code, err := openapi.ToHuman(spec, appName)
 	if err != nil {
 		// ToHuman returns code even with syntax warnings
-		fmt.Fprintln(os.Stderr, cli.Warn(err.Error()))
+		cli.Warnln(err.Error())
 	}
 
 	if code == "" {
-		fmt.Fprintln(os.Stderr, cli.Error("Conversion produced no output"))
+		cli.Errorln("Conversion produced no output")
 		os.Exit(1)
 	}
 
@@ -1372,12 +1984,12 @@ func cmdImportOpenAPI() {
 	}
 
 	if err := os.WriteFile(output, []byte(code), 0644); err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Writing output: %v", err)))
+		cli.Errorln(fmt.Sprintf("Writing output: %v", err))
 		os.Exit(1)
 	}
 
-	fmt.Println(cli.Success(fmt.Sprintf("Generated %s from OpenAPI spec", output)))
-	fmt.Println(cli.Info(fmt.Sprintf("Next: human check %s  or  human build %s", output, output)))
+	cli.Println(cli.Success(fmt.Sprintf("Generated %s from OpenAPI spec", output)))
+	cli.Println(cli.Info(fmt.Sprintf("Next: human check %s  or  human build %s", output, output)))
 }
 
 // ── feature ──
@@ -1399,19 +2011,19 @@ func cmdFeature() {
 			}
 		}
 		if err := git.FeatureFinish(dryRun); err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+			cli.Errorln(err.Error())
 			os.Exit(1)
 		}
 		branch, _ := git.DefaultBranch()
-		fmt.Println(cli.Success(fmt.Sprintf("Feature merged into %s and branch deleted.", branch)))
+		cli.Println(cli.Success(fmt.Sprintf("Feature merged into %s and branch deleted.", branch)))
 	default:
 		name := strings.Join(args, " ")
 		if err := git.Feature(name); err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+			cli.Errorln(err.Error())
 			os.Exit(1)
 		}
 		branch, _ := git.CurrentBranch()
-		fmt.Println(cli.Success(fmt.Sprintf("Created and switched to %s", branch)))
+		cli.Println(cli.Success(fmt.Sprintf("Created and switched to %s", branch)))
 	}
 }
 
@@ -1429,7 +2041,7 @@ func cmdRelease() {
 	case "notes":
 		notes, err := git.ReleaseNotes()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+			cli.Errorln(err.Error())
 			os.Exit(1)
 		}
 		fmt.Print(notes)
@@ -1442,11 +2054,11 @@ func cmdRelease() {
 			}
 		}
 		if err := git.Release(version, dryRun); err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+			cli.Errorln(err.Error())
 			os.Exit(1)
 		}
 		if !dryRun {
-			fmt.Println(cli.Success(fmt.Sprintf("Tagged %s — push with: git push origin %s", version, version)))
+			cli.Println(cli.Success(fmt.Sprintf("Tagged %s — push with: git push origin %s", version, version)))
 		}
 	}
 }
@@ -1538,13 +2150,13 @@ func cmdDesign() {
 	} else if figma.IsImageFile(input) {
 		code, err = designFromImage(inputs, cfg)
 	} else {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Unrecognized input: %s", input)))
+		cli.Errorln(fmt.Sprintf("Unrecognized input: %s", input))
 		fmt.Fprintln(os.Stderr, "  Provide a Figma URL (https://figma.com/...) or an image file (.png, .jpg, .webp)")
 		os.Exit(1)
 	}
 
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
 
@@ -1558,12 +2170,12 @@ func cmdDesign() {
 	}
 
 	if err := os.WriteFile(output, []byte(code+"\n"), 0644); err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Writing output: %v", err)))
+		cli.Errorln(fmt.Sprintf("Writing output: %v", err))
 		os.Exit(1)
 	}
 
-	fmt.Println(cli.Success(fmt.Sprintf("Generated %s", output)))
-	fmt.Println(cli.Info(fmt.Sprintf("Next: human check %s  or  human build %s", output, output)))
+	cli.Println(cli.Success(fmt.Sprintf("Generated %s", output)))
+	cli.Println(cli.Info(fmt.Sprintf("Next: human check %s  or  human build %s", output, output)))
 }
 
 func designFromFigma(url string, cfg *figma.GenerateConfig) (string, error) {
@@ -1577,7 +2189,7 @@ func designFromFigma(url string, cfg *figma.GenerateConfig) (string, error) {
 		return "", fmt.Errorf("no Figma access token found. Set FIGMA_ACCESS_TOKEN environment variable")
 	}
 
-	fmt.Println(cli.Info(fmt.Sprintf("Fetching Figma file %s...", fileKey)))
+	cli.Println(cli.Info(fmt.Sprintf("Fetching Figma file %s...", fileKey)))
 
 	var file *figma.FigmaFile
 	if nodeID != "" {
@@ -1593,7 +2205,7 @@ func designFromFigma(url string, cfg *figma.GenerateConfig) (string, error) {
 		cfg.AppName = file.Name
 	}
 
-	fmt.Println(cli.Info("Analyzing design and generating .human code..."))
+	cli.Println(cli.Info("Analyzing design and generating .human code..."))
 	return figma.GenerateHumanFile(file, cfg)
 }
 
@@ -1620,10 +2232,461 @@ func designFromImage(imagePaths []string, cfg *figma.GenerateConfig) (string, er
 		cfg.AppName = strings.Title(base)
 	}
 
-	fmt.Println(cli.Info(fmt.Sprintf("Analyzing %d image(s) via %s...", len(imagePaths), provider.Name())))
+	cli.Println(cli.Info(fmt.Sprintf("Analyzing %d image(s) via %s...", len(imagePaths), provider.Name())))
 	return figma.AnalyzeMultipleImages(imagePaths, cfg, provider)
 }
 
+// ── mock ──
+
+func cmdMock() {
+	port := ""
+	var seed int64 = fixtures.DefaultSeed
+	records := fixtures.DefaultCount
+	var file string
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--port", "-p":
+			if i+1 < len(args) {
+				i++
+				port = args[i]
+			} else {
+				cli.Errorln("--port requires a value (e.g. --port 3001)")
+				os.Exit(1)
+			}
+		case "--seed":
+			if i+1 < len(args) {
+				i++
+				n, err := strconv.ParseInt(args[i], 10, 64)
+				if err != nil {
+					cli.Errorln("--seed requires an integer")
+					os.Exit(1)
+				}
+				seed = n
+			}
+		case "--records":
+			if i+1 < len(args) {
+				i++
+				n, err := strconv.Atoi(args[i])
+				if err != nil || n <= 0 {
+					cli.Errorln("--records requires a positive integer")
+					os.Exit(1)
+				}
+				records = n
+			}
+		default:
+			if !strings.HasPrefix(args[i], "-") {
+				file = args[i]
+			}
+		}
+	}
+
+	// Auto-detect .human file if not provided
+	if file == "" {
+		matches, _ := filepath.Glob("*.human")
+		if len(matches) != 1 {
+			fmt.Fprintln(os.Stderr, "Usage: human mock [--port <n>] [--seed <n>] [--records <n>] <file.human | directory>")
+			os.Exit(1)
+		}
+		file = matches[0]
+	}
+
+	result, err := cmdutil.ParseAndAnalyze(file)
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	if cmdutil.PrintDiagnostics(result.Errs) {
+		fmt.Fprintf(os.Stderr, "\n%s\n", cli.Error(fmt.Sprintf("%d error(s) found — mock server not started", len(result.Errs.Errors()))))
+		os.Exit(1)
+	}
+	app := result.App
+
+	// Default to the backend port so the frontend's dev proxy works unchanged.
+	if port == "" {
+		port = docker.BackendPort(app)
+	}
+
+	srv := mock.New(app, mock.Options{Seed: seed, Records: records})
+
+	fmt.Println(cli.Heading(fmt.Sprintf("Mock API for %s", app.Name)))
+	for _, r := range srv.Routes() {
+		model := "—"
+		if r.Model != nil {
+			model = r.Model.Name
+		}
+		fmt.Printf("  %-7s %-28s %-7s %s\n", r.Method, r.Path, r.Kind, model)
+	}
+	fmt.Println()
+	cli.Println(cli.Info(fmt.Sprintf("Serving %d records per model on http://localhost:%s (Ctrl+C to stop)", records, port)))
+
+	if err := http.ListenAndServe(":"+port, srv.Handler()); err != nil {
+		cli.Errorln(fmt.Sprintf("Mock server failed: %v", err))
+		os.Exit(1)
+	}
+}
+
+// ── serve ──
+
+func cmdServe() {
+	addr := ":8080"
+	token := os.Getenv("HUMAN_SERVE_TOKEN")
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--http":
+			if i+1 < len(args) {
+				i++
+				addr = args[i]
+			} else {
+				cli.Errorln("--http requires an address (e.g. --http :8080)")
+				os.Exit(1)
+			}
+		case "--token":
+			if i+1 < len(args) {
+				i++
+				token = args[i]
+			} else {
+				cli.Errorln("--token requires a value")
+				os.Exit(1)
+			}
+		default:
+			fmt.Fprintln(os.Stderr, "Usage: human serve [--http <addr>] [--token <token>]")
+			os.Exit(1)
+		}
+	}
+
+	generated := token == ""
+	if generated {
+		token = serve.NewToken()
+	}
+
+	// /v1/ask needs an LLM provider. Unlike human ask, the server never
+	// prompts for one; without a config or API key, ask is turned off.
+	var connector *llm.Connector
+	cfg, err := config.Load(".")
+	if err != nil {
+		cli.Errorln(fmt.Sprintf("Config error: %v", err))
+		os.Exit(1)
+	}
+	if cfg.LLM == nil {
+		cfg.LLM = detectProviderFromEnv()
+	}
+	if cfg.LLM != nil {
+		if provider, err := llm.NewProvider(cfg.LLM); err == nil {
+			connector = llm.NewConnector(provider, cfg.LLM)
+		} else {
+			cli.Warnln(fmt.Sprintf("/v1/ask disabled: %v", err))
+		}
+	}
+
+	srv := serve.New(serve.Options{
+		Token:  token,
+		Ask:    connector,
+		Logger: log.New(os.Stderr, "[human-serve] ", log.LstdFlags),
+	})
+
+	fmt.Println(cli.Heading("Human build service"))
+	fmt.Println("  POST /v1/check   diagnostics and IR")
+	fmt.Println("  POST /v1/build   generated code as a zip")
+	if connector != nil {
+		fmt.Println("  POST /v1/ask     .human source from a description")
+	}
+	fmt.Println()
+	if generated {
+		cli.Println(cli.Info("Token: " + token))
+		fmt.Println("  Send it as \"Authorization: Bearer <token>\". Set HUMAN_SERVE_TOKEN or --token to choose one.")
+	}
+	cli.Println(cli.Info(fmt.Sprintf("Listening on %s (Ctrl+C to stop)", addr)))
+
+	server := &http.Server{Addr: addr, Handler: srv.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	if err := server.ListenAndServe(); err != nil {
+		cli.Errorln(fmt.Sprintf("Build service failed: %v", err))
+		os.Exit(1)
+	}
+}
+
+// ── sdk ──
+
+func cmdSDK() {
+	lang := "typescript"
+	outputDir := ""
+	var file string
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--lang", "-l":
+			if i+1 < len(args) {
+				i++
+				lang = args[i]
+			} else {
+				cli.Errorln("--lang requires a value (typescript, python, or go)")
+				os.Exit(1)
+			}
+		case "--output", "-o":
+			if i+1 < len(args) {
+				i++
+				outputDir = args[i]
+			} else {
+				cli.Errorln("--output requires a directory")
+				os.Exit(1)
+			}
+		default:
+			if !strings.HasPrefix(args[i], "-") {
+				file = args[i]
+			}
+		}
+	}
+
+	normalized := sdk.NormalizeLanguage(lang)
+	if normalized == "" {
+		cli.Errorln(fmt.Sprintf("Unsupported SDK language %q (supported: %s)", lang, strings.Join(sdk.Languages, ", ")))
+		os.Exit(1)
+	}
+
+	// Auto-detect .human file if not provided
+	if file == "" {
+		matches, _ := filepath.Glob("*.human")
+		if len(matches) != 1 {
+			fmt.Fprintln(os.Stderr, "Usage: human sdk [--lang typescript|python|go] [--output <dir>] <file.human | directory>")
+			os.Exit(1)
+		}
+		file = matches[0]
+	}
+
+	result, err := cmdutil.ParseAndAnalyze(file)
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	if cmdutil.PrintDiagnostics(result.Errs) {
+		fmt.Fprintf(os.Stderr, "\n%s\n", cli.Error(fmt.Sprintf("%d error(s) found — SDK not generated", len(result.Errs.Errors()))))
+		os.Exit(1)
+	}
+	app := result.App
+
+	if len(app.APIs) == 0 {
+		cli.Errorln("No API endpoints declared — nothing to generate an SDK for")
+		os.Exit(1)
+	}
+
+	if outputDir == "" {
+		outputDir = filepath.Join(".human", "output", "sdk", normalized)
+	}
+
+	n, err := sdk.Generate(app, normalized, outputDir)
+	if err != nil {
+		cli.Errorln(fmt.Sprintf("SDK generation failed: %v", err))
+		os.Exit(1)
+	}
+
+	cli.Println(cli.Success(fmt.Sprintf("Generated %s SDK for %s — %d files, %d endpoints in %s", normalized, app.Name, n, len(app.APIs), outputDir)))
+	cli.Println(cli.Info("See README.md in the output directory for install and usage instructions."))
+}
+
+// ── self-update ──
+
+func cmdSelfUpdate() {
+	var opts cmdutil.SelfUpdateOptions
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--channel" || args[i] == "-c":
+			if i+1 < len(args) {
+				i++
+				opts.Channel = args[i]
+			} else {
+				cli.Errorln("--channel requires a value (stable or prerelease)")
+				os.Exit(1)
+			}
+		case strings.HasPrefix(args[i], "--channel="):
+			opts.Channel = strings.TrimPrefix(args[i], "--channel=")
+		case args[i] == "--check":
+			opts.CheckOnly = true
+		case args[i] == "--force":
+			opts.Force = true
+		default:
+			fmt.Fprintln(os.Stderr, "Usage: human self-update [--channel stable|prerelease] [--check] [--force]")
+			os.Exit(1)
+		}
+	}
+
+	if _, err := cmdutil.SelfUpdate(os.Stdout, opts); err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+}
+
+// ── trust ──
+
+func cmdTrust() {
+	dir := "."
+	revoke, list := false, false
+	for _, arg := range os.Args[2:] {
+		switch {
+		case arg == "--revoke":
+			revoke = true
+		case arg == "--list":
+			list = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintln(os.Stderr, "Usage: human trust [dir] [--revoke] [--list]")
+			os.Exit(1)
+		default:
+			dir = arg
+		}
+	}
+
+	switch {
+	case list:
+		dirs, err := cmdutil.TrustedWorkspaces()
+		if err != nil {
+			cli.Errorln(err.Error())
+			os.Exit(1)
+		}
+		if len(dirs) == 0 {
+			cli.Println("No trusted workspaces.")
+			return
+		}
+		for _, d := range dirs {
+			fmt.Println(d)
+		}
+	case revoke:
+		removed, err := cmdutil.UntrustWorkspace(dir)
+		if err != nil {
+			cli.Errorln(err.Error())
+			os.Exit(1)
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		if !removed {
+			cli.Warnln(dir + " was not trusted.")
+			return
+		}
+		cli.Println(cli.Success("No longer trusting " + dir))
+	default:
+		path, err := cmdutil.TrustWorkspace(dir)
+		if err != nil {
+			cli.Errorln(err.Error())
+			os.Exit(1)
+		}
+		cli.Println(cli.Success("Trusted " + path))
+		cli.Println("  human run, test, deploy, storybook, hooks, and project commands can now run here.")
+	}
+}
+
+// ── db ──
+
+func cmdDB() {
+	args := filterGlobalFlags(os.Args[2:])
+	usage := "Usage: human db backup\n       human db restore <latest | backup name | file> [--yes]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	outputDir, err := cmdutil.RequireOutputDir()
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "backup":
+		requireTrust("back up the database")
+		if err := cmdutil.BackupDatabase(outputDir); err != nil {
+			cli.Errorln(fmt.Sprintf("Backup failed: %v", err))
+			os.Exit(1)
+		}
+	case "restore":
+		backup, yes := "", false
+		for _, arg := range args[1:] {
+			switch {
+			case arg == "--yes" || arg == "-y":
+				yes = true
+			case backup == "" && !strings.HasPrefix(arg, "-"):
+				backup = arg
+			default:
+				fmt.Fprintln(os.Stderr, usage)
+				os.Exit(1)
+			}
+		}
+		if backup == "" {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(1)
+		}
+		requireTrust("restore a database backup")
+		if !yes {
+			confirm := cmdutil.TerminalPrompt(os.Stdin, os.Stdout)
+			if confirm == nil {
+				cli.Errorln("Restoring replaces the database's contents. Pass --yes to confirm.")
+				os.Exit(1)
+			}
+			cli.Warnln("Restoring " + backup + " replaces everything in the database.")
+			if !confirm("Continue?") {
+				cli.Println("Restore cancelled.")
+				return
+			}
+		}
+		if err := cmdutil.RestoreDatabase(outputDir, backup); err != nil {
+			cli.Errorln(fmt.Sprintf("Restore failed: %v", err))
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "%s\n", cli.Error(fmt.Sprintf("Unknown db subcommand: %s", args[0])))
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+}
+
+// ── Seed Command ──
+
+func cmdSeed() {
+	if args := filterGlobalFlags(os.Args[2:]); len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: human seed")
+		os.Exit(1)
+	}
+
+	outputDir, err := cmdutil.RequireOutputDir()
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	requireTrust("seed the database")
+	if err := cmdutil.SeedDatabase(outputDir); err != nil {
+		cli.Errorln(fmt.Sprintf("Seeding failed: %v", err))
+		os.Exit(1)
+	}
+	cli.Println(cli.Success("Seeded the development database"))
+}
+
+func cmdMigrate() {
+	dryRun := false
+	for _, arg := range filterGlobalFlags(os.Args[2:]) {
+		if arg != "--dry-run" {
+			fmt.Fprintln(os.Stderr, "Usage: human migrate [--dry-run]")
+			os.Exit(1)
+		}
+		dryRun = true
+	}
+
+	outputDir, err := cmdutil.RequireOutputDir()
+	if err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+	if !dryRun {
+		requireTrust("migrate the database")
+	}
+	if err := cmdutil.MigrateDatabase(outputDir, dryRun, os.Stdout); err != nil {
+		cli.Errorln(fmt.Sprintf("Migration failed: %v", err))
+		os.Exit(1)
+	}
+	if !dryRun {
+		cli.Println(cli.Success("The database is up to date"))
+	}
+}
+
 // ── Plugin Command ──
 
 func cmdPlugin() {
@@ -1652,24 +2715,36 @@ func cmdPlugin() {
 func pluginList() {
 	manifests, err := plugin.List()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(fmt.Sprintf("Failed to list plugins: %v", err)))
+		cli.Errorln(fmt.Sprintf("Failed to list plugins: %v", err))
 		os.Exit(1)
 	}
 
-	if len(manifests) == 0 {
-		fmt.Println(cli.Info("No plugins installed."))
+	onPath := plugin.DiscoverPath()
+
+	if len(manifests) == 0 && len(onPath) == 0 {
+		cli.Println(cli.Info("No plugins installed."))
 		fmt.Println(cli.Muted("  Install one with: human plugin install <go-module-path>"))
+		fmt.Println(cli.Muted("  or put a human-gen-<name> generator on your PATH"))
 		return
 	}
 
 	fmt.Println()
-	fmt.Println(cli.Heading("Installed Plugins"))
-	fmt.Printf("  %-20s %-12s %s\n", "NAME", "VERSION", "CATEGORY")
-	fmt.Printf("  %-20s %-12s %s\n", "────", "───────", "────────")
-	for _, m := range manifests {
-		fmt.Printf("  %-20s %-12s %s\n", m.Name, m.Version, m.Category)
+	if len(manifests) > 0 {
+		fmt.Println(cli.Heading("Installed Plugins"))
+		fmt.Printf("  %-20s %-12s %s\n", "NAME", "VERSION", "CATEGORY")
+		fmt.Printf("  %-20s %-12s %s\n", "────", "───────", "────────")
+		for _, m := range manifests {
+			fmt.Printf("  %-20s %-12s %s\n", m.Name, m.Version, m.Category)
+		}
+		fmt.Println()
+	}
+	if len(onPath) > 0 {
+		fmt.Println(cli.Heading("Generators on PATH"))
+		for _, g := range onPath {
+			fmt.Printf("  %-20s %s\n", g.Meta().Name, cli.Muted(g.(*plugin.PathGenerator).BinaryPath()))
+		}
+		fmt.Println()
 	}
-	fmt.Println()
 }
 
 func pluginInstall(args []string) {
@@ -1687,20 +2762,20 @@ func pluginInstall(args []string) {
 		}
 		fmt.Printf("%s Installing plugin from binary: %s\n", cli.Accent("▶"), args[1])
 		if err := plugin.InstallFromBinary(args[1]); err != nil {
-			fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+			cli.Errorln(err.Error())
 			os.Exit(1)
 		}
-		fmt.Println(cli.Success("Plugin installed successfully."))
+		cli.Println(cli.Success("Plugin installed successfully."))
 		return
 	}
 
 	source := args[0]
 	fmt.Printf("%s Installing plugin: %s\n", cli.Accent("▶"), source)
 	if err := plugin.Install(source); err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
-	fmt.Println(cli.Success("Plugin installed successfully."))
+	cli.Println(cli.Success("Plugin installed successfully."))
 }
 
 func pluginRemove(args []string) {
@@ -1711,10 +2786,10 @@ func pluginRemove(args []string) {
 
 	name := args[0]
 	if err := plugin.Uninstall(name); err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
-	fmt.Println(cli.Success(fmt.Sprintf("Plugin %q removed.", name)))
+	cli.Println(cli.Success(fmt.Sprintf("Plugin %q removed.", name)))
 }
 
 func pluginCreate(args []string) {
@@ -1732,10 +2807,10 @@ func pluginCreate(args []string) {
 	outputDir := name
 	fmt.Printf("%s Scaffolding plugin: %s (%s)\n", cli.Accent("▶"), name, category)
 	if err := plugin.Scaffold(name, category, outputDir); err != nil {
-		fmt.Fprintln(os.Stderr, cli.Error(err.Error()))
+		cli.Errorln(err.Error())
 		os.Exit(1)
 	}
-	fmt.Println(cli.Success(fmt.Sprintf("Plugin project created at ./%s/", name)))
+	cli.Println(cli.Success(fmt.Sprintf("Plugin project created at ./%s/", name)))
 	fmt.Println(cli.Muted("  cd " + name + " && make build"))
 }
 
@@ -1752,6 +2827,15 @@ Subcommands:
 
 // ── Helpers ──
 
+// requireTrust exits unless the current directory is a trusted workspace,
+// asking the user first when stdin is a terminal.
+func requireTrust(action string) {
+	if err := cmdutil.RequireTrust(".", action); err != nil {
+		cli.Errorln(err.Error())
+		os.Exit(1)
+	}
+}
+
 func printUsage() {
 	fmt.Print(`Human — English in, production-ready code out.
 
@@ -1760,29 +2844,60 @@ Usage:
 
 Commands:
   check <file|dir>           Validate a .human file (discovers siblings)
+  lint <file|dir>            Check style and best practices (rules set in .humanlint.yaml)
+  lint --rules [dir]         List the lint rules and their severities
   build <file|dir>           Compile to IR and generate code
   build --inspect <file|dir> Parse and print IR as YAML to stdout
   build --watch <file|dir>   Rebuild automatically on file changes
+  build --watch --serve <file|dir> Also restart the dev server after each rebuild
+  build --env <name> <file|dir> Bake the environment's URL into API URLs and CORS
   build --timing <file|dir>  Show per-generator timing breakdown
+  build --no-cache <file|dir> Rerun every generator, ignoring the build cache
+  build --ci <file|dir>      Fail when the quality score is below the project's minimums
+  build --diagrams <file|dir> Regenerate only the Mermaid/PlantUML diagrams in .human/output/docs
+  diff <file|dir>            Show what rebuilding would change in the IR and generated files
+  compare <a> <b>            Compare two .human specs model by model (--checklist for migration steps)
+  simulate [file] "<event>"  Dry-run the workflow for an event, e.g. "a user signs up"
   init [name]               Create a new Human project
+  init --yes [name]         Create it with your saved defaults, without prompts
   init --multi [name]       Create a multi-file project (concern-based)
   split <file.human>        Split into multi-file project (concern-based)
   split --dry-run <file>    Preview split without writing files
-  run                       Start the development server
-  test                      Run generated tests
-  audit                     Display security and quality report
+  run [--chaos] [--record]  Start the dev server (--chaos injects failures, --record records requests)
+  replay [file|dir]         Send recorded API requests again and compare the responses
+  test                      Run generated tests with coverage
+  test --e2e                Boot the app with docker compose and run its Playwright tests
+  test --contract           Run the API contract against the backend and compare backends
+  audit [file]              Display security report and check compliance
   deploy [file]             Deploy the application (Docker/AWS/GCP)
   deploy --dry-run [file]   Show deploy steps without executing
   deploy --env <name> [file]  Deploy with a specific environment
+  deploy --estimate [file]  Estimate the monthly cloud cost of each environment
+  deploy --check-drift [file]  Check whether live infrastructure matches the build
   eject [path]              Export as standalone code (default: ./output/)
+  verify --integrity        Check the build output against its manifest and signature
   storybook                 Launch Storybook dev server from build output
+  mock [file]               Serve a fake API with generated data (no backend needed)
+  serve [--http :8080]      Serve check/build/ask as an authenticated HTTP API
+  sdk [file]                Generate a client SDK (--lang typescript|python|go)
 
 Reference & Diagnostics:
+  learn [file]              Interactive tutorial: write your first .human file step by step
+  repl                      Try .human lines interactively (:ir, :yaml, :gen react page Home)
   explain [topic]           Learn Human syntax by topic
+  explain <file|dir>        Summarize an app's architecture (--markdown, -o <file>)
   syntax [section]          Full syntax reference
   syntax --search <term>    Search syntax patterns
+  snippet [pattern-id]      Print a ready-to-paste block (e.g. integrate-stripe)
   fix [--dry-run] <file>    Find and auto-fix common issues
   doctor                    Check environment health
+  selfcheck [examples-dir]  Compile the shipped examples, patterns, and snippets
+  self-update               Update human to the latest release (--channel stable|prerelease)
+  trust [dir]               Allow run/test/deploy to execute this project's code (--revoke, --list)
+  db backup                 Back up the database with the generated backup script
+  db restore <backup>       Restore a backup (latest, a backup name, or a file)
+  seed                      Fill the development database with the app's sample records
+  migrate                   Apply the schema's migrations to the database (--dry-run to plan)
 
 Editor:
   edit <file.human>         Open interactive TUI editor
@@ -1812,9 +2927,17 @@ AI-Assisted (optional, requires API key or Ollama):
   how "<question>"          Ask about Human language usage
   suggest <file.human>      Get improvement suggestions for a file
   convert "<description>"   Convert description to .human
-
+`)
+	if cfg, err := config.Load("."); err == nil {
+		cmdutil.PrintProjectCommands(os.Stdout, cfg.Commands)
+	}
+	fmt.Print(`
 Flags:
   --no-color        Disable colored output
+  --verbose         Show generated files, timing, and compiler stages
+  --quiet, -q       Print errors only ("error: <message>" on stderr)
+  --offline         Don't use the network (self-host fonts, skip npm audit)
+  --clean-env       Run npm, docker, terraform, and hooks without credentials in the environment
   --version, -v     Print the compiler version
   --help, -h        Show this help message
 
@@ -3,6 +3,8 @@ module github.com/barun-bash/human
 go 1.25.6
 
 require (
-	golang.org/x/sys v0.41.0 // indirect
-	golang.org/x/term v0.40.0 // indirect
+	github.com/fsnotify/fsnotify v1.10.1
+	golang.org/x/term v0.40.0
 )
+
+require golang.org/x/sys v0.41.0 // indirect
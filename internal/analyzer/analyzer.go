@@ -2,8 +2,10 @@ package analyzer
 
 import (
 	"fmt"
-	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/barun-bash/human/internal/codegen/themes"
 	cerr "github.com/barun-bash/human/internal/errors"
@@ -35,6 +37,7 @@ func Analyze(app *ir.Application, file string) *cerr.CompilerErrors {
 	checkDuplicates(errs, app.Components, func(c *ir.Component) string { return c.Name }, "component", "E303")
 	checkDuplicates(errs, app.APIs, func(a *ir.Endpoint) string { return a.Name }, "API", "E304")
 	checkDuplicates(errs, app.Policies, func(p *ir.Policy) string { return p.Name }, "policy", "E305")
+	checkDuplicates(errs, app.Experiments, func(e *ir.Experiment) string { return e.Name }, "experiment", "E307")
 
 	// 2. Duplicate fields within a model
 	checkDuplicateFields(errs, app.Data)
@@ -90,6 +93,87 @@ func Analyze(app *ir.Application, file string) *cerr.CompilerErrors {
 	// 19. Trigger model references
 	checkTriggerModelRefs(errs, app, models, modelList)
 
+	// 20. Experiment variants and traffic split
+	checkExperiments(errs, app, pages, pageList)
+
+	// 21. In-app notification models, recipients, and triggers
+	checkNotifications(errs, app, models, modelList)
+
+	// 22. Calendar feed models, start fields, and timezones
+	checkCalendars(errs, app, models, modelList)
+
+	// 23. PDF document models
+	checkDocuments(errs, app, models, modelList)
+
+	// 24. Encrypted field types and lookups
+	checkEncryptedFields(errs, app.Data)
+
+	// 25. Database backup schedule and engine
+	checkBackup(errs, app)
+
+	// 26. Connection pool size and engine
+	checkConnectionPool(errs, app)
+
+	// 27. Compliance profile name
+	checkCompliance(errs, app)
+
+	// 28. Interpolated references in show "..." text
+	checkShowTemplates(errs, app, modelList)
+
+	// 29. Chart models and groupings
+	checkCharts(errs, app)
+
+	// 30. Report endpoint models, fields, and groupings
+	checkReports(errs, app)
+
+	// 31. Drag-to-reorder lists and keyboard shortcuts
+	checkInteractions(errs, app, pages, pageList)
+
+	// 32. Styling system name and frontend
+	checkStyling(errs, app)
+
+	// 33. Bundle budget size
+	checkBundleBudget(errs, app)
+
+	// 34. List page searches and filters the paginated API supports
+	checkListQueries(errs, app)
+
+	// 35. Imports name a model, and import forms have an API to upload to
+	checkImports(errs, app)
+
+	// 36. Time policy is understood, and Python stores aware datetimes
+	checkTimes(errs, app)
+
+	// 37. Locale is a language tag, and money has a currency to be shown in
+	checkLocale(errs, app)
+
+	// 38. Accounts statements, users' model, and the endpoints they add
+	checkAccounts(errs, app)
+
+	// 39. Invitations have a team to join and a way to be sent
+	checkInvitations(errs, app)
+
+	// 40. Plans can be bought, and their limits counted
+	checkBilling(errs, app)
+
+	// 41. Metered calls have a user to count them for, and quotas apply
+	checkMetering(errs, app)
+
+	// 42. API keys have a user to belong to and an API to call
+	checkAPIKeys(errs, app)
+
+	// 43. MongoDB has a backend that stores data in it, and what doesn't map
+	checkMongoDB(errs, app)
+
+	// 44. Model hooks name a model's event, and steps the backends can write
+	checkModelHooks(errs, app, modelList)
+
+	// 45. Pages remembering their list's view have a list
+	checkRememberedViews(errs, app)
+
+	// 46. Seed lines count records of a data model
+	checkSeeds(errs, app)
+
 	return errs
 }
 
@@ -291,7 +375,7 @@ func resolveIndexField(rawField string, model *ir.DataModel) bool {
 
 // ── Page navigation validation ──
 
-var navigatePattern = regexp.MustCompile(`(?i)navigates?\s+to\s+(\w+)`)
+var navigatePattern = lazy(`(?i)navigates?\s+to\s+(\w+)`, "navigate")
 
 func checkPageNavigation(errs *cerr.CompilerErrors, pages []*ir.Page, known map[string]bool, knownList []string) {
 	for _, page := range pages {
@@ -314,8 +398,6 @@ func checkPageNavigation(errs *cerr.CompilerErrors, pages []*ir.Page, known map[
 
 // ── CRUD model reference helpers ──
 
-var crudPattern = regexp.MustCompile(`(?i)\b(create|fetch|update|delete)\s+(?:a\s+|the\s+)?(\w+)\b`)
-
 // isSkipWord returns true for common non-model words that follow CRUD verbs.
 func isSkipWord(word string) bool {
 	lower := strings.ToLower(word)
@@ -360,9 +442,7 @@ func hasPossessive(text, word string) bool {
 // otherwise it emits warnings.
 func checkCRUDRefs(errs *cerr.CompilerErrors, label string, actions []*ir.Action, models map[string]bool, modelList []string, code string, asError bool) {
 	for _, action := range actions {
-		matches := crudPattern.FindAllStringSubmatch(action.Text, -1)
-		for _, m := range matches {
-			target := m[2]
+		for _, target := range crudTargets(action.Text) {
 			if isSkipWord(target) {
 				continue
 			}
@@ -594,8 +674,9 @@ func checkArchitecture(errs *cerr.CompilerErrors, app *ir.Application, models ma
 // ── Integration validation ──
 
 var (
-	sendEmailPattern = regexp.MustCompile(`(?i)\bsend\s+(email|notification|welcome email|reminder email)\b`)
-	slackAlertPattern = regexp.MustCompile(`(?i)\b(alert|notify|message)\b.*\bslack\b|\bslack\b.*\b(alert|notify|message)\b`)
+	sendEmailPattern  = lazy(`(?i)\bsend\s+(email|notification|welcome email|reminder email)\b`, "send")
+	slackAlertPattern = lazy(`(?i)\b(alert|notify|message)\b.*\bslack\b|\bslack\b.*\b(alert|notify|message)\b`, "slack")
+	sendSMSPattern    = lazy(`(?i)\bsend\b.*\b(sms|text message|whatsapp)\b`, "send")
 )
 
 func checkIntegrations(errs *cerr.CompilerErrors, app *ir.Application) {
@@ -632,12 +713,15 @@ func checkWorkflowIntegrationRefs(errs *cerr.CompilerErrors, app *ir.Application
 	// Build set of integration types present.
 	hasEmail := false
 	hasMessaging := false
+	hasSMS := false
 	for _, integ := range app.Integrations {
 		switch integ.Type {
 		case "email":
 			hasEmail = true
 		case "messaging":
 			hasMessaging = true
+		case "sms":
+			hasSMS = true
 		}
 	}
 
@@ -673,6 +757,13 @@ func checkWorkflowIntegrationRefs(errs *cerr.CompilerErrors, app *ir.Application
 				"%s references Slack but no messaging integration is declared — add an 'integrate with Slack' (or similar) block",
 				a.label))
 		}
+
+		// W506: sends SMS/WhatsApp but no Twilio integration
+		if !hasSMS && sendSMSPattern.MatchString(a.text) {
+			errs.AddWarning("W506", fmt.Sprintf(
+				"%s sends an SMS or WhatsApp message but no SMS integration is declared — add an 'integrate with Twilio' block",
+				a.label))
+		}
 	}
 }
 
@@ -779,15 +870,163 @@ func checkMonitoringChannels(errs *cerr.CompilerErrors, app *ir.Application) {
 	}
 }
 
+// ── Experiments (E106, E107, W505) ──
+
+func checkExperiments(errs *cerr.CompilerErrors, app *ir.Application, pages map[string]bool, pageList []string) {
+	if len(app.Experiments) == 0 {
+		return
+	}
+
+	for _, exp := range app.Experiments {
+		if len(exp.Variants) < 2 {
+			errs.AddError("E107", fmt.Sprintf(
+				"Experiment %q needs at least two variant pages to split traffic between", exp.Name))
+			continue
+		}
+
+		total := 0
+		var split []string
+		for _, v := range exp.Variants {
+			total += v.Weight
+			split = append(split, strconv.Itoa(v.Weight))
+			if !pages[strings.ToLower(v.Page)] {
+				msg := fmt.Sprintf("Experiment %q uses page %q which does not exist", exp.Name, v.Page)
+				if suggestion := cerr.FindClosest(v.Page, pageList, suggestionThreshold); suggestion != "" {
+					errs.AddErrorWithSuggestion("E106", msg, fmt.Sprintf("Did you mean %q?", suggestion))
+				} else {
+					errs.AddError("E106", msg)
+				}
+			}
+			if v.Weight == 0 {
+				errs.AddError("E107", fmt.Sprintf(
+					"Experiment %q gives no traffic to %q — list one percentage per variant", exp.Name, v.Page))
+			}
+		}
+		if total != 100 {
+			errs.AddError("E107", fmt.Sprintf(
+				"Experiment %q splits traffic %s, which adds up to %d%% instead of 100%%", exp.Name, strings.Join(split, "/"), total))
+		}
+	}
+
+	for _, integ := range app.Integrations {
+		if integ.Type == "analytics" {
+			return
+		}
+	}
+	errs.AddWarning("W505",
+		"Experiments are declared without an analytics integration — exposures will only be logged to the server console")
+}
+
+// ── Notifications (E108, E204, W110) ──
+
+func checkNotifications(errs *cerr.CompilerErrors, app *ir.Application, models map[string]bool, modelList []string) {
+	if len(app.Notifications) == 0 {
+		return
+	}
+
+	if app.Auth == nil || !models["user"] {
+		errs.AddError("E204",
+			"In-app notifications need an authentication block and a User data model to know who to notify")
+	}
+
+	for _, n := range app.Notifications {
+		if !models[strings.ToLower(n.Model)] {
+			msg := fmt.Sprintf("Notification %q is triggered by model %q which does not exist", n.Message, n.Model)
+			if suggestion := cerr.FindClosest(n.Model, modelList, suggestionThreshold); suggestion != "" {
+				errs.AddErrorWithSuggestion("E108", msg, fmt.Sprintf("Did you mean %q?", suggestion))
+			} else {
+				errs.AddError("E108", msg)
+			}
+			continue
+		}
+
+		triggered := false
+		for _, api := range app.APIs {
+			if n.Triggers(api) {
+				triggered = true
+				break
+			}
+		}
+		if !triggered {
+			errs.AddWarning("W110", fmt.Sprintf(
+				"Notification %q is never sent because no API matches \"%s %s\" — name an API after the event, e.g. %s",
+				n.Message, n.Model, n.Event, eventAPIName(n)))
+		}
+	}
+}
+
+// eventAPIName suggests an API name for a notification's event:
+// Task assigned → AssignTask, Order completed → CompleteOrder.
+func eventAPIName(n *ir.Notification) string {
+	verb := strings.ToLower(n.Event)
+	switch {
+	case verb == "":
+		return n.Model
+	case strings.HasSuffix(verb, "ied"):
+		verb = strings.TrimSuffix(verb, "ied") + "y"
+	case strings.HasSuffix(verb, "ed"):
+		verb = strings.TrimSuffix(verb, "ed")
+		for _, suffix := range []string{"at", "et", "ut", "iv", "ov", "as", "os", "is", "iz", "g", "c"} {
+			if strings.HasSuffix(verb, suffix) {
+				verb += "e"
+				break
+			}
+		}
+	}
+	return strings.ToUpper(verb[:1]) + verb[1:] + n.Model
+}
+
+// ── Calendars (E109, E110, W111) ──
+
+func checkCalendars(errs *cerr.CompilerErrors, app *ir.Application, models map[string]bool, modelList []string) {
+	for _, cal := range app.Calendars {
+		if !models[strings.ToLower(cal.Model)] {
+			msg := fmt.Sprintf("Calendar references model %q which does not exist", cal.Model)
+			if suggestion := cerr.FindClosest(cal.Model, modelList, suggestionThreshold); suggestion != "" {
+				errs.AddErrorWithSuggestion("E109", msg, fmt.Sprintf("Did you mean %q?", suggestion))
+			} else {
+				errs.AddError("E109", msg)
+			}
+			continue
+		}
+
+		if cal.Start == "" {
+			errs.AddError("E110", fmt.Sprintf(
+				"%s cannot be added to a calendar because it has no date or datetime field — add one, e.g. 'has a starts_at which is datetime'",
+				cal.Model))
+		}
+
+		if _, err := time.LoadLocation(cal.Timezone); err != nil {
+			errs.AddWarning("W111", fmt.Sprintf(
+				"Calendar for %s uses unknown timezone %q — times stored without a zone will be read as UTC",
+				cal.Model, cal.Timezone))
+		}
+	}
+}
+
+// ── Documents (E111) ──
+
+func checkDocuments(errs *cerr.CompilerErrors, app *ir.Application, models map[string]bool, modelList []string) {
+	for _, doc := range app.Documents {
+		if models[strings.ToLower(doc.Model)] {
+			continue
+		}
+		msg := fmt.Sprintf("PDF %s is generated from model %q which does not exist", doc.Kind, doc.Model)
+		if suggestion := cerr.FindClosest(doc.Model, modelList, suggestionThreshold); suggestion != "" {
+			errs.AddErrorWithSuggestion("E111", msg, fmt.Sprintf("Did you mean %q?", suggestion))
+		} else {
+			errs.AddError("E111", msg)
+		}
+	}
+}
+
 // ── Policy model references (W109) ──
 
 func checkPolicyModelRefs(errs *cerr.CompilerErrors, app *ir.Application, models map[string]bool, modelList []string) {
 	for _, policy := range app.Policies {
 		for _, rules := range [][]*ir.PolicyRule{policy.Permissions, policy.Restrictions} {
 			for _, rule := range rules {
-				matches := crudPattern.FindAllStringSubmatch(rule.Text, -1)
-				for _, m := range matches {
-					target := m[2]
+				for _, target := range crudTargets(rule.Text) {
 					if isSkipWord(target) {
 						continue
 					}
@@ -826,7 +1065,7 @@ func checkActionModelRefs(errs *cerr.CompilerErrors, app *ir.Application, models
 
 // ── Trigger model references (W106) ──
 
-var triggerModelPattern = regexp.MustCompile(`(?i)\b(\w+)\s+(?:is\s+)?(?:created|updated|deleted|completed|overdue|signs?\s+up)\b`)
+var triggerModelPattern = lazy(`(?i)\b(\w+)\s+(?:is\s+)?(?:created|updated|deleted|completed|overdue|signs?\s+up)\b`, "created", "updated", "deleted", "completed", "overdue", "sign")
 
 func checkTriggerModelRefs(errs *cerr.CompilerErrors, app *ir.Application, models map[string]bool, modelList []string) {
 	type triggerSource struct {
@@ -866,3 +1105,813 @@ func checkTriggerModelRefs(errs *cerr.CompilerErrors, app *ir.Application, model
 		}
 	}
 }
+
+// ── Encrypted fields (W112, W113) ──
+
+func checkEncryptedFields(errs *cerr.CompilerErrors, models []*ir.DataModel) {
+	for _, m := range models {
+		for _, f := range m.Fields {
+			if !f.Encrypted || strings.EqualFold(f.Name, "password") {
+				continue
+			}
+			if !f.EncryptsAtRest() {
+				errs.AddWarning("W112", fmt.Sprintf(
+					"%s.%s is marked encrypted but is a %s field — only text, email, and url fields are encrypted at rest, so it will be stored in plain form",
+					m.Name, f.Name, f.Type))
+				continue
+			}
+			if f.Unique {
+				errs.AddWarning("W113", fmt.Sprintf(
+					"%s.%s is encrypted and unique — each encryption of a value differs, so uniqueness can't be enforced and the field can't be searched",
+					m.Name, f.Name))
+			}
+		}
+	}
+}
+
+// ── Database backups (W114, W115) ──
+
+func checkBackup(errs *cerr.CompilerErrors, app *ir.Application) {
+	if app.Database == nil || app.Database.Backup == nil {
+		return
+	}
+	b := app.Database.Backup
+	if b.Schedule == "" {
+		errs.AddWarningWithSuggestion("W114",
+			fmt.Sprintf("Backup schedule %q isn't understood — backups will run daily at 3am UTC", b.Description),
+			`Try "backup daily at 3am", "backup every 6 hours", or "backup weekly on sunday at 2am"`)
+	}
+	if engine := ir.DatabaseEngine(app); engine != "" && !ir.BacksUpDatabase(app) {
+		errs.AddWarning("W115", fmt.Sprintf(
+			"Backups are automated with pg_dump, which only supports PostgreSQL — %s backups won't be generated", engine))
+	}
+}
+
+// ── Connection pooling (W116, W117) ──
+
+// postgresMaxConnections is PostgreSQL's default max_connections.
+const postgresMaxConnections = 100
+
+func checkConnectionPool(errs *cerr.CompilerErrors, app *ir.Application) {
+	if app.Database == nil || app.Database.PoolSize == 0 {
+		return
+	}
+	size := app.Database.PoolSize
+	if engine := ir.DatabaseEngine(app); engine != "" && !ir.PoolsConnections(app) {
+		errs.AddWarning("W116", fmt.Sprintf(
+			"PgBouncer only pools PostgreSQL connections — with %s, each backend process opens up to %d connections directly", engine, size))
+	}
+	if size >= postgresMaxConnections {
+		errs.AddWarningWithSuggestion("W117",
+			fmt.Sprintf("A pool of %d connections reaches PostgreSQL's default limit of %d", size, postgresMaxConnections),
+			"Lower the pool size, or raise max_connections on the database server")
+	}
+}
+
+// ── Compliance profile (W118) ──
+
+func checkCompliance(errs *cerr.CompilerErrors, app *ir.Application) {
+	if app.Config == nil || app.Config.Compliance == "" || ir.Compliance(app) != nil {
+		return
+	}
+	var names []string
+	for name := range ir.ComplianceProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errs.AddWarningWithSuggestion("W118",
+		fmt.Sprintf("Unknown compliance profile %q — no compliance defaults will be applied", app.Config.Compliance),
+		"Use one of: "+strings.Join(names, ", "))
+}
+
+// ── Charts and reports (W119, W120) ──
+
+// checkCharts warns about "show a ... chart of ..." text that names no
+// data model or no way to group its records. The page keeps a placeholder
+// for such a chart, so the app still builds.
+func checkCharts(errs *cerr.CompilerErrors, app *ir.Application) {
+	for _, page := range app.Pages {
+		for _, a := range page.Content {
+			if !ir.IsChart(a) {
+				continue
+			}
+			_, err := ir.ParseChart(app, a.Text)
+			if ae, ok := err.(*ir.AggregateError); ok {
+				warnAggregate(errs, "W119", fmt.Sprintf("Page %s shows a chart of", page.Name), ae)
+			}
+		}
+	}
+}
+
+// checkReports warns about API steps that count, sum, or average records
+// the way checkCharts does for charts. Such an API keeps its generic
+// steps instead of serving a report.
+func checkReports(errs *cerr.CompilerErrors, app *ir.Application) {
+	for _, ep := range app.APIs {
+		if ep.Aggregate != nil {
+			continue
+		}
+		for _, step := range ep.Steps {
+			if !ir.IsAggregate(step.Text) {
+				continue
+			}
+			_, err := ir.ParseAggregate(app, step.Text)
+			if ae, ok := err.(*ir.AggregateError); ok {
+				warnAggregate(errs, "W120", fmt.Sprintf("API %s aggregates", ep.Name), ae)
+			}
+			break
+		}
+	}
+}
+
+// warnAggregate reports why a chart or report's records can't be
+// aggregated, suggesting the closest field or relation to group by.
+func warnAggregate(errs *cerr.CompilerErrors, code, where string, ae *ir.AggregateError) {
+	switch {
+	case ae.Model == nil:
+		errs.AddWarningWithSuggestion(code, where+fmt.Sprintf(" %q, which names no data model", ae.Phrase),
+			"Name the records, e.g. 'orders by status'")
+	case ae.NoField:
+		var numbers []string
+		for _, f := range ae.Model.Fields {
+			if (f.Type == "number" || f.Type == "decimal") && !f.Encrypted {
+				numbers = append(numbers, f.Name)
+			}
+		}
+		msg := where + fmt.Sprintf(" %s records without naming a number field", ae.Model.Name)
+		if len(numbers) == 0 {
+			errs.AddWarningWithSuggestion(code, msg, fmt.Sprintf("%s has no number fields; count its records instead", ae.Model.Name))
+		} else {
+			errs.AddWarningWithSuggestion(code, msg, "Aggregate one of: "+strings.Join(numbers, ", "))
+		}
+	case ae.Phrase == "":
+		errs.AddWarningWithSuggestion(code, where+fmt.Sprintf(" %s records without saying how to group them", ae.Model.Name),
+			"Add 'by <field>' or a period, e.g. 'per week' or 'over the last 30 days'")
+	default:
+		var options []string
+		for _, f := range ae.Model.Fields {
+			if !f.Encrypted {
+				options = append(options, f.Name)
+			}
+		}
+		for _, r := range ae.Model.Relations {
+			if r.Kind == "belongs_to" {
+				options = append(options, strings.ToLower(r.Target))
+			}
+		}
+		msg := where + fmt.Sprintf(" %s records by %q, which is not a field or relation of %s", ae.Model.Name, ae.Phrase, ae.Model.Name)
+		if suggestion := cerr.FindClosest(ae.Phrase, options, suggestionThreshold); suggestion != "" {
+			errs.AddWarningWithSuggestion(code, msg, fmt.Sprintf("Did you mean %q?", suggestion))
+		} else {
+			errs.AddWarningWithSuggestion(code, msg, "Group by one of: "+strings.Join(options, ", ")+", or a period like 'per week'")
+		}
+	}
+}
+
+// ── Drag and keyboard interactions (W121, W122) ──
+
+// checkInteractions warns about "dragging ... reorders the list" text that
+// names no data model, and "pressing ..." text whose key or action isn't
+// understood. Pages keep a comment in their place.
+func checkInteractions(errs *cerr.CompilerErrors, app *ir.Application, pages map[string]bool, pageList []string) {
+	for _, page := range app.Pages {
+		for _, a := range page.Content {
+			switch {
+			case ir.IsReorder(a) && ir.ReorderModel(app, a) == nil:
+				errs.AddWarningWithSuggestion("W121",
+					fmt.Sprintf("Page %s lets users drag %q, which names no data model", page.Name, a.Text),
+					"Name the records, e.g. 'dragging a task reorders the list'")
+			case ir.IsKeyBinding(a):
+				kb, ok := ir.ParseKeyBinding(a.Text)
+				if !ok {
+					errs.AddWarningWithSuggestion("W122",
+						fmt.Sprintf("Page %s handles %q, which names no key or action Human understands", page.Name, a.Text),
+						"Write a key (Escape, Enter, N, slash, Ctrl K) and an action: closes the modal, opens the form, focuses the search, submits the form, or navigates to <Page>")
+					continue
+				}
+				if kb.Action == "navigate" && !pages[strings.ToLower(kb.Target)] {
+					msg := fmt.Sprintf("Page %s handles %q, but there is no page %s", page.Name, a.Text, kb.Target)
+					if suggestion := cerr.FindClosest(kb.Target, pageList, suggestionThreshold); suggestion != "" {
+						errs.AddWarningWithSuggestion("W122", msg, fmt.Sprintf("Did you mean %q?", suggestion))
+					} else {
+						errs.AddWarning("W122", msg)
+					}
+				}
+			}
+		}
+	}
+}
+
+// ── Styling (W123) ──
+
+// checkStyling warns about a styling system that doesn't exist or that the
+// frontend can't use. The frontend is styled with plain CSS instead.
+func checkStyling(errs *cerr.CompilerErrors, app *ir.Application) {
+	if app.Config == nil || app.Config.Styling == "" {
+		return
+	}
+	system, ok := ir.LookupStyling(app.Config.Styling)
+	switch {
+	case !ok:
+		errs.AddWarningWithSuggestion("W123",
+			fmt.Sprintf("Unknown styling system %q — the frontend will be styled with plain CSS", app.Config.Styling),
+			"Use one of: Tailwind, CSS modules, vanilla-extract, styled-components")
+	case !ir.StylingSupported(system, app.Config.Frontend):
+		errs.AddWarningWithSuggestion("W123",
+			fmt.Sprintf("Styling using %s needs a React frontend — the frontend will be styled with plain CSS", app.Config.Styling),
+			"Use Tailwind, which styles every frontend, or build the frontend using React")
+	}
+}
+
+// ── Bundle budget (W124) ──
+
+// checkBundleBudget warns about a bundle budget that isn't a size. Routes
+// are checked against the default budget instead.
+func checkBundleBudget(errs *cerr.CompilerErrors, app *ir.Application) {
+	if app.Config == nil || app.Config.BundleBudget == "" {
+		return
+	}
+	if _, ok := ir.ParseBundleBudget(app.Config.BundleBudget); !ok {
+		errs.AddWarningWithSuggestion("W124",
+			fmt.Sprintf("Bundle budget %q isn't a size in KB — each route gets the default budget of %d KB", app.Config.BundleBudget, ir.DefaultBundleBudgetKB),
+			"Give it in KB, e.g. bundle budget is 200 KB per route")
+	}
+}
+
+// ── List query state (W125) ──
+
+// checkListQueries warns about a page that searches or filters a list its
+// API serves a page at a time, when the API doesn't support searching or
+// filtering it that way. The API narrows a paginated list, so the search
+// or filter would have no effect.
+func checkListQueries(errs *cerr.CompilerErrors, app *ir.Application) {
+	for _, page := range app.Pages {
+		m := pageModel(page, app)
+		if m == nil {
+			continue
+		}
+		ep := ir.ListEndpoint(app, m.Name)
+		q := ir.ListQueryFor(app, page, m.Name, ep)
+		if q == nil || !q.Paged {
+			continue
+		}
+		params := ir.ListParamsFor(app, ep, m.Name)
+		if q.Search && len(params.Search) == 0 {
+			errs.AddWarningWithSuggestion("W125",
+				fmt.Sprintf("Page %s searches %s, but %s, which pages them, doesn't support searching", page.Name, m.Name, ep.Name),
+				fmt.Sprintf("Add 'support searching by <fields>' to api %s", ep.Name))
+		}
+		for _, f := range q.Filters {
+			if !params.FiltersBy(f) {
+				errs.AddWarningWithSuggestion("W125",
+					fmt.Sprintf("Page %s filters %s by %s, but %s, which pages them, doesn't support filtering by it", page.Name, m.Name, f.Name, ep.Name),
+					fmt.Sprintf("Add 'support filtering by %s' to api %s", f.Name, ep.Name))
+			}
+		}
+	}
+}
+
+// ── Imports (W126, W127) ──
+
+// checkImports warns about an import step naming no data model, which
+// imports nothing, and about a page's import form whose model no API
+// imports, which is left out of the page.
+func checkImports(errs *cerr.CompilerErrors, app *ir.Application) {
+	for _, ep := range app.APIs {
+		for _, step := range ep.Steps {
+			if ir.IsImport(step.Text) && ir.ImportModel(app, step.Text) == nil {
+				errs.AddWarningWithSuggestion("W126",
+					fmt.Sprintf("API %s imports records but doesn't name a data model: %q", ep.Name, step.Text),
+					"Name the model whose records it imports, e.g. 'import tasks from a csv or json file'")
+			}
+		}
+	}
+	for _, page := range app.Pages {
+		for _, a := range page.Content {
+			if !ir.IsImportForm(a) {
+				continue
+			}
+			m := ir.ImportFormModel(app, a)
+			if m == nil || ir.ImportEndpoint(app, m.Name) != nil {
+				continue
+			}
+			errs.AddWarningWithSuggestion("W127",
+				fmt.Sprintf("Page %s has a form to import %s, but no API imports them", page.Name, strings.ToLower(m.Name)+"s"),
+				fmt.Sprintf("Add an API with a step like 'import %s from a csv or json file'", strings.ToLower(m.Name)+"s"))
+		}
+	}
+}
+
+// ── Times (W128, W129) ──
+
+// checkTimes warns about a time policy the compiler doesn't understand,
+// and about a Python backend storing datetime fields without one: they are
+// written as naive datetimes, read back in whatever zone the server is in.
+func checkTimes(errs *cerr.CompilerErrors, app *ir.Application) {
+	if app.Config == nil {
+		return
+	}
+	if app.Config.Times != "" && !ir.StoresUTC(app) {
+		errs.AddWarningWithSuggestion("W128",
+			fmt.Sprintf("Time policy isn't understood: %q", "store all times in "+app.Config.Times),
+			"Write 'store all times in UTC and display in the user's timezone'")
+		return
+	}
+	if ir.StoresUTC(app) || !strings.Contains(strings.ToLower(app.Config.Backend), "python") {
+		return
+	}
+	for _, m := range app.Data {
+		for _, f := range m.Fields {
+			if f.Type == "datetime" {
+				errs.AddWarningWithSuggestion("W129",
+					fmt.Sprintf("%s.%s is stored by the Python backend as a naive datetime, with no time zone", m.Name, f.Name),
+					"Add 'store all times in UTC and display in the user's timezone' to the build block")
+				return
+			}
+		}
+	}
+}
+
+// ── Locale (W130, W131) ──
+
+// checkLocale warns about a theme locale that isn't a BCP 47 language tag,
+// which leaves numbers unformatted, and about money fields when neither the
+// theme nor the locale's region names a currency to show them in.
+func checkLocale(errs *cerr.CompilerErrors, app *ir.Application) {
+	if app.Theme == nil || app.Theme.Locale == "" {
+		return
+	}
+	if !ir.ValidLocale(app.Theme.Locale) {
+		errs.AddWarningWithSuggestion("W130",
+			fmt.Sprintf("Locale isn't a language tag: %q", app.Theme.Locale),
+			"Use a BCP 47 tag, e.g. 'locale is en-US'")
+		return
+	}
+	if ir.Currency(app) != "" {
+		return
+	}
+	for _, m := range app.Data {
+		for _, f := range m.Fields {
+			if ir.NumberFormat(f) == ir.NumberMoney {
+				errs.AddWarningWithSuggestion("W131",
+					fmt.Sprintf("%s.%s is money, but locale %s doesn't say which currency it's in", m.Name, f.Name, app.Theme.Locale),
+					"Add a currency to the theme, e.g. 'currency is USD', or a region to the locale, e.g. 'locale is en-US'")
+				return
+			}
+		}
+	}
+}
+
+// ── Accounts (W132–W135) ──
+
+// checkAccounts warns about accounts statements that say nothing the
+// compiler understands, a users' model it can't find or that has no
+// password to check, endpoints the app declares itself in place of the
+// ones the block adds, and accounts without authentication to say whose
+// they are.
+func checkAccounts(errs *cerr.CompilerErrors, app *ir.Application) {
+	acc := app.Accounts
+	if acc == nil {
+		return
+	}
+	for _, text := range acc.Unknown {
+		errs.AddWarningWithSuggestion("W132",
+			fmt.Sprintf("Accounts statement isn't understood: %q", text),
+			"Write e.g. 'users can update their profile, change password, and delete their account'")
+	}
+	m := ir.AccountModel(app)
+	if m == nil {
+		if acc.Profile || acc.Password || acc.Delete || acc.Invite != nil {
+			errs.AddWarningWithSuggestion("W133",
+				"Accounts block has no users' model: there's no User model and no model with a password",
+				"Add a User data model, e.g. 'data User:' with 'has an email which is unique email' and 'has a password which is text'")
+		}
+		return
+	}
+	if (acc.Password || acc.Delete) && m.FieldNamed("password") == nil {
+		errs.AddWarningWithSuggestion("W133",
+			fmt.Sprintf("%s has no password, so users can't change it or confirm deleting their account", m.Name),
+			fmt.Sprintf("Add 'has a password which is text' to %s", m.Name))
+	}
+	invitePage, acceptPage := "", ""
+	if acc.Invite != nil {
+		invitePage, acceptPage = acc.Invite.Page, acc.Invite.Accept
+	}
+	for _, own := range []struct {
+		name string
+		want bool
+		page string
+	}{
+		{"GetProfile", acc.Profile, acc.Page},
+		{"UpdateProfile", acc.Profile, acc.Page},
+		{"ChangePassword", acc.Password, acc.Page},
+		{"DeleteAccount", acc.Delete, acc.Page},
+		{"InviteTeammate", acc.Invite != nil, invitePage},
+		{"GetInvitation", acc.Invite != nil, acceptPage},
+		{"AcceptInvitation", acc.Invite != nil, acceptPage},
+	} {
+		if !own.want {
+			continue
+		}
+		for _, ep := range app.APIs {
+			if strings.EqualFold(ep.Name, own.name) && ep.Account == "" {
+				errs.AddWarningWithSuggestion("W134",
+					fmt.Sprintf("API %s is declared by the app, so the accounts block doesn't add its own and the %s page leaves it out", ep.Name, own.page),
+					fmt.Sprintf("Remove 'api %s' to use the one the accounts block adds", ep.Name))
+			}
+		}
+	}
+	if app.Auth == nil {
+		errs.AddWarningWithSuggestion("W135",
+			"Accounts block without authentication: its endpoints act on the signed-in user, but users can't sign in",
+			"Add an 'authentication:' block, e.g. 'method JWT tokens that expire in 7 days'")
+	}
+}
+
+// ── Invitations (W136–W137) ──
+
+// checkInvitations warns about invitations to a team the app has no model
+// for, and invitations the app has no way to email.
+func checkInvitations(errs *cerr.CompilerErrors, app *ir.Application) {
+	if app.Accounts == nil || app.Accounts.Invite == nil || ir.AccountModel(app) == nil {
+		return
+	}
+	inv := app.Accounts.Invite
+	if inv.Team == "" {
+		msg := "Invitations name no team to invite teammates to"
+		if inv.Missing != "" {
+			msg = fmt.Sprintf("Invitations are to %s, but there's no %s data model", inv.Missing, inv.Missing)
+		}
+		errs.AddWarningWithSuggestion("W136", msg,
+			"Declare the team, e.g. 'data Organization:' with 'has a name which is text', and write 'users can invite teammates by email to their Organization'")
+		return
+	}
+	if ir.EmailIntegration(app) == nil {
+		errs.AddWarningWithSuggestion("W137",
+			"Invitations can't be emailed without an email integration; the invite link is given to the inviter to share instead",
+			"Add an email integration, e.g. 'integrate with SendGrid:' with 'api key from environment variable SENDGRID_API_KEY'")
+	}
+}
+
+// ── Billing (W138–W141) ──
+
+// checkBilling warns about plans that can't be bought — without a payment
+// integration, a User model, or with the app's own Plan or Subscription
+// model — entries of the plans block that aren't a plan, and plan limits
+// on records that can't be counted per subscriber.
+func checkBilling(errs *cerr.CompilerErrors, app *ir.Application) {
+	b := app.Billing
+	if b == nil {
+		return
+	}
+	for _, entry := range b.Unknown {
+		errs.AddWarningWithSuggestion("W140",
+			fmt.Sprintf("Plans entry %q isn't a plan", entry),
+			"Name each plan with its price, if any, e.g. 'plans: Free, Pro ($19/month), Team ($190/year), Enterprise'")
+	}
+	if len(b.Plans) == 0 {
+		return
+	}
+	switch {
+	case ir.PaymentIntegration(app) == nil:
+		errs.AddWarningWithSuggestion("W138",
+			"Plans are declared without a payment integration, so they can't be bought and no billing is generated",
+			"Add 'integrate with Stripe:' with 'api key from environment variable STRIPE_SECRET_KEY'")
+		return
+	case findModel(app, "User") == nil:
+		errs.AddWarningWithSuggestion("W139",
+			"Plans are declared without a User model to subscribe, so no billing is generated",
+			"Add 'data User:' with 'has an email which is unique email'")
+		return
+	case !ir.Bills(app):
+		errs.AddWarningWithSuggestion("W139",
+			fmt.Sprintf("The app declares its own %s or %s model, so the plans block doesn't add billing", ir.PlanModel, ir.SubscriptionModel),
+			fmt.Sprintf("Rename the app's model, or remove it to use the %s and %s models billing adds", ir.PlanModel, ir.SubscriptionModel))
+		return
+	}
+	for _, limit := range ir.PlanLimits(app) {
+		if limit.Counted(app) {
+			continue
+		}
+		msg := fmt.Sprintf("The %s plan limits %ss, but there's no such model to count", limit.Plan, limit.Word)
+		if limit.Model != "" {
+			msg = fmt.Sprintf("The %s plan limits %ss, but %s doesn't belong to %s, so a subscriber's can't be counted and the limit isn't enforced", limit.Plan, limit.Word, limit.Model, b.Model)
+		}
+		errs.AddWarningWithSuggestion("W141", msg,
+			fmt.Sprintf("Add 'belongs to a %s' to the model the plan limits", b.Model))
+	}
+}
+
+// ── Metering (W142–W143) ──
+
+// checkMetering warns when calls can't be metered — without a User model,
+// with the app's own UsageRecord model, or with no API requiring
+// authentication — and about call quotas that aren't enforced.
+func checkMetering(errs *cerr.CompilerErrors, app *ir.Application) {
+	m := app.Metering
+	switch {
+	case m == nil:
+	case findModel(app, "User") == nil:
+		errs.AddWarningWithSuggestion("W142",
+			"API calls are metered per user, but there's no User model, so nothing is metered",
+			"Add 'data User:' with 'has an email which is unique email'")
+	case !ir.Meters(app):
+		errs.AddWarningWithSuggestion("W142",
+			fmt.Sprintf("The app declares its own %s model, so calls aren't metered", ir.UsageModel),
+			fmt.Sprintf("Rename the app's model, or remove it to use the %s model metering adds", ir.UsageModel))
+	default:
+		metered := false
+		for _, ep := range app.APIs {
+			metered = metered || ir.MetersEndpoint(app, ep)
+		}
+		if !metered {
+			errs.AddWarningWithSuggestion("W142",
+				"API calls are metered per user, but no API requires authentication, so no call is counted",
+				"Add 'requires authentication' to the APIs whose calls are metered")
+		}
+	}
+
+	for _, q := range ir.UsageQuotas(app) {
+		if q.Enforced(app) {
+			continue
+		}
+		var msg, fix string
+		switch {
+		case !ir.Meters(app):
+			msg = fmt.Sprintf("Policy %s caps API calls, but calls aren't metered, so the cap isn't enforced", q.Policy)
+			fix = "Add 'meter API calls per user per day'"
+		case q.Period != m.Period && m.Period != "day":
+			msg = fmt.Sprintf("Policy %s caps API calls per %s, but calls are counted per %s, so the cap isn't enforced", q.Policy, q.Period, m.Period)
+			fix = fmt.Sprintf("Cap calls per %s, or meter them per day", m.Period)
+		case ir.Bills(app):
+			msg = fmt.Sprintf("Policy %s caps API calls, but no plan follows it, so the cap isn't enforced", q.Policy)
+			fix = "Name the policy after a plan, e.g. 'policy FreePlan:'"
+		default:
+			msg = fmt.Sprintf("Policy %s caps API calls, but users have no role picking their policy, so the cap isn't enforced", q.Policy)
+			fix = fmt.Sprintf("Add 'has a role which is text' to %s", m.Model)
+		}
+		errs.AddWarningWithSuggestion("W143", msg, fix)
+	}
+}
+
+// ── API keys (W144) ──
+
+// checkAPIKeys warns when external clients can't authenticate with API
+// keys — without a User model or with the app's own ApiKey model — or when
+// no API requires authentication, so a key is needed for nothing.
+func checkAPIKeys(errs *cerr.CompilerErrors, app *ir.Application) {
+	switch {
+	case app.Auth == nil || app.Auth.APIKeys == nil:
+	case findModel(app, "User") == nil:
+		errs.AddWarningWithSuggestion("W144",
+			"External clients authenticate with API keys, but there's no User model for keys to belong to, so no keys are issued",
+			"Add 'data User:' with 'has an email which is unique email'")
+	case !ir.IssuesAPIKeys(app):
+		errs.AddWarningWithSuggestion("W144",
+			fmt.Sprintf("The app declares its own %s model, so no API keys are issued", ir.APIKeyModel),
+			fmt.Sprintf("Rename the app's model, or remove it to use the %s model API keys add", ir.APIKeyModel))
+	default:
+		for _, ep := range app.APIs {
+			if ep.Auth {
+				return
+			}
+		}
+		errs.AddWarningWithSuggestion("W144",
+			"External clients authenticate with API keys, but no API requires authentication, so keys unlock nothing",
+			"Add 'requires authentication' to the APIs external clients call")
+	}
+}
+
+// ── MongoDB (W145, W146, W147) ──
+
+// checkMongoDB warns about what doesn't carry over to MongoDB: backends
+// other than Node's, which store data with SQL, relationships through a
+// join model, which MongoDB can't join, and read replicas.
+func checkMongoDB(errs *cerr.CompilerErrors, app *ir.Application) {
+	if !ir.UsesMongoDB(app) {
+		return
+	}
+	if app.Config != nil && app.Config.Backend != "" && !strings.Contains(strings.ToLower(app.Config.Backend), "node") {
+		errs.AddWarningWithSuggestion("W145",
+			fmt.Sprintf("Only the Node backend stores data in MongoDB — the %s backend is generated for a SQL database", app.Config.Backend),
+			"Use 'backend using Node with Express', or a SQL database such as PostgreSQL")
+	}
+	for _, m := range app.Data {
+		for _, rel := range m.Relations {
+			if rel.Kind != "has_many_through" || rel.Through == "" {
+				continue
+			}
+			errs.AddWarningWithSuggestion("W146",
+				fmt.Sprintf("%s has many %s through %s, but MongoDB has no joins — each collection is read with its own query", m.Name, rel.Target, rel.Through),
+				"Keep join models small, or use PostgreSQL for data that's mostly relationships")
+		}
+	}
+	if app.Database != nil && app.Database.ReadReplica {
+		errs.AddWarningWithSuggestion("W147",
+			"MongoDB drivers choose which replica set member to read from, so no read replica is generated",
+			"Add readPreference=secondaryPreferred to DATABASE_URL to read from secondaries")
+	}
+}
+
+// ── Model hooks (E114, W148, W149) ──
+
+// checkModelHooks validates "before a Task is created, ..." hooks: the
+// event is a model being created, updated, or deleted, and each step is
+// one the backends generate code for rather than a TODO.
+func checkModelHooks(errs *cerr.CompilerErrors, app *ir.Application, modelList []string) {
+	for _, hook := range app.Hooks {
+		where := fmt.Sprintf("%s %s", hook.Timing, hook.Model)
+		if hook.Event == "" {
+			errs.AddWarningWithSuggestion("W148",
+				fmt.Sprintf("Hook %q isn't a model being created, updated, or deleted", where),
+				"Write it as 'before a Task is created, ...' or 'after a User is deleted, ...'")
+			continue
+		}
+		m := ir.HookModel(app, hook)
+		if m == nil {
+			addWithClosest(errs, "E114", fmt.Sprintf("Hook runs when a %s is %sd, but there is no %s model", hook.Model, hook.Event, hook.Model), hook.Model, modelList)
+			continue
+		}
+		for _, step := range hook.Steps {
+			if ir.ResolveHookStep(app, hook, m, step) != nil {
+				continue
+			}
+			errs.AddWarningWithSuggestion("W149",
+				fmt.Sprintf("Hook step %q when a %s is %sd is generated as a TODO", step.Text, m.Name, hook.Event),
+				"A before create or update hook can 'set its <field> to <value>'; a delete hook can 'remove their <records>' of a model that belongs to it")
+		}
+	}
+}
+
+// ── Remembered list views (W150) ──
+
+// checkRememberedViews warns about a page asking to remember the user's
+// filters when it lists no records to filter: nothing is generated for it.
+func checkRememberedViews(errs *cerr.CompilerErrors, app *ir.Application) {
+	for _, page := range app.Pages {
+		if !ir.RemembersView(page) || pageModel(page, app) != nil {
+			continue
+		}
+		errs.AddWarningWithSuggestion("W150",
+			fmt.Sprintf("Page %s remembers the view of its list, but doesn't list any records", page.Name),
+			"Load the records it shows, e.g. 'fetch all tasks', or remove the remember statement")
+	}
+}
+
+// ── Seed data (W151–W152) ──
+
+// checkSeeds warns about seed lines that don't count records of a data
+// model, which are left out of the seed scripts, and about a model seeded
+// twice, where the last count is used.
+func checkSeeds(errs *cerr.CompilerErrors, app *ir.Application) {
+	seen := map[string]bool{}
+	for _, s := range app.Seeds {
+		switch {
+		case s.Count == 0:
+			errs.AddWarningWithSuggestion("W151",
+				fmt.Sprintf("Seed line %q doesn't say how many records to seed, so it's ignored", s.Text),
+				"Start the line with a count and a model, e.g. 'seed 50 Users with realistic names and emails'")
+		case findModel(app, s.Model) == nil:
+			errs.AddWarningWithSuggestion("W151",
+				fmt.Sprintf("Seed line %q seeds %s, but there's no such data model, so it's ignored", s.Text, s.Model),
+				"Name a data model, singular or plural, e.g. 'seed 20 Tasks'")
+		case seen[s.Model]:
+			errs.AddWarningWithSuggestion("W152",
+				fmt.Sprintf("%s is seeded more than once; the last count, %d, is used", s.Model, s.Count),
+				"Keep one seed line per model")
+		default:
+			seen[s.Model] = true
+		}
+	}
+}
+
+// ── Interpolated text (E112, E113) ──
+
+// checkShowTemplates validates the {root.field} references in show "..."
+// text. In a component the root is a prop; in a page it is the data model
+// the page loads, which is shown from its first record.
+func checkShowTemplates(errs *cerr.CompilerErrors, app *ir.Application, modelList []string) {
+	for _, page := range app.Pages {
+		model := pageModel(page, app)
+		for _, ref := range templateRefs(page.Content) {
+			where := fmt.Sprintf("Page %s shows {%s}", page.Name, refText(ref))
+			if model == nil || !strings.EqualFold(ref.Root, model.Name) {
+				if m := findModel(app, ref.Root); m != nil {
+					errs.AddErrorWithSuggestion("E112", where+fmt.Sprintf(" but doesn't load a %s", m.Name),
+						fmt.Sprintf("Add a step that loads it, e.g. 'fetch all %s'", strings.ToLower(m.Name)+"s"))
+					continue
+				}
+				msg := where + fmt.Sprintf(" but %q is not a data model", ref.Root)
+				if model != nil {
+					msg = where + fmt.Sprintf(" but %q is not the data the page loads (%s)", ref.Root, model.Name)
+				}
+				addWithClosest(errs, "E112", msg, ref.Root, modelList)
+				continue
+			}
+			checkTemplateField(errs, where, ref, model)
+		}
+	}
+
+	for _, comp := range app.Components {
+		props := map[string]*ir.Prop{}
+		var propNames []string
+		for _, p := range comp.Props {
+			props[strings.ToLower(p.Name)] = p
+			propNames = append(propNames, p.Name)
+		}
+		for _, ref := range templateRefs(comp.Content) {
+			where := fmt.Sprintf("Component %s shows {%s}", comp.Name, refText(ref))
+			prop := props[strings.ToLower(ref.Root)]
+			if prop == nil {
+				addWithClosest(errs, "E112", where+fmt.Sprintf(" but it accepts no %q", ref.Root), ref.Root, propNames)
+				continue
+			}
+			if model := findModel(app, prop.Type); model != nil {
+				checkTemplateField(errs, where, ref, model)
+			}
+		}
+	}
+}
+
+// checkTemplateField checks that ref names a field of model.
+func checkTemplateField(errs *cerr.CompilerErrors, where string, ref ir.TemplatePart, model *ir.DataModel) {
+	var fields []string
+	for _, f := range model.Fields {
+		fields = append(fields, f.Name)
+	}
+	switch {
+	case ref.Field == "":
+		errs.AddErrorWithSuggestion("E113", where+" without a field",
+			fmt.Sprintf("Name one of %s's fields, e.g. {%s.%s}", model.Name, ref.Root, firstOr(fields, "name")))
+	case model.FieldNamed(ref.Field) == nil:
+		addWithClosest(errs, "E113", where+fmt.Sprintf(" but %s has no field %q", model.Name, ref.Field), ref.Field, fields)
+	}
+}
+
+// addWithClosest adds an error, suggesting the closest of candidates to name.
+func addWithClosest(errs *cerr.CompilerErrors, code, msg, name string, candidates []string) {
+	if suggestion := cerr.FindClosest(name, candidates, suggestionThreshold); suggestion != "" {
+		errs.AddErrorWithSuggestion(code, msg, fmt.Sprintf("Did you mean %q?", suggestion))
+	} else {
+		errs.AddError(code, msg)
+	}
+}
+
+// templateRefs returns the references in the show "..." text of actions.
+func templateRefs(actions []*ir.Action) []ir.TemplatePart {
+	var refs []ir.TemplatePart
+	for _, a := range actions {
+		for _, part := range ir.ParseTemplate(a.Template) {
+			if part.Root != "" {
+				refs = append(refs, part)
+			}
+		}
+	}
+	return refs
+}
+
+func refText(ref ir.TemplatePart) string {
+	if ref.Field == "" {
+		return ref.Root
+	}
+	return ref.Root + "." + ref.Field
+}
+
+func firstOr(list []string, fallback string) string {
+	if len(list) > 0 {
+		return list[0]
+	}
+	return fallback
+}
+
+// pageModel returns the data model a page loads, found the way the
+// frontend generators find it: the first model named by a query, loop, or
+// table.
+func pageModel(page *ir.Page, app *ir.Application) *ir.DataModel {
+	for _, a := range page.Content {
+		if ir.IsTable(a) {
+			if m := ir.TableModel(app, a); m != nil {
+				return m
+			}
+			continue
+		}
+		if a.Type != "query" && a.Type != "loop" {
+			continue
+		}
+		for _, m := range app.Data {
+			if strings.Contains(strings.ToLower(a.Text), strings.ToLower(m.Name)) {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+func findModel(app *ir.Application, name string) *ir.DataModel {
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, name) {
+			return m
+		}
+	}
+	return nil
+}
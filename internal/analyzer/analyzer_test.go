@@ -675,6 +675,35 @@ func TestWorkflowSlackWithIntegration(t *testing.T) {
 	}
 }
 
+func TestWorkflowSMSNoIntegration(t *testing.T) {
+	app := minApp()
+	app.Workflows = []*ir.Workflow{
+		{Trigger: "order ships", Steps: []*ir.Action{
+			{Type: "send", Text: `send an SMS "Your order has shipped"`},
+		}},
+	}
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W506")
+}
+
+func TestWorkflowSMSWithIntegration(t *testing.T) {
+	app := minApp()
+	app.Integrations = []*ir.Integration{
+		{Service: "Twilio", Type: "sms", Credentials: map[string]string{"account sid": "TWILIO_ACCOUNT_SID"}},
+	}
+	app.Workflows = []*ir.Workflow{
+		{Trigger: "order ships", Steps: []*ir.Action{
+			{Type: "send", Text: `send WhatsApp message with template "order-shipped"`},
+		}},
+	}
+	errs := Analyze(app, "test.human")
+	for _, w := range errs.Warnings() {
+		if w.Code == "W506" {
+			t.Errorf("unexpected W506 — Twilio integration exists: %s", w.Message)
+		}
+	}
+}
+
 func TestErrorHandlerSlackNoIntegration(t *testing.T) {
 	app := minApp()
 	app.ErrorHandlers = []*ir.ErrorHandler{
@@ -880,6 +909,106 @@ func TestMonitoringTrackNoAlert(t *testing.T) {
 	}
 }
 
+// ── Experiments (E106, E107, W505) ──
+
+func experimentApp() *ir.Application {
+	app := minApp()
+	app.Pages = append(app.Pages, &ir.Page{Name: "Pricing"}, &ir.Page{Name: "NewPricing"})
+	app.Integrations = []*ir.Integration{{Service: "PostHog", Type: "analytics", Credentials: map[string]string{"api key": "POSTHOG_API_KEY"}}}
+	app.Experiments = []*ir.Experiment{{
+		Name:     "pricing-page",
+		Audience: "visitors",
+		Variants: []*ir.ExperimentVariant{{Page: "Pricing", Weight: 50}, {Page: "NewPricing", Weight: 50}},
+	}}
+	return app
+}
+
+func TestExperimentValid(t *testing.T) {
+	errs := Analyze(experimentApp(), "test.human")
+	if errs.HasErrors() || errs.HasWarnings() {
+		t.Fatalf("expected no diagnostics, got:\n%s", errs.Format())
+	}
+}
+
+func TestExperimentUnknownPage(t *testing.T) {
+	app := experimentApp()
+	app.Experiments[0].Variants[1].Page = "NewPrcing"
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E106")
+	assertSuggestion(t, errs.Errors(), "NewPricing")
+}
+
+func TestExperimentSplitNot100(t *testing.T) {
+	app := experimentApp()
+	app.Experiments[0].Variants[0].Weight = 60
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E107")
+}
+
+func TestExperimentSingleVariant(t *testing.T) {
+	app := experimentApp()
+	app.Experiments[0].Variants = app.Experiments[0].Variants[:1]
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E107")
+}
+
+func TestExperimentDuplicateName(t *testing.T) {
+	app := experimentApp()
+	app.Experiments = append(app.Experiments, app.Experiments[0])
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E307")
+}
+
+func TestExperimentNoAnalytics(t *testing.T) {
+	app := experimentApp()
+	app.Integrations = nil
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W505")
+}
+
+// ── Notifications (E108, E204, W110) ──
+
+func notificationApp() *ir.Application {
+	app := minApp()
+	app.Auth = &ir.Auth{Methods: []*ir.AuthMethod{{Type: "jwt"}}}
+	app.APIs = append(app.APIs, &ir.Endpoint{Name: "AssignTask", Auth: true, Params: []*ir.Param{{Name: "task_id"}, {Name: "user_id"}}})
+	app.Notifications = []*ir.Notification{{Model: "Task", Event: "assigned", Message: "Your task was assigned"}}
+	return app
+}
+
+func TestNotificationValid(t *testing.T) {
+	errs := Analyze(notificationApp(), "test.human")
+	if errs.HasErrors() || errs.HasWarnings() {
+		t.Fatalf("expected no diagnostics, got:\n%s", errs.Format())
+	}
+}
+
+func TestNotificationUnknownModel(t *testing.T) {
+	app := notificationApp()
+	app.Notifications[0].Model = "Tsk"
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E108")
+	assertSuggestion(t, errs.Errors(), "Task")
+}
+
+func TestNotificationWithoutAuth(t *testing.T) {
+	app := notificationApp()
+	app.Auth = nil
+	app.APIs[1].Auth = false
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E204")
+}
+
+func TestNotificationNeverTriggered(t *testing.T) {
+	app := notificationApp()
+	app.Notifications[0].Event = "completed"
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W110")
+	if !strings.Contains(errs.Format(), "CompleteTask") {
+		t.Errorf("expected CompleteTask suggestion, got:\n%s", errs.Format())
+	}
+}
+
 // ── Policy model references (W109) ──
 
 func TestPolicyRefsUnknownModel(t *testing.T) {
@@ -1045,3 +1174,601 @@ func assertWarningSuggestion(t *testing.T, warnings []*cerr.CompilerError, conta
 	}
 	t.Errorf("expected a warning suggestion containing %q, found none", contains)
 }
+
+// ── Calendars (E109, E110, W111) ──
+
+func calendarApp() *ir.Application {
+	app := minApp()
+	app.Data = append(app.Data, &ir.DataModel{Name: "Event", Fields: []*ir.DataField{
+		{Name: "name", Type: "text"},
+		{Name: "date", Type: "datetime"},
+	}})
+	app.Calendars = []*ir.CalendarFeed{
+		{Model: "Event", Slug: "events", Title: "name", Start: "date", Timezone: "UTC"},
+	}
+	return app
+}
+
+func TestCalendarValid(t *testing.T) {
+	errs := Analyze(calendarApp(), "test.human")
+	if errs.HasErrors() || errs.HasWarnings() {
+		t.Fatalf("expected no diagnostics, got:\n%s", errs.Format())
+	}
+}
+
+func TestCalendarUnknownModel(t *testing.T) {
+	app := calendarApp()
+	app.Calendars[0].Model = "Evnt"
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E109")
+	assertSuggestion(t, errs.Errors(), "Event")
+}
+
+func TestCalendarWithoutStart(t *testing.T) {
+	app := calendarApp()
+	app.Calendars[0].Start = ""
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E110")
+}
+
+func TestCalendarUnknownTimezone(t *testing.T) {
+	app := calendarApp()
+	app.Calendars[0].Timezone = "Mars/Olympus_Mons"
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W111")
+}
+
+func TestDocumentValid(t *testing.T) {
+	app := minApp()
+	app.Data = append(app.Data, &ir.DataModel{Name: "Order", Fields: []*ir.DataField{{Name: "total", Type: "decimal"}}})
+	app.Documents = []*ir.Document{{Kind: "invoice", Model: "Order", Slug: "order-invoices", Total: "total"}}
+	errs := Analyze(app, "test.human")
+	if errs.HasErrors() || errs.HasWarnings() {
+		t.Fatalf("expected no diagnostics, got:\n%s", errs.Format())
+	}
+}
+
+func TestDocumentUnknownModel(t *testing.T) {
+	app := minApp()
+	app.Data = append(app.Data, &ir.DataModel{Name: "Order", Fields: []*ir.DataField{{Name: "total", Type: "decimal"}}})
+	app.Documents = []*ir.Document{{Kind: "invoice", Model: "Ordr", Slug: "ordr-invoices"}}
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E111")
+	assertSuggestion(t, errs.Errors(), "Order")
+}
+
+func TestEncryptedNonTextField(t *testing.T) {
+	app := minApp()
+	app.Data = append(app.Data, &ir.DataModel{Name: "Patient", Fields: []*ir.DataField{{Name: "age", Type: "number", Encrypted: true}}})
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W112")
+}
+
+func TestEncryptedUniqueField(t *testing.T) {
+	app := minApp()
+	app.Data = append(app.Data, &ir.DataModel{Name: "Patient", Fields: []*ir.DataField{{Name: "ssn", Type: "text", Encrypted: true, Unique: true}}})
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W113")
+}
+
+func TestBackupUnknownSchedule(t *testing.T) {
+	app := minApp()
+	app.Database = &ir.DatabaseConfig{Engine: "PostgreSQL", Backup: &ir.Backup{Description: "sometimes"}}
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W114")
+}
+
+func TestBackupNonPostgres(t *testing.T) {
+	app := minApp()
+	app.Config.Database = "MySQL"
+	app.Database = &ir.DatabaseConfig{Engine: "MySQL", Backup: &ir.Backup{Description: "daily at 3 am", Schedule: "0 3 * * *"}}
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W115")
+}
+
+func TestConnectionPoolNonPostgres(t *testing.T) {
+	app := minApp()
+	app.Config.Database = "MySQL"
+	app.Database = &ir.DatabaseConfig{Engine: "MySQL", PoolSize: 20}
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W116")
+}
+
+func TestConnectionPoolTooLarge(t *testing.T) {
+	app := minApp()
+	app.Database = &ir.DatabaseConfig{Engine: "PostgreSQL", PoolSize: 200}
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W117")
+}
+
+func TestUnknownComplianceProfile(t *testing.T) {
+	app := minApp()
+	app.Config.Compliance = "PCI"
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W118")
+	assertWarningSuggestion(t, errs.Warnings(), "SOC2")
+
+	app.Config.Compliance = "SOC2"
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W118" {
+			t.Errorf("SOC2 is a known profile: %s", w.Message)
+		}
+	}
+}
+
+func TestStylingSystem(t *testing.T) {
+	app := minApp()
+	app.Config.Frontend = "React"
+	app.Config.Styling = "Sass"
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W123")
+
+	app.Config.Frontend = "Svelte"
+	app.Config.Styling = "styled-components"
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W123")
+	assertWarningSuggestion(t, errs.Warnings(), "Tailwind")
+
+	app.Config.Styling = "Tailwind"
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W123" {
+			t.Errorf("Tailwind styles Svelte: %s", w.Message)
+		}
+	}
+}
+
+func TestBundleBudget(t *testing.T) {
+	app := minApp()
+	app.Config.BundleBudget = "small"
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W124")
+	assertWarningSuggestion(t, errs.Warnings(), "200 KB per route")
+
+	app.Config.BundleBudget = "200 KB per route"
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W124" {
+			t.Errorf("200 KB per route is a budget: %s", w.Message)
+		}
+	}
+}
+
+func TestListQueries(t *testing.T) {
+	app := minApp()
+	app.Data[1].Fields[1].EnumValues = []string{"todo", "done"}
+	app.Pages[1].Content = []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "loop", Text: "each task shows its title"},
+		{Type: "input", Text: "there is a search bar to search tasks by title"},
+		{Type: "input", Text: "there is a dropdown to filter by status"},
+	}
+	list := &ir.Endpoint{Name: "ListTasks", PageSize: 20, Steps: []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "respond", Text: "respond with tasks"},
+	}}
+	app.APIs = append(app.APIs, list)
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W125")
+	assertWarningSuggestion(t, errs.Warnings(), "support searching by <fields>")
+	assertWarningSuggestion(t, errs.Warnings(), "support filtering by status")
+
+	list.Steps = append(list.Steps,
+		&ir.Action{Type: "query", Text: "support searching by title"},
+		&ir.Action{Type: "query", Text: "support filtering by status"})
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W125" {
+			t.Errorf("ListTasks searches and filters the tasks: %s", w.Message)
+		}
+	}
+
+	list.PageSize = 0
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W125" {
+			t.Errorf("a list loaded whole is narrowed in the browser: %s", w.Message)
+		}
+	}
+}
+
+func TestImports(t *testing.T) {
+	app := minApp()
+	app.Pages[1].Content = []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "display", Text: "there is a form to import tasks"},
+	}
+	imp := &ir.Endpoint{Name: "ImportTasks", Steps: []*ir.Action{
+		{Type: "action", Text: "import records from a csv file"},
+	}}
+	app.APIs = append(app.APIs, imp)
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W126")
+	assertWarningCode(t, errs.Warnings(), "W127")
+	assertWarningSuggestion(t, errs.Warnings(), "import tasks from a csv or json file")
+
+	imp.Steps[0].Text = "import tasks from a csv file"
+	imp.Import = &ir.Import{Model: "Task", Formats: []string{"csv"}, Mode: ir.ImportAllOrNothing}
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W126" || w.Code == "W127" {
+			t.Errorf("ImportTasks imports the tasks the page uploads: %s", w.Message)
+		}
+	}
+}
+
+// ── Times (W128, W129) ──
+
+func TestTimes(t *testing.T) {
+	app := minApp()
+	app.Config.Backend = "Python with FastAPI"
+	app.Data[1].Fields = append(app.Data[1].Fields, &ir.DataField{Name: "due", Type: "datetime"})
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W129")
+	assertWarningSuggestion(t, errs.Warnings(), "store all times in UTC")
+
+	app.Config.Times = "local time"
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W128")
+
+	app.Config.Times = "UTC and display in the user's timezone"
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W128" || w.Code == "W129" {
+			t.Errorf("times are stored in UTC: %s", w.Message)
+		}
+	}
+}
+
+// ── Locale (W130, W131) ──
+
+func TestLocale(t *testing.T) {
+	app := minApp()
+	app.Data[1].Fields = append(app.Data[1].Fields, &ir.DataField{Name: "price", Type: "decimal"})
+	app.Theme = &ir.Theme{Locale: "English"}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W130")
+
+	app.Theme.Locale = "en"
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W131")
+	assertWarningSuggestion(t, errs.Warnings(), "currency is USD")
+
+	for _, theme := range []*ir.Theme{{Locale: "en-US"}, {Locale: "en", Currency: "EUR"}} {
+		app.Theme = theme
+		for _, w := range Analyze(app, "test.human").Warnings() {
+			if w.Code == "W130" || w.Code == "W131" {
+				t.Errorf("locale %q with currency %q: %s", theme.Locale, theme.Currency, w.Message)
+			}
+		}
+	}
+}
+
+// ── Accounts (W132–W135) ──
+
+func TestAccounts(t *testing.T) {
+	app := minApp()
+	app.Accounts = &ir.Accounts{Model: "User", Page: "Settings", Profile: true, Password: true, Unknown: []string{"users can export their data"}}
+	app.APIs = append(app.APIs, &ir.Endpoint{Name: "GetProfile"})
+	errs := Analyze(app, "test.human")
+	for _, code := range []string{"W132", "W133", "W134", "W135"} {
+		assertWarningCode(t, errs.Warnings(), code)
+	}
+	assertWarningSuggestion(t, errs.Warnings(), "has a password which is text")
+
+	app.Accounts = &ir.Accounts{Model: "User", Page: "Settings", Profile: true, Password: true}
+	app.APIs = app.APIs[:1]
+	app.Data[0].Fields = append(app.Data[0].Fields, &ir.DataField{Name: "password", Type: "text"})
+	app.Auth = &ir.Auth{}
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		switch w.Code {
+		case "W132", "W133", "W134", "W135":
+			t.Errorf("accounts are complete: %s", w.Message)
+		}
+	}
+
+	app.Accounts = &ir.Accounts{Profile: true}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W133")
+}
+
+// ── Invitations (W136–W137) ──
+
+func TestInvitations(t *testing.T) {
+	app := minApp()
+	app.Accounts = &ir.Accounts{Model: "User", Invite: &ir.Invitations{Missing: "Organization"}}
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W136")
+	assertWarningSuggestion(t, errs.Warnings(), "data Organization:")
+
+	app.Data = append(app.Data, &ir.DataModel{Name: "Organization"})
+	app.Accounts.Invite = &ir.Invitations{Team: "Organization", Page: "Team", Accept: "AcceptInvite"}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W137")
+
+	app.Integrations = append(app.Integrations, &ir.Integration{Service: "SendGrid", Type: "email"})
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		switch w.Code {
+		case "W136", "W137":
+			t.Errorf("invitations are complete: %s", w.Message)
+		}
+	}
+}
+
+// ── Billing (W138–W141) ──
+
+func TestBilling(t *testing.T) {
+	app := minApp()
+	app.Billing = &ir.Billing{Plans: []*ir.Plan{{Name: "Free"}}, Unknown: []string{"Startup for a limited time"}}
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W138")
+	assertWarningCode(t, errs.Warnings(), "W140")
+
+	// The app's own Plan model keeps billing from being added
+	app.Billing.Unknown = nil
+	app.Integrations = append(app.Integrations, &ir.Integration{Service: "Stripe", Type: "payment"})
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W139")
+
+	app.Billing = &ir.Billing{Model: "User", Plans: []*ir.Plan{{Name: "Free", Policy: "FreePlan"}}}
+	app.Data = append(app.Data, &ir.DataModel{Name: "Project"})
+	app.Policies = append(app.Policies, &ir.Policy{Name: "FreePlan", Permissions: []*ir.PolicyRule{{Text: "create up to 3 projects"}}})
+	errs = Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W141")
+	assertWarningSuggestion(t, errs.Warnings(), "belongs to a User")
+
+	app.Data[len(app.Data)-1].Relations = []*ir.Relation{{Kind: "belongs_to", Target: "User"}}
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		switch w.Code {
+		case "W138", "W139", "W140", "W141":
+			t.Errorf("billing is complete: %s", w.Message)
+		}
+	}
+}
+
+// ── Metering (W142–W143) ──
+
+func TestMetering(t *testing.T) {
+	app := minApp()
+	app.Metering = &ir.Metering{Period: "day", Model: "User"}
+	app.Policies = append(app.Policies, &ir.Policy{Name: "Member", Permissions: []*ir.PolicyRule{{Text: "make up to 1000 API calls per day"}}})
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W142")
+	assertWarningSuggestion(t, errs.Warnings(), "requires authentication")
+	// Users have no role picking their policy
+	assertWarningCode(t, errs.Warnings(), "W143")
+	assertWarningSuggestion(t, errs.Warnings(), "has a role")
+
+	app.APIs[0].Auth = true
+	app.Data[0].Fields = append(app.Data[0].Fields, &ir.DataField{Name: "role", Type: "text"})
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		switch w.Code {
+		case "W142", "W143":
+			t.Errorf("metering is complete: %s", w.Message)
+		}
+	}
+
+	// Calls counted per month can't be capped per day
+	app.Metering.Period = "month"
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W143")
+
+	app.Metering = nil
+	assertWarningSuggestion(t, Analyze(app, "test.human").Warnings(), "meter API calls")
+}
+
+// ── API keys (W144) ──
+
+func TestAPIKeys(t *testing.T) {
+	app := minApp()
+	app.Auth = &ir.Auth{APIKeys: &ir.APIKeys{Model: "User", Page: "ApiKeys"}}
+	assertWarningSuggestion(t, Analyze(app, "test.human").Warnings(), "requires authentication")
+
+	app.APIs[0].Auth = true
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W144" {
+			t.Errorf("API keys are complete: %s", w.Message)
+		}
+	}
+
+	// The app's own ApiKey model is kept, and no keys are issued
+	app.Auth.APIKeys.Model = ""
+	app.Data = append(app.Data, &ir.DataModel{Name: "ApiKey"})
+	assertWarningSuggestion(t, Analyze(app, "test.human").Warnings(), "Rename the app's model")
+}
+
+func TestMongoDB(t *testing.T) {
+	app := minApp()
+	app.Config.Database = "MongoDB"
+	app.Database = &ir.DatabaseConfig{Engine: "MongoDB"}
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W145" || w.Code == "W146" || w.Code == "W147" {
+			t.Errorf("Node with MongoDB is supported: %s", w.Message)
+		}
+	}
+
+	app.Config.Backend = "Python with FastAPI"
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W145")
+
+	app.Config.Backend = "Node with Express"
+	app.Data[0].Relations = []*ir.Relation{{Kind: "has_many_through", Target: "Task", Through: "Assignment"}}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W146")
+
+	app.Database.ReadReplica = true
+	assertWarningSuggestion(t, Analyze(app, "test.human").Warnings(), "readPreference=secondaryPreferred")
+}
+
+// ── Model hooks (E114, W148, W149) ──
+
+func TestModelHooks(t *testing.T) {
+	app := minApp()
+	app.Hooks = []*ir.ModelHook{
+		{Model: "Task", Timing: "before", Event: "create", Steps: []*ir.Action{{Type: "update", Text: "set its status to done"}}},
+		{Model: "User", Timing: "after", Event: "delete", Steps: []*ir.Action{{Type: "delete", Text: "remove their Tasks"}}},
+	}
+	errs := Analyze(app, "test.human")
+	if errs.HasErrors() || errs.HasWarnings() {
+		t.Fatalf("expected hooks to be clean, got:\n%s", errs.Format())
+	}
+
+	app.Hooks = []*ir.ModelHook{{Model: "Tsk", Timing: "before", Event: "create"}}
+	errs = Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E114")
+	assertSuggestion(t, errs.Errors(), "Task")
+
+	app.Hooks = []*ir.ModelHook{{Model: "the cart is emptied", Timing: "after"}}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W148")
+
+	// An after hook can't set a field the record was already written with
+	app.Hooks = []*ir.ModelHook{{Model: "Task", Timing: "after", Event: "create", Steps: []*ir.Action{{Type: "update", Text: "set its status to done"}}}}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W149")
+}
+
+// ── Remembered list views (W150) ──
+
+func TestRememberedViews(t *testing.T) {
+	app := minApp()
+	app.Pages = []*ir.Page{{Name: "Tasks", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "configure", Text: "remember the user's filters when they come back"},
+	}}}
+	if errs := Analyze(app, "test.human"); errs.HasWarnings() {
+		t.Fatalf("expected no warnings, got:\n%s", errs.Format())
+	}
+
+	app.Pages[0].Content = app.Pages[0].Content[1:]
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W150")
+}
+
+// ── Seed data (W151–W152) ──
+
+func TestSeeds(t *testing.T) {
+	app := minApp()
+	app.Seeds = []*ir.Seed{{Model: "Task", Count: 50, Text: "50 Tasks"}}
+	if errs := Analyze(app, "test.human"); errs.HasWarnings() {
+		t.Fatalf("expected no warnings, got:\n%s", errs.Format())
+	}
+
+	app.Seeds = []*ir.Seed{{Text: "some Tasks"}}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W151")
+
+	app.Seeds = []*ir.Seed{{Model: "Widgets", Count: 5, Text: "5 Widgets"}}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W151")
+
+	app.Seeds = []*ir.Seed{{Model: "Task", Count: 5, Text: "5 Tasks"}, {Model: "Task", Count: 9, Text: "9 Tasks"}}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W152")
+}
+
+// ── Interpolated text (E112, E113) ──
+
+func templateApp() *ir.Application {
+	app := minApp()
+	app.Pages = []*ir.Page{{Name: "Dashboard", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "display", Text: "show Next up: {task.title}", Template: "Next up: {task.title}"},
+	}}}
+	app.Components = []*ir.Component{{Name: "UserCard", Props: []*ir.Prop{{Name: "user", Type: "User"}}, Content: []*ir.Action{
+		{Type: "display", Text: "show Signed in as {user.name}", Template: "Signed in as {user.name}"},
+	}}}
+	return app
+}
+
+func TestShowTemplateValid(t *testing.T) {
+	errs := Analyze(templateApp(), "test.human")
+	if errs.HasErrors() {
+		t.Fatalf("expected no errors, got:\n%s", errs.Format())
+	}
+}
+
+func TestShowTemplateModelNotLoaded(t *testing.T) {
+	app := templateApp()
+	app.Pages[0].Content[1].Template = "Hello, {user.name}"
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E112")
+	assertSuggestion(t, errs.Errors(), "fetch all users")
+}
+
+func TestShowTemplateUnknownRoot(t *testing.T) {
+	app := templateApp()
+	app.Components[0].Content[0].Template = "Signed in as {usr.name}"
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E112")
+	assertSuggestion(t, errs.Errors(), "user")
+}
+
+func TestShowTemplateUnknownField(t *testing.T) {
+	app := templateApp()
+	app.Pages[0].Content[1].Template = "Next up: {task.titel}"
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E113")
+	assertSuggestion(t, errs.Errors(), "title")
+}
+
+func TestShowTemplateWithoutField(t *testing.T) {
+	app := templateApp()
+	app.Components[0].Content[0].Template = "Signed in as {user}"
+	errs := Analyze(app, "test.human")
+	assertCode(t, errs.Errors(), "E113")
+}
+
+// ── Charts (W119) ──
+
+func TestCharts(t *testing.T) {
+	app := minApp()
+	app.Pages = []*ir.Page{{Name: "Dashboard", Content: []*ir.Action{
+		{Type: "display", Text: "show a bar chart of tasks by status"},
+		{Type: "display", Text: "show a line chart of tasks per week"},
+	}}}
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W119" {
+			t.Errorf("valid chart warned: %s", w.Message)
+		}
+	}
+
+	app.Pages[0].Content = []*ir.Action{{Type: "display", Text: "show a bar chart of tasks by stats"}}
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W119")
+	assertWarningSuggestion(t, errs.Warnings(), "status")
+
+	app.Pages[0].Content = []*ir.Action{{Type: "display", Text: "show a bar chart of the weather by city"}}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W119")
+
+	app.Pages[0].Content = []*ir.Action{{Type: "display", Text: "show a bar chart of tasks"}}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W119")
+}
+
+func TestReports(t *testing.T) {
+	app := minApp()
+	app.Data[1].Fields = append(app.Data[1].Fields, &ir.DataField{Name: "points", Type: "number"})
+	report := &ir.Endpoint{Name: "TaskPoints", Steps: []*ir.Action{{Type: "unknown", Text: "sum the points of Tasks by status"}}}
+	app.APIs = append(app.APIs, report)
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W120" {
+			t.Errorf("valid report warned: %s", w.Message)
+		}
+	}
+
+	report.Steps[0].Text = "sum the points of Tasks by stats"
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W120")
+	assertWarningSuggestion(t, errs.Warnings(), "status")
+
+	report.Steps[0].Text = "average the Tasks by status"
+	errs = Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W120")
+	assertWarningSuggestion(t, errs.Warnings(), "points")
+}
+
+// ── Drag and keyboard interactions (W121, W122) ──
+
+func TestInteractions(t *testing.T) {
+	app := minApp()
+	app.Pages[0].Content = []*ir.Action{
+		{Type: "interact", Text: "dragging a task reorders the list"},
+		{Type: "interact", Text: "pressing Escape closes the modal"},
+		{Type: "interact", Text: "pressing Ctrl K navigates to Dashboard"},
+	}
+	for _, w := range Analyze(app, "test.human").Warnings() {
+		if w.Code == "W121" || w.Code == "W122" {
+			t.Errorf("valid interaction warned: %s", w.Message)
+		}
+	}
+
+	app.Pages[0].Content = []*ir.Action{{Type: "interact", Text: "dragging the weather reorders the list"}}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W121")
+
+	app.Pages[0].Content = []*ir.Action{{Type: "interact", Text: "pressing Escape sings a song"}}
+	assertWarningCode(t, Analyze(app, "test.human").Warnings(), "W122")
+
+	app.Pages[0].Content = []*ir.Action{{Type: "interact", Text: "pressing Ctrl K navigates to Dashbord"}}
+	errs := Analyze(app, "test.human")
+	assertWarningCode(t, errs.Warnings(), "W122")
+	assertWarningSuggestion(t, errs.Warnings(), "Dashboard")
+}
@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// lazyPattern is a precompiled pattern guarded by the words any match must
+// contain. Most statements contain none of them, and looking for a word
+// is far cheaper than running a case-insensitive regular expression, so
+// the expression only runs where it could match.
+type lazyPattern struct {
+	re    *regexp.Regexp
+	words []string // lowercase ASCII; every match contains one
+}
+
+// lazy compiles expr, to run only on text containing one of words.
+func lazy(expr string, words ...string) *lazyPattern {
+	return &lazyPattern{re: regexp.MustCompile(expr), words: words}
+}
+
+func (p *lazyPattern) mayMatch(s string) bool {
+	for _, w := range p.words {
+		if containsFold(s, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchString reports whether s contains a match of the pattern.
+func (p *lazyPattern) MatchString(s string) bool {
+	return p.mayMatch(s) && p.re.MatchString(s)
+}
+
+// FindAllStringSubmatch returns the pattern's matches in s, as
+// regexp.Regexp's does.
+func (p *lazyPattern) FindAllStringSubmatch(s string, n int) [][]string {
+	if !p.mayMatch(s) {
+		return nil
+	}
+	return p.re.FindAllStringSubmatch(s, n)
+}
+
+// containsFold reports whether s contains word, a lowercase ASCII word,
+// in any case.
+func containsFold(s, word string) bool {
+	for i := 0; i+len(word) <= len(s); i++ {
+		if s[i]|0x20 == word[0] && strings.EqualFold(s[i:i+len(word)], word) {
+			return true
+		}
+	}
+	return false
+}
+
+// crudTargets returns the word after each create, fetch, update, or
+// delete in s, skipping an "a" or "the" between them. It finds what
+// `(?i)\b(create|fetch|update|delete)\s+(?:a\s+|the\s+)?(\w+)\b` would,
+// in one pass over s: every API step runs through it, and most name a
+// verb, so a guarded regular expression would run on nearly all of them.
+func crudTargets(s string) []string {
+	var targets []string
+	for i := 0; i < len(s); {
+		verb, end := nextWord(s, i)
+		if verb == "" {
+			break
+		}
+		i = end
+		if !isCRUDVerb(verb) {
+			continue
+		}
+		start := skipSpace(s, end)
+		if start == end {
+			continue // the verb must be followed by whitespace
+		}
+		target, targetEnd := wordAt(s, start)
+		if target == "" {
+			continue
+		}
+		if strings.EqualFold(target, "a") || strings.EqualFold(target, "the") {
+			if next := skipSpace(s, targetEnd); next > targetEnd {
+				if word, wordEnd := wordAt(s, next); word != "" {
+					target, targetEnd = word, wordEnd
+				}
+			}
+		}
+		targets = append(targets, target)
+		i = targetEnd
+	}
+	return targets
+}
+
+func isCRUDVerb(w string) bool {
+	return strings.EqualFold(w, "create") || strings.EqualFold(w, "fetch") ||
+		strings.EqualFold(w, "update") || strings.EqualFold(w, "delete")
+}
+
+// nextWord returns the first run of word characters at or after i, and
+// the offset it ends at.
+func nextWord(s string, i int) (string, int) {
+	for i < len(s) && !isWordByte(s[i]) {
+		i++
+	}
+	return wordAt(s, i)
+}
+
+// wordAt returns the run of word characters starting at i, empty when
+// s[i] isn't one.
+func wordAt(s string, i int) (string, int) {
+	j := i
+	for j < len(s) && isWordByte(s[j]) {
+		j++
+	}
+	return s[i:j], j
+}
+
+// skipSpace returns the offset of the first non-space at or after i.
+func skipSpace(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n' || s[i] == '\r' || s[i] == '\f') {
+		i++
+	}
+	return i
+}
+
+// isWordByte reports whether c is a word character, as \w matches.
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// crudTargets must find what the regular expression it replaced did.
+func TestCRUDTargetsMatchPattern(t *testing.T) {
+	re := regexp.MustCompile(`(?i)\b(create|fetch|update|delete)\s+(?:a\s+|the\s+)?(\w+)\b`)
+	texts := []string{
+		"create a Task with the given fields",
+		"fetch all tasks for the current user",
+		"Update the task and DELETE the Comment",
+		"delete the",
+		"create a",
+		"create a  ",
+		"create the, task",
+		"recreate the task",
+		"pre-create the task",
+		"_create task",
+		"create\ttask",
+		"create\n  the\n  task",
+		"fetch the update task",
+		"fetch café order",
+		"create-task now",
+		"createtask",
+		"",
+		"send an email and create an invoice_2 for the user",
+	}
+	for _, text := range texts {
+		var want []string
+		for _, m := range re.FindAllStringSubmatch(text, -1) {
+			want = append(want, m[2])
+		}
+		if got := crudTargets(text); !slices.Equal(got, want) {
+			t.Errorf("crudTargets(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestLazyPatternMatchesRegexp(t *testing.T) {
+	texts := []string{
+		"Send Email to the user",
+		"send a welcome email",
+		"alert the team on Slack",
+		"notify everyone",
+		"SEND an SMS reminder",
+	}
+	for _, p := range []*lazyPattern{sendEmailPattern, slackAlertPattern, sendSMSPattern} {
+		for _, text := range texts {
+			if got, want := p.MatchString(text), p.re.MatchString(text); got != want {
+				t.Errorf("%s on %q: got %v, want %v", p.re, text, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkCRUDTargets(b *testing.B) {
+	re := regexp.MustCompile(`(?i)\b(create|fetch|update|delete)\s+(?:a\s+|the\s+)?(\w+)\b`)
+	text := "when the order is paid, update the Order status and create an Invoice for the customer"
+	b.Run("regexp", func(b *testing.B) {
+		for b.Loop() {
+			re.FindAllStringSubmatch(text, -1)
+		}
+	})
+	b.Run("scan", func(b *testing.B) {
+		for b.Loop() {
+			crudTargets(text)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/plugin"
+	"github.com/barun-bash/human/internal/version"
+)
+
+// CacheDir is where incremental builds keep their cache.
+var CacheDir = filepath.Join(".human", "cache")
+
+const cacheFile = "build.json"
+
+// Cache records what the last build gave each generator and what it wrote,
+// so the next build can skip generators whose inputs haven't changed.
+type Cache struct {
+	Compiler    string                      `json:"compiler"`
+	IRHash      string                      `json:"ir_hash"`
+	GeneratedAt string                      `json:"generated_at"`
+	Sections    map[string]string           `json:"sections"`
+	Generators  map[string]*CachedGenerator `json:"generators"`
+}
+
+// CachedGenerator is a generator's entry in the build cache.
+type CachedGenerator struct {
+	Key   string   `json:"key"`   // hash of the IR sections it reads
+	Files int      `json:"files"` // as counted for the build summary
+	Paths []string `json:"paths"` // files written, relative to the output dir
+}
+
+// LoadCache reads the build cache in dir. A missing or unreadable cache, or
+// one written by another compiler, is an empty one: everything reruns.
+func LoadCache(dir string) *Cache {
+	empty := &Cache{Compiler: version.Info(), Generators: map[string]*CachedGenerator{}}
+	data, err := os.ReadFile(filepath.Join(dir, cacheFile))
+	if err != nil {
+		return empty
+	}
+	var c Cache
+	if json.Unmarshal(data, &c) != nil || c.Compiler != version.Info() || c.Generators == nil {
+		return empty
+	}
+	return &c
+}
+
+// ClearCache removes the build cache in dir, so the next build runs every
+// generator.
+func ClearCache(dir string) error {
+	if err := os.Remove(filepath.Join(dir, cacheFile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Save writes the cache to dir.
+func (c *Cache) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, cacheFile), append(data, '\n'), 0644)
+}
+
+// Sections hashes app's IR in parts: each data model, page, and endpoint
+// on its own ("data/Order", "pages/Home", "apis/CreateOrder"), the order
+// they're declared in ("data", "pages", "apis"), and every other top-level
+// key as a whole ("config", "theme", ...).
+func Sections(app *ir.Application) (map[string]string, error) {
+	data, err := json.Marshal(app)
+	if err != nil {
+		return nil, err
+	}
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, err
+	}
+	sections := map[string]string{}
+	for key, raw := range top {
+		sections[key] = hashBytes(raw)
+	}
+	if err := hashNamed(sections, "data", app.Data, func(m *ir.DataModel) string { return m.Name }); err != nil {
+		return nil, err
+	}
+	if err := hashNamed(sections, "pages", app.Pages, func(p *ir.Page) string { return p.Name }); err != nil {
+		return nil, err
+	}
+	if err := hashNamed(sections, "apis", app.APIs, func(e *ir.Endpoint) string { return e.Name }); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+// hashNamed hashes each item under key/name, and the order of their names
+// under key.
+func hashNamed[T any](sections map[string]string, key string, items []T, name func(T) string) error {
+	var names []string
+	for _, item := range items {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		sections[key+"/"+name(item)] = hashBytes(raw)
+		names = append(names, name(item))
+	}
+	sections[key] = hashBytes([]byte(strings.Join(names, "\n")))
+	return nil
+}
+
+// inputKey hashes the sections g reads, with the project config (which
+// can change what generators write), or returns "" for generators that
+// must always run: external plugins, which the cache can't see into.
+func inputKey(g codegen.CodeGenerator, sections map[string]string, config []byte) string {
+	if _, ok := g.(*plugin.ExternalGenerator); ok {
+		return ""
+	}
+	var inputs []string
+	if d, ok := g.(codegen.InputDeclarer); ok {
+		inputs = d.Inputs()
+	}
+	var lines []string
+	for key, hash := range sections {
+		if reads(inputs, key) {
+			lines = append(lines, key+"="+hash)
+		}
+	}
+	sort.Strings(lines)
+	lines = append(lines, "generator="+g.Meta().Name+"@"+g.Meta().Version, "config="+hashBytes(config))
+	return hashBytes([]byte(strings.Join(lines, "\n")))
+}
+
+// reads reports whether a generator with the given inputs reads section:
+// all of them when it declares none.
+func reads(inputs []string, section string) bool {
+	if inputs == nil {
+		return true
+	}
+	top, _, _ := strings.Cut(section, "/")
+	for _, in := range inputs {
+		if in == top {
+			return true
+		}
+	}
+	return false
+}
+
+// fresh returns g's cache entry when its key is unchanged and the files it
+// wrote are all still in outputDir.
+func (c *Cache) fresh(name, key, outputDir string) *CachedGenerator {
+	entry := c.Generators[name]
+	if key == "" || entry == nil || entry.Key != key {
+		return nil
+	}
+	for _, p := range entry.Paths {
+		if _, err := os.Stat(filepath.Join(outputDir, p)); err != nil {
+			return nil
+		}
+	}
+	return entry
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
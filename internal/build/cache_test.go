@@ -0,0 +1,195 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/codegen/diagrams"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// countingGenerator writes one file and counts its runs. With inputs set,
+// it declares them.
+type countingGenerator struct {
+	name   string
+	inputs []string
+	runs   *int
+}
+
+func (g countingGenerator) Meta() codegen.PluginMeta {
+	return codegen.PluginMeta{Name: g.name, Version: "1.0.0"}
+}
+func (g countingGenerator) Enabled(*ir.Application) bool { return true }
+func (g countingGenerator) StageName() string            { return "Generating " + g.name }
+func (g countingGenerator) OutputDir() string            { return g.name }
+
+func (g countingGenerator) Generate(app *ir.Application, outputDir string) error {
+	*g.runs++
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputDir, "out.txt"), []byte(app.Name), 0644)
+}
+
+type declaringGenerator struct{ countingGenerator }
+
+func (g declaringGenerator) Inputs() []string { return g.inputs }
+
+func TestSections(t *testing.T) {
+	app := &ir.Application{
+		Name:  "Shop",
+		Data:  []*ir.DataModel{{Name: "Order"}, {Name: "Product"}},
+		Pages: []*ir.Page{{Name: "Home"}},
+	}
+	before, err := Sections(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"name", "data", "data/Order", "data/Product", "pages/Home"} {
+		if before[key] == "" {
+			t.Errorf("missing section %q", key)
+		}
+	}
+
+	app.Data[1].Fields = append(app.Data[1].Fields, &ir.DataField{Name: "price", Type: "decimal"})
+	after, _ := Sections(app)
+	if after["data/Product"] == before["data/Product"] {
+		t.Error("changing Product should change its hash")
+	}
+	if after["data/Order"] != before["data/Order"] || after["pages/Home"] != before["pages/Home"] {
+		t.Error("changing Product should not change other sections")
+	}
+
+	app.Data[0], app.Data[1] = app.Data[1], app.Data[0]
+	reordered, _ := Sections(app)
+	if reordered["data"] == after["data"] {
+		t.Error("reordering models should change the data section")
+	}
+}
+
+func TestIncrementalBuild(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("HUMAN_OFFLINE", "1")
+
+	var wholeRuns, dataRuns int
+	reg := codegen.NewRegistry()
+	reg.Register(countingGenerator{name: "whole", runs: &wholeRuns})
+	reg.Register(declaringGenerator{countingGenerator{name: "schema", inputs: []string{"data"}, runs: &dataRuns}})
+
+	app := func(name string, fields ...string) *ir.Application {
+		m := &ir.DataModel{Name: "Task"}
+		for _, f := range fields {
+			m.Fields = append(m.Fields, &ir.DataField{Name: f, Type: "text"})
+		}
+		return &ir.Application{Name: name, Data: []*ir.DataModel{m}}
+	}
+	build := func(a *ir.Application) []Result {
+		t.Helper()
+		results, _, _, err := runGenerators(reg, a, "output", "cache", ".", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+	skipped := func(results []Result) map[string]bool {
+		m := map[string]bool{}
+		for _, r := range results {
+			if r.Skipped {
+				m[r.Name] = true
+			}
+		}
+		return m
+	}
+
+	first := build(app("Tasks", "title"))
+	if wholeRuns != 1 || dataRuns != 1 || len(skipped(first)) != 0 {
+		t.Fatalf("a first build should run everything, got runs %d/%d", wholeRuns, dataRuns)
+	}
+	firstInfo := readFile(t, filepath.Join("output", "build-info.json"))
+
+	second := build(app("Tasks", "title"))
+	if wholeRuns != 1 || dataRuns != 1 {
+		t.Errorf("an unchanged build should skip both generators, got runs %d/%d", wholeRuns, dataRuns)
+	}
+	if s := skipped(second); !s["whole"] || !s["schema"] || s["quality"] || s["scaffold"] {
+		t.Errorf("skipped = %v, want whole and schema", s)
+	}
+	if r := second[0]; r.Files != first[0].Files || len(r.Paths) != 1 {
+		t.Errorf("a skipped generator should report the cached files, got %d %v", r.Files, r.Paths)
+	}
+	if info := readFile(t, filepath.Join("output", "build-info.json")); info != firstInfo {
+		t.Error("an unchanged build should keep the cached build's provenance")
+	}
+
+	build(app("Task List", "title"))
+	if wholeRuns != 2 || dataRuns != 1 {
+		t.Errorf("renaming the app should rerun only the generator reading everything, got runs %d/%d", wholeRuns, dataRuns)
+	}
+
+	build(app("Task List", "title", "notes"))
+	if wholeRuns != 3 || dataRuns != 2 {
+		t.Errorf("adding a field should rerun both, got runs %d/%d", wholeRuns, dataRuns)
+	}
+
+	os.Remove(filepath.Join("output", "schema", "out.txt"))
+	build(app("Task List", "title", "notes"))
+	if wholeRuns != 3 || dataRuns != 3 {
+		t.Errorf("only the generator whose files are missing should rerun, got runs %d/%d", wholeRuns, dataRuns)
+	}
+
+	if err := ClearCache("cache"); err != nil {
+		t.Fatal(err)
+	}
+	build(app("Task List", "title", "notes"))
+	if wholeRuns != 4 || dataRuns != 4 {
+		t.Errorf("a build after clearing the cache should run everything, got runs %d/%d", wholeRuns, dataRuns)
+	}
+}
+
+func TestIncrementalBuildRedrawsDiagramsWhenAPIsChange(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("HUMAN_OFFLINE", "1")
+
+	reg := codegen.NewRegistry()
+	reg.Register(diagrams.Generator{})
+	app := func(apis ...string) *ir.Application {
+		a := &ir.Application{
+			Name:         "Shop",
+			Config:       &ir.BuildConfig{Backend: "Node with Express", Database: "PostgreSQL"},
+			Architecture: &ir.Architecture{Style: "serverless"},
+		}
+		for _, name := range apis {
+			a.APIs = append(a.APIs, &ir.Endpoint{Name: name})
+		}
+		return a
+	}
+	diagram := filepath.Join("output", "docs", "architecture.mmd")
+
+	if _, _, _, err := runGenerators(reg, app("ListOrders"), "output", "cache", ".", nil); err != nil {
+		t.Fatal(err)
+	}
+	before := readFile(t, diagram)
+
+	results, _, _, err := runGenerators(reg, app("ListOrders", "CreateOrder"), "output", "cache", ".", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Skipped {
+		t.Error("adding an API should rerun the diagrams")
+	}
+	if after := readFile(t, diagram); after == before || !strings.Contains(after, "2 Lambda functions") {
+		t.Errorf("the architecture diagram should count the new API, got:\n%s", after)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -1,18 +1,22 @@
 package build
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/barun-bash/human/internal/cli"
 	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/codegen/scaffold"
-	"github.com/barun-bash/human/internal/codegen/storybook"
 	"github.com/barun-bash/human/internal/config"
 	"github.com/barun-bash/human/internal/ir"
 	"github.com/barun-bash/human/internal/plugin"
 	"github.com/barun-bash/human/internal/quality"
+	"github.com/barun-bash/human/internal/version"
 )
 
 // Result tracks the output of a single generator.
@@ -21,6 +25,8 @@ type Result struct {
 	Dir      string
 	Files    int
 	Duration time.Duration
+	Paths    []string // files written, relative to the output dir
+	Skipped  bool     // inputs unchanged since the last build; Files and Paths are the last build's
 }
 
 // BuildTiming holds the total build duration.
@@ -36,14 +42,45 @@ func MatchesGoBackend(backend string) bool {
 
 // CountFiles returns the number of regular files under dir.
 func CountFiles(dir string) int {
-	count := 0
-	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
-		if err == nil && !d.IsDir() {
-			count++
+	return codegen.CountFiles(dir)
+}
+
+// modTimes returns the modification time of each file under dir, keyed by
+// its path relative to dir. Dependencies installed into the output
+// (node_modules) are skipped: nothing generates them, and they're large.
+func modTimes(dir string) map[string]time.Time {
+	times := map[string]time.Time{}
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			if rel, err := filepath.Rel(dir, path); err == nil {
+				times[rel] = info.ModTime()
+			}
 		}
 		return nil
 	})
-	return count
+	return times
+}
+
+// changedFiles returns the files under dir that are new or modified since
+// the before snapshot, sorted.
+func changedFiles(dir string, before map[string]time.Time) []string {
+	var paths []string
+	for path, mod := range modTimes(dir) {
+		if prev, ok := before[path]; !ok || !prev.Equal(mod) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
 }
 
 // PlanStages returns the list of stage names that will run for the given app.
@@ -65,6 +102,12 @@ func PlanStagesWithRegistry(reg *codegen.Registry, app *ir.Application) []string
 	return stages
 }
 
+// NewBuildInfo returns the provenance of generating app's code with this
+// compiler at the given time.
+func NewBuildInfo(app *ir.Application, at time.Time) (*ir.BuildInfo, error) {
+	return ir.NewBuildInfo(app, strings.TrimPrefix(version.Version, "v"), at)
+}
+
 // ProgressFunc is called before each build stage with the stage name.
 type ProgressFunc func(stage string)
 
@@ -81,10 +124,32 @@ func RunGeneratorsWithProgress(app *ir.Application, outputDir string, progress P
 	return RunGeneratorsWithRegistry(DefaultRegistryWithPlugins(), app, outputDir, progress)
 }
 
+// RunGeneratorsIncremental is like RunGeneratorsWithProgress but keeps a
+// build cache in cacheDir, skipping generators whose IR inputs haven't
+// changed since the last build into outputDir. Quality and scaffold always
+// run.
+func RunGeneratorsIncremental(app *ir.Application, outputDir, cacheDir string, progress ProgressFunc) ([]Result, *quality.Result, *BuildTiming, error) {
+	return runGenerators(DefaultRegistryWithPlugins(), app, outputDir, cacheDir, ".", progress)
+}
+
 // RunGeneratorsWithRegistry dispatches generators from the given registry,
 // then runs the quality engine and scaffolder. This allows custom registries
 // for testing or plugin scenarios.
 func RunGeneratorsWithRegistry(reg *codegen.Registry, app *ir.Application, outputDir string, progress ProgressFunc) ([]Result, *quality.Result, *BuildTiming, error) {
+	return runGenerators(reg, app, outputDir, "", ".", progress)
+}
+
+// RunGeneratorsIsolated builds app with the built-in generators only, and
+// with projectDir's config rather than the working directory's. It's for
+// building sources sent from elsewhere, as human serve does, which mustn't
+// run the host's plugins or take its project's settings.
+func RunGeneratorsIsolated(app *ir.Application, outputDir, projectDir string) ([]Result, *quality.Result, *BuildTiming, error) {
+	return runGenerators(DefaultRegistry(), app, outputDir, "", projectDir, nil)
+}
+
+// runGenerators runs the build, with the build cache in cacheDir unless
+// it's empty, and the config of the project in projectDir.
+func runGenerators(reg *codegen.Registry, app *ir.Application, outputDir, cacheDir, projectDir string, progress ProgressFunc) ([]Result, *quality.Result, *BuildTiming, error) {
 	buildStart := time.Now()
 	var results []Result
 
@@ -94,20 +159,84 @@ func RunGeneratorsWithRegistry(reg *codegen.Registry, app *ir.Application, outpu
 		}
 	}
 
+	// Each stage's files are found by comparing the output dir before and
+	// after it, for --verbose and the build summary's per-generator sizes.
+	var before map[string]time.Time
+	snapshot := func() {
+		before = modTimes(outputDir)
+	}
+
 	timeGen := func(name, dir string, files int, start time.Time) Result {
-		return Result{Name: name, Dir: dir, Files: files, Duration: time.Since(start)}
+		r := Result{Name: name, Dir: dir, Files: files, Duration: time.Since(start)}
+		r.Paths = changedFiles(outputDir, before)
+		return r
 	}
 
+	// The cache is removed until the build finishes, so a failed or
+	// interrupted one leaves none to skip generators with.
+	var cache *Cache
+	if cacheDir != "" {
+		cache = LoadCache(cacheDir)
+		ClearCache(cacheDir)
+	}
+
+	// Provenance for build-info.json and the backends' X-Human-Build
+	// header, hashed before the build adjusts the IR below. Rebuilding an
+	// unchanged IR keeps the cached build's date, which skipped generators
+	// wrote into their files.
+	if app.Build == nil {
+		at := time.Now()
+		if cache != nil && cache.IRHash != "" {
+			if hash, err := ir.Hash(app); err == nil && hash == cache.IRHash {
+				if t, err := time.Parse(time.RFC3339, cache.GeneratedAt); err == nil {
+					at = t
+				}
+			}
+		}
+		info, err := NewBuildInfo(app, at)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("build info: %w", err)
+		}
+		app.Build = info
+	}
+	app.Build.Fsync = config.Fsync(projectDir)
+	app.Build.EmitWorkers = config.EmitWorkers(projectDir)
+
 	// Load project config for tri-state overrides and plugin settings.
-	cfg, _ := config.Load(".")
+	cfg, _ := config.Load(projectDir)
+
+	// Offline builds bundle theme fonts instead of linking Google Fonts.
+	if app.Theme != nil && config.Offline(projectDir) {
+		app.Theme.LocalFonts = true
+	}
 
 	// Get enabled generators, respecting config overrides.
 	enabled := reg.EnabledWithConfig(app, cfg)
 
+	// Hash the IR in sections, after the adjustments above, so each
+	// generator's inputs can be compared with the last build's.
+	var next *Cache
+	var sections map[string]string
+	var cfgData []byte
+	if cache != nil {
+		var err error
+		if sections, err = Sections(app); err != nil {
+			return nil, nil, nil, fmt.Errorf("build cache: %w", err)
+		}
+		cfgData, _ = json.Marshal(cfg)
+		next = &Cache{
+			Compiler:    cache.Compiler,
+			IRHash:      app.Build.IRHash,
+			GeneratedAt: app.Build.GeneratedAt,
+			Sections:    sections,
+			Generators:  map[string]*CachedGenerator{},
+		}
+	}
+
 	// Inject settings into external generators.
 	for _, g := range enabled {
-		if ext, ok := g.(*plugin.ExternalGenerator); ok {
-			if settings := cfg.PluginSettings(ext.Meta().Name); settings != nil {
+		if ext, ok := g.(plugin.Configurable); ok {
+			if settings := cfg.PluginSettings(g.Meta().Name); settings != nil {
 				ext.SetSettings(settings)
 			}
 		}
@@ -117,29 +246,36 @@ func RunGeneratorsWithRegistry(reg *codegen.Registry, app *ir.Application, outpu
 	for _, g := range enabled {
 		name := g.Meta().Name
 		report(g.StageName())
+		snapshot()
 		start := time.Now()
 
 		// Resolve target directory.
-		var dir string
-		switch name {
-		case "storybook":
-			// Storybook generates into the frontend directory, not standalone.
-			dir = resolveStorybookDir(app, outputDir)
-			if dir == "" {
-				fmt.Printf("  note: skipping Storybook (unsupported frontend %q)\n", app.Config.Frontend)
+		dir, root := filepath.Join(outputDir, g.OutputDir()), g.OutputDir() == ""
+		if r, ok := g.(codegen.DirResolver); ok {
+			root = false
+			var err error
+			if dir, err = r.TargetDir(app, outputDir); err != nil {
+				cli.Printf("  note: skipping %s (%v)\n", name, err)
 				continue
 			}
-		default:
-			if od := g.OutputDir(); od != "" {
-				dir = filepath.Join(outputDir, od)
-			} else {
-				dir = outputDir
+		}
+
+		// Skip generators whose inputs haven't changed.
+		var key string
+		if cache != nil {
+			key = inputKey(g, sections, cfgData)
+			if entry := cache.fresh(name, key, outputDir); entry != nil {
+				results = append(results, Result{Name: name, Dir: dir, Files: entry.Files, Paths: entry.Paths, Skipped: true})
+				next.Generators[name] = entry
+				continue
 			}
 		}
 
-		// For Docker, count files before generation so we can diff.
+		// A generator sharing the output root without counting its own
+		// files is counted by the files its run adds.
+		counter, counts := g.(codegen.FileCounter)
 		var beforeCount int
-		if name == "docker" {
+		if !counts && root {
 			beforeCount = CountFiles(outputDir)
 		}
 
@@ -148,35 +284,30 @@ func RunGeneratorsWithRegistry(reg *codegen.Registry, app *ir.Application, outpu
 			return nil, nil, nil, fmt.Errorf("%s codegen: %w", name, err)
 		}
 
-		// Storybook: call GetFramework so scaffold knows what deps to inject.
-		if name == "storybook" {
-			_ = storybook.GetFramework(app)
-		}
-
-		// Count generated files — each generator has a different counting strategy.
 		var files int
-		switch name {
-		case "storybook":
-			files = countStorybookFiles(dir)
-		case "docker":
+		switch {
+		case counts:
+			files = counter.CountFiles(dir)
+		case root:
 			files = CountFiles(outputDir) - beforeCount
-		case "cicd":
-			files = CountFiles(filepath.Join(outputDir, ".github"))
-		case "architecture":
-			files = CountFiles(filepath.Join(outputDir, "services")) +
-				CountFiles(filepath.Join(outputDir, "functions")) +
-				CountFiles(filepath.Join(outputDir, "gateway"))
 		default:
 			files = CountFiles(dir)
 		}
 
-		results = append(results, timeGen(name, dir, files, start))
+		r := timeGen(name, dir, files, start)
+		results = append(results, r)
+		if next != nil && key != "" {
+			next.Generators[name] = &CachedGenerator{Key: key, Files: files, Paths: r.Paths}
+		}
 	}
 
 	// Quality engine — always runs after code generators.
 	report("Running quality checks")
+	snapshot()
 	qualityStart := time.Now()
-	qResult, err := quality.Run(app, outputDir)
+	qResult, err := quality.RunWithProgress(app, outputDir, func(step string) {
+		report("Running quality checks: " + step)
+	})
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("quality engine: %w", err)
 	}
@@ -185,6 +316,7 @@ func RunGeneratorsWithRegistry(reg *codegen.Registry, app *ir.Application, outpu
 
 	// Scaffolder — always runs last.
 	report("Scaffolding project files")
+	snapshot()
 	scaffoldStart := time.Now()
 	sg := scaffold.Generator{}
 	if err := sg.Generate(app, outputDir); err != nil {
@@ -192,6 +324,12 @@ func RunGeneratorsWithRegistry(reg *codegen.Registry, app *ir.Application, outpu
 	}
 	results = append(results, timeGen("scaffold", outputDir, countScaffoldFiles(outputDir), scaffoldStart))
 
+	if next != nil {
+		if err := next.Save(cacheDir); err != nil {
+			return nil, nil, nil, fmt.Errorf("build cache: %w", err)
+		}
+	}
+
 	timing := &BuildTiming{Total: time.Since(buildStart)}
 	return results, qResult, timing, nil
 }
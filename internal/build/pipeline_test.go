@@ -1,6 +1,16 @@
 package build
 
-import "testing"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
 
 func TestMatchesGoBackend(t *testing.T) {
 	tests := []struct {
@@ -42,3 +52,112 @@ func TestCountFilesNonExistent(t *testing.T) {
 		t.Errorf("CountFiles(nonexistent) = %d, want 0", count)
 	}
 }
+
+func TestChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("kept.txt", "a")
+	write("rewritten.txt", "a")
+	old := time.Now().Add(-time.Hour)
+	for _, name := range []string{"kept.txt", "rewritten.txt"} {
+		if err := os.Chtimes(filepath.Join(dir, name), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := modTimes(dir)
+	write("rewritten.txt", "b")
+	write("src/new.txt", "c")
+	write("node_modules/dep/index.js", "d") // installed, not generated
+
+	got := strings.Join(changedFiles(dir, before), ",")
+	if want := strings.Join([]string{"rewritten.txt", filepath.Join("src", "new.txt")}, ","); got != want {
+		t.Errorf("changedFiles = %s, want %s", got, want)
+	}
+}
+
+func TestRegistryOf(t *testing.T) {
+	reg, err := RegistryOf([]string{"node", "React ", "postgres"})
+	if err != nil {
+		t.Fatalf("RegistryOf: %v", err)
+	}
+	if got := strings.Join(reg.Names(), ","); got != "react,node,postgres" {
+		t.Errorf("names = %s, want react,node,postgres in execution order", got)
+	}
+	if _, err := RegistryOf([]string{"cobol"}); err == nil || !strings.Contains(err.Error(), "known: react, vue") {
+		t.Errorf("expected an error listing the known generators, got %v", err)
+	}
+}
+
+// rootGenerator writes into the output root, as Docker does.
+type rootGenerator struct{ files []string }
+
+func (g rootGenerator) Meta() codegen.PluginMeta     { return codegen.PluginMeta{Name: "root"} }
+func (g rootGenerator) Enabled(*ir.Application) bool { return true }
+func (g rootGenerator) StageName() string            { return "Generating root files" }
+func (g rootGenerator) OutputDir() string            { return "" }
+func (g rootGenerator) Generate(_ *ir.Application, dir string) error {
+	for _, name := range g.files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nestedGenerator writes into another generator's directory, as
+// Storybook does, and counts its own files.
+type nestedGenerator struct{ rootGenerator }
+
+func (g nestedGenerator) Meta() codegen.PluginMeta { return codegen.PluginMeta{Name: "nested"} }
+func (g nestedGenerator) TargetDir(app *ir.Application, outputDir string) (string, error) {
+	if app.Config == nil {
+		return "", fmt.Errorf("no frontend")
+	}
+	return filepath.Join(outputDir, "web"), os.MkdirAll(filepath.Join(outputDir, "web"), 0755)
+}
+func (g nestedGenerator) CountFiles(dir string) int { return 1 }
+
+func TestRunGeneratorsResolvesDirsAndCounts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	out := t.TempDir()
+	os.WriteFile(filepath.Join(out, "existing.txt"), []byte("x"), 0644)
+
+	reg := codegen.NewRegistry()
+	reg.Register(rootGenerator{files: []string{"a.txt", "b.txt"}})
+	reg.Register(nestedGenerator{rootGenerator{files: []string{"c.txt", "d.txt"}}})
+	app := &ir.Application{Name: "Test", Config: &ir.BuildConfig{}}
+	results, _, _, err := RunGeneratorsWithRegistry(reg, app, out, nil)
+	if err != nil {
+		t.Fatalf("RunGeneratorsWithRegistry: %v", err)
+	}
+	if results[0].Name != "root" || results[0].Files != 2 {
+		t.Errorf("root result = %+v, want the 2 files it added", results[0])
+	}
+	if results[1].Name != "nested" || results[1].Dir != filepath.Join(out, "web") || results[1].Files != 1 {
+		t.Errorf("nested result = %+v, want its own count in web/", results[1])
+	}
+	if _, err := os.Stat(filepath.Join(out, "web", "c.txt")); err != nil {
+		t.Errorf("expected the nested generator to write into web/: %v", err)
+	}
+
+	app.Config = nil
+	results, _, _, err = RunGeneratorsWithRegistry(reg, app, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("RunGeneratorsWithRegistry: %v", err)
+	}
+	for _, r := range results {
+		if r.Name == "nested" {
+			t.Errorf("expected the nested generator to be skipped without a target dir")
+		}
+	}
+}
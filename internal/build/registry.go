@@ -1,7 +1,7 @@
 package build
 
 import (
-	"io/fs"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,11 +9,19 @@ import (
 	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/codegen/angular"
 	"github.com/barun-bash/human/internal/codegen/architecture"
+	"github.com/barun-bash/human/internal/codegen/asyncapi"
+	"github.com/barun-bash/human/internal/codegen/backup"
 	"github.com/barun-bash/human/internal/codegen/cicd"
+	"github.com/barun-bash/human/internal/codegen/diagrams"
 	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/codegen/fixtures"
+	"github.com/barun-bash/human/internal/codegen/flutter"
 	"github.com/barun-bash/human/internal/codegen/gobackend"
+	"github.com/barun-bash/human/internal/codegen/grpc"
+	"github.com/barun-bash/human/internal/codegen/hosting"
 	"github.com/barun-bash/human/internal/codegen/monitoring"
 	"github.com/barun-bash/human/internal/codegen/node"
+	"github.com/barun-bash/human/internal/codegen/openapi"
 	"github.com/barun-bash/human/internal/codegen/postgres"
 	"github.com/barun-bash/human/internal/codegen/python"
 	"github.com/barun-bash/human/internal/codegen/react"
@@ -21,13 +29,13 @@ import (
 	"github.com/barun-bash/human/internal/codegen/svelte"
 	"github.com/barun-bash/human/internal/codegen/terraform"
 	"github.com/barun-bash/human/internal/codegen/vue"
-	"github.com/barun-bash/human/internal/ir"
 	"github.com/barun-bash/human/internal/plugin"
 )
 
-// DefaultRegistry returns a registry populated with all 14 built-in code
-// generators in the correct execution order. Quality and scaffold are NOT
-// included — they are run as explicit post-loop steps in the pipeline.
+// DefaultRegistry returns a registry populated with all 22 built-in code
+// generators in the correct execution order, followed by those added with
+// codegen.Register. Quality and scaffold are NOT included — they are run as
+// explicit post-loop steps in the pipeline.
 func DefaultRegistry() *codegen.Registry {
 	reg := codegen.NewRegistry()
 
@@ -38,16 +46,24 @@ func DefaultRegistry() *codegen.Registry {
 		vue.Generator{},
 		angular.Generator{},
 		svelte.Generator{},
+		flutter.Generator{},
 		storybook.Generator{},
 		node.Generator{},
 		python.Generator{},
 		gobackend.Generator{},
+		grpc.Generator{},
+		asyncapi.Generator{},
+		openapi.Generator{},
 		postgres.Generator{},
+		fixtures.Generator{},
 		docker.Generator{},
 		cicd.Generator{},
+		backup.Generator{},
 		terraform.Generator{},
+		hosting.Generator{},
 		architecture.Generator{},
 		monitoring.Generator{},
+		diagrams.Generator{},
 	}
 
 	for _, g := range generators {
@@ -56,24 +72,51 @@ func DefaultRegistry() *codegen.Registry {
 			panic("built-in generator registration: " + err.Error())
 		}
 	}
+	for _, g := range codegen.Registered() {
+		if err := reg.Register(g); err != nil {
+			panic("generator registration: " + err.Error())
+		}
+	}
 
 	return reg
 }
 
+// RegistryOf returns a registry of the named built-in generators, in
+// execution order, for builds that run only some of them. An unknown name
+// is an error listing the known ones.
+func RegistryOf(names []string) (*codegen.Registry, error) {
+	all := DefaultRegistry()
+	want := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if all.Get(name) == nil {
+			return nil, fmt.Errorf("unknown generator %q (known: %s)", name, strings.Join(all.Names(), ", "))
+		}
+		want[name] = true
+	}
+
+	reg := codegen.NewRegistry()
+	for _, g := range all.All() {
+		if want[g.Meta().Name] {
+			_ = reg.Register(g)
+		}
+	}
+	return reg, nil
+}
+
 // DefaultRegistryWithPlugins returns a registry with all built-in generators
-// plus any external plugins discovered in ~/.human/plugins/. External plugins
-// that collide with built-in names are silently skipped.
+// plus any external plugins installed in ~/.human/plugins/, then the
+// human-gen-<name> generators on PATH. External plugins that collide with
+// an earlier name are silently skipped.
 func DefaultRegistryWithPlugins() *codegen.Registry {
 	reg := DefaultRegistry()
 
-	externals, err := plugin.LoadAll()
-	if err != nil {
-		// Plugin loading is best-effort; don't break the build.
-		return reg
-	}
+	// Plugin loading is best-effort; don't break the build.
+	externals, _ := plugin.LoadAll()
+	externals = append(externals, plugin.DiscoverPath()...)
 
 	for _, g := range externals {
-		// Skip if a built-in generator already has this name.
+		// Skip if an earlier generator already has this name.
 		if existing := reg.Get(g.Meta().Name); existing != nil {
 			continue
 		}
@@ -83,37 +126,10 @@ func DefaultRegistryWithPlugins() *codegen.Registry {
 	return reg
 }
 
-// resolveStorybookDir determines the frontend output directory for Storybook.
-// Storybook generates into the frontend directory, not a standalone subdirectory.
-func resolveStorybookDir(app *ir.Application, outputDir string) string {
-	if app.Config == nil {
-		return ""
-	}
-	frontendLower := strings.ToLower(app.Config.Frontend)
-	switch {
-	case strings.Contains(frontendLower, "react"):
-		return filepath.Join(outputDir, "react")
-	case strings.Contains(frontendLower, "vue"):
-		return filepath.Join(outputDir, "vue")
-	case strings.Contains(frontendLower, "angular"):
-		return filepath.Join(outputDir, "angular")
-	case strings.Contains(frontendLower, "svelte"):
-		return filepath.Join(outputDir, "svelte")
-	}
-	return ""
-}
-
-// countStorybookFiles counts the Storybook-specific files generated in a
-// frontend directory.
-func countStorybookFiles(frontendDir string) int {
-	return countFilesUnder(filepath.Join(frontendDir, ".storybook")) +
-		countFilesUnder(filepath.Join(frontendDir, "src", "stories"))
-}
-
 // countScaffoldFiles counts the scaffold-generated files across the output.
 func countScaffoldFiles(outputDir string) int {
 	count := 0
-	for _, name := range []string{"package.json", "README.md", ".env.example", "start.sh"} {
+	for _, name := range []string{"package.json", "turbo.json", "README.md", ".env.example", "start.sh"} {
 		if _, err := os.Stat(filepath.Join(outputDir, name)); err == nil {
 			count++
 		}
@@ -127,15 +143,3 @@ func countScaffoldFiles(outputDir string) int {
 	}
 	return count
 }
-
-// countFilesUnder returns the number of regular files under dir.
-func countFilesUnder(dir string) int {
-	count := 0
-	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
-		if err == nil && !d.IsDir() {
-			count++
-		}
-		return nil
-	})
-	return count
-}
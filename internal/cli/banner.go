@@ -101,7 +101,7 @@ type BannerInfo struct {
 // When animate is true and the writer is a TTY, the logo types in letter by
 // letter with a blinking underscore. Otherwise, the static logo is printed.
 func PrintBanner(w io.Writer, version string, animate bool, info *BannerInfo) {
-	if animate && isTTY(w) {
+	if animate && IsTTY(w) {
 		printAnimatedLogo(w)
 	} else {
 		printStaticLogo(w)
@@ -223,8 +223,8 @@ func accentCodes() (string, string) {
 	return c, reset
 }
 
-// isTTY returns true if w is a terminal.
-func isTTY(w io.Writer) bool {
+// IsTTY returns true if w is a terminal.
+func IsTTY(w io.Writer) bool {
 	f, ok := w.(*os.File)
 	if !ok {
 		return false
@@ -138,7 +138,7 @@ func TestRandomTipNotEmpty(t *testing.T) {
 
 func TestIsTTYBuffer(t *testing.T) {
 	var buf bytes.Buffer
-	if isTTY(&buf) {
+	if IsTTY(&buf) {
 		t.Error("bytes.Buffer should not be a TTY")
 	}
 }
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/barun-bash/human/internal/textdiff"
+)
+
+// diffContext is how many unchanged lines surround each hunk.
+const diffContext = 3
+
+// PrintDiff writes the change from oldSrc to newSrc as a colored unified
+// diff: removed lines red, added lines green, hunk headers in the accent
+// color, followed by a count of the lines changed.
+func PrintDiff(w io.Writer, oldSrc, newSrc string) {
+	hunks := textdiff.Hunks(oldSrc, newSrc, diffContext)
+	if len(hunks) == 0 {
+		fmt.Fprintln(w, Muted("  No changes."))
+		return
+	}
+	for _, h := range hunks {
+		PrintHunk(w, h)
+	}
+	printDiffStat(w, hunks)
+}
+
+// PrintHunk writes one hunk of a colored unified diff.
+func PrintHunk(w io.Writer, h textdiff.Hunk) {
+	fmt.Fprintln(w, Colorize(RoleAccent, h.Header()))
+	for _, l := range h.Lines {
+		line := string(l.Op) + l.Text
+		switch l.Op {
+		case textdiff.Insert:
+			fmt.Fprintln(w, Colorize(RoleSuccess, line))
+		case textdiff.Delete:
+			fmt.Fprintln(w, Colorize(RoleError, line))
+		default:
+			fmt.Fprintln(w, Muted(line))
+		}
+	}
+}
+
+func printDiffStat(w io.Writer, hunks []textdiff.Hunk) {
+	added, removed := 0, 0
+	for _, h := range hunks {
+		added += h.Added()
+		removed += h.Removed()
+	}
+	plural := "s"
+	if len(hunks) == 1 {
+		plural = ""
+	}
+	fmt.Fprintf(w, "  %s, %s in %d hunk%s\n",
+		Colorize(RoleSuccess, fmt.Sprintf("+%d", added)),
+		Colorize(RoleError, fmt.Sprintf("-%d", removed)),
+		len(hunks), plural)
+}
+
+// ReviewDiff shows the change from oldSrc to newSrc and asks whether to
+// apply it. "y" applies all of it, "n" none, and "p" goes through it hunk by
+// hunk. readLine reads an answer, returning false at end of input. It
+// returns the source with the accepted hunks applied, and whether any were.
+func ReviewDiff(w io.Writer, readLine func() (string, bool), question, oldSrc, newSrc string) (string, bool) {
+	hunks := textdiff.Hunks(oldSrc, newSrc, diffContext)
+	if len(hunks) == 0 {
+		fmt.Fprintln(w, Muted("  No changes."))
+		return oldSrc, false
+	}
+	for _, h := range hunks {
+		PrintHunk(w, h)
+	}
+	printDiffStat(w, hunks)
+
+	if len(hunks) == 1 {
+		fmt.Fprintf(w, "%s (y/n): ", question)
+	} else {
+		fmt.Fprintf(w, "%s (y/n, p to pick hunks): ", question)
+	}
+	answer, ok := readLine()
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return newSrc, ok
+	case "p", "pick":
+		if ok && len(hunks) > 1 {
+			return pickHunks(w, readLine, oldSrc, hunks)
+		}
+	}
+	return oldSrc, false
+}
+
+// pickHunks asks about each hunk in turn, as git add -p does: y applies it,
+// n skips it, a applies it and the rest, d skips it and the rest.
+func pickHunks(w io.Writer, readLine func() (string, bool), oldSrc string, hunks []textdiff.Hunk) (string, bool) {
+	accepted := make([]bool, len(hunks))
+	rest := ""
+	for i, h := range hunks {
+		if rest == "" {
+			fmt.Fprintln(w)
+			PrintHunk(w, h)
+			fmt.Fprintf(w, "Apply hunk %d/%d? (y/n/a/d): ", i+1, len(hunks))
+			answer, ok := readLine()
+			if !ok {
+				rest = "d"
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+				accepted[i] = true
+			case "a":
+				accepted[i], rest = true, "a"
+			case "d", "q":
+				rest = "d"
+			}
+			continue
+		}
+		accepted[i] = rest == "a"
+	}
+
+	applied := 0
+	for _, a := range accepted {
+		if a {
+			applied++
+		}
+	}
+	fmt.Fprintln(w, Muted(fmt.Sprintf("  %d of %d hunks accepted.", applied, len(hunks))))
+	if applied == 0 {
+		return oldSrc, false
+	}
+	return textdiff.Apply(oldSrc, hunks, accepted), true
+}
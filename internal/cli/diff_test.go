@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func answers(lines ...string) func() (string, bool) {
+	return func() (string, bool) {
+		if len(lines) == 0 {
+			return "", false
+		}
+		line := lines[0]
+		lines = lines[1:]
+		return line, true
+	}
+}
+
+func TestPrintDiff(t *testing.T) {
+	ColorEnabled = false
+	var buf bytes.Buffer
+	PrintDiff(&buf, "app Shop\n  has a name\n", "app Shop\n  has a title\n")
+	want := "@@ -1,3 +1,3 @@\n app Shop\n-  has a name\n+  has a title\n \n  +1, -1 in 1 hunk\n"
+	if buf.String() != want {
+		t.Errorf("PrintDiff =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestReviewDiff(t *testing.T) {
+	ColorEnabled = false
+	oldSrc := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	newSrc := "A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\n"
+
+	for _, tt := range []struct {
+		name    string
+		answers []string
+		want    string
+		ok      bool
+	}{
+		{"yes", []string{"y"}, newSrc, true},
+		{"no", []string{"n"}, oldSrc, false},
+		{"end of input", nil, oldSrc, false},
+		{"pick the second", []string{"p", "n", "y"}, "a\nb\nc\nd\ne\nf\ng\nh\ni\nJ\n", true},
+		{"pick all", []string{"p", "a"}, newSrc, true},
+		{"pick none", []string{"p", "d"}, oldSrc, false},
+	} {
+		var buf bytes.Buffer
+		got, ok := ReviewDiff(&buf, answers(tt.answers...), "Apply changes?", oldSrc, newSrc)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: ReviewDiff = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+		if !strings.Contains(buf.String(), "Apply changes? (y/n, p to pick hunks): ") {
+			t.Errorf("%s: prompt missing:\n%s", tt.name, buf.String())
+		}
+	}
+}
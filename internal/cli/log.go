@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Level controls how much the CLI prints.
+type Level int
+
+const (
+	// LevelQuiet prints errors only, uncolored, one per line on stderr.
+	LevelQuiet Level = iota
+	// LevelNormal prints progress and summaries.
+	LevelNormal
+	// LevelVerbose adds per-generator file lists, timing, and a trace of
+	// the compiler's stages.
+	LevelVerbose
+)
+
+// LogLevel is the current output level, set by --quiet and --verbose.
+var LogLevel = LevelNormal
+
+// Output and ErrOutput are where log output goes.
+var (
+	Output    io.Writer = os.Stdout
+	ErrOutput io.Writer = os.Stderr
+)
+
+// Printf prints at the normal level.
+func Printf(format string, args ...any) {
+	if LogLevel >= LevelNormal {
+		fmt.Fprintf(Output, format, args...)
+	}
+}
+
+// Println prints at the normal level.
+func Println(args ...any) {
+	if LogLevel >= LevelNormal {
+		fmt.Fprintln(Output, args...)
+	}
+}
+
+// Verbosef prints a line at the verbose level, muted.
+func Verbosef(format string, args ...any) {
+	if LogLevel < LevelVerbose {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	if ColorEnabled {
+		msg = themeColor(RoleMuted, "\033[90m") + msg + reset
+	}
+	fmt.Fprintln(Output, msg)
+}
+
+// Warnln prints a warning to stderr unless quiet.
+func Warnln(msg string) {
+	if LogLevel >= LevelNormal {
+		fmt.Fprintln(ErrOutput, Warn(msg))
+	}
+}
+
+// Errorln prints an error to stderr at every level. Quiet output is
+// "error: <msg>", for scripts to parse.
+func Errorln(msg string) {
+	if LogLevel == LevelQuiet {
+		fmt.Fprintln(ErrOutput, "error: "+msg)
+		return
+	}
+	fmt.Fprintln(ErrOutput, Error(msg))
+}
+
+// Verbose reports whether verbose output is on.
+func Verbose() bool {
+	return LogLevel >= LevelVerbose
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func captureLog(t *testing.T, level Level) (*bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	prevLevel, prevOut, prevErr, prevColor := LogLevel, Output, ErrOutput, ColorEnabled
+	LogLevel, Output, ErrOutput, ColorEnabled = level, &out, &errOut, false
+	t.Cleanup(func() { LogLevel, Output, ErrOutput, ColorEnabled = prevLevel, prevOut, prevErr, prevColor })
+	return &out, &errOut
+}
+
+func logAll() {
+	Println("built")
+	Verbosef("took %dms", 12)
+	Warnln("deprecated")
+	Errorln("broken")
+}
+
+func TestLogQuiet(t *testing.T) {
+	out, errOut := captureLog(t, LevelQuiet)
+	logAll()
+	if out.Len() != 0 {
+		t.Errorf("quiet should print nothing to stdout, got %q", out.String())
+	}
+	if errOut.String() != "error: broken\n" {
+		t.Errorf("quiet stderr = %q, want only the error", errOut.String())
+	}
+}
+
+func TestLogNormal(t *testing.T) {
+	out, errOut := captureLog(t, LevelNormal)
+	logAll()
+	if out.String() != "built\n" {
+		t.Errorf("stdout = %q, want no verbose lines", out.String())
+	}
+	if errOut.String() != "⚠ deprecated\n✗ broken\n" {
+		t.Errorf("stderr = %q", errOut.String())
+	}
+}
+
+func TestLogVerbose(t *testing.T) {
+	out, _ := captureLog(t, LevelVerbose)
+	logAll()
+	if out.String() != "built\ntook 12ms\n" {
+		t.Errorf("stdout = %q, want the verbose line", out.String())
+	}
+	if !Verbose() {
+		t.Error("Verbose() should be true")
+	}
+}
@@ -16,16 +16,17 @@ type ProgressBox struct {
 	title  string
 	stages []string
 	done   []bool
-	active int // index of currently running stage (-1 if none)
-	failed int // index of failed stage (-1 if none)
+	active int    // index of currently running stage (-1 if none)
+	detail string // the active stage's current step, if any
+	failed int    // index of failed stage (-1 if none)
 	mu     sync.Mutex
 	tty    bool
 	lines  int // number of lines drawn (for cursor rewind)
 
 	// Spinner animation
-	stop     chan struct{}
-	stopped  chan struct{}
-	spinIdx  int
+	stop    chan struct{}
+	stopped chan struct{}
+	spinIdx int
 }
 
 // NewProgressBox creates a progress display.
@@ -60,18 +61,26 @@ func (p *ProgressBox) Start() {
 }
 
 // Update marks the given stage as the currently active stage.
-// All stages before it are marked as done.
+// All stages before it are marked as done. A long stage reports its steps
+// as "<stage>: <step>", shown beside the stage.
 func (p *ProgressBox) Update(stageName string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	idx := -1
+	detail := ""
 	for i, s := range p.stages {
 		if s == stageName {
 			idx = i
 			break
 		}
+		if strings.HasPrefix(stageName, s+": ") {
+			idx = i
+			detail = strings.TrimPrefix(stageName, s+": ")
+			break
+		}
 	}
+	p.detail = detail
 
 	if idx < 0 {
 		// Unknown stage — append dynamically.
@@ -115,6 +124,7 @@ func (p *ProgressBox) FailStage(name string) {
 		}
 	}
 	p.active = -1
+	p.detail = ""
 	p.mu.Unlock()
 
 	if p.tty {
@@ -136,6 +146,7 @@ func (p *ProgressBox) Finish() {
 		p.done[i] = true
 	}
 	p.active = -1
+	p.detail = ""
 	p.mu.Unlock()
 
 	if p.tty {
@@ -277,6 +288,9 @@ func (p *ProgressBox) draw() {
 		// Truncate stage name if too long.
 		maxStage := width - 7
 		displayStage := stage
+		if i == p.active && p.detail != "" {
+			displayStage += " — " + p.detail
+		}
 		if len([]rune(displayStage)) > maxStage {
 			displayStage = string([]rune(displayStage)[:maxStage-3]) + "..."
 		}
@@ -51,3 +51,19 @@ func TestProgressBoxFailedInitValue(t *testing.T) {
 		t.Fatalf("expected failed=-1, got %d", box.failed)
 	}
 }
+
+func TestProgressBoxStepDetail(t *testing.T) {
+	var buf bytes.Buffer
+	box := NewProgressBox(&buf, "Test", []string{"Generating", "Running quality checks"})
+	box.Update("Running quality checks: scanning dependencies")
+	if box.active != 1 {
+		t.Fatalf("expected active=1, got %d", box.active)
+	}
+	if box.detail != "scanning dependencies" {
+		t.Fatalf("expected detail %q, got %q", "scanning dependencies", box.detail)
+	}
+	box.Finish()
+	if box.detail != "" {
+		t.Fatalf("expected detail cleared after Finish, got %q", box.detail)
+	}
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Section streams a long-running step's output (docker, terraform) beneath
+// a header, each line behind a "│" border. On a color terminal, a step that
+// succeeds collapses to a single line with its duration, and a failing step
+// stays expanded so its output can be read. With --verbose nothing
+// collapses; with --quiet the output is kept back and shown only if the
+// step fails.
+type Section struct {
+	out         io.Writer
+	title       string
+	start       time.Time
+	collapse    bool
+	width       int // terminal width, to count wrapped lines when collapsing
+	lines       int
+	col         int
+	atLineStart bool
+	held        bytes.Buffer // output held back under --quiet
+}
+
+// NewSection prints the section header and returns a writer for the step's
+// output.
+func NewSection(out io.Writer, title string) *Section {
+	s := &Section{
+		out:         out,
+		title:       title,
+		start:       time.Now(),
+		collapse:    ColorEnabled && IsTTY(out) && LogLevel == LevelNormal,
+		atLineStart: true,
+	}
+	if f, ok := out.(*os.File); ok && s.collapse {
+		if w, _, err := term.GetSize(int(f.Fd())); err == nil {
+			s.width = w
+		}
+	}
+	if LogLevel > LevelQuiet {
+		fmt.Fprintln(out, Info("▸ "+title))
+	}
+	return s
+}
+
+// Write writes step output into the section.
+func (s *Section) Write(p []byte) (int, error) {
+	if LogLevel == LevelQuiet {
+		return s.held.Write(p)
+	}
+	border := "  │ "
+	if ColorEnabled {
+		border = "  " + themeColor(RoleMuted, "\033[90m") + "│" + reset + " "
+	}
+	var buf bytes.Buffer
+	for _, b := range p {
+		if s.atLineStart {
+			buf.WriteString(border)
+			s.atLineStart = false
+			s.col = 4
+		}
+		buf.WriteByte(b)
+		switch {
+		case b == '\n':
+			s.lines++
+			s.atLineStart = true
+		case b&0xC0 != 0x80: // count a UTF-8 character once
+			s.col++
+			if s.width > 0 && s.col > s.width {
+				s.lines++
+				s.col = 1
+			}
+		}
+	}
+	if _, err := s.out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Done closes the section with the step's result.
+func (s *Section) Done(err error) {
+	if !s.atLineStart {
+		fmt.Fprintln(s.out)
+		s.lines++
+		s.atLineStart = true
+	}
+	elapsed := time.Since(s.start).Round(100 * time.Millisecond)
+
+	if err != nil {
+		if LogLevel == LevelQuiet {
+			ErrOutput.Write(s.held.Bytes())
+			return
+		}
+		fmt.Fprintln(s.out, Error(fmt.Sprintf("%s failed after %s", s.title, elapsed)))
+		return
+	}
+	if LogLevel == LevelQuiet {
+		return
+	}
+	if s.collapse {
+		// Move up over the output and the header, and clear them.
+		fmt.Fprintf(s.out, "\033[%dA\033[J", s.lines+1)
+	}
+	fmt.Fprintln(s.out, Success(fmt.Sprintf("%s (%s)", s.title, elapsed)))
+}
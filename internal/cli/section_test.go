@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func withLevel(t *testing.T, level Level) *bytes.Buffer {
+	t.Helper()
+	oldLevel, oldColor, oldErr := LogLevel, ColorEnabled, ErrOutput
+	var errBuf bytes.Buffer
+	LogLevel, ColorEnabled, ErrOutput = level, false, &errBuf
+	t.Cleanup(func() { LogLevel, ColorEnabled, ErrOutput = oldLevel, oldColor, oldErr })
+	return &errBuf
+}
+
+func TestSectionStreamsWithBorder(t *testing.T) {
+	withLevel(t, LevelNormal)
+	var buf bytes.Buffer
+	s := NewSection(&buf, "terraform init")
+	s.Write([]byte("Initializing...\nDone"))
+	s.Done(nil)
+
+	out := buf.String()
+	for _, want := range []string{"terraform init", "  │ Initializing...\n", "  │ Done\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\033[") {
+		t.Errorf("non-TTY output should not collapse:\n%q", out)
+	}
+}
+
+func TestSectionFailureKeepsOutput(t *testing.T) {
+	withLevel(t, LevelNormal)
+	var buf bytes.Buffer
+	s := NewSection(&buf, "docker compose build")
+	s.Write([]byte("step 3 failed\n"))
+	s.Done(errors.New("exit status 1"))
+
+	out := buf.String()
+	if !strings.Contains(out, "step 3 failed") || !strings.Contains(out, "docker compose build failed") {
+		t.Errorf("expected output and failure line, got:\n%s", out)
+	}
+}
+
+func TestSectionQuietHoldsOutput(t *testing.T) {
+	errBuf := withLevel(t, LevelQuiet)
+	var buf bytes.Buffer
+	s := NewSection(&buf, "terraform apply")
+	s.Write([]byte("Apply complete!\n"))
+	s.Done(nil)
+	if buf.Len() != 0 || errBuf.Len() != 0 {
+		t.Fatalf("quiet success should print nothing, got %q / %q", buf.String(), errBuf.String())
+	}
+
+	s = NewSection(&buf, "terraform apply")
+	s.Write([]byte("Error: quota exceeded\n"))
+	s.Done(errors.New("exit status 1"))
+	if buf.Len() != 0 {
+		t.Errorf("quiet failure should not write to out, got %q", buf.String())
+	}
+	if !strings.Contains(errBuf.String(), "Error: quota exceeded") {
+		t.Errorf("quiet failure should dump held output, got %q", errBuf.String())
+	}
+}
@@ -0,0 +1,114 @@
+package cmdutil
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// BackupDatabase runs the generated backup/backup.sh against the database,
+// writing the backup to .human/output/backups (and S3, when the app has an
+// S3 integration).
+func BackupDatabase(outputDir string) error {
+	return runBackupScript(outputDir, "backup.sh", "pg_dump", "")
+}
+
+// RestoreDatabase runs the generated backup/restore.sh, replacing the
+// database's contents with backup: "latest", a backup name, or a file.
+func RestoreDatabase(outputDir, backup string) error {
+	return runBackupScript(outputDir, "restore.sh", "pg_restore", backup)
+}
+
+// backupEnv is what backup scripts keep in a scrubbed environment: the
+// database URL and the cloud storage credentials.
+var backupEnv = append([]string{"DATABASE_URL", "S3_*", "BACKUP_*"}, CloudCredentialEnv...)
+
+// runBackupScript runs a backup script with the PostgreSQL client tools on
+// PATH, else in the Docker Compose backup container. Locally, DATABASE_URL
+// and the storage credentials come from the environment or, failing that,
+// the build's .env.
+func runBackupScript(outputDir, script, tool, arg string) error {
+	rel := filepath.Join("backup", script)
+	if _, err := os.Stat(filepath.Join(outputDir, rel)); os.IsNotExist(err) {
+		return fmt.Errorf("the build has no backup scripts. Add a rule like 'backup daily at 3am' to the database block and rebuild")
+	}
+
+	if _, err := exec.LookPath(tool); err == nil {
+		args := []string{rel}
+		if arg != "" {
+			args = append(args, arg)
+		}
+		cmd := exec.Command("sh", args...)
+		cmd.Dir = outputDir
+		cmd.Env = withDotEnv(CommandEnv(backupEnv...), filepath.Join(outputDir, ".env"))
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "docker-compose.yml")); err != nil {
+		return fmt.Errorf("%s not found in PATH. Install the PostgreSQL client tools", tool)
+	}
+	composeCmd, err := DetectComposeCommand()
+	if err != nil {
+		return fmt.Errorf("%s not found in PATH. Install the PostgreSQL client tools, or Docker to use the backup container", tool)
+	}
+
+	// A local file is mounted into the container.
+	args := append(composeCmd[1:], "run", "--rm", "--entrypoint", "/backup/"+script)
+	if arg != "" {
+		if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+			abs, err := filepath.Abs(arg)
+			if err != nil {
+				return err
+			}
+			args = append(args, "-v", abs+":/restore/"+filepath.Base(abs)+":ro")
+			arg = "/restore/" + filepath.Base(abs)
+		}
+	}
+	args = append(args, "backup")
+	if arg != "" {
+		args = append(args, arg)
+	}
+	return RunCommand(outputDir, composeCmd[0], args...)
+}
+
+// withDotEnv adds the KEY=value lines of a .env file whose keys aren't
+// already set in env.
+func withDotEnv(env []string, path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return env
+	}
+	defer f.Close()
+
+	set := make(map[string]bool, len(env))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		set[name] = true
+	}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		if set[key] {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		env = append(env, key+"="+value)
+	}
+	return env
+}
@@ -0,0 +1,39 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithDotEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# local defaults\nDATABASE_URL=postgresql://localhost/shop\nS3_BUCKET=\"files\"\nexport AWS_REGION='us-west-2'\n\nJWT_SECRET=from-file\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	env := withDotEnv([]string{"PATH=/usr/bin", "JWT_SECRET=from-env"}, path)
+	want := []string{
+		"PATH=/usr/bin",
+		"JWT_SECRET=from-env",
+		"DATABASE_URL=postgresql://localhost/shop",
+		"S3_BUCKET=files",
+		"AWS_REGION=us-west-2",
+	}
+	if strings.Join(env, "\n") != strings.Join(want, "\n") {
+		t.Errorf("withDotEnv:\ngot  %q\nwant %q", env, want)
+	}
+
+	if got := withDotEnv([]string{"PATH=/usr/bin"}, filepath.Join(t.TempDir(), "missing")); len(got) != 1 {
+		t.Errorf("a missing .env should leave the environment alone, got %q", got)
+	}
+}
+
+func TestBackupDatabaseWithoutScripts(t *testing.T) {
+	err := BackupDatabase(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "no backup scripts") {
+		t.Errorf("expected a missing-scripts error, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/config"
+)
+
+// LookupProjectCommand returns the project command with the given name from
+// .human/config.json in dir, or nil if there is none.
+func LookupProjectCommand(dir, name string) (*config.CommandConfig, error) {
+	cfg, err := config.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Commands[name], nil
+}
+
+// RunProjectCommand runs a project command's steps in dir, echoing each one
+// before it runs. args are appended to the last step.
+func RunProjectCommand(dir, name string, cmd *config.CommandConfig, args []string, out io.Writer) error {
+	if len(cmd.Steps) == 0 {
+		return fmt.Errorf("command %q has no steps", name)
+	}
+	if err := RequireTrust(dir, "run its "+name+" command"); err != nil {
+		return err
+	}
+	for i, step := range cmd.Steps {
+		if i == len(cmd.Steps)-1 {
+			for _, a := range args {
+				step += " " + shellQuote(a)
+			}
+		}
+		fmt.Fprintln(out, cli.Info("$ "+step))
+		if err := shellCommand(dir, step).Run(); err != nil {
+			return fmt.Errorf("%s: step %d failed (%s): %w", name, i+1, step, err)
+		}
+	}
+	return nil
+}
+
+// PrintProjectCommands lists the project's commands under the help text.
+func PrintProjectCommands(out io.Writer, commands map[string]*config.CommandConfig) {
+	if len(commands) == 0 {
+		return
+	}
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(out, "\nProject Commands (.human/config.json):")
+	for _, name := range names {
+		desc := commands[name].Description
+		if desc == "" {
+			desc = strings.Join(commands[name].Steps, " && ")
+		}
+		fmt.Fprintf(out, "  %-25s %s\n", name, desc)
+	}
+}
+
+// shellCommand returns a command running step through the shell in dir,
+// connected to the terminal. Steps that run human inherit --no-color and
+// --offline.
+func shellCommand(dir, step string) *exec.Cmd {
+	c := exec.Command(shell(), shellFlag(), expandHuman(step))
+	c.Dir = dir
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = CommandEnv()
+	if !cli.ColorEnabled {
+		c.Env = append(c.Env, "NO_COLOR=1")
+	}
+	if config.OfflineMode {
+		c.Env = append(c.Env, "HUMAN_OFFLINE=1")
+	}
+	return c
+}
+
+// expandHuman points a step starting with "human" at this binary, so
+// project commands work even when human is not on the PATH.
+func expandHuman(step string) string {
+	if step != "human" && !strings.HasPrefix(step, "human ") {
+		return step
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return step
+	}
+	return shellQuote(exe) + strings.TrimPrefix(step, "human")
+}
+
+func shell() string {
+	if runtime.GOOS == "windows" {
+		return "cmd"
+	}
+	return "sh"
+}
+
+func shellFlag() string {
+	if runtime.GOOS == "windows" {
+		return "/C"
+	}
+	return "-c"
+}
+
+// shellQuote quotes an argument for the shell when it needs it.
+func shellQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`&|;<>()*?[]{}!#~%") {
+		return s
+	}
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,76 @@
+package cmdutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/config"
+)
+
+func TestRunProjectCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("steps use POSIX shell syntax")
+	}
+	t.Setenv("HUMAN_TRUST_WORKSPACE", "1")
+	dir := t.TempDir()
+	cmd := &config.CommandConfig{Steps: []string{"echo first > log.txt", "echo >> log.txt"}}
+
+	var out bytes.Buffer
+	if err := RunProjectCommand(dir, "log", cmd, []string{"it's", "--env staging"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "log.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "first\nit's --env staging\n" {
+		t.Errorf("log.txt = %q, want the args appended to the last step", got)
+	}
+	if !strings.Contains(out.String(), "$ echo first > log.txt") {
+		t.Errorf("steps should be echoed, got:\n%s", out.String())
+	}
+}
+
+func TestRunProjectCommandStopsOnFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("steps use POSIX shell syntax")
+	}
+	t.Setenv("HUMAN_TRUST_WORKSPACE", "1")
+	dir := t.TempDir()
+	cmd := &config.CommandConfig{Steps: []string{"exit 3", "touch ran.txt"}}
+
+	err := RunProjectCommand(dir, "broken", cmd, nil, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "step 1 failed") {
+		t.Fatalf("expected step 1 to fail, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ran.txt")); err == nil {
+		t.Error("later steps should not run after a failure")
+	}
+}
+
+func TestExpandHuman(t *testing.T) {
+	exe, _ := os.Executable()
+	if got := expandHuman("human build app.human"); got != shellQuote(exe)+" build app.human" {
+		t.Errorf("expandHuman = %q, want this binary", got)
+	}
+	if got := expandHuman("humanize app"); got != "humanize app" {
+		t.Errorf("expandHuman should only match the human command, got %q", got)
+	}
+}
+
+func TestPrintProjectCommands(t *testing.T) {
+	var out bytes.Buffer
+	PrintProjectCommands(&out, map[string]*config.CommandConfig{
+		"deploy:staging": {Description: "Build and deploy to staging", Steps: []string{"human build app.human"}},
+		"fmt":            {Steps: []string{"human build app.human", "npx prettier --write .human/output"}},
+	})
+	for _, want := range []string{"deploy:staging", "Build and deploy to staging", "human build app.human && npx prettier --write .human/output"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("help missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cmdutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/irdiff"
+	"github.com/barun-bash/human/internal/parser"
+)
+
+// Comparison is the semantic difference between two .human specs.
+type Comparison struct {
+	Old, New  string
+	Changes   []irdiff.Change
+	Checklist []string // what moving from Old to New takes besides rebuilding
+}
+
+// Compare builds the IR of two .human files or project directories and
+// compares them. Unlike a build, a file is read on its own, with the files
+// it imports, so two versions of a spec can sit side by side.
+func Compare(oldPath, newPath string) (*Comparison, error) {
+	old, err := buildIR(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := buildIR(newPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Comparison{
+		Old:       oldPath,
+		New:       newPath,
+		Changes:   irdiff.Diff(old, cur),
+		Checklist: irdiff.Checklist(old, cur),
+	}, nil
+}
+
+// buildIR parses a .human file, or every .human file in a directory, and
+// builds its IR.
+func buildIR(path string) (*ir.Application, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access %s: %w", path, err)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = parser.DiscoverFiles(path); err != nil {
+			return nil, err
+		}
+	}
+	programs, _, err := parser.ParseProject(filepath.Dir(files[0]), files)
+	if err != nil {
+		return nil, err
+	}
+	prog := programs[0]
+	if len(programs) > 1 {
+		if prog, err = parser.MergePrograms(programs); err != nil {
+			return nil, err
+		}
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		return nil, fmt.Errorf("%s: IR build error: %w", path, err)
+	}
+	return app, nil
+}
+
+// PrintComparison prints what changed between two specs, and the migration
+// checklist when asked for.
+func PrintComparison(c *Comparison, checklist bool) {
+	if len(c.Changes) == 0 {
+		cli.Println(cli.Success(fmt.Sprintf("%s and %s describe the same application.", c.Old, c.New)))
+		return
+	}
+	fmt.Printf("Changes from %s to %s:\n", c.Old, c.New)
+	PrintChanges(c.Changes)
+
+	if !checklist {
+		return
+	}
+	fmt.Println("\n  Migration checklist")
+	if len(c.Checklist) == 0 {
+		fmt.Println("    Nothing to do besides rebuilding.")
+		return
+	}
+	for _, item := range c.Checklist {
+		fmt.Printf("    [ ] %s\n", item)
+	}
+}
@@ -0,0 +1,32 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	// Two versions side by side are read separately, not merged
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.human")
+	b := filepath.Join(dir, "b.human")
+	os.WriteFile(a, []byte(diffSource), 0644)
+	os.WriteFile(b, []byte(strings.Replace(diffSource, "  has a total which is number", "  has a total which is number\n  has a code which is text", 1)), 0644)
+
+	c, err := Compare(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Changes) != 1 || c.Changes[0].Name != "Order" || c.Changes[0].Details[0] != "+ field code (text, required)" {
+		t.Errorf("changes = %+v", c.Changes)
+	}
+	if len(c.Checklist) != 1 || !strings.HasPrefix(c.Checklist[0], "Backfill Order.code") {
+		t.Errorf("checklist = %v", c.Checklist)
+	}
+
+	if _, err := Compare(a, filepath.Join(dir, "missing.human")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
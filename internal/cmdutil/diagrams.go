@@ -0,0 +1,36 @@
+package cmdutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/codegen/diagrams"
+)
+
+// BuildDiagrams regenerates only the diagrams of the app in file, into
+// .human/output/docs, without running the other generators. The directory
+// is the diagrams generator's alone, so it's cleared first, dropping the
+// diagrams of removed workflows.
+func BuildDiagrams(file string) error {
+	result, err := ParseAndAnalyze(file)
+	if err != nil {
+		return err
+	}
+	if PrintDiagnostics(result.Errs) {
+		return fmt.Errorf("%d error(s) found", len(result.Errs.Errors()))
+	}
+
+	g := diagrams.Generator{}
+	dir := filepath.Join(".human", "output", g.OutputDir())
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("clearing %s: %w", dir, err)
+	}
+	if err := g.Generate(result.App, dir); err != nil {
+		return fmt.Errorf("generating diagrams: %w", err)
+	}
+	cli.Println(cli.Success(fmt.Sprintf("Generated %d diagram files in %s", codegen.CountFiles(dir), dir)))
+	return nil
+}
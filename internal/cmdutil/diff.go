@@ -0,0 +1,198 @@
+package cmdutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/barun-bash/human/internal/build"
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/irdiff"
+)
+
+// BuildPreview is what rebuilding a .human file would change, compared
+// with its last build.
+type BuildPreview struct {
+	Intent  string // the last build's IR, .human/intent/<name>.yaml
+	Changes []irdiff.Change
+	Files   *FilePreview // nil when there's no finished build to compare with
+}
+
+// FilePreview lists the generated files a build would add, change, or
+// stop generating, slash-separated and relative to the output dir.
+type FilePreview struct {
+	Added   []string
+	Changed []string
+	Removed []string
+}
+
+// Count returns the number of files that would change.
+func (f *FilePreview) Count() int {
+	return len(f.Added) + len(f.Changed) + len(f.Removed)
+}
+
+// PreviewBuild compares app, just built from file, with the IR file's last
+// build wrote, and generates its code into a temporary directory to find
+// the generated files a build would change.
+func PreviewBuild(file string, app *ir.Application) (*BuildPreview, error) {
+	intent := IntentPath(file)
+	data, err := os.ReadFile(intent)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s has not been built yet (no %s). Run 'human build %s' first", file, intent, file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	old, err := ir.FromYAML(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", intent, err)
+	}
+
+	// A build asks for ports the source doesn't set; use the ones it got.
+	if app.Config == nil {
+		app.Config = &ir.BuildConfig{}
+	}
+	if app.Config.Ports == (ir.PortConfig{}) && old.Config != nil {
+		app.Config.Ports = old.Config.Ports
+	}
+
+	p := &BuildPreview{Intent: intent, Changes: irdiff.Diff(old, app)}
+	outputDir := filepath.Join(".human", "output")
+	if prev, err := readManifest(outputDir); err == nil {
+		if p.Files, err = previewOutput(app, outputDir, prev); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// previewOutput generates app's code into a temporary directory and
+// compares it with the files in the last build's manifest. The build is
+// dated like the last one, so only what the IR changes differs.
+func previewOutput(app *ir.Application, outputDir string, prev *Manifest) (*FilePreview, error) {
+	at := time.Now()
+	if data, err := os.ReadFile(filepath.Join(outputDir, "build-info.json")); err == nil {
+		var last ir.BuildInfo
+		if json.Unmarshal(data, &last) == nil {
+			if t, err := time.Parse(time.RFC3339, last.GeneratedAt); err == nil {
+				at = t
+			}
+		}
+	}
+	info, err := build.NewBuildInfo(app, at)
+	if err != nil {
+		return nil, err
+	}
+	app.Build = info
+
+	tmp, err := os.MkdirTemp("", "human-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	if _, _, _, err := build.RunGenerators(app, tmp); err != nil {
+		return nil, fmt.Errorf("generating code to compare: %w", err)
+	}
+
+	f := &FilePreview{}
+	generated := map[string]bool{}
+	err = filepath.WalkDir(tmp, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(tmp, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		generated[rel] = true
+		// Files naming the output dir, like build-report.md, name the real one
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		hash := sha256.Sum256(bytes.ReplaceAll(content, []byte(tmp), []byte(outputDir)))
+		sum := hex.EncodeToString(hash[:])
+		switch old, ok := prev.Files[rel]; {
+		case !ok:
+			f.Added = append(f.Added, rel)
+		case old != sum:
+			f.Changed = append(f.Changed, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for rel := range prev.Files {
+		if !generated[rel] {
+			f.Removed = append(f.Removed, rel)
+		}
+	}
+	sort.Strings(f.Added)
+	sort.Strings(f.Changed)
+	sort.Strings(f.Removed)
+	return f, nil
+}
+
+// PrintChanges prints IR changes grouped by section, with what changed in
+// each changed item indented below it.
+func PrintChanges(changes []irdiff.Change) {
+	for _, section := range irdiff.Sections {
+		printed := false
+		for _, c := range changes {
+			if c.Section != section {
+				continue
+			}
+			if !printed {
+				fmt.Printf("\n  %s\n", section)
+				printed = true
+			}
+			fmt.Printf("    %s %s\n", c.Kind.Symbol(), c.Name)
+			for _, d := range c.Details {
+				fmt.Printf("        %s\n", d)
+			}
+		}
+	}
+}
+
+// PrintBuildPreview prints what rebuilding would change in the IR and in
+// the generated files.
+func PrintBuildPreview(p *BuildPreview) {
+	if len(p.Changes) == 0 {
+		cli.Println(cli.Success(fmt.Sprintf("No changes to the IR since the last build (%s).", p.Intent)))
+	} else {
+		fmt.Printf("Changes since the last build (%s):\n", p.Intent)
+		PrintChanges(p.Changes)
+	}
+
+	fmt.Println()
+	switch {
+	case p.Files == nil:
+		cli.Println(cli.Info("No finished build in .human/output to compare generated files with."))
+	case p.Files.Count() == 0:
+		cli.Println(cli.Success("No generated files would change."))
+	default:
+		fmt.Println("  Generated files")
+		for _, rel := range p.Files.Added {
+			fmt.Printf("    + %s\n", rel)
+		}
+		for _, rel := range p.Files.Changed {
+			fmt.Printf("    ~ %s\n", rel)
+		}
+		for _, rel := range p.Files.Removed {
+			fmt.Printf("    - %s\n", rel)
+		}
+		n := p.Files.Count()
+		fmt.Printf("\n%d generated file%s would change: %d added, %d changed, %d removed.\n",
+			n, Plural(n), len(p.Files.Added), len(p.Files.Changed), len(p.Files.Removed))
+	}
+}
@@ -0,0 +1,117 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barun-bash/human/internal/build"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/irdiff"
+	"github.com/barun-bash/human/internal/parser"
+)
+
+const diffSource = `app Shop is a web application
+
+data Order:
+  has a total which is number
+
+api GetOrders:
+  fetch all orders
+  respond with orders
+
+build with:
+  frontend using React with TypeScript
+  backend using Node with Express
+  database using PostgreSQL`
+
+func buildApp(t *testing.T, source string) *ir.Application {
+	t.Helper()
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+	return app
+}
+
+// writeIntent writes app's IR where a build of file would, with the ports
+// a build asks for.
+func writeIntent(t *testing.T, file string, app *ir.Application) {
+	t.Helper()
+	app.Config.Ports = ir.PortConfig{Frontend: 3000, Backend: 4000, Database: 5432}
+	yaml, err := ir.ToYAML(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Dir(IntentPath(file)), 0755)
+	if err := os.WriteFile(IntentPath(file), []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPreviewBuildUnbuilt(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_, err := PreviewBuild("app.human", buildApp(t, diffSource))
+	if err == nil || !strings.Contains(err.Error(), "human build app.human") {
+		t.Errorf("err = %v, want a hint to build first", err)
+	}
+}
+
+func TestPreviewBuild(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("HUMAN_OFFLINE", "1")
+
+	// The last build, with its output and manifest
+	last := buildApp(t, diffSource)
+	writeIntent(t, "app.human", last)
+	preview, err := PreviewBuild("app.human", buildApp(t, diffSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Changes) != 0 || preview.Files != nil {
+		t.Fatalf("without output: changes %+v, files %+v", preview.Changes, preview.Files)
+	}
+
+	outputDir := filepath.Join(".human", "output")
+	started := time.Now().Truncate(time.Second)
+	last.Build, _ = build.NewBuildInfo(last, time.Now())
+	if _, _, _, err := build.RunGenerators(last, outputDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writeManifest(outputDir, generatedSince(outputDir, started), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rebuilding the same source changes nothing; the ports are the ones
+	// the last build got.
+	preview, err = PreviewBuild("app.human", buildApp(t, diffSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Changes) != 0 {
+		t.Errorf("unchanged source: changes %+v", preview.Changes)
+	}
+	if preview.Files == nil || preview.Files.Count() != 0 {
+		t.Errorf("unchanged source: files %+v", preview.Files)
+	}
+
+	// A new model shows in the IR and in the files generated for it
+	changed := strings.Replace(diffSource, "api GetOrders:", "data Coupon:\n  has a code which is text\n\napi GetOrders:", 1)
+	preview, err = PreviewBuild("app.human", buildApp(t, changed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Changes) != 1 || preview.Changes[0].Kind != irdiff.Added || preview.Changes[0].Name != "Coupon" {
+		t.Errorf("changes = %+v, want Coupon added", preview.Changes)
+	}
+	if preview.Files == nil || !slices.Contains(preview.Files.Changed, "node/prisma/schema.prisma") {
+		t.Errorf("schema.prisma should change, got %+v", preview.Files)
+	}
+}
@@ -10,82 +10,147 @@ import (
 	"github.com/barun-bash/human/internal/cli"
 	"github.com/barun-bash/human/internal/ir"
 	"github.com/barun-bash/human/internal/parser"
+	"github.com/barun-bash/human/internal/quality"
 )
 
 // PrintIRSummary displays a summary of the IR application to stdout.
 func PrintIRSummary(app *ir.Application) {
-	fmt.Println(cli.Info(fmt.Sprintf("  app:          %s (%s)", app.Name, app.Platform)))
+	cli.Println(cli.Info(fmt.Sprintf("  app:          %s (%s)", app.Name, app.Platform)))
 	if app.Config != nil {
-		fmt.Println(cli.Info(fmt.Sprintf("  config:       %s / %s / %s", app.Config.Frontend, app.Config.Backend, app.Config.Database)))
+		cli.Println(cli.Info(fmt.Sprintf("  config:       %s / %s / %s", app.Config.Frontend, app.Config.Backend, app.Config.Database)))
 	}
 	if len(app.Data) > 0 {
-		fmt.Printf("  data models:  %d\n", len(app.Data))
+		cli.Printf("  data models:  %d\n", len(app.Data))
 	}
 	if len(app.Pages) > 0 {
-		fmt.Printf("  pages:        %d\n", len(app.Pages))
+		cli.Printf("  pages:        %d\n", len(app.Pages))
 	}
 	if len(app.Components) > 0 {
-		fmt.Printf("  components:   %d\n", len(app.Components))
+		cli.Printf("  components:   %d\n", len(app.Components))
 	}
 	if len(app.APIs) > 0 {
-		fmt.Printf("  APIs:         %d\n", len(app.APIs))
+		cli.Printf("  APIs:         %d\n", len(app.APIs))
 	}
 	if len(app.Policies) > 0 {
-		fmt.Printf("  policies:     %d\n", len(app.Policies))
+		cli.Printf("  policies:     %d\n", len(app.Policies))
 	}
 	if len(app.Workflows) > 0 {
-		fmt.Printf("  workflows:    %d\n", len(app.Workflows))
+		cli.Printf("  workflows:    %d\n", len(app.Workflows))
 	}
 	if len(app.Pipelines) > 0 {
-		fmt.Printf("  pipelines:    %d\n", len(app.Pipelines))
+		cli.Printf("  pipelines:    %d\n", len(app.Pipelines))
 	}
 	if app.Auth != nil && len(app.Auth.Methods) > 0 {
-		fmt.Printf("  auth methods: %d\n", len(app.Auth.Methods))
+		cli.Printf("  auth methods: %d\n", len(app.Auth.Methods))
 	}
 	if app.Database != nil {
-		fmt.Printf("  database:     %s\n", app.Database.Engine)
+		cli.Printf("  database:     %s\n", app.Database.Engine)
 	}
 	if len(app.Integrations) > 0 {
-		fmt.Printf("  integrations: %d\n", len(app.Integrations))
+		cli.Printf("  integrations: %d\n", len(app.Integrations))
 	}
 	if len(app.Environments) > 0 {
-		fmt.Printf("  environments: %d\n", len(app.Environments))
+		cli.Printf("  environments: %d\n", len(app.Environments))
 	}
 	if app.Architecture != nil {
-		fmt.Printf("  architecture: %s\n", app.Architecture.Style)
+		cli.Printf("  architecture: %s\n", app.Architecture.Style)
 	}
 	if len(app.Monitoring) > 0 {
-		fmt.Printf("  monitoring:   %d rule(s)\n", len(app.Monitoring))
+		cli.Printf("  monitoring:   %d rule(s)\n", len(app.Monitoring))
 	}
 }
 
-// PrintBuildSummary displays a table of generator results.
-func PrintBuildSummary(results []build.Result, outputDir string, timing *build.BuildTiming) {
+// PrintBuildSummary displays a table of generator results: files, bytes
+// written, changes since the previous build (when diff has one to compare
+// with), and time taken, or "cached" for generators the build skipped.
+func PrintBuildSummary(results []build.Result, outputDir string, timing *build.BuildTiming, diff *OutputDiff) {
 	total := 0
 	for _, r := range results {
 		total += r.Files
 	}
+	if diff == nil {
+		diff = &OutputDiff{}
+	}
+	changes := func(c *OutputChange) string {
+		if !diff.Previous {
+			return ""
+		}
+		return formatChange(c)
+	}
 
-	fmt.Println()
-	fmt.Println("  " + cli.Info("Build Summary"))
-	fmt.Println("  " + strings.Repeat("─", 50))
-	fmt.Printf("  %-14s %-8s %s\n", "Generator", "Files", "Output")
-	fmt.Println("  " + strings.Repeat("─", 50))
+	cli.Println()
+	cli.Println("  " + cli.Info("Build Summary"))
+	cli.Println("  " + strings.Repeat("─", 72))
+	cli.Printf("  %-14s %5s  %-9s  %-14s %6s  %s\n", "Generator", "Files", "Size", "Changes", "Time", "Output")
+	cli.Println("  " + strings.Repeat("─", 72))
+	var skipped []string
 	for _, r := range results {
 		relDir := r.Dir
 		if rel, err := filepath.Rel(".", r.Dir); err == nil {
 			relDir = rel
 		}
-		fmt.Printf("  %-14s %-8d %s/\n", r.Name, r.Files, relDir)
+		c := diff.Generators[r.Name]
+		if c == nil {
+			c = &OutputChange{}
+		}
+		took := formatDuration(r.Duration)
+		if r.Skipped {
+			took = "cached"
+			skipped = append(skipped, r.Name)
+		}
+		cli.Printf("  %-14s %5d  %-9s  %-14s %6s  %s/\n", r.Name, r.Files, formatBytes(c.Bytes), changes(c), took, relDir)
+	}
+	cli.Println("  " + strings.Repeat("─", 72))
+	totalTime := ""
+	if timing != nil {
+		totalTime = formatDuration(timing.Total)
+	}
+	cli.Printf("  %-14s %5d  %-9s  %-14s %6s\n", "Total", total, formatBytes(diff.Total.Bytes), changes(&diff.Total), totalTime)
+	cli.Println()
+	if diff.Previous {
+		if t := diff.Total; t.Added+t.Changed+t.Removed == 0 {
+			cli.Println("  No changes since the last build.")
+		} else {
+			cli.Printf("  Since the last build: %d added, %d changed, %d removed.\n", t.Added, t.Changed, t.Removed)
+		}
+	}
+	if len(skipped) > 0 {
+		cli.Printf("  Skipped %d generator%s with unchanged inputs: %s.\n", len(skipped), Plural(len(skipped)), strings.Join(skipped, ", "))
 	}
-	fmt.Println("  " + strings.Repeat("─", 50))
-	fmt.Printf("  %-14s %-8d\n", "Total", total)
-	fmt.Println()
 	if timing != nil {
-		fmt.Println(cli.Success(fmt.Sprintf("Build complete — %d files in %s/ (%s)", total, outputDir, formatDuration(timing.Total))))
+		cli.Println(cli.Success(fmt.Sprintf("Build complete — %d files in %s/ (%s)", total, outputDir, formatDuration(timing.Total))))
 	} else {
-		fmt.Println(cli.Success(fmt.Sprintf("Build complete — %d files in %s/", total, outputDir)))
+		cli.Println(cli.Success(fmt.Sprintf("Build complete — %d files in %s/", total, outputDir)))
+	}
+}
+
+// formatChange formats files added, changed, and removed as "+2 ~5 -1".
+func formatChange(c *OutputChange) string {
+	var parts []string
+	if c.Added > 0 {
+		parts = append(parts, fmt.Sprintf("+%d", c.Added))
+	}
+	if c.Changed > 0 {
+		parts = append(parts, fmt.Sprintf("~%d", c.Changed))
+	}
+	if c.Removed > 0 {
+		parts = append(parts, fmt.Sprintf("-%d", c.Removed))
+	}
+	if len(parts) == 0 {
+		return "unchanged"
 	}
+	return strings.Join(parts, " ")
+}
+
+// formatBytes formats a size as a human-readable string (e.g. "812 B", "14.2 KB").
+func formatBytes(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
 }
 
 // PrintBuildSummaryTiming displays a detailed per-stage timing breakdown.
@@ -95,19 +160,36 @@ func PrintBuildSummaryTiming(results []build.Result, outputDir string, timing *b
 		total += r.Files
 	}
 
-	fmt.Println()
-	fmt.Println("  " + cli.Info("Build Timing"))
-	fmt.Println("  " + strings.Repeat("─", 40))
+	cli.Println()
+	cli.Println("  " + cli.Info("Build Timing"))
+	cli.Println("  " + strings.Repeat("─", 40))
 	for _, r := range results {
-		fmt.Printf("  %-14s %3d files  %6s\n", r.Name, r.Files, formatDuration(r.Duration))
+		took := formatDuration(r.Duration)
+		if r.Skipped {
+			took = "cached"
+		}
+		cli.Printf("  %-14s %3d files  %6s\n", r.Name, r.Files, took)
 	}
-	fmt.Println("  " + strings.Repeat("─", 40))
+	cli.Println("  " + strings.Repeat("─", 40))
 	if timing != nil {
-		fmt.Printf("  %-14s %3d files  %6s\n", "Total", total, formatDuration(timing.Total))
+		cli.Printf("  %-14s %3d files  %6s\n", "Total", total, formatDuration(timing.Total))
 	}
-	fmt.Println()
+	cli.Println()
 	if timing != nil {
-		fmt.Println(cli.Success(fmt.Sprintf("Build complete — %d files in %s/ (%s)", total, outputDir, formatDuration(timing.Total))))
+		cli.Println(cli.Success(fmt.Sprintf("Build complete — %d files in %s/ (%s)", total, outputDir, formatDuration(timing.Total))))
+	}
+}
+
+// PrintGeneratedFiles lists the files each generator wrote, for --verbose.
+func PrintGeneratedFiles(results []build.Result, outputDir string) {
+	if !cli.Verbose() {
+		return
+	}
+	for _, r := range results {
+		cli.Verbosef("  %s (%d):", r.Name, len(r.Paths))
+		for _, p := range r.Paths {
+			cli.Verbosef("    %s", filepath.Join(outputDir, p))
+		}
 	}
 }
 
@@ -145,8 +227,45 @@ func PrintAuditReport(report string) {
 	}
 }
 
+// PrintComplianceChecks prints each requirement of a compliance profile
+// and whether the .human file meets it.
+func PrintComplianceChecks(profile string, checks []quality.ComplianceCheck) {
+	fmt.Println()
+	fmt.Println(cli.Info(fmt.Sprintf("Compliance (%s)", profile)))
+	failed := 0
+	for _, c := range checks {
+		line := fmt.Sprintf("  %-22s %s", c.Requirement, c.Detail)
+		switch c.Status {
+		case "fail":
+			failed++
+			fmt.Println(cli.Error(line))
+		case "warn":
+			fmt.Println(cli.Warn(line))
+		default:
+			fmt.Println(cli.Success(line))
+		}
+		if c.Fix != "" {
+			fmt.Printf("    %-22s → %s\n", "", c.Fix)
+		}
+	}
+	if failed > 0 {
+		fmt.Println()
+		fmt.Println(cli.Error(fmt.Sprintf("%d %s requirement%s not met.", failed, profile, Plural(failed))))
+	}
+}
+
 // CheckSummary returns a formatted summary of what was found in a parsed program.
 func CheckSummary(prog *parser.Program, file string) string {
+	msg := fmt.Sprintf("%s is valid", file)
+	if counts := ProgramCounts(prog); counts != "" {
+		msg += " — " + counts
+	}
+	return msg
+}
+
+// ProgramCounts returns what a parsed program declares, e.g.
+// "2 data models, 1 page", or "" if it declares none of them.
+func ProgramCounts(prog *parser.Program) string {
 	var parts []string
 	if len(prog.Data) > 0 {
 		parts = append(parts, fmt.Sprintf("%d data model%s", len(prog.Data), Plural(len(prog.Data))))
@@ -176,11 +295,7 @@ func CheckSummary(prog *parser.Program, file string) string {
 		parts = append(parts, fmt.Sprintf("%d error handler%s", len(prog.ErrorHandlers), Plural(len(prog.ErrorHandlers))))
 	}
 
-	msg := fmt.Sprintf("%s is valid", file)
-	if len(parts) > 0 {
-		msg += " — " + strings.Join(parts, ", ")
-	}
-	return msg
+	return strings.Join(parts, ", ")
 }
 
 // Plural returns "s" for n != 1, empty string for n == 1.
@@ -45,8 +45,8 @@ func DeployDocker(app *ir.Application, outputDir string, dryRun bool) error {
 	envExamplePath := filepath.Join(outputDir, ".env.example")
 	if _, err := os.Stat(envPath); os.IsNotExist(err) {
 		if _, err := os.Stat(envExamplePath); err == nil {
-			fmt.Println(cli.Warn("No .env file found. Copying .env.example → .env"))
-			fmt.Println(cli.Warn("Review and update .env with production values before deploying to production."))
+			cli.Println(cli.Warn("No .env file found. Copying .env.example → .env"))
+			cli.Println(cli.Warn("Review and update .env with production values before deploying to production."))
 			if !dryRun {
 				content, readErr := os.ReadFile(envExamplePath)
 				if readErr != nil {
@@ -59,35 +59,42 @@ func DeployDocker(app *ir.Application, outputDir string, dryRun bool) error {
 		}
 	}
 
+	composeCmd = withProfiles(composeCmd, outputDir)
+
 	// Build step
 	buildArgs := append(composeCmd, "build")
-	fmt.Println(cli.Info(fmt.Sprintf("Step 1/2: %s", strings.Join(buildArgs, " "))))
+	buildTitle := fmt.Sprintf("Step 1/2: %s", strings.Join(buildArgs, " "))
 	if dryRun {
-		fmt.Println(cli.Info("  (dry-run — skipped)"))
+		cli.Println(cli.Info(buildTitle))
+		cli.Println(cli.Info("  (dry-run — skipped)"))
 	} else {
-		if err := RunCommand(outputDir, buildArgs[0], buildArgs[1:]...); err != nil {
+		if err := RunInSection(buildTitle, outputDir, buildArgs[0], buildArgs[1:]...); err != nil {
 			return fmt.Errorf("Docker build failed: %w", err)
 		}
 	}
 
 	// Up step
 	upArgs := append(composeCmd, "up", "-d")
-	fmt.Println(cli.Info(fmt.Sprintf("Step 2/2: %s", strings.Join(upArgs, " "))))
+	upTitle := fmt.Sprintf("Step 2/2: %s", strings.Join(upArgs, " "))
 	if dryRun {
-		fmt.Println(cli.Info("  (dry-run — skipped)"))
+		cli.Println(cli.Info(upTitle))
+		cli.Println(cli.Info("  (dry-run — skipped)"))
 	} else {
-		if err := RunCommand(outputDir, upArgs[0], upArgs[1:]...); err != nil {
+		if err := RunInSection(upTitle, outputDir, upArgs[0], upArgs[1:]...); err != nil {
 			return fmt.Errorf("Docker deploy failed: %w", err)
 		}
 	}
 
 	if dryRun {
-		fmt.Println(cli.Success("Dry run complete — no changes were made."))
+		cli.Println(cli.Success("Dry run complete — no changes were made."))
 	} else {
-		fmt.Println(cli.Success(fmt.Sprintf("Deployed %s via Docker.", app.Name)))
-		fmt.Println(cli.Info("  Run 'docker compose ps' in .human/output/ to check status."))
-		fmt.Println(cli.Info("  Run 'docker compose logs -f' to view logs."))
-		fmt.Println(cli.Info("  Run 'docker compose down' to stop."))
+		cli.Println(cli.Success(fmt.Sprintf("Deployed %s via Docker.", app.Name)))
+		if env := ir.PublicEnvironment(app); env != nil && hasCaddyfile(outputDir) {
+			cli.Println(cli.Info(fmt.Sprintf("  Caddy serves https://%s once its DNS points at this host (set DOMAIN in .env to change it).", env.Host())))
+		}
+		cli.Println(cli.Info("  Run 'docker compose ps' in .human/output/ to check status."))
+		cli.Println(cli.Info("  Run 'docker compose logs -f' to view logs."))
+		cli.Println(cli.Info("  Run 'docker compose down' to stop."))
 	}
 	return nil
 }
@@ -98,7 +105,7 @@ func StopDocker(outputDir string) error {
 	if err != nil {
 		return err
 	}
-	downArgs := append(composeCmd, "down")
+	downArgs := append(withProfiles(composeCmd, outputDir), "down")
 	return RunCommand(outputDir, downArgs[0], downArgs[1:]...)
 }
 
@@ -111,3 +118,17 @@ func DockerStatus(outputDir string) error {
 	psArgs := append(composeCmd, "ps")
 	return RunCommandSilent(outputDir, psArgs[0], psArgs[1:]...)
 }
+
+// withProfiles enables the "https" Compose profile when the build put
+// Caddy in front of the app for an environment URL.
+func withProfiles(composeCmd []string, outputDir string) []string {
+	if !hasCaddyfile(outputDir) {
+		return composeCmd
+	}
+	return append(composeCmd[:len(composeCmd):len(composeCmd)], "--profile", "https")
+}
+
+func hasCaddyfile(outputDir string) bool {
+	_, err := os.Stat(filepath.Join(outputDir, "Caddyfile"))
+	return err == nil
+}
@@ -3,14 +3,17 @@ package cmdutil
 import (
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/barun-bash/human/internal/cli"
 	"github.com/barun-bash/human/internal/config"
+	"github.com/barun-bash/human/internal/ir"
 	"github.com/barun-bash/human/internal/version"
 )
 
@@ -22,24 +25,35 @@ type DoctorCheck struct {
 	Fix    string // suggested fix (empty if ok)
 }
 
-// RunDoctor performs environment, configuration, and project health checks.
-func RunDoctor(out io.Writer) {
+// RunDoctor performs environment, port, configuration, and project health
+// checks. Tools and ports the project's build config depends on fail when
+// missing or taken; the rest only warn. It returns the number of failures.
+func RunDoctor(out io.Writer) int {
 	fmt.Fprintln(out)
 
+	// The project decides which tools and ports are required, so it is
+	// analyzed first but reported last.
+	projChecks, app := checkProject()
+	needs := projectNeeds(app)
+
 	// Environment checks.
-	envChecks := checkEnvironment()
+	envChecks := checkEnvironment(needs)
 	printSection(out, "Environment", envChecks)
 
+	// Port checks.
+	portChecks := checkPorts(needs)
+	printSection(out, "Ports", portChecks)
+
 	// Configuration checks.
 	cfgChecks := checkConfiguration()
 	printSection(out, "Configuration", cfgChecks)
 
 	// Project checks.
-	projChecks := checkProject()
 	printSection(out, "Project", projChecks)
 
 	// Summary.
-	allChecks := append(envChecks, cfgChecks...)
+	allChecks := append(envChecks, portChecks...)
+	allChecks = append(allChecks, cfgChecks...)
 	allChecks = append(allChecks, projChecks...)
 
 	fails := 0
@@ -62,9 +76,65 @@ func RunDoctor(out io.Writer) {
 		fmt.Fprintln(out, cli.Success("All checks passed. Ready to build."))
 	}
 	fmt.Fprintln(out)
+	return fails
+}
+
+// toolNeeds records which external tools the project in the current
+// directory needs, so a missing tool it depends on is a failure rather
+// than a warning.
+type toolNeeds struct {
+	known  bool // a valid .human file was found
+	stack  string
+	node   bool
+	python bool
+	// nodeBackend and postgres pick which default ports the project uses.
+	nodeBackend bool
+	postgres    bool
+	golang      bool
+	docker      bool
+	terraform   bool
+}
+
+// projectNeeds derives the tools the app's build config depends on.
+func projectNeeds(app *ir.Application) toolNeeds {
+	n := toolNeeds{}
+	if app == nil || app.Config == nil {
+		return n
+	}
+	n.known = true
+	cfg := app.Config
+	var parts []string
+	for _, p := range []string{cfg.Frontend, cfg.Backend, cfg.Deploy} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	n.stack = strings.Join(parts, ", ")
+
+	backend := strings.ToLower(cfg.Backend)
+	deploy := strings.ToLower(cfg.Deploy)
+	n.nodeBackend = strings.Contains(backend, "node") || strings.Contains(backend, "express")
+	n.node = cfg.Frontend != "" || n.nodeBackend
+	n.python = strings.Contains(backend, "python") || strings.Contains(backend, "fastapi") ||
+		strings.Contains(backend, "django") || strings.Contains(backend, "flask")
+	n.golang = backend == "go" || strings.HasPrefix(backend, "go ") || strings.Contains(backend, "gin") ||
+		strings.Contains(backend, "fiber") || strings.Contains(backend, "golang")
+	n.postgres = strings.Contains(strings.ToLower(cfg.Database), "postgres")
+	n.docker = strings.Contains(deploy, "docker")
+	n.terraform = strings.Contains(deploy, "aws") || strings.Contains(deploy, "gcp") || strings.Contains(deploy, "google")
+	return n
 }
 
-func checkEnvironment() []DoctorCheck {
+// toolCheck describes an external tool doctor looks for.
+type toolCheck struct {
+	name    string
+	cmds    [][]string // version commands to try, in order; the first that runs wins
+	needed  bool
+	purpose string // what the tool is for, shown when it is missing
+	fix     string
+}
+
+func checkEnvironment(needs toolNeeds) []DoctorCheck {
 	var checks []DoctorCheck
 
 	// Human compiler version.
@@ -74,89 +144,121 @@ func checkEnvironment() []DoctorCheck {
 		Detail: fmt.Sprintf("v%s", version.Info()),
 	})
 
-	// Go runtime.
-	checks = append(checks, DoctorCheck{
-		Name:   "Go runtime",
-		Status: "ok",
-		Detail: runtime.Version(),
-	})
+	tools := []toolCheck{
+		{"Docker", [][]string{{"docker", "--version"}}, needs.docker,
+			"needed for deploy", "Install Docker from https://docs.docker.com/get-docker/"},
+		{"Docker Compose", [][]string{{"docker", "compose", "version"}, {"docker-compose", "--version"}}, needs.docker,
+			"needed for 'human run' and Docker deploys", "Install the Docker Compose plugin: https://docs.docker.com/compose/install/"},
+		{"Node.js", [][]string{{"node", "--version"}}, needs.node,
+			"needed for frontends and Node backends", "Install Node.js 18+ from https://nodejs.org"},
+		{"npm", [][]string{{"npm", "--version"}}, needs.node,
+			"needed to install frontend dependencies", "npm ships with Node.js — reinstall Node from https://nodejs.org"},
+		{"Python", [][]string{{"python3", "--version"}, {"python", "--version"}}, needs.python,
+			"needed for Python backends", "Install Python 3.10+ from https://python.org"},
+		{"Go toolchain", [][]string{{"go", "version"}}, needs.golang,
+			"needed for Go backends", "Install Go from https://go.dev/dl/"},
+		{"Terraform", [][]string{{"terraform", "--version"}}, needs.terraform,
+			"needed for AWS/GCP deploy", "Install Terraform from https://developer.hashicorp.com/terraform/install"},
+	}
+	for _, t := range tools {
+		checks = append(checks, checkTool(t, needs.stack))
+	}
 
-	// Docker.
-	if path, err := exec.LookPath("docker"); err == nil {
-		ver := getCommandVersion("docker", "--version")
-		checks = append(checks, DoctorCheck{
-			Name:   "Docker",
-			Status: "ok",
-			Detail: ver,
-		})
-		_ = path
-	} else {
-		checks = append(checks, DoctorCheck{
-			Name:   "Docker",
-			Status: "warn",
-			Detail: "not found",
-			Fix:    "Install Docker from https://docker.com (needed for deploy)",
-		})
+	return checks
+}
+
+// checkTool runs a tool's version commands and reports the first that
+// succeeds. A missing tool fails when the project needs it.
+func checkTool(t toolCheck, stack string) DoctorCheck {
+	for _, c := range t.cmds {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		out, err := exec.Command(c[0], c[1:]...).Output()
+		if err != nil {
+			continue
+		}
+		ver := strings.TrimSpace(string(out))
+		// Some tools (terraform) print several lines; take the first.
+		if idx := strings.Index(ver, "\n"); idx > 0 {
+			ver = ver[:idx]
+		}
+		return DoctorCheck{Name: t.name, Status: "ok", Detail: ver}
 	}
 
-	// Node.js.
-	if _, err := exec.LookPath("node"); err == nil {
-		ver := getCommandVersion("node", "--version")
-		checks = append(checks, DoctorCheck{
-			Name:   "Node.js",
-			Status: "ok",
-			Detail: ver,
-		})
-	} else {
-		checks = append(checks, DoctorCheck{
-			Name:   "Node.js",
-			Status: "warn",
-			Detail: "not found",
-			Fix:    "Install Node.js from https://nodejs.org (needed for frontend)",
-		})
+	if t.needed {
+		return DoctorCheck{
+			Name:   t.name,
+			Status: "fail",
+			Detail: fmt.Sprintf("not found — this project needs it (%s)", stack),
+			Fix:    t.fix,
+		}
 	}
+	return DoctorCheck{
+		Name:   t.name,
+		Status: "warn",
+		Detail: "not found (" + t.purpose + ")",
+		Fix:    t.fix,
+	}
+}
 
-	// Python.
-	if _, err := exec.LookPath("python3"); err == nil {
-		ver := getCommandVersion("python3", "--version")
-		checks = append(checks, DoctorCheck{
-			Name:   "Python",
-			Status: "ok",
-			Detail: ver,
-		})
-	} else {
-		checks = append(checks, DoctorCheck{
-			Name:   "Python",
-			Status: "warn",
-			Detail: "not found",
-			Fix:    "Install Python from https://python.org (needed for Python backend)",
-		})
+// checkPorts reports which of the generated services' default dev ports
+// are already taken on this machine. A taken port the project uses fails.
+func checkPorts(needs toolNeeds) []DoctorCheck {
+	ports := []struct {
+		port   int
+		use    string
+		needed bool
+	}{
+		{3001, "Node backend", needs.nodeBackend},
+		{8000, "Python backend", needs.python},
+		{8080, "Go backend", needs.golang},
+		{5432, "PostgreSQL", needs.postgres},
 	}
 
-	// Terraform.
-	if _, err := exec.LookPath("terraform"); err == nil {
-		ver := getCommandVersion("terraform", "--version")
-		// Terraform --version may output multiple lines; take the first.
-		if idx := strings.Index(ver, "\n"); idx > 0 {
-			ver = ver[:idx]
+	var checks []DoctorCheck
+	for _, p := range ports {
+		name := fmt.Sprintf("Port %d", p.port)
+		use := p.use
+		if p.needed {
+			use += ", used by this project"
+		}
+		if portFree(p.port) {
+			checks = append(checks, DoctorCheck{Name: name, Status: "ok", Detail: "free (" + use + ")"})
+			continue
+		}
+		status := "warn"
+		if p.needed {
+			status = "fail"
 		}
 		checks = append(checks, DoctorCheck{
-			Name:   "Terraform",
-			Status: "ok",
-			Detail: ver,
-		})
-	} else {
-		checks = append(checks, DoctorCheck{
-			Name:   "Terraform",
-			Status: "warn",
-			Detail: "not found",
-			Fix:    "Install Terraform from https://terraform.io (needed for AWS/GCP deploy)",
+			Name:   name,
+			Status: status,
+			Detail: "in use (" + use + ")",
+			Fix:    portFix(p.port),
 		})
 	}
-
 	return checks
 }
 
+// portFree reports whether a TCP port can be bound on this machine.
+func portFree(port int) bool {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	ln.Close()
+	return true
+}
+
+func portFix(port int) string {
+	find := fmt.Sprintf("lsof -i :%d", port)
+	if runtime.GOOS == "windows" {
+		find = fmt.Sprintf("netstat -ano | findstr :%d", port)
+	}
+	return fmt.Sprintf("Find and stop the process using it: %s", find)
+}
+
 func checkConfiguration() []DoctorCheck {
 	var checks []DoctorCheck
 
@@ -172,7 +274,7 @@ func checkConfiguration() []DoctorCheck {
 			Name:   "Project config",
 			Status: "fail",
 			Detail: err.Error(),
-			Fix:    "Run 'human init' to create a project",
+			Fix:    "Fix the JSON in .human/config.json, or delete it to use defaults",
 		})
 	} else {
 		configPath := filepath.Join(cwd, ".human", "config.json")
@@ -191,34 +293,158 @@ func checkConfiguration() []DoctorCheck {
 			})
 		}
 
-		// LLM configured.
-		if cfg.LLM != nil {
-			checks = append(checks, DoctorCheck{
-				Name:   "LLM provider",
-				Status: "ok",
-				Detail: fmt.Sprintf("%s (%s)", cfg.LLM.Provider, cfg.LLM.Model),
-			})
-		} else if os.Getenv("ANTHROPIC_API_KEY") != "" || os.Getenv("OPENAI_API_KEY") != "" {
-			checks = append(checks, DoctorCheck{
-				Name:   "LLM provider",
-				Status: "ok",
-				Detail: "detected from environment",
-			})
-		} else {
-			checks = append(checks, DoctorCheck{
+		checks = append(checks, validateConfig(cfg)...)
+		checks = append(checks, checkLLM(cfg))
+	}
+
+	// User-wide config and settings.
+	if _, err := config.LoadGlobalConfig(); err != nil {
+		checks = append(checks, DoctorCheck{
+			Name:   "Global config",
+			Status: "fail",
+			Detail: err.Error(),
+			Fix:    "Fix or delete ~/.human/config.json and run /connect again",
+		})
+	}
+	if _, err := config.LoadGlobal(); err != nil {
+		checks = append(checks, DoctorCheck{
+			Name:   "Global settings",
+			Status: "fail",
+			Detail: err.Error(),
+			Fix:    "Fix or delete ~/.human/settings.json",
+		})
+	}
+
+	return checks
+}
+
+// llmKeyEnv lists the API key variables of the hosted LLM providers.
+var llmKeyEnv = []struct{ provider, env string }{
+	{"anthropic", "ANTHROPIC_API_KEY"},
+	{"openai", "OPENAI_API_KEY"},
+	{"gemini", "GEMINI_API_KEY"},
+	{"groq", "GROQ_API_KEY"},
+	{"openrouter", "OPENROUTER_API_KEY"},
+}
+
+// checkLLM reports whether the configured LLM provider has a key, or which
+// provider keys are set in the environment. LLM features are optional, so
+// nothing here fails.
+func checkLLM(cfg *config.Config) DoctorCheck {
+	if cfg.LLM != nil {
+		if _, err := config.ResolveAPIKey(cfg.LLM.Provider); err != nil {
+			return DoctorCheck{
 				Name:   "LLM provider",
 				Status: "warn",
-				Detail: "not configured (optional)",
-				Fix:    "Run 'human connect' or set ANTHROPIC_API_KEY",
-			})
+				Detail: fmt.Sprintf("%s configured but %v", cfg.LLM.Provider, err),
+				Fix:    "Set the API key in your environment or run /connect " + cfg.LLM.Provider + " in the REPL",
+			}
+		}
+		return DoctorCheck{
+			Name:   "LLM provider",
+			Status: "ok",
+			Detail: fmt.Sprintf("%s (%s)", cfg.LLM.Provider, cfg.LLM.Model),
 		}
 	}
 
+	var found []string
+	for _, k := range llmKeyEnv {
+		if os.Getenv(k.env) != "" {
+			found = append(found, k.env)
+		}
+	}
+	if len(found) > 0 {
+		return DoctorCheck{
+			Name:   "LLM provider",
+			Status: "ok",
+			Detail: "detected from environment (" + strings.Join(found, ", ") + ")",
+		}
+	}
+	return DoctorCheck{
+		Name:   "LLM provider",
+		Status: "warn",
+		Detail: "not configured (optional — needed for ask, suggest, edit -i)",
+		Fix:    "Set ANTHROPIC_API_KEY or OPENAI_API_KEY, or run /connect in the REPL",
+	}
+}
+
+// validateConfig checks the parts of .human/config.json that JSON decoding
+// alone doesn't: empty hook and command steps, unknown LLM providers, and
+// unnamed plugin entries.
+func validateConfig(cfg *config.Config) []DoctorCheck {
+	var problems []string
+
+	if cfg.LLM != nil {
+		switch cfg.LLM.Provider {
+		case "anthropic", "openai", "gemini", "groq", "openrouter", "ollama", "custom":
+		case "":
+			problems = append(problems, "llm.provider is empty")
+		default:
+			problems = append(problems, fmt.Sprintf("llm.provider %q is not a known provider", cfg.LLM.Provider))
+		}
+	}
+
+	if cfg.Hooks != nil {
+		for _, h := range []struct {
+			name  string
+			steps []string
+		}{
+			{HookPreBuild, cfg.Hooks.PreBuild},
+			{HookPostGenerate, cfg.Hooks.PostGenerate},
+			{HookPreDeploy, cfg.Hooks.PreDeploy},
+		} {
+			for i, step := range h.steps {
+				if strings.TrimSpace(step) == "" {
+					problems = append(problems, fmt.Sprintf("hooks.%s[%d] is empty", h.name, i))
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Commands))
+	for name := range cfg.Commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd := cfg.Commands[name]
+		if cmd == nil || len(cmd.Steps) == 0 {
+			problems = append(problems, fmt.Sprintf("commands.%s has no steps", name))
+			continue
+		}
+		for i, step := range cmd.Steps {
+			if strings.TrimSpace(step) == "" {
+				problems = append(problems, fmt.Sprintf("commands.%s.steps[%d] is empty", name, i))
+			}
+		}
+	}
+
+	for i, p := range cfg.Plugins {
+		if p == nil || p.Name == "" {
+			problems = append(problems, fmt.Sprintf("plugins[%d] has no name", i))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	checks := make([]DoctorCheck, 0, len(problems))
+	for _, p := range problems {
+		checks = append(checks, DoctorCheck{
+			Name:   "Config",
+			Status: "fail",
+			Detail: p,
+			Fix:    "Edit .human/config.json",
+		})
+	}
 	return checks
 }
 
-func checkProject() []DoctorCheck {
+// checkProject validates the .human files in the current directory and
+// returns the first one that analyzes cleanly.
+func checkProject() ([]DoctorCheck, *ir.Application) {
 	var checks []DoctorCheck
+	var app *ir.Application
 
 	// Find .human files in current directory.
 	matches, _ := filepath.Glob("*.human")
@@ -237,7 +463,7 @@ func checkProject() []DoctorCheck {
 			Detail: "no .human files found",
 			Fix:    "Create a .human file or cd to your project directory",
 		})
-		return checks
+		return checks, nil
 	}
 
 	for _, file := range files {
@@ -253,6 +479,9 @@ func checkProject() []DoctorCheck {
 
 		errCount := len(result.Errs.Errors())
 		warnCount := len(result.Errs.Warnings())
+		if errCount == 0 && app == nil {
+			app = result.App
+		}
 
 		if errCount > 0 {
 			checks = append(checks, DoctorCheck{
@@ -276,7 +505,7 @@ func checkProject() []DoctorCheck {
 		}
 	}
 
-	return checks
+	return checks, app
 }
 
 func printSection(out io.Writer, title string, checks []DoctorCheck) {
@@ -309,11 +538,3 @@ func printSection(out io.Writer, title string, checks []DoctorCheck) {
 	}
 	fmt.Fprintln(out)
 }
-
-func getCommandVersion(name string, args ...string) string {
-	out, err := exec.Command(name, args...).Output()
-	if err != nil {
-		return "installed"
-	}
-	return strings.TrimSpace(string(out))
-}
@@ -0,0 +1,135 @@
+package cmdutil
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/config"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func TestProjectNeeds(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ir.BuildConfig
+		want toolNeeds
+	}{
+		{
+			"react node docker",
+			ir.BuildConfig{Frontend: "React", Backend: "Node with Express", Database: "PostgreSQL", Deploy: "Docker"},
+			toolNeeds{node: true, nodeBackend: true, postgres: true, docker: true},
+		},
+		{
+			"python on aws",
+			ir.BuildConfig{Backend: "Python with FastAPI", Deploy: "AWS"},
+			toolNeeds{python: true, terraform: true},
+		},
+		{
+			"angular go",
+			ir.BuildConfig{Frontend: "Angular", Backend: "Go with Gin", Database: "SQLite"},
+			toolNeeds{node: true, golang: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			got := projectNeeds(&ir.Application{Config: &cfg})
+			got.known, got.stack = false, ""
+			if got != tt.want {
+				t.Errorf("projectNeeds = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if n := projectNeeds(nil); n.known || n.node || n.docker {
+		t.Errorf("projectNeeds(nil) = %+v, want nothing needed", n)
+	}
+}
+
+func TestCheckToolMissing(t *testing.T) {
+	tool := toolCheck{
+		name:    "Nothing",
+		cmds:    [][]string{{"human-doctor-no-such-tool", "--version"}},
+		purpose: "needed for nothing",
+		fix:     "Install it",
+	}
+
+	c := checkTool(tool, "")
+	if c.Status != "warn" || c.Fix != "Install it" {
+		t.Errorf("optional missing tool = %+v, want warn with fix", c)
+	}
+
+	tool.needed = true
+	c = checkTool(tool, "React, Docker")
+	if c.Status != "fail" || !strings.Contains(c.Detail, "React, Docker") {
+		t.Errorf("required missing tool = %+v, want fail naming the stack", c)
+	}
+}
+
+func TestPortFree(t *testing.T) {
+	ln, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer ln.Close()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	if portFree(port) {
+		t.Errorf("port %d is held by a listener but reported free", port)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := &config.Config{
+		LLM:   &config.LLMConfig{Provider: "anthropc"},
+		Hooks: &config.HooksConfig{PreBuild: []string{"make gen", "  "}},
+		Commands: map[string]*config.CommandConfig{
+			"ok":    {Steps: []string{"echo ok"}},
+			"empty": {Description: "does nothing"},
+		},
+		Plugins: []*config.PluginConfig{{Name: ""}},
+	}
+
+	checks := validateConfig(cfg)
+	var details []string
+	for _, c := range checks {
+		if c.Status != "fail" {
+			t.Errorf("config problem %q has status %q, want fail", c.Detail, c.Status)
+		}
+		details = append(details, c.Detail)
+	}
+	joined := strings.Join(details, "\n")
+	for _, want := range []string{
+		`llm.provider "anthropc"`,
+		"hooks.pre_build[1] is empty",
+		"commands.empty has no steps",
+		"plugins[0] has no name",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing problem %q in:\n%s", want, joined)
+		}
+	}
+	if len(checks) != 4 {
+		t.Errorf("got %d problems, want 4:\n%s", len(checks), joined)
+	}
+
+	if checks := validateConfig(&config.Config{}); len(checks) != 0 {
+		t.Errorf("empty config reported problems: %+v", checks)
+	}
+}
+
+func TestCheckLLMFromEnvironment(t *testing.T) {
+	for _, k := range llmKeyEnv {
+		t.Setenv(k.env, "")
+	}
+	if c := checkLLM(&config.Config{}); c.Status != "warn" {
+		t.Errorf("no keys: status = %q, want warn", c.Status)
+	}
+
+	t.Setenv("GROQ_API_KEY", "test")
+	c := checkLLM(&config.Config{})
+	if c.Status != "ok" || !strings.Contains(c.Detail, "GROQ_API_KEY") {
+		t.Errorf("with GROQ_API_KEY: %+v", c)
+	}
+}
@@ -0,0 +1,199 @@
+package cmdutil
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/cli"
+)
+
+// DriftReport says whether live infrastructure matches the generated
+// configuration.
+type DriftReport struct {
+	Target      string // "Terraform" or "Docker"
+	Environment string
+	Changes     []string // e.g. "aws_db_instance.main will be updated in-place"
+}
+
+// Drifted reports whether anything differs from the generated configuration.
+func (r *DriftReport) Drifted() bool {
+	return len(r.Changes) > 0
+}
+
+// planChangeRe matches the resource headers in terraform plan output,
+// e.g. "  # aws_db_instance.main will be updated in-place".
+var planChangeRe = regexp.MustCompile(`^\s*# (\S+) ((?:will|must) be .+|has changed|has been deleted)$`)
+
+// CheckTerraformDrift runs terraform plan -detailed-exitcode in tfDir,
+// with envName's tfvars when there are any. Exit code 2 means the plan
+// would change something, i.e. the live infrastructure has drifted.
+func CheckTerraformDrift(tfDir, envName string) (*DriftReport, error) {
+	if _, err := os.Stat(filepath.Join(tfDir, "main.tf")); err != nil {
+		return nil, fmt.Errorf("Terraform files not found. Run 'human build <file>' first")
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		return nil, fmt.Errorf("terraform not found in PATH. Install Terraform to check for drift")
+	}
+
+	if _, err := captureCommand(tfDir, "terraform", "init", "-input=false", "-no-color"); err != nil {
+		return nil, fmt.Errorf("terraform init failed: %w", err)
+	}
+
+	args := []string{"plan", "-detailed-exitcode", "-input=false", "-lock=false", "-no-color"}
+	if envName != "" {
+		tfvars := filepath.Join("envs", strings.ToLower(envName)+".tfvars")
+		if _, err := os.Stat(filepath.Join(tfDir, tfvars)); err == nil {
+			args = append(args, "-var-file="+tfvars)
+		}
+	}
+	out, err := captureCommand(tfDir, "terraform", args...)
+	report := &DriftReport{Target: "Terraform", Environment: envName}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return report, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 2:
+		report.Changes = parsePlanChanges(out)
+		if len(report.Changes) == 0 {
+			report.Changes = []string{"terraform plan reports changes"}
+		}
+		return report, nil
+	default:
+		return nil, fmt.Errorf("terraform plan failed: %w", err)
+	}
+}
+
+// parsePlanChanges lists the resources a plan would change, including
+// those it found changed outside of Terraform.
+func parsePlanChanges(out []byte) []string {
+	var changes []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if m := planChangeRe.FindStringSubmatch(scanner.Text()); m != nil {
+			changes = append(changes, m[1]+" "+m[2])
+		}
+	}
+	return changes
+}
+
+// CheckDockerDrift compares the services in docker-compose.yml with the
+// running containers. A service has drifted when it isn't running, or
+// when its container was created from a different configuration than
+// the one compose would create now.
+func CheckDockerDrift(outputDir string) (*DriftReport, error) {
+	if _, err := os.Stat(filepath.Join(outputDir, "docker-compose.yml")); err != nil {
+		return nil, fmt.Errorf("docker-compose.yml not found. Run 'human build <file>' first")
+	}
+	composeCmd, err := DetectComposeCommand()
+	if err != nil {
+		return nil, err
+	}
+	composeCmd = withProfiles(composeCmd, outputDir)
+
+	// "config --hash" prints "<service> <hash>" for each service.
+	out, err := captureCommand(outputDir, composeCmd[0], append(composeCmd[1:], "config", "--hash", "*")...)
+	if err != nil {
+		return nil, fmt.Errorf("reading docker-compose.yml: %w", err)
+	}
+	want := parseServiceHashes(out)
+
+	out, err = captureCommand(outputDir, composeCmd[0], append(composeCmd[1:], "ps", "-q")...)
+	if err != nil {
+		return nil, fmt.Errorf("listing containers: %w", err)
+	}
+	have := map[string]string{}
+	if ids := strings.Fields(string(out)); len(ids) > 0 {
+		format := `{{index .Config.Labels "com.docker.compose.service"}} {{index .Config.Labels "com.docker.compose.config-hash"}}`
+		out, err = captureCommand(outputDir, "docker", append([]string{"inspect", "--format", format}, ids...)...)
+		if err != nil {
+			return nil, fmt.Errorf("inspecting containers: %w", err)
+		}
+		have = parseServiceHashes(out)
+	}
+
+	report := &DriftReport{Target: "Docker"}
+	services := make([]string, 0, len(want))
+	for svc := range want {
+		services = append(services, svc)
+	}
+	sort.Strings(services)
+	for _, svc := range services {
+		switch hash, ok := have[svc]; {
+		case !ok:
+			report.Changes = append(report.Changes, svc+" is not running")
+		case hash != want[svc]:
+			report.Changes = append(report.Changes, svc+" is running an outdated configuration")
+		}
+	}
+	return report, nil
+}
+
+func parseServiceHashes(out []byte) map[string]string {
+	hashes := map[string]string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		if f := strings.Fields(line); len(f) == 2 {
+			hashes[f[0]] = f[1]
+		}
+	}
+	return hashes
+}
+
+// captureCommand runs a command in dir and returns its combined output.
+func captureCommand(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = envFor(name)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := errorLine(out); msg != "" {
+			return out, &commandError{msg: msg, err: err}
+		}
+	}
+	return out, err
+}
+
+// commandError keeps the underlying error (and its exit code) while
+// reporting the line of output that explains it.
+type commandError struct {
+	msg string
+	err error
+}
+
+func (e *commandError) Error() string { return e.msg }
+func (e *commandError) Unwrap() error { return e.err }
+
+// errorLine returns the first "Error:" line of out, else its last line.
+func errorLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "Error:") {
+			return line
+		}
+	}
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// PrintDriftReport prints whether the infrastructure has drifted and what
+// changed.
+func PrintDriftReport(r *DriftReport) {
+	scope := r.Target
+	if r.Environment != "" {
+		scope += " (" + r.Environment + ")"
+	}
+	if !r.Drifted() {
+		cli.Println(cli.Success(fmt.Sprintf("No drift — live %s infrastructure matches the generated configuration.", scope)))
+		return
+	}
+	cli.Println(cli.Warn(fmt.Sprintf("Drift detected — live %s infrastructure differs from the generated configuration:", scope)))
+	for _, c := range r.Changes {
+		fmt.Printf("  %s\n", c)
+	}
+}
@@ -0,0 +1,85 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// stubCommand puts a shell script named name on an otherwise empty PATH.
+func stubCommand(t *testing.T, name, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script stub")
+	}
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckTerraformDrift(t *testing.T) {
+	stubCommand(t, "terraform", `case "$*" in
+  init*) exit 0 ;;
+  *production*) echo 'Error: No valid credential sources found' >&2; exit 1 ;;
+  *staging*)
+    echo '  # aws_db_instance.main has changed'
+    echo '  # aws_db_instance.main will be updated in-place'
+    echo '  # aws_ecs_service.app must be replaced'
+    exit 2 ;;
+esac
+echo 'No changes. Your infrastructure matches the configuration.'
+`)
+	tfDir := generateTerraform(t, awsApp())
+
+	report, err := CheckTerraformDrift(tfDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Drifted() {
+		t.Errorf("exit code 0 means no drift, got %v", report.Changes)
+	}
+
+	report, err = CheckTerraformDrift(tfDir, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"aws_db_instance.main has changed",
+		"aws_db_instance.main will be updated in-place",
+		"aws_ecs_service.app must be replaced",
+	}
+	if strings.Join(report.Changes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changes: got %q, want %q", report.Changes, want)
+	}
+
+	if _, err := CheckTerraformDrift(tfDir, "production"); err == nil || !strings.Contains(err.Error(), "No valid credential sources") {
+		t.Errorf("a failed plan should be an error, not drift: %v", err)
+	}
+}
+
+func TestCheckDockerDrift(t *testing.T) {
+	stubCommand(t, "docker", `case "$*" in
+  "compose version") exit 0 ;;
+  "compose config --hash *") printf 'backend aaa\nfrontend bbb\npostgres ccc\n' ;;
+  "compose ps -q") printf 'c1\nc2\n' ;;
+  inspect*) printf 'backend aaa\nfrontend old\n' ;;
+esac
+`)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte("services: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := CheckDockerDrift(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"frontend is running an outdated configuration", "postgres is not running"}
+	if strings.Join(report.Changes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changes: got %q, want %q", report.Changes, want)
+	}
+}
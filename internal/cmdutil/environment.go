@@ -0,0 +1,46 @@
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// SelectEnvironment makes app's build target the environment called name,
+// whose URL the generated frontends call the API at and the backends
+// accept requests from.
+func SelectEnvironment(app *ir.Application, name string) error {
+	var available []string
+	for _, env := range app.Environments {
+		if !strings.EqualFold(env.Name, name) {
+			available = append(available, env.Name)
+			continue
+		}
+		if env.URL() == "" {
+			return fmt.Errorf("environment %q has no url — add a line like 'url is app.example.com' to build for it", env.Name)
+		}
+		if app.Config == nil {
+			app.Config = &ir.BuildConfig{}
+		}
+		app.Config.Env = env.Name
+		return nil
+	}
+	msg := fmt.Sprintf("environment %q not found", name)
+	if len(available) > 0 {
+		msg += ". Available: " + strings.Join(available, ", ")
+	}
+	return errors.New(msg)
+}
+
+// BuildForDeploy builds file for the environment called env, or for local
+// development when env is "", as human deploy does before deploying, and
+// returns the app it built.
+func BuildForDeploy(file, env string) (*ir.Application, error) {
+	rb, err := RebuildQuietly(file, env)
+	if err != nil {
+		return nil, err
+	}
+	return rb.Result.App, nil
+}
@@ -0,0 +1,89 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func TestSelectEnvironment(t *testing.T) {
+	app := &ir.Application{Environments: []*ir.Environment{
+		{Name: "staging", Config: map[string]string{"url": "staging example com"}},
+		{Name: "production", Config: map[string]string{"url": "example com"}},
+		{Name: "local", Config: map[string]string{}},
+	}}
+
+	if err := SelectEnvironment(app, "Production"); err != nil {
+		t.Fatalf("SelectEnvironment: %v", err)
+	}
+	if app.Config.Env != "production" {
+		t.Errorf("Config.Env: got %q", app.Config.Env)
+	}
+
+	err := SelectEnvironment(app, "qa")
+	if err == nil || !strings.Contains(err.Error(), "Available: staging, production, local") {
+		t.Errorf("unknown environment should list the available ones, got %v", err)
+	}
+	if err := SelectEnvironment(app, "local"); err == nil || !strings.Contains(err.Error(), "has no url") {
+		t.Errorf("an environment without a url can't be built for, got %v", err)
+	}
+}
+
+func TestBuildForDeployBakesEnvironmentURL(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("HUMAN_OFFLINE", "1") // skip the npm audit
+	source := `app Shop is a web application
+
+data Product:
+  has a name which is text
+
+page Home:
+  show a list of products
+
+api ListProducts:
+  fetch all products
+  respond with products
+
+environment production:
+  url is https://shop.example.com:8443
+
+build with:
+  frontend using React
+  backend using Node with Express
+  database using PostgreSQL
+  deploy to Docker
+`
+	if err := os.WriteFile("app.human", []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app, err := BuildForDeploy("app.human", "production")
+	if err != nil {
+		t.Fatalf("BuildForDeploy: %v", err)
+	}
+	if app.Config.Env != "production" {
+		t.Errorf("Config.Env: got %q", app.Config.Env)
+	}
+	data, err := os.ReadFile(filepath.Join(".human", "output", "react", ".env.production"))
+	if err != nil {
+		t.Fatalf("a deploy for an environment should write the frontend's .env.production: %v", err)
+	}
+	if !strings.Contains(string(data), "VITE_API_URL=https://shop.example.com:8443") {
+		t.Errorf("expected the environment's URL in .env.production, got:\n%s", data)
+	}
+
+	// Without --env, the deploy builds for local development.
+	app, err = BuildForDeploy("app.human", "")
+	if err != nil {
+		t.Fatalf("BuildForDeploy: %v", err)
+	}
+	if app.Config.Env != "" {
+		t.Errorf("Config.Env without an environment: got %q", app.Config.Env)
+	}
+	if _, err := BuildForDeploy("app.human", "staging"); err == nil || !strings.Contains(err.Error(), `"staging" not found`) {
+		t.Errorf("a deploy to an undeclared environment should fail, got %v", err)
+	}
+}
@@ -0,0 +1,465 @@
+package cmdutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// CostEstimate is the monthly cost of one environment's Terraform.
+type CostEstimate struct {
+	Environment string
+	Source      string // "infracost" or "built-in prices"
+	Lines       []CostLine
+	Total       float64
+	Fallback    string // why infracost wasn't used, when it wasn't
+}
+
+// CostLine is the monthly cost of one Terraform resource. Resources billed
+// only by usage, or with no known price, carry a Note instead.
+type CostLine struct {
+	Resource string // e.g. "aws_db_instance.main"
+	Detail   string // e.g. "db.t3.micro, Multi-AZ, 20 GB"
+	Monthly  float64
+	Note     string
+}
+
+// EstimateCosts estimates the monthly cost of the Terraform in tfDir for
+// envName, or for each of the app's environments when envName is empty.
+// It uses infracost when it is installed and falls back to built-in
+// prices otherwise.
+func EstimateCosts(app *ir.Application, tfDir, envName string) ([]*CostEstimate, error) {
+	if _, err := os.Stat(filepath.Join(tfDir, "variables.tf")); err != nil {
+		return nil, fmt.Errorf("Terraform files not found. Run 'human build <file>' first")
+	}
+
+	var envs []string
+	switch {
+	case envName != "":
+		envs = []string{envName}
+	case len(app.Environments) > 0:
+		for _, env := range app.Environments {
+			envs = append(envs, env.Name)
+		}
+	default:
+		envs = []string{""} // variables.tf defaults
+	}
+
+	_, lookErr := exec.LookPath("infracost")
+	var estimates []*CostEstimate
+	for _, env := range envs {
+		varFile := ""
+		if env != "" {
+			rel := filepath.Join("envs", strings.ToLower(env)+".tfvars")
+			if _, err := os.Stat(filepath.Join(tfDir, rel)); err == nil {
+				varFile = rel
+			}
+		}
+
+		fallback := "infracost is not installed"
+		if lookErr == nil {
+			e, err := infracostEstimate(tfDir, varFile)
+			if err == nil {
+				e.Environment = env
+				estimates = append(estimates, e)
+				continue
+			}
+			fallback = "infracost failed: " + err.Error()
+		}
+
+		e, err := builtinEstimate(tfDir, varFile)
+		if err != nil {
+			return nil, err
+		}
+		e.Environment = env
+		e.Fallback = fallback
+		estimates = append(estimates, e)
+	}
+	return estimates, nil
+}
+
+// PrintCostEstimate prints an estimate as a table of resources.
+func PrintCostEstimate(e *CostEstimate) {
+	env := e.Environment
+	if env == "" {
+		env = "default variables"
+	}
+	cli.Println(cli.Info(fmt.Sprintf("Monthly cost estimate — %s (%s)", env, e.Source)))
+	if e.Fallback != "" {
+		cli.Println(cli.Warn(fmt.Sprintf("%s; using built-in prices for us-east-1 / us-central1", e.Fallback)))
+	}
+
+	width := len("Total")
+	for _, l := range e.Lines {
+		width = max(width, len(lineLabel(l)))
+	}
+	for _, l := range e.Lines {
+		amount := fmt.Sprintf("$%.2f", l.Monthly)
+		if l.Note != "" {
+			amount = l.Note
+		}
+		fmt.Printf("  %-*s  %s\n", width, lineLabel(l), amount)
+	}
+	fmt.Printf("  %-*s  $%.2f/month\n", width, "Total", e.Total)
+	fmt.Println("  Usage-based charges (requests, data transfer, logs, storage growth) are not included.")
+}
+
+func lineLabel(l CostLine) string {
+	if l.Detail == "" {
+		return l.Resource
+	}
+	return l.Resource + " (" + l.Detail + ")"
+}
+
+// ── infracost ──
+
+type infracostOutput struct {
+	TotalMonthlyCost *string `json:"totalMonthlyCost"`
+	Projects         []struct {
+		Breakdown struct {
+			Resources []struct {
+				Name        string  `json:"name"`
+				MonthlyCost *string `json:"monthlyCost"`
+			} `json:"resources"`
+		} `json:"breakdown"`
+	} `json:"projects"`
+}
+
+func infracostEstimate(tfDir, varFile string) (*CostEstimate, error) {
+	args := []string{"breakdown", "--path", ".", "--format", "json", "--no-color"}
+	if varFile != "" {
+		args = append(args, "--terraform-var-file", varFile)
+	}
+	cmd := exec.Command("infracost", args...)
+	cmd.Dir = tfDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s", strings.SplitN(msg, "\n", 2)[0])
+		}
+		return nil, err
+	}
+	return parseInfracost(out)
+}
+
+// parseInfracost reads infracost's JSON output. Resources with no monthly
+// cost are billed by usage alone.
+func parseInfracost(data []byte) (*CostEstimate, error) {
+	var out infracostOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("reading infracost output: %w", err)
+	}
+	e := &CostEstimate{Source: "infracost"}
+	for _, p := range out.Projects {
+		for _, r := range p.Breakdown.Resources {
+			line := CostLine{Resource: r.Name}
+			if r.MonthlyCost == nil {
+				line.Note = "usage-based"
+			} else {
+				line.Monthly, _ = strconv.ParseFloat(*r.MonthlyCost, 64)
+			}
+			e.Lines = append(e.Lines, line)
+		}
+	}
+	if out.TotalMonthlyCost != nil {
+		e.Total, _ = strconv.ParseFloat(*out.TotalMonthlyCost, 64)
+	} else {
+		e.Total = sumLines(e.Lines)
+	}
+	return e, nil
+}
+
+// ── Built-in prices ──
+
+// Approximate on-demand list prices in USD, for us-east-1 on AWS and
+// us-central1 on GCP. Other regions cost somewhat more or less.
+const (
+	hoursPerMonth   = 730
+	secondsPerMonth = hoursPerMonth * 3600
+
+	fargateVCPUHour   = 0.04048
+	fargateGBHour     = 0.004445
+	albHour           = 0.0225
+	natGatewayHour    = 0.045
+	publicIPv4Hour    = 0.005
+	rdsStorageGBMonth = 0.115
+
+	cloudRunIdleVCPUSecond = 0.0000025
+	cloudRunIdleGiBSecond  = 0.0000025
+	cloudSQLVCPUHour       = 0.0413
+	cloudSQLGBHour         = 0.007
+	cloudSQLStorageGBMonth = 0.17
+	cloudSQLDiskGB         = 10
+	forwardingRuleHour     = 0.025
+)
+
+// rdsHourly is the hourly price of RDS PostgreSQL and MySQL instance classes.
+var rdsHourly = map[string]float64{
+	"db.t3.micro":   0.018,
+	"db.t3.small":   0.036,
+	"db.t3.medium":  0.072,
+	"db.t3.large":   0.145,
+	"db.t3.xlarge":  0.29,
+	"db.t4g.micro":  0.016,
+	"db.t4g.small":  0.032,
+	"db.t4g.medium": 0.065,
+	"db.t4g.large":  0.129,
+	"db.m5.large":   0.171,
+	"db.m6g.large":  0.152,
+	"db.r5.large":   0.25,
+	"db.r6g.large":  0.225,
+}
+
+// cloudSQLShared is the monthly price of Cloud SQL's shared-core tiers.
+// Custom tiers (db-custom-<vCPUs>-<MB>) are priced per vCPU and GB.
+var cloudSQLShared = map[string]float64{
+	"db-f1-micro": 7.67,
+	"db-g1-small": 25.55,
+}
+
+var cloudSQLCustomRe = regexp.MustCompile(`^db-custom-(\d+)-(\d+)$`)
+
+// usageBased lists resource types billed only by usage.
+var usageBased = map[string]bool{
+	"aws_ecr_repository":                  true,
+	"aws_cloudwatch_log_group":            true,
+	"aws_s3_bucket":                       true,
+	"aws_cloudfront_distribution":         true,
+	"google_artifact_registry_repository": true,
+	"google_compute_backend_bucket":       true,
+	"google_storage_bucket":               true,
+}
+
+// builtinEstimate prices the resources in tfDir's .tf files, with
+// variables from variables.tf's defaults and varFile.
+func builtinEstimate(tfDir, varFile string) (*CostEstimate, error) {
+	vars, err := terraformVariables(filepath.Join(tfDir, "variables.tf"))
+	if err != nil {
+		return nil, err
+	}
+	if varFile != "" {
+		if err := readTFVars(filepath.Join(tfDir, varFile), vars); err != nil {
+			return nil, err
+		}
+	}
+	resources, err := terraformResources(tfDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The generated Terraform sizes production up with these conditions.
+	production := vars["environment"] == "production"
+	e := &CostEstimate{Source: "built-in prices"}
+	for _, r := range resources {
+		if m := tfOptionalCountRe.FindStringSubmatch(r.Attrs["count"]); m != nil && vars[m[1]] == "" {
+			continue
+		}
+		line, ok := priceResource(r, vars, production)
+		if ok {
+			e.Lines = append(e.Lines, line)
+		}
+	}
+	// Priced resources first, then usage-based ones.
+	sort.SliceStable(e.Lines, func(i, j int) bool {
+		return e.Lines[i].Note == "" && e.Lines[j].Note != ""
+	})
+	e.Total = sumLines(e.Lines)
+	return e, nil
+}
+
+// priceResource returns the cost line for a resource, or false for
+// resources that cost nothing themselves (IAM roles, subnets, and so on).
+func priceResource(r tfResource, vars map[string]string, production bool) (CostLine, bool) {
+	line := CostLine{Resource: r.Type + "." + r.Name}
+	switch r.Type {
+	case "aws_ecs_service":
+		count := atoiDefault(vars["desired_count"], 1)
+		cpu := atoiDefault(vars["cpu"], 256)
+		memory := atoiDefault(vars["memory"], 512)
+		vcpu, gb := float64(cpu)/1024, float64(memory)/1024
+		line.Detail = fmt.Sprintf("Fargate, %d × %g vCPU / %g GB", count, vcpu, gb)
+		line.Monthly = float64(count) * (vcpu*fargateVCPUHour + gb*fargateGBHour) * hoursPerMonth
+	case "aws_lb":
+		line.Detail = "Application Load Balancer"
+		line.Monthly = albHour * hoursPerMonth
+	case "aws_nat_gateway":
+		line.Detail = "NAT gateway"
+		line.Monthly = natGatewayHour * hoursPerMonth
+	case "aws_eip":
+		line.Detail = "public IPv4 address"
+		line.Monthly = publicIPv4Hour * hoursPerMonth
+	case "aws_db_instance":
+		class := vars["db_instance_class"]
+		storage := atoiDefault(r.Attrs["allocated_storage"], 20)
+		copies := 1.0
+		line.Detail = fmt.Sprintf("%s, %d GB", class, storage)
+		if production {
+			copies = 2
+			line.Detail = fmt.Sprintf("%s, Multi-AZ, %d GB", class, storage)
+		}
+		hourly, ok := rdsHourly[class]
+		if !ok {
+			line.Note = "price unknown"
+			break
+		}
+		line.Monthly = copies * (hourly*hoursPerMonth + float64(storage)*rdsStorageGBMonth)
+	case "google_cloud_run_v2_service":
+		// One idle minimum instance (1 vCPU, 512 MiB) in production;
+		// elsewhere the service scales to zero.
+		if !production {
+			line.Detail = "scales to zero"
+			line.Note = "usage-based"
+			break
+		}
+		line.Detail = "1 minimum instance, 1 vCPU / 0.5 GiB"
+		line.Monthly = (cloudRunIdleVCPUSecond + 0.5*cloudRunIdleGiBSecond) * secondsPerMonth
+	case "google_compute_global_forwarding_rule":
+		line.Detail = "load balancer forwarding rule"
+		line.Monthly = forwardingRuleHour * hoursPerMonth
+	case "google_sql_database_instance":
+		tier := vars["db_tier"]
+		line.Detail = fmt.Sprintf("%s, %d GB SSD", tier, cloudSQLDiskGB)
+		storage := cloudSQLDiskGB * cloudSQLStorageGBMonth
+		if monthly, ok := cloudSQLShared[tier]; ok {
+			line.Monthly = monthly + storage
+		} else if m := cloudSQLCustomRe.FindStringSubmatch(tier); m != nil {
+			vcpus, _ := strconv.Atoi(m[1])
+			mb, _ := strconv.Atoi(m[2])
+			line.Monthly = (float64(vcpus)*cloudSQLVCPUHour+float64(mb)/1024*cloudSQLGBHour)*hoursPerMonth + storage
+		} else {
+			line.Note = "price unknown"
+		}
+	default:
+		if !usageBased[r.Type] {
+			return line, false
+		}
+		line.Note = "usage-based"
+	}
+	return line, true
+}
+
+func sumLines(lines []CostLine) float64 {
+	total := 0.0
+	for _, l := range lines {
+		total += l.Monthly
+	}
+	return total
+}
+
+func atoiDefault(s string, def int) int {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return def
+}
+
+// ── Reading Terraform ──
+
+type tfResource struct {
+	Type, Name string
+	Attrs      map[string]string // top-level "key = value" attributes
+}
+
+var (
+	tfVariableRe = regexp.MustCompile(`^variable\s+"([^"]+)"`)
+	tfResourceRe = regexp.MustCompile(`^resource\s+"([^"]+)"\s+"([^"]+)"`)
+	tfAssignRe   = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.+)$`)
+
+	// A resource that only exists when a variable is set, like the DNS
+	// records for domain_name.
+	tfOptionalCountRe = regexp.MustCompile(`^var\.(\w+) != "" \? 1 : 0$`)
+)
+
+// terraformVariables reads the default of each variable in variables.tf.
+func terraformVariables(path string) (map[string]string, error) {
+	vars := make(map[string]string)
+	err := scanBlocks(path, tfVariableRe, func(m []string, attrs map[string]string) {
+		if def, ok := attrs["default"]; ok {
+			vars[m[1]] = def
+		}
+	})
+	return vars, err
+}
+
+// terraformResources lists the resources declared in dir's .tf files.
+func terraformResources(dir string) ([]tfResource, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var resources []tfResource
+	for _, path := range paths {
+		err := scanBlocks(path, tfResourceRe, func(m []string, attrs map[string]string) {
+			resources = append(resources, tfResource{Type: m[1], Name: m[2], Attrs: attrs})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+// scanBlocks calls fn with the header match and top-level attributes of
+// each block in a .tf file whose header matches re. Values are unquoted.
+func scanBlocks(path string, re *regexp.Regexp, fn func(m []string, attrs map[string]string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var header []string
+	var attrs map[string]string
+	depth := 0
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if depth == 0 {
+			if m := re.FindStringSubmatch(line); m != nil {
+				header, attrs = m, make(map[string]string)
+			}
+		} else if depth == 1 && header != nil {
+			if m := tfAssignRe.FindStringSubmatch(line); m != nil {
+				attrs[m[1]] = strings.Trim(strings.TrimSpace(m[2]), `"`)
+			}
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth == 0 && header != nil {
+			fn(header, attrs)
+			header = nil
+		}
+	}
+	return sc.Err()
+}
+
+// readTFVars sets vars from a .tfvars file's "key = value" lines.
+func readTFVars(path string, vars map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := tfAssignRe.FindStringSubmatch(line); m != nil {
+			vars[m[1]] = strings.Trim(strings.TrimSpace(m[2]), `"`)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package cmdutil
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/codegen/terraform"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func awsApp() *ir.Application {
+	return &ir.Application{
+		Name:   "TaskFlow",
+		Config: &ir.BuildConfig{Backend: "Node with Express", Database: "PostgreSQL", Deploy: "AWS"},
+		Environments: []*ir.Environment{
+			{Name: "staging", Config: map[string]string{}},
+			{Name: "production", Config: map[string]string{}},
+		},
+	}
+}
+
+func generateTerraform(t *testing.T, app *ir.Application) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := (terraform.Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func findLine(e *CostEstimate, resource string) *CostLine {
+	for i := range e.Lines {
+		if e.Lines[i].Resource == resource {
+			return &e.Lines[i]
+		}
+	}
+	return nil
+}
+
+func TestEstimateCostsBuiltin(t *testing.T) {
+	t.Setenv("PATH", t.TempDir()) // no infracost
+	app := awsApp()
+	dir := generateTerraform(t, app)
+
+	estimates, err := EstimateCosts(app, dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(estimates) != 2 {
+		t.Fatalf("expected an estimate per environment, got %d", len(estimates))
+	}
+	staging, production := estimates[0], estimates[1]
+	if staging.Source != "built-in prices" || staging.Fallback != "infracost is not installed" {
+		t.Errorf("unexpected source %q, fallback %q", staging.Source, staging.Fallback)
+	}
+
+	db := findLine(staging, "aws_db_instance.main")
+	if db == nil || db.Detail != "db.t3.micro, 20 GB" {
+		t.Fatalf("staging database line: %+v", db)
+	}
+	if want := 0.018*730 + 20*0.115; math.Abs(db.Monthly-want) > 0.01 {
+		t.Errorf("staging database: got %.2f, want %.2f", db.Monthly, want)
+	}
+	if svc := findLine(staging, "aws_ecs_service.app"); svc == nil || svc.Detail != "Fargate, 2 × 0.25 vCPU / 0.5 GB" {
+		t.Errorf("staging service line: %+v", svc)
+	}
+	if findLine(staging, "aws_nat_gateway.main") == nil || findLine(staging, "aws_lb.main") == nil {
+		t.Error("expected the NAT gateway and load balancer to be priced")
+	}
+	if findLine(staging, "aws_iam_role.ecs_task_execution") != nil {
+		t.Error("free resources should not be listed")
+	}
+
+	// production.tfvars sizes up the database (Multi-AZ) and the service.
+	if db := findLine(production, "aws_db_instance.main"); db == nil || db.Detail != "db.t3.small, Multi-AZ, 20 GB" {
+		t.Errorf("production database line: %+v", db)
+	}
+	if production.Total <= staging.Total {
+		t.Errorf("production (%.2f) should cost more than staging (%.2f)", production.Total, staging.Total)
+	}
+}
+
+func TestEstimateCostsGCP(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	app := awsApp()
+	app.Config.Deploy = "GCP"
+	dir := generateTerraform(t, app)
+
+	estimates, err := EstimateCosts(app, dir, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := estimates[0]
+	if run := findLine(e, "google_cloud_run_v2_service.backend"); run == nil || run.Note != "usage-based" {
+		t.Errorf("staging Cloud Run should scale to zero: %+v", run)
+	}
+	if sql := findLine(e, "google_sql_database_instance.main"); sql == nil || math.Abs(sql.Monthly-(7.67+1.70)) > 0.01 {
+		t.Errorf("Cloud SQL line: %+v", sql)
+	}
+}
+
+func TestEstimateCostsInfracost(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script stub")
+	}
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	stub := `#!/bin/sh
+case "$*" in
+  *production*) echo "Error: No INFRACOST_API_KEY environment variable is set." >&2; exit 1 ;;
+esac
+echo '{"totalMonthlyCost":"42.5","projects":[{"breakdown":{"resources":[{"name":"aws_db_instance.main","monthlyCost":"30"},{"name":"aws_s3_bucket.frontend","monthlyCost":null}]}}]}'
+`
+	if err := os.WriteFile(filepath.Join(bin, "infracost"), []byte(stub), 0755); err != nil {
+		t.Fatal(err)
+	}
+	app := awsApp()
+	dir := generateTerraform(t, app)
+
+	estimates, err := EstimateCosts(app, dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	staging := estimates[0]
+	if staging.Source != "infracost" || staging.Total != 42.5 {
+		t.Errorf("staging: source %q, total %.2f", staging.Source, staging.Total)
+	}
+	if s3 := findLine(staging, "aws_s3_bucket.frontend"); s3 == nil || s3.Note != "usage-based" {
+		t.Errorf("a resource without a monthly cost is usage-based: %+v", s3)
+	}
+
+	production := estimates[1]
+	if production.Source != "built-in prices" || !strings.Contains(production.Fallback, "INFRACOST_API_KEY") {
+		t.Errorf("a failed infracost run should fall back: source %q, fallback %q", production.Source, production.Fallback)
+	}
+}
+
+func TestEstimateCostsWithoutTerraform(t *testing.T) {
+	if _, err := EstimateCosts(awsApp(), t.TempDir(), ""); err == nil {
+		t.Error("expected an error without generated Terraform")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/barun-bash/human/internal/cli"
 )
 
 // RunCommand executes a command in the given directory with stdin, stdout,
@@ -12,6 +14,7 @@ import (
 func RunCommand(dir, name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	cmd.Dir = dir
+	cmd.Env = envFor(name)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
@@ -23,11 +26,34 @@ func RunCommand(dir, name string, args ...string) error {
 func RunCommandSilent(dir, name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	cmd.Dir = dir
+	cmd.Env = envFor(name)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// envFor returns the environment for running name (see CommandEnv).
+// Terraform keeps the cloud credentials it deploys with, cosign its key
+// password, the hosting CLIs their access tokens, and Playwright (run with
+// npx) the URL the end-to-end tests visit.
+func envFor(name string) []string {
+	switch name {
+	case "terraform":
+		return CommandEnv(CloudCredentialEnv...)
+	case "cosign":
+		return CommandEnv("COSIGN_*")
+	case "fly", "flyctl":
+		return CommandEnv("FLY_*")
+	case "vercel":
+		return CommandEnv("VERCEL_*")
+	case "netlify":
+		return CommandEnv("NETLIFY_*")
+	case "npx":
+		return CommandEnv("E2E_*")
+	}
+	return CommandEnv()
+}
+
 // RequireOutputDir checks that .human/output/ exists and returns its path.
 // Returns an error if the directory does not exist.
 func RequireOutputDir() (string, error) {
@@ -37,3 +63,17 @@ func RequireOutputDir() (string, error) {
 	}
 	return outputDir, nil
 }
+
+// RunInSection runs a command in dir with its output streamed into a
+// cli.Section with the given title, which collapses once it succeeds.
+func RunInSection(title, dir, name string, args ...string) error {
+	sec := cli.NewSection(os.Stdout, title)
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = envFor(name)
+	cmd.Stdout = sec
+	cmd.Stderr = sec
+	err := cmd.Run()
+	sec.Done(err)
+	return err
+}
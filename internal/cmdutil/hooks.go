@@ -0,0 +1,109 @@
+package cmdutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/config"
+)
+
+// Lifecycle hook names, as written in .human/config.json.
+const (
+	HookPreBuild     = "pre_build"
+	HookPostGenerate = "post_generate"
+	HookPreDeploy    = "pre_deploy"
+)
+
+// HookContext is the build context handed to a hook. Paths are absolute;
+// fields not yet known at the hook's point in the lifecycle are empty.
+type HookContext struct {
+	Hook        string `json:"hook"`
+	Source      string `json:"source"`
+	IRPath      string `json:"ir_path,omitempty"`
+	OutputDir   string `json:"output_dir,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Target      string `json:"target,omitempty"`
+	DryRun      bool   `json:"dry_run,omitempty"`
+}
+
+// env returns the context as HUMAN_* environment variables.
+func (h HookContext) env() []string {
+	vars := []string{
+		"HUMAN_HOOK=" + h.Hook,
+		"HUMAN_SOURCE=" + h.Source,
+		"HUMAN_IR_PATH=" + h.IRPath,
+		"HUMAN_OUTPUT_DIR=" + h.OutputDir,
+		"HUMAN_ENVIRONMENT=" + h.Environment,
+		"HUMAN_DEPLOY_TARGET=" + h.Target,
+	}
+	if h.DryRun {
+		vars = append(vars, "HUMAN_DRY_RUN=1")
+	}
+	return vars
+}
+
+// IntentPath returns where a build writes the IR for a .human file:
+// .human/intent/<name>.yaml.
+func IntentPath(file string) string {
+	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	return filepath.Join(".human", "intent", base+".yaml")
+}
+
+// RunHooks runs the hooks configured for hc.Hook in .human/config.json in
+// dir, stopping at the first that fails.
+func RunHooks(dir string, hc HookContext) error {
+	cfg, err := config.Load(dir)
+	if err != nil {
+		return err
+	}
+	steps := hookSteps(cfg.Hooks, hc.Hook)
+	if len(steps) == 0 {
+		return nil
+	}
+	if err := RequireTrust(dir, "run its "+string(hc.Hook)+" hooks"); err != nil {
+		return err
+	}
+
+	for _, p := range []*string{&hc.Source, &hc.IRPath, &hc.OutputDir} {
+		if *p == "" || filepath.IsAbs(*p) {
+			continue
+		}
+		if abs, err := filepath.Abs(filepath.Join(dir, *p)); err == nil {
+			*p = abs
+		}
+	}
+	payload, err := json.Marshal(hc)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		cli.Println(cli.Info(fmt.Sprintf("%s: %s", hc.Hook, step)))
+		c := shellCommand(dir, step)
+		c.Env = append(c.Env, hc.env()...)
+		c.Stdin = bytes.NewReader(payload)
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("%s hook failed (%s): %w", hc.Hook, step, err)
+		}
+	}
+	return nil
+}
+
+func hookSteps(hooks *config.HooksConfig, name string) []string {
+	if hooks == nil {
+		return nil
+	}
+	switch name {
+	case HookPreBuild:
+		return hooks.PreBuild
+	case HookPostGenerate:
+		return hooks.PostGenerate
+	case HookPreDeploy:
+		return hooks.PreDeploy
+	}
+	return nil
+}
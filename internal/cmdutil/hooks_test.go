@@ -0,0 +1,75 @@
+package cmdutil
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/config"
+)
+
+func writeHooks(t *testing.T, dir string, hooks *config.HooksConfig) {
+	t.Helper()
+	if err := config.Save(dir, &config.Config{Hooks: hooks}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunHooksPassesContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks use POSIX shell syntax")
+	}
+	t.Setenv("HUMAN_TRUST_WORKSPACE", "1")
+	dir := t.TempDir()
+	writeHooks(t, dir, &config.HooksConfig{PostGenerate: []string{
+		`echo "$HUMAN_HOOK $HUMAN_OUTPUT_DIR" > env.txt`,
+		"cat > context.json",
+	}})
+
+	hc := HookContext{Hook: HookPostGenerate, Source: "app.human", IRPath: IntentPath("app.human"), OutputDir: filepath.Join(".human", "output")}
+	if err := RunHooks(dir, hc); err != nil {
+		t.Fatal(err)
+	}
+
+	env, _ := os.ReadFile(filepath.Join(dir, "env.txt"))
+	if want := "post_generate " + filepath.Join(dir, ".human", "output"); strings.TrimSpace(string(env)) != want {
+		t.Errorf("env = %q, want %q", strings.TrimSpace(string(env)), want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "context.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got HookContext
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("stdin should be the JSON context: %v", err)
+	}
+	if got.IRPath != filepath.Join(dir, ".human", "intent", "app.yaml") {
+		t.Errorf("ir_path = %q", got.IRPath)
+	}
+}
+
+func TestRunHooksStopsOnFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks use POSIX shell syntax")
+	}
+	t.Setenv("HUMAN_TRUST_WORKSPACE", "1")
+	dir := t.TempDir()
+	writeHooks(t, dir, &config.HooksConfig{PreDeploy: []string{"exit 1", "touch ran.txt"}})
+
+	err := RunHooks(dir, HookContext{Hook: HookPreDeploy, Source: "app.human"})
+	if err == nil || !strings.Contains(err.Error(), "pre_deploy hook failed") {
+		t.Fatalf("expected the pre_deploy hook to fail, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ran.txt")); err == nil {
+		t.Error("later hooks should not run after a failure")
+	}
+
+	// Hooks for other points in the lifecycle are not run.
+	if err := RunHooks(dir, HookContext{Hook: HookPreBuild, Source: "app.human"}); err != nil {
+		t.Errorf("pre_build has no hooks, got %v", err)
+	}
+}
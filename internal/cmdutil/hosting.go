@@ -0,0 +1,160 @@
+package cmdutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/codegen/hosting"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// hostingStep is one platform CLI command a hosting deploy runs.
+type hostingStep struct {
+	dir  string
+	name string
+	args []string
+	// flyApp, when set, is created first if it doesn't exist yet.
+	flyApp string
+}
+
+// DeployHosting deploys the build to Vercel, Netlify, or Fly.io with the
+// platform's CLI: the backend to Fly.io, then the frontend to the
+// platform. A dry run lists the commands without running them.
+func DeployHosting(app *ir.Application, outputDir string, dryRun bool) error {
+	platform := hosting.Platform(app)
+	steps, err := hostingSteps(app, outputDir)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range steps {
+		if _, err := os.Stat(filepath.Join(s.dir, configFile(s))); os.IsNotExist(err) {
+			return fmt.Errorf("%s not found. Run 'human build <file>' first", filepath.Join(s.dir, configFile(s)))
+		}
+	}
+
+	// Resolve each CLI before running anything, so a missing one fails
+	// before half the app is deployed.
+	for i, s := range steps {
+		name, err := platformCLI(s.name)
+		if err != nil {
+			if dryRun {
+				cli.Println(cli.Warn(err.Error()))
+				continue
+			}
+			return err
+		}
+		steps[i].name = name
+	}
+
+	for i, s := range steps {
+		title := fmt.Sprintf("Step %d/%d: %s %s (in %s)", i+1, len(steps), s.name, strings.Join(s.args, " "), s.dir)
+		if dryRun {
+			cli.Println(cli.Info(title))
+			cli.Println(cli.Info("  (dry-run — skipped)"))
+			continue
+		}
+		if s.flyApp != "" {
+			if err := ensureFlyApp(s.name, s.flyApp); err != nil {
+				return err
+			}
+		}
+		if err := RunInSection(title, s.dir, s.name, s.args...); err != nil {
+			return fmt.Errorf("%s deploy failed: %w", s.name, err)
+		}
+	}
+
+	if dryRun {
+		cli.Println(cli.Success("Dry run complete — no changes were made."))
+	} else {
+		cli.Println(cli.Success(fmt.Sprintf("Deployed %s to %s.", app.Name, app.Config.Deploy)))
+	}
+	if hosting.HasBackend(app) {
+		cli.Println(cli.Info(fmt.Sprintf("  The API runs on Fly.io at %s.", hosting.BackendURL(app))))
+		if secrets := hosting.Secrets(app); len(secrets) > 0 {
+			cli.Println(cli.Info(fmt.Sprintf("  It reads these secrets — set them with 'fly secrets set --app %s': %s", hosting.FlyApp(app, true), strings.Join(secrets, ", "))))
+		}
+	}
+	if platform == hosting.Fly && hosting.HasFrontend(app) {
+		cli.Println(cli.Info(fmt.Sprintf("  The app is served at https://%s.fly.dev.", hosting.FlyApp(app, false))))
+	}
+	return nil
+}
+
+// hostingSteps returns the commands deploying the app: Fly.io for the
+// backend, and the platform's CLI for the frontend.
+func hostingSteps(app *ir.Application, outputDir string) ([]hostingStep, error) {
+	var steps []hostingStep
+	if hosting.HasBackend(app) {
+		steps = append(steps, hostingStep{
+			dir:    filepath.Join(outputDir, docker.BackendDir(app)),
+			name:   "fly",
+			args:   []string{"deploy", "--remote-only"},
+			flyApp: hosting.FlyApp(app, true),
+		})
+	}
+	if hosting.HasFrontend(app) {
+		dir := filepath.Join(outputDir, docker.FrontendDir(app))
+		switch hosting.Platform(app) {
+		case hosting.Vercel:
+			steps = append(steps, hostingStep{dir: dir, name: "vercel", args: []string{"deploy", "--prod", "--yes"}})
+		case hosting.Netlify:
+			steps = append(steps, hostingStep{dir: dir, name: "netlify", args: []string{"deploy", "--build", "--prod"}})
+		case hosting.Fly:
+			steps = append(steps, hostingStep{dir: dir, name: "fly", args: []string{"deploy", "--remote-only"}, flyApp: hosting.FlyApp(app, false)})
+		}
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("nothing to deploy: add a frontend or backend to the build block")
+	}
+	return steps, nil
+}
+
+// configFile is the platform config a step deploys with.
+func configFile(s hostingStep) string {
+	switch s.name {
+	case "vercel":
+		return "vercel.json"
+	case "netlify":
+		return "netlify.toml"
+	}
+	return "fly.toml"
+}
+
+// platformCLI returns the command for a platform's CLI. Fly's installs as
+// fly or flyctl.
+func platformCLI(name string) (string, error) {
+	candidates := []string{name}
+	if name == "fly" {
+		candidates = append(candidates, "flyctl")
+	}
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c); err == nil {
+			return c, nil
+		}
+	}
+	install := map[string]string{
+		"fly":     "https://fly.io/docs/flyctl/install/",
+		"vercel":  "npm install -g vercel",
+		"netlify": "npm install -g netlify-cli",
+	}
+	return "", fmt.Errorf("%s not found in PATH. Install it to deploy: %s", name, install[name])
+}
+
+// ensureFlyApp creates a Fly.io app unless it already exists.
+func ensureFlyApp(fly, name string) error {
+	status := exec.Command(fly, "status", "--app", name)
+	status.Env = envFor(fly)
+	if status.Run() == nil {
+		return nil
+	}
+	if err := RunCommandSilent(".", fly, "apps", "create", name); err != nil {
+		return fmt.Errorf("creating Fly.io app %s: %w", name, err)
+	}
+	return nil
+}
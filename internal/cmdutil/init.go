@@ -9,6 +9,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/config"
 	"github.com/barun-bash/human/internal/ir"
 	"github.com/barun-bash/human/internal/parser"
 )
@@ -56,14 +58,23 @@ func AvailableDesignSystems() []DesignSystem {
 
 // InitProject scaffolds a new Human project via an interactive wizard.
 // Prompts for app type, entity/fields (for CRUD), stack, and design system.
-// Creates the project directory and writes app.human, HUMAN.md, and .gitignore.
-// Returns the path to the generated .human file.
-func InitProject(name string, multi bool, in io.Reader, out io.Writer) (string, error) {
+// The prompts default to the user's and team's defaults (see
+// config.LoadDefaults); with yes, nothing is asked and the defaults are
+// used as they are. Creates the project directory and writes app.human,
+// HUMAN.md, and .gitignore. Returns the path to the generated .human file.
+func InitProject(name string, multi, yes bool, in io.Reader, out io.Writer) (string, error) {
+	defaults, err := config.LoadDefaults(".")
+	if err != nil {
+		fmt.Fprintf(out, "Ignoring defaults: %v\n", err)
+	}
+
 	if name == "" {
-		fmt.Fprintf(out, "App name: ")
-		scanner := bufio.NewScanner(in)
-		if scanner.Scan() {
-			name = strings.TrimSpace(scanner.Text())
+		if !yes {
+			fmt.Fprintf(out, "App name: ")
+			scanner := bufio.NewScanner(in)
+			if scanner.Scan() {
+				name = strings.TrimSpace(scanner.Text())
+			}
 		}
 		if name == "" {
 			dir, err := os.Getwd()
@@ -74,6 +85,11 @@ func InitProject(name string, multi bool, in io.Reader, out io.Writer) (string,
 		}
 	}
 
+	// With yes, every prompt reads an empty answer and takes its default.
+	if yes {
+		in = strings.NewReader("")
+		out = io.Discard
+	}
 	scanner := bufio.NewScanner(in)
 
 	fmt.Fprintln(out, "\nCreate a new Human project (no LLM required)")
@@ -81,14 +97,19 @@ func InitProject(name string, multi bool, in io.Reader, out io.Writer) (string,
 
 	// Step 1: App type selection.
 	appTypes := AvailableAppTypes()
+	typeIdx := 0
+	for i, t := range appTypes {
+		if strings.EqualFold(defaults.AppType, t.Key) || strings.EqualFold(defaults.AppType, t.Name) {
+			typeIdx = i
+		}
+	}
 	fmt.Fprintln(out, "Select app type:")
 	for i, t := range appTypes {
 		fmt.Fprintf(out, "  %d. %-16s — %s\n", i+1, t.Name, t.Description)
 	}
 	fmt.Fprintln(out)
 
-	typeIdx := 0
-	fmt.Fprintf(out, "Choice [1]: ")
+	fmt.Fprintf(out, "Choice [%d]: ", typeIdx+1)
 	if scanner.Scan() {
 		input := strings.TrimSpace(scanner.Text())
 		if input != "" {
@@ -119,28 +140,33 @@ func InitProject(name string, multi bool, in io.Reader, out io.Writer) (string,
 	}
 
 	// Step 3: Try loading from examples directory for pre-built templates.
+	// They keep their own stack, except where the defaults choose one.
 	var content string
 	if chosen.ExampleDir != "" {
 		loaded, err := loadExampleTemplate(chosen.ExampleDir, name)
 		if err == nil {
-			content = loaded
+			content = applyDefaults(loaded, defaults)
 		}
 	}
 
 	// Step 4: If no example loaded, build from wizard.
 	if content == "" {
-		frontend := Prompt(scanner, out, "Frontend", []string{"React", "Vue", "Angular", "Svelte", "None"}, "React")
+		frontends := []string{"React", "Vue", "Angular", "Svelte", "None"}
+		frontend := Prompt(scanner, out, "Frontend", frontends, defaultOption(frontends, defaults.Frontend, "React"))
 
 		// Design system (only if frontend is selected).
 		designSystem := ""
 		if !strings.EqualFold(frontend, "None") {
-			designSystem = promptDesignSystem(scanner, out)
+			designSystem = promptDesignSystem(scanner, out, defaults.DesignSystem)
 		}
 
-		backend := Prompt(scanner, out, "Backend", []string{"Node", "Python", "Go"}, "Node")
-		database := Prompt(scanner, out, "Database", []string{"PostgreSQL", "MySQL", "SQLite"}, "PostgreSQL")
+		backends := []string{"Node", "Python", "Go"}
+		backend := Prompt(scanner, out, "Backend", backends, defaultOption(backends, defaults.Backend, "Node"))
+		databases := []string{"PostgreSQL", "MySQL", "SQLite"}
+		database := Prompt(scanner, out, "Database", databases, defaultOption(databases, defaults.Database, "PostgreSQL"))
 
 		content = generateFromType(name, chosen.Key, frontend, backend, database, designSystem, entityName, entityFields)
+		content = applyDefaults(content, &config.Defaults{Platform: defaults.Platform, Deploy: defaults.Deploy})
 	}
 
 	// Create project directory.
@@ -242,8 +268,16 @@ func promptEntityFields(scanner *bufio.Scanner, out io.Writer) (string, []entity
 
 // ── Design System Prompt ──
 
-func promptDesignSystem(scanner *bufio.Scanner, out io.Writer) string {
+// promptDesignSystem asks for a design system, defaulting to the one keyed
+// or named by preferred, else the first.
+func promptDesignSystem(scanner *bufio.Scanner, out io.Writer, preferred string) string {
 	systems := AvailableDesignSystems()
+	dsIdx := 0
+	for i, ds := range systems {
+		if strings.EqualFold(preferred, ds.Key) || strings.EqualFold(preferred, ds.Name) {
+			dsIdx = i
+		}
+	}
 	fmt.Fprintln(out, "\nSelect design system:")
 	for i, ds := range systems {
 		rec := ""
@@ -254,8 +288,7 @@ func promptDesignSystem(scanner *bufio.Scanner, out io.Writer) string {
 	}
 	fmt.Fprintln(out)
 
-	fmt.Fprintf(out, "Choice [1]: ")
-	dsIdx := 0
+	fmt.Fprintf(out, "Choice [%d]: ", dsIdx+1)
 	if scanner.Scan() {
 		input := strings.TrimSpace(scanner.Text())
 		if input != "" {
@@ -361,6 +394,7 @@ func generateAuthApp(name, frontend, backend, database, ds string) string {
 	b.WriteString("  update current user with name, email, avatar\n")
 	b.WriteString("  respond with success\n\n")
 
+	writeAuthenticationSection(&b)
 	writeBuildSection(&b, frontend, backend, database, ds)
 	return b.String()
 }
@@ -464,6 +498,7 @@ func generateCRUDApp(name, frontend, backend, database, ds, entityName string, f
 	b.WriteString("  requires authentication\n")
 	b.WriteString("  respond with current user\n\n")
 
+	writeAuthenticationSection(&b)
 	writeBuildSection(&b, frontend, backend, database, ds)
 	return b.String()
 }
@@ -518,6 +553,7 @@ func generateDashboardApp(name, frontend, backend, database, ds string) string {
 	b.WriteString("  fetch all User\n")
 	b.WriteString("  respond with items\n\n")
 
+	writeAuthenticationSection(&b)
 	writeBuildSection(&b, frontend, backend, database, ds)
 	return b.String()
 }
@@ -607,6 +643,7 @@ func generateEcommerceApp(name, frontend, backend, database, ds string) string {
 	b.WriteString("  fetch Order where user is current user\n")
 	b.WriteString("  respond with items\n\n")
 
+	writeAuthenticationSection(&b)
 	writeBuildSection(&b, frontend, backend, database, ds)
 	return b.String()
 }
@@ -687,6 +724,7 @@ func generateBlogApp(name, frontend, backend, database, ds string) string {
 	b.WriteString("  create Comment with content, author, post\n")
 	b.WriteString("  respond with success\n\n")
 
+	writeAuthenticationSection(&b)
 	writeBuildSection(&b, frontend, backend, database, ds)
 	return b.String()
 }
@@ -761,6 +799,7 @@ func generateSaaSApp(name, frontend, backend, database, ds string) string {
 	b.WriteString("  update Subscription with plan\n")
 	b.WriteString("  respond with success\n\n")
 
+	writeAuthenticationSection(&b)
 	writeBuildSection(&b, frontend, backend, database, ds)
 	return b.String()
 }
@@ -791,6 +830,15 @@ func generateEmptyApp(name, frontend, backend, database, ds string) string {
 
 // ── Shared Helpers ──
 
+// writeAuthenticationSection writes the authentication block the
+// templates' "requires authentication" APIs and pages need.
+func writeAuthenticationSection(b *strings.Builder) {
+	b.WriteString("# ── Authentication ──\n\n")
+	b.WriteString("authentication:\n")
+	b.WriteString("  method JWT tokens that expire in 7 days\n")
+	b.WriteString("  passwords are hashed with bcrypt\n\n")
+}
+
 func writeStandardAuthAPIs(b *strings.Builder) {
 	b.WriteString("api Register:\n")
 	b.WriteString("  accepts name, email, password\n")
@@ -821,6 +869,54 @@ func writeBuildSection(b *strings.Builder, frontend, backend, database, ds strin
 	b.WriteString("  deploy to Docker\n")
 }
 
+// applyDefaults rewrites a template for the defaults that are set: the
+// platform in its app line, and the frontend, backend, database, and
+// deploy target lines of its build section.
+func applyDefaults(content string, d *config.Defaults) string {
+	frontend := d.Frontend
+	if frontend != "" && d.DesignSystem != "" && !strings.EqualFold(frontend, "None") {
+		frontend += " with " + d.DesignSystem
+	}
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		switch {
+		case d.Platform != "" && strings.HasPrefix(trimmed, "app ") && strings.HasSuffix(trimmed, " application"):
+			if at := strings.Index(line, " is a "); at >= 0 {
+				line = line[:at] + " is a " + strings.ToLower(d.Platform) + " application"
+			}
+		case frontend != "" && strings.HasPrefix(trimmed, "frontend using "):
+			if strings.EqualFold(frontend, "None") {
+				continue
+			}
+			line = indent + "frontend using " + frontend
+		case d.Backend != "" && strings.HasPrefix(trimmed, "backend using "):
+			line = indent + "backend using " + d.Backend
+		case d.Database != "" && strings.HasPrefix(trimmed, "database using "):
+			line = indent + "database using " + d.Database
+		case d.Deploy != "" && strings.HasPrefix(trimmed, "deploy to "):
+			line = indent + "deploy to " + d.Deploy
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// defaultOption returns the option matching value, ignoring case, or
+// value itself if none does; fallback when value is empty.
+func defaultOption(options []string, value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	for _, opt := range options {
+		if strings.EqualFold(value, opt) {
+			return opt
+		}
+	}
+	return value
+}
+
 func fieldNames(fields []entityField) string {
 	names := make([]string, len(fields))
 	for i, f := range fields {
@@ -863,19 +959,9 @@ func summarizeContent(content string) string {
 
 // loadExampleTemplate reads an example template and replaces the app name.
 func loadExampleTemplate(exampleDir, newName string) (string, error) {
-	// Try relative to CWD (development).
-	path := filepath.Join("examples", exampleDir, "app.human")
-	data, err := os.ReadFile(path)
+	data, err := readExample(exampleDir)
 	if err != nil {
-		// Try relative to executable.
-		exe, _ := os.Executable()
-		if exe != "" {
-			path = filepath.Join(filepath.Dir(exe), "..", "examples", exampleDir, "app.human")
-			data, err = os.ReadFile(path)
-		}
-		if err != nil {
-			return "", err
-		}
+		return "", err
 	}
 
 	content := string(data)
@@ -890,6 +976,28 @@ func loadExampleTemplate(exampleDir, newName string) (string, error) {
 	return content, nil
 }
 
+// readExample reads examples/<exampleDir>/app.human.
+func readExample(exampleDir string) ([]byte, error) {
+	var err error
+	for _, dir := range examplesDirs() {
+		var data []byte
+		if data, err = os.ReadFile(filepath.Join(dir, exampleDir, "app.human")); err == nil {
+			return data, nil
+		}
+	}
+	return nil, err
+}
+
+// examplesDirs returns where the example apps may be: relative to the
+// working directory (development), then to the executable.
+func examplesDirs() []string {
+	dirs := []string{"examples"}
+	if exe, _ := os.Executable(); exe != "" {
+		dirs = append(dirs, filepath.Join(filepath.Dir(exe), "..", "examples"))
+	}
+	return dirs
+}
+
 func generateHumanMD(name string, appType AppType) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "# %s\n\n", name)
@@ -937,9 +1045,10 @@ func GenerateTemplate(name, platform, frontend, backend, database string) string
 }
 
 // PromptForPorts interactively prompts the user to configure service ports.
+// Without a terminal on stdin, or with --quiet, it uses the defaults.
 func PromptForPorts(in io.Reader, out io.Writer) ir.PortConfig {
 	file, ok := in.(*os.File)
-	if !ok || file.Fd() != 0 {
+	if !ok || file.Fd() != 0 || cli.LogLevel == cli.LevelQuiet {
 		return ir.PortConfig{
 			Frontend: 3000,
 			Backend:  3001,
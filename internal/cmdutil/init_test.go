@@ -0,0 +1,90 @@
+package cmdutil
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/config"
+)
+
+func TestInitProject_YesUsesDefaults(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(filepath.Join(xdg, "human"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	user := `{"app_type": "crud", "frontend": "vue", "design_system": "Tailwind", "backend": "Go", "deploy": "AWS"}`
+	if err := os.WriteFile(filepath.Join(xdg, "human", "defaults.json"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(".human", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(".human", "defaults.json"), []byte(`{"database": "SQLite"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing to read: --yes must not prompt.
+	path, err := InitProject("shop", false, true, strings.NewReader(""), io.Discard)
+	if err != nil {
+		t.Fatalf("InitProject: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"data Item:",
+		"  frontend using Vue with Tailwind\n",
+		"  backend using Go\n",
+		"  database using SQLite\n",
+		"  deploy to AWS\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("app.human missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestInitProject_PromptsDefaultToDefaults(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(".human", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(".human", "defaults.json"), []byte(`{"app_type": "empty", "backend": "Python"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Enter at every prompt, but choose the frontend.
+	var out strings.Builder
+	path, err := InitProject("notes", false, false, strings.NewReader("\nSvelte\n\n\n\n"), &out)
+	if err != nil {
+		t.Fatalf("InitProject: %v", err)
+	}
+	if !strings.Contains(out.String(), "Choice [7]: ") || !strings.Contains(out.String(), "Backend (Node/Python/Go) [Python]: ") {
+		t.Errorf("prompts don't offer the defaults:\n%s", out.String())
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "frontend using Svelte") || !strings.Contains(string(data), "backend using Python") {
+		t.Errorf("app.human:\n%s", data)
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	template := "app Shop is a web application\n\nbuild with:\n  frontend using React with TypeScript\n  backend using Node with Express\n  database using PostgreSQL\n  deploy to Docker\n"
+
+	got := applyDefaults(template, &config.Defaults{Platform: "Mobile", Frontend: "None", Deploy: "GCP"})
+	want := "app Shop is a mobile application\n\nbuild with:\n  backend using Node with Express\n  database using PostgreSQL\n  deploy to GCP\n"
+	if got != want {
+		t.Errorf("applyDefaults =\n%s\nwant\n%s", got, want)
+	}
+	if got := applyDefaults(template, &config.Defaults{}); got != template {
+		t.Errorf("empty defaults changed the template:\n%s", got)
+	}
+}
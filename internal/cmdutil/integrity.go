@@ -0,0 +1,269 @@
+package cmdutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/config"
+	"github.com/barun-bash/human/internal/version"
+)
+
+// ManifestFile is the integrity manifest each build writes into the output
+// directory. It is removed when a build starts and written when it
+// finishes, so a missing manifest means the last build didn't complete.
+const ManifestFile = ".human-manifest.json"
+
+// Manifest records the sha256 of every file a build generated, and of the
+// .human files it was built from.
+type Manifest struct {
+	Compiler string            `json:"compiler"`
+	Sources  map[string]string `json:"sources"`
+	Files    map[string]string `json:"files"` // slash-separated, relative to the output dir
+}
+
+// signatureFile returns the signature tool's output file for the manifest.
+func signatureFile(tool string) string {
+	if tool == "minisign" {
+		return ManifestFile + ".minisig"
+	}
+	return ManifestFile + ".sig"
+}
+
+// isManifestFile reports whether rel (relative to the output dir) is the
+// manifest or one of its signatures.
+func isManifestFile(rel string) bool {
+	return rel == ManifestFile || rel == signatureFile("cosign") || rel == signatureFile("minisign")
+}
+
+// removeManifest deletes the manifest and its signatures before a build.
+func removeManifest(outputDir string) {
+	for _, name := range []string{ManifestFile, signatureFile("cosign"), signatureFile("minisign")} {
+		os.Remove(filepath.Join(outputDir, name))
+	}
+}
+
+// generatedSince lists the files under outputDir written at or after
+// start. Generators rewrite every file on each build, so these are exactly
+// the build's output; older files are leftovers or runtime state such as
+// node_modules.
+func generatedSince(outputDir string, start time.Time) []string {
+	var files []string
+	filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(outputDir, path)
+		if err != nil || isManifestFile(rel) {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !info.ModTime().Before(start) {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files
+}
+
+// writeManifest hashes files (relative to outputDir) and sources, writes
+// the manifest, and signs it when the project config asks for it. Files
+// removed since they were generated are left out.
+func writeManifest(outputDir string, files, sources []string, signing *config.SigningConfig) (*Manifest, error) {
+	m := Manifest{
+		Compiler: version.Info(),
+		Sources:  map[string]string{},
+		Files:    map[string]string{},
+	}
+	for _, src := range sources {
+		sum, err := fileSHA256(src)
+		if err != nil {
+			return nil, fmt.Errorf("hashing %s: %w", src, err)
+		}
+		m.Sources[filepath.ToSlash(src)] = sum
+	}
+	for _, rel := range files {
+		sum, err := fileSHA256(filepath.Join(outputDir, filepath.FromSlash(rel)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("hashing %s: %w", rel, err)
+		}
+		m.Files[rel] = sum
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, ManifestFile), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", ManifestFile, err)
+	}
+	if signing != nil && signing.Key != "" {
+		if err := signManifest(outputDir, signing); err != nil {
+			return nil, fmt.Errorf("signing %s: %w", ManifestFile, err)
+		}
+	}
+	return &m, nil
+}
+
+// readManifest reads the manifest the last build wrote into outputDir.
+func readManifest(outputDir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(outputDir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ManifestFile, err)
+	}
+	return &m, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// signManifest signs the manifest with cosign or minisign. Stdin stays
+// connected so minisign can prompt for the key password.
+func signManifest(outputDir string, signing *config.SigningConfig) error {
+	key, err := filepath.Abs(signing.Key)
+	if err != nil {
+		return err
+	}
+	var args []string
+	switch signing.Tool {
+	case "cosign":
+		args = []string{"sign-blob", "--yes", "--key", key, "--output-signature", signatureFile("cosign"), ManifestFile}
+	case "minisign":
+		args = []string{"-S", "-s", key, "-m", ManifestFile}
+	default:
+		return fmt.Errorf("unknown signing tool %q (use cosign or minisign)", signing.Tool)
+	}
+	if _, err := exec.LookPath(signing.Tool); err != nil {
+		return fmt.Errorf("%s not found in PATH", signing.Tool)
+	}
+	cmd := exec.Command(signing.Tool, args...)
+	cmd.Dir = outputDir
+	cmd.Env = envFor(signing.Tool)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// IntegrityReport compares the output directory with its manifest.
+type IntegrityReport struct {
+	Files    int      // files listed in the manifest
+	Modified []string // changed since the build
+	Missing  []string // deleted since the build
+	Signed   bool     // a signature was found and verified
+	SigError error    // the signature is missing, invalid, or couldn't be checked
+}
+
+// OK reports whether the output is exactly what the build produced.
+func (r *IntegrityReport) OK() bool {
+	return len(r.Modified) == 0 && len(r.Missing) == 0 && r.SigError == nil
+}
+
+// VerifyIntegrity checks the files in outputDir against the manifest the
+// last build wrote, and the manifest against its signature when signing
+// has a public key. Files that aren't in the manifest, such as
+// node_modules, aren't checked.
+func VerifyIntegrity(outputDir string, signing *config.SigningConfig) (*IntegrityReport, error) {
+	m, err := readManifest(outputDir)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no %s in %s — the last build didn't finish. Run 'human build' again", ManifestFile, outputDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &IntegrityReport{Files: len(m.Files)}
+	for rel, want := range m.Files {
+		sum, err := fileSHA256(filepath.Join(outputDir, filepath.FromSlash(rel)))
+		switch {
+		case os.IsNotExist(err):
+			report.Missing = append(report.Missing, rel)
+		case err != nil:
+			return nil, fmt.Errorf("hashing %s: %w", rel, err)
+		case sum != want:
+			report.Modified = append(report.Modified, rel)
+		}
+	}
+	sort.Strings(report.Modified)
+	sort.Strings(report.Missing)
+
+	if signing != nil && signing.PublicKey != "" {
+		report.SigError = verifySignature(outputDir, signing)
+		report.Signed = report.SigError == nil
+	}
+	return report, nil
+}
+
+// verifySignature checks the manifest's signature with signing's public key.
+func verifySignature(outputDir string, signing *config.SigningConfig) error {
+	sig := signatureFile(signing.Tool)
+	if _, err := os.Stat(filepath.Join(outputDir, sig)); err != nil {
+		return fmt.Errorf("the manifest is not signed (no %s)", sig)
+	}
+	pub, err := filepath.Abs(signing.PublicKey)
+	if err != nil {
+		return err
+	}
+	var args []string
+	switch signing.Tool {
+	case "cosign":
+		args = []string{"verify-blob", "--key", pub, "--signature", sig, ManifestFile}
+	case "minisign":
+		args = []string{"-V", "-p", pub, "-m", ManifestFile}
+	default:
+		return fmt.Errorf("unknown signing tool %q (use cosign or minisign)", signing.Tool)
+	}
+	if _, err := exec.LookPath(signing.Tool); err != nil {
+		return fmt.Errorf("%s not found in PATH", signing.Tool)
+	}
+	if _, err := captureCommand(outputDir, signing.Tool, args...); err != nil {
+		return fmt.Errorf("signature check failed: %w", err)
+	}
+	return nil
+}
+
+// PrintIntegrityReport prints what changed in the output since the build.
+func PrintIntegrityReport(r *IntegrityReport) {
+	for _, f := range r.Modified {
+		fmt.Printf("  modified  %s\n", f)
+	}
+	for _, f := range r.Missing {
+		fmt.Printf("  missing   %s\n", f)
+	}
+	if r.SigError != nil {
+		cli.Println(cli.Error("Signature: " + r.SigError.Error()))
+	} else if r.Signed {
+		cli.Println(cli.Success("Signature verified."))
+	}
+	if len(r.Modified) > 0 || len(r.Missing) > 0 {
+		cli.Println(cli.Error(fmt.Sprintf("%d of %d generated file%s changed since the build.",
+			len(r.Modified)+len(r.Missing), r.Files, Plural(r.Files))))
+		return
+	}
+	cli.Println(cli.Success(fmt.Sprintf("All %d generated file%s match the build manifest.", r.Files, Plural(r.Files))))
+}
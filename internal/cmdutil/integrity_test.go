@@ -0,0 +1,124 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barun-bash/human/internal/config"
+)
+
+func writeOutputFile(t *testing.T, dir, rel, content string, mod time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIntegrityManifest(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Truncate(time.Second)
+	writeOutputFile(t, dir, "node/src/server.ts", "server", start)
+	writeOutputFile(t, dir, "react/src/App.tsx", "app", start.Add(time.Second))
+	writeOutputFile(t, dir, "node/node_modules/x/index.js", "old", start.Add(-time.Hour))
+	writeOutputFile(t, dir, ManifestFile, "{}", start)
+
+	files := generatedSince(dir, start)
+	if want := []string{"node/src/server.ts", "react/src/App.tsx"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("generatedSince = %v, want %v", files, want)
+	}
+
+	src := filepath.Join(dir, "app.human")
+	os.WriteFile(src, []byte("app Clinic is a web application\n"), 0644)
+	if _, err := writeManifest(dir, files, []string{src}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := VerifyIntegrity(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() || report.Files != 2 {
+		t.Errorf("fresh build: %+v, want OK with 2 files", report)
+	}
+
+	os.WriteFile(filepath.Join(dir, "node", "src", "server.ts"), []byte("tampered"), 0644)
+	os.Remove(filepath.Join(dir, "react", "src", "App.tsx"))
+	writeOutputFile(t, dir, "node/node_modules/x/index.js", "updated", time.Now())
+	report, err = VerifyIntegrity(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.OK() {
+		t.Error("tampered output reported OK")
+	}
+	if want := []string{"node/src/server.ts"}; !reflect.DeepEqual(report.Modified, want) {
+		t.Errorf("Modified = %v, want %v", report.Modified, want)
+	}
+	if want := []string{"react/src/App.tsx"}; !reflect.DeepEqual(report.Missing, want) {
+		t.Errorf("Missing = %v, want %v", report.Missing, want)
+	}
+}
+
+func TestVerifyIntegrityPartialBuild(t *testing.T) {
+	dir := t.TempDir()
+	writeOutputFile(t, dir, "node/src/server.ts", "server", time.Now())
+	writeOutputFile(t, dir, ManifestFile, "{}", time.Now())
+	removeManifest(dir)
+
+	_, err := VerifyIntegrity(dir, nil)
+	if err == nil || !strings.Contains(err.Error(), "didn't finish") {
+		t.Errorf("err = %v, want a message about an unfinished build", err)
+	}
+}
+
+func TestIntegrityManifestSigned(t *testing.T) {
+	// The stub signs by copying the manifest and verifies by comparing.
+	stubCommand(t, "minisign", `case "$1" in
+  -S) cp .human-manifest.json .human-manifest.json.minisig ;;
+  -V) cmp -s .human-manifest.json .human-manifest.json.minisig || { echo 'Signature verification failed' >&2; exit 1; } ;;
+esac
+`)
+	t.Setenv("PATH", os.Getenv("PATH")+":/bin:/usr/bin")
+	dir := t.TempDir()
+	writeOutputFile(t, dir, "node/src/server.ts", "server", time.Now())
+	signing := &config.SigningConfig{Tool: "minisign", Key: "human.key", PublicKey: "human.pub"}
+	if _, err := writeManifest(dir, []string{"node/src/server.ts"}, nil, signing); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := VerifyIntegrity(dir, signing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() || !report.Signed {
+		t.Errorf("signed build: %+v, want OK and signed", report)
+	}
+
+	manifest := filepath.Join(dir, ManifestFile)
+	data, _ := os.ReadFile(manifest)
+	os.WriteFile(manifest, []byte(strings.Replace(string(data), `"files"`, `"files" `, 1)), 0644)
+	report, err = VerifyIntegrity(dir, signing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.OK() || report.SigError == nil {
+		t.Error("edited manifest passed the signature check")
+	}
+
+	os.Remove(filepath.Join(dir, signatureFile("minisign")))
+	report, _ = VerifyIntegrity(dir, signing)
+	if report.SigError == nil || !strings.Contains(report.SigError.Error(), "not signed") {
+		t.Errorf("SigError = %v, want not signed", report.SigError)
+	}
+}
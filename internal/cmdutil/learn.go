@@ -0,0 +1,525 @@
+package cmdutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/barun-bash/human/internal/analyzer"
+	"github.com/barun-bash/human/internal/build"
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/parser"
+	"github.com/barun-bash/human/internal/syntax"
+)
+
+// lesson is one step of the `human learn` tutorial: what to write, the
+// patterns and example that show how, and the generated file that shows
+// what it became.
+type lesson struct {
+	title    string
+	explain  string
+	patterns []string // templates from the syntax registry
+	example  string   // the snippet used when the learner presses Enter
+	real     string   // the block of examples/taskflow shown alongside, e.g. "data Task:"
+	noun     string   // what the step adds, e.g. "data model"
+
+	// count returns how many of the step's declarations an app has; a
+	// step is done when the learner's code adds one.
+	count func(app *ir.Application) int
+
+	// preview returns the generator and file (relative to its output
+	// directory) that show the step's result, and the line to show it
+	// from, or "" for the step without one.
+	preview func(app *ir.Application) (gen, file, from string)
+}
+
+// learnBuild is the build block previews use until the learner writes one.
+const learnBuild = `build with:
+  frontend using React with TypeScript
+  backend using Node with Express
+  database using PostgreSQL
+`
+
+var lessons = []lesson{
+	{
+		title: "Declare the app",
+		explain: "Every .human file starts by naming the app and the platform it runs on.\n" +
+			"The rest of the file describes it in plain English, one block at a time.",
+		patterns: []string{"app <Name> is a <platform> application"},
+		example:  "app Notes is a web application",
+		noun:     "app declaration",
+		count: func(app *ir.Application) int {
+			if app.Name != "" {
+				return 1
+			}
+			return 0
+		},
+	},
+	{
+		title: "Describe your data",
+		explain: "A data block is a model: a table in the database, a type in the frontend,\n" +
+			"and the fields the API reads and writes.",
+		patterns: []string{
+			"data <Name>:",
+			"has a <field> which is <type>",
+			"has an optional <field> which is <type>",
+			"has a <field> which is either <value> or <value>",
+		},
+		example: "data Note:\n  has a title which is text\n  has an optional body which is text\n  has a created datetime",
+		real:    "data Task:",
+		noun:    "data model",
+		count:   func(app *ir.Application) int { return len(app.Data) },
+		preview: func(app *ir.Application) (string, string, string) {
+			return "node", filepath.Join("prisma", "schema.prisma"), "model " + app.Data[len(app.Data)-1].Name + " {"
+		},
+	},
+	{
+		title: "Add a page",
+		explain: "A page block lists what the page shows and how it responds, one line each.\n" +
+			"Pages name the data they show, so the compiler knows what to fetch.",
+		patterns: []string{
+			"page <Name>:",
+			"show a list of <data>",
+			"show <what>",
+		},
+		example: "page Home:\n  show a list of notes\n  each note shows its title and created date",
+		real:    "page Profile:",
+		noun:    "page",
+		count:   func(app *ir.Application) int { return len(app.Pages) },
+		preview: func(app *ir.Application) (string, string, string) {
+			return "react", filepath.Join("src", "pages", app.Pages[len(app.Pages)-1].Name+"Page.tsx"), ""
+		},
+	},
+	{
+		title: "Add an API",
+		explain: "An api block is an endpoint: what it accepts, what it checks, what it does,\n" +
+			"and what it responds with.",
+		patterns: []string{
+			"api <Name>:",
+			"accepts <fields>",
+			"check that <validation>",
+			"create a <Data> with <fields>",
+		},
+		example: "api CreateNote:\n  accepts title and body\n  check that title is not empty\n  create a Note with the given fields\n  respond with the created note",
+		real:    "api SignUp:",
+		noun:    "API",
+		count:   func(app *ir.Application) int { return len(app.APIs) },
+		preview: func(app *ir.Application) (string, string, string) {
+			return "node", filepath.Join("src", "routes", learnKebab(app.APIs[len(app.APIs)-1].Name)+".ts"), "router."
+		},
+	},
+	{
+		title: "Choose what to build",
+		explain: "The build block picks the stack. The same file compiles to React or Vue,\n" +
+			"Node or Go, PostgreSQL or SQLite — change a line and rebuild.",
+		patterns: []string{
+			"build with:",
+			"frontend using <framework> with <language>",
+			"backend using <language> with <framework>",
+			"database using <database>",
+		},
+		example: strings.TrimSuffix(learnBuild, "\n"),
+		real:    "build with:",
+		noun:    "build block",
+		count: func(app *ir.Application) int {
+			if app.Config != nil && (app.Config.Frontend != "" || app.Config.Backend != "") {
+				return 1
+			}
+			return 0
+		},
+	},
+}
+
+// RunLearn runs the interactive tutorial: it walks the learner through
+// writing a .human file — the app, data, pages, APIs, and build — checking
+// each step with the parser and analyzer and showing the code it
+// generates, then saves the file to path. Quitting early saves the steps
+// done so far.
+func RunLearn(path string, in io.Reader, out io.Writer) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists — pass another file, e.g. 'human learn notes.human'", path)
+	}
+
+	scanner := bufio.NewScanner(in)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, cli.Heading("Learn Human"))
+	fmt.Fprintf(out, "You'll write a small app in %d steps, and see the code each one generates.\n", len(lessons))
+	fmt.Fprintln(out, cli.Muted("Type Human code and finish with an empty line. Press Enter on its own to use the example, or type 'quit' to stop."))
+
+	var source string
+	for i, l := range lessons {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, cli.Heading(fmt.Sprintf("Step %d/%d: %s", i+1, len(lessons), l.title)))
+		fmt.Fprintln(out, l.explain)
+		printLessonPatterns(out, l)
+
+		next, app, ok := runLesson(l, source, scanner, out)
+		if !ok {
+			return saveLearnFile(path, source, i, out)
+		}
+		source = next
+		fmt.Fprintln(out, cli.Success(fmt.Sprintf("Valid — your file now has %s.", learnSummary(app))))
+		printLessonPreview(out, l, source)
+	}
+
+	if err := saveLearnFile(path, source, len(lessons), out); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Next:")
+	fmt.Fprintf(out, "  %-24s %s\n", "human build "+path, "generate the whole app")
+	fmt.Fprintf(out, "  %-24s %s\n", "human explain <topic>", "learn more syntax, e.g. 'human explain workflows'")
+	if names := exampleNames(); len(names) > 0 {
+		fmt.Fprintf(out, "  %-24s %s\n", "examples/", "complete apps to read: "+strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// runLesson reads the learner's code for a lesson until it's valid and
+// adds what the lesson asks for, returning the source with it appended
+// and the app it compiles to. It returns false when the learner quits or
+// the input ends.
+func runLesson(l lesson, source string, scanner *bufio.Scanner, out io.Writer) (string, *ir.Application, bool) {
+	before := 0
+	if source != "" {
+		if app, _ := compileLearnSource(source); app != nil {
+			before = l.count(app)
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Try it — for example:")
+	fmt.Fprintln(out, indent(l.example, "  "))
+	for {
+		fmt.Fprintln(out)
+		snippet, ok := readSnippet(scanner, out)
+		if !ok {
+			return "", nil, false
+		}
+		if snippet == "" {
+			snippet = l.example
+			fmt.Fprintln(out, cli.Muted("Using the example."))
+		}
+
+		next := snippet
+		if source != "" {
+			next = source + "\n" + snippet
+		}
+		app, diags := compileLearnSource(next)
+		if len(diags) > 0 {
+			fmt.Fprintln(out, cli.Error("Not quite:"))
+			for _, d := range diags {
+				fmt.Fprintf(out, "  %s\n", d)
+			}
+			fmt.Fprintln(out, cli.Muted("Try again, or press Enter to use the example."))
+			continue
+		}
+		if l.count(app) <= before {
+			fmt.Fprintf(out, "That's valid Human, but it doesn't add a %s yet. Try again, or press Enter to use the example.\n", l.noun)
+			continue
+		}
+		return next + "\n", app, true
+	}
+}
+
+// readSnippet reads lines until an empty one, returning them joined, or
+// false when the learner types quit or the input ends.
+func readSnippet(scanner *bufio.Scanner, out io.Writer) (string, bool) {
+	var lines []string
+	fmt.Fprint(out, "> ")
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if len(lines) == 0 {
+			switch strings.ToLower(strings.TrimSpace(line)) {
+			case "quit", "exit", "q":
+				return "", false
+			}
+		}
+		if strings.TrimSpace(line) == "" {
+			return strings.Join(lines, "\n"), true
+		}
+		lines = append(lines, line)
+		fmt.Fprint(out, "  ")
+	}
+	if len(lines) > 0 {
+		return strings.Join(lines, "\n"), true
+	}
+	return "", false
+}
+
+// compileLearnSource parses, builds, and analyzes source, returning the
+// app, or the syntax and semantic errors it has.
+func compileLearnSource(source string) (*ir.Application, []string) {
+	prog, err := parser.Parse(source)
+	if syntaxErrs, ok := err.(*parser.Errors); ok {
+		var diags []string
+		for _, d := range syntaxErrs.Diagnostics {
+			diags = append(diags, formatLearnDiagnostic(d.Format(), d.Suggestion))
+		}
+		return nil, diags
+	}
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	errs := analyzer.Analyze(app, "")
+	if errs.HasErrors() {
+		var diags []string
+		for _, e := range errs.Errors() {
+			diags = append(diags, formatLearnDiagnostic(e.Format(), e.Suggestion))
+		}
+		return nil, diags
+	}
+	return app, nil
+}
+
+func formatLearnDiagnostic(msg, suggestion string) string {
+	if suggestion != "" {
+		return msg + "\n    suggestion: " + suggestion
+	}
+	return msg
+}
+
+// printLessonPatterns prints the lesson's patterns from the syntax
+// registry, and the matching block of a complete example app.
+func printLessonPatterns(out io.Writer, l lesson) {
+	byTemplate := map[string]syntax.Pattern{}
+	for _, p := range syntax.AllPatterns() {
+		byTemplate[p.Template] = p
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Patterns:")
+	for _, t := range l.patterns {
+		if p, ok := byTemplate[t]; ok {
+			fmt.Fprintf(out, "  %-46s %s\n", highlightPlaceholders(p.Template), cli.Muted(p.Description))
+		}
+	}
+
+	if l.real == "" {
+		return
+	}
+	if block := exampleBlock("taskflow", l.real, 8); block != "" {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, cli.Muted("From examples/taskflow/app.human:"))
+		fmt.Fprintln(out, indent(block, "  "))
+	}
+}
+
+// printLessonPreview generates the lesson's file from source and prints
+// its first lines. Until the learner writes a build block, the preview
+// uses the default stack.
+func printLessonPreview(out io.Writer, l lesson, source string) {
+	if l.preview == nil {
+		if l.noun == "build block" {
+			printLearnPlan(out, source)
+		}
+		return
+	}
+	if !strings.Contains(source, "build with:") {
+		source += "\n" + learnBuild
+	}
+	// The source was checked already; the default build block may not
+	// pass analysis yet (a frontend with no pages), but generates fine.
+	prog, err := parser.Parse(source)
+	if err != nil {
+		return
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		return
+	}
+	gen, file, from := l.preview(app)
+	snippet, err := learnPreview(app, gen, file, from, 20)
+	if err != nil || snippet == "" {
+		return
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, cli.Muted(fmt.Sprintf("Generated %s/%s:", gen, filepath.ToSlash(file))))
+	fmt.Fprintln(out, indent(snippet, "  "))
+}
+
+// printLearnPlan prints the generators `human build` would run for the
+// finished file.
+func printLearnPlan(out io.Writer, source string) {
+	app, diags := compileLearnSource(source)
+	if len(diags) > 0 {
+		return
+	}
+	stages := build.PlanStages(app)
+	if len(stages) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%s\n", cli.Muted(fmt.Sprintf("'human build' will run %d stages:", len(stages))))
+	for _, s := range stages {
+		fmt.Fprintf(out, "  • %s\n", s)
+	}
+}
+
+// learnPreview runs generator gen for app in a temporary directory and
+// returns up to max lines of file, starting at the first line with the
+// prefix from, or after the generated-file header when from is empty.
+func learnPreview(app *ir.Application, gen, file, from string, max int) (string, error) {
+	g := build.DefaultRegistry().Get(gen)
+	if g == nil {
+		return "", fmt.Errorf("unknown generator %q", gen)
+	}
+	dir, err := os.MkdirTemp("", "human-learn-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := g.Generate(app, dir); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	start := 0
+	for i, line := range lines {
+		if from != "" && strings.HasPrefix(strings.TrimSpace(line), from) {
+			start = i
+			break
+		}
+		if from == "" && strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "//") {
+			start = i
+			break
+		}
+	}
+	lines = lines[start:]
+	if len(lines) > max {
+		lines = append(lines[:max], "…")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// saveLearnFile writes the tutorial's source to path, after the given
+// number of completed steps.
+func saveLearnFile(path, source string, done int, out io.Writer) error {
+	fmt.Fprintln(out)
+	if source == "" {
+		fmt.Fprintln(out, "Stopped before the first step — nothing to save. Run 'human learn' to start again.")
+		return nil
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("could not create directory %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	if done < len(lessons) {
+		fmt.Fprintln(out, cli.Success(fmt.Sprintf("Saved %d of %d steps to %s", done, len(lessons), path)))
+	} else {
+		fmt.Fprintln(out, cli.Success(fmt.Sprintf("Done! Saved your app to %s", path)))
+	}
+	return nil
+}
+
+// learnSummary describes what a tutorial app declares so far, e.g.
+// "1 data model, 1 page".
+func learnSummary(app *ir.Application) string {
+	var parts []string
+	for _, c := range []struct {
+		n    int
+		noun string
+	}{
+		{len(app.Data), "data model"},
+		{len(app.Pages), "page"},
+		{len(app.APIs), "API"},
+	} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s%s", c.n, c.noun, Plural(c.n)))
+		}
+	}
+	if len(parts) == 0 {
+		return "the app " + app.Name
+	}
+	return strings.Join(parts, ", ")
+}
+
+// exampleBlock returns up to max lines of the block in an example app
+// starting at the line header, or "" when there's no such example or
+// block.
+func exampleBlock(exampleDir, header string, max int) string {
+	data, err := readExample(exampleDir)
+	if err != nil {
+		return ""
+	}
+	var block []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if len(block) == 0 {
+			if strings.TrimSpace(line) == header {
+				block = append(block, line)
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == "" || !strings.HasPrefix(line, " ") {
+			break
+		}
+		block = append(block, line)
+	}
+	if len(block) > max {
+		block = append(block[:max], "  …")
+	}
+	return strings.Join(block, "\n")
+}
+
+// exampleNames returns the example apps found next to the working
+// directory or the executable.
+func exampleNames() []string {
+	for _, dir := range examplesDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		var names []string
+		for _, e := range entries {
+			if _, err := os.Stat(filepath.Join(dir, e.Name(), "app.human")); e.IsDir() && err == nil {
+				names = append(names, e.Name())
+			}
+		}
+		if len(names) > 0 {
+			return names
+		}
+	}
+	return nil
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// learnKebab converts an API name to its route file's name, as the Node
+// generator does: "CreateNote" → "create-note".
+func learnKebab(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,145 @@
+package cmdutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/syntax"
+)
+
+func TestRunLearnWithExamples(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.human")
+	var out bytes.Buffer
+	if err := RunLearn(path, strings.NewReader(strings.Repeat("\n", len(lessons))), &out); err != nil {
+		t.Fatalf("RunLearn: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	for _, l := range lessons {
+		if !strings.Contains(string(data), l.example) {
+			t.Errorf("saved file missing the %q example:\n%s", l.title, data)
+		}
+	}
+	if app, diags := compileLearnSource(string(data)); app == nil {
+		t.Errorf("saved file should compile: %v", diags)
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"Step 1/5: Declare the app",
+		"Step 5/5: Choose what to build",
+		"Generated node/prisma/schema.prisma:",
+		"model Note {",
+		"Generated react/src/pages/HomePage.tsx:",
+		"Generated node/src/routes/create-note.ts:",
+		"router.post(",
+		"'human build' will run",
+		"Done! Saved your app to " + path,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestRunLearnRetriesInvalidCode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.human")
+	input := strings.Join([]string{
+		"", // app: use the example
+		"data:",
+		"",
+		"page Home:",
+		"  show a greeting",
+		"",
+		"data Recipe:",
+		"  has a name which is text",
+		"",
+		"quit",
+	}, "\n")
+	var out bytes.Buffer
+	if err := RunLearn(path, strings.NewReader(input), &out); err != nil {
+		t.Fatalf("RunLearn: %v", err)
+	}
+
+	output := out.String()
+	if !strings.Contains(output, "Not quite:") {
+		t.Errorf("a syntax error should be reported:\n%s", output)
+	}
+	if !strings.Contains(output, "doesn't add a data model yet") {
+		t.Errorf("code without the step's declaration should be refused:\n%s", output)
+	}
+	if !strings.Contains(output, "Saved 2 of 5 steps") {
+		t.Errorf("quitting should save the steps done:\n%s", output)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "data Recipe:") || strings.Contains(string(data), "page Home:") {
+		t.Errorf("only accepted code should be saved:\n%s", data)
+	}
+}
+
+func TestRunLearnQuitBeforeFirstStep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.human")
+	var out bytes.Buffer
+	if err := RunLearn(path, strings.NewReader("quit\n"), &out); err != nil {
+		t.Fatalf("RunLearn: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("nothing should be saved before the first step")
+	}
+}
+
+func TestRunLearnRefusesExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.human")
+	os.WriteFile(path, []byte("app Mine is a web application\n"), 0644)
+	if err := RunLearn(path, strings.NewReader("\n"), &bytes.Buffer{}); err == nil {
+		t.Fatal("expected an error for an existing file")
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "app Mine is a web application\n" {
+		t.Errorf("existing file was changed:\n%s", data)
+	}
+}
+
+func TestLessonPatternsExist(t *testing.T) {
+	templates := map[string]bool{}
+	for _, p := range syntax.AllPatterns() {
+		templates[p.Template] = true
+	}
+	for _, l := range lessons {
+		for _, tmpl := range l.patterns {
+			if !templates[tmpl] {
+				t.Errorf("lesson %q shows %q, which isn't in the syntax registry", l.title, tmpl)
+			}
+		}
+	}
+}
+
+func TestExampleBlock(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "examples", "demo"), 0755)
+	os.WriteFile(filepath.Join(dir, "examples", "demo", "app.human"), []byte(
+		"app Demo is a web application\n\ndata Task:\n  has a title which is text\n  has a due date\n\ndata Tag:\n  has a name which is text\n"), 0644)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	if got, want := exampleBlock("demo", "data Task:", 8), "data Task:\n  has a title which is text\n  has a due date"; got != want {
+		t.Errorf("exampleBlock = %q, want %q", got, want)
+	}
+	if got := exampleBlock("demo", "data Task:", 1); got != "data Task:\n  …" {
+		t.Errorf("exampleBlock should truncate, got %q", got)
+	}
+	if got := exampleBlock("demo", "api Missing:", 8); got != "" {
+		t.Errorf("a missing block should be empty, got %q", got)
+	}
+	if names := exampleNames(); len(names) != 1 || names[0] != "demo" {
+		t.Errorf("exampleNames = %v", names)
+	}
+}
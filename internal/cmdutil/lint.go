@@ -0,0 +1,73 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/lint"
+)
+
+// Lint parses and analyzes a .human file or project directory and runs the
+// lint rules its .humanlint.yaml enables. The config is looked up from the
+// project's directory upward.
+func Lint(path string) ([]lint.Finding, *lint.Config, error) {
+	dir := path
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+	cfg, err := lint.LoadConfig(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := ParseAndAnalyze(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if PrintDiagnostics(result.Errs) {
+		return nil, nil, fmt.Errorf("%d error(s) found — fix them with human check first", len(result.Errs.Errors()))
+	}
+	return lint.Run(result.App, cfg), cfg, nil
+}
+
+// PrintLint writes the findings, one per line with its rule, and a count
+// by severity.
+func PrintLint(out io.Writer, findings []lint.Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(out, cli.Success("No lint findings."))
+		return
+	}
+	counts := map[lint.Severity]int{}
+	for _, f := range findings {
+		counts[f.Severity]++
+		line := fmt.Sprintf("  %-7s %s [%s]", f.Severity, f.Message, f.Rule)
+		switch f.Severity {
+		case lint.SeverityError:
+			fmt.Fprintln(out, cli.Error(line))
+		case lint.SeverityWarning:
+			fmt.Fprintln(out, cli.Warn(line))
+		default:
+			fmt.Fprintln(out, cli.Info(line))
+		}
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%d error%s, %d warning%s, %d info\n",
+		counts[lint.SeverityError], Plural(counts[lint.SeverityError]),
+		counts[lint.SeverityWarning], Plural(counts[lint.SeverityWarning]),
+		counts[lint.SeverityInfo])
+}
+
+// PrintLintRules lists every rule with the severity cfg gives it.
+func PrintLintRules(out io.Writer, cfg *lint.Config) {
+	for _, rule := range lint.Rules() {
+		fmt.Fprintf(out, "  %-24s %-7s %s\n", rule.ID, cfg.SeverityOf(rule), rule.Description)
+	}
+	if cfg.Path != "" {
+		fmt.Fprintf(out, "\nSeverities from %s\n", cfg.Path)
+	} else {
+		fmt.Fprintf(out, "\nDefault severities — set your own in %s\n", lint.ConfigFile)
+	}
+}
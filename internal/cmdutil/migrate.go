@@ -0,0 +1,86 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/migrate"
+)
+
+// migrateScripts are the generated migrations, by backend directory. A
+// Node backend on a database Prisma Migrate doesn't take has its schema
+// pushed instead.
+var migrateScripts = []backendScript{
+	{"node", "prisma/migrations/migration_lock.toml", [][]string{{"npx", "prisma", "migrate", "deploy"}}},
+	{"node", "prisma/schema.prisma", [][]string{{"npx", "prisma", "db", "push"}}},
+	{"python", "alembic.ini", [][]string{{"alembic", "upgrade", "head"}, {"python3", "-m", "alembic", "upgrade", "head"}}},
+	{"go", "cmd/migrate/main.go", [][]string{{"go", "run", "./cmd/migrate"}}},
+}
+
+// migrateEnv is what migrations keep in a scrubbed environment: the
+// database URLs.
+var migrateEnv = []string{"DATABASE_URL", "DIRECT_DATABASE_URL"}
+
+// MigrateDatabase prints the project's migration history and applies the
+// migrations the database hasn't had, with the backend's own tool; with
+// dryRun, it only prints what it would do. DATABASE_URL comes from the
+// environment or, failing that, the build's .env.
+func MigrateDatabase(outputDir string, dryRun bool, out io.Writer) error {
+	history, err := migrate.Load(migrate.Dir)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("no migrations recorded. 'human build' records them for apps with data models")
+	}
+	dir, command, err := scriptCommand(outputDir, migrateScripts, !dryRun)
+	if err != nil {
+		return err
+	}
+	if command == nil {
+		return fmt.Errorf("the build has no migrations. Migrations are generated for Node, Python, and Go backends")
+	}
+
+	writeMigrationPlan(out, history)
+	if dryRun {
+		fmt.Fprintf(out, "\nDry run: would run '%s' in %s\n", strings.Join(command, " "), dir)
+		return nil
+	}
+	fmt.Fprintf(out, "\nRunning '%s' in %s\n", strings.Join(command, " "), dir)
+	return runBackendCommand(outputDir, dir, command, migrateEnv)
+}
+
+// writeMigrationPlan writes each migration of a history with its changes
+// and what they risk for existing data.
+func writeMigrationPlan(out io.Writer, history []*ir.Migration) {
+	fmt.Fprintf(out, "Migrations in %s (the database skips those it has had):\n", filepath.ToSlash(migrate.Dir))
+	for _, m := range history {
+		fmt.Fprintf(out, "\n  %s\n", m.Name)
+		for _, c := range m.Changes {
+			fmt.Fprintf(out, "    %s\n", migrate.Describe(c))
+			if w := migrate.Warning(c); w != "" {
+				fmt.Fprintf(out, "    %s\n", cli.Warn("warning: "+w))
+			}
+		}
+	}
+}
+
+// PrintMigration reports a migration the build recorded, warning of
+// changes that risk existing data. The first migration, which creates
+// the schema, is named only.
+func PrintMigration(m *ir.Migration) {
+	cli.Printf("Recorded migration %s\n", filepath.ToSlash(filepath.Join(migrate.Dir, m.Name+".json")))
+	if strings.HasSuffix(m.Name, "_initial") {
+		return
+	}
+	for _, c := range m.Changes {
+		cli.Printf("  %s\n", migrate.Describe(c))
+		if w := migrate.Warning(c); w != "" {
+			cli.Printf("    %s\n", cli.Warn("warning: "+w))
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package cmdutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/migrate"
+)
+
+func TestMigrateDatabaseDryRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+	out := filepath.Join(".human", "output")
+	var buf bytes.Buffer
+	if err := MigrateDatabase(out, true, &buf); err == nil || !strings.Contains(err.Error(), "no migrations recorded") {
+		t.Errorf("expected a no-history error, got %v", err)
+	}
+
+	app := &ir.Application{Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{{Name: "title", Type: "text"}}}}}
+	if _, err := migrate.Record(migrate.Dir, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Data[0].Fields = nil
+	if _, err := migrate.Record(migrate.Dir, app); err != nil {
+		t.Fatal(err)
+	}
+	if err := MigrateDatabase(out, true, &buf); err == nil || !strings.Contains(err.Error(), "no migrations.") {
+		t.Errorf("expected a no-migrations error, got %v", err)
+	}
+
+	os.MkdirAll(filepath.Join(out, "python"), 0755)
+	os.WriteFile(filepath.Join(out, "python", "alembic.ini"), []byte("[alembic]\n"), 0644)
+	buf.Reset()
+	if err := MigrateDatabase(out, true, &buf); err != nil {
+		t.Fatalf("MigrateDatabase: %v", err)
+	}
+	for _, want := range []string{
+		"  0001_initial\n    Create Task\n",
+		"  0002_drop_task_title\n    Drop Task.title\n",
+		"warning: drops Task.title and its values",
+		"Dry run: would run 'alembic upgrade head' in " + filepath.Join(out, "python"),
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("plan missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cmdutil
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/barun-bash/human/internal/build"
+)
+
+// OutputChange is what a build did to one generator's output, compared
+// with the previous build's manifest.
+type OutputChange struct {
+	Bytes   int64 // size of the files written
+	Added   int
+	Changed int
+	Removed int // generated last build but not this one
+}
+
+// OutputDiff compares a build's output with the previous build.
+type OutputDiff struct {
+	Generators map[string]*OutputChange
+	Total      OutputChange
+	Previous   bool // there was a previous manifest to compare with
+}
+
+// outputOwners maps each generated file (slash-separated, relative to the
+// output dir) to the generator that wrote it. Generators can write anywhere
+// in the output (the quality engine writes tests into the backend, for
+// example); a file written twice belongs to the later one.
+func outputOwners(results []build.Result) map[string]string {
+	owners := map[string]string{}
+	for _, r := range results {
+		for _, p := range r.Paths {
+			owners[filepath.ToSlash(p)] = r.Name
+		}
+	}
+	return owners
+}
+
+// diffOutput compares the manifest this build wrote with the previous one
+// (nil after a first or interrupted build). Removed files are credited to
+// the generator that wrote most of their directory, or the nearest parent
+// directory, this build; files in a top-level directory nothing wrote to
+// (the old backend's, after switching backends) count only in the total.
+func diffOutput(outputDir string, prev, cur *Manifest, owners map[string]string) *OutputDiff {
+	d := &OutputDiff{Generators: map[string]*OutputChange{}, Previous: prev != nil}
+	change := func(gen string) *OutputChange {
+		if d.Generators[gen] == nil {
+			d.Generators[gen] = &OutputChange{}
+		}
+		return d.Generators[gen]
+	}
+
+	dirFiles := map[string]map[string]int{} // dir → generator → files
+	for rel, sum := range cur.Files {
+		gen := owners[rel]
+		c := change(gen)
+		if info, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(rel))); err == nil {
+			c.Bytes += info.Size()
+			d.Total.Bytes += info.Size()
+		}
+		for dir := path.Dir(rel); ; dir = path.Dir(dir) {
+			if dirFiles[dir] == nil {
+				dirFiles[dir] = map[string]int{}
+			}
+			dirFiles[dir][gen]++
+			if dir == "." {
+				break
+			}
+		}
+		if prev == nil {
+			continue
+		}
+		switch old, ok := prev.Files[rel]; {
+		case !ok:
+			c.Added++
+			d.Total.Added++
+		case old != sum:
+			c.Changed++
+			d.Total.Changed++
+		}
+	}
+
+	if prev != nil {
+		for rel := range prev.Files {
+			if _, ok := cur.Files[rel]; ok {
+				continue
+			}
+			dir := path.Dir(rel)
+			for dirFiles[dir] == nil && path.Dir(dir) != "." {
+				dir = path.Dir(dir)
+			}
+			change(mostFiles(dirFiles[dir])).Removed++
+			d.Total.Removed++
+		}
+	}
+	return d
+}
+
+// mostFiles returns the generator with the most files, by name on a tie.
+func mostFiles(counts map[string]int) string {
+	best := ""
+	for gen, n := range counts {
+		if n > counts[best] || n == counts[best] && gen < best {
+			best = gen
+		}
+	}
+	return best
+}
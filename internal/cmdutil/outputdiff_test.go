@@ -0,0 +1,70 @@
+package cmdutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/barun-bash/human/internal/build"
+)
+
+func TestDiffOutput(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeOutputFile(t, dir, "node/src/server.ts", "server v2", now)
+	writeOutputFile(t, dir, "node/src/routes/posts.ts", "posts", now)
+	writeOutputFile(t, dir, "node/src/routes/posts.test.ts", "tests", now)
+	writeOutputFile(t, dir, "docker-compose.yml", "services:", now)
+
+	results := []build.Result{
+		{Name: "node", Paths: []string{"node/src/server.ts", "node/src/routes/posts.ts", "node/src/routes/posts.test.ts"}},
+		{Name: "docker", Paths: []string{"docker-compose.yml"}},
+		{Name: "quality", Paths: []string{"node/src/routes/posts.test.ts"}},
+	}
+	owners := outputOwners(results)
+	if owners["node/src/routes/posts.test.ts"] != "quality" {
+		t.Errorf("a file written twice belongs to the later generator, got %q", owners["node/src/routes/posts.test.ts"])
+	}
+
+	prev := &Manifest{Files: map[string]string{
+		"node/src/server.ts":         "old",
+		"docker-compose.yml":         "same",
+		"node/src/routes/authors.ts": "gone",
+		"python/main.py":             "gone",
+	}}
+	cur := &Manifest{Files: map[string]string{
+		"node/src/server.ts":            "new",
+		"node/src/routes/posts.ts":      "posts",
+		"node/src/routes/posts.test.ts": "tests",
+		"docker-compose.yml":            "same",
+	}}
+
+	d := diffOutput(dir, prev, cur, owners)
+	node := d.Generators["node"]
+	if node == nil || node.Added != 1 || node.Changed != 1 || node.Removed != 1 {
+		t.Errorf("node = %+v, want 1 added, 1 changed, 1 removed", node)
+	}
+	if node.Bytes != int64(len("server v2")+len("posts")) {
+		t.Errorf("node wrote %d bytes", node.Bytes)
+	}
+	if docker := d.Generators["docker"]; docker == nil || formatChange(docker) != "unchanged" {
+		t.Errorf("docker = %+v, want unchanged", docker)
+	}
+	if d.Total.Added != 2 || d.Total.Changed != 1 || d.Total.Removed != 2 {
+		t.Errorf("total = %+v, want 2 added, 1 changed, 2 removed", d.Total)
+	}
+	if got := formatChange(&d.Total); got != "+2 ~1 -2" {
+		t.Errorf("formatChange = %q", got)
+	}
+
+	if first := diffOutput(dir, nil, cur, owners); first.Previous || first.Total.Added != 0 {
+		t.Errorf("a first build has nothing to compare with: %+v", first.Total)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{0: "0 B", 812: "812 B", 14540: "14.2 KB", 3 << 20: "3.0 MB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/mock"
+)
+
+// overviewSection is one table of an architecture overview.
+type overviewSection struct {
+	Title   string
+	Columns []string
+	Rows    [][]string
+	Empty   string // shown instead of the table when there are no rows
+}
+
+// RunExplainApp prints an architecture overview of the app in file, a
+// .human file or project directory: its entities, endpoints, pages and the
+// APIs they call, integrations, and deployment. With markdown the overview
+// is printed as Markdown; with outputPath it is written there as Markdown.
+func RunExplainApp(out io.Writer, file string, markdown bool, outputPath string) error {
+	result, err := ParseAndAnalyze(file)
+	if err != nil {
+		return err
+	}
+	if result.Errs.HasErrors() {
+		PrintDiagnostics(result.Errs)
+		return fmt.Errorf("%d error(s) found", len(result.Errs.Errors()))
+	}
+
+	sections := architectureOverview(result.App)
+	if outputPath != "" {
+		if err := os.WriteFile(outputPath, []byte(renderOverviewMarkdown(result.App, sections)), 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", outputPath, err)
+		}
+		fmt.Fprintln(out, cli.Success("Wrote the architecture overview to "+outputPath))
+		return nil
+	}
+	if markdown {
+		fmt.Fprint(out, renderOverviewMarkdown(result.App, sections))
+		return nil
+	}
+	printOverview(out, result.App, sections)
+	return nil
+}
+
+// architectureOverview walks the IR into the overview's sections.
+func architectureOverview(app *ir.Application) []overviewSection {
+	return []overviewSection{
+		entitySection(app),
+		endpointSection(app),
+		pageSection(app),
+		integrationSection(app),
+		deploymentSection(app),
+	}
+}
+
+// renderOverviewMarkdown renders an overview's sections as a Markdown
+// document.
+func renderOverviewMarkdown(app *ir.Application, sections []overviewSection) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s — Architecture Overview\n\n", app.Name)
+	fmt.Fprintf(&b, "%s\n\n", overviewSummary(app))
+	for _, s := range sections {
+		fmt.Fprintf(&b, "## %s\n\n", s.Title)
+		if len(s.Rows) == 0 {
+			fmt.Fprintf(&b, "%s\n\n", s.Empty)
+			continue
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(s.Columns, " | "))
+		fmt.Fprintf(&b, "|%s\n", strings.Repeat("---|", len(s.Columns)))
+		for _, row := range s.Rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// printOverview prints an overview's sections as aligned columns.
+func printOverview(out io.Writer, app *ir.Application, sections []overviewSection) {
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%s\n", cli.Heading(app.Name+" — architecture overview"))
+	fmt.Fprintf(out, "%s\n", cli.Muted(overviewSummary(app)))
+	for _, s := range sections {
+		fmt.Fprintf(out, "\n%s\n", cli.Heading(s.Title))
+		if len(s.Rows) == 0 {
+			fmt.Fprintf(out, "  %s\n", cli.Muted(s.Empty))
+			continue
+		}
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "  %s\n", strings.Join(s.Columns, "\t"))
+		for _, row := range s.Rows {
+			fmt.Fprintf(tw, "  %s\n", strings.Join(row, "\t"))
+		}
+		tw.Flush()
+	}
+	fmt.Fprintln(out)
+}
+
+// overviewSummary counts what the app declares.
+func overviewSummary(app *ir.Application) string {
+	platform := app.Platform
+	if platform == "" {
+		platform = "web"
+	}
+	return fmt.Sprintf("A %s application with %d entities, %d endpoints, %d pages, and %d integrations.",
+		platform, len(app.Data), len(app.APIs), len(app.Pages), len(app.Integrations))
+}
+
+// ── Entities ──
+
+func entitySection(app *ir.Application) overviewSection {
+	s := overviewSection{
+		Title:   "Entities",
+		Columns: []string{"Entity", "Fields", "Relationships"},
+		Empty:   "No data models.",
+	}
+	for _, m := range app.Data {
+		var fields []string
+		for _, f := range m.Fields {
+			fields = append(fields, describeField(f))
+		}
+		var rels []string
+		for _, r := range m.Relations {
+			rels = append(rels, describeRelation(r))
+		}
+		s.Rows = append(s.Rows, []string{m.Name, orDash(strings.Join(fields, ", ")), orDash(strings.Join(rels, ", "))})
+	}
+	return s
+}
+
+// describeField writes a field as "email (email, required, unique)".
+func describeField(f *ir.DataField) string {
+	attrs := []string{f.Type}
+	if f.Type == "enum" && len(f.EnumValues) > 0 {
+		attrs[0] = "enum: " + strings.Join(f.EnumValues, "/")
+	}
+	if f.Required {
+		attrs = append(attrs, "required")
+	}
+	if f.Unique {
+		attrs = append(attrs, "unique")
+	}
+	if f.Encrypted {
+		attrs = append(attrs, "encrypted")
+	}
+	return fmt.Sprintf("%s (%s)", f.Name, strings.Join(attrs, ", "))
+}
+
+func describeRelation(r *ir.Relation) string {
+	switch r.Kind {
+	case "belongs_to":
+		return "belongs to " + r.Target
+	case "has_many_through":
+		return fmt.Sprintf("has many %s through %s", r.Target, r.Through)
+	case "has_many":
+		return "has many " + r.Target
+	}
+	return strings.ReplaceAll(r.Kind, "_", " ") + " " + r.Target
+}
+
+// ── Endpoints ──
+
+func endpointSection(app *ir.Application) overviewSection {
+	s := overviewSection{
+		Title:   "Endpoints",
+		Columns: []string{"Endpoint", "Route", "Auth", "Validation"},
+		Empty:   "No API endpoints.",
+	}
+	for _, route := range mock.InferRoutes(app) {
+		ep := route.Endpoint
+		auth := "public"
+		if ep.Auth {
+			auth = "required"
+		}
+		var rules []string
+		for _, v := range ep.Validation {
+			rules = append(rules, describeValidation(v))
+		}
+		s.Rows = append(s.Rows, []string{ep.Name, route.Method + " " + route.Path, auth, orDash(strings.Join(rules, ", "))})
+	}
+	return s
+}
+
+// describeValidation writes a rule as "title not empty" or "title max
+// length 100".
+func describeValidation(v *ir.ValidationRule) string {
+	desc := v.Field + " " + strings.ReplaceAll(v.Rule, "_", " ")
+	if v.Value != "" {
+		desc += " " + v.Value
+	}
+	return desc
+}
+
+// ── Pages ──
+
+var overviewNavigateRe = regexp.MustCompile(`(?i)navigates?\s+to\s+(\w+)`)
+
+func pageSection(app *ir.Application) overviewSection {
+	s := overviewSection{
+		Title:   "Pages",
+		Columns: []string{"Page", "Calls", "Navigates to"},
+		Empty:   "No pages.",
+	}
+	for _, page := range app.Pages {
+		var navs []string
+		seen := map[string]bool{}
+		for _, a := range page.Content {
+			for _, m := range overviewNavigateRe.FindAllStringSubmatch(a.Text, -1) {
+				if !seen[m[1]] {
+					seen[m[1]] = true
+					navs = append(navs, m[1])
+				}
+			}
+		}
+		s.Rows = append(s.Rows, []string{page.Name, orDash(strings.Join(pageAPIs(page, app), ", ")), orDash(strings.Join(navs, ", "))})
+	}
+	return s
+}
+
+// pageAPIs returns the endpoints a page calls: the list endpoint of the
+// model it loads, found as the frontend generators find it, and each
+// endpoint whose name's words all appear in one of its statements, as
+// "clicking "Save" updates the user profile" calls UpdateProfile.
+func pageAPIs(page *ir.Page, app *ir.Application) []string {
+	var apis []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			apis = append(apis, name)
+		}
+	}
+
+	if m := overviewPageModel(page, app); m != nil {
+		if ep := overviewListEndpoint(app, m.Name); ep != nil {
+			add(ep.Name)
+		}
+	}
+	for _, a := range page.Content {
+		text := strings.ToLower(a.Text)
+		for _, ep := range app.APIs {
+			words := splitCamel(ep.Name)
+			matched := len(words) > 0
+			for _, w := range words {
+				if !strings.Contains(text, w) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				add(ep.Name)
+			}
+		}
+	}
+	return apis
+}
+
+// overviewPageModel returns the first model named by a page's query,
+// loop, or table.
+func overviewPageModel(page *ir.Page, app *ir.Application) *ir.DataModel {
+	for _, a := range page.Content {
+		if ir.IsTable(a) {
+			if m := ir.TableModel(app, a); m != nil {
+				return m
+			}
+			continue
+		}
+		if a.Type != "query" && a.Type != "loop" {
+			continue
+		}
+		for _, m := range app.Data {
+			if strings.Contains(strings.ToLower(a.Text), strings.ToLower(m.Name)) {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// overviewListEndpoint returns the endpoint listing a model's records:
+// ListTasks, or else GetTasks.
+func overviewListEndpoint(app *ir.Application, model string) *ir.Endpoint {
+	lowerModel := strings.ToLower(model)
+	for _, prefix := range []string{"list", "get"} {
+		for _, ep := range app.APIs {
+			lower := strings.ToLower(ep.Name)
+			if strings.HasPrefix(lower, prefix) && strings.Contains(lower, lowerModel) && !ir.LoadsRecord(app, ep) {
+				return ep
+			}
+		}
+	}
+	return nil
+}
+
+// splitCamel splits "UpdateProfile" into "update" and "profile".
+func splitCamel(name string) []string {
+	var words []string
+	start := 0
+	for i := 1; i <= len(name); i++ {
+		if i == len(name) || (name[i] >= 'A' && name[i] <= 'Z') {
+			if w := strings.ToLower(strings.Trim(name[start:i], "_")); w != "" {
+				words = append(words, w)
+			}
+			start = i
+		}
+	}
+	return words
+}
+
+// ── Integrations ──
+
+func integrationSection(app *ir.Application) overviewSection {
+	s := overviewSection{
+		Title:   "Integrations",
+		Columns: []string{"Service", "Type", "Purpose", "Credentials"},
+		Empty:   "No third-party integrations.",
+	}
+	for _, integ := range app.Integrations {
+		var creds []string
+		for _, env := range integ.Credentials {
+			creds = append(creds, env)
+		}
+		sort.Strings(creds)
+		s.Rows = append(s.Rows, []string{integ.Service, orDash(integ.Type), orDash(integ.Purpose), orDash(strings.Join(creds, ", "))})
+	}
+	return s
+}
+
+// ── Deployment ──
+
+func deploymentSection(app *ir.Application) overviewSection {
+	s := overviewSection{
+		Title:   "Deployment",
+		Columns: []string{"Component", "Detail"},
+		Empty:   "No build or deployment configuration.",
+	}
+	add := func(name, detail string) {
+		if detail != "" {
+			s.Rows = append(s.Rows, []string{name, detail})
+		}
+	}
+
+	if c := app.Config; c != nil {
+		add("Frontend", c.Frontend)
+		add("Backend", c.Backend)
+		add("API style", c.APIStyle)
+		add("Database", c.Database)
+		add("Deploy", c.Deploy)
+	}
+	if app.Database != nil {
+		var db []string
+		if app.Database.ReadReplica {
+			db = append(db, "read replica")
+		}
+		if app.Database.PoolSize > 0 {
+			db = append(db, fmt.Sprintf("pool of %d connections", app.Database.PoolSize))
+		}
+		if app.Database.Backup != nil {
+			db = append(db, "backups")
+		}
+		add("Database setup", strings.Join(db, ", "))
+	}
+	if auth := app.Auth; auth != nil {
+		var methods []string
+		for _, m := range auth.Methods {
+			method := strings.ToUpper(m.Type)
+			if m.Type == "oauth" {
+				method = "OAuth"
+			}
+			if m.Provider != "" {
+				method += " (" + m.Provider + ")"
+			}
+			methods = append(methods, method)
+		}
+		add("Authentication", strings.Join(methods, ", "))
+	}
+	if ir.UsesJobQueue(app) {
+		add("Background jobs", fmt.Sprintf("%d workflow(s), queued in Redis and run by a worker", len(app.Workflows)))
+	}
+
+	if arch := app.Architecture; arch != nil {
+		add("Architecture", arch.Style)
+		for _, svc := range arch.Services {
+			var detail []string
+			if svc.Handles != "" {
+				detail = append(detail, "handles "+svc.Handles)
+			}
+			if svc.Port > 0 {
+				detail = append(detail, fmt.Sprintf("port %d", svc.Port))
+			}
+			if len(svc.Models) > 0 {
+				detail = append(detail, "owns "+strings.Join(svc.Models, ", "))
+			}
+			if svc.HasOwnDatabase {
+				detail = append(detail, "own database")
+			}
+			if len(svc.TalksTo) > 0 {
+				detail = append(detail, "talks to "+strings.Join(svc.TalksTo, ", "))
+			}
+			add("Service "+svc.Name, orDash(strings.Join(detail, "; ")))
+		}
+		if gw := arch.Gateway; gw != nil {
+			var routes []string
+			for path, svc := range gw.Routes {
+				routes = append(routes, path+" → "+svc)
+			}
+			sort.Strings(routes)
+			add("Gateway", orDash(strings.Join(routes, ", ")))
+		}
+		add("Message broker", arch.Broker)
+	}
+
+	for _, env := range app.Environments {
+		detail := env.URL()
+		if detail == "" {
+			detail = "-"
+		}
+		add("Environment "+env.Name, detail)
+	}
+	for _, p := range app.Pipelines {
+		add("Pipeline", fmt.Sprintf("%s (%d steps)", p.Trigger, len(p.Steps)))
+	}
+	return s
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
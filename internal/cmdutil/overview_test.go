@@ -0,0 +1,112 @@
+package cmdutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const overviewApp = `app Notes is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has many Note
+
+data Note:
+  belongs to a User
+  has a title which is text
+
+page Home:
+  show a list of notes
+  each note shows its title
+  clicking "New" opens a form to create a Note
+  clicking "Settings" navigates to Settings
+
+page Settings:
+  show "Settings"
+
+api GetNotes:
+  requires authentication
+  fetch all notes for the current user
+  respond with the notes
+
+api CreateNote:
+  requires authentication
+  accepts title
+  check that title is not empty
+  create a Note with the given fields
+  respond with the created note
+
+integrate with SendGrid:
+  api key from environment variable SENDGRID_API_KEY
+  use for sending emails
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  frontend using React
+  backend using Node with Express
+  database using PostgreSQL
+  deploy to Docker
+
+environment production:
+  url is notes.example.com
+`
+
+func TestRunExplainAppMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.human")
+	if err := os.WriteFile(file, []byte(overviewApp), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := RunExplainApp(&buf, file, true, ""); err != nil {
+		t.Fatalf("RunExplainApp: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"# Notes — Architecture Overview",
+		"| Note | title (text, required) | belongs to User |",
+		"| User | name (text, required), email (email, required, unique) | has many Note |",
+		"| GetNotes | GET /api/notes | required | - |",
+		"| CreateNote | POST /api/note | required | title not empty |",
+		"| Home | GetNotes, CreateNote | Settings |",
+		"| Settings | - | - |",
+		"| SendGrid | email | sending emails | SENDGRID_API_KEY |",
+		"| Frontend | React |",
+		"| Authentication | JWT |",
+		"| Environment production | https://notes.example.com |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("overview missing %q:\n%s", want, got)
+		}
+	}
+
+	out := filepath.Join(dir, "ARCHITECTURE.md")
+	buf.Reset()
+	if err := RunExplainApp(&buf, file, false, out); err != nil {
+		t.Fatalf("RunExplainApp -o: %v", err)
+	}
+	written, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(written) != got {
+		t.Errorf("written overview differs from the printed one:\n%s", written)
+	}
+}
+
+func TestSplitCamel(t *testing.T) {
+	got := strings.Join(splitCamel("UpdateProfile"), " ")
+	if got != "update profile" {
+		t.Errorf("splitCamel(UpdateProfile) = %q", got)
+	}
+	if got := strings.Join(splitCamel("Login"), " "); got != "login" {
+		t.Errorf("splitCamel(Login) = %q", got)
+	}
+}
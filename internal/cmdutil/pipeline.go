@@ -2,15 +2,20 @@ package cmdutil
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/barun-bash/human/internal/analyzer"
 	"github.com/barun-bash/human/internal/build"
 	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/config"
 	cerr "github.com/barun-bash/human/internal/errors"
 	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/migrate"
 	"github.com/barun-bash/human/internal/parser"
 	"github.com/barun-bash/human/internal/quality"
 )
@@ -20,18 +25,33 @@ type ParseResult struct {
 	Prog        *parser.Program
 	App         *ir.Application
 	Errs        *cerr.CompilerErrors
-	SourceFiles []string // all .human files in the project
+	SourceFiles []string // all .human files in the project, imported ones included
 }
 
 // ParseAndAnalyze reads a .human file (or directory), discovers sibling files,
-// parses and merges them, builds the IR, and runs semantic analysis.
+// parses them and the files they import, merges them, builds the IR for
+// local development, and runs semantic analysis.
 func ParseAndAnalyze(file string) (*ParseResult, error) {
+	return ParseAndAnalyzeEnv(file, "")
+}
+
+// ParseAndAnalyzeEnv is like ParseAndAnalyze but builds the IR for the
+// environment called env, as `human build --env` does. An empty env builds
+// for local development.
+func ParseAndAnalyzeEnv(file, env string) (*ParseResult, error) {
+	start := time.Now()
 	files, err := parser.DiscoverFiles(file)
 	if err != nil {
 		return nil, err
 	}
 
-	programs, err := parser.ParseFiles(files)
+	programs, files, err := parser.ParseProject(filepath.Dir(files[0]), files)
+	if syntax, ok := err.(*parser.Errors); ok {
+		for _, e := range syntax.Diagnostics {
+			PrintDiagnostic(e)
+		}
+		return nil, fmt.Errorf("%d syntax error(s) found", len(syntax.Diagnostics))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -46,15 +66,26 @@ func ParseAndAnalyze(file string) (*ParseResult, error) {
 		}
 	}
 
+	cli.Verbosef("parse: %d file(s) in %s", len(files), formatDuration(time.Since(start)))
+
+	start = time.Now()
 	app, err := ir.Build(prog)
 	if err != nil {
 		return nil, fmt.Errorf("IR build error: %w", err)
 	}
+	if env != "" {
+		if err := SelectEnvironment(app, env); err != nil {
+			return nil, err
+		}
+	}
+	cli.Verbosef("ir: %d model(s), %d page(s), %d API(s) in %s", len(app.Data), len(app.Pages), len(app.APIs), formatDuration(time.Since(start)))
 
+	start = time.Now()
 	errs := analyzer.Analyze(app, files[0])
+	cli.Verbosef("analyze: %d error(s), %d warning(s) in %s", len(errs.Errors()), len(errs.Warnings()), formatDuration(time.Since(start)))
 
 	if len(files) > 1 {
-		fmt.Printf("Parsed %d files\n", len(files))
+		cli.Printf("Parsed %d files\n", len(files))
 	}
 
 	return &ParseResult{Prog: prog, App: app, Errs: errs, SourceFiles: files}, nil
@@ -81,25 +112,65 @@ func PrintDiagnostics(errs *cerr.CompilerErrors) bool {
 func PrintDiagnostic(e *cerr.CompilerError) {
 	switch e.Severity {
 	case cerr.SeverityWarning:
-		fmt.Fprintln(os.Stderr, cli.Warn(e.Format()))
+		cli.Warnln(e.Format())
 	default:
-		fmt.Fprintln(os.Stderr, cli.Error(e.Format()))
+		cli.Errorln(e.Format())
 	}
-	if e.Suggestion != "" {
+	if e.Suggestion != "" && cli.LogLevel >= cli.LevelNormal {
 		fmt.Fprintf(os.Stderr, "  suggestion: %s\n", e.Suggestion)
 	}
 }
 
 // FullBuild runs the complete build pipeline: parse, analyze, generate IR YAML,
-// run code generators, and the quality engine. Returns the application IR,
-// generator results, quality result, and build timing.
-func FullBuild(file string) (*ir.Application, []build.Result, *quality.Result, *build.BuildTiming, error) {
-	return FullBuildWithProgress(file, nil)
+// run code generators, and the quality engine. It builds for the environment
+// called env, or for local development when env is "". Returns the
+// application IR, generator results, quality result, and build timing.
+func FullBuild(file, env string) (*ir.Application, []build.Result, *quality.Result, *build.BuildTiming, error) {
+	return FullBuildWithProgress(file, env, nil)
+}
+
+// ProgressDisplay shows progress while the generators and quality engine
+// run. *cli.ProgressBox is one.
+type ProgressDisplay interface {
+	Update(stage string)
+	FailStage(stage string)
+	Finish()
+}
+
+// progressFunc adapts a build.ProgressFunc to a ProgressDisplay.
+type progressFunc build.ProgressFunc
+
+func (f progressFunc) Update(stage string) {
+	if f != nil {
+		f(stage)
+	}
 }
 
+func (f progressFunc) FailStage(string) {}
+func (f progressFunc) Finish()          {}
+
 // FullBuildWithProgress is like FullBuild but reports progress via a callback.
-func FullBuildWithProgress(file string, progress build.ProgressFunc) (*ir.Application, []build.Result, *quality.Result, *build.BuildTiming, error) {
-	result, err := ParseAndAnalyze(file)
+func FullBuildWithProgress(file, env string, progress build.ProgressFunc) (*ir.Application, []build.Result, *quality.Result, *build.BuildTiming, error) {
+	return fullBuild(file, env, func(*ir.Application) ProgressDisplay { return progressFunc(progress) })
+}
+
+// FullBuildWithProgressBox is like FullBuild but draws a progress box on out
+// while the generators and quality engine run. The box is closed before the
+// build summary prints.
+func FullBuildWithProgressBox(file, env string, out io.Writer) (*ir.Application, []build.Result, *quality.Result, *build.BuildTiming, error) {
+	return fullBuild(file, env, func(app *ir.Application) ProgressDisplay {
+		box := cli.NewProgressBox(out, "Building "+app.Name, build.PlanStages(app))
+		box.Start()
+		return box
+	})
+}
+
+func fullBuild(file, env string, newDisplay func(*ir.Application) ProgressDisplay) (*ir.Application, []build.Result, *quality.Result, *build.BuildTiming, error) {
+	if err := RunHooks(".", HookContext{Hook: HookPreBuild, Source: file}); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	result, err := ParseAndAnalyzeEnv(file, env)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
@@ -116,6 +187,13 @@ func FullBuildWithProgress(file string, progress build.ProgressFunc) (*ir.Applic
 		result.App.Config.Ports = PromptForPorts(os.Stdin, os.Stdout)
 	}
 
+	// Record the migration for any change to the data models, so the
+	// backends migrate existing databases instead of replacing the schema.
+	migration, err := migrate.Record(migrate.Dir, result.App)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	yaml, err := ir.ToYAML(result.App)
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("serialization error: %w", err)
@@ -127,24 +205,84 @@ func FullBuildWithProgress(file string, progress build.ProgressFunc) (*ir.Applic
 		return nil, nil, nil, nil, fmt.Errorf("creating output directory: %w", err)
 	}
 
-	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
-	outFile := filepath.Join(outDir, base+".yaml")
+	outFile := IntentPath(file)
 	if err := os.WriteFile(outFile, []byte(yaml), 0644); err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("writing %s: %w", outFile, err)
 	}
 
-	fmt.Printf("Built %s → %s\n", file, outFile)
+	cli.Printf("Built %s → %s\n", file, outFile)
 	PrintIRSummary(result.App)
+	if migration != nil {
+		PrintMigration(migration)
+	}
 
-	// Run all code generators
+	// Run all code generators. The manifest is removed first so an
+	// interrupted build leaves none behind. Timestamps are truncated to the
+	// second because some filesystems store mtimes no finer than that.
 	outputDir := filepath.Join(".human", "output")
-	results, qResult, timing, genErr := build.RunGeneratorsWithProgress(result.App, outputDir, progress)
+	prev, _ := readManifest(outputDir)
+	removeManifest(outputDir)
+	started := time.Now().Truncate(time.Second)
+	display := newDisplay(result.App)
+	var stage string
+	results, qResult, timing, genErr := build.RunGeneratorsIncremental(result.App, outputDir, build.CacheDir, func(s string) {
+		stage, _, _ = strings.Cut(s, ": ")
+		display.Update(s)
+	})
 	if genErr != nil {
+		display.FailStage(stage)
 		return nil, nil, nil, nil, fmt.Errorf("build failed: %w", genErr)
 	}
+	display.Finish()
+	generated := withSkipped(generatedSince(outputDir, started), results)
+	owners := outputOwners(results)
 
 	quality.PrintSummary(qResult)
-	PrintBuildSummary(results, outputDir, timing)
+	if err := recordQuality(result.App, qResult); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	if err := RunHooks(".", HookContext{Hook: HookPostGenerate, Source: file, IRPath: outFile, OutputDir: outputDir}); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// Hash after the post_generate hooks, which may reformat generated
+	// files; files the hooks add (e.g. node_modules) aren't included.
+	cfg, err := config.Load(".")
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	cur, err := writeManifest(outputDir, generated, result.SourceFiles, cfg.Signing)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// The summary compares manifests, so it comes after the hooks too.
+	diff := diffOutput(outputDir, prev, cur, owners)
+	PrintGeneratedFiles(results, outputDir)
+	PrintBuildSummary(results, outputDir, timing, diff)
 
 	return result.App, results, qResult, timing, nil
 }
+
+// withSkipped adds the files of generators the build cache skipped, which
+// weren't rewritten, to the files generated this build.
+func withSkipped(generated []string, results []build.Result) []string {
+	seen := map[string]bool{}
+	for _, rel := range generated {
+		seen[rel] = true
+	}
+	for _, r := range results {
+		if !r.Skipped {
+			continue
+		}
+		for _, p := range r.Paths {
+			if rel := filepath.ToSlash(p); !seen[rel] {
+				seen[rel] = true
+				generated = append(generated, rel)
+			}
+		}
+	}
+	sort.Strings(generated)
+	return generated
+}
@@ -0,0 +1,70 @@
+package cmdutil
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/config"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/quality"
+)
+
+// CIBuild is set by `human build --ci`: a build whose quality score falls
+// short of the project's minimums fails.
+var CIBuild bool
+
+// QualityHistoryPath is the JSON Lines file each build appends its quality
+// score to, so the score can be tracked over time.
+var QualityHistoryPath = filepath.Join(".human", "quality-history.jsonl")
+
+// recordQuality appends the build's quality score to the history, noting
+// its change since the last build, and checks it against the minimums in
+// .human/config.json. Unmet minimums fail CI builds, and are warnings
+// otherwise.
+func recordQuality(app *ir.Application, q *quality.Result) error {
+	if q == nil || q.Score == nil {
+		return nil
+	}
+	history, _ := quality.ReadHistory(QualityHistoryPath)
+	if len(history) > 0 {
+		if delta := q.Score.Total - history[len(history)-1].Total; delta != 0 {
+			cli.Printf("  score change: %+d since the last build\n", delta)
+		}
+	}
+	builtAt := time.Now().UTC().Format(time.RFC3339)
+	if app.Build != nil {
+		builtAt = app.Build.GeneratedAt
+	}
+	if err := quality.AppendHistory(QualityHistoryPath, quality.NewHistoryEntry(app, q.Score, builtAt)); err != nil {
+		return fmt.Errorf("quality history: %w", err)
+	}
+
+	cfg, err := config.Load(".")
+	if err != nil {
+		return err
+	}
+	if cfg.Quality == nil {
+		return nil
+	}
+	for name := range cfg.Quality.Minimums {
+		if !slices.Contains(quality.ScoreCategories(), name) {
+			return fmt.Errorf("quality minimum for unknown category %q in .human/config.json (categories: %s)",
+				name, strings.Join(quality.ScoreCategories(), ", "))
+		}
+	}
+	unmet := quality.UnmetGates(q.Score, cfg.Quality.MinScore, cfg.Quality.Minimums)
+	if len(unmet) == 0 {
+		return nil
+	}
+	if CIBuild {
+		return fmt.Errorf("quality gates failed:\n  %s", strings.Join(unmet, "\n  "))
+	}
+	for _, msg := range unmet {
+		cli.Println(cli.Warn(msg))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/quality"
+)
+
+func TestRecordQuality(t *testing.T) {
+	t.Chdir(t.TempDir())
+	app := &ir.Application{Name: "Shop"}
+	q := &quality.Result{Score: &quality.Score{Total: 72, Categories: []quality.CategoryScore{{Name: "tests", Score: 60}}}}
+
+	// No minimums: the score is only recorded.
+	if err := recordQuality(app, q); err != nil {
+		t.Fatalf("recordQuality: %v", err)
+	}
+	os.MkdirAll(".human", 0755)
+	os.WriteFile(filepath.Join(".human", "config.json"), []byte(`{"quality": {"min_score": 80, "minimums": {"tests": 50}}}`), 0644)
+
+	// Unmet minimums warn...
+	if err := recordQuality(app, q); err != nil {
+		t.Fatalf("expected unmet minimums to warn outside CI, got %v", err)
+	}
+
+	// ...and fail CI builds.
+	CIBuild = true
+	defer func() { CIBuild = false }()
+	err := recordQuality(app, q)
+	if err == nil || !strings.Contains(err.Error(), "72 is below the minimum of 80") || strings.Contains(err.Error(), "tests") {
+		t.Errorf("recordQuality error = %v, want the total's minimum alone", err)
+	}
+
+	history, err := quality.ReadHistory(QualityHistoryPath)
+	if err != nil || len(history) != 3 || history[0].App != "Shop" || history[0].Total != 72 {
+		t.Errorf("history = %+v, %v, want each build's score", history, err)
+	}
+
+	os.WriteFile(filepath.Join(".human", "config.json"), []byte(`{"quality": {"minimums": {"coverage": 50}}}`), 0644)
+	if err := recordQuality(app, q); err == nil || !strings.Contains(err.Error(), `"coverage"`) {
+		t.Errorf("expected an unknown category to be an error, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package cmdutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// backendScript is a file a build generates for a backend, with the
+// commands that run it; the first command found on PATH is used.
+type backendScript struct {
+	dir, script string
+	commands    [][]string
+}
+
+// seedScripts are the generated seed scripts, by backend directory.
+var seedScripts = []backendScript{
+	{"node", "prisma/seed.ts", [][]string{{"npx", "prisma", "db", "seed"}}},
+	{"python", "seed.py", [][]string{{"python3", "seed.py"}, {"python", "seed.py"}}},
+	{"go", "cmd/seed/main.go", [][]string{{"go", "run", "./cmd/seed"}}},
+}
+
+// seedEnv is what seed scripts keep in a scrubbed environment: the
+// database URL, and the keys of fields encrypted at rest.
+var seedEnv = []string{"DATABASE_URL", "FIELD_ENCRYPTION_*"}
+
+// SeedDatabase runs the build's generated seed script, filling the
+// development database with the app's sample records. DATABASE_URL comes
+// from the environment or, failing that, the build's .env.
+func SeedDatabase(outputDir string) error {
+	dir, command, err := seedCommand(outputDir)
+	if err != nil {
+		return err
+	}
+	return runBackendCommand(outputDir, dir, command, seedEnv)
+}
+
+// seedCommand returns the backend directory holding the build's seed
+// script and the command running it.
+func seedCommand(outputDir string) (string, []string, error) {
+	dir, command, err := scriptCommand(outputDir, seedScripts, true)
+	if err == nil && command == nil {
+		err = fmt.Errorf("the build has no seed script. Seed scripts are generated for Node, Python, and Go backends with data models")
+	}
+	return dir, command, err
+}
+
+// scriptCommand returns the backend directory holding the first of
+// scripts the build generated, and the command running it: the first on
+// PATH or, when lookPath is false, the first listed. It returns a nil
+// command when the build has none of the scripts.
+func scriptCommand(outputDir string, scripts []backendScript, lookPath bool) (string, []string, error) {
+	for _, s := range scripts {
+		dir := filepath.Join(outputDir, s.dir)
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(s.script))); err != nil {
+			continue
+		}
+		if !lookPath {
+			return dir, s.commands[0], nil
+		}
+		for _, command := range s.commands {
+			if _, err := exec.LookPath(command[0]); err == nil {
+				return dir, command, nil
+			}
+		}
+		return "", nil, fmt.Errorf("%s not found in PATH. Install it to run %s/%s", s.commands[0][0], s.dir, s.script)
+	}
+	return "", nil, nil
+}
+
+// runBackendCommand runs command in a backend directory, with the
+// environment variables named by env from the environment or, failing
+// that, the build's .env.
+func runBackendCommand(outputDir, dir string, command, env []string) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Env = withDotEnv(CommandEnv(env...), filepath.Join(outputDir, ".env"))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,25 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSeedCommand(t *testing.T) {
+	out := t.TempDir()
+	if _, _, err := seedCommand(out); err == nil || !strings.Contains(err.Error(), "no seed script") {
+		t.Errorf("expected a missing-script error, got %v", err)
+	}
+
+	os.MkdirAll(filepath.Join(out, "go", "cmd", "seed"), 0755)
+	os.WriteFile(filepath.Join(out, "go", "cmd", "seed", "main.go"), []byte("package main\n"), 0644)
+	dir, command, err := seedCommand(out)
+	if err != nil {
+		t.Fatalf("seedCommand: %v", err)
+	}
+	if dir != filepath.Join(out, "go") || strings.Join(command, " ") != "go run ./cmd/seed" {
+		t.Errorf("seedCommand = %s, %q", dir, command)
+	}
+}
@@ -0,0 +1,69 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/selfcheck"
+)
+
+// RunSelfcheck compiles the Human code the compiler ships with — the
+// syntax patterns' examples, the snippets, the `human learn` lessons, the
+// `human init` templates, and the example apps in examplesDir, if it
+// exists — and prints what the parser or analyzer rejects. It returns an
+// error if anything was.
+func RunSelfcheck(out io.Writer, examplesDir string) error {
+	var problems []selfcheck.Problem
+	report := func(what string, count int, found []selfcheck.Problem) {
+		line := fmt.Sprintf("%d %s", count, what)
+		if len(found) > 0 {
+			fmt.Fprintf(out, "  %s\n", cli.Error(line))
+		} else {
+			fmt.Fprintf(out, "  %s\n", cli.Success(line))
+		}
+		problems = append(problems, found...)
+	}
+
+	found, count := selfcheck.CheckPatterns()
+	report("pattern examples", count, found)
+	found, count = selfcheck.CheckSnippets()
+	report("snippets", count, found)
+
+	var lessonSource strings.Builder
+	for _, l := range lessons {
+		lessonSource.WriteString(l.example + "\n\n")
+	}
+	report("learn lessons", len(lessons), selfcheck.Check("learn lessons", lessonSource.String()))
+
+	var templateProblems []selfcheck.Problem
+	templates := 0
+	for _, t := range AvailableAppTypes() {
+		if t.Key == "custom" {
+			continue
+		}
+		templates++
+		source := generateFromType("MyApp", t.Key, "React", "Node", "PostgreSQL", "Shadcn", "Task", defaultEntityFields)
+		templateProblems = append(templateProblems, selfcheck.Check("init template "+t.Key, source)...)
+	}
+	report("init templates", templates, templateProblems)
+
+	if info, err := os.Stat(examplesDir); err == nil && info.IsDir() {
+		found, count, err = selfcheck.CheckExamples(os.DirFS(examplesDir))
+		if err != nil {
+			return fmt.Errorf("reading examples: %w", err)
+		}
+		report("examples in "+examplesDir, count, found)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out)
+	for _, p := range problems {
+		fmt.Fprintf(out, "  %s\n", p)
+	}
+	return fmt.Errorf("selfcheck found %d problem(s)", len(problems))
+}
@@ -0,0 +1,36 @@
+package cmdutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunSelfcheck(t *testing.T) {
+	var out bytes.Buffer
+	if err := RunSelfcheck(&out, filepath.Join("..", "..", "examples")); err != nil {
+		t.Fatalf("RunSelfcheck: %v\n%s", err, out.String())
+	}
+	for _, want := range []string{"pattern examples", "snippets", "learn lessons", "init templates", "examples in"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunSelfcheckReportsExamples(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "broken"), 0755)
+	os.WriteFile(filepath.Join(dir, "broken", "app.human"), []byte("app Broken is a web application\n\ndata Post:\n  belongs to a Widget\n"), 0644)
+
+	var out bytes.Buffer
+	err := RunSelfcheck(&out, dir)
+	if err == nil || !strings.Contains(err.Error(), "1 problem") {
+		t.Fatalf("RunSelfcheck error = %v, want 1 problem", err)
+	}
+	if !strings.Contains(out.String(), "example broken: ") || !strings.Contains(out.String(), "Widget") {
+		t.Errorf("expected the broken example's problem:\n%s", out.String())
+	}
+}
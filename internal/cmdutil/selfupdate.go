@@ -0,0 +1,326 @@
+package cmdutil
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/config"
+	"github.com/barun-bash/human/internal/version"
+)
+
+// Release channels for self-update.
+const (
+	ChannelStable     = "stable"
+	ChannelPrerelease = "prerelease"
+)
+
+// releasesURL lists the project's GitHub releases. Package-level var so tests
+// can point it at httptest.NewServer.
+var releasesURL = "https://api.github.com/repos/barun-bash/human/releases"
+
+// checksumsAsset is the checksum file goreleaser publishes with each release.
+const checksumsAsset = "checksums.txt"
+
+// signatureAsset is the base64 ed25519 signature of checksumsAsset, made
+// with the release signing key when goreleaser publishes the release.
+const signatureAsset = checksumsAsset + ".sig"
+
+// releaseKey is the base64 ed25519 public key of the release signing key.
+// A release's checksums are only trusted with a signature it verifies, so
+// a compromised release page can't swap the binary and its checksum
+// together. Package-level var so tests can sign with their own key.
+var releaseKey = "t1+gXKL+46U3HX+/cXblj6kIdPWYxDKnqVwcVHykhd0="
+
+// Release is the subset of a GitHub release that self-update needs.
+type Release struct {
+	TagName    string         `json:"tag_name"`
+	Prerelease bool           `json:"prerelease"`
+	Draft      bool           `json:"draft"`
+	Assets     []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset is a file attached to a release.
+type ReleaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release's version without the leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+func (r *Release) asset(name string) *ReleaseAsset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// SelfUpdateOptions configures SelfUpdate.
+type SelfUpdateOptions struct {
+	Channel   string // ChannelStable (default) or ChannelPrerelease
+	CheckOnly bool   // report whether an update exists without installing it
+	Force     bool   // reinstall even if already on the latest version
+	Exe       string // binary to replace; defaults to the running executable
+}
+
+// SelfUpdate checks the channel's latest release and, if it is newer than
+// this binary, downloads the archive for this platform, verifies it against
+// the release's checksums.txt and that file's signature, and atomically
+// replaces the binary. It reports whether the binary was replaced.
+func SelfUpdate(out io.Writer, opts SelfUpdateOptions) (updated bool, err error) {
+	if opts.Channel == "" {
+		opts.Channel = ChannelStable
+	}
+	if opts.Channel != ChannelStable && opts.Channel != ChannelPrerelease {
+		return false, fmt.Errorf("unknown channel %q (use %s or %s)", opts.Channel, ChannelStable, ChannelPrerelease)
+	}
+	if config.Offline(".") {
+		return false, fmt.Errorf("self-update needs the network; it is disabled in offline mode")
+	}
+
+	fmt.Fprintln(out, cli.Info(fmt.Sprintf("Checking for updates (%s channel)...", opts.Channel)))
+	rel, err := LatestRelease(opts.Channel)
+	if err != nil {
+		return false, fmt.Errorf("could not check for updates: %w", err)
+	}
+
+	current := version.Version
+	latest := rel.Version()
+	if !opts.Force && !version.IsNewerRelease(latest, current) {
+		fmt.Fprintln(out, cli.Success(fmt.Sprintf("You're on the latest version (%s).", current)))
+		return false, nil
+	}
+	fmt.Fprintf(out, "  Update available: %s → %s\n", current, cli.Accent(latest))
+	if opts.CheckOnly {
+		return false, nil
+	}
+
+	exe := opts.Exe
+	if exe == "" {
+		if exe, err = os.Executable(); err != nil {
+			return false, fmt.Errorf("could not locate the running binary: %w", err)
+		}
+		if exe, err = filepath.EvalSymlinks(exe); err != nil {
+			return false, fmt.Errorf("could not locate the running binary: %w", err)
+		}
+	}
+
+	name := archiveName(latest, runtime.GOOS, runtime.GOARCH)
+	archive := rel.asset(name)
+	if archive == nil {
+		return false, fmt.Errorf("release %s has no build for %s/%s (expected %s)", rel.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	sums := rel.asset(checksumsAsset)
+	if sums == nil {
+		return false, fmt.Errorf("release %s has no %s — refusing to install an unverified binary", rel.TagName, checksumsAsset)
+	}
+	sig := rel.asset(signatureAsset)
+	if sig == nil {
+		return false, fmt.Errorf("release %s has no %s — refusing to install an unverified binary", rel.TagName, signatureAsset)
+	}
+
+	fmt.Fprintln(out, cli.Info("Downloading "+name+"..."))
+	data, err := download(archive.URL)
+	if err != nil {
+		return false, fmt.Errorf("downloading %s: %w", name, err)
+	}
+	sumData, err := download(sums.URL)
+	if err != nil {
+		return false, fmt.Errorf("downloading %s: %w", checksumsAsset, err)
+	}
+	sigData, err := download(sig.URL)
+	if err != nil {
+		return false, fmt.Errorf("downloading %s: %w", signatureAsset, err)
+	}
+	if err := verifyReleaseSignature(sumData, sigData); err != nil {
+		return false, fmt.Errorf("%s: %w", checksumsAsset, err)
+	}
+	fmt.Fprintln(out, cli.Info("Signature verified (ed25519)"))
+	want, err := lookupChecksum(sumData, name)
+	if err != nil {
+		return false, err
+	}
+	if err := verifyChecksum(data, want); err != nil {
+		return false, fmt.Errorf("%s: %w", name, err)
+	}
+	fmt.Fprintln(out, cli.Info("Checksum verified (sha256)"))
+
+	bin, err := extractBinary(data, "human")
+	if err != nil {
+		return false, fmt.Errorf("extracting %s: %w", name, err)
+	}
+	if err := replaceBinary(exe, bin); err != nil {
+		return false, err
+	}
+
+	fmt.Fprintln(out, cli.Success(fmt.Sprintf("Updated %s to %s.", exe, latest)))
+	return true, nil
+}
+
+// LatestRelease returns the newest published release on the channel. The
+// stable channel skips pre-releases.
+func LatestRelease(channel string) (*Release, error) {
+	body, err := download(releasesURL + "?per_page=30")
+	if err != nil {
+		return nil, err
+	}
+	var releases []Release
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, fmt.Errorf("reading release list: %w", err)
+	}
+
+	var best *Release
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft || (r.Prerelease && channel != ChannelPrerelease) {
+			continue
+		}
+		if _, err := version.Parse(r.Version()); err != nil {
+			continue
+		}
+		if best == nil || version.IsNewerRelease(r.Version(), best.Version()) {
+			best = r
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no %s releases found", channel)
+	}
+	return best, nil
+}
+
+// archiveName is the goreleaser archive for a version and platform:
+// human_<version>_<os>_<arch>.tar.gz.
+func archiveName(ver, goos, goarch string) string {
+	return fmt.Sprintf("human_%s_%s_%s.tar.gz", ver, goos, goarch)
+}
+
+// lookupChecksum finds a file's sha256 in a checksums.txt
+// ("<hex>  <name>" per line).
+func lookupChecksum(sums []byte, name string) (string, error) {
+	sc := bufio.NewScanner(bytes.NewReader(sums))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s has no entry for %s", checksumsAsset, name)
+}
+
+// verifyReleaseSignature checks a base64 ed25519 signature of data against
+// releaseKey.
+func verifyReleaseSignature(data, sig []byte) error {
+	key, err := base64.StdEncoding.DecodeString(releaseKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("this build's release key is malformed")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || len(raw) != ed25519.SignatureSize {
+		return fmt.Errorf("malformed %s", signatureAsset)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), data, raw) {
+		return fmt.Errorf("signature does not match the release key — refusing to install")
+	}
+	return nil
+}
+
+// verifyChecksum checks data against a hex-encoded sha256.
+func verifyChecksum(data []byte, want string) error {
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch: got %s, want %s", got, want)
+	}
+	return nil
+}
+
+// extractBinary returns the named file from a .tar.gz archive.
+func extractBinary(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("archive has no %s binary", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == name {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// replaceBinary writes bin next to exe and renames it over exe, so the swap
+// is atomic and a failed update leaves the old binary in place.
+func replaceBinary(exe string, bin []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".human-update-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s (try running with sudo): %w", filepath.Dir(exe), err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bin); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0o111); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		return fmt.Errorf("replacing %s: %w", exe, err)
+	}
+	return nil
+}
+
+// download fetches a URL, failing on any non-200 response.
+func download(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 2 * time.Minute}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "human-cli/"+version.Version)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,247 @@
+package cmdutil
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/version"
+)
+
+// tarGz builds a release archive holding a single "human" binary.
+func tarGz(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "human", Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	tw.Write([]byte(content))
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// signing is how releaseServer signs each release's checksums.txt.
+type signing int
+
+const (
+	signed        signing = iota // with the key releaseKey is set to
+	signedByOther                // with some other key
+	unsigned                     // not at all, publishing no checksums.txt.sig
+)
+
+// releaseServer serves a release list plus each release's archive,
+// checksums.txt, and checksums.txt.sig, and points releaseKey at the key
+// it signs with. corrupt serves a checksum that does not match.
+func releaseServer(t *testing.T, releases []Release, archive []byte, corrupt bool, sign signing) *httptest.Server {
+	t.Helper()
+	sum := sha256.Sum256(archive)
+	hexSum := hex.EncodeToString(sum[:])
+	if corrupt {
+		hexSum = strings.Repeat("0", 64)
+	}
+
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	if sign == signedByOther {
+		_, priv, _ = ed25519.GenerateKey(rand.Reader)
+	}
+	sums := func(ver string) string {
+		return fmt.Sprintf("%s  %s\n", hexSum, archiveName(ver, runtime.GOOS, runtime.GOARCH))
+	}
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/releases":
+			list := make([]Release, len(releases))
+			for i, rel := range releases {
+				name := archiveName(rel.Version(), runtime.GOOS, runtime.GOARCH)
+				rel.Assets = []ReleaseAsset{
+					{Name: name, URL: srv.URL + "/dl/" + name},
+					{Name: checksumsAsset, URL: srv.URL + "/dl/" + rel.TagName + "/" + checksumsAsset},
+				}
+				if sign != unsigned {
+					rel.Assets = append(rel.Assets, ReleaseAsset{Name: signatureAsset, URL: srv.URL + "/dl/" + rel.TagName + "/" + signatureAsset})
+				}
+				list[i] = rel
+			}
+			json.NewEncoder(w).Encode(list)
+		case strings.HasSuffix(r.URL.Path, signatureAsset):
+			ver := strings.TrimPrefix(strings.Split(strings.TrimPrefix(r.URL.Path, "/dl/"), "/")[0], "v")
+			fmt.Fprintln(w, base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(sums(ver)))))
+		case strings.HasSuffix(r.URL.Path, checksumsAsset):
+			ver := strings.TrimPrefix(strings.Split(strings.TrimPrefix(r.URL.Path, "/dl/"), "/")[0], "v")
+			fmt.Fprint(w, sums(ver))
+		case strings.HasPrefix(r.URL.Path, "/dl/"):
+			w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	orig, origKey := releasesURL, releaseKey
+	releasesURL = srv.URL + "/releases"
+	releaseKey = base64.StdEncoding.EncodeToString(pub)
+	t.Cleanup(func() { releasesURL, releaseKey = orig, origKey })
+	return srv
+}
+
+func fakeExe(t *testing.T) string {
+	t.Helper()
+	exe := filepath.Join(t.TempDir(), "human")
+	if err := os.WriteFile(exe, []byte("old binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return exe
+}
+
+func TestLatestReleaseChannels(t *testing.T) {
+	releaseServer(t, []Release{
+		{TagName: "v0.9.0"},
+		{TagName: "v1.0.0-rc.1", Prerelease: true},
+		{TagName: "v0.8.0"},
+		{TagName: "v2.0.0", Draft: true},
+	}, nil, false, signed)
+
+	stable, err := LatestRelease(ChannelStable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stable.Version() != "0.9.0" {
+		t.Errorf("stable = %s, want 0.9.0", stable.Version())
+	}
+
+	pre, err := LatestRelease(ChannelPrerelease)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pre.Version() != "1.0.0-rc.1" {
+		t.Errorf("prerelease = %s, want 1.0.0-rc.1", pre.Version())
+	}
+}
+
+func TestSelfUpdateReplacesBinary(t *testing.T) {
+	releaseServer(t, []Release{{TagName: "v99.0.0"}}, tarGz(t, "new binary"), false, signed)
+	exe := fakeExe(t)
+
+	var out bytes.Buffer
+	updated, err := SelfUpdate(&out, SelfUpdateOptions{Exe: exe})
+	if err != nil {
+		t.Fatalf("SelfUpdate: %v\n%s", err, out.String())
+	}
+	if !updated {
+		t.Fatal("expected binary to be updated")
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "new binary" {
+		t.Errorf("binary = %q, want %q", data, "new binary")
+	}
+	if info, _ := os.Stat(exe); info.Mode().Perm()&0o111 == 0 {
+		t.Errorf("binary is not executable: %v", info.Mode())
+	}
+	if !strings.Contains(out.String(), "Signature verified") || !strings.Contains(out.String(), "Checksum verified") {
+		t.Errorf("expected signature and checksum confirmations, got:\n%s", out.String())
+	}
+}
+
+func TestSelfUpdateChecksumMismatch(t *testing.T) {
+	releaseServer(t, []Release{{TagName: "v99.0.0"}}, tarGz(t, "tampered"), true, signed)
+	exe := fakeExe(t)
+
+	var out bytes.Buffer
+	_, err := SelfUpdate(&out, SelfUpdateOptions{Exe: exe})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "old binary" {
+		t.Errorf("binary was replaced despite bad checksum: %q", data)
+	}
+}
+
+func TestSelfUpdateSignature(t *testing.T) {
+	tests := []struct {
+		sign signing
+		want string
+	}{
+		// A checksum matching the tampered archive, signed by anyone else.
+		{signedByOther, "signature does not match"},
+		{unsigned, "no " + signatureAsset},
+	}
+	for _, tt := range tests {
+		releaseServer(t, []Release{{TagName: "v99.0.0"}}, tarGz(t, "tampered"), false, tt.sign)
+		exe := fakeExe(t)
+
+		_, err := SelfUpdate(&bytes.Buffer{}, SelfUpdateOptions{Exe: exe})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected %q, got %v", tt.want, err)
+		}
+		data, _ := os.ReadFile(exe)
+		if string(data) != "old binary" {
+			t.Errorf("binary was replaced despite an untrusted checksums.txt: %q", data)
+		}
+	}
+}
+
+func TestReleaseKey(t *testing.T) {
+	key, err := base64.StdEncoding.DecodeString(releaseKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		t.Fatalf("releaseKey is not a base64 ed25519 public key: %d bytes, %v", len(key), err)
+	}
+}
+
+func TestSelfUpdateAlreadyLatest(t *testing.T) {
+	releaseServer(t, []Release{{TagName: "v" + version.Version}}, tarGz(t, "same"), false, signed)
+	exe := fakeExe(t)
+
+	var out bytes.Buffer
+	updated, err := SelfUpdate(&out, SelfUpdateOptions{Exe: exe})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated {
+		t.Error("expected no update when already on the latest version")
+	}
+	if !strings.Contains(out.String(), "latest version") {
+		t.Errorf("expected up-to-date message, got:\n%s", out.String())
+	}
+}
+
+func TestSelfUpdateCheckOnly(t *testing.T) {
+	releaseServer(t, []Release{{TagName: "v99.0.0"}}, tarGz(t, "new binary"), false, signed)
+	exe := fakeExe(t)
+
+	var out bytes.Buffer
+	updated, err := SelfUpdate(&out, SelfUpdateOptions{Exe: exe, CheckOnly: true})
+	if err != nil || updated {
+		t.Fatalf("SelfUpdate(--check) = %v, %v", updated, err)
+	}
+	if !strings.Contains(out.String(), "99.0.0") {
+		t.Errorf("expected available version in output, got:\n%s", out.String())
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "old binary" {
+		t.Errorf("--check replaced the binary")
+	}
+}
+
+func TestSelfUpdateUnknownChannel(t *testing.T) {
+	if _, err := SelfUpdate(&bytes.Buffer{}, SelfUpdateOptions{Channel: "nightly"}); err == nil {
+		t.Error("expected error for unknown channel")
+	}
+}
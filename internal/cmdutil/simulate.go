@@ -0,0 +1,69 @@
+package cmdutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/simulate"
+)
+
+// PrintSimulation prints a workflow's dry run: each step and what it would
+// do, grouped by the delay before it runs, then what the workflow does in
+// all and the steps the IR can't carry out. Returns true if there are any.
+func PrintSimulation(sim *simulate.Simulation) bool {
+	fmt.Printf("Simulating: when %s\n", sim.Trigger)
+	if sim.Model != nil {
+		fmt.Printf("  (about a %s record)\n", sim.Model.Name)
+	}
+
+	after := ""
+	for i, st := range sim.Steps {
+		if i == 0 || st.After != after {
+			after = st.After
+			if after == "" {
+				fmt.Println("\n  Right away")
+			} else {
+				fmt.Printf("\n  After %s\n", after)
+			}
+		}
+		fmt.Printf("    %d. %s\n", i+1, st.Text)
+		switch {
+		case st.Problem != "":
+			fmt.Printf("       %s\n", cli.Warn(st.Problem))
+		case st.Detail != "":
+			fmt.Printf("       → %s\n", st.Detail)
+		}
+		if st.Starts != "" {
+			fmt.Printf("       → which starts the workflow: when %s\n", st.Starts)
+		}
+	}
+
+	fmt.Println("\n  Summary")
+	for _, row := range []struct {
+		label string
+		items []string
+	}{
+		{"Emails sent", sim.Effects(simulate.Email)},
+		{"Messages sent", sim.Effects(simulate.Message)},
+		{"Notifications", sim.Effects(simulate.Notify)},
+		{"Records created", sim.Effects(simulate.Create)},
+		{"Records updated", sim.Effects(simulate.Update)},
+		{"Records deleted", sim.Effects(simulate.Delete)},
+		{"Policies assigned", sim.Effects(simulate.Policy)},
+		{"Integrations used", sim.Services()},
+	} {
+		if len(row.items) > 0 {
+			fmt.Printf("    %-18s %s\n", row.label+":", strings.Join(row.items, ", "))
+		}
+	}
+
+	problems := sim.Problems()
+	fmt.Println()
+	if len(problems) == 0 {
+		cli.Println(cli.Success(fmt.Sprintf("All %d step%s can be carried out.", len(sim.Steps), Plural(len(sim.Steps)))))
+		return false
+	}
+	cli.Println(cli.Warn(fmt.Sprintf("%d step%s can't be carried out as written.", len(problems), Plural(len(problems)))))
+	return true
+}
@@ -0,0 +1,48 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/syntax"
+)
+
+// RunSnippet prints the snippet a query names, ready to paste into a
+// .human file. A query naming no snippet gets the closest match, with the
+// others noted on stderr; an empty query lists every snippet.
+func RunSnippet(out io.Writer, query string) error {
+	if query == "" {
+		listSnippets(out)
+		return nil
+	}
+
+	matches, exact := syntax.LookupSnippet(query)
+	if len(matches) == 0 {
+		return fmt.Errorf("no snippet matches %q. Run 'human snippet' to list them", query)
+	}
+	if !exact {
+		note := fmt.Sprintf("Closest match for %q: %s", query, matches[0].ID)
+		if len(matches) > 1 {
+			var others []string
+			for _, m := range matches[1:min(len(matches), 5)] {
+				others = append(others, m.ID)
+			}
+			note += " (also: " + strings.Join(others, ", ") + ")"
+		}
+		fmt.Fprintln(cli.ErrOutput, cli.Muted(note))
+	}
+	fmt.Fprintln(out, matches[0].Code)
+	return nil
+}
+
+// listSnippets lists every snippet's ID and description.
+func listSnippets(out io.Writer) {
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%s\n\n", cli.Heading("Snippets (human snippet <id>):"))
+	for _, s := range syntax.Snippets() {
+		fmt.Fprintf(out, "  %-42s %s\n", s.ID, cli.Muted(s.Description))
+	}
+	fmt.Fprintln(out)
+}
@@ -107,6 +107,14 @@ func renderAppFile(prog *parser.Program) string {
 		fmt.Fprintf(&b, "app %s is a %s application\n", prog.App.Name, prog.App.Platform)
 	}
 
+	// Imports stay with the app declaration
+	if len(prog.Imports) > 0 {
+		b.WriteString("\n")
+		for _, imp := range prog.Imports {
+			fmt.Fprintf(&b, "import %q\n", imp.Path)
+		}
+	}
+
 	if prog.Build != nil {
 		b.WriteString("\nbuild with:\n")
 		for _, s := range prog.Build.Statements {
@@ -0,0 +1,362 @@
+package cmdutil
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/quality"
+)
+
+// testStack is a generated stack whose tests `human test` runs with
+// coverage, and how to read the report they leave.
+type testStack struct {
+	name string
+	dir  string // relative to the output directory
+	// marker is a file the stack has only when it was generated with
+	// tests to run.
+	marker string
+	run    [][]string
+	report string // coverage report, relative to dir
+}
+
+var testStacks = []testStack{
+	{name: "node", dir: "node", marker: "package.json",
+		run:    [][]string{{"npm", "test", "--", "--coverage", "--coverageReporters=json"}},
+		report: filepath.Join("coverage", "coverage-final.json")},
+	{name: "react", dir: "react", marker: "jest.config.cjs",
+		run:    [][]string{{"npm", "test", "--", "--coverage", "--coverageReporters=json"}},
+		report: filepath.Join("coverage", "coverage-final.json")},
+	{name: "python", dir: "python", marker: "tests",
+		run:    [][]string{{"python3", "-m", "coverage", "run", "-m", "pytest"}, {"python3", "-m", "coverage", "json", "-o", "coverage.json"}},
+		report: "coverage.json"},
+	{name: "go", dir: "go", marker: "go.mod",
+		run:    [][]string{{"go", "test", "-coverprofile=coverage.out", "./..."}},
+		report: "coverage.out"},
+}
+
+// RunTests runs the generated tests of each stack in outputDir with
+// coverage on — Jest's for Node and React, coverage.py for Python, and go
+// test's cover profile for Go — and merges the results into one summary of
+// which endpoints and pages the tests exercised. The summary goes into
+// test-coverage.json and the build report, with the endpoints no test
+// reached flagged. It returns an error if any stack's tests failed.
+func RunTests(outputDir string) error {
+	hits := quality.LineHits{}
+	var ran, failed []string
+	for _, stack := range testStacks {
+		dir := filepath.Join(outputDir, stack.dir)
+		if _, err := os.Stat(filepath.Join(dir, stack.marker)); err != nil {
+			continue
+		}
+		cli.Println(cli.Info(fmt.Sprintf("Running %s tests with coverage...", stack.name)))
+		os.Remove(filepath.Join(dir, stack.report))
+		for _, args := range stack.run {
+			if err := RunCommandSilent(dir, args[0], args[1:]...); err != nil {
+				failed = append(failed, stack.name)
+				break
+			}
+		}
+		stackHits, err := readStackCoverage(outputDir, stack)
+		if err != nil {
+			cli.Warnln(fmt.Sprintf("No coverage for %s: %v", stack.name, err))
+			continue
+		}
+		hits.Merge(stackHits)
+		ran = append(ran, stack.name)
+	}
+	if len(ran) == 0 && len(failed) == 0 {
+		return fmt.Errorf("no generated tests to run in %s", outputDir)
+	}
+
+	if len(ran) > 0 {
+		app, err := lastBuiltApp()
+		if err != nil {
+			cli.Warnln(fmt.Sprintf("Skipping the coverage summary: %v", err))
+		} else {
+			cov := quality.MapTestCoverage(app, outputDir, ran, hits)
+			printTestCoverage(cov)
+			if err := quality.WriteTestCoverage(outputDir, cov); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("tests failed in %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// RunE2ETests boots the app's docker-compose stack, runs the generated
+// Playwright specs against it, and stops the stack. With E2E_BASE_URL set,
+// it tests that deployment instead and boots nothing. Playwright and its
+// browser are installed on the first run.
+func RunE2ETests(outputDir string) error {
+	e2eDir := filepath.Join(outputDir, "e2e")
+	if _, err := os.Stat(filepath.Join(e2eDir, "playwright.config.ts")); err != nil {
+		return fmt.Errorf("no end-to-end tests in %s (they're generated for apps with pages and a web frontend)", e2eDir)
+	}
+	if _, err := os.Stat(filepath.Join(e2eDir, "node_modules")); err != nil {
+		cli.Println(cli.Info("Installing Playwright..."))
+		if err := RunCommandSilent(e2eDir, "npm", "install"); err != nil {
+			return fmt.Errorf("installing Playwright: %w", err)
+		}
+		if err := RunCommandSilent(e2eDir, "npx", "playwright", "install", "chromium"); err != nil {
+			return fmt.Errorf("installing Playwright's browser: %w", err)
+		}
+	}
+
+	if os.Getenv("E2E_BASE_URL") == "" {
+		app, err := lastBuiltApp()
+		if err != nil {
+			return err
+		}
+		baseURL := quality.E2EBaseURL(app)
+		stop, err := startApp(outputDir, baseURL)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		os.Setenv("E2E_BASE_URL", baseURL)
+	}
+
+	cli.Println(cli.Info(fmt.Sprintf("Running end-to-end tests against %s...", os.Getenv("E2E_BASE_URL"))))
+	if err := RunCommandSilent(e2eDir, "npx", "playwright", "test"); err != nil {
+		return fmt.Errorf("end-to-end tests failed (see %s)", filepath.Join(e2eDir, "playwright-report"))
+	}
+	return nil
+}
+
+// RunContractTests runs the API contract the build generated against the
+// app's backend, booted with docker compose (or, with CONTRACT_BASE_URL
+// set, the backend already serving there), and saves the run in
+// .human/contract/<backend>.json. It prints a pass/fail matrix of the
+// contract's cases against every backend with a saved run, so building
+// the same app with another backend and running the contract again shows
+// where the two differ. It returns an error if this backend failed a case.
+func RunContractTests(outputDir string) error {
+	contract, err := quality.LoadContract(outputDir)
+	if err != nil {
+		return err
+	}
+	app, err := lastBuiltApp()
+	if err != nil {
+		return err
+	}
+	backend := quality.ContractBackend(app)
+
+	baseURL := os.Getenv("CONTRACT_BASE_URL")
+	if baseURL == "" {
+		baseURL = quality.ContractBaseURL(app)
+		stop, err := startApp(outputDir, baseURL)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	cli.Println(cli.Info(fmt.Sprintf("Running %d contract cases against the %s backend at %s...", len(contract.Cases), backend, baseURL)))
+	result := quality.RunContract(contract, backend, baseURL, &http.Client{Timeout: 30 * time.Second})
+	resultsDir := filepath.Join(".human", "contract")
+	if err := quality.SaveContractResult(resultsDir, result); err != nil {
+		return err
+	}
+	printContractMatrix(contract, quality.LoadContractResults(resultsDir))
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return fmt.Errorf("the %s backend failed %d of %d contract cases", backend, len(failed), len(contract.Cases))
+	}
+	return nil
+}
+
+// startApp boots the docker-compose stack in outputDir and waits for url
+// to answer. stop takes the stack down.
+func startApp(outputDir, url string) (stop func(), err error) {
+	composeCmd, err := DetectComposeCommand()
+	if err != nil {
+		return nil, err
+	}
+	cli.Println(cli.Info("Starting the app with docker compose..."))
+	up := append(append([]string{}, composeCmd...), "up", "-d", "--build")
+	if err := RunCommandSilent(outputDir, up[0], up[1:]...); err != nil {
+		return nil, fmt.Errorf("starting the app: %w", err)
+	}
+	stop = func() {
+		cli.Println(cli.Info("Stopping the app..."))
+		down := append(append([]string{}, composeCmd...), "down")
+		RunCommandSilent(outputDir, down[0], down[1:]...)
+	}
+	if err := waitForURL(url, 3*time.Minute); err != nil {
+		stop()
+		return nil, err
+	}
+	return stop, nil
+}
+
+// waitForURL polls url until it answers, or timeout passes.
+func waitForURL(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := client.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 500 {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the app at %s didn't come up within %s", url, timeout)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// readStackCoverage reads the coverage report a stack's test run left.
+func readStackCoverage(outputDir string, stack testStack) (quality.LineHits, error) {
+	dir := filepath.Join(outputDir, stack.dir)
+	data, err := os.ReadFile(filepath.Join(dir, stack.report))
+	if err != nil {
+		return nil, fmt.Errorf("the test run wrote no %s", stack.report)
+	}
+	switch stack.name {
+	case "python":
+		return quality.ParseCoveragePy(data, stack.dir)
+	case "go":
+		return quality.ParseGoCoverProfile(data, goModule(dir), stack.dir)
+	}
+	absOutput, err := filepath.Abs(outputDir)
+	if err != nil {
+		return nil, err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	return quality.ParseJestCoverage(data, absOutput, absDir)
+}
+
+// goModule returns the module path declared in dir's go.mod.
+func goModule(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return ""
+}
+
+// lastBuiltApp reads the Intent IR of the most recent build, which the
+// generated code in .human/output came from.
+func lastBuiltApp() (*ir.Application, error) {
+	matches, _ := filepath.Glob(filepath.Join(".human", "intent", "*.yaml"))
+	var latest string
+	var latestMod int64
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err == nil && info.ModTime().UnixNano() > latestMod {
+			latest, latestMod = m, info.ModTime().UnixNano()
+		}
+	}
+	if latest == "" {
+		return nil, fmt.Errorf("no build found in .human/intent")
+	}
+	data, err := os.ReadFile(latest)
+	if err != nil {
+		return nil, err
+	}
+	app, err := ir.FromYAML(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", latest, err)
+	}
+	return app, nil
+}
+
+func printTestCoverage(cov *quality.TestCoverage) {
+	cli.Println()
+	cli.Println(cli.Heading("Test coverage"))
+	if ex, total := cov.Exercised("endpoint"); total > 0 {
+		cli.Println(fmt.Sprintf("  Endpoints exercised: %d/%d", ex, total))
+	}
+	if ex, total := cov.Exercised("page"); total > 0 {
+		cli.Println(fmt.Sprintf("  Pages exercised:     %d/%d", ex, total))
+	}
+	for _, cc := range cov.Untested() {
+		cli.Warnln(fmt.Sprintf("%s has no test coverage (%s, %s)", cc.Name, cc.Stack, cc.File))
+	}
+	cli.Println(cli.Muted("  Details in test-coverage.json and build-report.md"))
+}
+
+// printContractMatrix prints each contract case against each backend's
+// latest run, marking the cases the backends answered differently.
+func printContractMatrix(contract *quality.Contract, results []*quality.ContractResult) {
+	width := 0
+	for _, cc := range contract.Cases {
+		width = max(width, len(cc.ID))
+	}
+	cli.Println()
+	cli.Println(cli.Heading("API contract"))
+	header := fmt.Sprintf("  %-*s", width, "")
+	for _, r := range results {
+		header += fmt.Sprintf("  %-8s", r.Backend)
+	}
+	cli.Println(cli.Muted(header))
+
+	divergent := 0
+	for _, cc := range contract.Cases {
+		row := fmt.Sprintf("  %-*s", width, cc.ID)
+		for _, r := range results {
+			row += "  " + contractCell(r.Outcome(cc.ID))
+		}
+		if quality.ContractDivergent(cc.ID, results) {
+			divergent++
+			row += "  " + cli.Warn("differs")
+		}
+		cli.Println(row)
+	}
+
+	cli.Println()
+	for _, r := range results {
+		passed := 0
+		for _, o := range r.Outcomes {
+			if o.Passed {
+				passed++
+			}
+		}
+		summary := fmt.Sprintf("%s: %d/%d passed", r.Backend, passed, len(r.Outcomes))
+		if len(r.Failed()) > 0 {
+			cli.Println("  " + cli.Error(summary))
+		} else {
+			cli.Println("  " + cli.Success(summary))
+		}
+	}
+	if divergent > 0 {
+		cli.Warnln(fmt.Sprintf("%d cases answered differently by different backends", divergent))
+	}
+}
+
+// contractCell renders a backend's outcome for one case, padded to the
+// matrix's column width.
+func contractCell(o *quality.ContractOutcome) string {
+	switch {
+	case o == nil:
+		return cli.Muted(fmt.Sprintf("%-8s", "–"))
+	case o.Skipped:
+		return cli.Muted(fmt.Sprintf("%-8s", "skipped"))
+	case o.Passed:
+		return cli.Success(fmt.Sprintf("%-6d", o.Status))
+	case o.Status == 0:
+		return cli.Error(fmt.Sprintf("%-6s", "error"))
+	}
+	return cli.Error(fmt.Sprintf("%-6d", o.Status))
+}
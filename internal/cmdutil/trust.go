@@ -0,0 +1,223 @@
+package cmdutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/config"
+	"golang.org/x/term"
+)
+
+// TrustPrompt asks the user a yes/no question before an untrusted workspace
+// runs commands. The CLI sets it when stdin is a terminal and the REPL sets
+// its own; when nil, untrusted workspaces are refused.
+var TrustPrompt func(question string) bool
+
+// CleanEnvMode is set by the --clean-env flag.
+var CleanEnvMode bool
+
+// IsTrusted reports whether dir is a trusted workspace: it, or a directory
+// above it, was trusted with 'human trust', or HUMAN_TRUST_WORKSPACE=1 is set
+// (for CI, where the checkout is the workspace).
+func IsTrusted(dir string) bool {
+	if os.Getenv("HUMAN_TRUST_WORKSPACE") == "1" {
+		return true
+	}
+	path, err := workspacePath(dir)
+	if err != nil {
+		return false
+	}
+	settings, err := config.LoadGlobal()
+	if err != nil {
+		return false
+	}
+	for _, t := range settings.TrustedWorkspaces {
+		if path == t || strings.HasPrefix(path, t+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustWorkspace records dir as trusted in ~/.human/settings.json.
+func TrustWorkspace(dir string) (string, error) {
+	path, err := workspacePath(dir)
+	if err != nil {
+		return "", err
+	}
+	settings, err := config.LoadGlobal()
+	if err != nil {
+		return "", err
+	}
+	for _, t := range settings.TrustedWorkspaces {
+		if t == path {
+			return path, nil
+		}
+	}
+	settings.TrustedWorkspaces = append(settings.TrustedWorkspaces, path)
+	sort.Strings(settings.TrustedWorkspaces)
+	return path, config.SaveGlobal(settings)
+}
+
+// UntrustWorkspace removes dir from the trusted workspaces. It reports
+// whether dir had been trusted.
+func UntrustWorkspace(dir string) (bool, error) {
+	path, err := workspacePath(dir)
+	if err != nil {
+		return false, err
+	}
+	settings, err := config.LoadGlobal()
+	if err != nil {
+		return false, err
+	}
+	kept := settings.TrustedWorkspaces[:0]
+	for _, t := range settings.TrustedWorkspaces {
+		if t != path {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == len(settings.TrustedWorkspaces) {
+		return false, nil
+	}
+	settings.TrustedWorkspaces = kept
+	return true, config.SaveGlobal(settings)
+}
+
+// TrustedWorkspaces lists the trusted workspace paths.
+func TrustedWorkspaces() ([]string, error) {
+	settings, err := config.LoadGlobal()
+	if err != nil {
+		return nil, err
+	}
+	return settings.TrustedWorkspaces, nil
+}
+
+// RequireTrust checks that dir is trusted before action (e.g. "run the
+// generated app") executes code from it. An untrusted workspace is trusted
+// if the user confirms through TrustPrompt; otherwise RequireTrust returns
+// an error telling them how to trust it.
+func RequireTrust(dir, action string) error {
+	if IsTrusted(dir) {
+		return nil
+	}
+	path, err := workspacePath(dir)
+	if err != nil {
+		return err
+	}
+
+	if TrustPrompt != nil {
+		cli.Warnln(fmt.Sprintf("%s is not a trusted workspace.", path))
+		cli.Println("  To " + action + ", human runs commands defined by this project (npm scripts, hooks,")
+		cli.Println("  Docker and Terraform files) with your environment, including any credentials in it.")
+		if TrustPrompt("Trust this workspace?") {
+			if _, err := TrustWorkspace(path); err != nil {
+				return fmt.Errorf("recording trust: %w", err)
+			}
+			cli.Println(cli.Success("Trusted " + path))
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not a trusted workspace, so human won't %s. Review the project, then run 'human trust' (or set HUMAN_TRUST_WORKSPACE=1 in CI)", path, action)
+}
+
+// TerminalPrompt returns a TrustPrompt that reads y/n from in, or nil when
+// in is not a terminal.
+func TerminalPrompt(in *os.File, out io.Writer) func(string) bool {
+	if !term.IsTerminal(int(in.Fd())) {
+		return nil
+	}
+	reader := bufio.NewReader(in)
+	return func(question string) bool {
+		fmt.Fprintf(out, "%s (y/n): ", question)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
+}
+
+func workspacePath(dir string) (string, error) {
+	path, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return path, nil
+}
+
+// ── Scrubbed environment ──
+
+// cleanEnvKeep is the environment a scrubbed command keeps: what shells,
+// package managers, and Docker need to work, and no credentials. Entries
+// ending in "*" are prefixes.
+var cleanEnvKeep = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM", "COLORTERM", "NO_COLOR",
+	"LANG", "LANGUAGE", "LC_*", "TZ", "TMPDIR", "TMP", "TEMP", "XDG_*",
+	"HUMAN_*", "NODE_ENV", "NODE_OPTIONS", "NVM_*",
+	"GOPATH", "GOROOT", "GOCACHE", "GOMODCACHE", "GOFLAGS",
+	"VIRTUAL_ENV", "PYTHONPATH", "PYENV_*",
+	"DOCKER_HOST", "DOCKER_CONTEXT", "DOCKER_CONFIG", "COMPOSE_*",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+	"SSL_CERT_FILE", "SSL_CERT_DIR",
+}
+
+// cleanEnvKeepWindows adds what Windows programs expect to find.
+var cleanEnvKeepWindows = []string{
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT",
+	"APPDATA", "LOCALAPPDATA", "USERPROFILE", "PROGRAMFILES*", "PROGRAMDATA",
+}
+
+// CloudCredentialEnv is what Terraform deploys keep in a scrubbed
+// environment, since they need the cloud provider's credentials.
+var CloudCredentialEnv = []string{"AWS_*", "GOOGLE_*", "CLOUDSDK_*", "TF_*"}
+
+// CleanEnv reports whether commands run with a scrubbed environment:
+// --clean-env, HUMAN_CLEAN_ENV=1, or clean_env in ~/.human/settings.json.
+func CleanEnv() bool {
+	if CleanEnvMode || os.Getenv("HUMAN_CLEAN_ENV") == "1" {
+		return true
+	}
+	settings, err := config.LoadGlobal()
+	return err == nil && settings.CleanEnv
+}
+
+// CommandEnv returns the environment for a command human runs on the
+// project's behalf: the full environment, or with CleanEnv the allowlisted
+// variables plus any matching keep.
+func CommandEnv(keep ...string) []string {
+	env := os.Environ()
+	if !CleanEnv() {
+		return env
+	}
+	allow := append(append([]string{}, cleanEnvKeep...), keep...)
+	if runtime.GOOS == "windows" {
+		allow = append(allow, cleanEnvKeepWindows...)
+	}
+	return scrubEnv(env, allow)
+}
+
+// scrubEnv keeps the variables in env whose names match allow.
+func scrubEnv(env, allow []string) []string {
+	var kept []string
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if runtime.GOOS == "windows" {
+			name = strings.ToUpper(name)
+		}
+		for _, a := range allow {
+			if name == a || (strings.HasSuffix(a, "*") && strings.HasPrefix(name, strings.TrimSuffix(a, "*"))) {
+				kept = append(kept, kv)
+				break
+			}
+		}
+	}
+	return kept
+}
@@ -0,0 +1,113 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// isolateSettings points ~/.human at a temp dir and clears the trust and
+// clean-env overrides.
+func isolateSettings(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("HUMAN_TRUST_WORKSPACE", "")
+	t.Setenv("HUMAN_CLEAN_ENV", "")
+	orig := TrustPrompt
+	TrustPrompt = nil
+	t.Cleanup(func() { TrustPrompt = orig })
+}
+
+func TestTrustWorkspace(t *testing.T) {
+	isolateSettings(t)
+	root := t.TempDir()
+	sub := filepath.Join(root, "app")
+	os.Mkdir(sub, 0755)
+
+	if IsTrusted(sub) {
+		t.Fatal("new workspace should not be trusted")
+	}
+	if _, err := TrustWorkspace(root); err != nil {
+		t.Fatal(err)
+	}
+	if !IsTrusted(sub) {
+		t.Error("directory under a trusted workspace should be trusted")
+	}
+	if IsTrusted(root + "-other") {
+		t.Error("sibling with a shared prefix should not be trusted")
+	}
+
+	removed, err := UntrustWorkspace(root)
+	if err != nil || !removed {
+		t.Fatalf("UntrustWorkspace = %v, %v", removed, err)
+	}
+	if IsTrusted(sub) {
+		t.Error("workspace still trusted after revoking")
+	}
+}
+
+func TestRequireTrust(t *testing.T) {
+	isolateSettings(t)
+	dir := t.TempDir()
+
+	err := RequireTrust(dir, "run the generated app")
+	if err == nil || !strings.Contains(err.Error(), "human trust") {
+		t.Fatalf("untrusted without a prompt: err = %v, want refusal", err)
+	}
+
+	TrustPrompt = func(string) bool { return false }
+	if err := RequireTrust(dir, "run the generated app"); err == nil {
+		t.Fatal("declined prompt should refuse")
+	}
+
+	TrustPrompt = func(string) bool { return true }
+	if err := RequireTrust(dir, "run the generated app"); err != nil {
+		t.Fatalf("accepted prompt: %v", err)
+	}
+	TrustPrompt = nil
+	if err := RequireTrust(dir, "run the generated app"); err != nil {
+		t.Errorf("trust was not recorded: %v", err)
+	}
+}
+
+func TestRequireTrustFromEnvironment(t *testing.T) {
+	isolateSettings(t)
+	t.Setenv("HUMAN_TRUST_WORKSPACE", "1")
+	if err := RequireTrust(t.TempDir(), "deploy it"); err != nil {
+		t.Errorf("HUMAN_TRUST_WORKSPACE=1: %v", err)
+	}
+}
+
+func TestCommandEnvScrubsCredentials(t *testing.T) {
+	isolateSettings(t)
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("GITHUB_TOKEN", "token")
+
+	if !hasEnv(CommandEnv(), "GITHUB_TOKEN") {
+		t.Fatal("environment should be inherited unless clean env is on")
+	}
+
+	t.Setenv("HUMAN_CLEAN_ENV", "1")
+	env := CommandEnv()
+	for _, name := range []string{"AWS_SECRET_ACCESS_KEY", "GITHUB_TOKEN"} {
+		if hasEnv(env, name) {
+			t.Errorf("clean env kept %s", name)
+		}
+	}
+	if !hasEnv(env, "PATH") || !hasEnv(env, "HOME") {
+		t.Error("clean env dropped PATH or HOME")
+	}
+	if !hasEnv(envFor("terraform"), "AWS_SECRET_ACCESS_KEY") {
+		t.Error("terraform should keep cloud credentials")
+	}
+}
+
+func hasEnv(env []string, name string) bool {
+	for _, kv := range env {
+		if strings.HasPrefix(kv, name+"=") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,350 @@
+package cmdutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/barun-bash/human/internal/build"
+	"github.com/barun-bash/human/internal/config"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/irdiff"
+	"github.com/barun-bash/human/internal/parser"
+)
+
+// watchDebounce is how long a watcher waits after a change for more:
+// editors often write a file several times, or write a temporary file and
+// rename it over the original.
+const watchDebounce = 100 * time.Millisecond
+
+// designRe matches "design dashboard from designs/dashboard.figma"; the
+// parser has already dropped the quotes around the file.
+var designRe = regexp.MustCompile(`(?i)^design\s+.+?\s+from\s+(\S+)$`)
+
+// DesignFiles returns the design files a program imports with "design
+// <name> from <file>", resolved against the project directory dir.
+func DesignFiles(prog *parser.Program, dir string) []string {
+	stmts := append([]*parser.Statement{}, prog.Statements...)
+	for _, p := range prog.Pages {
+		stmts = append(stmts, p.Statements...)
+	}
+	for _, c := range prog.Components {
+		stmts = append(stmts, c.Statements...)
+	}
+	seen := map[string]bool{}
+	var files []string
+	for _, s := range stmts {
+		m := designRe.FindStringSubmatch(strings.TrimSpace(s.Text))
+		if m == nil {
+			continue
+		}
+		path := m[1]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Watcher reports changes to a project's .human files, the files they
+// import, and their design files. It watches the directories holding them,
+// not the files, so that a file an editor saves by renaming a new one over
+// it, or a .human file added to the project, is still seen.
+type Watcher struct {
+	fs    *fsnotify.Watcher
+	files map[string]bool // watched files, absolute
+	dirs  map[string]bool // directories watched, whose .human files count
+}
+
+// NewWatcher returns a watcher watching nothing yet; see Watch.
+func NewWatcher() (*Watcher, error) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("starting file watcher: %w", err)
+	}
+	return &Watcher{fs: fw, files: map[string]bool{}, dirs: map[string]bool{}}, nil
+}
+
+// Watch sets the files to watch, replacing the previous set. Directories
+// no longer holding a watched file are dropped; a file that doesn't exist
+// yet is still watched, for when it's created.
+func (w *Watcher) Watch(files []string) error {
+	w.files = map[string]bool{}
+	dirs := map[string]bool{}
+	for _, f := range files {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return err
+		}
+		w.files[abs] = true
+		dirs[filepath.Dir(abs)] = true
+	}
+	for dir := range w.dirs {
+		if !dirs[dir] {
+			w.fs.Remove(dir)
+		}
+	}
+	for dir := range dirs {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.fs.Add(dir); err != nil {
+			return fmt.Errorf("watching %s: %w", dir, err)
+		}
+	}
+	w.dirs = dirs
+	return nil
+}
+
+// Matches reports whether a change to path is one to rebuild for: a
+// watched file, or a .human file in a watched directory.
+func (w *Watcher) Matches(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return w.files[abs] || strings.HasSuffix(abs, ".human") && w.dirs[filepath.Dir(abs)]
+}
+
+// Next waits for a change to rebuild for and returns the file changed,
+// once no more changes have come for watchDebounce. It returns false when
+// stop is closed or receives.
+func (w *Watcher) Next(stop <-chan os.Signal) (string, bool) {
+	changed := ""
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-stop:
+			return "", false
+		case <-settle:
+			return changed, true
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return "", false
+			}
+			fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		case ev, ok := <-w.fs.Events:
+			if !ok {
+				return "", false
+			}
+			if ev.Op == fsnotify.Chmod || !w.Matches(ev.Name) {
+				continue
+			}
+			if changed == "" {
+				changed = ev.Name
+			}
+			settle = time.After(watchDebounce)
+		}
+	}
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
+
+// Rebuild is what a watch-mode rebuild changed.
+type Rebuild struct {
+	Result  *ParseResult
+	Changes []irdiff.Change // IR changes since the last build; nil after a first build
+	Files   *FilePreview    // generated files added, changed, or no longer generated
+}
+
+// WatchFiles returns the files a rebuild of this result should be watched
+// for: its .human files, imported ones included, and its design files.
+func (r *Rebuild) WatchFiles() []string {
+	files := append([]string{}, r.Result.SourceFiles...)
+	if len(files) > 0 {
+		files = append(files, DesignFiles(r.Result.Prog, filepath.Dir(files[0]))...)
+	}
+	return files
+}
+
+// RebuildQuietly builds file for the environment called env, or for local
+// development when env is "", as human build does, without its progress and
+// summaries, for build --watch and deploy. It returns the result when the
+// source has errors, with the error, so a watch can keep watching the
+// files. The IR and generated files are compared with the previous build.
+func RebuildQuietly(file, env string) (*Rebuild, error) {
+	if err := RunHooks(".", HookContext{Hook: HookPreBuild, Source: file}); err != nil {
+		return nil, err
+	}
+
+	result, err := ParseAndAnalyzeEnv(file, env)
+	if err != nil {
+		return nil, err
+	}
+	rb := &Rebuild{Result: result}
+	if PrintDiagnostics(result.Errs) {
+		return rb, fmt.Errorf("%d error(s) found", len(result.Errs.Errors()))
+	}
+
+	outFile := IntentPath(file)
+	if data, err := os.ReadFile(outFile); err == nil {
+		if old, err := ir.FromYAML(string(data)); err == nil {
+			// A build asks for ports the source doesn't set; keep the ones it got.
+			if result.App.Config == nil {
+				result.App.Config = &ir.BuildConfig{}
+			}
+			if result.App.Config.Ports == (ir.PortConfig{}) && old.Config != nil {
+				result.App.Config.Ports = old.Config.Ports
+			}
+			rb.Changes = irdiff.Diff(old, result.App)
+		}
+	}
+
+	yaml, err := ir.ToYAML(result.App)
+	if err != nil {
+		return rb, fmt.Errorf("serialization error: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(outFile), 0755); err != nil {
+		return rb, err
+	}
+	if err := os.WriteFile(outFile, []byte(yaml), 0644); err != nil {
+		return rb, err
+	}
+
+	outputDir := filepath.Join(".human", "output")
+	prev, _ := readManifest(outputDir)
+	removeManifest(outputDir)
+	started := time.Now().Truncate(time.Second)
+	results, _, _, err := build.RunGeneratorsIncremental(result.App, outputDir, build.CacheDir, nil)
+	if err != nil {
+		return rb, err
+	}
+	generated := withSkipped(generatedSince(outputDir, started), results)
+
+	if err := RunHooks(".", HookContext{Hook: HookPostGenerate, Source: file, IRPath: outFile, OutputDir: outputDir}); err != nil {
+		return rb, err
+	}
+	cfg, err := config.Load(".")
+	if err != nil {
+		return rb, err
+	}
+	cur, err := writeManifest(outputDir, generated, result.SourceFiles, cfg.Signing)
+	if err != nil {
+		return rb, err
+	}
+	if prev != nil {
+		rb.Files = compareManifests(prev, cur)
+	}
+	return rb, nil
+}
+
+// compareManifests lists the generated files cur adds, changes, and no
+// longer has compared with prev.
+func compareManifests(prev, cur *Manifest) *FilePreview {
+	f := &FilePreview{}
+	for rel, sum := range cur.Files {
+		switch old, ok := prev.Files[rel]; {
+		case !ok:
+			f.Added = append(f.Added, rel)
+		case old != sum:
+			f.Changed = append(f.Changed, rel)
+		}
+	}
+	for rel := range prev.Files {
+		if _, ok := cur.Files[rel]; !ok {
+			f.Removed = append(f.Removed, rel)
+		}
+	}
+	sort.Strings(f.Added)
+	sort.Strings(f.Changed)
+	sort.Strings(f.Removed)
+	return f
+}
+
+// maxPreviewFiles is how many generated files a rebuild's preview lists
+// before summing up the rest.
+const maxPreviewFiles = 8
+
+// PrintRebuild prints what a watch-mode rebuild changed: the IR, then up
+// to maxPreviewFiles of the generated files.
+func PrintRebuild(rb *Rebuild) {
+	if len(rb.Changes) > 0 {
+		PrintChanges(rb.Changes)
+	}
+	if rb.Files == nil || rb.Files.Count() == 0 {
+		return
+	}
+	fmt.Println()
+	listed := 0
+	list := func(symbol string, files []string) {
+		for _, rel := range files {
+			if listed == maxPreviewFiles {
+				return
+			}
+			fmt.Printf("    %s %s\n", symbol, rel)
+			listed++
+		}
+	}
+	list("+", rb.Files.Added)
+	list("~", rb.Files.Changed)
+	list("-", rb.Files.Removed)
+	if n := rb.Files.Count(); n > listed {
+		fmt.Printf("    ... and %d more\n", n-listed)
+	}
+}
+
+// DevServer is the generated app's dev server, run as human run runs it,
+// so build --watch --serve can restart it after each rebuild.
+type DevServer struct {
+	Dir string // the build's output directory
+	cmd *exec.Cmd
+}
+
+// Restart stops the dev server if it's running and starts it again from
+// the build in Dir: start.sh when the build has one, else npm run dev.
+func (s *DevServer) Restart() error {
+	s.Stop()
+	name, args := "bash", []string{"start.sh"}
+	if _, err := os.Stat(filepath.Join(s.Dir, "start.sh")); err != nil {
+		if _, err := os.Stat(filepath.Join(s.Dir, "package.json")); err != nil {
+			return fmt.Errorf("no start.sh or package.json in %s to run", s.Dir)
+		}
+		name, args = "npm", []string{"run", "dev"}
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Dir = s.Dir
+	cmd.Env = envFor(name)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := startGroup(cmd); err != nil {
+		return fmt.Errorf("starting the dev server: %w", err)
+	}
+	s.cmd = cmd
+	return nil
+}
+
+// Stop stops the dev server and the processes it started, waiting up to
+// five seconds for them to exit before killing them.
+func (s *DevServer) Stop() {
+	if s.cmd == nil || s.cmd.Process == nil {
+		return
+	}
+	terminateGroup(s.cmd)
+	done := make(chan struct{})
+	go func() {
+		s.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		killGroup(s.cmd)
+		<-done
+	}
+	s.cmd = nil
+}
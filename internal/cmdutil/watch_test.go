@@ -0,0 +1,83 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/barun-bash/human/internal/parser"
+)
+
+func TestDesignFiles(t *testing.T) {
+	prog, err := parser.Parse(`app Shop is a web application
+
+design checkout from "designs/checkout.png"
+
+page Dashboard:
+  design dashboard from "designs/dashboard.figma"
+  show a list of orders`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := DesignFiles(prog, "shop")
+	want := []string{filepath.Join("shop", "designs", "checkout.png"), filepath.Join("shop", "designs", "dashboard.figma")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DesignFiles = %v, want %v", got, want)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	app := filepath.Join(dir, "app.human")
+	design := filepath.Join(dir, "designs", "home.png")
+	os.WriteFile(app, []byte("app Shop is a web application\n"), 0644)
+	os.MkdirAll(filepath.Dir(design), 0755)
+
+	w, err := NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := w.Watch([]string{app, design}); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]bool{
+		app:                                true,
+		filepath.Join(dir, "pages.human"):  true, // a file added to the project
+		design:                             true,
+		filepath.Join(dir, "notes.txt"):    false,
+		filepath.Join(dir, "designs", "x"): false,
+	} {
+		if got := w.Matches(path); got != want {
+			t.Errorf("Matches(%s) = %v, want %v", path, got, want)
+		}
+	}
+
+	// An editor saving by renaming a new file over the old one is a change
+	tmp := filepath.Join(dir, ".app.human.swp")
+	os.WriteFile(tmp, []byte("app Store is a web application\n"), 0644)
+	if err := os.Rename(tmp, app); err != nil {
+		t.Fatal(err)
+	}
+	changed, ok := w.Next(make(chan os.Signal))
+	if !ok || changed != app {
+		t.Errorf("Next = %q, %v; want %s", changed, ok, app)
+	}
+
+	stop := make(chan os.Signal, 1)
+	stop <- os.Interrupt
+	if _, ok := w.Next(stop); ok {
+		t.Error("expected Next to stop")
+	}
+}
+
+func TestCompareManifests(t *testing.T) {
+	prev := &Manifest{Files: map[string]string{"a.ts": "1", "b.ts": "1", "c.ts": "1"}}
+	cur := &Manifest{Files: map[string]string{"a.ts": "1", "b.ts": "2", "d.ts": "1"}}
+	f := compareManifests(prev, cur)
+	if !reflect.DeepEqual(f.Added, []string{"d.ts"}) || !reflect.DeepEqual(f.Changed, []string{"b.ts"}) || !reflect.DeepEqual(f.Removed, []string{"c.ts"}) {
+		t.Errorf("compareManifests = %+v", f)
+	}
+}
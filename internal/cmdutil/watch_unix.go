@@ -0,0 +1,25 @@
+//go:build !windows
+
+package cmdutil
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startGroup starts cmd in its own process group, so stopping the group
+// stops the servers it starts.
+func startGroup(cmd *exec.Cmd) error {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd.Start()
+}
+
+// terminateGroup asks cmd's process group to exit.
+func terminateGroup(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+// killGroup kills cmd's process group.
+func killGroup(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
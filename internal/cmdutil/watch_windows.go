@@ -0,0 +1,19 @@
+package cmdutil
+
+import "os/exec"
+
+// startGroup starts cmd. Windows has no process groups to signal, so
+// only cmd itself is stopped.
+func startGroup(cmd *exec.Cmd) error {
+	return cmd.Start()
+}
+
+// terminateGroup kills cmd: Windows can't ask a process to exit.
+func terminateGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+// killGroup kills cmd.
+func killGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
@@ -0,0 +1,365 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeProfileType writes the profile the account endpoints respond with:
+// the user without their password.
+func writeProfileType(b *strings.Builder, app *ir.Application) {
+	fmt.Fprintf(b, "\nexport type Profile = Omit<%s, 'password'>;\n", app.Accounts.Model)
+}
+
+// writeAccountMethod writes the service method of an endpoint the accounts
+// block added. A profile's fields are typed as the users' model types them,
+// with an optional field left empty sent as null.
+func writeAccountMethod(b *strings.Builder, ep *ir.Endpoint, app *ir.Application) {
+	fn := toCamelCase(ep.Name)
+	method := strings.ToLower(httpMethod(ep.Name))
+	path := apiPath(ep.Name)
+	switch ep.Account {
+	case ir.AccountProfile:
+		fmt.Fprintf(b, "  %s(): Observable<ApiResponse<Profile>> {\n", fn)
+		fmt.Fprintf(b, "    return this.http.%s<ApiResponse<Profile>>(`${this.baseUrl}%s`, { headers: this.getHeaders() });\n", method, path)
+	case ir.AccountUpdateProfile:
+		var fields []string
+		for _, f := range ir.ProfileFields(app) {
+			fields = append(fields, fmt.Sprintf("%s: %s", f.Name, profileFieldType(f)))
+		}
+		fmt.Fprintf(b, "  %s(params: { %s }): Observable<ApiResponse<Profile>> {\n", fn, strings.Join(fields, "; "))
+		fmt.Fprintf(b, "    return this.http.%s<ApiResponse<Profile>>(`${this.baseUrl}%s`, %s, { headers: this.getHeaders() });\n", method, path, requestBody(app))
+	default:
+		var fields []string
+		for _, p := range ep.Params {
+			fields = append(fields, p.Name+": string")
+		}
+		fmt.Fprintf(b, "  %s(params: { %s }): Observable<void> {\n", fn, strings.Join(fields, "; "))
+		if method == "delete" {
+			// HttpClient.delete takes no body argument; it goes in the options
+			fmt.Fprintf(b, "    return this.http.delete<void>(`${this.baseUrl}%s`, { headers: this.getHeaders(), body: params });\n", path)
+		} else {
+			fmt.Fprintf(b, "    return this.http.%s<void>(`${this.baseUrl}%s`, params, { headers: this.getHeaders() });\n", method, path)
+		}
+	}
+	b.WriteString("  }\n")
+}
+
+// profileFieldType returns the type a profile field is sent as.
+func profileFieldType(f *ir.DataField) string {
+	t := tsType(f.Type)
+	if f.Type == "boolean" || f.Required {
+		return t
+	}
+	return t + " | null"
+}
+
+// profileFormValue returns the expression sending a profile field from the
+// profile form's value.
+func profileFormValue(f *ir.DataField) string {
+	ref := "value." + f.Name
+	switch {
+	case f.Type == "boolean":
+		return "!!" + ref
+	case tsType(f.Type) == "number" && f.Required:
+		return "Number(" + ref + ")"
+	case tsType(f.Type) == "number":
+		return fmt.Sprintf("String(%s ?? '') ? Number(%s) : null", ref, ref)
+	case f.Required:
+		return ref + " ?? ''"
+	}
+	return ref + " || null"
+}
+
+// generateAccountPage produces the account page the accounts block added:
+// a form for the user's profile, one changing their password, and a
+// danger zone deleting their account. Deleting asks again in a dialog,
+// and only goes ahead once the user has typed DeleteConfirmation and
+// their password; they are then signed out.
+func generateAccountPage(page *ir.Page, app *ir.Application) string {
+	var b strings.Builder
+	ctx := &pageContext{app: app}
+	profile := ir.AccountEndpoint(app, ir.AccountProfile)
+	update := ir.AccountEndpoint(app, ir.AccountUpdateProfile)
+	password := ir.AccountEndpoint(app, ir.AccountChangePassword)
+	remove := ir.AccountEndpoint(app, ir.AccountDelete)
+	showsProfile := profile != nil && update != nil
+	fields := ir.ProfileFields(app)
+
+	var inputs []formInput
+	for _, f := range fields {
+		in := formInput{
+			id:        "profile-" + toKebabCase(toCamelCase(f.Name)),
+			name:      f.Name,
+			label:     ir.FieldLabel(f.Name),
+			inputType: inputType(f.Name, f),
+			required:  f.Required && f.Type != "boolean",
+		}
+		if f.Type == "boolean" {
+			in.inputType = "checkbox"
+		}
+		inputs = append(inputs, in)
+	}
+
+	core := []string{"Component", "inject", "signal"}
+	if showsProfile {
+		core = []string{"Component", "OnInit", "inject", "signal"}
+	}
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	fmt.Fprintf(&b, "import { %s } from '@angular/core';\n", strings.Join(core, ", "))
+	b.WriteString("import { CommonModule } from '@angular/common';\n")
+	b.WriteString("import { ReactiveFormsModule, FormBuilder, Validators } from '@angular/forms';\n")
+	if remove != nil {
+		b.WriteString("import { Router } from '@angular/router';\n")
+	}
+	serviceImports := []string{"ApiService", "errorMessage"}
+	if showsProfile {
+		serviceImports = append(serviceImports, "type Profile")
+	}
+	fmt.Fprintf(&b, "import { %s } from '../../services/api.service';\n", strings.Join(serviceImports, ", "))
+	if remove != nil && app.Auth != nil {
+		b.WriteString("import { AuthService } from '../../services/auth.service';\n")
+	}
+
+	b.WriteString("\n@Component({\n")
+	fmt.Fprintf(&b, "  selector: 'app-%s',\n", toKebabCase(page.Name))
+	b.WriteString("  standalone: true,\n")
+	b.WriteString("  imports: [CommonModule, ReactiveFormsModule],\n")
+	b.WriteString("  template: `\n")
+	fmt.Fprintf(&b, "    <div class=\"%s-page\">\n", toKebabCase(page.Name))
+	fmt.Fprintf(&b, "      <h1>%s</h1>\n", ir.FieldLabel(page.Name))
+
+	if showsProfile {
+		b.WriteString("      <section aria-labelledby=\"profile-title\">\n")
+		b.WriteString("        <h2 id=\"profile-title\">Profile</h2>\n")
+		b.WriteString("        @if (loadError()) {\n")
+		b.WriteString("          <p class=\"form-error\" role=\"alert\">{{ loadError() }}</p>\n")
+		b.WriteString("        }\n")
+		b.WriteString("        @if (profile()) {\n")
+		b.WriteString("          <form class=\"form\" aria-labelledby=\"profile-title\" [formGroup]=\"profileForm\" (ngSubmit)=\"saveProfile()\">\n")
+		for _, in := range inputs {
+			writeFormFieldNG(&b, "            ", in, ctx)
+		}
+		b.WriteString("            @if (profileError()) {\n")
+		b.WriteString("              <p class=\"form-error\" role=\"alert\">{{ profileError() }}</p>\n")
+		b.WriteString("            }\n")
+		b.WriteString("            @if (profileSaved()) {\n")
+		b.WriteString("              <p class=\"form-success\" role=\"status\">Your profile was saved</p>\n")
+		b.WriteString("            }\n")
+		b.WriteString("            <button type=\"submit\">Save profile</button>\n")
+		b.WriteString("          </form>\n")
+		b.WriteString("        }\n")
+		b.WriteString("      </section>\n")
+	}
+
+	if password != nil {
+		b.WriteString("      <section aria-labelledby=\"password-title\">\n")
+		b.WriteString("        <h2 id=\"password-title\">Change password</h2>\n")
+		b.WriteString("        <form class=\"form\" aria-labelledby=\"password-title\" [formGroup]=\"passwordForm\" (ngSubmit)=\"savePassword()\">\n")
+		writePasswordFieldNG(&b, "          ", "current-password", "current_password", "Current password", "current-password", false)
+		writePasswordFieldNG(&b, "          ", "new-password", "new_password", "New password", "new-password", true)
+		writePasswordFieldNG(&b, "          ", "confirm-password", "confirm_password", "Confirm new password", "new-password", true)
+		b.WriteString("          @if (passwordError()) {\n")
+		b.WriteString("            <p class=\"form-error\" role=\"alert\">{{ passwordError() }}</p>\n")
+		b.WriteString("          }\n")
+		b.WriteString("          @if (passwordChanged()) {\n")
+		b.WriteString("            <p class=\"form-success\" role=\"status\">Your password was changed</p>\n")
+		b.WriteString("          }\n")
+		b.WriteString("          <button type=\"submit\">Change password</button>\n")
+		b.WriteString("        </form>\n")
+		b.WriteString("      </section>\n")
+	}
+
+	if remove != nil {
+		b.WriteString("      <section class=\"danger-zone\" aria-labelledby=\"delete-title\">\n")
+		b.WriteString("        <h2 id=\"delete-title\">Delete account</h2>\n")
+		b.WriteString("        <p>Deleting your account removes it and everything in it for good.</p>\n")
+		b.WriteString("        <button type=\"button\" class=\"danger\" (click)=\"confirmingDelete.set(true)\">Delete account</button>\n")
+		b.WriteString("        @if (confirmingDelete()) {\n")
+		b.WriteString("          <div class=\"confirm-dialog\" role=\"alertdialog\" aria-modal=\"true\" aria-labelledby=\"delete-confirm-title\" aria-describedby=\"delete-confirm-description\" (keydown.escape)=\"cancelDelete()\">\n")
+		b.WriteString("            <h2 id=\"delete-confirm-title\">Delete your account?</h2>\n")
+		fmt.Fprintf(&b, "            <p id=\"delete-confirm-description\">This can't be undone. Type %s and enter your password to confirm.</p>\n", ir.DeleteConfirmation)
+		b.WriteString("            <form class=\"form\" aria-labelledby=\"delete-confirm-title\" [formGroup]=\"deleteForm\" (ngSubmit)=\"removeAccount()\">\n")
+		b.WriteString("              <div class=\"form-field\">\n")
+		fmt.Fprintf(&b, "                <label for=\"delete-confirmation\">Type %s to confirm</label>\n", ir.DeleteConfirmation)
+		b.WriteString("                <input id=\"delete-confirmation\" type=\"text\" formControlName=\"confirmation\" autocomplete=\"off\" autofocus required />\n")
+		b.WriteString("              </div>\n")
+		writePasswordFieldNG(&b, "              ", "delete-password", "password", "Password", "current-password", false)
+		b.WriteString("              @if (deleteError()) {\n")
+		b.WriteString("                <p class=\"form-error\" role=\"alert\">{{ deleteError() }}</p>\n")
+		b.WriteString("              }\n")
+		fmt.Fprintf(&b, "              <button type=\"submit\" class=\"danger\" [disabled]=\"deleteForm.value.confirmation !== '%s' || deleting()\">Delete my account</button>\n", ir.DeleteConfirmation)
+		b.WriteString("              <button type=\"button\" (click)=\"cancelDelete()\">Cancel</button>\n")
+		b.WriteString("            </form>\n")
+		b.WriteString("          </div>\n")
+		b.WriteString("        }\n")
+		b.WriteString("      </section>\n")
+	}
+
+	b.WriteString("    </div>\n  `\n})\n")
+
+	compName := toPascalCase(page.Name) + "Component"
+	if showsProfile {
+		fmt.Fprintf(&b, "export class %s implements OnInit {\n", compName)
+	} else {
+		fmt.Fprintf(&b, "export class %s {\n", compName)
+	}
+	b.WriteString("  private api = inject(ApiService);\n")
+	b.WriteString("  private fb = inject(FormBuilder);\n")
+	if remove != nil {
+		b.WriteString("  private router = inject(Router);\n")
+		if app.Auth != nil {
+			b.WriteString("  private auth = inject(AuthService);\n")
+		}
+	}
+
+	if showsProfile {
+		b.WriteString("\n  profile = signal<Profile | null>(null);\n")
+		b.WriteString("  loadError = signal('');\n")
+		b.WriteString("  profileError = signal('');\n")
+		b.WriteString("  profileSaved = signal(false);\n")
+		b.WriteString("  profileForm = this.fb.group({\n")
+		for i, in := range inputs {
+			fmt.Fprintf(&b, "    %s: [%s%s],\n", in.name, controlZero(fields[i]), controlValidators(in))
+		}
+		b.WriteString("  });\n")
+	}
+	if password != nil {
+		b.WriteString("\n  passwordError = signal('');\n")
+		b.WriteString("  passwordChanged = signal(false);\n")
+		b.WriteString("  passwordForm = this.fb.group({\n")
+		b.WriteString("    current_password: ['', Validators.required],\n")
+		fmt.Fprintf(&b, "    new_password: ['', [Validators.required, Validators.minLength(%d)]],\n", ir.MinPasswordLength)
+		b.WriteString("    confirm_password: ['', Validators.required],\n")
+		b.WriteString("  });\n")
+	}
+	if remove != nil {
+		b.WriteString("\n  confirmingDelete = signal(false);\n")
+		b.WriteString("  deleteError = signal('');\n")
+		b.WriteString("  deleting = signal(false);\n")
+		b.WriteString("  deleteForm = this.fb.group({\n")
+		b.WriteString("    confirmation: [''],\n")
+		b.WriteString("    password: ['', Validators.required],\n")
+		b.WriteString("  });\n")
+	}
+
+	if showsProfile {
+		b.WriteString("\n  ngOnInit() {\n")
+		fmt.Fprintf(&b, "    this.api.%s().subscribe({\n", toCamelCase(profile.Name))
+		b.WriteString("      next: (res) => this.showProfile(res.data),\n")
+		b.WriteString("      error: (err) => this.loadError.set(errorMessage(err, 'Could not load your profile')),\n")
+		b.WriteString("    });\n")
+		b.WriteString("  }\n")
+
+		var filled []string
+		for _, f := range fields {
+			filled = append(filled, fmt.Sprintf("%s: %s", f.Name, editValue("profile", f)))
+		}
+		b.WriteString("\n  private showProfile(profile: Profile) {\n")
+		b.WriteString("    this.profile.set(profile);\n")
+		fmt.Fprintf(&b, "    this.profileForm.reset({ %s });\n", strings.Join(filled, ", "))
+		b.WriteString("  }\n")
+
+		b.WriteString("\n  saveProfile() {\n")
+		b.WriteString("    if (this.profileForm.invalid) {\n")
+		b.WriteString("      this.profileForm.markAllAsTouched();\n")
+		b.WriteString("      return;\n")
+		b.WriteString("    }\n")
+		b.WriteString("    const value = this.profileForm.getRawValue();\n")
+		b.WriteString("    this.profileError.set('');\n")
+		b.WriteString("    this.profileSaved.set(false);\n")
+		fmt.Fprintf(&b, "    this.api.%s({\n", toCamelCase(update.Name))
+		for _, f := range fields {
+			fmt.Fprintf(&b, "      %s: %s,\n", f.Name, profileFormValue(f))
+		}
+		b.WriteString("    }).subscribe({\n")
+		b.WriteString("      next: (res) => {\n")
+		b.WriteString("        this.showProfile(res.data);\n")
+		b.WriteString("        this.profileSaved.set(true);\n")
+		b.WriteString("      },\n")
+		b.WriteString("      error: (err) => this.profileError.set(errorMessage(err, 'Could not save your profile')),\n")
+		b.WriteString("    });\n")
+		b.WriteString("  }\n")
+	}
+
+	if password != nil {
+		b.WriteString("\n  savePassword() {\n")
+		b.WriteString("    if (this.passwordForm.invalid) {\n")
+		b.WriteString("      this.passwordForm.markAllAsTouched();\n")
+		b.WriteString("      return;\n")
+		b.WriteString("    }\n")
+		b.WriteString("    const value = this.passwordForm.getRawValue();\n")
+		b.WriteString("    this.passwordError.set('');\n")
+		b.WriteString("    this.passwordChanged.set(false);\n")
+		b.WriteString("    if (value.new_password !== value.confirm_password) {\n")
+		b.WriteString("      this.passwordError.set('The new passwords do not match');\n")
+		b.WriteString("      return;\n")
+		b.WriteString("    }\n")
+		fmt.Fprintf(&b, "    this.api.%s({ current_password: value.current_password ?? '', new_password: value.new_password ?? '' }).subscribe({\n", toCamelCase(password.Name))
+		b.WriteString("      next: () => {\n")
+		b.WriteString("        this.passwordForm.reset();\n")
+		b.WriteString("        this.passwordChanged.set(true);\n")
+		b.WriteString("      },\n")
+		b.WriteString("      error: (err) => this.passwordError.set(errorMessage(err, 'Could not change your password')),\n")
+		b.WriteString("    });\n")
+		b.WriteString("  }\n")
+	}
+
+	if remove != nil {
+		b.WriteString("\n  cancelDelete() {\n")
+		b.WriteString("    this.confirmingDelete.set(false);\n")
+		b.WriteString("    this.deleteForm.reset();\n")
+		b.WriteString("    this.deleteError.set('');\n")
+		b.WriteString("  }\n")
+		b.WriteString("\n  removeAccount() {\n")
+		fmt.Fprintf(&b, "    if (this.deleteForm.value.confirmation !== '%s' || this.deleteForm.invalid) return;\n", ir.DeleteConfirmation)
+		b.WriteString("    this.deleteError.set('');\n")
+		b.WriteString("    this.deleting.set(true);\n")
+		fmt.Fprintf(&b, "    this.api.%s({ password: this.deleteForm.value.password ?? '' }).subscribe({\n", toCamelCase(remove.Name))
+		b.WriteString("      next: () => {\n")
+		if app.Auth != nil {
+			b.WriteString("        this.auth.logout();\n")
+		} else {
+			b.WriteString("        localStorage.removeItem('token');\n")
+		}
+		b.WriteString("        this.router.navigate(['/'], { replaceUrl: true });\n")
+		b.WriteString("      },\n")
+		b.WriteString("      error: (err) => {\n")
+		b.WriteString("        this.deleteError.set(errorMessage(err, 'Could not delete your account'));\n")
+		b.WriteString("        this.deleting.set(false);\n")
+		b.WriteString("      },\n")
+		b.WriteString("    });\n")
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// controlZero returns the value a profile form's control for f holds
+// before the profile loads.
+func controlZero(f *ir.DataField) string {
+	switch {
+	case f.Type == "boolean":
+		return "false"
+	case tsType(f.Type) == "number":
+		return "0"
+	}
+	return "''"
+}
+
+// writePasswordFieldNG renders a password input with its label, filled in
+// by password managers as autocomplete says. A new password must be
+// MinPasswordLength characters long.
+func writePasswordFieldNG(b *strings.Builder, indent, id, name, label, autocomplete string, isNew bool) {
+	fmt.Fprintf(b, "%s<div class=\"form-field\">\n", indent)
+	fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, id, label)
+	minLength := ""
+	if isNew {
+		minLength = fmt.Sprintf(" minlength=\"%d\"", ir.MinPasswordLength)
+	}
+	fmt.Fprintf(b, "%s  <input id=\"%s\" type=\"password\" formControlName=\"%s\" autocomplete=\"%s\"%s required />\n", indent, id, name, autocomplete, minLength)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
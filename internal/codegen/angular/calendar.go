@@ -0,0 +1,53 @@
+package angular
+
+import "strings"
+
+// writeCalendarMethods appends the calendar feed URLs to ApiService. The
+// feeds are fetched by the browser or a calendar app, not by HttpClient.
+func writeCalendarMethods(b *strings.Builder) {
+	b.WriteString(`
+  // calendarUrl returns a calendar's ICS feed, or one record's .ics download.
+  calendarUrl(feed: string, id?: string): string {
+    const path = id ? ` + "`${feed}/${encodeURIComponent(id)}`" + ` : feed;
+    return new URL(` + "`${this.baseUrl}/api/calendar/${path}.ics`" + `, window.location.href).href;
+  }
+`)
+}
+
+// generateAddToCalendar produces
+// src/app/components/add-to-calendar/add-to-calendar.component.ts. Given a
+// record id it downloads that record's .ics file; without one it
+// subscribes to the whole feed through a webcal:// link.
+func generateAddToCalendar() string {
+	return `// Generated by Human compiler — do not edit
+
+import { Component, Input, inject } from '@angular/core';
+import { ApiService } from '../../services/api.service';
+
+@Component({
+  selector: 'app-add-to-calendar',
+  standalone: true,
+  template: ` + "`" + `
+    @if (id) {
+      <a class="add-to-calendar" [href]="url" download>Add to calendar</a>
+    } @else {
+      <a class="add-to-calendar" [href]="subscribeUrl">Subscribe to calendar</a>
+    }
+  ` + "`" + `
+})
+export class AddToCalendarComponent {
+  private api = inject(ApiService);
+
+  @Input({ required: true }) feed = '';
+  @Input() id?: string;
+
+  get url(): string {
+    return this.api.calendarUrl(this.feed, this.id);
+  }
+
+  get subscribeUrl(): string {
+    return this.url.replace(/^https?:/, 'webcal:');
+  }
+}
+`
+}
@@ -0,0 +1,150 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeChartMethods appends the chart endpoint to ApiService.
+func writeChartMethods(b *strings.Builder) {
+	b.WriteString(`
+  // chart loads a chart's points from its aggregate endpoint.
+  chart(source: string): Observable<ApiResponse<ChartPoint[]>> {
+    return this.http.get<ApiResponse<ChartPoint[]>>(` + "`${this.baseUrl}/api/charts/${source}`" + `, { headers: this.getHeaders() });
+  }
+`)
+}
+
+// generateDataChart produces
+// src/app/components/data-chart/data-chart.component.ts: an ngx-charts bar,
+// line, or pie chart of the points an aggregate endpoint returns, drawn in
+// the theme's primary color.
+func generateDataChart() string {
+	return `// Generated by Human compiler — do not edit
+
+import { Component, Input, OnInit, inject } from '@angular/core';
+import { Color, NgxChartsModule, ScaleType } from '@swimlane/ngx-charts';
+import { ApiService, ChartPoint } from '../../services/api.service';
+
+const PALETTE = ['#6366f1', '#22c55e', '#f59e0b', '#ef4444', '#06b6d4', '#a855f7', '#ec4899', '#84cc16'];
+
+@Component({
+  selector: 'app-data-chart',
+  standalone: true,
+  imports: [NgxChartsModule],
+  template: ` + "`" + `
+    <figure class="chart">
+      <figcaption class="chart-title">{{ title }}</figcaption>
+      @if (loading) {
+        <p class="chart-status">Loading...</p>
+      } @else if (error) {
+        <p class="chart-status" role="alert">{{ error }}</p>
+      } @else if (points.length === 0) {
+        <p class="chart-status">No data yet</p>
+      } @else {
+        <div class="chart-canvas" style="height: 300px">
+          @switch (kind) {
+            @case ('line') {
+              <ngx-charts-line-chart [results]="series" [scheme]="scheme" [xAxis]="true" [yAxis]="true" />
+            }
+            @case ('pie') {
+              <ngx-charts-pie-chart [results]="points" [scheme]="scheme" [labels]="true" />
+            }
+            @default {
+              <ngx-charts-bar-vertical [results]="points" [scheme]="scheme" [xAxis]="true" [yAxis]="true" />
+            }
+          }
+        </div>
+      }
+    </figure>
+  ` + "`" + `
+})
+export class DataChartComponent implements OnInit {
+  private api = inject(ApiService);
+
+  @Input({ required: true }) kind: 'bar' | 'line' | 'pie' = 'bar';
+  @Input({ required: true }) title = '';
+  @Input({ required: true }) source = '';
+
+  points: { name: string; value: number }[] = [];
+  loading = true;
+  error = '';
+
+  // The theme's primary color leads, for bars, lines, and the first slice.
+  scheme: Color = {
+    name: 'theme',
+    selectable: false,
+    group: ScaleType.Ordinal,
+    domain: [
+      getComputedStyle(document.documentElement).getPropertyValue('--color-primary').trim() || PALETTE[0],
+      ...PALETTE.slice(1),
+    ],
+  };
+
+  get series() {
+    return [{ name: this.title, series: this.points }];
+  }
+
+  ngOnInit() {
+    this.api.chart(this.source).subscribe({
+      next: (res) => {
+        if (res.error) {
+          this.error = res.error;
+        } else {
+          this.points = (res.data ?? []).map((p: ChartPoint) => ({ name: p.label, value: p.value }));
+        }
+        this.loading = false;
+      },
+      error: () => {
+        this.error = 'Could not load the chart';
+        this.loading = false;
+      },
+    });
+  }
+}
+`
+}
+
+// chartAttr escapes a chart title for an attribute value inside the
+// component's template literal.
+var chartAttr = strings.NewReplacer("&", "&amp;", "\"", "&quot;", "<", "&lt;", ">", "&gt;", "{", "&#123;", "}", "&#125;", "`", "&#96;", "$", "&#36;")
+
+// writeChartNG emits the chart a "show a bar chart of ..." action draws.
+func writeChartNG(b *strings.Builder, c *ir.Chart, indent string) {
+	fmt.Fprintf(b, "%s<app-data-chart kind=\"%s\" title=\"%s\" source=\"%s\" />\n", indent, c.Kind, chartAttr.Replace(c.Title), c.Slug)
+}
+
+// pageHasChart reports whether the page draws a chart from an aggregate
+// endpoint.
+func pageHasChart(page *ir.Page, app *ir.Application) bool {
+	for _, a := range page.Content {
+		if ir.ChartFor(app, a) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReports reports whether any endpoint serves an aggregate.
+func hasReports(app *ir.Application) bool {
+	for _, ep := range app.APIs {
+		if ep.Aggregate != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// writeReportType declares the rows report endpoints return.
+func writeReportType(b *strings.Builder) {
+	b.WriteString(`
+// ReportRow is one group of a report: its label and the count, sum,
+// average, minimum, or maximum of its records.
+export interface ReportRow {
+  label: string;
+  value: number;
+}
+`)
+}
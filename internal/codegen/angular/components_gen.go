@@ -17,11 +17,38 @@ type pageContext struct {
 	props           map[string]string // component props: name → type
 	hasSuccessState bool
 	hasErrorState   bool
-	isComponent     bool              // true when generating a component (not a page)
-	needsFormState  bool              // true when a modal/form toggle is needed
+	isComponent     bool            // true when generating a component (not a page)
+	needsFormState  bool            // true when a modal/form toggle is needed
+	table           *ir.Table       // the page's "show posts in a table", if any
+	query           *ir.ListQuery   // the view of the list the page's URL holds, if any
+	reorder         *ir.Reorder     // the list the page lets users drag, if any
+	scroll          bool            // whether the list loads more records as the user nears its end
+	remember        bool            // whether the page restores its list as the user left it
+	newestFirst     bool            // whether new records go at the top of the list
+	deleteEp        *ir.Endpoint    // endpoint each item's delete button calls, if any
+	deleteLabel     string          // label of that button
+	tooltips        []*ir.Tooltip   // tooltips shown over the listed records or the component
+	panel           *ir.DetailPanel // panel clicking a listed record opens, if any
+	itemPage        string          // page clicking a listed record navigates to, if any
+	itemLink        *ir.Action      // the interaction saying so
+	record          *ir.DetailRoute // the record the page's URL names, if any
+	edit            *ir.EditForm    // the form editing that record, if any
+	importForm      *ir.ImportForm  // the form uploading a file of records to import, if any
+	timeHelpers     map[string]bool // time helpers the page uses from the API service
+	formatHelpers   map[string]bool // number helpers the page uses
+	loadError       string          // message shown when loading the page's data fails, if it loads any
+	retry           string          // statement the load error's button runs
+	itemFields      []string        // fields each listed record shows, shaping its loading skeleton
+	responsive      *ir.Responsive  // how the page adapts to narrower screens, if it declares so
+	fieldErrors     bool            // whether the page validates its form, showing why an input is invalid
+	ids             map[string]int  // element ids given out on the page, by base
 }
 
 func generatePage(page *ir.Page, app *ir.Application) string {
+	if ir.IsAccountPage(app, page) {
+		return generateAccountPage(page, app)
+	}
+
 	var b strings.Builder
 
 	modelName, varName, itemVar := detectPageModel(page, app)
@@ -34,6 +61,7 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	needsForm := false
 	needsFileUpload := false
 	var formFields []string
+	formText := ""
 	needsSuccess := false
 	needsError := false
 
@@ -49,9 +77,22 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			if strings.Contains(lower, "opens a form") || strings.Contains(lower, "open a form") {
 				needsFormState = true
 			}
+			if kb, ok := ir.ParseKeyBinding(a.Text); ok {
+				switch kb.Action {
+				case "open-form":
+					needsFormState = true
+				case "navigate":
+					needsRouter = true
+				}
+			}
 		case "query":
 			needsDataState = true
 			needsEffect = true
+		case "display":
+			if ir.IsTable(a) {
+				needsDataState = true
+				needsEffect = true
+			}
 		case "loop":
 			needsDataState = true
 			if modelName != "" {
@@ -61,10 +102,11 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			if strings.Contains(lower, "button") && (strings.Contains(lower, "create") || strings.Contains(lower, "new") || strings.Contains(lower, "add")) {
 				needsFormState = true
 			}
-			if strings.Contains(lower, "form") {
+			if strings.Contains(lower, "form") && !ir.IsImportForm(a) {
 				needsForm = true
 				if len(formFields) == 0 {
 					formFields = extractFormFields(lower, &pageContext{app: app})
+					formText = lower
 				}
 			}
 			if strings.Contains(lower, "file") || strings.Contains(lower, "upload") {
@@ -83,6 +125,15 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 		}
 	}
 
+	// The form editing the page's record is the page's form, unless
+	// another comes first
+	edit := ir.EditFormFor(app, page)
+	if edit != nil && (formText == "" || formText == strings.ToLower(edit.Form.Text)) {
+		needsForm, formText = true, strings.ToLower(edit.Form.Text)
+	} else {
+		edit = nil
+	}
+
 	ctx := &pageContext{
 		app:             app,
 		modelName:       modelName,
@@ -91,32 +142,145 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 		hasSuccessState: needsSuccess,
 		hasErrorState:   needsError,
 		needsFormState:  needsFormState,
+		table:           ir.TableFor(app, page, modelName),
+		reorder:         pageReorder(page, app, modelName),
+		record:          ir.DetailRouteFor(app, page.Name),
+		edit:            edit,
+		importForm:      ir.ImportFormFor(app, page),
+		responsive:      ir.ResponsiveFor(page),
+		fieldErrors:     needsForm,
+	}
+	keyBindings := ir.PageKeyBindings(page)
+	if ctx.table != nil && ctx.table.RowPage != "" {
+		needsRouter = true
+	}
+	var listEp *ir.Endpoint
+	if needsEffect && modelName != "" {
+		listEp = findListEndpoint(app, modelName)
+	}
+	ctx.scroll = pageScrolls(page, app, modelName, listEp)
+	if needsDataState {
+		ctx.query = ir.ListQueryFor(app, page, modelName, listEp)
 	}
+	if ctx.query != nil {
+		needsRouter = true
+	}
+	paged := ctx.query != nil && ctx.query.Paged
+	ctx.newestFirst = ir.ListsNewestFirst(app, listEp, modelName)
+	ctx.remember = needsEffect && ir.RemembersView(page)
+	if needsDataState {
+		ctx.deleteEp, ctx.deleteLabel = pageDelete(page, app, modelName)
+		ctx.tooltips = pageTooltips(page, app, modelName)
+		ctx.panel = pagePanel(page, app, modelName)
+		if ctx.table == nil {
+			ctx.itemPage, ctx.itemLink = ir.ItemLinkFor(app, page, modelName)
+		}
+	}
+	if ctx.itemPage != "" {
+		needsRouter = true
+	}
+	if needsEffect {
+		subject := varName
+		if subject == "" {
+			subject = "data"
+		}
+		ctx.loadError = ir.LoadErrorMessage(page, strings.ToLower(ir.FieldLabel(subject)))
+		ctx.retry = "load()"
+	}
+	if ctx.record != nil {
+		if ctx.loadError == "" {
+			ctx.loadError = ir.LoadErrorMessage(page, "the "+strings.ToLower(ir.FieldLabel(ctx.record.Model)))
+			ctx.retry = "load" + ctx.record.Model + "()"
+		} else {
+			ctx.retry += "; load" + ctx.record.Model + "()"
+		}
+	}
+	needsApi := needsDataState || needsEffect || ctx.record != nil || ctx.importForm != nil
 
 	// Imports
 	coreImports := []string{"Component", "OnInit", "signal", "inject"}
+	if ctx.query != nil {
+		coreImports = append(coreImports, "computed")
+	}
+	if ctx.edit != nil || ctx.remember {
+		coreImports = append(coreImports, "effect")
+	}
+	if len(keyBindings) > 0 || ctx.remember {
+		coreImports = append(coreImports, "HostListener")
+	}
+	if ctx.scroll {
+		coreImports = append(coreImports, "OnDestroy")
+	}
+	if ctx.scroll || needsFormState {
+		coreImports = append(coreImports, "ViewChild", "ElementRef")
+	}
 	b.WriteString(fmt.Sprintf("import { %s } from '@angular/core';\n", strings.Join(coreImports, ", ")))
 	b.WriteString("import { CommonModule } from '@angular/common';\n")
-	if needsRouter {
+	if ctx.query != nil {
+		b.WriteString("import { toSignal } from '@angular/core/rxjs-interop';\n")
+	}
+	if ctx.remember && paged {
+		b.WriteString("import { skip } from 'rxjs';\n")
+	}
+	if needsRouter && (ctx.record != nil || ctx.query != nil) {
+		b.WriteString("import { RouterModule, Router, ActivatedRoute } from '@angular/router';\n")
+	} else if needsRouter {
 		b.WriteString("import { RouterModule, Router } from '@angular/router';\n")
+	} else if ctx.record != nil {
+		b.WriteString("import { ActivatedRoute } from '@angular/router';\n")
 	}
+	var formControls []formInput
 	if needsForm {
-		b.WriteString("import { ReactiveFormsModule, FormBuilder, FormGroup } from '@angular/forms';\n")
+		isLogin := strings.Contains(formText, "login") || strings.Contains(formText, "sign in") || strings.Contains(formText, "signin")
+		if ctx.edit != nil {
+			formControls = editInputs(ctx.edit)
+		} else {
+			formControls = formInputs("", formFields, isLogin, formModel(formText, ctx))
+		}
+		validated := false
+		for _, in := range formControls {
+			validated = validated || controlValidators(in) != ""
+		}
+		if validated {
+			b.WriteString("import { ReactiveFormsModule, FormBuilder, FormGroup, Validators } from '@angular/forms';\n")
+		} else {
+			b.WriteString("import { ReactiveFormsModule, FormBuilder, FormGroup } from '@angular/forms';\n")
+		}
+	}
+	if ctx.reorder != nil {
+		b.WriteString("import { CdkDrag, CdkDragDrop, CdkDropList, moveItemInArray } from '@angular/cdk/drag-drop';\n")
 	}
-	if needsDataState || needsEffect {
+	if needsApi && ctx.record != nil {
+		b.WriteString("import { HttpClient, HttpErrorResponse } from '@angular/common/http';\n")
+	} else if needsApi {
 		b.WriteString("import { HttpClient } from '@angular/common/http';\n")
-		b.WriteString("import { ApiService } from '../../services/api.service';\n")
 	}
+	if needsApi {
+		serviceImports := []string{"ApiService"}
+		if ctx.loadError != "" || ctx.importForm != nil {
+			serviceImports = append(serviceImports, "errorMessage")
+		}
+		if ctx.importForm != nil {
+			serviceImports = append(serviceImports, "describeImport", "type ImportSummary")
+		}
+		if ctx.edit != nil && ctx.edit.Versioned {
+			serviceImports = append(serviceImports, "conflictingRecord")
+		}
+		fmt.Fprintf(&b, "import { %s } from '../../services/api.service';\n", strings.Join(serviceImports, ", "))
+	}
+	var types []string
 	if modelName != "" {
-		fmt.Fprintf(&b, "import type { %s } from '../../models/types';\n", modelName)
+		types = append(types, modelName)
+	}
+	if ctx.record != nil && ctx.record.Model != modelName {
+		types = append(types, ctx.record.Model)
+	}
+	if len(types) > 0 {
+		fmt.Fprintf(&b, "import type { %s } from '../../models/types';\n", strings.Join(types, ", "))
 	}
 
 	// Import API client functions for data fetching and form submission
-	var listEp *ir.Endpoint
 	var createEp *ir.Endpoint
-	if needsEffect && modelName != "" {
-		listEp = findListEndpoint(app, modelName)
-	}
 	if needsFormState && modelName != "" {
 		createEp = findCreateEndpoint(app, modelName)
 	}
@@ -140,6 +304,24 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 		kebab := toKebabCase(comp)
 		fmt.Fprintf(&b, "import { %sComponent } from '../../components/%s/%s.component';\n", comp, kebab, kebab)
 	}
+	loopsOverCalendar := pageLoopsOverCalendar(page, app, modelName)
+	if loopsOverCalendar {
+		b.WriteString("import { AddToCalendarComponent } from '../../components/add-to-calendar/add-to-calendar.component';\n")
+	}
+	hasChart := pageHasChart(page, app)
+	if hasChart {
+		b.WriteString("import { DataChartComponent } from '../../components/data-chart/data-chart.component';\n")
+	}
+	if len(ctx.tooltips) > 0 {
+		b.WriteString("import { TooltipComponent } from '../../components/tooltip/tooltip.component';\n")
+	}
+	if ctx.panel != nil {
+		b.WriteString("import { DetailPanelComponent } from '../../components/detail-panel/detail-panel.component';\n")
+	}
+	hasSkeleton := pageHasSkeleton(page)
+	if hasSkeleton {
+		b.WriteString("import { SkeletonComponent } from '../../components/skeleton/skeleton.component';\n")
+	}
 
 	compName := toPascalCase(page.Name) + "Component"
 	selector := "app-" + toKebabCase(page.Name)
@@ -157,57 +339,120 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	for _, comp := range usedComponents {
 		importsList = append(importsList, comp+"Component")
 	}
+	if loopsOverCalendar {
+		importsList = append(importsList, "AddToCalendarComponent")
+	}
+	if hasChart {
+		importsList = append(importsList, "DataChartComponent")
+	}
+	if ctx.reorder != nil {
+		importsList = append(importsList, "CdkDropList", "CdkDrag")
+	}
+	if len(ctx.tooltips) > 0 {
+		importsList = append(importsList, "TooltipComponent")
+	}
+	if ctx.panel != nil {
+		importsList = append(importsList, "DetailPanelComponent")
+	}
+	if hasSkeleton {
+		importsList = append(importsList, "SkeletonComponent")
+	}
 	fmt.Fprintf(&b, "  imports: [%s],\n", strings.Join(importsList, ", "))
 	b.WriteString("  template: `\n")
 
 	// Template
 	fmt.Fprintf(&b, "    <div class=\"%s-page\">\n", toKebabCase(page.Name))
+	// Pages declaring their error state show load errors there
+	if ctx.loadError != "" && !ctx.hasErrorState {
+		writeLoadErrorNG(&b, "      ", ctx)
+	}
 
 	loopFields := collectLoopFields(page, ctx)
+	ctx.itemFields = loopFields
 	loopRendered := false
 	for _, a := range page.Content {
+		if ctx.table != nil && ctx.table.Covers(a) {
+			fmt.Fprintf(&b, "      <!-- %s — rendered by the table -->\n", ngText.Replace(a.Text))
+			continue
+		}
 		if a.Type == "loop" && loopRendered {
 			continue
 		}
 		if a.Type == "loop" {
 			loopRendered = true
 			writeLoopNG(&b, a.Text, "      ", ctx, loopFields)
+			if ctx.table == nil {
+				writeListEnd(&b, "      ", page, ctx)
+			}
 			continue
 		}
 		writeTemplateAction(&b, a, "      ", ctx)
+		if ctx.table != nil && a == ctx.table.Show {
+			writeListEnd(&b, "      ", page, ctx)
+		}
 	}
 
 	if needsFormState {
 		fmt.Fprintf(&b, "      @if (showForm()) {\n")
 		b.WriteString("        <div class=\"modal-overlay\" (click)=\"showForm.set(false)\">\n")
-		b.WriteString("          <div class=\"modal\" (click)=\"$event.stopPropagation()\">\n")
-		b.WriteString("            <button class=\"modal-close\" (click)=\"showForm.set(false)\">&times;</button>\n")
+		b.WriteString("          <div\n")
+		b.WriteString("            #dialog\n")
+		b.WriteString("            class=\"modal\"\n")
+		b.WriteString("            role=\"dialog\"\n")
+		b.WriteString("            aria-modal=\"true\"\n")
+		title := ""
+		if modelName != "" {
+			title = ctx.uniqueID("new-" + toKebabCase(modelName) + "-title")
+			fmt.Fprintf(&b, "            aria-labelledby=\"%s\"\n", title)
+		} else {
+			b.WriteString("            aria-label=\"New\"\n")
+		}
+		b.WriteString("            (click)=\"$event.stopPropagation()\"\n")
+		b.WriteString("            (keydown.escape)=\"showForm.set(false)\"\n")
+		b.WriteString("          >\n")
+		b.WriteString("            <button type=\"button\" class=\"modal-close\" aria-label=\"Close\" (click)=\"showForm.set(false)\">&times;</button>\n")
 		if modelName != "" {
-			fmt.Fprintf(&b, "            <h2>New %s</h2>\n", modelName)
+			fmt.Fprintf(&b, "            <h2 id=\"%s\">New %s</h2>\n", title, modelName)
 		}
 		writeFormNG(&b, "a form to create a "+modelName, "            ", ctx)
 		b.WriteString("          </div>\n")
 		b.WriteString("        </div>\n")
 		b.WriteString("      }\n")
 	}
+	if ctx.panel != nil {
+		writePanelNG(&b, "      ", ctx)
+	}
 
-	b.WriteString("    </div>\n  `\n})\n")
+	b.WriteString("    </div>\n  `")
+	if ctx.responsive != nil {
+		b.WriteString(",\n  styles: [`\n")
+		b.WriteString(responsiveStyles(page, ctx))
+		b.WriteString("`],")
+	}
+	b.WriteString("\n})\n")
 
 	// Class
-	fmt.Fprintf(&b, "export class %s implements OnInit {\n", compName)
+	if ctx.scroll {
+		fmt.Fprintf(&b, "export class %s implements OnInit, OnDestroy {\n", compName)
+	} else {
+		fmt.Fprintf(&b, "export class %s implements OnInit {\n", compName)
+	}
 
 	if needsRouter {
 		b.WriteString("  private router = inject(Router);\n")
 	}
-	if needsDataState || needsEffect {
+	if ctx.record != nil || ctx.query != nil {
+		b.WriteString("  private route = inject(ActivatedRoute);\n")
+	}
+	if needsApi {
 		b.WriteString("  private http = inject(HttpClient);\n")
 		b.WriteString("  private api = inject(ApiService);\n")
 	}
 	if needsForm {
 		b.WriteString("  private fb = inject(FormBuilder);\n")
 		b.WriteString("  form: FormGroup = this.fb.group({\n")
-		for _, f := range formFields {
-			fmt.Fprintf(&b, "    %s: [''],\n", toCamelCase(f))
+		for _, in := range formControls {
+			fmt.Fprintf(&b, "    %s: [''%s],\n", in.name, controlValidators(in))
 		}
 		b.WriteString("  });\n")
 	}
@@ -219,11 +464,27 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			b.WriteString("  data = signal<any[]>([]);\n")
 		}
 	}
+	if ctx.scroll {
+		b.WriteString("  nextCursor = signal<string | null>(null);\n")
+		b.WriteString("  loadingMore = signal(false);\n")
+		b.WriteString("  @ViewChild('sentinel') sentinel?: ElementRef<HTMLElement>;\n")
+		b.WriteString("  private observer?: IntersectionObserver;\n")
+	}
+	if ctx.panel != nil {
+		fmt.Fprintf(&b, "  selected = signal<%s | null>(null);\n", modelName)
+	}
+	if r := ctx.record; r != nil {
+		fmt.Fprintf(&b, "  %s = signal<%s | null | undefined>(undefined);\n", strings.ToLower(r.Model[:1])+r.Model[1:], r.Model)
+	}
+	if ctx.query != nil {
+		writeListQueryState(&b, ctx)
+	}
 	if needsAuth {
 		b.WriteString("  isLoggedIn = signal(!!localStorage.getItem('token'));\n")
 	}
 	if needsFormState {
 		b.WriteString("  showForm = signal(false);\n")
+		writeDialogFocus(&b)
 	}
 	if needsSuccess {
 		b.WriteString("  success = signal('');\n")
@@ -231,6 +492,20 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	if needsError {
 		b.WriteString("  error = signal('');\n")
 	}
+	if ctx.loadError != "" {
+		b.WriteString("  loadError = signal('');\n")
+	}
+	if ctx.edit != nil {
+		writeEditState(&b, ctx)
+	}
+	if ctx.importForm != nil {
+		writeImportState(&b)
+	}
+	if ctx.remember {
+		writeRememberState(&b, pagePath(page.Name))
+	}
+	writeTimeMembers(&b, ctx)
+	writeFormatMembers(&b, ctx)
 
 	// ngOnInit
 	if needsEffect {
@@ -238,14 +513,48 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			listEp = findListEndpoint(app, modelName)
 		}
 		b.WriteString("\n  ngOnInit() {\n")
-		if listEp != nil {
+		if ctx.record != nil {
+			writeRecordInit(&b, ctx.record)
+		}
+		switch {
+		case ctx.remember:
+			writeRememberInit(&b, ctx, pagePath(page.Name))
+		case paged:
+			b.WriteString("    this.route.queryParamMap.subscribe(() => this.load());\n")
+		default:
+			b.WriteString("    this.load();\n")
+		}
+		b.WriteString("  }\n")
+		b.WriteString("\n  load() {\n")
+		b.WriteString("    this.loading.set(true);\n")
+		b.WriteString("    this.loadError.set('');\n")
+		if listEp != nil && paged {
+			fmt.Fprintf(&b, "    this.api.%s(undefined, this.listQuery()).subscribe({\n", toCamelCase(listEp.Name))
+			b.WriteString("      next: (res) => {\n")
+			fmt.Fprintf(&b, "        this.%s.set(res.data ?? []);\n", varName)
+			b.WriteString("        this.hasNext.set(res.pagination?.nextCursor != null);\n")
+			b.WriteString("        this.loading.set(false);\n")
+			b.WriteString("      },\n")
+			fmt.Fprintf(&b, "      error: (err) => { %s; this.loading.set(false); },\n", loadFailed(ctx))
+			b.WriteString("    });\n")
+		} else if listEp != nil && ctx.scroll {
+			fmt.Fprintf(&b, "    this.api.%s().subscribe({\n", toCamelCase(listEp.Name))
+			b.WriteString("      next: (res) => {\n")
+			fmt.Fprintf(&b, "        this.%s.set(res.data ?? []);\n", varName)
+			b.WriteString("        this.nextCursor.set(res.pagination?.nextCursor ?? null);\n")
+			b.WriteString("        this.loading.set(false);\n")
+			b.WriteString("        this.observeSentinel();\n")
+			b.WriteString("      },\n")
+			fmt.Fprintf(&b, "      error: (err) => { %s; this.loading.set(false); },\n", loadFailed(ctx))
+			b.WriteString("    });\n")
+		} else if listEp != nil {
 			fmt.Fprintf(&b, "    this.api.%s().subscribe({\n", toCamelCase(listEp.Name))
 			if modelName != "" {
 				fmt.Fprintf(&b, "      next: (res) => { this.%s.set(res.data ?? []); this.loading.set(false); },\n", varName)
 			} else {
 				b.WriteString("      next: (res) => { this.data.set(res.data ?? []); this.loading.set(false); },\n")
 			}
-			b.WriteString("      error: () => this.loading.set(false),\n")
+			fmt.Fprintf(&b, "      error: (err) => { %s; this.loading.set(false); },\n", loadFailed(ctx))
 			b.WriteString("    });\n")
 		} else {
 			apiPath := "/api/" + toKebabCase(varName)
@@ -256,13 +565,26 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			} else {
 				b.WriteString("      next: (res) => { this.data.set(res.data ?? []); this.loading.set(false); },\n")
 			}
-			b.WriteString("      error: () => this.loading.set(false),\n")
+			fmt.Fprintf(&b, "      error: (err) => { %s; this.loading.set(false); },\n", loadFailed(ctx))
 			b.WriteString("    });\n")
 		}
 		b.WriteString("  }\n")
+	} else if ctx.record != nil {
+		b.WriteString("\n  ngOnInit() {\n")
+		writeRecordInit(&b, ctx.record)
+		b.WriteString("  }\n")
 	} else {
 		b.WriteString("\n  ngOnInit() {}\n")
 	}
+	if ctx.record != nil {
+		writeRecordLoad(&b, ctx)
+	}
+	if ctx.edit != nil {
+		writeEditMethods(&b, ctx)
+	}
+	if ctx.importForm != nil {
+		writeImportMethods(&b, ctx)
+	}
 
 	// onSubmit method when create endpoint is available
 	if createEp != nil {
@@ -276,42 +598,50 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			}
 		}
 		b.WriteString("\n  onSubmit() {\n")
+		if ctx.fieldErrors {
+			b.WriteString("    if (!this.checkForm()) return;\n")
+		}
 		if ctx.hasErrorState {
 			b.WriteString("    this.error.set('');\n")
 		}
-		fmt.Fprintf(&b, "    this.api.%s(this.form.value).subscribe({\n", createFunc)
-		if isLogin {
-			b.WriteString("      next: (res: any) => {\n")
-			b.WriteString("        localStorage.setItem('token', res.token);\n")
-			if needsRouter {
-				b.WriteString("        this.router.navigate(['/']);\n")
-			} else {
-				b.WriteString("        window.location.href = '/';\n")
-			}
-			b.WriteString("      },\n")
+		if !isLogin && varName != "" && varName != "data" {
+			writeOptimisticCreate(&b, createFunc, ctx)
 		} else {
-			b.WriteString("      next: (res: any) => {\n")
-			if varName != "" && varName != "data" {
-				fmt.Fprintf(&b, "        this.%s.set([...this.%s(), res.data]);\n", varName, varName)
-			}
-			if needsFormState {
-				b.WriteString("        this.showForm.set(false);\n")
+			fmt.Fprintf(&b, "    this.api.%s(this.form.value).subscribe({\n", createFunc)
+			if isLogin {
+				b.WriteString("      next: (res: any) => {\n")
+				b.WriteString("        localStorage.setItem('token', res.token);\n")
+				if needsRouter {
+					b.WriteString("        this.router.navigate(['/']);\n")
+				} else {
+					b.WriteString("        window.location.href = '/';\n")
+				}
+				b.WriteString("      },\n")
+			} else {
+				b.WriteString("      next: (res: any) => {\n")
+				if needsFormState {
+					b.WriteString("        this.showForm.set(false);\n")
+				}
+				if ctx.hasSuccessState {
+					b.WriteString("        this.success.set('Created successfully');\n")
+				}
+				b.WriteString("        this.form.reset();\n")
+				b.WriteString("      },\n")
 			}
-			if ctx.hasSuccessState {
-				b.WriteString("        this.success.set('Created successfully');\n")
+			if ctx.hasErrorState {
+				b.WriteString("      error: (err: any) => this.error.set(err?.message ?? 'Failed to save'),\n")
+			} else {
+				b.WriteString("      error: () => {},\n")
 			}
-			b.WriteString("        this.form.reset();\n")
-			b.WriteString("      },\n")
-		}
-		if ctx.hasErrorState {
-			b.WriteString("      error: (err: any) => this.error.set(err?.message ?? 'Failed to save'),\n")
-		} else {
-			b.WriteString("      error: () => {},\n")
+			b.WriteString("    });\n")
 		}
-		b.WriteString("    });\n")
 		b.WriteString("  }\n")
 	}
 
+	if ctx.fieldErrors {
+		writeFieldErrorMethods(&b)
+	}
+
 	if needsFileUpload {
 		b.WriteString("\n  onFileSelected(event: Event) {\n")
 		b.WriteString("    const input = event.target as HTMLInputElement;\n")
@@ -334,12 +664,94 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 		b.WriteString("  }\n")
 	}
 
+	if ctx.query != nil {
+		writeListQueryMethods(&b, ctx.query)
+	}
+	if ctx.remember {
+		writeRememberMethods(&b, ctx, pagePath(page.Name))
+	}
+	if linkedPage(ctx) != "" {
+		writeOpenRecord(&b, ctx)
+	}
+
+	if ctx.scroll {
+		writeLoadMoreMethods(&b, listEp, ctx)
+	}
+	if ctx.deleteEp != nil {
+		writeDeleteMethod(&b, ctx.deleteEp, ctx)
+	}
+	if ctx.reorder != nil {
+		writeReorderMethod(&b, ctx.reorder, ctx)
+	}
+	if len(keyBindings) > 0 {
+		writeKeyBindings(&b, keyBindings, ctx)
+	}
+
 	if needsRouter {
 		b.WriteString("\n  navigate(path: string) {\n    this.router.navigate([path]);\n  }\n")
 	}
 
 	b.WriteString("}\n")
-	return b.String()
+	return withFormatImports(withTimeImports(b.String(), ctx), ctx)
+}
+
+// ── Table ──
+
+// writeTableNG renders the page's table: a header per column, sortable
+// ones clickable, and a row per record.
+func writeTableNG(b *strings.Builder, indent string, ctx *pageContext) {
+	t := ctx.table
+	rows := rowsVar(ctx)
+	fmt.Fprintf(b, "%s<table class=\"data-table\">\n", indent)
+	fmt.Fprintf(b, "%s  <thead>\n", indent)
+	fmt.Fprintf(b, "%s    <tr>\n", indent)
+	for _, f := range t.Columns {
+		label := ir.FieldLabel(f.Name)
+		if t.IsSortable(f) {
+			fmt.Fprintf(b, "%s      <th class=\"sortable\" (click)=\"sortBy('%s')\">\n", indent, f.Name)
+			fmt.Fprintf(b, "%s        %s{{ sortKey() === '%s' ? (sortDesc() ? ' ▼' : ' ▲') : '' }}\n", indent, label, f.Name)
+			fmt.Fprintf(b, "%s      </th>\n", indent)
+		} else {
+			fmt.Fprintf(b, "%s      <th>%s</th>\n", indent, label)
+		}
+	}
+	fmt.Fprintf(b, "%s    </tr>\n", indent)
+	fmt.Fprintf(b, "%s  </thead>\n", indent)
+	fmt.Fprintf(b, "%s  <tbody>\n", indent)
+	fmt.Fprintf(b, "%s    @for (%s of %s(); track %s.id) {\n", indent, ctx.itemVar, rows, ctx.itemVar)
+	if t.RowPage != "" {
+		fmt.Fprintf(b, "%s      <tr class=\"clickable\" (click)=\"%s\">\n", indent, openCall(ctx.itemVar, ctx))
+	} else if ctx.panel != nil {
+		fmt.Fprintf(b, "%s      <tr class=\"clickable\"%s>\n", indent, panelClick(ctx.itemVar, ctx))
+	} else {
+		fmt.Fprintf(b, "%s      <tr>\n", indent)
+	}
+	for _, f := range t.Columns {
+		value := fmt.Sprintf("{{ %s.%s }}", ctx.itemVar, f.Name)
+		if f.Type == "boolean" {
+			value = fmt.Sprintf("{{ %s.%s ? 'Yes' : 'No' }}", ctx.itemVar, f.Name)
+		} else if t := timeNG(ctx.itemVar+"."+f.Name, f, ctx); t != "" {
+			value = t
+		} else if n := numberNG(ctx.itemVar+"."+f.Name, f, ctx); n != "" {
+			value = n
+		}
+		if tip := tooltipOn(f.Name, ctx); tip != nil {
+			value = fmt.Sprintf("<app-tooltip %s>%s</app-tooltip>", tooltipText(tip, ctx.itemVar), value)
+		}
+		fmt.Fprintf(b, "%s        <td>%s</td>\n", indent, value)
+	}
+	fmt.Fprintf(b, "%s      </tr>\n", indent)
+	fmt.Fprintf(b, "%s    }\n", indent)
+	fmt.Fprintf(b, "%s  </tbody>\n", indent)
+	fmt.Fprintf(b, "%s</table>\n", indent)
+}
+
+// pagePath returns a page's route: "/" for Home, "/<kebab-case>" otherwise.
+func pagePath(name string) string {
+	if strings.ToLower(name) == "home" {
+		return "/"
+	}
+	return "/" + toKebabCase(name)
 }
 
 func generateComponent(comp *ir.Component, app *ir.Application) string {
@@ -347,7 +759,7 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 
 	b.WriteString("import { Component, Input, Output, EventEmitter, inject } from '@angular/core';\n")
 	b.WriteString("import { CommonModule } from '@angular/common';\n")
-	
+
 	needsForm := false
 	var formFields []string
 	for _, a := range comp.Content {
@@ -382,6 +794,10 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 		}
 		fmt.Fprintf(&b, "import type { %s } from '../../models/types';\n", strings.Join(models, ", "))
 	}
+	tips, tipProp := componentTooltips(comp, app)
+	if len(tips) > 0 {
+		b.WriteString("import { TooltipComponent } from '../tooltip/tooltip.component';\n")
+	}
 
 	compName := toPascalCase(comp.Name) + "Component"
 	selector := "app-" + toKebabCase(comp.Name)
@@ -389,11 +805,14 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 	fmt.Fprintf(&b, "\n@Component({\n")
 	fmt.Fprintf(&b, "  selector: '%s',\n", selector)
 	b.WriteString("  standalone: true,\n")
+	importsList := []string{"CommonModule"}
 	if needsForm {
-		b.WriteString("  imports: [CommonModule, ReactiveFormsModule],\n")
-	} else {
-		b.WriteString("  imports: [CommonModule],\n")
+		importsList = append(importsList, "ReactiveFormsModule")
 	}
+	if len(tips) > 0 {
+		importsList = append(importsList, "TooltipComponent")
+	}
+	fmt.Fprintf(&b, "  imports: [%s],\n", strings.Join(importsList, ", "))
 	b.WriteString("  template: `\n")
 
 	hasClick := hasClickHandler(comp)
@@ -413,10 +832,20 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 		app:         app,
 		props:       propsMap,
 		isComponent: true,
+		tooltips:    tips,
 	}
 
+	// Hovering anywhere over the component shows its tooltip
+	indent := "      "
+	if len(tips) > 0 {
+		fmt.Fprintf(&b, "%s<app-tooltip %s>\n", indent, tooltipText(tips[0], tipProp))
+		indent += "  "
+	}
 	for _, a := range comp.Content {
-		writeTemplateAction(&b, a, "      ", ctx)
+		writeTemplateAction(&b, a, indent, ctx)
+	}
+	if len(tips) > 0 {
+		b.WriteString("      </app-tooltip>\n")
 	}
 	b.WriteString("    </div>\n  `\n})\n")
 
@@ -451,12 +880,41 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 }
 
 func writeTemplateAction(b *strings.Builder, a *ir.Action, indent string, ctx *pageContext) {
+	if ctx.record != nil && ir.IsDetailRoute(a) {
+		writeRecordNG(b, indent, ctx)
+		return
+	}
+	if ctx.edit != nil && a == ctx.edit.Form {
+		writeEditFormNG(b, indent, ctx)
+		return
+	}
+	if ctx.importForm != nil && a == ctx.importForm.Form {
+		writeImportFormNG(b, indent, ctx)
+		return
+	}
 	switch a.Type {
 	case "display":
+		if a.Template != "" {
+			writeTemplateNG(b, a.Template, indent, ctx)
+			return
+		}
+		if ctx.table != nil && a == ctx.table.Show {
+			writeTableNG(b, indent, ctx)
+			return
+		}
+		if c := ir.ChartFor(ctx.app, a); c != nil {
+			writeChartNG(b, c, indent)
+			return
+		}
 		writeDisplayNG(b, a.Text, indent, ctx)
 	case "input":
 		writeInputNG(b, a.Text, indent, ctx)
 	case "interact":
+		if ir.IsReorder(a) || ir.IsKeyBinding(a) || ir.IsInfiniteScroll(a) || ir.IsDelete(a) && ctx.deleteEp != nil ||
+			tooltipWired(a, ctx) || panelWired(a, ctx) || a == ctx.itemLink {
+			writeInteractionNote(b, a, indent, ctx)
+			return
+		}
 		writeInteractNG(b, a.Text, indent, ctx)
 	case "condition":
 		writeConditionNG(b, a.Text, indent, ctx)
@@ -465,6 +923,14 @@ func writeTemplateAction(b *strings.Builder, a *ir.Action, indent string, ctx *p
 	case "query":
 		// handled by ngOnInit
 	default:
+		if ctx.remember && ir.IsRememberView(a) {
+			fmt.Fprintf(b, "%s<!-- %s — restored from sessionStorage -->\n", indent, a.Text)
+			return
+		}
+		if ctx.responsive != nil && ir.IsResponsive(a) {
+			fmt.Fprintf(b, "%s<!-- %s — handled by the page's styles -->\n", indent, a.Text)
+			return
+		}
 		fmt.Fprintf(b, "%s<!-- TODO: %s -->\n", indent, a.Text)
 	}
 }
@@ -482,6 +948,12 @@ func writeDisplayNG(b *strings.Builder, text string, indent string, ctx *pageCon
 	}
 	lower := strings.ToLower(cleaned)
 
+	// Pagination
+	if (strings.Contains(lower, "pagination") || strings.Contains(lower, "page controls")) && ctx.query != nil && ctx.query.Paged {
+		writePaginationNG(b, indent)
+		return
+	}
+
 	// Hero section
 	if strings.Contains(lower, "hero") {
 		appName := ""
@@ -660,31 +1132,84 @@ func writeDisplayNG(b *strings.Builder, text string, indent string, ctx *pageCon
 	fmt.Fprintf(b, "%s<div class=\"%s\"></div>\n", indent, slugify(text))
 }
 
+// writeTemplateNG renders show "..." text. References interpolate a
+// component's inputs, or a page's data from its first loaded record;
+// Angular escapes the values. The text is escaped for the inline template:
+// braces, @ blocks, and the backtick string it sits in.
+func writeTemplateNG(b *strings.Builder, tmpl string, indent string, ctx *pageContext) {
+	var out strings.Builder
+	for _, part := range ir.ParseTemplate(tmpl) {
+		if part.Root == "" {
+			out.WriteString(ngText.Replace(part.Text))
+			continue
+		}
+		fmt.Fprintf(&out, "{{ %s }}", templateExpr(part, ctx))
+	}
+	fmt.Fprintf(b, "%s<p>%s</p>\n", indent, out.String())
+}
+
+var ngText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "{", "&#123;", "}", "&#125;", "@", "&#64;", "`", "&#96;", "$", "&#36;", "\\", "&#92;")
+
+// templateExpr resolves a template reference: "task.title" to a prop's
+// field, or to the page's first record.
+func templateExpr(ref ir.TemplatePart, ctx *pageContext) string {
+	for name, typ := range ctx.props {
+		if strings.EqualFold(name, ref.Root) {
+			return name + templateField(findModel(ctx.app, typ), ref.Field, ".")
+		}
+	}
+	return ctx.varName + "()[0]" + templateField(findModel(ctx.app, ctx.modelName), ref.Field, "?.")
+}
+
+// templateField returns sep and the model's name for field, or nothing
+// without a field.
+func templateField(model *ir.DataModel, field, sep string) string {
+	if field == "" {
+		return ""
+	}
+	if model != nil {
+		if f := model.FieldNamed(field); f != nil {
+			return sep + f.Name
+		}
+	}
+	return sep + field
+}
+
 // ── Input ──
 
 func writeInputNG(b *strings.Builder, text string, indent string, ctx *pageContext) {
 	lower := strings.ToLower(text)
 
+	if strings.Contains(lower, "search") && ctx.query != nil {
+		writeSearchNG(b, indent)
+		return
+	}
 	if strings.Contains(lower, "search") {
-		fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" class=\"search-input\" />\n", indent)
+		fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" aria-label=\"Search\" class=\"search-input\" />\n", indent)
 		return
 	}
+	if ctx.query != nil {
+		if f := ir.FilterField(ctx.query.Model, &ir.Action{Type: "input", Text: text}); f != nil {
+			writeFilterNG(b, indent, f)
+			return
+		}
+	}
 	if strings.Contains(lower, "dropdown") || strings.Contains(lower, "filter by") || strings.Contains(lower, "select") {
-		label := "All"
+		label, name := "All", "Filter"
 		if strings.Contains(lower, "status") {
-			label = "All Statuses"
+			label, name = "All Statuses", "Filter by status"
 		} else if strings.Contains(lower, "priority") {
-			label = "All Priorities"
+			label, name = "All Priorities", "Filter by priority"
 		} else if strings.Contains(lower, "category") {
-			label = "Select Category"
+			label, name = "Select Category", "Category"
 		}
-		fmt.Fprintf(b, "%s<select class=\"filter-select\">\n", indent)
+		fmt.Fprintf(b, "%s<select class=\"filter-select\" aria-label=\"%s\">\n", indent, name)
 		fmt.Fprintf(b, "%s  <option value=\"\">%s</option>\n", indent, label)
 		fmt.Fprintf(b, "%s</select>\n", indent)
 		return
 	}
 	if strings.Contains(lower, "date") && (strings.Contains(lower, "picker") || strings.Contains(lower, "range")) {
-		fmt.Fprintf(b, "%s<input type=\"date\" class=\"date-filter\" />\n", indent)
+		fmt.Fprintf(b, "%s<input type=\"date\" class=\"date-filter\" aria-label=\"Filter by date\" />\n", indent)
 		return
 	}
 	if strings.Contains(lower, "button") && (strings.Contains(lower, "create") || strings.Contains(lower, "new") || strings.Contains(lower, "add")) {
@@ -711,9 +1236,10 @@ func writeInputNG(b *strings.Builder, text string, indent string, ctx *pageConte
 		} else if strings.Contains(lower, "cover") || strings.Contains(lower, "image") {
 			label = "Upload image"
 		}
+		id := ctx.uniqueID("file-upload")
 		fmt.Fprintf(b, "%s<div class=\"file-upload\">\n", indent)
-		fmt.Fprintf(b, "%s  <label>%s</label>\n", indent, label)
-		fmt.Fprintf(b, "%s  <input type=\"file\" accept=\"image/*\" (change)=\"onFileSelected($event)\" />\n", indent)
+		fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, id, label)
+		fmt.Fprintf(b, "%s  <input id=\"%s\" type=\"file\" accept=\"image/*\" (change)=\"onFileSelected($event)\" />\n", indent, id)
 		fmt.Fprintf(b, "%s</div>\n", indent)
 		return
 	}
@@ -723,7 +1249,7 @@ func writeInputNG(b *strings.Builder, text string, indent string, ctx *pageConte
 	}
 	if strings.Contains(lower, "rich text") || strings.Contains(lower, "editor") {
 		fmt.Fprintf(b, "%s<div class=\"rich-text-editor\">\n", indent)
-		fmt.Fprintf(b, "%s  <textarea placeholder=\"Write your content...\"></textarea>\n", indent)
+		fmt.Fprintf(b, "%s  <textarea placeholder=\"Write your content...\" aria-label=\"%s\"></textarea>\n", indent, inputLabel(text))
 		fmt.Fprintf(b, "%s</div>\n", indent)
 		return
 	}
@@ -735,9 +1261,10 @@ func writeInputNG(b *strings.Builder, text string, indent string, ctx *pageConte
 				break
 			}
 		}
+		id := ctx.uniqueID(toKebabCase(toCamelCase(fieldName)))
 		fmt.Fprintf(b, "%s<div class=\"form-field\">\n", indent)
-		fmt.Fprintf(b, "%s  <label>%s</label>\n", indent, capitalize(fieldName))
-		fmt.Fprintf(b, "%s  <input type=\"text\" placeholder=\"%s\" />\n", indent, fieldName)
+		fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, id, capitalize(fieldName))
+		fmt.Fprintf(b, "%s  <input id=\"%s\" type=\"text\" placeholder=\"%s\" />\n", indent, id, fieldName)
 		fmt.Fprintf(b, "%s</div>\n", indent)
 		return
 	}
@@ -749,7 +1276,7 @@ func writeInputNG(b *strings.Builder, text string, indent string, ctx *pageConte
 		fmt.Fprintf(b, "%s<button class=\"btn\">%s</button>\n", indent, label)
 		return
 	}
-	fmt.Fprintf(b, "%s<input type=\"text\" placeholder=\"%s\" />\n", indent, text)
+	fmt.Fprintf(b, "%s<input type=\"text\" placeholder=\"%s\" aria-label=\"%s\" />\n", indent, text, inputLabel(text))
 }
 
 func writeFormNG(b *strings.Builder, text string, indent string, ctx *pageContext) {
@@ -769,62 +1296,46 @@ func writeFormNG(b *strings.Builder, text string, indent string, ctx *pageContex
 		}
 	}
 
+	model := formModel(lower, ctx)
+	formID := "form"
+	switch {
+	case isLogin:
+		formID = "login-form"
+	case model != nil:
+		formID = toKebabCase(model.Name) + "-form"
+	}
+	formID = ctx.uniqueID(formID)
+
 	if createEp != nil {
 		fmt.Fprintf(b, "%s<form class=\"form\" [formGroup]=\"form\" (ngSubmit)=\"onSubmit()\">\n", indent)
-		for _, f := range fields {
-			inputType := "text"
-			fl := strings.ToLower(f)
-			if strings.Contains(fl, "email") {
-				inputType = "email"
-			} else if strings.Contains(fl, "password") {
-				inputType = "password"
-			} else if strings.Contains(fl, "date") {
-				inputType = "date"
-			} else if strings.Contains(fl, "number") || strings.Contains(fl, "count") {
-				inputType = "number"
-			}
-			fmt.Fprintf(b, "%s  <div class=\"form-field\">\n", indent)
-			fmt.Fprintf(b, "%s    <label>%s</label>\n", indent, capitalize(f))
-			fmt.Fprintf(b, "%s    <input type=\"%s\" formControlName=\"%s\" placeholder=\"%s\" />\n", indent, inputType, toCamelCase(f), capitalize(f))
-			fmt.Fprintf(b, "%s  </div>\n", indent)
-		}
-		fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
-		fmt.Fprintf(b, "%s</form>\n", indent)
 	} else {
 		onSubmit := "/* TODO: submit */"
 		if ctx.hasSuccessState && ctx.hasErrorState {
 			onSubmit = "error.set(''); success.set('Saved successfully')"
+			if ctx.fieldErrors {
+				onSubmit = "error.set(''); checkForm() && success.set('Saved successfully')"
+			}
 		} else if ctx.hasSuccessState {
 			onSubmit = "success.set('Saved successfully')"
-		}
-
-		fmt.Fprintf(b, "%s<form class=\"form\" [formGroup]=\"form\" (ngSubmit)=\"%s\">\n", indent, onSubmit)
-		for _, f := range fields {
-			inputType := "text"
-			fl := strings.ToLower(f)
-			if strings.Contains(fl, "email") {
-				inputType = "email"
-			} else if strings.Contains(fl, "password") {
-				inputType = "password"
-			} else if strings.Contains(fl, "date") {
-				inputType = "date"
-			} else if strings.Contains(fl, "number") || strings.Contains(fl, "count") {
-				inputType = "number"
+			if ctx.fieldErrors {
+				onSubmit = "checkForm() && success.set('Saved successfully')"
 			}
-			fmt.Fprintf(b, "%s  <div class=\"form-field\">\n", indent)
-			fmt.Fprintf(b, "%s    <label>%s</label>\n", indent, capitalize(f))
-			fmt.Fprintf(b, "%s    <input type=\"%s\" formControlName=\"%s\" placeholder=\"%s\" />\n", indent, inputType, toCamelCase(f), capitalize(f))
-			fmt.Fprintf(b, "%s  </div>\n", indent)
+		} else if ctx.fieldErrors {
+			onSubmit = "checkForm()"
 		}
-		fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
-		fmt.Fprintf(b, "%s</form>\n", indent)
+		fmt.Fprintf(b, "%s<form class=\"form\" [formGroup]=\"form\" (ngSubmit)=\"%s\">\n", indent, onSubmit)
+	}
+	for _, in := range formInputs(formID, fields, isLogin, model) {
+		writeFormFieldNG(b, indent+"  ", in, ctx)
 	}
+	fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
+	fmt.Fprintf(b, "%s</form>\n", indent)
 }
 
 // ── Loop ──
 
 func writeLoopNG(b *strings.Builder, text string, indent string, ctx *pageContext, fields []string) {
-	dataVar := ctx.varName
+	dataVar := rowsVar(ctx)
 	if dataVar == "" {
 		dataVar = "data"
 	}
@@ -833,12 +1344,44 @@ func writeLoopNG(b *strings.Builder, text string, indent string, ctx *pageContex
 		item = "item"
 	}
 
+	// A list users can drag is a CDK drop list of draggable items
+	open := func() string {
+		if ctx.reorder != nil {
+			fmt.Fprintf(b, "%s<div cdkDropList class=\"sortable-list\" (cdkDropListDropped)=\"onReorder($event)\">\n", indent)
+			fmt.Fprintf(b, "%s  @for (%s of %s(); track %s.id) {\n", indent, item, dataVar, item)
+			return indent + "  "
+		}
+		fmt.Fprintf(b, "%s@for (%s of %s(); track %s.id) {\n", indent, item, dataVar, item)
+		return indent
+	}
+	close := func() {
+		if ctx.reorder != nil {
+			fmt.Fprintf(b, "%s  }\n", indent)
+			fmt.Fprintf(b, "%s</div>\n", indent)
+			return
+		}
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+	drag := ""
+	if ctx.reorder != nil {
+		drag = "cdkDrag "
+	}
+
 	compRef := extractComponentRef(text)
 	if compRef != "" {
-		fmt.Fprintf(b, "%s@for (%s of %s(); track %s.id) {\n", indent, item, dataVar, item)
+		inner := open()
 		compSelector := "app-" + toKebabCase(compRef)
-		fmt.Fprintf(b, "%s  <%s [%s]=\"%s\" (onClick)=\"/* TODO */\"></%s>\n", indent, compSelector, item, item, compSelector)
-		fmt.Fprintf(b, "%s}\n", indent)
+		if ctx.deleteEp != nil || ctx.panel != nil || ctx.itemPage != "" {
+			fmt.Fprintf(b, "%s  <div %sclass=\"%s-item\"%s>\n", inner, drag, toKebabCase(ctx.modelName), itemClick(item, ctx))
+			fmt.Fprintf(b, "%s    <%s [%s]=\"%s\" (onClick)=\"/* TODO */\"></%s>\n", inner, compSelector, item, item, compSelector)
+			if ctx.deleteEp != nil {
+				writeDeleteButton(b, inner+"    ", item, ctx)
+			}
+			fmt.Fprintf(b, "%s  </div>\n", inner)
+		} else {
+			fmt.Fprintf(b, "%s  <%s %s[%s]=\"%s\" (onClick)=\"/* TODO */\"></%s>\n", inner, compSelector, drag, item, item, compSelector)
+		}
+		close()
 		return
 	}
 
@@ -850,31 +1393,73 @@ func writeLoopNG(b *strings.Builder, text string, indent string, ctx *pageContex
 	if modelClass == "" {
 		modelClass = "item"
 	}
-	fmt.Fprintf(b, "%s@for (%s of %s(); track %s.id) {\n", indent, item, dataVar, item)
-	fmt.Fprintf(b, "%s  <div class=\"%s-item\">\n", indent, modelClass)
+	inner := open()
+	fmt.Fprintf(b, "%s  <div %sclass=\"%s-item\"%s>\n", inner, drag, modelClass, itemClick(item, ctx))
+	whole := tooltipOn("", ctx)
+	if whole != nil {
+		fmt.Fprintf(b, "%s    <app-tooltip %s>\n", inner, tooltipText(whole, item))
+		inner += "  "
+	}
 	if len(fields) > 0 {
 		for _, f := range fields {
 			fieldExpr := item + "." + f
 			fl := strings.ToLower(f)
+			var el string
 			if fl == "status" || fl == "role" || fl == "priority" || fl == "category" {
-				fmt.Fprintf(b, "%s    <span class=\"badge\">{{ %s }}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span class=\"badge\">{{ %s }}</span>", fieldExpr)
 			} else if fl == "title" || fl == "name" {
-				fmt.Fprintf(b, "%s    <h3>{{ %s }}</h3>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<h3>{{ %s }}</h3>", fieldExpr)
+			} else if t := timeNG(fieldExpr, modelField(f, ctx), ctx); t != "" {
+				el = t
+			} else if n := numberNG(fieldExpr, modelField(f, ctx), ctx); n != "" {
+				el = fmt.Sprintf("<span class=\"%s\">%s</span>", numberClass(modelField(f, ctx)), n)
 			} else if strings.Contains(fl, "date") || fl == "due" || fl == "created" || strings.Contains(fl, "published") {
-				fmt.Fprintf(b, "%s    <time>{{ %s }}</time>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<time>{{ %s }}</time>", fieldExpr)
 			} else if strings.Contains(fl, "excerpt") {
-				fmt.Fprintf(b, "%s    <p>{{ %s }}</p>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<p>{{ %s }}</p>", fieldExpr)
 			} else if strings.Contains(fl, "count") || strings.Contains(fl, "view") {
-				fmt.Fprintf(b, "%s    <span class=\"count\">{{ %s }}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span class=\"count\">{{ %s }}</span>", fieldExpr)
 			} else {
-				fmt.Fprintf(b, "%s    <span>{{ %s }}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span>{{ %s }}</span>", fieldExpr)
 			}
+			if tip := tooltipOn(f, ctx); tip != nil {
+				el = fmt.Sprintf("<app-tooltip %s>%s</app-tooltip>", tooltipText(tip, item), el)
+			}
+			fmt.Fprintf(b, "%s    %s\n", inner, el)
 		}
 	} else {
-		fmt.Fprintf(b, "%s    <span>{{ %s | json }}</span>\n", indent, item)
+		fmt.Fprintf(b, "%s    <span>{{ %s | json }}</span>\n", inner, item)
+	}
+	if whole != nil {
+		inner = inner[:len(inner)-2]
+		fmt.Fprintf(b, "%s    </app-tooltip>\n", inner)
+	}
+	cal := ir.CalendarFor(ctx.app, ctx.modelName)
+	if cal != nil {
+		fmt.Fprintf(b, "%s    <app-add-to-calendar feed=\"%s\" [id]=\"%s.id\"></app-add-to-calendar>\n", inner, cal.Slug, item)
+	}
+	if ctx.deleteEp != nil {
+		writeDeleteButton(b, inner+"    ", item, ctx)
+	}
+	fmt.Fprintf(b, "%s  </div>\n", inner)
+	close()
+	if cal != nil {
+		fmt.Fprintf(b, "%s<app-add-to-calendar feed=\"%s\"></app-add-to-calendar>\n", indent, cal.Slug)
 	}
-	fmt.Fprintf(b, "%s  </div>\n", indent)
-	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// pageLoopsOverCalendar reports whether the page lists records of a model
+// published as a calendar, so each one gets an "Add to calendar" button.
+func pageLoopsOverCalendar(page *ir.Page, app *ir.Application, modelName string) bool {
+	if ir.CalendarFor(app, modelName) == nil {
+		return false
+	}
+	for _, a := range page.Content {
+		if a.Type == "loop" {
+			return true
+		}
+	}
+	return false
 }
 
 // ── Condition ──
@@ -895,13 +1480,7 @@ func writeConditionNG(b *strings.Builder, text string, indent string, ctx *pageC
 	// Loading
 	if strings.Contains(lower, "while loading") || strings.Contains(lower, "is loading") {
 		if strings.Contains(lower, "skeleton") {
-			fmt.Fprintf(b, "%s@if (loading()) {\n", indent)
-			fmt.Fprintf(b, "%s  <div class=\"skeleton-screen\">\n", indent)
-			fmt.Fprintf(b, "%s    @for (i of [1,2,3]; track i) {\n", indent)
-			fmt.Fprintf(b, "%s      <div class=\"skeleton-item\"></div>\n", indent)
-			fmt.Fprintf(b, "%s    }\n", indent)
-			fmt.Fprintf(b, "%s  </div>\n", indent)
-			fmt.Fprintf(b, "%s}\n", indent)
+			writeSkeletonNG(b, indent, ctx)
 		} else {
 			fmt.Fprintf(b, "%s@if (loading()) {\n", indent)
 			fmt.Fprintf(b, "%s  <div class=\"loading-spinner\">\n", indent)
@@ -962,17 +1541,24 @@ func writeConditionNG(b *strings.Builder, text string, indent string, ctx *pageC
 		if message == "" {
 			message = "Success!"
 		}
-		fmt.Fprintf(b, "%s@if (success()) {\n", indent)
-		fmt.Fprintf(b, "%s  <div class=\"alert alert-success\">{{ success() || '%s' }}</div>\n", indent, message)
-		fmt.Fprintf(b, "%s}\n", indent)
+		fmt.Fprintf(b, "%s<div role=\"status\">\n", indent)
+		fmt.Fprintf(b, "%s  @if (success()) {\n", indent)
+		fmt.Fprintf(b, "%s    <div class=\"alert alert-success\">{{ success() || '%s' }}</div>\n", indent, message)
+		fmt.Fprintf(b, "%s  }\n", indent)
+		fmt.Fprintf(b, "%s</div>\n", indent)
 		return
 	}
 
 	// Error
 	if strings.Contains(lower, "error") {
-		fmt.Fprintf(b, "%s@if (error()) {\n", indent)
-		fmt.Fprintf(b, "%s  <div class=\"alert alert-error\">{{ error() }}</div>\n", indent)
-		fmt.Fprintf(b, "%s}\n", indent)
+		fmt.Fprintf(b, "%s<div role=\"alert\">\n", indent)
+		fmt.Fprintf(b, "%s  @if (error()) {\n", indent)
+		fmt.Fprintf(b, "%s    <div class=\"alert alert-error\">{{ error() }}</div>\n", indent)
+		fmt.Fprintf(b, "%s  }\n", indent)
+		fmt.Fprintf(b, "%s</div>\n", indent)
+		if ctx.loadError != "" {
+			writeLoadErrorNG(b, indent, ctx)
+		}
 		return
 	}
 
@@ -1114,6 +1700,12 @@ func writeInteractNG(b *strings.Builder, text string, indent string, ctx *pageCo
 
 func detectPageModel(page *ir.Page, app *ir.Application) (modelName, varName, itemVar string) {
 	for _, a := range page.Content {
+		if ir.IsTable(a) {
+			if m := ir.TableModel(app, a); m != nil {
+				return m.Name, strings.ToLower(pluralize(m.Name)), strings.ToLower(m.Name)
+			}
+			continue
+		}
 		if a.Type == "query" || a.Type == "loop" {
 			for _, m := range app.Data {
 				lowerText := strings.ToLower(a.Text)
@@ -1169,7 +1761,7 @@ func findListEndpoint(app *ir.Application, modelName string) *ir.Endpoint {
 	// Priority 2: "GetTasks"
 	for i := range app.APIs {
 		lower := strings.ToLower(app.APIs[i].Name)
-		if strings.HasPrefix(lower, "get") && strings.Contains(lower, lowerModel) {
+		if strings.HasPrefix(lower, "get") && strings.Contains(lower, lowerModel) && !ir.LoadsRecord(app, app.APIs[i]) {
 			return app.APIs[i]
 		}
 	}
@@ -1454,6 +2046,10 @@ func extractFormFields(lower string, ctx *pageContext) []string {
 						if f.Encrypted {
 							continue
 						}
+						// Dragging the list sets its position
+						if r := ir.ReorderFor(ctx.app, model.Name); r != nil && r.Field == f.Name {
+							continue
+						}
 						fields = append(fields, f.Name)
 					}
 					return fields
@@ -30,6 +30,9 @@ func generateTypes(app *ir.Application) string {
 			fmt.Fprintf(&b, "  %s%s: %s;\n", f.Name, optional, fieldType)
 		}
 
+		if model.Versioned {
+			fmt.Fprintf(&b, "  %s: number;\n", ir.VersionColumn)
+		}
 		for _, rel := range model.Relations {
 			switch rel.Kind {
 			case "belongs_to":
@@ -50,18 +53,63 @@ func generateApiService(app *ir.Application) string {
 	b.WriteString(`// Generated by Human compiler — do not edit
 
 import { Injectable, inject } from '@angular/core';
-import { HttpClient, HttpHeaders, HttpParams } from '@angular/common/http';
-import { Observable } from 'rxjs';
-
+import { HttpClient, HttpErrorResponse, HttpHeaders, HttpParams } from '@angular/common/http';
+`)
+	if ir.HasImports(app) {
+		b.WriteString("import { Observable, catchError, from, map, of, switchMap, throwError } from 'rxjs';\n")
+	} else {
+		b.WriteString("import { Observable } from 'rxjs';\n")
+	}
+	var types []string
+	if len(app.Notifications) > 0 {
+		types = append(types, "Notification")
+	}
+	if ir.AccountModel(app) != nil {
+		types = append(types, app.Accounts.Model)
+	}
+	if len(types) > 0 {
+		fmt.Fprintf(&b, "import { %s } from '../models/types';\n", strings.Join(types, ", "))
+	}
+	b.WriteString(`
 export interface ApiResponse<T> {
   data: T;
   error?: string;
+  pagination?: { limit: number; nextCursor: string | null };
 }
 
+// The server's reason a request failed, else the fallback
+export function errorMessage(err: unknown, fallback: string): string {
+  return err instanceof HttpErrorResponse && typeof err.error?.error === 'string' ? err.error.error : fallback;
+}
+`)
+	if ir.ProtectsEdits(app) {
+		writeConflictHelper(&b)
+	}
+	if hasReports(app) {
+		writeReportType(&b)
+	}
+	if ir.HasImports(app) {
+		writeImportTypes(&b)
+	}
+	if ir.AccountModel(app) != nil {
+		writeProfileType(&b, app)
+	}
+	if ir.StoresUTC(app) {
+		writeTimeClient(&b)
+	}
+	if len(app.Charts) > 0 {
+		b.WriteString(`
+export interface ChartPoint {
+  label: string;
+  value: number;
+}
+`)
+	}
+	b.WriteString(`
 @Injectable({ providedIn: 'root' })
 export class ApiService {
   private http = inject(HttpClient);
-  private baseUrl = ''; // Set via environment
+  private baseUrl = '` + apiBaseURL(app) + `';
 
   private getHeaders(): HttpHeaders {
     let headers = new HttpHeaders({ 'Content-Type': 'application/json' });
@@ -75,9 +123,25 @@ export class ApiService {
 
 	for _, ep := range app.APIs {
 		b.WriteString("\n")
+		if m := ir.ConflictModel(app, ep); m != nil && len(ep.Params) > 0 {
+			writeVersionedUpdateMethod(&b, ep, m, requestBody(app))
+			continue
+		}
+		if ep.Import != nil {
+			writeImportMethod(&b, ep)
+			continue
+		}
+		if ep.Account != "" && app.Accounts != nil {
+			writeAccountMethod(&b, ep, app)
+			continue
+		}
 		funcName := toCamelCase(ep.Name)
 		method := httpMethod(ep.Name)
 		path := apiPath(ep.Name)
+		response := "unknown"
+		if ep.Aggregate != nil {
+			response = "ReportRow[]"
+		}
 
 		if len(ep.Params) > 0 {
 			paramFields := make([]string, len(ep.Params))
@@ -86,27 +150,70 @@ export class ApiService {
 				paramFields[i] = fmt.Sprintf("%s: string", paramName)
 			}
 			paramType := fmt.Sprintf("{ %s }", strings.Join(paramFields, "; "))
-			fmt.Fprintf(&b, "  %s(params: %s): Observable<ApiResponse<unknown>> {\n", funcName, paramType)
-			
-			if method == "GET" {
+			if method == "GET" && ep.PageSize > 0 {
+				// Paginated lists take the nextCursor of the page before
+				fmt.Fprintf(&b, "  %s(params: %s, cursor?: string): Observable<ApiResponse<%s>> {\n", funcName, paramType, response)
+			} else {
+				fmt.Fprintf(&b, "  %s(params: %s): Observable<ApiResponse<%s>> {\n", funcName, paramType, response)
+			}
+
+			if method == "GET" && ep.PageSize > 0 {
+				b.WriteString("    let httpParams = new HttpParams({ fromObject: params as any });\n")
+				b.WriteString("    if (cursor) httpParams = httpParams.set('cursor', cursor);\n")
+				fmt.Fprintf(&b, "    return this.http.get<ApiResponse<%s>>(`${this.baseUrl}%s`, { headers: this.getHeaders(), params: httpParams });\n", response, path)
+			} else if method == "GET" {
 				b.WriteString("    const httpParams = new HttpParams({ fromObject: params as any });\n")
-				fmt.Fprintf(&b, "    return this.http.get<ApiResponse<unknown>>(`${this.baseUrl}%s`, { headers: this.getHeaders(), params: httpParams });\n", path)
+				fmt.Fprintf(&b, "    return this.http.get<ApiResponse<%s>>(`${this.baseUrl}%s`, { headers: this.getHeaders(), params: httpParams });\n", response, path)
+			} else if method == "DELETE" {
+				// HttpClient.delete takes no body argument; it goes in the options
+				fmt.Fprintf(&b, "    return this.http.delete<ApiResponse<%s>>(`${this.baseUrl}%s`, { headers: this.getHeaders(), body: params });\n", response, path)
 			} else {
 				methodLower := strings.ToLower(method)
-				fmt.Fprintf(&b, "    return this.http.%s<ApiResponse<unknown>>(`${this.baseUrl}%s`, params, { headers: this.getHeaders() });\n", methodLower, path)
+				fmt.Fprintf(&b, "    return this.http.%s<ApiResponse<%s>>(`${this.baseUrl}%s`, %s, { headers: this.getHeaders() });\n", methodLower, response, path, requestBody(app))
 			}
+		} else if method == "GET" && ep.PageSize > 0 {
+			// and the page's search, filters, sort, and page
+			fmt.Fprintf(&b, "  %s(cursor?: string, query: Record<string, string> = {}): Observable<ApiResponse<%s>> {\n", funcName, response)
+			b.WriteString("    let params = new HttpParams({ fromObject: query });\n")
+			b.WriteString("    if (cursor) params = params.set('cursor', cursor);\n")
+			fmt.Fprintf(&b, "    return this.http.get<ApiResponse<%s>>(`${this.baseUrl}%s`, { headers: this.getHeaders(), params });\n", response, path)
 		} else {
-			fmt.Fprintf(&b, "  %s(): Observable<ApiResponse<unknown>> {\n", funcName)
+			fmt.Fprintf(&b, "  %s(): Observable<ApiResponse<%s>> {\n", funcName, response)
 			methodLower := strings.ToLower(method)
 			if method == "GET" || method == "DELETE" {
-				fmt.Fprintf(&b, "    return this.http.%s<ApiResponse<unknown>>(`${this.baseUrl}%s`, { headers: this.getHeaders() });\n", methodLower, path)
+				fmt.Fprintf(&b, "    return this.http.%s<ApiResponse<%s>>(`${this.baseUrl}%s`, { headers: this.getHeaders() });\n", methodLower, response, path)
 			} else {
-				fmt.Fprintf(&b, "    return this.http.%s<ApiResponse<unknown>>(`${this.baseUrl}%s`, {}, { headers: this.getHeaders() });\n", methodLower, path)
+				fmt.Fprintf(&b, "    return this.http.%s<ApiResponse<%s>>(`${this.baseUrl}%s`, {}, { headers: this.getHeaders() });\n", methodLower, response, path)
 			}
 		}
 		b.WriteString("  }\n")
 	}
 
+	if len(app.Notifications) > 0 {
+		writeNotificationsMethods(&b)
+	}
+	if len(app.Calendars) > 0 {
+		writeCalendarMethods(&b)
+	}
+	if len(app.Charts) > 0 {
+		writeChartMethods(&b)
+	}
+	if len(app.Reorders) > 0 {
+		writeReorderMethods(&b)
+	}
+	if ir.HasImports(app) {
+		writeUploadImportMethod(&b)
+	}
+
 	b.WriteString("}\n")
 	return b.String()
 }
+
+// apiBaseURL returns the origin the API service calls: the API of the
+// environment the build targets, or "" for the dev server's proxy.
+func apiBaseURL(app *ir.Application) string {
+	if env := ir.TargetEnvironment(app); env != nil {
+		return env.APIURL(app)
+	}
+	return ""
+}
@@ -0,0 +1,227 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeConflictHelper writes the function reading the record as it is now
+// off an update refused because someone else saved it first.
+func writeConflictHelper(b *strings.Builder) {
+	b.WriteString(`
+// The record as it is now when an update was refused because someone else
+// saved it first, else null
+export function conflictingRecord(err: unknown): unknown {
+  return err instanceof HttpErrorResponse && err.status === 409 ? err.error?.current ?? null : null;
+}
+`)
+}
+
+// writeVersionedUpdateMethod writes the service method of an update to a
+// record protected against conflicting edits: it takes the record's fields
+// as the model types them, and sends the version of the record the changes
+// were made to in an If-Match header.
+func writeVersionedUpdateMethod(b *strings.Builder, ep *ir.Endpoint, m *ir.DataModel, body string) {
+	paramFields := make([]string, len(ep.Params))
+	for i, p := range ep.Params {
+		paramType := "string"
+		if f := m.FieldNamed(p.Name); f != nil && f.Type != "enum" && f.Type != "json" {
+			paramType = tsType(f.Type)
+		}
+		paramFields[i] = fmt.Sprintf("%s: %s", toCamelCase(p.Name), paramType)
+	}
+	fmt.Fprintf(b, "  %s(params: { %s }, version: number): Observable<ApiResponse<unknown>> {\n", toCamelCase(ep.Name), strings.Join(paramFields, "; "))
+	b.WriteString("    const headers = this.getHeaders().set('If-Match', `\"${version}\"`);\n")
+	fmt.Fprintf(b, "    return this.http.%s<ApiResponse<unknown>>(`${this.baseUrl}%s`, %s, { headers });\n", strings.ToLower(httpMethod(ep.Name)), apiPath(ep.Name), body)
+	b.WriteString("  }\n")
+}
+
+// editInputs returns the inputs of the form editing the record a detail
+// route's page shows, one per field its Update endpoint takes.
+func editInputs(e *ir.EditForm) []formInput {
+	formID := toKebabCase(e.Model.Name) + "-edit"
+	var inputs []formInput
+	for _, f := range e.Fields {
+		in := formInput{
+			id:        formID + "-" + toKebabCase(toCamelCase(f.Name)),
+			name:      toCamelCase(f.Name),
+			label:     ir.FieldLabel(f.Name),
+			inputType: inputType(f.Name, f),
+			required:  f.Required && f.Type != "boolean",
+		}
+		if f.Type == "boolean" {
+			in.inputType = "checkbox"
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
+
+// writeEditState declares the state of the form editing the record a
+// detail route's page shows, filling the form in with the record whenever
+// it loads or is saved.
+func writeEditState(b *strings.Builder, ctx *pageContext) {
+	e := ctx.edit
+	v := recordVar(ctx)
+	b.WriteString("  editError = signal('');\n")
+	if e.Versioned {
+		fmt.Fprintf(b, "  conflict = signal<{ current: %s; changes: Parameters<ApiService['%s']>[0] } | null>(null);\n", e.Model.Name, toCamelCase(e.Endpoint.Name))
+	}
+	var filled []string
+	for _, f := range e.Fields {
+		value := editValue(v, f)
+		if inputType(f.Name, f) == "datetime-local" {
+			if local := localTimeValue(v+"."+f.Name, ctx); local != "" {
+				value = local
+			}
+		}
+		filled = append(filled, fmt.Sprintf("%s: %s", toCamelCase(f.Name), value))
+	}
+	b.WriteString("  private fillForm = effect(() => {\n")
+	fmt.Fprintf(b, "    const %s = this.%s();\n", v, v)
+	fmt.Fprintf(b, "    if (%s) this.form.reset({ %s });\n", v, strings.Join(filled, ", "))
+	b.WriteString("  });\n")
+}
+
+// writeEditMethods emits the methods saving the record a detail route's
+// page shows. Saved changes replace the record shown. A versioned record's
+// changes are made to the version the form was filled in from; when
+// someone else saved first, the changes are held until the user keeps
+// them, saving them over the record as it is now, or uses the saved record
+// instead.
+func writeEditMethods(b *strings.Builder, ctx *pageContext) {
+	e := ctx.edit
+	v := recordVar(ctx)
+	fn := toCamelCase(e.Endpoint.Name)
+	label := strings.ToLower(ir.FieldLabel(e.Model.Name))
+	changes := fmt.Sprintf("Parameters<ApiService['%s']>[0]", fn)
+	if e.Versioned {
+		fmt.Fprintf(b, "\n  save%s(changes: %s, version: number) {\n", e.Model.Name, changes)
+	} else {
+		fmt.Fprintf(b, "\n  save%s(changes: %s) {\n", e.Model.Name, changes)
+	}
+	b.WriteString("    this.editError.set('');\n")
+	if e.Versioned {
+		fmt.Fprintf(b, "    this.api.%s(changes, version).subscribe({\n", fn)
+		b.WriteString("      next: (res) => {\n")
+		b.WriteString("        this.conflict.set(null);\n")
+		fmt.Fprintf(b, "        this.%s.set(res.data as %s);\n", v, e.Model.Name)
+		b.WriteString("      },\n")
+		b.WriteString("      error: (err) => {\n")
+		b.WriteString("        const current = conflictingRecord(err);\n")
+		fmt.Fprintf(b, "        if (current) this.conflict.set({ current: current as %s, changes });\n", e.Model.Name)
+		fmt.Fprintf(b, "        else this.editError.set(errorMessage(err, 'Could not save the %s'));\n", label)
+		b.WriteString("      },\n")
+	} else {
+		fmt.Fprintf(b, "    this.api.%s(changes).subscribe({\n", fn)
+		fmt.Fprintf(b, "      next: (res) => this.%s.set(res.data as %s),\n", v, e.Model.Name)
+		fmt.Fprintf(b, "      error: (err) => this.editError.set(errorMessage(err, 'Could not save the %s')),\n", label)
+	}
+	b.WriteString("    });\n")
+	b.WriteString("  }\n")
+
+	fmt.Fprintf(b, "\n  submit%s() {\n", e.Model.Name)
+	fmt.Fprintf(b, "    const %s = this.%s();\n", v, v)
+	fmt.Fprintf(b, "    if (!%s || !this.checkForm()) return;\n", v)
+	b.WriteString("    const value = this.form.value;\n")
+	fmt.Fprintf(b, "    this.save%s({\n", e.Model.Name)
+	for _, p := range e.Endpoint.Params {
+		name := toCamelCase(p.Name)
+		f := editField(e, p.Name)
+		switch {
+		case p.Name == e.Param:
+			fmt.Fprintf(b, "      %s: %s.id,\n", name, v)
+		case f == nil:
+			fmt.Fprintf(b, "      %s: '',\n", name)
+		case e.Versioned || tsType(f.Type) == "string":
+			fmt.Fprintf(b, "      %s: value.%s,\n", name, toCamelCase(f.Name))
+		default:
+			fmt.Fprintf(b, "      %s: String(value.%s),\n", name, toCamelCase(f.Name))
+		}
+	}
+	if e.Versioned {
+		fmt.Fprintf(b, "    }, %s.%s);\n", v, ir.VersionColumn)
+	} else {
+		b.WriteString("    });\n")
+	}
+	b.WriteString("  }\n")
+
+	if e.Versioned {
+		fmt.Fprintf(b, "\n  useTheir%s() {\n", e.Model.Name)
+		b.WriteString("    const conflict = this.conflict();\n")
+		b.WriteString("    if (!conflict) return;\n")
+		fmt.Fprintf(b, "    this.%s.set(conflict.current);\n", v)
+		b.WriteString("    this.conflict.set(null);\n")
+		b.WriteString("  }\n")
+	}
+}
+
+// writeEditFormNG renders the form editing the record a detail route's
+// page shows. A versioned record's form is followed by the prompt shown
+// when someone else saved it first.
+func writeEditFormNG(b *strings.Builder, indent string, ctx *pageContext) {
+	e := ctx.edit
+	v := recordVar(ctx)
+	label := strings.ToLower(ir.FieldLabel(e.Model.Name))
+	fmt.Fprintf(b, "%s@if (%s()) {\n", indent, v)
+	fmt.Fprintf(b, "%s  <form class=\"form edit-form\" aria-label=\"Edit %s\" [formGroup]=\"form\" (ngSubmit)=\"submit%s()\">\n", indent, label, e.Model.Name)
+	for _, in := range editInputs(e) {
+		in.id = ctx.uniqueID(in.id)
+		writeFormFieldNG(b, indent+"    ", in, ctx)
+	}
+	fmt.Fprintf(b, "%s    @if (editError()) {\n", indent)
+	fmt.Fprintf(b, "%s      <p class=\"form-error\" role=\"alert\">{{ editError() }}</p>\n", indent)
+	fmt.Fprintf(b, "%s    }\n", indent)
+	fmt.Fprintf(b, "%s    <button type=\"submit\">Save</button>\n", indent)
+	fmt.Fprintf(b, "%s  </form>\n", indent)
+	fmt.Fprintf(b, "%s}\n", indent)
+	if !e.Versioned {
+		return
+	}
+	fmt.Fprintf(b, "%s@if (conflict(); as conflict) {\n", indent)
+	fmt.Fprintf(b, "%s  <div class=\"conflict-dialog\" role=\"alertdialog\" aria-modal=\"true\" aria-labelledby=\"conflict-title\" aria-describedby=\"conflict-description\">\n", indent)
+	fmt.Fprintf(b, "%s    <h2 id=\"conflict-title\">This %s changed while you were editing</h2>\n", indent, label)
+	fmt.Fprintf(b, "%s    <p id=\"conflict-description\">Someone else saved the %s after you opened it. Keep your changes to save them over theirs, or use their version and discard yours.</p>\n", indent, label)
+	fmt.Fprintf(b, "%s    <button autofocus (click)=\"save%s(conflict.changes, conflict.current.%s)\">Keep my changes</button>\n", indent, e.Model.Name, ir.VersionColumn)
+	fmt.Fprintf(b, "%s    <button (click)=\"useTheir%s()\">Use their version</button>\n", indent, e.Model.Name)
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// recordVar returns the signal holding the record a detail route's page
+// shows.
+func recordVar(ctx *pageContext) string {
+	m := ctx.record.Model
+	return strings.ToLower(m[:1]) + m[1:]
+}
+
+// editField returns the field of an edit form an endpoint parameter fills.
+func editField(e *ir.EditForm, param string) *ir.DataField {
+	for _, f := range e.Fields {
+		if strings.EqualFold(f.Name, param) {
+			return f
+		}
+	}
+	return nil
+}
+
+// editValue returns the expression filling an edit form's control for f
+// in from the record v.
+func editValue(v string, f *ir.DataField) string {
+	ref := v + "." + f.Name
+	switch inputType(f.Name, f) {
+	case "date":
+		return ref + "?.slice(0, 10) ?? ''"
+	case "datetime-local":
+		return ref + "?.slice(0, 16) ?? ''"
+	}
+	switch {
+	case f.Type == "boolean":
+		return ref + " ?? false"
+	case tsType(f.Type) == "number":
+		return ref + " ?? 0"
+	}
+	return ref + " ?? ''"
+}
@@ -0,0 +1,43 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+)
+
+// generateGlobalErrorHandler produces src/app/services/global-error-handler.ts:
+// the app's ErrorHandler, which logs errors no page handled and keeps the
+// latest for the app component to show.
+func generateGlobalErrorHandler() string {
+	return `// Generated by Human compiler — do not edit
+
+import { ErrorHandler, Injectable, signal } from '@angular/core';
+
+@Injectable({ providedIn: 'root' })
+export class GlobalErrorHandler implements ErrorHandler {
+  readonly error = signal<string | null>(null);
+
+  handleError(error: unknown): void {
+    console.error(error);
+    this.error.set(error instanceof Error ? error.message : String(error));
+  }
+}
+`
+}
+
+// loadFailed returns the statement recording why a load failed: the
+// server's message, else the page's.
+func loadFailed(ctx *pageContext) string {
+	return fmt.Sprintf("this.loadError.set(errorMessage(err, '%s'))", strings.ReplaceAll(ctx.loadError, "'", "\\'"))
+}
+
+// writeLoadErrorNG renders why loading the page's data failed, with a
+// button loading it again.
+func writeLoadErrorNG(b *strings.Builder, indent string, ctx *pageContext) {
+	fmt.Fprintf(b, "%s@if (loadError()) {\n", indent)
+	fmt.Fprintf(b, "%s  <div class=\"error-state\" role=\"alert\">\n", indent)
+	fmt.Fprintf(b, "%s    <p>{{ loadError() }}</p>\n", indent)
+	fmt.Fprintf(b, "%s    <button (click)=\"%s\">Try again</button>\n", indent, ctx.retry)
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s}\n", indent)
+}
@@ -0,0 +1,99 @@
+package angular
+
+import (
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// controlExperiment returns the experiment whose control (first variant)
+// is the given page. The control page's route serves the experiment.
+func controlExperiment(app *ir.Application, pageName string) *ir.Experiment {
+	for _, exp := range app.Experiments {
+		if len(exp.Variants) > 0 && strings.EqualFold(exp.Variants[0].Page, pageName) {
+			return exp
+		}
+	}
+	return nil
+}
+
+// variantPage returns the declared name of a variant's page, matching
+// case-insensitively as the analyzer does.
+func variantPage(app *ir.Application, name string) string {
+	for _, page := range app.Pages {
+		if strings.EqualFold(page.Name, name) {
+			return page.Name
+		}
+	}
+	return name
+}
+
+// generateExperimentsClient produces src/app/experiments/experiments.ts, which loads
+// the visitor's variant assignments and reports exposures to the backend.
+func generateExperimentsClient() string {
+	return `// Generated by Human compiler — do not edit
+
+const API_BASE_URL = ''; // Same origin as ApiService
+const VISITOR_KEY = 'hx_visitor';
+
+let assignments: Promise<Record<string, string>> | null = null;
+const exposed = new Set<string>();
+
+function visitorHeaders(): Record<string, string> {
+  const id = localStorage.getItem(VISITOR_KEY);
+  return id ? { 'X-Visitor-Id': id } : {};
+}
+
+// fetchAssignments loads the visitor's variants once per page load. The
+// backend does the bucketing; keeping its visitor id makes assignments
+// sticky even when cookies do not reach a cross-origin API.
+export function fetchAssignments(): Promise<Record<string, string>> {
+  if (!assignments) {
+    assignments = fetch(` + "`${API_BASE_URL}/api/experiments`" + `, { headers: visitorHeaders() })
+      .then((res) => res.json())
+      .then(({ data }) => {
+        localStorage.setItem(VISITOR_KEY, data.visitorId);
+        return data.assignments as Record<string, string>;
+      })
+      .catch(() => ({}));
+  }
+  return assignments;
+}
+
+export async function getVariant(experiment: string): Promise<string | undefined> {
+  return (await fetchAssignments())[experiment];
+}
+
+export function logExposure(experiment: string): void {
+  if (exposed.has(experiment)) return;
+  exposed.add(experiment);
+  fetch(` + "`${API_BASE_URL}/api/experiments/${encodeURIComponent(experiment)}/exposure`" + `, {
+    method: 'POST',
+    headers: visitorHeaders(),
+  }).catch(() => {});
+}
+`
+}
+
+// generateVariantGuard produces src/app/experiments/variant.guard.ts. Each
+// variant gets a route on the control page's path, and the guard lets only
+// the visitor's assigned variant match.
+func generateVariantGuard() string {
+	return `// Generated by Human compiler — do not edit
+
+import { CanMatchFn } from '@angular/router';
+import { getVariant, logExposure } from './experiments';
+
+// variantGuard matches a route only for visitors assigned to the variant.
+// The control also matches when no assignment is available, so the
+// experiment's URL always resolves.
+export function variantGuard(experiment: string, variant: string, control: string): CanMatchFn {
+  return async () => {
+    const assigned = await getVariant(experiment);
+    const matches = assigned ? assigned === variant : variant === control;
+    if (matches && assigned) logExposure(experiment);
+    return matches;
+  };
+}
+`
+}
@@ -0,0 +1,110 @@
+package angular
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateFormat produces src/app/utils/format.ts: the helpers showing numbers
+// in the theme's locale, as money in its currency, percentages, compact
+// counts, and plain numbers.
+func generateFormat(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	fmt.Fprintf(&b, "const LOCALE = '%s';\n\n", ir.Locale(app))
+	if currency := ir.Currency(app); currency != "" {
+		fmt.Fprintf(&b, "const money = new Intl.NumberFormat(LOCALE, { style: 'currency', currency: '%s' });\n", currency)
+	} else {
+		b.WriteString("// No currency is set, so money is shown as an amount without one\n")
+		b.WriteString("const money = new Intl.NumberFormat(LOCALE, { minimumFractionDigits: 2, maximumFractionDigits: 2 });\n")
+	}
+	b.WriteString(`const percent = new Intl.NumberFormat(LOCALE, { style: 'percent', maximumFractionDigits: 1 });
+const compact = new Intl.NumberFormat(LOCALE, { notation: 'compact', maximumFractionDigits: 1 });
+const plain = new Intl.NumberFormat(LOCALE);
+
+// Decimals may arrive as strings, so they keep their precision over JSON
+function show(format: Intl.NumberFormat, value: number | string | null | undefined, scale = 1): string {
+  if (value === null || value === undefined || value === '') return '';
+  const n = Number(value);
+  return Number.isNaN(n) ? String(value) : format.format(n * scale);
+}
+
+export function formatMoney(value: number | string | null | undefined): string {
+  return show(money, value);
+}
+
+// A percentage holds whole percents: 45 is 45%
+export function formatPercent(value: number | string | null | undefined): string {
+  return show(percent, value, 0.01);
+}
+
+export function formatCompact(value: number | string | null | undefined): string {
+  return show(compact, value);
+}
+
+export function formatNumber(value: number | string | null | undefined): string {
+  return show(plain, value);
+}
+`)
+	return b.String()
+}
+
+// numberHelpers names the helper showing each number format.
+var numberHelpers = map[string]string{
+	ir.NumberMoney:   "formatMoney",
+	ir.NumberPercent: "formatPercent",
+	ir.NumberCompact: "formatCompact",
+	ir.NumberPlain:   "formatNumber",
+}
+
+// numberNG returns the template showing a numeric field in the theme's
+// locale, or "" when f isn't a number or the theme sets no locale.
+func numberNG(expr string, f *ir.DataField, ctx *pageContext) string {
+	helper := numberHelpers[ir.NumberFormat(f)]
+	if helper == "" || ir.Locale(ctx.app) == "" {
+		return ""
+	}
+	if ctx.formatHelpers == nil {
+		ctx.formatHelpers = map[string]bool{}
+	}
+	ctx.formatHelpers[helper] = true
+	return fmt.Sprintf("{{ %s(%s) }}", helper, expr)
+}
+
+// numberClass returns the class of the element showing a numeric field.
+func numberClass(f *ir.DataField) string {
+	if ir.NumberFormat(f) == ir.NumberCompact {
+		return "count"
+	}
+	return "number"
+}
+
+// formatHelperNames returns the number helpers the page uses, in order.
+func formatHelperNames(ctx *pageContext) []string {
+	var names []string
+	for name := range ctx.formatHelpers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeFormatMembers exposes the number helpers the page's template uses
+// to it.
+func writeFormatMembers(b *strings.Builder, ctx *pageContext) {
+	for _, name := range formatHelperNames(ctx) {
+		fmt.Fprintf(b, "  readonly %s = %s;\n", name, name)
+	}
+}
+
+// withFormatImports adds the number helpers a page used to its imports.
+func withFormatImports(out string, ctx *pageContext) string {
+	names := formatHelperNames(ctx)
+	if len(names) == 0 {
+		return out
+	}
+	return strings.Replace(out, "\n@Component(", fmt.Sprintf("import { %s } from '../../utils/format';\n\n@Component(", strings.Join(names, ", ")), 1)
+}
@@ -0,0 +1,190 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// formInput is one labelled input of a generated form.
+type formInput struct {
+	id        string // unique on the page, e.g. "task-form-title"
+	name      string // the name it's submitted under
+	label     string
+	inputType string
+	required  bool
+}
+
+// formInputs returns the inputs of a form for its fields. A field is
+// required when the form's model declares it so, except a yes/no field,
+// which can be left empty; every field of a login form is required.
+func formInputs(formID string, fields []string, login bool, model *ir.DataModel) []formInput {
+	var inputs []formInput
+	for _, f := range fields {
+		in := formInput{
+			id:        formID + "-" + toKebabCase(toCamelCase(f)),
+			name:      toCamelCase(f),
+			label:     capitalize(f),
+			inputType: inputType(f, nil),
+			required:  login,
+		}
+		if model != nil {
+			if field := model.FieldNamed(f); field != nil {
+				in.inputType = inputType(f, field)
+				in.required = field.Required && field.Type != "boolean"
+			}
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
+
+// formModel returns the model a form creates or edits: the one it names,
+// "a form to create a Task", or else the page's.
+func formModel(lower string, ctx *pageContext) *ir.DataModel {
+	for _, marker := range []string{"form to update ", "form to create ", "form to edit "} {
+		if idx := strings.Index(lower, marker); idx != -1 {
+			rest := strings.TrimPrefix(strings.TrimPrefix(lower[idx+len(marker):], "a "), "an ")
+			if m := findModel(ctx.app, strings.TrimPrefix(strings.TrimSpace(rest), "new ")); m != nil {
+				return m
+			}
+		}
+	}
+	return findModel(ctx.app, ctx.modelName)
+}
+
+// inputType returns the type of the input a form field is entered in, by
+// the model's type for it when known or else by its name.
+func inputType(name string, field *ir.DataField) string {
+	if field != nil {
+		switch field.Type {
+		case "email", "date", "number":
+			return field.Type
+		case "decimal":
+			return "number"
+		case "datetime":
+			return "datetime-local"
+		}
+	}
+	fl := strings.ToLower(name)
+	switch {
+	case strings.Contains(fl, "email"):
+		return "email"
+	case strings.Contains(fl, "password"):
+		return "password"
+	case strings.Contains(fl, "date"):
+		return "date"
+	case strings.Contains(fl, "number") || strings.Contains(fl, "count"):
+		return "number"
+	}
+	return "text"
+}
+
+// inputLabel returns the accessible name of an input described by text:
+// what it's for, e.g. "Event name" for "there is a text input for event
+// name", or the text itself.
+func inputLabel(text string) string {
+	if i := strings.LastIndex(strings.ToLower(text), " for "); i >= 0 && i+5 < len(text) {
+		return capitalize(strings.TrimSpace(text[i+5:]))
+	}
+	return text
+}
+
+// uniqueID returns an id for an element of the page, numbering the ones
+// after the first that would share it.
+func (ctx *pageContext) uniqueID(base string) string {
+	if ctx.ids == nil {
+		ctx.ids = map[string]int{}
+	}
+	ctx.ids[base]++
+	if n := ctx.ids[base]; n > 1 {
+		return fmt.Sprintf("%s-%d", base, n)
+	}
+	return base
+}
+
+// writeFormFieldNG renders a form input with its label, bound to the form
+// control named like the field. On a page validating its form, once the
+// input's value is found invalid it's marked so and described by the
+// message below it.
+func writeFormFieldNG(b *strings.Builder, indent string, in formInput, ctx *pageContext) {
+	invalid := fmt.Sprintf("fieldError('%s')", in.name)
+	fmt.Fprintf(b, "%s<div class=\"form-field\">\n", indent)
+	fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, in.id, in.label)
+	fmt.Fprintf(b, "%s  <input\n", indent)
+	fmt.Fprintf(b, "%s    id=\"%s\"\n", indent, in.id)
+	fmt.Fprintf(b, "%s    type=\"%s\"\n", indent, in.inputType)
+	fmt.Fprintf(b, "%s    formControlName=\"%s\"\n", indent, in.name)
+	if in.inputType != "checkbox" {
+		fmt.Fprintf(b, "%s    placeholder=\"%s\"\n", indent, in.label)
+	}
+	if in.required {
+		fmt.Fprintf(b, "%s    required\n", indent)
+	}
+	if !ctx.fieldErrors {
+		fmt.Fprintf(b, "%s  />\n", indent)
+		fmt.Fprintf(b, "%s</div>\n", indent)
+		return
+	}
+	fmt.Fprintf(b, "%s    [attr.aria-invalid]=\"!!%s\"\n", indent, invalid)
+	fmt.Fprintf(b, "%s    [attr.aria-describedby]=\"%s ? '%s-error' : null\"\n", indent, invalid, in.id)
+	fmt.Fprintf(b, "%s  />\n", indent)
+	fmt.Fprintf(b, "%s  @if (%s) {\n", indent, invalid)
+	fmt.Fprintf(b, "%s    <p id=\"%s-error\" class=\"field-error\">{{ %s }}</p>\n", indent, in.id, invalid)
+	fmt.Fprintf(b, "%s  }\n", indent)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
+
+// controlValidators returns the validators of an input's form control,
+// written after its initial value, or nothing when it has none.
+func controlValidators(in formInput) string {
+	var vs []string
+	if in.required {
+		vs = append(vs, "Validators.required")
+	}
+	if in.inputType == "email" {
+		vs = append(vs, "Validators.email")
+	}
+	switch len(vs) {
+	case 0:
+		return ""
+	case 1:
+		return ", " + vs[0]
+	}
+	return ", [" + strings.Join(vs, ", ") + "]"
+}
+
+// writeFieldErrorMethods declares the methods of a page validating its
+// form: why a control's value is invalid, once it's been touched, and the
+// check run as the form is submitted, which marks every control touched and
+// focuses the first invalid one.
+func writeFieldErrorMethods(b *strings.Builder) {
+	b.WriteString("\n  fieldError(name: string): string {\n")
+	b.WriteString("    const control = this.form.get(name);\n")
+	b.WriteString("    if (!control?.invalid || !control.touched) return '';\n")
+	b.WriteString("    return control.hasError('email') ? 'Enter a valid email address.' : 'Please fill in this field.';\n")
+	b.WriteString("  }\n")
+	b.WriteString("\n  checkForm(): boolean {\n")
+	b.WriteString("    if (this.form.valid) return true;\n")
+	b.WriteString("    this.form.markAllAsTouched();\n")
+	b.WriteString("    const name = Object.keys(this.form.controls).find((key) => this.form.controls[key].invalid);\n")
+	b.WriteString("    document.querySelector<HTMLElement>(`[formControlName=\"${name}\"]`)?.focus();\n")
+	b.WriteString("    return false;\n")
+	b.WriteString("  }\n")
+}
+
+// writeDialogFocus declares the form dialog's view query, moving focus into
+// it as it opens and back to whatever opened it once it closes.
+func writeDialogFocus(b *strings.Builder) {
+	b.WriteString("  private opener: HTMLElement | null = null;\n")
+	b.WriteString("  @ViewChild('dialog') set dialog(ref: ElementRef<HTMLElement> | undefined) {\n")
+	b.WriteString("    if (!ref) {\n")
+	b.WriteString("      this.opener?.focus();\n")
+	b.WriteString("      this.opener = null;\n")
+	b.WriteString("      return;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    this.opener = document.activeElement as HTMLElement | null;\n")
+	b.WriteString("    ref.nativeElement.querySelector<HTMLElement>('input, select, textarea')?.focus();\n")
+	b.WriteString("  }\n")
+}
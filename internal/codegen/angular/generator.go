@@ -7,6 +7,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/codegen/themes"
 	"github.com/barun-bash/human/internal/ir"
 )
@@ -27,18 +28,26 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 	}
 
 	files := map[string]string{
-		filepath.Join(outputDir, "package.json"):                     generatePackageJson(app),
-		filepath.Join(outputDir, "angular.json"):                     generateAngularJson(app),
-		filepath.Join(outputDir, "tsconfig.json"):                    generateTsConfig(app),
-		filepath.Join(outputDir, "src", "index.html"):                generateIndexHtml(app),
-		filepath.Join(outputDir, "src", "main.ts"):                   generateMainTs(app),
-		filepath.Join(outputDir, "src", "app", "app.config.ts"):      generateAppConfig(app),
-		filepath.Join(outputDir, "src", "app", "app.routes.ts"):      generateRoutes(app),
-		filepath.Join(outputDir, "src", "app", "app.component.ts"):   generateAppComponent(app),
-		filepath.Join(outputDir, "src", "app", "models", "types.ts"): generateTypes(app),
+		filepath.Join(outputDir, "package.json"):                             generatePackageJson(app),
+		filepath.Join(outputDir, "angular.json"):                             generateAngularJson(app),
+		filepath.Join(outputDir, "tsconfig.json"):                            generateTsConfig(app),
+		filepath.Join(outputDir, "src", "index.html"):                        generateIndexHtml(app),
+		filepath.Join(outputDir, "src", "main.ts"):                           generateMainTs(app),
+		filepath.Join(outputDir, "src", "app", "app.config.ts"):              generateAppConfig(app),
+		filepath.Join(outputDir, "src", "app", "app.routes.ts"):              generateRoutes(app),
+		filepath.Join(outputDir, "src", "app", "app.component.ts"):           generateAppComponent(app),
+		filepath.Join(outputDir, "src", "app", "models", "types.ts"):         generateTypes(app),
 		filepath.Join(outputDir, "src", "app", "services", "api.service.ts"): generateApiService(app),
 	}
 
+	// Numbers shown in the theme's locale
+	if ir.Locale(app) != "" {
+		if err := os.MkdirAll(filepath.Join(outputDir, "src", "app", "utils"), 0755); err != nil {
+			return fmt.Errorf("creating utils directory: %w", err)
+		}
+		files[filepath.Join(outputDir, "src", "app", "utils", "format.ts")] = generateFormat(app)
+	}
+
 	for _, page := range app.Pages {
 		name := toKebabCase(page.Name)
 		path := filepath.Join(outputDir, "src", "app", "pages", name, name+".component.ts")
@@ -64,6 +73,47 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		files[filepath.Join(outputDir, "src", "app", "guards", "auth.guard.ts")] = generateAuthGuard()
 	}
 
+	// Generate experiment variant routing
+	if len(app.Experiments) > 0 {
+		files[filepath.Join(outputDir, "src", "app", "experiments", "experiments.ts")] = generateExperimentsClient()
+		files[filepath.Join(outputDir, "src", "app", "experiments", "variant.guard.ts")] = generateVariantGuard()
+	}
+
+	// Generate the in-app notifications dropdown
+	if len(app.Notifications) > 0 {
+		files[filepath.Join(outputDir, "src", "app", "components", "notification-bell", "notification-bell.component.ts")] = generateNotificationBell()
+	}
+
+	// Generate the "Add to calendar" button
+	if len(app.Calendars) > 0 {
+		files[filepath.Join(outputDir, "src", "app", "components", "add-to-calendar", "add-to-calendar.component.ts")] = generateAddToCalendar()
+	}
+
+	// Generate the chart component for aggregate endpoints
+	if len(app.Charts) > 0 {
+		files[filepath.Join(outputDir, "src", "app", "components", "data-chart", "data-chart.component.ts")] = generateDataChart()
+	}
+
+	files[filepath.Join(outputDir, "src", "app", "services", "global-error-handler.ts")] = generateGlobalErrorHandler()
+
+	// Generate hover tooltips and slide-over detail panels
+	if ir.HasTooltips(app) {
+		files[filepath.Join(outputDir, "src", "app", "components", "tooltip", "tooltip.component.ts")] = generateTooltip()
+	}
+	if ir.HasDetailPanels(app) {
+		files[filepath.Join(outputDir, "src", "app", "components", "detail-panel", "detail-panel.component.ts")] = generateDetailPanel()
+	}
+
+	// Skeleton screens shaped like the records they stand in for
+	if ir.HasSkeletons(app) {
+		files[filepath.Join(outputDir, "src", "app", "components", "skeleton", "skeleton.component.ts")] = generateSkeleton(app)
+	}
+
+	// Canonical links for web apps with public pages
+	if app.Sitemap != nil {
+		files[filepath.Join(outputDir, "src", "app", "services", "canonical-link.service.ts")] = generateCanonicalLinkService(app)
+	}
+
 	// Generate theme files
 	if app.Theme != nil {
 		themeFiles := themes.GenerateAngularTheme(app.Theme)
@@ -72,24 +122,12 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		}
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
+	// Styles and tooling of the app's styling system
+	for relPath, content := range themes.StylingFiles(ir.Styling(app), "angular", app.Theme) {
+		files[filepath.Join(outputDir, relPath)] = content
 	}
 
-	return nil
-}
-
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 func toCamelCase(s string) string {
@@ -116,8 +116,8 @@ func TestGenerateComponent(t *testing.T) {
 		Data: []*ir.DataModel{{Name: "Task"}},
 	}
 	comp := &ir.Component{
-		Name: "TaskCard",
-		Props: []*ir.Prop{{Name: "task", Type: "Task"}},
+		Name:    "TaskCard",
+		Props:   []*ir.Prop{{Name: "task", Type: "Task"}},
 		Content: []*ir.Action{{Type: "interact", Text: "click"}},
 	}
 	out := generateComponent(comp, app)
@@ -380,3 +380,858 @@ func TestFullIntegration(t *testing.T) {
 		t.Error("package.json missing build-storybook script")
 	}
 }
+
+func TestAngularExperimentRoutes(t *testing.T) {
+	app := &ir.Application{
+		Name:  "Shop",
+		Pages: []*ir.Page{{Name: "Home"}, {Name: "Classic"}, {Name: "NewPricing"}},
+		Experiments: []*ir.Experiment{
+			{Name: "pricing-page", Audience: "visitors", Variants: []*ir.ExperimentVariant{{Page: "Classic", Weight: 50}, {Page: "NewPricing", Weight: 50}}},
+		},
+	}
+
+	output := generateRoutes(app)
+	variant := strings.Index(output, "canMatch: [variantGuard('pricing-page', 'NewPricing', 'Classic')]")
+	control := strings.Index(output, "canMatch: [variantGuard('pricing-page', 'Classic', 'Classic')]")
+	if variant < 0 || control < 0 {
+		t.Fatalf("routes missing variant guards:\n%s", output)
+	}
+	if variant > control {
+		t.Error("variant routes should be matched before the control route")
+	}
+	if !strings.Contains(output, "import { variantGuard } from './experiments/variant.guard';") {
+		t.Error("routes should import variantGuard")
+	}
+}
+
+func TestNotificationBellWired(t *testing.T) {
+	app := &ir.Application{
+		Name:          "TaskFlow",
+		Notifications: []*ir.Notification{{Model: "Task", Event: "assigned", Message: "Your task was assigned"}},
+	}
+
+	component := generateAppComponent(app)
+	for _, want := range []string{"imports: [CommonModule, RouterModule, NotificationBellComponent]", "<app-notification-bell></app-notification-bell>"} {
+		if !strings.Contains(component, want) {
+			t.Errorf("app.component.ts missing %q", want)
+		}
+	}
+
+	service := generateApiService(app)
+	for _, want := range []string{"import { Notification } from '../models/types';", "getUnreadNotificationCount(): Observable<ApiResponse<{ count: number }>>"} {
+		if !strings.Contains(service, want) {
+			t.Errorf("api.service.ts missing %q", want)
+		}
+	}
+}
+
+func TestAddToCalendarWired(t *testing.T) {
+	app := &ir.Application{
+		Name: "EventHub",
+		Data: []*ir.DataModel{{Name: "Event", Fields: []*ir.DataField{
+			{Name: "name", Type: "text"},
+			{Name: "date", Type: "datetime"},
+		}}},
+		Calendars: []*ir.CalendarFeed{{Model: "Event", Slug: "events", Title: "name", Start: "date", Timezone: "UTC"}},
+	}
+	page := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all events"},
+		{Type: "loop", Text: "each event shows its name and date"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"AddToCalendarComponent]", `<app-add-to-calendar feed="events" [id]="event.id"></app-add-to-calendar>`, `<app-add-to-calendar feed="events"></app-add-to-calendar>`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("home.component.ts missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateApiService(app), "calendarUrl(feed: string, id?: string): string") {
+		t.Error("api.service.ts should build calendar feed URLs")
+	}
+
+	app.Calendars = nil
+	if strings.Contains(generatePage(page, app), "app-add-to-calendar") {
+		t.Error("pages should not render the button without calendars")
+	}
+}
+
+func TestCanonicalLinkWired(t *testing.T) {
+	app := &ir.Application{
+		Name:    "Shop",
+		Pages:   []*ir.Page{{Name: "Home"}},
+		Sitemap: &ir.Sitemap{URL: "https://shop.example.com", Pages: []string{"/"}},
+	}
+
+	if !strings.Contains(generateCanonicalLinkService(app), "const SITE_URL = 'https://shop.example.com';") {
+		t.Error("canonical-link.service.ts should use the declared URL")
+	}
+	if !strings.Contains(generateAppComponent(app), "inject(CanonicalLinkService).start();") {
+		t.Error("app.component.ts should start the canonical link service")
+	}
+	app.Sitemap = nil
+	if strings.Contains(generateAppComponent(app), "CanonicalLinkService") {
+		t.Error("app.component.ts should not use the service without a sitemap")
+	}
+}
+
+func TestShowTemplate(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "User", Fields: []*ir.DataField{{Name: "name", Type: "text"}}}},
+		Pages: []*ir.Page{{Name: "Profile", Content: []*ir.Action{
+			{Type: "query", Text: "fetch all users"},
+			{Type: "display", Text: "show Hello", Template: "Hello, {user.name}! <3 {1} `code`"},
+		}}},
+		Components: []*ir.Component{{Name: "UserCard", Props: []*ir.Prop{{Name: "user", Type: "User"}}, Content: []*ir.Action{
+			{Type: "display", Text: "show Signed in", Template: "Signed in as {user.name}"},
+		}}},
+	}
+
+	if page := generatePage(app.Pages[0], app); !strings.Contains(page, "<p>Hello, {{ users()[0]?.name }}! &lt;3 &#123;1&#125; &#96;code&#96;</p>") {
+		t.Errorf("page should interpolate the first record and escape the text:\n%s", page)
+	}
+	if comp := generateComponent(app.Components[0], app); !strings.Contains(comp, "<p>Signed in as {{ user.name }}</p>") {
+		t.Errorf("component should interpolate the prop:\n%s", comp)
+	}
+}
+
+func TestTablePage(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "User", Fields: []*ir.DataField{
+			{Name: "name", Type: "text"}, {Name: "email", Type: "email"}, {Name: "active", Type: "boolean"},
+		}}},
+		Pages: []*ir.Page{
+			{Name: "Users", Content: []*ir.Action{
+				{Type: "display", Text: "show users in a table sorted by name"},
+				{Type: "interact", Text: "clicking a user navigates to UserDetail"},
+			}},
+			{Name: "UserDetail"},
+		},
+	}
+
+	page := generatePage(app.Pages[0], app)
+	for _, want := range []string{"<th>Email</th>", "(click)=\"sortBy('name')\"", "@for (user of visibleUsers(); track user.id)", "this.router.navigate(['/user-detail'], { queryParams: { id } })", "<td>{{ user.active ? 'Yes' : 'No' }}</td>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("table page missing %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, ">user</button>") {
+		t.Error("the row click should not also render a button")
+	}
+}
+
+func TestChartWired(t *testing.T) {
+	app := &ir.Application{
+		Name: "Shop",
+		Data: []*ir.DataModel{{Name: "Product", Fields: []*ir.DataField{
+			{Name: "name", Type: "text"},
+			{Name: "category", Type: "text"},
+			{Name: "current_stock", Type: "number"},
+		}}},
+		Charts: []*ir.Chart{{Kind: "bar", Title: "Stock levels by category", Slug: "stock-levels-by-category", Aggregate: ir.Aggregate{Func: "sum", Field: "current_stock", Model: "Product", GroupBy: "category"}}},
+	}
+	page := &ir.Page{Name: "Dashboard", Content: []*ir.Action{
+		{Type: "display", Text: "show a bar chart of stock levels by category"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"DataChartComponent]", `<app-data-chart kind="bar" title="Stock levels by category" source="stock-levels-by-category" />`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("dashboard.component.ts missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateApiService(app), "chart(source: string): Observable<ApiResponse<ChartPoint[]>>") {
+		t.Error("api.service.ts should fetch chart points")
+	}
+	if !strings.Contains(generateAppConfig(app), "provideAnimations()") {
+		t.Error("app.config.ts should provide the animations ngx-charts uses")
+	}
+
+	app.Charts = nil
+	if strings.Contains(generatePage(page, app), "DataChart") {
+		t.Error("pages should not render a chart the IR did not collect")
+	}
+}
+
+func TestReorderWired(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"},
+			{Name: "position", Type: "number"},
+		}}},
+		Reorders: []*ir.Reorder{{Model: "Task", Field: "position", Slug: "tasks"}},
+		Pages:    []*ir.Page{{Name: "Settings"}},
+	}
+	page := &ir.Page{Name: "Board", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of tasks"},
+		{Type: "loop", Text: "each task shows its title"},
+		{Type: "interact", Text: "dragging a task reorders the list"},
+		{Type: "interact", Text: "pressing slash focuses the search"},
+		{Type: "interact", Text: "pressing Ctrl+K navigates to Settings"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"CdkDropList, CdkDrag", `(cdkDropListDropped)="onReorder($event)"`, "@HostListener('document:keydown', ['$event'])", "this.router.navigate(['/settings']);"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("board.component.ts missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateApiService(app), "reorder(list: string, ids: string[])") {
+		t.Error("api.service.ts should save a dragged order")
+	}
+
+	app.Reorders = nil
+	if strings.Contains(generatePage(page, app), "cdkDropList") {
+		t.Error("pages should not render a draggable list the IR did not collect")
+	}
+}
+
+func TestInfiniteScrollWired(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Post", Fields: []*ir.DataField{{Name: "title", Type: "text"}}}},
+		APIs: []*ir.Endpoint{
+			{Name: "ListPosts", PageSize: 20, Steps: []*ir.Action{{Type: "query", Text: "fetch all posts"}}},
+			{Name: "CreatePost", Params: []*ir.Param{{Name: "title"}}},
+			{Name: "DeletePost", Params: []*ir.Param{{Name: "post_id"}}},
+		},
+	}
+	page := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of posts"},
+		{Type: "loop", Text: "each post shows its title"},
+		{Type: "interact", Text: "clicking New Post opens a form"},
+		{Type: "interact", Text: "clicking Remove deletes the post"},
+		{Type: "interact", Text: "scrolling to bottom loads more posts"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"export class HomeComponent implements OnInit, OnDestroy {", "@ViewChild('sentinel') sentinel?: ElementRef<HTMLElement>;", "this.api.listPosts(cursor).subscribe({", "<div #sentinel class=\"load-more\">", "this.posts.set([{ ...values, id: tempId } as Post, ...this.posts()]);", "this.api.deletePost({ post_id: post.id }).subscribe({", "(click)=\"onDelete(post); $event.stopPropagation()\">Remove</button>"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("home.component.ts missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateApiService(app), "listPosts(cursor?: string, query: Record<string, string> = {})") {
+		t.Error("the API client should fetch the page after a cursor")
+	}
+
+	app.APIs[0].PageSize = 0
+	if strings.Contains(generatePage(page, app), "sentinel") {
+		t.Error("pages should not scroll a list whose endpoint does not paginate")
+	}
+}
+
+func TestListQueryInURL(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"},
+			{Name: "status", Type: "enum", EnumValues: []string{"todo", "done"}},
+		}}},
+		APIs: []*ir.Endpoint{
+			{Name: "ListTasks", PageSize: 20, Steps: []*ir.Action{{Type: "query", Text: "fetch all tasks"}}},
+		},
+	}
+	page := &ir.Page{Name: "Tasks", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of tasks"},
+		{Type: "loop", Text: "each task shows its title"},
+		{Type: "input", Text: "there is a search bar to search tasks by title"},
+		{Type: "input", Text: "there is a dropdown to filter by status"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"private queryParams = toSignal(this.route.queryParamMap, { initialValue: this.route.snapshot.queryParamMap });",
+		"this.route.queryParamMap.subscribe(() => this.load());",
+		"this.api.listTasks(undefined, this.listQuery())",
+		"[value]=\"statusFilter()\" (change)=\"setParam('status', statusSelect.value)\"",
+		"this.router.navigate([], { relativeTo: this.route, queryParams, queryParamsHandling: 'merge' });",
+		"<nav class=\"pagination\" aria-label=\"Pagination\">",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("tasks.component.ts missing %q:\n%s", want, output)
+		}
+	}
+
+	// A list loaded whole is narrowed in the browser
+	app.APIs[0].PageSize = 0
+	output = generatePage(page, app)
+	for _, want := range []string{
+		"visibleTasks = computed(() => {",
+		"(!this.statusFilter() || String(task.status) === this.statusFilter())",
+		"@for (task of visibleTasks();",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("tasks.component.ts missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Pagination") {
+		t.Error("a list loaded whole has no pages")
+	}
+}
+
+func TestRememberedView(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"},
+			{Name: "status", Type: "enum", EnumValues: []string{"todo", "done"}},
+		}}},
+		APIs: []*ir.Endpoint{
+			{Name: "ListTasks", PageSize: 20, Steps: []*ir.Action{{Type: "query", Text: "fetch all tasks"}}},
+		},
+	}
+	page := &ir.Page{Name: "Tasks", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of tasks"},
+		{Type: "loop", Text: "each task shows its title"},
+		{Type: "input", Text: "there is a dropdown to filter by status"},
+		{Type: "configure", Text: "remember the user's filters when they come back"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { skip } from 'rxjs';",
+		"private savedScroll = Number(sessionStorage.getItem('scroll:/tasks'));",
+		"sessionStorage.setItem('view:/tasks', new URLSearchParams(params).toString())",
+		"this.route.queryParamMap.pipe(skip(restoring ? 1 : 0)).subscribe(() => this.load());",
+		"queryParams: Object.fromEntries(new URLSearchParams(saved)), replaceUrl: true",
+		"@HostListener('window:scroll')",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("tasks.component.ts missing %q:\n%s", want, output)
+		}
+	}
+
+	// A list loaded whole loads while its view is restored
+	app.APIs[0].PageSize = 0
+	output = generatePage(page, app)
+	if strings.Contains(output, "skip(") || !strings.Contains(output, "    this.restoreView();\n") {
+		t.Errorf("expected the list to load as its view is restored:\n%s", output)
+	}
+}
+
+func TestTooltipAndPanelWired(t *testing.T) {
+	user := &ir.DataModel{Name: "User", Fields: []*ir.DataField{
+		{Name: "name", Type: "text"},
+		{Name: "avatar", Type: "text"},
+		{Name: "password", Type: "text"},
+	}}
+	app := &ir.Application{
+		Data: []*ir.DataModel{user},
+		APIs: []*ir.Endpoint{{Name: "ListUsers"}},
+		Components: []*ir.Component{{Name: "MemberAvatar", Props: []*ir.Prop{{Name: "user", Type: "User"}}, Content: []*ir.Action{
+			{Type: "interact", Text: "hovering shows the user's name as a tooltip"},
+		}}},
+	}
+	page := &ir.Page{Name: "Team", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all users"},
+		{Type: "loop", Text: "each user shows their name and avatar"},
+		{Type: "interact", Text: "hovering over a user avatar shows their name"},
+		{Type: "interact", Text: "clicking a user opens a detail panel on the right"},
+	}}
+	app.Pages = []*ir.Page{page}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"TooltipComponent, DetailPanelComponent", `<app-tooltip [text]="user.name"><span>{{ user.avatar }}</span></app-tooltip>`, `(click)="selected.set(user)"`, `<app-detail-panel title="User" side="right" (closed)="selected.set(null)">`, "selected = signal<User | null>(null);", "— handled by the detail panel"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("team.component.ts missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, ".password") {
+		t.Error("the detail panel should not show passwords")
+	}
+	if comp := generateComponent(app.Components[0], app); !strings.Contains(comp, `<app-tooltip [text]="user.name">`) {
+		t.Errorf("MemberAvatar should show the user's name on hover:\n%s", comp)
+	}
+}
+
+func TestDetailRouteWired(t *testing.T) {
+	post := &ir.DataModel{Name: "Post", Fields: []*ir.DataField{
+		{Name: "title", Type: "text"},
+		{Name: "published", Type: "boolean"},
+	}}
+	home := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of posts"},
+		{Type: "loop", Text: "each post shows its title"},
+		{Type: "interact", Text: "clicking a post navigates to PostDetail"},
+	}}
+	archive := &ir.Page{Name: "Archive", Content: []*ir.Action{
+		{Type: "display", Text: "show a table of posts"},
+		{Type: "interact", Text: "clicking a post navigates to PostDetail"},
+	}}
+	detail := &ir.Page{Name: "PostDetail", Content: []*ir.Action{
+		{Type: "configure", Text: "shows the Post with the id from the url"},
+	}}
+	app := &ir.Application{
+		Data:         []*ir.DataModel{post},
+		Pages:        []*ir.Page{home, archive, detail},
+		APIs:         []*ir.Endpoint{{Name: "ListPosts"}, {Name: "GetPost", Params: []*ir.Param{{Name: "post_id"}}}},
+		DetailRoutes: []*ir.DetailRoute{{Page: "PostDetail", Model: "Post", Param: "id", Slug: "posts", Endpoint: "GetPost", Arg: "post_id"}},
+	}
+
+	if output := generateRoutes(app); !strings.Contains(output, "{ path: 'posts/:id', loadComponent:") {
+		t.Errorf("app.routes.ts should route PostDetail by id:\n%s", output)
+	}
+	output := generatePage(home, app)
+	for _, want := range []string{`(click)="openPost(post.id)"`, "this.router.navigate(['/posts', id]);", "— handled by each item's link"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("home.component.ts missing %q:\n%s", want, output)
+		}
+	}
+	if output := generatePage(archive, app); !strings.Contains(output, "this.router.navigate(['/posts', id]);") {
+		t.Errorf("archive.component.ts rows should link to /posts/<id>:\n%s", output)
+	}
+	output = generatePage(detail, app)
+	for _, want := range []string{"private route = inject(ActivatedRoute);", "this.route.snapshot.paramMap.get('id')", "this.api.getPost({ post_id: value })", "<h1>{{ post.title }}</h1>", "Post not found"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("post-detail.component.ts missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestLoadErrorWired(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Post", Fields: []*ir.DataField{{Name: "title", Type: "text"}}}},
+		APIs: []*ir.Endpoint{{Name: "ListPosts"}},
+	}
+	page := &ir.Page{Name: "Feed", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all posts"},
+		{Type: "loop", Text: "each post shows its title"},
+		{Type: "condition", Text: "if there is an error, show Could not reach the journal"},
+	}}
+	app.Pages = []*ir.Page{page}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"import { ApiService, errorMessage } from '../../services/api.service';", "ngOnInit() {\n    this.load();\n  }", "error: (err) => { this.loadError.set(errorMessage(err, 'Could not reach the journal')); this.loading.set(false); },", `<button (click)="load()">Try again</button>`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("feed.component.ts missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "@if (loadError())") < strings.Index(output, "alert-error") {
+		t.Error("the load error should show where the page declares its error state")
+	}
+	if service := generateApiService(app); !strings.Contains(service, "export function errorMessage(err: unknown, fallback: string): string {") {
+		t.Errorf("api.service.ts should read the server's reason from failed requests:\n%s", service)
+	}
+	if config := generateAppConfig(app); !strings.Contains(config, "{ provide: ErrorHandler, useExisting: GlobalErrorHandler }") {
+		t.Errorf("app.config.ts should provide the global error handler:\n%s", config)
+	}
+	if root := generateAppComponent(app); !strings.Contains(root, "@if (errors.error(); as message)") {
+		t.Errorf("app.component.ts should show unhandled errors:\n%s", root)
+	}
+}
+
+func TestSkeletonShapedLikeItems(t *testing.T) {
+	app := &ir.Application{
+		Theme: &ir.Theme{BorderRadius: "rounded"},
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"}, {Name: "status", Type: "text"}, {Name: "created", Type: "date"},
+		}}},
+		APIs: []*ir.Endpoint{{Name: "ListTasks"}},
+	}
+	list := &ir.Page{Name: "Board", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "condition", Text: "while loading, show a skeleton screen"},
+		{Type: "loop", Text: "each task shows its title, status and created"},
+	}}
+	table := &ir.Page{Name: "Archive", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "condition", Text: "while loading, show a skeleton screen"},
+		{Type: "display", Text: "show a table of tasks"},
+	}}
+	app.Pages = []*ir.Page{list, table}
+
+	output := generatePage(list, app)
+	for _, want := range []string{"import { SkeletonComponent } from '../../components/skeleton/skeleton.component';", `<div class="task-item">`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("board.component.ts missing %q:\n%s", want, output)
+		}
+	}
+	heading := strings.Index(output, `<app-skeleton shape="heading" />`)
+	badge := strings.Index(output, `<app-skeleton shape="badge" />`)
+	date := strings.Index(output, `<app-skeleton shape="date" />`)
+	if heading < 0 || badge < heading || date < badge {
+		t.Errorf("the skeleton should have a heading, badge, and date like each task:\n%s", output)
+	}
+	if strings.Contains(output, "skeleton-item") {
+		t.Errorf("the skeleton should not fall back to generic placeholders:\n%s", output)
+	}
+	if output := generatePage(table, app); !strings.Contains(output, `<td><app-skeleton shape="heading" /></td>`) {
+		t.Errorf("the table's skeleton should have a placeholder per column:\n%s", output)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "src", "app", "components", "skeleton", "skeleton.component.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "var(--radius, 12px)") {
+		t.Errorf("the skeleton should be themed from the app's theme:\n%s", data)
+	}
+}
+
+func TestResponsivePage(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Post", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"}, {Name: "created", Type: "date"},
+		}}},
+		APIs: []*ir.Endpoint{{Name: "ListPosts"}},
+	}
+	page := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "configure", Text: "make the layout responsive for mobile and tablet"},
+		{Type: "query", Text: "fetch all posts"},
+		{Type: "loop", Text: "each post shows its title and created"},
+	}}
+	app.Pages = []*ir.Page{page}
+
+	output := generatePage(page, app)
+	if strings.Contains(output, "TODO: make the layout responsive") {
+		t.Errorf("the responsive layout should be handled:\n%s", output)
+	}
+	for _, want := range []string{"styles: [`", ".home-page .post-item {", "grid-template-columns: minmax(0, 2fr) auto;", "@container (max-width: 639px) {", "`],"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("home.component.ts missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestStylingTailwind(t *testing.T) {
+	app := &ir.Application{Config: &ir.BuildConfig{Frontend: "Angular", Styling: "Tailwind"}}
+	if output := generateAngularJson(app); !strings.Contains(output, `"styles": ["src/styles.css", "src/tailwind.css"],`) {
+		t.Errorf("angular.json should build the Tailwind stylesheet:\n%s", output)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"tailwind.config.js", "src/tailwind.css"} {
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Errorf("expected %s: %v", path, err)
+		}
+	}
+}
+
+func TestAccessibleForm(t *testing.T) {
+	app := &ir.Application{
+		Name: "TestApp",
+		Data: []*ir.DataModel{
+			{Name: "Task", Fields: []*ir.DataField{
+				{Name: "title", Type: "text", Required: true},
+				{Name: "due", Type: "date", Required: true},
+				{Name: "done", Type: "boolean", Required: true},
+			}},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "ListTasks", Steps: []*ir.Action{{Type: "query", Text: "fetch all Tasks"}}},
+			{Name: "CreateTask", Params: []*ir.Param{{Name: "title"}, {Name: "due"}, {Name: "done"}}},
+		},
+		Pages: []*ir.Page{
+			{Name: "Dashboard", Content: []*ir.Action{
+				{Type: "query", Text: "fetch all Tasks"},
+				{Type: "loop", Text: "each task shows its title"},
+				{Type: "interact", Text: "clicking Add opens a form"},
+				{Type: "input", Text: "there is a form to create a Task"},
+				{Type: "condition", Text: "if creation succeeds, show \"Saved\""},
+				{Type: "condition", Text: "if there is an error, show the error"},
+			}},
+		},
+	}
+
+	output := generatePage(app.Pages[0], app)
+
+	for _, want := range []string{
+		// Each input is labelled and validated
+		`<label for="task-form-title">Title</label>`,
+		`[attr.aria-invalid]="!!fieldError('title')"`,
+		`<p id="task-form-title-error" class="field-error">{{ fieldError('title') }}</p>`,
+		"title: ['', Validators.required],",
+		"done: [''],",
+		"if (!this.checkForm()) return;",
+		"this.form.markAllAsTouched();",
+		// The modal is a dialog, focused as it opens
+		"#dialog",
+		`role="dialog"`,
+		`aria-labelledby="new-task-title"`,
+		`(keydown.escape)="showForm.set(false)"`,
+		`aria-label="Close"`,
+		"@ViewChild('dialog') set dialog(ref: ElementRef<HTMLElement> | undefined) {",
+		// Alerts are announced
+		`<div role="status">`,
+		`<div role="alert">`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("page should contain %q", want)
+		}
+	}
+}
+
+func TestEditFormWired(t *testing.T) {
+	post := &ir.DataModel{Name: "Post", Versioned: true, Fields: []*ir.DataField{
+		{Name: "title", Type: "text", Required: true},
+		{Name: "published", Type: "boolean"},
+	}}
+	detail := &ir.Page{Name: "PostDetail", Content: []*ir.Action{
+		{Type: "configure", Text: "shows the Post with the id from the url"},
+		{Type: "input", Text: "there is a form to edit the post"},
+	}}
+	app := &ir.Application{
+		Data:  []*ir.DataModel{post},
+		Pages: []*ir.Page{detail},
+		APIs: []*ir.Endpoint{
+			{Name: "GetPost", Params: []*ir.Param{{Name: "post_id"}}},
+			{Name: "UpdatePost", Params: []*ir.Param{{Name: "post_id"}, {Name: "title"}, {Name: "published"}}},
+		},
+		DetailRoutes: []*ir.DetailRoute{{Page: "PostDetail", Model: "Post", Param: "id", Slug: "posts", Endpoint: "GetPost", Arg: "post_id"}},
+	}
+
+	output := generatePage(detail, app)
+	for _, want := range []string{
+		"import { ApiService, errorMessage, conflictingRecord } from '../../services/api.service';",
+		"if (post) this.form.reset({ title: post.title ?? '', published: post.published ?? false });",
+		"<form class=\"form edit-form\" aria-label=\"Edit post\" [formGroup]=\"form\" (ngSubmit)=\"submitPost()\">",
+		"type=\"checkbox\"",
+		"}, post.version);",
+		"if (current) this.conflict.set({ current: current as Post, changes });",
+		"role=\"alertdialog\" aria-modal=\"true\" aria-labelledby=\"conflict-title\" aria-describedby=\"conflict-description\"",
+		"<button autofocus (click)=\"savePost(conflict.changes, conflict.current.version)\">Keep my changes</button>",
+		"<button (click)=\"useTheirPost()\">Use their version</button>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("post-detail.component.ts missing %q:\n%s", want, output)
+		}
+	}
+	service := generateApiService(app)
+	for _, want := range []string{
+		"export function conflictingRecord(err: unknown): unknown {",
+		"updatePost(params: { post_id: string; title: string; published: boolean }, version: number): Observable<ApiResponse<unknown>> {",
+		"const headers = this.getHeaders().set('If-Match', `\"${version}\"`);",
+	} {
+		if !strings.Contains(service, want) {
+			t.Errorf("api.service.ts missing %q:\n%s", want, service)
+		}
+	}
+	if !strings.Contains(generateTypes(app), "  version: number;\n") {
+		t.Error("the Post type should carry its version")
+	}
+
+	// Without the protection, the form just saves
+	post.Versioned = false
+	output = generatePage(detail, app)
+	if strings.Contains(output, "alertdialog") || !strings.Contains(output, "published: String(value.published),") {
+		t.Errorf("an unversioned post's form should save without a conflict prompt:\n%s", output)
+	}
+}
+
+func TestImportFormWired(t *testing.T) {
+	task := &ir.DataModel{Name: "Task", Fields: []*ir.DataField{
+		{Name: "title", Type: "text", Required: true},
+	}}
+	page := &ir.Page{Name: "Tasks", Content: []*ir.Action{
+		{Type: "input", Text: "there is a form to import tasks"},
+	}}
+	app := &ir.Application{
+		Data:  []*ir.DataModel{task},
+		Pages: []*ir.Page{page},
+		APIs: []*ir.Endpoint{{Name: "ImportTasks", Import: &ir.Import{
+			Model: "Task", Formats: []string{"csv", "json"}, Mode: ir.ImportAllOrNothing,
+		}}},
+	}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { ApiService, errorMessage, describeImport, type ImportSummary } from '../../services/api.service';",
+		"<form class=\"form import-form\" aria-label=\"Import tasks\" (submit)=\"$event.preventDefault(); runImport(false)\">",
+		"<label for=\"task-import-file\">CSV or JSON file</label>",
+		"(change)=\"chooseImportFile($event)\"",
+		"@for (problem of summary.errors; track $index) {",
+		"importSummary = signal<ImportSummary | null>(null);",
+		"this.api.importTasks(file, dryRun).subscribe({",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("tasks.component.ts missing %q:\n%s", want, output)
+		}
+	}
+	service := generateApiService(app)
+	for _, want := range []string{
+		"import { Observable, catchError, from, map, of, switchMap, throwError } from 'rxjs';",
+		"export interface ImportSummary {",
+		"importTasks(file: File, dryRun = false): Observable<ImportSummary> {",
+		"private uploadImport(path: string, file: File, dryRun: boolean): Observable<ImportSummary> {",
+	} {
+		if !strings.Contains(service, want) {
+			t.Errorf("api.service.ts missing %q:\n%s", want, service)
+		}
+	}
+}
+
+func TestTimesInUserZone(t *testing.T) {
+	event := &ir.DataModel{Name: "Event", Fields: []*ir.DataField{
+		{Name: "title", Type: "text"},
+		{Name: "starts", Type: "datetime"},
+		{Name: "day", Type: "date"},
+	}}
+	home := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of events"},
+		{Type: "loop", Text: "each event shows its title and starts"},
+	}}
+	detail := &ir.Page{Name: "EventDetail", Content: []*ir.Action{
+		{Type: "configure", Text: "shows the Event with the id from the url"},
+	}}
+	app := &ir.Application{
+		Config: &ir.BuildConfig{Times: "UTC and display in the user's timezone"},
+		Data:   []*ir.DataModel{event},
+		Pages:  []*ir.Page{home, detail},
+		APIs: []*ir.Endpoint{
+			{Name: "ListEvents"},
+			{Name: "GetEvent", Params: []*ir.Param{{Name: "event_id"}}},
+			{Name: "CreateEvent", Params: []*ir.Param{{Name: "title"}, {Name: "starts"}}},
+		},
+		DetailRoutes: []*ir.DetailRoute{{Page: "EventDetail", Model: "Event", Param: "id", Slug: "events", Endpoint: "GetEvent", Arg: "event_id"}},
+	}
+
+	output := generatePage(home, app)
+	for _, want := range []string{
+		"import { ApiService, errorMessage, formatDateTime } from '../../services/api.service';",
+		"<time [attr.datetime]=\"event.starts\">{{ formatDateTime(event.starts) }}</time>",
+		"readonly formatDateTime = formatDateTime;",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("home.component.ts missing %q:\n%s", want, output)
+		}
+	}
+	output = generatePage(detail, app)
+	if want := "<dd><time [attr.datetime]=\"event.day\">{{ formatDate(event.day) }}</time></dd>"; !strings.Contains(output, want) {
+		t.Errorf("event-detail.component.ts missing %q:\n%s", want, output)
+	}
+	client := generateApiService(app)
+	for _, want := range []string{
+		"export function formatDateTime(value: string | null | undefined): string {",
+		"timeZone: 'UTC'",
+		"function utcTimes(body: Record<string, unknown>)",
+		"`${this.baseUrl}/api/event`, utcTimes(params), { headers: this.getHeaders() });",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("api.service.ts missing %q:\n%s", want, client)
+		}
+	}
+
+	app.Config.Times = ""
+	if output := generatePage(home, app); strings.Contains(output, "formatDateTime") {
+		t.Errorf("times shouldn't be formatted without a time policy:\n%s", output)
+	}
+}
+
+func TestNumbersInLocale(t *testing.T) {
+	product := &ir.DataModel{Name: "Product", Fields: []*ir.DataField{
+		{Name: "name", Type: "text"},
+		{Name: "price", Type: "decimal"},
+		{Name: "view_count", Type: "number"},
+	}}
+	page := &ir.Page{Name: "Products", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of products"},
+		{Type: "loop", Text: "each product shows its name, price, and view_count"},
+	}}
+	app := &ir.Application{
+		Theme: &ir.Theme{Locale: "de-DE"},
+		Data:  []*ir.DataModel{product},
+		Pages: []*ir.Page{page},
+		APIs:  []*ir.Endpoint{{Name: "ListProducts"}},
+	}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { formatCompact, formatMoney } from '../../utils/format';",
+		"<span class=\"number\">{{ formatMoney(product.price) }}</span>",
+		"<span class=\"count\">{{ formatCompact(product.view_count) }}</span>",
+		"readonly formatMoney = formatMoney;",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("products.component.ts missing %q:\n%s", want, output)
+		}
+	}
+	format := generateFormat(app)
+	for _, want := range []string{
+		"const LOCALE = 'de-DE';",
+		"style: 'currency', currency: 'EUR'",
+		"export function formatPercent(value: number | string | null | undefined): string {",
+	} {
+		if !strings.Contains(format, want) {
+			t.Errorf("format.ts missing %q:\n%s", want, format)
+		}
+	}
+
+	app.Theme.Locale = ""
+	if output := generatePage(page, app); strings.Contains(output, "formatMoney") {
+		t.Errorf("numbers shouldn't be formatted without a locale:\n%s", output)
+	}
+}
+
+func TestAccountSettingsPage(t *testing.T) {
+	user := &ir.DataModel{Name: "User", Fields: []*ir.DataField{
+		{Name: "name", Type: "text", Required: true},
+		{Name: "email", Type: "email", Required: true, Unique: true},
+		{Name: "password", Type: "text", Required: true},
+		{Name: "bio", Type: "text"},
+	}}
+	page := &ir.Page{Name: "Settings"}
+	app := &ir.Application{
+		Data:     []*ir.DataModel{user},
+		Pages:    []*ir.Page{page},
+		Auth:     &ir.Auth{},
+		Accounts: &ir.Accounts{Model: "User", Page: "Settings", Profile: true, Password: true, Delete: true},
+		APIs: []*ir.Endpoint{
+			{Name: "GetProfile", Auth: true, Account: ir.AccountProfile},
+			{Name: "UpdateProfile", Auth: true, Account: ir.AccountUpdateProfile, Params: []*ir.Param{{Name: "name"}, {Name: "email"}, {Name: "bio"}}},
+			{Name: "ChangePassword", Auth: true, Account: ir.AccountChangePassword, Params: []*ir.Param{{Name: "current_password"}, {Name: "new_password"}}},
+			{Name: "DeleteAccount", Auth: true, Account: ir.AccountDelete, Params: []*ir.Param{{Name: "password"}}},
+		},
+	}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { ApiService, errorMessage, type Profile } from '../../services/api.service';",
+		"private auth = inject(AuthService);",
+		"email: ['', [Validators.required, Validators.email]],",
+		"new_password: ['', [Validators.required, Validators.minLength(8)]],",
+		"bio: value.bio || null,",
+		"[disabled]=\"deleteForm.value.confirmation !== 'DELETE' || deleting()\"",
+		"this.router.navigate(['/'], { replaceUrl: true });",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("settings.component.ts missing %q:\n%s", want, output)
+		}
+	}
+	service := generateApiService(app)
+	for _, want := range []string{
+		"export type Profile = Omit<User, 'password'>;",
+		"return this.http.delete<void>(`${this.baseUrl}/api/account`, { headers: this.getHeaders(), body: params });",
+	} {
+		if !strings.Contains(service, want) {
+			t.Errorf("api.service.ts missing %q:\n%s", want, service)
+		}
+	}
+}
+
+func TestTargetEnvironment(t *testing.T) {
+	app := &ir.Application{
+		Name:         "Shop",
+		Config:       &ir.BuildConfig{Frontend: "Angular", Deploy: "AWS", Env: "production"},
+		Environments: []*ir.Environment{{Name: "production", Config: map[string]string{"url": "shop example com"}}},
+	}
+	if got := generateApiService(app); !strings.Contains(got, "private baseUrl = 'https://api.shop.example.com';") {
+		t.Errorf("the API service should call the production API:\n%s", got)
+	}
+	if got := generateAngularJson(app); !strings.Contains(got, `"allowedHosts": ["shop.example.com"]`) {
+		t.Errorf("the dev server should answer on the environment's host:\n%s", got)
+	}
+	app.Config.Env = ""
+	if got := generateApiService(app); !strings.Contains(got, "private baseUrl = '';") {
+		t.Error("a local build should call the API through the dev server")
+	}
+	if strings.Contains(generateAngularJson(app), "allowedHosts") {
+		t.Error("a local build should leave the dev server's hosts alone")
+	}
+}
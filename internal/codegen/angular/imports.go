@@ -0,0 +1,147 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeImportTypes writes the summary an import responds with and the
+// sentence summing it up.
+func writeImportTypes(b *strings.Builder) {
+	b.WriteString(`
+export interface ImportError {
+  row: number;
+  field?: string;
+  message: string;
+}
+
+export interface ImportSummary {
+  mode: 'all-or-nothing' | 'continue-on-error';
+  dryRun: boolean;
+  total: number;
+  imported: number;
+  failed: number;
+  errors: ImportError[];
+}
+
+export function describeImport(summary: ImportSummary): string {
+  const { total, imported, failed } = summary;
+  if (summary.dryRun) {
+    return failed > 0
+      ? ` + "`${total - failed} of ${total} records are ready to import; ${failed} have problems`" + `
+      : ` + "`All ${total} records are ready to import`" + `;
+  }
+  if (summary.mode === 'all-or-nothing' && failed > 0) {
+    return ` + "`Nothing was imported: ${failed} of ${total} records have problems`" + `;
+  }
+  return failed > 0
+    ? ` + "`Imported ${imported} of ${total} records; ${failed} were skipped`" + `
+    : ` + "`Imported all ${total} records`" + `;
+}
+`)
+}
+
+// writeUploadImportMethod writes the service's helper uploading a file of
+// records to an import.
+func writeUploadImportMethod(b *strings.Builder) {
+	b.WriteString(`
+  // Uploads a CSV or JSON file of records to an import, only checking them
+  // on a dry run. An all-or-nothing import refused for invalid records still
+  // answers with its summary.
+  private uploadImport(path: string, file: File, dryRun: boolean): Observable<ImportSummary> {
+    const type = /\.json$/i.test(file.name) || file.type.includes('json') ? 'application/json' : 'text/csv';
+    const headers = this.getHeaders().set('Content-Type', type);
+    const params = dryRun ? new HttpParams().set('dry_run', 'true') : undefined;
+    return from(file.text()).pipe(
+      switchMap((body) => this.http.post<ApiResponse<ImportSummary>>(` + "`${this.baseUrl}${path}`" + `, body, { headers, params })),
+      map((res) => res.data),
+      catchError((err) => (err instanceof HttpErrorResponse && err.error?.data ? of(err.error.data as ImportSummary) : throwError(() => err))),
+    );
+  }
+`)
+}
+
+// writeImportMethod writes the service method uploading a file to an
+// import endpoint.
+func writeImportMethod(b *strings.Builder, ep *ir.Endpoint) {
+	fmt.Fprintf(b, "  %s(file: File, dryRun = false): Observable<ImportSummary> {\n", toCamelCase(ep.Name))
+	fmt.Fprintf(b, "    return this.uploadImport('%s', file, dryRun);\n", apiPath(ep.Name))
+	b.WriteString("  }\n")
+}
+
+// writeImportState declares the state of a page's import form.
+func writeImportState(b *strings.Builder) {
+	b.WriteString("  importFile = signal<File | null>(null);\n")
+	b.WriteString("  importing = signal(false);\n")
+	b.WriteString("  importError = signal('');\n")
+	b.WriteString("  importSummary = signal<ImportSummary | null>(null);\n")
+	b.WriteString("  readonly describeImport = describeImport;\n")
+}
+
+// writeImportMethods emits the methods choosing the file of a page's
+// import form and uploading it, to check it or to import it.
+func writeImportMethods(b *strings.Builder, ctx *pageContext) {
+	im := ctx.importForm
+	b.WriteString("\n  chooseImportFile(ev: Event) {\n")
+	b.WriteString("    this.importFile.set((ev.target as HTMLInputElement).files?.[0] ?? null);\n")
+	b.WriteString("    this.importSummary.set(null);\n")
+	b.WriteString("  }\n")
+	b.WriteString("\n  runImport(dryRun: boolean) {\n")
+	b.WriteString("    const file = this.importFile();\n")
+	b.WriteString("    if (!file) return;\n")
+	b.WriteString("    this.importing.set(true);\n")
+	b.WriteString("    this.importError.set('');\n")
+	fmt.Fprintf(b, "    this.api.%s(file, dryRun).subscribe({\n", toCamelCase(im.Endpoint.Name))
+	b.WriteString("      next: (summary) => {\n")
+	b.WriteString("        this.importSummary.set(summary);\n")
+	b.WriteString("        this.importing.set(false);\n")
+	b.WriteString("      },\n")
+	b.WriteString("      error: (err) => {\n")
+	b.WriteString("        this.importSummary.set(null);\n")
+	b.WriteString("        this.importError.set(errorMessage(err, 'Could not import the file'));\n")
+	b.WriteString("        this.importing.set(false);\n")
+	b.WriteString("      },\n")
+	b.WriteString("    });\n")
+	b.WriteString("  }\n")
+}
+
+// writeImportFormNG renders a page's import form: a file input, a button
+// checking the file and one importing it, and the summary of the last
+// check or import with a table of the problems found, announced as it
+// changes.
+func writeImportFormNG(b *strings.Builder, indent string, ctx *pageContext) {
+	im := ctx.importForm
+	label := strings.ToLower(ir.FieldLabel(im.Model.Name))
+	id := ctx.uniqueID(toKebabCase(im.Model.Name) + "-import-file")
+	fmt.Fprintf(b, "%s<form class=\"form import-form\" aria-label=\"Import %ss\" (submit)=\"$event.preventDefault(); runImport(false)\">\n", indent, label)
+	fmt.Fprintf(b, "%s  <div class=\"form-field\">\n", indent)
+	fmt.Fprintf(b, "%s    <label for=\"%s\">%s</label>\n", indent, id, im.Endpoint.Import.Describe())
+	fmt.Fprintf(b, "%s    <input id=\"%s\" type=\"file\" name=\"file\" accept=\"%s\" (change)=\"chooseImportFile($event)\" />\n", indent, id, im.Endpoint.Import.Accepts())
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s  @if (importError()) {\n", indent)
+	fmt.Fprintf(b, "%s    <p class=\"form-error\" role=\"alert\">{{ importError() }}</p>\n", indent)
+	fmt.Fprintf(b, "%s  }\n", indent)
+	fmt.Fprintf(b, "%s  <button type=\"button\" [disabled]=\"!importFile() || importing()\" (click)=\"runImport(true)\">Check file</button>\n", indent)
+	fmt.Fprintf(b, "%s  <button type=\"submit\" [disabled]=\"!importFile() || importing()\">Import</button>\n", indent)
+	fmt.Fprintf(b, "%s</form>\n", indent)
+	fmt.Fprintf(b, "%s<section class=\"import-summary\" aria-live=\"polite\">\n", indent)
+	fmt.Fprintf(b, "%s  @if (importSummary(); as summary) {\n", indent)
+	fmt.Fprintf(b, "%s    <p>{{ describeImport(summary) }}</p>\n", indent)
+	fmt.Fprintf(b, "%s    @if (summary.errors.length > 0) {\n", indent)
+	fmt.Fprintf(b, "%s      <table>\n", indent)
+	fmt.Fprintf(b, "%s        <caption>Problems found</caption>\n", indent)
+	fmt.Fprintf(b, "%s        <thead>\n", indent)
+	fmt.Fprintf(b, "%s          <tr><th scope=\"col\">Row</th><th scope=\"col\">Field</th><th scope=\"col\">Problem</th></tr>\n", indent)
+	fmt.Fprintf(b, "%s        </thead>\n", indent)
+	fmt.Fprintf(b, "%s        <tbody>\n", indent)
+	fmt.Fprintf(b, "%s          @for (problem of summary.errors; track $index) {\n", indent)
+	fmt.Fprintf(b, "%s            <tr><td>{{ problem.row }}</td><td>{{ problem.field ?? '' }}</td><td>{{ problem.message }}</td></tr>\n", indent)
+	fmt.Fprintf(b, "%s          }\n", indent)
+	fmt.Fprintf(b, "%s        </tbody>\n", indent)
+	fmt.Fprintf(b, "%s      </table>\n", indent)
+	fmt.Fprintf(b, "%s    }\n", indent)
+	fmt.Fprintf(b, "%s  }\n", indent)
+	fmt.Fprintf(b, "%s</section>\n", indent)
+}
@@ -0,0 +1,160 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeReorderMethods appends the reorder endpoint to ApiService.
+func writeReorderMethods(b *strings.Builder) {
+	b.WriteString(`
+  // reorder saves the order a list's records were dragged into.
+  reorder(list: string, ids: string[]): Observable<ApiResponse<string[]>> {
+    return this.http.put<ApiResponse<string[]>>(` + "`${this.baseUrl}/api/reorder/${list}`" + `, { ids }, { headers: this.getHeaders() });
+  }
+`)
+}
+
+// pageReorder returns the reorderable list a page's "dragging a task
+// reorders the list" drags: the page's primary model, when the page lists
+// its records.
+func pageReorder(page *ir.Page, app *ir.Application, modelName string) *ir.Reorder {
+	r := ir.ReorderFor(app, modelName)
+	if r == nil {
+		return nil
+	}
+	dragged, listed := false, false
+	for _, a := range page.Content {
+		if ir.IsReorder(a) {
+			if m := ir.ReorderModel(app, a); m != nil && m.Name == r.Model {
+				dragged = true
+			}
+		}
+		if a.Type == "loop" {
+			listed = true
+		}
+	}
+	if dragged && listed {
+		return r
+	}
+	return nil
+}
+
+// writeReorderMethod emits the page's handler for a CDK drop: it shows the
+// new order at once and puts the old one back if saving fails.
+func writeReorderMethod(b *strings.Builder, r *ir.Reorder, ctx *pageContext) {
+	fmt.Fprintf(b, "\n  onReorder(event: CdkDragDrop<%s[]>) {\n", ctx.modelName)
+	b.WriteString("    if (event.previousIndex === event.currentIndex) return;\n")
+	fmt.Fprintf(b, "    const previous = this.%s();\n", ctx.varName)
+	b.WriteString("    const next = [...previous];\n")
+	b.WriteString("    moveItemInArray(next, event.previousIndex, event.currentIndex);\n")
+	fmt.Fprintf(b, "    this.%s.set(next);\n", ctx.varName)
+	fmt.Fprintf(b, "    this.api.reorder('%s', next.map((%s) => %s.id)).subscribe({\n", r.Slug, ctx.itemVar, ctx.itemVar)
+	fmt.Fprintf(b, "      error: () => this.%s.set(previous),\n", ctx.varName)
+	b.WriteString("    });\n")
+	b.WriteString("  }\n")
+}
+
+// writeInteractionNote marks where a page's drag, key binding, infinite
+// scroll, delete, tooltip, detail panel, or item link was declared; the
+// list and the listeners implement it.
+func writeInteractionNote(b *strings.Builder, a *ir.Action, indent string, ctx *pageContext) {
+	_, parsed := ir.ParseKeyBinding(a.Text)
+	switch {
+	case ir.IsReorder(a) && ctx.reorder != nil:
+		fmt.Fprintf(b, "%s<!-- %s — handled by the drop list -->\n", indent, ngText.Replace(a.Text))
+	case ir.IsKeyBinding(a) && parsed:
+		fmt.Fprintf(b, "%s<!-- %s — handled by the keydown listener -->\n", indent, ngText.Replace(a.Text))
+	case ir.IsInfiniteScroll(a) && ctx.scroll:
+		fmt.Fprintf(b, "%s<!-- %s — handled by the load-more sentinel -->\n", indent, ngText.Replace(a.Text))
+	case ir.IsDelete(a) && ctx.deleteEp != nil:
+		fmt.Fprintf(b, "%s<!-- %s — handled by each item's delete button -->\n", indent, ngText.Replace(a.Text))
+	case tooltipWired(a, ctx):
+		fmt.Fprintf(b, "%s<!-- %s — handled by the tooltip -->\n", indent, ngText.Replace(a.Text))
+	case panelWired(a, ctx):
+		fmt.Fprintf(b, "%s<!-- %s — handled by the detail panel -->\n", indent, ngText.Replace(a.Text))
+	case a == ctx.itemLink:
+		fmt.Fprintf(b, "%s<!-- %s — handled by each item's link -->\n", indent, ngText.Replace(a.Text))
+	default:
+		fmt.Fprintf(b, "%s<!-- TODO: %s -->\n", indent, ngText.Replace(a.Text))
+	}
+}
+
+// writeKeyBindings emits the document keydown listener for a page's
+// keyboard shortcuts. Plain keys are ignored while the user is typing in a
+// field; Ctrl also matches Cmd on a Mac.
+func writeKeyBindings(b *strings.Builder, bindings []*ir.KeyBinding, ctx *pageContext) {
+	b.WriteString("\n  @HostListener('document:keydown', ['$event'])\n")
+	b.WriteString("  onKeyDown(ev: KeyboardEvent) {\n")
+	for _, kb := range bindings {
+		if isPlainKey(kb) {
+			b.WriteString("    const target = ev.target as HTMLElement;\n")
+			b.WriteString("    const typing = target.isContentEditable || ['INPUT', 'TEXTAREA', 'SELECT'].includes(target.tagName);\n")
+			break
+		}
+	}
+	for _, kb := range bindings {
+		fmt.Fprintf(b, "    if (%s) {\n", keyCondition(kb))
+		b.WriteString("      ev.preventDefault();\n")
+		fmt.Fprintf(b, "      %s;\n", keyAction(kb, ctx))
+		b.WriteString("      return;\n")
+		b.WriteString("    }\n")
+	}
+	b.WriteString("  }\n")
+}
+
+// keyCondition returns the JavaScript test matching a key binding's key
+// and modifiers on a KeyboardEvent named ev.
+func keyCondition(kb *ir.KeyBinding) string {
+	var parts []string
+	if isPlainKey(kb) {
+		parts = append(parts, "!typing")
+	}
+	if len([]rune(kb.Key)) == 1 && kb.Key != " " {
+		parts = append(parts, fmt.Sprintf("ev.key.toLowerCase() === '%s'", kb.Key))
+	} else {
+		parts = append(parts, fmt.Sprintf("ev.key === '%s'", kb.Key))
+	}
+	if kb.Ctrl {
+		parts = append(parts, "(ev.ctrlKey || ev.metaKey)")
+	} else {
+		parts = append(parts, "!ev.ctrlKey && !ev.metaKey")
+	}
+	if kb.Alt {
+		parts = append(parts, "ev.altKey")
+	} else {
+		parts = append(parts, "!ev.altKey")
+	}
+	// Symbols like ? already need Shift on most layouts.
+	if kb.Shift {
+		parts = append(parts, "ev.shiftKey")
+	}
+	return strings.Join(parts, " && ")
+}
+
+// isPlainKey reports whether a key binding would fire while typing: no Ctrl
+// or Alt, and not Escape, which should close things from anywhere.
+func isPlainKey(kb *ir.KeyBinding) bool {
+	return !kb.Ctrl && !kb.Alt && kb.Key != "Escape"
+}
+
+// keyAction returns the statement a key binding runs.
+func keyAction(kb *ir.KeyBinding, ctx *pageContext) string {
+	switch kb.Action {
+	case "close":
+		if ctx.needsFormState {
+			return "this.showForm.set(false)"
+		}
+		return "document.querySelector<HTMLElement>('.modal-close')?.click()"
+	case "open-form":
+		return "this.showForm.set(true)"
+	case "navigate":
+		return fmt.Sprintf("this.router.navigate(['%s'])", pagePath(kb.Target))
+	case "focus-search":
+		return "document.querySelector<HTMLInputElement>('input[type=\"search\"]')?.focus()"
+	default: // submit
+		return "document.querySelector<HTMLFormElement>('form')?.requestSubmit()"
+	}
+}
@@ -0,0 +1,175 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// pageScrolls reports whether a page loads the next page of its list as
+// the user nears the end: it says "scrolling to bottom loads more posts"
+// of the model it lists, and the endpoint it lists them from paginates.
+func pageScrolls(page *ir.Page, app *ir.Application, modelName string, listEp *ir.Endpoint) bool {
+	if listEp == nil || listEp.PageSize == 0 || len(listEp.Params) > 0 {
+		return false
+	}
+	for _, a := range page.Content {
+		if ir.IsInfiniteScroll(a) {
+			if m := ir.ScrollModel(app, a); m != nil && m.Name == modelName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pageDelete returns the endpoint and button label of a page's
+// `clicking "Delete" deletes the task`, when the page lists tasks and a
+// delete endpoint takes just the task's id.
+func pageDelete(page *ir.Page, app *ir.Application, modelName string) (*ir.Endpoint, string) {
+	if modelName == "" {
+		return nil, ""
+	}
+	for _, a := range page.Content {
+		if !ir.IsDelete(a) || !strings.Contains(strings.ToLower(a.Text), strings.ToLower(modelName)) {
+			continue
+		}
+		ep := findDeleteEndpoint(app, modelName)
+		if ep == nil || len(ep.Params) != 1 || !strings.HasSuffix(strings.ToLower(ep.Params[0].Name), "id") {
+			return nil, ""
+		}
+		return ep, ir.DeleteLabel(a)
+	}
+	return nil, ""
+}
+
+// findDeleteEndpoint finds a delete-type API endpoint matching the model.
+func findDeleteEndpoint(app *ir.Application, modelName string) *ir.Endpoint {
+	lowerModel := strings.ToLower(modelName)
+	for _, ep := range app.APIs {
+		lower := strings.ToLower(ep.Name)
+		if strings.HasPrefix(lower, "delete") && strings.Contains(lower, lowerModel) {
+			return ep
+		}
+	}
+	return nil
+}
+
+// writeLoadMoreMethods emits the methods of an infinitely scrolling list.
+// An IntersectionObserver watches a sentinel below the list and fetches
+// the page after nextCursor when it comes into view. The sentinel is
+// observed afresh after each page, so a short page that leaves it in view
+// loads the next one too. A failed fetch stops loading more.
+func writeLoadMoreMethods(b *strings.Builder, listEp *ir.Endpoint, ctx *pageContext) {
+	b.WriteString("\n  observeSentinel() {\n")
+	b.WriteString("    this.observer?.disconnect();\n")
+	b.WriteString("    const el = this.sentinel?.nativeElement;\n")
+	b.WriteString("    if (!el || !this.nextCursor()) return;\n")
+	b.WriteString("    this.observer = new IntersectionObserver(([entry]) => {\n")
+	b.WriteString("      if (entry.isIntersecting) this.loadMore();\n")
+	b.WriteString("    }, { rootMargin: '200px' });\n")
+	b.WriteString("    this.observer.observe(el);\n")
+	b.WriteString("  }\n")
+	b.WriteString("\n  loadMore() {\n")
+	b.WriteString("    const cursor = this.nextCursor();\n")
+	b.WriteString("    if (!cursor || this.loadingMore()) return;\n")
+	b.WriteString("    this.observer?.disconnect();\n")
+	b.WriteString("    this.loadingMore.set(true);\n")
+	fmt.Fprintf(b, "    this.api.%s(cursor).subscribe({\n", toCamelCase(listEp.Name))
+	b.WriteString("      next: (res) => {\n")
+	fmt.Fprintf(b, "        this.%s.set([...this.%s(), ...((res.data as %s[]) ?? [])]);\n", ctx.varName, ctx.varName, ctx.modelName)
+	b.WriteString("        this.nextCursor.set(res.pagination?.nextCursor ?? null);\n")
+	b.WriteString("        this.loadingMore.set(false);\n")
+	b.WriteString("        this.observeSentinel();\n")
+	b.WriteString("      },\n")
+	b.WriteString("      error: () => {\n")
+	b.WriteString("        this.nextCursor.set(null);\n")
+	b.WriteString("        this.loadingMore.set(false);\n")
+	b.WriteString("      },\n")
+	b.WriteString("    });\n")
+	b.WriteString("  }\n")
+	b.WriteString("\n  ngOnDestroy() {\n")
+	b.WriteString("    this.observer?.disconnect();\n")
+	b.WriteString("  }\n")
+}
+
+// writeListEnd emits what follows the page's list: the sentinel loading
+// more of a scrolling list, or the controls paging a paginated one unless
+// the page places them itself.
+func writeListEnd(b *strings.Builder, indent string, page *ir.Page, ctx *pageContext) {
+	switch {
+	case ctx.scroll:
+		writeLoadMoreSentinel(b, indent)
+	case ctx.query != nil && ctx.query.Paged && !ir.ShowsPagination(page):
+		writePaginationNG(b, indent)
+	}
+}
+
+// writeLoadMoreSentinel emits the element below a scrolling list whose
+// coming into view loads the next page.
+func writeLoadMoreSentinel(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<div #sentinel class=\"load-more\">\n", indent)
+	fmt.Fprintf(b, "%s  @if (loadingMore()) {\n", indent)
+	fmt.Fprintf(b, "%s    <div class=\"spinner\"></div>\n", indent)
+	fmt.Fprintf(b, "%s  }\n", indent)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
+
+// writeDeleteMethod emits the page's handler for an item's Delete button:
+// it removes the item at once and puts the list back if deleting fails.
+func writeDeleteMethod(b *strings.Builder, ep *ir.Endpoint, ctx *pageContext) {
+	item := ctx.itemVar
+	fmt.Fprintf(b, "\n  onDelete(%s: %s) {\n", item, ctx.modelName)
+	fmt.Fprintf(b, "    const previous = this.%s();\n", ctx.varName)
+	fmt.Fprintf(b, "    this.%s.set(previous.filter((other) => other.id !== %s.id));\n", ctx.varName, item)
+	fmt.Fprintf(b, "    this.api.%s({ %s: %s.id }).subscribe({\n", toCamelCase(ep.Name), toCamelCase(ep.Params[0].Name), item)
+	fmt.Fprintf(b, "      next: (res) => { if (res.error) this.%s.set(previous); },\n", ctx.varName)
+	fmt.Fprintf(b, "      error: () => this.%s.set(previous),\n", ctx.varName)
+	b.WriteString("    });\n")
+	b.WriteString("  }\n")
+}
+
+// writeDeleteButton emits a list item's delete button.
+func writeDeleteButton(b *strings.Builder, indent, item string, ctx *pageContext) {
+	fmt.Fprintf(b, "%s<button class=\"delete-button\" (click)=\"onDelete(%s); $event.stopPropagation()\">%s</button>\n", indent, item, ngText.Replace(ctx.deleteLabel))
+}
+
+// writeOptimisticCreate emits the body of a create form's onSubmit that
+// shows the new record in the list at once, under a temporary id, swaps in
+// the saved record when the API answers, and takes it out again if saving
+// fails. Paginated lists show their newest records first, so the record
+// goes at the top of those.
+func writeOptimisticCreate(b *strings.Builder, createFunc string, ctx *pageContext) {
+	list := ctx.varName
+	b.WriteString("    const values = this.form.value;\n")
+	b.WriteString("    const tempId = `temp-${Date.now()}`;\n")
+	pending := fmt.Sprintf("{ ...values, id: tempId } as %s", ctx.modelName)
+	if ctx.newestFirst {
+		fmt.Fprintf(b, "    this.%s.set([%s, ...this.%s()]);\n", list, pending, list)
+	} else {
+		fmt.Fprintf(b, "    this.%s.set([...this.%s(), %s]);\n", list, list, pending)
+	}
+	if ctx.needsFormState {
+		b.WriteString("    this.showForm.set(false);\n")
+	}
+	b.WriteString("    this.form.reset();\n")
+	fmt.Fprintf(b, "    this.api.%s(values).subscribe({\n", createFunc)
+	b.WriteString("      next: (res) => {\n")
+	fmt.Fprintf(b, "        this.%s.set(this.%s().map((other) => (other.id === tempId ? res.data as %s : other)));\n", list, list, ctx.modelName)
+	if ctx.hasSuccessState {
+		b.WriteString("        this.success.set('Created successfully');\n")
+	}
+	b.WriteString("      },\n")
+	if ctx.hasErrorState {
+		b.WriteString("      error: (err: any) => {\n")
+	} else {
+		b.WriteString("      error: () => {\n")
+	}
+	fmt.Fprintf(b, "        this.%s.set(this.%s().filter((other) => other.id !== tempId));\n", list, list)
+	if ctx.hasErrorState {
+		b.WriteString("        this.error.set(err?.message ?? 'Failed to save');\n")
+	}
+	b.WriteString("      },\n")
+	b.WriteString("    });\n")
+}
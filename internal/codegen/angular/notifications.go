@@ -0,0 +1,119 @@
+package angular
+
+import "strings"
+
+// writeNotificationsMethods appends the in-app notification endpoints to
+// ApiService.
+func writeNotificationsMethods(b *strings.Builder) {
+	b.WriteString(`
+  listNotifications(): Observable<ApiResponse<Notification[]>> {
+    return this.http.get<ApiResponse<Notification[]>>(` + "`${this.baseUrl}/api/notifications`" + `, { headers: this.getHeaders() });
+  }
+
+  getUnreadNotificationCount(): Observable<ApiResponse<{ count: number }>> {
+    return this.http.get<ApiResponse<{ count: number }>>(` + "`${this.baseUrl}/api/notifications/unread-count`" + `, { headers: this.getHeaders() });
+  }
+
+  markNotificationRead(id: string): Observable<ApiResponse<Notification>> {
+    return this.http.post<ApiResponse<Notification>>(` + "`${this.baseUrl}/api/notifications/${encodeURIComponent(id)}/read`" + `, {}, { headers: this.getHeaders() });
+  }
+
+  markAllNotificationsRead(): Observable<ApiResponse<{ count: number }>> {
+    return this.http.post<ApiResponse<{ count: number }>>(` + "`${this.baseUrl}/api/notifications/read-all`" + `, {}, { headers: this.getHeaders() });
+  }
+`)
+}
+
+// generateNotificationBell produces
+// src/app/components/notification-bell/notification-bell.component.ts: a
+// dropdown of the signed-in user's notifications. The unread badge polls
+// the backend every 30 seconds.
+func generateNotificationBell() string {
+	return `// Generated by Human compiler — do not edit
+
+import { Component, OnDestroy, OnInit, inject } from '@angular/core';
+import { CommonModule } from '@angular/common';
+import { ApiService } from '../../services/api.service';
+import { Notification } from '../../models/types';
+
+const POLL_INTERVAL_MS = 30_000;
+
+@Component({
+  selector: 'app-notification-bell',
+  standalone: true,
+  imports: [CommonModule],
+  template: ` + "`" + `
+    <div *ngIf="signedIn" style="position: fixed; top: 1rem; right: 1rem; z-index: 1000">
+      <button type="button" aria-label="Notifications" [attr.aria-expanded]="open" (click)="toggle()">
+        🔔<span *ngIf="unread > 0" style="margin-left: 0.25rem; font-weight: bold">{{ unread }}</span>
+      </button>
+      <div *ngIf="open" role="menu" style="position: absolute; right: 0; margin-top: 0.5rem; width: 20rem; max-height: 24rem; overflow-y: auto; background: white; border: 1px solid #e5e7eb; border-radius: 0.5rem; box-shadow: 0 4px 12px rgba(0,0,0,0.1)">
+        <div style="display: flex; justify-content: space-between; padding: 0.5rem 0.75rem; border-bottom: 1px solid #e5e7eb">
+          <strong>Notifications</strong>
+          <button *ngIf="unread > 0" type="button" (click)="markAllRead()">Mark all read</button>
+        </div>
+        <p *ngIf="notifications.length === 0" style="padding: 0.75rem; color: #6b7280">No notifications yet</p>
+        <div *ngFor="let n of notifications" role="menuitem" (click)="markRead(n)"
+          style="padding: 0.75rem; cursor: pointer; border-bottom: 1px solid #f3f4f6"
+          [style.font-weight]="n.seen ? 'normal' : 'bold'">
+          {{ n.message }}
+        </div>
+      </div>
+    </div>
+  ` + "`" + `
+})
+export class NotificationBellComponent implements OnInit, OnDestroy {
+  private api = inject(ApiService);
+  private timer?: ReturnType<typeof setInterval>;
+
+  open = false;
+  unread = 0;
+  notifications: Notification[] = [];
+  signedIn = !!localStorage.getItem('token');
+
+  ngOnInit(): void {
+    this.refreshCount();
+    this.timer = setInterval(() => this.refreshCount(), POLL_INTERVAL_MS);
+  }
+
+  ngOnDestroy(): void {
+    clearInterval(this.timer);
+  }
+
+  // Polling also notices signing in and out, since the token lives in localStorage.
+  refreshCount(): void {
+    this.signedIn = !!localStorage.getItem('token');
+    if (!this.signedIn) return;
+    this.api.getUnreadNotificationCount().subscribe({
+      next: (res) => (this.unread = res.data?.count ?? 0),
+      error: () => {},
+    });
+  }
+
+  toggle(): void {
+    this.open = !this.open;
+    if (!this.open) return;
+    this.api.listNotifications().subscribe({
+      next: (res) => (this.notifications = res.data ?? []),
+      error: () => {},
+    });
+  }
+
+  markRead(notification: Notification): void {
+    if (notification.seen) return;
+    this.api.markNotificationRead(notification.id).subscribe(() => {
+      notification.seen = new Date().toISOString();
+      this.unread = Math.max(0, this.unread - 1);
+    });
+  }
+
+  markAllRead(): void {
+    this.api.markAllNotificationsRead().subscribe(() => {
+      const now = new Date().toISOString();
+      this.notifications.forEach((n) => (n.seen = n.seen ?? now));
+      this.unread = 0;
+    });
+  }
+}
+`
+}
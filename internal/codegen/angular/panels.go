@@ -0,0 +1,222 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateTooltip produces components/tooltip/tooltip.component.ts: a
+// wrapper that shows its text above its content while it is hovered or
+// focused. Escape hides it without moving the pointer away.
+func generateTooltip() string {
+	return `// Generated by Human compiler — do not edit
+
+import { Component, Input } from '@angular/core';
+
+let nextId = 0;
+
+@Component({
+  selector: 'app-tooltip',
+  standalone: true,
+  template: ` + "`" + `
+    <span
+      class="tooltip-anchor"
+      style="position: relative; display: inline-block"
+      tabindex="0"
+      [attr.aria-describedby]="shown ? id : null"
+      (mouseenter)="open = true"
+      (mouseleave)="open = false"
+      (focusin)="open = true"
+      (focusout)="open = false"
+      (keydown.escape)="open = false"
+    >
+      <ng-content />
+      @if (shown) {
+        <span
+          [id]="id"
+          role="tooltip"
+          class="tooltip"
+          style="position: absolute; bottom: 100%; left: 50%; transform: translateX(-50%); margin-bottom: 6px; padding: 4px 8px; border-radius: 4px; background: #1f2937; color: #fff; font-size: 12px; white-space: nowrap; pointer-events: none; z-index: 1000"
+        >{{ text }}</span>
+      }
+    </span>
+  ` + "`" + `
+})
+export class TooltipComponent {
+  @Input() text: string | number | null | undefined;
+  open = false;
+  readonly id = ` + "`tooltip-${++nextId}`" + `;
+
+  get shown(): boolean {
+    return this.open && this.text !== null && this.text !== undefined && this.text !== '';
+  }
+}
+`
+}
+
+// generateDetailPanel produces components/detail-panel/detail-panel.component.ts:
+// a panel that slides over the page from one side, closed by its close
+// button, a click outside it, or Escape.
+func generateDetailPanel() string {
+	return `// Generated by Human compiler — do not edit
+
+import { Component, EventEmitter, HostListener, Input, Output } from '@angular/core';
+
+@Component({
+  selector: 'app-detail-panel',
+  standalone: true,
+  template: ` + "`" + `
+    <div
+      class="detail-panel-overlay"
+      style="position: fixed; inset: 0; background: rgba(0, 0, 0, 0.3); z-index: 1000"
+      (click)="closed.emit()"
+    >
+      <aside
+        role="dialog"
+        aria-modal="true"
+        [attr.aria-label]="title"
+        class="detail-panel"
+        style="position: fixed; top: 0; bottom: 0; width: min(420px, 100%); overflow-y: auto; padding: 24px; background: #fff; box-shadow: 0 0 24px rgba(0, 0, 0, 0.2)"
+        [style.left]="side === 'left' ? '0' : null"
+        [style.right]="side === 'right' ? '0' : null"
+        (click)="$event.stopPropagation()"
+      >
+        <header style="display: flex; justify-content: space-between; align-items: center; margin-bottom: 16px">
+          <h2 style="margin: 0">{{ title }}</h2>
+          <button type="button" class="detail-panel-close" aria-label="Close" (click)="closed.emit()">&times;</button>
+        </header>
+        <ng-content />
+      </aside>
+    </div>
+  ` + "`" + `
+})
+export class DetailPanelComponent {
+  @Input() title = '';
+  @Input() side: 'left' | 'right' = 'right';
+  @Output() closed = new EventEmitter<void>();
+
+  @HostListener('document:keydown.escape')
+  onEscape() {
+    this.closed.emit();
+  }
+}
+`
+}
+
+// pageTooltips returns the tooltips a page shows over the records it
+// lists: "hovering over a task title shows its description".
+func pageTooltips(page *ir.Page, app *ir.Application, modelName string) []*ir.Tooltip {
+	if modelName == "" {
+		return nil
+	}
+	var tips []*ir.Tooltip
+	for _, a := range page.Content {
+		if t := ir.TooltipFor(app, a, modelName); t != nil && t.Model != nil && t.Model.Name == modelName {
+			tips = append(tips, t)
+		}
+	}
+	return tips
+}
+
+// componentTooltips returns the tooltip a component shows over itself, as
+// a one-element slice, and the prop holding the record it shows a field of.
+func componentTooltips(comp *ir.Component, app *ir.Application) ([]*ir.Tooltip, string) {
+	for _, p := range comp.Props {
+		if !isDataModel(p.Type, app) {
+			continue
+		}
+		for _, a := range comp.Content {
+			if t := ir.TooltipFor(app, a, p.Type); t != nil && t.Model != nil && t.Model.Name == p.Type {
+				return []*ir.Tooltip{t}, p.Name
+			}
+		}
+		return nil, ""
+	}
+	return nil, ""
+}
+
+// pagePanel returns the detail panel a page's "clicking a task opens a
+// detail panel" opens on the records it lists, or nil.
+func pagePanel(page *ir.Page, app *ir.Application, modelName string) *ir.DetailPanel {
+	if modelName == "" {
+		return nil
+	}
+	for _, a := range page.Content {
+		if p := ir.DetailPanelFor(app, a); p != nil && p.Model.Name == modelName {
+			return p
+		}
+	}
+	return nil
+}
+
+// tooltipWired reports whether a tooltip interaction is one the page or
+// component renders.
+func tooltipWired(a *ir.Action, ctx *pageContext) bool {
+	for _, w := range ctx.tooltips {
+		if t := ir.TooltipFor(ctx.app, a, w.Model.Name); t != nil && *t == *w {
+			return true
+		}
+	}
+	return false
+}
+
+// panelWired reports whether a detail panel interaction opens the page's
+// panel.
+func panelWired(a *ir.Action, ctx *pageContext) bool {
+	p := ir.DetailPanelFor(ctx.app, a)
+	return p != nil && ctx.panel != nil && p.Model == ctx.panel.Model
+}
+
+// tooltipOn returns the tooltip shown over a field of each listed record,
+// or over the whole record when field is "".
+func tooltipOn(field string, ctx *pageContext) *ir.Tooltip {
+	for _, t := range ctx.tooltips {
+		if t.Target == nil && field == "" || t.Target != nil && t.Target.Name == field {
+			return t
+		}
+	}
+	return nil
+}
+
+// tooltipText returns the attribute giving a tooltip's text for the record
+// named item.
+func tooltipText(t *ir.Tooltip, item string) string {
+	if t.Field != nil {
+		return fmt.Sprintf("[text]=\"%s.%s\"", item, t.Field.Name)
+	}
+	return fmt.Sprintf("text=\"%s\"", strings.ReplaceAll(ngText.Replace(t.Text), `"`, "&quot;"))
+}
+
+// panelClick returns the attributes making a listed record open the detail
+// panel when clicked or when Enter is pressed on it, if the page has one.
+func panelClick(item string, ctx *pageContext) string {
+	if ctx.panel == nil {
+		return ""
+	}
+	return fmt.Sprintf(" role=\"button\" tabindex=\"0\" (click)=\"selected.set(%s)\" (keydown.enter)=\"$event.target === $event.currentTarget && selected.set(%s)\"", item, item)
+}
+
+// writePanelNG renders the detail panel of the selected record, listing
+// the panel's fields.
+func writePanelNG(b *strings.Builder, indent string, ctx *pageContext) {
+	p := ctx.panel
+	item := ctx.itemVar
+	fmt.Fprintf(b, "%s@if (selected(); as %s) {\n", indent, item)
+	fmt.Fprintf(b, "%s  <app-detail-panel title=\"%s\" side=\"%s\" (closed)=\"selected.set(null)\">\n", indent, ir.FieldLabel(p.Model.Name), p.Side)
+	fmt.Fprintf(b, "%s    <dl class=\"detail-fields\">\n", indent)
+	for _, f := range p.Fields {
+		fmt.Fprintf(b, "%s      <dt>%s</dt>\n", indent, ir.FieldLabel(f.Name))
+		if f.Type == "boolean" {
+			fmt.Fprintf(b, "%s      <dd>{{ %s.%s ? 'Yes' : 'No' }}</dd>\n", indent, item, f.Name)
+		} else if t := timeNG(item+"."+f.Name, f, ctx); t != "" {
+			fmt.Fprintf(b, "%s      <dd>%s</dd>\n", indent, t)
+		} else {
+			fmt.Fprintf(b, "%s      <dd>{{ %s.%s }}</dd>\n", indent, item, f.Name)
+		}
+	}
+	fmt.Fprintf(b, "%s    </dl>\n", indent)
+	fmt.Fprintf(b, "%s  </app-detail-panel>\n", indent)
+	fmt.Fprintf(b, "%s}\n", indent)
+}
@@ -0,0 +1,178 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeListQueryState declares the view of the page's list its URL holds:
+// the search, each filter, the sort, and the page, read from the route's
+// query params, so the view can be shared, bookmarked, and gone back to.
+// A list the browser narrows itself gets the records matching the view.
+func writeListQueryState(b *strings.Builder, ctx *pageContext) {
+	q := ctx.query
+	b.WriteString("  private queryParams = toSignal(this.route.queryParamMap, { initialValue: this.route.snapshot.queryParamMap });\n")
+	if q.Search {
+		b.WriteString("  search = computed(() => this.queryParams().get('q') ?? '');\n")
+	}
+	for _, f := range q.Filters {
+		fmt.Fprintf(b, "  %s = computed(() => this.queryParams().get('%s') ?? '');\n", filterVar(f), f.Name)
+	}
+	if len(q.Sortable) > 0 {
+		var keys []string
+		for _, f := range q.Sortable {
+			keys = append(keys, "'"+f.Name+"'")
+		}
+		initial, desc := "null", "false"
+		if q.SortBy != nil {
+			initial = "'" + q.SortBy.Name + "'"
+			desc = fmt.Sprintf("!this.queryParams().has('sort') && %t", q.Desc)
+		}
+		fmt.Fprintf(b, "  sortKey = computed(() => ([%s] as const).find((key) => key === this.queryParams().get('sort')) ?? %s);\n", strings.Join(keys, ", "), initial)
+		fmt.Fprintf(b, "  sortDesc = computed(() => (this.queryParams().has('order') ? this.queryParams().get('order') === 'desc' : %s));\n", desc)
+	}
+	if q.Paged {
+		b.WriteString("  page = computed(() => Math.max(Number(this.queryParams().get('page')) || 1, 1));\n")
+		b.WriteString("  hasNext = signal(false);\n")
+		writeListQuery(b, q)
+	} else {
+		writeVisibleRows(b, ctx)
+	}
+}
+
+// writeListQuery declares the query a paginated list is fetched with: its
+// search, filters, sort, and page.
+func writeListQuery(b *strings.Builder, q *ir.ListQuery) {
+	b.WriteString("  private listQuery = computed(() => {\n")
+	b.WriteString("    const query: Record<string, string> = {};\n")
+	if q.Search {
+		b.WriteString("    if (this.search()) query['q'] = this.search();\n")
+	}
+	for _, f := range q.Filters {
+		fmt.Fprintf(b, "    if (this.%s()) query['%s'] = this.%s();\n", filterVar(f), f.Name, filterVar(f))
+	}
+	if len(q.Sortable) > 0 {
+		b.WriteString("    const sort = this.sortKey();\n")
+		b.WriteString("    if (sort) {\n")
+		b.WriteString("      query['sort'] = sort;\n")
+		b.WriteString("      query['order'] = this.sortDesc() ? 'desc' : 'asc';\n")
+		b.WriteString("    }\n")
+	}
+	b.WriteString("    if (this.page() > 1) query['page'] = String(this.page());\n")
+	b.WriteString("    return query;\n")
+	b.WriteString("  });\n")
+}
+
+// writeVisibleRows declares the records of a list loaded whole that match
+// the search and filters, in the order picked.
+func writeVisibleRows(b *strings.Builder, ctx *pageContext) {
+	q, item := ctx.query, ctx.itemVar
+	var conds []string
+	for _, f := range q.Filters {
+		conds = append(conds, fmt.Sprintf("(!this.%s() || String(%s.%s) === this.%s())", filterVar(f), item, f.Name, filterVar(f)))
+	}
+	if q.Search {
+		conds = append(conds, fmt.Sprintf("(!search || Object.values(%s).some((value) => String(value ?? '').toLowerCase().includes(search)))", item))
+	}
+	fmt.Fprintf(b, "  %s = computed(() => {\n", rowsVar(ctx))
+	if q.Search {
+		b.WriteString("    const search = this.search().toLowerCase();\n")
+	}
+	if len(conds) > 0 {
+		fmt.Fprintf(b, "    const rows = this.%s().filter((%s) =>\n", ctx.varName, item)
+		fmt.Fprintf(b, "      %s,\n", strings.Join(conds, " &&\n      "))
+		b.WriteString("    );\n")
+	} else {
+		fmt.Fprintf(b, "    const rows = [...this.%s()];\n", ctx.varName)
+	}
+	if len(q.Sortable) > 0 {
+		b.WriteString("    const key = this.sortKey();\n")
+		b.WriteString("    if (key) {\n")
+		b.WriteString("      rows.sort((a, b) => {\n")
+		b.WriteString("        const order = String(a[key] ?? '').localeCompare(String(b[key] ?? ''), undefined, { numeric: true });\n")
+		b.WriteString("        return this.sortDesc() ? -order : order;\n")
+		b.WriteString("      });\n")
+		b.WriteString("    }\n")
+	}
+	b.WriteString("    return rows;\n")
+	b.WriteString("  });\n")
+}
+
+// writeListQueryMethods emits the methods writing the list's view back to
+// the URL. Changing the search, a filter, or the sort goes back to the
+// first page.
+func writeListQueryMethods(b *strings.Builder, q *ir.ListQuery) {
+	b.WriteString("\n  setParam(name: string, value: string) {\n")
+	b.WriteString("    const queryParams: Record<string, string | null> = { [name]: value || null };\n")
+	b.WriteString("    if (name !== 'page') queryParams['page'] = null;\n")
+	b.WriteString("    this.router.navigate([], { relativeTo: this.route, queryParams, queryParamsHandling: 'merge' });\n")
+	b.WriteString("  }\n")
+	if q.Paged {
+		b.WriteString("\n  goToPage(page: number) {\n")
+		b.WriteString("    this.setParam('page', page > 1 ? String(page) : '');\n")
+		b.WriteString("  }\n")
+	}
+	if len(q.Sortable) > 0 {
+		b.WriteString("\n  sortBy(key: string) {\n")
+		b.WriteString("    const order = key === this.sortKey() && !this.sortDesc() ? 'desc' : 'asc';\n")
+		b.WriteString("    this.router.navigate([], { relativeTo: this.route, queryParams: { sort: key, order, page: null }, queryParamsHandling: 'merge' });\n")
+		b.WriteString("  }\n")
+	}
+}
+
+// rowsVar returns the records the page's list renders: the ones matching
+// its view, or all it loaded when the API pages them.
+func rowsVar(ctx *pageContext) string {
+	if ctx.query == nil || ctx.query.Paged {
+		return ctx.varName
+	}
+	return "visible" + toPascalCase(ctx.varName)
+}
+
+// filterVar returns the name of the value a list is filtered by f to.
+func filterVar(f *ir.DataField) string {
+	return toCamelCase(f.Name) + "Filter"
+}
+
+// filterOptions returns the values and labels of a filter's options.
+func filterOptions(f *ir.DataField) [][2]string {
+	if f.Type == "boolean" {
+		return [][2]string{{"true", "Yes"}, {"false", "No"}}
+	}
+	var opts [][2]string
+	for _, v := range f.EnumValues {
+		opts = append(opts, [2]string{v, capitalize(v)})
+	}
+	return opts
+}
+
+// writeSearchNG renders the search input of a list, kept in the URL.
+func writeSearchNG(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<input #searchInput type=\"search\" placeholder=\"Search...\" aria-label=\"Search\" class=\"search-input\" [value]=\"search()\" (input)=\"setParam('q', searchInput.value)\" />\n", indent)
+}
+
+// writeFilterNG renders the dropdown filtering a list by f, kept in the
+// URL.
+func writeFilterNG(b *strings.Builder, indent string, f *ir.DataField) {
+	label := ir.FieldLabel(f.Name)
+	ref := toCamelCase(f.Name) + "Select"
+	fmt.Fprintf(b, "%s<select #%s class=\"filter-select\" aria-label=\"Filter by %s\" [value]=\"%s()\" (change)=\"setParam('%s', %s.value)\">\n",
+		indent, ref, strings.ToLower(label), filterVar(f), f.Name, ref)
+	fmt.Fprintf(b, "%s  <option value=\"\">All</option>\n", indent)
+	for _, o := range filterOptions(f) {
+		fmt.Fprintf(b, "%s  <option value=\"%s\">%s</option>\n", indent, o[0], o[1])
+	}
+	fmt.Fprintf(b, "%s</select>\n", indent)
+}
+
+// writePaginationNG renders the controls moving between the pages of a
+// paginated list, kept in the URL.
+func writePaginationNG(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<nav class=\"pagination\" aria-label=\"Pagination\">\n", indent)
+	fmt.Fprintf(b, "%s  <button class=\"page-btn\" [disabled]=\"page() <= 1\" (click)=\"goToPage(page() - 1)\">&laquo; Prev</button>\n", indent)
+	fmt.Fprintf(b, "%s  <span class=\"page-number\" aria-current=\"page\">{{ page() }}</span>\n", indent)
+	fmt.Fprintf(b, "%s  <button class=\"page-btn\" [disabled]=\"!hasNext()\" (click)=\"goToPage(page() + 1)\">Next &raquo;</button>\n", indent)
+	fmt.Fprintf(b, "%s</nav>\n", indent)
+}
@@ -0,0 +1,114 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// linkedPage returns the page clicking a listed record opens: the table's
+// row page, else the page the list links its items to, or "".
+func linkedPage(ctx *pageContext) string {
+	if ctx.table != nil && ctx.table.RowPage != "" {
+		return ctx.table.RowPage
+	}
+	return ctx.itemPage
+}
+
+// openCall returns the template call opening a listed record's page.
+func openCall(item string, ctx *pageContext) string {
+	_, field := ir.RecordLink(ctx.app, linkedPage(ctx))
+	return fmt.Sprintf("open%s(%s.%s)", ctx.modelName, item, field)
+}
+
+// writeOpenRecord emits the method navigating to a listed record's page:
+// its detail route, else the page with an id query.
+func writeOpenRecord(b *strings.Builder, ctx *pageContext) {
+	page := linkedPage(ctx)
+	if r := ir.DetailRouteFor(ctx.app, page); r != nil {
+		fmt.Fprintf(b, "\n  open%s(%s: string) {\n", ctx.modelName, r.Param)
+		fmt.Fprintf(b, "    this.router.navigate(['/%s', %s]);\n", r.Slug, r.Param)
+		b.WriteString("  }\n")
+		return
+	}
+	fmt.Fprintf(b, "\n  open%s(id: string) {\n", ctx.modelName)
+	fmt.Fprintf(b, "    this.router.navigate(['%s'], { queryParams: { id } });\n", pagePath(page))
+	b.WriteString("  }\n")
+}
+
+// itemClick returns the attributes making a listed record open the detail
+// panel, or navigate to the page its list links to, when clicked or when
+// Enter is pressed on it.
+func itemClick(item string, ctx *pageContext) string {
+	if ctx.panel != nil || ctx.itemPage == "" {
+		return panelClick(item, ctx)
+	}
+	call := openCall(item, ctx)
+	return fmt.Sprintf(" role=\"link\" tabindex=\"0\" (click)=\"%s\" (keydown.enter)=\"$event.target === $event.currentTarget && %s\"", call, call)
+}
+
+// writeRecordInit loads the record a detail route's page shows whenever
+// the URL's value changes.
+func writeRecordInit(b *strings.Builder, r *ir.DetailRoute) {
+	fmt.Fprintf(b, "    this.route.paramMap.subscribe(() => this.load%s());\n", r.Model)
+}
+
+// writeRecordLoad emits the method loading the record a detail route's
+// page shows: undefined while loading, null when there is no such record.
+func writeRecordLoad(b *strings.Builder, ctx *pageContext) {
+	r := ctx.record
+	v := strings.ToLower(r.Model[:1]) + r.Model[1:]
+	fmt.Fprintf(b, "\n  load%s() {\n", r.Model)
+	fmt.Fprintf(b, "    const value = this.route.snapshot.paramMap.get('%s');\n", r.Param)
+	b.WriteString("    if (!value) return;\n")
+	fmt.Fprintf(b, "    this.%s.set(undefined);\n", v)
+	b.WriteString("    this.loadError.set('');\n")
+	fmt.Fprintf(b, "    this.api.%s({ %s: value }).subscribe({\n", toCamelCase(r.Endpoint), toCamelCase(r.Arg))
+	fmt.Fprintf(b, "      next: (res) => this.%s.set((res.data as %s) ?? null),\n", v, r.Model)
+	b.WriteString("      error: (err) => {\n")
+	fmt.Fprintf(b, "        if (err instanceof HttpErrorResponse && err.status === 404) this.%s.set(null);\n", v)
+	fmt.Fprintf(b, "        else %s;\n", loadFailed(ctx))
+	b.WriteString("      },\n")
+	b.WriteString("    });\n")
+	b.WriteString("  }\n")
+}
+
+// writeRecordNG renders the record a detail route's page shows: its title
+// as the heading and its other fields, or a not-found message.
+func writeRecordNG(b *strings.Builder, indent string, ctx *pageContext) {
+	r := ctx.record
+	v := strings.ToLower(r.Model[:1]) + r.Model[1:]
+	m := findModel(ctx.app, r.Model)
+	fmt.Fprintf(b, "%s@if (%s(); as %s) {\n", indent, v, v)
+	fmt.Fprintf(b, "%s  <article class=\"%s-record\">\n", indent, toKebabCase(r.Model))
+	title := ir.RecordTitle(m)
+	if title != nil {
+		fmt.Fprintf(b, "%s    <h1>{{ %s.%s }}</h1>\n", indent, v, title.Name)
+	} else {
+		fmt.Fprintf(b, "%s    <h1>%s</h1>\n", indent, ir.FieldLabel(r.Model))
+	}
+	fmt.Fprintf(b, "%s    <dl class=\"detail-fields\">\n", indent)
+	for _, f := range ir.RecordFields(m) {
+		if f == title {
+			continue
+		}
+		fmt.Fprintf(b, "%s      <dt>%s</dt>\n", indent, ir.FieldLabel(f.Name))
+		if f.Type == "boolean" {
+			fmt.Fprintf(b, "%s      <dd>{{ %s.%s ? 'Yes' : 'No' }}</dd>\n", indent, v, f.Name)
+		} else if t := timeNG(v+"."+f.Name, f, ctx); t != "" {
+			fmt.Fprintf(b, "%s      <dd>%s</dd>\n", indent, t)
+		} else {
+			fmt.Fprintf(b, "%s      <dd>{{ %s.%s }}</dd>\n", indent, v, f.Name)
+		}
+	}
+	fmt.Fprintf(b, "%s    </dl>\n", indent)
+	fmt.Fprintf(b, "%s  </article>\n", indent)
+	fmt.Fprintf(b, "%s} @else if (%s() === null) {\n", indent, v)
+	fmt.Fprintf(b, "%s  <div class=\"empty-state\">%s not found</div>\n", indent, ir.FieldLabel(r.Model))
+	fmt.Fprintf(b, "%s} @else if (!loadError()) {\n", indent)
+	fmt.Fprintf(b, "%s  <div class=\"loading-spinner\">\n", indent)
+	fmt.Fprintf(b, "%s    <div class=\"spinner\"></div>\n", indent)
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s}\n", indent)
+}
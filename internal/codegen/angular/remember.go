@@ -0,0 +1,64 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+)
+
+// writeRememberState declares how far down the list the user had scrolled
+// when they last left the page at path, read as it opens, and the effect
+// scrolling back there once the list has loaded. The page's keys in
+// sessionStorage name its route rather than the browser's location, which
+// the router only updates once the navigation to the page ends.
+func writeRememberState(b *strings.Builder, path string) {
+	b.WriteString("  // Coming back to the page restores the list as the user left it\n")
+	fmt.Fprintf(b, "  private savedScroll = Number(sessionStorage.getItem('scroll:%s'));\n", path)
+	b.WriteString("  private restoreScroll = effect(() => {\n")
+	b.WriteString("    if (this.loading() || !this.savedScroll) return;\n")
+	b.WriteString("    const y = this.savedScroll;\n")
+	b.WriteString("    this.savedScroll = 0;\n")
+	b.WriteString("    setTimeout(() => window.scrollTo(0, y));\n")
+	b.WriteString("  });\n")
+}
+
+// writeRememberInit emits the start of ngOnInit on a page remembering its
+// list: it restores the view the user left, then keeps the view in
+// sessionStorage as it changes. A paginated list restoring its view skips
+// loading the empty one the page opened on.
+func writeRememberInit(b *strings.Builder, ctx *pageContext, path string) {
+	if ctx.query == nil {
+		b.WriteString("    this.load();\n")
+		return
+	}
+	if ctx.query.Paged {
+		b.WriteString("    const restoring = this.restoreView();\n")
+	} else {
+		b.WriteString("    this.restoreView();\n")
+	}
+	fmt.Fprintf(b, "    this.route.queryParams.subscribe((params) => sessionStorage.setItem('view:%s', new URLSearchParams(params).toString()));\n", path)
+	if ctx.query.Paged {
+		b.WriteString("    this.route.queryParamMap.pipe(skip(restoring ? 1 : 0)).subscribe(() => this.load());\n")
+	} else {
+		b.WriteString("    this.load();\n")
+	}
+}
+
+// writeRememberMethods emits the methods restoring the view of the page's
+// list and saving how far down it the user scrolled. A restored view
+// replaces the page's entry in the history rather than adding one.
+func writeRememberMethods(b *strings.Builder, ctx *pageContext, path string) {
+	if ctx.query != nil {
+		b.WriteString("\n  // Opens the page on the view of its list the user left, when its URL\n")
+		b.WriteString("  // doesn't name one; true when it navigates there.\n")
+		b.WriteString("  private restoreView(): boolean {\n")
+		fmt.Fprintf(b, "    const saved = sessionStorage.getItem('view:%s');\n", path)
+		b.WriteString("    if (!saved || this.route.snapshot.queryParamMap.keys.length > 0) return false;\n")
+		b.WriteString("    this.router.navigate([], { relativeTo: this.route, queryParams: Object.fromEntries(new URLSearchParams(saved)), replaceUrl: true });\n")
+		b.WriteString("    return true;\n")
+		b.WriteString("  }\n")
+	}
+	b.WriteString("\n  @HostListener('window:scroll')\n")
+	b.WriteString("  saveScroll() {\n")
+	fmt.Fprintf(b, "    sessionStorage.setItem('scroll:%s', String(window.scrollY));\n", path)
+	b.WriteString("  }\n")
+}
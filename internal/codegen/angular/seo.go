@@ -0,0 +1,50 @@
+package angular
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateCanonicalLinkService produces
+// src/app/services/canonical-link.service.ts, which keeps the page's
+// <link rel="canonical"> on the site's origin as the route changes. A
+// record's ?id stays part of the URL, as in the sitemap.
+func generateCanonicalLinkService(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Injectable, inject } from '@angular/core';\n")
+	b.WriteString("import { DOCUMENT } from '@angular/common';\n")
+	b.WriteString("import { NavigationEnd, Router } from '@angular/router';\n")
+	b.WriteString("import { filter } from 'rxjs/operators';\n\n")
+	if app.Sitemap.URL != "" {
+		fmt.Fprintf(&b, "const SITE_URL = '%s';\n", app.Sitemap.URL)
+	} else {
+		b.WriteString("const SITE_URL = window.location.origin;\n")
+	}
+
+	b.WriteString(`
+@Injectable({ providedIn: 'root' })
+export class CanonicalLinkService {
+  private document = inject(DOCUMENT);
+  private router = inject(Router);
+
+  start(): void {
+    this.router.events.pipe(filter((e) => e instanceof NavigationEnd)).subscribe(() => {
+      const path = this.router.url.split(/[?#]/)[0];
+      const id = this.router.parseUrl(this.router.url).queryParams['id'];
+      let link = this.document.querySelector<HTMLLinkElement>('link[rel="canonical"]');
+      if (!link) {
+        link = this.document.createElement('link');
+        link.rel = 'canonical';
+        this.document.head.appendChild(link);
+      }
+      link.href = SITE_URL + path + (id ? ` + "`?id=${encodeURIComponent(id)}`" + ` : '');
+    });
+  }
+}
+`)
+	return b.String()
+}
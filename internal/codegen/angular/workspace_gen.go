@@ -32,12 +32,13 @@ func generateAngularJson(app *ir.Application) string {
             "polyfills": ["zone.js"],
             "tsConfig": "tsconfig.json",
             "assets": ["src/favicon.ico", "src/assets"],
-            "styles": ["src/styles.css"],
-            "scripts": []
+            "styles": ` + angularStyles(app) + `,
+            "scripts": [],
+            "outputHashing": "all"
           }
         },
         "serve": {
-          "builder": "@angular-devkit/build-angular:dev-server",
+          "builder": "@angular-devkit/build-angular:dev-server",` + angularServeOptions(app) + `
           "configurations": {
             "production": {
               "buildTarget": "app:build:production"
@@ -51,6 +52,28 @@ func generateAngularJson(app *ir.Application) string {
 }`
 }
 
+// angularServeOptions returns the dev server's options for a build
+// targeting an environment, which answers on its host, or "".
+func angularServeOptions(app *ir.Application) string {
+	env := ir.TargetEnvironment(app)
+	if env == nil {
+		return ""
+	}
+	return fmt.Sprintf(`
+          "options": {
+            "allowedHosts": ["%s"]
+          },`, env.Host())
+}
+
+// angularStyles returns the global stylesheets angular.json builds, with
+// Tailwind's after the theme's when the app is styled using it.
+func angularStyles(app *ir.Application) string {
+	if ir.Styling(app) == ir.StylingTailwind {
+		return `["src/styles.css", "src/tailwind.css"]`
+	}
+	return `["src/styles.css"]`
+}
+
 func generateTsConfig(app *ir.Application) string {
 	return `{
   "compileOnSave": false,
@@ -107,15 +130,36 @@ bootstrapApplication(AppComponent, appConfig)
 }
 
 func generateAppConfig(app *ir.Application) string {
-	return `import { ApplicationConfig } from '@angular/core';
+	// ngx-charts animates its charts
+	if len(app.Charts) > 0 {
+		return `import { ApplicationConfig, ErrorHandler } from '@angular/core';
+import { provideRouter } from '@angular/router';
+import { provideHttpClient } from '@angular/common/http';
+import { provideAnimations } from '@angular/platform-browser/animations';
+import { routes } from './app.routes';
+import { GlobalErrorHandler } from './services/global-error-handler';
+
+export const appConfig: ApplicationConfig = {
+  providers: [
+    provideRouter(routes),
+    provideHttpClient(),
+    provideAnimations(),
+    { provide: ErrorHandler, useExisting: GlobalErrorHandler }
+  ]
+};
+`
+	}
+	return `import { ApplicationConfig, ErrorHandler } from '@angular/core';
 import { provideRouter } from '@angular/router';
 import { provideHttpClient } from '@angular/common/http';
 import { routes } from './app.routes';
+import { GlobalErrorHandler } from './services/global-error-handler';
 
 export const appConfig: ApplicationConfig = {
   providers: [
     provideRouter(routes),
-    provideHttpClient()
+    provideHttpClient(),
+    { provide: ErrorHandler, useExisting: GlobalErrorHandler }
   ]
 };
 `
@@ -128,27 +172,27 @@ func generatePackageJson(app *ir.Application) string {
 	}
 
 	deps := map[string]string{
-		"@angular/animations":              "^17.0.0",
-		"@angular/common":                  "^17.0.0",
-		"@angular/compiler":                "^17.0.0",
-		"@angular/core":                    "^17.0.0",
-		"@angular/forms":                   "^17.0.0",
-		"@angular/platform-browser":        "^17.0.0",
+		"@angular/animations":               "^17.0.0",
+		"@angular/common":                   "^17.0.0",
+		"@angular/compiler":                 "^17.0.0",
+		"@angular/core":                     "^17.0.0",
+		"@angular/forms":                    "^17.0.0",
+		"@angular/platform-browser":         "^17.0.0",
 		"@angular/platform-browser-dynamic": "^17.0.0",
-		"@angular/router":                  "^17.0.0",
-		"rxjs":                             "~7.8.0",
-		"tslib":                            "^2.3.0",
-		"zone.js":                          "~0.14.2",
+		"@angular/router":                   "^17.0.0",
+		"rxjs":                              "~7.8.0",
+		"tslib":                             "^2.3.0",
+		"zone.js":                           "~0.14.2",
 	}
 	devDeps := map[string]string{
 		"@angular-devkit/build-angular": "^17.0.0",
-		"@angular/cli":                 "^17.0.0",
-		"@angular/compiler-cli":        "^17.0.0",
-		"@types/node":                  "^18.18.0",
-		"autoprefixer":                 "^10.4.0",
-		"postcss":                      "^8.4.0",
-		"tailwindcss":                  "^3.4.0",
-		"typescript":                   "~5.2.2",
+		"@angular/cli":                  "^17.0.0",
+		"@angular/compiler-cli":         "^17.0.0",
+		"@types/node":                   "^18.18.0",
+		"autoprefixer":                  "^10.4.0",
+		"postcss":                       "^8.4.0",
+		"tailwindcss":                   "^3.4.0",
+		"typescript":                    "~5.2.2",
 	}
 
 	// Inject design system dependencies
@@ -162,6 +206,19 @@ func generatePackageJson(app *ir.Application) string {
 		}
 	}
 
+	// Self-hosted theme fonts (offline builds)
+	for k, v := range themes.FontDependencies(app.Theme) {
+		deps[k] = v
+	}
+
+	if len(app.Charts) > 0 {
+		deps["@angular/cdk"] = "^17.0.0"
+		deps["@swimlane/ngx-charts"] = "^20.5.0"
+	}
+	if len(app.Reorders) > 0 {
+		deps["@angular/cdk"] = "^17.0.0"
+	}
+
 	// Storybook dependencies
 	for k, v := range storybook.DevDependencies("angular") {
 		devDeps[k] = v
@@ -199,6 +256,9 @@ func generateRoutes(app *ir.Application) string {
 	if hasAuth {
 		b.WriteString("import { authGuard } from './guards/auth.guard';\n")
 	}
+	if len(app.Experiments) > 0 {
+		b.WriteString("import { variantGuard } from './experiments/variant.guard';\n")
+	}
 
 	b.WriteString("\nexport const routes: Routes = [\n")
 
@@ -207,9 +267,28 @@ func generateRoutes(app *ir.Application) string {
 		if strings.ToLower(page.Name) != "home" {
 			routePath = toKebabCase(page.Name)
 		}
+		if r := ir.DetailRouteFor(app, page.Name); r != nil {
+			routePath = strings.TrimPrefix(r.Path(), "/")
+		}
 		fileName := toKebabCase(page.Name)
 		compName := toPascalCase(page.Name) + "Component"
 
+		// The control page's path serves the experiment: one guarded route
+		// per variant, with the control last.
+		if exp := controlExperiment(app, page.Name); exp != nil {
+			control := exp.Variants[0].Page
+			canActivate := ""
+			if hasAuth && !isPublicPage(page.Name) {
+				canActivate = ", canActivate: [authGuard]"
+			}
+			ordered := append(append([]*ir.ExperimentVariant{}, exp.Variants[1:]...), exp.Variants[0])
+			for _, v := range ordered {
+				vFile := toKebabCase(variantPage(app, v.Page))
+				vComp := toPascalCase(variantPage(app, v.Page)) + "Component"
+				b.WriteString(fmt.Sprintf("  { path: '%s', canMatch: [variantGuard('%s', '%s', '%s')], loadComponent: () => import('./pages/%s/%s.component').then(m => m.%s)%s },\n", routePath, exp.Name, v.Page, control, vFile, vFile, vComp, canActivate))
+			}
+		}
+
 		if hasAuth && !isPublicPage(page.Name) {
 			b.WriteString(fmt.Sprintf("  { path: '%s', loadComponent: () => import('./pages/%s/%s.component').then(m => m.%s), canActivate: [authGuard] },\n", routePath, fileName, fileName, compName))
 		} else {
@@ -223,18 +302,43 @@ func generateRoutes(app *ir.Application) string {
 }
 
 func generateAppComponent(app *ir.Application) string {
-	return `import { Component } from '@angular/core';
-import { CommonModule } from '@angular/common';
-import { RouterModule } from '@angular/router';
+	var b strings.Builder
 
-@Component({
-  selector: 'app-root',
-  standalone: true,
-  imports: [CommonModule, RouterModule],
-  template: '<router-outlet></router-outlet>'
-})
-export class AppComponent {}
-`
+	imports := []string{"CommonModule", "RouterModule"}
+	template := "<router-outlet></router-outlet>"
+
+	b.WriteString("import { Component, inject } from '@angular/core';\n")
+	b.WriteString("import { CommonModule } from '@angular/common';\n")
+	b.WriteString("import { RouterModule } from '@angular/router';\n")
+	b.WriteString("import { GlobalErrorHandler } from './services/global-error-handler';\n")
+	if len(app.Notifications) > 0 {
+		b.WriteString("import { NotificationBellComponent } from './components/notification-bell/notification-bell.component';\n")
+		imports = append(imports, "NotificationBellComponent")
+		template = "<app-notification-bell></app-notification-bell>" + template
+	}
+	if app.Sitemap != nil {
+		b.WriteString("import { CanonicalLinkService } from './services/canonical-link.service';\n")
+	}
+	// Errors no page handled show above the page instead of failing silently
+	template = "@if (errors.error(); as message) {" +
+		"<div class=\"error-state\" role=\"alert\"><p>Something went wrong: {{ message }}</p>" +
+		"<button (click)=\"errors.error.set(null)\">Dismiss</button></div>}" + template
+
+	b.WriteString("\n@Component({\n")
+	b.WriteString("  selector: 'app-root',\n")
+	b.WriteString("  standalone: true,\n")
+	fmt.Fprintf(&b, "  imports: [%s],\n", strings.Join(imports, ", "))
+	fmt.Fprintf(&b, "  template: '%s'\n", template)
+	b.WriteString("})\n")
+	b.WriteString("export class AppComponent {\n")
+	b.WriteString("  errors = inject(GlobalErrorHandler);\n")
+	if app.Sitemap != nil {
+		b.WriteString("\n  constructor() {\n")
+		b.WriteString("    inject(CanonicalLinkService).start();\n")
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
 }
 
 func generateNotFoundComponent() string {
@@ -2,11 +2,11 @@ package architecture
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -53,13 +53,7 @@ func (g Generator) generateMicroservices(app *ir.Application, outputDir string)
 		files[filepath.Join(outputDir, "gateway", "Dockerfile")] = generateGatewayDockerfile()
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 func (g Generator) generateServerless(app *ir.Application, outputDir string) error {
@@ -84,24 +78,7 @@ func (g Generator) generateServerless(app *ir.Application, outputDir string) err
 		files[filepath.Join(outputDir, "functions", fnName, handlerFile)] = handlerContent
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 func appNameLower(app *ir.Application) string {
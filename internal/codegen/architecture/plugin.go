@@ -1,6 +1,8 @@
 package architecture
 
 import (
+	"path/filepath"
+
 	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/ir"
 )
@@ -25,3 +27,10 @@ func (g Generator) StageName() string { return "Generating architecture layout"
 
 // OutputDir returns empty because architecture files are written to the root output dir.
 func (g Generator) OutputDir() string { return "" }
+
+// CountFiles counts the services, functions, and gateway.
+func (g Generator) CountFiles(dir string) int {
+	return codegen.CountFiles(filepath.Join(dir, "services")) +
+		codegen.CountFiles(filepath.Join(dir, "functions")) +
+		codegen.CountFiles(filepath.Join(dir, "gateway"))
+}
@@ -0,0 +1,162 @@
+package asyncapi
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Event is a message the application publishes: either a workflow trigger
+// ("when a post is published") or an event a workflow step emits by name
+// (`deliver webhook event "payout.initiated"`).
+type Event struct {
+	Name     string        // dotted event type: "post.published"
+	Summary  string        // the trigger text, or the step that emits it
+	Model    *ir.DataModel // payload data model, nil when the event carries no model
+	Consumed bool          // a workflow in the app listens for it
+}
+
+// eventNamePattern matches a quoted dotted event name in a workflow step.
+var eventNamePattern = regexp.MustCompile(`(?i)\bevent\s+"([a-z0-9_]+(?:\.[a-z0-9_]+)+)"`)
+
+// triggerStopWords are dropped when turning a trigger into an event name.
+var triggerStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "is": true, "are": true, "was": true,
+	"were": true, "becomes": true, "its": true, "their": true,
+}
+
+// Events derives the app's events from its workflows, in declaration order.
+// Triggers that name the same event are merged.
+func Events(app *ir.Application) []Event {
+	var events []Event
+	index := map[string]int{}
+	add := func(e Event) {
+		if i, ok := index[e.Name]; ok {
+			events[i].Consumed = events[i].Consumed || e.Consumed
+			if events[i].Model == nil {
+				events[i].Model = e.Model
+			}
+			return
+		}
+		index[e.Name] = len(events)
+		events = append(events, e)
+	}
+
+	for _, wf := range app.Workflows {
+		model, name := parseTrigger(wf.Trigger, app.Data)
+		add(Event{Name: name, Summary: wf.Trigger, Model: model, Consumed: true})
+	}
+	for _, wf := range app.Workflows {
+		for _, step := range wf.Steps {
+			for _, m := range eventNamePattern.FindAllStringSubmatch(step.Text, -1) {
+				add(Event{Name: strings.ToLower(m[1]), Summary: step.Text})
+			}
+		}
+	}
+	return events
+}
+
+// parseTrigger finds the data model a trigger is about and names the event
+// after it: "a post is published" → Post, "post.published". Triggers that
+// mention no model are grouped under "app".
+func parseTrigger(trigger string, models []*ir.DataModel) (*ir.DataModel, string) {
+	tokens := tokenize(trigger)
+
+	var model *ir.DataModel
+	start, end := -1, -1
+	for _, m := range models {
+		mw := words(m.Name)
+		for i := 0; i+len(mw) <= len(tokens); i++ {
+			if matchesModel(tokens[i:i+len(mw)], mw) && len(mw) > end-start {
+				model, start, end = m, i, i+len(mw)
+			}
+		}
+	}
+
+	var rest []string
+	for i, t := range tokens {
+		if (i >= start && i < end) || triggerStopWords[t] {
+			continue
+		}
+		rest = append(rest, t)
+	}
+
+	domain := "app"
+	if model != nil {
+		domain = strings.Join(words(model.Name), "_")
+	}
+	action := strings.Join(rest, "_")
+	if action == "" {
+		action = "occurred"
+	}
+	return model, domain + "." + action
+}
+
+// matchesModel compares trigger tokens to a model's words, allowing the
+// last word to be plural: "tasks" matches Task.
+func matchesModel(tokens, modelWords []string) bool {
+	for i, w := range modelWords {
+		t := tokens[i]
+		if t == w {
+			continue
+		}
+		if i == len(modelWords)-1 && (t == w+"s" || t == w+"es" || (strings.HasSuffix(w, "y") && t == w[:len(w)-1]+"ies")) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// tokenize lowercases text and splits it into alphanumeric words.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// words splits an identifier on spaces, underscores, hyphens, and case
+// boundaries: "StockMovement" → [stock movement].
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	runes := []rune(s)
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, strings.ToLower(string(cur)))
+			cur = nil
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == ' ' || r == '_' || r == '-':
+			flush()
+		case unicode.IsUpper(r) && len(cur) > 0 &&
+			(!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return out
+}
+
+func toPascalCase(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+func toCamelCase(s string) string {
+	p := toPascalCase(s)
+	if p == "" {
+		return p
+	}
+	return strings.ToLower(p[:1]) + p[1:]
+}
@@ -0,0 +1,168 @@
+// Package asyncapi generates an event contract for apps with workflows: an
+// AsyncAPI 3.0 document describing every event the app publishes, and a
+// JSON Schema per event payload derived from the referenced data model.
+// Consumers outside the generated code validate messages against these.
+package asyncapi
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Generator produces the AsyncAPI document and event schemas.
+type Generator struct{}
+
+// Generate writes asyncapi.yaml, one schema per event, and a README to
+// outputDir.
+func (g Generator) Generate(app *ir.Application, outputDir string) error {
+	events := Events(app)
+
+	files := map[string]string{
+		filepath.Join(outputDir, "asyncapi.yaml"): generateAsyncAPI(app, events),
+		filepath.Join(outputDir, "README.md"):     generateReadme(app, events),
+	}
+	for _, e := range events {
+		content, err := marshalSchema(eventSchema(e))
+		if err != nil {
+			return fmt.Errorf("encoding %s schema: %w", e.Name, err)
+		}
+		files[filepath.Join(outputDir, filepath.FromSlash(schemaPath(e)))] = content
+	}
+
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
+}
+
+// channelID returns the AsyncAPI identifier for an event:
+// "post.published" → "postPublished".
+func channelID(e Event) string {
+	return toCamelCase(strings.ReplaceAll(e.Name, ".", " "))
+}
+
+func generateAsyncAPI(app *ir.Application, events []Event) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("asyncapi: 3.0.0\n")
+	b.WriteString("info:\n")
+	fmt.Fprintf(&b, "  title: %s Events\n", appName(app))
+	b.WriteString("  version: 1.0.0\n")
+	fmt.Fprintf(&b, "  description: Events published by %s. Each payload is an envelope (id, type, occurredAt) around the data, defined by the JSON Schemas in schemas/.\n", appName(app))
+	b.WriteString("defaultContentType: application/json\n")
+
+	b.WriteString("channels:\n")
+	for _, e := range events {
+		id := channelID(e)
+		fmt.Fprintf(&b, "  %s:\n", id)
+		fmt.Fprintf(&b, "    address: %s\n", e.Name)
+		fmt.Fprintf(&b, "    description: %s\n", yamlString(e.Summary))
+		b.WriteString("    messages:\n")
+		fmt.Fprintf(&b, "      %s:\n", id)
+		fmt.Fprintf(&b, "        $ref: '#/components/messages/%s'\n", id)
+	}
+
+	b.WriteString("operations:\n")
+	for _, e := range events {
+		id := channelID(e)
+		writeOperation(&b, "publish"+toPascalCase(id), "send", id)
+		if e.Consumed {
+			writeOperation(&b, "on"+toPascalCase(id), "receive", id)
+		}
+	}
+
+	b.WriteString("components:\n")
+	b.WriteString("  messages:\n")
+	for _, e := range events {
+		fmt.Fprintf(&b, "    %s:\n", channelID(e))
+		fmt.Fprintf(&b, "      name: %s\n", e.Name)
+		fmt.Fprintf(&b, "      summary: %s\n", yamlString(e.Summary))
+		b.WriteString("      payload:\n")
+		b.WriteString("        schemaFormat: application/schema+json;version=draft-07\n")
+		b.WriteString("        schema:\n")
+		fmt.Fprintf(&b, "          $ref: './%s'\n", schemaPath(e))
+	}
+
+	return b.String()
+}
+
+func writeOperation(b *strings.Builder, name, action, channel string) {
+	fmt.Fprintf(b, "  %s:\n", name)
+	fmt.Fprintf(b, "    action: %s\n", action)
+	b.WriteString("    channel:\n")
+	fmt.Fprintf(b, "      $ref: '#/channels/%s'\n", channel)
+	b.WriteString("    messages:\n")
+	fmt.Fprintf(b, "      - $ref: '#/channels/%s/messages/%s'\n", channel, channel)
+}
+
+func generateReadme(app *ir.Application, events []Event) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s event contract\n\n", appName(app))
+	b.WriteString("Generated by the Human compiler from the app's workflows. ")
+	b.WriteString("`asyncapi.yaml` describes every event the app publishes; `schemas/` holds a JSON Schema (draft-07) per event payload, ")
+	b.WriteString("so services outside the generated code can validate messages against the same contract.\n\n")
+
+	b.WriteString("## Events\n\n")
+	b.WriteString("| Event | Data | Schema | Source |\n")
+	b.WriteString("|-------|------|--------|--------|\n")
+	for _, e := range events {
+		data := "object"
+		if e.Model != nil {
+			data = e.Model.Name
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | [`%s`](%s) | %s |\n", e.Name, data, schemaPath(e), schemaPath(e), strings.ReplaceAll(e.Summary, "|", "\\|"))
+	}
+
+	b.WriteString("\n## Envelope\n\n")
+	b.WriteString("```json\n")
+	b.WriteString("{\n")
+	b.WriteString("  \"id\": \"9b2f…\",\n")
+	if len(events) > 0 {
+		fmt.Fprintf(&b, "  \"type\": \"%s\",\n", events[0].Name)
+	}
+	b.WriteString("  \"occurredAt\": \"2024-01-01T12:00:00Z\",\n")
+	b.WriteString("  \"data\": { }\n")
+	b.WriteString("}\n")
+	b.WriteString("```\n\n")
+
+	b.WriteString("## Validating messages\n\n")
+	b.WriteString("Node (ajv):\n\n")
+	b.WriteString("```ts\n")
+	b.WriteString("import Ajv from 'ajv';\n")
+	b.WriteString("import addFormats from 'ajv-formats';\n")
+	if len(events) > 0 {
+		fmt.Fprintf(&b, "import schema from './%s';\n\n", schemaPath(events[0]))
+	}
+	b.WriteString("const ajv = addFormats(new Ajv());\n")
+	b.WriteString("const validate = ajv.compile(schema);\n")
+	b.WriteString("if (!validate(message)) throw new Error(ajv.errorsText(validate.errors));\n")
+	b.WriteString("```\n\n")
+	b.WriteString("Python (jsonschema):\n\n")
+	b.WriteString("```python\n")
+	b.WriteString("import json, jsonschema\n\n")
+	if len(events) > 0 {
+		fmt.Fprintf(&b, "schema = json.load(open(\"%s\"))\n", schemaPath(events[0]))
+	}
+	b.WriteString("jsonschema.validate(message, schema, format_checker=jsonschema.FormatChecker())\n")
+	b.WriteString("```\n")
+
+	return b.String()
+}
+
+// yamlString quotes s when it could be misread as YAML syntax.
+func yamlString(s string) string {
+	if strings.ContainsAny(s, ":#'\"{}[],&*!|>%@`") || strings.HasPrefix(s, "-") {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	return s
+}
+
+func appName(app *ir.Application) string {
+	if app.Name == "" {
+		return "App"
+	}
+	return app.Name
+}
@@ -0,0 +1,151 @@
+package asyncapi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func testApp() *ir.Application {
+	return &ir.Application{
+		Name: "Shop",
+		Data: []*ir.DataModel{
+			{
+				Name: "Order",
+				Fields: []*ir.DataField{
+					{Name: "total", Type: "decimal", Required: true},
+					{Name: "status", Type: "enum", EnumValues: []string{"placed", "shipped"}, Required: true},
+					{Name: "notes", Type: "text"},
+				},
+				Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}},
+			},
+			{Name: "User", Fields: []*ir.DataField{{Name: "email", Type: "email", Required: true}, {Name: "password", Type: "text", Required: true}}},
+			{Name: "StockMovement", Fields: []*ir.DataField{{Name: "quantity", Type: "number", Required: true}}},
+		},
+		Workflows: []*ir.Workflow{
+			{Trigger: "an order is placed", Steps: []*ir.Action{{Text: `deliver webhook event "payment.captured"`}}},
+			{Trigger: "a stock movement is recorded"},
+			{Trigger: "a user signs up"},
+			{Trigger: "the nightly report is due"},
+			{Trigger: "orders are placed"},
+		},
+	}
+}
+
+func TestEvents(t *testing.T) {
+	events := Events(testApp())
+
+	want := []struct {
+		name, model string
+		consumed    bool
+	}{
+		{"order.placed", "Order", true},
+		{"stock_movement.recorded", "StockMovement", true},
+		{"user.signs_up", "User", true},
+		{"app.nightly_report_due", "", true},
+		{"payment.captured", "", false},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		model := ""
+		if e.Model != nil {
+			model = e.Model.Name
+		}
+		if e.Name != w.name || model != w.model || e.Consumed != w.consumed {
+			t.Errorf("event %d = %s (%s, consumed=%v), want %s (%s, consumed=%v)", i, e.Name, model, e.Consumed, w.name, w.model, w.consumed)
+		}
+	}
+}
+
+func TestEventSchema(t *testing.T) {
+	events := Events(testApp())
+	content, err := marshalSchema(eventSchema(events[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(content), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	props := schema["properties"].(map[string]any)
+	if props["type"].(map[string]any)["const"] != "order.placed" {
+		t.Error("type should be pinned to the event name")
+	}
+
+	data := props["data"].(map[string]any)
+	fields := data["properties"].(map[string]any)
+	if fields["total"].(map[string]any)["type"] != "number" {
+		t.Error("decimal should map to number")
+	}
+	if _, ok := fields["userId"]; !ok {
+		t.Error("belongs_to should add a userId foreign key")
+	}
+	notes := fields["notes"].(map[string]any)["type"].([]any)
+	if len(notes) != 2 || notes[1] != "null" {
+		t.Errorf("optional notes type = %v, want [string null]", notes)
+	}
+	required := data["required"].([]any)
+	if len(required) != 4 || required[3] != "userId" {
+		t.Errorf("required = %v, want [id total status userId]", required)
+	}
+
+	// Declaration order is preserved.
+	if strings.Index(content, `"total"`) > strings.Index(content, `"notes"`) {
+		t.Error("fields should follow declaration order")
+	}
+
+	user, _ := marshalSchema(eventSchema(events[2]))
+	if strings.Contains(user, "password") {
+		t.Error("password must not appear in event payloads")
+	}
+}
+
+func TestAsyncAPIDocument(t *testing.T) {
+	app := testApp()
+	doc := generateAsyncAPI(app, Events(app))
+
+	for _, want := range []string{
+		"asyncapi: 3.0.0\n",
+		"  title: Shop Events\n",
+		"  orderPlaced:\n    address: order.placed\n",
+		"  publishOrderPlaced:\n    action: send\n",
+		"  onOrderPlaced:\n    action: receive\n",
+		"          $ref: './schemas/order.placed.json'\n",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("asyncapi.yaml missing %q", want)
+		}
+	}
+	if strings.Contains(doc, "onPaymentCaptured") {
+		t.Error("events only emitted by steps have no receive operation")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(testApp(), dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"asyncapi.yaml", "README.md", "schemas/order.placed.json", "schemas/payment.captured.json"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("missing %s", rel)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	if (Generator{}).Enabled(&ir.Application{}) {
+		t.Error("apps without workflows should not generate event schemas")
+	}
+	if !(Generator{}).Enabled(testApp()) {
+		t.Error("apps with workflows should generate event schemas")
+	}
+}
@@ -0,0 +1,30 @@
+package asyncapi
+
+import (
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Meta returns the generator's metadata.
+func (g Generator) Meta() codegen.PluginMeta {
+	return codegen.PluginMeta{
+		Name:        "asyncapi",
+		Version:     "1.0.0",
+		Description: "AsyncAPI document and JSON Schemas for published events",
+		Category:    codegen.CategoryBackend,
+	}
+}
+
+// Enabled reports whether the app declares any event workflows.
+func (g Generator) Enabled(app *ir.Application) bool {
+	return len(app.Workflows) > 0
+}
+
+// StageName returns the display name for progress reporting.
+func (g Generator) StageName() string { return "Generating event schemas" }
+
+// OutputDir returns the subdirectory name within the build output.
+func (g Generator) OutputDir() string { return "events" }
+
+// Inputs returns the parts of the IR the event schemas are generated from.
+func (g Generator) Inputs() []string { return []string{"name", "data", "workflows"} }
@@ -0,0 +1,164 @@
+package asyncapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// object is a JSON object that keeps its keys in insertion order, so
+// schemas read in the same order as the .human declarations.
+type object []member
+
+type member struct {
+	Key   string
+	Value any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// schemaPath returns an event's schema file, relative to the output
+// directory: "schemas/post.published.json".
+func schemaPath(e Event) string {
+	return "schemas/" + e.Name + ".json"
+}
+
+// eventSchema returns the JSON Schema (draft-07) for an event: an
+// envelope with the event id, type, and timestamp around the payload data.
+func eventSchema(e Event) object {
+	return object{
+		{"$schema", "http://json-schema.org/draft-07/schema#"},
+		{"title", e.Name},
+		{"description", e.Summary},
+		{"type", "object"},
+		{"properties", object{
+			{"id", object{{"type", "string"}, {"description", "Unique event id, for deduplication"}}},
+			{"type", object{{"const", e.Name}}},
+			{"occurredAt", object{{"type", "string"}, {"format", "date-time"}}},
+			{"data", dataSchema(e.Model)},
+		}},
+		{"required", []string{"id", "type", "occurredAt", "data"}},
+		{"additionalProperties", false},
+	}
+}
+
+// dataSchema describes a data model record as the generated backends
+// serialize it. Events without a model carry a free-form object.
+func dataSchema(model *ir.DataModel) object {
+	if model == nil {
+		return object{{"type", "object"}}
+	}
+
+	props := object{{"id", object{{"type", "string"}}}}
+	required := []string{"id"}
+	for _, f := range model.Fields {
+		if strings.EqualFold(f.Name, "password") {
+			continue // never leaves the backend
+		}
+		props = append(props, member{f.Name, fieldSchema(f)})
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+	for _, rel := range model.Relations {
+		if rel.Kind == "belongs_to" {
+			fk := toCamelCase(rel.Target) + "Id"
+			props = append(props, member{fk, object{{"type", "string"}}})
+			required = append(required, fk)
+		}
+	}
+	props = append(props,
+		member{"createdAt", object{{"type", "string"}, {"format", "date-time"}}},
+		member{"updatedAt", object{{"type", "string"}, {"format", "date-time"}}},
+	)
+
+	return object{
+		{"title", model.Name},
+		{"type", "object"},
+		{"properties", props},
+		{"required", required},
+	}
+}
+
+// fieldSchema maps an IR field type to a JSON Schema. Optional fields
+// also accept null, which is how the backends serialize unset columns.
+func fieldSchema(f *ir.DataField) object {
+	s := baseFieldSchema(f)
+	if f.Required {
+		return s
+	}
+	for i, m := range s {
+		switch m.Key {
+		case "type":
+			s[i].Value = []string{m.Value.(string), "null"}
+		case "enum":
+			s[i].Value = append(toAny(m.Value.([]string)), nil)
+		}
+	}
+	return s
+}
+
+func toAny(values []string) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+func baseFieldSchema(f *ir.DataField) object {
+	switch strings.ToLower(f.Type) {
+	case "number":
+		return object{{"type", "integer"}}
+	case "decimal":
+		return object{{"type", "number"}}
+	case "boolean":
+		return object{{"type", "boolean"}}
+	case "email":
+		return object{{"type", "string"}, {"format", "email"}}
+	case "url", "file", "image":
+		return object{{"type", "string"}, {"format", "uri"}}
+	case "date":
+		return object{{"type", "string"}, {"format", "date"}}
+	case "datetime":
+		return object{{"type", "string"}, {"format", "date-time"}}
+	case "enum":
+		return object{{"type", "string"}, {"enum", f.EnumValues}}
+	case "json":
+		return object{}
+	default:
+		return object{{"type", "string"}}
+	}
+}
+
+// marshalSchema encodes a schema with two-space indentation.
+func marshalSchema(o object) (string, error) {
+	out, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
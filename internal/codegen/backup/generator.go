@@ -0,0 +1,347 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Generator produces database backup automation from the database block's
+// backup rules: pg_dump and restore scripts, a cron container for Docker
+// deployments, and a scheduled GitHub Actions workflow.
+type Generator struct{}
+
+// Generate writes backup/ and .github/workflows/backup.yml to outputDir.
+func (g Generator) Generate(app *ir.Application, outputDir string) error {
+	files := map[string]string{
+		filepath.Join(outputDir, "backup", "backup.sh"):                generateBackupScript(app),
+		filepath.Join(outputDir, "backup", "restore.sh"):               generateRestoreScript(app),
+		filepath.Join(outputDir, "backup", "entrypoint.sh"):            generateEntrypoint(),
+		filepath.Join(outputDir, "backup", "crontab"):                  generateCrontab(app),
+		filepath.Join(outputDir, "backup", "Dockerfile"):               generateDockerfile(app),
+		filepath.Join(outputDir, ".github", "workflows", "backup.yml"): generateWorkflow(app),
+	}
+
+	if err := codegen.WriteFiles(files, codegen.WriteOptionsFor(app)); err != nil {
+		return err
+	}
+	// The scripts run in the cron container and by hand.
+	for path := range files {
+		if strings.HasSuffix(path, ".sh") {
+			if err := os.Chmod(path, 0755); err != nil {
+				return fmt.Errorf("making %s executable: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func appNameLower(app *ir.Application) string {
+	if app.Name != "" {
+		return strings.ToLower(strings.ReplaceAll(app.Name, " ", "-"))
+	}
+	return "app"
+}
+
+// storage returns the S3 integration that receives backups, or nil.
+func storage(app *ir.Application) *ir.Integration {
+	name := app.Database.Backup.Storage
+	if name == "" {
+		return nil
+	}
+	for _, integ := range app.Integrations {
+		if integ.Service == name {
+			return integ
+		}
+	}
+	return nil
+}
+
+// storageEnv returns the environment variables that hold the storage
+// integration's access key and secret, e.g. AWS_ACCESS_KEY and
+// AWS_SECRET_KEY.
+func storageEnv(integ *ir.Integration) (keyEnv, secretEnv string) {
+	keyEnv, secretEnv = "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"
+	for label, env := range integ.Credentials {
+		if strings.Contains(label, "secret") {
+			secretEnv = env
+		} else {
+			keyEnv = env
+		}
+	}
+	return keyEnv, secretEnv
+}
+
+// ── backup.sh ──
+
+func generateBackupScript(app *ir.Application) string {
+	b := app.Database.Backup
+	name := appNameLower(app)
+	var sb strings.Builder
+
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString("# Generated by Human compiler — do not edit\n")
+	sb.WriteString("#\n")
+	fmt.Fprintf(&sb, "# Backs up the database with pg_dump (scheduled: %s, cron %q, UTC).\n", b.Description, b.Cron())
+	sb.WriteString("# Backups are written to BACKUP_DIR")
+	if storage(app) != nil {
+		sb.WriteString(" and copied to S3")
+	}
+	sb.WriteString(".\n")
+	if b.RetentionDays > 0 {
+		fmt.Fprintf(&sb, "# Backups older than %d days are deleted.\n", b.RetentionDays)
+	}
+	sb.WriteString("set -eu\n\n")
+
+	sb.WriteString(": \"${DATABASE_URL:?DATABASE_URL is not set}\"\n")
+	sb.WriteString("BACKUP_DIR=\"${BACKUP_DIR:-$(dirname \"$0\")/../backups}\"\n")
+	fmt.Fprintf(&sb, "RETENTION_DAYS=\"${BACKUP_RETENTION_DAYS:-%d}\"\n", b.RetentionDays)
+	fmt.Fprintf(&sb, "PREFIX=%s\n\n", name)
+
+	writeURLHelper(&sb)
+	if integ := storage(app); integ != nil {
+		writeS3Helper(&sb, integ)
+	}
+
+	sb.WriteString("mkdir -p \"$BACKUP_DIR\"\n")
+	sb.WriteString("name=\"$PREFIX-$(date -u +%Y%m%dT%H%M%SZ).dump\"\n")
+	sb.WriteString("pg_dump --format=custom --no-owner --no-acl --file=\"$BACKUP_DIR/$name.partial\" \"$(libpq_url)\"\n")
+	sb.WriteString("mv \"$BACKUP_DIR/$name.partial\" \"$BACKUP_DIR/$name\"\n")
+	sb.WriteString("echo \"Backed up to $BACKUP_DIR/$name\"\n")
+	if storage(app) != nil {
+		sb.WriteString("\nif [ -n \"$S3_URL\" ]; then\n")
+		sb.WriteString("  s3 cp \"$BACKUP_DIR/$name\" \"$S3_URL/$name\"\n")
+		sb.WriteString("  echo \"Copied to $S3_URL/$name\"\n")
+		sb.WriteString("else\n")
+		sb.WriteString("  echo \"S3_BUCKET is not set, so the backup was not copied to S3\" >&2\n")
+		sb.WriteString("fi\n")
+	}
+
+	sb.WriteString("\n# Retention\n")
+	sb.WriteString("if [ \"$RETENTION_DAYS\" -gt 0 ]; then\n")
+	sb.WriteString("  find \"$BACKUP_DIR\" -name \"$PREFIX-*.dump\" -mtime +\"$RETENTION_DAYS\" -exec rm -f {} \\;\n")
+	if storage(app) != nil {
+		sb.WriteString("  if [ -n \"$S3_URL\" ]; then\n")
+		sb.WriteString("    # GNU and BusyBox date take -d @<seconds>; BSD date takes -v.\n")
+		sb.WriteString("    stamp=$(date -u -d \"@$(( $(date +%s) - RETENTION_DAYS * 86400 ))\" +%Y%m%dT%H%M%SZ 2>/dev/null ||\n")
+		sb.WriteString("      date -u -v-\"$RETENTION_DAYS\"d +%Y%m%dT%H%M%SZ)\n")
+		sb.WriteString("    cutoff=\"$PREFIX-$stamp.dump\"\n")
+		sb.WriteString("    s3 ls \"$S3_URL/\" | awk -v cutoff=\"$cutoff\" -v prefix=\"$PREFIX-\" \\\n")
+		sb.WriteString("      'index($4, prefix) == 1 && $4 < cutoff { print $4 }' |\n")
+		sb.WriteString("      while read -r old; do\n")
+		sb.WriteString("        s3 rm \"$S3_URL/$old\"\n")
+		sb.WriteString("      done\n")
+		sb.WriteString("  fi\n")
+	}
+	sb.WriteString("fi\n")
+
+	return sb.String()
+}
+
+// writeURLHelper writes libpq_url, which drops Prisma's ?schema= parameter
+// from DATABASE_URL since pg_dump and pg_restore reject it.
+func writeURLHelper(sb *strings.Builder) {
+	sb.WriteString("# Prisma's ?schema= parameter isn't a libpq option.\n")
+	sb.WriteString("libpq_url() {\n")
+	sb.WriteString("  printf '%s' \"$DATABASE_URL\" | sed -E 's/([?&])schema=[^&]*&?/\\1/; s/[?&]$//'\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeS3Helper writes S3_URL, empty when no bucket is set, and an s3
+// function that runs the AWS CLI with the storage integration's
+// credentials. S3_ENDPOINT points it at an S3-compatible service such as
+// MinIO.
+func writeS3Helper(sb *strings.Builder, integ *ir.Integration) {
+	keyEnv, secretEnv := storageEnv(integ)
+	region := integ.Config["region"]
+	if region == "" {
+		region = "us-east-1"
+	}
+	bucket := integ.Config["bucket"]
+
+	fmt.Fprintf(sb, "# %s\n", integ.Service)
+	if keyEnv != "AWS_ACCESS_KEY_ID" {
+		fmt.Fprintf(sb, "export AWS_ACCESS_KEY_ID=\"${AWS_ACCESS_KEY_ID:-${%s:-}}\"\n", keyEnv)
+	}
+	if secretEnv != "AWS_SECRET_ACCESS_KEY" {
+		fmt.Fprintf(sb, "export AWS_SECRET_ACCESS_KEY=\"${AWS_SECRET_ACCESS_KEY:-${%s:-}}\"\n", secretEnv)
+	}
+	fmt.Fprintf(sb, "export AWS_DEFAULT_REGION=\"${AWS_REGION:-%s}\"\n", region)
+	fmt.Fprintf(sb, "S3_BUCKET=\"${S3_BUCKET:-%s}\"\n", bucket)
+	sb.WriteString("S3_URL=\"${S3_BUCKET:+s3://$S3_BUCKET/backups}\"\n")
+	sb.WriteString("s3() {\n")
+	sb.WriteString("  aws ${S3_ENDPOINT:+--endpoint-url \"$S3_ENDPOINT\"} s3 \"$@\"\n")
+	sb.WriteString("}\n\n")
+}
+
+// ── restore.sh ──
+
+func generateRestoreScript(app *ir.Application) string {
+	name := appNameLower(app)
+	integ := storage(app)
+	var sb strings.Builder
+
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString("# Generated by Human compiler — do not edit\n")
+	sb.WriteString("#\n")
+	sb.WriteString("# Restores a backup made by backup.sh, replacing the database's contents.\n")
+	sb.WriteString("#\n")
+	sb.WriteString("#   restore.sh latest\n")
+	fmt.Fprintf(&sb, "#   restore.sh %s-20260101T030000Z.dump\n", name)
+	sb.WriteString("#   restore.sh /path/to/backup.dump\n")
+	sb.WriteString("#\n")
+	sb.WriteString("# A backup name is looked up in BACKUP_DIR")
+	if integ != nil {
+		sb.WriteString(", then in S3")
+	}
+	sb.WriteString(".\n")
+	sb.WriteString("set -eu\n\n")
+
+	sb.WriteString(": \"${DATABASE_URL:?DATABASE_URL is not set}\"\n")
+	sb.WriteString("BACKUP_DIR=\"${BACKUP_DIR:-$(dirname \"$0\")/../backups}\"\n")
+	fmt.Fprintf(&sb, "PREFIX=%s\n", name)
+	sb.WriteString("backup=\"${1:?usage: restore.sh <latest | backup name | file>}\"\n\n")
+
+	writeURLHelper(&sb)
+	if integ != nil {
+		writeS3Helper(&sb, integ)
+	}
+
+	sb.WriteString("if [ \"$backup\" = latest ]; then\n")
+	sb.WriteString("  backup=$(ls -1 \"$BACKUP_DIR\" 2>/dev/null | grep \"^$PREFIX-.*\\.dump$\" | sort | tail -n 1 || true)\n")
+	if integ != nil {
+		sb.WriteString("  if [ -n \"$S3_URL\" ]; then\n")
+		sb.WriteString("    remote=$(s3 ls \"$S3_URL/\" | awk '{ print $4 }' | grep \"^$PREFIX-.*\\.dump$\" | sort | tail -n 1 || true)\n")
+		sb.WriteString("    if [ -n \"$remote\" ] && [ \"$remote\" \\> \"$backup\" ]; then\n")
+		sb.WriteString("      backup=$remote\n")
+		sb.WriteString("    fi\n")
+		sb.WriteString("  fi\n")
+	}
+	sb.WriteString("  if [ -z \"$backup\" ]; then\n")
+	sb.WriteString("    echo \"No backups found.\" >&2\n")
+	sb.WriteString("    exit 1\n")
+	sb.WriteString("  fi\n")
+	sb.WriteString("fi\n\n")
+
+	sb.WriteString("if [ -f \"$backup\" ]; then\n")
+	sb.WriteString("  file=$backup\n")
+	sb.WriteString("elif [ -f \"$BACKUP_DIR/$backup\" ]; then\n")
+	sb.WriteString("  file=$BACKUP_DIR/$backup\n")
+	if integ != nil {
+		sb.WriteString("elif [ -n \"$S3_URL\" ]; then\n")
+		sb.WriteString("  file=$(mktemp)\n")
+		sb.WriteString("  trap 'rm -f \"$file\"' EXIT\n")
+		sb.WriteString("  s3 cp \"$S3_URL/$(basename \"$backup\")\" \"$file\"\n")
+	}
+	sb.WriteString("else\n")
+	sb.WriteString("  echo \"Backup not found: $backup\" >&2\n")
+	sb.WriteString("  exit 1\n")
+	sb.WriteString("fi\n\n")
+
+	sb.WriteString("echo \"Restoring $backup...\"\n")
+	sb.WriteString("pg_restore --clean --if-exists --no-owner --no-acl --single-transaction --dbname=\"$(libpq_url)\" \"$file\"\n")
+	sb.WriteString("echo \"Restored $backup\"\n")
+
+	return sb.String()
+}
+
+// ── Backup container ──
+
+// generateEntrypoint saves the container's environment for cron jobs,
+// which start with an empty one, then runs crond in the foreground.
+func generateEntrypoint() string {
+	return `#!/bin/sh
+# Generated by Human compiler — do not edit
+set -eu
+export -p > /etc/backup.env
+chmod 600 /etc/backup.env
+exec crond -f -l 8
+`
+}
+
+func generateCrontab(app *ir.Application) string {
+	b := app.Database.Backup
+	var sb strings.Builder
+	sb.WriteString("# Generated by Human compiler — do not edit\n")
+	fmt.Fprintf(&sb, "# backup %s (UTC)\n", b.Description)
+	fmt.Fprintf(&sb, "%s . /etc/backup.env; /backup/backup.sh > /proc/1/fd/1 2>&1\n", b.Cron())
+	return sb.String()
+}
+
+func generateDockerfile(app *ir.Application) string {
+	var sb strings.Builder
+	sb.WriteString("# Generated by Human compiler — do not edit\n")
+	sb.WriteString("FROM postgres:16-alpine\n")
+	if storage(app) != nil {
+		sb.WriteString("RUN apk add --no-cache aws-cli\n")
+	}
+	sb.WriteString("COPY backup.sh restore.sh entrypoint.sh /backup/\n")
+	sb.WriteString("COPY crontab /etc/crontabs/root\n")
+	sb.WriteString("RUN chmod +x /backup/*.sh\n")
+	sb.WriteString("ENV BACKUP_DIR=/backups\n")
+	sb.WriteString("VOLUME /backups\n")
+	sb.WriteString("ENTRYPOINT [\"/backup/entrypoint.sh\"]\n")
+	return sb.String()
+}
+
+// ── Scheduled workflow ──
+
+// artifactRetentionLimit is the longest GitHub keeps workflow artifacts.
+const artifactRetentionLimit = 90
+
+func generateWorkflow(app *ir.Application) string {
+	b := app.Database.Backup
+	name := appNameLower(app)
+	integ := storage(app)
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "name: %s-backup\n\n", name)
+	sb.WriteString("# Set the DATABASE_URL secret to the production database.\n")
+	if integ != nil {
+		keyEnv, secretEnv := storageEnv(integ)
+		fmt.Fprintf(&sb, "# Backups are copied to S3 with the %s, %s, AWS_REGION, and S3_BUCKET secrets.\n", keyEnv, secretEnv)
+	} else {
+		sb.WriteString("# Backups are kept as workflow artifacts.\n")
+	}
+	sb.WriteString("\"on\":\n")
+	sb.WriteString("  schedule:\n")
+	fmt.Fprintf(&sb, "    - cron: '%s'\n", b.Cron())
+	sb.WriteString("  workflow_dispatch:\n\n")
+	sb.WriteString("jobs:\n")
+	sb.WriteString("  backup:\n")
+	sb.WriteString("    runs-on: ubuntu-latest\n")
+	sb.WriteString("    steps:\n")
+	sb.WriteString("      - uses: actions/checkout@v4\n")
+	sb.WriteString("      - name: Install PostgreSQL client\n")
+	sb.WriteString("        run: |\n")
+	sb.WriteString("          sudo apt-get update\n")
+	sb.WriteString("          sudo apt-get install -y postgresql-client-16\n")
+	sb.WriteString("      - name: Back up database\n")
+	sb.WriteString("        run: sh backup/backup.sh\n")
+	sb.WriteString("        env:\n")
+	sb.WriteString("          DATABASE_URL: ${{ secrets.DATABASE_URL }}\n")
+	sb.WriteString("          BACKUP_DIR: backups\n")
+	if integ != nil {
+		keyEnv, secretEnv := storageEnv(integ)
+		for _, env := range []string{keyEnv, secretEnv, "AWS_REGION", "S3_BUCKET"} {
+			fmt.Fprintf(&sb, "          %s: ${{ secrets.%s }}\n", env, env)
+		}
+		return sb.String()
+	}
+
+	retention := b.RetentionDays
+	if retention == 0 || retention > artifactRetentionLimit {
+		retention = artifactRetentionLimit
+	}
+	sb.WriteString("      - name: Upload backup\n")
+	sb.WriteString("        uses: actions/upload-artifact@v4\n")
+	sb.WriteString("        with:\n")
+	fmt.Fprintf(&sb, "          name: %s-backup-${{ github.run_id }}\n", name)
+	sb.WriteString("          path: backups/\n")
+	fmt.Fprintf(&sb, "          retention-days: %d\n", retention)
+	return sb.String()
+}
@@ -0,0 +1,154 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func testApp() *ir.Application {
+	return &ir.Application{
+		Name:   "TaskFlow",
+		Config: &ir.BuildConfig{Backend: "Node with Express", Database: "PostgreSQL", Deploy: "Docker"},
+		Database: &ir.DatabaseConfig{
+			Engine: "PostgreSQL",
+			Backup: &ir.Backup{Schedule: "0 3 * * *", Description: "daily at 3 am", RetentionDays: 30},
+		},
+	}
+}
+
+func withS3(app *ir.Application) *ir.Application {
+	app.Integrations = []*ir.Integration{{
+		Service:     "AWS S3",
+		Type:        "storage",
+		Credentials: map[string]string{"api key": "AWS_ACCESS_KEY", "secret": "AWS_SECRET_KEY"},
+		Config:      map[string]string{"bucket": "taskflow-files"},
+	}}
+	app.Database.Backup.Storage = "AWS S3"
+	return app
+}
+
+func TestEnabled(t *testing.T) {
+	if !(Generator{}).Enabled(testApp()) {
+		t.Error("expected the generator to run for a PostgreSQL app with backup rules")
+	}
+
+	mysql := testApp()
+	mysql.Config.Database = "MySQL"
+	if (Generator{}).Enabled(mysql) {
+		t.Error("backups are only generated for PostgreSQL")
+	}
+
+	none := testApp()
+	none.Database.Backup = nil
+	if (Generator{}).Enabled(none) {
+		t.Error("no backup rules should mean no backups")
+	}
+}
+
+func TestGenerateWritesFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(testApp(), dir); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	for _, rel := range []string{"backup/backup.sh", "backup/restore.sh", "backup/entrypoint.sh", "backup/crontab", "backup/Dockerfile", ".github/workflows/backup.yml"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("missing %s", rel)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "backup", "backup.sh"))
+	if err == nil && info.Mode().Perm()&0111 == 0 {
+		t.Error("backup.sh should be executable")
+	}
+}
+
+func TestBackupScript(t *testing.T) {
+	script := generateBackupScript(testApp())
+	for _, want := range []string{
+		"#!/bin/sh",
+		`RETENTION_DAYS="${BACKUP_RETENTION_DAYS:-30}"`,
+		"PREFIX=taskflow",
+		"pg_dump --format=custom --no-owner --no-acl",
+		`-mtime +"$RETENTION_DAYS"`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("backup.sh missing %q", want)
+		}
+	}
+	if strings.Contains(script, "aws") {
+		t.Error("backup.sh without a storage integration should not use S3")
+	}
+}
+
+func TestBackupScriptS3(t *testing.T) {
+	app := withS3(testApp())
+	script := generateBackupScript(app)
+	for _, want := range []string{
+		`export AWS_ACCESS_KEY_ID="${AWS_ACCESS_KEY_ID:-${AWS_ACCESS_KEY:-}}"`,
+		`export AWS_SECRET_ACCESS_KEY="${AWS_SECRET_ACCESS_KEY:-${AWS_SECRET_KEY:-}}"`,
+		`S3_BUCKET="${S3_BUCKET:-taskflow-files}"`,
+		`s3 cp "$BACKUP_DIR/$name" "$S3_URL/$name"`,
+		`s3 rm "$S3_URL/$old"`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("backup.sh missing %q", want)
+		}
+	}
+
+	restore := generateRestoreScript(app)
+	for _, want := range []string{
+		"pg_restore --clean --if-exists --no-owner --no-acl --single-transaction",
+		`s3 cp "$S3_URL/$(basename "$backup")" "$file"`,
+	} {
+		if !strings.Contains(restore, want) {
+			t.Errorf("restore.sh missing %q", want)
+		}
+	}
+
+	if !strings.Contains(generateDockerfile(app), "apk add --no-cache aws-cli") {
+		t.Error("Dockerfile should install the AWS CLI for S3 uploads")
+	}
+}
+
+func TestCrontab(t *testing.T) {
+	crontab := generateCrontab(testApp())
+	if !strings.Contains(crontab, "0 3 * * * . /etc/backup.env; /backup/backup.sh") {
+		t.Errorf("unexpected crontab:\n%s", crontab)
+	}
+
+	app := testApp()
+	app.Database.Backup.Schedule = ""
+	if !strings.Contains(generateCrontab(app), ir.DefaultBackupSchedule+" ") {
+		t.Error("an unparsed schedule should fall back to the default")
+	}
+}
+
+func TestWorkflow(t *testing.T) {
+	wf := generateWorkflow(testApp())
+	for _, want := range []string{
+		"name: taskflow-backup",
+		"    - cron: '0 3 * * *'",
+		"  workflow_dispatch:",
+		"run: sh backup/backup.sh",
+		"DATABASE_URL: ${{ secrets.DATABASE_URL }}",
+		"uses: actions/upload-artifact@v4",
+		"retention-days: 30",
+	} {
+		if !strings.Contains(wf, want) {
+			t.Errorf("backup.yml missing %q", want)
+		}
+	}
+
+	s3 := generateWorkflow(withS3(testApp()))
+	if strings.Contains(s3, "upload-artifact") {
+		t.Error("backups copied to S3 should not be uploaded as artifacts")
+	}
+	if !strings.Contains(s3, "AWS_ACCESS_KEY: ${{ secrets.AWS_ACCESS_KEY }}") {
+		t.Error("backup.yml should pass the S3 credentials")
+	}
+}
@@ -0,0 +1,37 @@
+package backup
+
+import (
+	"path/filepath"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Meta returns the generator's metadata.
+func (g Generator) Meta() codegen.PluginMeta {
+	return codegen.PluginMeta{
+		Name:        "backup",
+		Version:     "1.0.0",
+		Description: "Scheduled PostgreSQL backups and restore scripts",
+		Category:    codegen.CategoryInfra,
+	}
+}
+
+// Enabled reports whether the app backs up a PostgreSQL database.
+func (g Generator) Enabled(app *ir.Application) bool {
+	return ir.BacksUpDatabase(app)
+}
+
+// StageName returns the display name for progress reporting.
+func (g Generator) StageName() string { return "Generating database backups" }
+
+// OutputDir returns empty because backup files are written to the root output dir.
+func (g Generator) OutputDir() string { return "" }
+
+// CountFiles counts the backup scripts and the backup workflow.
+func (g Generator) CountFiles(dir string) int {
+	return codegen.CountFiles(filepath.Join(dir, "backup")) + 1 // + .github/workflows/backup.yml
+}
+
+// Inputs returns the parts of the IR the backup setup is generated from.
+func (g Generator) Inputs() []string { return []string{"name", "database", "integrations"} }
@@ -2,10 +2,12 @@ package cicd
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/codegen/hosting"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -15,32 +17,15 @@ type Generator struct{}
 // Generate writes CI/CD workflows and GitHub templates to outputDir.
 func (g Generator) Generate(app *ir.Application, outputDir string) error {
 	files := map[string]string{
-		filepath.Join(outputDir, ".github", "workflows", "ci.yml"):              generateCIWorkflow(app),
-		filepath.Join(outputDir, ".github", "workflows", "deploy.yml"):          generateDeployWorkflow(app),
-		filepath.Join(outputDir, ".github", "workflows", "security.yml"):        generateSecurityWorkflow(app),
-		filepath.Join(outputDir, ".github", "PULL_REQUEST_TEMPLATE.md"):         generatePRTemplate(app),
-		filepath.Join(outputDir, ".github", "ISSUE_TEMPLATE", "bug_report.md"):  generateBugReport(app),
+		filepath.Join(outputDir, ".github", "workflows", "ci.yml"):                  generateCIWorkflow(app),
+		filepath.Join(outputDir, ".github", "workflows", "deploy.yml"):              generateDeployWorkflow(app),
+		filepath.Join(outputDir, ".github", "workflows", "security.yml"):            generateSecurityWorkflow(app),
+		filepath.Join(outputDir, ".github", "PULL_REQUEST_TEMPLATE.md"):             generatePRTemplate(app),
+		filepath.Join(outputDir, ".github", "ISSUE_TEMPLATE", "bug_report.md"):      generateBugReport(app),
 		filepath.Join(outputDir, ".github", "ISSUE_TEMPLATE", "feature_request.md"): generateFeatureRequest(app),
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 // ── Stack Detection ──
@@ -221,16 +206,12 @@ func generateDeployWorkflow(app *ir.Application) string {
 	b.WriteString("      - uses: actions/checkout@v4\n")
 
 	target := deployTarget(app)
+	if platform := hosting.Platform(app); platform != "" {
+		writeHostingDeploySteps(&b, app, platform)
+		return b.String()
+	}
 
 	switch target {
-	case "vercel":
-		b.WriteString("      - name: Install Vercel CLI\n")
-		b.WriteString("        run: npm install -g vercel\n")
-		b.WriteString("      - name: Deploy to Vercel\n")
-		b.WriteString("        run: vercel --prod --token ${{ secrets.VERCEL_TOKEN }}\n")
-		b.WriteString("        env:\n")
-		b.WriteString("          VERCEL_TOKEN: ${{ secrets.VERCEL_TOKEN }}\n")
-
 	case "aws":
 		b.WriteString("      - name: Configure AWS credentials\n")
 		b.WriteString("        uses: aws-actions/configure-aws-credentials@v4\n")
@@ -274,6 +255,53 @@ func generateDeployWorkflow(app *ir.Application) string {
 	return b.String()
 }
 
+// writeHostingDeploySteps deploys the backend to Fly.io and the frontend
+// to Vercel, Netlify, or Fly.io, as `human deploy` does.
+func writeHostingDeploySteps(b *strings.Builder, app *ir.Application, platform string) {
+	fe := docker.FrontendDir(app)
+	if hosting.HasBackend(app) || platform == hosting.Fly {
+		b.WriteString("      - uses: superfly/flyctl-actions/setup-flyctl@master\n")
+	}
+	if hosting.HasBackend(app) {
+		b.WriteString("      - name: Deploy the API to Fly.io\n")
+		b.WriteString("        run: flyctl deploy --remote-only\n")
+		fmt.Fprintf(b, "        working-directory: %s\n", docker.BackendDir(app))
+		b.WriteString("        env:\n")
+		b.WriteString("          FLY_API_TOKEN: ${{ secrets.FLY_API_TOKEN }}\n")
+	}
+	if !hosting.HasFrontend(app) {
+		return
+	}
+
+	switch platform {
+	case hosting.Vercel:
+		b.WriteString("      - name: Install Vercel CLI\n")
+		b.WriteString("        run: npm install -g vercel\n")
+		b.WriteString("      - name: Deploy to Vercel\n")
+		b.WriteString("        run: vercel --prod --yes --token ${{ secrets.VERCEL_TOKEN }}\n")
+		fmt.Fprintf(b, "        working-directory: %s\n", fe)
+		b.WriteString("        env:\n")
+		b.WriteString("          VERCEL_TOKEN: ${{ secrets.VERCEL_TOKEN }}\n")
+		b.WriteString("          VERCEL_ORG_ID: ${{ secrets.VERCEL_ORG_ID }}\n")
+		b.WriteString("          VERCEL_PROJECT_ID: ${{ secrets.VERCEL_PROJECT_ID }}\n")
+	case hosting.Netlify:
+		b.WriteString("      - name: Install Netlify CLI\n")
+		b.WriteString("        run: npm install -g netlify-cli\n")
+		b.WriteString("      - name: Deploy to Netlify\n")
+		b.WriteString("        run: netlify deploy --build --prod\n")
+		fmt.Fprintf(b, "        working-directory: %s\n", fe)
+		b.WriteString("        env:\n")
+		b.WriteString("          NETLIFY_AUTH_TOKEN: ${{ secrets.NETLIFY_AUTH_TOKEN }}\n")
+		b.WriteString("          NETLIFY_SITE_ID: ${{ secrets.NETLIFY_SITE_ID }}\n")
+	case hosting.Fly:
+		b.WriteString("      - name: Deploy the frontend to Fly.io\n")
+		b.WriteString("        run: flyctl deploy --remote-only\n")
+		fmt.Fprintf(b, "        working-directory: %s\n", fe)
+		b.WriteString("        env:\n")
+		b.WriteString("          FLY_API_TOKEN: ${{ secrets.FLY_API_TOKEN }}\n")
+	}
+}
+
 // ── Security Workflow ──
 
 func generateSecurityWorkflow(app *ir.Application) string {
@@ -296,7 +296,7 @@ func TestDeployWorkflowDocker(t *testing.T) {
 func TestDeployWorkflowVercel(t *testing.T) {
 	app := &ir.Application{
 		Name:   "TestApp",
-		Config: &ir.BuildConfig{Deploy: "Vercel"},
+		Config: &ir.BuildConfig{Deploy: "Vercel", Frontend: "React", Backend: "Node"},
 	}
 	output := generateDeployWorkflow(app)
 
@@ -307,6 +307,9 @@ func TestDeployWorkflowVercel(t *testing.T) {
 		{"vercel install", "npm install -g vercel"},
 		{"vercel token", "${{ secrets.VERCEL_TOKEN }}"},
 		{"vercel prod", "vercel --prod"},
+		{"frontend dir", "working-directory: react"},
+		{"api on fly", "run: flyctl deploy --remote-only\n        working-directory: node"},
+		{"fly token", "${{ secrets.FLY_API_TOKEN }}"},
 	}
 	for _, c := range checks {
 		if !strings.Contains(output, c.pattern) {
@@ -315,6 +318,21 @@ func TestDeployWorkflowVercel(t *testing.T) {
 	}
 }
 
+func TestDeployWorkflowNetlifyAPIOnly(t *testing.T) {
+	app := &ir.Application{
+		Name:   "TestApp",
+		Config: &ir.BuildConfig{Deploy: "Netlify", Frontend: "none", Backend: "Python with FastAPI"},
+	}
+	output := generateDeployWorkflow(app)
+
+	if !strings.Contains(output, "working-directory: python") {
+		t.Errorf("Deploy Netlify: the API should deploy to Fly.io:\n%s", output)
+	}
+	if strings.Contains(output, "netlify") {
+		t.Errorf("Deploy Netlify: an app without a frontend has nothing for Netlify:\n%s", output)
+	}
+}
+
 func TestDeployWorkflowAWS(t *testing.T) {
 	app := &ir.Application{
 		Name:   "TestApp",
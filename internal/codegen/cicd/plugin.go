@@ -1,6 +1,8 @@
 package cicd
 
 import (
+	"path/filepath"
+
 	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/ir"
 )
@@ -23,3 +25,8 @@ func (g Generator) StageName() string { return "Generating CI/CD pipelines" }
 
 // OutputDir returns empty because CI/CD files are written to the root output dir.
 func (g Generator) OutputDir() string { return "" }
+
+// CountFiles counts the workflows under .github.
+func (g Generator) CountFiles(dir string) int {
+	return codegen.CountFiles(filepath.Join(dir, ".github"))
+}
@@ -0,0 +1,285 @@
+package diagrams
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Kinds of node in a container diagram, after the C4 model's elements.
+const (
+	kindPerson    = "person"
+	kindContainer = "container"
+	kindDatabase  = "database"
+	kindQueue     = "queue"
+	kindExternal  = "external"
+)
+
+// c4Diagram is a C4-style container diagram: the people using the app,
+// the containers inside its boundary, and the external systems it calls.
+type c4Diagram struct {
+	title string
+	nodes []c4Node
+	edges []c4Edge
+}
+
+type c4Node struct {
+	id, name, tech, kind string
+}
+
+type c4Edge struct {
+	from, to, label string
+}
+
+func (d *c4Diagram) node(id, name, tech, kind string) {
+	d.nodes = append(d.nodes, c4Node{id: id, name: name, tech: tech, kind: kind})
+}
+
+func (d *c4Diagram) edge(from, to, label string) {
+	d.edges = append(d.edges, c4Edge{from: from, to: to, label: label})
+}
+
+// containerDiagram reads the build config and architecture block into a
+// container diagram. A monolith is a frontend, a backend, and a database;
+// microservices are each drawn behind the gateway with the services they
+// talk to; serverless apps are functions behind an API gateway. Workflows
+// add a job queue and worker, and integrations are external systems.
+func containerDiagram(app *ir.Application) *c4Diagram {
+	d := &c4Diagram{title: app.Name}
+	cfg := app.Config
+	if cfg == nil {
+		cfg = &ir.BuildConfig{}
+	}
+	database := cfg.Database
+	if database == "" && app.Database != nil {
+		database = app.Database.Engine
+	}
+
+	d.node("user", "User", "", kindPerson)
+	entry := "backend"
+	if cfg.Frontend != "" {
+		d.node("frontend", "Frontend", cfg.Frontend, kindContainer)
+		d.edge("user", "frontend", "Uses")
+		entry = "frontend"
+	}
+	calls := func(to string) {
+		if entry == "frontend" {
+			d.edge("frontend", to, "Calls the API")
+		} else {
+			d.edge("user", to, "Calls the API")
+		}
+	}
+
+	style := ""
+	if app.Architecture != nil {
+		style = strings.ToLower(app.Architecture.Style)
+	}
+	var callers []string // containers that reach the database and integrations
+	switch {
+	case strings.Contains(style, "microservice") && len(app.Architecture.Services) > 0:
+		arch := app.Architecture
+		if arch.Gateway != nil {
+			d.node("gateway", "API Gateway", "nginx", kindContainer)
+			calls("gateway")
+		}
+		shared := false
+		for _, svc := range arch.Services {
+			id := "svc_" + identifier(svc.Name)
+			tech := cfg.Backend
+			if svc.Port > 0 {
+				if tech != "" {
+					tech += ", "
+				}
+				tech += fmt.Sprintf("port %d", svc.Port)
+			}
+			d.node(id, svc.Name, tech, kindContainer)
+			if arch.Gateway != nil {
+				route := "Routes requests"
+				if svc.Handles != "" {
+					route = "Routes " + svc.Handles
+				}
+				d.edge("gateway", id, route)
+			} else {
+				calls(id)
+			}
+			if svc.HasOwnDatabase {
+				dbID := id + "_db"
+				d.node(dbID, svc.Name+" database", database, kindDatabase)
+				d.edge(id, dbID, "Reads and writes")
+			} else {
+				shared = true
+				d.edge(id, "db", "Reads and writes")
+			}
+			callers = append(callers, id)
+		}
+		for _, svc := range arch.Services {
+			for _, other := range svc.TalksTo {
+				d.edge("svc_"+identifier(svc.Name), "svc_"+identifier(other), "Calls")
+			}
+		}
+		if shared {
+			d.node("db", "Database", database, kindDatabase)
+		}
+		if arch.Broker != "" {
+			d.node("broker", "Message broker", arch.Broker, kindQueue)
+			for _, svc := range arch.Services {
+				d.edge("svc_"+identifier(svc.Name), "broker", "Publishes and consumes events")
+			}
+		}
+	case strings.Contains(style, "serverless"):
+		d.node("apigw", "API Gateway", "AWS API Gateway", kindContainer)
+		d.node("functions", "Functions", fmt.Sprintf("%d Lambda functions", len(app.APIs)), kindContainer)
+		calls("apigw")
+		d.edge("apigw", "functions", "Invokes")
+		d.node("db", "Database", database, kindDatabase)
+		d.edge("functions", "db", "Reads and writes")
+		callers = []string{"functions"}
+	default:
+		d.node("backend", "Backend API", cfg.Backend, kindContainer)
+		if entry == "frontend" {
+			d.edge("frontend", "backend", "Calls the API")
+		}
+		if database != "" {
+			d.node("db", "Database", database, kindDatabase)
+			d.edge("backend", "db", "Reads and writes")
+		}
+		callers = []string{"backend"}
+	}
+
+	if ir.UsesJobQueue(app) && len(callers) > 0 {
+		d.node("queue", "Job queue", "Redis", kindQueue)
+		d.node("worker", "Worker", fmt.Sprintf("%d workflow(s)", len(app.Workflows)), kindContainer)
+		d.edge(callers[0], "queue", "Queues jobs")
+		d.edge("worker", "queue", "Runs jobs")
+		if hasNode(d, "db") {
+			d.edge("worker", "db", "Reads and writes")
+		}
+	}
+
+	for _, integ := range app.Integrations {
+		id := "ext_" + identifier(strings.ToLower(integ.Service))
+		d.node(id, integ.Service, integ.Type, kindExternal)
+		purpose := integ.Purpose
+		if purpose == "" {
+			purpose = "Uses"
+		}
+		for _, c := range callers {
+			d.edge(c, id, purpose)
+		}
+	}
+	return d
+}
+
+func hasNode(d *c4Diagram, id string) bool {
+	for _, n := range d.nodes {
+		if n.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *c4Diagram) mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	inside, outside := d.partition()
+	for _, n := range outside {
+		if n.kind == kindPerson {
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", n.id, mermaidNodeText(n))
+		}
+	}
+	fmt.Fprintf(&b, "    subgraph system[\"%s\"]\n", label(d.title))
+	for _, n := range inside {
+		fmt.Fprintf(&b, "        %s\n", mermaidNode(n))
+	}
+	b.WriteString("    end\n")
+	for _, n := range outside {
+		if n.kind != kindPerson {
+			fmt.Fprintf(&b, "    %s\n", mermaidNode(n))
+		}
+	}
+	for _, e := range d.edges {
+		fmt.Fprintf(&b, "    %s -->|\"%s\"| %s\n", e.from, label(e.label), e.to)
+	}
+	var externals []string
+	for _, n := range outside {
+		if n.kind == kindExternal {
+			externals = append(externals, n.id)
+		}
+	}
+	if len(externals) > 0 {
+		b.WriteString("    classDef external fill:#999,color:#fff,stroke:#666\n")
+		fmt.Fprintf(&b, "    class %s external\n", strings.Join(externals, ","))
+	}
+	return b.String()
+}
+
+func mermaidNode(n c4Node) string {
+	text := mermaidNodeText(n)
+	switch n.kind {
+	case kindDatabase:
+		return fmt.Sprintf("%s[(\"%s\")]", n.id, text)
+	case kindQueue:
+		return fmt.Sprintf("%s[[\"%s\"]]", n.id, text)
+	}
+	return fmt.Sprintf("%s[\"%s\"]", n.id, text)
+}
+
+// mermaidNodeText writes a node's name over its technology, as C4
+// diagrams do.
+func mermaidNodeText(n c4Node) string {
+	text := "<b>" + label(n.name) + "</b>"
+	if n.tech != "" {
+		text += "<br/>[" + label(n.tech) + "]"
+	}
+	return text
+}
+
+func (d *c4Diagram) plantUML(title string) string {
+	var b strings.Builder
+	b.WriteString("@startuml\n!include <C4/C4_Container>\n\n")
+	fmt.Fprintf(&b, "title %s containers\n\n", label(title))
+	inside, outside := d.partition()
+	for _, n := range outside {
+		if n.kind == kindPerson {
+			fmt.Fprintf(&b, "Person(%s, %q)\n", n.id, label(n.name))
+		}
+	}
+	fmt.Fprintf(&b, "System_Boundary(system, %q) {\n", label(d.title))
+	for _, n := range inside {
+		macro := "Container"
+		switch n.kind {
+		case kindDatabase:
+			macro = "ContainerDb"
+		case kindQueue:
+			macro = "ContainerQueue"
+		}
+		fmt.Fprintf(&b, "  %s(%s, %q, %q)\n", macro, n.id, label(n.name), label(n.tech))
+	}
+	b.WriteString("}\n")
+	for _, n := range outside {
+		if n.kind == kindExternal {
+			fmt.Fprintf(&b, "System_Ext(%s, %q, %q)\n", n.id, label(n.name), label(n.tech))
+		}
+	}
+	b.WriteString("\n")
+	for _, e := range d.edges {
+		fmt.Fprintf(&b, "Rel(%s, %s, %q)\n", e.from, e.to, label(e.label))
+	}
+	b.WriteString("@enduml\n")
+	return b.String()
+}
+
+// partition splits the nodes into those inside the system boundary and
+// those outside it, each in the order added; the user comes first.
+func (d *c4Diagram) partition() (inside, outside []c4Node) {
+	for _, n := range d.nodes {
+		if n.kind == kindPerson || n.kind == kindExternal {
+			outside = append(outside, n)
+		} else {
+			inside = append(inside, n)
+		}
+	}
+	return inside, outside
+}
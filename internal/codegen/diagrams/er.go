@@ -0,0 +1,144 @@
+package diagrams
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// erDiagram is the entity-relationship diagram of an app's data models.
+type erDiagram struct {
+	entities []erEntity
+	links    []erLink
+}
+
+type erEntity struct {
+	name       string
+	attributes []erAttribute
+}
+
+type erAttribute struct {
+	name     string
+	typ      string
+	key      string // PK, FK, or UK
+	required bool
+}
+
+// erLink is a relationship: one to many, or many to many through a join
+// model.
+type erLink struct {
+	from, to string
+	many     bool   // many to many
+	label    string // "has", or "through <join model>"
+}
+
+// entityDiagram reads the data models into an ER diagram. Each model
+// has an id and a foreign key per belongs-to. A relationship declared on
+// both sides, as "has many Task" and "belongs to a User" are, is drawn
+// once.
+func entityDiagram(app *ir.Application) *erDiagram {
+	d := &erDiagram{}
+	seen := map[string]bool{}
+	link := func(l erLink) {
+		key := l.from + ">" + l.to
+		if l.many && l.to < l.from {
+			key = l.to + ">" + l.from
+		}
+		if !seen[key] {
+			seen[key] = true
+			d.links = append(d.links, l)
+		}
+	}
+
+	for _, m := range app.Data {
+		e := erEntity{name: m.Name, attributes: []erAttribute{{name: "id", typ: "id", key: "PK", required: true}}}
+		for _, r := range m.Relations {
+			switch r.Kind {
+			case "belongs_to":
+				e.attributes = append(e.attributes, erAttribute{name: strings.ToLower(r.Target) + "_id", typ: "id", key: "FK", required: true})
+				link(erLink{from: r.Target, to: m.Name, label: "has"})
+			case "has_many":
+				link(erLink{from: m.Name, to: r.Target, label: "has"})
+			case "has_many_through":
+				link(erLink{from: m.Name, to: r.Target, many: true, label: "through " + r.Through})
+			}
+		}
+		for _, f := range m.Fields {
+			a := erAttribute{name: f.Name, typ: attributeType(f), required: f.Required}
+			if f.Unique {
+				a.key = "UK"
+			}
+			e.attributes = append(e.attributes, a)
+		}
+		d.entities = append(d.entities, e)
+	}
+	return d
+}
+
+// attributeType returns a field's type as one word, as ER diagrams need.
+func attributeType(f *ir.DataField) string {
+	t := identifier(strings.ToLower(f.Type))
+	if t == "" {
+		return "text"
+	}
+	return t
+}
+
+func (d *erDiagram) mermaid() string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, e := range d.entities {
+		fmt.Fprintf(&b, "    %s {\n", identifier(e.name))
+		for _, a := range e.attributes {
+			line := a.typ + " " + identifier(a.name)
+			if a.key != "" {
+				line += " " + a.key
+			}
+			fmt.Fprintf(&b, "        %s\n", line)
+		}
+		b.WriteString("    }\n")
+	}
+	for _, l := range d.links {
+		cardinality := "||--o{"
+		if l.many {
+			cardinality = "}o--o{"
+		}
+		fmt.Fprintf(&b, "    %s %s %s : %q\n", identifier(l.from), cardinality, identifier(l.to), l.label)
+	}
+	return b.String()
+}
+
+func (d *erDiagram) plantUML(title string) string {
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	fmt.Fprintf(&b, "title %s data model\n", label(title))
+	b.WriteString("hide circle\nskinparam linetype ortho\n\n")
+	for _, e := range d.entities {
+		fmt.Fprintf(&b, "entity %s {\n", identifier(e.name))
+		for i, a := range e.attributes {
+			marker := "  "
+			if a.required {
+				marker = "* "
+			}
+			line := marker + a.name + " : " + a.typ
+			if a.key != "" {
+				line += " <<" + a.key + ">>"
+			}
+			fmt.Fprintf(&b, "  %s\n", line)
+			if i == 0 {
+				b.WriteString("  --\n")
+			}
+		}
+		b.WriteString("}\n\n")
+	}
+	for _, l := range d.links {
+		cardinality := "||--o{"
+		if l.many {
+			cardinality = "}o--o{"
+		}
+		fmt.Fprintf(&b, "%s %s %s : %s\n", identifier(l.from), cardinality, identifier(l.to), l.label)
+	}
+	b.WriteString("@enduml\n")
+	return b.String()
+}
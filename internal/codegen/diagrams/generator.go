@@ -0,0 +1,73 @@
+// Package diagrams draws an application's design from its Intent IR: an
+// entity-relationship diagram of its data models, a C4-style container
+// diagram of its architecture, and a sequence diagram for each workflow.
+// Each is written in Mermaid, which GitHub renders, and PlantUML.
+package diagrams
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Generator produces Mermaid and PlantUML diagrams from Intent IR.
+type Generator struct{}
+
+// Generate writes the diagrams to outputDir, with a README.md showing the
+// Mermaid ones.
+func (g Generator) Generate(app *ir.Application, outputDir string) error {
+	files := map[string]string{}
+	var readme strings.Builder
+	fmt.Fprintf(&readme, "# %s — Diagrams\n\n", app.Name)
+	readme.WriteString("Generated by Human from the app's .human files. Each diagram is also in PlantUML, in the .puml file of the same name.\n\n")
+	add := func(name, title, mermaid, plantUML string) {
+		files[name+".mmd"] = mermaid
+		files[name+".puml"] = plantUML
+		fmt.Fprintf(&readme, "## %s\n\n```mermaid\n%s```\n\n", title, mermaid)
+	}
+
+	if len(app.Data) > 0 {
+		er := entityDiagram(app)
+		add("er", "Data Model", er.mermaid(), er.plantUML(app.Name))
+	}
+	if app.Config != nil || app.Architecture != nil {
+		c := containerDiagram(app)
+		add("architecture", "Architecture", c.mermaid(), c.plantUML(app.Name))
+	}
+	for _, wf := range app.Workflows {
+		seq := sequenceDiagram(app, wf)
+		add(filepath.ToSlash(filepath.Join("workflows", ir.JobName(wf.Trigger))), "When "+wf.Trigger, seq.mermaid(), seq.plantUML(wf.Trigger))
+	}
+	files["README.md"] = readme.String()
+
+	paths := make(map[string]string, len(files))
+	for name, content := range files {
+		paths[filepath.Join(outputDir, filepath.FromSlash(name))] = content
+	}
+	return codegen.WriteFiles(paths, codegen.WriteOptionsFor(app))
+}
+
+// label makes text safe for a diagram label: Mermaid ends a statement at a
+// semicolon and reads # as the start of an entity code.
+func label(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.NewReplacer(";", ",", "#", "", `"`, "'").Replace(text)
+}
+
+// identifier turns a name into a diagram identifier: letters, digits,
+// and underscores.
+func identifier(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,190 @@
+package diagrams
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func testApp() *ir.Application {
+	return &ir.Application{
+		Name: "TaskFlow",
+		Config: &ir.BuildConfig{
+			Frontend: "React with TypeScript",
+			Backend:  "Node with Express",
+			Database: "PostgreSQL",
+		},
+		Data: []*ir.DataModel{
+			{
+				Name:      "User",
+				Fields:    []*ir.DataField{{Name: "email", Type: "email", Required: true, Unique: true}},
+				Relations: []*ir.Relation{{Kind: "has_many", Target: "Task"}},
+			},
+			{
+				Name:   "Task",
+				Fields: []*ir.DataField{{Name: "title", Type: "text", Required: true}},
+				Relations: []*ir.Relation{
+					{Kind: "belongs_to", Target: "User"},
+					{Kind: "has_many_through", Target: "Tag", Through: "TaskTag"},
+				},
+			},
+			{
+				Name:      "Tag",
+				Fields:    []*ir.DataField{{Name: "name", Type: "text"}},
+				Relations: []*ir.Relation{{Kind: "has_many_through", Target: "Task", Through: "TaskTag"}},
+			},
+		},
+		Integrations: []*ir.Integration{
+			{Service: "SendGrid", Type: "email", Purpose: "sending emails"},
+		},
+		Workflows: []*ir.Workflow{{
+			Trigger: "a user signs up",
+			Steps: []*ir.Action{
+				{Type: "create", Text: "create their account"},
+				{Type: "send", Text: "send welcome email with template \"welcome\""},
+				{Type: "delay", Text: "after 3 days, send email with template \"tips\""},
+			},
+		}},
+	}
+}
+
+func TestEntityDiagram(t *testing.T) {
+	out := entityDiagram(testApp()).mermaid()
+	for _, want := range []string{
+		"erDiagram\n",
+		"    User {\n        id id PK\n        email email UK\n    }\n",
+		"        id user_id FK\n",
+		`    User ||--o{ Task : "has"`,
+		`    Task }o--o{ Tag : "through TaskTag"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ER diagram missing %q:\n%s", want, out)
+		}
+	}
+	// Each relationship is declared on both sides but drawn once.
+	if n := strings.Count(out, "||--o{"); n != 1 {
+		t.Errorf("expected 1 one-to-many link, got %d:\n%s", n, out)
+	}
+	if n := strings.Count(out, "}o--o{"); n != 1 {
+		t.Errorf("expected 1 many-to-many link, got %d:\n%s", n, out)
+	}
+
+	puml := entityDiagram(testApp()).plantUML("TaskFlow")
+	for _, want := range []string{"@startuml\n", "entity User {\n  * id : id <<PK>>\n  --\n", "User ||--o{ Task : has\n", "@enduml\n"} {
+		if !strings.Contains(puml, want) {
+			t.Errorf("PlantUML ER diagram missing %q:\n%s", want, puml)
+		}
+	}
+}
+
+func TestContainerDiagramMonolith(t *testing.T) {
+	out := containerDiagram(testApp()).mermaid()
+	for _, want := range []string{
+		"flowchart LR\n",
+		`    subgraph system["TaskFlow"]`,
+		`backend["<b>Backend API</b><br/>[Node with Express]"]`,
+		`db[("<b>Database</b><br/>[PostgreSQL]")]`,
+		`queue[["<b>Job queue</b><br/>[Redis]"]]`,
+		`    frontend -->|"Calls the API"| backend`,
+		`    backend -->|"sending emails"| ext_sendgrid`,
+		"    class ext_sendgrid external",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("container diagram missing %q:\n%s", want, out)
+		}
+	}
+
+	puml := containerDiagram(testApp()).plantUML("TaskFlow")
+	for _, want := range []string{
+		"!include <C4/C4_Container>",
+		`Person(user, "User")`,
+		`  ContainerDb(db, "Database", "PostgreSQL")`,
+		`System_Ext(ext_sendgrid, "SendGrid", "email")`,
+		`Rel(backend, queue, "Queues jobs")`,
+	} {
+		if !strings.Contains(puml, want) {
+			t.Errorf("PlantUML container diagram missing %q:\n%s", want, puml)
+		}
+	}
+}
+
+func TestContainerDiagramMicroservices(t *testing.T) {
+	app := testApp()
+	app.Workflows = nil
+	app.Architecture = &ir.Architecture{
+		Style: "microservices",
+		Services: []*ir.ServiceDef{
+			{Name: "UserService", Handles: "accounts", Port: 3001, HasOwnDatabase: true, TalksTo: []string{"TaskService"}},
+			{Name: "TaskService", Port: 3002},
+		},
+		Gateway: &ir.GatewayDef{Routes: map[string]string{"/api/users": "UserService"}},
+		Broker:  "RabbitMQ",
+	}
+	out := containerDiagram(app).mermaid()
+	for _, want := range []string{
+		`gateway["<b>API Gateway</b><br/>[nginx]"]`,
+		`svc_UserService["<b>UserService</b><br/>[Node with Express, port 3001]"]`,
+		`    gateway -->|"Routes accounts"| svc_UserService`,
+		`    svc_UserService -->|"Reads and writes"| svc_UserService_db`,
+		`    svc_TaskService -->|"Reads and writes"| db`,
+		`    svc_UserService -->|"Calls"| svc_TaskService`,
+		`broker[["<b>Message broker</b><br/>[RabbitMQ]"]]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("microservices diagram missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "backend") {
+		t.Errorf("microservices diagram should have no monolith backend:\n%s", out)
+	}
+}
+
+func TestSequenceDiagram(t *testing.T) {
+	app := testApp()
+	out := sequenceDiagram(app, app.Workflows[0]).mermaid()
+	want := `sequenceDiagram
+    participant App as Backend
+    participant Queue as Job queue
+    participant Worker as Worker
+    participant DB as Database
+    participant ext_sendgrid as SendGrid
+    App->>Queue: queue user-signs-up
+    Queue->>Worker: run user-signs-up
+    Worker->>DB: create their account
+    Worker->>ext_sendgrid: send welcome email with template 'welcome'
+    Worker->>Queue: schedule user-signs-up-after-3-days in 3 days
+    Queue->>Worker: run user-signs-up-after-3-days (3 days later)
+    Worker->>ext_sendgrid: send email with template 'tips'
+`
+	if out != want {
+		t.Errorf("sequence diagram:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(testApp(), dir); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, name := range []string{
+		"README.md", "er.mmd", "er.puml", "architecture.mmd", "architecture.puml",
+		"workflows/user-signs-up.mmd", "workflows/user-signs-up.puml",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("missing %s", name)
+		}
+	}
+	readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+	if n := strings.Count(string(readme), "```mermaid"); n != 3 {
+		t.Errorf("README.md has %d Mermaid diagrams, want 3:\n%s", n, readme)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	if got := label(`say "hi";  #1`); got != "say 'hi', 1" {
+		t.Errorf("label = %q", got)
+	}
+}
@@ -0,0 +1,33 @@
+package diagrams
+
+import (
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Meta returns the generator's metadata.
+func (g Generator) Meta() codegen.PluginMeta {
+	return codegen.PluginMeta{
+		Name:        "diagrams",
+		Version:     "1.0.0",
+		Description: "Mermaid and PlantUML ER, architecture, and workflow diagrams",
+		Category:    codegen.CategoryInfra,
+	}
+}
+
+// Enabled reports whether the app has anything to draw: data models, a
+// stack, or workflows.
+func (g Generator) Enabled(app *ir.Application) bool {
+	return len(app.Data) > 0 || app.Config != nil || len(app.Workflows) > 0
+}
+
+// StageName returns the display name for progress reporting.
+func (g Generator) StageName() string { return "Generating diagrams" }
+
+// OutputDir returns the subdirectory name within the build output.
+func (g Generator) OutputDir() string { return "docs" }
+
+// Inputs returns the parts of the IR the diagrams are drawn from.
+func (g Generator) Inputs() []string {
+	return []string{"name", "config", "data", "apis", "database", "integrations", "workflows", "architecture"}
+}
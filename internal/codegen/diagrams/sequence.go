@@ -0,0 +1,147 @@
+package diagrams
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// seqDiagram is the sequence diagram of a workflow run as background
+// jobs.
+type seqDiagram struct {
+	participants []seqParticipant
+	messages     []seqMessage
+}
+
+type seqParticipant struct {
+	id, name string
+}
+
+// seqMessage is an arrow from one participant to another, or, with no
+// to, a note over from.
+type seqMessage struct {
+	from, to, text string
+}
+
+func (d *seqDiagram) participant(id, name string) {
+	for _, p := range d.participants {
+		if p.id == id {
+			return
+		}
+	}
+	d.participants = append(d.participants, seqParticipant{id: id, name: name})
+}
+
+func (d *seqDiagram) send(from, to, text string) {
+	d.messages = append(d.messages, seqMessage{from: from, to: to, text: text})
+}
+
+// sequenceDiagram draws a workflow as the backend queues it: the worker
+// runs its first job, which schedules its delayed ones, and each step is
+// an arrow to what it acts on — the database, or an integration — or a
+// note when it's neither.
+func sequenceDiagram(app *ir.Application, wf *ir.Workflow) *seqDiagram {
+	d := &seqDiagram{}
+	d.participant("App", "Backend")
+	d.participant("Queue", "Job queue")
+	d.participant("Worker", "Worker")
+
+	jobs := ir.WorkflowJobs(wf)
+	d.send("App", "Queue", "queue "+jobs[0].Name)
+	d.send("Queue", "Worker", "run "+jobs[0].Name)
+	for i, job := range jobs {
+		if i > 0 {
+			d.send("Queue", "Worker", fmt.Sprintf("run %s (%s later)", job.Name, job.After))
+		}
+		for _, step := range job.Steps {
+			to, name := stepTarget(app, step)
+			if to == "" {
+				d.send("Worker", "", strings.TrimSuffix(step.Text, ","))
+				continue
+			}
+			d.participant(to, name)
+			d.send("Worker", to, step.Text)
+		}
+		if i == 0 {
+			for _, delayed := range jobs[1:] {
+				d.send("Worker", "Queue", fmt.Sprintf("schedule %s in %s", delayed.Name, delayed.After))
+			}
+		}
+	}
+	return d
+}
+
+// stepTarget returns the participant a workflow step acts on, and its
+// display name: an integration it names, or sends through by type, or the
+// database for reads and writes. Other steps, like conditions and
+// logging, return "".
+func stepTarget(app *ir.Application, step *ir.Action) (id, name string) {
+	text := strings.ToLower(step.Text)
+	for _, integ := range app.Integrations {
+		if strings.Contains(text, strings.ToLower(integ.Service)) {
+			return "ext_" + identifier(strings.ToLower(integ.Service)), integ.Service
+		}
+	}
+	switch step.Type {
+	case "send", "alert":
+		for _, integ := range app.Integrations {
+			if sendsVia(text, integ.Type) {
+				return "ext_" + identifier(strings.ToLower(integ.Service)), integ.Service
+			}
+		}
+		return "App", "Backend"
+	case "create", "update", "delete", "query", "assign":
+		return "DB", "Database"
+	}
+	return "", ""
+}
+
+// sendsVia reports whether a send step's text goes out through an
+// integration of the given type.
+func sendsVia(text, integrationType string) bool {
+	switch integrationType {
+	case "email":
+		return strings.Contains(text, "email")
+	case "sms":
+		return strings.Contains(text, "sms") || strings.Contains(text, "text message") || strings.Contains(text, "whatsapp")
+	case "messaging":
+		return strings.Contains(text, "slack") || strings.Contains(text, "message")
+	}
+	return false
+}
+
+func (d *seqDiagram) mermaid() string {
+	var b strings.Builder
+	b.WriteString("sequenceDiagram\n")
+	for _, p := range d.participants {
+		fmt.Fprintf(&b, "    participant %s as %s\n", p.id, label(p.name))
+	}
+	for _, m := range d.messages {
+		if m.to == "" {
+			fmt.Fprintf(&b, "    Note over %s: %s\n", m.from, label(m.text))
+			continue
+		}
+		fmt.Fprintf(&b, "    %s->>%s: %s\n", m.from, m.to, label(m.text))
+	}
+	return b.String()
+}
+
+func (d *seqDiagram) plantUML(trigger string) string {
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	fmt.Fprintf(&b, "title When %s\n\n", label(trigger))
+	for _, p := range d.participants {
+		fmt.Fprintf(&b, "participant %q as %s\n", label(p.name), p.id)
+	}
+	b.WriteString("\n")
+	for _, m := range d.messages {
+		if m.to == "" {
+			fmt.Fprintf(&b, "note over %s : %s\n", m.from, label(m.text))
+			continue
+		}
+		fmt.Fprintf(&b, "%s -> %s : %s\n", m.from, m.to, label(m.text))
+	}
+	b.WriteString("@enduml\n")
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// ServesHTTPS reports whether the Docker deployment puts Caddy in front of
+// the app, which happens when an environment declares a URL. Caddy runs
+// under the "https" Compose profile, so local `docker compose up` skips it.
+func ServesHTTPS(app *ir.Application) bool {
+	return ir.PublicEnvironment(app) != nil
+}
+
+// generateCaddyfile produces a Caddyfile serving $DOMAIN over HTTPS, with
+// certificates Caddy obtains and renews from Let's Encrypt. The frontend's
+// nginx already proxies /api, so everything goes to it when there is one.
+func generateCaddyfile(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("# Caddy obtains and renews the certificate for $DOMAIN from Let's Encrypt.\n")
+	b.WriteString("# The domain's DNS must point at this host, with ports 80 and 443 open.\n\n")
+
+	b.WriteString("{$DOMAIN} {\n")
+	b.WriteString("\tencode zstd gzip\n")
+	if hasFrontend(app) {
+		b.WriteString("\treverse_proxy frontend:80\n")
+	} else {
+		fmt.Fprintf(&b, "\treverse_proxy backend:%s\n", BackendPort(app))
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// writeCaddyService adds the caddy service to docker-compose.yml.
+func writeCaddyService(b *strings.Builder, app *ir.Application) {
+	upstream := "backend"
+	if hasFrontend(app) {
+		upstream = "frontend"
+	}
+	b.WriteString("  caddy:\n")
+	b.WriteString("    image: caddy:2-alpine\n")
+	b.WriteString("    profiles: [\"https\"]\n")
+	b.WriteString("    restart: unless-stopped\n")
+	b.WriteString("    ports:\n")
+	b.WriteString("      - \"80:80\"\n")
+	b.WriteString("      - \"443:443\"\n")
+	b.WriteString("      - \"443:443/udp\"\n")
+	b.WriteString("    environment:\n")
+	fmt.Fprintf(b, "      DOMAIN: ${DOMAIN:-%s}\n", ir.PublicEnvironment(app).Host())
+	b.WriteString("    volumes:\n")
+	b.WriteString("      - ./Caddyfile:/etc/caddy/Caddyfile:ro\n")
+	b.WriteString("      - caddy-data:/data\n")
+	b.WriteString("      - caddy-config:/config\n")
+	b.WriteString("    depends_on:\n")
+	fmt.Fprintf(b, "      - %s\n", upstream)
+	b.WriteString("\n")
+}
@@ -52,13 +52,25 @@ func generateNodeDockerfile(app *ir.Application) string {
 	b.WriteString("COPY --from=builder /app/package.json ./\n")
 	b.WriteString("COPY --from=builder /app/node_modules ./node_modules\n")
 	b.WriteString("COPY --from=builder /app/dist ./dist\n")
-	b.WriteString("COPY --from=builder /app/prisma ./prisma\n\n")
+	b.WriteString("COPY --from=builder /app/prisma ./prisma\n")
+	// The OpenAPI document requests are validated against
+	if ir.ValidatesRequests(app) {
+		b.WriteString("COPY --from=builder /app/openapi.yaml ./\n")
+	}
+	b.WriteString("\n")
 
 	b.WriteString("# Generate start script\n")
 	b.WriteString("RUN echo '#!/bin/sh' > start.sh && \\\n")
 	b.WriteString("    echo 'set -e' >> start.sh && \\\n")
-	b.WriteString("    echo 'echo \"Syncing database schema...\"' >> start.sh && \\\n")
-	b.WriteString("    echo 'npx prisma db push --accept-data-loss' >> start.sh && \\\n")
+	if ir.UsesPostgres(app) {
+		b.WriteString("    echo 'echo \"Running database migrations...\"' >> start.sh && \\\n")
+		b.WriteString("    echo 'npx prisma migrate deploy' >> start.sh && \\\n")
+	} else {
+		// Migrations are generated for PostgreSQL; other databases have
+		// the schema pushed.
+		b.WriteString("    echo 'echo \"Syncing database schema...\"' >> start.sh && \\\n")
+		b.WriteString("    echo 'npx prisma db push --accept-data-loss' >> start.sh && \\\n")
+	}
 	b.WriteString("    echo 'echo \"Starting application...\"' >> start.sh && \\\n")
 	b.WriteString("    echo 'node dist/server.js' >> start.sh && \\\n")
 	b.WriteString("    chmod +x start.sh\n\n")
@@ -95,7 +107,7 @@ func generatePythonDockerfile(app *ir.Application) string {
 	b.WriteString("RUN echo '#!/bin/sh' > start.sh && \\\n")
 	b.WriteString("    echo 'set -e' >> start.sh && \\\n")
 	b.WriteString("    echo 'echo \"Running database migrations...\"' >> start.sh && \\\n")
-	b.WriteString("    echo 'python -c \"from database import engine, Base; from models import *; Base.metadata.create_all(bind=engine)\"' >> start.sh && \\\n")
+	b.WriteString("    echo 'alembic upgrade head' >> start.sh && \\\n")
 	b.WriteString("    echo 'echo \"Starting application...\"' >> start.sh && \\\n")
 	b.WriteString("    echo 'exec uvicorn main:app --host 0.0.0.0 --port 8000' >> start.sh && \\\n")
 	b.WriteString("    chmod +x start.sh\n\n")
@@ -194,9 +206,12 @@ func generateViteFrontendDockerfile(app *ir.Application) string {
 	b.WriteString("    proxy_set_header Host $host;\\n\\\n")
 	b.WriteString("    proxy_set_header X-Real-IP $remote_addr;\\n\\\n")
 	b.WriteString("  }\\n\\\n")
+	writeSitemapProxy(&b, app, port)
+	writeAssetCaching(&b, app)
 	b.WriteString("  location / {\\n\\\n")
 	b.WriteString("    root /usr/share/nginx/html;\\n\\\n")
 	b.WriteString("    try_files $uri $uri/ /index.html;\\n\\\n")
+	fmt.Fprintf(&b, "    add_header Cache-Control \"%s\";\\n\\\n", ir.RevalidateCacheControl)
 	b.WriteString("  }\\n\\\n")
 	b.WriteString("}\\n' > /etc/nginx/conf.d/default.conf\n\n")
 
@@ -245,9 +260,12 @@ func generateAngularFrontendDockerfile(app *ir.Application) string {
 	b.WriteString("    proxy_set_header Host $host;\\n\\\n")
 	b.WriteString("    proxy_set_header X-Real-IP $remote_addr;\\n\\\n")
 	b.WriteString("  }\\n\\\n")
+	writeSitemapProxy(&b, app, port)
+	writeAssetCaching(&b, app)
 	b.WriteString("  location / {\\n\\\n")
 	b.WriteString("    root /usr/share/nginx/html;\\n\\\n")
 	b.WriteString("    try_files $uri $uri/ /index.html;\\n\\\n")
+	fmt.Fprintf(&b, "    add_header Cache-Control \"%s\";\\n\\\n", ir.RevalidateCacheControl)
 	b.WriteString("  }\\n\\\n")
 	b.WriteString("}\\n' > /etc/nginx/conf.d/default.conf\n\n")
 
@@ -257,6 +275,37 @@ func generateAngularFrontendDockerfile(app *ir.Application) string {
 	return b.String()
 }
 
+// writeSitemapProxy serves /sitemap.xml and /robots.txt from the backend,
+// which builds them under /api.
+func writeSitemapProxy(b *strings.Builder, app *ir.Application, port string) {
+	if app.Sitemap == nil {
+		return
+	}
+	for _, file := range []string{"sitemap.xml", "robots.txt"} {
+		fmt.Fprintf(b, "  location = /%s {\\n\\\n", file)
+		fmt.Fprintf(b, "    proxy_pass http://backend:%s/api/%s;\\n\\\n", port, file)
+		b.WriteString("    proxy_set_header Host $host;\\n\\\n")
+		b.WriteString("  }\\n\\\n")
+	}
+}
+
+// writeAssetCaching serves the build's content-hashed files with a
+// long-lived Cache-Control header. Angular's root bundles are matched by a
+// regex anchored to the root, so it can't catch /api/ paths.
+func writeAssetCaching(b *strings.Builder, app *ir.Application) {
+	for _, pattern := range ir.HashedAssetPaths(app) {
+		if ext, ok := strings.CutPrefix(pattern, "*."); ok {
+			fmt.Fprintf(b, "  location ~ ^/[^/]+\\\\.%s$ {\\n\\\n", ext)
+		} else {
+			fmt.Fprintf(b, "  location %s {\\n\\\n", strings.TrimSuffix(pattern, "*"))
+		}
+		b.WriteString("    root /usr/share/nginx/html;\\n\\\n")
+		fmt.Fprintf(b, "    add_header Cache-Control \"%s\";\\n\\\n", ir.ImmutableCacheControl)
+		b.WriteString("    try_files $uri =404;\\n\\\n")
+		b.WriteString("  }\\n\\\n")
+	}
+}
+
 // generateBackendDockerignore produces a .dockerignore for the backend directory.
 func generateBackendDockerignore(app *ir.Application) string {
 	dir := BackendDir(app)
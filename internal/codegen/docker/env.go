@@ -67,7 +67,9 @@ func envCategory(v EnvVar) string {
 		return "Database"
 	case strings.Contains(name, "JWT"):
 		return "Authentication"
-	case strings.Contains(name, "PORT"):
+	case strings.Contains(name, "FIELD_ENCRYPTION"):
+		return "Field Encryption"
+	case strings.Contains(name, "PORT") || name == "DOMAIN":
 		return "Server"
 	case strings.Contains(name, "VITE") || strings.Contains(name, "NG_APP"):
 		return "Frontend"
@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// EnvKind is the shape a backend expects an env var's value to have.
+type EnvKind string
+
+const (
+	EnvText EnvKind = "text"
+	EnvURL  EnvKind = "url"  // absolute, with a scheme and a host
+	EnvPort EnvKind = "port" // 1–65535
+	EnvKey  EnvKind = "key"  // 32 bytes, base64
+)
+
+// EnvRule is how a generated backend checks one env var at startup, before
+// anything reads it, so a missing or malformed value stops the server with
+// a clear message instead of a driver error later on.
+type EnvRule struct {
+	Name    string
+	Kind    EnvKind
+	Comment string
+	// Required vars must be set in every environment; vars
+	// RequiredInProduction are left blank in the local .env.
+	Required             bool
+	RequiredInProduction bool
+	// DevValues are refused in production: the development defaults the
+	// generated .env and the backends' fallbacks use.
+	DevValues []string
+}
+
+// ProductionEnv returns the env var whose value is "production" when the
+// backend runs in production, as set by the Terraform deployments.
+func ProductionEnv(app *ir.Application) string {
+	if BackendDir(app) == "node" {
+		return "NODE_ENV"
+	}
+	return "APP_ENV"
+}
+
+// EnvRules returns the checks for the env vars the backend reads, in the
+// order of CollectEnvVars. The frontend's API URL and Caddy's DOMAIN are
+// left out: the backend never reads them.
+func EnvRules(app *ir.Application) []EnvRule {
+	var rules []EnvRule
+	for _, v := range CollectEnvVars(app) {
+		if v.Name == "DOMAIN" || (hasFrontend(app) && v.Name == FrontendAPIEnvName(app)) {
+			continue
+		}
+		r := EnvRule{Name: v.Name, Kind: EnvText, Comment: v.Comment}
+		switch v.Name {
+		case "DATABASE_URL":
+			r.Kind, r.Required = EnvURL, true
+		case "PORT":
+			r.Kind = EnvPort
+		case "JWT_SECRET":
+			r.RequiredInProduction = true
+			r.DevValues = []string{v.Example, "change-me", "supersecretkey"}
+		case "FIELD_ENCRYPTION_KEY":
+			r.Kind, r.Required = EnvKey, true
+			r.DevValues = []string{devFieldEncryptionKey}
+		case "FIELD_ENCRYPTION_OLD_KEYS":
+		default:
+			// Integration credentials have no example value, so the local
+			// .env leaves them blank; production must fill them in.
+			if strings.HasSuffix(v.Name, "_URL") {
+				r.Kind = EnvURL
+			}
+			r.RequiredInProduction = v.Example == ""
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
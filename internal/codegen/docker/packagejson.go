@@ -30,10 +30,16 @@ func generatePackageJSON(app *ir.Application) string {
 		fmt.Fprintf(&b, "    \"db:migrate\": \"cd %s && alembic upgrade head\",\n", backendDir)
 		fmt.Fprintf(&b, "    \"db:seed\": \"cd %s && python seed.py\"\n", backendDir)
 	case "go":
-		fmt.Fprintf(&b, "    \"db:migrate\": \"cd %s && go run ./migrations\",\n", backendDir)
-		fmt.Fprintf(&b, "    \"db:seed\": \"cd %s && go run ./seed\"\n", backendDir)
+		fmt.Fprintf(&b, "    \"db:migrate\": \"cd %s && go run ./cmd/migrate\",\n", backendDir)
+		fmt.Fprintf(&b, "    \"db:seed\": \"cd %s && go run ./cmd/seed\"\n", backendDir)
 	default:
-		fmt.Fprintf(&b, "    \"db:migrate\": \"cd %s && npx prisma migrate deploy\",\n", backendDir)
+		// Migrations are generated for PostgreSQL; other databases have
+		// the schema pushed.
+		if !ir.UsesPostgres(app) {
+			fmt.Fprintf(&b, "    \"db:migrate\": \"cd %s && npx prisma db push\",\n", backendDir)
+		} else {
+			fmt.Fprintf(&b, "    \"db:migrate\": \"cd %s && npx prisma migrate deploy\",\n", backendDir)
+		}
 		fmt.Fprintf(&b, "    \"db:seed\": \"cd %s && npx prisma db seed\",\n", backendDir)
 		fmt.Fprintf(&b, "    \"db:studio\": \"cd %s && npx prisma studio\"\n", backendDir)
 	}
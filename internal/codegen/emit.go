@@ -0,0 +1,136 @@
+package codegen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// DefaultEmitWorkers is how many files WriteFiles writes at once unless
+// told otherwise. On a network filesystem or a CI runner's disk each write
+// waits mostly on latency, so overlapping them is what speeds a large
+// app's build up.
+const DefaultEmitWorkers = 16
+
+// WriteOptions sets how WriteFiles writes. The zero value writes
+// DefaultEmitWorkers files at once without syncing them.
+type WriteOptions struct {
+	Workers int  // files written at once; DefaultEmitWorkers when 0
+	Sync    bool // flush each file to disk before returning
+}
+
+// WriteOptionsFor returns the WriteOptions of the build generating app's
+// code: its worker count, and whether to sync the files, for output that
+// must survive a crash of the machine writing it.
+func WriteOptionsFor(app *ir.Application) WriteOptions {
+	if app == nil || app.Build == nil {
+		return WriteOptions{}
+	}
+	return WriteOptions{Workers: app.Build.EmitWorkers, Sync: app.Build.Fsync}
+}
+
+// WriteFiles writes generated files, keyed by path. Their directories are
+// created first, each once, then the files are written by a pool of
+// opts.Workers workers. With opts.Sync, the workers sync the files they
+// write, so the filesystem commits the syncs together rather than one
+// after another, and each directory is synced once, after all its files.
+// When writes fail, the error for the first failing path is returned.
+func WriteFiles(files map[string]string, opts WriteOptions) error {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultEmitWorkers
+	}
+	paths := make([]string, 0, len(files))
+	dirs := map[string]bool{}
+	for path := range files {
+		paths = append(paths, path)
+		dirs[filepath.Dir(path)] = true
+	}
+	sort.Strings(paths)
+
+	dirList := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		dirList = append(dirList, dir)
+	}
+	sort.Strings(dirList)
+	for _, dir := range dirList {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating directory %s: %w", dir, err)
+		}
+	}
+
+	errs := make([]error, len(paths))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(paths)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = emitFile(paths[i], files[paths[i]], opts.Sync)
+			}
+		}()
+	}
+	for i := range paths {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	if opts.Sync {
+		for _, dir := range dirList {
+			if err := syncDir(dir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// emitFile writes one file into its existing directory, syncing it if
+// sync is set.
+func emitFile(path, content string, sync bool) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	_, err = f.WriteString(content)
+	if err == nil && sync {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// syncDir flushes a directory's entries, so the files created in it are
+// found after a crash. Windows can't sync a directory, and doesn't need
+// to.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err == nil {
+		err = d.Sync()
+		d.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("syncing %s: %w", dir, err)
+	}
+	return nil
+}
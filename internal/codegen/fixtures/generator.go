@@ -0,0 +1,202 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/fixtures"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Generator writes the app's canonical sample data as one JSON file per
+// model, plus TypeScript and Python loaders. Backend tests, frontend tests,
+// the database seed, and `human mock` all read the same records.
+type Generator struct{}
+
+// Generate writes fixture files to outputDir.
+func (g Generator) Generate(app *ir.Application, outputDir string) error {
+	set := fixtures.Seeded(app)
+
+	files := map[string]string{
+		filepath.Join(outputDir, "index.ts"):    generateIndexTS(set),
+		filepath.Join(outputDir, "README.md"):   generateReadme(set),
+		filepath.Join(outputDir, "fixtures.py"): generatePythonLoader(set),
+	}
+	for _, model := range set.Models {
+		content, err := generateModelJSON(model, set.Records[model.Name])
+		if err != nil {
+			return fmt.Errorf("encoding %s fixtures: %w", model.Name, err)
+		}
+		files[filepath.Join(outputDir, fileName(model.Name))] = content
+	}
+
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
+}
+
+// generateModelJSON encodes records as a JSON array. Keys follow the model
+// declaration (id, fields, foreign keys) rather than alphabetical order so
+// the files read like the .human source.
+func generateModelJSON(model *ir.DataModel, records []map[string]any) (string, error) {
+	keys := []string{"id"}
+	for _, f := range model.Fields {
+		keys = append(keys, f.Name)
+	}
+	for _, rel := range model.Relations {
+		if rel.Kind == "belongs_to" {
+			keys = append(keys, fixtures.ForeignKey(rel.Target))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("[\n")
+	for i, rec := range records {
+		b.WriteString("  {\n")
+		var props []string
+		for _, k := range keys {
+			v, ok := rec[k]
+			if !ok {
+				continue
+			}
+			key, _ := json.Marshal(k)
+			val, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			props = append(props, fmt.Sprintf("    %s: %s", key, val))
+		}
+		b.WriteString(strings.Join(props, ",\n"))
+		b.WriteString("\n  }")
+		if i < len(records)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+
+	return b.String(), nil
+}
+
+// generateIndexTS produces index.ts, re-exporting every model's records.
+// The fixtures map is keyed by camelCase model name, which is also the
+// Prisma delegate name, so generated backend tests can index it directly.
+func generateIndexTS(set *fixtures.Set) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n")
+	b.WriteString("// Canonical sample data shared by backend tests, frontend tests,\n")
+	b.WriteString("// the database seed, and `human mock`.\n\n")
+
+	for _, model := range set.Models {
+		fmt.Fprintf(&b, "import %sData from './%s';\n", pluralVar(model.Name), fileName(model.Name))
+	}
+	b.WriteString("\n")
+
+	for _, model := range set.Models {
+		fmt.Fprintf(&b, "export const %s = %sData;\n", pluralVar(model.Name), pluralVar(model.Name))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("export const fixtures: Record<string, Array<{ id: string } & Record<string, unknown>>> = {\n")
+	for _, model := range set.Models {
+		fmt.Fprintf(&b, "  %s: %s,\n", lowerFirst(model.Name), pluralVar(model.Name))
+	}
+	b.WriteString("};\n\n")
+
+	b.WriteString("/** Returns the fixture record of model with the given id, if any. */\n")
+	b.WriteString("export function fixtureById(model: string, id: string) {\n")
+	b.WriteString("  return fixtures[model]?.find((record) => record.id === id);\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// generatePythonLoader produces fixtures.py for pytest suites and seed
+// scripts in the Python backend.
+func generatePythonLoader(set *fixtures.Set) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("# Canonical sample data shared by backend tests, frontend tests,\n")
+	b.WriteString("# the database seed, and `human mock`.\n\n")
+	b.WriteString("import json\n")
+	b.WriteString("from pathlib import Path\n\n")
+	b.WriteString("_DIR = Path(__file__).parent\n\n")
+	b.WriteString("FILES = {\n")
+	for _, model := range set.Models {
+		fmt.Fprintf(&b, "    %q: %q,\n", model.Name, fileName(model.Name))
+	}
+	b.WriteString("}\n\n\n")
+	b.WriteString("def load(model: str) -> list[dict]:\n")
+	b.WriteString("    \"\"\"Return the fixture records for a model, e.g. load(\"User\").\"\"\"\n")
+	b.WriteString("    with open(_DIR / FILES[model], encoding=\"utf-8\") as f:\n")
+	b.WriteString("        return json.load(f)\n\n\n")
+	b.WriteString("def load_all() -> dict[str, list[dict]]:\n")
+	b.WriteString("    \"\"\"Return every model's records, in foreign-key-safe insert order.\"\"\"\n")
+	b.WriteString("    return {model: load(model) for model in FILES}\n")
+
+	return b.String()
+}
+
+// generateReadme documents the fixture files and their consumers.
+func generateReadme(set *fixtures.Set) string {
+	var b strings.Builder
+
+	b.WriteString("# Fixtures\n\n")
+	b.WriteString("Canonical sample data generated from the data models. Every layer uses the same records:\n\n")
+	b.WriteString("- **Backend tests** read them through `index.ts` (Node) or `fixtures.py` (Python)\n")
+	b.WriteString("- **Frontend tests** import `index.ts`\n")
+	b.WriteString("- **Database seed** (`postgres/seed.sql`, and `human seed`'s backend script) inserts the same rows\n")
+	b.WriteString("- **`human mock`** serves them with its default `--seed` and `--records`\n\n")
+	b.WriteString("Ids are stable across builds and every foreign key points at an existing record. ")
+	fmt.Fprintf(&b, "Models get %d records each unless a `seed` line asks for another count.\n\n", fixtures.DefaultCount)
+	b.WriteString("| Model | File | Records |\n")
+	b.WriteString("|-------|------|---------|\n")
+	for _, model := range set.Models {
+		fmt.Fprintf(&b, "| %s | `%s` | %d |\n", model.Name, fileName(model.Name), len(set.Records[model.Name]))
+	}
+
+	return b.String()
+}
+
+// fileName returns the JSON file name for a model: "TaskTag" → "task-tags.json".
+func fileName(model string) string {
+	return pluralize(toKebabCase(model)) + ".json"
+}
+
+// pluralVar returns the exported variable name for a model's records:
+// "TaskTag" → "taskTags".
+func pluralVar(model string) string {
+	return pluralize(lowerFirst(model))
+}
+
+func pluralize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsRune("aeiou", rune(s[len(s)-2])):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	}
+	return s + "s"
+}
+
+func toKebabCase(s string) string {
+	var result []rune
+	for i, r := range s {
+		if unicode.IsUpper(r) && i > 0 {
+			result = append(result, '-')
+		}
+		result = append(result, unicode.ToLower(r))
+	}
+	return string(result)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,113 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func testApp() *ir.Application {
+	return &ir.Application{
+		Name: "TaskFlow",
+		Data: []*ir.DataModel{
+			{
+				Name: "Task",
+				Fields: []*ir.DataField{
+					{Name: "title", Type: "text", Required: true},
+					{Name: "status", Type: "enum", EnumValues: []string{"pending", "done"}},
+				},
+				Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}},
+			},
+			{
+				Name: "User",
+				Fields: []*ir.DataField{
+					{Name: "name", Type: "text"},
+					{Name: "email", Type: "email"},
+				},
+			},
+			{
+				Name:   "Category",
+				Fields: []*ir.DataField{{Name: "name", Type: "text"}},
+			},
+		},
+	}
+}
+
+func TestGenerateWritesFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(testApp(), dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"index.ts", "fixtures.py", "README.md", "tasks.json", "users.json", "categories.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("missing %s", name)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tasks []map[string]any
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		t.Fatalf("tasks.json is not valid JSON: %v", err)
+	}
+	if len(tasks) == 0 || tasks[0]["userId"] == nil {
+		t.Errorf("tasks should carry userId foreign keys: %v", tasks)
+	}
+}
+
+func TestModelJSONKeyOrder(t *testing.T) {
+	app := testApp()
+	out, err := generateModelJSON(app.Data[0], []map[string]any{
+		{"id": "1", "userId": "2", "status": "done", "title": "Ship it"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := strings.Index(out, `"id"`)
+	title := strings.Index(out, `"title"`)
+	status := strings.Index(out, `"status"`)
+	user := strings.Index(out, `"userId"`)
+	if !(id < title && title < status && status < user) {
+		t.Errorf("keys not in declaration order:\n%s", out)
+	}
+}
+
+func TestIndexTS(t *testing.T) {
+	g := Generator{}
+	dir := t.TempDir()
+	if err := g.Generate(testApp(), dir); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "index.ts"))
+	out := string(data)
+
+	// Users are imported before tasks because tasks reference them.
+	if strings.Index(out, "import usersData") > strings.Index(out, "import tasksData") {
+		t.Error("index.ts should list models in dependency order")
+	}
+	for _, want := range []string{
+		"import categoriesData from './categories.json';",
+		"export const tasks = tasksData;",
+		"  task: tasks,",
+		"export function fixtureById(",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("index.ts missing %q", want)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	if (Generator{}).Enabled(&ir.Application{}) {
+		t.Error("should be disabled without data models")
+	}
+	if !(Generator{}).Enabled(testApp()) {
+		t.Error("should be enabled with data models")
+	}
+}
@@ -0,0 +1,30 @@
+package fixtures
+
+import (
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Meta returns the generator's metadata.
+func (g Generator) Meta() codegen.PluginMeta {
+	return codegen.PluginMeta{
+		Name:        "fixtures",
+		Version:     "1.0.0",
+		Description: "Canonical sample data shared by tests, seeds, and the mock server",
+		Category:    codegen.CategoryDatabase,
+	}
+}
+
+// Enabled reports whether the app declares any data models.
+func (g Generator) Enabled(app *ir.Application) bool {
+	return len(app.Data) > 0
+}
+
+// StageName returns the display name for progress reporting.
+func (g Generator) StageName() string { return "Generating sample data fixtures" }
+
+// OutputDir returns the subdirectory name within the build output.
+func (g Generator) OutputDir() string { return "fixtures" }
+
+// Inputs returns the parts of the IR the fixtures are generated from.
+func (g Generator) Inputs() []string { return []string{"data", "seeds"} }
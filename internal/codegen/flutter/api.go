@@ -0,0 +1,251 @@
+package flutter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateAPIClient produces lib/api/client.dart: an ApiClient with a
+// typed method for each endpoint, and the `api` instance screens call.
+// Failed requests throw an ApiException carrying the status and the
+// server's error message.
+func generateAPIClient(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import 'dart:convert';\n\n")
+	b.WriteString("import 'package:http/http.dart' as http;\n")
+	models := clientModels(app)
+	if len(models) > 0 {
+		b.WriteString("\n")
+	}
+	for _, m := range models {
+		fmt.Fprintf(&b, "import '../models/%s.dart';\n", toSnakeCase(m))
+	}
+
+	b.WriteString("\n// The API's origin: pass --dart-define=API_URL=... to call another, e.g.\n")
+	b.WriteString("// http://10.0.2.2:<port> from the Android emulator.\n")
+	fmt.Fprintf(&b, "const apiUrl = String.fromEnvironment('API_URL', defaultValue: '%s');\n\n", defaultAPIURL(app))
+	b.WriteString("final api = ApiClient(apiUrl);\n")
+
+	b.WriteString(`
+class ApiException implements Exception {
+  final int status;
+  final String message;
+
+  const ApiException(this.status, this.message);
+
+  @override
+  String toString() => message;
+}
+
+class ApiClient {
+  final String baseUrl;
+  final http.Client _http;
+
+  /// The signed-in user's token, sent with every request.
+  String? token;
+
+  ApiClient(this.baseUrl, {http.Client? client}) : _http = client ?? http.Client();
+
+  Future<dynamic> _request(String method, String path, {Map<String, dynamic>? body, Map<String, String>? query}) async {
+    final request = http.Request(method, Uri.parse('$baseUrl$path').replace(queryParameters: query));
+    request.headers['Content-Type'] = 'application/json';
+    if (token != null) {
+      request.headers['Authorization'] = 'Bearer $token';
+    }
+    if (body != null) {
+      request.body = jsonEncode(body);
+    }
+    final res = await http.Response.fromStream(await _http.send(request));
+    final json = res.body.isEmpty ? <String, dynamic>{} : jsonDecode(res.body) as Map<String, dynamic>;
+    if (res.statusCode >= 400) {
+      throw ApiException(res.statusCode, json['error'] as String? ?? res.reasonPhrase ?? 'Request failed');
+    }
+    return json['data'];
+  }
+`)
+
+	for _, ep := range app.APIs {
+		b.WriteString("\n")
+		writeEndpointMethod(&b, ep, app)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// writeEndpointMethod writes the client method calling an endpoint. Its
+// inputs are named arguments, sent in the query string of a GET and the
+// body of anything else; paginated lists take the cursor of the page
+// before.
+func writeEndpointMethod(b *strings.Builder, ep *ir.Endpoint, app *ir.Application) {
+	method := httpMethod(ep.Name)
+	model, list := responseModel(ep, app)
+
+	var args []string
+	for _, p := range ep.Params {
+		args = append(args, "required String "+toCamelCase(p.Name))
+	}
+	paged := method == "GET" && ep.PageSize > 0
+	if paged {
+		args = append(args, "String? cursor")
+	}
+	sig := ""
+	if len(args) > 0 {
+		sig = "{" + strings.Join(args, ", ") + "}"
+	}
+
+	var returns string
+	switch {
+	case method == "DELETE":
+		returns = "void"
+	case model != "" && list:
+		returns = "List<" + model + ">"
+	case model != "":
+		returns = model
+	default:
+		returns = "dynamic"
+	}
+
+	var opts []string
+	if len(ep.Params) > 0 || paged {
+		var pairs []string
+		for _, p := range ep.Params {
+			pairs = append(pairs, fmt.Sprintf("'%s': %s", p.Name, toCamelCase(p.Name)))
+		}
+		if paged {
+			pairs = append(pairs, "if (cursor != null) 'cursor': cursor")
+		}
+		if method == "GET" {
+			opts = append(opts, "query: {"+strings.Join(pairs, ", ")+"}")
+		} else {
+			opts = append(opts, "body: {"+strings.Join(pairs, ", ")+"}")
+		}
+	}
+	call := fmt.Sprintf("_request('%s', '%s'", method, apiPath(ep.Name))
+	if len(opts) > 0 {
+		call += ", " + strings.Join(opts, ", ")
+	}
+	call += ")"
+
+	fmt.Fprintf(b, "  Future<%s> %s(%s) async {\n", returns, toCamelCase(ep.Name), sig)
+	switch returns {
+	case "void":
+		fmt.Fprintf(b, "    await %s;\n", call)
+	case "dynamic":
+		fmt.Fprintf(b, "    return %s;\n", call)
+	default:
+		fmt.Fprintf(b, "    final data = await %s;\n", call)
+		if list {
+			fmt.Fprintf(b, "    return [for (final e in data as List) %s.fromJson(e as Map<String, dynamic>)];\n", model)
+		} else {
+			fmt.Fprintf(b, "    return %s.fromJson(data as Map<String, dynamic>);\n", model)
+		}
+	}
+	b.WriteString("  }\n")
+}
+
+// responseModel returns the data model an endpoint responds with, inferred
+// from its name — CreateTask → Task, ListTasks → a list of Task — and
+// whether it responds with a list of them, or "" when it names none.
+func responseModel(ep *ir.Endpoint, app *ir.Application) (model string, list bool) {
+	if ep.Aggregate != nil || ep.Account != "" {
+		return "", false
+	}
+	lower := strings.ToLower(ep.Name)
+	for _, prefix := range []string{"create", "update", "get", "list", "fetch", "search"} {
+		if !strings.HasPrefix(lower, prefix) || len(ep.Name) == len(prefix) {
+			continue
+		}
+		name := ep.Name[len(prefix):]
+		for _, m := range app.Data {
+			if name == m.Name {
+				return m.Name, prefix == "list" || prefix == "search"
+			}
+			if name == m.Name+"s" || name == pluralize(m.Name) {
+				return m.Name, true
+			}
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// clientModels returns the models the API client reads, sorted.
+func clientModels(app *ir.Application) []string {
+	seen := map[string]bool{}
+	var models []string
+	for _, ep := range app.APIs {
+		if httpMethod(ep.Name) == "DELETE" {
+			continue
+		}
+		if m, _ := responseModel(ep, app); m != "" && !seen[m] {
+			seen[m] = true
+			models = append(models, m)
+		}
+	}
+	sort.Strings(models)
+	return models
+}
+
+// defaultAPIURL returns the API the app calls without --dart-define: the
+// API of the environment the build targets, else the local backend.
+func defaultAPIURL(app *ir.Application) string {
+	if env := ir.TargetEnvironment(app); env != nil {
+		return env.APIURL(app)
+	}
+	port := 3001
+	if app.Config != nil && app.Config.Ports.Backend > 0 {
+		port = app.Config.Ports.Backend
+	}
+	return fmt.Sprintf("http://localhost:%d", port)
+}
+
+// httpMethod infers the HTTP method from an API endpoint name.
+func httpMethod(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "get"),
+		strings.HasPrefix(lower, "list"),
+		strings.HasPrefix(lower, "search"),
+		strings.HasPrefix(lower, "fetch"):
+		return "GET"
+	case strings.HasPrefix(lower, "delete"):
+		return "DELETE"
+	case strings.HasPrefix(lower, "update"):
+		return "PUT"
+	default:
+		return "POST"
+	}
+}
+
+// apiPath infers the REST path from an API endpoint name.
+// "GetTasks" → "/api/tasks", "SignUp" → "/api/sign-up"
+func apiPath(name string) string {
+	stripped := name
+	for _, prefix := range []string{"Get", "List", "Search", "Fetch", "Create", "Update", "Delete"} {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			stripped = name[len(prefix):]
+			break
+		}
+	}
+	return "/api/" + toKebabCase(stripped)
+}
+
+// pluralize returns a naive English plural of the given word.
+func pluralize(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case s == "":
+		return s
+	case strings.HasSuffix(lower, "s") || strings.HasSuffix(lower, "sh") || strings.HasSuffix(lower, "ch") || strings.HasSuffix(lower, "x") || strings.HasSuffix(lower, "z"):
+		return s + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return s[:len(s)-1] + "ies"
+	}
+	return s + "s"
+}
@@ -0,0 +1,295 @@
+package flutter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Generator produces a Flutter mobile app from Intent IR: a screen for
+// each page, a widget for each component, a Dart class for each data
+// model, and a typed client for the API.
+type Generator struct{}
+
+// Generate writes a complete Flutter project to outputDir.
+func (g Generator) Generate(app *ir.Application, outputDir string) error {
+	files := map[string]string{
+		filepath.Join(outputDir, "pubspec.yaml"):                      generatePubspec(app),
+		filepath.Join(outputDir, "analysis_options.yaml"):             generateAnalysisOptions(),
+		filepath.Join(outputDir, "lib", "main.dart"):                  generateMain(app),
+		filepath.Join(outputDir, "lib", "api", "client.dart"):         generateAPIClient(app),
+		filepath.Join(outputDir, "lib", "widgets", "app_drawer.dart"): generateDrawer(app),
+	}
+
+	for _, model := range app.Data {
+		files[filepath.Join(outputDir, "lib", "models", toSnakeCase(model.Name)+".dart")] = generateModel(model)
+	}
+	for _, page := range app.Pages {
+		files[filepath.Join(outputDir, "lib", "screens", toSnakeCase(page.Name)+"_screen.dart")] = generateScreen(page, app)
+	}
+	for _, comp := range app.Components {
+		files[filepath.Join(outputDir, "lib", "widgets", toSnakeCase(comp.Name)+".dart")] = generateWidget(comp, app)
+	}
+
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
+}
+
+// generatePubspec produces pubspec.yaml.
+func generatePubspec(app *ir.Application) string {
+	return fmt.Sprintf(`# Generated by Human compiler — do not edit
+name: %s
+description: %s mobile app
+publish_to: none
+version: 1.0.0+1
+
+environment:
+  sdk: ">=3.3.0 <4.0.0"
+
+dependencies:
+  flutter:
+    sdk: flutter
+  http: ^1.2.0
+
+dev_dependencies:
+  flutter_test:
+    sdk: flutter
+  flutter_lints: ^4.0.0
+
+flutter:
+  uses-material-design: true
+`, packageName(app), appTitle(app))
+}
+
+// generateAnalysisOptions produces analysis_options.yaml with Flutter's
+// recommended lints.
+func generateAnalysisOptions() string {
+	return "# Generated by Human compiler — do not edit\ninclude: package:flutter_lints/flutter.yaml\n"
+}
+
+// generateMain produces lib/main.dart: a MaterialApp themed with the
+// app's primary color, routing each page's path to its screen.
+func generateMain(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import 'package:flutter/material.dart';\n\n")
+	for _, page := range app.Pages {
+		fmt.Fprintf(&b, "import 'screens/%s_screen.dart';\n", toSnakeCase(page.Name))
+	}
+	if len(app.Pages) > 0 {
+		b.WriteString("\n")
+	}
+
+	class := toPascalCase(appTitle(app)) + "App"
+	fmt.Fprintf(&b, "void main() => runApp(const %s());\n\n", class)
+	fmt.Fprintf(&b, "class %s extends StatelessWidget {\n", class)
+	fmt.Fprintf(&b, "  const %s({super.key});\n\n", class)
+	b.WriteString("  @override\n")
+	b.WriteString("  Widget build(BuildContext context) {\n")
+	b.WriteString("    return MaterialApp(\n")
+	fmt.Fprintf(&b, "      title: '%s',\n", dartString(appTitle(app)))
+	fmt.Fprintf(&b, "      theme: ThemeData(colorSchemeSeed: const Color(%s), useMaterial3: true),\n", seedColor(app))
+	if app.Theme != nil && app.Theme.DarkMode {
+		fmt.Fprintf(&b, "      darkTheme: ThemeData(colorSchemeSeed: const Color(%s), brightness: Brightness.dark, useMaterial3: true),\n", seedColor(app))
+	}
+	if len(app.Pages) > 0 {
+		b.WriteString("      initialRoute: '/',\n")
+		b.WriteString("      routes: {\n")
+		for _, page := range app.Pages {
+			fmt.Fprintf(&b, "        '%s': (context) => const %sScreen(),\n", routePath(app, page.Name), toPascalCase(page.Name))
+		}
+		b.WriteString("      },\n")
+	} else {
+		fmt.Fprintf(&b, "      home: const Scaffold(body: Center(child: Text('%s'))),\n", dartString(appTitle(app)))
+	}
+	b.WriteString("    );\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// generateDrawer produces the navigation drawer listing the app's pages,
+// shown by every screen when there is more than one.
+func generateDrawer(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import 'package:flutter/material.dart';\n\n")
+	b.WriteString("class AppDrawer extends StatelessWidget {\n")
+	b.WriteString("  const AppDrawer({super.key});\n\n")
+	b.WriteString("  @override\n")
+	b.WriteString("  Widget build(BuildContext context) {\n")
+	b.WriteString("    final current = ModalRoute.of(context)?.settings.name;\n")
+	b.WriteString("    return Drawer(\n")
+	b.WriteString("      child: ListView(\n")
+	b.WriteString("        children: [\n")
+	fmt.Fprintf(&b, "          DrawerHeader(child: Text('%s', style: Theme.of(context).textTheme.titleLarge)),\n", dartString(appTitle(app)))
+	for _, page := range app.Pages {
+		path := routePath(app, page.Name)
+		b.WriteString("          ListTile(\n")
+		fmt.Fprintf(&b, "            title: const Text('%s'),\n", dartString(humanize(page.Name)))
+		fmt.Fprintf(&b, "            selected: current == '%s',\n", path)
+		fmt.Fprintf(&b, "            onTap: () => Navigator.pushReplacementNamed(context, '%s'),\n", path)
+		b.WriteString("          ),\n")
+	}
+	b.WriteString("        ],\n")
+	b.WriteString("      ),\n")
+	b.WriteString("    );\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// seedColor returns the Dart color literal the app's color scheme is
+// derived from: the theme's primary color, else indigo.
+func seedColor(app *ir.Application) string {
+	if app.Theme != nil {
+		c := strings.TrimPrefix(strings.TrimSpace(app.Theme.Colors["primary"]), "#")
+		if len(c) == 3 {
+			c = string([]byte{c[0], c[0], c[1], c[1], c[2], c[2]})
+		}
+		if len(c) == 6 && isHex(c) {
+			return "0xFF" + strings.ToUpper(c)
+		}
+	}
+	return "0xFF4F46E5"
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !unicode.Is(unicode.ASCII_Hex_Digit, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// routePath returns a page's named route: "/" for the home page (the one
+// named Home or Index, else the first), else "/" and its name in
+// kebab-case.
+func routePath(app *ir.Application, page string) string {
+	if page == homePage(app) {
+		return "/"
+	}
+	return "/" + toKebabCase(page)
+}
+
+func homePage(app *ir.Application) string {
+	for _, page := range app.Pages {
+		if lower := strings.ToLower(page.Name); lower == "home" || lower == "index" {
+			return page.Name
+		}
+	}
+	if len(app.Pages) > 0 {
+		return app.Pages[0].Name
+	}
+	return ""
+}
+
+func appTitle(app *ir.Application) string {
+	if app.Name != "" {
+		return app.Name
+	}
+	return "App"
+}
+
+// packageName returns the pubspec package name: the app's name in
+// snake_case.
+func packageName(app *ir.Application) string {
+	name := toSnakeCase(strings.ReplaceAll(appTitle(app), " ", ""))
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "app_" + name
+	}
+	return name
+}
+
+// dartString escapes s for a single-quoted Dart string.
+func dartString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "'", `\'`)
+	return strings.ReplaceAll(s, "$", `\$`)
+}
+
+// humanize splits a PascalCase name into words: "TaskDetail" → "Task Detail".
+func humanize(s string) string {
+	var out []rune
+	for i, r := range s {
+		if unicode.IsUpper(r) && i > 0 {
+			out = append(out, ' ')
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// words splits a name on spaces, underscores, hyphens, and lower-to-upper
+// case changes.
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == ' ' || r == '_' || r == '-':
+			if len(cur) > 0 {
+				out = append(out, string(cur))
+				cur = nil
+			}
+			continue
+		case unicode.IsUpper(r) && i > 0 && len(cur) > 0 && !unicode.IsUpper(runes[i-1]):
+			out = append(out, string(cur))
+			cur = nil
+		}
+		cur = append(cur, r)
+	}
+	if len(cur) > 0 {
+		out = append(out, string(cur))
+	}
+	return out
+}
+
+// toPascalCase converts a name to PascalCase: "due date" → "DueDate".
+func toPascalCase(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// toCamelCase converts a name to camelCase: "due_date" → "dueDate".
+func toCamelCase(s string) string {
+	p := toPascalCase(s)
+	if p == "" {
+		return p
+	}
+	return strings.ToLower(p[:1]) + p[1:]
+}
+
+// toSnakeCase converts a name to snake_case, as Dart names its files:
+// "TaskCard" → "task_card".
+func toSnakeCase(s string) string {
+	ws := words(s)
+	for i, w := range ws {
+		ws[i] = strings.ToLower(w)
+	}
+	return strings.Join(ws, "_")
+}
+
+// toKebabCase converts a name to kebab-case: "TaskDetail" → "task-detail".
+func toKebabCase(s string) string {
+	return strings.ReplaceAll(toSnakeCase(s), "_", "-")
+}
@@ -0,0 +1,223 @@
+package flutter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func taskApp() *ir.Application {
+	return &ir.Application{
+		Name:     "TaskFlow",
+		Platform: "mobile",
+		Data: []*ir.DataModel{
+			{
+				Name: "Task",
+				Fields: []*ir.DataField{
+					{Name: "title", Type: "text", Required: true},
+					{Name: "due_date", Type: "date"},
+					{Name: "status", Type: "enum", Required: true, EnumValues: []string{"todo", "done"}},
+				},
+				Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}},
+			},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "ListTasks"},
+			{Name: "CreateTask", Params: []*ir.Param{{Name: "title"}, {Name: "due_date"}}},
+			{Name: "DeleteTask", Params: []*ir.Param{{Name: "task_id"}}},
+		},
+		Pages: []*ir.Page{
+			{Name: "Home", Content: []*ir.Action{
+				{Type: "display", Text: "show a heading My Tasks"},
+				{Type: "display", Text: "show a list of tasks"},
+				{Type: "loop", Text: "each task shows its title and status"},
+				{Type: "interact", Text: "clicking a task navigates to Profile"},
+				{Type: "input", Text: "there is a form to create a task"},
+			}},
+			{Name: "Profile", Content: []*ir.Action{
+				{Type: "input", Text: "there is a button Back"},
+				{Type: "interact", Text: "clicking the Back button navigates to Home"},
+			}},
+		},
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	g := Generator{}
+	if !g.Enabled(&ir.Application{Platform: "mobile"}) {
+		t.Error("a mobile app should enable the Flutter generator")
+	}
+	if !g.Enabled(&ir.Application{Platform: "web", Config: &ir.BuildConfig{Frontend: "Flutter"}}) {
+		t.Error("a Flutter frontend should enable the Flutter generator")
+	}
+	if g.Enabled(&ir.Application{Platform: "web", Config: &ir.BuildConfig{Frontend: "React"}}) {
+		t.Error("a React web app should not enable the Flutter generator")
+	}
+}
+
+func TestNameCases(t *testing.T) {
+	tests := []struct {
+		fn    func(string) string
+		input string
+		want  string
+	}{
+		{toSnakeCase, "TaskCard", "task_card"},
+		{toKebabCase, "TaskDetail", "task-detail"},
+		{toCamelCase, "due_date", "dueDate"},
+		{toPascalCase, "due date", "DueDate"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.input); got != tt.want {
+			t.Errorf("got %q for %q, want %q", got, tt.input, tt.want)
+		}
+	}
+}
+
+func TestGenerateModel(t *testing.T) {
+	out := generateModel(taskApp().Data[0])
+	for _, want := range []string{
+		"class Task {",
+		"final String title;",
+		"final DateTime? dueDate;",
+		"final String? userId;",
+		"required this.title,",
+		"dueDate: json['due_date'] == null ? null : DateTime.parse(json['due_date'] as String),",
+		"'due_date': dueDate?.toIso8601String(),",
+		"const taskStatusValues = ['todo', 'done'];",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("task.dart missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateAPIClient(t *testing.T) {
+	app := taskApp()
+	app.APIs = append(app.APIs, &ir.Endpoint{Name: "SearchTasks", PageSize: 20, Params: []*ir.Param{{Name: "query"}}})
+	out := generateAPIClient(app)
+	for _, want := range []string{
+		"import '../models/task.dart';",
+		"const apiUrl = String.fromEnvironment('API_URL', defaultValue: 'http://localhost:3001');",
+		"Future<List<Task>> listTasks() async {",
+		"final data = await _request('GET', '/api/tasks');",
+		"Future<Task> createTask({required String title, required String dueDate}) async {",
+		"_request('POST', '/api/task', body: {'title': title, 'due_date': dueDate})",
+		"Future<void> deleteTask({required String taskId}) async {",
+		"Future<List<Task>> searchTasks({required String query, String? cursor}) async {",
+		"query: {'query': query, if (cursor != null) 'cursor': cursor}",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("client.dart missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestAPIClientCallsTargetEnvironment(t *testing.T) {
+	app := taskApp()
+	app.Environments = []*ir.Environment{{Name: "production", Config: map[string]string{"url": "tasks.example.com"}}}
+	app.Config = &ir.BuildConfig{Env: "production"}
+	out := generateAPIClient(app)
+	if !strings.Contains(out, "defaultValue: 'https://tasks.example.com'") {
+		t.Errorf("the client should default to the target environment's API:\n%s", out)
+	}
+}
+
+func TestGenerateMainRoutes(t *testing.T) {
+	out := generateMain(taskApp())
+	for _, want := range []string{
+		"void main() => runApp(const TaskFlowApp());",
+		"'/': (context) => const HomeScreen(),",
+		"'/profile': (context) => const ProfileScreen(),",
+		"colorSchemeSeed: const Color(0xFF4F46E5)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("main.dart missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestListScreen(t *testing.T) {
+	app := taskApp()
+	out := generateScreen(app.Pages[0], app)
+	for _, want := range []string{
+		"class HomeScreen extends StatefulWidget {",
+		"final tasks = await api.listTasks();",
+		"if (!mounted) return;",
+		"onRefresh: _load,",
+		"for (final task in _tasks)",
+		"onTap: () => Navigator.pushNamed(context, '/profile', arguments: task.id),",
+		"final _titleController = TextEditingController();",
+		"await api.createTask(title: _titleController.text, dueDate: _dueDateController.text);",
+		"drawer: const AppDrawer(),",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("home_screen.dart missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "for (final task in _tasks)") != 1 {
+		t.Errorf("the list should render once:\n%s", out)
+	}
+}
+
+func TestButtonNavigates(t *testing.T) {
+	app := taskApp()
+	out := generateScreen(app.Pages[1], app)
+	if !strings.Contains(out, "class ProfileScreen extends StatelessWidget {") {
+		t.Errorf("a screen without a list or form should be stateless:\n%s", out)
+	}
+	if !strings.Contains(out, "FilledButton(onPressed: () => Navigator.pushNamed(context, '/'), child: const Text('Back')),") {
+		t.Errorf("the Back button should open the home screen:\n%s", out)
+	}
+}
+
+func TestGenerateWidget(t *testing.T) {
+	app := taskApp()
+	comp := &ir.Component{
+		Name:    "TaskCard",
+		Props:   []*ir.Prop{{Name: "task", Type: "Task"}},
+		Content: []*ir.Action{{Type: "display", Text: "show the task's title"}},
+	}
+	out := generateWidget(comp, app)
+	for _, want := range []string{
+		"import '../models/task.dart';",
+		"class TaskCard extends StatelessWidget {",
+		"final Task task;",
+		"const TaskCard({super.key, required this.task});",
+		"Text(task.title),",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("task_card.dart missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateWritesProject(t *testing.T) {
+	dir := t.TempDir()
+	app := taskApp()
+	app.Components = []*ir.Component{{Name: "TaskCard"}}
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, f := range []string{
+		"pubspec.yaml",
+		"analysis_options.yaml",
+		"lib/main.dart",
+		"lib/api/client.dart",
+		"lib/models/task.dart",
+		"lib/screens/home_screen.dart",
+		"lib/screens/profile_screen.dart",
+		"lib/widgets/task_card.dart",
+		"lib/widgets/app_drawer.dart",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("expected %s: %v", f, err)
+		}
+	}
+	pubspec, _ := os.ReadFile(filepath.Join(dir, "pubspec.yaml"))
+	if !strings.Contains(string(pubspec), "name: task_flow") {
+		t.Errorf("pubspec.yaml should name the package after the app:\n%s", pubspec)
+	}
+}
@@ -0,0 +1,148 @@
+package flutter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateModel produces lib/models/<model>.dart: an immutable class with
+// the model's fields and the records it belongs to, read from and written
+// to the API's JSON.
+func generateModel(model *ir.DataModel) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n")
+
+	fields := modelFields(model)
+	var enums []*ir.DataField
+	for _, f := range model.Fields {
+		if f.Type == "enum" && len(f.EnumValues) > 0 {
+			enums = append(enums, f)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nclass %s {\n", model.Name)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "  final %s %s;\n", f.typ+f.optional(), f.name)
+	}
+
+	// Constructor
+	fmt.Fprintf(&b, "\n  const %s({\n", model.Name)
+	for _, f := range fields {
+		if f.required {
+			fmt.Fprintf(&b, "    required this.%s,\n", f.name)
+		} else {
+			fmt.Fprintf(&b, "    this.%s,\n", f.name)
+		}
+	}
+	b.WriteString("  });\n")
+
+	// fromJson
+	fmt.Fprintf(&b, "\n  factory %s.fromJson(Map<String, dynamic> json) => %s(\n", model.Name, model.Name)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "        %s: %s,\n", f.name, f.fromJSON(fmt.Sprintf("json['%s']", f.key)))
+	}
+	b.WriteString("      );\n")
+
+	// toJson
+	b.WriteString("\n  Map<String, dynamic> toJson() => {\n")
+	for _, f := range fields {
+		fmt.Fprintf(&b, "        '%s': %s,\n", f.key, f.toJSON())
+	}
+	b.WriteString("      };\n")
+	b.WriteString("}\n")
+
+	for _, f := range enums {
+		fmt.Fprintf(&b, "\n/// The values %s.%s can take.\n", model.Name, toCamelCase(f.Name))
+		fmt.Fprintf(&b, "const %s%sValues = [", toCamelCase(model.Name), toPascalCase(f.Name))
+		for i, v := range f.EnumValues {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "'%s'", dartString(v))
+		}
+		b.WriteString("];\n")
+	}
+
+	return b.String()
+}
+
+// dartField is a field of a model's Dart class.
+type dartField struct {
+	name     string // Dart name, e.g. dueDate
+	key      string // JSON key, e.g. dueDate or userId
+	typ      string // Dart type without its ?
+	required bool
+}
+
+func (f dartField) optional() string {
+	if f.required {
+		return ""
+	}
+	return "?"
+}
+
+// fromJSON returns the Dart expression reading the field from a JSON value.
+func (f dartField) fromJSON(v string) string {
+	switch f.typ {
+	case "DateTime":
+		if f.required {
+			return fmt.Sprintf("DateTime.parse(%s as String)", v)
+		}
+		return fmt.Sprintf("%s == null ? null : DateTime.parse(%s as String)", v, v)
+	case "double":
+		return fmt.Sprintf("(%s as num%s)%s.toDouble()", v, f.optional(), f.optional())
+	case "String", "int", "num", "bool", "Map<String, dynamic>":
+		return fmt.Sprintf("%s as %s%s", v, f.typ, f.optional())
+	}
+	return v
+}
+
+// toJSON returns the Dart expression writing the field to JSON.
+func (f dartField) toJSON() string {
+	if f.typ == "DateTime" {
+		return f.name + f.optional() + ".toIso8601String()"
+	}
+	return f.name
+}
+
+// modelFields returns the fields of a model's Dart class: its id, its
+// fields, its version when edits are protected, and the id of each record
+// it belongs to.
+func modelFields(model *ir.DataModel) []dartField {
+	fields := []dartField{{name: "id", key: "id", typ: "String", required: true}}
+	for _, f := range model.Fields {
+		fields = append(fields, dartField{name: toCamelCase(f.Name), key: f.Name, typ: dartType(f.Type), required: f.Required})
+	}
+	if model.Versioned {
+		fields = append(fields, dartField{name: toCamelCase(ir.VersionColumn), key: ir.VersionColumn, typ: "int", required: true})
+	}
+	for _, rel := range model.Relations {
+		if rel.Kind == "belongs_to" {
+			key := toCamelCase(rel.Target) + "Id"
+			fields = append(fields, dartField{name: key, key: key, typ: "String"})
+		}
+	}
+	return fields
+}
+
+// dartType maps a Human field type to its Dart type.
+func dartType(t string) string {
+	switch strings.ToLower(t) {
+	case "number":
+		return "num"
+	case "decimal":
+		return "double"
+	case "boolean":
+		return "bool"
+	case "date", "datetime":
+		return "DateTime"
+	case "json":
+		return "Map<String, dynamic>"
+	default:
+		// text, email, url, file, image, enum
+		return "String"
+	}
+}
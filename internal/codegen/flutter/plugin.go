@@ -0,0 +1,36 @@
+package flutter
+
+import (
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Meta returns the generator's metadata.
+func (g Generator) Meta() codegen.PluginMeta {
+	return codegen.PluginMeta{
+		Name:        "flutter",
+		Version:     "1.0.0",
+		Description: "Flutter mobile app",
+		Category:    codegen.CategoryFrontend,
+	}
+}
+
+// Enabled reports whether the app is a mobile application, or its
+// frontend config names Flutter.
+func (g Generator) Enabled(app *ir.Application) bool {
+	if strings.EqualFold(app.Platform, "mobile") {
+		return true
+	}
+	if app.Config == nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(app.Config.Frontend), "flutter")
+}
+
+// StageName returns the display name for progress reporting.
+func (g Generator) StageName() string { return "Generating Flutter mobile app" }
+
+// OutputDir returns the subdirectory name within the build output.
+func (g Generator) OutputDir() string { return "flutter" }
@@ -0,0 +1,572 @@
+package flutter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// screenContext carries what a screen's or widget's statements render
+// with.
+type screenContext struct {
+	app      *ir.Application
+	page     *ir.Page
+	model    *ir.DataModel       // the model the screen lists, if any
+	listEp   *ir.Endpoint        // endpoint loading the list, if the screen loads it
+	items    string              // Dart name of the loaded list, e.g. _tasks
+	item     string              // Dart name of each record, e.g. task
+	itemPage string              // page tapping a listed record opens, if any
+	listed   bool                // whether the list has been rendered
+	createEp *ir.Endpoint        // endpoint the screen's form calls, if it has one
+	form     *ir.Action          // the statement declaring that form
+	props    map[string]*ir.Prop // a widget's props, by name
+	imports  map[string]bool     // models the file imports
+	stateful bool
+}
+
+// generateScreen produces lib/screens/<page>_screen.dart. A screen listing
+// a model loads it from the API when it opens and when pulled down, and a
+// screen with a form to create a record submits it to the API.
+func generateScreen(page *ir.Page, app *ir.Application) string {
+	ctx := &screenContext{app: app, page: page, imports: map[string]bool{}}
+	if m := pageModel(page, app); m != nil {
+		ctx.model = m
+		ctx.item = toCamelCase(m.Name)
+		if ep := ir.ListEndpoint(app, m.Name); ep != nil && httpMethod(ep.Name) == "GET" && len(ep.Params) == 0 {
+			if rm, list := responseModel(ep, app); rm == m.Name && list {
+				ctx.listEp = ep
+				ctx.items = "_" + toCamelCase(pluralize(m.Name))
+				ctx.imports[m.Name] = true
+			}
+		}
+		ctx.itemPage, _ = ir.ItemLinkFor(app, page, m.Name)
+		for _, a := range page.Content {
+			if a.Type == "input" && strings.Contains(strings.ToLower(a.Text), "form") {
+				if ep := createEndpoint(app, m.Name); ep != nil {
+					ctx.createEp, ctx.form = ep, a
+				}
+				break
+			}
+		}
+	}
+	ctx.stateful = ctx.listEp != nil || ctx.createEp != nil
+
+	// The list pulls down to reload, so it sits one level deeper. The body
+	// is rendered first: it decides what the file imports.
+	indent := "          "
+	if ctx.listEp != nil {
+		indent = "            "
+	}
+	var body strings.Builder
+	for _, a := range page.Content {
+		writeAction(&body, a, indent, ctx)
+	}
+	if ctx.listEp != nil {
+		writeList(&body, indent, ctx)
+	}
+
+	class := toPascalCase(page.Name) + "Screen"
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import 'package:flutter/material.dart';\n\n")
+	if ctx.stateful {
+		b.WriteString("import '../api/client.dart';\n")
+	}
+	writeModelImports(&b, ctx)
+	drawer := len(app.Pages) > 1
+	if drawer {
+		b.WriteString("import '../widgets/app_drawer.dart';\n")
+	}
+	if ctx.stateful || len(ctx.imports) > 0 || drawer {
+		b.WriteString("\n")
+	}
+
+	if ctx.stateful {
+		fmt.Fprintf(&b, "class %s extends StatefulWidget {\n", class)
+		fmt.Fprintf(&b, "  const %s({super.key});\n\n", class)
+		b.WriteString("  @override\n")
+		fmt.Fprintf(&b, "  State<%s> createState() => _%sState();\n", class, class)
+		b.WriteString("}\n\n")
+		fmt.Fprintf(&b, "class _%sState extends State<%s> {\n", class, class)
+		writeScreenState(&b, ctx)
+	} else {
+		fmt.Fprintf(&b, "class %s extends StatelessWidget {\n", class)
+		fmt.Fprintf(&b, "  const %s({super.key});\n", class)
+	}
+
+	b.WriteString("\n  @override\n")
+	b.WriteString("  Widget build(BuildContext context) {\n")
+	b.WriteString("    return Scaffold(\n")
+	fmt.Fprintf(&b, "      appBar: AppBar(title: const Text('%s')),\n", dartString(humanize(page.Name)))
+	if drawer {
+		b.WriteString("      drawer: const AppDrawer(),\n")
+	}
+	if ctx.listEp != nil {
+		b.WriteString("      body: RefreshIndicator(\n")
+		b.WriteString("        onRefresh: _load,\n")
+		b.WriteString("        child: ListView(\n")
+	} else {
+		b.WriteString("      body: ListView(\n")
+	}
+	outer := indent[:len(indent)-2]
+	fmt.Fprintf(&b, "%spadding: const EdgeInsets.all(16),\n", outer)
+	fmt.Fprintf(&b, "%schildren: [\n", outer)
+	b.WriteString(body.String())
+	fmt.Fprintf(&b, "%s],\n", outer)
+	if ctx.listEp != nil {
+		b.WriteString("        ),\n")
+	}
+	b.WriteString("      ),\n")
+	b.WriteString("    );\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// writeScreenState writes the state of a screen loading a list or
+// submitting a form, and the methods doing so.
+func writeScreenState(b *strings.Builder, ctx *screenContext) {
+	if ctx.listEp != nil {
+		fmt.Fprintf(b, "  List<%s> %s = [];\n", ctx.model.Name, ctx.items)
+		b.WriteString("  bool _loading = true;\n")
+		b.WriteString("  String? _error;\n")
+	}
+	if ctx.createEp != nil {
+		for _, p := range ctx.createEp.Params {
+			fmt.Fprintf(b, "  final _%sController = TextEditingController();\n", toCamelCase(p.Name))
+		}
+		b.WriteString("  bool _submitting = false;\n")
+	}
+
+	if ctx.listEp != nil {
+		b.WriteString("\n  @override\n")
+		b.WriteString("  void initState() {\n")
+		b.WriteString("    super.initState();\n")
+		b.WriteString("    _load();\n")
+		b.WriteString("  }\n")
+	}
+	if ctx.createEp != nil && len(ctx.createEp.Params) > 0 {
+		b.WriteString("\n  @override\n")
+		b.WriteString("  void dispose() {\n")
+		for _, p := range ctx.createEp.Params {
+			fmt.Fprintf(b, "    _%sController.dispose();\n", toCamelCase(p.Name))
+		}
+		b.WriteString("    super.dispose();\n")
+		b.WriteString("  }\n")
+	}
+
+	if ctx.listEp != nil {
+		subject := strings.ToLower(pluralize(ctx.model.Name))
+		b.WriteString("\n  Future<void> _load() async {\n")
+		b.WriteString("    try {\n")
+		fmt.Fprintf(b, "      final %s = await api.%s();\n", strings.TrimPrefix(ctx.items, "_"), toCamelCase(ctx.listEp.Name))
+		b.WriteString("      if (!mounted) return;\n")
+		b.WriteString("      setState(() {\n")
+		fmt.Fprintf(b, "        %s = %s;\n", ctx.items, strings.TrimPrefix(ctx.items, "_"))
+		b.WriteString("        _error = null;\n")
+		b.WriteString("        _loading = false;\n")
+		b.WriteString("      });\n")
+		b.WriteString("    } catch (e) {\n")
+		b.WriteString("      if (!mounted) return;\n")
+		b.WriteString("      setState(() {\n")
+		fmt.Fprintf(b, "        _error = e is ApiException ? e.message : '%s';\n", dartString(ir.LoadErrorMessage(ctx.page, subject)))
+		b.WriteString("        _loading = false;\n")
+		b.WriteString("      });\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n")
+	}
+
+	if ctx.createEp != nil {
+		var args []string
+		for _, p := range ctx.createEp.Params {
+			args = append(args, fmt.Sprintf("%s: _%sController.text", toCamelCase(p.Name), toCamelCase(p.Name)))
+		}
+		b.WriteString("\n  Future<void> _submit() async {\n")
+		b.WriteString("    setState(() => _submitting = true);\n")
+		b.WriteString("    try {\n")
+		fmt.Fprintf(b, "      await api.%s(%s);\n", toCamelCase(ctx.createEp.Name), strings.Join(args, ", "))
+		b.WriteString("      if (!mounted) return;\n")
+		for _, p := range ctx.createEp.Params {
+			fmt.Fprintf(b, "      _%sController.clear();\n", toCamelCase(p.Name))
+		}
+		fmt.Fprintf(b, "      ScaffoldMessenger.of(context).showSnackBar(const SnackBar(content: Text('%s saved')));\n", dartString(humanize(ctx.model.Name)))
+		if ctx.listEp != nil {
+			b.WriteString("      await _load();\n")
+		}
+		b.WriteString("    } catch (e) {\n")
+		b.WriteString("      if (!mounted) return;\n")
+		b.WriteString("      ScaffoldMessenger.of(context).showSnackBar(SnackBar(content: Text(e is ApiException ? e.message : 'Something went wrong')));\n")
+		b.WriteString("    } finally {\n")
+		b.WriteString("      if (mounted) setState(() => _submitting = false);\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n")
+	}
+}
+
+// generateWidget produces lib/widgets/<component>.dart: a stateless
+// widget taking the component's props.
+func generateWidget(comp *ir.Component, app *ir.Application) string {
+	ctx := &screenContext{app: app, props: map[string]*ir.Prop{}, imports: map[string]bool{}}
+	for _, p := range comp.Props {
+		ctx.props[strings.ToLower(p.Name)] = p
+		if modelNamed(app, p.Type) != nil {
+			ctx.imports[modelNamed(app, p.Type).Name] = true
+		}
+	}
+
+	var body strings.Builder
+	for _, a := range comp.Content {
+		writeAction(&body, a, "        ", ctx)
+	}
+
+	class := toPascalCase(comp.Name)
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import 'package:flutter/material.dart';\n")
+	if len(ctx.imports) > 0 {
+		b.WriteString("\n")
+	}
+	writeModelImports(&b, ctx)
+	fmt.Fprintf(&b, "\nclass %s extends StatelessWidget {\n", class)
+	for _, p := range comp.Props {
+		fmt.Fprintf(&b, "  final %s %s;\n", propType(app, p), toCamelCase(p.Name))
+	}
+	if len(comp.Props) > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "  const %s({super.key", class)
+	for _, p := range comp.Props {
+		fmt.Fprintf(&b, ", required this.%s", toCamelCase(p.Name))
+	}
+	b.WriteString("});\n\n")
+	b.WriteString("  @override\n")
+	b.WriteString("  Widget build(BuildContext context) {\n")
+	b.WriteString("    return Column(\n")
+	b.WriteString("      crossAxisAlignment: CrossAxisAlignment.start,\n")
+	b.WriteString("      children: [\n")
+	b.WriteString(body.String())
+	b.WriteString("      ],\n")
+	b.WriteString("    );\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// writeModelImports imports the models a file uses, sorted.
+func writeModelImports(b *strings.Builder, ctx *screenContext) {
+	var names []string
+	for name := range ctx.imports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(b, "import '../models/%s.dart';\n", toSnakeCase(name))
+	}
+}
+
+// writeAction writes the widgets a page or component statement renders as,
+// in a children list. Statements with no widget of their own are left as
+// comments.
+func writeAction(b *strings.Builder, a *ir.Action, indent string, ctx *screenContext) {
+	lower := strings.ToLower(a.Text)
+	if a == ctx.form {
+		writeForm(b, indent, ctx)
+		return
+	}
+	switch a.Type {
+	case "display":
+		if a.Template != "" {
+			if expr, ok := templateString(a.Template, ctx); ok {
+				fmt.Fprintf(b, "%sText(%s),\n", indent, expr)
+				return
+			}
+		}
+		if heading, ok := strings.CutPrefix(lower, "show a heading "); ok {
+			fmt.Fprintf(b, "%sText('%s', style: Theme.of(context).textTheme.headlineSmall),\n", indent, dartString(a.Text[len(a.Text)-len(heading):]))
+			return
+		}
+		if ctx.listEp != nil && (ir.IsTable(a) || strings.Contains(lower, "list of")) {
+			writeList(b, indent, ctx)
+			return
+		}
+		if strings.Contains(lower, "button") {
+			writeButton(b, a.Text, indent, ctx)
+			return
+		}
+		if fields, ok := propFields(a.Text, ctx); ok {
+			for _, f := range fields {
+				fmt.Fprintf(b, "%sText(%s),\n", indent, f)
+			}
+			return
+		}
+	case "loop":
+		if ctx.listEp != nil {
+			writeList(b, indent, ctx)
+			return
+		}
+	case "input":
+		if strings.Contains(lower, "button") {
+			writeButton(b, a.Text, indent, ctx)
+			return
+		}
+	case "interact":
+		if ctx.itemPage != "" && ctx.listEp != nil && strings.Contains(lower, "navigates to") {
+			fmt.Fprintf(b, "%s// %s — tapping a listed %s opens it\n", indent, a.Text, strings.ToLower(ctx.model.Name))
+			return
+		}
+	case "query":
+		if ctx.listEp != nil {
+			fmt.Fprintf(b, "%s// %s — loaded by _load()\n", indent, a.Text)
+			return
+		}
+	}
+	fmt.Fprintf(b, "%s// TODO: %s\n", indent, a.Text)
+}
+
+// writeList writes the screen's list of records, once: a spinner while it
+// loads, the error if loading failed, and a tile for each record, opening
+// the page its records link to when tapped.
+func writeList(b *strings.Builder, indent string, ctx *screenContext) {
+	if ctx.listed {
+		return
+	}
+	ctx.listed = true
+	title, subtitle := listFields(ctx)
+
+	fmt.Fprintf(b, "%sif (_loading)\n", indent)
+	fmt.Fprintf(b, "%s  const Center(child: CircularProgressIndicator())\n", indent)
+	fmt.Fprintf(b, "%selse if (_error != null)\n", indent)
+	fmt.Fprintf(b, "%s  Text(_error!, style: TextStyle(color: Theme.of(context).colorScheme.error))\n", indent)
+	fmt.Fprintf(b, "%selse if (%s.isEmpty)\n", indent, ctx.items)
+	fmt.Fprintf(b, "%s  const Text('No %s yet.')\n", indent, strings.ToLower(pluralize(humanize(ctx.model.Name))))
+	fmt.Fprintf(b, "%selse\n", indent)
+	fmt.Fprintf(b, "%s  for (final %s in %s)\n", indent, ctx.item, ctx.items)
+	fmt.Fprintf(b, "%s    ListTile(\n", indent)
+	fmt.Fprintf(b, "%s      title: Text(%s),\n", indent, fieldText(ctx.item, title))
+	if subtitle != nil {
+		fmt.Fprintf(b, "%s      subtitle: Text(%s),\n", indent, fieldText(ctx.item, subtitle))
+	}
+	if ctx.itemPage != "" {
+		fmt.Fprintf(b, "%s      onTap: () => Navigator.pushNamed(context, '%s', arguments: %s.id),\n", indent, routePath(ctx.app, ctx.itemPage), ctx.item)
+	}
+	fmt.Fprintf(b, "%s    ),\n", indent)
+}
+
+// listFields returns the fields each listed record shows: the ones the
+// page's "each task shows its title and status" names, else its name,
+// title, or label, or its first field.
+func listFields(ctx *screenContext) (title, subtitle *ir.DataField) {
+	var named []*ir.DataField
+	for _, a := range ctx.page.Content {
+		if a.Type != "loop" {
+			continue
+		}
+		for _, w := range strings.FieldsFunc(strings.ToLower(a.Text), func(r rune) bool { return r == ' ' || r == ',' }) {
+			if f := ctx.model.FieldNamed(w); f != nil && !f.Encrypted {
+				named = append(named, f)
+			}
+		}
+	}
+	if len(named) == 0 {
+		if f := ir.RecordTitle(ctx.model); f != nil {
+			named = append(named, f)
+		} else if fields := ir.RecordFields(ctx.model); len(fields) > 0 {
+			named = append(named, fields[0])
+		}
+	}
+	switch len(named) {
+	case 0:
+		return &ir.DataField{Name: "id", Type: "text", Required: true}, nil
+	case 1:
+		return named[0], nil
+	}
+	return named[0], named[1]
+}
+
+// fieldText returns the Dart expression showing a record's field as text.
+func fieldText(record string, f *ir.DataField) string {
+	expr := record + "." + toCamelCase(f.Name)
+	if dartType(f.Type) == "String" && f.Required {
+		return expr
+	}
+	return "'${" + expr + "}'"
+}
+
+// writeForm writes the screen's form creating a record: a text field for
+// each input of the create endpoint, and a button submitting them.
+func writeForm(b *strings.Builder, indent string, ctx *screenContext) {
+	for _, p := range ctx.createEp.Params {
+		fmt.Fprintf(b, "%sTextField(\n", indent)
+		fmt.Fprintf(b, "%s  controller: _%sController,\n", indent, toCamelCase(p.Name))
+		fmt.Fprintf(b, "%s  decoration: const InputDecoration(labelText: '%s'),\n", indent, dartString(capitalize(strings.ReplaceAll(p.Name, "_", " "))))
+		fmt.Fprintf(b, "%s),\n", indent)
+	}
+	fmt.Fprintf(b, "%sconst SizedBox(height: 12),\n", indent)
+	fmt.Fprintf(b, "%sFilledButton(\n", indent)
+	fmt.Fprintf(b, "%s  onPressed: _submitting ? null : _submit,\n", indent)
+	fmt.Fprintf(b, "%s  child: Text(_submitting ? 'Saving…' : 'Create %s'),\n", indent, dartString(strings.ToLower(humanize(ctx.model.Name))))
+	fmt.Fprintf(b, "%s),\n", indent)
+}
+
+// writeButton writes a button, labeled with the words after "button", or
+// before it. One the page says navigates somewhere opens that page.
+func writeButton(b *strings.Builder, text, indent string, ctx *screenContext) {
+	label := buttonLabel(text)
+	onPressed := "() {}"
+	if ctx.page != nil && label != "" {
+		for _, a := range ctx.page.Content {
+			lower := strings.ToLower(a.Text)
+			if a.Type != "interact" || !strings.Contains(lower, strings.ToLower(label)) {
+				continue
+			}
+			if _, target, ok := strings.Cut(a.Text, "navigates to "); ok {
+				for _, p := range ctx.app.Pages {
+					if strings.EqualFold(strings.TrimSpace(target), p.Name) {
+						onPressed = fmt.Sprintf("() => Navigator.pushNamed(context, '%s')", routePath(ctx.app, p.Name))
+					}
+				}
+			}
+		}
+	}
+	if onPressed == "() {}" {
+		fmt.Fprintf(b, "%s// TODO: %s\n", indent, text)
+	}
+	fmt.Fprintf(b, "%sFilledButton(onPressed: %s, child: const Text('%s')),\n", indent, onPressed, dartString(label))
+}
+
+func buttonLabel(text string) string {
+	lower := strings.ToLower(text)
+	idx := strings.Index(lower, "button")
+	after := strings.TrimSpace(text[idx+len("button"):])
+	for _, w := range []string{"to ", "that ", "which ", "for "} {
+		if strings.HasPrefix(strings.ToLower(after), w) {
+			after = ""
+		}
+	}
+	if after != "" {
+		return after
+	}
+	before := strings.Fields(text[:idx])
+	for len(before) > 0 {
+		switch strings.ToLower(before[0]) {
+		case "there", "is", "a", "an", "the", "show", "display", "add":
+			before = before[1:]
+			continue
+		}
+		break
+	}
+	if len(before) == 0 {
+		return "Submit"
+	}
+	return capitalize(strings.Join(before, " "))
+}
+
+// templateString returns the Dart string for a show "..." template whose
+// references all name a widget's props, e.g. 'Hello, ${user.name}!'.
+func templateString(tmpl string, ctx *screenContext) (string, bool) {
+	var b strings.Builder
+	for _, part := range ir.ParseTemplate(tmpl) {
+		if part.Root == "" {
+			b.WriteString(dartString(part.Text))
+			continue
+		}
+		p := ctx.props[strings.ToLower(part.Root)]
+		if p == nil {
+			return "", false
+		}
+		b.WriteString("${" + toCamelCase(p.Name))
+		if part.Field != "" {
+			b.WriteString("." + toCamelCase(part.Field))
+		}
+		b.WriteString("}")
+	}
+	return "'" + b.String() + "'", true
+}
+
+// propFields returns the texts showing the fields a "show the task's title
+// and status" statement names of a widget's prop.
+func propFields(text string, ctx *screenContext) ([]string, bool) {
+	lower := strings.ToLower(text)
+	idx := strings.Index(lower, "'s ")
+	if idx < 0 || ctx.props == nil {
+		return nil, false
+	}
+	words := strings.Fields(lower[:idx])
+	if len(words) == 0 {
+		return nil, false
+	}
+	p := ctx.props[words[len(words)-1]]
+	if p == nil {
+		return nil, false
+	}
+	m := modelNamed(ctx.app, p.Type)
+	if m == nil {
+		return nil, false
+	}
+	var texts []string
+	for _, w := range strings.FieldsFunc(lower[idx+3:], func(r rune) bool { return r == ' ' || r == ',' }) {
+		if f := m.FieldNamed(w); f != nil && !f.Encrypted {
+			texts = append(texts, fieldText(toCamelCase(p.Name), f))
+		}
+	}
+	return texts, len(texts) > 0
+}
+
+// pageModel returns the model a page lists: the one its table, query, or
+// loop names.
+func pageModel(page *ir.Page, app *ir.Application) *ir.DataModel {
+	for _, a := range page.Content {
+		if ir.IsTable(a) {
+			if m := ir.TableModel(app, a); m != nil {
+				return m
+			}
+			continue
+		}
+		if a.Type != "query" && a.Type != "loop" && !(a.Type == "display" && strings.Contains(strings.ToLower(a.Text), "list of")) {
+			continue
+		}
+		lower := strings.ToLower(a.Text)
+		for _, m := range app.Data {
+			if strings.Contains(lower, strings.ToLower(m.Name)) {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// createEndpoint returns the endpoint creating a model's records, or nil.
+func createEndpoint(app *ir.Application, model string) *ir.Endpoint {
+	for _, ep := range app.APIs {
+		if strings.EqualFold(ep.Name, "Create"+model) {
+			return ep
+		}
+	}
+	return nil
+}
+
+// modelNamed returns the data model called name, or nil.
+func modelNamed(app *ir.Application, name string) *ir.DataModel {
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, name) {
+			return m
+		}
+	}
+	return nil
+}
+
+// propType returns the Dart type of a widget's prop: its model, its field
+// type, or dynamic.
+func propType(app *ir.Application, p *ir.Prop) string {
+	if m := modelNamed(app, p.Type); m != nil {
+		return m.Name
+	}
+	if p.Type == "" {
+		return "dynamic"
+	}
+	return dartType(p.Type)
+}
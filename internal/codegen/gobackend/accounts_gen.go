@@ -0,0 +1,276 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeAccountDTO writes the request of an endpoint the accounts block
+// added. Its fields have the model's types, so they can be saved as sent,
+// and binding tags check what Gin can: an email is an email address, and a
+// new password is long enough. It reports whether a field is a time.
+func writeAccountDTO(sb *strings.Builder, api *ir.Endpoint, app *ir.Application) (usesTime bool) {
+	fmt.Fprintf(sb, "type %sRequest struct {\n", toPascalCase(api.Name))
+	switch api.Account {
+	case ir.AccountUpdateProfile:
+		for _, f := range ir.ProfileFields(app) {
+			goT := goType(f.Type, f.Required)
+			if f.EncryptsAtRest() {
+				goT = encryptedGoType(f.Required)
+			}
+			usesTime = usesTime || strings.Contains(goT, "time.Time")
+			var rules []string
+			if f.Required && strings.HasSuffix(goT, "string") {
+				rules = append(rules, "required")
+			} else if f.Type == "email" {
+				rules = append(rules, "omitempty")
+			}
+			if f.Type == "email" {
+				rules = append(rules, "email")
+			}
+			binding := ""
+			if len(rules) > 0 {
+				binding = fmt.Sprintf(" binding:\"%s\"", strings.Join(rules, ","))
+			}
+			fmt.Fprintf(sb, "\t%s %s `json:\"%s\"%s`\n", toPascalCase(f.Name), goT, f.Name, binding)
+		}
+	case ir.AccountChangePassword:
+		sb.WriteString("\tCurrentPassword string `json:\"current_password\" binding:\"required\"`\n")
+		fmt.Fprintf(sb, "\tNewPassword string `json:\"new_password\" binding:\"required,min=%d\"`\n", ir.MinPasswordLength)
+	case ir.AccountInvite:
+		sb.WriteString("\tEmail string `json:\"email\" binding:\"required,email\"`\n")
+		sb.WriteString("\tRole string `json:\"role\"`\n")
+	default:
+		for _, p := range api.Params {
+			fmt.Fprintf(sb, "\t%s string `json:\"%s\" binding:\"required\"`\n", toPascalCase(p.Name), p.Name)
+		}
+	}
+	sb.WriteString("}\n\n")
+	return usesTime
+}
+
+// writeAccountHandler writes the body of an endpoint the accounts block
+// added. Each acts on the signed-in user, and none responds with their
+// password. Changing the password and deleting the account both take the
+// current password, so a stolen session alone can do neither. It reports
+// whether the body uses fieldcrypt.
+func writeAccountHandler(sb *strings.Builder, api *ir.Endpoint, app *ir.Application) (usesFieldCrypt bool) {
+	model := toPascalCase(app.Accounts.Model)
+	if api.Auth {
+		fmt.Fprintf(sb, "\t\tuser := c.MustGet(\"user\").(*models.%s)\n", model)
+	}
+
+	switch api.Account {
+	case ir.AccountProfile:
+		sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": profileOf(user)})\n")
+
+	case ir.AccountUpdateProfile:
+		fields := ir.ProfileFields(app)
+		for _, f := range fields {
+			if !f.Unique || f.EncryptsAtRest() {
+				continue
+			}
+			name := toPascalCase(f.Name)
+			fmt.Fprintf(sb, "\t\t// %s belongs to one account at a time\n", f.Name)
+			sb.WriteString("\t\tvar taken int64\n")
+			fmt.Fprintf(sb, "\t\tdb.Model(&models.%s{}).Where(\"%s = ? AND id <> ?\", req.%s, user.ID).Count(&taken)\n", model, toSnakeCase(f.Name), name)
+			sb.WriteString("\t\tif taken > 0 {\n")
+			fmt.Fprintf(sb, "\t\t\tc.JSON(http.StatusConflict, gin.H{\"error\": \"%s is already taken\"})\n", f.Name)
+			sb.WriteString("\t\t\treturn\n\t\t}\n")
+		}
+		for _, f := range fields {
+			name := toPascalCase(f.Name)
+			fmt.Fprintf(sb, "\t\tuser.%s = req.%s\n", name, name)
+			usesFieldCrypt = usesFieldCrypt || f.EncryptsAtRest()
+		}
+		sb.WriteString("\t\tif err := db.Save(user).Error; err != nil {\n")
+		sb.WriteString("\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to save your profile\"})\n")
+		sb.WriteString("\t\t\treturn\n\t\t}\n")
+		sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": profileOf(user)})\n")
+
+	case ir.AccountChangePassword:
+		sb.WriteString("\t\tif !middleware.CheckPasswordHash(req.CurrentPassword, user.Password) {\n")
+		sb.WriteString("\t\t\tc.JSON(http.StatusForbidden, gin.H{\"error\": \"Your current password is incorrect\"})\n")
+		sb.WriteString("\t\t\treturn\n\t\t}\n")
+		sb.WriteString("\t\tif req.NewPassword == req.CurrentPassword {\n")
+		sb.WriteString("\t\t\tc.JSON(http.StatusBadRequest, gin.H{\"error\": \"Choose a password you are not using now\"})\n")
+		sb.WriteString("\t\t\treturn\n\t\t}\n")
+		sb.WriteString("\t\thashedPassword, err := middleware.HashPassword(req.NewPassword)\n")
+		sb.WriteString("\t\tif err != nil {\n\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to hash password\"})\n\t\t\treturn\n\t\t}\n")
+		sb.WriteString("\t\tif err := db.Model(user).Update(\"password\", hashedPassword).Error; err != nil {\n")
+		sb.WriteString("\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to change your password\"})\n")
+		sb.WriteString("\t\t\treturn\n\t\t}\n")
+		sb.WriteString("\t\tc.Status(http.StatusNoContent)\n")
+
+	case ir.AccountDelete:
+		sb.WriteString("\t\tif !middleware.CheckPasswordHash(req.Password, user.Password) {\n")
+		sb.WriteString("\t\t\tc.JSON(http.StatusForbidden, gin.H{\"error\": \"Your password is incorrect\"})\n")
+		sb.WriteString("\t\t\treturn\n\t\t}\n")
+		sb.WriteString("\t\tif err := db.Delete(user).Error; err != nil {\n")
+		sb.WriteString("\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to delete your account\"})\n")
+		sb.WriteString("\t\t\treturn\n\t\t}\n")
+		sb.WriteString("\t\tc.Status(http.StatusNoContent)\n")
+
+	case ir.AccountInvite, ir.AccountInvitation, ir.AccountAcceptInvite:
+		writeInvitationHandler(sb, api, app)
+	}
+	return usesFieldCrypt
+}
+
+// invitesTeammates reports whether the app's accounts block lets users
+// invite teammates to a team it has a model for.
+func invitesTeammates(app *ir.Application) bool {
+	return ir.AccountModel(app) != nil && app.Accounts.Invite != nil && app.Accounts.Invite.Team != ""
+}
+
+// writeInvitationHandler writes the body of an invitation endpoint. A
+// member invites a teammate to their own team as one of the app's roles;
+// the invitation is a secret token, emailed as a link to the accept page
+// when the app sends email and given back to the inviter to share when it
+// doesn't. Accepting takes the signed-in user whose email it was sent to,
+// before it expires, and makes them a member with the role, which the
+// policies then check.
+func writeInvitationHandler(sb *strings.Builder, api *ir.Endpoint, app *ir.Application) {
+	if !invitesTeammates(app) {
+		sb.WriteString("\t\tc.JSON(http.StatusNotImplemented, gin.H{\"error\": \"Invitations have no team to join\"})\n")
+		return
+	}
+	inv := app.Accounts.Invite
+	user := toPascalCase(app.Accounts.Model)
+	team := toPascalCase(inv.Team)
+	teamKey := team + "ID"
+	teamCol := toSnakeCase(inv.Team) + "_id"
+	userCol := toSnakeCase(app.Accounts.Model) + "_id"
+	hasEmail := ir.AccountModel(app).FieldNamed("email") != nil
+	fail := func(status, msg string) {
+		fmt.Fprintf(sb, "\t\t\tc.JSON(http.%s, gin.H{\"error\": %s})\n", status, msg)
+		sb.WriteString("\t\t\treturn\n\t\t}\n")
+	}
+
+	switch api.Account {
+	case ir.AccountInvite:
+		fmt.Fprintf(sb, "\t\tvar membership models.%s\n", ir.MembershipModel)
+		fmt.Fprintf(sb, "\t\tif err := db.Where(\"%s = ?\", user.ID).First(&membership).Error; err != nil {\n", userCol)
+		fail("StatusForbidden", fmt.Sprintf("\"Only %s members can invite teammates\"", ir.FieldLabel(inv.Team)))
+		sb.WriteString("\t\trole := req.Role\n")
+		fmt.Fprintf(sb, "\t\tif role == \"\" {\n\t\t\trole = %q\n\t\t}\n", inv.Role)
+		if len(inv.Roles) > 0 {
+			var cases []string
+			for _, r := range inv.Roles {
+				cases = append(cases, fmt.Sprintf("%q", r))
+			}
+			fmt.Fprintf(sb, "\t\tswitch role {\n\t\tcase %s:\n\t\tdefault:\n", strings.Join(cases, ", "))
+			fmt.Fprintf(sb, "\t\t\tc.JSON(http.StatusBadRequest, gin.H{\"error\": \"role must be one of: %s\"})\n", strings.Join(inv.Roles, ", "))
+			sb.WriteString("\t\t\treturn\n\t\t}\n")
+		}
+		if hasEmail {
+			sb.WriteString("\t\tvar teammates int64\n")
+			fmt.Fprintf(sb, "\t\tdb.Model(&models.%s{}).Where(\"%s = ? AND %s IN (?)\", membership.%s, db.Model(&models.%s{}).Select(\"id\").Where(\"email = ?\", req.Email)).Count(&teammates)\n",
+				ir.MembershipModel, teamCol, userCol, teamKey, user)
+			sb.WriteString("\t\tif teammates > 0 {\n")
+			fail("StatusConflict", "req.Email + \" is already a teammate\"")
+		}
+		sb.WriteString("\t\ttoken := make([]byte, 32)\n")
+		sb.WriteString("\t\tif _, err := rand.Read(token); err != nil {\n")
+		fail("StatusInternalServerError", "\"Failed to create the invitation\"")
+		sb.WriteString("\t\t// A new invitation replaces one still pending for the same email\n")
+		fmt.Fprintf(sb, "\t\tdb.Where(\"%s = ? AND email = ? AND accepted IS NULL\", membership.%s, req.Email).Delete(&models.%s{})\n", teamCol, teamKey, ir.InvitationModel)
+		fmt.Fprintf(sb, "\t\tinvitation := models.%s{\n", ir.InvitationModel)
+		sb.WriteString("\t\t\tEmail:   req.Email,\n")
+		sb.WriteString("\t\t\tToken:   hex.EncodeToString(token),\n")
+		sb.WriteString("\t\t\tRole:    role,\n")
+		fmt.Fprintf(sb, "\t\t\tExpires: time.Now().Add(%d * 24 * time.Hour),\n", inv.Expires)
+		fmt.Fprintf(sb, "\t\t\t%s: membership.%s,\n", teamKey, teamKey)
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t\tif err := db.Create(&invitation).Error; err != nil {\n")
+		fail("StatusInternalServerError", "\"Failed to create the invitation\"")
+		fmt.Fprintf(sb, "\t\tlink := strings.TrimSuffix(os.Getenv(\"SITE_URL\"), \"/\") + \"%s?token=\" + invitation.Token\n", inv.AcceptPath())
+		sb.WriteString("\t\tsent := gin.H{\"id\": invitation.ID, \"email\": invitation.Email, \"role\": role, \"expires\": invitation.Expires}\n")
+		if ir.EmailIntegration(app) != nil {
+			sb.WriteString("\t\tsubject := \"You're invited to join the team\"\n")
+			if m := findModel(app, inv.Team); m != nil && m.FieldNamed("name") != nil {
+				fmt.Fprintf(sb, "\t\tvar team models.%s\n", team)
+				fmt.Fprintf(sb, "\t\tif db.First(&team, \"id = ?\", membership.%s).Error == nil {\n", teamKey)
+				sb.WriteString("\t\t\tsubject = \"You're invited to join \" + team.Name\n")
+				sb.WriteString("\t\t}\n")
+			}
+			fmt.Fprintf(sb, "\t\tif err := services.SendEmail(req.Email, subject, \"You've been invited as \"+role+\". Accept within %d days: \"+link); err != nil {\n", inv.Expires)
+			fail("StatusBadGateway", "\"Failed to email the invitation\"")
+			sb.WriteString("\t\tc.JSON(http.StatusCreated, gin.H{\"data\": sent})\n")
+		} else {
+			sb.WriteString("\t\t// No email integration: the inviter shares the link\n")
+			sb.WriteString("\t\tsent[\"link\"] = link\n")
+			sb.WriteString("\t\tc.JSON(http.StatusCreated, gin.H{\"data\": sent})\n")
+		}
+
+	case ir.AccountInvitation:
+		fmt.Fprintf(sb, "\t\tvar invitation models.%s\n", ir.InvitationModel)
+		fmt.Fprintf(sb, "\t\tif err := db.Preload(%q).Where(\"token = ?\", c.Query(\"token\")).First(&invitation).Error; err != nil {\n", team)
+		fail("StatusNotFound", "\"Invitation not found\"")
+		writeInvitationUsable(sb)
+		sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": gin.H{\n")
+		sb.WriteString("\t\t\t\"email\":   invitation.Email,\n")
+		sb.WriteString("\t\t\t\"role\":    invitation.Role,\n")
+		sb.WriteString("\t\t\t\"expires\": invitation.Expires,\n")
+		fmt.Fprintf(sb, "\t\t\t\"team\":    invitation.%s,\n", team)
+		sb.WriteString("\t\t}})\n")
+
+	case ir.AccountAcceptInvite:
+		fmt.Fprintf(sb, "\t\tvar invitation models.%s\n", ir.InvitationModel)
+		sb.WriteString("\t\tif err := db.Where(\"token = ?\", req.Token).First(&invitation).Error; err != nil {\n")
+		fail("StatusNotFound", "\"Invitation not found\"")
+		writeInvitationUsable(sb)
+		if hasEmail {
+			sb.WriteString("\t\tif !strings.EqualFold(user.Email, invitation.Email) {\n")
+			fail("StatusForbidden", "\"This invitation is for \" + invitation.Email")
+		}
+		fmt.Fprintf(sb, "\t\tvar membership models.%s\n", ir.MembershipModel)
+		sb.WriteString("\t\terr := db.Transaction(func(tx *gorm.DB) error {\n")
+		sb.WriteString("\t\t\tnow := time.Now()\n")
+		sb.WriteString("\t\t\tif err := tx.Model(&invitation).Update(\"accepted\", &now).Error; err != nil {\n\t\t\t\treturn err\n\t\t\t}\n")
+		if ir.InviteSetsUserRole(app) {
+			sb.WriteString("\t\t\t// The role the policies check\n")
+			sb.WriteString("\t\t\tif err := tx.Model(user).Update(\"role\", invitation.Role).Error; err != nil {\n\t\t\t\treturn err\n\t\t\t}\n")
+		}
+		fmt.Fprintf(sb, "\t\t\tif err := tx.Where(\"%s = ? AND %s = ?\", user.ID, invitation.%s).First(&membership).Error; err == nil {\n", userCol, teamCol, teamKey)
+		sb.WriteString("\t\t\t\treturn tx.Model(&membership).Update(\"role\", invitation.Role).Error\n")
+		sb.WriteString("\t\t\t}\n")
+		fmt.Fprintf(sb, "\t\t\tmembership = models.%s{Role: invitation.Role, %sID: user.ID, %s: invitation.%s}\n", ir.MembershipModel, user, teamKey, teamKey)
+		sb.WriteString("\t\t\treturn tx.Create(&membership).Error\n")
+		sb.WriteString("\t\t})\n")
+		sb.WriteString("\t\tif err != nil {\n")
+		fail("StatusInternalServerError", "\"Failed to accept the invitation\"")
+		sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": membership})\n")
+	}
+}
+
+// writeInvitationUsable answers 410 for an invitation already accepted or
+// past its expiry.
+func writeInvitationUsable(sb *strings.Builder) {
+	sb.WriteString("\t\tif invitation.Accepted != nil {\n")
+	sb.WriteString("\t\t\tc.JSON(http.StatusGone, gin.H{\"error\": \"This invitation has already been accepted\"})\n")
+	sb.WriteString("\t\t\treturn\n\t\t}\n")
+	sb.WriteString("\t\tif invitation.Expires.Before(time.Now()) {\n")
+	sb.WriteString("\t\t\tc.JSON(http.StatusGone, gin.H{\"error\": \"This invitation has expired\"})\n")
+	sb.WriteString("\t\t\treturn\n\t\t}\n")
+}
+
+// writeProfileOf writes the helper responding with a user's account:
+// everything but their password.
+func writeProfileOf(sb *strings.Builder, app *ir.Application) {
+	m := ir.AccountModel(app)
+	fmt.Fprintf(sb, "// profileOf is a user's account as they see it: everything but their\n// password.\nfunc profileOf(user *models.%s) gin.H {\n", toPascalCase(m.Name))
+	sb.WriteString("\treturn gin.H{\n")
+	sb.WriteString("\t\t\"id\": user.ID,\n")
+	for _, f := range m.Fields {
+		if strings.EqualFold(f.Name, "password") {
+			continue
+		}
+		fmt.Fprintf(sb, "\t\t\"%s\": user.%s,\n", toCamelCase(f.Name), toPascalCase(f.Name))
+	}
+	sb.WriteString("\t\t\"createdAt\": user.CreatedAt,\n")
+	sb.WriteString("\t\t\"updatedAt\": user.UpdatedAt,\n")
+	sb.WriteString("\t}\n}\n\n")
+}
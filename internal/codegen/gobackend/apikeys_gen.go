@@ -0,0 +1,200 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeAPIKeyAuth appends the API key half of RequireAuth to the auth
+// middleware: a request carrying a key in the X-API-Key header is the
+// key's user's, and a read key can only make GET requests. RequireSignIn
+// keeps keys from managing keys.
+func writeAPIKeyAuth(sb *strings.Builder, app *ir.Application) {
+	sb.WriteString(fmt.Sprintf(`// APIKeyHeader is the header external clients send their API key in.
+const APIKeyHeader = %q
+
+// HashAPIKey returns a key's SHA-256 hash, which is all that's stored of it.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// KeyAllows reports whether a key with these scopes may make a request of
+// this HTTP method: a read key can only make GET requests.
+func KeyAllows(scopes, method string) bool {
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope == %q {
+			return true
+		}
+	}
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// authenticateAPIKey authenticates an external client by its API key: the
+// request is the key's user's, and each use is recorded as the key's last.
+func authenticateAPIKey(c *gin.Context, db *gorm.DB, key string) {
+	var apiKey models.%s
+	if err := db.Preload(%q).First(&apiKey, "key_hash = ?", HashAPIKey(key)).Error; err != nil || apiKey.%s == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		return
+	}
+	if !KeyAllows(apiKey.Scopes, c.Request.Method) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This API key can only read"})
+		return
+	}
+	db.Model(&apiKey).UpdateColumn("last_used_at", time.Now())
+	c.Set("user", apiKey.%s)
+	c.Set("apiKey", &apiKey)
+	c.Next()
+}
+
+// RequireSignIn refuses requests authenticated by an API key, so a leaked
+// key can't list, create, or revoke keys. It follows RequireAuth.
+func RequireSignIn() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("apiKey"); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API keys can't manage API keys; sign in instead"})
+			return
+		}
+		c.Next()
+	}
+}
+
+`, ir.APIKeyHeader, ir.ScopeWrite, ir.APIKeyModel, toPascalCase(app.Auth.APIKeys.Model), toPascalCase(app.Auth.APIKeys.Model), toPascalCase(app.Auth.APIKeys.Model)))
+}
+
+// generateAPIKeyHandlers produces handlers/api_keys.go: the signed-in
+// user's keys, creating one, and revoking one.
+func generateAPIKeyHandlers(moduleName string, app *ir.Application) string {
+	return fmt.Sprintf(`package handlers
+
+// Generated by Human compiler — do not edit
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"%[1]s/middleware"
+	"%[1]s/models"
+)
+
+// apiKeyScopes are the scopes a key can have: a read key can only make GET
+// requests.
+var apiKeyScopes = []string{%[4]q, %[5]q}
+
+// presentAPIKey is what's shown of a key: never its hash.
+func presentAPIKey(key *models.%[3]s) gin.H {
+	return gin.H{
+		"id":         key.ID,
+		"name":       key.Name,
+		"prefix":     key.Prefix,
+		"scopes":     strings.Split(key.Scopes, ","),
+		"lastUsedAt": key.LastUsedAt,
+		"createdAt":  key.CreatedAt,
+	}
+}
+
+// ListAPIKeys returns the signed-in user's keys, newest first.
+func ListAPIKeys(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.%[2]s)
+		var keys []models.%[3]s
+		if err := db.Where("%[6]s = ?", user.ID).Order("created_at DESC").Find(&keys).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API keys"})
+			return
+		}
+		data := make([]gin.H, len(keys))
+		for i := range keys {
+			data[i] = presentAPIKey(&keys[i])
+		}
+		c.JSON(http.StatusOK, gin.H{"data": data})
+	}
+}
+
+// CreateAPIKey creates a key with the given scopes (read by default); the
+// key itself is in this response only.
+func CreateAPIKey(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.%[2]s)
+		var req struct {
+			Name   string   `+"`json:\"name\"`"+`
+			Scopes []string `+"`json:\"scopes\"`"+`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			return
+		}
+		if req.Scopes == nil {
+			req.Scopes = []string{%[4]q}
+		}
+		var scopes []string
+		for _, scope := range req.Scopes {
+			if !slices.Contains(apiKeyScopes, scope) {
+				scopes = nil
+				break
+			}
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+		if len(scopes) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "scopes must be some of: " + strings.Join(apiKeyScopes, ", ")})
+			return
+		}
+
+		secret := make([]byte, 24)
+		if _, err := rand.Read(secret); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+			return
+		}
+		key := %[7]q + base64.RawURLEncoding.EncodeToString(secret)
+		apiKey := models.%[3]s{
+			Name:    name,
+			Prefix:  key[:%[8]d],
+			KeyHash: middleware.HashAPIKey(key),
+			Scopes:  strings.Join(scopes, ","),
+			%[2]sID:  user.ID,
+		}
+		if err := db.Create(&apiKey).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+			return
+		}
+		data := presentAPIKey(&apiKey)
+		data["key"] = key
+		c.JSON(http.StatusCreated, gin.H{"data": data})
+	}
+}
+
+// RevokeAPIKey deletes one of the signed-in user's keys: calls with it are
+// refused from now on.
+func RevokeAPIKey(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.%[2]s)
+		result := db.Where("id = ? AND %[6]s = ?", c.Param("id"), user.ID).Delete(&models.%[3]s{})
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+			return
+		}
+		if result.RowsAffected == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+`, moduleName, toPascalCase(app.Auth.APIKeys.Model), ir.APIKeyModel, ir.ScopeRead, ir.ScopeWrite,
+		toSnakeCase(app.Auth.APIKeys.Model)+"_id", ir.APIKeyPrefix, len(ir.APIKeyPrefix)+8)
+}
@@ -2,15 +2,19 @@ package gobackend
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/barun-bash/human/internal/ir"
 )
 
 func generateAuth(moduleName string, app *ir.Application) string {
-	return fmt.Sprintf(`package middleware
-
-import (
-	"fmt"
+	var sb strings.Builder
+	keys := ir.IssuesAPIKeys(app)
+	sb.WriteString("package middleware\n\nimport (\n")
+	if keys {
+		sb.WriteString("\t\"crypto/sha256\"\n\t\"encoding/hex\"\n")
+	}
+	sb.WriteString(fmt.Sprintf(`	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -42,9 +46,24 @@ func GenerateToken(userID string, cfg *config.Config) (string, error) {
 	return token.SignedString([]byte(cfg.JWTSecret))
 }
 
-func RequireAuth(db *gorm.DB, cfg *config.Config) gin.HandlerFunc {
+`, moduleName, moduleName))
+
+	if keys {
+		writeAPIKeyAuth(&sb, app)
+	}
+
+	sb.WriteString(`func RequireAuth(db *gorm.DB, cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
+`)
+	if keys {
+		sb.WriteString(`		if key := c.GetHeader(APIKeyHeader); key != "" {
+			authenticateAPIKey(c, db, key)
+			return
+		}
+
+`)
+	}
+	sb.WriteString(`		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing"})
 			return
@@ -91,5 +110,6 @@ func RequireAuth(db *gorm.DB, cfg *config.Config) gin.HandlerFunc {
 		c.Next()
 	}
 }
-`, moduleName, moduleName)
+`)
+	return sb.String()
 }
@@ -0,0 +1,623 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// planCheck returns the action and model an endpoint's plan check is made
+// for, e.g. "create" and "project" for CreateProject, or empty strings when
+// no plan's policy has a say in it.
+func planCheck(api *ir.Endpoint, app *ir.Application) (string, string) {
+	if !ir.Bills(app) || !api.Auth || api.Account != "" {
+		return "", ""
+	}
+	action, model := "", ""
+	for _, verb := range [][2]string{
+		{"Create", "create"}, {"Get", "view"}, {"List", "view"}, {"Fetch", "view"},
+		{"Update", "edit"}, {"Edit", "edit"}, {"Delete", "delete"}, {"Remove", "delete"},
+	} {
+		if strings.HasPrefix(api.Name, verb[0]) && len(api.Name) > len(verb[0]) {
+			action, model = verb[1], goSingularize(strings.ToLower(api.Name[len(verb[0]):]))
+			break
+		}
+	}
+	if action == "" {
+		return "", ""
+	}
+	for _, restricted := range planRestrictions(app) {
+		if restricted[1] == action && (restricted[2] == model || restricted[2] == "*") {
+			return action, model
+		}
+	}
+	if action == "create" {
+		for _, limit := range ir.PlanLimits(app) {
+			if limit.Word == model {
+				return action, model
+			}
+		}
+	}
+	return "", ""
+}
+
+// planRestrictions returns each plan's restricted actions as plan, action,
+// and model, from the policy named after the plan.
+func planRestrictions(app *ir.Application) [][3]string {
+	var restrictions [][3]string
+	for _, plan := range app.Billing.Plans {
+		for _, pol := range app.Policies {
+			if pol.Name != plan.Policy {
+				continue
+			}
+			for _, rest := range pol.Restrictions {
+				r := parsePolicyRule(rest.Text)
+				restrictions = append(restrictions, [3]string{plan.Name, r.action, r.model})
+			}
+		}
+	}
+	return restrictions
+}
+
+// generateBillingService produces services/billing.go: the plan a user is
+// on, and saving a subscription as Stripe's webhook reports it.
+func generateBillingService(moduleName string, app *ir.Application) string {
+	bill := app.Billing
+	apiKeyEnv := "STRIPE_SECRET_KEY"
+	if integ := ir.PaymentIntegration(app); integ != nil {
+		for _, envVar := range integ.Credentials {
+			apiKeyEnv = envVar
+			break
+		}
+	}
+	free := ""
+	if p := bill.FreePlan(); p != nil {
+		free = p.Name
+	}
+	user := toPascalCase(bill.Model)
+
+	return fmt.Sprintf(`package services
+
+// Generated by Human compiler — do not edit
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/stripe/stripe-go/v81"
+	"gorm.io/gorm"
+
+	"%[1]s/models"
+)
+
+func init() {
+	stripe.Key = os.Getenv(%[2]q)
+}
+
+// ActiveStatuses are the subscriptions that keep their plan: past due ones
+// while Stripe retries the payment.
+var ActiveStatuses = []string{"active", "trialing", "past_due"}
+
+// FreePlan is the plan users are on until they subscribe to another; empty
+// when there's none.
+const FreePlan = %[3]q
+
+// LatestSubscription returns a user's most recent subscription, or nil.
+func LatestSubscription(db *gorm.DB, userID string) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := db.Preload("Plan").Where("%[4]s_id = ?", userID).Order("created_at desc").First(&subscription).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// Active reports whether a subscription keeps its plan.
+func Active(subscription *models.Subscription) bool {
+	if subscription == nil {
+		return false
+	}
+	for _, status := range ActiveStatuses {
+		if subscription.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentPlan returns the name of the plan a user is subscribed to, else
+// the free plan.
+func CurrentPlan(db *gorm.DB, userID string) (string, error) {
+	subscription, err := LatestSubscription(db, userID)
+	if err != nil {
+		return "", err
+	}
+	if Active(subscription) && subscription.Plan != nil {
+		return subscription.Plan.Name, nil
+	}
+	return FreePlan, nil
+}
+
+// SaveSubscription saves a subscription as Stripe reports it: its status,
+// its plan, and when it renews. Checkout tags the subscriptions it starts
+// with the user's id; others, e.g. made in the Stripe dashboard, are only
+// kept in step once known.
+func SaveSubscription(db *gorm.DB, sub *stripe.Subscription) error {
+	var plan *models.Plan
+	if sub.Items != nil && len(sub.Items.Data) > 0 && sub.Items.Data[0].Price != nil {
+		var found models.Plan
+		err := db.Where("stripe_price_id = ?", sub.Items.Data[0].Price.ID).First(&found).Error
+		if err == nil {
+			plan = &found
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+
+	var record models.Subscription
+	err := db.Where("stripe_subscription_id = ?", sub.ID).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		userID := sub.Metadata["userId"]
+		if plan == nil || userID == "" {
+			return nil
+		}
+		record = models.Subscription{StripeSubscriptionID: sub.ID, %[5]sID: userID}
+	} else if err != nil {
+		return err
+	}
+
+	record.Status = string(sub.Status)
+	if sub.Customer != nil {
+		record.StripeCustomerID = sub.Customer.ID
+	}
+	periodEnd := time.Unix(sub.CurrentPeriodEnd, 0)
+	record.CurrentPeriodEnd = &periodEnd
+	if plan != nil {
+		record.PlanID = plan.ID
+	}
+	return db.Save(&record).Error
+}
+`, moduleName, apiKeyEnv, free, toSnakeCase(bill.Model), user)
+}
+
+// generateBillingHandlers produces handlers/billing.go: the signed-in
+// user's subscription, starting Stripe Checkout for a paid plan, opening
+// the billing portal to change plan, update the card, or cancel, and the
+// webhook Stripe reports subscription changes to.
+func generateBillingHandlers(moduleName string, app *ir.Application) string {
+	bill := app.Billing
+	email := ""
+	for _, m := range app.Data {
+		if m.Name != bill.Model {
+			continue
+		}
+		if f := m.FieldNamed("email"); f != nil {
+			if f.Required {
+				email = "\t\t} else {\n\t\t\tparams.CustomerEmail = stripe.String(user.Email)\n"
+			} else {
+				email = "\t\t} else {\n\t\t\tparams.CustomerEmail = user.Email\n"
+			}
+		}
+	}
+
+	return fmt.Sprintf(`package handlers
+
+// Generated by Human compiler — do not edit
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stripe/stripe-go/v81"
+	portalsession "github.com/stripe/stripe-go/v81/billingportal/session"
+	"github.com/stripe/stripe-go/v81/checkout/session"
+	"github.com/stripe/stripe-go/v81/webhook"
+	"gorm.io/gorm"
+
+	"%[1]s/models"
+	"%[1]s/services"
+)
+
+// pricingURL returns the pricing page, which Checkout and the billing
+// portal return to.
+func pricingURL(c *gin.Context) string {
+	base := os.Getenv("SITE_URL")
+	if base == "" {
+		base = c.GetHeader("Origin")
+	}
+	return strings.TrimSuffix(base, "/") + %[2]q
+}
+
+// BillingSubscription returns the signed-in user's plan and latest subscription.
+func BillingSubscription(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.User)
+		plan, err := services.CurrentPlan(db, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subscription"})
+			return
+		}
+		subscription, err := services.LatestSubscription(db, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subscription"})
+			return
+		}
+		var current any
+		if plan != "" {
+			current = plan
+		}
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"plan": current, "subscription": subscription}})
+	}
+}
+
+// BillingCheckout starts Stripe Checkout for a paid plan.
+func BillingCheckout(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.User)
+		var req struct {
+			Plan string `+"`json:\"plan\"`"+`
+		}
+		_ = c.ShouldBindJSON(&req)
+
+		var plan models.Plan
+		if err := db.Where("name = ?", req.Plan).First(&plan).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Plan not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plan"})
+			return
+		}
+		if plan.StripePriceID == nil || *plan.StripePriceID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The " + plan.Name + " plan can't be bought online"})
+			return
+		}
+		latest, err := services.LatestSubscription(db, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subscription"})
+			return
+		}
+		if services.Active(latest) {
+			c.JSON(http.StatusConflict, gin.H{"error": "You already have a subscription: change your plan from the billing portal"})
+			return
+		}
+
+		params := &stripe.CheckoutSessionParams{
+			Mode:              stripe.String(string(stripe.CheckoutSessionModeSubscription)),
+			LineItems:         []*stripe.CheckoutSessionLineItemParams{{Price: plan.StripePriceID, Quantity: stripe.Int64(1)}},
+			ClientReferenceID: stripe.String(user.ID),
+			SubscriptionData: &stripe.CheckoutSessionSubscriptionDataParams{
+				Metadata: map[string]string{"userId": user.ID},
+			},
+			SuccessURL: stripe.String(pricingURL(c) + "?checkout=success"),
+			CancelURL:  stripe.String(pricingURL(c)),
+		}
+		if latest != nil {
+			params.Customer = stripe.String(latest.StripeCustomerID)
+%[3]s		}
+		s, err := session.New(params)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to start checkout"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"url": s.URL}})
+	}
+}
+
+// BillingPortal opens Stripe's billing portal, where the signed-in user
+// changes plan, updates their card, or cancels.
+func BillingPortal(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.User)
+		latest, err := services.LatestSubscription(db, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subscription"})
+			return
+		}
+		if latest == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "You have no subscription to manage"})
+			return
+		}
+		s, err := portalsession.New(&stripe.BillingPortalSessionParams{
+			Customer:  stripe.String(latest.StripeCustomerID),
+			ReturnURL: stripe.String(pricingURL(c)),
+		})
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to open the billing portal"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"url": s.URL}})
+	}
+}
+
+// BillingWebhook saves the subscriptions Stripe reports starting, changing
+// plan, renewing, and ending.
+func BillingWebhook(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read body"})
+			return
+		}
+		event, err := webhook.ConstructEvent(body, c.GetHeader("Stripe-Signature"), os.Getenv("STRIPE_WEBHOOK_SECRET"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook verification failed"})
+			return
+		}
+		switch event.Type {
+		case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+			var sub stripe.Subscription
+			if err := json.Unmarshal(event.Data.Raw, &sub); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subscription"})
+				return
+			}
+			if err := services.SaveSubscription(db, &sub); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save subscription"})
+				return
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"received": true})
+	}
+}
+`, moduleName, bill.PagePath(), email)
+}
+
+// generatePlanMiddleware produces middleware/plans.go: RequirePlan(), which
+// checks the signed-in user's plan against the policy named after it.
+// Restricted actions and creations past the plan's limit are refused with
+// 402 Payment Required, so the frontend can offer an upgrade.
+func generatePlanMiddleware(moduleName string, app *ir.Application) string {
+	var sb strings.Builder
+	fk := toSnakeCase(app.Billing.Model) + "_id"
+
+	sb.WriteString(fmt.Sprintf(`package middleware
+
+// Generated by Human compiler — do not edit
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"%[1]s/models"
+	"%[1]s/services"
+)
+
+// planRule is a plan's restriction, or its limit on creating a model.
+type planRule struct {
+	Plan   string
+	Action string
+	Model  string
+	Limit  int
+	Period string
+}
+
+// planRestrictions are what each plan's subscribers can't do.
+var planRestrictions = []planRule{
+`, moduleName))
+	for _, r := range planRestrictions(app) {
+		sb.WriteString(fmt.Sprintf("\t{Plan: %q, Action: %q, Model: %q},\n", r[0], r[1], r[2]))
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// planLimits are how many records each plan's subscribers can create.\n")
+	sb.WriteString("var planLimits = []planRule{\n")
+	for _, limit := range ir.PlanLimits(app) {
+		if !limit.Counted(app) {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\t{Plan: %q, Action: \"create\", Model: %q, Limit: %d", limit.Plan, limit.Word, limit.Limit))
+		if limit.Period != "" {
+			sb.WriteString(fmt.Sprintf(", Period: %q", limit.Period))
+		}
+		sb.WriteString("},\n")
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// planModels are the records each limited model counts.\n")
+	sb.WriteString("var planModels = map[string]any{\n")
+	seen := map[string]bool{}
+	for _, limit := range ir.PlanLimits(app) {
+		if !limit.Counted(app) || seen[limit.Word] {
+			continue
+		}
+		seen[limit.Word] = true
+		sb.WriteString(fmt.Sprintf("\t%q: &models.%s{},\n", limit.Word, toPascalCase(limit.Model)))
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(fmt.Sprintf(`// periodStart returns the start of the day, week, month, or year a limit
+// counts from; the zero time counts all time.
+func periodStart(period string) time.Time {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch period {
+	case "day":
+		return today
+	case "week":
+		return today.AddDate(0, 0, -int(today.Weekday()))
+	case "month":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	case "year":
+		return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+	}
+	return time.Time{}
+}
+
+// RequirePlan returns a Gin middleware that checks the signed-in user's
+// plan against its policy.
+//
+// Usage:
+//
+//	api.POST("/projects", middleware.RequireAuth(db, cfg), middleware.RequirePlan(db, "create", "project"), handlers.CreateProject(db, cfg))
+//
+// Behavior:
+//  1. If a restriction matches the action+model → %[1]d
+//  2. If a limit on creating the model has been reached → %[1]d
+//  3. Otherwise → allowed
+func RequirePlan(db *gorm.DB, action string, model string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.User)
+		plan, err := services.CurrentPlan(db, user.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plan"})
+			return
+		}
+		if plan == "" {
+			c.AbortWithStatusJSON(http.StatusPaymentRequired, gin.H{"error": fmt.Sprintf("Subscribe to a plan to %%s %%s", action, model)})
+			return
+		}
+		for _, r := range planRestrictions {
+			if r.Plan == plan && r.Action == action && (r.Model == model || r.Model == "*") {
+				c.AbortWithStatusJSON(http.StatusPaymentRequired, gin.H{"error": fmt.Sprintf("The %%s plan can't %%s %%s", plan, action, model)})
+				return
+			}
+		}
+		counted, ok := planModels[model]
+		for _, r := range planLimits {
+			if !ok || r.Plan != plan || r.Action != action || r.Model != model {
+				continue
+			}
+			var count int64
+			query := db.Model(counted).Where("%[2]s = ?", user.ID)
+			if since := periodStart(r.Period); !since.IsZero() {
+				query = query.Where("created_at >= ?", since)
+			}
+			if err := query.Count(&count).Error; err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check plan limit"})
+				return
+			}
+			if count >= int64(r.Limit) {
+				per := ""
+				if r.Period != "" {
+					per = " per " + r.Period
+				}
+				c.AbortWithStatusJSON(http.StatusPaymentRequired, gin.H{
+					"error": fmt.Sprintf("The %%s plan's limit is %%d%%s: upgrade to %%s another %%s", plan, r.Limit, per, action, model),
+				})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+`, ir.PlanUpgradeStatus, fk))
+	return sb.String()
+}
+
+// generatePlanSync produces cmd/sync-plans/main.go, run with
+// `go run ./cmd/sync-plans`: it creates each paid plan's Stripe price and
+// stores the plans with their prices, which checkout and the webhook look up.
+func generatePlanSync(moduleName string, app *ir.Application) string {
+	var sb strings.Builder
+	bill := app.Billing
+	sb.WriteString(fmt.Sprintf(`// Command sync-plans creates each paid plan's Stripe product and price,
+// and stores the plans with their prices. Run after changing the plans
+// block:
+//
+//	go run ./cmd/sync-plans
+//
+// Stripe prices can't change, so a new price is created under a new lookup
+// key; existing subscribers stay on the old one until they change plan.
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/price"
+
+	"%[1]s/config"
+	"%[1]s/database"
+	"%[1]s/models"
+	_ "%[1]s/services" // sets the Stripe API key
+)
+
+var plans = []struct {
+	Name     string
+	Price    int
+	Interval string
+}{
+`, moduleName))
+	for _, plan := range bill.Plans {
+		interval := ""
+		if plan.Paid() {
+			interval = plan.Interval
+		}
+		sb.WriteString(fmt.Sprintf("\t{Name: %q, Price: %d, Interval: %q},\n", plan.Name, plan.Price, interval))
+	}
+	sb.WriteString(fmt.Sprintf(`}
+
+var nonWord = regexp.MustCompile(`+"`\\W+`"+`)
+
+func main() {
+	db, err := database.Connect(config.Load())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for position, plan := range plans {
+		var stripePriceID *string
+		if plan.Price > 0 && plan.Interval != "" {
+			lookupKey := fmt.Sprintf("%%s_%%d_%%s", nonWord.ReplaceAllString(strings.ToLower(plan.Name), "_"), plan.Price, plan.Interval)
+			list := price.List(&stripe.PriceListParams{LookupKeys: stripe.StringSlice([]string{lookupKey})})
+			var p *stripe.Price
+			if list.Next() {
+				p = list.Price()
+			} else if err := list.Err(); err != nil {
+				log.Fatalf("%%s: %%v", plan.Name, err)
+			} else {
+				p, err = price.New(&stripe.PriceParams{
+					Currency:    stripe.String(%[1]q),
+					UnitAmount:  stripe.Int64(int64(plan.Price)),
+					Recurring:   &stripe.PriceRecurringParams{Interval: stripe.String(plan.Interval)},
+					LookupKey:   stripe.String(lookupKey),
+					ProductData: &stripe.PriceProductDataParams{Name: stripe.String(plan.Name)},
+				})
+				if err != nil {
+					log.Fatalf("%%s: %%v", plan.Name, err)
+				}
+			}
+			stripePriceID = &p.ID
+		}
+
+		var record models.Plan
+		db.Where("name = ?", plan.Name).Limit(1).Find(&record)
+		record.Name = plan.Name
+		record.Price = plan.Price
+		record.Position = position
+		record.StripePriceID = stripePriceID
+		record.Interval = nil
+		if plan.Interval != "" {
+			record.Interval = &plan.Interval
+		}
+		if err := db.Save(&record).Error; err != nil {
+			log.Fatalf("%%s: %%v", plan.Name, err)
+		}
+
+		sold := "not sold online"
+		if stripePriceID != nil {
+			sold = *stripePriceID
+		}
+		fmt.Printf("%%s: %%s\n", plan.Name, sold)
+	}
+}
+`, bill.Currency))
+	return sb.String()
+}
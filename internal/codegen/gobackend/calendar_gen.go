@@ -0,0 +1,336 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateCalendarService produces services/calendar.go: an iCalendar
+// (RFC 5545) writer. Times are written in UTC, all-day events as dates,
+// and text is escaped and folded at 75 octets.
+func generateCalendarService(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n")
+	b.WriteString("package services\n\n")
+	b.WriteString("import (\n")
+	b.WriteString("\t\"strings\"\n")
+	b.WriteString("\t\"time\"\n")
+	b.WriteString(")\n\n")
+	fmt.Fprintf(&b, "const calendarProdID = %q\n\n", "-//Human//"+app.Name+"//EN")
+
+	b.WriteString(`// CalendarEvent is one VEVENT. Without an End, timed events last an hour
+// and all-day events a day.
+type CalendarEvent struct {
+	UID         string
+	Title       string
+	Start       time.Time
+	End         *time.Time
+	AllDay      bool
+	Location    string
+	Description string
+}
+
+var icsEscaper = strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\r\n", "\\n", "\n", "\\n")
+
+// EscapeText escapes a TEXT value: backslashes, semicolons, commas, and
+// newlines.
+func EscapeText(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// Fold splits a content line so no line exceeds 75 octets; continuation
+// lines start with a space. Multi-byte characters are never split.
+func Fold(line string) string {
+	var b strings.Builder
+	size := 0
+	for _, r := range line {
+		n := len(string(r))
+		if size+n > 75 {
+			b.WriteString("\r\n ")
+			size = 1
+		}
+		b.WriteRune(r)
+		size += n
+	}
+	return b.String()
+}
+
+func eventLines(e CalendarEvent, stamp string) []string {
+	lines := []string{"BEGIN:VEVENT", "UID:" + e.UID, "DTSTAMP:" + stamp}
+	if e.AllDay {
+		// DTEND is exclusive, so a one-day event ends the next day.
+		end := e.Start
+		if e.End != nil {
+			end = *e.End
+		}
+		lines = append(lines,
+			"DTSTART;VALUE=DATE:"+e.Start.Format("20060102"),
+			"DTEND;VALUE=DATE:"+end.AddDate(0, 0, 1).Format("20060102"))
+	} else {
+		end := e.Start.Add(time.Hour)
+		if e.End != nil {
+			end = *e.End
+		}
+		lines = append(lines,
+			"DTSTART:"+e.Start.UTC().Format("20060102T150405Z"),
+			"DTEND:"+end.UTC().Format("20060102T150405Z"))
+	}
+	lines = append(lines, "SUMMARY:"+EscapeText(e.Title))
+	if e.Location != "" {
+		lines = append(lines, "LOCATION:"+EscapeText(e.Location))
+	}
+	if e.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+EscapeText(e.Description))
+	}
+	return append(lines, "END:VEVENT")
+}
+
+// ToICS renders events as an iCalendar document with CRLF line endings.
+func ToICS(name, timezone string, events []CalendarEvent) string {
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + calendarProdID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + EscapeText(name),
+		"X-WR-TIMEZONE:" + timezone,
+	}
+	for _, e := range events {
+		lines = append(lines, eventLines(e, stamp)...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(Fold(line))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+`)
+
+	return b.String()
+}
+
+// generateCalendarTests produces services/calendar_test.go, which checks
+// the iCalendar writer's output format.
+func generateCalendarTests() string {
+	return `// Generated by Human compiler — do not edit
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToICSTimedEvent(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)
+	ics := ToICS("Events", "UTC", []CalendarEvent{{UID: "event-1@test", Title: "Launch", Start: start}})
+
+	for _, want := range []string{"DTSTART:20260301T183000Z\r\n", "DTEND:20260301T193000Z\r\n"} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expected %q in:\n%s", want, ics)
+		}
+	}
+	if !strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(ics, "END:VCALENDAR\r\n") {
+		t.Errorf("expected a CRLF-delimited VCALENDAR, got:\n%s", ics)
+	}
+}
+
+func TestToICSConvertsToUTC(t *testing.T) {
+	newYork := time.FixedZone("EST", -5*60*60)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, newYork)
+	ics := ToICS("Events", "America/New_York", []CalendarEvent{{UID: "event-1@test", Title: "Launch", Start: start}})
+	if !strings.Contains(ics, "DTSTART:20260301T140000Z\r\n") {
+		t.Errorf("expected the start in UTC, got:\n%s", ics)
+	}
+}
+
+func TestToICSAllDayEvent(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	ics := ToICS("Events", "UTC", []CalendarEvent{{UID: "event-1@test", Title: "Holiday", Start: start, AllDay: true}})
+	for _, want := range []string{"DTSTART;VALUE=DATE:20260301\r\n", "DTEND;VALUE=DATE:20260302\r\n"} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expected %q in:\n%s", want, ics)
+		}
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	if got := EscapeText("a, b; c\\d\ne"); got != "a\\, b\\; c\\\\d\\ne" {
+		t.Errorf("EscapeText = %q", got)
+	}
+}
+
+func TestFold(t *testing.T) {
+	lines := strings.Split(Fold("DESCRIPTION:"+strings.Repeat("x", 200)), "\r\n")
+	if len(lines) < 2 {
+		t.Fatal("expected the line to be folded")
+	}
+	for i, line := range lines {
+		if len(line) > 75 {
+			t.Errorf("line %d is %d octets", i, len(line))
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("continuation line %d does not start with a space", i)
+		}
+	}
+}
+`
+}
+
+// generateCalendarHandlers produces handlers/calendar.go: for each
+// calendar, an ICS feed of every record and a single record's .ics
+// download. The feeds are public so calendar apps can subscribe to them.
+func generateCalendarHandlers(moduleName string, app *ir.Application) string {
+	var sb strings.Builder
+
+	sb.WriteString("package handlers\n\n")
+	sb.WriteString("import (\n")
+	sb.WriteString("\t\"fmt\"\n")
+	sb.WriteString("\t\"net/http\"\n")
+	sb.WriteString("\t\"strings\"\n\n")
+	sb.WriteString("\t\"github.com/gin-gonic/gin\"\n")
+	sb.WriteString("\t\"gorm.io/gorm\"\n\n")
+	fmt.Fprintf(&sb, "\t\"%s/models\"\n", moduleName)
+	fmt.Fprintf(&sb, "\t\"%s/services\"\n", moduleName)
+	sb.WriteString(")\n\n")
+
+	sb.WriteString(`func sendICS(c *gin.Context, ics, filename string) {
+	if filename != "" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(ics))
+}
+`)
+
+	uidDomain := strings.ToLower(strings.ReplaceAll(app.Name, " ", "-"))
+	for _, cal := range app.Calendars {
+		model := toPascalCase(cal.Model)
+		fields := modelFieldSet(app, cal.Model)
+		fn := toCamelCase(cal.Model) + "CalendarEvent"
+		prefix := strings.ToLower(model)
+
+		// Optional fields are pointers on the model; encrypted ones are
+		// fieldcrypt.Text.
+		text := func(field string) string {
+			fi := fields[strings.ToLower(field)]
+			switch {
+			case fi.required && fi.encrypted:
+				return fmt.Sprintf("string(r.%s)", toPascalCase(field))
+			case fi.required:
+				return "r." + toPascalCase(field)
+			case fi.encrypted:
+				return fmt.Sprintf("derefString((*string)(r.%s))", toPascalCase(field))
+			}
+			return fmt.Sprintf("derefString(r.%s)", toPascalCase(field))
+		}
+
+		fmt.Fprintf(&sb, "\n// %s converts a %s record; ok is false when it has no %s.\n", fn, cal.Model, cal.Start)
+		fmt.Fprintf(&sb, "func %s(r models.%s) (event services.CalendarEvent, ok bool) {\n", fn, model)
+		start := "r." + toPascalCase(cal.Start)
+		if !fields[strings.ToLower(cal.Start)].required {
+			fmt.Fprintf(&sb, "\tif %s == nil {\n", start)
+			sb.WriteString("\t\treturn event, false\n")
+			sb.WriteString("\t}\n")
+			start = "*" + start
+		}
+		sb.WriteString("\tevent = services.CalendarEvent{\n")
+		fmt.Fprintf(&sb, "\t\tUID: fmt.Sprintf(\"%s-%%s@%s\", r.ID),\n", prefix, uidDomain)
+		if cal.Title != "" {
+			fmt.Fprintf(&sb, "\t\tTitle: %s,\n", text(cal.Title))
+		} else {
+			fmt.Fprintf(&sb, "\t\tTitle: %q,\n", cal.Model)
+		}
+		fmt.Fprintf(&sb, "\t\tStart: %s,\n", start)
+		if cal.AllDay {
+			sb.WriteString("\t\tAllDay: true,\n")
+		}
+		if cal.Location != "" {
+			fmt.Fprintf(&sb, "\t\tLocation: %s,\n", text(cal.Location))
+		}
+		if cal.Description != "" {
+			fmt.Fprintf(&sb, "\t\tDescription: %s,\n", text(cal.Description))
+		}
+		sb.WriteString("\t}\n")
+		if cal.End != "" {
+			end := "r." + toPascalCase(cal.End)
+			if fields[strings.ToLower(cal.End)].required {
+				fmt.Fprintf(&sb, "\tevent.End = &%s\n", end)
+			} else {
+				fmt.Fprintf(&sb, "\tevent.End = %s\n", end)
+			}
+		}
+		sb.WriteString("\treturn event, true\n")
+		sb.WriteString("}\n")
+
+		fmt.Fprintf(&sb, "\n// %sCalendarFeed serves every %s as an ICS feed.\n", model, cal.Model)
+		fmt.Fprintf(&sb, "func %sCalendarFeed(db *gorm.DB) gin.HandlerFunc {\n", model)
+		sb.WriteString("\treturn func(c *gin.Context) {\n")
+		fmt.Fprintf(&sb, "\t\tvar records []models.%s\n", model)
+		sb.WriteString("\t\tif err := db.Find(&records).Error; err != nil {\n")
+		fmt.Fprintf(&sb, "\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to fetch %s\"})\n", strings.ToLower(cal.Name()))
+		sb.WriteString("\t\t\treturn\n")
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t\tevents := make([]services.CalendarEvent, 0, len(records))\n")
+		sb.WriteString("\t\tfor _, r := range records {\n")
+		fmt.Fprintf(&sb, "\t\t\tif event, ok := %s(r); ok {\n", fn)
+		sb.WriteString("\t\t\t\tevents = append(events, event)\n")
+		sb.WriteString("\t\t\t}\n")
+		sb.WriteString("\t\t}\n")
+		fmt.Fprintf(&sb, "\t\tsendICS(c, services.ToICS(%q, %q, events), \"\")\n", cal.Name(), cal.Timezone)
+		sb.WriteString("\t}\n")
+		sb.WriteString("}\n")
+
+		fmt.Fprintf(&sb, "\n// %sCalendarDownload serves one %s as an .ics download.\n", model, cal.Model)
+		fmt.Fprintf(&sb, "func %sCalendarDownload(db *gorm.DB) gin.HandlerFunc {\n", model)
+		sb.WriteString("\treturn func(c *gin.Context) {\n")
+		sb.WriteString("\t\tid := strings.TrimSuffix(c.Param(\"file\"), \".ics\")\n")
+		fmt.Fprintf(&sb, "\t\tvar record models.%s\n", model)
+		sb.WriteString("\t\tif err := db.First(&record, \"id = ?\", id).Error; err != nil {\n")
+		fmt.Fprintf(&sb, "\t\t\tc.JSON(http.StatusNotFound, gin.H{\"error\": \"%s not found\"})\n", cal.Model)
+		sb.WriteString("\t\t\treturn\n")
+		sb.WriteString("\t\t}\n")
+		fmt.Fprintf(&sb, "\t\tevent, ok := %s(record)\n", fn)
+		sb.WriteString("\t\tif !ok {\n")
+		fmt.Fprintf(&sb, "\t\t\tc.JSON(http.StatusNotFound, gin.H{\"error\": \"%s not found\"})\n", cal.Model)
+		sb.WriteString("\t\t\treturn\n")
+		sb.WriteString("\t\t}\n")
+		fmt.Fprintf(&sb, "\t\tsendICS(c, services.ToICS(%q, %q, []services.CalendarEvent{event}), \"%s-\"+record.ID+\".ics\")\n", cal.Name(), cal.Timezone, prefix)
+		sb.WriteString("\t}\n")
+		sb.WriteString("}\n")
+	}
+
+	if calendarUsesDeref(app) {
+		sb.WriteString(`
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+`)
+	}
+
+	return sb.String()
+}
+
+// calendarUsesDeref reports whether a calendar reads an optional text field,
+// which the handlers dereference with derefString().
+func calendarUsesDeref(app *ir.Application) bool {
+	for _, cal := range app.Calendars {
+		fields := modelFieldSet(app, cal.Model)
+		for _, f := range []string{cal.Title, cal.Location, cal.Description} {
+			if f != "" && !fields[strings.ToLower(f)].required {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateChaos produces middleware/chaos.go: with chaos mode on (`human
+// run --chaos`), some API requests fail the way the error handlers are
+// declared for, so their retries, fallbacks, and alerts can be watched at
+// work. It does nothing otherwise.
+func generateChaos(app *ir.Application) string {
+	var faults strings.Builder
+	for _, f := range ir.ChaosFaults(app) {
+		faults.WriteString("\t{\n")
+		fmt.Fprintf(&faults, "\t\tCondition: %q,\n", f.Condition)
+		fmt.Fprintf(&faults, "\t\tAttempts:  %d,\n", f.Attempts)
+		fmt.Fprintf(&faults, "\t\tDelay:     %d * time.Millisecond,\n", f.DelayMs)
+		fmt.Fprintf(&faults, "\t\tStatus:    %d,\n", f.Status)
+		fmt.Fprintf(&faults, "\t\tMessage:   %q,\n", f.Message)
+		if f.Alert != "" {
+			fmt.Fprintf(&faults, "\t\tAlert:     %q,\n", f.Alert)
+		}
+		faults.WriteString("\t},\n")
+	}
+
+	return fmt.Sprintf(`package middleware
+
+// Generated by Human compiler — do not edit
+
+import (
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type chaosFault struct {
+	Condition string
+	Attempts  int
+	Delay     time.Duration
+	Status    int
+	Message   string
+	Alert     string
+}
+
+// chaosFaults are the failure each error handler in the .human file is
+// declared for.
+var chaosFaults = []chaosFault{
+%[4]s}
+
+// Chaos injects the error handlers' failures into API requests when
+// %[1]s=1, and does nothing otherwise. It hits a share of requests
+// (%[2]s, 0.1 by default), adding up to %[3]s of latency
+// (2000 by default) and making one handler's failure happen some attempts
+// in a row. A request with an attempt succeeding within the handler's
+// attempts goes through; the rest are answered with the handler's
+// response, and its alert is logged. Responses chaos mode answered carry
+// an X-Chaos header naming the failure.
+func Chaos() gin.HandlerFunc {
+	if v := os.Getenv(%[1]q); v != "1" && v != "true" {
+		return func(c *gin.Context) { c.Next() }
+	}
+	rate, err := strconv.ParseFloat(os.Getenv(%[2]q), 64)
+	if err != nil {
+		rate = 0.1
+	}
+	maxLatency, err := strconv.Atoi(os.Getenv(%[3]q))
+	if err != nil {
+		maxLatency = 2000
+	}
+	log.Printf("[chaos] Injecting failures into %%.0f%%%% of API requests", rate*100)
+
+	return func(c *gin.Context) {
+		if rand.Float64() >= rate {
+			c.Next()
+			return
+		}
+		fault := chaosFaults[rand.Intn(len(chaosFaults))]
+		failures := 1 + rand.Intn(fault.Attempts)
+		time.Sleep(time.Duration(rand.Float64()*float64(maxLatency))*time.Millisecond +
+			time.Duration(min(failures, fault.Attempts-1))*fault.Delay)
+
+		request := c.Request.Method + " " + c.Request.URL.Path
+		if failures < fault.Attempts {
+			log.Printf("[chaos] %%s: %%s, recovered on attempt %%d of %%d", request, fault.Condition, failures+1, fault.Attempts)
+			c.Next()
+			return
+		}
+		log.Printf("[chaos] %%s: %%s, failed %%d of %%d attempts → %%d", request, fault.Condition, fault.Attempts, fault.Attempts, fault.Status)
+		if fault.Alert != "" {
+			log.Printf("[chaos] %%s", fault.Alert)
+		}
+		c.Header("X-Chaos", fault.Condition)
+		c.AbortWithStatusJSON(fault.Status, gin.H{"error": fault.Message})
+	}
+}
+`, ir.ChaosEnv, ir.ChaosRateEnv, ir.ChaosLatencyEnv, faults.String())
+}
@@ -0,0 +1,140 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// chartHandler returns the handler serving a chart's points:
+// "signups-per-week" → "ChartSignupsPerWeek".
+func chartHandler(c *ir.Chart) string {
+	return "Chart" + toPascalCase(c.Slug)
+}
+
+// tableName returns the table GORM keeps a model's records in:
+// "TeamMember" → "team_members".
+func tableName(model string) string {
+	return toSnakeCase(pluralize(toPascalCase(model)))
+}
+
+// hasAggregates reports whether any chart or report endpoint needs the
+// shared aggregate helpers.
+func hasAggregates(app *ir.Application) bool {
+	if len(app.Charts) > 0 {
+		return true
+	}
+	for _, api := range app.APIs {
+		if api.Aggregate != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// generateAggregateHelpers produces handlers/aggregates.go, shared by chart
+// handlers and report endpoints.
+func generateAggregateHelpers() string {
+	return `package handlers
+
+import "time"
+
+// AggregateRow is one group of a chart or report: its label and the count,
+// sum, average, minimum, or maximum of its records.
+type AggregateRow struct {
+	Label string  ` + "`json:\"label\"`" + `
+	Value float64 ` + "`json:\"value\"`" + `
+}
+
+// aggregateSince returns the start of the last n days.
+func aggregateSince(days int) time.Time {
+	return time.Now().AddDate(0, 0, -days)
+}
+`
+}
+
+// generateChartHandlers produces handlers/charts.go: for each chart, a
+// handler returning {"data": [{"label", "value"}]}. Points for a model that
+// belongs to a user are limited to the signed-in user's records.
+func generateChartHandlers(moduleName string, app *ir.Application) string {
+	var sb strings.Builder
+
+	sb.WriteString("package handlers\n\n")
+	sb.WriteString("import (\n")
+	sb.WriteString("\t\"net/http\"\n\n")
+	sb.WriteString("\t\"github.com/gin-gonic/gin\"\n")
+	sb.WriteString("\t\"gorm.io/gorm\"\n\n")
+	fmt.Fprintf(&sb, "\t\"%s/models\"\n", moduleName)
+	sb.WriteString(")\n")
+
+	for _, c := range app.Charts {
+		scoped := app.Auth != nil && modelBelongsToUser(c.Model, app) && !strings.EqualFold(c.Model, "User")
+		fmt.Fprintf(&sb, "\n// %s serves the %s chart.\n", chartHandler(c), strings.ToLower(c.Title[:1])+c.Title[1:])
+		fmt.Fprintf(&sb, "func %s(db *gorm.DB) gin.HandlerFunc {\n", chartHandler(c))
+		sb.WriteString("\treturn func(c *gin.Context) {\n")
+		writeAggregateQuery(&sb, &c.Aggregate, scoped)
+		sb.WriteString("\t}\n")
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
+
+// writeAggregateQuery emits the GROUP BY query computing an aggregate's
+// rows and the JSON response carrying them. Time series are bucketed with
+// date_trunc.
+func writeAggregateQuery(sb *strings.Builder, agg *ir.Aggregate, scoped bool) {
+	table := tableName(agg.Model)
+	column := func(field string) string { return table + "." + toSnakeCase(toPascalCase(field)) }
+
+	value := "COUNT(*)"
+	if agg.Func != "count" {
+		value = fmt.Sprintf("COALESCE(%s(%s), 0)", strings.ToUpper(agg.Func), column(agg.Field))
+	}
+	date := table + ".created_at"
+	if agg.Date != "" {
+		date = column(agg.Date)
+	}
+	var label, join, order string
+	switch {
+	case agg.Period != "":
+		pattern := map[string]string{"day": "YYYY-MM-DD", "week": "YYYY-MM-DD", "month": "YYYY-MM", "year": "YYYY"}[agg.Period]
+		label, order = fmt.Sprintf("to_char(date_trunc('%s', %s), '%s')", agg.Period, date, pattern), "label"
+	case agg.Relation != "" && agg.Label != "":
+		related := tableName(agg.Relation)
+		label = fmt.Sprintf("COALESCE(CAST(%s.%s AS TEXT), 'None')", related, toSnakeCase(toPascalCase(agg.Label)))
+		join = fmt.Sprintf("LEFT JOIN %s ON %s.id = %s", related, related, table+"."+toSnakeCase(toPascalCase(agg.Relation))+"_id")
+		order = "value DESC"
+	case agg.Relation != "":
+		label, order = table+"."+toSnakeCase(toPascalCase(agg.Relation))+"_id", "value DESC"
+	default:
+		label, order = fmt.Sprintf("COALESCE(CAST(%s AS TEXT), 'None')", column(agg.GroupBy)), "value DESC"
+	}
+
+	sb.WriteString("\t\trows := []AggregateRow{}\n")
+	fmt.Fprintf(sb, "\t\tquery := db.Model(&models.%s{}).Select(%q)\n", toPascalCase(agg.Model), label+" AS label, "+value+" AS value")
+	if join != "" {
+		fmt.Fprintf(sb, "\t\tquery = query.Joins(%q)\n", join)
+	}
+	if scoped {
+		fmt.Fprintf(sb, "\t\tquery = query.Where(\"%s.user_id = ?\", c.MustGet(\"user\").(*models.User).ID)\n", table)
+	}
+	if agg.Days > 0 {
+		fmt.Fprintf(sb, "\t\tquery = query.Where(\"%s >= ?\", aggregateSince(%d))\n", date, agg.Days)
+	}
+	fmt.Fprintf(sb, "\t\tif err := query.Group(\"label\").Order(%q).Scan(&rows).Error; err != nil {\n", order)
+	sb.WriteString("\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to load aggregate\"})\n")
+	sb.WriteString("\t\t\treturn\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": rows})\n")
+}
+
+// cacheControl returns the Cache-Control header of a report endpoint:
+// private when the rows depend on who asks.
+func cacheControl(api *ir.Endpoint) string {
+	if api.Auth {
+		return fmt.Sprintf("private, max-age=%d", api.Cache)
+	}
+	return fmt.Sprintf("public, max-age=%d", api.Cache)
+}
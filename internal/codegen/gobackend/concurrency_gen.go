@@ -0,0 +1,81 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateConcurrencyHelpers produces handlers/concurrency.go, reading the
+// version of a record an update was made to.
+func generateConcurrencyHelpers() string {
+	return `package handlers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// expectedVersion returns the version of the record an update was made to:
+// the If-Match header's ("3" or W/"3"), else the body's version field.
+func expectedVersion(ifMatch string, version *int) (int, bool) {
+	if ifMatch != "" {
+		n, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), "\""))
+		return n, err == nil && n > 0
+	}
+	if version != nil && *version > 0 {
+		return *version, true
+	}
+	return 0, false
+}
+`
+}
+
+// writeVersionedUpdate writes the update of a record protected against
+// conflicting edits. In one transaction it bumps the record's version only
+// if it is still the one the client edited, then saves the changes; when
+// the version moved on, someone else saved first, and the client gets 409
+// Conflict with the record as it is now.
+func writeVersionedUpdate(sb *strings.Builder, m *ir.DataModel) {
+	label := strings.ToLower(ir.FieldLabel(m.Name))
+	col := ir.VersionColumn
+	sb.WriteString("\t\texpected, ok := expectedVersion(c.GetHeader(\"If-Match\"), req.Version)\n")
+	sb.WriteString("\t\tif !ok {\n")
+	fmt.Fprintf(sb, "\t\t\tc.JSON(http.StatusPreconditionRequired, gin.H{\"error\": \"Send the version of the %s you edited in an If-Match header\"})\n", label)
+	sb.WriteString("\t\t\treturn\n\t\t}\n")
+	sb.WriteString("\t\treq.Version = nil\n")
+	sb.WriteString("\t\tconflict := false\n")
+	sb.WriteString("\t\ttxErr := db.Transaction(func(tx *gorm.DB) error {\n")
+	fmt.Fprintf(sb, "\t\t\tbumped := tx.Model(&item).Where(\"%s = ?\", expected).UpdateColumn(\"%s\", gorm.Expr(\"%s + 1\"))\n", col, col, col)
+	sb.WriteString("\t\t\tif bumped.Error != nil || bumped.RowsAffected == 0 {\n")
+	sb.WriteString("\t\t\t\tconflict = bumped.Error == nil\n")
+	sb.WriteString("\t\t\t\treturn bumped.Error\n")
+	sb.WriteString("\t\t\t}\n")
+	sb.WriteString("\t\t\treturn tx.Model(&item).Updates(req).Error\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\tif txErr != nil {\n\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to update\"})\n\t\t\treturn\n\t\t}\n")
+	sb.WriteString("\t\tif err := db.First(&item, \"id = ?\", item.ID).Error; err != nil {\n")
+	fmt.Fprintf(sb, "\t\t\tc.JSON(http.StatusNotFound, gin.H{\"error\": \"%s not found\"})\n", m.Name)
+	sb.WriteString("\t\t\treturn\n\t\t}\n")
+	sb.WriteString("\t\tif conflict {\n")
+	fmt.Fprintf(sb, "\t\t\tc.JSON(http.StatusConflict, gin.H{\"error\": \"This %s was changed by someone else\", \"current\": item})\n", label)
+	sb.WriteString("\t\t\treturn\n\t\t}\n")
+}
+
+// versionBump returns the statement bumping a versioned record's version
+// after an update other than its endpoint's, so edits made to the version
+// before it are refused.
+func versionBump() string {
+	return fmt.Sprintf("\t\tif err := db.Model(&item).UpdateColumn(\"%s\", gorm.Expr(\"%s + 1\")).Error; err != nil {\n\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to update\"})\n\t\t\treturn\n\t\t}\n", ir.VersionColumn, ir.VersionColumn)
+}
+
+// findModel returns the model called name, or nil.
+func findModel(app *ir.Application, name string) *ir.DataModel {
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, name) {
+			return m
+		}
+	}
+	return nil
+}
@@ -5,25 +5,66 @@ import (
 	"strings"
 
 	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/migrate"
 )
 
 func generateDatabase(moduleName string, app *ir.Application) string {
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf(`package database
+	maxOpen, maxIdle := 100, 10
+	if app.Database != nil && app.Database.PoolSize > 0 {
+		maxOpen = app.Database.PoolSize
+		maxIdle = min(maxIdle, maxOpen)
+	}
+	replica := ir.UsesReadReplica(app)
 
-import (
-	"fmt"
-	"time"
+	// With personal fields, SQL is logged without its parameters.
+	masks := len(ir.PersonalFieldNames(app)) > 0
+	var gormOptions []string
+	if masks {
+		gormOptions = append(gormOptions, "Logger: logger.New(log.New(os.Stdout, \"\\r\\n\", log.LstdFlags), logger.Config{\n\t\tSlowThreshold:             200 * time.Millisecond,\n\t\tLogLevel:                  logger.Warn,\n\t\tIgnoreRecordNotFoundError: true,\n\t\tParameterizedQueries:      true,\n\t})")
+	}
+	// When the app stores times in UTC, records are stamped in UTC too.
+	utc := ir.StoresUTC(app)
+	if utc {
+		gormOptions = append(gormOptions, "NowFunc: func() time.Time { return time.Now().UTC() }")
+	}
+	gormConfig := "&gorm.Config{" + strings.Join(gormOptions, ", ") + "}"
 
-	"%s/config"
-	"%s/models"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
-)
+	var sb strings.Builder
+	sb.WriteString("package database\n\nimport (\n\t\"fmt\"\n")
+	if masks {
+		sb.WriteString("\t\"log\"\n\t\"os\"\n")
+	}
+	sb.WriteString("\t\"time\"\n\n")
+	migrates := len(migrate.History(app)) > 0
+	sb.WriteString(fmt.Sprintf("\t\"%s/config\"\n", moduleName))
+	if migrates {
+		sb.WriteString(fmt.Sprintf("\t\"%s/migrations\"\n", moduleName))
+	}
+	sb.WriteString("\t\"gorm.io/driver/postgres\"\n\t\"gorm.io/gorm\"\n")
+	if masks {
+		sb.WriteString("\t\"gorm.io/gorm/logger\"\n")
+	}
+	if replica {
+		sb.WriteString("\t\"gorm.io/plugin/dbresolver\"\n")
+	}
+	sb.WriteString(")\n\n")
 
-func Connect(cfg *config.Config) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{})
-	if err != nil {
+	sb.WriteString("func Connect(cfg *config.Config) (*gorm.DB, error) {\n")
+	if utc {
+		sb.WriteString("\t// Times are kept in UTC: timestamptz columns are read back in UTC and\n")
+		sb.WriteString("\t// written to JSON as RFC 3339 with their offset.\n")
+		sb.WriteString("\ttime.Local = time.UTC\n\n")
+	}
+	if ir.PoolsConnections(app) {
+		// PgBouncer's transaction pooling can't keep prepared statements
+		// between queries.
+		fmt.Fprintf(&sb, `	db, err := gorm.Open(postgres.New(postgres.Config{DSN: cfg.DatabaseURL, PreferSimpleProtocol: true}), %s)
+`, gormConfig)
+	} else {
+		fmt.Fprintf(&sb, `	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), %s)
+`, gormConfig)
+	}
+	sb.WriteString(fmt.Sprintf(`	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %%w", err)
 	}
 
@@ -32,27 +73,48 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to get sql.DB: %%w", err)
 	}
 
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
+	sqlDB.SetMaxIdleConns(%d)
+	sqlDB.SetMaxOpenConns(%d)
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
-	// AutoMigrate models
-	err = db.AutoMigrate(
-`, moduleName, moduleName))
+`, maxIdle, maxOpen))
+	if migrates {
+		sb.WriteString(`	// Apply the schema migrations the database hasn't had
+	if err := migrations.Up(sqlDB); err != nil {
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
 
-	for _, model := range app.Data {
-		sb.WriteString(fmt.Sprintf("\t\t&models.%s{},\n", toPascalCase(model.Name)))
+`)
+	}
+	if replica {
+		sb.WriteString(fmt.Sprintf(`	// Reads outside a transaction go to the read replica; writes and
+	// transactions go to the primary. Use db.Clauses(dbresolver.Write) for a
+	// read that must see a write made just before it.
+	if cfg.DatabaseReplicaURL != "" {
+		resolver := dbresolver.Register(dbresolver.Config{
+			Replicas: []gorm.Dialector{postgres.Open(cfg.DatabaseReplicaURL)},
+		}).
+			SetMaxIdleConns(%d).
+			SetMaxOpenConns(%d).
+			SetConnMaxLifetime(time.Hour)
+		if err := db.Use(resolver); err != nil {
+			return nil, fmt.Errorf("failed to register read replica: %%w", err)
+		}
 	}
 
-	sb.WriteString("\t)\n")
-	sb.WriteString("\tif err != nil {\n\t\treturn nil, fmt.Errorf(\"migration failed: %w\", err)\n\t}\n\n")
+`, maxIdle, maxOpen))
+	}
 	sb.WriteString("\treturn db, nil\n}\n")
 	return sb.String()
 }
 
 func generateModels(moduleName string, app *ir.Application) string {
 	var sb strings.Builder
-	sb.WriteString("package models\n\nimport (\n\t\"time\"\n)\n\n")
+	sb.WriteString("package models\n\nimport (\n\t\"time\"\n")
+	if ir.UsesFieldEncryption(app) {
+		sb.WriteString(fmt.Sprintf("\n\t\"%s/fieldcrypt\"\n", moduleName))
+	}
+	sb.WriteString(")\n\n")
 
 	for _, model := range app.Data {
 		sb.WriteString(fmt.Sprintf("type %s struct {\n", toPascalCase(model.Name)))
@@ -61,8 +123,11 @@ func generateModels(moduleName string, app *ir.Application) string {
 
 		for _, field := range model.Fields {
 			goT := goType(field.Type, field.Required)
+			if field.EncryptsAtRest() {
+				goT = encryptedGoType(field.Required)
+			}
 			tags := []string{}
-			
+
 			if field.Unique {
 				tags = append(tags, "uniqueIndex")
 			}
@@ -76,7 +141,7 @@ func generateModels(moduleName string, app *ir.Application) string {
 			}
 
 			jsonTag := fmt.Sprintf(` json:"%s"`, toCamelCase(field.Name))
-			
+
 			// Optional pointer handling for time/bools when required
 			if strings.Contains(goT, "time.Time") && !strings.Contains(sb.String(), "\"time\"") {
 				sb.WriteString("\t\"time\"\n") // basic check
@@ -104,6 +169,9 @@ func generateModels(moduleName string, app *ir.Application) string {
 
 		sb.WriteString("\tCreatedAt time.Time `json:\"createdAt\"`\n")
 		sb.WriteString("\tUpdatedAt time.Time `json:\"updatedAt\"`\n")
+		if model.Versioned {
+			fmt.Fprintf(&sb, "\tVersion int `gorm:\"not null;default:1\" json:\"%s\"`\n", ir.VersionColumn)
+		}
 		sb.WriteString("}\n\n")
 	}
 
@@ -113,18 +181,32 @@ func generateModels(moduleName string, app *ir.Application) string {
 func generateDTOs(moduleName string, app *ir.Application) string {
 	// Build a map of model fields for type lookups
 	fieldTypes := map[string]map[string]string{} // modelNameLower -> fieldNameLower -> irType
+	encrypted := map[string]map[string]bool{}    // modelNameLower -> fieldNameLower -> encrypted at rest
 	for _, model := range app.Data {
 		m := map[string]string{}
+		e := map[string]bool{}
 		for _, f := range model.Fields {
 			m[strings.ToLower(f.Name)] = f.Type
+			e[strings.ToLower(f.Name)] = f.EncryptsAtRest()
 		}
 		fieldTypes[strings.ToLower(model.Name)] = m
+		encrypted[strings.ToLower(model.Name)] = e
 	}
 
 	var sb strings.Builder
-	sb.WriteString("package dto\n\n")
+	usesFieldCrypt := false
+	usesTime := false
 
 	for _, api := range app.APIs {
+		if api.Account != "" && app.Accounts != nil && len(api.Params) > 0 {
+			usesTime = writeAccountDTO(&sb, api, app) || usesTime
+			if api.Account == ir.AccountUpdateProfile {
+				for _, f := range ir.ProfileFields(app) {
+					usesFieldCrypt = usesFieldCrypt || f.EncryptsAtRest()
+				}
+			}
+			continue
+		}
 		if len(api.Params) > 0 {
 			// Determine the target model for this endpoint
 			targetModel := inferTargetModel(api)
@@ -141,6 +223,12 @@ func generateDTOs(moduleName string, app *ir.Application) string {
 							goT = goType(irType, true)
 						}
 					}
+					// Encrypted fields keep the model's type so they are
+					// encrypted in Updates too.
+					if encrypted[strings.ToLower(targetModel)][pLower] {
+						goT = encryptedGoType(true)
+						usesFieldCrypt = true
+					}
 				}
 				// Fall back to searching all models only if we didn't find it
 				if goT == "string" && targetModel == "" {
@@ -162,11 +250,24 @@ func generateDTOs(moduleName string, app *ir.Application) string {
 				}
 				sb.WriteString(fmt.Sprintf("\t%s %s `json:\"%s\"%s`\n", toPascalCase(p.Name), goT, toCamelCase(p.Name), bindTag))
 			}
+			// The version edited, when not sent in an If-Match header
+			if ir.ConflictModel(app, api) != nil {
+				fmt.Fprintf(&sb, "\tVersion *int `json:\"%s\"`\n", ir.VersionColumn)
+			}
 			sb.WriteString("}\n\n")
 		}
 	}
 
-	return sb.String()
+	header := "package dto\n\n"
+	switch {
+	case usesTime && usesFieldCrypt:
+		header += fmt.Sprintf("import (\n\t\"time\"\n\n\t\"%s/fieldcrypt\"\n)\n\n", moduleName)
+	case usesTime:
+		header += "import \"time\"\n\n"
+	case usesFieldCrypt:
+		header += fmt.Sprintf("import \"%s/fieldcrypt\"\n\n", moduleName)
+	}
+	return header + sb.String()
 }
 
 // inferTargetModel extracts the model name from an endpoint name or its steps.
@@ -0,0 +1,366 @@
+package gobackend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// documentName returns the identifier for a document: Order + "packing
+// slip" → "OrderPackingSlip".
+func documentName(doc *ir.Document) string {
+	return toPascalCase(doc.Model) + strings.ReplaceAll(doc.Title(), " ", "")
+}
+
+// writeDocumentCall emits a "generate a PDF invoice from the Order" step:
+// the PDF is rendered and kept in the storage integration. Without one,
+// it is only rendered when downloaded.
+func writeDocumentCall(sb *strings.Builder, doc *ir.Document, api *ir.Endpoint, app *ir.Application, record string) {
+	if !hasStorageIntegration(app) {
+		fmt.Fprintf(sb, "\t\t// The %s is rendered when downloaded from /api/documents/%s/:id.pdf\n", doc.Kind, doc.Slug)
+		return
+	}
+	id := ""
+	if record != "" {
+		id = record + ".ID"
+	} else if len(api.Params) > 0 {
+		id = "req." + findIDParam(api)
+	}
+	if id == "" {
+		fmt.Fprintf(sb, "\t\t// TODO: no %s to render the %s from — fetch or create it first\n", doc.Model, doc.Kind)
+		return
+	}
+	fmt.Fprintf(sb, "\t\tif err := store%s(db, %s); err != nil {\n", documentName(doc), id)
+	sb.WriteString("\t\t\t_ = err\n")
+	sb.WriteString("\t\t}\n")
+}
+
+// rgb converts a "#rrggbb" color to a Go array literal of its components.
+func rgb(hex string) string {
+	v, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil {
+		return "[3]int{0, 0, 0}"
+	}
+	return fmt.Sprintf("[3]int{%d, %d, %d}", v>>16&0xff, v>>8&0xff, v&0xff)
+}
+
+// generateDocumentService produces services/documents.go: a gofpdf
+// renderer styled from the app's theme. Documents are drawn on A4 with a
+// header band in the theme color, the title, reference, and date, the
+// detail rows, the items table, and the total.
+func generateDocumentService(app *ir.Application) string {
+	var b strings.Builder
+
+	color, family := ir.PDFStyle(app.Theme)
+
+	b.WriteString("// Generated by Human compiler — do not edit\n")
+	b.WriteString("package services\n\n")
+	b.WriteString("import (\n")
+	b.WriteString("\t\"bytes\"\n")
+	b.WriteString("\t\"fmt\"\n")
+	b.WriteString("\t\"reflect\"\n")
+	b.WriteString("\t\"time\"\n\n")
+	b.WriteString("\t\"github.com/jung-kurt/gofpdf\"\n")
+	b.WriteString(")\n\n")
+	fmt.Fprintf(&b, "const documentAppName = %q\n\n", app.Name)
+	b.WriteString("// From the app's theme.\n")
+	fmt.Fprintf(&b, "const pdfFont = %q\n\n", family)
+	b.WriteString("var (\n")
+	fmt.Fprintf(&b, "\tpdfPrimary = %s\n", rgb(color))
+	b.WriteString("\tpdfText    = [3]int{17, 24, 39}\n")
+	b.WriteString("\tpdfMuted   = [3]int{107, 114, 128}\n")
+	b.WriteString("\tpdfRule    = [3]int{229, 231, 235}\n")
+	b.WriteString(")\n")
+
+	b.WriteString(`
+// PDFContent is what a document shows. Details are label/value rows;
+// Columns and Rows make up the items table.
+type PDFContent struct {
+	Title     string
+	Reference string
+	Date      time.Time
+	Details   [][2]string
+	Columns   []string
+	Rows      [][]string
+	Total     *[2]string
+}
+
+// FormatValue formats a field value: dates as YYYY-MM-DD, decimals to two
+// places, and missing values as "-".
+func FormatValue(v any) string {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "-"
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return "-"
+	}
+	switch x := rv.Interface().(type) {
+	case time.Time:
+		if x.IsZero() {
+			return "-"
+		}
+		return x.Format("2006-01-02")
+	case float32, float64:
+		return fmt.Sprintf("%.2f", rv.Float())
+	case string:
+		if x == "" {
+			return "-"
+		}
+		return x
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// RenderPDF renders a document as an A4 PDF.
+func RenderPDF(content PDFContent) ([]byte, error) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(content.Title+" "+content.Reference, true)
+	pdf.SetAuthor(documentAppName, true)
+	pdf.AddPage()
+	width, height := pdf.GetPageSize()
+	left, right := 50.0, width-50
+
+	fill := func(c [3]int) { pdf.SetFillColor(c[0], c[1], c[2]) }
+	text := func(c [3]int) { pdf.SetTextColor(c[0], c[1], c[2]) }
+
+	fill(pdfPrimary)
+	pdf.Rect(0, 0, width, 80, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont(pdfFont, "B", 18)
+	pdf.Text(left, 48, tr(documentAppName))
+
+	date := content.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	y := 130.0
+	text(pdfPrimary)
+	pdf.SetFont(pdfFont, "B", 24)
+	pdf.Text(left, y, tr(content.Title))
+	text(pdfMuted)
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.Text(left, y+20, tr(content.Reference))
+	pdf.Text(left, y+34, date.Format("2006-01-02"))
+	y += 64
+
+	for _, d := range content.Details {
+		text(pdfMuted)
+		pdf.Text(left, y, tr(d[0]))
+		text(pdfText)
+		pdf.Text(left+160, y, tr(d[1]))
+		y += 16
+	}
+
+	if len(content.Columns) > 0 {
+		y += 16
+		colWidth := (right - left) / float64(len(content.Columns))
+		fill(pdfPrimary)
+		pdf.Rect(left, y-14, right-left, 20, "F")
+		pdf.SetTextColor(255, 255, 255)
+		pdf.SetFont(pdfFont, "B", 10)
+		for i, col := range content.Columns {
+			pdf.Text(left+float64(i)*colWidth+5, y, tr(col))
+		}
+		y += 22
+		pdf.SetFont(pdfFont, "", 10)
+		pdf.SetDrawColor(pdfRule[0], pdfRule[1], pdfRule[2])
+		for _, row := range content.Rows {
+			if y > height-80 {
+				pdf.AddPage()
+				y = 50
+			}
+			text(pdfText)
+			for i, cell := range row {
+				pdf.Text(left+float64(i)*colWidth+5, y, tr(cell))
+			}
+			pdf.Line(left, y+6, right, y+6)
+			y += 18
+		}
+	}
+
+	if content.Total != nil {
+		y += 20
+		text(pdfPrimary)
+		pdf.SetFont(pdfFont, "B", 14)
+		pdf.Text(left, y, tr(content.Total[0]))
+		value := tr(content.Total[1])
+		pdf.Text(right-pdf.GetStringWidth(value), y, value)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+`)
+
+	return b.String()
+}
+
+// generateDocumentHandlers produces handlers/documents.go: for each
+// document, a function mapping a record to its content, one rendering it
+// by id, and the PDF download. With authentication, downloads need a
+// signed-in user, who only sees records they own. With a storage
+// integration, documents can also be stored as documents/<slug>/<id>.pdf.
+func generateDocumentHandlers(moduleName string, app *ir.Application) string {
+	var sb strings.Builder
+	storage := hasStorageIntegration(app)
+
+	sb.WriteString("package handlers\n\n")
+	sb.WriteString("import (\n")
+	if storage {
+		sb.WriteString("\t\"bytes\"\n")
+	}
+	sb.WriteString("\t\"errors\"\n")
+	sb.WriteString("\t\"fmt\"\n")
+	sb.WriteString("\t\"net/http\"\n")
+	sb.WriteString("\t\"strings\"\n\n")
+	sb.WriteString("\t\"github.com/gin-gonic/gin\"\n")
+	sb.WriteString("\t\"gorm.io/gorm\"\n\n")
+	fmt.Fprintf(&sb, "\t\"%s/models\"\n", moduleName)
+	fmt.Fprintf(&sb, "\t\"%s/services\"\n", moduleName)
+	sb.WriteString(")\n\n")
+
+	sb.WriteString(`func sendPDF(c *gin.Context, pdf []byte, filename string) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+`)
+
+	for _, doc := range app.Documents {
+		name := documentName(doc)
+		fn := strings.ToLower(name[:1]) + name[1:]
+		model := toPascalCase(doc.Model)
+		scoped := app.Auth != nil && modelBelongsToUser(doc.Model, app)
+
+		fmt.Fprintf(&sb, "\n// %s is the %s content for a%s %s.\n", fn, doc.Kind, articleSuffix(doc.Model), doc.Model)
+		fmt.Fprintf(&sb, "func %s(r models.%s) services.PDFContent {\n", fn, model)
+		sb.WriteString("\tcontent := services.PDFContent{\n")
+		fmt.Fprintf(&sb, "\t\tTitle:     %q,\n", doc.Title())
+		fmt.Fprintf(&sb, "\t\tReference: \"%s \" + r.ID,\n", doc.Model)
+		sb.WriteString("\t\tDate:      r.CreatedAt,\n")
+		sb.WriteString("\t\tDetails: [][2]string{\n")
+		for _, f := range doc.Fields {
+			fmt.Fprintf(&sb, "\t\t\t{%q, services.FormatValue(r.%s)},\n", ir.FieldLabel(f), toPascalCase(f))
+		}
+		sb.WriteString("\t\t},\n")
+		if doc.Items != "" {
+			labels := make([]string, len(doc.ItemFields))
+			for i, f := range doc.ItemFields {
+				labels[i] = strconv.Quote(ir.FieldLabel(f))
+			}
+			fmt.Fprintf(&sb, "\t\tColumns: []string{%s},\n", strings.Join(labels, ", "))
+		}
+		if doc.Total != "" {
+			fmt.Fprintf(&sb, "\t\tTotal:   &[2]string{%q, services.FormatValue(r.%s)},\n", ir.FieldLabel(doc.Total), toPascalCase(doc.Total))
+		}
+		sb.WriteString("\t}\n")
+		if doc.Reference != "" {
+			fmt.Fprintf(&sb, "\tif ref := services.FormatValue(r.%s); ref != \"-\" {\n", toPascalCase(doc.Reference))
+			fmt.Fprintf(&sb, "\t\tcontent.Reference = \"%s \" + ref\n", doc.Model)
+			sb.WriteString("\t}\n")
+		}
+		if doc.Items != "" {
+			cells := make([]string, len(doc.ItemFields))
+			for i, f := range doc.ItemFields {
+				cells[i] = "services.FormatValue(item." + toPascalCase(f) + ")"
+			}
+			fmt.Fprintf(&sb, "\tfor _, item := range r.%s {\n", pluralize(toPascalCase(doc.Items)))
+			fmt.Fprintf(&sb, "\t\tcontent.Rows = append(content.Rows, []string{%s})\n", strings.Join(cells, ", "))
+			sb.WriteString("\t}\n")
+		}
+		sb.WriteString("\treturn content\n")
+		sb.WriteString("}\n")
+
+		query := "db"
+		if doc.Items != "" {
+			query = fmt.Sprintf("db.Preload(%q)", pluralize(toPascalCase(doc.Items)))
+		}
+		if scoped {
+			fmt.Fprintf(&sb, "\n// render%s renders a%s %s's %s; a non-empty userID\n// limits it to that user's records.\n", name, articleSuffix(doc.Model), doc.Model, doc.Kind)
+			fmt.Fprintf(&sb, "func render%s(db *gorm.DB, id, userID string) ([]byte, error) {\n", name)
+			fmt.Fprintf(&sb, "\tquery := %s.Where(\"id = ?\", id)\n", query)
+			sb.WriteString("\tif userID != \"\" {\n")
+			sb.WriteString("\t\tquery = query.Where(\"user_id = ?\", userID)\n")
+			sb.WriteString("\t}\n")
+		} else {
+			fmt.Fprintf(&sb, "\n// render%s renders a%s %s's %s.\n", name, articleSuffix(doc.Model), doc.Model, doc.Kind)
+			fmt.Fprintf(&sb, "func render%s(db *gorm.DB, id string) ([]byte, error) {\n", name)
+			fmt.Fprintf(&sb, "\tquery := %s.Where(\"id = ?\", id)\n", query)
+		}
+		fmt.Fprintf(&sb, "\tvar record models.%s\n", model)
+		sb.WriteString("\tif err := query.First(&record).Error; err != nil {\n")
+		sb.WriteString("\t\treturn nil, err\n")
+		sb.WriteString("\t}\n")
+		fmt.Fprintf(&sb, "\treturn services.RenderPDF(%s(record))\n", fn)
+		sb.WriteString("}\n")
+
+		renderArgs := "db, id"
+		if scoped {
+			renderArgs = "db, id, \"\""
+		}
+		if storage {
+			fmt.Fprintf(&sb, "\n// store%s renders a%s %s's %s and stores it as documents/%s/<id>.pdf.\n", name, articleSuffix(doc.Model), doc.Model, doc.Kind, doc.Slug)
+			fmt.Fprintf(&sb, "func store%s(db *gorm.DB, id string) error {\n", name)
+			fmt.Fprintf(&sb, "\tpdf, err := render%s(%s)\n", name, renderArgs)
+			sb.WriteString("\tif err != nil {\n")
+			sb.WriteString("\t\treturn err\n")
+			sb.WriteString("\t}\n")
+			fmt.Fprintf(&sb, "\treturn services.UploadFile(\"documents/%s/\"+id+\".pdf\", bytes.NewReader(pdf))\n", doc.Slug)
+			sb.WriteString("}\n")
+		}
+
+		if scoped {
+			renderArgs = "db, id, c.MustGet(\"user\").(*models.User).ID"
+		}
+		fmt.Fprintf(&sb, "\n// %sPDF serves a%s %s's %s as a PDF download.\n", name, articleSuffix(doc.Model), doc.Model, doc.Kind)
+		fmt.Fprintf(&sb, "func %sPDF(db *gorm.DB) gin.HandlerFunc {\n", name)
+		sb.WriteString("\treturn func(c *gin.Context) {\n")
+		sb.WriteString("\t\tid := strings.TrimSuffix(c.Param(\"file\"), \".pdf\")\n")
+		fmt.Fprintf(&sb, "\t\tpdf, err := render%s(%s)\n", name, renderArgs)
+		sb.WriteString("\t\tif errors.Is(err, gorm.ErrRecordNotFound) {\n")
+		fmt.Fprintf(&sb, "\t\t\tc.JSON(http.StatusNotFound, gin.H{\"error\": \"%s not found\"})\n", doc.Model)
+		sb.WriteString("\t\t\treturn\n")
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t\tif err != nil {\n")
+		fmt.Fprintf(&sb, "\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to render %s\"})\n", doc.Kind)
+		sb.WriteString("\t\t\treturn\n")
+		sb.WriteString("\t\t}\n")
+		fmt.Fprintf(&sb, "\t\tsendPDF(c, pdf, \"%s-\"+id+\".pdf\")\n", strings.ReplaceAll(doc.Kind, " ", "-"))
+		sb.WriteString("\t}\n")
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
+
+// modelBelongsToUser reports whether a model has a belongs_to User relation.
+func modelBelongsToUser(modelName string, app *ir.Application) bool {
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, modelName) {
+			for _, r := range m.Relations {
+				if r.Kind == "belongs_to" && strings.EqualFold(r.Target, "User") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// articleSuffix returns "n" when a word takes "an": "a" + "n Order".
+func articleSuffix(word string) string {
+	if word != "" && strings.ContainsRune("AEIOUaeiou", rune(word[0])) {
+		return "n"
+	}
+	return ""
+}
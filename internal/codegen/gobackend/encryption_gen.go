@@ -0,0 +1,262 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// encryptedGoType is the model and DTO type of a field encrypted at rest.
+func encryptedGoType(required bool) string {
+	if required {
+		return "fieldcrypt.Text"
+	}
+	return "*fieldcrypt.Text"
+}
+
+// generateFieldCrypt produces fieldcrypt/fieldcrypt.go: AES-256-GCM
+// encryption of the fields marked encrypted, as a Text column type that
+// encrypts in Value and decrypts in Scan. The stored format is shared with
+// the Node and Python backends.
+func generateFieldCrypt() string {
+	return `// Package fieldcrypt encrypts data model fields at rest with AES-256-GCM.
+//
+// Values are stored as enc:v1:<key id>:<base64 of nonce, ciphertext, and tag>,
+// so each value records which key wrote it. FIELD_ENCRYPTION_KEY is the key
+// new values are written with: 32 random bytes, base64-encoded (openssl rand
+// -base64 32). Load it from your secrets manager in production.
+// FIELD_ENCRYPTION_OLD_KEYS lists retired keys, comma-separated, that can
+// still be read during a rotation.
+//
+// Encrypted fields can't be used in a Where clause: each encryption of a
+// value differs.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const prefix = "enc:v1:"
+
+type fieldKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+var (
+	loadOnce sync.Once
+	keys     []fieldKey
+	loadErr  error
+)
+
+func parseKey(encoded string) (fieldKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(raw) != 32 {
+		return fieldKey{}, errors.New("field encryption keys must be 32 bytes, base64-encoded (openssl rand -base64 32)")
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return fieldKey{}, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return fieldKey{}, err
+	}
+	sum := sha256.Sum256(raw)
+	return fieldKey{id: hex.EncodeToString(sum[:])[:8], aead: aead}, nil
+}
+
+// loadKeys returns the current key followed by the old ones.
+func loadKeys() ([]fieldKey, error) {
+	loadOnce.Do(func() {
+		current := os.Getenv("FIELD_ENCRYPTION_KEY")
+		if current == "" {
+			loadErr = errors.New("FIELD_ENCRYPTION_KEY is not set")
+			return
+		}
+		encoded := []string{current}
+		for _, k := range strings.Split(os.Getenv("FIELD_ENCRYPTION_OLD_KEYS"), ",") {
+			if strings.TrimSpace(k) != "" {
+				encoded = append(encoded, k)
+			}
+		}
+		for _, e := range encoded {
+			k, err := parseKey(e)
+			if err != nil {
+				loadErr = err
+				return
+			}
+			keys = append(keys, k)
+		}
+	})
+	return keys, loadErr
+}
+
+// Encrypt encrypts plaintext with the current key.
+func Encrypt(plaintext string) (string, error) {
+	ks, err := loadKeys()
+	if err != nil {
+		return "", err
+	}
+	k := ks[0]
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := k.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefix + k.id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a stored value. A value that isn't ciphertext is
+// returned unchanged: it was stored before the field was encrypted.
+func Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, prefix) {
+		return value, nil
+	}
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", errors.New("fieldcrypt: malformed ciphertext")
+	}
+	ks, err := loadKeys()
+	if err != nil {
+		return "", err
+	}
+	for _, k := range ks {
+		if k.id != id {
+			continue
+		}
+		sealed, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(sealed) < k.aead.NonceSize() {
+			return "", errors.New("fieldcrypt: malformed ciphertext")
+		}
+		n := k.aead.NonceSize()
+		plain, err := k.aead.Open(nil, sealed[:n], sealed[n:], nil)
+		if err != nil {
+			return "", fmt.Errorf("fieldcrypt: %w", err)
+		}
+		return string(plain), nil
+	}
+	return "", fmt.Errorf("no field encryption key with id %s — add the retired key to FIELD_ENCRYPTION_OLD_KEYS", id)
+}
+
+// Text is a string column encrypted on write and decrypted on read. It
+// marshals to JSON as the plain string.
+type Text string
+
+// Value encrypts the text for the database.
+func (t Text) Value() (driver.Value, error) {
+	return Encrypt(string(t))
+}
+
+// Scan decrypts a stored value.
+func (t *Text) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*t = ""
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("fieldcrypt: cannot scan %T into Text", src)
+	}
+	plain, err := Decrypt(s)
+	if err != nil {
+		return err
+	}
+	*t = Text(plain)
+	return nil
+}
+
+// GormDataType stores Text in a text column, since ciphertext is longer
+// than the value.
+func (Text) GormDataType() string {
+	return "text"
+}
+`
+}
+
+// generateKeyRotation produces cmd/rotate-keys/main.go, which re-encrypts
+// every encrypted field with the current key. Values stored before the
+// field was encrypted are encrypted too.
+func generateKeyRotation(moduleName string, app *ir.Application) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`// Command rotate-keys re-encrypts encrypted fields with the current
+// FIELD_ENCRYPTION_KEY.
+//
+// To rotate the key:
+//  1. Add the current key to FIELD_ENCRYPTION_OLD_KEYS.
+//  2. Set FIELD_ENCRYPTION_KEY to a new key (openssl rand -base64 32).
+//  3. Deploy, then run: go run ./cmd/rotate-keys
+//  4. Remove the old key from FIELD_ENCRYPTION_OLD_KEYS.
+package main
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+
+	"%s/config"
+	"%s/database"
+	"%s/models"
+)
+
+const batchSize = 500
+
+func main() {
+	db, err := database.Connect(config.Load())
+	if err != nil {
+		log.Fatal(err)
+	}
+`, moduleName, moduleName, moduleName))
+
+	for _, m := range app.Data {
+		fields := m.EncryptedFields()
+		if len(fields) == 0 {
+			continue
+		}
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = fmt.Sprintf("%q", toPascalCase(f.Name))
+		}
+		sb.WriteString(fmt.Sprintf("\trotate[models.%s](db, %q, %s)\n", toPascalCase(m.Name), m.Name, strings.Join(names, ", ")))
+	}
+
+	sb.WriteString(`}
+
+// rotate rewrites each row's encrypted fields; reading them decrypts with
+// whichever key wrote them and writing encrypts with the current key.
+func rotate[T any](db *gorm.DB, name string, fields ...string) {
+	var rows []T
+	count := 0
+	err := db.FindInBatches(&rows, batchSize, func(tx *gorm.DB, batch int) error {
+		for i := range rows {
+			if err := db.Model(&rows[i]).Select(fields).UpdateColumns(&rows[i]).Error; err != nil {
+				return err
+			}
+		}
+		count += len(rows)
+		return nil
+	}).Error
+	if err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+	log.Printf("%s: re-encrypted %d records", name, count)
+}
+`)
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package gobackend
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateEnvCheck produces config/validate.go. main calls Validate before
+// loading the config, so a missing or malformed env var stops the server
+// with a clear message instead of a driver error later on.
+func generateEnvCheck(app *ir.Application) string {
+	var checks strings.Builder
+	for _, r := range docker.EnvRules(app) {
+		fmt.Fprintf(&checks, "\t{name: %q, kind: %q", r.Name, string(r.Kind))
+		if r.Required {
+			checks.WriteString(", required: true")
+		}
+		if r.RequiredInProduction {
+			checks.WriteString(", requiredInProduction: true")
+		}
+		if len(r.DevValues) > 0 {
+			checks.WriteString(", devValues: []string{")
+			for i, v := range r.DevValues {
+				if i > 0 {
+					checks.WriteString(", ")
+				}
+				fmt.Fprintf(&checks, "%q", v)
+			}
+			checks.WriteString("}")
+		}
+		checks.WriteString("},")
+		if r.Comment != "" {
+			fmt.Fprintf(&checks, " // %s", r.Comment)
+		}
+		checks.WriteString("\n")
+	}
+
+	src := `package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type envCheck struct {
+	name                 string
+	kind                 string // text, url, port or key
+	required             bool
+	requiredInProduction bool
+	devValues            []string // refused in production
+}
+
+var envChecks = []envCheck{
+` + checks.String() + `}
+
+// Validate checks the environment variables the server reads and reports
+// every problem it finds at once.
+func Validate() error {
+	production := os.Getenv("` + docker.ProductionEnv(app) + `") == "production"
+	var problems []string
+	for _, c := range envChecks {
+		if p := c.problem(os.Getenv(c.name), production); p != "" {
+			problems = append(problems, "  - "+p)
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid environment:\n" + strings.Join(problems, "\n") + "\nSee .env.example for the expected values.")
+	}
+	return nil
+}
+
+func (c envCheck) problem(value string, production bool) string {
+	if value == "" {
+		if c.required || (production && c.requiredInProduction) {
+			return c.name + " is not set"
+		}
+		return ""
+	}
+	if production {
+		for _, dev := range c.devValues {
+			if value == dev {
+				return c.name + " still has its development value — set your own in production"
+			}
+		}
+	}
+	switch c.kind {
+	case "url":
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return c.name + " must be a URL with a scheme and a host"
+		}
+	case "port":
+		if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+			return fmt.Sprintf("%s must be a port number (1-65535), got %q", c.name, value)
+		}
+	case "key":
+		if key, err := base64.StdEncoding.DecodeString(value); err != nil || len(key) != 32 {
+			return c.name + " must be 32 bytes, base64 — generate one with openssl rand -base64 32"
+		}
+	}
+	return ""
+}
+`
+	if formatted, err := format.Source([]byte(src)); err == nil {
+		return string(formatted)
+	}
+	return src
+}
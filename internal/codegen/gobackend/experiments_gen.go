@@ -0,0 +1,180 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// hasAnalyticsIntegration returns true if the app has an analytics integration.
+func hasAnalyticsIntegration(app *ir.Application) bool {
+	for _, integ := range app.Integrations {
+		if integ.Type == "analytics" {
+			return true
+		}
+	}
+	return false
+}
+
+// generateExperimentsMiddleware produces middleware/experiments.go, which
+// identifies the visitor and assigns a sticky variant for every experiment.
+func generateExperimentsMiddleware(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString(`package middleware
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Variant is one arm of an experiment.
+type Variant struct {
+	Name   string
+	Weight int
+}
+
+// Experiment splits traffic between variant pages.
+type Experiment struct {
+	Name     string
+	Audience string
+	Variants []Variant
+}
+
+// Experiments lists every experiment declared in the .human file.
+var Experiments = []Experiment{
+`)
+	for _, exp := range app.Experiments {
+		var variants []string
+		for _, v := range exp.Variants {
+			variants = append(variants, fmt.Sprintf("{%q, %d}", v.Page, v.Weight))
+		}
+		fmt.Fprintf(&b, "\t{Name: %q, Audience: %q, Variants: []Variant{%s}},\n", exp.Name, exp.Audience, strings.Join(variants, ", "))
+	}
+	b.WriteString(`}
+
+const visitorCookie = "hx_visitor"
+
+var visitorIDPattern = regexp.MustCompile(` + "`^[A-Za-z0-9_-]{8,64}$`" + `)
+
+// Bucket maps a visitor to 0–99 for an experiment. The hash is
+// deterministic, so a visitor always lands in the same variant.
+func Bucket(experiment, visitorID string) int {
+	sum := sha256.Sum256([]byte(experiment + ":" + visitorID))
+	return int(binary.BigEndian.Uint32(sum[:4]) % 100)
+}
+
+// AssignVariant returns the variant a visitor sees in an experiment.
+func AssignVariant(exp Experiment, visitorID string) string {
+	point := Bucket(exp.Name, visitorID)
+	for _, v := range exp.Variants {
+		if point < v.Weight {
+			return v.Name
+		}
+		point -= v.Weight
+	}
+	return exp.Variants[0].Name
+}
+
+// AssignExperiments identifies the visitor — X-Visitor-Id header, then the
+// visitor cookie, else a new id — and stores a variant for every experiment
+// in the context under "visitorID" and "experiments".
+func AssignExperiments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cookie, _ := c.Cookie(visitorCookie)
+		visitorID := ""
+		for _, id := range []string{c.GetHeader("X-Visitor-Id"), cookie} {
+			if visitorIDPattern.MatchString(id) {
+				visitorID = id
+				break
+			}
+		}
+		if visitorID == "" {
+			buf := make([]byte, 16)
+			rand.Read(buf)
+			visitorID = hex.EncodeToString(buf)
+		}
+		if cookie != visitorID {
+			c.SetSameSite(http.SameSiteLaxMode)
+			c.SetCookie(visitorCookie, visitorID, 365*24*60*60, "/", "", false, true)
+		}
+
+		assignments := make(map[string]string, len(Experiments))
+		for _, exp := range Experiments {
+			assignments[exp.Name] = AssignVariant(exp, visitorID)
+		}
+		c.Set("visitorID", visitorID)
+		c.Set("experiments", assignments)
+		c.Next()
+	}
+}
+`)
+
+	return b.String()
+}
+
+// generateExperimentsHandlers produces handlers/experiments.go: the
+// endpoint the frontend reads assignments from and exposure logging.
+func generateExperimentsHandlers(moduleName string, app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("package handlers\n\n")
+	b.WriteString("import (\n")
+	if !hasAnalyticsIntegration(app) {
+		b.WriteString("\t\"encoding/json\"\n")
+	}
+	b.WriteString("\t\"log\"\n")
+	b.WriteString("\t\"net/http\"\n\n")
+	b.WriteString("\t\"github.com/gin-gonic/gin\"\n")
+	if hasAnalyticsIntegration(app) {
+		fmt.Fprintf(&b, "\n\t\"%s/services\"\n", moduleName)
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString(`// ExperimentAssignments returns the visitor's id and variant assignments.
+func ExperimentAssignments(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"visitorId":   c.GetString("visitorID"),
+		"assignments": c.GetStringMapString("experiments"),
+	}})
+}
+
+// ExperimentExposure records that the visitor saw their variant. Analytics
+// failures are logged and swallowed so they never break the page.
+func ExperimentExposure(c *gin.Context) {
+	name := c.Param("name")
+	variant, ok := c.GetStringMapString("experiments")[name]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown experiment"})
+		return
+	}
+
+	visitorID := c.GetString("visitorID")
+	properties := map[string]any{"experiment": name, "variant": variant}
+`)
+	if hasAnalyticsIntegration(app) {
+		b.WriteString(`	if err := services.Track(c.Request.Context(), visitorID, "Experiment Viewed", properties); err != nil {
+		log.Printf("Failed to log experiment exposure: %v", err)
+	}
+`)
+	} else {
+		b.WriteString(`	properties["event"] = "Experiment Viewed"
+	properties["visitorId"] = visitorID
+	if line, err := json.Marshal(properties); err == nil {
+		log.Println(string(line))
+	}
+`)
+	}
+	b.WriteString(`	c.Status(http.StatusNoContent)
+}
+`)
+
+	return b.String()
+}
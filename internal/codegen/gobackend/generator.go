@@ -7,6 +7,9 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/codegen/grpc"
+	"github.com/barun-bash/human/internal/codegen/openapi"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -38,17 +41,49 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 	}
 
 	files := map[string]string{
-		filepath.Join(outputDir, "go.mod"):                    generateGoMod(moduleName, app),
-		filepath.Join(outputDir, "main.go"):                   generateMain(moduleName, app),
-		filepath.Join(outputDir, "config", "config.go"):       generateConfig(moduleName),
-		filepath.Join(outputDir, "database", "database.go"):   generateDatabase(moduleName, app),
-		filepath.Join(outputDir, "models", "models.go"):       generateModels(moduleName, app),
-		filepath.Join(outputDir, "dto", "dto.go"):             generateDTOs(moduleName, app),
-		filepath.Join(outputDir, "middleware", "auth.go"):     generateAuth(moduleName, app),
-		filepath.Join(outputDir, "handlers", "handlers.go"):   generateHandlers(moduleName, app),
-		filepath.Join(outputDir, "routes", "routes.go"):       generateRoutes(moduleName, app),
-		filepath.Join(outputDir, "migrations", "initial.sql"): generateMigration(app),
-		filepath.Join(outputDir, "setup.sh"):                  generateSetupScript(),
+		filepath.Join(outputDir, "go.mod"):                  generateGoMod(moduleName, app),
+		filepath.Join(outputDir, "main.go"):                 generateMain(moduleName, app),
+		filepath.Join(outputDir, "config", "config.go"):     generateConfig(moduleName, app),
+		filepath.Join(outputDir, "config", "validate.go"):   generateEnvCheck(app),
+		filepath.Join(outputDir, "database", "database.go"): generateDatabase(moduleName, app),
+		filepath.Join(outputDir, "models", "models.go"):     generateModels(moduleName, app),
+		filepath.Join(outputDir, "dto", "dto.go"):           generateDTOs(moduleName, app),
+		filepath.Join(outputDir, "middleware", "auth.go"):   generateAuth(moduleName, app),
+		filepath.Join(outputDir, "handlers", "handlers.go"): generateHandlers(moduleName, app),
+		filepath.Join(outputDir, "routes", "routes.go"):     generateRoutes(moduleName, app),
+		filepath.Join(outputDir, "setup.sh"):                generateSetupScript(),
+	}
+
+	// Migrations taking databases through the schema's history
+	for relPath, content := range generateMigrations(moduleName, app) {
+		files[filepath.Join(outputDir, filepath.FromSlash(relPath))] = content
+	}
+
+	// Generate field encryption and its key rotation command
+	if ir.UsesFieldEncryption(app) {
+		files[filepath.Join(outputDir, "fieldcrypt", "fieldcrypt.go")] = generateFieldCrypt()
+		files[filepath.Join(outputDir, "cmd", "rotate-keys", "main.go")] = generateKeyRotation(moduleName, app)
+	}
+
+	// Generate the audit log middleware for compliance profiles
+	if ir.AuditsRequests(app) {
+		files[filepath.Join(outputDir, "middleware", "audit.go")] = generateAuditLog(moduleName)
+	}
+
+	// Request validation against the OpenAPI document, embedded by main.go
+	if ir.ValidatesRequests(app) {
+		files[filepath.Join(outputDir, "openapi.yaml")] = openapi.Spec(app)
+		files[filepath.Join(outputDir, "middleware", "openapi.go")] = generateOpenAPIValidation()
+	}
+
+	// Workflow jobs, run by "<binary> worker"
+	if ir.UsesJobQueue(app) {
+		files[filepath.Join(outputDir, "jobs", "jobs.go")] = generateJobs(app)
+	}
+
+	// Masking of fields marked personal in logs and error responses
+	if len(ir.PersonalFieldNames(app)) > 0 {
+		files[filepath.Join(outputDir, "middleware", "masking.go")] = generateMasking(app)
 	}
 
 	// Add policy files if policies are defined
@@ -77,24 +112,107 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		files[filepath.Join(outputDir, "handlers", "upload.go")] = generateUploadHandler(moduleName, app)
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
+	// Generate in-app notification handlers
+	if len(app.Notifications) > 0 {
+		files[filepath.Join(outputDir, "handlers", "notifications.go")] = generateNotificationHandlers(moduleName)
+	}
+
+	// Generate subscription billing, plan checks, and the plan sync command
+	if ir.Bills(app) {
+		files[filepath.Join(outputDir, "services", "billing.go")] = generateBillingService(moduleName, app)
+		files[filepath.Join(outputDir, "handlers", "billing.go")] = generateBillingHandlers(moduleName, app)
+		files[filepath.Join(outputDir, "cmd", "sync-plans", "main.go")] = generatePlanSync(moduleName, app)
+		if app.Billing.Gated() {
+			files[filepath.Join(outputDir, "middleware", "plans.go")] = generatePlanMiddleware(moduleName, app)
 		}
 	}
 
-	return nil
-}
+	// Generate usage metering: counting calls, quotas, and the usage endpoints
+	if ir.Meters(app) {
+		files[filepath.Join(outputDir, "services", "usage.go")] = generateUsageService(moduleName, app)
+		files[filepath.Join(outputDir, "middleware", "metering.go")] = generateMeteringMiddleware(moduleName, app)
+		files[filepath.Join(outputDir, "handlers", "usage.go")] = generateUsageHandlers(moduleName, app)
+	}
 
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
+	// Generate the endpoints managing API keys for external clients
+	if ir.IssuesAPIKeys(app) {
+		files[filepath.Join(outputDir, "handlers", "api_keys.go")] = generateAPIKeyHandlers(moduleName, app)
 	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
+
+	// Generate chaos mode, failing API requests the way the error handlers
+	// are declared for
+	if ir.InjectsChaos(app) {
+		files[filepath.Join(outputDir, "middleware", "chaos.go")] = generateChaos(app)
+	}
+
+	// Generate the seed command filling the development database
+	if len(app.Data) > 0 {
+		files[filepath.Join(outputDir, "cmd", "seed", "main.go")] = generateSeed(moduleName, app)
+	}
+
+	// Generate request recording, for replaying API requests
+	files[filepath.Join(outputDir, "middleware", "record.go")] = generateRecord()
+
+	// Generate calendar feeds and .ics downloads
+	if len(app.Calendars) > 0 {
+		files[filepath.Join(outputDir, "services", "calendar.go")] = generateCalendarService(app)
+		files[filepath.Join(outputDir, "services", "calendar_test.go")] = generateCalendarTests()
+		files[filepath.Join(outputDir, "handlers", "calendar.go")] = generateCalendarHandlers(moduleName, app)
 	}
-	return nil
+
+	// Generate PDF documents and their downloads
+	if len(app.Documents) > 0 {
+		files[filepath.Join(outputDir, "services", "documents.go")] = generateDocumentService(app)
+		files[filepath.Join(outputDir, "handlers", "documents.go")] = generateDocumentHandlers(moduleName, app)
+	}
+
+	// Generate the models' lifecycle hooks as GORM hook methods
+	if ir.HasModelHooks(app) {
+		files[filepath.Join(outputDir, "models", "hooks.go")] = generateModelHooks(app)
+	}
+
+	// Generate the version checks of records protected against conflicting edits
+	if ir.ProtectsEdits(app) {
+		files[filepath.Join(outputDir, "handlers", "concurrency.go")] = generateConcurrencyHelpers()
+	}
+
+	// Generate the record checks of CSV and JSON imports
+	if ir.HasImports(app) {
+		files[filepath.Join(outputDir, "handlers", "imports.go")] = generateImportHelpers()
+	}
+
+	// Generate aggregate helpers for charts and report endpoints
+	if hasAggregates(app) {
+		files[filepath.Join(outputDir, "handlers", "aggregates.go")] = generateAggregateHelpers()
+	}
+
+	// Generate reorder endpoints for drag-and-drop lists
+	if len(app.Reorders) > 0 {
+		files[filepath.Join(outputDir, "handlers", "reorder.go")] = generateReorderHandlers(moduleName, app)
+	}
+
+	// Generate aggregate endpoints for dashboard charts
+	if len(app.Charts) > 0 {
+		files[filepath.Join(outputDir, "handlers", "charts.go")] = generateChartHandlers(moduleName, app)
+	}
+
+	// Generate sitemap.xml and robots.txt for web apps with public pages
+	if app.Sitemap != nil {
+		files[filepath.Join(outputDir, "handlers", "sitemap.go")] = generateSitemapHandlers(moduleName, app)
+	}
+
+	// Generate experiment assignment middleware when experiments are declared
+	if len(app.Experiments) > 0 {
+		files[filepath.Join(outputDir, "middleware", "experiments.go")] = generateExperimentsMiddleware(app)
+		files[filepath.Join(outputDir, "handlers", "experiments.go")] = generateExperimentsHandlers(moduleName, app)
+	}
+
+	// Generate the gRPC server when the API style is gRPC
+	if grpc.IsEnabled(app) {
+		files[filepath.Join(outputDir, "grpcserver", "server.go")] = generateGrpcServer(moduleName, app)
+	}
+
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 func appNameLower(app *ir.Application) string {
@@ -1,6 +1,11 @@
 package gobackend
 
 import (
+	"go/ast"
+	"go/importer"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -154,7 +159,10 @@ func TestGenerateWritesFiles(t *testing.T) {
 		"middleware/auth.go",
 		"handlers/handlers.go",
 		"routes/routes.go",
-		"migrations/initial.sql",
+		"migrations/0001_initial.up.sql",
+		"migrations/0001_initial.down.sql",
+		"migrations/migrations.go",
+		"cmd/migrate/main.go",
 	}
 
 	for _, f := range expectedFiles {
@@ -256,3 +264,1879 @@ func TestFullIntegration(t *testing.T) {
 		t.Error("routes.go: missing tasks route with auth")
 	}
 }
+
+func TestGenerateGrpcServer(t *testing.T) {
+	app := &ir.Application{
+		Name:   "TaskFlow",
+		Config: &ir.BuildConfig{Backend: "Go with Gin", APIStyle: "gRPC"},
+		Data: []*ir.DataModel{
+			{Name: "Task", Fields: []*ir.DataField{{Name: "title", Type: "text"}}},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "CreateTask", Auth: true, Params: []*ir.Param{{Name: "title"}, {Name: "due date"}}},
+			{Name: "GetProfile", Auth: true},
+		},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := os.ReadFile(filepath.Join(dir, "grpcserver", "server.go"))
+	if err != nil {
+		t.Fatal("missing grpcserver/server.go")
+	}
+	if _, err := goparser.ParseFile(token.NewFileSet(), "server.go", server, goparser.AllErrors); err != nil {
+		t.Fatalf("grpcserver/server.go does not parse: %v", err)
+	}
+	for _, want := range []string{
+		`pb "taskflow-grpc/gen/go/taskflow/v1"`,
+		"pb.UnimplementedTaskFlowServiceServer",
+		`err := s.forward(ctx, "POST", "/api/task", map[string]any{"title": req.GetTitle(), "dueDate": req.GetDueDate()}, false, resp)`,
+		`err := s.forward(ctx, "GET", "/api/profile", nil, true, resp)`,
+	} {
+		if !strings.Contains(string(server), want) {
+			t.Errorf("server.go missing %q", want)
+		}
+	}
+
+	main, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if _, err := goparser.ParseFile(token.NewFileSet(), "main.go", main, goparser.AllErrors); err != nil {
+		t.Fatalf("main.go does not parse: %v", err)
+	}
+	if !strings.Contains(string(main), "grpcserver.Serve(\":\"+grpcPort, r)") {
+		t.Error("main.go should start the gRPC server")
+	}
+	gomod, _ := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if !strings.Contains(string(gomod), "replace taskflow-grpc => ../grpc") {
+		t.Error("go.mod should replace the stubs module with ../grpc")
+	}
+}
+
+func TestGenerateExperiments(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Shop",
+		Config: &ir.BuildConfig{Backend: "Go with Gin"},
+		Pages:  []*ir.Page{{Name: "Classic"}, {Name: "NewPricing"}},
+		Integrations: []*ir.Integration{
+			{Service: "Mixpanel", Type: "analytics"},
+		},
+		Experiments: []*ir.Experiment{
+			{Name: "pricing-page", Audience: "visitors", Variants: []*ir.ExperimentVariant{{Page: "Classic", Weight: 50}, {Page: "NewPricing", Weight: 50}}},
+		},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"middleware/experiments.go", "handlers/experiments.go", "services/analytics.go", "main.go", "routes/routes.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Fatalf("%s does not parse: %v", rel, err)
+		}
+	}
+
+	middleware, _ := os.ReadFile(filepath.Join(dir, "middleware", "experiments.go"))
+	if !strings.Contains(string(middleware), `{Name: "pricing-page", Audience: "visitors", Variants: []Variant{{"Classic", 50}, {"NewPricing", 50}}},`) {
+		t.Error("middleware/experiments.go missing the experiment table")
+	}
+	handlers, _ := os.ReadFile(filepath.Join(dir, "handlers", "experiments.go"))
+	if !strings.Contains(string(handlers), `services.Track(c.Request.Context(), visitorID, "Experiment Viewed", properties)`) {
+		t.Error("exposures should be sent to the analytics integration")
+	}
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes", "routes.go"))
+	for _, want := range []string{
+		"api.Use(middleware.AssignExperiments())",
+		`api.GET("/experiments", handlers.ExperimentAssignments)`,
+		`api.POST("/experiments/:name/exposure", handlers.ExperimentExposure)`,
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.go missing %q", want)
+		}
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if !strings.Contains(string(main), "X-Visitor-Id") {
+		t.Error("CORS should allow the X-Visitor-Id header")
+	}
+}
+
+func TestNotificationsGenerated(t *testing.T) {
+	source := `app TaskFlow is a web application
+
+data User:
+  has a name which is text
+
+data Task:
+  belongs to a User
+  has a title which is text
+
+api AssignTask:
+  requires authentication
+  accepts task_id and user_id
+  update the Task with the assignee
+  respond with the updated task
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+notify the user in the app when their task is assigned
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"handlers/notifications.go", "handlers/handlers.go", "routes/routes.go", "models/models.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v", rel, err)
+		}
+	}
+
+	handlers, _ := os.ReadFile(filepath.Join(dir, "handlers", "handlers.go"))
+	if !strings.Contains(string(handlers), `notify(db, req.UserID, "Your task was assigned")`) {
+		t.Error("AssignTask should notify the assignee")
+	}
+
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes", "routes.go"))
+	for _, want := range []string{
+		`notifications := api.Group("/notifications", middleware.RequireAuth(db, cfg))`,
+		`notifications.GET("/unread-count", handlers.UnreadNotificationCount(db))`,
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.go missing %q", want)
+		}
+	}
+}
+
+func TestCalendarFeedsGenerated(t *testing.T) {
+	source := `app EventHub is a web application
+
+data Event:
+  has a name which is text
+  has an optional location which is text
+  has a starts_at which is datetime
+  has an optional ends_at which is datetime
+
+users can add an Event to their calendar in America/New_York
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"handlers/calendar.go", "services/calendar.go", "services/calendar_test.go", "routes/routes.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v", rel, err)
+		}
+	}
+
+	handlers, _ := os.ReadFile(filepath.Join(dir, "handlers", "calendar.go"))
+	for _, want := range []string{
+		"Location: derefString(r.Location),",
+		"event.End = r.EndsAt",
+		`services.ToICS("Events", "America/New_York", events)`,
+		`strings.TrimSuffix(c.Param("file"), ".ics")`,
+	} {
+		if !strings.Contains(string(handlers), want) {
+			t.Errorf("handlers/calendar.go missing %q", want)
+		}
+	}
+
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes", "routes.go"))
+	for _, want := range []string{
+		`api.GET("/calendar/events.ics", handlers.EventCalendarFeed(db))`,
+		`api.GET("/calendar/events/:file", handlers.EventCalendarDownload(db))`,
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.go missing %q", want)
+		}
+	}
+}
+
+func TestPDFDocumentsGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data User:
+  has a name which is text
+  has many Order
+
+data Order:
+  belongs to a User
+  has an order_number which is unique text
+  has a total which is decimal
+  has many OrderItem
+
+data OrderItem:
+  belongs to an Order
+  has a quantity which is number
+
+api CreateOrder:
+  requires authentication
+  accepts items
+  create an Order with the given fields
+  generate a PDF invoice from the Order
+  respond with the created order
+
+theme:
+  primary color is #E94560
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+integrate with AWS S3:
+  api key from environment variable AWS_ACCESS_KEY
+  use for storing invoices
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"handlers/documents.go", "services/documents.go", "handlers/handlers.go", "routes/routes.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v", rel, err)
+		}
+	}
+
+	service, _ := os.ReadFile(filepath.Join(dir, "services", "documents.go"))
+	if !strings.Contains(string(service), "pdfPrimary = [3]int{233, 69, 96}") {
+		t.Error("services/documents.go should use the theme's primary color")
+	}
+
+	handlers, _ := os.ReadFile(filepath.Join(dir, "handlers", "documents.go"))
+	for _, want := range []string{
+		`query := db.Preload("OrderItems").Where("id = ?", id)`,
+		`query = query.Where("user_id = ?", userID)`,
+		`content.Reference = "Order " + ref`,
+		`services.UploadFile("documents/order-invoices/"+id+".pdf", bytes.NewReader(pdf))`,
+		`renderOrderInvoice(db, id, c.MustGet("user").(*models.User).ID)`,
+	} {
+		if !strings.Contains(string(handlers), want) {
+			t.Errorf("handlers/documents.go missing %q", want)
+		}
+	}
+
+	steps, _ := os.ReadFile(filepath.Join(dir, "handlers", "handlers.go"))
+	if !strings.Contains(string(steps), "storeOrderInvoice(db, newItem.ID)") {
+		t.Error("handlers.go should store the invoice for the created order")
+	}
+
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes", "routes.go"))
+	if !strings.Contains(string(routes), `api.GET("/documents/order-invoices/:file", middleware.RequireAuth(db, cfg), handlers.OrderInvoicePDF(db))`) {
+		t.Error("routes.go should register the invoice download")
+	}
+
+	gomod, _ := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if !strings.Contains(string(gomod), "github.com/jung-kurt/gofpdf") {
+		t.Error("go.mod should require gofpdf")
+	}
+}
+
+func TestSitemapGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data Product:
+  has a name which is text
+  has a price which is decimal
+
+page Home:
+  show a heading
+
+page ProductDetail:
+  show the product name
+
+environment production:
+  url is shop.example.com
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "handlers", "sitemap.go")
+	handlers, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal("missing handlers/sitemap.go")
+	}
+	if _, err := goparser.ParseFile(token.NewFileSet(), path, handlers, 0); err != nil {
+		t.Fatalf("handlers/sitemap.go does not parse: %v", err)
+	}
+	for _, want := range []string{
+		"var sitemapPages = []string{\"/\", \"/product-detail\"}",
+		"return \"https://shop.example.com\"",
+		"db.Select(\"id\", \"updated_at\").Limit(sitemapMaxURLs).Find(&products)",
+		"writeSitemapURL(&b, origin+\"/product-detail?id=\"+r.ID, r.UpdatedAt)",
+		"func Robots(c *gin.Context) {",
+	} {
+		if !strings.Contains(string(handlers), want) {
+			t.Errorf("sitemap.go missing %q", want)
+		}
+	}
+
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes", "routes.go"))
+	for _, want := range []string{"api.GET(\"/sitemap.xml\", handlers.Sitemap(db))", "api.GET(\"/robots.txt\", handlers.Robots)"} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.go missing %q", want)
+		}
+	}
+}
+
+func TestFieldEncryptionGenerated(t *testing.T) {
+	source := `app Clinic is a web application
+
+data Patient:
+  has a name which is text
+  has a ssn which is encrypted text
+  has an optional notes which is encrypted text
+
+page Home:
+  show a list of patients
+
+api CreatePatient:
+  accepts name, ssn, and notes
+  create a Patient with the given fields
+  respond with the created patient
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"fieldcrypt/fieldcrypt.go", "cmd/rotate-keys/main.go", "models/models.go", "dto/dto.go", "handlers/handlers.go"} {
+		path := filepath.Join(dir, rel)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), path, src, 0); err != nil {
+			t.Fatalf("%s does not parse: %v", rel, err)
+		}
+	}
+
+	checks := map[string][]string{
+		"models/models.go":        {"\"clinic/fieldcrypt\"", "Ssn fieldcrypt.Text `", "Notes *fieldcrypt.Text `"},
+		"dto/dto.go":              {"import \"clinic/fieldcrypt\"", "Ssn fieldcrypt.Text `"},
+		"handlers/handlers.go":    {"Ssn: fieldcrypt.Text(req.Ssn),", "Notes: (*fieldcrypt.Text)(&req.Notes),"},
+		"cmd/rotate-keys/main.go": {"rotate[models.Patient](db, \"Patient\", \"Ssn\", \"Notes\")"},
+	}
+	for rel, wants := range checks {
+		content, _ := os.ReadFile(filepath.Join(dir, rel))
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q", rel, want)
+			}
+		}
+	}
+}
+
+func TestReadReplicaAndPoolGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:     "TaskFlow",
+		Config:   &ir.BuildConfig{Backend: "Go with Gin", Database: "PostgreSQL"},
+		Database: &ir.DatabaseConfig{Engine: "PostgreSQL", ReadReplica: true, PoolSize: 20},
+		Data:     []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{{Name: "title", Type: "text"}}}},
+	}
+
+	files := map[string]string{
+		"database.go": generateDatabase("taskflow", app),
+		"config.go":   generateConfig("taskflow", app),
+	}
+	for name, src := range files {
+		if _, err := goparser.ParseFile(token.NewFileSet(), name, src, goparser.AllErrors); err != nil {
+			t.Fatalf("%s does not parse: %v", name, err)
+		}
+	}
+	for _, want := range []string{
+		"postgres.Config{DSN: cfg.DatabaseURL, PreferSimpleProtocol: true}",
+		"sqlDB.SetMaxOpenConns(20)",
+		"Replicas: []gorm.Dialector{postgres.Open(cfg.DatabaseReplicaURL)},",
+		"db.Use(resolver)",
+	} {
+		if !strings.Contains(files["database.go"], want) {
+			t.Errorf("database.go missing %q", want)
+		}
+	}
+	if !strings.Contains(files["config.go"], `os.Getenv("DATABASE_REPLICA_URL")`) {
+		t.Error("config.go should read DATABASE_REPLICA_URL")
+	}
+	if !strings.Contains(generateGoMod("taskflow", app), "gorm.io/plugin/dbresolver") {
+		t.Error("go.mod should require dbresolver")
+	}
+
+	app.Database = &ir.DatabaseConfig{Engine: "PostgreSQL"}
+	plain := generateDatabase("taskflow", app)
+	if strings.Contains(plain, "dbresolver") || !strings.Contains(plain, "sqlDB.SetMaxOpenConns(100)") {
+		t.Error("without replica or pool rules, database.go should be unchanged")
+	}
+}
+
+func TestGenerateAuditLog(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Clinic",
+		Config: &ir.BuildConfig{Backend: "Go with Gin", Compliance: "SOC2"},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"middleware/audit.go", "main.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Fatalf("%s does not parse: %v", rel, err)
+		}
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if !strings.Contains(string(main), "r.Use(middleware.AuditLog())") {
+		t.Error("main.go should install the audit log middleware")
+	}
+}
+
+func TestGenerateOpenAPIValidation(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Shop",
+		Config: &ir.BuildConfig{Backend: "Go with Gin", Validation: "the OpenAPI spec"},
+		APIs:   []*ir.Endpoint{{Name: "CreateOrder", Params: []*ir.Param{{Name: "quantity"}}}},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "openapi.yaml")); err != nil {
+		t.Fatal("missing openapi.yaml")
+	}
+	for _, rel := range []string{"middleware/openapi.go", "main.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Fatalf("%s does not parse: %v", rel, err)
+		}
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	for _, want := range []string{"//go:embed openapi.yaml", "middleware.ValidateRequests(openAPISpec)", "r.Use(validate)"} {
+		if !strings.Contains(string(main), want) {
+			t.Errorf("main.go missing %q", want)
+		}
+	}
+	gomod, _ := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if !strings.Contains(string(gomod), "github.com/getkin/kin-openapi") {
+		t.Error("go.mod should require kin-openapi")
+	}
+
+	app.Config.Validation = ""
+	dir = t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "middleware", "openapi.go")); err == nil {
+		t.Error("middleware/openapi.go should only be generated when the build asks for validation")
+	}
+}
+
+func TestGenerateMasking(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Clinic",
+		Config: &ir.BuildConfig{Backend: "Go with Gin"},
+		Data: []*ir.DataModel{{Name: "Patient", Fields: []*ir.DataField{
+			{Name: "email", Type: "email", Personal: true},
+		}}},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"middleware/masking.go", "main.go", "database/database.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Fatalf("%s does not parse: %v", rel, err)
+		}
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	for _, want := range []string{"r.Use(gin.LoggerWithFormatter(middleware.MaskedLogFormatter), gin.Recovery())", "r.Use(middleware.MaskErrorResponses())"} {
+		if !strings.Contains(string(main), want) {
+			t.Errorf("main.go missing %q", want)
+		}
+	}
+	db, _ := os.ReadFile(filepath.Join(dir, "database", "database.go"))
+	if !strings.Contains(string(db), "ParameterizedQueries:      true,") {
+		t.Error("SQL should be logged without parameters")
+	}
+}
+
+func TestGenerateJobs(t *testing.T) {
+	app := &ir.Application{
+		Name:   "TaskFlow",
+		Config: &ir.BuildConfig{Backend: "Go with Gin"},
+		Workflows: []*ir.Workflow{{Trigger: "a user signs up", Steps: []*ir.Action{
+			{Type: "send", Text: "send welcome email"},
+			{Type: "delay", Text: "after 3 days, send a tips email"},
+		}}},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"jobs/jobs.go", "main.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Fatalf("%s does not parse: %v", rel, err)
+		}
+	}
+	jobs, _ := os.ReadFile(filepath.Join(dir, "jobs", "jobs.go"))
+	for _, want := range []string{
+		`mux.HandleFunc("user-signs-up-after-3-days", onUserSignsUpAfter3Days)`,
+		`Enqueue("user-signs-up-after-3-days", json.RawMessage(t.Payload()), 72*time.Hour)`,
+	} {
+		if !strings.Contains(string(jobs), want) {
+			t.Errorf("jobs.go missing %q", want)
+		}
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if !strings.Contains(string(main), "jobs.RunWorker()") {
+		t.Error("main.go should run the worker when started as worker")
+	}
+	gomod, _ := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if !strings.Contains(string(gomod), "github.com/hibiken/asynq") {
+		t.Error("go.mod should require asynq")
+	}
+}
+
+func TestChartsGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data User:
+  has a name which is text
+  has many Expense
+
+data Project:
+  has a title which is text
+
+data Product:
+  has a category which is text
+  has a current_stock which is number
+
+data Expense:
+  belongs to a User
+  belongs to a Project
+  has an amount which is decimal
+  has a date which is date
+
+page Dashboard:
+  show a bar chart of stock levels by category
+  show a pie chart of total expenses by project
+  show a line chart of expenses over the last 30 days
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"handlers/charts.go", "routes/routes.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v", rel, err)
+		}
+	}
+	handlers, _ := os.ReadFile(filepath.Join(dir, "handlers", "charts.go"))
+	for _, want := range []string{
+		`Select("COALESCE(CAST(products.category AS TEXT), 'None') AS label, COALESCE(SUM(products.current_stock), 0) AS value")`,
+		`query = query.Joins("LEFT JOIN projects ON projects.id = expenses.project_id")`,
+		`query = query.Where("expenses.user_id = ?", c.MustGet("user").(*models.User).ID)`,
+		`to_char(date_trunc('day', expenses.date), 'YYYY-MM-DD') AS label`,
+		`query = query.Where("expenses.date >= ?", aggregateSince(30))`,
+	} {
+		if !strings.Contains(string(handlers), want) {
+			t.Errorf("handlers/charts.go missing %q", want)
+		}
+	}
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes", "routes.go"))
+	if !strings.Contains(string(routes), `api.GET("/charts/stock-levels-by-category", middleware.RequireAuth(db, cfg), handlers.ChartStockLevelsByCategory(db))`) {
+		t.Error("routes.go should register the chart handlers")
+	}
+}
+
+func TestReportEndpointsGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data User:
+  has a name which is text
+  has many Order
+
+data Order:
+  belongs to a User
+  has an amount which is decimal
+  has a status which is text
+
+api MonthlyRevenue: sum the amount of Orders grouped by month for the last 12 months
+
+api GetOrdersByStatus:
+  requires authentication
+  count the Orders grouped by status
+  cache for 5 minutes
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"handlers/handlers.go", "handlers/aggregates.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v", rel, err)
+		}
+	}
+	handlers, _ := os.ReadFile(filepath.Join(dir, "handlers", "handlers.go"))
+	for _, want := range []string{
+		`c.Header("Cache-Control", "public, max-age=60")`,
+		`Select("to_char(date_trunc('month', orders.created_at), 'YYYY-MM') AS label, COALESCE(SUM(orders.amount), 0) AS value")`,
+		`query = query.Where("orders.created_at >= ?", aggregateSince(360))`,
+		`c.Header("Cache-Control", "private, max-age=300")`,
+		`query = query.Where("orders.user_id = ?", c.MustGet("user").(*models.User).ID)`,
+	} {
+		if !strings.Contains(string(handlers), want) {
+			t.Errorf("handlers.go missing %q", want)
+		}
+	}
+}
+
+func TestReorderRoutesGenerated(t *testing.T) {
+	source := `app Board is a web application
+
+data User:
+  has a name which is text
+  has many Task
+
+data Task:
+  belongs to a User
+  has a title which is text
+
+page Home:
+  show a list of tasks
+  each task shows its title
+  dragging a task reorders the list
+
+api ListTasks:
+  requires authentication
+  fetch all tasks for the current user
+  respond with tasks
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"handlers/reorder.go", "routes/routes.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("missing %s", rel)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), rel, src, goparser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v", rel, err)
+		}
+	}
+	handlers, _ := os.ReadFile(filepath.Join(dir, "handlers", "reorder.go"))
+	for _, want := range []string{
+		"func ReorderTasks(db *gorm.DB) gin.HandlerFunc {",
+		"err := db.Transaction(func(tx *gorm.DB) error {",
+		`query = query.Where("user_id = ?", c.MustGet("user").(*models.User).ID)`,
+		`if err := query.Update("position", position).Error; err != nil {`,
+	} {
+		if !strings.Contains(string(handlers), want) {
+			t.Errorf("handlers/reorder.go missing %q", want)
+		}
+	}
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes", "routes.go"))
+	if !strings.Contains(string(routes), `api.PUT("/reorder/tasks", middleware.RequireAuth(db, cfg), handlers.ReorderTasks(db))`) {
+		t.Error("routes.go should register the reorder handler")
+	}
+	list, _ := os.ReadFile(filepath.Join(dir, "handlers", "handlers.go"))
+	if !strings.Contains(string(list), `db.Order("position asc").Find(&items)`) {
+		t.Error("ListTasks should list tasks in their dragged order")
+	}
+}
+
+func TestPaginatedListGenerated(t *testing.T) {
+	source := `app Feed is a web application
+
+data Post:
+  has a title which is text
+
+data Task:
+  has a title which is text
+
+page Home:
+  show a list of posts
+  each post shows its title
+  scrolling to bottom loads more posts
+
+page Board:
+  show a list of tasks
+  each task shows its title
+  dragging a task reorders the list
+
+api ListPosts:
+  fetch all posts
+  respond with posts
+
+api ListTasks:
+  fetch all tasks
+  paginate with 50 per page
+  respond with tasks
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "handlers", "handlers.go"))
+	if err != nil {
+		t.Fatal("missing handlers/handlers.go")
+	}
+	if _, err := goparser.ParseFile(token.NewFileSet(), "handlers.go", src, goparser.AllErrors); err != nil {
+		t.Errorf("handlers.go does not parse: %v", err)
+	}
+	for _, want := range []string{
+		`"strconv"`,
+		"limit := 20",
+		`query = query.Order("created_at desc, id desc")`,
+		`query = query.Where("(created_at, id) < (?)", db.Model(&models.Post{}).Select("created_at, id").Where("id = ?", cursor))`,
+		"limit := 50",
+		`query = query.Order("COALESCE(position, 2147483647) asc, id asc")`,
+		`query = query.Offset((max(page, 1) - 1) * limit)`,
+		`gin.H{"data": items, "pagination": gin.H{"limit": limit, "nextCursor": nextCursor}}`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("handlers.go missing %q", want)
+		}
+	}
+}
+
+func TestPaginatedListQueryParams(t *testing.T) {
+	source := `app Tracker is a web application
+
+data Task:
+  has a title which is text
+  has a status which is either "todo" or "done"
+  has a done which is boolean
+
+api ListTasks:
+  fetch all tasks
+  support filtering by status and done
+  support searching by title
+  paginate with 20 per page
+  respond with tasks
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "handlers", "handlers.go"))
+	if err != nil {
+		t.Fatal("missing handlers/handlers.go")
+	}
+	if _, err := goparser.ParseFile(token.NewFileSet(), "handlers.go", src, goparser.AllErrors); err != nil {
+		t.Errorf("handlers.go does not parse: %v", err)
+	}
+	for _, want := range []string{
+		`query = query.Where("status = ?", v)`,
+		`query = query.Where("done = ?", v == "true")`,
+		`query = query.Where("LOWER(title) LIKE LOWER(?)", like)`,
+		`sortColumns := map[string]string{"title": "title", "status": "status", "done": "done"}`,
+		`query = query.Order(column + " " + direction + ", id " + direction)`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("handlers.go missing %q", want)
+		}
+	}
+}
+
+func TestVersionedUpdate(t *testing.T) {
+	source := `app Blog is a web application
+
+data Post:
+  has a title which is text
+  has a published which is boolean
+  protect against conflicting edits
+
+api UpdatePost:
+  accepts post_id, title, and published
+  fetch the Post by post_id
+  if post does not exist, respond with "post not found"
+  update the post with the given fields
+  respond with the updated post
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"handlers.go", "concurrency.go"} {
+		src, err := os.ReadFile(filepath.Join(dir, "handlers", name))
+		if err != nil {
+			t.Fatalf("missing handlers/%s", name)
+		}
+		if _, err := goparser.ParseFile(token.NewFileSet(), name, src, goparser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v", name, err)
+		}
+	}
+	src, _ := os.ReadFile(filepath.Join(dir, "handlers", "handlers.go"))
+	for _, want := range []string{
+		`expected, ok := expectedVersion(c.GetHeader("If-Match"), req.Version)`,
+		`c.JSON(http.StatusPreconditionRequired, gin.H{"error": "Send the version of the post you edited in an If-Match header"})`,
+		`bumped := tx.Model(&item).Where("version = ?", expected).UpdateColumn("version", gorm.Expr("version + 1"))`,
+		`c.JSON(http.StatusConflict, gin.H{"error": "This post was changed by someone else", "current": item})`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("handlers.go missing %q", want)
+		}
+	}
+	models, _ := os.ReadFile(filepath.Join(dir, "models", "models.go"))
+	if !strings.Contains(string(models), "Version int `gorm:\"not null;default:1\" json:\"version\"`") {
+		t.Errorf("Post should count its versions:\n%s", models)
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if !strings.Contains(string(main), "If-Match") {
+		t.Error("CORS should allow the If-Match header")
+	}
+}
+
+func TestImport(t *testing.T) {
+	source := `app Tracker is a web application
+
+data Task:
+  has a title which is text
+  has a status which is either "todo" or "done"
+  has an optional estimate which is number
+  has a done which is boolean
+
+api ImportTasks:
+  import tasks from a csv or json file
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "handlers", "handlers.go"))
+	if err != nil {
+		t.Fatal("missing handlers/handlers.go")
+	}
+	for _, want := range []string{
+		"records, ok := readImport(c)",
+		`summary := importSummary{Mode: "all-or-nothing"`,
+		`Status: valueOf(record.oneOf("status", []string{"todo", "done"}, true)),`,
+		`Estimate: optional(record.number("estimate", false)),`,
+		"rejectImport(c, summary,",
+		"db.Transaction(func(tx *gorm.DB) error {",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("handlers.go missing %q:\n%s", want, src)
+		}
+	}
+	helpers, err := os.ReadFile(filepath.Join(dir, "handlers", "imports.go"))
+	if err != nil {
+		t.Fatal("missing handlers/imports.go")
+	}
+	if !strings.Contains(string(helpers), "http.MaxBytesReader") {
+		t.Error("imports.go should cap the file's size")
+	}
+}
+
+func TestTimesInUTC(t *testing.T) {
+	source := `app Planner is a web application
+
+data Event:
+  has a title which is text
+  has a starts which is datetime
+  has an optional day which is date
+
+api CreateEvent:
+  accepts title, starts, and day
+  create an event with the given fields
+  respond with the created event
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL
+  store all times in UTC and display in the user's timezone`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "database", "database.go"))
+	if err != nil {
+		t.Fatal("missing database/database.go")
+	}
+	for _, want := range []string{
+		"time.Local = time.UTC",
+		"NowFunc: func() time.Time { return time.Now().UTC() }",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("database.go missing %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestAccountEndpoints(t *testing.T) {
+	source := `app Notes is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+  has an optional bio which is text
+
+accounts:
+  users can update their profile, change password, and delete their account
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "handlers", "handlers.go"))
+	if err != nil {
+		t.Fatal("missing handlers/handlers.go")
+	}
+	for _, want := range []string{
+		"func profileOf(user *models.User) gin.H {",
+		"user := c.MustGet(\"user\").(*models.User)",
+		"db.Model(&models.User{}).Where(\"email = ? AND id <> ?\", req.Email, user.ID).Count(&taken)",
+		"if !middleware.CheckPasswordHash(req.CurrentPassword, user.Password) {",
+		"db.Model(user).Update(\"password\", hashedPassword)",
+		"if err := db.Delete(user).Error; err != nil {",
+		"c.Status(http.StatusNoContent)",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("handlers.go missing %q:\n%s", want, src)
+		}
+	}
+	if strings.Contains(string(src), "\"password\": user.Password") {
+		t.Error("a profile leaves out the password")
+	}
+}
+
+func TestInvitationHandlers(t *testing.T) {
+	source := `app Teams is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+  has a role which is text
+
+data Organization:
+  has a name which is text
+
+accounts:
+  users can invite teammates by email to their Organization
+  invitations expire in 3 days
+
+policy Admin:
+  can invite teammates
+
+policy Member:
+  can view tasks
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "handlers", "handlers.go"))
+	if err != nil {
+		t.Fatal("missing handlers/handlers.go")
+	}
+	for _, want := range []string{
+		"\"crypto/rand\"",
+		"case \"Admin\", \"Member\":",
+		"Token:   hex.EncodeToString(token),",
+		"Expires: time.Now().Add(3 * 24 * time.Hour),",
+		"sent[\"link\"] = link",
+		"db.Preload(\"Organization\").Where(\"token = ?\", c.Query(\"token\")).First(&invitation)",
+		"if invitation.Expires.Before(time.Now()) {",
+		"if !strings.EqualFold(user.Email, invitation.Email) {",
+		"membership = models.Membership{Role: invitation.Role, UserID: user.ID, OrganizationID: invitation.OrganizationID}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("handlers.go missing %q:\n%s", want, src)
+		}
+	}
+	dto, _ := os.ReadFile(filepath.Join(dir, "dto", "dto.go"))
+	if !strings.Contains(string(dto), "Email string `json:\"email\" binding:\"required,email\"`") {
+		t.Errorf("an invitation's email is checked:\n%s", dto)
+	}
+}
+
+func TestBillingHandlers(t *testing.T) {
+	source := `app Boards is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+
+data Project:
+  belongs to a User
+  has a name which is text
+
+plans: Free, Pro ($19/month), Enterprise
+
+policy FreePlan:
+  can create up to 3 projects per month
+  cannot export data
+
+api CreateProject:
+  requires authentication
+  accepts name
+  create a Project with the given fields
+  respond with the created project
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+integrate with Stripe:
+  api key from environment variable STRIPE_SECRET_KEY
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"services/billing.go": {
+			"stripe.Key = os.Getenv(\"STRIPE_SECRET_KEY\")",
+			"const FreePlan = \"Free\"",
+			"record = models.Subscription{StripeSubscriptionID: sub.ID, UserID: userID}",
+		},
+		"handlers/billing.go": {
+			"Mode:              stripe.String(string(stripe.CheckoutSessionModeSubscription)),",
+			"params.CustomerEmail = stripe.String(user.Email)",
+			"portalsession.New(&stripe.BillingPortalSessionParams{",
+			"webhook.ConstructEvent(body, c.GetHeader(\"Stripe-Signature\"), os.Getenv(\"STRIPE_WEBHOOK_SECRET\"))",
+			"+ \"/pricing\"",
+		},
+		"middleware/plans.go": {
+			"{Plan: \"Free\", Action: \"export\", Model: \"data\"},",
+			"{Plan: \"Free\", Action: \"create\", Model: \"project\", Limit: 3, Period: \"month\"},",
+			"\"project\": &models.Project{},",
+			"http.StatusPaymentRequired",
+		},
+		"routes/routes.go": {
+			"billing.POST(\"/webhook\", handlers.BillingWebhook(db))",
+			"api.POST(\"/project\", middleware.RequireAuth(db, cfg), middleware.RequirePlan(db, \"create\", \"project\"), handlers.CreateProject(db, cfg))",
+		},
+		"cmd/sync-plans/main.go": {
+			"{Name: \"Pro\", Price: 1900, Interval: \"month\"},",
+			"Currency:    stripe.String(\"usd\"),",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+}
+
+func TestUsageMetering(t *testing.T) {
+	source := `app Forecast is a web application
+
+data User:
+  has an email which is unique email
+  has a password which is text
+  has a role which is text
+
+api GetForecast:
+  requires authentication
+  respond with the forecast
+
+policy Member:
+  can make up to 5000 API calls per week
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+meter API calls per user per week
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"services/usage.go": {
+			"const MeterPeriod = \"week\"",
+			"\"Member\": {Limit: 5000, Period: \"week\"},",
+			"quota, ok = quotas[user.Role]",
+			"UpdateColumn(\"calls\", gorm.Expr(\"calls + 1\"))",
+			"db.Create(&models.UsageRecord{UserID: userID, PeriodStart: start, Calls: 1})",
+		},
+		"middleware/metering.go": {
+			"c.Header(\"Retry-After\",",
+			"http.StatusTooManyRequests",
+		},
+		"handlers/usage.go": {
+			"func UsageSummary(db *gorm.DB) gin.HandlerFunc {",
+			"services.CallHistory(db, user.ID, periods)",
+		},
+		"routes/routes.go": {
+			"usage.GET(\"/history\", handlers.UsageHistory(db))",
+			"api.GET(\"/forecast\", middleware.RequireAuth(db, cfg), middleware.Meter(db), handlers.GetForecast(db, cfg))",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+	usage, _ := os.ReadFile(filepath.Join(dir, "services", "usage.go"))
+	if strings.Contains(string(usage), "stripe") {
+		t.Error("an app that doesn't bill shouldn't report usage to Stripe")
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	source := `app Forecast is a web application
+
+data User:
+  has an email which is unique email
+  has a password which is text
+
+api GetForecast:
+  requires authentication
+  respond with the forecast
+
+authentication:
+  method JWT tokens that expire in 7 days
+  external clients authenticate with API keys
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"middleware/auth.go": {
+			"\"crypto/sha256\"",
+			"if key := c.GetHeader(APIKeyHeader); key != \"\" {",
+			"db.Preload(\"User\").First(&apiKey, \"key_hash = ?\", HashAPIKey(key))",
+			"db.Model(&apiKey).UpdateColumn(\"last_used_at\", time.Now())",
+			"func RequireSignIn() gin.HandlerFunc {",
+		},
+		"handlers/api_keys.go": {
+			"func ListAPIKeys(db *gorm.DB) gin.HandlerFunc {",
+			"key := \"hk_\" + base64.RawURLEncoding.EncodeToString(secret)",
+			"KeyHash: middleware.HashAPIKey(key),",
+			"c.JSON(http.StatusCreated, gin.H{\"data\": data})",
+		},
+		"routes/routes.go": {
+			"keys := api.Group(\"/keys\", middleware.RequireAuth(db, cfg), middleware.RequireSignIn())",
+			"keys.DELETE(\"/:id\", handlers.RevokeAPIKey(db))",
+		},
+		"main.go": {
+			"Authorization, accept, origin, Cache-Control, X-Requested-With, X-API-Key",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+}
+
+func TestChaos(t *testing.T) {
+	source := `app Shop is a web application
+
+data Order:
+  has a total which is number
+
+api ListOrders:
+  fetch all orders
+  respond with orders
+
+if database is unreachable:
+  retry 3 times with 2 second delay
+  if still failing, respond with "service temporarily unavailable"
+  alert the engineering team via Slack
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"middleware/chaos.go": {
+			"if v := os.Getenv(\"HUMAN_CHAOS\"); v != \"1\" && v != \"true\" {",
+			"Condition: \"database is unreachable\",",
+			"Attempts:  3,",
+			"Delay:     2000 * time.Millisecond,",
+			"Status:    503,",
+			"Message:   \"Service temporarily unavailable\",",
+			"Alert:     \"alert the engineering team via Slack\",",
+			"c.Header(\"X-Chaos\", fault.Condition)",
+		},
+		"routes/routes.go": {
+			"api.Use(middleware.Chaos())",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+
+	// Without error handlers there's nothing to inject
+	app.ErrorHandlers = nil
+	plain := t.TempDir()
+	if err := (Generator{}).Generate(app, plain); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(plain, "middleware", "chaos.go")); err == nil {
+		t.Error("chaos middleware generated without error handlers")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	source := `app Shop is a web application
+
+data Order:
+  has a total which is number
+
+api ListOrders:
+  fetch all orders
+  respond with orders
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"middleware/record.go": {
+			"dir := os.Getenv(\"HUMAN_RECORD_DIR\")",
+			"c.Request.Body = io.NopCloser(bytes.NewReader(body))",
+			"fmt.Fprintf(&b, \"%s {{host}}%s\\n\", c.Request.Method, c.Request.URL.RequestURI())",
+		},
+		"routes/routes.go": {
+			"api.Use(middleware.Record())",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+}
+
+func TestBuildHeader(t *testing.T) {
+	prog, err := parser.Parse(`app Shop is a web application
+
+data Order:
+  has a total which is number
+
+api ListOrders:
+  fetch all orders
+  respond with orders
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	// Only a build has provenance to send
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if strings.Contains(string(content), "X-Human-Build") {
+		t.Errorf("main.go should not send X-Human-Build without a build")
+	}
+
+	app.Build = &ir.BuildInfo{Compiler: "0.4.0", IRHash: "sha256:abc", GeneratedAt: "2026-03-01T12:00:00Z"}
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	content, _ = os.ReadFile(filepath.Join(dir, "main.go"))
+	want := "\tr.Use(func(c *gin.Context) {\n\t\tc.Header(\"X-Human-Build\", \"human/0.4.0; ir=sha256:abc; generated=2026-03-01T12:00:00Z\")\n\t\tc.Next()\n\t})"
+	if !strings.Contains(string(content), want) {
+		t.Errorf("main.go missing %q:\n%s", want, content)
+	}
+}
+
+func TestModelHooks(t *testing.T) {
+	prog, err := parser.Parse(`app Tasks is a web application
+
+data User:
+  has an email which is unique email
+
+data Session:
+  belongs to a User
+  has a token which is text
+
+data Task:
+  belongs to a User
+  has a status which is text
+  has a priority which is number
+
+before a Task is created, set its status to "pending"
+before a Task is updated:
+  set its priority to 2
+  notify the owner
+after a User is deleted, remove their Sessions
+
+build with:
+  backend using Go with Gin
+  database using PostgreSQL`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "models/hooks.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"func (m *Task) BeforeCreate(tx *gorm.DB) error {\n\t// before a Task is created\n\ttx.Statement.SetColumn(\"Status\", \"pending\")\n\treturn nil\n}",
+		"tx.Statement.SetColumn(\"Priority\", 2)\n\t// TODO: notify the owner",
+		"func (m *User) BeforeDelete(tx *gorm.DB) error {",
+		"tx.Session(&gorm.Session{NewDB: true}).Where(\"user_id = ?\", m.ID).Delete(&Session{})",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("models/hooks.go missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestCORSOrigin(t *testing.T) {
+	app := &ir.Application{
+		Name:         "Shop",
+		Config:       &ir.BuildConfig{Backend: "Go with Gin"},
+		Environments: []*ir.Environment{{Name: "production", Config: map[string]string{"url": "shop example com"}}},
+	}
+	if main := generateMain("shop", app); !strings.Contains(main, `Set("Access-Control-Allow-Origin", "*")`) {
+		t.Error("a local build should accept requests from any origin")
+	}
+	app.Config.Env = "production"
+	if main := generateMain("shop", app); !strings.Contains(main, `Set("Access-Control-Allow-Origin", "https://shop.example.com")`) {
+		t.Errorf("a production build should only accept its frontend:\n%s", main)
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{
+			{Name: "User", Fields: []*ir.DataField{
+				{Name: "email", Type: "email", Required: true},
+				{Name: "password", Type: "text"},
+			}},
+			{Name: "Task", Fields: []*ir.DataField{
+				{Name: "title", Type: "text", Required: true},
+				{Name: "due date", Type: "date"},
+			}, Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}}},
+		},
+		Seeds: []*ir.Seed{{Model: "Task", Count: 3}},
+	}
+
+	out := generateSeed("shop", app)
+	for _, want := range []string{
+		"//\tgo run ./cmd/seed",
+		"\"shop/middleware\"",
+		"const userRecords = `[\n\t{\"id\": \"00000001-0000-4000-8000-000000000001\", \"email\": ",
+		"\"dueDate\": \"2025-",
+		"T00:00:00Z\", \"userId\": \"00000001-0000-4000-8000-0000000000",
+		"password, err := middleware.HashPassword(\"password123\")",
+		"seed[models.User](db, \"users\", userRecords, func(r *models.User) { r.Password = &password })",
+		"seed[models.Task](db, \"tasks\", taskRecords, nil)",
+		"db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&rows)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("cmd/seed/main.go missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "00000002-0000-4000-8000-000000000004") {
+		t.Errorf("expected 3 tasks:\n%s", out)
+	}
+	if err := typecheckSeed(out); err != nil {
+		t.Errorf("cmd/seed/main.go doesn't compile: %v\n%s", err, out)
+	}
+}
+
+// seedStubs are the packages cmd/seed/main.go imports, cut down to what it
+// uses, so the seeder can be type-checked without gorm or a generated app.
+var seedStubs = map[string]string{
+	"gorm.io/gorm": `package gorm
+import "gorm.io/gorm/clause"
+type DB struct{ Error error }
+func (db *DB) Clauses(conds ...clause.Expression) *DB { return db }
+func (db *DB) Create(value any) *DB { return db }`,
+	"gorm.io/gorm/clause": `package clause
+type Expression interface{}
+type OnConflict struct{ UpdateAll bool }`,
+	"shop/config": `package config
+type Config struct{}
+func Load() *Config { return nil }`,
+	"shop/database": `package database
+import ("gorm.io/gorm"; "shop/config")
+func Connect(cfg *config.Config) (*gorm.DB, error) { return nil, nil }`,
+	"shop/middleware": `package middleware
+func HashPassword(password string) (string, error) { return password, nil }`,
+	"shop/models": `package models
+type User struct{ Password *string }
+type Task struct{}`,
+}
+
+// stubImporter type-checks the stubs it's asked for and imports the
+// standard library from source.
+type stubImporter struct {
+	fset *token.FileSet
+	std  types.Importer
+	pkgs map[string]*types.Package
+}
+
+func (im *stubImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := im.pkgs[path]; ok {
+		return pkg, nil
+	}
+	src, ok := seedStubs[path]
+	if !ok {
+		return im.std.Import(path)
+	}
+	file, err := goparser.ParseFile(im.fset, path+".go", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := (&types.Config{Importer: im}).Check(path, im.fset, []*ast.File{file}, nil)
+	if err != nil {
+		return nil, err
+	}
+	im.pkgs[path] = pkg
+	return pkg, nil
+}
+
+// typecheckSeed type-checks a generated cmd/seed/main.go for module "shop".
+func typecheckSeed(src string) error {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, "main.go", src, 0)
+	if err != nil {
+		return err
+	}
+	im := &stubImporter{fset: fset, std: importer.ForCompiler(fset, "source", nil), pkgs: map[string]*types.Package{}}
+	_, err = (&types.Config{Importer: im}).Check("shop/cmd/seed", fset, []*ast.File{file}, nil)
+	return err
+}
+
+func TestGenerateMigrations(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{
+			{Name: "User", Fields: []*ir.DataField{{Name: "email", Type: "email", Required: true, Unique: true}}},
+			{Name: "Task", Fields: []*ir.DataField{
+				{Name: "title", Type: "text", Required: true},
+				{Name: "due date", Type: "date"},
+			}, Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}}},
+		},
+	}
+
+	files := generateMigrations("shop", app)
+	up := files["migrations/0001_initial.up.sql"]
+	for _, want := range []string{
+		"CREATE TABLE \"users\" (\n    \"id\" UUID NOT NULL DEFAULT gen_random_uuid(),\n    \"email\" TEXT NOT NULL,",
+		"CREATE UNIQUE INDEX \"idx_users_email\" ON \"users\" (\"email\");",
+		"\"due_date\" TIMESTAMPTZ,",
+		"\"user_id\" UUID,",
+		"ADD CONSTRAINT \"fk_tasks_user_id\" FOREIGN KEY (\"user_id\") REFERENCES \"users\" (\"id\")",
+	} {
+		if !strings.Contains(up, want) {
+			t.Errorf("0001_initial.up.sql missing %q:\n%s", want, up)
+		}
+	}
+	if down := files["migrations/0001_initial.down.sql"]; !strings.Contains(down, "DROP TABLE \"tasks\";") {
+		t.Errorf("0001_initial.down.sql doesn't drop tasks:\n%s", down)
+	}
+
+	for _, name := range []string{"migrations/migrations.go", "cmd/migrate/main.go"} {
+		if _, err := goparser.ParseFile(token.NewFileSet(), name, files[name], 0); err != nil {
+			t.Errorf("%s doesn't parse: %v", name, err)
+		}
+	}
+	if !strings.Contains(files["cmd/migrate/main.go"], "\"shop/database\"") {
+		t.Errorf("cmd/migrate/main.go doesn't import the database package:\n%s", files["cmd/migrate/main.go"])
+	}
+	if db := generateDatabase("shop", app); !strings.Contains(db, "migrations.Up(sqlDB)") || strings.Contains(db, "AutoMigrate") {
+		t.Errorf("database.go should apply the migrations:\n%s", db)
+	}
+}
+
+func TestEnvCheck(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Shop",
+		Config: &ir.BuildConfig{Backend: "Go with Gin"},
+		Integrations: []*ir.Integration{
+			{Service: "SendGrid", Type: "email", Credentials: map[string]string{"api key": "SENDGRID_API_KEY"}},
+		},
+	}
+	check := generateEnvCheck(app)
+	for _, want := range []string{
+		`{name: "DATABASE_URL", kind: "url", required: true}`,
+		`{name: "SENDGRID_API_KEY", kind: "text", requiredInProduction: true}`,
+		`os.Getenv("APP_ENV") == "production"`,
+		"func Validate() error {",
+	} {
+		if !strings.Contains(check, want) {
+			t.Errorf("validate.go missing %q", want)
+		}
+	}
+	if main := generateMain("shop", app); !strings.Contains(main, "if err := config.Validate(); err != nil {\n\t\tlog.Fatal(err)\n\t}\n\tcfg := config.Load()") {
+		t.Error("main should validate the environment before loading the config")
+	}
+}
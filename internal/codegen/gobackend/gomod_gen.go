@@ -2,9 +2,12 @@ package gobackend
 
 import (
 	"fmt"
+	"go/format"
 	"strings"
 
+	"github.com/barun-bash/human/internal/codegen/grpc"
 	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/migrate"
 )
 
 func generateGoMod(moduleName string, app *ir.Application) string {
@@ -40,16 +43,128 @@ require (
 		}
 	}
 
+	if app != nil && len(app.Documents) > 0 {
+		deps.WriteString("\tgithub.com/jung-kurt/gofpdf v1.16.2\n")
+	}
+
+	if app != nil && len(migrate.History(app)) > 0 {
+		deps.WriteString("\tgithub.com/golang-migrate/migrate/v4 v4.18.1\n")
+	}
+
+	if app != nil && ir.UsesReadReplica(app) {
+		deps.WriteString("\tgorm.io/plugin/dbresolver v1.5.3\n")
+	}
+
+	if app != nil && ir.UsesJobQueue(app) {
+		deps.WriteString("\tgithub.com/hibiken/asynq v0.24.1\n")
+	}
+
+	if app != nil && ir.ValidatesRequests(app) {
+		deps.WriteString("\tgithub.com/getkin/kin-openapi v0.128.0\n")
+	}
+
+	if app != nil && grpc.IsEnabled(app) {
+		deps.WriteString(fmt.Sprintf("\t%s v0.0.0\n", grpc.ModuleName(app)))
+		deps.WriteString("\tgoogle.golang.org/grpc v1.68.0\n")
+		deps.WriteString("\tgoogle.golang.org/protobuf v1.35.2\n")
+	}
+
 	deps.WriteString(")\n")
+	if app != nil && grpc.IsEnabled(app) {
+		// The stubs are generated into the sibling grpc/ module by buf.
+		deps.WriteString(fmt.Sprintf("\nreplace %s => ../grpc\n", grpc.ModuleName(app)))
+	}
 	return deps.String()
 }
 
 func generateMain(moduleName string, app *ir.Application) string {
+	var grpcImport, grpcStart, grpcStop string
+	if app != nil && grpc.IsEnabled(app) {
+		grpcImport = fmt.Sprintf("\t\"%s/grpcserver\"\n", moduleName)
+		grpcStart = fmt.Sprintf(`
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "%d"
+	}
+	grpcSrv, err := grpcserver.Serve(":"+grpcPort, r)
+	if err != nil {
+		log.Fatalf("Failed to start gRPC server: %%v", err)
+	}
+	log.Printf("gRPC server running on port %%s", grpcPort)
+`, grpc.DefaultPort)
+		grpcStop = "\tgrpcSrv.GracefulStop()\n"
+	}
+
+	var middlewareImport, auditUse string
+	if app != nil && ir.AuditsRequests(app) {
+		middlewareImport = fmt.Sprintf("\t\"%s/middleware\"\n", moduleName)
+		auditUse = "\t// Audit log of requests that change data\n\tr.Use(middleware.AuditLog())\n\n"
+	}
+
+	// Requests are checked against the OpenAPI document embedded in the binary.
+	var embedImport, specVar, validateUse string
+	if app != nil && ir.ValidatesRequests(app) {
+		middlewareImport = fmt.Sprintf("\t\"%s/middleware\"\n", moduleName)
+		embedImport = "\t_ \"embed\"\n"
+		specVar = "//go:embed openapi.yaml\nvar openAPISpec []byte\n\n"
+		validateUse = "\t// Request validation against openapi.yaml\n\tvalidate, err := middleware.ValidateRequests(openAPISpec)\n\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n\tr.Use(validate)\n\n"
+	}
+
+	// "<binary> worker" runs the workflow jobs instead of the server.
+	var jobsImport, workerStart string
+	if app != nil && ir.UsesJobQueue(app) {
+		jobsImport = fmt.Sprintf("\t\"%s/jobs\"\n", moduleName)
+		workerStart = `
+	if len(os.Args) > 1 && os.Args[1] == "worker" {
+		if err := jobs.RunWorker(); err != nil {
+			log.Fatalf("Worker stopped: %v", err)
+		}
+		return
+	}
+`
+	}
+
+	// Personal fields are masked in the request log and error responses.
+	router := "\tr := gin.Default()\n"
+	if app != nil && len(ir.PersonalFieldNames(app)) > 0 {
+		middlewareImport = fmt.Sprintf("\t\"%s/middleware\"\n", moduleName)
+		router = "\tr := gin.New()\n\tr.Use(gin.LoggerWithFormatter(middleware.MaskedLogFormatter), gin.Recovery())\n\tr.Use(middleware.MaskErrorResponses())\n"
+	}
+
+	// Experiment assignments stay sticky across origins via X-Visitor-Id.
+	corsHeaders := "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With"
+	if app != nil && len(app.Experiments) > 0 {
+		corsHeaders += ", X-Visitor-Id"
+	}
+	// Updates of records protected against conflicting edits name the
+	// version they were made to.
+	if app != nil && ir.ProtectsEdits(app) {
+		corsHeaders += ", If-Match"
+	}
+	// External clients send their API key in its own header.
+	if app != nil && ir.IssuesAPIKeys(app) {
+		corsHeaders += ", " + ir.APIKeyHeader
+	}
+
+	// Built for an environment, only its frontend may call the API.
+	corsOrigin := "*"
+	if app != nil {
+		if origin := ir.AllowedOrigin(app); origin != "" {
+			corsOrigin = origin
+		}
+	}
+
+	// Every response names the build it came from (build-info.json).
+	var buildHeader string
+	if app != nil && app.Build != nil {
+		buildHeader = fmt.Sprintf("\t// Build provenance\n\tr.Use(func(c *gin.Context) {\n\t\tc.Header(%q, %q)\n\t\tc.Next()\n\t})\n\n", ir.BuildHeader, app.Build.Header())
+	}
+
 	return fmt.Sprintf(`package main
 
 import (
 	"context"
-	"log"
+%s	"log"
 	"net/http"
 	"os"
 	"os/signal"
@@ -60,24 +175,26 @@ import (
 
 	"%s/config"
 	"%s/database"
-	"%s/routes"
+%s%s%s	"%s/routes"
 )
 
-func main() {
+%sfunc main() {
+	if err := config.Validate(); err != nil {
+		log.Fatal(err)
+	}
 	cfg := config.Load()
-
+%s
 	db, err := database.Connect(cfg)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %%v", err)
 	}
 
-	r := gin.Default()
-
-	// CORS Middleware
+%s
+%s	// CORS Middleware
 	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		c.Writer.Header().Set("Access-Control-Allow-Origin", %q)
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "%s")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -87,7 +204,7 @@ func main() {
 		c.Next()
 	})
 
-	routes.Setup(r, db)
+%s%s	routes.Setup(r, db)
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
@@ -101,12 +218,12 @@ func main() {
 	}()
 
 	log.Printf("Server running on port %%s", cfg.Port)
-
+%s
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
-
+%s
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
@@ -115,17 +232,23 @@ func main() {
 
 	log.Println("Server exiting")
 }
-`, moduleName, moduleName, moduleName)
+`, embedImport, moduleName, moduleName, grpcImport, jobsImport, middlewareImport, moduleName, specVar, workerStart, router, buildHeader, corsOrigin, corsHeaders, auditUse, validateUse, grpcStart, grpcStop)
 }
 
-func generateConfig(moduleName string) string {
-	return `package config
+func generateConfig(moduleName string, app *ir.Application) string {
+	replicaField, replicaLoad, replicaValue := "", "", ""
+	if ir.UsesReadReplica(app) {
+		replicaField = "\tDatabaseReplicaURL string // reads go here when set\n"
+		replicaLoad = "\n\treplicaUrl := os.Getenv(\"DATABASE_REPLICA_URL\")\n"
+		replicaValue = "\t\tDatabaseReplicaURL: replicaUrl,\n"
+	}
+	src := `package config
 
 import "os"
 
 type Config struct {
 	DatabaseURL string
-	JWTSecret   string
+` + replicaField + `	JWTSecret   string
 	Port        string
 }
 
@@ -139,7 +262,7 @@ func Load() *Config {
 	if dbUrl == "" {
 		dbUrl = "host=localhost user=postgres password=postgres dbname=postgres port=5432 sslmode=disable"
 	}
-
+` + replicaLoad + `
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
 		jwtSecret = "supersecretkey"
@@ -147,9 +270,14 @@ func Load() *Config {
 
 	return &Config{
 		DatabaseURL: dbUrl,
-		JWTSecret:   jwtSecret,
+` + replicaValue + `		JWTSecret:   jwtSecret,
 		Port:        port,
 	}
 }
 `
+	// Realign the struct and literal around the replica field.
+	if formatted, err := format.Source([]byte(src)); err == nil {
+		return string(formatted)
+	}
+	return src
 }
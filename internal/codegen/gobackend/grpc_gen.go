@@ -0,0 +1,174 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/grpc"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateGrpcServer produces grpcserver/server.go, which implements the
+// generated service interface by dispatching each RPC to the gin router
+// in-process. RPCs therefore run the same middleware, validation, and
+// business steps as the REST routes.
+func generateGrpcServer(moduleName string, app *ir.Application) string {
+	var sb strings.Builder
+	svc := grpc.ServiceName(app)
+
+	sb.WriteString(fmt.Sprintf(`package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "%s"
+)
+
+// Server implements pb.%sServer on top of the REST handler.
+type Server struct {
+	pb.Unimplemented%sServer
+	handler http.Handler
+}
+
+// New returns a Server that dispatches RPCs to handler.
+func New(handler http.Handler) *Server {
+	return &Server{handler: handler}
+}
+
+// Serve starts a gRPC server on addr in the background.
+func Serve(addr string, handler http.Handler) (*grpc.Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := grpc.NewServer()
+	pb.Register%sServer(s, New(handler))
+	go s.Serve(lis)
+	return s, nil
+}
+`, grpc.StubImportPath(app), svc, svc, svc))
+
+	for _, rpc := range grpc.RPCs(app) {
+		params := "nil"
+		if len(rpc.Fields) > 0 {
+			var pairs []string
+			for _, f := range rpc.Fields {
+				pairs = append(pairs, fmt.Sprintf("%q: req.Get%s()", toCamelCase(f.Param), f.GoName()))
+			}
+			params = "map[string]any{" + strings.Join(pairs, ", ") + "}"
+		}
+		sb.WriteString(fmt.Sprintf(`
+func (s *Server) %s(ctx context.Context, req *pb.%sRequest) (*pb.%sResponse, error) {
+	resp := &pb.%sResponse{}
+	err := s.forward(ctx, %q, "/api%s", %s, %v, resp)
+	return resp, err
+}
+`, rpc.Name, rpc.Name, rpc.Name, rpc.Name, httpMethod(rpc.Endpoint.Name), routePath(rpc.Endpoint.Name), params, rpc.JSONData()))
+	}
+
+	var jsonFields []string
+	for _, f := range grpc.JSONFields(app) {
+		jsonFields = append(jsonFields, fmt.Sprintf("%q: true", toCamelCase(f)))
+	}
+
+	sb.WriteString(fmt.Sprintf(`
+// jsonFields are json model fields, which proto messages carry as
+// JSON-encoded strings.
+var jsonFields = map[string]bool{%s}
+
+// forward runs the REST route for an RPC and decodes its JSON response
+// into out. jsonData marks responses whose data has no proto model.
+func (s *Server) forward(ctx context.Context, method, path string, params map[string]any, jsonData bool, out proto.Message) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	r := httptest.NewRequest(method, path, bytes.NewReader(body)).WithContext(ctx)
+	r.Header.Set("Content-Type", "application/json")
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			r.Header.Set("Authorization", v[0])
+		}
+	}
+	w := httptest.NewRecorder()
+	s.handler.ServeHTTP(w, r)
+
+	payload := map[string]any{}
+	_ = json.Unmarshal(w.Body.Bytes(), &payload)
+	if w.Code >= 400 {
+		msg, _ := payload["error"].(string)
+		if msg == "" {
+			msg = http.StatusText(w.Code)
+		}
+		return status.Error(grpcCode(w.Code), msg)
+	}
+
+	if jsonData {
+		data, _ := json.Marshal(payload["data"])
+		payload["data"] = string(data)
+	} else {
+		payload["data"] = encodeJSONFields(payload["data"])
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(encoded, out); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+func encodeJSONFields(v any) any {
+	switch v := v.(type) {
+	case []any:
+		for i := range v {
+			v[i] = encodeJSONFields(v[i])
+		}
+	case map[string]any:
+		for k, field := range v {
+			if _, isString := field.(string); jsonFields[k] && !isString {
+				b, _ := json.Marshal(field)
+				v[k] = string(b)
+			} else {
+				v[k] = encodeJSONFields(field)
+			}
+		}
+	}
+	return v
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+`, strings.Join(jsonFields, ", ")))
+
+	return sb.String()
+}
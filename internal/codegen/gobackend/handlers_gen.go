@@ -9,8 +9,9 @@ import (
 
 // modelFieldInfo holds type information for a model field.
 type modelFieldInfo struct {
-	exists   bool
-	required bool
+	exists    bool
+	required  bool
+	encrypted bool // a fieldcrypt.Text on the model
 }
 
 // modelFieldSet builds a map of field names (lowercase) → info for a given model.
@@ -19,7 +20,7 @@ func modelFieldSet(app *ir.Application, modelName string) map[string]modelFieldI
 	for _, m := range app.Data {
 		if strings.EqualFold(m.Name, modelName) {
 			for _, f := range m.Fields {
-				fields[strings.ToLower(f.Name)] = modelFieldInfo{exists: true, required: f.Required}
+				fields[strings.ToLower(f.Name)] = modelFieldInfo{exists: true, required: f.Required, encrypted: f.EncryptsAtRest()}
 			}
 			// Add foreign key fields from belongs_to relations
 			for _, r := range m.Relations {
@@ -33,11 +34,26 @@ func modelFieldSet(app *ir.Application, modelName string) map[string]modelFieldI
 
 func generateHandlers(moduleName string, app *ir.Application) string {
 	hasIntegrations := len(app.Integrations) > 0
+	usesFieldCrypt := false
 
 	var sb strings.Builder
 	sb.WriteString("package handlers\n\nimport (\n")
-	sb.WriteString("\t\"net/http\"\n\n")
-	sb.WriteString("\t\"github.com/gin-gonic/gin\"\n")
+	if invitesTeammates(app) {
+		sb.WriteString("\t\"crypto/rand\"\n")
+		sb.WriteString("\t\"encoding/hex\"\n")
+	}
+	sb.WriteString("\t\"net/http\"\n")
+	if invitesTeammates(app) {
+		sb.WriteString("\t\"os\"\n")
+	}
+	if hasPagedEndpoints(app) {
+		sb.WriteString("\t\"strconv\"\n")
+	}
+	if invitesTeammates(app) {
+		sb.WriteString("\t\"strings\"\n")
+		sb.WriteString("\t\"time\"\n")
+	}
+	sb.WriteString("\n\t\"github.com/gin-gonic/gin\"\n")
 	sb.WriteString("\t\"gorm.io/gorm\"\n\n")
 	sb.WriteString(fmt.Sprintf("\t\"%s/config\"\n", moduleName))
 	sb.WriteString(fmt.Sprintf("\t\"%s/dto\"\n", moduleName))
@@ -47,6 +63,9 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 		sb.WriteString(fmt.Sprintf("\t\"%s/services\"\n", moduleName))
 	}
 	sb.WriteString(")\n\n")
+	if ir.AccountModel(app) != nil {
+		writeProfileOf(&sb, app)
+	}
 
 	for _, api := range app.APIs {
 		isLogin := isLoginEndpoint(api.Name)
@@ -69,9 +88,34 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 			}
 		}
 
+		// Reports return their aggregate's rows with a cache hint
+		if agg := api.Aggregate; agg != nil {
+			scoped := api.Auth && modelBelongsToUser(agg.Model, app) && !strings.EqualFold(agg.Model, "User")
+			fmt.Fprintf(&sb, "\t\tc.Header(\"Cache-Control\", %q)\n", cacheControl(api))
+			writeAggregateQuery(&sb, agg, scoped)
+			sb.WriteString("\t}\n}\n\n")
+			continue
+		}
+
+		// Imports check and save each record of the uploaded file
+		if api.Import != nil {
+			usesFieldCrypt = writeImportHandler(&sb, api, app) || usesFieldCrypt
+			sb.WriteString("\t}\n}\n\n")
+			continue
+		}
+
+		// Account endpoints act on the signed-in user
+		if api.Account != "" && app.Accounts != nil {
+			usesFieldCrypt = writeAccountHandler(&sb, api, app) || usesFieldCrypt
+			sb.WriteString("\t}\n}\n\n")
+			continue
+		}
+
 		// Track state
 		queryModelName := ""
+		createModelName := ""
 		queryUsedItems := false // true if we queried a list (items), false if single (item)
+		paged := pagedModel(api)
 		hasCreate := false
 		hasReturn := false
 
@@ -91,6 +135,7 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 					continue
 				}
 				hasCreate = true
+				createModelName = modelName
 
 				fields := modelFieldSet(app, modelName)
 
@@ -103,12 +148,8 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 						if pLower == "password" {
 							sb.WriteString("\t\t\tPassword: hashedPassword,\n")
 						} else if fi, ok := fields[pLower]; ok && fi.exists {
-							pName := toPascalCase(p.Name)
-							if !fi.required {
-								sb.WriteString(fmt.Sprintf("\t\t\t%s: &req.%s,\n", pName, pName))
-							} else {
-								sb.WriteString(fmt.Sprintf("\t\t\t%s: req.%s,\n", pName, pName))
-							}
+							writeCreateField(&sb, toPascalCase(p.Name), fi)
+							usesFieldCrypt = usesFieldCrypt || fi.encrypted
 						}
 					}
 					sb.WriteString("\t\t}\n")
@@ -121,12 +162,8 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 						}
 						// Only assign if this field exists on the model
 						if fi, ok := fields[pLower]; ok && fi.exists {
-							pName := toPascalCase(p.Name)
-							if !fi.required {
-								sb.WriteString(fmt.Sprintf("\t\t\t%s: &req.%s,\n", pName, pName))
-							} else {
-								sb.WriteString(fmt.Sprintf("\t\t\t%s: req.%s,\n", pName, pName))
-							}
+							writeCreateField(&sb, toPascalCase(p.Name), fi)
+							usesFieldCrypt = usesFieldCrypt || fi.encrypted
 						}
 					}
 					if api.Auth {
@@ -165,10 +202,17 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 						sb.WriteString(fmt.Sprintf("\t\t\tc.JSON(http.StatusNotFound, gin.H{\"error\": \"%s not found\"})\n", modelName))
 					}
 					sb.WriteString("\t\t\treturn\n\t\t}\n")
+				} else if paged != "" {
+					queryUsedItems = true
+					writePagedQuery(&sb, modelName, api, app)
 				} else if strings.Contains(lowerText, "all") || strings.Contains(lowerText, "where") {
 					queryUsedItems = true
 					sb.WriteString(fmt.Sprintf("\t\tvar items []models.%s\n", toPascalCase(modelName)))
-					sb.WriteString("\t\tif err := db.Find(&items).Error; err != nil {\n\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to fetch items\"})\n\t\t\treturn\n\t\t}\n")
+					find := "db"
+					if r := ir.ReorderFor(app, modelName); r != nil {
+						find = fmt.Sprintf("db.Order(\"%s asc\")", toSnakeCase(toPascalCase(r.Field)))
+					}
+					sb.WriteString("\t\tif err := " + find + ".Find(&items).Error; err != nil {\n\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to fetch items\"})\n\t\t\treturn\n\t\t}\n")
 				} else {
 					idParam := findIDParam(api)
 					sb.WriteString(fmt.Sprintf("\t\tvar item models.%s\n", toPascalCase(modelName)))
@@ -187,10 +231,21 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 
 			case "update":
 				lowerText := strings.ToLower(step.Text)
+				target := findModel(app, inferModelFromAction(step.Text))
 				if strings.Contains(lowerText, "update") && strings.Contains(lowerText, "with") {
+					if m := ir.ConflictModel(app, api); m != nil && m == target && len(api.Params) > 0 {
+						writeVersionedUpdate(&sb, m)
+						continue
+					}
 					sb.WriteString("\t\tif err := db.Model(&item).Updates(req).Error; err != nil {\n\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to update\"})\n\t\t\treturn\n\t\t}\n")
 				} else if strings.Contains(lowerText, "update") && strings.Contains(lowerText, "status") {
 					sb.WriteString("\t\tif err := db.Model(&item).Update(\"status\", req.Status).Error; err != nil {\n\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to update\"})\n\t\t\treturn\n\t\t}\n")
+				} else {
+					continue
+				}
+				// Any other change to a versioned record makes edits of it stale
+				if target != nil && target.Versioned {
+					sb.WriteString(versionBump())
 				}
 
 			case "delete":
@@ -210,12 +265,21 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 					sb.WriteString("\t\tif err := services.SendSlackMessage(\"Action completed\"); err != nil {\n")
 					sb.WriteString("\t\t\t_ = err\n")
 					sb.WriteString("\t\t}\n")
+				case "sms":
+					writeSMSCall(&sb, step, api)
 				default:
 					sb.WriteString("\t\t// TODO: no matching integration service configured\n")
 				}
 
 			case "respond":
 				hasReturn = true
+				record := ""
+				if hasCreate {
+					record = "newItem"
+				} else if queryModelName != "" && !queryUsedItems {
+					record = "item"
+				}
+				writeNotifyCalls(&sb, api, app, record)
 				lowerText := strings.ToLower(step.Text)
 				if (isLogin || isSignUp) && strings.Contains(lowerText, "token") {
 					if isLogin {
@@ -235,6 +299,8 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 					sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": item})\n")
 				} else if strings.Contains(lowerText, "deleted") {
 					sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"message\": \"Deleted successfully\"})\n")
+				} else if paged != "" {
+					sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": items, \"pagination\": gin.H{\"limit\": limit, \"nextCursor\": nextCursor}})\n")
 				} else if queryUsedItems {
 					sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": items})\n")
 				} else if hasCreate {
@@ -244,6 +310,17 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 				} else {
 					sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"message\": \"Success\"})\n")
 				}
+
+			default:
+				if doc := ir.DocumentFor(app, step.Text); doc != nil {
+					record := ""
+					if hasCreate && strings.EqualFold(createModelName, doc.Model) {
+						record = "newItem"
+					} else if !queryUsedItems && strings.EqualFold(queryModelName, doc.Model) {
+						record = "item"
+					}
+					writeDocumentCall(&sb, doc, api, app, record)
+				}
 			}
 		}
 
@@ -254,7 +331,28 @@ func generateHandlers(moduleName string, app *ir.Application) string {
 		sb.WriteString("\t}\n}\n\n")
 	}
 
-	return sb.String()
+	out := sb.String()
+	if usesFieldCrypt {
+		dtoImport := fmt.Sprintf("\t\"%s/dto\"\n", moduleName)
+		out = strings.Replace(out, dtoImport, dtoImport+fmt.Sprintf("\t\"%s/fieldcrypt\"\n", moduleName), 1)
+	}
+	return out
+}
+
+// writeCreateField assigns a request field to the new record. Optional
+// fields are pointers on the model, and encrypted fields are converted to
+// fieldcrypt.Text whatever type the request gives them.
+func writeCreateField(sb *strings.Builder, name string, fi modelFieldInfo) {
+	switch {
+	case fi.encrypted && fi.required:
+		sb.WriteString(fmt.Sprintf("\t\t\t%s: fieldcrypt.Text(req.%s),\n", name, name))
+	case fi.encrypted:
+		sb.WriteString(fmt.Sprintf("\t\t\t%s: (*fieldcrypt.Text)(&req.%s),\n", name, name))
+	case fi.required:
+		sb.WriteString(fmt.Sprintf("\t\t\t%s: req.%s,\n", name, name))
+	default:
+		sb.WriteString(fmt.Sprintf("\t\t\t%s: &req.%s,\n", name, name))
+	}
 }
 
 // detectSendIntegration inspects the step text and app integrations to determine
@@ -263,6 +361,13 @@ func detectSendIntegration(text string, app *ir.Application) string {
 	lower := strings.ToLower(text)
 
 	// Check for explicit keywords in the step text.
+	if _, ok := ir.ParseSMSStep(text); ok {
+		for _, integ := range app.Integrations {
+			if integ.Type == "sms" {
+				return "sms"
+			}
+		}
+	}
 	if strings.Contains(lower, "email") || strings.Contains(lower, "mail") {
 		for _, integ := range app.Integrations {
 			if integ.Type == "email" {
@@ -0,0 +1,82 @@
+package gobackend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// hookMethods are the GORM hook methods model hooks run in, by event and
+// timing.
+var hookMethods = []struct{ event, timing, method string }{
+	{"create", "before", "BeforeCreate"},
+	{"create", "after", "AfterCreate"},
+	{"update", "before", "BeforeUpdate"},
+	{"update", "after", "AfterUpdate"},
+	{"delete", "before", "BeforeDelete"},
+	{"delete", "after", "AfterDelete"},
+}
+
+// generateModelHooks produces models/hooks.go: the models' lifecycle hooks
+// as GORM hook methods, so they run on every write of the model whichever
+// handler makes it. Writes without a loaded record, such as
+// db.Where(...).Delete(&Task{}), don't run them.
+func generateModelHooks(app *ir.Application) string {
+	var sb strings.Builder
+	sb.WriteString("package models\n\nimport \"gorm.io/gorm\"\n")
+	for _, m := range app.Data {
+		hooks := ir.HooksOn(app, m)
+		for _, hm := range hookMethods {
+			var lines []string
+			for _, hook := range hooks {
+				if hook.Event == hm.event {
+					lines = append(lines, goHookLines(app, m, hook, hm.timing)...)
+				}
+			}
+			if len(lines) == 0 {
+				continue
+			}
+			name := toPascalCase(m.Name)
+			fmt.Fprintf(&sb, "\nfunc (m *%s) %s(tx *gorm.DB) error {\n", name, hm.method)
+			for _, line := range lines {
+				fmt.Fprintf(&sb, "\t%s\n", line)
+			}
+			sb.WriteString("\treturn nil\n}\n")
+		}
+	}
+	return sb.String()
+}
+
+// goHookLines returns the lines of a hook on model m that run at timing. A
+// delete hook's removals always run before the delete, since the foreign
+// keys of the records removed would block it.
+func goHookLines(app *ir.Application, m *ir.DataModel, hook *ir.ModelHook, timing string) []string {
+	var lines []string
+	for _, step := range hook.Steps {
+		s := ir.ResolveHookStep(app, hook, m, step)
+		switch {
+		case s != nil && s.Remove != nil:
+			if timing == "before" {
+				lines = append(lines,
+					fmt.Sprintf("if err := tx.Session(&gorm.Session{NewDB: true}).Where(\"%s_id = ?\", m.ID).Delete(&%s{}).Error; err != nil {", toSnakeCase(m.Name), toPascalCase(s.Remove.Name)),
+					"\treturn err",
+					"}")
+			}
+		case hook.Timing != timing:
+		case s == nil:
+			lines = append(lines, "// TODO: "+step.Text)
+		default:
+			value := s.Value
+			if s.Quoted() {
+				value = strconv.Quote(value)
+			}
+			lines = append(lines, fmt.Sprintf("tx.Statement.SetColumn(%q, %s)", toPascalCase(s.Field.Name), value))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return append([]string{fmt.Sprintf("// %s a %s is %sd", hook.Timing, m.Name, hook.Event)}, lines...)
+}
@@ -0,0 +1,404 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateImportHelpers produces handlers/imports.go: reading an imported
+// file's records and checking their fields.
+func generateImportHelpers() string {
+	return fmt.Sprintf(`package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBytes is the size of the largest file an import accepts.
+const maxImportBytes = %d
+
+// importError is a problem with one record of an imported file. Records are
+// numbered from 1, not counting a CSV file's header line.
+type importError struct {
+	Row     int    `+"`json:\"row\"`"+`
+	Field   string `+"`json:\"field,omitempty\"`"+`
+	Message string `+"`json:\"message\"`"+`
+}
+
+// importSummary is what an import did, or on a dry run would do.
+type importSummary struct {
+	Mode     string        `+"`json:\"mode\"`"+`
+	DryRun   bool          `+"`json:\"dryRun\"`"+`
+	Total    int           `+"`json:\"total\"`"+`
+	Imported int           `+"`json:\"imported\"`"+`
+	Failed   int           `+"`json:\"failed\"`"+`
+	Errors   []importError `+"`json:\"errors\"`"+`
+}
+
+var nonColumnChars = regexp.MustCompile(`+"`[^a-z0-9]`"+`)
+
+// columnKey normalizes a column name, so "Due date", "due_date", and
+// "dueDate" name the same field.
+func columnKey(name string) string {
+	return nonColumnChars.ReplaceAllString(strings.ToLower(name), "")
+}
+
+// readImport reads the records of the file uploaded to an import,
+// answering 413 or 400 itself when it can't.
+func readImport(c *gin.Context) ([]map[string]string, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "The file is larger than %d MB"})
+		return nil, false
+	}
+	records, err := parseRecords(body, c.ContentType())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return records, true
+}
+
+// parseRecords reads an imported file's records, each value as text: a JSON
+// array of objects, or CSV with a header row.
+func parseRecords(body []byte, contentType string) ([]map[string]string, error) {
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+	if strings.Contains(contentType, "json") || bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var parsed []any
+		if err := dec.Decode(&parsed); err != nil {
+			return nil, errors.New("The file should hold a JSON array of records")
+		}
+		records := make([]map[string]string, len(parsed))
+		for i, record := range parsed {
+			values := map[string]string{}
+			fields, _ := record.(map[string]any)
+			for key, value := range fields {
+				switch v := value.(type) {
+				case nil:
+				case map[string]any, []any:
+					raw, _ := json.Marshal(v)
+					values[columnKey(key)] = string(raw)
+				default:
+					values[columnKey(key)] = fmt.Sprint(v)
+				}
+			}
+			records[i] = values
+		}
+		return records, nil
+	}
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.New("The file is not valid CSV")
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		keys[i] = columnKey(name)
+	}
+	records := make([]map[string]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		values := map[string]string{}
+		for i, cell := range row {
+			if i < len(keys) {
+				values[keys[i]] = cell
+			}
+		}
+		records = append(records, values)
+	}
+	return records, nil
+}
+
+// rejectImport answers an all-or-nothing import that saved nothing.
+func rejectImport(c *gin.Context, summary importSummary, format string, args ...any) {
+	c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf(format, args...), "data": summary})
+}
+
+// sortImportErrors orders an import's problems by record.
+func sortImportErrors(errs []importError) {
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Row < errs[j].Row })
+}
+
+// importRecord reads the fields of one imported record, noting each
+// problem. Each reader returns the field's value and whether it has one: a
+// blank field or one with a problem has none.
+type importRecord struct {
+	values map[string]string
+	row    int
+	errors []importError
+}
+
+// valueOf drops whether a required field has a value; a record missing one
+// has a problem noted and isn't saved.
+func valueOf[T any](v T, _ bool) T {
+	return v
+}
+
+// optional returns a pointer to an optional field's value, or nil.
+func optional[T any](v T, ok bool) *T {
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+func (r *importRecord) value(field string, required bool) (string, bool) {
+	v := strings.TrimSpace(r.values[columnKey(field)])
+	if v == "" && required {
+		r.problem(field, field+" is required")
+	}
+	return v, v != ""
+}
+
+func (r *importRecord) problem(field, message string) {
+	r.errors = append(r.errors, importError{Row: r.row, Field: field, Message: message})
+}
+
+func (r *importRecord) text(field string, required bool) (string, bool) {
+	return r.value(field, required)
+}
+
+func (r *importRecord) email(field string, required bool) (string, bool) {
+	v, ok := r.value(field, required)
+	if _, err := mail.ParseAddress(v); ok && err != nil {
+		r.problem(field, field+" must be an email address")
+		return "", false
+	}
+	return v, ok
+}
+
+func (r *importRecord) url(field string, required bool) (string, bool) {
+	v, ok := r.value(field, required)
+	if u, err := url.ParseRequestURI(v); ok && (err != nil || u.Host == "") {
+		r.problem(field, field+" must be a URL")
+		return "", false
+	}
+	return v, ok
+}
+
+func (r *importRecord) number(field string, required bool) (int, bool) {
+	v, ok := r.value(field, required)
+	n, err := strconv.Atoi(v)
+	if ok && err != nil {
+		r.problem(field, field+" must be a whole number")
+		return 0, false
+	}
+	return n, ok
+}
+
+func (r *importRecord) decimal(field string, required bool) (float64, bool) {
+	v, ok := r.value(field, required)
+	n, err := strconv.ParseFloat(v, 64)
+	if ok && err != nil {
+		r.problem(field, field+" must be a number")
+		return 0, false
+	}
+	return n, ok
+}
+
+func (r *importRecord) boolean(field string, required bool) (bool, bool) {
+	v, ok := r.value(field, required)
+	switch strings.ToLower(v) {
+	case "true", "yes", "1":
+		return true, true
+	case "false", "no", "0":
+		return false, true
+	}
+	if ok {
+		r.problem(field, field+" must be true or false")
+	}
+	return false, false
+}
+
+func (r *importRecord) date(field string, required bool) (time.Time, bool) {
+	v, ok := r.value(field, required)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	r.problem(field, field+" must be a date, e.g. 2024-01-31")
+	return time.Time{}, false
+}
+
+func (r *importRecord) oneOf(field string, values []string, required bool) (string, bool) {
+	v, ok := r.value(field, required)
+	if !ok {
+		return "", false
+	}
+	for _, allowed := range values {
+		if strings.EqualFold(allowed, v) {
+			return allowed, true
+		}
+	}
+	r.problem(field, field+" must be one of "+strings.Join(values, ", "))
+	return "", false
+}
+
+func (r *importRecord) jsonValue(field string, required bool) (map[string]any, bool) {
+	v, ok := r.value(field, required)
+	if !ok {
+		return nil, false
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(v), &obj); err != nil {
+		r.problem(field, field+" must be a JSON object")
+		return nil, false
+	}
+	return obj, true
+}
+`, ir.MaxImportBytes, ir.MaxImportBytes>>20)
+}
+
+// writeImportHandler writes the body of an import handler. Each record of
+// the file is checked against the model's fields; records with problems
+// are reported by row and field. An all-or-nothing import saves nothing
+// unless every record is valid, and saves them in one transaction; one that
+// continues on error saves each valid record it can. ?dry_run=true only
+// checks the file.
+func writeImportHandler(sb *strings.Builder, api *ir.Endpoint, app *ir.Application) (usesFieldCrypt bool) {
+	im := api.Import
+	var m *ir.DataModel
+	for _, dm := range app.Data {
+		if dm.Name == im.Model {
+			m = dm
+		}
+	}
+	model := toPascalCase(m.Name)
+	scoped := api.Auth && modelBelongsToUser(m.Name, app) && !strings.EqualFold(m.Name, "User")
+
+	sb.WriteString("\t\trecords, ok := readImport(c)\n")
+	sb.WriteString("\t\tif !ok {\n\t\t\treturn\n\t\t}\n")
+	sb.WriteString("\t\tdryRun := c.Query(\"dry_run\") == \"true\"\n")
+	fmt.Fprintf(sb, "\t\tsummary := importSummary{Mode: %q, DryRun: dryRun, Total: len(records), Errors: []importError{}}\n", im.Mode)
+	fmt.Fprintf(sb, "\t\ttype validRecord struct {\n\t\t\trow  int\n\t\t\titem models.%s\n\t\t}\n", model)
+	sb.WriteString("\t\tvar valid []validRecord\n")
+	sb.WriteString("\t\tfor i, values := range records {\n")
+	sb.WriteString("\t\t\trecord := &importRecord{values: values, row: i + 1}\n")
+	fmt.Fprintf(sb, "\t\t\titem := models.%s{\n", model)
+	for _, col := range ir.ImportColumns(m, api.Auth) {
+		if col.Field == nil {
+			fmt.Fprintf(sb, "\t\t\t\t%sID: valueOf(record.text(%q, true)),\n", toPascalCase(col.Relation), col.Name)
+			continue
+		}
+		f := col.Field
+		read := importReader(col)
+		switch {
+		case f.EncryptsAtRest() && f.Required:
+			read = "fieldcrypt.Text(valueOf(" + read + "))"
+			usesFieldCrypt = true
+		case f.EncryptsAtRest():
+			read = "(*fieldcrypt.Text)(optional(" + read + "))"
+			usesFieldCrypt = true
+		case f.Required:
+			read = "valueOf(" + read + ")"
+		default:
+			read = "optional(" + read + ")"
+		}
+		fmt.Fprintf(sb, "\t\t\t\t%s: %s,\n", toPascalCase(f.Name), read)
+	}
+	if scoped {
+		sb.WriteString("\t\t\t\tUserID: c.GetString(\"userID\"),\n")
+	}
+	sb.WriteString("\t\t\t}\n")
+	sb.WriteString("\t\t\tif len(record.errors) > 0 {\n")
+	sb.WriteString("\t\t\t\tsummary.Failed++\n")
+	sb.WriteString("\t\t\t\tsummary.Errors = append(summary.Errors, record.errors...)\n")
+	sb.WriteString("\t\t\t\tcontinue\n")
+	sb.WriteString("\t\t\t}\n")
+	sb.WriteString("\t\t\tvalid = append(valid, validRecord{row: record.row, item: item})\n")
+	sb.WriteString("\t\t}\n\n")
+
+	if im.Mode == ir.ImportContinueOnError {
+		sb.WriteString("\t\tif !dryRun {\n")
+		sb.WriteString("\t\t\tfor _, v := range valid {\n")
+		sb.WriteString("\t\t\t\tif err := db.Create(&v.item).Error; err != nil {\n")
+		sb.WriteString("\t\t\t\t\tsummary.Failed++\n")
+		sb.WriteString("\t\t\t\t\tsummary.Errors = append(summary.Errors, importError{Row: v.row, Message: \"This record could not be saved\"})\n")
+		sb.WriteString("\t\t\t\t\tcontinue\n")
+		sb.WriteString("\t\t\t\t}\n")
+		sb.WriteString("\t\t\t\tsummary.Imported++\n")
+		sb.WriteString("\t\t\t}\n")
+		sb.WriteString("\t\t\tsortImportErrors(summary.Errors)\n")
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": summary})\n")
+		return usesFieldCrypt
+	}
+
+	sb.WriteString("\t\tif dryRun {\n")
+	sb.WriteString("\t\t\tc.JSON(http.StatusOK, gin.H{\"data\": summary})\n")
+	sb.WriteString("\t\t\treturn\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\tif summary.Failed > 0 {\n")
+	sb.WriteString("\t\t\trejectImport(c, summary, \"Nothing was imported: %d of %d records have problems\", summary.Failed, summary.Total)\n")
+	sb.WriteString("\t\t\treturn\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\trow := 0\n")
+	sb.WriteString("\t\tif err := db.Transaction(func(tx *gorm.DB) error {\n")
+	sb.WriteString("\t\t\tfor _, v := range valid {\n")
+	sb.WriteString("\t\t\t\trow = v.row\n")
+	sb.WriteString("\t\t\t\tif err := tx.Create(&v.item).Error; err != nil {\n")
+	sb.WriteString("\t\t\t\t\treturn err\n")
+	sb.WriteString("\t\t\t\t}\n")
+	sb.WriteString("\t\t\t}\n")
+	sb.WriteString("\t\t\treturn nil\n")
+	sb.WriteString("\t\t}); err != nil {\n")
+	sb.WriteString("\t\t\tsummary.Failed = 1\n")
+	sb.WriteString("\t\t\tsummary.Errors = append(summary.Errors, importError{Row: row, Message: \"This record could not be saved\"})\n")
+	sb.WriteString("\t\t\trejectImport(c, summary, \"Nothing was imported: record %d could not be saved\", row)\n")
+	sb.WriteString("\t\t\treturn\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\tsummary.Imported = len(valid)\n")
+	sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": summary})\n")
+	return usesFieldCrypt
+}
+
+// importReader returns the importRecord call reading a column's field, as
+// its value and whether it has one.
+func importReader(col *ir.ImportColumn) string {
+	f := col.Field
+	required := fmt.Sprint(f.Required)
+	switch f.Type {
+	case "boolean":
+		// A blank boolean is false, as an unchecked box would be
+		return fmt.Sprintf("record.boolean(%q, false)", col.Name)
+	case "email", "url", "number", "decimal":
+		return fmt.Sprintf("record.%s(%q, %s)", f.Type, col.Name, required)
+	case "date", "datetime":
+		return fmt.Sprintf("record.date(%q, %s)", col.Name, required)
+	case "json":
+		return fmt.Sprintf("record.jsonValue(%q, %s)", col.Name, required)
+	case "enum":
+		values := make([]string, len(f.EnumValues))
+		for i, v := range f.EnumValues {
+			values[i] = fmt.Sprintf("%q", v)
+		}
+		return fmt.Sprintf("record.oneOf(%q, []string{%s}, %s)", col.Name, strings.Join(values, ", "), required)
+	}
+	return fmt.Sprintf("record.text(%q, %s)", col.Name, required)
+}
@@ -26,8 +26,13 @@ func generateIntegrations(moduleName string, app *ir.Application) map[string]str
 			files["services/stripe.go"] = generatePaymentService(moduleName, integ)
 		case "messaging":
 			files["services/slack.go"] = generateMessagingService(moduleName, integ)
+		case "sms":
+			files["services/sms.go"] = generateSMSService(integ, app)
+			files["services/sms_test.go"] = generateSMSTests()
 		case "oauth":
 			files["services/oauth.go"] = generateOAuthService(moduleName, integ)
+		case "analytics":
+			files["services/analytics.go"] = generateAnalyticsService(moduleName, integ)
 		default:
 			files["services/"+toSnakeCase(integ.Service)+".go"] = generateGenericGoService(moduleName, integ)
 		}
@@ -251,6 +256,112 @@ func generateOAuthService(_ string, integ *ir.Integration) string {
 	return b.String()
 }
 
+// generateAnalyticsService produces a Go service that sends server-side
+// events to the analytics provider's HTTP API.
+func generateAnalyticsService(_ string, integ *ir.Integration) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n")
+	fmt.Fprintf(&b, "// Integration: %s (analytics)\n", integ.Service)
+	b.WriteString("package services\n\n")
+
+	provider := analyticsProvider(integ.Service)
+
+	keyEnv := map[string]string{
+		"posthog":   "POSTHOG_API_KEY",
+		"segment":   "SEGMENT_WRITE_KEY",
+		"mixpanel":  "MIXPANEL_TOKEN",
+		"amplitude": "AMPLITUDE_API_KEY",
+	}[provider]
+	for _, envVar := range integ.Credentials {
+		keyEnv = envVar
+		break
+	}
+
+	b.WriteString("import (\n")
+	b.WriteString("\t\"bytes\"\n")
+	b.WriteString("\t\"context\"\n")
+	b.WriteString("\t\"encoding/json\"\n")
+	b.WriteString("\t\"fmt\"\n")
+	b.WriteString("\t\"net/http\"\n")
+	b.WriteString("\t\"os\"\n")
+	b.WriteString("\t\"time\"\n")
+	b.WriteString(")\n\n")
+
+	b.WriteString("var analyticsClient = &http.Client{Timeout: 5 * time.Second}\n\n")
+
+	fmt.Fprintf(&b, "// Track sends an event to %s. It is a no-op when %s is unset.\n", integ.Service, keyEnv)
+	b.WriteString("func Track(ctx context.Context, distinctID, event string, properties map[string]any) error {\n")
+	fmt.Fprintf(&b, "\tapiKey := os.Getenv(\"%s\")\n", keyEnv)
+	b.WriteString("\tif apiKey == \"\" {\n")
+	b.WriteString("\t\treturn nil\n")
+	b.WriteString("\t}\n\n")
+
+	switch provider {
+	case "posthog":
+		b.WriteString("\thost := os.Getenv(\"POSTHOG_HOST\")\n")
+		b.WriteString("\tif host == \"\" {\n")
+		b.WriteString("\t\thost = \"https://us.i.posthog.com\"\n")
+		b.WriteString("\t}\n")
+		b.WriteString("\turl := host + \"/capture/\"\n")
+		b.WriteString("\tpayload := map[string]any{\"api_key\": apiKey, \"event\": event, \"distinct_id\": distinctID, \"properties\": properties}\n")
+	case "segment":
+		b.WriteString("\turl := \"https://api.segment.io/v1/track\"\n")
+		b.WriteString("\tpayload := map[string]any{\"anonymousId\": distinctID, \"event\": event, \"properties\": properties}\n")
+	case "mixpanel":
+		b.WriteString("\turl := \"https://api.mixpanel.com/track\"\n")
+		b.WriteString("\tprops := map[string]any{\"token\": apiKey, \"distinct_id\": distinctID}\n")
+		b.WriteString("\tfor k, v := range properties {\n")
+		b.WriteString("\t\tprops[k] = v\n")
+		b.WriteString("\t}\n")
+		b.WriteString("\tpayload := []map[string]any{{\"event\": event, \"properties\": props}}\n")
+	default: // amplitude
+		b.WriteString("\turl := \"https://api2.amplitude.com/2/httpapi\"\n")
+		b.WriteString("\tpayload := map[string]any{\"api_key\": apiKey, \"events\": []map[string]any{{\"device_id\": distinctID, \"event_type\": event, \"event_properties\": properties}}}\n")
+	}
+
+	b.WriteString("\n\tbody, err := json.Marshal(payload)\n")
+	b.WriteString("\tif err != nil {\n")
+	b.WriteString("\t\treturn err\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\treq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))\n")
+	b.WriteString("\tif err != nil {\n")
+	b.WriteString("\t\treturn err\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\treq.Header.Set(\"Content-Type\", \"application/json\")\n")
+	if provider == "segment" {
+		b.WriteString("\treq.SetBasicAuth(apiKey, \"\")\n")
+	}
+	b.WriteString("\n\tresp, err := analyticsClient.Do(req)\n")
+	b.WriteString("\tif err != nil {\n")
+	b.WriteString("\t\treturn err\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\tdefer resp.Body.Close()\n")
+	b.WriteString("\tif resp.StatusCode >= 300 {\n")
+	fmt.Fprintf(&b, "\t\treturn fmt.Errorf(\"%s rejected event %%s: %%s\", event, resp.Status)\n", integ.Service)
+	b.WriteString("\t}\n")
+	b.WriteString("\treturn nil\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// analyticsProvider maps an analytics integration's service name to the
+// provider whose HTTP API the generated service calls.
+func analyticsProvider(service string) string {
+	s := strings.ToLower(service)
+	switch {
+	case strings.Contains(s, "posthog"):
+		return "posthog"
+	case strings.Contains(s, "segment"):
+		return "segment"
+	case strings.Contains(s, "mixpanel"):
+		return "mixpanel"
+	default:
+		return "amplitude"
+	}
+}
+
 // generateGenericGoService produces a minimal Go service for unknown integrations.
 func generateGenericGoService(_ string, integ *ir.Integration) string {
 	var b strings.Builder
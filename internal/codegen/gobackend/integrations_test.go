@@ -124,3 +124,41 @@ func TestOAuthHandlerGenerated(t *testing.T) {
 		t.Error("should generate GoogleCallback handler")
 	}
 }
+
+func TestGenerateSMSServiceGo(t *testing.T) {
+	app := &ir.Application{
+		Integrations: []*ir.Integration{
+			{Service: "Twilio", Type: "sms", Credentials: map[string]string{"account sid": "TWILIO_SID", "auth token": "TWILIO_TOKEN"}},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "ShipOrder", Params: []*ir.Param{{Name: "phone"}}, Steps: []*ir.Action{
+				{Type: "send", Text: "send WhatsApp message saying Your order has shipped"},
+			}},
+		},
+	}
+
+	files := generateIntegrations("testapp", app)
+	content, ok := files["services/sms.go"]
+	if !ok {
+		t.Fatal("Twilio should generate services/sms.go")
+	}
+	for _, check := range []string{
+		`sid := os.Getenv("TWILIO_SID")`,
+		`req.SetBasicAuth(sid, os.Getenv("TWILIO_TOKEN"))`,
+		"smsAttempts   = 3",
+		"Outbox = append(Outbox, msg)",
+		"func SendWhatsApp(to, body string) error",
+	} {
+		if !strings.Contains(content, check) {
+			t.Errorf("sms service missing %q", check)
+		}
+	}
+	if _, ok := files["services/sms_test.go"]; !ok {
+		t.Error("missing sandbox tests for the SMS service")
+	}
+
+	handlers := generateHandlers("testapp", app)
+	if !strings.Contains(handlers, `services.SendWhatsApp(req.Phone, "Your order has shipped")`) {
+		t.Error("ShipOrder should message the phone param over WhatsApp")
+	}
+}
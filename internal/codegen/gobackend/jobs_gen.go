@@ -0,0 +1,105 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateJobs produces jobs/jobs.go: the workflow jobs, queued in Redis
+// with asynq, and RunWorker, which the binary runs when started as
+// "<binary> worker".
+func generateJobs(app *ir.Application) string {
+	var handlers, funcs strings.Builder
+	for _, wf := range app.Workflows {
+		jobs := ir.WorkflowJobs(wf)
+		for i, job := range jobs {
+			fn := "on" + toPascalCase(strings.ReplaceAll(job.Name, "-", " "))
+			fmt.Fprintf(&handlers, "\tmux.HandleFunc(%q, %s)\n", job.Name, fn)
+
+			if job.Delay == 0 {
+				fmt.Fprintf(&funcs, "\n// %s runs when %s.\n", fn, job.Trigger)
+			} else {
+				fmt.Fprintf(&funcs, "\n// %s runs %s after %s.\n", fn, job.After, job.Trigger)
+			}
+			fmt.Fprintf(&funcs, "func %s(ctx context.Context, t *asynq.Task) error {\n", fn)
+			for _, step := range job.Steps {
+				fmt.Fprintf(&funcs, "\t// TODO: %s\n", step.Text)
+			}
+			if i == 0 {
+				for _, later := range jobs[1:] {
+					fmt.Fprintf(&funcs, "\tif err := Enqueue(%q, json.RawMessage(t.Payload()), %s); err != nil {\n\t\treturn err\n\t}\n", later.Name, goDuration(later.Delay.Hours()))
+				}
+			}
+			funcs.WriteString("\treturn nil\n}\n")
+		}
+	}
+
+	var doc strings.Builder
+	for _, wf := range app.Workflows {
+		fmt.Fprintf(&doc, "//\tjobs.Enqueue(%q, data, 0) // when %s\n", ir.JobName(wf.Trigger), wf.Trigger)
+	}
+
+	return `// Package jobs runs workflows in the background. Queue a job where its
+// trigger happens:
+//
+` + doc.String() + `package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+func redisOpt() asynq.RedisConnOpt {
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		url = "redis://localhost:6379"
+	}
+	opt, err := asynq.ParseRedisURI(url)
+	if err != nil {
+		log.Fatalf("Invalid REDIS_URL: %v", err)
+	}
+	return opt
+}
+
+var client = sync.OnceValue(func() *asynq.Client {
+	return asynq.NewClient(redisOpt())
+})
+
+// Enqueue queues the job called name for the worker, delay from now.
+func Enqueue(name string, data any, delay time.Duration) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = client().Enqueue(asynq.NewTask(name, payload), asynq.ProcessIn(delay), asynq.MaxRetry(3))
+	return err
+}
+
+// RunWorker runs queued jobs until the process is stopped.
+func RunWorker() error {
+	srv := asynq.NewServer(redisOpt(), asynq.Config{Concurrency: 10})
+	mux := asynq.NewServeMux()
+` + handlers.String() + `	log.Println("Worker processing jobs")
+	return srv.Run(mux)
+}
+` + funcs.String()
+}
+
+// goDuration writes hours as a Go duration expression.
+func goDuration(hours float64) string {
+	switch {
+	case hours >= 1 && hours == float64(int64(hours)):
+		return fmt.Sprintf("%d*time.Hour", int64(hours))
+	case hours*60 == float64(int64(hours*60)):
+		return fmt.Sprintf("%d*time.Minute", int64(hours*60))
+	}
+	return fmt.Sprintf("%d*time.Second", int64(hours*3600))
+}
@@ -0,0 +1,185 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateMasking produces middleware/masking.go, which keeps fields
+// marked personal out of the request log and error responses. Field names
+// are compared without case or separators, so "birthDate", "birth_date",
+// and "birth date" all match. SQL logging is handled in database.Connect.
+func generateMasking(app *ir.Application) string {
+	var names []string
+	for _, name := range ir.PersonalFieldNames(app) {
+		names = append(names, fmt.Sprintf("%q: true", strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(name))))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// personalFields are the fields marked personal in the .human file.
+`)
+	fmt.Fprintf(&sb, "var personalFields = map[string]bool{%s}\n", strings.Join(names, ", "))
+	sb.WriteString(`
+const redacted = "[REDACTED]"
+
+// pairPattern matches key: value and key=value pairs in log lines and
+// error messages.
+var pairPattern = regexp.MustCompile(` + "`" + `(["']?)([A-Za-z][\w-]*)["']?(\s*[:=]\s*)("[^"]*"|'[^']*'|[^\s,&;}\]]+)` + "`" + `)
+
+func isPersonal(key string) bool {
+	return personalFields[strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(key))]
+}
+
+// MaskText masks the values of personal key: value pairs, and any of
+// values wherever they appear.
+func MaskText(text string, values ...string) string {
+	masked := pairPattern.ReplaceAllStringFunc(text, func(pair string) string {
+		m := pairPattern.FindStringSubmatch(pair)
+		if !isPersonal(m[2]) {
+			return pair
+		}
+		q := ""
+		if strings.HasPrefix(m[4], "\"") || strings.HasPrefix(m[4], "'") {
+			q = m[4][:1]
+		}
+		return strings.TrimSuffix(pair, m[4]) + q + redacted + q
+	})
+	for _, v := range values {
+		if v != "" {
+			masked = strings.ReplaceAll(masked, v, redacted)
+		}
+	}
+	return masked
+}
+
+// MaskPersonalData returns a copy of a decoded JSON value with personal
+// fields replaced, at any depth.
+func MaskPersonalData(value any, values ...string) any {
+	switch v := value.(type) {
+	case string:
+		return MaskText(v, values...)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = MaskPersonalData(item, values...)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			if isPersonal(key) {
+				out[key] = redacted
+			} else {
+				out[key] = MaskPersonalData(item, values...)
+			}
+		}
+		return out
+	}
+	return value
+}
+
+// personalValues returns the personal values in a decoded request body.
+func personalValues(value any) []string {
+	var found []string
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			found = append(found, personalValues(item)...)
+		}
+	case map[string]any:
+		for key, item := range v {
+			if s, ok := item.(string); ok && s != "" && isPersonal(key) {
+				found = append(found, s)
+			} else {
+				found = append(found, personalValues(item)...)
+			}
+		}
+	}
+	return found
+}
+
+// MaskedLogFormatter is gin's request log line with personal query
+// parameters masked.
+func MaskedLogFormatter(p gin.LogFormatterParams) string {
+	return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+		p.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		p.StatusCode,
+		p.Latency.Truncate(time.Microsecond),
+		p.ClientIP,
+		p.Method,
+		MaskText(p.Path),
+		p.ErrorMessage,
+	)
+}
+
+// maskingWriter holds back error response bodies so they can be masked.
+type maskingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *maskingWriter) Write(b []byte) (int, error) {
+	if w.Status() < 400 {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+func (w *maskingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// MaskErrorResponses masks personal fields in error responses, including
+// values the client sent that an error message repeats.
+func MaskErrorResponses() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var values []string
+		if c.Request.Body != nil && c.ContentType() == "application/json" {
+			body, _ := io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			var decoded any
+			if json.Unmarshal(body, &decoded) == nil {
+				values = personalValues(decoded)
+			}
+		}
+		for key, vs := range c.Request.URL.Query() {
+			if isPersonal(key) {
+				values = append(values, vs...)
+			}
+		}
+
+		w := &maskingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		if w.body.Len() == 0 {
+			return
+		}
+		var payload any
+		if err := json.Unmarshal(w.body.Bytes(), &payload); err != nil {
+			w.ResponseWriter.WriteString(MaskText(w.body.String(), values...))
+			return
+		}
+		masked, _ := json.Marshal(MaskPersonalData(payload, values...))
+		w.ResponseWriter.Write(masked)
+	}
+}
+`)
+	return sb.String()
+}
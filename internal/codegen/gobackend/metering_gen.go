@@ -0,0 +1,332 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateUsageService produces services/usage.go: counting a user's calls
+// per period, how many they've made, the quota they're held to, and, when
+// the app bills, reporting calls to a Stripe meter.
+func generateUsageService(moduleName string, app *ir.Application) string {
+	var sb strings.Builder
+	m := app.Metering
+	fk := toSnakeCase(m.Model) + "_id"
+	user := toPascalCase(m.Model)
+	bills := ir.Bills(app)
+
+	sb.WriteString("package services\n\n// Generated by Human compiler — do not edit\n\nimport (\n")
+	if bills {
+		sb.WriteString("\t\"os\"\n")
+	}
+	sb.WriteString("\t\"time\"\n\n")
+	if bills {
+		sb.WriteString("\t\"github.com/stripe/stripe-go/v81\"\n")
+		sb.WriteString("\t\"github.com/stripe/stripe-go/v81/billing/meterevent\"\n")
+	}
+	sb.WriteString(fmt.Sprintf("\t\"gorm.io/gorm\"\n\n\t\"%s/models\"\n)\n\n", moduleName))
+
+	sb.WriteString("// MeterPeriod is the period calls are counted per: \"day\", \"week\", or \"month\".\n")
+	sb.WriteString(fmt.Sprintf("const MeterPeriod = %q\n\n", m.Period))
+	sb.WriteString("// Quota caps a user's calls: how many they may make per period.\n")
+	sb.WriteString("type Quota struct {\n\tLimit  int\n\tPeriod string\n}\n\n")
+	if bills {
+		sb.WriteString("// quotas are each plan's subscribers' quotas, from the policy named after\n// the plan.\n")
+	} else {
+		sb.WriteString("// quotas are each role's quota, from the policy named after it.\n")
+	}
+	sb.WriteString("var quotas = map[string]Quota{\n")
+	for _, q := range ir.UsageQuotas(app) {
+		if !q.Enforced(app) {
+			continue
+		}
+		key := q.Policy
+		if q.Plan != "" {
+			key = q.Plan
+		}
+		sb.WriteString(fmt.Sprintf("\t%q: {Limit: %d, Period: %q},\n", key, q.Limit, q.Period))
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(`// PeriodStart returns the start of the period t falls in, in UTC. Weeks
+// start on Sunday.
+func PeriodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case "week":
+		return day.AddDate(0, 0, -int(day.Weekday()))
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// AddPeriods returns the start of the period n periods after the one
+// starting at start.
+func AddPeriods(start time.Time, period string, n int) time.Time {
+	switch period {
+	case "week":
+		return start.AddDate(0, 0, 7*n)
+	case "month":
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, n)
+}
+
+// QuotaFor returns the quota a user is held to; ok is false when their
+// calls aren't capped.
+`)
+	sb.WriteString(fmt.Sprintf("func QuotaFor(db *gorm.DB, user *models.%s) (quota Quota, ok bool, err error) {\n", user))
+	switch {
+	case bills:
+		sb.WriteString("\tplan, err := CurrentPlan(db, user.ID)\n")
+		sb.WriteString("\tif err != nil || plan == \"\" {\n\t\treturn Quota{}, false, err\n\t}\n")
+		sb.WriteString("\tquota, ok = quotas[plan]\n")
+		sb.WriteString("\treturn quota, ok, nil\n")
+	case ir.QuotaByRole(app):
+		if f := findModel(app, m.Model).FieldNamed("role"); !f.Required {
+			sb.WriteString("\tif user.Role == nil {\n\t\treturn Quota{}, false, nil\n\t}\n")
+			sb.WriteString("\tquota, ok = quotas[*user.Role]\n")
+		} else {
+			sb.WriteString("\tquota, ok = quotas[user.Role]\n")
+		}
+		sb.WriteString("\treturn quota, ok, nil\n")
+	default:
+		sb.WriteString("\treturn Quota{}, false, nil\n")
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(fmt.Sprintf(`// CallsSince returns how many calls a user has made since the start of a
+// period.
+func CallsSince(db *gorm.DB, userID string, since time.Time) (int, error) {
+	var total int
+	err := db.Model(&models.UsageRecord{}).
+		Where("%[1]s = ? AND period_start >= ?", userID, since).
+		Select("COALESCE(SUM(calls), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// UsagePeriod is a user's calls in one period.
+type UsagePeriod struct {
+	Start time.Time `+"`json:\"start\"`"+`
+	Calls int       `+"`json:\"calls\"`"+`
+}
+
+// CallHistory returns a user's calls in each of the last periods, oldest
+// first, with none counted as 0.
+func CallHistory(db *gorm.DB, userID string, periods int) ([]UsagePeriod, error) {
+	since := AddPeriods(PeriodStart(MeterPeriod, time.Now()), MeterPeriod, 1-periods)
+	var records []models.UsageRecord
+	if err := db.Where("%[1]s = ? AND period_start >= ?", userID, since).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	calls := map[int64]int{}
+	for _, record := range records {
+		calls[record.PeriodStart.Unix()] += record.Calls
+	}
+	history := make([]UsagePeriod, periods)
+	for i := range history {
+		start := AddPeriods(since, MeterPeriod, i)
+		history[i] = UsagePeriod{Start: start, Calls: calls[start.Unix()]}
+	}
+	return history, nil
+}
+
+// RecordCall counts a call in the user's record for the current period.
+// Two first calls at once may each create a record; counts add up all of
+// them.
+func RecordCall(db *gorm.DB, userID string) error {
+	start := PeriodStart(MeterPeriod, time.Now())
+	result := db.Model(&models.UsageRecord{}).
+		Where("%[1]s = ? AND period_start = ?", userID, start).
+		UpdateColumn("calls", gorm.Expr("calls + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return db.Create(&models.UsageRecord{%[2]sID: userID, PeriodStart: start, Calls: 1}).Error
+	}
+	return nil
+}
+`, fk, user))
+
+	if bills {
+		sb.WriteString(`
+// ReportCall reports a call to the Stripe meter named by
+// STRIPE_USAGE_METER, which metered prices bill by. Nothing is reported
+// when it isn't set or the user has no active subscription.
+func ReportCall(db *gorm.DB, userID string) error {
+	meter := os.Getenv("STRIPE_USAGE_METER")
+	if meter == "" {
+		return nil
+	}
+	subscription, err := LatestSubscription(db, userID)
+	if err != nil || !Active(subscription) {
+		return err
+	}
+	_, err = meterevent.New(&stripe.BillingMeterEventParams{
+		EventName: stripe.String(meter),
+		Payload:   map[string]string{"stripe_customer_id": subscription.StripeCustomerID, "value": "1"},
+	})
+	return err
+}
+`)
+	}
+	return sb.String()
+}
+
+// generateMeteringMiddleware produces middleware/metering.go: Meter(),
+// which counts the signed-in user's call and refuses it once they've made
+// as many as their quota allows this period.
+func generateMeteringMiddleware(moduleName string, app *ir.Application) string {
+	bills := ir.Bills(app)
+	imports := "\t\"fmt\"\n"
+	if bills {
+		imports += "\t\"log\"\n"
+	}
+	imports += "\t\"math\"\n\t\"net/http\"\n\t\"strconv\"\n\t\"time\"\n"
+	upgrade, report := "", ""
+	if bills {
+		upgrade = ": upgrade your plan for more"
+		report = `		go func(userID string) {
+			if err := services.ReportCall(db, userID); err != nil {
+				log.Printf("Could not report usage to Stripe: %v", err)
+			}
+		}(user.ID)
+`
+	}
+
+	return fmt.Sprintf(`package middleware
+
+// Generated by Human compiler — do not edit
+
+import (
+%[1]s
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"%[2]s/models"
+	"%[2]s/services"
+)
+
+// Meter returns a Gin middleware that counts the signed-in user's calls.
+//
+// Usage:
+//
+//	api.GET("/forecast", middleware.RequireAuth(db, cfg), middleware.Meter(db), handlers.GetForecast(db, cfg))
+//
+// Behavior:
+//  1. If the user has made all the calls their quota allows this period → %[3]d, with Retry-After
+//  2. Otherwise → the call is counted and allowed
+func Meter(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.%[4]s)
+		quota, ok, err := services.QuotaFor(db, user)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check usage"})
+			return
+		}
+		if ok {
+			start := services.PeriodStart(quota.Period, time.Now())
+			used, err := services.CallsSince(db, user.ID, start)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check usage"})
+				return
+			}
+			if used >= quota.Limit {
+				wait := time.Until(services.AddPeriods(start, quota.Period, 1))
+				c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+					"error": fmt.Sprintf("You've made all %%d of your API calls this %%s%[5]s", quota.Limit, quota.Period),
+				})
+				return
+			}
+		}
+		if err := services.RecordCall(db, user.ID); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to record usage"})
+			return
+		}
+%[6]s		c.Next()
+	}
+}
+`, imports, moduleName, ir.QuotaExceededStatus, toPascalCase(app.Metering.Model), upgrade, report)
+}
+
+// generateUsageHandlers produces handlers/usage.go: the signed-in user's
+// calls this period against their quota, and their calls in each of the
+// last periods for the usage dashboard.
+func generateUsageHandlers(moduleName string, app *ir.Application) string {
+	return fmt.Sprintf(`package handlers
+
+// Generated by Human compiler — do not edit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"%[1]s/models"
+	"%[1]s/services"
+)
+
+// UsageSummary returns the signed-in user's calls this period: per their
+// quota, else per the metering period.
+func UsageSummary(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.%[2]s)
+		quota, ok, err := services.QuotaFor(db, user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch usage"})
+			return
+		}
+		period := services.MeterPeriod
+		var limit any
+		if ok {
+			period, limit = quota.Period, quota.Limit
+		}
+		start := services.PeriodStart(period, time.Now())
+		used, err := services.CallsSince(db, user.ID, start)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch usage"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{
+			"period":   period,
+			"used":     used,
+			"limit":    limit,
+			"resetsAt": services.AddPeriods(start, period, 1),
+		}})
+	}
+}
+
+// UsageHistory returns the signed-in user's calls in each of the last
+// ?periods= periods (30 by default, at most 365), oldest first.
+func UsageHistory(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.%[2]s)
+		periods := 30
+		if n, err := strconv.Atoi(c.Query("periods")); err == nil {
+			periods = n
+		}
+		if periods < 1 {
+			periods = 1
+		} else if periods > 365 {
+			periods = 365
+		}
+		history, err := services.CallHistory(db, user.ID, periods)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch usage"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": history})
+	}
+}
+`, moduleName, toPascalCase(app.Metering.Model))
+}
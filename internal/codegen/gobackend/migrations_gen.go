@@ -0,0 +1,135 @@
+package gobackend
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/migrate"
+)
+
+// generateMigrations returns the app's migration history as golang-migrate
+// files, by path relative to the backend: an up and a down .sql per
+// migration, the migrations package that database.Connect applies them
+// with, and cmd/migrate/main.go, which applies them without starting the
+// server (`go run ./cmd/migrate`, `human migrate`).
+func generateMigrations(moduleName string, app *ir.Application) map[string]string {
+	history := migrate.History(app)
+	if len(history) == 0 {
+		return nil
+	}
+	files := map[string]string{
+		"migrations/migrations.go": generateMigrationsPackage(),
+		"cmd/migrate/main.go":      generateMigrateCommand(moduleName),
+	}
+	dialect := gormDialect()
+	header := "-- Generated by Human compiler — do not edit\n-- Migration: %s (%s)\n\n"
+	for _, m := range history {
+		files[path.Join("migrations", m.Name+".up.sql")] = fmt.Sprintf(header, m.Name, "up") + dialect.Up(m)
+		files[path.Join("migrations", m.Name+".down.sql")] = fmt.Sprintf(header, m.Name, "down") + dialect.Down(m)
+	}
+	return files
+}
+
+// gormDialect lays out tables as GORM names the models' structs: plural
+// snake_case tables, snake_case columns, and UUID ids.
+func gormDialect() migrate.Dialect {
+	return migrate.Dialect{
+		Table:      func(model string) string { return pluralize(toSnakeCase(toPascalCase(model))) },
+		Column:     func(field string) string { return toSnakeCase(toPascalCase(field)) },
+		ForeignKey: func(target string) string { return toSnakeCase(toPascalCase(target)) + "_id" },
+		Type: func(f *ir.DataField) string {
+			if f.EncryptsAtRest() {
+				return "TEXT"
+			}
+			switch goType(f.Type, true) {
+			case "int":
+				return "BIGINT"
+			case "float64":
+				return "NUMERIC"
+			case "bool":
+				return "BOOLEAN"
+			case "time.Time":
+				return "TIMESTAMPTZ"
+			case "map[string]any":
+				return "JSONB"
+			}
+			return "TEXT"
+		},
+		Constraint: func(table, column, kind string) string {
+			switch kind {
+			case "key":
+				return "idx_" + table + "_" + column
+			case "fkey":
+				return "fk_" + table + "_" + column
+			}
+			return table + "_pkey"
+		},
+		ID:         "UUID NOT NULL DEFAULT gen_random_uuid()",
+		Reference:  "UUID",
+		Timestamps: []string{`"created_at" TIMESTAMPTZ`, `"updated_at" TIMESTAMPTZ`},
+	}
+}
+
+func generateMigrationsPackage() string {
+	return `// Package migrations applies the database schema's migrations, the .sql
+// files beside this one, generated from the app's schema history.
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Up applies the migrations db hasn't had.
+func Up(db *sql.DB) error {
+	source, err := iofs.New(files, ".")
+	if err != nil {
+		return err
+	}
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return err
+	}
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+`
+}
+
+func generateMigrateCommand(moduleName string) string {
+	return fmt.Sprintf(`// Command migrate applies the database schema's migrations, as the
+// server does when it connects. Run with:
+//
+//	go run ./cmd/migrate
+package main
+
+import (
+	"log"
+
+	"%[1]s/config"
+	"%[1]s/database"
+)
+
+func main() {
+	if _, err := database.Connect(config.Load()); err != nil {
+		log.Fatal(err)
+	}
+	log.Print("The database is up to date")
+}
+`, moduleName)
+}
@@ -0,0 +1,122 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeNotifyCalls emits a notify() call for each in-app notification the
+// endpoint triggers. The recipient is the user named by a param (user_id,
+// assignee, ...), else the record's owner, else the current user.
+func writeNotifyCalls(sb *strings.Builder, api *ir.Endpoint, app *ir.Application, record string) {
+	for _, n := range app.Notifications {
+		if !n.Triggers(api) {
+			continue
+		}
+		recipient := ""
+		if param := n.RecipientParam(api); param != "" {
+			recipient = "req." + toPascalCase(param)
+		} else if record != "" && modelFieldSet(app, n.Model)["user_id"].exists {
+			recipient = record + ".UserID"
+		} else if api.Auth {
+			recipient = "c.MustGet(\"user\").(*models.User).ID"
+		}
+		if recipient == "" {
+			sb.WriteString("\t\t// TODO: no recipient for the in-app notification — require authentication\n")
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\t\tnotify(db, %s, %q)\n", recipient, n.Message))
+	}
+}
+
+// generateNotificationHandlers produces handlers/notifications.go: notify()
+// for storing an in-app notification, and the current user's
+// notifications, their unread count, and marking them read. The frontend
+// dropdown polls the unread count.
+func generateNotificationHandlers(moduleName string) string {
+	return fmt.Sprintf(`package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"%s/models"
+)
+
+// notify stores an in-app notification. Failures are logged rather than
+// failing the request that triggered them.
+func notify(db *gorm.DB, userID any, message string) {
+	notification := models.Notification{UserID: fmt.Sprint(userID), Message: message}
+	if err := db.Create(&notification).Error; err != nil {
+		log.Printf("Failed to store notification: %%v", err)
+	}
+}
+
+func currentUserID(c *gin.Context) string {
+	return c.MustGet("user").(*models.User).ID
+}
+
+// ListNotifications returns the current user's 50 latest notifications.
+func ListNotifications(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var notifications []models.Notification
+		if err := db.Where("user_id = ?", currentUserID(c)).Order("created_at desc").Limit(50).Find(&notifications).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": notifications})
+	}
+}
+
+// UnreadNotificationCount returns how many notifications are unread.
+func UnreadNotificationCount(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var count int64
+		if err := db.Model(&models.Notification{}).Where("user_id = ? AND seen IS NULL", currentUserID(c)).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"count": count}})
+	}
+}
+
+// ReadAllNotifications marks every unread notification as read.
+func ReadAllNotifications(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result := db.Model(&models.Notification{}).Where("user_id = ? AND seen IS NULL", currentUserID(c)).Update("seen", time.Now())
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"count": result.RowsAffected}})
+	}
+}
+
+// ReadNotification marks one of the current user's notifications as read.
+func ReadNotification(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var notification models.Notification
+		if err := db.Where("id = ? AND user_id = ?", c.Param("id"), currentUserID(c)).First(&notification).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+			return
+		}
+		if notification.Seen == nil {
+			now := time.Now()
+			notification.Seen = &now
+			if err := db.Save(&notification).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+				return
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"data": notification})
+	}
+}
+`, moduleName)
+}
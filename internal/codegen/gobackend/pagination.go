@@ -0,0 +1,125 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// pagedModel returns the model a paginated endpoint serves a page at a
+// time: the one its first query fetches all of. It returns "" when the
+// endpoint doesn't paginate.
+func pagedModel(api *ir.Endpoint) string {
+	if api.PageSize == 0 {
+		return ""
+	}
+	for _, step := range api.Steps {
+		if step.Type != "query" {
+			continue
+		}
+		model := inferModelFromAction(step.Text)
+		if model == "" {
+			continue
+		}
+		lower := strings.ToLower(step.Text)
+		if !strings.Contains(lower, " by ") && (strings.Contains(lower, "all") || strings.Contains(lower, "where")) {
+			return model
+		}
+		return ""
+	}
+	return ""
+}
+
+// hasPagedEndpoints reports whether any endpoint serves its list a page at
+// a time.
+func hasPagedEndpoints(app *ir.Application) bool {
+	for _, api := range app.APIs {
+		if pagedModel(api) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// writePagedQuery fetches a page of items: the one the page query
+// parameter numbers, or else the one after the record the cursor names.
+// One extra record is fetched to tell whether another page follows.
+// Records come newest first, or in the order users dragged them into, with
+// records never dragged last, unless sort orders them by one of the
+// model's fields; sorted records are paged by number, since a cursor only
+// follows the default order. The endpoint's filters and q narrow them
+// first.
+func writePagedQuery(sb *strings.Builder, model string, api *ir.Endpoint, app *ir.Application) {
+	typ := "models." + toPascalCase(model)
+	order, key, cmp := "created_at desc, id desc", "created_at, id", "<"
+	if r := ir.ReorderFor(app, model); r != nil {
+		pos := fmt.Sprintf("COALESCE(%s, 2147483647)", toSnakeCase(toPascalCase(r.Field)))
+		order, key, cmp = pos+" asc, id asc", pos+", id", ">"
+	}
+	params := ir.ListParamsFor(app, api, model)
+	if params == nil {
+		params = &ir.ListParams{}
+	}
+	fmt.Fprintf(sb, "\t\tlimit := %d\n", api.PageSize)
+	sb.WriteString("\t\tif n, err := strconv.Atoi(c.Query(\"limit\")); err == nil && n > 0 && n <= 100 {\n")
+	sb.WriteString("\t\t\tlimit = n\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\tquery := db\n")
+	for _, f := range params.Filters {
+		column := toSnakeCase(toPascalCase(f.Name))
+		switch f.Type {
+		case "boolean":
+			fmt.Fprintf(sb, "\t\tif v := c.Query(%q); v == \"true\" || v == \"false\" {\n", f.Name)
+			fmt.Fprintf(sb, "\t\t\tquery = query.Where(\"%s = ?\", v == \"true\")\n", column)
+		case "number":
+			fmt.Fprintf(sb, "\t\tif v, err := strconv.ParseFloat(c.Query(%q), 64); err == nil {\n", f.Name)
+			fmt.Fprintf(sb, "\t\t\tquery = query.Where(\"%s = ?\", v)\n", column)
+		default:
+			fmt.Fprintf(sb, "\t\tif v := c.Query(%q); v != \"\" {\n", f.Name)
+			fmt.Fprintf(sb, "\t\t\tquery = query.Where(\"%s = ?\", v)\n", column)
+		}
+		sb.WriteString("\t\t}\n")
+	}
+	if len(params.Search) > 0 {
+		var likes, args []string
+		for _, f := range params.Search {
+			likes = append(likes, fmt.Sprintf("LOWER(%s) LIKE LOWER(?)", toSnakeCase(toPascalCase(f.Name))))
+			args = append(args, "like")
+		}
+		sb.WriteString("\t\tif q := c.Query(\"q\"); q != \"\" {\n")
+		sb.WriteString("\t\t\tlike := \"%\" + q + \"%\"\n")
+		fmt.Fprintf(sb, "\t\t\tquery = query.Where(%q, %s)\n", strings.Join(likes, " OR "), strings.Join(args, ", "))
+		sb.WriteString("\t\t}\n")
+	}
+	var columns []string
+	for _, f := range params.Sortable {
+		columns = append(columns, fmt.Sprintf("%q: %q", f.Name, toSnakeCase(toPascalCase(f.Name))))
+	}
+	fmt.Fprintf(sb, "\t\tsortColumns := map[string]string{%s}\n", strings.Join(columns, ", "))
+	sb.WriteString("\t\tpage, _ := strconv.Atoi(c.Query(\"page\"))\n")
+	sb.WriteString("\t\tif column, ok := sortColumns[c.Query(\"sort\")]; ok || page > 0 {\n")
+	sb.WriteString("\t\t\tif ok {\n")
+	sb.WriteString("\t\t\t\tdirection := \"asc\"\n")
+	sb.WriteString("\t\t\t\tif c.Query(\"order\") == \"desc\" {\n")
+	sb.WriteString("\t\t\t\t\tdirection = \"desc\"\n")
+	sb.WriteString("\t\t\t\t}\n")
+	sb.WriteString("\t\t\t\tquery = query.Order(column + \" \" + direction + \", id \" + direction)\n")
+	sb.WriteString("\t\t\t} else {\n")
+	fmt.Fprintf(sb, "\t\t\t\tquery = query.Order(%q)\n", order)
+	sb.WriteString("\t\t\t}\n")
+	sb.WriteString("\t\t\tquery = query.Offset((max(page, 1) - 1) * limit)\n")
+	sb.WriteString("\t\t} else {\n")
+	fmt.Fprintf(sb, "\t\t\tquery = query.Order(%q)\n", order)
+	sb.WriteString("\t\t\tif cursor := c.Query(\"cursor\"); cursor != \"\" {\n")
+	fmt.Fprintf(sb, "\t\t\t\tquery = query.Where(\"(%s) %s (?)\", db.Model(&%s{}).Select(%q).Where(\"id = ?\", cursor))\n", key, cmp, typ, key)
+	sb.WriteString("\t\t\t}\n")
+	sb.WriteString("\t\t}\n")
+	fmt.Fprintf(sb, "\t\tvar items []%s\n", typ)
+	sb.WriteString("\t\tif err := query.Limit(limit + 1).Find(&items).Error; err != nil {\n\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to fetch items\"})\n\t\t\treturn\n\t\t}\n")
+	sb.WriteString("\t\tvar nextCursor *string\n")
+	sb.WriteString("\t\tif len(items) > limit {\n")
+	sb.WriteString("\t\t\tnextCursor = &items[limit-1].ID\n")
+	sb.WriteString("\t\t\titems = items[:limit]\n")
+	sb.WriteString("\t\t}\n")
+}
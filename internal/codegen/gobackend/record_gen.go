@@ -0,0 +1,129 @@
+package gobackend
+
+import (
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateRecord produces middleware/record.go: with request recording on
+// (`human run --record`), every API request and the response it got is
+// written to an .http file that `human replay` can send again. It does
+// nothing otherwise.
+func generateRecord() string {
+	return `package middleware
+
+// Generated by Human compiler — do not edit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// skippedHeaders are left out of recordings; the rest are sent again on
+// replay as they were.
+var skippedHeaders = map[string]bool{
+	"Host":              true,
+	"Connection":        true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Accept-Encoding":   true,
+}
+
+var (
+	recordSeq atomic.Int64
+	slugChars = regexp.MustCompile(` + "`[^a-z0-9]+`" + `)
+)
+
+// recordingWriter keeps a copy of the response body it writes.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Record writes each API request to its own .http file in the directory
+// ` + ir.RecordEnv + ` names, in the VS Code REST Client format, with the
+// response it got in the comments above it, so it can be sent again from
+// the editor or with ` + "`human replay`" + `. It does nothing without it.
+func Record() gin.HandlerFunc {
+	dir := os.Getenv("` + ir.RecordEnv + `")
+	if dir == "" {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Printf("[record] Not recording: %v", err)
+		return func(c *gin.Context) { c.Next() }
+	}
+	log.Printf("[record] Recording API requests to %s", dir)
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	return func(c *gin.Context) {
+		recordedAt := time.Now().UTC()
+		var body []byte
+		if c.Request.Body != nil {
+			body, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "@host = http://localhost:%s\n\n###\n", port)
+		fmt.Fprintf(&b, "# Recorded: %s\n", recordedAt.Format("2006-01-02T15:04:05.000Z"))
+		fmt.Fprintf(&b, "# Response: %d (%d ms)\n", w.Status(), time.Since(recordedAt).Milliseconds())
+		for _, line := range strings.Split(w.body.String(), "\n") {
+			if line != "" {
+				fmt.Fprintf(&b, "# %s\n", line)
+			}
+		}
+		fmt.Fprintf(&b, "%s {{host}}%s\n", c.Request.Method, c.Request.URL.RequestURI())
+		names := make([]string, 0, len(c.Request.Header))
+		for name := range c.Request.Header {
+			if !skippedHeaders[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s: %s\n", name, strings.Join(c.Request.Header[name], ", "))
+		}
+		if len(body) > 0 {
+			fmt.Fprintf(&b, "\n%s\n", body)
+		}
+
+		slug := strings.Trim(slugChars.ReplaceAllString(strings.ToLower(strings.TrimPrefix(c.Request.URL.Path, "/api/")), "-"), "-")
+		if slug == "" {
+			slug = "root"
+		}
+		stamp := strings.NewReplacer(":", "-", ".", "-").Replace(recordedAt.Format("2006-01-02T15:04:05.000Z"))
+		name := fmt.Sprintf("%s-%04d-%s-%s.http", stamp, recordSeq.Add(1), c.Request.Method, slug)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(b.String()), 0644); err != nil {
+			log.Printf("[record] Could not record %s %s: %v", c.Request.Method, c.Request.URL.RequestURI(), err)
+		}
+	}
+}
+`
+}
@@ -0,0 +1,68 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// reorderHandler returns the handler saving a list's drag-and-drop order:
+// "tasks" → "ReorderTasks".
+func reorderHandler(r *ir.Reorder) string {
+	return "Reorder" + toPascalCase(r.Slug)
+}
+
+// generateReorderHandlers produces handlers/reorder.go: for each list users
+// can drag into a new order, a handler taking {"ids"} in their new order
+// and numbering the model's position column from 0 in one transaction. A
+// model that belongs to a user only reorders the signed-in user's records.
+func generateReorderHandlers(moduleName string, app *ir.Application) string {
+	var sb strings.Builder
+
+	sb.WriteString("package handlers\n\n")
+	sb.WriteString("import (\n")
+	sb.WriteString("\t\"net/http\"\n\n")
+	sb.WriteString("\t\"github.com/gin-gonic/gin\"\n")
+	sb.WriteString("\t\"gorm.io/gorm\"\n\n")
+	fmt.Fprintf(&sb, "\t\"%s/models\"\n", moduleName)
+	sb.WriteString(")\n\n")
+	sb.WriteString("// ReorderRequest lists record ids in their new order.\n")
+	sb.WriteString("type ReorderRequest struct {\n")
+	sb.WriteString("\tIDs []string `json:\"ids\" binding:\"required\"`\n")
+	sb.WriteString("}\n")
+
+	for _, r := range app.Reorders {
+		scoped := app.Auth != nil && modelBelongsToUser(r.Model, app) && !strings.EqualFold(r.Model, "User")
+		records := strings.ReplaceAll(r.Slug, "-", " ")
+		fmt.Fprintf(&sb, "\n// %s saves the drag-and-drop order of %s.\n", reorderHandler(r), records)
+		fmt.Fprintf(&sb, "func %s(db *gorm.DB) gin.HandlerFunc {\n", reorderHandler(r))
+		sb.WriteString("\treturn func(c *gin.Context) {\n")
+		sb.WriteString("\t\tvar req ReorderRequest\n")
+		sb.WriteString("\t\tif err := c.ShouldBindJSON(&req); err != nil {\n")
+		fmt.Fprintf(&sb, "\t\t\tc.JSON(http.StatusBadRequest, gin.H{\"error\": \"ids must list the %s in their new order\"})\n", records)
+		sb.WriteString("\t\t\treturn\n")
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t\terr := db.Transaction(func(tx *gorm.DB) error {\n")
+		sb.WriteString("\t\t\tfor position, id := range req.IDs {\n")
+		fmt.Fprintf(&sb, "\t\t\t\tquery := tx.Model(&models.%s{}).Where(\"id = ?\", id)\n", toPascalCase(r.Model))
+		if scoped {
+			sb.WriteString("\t\t\t\tquery = query.Where(\"user_id = ?\", c.MustGet(\"user\").(*models.User).ID)\n")
+		}
+		fmt.Fprintf(&sb, "\t\t\t\tif err := query.Update(%q, position).Error; err != nil {\n", toSnakeCase(toPascalCase(r.Field)))
+		sb.WriteString("\t\t\t\t\treturn err\n")
+		sb.WriteString("\t\t\t\t}\n")
+		sb.WriteString("\t\t\t}\n")
+		sb.WriteString("\t\t\treturn nil\n")
+		sb.WriteString("\t\t})\n")
+		sb.WriteString("\t\tif err != nil {\n")
+		sb.WriteString("\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to save the new order\"})\n")
+		sb.WriteString("\t\t\treturn\n")
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t\tc.JSON(http.StatusOK, gin.H{\"data\": req.IDs})\n")
+		sb.WriteString("\t}\n")
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
@@ -26,25 +26,120 @@ func Setup(r *gin.Engine, db *gorm.DB) {
 
 `, moduleName, moduleName, moduleName))
 
+	// Requests recorded for replay (human run --record), with the
+	// responses chaos mode gives them
+	sb.WriteString("\tapi.Use(middleware.Record())\n")
+	// Failures injected in chaos mode (human run --chaos)
+	if ir.InjectsChaos(app) {
+		sb.WriteString("\tapi.Use(middleware.Chaos())\n\n")
+	}
+
+	if len(app.Experiments) > 0 {
+		sb.WriteString("\tapi.Use(middleware.AssignExperiments())\n")
+		sb.WriteString("\tapi.GET(\"/experiments\", handlers.ExperimentAssignments)\n")
+		sb.WriteString("\tapi.POST(\"/experiments/:name/exposure\", handlers.ExperimentExposure)\n\n")
+	}
+
+	if ir.Bills(app) {
+		sb.WriteString("\tbilling := api.Group(\"/billing\")\n")
+		sb.WriteString("\tbilling.GET(\"/subscription\", middleware.RequireAuth(db, cfg), handlers.BillingSubscription(db))\n")
+		sb.WriteString("\tbilling.POST(\"/checkout\", middleware.RequireAuth(db, cfg), handlers.BillingCheckout(db))\n")
+		sb.WriteString("\tbilling.POST(\"/portal\", middleware.RequireAuth(db, cfg), handlers.BillingPortal(db))\n")
+		sb.WriteString("\tbilling.POST(\"/webhook\", handlers.BillingWebhook(db))\n\n")
+	}
+
+	if ir.Meters(app) {
+		sb.WriteString("\tusage := api.Group(\"/usage\", middleware.RequireAuth(db, cfg))\n")
+		sb.WriteString("\tusage.GET(\"\", handlers.UsageSummary(db))\n")
+		sb.WriteString("\tusage.GET(\"/history\", handlers.UsageHistory(db))\n\n")
+	}
+
+	if ir.IssuesAPIKeys(app) {
+		sb.WriteString("\tkeys := api.Group(\"/keys\", middleware.RequireAuth(db, cfg), middleware.RequireSignIn())\n")
+		sb.WriteString("\tkeys.GET(\"\", handlers.ListAPIKeys(db))\n")
+		sb.WriteString("\tkeys.POST(\"\", handlers.CreateAPIKey(db))\n")
+		sb.WriteString("\tkeys.DELETE(\"/:id\", handlers.RevokeAPIKey(db))\n\n")
+	}
+
+	if len(app.Notifications) > 0 {
+		sb.WriteString("\tnotifications := api.Group(\"/notifications\", middleware.RequireAuth(db, cfg))\n")
+		sb.WriteString("\tnotifications.GET(\"\", handlers.ListNotifications(db))\n")
+		sb.WriteString("\tnotifications.GET(\"/unread-count\", handlers.UnreadNotificationCount(db))\n")
+		sb.WriteString("\tnotifications.POST(\"/read-all\", handlers.ReadAllNotifications(db))\n")
+		sb.WriteString("\tnotifications.POST(\"/:id/read\", handlers.ReadNotification(db))\n\n")
+	}
+
+	for _, cal := range app.Calendars {
+		model := toPascalCase(cal.Model)
+		sb.WriteString(fmt.Sprintf("\tapi.GET(\"/calendar/%s.ics\", handlers.%sCalendarFeed(db))\n", cal.Slug, model))
+		sb.WriteString(fmt.Sprintf("\tapi.GET(\"/calendar/%s/:file\", handlers.%sCalendarDownload(db))\n", cal.Slug, model))
+	}
+	if len(app.Calendars) > 0 {
+		sb.WriteString("\n")
+	}
+
+	for _, doc := range app.Documents {
+		if app.Auth != nil {
+			sb.WriteString(fmt.Sprintf("\tapi.GET(\"/documents/%s/:file\", middleware.RequireAuth(db, cfg), handlers.%sPDF(db))\n", doc.Slug, documentName(doc)))
+		} else {
+			sb.WriteString(fmt.Sprintf("\tapi.GET(\"/documents/%s/:file\", handlers.%sPDF(db))\n", doc.Slug, documentName(doc)))
+		}
+	}
+	if len(app.Documents) > 0 {
+		sb.WriteString("\n")
+	}
+
+	for _, c := range app.Charts {
+		if app.Auth != nil {
+			sb.WriteString(fmt.Sprintf("\tapi.GET(\"/charts/%s\", middleware.RequireAuth(db, cfg), handlers.%s(db))\n", c.Slug, chartHandler(c)))
+		} else {
+			sb.WriteString(fmt.Sprintf("\tapi.GET(\"/charts/%s\", handlers.%s(db))\n", c.Slug, chartHandler(c)))
+		}
+	}
+	if len(app.Charts) > 0 {
+		sb.WriteString("\n")
+	}
+
+	for _, r := range app.Reorders {
+		if app.Auth != nil {
+			sb.WriteString(fmt.Sprintf("\tapi.PUT(\"/reorder/%s\", middleware.RequireAuth(db, cfg), handlers.%s(db))\n", r.Slug, reorderHandler(r)))
+		} else {
+			sb.WriteString(fmt.Sprintf("\tapi.PUT(\"/reorder/%s\", handlers.%s(db))\n", r.Slug, reorderHandler(r)))
+		}
+	}
+	if len(app.Reorders) > 0 {
+		sb.WriteString("\n")
+	}
+
+	if app.Sitemap != nil {
+		sb.WriteString("\tapi.GET(\"/sitemap.xml\", handlers.Sitemap(db))\n")
+		sb.WriteString("\tapi.GET(\"/robots.txt\", handlers.Robots)\n\n")
+	}
+
 	for _, api := range app.APIs {
 		method := httpMethod(api.Name)
 		path := routePath(api.Name)
 
+		var chain []string
 		if api.Auth {
-			sb.WriteString(fmt.Sprintf("\tapi.%s(\"%s\", middleware.RequireAuth(db, cfg), handlers.%s(db, cfg))\n", method, path, toPascalCase(api.Name)))
-		} else {
-			sb.WriteString(fmt.Sprintf("\tapi.%s(\"%s\", handlers.%s(db, cfg))\n", method, path, toPascalCase(api.Name)))
+			chain = append(chain, "middleware.RequireAuth(db, cfg)")
 		}
+		// Metered calls are counted, and refused past the user's quota
+		if ir.MetersEndpoint(app, api) {
+			chain = append(chain, "middleware.Meter(db)")
+		}
+		// Plans with a say in the action check the signed-in user's plan
+		if action, model := planCheck(api, app); action != "" {
+			chain = append(chain, fmt.Sprintf("middleware.RequirePlan(db, %q, %q)", action, model))
+		}
+		chain = append(chain, fmt.Sprintf("handlers.%s(db, cfg)", toPascalCase(api.Name)))
+		sb.WriteString(fmt.Sprintf("\tapi.%s(\"%s\", %s)\n", method, path, strings.Join(chain, ", ")))
 	}
 
 	sb.WriteString("}\n")
 	return sb.String()
 }
 
-func generateMigration(app *ir.Application) string {
-	return "-- Initial Migration\n-- Schema auto-generated via GORM AutoMigrate is recommended for early dev, but here is a placeholder.\n\nSELECT 1;\n"
-}
-
 func generateSetupScript() string {
 	return `#!/bin/bash
 # Setup script for the generated Go backend
@@ -0,0 +1,154 @@
+package gobackend
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/barun-bash/human/internal/fixtures"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateSeed produces cmd/seed/main.go, run with `go run ./cmd/seed`
+// (`human seed`): it upserts the app's seed records — the same records as
+// fixtures/ — model by model, belongs_to targets first, so running it again
+// leaves the records as they were generated. Records are JSON in the
+// models' own encoding; password fields are set to the bcrypt hash of
+// ir.SeedPassword.
+func generateSeed(moduleName string, app *ir.Application) string {
+	set := fixtures.Seeded(app)
+	var passwords []*ir.DataField
+	for _, model := range set.Models {
+		for _, f := range model.Fields {
+			if strings.EqualFold(f.Name, "password") {
+				passwords = append(passwords, f)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`// Command seed seeds the development database with sample records, the
+// same records as fixtures/. Run with:
+//
+//	go run ./cmd/seed
+//
+// Running it again resets the records to these values.
+`)
+	if len(passwords) > 0 {
+		fmt.Fprintf(&sb, "// Seeded users sign in with the password %q.\n", ir.SeedPassword)
+	}
+	fmt.Fprintf(&sb, `package main
+
+import (
+	"encoding/json"
+	"log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"%[1]s/config"
+	"%[1]s/database"
+`, moduleName)
+	if len(passwords) > 0 {
+		fmt.Fprintf(&sb, "\t\"%s/middleware\"\n", moduleName)
+	}
+	fmt.Fprintf(&sb, "\t\"%s/models\"\n)\n\n", moduleName)
+
+	for _, model := range set.Models {
+		records := set.Records[model.Name]
+		if len(records) == 0 {
+			continue
+		}
+		rows := make([]string, len(records))
+		for i, rec := range records {
+			rows[i] = "\t" + seedJSON(model, rec)
+		}
+		fmt.Fprintf(&sb, "const %s = `[\n%s\n]`\n\n", seedConstant(model), strings.Join(rows, ",\n"))
+	}
+
+	sb.WriteString(`func main() {
+	db, err := database.Connect(config.Load())
+	if err != nil {
+		log.Fatal(err)
+	}
+`)
+	if len(passwords) > 0 {
+		fmt.Fprintf(&sb, "\tpassword, err := middleware.HashPassword(%q)\n", ir.SeedPassword)
+		sb.WriteString("\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n")
+	}
+	sb.WriteString("\n")
+	for _, model := range set.Models {
+		if len(set.Records[model.Name]) == 0 {
+			continue
+		}
+		name := toPascalCase(model.Name)
+		setter := "nil"
+		for _, f := range model.Fields {
+			if !strings.EqualFold(f.Name, "password") {
+				continue
+			}
+			value := "password"
+			if !f.Required {
+				value = "&password"
+			}
+			setter = fmt.Sprintf("func(r *models.%s) { r.%s = %s }", name, toPascalCase(f.Name), value)
+		}
+		fmt.Fprintf(&sb, "\tseed[models.%s](db, %q, %s, %s)\n", name, strings.ToLower(pluralize(name)), seedConstant(model), setter)
+	}
+	sb.WriteString(`}
+
+// seed decodes a model's records, sets their passwords, and upserts them
+// by id.
+func seed[T any](db *gorm.DB, name, records string, set func(*T)) {
+	var rows []T
+	if err := json.Unmarshal([]byte(records), &rows); err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+	if set != nil {
+		for i := range rows {
+			set(&rows[i])
+		}
+	}
+	if err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&rows).Error; err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+	log.Printf("Seeded %d %s", len(rows), name)
+}
+`)
+	return sb.String()
+}
+
+// seedConstant returns the name of the constant holding a model's seed
+// records: "TaskTag" → "taskTagRecords".
+func seedConstant(model *ir.DataModel) string {
+	return toCamelCase(model.Name) + "Records"
+}
+
+// seedJSON returns a fixture record as JSON the model decodes: keys are
+// the models' JSON tags, and dates are RFC 3339 times, which time.Time
+// reads.
+func seedJSON(model *ir.DataModel, rec map[string]any) string {
+	var pairs []string
+	add := func(key string, v any) {
+		k, _ := json.Marshal(key)
+		data, _ := json.Marshal(v)
+		pairs = append(pairs, string(k)+": "+string(data))
+	}
+	add("id", rec["id"])
+	for _, f := range model.Fields {
+		v := rec[f.Name]
+		if s, ok := v.(string); ok && f.Type == "date" {
+			if t, err := time.Parse("2006-01-02", s); err == nil {
+				v = t.Format(time.RFC3339)
+			}
+		}
+		add(toCamelCase(f.Name), v)
+	}
+	for _, rel := range model.Relations {
+		if v, ok := rec[fixtures.ForeignKey(rel.Target)]; ok && rel.Kind == "belongs_to" {
+			add(toCamelCase(rel.Target)+"Id", v)
+		}
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
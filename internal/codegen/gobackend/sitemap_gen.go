@@ -0,0 +1,122 @@
+package gobackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateSitemapHandlers produces handlers/sitemap.go: sitemap.xml, with
+// the public pages and a URL per record of each public model, and
+// robots.txt, which keeps crawlers out of the API and private pages. Both
+// are served under /api; the frontend's server proxies /sitemap.xml and
+// /robots.txt to them.
+func generateSitemapHandlers(moduleName string, app *ir.Application) string {
+	sm := app.Sitemap
+	var sb strings.Builder
+
+	sb.WriteString("package handlers\n\n")
+	sb.WriteString("import (\n")
+	sb.WriteString("\t\"encoding/xml\"\n")
+	sb.WriteString("\t\"net/http\"\n")
+	sb.WriteString("\t\"os\"\n")
+	sb.WriteString("\t\"strings\"\n")
+	sb.WriteString("\t\"time\"\n\n")
+	sb.WriteString("\t\"github.com/gin-gonic/gin\"\n")
+	sb.WriteString("\t\"gorm.io/gorm\"\n")
+	if len(sm.Records) > 0 {
+		fmt.Fprintf(&sb, "\n\t\"%s/models\"\n", moduleName)
+	}
+	sb.WriteString(")\n\n")
+
+	pages := make([]string, len(sm.Pages))
+	for i, p := range sm.Pages {
+		pages[i] = fmt.Sprintf("%q", p)
+	}
+	fmt.Fprintf(&sb, "var sitemapPages = []string{%s}\n\n", strings.Join(pages, ", "))
+	sb.WriteString("// A sitemap holds at most 50,000 URLs.\n")
+	sb.WriteString("const sitemapMaxURLs = 50000\n\n")
+
+	sb.WriteString("// siteURL returns the canonical origin: SITE_URL, else the declared\n")
+	sb.WriteString("// production URL, else the request's host.\n")
+	sb.WriteString("func siteURL(c *gin.Context) string {\n")
+	sb.WriteString("\tif u := os.Getenv(\"SITE_URL\"); u != \"\" {\n")
+	sb.WriteString("\t\treturn strings.TrimSuffix(u, \"/\")\n")
+	sb.WriteString("\t}\n")
+	if sm.URL != "" {
+		fmt.Fprintf(&sb, "\treturn %q\n", sm.URL)
+	} else {
+		sb.WriteString("\tscheme := \"http\"\n")
+		sb.WriteString("\tif c.Request.TLS != nil {\n")
+		sb.WriteString("\t\tscheme = \"https\"\n")
+		sb.WriteString("\t}\n")
+		sb.WriteString("\treturn scheme + \"://\" + c.Request.Host\n")
+	}
+	sb.WriteString("}\n")
+
+	sb.WriteString(`
+func writeSitemapURL(b *strings.Builder, loc string, lastmod time.Time) {
+	b.WriteString("  <url><loc>")
+	xml.EscapeText(b, []byte(loc))
+	b.WriteString("</loc>")
+	if !lastmod.IsZero() {
+		b.WriteString("<lastmod>" + lastmod.Format("2006-01-02") + "</lastmod>")
+	}
+	b.WriteString("</url>\n")
+}
+
+// Sitemap serves sitemap.xml.
+`)
+	if len(sm.Records) > 0 {
+		sb.WriteString("func Sitemap(db *gorm.DB) gin.HandlerFunc {\n")
+	} else {
+		sb.WriteString("func Sitemap(_ *gorm.DB) gin.HandlerFunc {\n")
+	}
+	sb.WriteString(`	return func(c *gin.Context) {
+		origin := siteURL(c)
+		var b strings.Builder
+		b.WriteString(xml.Header)
+		b.WriteString("<urlset xmlns=\"http://www.sitemaps.org/schemas/sitemap/0.9\">\n")
+		for _, path := range sitemapPages {
+			writeSitemapURL(&b, origin+path, time.Time{})
+		}
+`)
+	for _, rec := range sm.Records {
+		records := pluralize(toCamelCase(rec.Model))
+		fmt.Fprintf(&sb, "\t\tvar %s []models.%s\n", records, toPascalCase(rec.Model))
+		fmt.Fprintf(&sb, "\t\tif err := db.Select(\"id\", \"updated_at\").Limit(sitemapMaxURLs).Find(&%s).Error; err != nil {\n", records)
+		sb.WriteString("\t\t\tc.JSON(http.StatusInternalServerError, gin.H{\"error\": \"Failed to build the sitemap\"})\n")
+		sb.WriteString("\t\t\treturn\n")
+		sb.WriteString("\t\t}\n")
+		fmt.Fprintf(&sb, "\t\tfor _, r := range %s {\n", records)
+		fmt.Fprintf(&sb, "\t\t\twriteSitemapURL(&b, origin+\"%s\"+r.ID, r.UpdatedAt)\n", rec.Link())
+		sb.WriteString("\t\t}\n")
+	}
+	sb.WriteString("\t\tb.WriteString(\"</urlset>\\n\")\n")
+	sb.WriteString("\t\tc.Data(http.StatusOK, \"application/xml; charset=utf-8\", []byte(b.String()))\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("}\n")
+
+	sb.WriteString("\n// Robots serves robots.txt.\n")
+	sb.WriteString("func Robots(c *gin.Context) {\n")
+	sb.WriteString("\tvar b strings.Builder\n")
+	for _, line := range robotsRules(sm) {
+		fmt.Fprintf(&sb, "\tb.WriteString(%q)\n", line+"\n")
+	}
+	sb.WriteString("\tb.WriteString(\"\\nSitemap: \" + siteURL(c) + \"/sitemap.xml\\n\")\n")
+	sb.WriteString("\tc.Data(http.StatusOK, \"text/plain; charset=utf-8\", []byte(b.String()))\n")
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// robotsRules returns robots.txt's rules: every crawler stays out of the
+// API and the pages that need signing in.
+func robotsRules(sm *ir.Sitemap) []string {
+	lines := []string{"User-agent: *", "Disallow: /api/"}
+	for _, p := range sm.Private {
+		lines = append(lines, "Disallow: "+p)
+	}
+	return lines
+}
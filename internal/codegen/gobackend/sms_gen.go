@@ -0,0 +1,238 @@
+package gobackend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// twilioEnv returns the environment variable for the first credential whose
+// key mentions one of the keywords ("account sid" → TWILIO_ACCOUNT_SID).
+func twilioEnv(integ *ir.Integration, fallback string, keywords ...string) string {
+	keys := make([]string, 0, len(integ.Credentials))
+	for key := range integ.Credentials {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, kw := range keywords {
+			if strings.Contains(strings.ToLower(key), kw) {
+				return integ.Credentials[key]
+			}
+		}
+	}
+	return fallback
+}
+
+// generateSMSService produces services/sms.go: SMS and WhatsApp messages
+// through Twilio's REST API, retried per the app's error handlers. In
+// sandbox mode messages are recorded in Outbox instead of sent.
+func generateSMSService(integ *ir.Integration, app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n")
+	fmt.Fprintf(&b, "// Integration: %s (sms)\n", integ.Service)
+	b.WriteString("package services\n\n")
+
+	b.WriteString("import (\n")
+	b.WriteString("\t\"encoding/json\"\n")
+	b.WriteString("\t\"fmt\"\n")
+	b.WriteString("\t\"net/http\"\n")
+	b.WriteString("\t\"net/url\"\n")
+	b.WriteString("\t\"os\"\n")
+	b.WriteString("\t\"strings\"\n")
+	b.WriteString("\t\"sync\"\n")
+	b.WriteString("\t\"time\"\n")
+	b.WriteString(")\n\n")
+
+	attempts, delayMs := ir.SMSRetryPolicy(app)
+	b.WriteString("// Retry policy, from the app's error handlers.\n")
+	b.WriteString("const (\n")
+	fmt.Fprintf(&b, "\tsmsAttempts   = %d\n", attempts)
+	fmt.Fprintf(&b, "\tsmsRetryDelay = %d * time.Millisecond\n", delayMs)
+	b.WriteString(")\n\n")
+
+	b.WriteString("// SMSTemplates maps approved message templates to their Twilio Content SIDs.\n")
+	b.WriteString("var SMSTemplates = map[string]string{\n")
+	for _, tpl := range integ.Templates {
+		fmt.Fprintf(&b, "\t%q: os.Getenv(%q),\n", tpl, "TWILIO_TEMPLATE_"+strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(tpl)))
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString(`// SentMessage is a message recorded in sandbox mode.
+type SentMessage struct {
+	Channel   string
+	To        string
+	Body      string
+	Template  string
+	Variables map[string]string
+}
+
+// In sandbox mode (TWILIO_SANDBOX=true) messages are recorded in Outbox
+// instead of sent, so tests never text real phones.
+var (
+	outboxMu sync.Mutex
+	Outbox   []SentMessage
+)
+
+var smsClient = &http.Client{Timeout: 10 * time.Second}
+
+`)
+
+	sidEnv := twilioEnv(integ, "TWILIO_ACCOUNT_SID", "sid", "account")
+	tokenEnv := twilioEnv(integ, "TWILIO_AUTH_TOKEN", "token", "secret")
+	fromEnv := twilioEnv(integ, "TWILIO_FROM_NUMBER", "from", "number", "phone")
+
+	b.WriteString("func fromNumber() string {\n")
+	fmt.Fprintf(&b, "\tif v := os.Getenv(%q); v != \"\" {\n", fromEnv)
+	b.WriteString("\t\treturn v\n")
+	b.WriteString("\t}\n")
+	fmt.Fprintf(&b, "\treturn %q\n", integ.Config["sender_email"])
+	b.WriteString("}\n\n")
+
+	b.WriteString("func deliver(msg SentMessage) error {\n")
+	b.WriteString("\tif os.Getenv(\"TWILIO_SANDBOX\") == \"true\" {\n")
+	b.WriteString("\t\toutboxMu.Lock()\n")
+	b.WriteString("\t\tOutbox = append(Outbox, msg)\n")
+	b.WriteString("\t\toutboxMu.Unlock()\n")
+	b.WriteString("\t\treturn nil\n")
+	b.WriteString("\t}\n\n")
+
+	b.WriteString("\tfrom, to := fromNumber(), msg.To\n")
+	b.WriteString("\tif msg.Channel == \"whatsapp\" {\n")
+	b.WriteString("\t\tif v := os.Getenv(\"TWILIO_WHATSAPP_FROM\"); v != \"\" {\n")
+	b.WriteString("\t\t\tfrom = v\n")
+	b.WriteString("\t\t}\n")
+	b.WriteString("\t\tfrom, to = \"whatsapp:\"+from, \"whatsapp:\"+to\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\tform := url.Values{\"From\": {from}, \"To\": {to}}\n")
+	b.WriteString("\tif msg.Template != \"\" {\n")
+	b.WriteString("\t\tvariables, _ := json.Marshal(msg.Variables)\n")
+	b.WriteString("\t\tform.Set(\"ContentSid\", SMSTemplates[msg.Template])\n")
+	b.WriteString("\t\tform.Set(\"ContentVariables\", string(variables))\n")
+	b.WriteString("\t} else {\n")
+	b.WriteString("\t\tform.Set(\"Body\", msg.Body)\n")
+	b.WriteString("\t}\n\n")
+
+	fmt.Fprintf(&b, "\tsid := os.Getenv(%q)\n", sidEnv)
+	b.WriteString("\tendpoint := fmt.Sprintf(\"https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json\", sid)\n\n")
+
+	b.WriteString("\tvar lastErr error\n")
+	b.WriteString("\tfor attempt := 1; attempt <= smsAttempts; attempt++ {\n")
+	b.WriteString("\t\treq, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))\n")
+	b.WriteString("\t\tif err != nil {\n")
+	b.WriteString("\t\t\treturn err\n")
+	b.WriteString("\t\t}\n")
+	fmt.Fprintf(&b, "\t\treq.SetBasicAuth(sid, os.Getenv(%q))\n", tokenEnv)
+	b.WriteString("\t\treq.Header.Set(\"Content-Type\", \"application/x-www-form-urlencoded\")\n\n")
+	b.WriteString("\t\tres, err := smsClient.Do(req)\n")
+	b.WriteString("\t\tif err == nil {\n")
+	b.WriteString("\t\t\tres.Body.Close()\n")
+	b.WriteString("\t\t\tif res.StatusCode < 300 {\n")
+	b.WriteString("\t\t\t\treturn nil\n")
+	b.WriteString("\t\t\t}\n")
+	b.WriteString("\t\t\terr = fmt.Errorf(\"twilio: %s\", res.Status)\n")
+	b.WriteString("\t\t}\n")
+	b.WriteString("\t\tlastErr = err\n")
+	b.WriteString("\t\tif attempt < smsAttempts {\n")
+	b.WriteString("\t\t\ttime.Sleep(smsRetryDelay)\n")
+	b.WriteString("\t\t}\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\treturn lastErr\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(`// SendSMS texts body to the phone number.
+func SendSMS(to, body string) error {
+	return deliver(SentMessage{Channel: "sms", To: to, Body: body})
+}
+
+// SendWhatsApp sends body to the phone number over WhatsApp.
+func SendWhatsApp(to, body string) error {
+	return deliver(SentMessage{Channel: "whatsapp", To: to, Body: body})
+}
+
+// SendTemplate sends an approved template over "sms" or "whatsapp".
+func SendTemplate(channel, to, template string, variables map[string]string) error {
+	return deliver(SentMessage{Channel: channel, To: to, Template: template, Variables: variables})
+}
+`)
+
+	return b.String()
+}
+
+// generateSMSTests produces services/sms_test.go, which exercises the SMS
+// service against its sandbox outbox.
+func generateSMSTests() string {
+	return `// Generated by Human compiler — do not edit
+package services
+
+import "testing"
+
+func TestSendSMSSandbox(t *testing.T) {
+	t.Setenv("TWILIO_SANDBOX", "true")
+	Outbox = nil
+
+	if err := SendSMS("+15555550100", "Your order has shipped"); err != nil {
+		t.Fatal(err)
+	}
+	if len(Outbox) != 1 || Outbox[0].Channel != "sms" || Outbox[0].Body != "Your order has shipped" {
+		t.Errorf("unexpected outbox: %+v", Outbox)
+	}
+}
+
+func TestSendWhatsAppSandbox(t *testing.T) {
+	t.Setenv("TWILIO_SANDBOX", "true")
+	Outbox = nil
+
+	if err := SendWhatsApp("+15555550100", "Hello"); err != nil {
+		t.Fatal(err)
+	}
+	if len(Outbox) != 1 || Outbox[0].Channel != "whatsapp" {
+		t.Errorf("unexpected outbox: %+v", Outbox)
+	}
+}
+
+func TestSendTemplateSandbox(t *testing.T) {
+	t.Setenv("TWILIO_SANDBOX", "true")
+	Outbox = nil
+
+	if err := SendTemplate("sms", "+15555550100", "reminder", map[string]string{"name": "Ada"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(Outbox) != 1 || Outbox[0].Template != "reminder" || Outbox[0].Variables["name"] != "Ada" {
+		t.Errorf("unexpected outbox: %+v", Outbox)
+	}
+}
+`
+}
+
+// writeSMSCall emits the services call for a "send SMS ..." step. The
+// recipient is the endpoint's phone param.
+func writeSMSCall(sb *strings.Builder, step *ir.Action, api *ir.Endpoint) {
+	msg, _ := ir.ParseSMSStep(step.Text)
+	param := ir.PhoneParam(api)
+	if param == "" {
+		sb.WriteString("\t\t// TODO: no recipient for the SMS — accept a phone param\n")
+		return
+	}
+	to := "req." + toPascalCase(param)
+	body := msg.Body
+	if body == "" {
+		body = "Action completed"
+	}
+
+	call := ""
+	switch {
+	case msg.Template != "":
+		call = fmt.Sprintf("services.SendTemplate(%q, %s, %q, nil)", msg.Channel, to, msg.Template)
+	case msg.Channel == "whatsapp":
+		call = fmt.Sprintf("services.SendWhatsApp(%s, %q)", to, body)
+	default:
+		call = fmt.Sprintf("services.SendSMS(%s, %q)", to, body)
+	}
+	sb.WriteString(fmt.Sprintf("\t\tif err := %s; err != nil {\n", call))
+	sb.WriteString("\t\t\t_ = err\n")
+	sb.WriteString("\t\t}\n")
+}
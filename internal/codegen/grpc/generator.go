@@ -0,0 +1,243 @@
+// Package grpc generates a Protocol Buffers service definition from an
+// application's data models and endpoints, buf configuration for producing
+// stubs, and a grpc-gateway program that transcodes the REST routes to the
+// service. The backend generators emit the matching gRPC servers, which run
+// the same business steps as their REST handlers.
+package grpc
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Generator produces the gRPC service definition and gateway.
+type Generator struct{}
+
+// Generate writes the proto, buf config, and gateway to outputDir.
+func (g Generator) Generate(app *ir.Application, outputDir string) error {
+	files := map[string]string{
+		filepath.Join(outputDir, "proto", filepath.FromSlash(ProtoPath(app))): Proto(app),
+		filepath.Join(outputDir, "buf.yaml"):                                  generateBufYAML(),
+		filepath.Join(outputDir, "buf.gen.yaml"):                              generateBufGenYAML(app),
+		filepath.Join(outputDir, "gateway.yaml"):                              generateGatewayConfig(app),
+		filepath.Join(outputDir, "go.mod"):                                    generateGoMod(app),
+		filepath.Join(outputDir, "gateway", "main.go"):                        generateGatewayMain(app),
+		filepath.Join(outputDir, "README.md"):                                 generateReadme(app),
+	}
+
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
+}
+
+func generateBufYAML() string {
+	return `# Generated by Human compiler — do not edit
+version: v2
+modules:
+  - path: proto
+lint:
+  use:
+    - STANDARD
+breaking:
+  use:
+    - FILE
+`
+}
+
+// generateBufGenYAML configures Go stubs and the gateway (always), plus
+// Python stubs written straight into the Python backend when it is the
+// selected backend. The Node server loads the proto at runtime instead.
+func generateBufGenYAML(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("version: v2\n")
+	b.WriteString("managed:\n")
+	b.WriteString("  enabled: true\n")
+	b.WriteString("  override:\n")
+	b.WriteString("    - file_option: go_package_prefix\n")
+	fmt.Fprintf(&b, "      value: %s/gen/go\n", ModuleName(app))
+	b.WriteString("plugins:\n")
+	b.WriteString("  - remote: buf.build/protocolbuffers/go\n")
+	b.WriteString("    out: gen/go\n")
+	b.WriteString("    opt: paths=source_relative\n")
+	b.WriteString("  - remote: buf.build/grpc/go\n")
+	b.WriteString("    out: gen/go\n")
+	b.WriteString("    opt: paths=source_relative\n")
+	b.WriteString("  - remote: buf.build/grpc-ecosystem/gateway\n")
+	b.WriteString("    out: gen/go\n")
+	b.WriteString("    opt:\n")
+	b.WriteString("      - paths=source_relative\n")
+	b.WriteString("      - grpc_api_configuration=gateway.yaml\n")
+	if usesPythonBackend(app) {
+		b.WriteString("  - remote: buf.build/protocolbuffers/python\n")
+		b.WriteString("    out: ../python/gen\n")
+		b.WriteString("  - remote: buf.build/protocolbuffers/pyi\n")
+		b.WriteString("    out: ../python/gen\n")
+	}
+	return b.String()
+}
+
+// generateGatewayConfig maps each RPC to its REST route. Keeping the HTTP
+// rules in a service config (rather than google.api.http annotations)
+// leaves the proto free of third-party imports, so runtimes that load it
+// directly need nothing beyond the proto itself.
+func generateGatewayConfig(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("# HTTP rules for grpc-gateway: each RPC is served at its REST route.\n")
+	b.WriteString("type: google.api.Service\n")
+	b.WriteString("config_version: 3\n")
+	b.WriteString("http:\n")
+	rpcs := RPCs(app)
+	if len(rpcs) == 0 {
+		b.WriteString("  rules: []\n")
+		return b.String()
+	}
+	b.WriteString("  rules:\n")
+	for _, rpc := range rpcs {
+		fmt.Fprintf(&b, "    - selector: %s.%s\n", FullServiceName(app), rpc.Name)
+		fmt.Fprintf(&b, "      %s: %s\n", strings.ToLower(rpc.Method), rpc.Path)
+		if rpc.Method == "POST" || rpc.Method == "PUT" {
+			b.WriteString("      body: \"*\"\n")
+		}
+	}
+	return b.String()
+}
+
+func generateGoMod(app *ir.Application) string {
+	return fmt.Sprintf(`module %s
+
+go 1.23
+
+require (
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.24.0
+	google.golang.org/genproto/googleapis/api v0.0.0-20241113202542-65e8d215514f
+	google.golang.org/grpc v1.68.0
+	google.golang.org/protobuf v1.35.2
+)
+`, ModuleName(app))
+}
+
+func generateGatewayMain(app *ir.Application) string {
+	return fmt.Sprintf(`// Generated by Human compiler — do not edit
+
+// Command gateway serves the %s REST API by transcoding each request to the
+// gRPC service, so REST clients and gRPC clients share one backend.
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "%s"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	backend := getenv("GRPC_BACKEND", "localhost:%d")
+	addr := ":" + getenv("PORT", "8081")
+
+	// The Authorization header is forwarded to the service as
+	// "authorization" metadata by default.
+	mux := runtime.NewServeMux()
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if err := pb.Register%sHandlerFromEndpoint(ctx, mux, backend, opts); err != nil {
+		log.Fatalf("registering gateway: %%v", err)
+	}
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	log.Printf("REST gateway on %%s → gRPC %%s", addr, backend)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+`, appTitle(app), StubImportPath(app), DefaultPort, ServiceName(app))
+}
+
+func generateReadme(app *ir.Application) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s gRPC service\n\n", appTitle(app))
+	b.WriteString("Generated by the Human compiler from `api style is gRPC`.\n\n")
+	b.WriteString("| Path | Purpose |\n")
+	b.WriteString("|------|---------|\n")
+	fmt.Fprintf(&b, "| `proto/%s` | Service definition: one message per data model, one RPC per endpoint |\n", ProtoPath(app))
+	b.WriteString("| `buf.yaml`, `buf.gen.yaml` | Lint, breaking-change, and stub generation config |\n")
+	b.WriteString("| `gateway.yaml` | HTTP rules mapping each RPC to its REST route |\n")
+	b.WriteString("| `gateway/` | grpc-gateway program that serves the REST API from the gRPC service |\n\n")
+
+	b.WriteString("## Generate stubs\n\n")
+	b.WriteString("```bash\n")
+	b.WriteString("buf generate\n")
+	b.WriteString("go mod tidy\n")
+	b.WriteString("```\n\n")
+	fmt.Fprintf(&b, "Go stubs land in `gen/go` (import path `%s`).", StubImportPath(app))
+	if usesPythonBackend(app) {
+		b.WriteString(" Python stubs are written to `../python/gen` for the Python backend's gRPC server.")
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString("## Run\n\n")
+	switch {
+	case usesPythonBackend(app):
+		fmt.Fprintf(&b, "The Python backend starts the gRPC server on `GRPC_PORT` (default %d) alongside the REST API.\n\n", DefaultPort)
+	case codegen.MatchesGoBackend(backend(app)):
+		fmt.Fprintf(&b, "The Go backend starts the gRPC server on `GRPC_PORT` (default %d) alongside the REST API. Run `buf generate` here first; the backend imports the stubs through a `replace` directive.\n\n", DefaultPort)
+	default:
+		fmt.Fprintf(&b, "The Node backend starts the gRPC server on `GRPC_PORT` (default %d) alongside the REST API, loading `proto/` at runtime.\n\n", DefaultPort)
+	}
+	b.WriteString("Each RPC runs the same business steps as its REST endpoint: the server forwards the call to the backend's own route handlers in-process, so validation, authorization, and data access behave identically. Pass the bearer token as `authorization` metadata.\n\n")
+	b.WriteString("To serve REST clients from the gRPC service instead (for example when the service is embedded in a polyglot backend):\n\n")
+	b.WriteString("```bash\n")
+	fmt.Fprintf(&b, "GRPC_BACKEND=localhost:%d PORT=8081 go run ./gateway\n", DefaultPort)
+	b.WriteString("```\n\n")
+
+	rpcs := RPCs(app)
+	if len(rpcs) > 0 {
+		b.WriteString("## RPCs\n\n")
+		b.WriteString("| RPC | REST route | Auth |\n")
+		b.WriteString("|-----|------------|------|\n")
+		for _, rpc := range rpcs {
+			auth := ""
+			if rpc.Endpoint.Auth {
+				auth = "required"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s `%s` | %s |\n", rpc.Name, rpc.Method, rpc.Path, auth)
+		}
+	}
+	return b.String()
+}
+
+func backend(app *ir.Application) string {
+	if app.Config == nil {
+		return ""
+	}
+	return app.Config.Backend
+}
+
+func usesPythonBackend(app *ir.Application) bool {
+	return strings.Contains(strings.ToLower(backend(app)), "python")
+}
@@ -0,0 +1,186 @@
+package grpc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func testApp() *ir.Application {
+	return &ir.Application{
+		Name:   "TaskFlow",
+		Config: &ir.BuildConfig{Backend: "Node with Express", APIStyle: "gRPC"},
+		Data: []*ir.DataModel{
+			{
+				Name: "User",
+				Fields: []*ir.DataField{
+					{Name: "name", Type: "text"},
+					{Name: "email", Type: "email"},
+					{Name: "password", Type: "text"},
+				},
+			},
+			{
+				Name: "Task",
+				Fields: []*ir.DataField{
+					{Name: "title", Type: "text"},
+					{Name: "status", Type: "enum", EnumValues: []string{"pending", "done"}},
+					{Name: "due date", Type: "date"},
+					{Name: "estimate", Type: "number"},
+					{Name: "metadata", Type: "json"},
+					{Name: "user_id", Type: "text"},
+				},
+				Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}},
+			},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "SignUp", Params: []*ir.Param{{Name: "name"}, {Name: "email"}, {Name: "password"}}},
+			{Name: "GetTasks", Auth: true, Params: []*ir.Param{{Name: "status"}}},
+			{Name: "CreateTask", Auth: true, Params: []*ir.Param{{Name: "title"}, {Name: "due date"}, {Name: "estimate"}}},
+			{Name: "DeleteTask", Auth: true, Params: []*ir.Param{{Name: "task_id"}}},
+		},
+	}
+}
+
+func TestIsEnabled(t *testing.T) {
+	if !IsEnabled(testApp()) {
+		t.Error("expected gRPC to be enabled for api style gRPC")
+	}
+	if IsEnabled(&ir.Application{Config: &ir.BuildConfig{APIStyle: "REST"}}) {
+		t.Error("REST api style should not enable gRPC")
+	}
+	if IsEnabled(&ir.Application{}) {
+		t.Error("missing config should not enable gRPC")
+	}
+}
+
+func TestNaming(t *testing.T) {
+	app := testApp()
+	tests := map[string]string{
+		"package":  PackageName(app),
+		"service":  FullServiceName(app),
+		"path":     ProtoPath(app),
+		"module":   ModuleName(app),
+		"stubs":    StubImportPath(app),
+		"fallback": PackageName(&ir.Application{Name: "42 Things"}),
+	}
+	want := map[string]string{
+		"package":  "taskflow.v1",
+		"service":  "taskflow.v1.TaskFlowService",
+		"path":     "taskflow/v1/taskflow.proto",
+		"module":   "taskflow-grpc",
+		"stubs":    "taskflow-grpc/gen/go/taskflow/v1",
+		"fallback": "app42things.v1",
+	}
+	for k, got := range tests {
+		if got != want[k] {
+			t.Errorf("%s = %q, want %q", k, got, want[k])
+		}
+	}
+}
+
+func TestRPCs(t *testing.T) {
+	rpcs := RPCs(testApp())
+	if len(rpcs) != 4 {
+		t.Fatalf("got %d RPCs, want 4", len(rpcs))
+	}
+	if rpcs[1].Method != "GET" || rpcs[1].Path != "/api/tasks" || !rpcs[1].List {
+		t.Errorf("GetTasks = %s %s list=%v", rpcs[1].Method, rpcs[1].Path, rpcs[1].List)
+	}
+	if !rpcs[0].JSONData() {
+		t.Error("SignUp has no model and should carry JSON-encoded data")
+	}
+
+	due := rpcs[2].Fields[1]
+	if due.Name != "due_date" || due.JSONName() != "dueDate" || due.GoName() != "DueDate" {
+		t.Errorf("due date field = %+v (json %q, go %q)", due, due.JSONName(), due.GoName())
+	}
+	if rpcs[2].Fields[2].Type != "int32" {
+		t.Errorf("estimate type = %q, want int32", rpcs[2].Fields[2].Type)
+	}
+	if got := rpcs[3].Fields[0].GoName(); got != "TaskId" {
+		t.Errorf("task_id Go name = %q, want TaskId", got)
+	}
+}
+
+func TestProto(t *testing.T) {
+	proto := Proto(testApp())
+
+	for _, want := range []string{
+		"package taskflow.v1;",
+		"service TaskFlowService {",
+		"  rpc GetTasks(GetTasksRequest) returns (GetTasksResponse);",
+		"  // One of: pending, done\n  string status = 3;",
+		"  // JSON-encoded.\n  string metadata = 6;",
+		"  repeated Task data = 1;",
+		"message SignUpResponse {\n  // JSON-encoded response data.\n  string data = 1;",
+		"message CreateTaskRequest {\n  string title = 1;\n  string due_date = 2;\n  int32 estimate = 3;\n}",
+	} {
+		if !strings.Contains(proto, want) {
+			t.Errorf("proto missing %q", want)
+		}
+	}
+
+	user := proto[strings.Index(proto, "message User {"):]
+	user = user[:strings.Index(user, "}")]
+	if strings.Contains(user, "password") {
+		t.Error("password must not be exposed in the User message")
+	}
+	if strings.Count(proto, "string user_id") != 1 {
+		t.Error("declared user_id field and belongs_to foreign key should produce one field")
+	}
+	if strings.Contains(proto, "import ") {
+		t.Error("proto should not import third-party definitions")
+	}
+}
+
+func TestGatewayConfig(t *testing.T) {
+	cfg := generateGatewayConfig(testApp())
+	for _, want := range []string{
+		"    - selector: taskflow.v1.TaskFlowService.CreateTask\n      post: /api/task\n      body: \"*\"\n",
+		"    - selector: taskflow.v1.TaskFlowService.GetTasks\n      get: /api/tasks\n",
+		"    - selector: taskflow.v1.TaskFlowService.DeleteTask\n      delete: /api/task\n",
+	} {
+		if !strings.Contains(cfg, want) {
+			t.Errorf("gateway.yaml missing %q\n%s", want, cfg)
+		}
+	}
+}
+
+func TestBufGenPython(t *testing.T) {
+	app := testApp()
+	if strings.Contains(generateBufGenYAML(app), "python") {
+		t.Error("Node backend should not generate Python stubs")
+	}
+	app.Config.Backend = "Python with FastAPI"
+	if !strings.Contains(generateBufGenYAML(app), "out: ../python/gen") {
+		t.Error("Python backend should get stubs in ../python/gen")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(testApp(), dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{
+		"proto/taskflow/v1/taskflow.proto",
+		"buf.yaml",
+		"buf.gen.yaml",
+		"gateway.yaml",
+		"go.mod",
+		"gateway/main.go",
+		"README.md",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("missing %s", rel)
+		}
+	}
+
+	main, _ := os.ReadFile(filepath.Join(dir, "gateway", "main.go"))
+	if !strings.Contains(string(main), "pb.RegisterTaskFlowServiceHandlerFromEndpoint") {
+		t.Error("gateway should register the service handler")
+	}
+}
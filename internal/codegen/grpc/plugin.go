@@ -0,0 +1,27 @@
+package grpc
+
+import (
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Meta returns the generator's metadata.
+func (g Generator) Meta() codegen.PluginMeta {
+	return codegen.PluginMeta{
+		Name:        "grpc",
+		Version:     "1.0.0",
+		Description: "Protocol Buffers service definition, buf config, and REST gateway",
+		Category:    codegen.CategoryBackend,
+	}
+}
+
+// Enabled reports whether the build config selects the gRPC API style.
+func (g Generator) Enabled(app *ir.Application) bool {
+	return IsEnabled(app)
+}
+
+// StageName returns the display name for progress reporting.
+func (g Generator) StageName() string { return "Generating gRPC service" }
+
+// OutputDir returns the subdirectory name within the build output.
+func (g Generator) OutputDir() string { return "grpc" }
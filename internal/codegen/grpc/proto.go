@@ -0,0 +1,419 @@
+package grpc
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// DefaultPort is the port generated gRPC servers listen on when GRPC_PORT
+// is not set.
+const DefaultPort = 50051
+
+// IsEnabled reports whether the build config selects gRPC as the API style
+// ("api style is gRPC"). Backends use it to decide whether to emit a gRPC
+// server alongside their REST routes.
+func IsEnabled(app *ir.Application) bool {
+	return app.Config != nil && strings.Contains(strings.ToLower(app.Config.APIStyle), "grpc")
+}
+
+// RPC is one endpoint exposed as a unary gRPC method. Method and Path are
+// the REST route the gateway transcodes to the RPC.
+type RPC struct {
+	Endpoint *ir.Endpoint
+	Name     string // PascalCase method name: "CreateTask"
+	Method   string // GET, POST, PUT, DELETE
+	Path     string // /api/...
+	Fields   []Field
+	Model    *ir.DataModel // response model, nil when unknown
+	List     bool          // response data is repeated Model
+}
+
+// JSONData reports whether the response data has no known model and is
+// carried as a JSON-encoded string.
+func (r RPC) JSONData() bool { return r.Model == nil }
+
+// Field is an RPC request field derived from an endpoint parameter.
+type Field struct {
+	Param string // as declared: "task_id", "due date"
+	Name  string // proto field name: "task_id", "due_date"
+	Type  string // proto scalar type: string, int32, double, bool
+}
+
+// JSONName returns the field's proto3 JSON name, which is also the
+// property name protobuf runtimes use in JavaScript: "due_date" → "dueDate".
+func (f Field) JSONName() string { return lowerCamel(f.Name) }
+
+// GoName returns the struct field name protoc-gen-go generates for the
+// field: "task_id" → "TaskId".
+func (f Field) GoName() string {
+	c := lowerCamel(f.Name)
+	if c == "" {
+		return c
+	}
+	return strings.ToUpper(c[:1]) + c[1:]
+}
+
+// RPCs derives the service methods from the app's endpoints, in
+// declaration order. Routes follow the Node backend: Get/List → GET,
+// Delete → DELETE, Update → PUT, everything else POST.
+func RPCs(app *ir.Application) []RPC {
+	var rpcs []RPC
+	for _, ep := range app.APIs {
+		rpc := RPC{
+			Endpoint: ep,
+			Name:     toPascalCase(ep.Name),
+			Method:   httpMethod(ep.Name),
+			Path:     "/api/" + toKebabCase(stripVerb(ep.Name)),
+		}
+		noun := stripVerb(ep.Name)
+		rpc.Model = findModel(noun, app)
+		if rpc.Model != nil && rpc.Method == "GET" && rpc.Model.Name != noun {
+			rpc.List = true
+		}
+		for _, p := range ep.Params {
+			rpc.Fields = append(rpc.Fields, Field{
+				Param: p.Name,
+				Name:  toSnakeCase(p.Name),
+				Type:  protoType(paramType(p.Name, rpc.Model)),
+			})
+		}
+		rpcs = append(rpcs, rpc)
+	}
+	return rpcs
+}
+
+// JSONFields returns the declared names of every json-typed model field.
+// Proto messages carry these as JSON-encoded strings, so servers encode
+// them before building a response; each backend maps the names to its
+// own property naming.
+func JSONFields(app *ir.Application) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, m := range app.Data {
+		for _, f := range m.Fields {
+			if strings.EqualFold(f.Type, "json") && !seen[f.Name] {
+				seen[f.Name] = true
+				names = append(names, f.Name)
+			}
+		}
+	}
+	return names
+}
+
+// ── Naming ──
+
+// PackageName returns the proto package: "TaskFlow" → "taskflow.v1".
+func PackageName(app *ir.Application) string {
+	return baseName(app) + ".v1"
+}
+
+// ServiceName returns the service name: "TaskFlow" → "TaskFlowService".
+func ServiceName(app *ir.Application) string {
+	name := toPascalCase(app.Name)
+	if name == "" {
+		name = "App"
+	}
+	return name + "Service"
+}
+
+// FullServiceName returns the fully-qualified service name used in gRPC
+// method paths and gateway selectors: "taskflow.v1.TaskFlowService".
+func FullServiceName(app *ir.Application) string {
+	return PackageName(app) + "." + ServiceName(app)
+}
+
+// ProtoPath returns the proto file path relative to the proto root:
+// "taskflow/v1/taskflow.proto".
+func ProtoPath(app *ir.Application) string {
+	base := baseName(app)
+	return base + "/v1/" + base + ".proto"
+}
+
+// ModuleName returns the Go module path of the generated stubs and gateway:
+// "TaskFlow" → "taskflow-grpc".
+func ModuleName(app *ir.Application) string {
+	return baseName(app) + "-grpc"
+}
+
+// StubImportPath returns the Go import path of the generated stubs.
+func StubImportPath(app *ir.Application) string {
+	base := baseName(app)
+	return ModuleName(app) + "/gen/go/" + base + "/v1"
+}
+
+// baseName returns the app name lowercased with non-alphanumerics removed,
+// which is valid as a proto package, Go package, and directory name.
+func baseName(app *ir.Application) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(app.Name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "app" + name
+	}
+	return name
+}
+
+// ── Proto ──
+
+// Proto returns the service definition. Enums, dates, and ids are strings
+// so every backend's JSON round-trips unchanged; json fields and responses
+// without a known model are JSON-encoded strings.
+func Proto(app *ir.Application) string {
+	var b strings.Builder
+	rpcs := RPCs(app)
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n\n", PackageName(app))
+
+	fmt.Fprintf(&b, "// %s exposes the %s API over gRPC. Each RPC runs the same\n", ServiceName(app), appTitle(app))
+	b.WriteString("// business steps as the matching REST endpoint.\n")
+	fmt.Fprintf(&b, "service %s {\n", ServiceName(app))
+	for _, rpc := range rpcs {
+		comment := fmt.Sprintf("%s %s", rpc.Method, rpc.Path)
+		if rpc.Endpoint.Auth {
+			comment += " (requires authorization metadata)"
+		}
+		fmt.Fprintf(&b, "  // %s\n", comment)
+		fmt.Fprintf(&b, "  rpc %s(%sRequest) returns (%sResponse);\n", rpc.Name, rpc.Name, rpc.Name)
+	}
+	b.WriteString("}\n")
+
+	for _, model := range app.Data {
+		b.WriteString("\n")
+		writeModelMessage(&b, model)
+	}
+
+	for _, rpc := range rpcs {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "message %sRequest {\n", rpc.Name)
+		for i, f := range rpc.Fields {
+			fmt.Fprintf(&b, "  %s %s = %d;\n", f.Type, f.Name, i+1)
+		}
+		b.WriteString("}\n\n")
+
+		fmt.Fprintf(&b, "message %sResponse {\n", rpc.Name)
+		switch {
+		case rpc.Model != nil && rpc.List:
+			fmt.Fprintf(&b, "  repeated %s data = 1;\n", toPascalCase(rpc.Model.Name))
+		case rpc.Model != nil:
+			fmt.Fprintf(&b, "  %s data = 1;\n", toPascalCase(rpc.Model.Name))
+		default:
+			b.WriteString("  // JSON-encoded response data.\n")
+			b.WriteString("  string data = 1;\n")
+		}
+		b.WriteString("  // Set by endpoints that issue an auth token (sign-up, login).\n")
+		b.WriteString("  string token = 2;\n")
+		b.WriteString("  string message = 3;\n")
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+func writeModelMessage(b *strings.Builder, model *ir.DataModel) {
+	fmt.Fprintf(b, "message %s {\n", toPascalCase(model.Name))
+	n := 1
+	seen := map[string]bool{}
+	field := func(typ, name, comment string) {
+		if seen[name] {
+			return // e.g. a declared "payment_method_id" field that is also a foreign key
+		}
+		seen[name] = true
+		if comment != "" {
+			fmt.Fprintf(b, "  // %s\n", comment)
+		}
+		fmt.Fprintf(b, "  %s %s = %d;\n", typ, name, n)
+		n++
+	}
+
+	field("string", "id", "")
+	for _, f := range model.Fields {
+		if strings.EqualFold(f.Name, "password") {
+			continue // never leaves the backend
+		}
+		comment := ""
+		switch strings.ToLower(f.Type) {
+		case "enum":
+			comment = "One of: " + strings.Join(f.EnumValues, ", ")
+		case "json":
+			comment = "JSON-encoded."
+		case "date", "datetime":
+			comment = "ISO 8601."
+		}
+		field(protoType(f.Type), toSnakeCase(f.Name), comment)
+	}
+	for _, rel := range model.Relations {
+		if rel.Kind == "belongs_to" {
+			field("string", toSnakeCase(rel.Target)+"_id", "")
+		}
+	}
+	field("string", "created_at", "")
+	field("string", "updated_at", "")
+	b.WriteString("}\n")
+}
+
+// protoType maps an IR field type to a proto3 scalar.
+func protoType(irType string) string {
+	switch strings.ToLower(irType) {
+	case "number":
+		return "int32"
+	case "decimal":
+		return "double"
+	case "boolean":
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// ── Endpoint inference ──
+
+func httpMethod(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "get"), strings.HasPrefix(lower, "list"):
+		return "GET"
+	case strings.HasPrefix(lower, "delete"):
+		return "DELETE"
+	case strings.HasPrefix(lower, "update"):
+		return "PUT"
+	default:
+		return "POST"
+	}
+}
+
+func stripVerb(name string) string {
+	for _, prefix := range []string{"Get", "List", "Create", "Update", "Delete"} {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return name[len(prefix):]
+		}
+	}
+	return name
+}
+
+// findModel matches a noun ("Tasks", "Task", "Categories") to a data model.
+func findModel(noun string, app *ir.Application) *ir.DataModel {
+	for _, candidate := range []string{noun, singularize(noun)} {
+		for _, m := range app.Data {
+			if strings.EqualFold(m.Name, candidate) {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+func singularize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+// paramType returns the IR type of the model field a parameter names, or
+// "text" when it names no field.
+func paramType(name string, model *ir.DataModel) string {
+	if model == nil {
+		return "text"
+	}
+	for _, f := range model.Fields {
+		if strings.EqualFold(f.Name, name) && f.Type != "" {
+			return f.Type
+		}
+	}
+	return "text"
+}
+
+// ── Case helpers ──
+
+func appTitle(app *ir.Application) string {
+	if app.Name == "" {
+		return "the application"
+	}
+	return app.Name
+}
+
+// words splits an identifier on spaces, underscores, hyphens, and case
+// boundaries: "GetTasksByUser" → [get tasks by user].
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	runes := []rune(s)
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, strings.ToLower(string(cur)))
+			cur = nil
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == ' ' || r == '_' || r == '-':
+			flush()
+		case unicode.IsUpper(r) && len(cur) > 0 &&
+			(!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return out
+}
+
+func toPascalCase(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+func toSnakeCase(s string) string {
+	return strings.Join(words(s), "_")
+}
+
+// lowerCamel applies protobuf's JSON name rule: drop underscores and
+// uppercase the letter after each one.
+func lowerCamel(snake string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range snake {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// toKebabCase matches the backend route naming (every uppercase letter
+// starts a segment), so gateway paths mirror the REST routes.
+func toKebabCase(s string) string {
+	var result []rune
+	for i, r := range s {
+		if unicode.IsUpper(r) && i > 0 {
+			result = append(result, '-')
+		}
+		result = append(result, unicode.ToLower(r))
+	}
+	return string(result)
+}
@@ -0,0 +1,156 @@
+package hosting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateBackendFlyToml produces the backend's fly.toml. Machines stop
+// when idle and start on the next request; the comment lists the secrets
+// to set before the first deploy.
+func generateBackendFlyToml(app *ir.Application) string {
+	var b strings.Builder
+	name := FlyApp(app, true)
+	port := docker.BackendPort(app)
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("#\n")
+	b.WriteString("# Set the API's secrets before its first deploy")
+	secrets := Secrets(app)
+	if !ir.UsesMongoDB(app) {
+		// Attaching a Fly Postgres cluster sets DATABASE_URL.
+		b.WriteString(":\n")
+		fmt.Fprintf(&b, "#   fly postgres create && fly postgres attach --app %s <database>\n", name)
+		secrets = remove(secrets, "DATABASE_URL")
+	} else {
+		b.WriteString(", with DATABASE_URL\n# your MongoDB connection string:\n")
+	}
+	if len(secrets) > 0 {
+		fmt.Fprintf(&b, "#   fly secrets set --app %s", name)
+		for _, s := range secrets {
+			fmt.Fprintf(&b, " %s=...", s)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "app = %q\n", name)
+	fmt.Fprintf(&b, "primary_region = %q\n\n", region(app))
+
+	b.WriteString("[build]\n")
+	b.WriteString("  dockerfile = \"Dockerfile\"\n\n")
+
+	b.WriteString("[env]\n")
+	fmt.Fprintf(&b, "  PORT = %q\n\n", port)
+
+	writeHTTPService(&b, port)
+	if docker.BackendDir(app) != "go" {
+		b.WriteString("\n  [[http_service.checks]]\n")
+		b.WriteString("    grace_period = \"10s\"\n")
+		b.WriteString("    interval = \"30s\"\n")
+		b.WriteString("    method = \"GET\"\n")
+		b.WriteString("    path = \"/health\"\n")
+		b.WriteString("    timeout = \"5s\"\n")
+	}
+
+	b.WriteString("\n[[vm]]\n")
+	b.WriteString("  memory = \"512mb\"\n")
+
+	return b.String()
+}
+
+// generateFrontendFlyToml produces the frontend's fly.toml, serving the
+// build with Nginx.
+func generateFrontendFlyToml(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n\n")
+	fmt.Fprintf(&b, "app = %q\n", FlyApp(app, false))
+	fmt.Fprintf(&b, "primary_region = %q\n\n", region(app))
+	b.WriteString("[build]\n")
+	b.WriteString("  dockerfile = \"Dockerfile\"\n\n")
+	writeHTTPService(&b, "80")
+	b.WriteString("\n[[vm]]\n")
+	b.WriteString("  memory = \"256mb\"\n")
+
+	return b.String()
+}
+
+func writeHTTPService(b *strings.Builder, port string) {
+	b.WriteString("[http_service]\n")
+	fmt.Fprintf(b, "  internal_port = %s\n", port)
+	b.WriteString("  force_https = true\n")
+	b.WriteString("  auto_stop_machines = \"stop\"\n")
+	b.WriteString("  auto_start_machines = true\n")
+	b.WriteString("  min_machines_running = 0\n")
+}
+
+// generateFrontendDockerfile produces the frontend's Dockerfile for Fly.io:
+// the build, served by Nginx with nginx.conf.
+func generateFrontendDockerfile(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n\n")
+	b.WriteString("# Build stage\n")
+	b.WriteString("FROM node:20-alpine AS builder\n\n")
+	b.WriteString("WORKDIR /app\n\n")
+	b.WriteString("COPY package.json package-lock.json* ./\n")
+	b.WriteString("RUN npm install\n\n")
+	b.WriteString("COPY . .\n")
+	b.WriteString("RUN npm run build\n\n")
+	b.WriteString("# Serve stage\n")
+	b.WriteString("FROM nginx:alpine\n\n")
+	fmt.Fprintf(&b, "COPY --from=builder /app/%s /usr/share/nginx/html\n", buildOutput(app))
+	b.WriteString("COPY nginx.conf /etc/nginx/conf.d/default.conf\n\n")
+	b.WriteString("EXPOSE 80\n\n")
+	b.WriteString("CMD [\"nginx\", \"-g\", \"daemon off;\"]\n")
+
+	return b.String()
+}
+
+// generateNginxConf produces the frontend's Nginx config: the backend's
+// paths proxied to its Fly.io app, and the build for everything else.
+func generateNginxConf(app *ir.Application) string {
+	var b strings.Builder
+	host := FlyApp(app, true) + ".fly.dev"
+
+	b.WriteString("# Generated by Human compiler — do not edit\n\n")
+	b.WriteString("server {\n")
+	b.WriteString("  listen 80;\n")
+	if HasBackend(app) {
+		for _, p := range proxiedPaths(app) {
+			if p[0] == "/api/" {
+				b.WriteString("\n  location /api/ {\n")
+				fmt.Fprintf(&b, "    proxy_pass https://%s;\n", host)
+			} else {
+				fmt.Fprintf(&b, "\n  location = %s {\n", p[0])
+				fmt.Fprintf(&b, "    proxy_pass https://%s%s;\n", host, p[1])
+			}
+			b.WriteString("    proxy_ssl_server_name on;\n")
+			fmt.Fprintf(&b, "    proxy_set_header Host %s;\n", host)
+			b.WriteString("    proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;\n")
+			b.WriteString("  }\n")
+		}
+	}
+	b.WriteString("\n  location / {\n")
+	b.WriteString("    root /usr/share/nginx/html;\n")
+	b.WriteString("    try_files $uri $uri/ /index.html;\n")
+	fmt.Fprintf(&b, "    add_header Cache-Control \"%s\";\n", ir.RevalidateCacheControl)
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func remove(names []string, name string) []string {
+	var out []string
+	for _, n := range names {
+		if n != name {
+			out = append(out, n)
+		}
+	}
+	return out
+}
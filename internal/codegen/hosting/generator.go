@@ -0,0 +1,162 @@
+package hosting
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Platforms a build can deploy to with this generator.
+const (
+	Vercel  = "vercel"
+	Netlify = "netlify"
+	Fly     = "fly"
+)
+
+// Generator produces the config for deploying to Vercel, Netlify, or
+// Fly.io. Vercel and Netlify serve static frontends, so the frontend goes
+// there and the backend goes to Fly.io; `deploy to Fly.io` runs both on
+// Fly. The frontend proxies /api to the backend, so it calls the API on
+// its own origin.
+type Generator struct{}
+
+// Generate writes the platform config into the frontend and backend
+// output directories.
+func (g Generator) Generate(app *ir.Application, outputDir string) error {
+	files := map[string]string{}
+
+	if HasBackend(app) {
+		for path, content := range docker.BackendImageFiles(app) {
+			files[filepath.Join(outputDir, path)] = content
+		}
+		files[filepath.Join(outputDir, docker.BackendDir(app), "fly.toml")] = generateBackendFlyToml(app)
+	}
+
+	if HasFrontend(app) {
+		feDir := filepath.Join(outputDir, docker.FrontendDir(app))
+		switch Platform(app) {
+		case Vercel:
+			files[filepath.Join(feDir, "vercel.json")] = generateVercelJSON(app)
+		case Netlify:
+			files[filepath.Join(feDir, "netlify.toml")] = generateNetlifyToml(app)
+		case Fly:
+			files[filepath.Join(feDir, "fly.toml")] = generateFrontendFlyToml(app)
+			files[filepath.Join(feDir, "Dockerfile")] = generateFrontendDockerfile(app)
+			files[filepath.Join(feDir, "nginx.conf")] = generateNginxConf(app)
+			files[filepath.Join(feDir, ".dockerignore")] = "node_modules\n.git\ndist\n.env\nnpm-debug.log*\n"
+		}
+	}
+
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
+}
+
+// Platform returns the platform the app deploys to — Vercel, Netlify, or
+// Fly — or "" for any other deploy target.
+func Platform(app *ir.Application) string {
+	if app.Config == nil {
+		return ""
+	}
+	deploy := strings.ToLower(app.Config.Deploy)
+	switch {
+	case strings.Contains(deploy, "vercel"):
+		return Vercel
+	case strings.Contains(deploy, "netlify"):
+		return Netlify
+	case strings.Contains(deploy, "fly"):
+		return Fly
+	}
+	return ""
+}
+
+// HasFrontend reports whether the app has a frontend framework configured.
+func HasFrontend(app *ir.Application) bool {
+	return app.Config != nil && app.Config.Frontend != "" && !strings.EqualFold(app.Config.Frontend, "none")
+}
+
+// HasBackend reports whether the app has a backend framework configured.
+func HasBackend(app *ir.Application) bool {
+	return app.Config != nil && app.Config.Backend != "" && !strings.EqualFold(app.Config.Backend, "none")
+}
+
+// FlyApp returns the name of the app's Fly.io app: its name, suffixed with
+// the target environment unless that is production, and with "-api" for
+// the backend.
+func FlyApp(app *ir.Application, backend bool) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(app.Name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	name := strings.Trim(b.String(), "-")
+	if name == "" {
+		name = "app"
+	}
+	if env := ir.TargetEnvironment(app); env != nil {
+		if lower := strings.ToLower(env.Name); lower != "production" && lower != "prod" {
+			name += "-" + lower
+		}
+	}
+	if backend {
+		name += "-api"
+	}
+	return name
+}
+
+// BackendURL returns the origin the backend serves at on Fly.io.
+func BackendURL(app *ir.Application) string {
+	return "https://" + FlyApp(app, true) + ".fly.dev"
+}
+
+// Secrets returns the environment variables the backend reads that are
+// not in its fly.toml, which must be set with `fly secrets set`.
+func Secrets(app *ir.Application) []string {
+	var names []string
+	for _, v := range docker.CollectEnvVars(app) {
+		switch v.Name {
+		case "PORT", "DOMAIN", docker.FrontendAPIEnvName(app):
+			continue
+		}
+		names = append(names, v.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// region returns the Fly.io region the app runs in: the target
+// environment's region, else iad (Ashburn, Virginia).
+func region(app *ir.Application) string {
+	if env := ir.TargetEnvironment(app); env != nil && env.Config["region"] != "" {
+		return env.Config["region"]
+	}
+	return "iad"
+}
+
+// buildOutput returns the directory the frontend's build writes to.
+func buildOutput(app *ir.Application) string {
+	if docker.FrontendDir(app) == "angular" {
+		return "dist/app/browser"
+	}
+	return "dist"
+}
+
+// proxiedPaths returns the frontend paths the backend serves: /api, and
+// the sitemap and robots.txt it builds under /api. Each maps a path prefix
+// or file to the backend path it proxies to.
+func proxiedPaths(app *ir.Application) [][2]string {
+	paths := [][2]string{{"/api/", "/api/"}}
+	if app.Sitemap != nil {
+		paths = append(paths,
+			[2]string{"/sitemap.xml", "/api/sitemap.xml"},
+			[2]string{"/robots.txt", "/api/robots.txt"},
+		)
+	}
+	return paths
+}
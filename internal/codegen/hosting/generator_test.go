@@ -0,0 +1,195 @@
+package hosting
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func hostedApp(deploy string) *ir.Application {
+	return &ir.Application{
+		Name: "Task Flow",
+		Config: &ir.BuildConfig{
+			Frontend: "React with TypeScript",
+			Backend:  "Node with Express",
+			Database: "PostgreSQL",
+			Deploy:   deploy,
+		},
+	}
+}
+
+func TestPlatform(t *testing.T) {
+	tests := []struct {
+		deploy string
+		want   string
+	}{
+		{"Vercel", Vercel},
+		{"Netlify", Netlify},
+		{"Fly.io", Fly},
+		{"fly", Fly},
+		{"Docker", ""},
+		{"AWS", ""},
+	}
+	for _, tt := range tests {
+		if got := Platform(hostedApp(tt.deploy)); got != tt.want {
+			t.Errorf("Platform(%q) = %q, want %q", tt.deploy, got, tt.want)
+		}
+	}
+	if (Generator{}).Enabled(&ir.Application{}) {
+		t.Error("an app without a build block should not enable the hosting generator")
+	}
+}
+
+func TestFlyApp(t *testing.T) {
+	app := hostedApp("Fly.io")
+	if got := FlyApp(app, false); got != "task-flow" {
+		t.Errorf("frontend app = %q, want task-flow", got)
+	}
+	if got := BackendURL(app); got != "https://task-flow-api.fly.dev" {
+		t.Errorf("backend URL = %q", got)
+	}
+
+	app.Environments = []*ir.Environment{{Name: "staging"}, {Name: "production"}}
+	app.Config.Env = "staging"
+	if got := FlyApp(app, true); got != "task-flow-staging-api" {
+		t.Errorf("staging backend app = %q, want task-flow-staging-api", got)
+	}
+	app.Config.Env = "production"
+	if got := FlyApp(app, true); got != "task-flow-api" {
+		t.Errorf("production backend app = %q, want task-flow-api", got)
+	}
+}
+
+func TestVercelJSON(t *testing.T) {
+	app := hostedApp("Vercel")
+	app.Sitemap = &ir.Sitemap{}
+	var cfg vercelConfig
+	if err := json.Unmarshal([]byte(generateVercelJSON(app)), &cfg); err != nil {
+		t.Fatalf("vercel.json is not valid JSON: %v", err)
+	}
+	if cfg.Framework != "vite" || cfg.OutputDirectory != "dist" {
+		t.Errorf("framework = %q, output = %q", cfg.Framework, cfg.OutputDirectory)
+	}
+	want := []vercelRewrite{
+		{"/api/:path*", "https://task-flow-api.fly.dev/api/:path*"},
+		{"/sitemap.xml", "https://task-flow-api.fly.dev/api/sitemap.xml"},
+		{"/robots.txt", "https://task-flow-api.fly.dev/api/robots.txt"},
+		{"/(.*)", "/index.html"},
+	}
+	if len(cfg.Rewrites) != len(want) {
+		t.Fatalf("rewrites = %+v, want %+v", cfg.Rewrites, want)
+	}
+	for i := range want {
+		if cfg.Rewrites[i] != want[i] {
+			t.Errorf("rewrite %d = %+v, want %+v", i, cfg.Rewrites[i], want[i])
+		}
+	}
+
+	app.Config.Frontend = "Angular"
+	if err := json.Unmarshal([]byte(generateVercelJSON(app)), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Framework != "angular" || cfg.OutputDirectory != "dist/app/browser" {
+		t.Errorf("angular: framework = %q, output = %q", cfg.Framework, cfg.OutputDirectory)
+	}
+}
+
+func TestNetlifyToml(t *testing.T) {
+	out := generateNetlifyToml(hostedApp("Netlify"))
+	for _, want := range []string{
+		"publish = \"dist\"",
+		"from = \"/api/*\"\n  to = \"https://task-flow-api.fly.dev/api/:splat\"\n  status = 200\n  force = true",
+		"from = \"/*\"\n  to = \"/index.html\"",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("netlify.toml missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "/api/*") > strings.Index(out, "from = \"/*\"") {
+		t.Errorf("the API redirect must come before the SPA fallback:\n%s", out)
+	}
+}
+
+func TestBackendFlyToml(t *testing.T) {
+	app := hostedApp("Vercel")
+	app.Integrations = []*ir.Integration{{Service: "Stripe", Credentials: map[string]string{"secret key": "STRIPE_SECRET_KEY"}}}
+	out := generateBackendFlyToml(app)
+	for _, want := range []string{
+		"app = \"task-flow-api\"",
+		"primary_region = \"iad\"",
+		"PORT = \"3001\"",
+		"internal_port = 3001",
+		"path = \"/health\"",
+		"fly postgres attach --app task-flow-api",
+		"fly secrets set --app task-flow-api JWT_SECRET=... STRIPE_SECRET_KEY=...",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("fly.toml missing %q:\n%s", want, out)
+		}
+	}
+
+	app.Config.Backend = "Go with Gin"
+	if out := generateBackendFlyToml(app); strings.Contains(out, "/health") || !strings.Contains(out, "internal_port = 8080") {
+		t.Errorf("the Go backend serves on 8080 without a health endpoint:\n%s", out)
+	}
+}
+
+func TestNginxConfProxiesAPI(t *testing.T) {
+	out := generateNginxConf(hostedApp("Fly.io"))
+	for _, want := range []string{
+		"proxy_pass https://task-flow-api.fly.dev;",
+		"proxy_set_header Host task-flow-api.fly.dev;",
+		"try_files $uri $uri/ /index.html;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("nginx.conf missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateSplitsFrontendAndBackend(t *testing.T) {
+	tests := []struct {
+		deploy string
+		want   []string
+		absent []string
+	}{
+		{"Vercel", []string{"react/vercel.json", "node/fly.toml", "node/Dockerfile"}, []string{"react/fly.toml", "react/netlify.toml"}},
+		{"Netlify", []string{"react/netlify.toml", "node/fly.toml"}, []string{"react/vercel.json"}},
+		{"Fly.io", []string{"react/fly.toml", "react/Dockerfile", "react/nginx.conf", "node/fly.toml", "node/Dockerfile"}, []string{"react/vercel.json"}},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if err := (Generator{}).Generate(hostedApp(tt.deploy), dir); err != nil {
+			t.Fatalf("%s: Generate: %v", tt.deploy, err)
+		}
+		for _, f := range tt.want {
+			if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+				t.Errorf("%s: expected %s", tt.deploy, f)
+			}
+		}
+		for _, f := range tt.absent {
+			if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+				t.Errorf("%s: unexpected %s", tt.deploy, f)
+			}
+		}
+	}
+}
+
+func TestGenerateAPIOnly(t *testing.T) {
+	dir := t.TempDir()
+	app := hostedApp("Vercel")
+	app.Config.Frontend = "none"
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "node", "fly.toml")); err != nil {
+		t.Error("the API should still deploy to Fly.io")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "react")); err == nil {
+		t.Error("an app without a frontend has nothing for Vercel")
+	}
+}
@@ -0,0 +1,43 @@
+package hosting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateNetlifyToml produces the frontend's netlify.toml: the build, the
+// backend on Fly.io behind /api, and index.html for every other path the
+// build has no file for.
+func generateNetlifyToml(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n\n")
+	b.WriteString("[build]\n")
+	b.WriteString("  command = \"npm run build\"\n")
+	fmt.Fprintf(&b, "  publish = %q\n\n", buildOutput(app))
+	b.WriteString("[build.environment]\n")
+	b.WriteString("  NODE_VERSION = \"20\"\n")
+
+	if HasBackend(app) {
+		for _, p := range proxiedPaths(app) {
+			from, to := p[0], BackendURL(app)+p[1]
+			if p[0] == "/api/" {
+				from, to = "/api/*", to+":splat"
+			}
+			b.WriteString("\n[[redirects]]\n")
+			fmt.Fprintf(&b, "  from = %q\n", from)
+			fmt.Fprintf(&b, "  to = %q\n", to)
+			b.WriteString("  status = 200\n")
+			b.WriteString("  force = true\n")
+		}
+	}
+
+	b.WriteString("\n[[redirects]]\n")
+	b.WriteString("  from = \"/*\"\n")
+	b.WriteString("  to = \"/index.html\"\n")
+	b.WriteString("  status = 200\n")
+
+	return b.String()
+}
@@ -0,0 +1,28 @@
+package hosting
+
+import (
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Meta returns the generator's metadata.
+func (g Generator) Meta() codegen.PluginMeta {
+	return codegen.PluginMeta{
+		Name:        "hosting",
+		Version:     "1.0.0",
+		Description: "Vercel, Netlify, and Fly.io deploy configuration",
+		Category:    codegen.CategoryInfra,
+	}
+}
+
+// Enabled reports whether the app deploys to Vercel, Netlify, or Fly.io.
+func (g Generator) Enabled(app *ir.Application) bool {
+	return Platform(app) != ""
+}
+
+// StageName returns the display name for progress reporting.
+func (g Generator) StageName() string { return "Generating hosting configuration" }
+
+// OutputDir returns empty because each platform's config is written into
+// the frontend and backend directories.
+func (g Generator) OutputDir() string { return "" }
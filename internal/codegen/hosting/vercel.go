@@ -0,0 +1,49 @@
+package hosting
+
+import (
+	"encoding/json"
+
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+type vercelConfig struct {
+	Schema          string          `json:"$schema"`
+	Framework       string          `json:"framework"`
+	BuildCommand    string          `json:"buildCommand"`
+	OutputDirectory string          `json:"outputDirectory"`
+	Rewrites        []vercelRewrite `json:"rewrites"`
+}
+
+type vercelRewrite struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+// generateVercelJSON produces the frontend's vercel.json: the build, the
+// backend on Fly.io behind /api, and index.html for every other path the
+// build has no file for.
+func generateVercelJSON(app *ir.Application) string {
+	cfg := vercelConfig{
+		Schema:          "https://openapi.vercel.sh/vercel.json",
+		Framework:       "vite",
+		BuildCommand:    "npm run build",
+		OutputDirectory: buildOutput(app),
+	}
+	if docker.FrontendDir(app) == "angular" {
+		cfg.Framework = "angular"
+	}
+	if HasBackend(app) {
+		for _, p := range proxiedPaths(app) {
+			if p[0] == "/api/" {
+				cfg.Rewrites = append(cfg.Rewrites, vercelRewrite{"/api/:path*", BackendURL(app) + "/api/:path*"})
+			} else {
+				cfg.Rewrites = append(cfg.Rewrites, vercelRewrite{p[0], BackendURL(app) + p[1]})
+			}
+		}
+	}
+	cfg.Rewrites = append(cfg.Rewrites, vercelRewrite{"/(.*)", "/index.html"})
+
+	out, _ := json.MarshalIndent(cfg, "", "  ")
+	return string(out) + "\n"
+}
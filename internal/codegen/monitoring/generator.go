@@ -2,12 +2,12 @@ package monitoring
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -18,12 +18,12 @@ type Generator struct{}
 // Generate writes monitoring configuration files to outputDir.
 func (g Generator) Generate(app *ir.Application, outputDir string) error {
 	files := map[string]string{
-		filepath.Join(outputDir, "prometheus", "prometheus.yml"):     generatePrometheusConfig(app),
-		filepath.Join(outputDir, "prometheus", "alerts.yml"):         generateAlertRules(app),
+		filepath.Join(outputDir, "prometheus", "prometheus.yml"):                             generatePrometheusConfig(app),
+		filepath.Join(outputDir, "prometheus", "alerts.yml"):                                 generateAlertRules(app),
 		filepath.Join(outputDir, "grafana", "provisioning", "datasources", "prometheus.yml"): generateGrafanaDatasource(),
 		filepath.Join(outputDir, "grafana", "provisioning", "dashboards", "dashboards.yml"):  generateGrafanaDashboardProvisioning(),
 		filepath.Join(outputDir, "grafana", "dashboards", "app.json"):                        generateGrafanaDashboard(app),
-		filepath.Join(outputDir, "docker-compose.monitoring.yml"):                             generateMonitoringCompose(app),
+		filepath.Join(outputDir, "docker-compose.monitoring.yml"):                            generateMonitoringCompose(app),
 	}
 
 	// Backend instrumentation
@@ -38,24 +38,7 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		files[filepath.Join(outputDir, "instrumentation", "middleware.go")] = generateGoMiddleware(app)
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 // ── Stack Detection ──
@@ -0,0 +1,205 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeAccountBody emits the body of an endpoint the accounts block added.
+// Each acts on the signed-in user, and none responds with their password.
+// Changing the password and deleting the account both take the current
+// password, so a stolen session alone can do neither.
+func writeAccountBody(b *strings.Builder, ep *ir.Endpoint, app *ir.Application) {
+	user := toCamelCase(app.Accounts.Model)
+
+	switch ep.Account {
+	case ir.AccountProfile:
+		fmt.Fprintf(b, "    const user = await prisma.%s.findUnique({ where: { id: req.userId! } });\n", user)
+		writeAccountNotFound(b)
+		b.WriteString("    const { password: _password, ...profile } = user;\n")
+		b.WriteString("    return res.json({ data: profile });\n")
+
+	case ir.AccountUpdateProfile:
+		var fields []string
+		for _, f := range ir.ProfileFields(app) {
+			fields = append(fields, f.Name)
+			if !f.Unique || f.EncryptsAtRest() {
+				continue
+			}
+			fmt.Fprintf(b, "    // %s belongs to one account at a time\n", f.Name)
+			fmt.Fprintf(b, "    if (await prisma.%s.findFirst({ where: { %s, NOT: { id: req.userId! } } })) {\n", user, f.Name)
+			fmt.Fprintf(b, "      return res.status(409).json({ error: '%s is already taken' });\n", f.Name)
+			b.WriteString("    }\n")
+		}
+		fmt.Fprintf(b, "    const user = await prisma.%s.update({\n", user)
+		b.WriteString("      where: { id: req.userId! },\n")
+		fmt.Fprintf(b, "      data: { %s },\n", strings.Join(fields, ", "))
+		b.WriteString("    });\n")
+		b.WriteString("    const { password: _password, ...profile } = user;\n")
+		b.WriteString("    return res.json({ data: profile });\n")
+
+	case ir.AccountChangePassword:
+		fmt.Fprintf(b, "    const user = await prisma.%s.findUnique({ where: { id: req.userId! } });\n", user)
+		writeAccountNotFound(b)
+		b.WriteString("    if (!(await bcrypt.compare(current_password, user.password))) {\n")
+		b.WriteString("      return res.status(403).json({ error: 'Your current password is incorrect' });\n")
+		b.WriteString("    }\n")
+		b.WriteString("    if (new_password === current_password) {\n")
+		b.WriteString("      return res.status(400).json({ error: 'Choose a password you are not using now' });\n")
+		b.WriteString("    }\n")
+		fmt.Fprintf(b, "    await prisma.%s.update({\n", user)
+		b.WriteString("      where: { id: user.id },\n")
+		b.WriteString("      data: { password: await bcrypt.hash(new_password, 12) },\n")
+		b.WriteString("    });\n")
+		b.WriteString("    return res.status(204).end();\n")
+
+	case ir.AccountDelete:
+		fmt.Fprintf(b, "    const user = await prisma.%s.findUnique({ where: { id: req.userId! } });\n", user)
+		writeAccountNotFound(b)
+		b.WriteString("    if (!(await bcrypt.compare(password, user.password))) {\n")
+		b.WriteString("      return res.status(403).json({ error: 'Your password is incorrect' });\n")
+		b.WriteString("    }\n")
+		fmt.Fprintf(b, "    await prisma.%s.delete({ where: { id: user.id } });\n", user)
+		b.WriteString("    return res.status(204).end();\n")
+
+	case ir.AccountInvite, ir.AccountInvitation, ir.AccountAcceptInvite:
+		writeInvitationBody(b, ep, app)
+	}
+}
+
+// writeInvitationBody emits the body of an invitation endpoint. A member
+// invites a teammate to their own team as one of the app's roles; the
+// invitation is a secret token, emailed as a link to the accept page when
+// the app sends email and given back to the inviter to share when it
+// doesn't. Accepting takes the signed-in user whose email it was sent to,
+// before it expires, and makes them a member with the role, which the
+// policies then check.
+func writeInvitationBody(b *strings.Builder, ep *ir.Endpoint, app *ir.Application) {
+	inv := app.Accounts.Invite
+	if inv == nil || inv.Team == "" {
+		b.WriteString("    return res.status(501).json({ error: 'Invitations have no team to join' });\n")
+		return
+	}
+	user := app.Accounts.Model
+	teamKey := toCamelCase(inv.Team) + "Id"
+	userKey := toCamelCase(user) + "Id"
+	invitation := toCamelCase(ir.InvitationModel)
+	membership := toCamelCase(ir.MembershipModel)
+	team := findModel(inv.Team, app)
+	hasEmail := findModel(user, app).FieldNamed("email") != nil
+
+	switch ep.Account {
+	case ir.AccountInvite:
+		fmt.Fprintf(b, "    const membership = await prisma.%s.findFirst({ where: { %s: req.userId! } });\n", membership, userKey)
+		b.WriteString("    if (!membership) {\n")
+		fmt.Fprintf(b, "      return res.status(403).json({ error: 'Only %s members can invite teammates' });\n", ir.FieldLabel(inv.Team))
+		b.WriteString("    }\n")
+		fmt.Fprintf(b, "    const invitedRole = role || '%s';\n", inv.Role)
+		if len(inv.Roles) > 0 {
+			quoted := make([]string, len(inv.Roles))
+			for i, r := range inv.Roles {
+				quoted[i] = "'" + r + "'"
+			}
+			fmt.Fprintf(b, "    if (![%s].includes(invitedRole)) {\n", strings.Join(quoted, ", "))
+			fmt.Fprintf(b, "      return res.status(400).json({ error: 'role must be one of: %s' });\n", strings.Join(inv.Roles, ", "))
+			b.WriteString("    }\n")
+		}
+		if hasEmail {
+			fmt.Fprintf(b, "    if (await prisma.%s.findFirst({ where: { %s: membership.%s, %s: { email } } })) {\n", membership, teamKey, teamKey, toCamelCase(user))
+			b.WriteString("      return res.status(409).json({ error: `${email} is already a teammate` });\n")
+			b.WriteString("    }\n")
+		}
+		b.WriteString("    // A new invitation replaces one still pending for the same email\n")
+		fmt.Fprintf(b, "    await prisma.%s.deleteMany({ where: { %s: membership.%s, email, accepted: null } });\n", invitation, teamKey, teamKey)
+		fmt.Fprintf(b, "    const invitation = await prisma.%s.create({\n", invitation)
+		b.WriteString("      data: {\n")
+		b.WriteString("        email,\n")
+		b.WriteString("        token: randomBytes(32).toString('hex'),\n")
+		b.WriteString("        role: invitedRole,\n")
+		fmt.Fprintf(b, "        expires: new Date(Date.now() + %d * 24 * 60 * 60 * 1000),\n", inv.Expires)
+		fmt.Fprintf(b, "        %s: membership.%s,\n", teamKey, teamKey)
+		b.WriteString("      },\n")
+		b.WriteString("    });\n")
+		fmt.Fprintf(b, "    const link = `${(process.env.SITE_URL || req.get('origin') || '').replace(/\\/$/, '')}%s?token=${invitation.token}`;\n", inv.AcceptPath())
+		b.WriteString("    const sent = { id: invitation.id, email, role: invitedRole, expires: invitation.expires };\n")
+		if ir.EmailIntegration(app) != nil {
+			subject := "You're invited to join the team"
+			if team != nil && team.FieldNamed("name") != nil {
+				fmt.Fprintf(b, "    const %s = await prisma.%s.findUnique({ where: { id: membership.%s } });\n", toCamelCase(inv.Team), toCamelCase(inv.Team), teamKey)
+				subject = fmt.Sprintf("You're invited to join ${%s?.name}", toCamelCase(inv.Team))
+			}
+			b.WriteString("    await sendEmail({\n")
+			b.WriteString("      to: email,\n")
+			fmt.Fprintf(b, "      subject: `%s`,\n", subject)
+			fmt.Fprintf(b, "      text: `You've been invited as ${invitedRole}. Accept within %d days: ${link}`,\n", inv.Expires)
+			b.WriteString("    });\n")
+			b.WriteString("    return res.status(201).json({ data: sent });\n")
+		} else {
+			b.WriteString("    // No email integration: the inviter shares the link\n")
+			b.WriteString("    return res.status(201).json({ data: { ...sent, link } });\n")
+		}
+
+	case ir.AccountInvitation:
+		b.WriteString("    const token = String(req.query.token ?? '');\n")
+		fmt.Fprintf(b, "    const invitation = await prisma.%s.findUnique({\n", invitation)
+		b.WriteString("      where: { token },\n")
+		fmt.Fprintf(b, "      include: { %s: true },\n", toCamelCase(inv.Team))
+		b.WriteString("    });\n")
+		writeInvitationUsable(b)
+		b.WriteString("    return res.json({\n")
+		b.WriteString("      data: {\n")
+		b.WriteString("        email: invitation.email,\n")
+		b.WriteString("        role: invitation.role,\n")
+		b.WriteString("        expires: invitation.expires,\n")
+		fmt.Fprintf(b, "        team: invitation.%s,\n", toCamelCase(inv.Team))
+		b.WriteString("      },\n")
+		b.WriteString("    });\n")
+
+	case ir.AccountAcceptInvite:
+		fmt.Fprintf(b, "    const invitation = await prisma.%s.findUnique({ where: { token } });\n", invitation)
+		writeInvitationUsable(b)
+		if hasEmail {
+			fmt.Fprintf(b, "    const user = await prisma.%s.findUnique({ where: { id: req.userId! } });\n", toCamelCase(user))
+			b.WriteString("    if (!user || user.email.toLowerCase() !== invitation.email.toLowerCase()) {\n")
+			b.WriteString("      return res.status(403).json({ error: `This invitation is for ${invitation.email}` });\n")
+			b.WriteString("    }\n")
+		}
+		fmt.Fprintf(b, "    const membership = await prisma.$transaction(async (tx) => {\n")
+		fmt.Fprintf(b, "      await tx.%s.update({ where: { id: invitation.id }, data: { accepted: new Date() } });\n", invitation)
+		if ir.InviteSetsUserRole(app) {
+			b.WriteString("      // The role the policies check\n")
+			fmt.Fprintf(b, "      await tx.%s.update({ where: { id: req.userId! }, data: { role: invitation.role } });\n", toCamelCase(user))
+		}
+		fmt.Fprintf(b, "      const existing = await tx.%s.findFirst({ where: { %s: req.userId!, %s: invitation.%s } });\n", membership, userKey, teamKey, teamKey)
+		b.WriteString("      if (existing) {\n")
+		fmt.Fprintf(b, "        return tx.%s.update({ where: { id: existing.id }, data: { role: invitation.role } });\n", membership)
+		b.WriteString("      }\n")
+		fmt.Fprintf(b, "      return tx.%s.create({ data: { role: invitation.role, %s: req.userId!, %s: invitation.%s } });\n", membership, userKey, teamKey, teamKey)
+		b.WriteString("    });\n")
+		b.WriteString("    return res.json({ data: membership });\n")
+	}
+}
+
+// writeInvitationUsable answers 404 for a token no invitation has, and 410
+// for an invitation already accepted or past its expiry.
+func writeInvitationUsable(b *strings.Builder) {
+	b.WriteString("    if (!invitation) {\n")
+	b.WriteString("      return res.status(404).json({ error: 'Invitation not found' });\n")
+	b.WriteString("    }\n")
+	b.WriteString("    if (invitation.accepted) {\n")
+	b.WriteString("      return res.status(410).json({ error: 'This invitation has already been accepted' });\n")
+	b.WriteString("    }\n")
+	b.WriteString("    if (invitation.expires < new Date()) {\n")
+	b.WriteString("      return res.status(410).json({ error: 'This invitation has expired' });\n")
+	b.WriteString("    }\n")
+}
+
+// writeAccountNotFound answers 404 when the signed-in user's account no
+// longer exists.
+func writeAccountNotFound(b *strings.Builder) {
+	b.WriteString("    if (!user) {\n")
+	b.WriteString("      return res.status(404).json({ error: 'Account not found' });\n")
+	b.WriteString("    }\n")
+}
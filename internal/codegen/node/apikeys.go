@@ -0,0 +1,203 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeAPIKeyAuth appends the API key half of authenticate: a request
+// carrying a key in the X-API-Key header is the key's user's, and a read
+// key can only make GET requests.
+func writeAPIKeyAuth(b *strings.Builder, app *ir.Application) {
+	role := ""
+	if ir.APIKeyRole(app) {
+		role = fmt.Sprintf("      req.userRole = apiKey.%s.role ?? undefined;\n", toCamelCase(app.Auth.APIKeys.Model))
+	}
+	fmt.Fprintf(b, `
+/**
+ * Authentication middleware — the signed-in user, by their JWT, or an
+ * external client, by the API key in the %[1]s header.
+ *
+ * Behavior:
+ *   1. An unknown API key → 401
+ *   2. A read key making anything but a GET request → 403
+ *   3. A missing, invalid, or expired JWT → 401
+ */
+export async function authenticate(req: Request, res: Response, next: NextFunction) {
+  const key = req.header('%[1]s');
+  if (key) {
+    try {
+      const apiKey = await findApiKey(key);
+      if (!apiKey) {
+        return res.status(401).json({ error: 'Invalid API key' });
+      }
+      if (!allows(apiKey.scopes, req.method)) {
+        return res.status(403).json({ error: 'This API key can only read' });
+      }
+      req.userId = apiKey.%[2]sId;
+%[3]s      req.apiKeyId = apiKey.id;
+      return next();
+    } catch (error) {
+      return next(error);
+    }
+  }
+
+  const header = req.headers.authorization;
+  if (!header || !header.startsWith('Bearer ')) {
+    return res.status(401).json({ error: 'Authentication required' });
+  }
+
+  const token = header.slice(7);
+  try {
+    const payload = jwt.verify(token, JWT_SECRET) as { userId: string; role?: string };
+    req.userId = payload.userId;
+    req.userRole = payload.role;
+    next();
+  } catch {
+    return res.status(401).json({ error: 'Invalid or expired token' });
+  }
+}
+`, ir.APIKeyHeader, toCamelCase(app.Auth.APIKeys.Model), role)
+}
+
+// generateAPIKeyService produces src/services/apiKeys.ts: creating keys,
+// hashing them, and finding the key a client sent.
+func generateAPIKeyService(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { createHash, randomBytes } from 'crypto';\n")
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	b.WriteString(prismaImport(app, "."))
+	fmt.Fprintf(&b, "\nconst prisma = %s;\n\n", newPrismaClient(app))
+
+	fmt.Fprintf(&b, `/** The scopes a key can have: a read key can only make GET requests. */
+export const SCOPES = ['%[1]s', '%[2]s'];
+
+/** A key's SHA-256 hash, which is all that's stored of it. */
+export function hashApiKey(key: string): string {
+  return createHash('sha256').update(key).digest('hex');
+}
+
+/** A new random key, and the start of it that's shown to recognize it by. */
+export function generateApiKey(): { key: string; prefix: string } {
+  const key = '%[3]s' + randomBytes(24).toString('base64url');
+  return { key, prefix: key.slice(0, %[4]d) };
+}
+
+/** Whether a key with these scopes may make a request of this HTTP method. */
+export function allows(scopes: string, method: string): boolean {
+  return scopes.split(',').includes('%[2]s') || ['GET', 'HEAD', 'OPTIONS'].includes(method);
+}
+
+/**
+ * The key a client sent, with its user, or null when there's no such key.
+ * Each use is recorded as the key's last.
+ */
+export async function findApiKey(key: string) {
+  const apiKey = await prisma.apiKey.findUnique({ where: { key_hash: hashApiKey(key) }, include: { %[5]s: true } });
+  if (apiKey) {
+    prisma.apiKey
+      .update({ where: { id: apiKey.id }, data: { last_used_at: new Date() } })
+      .catch((err) => console.error('Could not record API key use:', err));
+  }
+  return apiKey;
+}
+`, ir.ScopeRead, ir.ScopeWrite, ir.APIKeyPrefix, len(ir.APIKeyPrefix)+8, toCamelCase(app.Auth.APIKeys.Model))
+	return b.String()
+}
+
+// generateAPIKeyRoutes produces src/routes/apiKeys.ts: the signed-in
+// user's keys, creating one, and revoking one. Keys are managed signed in,
+// so a leaked key can't make more.
+func generateAPIKeyRoutes(app *ir.Application) string {
+	var b strings.Builder
+	owner := toCamelCase(app.Auth.APIKeys.Model) + "Id: req.userId!"
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Router, Request, Response, NextFunction } from 'express';\n")
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	b.WriteString(prismaImport(app, "../services"))
+	b.WriteString("import { authenticate } from '../middleware/auth';\n")
+	b.WriteString("import { SCOPES, generateApiKey, hashApiKey } from '../services/apiKeys';\n\n")
+	b.WriteString("const router = Router();\n")
+	fmt.Fprintf(&b, "const prisma = %s;\n\n", newPrismaClient(app))
+
+	fmt.Fprintf(&b, `// What's shown of a key: never its hash
+const KEY_FIELDS = { id: true, name: true, prefix: true, scopes: true, last_used_at: true, createdAt: true } as const;
+
+type KeyRecord = { id: string; name: string; prefix: string; scopes: string; last_used_at: Date | null; createdAt: Date };
+
+function present(apiKey: KeyRecord) {
+  return {
+    id: apiKey.id,
+    name: apiKey.name,
+    prefix: apiKey.prefix,
+    scopes: apiKey.scopes.split(','),
+    lastUsedAt: apiKey.last_used_at,
+    createdAt: apiKey.createdAt,
+  };
+}
+
+// Keys are managed signed in: an API key can't list, create, or revoke keys
+function signedIn(req: Request, res: Response, next: NextFunction) {
+  if (req.apiKeyId) {
+    return res.status(403).json({ error: "API keys can't manage API keys; sign in instead" });
+  }
+  next();
+}
+
+router.get('/', authenticate, signedIn, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const keys = await prisma.apiKey.findMany({
+      where: { %[1]s },
+      select: KEY_FIELDS,
+      orderBy: { createdAt: 'desc' },
+    });
+    res.json({ data: keys.map(present) });
+  } catch (error) {
+    next(error);
+  }
+});
+
+// Creates a key with the given scopes (read by default); the key itself is
+// in this response only
+router.post('/', authenticate, signedIn, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const name = typeof req.body.name === 'string' ? req.body.name.trim() : '';
+    if (!name) {
+      return res.status(400).json({ error: 'name is required' });
+    }
+    const scopes: unknown[] = Array.isArray(req.body.scopes) ? req.body.scopes : ['%[2]s'];
+    if (scopes.length === 0 || !scopes.every((scope) => typeof scope === 'string' && SCOPES.includes(scope))) {
+      return res.status(400).json({ error: `+"`scopes must be some of: ${SCOPES.join(', ')}`"+` });
+    }
+    const { key, prefix } = generateApiKey();
+    const apiKey = await prisma.apiKey.create({
+      data: { name, prefix, key_hash: hashApiKey(key), scopes: [...new Set(scopes)].join(','), %[1]s },
+      select: KEY_FIELDS,
+    });
+    res.status(201).json({ data: { ...present(apiKey), key } });
+  } catch (error) {
+    next(error);
+  }
+});
+
+// Revokes a key: calls with it are refused from now on
+router.delete('/:id', authenticate, signedIn, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const { count } = await prisma.apiKey.deleteMany({ where: { id: req.params.id, %[1]s } });
+    if (count === 0) {
+      return res.status(404).json({ error: 'API key not found' });
+    }
+    return res.status(204).end();
+  } catch (error) {
+    next(error);
+  }
+});
+
+export { router };
+`, owner, ir.ScopeRead)
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package node
+
+// generateAuditLog produces src/middleware/audit.ts: one JSON line per
+// request that changes data, written to stdout so it is kept with the
+// container logs. It is generated for apps with a compliance profile.
+func generateAuditLog() string {
+	return `// Generated by Human compiler — do not edit
+
+import { Request, Response, NextFunction } from 'express';
+
+const READ_METHODS = new Set(['GET', 'HEAD', 'OPTIONS']);
+
+/**
+ * Audit log — records who changed what, and whether it succeeded.
+ *
+ * Each entry is a single JSON line with "type": "audit", written once the
+ * response is sent, so the user set by authenticate() is known. Request
+ * bodies are not logged: they may hold personal data.
+ */
+export function auditLog(req: Request, res: Response, next: NextFunction) {
+  if (READ_METHODS.has(req.method)) {
+    return next();
+  }
+  const start = new Date();
+  res.on('finish', () => {
+    console.log(JSON.stringify({
+      type: 'audit',
+      time: start.toISOString(),
+      userId: req.userId ?? null,
+      role: req.userRole ?? null,
+      method: req.method,
+      path: req.originalUrl.split('?')[0],
+      status: res.statusCode,
+      ip: req.ip,
+    }));
+  });
+  next();
+}
+`
+}
@@ -0,0 +1,392 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// stripeKeyEnv returns the environment variable holding the payment
+// integration's secret key.
+func stripeKeyEnv(app *ir.Application) string {
+	if integ := ir.PaymentIntegration(app); integ != nil {
+		for _, envVar := range integ.Credentials {
+			return envVar
+		}
+	}
+	return "STRIPE_SECRET_KEY"
+}
+
+// subscriberKey returns the object key matching a user's records to the
+// userId variable: "userId", or "accountId: userId" for another users'
+// model.
+func subscriberKey(app *ir.Application) string {
+	field := toCamelCase(app.Billing.Model) + "Id"
+	if field == "userId" {
+		return field
+	}
+	return field + ": userId"
+}
+
+// planGates reports whether a plan's policy has a say in action on model:
+// a restriction refusing it, or a limit on how many records are created.
+// Those endpoints check the signed-in user's plan.
+func planGates(app *ir.Application, action, model string) bool {
+	if !ir.Bills(app) || action == "" || model == "" {
+		return false
+	}
+	for _, plan := range app.Billing.Plans {
+		for _, pol := range app.Policies {
+			if pol.Name != plan.Policy {
+				continue
+			}
+			for _, rest := range pol.Restrictions {
+				r := parseRuleText(rest.Text)
+				if r.Action == action && (r.Model == model || r.Model == "*") {
+					return true
+				}
+			}
+			for _, perm := range pol.Permissions {
+				r := parseRuleText(perm.Text)
+				if r.Action == action && r.Model == model && r.Limit > 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// generateBillingService produces src/services/billing.ts: the Stripe
+// client, the plan a user is on, and saving a subscription as Stripe's
+// webhook reports it.
+func generateBillingService(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import Stripe from 'stripe';\n")
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	b.WriteString(prismaImport(app, "."))
+	fmt.Fprintf(&b, "\nexport const stripe = new Stripe(process.env.%s || '', { apiVersion: '2024-06-20' });\n", stripeKeyEnv(app))
+	fmt.Fprintf(&b, "const prisma = %s;\n\n", newPrismaClient(app))
+
+	b.WriteString("/** Subscriptions that keep their plan: past due ones while Stripe retries the payment. */\n")
+	b.WriteString("export const ACTIVE_STATUSES = ['active', 'trialing', 'past_due'];\n\n")
+
+	free := "null"
+	if p := app.Billing.FreePlan(); p != nil {
+		free = "'" + p.Name + "'"
+	}
+	b.WriteString("/** The plan users are on until they subscribe to another. */\n")
+	fmt.Fprintf(&b, "export const FREE_PLAN: string | null = %s;\n\n", free)
+
+	key := subscriberKey(app)
+	fmt.Fprintf(&b, `/** The name of the plan a user is subscribed to, else the free plan. */
+export async function currentPlan(userId: string): Promise<string | null> {
+  const subscription = await prisma.subscription.findFirst({
+    where: { %s, status: { in: ACTIVE_STATUSES } },
+    include: { plan: true },
+    orderBy: { createdAt: 'desc' },
+  });
+  return subscription?.plan.name ?? FREE_PLAN;
+}
+
+/**
+ * Saves a subscription as Stripe reports it: its status, its plan, and when
+ * it renews. Checkout tags the subscriptions it starts with the user's id;
+ * others, e.g. made in the Stripe dashboard, are only kept in step once known.
+ */
+export async function saveSubscription(subscription: Stripe.Subscription) {
+  const priceId = subscription.items.data[0]?.price.id;
+  const plan = priceId ? await prisma.plan.findFirst({ where: { stripe_price_id: priceId } }) : null;
+  const data = {
+    status: subscription.status,
+    stripe_customer_id: typeof subscription.customer === 'string' ? subscription.customer : subscription.customer.id,
+    current_period_end: new Date(subscription.current_period_end * 1000),
+    ...(plan && { planId: plan.id }),
+  };
+  const userId = subscription.metadata.userId;
+  if (!plan || !userId) {
+    return prisma.subscription.updateMany({ where: { stripe_subscription_id: subscription.id }, data });
+  }
+  return prisma.subscription.upsert({
+    where: { stripe_subscription_id: subscription.id },
+    update: data,
+    create: { ...data, planId: plan.id, %s, stripe_subscription_id: subscription.id },
+  });
+}
+`, key, key)
+	return b.String()
+}
+
+// generateBillingRoutes produces src/routes/billing.ts: the signed-in
+// user's subscription, starting Stripe Checkout for a paid plan, opening
+// the billing portal to change plan, update the card, or cancel, and the
+// webhook Stripe reports subscription changes to.
+func generateBillingRoutes(app *ir.Application) string {
+	var b strings.Builder
+	bill := app.Billing
+	userID := toCamelCase(bill.Model) + "Id"
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Router, Request, Response, NextFunction } from 'express';\n")
+	b.WriteString("import Stripe from 'stripe';\n")
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	b.WriteString("import { authenticate } from '../middleware/auth';\n")
+	b.WriteString("import { ACTIVE_STATUSES, currentPlan, saveSubscription, stripe } from '../services/billing';\n")
+	b.WriteString(prismaImport(app, "../services"))
+	fmt.Fprintf(&b, "\nconst prisma = %s;\n", newPrismaClient(app))
+	b.WriteString("const router = Router();\n\n")
+
+	b.WriteString("/** The pricing page, which Checkout and the billing portal return to. */\n")
+	b.WriteString("function pricingUrl(req: Request): string {\n")
+	fmt.Fprintf(&b, "  return `${(process.env.SITE_URL || req.headers.origin || '').replace(/\\/$/, '')}%s`;\n", bill.PagePath())
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, `router.get('/subscription', authenticate, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const subscription = await prisma.subscription.findFirst({
+      where: { %[1]s: req.userId! },
+      include: { plan: true },
+      orderBy: { createdAt: 'desc' },
+    });
+    res.json({ data: { plan: await currentPlan(req.userId!), subscription } });
+  } catch (error) {
+    next(error);
+  }
+});
+
+router.post('/checkout', authenticate, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const plan = await prisma.plan.findUnique({ where: { name: String(req.body.plan ?? '') } });
+    if (!plan) {
+      return res.status(404).json({ error: 'Plan not found' });
+    }
+    if (!plan.stripe_price_id) {
+      return res.status(400).json({ error: `+"`The ${plan.name} plan can't be bought online`"+` });
+    }
+    const latest = await prisma.subscription.findFirst({ where: { %[1]s: req.userId! }, orderBy: { createdAt: 'desc' } });
+    if (latest && ACTIVE_STATUSES.includes(latest.status)) {
+      return res.status(409).json({ error: 'You already have a subscription: change your plan from the billing portal' });
+    }
+`, userID)
+	customer := "      ...(latest && { customer: latest.stripe_customer_id }),\n"
+	if m := findModel(bill.Model, app); m != nil && m.FieldNamed("email") != nil {
+		fmt.Fprintf(&b, "    const user = await prisma.%s.findUnique({ where: { id: req.userId! } });\n", toCamelCase(bill.Model))
+		customer = "      ...(latest ? { customer: latest.stripe_customer_id } : { customer_email: user?.email }),\n"
+	}
+	b.WriteString(`    const session = await stripe.checkout.sessions.create({
+      mode: 'subscription',
+      line_items: [{ price: plan.stripe_price_id, quantity: 1 }],
+` + customer + `      client_reference_id: req.userId!,
+      subscription_data: { metadata: { userId: req.userId! } },
+      success_url: ` + "`${pricingUrl(req)}?checkout=success`" + `,
+      cancel_url: pricingUrl(req),
+    });
+    res.json({ data: { url: session.url } });
+  } catch (error) {
+    next(error);
+  }
+});
+
+`)
+	fmt.Fprintf(&b, `router.post('/portal', authenticate, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const latest = await prisma.subscription.findFirst({ where: { %s: req.userId! }, orderBy: { createdAt: 'desc' } });
+    if (!latest) {
+      return res.status(404).json({ error: 'You have no subscription to manage' });
+    }
+    const session = await stripe.billingPortal.sessions.create({ customer: latest.stripe_customer_id, return_url: pricingUrl(req) });
+    res.json({ data: { url: session.url } });
+  } catch (error) {
+    next(error);
+  }
+});
+
+`, userID)
+	b.WriteString(`// Stripe reports subscriptions starting, changing plan, renewing, and
+// ending here; the raw body is kept so its signature can be checked.
+router.post('/webhook', async (req: Request, res: Response, next: NextFunction) => {
+  let event: Stripe.Event;
+  try {
+    event = stripe.webhooks.constructEvent(req.body, req.headers['stripe-signature'] as string, process.env.STRIPE_WEBHOOK_SECRET || '');
+  } catch {
+    return res.status(400).json({ error: 'Webhook verification failed' });
+  }
+  try {
+    switch (event.type) {
+      case 'customer.subscription.created':
+      case 'customer.subscription.updated':
+      case 'customer.subscription.deleted':
+        await saveSubscription(event.data.object);
+        break;
+    }
+    res.json({ received: true });
+  } catch (error) {
+    next(error);
+  }
+});
+
+export { router };
+`)
+	return b.String()
+}
+
+// generatePlanMiddleware produces src/middleware/plans.ts: requirePlan(),
+// which checks the signed-in user's plan against the policy named after
+// it. Restricted actions and creations past the plan's limit are refused
+// with 402 Payment Required, so the frontend can offer an upgrade.
+func generatePlanMiddleware(app *ir.Application) string {
+	var b strings.Builder
+	bill := app.Billing
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Request, Response, NextFunction } from 'express';\n")
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	b.WriteString("import { policies } from './policies';\n")
+	b.WriteString("import { currentPlan } from '../services/billing';\n")
+	b.WriteString(prismaImport(app, "../services"))
+	fmt.Fprintf(&b, "\nconst prisma = %s;\n\n", newPrismaClient(app))
+
+	b.WriteString("/** The policy each plan's subscribers follow. */\n")
+	b.WriteString("const planPolicies: Record<string, string> = {\n")
+	for _, plan := range bill.Plans {
+		if plan.Policy != "" {
+			fmt.Fprintf(&b, "  '%s': '%s',\n", plan.Name, plan.Policy)
+		}
+	}
+	b.WriteString("};\n\n")
+
+	b.WriteString(`/** The start of the day, week, month, or year a limit counts from; undefined counts all time. */
+function periodStart(period?: string): Date | undefined {
+  const now = new Date();
+  switch (period) {
+    case 'day':
+      return new Date(now.getFullYear(), now.getMonth(), now.getDate());
+    case 'week':
+      return new Date(now.getFullYear(), now.getMonth(), now.getDate() - now.getDay());
+    case 'month':
+      return new Date(now.getFullYear(), now.getMonth(), 1);
+    case 'year':
+      return new Date(now.getFullYear(), 0, 1);
+  }
+  return undefined;
+}
+
+`)
+	b.WriteString("/** How many records a user has created since a time, for each model a plan limits. */\n")
+	b.WriteString("const counts: Record<string, (userId: string, since?: Date) => Promise<number>> = {\n")
+	seen := map[string]bool{}
+	for _, limit := range ir.PlanLimits(app) {
+		if !limit.Counted(app) || seen[limit.Word] {
+			continue
+		}
+		seen[limit.Word] = true
+		fmt.Fprintf(&b, "  %s: (userId, since) =>\n    prisma.%s.count({ where: { %s, ...(since && { createdAt: { gte: since } }) } }),\n",
+			limit.Word, toCamelCase(limit.Model), subscriberKey(app))
+	}
+	b.WriteString("};\n\n")
+
+	fmt.Fprintf(&b, `/**
+ * Plan middleware — checks the signed-in user's plan against its policy.
+ *
+ * Usage:
+ *   router.post('/', authenticate, requirePlan('create', 'project'), handler);
+ *
+ * Behavior:
+ *   1. If a restriction matches the action+model → %[1]d
+ *   2. If a permission limits how many are created and the user has reached it → %[1]d
+ *   3. Otherwise → allowed
+ */
+export function requirePlan(action: string, model: string) {
+  return async (req: Request, res: Response, next: NextFunction) => {
+    try {
+      const plan = await currentPlan(req.userId!);
+      if (!plan) {
+        return res.status(%[1]d).json({ error: `+"`Subscribe to a plan to ${action} ${model}`"+` });
+      }
+      const policy = policies[planPolicies[plan]];
+      if (!policy) {
+        return next();
+      }
+      const denied = policy.restrictions.find(r => r.action === action && (r.model === model || r.model === '*'));
+      if (denied) {
+        return res.status(%[1]d).json({ error: `+"`The ${plan} plan can't ${action} ${model}`"+` });
+      }
+      const limited = policy.permissions.find(r => r.action === action && r.model === model && r.limit);
+      const count = counts[model];
+      if (limited && count && (await count(req.userId!, periodStart(limited.period))) >= limited.limit!) {
+        const per = limited.period ? `+"` per ${limited.period}`"+` : '';
+        return res.status(%[1]d).json({ error: `+"`The ${plan} plan's limit is ${limited.limit}${per}: upgrade to ${action} another ${model}`"+` });
+      }
+      next();
+    } catch (error) {
+      next(error);
+    }
+  };
+}
+`, ir.PlanUpgradeStatus)
+	return b.String()
+}
+
+// generatePlanSync produces src/scripts/sync-plans.ts, run with
+// `npm run sync-plans`: it creates each paid plan's Stripe price and stores
+// the plans with their prices, which checkout and the webhook look up.
+func generatePlanSync(app *ir.Application) string {
+	var b strings.Builder
+	bill := app.Billing
+	b.WriteString(`// Generated by Human compiler — do not edit
+//
+// Creates each paid plan's Stripe product and price, and stores the plans
+// with their prices. Run after changing the plans block:
+//   npm run sync-plans
+// Stripe prices can't change, so a new price is created under a new lookup
+// key; existing subscribers stay on the old one until they change plan.
+
+import { PrismaClient } from '@prisma/client';
+import { stripe } from '../services/billing';
+
+const prisma = new PrismaClient();
+
+const PLANS: { name: string; price: number; interval: 'month' | 'year' | null }[] = [
+`)
+	for _, plan := range bill.Plans {
+		interval := "null"
+		if plan.Paid() {
+			interval = "'" + plan.Interval + "'"
+		}
+		fmt.Fprintf(&b, "  { name: '%s', price: %d, interval: %s },\n", plan.Name, plan.Price, interval)
+	}
+	fmt.Fprintf(&b, `];
+
+async function main() {
+  for (const [position, plan] of PLANS.entries()) {
+    let stripePriceId: string | null = null;
+    if (plan.price > 0 && plan.interval) {
+      const lookupKey = `+"`${plan.name.toLowerCase().replace(/\\W+/g, '_')}_${plan.price}_${plan.interval}`"+`;
+      const existing = await stripe.prices.list({ lookup_keys: [lookupKey], limit: 1 });
+      const price =
+        existing.data[0] ??
+        (await stripe.prices.create({
+          currency: '%s',
+          unit_amount: plan.price,
+          recurring: { interval: plan.interval },
+          lookup_key: lookupKey,
+          product_data: { name: plan.name },
+        }));
+      stripePriceId = price.id;
+    }
+    const data = { price: plan.price, interval: plan.interval, position, stripe_price_id: stripePriceId };
+    await prisma.plan.upsert({ where: { name: plan.name }, update: data, create: { name: plan.name, ...data } });
+    console.log(`+"`${plan.name}: ${stripePriceId ?? 'not sold online'}`"+`);
+  }
+}
+
+main()
+  .catch((err) => {
+    console.error(err);
+    process.exitCode = 1;
+  })
+  .finally(() => prisma.$disconnect());
+`, bill.Currency)
+	return b.String()
+}
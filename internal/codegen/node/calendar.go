@@ -0,0 +1,226 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateCalendarService produces src/services/calendar.ts: an iCalendar
+// (RFC 5545) writer. Times are written in UTC, all-day events as dates,
+// and text is escaped and folded at 75 octets.
+func generateCalendarService(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	fmt.Fprintf(&b, "const PRODID = '-//Human//%s//EN';\n\n", escapeQuote(app.Name))
+
+	b.WriteString(`export interface CalendarEvent {
+  uid: string;
+  title: string;
+  start: Date;
+  end?: Date;
+  allDay?: boolean;
+  location?: string | null;
+  description?: string | null;
+}
+
+// Escapes a TEXT value: backslashes, semicolons, commas, and newlines.
+export function escapeText(value: string): string {
+  return value
+    .replace(/\\/g, '\\\\')
+    .replace(/;/g, '\\;')
+    .replace(/,/g, '\\,')
+    .replace(/\r?\n/g, '\\n');
+}
+
+// Folds a content line so no line exceeds 75 octets; continuation lines
+// start with a space.
+export function fold(line: string): string {
+  const lines: string[] = [];
+  let current = '';
+  let size = 0;
+  for (const ch of line) {
+    const n = Buffer.byteLength(ch, 'utf8');
+    if (size + n > 75) {
+      lines.push(current);
+      current = ' ';
+      size = 1;
+    }
+    current += ch;
+    size += n;
+  }
+  lines.push(current);
+  return lines.join('\r\n');
+}
+
+// 2026-03-01T18:30:00.000Z → 20260301T183000Z
+export function formatDateTime(date: Date): string {
+  return date.toISOString().replace(/[-:]/g, '').replace(/\.\d{3}/, '');
+}
+
+// 2026-03-01T00:00:00.000Z → 20260301
+export function formatDate(date: Date): string {
+  return date.toISOString().slice(0, 10).replace(/-/g, '');
+}
+
+const HOUR_MS = 60 * 60 * 1000;
+const DAY_MS = 24 * HOUR_MS;
+
+function eventLines(event: CalendarEvent, stamp: string): string[] {
+  const lines = ['BEGIN:VEVENT', ` + "`UID:${event.uid}`" + `, ` + "`DTSTAMP:${stamp}`" + `];
+  if (event.allDay) {
+    // DTEND is exclusive, so a one-day event ends the next day.
+    const end = event.end ? new Date(event.end.getTime() + DAY_MS) : new Date(event.start.getTime() + DAY_MS);
+    lines.push(` + "`DTSTART;VALUE=DATE:${formatDate(event.start)}`" + `, ` + "`DTEND;VALUE=DATE:${formatDate(end)}`" + `);
+  } else {
+    const end = event.end ?? new Date(event.start.getTime() + HOUR_MS);
+    lines.push(` + "`DTSTART:${formatDateTime(event.start)}`" + `, ` + "`DTEND:${formatDateTime(end)}`" + `);
+  }
+  lines.push(` + "`SUMMARY:${escapeText(event.title)}`" + `);
+  if (event.location) lines.push(` + "`LOCATION:${escapeText(event.location)}`" + `);
+  if (event.description) lines.push(` + "`DESCRIPTION:${escapeText(event.description)}`" + `);
+  lines.push('END:VEVENT');
+  return lines;
+}
+
+// Renders events as an iCalendar document with CRLF line endings.
+export function toICS(name: string, timezone: string, events: CalendarEvent[]): string {
+  const stamp = formatDateTime(new Date());
+  const lines = [
+    'BEGIN:VCALENDAR',
+    'VERSION:2.0',
+    ` + "`PRODID:${PRODID}`" + `,
+    'CALSCALE:GREGORIAN',
+    'METHOD:PUBLISH',
+    ` + "`X-WR-CALNAME:${escapeText(name)}`" + `,
+    ` + "`X-WR-TIMEZONE:${timezone}`" + `,
+    ...events.flatMap((event) => eventLines(event, stamp)),
+    'END:VCALENDAR',
+  ];
+  return lines.map(fold).join('\r\n') + '\r\n';
+}
+`)
+
+	return b.String()
+}
+
+// generateCalendarTests produces src/__tests__/calendar.test.ts, which
+// checks the iCalendar writer's output format.
+func generateCalendarTests() string {
+	return `// Generated by Human compiler — do not edit
+
+import { escapeText, fold, toICS } from '../services/calendar';
+
+describe('calendar service', () => {
+  const start = new Date('2026-03-01T18:30:00Z');
+
+  it('writes timed events in UTC, an hour long by default', () => {
+    const ics = toICS('Events', 'UTC', [{ uid: 'event-1@test', title: 'Launch', start }]);
+    expect(ics).toContain('DTSTART:20260301T183000Z\r\n');
+    expect(ics).toContain('DTEND:20260301T193000Z\r\n');
+    expect(ics.startsWith('BEGIN:VCALENDAR\r\n')).toBe(true);
+    expect(ics.endsWith('END:VCALENDAR\r\n')).toBe(true);
+  });
+
+  it('writes all-day events as dates with an exclusive end', () => {
+    const ics = toICS('Events', 'UTC', [{ uid: 'event-1@test', title: 'Holiday', start: new Date('2026-03-01'), allDay: true }]);
+    expect(ics).toContain('DTSTART;VALUE=DATE:20260301\r\n');
+    expect(ics).toContain('DTEND;VALUE=DATE:20260302\r\n');
+  });
+
+  it('escapes text values', () => {
+    expect(escapeText('a, b; c\\d\ne')).toBe('a\\, b\\; c\\\\d\\ne');
+  });
+
+  it('folds long lines at 75 octets', () => {
+    const lines = fold('DESCRIPTION:' + 'x'.repeat(200)).split('\r\n');
+    expect(lines.length).toBeGreaterThan(1);
+    lines.forEach((line) => expect(Buffer.byteLength(line)).toBeLessThanOrEqual(75));
+    lines.slice(1).forEach((line) => expect(line.startsWith(' ')).toBe(true));
+  });
+});
+`
+}
+
+// generateCalendarRoutes produces src/routes/calendar.ts: for each calendar,
+// an ICS feed of every record (GET /api/calendar/events.ics) and a single
+// record's .ics download (GET /api/calendar/events/:id.ics). The feeds are
+// public so calendar apps can subscribe to them.
+func generateCalendarRoutes(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Router, Request, Response, NextFunction } from 'express';\n")
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	b.WriteString("import { CalendarEvent, toICS } from '../services/calendar';\n")
+	b.WriteString(prismaImport(app, "../services"))
+	fmt.Fprintf(&b, "\nconst prisma = %s;\n", newPrismaClient(app))
+	b.WriteString("const router = Router();\n\n")
+
+	b.WriteString(`function sendICS(res: Response, ics: string, filename?: string) {
+  res.type('text/calendar; charset=utf-8');
+  if (filename) {
+    res.setHeader('Content-Disposition', ` + "`attachment; filename=\"${filename}\"`" + `);
+  }
+  res.send(ics);
+}
+`)
+
+	for _, cal := range app.Calendars {
+		model := toCamelCase(cal.Model)
+		fn := model + "CalendarEvent"
+		name := cal.Name()
+
+		fmt.Fprintf(&b, "\nfunction %s(record: any): CalendarEvent {\n", fn)
+		b.WriteString("  return {\n")
+		fmt.Fprintf(&b, "    uid: `%s-${record.id}@%s`,\n", strings.ToLower(cal.Model), strings.ToLower(strings.ReplaceAll(app.Name, " ", "-")))
+		if cal.Title != "" {
+			fmt.Fprintf(&b, "    title: String(record.%s ?? '%s'),\n", cal.Title, escapeQuote(cal.Model))
+		} else {
+			fmt.Fprintf(&b, "    title: '%s',\n", escapeQuote(cal.Model))
+		}
+		fmt.Fprintf(&b, "    start: new Date(record.%s),\n", cal.Start)
+		if cal.End != "" {
+			fmt.Fprintf(&b, "    end: record.%s ? new Date(record.%s) : undefined,\n", cal.End, cal.End)
+		}
+		if cal.AllDay {
+			b.WriteString("    allDay: true,\n")
+		}
+		if cal.Location != "" {
+			fmt.Fprintf(&b, "    location: record.%s,\n", cal.Location)
+		}
+		if cal.Description != "" {
+			fmt.Fprintf(&b, "    description: record.%s,\n", cal.Description)
+		}
+		b.WriteString("  };\n")
+		b.WriteString("}\n")
+
+		fmt.Fprintf(&b, "\nrouter.get('/%s.ics', async (_req: Request, res: Response, next: NextFunction) => {\n", cal.Slug)
+		b.WriteString("  try {\n")
+		fmt.Fprintf(&b, "    const records = await prisma.%s.findMany();\n", model)
+		fmt.Fprintf(&b, "    sendICS(res, toICS('%s', '%s', records.filter((r: any) => r.%s).map(%s)));\n", escapeQuote(name), cal.Timezone, cal.Start, fn)
+		b.WriteString("  } catch (error) {\n")
+		b.WriteString("    next(error);\n")
+		b.WriteString("  }\n")
+		b.WriteString("});\n")
+
+		fmt.Fprintf(&b, "\nrouter.get('/%s/:id.ics', async (req: Request, res: Response, next: NextFunction) => {\n", cal.Slug)
+		b.WriteString("  try {\n")
+		fmt.Fprintf(&b, "    const record = await prisma.%s.findUnique({ where: { id: req.params.id } });\n", model)
+		fmt.Fprintf(&b, "    if (!record || !record.%s) {\n", cal.Start)
+		fmt.Fprintf(&b, "      res.status(404).json({ error: '%s not found' });\n", escapeQuote(cal.Model))
+		b.WriteString("      return;\n")
+		b.WriteString("    }\n")
+		fmt.Fprintf(&b, "    sendICS(res, toICS('%s', '%s', [%s(record)]), `%s-${record.id}.ics`);\n",
+			escapeQuote(name), cal.Timezone, fn, strings.ToLower(cal.Model))
+		b.WriteString("  } catch (error) {\n")
+		b.WriteString("    next(error);\n")
+		b.WriteString("  }\n")
+		b.WriteString("});\n")
+	}
+
+	b.WriteString("\nexport { router };\n")
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateChaos produces src/middleware/chaos.ts: with chaos mode on
+// (`human run --chaos`), some API requests fail the way the error handlers
+// are declared for, so their retries, fallbacks, and alerts can be watched
+// at work. It does nothing otherwise.
+func generateChaos(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Request, Response, NextFunction } from 'express';\n\n")
+	fmt.Fprintf(&b, "const ENABLED = ['1', 'true'].includes(process.env.%s ?? '');\n", ir.ChaosEnv)
+	fmt.Fprintf(&b, "const RATE = Number(process.env.%s ?? 0.1);\n", ir.ChaosRateEnv)
+	fmt.Fprintf(&b, "const MAX_LATENCY_MS = Number(process.env.%s ?? 2000);\n\n", ir.ChaosLatencyEnv)
+
+	b.WriteString("// The failure each error handler in the .human file is declared for\n")
+	b.WriteString("const faults: Array<{\n")
+	b.WriteString("  condition: string;\n")
+	b.WriteString("  attempts: number;\n")
+	b.WriteString("  delayMs: number;\n")
+	b.WriteString("  status: number;\n")
+	b.WriteString("  message: string;\n")
+	b.WriteString("  alert?: string;\n")
+	b.WriteString("}> = [\n")
+	for _, f := range ir.ChaosFaults(app) {
+		b.WriteString("  {\n")
+		fmt.Fprintf(&b, "    condition: '%s',\n", escapeQuote(f.Condition))
+		fmt.Fprintf(&b, "    attempts: %d,\n", f.Attempts)
+		fmt.Fprintf(&b, "    delayMs: %d,\n", f.DelayMs)
+		fmt.Fprintf(&b, "    status: %d,\n", f.Status)
+		fmt.Fprintf(&b, "    message: '%s',\n", escapeQuote(f.Message))
+		if f.Alert != "" {
+			fmt.Fprintf(&b, "    alert: '%s',\n", escapeQuote(f.Alert))
+		}
+		b.WriteString("  },\n")
+	}
+	b.WriteString("];\n\n")
+
+	b.WriteString(`function sleep(ms: number): Promise<void> {
+  return new Promise(resolve => setTimeout(resolve, ms));
+}
+
+if (ENABLED) {
+  console.warn(` + "`[chaos] Injecting failures into ${Math.round(RATE * 100)}% of API requests`" + `);
+}
+
+/**
+ * Chaos middleware — off unless chaos mode is on.
+ *
+ * Behavior, for the share of API requests it hits:
+ *   1. Up to MAX_LATENCY_MS of latency is added
+ *   2. One error handler's failure happens some attempts in a row,
+ *      each attempt after the handler's retry delay
+ *   3. If an attempt within the handler's attempts succeeds, the request
+ *      goes through; otherwise it's answered with the handler's response,
+ *      and its alert is logged
+ * Responses chaos mode answered carry an X-Chaos header naming the failure.
+ */
+export async function chaos(req: Request, res: Response, next: NextFunction) {
+  if (!ENABLED || Math.random() >= RATE) {
+    return next();
+  }
+  const fault = faults[Math.floor(Math.random() * faults.length)];
+  const failures = 1 + Math.floor(Math.random() * fault.attempts);
+  await sleep(Math.random() * MAX_LATENCY_MS + Math.min(failures, fault.attempts - 1) * fault.delayMs);
+
+  const request = ` + "`${req.method} ${req.originalUrl}`" + `;
+  if (failures < fault.attempts) {
+    console.warn(` + "`[chaos] ${request}: ${fault.condition}, recovered on attempt ${failures + 1} of ${fault.attempts}`" + `);
+    return next();
+  }
+  console.warn(` + "`[chaos] ${request}: ${fault.condition}, failed ${fault.attempts} of ${fault.attempts} attempts → ${fault.status}`" + `);
+  if (fault.alert) {
+    console.warn(` + "`[chaos] ${fault.alert}`" + `);
+  }
+  res.set('X-Chaos', fault.condition);
+  return res.status(fault.status).json({ error: fault.message });
+}
+`)
+	return b.String()
+}
@@ -0,0 +1,343 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateAggregateHelpers produces src/services/aggregates.ts, shared by
+// chart routes and report endpoints. MongoDB has no SQL to bucket dates
+// in, so with it time series are bucketed here.
+func generateAggregateHelpers(app *ir.Application) string {
+	helpers := `// Generated by Human compiler — do not edit
+
+// AggregateRow is one group of a chart or report: its label and the count,
+// sum, average, minimum, or maximum of its records.
+export interface AggregateRow {
+  label: string;
+  value: number;
+}
+
+const DAY_MS = 24 * 60 * 60 * 1000;
+
+// The start of the last n days.
+export function since(days: number): Date {
+  return new Date(Date.now() - days * DAY_MS);
+}
+
+// Largest groups first.
+export function byValue(a: AggregateRow, b: AggregateRow): number {
+  return b.value - a.value;
+}
+`
+	if prismaProvider(app) != "mongodb" {
+		return helpers
+	}
+	return helpers + `
+// Groups records by the UTC day, week (starting on Monday), month, or year
+// of their date, oldest first, labelled YYYY-MM-DD, YYYY-MM, or YYYY.
+// Records without a date are left out, and so are null values, except
+// from counts.
+export function byPeriod(
+  records: { date: Date | null; value: number | null }[],
+  period: 'day' | 'week' | 'month' | 'year',
+  fn: 'count' | 'sum' | 'avg' | 'min' | 'max',
+): AggregateRow[] {
+  const groups = new Map<string, { count: number; values: number[] }>();
+  for (const record of records) {
+    if (record.date === null) continue;
+    const date = new Date(record.date);
+    if (period === 'week') {
+      date.setUTCDate(date.getUTCDate() - ((date.getUTCDay() + 6) % 7));
+    }
+    const label = date.toISOString().slice(0, { day: 10, week: 10, month: 7, year: 4 }[period]);
+    const group = groups.get(label) ?? { count: 0, values: [] };
+    group.count++;
+    if (record.value !== null) {
+      group.values.push(record.value);
+    }
+    groups.set(label, group);
+  }
+  return [...groups.entries()]
+    .sort(([a], [b]) => a.localeCompare(b))
+    .map(([label, { count, values }]) => ({ label, value: reduce(fn, count, values) }));
+}
+
+function reduce(fn: 'count' | 'sum' | 'avg' | 'min' | 'max', count: number, values: number[]): number {
+  if (fn === 'count') return count;
+  if (values.length === 0) return 0;
+  const sum = values.reduce((total, v) => total + v, 0);
+  switch (fn) {
+    case 'sum':
+      return sum;
+    case 'avg':
+      return sum / values.length;
+    case 'min':
+      return Math.min(...values);
+    default:
+      return Math.max(...values);
+  }
+}
+`
+}
+
+// aggregatesImport imports the aggregate helpers a chart route or report
+// endpoint uses.
+func aggregatesImport(app *ir.Application) string {
+	if prismaProvider(app) == "mongodb" {
+		return "import { AggregateRow, byPeriod, byValue, since } from '../services/aggregates';\n"
+	}
+	return "import { AggregateRow, byValue, since } from '../services/aggregates';\n"
+}
+
+// hasAggregates reports whether any chart or report endpoint needs the
+// shared aggregate helpers.
+func hasAggregates(app *ir.Application) bool {
+	if len(app.Charts) > 0 {
+		return true
+	}
+	for _, ep := range app.APIs {
+		if ep.Aggregate != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// generateChartRoutes produces src/routes/charts.ts: for each chart, a GET
+// /api/charts/<slug> returning { data: [{ label, value }] }. Points for a
+// model that belongs to a user are limited to the signed-in user's records.
+func generateChartRoutes(app *ir.Application) string {
+	var b strings.Builder
+	auth := app.Auth != nil
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Router, Request, Response, NextFunction } from 'express';\n")
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	if auth {
+		b.WriteString("import { authenticate } from '../middleware/auth';\n")
+	}
+	b.WriteString(aggregatesImport(app))
+	b.WriteString(prismaImport(app, "../services"))
+	fmt.Fprintf(&b, "\nconst prisma = %s;\n", newPrismaClient(app))
+	b.WriteString("const router = Router();\n")
+	if auth {
+		b.WriteString("\nrouter.use(authenticate);\n")
+	}
+
+	engine := prismaProvider(app)
+	for _, c := range app.Charts {
+		scoped := auth && modelBelongsToUser(c.Model, app) && !strings.EqualFold(c.Model, "User")
+		fmt.Fprintf(&b, "\n// %s\n", c.Title)
+		fmt.Fprintf(&b, "router.get('/%s', async (%s: Request, res: Response, next: NextFunction) => {\n", c.Slug, chartReq(scoped))
+		b.WriteString("  try {\n")
+		writeAggregateQuery(&b, &c.Aggregate, engine, scoped)
+		b.WriteString("    res.json({ data });\n")
+		b.WriteString("  } catch (error) {\n")
+		b.WriteString("    next(error);\n")
+		b.WriteString("  }\n")
+		b.WriteString("});\n")
+	}
+
+	b.WriteString("\nexport { router };\n")
+	return b.String()
+}
+
+// chartReq names the request parameter, unused unless the handler reads it.
+func chartReq(used bool) string {
+	if used {
+		return "req"
+	}
+	return "_req"
+}
+
+// writeReportBody emits a report endpoint's handler body: a cache hint and
+// its aggregate's rows. Rows of a model that belongs to a user are limited
+// to the signed-in user's records.
+func writeReportBody(b *strings.Builder, ep *ir.Endpoint, app *ir.Application) {
+	agg := ep.Aggregate
+	scoped := ep.Auth && modelBelongsToUser(agg.Model, app) && !strings.EqualFold(agg.Model, "User")
+	fmt.Fprintf(b, "    res.set('Cache-Control', '%s');\n", cacheControl(ep))
+	writeAggregateQuery(b, agg, prismaProvider(app), scoped)
+	b.WriteString("    res.json({ data });\n")
+}
+
+// cacheControl returns the Cache-Control header of a report endpoint:
+// private when the rows depend on who asks.
+func cacheControl(ep *ir.Endpoint) string {
+	if ep.Auth {
+		return fmt.Sprintf("private, max-age=%d", ep.Cache)
+	}
+	return fmt.Sprintf("public, max-age=%d", ep.Cache)
+}
+
+// writeAggregateQuery emits the query computing an aggregate's rows into
+// data. Field and relation groupings use Prisma's groupBy; time series
+// GROUP BY a bucket of the date in SQL, as the engine spells it, or with
+// MongoDB are bucketed by byPeriod.
+func writeAggregateQuery(b *strings.Builder, agg *ir.Aggregate, engine string, scoped bool) {
+	if agg.Period != "" {
+		writeTimeSeries(b, agg, engine, scoped)
+	} else {
+		writeGroupBy(b, agg, scoped)
+	}
+}
+
+// writeGroupBy emits an aggregate grouped by a field or by the record the
+// model belongs to, whose label field names each group.
+func writeGroupBy(b *strings.Builder, agg *ir.Aggregate, scoped bool) {
+	model := toCamelCase(agg.Model)
+	by := agg.GroupBy
+	if agg.Relation != "" {
+		by = toCamelCase(agg.Relation) + "Id"
+	}
+	var where []string
+	if scoped {
+		where = append(where, "userId: req.userId!")
+	}
+	if agg.Days > 0 {
+		date := "createdAt"
+		if agg.Date != "" {
+			date = agg.Date
+		}
+		where = append(where, fmt.Sprintf("%s: { gte: since(%d) }", date, agg.Days))
+	}
+	aggregate, value := "_count: { _all: true }", "g._count._all"
+	if agg.Func != "count" {
+		aggregate = fmt.Sprintf("_%s: { %s: true }", agg.Func, agg.Field)
+		value = fmt.Sprintf("Number(g._%s.%s ?? 0)", agg.Func, agg.Field)
+	}
+
+	fmt.Fprintf(b, "    const groups = await prisma.%s.groupBy({\n", model)
+	fmt.Fprintf(b, "      by: ['%s'],\n", by)
+	if len(where) > 0 {
+		fmt.Fprintf(b, "      where: { %s },\n", strings.Join(where, ", "))
+	}
+	fmt.Fprintf(b, "      %s,\n", aggregate)
+	b.WriteString("    });\n")
+
+	label := fmt.Sprintf("String(g.%s ?? 'None')", by)
+	if agg.Relation != "" && agg.Label != "" {
+		fmt.Fprintf(b, "    const related = await prisma.%s.findMany({\n", toCamelCase(agg.Relation))
+		fmt.Fprintf(b, "      where: { id: { in: groups.map((g) => g.%s) } },\n", by)
+		fmt.Fprintf(b, "      select: { id: true, %s: true },\n", agg.Label)
+		b.WriteString("    });\n")
+		fmt.Fprintf(b, "    const labels = new Map(related.map((r) => [r.id, String(r.%s)]));\n", agg.Label)
+		label = fmt.Sprintf("labels.get(g.%s) ?? g.%s", by, by)
+	}
+	b.WriteString("    const data: AggregateRow[] = groups\n")
+	fmt.Fprintf(b, "      .map((g) => ({ label: %s, value: %s }))\n", label, value)
+	b.WriteString("      .sort(byValue);\n")
+}
+
+// writeTimeSeries emits an aggregate per day, week, month, or year,
+// oldest first.
+func writeTimeSeries(b *strings.Builder, agg *ir.Aggregate, engine string, scoped bool) {
+	if engine == "mongodb" {
+		writeBucketedTimeSeries(b, agg, scoped)
+		return
+	}
+	quote := func(name string) string {
+		if engine == "mysql" {
+			return "\\`" + name + "\\`" // escaped inside the template literal
+		}
+		return `"` + name + `"`
+	}
+	date := quote("createdAt")
+	if agg.Date != "" {
+		date = quote(agg.Date)
+	}
+	value := "COUNT(*)"
+	if agg.Func != "count" {
+		value = "COALESCE(" + strings.ToUpper(agg.Func) + "(" + quote(agg.Field) + "), 0)"
+	}
+	var where []string
+	if agg.Days > 0 {
+		where = append(where, fmt.Sprintf("%s >= ${since(%d)}", date, agg.Days))
+	}
+	if scoped {
+		where = append(where, quote("userId")+" = ${req.userId}")
+	}
+
+	b.WriteString("    const rows = await prisma.$queryRaw<{ label: string; value: number | bigint }[]>`\n")
+	fmt.Fprintf(b, "      SELECT %s AS label, %s AS value\n", dateBucket(engine, agg.Period, date), value)
+	fmt.Fprintf(b, "      FROM %s\n", quote(agg.Model))
+	if len(where) > 0 {
+		fmt.Fprintf(b, "      WHERE %s\n", strings.Join(where, " AND "))
+	}
+	b.WriteString("      GROUP BY 1\n")
+	b.WriteString("      ORDER BY 1`;\n")
+	b.WriteString("    const data: AggregateRow[] = rows.map((r) => ({ label: r.label, value: Number(r.value) }));\n")
+}
+
+// writeBucketedTimeSeries emits a time series for MongoDB: the records'
+// dates and values, bucketed by byPeriod.
+func writeBucketedTimeSeries(b *strings.Builder, agg *ir.Aggregate, scoped bool) {
+	date := "createdAt"
+	if agg.Date != "" {
+		date = agg.Date
+	}
+	var where []string
+	if agg.Days > 0 {
+		where = append(where, fmt.Sprintf("%s: { gte: since(%d) }", date, agg.Days))
+	}
+	if scoped {
+		where = append(where, "userId: req.userId!")
+	}
+	selected := date + ": true"
+	value := "1"
+	if agg.Func != "count" {
+		selected += ", " + agg.Field + ": true"
+		value = "r." + agg.Field
+	}
+
+	fmt.Fprintf(b, "    const records = await prisma.%s.findMany({\n", toCamelCase(agg.Model))
+	if len(where) > 0 {
+		fmt.Fprintf(b, "      where: { %s },\n", strings.Join(where, ", "))
+	}
+	fmt.Fprintf(b, "      select: { %s },\n", selected)
+	b.WriteString("    });\n")
+	fmt.Fprintf(b, "    const data: AggregateRow[] = byPeriod(records.map((r) => ({ date: r.%s, value: %s })), '%s', '%s');\n", date, value, agg.Period, agg.Func)
+}
+
+// dateBucket returns the SQL labelling a date with the start of its
+// period: YYYY-MM-DD for days and weeks (which start on Monday), YYYY-MM
+// for months, and YYYY for years. Prisma keeps SQLite dates as
+// milliseconds since the epoch.
+func dateBucket(engine, period, col string) string {
+	format := map[string]string{"day": "%Y-%m-%d", "week": "%Y-%m-%d", "month": "%Y-%m", "year": "%Y"}[period]
+	switch engine {
+	case "mysql":
+		if period == "week" {
+			return "DATE_FORMAT(DATE_SUB(" + col + ", INTERVAL WEEKDAY(" + col + ") DAY), '" + format + "')"
+		}
+		return "DATE_FORMAT(" + col + ", '" + format + "')"
+	case "sqlite":
+		if period == "week" {
+			return "date(" + col + " / 1000, 'unixepoch', 'weekday 0', '-6 days')"
+		}
+		return "strftime('" + format + "', " + col + " / 1000, 'unixepoch')"
+	}
+	pattern := map[string]string{"day": "YYYY-MM-DD", "week": "YYYY-MM-DD", "month": "YYYY-MM", "year": "YYYY"}[period]
+	return "to_char(date_trunc('" + period + "', " + col + "), '" + pattern + "')"
+}
+
+// prismaProvider returns the database provider the Prisma schema
+// declares: "postgresql", "mysql", "sqlite", or "mongodb".
+func prismaProvider(app *ir.Application) string {
+	if ir.UsesMongoDB(app) {
+		return "mongodb"
+	}
+	if app.Database != nil {
+		engine := strings.ToLower(app.Database.Engine)
+		switch {
+		case strings.Contains(engine, "mysql"):
+			return "mysql"
+		case strings.Contains(engine, "sqlite"):
+			return "sqlite"
+		}
+	}
+	return "postgresql"
+}
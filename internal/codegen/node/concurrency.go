@@ -0,0 +1,54 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateConcurrencyHelpers produces src/services/concurrency.ts, reading
+// the version of a record an update was made to.
+func generateConcurrencyHelpers() string {
+	return `// Generated by Human compiler — do not edit
+
+import { Request } from 'express';
+
+// expectedVersion returns the version of the record an update was made to:
+// the If-Match header's ("3" or W/"3"), else the body's version field.
+export function expectedVersion(req: Request): number | undefined {
+  const header = req.get('If-Match');
+  const raw = header !== undefined ? header.replace(/^W\//, '').replace(/"/g, '') : req.body?.version;
+  const version = Number(raw);
+  return Number.isInteger(version) && version > 0 ? version : undefined;
+}
+`
+}
+
+// writeVersionedUpdate writes the update of a record protected against
+// conflicting edits. It only matches the version the client edited, and
+// bumps it; when nothing matched, someone else saved first, and the
+// client gets 409 Conflict with the record as it is now.
+func writeVersionedUpdate(b *strings.Builder, varName, idParam string, ep *ir.Endpoint, m *ir.DataModel) {
+	modelCamel := toCamelCase(m.Name)
+	label := strings.ToLower(ir.FieldLabel(m.Name))
+	b.WriteString("    const expected = expectedVersion(req);\n")
+	b.WriteString("    if (expected === undefined) {\n")
+	fmt.Fprintf(b, "      return res.status(428).json({ error: 'Send the version of the %s you edited in an If-Match header' });\n", label)
+	b.WriteString("    }\n")
+	fmt.Fprintf(b, "    const { count } = await prisma.%s.updateMany({\n", modelCamel)
+	fmt.Fprintf(b, "      where: { id: %s, %s: expected },\n", idParam, ir.VersionColumn)
+	b.WriteString("      data: {\n")
+	writeUpdateFields(b, ep, m)
+	fmt.Fprintf(b, "        %s: { increment: 1 },\n", ir.VersionColumn)
+	b.WriteString("      },\n")
+	b.WriteString("    });\n")
+	b.WriteString("    if (count === 0) {\n")
+	fmt.Fprintf(b, "      const current = await prisma.%s.findUnique({ where: { id: %s } });\n", modelCamel, idParam)
+	b.WriteString("      if (!current) {\n")
+	fmt.Fprintf(b, "        return res.status(404).json({ error: '%s not found' });\n", m.Name)
+	b.WriteString("      }\n")
+	fmt.Fprintf(b, "      return res.status(409).json({ error: 'This %s was changed by someone else', current });\n", label)
+	b.WriteString("    }\n")
+	fmt.Fprintf(b, "    %s = await prisma.%s.findUnique({ where: { id: %s } });\n\n", varName, modelCamel, idParam)
+}
@@ -0,0 +1,133 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// sharesPrismaClient reports whether routes share one Prisma client from
+// src/services/database.ts: with a read replica or a pool size, one client
+// per process keeps the connection count to what the database block says.
+func sharesPrismaClient(app *ir.Application) bool {
+	return app.Database != nil && (app.Database.ReadReplica || app.Database.PoolSize > 0)
+}
+
+// prismaImport returns the imports newPrismaClient's expression needs in a
+// file whose path to src/services is servicesDir ("." or "../services"),
+// or "" when it needs none beyond @prisma/client.
+func prismaImport(app *ir.Application, servicesDir string) string {
+	if sharesPrismaClient(app) {
+		return fmt.Sprintf("import { getPrismaClient } from '%s/database';\n", servicesDir)
+	}
+	imports := ""
+	if ir.UsesFieldEncryption(app) {
+		imports += fmt.Sprintf("import { withFieldEncryption } from '%s/encryption';\n", servicesDir)
+	}
+	if ir.HasModelHooks(app) {
+		imports += fmt.Sprintf("import { modelHooks } from '%s/hooks';\n", servicesDir)
+	}
+	return imports
+}
+
+// newPrismaClient returns the expression that creates the Prisma client.
+// With encrypted fields it is wrapped so they are encrypted on every write
+// and decrypted on every read, and with model hooks extended to run them.
+func newPrismaClient(app *ir.Application) string {
+	if sharesPrismaClient(app) {
+		return "getPrismaClient()"
+	}
+	client := "new PrismaClient()"
+	if ir.UsesFieldEncryption(app) {
+		client = "withFieldEncryption(" + client + ")"
+	}
+	if ir.HasModelHooks(app) {
+		client += ".$extends(modelHooks)"
+	}
+	return client
+}
+
+// generateDatabaseClient produces src/services/database.ts: the Prisma
+// client shared by every route, capped at the database block's pool size
+// and, with a read replica, sending reads to DATABASE_REPLICA_URL through
+// the read replicas extension.
+func generateDatabaseClient(app *ir.Application) string {
+	db := app.Database
+	encrypted := ir.UsesFieldEncryption(app)
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n")
+	b.WriteString("//\n")
+	b.WriteString("// The Prisma client shared by every route, so each process keeps one\n")
+	b.WriteString("// connection pool")
+	if db.PoolSize > 0 {
+		fmt.Fprintf(&b, " of at most %d connections", db.PoolSize)
+	}
+	b.WriteString(".\n")
+	if db.ReadReplica {
+		b.WriteString("//\n")
+		b.WriteString("// Reads outside a transaction (findMany, findUnique, count, ...) go to the\n")
+		b.WriteString("// read replica at DATABASE_REPLICA_URL, or the primary when it is unset.\n")
+		b.WriteString("// Writes and transactions go to the primary; use prisma.$primary() for a\n")
+		b.WriteString("// read that must see a write made just before it.\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	if db.ReadReplica {
+		b.WriteString("import { readReplicas } from '@prisma/extension-read-replicas';\n")
+	}
+	if encrypted {
+		b.WriteString("import { withFieldEncryption } from './encryption';\n")
+	}
+	if ir.HasModelHooks(app) {
+		b.WriteString("import { modelHooks } from './hooks';\n")
+	}
+	b.WriteString("\n")
+
+	if db.PoolSize > 0 {
+		fmt.Fprintf(&b, "const POOL_SIZE = %d;\n\n", db.PoolSize)
+		b.WriteString("// withPoolSize caps a connection string's pool at POOL_SIZE connections.\n")
+		b.WriteString("function withPoolSize(url: string): string {\n")
+		b.WriteString("  const parsed = new URL(url);\n")
+		b.WriteString("  parsed.searchParams.set('connection_limit', String(POOL_SIZE));\n")
+		b.WriteString("  return parsed.toString();\n")
+		b.WriteString("}\n\n")
+	}
+
+	datasource := "url"
+	if db.PoolSize > 0 {
+		datasource = "withPoolSize(url)"
+	}
+	client := fmt.Sprintf("new PrismaClient({ datasourceUrl: %s })", datasource)
+	if encrypted {
+		client = "withFieldEncryption(" + client + ")"
+	}
+	if ir.HasModelHooks(app) {
+		client += ".$extends(modelHooks)"
+	}
+	b.WriteString("function connect(url: string) {\n")
+	fmt.Fprintf(&b, "  return %s;\n", client)
+	b.WriteString("}\n\n")
+
+	b.WriteString("function createClient() {\n")
+	b.WriteString("  const url = process.env.DATABASE_URL!;\n")
+	if db.ReadReplica {
+		b.WriteString("  const replicaUrl = process.env.DATABASE_REPLICA_URL || url;\n")
+		b.WriteString("  return connect(url).$extends(readReplicas({ replicas: [connect(replicaUrl)] }));\n")
+	} else {
+		b.WriteString("  return connect(url);\n")
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("let client: ReturnType<typeof createClient> | undefined;\n\n")
+	b.WriteString("// getPrismaClient returns the process's Prisma client, creating it on\n")
+	b.WriteString("// first use.\n")
+	b.WriteString("export function getPrismaClient() {\n")
+	b.WriteString("  client ??= createClient();\n")
+	b.WriteString("  return client;\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
@@ -0,0 +1,331 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// documentFunctions returns the documents service functions an endpoint's
+// "generate a PDF ..." steps call.
+func documentFunctions(ep *ir.Endpoint, app *ir.Application) []string {
+	if !hasStorageIntegration(app) {
+		return nil
+	}
+	var fns []string
+	seen := map[string]bool{}
+	for _, step := range ep.Steps {
+		if doc := ir.DocumentFor(app, step.Text); doc != nil && !seen[doc.Slug] {
+			seen[doc.Slug] = true
+			fns = append(fns, "store"+documentName(doc))
+		}
+	}
+	return fns
+}
+
+// documentName returns the PascalCase name of a document's functions:
+// an Order's invoice → "OrderInvoice".
+func documentName(doc *ir.Document) string {
+	return capitalize(doc.Model) + strings.ReplaceAll(doc.Title(), " ", "")
+}
+
+// writeDocumentCall emits a "generate a PDF invoice from the Order" step:
+// the PDF is rendered and kept in the storage integration. Without one,
+// it is only rendered when downloaded.
+func writeDocumentCall(b *strings.Builder, doc *ir.Document, step *ir.Action, ep *ir.Endpoint, app *ir.Application) {
+	if !hasStorageIntegration(app) {
+		fmt.Fprintf(b, "    // The %s is rendered when downloaded from /api/documents/%s/:id.pdf\n\n", doc.Kind, doc.Slug)
+		return
+	}
+	id := ""
+	if idx := documentRecordIndex(doc, step, ep, app); idx > 0 {
+		id = lastResultVar(idx) + ".id"
+	} else if param := findIdParam(ep); param != "" {
+		id = param
+	}
+	if id == "" {
+		fmt.Fprintf(b, "    // TODO: no %s to render the %s from — fetch or create it first\n\n", doc.Model, doc.Kind)
+		return
+	}
+	fmt.Fprintf(b, "    await store%s(%s);\n\n", documentName(doc), id)
+}
+
+// documentRecordIndex returns the index of the latest result variable
+// before the step holding the document's model, or 0 if there is none.
+// It counts results the way writeStepCode declares them.
+func documentRecordIndex(doc *ir.Document, step *ir.Action, ep *ir.Endpoint, app *ir.Application) int {
+	idx, found := 0, 0
+	for _, s := range ep.Steps {
+		if s == step {
+			break
+		}
+		switch s.Type {
+		case "create":
+		case "query":
+			if isQueryModifier(s.Text) {
+				continue
+			}
+		case "update":
+			if isDefaultAssignment(s.Text) {
+				continue
+			}
+		case "delete":
+			idx++
+			continue
+		default:
+			continue
+		}
+		idx++
+		if strings.EqualFold(inferModelFromAction(s.Text, app), doc.Model) {
+			found = idx
+		}
+	}
+	return found
+}
+
+// pdfFonts returns the regular and bold standard PDF fonts for a family.
+func pdfFonts(family string) (regular, bold string) {
+	switch family {
+	case "Times":
+		return "Times-Roman", "Times-Bold"
+	case "Courier":
+		return "Courier", "Courier-Bold"
+	}
+	return "Helvetica", "Helvetica-Bold"
+}
+
+// generateDocumentService produces src/services/documents.ts: a PDFKit
+// renderer styled from the app's theme, and for each document a function
+// mapping a record to its content and one rendering it by id. With a
+// storage integration, documents can also be stored as
+// documents/<slug>/<id>.pdf.
+func generateDocumentService(app *ir.Application) string {
+	var b strings.Builder
+	storage := hasStorageIntegration(app)
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import PDFDocument from 'pdfkit';\n")
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	if storage {
+		b.WriteString("import { uploadFile } from './storage';\n")
+	}
+	b.WriteString(prismaImport(app, "."))
+	fmt.Fprintf(&b, "\nconst prisma = %s;\n\n", newPrismaClient(app))
+
+	color, family := ir.PDFStyle(app.Theme)
+	regular, bold := pdfFonts(family)
+	fmt.Fprintf(&b, "const APP_NAME = '%s';\n\n", escapeQuote(app.Name))
+	b.WriteString("// From the app's theme.\n")
+	b.WriteString("const THEME = {\n")
+	fmt.Fprintf(&b, "  primary: '%s',\n", color)
+	fmt.Fprintf(&b, "  font: '%s',\n", regular)
+	fmt.Fprintf(&b, "  bold: '%s',\n", bold)
+	b.WriteString("  text: '#111827',\n")
+	b.WriteString("  muted: '#6b7280',\n")
+	b.WriteString("  rule: '#e5e7eb',\n")
+	b.WriteString("};\n\n")
+
+	b.WriteString(`export interface PdfContent {
+  title: string;
+  reference: string;
+  date: Date;
+  details: [string, string][];
+  columns?: string[];
+  rows?: string[][];
+  total?: [string, string];
+}
+
+// Formats a field value: dates as YYYY-MM-DD, decimals to two places.
+export function formatValue(value: unknown): string {
+  if (value === null || value === undefined || value === '') return '-';
+  if (value instanceof Date) return value.toISOString().slice(0, 10);
+  if (typeof value === 'number') return Number.isInteger(value) ? String(value) : value.toFixed(2);
+  if (typeof value === 'object' && value !== null && 'toFixed' in value) {
+    // Prisma Decimal
+    return (value as { toFixed(digits: number): string }).toFixed(2);
+  }
+  return String(value);
+}
+
+// Renders a document: a header band in the theme color, the title,
+// reference, and date, the detail rows, the items table, and the total.
+export function renderPdf(content: PdfContent): Promise<Buffer> {
+  return new Promise((resolve, reject) => {
+    const doc = new PDFDocument({ size: 'A4', margin: 50, info: { Title: ` + "`${content.title} ${content.reference}`" + `, Author: APP_NAME } });
+    const chunks: Buffer[] = [];
+    doc.on('data', (chunk: Buffer) => chunks.push(chunk));
+    doc.on('end', () => resolve(Buffer.concat(chunks)));
+    doc.on('error', reject);
+
+    const left = 50;
+    const width = doc.page.width - 100;
+
+    doc.rect(0, 0, doc.page.width, 80).fill(THEME.primary);
+    doc.fillColor('#ffffff').font(THEME.bold).fontSize(18).text(APP_NAME, left, 32, { width });
+
+    doc.fillColor(THEME.primary).font(THEME.bold).fontSize(24).text(content.title, left, 110);
+    doc.fillColor(THEME.muted).font(THEME.font).fontSize(10);
+    doc.text(content.reference).text(content.date.toISOString().slice(0, 10));
+    doc.moveDown(1.5);
+
+    for (const [label, value] of content.details) {
+      const y = doc.y;
+      doc.fillColor(THEME.muted).font(THEME.font).fontSize(10).text(label, left, y, { width: 150 });
+      doc.fillColor(THEME.text).text(value, left + 160, y, { width: width - 160 });
+      doc.moveDown(0.4);
+    }
+
+    if (content.columns && content.rows) {
+      doc.moveDown(1);
+      const colWidth = width / content.columns.length;
+      let y = doc.y;
+      doc.rect(left, y - 5, width, 20).fill(THEME.primary);
+      doc.fillColor('#ffffff').font(THEME.bold).fontSize(10);
+      content.columns.forEach((col, i) => doc.text(col, left + i * colWidth + 5, y, { width: colWidth - 10 }));
+      y += 22;
+      doc.fillColor(THEME.text).font(THEME.font);
+      for (const row of content.rows) {
+        if (y > doc.page.height - 100) {
+          doc.addPage();
+          y = 50;
+        }
+        row.forEach((cell, i) => doc.text(cell, left + i * colWidth + 5, y, { width: colWidth - 10 }));
+        y += 18;
+        doc.moveTo(left, y - 5).lineTo(left + width, y - 5).strokeColor(THEME.rule).stroke();
+      }
+      doc.x = left;
+      doc.y = y;
+    }
+
+    if (content.total) {
+      doc.moveDown(1);
+      const y = doc.y;
+      doc.fillColor(THEME.primary).font(THEME.bold).fontSize(14);
+      doc.text(content.total[0], left, y, { width: width - 150 });
+      doc.text(content.total[1], left + width - 150, y, { width: 150, align: 'right' });
+    }
+
+    doc.end();
+  });
+}
+`)
+
+	for _, doc := range app.Documents {
+		name := documentName(doc)
+		record := toCamelCase(doc.Model)
+		model := findModel(doc.Model, app)
+
+		fmt.Fprintf(&b, "\n// The %s content for a%s %s.\n", doc.Kind, articleSuffix(doc.Model), doc.Model)
+		fmt.Fprintf(&b, "export function %s(%s: any): PdfContent {\n", toCamelCase(name), record)
+		b.WriteString("  return {\n")
+		fmt.Fprintf(&b, "    title: '%s',\n", escapeQuote(doc.Title()))
+		if doc.Reference != "" {
+			fmt.Fprintf(&b, "    reference: `%s ${%s.%s ?? %s.id}`,\n", doc.Model, record, doc.Reference, record)
+		} else {
+			fmt.Fprintf(&b, "    reference: `%s ${%s.id}`,\n", doc.Model, record)
+		}
+		fmt.Fprintf(&b, "    date: new Date(%s.createdAt ?? Date.now()),\n", record)
+		b.WriteString("    details: [\n")
+		for _, f := range doc.Fields {
+			fmt.Fprintf(&b, "      ['%s', formatValue(%s.%s)],\n", escapeQuote(ir.FieldLabel(f)), record, f)
+		}
+		b.WriteString("    ],\n")
+		if doc.Items != "" {
+			labels := make([]string, len(doc.ItemFields))
+			cells := make([]string, len(doc.ItemFields))
+			for i, f := range doc.ItemFields {
+				labels[i] = "'" + escapeQuote(ir.FieldLabel(f)) + "'"
+				cells[i] = "formatValue(item." + f + ")"
+			}
+			fmt.Fprintf(&b, "    columns: [%s],\n", strings.Join(labels, ", "))
+			fmt.Fprintf(&b, "    rows: (%s.%ss ?? []).map((item: any) => [%s]),\n", record, toCamelCase(doc.Items), strings.Join(cells, ", "))
+		}
+		if doc.Total != "" {
+			fmt.Fprintf(&b, "    total: ['%s', formatValue(%s.%s)],\n", escapeQuote(ir.FieldLabel(doc.Total)), record, doc.Total)
+		}
+		b.WriteString("  };\n")
+		b.WriteString("}\n")
+
+		include := ""
+		if doc.Items != "" && model != nil {
+			include = fmt.Sprintf(", include: { %ss: true }", toCamelCase(doc.Items))
+		}
+		fmt.Fprintf(&b, "\n// Renders a%s %s's %s, or returns null if there is none matching.\n", articleSuffix(doc.Model), doc.Model, doc.Kind)
+		fmt.Fprintf(&b, "export async function render%s(id: string, where: Record<string, unknown> = {}): Promise<Buffer | null> {\n", name)
+		fmt.Fprintf(&b, "  const %s = await prisma.%s.findFirst({ where: { id, ...where }%s });\n", record, toCamelCase(doc.Model), include)
+		fmt.Fprintf(&b, "  return %s ? renderPdf(%s(%s)) : null;\n", record, toCamelCase(name), record)
+		b.WriteString("}\n")
+
+		if storage {
+			fmt.Fprintf(&b, "\n// Renders a%s %s's %s and stores it as documents/%s/<id>.pdf.\n", articleSuffix(doc.Model), doc.Model, doc.Kind, doc.Slug)
+			fmt.Fprintf(&b, "export async function store%s(id: string): Promise<string | null> {\n", name)
+			fmt.Fprintf(&b, "  const pdf = await render%s(id);\n", name)
+			b.WriteString("  if (!pdf) return null;\n")
+			fmt.Fprintf(&b, "  return uploadFile(`documents/%s/${id}.pdf`, pdf, 'application/pdf');\n", doc.Slug)
+			b.WriteString("}\n")
+		}
+	}
+
+	return b.String()
+}
+
+// articleSuffix returns "n" when a word takes "an": "a" + "n Order".
+func articleSuffix(word string) string {
+	if word != "" && strings.ContainsRune("AEIOUaeiou", rune(word[0])) {
+		return "n"
+	}
+	return ""
+}
+
+// generateDocumentRoutes produces src/routes/documents.ts: each document as
+// a PDF download at GET /api/documents/<slug>/:id.pdf. With authentication,
+// downloads need a signed-in user, who only sees records they own.
+func generateDocumentRoutes(app *ir.Application) string {
+	var b strings.Builder
+	auth := app.Auth != nil
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Router, Request, Response, NextFunction } from 'express';\n")
+	if auth {
+		b.WriteString("import { authenticate } from '../middleware/auth';\n")
+	}
+	renders := make([]string, len(app.Documents))
+	for i, doc := range app.Documents {
+		renders[i] = "render" + documentName(doc)
+	}
+	fmt.Fprintf(&b, "import { %s } from '../services/documents';\n\n", strings.Join(renders, ", "))
+	b.WriteString("const router = Router();\n\n")
+	if auth {
+		b.WriteString("router.use(authenticate);\n\n")
+	}
+
+	b.WriteString(`function sendPDF(res: Response, pdf: Buffer, filename: string) {
+  res.type('application/pdf');
+  res.setHeader('Content-Disposition', ` + "`attachment; filename=\"${filename}\"`" + `);
+  res.send(pdf);
+}
+`)
+
+	for _, doc := range app.Documents {
+		scope := ""
+		if auth && modelBelongsToUser(doc.Model, app) {
+			scope = ", { userId: req.userId! }"
+		}
+		fmt.Fprintf(&b, "\nrouter.get('/%s/:id.pdf', async (req: Request, res: Response, next: NextFunction) => {\n", doc.Slug)
+		b.WriteString("  try {\n")
+		fmt.Fprintf(&b, "    const pdf = await render%s(req.params.id%s);\n", documentName(doc), scope)
+		b.WriteString("    if (!pdf) {\n")
+		fmt.Fprintf(&b, "      res.status(404).json({ error: '%s not found' });\n", escapeQuote(doc.Model))
+		b.WriteString("      return;\n")
+		b.WriteString("    }\n")
+		fmt.Fprintf(&b, "    sendPDF(res, pdf, `%s-${req.params.id}.pdf`);\n", strings.ReplaceAll(doc.Kind, " ", "-"))
+		b.WriteString("  } catch (error) {\n")
+		b.WriteString("    next(error);\n")
+		b.WriteString("  }\n")
+		b.WriteString("});\n")
+	}
+
+	b.WriteString("\nexport { router };\n")
+	return b.String()
+}
@@ -0,0 +1,238 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// encryptedFieldMap renders each model's encrypted fields as a TypeScript
+// object literal body: "  Customer: ['ssn', 'notes'],".
+func encryptedFieldMap(app *ir.Application) string {
+	var b strings.Builder
+	for _, m := range app.Data {
+		fields := m.EncryptedFields()
+		if len(fields) == 0 {
+			continue
+		}
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = "'" + f.Name + "'"
+		}
+		fmt.Fprintf(&b, "  %s: [%s],\n", m.Name, strings.Join(names, ", "))
+	}
+	return b.String()
+}
+
+// generateFieldEncryption produces src/services/encryption.ts: AES-256-GCM
+// encryption of the fields marked encrypted, applied to every Prisma query
+// through a client extension. The stored format is shared with the Python
+// and Go backends.
+func generateFieldEncryption(app *ir.Application) string {
+	return `// Generated by Human compiler — do not edit
+//
+// Field-level encryption at rest (AES-256-GCM). Values are stored as
+// enc:v1:<key id>:<base64 of nonce, ciphertext, and tag>, so each value
+// records which key wrote it.
+//
+// FIELD_ENCRYPTION_KEY is the key new values are written with: 32 random
+// bytes, base64-encoded (openssl rand -base64 32). Load it from your
+// secrets manager in production. FIELD_ENCRYPTION_OLD_KEYS lists retired
+// keys, comma-separated, that can still be read during a rotation.
+
+import crypto from 'crypto';
+import { PrismaClient } from '@prisma/client';
+
+const PREFIX = 'enc:v1:';
+
+// Encrypted fields by model.
+export const encryptedFields: Record<string, string[]> = {
+` + encryptedFieldMap(app) + `};
+
+interface FieldKey {
+  id: string;
+  key: Buffer;
+}
+
+let keys: FieldKey[] | null = null;
+
+function parseKey(encoded: string): FieldKey {
+  const key = Buffer.from(encoded.trim(), 'base64');
+  if (key.length !== 32) {
+    throw new Error('Field encryption keys must be 32 bytes, base64-encoded (openssl rand -base64 32)');
+  }
+  return { id: crypto.createHash('sha256').update(key).digest('hex').slice(0, 8), key };
+}
+
+// loadKeys returns the current key followed by the old ones.
+function loadKeys(): FieldKey[] {
+  if (keys) return keys;
+  const current = process.env.FIELD_ENCRYPTION_KEY;
+  if (!current) {
+    throw new Error('FIELD_ENCRYPTION_KEY is not set');
+  }
+  const old = (process.env.FIELD_ENCRYPTION_OLD_KEYS || '').split(',').filter((k) => k.trim() !== '');
+  keys = [parseKey(current), ...old.map(parseKey)];
+  return keys;
+}
+
+export function isEncrypted(value: unknown): value is string {
+  return typeof value === 'string' && value.startsWith(PREFIX);
+}
+
+export function encrypt(plaintext: string): string {
+  const { id, key } = loadKeys()[0];
+  const nonce = crypto.randomBytes(12);
+  const cipher = crypto.createCipheriv('aes-256-gcm', key, nonce);
+  const sealed = Buffer.concat([nonce, cipher.update(plaintext, 'utf8'), cipher.final(), cipher.getAuthTag()]);
+  return PREFIX + id + ':' + sealed.toString('base64');
+}
+
+// decrypt returns a value that isn't ciphertext unchanged: it was stored
+// before the field was encrypted.
+export function decrypt(value: string): string {
+  if (!isEncrypted(value)) return value;
+  const [id, encoded] = value.slice(PREFIX.length).split(':');
+  const fieldKey = loadKeys().find((k) => k.id === id);
+  if (!fieldKey) {
+    throw new Error('No field encryption key with id ' + id + ' — add the retired key to FIELD_ENCRYPTION_OLD_KEYS');
+  }
+  const sealed = Buffer.from(encoded, 'base64');
+  const decipher = crypto.createDecipheriv('aes-256-gcm', fieldKey.key, sealed.subarray(0, 12));
+  decipher.setAuthTag(sealed.subarray(sealed.length - 16));
+  return Buffer.concat([decipher.update(sealed.subarray(12, sealed.length - 16)), decipher.final()]).toString('utf8');
+}
+
+// needsRotation reports whether a stored value is plaintext or was written
+// with a key other than the current one.
+export function needsRotation(value: string): boolean {
+  return !value.startsWith(PREFIX + loadKeys()[0].id + ':');
+}
+
+function encryptData(model: string, data: any): any {
+  const fields = encryptedFields[model];
+  if (!fields || !data || typeof data !== 'object') return data;
+  if (Array.isArray(data)) return data.map((d) => encryptData(model, d));
+  const out = { ...data };
+  for (const field of fields) {
+    const value = out[field];
+    if (typeof value === 'string') {
+      out[field] = encrypt(value);
+    } else if (value && typeof value === 'object' && typeof value.set === 'string') {
+      out[field] = { set: encrypt(value.set) };
+    }
+  }
+  return out;
+}
+
+function decryptResult(value: any): any {
+  if (isEncrypted(value)) return decrypt(value);
+  if (Array.isArray(value)) return value.map(decryptResult);
+  if (value && typeof value === 'object' && !(value instanceof Date) && !Buffer.isBuffer(value)) {
+    for (const key of Object.keys(value)) {
+      value[key] = decryptResult(value[key]);
+    }
+  }
+  return value;
+}
+
+// withFieldEncryption encrypts encrypted fields in every create, update,
+// and upsert, and decrypts them in every result. Encrypted fields can't be
+// used in a where clause: each encryption of a value differs.
+export function withFieldEncryption(client: PrismaClient) {
+  return client.$extends({
+    query: {
+      $allModels: {
+        async $allOperations({ model, args, query }) {
+          const a = args as any;
+          if (a.data) a.data = encryptData(model, a.data);
+          if (a.create) a.create = encryptData(model, a.create);
+          if (a.update) a.update = encryptData(model, a.update);
+          return decryptResult(await query(args));
+        },
+      },
+    },
+  });
+}
+`
+}
+
+// generateKeyRotation produces src/scripts/rotate-field-keys.ts, run with
+// npm run rotate-keys. It re-encrypts every encrypted field still on an old
+// key, and encrypts values stored before the field was encrypted.
+func generateKeyRotation(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString(`// Generated by Human compiler — do not edit
+//
+// Re-encrypts encrypted fields with the current FIELD_ENCRYPTION_KEY.
+// To rotate the key:
+//   1. Add the current key to FIELD_ENCRYPTION_OLD_KEYS.
+//   2. Set FIELD_ENCRYPTION_KEY to a new key (openssl rand -base64 32).
+//   3. Deploy, then run: npm run rotate-keys
+//   4. Remove the old key from FIELD_ENCRYPTION_OLD_KEYS.
+
+import { PrismaClient } from '@prisma/client';
+import { decrypt, encrypt, needsRotation } from '../services/encryption';
+
+// The plain client, so stored values are read and written as they are.
+const prisma = new PrismaClient();
+
+const BATCH = 500;
+
+async function rotate(delegate: any, fields: string[]): Promise<number> {
+  const select: Record<string, boolean> = { id: true };
+  for (const field of fields) select[field] = true;
+
+  let updated = 0;
+  let cursor: string | undefined;
+  for (;;) {
+    const rows: any[] = await delegate.findMany({
+      select,
+      orderBy: { id: 'asc' },
+      take: BATCH,
+      ...(cursor ? { skip: 1, cursor: { id: cursor } } : {}),
+    });
+    if (rows.length === 0) return updated;
+    for (const row of rows) {
+      const data: Record<string, string> = {};
+      for (const field of fields) {
+        const value = row[field];
+        if (typeof value === 'string' && needsRotation(value)) {
+          data[field] = encrypt(decrypt(value));
+        }
+      }
+      if (Object.keys(data).length > 0) {
+        await delegate.update({ where: { id: row.id }, data });
+        updated++;
+      }
+    }
+    cursor = rows[rows.length - 1].id;
+  }
+}
+
+async function main() {
+`)
+	for _, m := range app.Data {
+		fields := m.EncryptedFields()
+		if len(fields) == 0 {
+			continue
+		}
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = "'" + f.Name + "'"
+		}
+		fmt.Fprintf(&b, "  const %s = await rotate(prisma.%s, [%s]);\n", toCamelCase(m.Name)+"Count", toCamelCase(m.Name), strings.Join(names, ", "))
+		fmt.Fprintf(&b, "  console.log(`%s: re-encrypted ${%s} records`);\n", m.Name, toCamelCase(m.Name)+"Count")
+	}
+	b.WriteString(`}
+
+main()
+  .catch((err) => {
+    console.error(err);
+    process.exitCode = 1;
+  })
+  .finally(() => prisma.$disconnect());
+`)
+	return b.String()
+}
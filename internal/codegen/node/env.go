@@ -0,0 +1,99 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateEnvCheck produces src/env.ts, which checks the env vars the
+// server reads when it's imported. server.ts and worker.ts import it first,
+// so a missing or malformed variable stops them before any module reads it.
+func generateEnvCheck(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("type EnvCheck = {\n")
+	b.WriteString("  name: string;\n")
+	b.WriteString("  kind: 'text' | 'url' | 'port' | 'key';\n")
+	b.WriteString("  required?: boolean;\n")
+	b.WriteString("  requiredInProduction?: boolean;\n")
+	b.WriteString("  devValues?: string[];\n")
+	b.WriteString("};\n\n")
+
+	b.WriteString("const CHECKS: EnvCheck[] = [\n")
+	for _, r := range docker.EnvRules(app) {
+		fmt.Fprintf(&b, "  { name: '%s', kind: '%s'", r.Name, r.Kind)
+		if r.Required {
+			b.WriteString(", required: true")
+		}
+		if r.RequiredInProduction {
+			b.WriteString(", requiredInProduction: true")
+		}
+		if len(r.DevValues) > 0 {
+			b.WriteString(", devValues: [")
+			for i, v := range r.DevValues {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				fmt.Fprintf(&b, "'%s'", v)
+			}
+			b.WriteString("]")
+		}
+		b.WriteString(" },")
+		if r.Comment != "" {
+			fmt.Fprintf(&b, " // %s", r.Comment)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("];\n\n")
+
+	fmt.Fprintf(&b, "const production = process.env.%s === 'production';\n\n", docker.ProductionEnv(app))
+	b.WriteString(`function problem(check: EnvCheck, value: string | undefined): string | null {
+  if (value === undefined || value === '') {
+    if (check.required || (production && check.requiredInProduction)) {
+      return ` + "`${check.name} is not set`" + `;
+    }
+    return null;
+  }
+  if (production && check.devValues?.includes(value)) {
+    return ` + "`${check.name} still has its development value — set your own in production`" + `;
+  }
+  switch (check.kind) {
+    case 'url':
+      try {
+        if (new URL(value).host === '') {
+          throw new Error('no host');
+        }
+      } catch {
+        return ` + "`${check.name} must be a URL with a scheme and a host`" + `;
+      }
+      return null;
+    case 'port': {
+      const port = Number(value);
+      if (!Number.isInteger(port) || port < 1 || port > 65535) {
+        return ` + "`${check.name} must be a port number (1-65535), got \"${value}\"`" + `;
+      }
+      return null;
+    }
+    case 'key':
+      if (Buffer.from(value, 'base64').length !== 32) {
+        return ` + "`${check.name} must be 32 bytes, base64 — generate one with openssl rand -base64 32`" + `;
+      }
+      return null;
+    default:
+      return null;
+  }
+}
+
+const problems = CHECKS.map((check) => problem(check, process.env[check.name])).filter((p): p is string => p !== null);
+if (problems.length > 0) {
+  console.error(` + "`Invalid environment:\\n${problems.map((p) => `  - ${p}`).join('\\n')}\\nSee .env.example for the expected values.`" + `);
+  process.exit(1);
+}
+
+export {};
+`)
+	return b.String()
+}
@@ -12,7 +12,12 @@ func generateErrorHandler(app *ir.Application) string {
 	var b strings.Builder
 
 	b.WriteString("// Generated by Human compiler — do not edit\n\n")
-	b.WriteString("import { Request, Response, NextFunction } from 'express';\n\n")
+	b.WriteString("import { Request, Response, NextFunction } from 'express';\n")
+	masks := len(ir.PersonalFieldNames(app)) > 0
+	if masks {
+		b.WriteString("import { maskText, personalValues } from './masking';\n")
+	}
+	b.WriteString("\n")
 
 	// Write handler configs from IR error handlers
 	if len(app.ErrorHandlers) > 0 {
@@ -48,9 +53,15 @@ func generateErrorHandler(app *ir.Application) string {
 	b.WriteString("  return new Promise(resolve => setTimeout(resolve, ms));\n")
 	b.WriteString("}\n\n")
 
-	// Main error handler middleware
-	b.WriteString(`export function errorHandler(err: Error, req: Request, res: Response, _next: NextFunction) {
-  console.error('[Error]', err.message);
+	// Main error handler middleware. Messages can repeat what the client
+	// sent, so personal values are masked before logging.
+	b.WriteString("export function errorHandler(err: Error, req: Request, res: Response, _next: NextFunction) {\n")
+	if masks {
+		b.WriteString("  console.error('[Error]', maskText(err.message, personalValues(req)));\n")
+	} else {
+		b.WriteString("  console.error('[Error]', err.message);\n")
+	}
+	b.WriteString(`
 
   // Database connection errors
   if (err.message.includes('connect') || err.message.includes('ECONNREFUSED')) {
@@ -0,0 +1,151 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// hasAnalyticsIntegration returns true if the app has an analytics integration.
+func hasAnalyticsIntegration(app *ir.Application) bool {
+	for _, integ := range app.Integrations {
+		if integ.Type == "analytics" {
+			return true
+		}
+	}
+	return false
+}
+
+// generateExperiments produces the A/B experiment middleware: sticky
+// variant assignment for every request, an endpoint the frontend reads
+// assignments from, and exposure logging to the analytics integration.
+func generateExperiments(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { createHash, randomUUID } from 'crypto';\n")
+	b.WriteString("import { Router, Request, Response, NextFunction } from 'express';\n")
+	if hasAnalyticsIntegration(app) {
+		b.WriteString("import { track } from '../services/analytics';\n")
+	}
+
+	b.WriteString(`
+export interface Variant {
+  name: string;
+  weight: number;
+}
+
+export interface Experiment {
+  name: string;
+  audience: string;
+  variants: Variant[];
+}
+
+`)
+
+	b.WriteString("export const EXPERIMENTS: Experiment[] = [\n")
+	for _, exp := range app.Experiments {
+		var variants []string
+		for _, v := range exp.Variants {
+			variants = append(variants, fmt.Sprintf("{ name: '%s', weight: %d }", v.Page, v.Weight))
+		}
+		fmt.Fprintf(&b, "  { name: '%s', audience: '%s', variants: [%s] },\n", exp.Name, exp.Audience, strings.Join(variants, ", "))
+	}
+	b.WriteString("];\n")
+
+	b.WriteString(`
+const VISITOR_COOKIE = 'hx_visitor';
+const VISITOR_ID = /^[A-Za-z0-9_-]{8,64}$/;
+const ONE_YEAR_MS = 365 * 24 * 60 * 60 * 1000;
+
+declare global {
+  namespace Express {
+    interface Request {
+      visitorId?: string;
+      experiments?: Record<string, string>;
+    }
+  }
+}
+
+// bucket maps a visitor to 0–99 for an experiment. The hash is
+// deterministic, so a visitor always lands in the same variant.
+export function bucket(experiment: string, visitorId: string): number {
+  const digest = createHash('sha256').update(` + "`${experiment}:${visitorId}`" + `).digest();
+  return digest.readUInt32BE(0) % 100;
+}
+
+export function assignVariant(experiment: Experiment, visitorId: string): string {
+  let point = bucket(experiment.name, visitorId);
+  for (const variant of experiment.variants) {
+    if (point < variant.weight) return variant.name;
+    point -= variant.weight;
+  }
+  return experiment.variants[0].name;
+}
+
+function readCookie(req: Request, name: string): string | undefined {
+  for (const part of (req.headers.cookie || '').split(';')) {
+    const [key, ...rest] = part.trim().split('=');
+    if (key === name) return decodeURIComponent(rest.join('='));
+  }
+  return undefined;
+}
+
+// assignExperiments identifies the visitor — X-Visitor-Id header, then the
+// visitor cookie, else a new id — and assigns a variant for every experiment.
+export function assignExperiments(req: Request, res: Response, next: NextFunction) {
+  const header = req.headers['x-visitor-id'];
+  const cookie = readCookie(req, VISITOR_COOKIE);
+  let visitorId = [header, cookie].find((id): id is string => typeof id === 'string' && VISITOR_ID.test(id));
+  if (!visitorId) {
+    visitorId = randomUUID();
+  }
+  if (cookie !== visitorId) {
+    res.cookie(VISITOR_COOKIE, visitorId, { maxAge: ONE_YEAR_MS, httpOnly: true, sameSite: 'lax' });
+  }
+
+  req.visitorId = visitorId;
+  req.experiments = {};
+  for (const experiment of EXPERIMENTS) {
+    req.experiments[experiment.name] = assignVariant(experiment, visitorId);
+  }
+  next();
+}
+
+// logExposure records that a visitor saw their variant. Analytics failures
+// are logged and swallowed so they never break the page.
+async function logExposure(visitorId: string, experiment: string, variant: string): Promise<void> {
+  const properties = { experiment, variant };
+`)
+	if hasAnalyticsIntegration(app) {
+		b.WriteString(`  try {
+    await track(visitorId, 'Experiment Viewed', properties);
+  } catch (err) {
+    console.error('Failed to log experiment exposure:', err);
+  }
+`)
+	} else {
+		b.WriteString("  console.log(JSON.stringify({ event: 'Experiment Viewed', visitorId, ...properties }));\n")
+	}
+	b.WriteString(`}
+
+export const experimentsRouter = Router();
+
+experimentsRouter.get('/experiments', (req: Request, res: Response) => {
+  res.json({ data: { visitorId: req.visitorId, assignments: req.experiments } });
+});
+
+experimentsRouter.post('/experiments/:name/exposure', async (req: Request, res: Response) => {
+  const variant = req.experiments?.[req.params.name];
+  if (!variant) {
+    res.status(404).json({ error: 'Unknown experiment' });
+    return;
+  }
+  await logExposure(req.visitorId!, req.params.name, variant);
+  res.status(204).end();
+});
+`)
+
+	return b.String()
+}
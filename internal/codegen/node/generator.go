@@ -7,6 +7,9 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/codegen/grpc"
+	"github.com/barun-bash/human/internal/codegen/openapi"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -34,10 +37,11 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 
 	files := map[string]string{
 		filepath.Join(outputDir, "prisma", "schema.prisma"):        generatePrismaSchema(app),
-		filepath.Join(outputDir, "src", "middleware", "auth.ts"):    generateAuthMiddleware(app),
-		filepath.Join(outputDir, "src", "middleware", "errors.ts"):  generateErrorHandler(app),
+		filepath.Join(outputDir, "src", "middleware", "auth.ts"):   generateAuthMiddleware(app),
+		filepath.Join(outputDir, "src", "middleware", "errors.ts"): generateErrorHandler(app),
 		filepath.Join(outputDir, "src", "routes", "index.ts"):      generateRouteIndex(app),
-		filepath.Join(outputDir, "src", "server.ts"):                generateServer(app),
+		filepath.Join(outputDir, "src", "server.ts"):               generateServer(app),
+		filepath.Join(outputDir, "src", "env.ts"):                  generateEnvCheck(app),
 	}
 
 	// Generate authorization middleware when policies are defined
@@ -73,25 +77,148 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		files[filepath.Join(outputDir, "src", "routes", "upload.ts")] = generateUploadRoute(app)
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
+	// Generate the audit log middleware for compliance profiles
+	if ir.AuditsRequests(app) {
+		files[filepath.Join(outputDir, "src", "middleware", "audit.ts")] = generateAuditLog()
+	}
+
+	// Generate log and error masking for fields marked personal
+	if len(ir.PersonalFieldNames(app)) > 0 {
+		files[filepath.Join(outputDir, "src", "middleware", "masking.ts")] = generateMasking(app)
+	}
+
+	// Generate experiment assignment middleware when experiments are declared
+	if len(app.Experiments) > 0 {
+		files[filepath.Join(outputDir, "src", "middleware", "experiments.ts")] = generateExperiments(app)
+	}
+
+	// Generate in-app notification storage and routes
+	if len(app.Notifications) > 0 {
+		files[filepath.Join(outputDir, "src", "services", "notifications.ts")] = generateNotificationService()
+		files[filepath.Join(outputDir, "src", "routes", "notifications.ts")] = generateNotificationRoutes()
+	}
+
+	// Generate subscription billing: Stripe Checkout, the webhook, plan
+	// checks, and the script syncing plans to Stripe
+	if ir.Bills(app) {
+		files[filepath.Join(outputDir, "src", "services", "billing.ts")] = generateBillingService(app)
+		files[filepath.Join(outputDir, "src", "routes", "billing.ts")] = generateBillingRoutes(app)
+		files[filepath.Join(outputDir, "src", "scripts", "sync-plans.ts")] = generatePlanSync(app)
+		if app.Billing.Gated() && len(app.Policies) > 0 {
+			files[filepath.Join(outputDir, "src", "middleware", "plans.ts")] = generatePlanMiddleware(app)
 		}
 	}
 
-	return nil
-}
+	// Generate usage metering: counting calls, quotas, and the usage endpoints
+	if ir.Meters(app) {
+		files[filepath.Join(outputDir, "src", "services", "usage.ts")] = generateUsageService(app)
+		files[filepath.Join(outputDir, "src", "middleware", "metering.ts")] = generateMeteringMiddleware(app)
+		files[filepath.Join(outputDir, "src", "routes", "usage.ts")] = generateUsageRoutes(app)
+	}
 
-// writeFile writes content to a file, creating parent directories if needed.
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
+	// Generate API keys for external clients and the endpoints managing them
+	if ir.IssuesAPIKeys(app) {
+		files[filepath.Join(outputDir, "src", "services", "apiKeys.ts")] = generateAPIKeyService(app)
+		files[filepath.Join(outputDir, "src", "routes", "apiKeys.ts")] = generateAPIKeyRoutes(app)
 	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
+
+	// Generate chaos mode, failing API requests the way the error handlers
+	// are declared for
+	if ir.InjectsChaos(app) {
+		files[filepath.Join(outputDir, "src", "middleware", "chaos.ts")] = generateChaos(app)
 	}
-	return nil
+
+	// Generate the migrations taking databases through the schema's history
+	for relPath, content := range generateMigrations(app) {
+		files[filepath.Join(outputDir, filepath.FromSlash(relPath))] = content
+	}
+
+	// Generate the seed script filling the development database
+	if len(app.Data) > 0 {
+		files[filepath.Join(outputDir, "prisma", "seed.ts")] = generateSeed(app)
+	}
+
+	// Generate request recording, for replaying API requests
+	files[filepath.Join(outputDir, "src", "middleware", "record.ts")] = generateRecord()
+
+	// Generate request validation against the OpenAPI document
+	if ir.ValidatesRequests(app) {
+		files[filepath.Join(outputDir, "openapi.yaml")] = openapi.Spec(app)
+		files[filepath.Join(outputDir, "src", "middleware", "openapi.ts")] = generateOpenAPIValidation()
+	}
+
+	// Generate calendar feeds and .ics downloads
+	if len(app.Calendars) > 0 {
+		files[filepath.Join(outputDir, "src", "services", "calendar.ts")] = generateCalendarService(app)
+		files[filepath.Join(outputDir, "src", "routes", "calendar.ts")] = generateCalendarRoutes(app)
+		files[filepath.Join(outputDir, "src", "__tests__", "calendar.test.ts")] = generateCalendarTests()
+	}
+
+	// Generate PDF documents and their downloads
+	if len(app.Documents) > 0 {
+		files[filepath.Join(outputDir, "src", "services", "documents.ts")] = generateDocumentService(app)
+		files[filepath.Join(outputDir, "src", "routes", "documents.ts")] = generateDocumentRoutes(app)
+	}
+
+	// Generate aggregate helpers for charts and report endpoints
+	if hasAggregates(app) {
+		files[filepath.Join(outputDir, "src", "services", "aggregates.ts")] = generateAggregateHelpers(app)
+	}
+
+	// Generate aggregate endpoints for dashboard charts
+	if len(app.Charts) > 0 {
+		files[filepath.Join(outputDir, "src", "routes", "charts.ts")] = generateChartRoutes(app)
+	}
+
+	// Generate the endpoints persisting drag-and-drop order
+	if len(app.Reorders) > 0 {
+		files[filepath.Join(outputDir, "src", "routes", "reorder.ts")] = generateReorderRoutes(app)
+	}
+
+	// Generate the version checks of records protected against conflicting edits
+	if ir.ProtectsEdits(app) {
+		files[filepath.Join(outputDir, "src", "services", "concurrency.ts")] = generateConcurrencyHelpers()
+	}
+
+	// Generate the record checks of CSV and JSON imports
+	if ir.HasImports(app) {
+		files[filepath.Join(outputDir, "src", "services", "imports.ts")] = generateImportHelpers()
+	}
+
+	// Generate the shared Prisma client for read replicas and pool sizes
+	if sharesPrismaClient(app) {
+		files[filepath.Join(outputDir, "src", "services", "database.ts")] = generateDatabaseClient(app)
+	}
+
+	// Generate the models' lifecycle hooks as a Prisma client extension
+	if ir.HasModelHooks(app) {
+		files[filepath.Join(outputDir, "src", "services", "hooks.ts")] = generateModelHooks(app)
+	}
+
+	// Generate field encryption and its key rotation script
+	if ir.UsesFieldEncryption(app) {
+		files[filepath.Join(outputDir, "src", "services", "encryption.ts")] = generateFieldEncryption(app)
+		files[filepath.Join(outputDir, "src", "scripts", "rotate-field-keys.ts")] = generateKeyRotation(app)
+	}
+
+	// Generate sitemap.xml and robots.txt for web apps with public pages
+	if app.Sitemap != nil {
+		files[filepath.Join(outputDir, "src", "routes", "sitemap.ts")] = generateSitemapRoutes(app)
+	}
+
+	// Generate the job queue and worker that run workflows
+	if ir.UsesJobQueue(app) {
+		files[filepath.Join(outputDir, "src", "jobs", "queue.ts")] = generateJobQueue(app)
+		files[filepath.Join(outputDir, "src", "worker.ts")] = generateWorker(app)
+	}
+
+	// Generate the gRPC server and its proto when the API style is gRPC
+	if grpc.IsEnabled(app) {
+		files[filepath.Join(outputDir, "src", "grpc", "server.ts")] = generateGrpcServer(app)
+		files[filepath.Join(outputDir, "proto", filepath.FromSlash(grpc.ProtoPath(app)))] = grpc.Proto(app)
+	}
+
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 // toCamelCase converts PascalCase or space-separated to camelCase.
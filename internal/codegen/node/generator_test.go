@@ -1559,7 +1559,7 @@ func TestWebhookRouteGenerated(t *testing.T) {
 		Name: "TestApp",
 		Integrations: []*ir.Integration{
 			{Service: "Stripe", Type: "payment",
-				Config: map[string]string{"webhook_endpoint": "/webhooks/stripe"},
+				Config:      map[string]string{"webhook_endpoint": "/webhooks/stripe"},
 				Credentials: map[string]string{"api key": "STRIPE_SECRET_KEY"},
 			},
 		},
@@ -1603,3 +1603,1979 @@ func TestOAuthCallbackRoutes(t *testing.T) {
 		t.Error("callback should sign a JWT token")
 	}
 }
+
+func TestGenerateGrpcServer(t *testing.T) {
+	app := &ir.Application{
+		Name:   "TaskFlow",
+		Config: &ir.BuildConfig{Backend: "Node with Express", APIStyle: "gRPC"},
+		Data: []*ir.DataModel{
+			{Name: "Task", Fields: []*ir.DataField{{Name: "title", Type: "text"}, {Name: "meta", Type: "json"}}},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "GetTasks", Auth: true},
+			{Name: "DeleteTask", Auth: true, Params: []*ir.Param{{Name: "task_id"}}},
+			{Name: "Login", Params: []*ir.Param{{Name: "email"}}},
+		},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	server, err := os.ReadFile(filepath.Join(dir, "src", "grpc", "server.ts"))
+	if err != nil {
+		t.Fatal("missing src/grpc/server.ts")
+	}
+	for _, want := range []string{
+		"const PROTO_PATH = path.resolve(__dirname, '../../proto/taskflow/v1/taskflow.proto');",
+		"  GetTasks: { method: 'GET', path: '/api/tasks', params: [] },",
+		"  DeleteTask: { method: 'DELETE', path: '/api/task', params: [['taskId', 'task_id']] },",
+		"  Login: { method: 'POST', path: '/api/login', params: [['email', 'email']], jsonData: true },",
+		"const JSON_FIELDS = new Set<string>(['meta']);",
+		"const service = proto.taskflow.v1.TaskFlowService.service;",
+	} {
+		if !strings.Contains(string(server), want) {
+			t.Errorf("server.ts missing %q", want)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "proto", "taskflow", "v1", "taskflow.proto")); err != nil {
+		t.Error("missing proto copy for the runtime loader")
+	}
+	entry, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	if !strings.Contains(string(entry), "startGrpcServer(app)") {
+		t.Error("server.ts should start the gRPC server")
+	}
+}
+
+func TestExperimentsGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Shop",
+		Config: &ir.BuildConfig{Backend: "Node with Express"},
+		Pages:  []*ir.Page{{Name: "Classic"}, {Name: "NewPricing"}},
+		Integrations: []*ir.Integration{
+			{Service: "PostHog", Type: "analytics", Credentials: map[string]string{"api key": "POSTHOG_KEY"}},
+		},
+		Experiments: []*ir.Experiment{
+			{Name: "pricing-page", Audience: "visitors", Variants: []*ir.ExperimentVariant{{Page: "Classic", Weight: 50}, {Page: "NewPricing", Weight: 50}}},
+		},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	middleware, err := os.ReadFile(filepath.Join(dir, "src", "middleware", "experiments.ts"))
+	if err != nil {
+		t.Fatal("missing src/middleware/experiments.ts")
+	}
+	for _, want := range []string{
+		"import { track } from '../services/analytics';",
+		"{ name: 'pricing-page', audience: 'visitors', variants: [{ name: 'Classic', weight: 50 }, { name: 'NewPricing', weight: 50 }] },",
+		"return digest.readUInt32BE(0) % 100;",
+		"res.cookie(VISITOR_COOKIE, visitorId,",
+		"await track(visitorId, 'Experiment Viewed', properties);",
+		"experimentsRouter.post('/experiments/:name/exposure',",
+	} {
+		if !strings.Contains(string(middleware), want) {
+			t.Errorf("experiments.ts missing %q", want)
+		}
+	}
+
+	analytics, err := os.ReadFile(filepath.Join(dir, "src", "services", "analytics.ts"))
+	if err != nil {
+		t.Fatal("missing src/services/analytics.ts")
+	}
+	if !strings.Contains(string(analytics), "process.env.POSTHOG_KEY") {
+		t.Error("analytics.ts should read the declared credential")
+	}
+
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	for _, want := range []string{"app.use(assignExperiments);", "app.use('/api', experimentsRouter);"} {
+		if !strings.Contains(string(server), want) {
+			t.Errorf("server.ts missing %q", want)
+		}
+	}
+}
+
+func TestExperimentsWithoutAnalyticsLogToConsole(t *testing.T) {
+	app := &ir.Application{
+		Experiments: []*ir.Experiment{
+			{Name: "hero", Audience: "visitors", Variants: []*ir.ExperimentVariant{{Page: "Home", Weight: 50}, {Page: "Landing", Weight: 50}}},
+		},
+	}
+	output := generateExperiments(app)
+	if strings.Contains(output, "services/analytics") {
+		t.Error("experiments.ts should not import analytics without the integration")
+	}
+	if !strings.Contains(output, "console.log(JSON.stringify({ event: 'Experiment Viewed'") {
+		t.Error("exposures should be logged to the console without analytics")
+	}
+}
+
+const notificationSource = `app TaskFlow is a web application
+
+data User:
+  has a name which is text
+  has an email which is email
+
+data Task:
+  belongs to a User
+  has a title which is text
+
+api AssignTask:
+  requires authentication
+  accepts task_id and user_id
+  update the Task with the assignee
+  respond with the updated task
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+notify the user in the app when their task is assigned
+
+build with:
+  frontend using React with TypeScript
+  backend using Node with Express
+  database using PostgreSQL`
+
+func TestNotificationsGenerated(t *testing.T) {
+	prog, err := parser.Parse(notificationSource)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, _ := os.ReadFile(filepath.Join(dir, "src", "routes", "assign-task.ts"))
+	for _, want := range []string{
+		"import { notify } from '../services/notifications';",
+		"await notify(user_id, 'Your task was assigned');",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("assign-task.ts missing %q", want)
+		}
+	}
+
+	notifications, err := os.ReadFile(filepath.Join(dir, "src", "routes", "notifications.ts"))
+	if err != nil {
+		t.Fatal("missing src/routes/notifications.ts")
+	}
+	for _, want := range []string{
+		"router.use(authenticate);",
+		"router.get('/unread-count',",
+		"where: { userId: req.userId!, seen: null }",
+		"router.post('/:id/read',",
+	} {
+		if !strings.Contains(string(notifications), want) {
+			t.Errorf("notifications.ts missing %q", want)
+		}
+	}
+
+	schema, _ := os.ReadFile(filepath.Join(dir, "prisma", "schema.prisma"))
+	if !strings.Contains(string(schema), "model Notification {") {
+		t.Error("schema.prisma should declare the Notification model")
+	}
+
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	if !strings.Contains(string(server), "app.use('/api/notifications', require('./routes/notifications').router);") {
+		t.Error("server.ts should mount the notifications routes")
+	}
+}
+
+func TestCalendarFeedsGenerated(t *testing.T) {
+	source := `app EventHub is a web application
+
+data Event:
+  has a name which is text
+  has a starts_at which is datetime
+  has an ends_at which is datetime
+
+data Holiday:
+  has a title which is text
+  has a day which is date
+
+users can add an Event to their calendar
+users can add a Holiday to their calendar
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := os.ReadFile(filepath.Join(dir, "src", "routes", "calendar.ts"))
+	if err != nil {
+		t.Fatal("missing src/routes/calendar.ts")
+	}
+	for _, want := range []string{
+		"router.get('/events.ics',",
+		"router.get('/events/:id.ics',",
+		"end: record.ends_at ? new Date(record.ends_at) : undefined,",
+		"router.get('/holidays.ics',",
+		"allDay: true,",
+		"`holiday-${record.id}.ics`",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("calendar.ts missing %q", want)
+		}
+	}
+
+	for _, rel := range []string{"src/services/calendar.ts", "src/__tests__/calendar.test.ts"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(rel))); err != nil {
+			t.Errorf("missing %s", rel)
+		}
+	}
+
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	if !strings.Contains(string(server), "app.use('/api/calendar', require('./routes/calendar').router);") {
+		t.Error("server.ts should mount the calendar routes")
+	}
+}
+
+func TestPDFDocumentsGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data User:
+  has a name which is text
+  has many Order
+
+data Order:
+  belongs to a User
+  has an order_number which is unique text
+  has a total which is decimal
+  has many OrderItem
+
+data OrderItem:
+  belongs to an Order
+  has a quantity which is number
+
+api CreateOrder:
+  requires authentication
+  accepts items
+  create an Order with the given fields
+  generate a PDF invoice from the Order
+  respond with the created order
+
+theme:
+  primary color is #E94560
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+integrate with AWS S3:
+  api key from environment variable AWS_ACCESS_KEY
+  use for storing invoices
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	service, err := os.ReadFile(filepath.Join(dir, "src", "services", "documents.ts"))
+	if err != nil {
+		t.Fatal("missing src/services/documents.ts")
+	}
+	for _, want := range []string{
+		"import PDFDocument from 'pdfkit';",
+		"primary: '#e94560',",
+		"reference: `Order ${order.order_number ?? order.id}`,",
+		"rows: (order.orderItems ?? []).map((item: any) => [formatValue(item.quantity)]),",
+		"total: ['Total', formatValue(order.total)],",
+		"include: { orderItems: true }",
+		"uploadFile(`documents/order-invoices/${id}.pdf`, pdf, 'application/pdf')",
+	} {
+		if !strings.Contains(string(service), want) {
+			t.Errorf("documents.ts missing %q", want)
+		}
+	}
+
+	routes, _ := os.ReadFile(filepath.Join(dir, "src", "routes", "documents.ts"))
+	for _, want := range []string{
+		"router.use(authenticate);",
+		"router.get('/order-invoices/:id.pdf',",
+		"await renderOrderInvoice(req.params.id, { userId: req.userId! });",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes/documents.ts missing %q", want)
+		}
+	}
+
+	route, _ := os.ReadFile(filepath.Join(dir, "src", "routes", "create-order.ts"))
+	if !strings.Contains(string(route), "await storeOrderInvoice(result.id);") {
+		t.Errorf("create-order.ts should store the invoice, got:\n%s", route)
+	}
+
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	if !strings.Contains(string(server), "app.use('/api/documents', require('./routes/documents').router);") {
+		t.Error("server.ts should mount the document routes")
+	}
+}
+
+func TestSitemapGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data Product:
+  has a name which is text
+  has a price which is decimal
+
+page Home:
+  show a heading
+
+page ProductDetail:
+  show the product name
+
+environment production:
+  url is shop.example.com
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := os.ReadFile(filepath.Join(dir, "src", "routes", "sitemap.ts"))
+	if err != nil {
+		t.Fatal("missing src/routes/sitemap.ts")
+	}
+	for _, want := range []string{
+		"const PAGES = ['/', '/product-detail'];",
+		"return (process.env.SITE_URL || 'https://shop.example.com').replace(/\\/$/, '');",
+		"await prisma.product.findMany({ select: { id: true, updatedAt: true }, take: MAX_URLS });",
+		"urlEntry(`${origin}/product-detail?id=${r.id}`, r.updatedAt)",
+		"router.get('/robots.txt',",
+		"'Disallow: /api/',",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("sitemap.ts missing %q", want)
+		}
+	}
+
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	if !strings.Contains(string(server), "app.use('/api', require('./routes/sitemap').router);") {
+		t.Error("server.ts should mount the sitemap routes")
+	}
+}
+
+func TestFieldEncryptionGenerated(t *testing.T) {
+	source := `app Clinic is a web application
+
+data Patient:
+  has a name which is text
+  has a ssn which is encrypted text
+  has an optional notes which is encrypted text
+
+page Home:
+  show a list of patients
+
+api CreatePatient:
+  accepts name, ssn, and notes
+  create a Patient with the given fields
+  respond with the created patient
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := os.ReadFile(filepath.Join(dir, "src", "services", "encryption.ts"))
+	if err != nil {
+		t.Fatal("missing src/services/encryption.ts")
+	}
+	for _, want := range []string{
+		"Patient: ['ssn', 'notes'],",
+		"crypto.createCipheriv('aes-256-gcm', key, nonce)",
+		"export function withFieldEncryption(client: PrismaClient) {",
+	} {
+		if !strings.Contains(string(enc), want) {
+			t.Errorf("encryption.ts missing %q", want)
+		}
+	}
+
+	rotate, err := os.ReadFile(filepath.Join(dir, "src", "scripts", "rotate-field-keys.ts"))
+	if err != nil {
+		t.Fatal("missing src/scripts/rotate-field-keys.ts")
+	}
+	if !strings.Contains(string(rotate), "await rotate(prisma.patient, ['ssn', 'notes']);") {
+		t.Error("rotate-field-keys.ts should rotate the patient's encrypted fields")
+	}
+
+	route, _ := os.ReadFile(filepath.Join(dir, "src", "routes", "create-patient.ts"))
+	for _, want := range []string{
+		"import { withFieldEncryption } from '../services/encryption';",
+		"const prisma = withFieldEncryption(new PrismaClient());",
+	} {
+		if !strings.Contains(string(route), want) {
+			t.Errorf("create-patient.ts missing %q", want)
+		}
+	}
+}
+
+func TestSharedPrismaClientGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:     "TaskFlow",
+		Config:   &ir.BuildConfig{Backend: "Node with Express", Database: "PostgreSQL"},
+		Database: &ir.DatabaseConfig{Engine: "PostgreSQL", ReadReplica: true, PoolSize: 20},
+	}
+
+	client := generateDatabaseClient(app)
+	for _, want := range []string{
+		"import { readReplicas } from '@prisma/extension-read-replicas';",
+		"const POOL_SIZE = 20;",
+		"parsed.searchParams.set('connection_limit', String(POOL_SIZE));",
+		"const replicaUrl = process.env.DATABASE_REPLICA_URL || url;",
+		"connect(url).$extends(readReplicas({ replicas: [connect(replicaUrl)] }))",
+		"export function getPrismaClient() {",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("database.ts missing %q", want)
+		}
+	}
+	if got := prismaImport(app, "../services"); got != "import { getPrismaClient } from '../services/database';\n" {
+		t.Errorf("prismaImport: got %q", got)
+	}
+	if got := newPrismaClient(app); got != "getPrismaClient()" {
+		t.Errorf("newPrismaClient: got %q", got)
+	}
+	if schema := generatePrismaSchema(app); !strings.Contains(schema, `directUrl = env("DIRECT_DATABASE_URL")`) {
+		t.Error("schema.prisma should apply schema changes over a direct connection behind PgBouncer")
+	}
+
+	// Encrypted fields are decrypted on replica reads too.
+	app.Data = []*ir.DataModel{{Name: "Patient", Fields: []*ir.DataField{{Name: "ssn", Type: "text", Encrypted: true}}}}
+	if !strings.Contains(generateDatabaseClient(app), "return withFieldEncryption(new PrismaClient({ datasourceUrl: withPoolSize(url) }));") {
+		t.Error("database.ts should wrap primary and replica clients with field encryption")
+	}
+
+	app.Database = &ir.DatabaseConfig{Engine: "PostgreSQL"}
+	app.Data = nil
+	if sharesPrismaClient(app) || newPrismaClient(app) != "new PrismaClient()" {
+		t.Error("without a replica or pool size, routes create their own client")
+	}
+}
+
+func TestAuditLogGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Clinic",
+		Config: &ir.BuildConfig{Backend: "Node with Express", Compliance: "SOC2"},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	audit, err := os.ReadFile(filepath.Join(dir, "src", "middleware", "audit.ts"))
+	if err != nil {
+		t.Fatal("missing src/middleware/audit.ts")
+	}
+	if !strings.Contains(string(audit), "type: 'audit',") {
+		t.Error("audit.ts should write audit entries")
+	}
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	for _, want := range []string{"import { auditLog } from './middleware/audit';", "app.use(auditLog);"} {
+		if !strings.Contains(string(server), want) {
+			t.Errorf("server.ts missing %q", want)
+		}
+	}
+
+	app.Config.Compliance = ""
+	dir = t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "middleware", "audit.ts")); err == nil {
+		t.Error("audit.ts should only be generated with a compliance profile")
+	}
+}
+
+func TestOpenAPIValidationGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Shop",
+		Config: &ir.BuildConfig{Backend: "Node with Express", Validation: "the OpenAPI spec"},
+		APIs:   []*ir.Endpoint{{Name: "CreateOrder", Params: []*ir.Param{{Name: "quantity"}}}},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := os.ReadFile(filepath.Join(dir, "openapi.yaml"))
+	if err != nil {
+		t.Fatal("missing openapi.yaml")
+	}
+	if !strings.Contains(string(spec), "openapi: 3") {
+		t.Error("openapi.yaml should be the generated OpenAPI document")
+	}
+	validation, err := os.ReadFile(filepath.Join(dir, "src", "middleware", "openapi.ts"))
+	if err != nil {
+		t.Fatal("missing src/middleware/openapi.ts")
+	}
+	if !strings.Contains(string(validation), "OpenApiValidator.middleware({") {
+		t.Error("openapi.ts should use express-openapi-validator")
+	}
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	for _, want := range []string{"import { validateRequests } from './middleware/openapi';", "app.use(validateRequests);"} {
+		if !strings.Contains(string(server), want) {
+			t.Errorf("server.ts missing %q", want)
+		}
+	}
+
+	app.Config.Validation = ""
+	dir = t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"openapi.yaml", "src/middleware/openapi.ts"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err == nil {
+			t.Errorf("%s should only be generated when the build asks for validation", rel)
+		}
+	}
+}
+
+func TestMaskingGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Clinic",
+		Config: &ir.BuildConfig{Backend: "Node with Express"},
+		Data: []*ir.DataModel{{Name: "Patient", Fields: []*ir.DataField{
+			{Name: "name", Type: "text"},
+			{Name: "email", Type: "email", Personal: true},
+			{Name: "phone_number", Type: "text", Personal: true},
+		}}},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	masking, err := os.ReadFile(filepath.Join(dir, "src", "middleware", "masking.ts"))
+	if err != nil {
+		t.Fatal("missing src/middleware/masking.ts")
+	}
+	if !strings.Contains(string(masking), "const PERSONAL_FIELDS = new Set(['email', 'phonenumber']);") {
+		t.Error("masking.ts should list the personal fields")
+	}
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	for _, want := range []string{"installLogMasking();", "app.use(maskErrorResponses);"} {
+		if !strings.Contains(string(server), want) {
+			t.Errorf("server.ts missing %q", want)
+		}
+	}
+	errors, _ := os.ReadFile(filepath.Join(dir, "src", "middleware", "errors.ts"))
+	if !strings.Contains(string(errors), "console.error('[Error]', maskText(err.message, personalValues(req)));") {
+		t.Error("errors.ts should mask personal values in logged errors")
+	}
+}
+
+func TestJobQueueGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "TaskFlow",
+		Config: &ir.BuildConfig{Backend: "Node with Express"},
+		Workflows: []*ir.Workflow{{Trigger: "a user signs up", Steps: []*ir.Action{
+			{Type: "send", Text: "send welcome email"},
+			{Type: "delay", Text: "after 3 days, send a tips email"},
+		}}},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	queue, err := os.ReadFile(filepath.Join(dir, "src", "jobs", "queue.ts"))
+	if err != nil {
+		t.Fatal("missing src/jobs/queue.ts")
+	}
+	if !strings.Contains(string(queue), "export async function enqueue(") {
+		t.Error("queue.ts should export enqueue")
+	}
+	worker, err := os.ReadFile(filepath.Join(dir, "src", "worker.ts"))
+	if err != nil {
+		t.Fatal("missing src/worker.ts")
+	}
+	for _, want := range []string{
+		"'user-signs-up': async (job) => {",
+		"    // TODO: send welcome email\n",
+		"await enqueue('user-signs-up-after-3-days', job.data, 259200000);",
+		"'user-signs-up-after-3-days': async (job) => {\n    // TODO: send a tips email\n",
+	} {
+		if !strings.Contains(string(worker), want) {
+			t.Errorf("worker.ts missing %q", want)
+		}
+	}
+}
+
+func TestChartsGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data User:
+  has a name which is text
+  has many Expense
+
+data Project:
+  has a title which is text
+
+data Product:
+  has a category which is text
+  has a current_stock which is number
+
+data Expense:
+  belongs to a User
+  belongs to a Project
+  has an amount which is decimal
+  has a date which is date
+
+page Dashboard:
+  show a bar chart of stock levels by category
+  show a pie chart of total expenses by project
+  show a line chart of expenses over the last 30 days
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Node with Express
+
+database:
+  use MySQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := os.ReadFile(filepath.Join(dir, "src", "routes", "charts.ts"))
+	if err != nil {
+		t.Fatal("missing src/routes/charts.ts")
+	}
+	for _, want := range []string{
+		"router.use(authenticate);",
+		"router.get('/stock-levels-by-category', async (_req: Request,",
+		"      by: ['category'],\n      _sum: { current_stock: true },",
+		"      by: ['projectId'],\n      where: { userId: req.userId! },\n      _sum: { amount: true },",
+		"select: { id: true, title: true },",
+		"SELECT DATE_FORMAT(\\`date\\`, '%Y-%m-%d') AS label, COUNT(*) AS value",
+		"WHERE \\`date\\` >= ${since(30)} AND \\`userId\\` = ${req.userId}",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("charts.ts missing %q", want)
+		}
+	}
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	if !strings.Contains(string(server), "app.use('/api/charts', require('./routes/charts').router);") {
+		t.Error("server.ts should mount the chart routes")
+	}
+}
+
+func TestMongoDB(t *testing.T) {
+	source := `app Shop is a web application
+
+data User:
+  has a name which is text
+  has many Expense
+
+data Expense:
+  belongs to a User
+  has an amount which is decimal
+  has a date which is date
+
+page Dashboard:
+  show a line chart of expenses over the last 30 days
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Node with Express
+
+database:
+  use MongoDB`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, _ := os.ReadFile(filepath.Join(dir, "prisma", "schema.prisma"))
+	for _, want := range []string{
+		`provider = "mongodb"`,
+		`id        String   @id @default(cuid()) @map("_id")`,
+	} {
+		if !strings.Contains(string(schema), want) {
+			t.Errorf("schema.prisma missing %q", want)
+		}
+	}
+
+	// MongoDB has no raw SQL, so time series are bucketed in memory
+	routes, _ := os.ReadFile(filepath.Join(dir, "src", "routes", "charts.ts"))
+	for _, want := range []string{
+		"import { AggregateRow, byPeriod, byValue, since } from '../services/aggregates';",
+		"where: { date: { gte: since(30) }, userId: req.userId! },",
+		"byPeriod(records.map((r) => ({ date: r.date, value: 1 })), 'day', 'count');",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("charts.ts missing %q", want)
+		}
+	}
+	if strings.Contains(string(routes), "$queryRaw") {
+		t.Error("charts.ts should not use raw SQL with MongoDB")
+	}
+	aggregates, _ := os.ReadFile(filepath.Join(dir, "src", "services", "aggregates.ts"))
+	if !strings.Contains(string(aggregates), "export function byPeriod(") {
+		t.Error("aggregates.ts should define byPeriod with MongoDB")
+	}
+}
+
+func TestReportEndpointsGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data User:
+  has a name which is text
+  has many Order
+
+data Order:
+  belongs to a User
+  has an amount which is decimal
+  has a status which is text
+
+api MonthlyRevenue: sum the amount of Orders grouped by month for the last 12 months
+
+api GetOrdersByStatus:
+  requires authentication
+  count the Orders grouped by status
+  cache for 5 minutes
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	revenue, _ := os.ReadFile(filepath.Join(dir, "src", "routes", "monthly-revenue.ts"))
+	for _, want := range []string{
+		"res.set('Cache-Control', 'public, max-age=60');",
+		`SELECT to_char(date_trunc('month', "createdAt"), 'YYYY-MM') AS label, COALESCE(SUM("amount"), 0) AS value`,
+		`WHERE "createdAt" >= ${since(360)}`,
+	} {
+		if !strings.Contains(string(revenue), want) {
+			t.Errorf("monthly-revenue.ts missing %q:\n%s", want, revenue)
+		}
+	}
+	byStatus, _ := os.ReadFile(filepath.Join(dir, "src", "routes", "get-orders-by-status.ts"))
+	for _, want := range []string{
+		"res.set('Cache-Control', 'private, max-age=300');",
+		"      by: ['status'],\n      where: { userId: req.userId! },\n      _count: { _all: true },",
+	} {
+		if !strings.Contains(string(byStatus), want) {
+			t.Errorf("get-orders-by-status.ts missing %q:\n%s", want, byStatus)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "services", "aggregates.ts")); err != nil {
+		t.Error("missing src/services/aggregates.ts")
+	}
+}
+
+func TestReorderRoutesGenerated(t *testing.T) {
+	source := `app Board is a web application
+
+data User:
+  has a name which is text
+  has many Task
+
+data Task:
+  belongs to a User
+  has a title which is text
+
+page Home:
+  show a list of tasks
+  each task shows its title
+  dragging a task reorders the list
+
+api ListTasks:
+  requires authentication
+  fetch all tasks for the current user
+  respond with tasks
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := os.ReadFile(filepath.Join(dir, "src", "routes", "reorder.ts"))
+	if err != nil {
+		t.Fatal("missing src/routes/reorder.ts")
+	}
+	for _, want := range []string{
+		"router.use(authenticate);",
+		"router.put('/tasks', async (req: Request, res: Response, next: NextFunction) => {",
+		"if (!isIdList(ids)) {",
+		"ids.map((id, position) => prisma.task.updateMany({ where: { id, userId: req.userId! }, data: { position: position } })),",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("reorder.ts missing %q", want)
+		}
+	}
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	if !strings.Contains(string(server), "app.use('/api/reorder', require('./routes/reorder').router);") {
+		t.Error("server.ts should mount the reorder routes")
+	}
+	list, _ := os.ReadFile(filepath.Join(dir, "src", "routes", "list-tasks.ts"))
+	if !strings.Contains(string(list), "orderBy: { position: 'asc' }") {
+		t.Error("ListTasks should list tasks in their dragged order")
+	}
+}
+
+func TestPaginatedListGenerated(t *testing.T) {
+	source := `app Feed is a web application
+
+data User:
+  has a name which is text
+  has many Post
+
+data Post:
+  belongs to a User
+  has a title which is text
+
+data Task:
+  has a title which is text
+
+page Home:
+  show a list of posts
+  each post shows its title
+  scrolling to bottom loads more posts
+
+page Board:
+  show a list of tasks
+  each task shows its title
+  dragging a task reorders the list
+
+api ListPosts:
+  requires authentication
+  fetch all posts for the current user
+  respond with posts
+
+api ListTasks:
+  fetch all tasks
+  paginate with 50 per page
+  respond with tasks
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	posts, err := os.ReadFile(filepath.Join(dir, "src", "routes", "list-posts.ts"))
+	if err != nil {
+		t.Fatal("missing src/routes/list-posts.ts")
+	}
+	for _, want := range []string{
+		"const limit = Math.min(Number(req.query.limit) || 20, 100);",
+		"userId: req.userId,",
+		": [{ createdAt: 'desc' }, { id: 'desc' }];",
+		"take: limit + 1,",
+		"...(sort || req.query.page ? { skip: (page - 1) * limit } : cursor ? { cursor: { id: cursor }, skip: 1 } : {}),",
+		"res.json({ data: result, pagination: { limit, nextCursor } });",
+	} {
+		if !strings.Contains(string(posts), want) {
+			t.Errorf("list-posts.ts missing %q", want)
+		}
+	}
+
+	tasks, _ := os.ReadFile(filepath.Join(dir, "src", "routes", "list-tasks.ts"))
+	for _, want := range []string{
+		"const limit = Math.min(Number(req.query.limit) || 50, 100);",
+		": [{ position: 'asc' }, { id: 'asc' }];",
+	} {
+		if !strings.Contains(string(tasks), want) {
+			t.Errorf("list-tasks.ts missing %q", want)
+		}
+	}
+}
+
+func TestPaginatedListQueryParams(t *testing.T) {
+	source := `app Tracker is a web application
+
+data Task:
+  has a title which is text
+  has a status which is either "todo" or "done"
+  has a done which is boolean
+
+api ListTasks:
+  fetch all tasks
+  support filtering by status and done
+  support searching by title
+  paginate with 20 per page
+  respond with tasks
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	route, err := os.ReadFile(filepath.Join(dir, "src", "routes", "list-tasks.ts"))
+	if err != nil {
+		t.Fatal("missing src/routes/list-tasks.ts")
+	}
+	for _, want := range []string{
+		"import { PrismaClient, Prisma } from '@prisma/client';",
+		"const sort = new Map([['title', 'title'], ['status', 'status'], ['done', 'done']]).get(String(req.query.sort));",
+		"status: (['todo', 'done'] as const).find(value => value === req.query.status),",
+		"done: req.query.done === 'true' ? true : req.query.done === 'false' ? false : undefined,",
+		"{ OR: [{ title: { contains: req.query.q, mode: 'insensitive' as const } }] }",
+		"...(sort || req.query.page ? { skip: (page - 1) * limit } : cursor ? { cursor: { id: cursor }, skip: 1 } : {}),",
+		"// support searching by title",
+	} {
+		if !strings.Contains(string(route), want) {
+			t.Errorf("list-tasks.ts missing %q:\n%s", want, route)
+		}
+	}
+	if strings.Contains(string(route), "TODO") {
+		t.Error("the searching and filtering steps are served, not left to do")
+	}
+}
+
+func TestVersionedUpdate(t *testing.T) {
+	source := `app Blog is a web application
+
+data Post:
+  has a title which is text
+  has a published which is boolean
+  protect against conflicting edits
+
+api UpdatePost:
+  accepts post_id, title, and published
+  fetch the Post by post_id
+  if post does not exist, respond with "post not found"
+  update the post with the given fields
+  respond with the updated post
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	route, err := os.ReadFile(filepath.Join(dir, "src", "routes", "update-post.ts"))
+	if err != nil {
+		t.Fatal("missing src/routes/update-post.ts")
+	}
+	for _, want := range []string{
+		"import { expectedVersion } from '../services/concurrency';",
+		"return res.status(428).json({ error: 'Send the version of the post you edited in an If-Match header' });",
+		"where: { id: post_id, version: expected },",
+		"version: { increment: 1 },",
+		"return res.status(409).json({ error: 'This post was changed by someone else', current });",
+	} {
+		if !strings.Contains(string(route), want) {
+			t.Errorf("update-post.ts missing %q:\n%s", want, route)
+		}
+	}
+	if strings.Contains(string(route), "        post_id,\n") {
+		t.Error("the record's id picks it, it isn't saved over")
+	}
+
+	helpers, err := os.ReadFile(filepath.Join(dir, "src", "services", "concurrency.ts"))
+	if err != nil {
+		t.Fatal("missing src/services/concurrency.ts")
+	}
+	if !strings.Contains(string(helpers), "req.get('If-Match')") {
+		t.Error("the expected version should be read from If-Match")
+	}
+	schema, _ := os.ReadFile(filepath.Join(dir, "prisma", "schema.prisma"))
+	if !strings.Contains(string(schema), "@default(1)") {
+		t.Errorf("Post should have a version starting at 1:\n%s", schema)
+	}
+}
+
+func TestImport(t *testing.T) {
+	source := `app Tracker is a web application
+
+data Task:
+  has a title which is text
+  has a status which is either "todo" or "done"
+  has an optional estimate which is number
+  has a done which is boolean
+
+api ImportTasks:
+  import tasks from a csv or json file
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	route, err := os.ReadFile(filepath.Join(dir, "src", "routes", "import-tasks.ts"))
+	if err != nil {
+		t.Fatal("missing src/routes/import-tasks.ts")
+	}
+	for _, want := range []string{
+		"import { ImportFileError, ImportRecord, ImportSummary, parseRecords } from '../services/imports';",
+		"records = parseRecords(req.body, req.get('Content-Type'));",
+		"return res.status(400).json({ error: err.message });",
+		"mode: 'all-or-nothing'",
+		"status: record.oneOf('status', ['todo', 'done'] as const, true),",
+		"estimate: record.number('estimate', false),",
+		"done: record.boolean('done', false) ?? false,",
+		"return res.status(422).json({ error: `Nothing was imported: ${summary.failed} of ${summary.total} records have problems`, data: summary });",
+		"await prisma.$transaction(async (tx) => {",
+	} {
+		if !strings.Contains(string(route), want) {
+			t.Errorf("import-tasks.ts missing %q:\n%s", want, route)
+		}
+	}
+
+	helpers, err := os.ReadFile(filepath.Join(dir, "src", "services", "imports.ts"))
+	if err != nil {
+		t.Fatal("missing src/services/imports.ts")
+	}
+	if !strings.Contains(string(helpers), "export class ImportRecord {") {
+		t.Error("imports.ts should export ImportRecord")
+	}
+	server, _ := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	text := strings.Index(string(server), "app.use('/api/import-tasks', express.text(")
+	if text < 0 || text > strings.Index(string(server), "express.json()") {
+		t.Errorf("the import's file should be read as text before JSON bodies are parsed:\n%s", server)
+	}
+}
+
+func TestTimesInUTC(t *testing.T) {
+	source := `app Planner is a web application
+
+data Event:
+  has a title which is text
+  has a starts which is datetime
+  has an optional day which is date
+
+api CreateEvent:
+  accepts title, starts, and day
+  create an event with the given fields
+  respond with the created event
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL
+  store all times in UTC and display in the user's timezone`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := os.ReadFile(filepath.Join(dir, "prisma", "schema.prisma"))
+	if err != nil {
+		t.Fatal("missing prisma/schema.prisma")
+	}
+	for _, want := range []string{
+		"starts    DateTime @db.Timestamptz(3)",
+		"day       DateTime? @db.Date",
+		"createdAt DateTime @default(now()) @db.Timestamptz(3)",
+	} {
+		if !strings.Contains(string(schema), want) {
+			t.Errorf("schema.prisma missing %q:\n%s", want, schema)
+		}
+	}
+	server, err := os.ReadFile(filepath.Join(dir, "src", "server.ts"))
+	if err != nil {
+		t.Fatal("missing src/server.ts")
+	}
+	if !strings.Contains(string(server), "process.env.TZ = 'UTC';") {
+		t.Errorf("server.ts should run in UTC:\n%s", server)
+	}
+}
+
+func TestAccountEndpoints(t *testing.T) {
+	source := `app Notes is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+  has an optional bio which is text
+
+accounts:
+  users can update their profile, change password, and delete their account
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"get-profile.ts": {
+			"const user = await prisma.user.findUnique({ where: { id: req.userId! } });",
+			"const { password: _password, ...profile } = user;",
+		},
+		"update-profile.ts": {
+			"if (await prisma.user.findFirst({ where: { email, NOT: { id: req.userId! } } })) {",
+			"return res.status(409).json({ error: 'email is already taken' });",
+			"data: { name, email, bio },",
+		},
+		"change-password.ts": {
+			"if (!new_password || new_password.length < 8) {",
+			"if (!(await bcrypt.compare(current_password, user.password))) {",
+			"data: { password: await bcrypt.hash(new_password, 12) },",
+			"return res.status(204).end();",
+		},
+		"delete-account.ts": {
+			"router.delete('/',",
+			"const { password } = req.body;",
+			"return res.status(403).json({ error: 'Your password is incorrect' });",
+			"await prisma.user.delete({ where: { id: user.id } });",
+		},
+	} {
+		route, err := os.ReadFile(filepath.Join(dir, "src", "routes", file))
+		if err != nil {
+			t.Fatalf("missing src/routes/%s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(route), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, route)
+			}
+		}
+	}
+}
+
+func TestInvitationEndpoints(t *testing.T) {
+	source := `app Teams is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+  has a role which is text
+
+data Organization:
+  has a name which is text
+
+accounts:
+  users can invite teammates by email to their Organization
+  invitations expire in 3 days
+
+policy Admin:
+  can invite teammates
+
+policy Member:
+  can view tasks
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+integrate with SendGrid:
+  api key from environment variable SENDGRID_API_KEY
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"invite-teammate.ts": {
+			"authorize('invite', 'teammate'),",
+			"const membership = await prisma.membership.findFirst({ where: { userId: req.userId! } });",
+			"if (!['Admin', 'Member'].includes(invitedRole)) {",
+			"token: randomBytes(32).toString('hex'),",
+			"expires: new Date(Date.now() + 3 * 24 * 60 * 60 * 1000),",
+			"/accept-invite?token=${invitation.token}",
+			"await sendEmail({",
+		},
+		"get-invitation.ts": {
+			"router.get('/', async",
+			"const token = String(req.query.token ?? '');",
+			"return res.status(410).json({ error: 'This invitation has expired' });",
+		},
+		"accept-invitation.ts": {
+			"return res.status(403).json({ error: `This invitation is for ${invitation.email}` });",
+			"await tx.user.update({ where: { id: req.userId! }, data: { role: invitation.role } });",
+			"return tx.membership.create({ data: { role: invitation.role, userId: req.userId!, organizationId: invitation.organizationId } });",
+		},
+	} {
+		route, err := os.ReadFile(filepath.Join(dir, "src", "routes", file))
+		if err != nil {
+			t.Fatalf("missing src/routes/%s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(route), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, route)
+			}
+		}
+	}
+	schema, _ := os.ReadFile(filepath.Join(dir, "prisma", "schema.prisma"))
+	for _, want := range []string{"model Invitation {", "model Membership {"} {
+		if !strings.Contains(string(schema), want) {
+			t.Errorf("schema.prisma missing %q", want)
+		}
+	}
+}
+
+const billingSource = `app Boards is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+
+data Project:
+  belongs to a User
+  has a name which is text
+
+plans: Free, Pro ($19/month), Enterprise
+
+policy FreePlan:
+  can create up to 3 projects per month
+  cannot export data
+
+api CreateProject:
+  requires authentication
+  accepts name
+  create a Project with the given fields
+  respond with the created project
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+integrate with Stripe:
+  api key from environment variable STRIPE_SECRET_KEY
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+func TestBillingEndpoints(t *testing.T) {
+	prog, err := parser.Parse(billingSource)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"src/routes/billing.ts": {
+			"router.post('/checkout', authenticate,",
+			"mode: 'subscription',",
+			"customer_email: user?.email",
+			"subscription_data: { metadata: { userId: req.userId! } },",
+			"stripe.billingPortal.sessions.create(",
+			"stripe.webhooks.constructEvent(req.body, req.headers['stripe-signature'] as string,",
+			"case 'customer.subscription.deleted':",
+			"/pricing`;",
+		},
+		"src/services/billing.ts": {
+			"export const FREE_PLAN: string | null = 'Free';",
+			"prisma.plan.findFirst({ where: { stripe_price_id: priceId } })",
+			"create: { ...data, planId: plan.id, userId, stripe_subscription_id: subscription.id },",
+		},
+		"src/middleware/plans.ts": {
+			"'Free': 'FreePlan',",
+			"prisma.project.count({ where: { userId, ...(since && { createdAt: { gte: since } }) } }),",
+			"return res.status(402).json(",
+		},
+		"src/routes/create-project.ts": {
+			"import { requirePlan } from '../middleware/plans';",
+			"requirePlan('create', 'project'),",
+		},
+		"src/scripts/sync-plans.ts": {
+			"{ name: 'Free', price: 0, interval: null },",
+			"{ name: 'Pro', price: 1900, interval: 'month' },",
+			"currency: 'usd',",
+			"await prisma.plan.upsert(",
+		},
+		"src/server.ts": {
+			"app.use('/api/billing/webhook', express.raw({ type: 'application/json' }));",
+			"app.use('/api/billing', require('./routes/billing').router);",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+	schema, _ := os.ReadFile(filepath.Join(dir, "prisma", "schema.prisma"))
+	for _, want := range []string{"model Plan {", "model Subscription {", "stripe_subscription_id String @unique"} {
+		if !strings.Contains(string(schema), want) {
+			t.Errorf("schema.prisma missing %q", want)
+		}
+	}
+}
+
+func TestUsageMetering(t *testing.T) {
+	source := strings.Replace(billingSource, "  cannot export data", "  cannot export data\n  can make up to 1000 API calls per day", 1) +
+		"\n\nmeter API calls per user per day"
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"src/services/usage.ts": {
+			"export const METER_PERIOD: Period = 'day';",
+			"'Free': { limit: 1000, period: 'day' },",
+			"const plan = await currentPlan(userId);",
+			"data: { calls: { increment: 1 } },",
+			"await prisma.usageRecord.create({ data: { userId, period_start, calls: 1 } });",
+			"stripe.billing.meterEvents.create({",
+		},
+		"src/middleware/metering.ts": {
+			"return res.status(429).json(",
+			"res.setHeader('Retry-After',",
+			"reportCall(req.userId!).catch(",
+		},
+		"src/routes/usage.ts": {
+			"router.get('/', authenticate,",
+			"router.get('/history', authenticate,",
+		},
+		"src/routes/create-project.ts": {
+			"import { meter } from '../middleware/metering';",
+			"authenticate,\n  meter,",
+		},
+		"src/server.ts": {
+			"app.use('/api/usage', require('./routes/usage').router);",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+	schema, _ := os.ReadFile(filepath.Join(dir, "prisma", "schema.prisma"))
+	if !strings.Contains(string(schema), "model UsageRecord {") {
+		t.Error("schema.prisma missing the UsageRecord model")
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	source := strings.Replace(billingSource, "  method JWT tokens that expire in 7 days", "  method JWT tokens that expire in 7 days\n  external clients authenticate with API keys", 1)
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"src/middleware/auth.ts": {
+			"import { allows, findApiKey } from '../services/apiKeys';",
+			"apiKeyId?: string;",
+			"const key = req.header('X-API-Key');",
+			"return res.status(403).json({ error: 'This API key can only read' });",
+			"const payload = jwt.verify(token, JWT_SECRET)",
+		},
+		"src/services/apiKeys.ts": {
+			"return createHash('sha256').update(key).digest('hex');",
+			"const key = 'hk_' + randomBytes(24).toString('base64url');",
+			"where: { key_hash: hashApiKey(key) }, include: { user: true }",
+			"data: { last_used_at: new Date() }",
+		},
+		"src/routes/apiKeys.ts": {
+			"router.get('/', authenticate, signedIn,",
+			"router.post('/', authenticate, signedIn,",
+			"router.delete('/:id', authenticate, signedIn,",
+			"key_hash: hashApiKey(key)",
+			"res.status(201).json({ data: { ...present(apiKey), key } });",
+		},
+		"src/server.ts": {
+			"app.use('/api/keys', require('./routes/apiKeys').router);",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+	schema, _ := os.ReadFile(filepath.Join(dir, "prisma", "schema.prisma"))
+	if !strings.Contains(string(schema), "key_hash  String @unique") {
+		t.Errorf("schema.prisma missing the ApiKey model's unique hash:\n%s", schema)
+	}
+}
+
+func TestChaos(t *testing.T) {
+	source := `app Shop is a web application
+
+data Order:
+  has a total which is number
+
+api ListOrders:
+  fetch all orders
+  respond with orders
+
+if database is unreachable:
+  retry 3 times with 2 second delay
+  if still failing, respond with "service temporarily unavailable"
+  alert the engineering team via Slack
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"src/middleware/chaos.ts": {
+			"const ENABLED = ['1', 'true'].includes(process.env.HUMAN_CHAOS ?? '');",
+			"condition: 'database is unreachable',",
+			"attempts: 3,",
+			"delayMs: 2000,",
+			"status: 503,",
+			"message: 'Service temporarily unavailable',",
+			"alert: 'alert the engineering team via Slack',",
+			"res.set('X-Chaos', fault.condition);",
+		},
+		"src/server.ts": {
+			"import { chaos } from './middleware/chaos';",
+			"app.use('/api', chaos);",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+
+	// Without error handlers there's nothing to inject
+	app.ErrorHandlers = nil
+	plain := t.TempDir()
+	if err := (Generator{}).Generate(app, plain); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(plain, "src", "middleware", "chaos.ts")); err == nil {
+		t.Error("chaos middleware generated without error handlers")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	source := `app Shop is a web application
+
+data Order:
+  has a total which is number
+
+api ListOrders:
+  fetch all orders
+  respond with orders
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"src/middleware/record.ts": {
+			"const DIR = process.env.HUMAN_RECORD_DIR;",
+			"'###',",
+			"`# Response: ${res.statusCode} (${Date.now() - recordedAt.getTime()} ms)`,",
+			"`${req.method} {{host}}${req.originalUrl}`,",
+		},
+		"src/server.ts": {
+			"import { record } from './middleware/record';",
+			"app.use(express.json());\napp.use('/api', record);",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+}
+
+func TestBuildHeader(t *testing.T) {
+	prog, err := parser.Parse(`app Shop is a web application
+
+data Order:
+  has a total which is number
+
+api ListOrders:
+  fetch all orders
+  respond with orders
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	// Only a build has provenance to send
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "src/server.ts"))
+	if strings.Contains(string(content), "X-Human-Build") {
+		t.Errorf("src/server.ts should not send X-Human-Build without a build")
+	}
+
+	app.Build = &ir.BuildInfo{Compiler: "0.4.0", IRHash: "sha256:abc", GeneratedAt: "2026-03-01T12:00:00Z"}
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	content, _ = os.ReadFile(filepath.Join(dir, "src/server.ts"))
+	want := "// Middleware\napp.use((_req, res, next) => {\n  res.setHeader('X-Human-Build', 'human/0.4.0; ir=sha256:abc; generated=2026-03-01T12:00:00Z');\n  next();\n});\napp.use(cors());"
+	if !strings.Contains(string(content), want) {
+		t.Errorf("src/server.ts missing %q:\n%s", want, content)
+	}
+}
+
+func TestModelHooks(t *testing.T) {
+	source := `app Tasks is a web application
+
+data User:
+  has an email which is unique email
+
+data Session:
+  belongs to a User
+  has a token which is text
+
+data Task:
+  belongs to a User
+  has a status which is text
+  has a priority which is number
+
+before a Task is created, set its status to "pending"
+before a Task is updated:
+  set its priority to 2
+  notify the owner
+after a User is deleted, remove their Sessions
+
+api CreateTask:
+  accepts status
+  create a Task with the given fields
+  respond with the created task
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"src/services/hooks.ts": {
+			"export const modelHooks = Prisma.defineExtension((client) =>",
+			"      task: {\n        async create({ args, query }) {\n          // before a Task is created\n          (args.data as any).status = 'pending';\n          return query(args);",
+			"(args.data as any).priority = 2;",
+			"// TODO: notify the owner",
+			"const record = await client.user.findUnique({ where: args.where });",
+			"await client.session.deleteMany({ where: { userId: record.id } });",
+		},
+		"src/routes/create-task.ts": {
+			"import { modelHooks } from '../services/hooks';",
+			"const prisma = new PrismaClient().$extends(modelHooks);",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+
+	// Without hooks the client isn't extended
+	app.Hooks = nil
+	plain := t.TempDir()
+	if err := (Generator{}).Generate(app, plain); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(plain, "src", "services", "hooks.ts")); err == nil {
+		t.Error("hooks generated without model hooks")
+	}
+}
+
+func TestCORSOrigin(t *testing.T) {
+	app := &ir.Application{
+		Name:         "Shop",
+		Config:       &ir.BuildConfig{Backend: "Node with Express"},
+		Environments: []*ir.Environment{{Name: "production", Config: map[string]string{"url": "shop example com"}}},
+	}
+	if server := generateServer(app); !strings.Contains(server, "app.use(cors());\n") {
+		t.Error("a local build should accept requests from any origin")
+	}
+	app.Config.Env = "production"
+	if server := generateServer(app); !strings.Contains(server, "app.use(cors({ origin: 'https://shop.example.com', credentials: true }));\n") {
+		t.Errorf("a production build should only accept its frontend:\n%s", server)
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{
+			{Name: "User", Fields: []*ir.DataField{
+				{Name: "email", Type: "email", Required: true},
+				{Name: "password", Type: "text", Required: true},
+			}},
+			{Name: "Task", Fields: []*ir.DataField{
+				{Name: "title", Type: "text", Required: true},
+				{Name: "due", Type: "date"},
+				{Name: "created", Type: "datetime"},
+			}, Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}}},
+		},
+		Seeds: []*ir.Seed{{Model: "User", Count: 2}, {Model: "Task", Count: 3}},
+	}
+
+	out := generateSeed(app)
+	for _, want := range []string{
+		`// Seeded users sign in with the password "password123".`,
+		"import bcrypt from 'bcryptjs';",
+		"const password = await bcrypt.hash('password123', 12);",
+		"{ id: '00000001-0000-4000-8000-000000000002', email: ",
+		"', password },",
+		"due: new Date('2025-",
+		"userId: '00000001-0000-4000-8000-00000000000",
+		"await prisma.task.upsert({ where: { id: data.id }, update: data, create: data });",
+		"console.log(`Seeded ${tasks.length} tasks`);",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("seed.ts missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "000000000003', email") || strings.Contains(out, "created:") {
+		t.Errorf("expected 2 users and no created field:\n%s", out)
+	}
+	if strings.Index(out, "prisma.user.upsert") > strings.Index(out, "prisma.task.upsert") {
+		t.Error("users must be seeded before the tasks belonging to them")
+	}
+}
+
+func TestGenerateMigrations(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{
+			{Name: "User", Fields: []*ir.DataField{{Name: "email", Type: "email", Required: true, Unique: true}}},
+			{Name: "Task", Fields: []*ir.DataField{
+				{Name: "title", Type: "text", Required: true},
+				{Name: "status", Type: "enum", EnumValues: []string{"open", "done"}},
+			}, Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}}},
+		},
+	}
+	files := generateMigrations(app)
+	if !strings.Contains(files["prisma/migrations/migration_lock.toml"], `provider = "postgresql"`) {
+		t.Errorf("missing migration_lock.toml: %v", files)
+	}
+	sql := files["prisma/migrations/0001_initial/migration.sql"]
+	for _, want := range []string{
+		"-- Migration: 0001_initial",
+		"CREATE TABLE \"User\" (\n    \"id\" TEXT NOT NULL,\n    \"email\" TEXT NOT NULL,",
+		"CREATE UNIQUE INDEX \"User_email_key\" ON \"User\" (\"email\");",
+		"CREATE TYPE \"TaskStatus\" AS ENUM ('open', 'done');",
+		"\"createdAt\" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP",
+		"CONSTRAINT \"Task_pkey\" PRIMARY KEY (\"id\")",
+		"ADD CONSTRAINT \"Task_userId_fkey\" FOREIGN KEY (\"userId\") REFERENCES \"User\" (\"id\")",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("migration.sql missing %q:\n%s", want, sql)
+		}
+	}
+
+	app.Database = &ir.DatabaseConfig{Engine: "MySQL"}
+	if files := generateMigrations(app); len(files) != 0 {
+		t.Errorf("expected no migrations for MySQL, got %v", files)
+	}
+}
+
+func TestEnvCheck(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Shop",
+		Config: &ir.BuildConfig{Backend: "Node with Express"},
+		Integrations: []*ir.Integration{
+			{Service: "SendGrid", Type: "email", Credentials: map[string]string{"api key": "SENDGRID_API_KEY"}},
+		},
+	}
+	check := generateEnvCheck(app)
+	for _, want := range []string{
+		"{ name: 'DATABASE_URL', kind: 'url', required: true },",
+		"{ name: 'SENDGRID_API_KEY', kind: 'text', requiredInProduction: true },",
+		"const production = process.env.NODE_ENV === 'production';",
+		"process.exit(1);",
+	} {
+		if !strings.Contains(check, want) {
+			t.Errorf("env.ts missing %q", want)
+		}
+	}
+	if server := generateServer(app); !strings.Contains(server, "do not edit\n\nimport './env';\nimport express") {
+		t.Error("server.ts should import the env check first")
+	}
+}
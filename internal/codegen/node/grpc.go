@@ -0,0 +1,169 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/grpc"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateGrpcServer produces src/grpc/server.ts, a gRPC server for the
+// proto in proto/. Each RPC is forwarded to the Express app over loopback,
+// so it runs exactly the same middleware, validation, and business steps
+// as the REST route.
+func generateGrpcServer(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import http from 'http';\n")
+	b.WriteString("import path from 'path';\n")
+	b.WriteString("import { AddressInfo } from 'net';\n")
+	b.WriteString("import type { Express } from 'express';\n")
+	b.WriteString("import * as grpc from '@grpc/grpc-js';\n")
+	b.WriteString("import * as protoLoader from '@grpc/proto-loader';\n\n")
+
+	fmt.Fprintf(&b, "const PROTO_PATH = path.resolve(__dirname, '../../proto/%s');\n", grpc.ProtoPath(app))
+	fmt.Fprintf(&b, "const GRPC_PORT = process.env.GRPC_PORT || %d;\n\n", grpc.DefaultPort)
+
+	b.WriteString("interface Route {\n")
+	b.WriteString("  method: 'GET' | 'POST' | 'PUT' | 'DELETE';\n")
+	b.WriteString("  path: string;\n")
+	b.WriteString("  // [request field, REST parameter]\n")
+	b.WriteString("  params: [string, string][];\n")
+	b.WriteString("  // Response data has no model and is sent as a JSON string.\n")
+	b.WriteString("  jsonData?: boolean;\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("const routes: Record<string, Route> = {\n")
+	for _, rpc := range grpc.RPCs(app) {
+		ep := rpc.Endpoint
+		var params []string
+		for _, f := range rpc.Fields {
+			params = append(params, fmt.Sprintf("['%s', '%s']", f.JSONName(), sanitizeParamName(f.Param)))
+		}
+		jsonData := ""
+		if rpc.JSONData() {
+			jsonData = ", jsonData: true"
+		}
+		fmt.Fprintf(&b, "  %s: { method: '%s', path: '/api%s', params: [%s]%s },\n",
+			rpc.Name, strings.ToUpper(httpMethod(ep.Name)), routePath(ep.Name), strings.Join(params, ", "), jsonData)
+	}
+	b.WriteString("};\n\n")
+
+	jsonFields := grpc.JSONFields(app)
+	quoted := make([]string, len(jsonFields))
+	for i, f := range jsonFields {
+		quoted[i] = "'" + f + "'"
+	}
+	b.WriteString("// json model fields travel as JSON-encoded strings in proto messages.\n")
+	fmt.Fprintf(&b, "const JSON_FIELDS = new Set<string>([%s]);\n\n", strings.Join(quoted, ", "))
+
+	b.WriteString(`const STATUS_CODES: Record<number, grpc.status> = {
+  400: grpc.status.INVALID_ARGUMENT,
+  401: grpc.status.UNAUTHENTICATED,
+  403: grpc.status.PERMISSION_DENIED,
+  404: grpc.status.NOT_FOUND,
+  409: grpc.status.ALREADY_EXISTS,
+  429: grpc.status.RESOURCE_EXHAUSTED,
+};
+
+function encodeJsonFields(value: unknown): unknown {
+  if (Array.isArray(value)) return value.map(encodeJsonFields);
+  if (value === null || typeof value !== 'object' || value instanceof Date) return value;
+  const out: Record<string, unknown> = {};
+  for (const [key, v] of Object.entries(value)) {
+    out[key] = JSON_FIELDS.has(key) && typeof v !== 'string' ? JSON.stringify(v) : encodeJsonFields(v);
+  }
+  return out;
+}
+
+function forward(port: number, route: Route, request: Record<string, unknown>, metadata: grpc.Metadata): Promise<Record<string, unknown>> {
+  const params: Record<string, unknown> = {};
+  for (const [field, key] of route.params) {
+    params[key] = request[field];
+  }
+
+  let requestPath = '/api' + route.path;
+  let body: string | undefined;
+  if (route.method === 'GET' || route.method === 'DELETE') {
+    const query = new URLSearchParams();
+    for (const [key, value] of Object.entries(params)) {
+      if (value !== undefined && value !== '') query.set(key, String(value));
+    }
+    if ([...query].length > 0) requestPath += '?' + query.toString();
+  } else {
+    body = JSON.stringify(params);
+  }
+
+  const headers: http.OutgoingHttpHeaders = { 'Content-Type': 'application/json' };
+  const authorization = metadata.get('authorization')[0];
+  if (authorization) headers.Authorization = String(authorization);
+  if (body) headers['Content-Length'] = Buffer.byteLength(body);
+
+  return new Promise((resolve, reject) => {
+    const req = http.request({ host: '127.0.0.1', port, path: requestPath, method: route.method, headers }, (res) => {
+      const chunks: Buffer[] = [];
+      res.on('data', (chunk) => chunks.push(chunk));
+      res.on('end', () => {
+        let payload: Record<string, unknown> = {};
+        try {
+          payload = JSON.parse(Buffer.concat(chunks).toString() || '{}');
+        } catch {
+          // Non-JSON bodies only come from errors; the status code is enough.
+        }
+        const status = res.statusCode ?? 500;
+        if (status >= 400) {
+          const message = typeof payload.error === 'string' ? payload.error : http.STATUS_CODES[status];
+          reject({ code: STATUS_CODES[status] ?? grpc.status.INTERNAL, details: message });
+          return;
+        }
+        const data = route.jsonData ? JSON.stringify(payload.data ?? null) : encodeJsonFields(payload.data);
+        resolve({ ...payload, data });
+      });
+    });
+    req.on('error', (err) => reject({ code: grpc.status.UNAVAILABLE, details: err.message }));
+    if (body) req.write(body);
+    req.end();
+  });
+}
+
+/**
+ * Starts the gRPC server. The Express app is bound to an ephemeral loopback
+ * port that only this server talks to; the public REST port is unaffected.
+ */
+export function startGrpcServer(app: Express): Promise<grpc.Server> {
+  const definition = protoLoader.loadSync(PROTO_PATH, { longs: Number, enums: String, defaults: false });
+  const proto = grpc.loadPackageDefinition(definition) as any;
+`)
+	fmt.Fprintf(&b, "  const service = proto.%s.%s.service;\n\n", grpc.PackageName(app), grpc.ServiceName(app))
+	b.WriteString(`  return new Promise((resolve, reject) => {
+    const internal = app.listen(0, '127.0.0.1', () => {
+      const { port } = internal.address() as AddressInfo;
+
+      const handlers: grpc.UntypedServiceImplementation = {};
+      for (const [name, route] of Object.entries(routes)) {
+        handlers[name] = (call: grpc.ServerUnaryCall<any, any>, callback: grpc.sendUnaryData<any>) => {
+          forward(port, route, call.request, call.metadata)
+            .then((payload) => callback(null, payload))
+            .catch((err) => callback(err));
+        };
+      }
+
+      const server = new grpc.Server();
+      server.addService(service, handlers);
+      server.bindAsync(` + "`0.0.0.0:${GRPC_PORT}`" + `, grpc.ServerCredentials.createInsecure(), (err) => {
+        if (err) {
+          reject(err);
+          return;
+        }
+        console.log(` + "`gRPC server running on port ${GRPC_PORT}`" + `);
+        resolve(server);
+      });
+    });
+  });
+}
+`)
+
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// hookOperations are the Prisma operations model hooks run on, by event.
+var hookOperations = []struct{ event, operation string }{
+	{"create", "create"},
+	{"update", "update"},
+	{"delete", "delete"},
+}
+
+// generateModelHooks produces src/services/hooks.ts: the models' lifecycle
+// hooks as a Prisma client extension, so they run on every write of the
+// model whichever route makes it.
+func generateModelHooks(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n")
+	b.WriteString("//\n")
+	b.WriteString("// Model lifecycle hooks. They run on create, update, and delete of a\n")
+	b.WriteString("// single record; createMany, updateMany, and deleteMany skip them.\n\n")
+	b.WriteString("import { Prisma } from '@prisma/client';\n\n")
+	b.WriteString("export const modelHooks = Prisma.defineExtension((client) =>\n")
+	b.WriteString("  client.$extends({\n")
+	b.WriteString("    query: {\n")
+	for _, m := range app.Data {
+		hooks := ir.HooksOn(app, m)
+		if len(hooks) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "      %s: {\n", toCamelCase(m.Name))
+		for _, op := range hookOperations {
+			writeHookOperation(&b, app, m, hooks, op.event, op.operation)
+		}
+		b.WriteString("      },\n")
+	}
+	b.WriteString("    },\n")
+	b.WriteString("  }),\n")
+	b.WriteString(");\n")
+	return b.String()
+}
+
+// writeHookOperation writes the query override running model m's hooks on
+// one event, or nothing when none run on it.
+func writeHookOperation(b *strings.Builder, app *ir.Application, m *ir.DataModel, hooks []*ir.ModelHook, event, operation string) {
+	var before, removals, after []string
+	for _, hook := range hooks {
+		if hook.Event != event {
+			continue
+		}
+		comment := fmt.Sprintf("// %s a %s is %sd", hook.Timing, m.Name, event)
+		var lines, removes []string
+		for _, step := range hook.Steps {
+			s := ir.ResolveHookStep(app, hook, m, step)
+			switch {
+			case s == nil:
+				lines = append(lines, "// TODO: "+step.Text)
+			case s.Field != nil:
+				value := s.Value
+				if s.Quoted() {
+					value = "'" + escapeQuote(value) + "'"
+				}
+				lines = append(lines, fmt.Sprintf("(args.data as any).%s = %s;", resolvePrismaFieldName(s.Field.Name, m), value))
+			case s.Remove != nil:
+				removes = append(removes, fmt.Sprintf("await client.%s.deleteMany({ where: { %sId: record.id } });", toCamelCase(s.Remove.Name), toCamelCase(m.Name)))
+			}
+		}
+		if len(removes) > 0 {
+			removals = append(append(removals, comment), removes...)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if hook.Timing == "before" {
+			before = append(append(before, comment), lines...)
+		} else {
+			after = append(append(after, comment), lines...)
+		}
+	}
+	if len(before)+len(removals)+len(after) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "        async %s({ args, query }) {\n", operation)
+	for _, line := range before {
+		fmt.Fprintf(b, "          %s\n", line)
+	}
+	if len(removals) > 0 {
+		b.WriteString("          // Records belonging to this one go first: their foreign keys would\n")
+		b.WriteString("          // block the delete.\n")
+		fmt.Fprintf(b, "          const record = await client.%s.findUnique({ where: args.where });\n", toCamelCase(m.Name))
+		b.WriteString("          if (record) {\n")
+		for _, line := range removals {
+			fmt.Fprintf(b, "            %s\n", line)
+		}
+		b.WriteString("          }\n")
+	}
+	if len(after) == 0 {
+		b.WriteString("          return query(args);\n")
+	} else {
+		b.WriteString("          const result = await query(args);\n")
+		for _, line := range after {
+			fmt.Fprintf(b, "          %s\n", line)
+		}
+		b.WriteString("          return result;\n")
+	}
+	b.WriteString("        },\n")
+}
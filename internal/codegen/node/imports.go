@@ -0,0 +1,319 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateImportHelpers produces src/services/imports.ts: reading an
+// imported file's records and checking their fields.
+func generateImportHelpers() string {
+	return `// Generated by Human compiler — do not edit
+
+// A problem with one record of an imported file. Records are numbered from
+// 1, not counting a CSV file's header line.
+export interface ImportError {
+  row: number;
+  field?: string;
+  message: string;
+}
+
+// What an import did, or on a dry run would do.
+export interface ImportSummary {
+  mode: 'all-or-nothing' | 'continue-on-error';
+  dryRun: boolean;
+  total: number;
+  imported: number;
+  failed: number;
+  errors: ImportError[];
+}
+
+// A file that can't be read as records at all.
+export class ImportFileError extends Error {}
+
+// columnKey normalizes a column name, so "Due date", "due_date", and
+// "dueDate" name the same field.
+function columnKey(name: string): string {
+  return name.toLowerCase().replace(/[^a-z0-9]/g, '');
+}
+
+// parseCsv splits CSV text into rows of cells. Cells may be quoted, and a
+// quoted cell may hold commas, line breaks, and "" for a quote.
+function parseCsv(text: string): string[][] {
+  const rows: string[][] = [];
+  let row: string[] = [];
+  let cell = '';
+  let quoted = false;
+  for (let i = 0; i < text.length; i++) {
+    const c = text[i];
+    if (quoted) {
+      if (c === '"' && text[i + 1] === '"') {
+        cell += '"';
+        i++;
+      } else if (c === '"') {
+        quoted = false;
+      } else {
+        cell += c;
+      }
+    } else if (c === '"') {
+      quoted = true;
+    } else if (c === ',') {
+      row.push(cell);
+      cell = '';
+    } else if (c === '\n' || c === '\r') {
+      if (c === '\r' && text[i + 1] === '\n') i++;
+      row.push(cell);
+      rows.push(row);
+      row = [];
+      cell = '';
+    } else {
+      cell += c;
+    }
+  }
+  if (cell !== '' || row.length > 0) {
+    row.push(cell);
+    rows.push(row);
+  }
+  return rows.filter(r => r.some(c => c.trim() !== ''));
+}
+
+// parseRecords reads an imported file's records, each value as text: a JSON
+// array of objects, or CSV with a header row.
+export function parseRecords(body: unknown, contentType = ''): Record<string, string>[] {
+  if (typeof body !== 'string') {
+    throw new ImportFileError('Send the file as text/csv or application/json');
+  }
+  const text = body.replace(/^\uFEFF/, '');
+  if (contentType.includes('json') || text.trimStart().startsWith('[')) {
+    let parsed: unknown;
+    try {
+      parsed = JSON.parse(text);
+    } catch {
+      throw new ImportFileError('The file is not valid JSON');
+    }
+    if (!Array.isArray(parsed)) {
+      throw new ImportFileError('The file should hold a JSON array of records');
+    }
+    return parsed.map(record => {
+      const values: Record<string, string> = {};
+      if (record && typeof record === 'object' && !Array.isArray(record)) {
+        for (const [key, value] of Object.entries(record)) {
+          if (value !== null && value !== undefined) {
+            values[columnKey(key)] = typeof value === 'object' ? JSON.stringify(value) : String(value);
+          }
+        }
+      }
+      return values;
+    });
+  }
+  const [header, ...rows] = parseCsv(text);
+  if (!header) return [];
+  const keys = header.map(columnKey);
+  return rows.map(row => {
+    const values: Record<string, string> = {};
+    keys.forEach((key, i) => {
+      if (row[i] !== undefined) values[key] = row[i];
+    });
+    return values;
+  });
+}
+
+// ImportRecord reads the fields of one imported record, noting each
+// problem. A field with a problem reads as undefined.
+export class ImportRecord {
+  readonly errors: ImportError[] = [];
+
+  constructor(private readonly values: Record<string, string>, readonly row: number) {}
+
+  private value(field: string, required: boolean): string | undefined {
+    const value = this.values[columnKey(field)]?.trim();
+    if (value) return value;
+    if (required) this.problem(field, ` + "`${field} is required`" + `);
+    return undefined;
+  }
+
+  private problem(field: string, message: string): undefined {
+    this.errors.push({ row: this.row, field, message });
+    return undefined;
+  }
+
+  text(field: string, required: boolean): string | undefined {
+    return this.value(field, required);
+  }
+
+  email(field: string, required: boolean): string | undefined {
+    const value = this.value(field, required);
+    if (value !== undefined && !/^[^\s@]+@[^\s@]+\.[^\s@]+$/.test(value)) {
+      return this.problem(field, ` + "`${field} must be an email address`" + `);
+    }
+    return value;
+  }
+
+  url(field: string, required: boolean): string | undefined {
+    const value = this.value(field, required);
+    if (value === undefined) return undefined;
+    try {
+      new URL(value);
+      return value;
+    } catch {
+      return this.problem(field, ` + "`${field} must be a URL`" + `);
+    }
+  }
+
+  number(field: string, required: boolean): number | undefined {
+    const value = this.value(field, required);
+    if (value === undefined) return undefined;
+    const n = Number(value);
+    return Number.isInteger(n) ? n : this.problem(field, ` + "`${field} must be a whole number`" + `);
+  }
+
+  decimal(field: string, required: boolean): number | undefined {
+    const value = this.value(field, required);
+    if (value === undefined) return undefined;
+    const n = Number(value);
+    return Number.isFinite(n) ? n : this.problem(field, ` + "`${field} must be a number`" + `);
+  }
+
+  boolean(field: string, required: boolean): boolean | undefined {
+    const value = this.value(field, required)?.toLowerCase();
+    if (value === undefined) return undefined;
+    if (['true', 'yes', '1'].includes(value)) return true;
+    if (['false', 'no', '0'].includes(value)) return false;
+    return this.problem(field, ` + "`${field} must be true or false`" + `);
+  }
+
+  date(field: string, required: boolean): Date | undefined {
+    const value = this.value(field, required);
+    if (value === undefined) return undefined;
+    const date = new Date(value);
+    return Number.isNaN(date.getTime()) ? this.problem(field, ` + "`${field} must be a date, e.g. 2024-01-31`" + `) : date;
+  }
+
+  oneOf<T extends string>(field: string, values: readonly T[], required: boolean): T | undefined {
+    const value = this.value(field, required);
+    if (value === undefined) return undefined;
+    return values.find(v => v.toLowerCase() === value.toLowerCase()) ?? this.problem(field, ` + "`${field} must be one of ${values.join(', ')}`" + `);
+  }
+
+  json(field: string, required: boolean): unknown {
+    const value = this.value(field, required);
+    if (value === undefined) return undefined;
+    try {
+      return JSON.parse(value);
+    } catch {
+      return this.problem(field, ` + "`${field} must be JSON`" + `);
+    }
+  }
+}
+`
+}
+
+// writeImportBody emits the body of an import route. Each record of the
+// file is checked against the model's fields; records with problems are
+// reported by row and field. An all-or-nothing import saves nothing unless
+// every record is valid, and saves them in one transaction; one that
+// continues on error saves each valid record it can. ?dry_run=true only
+// checks the file.
+func writeImportBody(b *strings.Builder, ep *ir.Endpoint, app *ir.Application) {
+	im := ep.Import
+	m := findModel(im.Model, app)
+	modelCamel := toCamelCase(m.Name)
+	scoped := ep.Auth && modelBelongsToUser(m.Name, app) && !strings.EqualFold(m.Name, "User")
+
+	b.WriteString("    const dryRun = req.query.dry_run === 'true';\n")
+	b.WriteString("    let records: Record<string, string>[];\n")
+	b.WriteString("    try {\n")
+	b.WriteString("      records = parseRecords(req.body, req.get('Content-Type'));\n")
+	b.WriteString("    } catch (err) {\n")
+	b.WriteString("      if (err instanceof ImportFileError) {\n")
+	b.WriteString("        return res.status(400).json({ error: err.message });\n")
+	b.WriteString("      }\n")
+	b.WriteString("      throw err;\n")
+	b.WriteString("    }\n\n")
+	fmt.Fprintf(b, "    const summary: ImportSummary = { mode: '%s', dryRun, total: records.length, imported: 0, failed: 0, errors: [] };\n", im.Mode)
+	fmt.Fprintf(b, "    const valid: { row: number; data: Prisma.%sUncheckedCreateInput }[] = [];\n", m.Name)
+	b.WriteString("    records.forEach((values, i) => {\n")
+	b.WriteString("      const record = new ImportRecord(values, i + 1);\n")
+	b.WriteString("      const data = {\n")
+	for _, col := range ir.ImportColumns(m, ep.Auth) {
+		if col.Field == nil {
+			fmt.Fprintf(b, "        %sId: record.text('%s', true),\n", toCamelCase(col.Relation), col.Name)
+			continue
+		}
+		fmt.Fprintf(b, "        %s: %s,\n", col.Field.Name, importReader(col))
+	}
+	if scoped {
+		b.WriteString("        userId: req.userId!,\n")
+	}
+	b.WriteString("      };\n")
+	b.WriteString("      if (record.errors.length > 0) {\n")
+	b.WriteString("        summary.failed++;\n")
+	b.WriteString("        summary.errors.push(...record.errors);\n")
+	b.WriteString("      } else {\n")
+	fmt.Fprintf(b, "        valid.push({ row: record.row, data: data as Prisma.%sUncheckedCreateInput });\n", m.Name)
+	b.WriteString("      }\n")
+	b.WriteString("    });\n\n")
+
+	if im.Mode == ir.ImportContinueOnError {
+		b.WriteString("    if (!dryRun) {\n")
+		b.WriteString("      for (const { row, data } of valid) {\n")
+		b.WriteString("        try {\n")
+		fmt.Fprintf(b, "          await prisma.%s.create({ data });\n", modelCamel)
+		b.WriteString("          summary.imported++;\n")
+		b.WriteString("        } catch {\n")
+		b.WriteString("          summary.failed++;\n")
+		b.WriteString("          summary.errors.push({ row, message: 'This record could not be saved' });\n")
+		b.WriteString("        }\n")
+		b.WriteString("      }\n")
+		b.WriteString("      summary.errors.sort((a, b) => a.row - b.row);\n")
+		b.WriteString("    }\n")
+		b.WriteString("    return res.json({ data: summary });\n")
+		return
+	}
+
+	b.WriteString("    if (dryRun) {\n")
+	b.WriteString("      return res.json({ data: summary });\n")
+	b.WriteString("    }\n")
+	b.WriteString("    if (summary.failed > 0) {\n")
+	b.WriteString("      return res.status(422).json({ error: `Nothing was imported: ${summary.failed} of ${summary.total} records have problems`, data: summary });\n")
+	b.WriteString("    }\n")
+	b.WriteString("    let row = 0;\n")
+	b.WriteString("    try {\n")
+	b.WriteString("      await prisma.$transaction(async (tx) => {\n")
+	b.WriteString("        for (const record of valid) {\n")
+	b.WriteString("          row = record.row;\n")
+	fmt.Fprintf(b, "          await tx.%s.create({ data: record.data });\n", modelCamel)
+	b.WriteString("        }\n")
+	b.WriteString("      }, { timeout: 60_000 });\n")
+	b.WriteString("    } catch {\n")
+	b.WriteString("      summary.failed = 1;\n")
+	b.WriteString("      summary.errors.push({ row, message: 'This record could not be saved' });\n")
+	b.WriteString("      return res.status(422).json({ error: `Nothing was imported: record ${row} could not be saved`, data: summary });\n")
+	b.WriteString("    }\n")
+	b.WriteString("    summary.imported = valid.length;\n")
+	b.WriteString("    return res.json({ data: summary });\n")
+}
+
+// importReader returns the ImportRecord call reading a column's field.
+func importReader(col *ir.ImportColumn) string {
+	f := col.Field
+	required := fmt.Sprint(f.Required)
+	switch f.Type {
+	case "boolean":
+		// A blank boolean is false, as an unchecked box would be
+		return fmt.Sprintf("record.boolean('%s', false) ?? false", col.Name)
+	case "email", "url", "number", "decimal", "json":
+		return fmt.Sprintf("record.%s('%s', %s)", f.Type, col.Name, required)
+	case "date", "datetime":
+		return fmt.Sprintf("record.date('%s', %s)", col.Name, required)
+	case "enum":
+		values := make([]string, len(f.EnumValues))
+		for i, v := range f.EnumValues {
+			values[i] = "'" + v + "'"
+		}
+		return fmt.Sprintf("record.oneOf('%s', [%s] as const, %s)", col.Name, strings.Join(values, ", "), required)
+	}
+	return fmt.Sprintf("record.text('%s', %s)", col.Name, required)
+}
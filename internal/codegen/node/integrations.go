@@ -35,9 +35,16 @@ func generateIntegrations(app *ir.Application) map[string]string {
 		case "messaging":
 			filename = "slack.ts"
 			content = generateMessagingService(integ)
+		case "sms":
+			filename = "sms.ts"
+			content = generateSMSService(integ, app)
+			files["src/__tests__/sms.test.ts"] = generateSMSTests()
 		case "oauth":
 			filename = "oauth.ts"
 			content = generateOAuthService(integ)
+		case "analytics":
+			filename = "analytics.ts"
+			content = generateAnalyticsService(integ)
 		default:
 			filename = toKebabCase(integ.Service) + ".ts"
 			content = generateGenericService(integ)
@@ -291,6 +298,91 @@ func generateMessagingService(integ *ir.Integration) string {
 	return b.String()
 }
 
+// generateAnalyticsService produces a TypeScript service that sends server-side
+// events to the analytics provider's HTTP API.
+func generateAnalyticsService(integ *ir.Integration) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n")
+	fmt.Fprintf(&b, "// Integration: %s (analytics)\n\n", integ.Service)
+
+	provider := analyticsProvider(integ.Service)
+
+	keyEnv := map[string]string{
+		"posthog":   "POSTHOG_API_KEY",
+		"segment":   "SEGMENT_WRITE_KEY",
+		"mixpanel":  "MIXPANEL_TOKEN",
+		"amplitude": "AMPLITUDE_API_KEY",
+	}[provider]
+	for _, envVar := range integ.Credentials {
+		keyEnv = envVar
+		break
+	}
+
+	fmt.Fprintf(&b, "const API_KEY = process.env.%s || \"\";\n", keyEnv)
+	if provider == "posthog" {
+		b.WriteString("const HOST = process.env.POSTHOG_HOST || \"https://us.i.posthog.com\";\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString("export async function track(\n")
+	b.WriteString("  distinctId: string,\n")
+	b.WriteString("  event: string,\n")
+	b.WriteString("  properties: Record<string, unknown> = {},\n")
+	b.WriteString("): Promise<void> {\n")
+	b.WriteString("  if (!API_KEY) return;\n\n")
+
+	switch provider {
+	case "posthog":
+		b.WriteString("  const res = await fetch(`${HOST}/capture/`, {\n")
+		b.WriteString("    method: \"POST\",\n")
+		b.WriteString("    headers: { \"Content-Type\": \"application/json\" },\n")
+		b.WriteString("    body: JSON.stringify({ api_key: API_KEY, event, distinct_id: distinctId, properties }),\n")
+		b.WriteString("  });\n")
+	case "segment":
+		b.WriteString("  const res = await fetch(\"https://api.segment.io/v1/track\", {\n")
+		b.WriteString("    method: \"POST\",\n")
+		b.WriteString("    headers: {\n")
+		b.WriteString("      \"Content-Type\": \"application/json\",\n")
+		b.WriteString("      Authorization: `Basic ${Buffer.from(`${API_KEY}:`).toString(\"base64\")}`,\n")
+		b.WriteString("    },\n")
+		b.WriteString("    body: JSON.stringify({ anonymousId: distinctId, event, properties }),\n")
+		b.WriteString("  });\n")
+	case "mixpanel":
+		b.WriteString("  const res = await fetch(\"https://api.mixpanel.com/track\", {\n")
+		b.WriteString("    method: \"POST\",\n")
+		b.WriteString("    headers: { \"Content-Type\": \"application/json\" },\n")
+		b.WriteString("    body: JSON.stringify([{ event, properties: { ...properties, token: API_KEY, distinct_id: distinctId } }]),\n")
+		b.WriteString("  });\n")
+	default: // amplitude
+		b.WriteString("  const res = await fetch(\"https://api2.amplitude.com/2/httpapi\", {\n")
+		b.WriteString("    method: \"POST\",\n")
+		b.WriteString("    headers: { \"Content-Type\": \"application/json\" },\n")
+		b.WriteString("    body: JSON.stringify({ api_key: API_KEY, events: [{ device_id: distinctId, event_type: event, event_properties: properties }] }),\n")
+		b.WriteString("  });\n")
+	}
+	fmt.Fprintf(&b, "  if (!res.ok) {\n    throw new Error(`%s rejected event ${event}: ${res.status}`);\n  }\n", integ.Service)
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// analyticsProvider maps an analytics integration's service name to the
+// provider whose HTTP API the generated service calls.
+func analyticsProvider(service string) string {
+	s := strings.ToLower(service)
+	switch {
+	case strings.Contains(s, "posthog"):
+		return "posthog"
+	case strings.Contains(s, "segment"):
+		return "segment"
+	case strings.Contains(s, "mixpanel"):
+		return "mixpanel"
+	default:
+		return "amplitude"
+	}
+}
+
 // generateOAuthService produces a TypeScript OAuth service using Passport.js.
 func generateOAuthService(integ *ir.Integration) string {
 	var b strings.Builder
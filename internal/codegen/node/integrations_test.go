@@ -216,3 +216,72 @@ func TestGenerateIntegrationsBarrelExport(t *testing.T) {
 		t.Error("index.ts should contain barrel exports")
 	}
 }
+
+func smsApp() *ir.Application {
+	return &ir.Application{
+		Name: "Shop",
+		Integrations: []*ir.Integration{
+			{
+				Service:     "Twilio",
+				Type:        "sms",
+				Credentials: map[string]string{"account sid": "TWILIO_SID", "auth token": "TWILIO_TOKEN"},
+				Config:      map[string]string{"sender_email": "+15550001111"},
+				Templates:   []string{"order-shipped"},
+			},
+		},
+		ErrorHandlers: []*ir.ErrorHandler{
+			{Condition: "sending an SMS fails", Steps: []*ir.Action{{Type: "retry", Text: "retry 4 times with 2 second delay"}}},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "ShipOrder", Params: []*ir.Param{{Name: "order_id"}, {Name: "phone"}}, Steps: []*ir.Action{
+				{Type: "send", Text: "send an SMS Your order has shipped"},
+				{Type: "send", Text: "send WhatsApp message with template order-shipped"},
+			}},
+		},
+	}
+}
+
+func TestGenerateSMSService(t *testing.T) {
+	app := smsApp()
+	files := generateIntegrations(app)
+
+	content, ok := files["src/services/sms.ts"]
+	if !ok {
+		t.Fatal("Twilio should generate src/services/sms.ts")
+	}
+	if _, ok := files["src/services/slack.ts"]; ok {
+		t.Error("Twilio should not generate a Slack service")
+	}
+	for _, check := range []string{
+		`import twilio from "twilio";`,
+		`twilio(process.env.TWILIO_SID || "", process.env.TWILIO_TOKEN || "")`,
+		`process.env.TWILIO_FROM_NUMBER || "+15550001111"`,
+		"const ATTEMPTS = 4;",
+		"const RETRY_DELAY_MS = 2000;",
+		`"order-shipped": process.env.TWILIO_TEMPLATE_ORDER_SHIPPED || ""`,
+		"outbox.push(message);",
+		"export async function sendWhatsApp",
+	} {
+		if !strings.Contains(content, check) {
+			t.Errorf("sms service missing %q", check)
+		}
+	}
+	if _, ok := files["src/__tests__/sms.test.ts"]; !ok {
+		t.Error("missing sandbox tests for the SMS service")
+	}
+}
+
+func TestSMSSendSteps(t *testing.T) {
+	app := smsApp()
+	output := generateRoute(app.APIs[0], app)
+
+	for _, want := range []string{
+		"import { sendSMS, sendTemplate } from '../services/sms';",
+		"await sendSMS(phone, 'Your order has shipped');",
+		"await sendTemplate('whatsapp', phone, 'order-shipped');",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("route missing %q", want)
+		}
+	}
+}
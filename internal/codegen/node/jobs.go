@@ -0,0 +1,92 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateJobQueue produces src/jobs/queue.ts, which queues workflow jobs
+// in Redis with BullMQ for the worker to run.
+func generateJobQueue(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Queue } from 'bullmq';\n")
+	b.WriteString("import IORedis from 'ioredis';\n\n")
+	b.WriteString("export const QUEUE_NAME = 'jobs';\n\n")
+	b.WriteString("// BullMQ workers need maxRetriesPerRequest: null.\n")
+	b.WriteString("export const connection = new IORedis(process.env.REDIS_URL || 'redis://localhost:6379', {\n")
+	b.WriteString("  maxRetriesPerRequest: null,\n")
+	b.WriteString("});\n\n")
+	b.WriteString("const queue = new Queue(QUEUE_NAME, { connection });\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Queues a job for the worker (src/worker.ts), delayed by delayMs.\n")
+	b.WriteString(" * Call it where each workflow's trigger happens:\n")
+	b.WriteString(" *\n")
+	for _, wf := range app.Workflows {
+		fmt.Fprintf(&b, " *   enqueue('%s', data)  // when %s\n", ir.JobName(wf.Trigger), wf.Trigger)
+	}
+	b.WriteString(" */\n")
+	b.WriteString("export async function enqueue(name: string, data: Record<string, unknown> = {}, delayMs = 0) {\n")
+	b.WriteString("  await queue.add(name, data, {\n")
+	b.WriteString("    delay: delayMs,\n")
+	b.WriteString("    attempts: 3,\n")
+	b.WriteString("    backoff: { type: 'exponential', delay: 1000 },\n")
+	b.WriteString("    removeOnComplete: true,\n")
+	b.WriteString("  });\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// generateWorker produces src/worker.ts, the entry point of the worker
+// service: one handler per workflow job. A workflow's first job schedules
+// its delayed jobs.
+func generateWorker(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import './env';\n")
+	b.WriteString("import { Job, Worker } from 'bullmq';\n")
+	b.WriteString("import { connection, enqueue, QUEUE_NAME } from './jobs/queue';\n\n")
+	b.WriteString("const handlers: Record<string, (job: Job) => Promise<void>> = {\n")
+	for _, wf := range app.Workflows {
+		jobs := ir.WorkflowJobs(wf)
+		for i, job := range jobs {
+			if job.Delay == 0 {
+				fmt.Fprintf(&b, "  // when %s\n", job.Trigger)
+			} else {
+				fmt.Fprintf(&b, "  // %s after %s\n", job.After, job.Trigger)
+			}
+			fmt.Fprintf(&b, "  '%s': async (job) => {\n", job.Name)
+			for _, step := range job.Steps {
+				fmt.Fprintf(&b, "    // TODO: %s\n", step.Text)
+			}
+			if i == 0 {
+				for _, later := range jobs[1:] {
+					fmt.Fprintf(&b, "    await enqueue('%s', job.data, %d);\n", later.Name, later.Delay.Milliseconds())
+				}
+			}
+			b.WriteString("  },\n")
+		}
+	}
+	b.WriteString("};\n\n")
+	b.WriteString(`const worker = new Worker(
+  QUEUE_NAME,
+  async (job) => {
+    const handler = handlers[job.name];
+    if (!handler) {
+      throw new Error(` + "`No handler for job ${job.name}`" + `);
+    }
+    await handler(job);
+  },
+  { connection },
+);
+
+worker.on('failed', (job, err) => {
+  console.error(` + "`Job ${job?.name} failed: ${err.message}`" + `);
+});
+
+console.log(` + "`Worker processing the ${QUEUE_NAME} queue`" + `);
+`)
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateMasking produces src/middleware/masking.ts, which keeps fields
+// marked personal out of logs and error responses. Field names are
+// compared without case or separators, so "birthDate", "birth_date", and
+// "birth date" all match.
+func generateMasking(app *ir.Application) string {
+	var names []string
+	for _, name := range ir.PersonalFieldNames(app) {
+		names = append(names, "'"+maskingKey(name)+"'")
+	}
+
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Request, Response, NextFunction } from 'express';\n\n")
+	b.WriteString("// Fields marked personal in the .human file\n")
+	fmt.Fprintf(&b, "const PERSONAL_FIELDS = new Set([%s]);\n", strings.Join(names, ", "))
+	b.WriteString(`const REDACTED = '[REDACTED]';
+
+// key: value and key=value pairs in log lines and error messages
+const PAIR_PATTERN = /(["']?)([A-Za-z][\w-]*)\1(\s*[:=]\s*)("[^"]*"|'[^']*'|[^\s,&;}\]]+)/g;
+
+function isPersonal(key: string): boolean {
+  return PERSONAL_FIELDS.has(key.toLowerCase().replace(/[\s_-]/g, ''));
+}
+
+/**
+ * Masks personal fields in text: the values of key: value pairs, and any of
+ * the given values wherever they appear.
+ */
+export function maskText(text: string, values: string[] = []): string {
+  let masked = text.replace(PAIR_PATTERN, (pair, quote, key, sep, value) => {
+    if (!isPersonal(key)) return pair;
+    const q = /^["']/.test(value) ? value[0] : '';
+    return ` + "`${quote}${key}${quote}${sep}${q}${REDACTED}${q}`" + `;
+  });
+  for (const value of values) {
+    masked = masked.split(value).join(REDACTED);
+  }
+  return masked;
+}
+
+/**
+ * Returns a copy of value with personal fields replaced by [REDACTED], at
+ * any depth. Strings are masked with maskText.
+ */
+export function maskPersonalData(value: unknown, values: string[] = []): unknown {
+  if (typeof value === 'string') {
+    return maskText(value, values);
+  }
+  if (Array.isArray(value)) {
+    return value.map((item) => maskPersonalData(item, values));
+  }
+  if (value instanceof Error) {
+    const masked = new Error(maskText(value.message, values));
+    masked.name = value.name;
+    return masked;
+  }
+  if (value && typeof value === 'object') {
+    const masked: Record<string, unknown> = {};
+    for (const [key, item] of Object.entries(value)) {
+      masked[key] = isPersonal(key) ? REDACTED : maskPersonalData(item, values);
+    }
+    return masked;
+  }
+  return value;
+}
+
+/** Returns the personal values the client sent in the body or query string. */
+export function personalValues(req: Request): string[] {
+  const values: string[] = [];
+  const collect = (source: unknown) => {
+    if (!source || typeof source !== 'object') return;
+    for (const [key, item] of Object.entries(source)) {
+      if (isPersonal(key) && typeof item === 'string' && item.length > 0) {
+        values.push(item);
+      } else if (item && typeof item === 'object') {
+        collect(item);
+      }
+    }
+  };
+  collect(req.body);
+  collect(req.query);
+  return values;
+}
+
+/** Masks personal fields in everything written through console. */
+export function installLogMasking() {
+  for (const level of ['log', 'info', 'warn', 'error', 'debug'] as const) {
+    const write = console[level].bind(console);
+    console[level] = (...args: unknown[]) => write(...args.map((arg) => maskPersonalData(arg)));
+  }
+}
+
+/**
+ * Masks personal fields in error responses, including values the client
+ * sent that an error message repeats.
+ */
+export function maskErrorResponses(req: Request, res: Response, next: NextFunction) {
+  const json = res.json.bind(res);
+  res.json = (body: unknown) => {
+    if (res.statusCode < 400) {
+      return json(body);
+    }
+    return json(maskPersonalData(body, personalValues(req)));
+  };
+  next();
+}
+`)
+	return b.String()
+}
+
+// maskingKey normalizes a field name the way the masking code compares
+// keys: lowercase, without spaces, underscores, or dashes.
+func maskingKey(name string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(name))
+}
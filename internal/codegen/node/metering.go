@@ -0,0 +1,257 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateUsageService produces src/services/usage.ts: counting a user's
+// calls per period, how many they've made, the quota they're held to, and,
+// when the app bills, reporting calls to a Stripe meter.
+func generateUsageService(app *ir.Application) string {
+	var b strings.Builder
+	m := app.Metering
+	userID := toCamelCase(m.Model) + "Id"
+	if userID != "userId" {
+		userID += ": userId"
+	}
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	if ir.Bills(app) {
+		b.WriteString("import { ACTIVE_STATUSES, currentPlan, stripe } from './billing';\n")
+	}
+	b.WriteString(prismaImport(app, "."))
+	fmt.Fprintf(&b, "\nconst prisma = %s;\n\n", newPrismaClient(app))
+
+	b.WriteString("export type Period = 'day' | 'week' | 'month';\n\n")
+	b.WriteString("/** Calls are counted per this period. */\n")
+	fmt.Fprintf(&b, "export const METER_PERIOD: Period = '%s';\n\n", m.Period)
+
+	b.WriteString("/** A cap on a user's calls: how many they may make per period. */\n")
+	b.WriteString("export interface Quota {\n  limit: number;\n  period: Period;\n}\n\n")
+	if ir.Bills(app) {
+		b.WriteString("/** The quota of each plan's subscribers, from the policy named after the plan. */\n")
+	} else {
+		b.WriteString("/** The quota of each role, from the policy named after it. */\n")
+	}
+	b.WriteString("const QUOTAS: Record<string, Quota> = {\n")
+	for _, q := range ir.UsageQuotas(app) {
+		if !q.Enforced(app) {
+			continue
+		}
+		key := q.Policy
+		if q.Plan != "" {
+			key = q.Plan
+		}
+		fmt.Fprintf(&b, "  '%s': { limit: %d, period: '%s' },\n", key, q.Limit, q.Period)
+	}
+	b.WriteString("};\n\n")
+
+	b.WriteString(`/** The start of the period a time falls in, in UTC. Weeks start on Sunday. */
+export function periodStart(period: Period, at: Date = new Date()): Date {
+  const day = new Date(Date.UTC(at.getUTCFullYear(), at.getUTCMonth(), at.getUTCDate()));
+  switch (period) {
+    case 'week':
+      day.setUTCDate(day.getUTCDate() - day.getUTCDay());
+      break;
+    case 'month':
+      day.setUTCDate(1);
+      break;
+  }
+  return day;
+}
+
+/** The start of the period n periods after the one starting at start. */
+export function addPeriods(start: Date, period: Period, n: number): Date {
+  const date = new Date(start);
+  if (period === 'month') {
+    date.setUTCMonth(date.getUTCMonth() + n);
+  } else {
+    date.setUTCDate(date.getUTCDate() + n * (period === 'week' ? 7 : 1));
+  }
+  return date;
+}
+
+`)
+	b.WriteString("/** The quota a user is held to, or undefined when their calls aren't capped. */\n")
+	switch {
+	case ir.Bills(app):
+		b.WriteString("export async function quotaFor(userId: string, _role?: string): Promise<Quota | undefined> {\n")
+		b.WriteString("  const plan = await currentPlan(userId);\n")
+		b.WriteString("  return plan ? QUOTAS[plan] : undefined;\n")
+	case ir.QuotaByRole(app):
+		b.WriteString("export async function quotaFor(_userId: string, role?: string): Promise<Quota | undefined> {\n")
+		b.WriteString("  return role ? QUOTAS[role] : undefined;\n")
+	default:
+		b.WriteString("export async function quotaFor(_userId: string, _role?: string): Promise<Quota | undefined> {\n")
+		b.WriteString("  return undefined;\n")
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, `/** How many calls a user has made since the start of a period. */
+export async function callsSince(userId: string, since: Date): Promise<number> {
+  const { _sum } = await prisma.usageRecord.aggregate({
+    where: { %[1]s, period_start: { gte: since } },
+    _sum: { calls: true },
+  });
+  return _sum.calls ?? 0;
+}
+
+/** A user's calls in each of the last periods, oldest first, with none counted as 0. */
+export async function callHistory(userId: string, periods: number): Promise<{ start: Date; calls: number }[]> {
+  const since = addPeriods(periodStart(METER_PERIOD), METER_PERIOD, 1 - periods);
+  const records = await prisma.usageRecord.findMany({ where: { %[1]s, period_start: { gte: since } } });
+  const calls = new Map<number, number>();
+  for (const record of records) {
+    const start = record.period_start.getTime();
+    calls.set(start, (calls.get(start) ?? 0) + record.calls);
+  }
+  return Array.from({ length: periods }, (_, i) => {
+    const start = addPeriods(since, METER_PERIOD, i);
+    return { start, calls: calls.get(start.getTime()) ?? 0 };
+  });
+}
+
+/**
+ * Counts a call in the user's record for the current period. Two first
+ * calls at once may each create a record; counts add up all of them.
+ */
+export async function recordCall(userId: string): Promise<void> {
+  const period_start = periodStart(METER_PERIOD);
+  const { count } = await prisma.usageRecord.updateMany({
+    where: { %[1]s, period_start },
+    data: { calls: { increment: 1 } },
+  });
+  if (count === 0) {
+    await prisma.usageRecord.create({ data: { %[1]s, period_start, calls: 1 } });
+  }
+}
+`, userID)
+
+	if ir.Bills(app) {
+		fmt.Fprintf(&b, `
+/**
+ * Reports a call to the Stripe meter named by STRIPE_USAGE_METER, which
+ * metered prices bill by. Nothing is reported when it isn't set or the
+ * user has no active subscription.
+ */
+export async function reportCall(userId: string): Promise<void> {
+  const meter = process.env.STRIPE_USAGE_METER;
+  if (!meter) {
+    return;
+  }
+  const subscription = await prisma.subscription.findFirst({
+    where: { %s, status: { in: ACTIVE_STATUSES } },
+    orderBy: { createdAt: 'desc' },
+  });
+  if (!subscription) {
+    return;
+  }
+  await stripe.billing.meterEvents.create({
+    event_name: meter,
+    payload: { stripe_customer_id: subscription.stripe_customer_id, value: '1' },
+  });
+}
+`, subscriberKey(app))
+	}
+	return b.String()
+}
+
+// generateMeteringMiddleware produces src/middleware/metering.ts: meter,
+// which counts the signed-in user's call and refuses it once they've made
+// as many as their quota allows this period.
+func generateMeteringMiddleware(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Request, Response, NextFunction } from 'express';\n")
+	if ir.Bills(app) {
+		b.WriteString("import { addPeriods, callsSince, periodStart, quotaFor, recordCall, reportCall } from '../services/usage';\n\n")
+	} else {
+		b.WriteString("import { addPeriods, callsSince, periodStart, quotaFor, recordCall } from '../services/usage';\n\n")
+	}
+
+	upgrade := ""
+	if ir.Bills(app) {
+		upgrade = ": upgrade your plan for more"
+	}
+	fmt.Fprintf(&b, `/**
+ * Metering middleware — counts the signed-in user's calls.
+ *
+ * Usage:
+ *   router.get('/', authenticate, meter, handler);
+ *
+ * Behavior:
+ *   1. If the user has made all the calls their quota allows this period → %[1]d, with Retry-After
+ *   2. Otherwise → the call is counted and allowed
+ */
+export async function meter(req: Request, res: Response, next: NextFunction) {
+  try {
+    const quota = await quotaFor(req.userId!, req.userRole);
+    if (quota) {
+      const start = periodStart(quota.period);
+      if ((await callsSince(req.userId!, start)) >= quota.limit) {
+        const resets = addPeriods(start, quota.period, 1);
+        res.setHeader('Retry-After', String(Math.ceil((resets.getTime() - Date.now()) / 1000)));
+        return res.status(%[1]d).json({ error: `+"`You've made all ${quota.limit} of your API calls this ${quota.period}%[2]s`"+` });
+      }
+    }
+    await recordCall(req.userId!);
+`, ir.QuotaExceededStatus, upgrade)
+	if ir.Bills(app) {
+		b.WriteString("    reportCall(req.userId!).catch((err) => console.error('Could not report usage to Stripe:', err));\n")
+	}
+	b.WriteString(`    next();
+  } catch (error) {
+    next(error);
+  }
+}
+`)
+	return b.String()
+}
+
+// generateUsageRoutes produces src/routes/usage.ts: the signed-in user's
+// calls this period against their quota, and their calls in each of the
+// last periods for the usage dashboard.
+func generateUsageRoutes(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Router, Request, Response, NextFunction } from 'express';\n")
+	b.WriteString("import { authenticate } from '../middleware/auth';\n")
+	b.WriteString("import { METER_PERIOD, addPeriods, callHistory, callsSince, periodStart, quotaFor } from '../services/usage';\n\n")
+	b.WriteString("const router = Router();\n\n")
+	b.WriteString(`// Calls this period: per the user's quota, else per the metering period
+router.get('/', authenticate, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const quota = await quotaFor(req.userId!, req.userRole);
+    const period = quota?.period ?? METER_PERIOD;
+    const start = periodStart(period);
+    res.json({
+      data: {
+        period,
+        used: await callsSince(req.userId!, start),
+        limit: quota?.limit ?? null,
+        resetsAt: addPeriods(start, period, 1),
+      },
+    });
+  } catch (error) {
+    next(error);
+  }
+});
+
+// Calls in each of the last ?periods= periods (30 by default, at most 365)
+router.get('/history', authenticate, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const periods = Math.min(Math.max(Math.floor(Number(req.query.periods)) || 30, 1), 365);
+    res.json({ data: await callHistory(req.userId!, periods) });
+  } catch (error) {
+    next(error);
+  }
+});
+
+export { router };
+`)
+	return b.String()
+}
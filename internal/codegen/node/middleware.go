@@ -13,7 +13,11 @@ func generateAuthMiddleware(app *ir.Application) string {
 
 	b.WriteString("// Generated by Human compiler — do not edit\n\n")
 	b.WriteString("import { Request, Response, NextFunction } from 'express';\n")
-	b.WriteString("import jwt from 'jsonwebtoken';\n\n")
+	b.WriteString("import jwt from 'jsonwebtoken';\n")
+	if ir.IssuesAPIKeys(app) {
+		b.WriteString("import { allows, findApiKey } from '../services/apiKeys';\n")
+	}
+	b.WriteString("\n")
 
 	// Extract JWT config from auth methods
 	secret := "process.env.JWT_SECRET || 'change-me'"
@@ -37,13 +41,20 @@ func generateAuthMiddleware(app *ir.Application) string {
     interface Request {
       userId?: string;
       userRole?: string;
-    }
+`)
+	if ir.IssuesAPIKeys(app) {
+		b.WriteString("      apiKeyId?: string; // set when the request was authenticated by an API key\n")
+	}
+	b.WriteString(`    }
   }
 }
 `)
 
 	// authenticate middleware
-	b.WriteString(`
+	if ir.IssuesAPIKeys(app) {
+		writeAPIKeyAuth(&b, app)
+	} else {
+		b.WriteString(`
 export function authenticate(req: Request, res: Response, next: NextFunction) {
   const header = req.headers.authorization;
   if (!header || !header.startsWith('Bearer ')) {
@@ -61,6 +72,7 @@ export function authenticate(req: Request, res: Response, next: NextFunction) {
   }
 }
 `)
+	}
 
 	// signToken helper
 	b.WriteString(`
@@ -0,0 +1,84 @@
+package node
+
+import (
+	"path"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/migrate"
+)
+
+// generateMigrations returns the app's migration history as Prisma
+// Migrate's files, by path relative to the backend: each migration's
+// migration.sql, applied with `npx prisma migrate deploy` (`human
+// migrate`), and the lock file naming the provider. They're generated
+// for PostgreSQL, Prisma's default; other databases have the schema
+// pushed.
+func generateMigrations(app *ir.Application) map[string]string {
+	history := migrate.History(app)
+	if prismaProvider(app) != "postgresql" || len(history) == 0 {
+		return nil
+	}
+	files := map[string]string{
+		"prisma/migrations/migration_lock.toml": `# Please do not edit this file manually
+# It should be added in your version-control system (e.g., Git)
+provider = "postgresql"
+`,
+	}
+	dialect := prismaDialect(app)
+	for _, m := range history {
+		files[path.Join("prisma", "migrations", m.Name, "migration.sql")] =
+			"-- Generated by Human compiler — do not edit\n-- Migration: " + m.Name + "\n\n" + dialect.Up(m)
+	}
+	return files
+}
+
+// prismaDialect lays out tables as Prisma Migrate does in PostgreSQL,
+// matching schema.prisma: tables and columns keep the model and field
+// names, ids are cuids, and enum fields have native enum types.
+func prismaDialect(app *ir.Application) migrate.Dialect {
+	timestamp := "TIMESTAMP(3)"
+	if prismaTimeAttr("datetime", app) != "" {
+		timestamp = "TIMESTAMPTZ(3)"
+	}
+	return migrate.Dialect{
+		Table:      func(model string) string { return model },
+		Column:     func(field string) string { return field },
+		ForeignKey: func(target string) string { return toCamelCase(target) + "Id" },
+		Type: func(f *ir.DataField) string {
+			switch prismaType(f.Type) {
+			case "Int":
+				return "INTEGER"
+			case "Float":
+				return "DOUBLE PRECISION"
+			case "Boolean":
+				return "BOOLEAN"
+			case "DateTime":
+				if f.Type == "date" && prismaTimeAttr("date", app) != "" {
+					return "DATE"
+				}
+				return timestamp
+			case "Json":
+				return "JSONB"
+			}
+			return "TEXT"
+		},
+		EnumType: func(model, field string) string { return model + capitalize(field) },
+		Constraint: func(table, column, kind string) string {
+			if kind == "pkey" {
+				return table + "_pkey"
+			}
+			return table + "_" + column + "_" + kind
+		},
+		Skip: func(f *ir.DataField) bool {
+			lower := strings.ToLower(f.Name)
+			return lower == "created" || lower == "createdat" || lower == "updated" || lower == "updatedat"
+		},
+		ID:        "TEXT NOT NULL",
+		Reference: "TEXT NOT NULL",
+		Timestamps: []string{
+			`"createdAt" ` + timestamp + ` NOT NULL DEFAULT CURRENT_TIMESTAMP`,
+			`"updatedAt" ` + timestamp + ` NOT NULL`,
+		},
+	}
+}
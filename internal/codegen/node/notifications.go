@@ -0,0 +1,126 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// triggeredNotifications returns the in-app notifications an endpoint sends.
+func triggeredNotifications(ep *ir.Endpoint, app *ir.Application) []*ir.Notification {
+	var out []*ir.Notification
+	for _, n := range app.Notifications {
+		if n.Triggers(ep) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// writeNotifyCalls emits a notify() call for each in-app notification the
+// endpoint triggers. The recipient is the user named by a param (user_id,
+// assignee, ...), else the record's owner, else the current user.
+func writeNotifyCalls(b *strings.Builder, ep *ir.Endpoint, app *ir.Application, resultIdx int) {
+	for _, n := range triggeredNotifications(ep, app) {
+		recipient := ""
+		if param := n.RecipientParam(ep); param != "" {
+			recipient = sanitizeParamName(param)
+		} else if resultIdx > 0 && modelBelongsToUser(n.Model, app) {
+			recipient = lastResultVar(resultIdx) + ".userId"
+		} else if ep.Auth {
+			recipient = "req.userId!"
+		}
+		fmt.Fprintf(b, "    // notify the user in the app when their %s is %s\n", strings.ToLower(n.Model), n.Event)
+		if recipient == "" {
+			b.WriteString("    // TODO: no recipient for the in-app notification — require authentication\n\n")
+			continue
+		}
+		fmt.Fprintf(b, "    await notify(%s, '%s');\n\n", recipient, n.Message)
+	}
+}
+
+// generateNotificationService produces src/services/notifications.ts, which
+// stores an in-app notification for a user.
+func generateNotificationService() string {
+	return `// Generated by Human compiler — do not edit
+
+import { PrismaClient } from '@prisma/client';
+
+const prisma = new PrismaClient();
+
+export async function notify(userId: string, message: string) {
+  return prisma.notification.create({ data: { userId, message } });
+}
+`
+}
+
+// generateNotificationRoutes produces src/routes/notifications.ts: the
+// current user's notifications, their unread count, and marking them read.
+// The frontend dropdown polls the unread count.
+func generateNotificationRoutes() string {
+	return `// Generated by Human compiler — do not edit
+
+import { Router, Request, Response, NextFunction } from 'express';
+import { PrismaClient } from '@prisma/client';
+import { authenticate } from '../middleware/auth';
+
+const prisma = new PrismaClient();
+const router = Router();
+
+router.use(authenticate);
+
+router.get('/', async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const notifications = await prisma.notification.findMany({
+      where: { userId: req.userId! },
+      orderBy: { createdAt: 'desc' },
+      take: 50,
+    });
+    res.json({ data: notifications });
+  } catch (error) {
+    next(error);
+  }
+});
+
+router.get('/unread-count', async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const count = await prisma.notification.count({ where: { userId: req.userId!, seen: null } });
+    res.json({ data: { count } });
+  } catch (error) {
+    next(error);
+  }
+});
+
+router.post('/read-all', async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const { count } = await prisma.notification.updateMany({
+      where: { userId: req.userId!, seen: null },
+      data: { seen: new Date() },
+    });
+    res.json({ data: { count } });
+  } catch (error) {
+    next(error);
+  }
+});
+
+router.post('/:id/read', async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const notification = await prisma.notification.findFirst({ where: { id: req.params.id, userId: req.userId! } });
+    if (!notification) {
+      res.status(404).json({ error: 'Notification not found' });
+      return;
+    }
+    const updated = await prisma.notification.update({
+      where: { id: notification.id },
+      data: { seen: notification.seen ?? new Date() },
+    });
+    res.json({ data: updated });
+  } catch (error) {
+    next(error);
+  }
+});
+
+export { router };
+`
+}
@@ -0,0 +1,45 @@
+package node
+
+// generateOpenAPIValidation produces src/middleware/openapi.ts, which
+// checks each API request against openapi.yaml, the copy of
+// openapi/openapi.yaml beside src/, with express-openapi-validator.
+func generateOpenAPIValidation() string {
+	return `// Generated by Human compiler — do not edit
+
+import path from 'path';
+import { ErrorRequestHandler } from 'express';
+import * as OpenApiValidator from 'express-openapi-validator';
+
+// Beside src/ and dist/ alike
+const SPEC = path.join(__dirname, '..', '..', 'openapi.yaml');
+
+/**
+ * Request validation against the app's OpenAPI document.
+ *
+ * Each API request the document describes is checked against its
+ * parameters and body before the route runs, so handwritten changes to the
+ * routes can't drift from the declared contract unnoticed. Requests it
+ * doesn't describe pass through, and authentication and uploads are left
+ * to the routes.
+ */
+const validators = OpenApiValidator.middleware({
+  apiSpec: SPEC,
+  validateRequests: { allowUnknownQueryParameters: true },
+  validateResponses: false,
+  validateSecurity: false,
+  ignoreUndocumented: true,
+  fileUploader: false,
+});
+
+// A request that doesn't match gets the validator's status, 400 for a bad
+// parameter or body, with its message, as the routes' own checks give
+const rejectInvalid: ErrorRequestHandler = (err, _req, res, next) => {
+  if (!err?.status || !Array.isArray(err.errors)) {
+    return next(err);
+  }
+  res.status(err.status).json({ error: err.message });
+};
+
+export const validateRequests = [...validators, rejectInvalid];
+`
+}
@@ -0,0 +1,135 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// pagedStep returns the step whose records a paginated endpoint serves a
+// page at a time: its first "fetch all" query. It returns nil when the
+// endpoint doesn't paginate.
+func pagedStep(ep *ir.Endpoint) *ir.Action {
+	if ep.PageSize == 0 {
+		return nil
+	}
+	for _, step := range ep.Steps {
+		if step.Type == "query" && !isQueryModifier(step.Text) && !isSingleFetch(step.Text) {
+			return step
+		}
+	}
+	return nil
+}
+
+// pagedParams returns the query parameters a paginated endpoint narrows,
+// orders, and pages its records by, or nil when it doesn't paginate.
+func pagedParams(ep *ir.Endpoint, app *ir.Application) *ir.ListParams {
+	step := pagedStep(ep)
+	if step == nil {
+		return nil
+	}
+	return ir.ListParamsFor(app, ep, inferModelFromAction(step.Text, app))
+}
+
+// writePagedQuery writes a paginated findMany. Clients pass the nextCursor
+// of the page they have to get the one after it; one extra record is
+// fetched to tell whether there is one. Records come newest first, or in
+// the order users dragged them into, unless ?sort= orders them by a field;
+// sorted records, and ones asked for with ?page=, are paged by offset. ?q=
+// and the endpoint's filters narrow the records first.
+func writePagedQuery(b *strings.Builder, varName, model string, ep *ir.Endpoint, app *ir.Application) {
+	order := "[{ createdAt: 'desc' }, { id: 'desc' }]"
+	if r := ir.ReorderFor(app, model); r != nil {
+		order = fmt.Sprintf("[{ %s: 'asc' }, { id: 'asc' }]", toCamelCase(r.Field))
+	}
+	params := ir.ListParamsFor(app, ep, model)
+	fmt.Fprintf(b, "    const limit = Math.min(Number(req.query.limit) || %d, 100);\n", ep.PageSize)
+	b.WriteString("    const cursor = typeof req.query.cursor === 'string' ? req.query.cursor : undefined;\n")
+	if params != nil {
+		writeListQueryParams(b, model, order, params)
+	}
+	fmt.Fprintf(b, "    const rows = await prisma.%s.findMany({\n", toCamelCase(model))
+	if params != nil {
+		writeListWhere(b, model, params, ep, app)
+	} else if ep.Auth && modelBelongsToUser(model, app) {
+		b.WriteString("      where: { userId: req.userId },\n")
+	}
+	if params != nil {
+		b.WriteString("      orderBy,\n")
+	} else {
+		fmt.Fprintf(b, "      orderBy: %s,\n", order)
+	}
+	b.WriteString("      take: limit + 1,\n")
+	if params != nil {
+		b.WriteString("      ...(sort || req.query.page ? { skip: (page - 1) * limit } : cursor ? { cursor: { id: cursor }, skip: 1 } : {}),\n")
+	} else {
+		b.WriteString("      ...(cursor ? { cursor: { id: cursor }, skip: 1 } : {}),\n")
+	}
+	b.WriteString("    });\n")
+	b.WriteString("    const nextCursor = rows.length > limit ? rows[limit - 1].id : null;\n")
+	fmt.Fprintf(b, "    %s = rows.slice(0, limit);\n\n", varName)
+}
+
+// writeListQueryParams reads the page number and the field to sort by,
+// which must be one of the model's, and the order it falls back to.
+// Creation and update times are Prisma's createdAt and updatedAt.
+func writeListQueryParams(b *strings.Builder, model, order string, params *ir.ListParams) {
+	var names []string
+	for _, f := range params.Sortable {
+		column := f.Name
+		switch strings.ToLower(f.Name) {
+		case "created", "createdat":
+			column = "createdAt"
+		case "updated", "updatedat":
+			column = "updatedAt"
+		}
+		names = append(names, fmt.Sprintf("['%s', '%s']", f.Name, column))
+	}
+	b.WriteString("    const page = Math.max(Math.floor(Number(req.query.page)) || 1, 1);\n")
+	fmt.Fprintf(b, "    const sort = new Map([%s]).get(String(req.query.sort));\n", strings.Join(names, ", "))
+	b.WriteString("    const order: Prisma.SortOrder = req.query.order === 'desc' ? 'desc' : 'asc';\n")
+	fmt.Fprintf(b, "    const orderBy: Prisma.%sOrderByWithRelationInput[] = sort\n", model)
+	fmt.Fprintf(b, "      ? [{ [sort]: order } as Prisma.%sOrderByWithRelationInput, { id: order }]\n", model)
+	fmt.Fprintf(b, "      : %s;\n", order)
+}
+
+// writeListWhere writes the where clause of a paginated findMany: the
+// signed-in user's records of a model that belongs to users, narrowed by
+// each filter given and by ?q=, which any of the searched fields may
+// contain. A filter that isn't a valid value is ignored.
+func writeListWhere(b *strings.Builder, model string, params *ir.ListParams, ep *ir.Endpoint, app *ir.Application) {
+	b.WriteString("      where: {\n")
+	if ep.Auth && modelBelongsToUser(model, app) {
+		b.WriteString("        userId: req.userId,\n")
+	}
+	for _, f := range params.Filters {
+		q := "req.query." + f.Name
+		switch f.Type {
+		case "enum":
+			values := make([]string, len(f.EnumValues))
+			for i, v := range f.EnumValues {
+				values[i] = "'" + v + "'"
+			}
+			fmt.Fprintf(b, "        %s: ([%s] as const).find(value => value === %s),\n", f.Name, strings.Join(values, ", "), q)
+		case "boolean":
+			fmt.Fprintf(b, "        %s: %s === 'true' ? true : %s === 'false' ? false : undefined,\n", f.Name, q, q)
+		case "number":
+			fmt.Fprintf(b, "        %s: %s && Number.isFinite(Number(%s)) ? Number(%s) : undefined,\n", f.Name, q, q, q)
+		default:
+			fmt.Fprintf(b, "        %s: typeof %s === 'string' && %s ? %s : undefined,\n", f.Name, q, q, q)
+		}
+	}
+	if len(params.Search) > 0 {
+		mode := ""
+		if p := prismaProvider(app); p == "postgresql" || p == "mongodb" {
+			mode = ", mode: 'insensitive' as const"
+		}
+		var ors []string
+		for _, f := range params.Search {
+			ors = append(ors, fmt.Sprintf("{ %s: { contains: req.query.q%s } }", f.Name, mode))
+		}
+		fmt.Fprintf(b, "        ...(typeof req.query.q === 'string' && req.query.q ? { OR: [%s] } : {}),\n", strings.Join(ors, ", "))
+	}
+	b.WriteString("      },\n")
+}
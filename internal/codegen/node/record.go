@@ -0,0 +1,84 @@
+package node
+
+import (
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateRecord produces src/middleware/record.ts: with request recording
+// on (`human run --record`), every API request and the response it got is
+// written to an .http file that `human replay` can send again. It does
+// nothing otherwise.
+func generateRecord() string {
+	return `// Generated by Human compiler — do not edit
+
+import { Request, Response, NextFunction } from 'express';
+import fs from 'fs';
+import path from 'path';
+
+const DIR = process.env.` + ir.RecordEnv + `;
+const PORT = process.env.PORT || 3001;
+// Sent again on replay as they were, except these
+const SKIPPED_HEADERS = ['host', 'connection', 'content-length', 'transfer-encoding', 'accept-encoding'];
+let seq = 0;
+
+if (DIR) {
+  fs.mkdirSync(DIR, { recursive: true });
+  console.log(` + "`[record] Recording API requests to ${DIR}`" + `);
+}
+
+function requestBody(req: Request): string {
+  if (typeof req.body === 'string') return req.body;
+  if (Buffer.isBuffer(req.body)) return req.body.toString();
+  if (req.body && Object.keys(req.body).length > 0) return JSON.stringify(req.body, null, 2);
+  return '';
+}
+
+/**
+ * Request recorder — off unless recording is on.
+ *
+ * Each API request is written to its own .http file in the VS Code REST
+ * Client format, with the response it got in the comments above it, so it
+ * can be sent again from the editor or with ` + "`human replay`" + `.
+ */
+export function record(req: Request, res: Response, next: NextFunction) {
+  if (!DIR) {
+    return next();
+  }
+  const recordedAt = new Date();
+  let body = '';
+  const send = res.send.bind(res);
+  res.send = (data?: any) => {
+    body = typeof data === 'string' || Buffer.isBuffer(data) ? String(data) : JSON.stringify(data) ?? '';
+    return send(data);
+  };
+
+  res.on('finish', () => {
+    const lines = [
+      ` + "`@host = http://localhost:${PORT}`" + `,
+      '',
+      '###',
+      ` + "`# Recorded: ${recordedAt.toISOString()}`" + `,
+      ` + "`# Response: ${res.statusCode} (${Date.now() - recordedAt.getTime()} ms)`" + `,
+      ...body.split('\n').filter(Boolean).map(line => ` + "`# ${line}`" + `),
+      ` + "`${req.method} {{host}}${req.originalUrl}`" + `,
+    ];
+    for (const [name, value] of Object.entries(req.headers)) {
+      if (value !== undefined && !SKIPPED_HEADERS.includes(name)) {
+        lines.push(` + "`${name}: ${Array.isArray(value) ? value.join(', ') : value}`" + `);
+      }
+    }
+    const sent = requestBody(req);
+    if (sent) {
+      lines.push('', sent);
+    }
+
+    const slug = req.path.toLowerCase().replace(/[^a-z0-9]+/g, '-').replace(/^-|-$/g, '') || 'root';
+    const name = ` + "`${recordedAt.toISOString().replace(/[:.]/g, '-')}-${String(++seq).padStart(4, '0')}-${req.method}-${slug}.http`" + `;
+    fs.writeFile(path.join(DIR, name), lines.join('\n') + '\n', (err) => {
+      if (err) console.warn(` + "`[record] Could not record ${req.method} ${req.originalUrl}: ${err.message}`" + `);
+    });
+  });
+  next();
+}
+`
+}
@@ -0,0 +1,72 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateReorderRoutes produces src/routes/reorder.ts: for each list users
+// can drag into a new order, a PUT /api/reorder/<slug> taking { ids } in
+// their new order and numbering the model's position field from 0. A
+// model that belongs to a user only reorders the signed-in user's records.
+func generateReorderRoutes(app *ir.Application) string {
+	var b strings.Builder
+	auth := app.Auth != nil
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Router, Request, Response, NextFunction } from 'express';\n")
+	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	if auth {
+		b.WriteString("import { authenticate } from '../middleware/auth';\n")
+	}
+	b.WriteString(prismaImport(app, "../services"))
+	fmt.Fprintf(&b, "\nconst prisma = %s;\n", newPrismaClient(app))
+	b.WriteString("const router = Router();\n")
+	if auth {
+		b.WriteString("\nrouter.use(authenticate);\n")
+	}
+	b.WriteString(`
+// isIdList reports whether a request body lists record ids.
+function isIdList(ids: unknown): ids is string[] {
+  return Array.isArray(ids) && ids.every((id) => typeof id === 'string');
+}
+`)
+
+	for _, r := range app.Reorders {
+		scoped := auth && modelBelongsToUser(r.Model, app) && !strings.EqualFold(r.Model, "User")
+		where := "{ id }"
+		if scoped {
+			where = "{ id, userId: req.userId! }"
+		}
+		records := strings.ReplaceAll(r.Slug, "-", " ")
+		fmt.Fprintf(&b, "\n// Drag-and-drop order of %s\n", records)
+		fmt.Fprintf(&b, "router.put('/%s', async (req: Request, res: Response, next: NextFunction) => {\n", r.Slug)
+		b.WriteString("  const { ids } = req.body;\n")
+		b.WriteString("  if (!isIdList(ids)) {\n")
+		fmt.Fprintf(&b, "    return res.status(400).json({ error: 'ids must list the %s in their new order' });\n", records)
+		b.WriteString("  }\n")
+		b.WriteString("  try {\n")
+		b.WriteString("    await prisma.$transaction(\n")
+		fmt.Fprintf(&b, "      ids.map((id, position) => prisma.%s.updateMany({ where: %s, data: { %s: position } })),\n", toCamelCase(r.Model), where, toCamelCase(r.Field))
+		b.WriteString("    );\n")
+		b.WriteString("    res.json({ data: ids });\n")
+		b.WriteString("  } catch (error) {\n")
+		b.WriteString("    next(error);\n")
+		b.WriteString("  }\n")
+		b.WriteString("});\n")
+	}
+
+	b.WriteString("\nexport { router };\n")
+	return b.String()
+}
+
+// listOrder returns the orderBy clause listing a model's records in the
+// order users dragged them into, or "".
+func listOrder(model string, app *ir.Application) string {
+	if r := ir.ReorderFor(app, model); r != nil {
+		return fmt.Sprintf("orderBy: { %s: 'asc' }", toCamelCase(r.Field))
+	}
+	return ""
+}
@@ -42,12 +42,16 @@ func generateRoute(ep *ir.Endpoint, app *ir.Application) string {
 
 	isSignUp := isSignUpEndpoint(ep.Name)
 	isLogin := isLoginEndpoint(ep.Name)
-	needsBcrypt := isSignUp || isLogin
+	needsBcrypt := isSignUp || isLogin || ep.Account == ir.AccountChangePassword || ep.Account == ir.AccountDelete
 	needsSignToken := isSignUp || isLogin
 
 	b.WriteString("// Generated by Human compiler — do not edit\n\n")
 	b.WriteString("import { Router, Request, Response, NextFunction } from 'express';\n")
-	b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	if pagedParams(ep, app) != nil || ep.Import != nil {
+		b.WriteString("import { PrismaClient, Prisma } from '@prisma/client';\n")
+	} else {
+		b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+	}
 
 	if ep.Auth {
 		b.WriteString("import { authenticate } from '../middleware/auth';\n")
@@ -56,14 +60,30 @@ func generateRoute(ep *ir.Endpoint, app *ir.Application) string {
 	// Import authorize when policies exist and endpoint has auth
 	action := inferRouteAction(ep.Name)
 	model := inferRouteModel(ep.Name)
-	useAuthorize := len(app.Policies) > 0 && ep.Auth && action != "" && model != ""
+	useAuthorize := len(app.Policies) > 0 && ep.Auth && action != "" && model != "" && ep.Account == ""
+	// Inviting a teammate is checked against the inviter's policy
+	if ep.Account == ir.AccountInvite && len(app.Policies) > 0 {
+		useAuthorize, action, model = true, "invite", "teammate"
+	}
 	if useAuthorize {
 		b.WriteString("import { authorize } from '../middleware/authorize';\n")
 	}
+	// Plans with a say in the action check the signed-in user's plan
+	usePlan := ep.Auth && ep.Account == "" && planGates(app, inferRouteAction(ep.Name), inferRouteModel(ep.Name))
+	if usePlan {
+		b.WriteString("import { requirePlan } from '../middleware/plans';\n")
+	}
+	// Metered calls are counted, and refused past the user's quota
+	if ir.MetersEndpoint(app, ep) {
+		b.WriteString("import { meter } from '../middleware/metering';\n")
+	}
 
 	if needsBcrypt {
 		b.WriteString("import bcrypt from 'bcryptjs';\n")
 	}
+	if ep.Account == ir.AccountInvite {
+		b.WriteString("import { randomBytes } from 'crypto';\n")
+	}
 	if needsSignToken {
 		b.WriteString("import { signToken } from '../middleware/auth';\n")
 	}
@@ -81,14 +101,36 @@ func generateRoute(ep *ir.Endpoint, app *ir.Application) string {
 			}
 		}
 	}
+	if ep.Account == ir.AccountInvite && ir.EmailIntegration(app) != nil {
+		needsEmailImport = true
+	}
 	if needsEmailImport {
 		b.WriteString("import { sendEmail } from '../services/email';\n")
 	}
 	if needsMessagingImport {
 		b.WriteString("import { sendSlackMessage } from '../services/slack';\n")
 	}
+	if fns := smsFunctions(ep, app); len(fns) > 0 {
+		fmt.Fprintf(&b, "import { %s } from '../services/sms';\n", strings.Join(fns, ", "))
+	}
+	if len(triggeredNotifications(ep, app)) > 0 {
+		b.WriteString("import { notify } from '../services/notifications';\n")
+	}
+	if fns := documentFunctions(ep, app); len(fns) > 0 {
+		fmt.Fprintf(&b, "import { %s } from '../services/documents';\n", strings.Join(fns, ", "))
+	}
+	if ep.Aggregate != nil {
+		b.WriteString(aggregatesImport(app))
+	}
+	if ir.ConflictModel(app, ep) != nil {
+		b.WriteString("import { expectedVersion } from '../services/concurrency';\n")
+	}
+	if ep.Import != nil {
+		b.WriteString("import { ImportFileError, ImportRecord, ImportSummary, parseRecords } from '../services/imports';\n")
+	}
+	b.WriteString(prismaImport(app, "../services"))
 
-	b.WriteString("\nconst prisma = new PrismaClient();\n")
+	fmt.Fprintf(&b, "\nconst prisma = %s;\n", newPrismaClient(app))
 	b.WriteString("const router = Router();\n\n")
 
 	method := httpMethod(ep.Name)
@@ -98,9 +140,15 @@ func generateRoute(ep *ir.Endpoint, app *ir.Application) string {
 	if ep.Auth {
 		middlewares = append(middlewares, "authenticate")
 	}
+	if ir.MetersEndpoint(app, ep) {
+		middlewares = append(middlewares, "meter")
+	}
 	if useAuthorize {
 		middlewares = append(middlewares, fmt.Sprintf("authorize('%s', '%s')", action, model))
 	}
+	if usePlan {
+		middlewares = append(middlewares, fmt.Sprintf("requirePlan('%s', '%s')", inferRouteAction(ep.Name), inferRouteModel(ep.Name)))
+	}
 
 	// Route handler
 	if len(middlewares) > 0 {
@@ -129,7 +177,8 @@ func generateRoute(ep *ir.Endpoint, app *ir.Application) string {
 		if hasDefaultAssign {
 			binding = "let"
 		}
-		if method == "get" || method == "delete" {
+		// Deleting an account takes the password in the body, out of logs
+		if method == "get" || method == "delete" && ep.Account == "" {
 			fmt.Fprintf(&b, "    %s { %s } = req.query as Record<string, string>;\n", binding, strings.Join(paramNames, ", "))
 		} else {
 			fmt.Fprintf(&b, "    %s { %s } = req.body;\n", binding, strings.Join(paramNames, ", "))
@@ -146,10 +195,18 @@ func generateRoute(ep *ir.Endpoint, app *ir.Application) string {
 		b.WriteString("\n")
 	}
 
-	// Special-case Login: emit hand-crafted auth logic instead of generic steps
-	if isLogin {
+	// Reports, imports, account endpoints, and Login get hand-crafted
+	// bodies instead of generic steps
+	switch {
+	case ep.Account != "" && app.Accounts != nil:
+		writeAccountBody(&b, ep, app)
+	case ep.Aggregate != nil:
+		writeReportBody(&b, ep, app)
+	case ep.Import != nil && findModel(ep.Import.Model, app) != nil:
+		writeImportBody(&b, ep, app)
+	case isLogin:
 		writeLoginBody(&b, ep, app)
-	} else {
+	default:
 		// Steps as comments with skeleton code
 		resultIdx := 0
 		for _, step := range ep.Steps {
@@ -334,6 +391,10 @@ func writeStepCode(b *strings.Builder, step *ir.Action, ep *ir.Endpoint, app *ir
 	case "query":
 		// Skip query modifiers — emit as TODO comments only
 		if isQueryModifier(step.Text) {
+			if params := pagedParams(ep, app); params != nil && (strings.HasPrefix(strings.ToLower(step.Text), "paginate") || params.Covers(step)) {
+				fmt.Fprintf(b, "    // %s\n", step.Text)
+				return
+			}
 			fmt.Fprintf(b, "    // TODO: %s\n", step.Text)
 			return
 		}
@@ -351,9 +412,17 @@ func writeStepCode(b *strings.Builder, step *ir.Action, ep *ir.Endpoint, app *ir
 			} else {
 				fmt.Fprintf(b, "    %s = await prisma.%s.findUnique({ where: { id: req.body.id } });\n\n", varName, modelCamel)
 			}
+		} else if step == pagedStep(ep) {
+			writePagedQuery(b, varName, model, ep, app)
 		} else if ep.Auth && modelBelongsToUser(model, app) {
 			// Authenticated query on a model that belongs to User → scope by userId
-			fmt.Fprintf(b, "    %s = await prisma.%s.findMany({ where: { userId: req.userId } });\n\n", varName, modelCamel)
+			if order := listOrder(model, app); order != "" {
+				fmt.Fprintf(b, "    %s = await prisma.%s.findMany({ where: { userId: req.userId }, %s });\n\n", varName, modelCamel, order)
+			} else {
+				fmt.Fprintf(b, "    %s = await prisma.%s.findMany({ where: { userId: req.userId } });\n\n", varName, modelCamel)
+			}
+		} else if order := listOrder(model, app); order != "" {
+			fmt.Fprintf(b, "    %s = await prisma.%s.findMany({ %s });\n\n", varName, modelCamel, order)
 		} else {
 			fmt.Fprintf(b, "    %s = await prisma.%s.findMany();\n\n", varName, modelCamel)
 		}
@@ -378,21 +447,17 @@ func writeStepCode(b *strings.Builder, step *ir.Action, ep *ir.Endpoint, app *ir
 
 		varName := resultVarName(resultIdx)
 		fmt.Fprintf(b, "    // %s\n", step.Text)
+		if m := ir.ConflictModel(app, ep); m != nil && m == targetModel {
+			writeVersionedUpdate(b, varName, idParam, ep, m)
+			return
+		}
 		fmt.Fprintf(b, "    %s = await prisma.%s.update({\n", varName, modelCamel)
 		fmt.Fprintf(b, "      where: { id: %s },\n", idParam)
 		b.WriteString("      data: {\n")
-		for _, p := range ep.Params {
-			name := sanitizeParamName(p.Name)
-			if name == "task_id" || name == "user_id" || strings.HasSuffix(name, "Id") {
-				continue
-			}
-			// Map param name to Prisma field name
-			prismaField, paramRef := mapParamToPrismaField(p.Name, targetModel)
-			if prismaField != paramRef {
-				fmt.Fprintf(b, "        %s: %s,\n", prismaField, paramRef)
-			} else {
-				fmt.Fprintf(b, "        %s,\n", name)
-			}
+		writeUpdateFields(b, ep, targetModel)
+		// Any other change to a versioned record makes edits of it stale
+		if targetModel != nil && targetModel.Versioned {
+			fmt.Fprintf(b, "        %s: { increment: 1 },\n", ir.VersionColumn)
 		}
 		b.WriteString("      },\n")
 		b.WriteString("    });\n\n")
@@ -413,12 +478,16 @@ func writeStepCode(b *strings.Builder, step *ir.Action, ep *ir.Endpoint, app *ir
 		b.WriteString("    });\n\n")
 
 	case "respond":
+		writeNotifyCalls(b, ep, app, *resultIdx)
 		fmt.Fprintf(b, "    // %s\n", step.Text)
 		if isSignUp {
 			// SignUp response: include token
 			lastVar := lastResultVar(*resultIdx)
 			fmt.Fprintf(b, "    const token = signToken(%s.id, %s.role);\n", lastVar, lastVar)
 			fmt.Fprintf(b, "    return res.json({ data: %s, token });\n\n", lastVar)
+		} else if pagedStep(ep) != nil {
+			lastVar := lastResultVar(*resultIdx)
+			fmt.Fprintf(b, "    return res.json({ data: %s, pagination: { limit, nextCursor } });\n\n", lastVar)
 		} else {
 			lastVar := lastResultVar(*resultIdx)
 			fmt.Fprintf(b, "    return res.json({ data: %s });\n\n", lastVar)
@@ -439,6 +508,8 @@ func writeStepCode(b *strings.Builder, step *ir.Action, ep *ir.Endpoint, app *ir
 			b.WriteString("    await sendEmail({ to: result.email ?? req.body.email, subject: 'Notification', text: `Action completed successfully` });\n\n")
 		case "messaging":
 			b.WriteString("    await sendSlackMessage({ text: `Action completed successfully` });\n\n")
+		case "sms":
+			writeSMSCall(b, step, ep)
 		default:
 			b.WriteString("    // TODO: no matching integration service configured\n\n")
 		}
@@ -448,6 +519,27 @@ func writeStepCode(b *strings.Builder, step *ir.Action, ep *ir.Endpoint, app *ir
 
 	default:
 		fmt.Fprintf(b, "    // %s\n", step.Text)
+		if doc := ir.DocumentFor(app, step.Text); doc != nil {
+			writeDocumentCall(b, doc, step, ep, app)
+		}
+	}
+}
+
+// writeUpdateFields writes the fields an update sets from the endpoint's
+// parameters, leaving out the ids naming records.
+func writeUpdateFields(b *strings.Builder, ep *ir.Endpoint, targetModel *ir.DataModel) {
+	for _, p := range ep.Params {
+		name := sanitizeParamName(p.Name)
+		if name == "id" || strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "Id") {
+			continue
+		}
+		// Map param name to Prisma field name
+		prismaField, paramRef := mapParamToPrismaField(p.Name, targetModel)
+		if prismaField != paramRef {
+			fmt.Fprintf(b, "        %s: %s,\n", prismaField, paramRef)
+		} else {
+			fmt.Fprintf(b, "        %s,\n", name)
+		}
 	}
 }
 
@@ -760,6 +852,13 @@ func extractRespondMessage(text string) string {
 // detectSendIntegration matches a send step's text to a configured integration.
 func detectSendIntegration(stepText string, app *ir.Application) string {
 	lower := strings.ToLower(stepText)
+	if _, ok := ir.ParseSMSStep(stepText); ok {
+		for _, integ := range app.Integrations {
+			if integ.Type == "sms" {
+				return "sms"
+			}
+		}
+	}
 	for _, integ := range app.Integrations {
 		switch integ.Type {
 		case "email":
@@ -14,21 +14,18 @@ func generatePrismaSchema(app *ir.Application) string {
 	b.WriteString("// Generated by Human compiler — do not edit\n\n")
 
 	// Datasource
-	engine := "postgresql"
-	if app.Database != nil && app.Database.Engine != "" {
-		engine = strings.ToLower(app.Database.Engine)
-		if strings.Contains(engine, "postgres") {
-			engine = "postgresql"
-		} else if strings.Contains(engine, "mysql") {
-			engine = "mysql"
-		} else if strings.Contains(engine, "sqlite") {
-			engine = "sqlite"
-		}
-	}
+	engine := prismaProvider(app)
 
 	fmt.Fprintf(&b, "datasource db {\n")
-	fmt.Fprintf(&b, "  provider = \"%s\"\n", engine)
-	b.WriteString("  url      = env(\"DATABASE_URL\")\n")
+	if ir.PoolsConnections(app) {
+		// Schema changes bypass PgBouncer.
+		fmt.Fprintf(&b, "  provider  = \"%s\"\n", engine)
+		b.WriteString("  url       = env(\"DATABASE_URL\")\n")
+		b.WriteString("  directUrl = env(\"DIRECT_DATABASE_URL\")\n")
+	} else {
+		fmt.Fprintf(&b, "  provider = \"%s\"\n", engine)
+		b.WriteString("  url      = env(\"DATABASE_URL\")\n")
+	}
 	b.WriteString("}\n\n")
 
 	// Generator
@@ -67,12 +64,16 @@ func buildIndexMap(app *ir.Application) map[string][][]string {
 func writePrismaModel(b *strings.Builder, model *ir.DataModel, app *ir.Application, indexMap map[string][][]string) {
 	fmt.Fprintf(b, "model %s {\n", model.Name)
 
-	// id field
-	b.WriteString("  id        String   @id @default(cuid())\n")
+	// id field; MongoDB keeps a document's in _id
+	if prismaProvider(app) == "mongodb" {
+		b.WriteString("  id        String   @id @default(cuid()) @map(\"_id\")\n")
+	} else {
+		b.WriteString("  id        String   @id @default(cuid())\n")
+	}
 
 	// Fields
 	for _, f := range model.Fields {
-		writePrismaField(b, f, model)
+		writePrismaField(b, f, model, app)
 	}
 
 	// Relation fields
@@ -109,8 +110,16 @@ func writePrismaModel(b *strings.Builder, model *ir.DataModel, app *ir.Applicati
 	}
 
 	// Timestamp fields
-	b.WriteString("  createdAt DateTime @default(now())\n")
-	b.WriteString("  updatedAt DateTime @updatedAt\n")
+	if tz := prismaTimeAttr("datetime", app); tz != "" {
+		fmt.Fprintf(b, "  createdAt DateTime @default(now()) %s\n", tz)
+		fmt.Fprintf(b, "  updatedAt DateTime @updatedAt %s\n", tz)
+	} else {
+		b.WriteString("  createdAt DateTime @default(now())\n")
+		b.WriteString("  updatedAt DateTime @updatedAt\n")
+	}
+	if model.Versioned {
+		fmt.Fprintf(b, "  %-9s Int      @default(1)\n", ir.VersionColumn)
+	}
 
 	// Indexes from database config
 	if indexes, ok := indexMap[model.Name]; ok {
@@ -127,7 +136,7 @@ func writePrismaModel(b *strings.Builder, model *ir.DataModel, app *ir.Applicati
 }
 
 // writePrismaField writes a single field line in a Prisma model.
-func writePrismaField(b *strings.Builder, f *ir.DataField, model *ir.DataModel) {
+func writePrismaField(b *strings.Builder, f *ir.DataField, model *ir.DataModel, app *ir.Application) {
 	// Skip fields that will be represented as Prisma timestamps
 	lower := strings.ToLower(f.Name)
 	if lower == "created" || lower == "createdat" || lower == "updated" || lower == "updatedat" {
@@ -158,6 +167,10 @@ func writePrismaField(b *strings.Builder, f *ir.DataField, model *ir.DataModel)
 		attrs = append(attrs, fmt.Sprintf("@default(\"%s\")", f.Default))
 	}
 
+	if tz := prismaTimeAttr(f.Type, app); tz != "" {
+		attrs = append(attrs, tz)
+	}
+
 	line := fmt.Sprintf("  %-9s %s%s", name, pType, optional)
 	if len(attrs) > 0 {
 		line += " " + strings.Join(attrs, " ")
@@ -165,6 +178,23 @@ func writePrismaField(b *strings.Builder, f *ir.DataField, model *ir.DataModel)
 	b.WriteString(line + "\n")
 }
 
+// prismaTimeAttr returns the native type of a date or time column when the
+// app stores times in UTC: PostgreSQL keeps a time's zone with
+// timestamptz, and a date is only a date. Prisma's default, a timestamp
+// without a zone, is left to other databases and apps.
+func prismaTimeAttr(fieldType string, app *ir.Application) string {
+	if !ir.StoresUTC(app) || prismaProvider(app) != "postgresql" {
+		return ""
+	}
+	switch fieldType {
+	case "datetime":
+		return "@db.Timestamptz(3)"
+	case "date":
+		return "@db.Date"
+	}
+	return ""
+}
+
 // writePrismaRelation writes relation fields for a Prisma model.
 func writePrismaRelation(b *strings.Builder, rel *ir.Relation, model *ir.DataModel) {
 	switch rel.Kind {
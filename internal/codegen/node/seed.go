@@ -0,0 +1,138 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/barun-bash/human/internal/fixtures"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// jsIdentifier matches object keys that need no quotes.
+var jsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// generateSeed produces prisma/seed.ts, run with `npx prisma db seed`
+// (`human seed`): it upserts the app's seed records — the same records as
+// fixtures/ — model by model, belongs_to targets first, so running it again
+// leaves the records as they were generated. Password fields are set to
+// the bcrypt hash of ir.SeedPassword.
+func generateSeed(app *ir.Application) string {
+	set := fixtures.Seeded(app)
+	hashes := false
+	for _, model := range set.Models {
+		for _, f := range model.Fields {
+			hashes = hashes || isSeedPassword(f)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`// Generated by Human compiler — do not edit
+//
+// Seeds the development database with sample records, the same records as
+// fixtures/. Run with:
+//   npx prisma db seed
+// Running it again resets the records to these values.
+`)
+	if hashes {
+		fmt.Fprintf(&b, "// Seeded users sign in with the password %q.\n", ir.SeedPassword)
+	}
+	b.WriteString("\nimport { PrismaClient } from '@prisma/client';\n")
+	if hashes {
+		b.WriteString("import bcrypt from 'bcryptjs';\n")
+	}
+	if ir.UsesFieldEncryption(app) {
+		b.WriteString("import { withFieldEncryption } from '../src/services/encryption';\n\n")
+		b.WriteString("const prisma = withFieldEncryption(new PrismaClient());\n\n")
+	} else {
+		b.WriteString("\nconst prisma = new PrismaClient();\n\n")
+	}
+
+	b.WriteString("async function main() {\n")
+	if hashes {
+		fmt.Fprintf(&b, "  const password = await bcrypt.hash(%s, 12);\n", jsLiteral(ir.SeedPassword))
+	}
+	for i, model := range set.Models {
+		records := set.Records[model.Name]
+		if len(records) == 0 {
+			continue
+		}
+		if i > 0 || hashes {
+			b.WriteString("\n")
+		}
+		plural := toCamelCase(model.Name) + "s"
+		fmt.Fprintf(&b, "  const %s = [\n", plural)
+		for _, rec := range records {
+			fmt.Fprintf(&b, "    { %s },\n", strings.Join(seedProperties(model, rec), ", "))
+		}
+		b.WriteString("  ];\n")
+		fmt.Fprintf(&b, "  for (const data of %s) {\n", plural)
+		fmt.Fprintf(&b, "    await prisma.%s.upsert({ where: { id: data.id }, update: data, create: data });\n", toCamelCase(model.Name))
+		b.WriteString("  }\n")
+		fmt.Fprintf(&b, "  console.log(`Seeded ${%s.length} %s`);\n", plural, plural)
+	}
+	b.WriteString(`}
+
+main()
+  .catch((err) => {
+    console.error(err);
+    process.exitCode = 1;
+  })
+  .finally(() => prisma.$disconnect());
+`)
+	return b.String()
+}
+
+// seedProperties returns a fixture record as the properties of a Prisma
+// create input: the id, each field the schema keeps, and foreign keys.
+func seedProperties(model *ir.DataModel, rec map[string]any) []string {
+	props := []string{"id: " + jsLiteral(rec["id"])}
+	for _, f := range model.Fields {
+		lower := strings.ToLower(f.Name)
+		if lower == "created" || lower == "createdat" || lower == "updated" || lower == "updatedat" {
+			continue
+		}
+		value := jsLiteral(rec[f.Name])
+		switch {
+		case f.Name == "password":
+			props = append(props, "password")
+			continue
+		case isSeedPassword(f):
+			value = "password"
+		case f.Type == "date" || f.Type == "datetime":
+			value = "new Date(" + value + ")"
+		}
+		props = append(props, jsKey(f.Name)+": "+value)
+	}
+	for _, rel := range model.Relations {
+		key := fixtures.ForeignKey(rel.Target)
+		if v, ok := rec[key]; ok && rel.Kind == "belongs_to" {
+			props = append(props, jsKey(toCamelCase(rel.Target)+"Id")+": "+jsLiteral(v))
+		}
+	}
+	return props
+}
+
+// isSeedPassword reports whether a field holds a password hash, which the
+// seed sets from ir.SeedPassword rather than the fixture placeholder.
+func isSeedPassword(f *ir.DataField) bool {
+	return strings.EqualFold(f.Name, "password")
+}
+
+// jsKey returns name as an object key, quoted when it isn't an identifier.
+func jsKey(name string) string {
+	if jsIdentifier.MatchString(name) {
+		return name
+	}
+	return jsLiteral(name)
+}
+
+// jsLiteral returns a fixture value as a JavaScript literal.
+func jsLiteral(v any) string {
+	if s, ok := v.(string); ok {
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/barun-bash/human/internal/codegen/grpc"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -12,6 +13,8 @@ func generateServer(app *ir.Application) string {
 	var b strings.Builder
 
 	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	// Checked first, before any import reads the environment
+	b.WriteString("import './env';\n")
 	b.WriteString("import express from 'express';\n")
 	b.WriteString("import cors from 'cors';\n")
 	b.WriteString("import { router } from './routes';\n")
@@ -22,12 +25,67 @@ func generateServer(app *ir.Application) string {
 		b.WriteString("import passport from 'passport';\n")
 	}
 
+	if len(app.Experiments) > 0 {
+		b.WriteString("import { assignExperiments, experimentsRouter } from './middleware/experiments';\n")
+	}
+
+	if ir.AuditsRequests(app) {
+		b.WriteString("import { auditLog } from './middleware/audit';\n")
+	}
+
+	if ir.InjectsChaos(app) {
+		b.WriteString("import { chaos } from './middleware/chaos';\n")
+	}
+	b.WriteString("import { record } from './middleware/record';\n")
+	if ir.ValidatesRequests(app) {
+		b.WriteString("import { validateRequests } from './middleware/openapi';\n")
+	}
+
+	masks := len(ir.PersonalFieldNames(app)) > 0
+	if masks {
+		b.WriteString("import { installLogMasking, maskErrorResponses } from './middleware/masking';\n")
+	}
+
+	if grpc.IsEnabled(app) {
+		b.WriteString("import { startGrpcServer } from './grpc/server';\n")
+	}
+
+	// Mask personal fields in everything logged from here on
+	if masks {
+		b.WriteString("\ninstallLogMasking();\n")
+	}
+
+	// The server's clock reads UTC, so a time written without an offset is
+	// taken as UTC
+	if ir.StoresUTC(app) {
+		b.WriteString("\n// Times are kept in UTC and sent as ISO 8601\nprocess.env.TZ = 'UTC';\n")
+	}
+
 	b.WriteString("\nconst app = express();\n")
 	fmt.Fprintf(&b, "const PORT = process.env.PORT || %d;\n\n", 3001)
 
 	// Core middleware
 	b.WriteString("// Middleware\n")
-	b.WriteString("app.use(cors());\n")
+	// Every response names the build it came from (build-info.json)
+	if app.Build != nil {
+		fmt.Fprintf(&b, "app.use((_req, res, next) => {\n  res.setHeader('%s', '%s');\n  next();\n});\n", ir.BuildHeader, app.Build.Header())
+	}
+	// Built for an environment, only its frontend may call the API
+	if origin := ir.AllowedOrigin(app); origin != "" {
+		fmt.Fprintf(&b, "app.use(cors({ origin: '%s', credentials: true }));\n", origin)
+	} else {
+		b.WriteString("app.use(cors());\n")
+	}
+	// Imported files are read as text, CSV or JSON (before the json middleware)
+	for _, ep := range app.APIs {
+		if ep.Import != nil {
+			fmt.Fprintf(&b, "app.use('/api%s', express.text({ type: ['text/csv', 'text/plain', 'application/json'], limit: '%dmb' }));\n", routePath(ep.Name), ir.MaxImportBytes>>20)
+		}
+	}
+	// Stripe signs the billing webhook's raw body
+	if ir.Bills(app) {
+		b.WriteString("app.use('/api/billing/webhook', express.raw({ type: 'application/json' }));\n")
+	}
 	b.WriteString("app.use(express.json());\n")
 
 	// Raw body parsing for webhooks (must be before json middleware for specific routes)
@@ -35,6 +93,36 @@ func generateServer(app *ir.Application) string {
 		b.WriteString("app.use('/api/webhooks', express.raw({ type: 'application/json' }));\n")
 	}
 
+	// Personal fields in error responses
+	if masks {
+		b.WriteString("app.use(maskErrorResponses);\n")
+	}
+
+	// Audit log of requests that change data
+	if ir.AuditsRequests(app) {
+		b.WriteString("app.use(auditLog);\n")
+	}
+
+	// Sticky A/B variant assignment
+	if len(app.Experiments) > 0 {
+		b.WriteString("app.use(assignExperiments);\n")
+	}
+
+	// Requests recorded for replay (human run --record), with the
+	// responses chaos mode gives them
+	b.WriteString("app.use('/api', record);\n")
+
+	// Requests checked against the OpenAPI document, once their bodies are
+	// parsed
+	if ir.ValidatesRequests(app) {
+		b.WriteString("app.use(validateRequests);\n")
+	}
+
+	// Failures injected in chaos mode (human run --chaos)
+	if ir.InjectsChaos(app) {
+		b.WriteString("app.use('/api', chaos);\n")
+	}
+
 	// Passport initialization
 	if hasOAuthIntegration(app) {
 		b.WriteString("\n// OAuth\n")
@@ -58,6 +146,36 @@ func generateServer(app *ir.Application) string {
 	if hasStorageIntegration(app) {
 		b.WriteString("app.use('/api', require('./routes/upload').router);\n")
 	}
+	if len(app.Experiments) > 0 {
+		b.WriteString("app.use('/api', experimentsRouter);\n")
+	}
+	if ir.Bills(app) {
+		b.WriteString("app.use('/api/billing', require('./routes/billing').router);\n")
+	}
+	if ir.Meters(app) {
+		b.WriteString("app.use('/api/usage', require('./routes/usage').router);\n")
+	}
+	if ir.IssuesAPIKeys(app) {
+		b.WriteString("app.use('/api/keys', require('./routes/apiKeys').router);\n")
+	}
+	if len(app.Notifications) > 0 {
+		b.WriteString("app.use('/api/notifications', require('./routes/notifications').router);\n")
+	}
+	if len(app.Calendars) > 0 {
+		b.WriteString("app.use('/api/calendar', require('./routes/calendar').router);\n")
+	}
+	if len(app.Documents) > 0 {
+		b.WriteString("app.use('/api/documents', require('./routes/documents').router);\n")
+	}
+	if len(app.Charts) > 0 {
+		b.WriteString("app.use('/api/charts', require('./routes/charts').router);\n")
+	}
+	if len(app.Reorders) > 0 {
+		b.WriteString("app.use('/api/reorder', require('./routes/reorder').router);\n")
+	}
+	if app.Sitemap != nil {
+		b.WriteString("app.use('/api', require('./routes/sitemap').router);\n")
+	}
 
 	b.WriteString("\n")
 
@@ -76,6 +194,12 @@ func generateServer(app *ir.Application) string {
 	b.WriteString("  app.listen(PORT, () => {\n")
 	fmt.Fprintf(&b, "    console.log(`%s server running on port ${PORT}`);\n", appName(app))
 	b.WriteString("  });\n")
+	if grpc.IsEnabled(app) {
+		b.WriteString("  startGrpcServer(app).catch((err) => {\n")
+		b.WriteString("    console.error('Failed to start gRPC server:', err);\n")
+		b.WriteString("    process.exit(1);\n")
+		b.WriteString("  });\n")
+	}
 	b.WriteString("}\n\n")
 	b.WriteString("export { app };\n")
 
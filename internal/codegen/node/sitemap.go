@@ -0,0 +1,111 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateSitemapRoutes produces src/routes/sitemap.ts: sitemap.xml, with
+// the public pages and a URL per record of each public model, and
+// robots.txt, which keeps crawlers out of the API and private pages. Both
+// are mounted under /api; the frontend's server proxies /sitemap.xml and
+// /robots.txt to them.
+func generateSitemapRoutes(app *ir.Application) string {
+	sm := app.Sitemap
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { Router, Request, Response, NextFunction } from 'express';\n")
+	if len(sm.Records) > 0 {
+		b.WriteString("import { PrismaClient } from '@prisma/client';\n")
+		b.WriteString(prismaImport(app, "../services"))
+		fmt.Fprintf(&b, "\nconst prisma = %s;\n", newPrismaClient(app))
+	} else {
+		b.WriteString("\n")
+	}
+	b.WriteString("const router = Router();\n\n")
+
+	pages := make([]string, len(sm.Pages))
+	for i, p := range sm.Pages {
+		pages[i] = "'" + p + "'"
+	}
+	fmt.Fprintf(&b, "const PAGES = [%s];\n", strings.Join(pages, ", "))
+	b.WriteString("// A sitemap holds at most 50,000 URLs.\n")
+	b.WriteString("const MAX_URLS = 50000;\n\n")
+
+	b.WriteString("/** The canonical origin: SITE_URL, else the declared production URL, else the request's host. */\n")
+	b.WriteString("function siteUrl(req: Request): string {\n")
+	if sm.URL != "" {
+		fmt.Fprintf(&b, "  return (process.env.SITE_URL || '%s').replace(/\\/$/, '');\n", sm.URL)
+	} else {
+		b.WriteString("  return (process.env.SITE_URL || `${req.protocol}://${req.get('host')}`).replace(/\\/$/, '');\n")
+	}
+	b.WriteString("}\n")
+
+	b.WriteString(`
+function escapeXml(value: string): string {
+  return value
+    .replace(/&/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;')
+    .replace(/'/g, '&apos;');
+}
+
+function urlEntry(loc: string, lastmod?: Date): string {
+  const mod = lastmod ? ` + "`<lastmod>${lastmod.toISOString().slice(0, 10)}</lastmod>`" + ` : '';
+  return ` + "`  <url><loc>${escapeXml(loc)}</loc>${mod}</url>`" + `;
+}
+
+router.get('/sitemap.xml', async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const origin = siteUrl(req);
+    const urls = PAGES.map((path) => urlEntry(origin + path));
+`)
+	for _, rec := range sm.Records {
+		fmt.Fprintf(&b, "    const %s = await prisma.%s.findMany({ select: { id: true, updatedAt: true }, take: MAX_URLS });\n",
+			toCamelCase(rec.Model)+"s", toCamelCase(rec.Model))
+		fmt.Fprintf(&b, "    urls.push(...%s.map((r: any) => urlEntry(`${origin}%s${r.id}`, r.updatedAt)));\n",
+			toCamelCase(rec.Model)+"s", rec.Link())
+	}
+	b.WriteString(`    res.type('application/xml');
+    res.send([
+      '<?xml version="1.0" encoding="UTF-8"?>',
+      '<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">',
+      ...urls.slice(0, MAX_URLS),
+      '</urlset>',
+      '',
+    ].join('\n'));
+  } catch (error) {
+    next(error);
+  }
+});
+`)
+
+	b.WriteString("\nrouter.get('/robots.txt', (req: Request, res: Response) => {\n")
+	b.WriteString("  const lines = [\n")
+	for _, line := range robotsRules(sm) {
+		fmt.Fprintf(&b, "    '%s',\n", line)
+	}
+	b.WriteString("    '',\n")
+	b.WriteString("    `Sitemap: ${siteUrl(req)}/sitemap.xml`,\n")
+	b.WriteString("    '',\n")
+	b.WriteString("  ];\n")
+	b.WriteString("  res.type('text/plain').send(lines.join('\\n'));\n")
+	b.WriteString("});\n")
+
+	b.WriteString("\nexport { router };\n")
+	return b.String()
+}
+
+// robotsRules returns robots.txt's rules: every crawler stays out of the
+// API and the pages that need signing in.
+func robotsRules(sm *ir.Sitemap) []string {
+	lines := []string{"User-agent: *", "Disallow: /api/"}
+	for _, p := range sm.Private {
+		lines = append(lines, "Disallow: "+p)
+	}
+	return lines
+}
@@ -0,0 +1,227 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// twilioEnv returns the environment variable for the first credential whose
+// key mentions one of the keywords ("account sid" → TWILIO_ACCOUNT_SID).
+func twilioEnv(integ *ir.Integration, fallback string, keywords ...string) string {
+	keys := make([]string, 0, len(integ.Credentials))
+	for key := range integ.Credentials {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, kw := range keywords {
+			if strings.Contains(strings.ToLower(key), kw) {
+				return integ.Credentials[key]
+			}
+		}
+	}
+	return fallback
+}
+
+// smsTemplateEnv names the environment variable holding a template's
+// Twilio Content SID: "order-shipped" → TWILIO_TEMPLATE_ORDER_SHIPPED.
+func smsTemplateEnv(name string) string {
+	return "TWILIO_TEMPLATE_" + strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(name))
+}
+
+// generateSMSService produces src/services/sms.ts: SMS and WhatsApp
+// messages through Twilio, retried per the app's error handlers. In sandbox
+// mode messages are recorded in an outbox instead of sent.
+func generateSMSService(integ *ir.Integration, app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n")
+	fmt.Fprintf(&b, "// Integration: %s (sms)\n\n", integ.Service)
+
+	b.WriteString("import twilio from \"twilio\";\n\n")
+
+	sidEnv := twilioEnv(integ, "TWILIO_ACCOUNT_SID", "sid", "account")
+	tokenEnv := twilioEnv(integ, "TWILIO_AUTH_TOKEN", "token", "secret")
+	fromEnv := twilioEnv(integ, "TWILIO_FROM_NUMBER", "from", "number", "phone")
+	defaultFrom := integ.Config["sender_email"]
+
+	if defaultFrom != "" {
+		fmt.Fprintf(&b, "const FROM_NUMBER = process.env.%s || \"%s\";\n", fromEnv, defaultFrom)
+	} else {
+		fmt.Fprintf(&b, "const FROM_NUMBER = process.env.%s || \"\";\n", fromEnv)
+	}
+	b.WriteString("const WHATSAPP_FROM = process.env.TWILIO_WHATSAPP_FROM || FROM_NUMBER;\n\n")
+
+	attempts, delayMs := ir.SMSRetryPolicy(app)
+	b.WriteString("// Retry policy, from the app's error handlers.\n")
+	fmt.Fprintf(&b, "const ATTEMPTS = %d;\n", attempts)
+	fmt.Fprintf(&b, "const RETRY_DELAY_MS = %d;\n\n", delayMs)
+
+	b.WriteString("// Twilio Content SIDs of the approved message templates.\n")
+	b.WriteString("export const SMS_TEMPLATES: Record<string, string> = {\n")
+	for _, tpl := range integ.Templates {
+		fmt.Fprintf(&b, "  \"%s\": process.env.%s || \"\",\n", tpl, smsTemplateEnv(tpl))
+	}
+	b.WriteString("};\n\n")
+
+	b.WriteString(`export interface SentMessage {
+  channel: "sms" | "whatsapp";
+  to: string;
+  body?: string;
+  template?: string;
+  variables?: Record<string, string>;
+}
+
+// In sandbox mode — under Jest, or with TWILIO_SANDBOX=true — messages are
+// recorded in outbox instead of sent, so tests never text real phones.
+export const outbox: SentMessage[] = [];
+
+function isSandbox(): boolean {
+  return process.env.TWILIO_SANDBOX === "true" || process.env.NODE_ENV === "test";
+}
+
+let client: ReturnType<typeof twilio> | null = null;
+
+`)
+	fmt.Fprintf(&b, "function getClient(): ReturnType<typeof twilio> {\n")
+	fmt.Fprintf(&b, "  if (!client) {\n")
+	fmt.Fprintf(&b, "    client = twilio(process.env.%s || \"\", process.env.%s || \"\");\n", sidEnv, tokenEnv)
+	b.WriteString("  }\n")
+	b.WriteString("  return client;\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(`async function deliver(message: SentMessage): Promise<void> {
+  if (isSandbox()) {
+    outbox.push(message);
+    return;
+  }
+
+  const whatsapp = message.channel === "whatsapp";
+  const params = {
+    from: whatsapp ? ` + "`whatsapp:${WHATSAPP_FROM}`" + ` : FROM_NUMBER,
+    to: whatsapp ? ` + "`whatsapp:${message.to}`" + ` : message.to,
+    ...(message.template
+      ? { contentSid: SMS_TEMPLATES[message.template], contentVariables: JSON.stringify(message.variables ?? {}) }
+      : { body: message.body }),
+  };
+
+  let lastError: unknown;
+  for (let attempt = 1; attempt <= ATTEMPTS; attempt++) {
+    try {
+      await getClient().messages.create(params);
+      return;
+    } catch (err) {
+      lastError = err;
+      if (attempt < ATTEMPTS) {
+        await new Promise((resolve) => setTimeout(resolve, RETRY_DELAY_MS));
+      }
+    }
+  }
+  throw lastError;
+}
+
+export async function sendSMS(to: string, body: string): Promise<void> {
+  await deliver({ channel: "sms", to, body });
+}
+
+export async function sendWhatsApp(to: string, body: string): Promise<void> {
+  await deliver({ channel: "whatsapp", to, body });
+}
+
+export async function sendTemplate(
+  channel: "sms" | "whatsapp",
+  to: string,
+  template: string,
+  variables: Record<string, string> = {},
+): Promise<void> {
+  await deliver({ channel, to, template, variables });
+}
+`)
+
+	return b.String()
+}
+
+// generateSMSTests produces src/__tests__/sms.test.ts, which exercises the
+// SMS service against its sandbox outbox.
+func generateSMSTests() string {
+	return `// Generated by Human compiler — do not edit
+
+import { outbox, sendSMS, sendTemplate, sendWhatsApp } from '../services/sms';
+
+describe('SMS service (sandbox)', () => {
+  beforeEach(() => {
+    outbox.length = 0;
+  });
+
+  it('records SMS instead of sending them', async () => {
+    await sendSMS('+15555550100', 'Your order has shipped');
+    expect(outbox).toEqual([{ channel: 'sms', to: '+15555550100', body: 'Your order has shipped' }]);
+  });
+
+  it('records WhatsApp messages', async () => {
+    await sendWhatsApp('+15555550100', 'Hello');
+    expect(outbox[0].channel).toBe('whatsapp');
+  });
+
+  it('records template messages with their variables', async () => {
+    await sendTemplate('sms', '+15555550100', 'reminder', { name: 'Ada' });
+    expect(outbox[0]).toMatchObject({ template: 'reminder', variables: { name: 'Ada' } });
+  });
+});
+`
+}
+
+// smsFunctions returns the sms.ts functions an endpoint's send steps call.
+func smsFunctions(ep *ir.Endpoint, app *ir.Application) []string {
+	used := map[string]bool{}
+	for _, step := range ep.Steps {
+		if step.Type != "send" || detectSendIntegration(step.Text, app) != "sms" {
+			continue
+		}
+		msg, _ := ir.ParseSMSStep(step.Text)
+		switch {
+		case msg.Template != "":
+			used["sendTemplate"] = true
+		case msg.Channel == "whatsapp":
+			used["sendWhatsApp"] = true
+		default:
+			used["sendSMS"] = true
+		}
+	}
+	var fns []string
+	for _, fn := range []string{"sendSMS", "sendTemplate", "sendWhatsApp"} {
+		if used[fn] {
+			fns = append(fns, fn)
+		}
+	}
+	return fns
+}
+
+// writeSMSCall emits the sms.ts call for a "send SMS ..." step. The
+// recipient is the endpoint's phone param, else the request's phone field.
+func writeSMSCall(b *strings.Builder, step *ir.Action, ep *ir.Endpoint) {
+	msg, _ := ir.ParseSMSStep(step.Text)
+	to := "req.body.phone"
+	if param := ir.PhoneParam(ep); param != "" {
+		to = sanitizeParamName(param)
+	}
+	switch {
+	case msg.Template != "":
+		fmt.Fprintf(b, "    await sendTemplate('%s', %s, '%s');\n\n", msg.Channel, to, escapeQuote(msg.Template))
+	case msg.Channel == "whatsapp":
+		fmt.Fprintf(b, "    await sendWhatsApp(%s, '%s');\n\n", to, escapeQuote(smsBody(msg)))
+	default:
+		fmt.Fprintf(b, "    await sendSMS(%s, '%s');\n\n", to, escapeQuote(smsBody(msg)))
+	}
+}
+
+// smsBody returns the message text, defaulting like the other send steps.
+func smsBody(msg *ir.SMSMessage) string {
+	if msg.Body != "" {
+		return msg.Body
+	}
+	return "Action completed successfully"
+}
@@ -0,0 +1,431 @@
+// Package openapi generates an OpenAPI 3.1 document describing an app's
+// REST API: a path per endpoint with its method, parameters, request body
+// and validation constraints, the security schemes protecting it, and
+// response schemas derived from the data models it returns. Clients
+// generate SDKs and mock servers from it with any OpenAPI tool.
+package openapi
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Generator produces the OpenAPI document.
+type Generator struct{}
+
+// Generate writes openapi.yaml and a README to outputDir.
+func (g Generator) Generate(app *ir.Application, outputDir string) error {
+	files := map[string]string{
+		filepath.Join(outputDir, "openapi.yaml"): generateSpec(app),
+		filepath.Join(outputDir, "README.md"):    generateReadme(app),
+	}
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
+}
+
+// Spec returns the app's OpenAPI document, as openapi/openapi.yaml holds
+// it. The backends keep a copy to validate requests against when
+// ir.ValidatesRequests.
+func Spec(app *ir.Application) string {
+	return generateSpec(app)
+}
+
+// Security scheme names.
+const (
+	bearerScheme = "bearerAuth"
+	apiKeyScheme = "apiKeyAuth"
+)
+
+func generateSpec(app *ir.Application) string {
+	s := stackOf(app)
+	ops := operations(app)
+
+	doc := object{
+		{"openapi", "3.1.0"},
+		{"info", object{
+			{"title", appName(app) + " API"},
+			{"version", "1.0.0"},
+			{"description", fmt.Sprintf("REST API of %s, generated from its .human file. Successful responses wrap their result in `data`; errors carry a message in `%s`.", appName(app), s.errorKey())},
+		}},
+		{"servers", servers(app)},
+	}
+
+	// Endpoints sharing a path are grouped under it, in declaration order
+	paths := object{}
+	index := map[string]int{}
+	models := map[*ir.DataModel]bool{}
+	for _, op := range ops {
+		i, ok := index[op.Path]
+		if !ok {
+			i = len(paths)
+			index[op.Path] = i
+			paths = append(paths, member{op.Path, object{}})
+		}
+		paths[i].Value = append(paths[i].Value.(object), member{op.Method, operationObject(op, app, s)})
+		if op.Model != nil {
+			models[op.Model] = true
+		}
+	}
+	doc = append(doc, member{"paths", paths})
+
+	schemas := object{}
+	for _, m := range app.Data {
+		if models[m] {
+			schemas = append(schemas, member{m.Name, modelSchema(m)})
+		}
+	}
+	schemas = append(schemas,
+		member{"Pagination", object{
+			{"type", "object"},
+			{"properties", object{
+				{"limit", object{{"type", "integer"}}},
+				{"nextCursor", object{{"type", []any{"string", "null"}}, {"description", "Pass as `cursor` to get the next page; null on the last page"}}},
+			}},
+			{"required", []any{"limit", "nextCursor"}},
+		}},
+		member{"Error", object{
+			{"type", "object"},
+			{"properties", object{{s.errorKey(), object{{"type", "string"}}}}},
+			{"required", []any{s.errorKey()}},
+		}},
+	)
+	components := object{{"schemas", schemas}}
+	if securitySchemes := securitySchemes(app); len(securitySchemes) > 0 {
+		components = append(components, member{"securitySchemes", securitySchemes})
+	}
+	doc = append(doc, member{"components", components})
+
+	return "# Generated by Human compiler — do not edit\n" + encodeYAML(doc)
+}
+
+// servers lists the development server and each environment with a URL.
+func servers(app *ir.Application) []any {
+	list := []any{object{
+		{"url", "http://localhost:" + docker.BackendPort(app)},
+		{"description", "Development"},
+	}}
+	for _, env := range app.Environments {
+		if u := env.URL(); u != "" {
+			list = append(list, object{{"url", u}, {"description", ir.FieldLabel(env.Name)}})
+		}
+	}
+	return list
+}
+
+func securitySchemes(app *ir.Application) object {
+	schemes := object{}
+	if app.Auth != nil || requiresAuth(app) {
+		schemes = append(schemes, member{bearerScheme, object{
+			{"type", "http"},
+			{"scheme", "bearer"},
+			{"bearerFormat", "JWT"},
+			{"description", "The token sign-up and login respond with"},
+		}})
+	}
+	if ir.IssuesAPIKeys(app) {
+		schemes = append(schemes, member{apiKeyScheme, object{
+			{"type", "apiKey"},
+			{"in", "header"},
+			{"name", ir.APIKeyHeader},
+			{"description", "A key created under /api/keys; read keys can only make GET requests"},
+		}})
+	}
+	return schemes
+}
+
+func requiresAuth(app *ir.Application) bool {
+	for _, ep := range app.APIs {
+		if ep.Auth {
+			return true
+		}
+	}
+	return false
+}
+
+func operationObject(op operation, app *ir.Application, s stack) object {
+	ep := op.Endpoint
+	o := object{
+		{"operationId", toCamelCase(ep.Name)},
+		{"summary", ir.FieldLabel(ep.Name)},
+	}
+	if op.Model != nil {
+		o = append(o, member{"tags", []any{op.Model.Name}})
+	}
+	if ep.Auth {
+		security := []any{object{{bearerScheme, []any{}}}}
+		if ir.IssuesAPIKeys(app) {
+			security = append(security, object{{apiKeyScheme, []any{}}})
+		}
+		o = append(o, member{"security", security})
+	}
+
+	var params []any
+	if op.Query {
+		for _, p := range ep.Params {
+			prop, required := paramSchema(p, op, s)
+			params = append(params, object{
+				{"name", s.paramName(p.Name)},
+				{"in", "query"},
+				{"required", required},
+				{"schema", prop},
+			})
+		}
+	}
+	params = append(params, listParameters(op, app)...)
+	if len(params) > 0 {
+		o = append(o, member{"parameters", params})
+	}
+	if body := requestBody(op, s); body != nil {
+		o = append(o, member{"requestBody", body})
+	}
+	o = append(o, member{"responses", responses(op, app)})
+	return o
+}
+
+// requestBody describes the JSON body an endpoint's parameters are sent in.
+func requestBody(op operation, s stack) object {
+	ep := op.Endpoint
+	if op.Query || len(ep.Params) == 0 {
+		return nil
+	}
+	props := object{}
+	var required []any
+	for _, p := range ep.Params {
+		prop, req := paramSchema(p, op, s)
+		name := s.paramName(p.Name)
+		props = append(props, member{name, prop})
+		if req {
+			required = append(required, name)
+		}
+	}
+	schema := object{{"type", "object"}, {"properties", props}}
+	if len(required) > 0 {
+		schema = append(schema, member{"required", required})
+	}
+	return object{
+		{"required", true},
+		{"content", object{{"application/json", object{{"schema", schema}}}}},
+	}
+}
+
+// paramSchema returns the schema of an endpoint parameter, typed by the
+// model field it names and constrained by the endpoint's checks, and
+// whether it is required.
+func paramSchema(p *ir.Param, op operation, s stack) (object, bool) {
+	irType, enum := "text", []string(nil)
+	if op.Model != nil {
+		if f := fieldNamed(op.Model, p.Name); f != nil {
+			irType, enum = f.Type, f.EnumValues
+		}
+	}
+	schema := baseFieldSchema(irType, enum)
+	// Gin binds every parameter not named optional as required
+	required := s == stackGo && !strings.HasPrefix(strings.ToLower(p.Name), "optional")
+
+	var notes []string
+	for _, v := range op.Endpoint.Validation {
+		if !sameName(v.Field, p.Name) {
+			continue
+		}
+		switch v.Rule {
+		case "not_empty":
+			required = true
+			if isString(schema) {
+				schema = append(schema, member{"minLength", 1})
+			}
+		case "valid_email":
+			schema = object{{"type", "string"}, {"format", "email"}}
+		case "min_length":
+			if n, err := strconv.Atoi(v.Value); err == nil {
+				schema = append(schema, member{"minLength", n})
+			}
+		case "max_length":
+			if n, err := strconv.Atoi(v.Value); err == nil {
+				schema = append(schema, member{"maxLength", n})
+			}
+		case "unique":
+			notes = append(notes, "Must not already be taken")
+		case "future_date":
+			notes = append(notes, "Must be in the future")
+		}
+	}
+	if len(notes) > 0 {
+		schema = append(schema, member{"description", strings.Join(notes, ". ")})
+	}
+	return schema, required
+}
+
+// listParameters returns the query parameters a paginated list reads:
+// its page, sort order, search, and filters.
+func listParameters(op operation, app *ir.Application) []any {
+	ep := op.Endpoint
+	if ep.PageSize == 0 || op.Model == nil {
+		return nil
+	}
+	query := func(name, description string, schema object) object {
+		return object{{"name", name}, {"in", "query"}, {"description", description}, {"schema", schema}}
+	}
+	params := []any{
+		query("limit", fmt.Sprintf("Records per page (%d by default)", ep.PageSize),
+			object{{"type", "integer"}, {"minimum", 1}, {"maximum", 100}, {"default", ep.PageSize}}),
+		query("cursor", "The nextCursor of the previous page", object{{"type", "string"}}),
+	}
+	lp := ir.ListParamsFor(app, ep, op.Model.Name)
+	if lp == nil {
+		return params
+	}
+	params = append(params, query("page", "Page number, paging by offset", object{{"type", "integer"}, {"minimum", 1}}))
+	if len(lp.Sortable) > 0 {
+		var fields []any
+		for _, f := range lp.Sortable {
+			fields = append(fields, f.Name)
+		}
+		params = append(params,
+			query("sort", "Field to order the records by", object{{"type", "string"}, {"enum", fields}}),
+			query("order", "Sort direction", object{{"type", "string"}, {"enum", []any{"asc", "desc"}}}),
+		)
+	}
+	if len(lp.Search) > 0 {
+		var names []string
+		for _, f := range lp.Search {
+			names = append(names, f.Name)
+		}
+		params = append(params, query("q", "Text to search "+strings.Join(names, ", ")+" for, ignoring case", object{{"type", "string"}}))
+	}
+	for _, f := range lp.Filters {
+		params = append(params, query(f.Name, "Only records with this "+ir.FieldLabel(f.Name), baseFieldSchema(f.Type, f.EnumValues)))
+	}
+	return params
+}
+
+// responses describes the endpoint's success response and the errors it
+// can answer with.
+func responses(op operation, app *ir.Application) object {
+	ep := op.Endpoint
+	data := object{}
+	switch {
+	case ep.Aggregate != nil:
+		data = object{{"type", "array"}, {"items", object{
+			{"type", "object"},
+			{"properties", object{
+				{"label", object{{"type", "string"}}},
+				{"value", object{{"type", "number"}}},
+			}},
+			{"required", []any{"label", "value"}},
+		}}}
+	case op.Model != nil && op.List:
+		data = object{{"type", "array"}, {"items", schemaRef(op.Model.Name)}}
+	case op.Model != nil:
+		data = schemaRef(op.Model.Name)
+	}
+	props := object{{"data", data}}
+	if isLogin(ep.Name) || isSignUp(ep.Name) {
+		props = append(props, member{"token", object{{"type", "string"}, {"description", "JWT to send as a bearer token"}}})
+	}
+	if ep.PageSize > 0 && op.List {
+		props = append(props, member{"pagination", schemaRef("Pagination")})
+	}
+
+	status := "200"
+	if op.Created {
+		status = "201"
+	}
+	out := object{{status, object{
+		{"description", "Success"},
+		{"content", object{{"application/json", object{{"schema", object{
+			{"type", "object"},
+			{"properties", props},
+		}}}}}},
+	}}}
+
+	errorResponse := func(code, description string) {
+		out = append(out, member{code, object{
+			{"description", description},
+			{"content", object{{"application/json", object{{"schema", schemaRef("Error")}}}}},
+		}})
+	}
+	if len(ep.Params) > 0 || len(ep.Validation) > 0 {
+		errorResponse("400", "Invalid input")
+	}
+	if ep.Auth || isLogin(ep.Name) {
+		errorResponse("401", "Not signed in, or invalid credentials")
+	}
+	for _, v := range ep.Validation {
+		if v.Rule == "unique" {
+			errorResponse("409", "Already taken")
+			break
+		}
+	}
+	if ir.MetersEndpoint(app, ep) {
+		errorResponse("429", "The user's quota is used up")
+	}
+	return out
+}
+
+func fieldNamed(model *ir.DataModel, name string) *ir.DataField {
+	for _, f := range model.Fields {
+		if sameName(f.Name, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// sameName compares names ignoring case, spaces, and underscores, so
+// "due date", "due_date", and "dueDate" all match.
+func sameName(a, b string) bool {
+	norm := func(s string) string {
+		return strings.NewReplacer(" ", "", "_", "").Replace(strings.ToLower(s))
+	}
+	return norm(a) == norm(b)
+}
+
+func isString(schema object) bool {
+	for _, m := range schema {
+		if m.Key == "type" {
+			return m.Value == "string"
+		}
+	}
+	return false
+}
+
+func generateReadme(app *ir.Application) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s API\n\n", appName(app))
+	b.WriteString("Generated by the Human compiler from the app's APIs. ")
+	b.WriteString("`openapi.yaml` is an OpenAPI 3.1 document describing every endpoint: its method and path, parameters and their validation, ")
+	b.WriteString("how it authenticates, and the data models it responds with.\n\n")
+
+	b.WriteString("## Endpoints\n\n")
+	b.WriteString("| Method | Path | Operation | Auth |\n")
+	b.WriteString("|--------|------|-----------|------|\n")
+	for _, op := range operations(app) {
+		auth := ""
+		if op.Endpoint.Auth {
+			auth = "required"
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | `%s` | %s |\n", strings.ToUpper(op.Method), op.Path, toCamelCase(op.Endpoint.Name), auth)
+	}
+
+	b.WriteString("\n## Generating clients\n\n")
+	b.WriteString("```bash\n")
+	b.WriteString("npx @openapitools/openapi-generator-cli generate -i openapi.yaml -g typescript-fetch -o client\n")
+	b.WriteString("npx openapi-typescript openapi.yaml -o api.d.ts\n")
+	b.WriteString("```\n\n")
+	b.WriteString("`human sdk` generates a ready-made client for TypeScript, Python, or Go instead.\n")
+
+	return b.String()
+}
+
+func appName(app *ir.Application) string {
+	if app.Name == "" {
+		return "App"
+	}
+	return app.Name
+}
@@ -0,0 +1,181 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func testApp() *ir.Application {
+	return &ir.Application{
+		Name:   "Shop",
+		Config: &ir.BuildConfig{Backend: "Node with Express"},
+		Auth:   &ir.Auth{Methods: []*ir.AuthMethod{{Type: "jwt"}}},
+		Data: []*ir.DataModel{
+			{
+				Name: "Order",
+				Fields: []*ir.DataField{
+					{Name: "total", Type: "decimal", Required: true},
+					{Name: "status", Type: "enum", EnumValues: []string{"placed", "shipped"}, Required: true},
+					{Name: "notes", Type: "text"},
+				},
+				Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}},
+			},
+			{Name: "User", Fields: []*ir.DataField{{Name: "email", Type: "email", Required: true}, {Name: "password", Type: "text", Required: true}}},
+		},
+		APIs: []*ir.Endpoint{
+			{
+				Name:   "SignUp",
+				Params: []*ir.Param{{Name: "email"}, {Name: "password"}},
+				Validation: []*ir.ValidationRule{
+					{Field: "email", Rule: "valid_email"},
+					{Field: "email", Rule: "unique"},
+					{Field: "password", Rule: "min_length", Value: "8"},
+				},
+				Steps: []*ir.Action{{Type: "create", Text: "create a User with the given fields"}},
+			},
+			{Name: "ListOrders", Auth: true, PageSize: 20, Steps: []*ir.Action{{Type: "query", Text: "fetch all orders"}}},
+			{
+				Name:       "CreateOrder",
+				Auth:       true,
+				Params:     []*ir.Param{{Name: "total"}, {Name: "notes"}},
+				Validation: []*ir.ValidationRule{{Field: "total", Rule: "not_empty"}, {Field: "notes", Rule: "max_length", Value: "500"}},
+			},
+			{Name: "DeleteOrder", Auth: true, Params: []*ir.Param{{Name: "order_id"}}},
+			{Name: "GetOrders"},
+		},
+	}
+}
+
+func TestOperations(t *testing.T) {
+	ops := operations(testApp())
+
+	want := []struct{ method, path, model string }{
+		{"post", "/api/sign-up", "User"},
+		{"get", "/api/orders", "Order"},
+		{"post", "/api/order", "Order"},
+		{"delete", "/api/order", "Order"},
+	}
+	if len(ops) != len(want) {
+		t.Fatalf("got %d operations, want %d (GetOrders repeats ListOrders' route)", len(ops), len(want))
+	}
+	for i, w := range want {
+		op := ops[i]
+		if op.Method != w.method || op.Path != w.path || op.Model == nil || op.Model.Name != w.model {
+			t.Errorf("operation %d = %s %s, want %s %s (%s)", i, op.Method, op.Path, w.method, w.path, w.model)
+		}
+	}
+	if !ops[1].List {
+		t.Error("ListOrders should respond with a list")
+	}
+	if !ops[3].Query || ops[2].Query {
+		t.Error("Node reads DELETE parameters from the query string and POST parameters from the body")
+	}
+}
+
+func TestOperationsFollowBackend(t *testing.T) {
+	app := testApp()
+	app.Config.Backend = "Python with FastAPI"
+	ops := operations(app)
+	if ops[1].Path != "/api/list-orders" {
+		t.Errorf("Python keeps List in paths, got %s", ops[1].Path)
+	}
+	if ops[3].Query {
+		t.Error("Python reads every parameter from the body")
+	}
+
+	app.Config.Backend = "Go with Gin"
+	if !operations(app)[0].Created {
+		t.Error("Go answers a create with 201")
+	}
+}
+
+func TestSpec(t *testing.T) {
+	spec := generateSpec(testApp())
+
+	for _, want := range []string{
+		"openapi: 3.1.0\n",
+		"  title: Shop API\n",
+		"  - url: 'http://localhost:3001'\n",
+		"  /api/sign-up:\n    post:\n      operationId: signUp\n",
+		"                email:\n                  type: string\n                  format: email\n                  description: Must not already be taken\n",
+		"                password:\n                  type: string\n                  minLength: 8\n",
+		"        '409':\n",
+		"                  token:\n",
+		"      security:\n        - bearerAuth: []\n",
+		"        - name: limit\n          in: query\n",
+		"                    items:\n                      $ref: '#/components/schemas/Order'\n",
+		"                  pagination:\n                    $ref: '#/components/schemas/Pagination'\n",
+		"                total:\n                  type: number\n",
+		"                  maxLength: 500\n",
+		"              required: [total]\n",
+		"        - name: order_id\n          in: query\n",
+		"    bearerAuth:\n      type: http\n      scheme: bearer\n",
+		"          type: [string, 'null']\n",
+	} {
+		if !strings.Contains(spec, want) {
+			t.Errorf("openapi.yaml missing %q", want)
+		}
+	}
+	if strings.Contains(spec, "password:\n          type") {
+		t.Error("password must not appear in response schemas")
+	}
+	if strings.Contains(spec, "apiKeyAuth") {
+		t.Error("apps without API keys have no API key scheme")
+	}
+}
+
+func TestSpecAPIKeys(t *testing.T) {
+	app := testApp()
+	app.Auth.APIKeys = &ir.APIKeys{Model: "User"}
+	spec := generateSpec(app)
+	for _, want := range []string{
+		"        - bearerAuth: []\n        - apiKeyAuth: []\n",
+		"    apiKeyAuth:\n      type: apiKey\n      in: header\n      name: X-API-Key\n",
+	} {
+		if !strings.Contains(spec, want) {
+			t.Errorf("openapi.yaml missing %q", want)
+		}
+	}
+}
+
+func TestYAMLString(t *testing.T) {
+	for in, want := range map[string]string{
+		"Shop":                   "Shop",
+		"200":                    "'200'",
+		"true":                   "'true'",
+		"":                       "''",
+		"#/components/schemas/X": "'#/components/schemas/X'",
+		"it's: here":             "'it''s: here'",
+		"two\nlines":             `"two\nlines"`,
+		"/api/orders":            "/api/orders",
+	} {
+		if got := yamlString(in); got != want {
+			t.Errorf("yamlString(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(testApp(), dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"openapi.yaml", "README.md"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("missing %s", rel)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	if (Generator{}).Enabled(&ir.Application{}) {
+		t.Error("apps without APIs should not generate an OpenAPI spec")
+	}
+	if !(Generator{}).Enabled(testApp()) {
+		t.Error("apps with APIs should generate an OpenAPI spec")
+	}
+}
@@ -0,0 +1,250 @@
+package openapi
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// stack is the backend an app is built with, which decides how endpoints
+// are routed and what their requests and errors look like.
+type stack int
+
+const (
+	stackNode stack = iota
+	stackPython
+	stackGo
+)
+
+func stackOf(app *ir.Application) stack {
+	if app.Config == nil {
+		return stackNode
+	}
+	lower := strings.ToLower(app.Config.Backend)
+	switch {
+	case strings.Contains(lower, "python"), strings.Contains(lower, "fastapi"), strings.Contains(lower, "django"):
+		return stackPython
+	case lower == "go" || strings.HasPrefix(lower, "go "), strings.Contains(lower, "gin"), strings.Contains(lower, "golang"):
+		return stackGo
+	}
+	return stackNode
+}
+
+// errorKey is the property the backend puts an error's message in.
+func (s stack) errorKey() string {
+	if s == stackPython {
+		return "detail" // FastAPI's HTTPException
+	}
+	return "error"
+}
+
+// operation is one endpoint as the backend serves it.
+type operation struct {
+	Endpoint *ir.Endpoint
+	Method   string // get, post, put, delete
+	Path     string // /api/...
+	Query    bool   // parameters are read from the query string, not a JSON body
+	Model    *ir.DataModel
+	List     bool // responds with an array of Model
+	Created  bool // responds 201 with the record it created
+}
+
+// operations derives the app's REST operations, in declaration order,
+// following the routing of the backend it is built with: Get/List (and,
+// outside Node, Search) → GET, Delete → DELETE, Update → PUT, everything
+// else POST. Node also strips List from paths. An endpoint whose method and
+// path an earlier one already serves is left out, as it is never reached.
+func operations(app *ir.Application) []operation {
+	s := stackOf(app)
+	seen := map[string]bool{}
+	var ops []operation
+	for _, ep := range app.APIs {
+		op := operation{
+			Endpoint: ep,
+			Method:   s.method(ep.Name),
+			Path:     "/api" + s.path(ep.Name),
+		}
+		if seen[op.Method+" "+op.Path] {
+			continue
+		}
+		seen[op.Method+" "+op.Path] = true
+
+		// Node reads GET and DELETE parameters from the query string;
+		// deleting an account takes the password in the body, out of logs
+		op.Query = s == stackNode && (op.Method == "get" || op.Method == "delete" && ep.Account == "")
+
+		noun := stripVerb(ep.Name, true)
+		op.Model = findModel(noun, app)
+		if op.Model == nil {
+			op.Model = stepModel(ep, app)
+		}
+		if op.Model != nil && op.Method == "get" && (ep.PageSize > 0 || !strings.EqualFold(op.Model.Name, noun)) {
+			op.List = true
+		}
+		op.Created = s == stackGo && hasStep(ep, "create") && !isLogin(ep.Name)
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func (s stack) method(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "get"), strings.HasPrefix(lower, "list"):
+		return "get"
+	case strings.HasPrefix(lower, "search") && s != stackNode:
+		return "get"
+	case strings.HasPrefix(lower, "delete"):
+		return "delete"
+	case strings.HasPrefix(lower, "update"):
+		return "put"
+	default:
+		return "post"
+	}
+}
+
+func (s stack) path(name string) string {
+	return "/" + toKebabCase(stripVerb(name, s == stackNode))
+}
+
+// paramName is the name a parameter is sent under: camelCase in Go, snake
+// case in Python, and as declared in Node unless it has spaces.
+func (s stack) paramName(name string) string {
+	switch {
+	case s == stackGo:
+		return toCamelCase(name)
+	case s == stackPython:
+		return toSnakeCase(name)
+	case strings.Contains(name, " "):
+		return toCamelCase(name)
+	}
+	return name
+}
+
+func stripVerb(name string, list bool) string {
+	prefixes := []string{"Get", "Create", "Update", "Delete"}
+	if list {
+		prefixes = append(prefixes, "List")
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return name[len(prefix):]
+		}
+	}
+	return name
+}
+
+// findModel matches a noun ("Tasks", "Task", "Categories") to a data model.
+func findModel(noun string, app *ir.Application) *ir.DataModel {
+	for _, candidate := range []string{noun, singularize(noun)} {
+		for _, m := range app.Data {
+			if strings.EqualFold(m.Name, candidate) {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// stepModel returns the model the endpoint's first create, query, or
+// update step names, for endpoints whose name doesn't.
+func stepModel(ep *ir.Endpoint, app *ir.Application) *ir.DataModel {
+	for _, step := range ep.Steps {
+		if step.Type != "create" && step.Type != "query" && step.Type != "update" {
+			continue
+		}
+		for _, word := range strings.Fields(strings.ToLower(step.Text)) {
+			for _, m := range app.Data {
+				if name := strings.ToLower(m.Name); word == name || singularize(word) == name {
+					return m
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func hasStep(ep *ir.Endpoint, kind string) bool {
+	for _, step := range ep.Steps {
+		if step.Type == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func isLogin(name string) bool {
+	return strings.EqualFold(name, "login")
+}
+
+func isSignUp(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "signup" || lower == "sign_up"
+}
+
+func singularize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+// ── Naming ──
+
+func splitWords(s string) []string {
+	var words []string
+	var cur []rune
+	for i, r := range s {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			if len(cur) > 0 {
+				words = append(words, string(cur))
+			}
+			cur = nil
+			continue
+		case unicode.IsUpper(r) && i > 0 && len(cur) > 0 && !unicode.IsUpper(cur[len(cur)-1]):
+			words = append(words, string(cur))
+			cur = nil
+		}
+		cur = append(cur, r)
+	}
+	if len(cur) > 0 {
+		words = append(words, string(cur))
+	}
+	return words
+}
+
+func toCamelCase(s string) string {
+	var b strings.Builder
+	for i, w := range splitWords(s) {
+		if i == 0 {
+			b.WriteString(strings.ToLower(w))
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + strings.ToLower(w[1:]))
+	}
+	return b.String()
+}
+
+// toKebabCase hyphenates an endpoint name the way the backends do, before
+// every capital: "TaskComments" → "task-comments".
+func toKebabCase(s string) string {
+	var result []rune
+	for i, r := range s {
+		if unicode.IsUpper(r) && i > 0 {
+			result = append(result, '-')
+		}
+		result = append(result, unicode.ToLower(r))
+	}
+	return string(result)
+}
+
+func toSnakeCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "_"))
+}
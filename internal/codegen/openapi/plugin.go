@@ -0,0 +1,27 @@
+package openapi
+
+import (
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Meta returns the generator's metadata.
+func (g Generator) Meta() codegen.PluginMeta {
+	return codegen.PluginMeta{
+		Name:        "openapi",
+		Version:     "1.0.0",
+		Description: "OpenAPI 3.1 document describing the REST API",
+		Category:    codegen.CategoryBackend,
+	}
+}
+
+// Enabled reports whether the app declares any APIs.
+func (g Generator) Enabled(app *ir.Application) bool {
+	return len(app.APIs) > 0
+}
+
+// StageName returns the display name for progress reporting.
+func (g Generator) StageName() string { return "Generating OpenAPI spec" }
+
+// OutputDir returns the subdirectory name within the build output.
+func (g Generator) OutputDir() string { return "openapi" }
@@ -0,0 +1,202 @@
+package openapi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// object is a YAML mapping that keeps its keys in insertion order, so the
+// document reads in the same order as the .human declarations.
+type object []member
+
+type member struct {
+	Key   string
+	Value any
+}
+
+// ── Schemas ──
+
+// schemaRef points at a component schema.
+func schemaRef(name string) object {
+	return object{{"$ref", "#/components/schemas/" + name}}
+}
+
+// modelSchema describes a data model record as the generated backends
+// serialize it.
+func modelSchema(model *ir.DataModel) object {
+	props := object{{"id", object{{"type", "string"}}}}
+	required := []any{"id"}
+	for _, f := range model.Fields {
+		if strings.EqualFold(f.Name, "password") {
+			continue // never leaves the backend
+		}
+		props = append(props, member{f.Name, fieldSchema(f.Type, f.EnumValues, f.Required)})
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+	for _, rel := range model.Relations {
+		if rel.Kind == "belongs_to" {
+			fk := toCamelCase(rel.Target) + "Id"
+			props = append(props, member{fk, object{{"type", "string"}}})
+			required = append(required, fk)
+		}
+	}
+	props = append(props,
+		member{"createdAt", object{{"type", "string"}, {"format", "date-time"}}},
+		member{"updatedAt", object{{"type", "string"}, {"format", "date-time"}}},
+	)
+
+	return object{
+		{"type", "object"},
+		{"properties", props},
+		{"required", required},
+	}
+}
+
+// fieldSchema maps an IR field type to a JSON Schema. Optional fields
+// also accept null, which is how the backends serialize unset columns.
+func fieldSchema(irType string, enum []string, required bool) object {
+	s := baseFieldSchema(irType, enum)
+	if required {
+		return s
+	}
+	for i, m := range s {
+		switch m.Key {
+		case "type":
+			s[i].Value = []any{m.Value, "null"}
+		case "enum":
+			s[i].Value = append(m.Value.([]any), nil)
+		}
+	}
+	return s
+}
+
+func baseFieldSchema(irType string, enum []string) object {
+	switch strings.ToLower(irType) {
+	case "number":
+		return object{{"type", "integer"}}
+	case "decimal":
+		return object{{"type", "number"}}
+	case "boolean":
+		return object{{"type", "boolean"}}
+	case "email":
+		return object{{"type", "string"}, {"format", "email"}}
+	case "url", "file", "image":
+		return object{{"type", "string"}, {"format", "uri"}}
+	case "date":
+		return object{{"type", "string"}, {"format", "date"}}
+	case "datetime":
+		return object{{"type", "string"}, {"format", "date-time"}}
+	case "enum":
+		values := make([]any, len(enum))
+		for i, v := range enum {
+			values[i] = v
+		}
+		return object{{"type", "string"}, {"enum", values}}
+	case "json":
+		return object{}
+	default:
+		return object{{"type", "string"}}
+	}
+}
+
+// ── YAML ──
+
+// encodeYAML writes a document as block YAML with two-space indentation.
+// Lists of scalars are written inline.
+func encodeYAML(doc object) string {
+	var b strings.Builder
+	writeObject(&b, doc, 0, true)
+	return b.String()
+}
+
+func writeObject(b *strings.Builder, o object, indent int, padFirst bool) {
+	for i, m := range o {
+		if i > 0 || padFirst {
+			b.WriteString(strings.Repeat(" ", indent))
+		}
+		b.WriteString(yamlScalar(m.Key) + ":")
+		writeValue(b, m.Value, indent)
+	}
+}
+
+// writeValue writes what follows a key's colon or a list item's dash.
+func writeValue(b *strings.Builder, v any, indent int) {
+	switch v := v.(type) {
+	case object:
+		if len(v) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		writeObject(b, v, indent+2, true)
+	case []any:
+		if scalars(v) {
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = yamlScalar(item)
+			}
+			b.WriteString(" [" + strings.Join(items, ", ") + "]\n")
+			return
+		}
+		b.WriteString("\n")
+		for _, item := range v {
+			b.WriteString(strings.Repeat(" ", indent+2) + "-")
+			if o, ok := item.(object); ok && len(o) > 0 {
+				b.WriteString(" ")
+				writeObject(b, o, indent+4, false)
+				continue
+			}
+			writeValue(b, item, indent+2)
+		}
+	default:
+		b.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+func scalars(items []any) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case object, []any:
+			return false
+		}
+	}
+	return true
+}
+
+// yamlScalar formats a scalar, quoting strings that could be misread as
+// YAML syntax, a number, or a boolean.
+func yamlScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case string:
+		return yamlString(v)
+	}
+	return ""
+}
+
+func yamlString(s string) string {
+	if strings.ContainsAny(s, "\n\t\\") {
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return "'" + s + "'"
+	}
+	switch strings.ToLower(s) {
+	case "", "true", "false", "null", "yes", "no", "on", "off", "~":
+		return "'" + s + "'"
+	}
+	if strings.ContainsAny(s, ":#'\"{}[],&*!|>%@`") || strings.HasPrefix(s, "-") || strings.HasPrefix(s, "?") ||
+		strings.HasPrefix(s, " ") || strings.HasSuffix(s, " ") {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	return s
+}
@@ -2,6 +2,8 @@ package codegen
 
 import (
 	"fmt"
+	"io/fs"
+	"path/filepath"
 	"strings"
 
 	"github.com/barun-bash/human/internal/config"
@@ -28,7 +30,7 @@ type PluginMeta struct {
 
 // CodeGenerator is the interface that all code generators implement.
 // Built-in generators satisfy this interface directly; external plugins
-// will be loaded via a future plugin system.
+// are adapted to it by the plugin package.
 type CodeGenerator interface {
 	// Meta returns the generator's metadata.
 	Meta() PluginMeta
@@ -49,6 +51,69 @@ type CodeGenerator interface {
 	Generate(app *ir.Application, outputDir string) error
 }
 
+// InputDeclarer is implemented by generators that read only part of the
+// IR. Inputs returns the top-level IR keys the generator reads (e.g.
+// "data", "config"), so the build cache can skip it when none of them
+// changed. Generators that don't implement it are rerun on any change.
+type InputDeclarer interface {
+	Inputs() []string
+}
+
+// DirResolver is implemented by generators that write into another
+// generator's directory, as Storybook writes into the frontend's. TargetDir
+// returns the directory under outputDir to generate into, or an error
+// saying why the generator is skipped for app.
+type DirResolver interface {
+	TargetDir(app *ir.Application, outputDir string) (string, error)
+}
+
+// FileCounter is implemented by generators that don't own every file
+// under their directory, such as those writing into the output root.
+// CountFiles returns how many of the files under dir are the generator's.
+// Generators writing into the root without it are counted by the files
+// their run added.
+type FileCounter interface {
+	CountFiles(dir string) int
+}
+
+// CountFiles returns the number of regular files under dir.
+func CountFiles(dir string) int {
+	count := 0
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+// registered holds the generators added with Register.
+var registered = NewRegistry()
+
+// Register makes a generator part of every build, run after the built-in
+// generators in registration order. Like database/sql's Register, it's
+// meant to be called from the init function of the package providing the
+// generator, and panics if name is taken or isn't the generator's
+// Meta().Name.
+func Register(name string, g CodeGenerator) {
+	if g == nil {
+		panic("codegen: Register generator is nil")
+	}
+	if meta := g.Meta().Name; meta != name {
+		panic(fmt.Sprintf("codegen: Register %q: generator is named %q", name, meta))
+	}
+	if err := registered.Register(g); err != nil {
+		panic("codegen: Register: " + err.Error())
+	}
+}
+
+// Registered returns the generators added with Register, in registration
+// order.
+func Registered() []CodeGenerator {
+	return registered.All()
+}
+
 // Registry holds an ordered collection of code generators.
 // Registration order determines execution order.
 type Registry struct {
@@ -25,8 +25,8 @@ func (g *testGenerator) Meta() PluginMeta {
 }
 
 func (g *testGenerator) Enabled(_ *ir.Application) bool { return g.enabled }
-func (g *testGenerator) StageName() string               { return g.stage }
-func (g *testGenerator) OutputDir() string                { return g.outDir }
+func (g *testGenerator) StageName() string              { return g.stage }
+func (g *testGenerator) OutputDir() string              { return g.outDir }
 
 func (g *testGenerator) Generate(_ *ir.Application, _ string) error { return nil }
 
@@ -177,3 +177,27 @@ func TestMatchesGoBackend(t *testing.T) {
 		}
 	}
 }
+
+func TestRegister(t *testing.T) {
+	g := &testGenerator{name: "registered-test"}
+	Register("registered-test", g)
+	all := Registered()
+	if len(all) == 0 || all[len(all)-1] != CodeGenerator(g) {
+		t.Errorf("Registered() = %v, want it to end with the generator", all)
+	}
+
+	for name, register := range map[string]func(){
+		"duplicate": func() { Register("registered-test", &testGenerator{name: "registered-test"}) },
+		"mismatch":  func() { Register("rails", &testGenerator{name: "laravel"}) },
+		"nil":       func() { Register("nil", nil) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected Register to panic", name)
+				}
+			}()
+			register()
+		}()
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -25,24 +26,7 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		filepath.Join(outputDir, "seed.sql"):            generateSeed(app),
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 // pgType maps an IR field type to a PostgreSQL column type.
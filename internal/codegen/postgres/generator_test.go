@@ -66,15 +66,15 @@ func TestToTableName(t *testing.T) {
 		{"Task", "tasks"},
 		{"TaskTag", "task_tags"},
 		{"Tag", "tags"},
-		{"Category", "categories"},           // consonant + y → ies
+		{"Category", "categories"}, // consonant + y → ies
 		{"ProductCategory", "product_categories"},
-		{"Address", "addresses"},             // ends in s → es
-		{"Match", "matches"},                 // ends in ch → es
+		{"Address", "addresses"}, // ends in s → es
+		{"Match", "matches"},     // ends in ch → es
 		{"Batch", "batches"},
-		{"Box", "boxes"},                     // ends in x → es
-		{"Buzz", "buzzes"},                   // ends in z → es
-		{"Wish", "wishes"},                   // ends in sh → es
-		{"Day", "days"},                      // vowel + y → just s
+		{"Box", "boxes"},   // ends in x → es
+		{"Buzz", "buzzes"}, // ends in z → es
+		{"Wish", "wishes"}, // ends in sh → es
+		{"Day", "days"},    // vowel + y → just s
 		{"Key", "keys"},
 	}
 	for _, tt := range tests {
@@ -462,6 +462,20 @@ func TestGenerateMigrationWithCategory(t *testing.T) {
 	}
 }
 
+func TestGenerateMigrationVersioned(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{
+			{Name: "Post", Versioned: true, Fields: []*ir.DataField{{Name: "title", Type: "text", Required: true}}},
+			{Name: "Comment", Fields: []*ir.DataField{{Name: "body", Type: "text", Required: true}}},
+		},
+	}
+
+	output := generateMigration(app)
+	if strings.Count(output, "version INTEGER NOT NULL DEFAULT 1,") != 1 {
+		t.Errorf("only posts should count their versions\n%s", output)
+	}
+}
+
 func TestGenerateMigrationTimestampIndex(t *testing.T) {
 	app := &ir.Application{
 		Database: &ir.DatabaseConfig{
@@ -591,6 +605,21 @@ func TestGenerateSeed(t *testing.T) {
 	if !strings.Contains(output, "user_id") {
 		t.Error("missing FK reference in task seed")
 	}
+
+	// Rows come from the shared fixture set.
+	if !strings.Contains(output, "'00000001-0000-4000-8000-000000000001'") {
+		t.Error("seed should use fixture ids")
+	}
+	if strings.Index(output, "INSERT INTO users") > strings.Index(output, "INSERT INTO tasks") {
+		t.Error("users must be seeded before tasks that reference them")
+	}
+
+	// Seed lines set a model's count, and what points at it
+	app.Seeds = []*ir.Seed{{Model: "Task", Count: 2}}
+	output = generateSeed(app)
+	if !strings.Contains(output, "'00000002-0000-4000-8000-000000000002'") || strings.Contains(output, "'00000002-0000-4000-8000-000000000003'") {
+		t.Errorf("expected 2 tasks:\n%s", output)
+	}
 }
 
 // ── Generate to Filesystem ──
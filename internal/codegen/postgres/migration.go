@@ -112,6 +112,11 @@ func writeCreateTable(b *strings.Builder, model *ir.DataModel, app *ir.Applicati
 		}
 	}
 
+	// Edits counted to refuse ones made to an older version
+	if model.Versioned {
+		fmt.Fprintf(b, "  %s INTEGER NOT NULL DEFAULT 1,\n", ir.VersionColumn)
+	}
+
 	// Timestamps
 	b.WriteString("  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),\n")
 	b.WriteString("  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()\n")
@@ -30,3 +30,6 @@ func (g Generator) StageName() string { return "Generating PostgreSQL schema" }
 
 // OutputDir returns the subdirectory name within the build output.
 func (g Generator) OutputDir() string { return "postgres" }
+
+// Inputs returns the parts of the IR the schema is generated from.
+func (g Generator) Inputs() []string { return []string{"config", "data", "database", "seeds"} }
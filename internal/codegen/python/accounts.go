@@ -0,0 +1,233 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeAccountRequest writes the request schema of an endpoint the
+// accounts block added, typed so FastAPI checks what it can: an email is
+// an email address, and a new password is long enough.
+func writeAccountRequest(sb *strings.Builder, api *ir.Endpoint, app *ir.Application) {
+	fmt.Fprintf(sb, "class %sRequest(schemas.BaseModel):\n", toPascalCase(api.Name))
+	switch api.Account {
+	case ir.AccountUpdateProfile:
+		for _, f := range ir.ProfileFields(app) {
+			pyType := "Any"
+			switch f.Type {
+			case "email":
+				pyType = "schemas.EmailStr"
+			case "text", "url", "enum", "number", "decimal", "boolean":
+				pyType = pythonType(f.Type)
+			}
+			if f.Required || pyType == "Any" {
+				fmt.Fprintf(sb, "    %s: %s\n", toSnakeCase(f.Name), pyType)
+			} else {
+				fmt.Fprintf(sb, "    %s: Optional[%s] = None\n", toSnakeCase(f.Name), pyType)
+			}
+		}
+	case ir.AccountChangePassword:
+		sb.WriteString("    current_password: str\n")
+		fmt.Fprintf(sb, "    new_password: str = schemas.Field(min_length=%d)\n", ir.MinPasswordLength)
+	case ir.AccountInvite:
+		sb.WriteString("    email: schemas.EmailStr\n")
+		sb.WriteString("    role: Optional[str] = None\n")
+	default:
+		for _, p := range api.Params {
+			fmt.Fprintf(sb, "    %s: str\n", toSnakeCase(p.Name))
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// writeAccountRoute writes the body of an endpoint the accounts block
+// added. Each acts on the signed-in user, and none responds with their
+// password. Changing the password and deleting the account both take the
+// current password, so a stolen session alone can do neither.
+func writeAccountRoute(sb *strings.Builder, api *ir.Endpoint, app *ir.Application) {
+	model := toPascalCase(app.Accounts.Model)
+
+	switch api.Account {
+	case ir.AccountProfile:
+		sb.WriteString("    return {'data': profile_of(current_user)}\n")
+
+	case ir.AccountUpdateProfile:
+		fields := ir.ProfileFields(app)
+		for _, f := range fields {
+			if !f.Unique || f.EncryptsAtRest() {
+				continue
+			}
+			col := toSnakeCase(f.Name)
+			fmt.Fprintf(sb, "    # %s belongs to one account at a time\n", f.Name)
+			fmt.Fprintf(sb, "    if db.query(models.%s).filter(models.%s.%s == payload.%s, models.%s.id != current_user.id).first():\n", model, model, col, col, model)
+			fmt.Fprintf(sb, "        raise HTTPException(status_code=409, detail='%s is already taken')\n", f.Name)
+		}
+		for _, f := range fields {
+			col := toSnakeCase(f.Name)
+			fmt.Fprintf(sb, "    current_user.%s = payload.%s\n", col, col)
+		}
+		sb.WriteString("    db.commit()\n")
+		sb.WriteString("    db.refresh(current_user)\n")
+		sb.WriteString("    return {'data': profile_of(current_user)}\n")
+
+	case ir.AccountChangePassword:
+		sb.WriteString("    if not auth.verify_password(payload.current_password, current_user.password):\n")
+		sb.WriteString("        raise HTTPException(status_code=403, detail='Your current password is incorrect')\n")
+		sb.WriteString("    if payload.new_password == payload.current_password:\n")
+		sb.WriteString("        raise HTTPException(status_code=400, detail='Choose a password you are not using now')\n")
+		sb.WriteString("    current_user.password = auth.get_password_hash(payload.new_password)\n")
+		sb.WriteString("    db.commit()\n")
+		sb.WriteString("    return Response(status_code=status.HTTP_204_NO_CONTENT)\n")
+
+	case ir.AccountDelete:
+		sb.WriteString("    if not auth.verify_password(payload.password, current_user.password):\n")
+		sb.WriteString("        raise HTTPException(status_code=403, detail='Your password is incorrect')\n")
+		sb.WriteString("    db.delete(current_user)\n")
+		sb.WriteString("    db.commit()\n")
+		sb.WriteString("    return Response(status_code=status.HTTP_204_NO_CONTENT)\n")
+
+	case ir.AccountInvite, ir.AccountInvitation, ir.AccountAcceptInvite:
+		writeInvitationRoute(sb, api, app)
+	}
+}
+
+// invitesTeammates reports whether the app's accounts block lets users
+// invite teammates to a team it has a model for.
+func invitesTeammates(app *ir.Application) bool {
+	return ir.AccountModel(app) != nil && app.Accounts.Invite != nil && app.Accounts.Invite.Team != ""
+}
+
+// writeInvitationRoute writes the body of an invitation endpoint. A member
+// invites a teammate to their own team as one of the app's roles; the
+// invitation is a secret token, emailed as a link to the accept page when
+// the app sends email and given back to the inviter to share when it
+// doesn't. Accepting takes the signed-in user whose email it was sent to,
+// before it expires, and makes them a member with the role, which the
+// policies then check.
+func writeInvitationRoute(sb *strings.Builder, api *ir.Endpoint, app *ir.Application) {
+	if !invitesTeammates(app) {
+		sb.WriteString("    raise HTTPException(status_code=501, detail='Invitations have no team to join')\n")
+		return
+	}
+	inv := app.Accounts.Invite
+	user := toPascalCase(app.Accounts.Model)
+	teamKey := toSnakeCase(inv.Team) + "_id"
+	userKey := toSnakeCase(app.Accounts.Model) + "_id"
+	invitation := toPascalCase(ir.InvitationModel)
+	membership := toPascalCase(ir.MembershipModel)
+	hasEmail := ir.AccountModel(app).FieldNamed("email") != nil
+	// Times are compared as the columns keep them: with their time zone
+	// when the app stores times in UTC, and naive otherwise.
+	now := "datetime.now(timezone.utc).replace(tzinfo=None)"
+	if ir.StoresUTC(app) {
+		now = "datetime.now(timezone.utc)"
+	}
+
+	switch api.Account {
+	case ir.AccountInvite:
+		fmt.Fprintf(sb, "    membership = db.query(models.%s).filter(models.%s.%s == current_user.id).first()\n", membership, membership, userKey)
+		sb.WriteString("    if not membership:\n")
+		fmt.Fprintf(sb, "        raise HTTPException(status_code=403, detail='Only %s members can invite teammates')\n", ir.FieldLabel(inv.Team))
+		fmt.Fprintf(sb, "    role = payload.role or '%s'\n", inv.Role)
+		if len(inv.Roles) > 0 {
+			quoted := make([]string, len(inv.Roles))
+			for i, r := range inv.Roles {
+				quoted[i] = "'" + r + "'"
+			}
+			fmt.Fprintf(sb, "    if role not in (%s):\n", strings.Join(quoted, ", "))
+			fmt.Fprintf(sb, "        raise HTTPException(status_code=400, detail='role must be one of: %s')\n", strings.Join(inv.Roles, ", "))
+		}
+		if hasEmail {
+			fmt.Fprintf(sb, "    if db.query(models.%s).join(models.%s).filter(models.%s.%s == membership.%s, models.%s.email == payload.email).first():\n", membership, user, membership, teamKey, teamKey, user)
+			sb.WriteString("        raise HTTPException(status_code=409, detail=f'{payload.email} is already a teammate')\n")
+		}
+		sb.WriteString("    # A new invitation replaces one still pending for the same email\n")
+		fmt.Fprintf(sb, "    db.query(models.%s).filter(models.%s.%s == membership.%s, models.%s.email == payload.email, models.%s.accepted.is_(None)).delete()\n", invitation, invitation, teamKey, teamKey, invitation, invitation)
+		fmt.Fprintf(sb, "    invitation = models.%s(\n", invitation)
+		sb.WriteString("        email=payload.email,\n")
+		sb.WriteString("        token=secrets.token_hex(32),\n")
+		sb.WriteString("        role=role,\n")
+		fmt.Fprintf(sb, "        expires=%s + timedelta(days=%d),\n", now, inv.Expires)
+		fmt.Fprintf(sb, "        %s=membership.%s,\n", teamKey, teamKey)
+		sb.WriteString("    )\n")
+		sb.WriteString("    db.add(invitation)\n")
+		sb.WriteString("    db.commit()\n")
+		fmt.Fprintf(sb, "    link = f\"{os.getenv('SITE_URL', '').rstrip('/')}%s?token={invitation.token}\"\n", inv.AcceptPath())
+		sb.WriteString("    sent = {'id': invitation.id, 'email': invitation.email, 'role': role, 'expires': invitation.expires}\n")
+		if ir.EmailIntegration(app) != nil {
+			sb.WriteString("    from services.email_service import send_email\n")
+			subject := "\"You're invited to join the team\""
+			if ir.AccountModel(app) != nil && modelHasName(app, inv.Team) {
+				subject = fmt.Sprintf("f\"You're invited to join {membership.%s.name}\"", toSnakeCase(inv.Team))
+			}
+			fmt.Fprintf(sb, "    await send_email(to=payload.email, subject=%s, text=f'You\\'ve been invited as {role}. Accept within %d days: {link}')\n", subject, inv.Expires)
+			sb.WriteString("    return JSONResponse(status_code=201, content=jsonable_encoder({'data': sent}))\n")
+		} else {
+			sb.WriteString("    # No email integration: the inviter shares the link\n")
+			sb.WriteString("    return JSONResponse(status_code=201, content=jsonable_encoder({'data': {**sent, 'link': link}}))\n")
+		}
+
+	case ir.AccountInvitation:
+		fmt.Fprintf(sb, "    invitation = db.query(models.%s).filter(models.%s.token == token).first()\n", invitation, invitation)
+		writeInvitationUsable(sb, now)
+		fmt.Fprintf(sb, "    team = invitation.%s\n", toSnakeCase(inv.Team))
+		sb.WriteString("    return {'data': {\n")
+		sb.WriteString("        'email': invitation.email,\n")
+		sb.WriteString("        'role': invitation.role,\n")
+		sb.WriteString("        'expires': invitation.expires,\n")
+		sb.WriteString("        'team': {c.name: getattr(team, c.name) for c in team.__table__.columns},\n")
+		sb.WriteString("    }}\n")
+
+	case ir.AccountAcceptInvite:
+		fmt.Fprintf(sb, "    invitation = db.query(models.%s).filter(models.%s.token == payload.token).first()\n", invitation, invitation)
+		writeInvitationUsable(sb, now)
+		if hasEmail {
+			sb.WriteString("    if current_user.email.lower() != invitation.email.lower():\n")
+			sb.WriteString("        raise HTTPException(status_code=403, detail=f'This invitation is for {invitation.email}')\n")
+		}
+		fmt.Fprintf(sb, "    invitation.accepted = %s\n", now)
+		if ir.InviteSetsUserRole(app) {
+			sb.WriteString("    # The role the policies check\n")
+			sb.WriteString("    current_user.role = invitation.role\n")
+		}
+		fmt.Fprintf(sb, "    membership = db.query(models.%s).filter(models.%s.%s == current_user.id, models.%s.%s == invitation.%s).first()\n", membership, membership, userKey, membership, teamKey, teamKey)
+		sb.WriteString("    if membership:\n")
+		sb.WriteString("        membership.role = invitation.role\n")
+		sb.WriteString("    else:\n")
+		fmt.Fprintf(sb, "        membership = models.%s(role=invitation.role, %s=current_user.id, %s=invitation.%s)\n", membership, userKey, teamKey, teamKey)
+		sb.WriteString("        db.add(membership)\n")
+		sb.WriteString("    db.commit()\n")
+		sb.WriteString("    db.refresh(membership)\n")
+		sb.WriteString("    return {'data': {c.name: getattr(membership, c.name) for c in membership.__table__.columns}}\n")
+	}
+}
+
+// modelHasName reports whether the named model has a name field.
+func modelHasName(app *ir.Application, model string) bool {
+	for _, m := range app.Data {
+		if m.Name == model {
+			return m.FieldNamed("name") != nil
+		}
+	}
+	return false
+}
+
+// writeInvitationUsable answers 404 for a token no invitation has, and 410
+// for an invitation already accepted or past its expiry.
+func writeInvitationUsable(sb *strings.Builder, now string) {
+	sb.WriteString("    if not invitation:\n")
+	sb.WriteString("        raise HTTPException(status_code=404, detail='Invitation not found')\n")
+	sb.WriteString("    if invitation.accepted:\n")
+	sb.WriteString("        raise HTTPException(status_code=410, detail='This invitation has already been accepted')\n")
+	fmt.Fprintf(sb, "    if invitation.expires < %s:\n", now)
+	sb.WriteString("        raise HTTPException(status_code=410, detail='This invitation has expired')\n")
+}
+
+// writeProfileOf writes the helper responding with a user's account: every
+// column but their password.
+func writeProfileOf(sb *strings.Builder) {
+	sb.WriteString("def profile_of(user):\n")
+	sb.WriteString("    return {c.name: getattr(user, c.name) for c in user.__table__.columns if c.name != 'password'}\n\n")
+}
@@ -0,0 +1,158 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeAPIKeyAuth appends get_current_user taking either the signed-in
+// user's JWT or an external client's API key, in the X-API-Key header. A
+// read key can only make GET requests.
+func writeAPIKeyAuth(b *strings.Builder, app *ir.Application) {
+	fmt.Fprintf(b, `
+def hash_api_key(key: str) -> str:
+    """A key's SHA-256 hash, which is all that's stored of it."""
+    return hashlib.sha256(key.encode()).hexdigest()
+
+def get_current_user(
+    request: Request,
+    token: Optional[str] = Depends(oauth2_scheme),
+    api_key: Optional[str] = Depends(api_key_header),
+    db: Session = Depends(get_db),
+):
+    """
+    The signed-in user, by their JWT, or an external client's, by their API key.
+
+    Behavior:
+        1. An unknown API key -> 401
+        2. A read key making anything but a GET request -> 403
+        3. A missing, invalid, or expired JWT -> 401
+    """
+    if api_key:
+        key = db.query(models.%[1]s).filter(models.%[1]s.key_hash == hash_api_key(api_key)).first()
+        if key is None:
+            raise HTTPException(status_code=status.HTTP_401_UNAUTHORIZED, detail="Invalid API key")
+        if '%[2]s' not in key.scopes.split(',') and request.method not in ('GET', 'HEAD', 'OPTIONS'):
+            raise HTTPException(status_code=status.HTTP_403_FORBIDDEN, detail="This API key can only read")
+        key.last_used_at = datetime.now(timezone.utc)
+        db.commit()
+        request.state.api_key_id = key.id
+        return key.%[3]s
+
+    credentials_exception = HTTPException(
+        status_code=status.HTTP_401_UNAUTHORIZED,
+        detail="Could not validate credentials",
+        headers={"WWW-Authenticate": "Bearer"},
+    )
+    if not token:
+        raise credentials_exception
+    try:
+        payload = jwt.decode(token, SECRET_KEY, algorithms=[ALGORITHM])
+        user_id: str = payload.get("sub")
+        if user_id is None:
+            raise credentials_exception
+    except JWTError:
+        raise credentials_exception
+
+    user = db.query(models.User).filter(models.User.id == user_id).first()
+    if user is None:
+        raise credentials_exception
+    return user
+`, ir.APIKeyModel, ir.ScopeWrite, toSnakeCase(app.Auth.APIKeys.Model))
+}
+
+// generateAPIKeys produces api_keys.py: the signed-in user's keys,
+// creating one, and revoking one. Keys are managed signed in, so a leaked
+// key can't make more.
+func generateAPIKeys(app *ir.Application) string {
+	fk := toSnakeCase(app.Auth.APIKeys.Model) + "_id"
+	return fmt.Sprintf(`# Generated by Human compiler — do not edit
+import secrets
+from typing import Any, List, Optional
+
+from fastapi import APIRouter, Body, Depends, HTTPException, Request, Response, status
+from sqlalchemy.orm import Session
+
+import models, auth
+from database import get_db
+
+router = APIRouter()
+
+# The scopes a key can have: a read key can only make GET requests.
+SCOPES = ('%[2]s', '%[3]s')
+
+
+def signed_in(request: Request, current_user: Any = Depends(auth.get_current_user)):
+    """The signed-in user; an API key can't list, create, or revoke keys."""
+    if getattr(request.state, 'api_key_id', None):
+        raise HTTPException(status_code=403, detail="API keys can't manage API keys; sign in instead")
+    return current_user
+
+
+def present(key: Any) -> dict:
+    """What's shown of a key: never its hash."""
+    return {
+        "id": key.id,
+        "name": key.name,
+        "prefix": key.prefix,
+        "scopes": key.scopes.split(','),
+        "lastUsedAt": key.last_used_at,
+        "createdAt": key.created_at,
+    }
+
+
+@router.get('/keys')
+def list_api_keys(db: Session = Depends(get_db), current_user: Any = Depends(signed_in)):
+    keys = (
+        db.query(models.%[1]s)
+        .filter(models.%[1]s.%[4]s == current_user.id)
+        .order_by(models.%[1]s.created_at.desc())
+        .all()
+    )
+    return {"data": [present(key) for key in keys]}
+
+
+@router.post('/keys', status_code=201)
+def create_api_key(
+    name: str = Body('', embed=True),
+    scopes: Optional[List[str]] = Body(None, embed=True),
+    db: Session = Depends(get_db),
+    current_user: Any = Depends(signed_in),
+):
+    """Creates a key with the given scopes (read by default); the key itself is in this response only."""
+    name = name.strip()
+    if not name:
+        raise HTTPException(status_code=400, detail='name is required')
+    scopes = ['%[2]s'] if scopes is None else scopes
+    if not scopes or any(scope not in SCOPES for scope in scopes):
+        raise HTTPException(status_code=400, detail='scopes must be some of: ' + ', '.join(SCOPES))
+    key = '%[5]s' + secrets.token_urlsafe(24)
+    api_key = models.%[1]s(
+        name=name,
+        prefix=key[:%[6]d],
+        key_hash=auth.hash_api_key(key),
+        scopes=','.join(dict.fromkeys(scopes)),
+        %[4]s=current_user.id,
+    )
+    db.add(api_key)
+    db.commit()
+    db.refresh(api_key)
+    return {"data": {**present(api_key), "key": key}}
+
+
+@router.delete('/keys/{key_id}')
+def revoke_api_key(key_id: str, db: Session = Depends(get_db), current_user: Any = Depends(signed_in)):
+    """Revokes a key: calls with it are refused from now on."""
+    deleted = (
+        db.query(models.%[1]s)
+        .filter(models.%[1]s.id == key_id, models.%[1]s.%[4]s == current_user.id)
+        .delete(synchronize_session=False)
+    )
+    if not deleted:
+        raise HTTPException(status_code=404, detail='API key not found')
+    db.commit()
+    return Response(status_code=status.HTTP_204_NO_CONTENT)
+`, ir.APIKeyModel, ir.ScopeRead, ir.ScopeWrite, fk, ir.APIKeyPrefix, len(ir.APIKeyPrefix)+8)
+}
@@ -0,0 +1,52 @@
+package python
+
+// generateAuditLog produces audit.py: an HTTP middleware writing one JSON
+// line per request that changes data. It is generated for apps with a
+// compliance profile.
+func generateAuditLog() string {
+	return `"""Audit log — records who changed what, and whether it succeeded.
+
+Each entry is a single JSON line with "type": "audit", written to stdout so
+it is kept with the container logs. The user comes from the bearer token;
+request bodies are not logged, since they may hold personal data.
+"""
+import json
+import sys
+from datetime import datetime, timezone
+
+from fastapi import Request
+from jose import JWTError, jwt
+
+from auth import ALGORITHM, SECRET_KEY
+
+READ_METHODS = {"GET", "HEAD", "OPTIONS"}
+
+
+def _user_id(request: Request):
+    header = request.headers.get("authorization", "")
+    if not header.startswith("Bearer "):
+        return None
+    try:
+        return jwt.decode(header[7:], SECRET_KEY, algorithms=[ALGORITHM]).get("sub")
+    except JWTError:
+        return None
+
+
+async def audit_log(request: Request, call_next):
+    if request.method in READ_METHODS:
+        return await call_next(request)
+    start = datetime.now(timezone.utc)
+    response = await call_next(request)
+    entry = {
+        "type": "audit",
+        "time": start.isoformat(),
+        "userId": _user_id(request),
+        "method": request.method,
+        "path": request.url.path,
+        "status": response.status_code,
+        "ip": request.client.host if request.client else None,
+    }
+    print(json.dumps(entry), file=sys.stdout, flush=True)
+    return response
+`
+}
@@ -0,0 +1,410 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// stripeKeyEnv returns the environment variable holding the payment
+// integration's secret key.
+func stripeKeyEnv(app *ir.Application) string {
+	if integ := ir.PaymentIntegration(app); integ != nil {
+		for _, envVar := range integ.Credentials {
+			return envVar
+		}
+	}
+	return "STRIPE_SECRET_KEY"
+}
+
+// planCheck returns the action and model an endpoint's plan check is made
+// for, e.g. "create" and "project" for CreateProject, or empty strings when
+// no plan's policy has a say in it.
+func planCheck(api *ir.Endpoint, app *ir.Application) (string, string) {
+	if !ir.Bills(app) || !api.Auth || api.Account != "" {
+		return "", ""
+	}
+	action, model := "", ""
+	for _, verb := range [][2]string{
+		{"Create", "create"}, {"Get", "view"}, {"List", "view"}, {"Fetch", "view"},
+		{"Update", "edit"}, {"Edit", "edit"}, {"Delete", "delete"}, {"Remove", "delete"},
+	} {
+		if strings.HasPrefix(api.Name, verb[0]) && len(api.Name) > len(verb[0]) {
+			action, model = verb[1], singularize(strings.ToLower(api.Name[len(verb[0]):]))
+			break
+		}
+	}
+	if action == "" {
+		return "", ""
+	}
+	for _, restricted := range planRestrictions(app) {
+		if restricted[1] == action && (restricted[2] == model || restricted[2] == "*") {
+			return action, model
+		}
+	}
+	if action == "create" {
+		for _, limit := range ir.PlanLimits(app) {
+			if limit.Word == model {
+				return action, model
+			}
+		}
+	}
+	return "", ""
+}
+
+// planRestrictions returns each plan's restricted actions as plan, action,
+// and model, from the policy named after the plan.
+func planRestrictions(app *ir.Application) [][3]string {
+	var restrictions [][3]string
+	for _, plan := range app.Billing.Plans {
+		for _, pol := range app.Policies {
+			if pol.Name != plan.Policy {
+				continue
+			}
+			for _, rest := range pol.Restrictions {
+				r := parsePolicyRuleText(rest.Text)
+				restrictions = append(restrictions, [3]string{plan.Name, r.action, r.model})
+			}
+		}
+	}
+	return restrictions
+}
+
+// generateBilling produces billing.py: the plan a user is on, their
+// subscription, starting Stripe Checkout for a paid plan, opening the
+// billing portal to change plan, update the card, or cancel, and the
+// webhook Stripe reports subscription changes to.
+func generateBilling(app *ir.Application) string {
+	var b strings.Builder
+	bill := app.Billing
+	fk := toSnakeCase(bill.Model) + "_id"
+
+	fmt.Fprintf(&b, `# Generated by Human compiler — do not edit
+import datetime
+import os
+
+import stripe
+from fastapi import APIRouter, Body, Depends, HTTPException, Request
+from sqlalchemy.orm import Session
+from typing import Any, Optional
+
+import models, auth
+from database import get_db
+
+stripe.api_key = os.environ.get('%s', '')
+
+router = APIRouter()
+
+# Subscriptions that keep their plan: past due ones while Stripe retries the payment.
+ACTIVE_STATUSES = ['active', 'trialing', 'past_due']
+
+`, stripeKeyEnv(app))
+	free := "None"
+	if p := bill.FreePlan(); p != nil {
+		free = "'" + p.Name + "'"
+	}
+	b.WriteString("# The plan users are on until they subscribe to another.\n")
+	fmt.Fprintf(&b, "FREE_PLAN: Optional[str] = %s\n\n\n", free)
+
+	fmt.Fprintf(&b, `def latest_subscription(db: Session, user_id: str) -> Optional[models.Subscription]:
+    return (
+        db.query(models.Subscription)
+        .filter(models.Subscription.%[1]s == user_id)
+        .order_by(models.Subscription.created_at.desc())
+        .first()
+    )
+
+
+def current_plan(db: Session, user_id: str) -> Optional[str]:
+    """The name of the plan a user is subscribed to, else the free plan."""
+    subscription = latest_subscription(db, user_id)
+    if subscription is not None and subscription.status in ACTIVE_STATUSES and subscription.plan is not None:
+        return subscription.plan.name
+    return FREE_PLAN
+
+
+def save_subscription(db: Session, subscription: Any) -> None:
+    """
+    Saves a subscription as Stripe reports it: its status, its plan, and when
+    it renews. Checkout tags the subscriptions it starts with the user's id;
+    others, e.g. made in the Stripe dashboard, are only kept in step once known.
+    """
+    items = subscription["items"]["data"]
+    price_id = items[0]["price"]["id"] if items else None
+    plan = db.query(models.Plan).filter(models.Plan.stripe_price_id == price_id).first() if price_id else None
+    customer = subscription["customer"]
+    data = {
+        "status": subscription["status"],
+        "stripe_customer_id": customer if isinstance(customer, str) else customer["id"],
+        "current_period_end": datetime.datetime.fromtimestamp(subscription["current_period_end"], datetime.timezone.utc),
+    }
+    if plan is not None:
+        data["plan_id"] = plan.id
+    record = db.query(models.Subscription).filter(models.Subscription.stripe_subscription_id == subscription["id"]).first()
+    user_id = (subscription.get("metadata") or {}).get("userId")
+    if record is None:
+        if plan is None or not user_id:
+            return
+        record = models.Subscription(stripe_subscription_id=subscription["id"], %[1]s=user_id)
+        db.add(record)
+    for key, value in data.items():
+        setattr(record, key, value)
+    db.commit()
+
+
+def serialize(subscription: Optional[models.Subscription]) -> Optional[dict]:
+    if subscription is None:
+        return None
+    return {
+        "id": subscription.id,
+        "status": subscription.status,
+        "current_period_end": subscription.current_period_end.isoformat() if subscription.current_period_end else None,
+        "plan": {"name": subscription.plan.name} if subscription.plan else None,
+    }
+
+
+def pricing_url(request: Request) -> str:
+    """The pricing page, which Checkout and the billing portal return to."""
+    base = os.environ.get('SITE_URL') or request.headers.get('origin') or ''
+    return base.rstrip('/') + '%[2]s'
+
+
+@router.get("/billing/subscription")
+def get_subscription(db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+    return {"data": {"plan": current_plan(db, current_user.id), "subscription": serialize(latest_subscription(db, current_user.id))}}
+
+
+@router.post("/billing/checkout")
+def checkout(request: Request, plan: str = Body("", embed=True), db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+    record = db.query(models.Plan).filter(models.Plan.name == plan).first()
+    if record is None:
+        raise HTTPException(status_code=404, detail="Plan not found")
+    if not record.stripe_price_id:
+        raise HTTPException(status_code=400, detail=f"The {record.name} plan can't be bought online")
+    latest = latest_subscription(db, current_user.id)
+    if latest is not None and latest.status in ACTIVE_STATUSES:
+        raise HTTPException(status_code=409, detail="You already have a subscription: change your plan from the billing portal")
+    params = {
+        "mode": "subscription",
+        "line_items": [{"price": record.stripe_price_id, "quantity": 1}],
+        "client_reference_id": str(current_user.id),
+        "subscription_data": {"metadata": {"userId": str(current_user.id)}},
+        "success_url": pricing_url(request) + "?checkout=success",
+        "cancel_url": pricing_url(request),
+    }
+    if latest is not None:
+        params["customer"] = latest.stripe_customer_id
+`, fk, bill.PagePath())
+	for _, m := range app.Data {
+		if m.Name == bill.Model && m.FieldNamed("email") != nil {
+			b.WriteString("    else:\n        params[\"customer_email\"] = current_user.email\n")
+		}
+	}
+	b.WriteString(`    session = stripe.checkout.Session.create(**params)
+    return {"data": {"url": session.url}}
+
+
+@router.post("/billing/portal")
+def portal(request: Request, db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+    latest = latest_subscription(db, current_user.id)
+    if latest is None:
+        raise HTTPException(status_code=404, detail="You have no subscription to manage")
+    session = stripe.billing_portal.Session.create(customer=latest.stripe_customer_id, return_url=pricing_url(request))
+    return {"data": {"url": session.url}}
+
+
+# Stripe reports subscriptions starting, changing plan, renewing, and
+# ending here; the raw body is read so its signature can be checked.
+@router.post("/billing/webhook")
+async def webhook(request: Request, db: Session = Depends(get_db)):
+    payload = await request.body()
+    try:
+        event = stripe.Webhook.construct_event(payload, request.headers.get("stripe-signature"), os.environ.get("STRIPE_WEBHOOK_SECRET", ""))
+    except (ValueError, stripe.error.SignatureVerificationError):
+        raise HTTPException(status_code=400, detail="Webhook verification failed")
+    if event["type"] in ("customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted"):
+        save_subscription(db, event["data"]["object"])
+    return {"received": True}
+`)
+	return b.String()
+}
+
+// generatePlans produces plans.py: require_plan(), a dependency checking the
+// signed-in user's plan against the policy named after it. Restricted
+// actions and creations past the plan's limit are refused with 402 Payment
+// Required, so the frontend can offer an upgrade.
+func generatePlans(app *ir.Application) string {
+	var b strings.Builder
+	fk := toSnakeCase(app.Billing.Model) + "_id"
+
+	b.WriteString(`# Generated by Human compiler — do not edit
+import datetime
+
+from fastapi import Depends, HTTPException
+from sqlalchemy.orm import Session
+from typing import Any, Optional
+
+import models, auth
+from billing import current_plan
+from database import get_db
+
+# What each plan's subscribers can't do, as (plan, action, model).
+RESTRICTIONS = [
+`)
+	for _, r := range planRestrictions(app) {
+		fmt.Fprintf(&b, "    ('%s', '%s', '%s'),\n", r[0], r[1], r[2])
+	}
+	b.WriteString("]\n\n")
+
+	b.WriteString("# How many records each plan's subscribers can create, as (plan, model, limit, period).\n")
+	b.WriteString("LIMITS = [\n")
+	for _, limit := range ir.PlanLimits(app) {
+		if !limit.Counted(app) {
+			continue
+		}
+		period := "None"
+		if limit.Period != "" {
+			period = "'" + limit.Period + "'"
+		}
+		fmt.Fprintf(&b, "    ('%s', '%s', %d, %s),\n", limit.Plan, limit.Word, limit.Limit, period)
+	}
+	b.WriteString("]\n\n")
+
+	b.WriteString("# The model each limited word counts.\n")
+	b.WriteString("MODELS = {\n")
+	seen := map[string]bool{}
+	for _, limit := range ir.PlanLimits(app) {
+		if !limit.Counted(app) || seen[limit.Word] {
+			continue
+		}
+		seen[limit.Word] = true
+		fmt.Fprintf(&b, "    '%s': models.%s,\n", limit.Word, toPascalCase(limit.Model))
+	}
+	b.WriteString("}\n\n\n")
+
+	fmt.Fprintf(&b, `def period_start(period: Optional[str]) -> Optional[datetime.datetime]:
+    """The start of the day, week, month, or year a limit counts from; None counts all time."""
+    now = datetime.datetime.now(datetime.timezone.utc)
+    today = now.replace(hour=0, minute=0, second=0, microsecond=0)
+    if period == 'day':
+        return today
+    if period == 'week':
+        return today - datetime.timedelta(days=(today.weekday() + 1) %% 7)
+    if period == 'month':
+        return today.replace(day=1)
+    if period == 'year':
+        return today.replace(month=1, day=1)
+    return None
+
+
+def require_plan(action: str, model: str):
+    """
+    Plan dependency — checks the signed-in user's plan against its policy.
+
+    Usage:
+        @router.post('/projects')
+        def create_project(current_user = Depends(auth.get_current_user),
+                           _plan = Depends(require_plan('create', 'project'))):
+
+    Behavior:
+        1. If a restriction matches the action+model -> %[1]d
+        2. If a limit on creating the model has been reached -> %[1]d
+        3. Otherwise -> allowed
+    """
+    def dependency(db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+        plan = current_plan(db, current_user.id)
+        if plan is None:
+            raise HTTPException(status_code=%[1]d, detail=f"Subscribe to a plan to {action} {model}")
+        for name, act, mod in RESTRICTIONS:
+            if name == plan and act == action and (mod == model or mod == '*'):
+                raise HTTPException(status_code=%[1]d, detail=f"The {plan} plan can't {action} {model}")
+        if action != 'create' or model not in MODELS:
+            return current_user
+        for name, mod, limit, period in LIMITS:
+            if name != plan or mod != model:
+                continue
+            counted = MODELS[model]
+            query = db.query(counted).filter(counted.%[2]s == current_user.id)
+            since = period_start(period)
+            if since is not None:
+                query = query.filter(counted.created_at >= since)
+            if query.count() >= limit:
+                per = f" per {period}" if period else ""
+                raise HTTPException(status_code=%[1]d, detail=f"The {plan} plan's limit is {limit}{per}: upgrade to {action} another {model}")
+        return current_user
+
+    return dependency
+`, ir.PlanUpgradeStatus, fk)
+	return b.String()
+}
+
+// generatePlanSync produces sync_plans.py, run with `python sync_plans.py`:
+// it creates each paid plan's Stripe price and stores the plans with their
+// prices, which checkout and the webhook look up.
+func generatePlanSync(app *ir.Application) string {
+	var b strings.Builder
+	bill := app.Billing
+	b.WriteString(`# Generated by Human compiler — do not edit
+#
+# Creates each paid plan's Stripe product and price, and stores the plans
+# with their prices. Run after changing the plans block:
+#   python sync_plans.py
+# Stripe prices can't change, so a new price is created under a new lookup
+# key; existing subscribers stay on the old one until they change plan.
+import re
+
+import stripe
+
+import billing  # sets the Stripe API key
+import models
+from database import SessionLocal
+
+PLANS = [
+`)
+	for _, plan := range bill.Plans {
+		interval := "None"
+		if plan.Paid() {
+			interval = "'" + plan.Interval + "'"
+		}
+		fmt.Fprintf(&b, "    {'name': '%s', 'price': %d, 'interval': %s},\n", plan.Name, plan.Price, interval)
+	}
+	fmt.Fprintf(&b, `]
+
+
+def main() -> None:
+    db = SessionLocal()
+    try:
+        for position, plan in enumerate(PLANS):
+            stripe_price_id = None
+            if plan['price'] > 0 and plan['interval']:
+                slug = re.sub(r'\W+', '_', plan['name'].lower())
+                lookup_key = f"{slug}_{plan['price']}_{plan['interval']}"
+                existing = stripe.Price.list(lookup_keys=[lookup_key], limit=1)
+                price = existing.data[0] if existing.data else stripe.Price.create(
+                    currency='%s',
+                    unit_amount=plan['price'],
+                    recurring={'interval': plan['interval']},
+                    lookup_key=lookup_key,
+                    product_data={'name': plan['name']},
+                )
+                stripe_price_id = price.id
+            record = db.query(models.Plan).filter(models.Plan.name == plan['name']).first()
+            if record is None:
+                record = models.Plan(name=plan['name'])
+                db.add(record)
+            record.price = plan['price']
+            record.interval = plan['interval']
+            record.position = position
+            record.stripe_price_id = stripe_price_id
+            db.commit()
+            print(f"{plan['name']}: {stripe_price_id or 'not sold online'}")
+    finally:
+        db.close()
+
+
+if __name__ == "__main__":
+    main()
+`, bill.Currency)
+	return b.String()
+}
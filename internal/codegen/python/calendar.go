@@ -0,0 +1,223 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateCalendarICS produces calendar_ics.py: an iCalendar (RFC 5545)
+// writer. Times are written in UTC, all-day events as dates, and text is
+// escaped and folded at 75 octets. Naive datetimes are read in the
+// calendar's timezone.
+func generateCalendarICS(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("import datetime\n")
+	b.WriteString("from dataclasses import dataclass\n")
+	b.WriteString("from typing import List, Optional, Union\n")
+	b.WriteString("from zoneinfo import ZoneInfo\n\n")
+	fmt.Fprintf(&b, "PRODID = '-//Human//%s//EN'\n\n", strings.ReplaceAll(app.Name, "'", "\\'"))
+
+	b.WriteString(`
+@dataclass
+class CalendarEvent:
+    uid: str
+    title: str
+    start: Union[datetime.date, datetime.datetime]
+    end: Optional[Union[datetime.date, datetime.datetime]] = None
+    location: Optional[str] = None
+    description: Optional[str] = None
+
+
+def escape_text(value: str) -> str:
+    """Escapes a TEXT value: backslashes, semicolons, commas, and newlines."""
+    return (
+        value.replace('\\', '\\\\')
+        .replace(';', '\\;')
+        .replace(',', '\\,')
+        .replace('\r\n', '\\n')
+        .replace('\n', '\\n')
+    )
+
+
+def fold(line: str) -> str:
+    """Folds a content line so no line exceeds 75 octets; continuation
+    lines start with a space."""
+    lines, current, size = [], '', 0
+    for ch in line:
+        n = len(ch.encode('utf-8'))
+        if size + n > 75:
+            lines.append(current)
+            current, size = ' ', 1
+        current += ch
+        size += n
+    lines.append(current)
+    return '\r\n'.join(lines)
+
+
+def to_utc(value: datetime.datetime, timezone: str) -> datetime.datetime:
+    """Converts to UTC, reading naive datetimes in the calendar's timezone."""
+    if value.tzinfo is None:
+        value = value.replace(tzinfo=ZoneInfo(timezone))
+    return value.astimezone(datetime.timezone.utc)
+
+
+def format_datetime(value: datetime.datetime, timezone: str) -> str:
+    return to_utc(value, timezone).strftime('%Y%m%dT%H%M%SZ')
+
+
+def is_all_day(value) -> bool:
+    return isinstance(value, datetime.date) and not isinstance(value, datetime.datetime)
+
+
+def _event_lines(event: CalendarEvent, timezone: str, stamp: str) -> List[str]:
+    lines = ['BEGIN:VEVENT', f'UID:{event.uid}', f'DTSTAMP:{stamp}']
+    if is_all_day(event.start):
+        # DTEND is exclusive, so a one-day event ends the next day.
+        end = (event.end or event.start) + datetime.timedelta(days=1)
+        lines.append(f"DTSTART;VALUE=DATE:{event.start.strftime('%Y%m%d')}")
+        lines.append(f"DTEND;VALUE=DATE:{end.strftime('%Y%m%d')}")
+    else:
+        end = event.end or event.start + datetime.timedelta(hours=1)
+        lines.append(f'DTSTART:{format_datetime(event.start, timezone)}')
+        lines.append(f'DTEND:{format_datetime(end, timezone)}')
+    lines.append(f'SUMMARY:{escape_text(event.title)}')
+    if event.location:
+        lines.append(f'LOCATION:{escape_text(event.location)}')
+    if event.description:
+        lines.append(f'DESCRIPTION:{escape_text(event.description)}')
+    lines.append('END:VEVENT')
+    return lines
+
+
+def to_ics(name: str, timezone: str, events: List[CalendarEvent]) -> str:
+    """Renders events as an iCalendar document with CRLF line endings."""
+    stamp = datetime.datetime.now(datetime.timezone.utc).strftime('%Y%m%dT%H%M%SZ')
+    lines = [
+        'BEGIN:VCALENDAR',
+        'VERSION:2.0',
+        f'PRODID:{PRODID}',
+        'CALSCALE:GREGORIAN',
+        'METHOD:PUBLISH',
+        f'X-WR-CALNAME:{escape_text(name)}',
+        f'X-WR-TIMEZONE:{timezone}',
+    ]
+    for event in events:
+        lines.extend(_event_lines(event, timezone, stamp))
+    lines.append('END:VCALENDAR')
+    return '\r\n'.join(fold(line) for line in lines) + '\r\n'
+`)
+
+	return b.String()
+}
+
+// generateCalendarICSTests produces tests/test_calendar_ics.py, which
+// checks the iCalendar writer's output format.
+func generateCalendarICSTests() string {
+	return `# Generated by Human compiler — do not edit
+import datetime
+
+from calendar_ics import CalendarEvent, escape_text, fold, to_ics
+
+
+def test_timed_events_are_utc_and_an_hour_long_by_default():
+    start = datetime.datetime(2026, 3, 1, 18, 30, tzinfo=datetime.timezone.utc)
+    ics = to_ics('Events', 'UTC', [CalendarEvent(uid='event-1@test', title='Launch', start=start)])
+    assert 'DTSTART:20260301T183000Z\r\n' in ics
+    assert 'DTEND:20260301T193000Z\r\n' in ics
+    assert ics.startswith('BEGIN:VCALENDAR\r\n')
+    assert ics.endswith('END:VCALENDAR\r\n')
+
+
+def test_naive_times_are_read_in_the_calendar_timezone():
+    start = datetime.datetime(2026, 3, 1, 9, 0)
+    ics = to_ics('Events', 'America/New_York', [CalendarEvent(uid='event-1@test', title='Launch', start=start)])
+    assert 'DTSTART:20260301T140000Z\r\n' in ics
+
+
+def test_all_day_events_use_dates_with_an_exclusive_end():
+    ics = to_ics('Events', 'UTC', [CalendarEvent(uid='event-1@test', title='Holiday', start=datetime.date(2026, 3, 1))])
+    assert 'DTSTART;VALUE=DATE:20260301\r\n' in ics
+    assert 'DTEND;VALUE=DATE:20260302\r\n' in ics
+
+
+def test_escapes_text_values():
+    assert escape_text('a, b; c\\d\ne') == 'a\\, b\\; c\\\\d\\ne'
+
+
+def test_folds_long_lines_at_75_octets():
+    lines = fold('DESCRIPTION:' + 'x' * 200).split('\r\n')
+    assert len(lines) > 1
+    assert all(len(line.encode('utf-8')) <= 75 for line in lines)
+    assert all(line.startswith(' ') for line in lines[1:])
+`
+}
+
+// generateCalendarFeeds produces calendar_feeds.py: for each calendar, an
+// ICS feed of every record (GET /api/calendar/events.ics) and a single
+// record's .ics download (GET /api/calendar/events/{id}.ics). The feeds
+// are public so calendar apps can subscribe to them.
+func generateCalendarFeeds(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString(`# Generated by Human compiler — do not edit
+from fastapi import APIRouter, Depends, HTTPException
+from fastapi.responses import Response
+from sqlalchemy.orm import Session
+
+import models
+from database import get_db
+from calendar_ics import CalendarEvent, to_ics
+
+router = APIRouter()
+
+
+def ics_response(ics: str, filename: str = '') -> Response:
+    headers = {'Content-Disposition': f'attachment; filename="{filename}"'} if filename else {}
+    return Response(content=ics, media_type='text/calendar; charset=utf-8', headers=headers)
+`)
+
+	uidDomain := strings.ToLower(strings.ReplaceAll(app.Name, " ", "-"))
+	for _, cal := range app.Calendars {
+		class := "models." + toPascalCase(cal.Model)
+		fn := toSnakeCase(cal.Model)
+		start := toSnakeCase(cal.Start)
+
+		fmt.Fprintf(&b, "\n\ndef %s_calendar_event(record) -> CalendarEvent:\n", fn)
+		b.WriteString("    return CalendarEvent(\n")
+		fmt.Fprintf(&b, "        uid=f'%s-{record.id}@%s',\n", fn, uidDomain)
+		if cal.Title != "" {
+			fmt.Fprintf(&b, "        title=str(record.%s or '%s'),\n", toSnakeCase(cal.Title), cal.Model)
+		} else {
+			fmt.Fprintf(&b, "        title='%s',\n", cal.Model)
+		}
+		fmt.Fprintf(&b, "        start=record.%s,\n", start)
+		if cal.End != "" {
+			fmt.Fprintf(&b, "        end=record.%s,\n", toSnakeCase(cal.End))
+		}
+		if cal.Location != "" {
+			fmt.Fprintf(&b, "        location=record.%s,\n", toSnakeCase(cal.Location))
+		}
+		if cal.Description != "" {
+			fmt.Fprintf(&b, "        description=record.%s,\n", toSnakeCase(cal.Description))
+		}
+		b.WriteString("    )\n")
+
+		fmt.Fprintf(&b, "\n\n@router.get('/calendar/%s.ics')\n", cal.Slug)
+		fmt.Fprintf(&b, "def %s_feed(db: Session = Depends(get_db)):\n", fn)
+		fmt.Fprintf(&b, "    records = db.query(%s).filter(%s.%s.isnot(None)).all()\n", class, class, start)
+		fmt.Fprintf(&b, "    return ics_response(to_ics('%s', '%s', [%s_calendar_event(r) for r in records]))\n", cal.Name(), cal.Timezone, fn)
+
+		fmt.Fprintf(&b, "\n\n@router.get('/calendar/%s/{record_id}.ics')\n", cal.Slug)
+		fmt.Fprintf(&b, "def %s_ics(record_id: str, db: Session = Depends(get_db)):\n", fn)
+		fmt.Fprintf(&b, "    record = db.query(%s).filter(%s.id == record_id).first()\n", class, class)
+		fmt.Fprintf(&b, "    if record is None or record.%s is None:\n", start)
+		fmt.Fprintf(&b, "        raise HTTPException(status_code=404, detail='%s not found')\n", cal.Model)
+		fmt.Fprintf(&b, "    return ics_response(to_ics('%s', '%s', [%s_calendar_event(record)]), f'%s-{record.id}.ics')\n", cal.Name(), cal.Timezone, fn, fn)
+	}
+
+	return b.String()
+}
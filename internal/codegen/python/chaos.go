@@ -0,0 +1,86 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateChaos produces chaos.py: with chaos mode on (`human run
+// --chaos`), some API requests fail the way the error handlers are
+// declared for, so their retries, fallbacks, and alerts can be watched at
+// work. It does nothing otherwise.
+func generateChaos(app *ir.Application) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `"""Chaos mode — failures the .human file's error handlers are declared for.
+
+Off unless %[1]s=1. It then hits a share of API requests (%[2]s,
+0.1 by default), adding up to %[3]s of latency (2000 by default)
+and making one handler's failure happen some attempts in a row. A request
+with an attempt succeeding within the handler's attempts goes through; the
+rest are answered with the handler's response, and its alert is logged.
+Responses chaos mode answered carry an X-Chaos header naming the failure.
+"""
+import asyncio
+import logging
+import os
+import random
+
+from fastapi import Request
+from fastapi.responses import JSONResponse
+
+logger = logging.getLogger(__name__)
+
+ENABLED = os.getenv("%[1]s", "") in ("1", "true")
+RATE = float(os.getenv("%[2]s", "0.1"))
+MAX_LATENCY_MS = int(os.getenv("%[3]s", "2000"))
+
+# The failure each error handler in the .human file is declared for
+FAULTS = [
+`, ir.ChaosEnv, ir.ChaosRateEnv, ir.ChaosLatencyEnv)
+	for _, f := range ir.ChaosFaults(app) {
+		sb.WriteString("    {\n")
+		fmt.Fprintf(&sb, "        \"condition\": %q,\n", f.Condition)
+		fmt.Fprintf(&sb, "        \"attempts\": %d,\n", f.Attempts)
+		fmt.Fprintf(&sb, "        \"delay_ms\": %d,\n", f.DelayMs)
+		fmt.Fprintf(&sb, "        \"status\": %d,\n", f.Status)
+		fmt.Fprintf(&sb, "        \"message\": %q,\n", f.Message)
+		if f.Alert != "" {
+			fmt.Fprintf(&sb, "        \"alert\": %q,\n", f.Alert)
+		}
+		sb.WriteString("    },\n")
+	}
+	sb.WriteString(`]
+
+if ENABLED:
+    logger.warning("[chaos] Injecting failures into %d%% of API requests", round(RATE * 100))
+
+
+async def chaos(request: Request, call_next):
+    if not ENABLED or not request.url.path.startswith("/api/") or random.random() >= RATE:
+        return await call_next(request)
+    fault = random.choice(FAULTS)
+    failures = random.randint(1, fault["attempts"])
+    await asyncio.sleep(
+        (random.random() * MAX_LATENCY_MS + min(failures, fault["attempts"] - 1) * fault["delay_ms"]) / 1000
+    )
+
+    target = f"{request.method} {request.url.path}"
+    if failures < fault["attempts"]:
+        logger.warning("[chaos] %s: %s, recovered on attempt %d of %d", target, fault["condition"], failures + 1, fault["attempts"])
+        return await call_next(request)
+    logger.warning(
+        "[chaos] %s: %s, failed %d of %d attempts -> %d",
+        target, fault["condition"], fault["attempts"], fault["attempts"], fault["status"],
+    )
+    if fault.get("alert"):
+        logger.warning("[chaos] %s", fault["alert"])
+    return JSONResponse(
+        status_code=fault["status"],
+        content={"detail": fault["message"]},
+        headers={"X-Chaos": fault["condition"]},
+    )
+`)
+	return sb.String()
+}
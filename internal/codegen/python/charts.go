@@ -0,0 +1,135 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateAggregateHelpers produces aggregates.py, shared by chart routes
+// and report endpoints.
+func generateAggregateHelpers() string {
+	return `# Generated by Human compiler — do not edit
+from datetime import datetime, timedelta, timezone
+
+
+def since(days: int) -> datetime:
+    """The start of the last n days."""
+    return datetime.now(timezone.utc) - timedelta(days=days)
+
+
+def points(rows) -> dict:
+    """Chart or report rows from (label, value) rows."""
+    return {'data': [{'label': 'None' if label is None else str(label), 'value': float(value or 0)} for label, value in rows]}
+`
+}
+
+// hasAggregates reports whether any chart or report endpoint needs the
+// shared aggregate helpers.
+func hasAggregates(app *ir.Application) bool {
+	return len(app.Charts) > 0 || hasReports(app)
+}
+
+// hasReports reports whether any endpoint serves an aggregate.
+func hasReports(app *ir.Application) bool {
+	for _, ep := range app.APIs {
+		if ep.Aggregate != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// generateCharts produces charts.py: for each chart, a GET
+// /api/charts/<slug> returning {"data": [{"label", "value"}]}. Points for
+// a model that belongs to a user are limited to the signed-in user's
+// records.
+func generateCharts(app *ir.Application) string {
+	var b strings.Builder
+	authed := app.Auth != nil
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("from typing import Any\n\n")
+	b.WriteString("from fastapi import APIRouter, Depends\n")
+	b.WriteString("from sqlalchemy import func\n")
+	b.WriteString("from sqlalchemy.orm import Session\n\n")
+	if authed {
+		b.WriteString("import models, auth\n")
+	} else {
+		b.WriteString("import models\n")
+	}
+	b.WriteString("from aggregates import since, points\n")
+	b.WriteString("from database import get_db\n")
+	b.WriteString("\nrouter = APIRouter()\n")
+
+	for _, c := range app.Charts {
+		scoped := authed && modelBelongsToUser(c.Model, app) && !strings.EqualFold(c.Model, "User")
+		fn := "chart_" + strings.ReplaceAll(c.Slug, "-", "_")
+		fmt.Fprintf(&b, "\n\n@router.get('/charts/%s')\n", c.Slug)
+		if authed {
+			fmt.Fprintf(&b, "def %s(db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):\n", fn)
+		} else {
+			fmt.Fprintf(&b, "def %s(db: Session = Depends(get_db)):\n", fn)
+		}
+		fmt.Fprintf(&b, "    \"\"\"%s.\"\"\"\n", c.Title)
+		writeAggregateQuery(&b, &c.Aggregate, scoped)
+	}
+
+	return b.String()
+}
+
+// writeAggregateQuery emits the GROUP BY query computing an aggregate's
+// rows and returns them. Time series are bucketed with date_trunc.
+func writeAggregateQuery(b *strings.Builder, agg *ir.Aggregate, scoped bool) {
+	class := "models." + toPascalCase(agg.Model)
+	column := func(field string) string { return class + "." + toSnakeCase(field) }
+
+	if agg.Func == "count" {
+		fmt.Fprintf(b, "    value = func.count(%s.id)\n", class)
+	} else {
+		fmt.Fprintf(b, "    value = func.coalesce(func.%s(%s), 0)\n", agg.Func, column(agg.Field))
+	}
+
+	date := class + ".created_at"
+	if agg.Date != "" {
+		date = column(agg.Date)
+	}
+	var group, order string
+	switch {
+	case agg.Period != "":
+		pattern := map[string]string{"day": "YYYY-MM-DD", "week": "YYYY-MM-DD", "month": "YYYY-MM", "year": "YYYY"}[agg.Period]
+		fmt.Fprintf(b, "    bucket = func.to_char(func.date_trunc('%s', %s), '%s')\n", agg.Period, date, pattern)
+		b.WriteString("    query = db.query(bucket, value)\n")
+		group, order = "bucket", "bucket"
+	case agg.Relation != "" && agg.Label != "":
+		related := "models." + toPascalCase(agg.Relation)
+		fk := column(toSnakeCase(agg.Relation) + "_id")
+		fmt.Fprintf(b, "    query = db.query(%s.%s, value).select_from(%s)\n", related, toSnakeCase(agg.Label), class)
+		fmt.Fprintf(b, "    query = query.outerjoin(%s, %s == %s.id)\n", related, fk, related)
+		group, order = fmt.Sprintf("%s.id, %s.%s", related, related, toSnakeCase(agg.Label)), "value.desc()"
+	case agg.Relation != "":
+		fk := column(toSnakeCase(agg.Relation) + "_id")
+		fmt.Fprintf(b, "    query = db.query(%s, value)\n", fk)
+		group, order = fk, "value.desc()"
+	default:
+		fmt.Fprintf(b, "    query = db.query(%s, value)\n", column(agg.GroupBy))
+		group, order = column(agg.GroupBy), "value.desc()"
+	}
+	if scoped {
+		fmt.Fprintf(b, "    query = query.filter(%s.user_id == current_user.id)\n", class)
+	}
+	if agg.Days > 0 {
+		fmt.Fprintf(b, "    query = query.filter(%s >= since(%d))\n", date, agg.Days)
+	}
+	fmt.Fprintf(b, "    return points(query.group_by(%s).order_by(%s).all())\n", group, order)
+}
+
+// cacheControl returns the Cache-Control header of a report endpoint:
+// private when the rows depend on who asks.
+func cacheControl(ep *ir.Endpoint) string {
+	if ep.Auth {
+		return fmt.Sprintf("private, max-age=%d", ep.Cache)
+	}
+	return fmt.Sprintf("public, max-age=%d", ep.Cache)
+}
@@ -0,0 +1,59 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateConcurrency produces concurrency.py, reading the version of a
+// record an update was made to and answering updates made to an older one.
+func generateConcurrency() string {
+	return `from typing import Any, Optional
+
+from fastapi.encoders import jsonable_encoder
+from fastapi.responses import JSONResponse
+
+
+def expected_version(if_match: Optional[str], version: Optional[int]) -> Optional[int]:
+    """The version of the record an update was made to: the If-Match
+    header's ("3" or W/"3"), else the body's version field."""
+    raw = if_match.removeprefix('W/').strip('"') if if_match is not None else version
+    try:
+        expected = int(raw)
+    except (TypeError, ValueError):
+        return None
+    return expected if expected > 0 else None
+
+
+def conflict(message: str, current: Any) -> JSONResponse:
+    """409 Conflict with the record as it is now, so the client can keep
+    its changes or take the saved ones."""
+    return JSONResponse(status_code=409, content={'error': message, 'current': jsonable_encoder(current)})
+`
+}
+
+// writeVersionedUpdate writes the update of a record protected against
+// conflicting edits. The record's version must be the one the client
+// edited; SQLAlchemy's version counter bumps it and refuses the write if
+// someone else saved in between.
+func writeVersionedUpdate(sb *strings.Builder, m *ir.DataModel) {
+	label := strings.ToLower(ir.FieldLabel(m.Name))
+	current := fmt.Sprintf("schemas.%sResponse.model_validate(item)", toPascalCase(m.Name))
+	conflict := fmt.Sprintf("        return concurrency.conflict('This %s was changed by someone else', %s)\n", label, current)
+	sb.WriteString("    expected = concurrency.expected_version(if_match, payload.version)\n")
+	sb.WriteString("    if expected is None:\n")
+	fmt.Fprintf(sb, "        raise HTTPException(status_code=428, detail='Send the version of the %s you edited in an If-Match header')\n", label)
+	fmt.Fprintf(sb, "    if item.%s != expected:\n", ir.VersionColumn)
+	sb.WriteString(conflict)
+	fmt.Fprintf(sb, "    for key, value in payload.model_dump(exclude_unset=True, exclude={'%s'}).items():\n", ir.VersionColumn)
+	sb.WriteString("        setattr(item, key, value)\n")
+	sb.WriteString("    try:\n")
+	sb.WriteString("        db.commit()\n")
+	sb.WriteString("    except StaleDataError:\n")
+	sb.WriteString("        db.rollback()\n")
+	sb.WriteString("        db.refresh(item)\n")
+	sb.WriteString(conflict)
+	sb.WriteString("    db.refresh(item)\n")
+}
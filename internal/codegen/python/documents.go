@@ -0,0 +1,277 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// documentName returns the snake_case name of a document's functions:
+// an Order's invoice → "order_invoice".
+func documentName(doc *ir.Document) string {
+	return toSnakeCase(doc.Model) + "_" + strings.ReplaceAll(doc.Kind, " ", "_")
+}
+
+// writeDocumentCall emits a "generate a PDF invoice from the Order" step:
+// the PDF is rendered and kept in the storage integration. Without one,
+// it is only rendered when downloaded. record is the variable holding the
+// document's model, or "" if the endpoint has none.
+func writeDocumentCall(sb *strings.Builder, doc *ir.Document, api *ir.Endpoint, app *ir.Application, record string) {
+	if !hasStorageIntegration(app) {
+		fmt.Fprintf(sb, "    # The %s is rendered when downloaded from /api/documents/%s/{id}.pdf\n", doc.Kind, doc.Slug)
+		return
+	}
+	id := ""
+	if record != "" {
+		id = record + ".id"
+	} else if len(api.Params) > 0 {
+		id = "payload." + findIDParam(api)
+	}
+	if id == "" {
+		fmt.Fprintf(sb, "    # TODO: no %s to render the %s from — fetch or create it first\n", doc.Model, doc.Kind)
+		return
+	}
+	fmt.Fprintf(sb, "    documents.store_%s(db, %s)\n", documentName(doc), id)
+}
+
+// pdfFonts returns the regular and bold standard PDF fonts for a family.
+func pdfFonts(family string) (regular, bold string) {
+	switch family {
+	case "Times":
+		return "Times-Roman", "Times-Bold"
+	case "Courier":
+		return "Courier", "Courier-Bold"
+	}
+	return "Helvetica", "Helvetica-Bold"
+}
+
+// generateDocuments produces documents.py: a ReportLab renderer styled
+// from the app's theme, for each document a function mapping a record to
+// its content and one rendering it by id, and the PDF downloads at
+// GET /api/documents/<slug>/{id}.pdf. With authentication, downloads need
+// a signed-in user, who only sees records they own. With a storage
+// integration, documents can also be stored as documents/<slug>/<id>.pdf.
+func generateDocuments(app *ir.Application) string {
+	var b strings.Builder
+	storage := hasStorageIntegration(app)
+	authed := app.Auth != nil
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("import datetime\n")
+	b.WriteString("import io\n")
+	b.WriteString("from dataclasses import dataclass, field\n")
+	b.WriteString("from decimal import Decimal\n")
+	b.WriteString("from typing import Any, List, Optional, Tuple\n\n")
+	b.WriteString("from fastapi import APIRouter, Depends, HTTPException\n")
+	b.WriteString("from fastapi.responses import Response\n")
+	b.WriteString("from reportlab.lib.colors import HexColor, white\n")
+	b.WriteString("from reportlab.lib.pagesizes import A4\n")
+	b.WriteString("from reportlab.pdfgen import canvas\n")
+	b.WriteString("from sqlalchemy.orm import Session\n\n")
+	if authed {
+		b.WriteString("import models, auth\n")
+	} else {
+		b.WriteString("import models\n")
+	}
+	b.WriteString("from database import get_db\n")
+	if storage {
+		b.WriteString("from services.storage_service import s3_client, BUCKET\n")
+	}
+	b.WriteString("\nrouter = APIRouter()\n\n")
+
+	color, family := ir.PDFStyle(app.Theme)
+	regular, bold := pdfFonts(family)
+	fmt.Fprintf(&b, "APP_NAME = '%s'\n\n", strings.ReplaceAll(app.Name, "'", "\\'"))
+	b.WriteString("# From the app's theme.\n")
+	fmt.Fprintf(&b, "PRIMARY = HexColor('%s')\n", color)
+	b.WriteString("TEXT = HexColor('#111827')\n")
+	b.WriteString("MUTED = HexColor('#6b7280')\n")
+	b.WriteString("RULE = HexColor('#e5e7eb')\n")
+	fmt.Fprintf(&b, "FONT = '%s'\n", regular)
+	fmt.Fprintf(&b, "BOLD = '%s'\n", bold)
+
+	b.WriteString(`
+
+@dataclass
+class PdfContent:
+    title: str
+    reference: str
+    date: datetime.date
+    details: List[Tuple[str, str]]
+    columns: List[str] = field(default_factory=list)
+    rows: List[List[str]] = field(default_factory=list)
+    total: Optional[Tuple[str, str]] = None
+
+
+def format_value(value) -> str:
+    """Formats a field value: dates as YYYY-MM-DD, decimals to two places."""
+    if value is None or value == '':
+        return '-'
+    if isinstance(value, (datetime.date, datetime.datetime)):
+        return value.strftime('%Y-%m-%d')
+    if isinstance(value, (float, Decimal)):
+        return f'{value:.2f}'
+    return str(value)
+
+
+def render_pdf(content: PdfContent) -> bytes:
+    """Renders a document: a header band in the theme color, the title,
+    reference, and date, the detail rows, the items table, and the total."""
+    buffer = io.BytesIO()
+    pdf = canvas.Canvas(buffer, pagesize=A4)
+    pdf.setTitle(f'{content.title} {content.reference}')
+    pdf.setAuthor(APP_NAME)
+    width, height = A4
+    left, right = 50, width - 50
+
+    pdf.setFillColor(PRIMARY)
+    pdf.rect(0, height - 80, width, 80, stroke=0, fill=1)
+    pdf.setFillColor(white)
+    pdf.setFont(BOLD, 18)
+    pdf.drawString(left, height - 48, APP_NAME)
+
+    y = height - 130
+    pdf.setFillColor(PRIMARY)
+    pdf.setFont(BOLD, 24)
+    pdf.drawString(left, y, content.title)
+    pdf.setFillColor(MUTED)
+    pdf.setFont(FONT, 10)
+    pdf.drawString(left, y - 20, content.reference)
+    pdf.drawString(left, y - 34, content.date.strftime('%Y-%m-%d'))
+    y -= 64
+
+    for label, value in content.details:
+        pdf.setFillColor(MUTED)
+        pdf.drawString(left, y, label)
+        pdf.setFillColor(TEXT)
+        pdf.drawString(left + 160, y, value)
+        y -= 16
+
+    if content.columns:
+        y -= 16
+        col_width = (right - left) / len(content.columns)
+        pdf.setFillColor(PRIMARY)
+        pdf.rect(left, y - 6, right - left, 20, stroke=0, fill=1)
+        pdf.setFillColor(white)
+        pdf.setFont(BOLD, 10)
+        for i, col in enumerate(content.columns):
+            pdf.drawString(left + i * col_width + 5, y, col)
+        y -= 22
+        pdf.setFont(FONT, 10)
+        for row in content.rows:
+            if y < 80:
+                pdf.showPage()
+                pdf.setFont(FONT, 10)
+                y = height - 50
+            pdf.setFillColor(TEXT)
+            for i, cell in enumerate(row):
+                pdf.drawString(left + i * col_width + 5, y, cell)
+            pdf.setStrokeColor(RULE)
+            pdf.line(left, y - 6, right, y - 6)
+            y -= 18
+
+    if content.total:
+        y -= 20
+        pdf.setFillColor(PRIMARY)
+        pdf.setFont(BOLD, 14)
+        pdf.drawString(left, y, content.total[0])
+        pdf.drawRightString(right, y, content.total[1])
+
+    pdf.showPage()
+    pdf.save()
+    return buffer.getvalue()
+
+
+def pdf_response(pdf: bytes, filename: str) -> Response:
+    headers = {'Content-Disposition': f'attachment; filename="{filename}"'}
+    return Response(content=pdf, media_type='application/pdf', headers=headers)
+`)
+
+	for _, doc := range app.Documents {
+		name := documentName(doc)
+		record := toSnakeCase(doc.Model)
+		class := "models." + toPascalCase(doc.Model)
+		scoped := authed && modelBelongsToUser(doc.Model, app)
+
+		fmt.Fprintf(&b, "\n\ndef %s(%s) -> PdfContent:\n", name, record)
+		fmt.Fprintf(&b, "    \"\"\"The %s content for a%s %s.\"\"\"\n", doc.Kind, articleSuffix(doc.Model), doc.Model)
+		b.WriteString("    return PdfContent(\n")
+		fmt.Fprintf(&b, "        title='%s',\n", doc.Title())
+		if doc.Reference != "" {
+			fmt.Fprintf(&b, "        reference=f'%s {%s.%s or %s.id}',\n", doc.Model, record, toSnakeCase(doc.Reference), record)
+		} else {
+			fmt.Fprintf(&b, "        reference=f'%s {%s.id}',\n", doc.Model, record)
+		}
+		fmt.Fprintf(&b, "        date=%s.created_at or datetime.date.today(),\n", record)
+		b.WriteString("        details=[\n")
+		for _, f := range doc.Fields {
+			fmt.Fprintf(&b, "            ('%s', format_value(%s.%s)),\n", ir.FieldLabel(f), record, toSnakeCase(f))
+		}
+		b.WriteString("        ],\n")
+		if doc.Items != "" {
+			labels := make([]string, len(doc.ItemFields))
+			cells := make([]string, len(doc.ItemFields))
+			for i, f := range doc.ItemFields {
+				labels[i] = "'" + ir.FieldLabel(f) + "'"
+				cells[i] = "format_value(item." + toSnakeCase(f) + ")"
+			}
+			fmt.Fprintf(&b, "        columns=[%s],\n", strings.Join(labels, ", "))
+			fmt.Fprintf(&b, "        rows=[[%s] for item in %s.%ss],\n", strings.Join(cells, ", "), record, toSnakeCase(doc.Items))
+		}
+		if doc.Total != "" {
+			fmt.Fprintf(&b, "        total=('%s', format_value(%s.%s)),\n", ir.FieldLabel(doc.Total), record, toSnakeCase(doc.Total))
+		}
+		b.WriteString("    )\n")
+
+		if scoped {
+			fmt.Fprintf(&b, "\n\ndef render_%s(db: Session, record_id: str, user_id: Optional[str] = None) -> Optional[bytes]:\n", name)
+		} else {
+			fmt.Fprintf(&b, "\n\ndef render_%s(db: Session, record_id: str) -> Optional[bytes]:\n", name)
+		}
+		fmt.Fprintf(&b, "    \"\"\"Renders a%s %s's %s, or returns None if there is none matching.\"\"\"\n", articleSuffix(doc.Model), doc.Model, doc.Kind)
+		fmt.Fprintf(&b, "    query = db.query(%s).filter(%s.id == record_id)\n", class, class)
+		if scoped {
+			b.WriteString("    if user_id is not None:\n")
+			fmt.Fprintf(&b, "        query = query.filter(%s.user_id == user_id)\n", class)
+		}
+		fmt.Fprintf(&b, "    %s = query.first()\n", record)
+		fmt.Fprintf(&b, "    return render_pdf(%s(%s)) if %s else None\n", name, record, record)
+
+		if storage {
+			fmt.Fprintf(&b, "\n\ndef store_%s(db: Session, record_id: str) -> Optional[str]:\n", name)
+			fmt.Fprintf(&b, "    \"\"\"Renders a%s %s's %s and stores it as documents/%s/<id>.pdf.\"\"\"\n", articleSuffix(doc.Model), doc.Model, doc.Kind, doc.Slug)
+			fmt.Fprintf(&b, "    pdf = render_%s(db, record_id)\n", name)
+			b.WriteString("    if pdf is None:\n")
+			b.WriteString("        return None\n")
+			fmt.Fprintf(&b, "    key = f'documents/%s/{record_id}.pdf'\n", doc.Slug)
+			b.WriteString("    s3_client.put_object(Bucket=BUCKET, Key=key, Body=pdf, ContentType='application/pdf')\n")
+			b.WriteString("    return key\n")
+		}
+
+		fmt.Fprintf(&b, "\n\n@router.get('/documents/%s/{record_id}.pdf')\n", doc.Slug)
+		if authed {
+			fmt.Fprintf(&b, "def %s_pdf(record_id: str, db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):\n", name)
+		} else {
+			fmt.Fprintf(&b, "def %s_pdf(record_id: str, db: Session = Depends(get_db)):\n", name)
+		}
+		if scoped {
+			fmt.Fprintf(&b, "    pdf = render_%s(db, record_id, current_user.id)\n", name)
+		} else {
+			fmt.Fprintf(&b, "    pdf = render_%s(db, record_id)\n", name)
+		}
+		b.WriteString("    if pdf is None:\n")
+		fmt.Fprintf(&b, "        raise HTTPException(status_code=404, detail='%s not found')\n", doc.Model)
+		fmt.Fprintf(&b, "    return pdf_response(pdf, f'%s-{record_id}.pdf')\n", strings.ReplaceAll(doc.Kind, " ", "-"))
+	}
+
+	return b.String()
+}
+
+// articleSuffix returns "n" when a word takes "an": "a" + "n Order".
+func articleSuffix(word string) string {
+	if word != "" && strings.ContainsRune("AEIOUaeiou", rune(word[0])) {
+		return "n"
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// isJoinModel reports whether a model is the through table of a
+// has_many_through relation, emitted as an association table rather than
+// a class.
+func isJoinModel(app *ir.Application, model *ir.DataModel) bool {
+	for _, other := range app.Data {
+		for _, rel := range other.Relations {
+			if rel.Kind == "has_many_through" && rel.Through == model.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateFieldEncryption produces encryption.py: AES-256-GCM encryption
+// of the fields marked encrypted, as an EncryptedText column type. The
+// stored format is shared with the Node and Go backends.
+func generateFieldEncryption() string {
+	return `"""Field-level encryption at rest (AES-256-GCM).
+
+Values are stored as enc:v1:<key id>:<base64 of nonce, ciphertext, and tag>,
+so each value records which key wrote it.
+
+FIELD_ENCRYPTION_KEY is the key new values are written with: 32 random bytes,
+base64-encoded (openssl rand -base64 32). Load it from your secrets manager in
+production. FIELD_ENCRYPTION_OLD_KEYS lists retired keys, comma-separated,
+that can still be read during a rotation.
+
+Encrypted columns can't be used in filters: each encryption of a value
+differs.
+"""
+import base64
+import hashlib
+import os
+
+from cryptography.hazmat.primitives.ciphers.aead import AESGCM
+from sqlalchemy import Text
+from sqlalchemy.types import TypeDecorator
+
+PREFIX = "enc:v1:"
+
+_keys = None
+
+
+def _parse_key(encoded):
+    key = base64.b64decode(encoded.strip())
+    if len(key) != 32:
+        raise ValueError("Field encryption keys must be 32 bytes, base64-encoded (openssl rand -base64 32)")
+    return hashlib.sha256(key).hexdigest()[:8], key
+
+
+def _load_keys():
+    """Returns the current key followed by the old ones, as (id, key) pairs."""
+    global _keys
+    if _keys is None:
+        current = os.environ.get("FIELD_ENCRYPTION_KEY")
+        if not current:
+            raise RuntimeError("FIELD_ENCRYPTION_KEY is not set")
+        old = [k for k in os.environ.get("FIELD_ENCRYPTION_OLD_KEYS", "").split(",") if k.strip()]
+        _keys = [_parse_key(current)] + [_parse_key(k) for k in old]
+    return _keys
+
+
+def is_encrypted(value):
+    return isinstance(value, str) and value.startswith(PREFIX)
+
+
+def encrypt(plaintext):
+    key_id, key = _load_keys()[0]
+    nonce = os.urandom(12)
+    sealed = nonce + AESGCM(key).encrypt(nonce, plaintext.encode("utf-8"), None)
+    return PREFIX + key_id + ":" + base64.b64encode(sealed).decode("ascii")
+
+
+def decrypt(value):
+    """Decrypts a stored value. A value that isn't ciphertext is returned
+    unchanged: it was stored before the field was encrypted."""
+    if not is_encrypted(value):
+        return value
+    key_id, encoded = value[len(PREFIX):].split(":", 1)
+    key = next((k for i, k in _load_keys() if i == key_id), None)
+    if key is None:
+        raise RuntimeError(
+            f"No field encryption key with id {key_id} — add the retired key to FIELD_ENCRYPTION_OLD_KEYS")
+    sealed = base64.b64decode(encoded)
+    return AESGCM(key).decrypt(sealed[:12], sealed[12:], None).decode("utf-8")
+
+
+class EncryptedText(TypeDecorator):
+    """A text column encrypted on write and decrypted on read."""
+
+    impl = Text
+    cache_ok = True
+
+    def process_bind_param(self, value, dialect):
+        if value is None:
+            return None
+        return encrypt(value)
+
+    def process_result_value(self, value, dialect):
+        if value is None:
+            return None
+        return decrypt(value)
+`
+}
+
+// generateKeyRotation produces rotate_keys.py, which re-encrypts every
+// encrypted field with the current key. Values stored before the field was
+// encrypted are encrypted too.
+func generateKeyRotation(app *ir.Application) string {
+	var sb strings.Builder
+	sb.WriteString(`"""Re-encrypts encrypted fields with the current FIELD_ENCRYPTION_KEY.
+
+To rotate the key:
+  1. Add the current key to FIELD_ENCRYPTION_OLD_KEYS.
+  2. Set FIELD_ENCRYPTION_KEY to a new key (openssl rand -base64 32).
+  3. Deploy, then run: python rotate_keys.py
+  4. Remove the old key from FIELD_ENCRYPTION_OLD_KEYS.
+"""
+from sqlalchemy.orm.attributes import flag_modified
+
+import models
+from database import SessionLocal
+
+BATCH = 500
+
+ENCRYPTED_FIELDS = [
+`)
+	for _, m := range app.Data {
+		fields := m.EncryptedFields()
+		if len(fields) == 0 || isJoinModel(app, m) {
+			continue
+		}
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = fmt.Sprintf("%q", toSnakeCase(f.Name))
+		}
+		sb.WriteString(fmt.Sprintf("    (models.%s, [%s]),\n", toPascalCase(m.Name), strings.Join(names, ", ")))
+	}
+	sb.WriteString(`]
+
+
+def rotate(db, model, fields):
+    """Rewrites each row's encrypted fields, which EncryptedText encrypts
+    with the current key."""
+    count = 0
+    last_id = None
+    while True:
+        query = db.query(model).order_by(model.id)
+        if last_id is not None:
+            query = query.filter(model.id > last_id)
+        rows = query.limit(BATCH).all()
+        if not rows:
+            return count
+        for row in rows:
+            for field in fields:
+                flag_modified(row, field)
+        last_id = rows[-1].id
+        db.commit()
+        count += len(rows)
+
+
+def main():
+    db = SessionLocal()
+    try:
+        for model, fields in ENCRYPTED_FIELDS:
+            count = rotate(db, model, fields)
+            print(f"{model.__name__}: re-encrypted {count} records")
+    finally:
+        db.close()
+
+
+if __name__ == "__main__":
+    main()
+`)
+	return sb.String()
+}
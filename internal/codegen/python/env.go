@@ -0,0 +1,75 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateEnvCheck produces env_check.py. main.py and worker.py call
+// check_env() before importing anything that reads the environment, so a
+// missing or malformed variable stops them with a clear message.
+func generateEnvCheck(app *ir.Application) string {
+	var sb strings.Builder
+	sb.WriteString(`"""Startup check of the environment variables the app reads."""
+import base64
+import binascii
+import os
+import sys
+from urllib.parse import urlparse
+
+# (name, kind, required, required in production, development values)
+CHECKS = [
+`)
+	for _, r := range docker.EnvRules(app) {
+		var devValues []string
+		for _, v := range r.DevValues {
+			devValues = append(devValues, fmt.Sprintf("%q", v))
+		}
+		fmt.Fprintf(&sb, "    (%q, %q, %s, %s, [%s]),", r.Name, string(r.Kind), pyBool(r.Required), pyBool(r.RequiredInProduction), strings.Join(devValues, ", "))
+		if r.Comment != "" {
+			fmt.Fprintf(&sb, "  # %s", r.Comment)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("]\n")
+
+	fmt.Fprintf(&sb, `
+
+def _problem(name, kind, required, required_in_production, dev_values, production):
+    value = os.environ.get(name, "")
+    if value == "":
+        if required or (production and required_in_production):
+            return f"{name} is not set"
+        return None
+    if production and value in dev_values:
+        return f"{name} still has its development value — set your own in production"
+    if kind == "url":
+        parsed = urlparse(value)
+        if not parsed.scheme or not parsed.netloc:
+            return f"{name} must be a URL with a scheme and a host"
+    elif kind == "port":
+        if not value.isdigit() or not 1 <= int(value) <= 65535:
+            return f'{name} must be a port number (1-65535), got "{value}"'
+    elif kind == "key":
+        try:
+            key = base64.b64decode(value, validate=True)
+        except (binascii.Error, ValueError):
+            key = b""
+        if len(key) != 32:
+            return f"{name} must be 32 bytes, base64 — generate one with openssl rand -base64 32"
+    return None
+
+
+def check_env():
+    """Exits with every problem found when the environment is invalid."""
+    production = os.environ.get(%q) == "production"
+    problems = [p for p in (_problem(*check, production) for check in CHECKS) if p]
+    if problems:
+        lines = "\n".join(f"  - {p}" for p in problems)
+        sys.exit(f"Invalid environment:\n{lines}\nSee .env.example for the expected values.")
+`, docker.ProductionEnv(app))
+	return sb.String()
+}
@@ -0,0 +1,123 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// hasAnalyticsIntegration returns true if the app has an analytics integration.
+func hasAnalyticsIntegration(app *ir.Application) bool {
+	for _, integ := range app.Integrations {
+		if integ.Type == "analytics" {
+			return true
+		}
+	}
+	return false
+}
+
+// generateExperiments produces experiments.py: middleware that assigns a
+// sticky variant for every experiment, the endpoint the frontend reads
+// assignments from, and exposure logging to the analytics integration.
+func generateExperiments(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("import hashlib\n")
+	b.WriteString("import json\n")
+	b.WriteString("import logging\n")
+	b.WriteString("import re\n")
+	b.WriteString("import uuid\n\n")
+	b.WriteString("from fastapi import APIRouter, Request, Response\n")
+	b.WriteString("from fastapi.responses import JSONResponse\n")
+	if hasAnalyticsIntegration(app) {
+		b.WriteString("\nfrom services.analytics_service import track\n")
+	}
+
+	b.WriteString("\nEXPERIMENTS = [\n")
+	for _, exp := range app.Experiments {
+		var variants []string
+		for _, v := range exp.Variants {
+			variants = append(variants, fmt.Sprintf("(\"%s\", %d)", v.Page, v.Weight))
+		}
+		fmt.Fprintf(&b, "    {\"name\": \"%s\", \"audience\": \"%s\", \"variants\": [%s]},\n", exp.Name, exp.Audience, strings.Join(variants, ", "))
+	}
+	b.WriteString("]\n")
+
+	b.WriteString(`
+VISITOR_COOKIE = "hx_visitor"
+VISITOR_ID = re.compile(r"^[A-Za-z0-9_-]{8,64}$")
+ONE_YEAR = 365 * 24 * 60 * 60
+
+logger = logging.getLogger(__name__)
+
+
+def bucket(experiment: str, visitor_id: str) -> int:
+    """Map a visitor to 0-99 for an experiment. The hash is deterministic,
+    so a visitor always lands in the same variant."""
+    digest = hashlib.sha256(f"{experiment}:{visitor_id}".encode()).digest()
+    return int.from_bytes(digest[:4], "big") % 100
+
+
+def assign_variant(experiment: dict, visitor_id: str) -> str:
+    point = bucket(experiment["name"], visitor_id)
+    for name, weight in experiment["variants"]:
+        if point < weight:
+            return name
+        point -= weight
+    return experiment["variants"][0][0]
+
+
+async def assign_experiments(request: Request, call_next):
+    """Identify the visitor (X-Visitor-Id header, then the visitor cookie,
+    else a new id) and assign a variant for every experiment."""
+    cookie = request.cookies.get(VISITOR_COOKIE)
+    candidates = [request.headers.get("x-visitor-id"), cookie]
+    visitor_id = next((c for c in candidates if c and VISITOR_ID.match(c)), None) or str(uuid.uuid4())
+
+    request.state.visitor_id = visitor_id
+    request.state.experiments = {e["name"]: assign_variant(e, visitor_id) for e in EXPERIMENTS}
+
+    response = await call_next(request)
+    if cookie != visitor_id:
+        response.set_cookie(VISITOR_COOKIE, visitor_id, max_age=ONE_YEAR, httponly=True, samesite="lax")
+    return response
+
+
+async def log_exposure(visitor_id: str, experiment: str, variant: str) -> None:
+    """Record that a visitor saw their variant. Analytics failures are
+    logged and swallowed so they never break the page."""
+    properties = {"experiment": experiment, "variant": variant}
+`)
+	if hasAnalyticsIntegration(app) {
+		b.WriteString(`    try:
+        await track(visitor_id, "Experiment Viewed", properties)
+    except Exception:
+        logger.exception("Failed to log experiment exposure")
+`)
+	} else {
+		b.WriteString("    logger.info(json.dumps({\"event\": \"Experiment Viewed\", \"visitorId\": visitor_id, **properties}))\n")
+	}
+
+	b.WriteString(`
+
+router = APIRouter()
+
+
+@router.get("/experiments")
+def list_assignments(request: Request):
+    return {"data": {"visitorId": request.state.visitor_id, "assignments": request.state.experiments}}
+
+
+@router.post("/experiments/{name}/exposure", status_code=204)
+async def record_exposure(name: str, request: Request):
+    variant = request.state.experiments.get(name)
+    if variant is None:
+        return JSONResponse(status_code=404, content={"error": "Unknown experiment"})
+    await log_exposure(request.state.visitor_id, name, variant)
+    return Response(status_code=204)
+`)
+
+	return b.String()
+}
@@ -438,3 +438,1783 @@ func TestPythonOAuthRoutesGenerated(t *testing.T) {
 		t.Error("requirements.txt should include authlib for OAuth")
 	}
 }
+
+func TestPythonGrpcServerGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "TaskFlow",
+		Config: &ir.BuildConfig{Backend: "Python with FastAPI", APIStyle: "gRPC"},
+		Data: []*ir.DataModel{
+			{Name: "Task", Fields: []*ir.DataField{{Name: "title", Type: "text"}, {Name: "extra data", Type: "json"}}},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "CreateTask", Auth: true, Params: []*ir.Param{{Name: "title"}, {Name: "due date"}}},
+		},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := os.ReadFile(filepath.Join(dir, "grpc_server.py"))
+	if err != nil {
+		t.Fatal("missing grpc_server.py")
+	}
+	for _, want := range []string{
+		"from taskflow.v1 import taskflow_pb2 as pb",
+		`SERVICE = "taskflow.v1.TaskFlowService"`,
+		`"CreateTask": ("POST", "/api/task", [("title", "title"), ("due_date", "due_date")], False),`,
+		`JSON_FIELDS = {"extra_data"}`,
+	} {
+		if !strings.Contains(string(server), want) {
+			t.Errorf("grpc_server.py missing %q", want)
+		}
+	}
+
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if !strings.Contains(string(main), "grpc_server.serve(app)") {
+		t.Error("main.py should start the gRPC server on startup")
+	}
+	reqs, _ := os.ReadFile(filepath.Join(dir, "requirements.txt"))
+	if !strings.Contains(string(reqs), "grpcio==") || !strings.Contains(string(reqs), "httpx==") {
+		t.Error("requirements.txt should include grpcio and httpx")
+	}
+}
+
+func TestPythonExperimentsGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Shop",
+		Config: &ir.BuildConfig{Backend: "Python with FastAPI"},
+		Pages:  []*ir.Page{{Name: "Classic"}, {Name: "NewPricing"}},
+		Integrations: []*ir.Integration{
+			{Service: "Segment", Type: "analytics"},
+		},
+		Experiments: []*ir.Experiment{
+			{Name: "pricing-page", Audience: "visitors", Variants: []*ir.ExperimentVariant{{Page: "Classic", Weight: 50}, {Page: "NewPricing", Weight: 50}}},
+		},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	experiments, err := os.ReadFile(filepath.Join(dir, "experiments.py"))
+	if err != nil {
+		t.Fatal("missing experiments.py")
+	}
+	for _, want := range []string{
+		"from services.analytics_service import track",
+		`{"name": "pricing-page", "audience": "visitors", "variants": [("Classic", 50), ("NewPricing", 50)]},`,
+		`return int.from_bytes(digest[:4], "big") % 100`,
+		`await track(visitor_id, "Experiment Viewed", properties)`,
+	} {
+		if !strings.Contains(string(experiments), want) {
+			t.Errorf("experiments.py missing %q", want)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "services", "analytics_service.py")); err != nil {
+		t.Error("missing services/analytics_service.py")
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	for _, want := range []string{`app.middleware("http")(assign_experiments)`, `app.include_router(experiments_router, prefix="/api")`} {
+		if !strings.Contains(string(main), want) {
+			t.Errorf("main.py missing %q", want)
+		}
+	}
+	reqs, _ := os.ReadFile(filepath.Join(dir, "requirements.txt"))
+	if strings.Count(string(reqs), "httpx==") != 1 {
+		t.Error("requirements.txt should include httpx once for the analytics client")
+	}
+}
+
+func TestNotificationsGenerated(t *testing.T) {
+	source := `app TaskFlow is a web application
+
+data User:
+  has a name which is text
+
+data Task:
+  belongs to a User
+  has a title which is text
+
+api AssignTask:
+  requires authentication
+  accepts task_id and user_id
+  update the Task with the assignee
+  respond with the updated task
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+notify the user in the app when their task is assigned
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes.py"))
+	for _, want := range []string{"import notifications", "notifications.notify(db, payload.user_id, 'Your task was assigned')"} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.py missing %q", want)
+		}
+	}
+
+	notifications, err := os.ReadFile(filepath.Join(dir, "notifications.py"))
+	if err != nil {
+		t.Fatal("missing notifications.py")
+	}
+	for _, want := range []string{
+		`@router.get("/notifications/unread-count")`,
+		"models.Notification.seen.is_(None)",
+		`@router.post("/notifications/{notification_id}/read")`,
+	} {
+		if !strings.Contains(string(notifications), want) {
+			t.Errorf("notifications.py missing %q", want)
+		}
+	}
+
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if !strings.Contains(string(main), "from notifications import router as notifications_router") {
+		t.Error("main.py should include the notifications router")
+	}
+}
+
+func TestCalendarFeedsGenerated(t *testing.T) {
+	source := `app EventHub is a web application
+
+data Event:
+  has a name which is text
+  has a location which is text
+  has a starts_at which is datetime
+
+users can add an Event to their calendar in America/New_York
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	feeds, err := os.ReadFile(filepath.Join(dir, "calendar_feeds.py"))
+	if err != nil {
+		t.Fatal("missing calendar_feeds.py")
+	}
+	for _, want := range []string{
+		"@router.get('/calendar/events.ics')",
+		"@router.get('/calendar/events/{record_id}.ics')",
+		"models.Event.starts_at.isnot(None)",
+		"to_ics('Events', 'America/New_York',",
+		"f'event-{record.id}.ics'",
+	} {
+		if !strings.Contains(string(feeds), want) {
+			t.Errorf("calendar_feeds.py missing %q", want)
+		}
+	}
+
+	for _, rel := range []string{"calendar_ics.py", filepath.Join("tests", "test_calendar_ics.py")} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("missing %s", rel)
+		}
+	}
+
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if !strings.Contains(string(main), "from calendar_feeds import router as calendar_router") {
+		t.Error("main.py should include the calendar router")
+	}
+}
+
+func TestPDFDocumentsGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data User:
+  has a name which is text
+  has many Order
+
+data Order:
+  belongs to a User
+  has an order_number which is unique text
+  has a total which is decimal
+  has many OrderItem
+
+data OrderItem:
+  belongs to an Order
+  has a quantity which is number
+
+api CreateOrder:
+  requires authentication
+  accepts items
+  create an Order with the given fields
+  generate a PDF invoice from the Order
+  respond with the created order
+
+theme:
+  primary color is #E94560
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+integrate with AWS S3:
+  api key from environment variable AWS_ACCESS_KEY
+  use for storing invoices
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := os.ReadFile(filepath.Join(dir, "documents.py"))
+	if err != nil {
+		t.Fatal("missing documents.py")
+	}
+	for _, want := range []string{
+		"from reportlab.pdfgen import canvas",
+		"PRIMARY = HexColor('#e94560')",
+		"reference=f'Order {order.order_number or order.id}',",
+		"for item in order.order_items],",
+		"query = query.filter(models.Order.user_id == user_id)",
+		"key = f'documents/order-invoices/{record_id}.pdf'",
+		"@router.get('/documents/order-invoices/{record_id}.pdf')",
+		"pdf = render_order_invoice(db, record_id, current_user.id)",
+	} {
+		if !strings.Contains(string(docs), want) {
+			t.Errorf("documents.py missing %q", want)
+		}
+	}
+
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes.py"))
+	if !strings.Contains(string(routes), "documents.store_order_invoice(db, new_item.id)") {
+		t.Error("routes.py should store the invoice for the created order")
+	}
+
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if !strings.Contains(string(main), "from documents import router as documents_router") {
+		t.Error("main.py should include the documents router")
+	}
+
+	reqs, _ := os.ReadFile(filepath.Join(dir, "requirements.txt"))
+	if !strings.Contains(string(reqs), "reportlab==") {
+		t.Error("requirements.txt should include reportlab")
+	}
+}
+
+func TestSitemapGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data Product:
+  has a name which is text
+  has a price which is decimal
+
+page Home:
+  show a heading
+
+page ProductDetail:
+  show the product name
+
+environment production:
+  url is shop.example.com
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	sitemap, err := os.ReadFile(filepath.Join(dir, "sitemap.py"))
+	if err != nil {
+		t.Fatal("missing sitemap.py")
+	}
+	for _, want := range []string{
+		"PAGES = ['/', '/product-detail']",
+		"return (os.getenv('SITE_URL') or 'https://shop.example.com').rstrip('/')",
+		"def sitemap(request: Request, db: Session = Depends(get_db)):",
+		"for record in db.query(models.Product).limit(MAX_URLS).all():",
+		"url_entry(f'{origin}/product-detail?id={record.id}', record.updated_at)",
+		"@router.get('/robots.txt')",
+	} {
+		if !strings.Contains(string(sitemap), want) {
+			t.Errorf("sitemap.py missing %q", want)
+		}
+	}
+
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if !strings.Contains(string(main), "app.include_router(sitemap_router, prefix=\"/api\")") {
+		t.Error("main.py should include the sitemap router")
+	}
+}
+
+func TestFieldEncryptionGenerated(t *testing.T) {
+	source := `app Clinic is a web application
+
+data Patient:
+  has a name which is text
+  has a ssn which is encrypted text
+  has an optional notes which is encrypted text
+
+page Home:
+  show a list of patients
+
+api CreatePatient:
+  accepts name, ssn, and notes
+  create a Patient with the given fields
+  respond with the created patient
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "encryption.py")); err != nil {
+		t.Fatal("missing encryption.py")
+	}
+	models, _ := os.ReadFile(filepath.Join(dir, "models.py"))
+	for _, want := range []string{
+		"from encryption import EncryptedText",
+		"ssn = Column(EncryptedText, nullable=False,",
+		"notes = Column(EncryptedText, nullable=True,",
+	} {
+		if !strings.Contains(string(models), want) {
+			t.Errorf("models.py missing %q", want)
+		}
+	}
+
+	rotate, err := os.ReadFile(filepath.Join(dir, "rotate_keys.py"))
+	if err != nil {
+		t.Fatal("missing rotate_keys.py")
+	}
+	if !strings.Contains(string(rotate), `(models.Patient, ["ssn", "notes"]),`) {
+		t.Error("rotate_keys.py should rotate the patient's encrypted fields")
+	}
+
+	reqs, _ := os.ReadFile(filepath.Join(dir, "requirements.txt"))
+	if !strings.Contains(string(reqs), "cryptography==") {
+		t.Error("requirements.txt should include cryptography")
+	}
+}
+
+func TestReadReplicaAndPoolGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:     "TaskFlow",
+		Config:   &ir.BuildConfig{Backend: "Python with FastAPI", Database: "PostgreSQL"},
+		Database: &ir.DatabaseConfig{Engine: "PostgreSQL", ReadReplica: true, PoolSize: 20},
+	}
+
+	db := generateDatabase(app)
+	for _, want := range []string{
+		`SQLALCHEMY_REPLICA_URL = os.environ.get("DATABASE_REPLICA_URL") or SQLALCHEMY_DATABASE_URL`,
+		"POOL_SIZE = 20",
+		"engine = create_engine(SQLALCHEMY_DATABASE_URL, pool_size=POOL_SIZE, max_overflow=0, pool_pre_ping=True)",
+		"replica_engine = create_engine(SQLALCHEMY_REPLICA_URL, pool_size=POOL_SIZE",
+		"class RoutingSession(Session):",
+		"return engine if self.wrote else replica_engine",
+		"SessionLocal = sessionmaker(class_=RoutingSession, autocommit=False, autoflush=False)",
+		"def get_db():",
+	} {
+		if !strings.Contains(db, want) {
+			t.Errorf("database.py missing %q", want)
+		}
+	}
+
+	app.Database.ReadReplica = false
+	pooled := generateDatabase(app)
+	if strings.Contains(pooled, "replica") || !strings.Contains(pooled, "SessionLocal = sessionmaker(autocommit=False, autoflush=False, bind=engine)") {
+		t.Error("a pool size without a replica should bind sessions to the primary")
+	}
+}
+
+func TestPythonAuditLogGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Clinic",
+		Config: &ir.BuildConfig{Backend: "Python with FastAPI", Compliance: "HIPAA"},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	audit, err := os.ReadFile(filepath.Join(dir, "audit.py"))
+	if err != nil {
+		t.Fatal("missing audit.py")
+	}
+	if !strings.Contains(string(audit), "async def audit_log(request: Request, call_next):") {
+		t.Error("audit.py should define the audit_log middleware")
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if !strings.Contains(string(main), `app.middleware("http")(audit_log)`) {
+		t.Error("main.py should install the audit log middleware")
+	}
+}
+
+func TestPythonOpenAPIValidationGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Shop",
+		Config: &ir.BuildConfig{Backend: "Python with FastAPI", Validation: "the OpenAPI spec"},
+		APIs:   []*ir.Endpoint{{Name: "CreateOrder", Params: []*ir.Param{{Name: "quantity"}}}},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "openapi.yaml")); err != nil {
+		t.Fatal("missing openapi.yaml")
+	}
+	validation, err := os.ReadFile(filepath.Join(dir, "openapi_validation.py"))
+	if err != nil {
+		t.Fatal("missing openapi_validation.py")
+	}
+	if !strings.Contains(string(validation), "async def validate_request(request: Request, call_next):") {
+		t.Error("openapi_validation.py should define the validate_request middleware")
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if !strings.Contains(string(main), `app.middleware("http")(validate_request)`) {
+		t.Error("main.py should install the validation middleware")
+	}
+	reqs, _ := os.ReadFile(filepath.Join(dir, "requirements.txt"))
+	if !strings.Contains(string(reqs), "openapi-core==") {
+		t.Error("requirements.txt should include openapi-core")
+	}
+
+	app.Config.Validation = ""
+	dir = t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "openapi_validation.py")); err == nil {
+		t.Error("openapi_validation.py should only be generated when the build asks for validation")
+	}
+}
+
+func TestPythonMaskingGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Clinic",
+		Config: &ir.BuildConfig{Backend: "Python with FastAPI"},
+		Data: []*ir.DataModel{{Name: "Patient", Fields: []*ir.DataField{
+			{Name: "email", Type: "email", Personal: true},
+		}}},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	masking, err := os.ReadFile(filepath.Join(dir, "masking.py"))
+	if err != nil {
+		t.Fatal("missing masking.py")
+	}
+	for _, want := range []string{`PERSONAL_FIELDS = {"email"}`, "app.add_exception_handler(RequestValidationError, validation_error)"} {
+		if !strings.Contains(string(masking), want) {
+			t.Errorf("masking.py missing %q", want)
+		}
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if !strings.Contains(string(main), "install_masking(app)") {
+		t.Error("main.py should install masking")
+	}
+}
+
+func TestPythonJobsGenerated(t *testing.T) {
+	app := &ir.Application{
+		Name:   "TaskFlow",
+		Config: &ir.BuildConfig{Backend: "Python with FastAPI"},
+		Workflows: []*ir.Workflow{{Trigger: "a user signs up", Steps: []*ir.Action{
+			{Type: "send", Text: "send welcome email"},
+			{Type: "delay", Text: "after 3 days, send a tips email"},
+		}}},
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := os.ReadFile(filepath.Join(dir, "jobs.py"))
+	if err != nil {
+		t.Fatal("missing jobs.py")
+	}
+	for _, want := range []string{
+		"def on_user_signs_up(data: dict):",
+		`enqueue("user-signs-up-after-3-days", data, timedelta(seconds=259200))`,
+		`"user-signs-up-after-3-days": on_user_signs_up_after_3_days,`,
+	} {
+		if !strings.Contains(string(jobs), want) {
+			t.Errorf("jobs.py missing %q", want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "worker.py")); err != nil {
+		t.Error("missing worker.py")
+	}
+	reqs, _ := os.ReadFile(filepath.Join(dir, "requirements.txt"))
+	if !strings.Contains(string(reqs), "rq==") {
+		t.Error("requirements.txt should include rq")
+	}
+}
+
+func TestChartsGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data User:
+  has a name which is text
+  has many Expense
+
+data Project:
+  has a title which is text
+
+data Product:
+  has a category which is text
+  has a current_stock which is number
+
+data Expense:
+  belongs to a User
+  belongs to a Project
+  has an amount which is decimal
+  has a date which is date
+
+page Dashboard:
+  show a bar chart of stock levels by category
+  show a pie chart of total expenses by project
+  show a line chart of expenses over the last 30 days
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	charts, err := os.ReadFile(filepath.Join(dir, "charts.py"))
+	if err != nil {
+		t.Fatal("missing charts.py")
+	}
+	for _, want := range []string{
+		"@router.get('/charts/stock-levels-by-category')",
+		"value = func.coalesce(func.sum(models.Product.current_stock), 0)",
+		"query = query.outerjoin(models.Project, models.Expense.project_id == models.Project.id)",
+		"query = query.filter(models.Expense.user_id == current_user.id)",
+		"bucket = func.to_char(func.date_trunc('day', models.Expense.date), 'YYYY-MM-DD')",
+		"query = query.filter(models.Expense.date >= since(30))",
+	} {
+		if !strings.Contains(string(charts), want) {
+			t.Errorf("charts.py missing %q", want)
+		}
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if !strings.Contains(string(main), "app.include_router(charts_router, prefix=\"/api\")") {
+		t.Error("main.py should include the chart routes")
+	}
+}
+
+func TestReportEndpointsGenerated(t *testing.T) {
+	source := `app Shop is a web application
+
+data User:
+  has a name which is text
+  has many Order
+
+data Order:
+  belongs to a User
+  has an amount which is decimal
+  has a status which is text
+
+api MonthlyRevenue: sum the amount of Orders grouped by month for the last 12 months
+
+api GetOrdersByStatus:
+  requires authentication
+  count the Orders grouped by status
+  cache for 5 minutes
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes.py"))
+	for _, want := range []string{
+		"def monthly_revenue(response: Response, db: Session = Depends(get_db)):",
+		"    response.headers['Cache-Control'] = 'public, max-age=60'",
+		"    value = func.coalesce(func.sum(models.Order.amount), 0)",
+		"    query = query.filter(models.Order.created_at >= since(360))",
+		"    response.headers['Cache-Control'] = 'private, max-age=300'",
+		"    query = query.filter(models.Order.user_id == current_user.id)",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.py missing %q", want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "aggregates.py")); err != nil {
+		t.Error("missing aggregates.py")
+	}
+}
+
+func TestJoinModelFieldsOnAssociationTable(t *testing.T) {
+	app := &ir.Application{Data: []*ir.DataModel{
+		{Name: "Team", Relations: []*ir.Relation{{Kind: "has_many_through", Target: "User", Through: "TeamMember"}}},
+		{Name: "User", Relations: []*ir.Relation{{Kind: "has_many_through", Target: "Team", Through: "TeamMember"}}},
+		{Name: "TeamMember",
+			Fields:    []*ir.DataField{{Name: "role", Type: "text", Required: true}},
+			Relations: []*ir.Relation{{Kind: "belongs_to", Target: "Team"}, {Kind: "belongs_to", Target: "User"}},
+		},
+	}}
+
+	models := generateModels(app)
+	if !strings.Contains(models, "team_member = Table(") {
+		t.Fatalf("models.py should emit TeamMember as an association table:\n%s", models)
+	}
+	if !strings.Contains(models, "    Column('role', String),\n") {
+		t.Errorf("association table should keep the join model's fields:\n%s", models)
+	}
+}
+
+func TestReorderRoutesGenerated(t *testing.T) {
+	source := `app Board is a web application
+
+data User:
+  has a name which is text
+  has many Task
+
+data Task:
+  belongs to a User
+  has a title which is text
+
+page Home:
+  show a list of tasks
+  each task shows its title
+  dragging a task reorders the list
+
+api ListTasks:
+  requires authentication
+  fetch all tasks for the current user
+  respond with tasks
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	reorder, err := os.ReadFile(filepath.Join(dir, "reorder.py"))
+	if err != nil {
+		t.Fatal("missing reorder.py")
+	}
+	for _, want := range []string{
+		"@router.put('/reorder/tasks')",
+		"for position, record_id in enumerate(payload.ids):",
+		"query = query.filter(models.Task.user_id == current_user.id)",
+		"query.update({models.Task.position: position})",
+	} {
+		if !strings.Contains(string(reorder), want) {
+			t.Errorf("reorder.py missing %q", want)
+		}
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if !strings.Contains(string(main), "app.include_router(reorder_router, prefix=\"/api\")") {
+		t.Error("main.py should include the reorder routes")
+	}
+	routes, _ := os.ReadFile(filepath.Join(dir, "routes.py"))
+	if !strings.Contains(string(routes), "query = query.order_by(models.Task.position)") {
+		t.Error("ListTasks should list tasks in their dragged order")
+	}
+}
+
+func TestPaginatedListGenerated(t *testing.T) {
+	source := `app Feed is a web application
+
+data Post:
+  has a title which is text
+
+data Task:
+  has a title which is text
+
+page Home:
+  show a list of posts
+  each post shows its title
+  scrolling to bottom loads more posts
+
+page Board:
+  show a list of tasks
+  each task shows its title
+  dragging a task reorders the list
+
+api ListPosts:
+  fetch all posts
+  respond with posts
+
+api ListTasks:
+  fetch all tasks
+  paginate with 50 per page
+  respond with tasks
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := os.ReadFile(filepath.Join(dir, "routes.py"))
+	if err != nil {
+		t.Fatal("missing routes.py")
+	}
+	for _, want := range []string{
+		"from sqlalchemy import and_, asc, desc, or_, tuple_",
+		"def list_posts(cursor: Optional[str] = None, limit: int = Query(20, ge=1, le=100),",
+		"query = query.order_by(models.Post.created_at.desc(), models.Post.id.desc())",
+		"query = query.filter(tuple_(models.Post.created_at, models.Post.id) < (after.created_at, after.id))",
+		"limit: int = Query(50, ge=1, le=100)",
+		"query = query.order_by(models.Task.position.asc().nulls_last(), models.Task.id)",
+		"items = query.limit(limit + 1).all()",
+		"return {'data': items, 'pagination': {'limit': limit, 'nextCursor': next_cursor}}",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.py missing %q", want)
+		}
+	}
+}
+
+func TestPaginatedListQueryParams(t *testing.T) {
+	source := `app Tracker is a web application
+
+data Task:
+  has a title which is text
+  has a status which is either "todo" or "done"
+  has a done which is boolean
+
+api ListTasks:
+  fetch all tasks
+  support filtering by status and done
+  support searching by title
+  paginate with 20 per page
+  respond with tasks
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := os.ReadFile(filepath.Join(dir, "routes.py"))
+	if err != nil {
+		t.Fatal("missing routes.py")
+	}
+	for _, want := range []string{
+		"page: Optional[int] = Query(None, ge=1), sort: Optional[str] = None, order: str = 'asc', q: Optional[str] = None, status: Optional[str] = None, done: Optional[bool] = None,",
+		"query = query.filter(models.Task.status == status)",
+		"query = query.filter(or_(models.Task.title.ilike(f'%{q}%')))",
+		"sort_column = {'title': models.Task.title, 'status': models.Task.status, 'done': models.Task.done}.get(sort or '')",
+		"query = query.offset(((page or 1) - 1) * limit)",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.py missing %q", want)
+		}
+	}
+}
+
+func TestVersionedUpdate(t *testing.T) {
+	source := `app Blog is a web application
+
+data Post:
+  has a title which is text
+  has a published which is boolean
+  protect against conflicting edits
+
+api UpdatePost:
+  accepts post_id, title, and published
+  fetch the Post by post_id
+  if post does not exist, respond with "post not found"
+  update the post with the given fields
+  respond with the updated post
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := os.ReadFile(filepath.Join(dir, "routes.py"))
+	if err != nil {
+		t.Fatal("missing routes.py")
+	}
+	for _, want := range []string{
+		"if_match: Optional[str] = Header(None)",
+		"expected = concurrency.expected_version(if_match, payload.version)",
+		"raise HTTPException(status_code=428, detail='Send the version of the post you edited in an If-Match header')",
+		"return concurrency.conflict('This post was changed by someone else', schemas.PostResponse.model_validate(item))",
+		"except StaleDataError:",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.py missing %q", want)
+		}
+	}
+	models, _ := os.ReadFile(filepath.Join(dir, "models.py"))
+	if !strings.Contains(string(models), "__mapper_args__ = {'version_id_col': version}") {
+		t.Errorf("Post should count its versions:\n%s", models)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "concurrency.py")); err != nil {
+		t.Error("missing concurrency.py")
+	}
+}
+
+func TestImport(t *testing.T) {
+	source := `app Tracker is a web application
+
+data Task:
+  has a title which is text
+  has a status which is either "todo" or "done"
+  has an optional estimate which is number
+  has a done which is boolean
+
+api ImportTasks:
+  import tasks from a csv or json file
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := os.ReadFile(filepath.Join(dir, "routes.py"))
+	if err != nil {
+		t.Fatal("missing routes.py")
+	}
+	for _, want := range []string{
+		"import imports",
+		"async def import_tasks(request: Request, dry_run: bool = False",
+		"records = imports.parse_records(body, request.headers.get('content-type', ''))",
+		"status=record.one_of('status', ['todo', 'done'], True),",
+		"estimate=record.number('estimate', False),",
+		"done=record.boolean('done', False) or False,",
+		"return imports.rejected(",
+		"db.rollback()",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.py missing %q:\n%s", want, routes)
+		}
+	}
+	helpers, err := os.ReadFile(filepath.Join(dir, "imports.py"))
+	if err != nil {
+		t.Fatal("missing imports.py")
+	}
+	if !strings.Contains(string(helpers), "MAX_IMPORT_BYTES = 10485760") {
+		t.Error("imports.py should cap files at 10 MB")
+	}
+}
+
+func TestTimesInUTC(t *testing.T) {
+	source := `app Planner is a web application
+
+data Event:
+  has a title which is text
+  has a starts which is datetime
+  has an optional day which is date
+
+api CreateEvent:
+  accepts title, starts, and day
+  create an event with the given fields
+  respond with the created event
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL
+  store all times in UTC and display in the user's timezone`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]string{
+		"models.py":   {"starts = Column(DateTime(timezone=True), nullable=False", "created_at = Column(DateTime(timezone=True), server_default=func.now())"},
+		"schemas.py":  {"def as_utc(value: datetime.datetime) -> datetime.datetime:", "UTCDateTime = Annotated[datetime.datetime, AfterValidator(as_utc)]", "starts: UTCDateTime"},
+		"database.py": {`connect_args={"options": "-c timezone=utc"}`},
+	}
+	for name, wants := range files {
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("missing %s", name)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(src), want) {
+				t.Errorf("%s missing %q:\n%s", name, want, src)
+			}
+		}
+	}
+}
+
+func TestAccountEndpoints(t *testing.T) {
+	source := `app Notes is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+  has an optional bio which is text
+
+accounts:
+  users can update their profile, change password, and delete their account
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := os.ReadFile(filepath.Join(dir, "routes.py"))
+	if err != nil {
+		t.Fatal("missing routes.py")
+	}
+	for _, want := range []string{
+		"def profile_of(user):",
+		"    email: schemas.EmailStr\n",
+		"    bio: Optional[str] = None\n",
+		"    new_password: str = schemas.Field(min_length=8)\n",
+		"    return {'data': profile_of(current_user)}\n",
+		"raise HTTPException(status_code=409, detail='email is already taken')",
+		"    if not auth.verify_password(payload.current_password, current_user.password):\n",
+		"    current_user.password = auth.get_password_hash(payload.new_password)\n",
+		"    db.delete(current_user)\n",
+		"    return Response(status_code=status.HTTP_204_NO_CONTENT)\n",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.py missing %q:\n%s", want, routes)
+		}
+	}
+}
+
+func TestInvitationEndpoints(t *testing.T) {
+	source := `app Teams is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+  has a role which is text
+
+data Organization:
+  has a name which is text
+
+accounts:
+  users can invite teammates by email to their Organization
+  invitations expire in 3 days
+
+policy Admin:
+  can invite teammates
+
+policy Member:
+  can view tasks
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := os.ReadFile(filepath.Join(dir, "routes.py"))
+	if err != nil {
+		t.Fatal("missing routes.py")
+	}
+	for _, want := range []string{
+		"from authorize import authorize\n",
+		"def invite_teammate(payload: InviteTeammateRequest, db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user), _authz: Any = Depends(authorize('invite', 'teammate'))):\n",
+		"    if role not in ('Admin', 'Member'):\n",
+		"        token=secrets.token_hex(32),\n",
+		"timedelta(days=3)",
+		"    return JSONResponse(status_code=201, content=jsonable_encoder({'data': {**sent, 'link': link}}))\n",
+		"def get_invitation(token: str, db: Session = Depends(get_db)):\n",
+		"        raise HTTPException(status_code=410, detail='This invitation has expired')\n",
+		"    current_user.role = invitation.role\n",
+		"        membership = models.Membership(role=invitation.role, user_id=current_user.id, organization_id=invitation.organization_id)\n",
+	} {
+		if !strings.Contains(string(routes), want) {
+			t.Errorf("routes.py missing %q:\n%s", want, routes)
+		}
+	}
+	if strings.Contains(string(routes), "send_email") {
+		t.Error("no email integration: the inviter shares the link")
+	}
+}
+
+func TestBillingEndpoints(t *testing.T) {
+	source := `app Boards is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+
+data Project:
+  belongs to a User
+  has a name which is text
+
+plans: Free, Pro ($19/month), Enterprise
+
+policy FreePlan:
+  can create up to 3 projects per month
+  cannot export data
+
+api CreateProject:
+  requires authentication
+  accepts name
+  create a Project with the given fields
+  respond with the created project
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+integrate with Stripe:
+  api key from environment variable STRIPE_SECRET_KEY
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"billing.py": {
+			"stripe.api_key = os.environ.get('STRIPE_SECRET_KEY', '')",
+			"FREE_PLAN: Optional[str] = 'Free'",
+			"@router.post(\"/billing/checkout\")",
+			"\"mode\": \"subscription\",",
+			"        params[\"customer_email\"] = current_user.email\n",
+			"stripe.billing_portal.Session.create(",
+			"event = stripe.Webhook.construct_event(payload, request.headers.get(\"stripe-signature\"),",
+			"record = models.Subscription(stripe_subscription_id=subscription[\"id\"], user_id=user_id)",
+			"return base.rstrip('/') + '/pricing'",
+		},
+		"plans.py": {
+			"    ('Free', 'export', 'data'),\n",
+			"    ('Free', 'project', 3, 'month'),\n",
+			"    'project': models.Project,\n",
+			"raise HTTPException(status_code=402,",
+		},
+		"routes.py": {
+			"from plans import require_plan\n",
+			"_plan: Any = Depends(require_plan('create', 'project'))):\n",
+		},
+		"sync_plans.py": {
+			"    {'name': 'Pro', 'price': 1900, 'interval': 'month'},\n",
+			"currency='usd',",
+			"record.stripe_price_id = stripe_price_id",
+		},
+		"main.py": {
+			"from billing import router as billing_router\napp.include_router(billing_router, prefix=\"/api\")\n",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+}
+
+func TestUsageMetering(t *testing.T) {
+	source := `app Forecast is a web application
+
+data User:
+  has an email which is unique email
+  has a password which is text
+  has a role which is text
+
+api GetForecast:
+  requires authentication
+  respond with the forecast
+
+policy Member:
+  can make up to 1000 API calls per day
+
+authentication:
+  method JWT tokens that expire in 7 days
+
+meter API calls per user per day
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"usage.py": {
+			"METER_PERIOD = 'day'\n",
+			"    'Member': (1000, 'day'),\n",
+			"return QUOTAS.get(user.role) if user.role else None",
+			".update({models.UsageRecord.calls: models.UsageRecord.calls + 1}, synchronize_session=False)",
+			"status_code=429,",
+			"headers={\"Retry-After\":",
+			"@router.get('/usage/history')",
+		},
+		"routes.py": {
+			"from usage import meter\n",
+			"_usage: Any = Depends(meter)):\n",
+		},
+		"main.py": {
+			"from usage import router as usage_router\napp.include_router(usage_router, prefix=\"/api\")\n",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+	usage, _ := os.ReadFile(filepath.Join(dir, "usage.py"))
+	if strings.Contains(string(usage), "stripe") {
+		t.Error("an app that doesn't bill shouldn't report usage to Stripe")
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	source := `app Forecast is a web application
+
+data User:
+  has an email which is unique email
+  has a password which is text
+
+api GetForecast:
+  requires authentication
+  respond with the forecast
+
+authentication:
+  method JWT tokens that expire in 7 days
+  external clients authenticate with API keys
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"auth.py": {
+			"oauth2_scheme = OAuth2PasswordBearer(tokenUrl=\"api/login\", auto_error=False)\n",
+			"api_key_header = APIKeyHeader(name=\"X-API-Key\", auto_error=False)\n",
+			"models.ApiKey.key_hash == hash_api_key(api_key)",
+			"detail=\"This API key can only read\"",
+			"request.state.api_key_id = key.id",
+			"    if not token:\n        raise credentials_exception\n",
+		},
+		"api_keys.py": {
+			"@router.get('/keys')",
+			"@router.post('/keys', status_code=201)",
+			"@router.delete('/keys/{key_id}')",
+			"key = 'hk_' + secrets.token_urlsafe(24)",
+			"key_hash=auth.hash_api_key(key),",
+			"current_user: Any = Depends(signed_in)",
+		},
+		"main.py": {
+			"from api_keys import router as api_keys_router\napp.include_router(api_keys_router, prefix=\"/api\")\n",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+}
+
+func TestChaos(t *testing.T) {
+	source := `app Shop is a web application
+
+data Order:
+  has a total which is number
+
+api ListOrders:
+  fetch all orders
+  respond with orders
+
+if database is unreachable:
+  retry 3 times with 2 second delay
+  if still failing, respond with "service temporarily unavailable"
+  alert the engineering team via Slack
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"chaos.py": {
+			"ENABLED = os.getenv(\"HUMAN_CHAOS\", \"\") in (\"1\", \"true\")",
+			"\"condition\": \"database is unreachable\",",
+			"\"attempts\": 3,",
+			"\"delay_ms\": 2000,",
+			"\"status\": 503,",
+			"\"message\": \"Service temporarily unavailable\",",
+			"\"alert\": \"alert the engineering team via Slack\",",
+			"headers={\"X-Chaos\": fault[\"condition\"]},",
+		},
+		"main.py": {
+			// Inside recording and CORS, so its responses are recorded and
+			// carry CORS headers
+			"from chaos import chaos\napp.middleware(\"http\")(chaos)\n\nfrom record import record\napp.middleware(\"http\")(record)\n\napp.add_middleware(\n    CORSMiddleware,",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+
+	// Without error handlers there's nothing to inject
+	app.ErrorHandlers = nil
+	plain := t.TempDir()
+	if err := (Generator{}).Generate(app, plain); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(plain, "chaos.py")); err == nil {
+		t.Error("chaos middleware generated without error handlers")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	source := `app Shop is a web application
+
+data Order:
+  has a total which is number
+
+api ListOrders:
+  fetch all orders
+  respond with orders
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, wants := range map[string][]string{
+		"record.py": {
+			"DIR = os.getenv(\"HUMAN_RECORD_DIR\", \"\")",
+			"f\"# Response: {response.status_code} ({elapsed} ms)\",",
+			"lines.append(f\"{request.method} {{{{host}}}}{target}\")",
+			"request._receive = receive",
+		},
+		"main.py": {
+			"from record import record\napp.middleware(\"http\")(record)\n",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("missing %s", file)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+		}
+	}
+}
+
+func TestBuildHeader(t *testing.T) {
+	prog, err := parser.Parse(`app Shop is a web application
+
+data Order:
+  has a total which is number
+
+api ListOrders:
+  fetch all orders
+  respond with orders
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	// Only a build has provenance to send
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "main.py"))
+	if strings.Contains(string(content), "X-Human-Build") {
+		t.Errorf("main.py should not send X-Human-Build without a build")
+	}
+
+	app.Build = &ir.BuildInfo{Compiler: "0.4.0", IRHash: "sha256:abc", GeneratedAt: "2026-03-01T12:00:00Z"}
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	content, _ = os.ReadFile(filepath.Join(dir, "main.py"))
+	want := "    response.headers[\"X-Human-Build\"] = \"human/0.4.0; ir=sha256:abc; generated=2026-03-01T12:00:00Z\""
+	if !strings.Contains(string(content), want) {
+		t.Errorf("main.py missing %q:\n%s", want, content)
+	}
+}
+
+func TestModelHooks(t *testing.T) {
+	prog, err := parser.Parse(`app Tasks is a web application
+
+data User:
+  has an email which is unique email
+
+data Session:
+  belongs to a User
+  has a token which is text
+
+data Task:
+  belongs to a User
+  has a status which is text
+  has a priority which is number
+
+before a Task is created, set its status to "pending"
+before a Task is updated:
+  set its priority to 2
+  notify the owner
+after a User is deleted, remove their Sessions
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "models.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"from sqlalchemy import delete, event",
+		"@event.listens_for(Task, 'before_insert')\ndef task_before_insert(mapper, connection, target):\n    # before a Task is created\n    target.status = 'pending'\n",
+		"    target.priority = 2\n    # TODO: notify the owner\n",
+		"@event.listens_for(User, 'before_delete')",
+		"connection.execute(delete(Session.__table__).where(Session.__table__.c.user_id == target.id))",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("models.py missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestCORSOrigin(t *testing.T) {
+	app := &ir.Application{
+		Name:         "Shop",
+		Config:       &ir.BuildConfig{Backend: "Python with FastAPI"},
+		Environments: []*ir.Environment{{Name: "production", Config: map[string]string{"url": "shop example com"}}},
+	}
+	if main := generateMain(app); !strings.Contains(main, `allow_origins=["*"],`) {
+		t.Error("a local build should accept requests from any origin")
+	}
+	app.Config.Env = "production"
+	if main := generateMain(app); !strings.Contains(main, `allow_origins=["https://shop.example.com"],`) {
+		t.Errorf("a production build should only accept its frontend:\n%s", main)
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{
+			{Name: "User", Fields: []*ir.DataField{
+				{Name: "email", Type: "email", Required: true},
+				{Name: "password", Type: "text", Required: true},
+			}},
+			{Name: "Tag", Fields: []*ir.DataField{{Name: "label", Type: "text"}},
+				Relations: []*ir.Relation{{Kind: "has_many_through", Target: "User", Through: "UserTag"}}},
+			{Name: "UserTag", Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}, {Kind: "belongs_to", Target: "Tag"}}},
+			{Name: "Task", Fields: []*ir.DataField{
+				{Name: "title", Type: "text", Required: true},
+				{Name: "dueDate", Type: "date"},
+				{Name: "active", Type: "boolean"},
+			}, Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}}},
+		},
+		Seeds: []*ir.Seed{{Model: "User", Count: 2}, {Model: "UserTag", Count: 40}},
+	}
+
+	out := generateSeed(app)
+	for _, want := range []string{
+		"PASSWORD = auth.get_password_hash('password123')",
+		"{'id': '00000001-0000-4000-8000-000000000002', 'email': ",
+		"'password': PASSWORD}",
+		"'user_id': '00000001-0000-4000-8000-00000000000",
+		"'due_date': datetime.date(2025, ",
+		"db.merge(models.Task(**data))",
+		"db.execute(models.user_tag.delete().where(models.user_tag.c.tag_id == data['tag_id'], models.user_tag.c.user_id == data['user_id']))",
+		"db.execute(models.user_tag.insert().values(**data))",
+		"print(f'Seeded {len(TASKS)} tasks')",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("seed.py missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, "'active': True") && !strings.Contains(out, "'active': False") {
+		t.Errorf("booleans should be Python literals:\n%s", out)
+	}
+
+	// Association rows have no id, and link each pair once
+	start := strings.Index(out, "USER_TAGS = [")
+	rows := strings.Split(strings.TrimSpace(out[start:strings.Index(out[start:], "]\n")+start]), "\n")[1:]
+	seen := map[string]bool{}
+	for _, row := range rows {
+		if strings.Contains(row, "'id'") || seen[row] {
+			t.Errorf("unexpected association row %s", row)
+		}
+		seen[row] = true
+	}
+	if len(rows) == 0 || len(rows) > 50 {
+		t.Errorf("expected at most 2 users × 25 tags linked, got %d rows", len(rows))
+	}
+}
+
+func TestGenerateMigrations(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{
+			{Name: "Task", Fields: []*ir.DataField{
+				{Name: "title", Type: "text", Required: true},
+				{Name: "score", Type: "number"},
+			}},
+		},
+		Migrations: []*ir.Migration{
+			{Name: "0001_initial", Changes: []*ir.SchemaChange{{Kind: ir.CreateModel, Model: "Task", Data: &ir.DataModel{
+				Name: "Task", Fields: []*ir.DataField{{Name: "name", Type: "text", Required: true}},
+			}}}},
+			{Name: "0002_update_task", Changes: []*ir.SchemaChange{
+				{Kind: ir.RenameField, Model: "Task",
+					From:  &ir.DataField{Name: "name", Type: "text", Required: true},
+					Field: &ir.DataField{Name: "title", Type: "text", Required: true}},
+				{Kind: ir.AddField, Model: "Task", Field: &ir.DataField{Name: "score", Type: "number", Required: true}},
+			}},
+		},
+	}
+
+	files := generateMigrations(app)
+	initial := files["alembic/versions/initial.py"]
+	for _, want := range []string{
+		"revision: str = '000000000000'",
+		"down_revision: Union[str, None] = None",
+		"    op.create_table(\n        'task',\n        sa.Column('id', sa.String(), nullable=False),\n        sa.Column('name', sa.String(), nullable=False),",
+		"    op.drop_table('task')",
+	} {
+		if !strings.Contains(initial, want) {
+			t.Errorf("initial.py missing %q:\n%s", want, initial)
+		}
+	}
+
+	second := files["alembic/versions/0002_update_task.py"]
+	for _, want := range []string{
+		`"""update task`,
+		"revision: str = '000000000001'",
+		"down_revision: Union[str, None] = '000000000000'",
+		"    op.alter_column('task', 'name', new_column_name='title')",
+		"    op.add_column('task', sa.Column('score', sa.Integer(), nullable=False, server_default=sa.text(\"0\")))\n    op.alter_column('task', 'score', server_default=None)",
+		"def downgrade() -> None:\n    # Drop Task.score\n    op.drop_column('task', 'score')",
+	} {
+		if !strings.Contains(second, want) {
+			t.Errorf("0002_update_task.py missing %q:\n%s", want, second)
+		}
+	}
+
+	if out := generateMigrations(&ir.Application{})["alembic/versions/initial.py"]; !strings.Contains(out, "def upgrade() -> None:\n    pass") {
+		t.Errorf("expected an empty initial revision without data models:\n%s", out)
+	}
+}
+
+func TestEnvCheck(t *testing.T) {
+	app := &ir.Application{
+		Name:   "Shop",
+		Config: &ir.BuildConfig{Backend: "Python with FastAPI"},
+		Integrations: []*ir.Integration{
+			{Service: "SendGrid", Type: "email", Credentials: map[string]string{"api key": "SENDGRID_API_KEY"}},
+		},
+	}
+	check := generateEnvCheck(app)
+	for _, want := range []string{
+		`("DATABASE_URL", "url", True, False, []),`,
+		`("SENDGRID_API_KEY", "text", False, True, []),`,
+		`os.environ.get("APP_ENV") == "production"`,
+	} {
+		if !strings.Contains(check, want) {
+			t.Errorf("env_check.py missing %q", want)
+		}
+	}
+	if main := generateMain(app); !strings.HasPrefix(main, "from env_check import check_env\n\ncheck_env()\n") {
+		t.Error("main.py should check the environment before importing the routes")
+	}
+}
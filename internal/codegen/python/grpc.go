@@ -0,0 +1,145 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/grpc"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateGrpcServer produces grpc_server.py. Each RPC is dispatched to the
+// FastAPI app in-process, so it runs the same dependencies, validation, and
+// business steps as the REST route. Message classes come from the stubs
+// `buf generate` writes to gen/.
+func generateGrpcServer(app *ir.Application) string {
+	var sb strings.Builder
+	appName := app.Name
+	if appName == "" {
+		appName = "FastAPI App"
+	}
+	module := strings.TrimSuffix(strings.ReplaceAll(grpc.ProtoPath(app), "/", "."), ".proto") + "_pb2"
+	pkg, name := module[:strings.LastIndex(module, ".")], module[strings.LastIndex(module, ".")+1:]
+
+	sb.WriteString(fmt.Sprintf(`"""gRPC server for the %s API.
+
+Generated by Human compiler — do not edit. Run `+"`buf generate`"+` in ../grpc
+first to produce the message classes in gen/.
+"""
+import json
+import os
+import sys
+from concurrent import futures
+from pathlib import Path
+
+import grpc
+from fastapi.testclient import TestClient
+from google.protobuf import json_format
+
+sys.path.insert(0, str(Path(__file__).resolve().parent / "gen"))
+from %s import %s as pb  # noqa: E402
+
+SERVICE = "%s"
+GRPC_PORT = int(os.getenv("GRPC_PORT", "%d"))
+
+`, appName, pkg, name, grpc.FullServiceName(app), grpc.DefaultPort))
+
+	sb.WriteString("# rpc -> (method, path, [(request field, REST parameter)], JSON-encoded data)\n")
+	sb.WriteString("ROUTES = {\n")
+	for _, rpc := range grpc.RPCs(app) {
+		var params []string
+		for _, f := range rpc.Fields {
+			params = append(params, fmt.Sprintf("(%q, %q)", f.Name, toSnakeCase(f.Param)))
+		}
+		jsonData := "False"
+		if rpc.JSONData() {
+			jsonData = "True"
+		}
+		sb.WriteString(fmt.Sprintf("    %q: (%q, \"/api%s\", [%s], %s),\n",
+			rpc.Name, strings.ToUpper(httpMethod(rpc.Endpoint.Name)), routePath(rpc.Endpoint.Name), strings.Join(params, ", "), jsonData))
+	}
+	sb.WriteString("}\n\n")
+
+	var jsonFields []string
+	for _, f := range grpc.JSONFields(app) {
+		jsonFields = append(jsonFields, fmt.Sprintf("%q", toSnakeCase(f)))
+	}
+	sb.WriteString("# json model fields travel as JSON-encoded strings in proto messages.\n")
+	if len(jsonFields) == 0 {
+		sb.WriteString("JSON_FIELDS = set()\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("JSON_FIELDS = {%s}\n", strings.Join(jsonFields, ", ")))
+	}
+
+	sb.WriteString(`
+STATUS_CODES = {
+    400: grpc.StatusCode.INVALID_ARGUMENT,
+    401: grpc.StatusCode.UNAUTHENTICATED,
+    403: grpc.StatusCode.PERMISSION_DENIED,
+    404: grpc.StatusCode.NOT_FOUND,
+    409: grpc.StatusCode.ALREADY_EXISTS,
+    422: grpc.StatusCode.INVALID_ARGUMENT,
+    429: grpc.StatusCode.RESOURCE_EXHAUSTED,
+}
+
+
+def _encode_json_fields(value):
+    if isinstance(value, list):
+        return [_encode_json_fields(v) for v in value]
+    if isinstance(value, dict):
+        return {
+            k: json.dumps(v) if k in JSON_FIELDS and not isinstance(v, str) else _encode_json_fields(v)
+            for k, v in value.items()
+        }
+    return value
+
+
+def _handler(client, rpc):
+    method, path, params, json_data = ROUTES[rpc]
+    request_class = getattr(pb, rpc + "Request")
+    response_class = getattr(pb, rpc + "Response")
+
+    def handle(request, context):
+        body = {key: getattr(request, field) for field, key in params}
+        headers = {}
+        for key, value in context.invocation_metadata():
+            if key == "authorization":
+                headers["Authorization"] = value
+        res = client.request(method, path, json=body, headers=headers)
+        try:
+            payload = res.json()
+        except ValueError:
+            payload = {}
+        if res.status_code >= 400:
+            detail = payload.get("detail") or payload.get("error") or payload.get("message") or res.reason_phrase
+            context.abort(STATUS_CODES.get(res.status_code, grpc.StatusCode.INTERNAL), str(detail))
+        data = payload.get("data")
+        payload["data"] = json.dumps(data) if json_data else _encode_json_fields(data)
+        return json_format.ParseDict(payload, response_class(), ignore_unknown_fields=True)
+
+    return grpc.unary_unary_rpc_method_handler(
+        handle,
+        request_deserializer=request_class.FromString,
+        response_serializer=response_class.SerializeToString,
+    )
+
+
+def serve(app, port=GRPC_PORT):
+    """Start the gRPC server for app and return it (non-blocking)."""
+    client = TestClient(app, raise_server_exceptions=False)
+    handlers = {rpc: _handler(client, rpc) for rpc in ROUTES}
+    server = grpc.server(futures.ThreadPoolExecutor(max_workers=10))
+    server.add_generic_rpc_handlers((grpc.method_handlers_generic_handler(SERVICE, handlers),))
+    server.add_insecure_port(f"[::]:{port}")
+    server.start()
+    print(f"gRPC server running on port {port}")
+    return server
+
+
+if __name__ == "__main__":
+    from main import app
+
+    serve(app).wait_for_termination()
+`)
+	return sb.String()
+}
@@ -0,0 +1,90 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// hookEvents are the SQLAlchemy mapper events model hooks run on, by
+// event and timing.
+var hookEvents = []struct{ event, timing, mapperEvent string }{
+	{"create", "before", "before_insert"},
+	{"create", "after", "after_insert"},
+	{"update", "before", "before_update"},
+	{"update", "after", "after_update"},
+	{"delete", "before", "before_delete"},
+	{"delete", "after", "after_delete"},
+}
+
+// generateModelHooks produces the end of models.py: the models' lifecycle
+// hooks as SQLAlchemy mapper events, so they run on every flush of the
+// model whichever route writes it. Bulk query.update() and query.delete()
+// skip them.
+func generateModelHooks(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("\n# ── Lifecycle hooks ──\n")
+	for _, m := range app.Data {
+		if isJoinModel(app, m) {
+			continue
+		}
+		hooks := ir.HooksOn(app, m)
+		for _, he := range hookEvents {
+			var lines []string
+			for _, hook := range hooks {
+				if hook.Event == he.event {
+					lines = append(lines, hookLines(app, m, hook, he.timing)...)
+				}
+			}
+			if len(lines) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "\n\n@event.listens_for(%s, '%s')\n", toPascalCase(m.Name), he.mapperEvent)
+			fmt.Fprintf(&b, "def %s_%s(mapper, connection, target):\n", toSnakeCase(m.Name), he.mapperEvent)
+			for _, line := range lines {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// hookLines returns the lines of a hook on model m that run at timing. A
+// delete hook's removals always run before the delete, since the foreign
+// keys of the records removed would block it.
+func hookLines(app *ir.Application, m *ir.DataModel, hook *ir.ModelHook, timing string) []string {
+	var lines []string
+	for _, step := range hook.Steps {
+		s := ir.ResolveHookStep(app, hook, m, step)
+		switch {
+		case s != nil && s.Remove != nil:
+			if timing == "before" {
+				table := toPascalCase(s.Remove.Name) + ".__table__"
+				lines = append(lines, fmt.Sprintf("connection.execute(delete(%s).where(%s.c.%s_id == target.id))", table, table, toSnakeCase(m.Name)))
+			}
+		case hook.Timing != timing:
+		case s == nil:
+			lines = append(lines, "# TODO: "+step.Text)
+		default:
+			lines = append(lines, fmt.Sprintf("target.%s = %s", toSnakeCase(s.Field.Name), pythonHookValue(s)))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return append([]string{fmt.Sprintf("# %s a %s is %sd", hook.Timing, m.Name, hook.Event)}, lines...)
+}
+
+// pythonHookValue renders a set step's value as a Python literal.
+func pythonHookValue(s *ir.HookStep) string {
+	switch {
+	case s.Quoted():
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s.Value) + "'"
+	case s.Value == "true":
+		return "True"
+	case s.Value == "false":
+		return "False"
+	}
+	return s.Value
+}
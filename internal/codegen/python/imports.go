@@ -0,0 +1,285 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateImports produces imports.py: reading an imported file's records
+// and checking their fields.
+func generateImports() string {
+	return fmt.Sprintf(`import csv
+import datetime
+import io
+import json
+import re
+from typing import Any, Dict, List, Optional, Sequence
+
+from fastapi.responses import JSONResponse
+
+MAX_IMPORT_BYTES = %d
+
+
+class ImportFileError(Exception):
+    """A file that can't be read as records at all."""
+
+
+def column_key(name: str) -> str:
+    """Normalizes a column name, so "Due date", "due_date", and "dueDate"
+    name the same field."""
+    return re.sub(r'[^a-z0-9]', '', name.lower())
+
+
+def parse_records(body: bytes, content_type: str = '') -> List[Dict[str, str]]:
+    """Reads an imported file's records, each value as text: a JSON array
+    of objects, or CSV with a header row."""
+    try:
+        text = body.decode('utf-8-sig')
+    except UnicodeDecodeError:
+        raise ImportFileError('The file is not UTF-8 text')
+    if 'json' in content_type or text.lstrip().startswith('['):
+        try:
+            parsed = json.loads(text)
+        except ValueError:
+            raise ImportFileError('The file is not valid JSON')
+        if not isinstance(parsed, list):
+            raise ImportFileError('The file should hold a JSON array of records')
+        records = []
+        for record in parsed:
+            values = {}
+            if isinstance(record, dict):
+                for key, value in record.items():
+                    if value is None:
+                        continue
+                    if isinstance(value, bool):
+                        value = 'true' if value else 'false'
+                    elif isinstance(value, (dict, list)):
+                        value = json.dumps(value)
+                    values[column_key(key)] = str(value)
+            records.append(values)
+        return records
+    try:
+        rows = [row for row in csv.reader(io.StringIO(text)) if any(cell.strip() for cell in row)]
+    except csv.Error:
+        raise ImportFileError('The file is not valid CSV')
+    if not rows:
+        return []
+    keys = [column_key(cell) for cell in rows[0]]
+    return [dict(zip(keys, row)) for row in rows[1:]]
+
+
+def rejected(message: str, summary: Dict[str, Any]) -> JSONResponse:
+    """422 for an all-or-nothing import that saved nothing, with its summary."""
+    return JSONResponse(status_code=422, content={'error': message, 'data': summary})
+
+
+class ImportRecord:
+    """Reads the fields of one imported record, noting each problem. Records
+    are numbered from 1, not counting a CSV file's header line. A field with
+    a problem reads as None."""
+
+    def __init__(self, values: Dict[str, str], row: int):
+        self.values = values
+        self.row = row
+        self.errors: List[Dict[str, Any]] = []
+
+    def _value(self, field: str, required: bool) -> Optional[str]:
+        value = (self.values.get(column_key(field)) or '').strip()
+        if value:
+            return value
+        if required:
+            self._problem(field, f'{field} is required')
+        return None
+
+    def _problem(self, field: str, message: str) -> None:
+        self.errors.append({'row': self.row, 'field': field, 'message': message})
+
+    def text(self, field: str, required: bool) -> Optional[str]:
+        return self._value(field, required)
+
+    def email(self, field: str, required: bool) -> Optional[str]:
+        value = self._value(field, required)
+        if value is not None and not re.fullmatch(r'[^\s@]+@[^\s@]+\.[^\s@]+', value):
+            return self._problem(field, f'{field} must be an email address')
+        return value
+
+    def url(self, field: str, required: bool) -> Optional[str]:
+        value = self._value(field, required)
+        if value is not None and not re.match(r'https?://[^\s/]+', value):
+            return self._problem(field, f'{field} must be a URL')
+        return value
+
+    def number(self, field: str, required: bool) -> Optional[int]:
+        value = self._value(field, required)
+        if value is None:
+            return None
+        try:
+            return int(value)
+        except ValueError:
+            return self._problem(field, f'{field} must be a whole number')
+
+    def decimal(self, field: str, required: bool) -> Optional[float]:
+        value = self._value(field, required)
+        if value is None:
+            return None
+        try:
+            return float(value)
+        except ValueError:
+            return self._problem(field, f'{field} must be a number')
+
+    def boolean(self, field: str, required: bool) -> Optional[bool]:
+        value = self._value(field, required)
+        if value is None:
+            return None
+        if value.lower() in ('true', 'yes', '1'):
+            return True
+        if value.lower() in ('false', 'no', '0'):
+            return False
+        return self._problem(field, f'{field} must be true or false')
+
+    def date(self, field: str, required: bool) -> Optional[datetime.date]:
+        value = self._value(field, required)
+        if value is None:
+            return None
+        try:
+            return datetime.date.fromisoformat(value[:10])
+        except ValueError:
+            return self._problem(field, f'{field} must be a date, e.g. 2024-01-31')
+
+    def date_time(self, field: str, required: bool) -> Optional[datetime.datetime]:
+        value = self._value(field, required)
+        if value is None:
+            return None
+        try:
+            return datetime.datetime.fromisoformat(value.replace('Z', '+00:00'))
+        except ValueError:
+            return self._problem(field, f'{field} must be a date and time, e.g. 2024-01-31T09:30:00Z')
+
+    def one_of(self, field: str, values: Sequence[str], required: bool) -> Optional[str]:
+        value = self._value(field, required)
+        if value is None:
+            return None
+        for allowed in values:
+            if allowed.lower() == value.lower():
+                return allowed
+        return self._problem(field, f'{field} must be one of {", ".join(values)}')
+
+    def json_value(self, field: str, required: bool) -> Any:
+        value = self._value(field, required)
+        if value is None:
+            return None
+        try:
+            return json.loads(value)
+        except ValueError:
+            return self._problem(field, f'{field} must be JSON')
+`, ir.MaxImportBytes)
+}
+
+// writeImportRoute writes the body of an import route. Each record of the
+// file is checked against the model's fields; records with problems are
+// reported by row and field. An all-or-nothing import saves nothing unless
+// every record is valid, and saves them in one transaction; one that
+// continues on error saves each valid record it can, each in a savepoint.
+// ?dry_run=true only checks the file.
+func writeImportRoute(sb *strings.Builder, api *ir.Endpoint, app *ir.Application) {
+	im := api.Import
+	var m *ir.DataModel
+	for _, dm := range app.Data {
+		if dm.Name == im.Model {
+			m = dm
+		}
+	}
+	scoped := api.Auth && modelBelongsToUser(m.Name, app) && !strings.EqualFold(m.Name, "User")
+
+	sb.WriteString("    body = await request.body()\n")
+	sb.WriteString("    if len(body) > imports.MAX_IMPORT_BYTES:\n")
+	fmt.Fprintf(sb, "        raise HTTPException(status_code=413, detail='The file is larger than %d MB')\n", ir.MaxImportBytes>>20)
+	sb.WriteString("    try:\n")
+	sb.WriteString("        records = imports.parse_records(body, request.headers.get('content-type', ''))\n")
+	sb.WriteString("    except imports.ImportFileError as err:\n")
+	sb.WriteString("        raise HTTPException(status_code=400, detail=str(err))\n\n")
+	fmt.Fprintf(sb, "    summary = {'mode': '%s', 'dryRun': dry_run, 'total': len(records), 'imported': 0, 'failed': 0, 'errors': []}\n", im.Mode)
+	sb.WriteString("    valid = []\n")
+	sb.WriteString("    for i, values in enumerate(records):\n")
+	sb.WriteString("        record = imports.ImportRecord(values, i + 1)\n")
+	sb.WriteString("        data = dict(\n")
+	for _, col := range ir.ImportColumns(m, api.Auth) {
+		if col.Field == nil {
+			fmt.Fprintf(sb, "            %s_id=record.text('%s', True),\n", toSnakeCase(col.Relation), col.Name)
+			continue
+		}
+		fmt.Fprintf(sb, "            %s=%s,\n", toSnakeCase(col.Field.Name), importReader(col))
+	}
+	if scoped {
+		sb.WriteString("            user_id=current_user.id,\n")
+	}
+	sb.WriteString("        )\n")
+	sb.WriteString("        if record.errors:\n")
+	sb.WriteString("            summary['failed'] += 1\n")
+	sb.WriteString("            summary['errors'].extend(record.errors)\n")
+	sb.WriteString("        else:\n")
+	sb.WriteString("            valid.append((record.row, data))\n\n")
+
+	if im.Mode == ir.ImportContinueOnError {
+		sb.WriteString("    if not dry_run:\n")
+		sb.WriteString("        for row, data in valid:\n")
+		sb.WriteString("            try:\n")
+		sb.WriteString("                with db.begin_nested():\n")
+		fmt.Fprintf(sb, "                    db.add(models.%s(**data))\n", m.Name)
+		sb.WriteString("                summary['imported'] += 1\n")
+		sb.WriteString("            except Exception:\n")
+		sb.WriteString("                summary['failed'] += 1\n")
+		sb.WriteString("                summary['errors'].append({'row': row, 'message': 'This record could not be saved'})\n")
+		sb.WriteString("        db.commit()\n")
+		sb.WriteString("        summary['errors'].sort(key=lambda e: e['row'])\n")
+		sb.WriteString("    return {'data': summary}\n\n")
+		return
+	}
+
+	sb.WriteString("    if dry_run:\n")
+	sb.WriteString("        return {'data': summary}\n")
+	sb.WriteString("    if summary['failed'] > 0:\n")
+	sb.WriteString("        return imports.rejected(f\"Nothing was imported: {summary['failed']} of {summary['total']} records have problems\", summary)\n")
+	sb.WriteString("    row = 0\n")
+	sb.WriteString("    try:\n")
+	sb.WriteString("        for row, data in valid:\n")
+	fmt.Fprintf(sb, "            db.add(models.%s(**data))\n", m.Name)
+	sb.WriteString("            db.flush()\n")
+	sb.WriteString("        db.commit()\n")
+	sb.WriteString("    except Exception:\n")
+	sb.WriteString("        db.rollback()\n")
+	sb.WriteString("        summary['failed'] = 1\n")
+	sb.WriteString("        summary['errors'].append({'row': row, 'message': 'This record could not be saved'})\n")
+	sb.WriteString("        return imports.rejected(f'Nothing was imported: record {row} could not be saved', summary)\n")
+	sb.WriteString("    summary['imported'] = len(valid)\n")
+	sb.WriteString("    return {'data': summary}\n\n")
+}
+
+// importReader returns the ImportRecord call reading a column's field.
+func importReader(col *ir.ImportColumn) string {
+	f := col.Field
+	required := "False"
+	if f.Required {
+		required = "True"
+	}
+	switch f.Type {
+	case "boolean":
+		// A blank boolean is false, as an unchecked box would be
+		return fmt.Sprintf("record.boolean('%s', False) or False", col.Name)
+	case "email", "url", "number", "decimal", "date":
+		return fmt.Sprintf("record.%s('%s', %s)", f.Type, col.Name, required)
+	case "datetime":
+		return fmt.Sprintf("record.date_time('%s', %s)", col.Name, required)
+	case "json":
+		return fmt.Sprintf("record.json_value('%s', %s)", col.Name, required)
+	case "enum":
+		values := make([]string, len(f.EnumValues))
+		for i, v := range f.EnumValues {
+			values[i] = "'" + v + "'"
+		}
+		return fmt.Sprintf("record.one_of('%s', [%s], %s)", col.Name, strings.Join(values, ", "), required)
+	}
+	return fmt.Sprintf("record.text('%s', %s)", col.Name, required)
+}
@@ -34,9 +34,15 @@ func generateIntegrations(app *ir.Application) map[string]string {
 		case "messaging":
 			filename = "slack_service.py"
 			content = generateMessagingService(integ)
+		case "sms":
+			filename = "sms_service.py"
+			content = generateSMSService(integ, app)
 		case "oauth":
 			filename = "oauth_service.py"
 			content = generateOAuthService(integ)
+		case "analytics":
+			filename = "analytics_service.py"
+			content = generateAnalyticsService(integ)
 		default:
 			filename = toSnakeCase(integ.Service) + "_service.py"
 			content = generateGenericService(integ)
@@ -55,6 +61,14 @@ func generateIntegrations(app *ir.Application) map[string]string {
 	// Generate __init__.py
 	files["services/__init__.py"] = generateServiceInit(files)
 
+	// The SMS service ships with tests against its sandbox outbox.
+	for _, integ := range app.Integrations {
+		if integ.Type == "sms" {
+			files["tests/test_sms_service.py"] = generateSMSTests()
+			break
+		}
+	}
+
 	return files
 }
 
@@ -303,6 +317,85 @@ func generateOAuthService(integ *ir.Integration) string {
 	return b.String()
 }
 
+// generateAnalyticsService produces a Python service that sends server-side
+// events to the analytics provider's HTTP API.
+func generateAnalyticsService(integ *ir.Integration) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	fmt.Fprintf(&b, "# Integration: %s (analytics)\n\n", integ.Service)
+
+	provider := analyticsProvider(integ.Service)
+
+	keyEnv := map[string]string{
+		"posthog":   "POSTHOG_API_KEY",
+		"segment":   "SEGMENT_WRITE_KEY",
+		"mixpanel":  "MIXPANEL_TOKEN",
+		"amplitude": "AMPLITUDE_API_KEY",
+	}[provider]
+	for _, envVar := range integ.Credentials {
+		keyEnv = envVar
+		break
+	}
+
+	b.WriteString("import os\n")
+	b.WriteString("from typing import Optional\n\n")
+	b.WriteString("import httpx\n\n")
+	fmt.Fprintf(&b, "API_KEY = os.environ.get('%s', '')\n", keyEnv)
+	if provider == "posthog" {
+		b.WriteString("HOST = os.environ.get('POSTHOG_HOST', 'https://us.i.posthog.com')\n")
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString("async def track(distinct_id: str, event: str, properties: Optional[dict] = None) -> None:\n")
+	b.WriteString("    if not API_KEY:\n")
+	b.WriteString("        return\n")
+	b.WriteString("    properties = properties or {}\n")
+	b.WriteString("    async with httpx.AsyncClient(timeout=5) as client:\n")
+	switch provider {
+	case "posthog":
+		b.WriteString("        res = await client.post(\n")
+		b.WriteString("            f'{HOST}/capture/',\n")
+		b.WriteString("            json={'api_key': API_KEY, 'event': event, 'distinct_id': distinct_id, 'properties': properties},\n")
+		b.WriteString("        )\n")
+	case "segment":
+		b.WriteString("        res = await client.post(\n")
+		b.WriteString("            'https://api.segment.io/v1/track',\n")
+		b.WriteString("            auth=(API_KEY, ''),\n")
+		b.WriteString("            json={'anonymousId': distinct_id, 'event': event, 'properties': properties},\n")
+		b.WriteString("        )\n")
+	case "mixpanel":
+		b.WriteString("        res = await client.post(\n")
+		b.WriteString("            'https://api.mixpanel.com/track',\n")
+		b.WriteString("            json=[{'event': event, 'properties': {**properties, 'token': API_KEY, 'distinct_id': distinct_id}}],\n")
+		b.WriteString("        )\n")
+	default: // amplitude
+		b.WriteString("        res = await client.post(\n")
+		b.WriteString("            'https://api2.amplitude.com/2/httpapi',\n")
+		b.WriteString("            json={'api_key': API_KEY, 'events': [{'device_id': distinct_id, 'event_type': event, 'event_properties': properties}]},\n")
+		b.WriteString("        )\n")
+	}
+	b.WriteString("        res.raise_for_status()\n")
+
+	return b.String()
+}
+
+// analyticsProvider maps an analytics integration's service name to the
+// provider whose HTTP API the generated service calls.
+func analyticsProvider(service string) string {
+	s := strings.ToLower(service)
+	switch {
+	case strings.Contains(s, "posthog"):
+		return "posthog"
+	case strings.Contains(s, "segment"):
+		return "segment"
+	case strings.Contains(s, "mixpanel"):
+		return "mixpanel"
+	default:
+		return "amplitude"
+	}
+}
+
 // generateGenericService produces a minimal Python service for unknown integrations.
 func generateGenericService(integ *ir.Integration) string {
 	var b strings.Builder
@@ -100,3 +100,50 @@ func TestRequirementsWithIntegrations(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateSMSServicePython(t *testing.T) {
+	app := &ir.Application{
+		Integrations: []*ir.Integration{
+			{Service: "Twilio", Type: "sms", Credentials: map[string]string{"account sid": "TWILIO_SID", "auth token": "TWILIO_TOKEN"}},
+		},
+		ErrorHandlers: []*ir.ErrorHandler{
+			{Condition: "sending an SMS fails", Steps: []*ir.Action{{Type: "retry", Text: "retry 4 times with 2 second delay"}}},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "ShipOrder", Params: []*ir.Param{{Name: "phone"}}, Steps: []*ir.Action{
+				{Type: "send", Text: "send an SMS Your order has shipped"},
+			}},
+		},
+	}
+
+	files := generateIntegrations(app)
+	content, ok := files["services/sms_service.py"]
+	if !ok {
+		t.Fatal("Twilio should generate services/sms_service.py")
+	}
+	for _, check := range []string{
+		"Client(os.environ.get('TWILIO_SID', ''), os.environ.get('TWILIO_TOKEN', ''))",
+		"ATTEMPTS = 4",
+		"RETRY_DELAY_SECONDS = 2",
+		"outbox.append(message)",
+		"def send_whatsapp(to: str, body: str) -> None:",
+	} {
+		if !strings.Contains(content, check) {
+			t.Errorf("sms service missing %q", check)
+		}
+	}
+	if _, ok := files["tests/test_sms_service.py"]; !ok {
+		t.Error("missing sandbox tests for the SMS service")
+	}
+	if strings.Contains(files["services/__init__.py"], "test_sms_service") {
+		t.Error("services/__init__.py should not import the tests")
+	}
+
+	routes := generateRoutes(app)
+	if !strings.Contains(routes, "send_sms(payload.phone, 'Your order has shipped')") {
+		t.Error("ShipOrder should text the phone param")
+	}
+	if !strings.Contains(generateRequirements(app), "twilio==") {
+		t.Error("requirements.txt should include twilio")
+	}
+}
@@ -0,0 +1,85 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateJobs produces jobs.py: the workflow jobs, which run on the
+// worker with RQ, and enqueue() to queue them in Redis.
+func generateJobs(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString(`"""Background jobs — workflows run on the worker (worker.py), not in the
+request that triggers them. Queue one where its trigger happens:
+
+`)
+	for _, wf := range app.Workflows {
+		fmt.Fprintf(&b, "    enqueue(\"%s\", data)  # when %s\n", ir.JobName(wf.Trigger), wf.Trigger)
+	}
+	b.WriteString(`"""
+import os
+from datetime import timedelta
+
+from redis import Redis
+from rq import Queue, Retry
+
+redis = Redis.from_url(os.getenv("REDIS_URL", "redis://localhost:6379"))
+queue = Queue("jobs", connection=redis)
+
+
+def enqueue(name: str, data: dict | None = None, delay: timedelta | None = None):
+    """Queues the job called name for the worker, delay from now."""
+    retry = Retry(max=3, interval=[1, 10, 60])
+    if delay:
+        return queue.enqueue_in(delay, JOBS[name], data or {}, retry=retry)
+    return queue.enqueue(JOBS[name], data or {}, retry=retry)
+`)
+	var table []string
+	for _, wf := range app.Workflows {
+		jobs := ir.WorkflowJobs(wf)
+		for i, job := range jobs {
+			fn := "on_" + strings.ReplaceAll(job.Name, "-", "_")
+			table = append(table, fmt.Sprintf("    %q: %s,\n", job.Name, fn))
+			fmt.Fprintf(&b, "\n\ndef %s(data: dict):\n", fn)
+			if job.Delay == 0 {
+				fmt.Fprintf(&b, "    \"\"\"When %s.\"\"\"\n", job.Trigger)
+			} else {
+				fmt.Fprintf(&b, "    \"\"\"%s after %s.\"\"\"\n", job.After, job.Trigger)
+			}
+			for _, step := range job.Steps {
+				fmt.Fprintf(&b, "    # TODO: %s\n", step.Text)
+			}
+			if i == 0 {
+				for _, later := range jobs[1:] {
+					fmt.Fprintf(&b, "    enqueue(%q, data, timedelta(seconds=%d))\n", later.Name, int64(later.Delay.Seconds()))
+				}
+			}
+			if i > 0 || len(jobs) == 1 {
+				b.WriteString("    pass\n")
+			}
+		}
+	}
+	b.WriteString("\n\nJOBS = {\n")
+	b.WriteString(strings.Join(table, ""))
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// generateWorker produces worker.py, the entry point of the worker service.
+// Its scheduler moves delayed jobs onto the queue when they're due.
+func generateWorker() string {
+	return `"""Runs the jobs in jobs.py: python worker.py"""
+from env_check import check_env
+
+check_env()
+
+from rq import Worker
+
+from jobs import queue, redis
+
+if __name__ == "__main__":
+    Worker([queue], connection=redis).work(with_scheduler=True)
+`
+}
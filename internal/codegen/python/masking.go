@@ -0,0 +1,128 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateMasking produces masking.py, which keeps fields marked personal
+// out of logs and error responses. Field names are compared without case
+// or separators, so "birthDate", "birth_date", and "birth date" all match.
+func generateMasking(app *ir.Application) string {
+	var names []string
+	for _, name := range ir.PersonalFieldNames(app) {
+		names = append(names, fmt.Sprintf("%q", strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(name))))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`"""Personal data masking — fields marked personal in the .human file are
+replaced with [REDACTED] in log records, including uvicorn's access log,
+and in error responses.
+"""
+import logging
+import re
+
+from fastapi import FastAPI, Request
+from fastapi.encoders import jsonable_encoder
+from fastapi.exceptions import RequestValidationError
+from fastapi.responses import JSONResponse
+from starlette.exceptions import HTTPException as StarletteHTTPException
+
+`)
+	fmt.Fprintf(&sb, "PERSONAL_FIELDS = {%s}\n", strings.Join(names, ", "))
+	sb.WriteString(`REDACTED = "[REDACTED]"
+
+# key: value and key=value pairs in log lines and error messages
+PAIR_PATTERN = re.compile(r"""(["']?)([A-Za-z][\w-]*)\1(\s*[:=]\s*)("[^"]*"|'[^']*'|[^\s,&;}\]]+)""")
+
+
+def is_personal(key) -> bool:
+    return isinstance(key, str) and re.sub(r"[\s_-]", "", key.lower()) in PERSONAL_FIELDS
+
+
+def mask_text(text: str, values=()) -> str:
+    """Masks the values of personal key: value pairs, and any of values
+    wherever they appear."""
+    def replace(match):
+        quote, key, sep, value = match.groups()
+        if not is_personal(key):
+            return match.group(0)
+        q = value[0] if value[0] in "\"'" else ""
+        return f"{quote}{key}{quote}{sep}{q}{REDACTED}{q}"
+
+    masked = PAIR_PATTERN.sub(replace, text)
+    for value in values:
+        masked = masked.replace(value, REDACTED)
+    return masked
+
+
+def mask_personal_data(value, values=()):
+    """Returns a copy of value with personal fields replaced, at any depth."""
+    if isinstance(value, str):
+        return mask_text(value, values)
+    if isinstance(value, dict):
+        return {k: REDACTED if is_personal(k) else mask_personal_data(v, values) for k, v in value.items()}
+    if isinstance(value, (list, tuple)):
+        return type(value)(mask_personal_data(v, values) for v in value)
+    return value
+
+
+def personal_values(value) -> list:
+    """Returns the personal values in a request body."""
+    found = []
+    if isinstance(value, dict):
+        for k, v in value.items():
+            if is_personal(k) and isinstance(v, str) and v:
+                found.append(v)
+            else:
+                found.extend(personal_values(v))
+    elif isinstance(value, list):
+        for v in value:
+            found.extend(personal_values(v))
+    return found
+
+
+class MaskingFilter(logging.Filter):
+    def filter(self, record: logging.LogRecord) -> bool:
+        if isinstance(record.msg, str):
+            record.msg = mask_text(record.msg)
+        if record.args:
+            record.args = mask_personal_data(record.args)
+        return True
+
+
+async def validation_error(request: Request, exc: RequestValidationError):
+    """FastAPI's 422 response echoes the input; mask the personal parts."""
+    values = personal_values(exc.body)
+    errors = []
+    for error in jsonable_encoder(exc.errors()):
+        if any(is_personal(loc) for loc in error.get("loc", ())):
+            error["input"] = REDACTED
+        errors.append(mask_personal_data(error, values))
+    return JSONResponse(status_code=422, content={"detail": errors})
+
+
+async def http_error(request: Request, exc: StarletteHTTPException):
+    return JSONResponse(
+        status_code=exc.status_code,
+        content={"detail": mask_personal_data(exc.detail)},
+        headers=getattr(exc, "headers", None),
+    )
+
+
+def install_masking(app: FastAPI):
+    masking = MaskingFilter()
+    # Filters don't apply to records propagated from child loggers, so
+    # they go on the handlers too.
+    for name in ("", "uvicorn", "uvicorn.access", "uvicorn.error"):
+        logger = logging.getLogger(name)
+        logger.addFilter(masking)
+        for handler in logger.handlers:
+            handler.addFilter(masking)
+    app.add_exception_handler(RequestValidationError, validation_error)
+    app.add_exception_handler(StarletteHTTPException, http_error)
+`)
+	return sb.String()
+}
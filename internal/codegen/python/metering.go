@@ -0,0 +1,211 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateUsage produces usage.py: meter, a dependency counting the
+// signed-in user's calls and refusing them past their quota, and the
+// endpoints returning their calls this period and in each of the last
+// periods. When the app bills, calls are also reported to a Stripe meter.
+func generateUsage(app *ir.Application) string {
+	var b strings.Builder
+	m := app.Metering
+	fk := toSnakeCase(m.Model) + "_id"
+	bills := ir.Bills(app)
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("import datetime\n")
+	if bills {
+		b.WriteString("import logging\n")
+	}
+	b.WriteString("import math\n")
+	if bills {
+		b.WriteString("import os\n\n")
+		b.WriteString("import stripe\n")
+	} else {
+		b.WriteString("\n")
+	}
+	b.WriteString(`from fastapi import APIRouter, BackgroundTasks, Depends, HTTPException, Query
+from sqlalchemy import func
+from sqlalchemy.orm import Session
+from typing import Any, Optional, Tuple
+
+import models, auth
+`)
+	if bills {
+		b.WriteString("from billing import ACTIVE_STATUSES, current_plan, latest_subscription\n")
+	}
+	b.WriteString("from database import get_db\n\n")
+	b.WriteString("router = APIRouter()\n\n")
+	b.WriteString("# Calls are counted per this period.\n")
+	fmt.Fprintf(&b, "METER_PERIOD = '%s'\n\n", m.Period)
+
+	if bills {
+		b.WriteString("# The quota of each plan's subscribers, from the policy named after the plan, as (calls, period).\n")
+	} else {
+		b.WriteString("# The quota of each role, from the policy named after it, as (calls, period).\n")
+	}
+	b.WriteString("QUOTAS = {\n")
+	for _, q := range ir.UsageQuotas(app) {
+		if !q.Enforced(app) {
+			continue
+		}
+		key := q.Policy
+		if q.Plan != "" {
+			key = q.Plan
+		}
+		fmt.Fprintf(&b, "    '%s': (%d, '%s'),\n", key, q.Limit, q.Period)
+	}
+	b.WriteString("}\n\n\n")
+
+	b.WriteString(`def period_start(period: str, at: Optional[datetime.datetime] = None) -> datetime.datetime:
+    """The start of the period a time falls in, in UTC. Weeks start on Sunday."""
+    at = at or datetime.datetime.now(datetime.timezone.utc)
+    day = at.replace(hour=0, minute=0, second=0, microsecond=0)
+    if period == 'week':
+        return day - datetime.timedelta(days=(day.weekday() + 1) % 7)
+    if period == 'month':
+        return day.replace(day=1)
+    return day
+
+
+def add_periods(start: datetime.datetime, period: str, n: int) -> datetime.datetime:
+    """The start of the period n periods after the one starting at start."""
+    if period == 'month':
+        months = start.month - 1 + n
+        return start.replace(year=start.year + months // 12, month=months % 12 + 1)
+    return start + datetime.timedelta(days=n * (7 if period == 'week' else 1))
+
+
+`)
+	b.WriteString("def quota_for(db: Session, user: Any) -> Optional[Tuple[int, str]]:\n")
+	b.WriteString("    \"\"\"The quota a user is held to, or None when their calls aren't capped.\"\"\"\n")
+	switch {
+	case bills:
+		b.WriteString("    plan = current_plan(db, user.id)\n")
+		b.WriteString("    return QUOTAS.get(plan) if plan else None\n\n\n")
+	case ir.QuotaByRole(app):
+		b.WriteString("    return QUOTAS.get(user.role) if user.role else None\n\n\n")
+	default:
+		b.WriteString("    return None\n\n\n")
+	}
+
+	fmt.Fprintf(&b, `def calls_since(db: Session, user_id: str, since: datetime.datetime) -> int:
+    """How many calls a user has made since the start of a period."""
+    total = (
+        db.query(func.sum(models.UsageRecord.calls))
+        .filter(models.UsageRecord.%[1]s == user_id, models.UsageRecord.period_start >= since)
+        .scalar()
+    )
+    return int(total or 0)
+
+
+def record_call(db: Session, user_id: str) -> None:
+    """
+    Counts a call in the user's record for the current period. Two first
+    calls at once may each add a record; counts add up all of them.
+    """
+    start = period_start(METER_PERIOD)
+    updated = (
+        db.query(models.UsageRecord)
+        .filter(models.UsageRecord.%[1]s == user_id, models.UsageRecord.period_start == start)
+        .update({models.UsageRecord.calls: models.UsageRecord.calls + 1}, synchronize_session=False)
+    )
+    if not updated:
+        db.add(models.UsageRecord(%[1]s=user_id, period_start=start, calls=1))
+    db.commit()
+
+
+`, fk)
+
+	if bills {
+		b.WriteString(`def report_call(meter: str, customer_id: str) -> None:
+    """Reports a call to a Stripe meter, which metered prices bill by."""
+    try:
+        stripe.billing.MeterEvent.create(event_name=meter, payload={"stripe_customer_id": customer_id, "value": "1"})
+    except stripe.error.StripeError:
+        logging.exception("Could not report usage to Stripe")
+
+
+`)
+	}
+
+	upgrade := ""
+	if bills {
+		upgrade = ": upgrade your plan for more"
+	}
+	fmt.Fprintf(&b, `def meter(background_tasks: BackgroundTasks, db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+    """
+    Metering dependency — counts the signed-in user's calls.
+
+    Usage:
+        @router.get('/forecast')
+        def get_forecast(current_user = Depends(auth.get_current_user),
+                         _usage = Depends(meter)):
+
+    Behavior:
+        1. If the user has made all the calls their quota allows this period -> %[1]d, with Retry-After
+        2. Otherwise -> the call is counted and allowed
+    """
+    quota = quota_for(db, current_user)
+    if quota is not None:
+        limit, period = quota
+        start = period_start(period)
+        if calls_since(db, current_user.id, start) >= limit:
+            wait = add_periods(start, period, 1) - datetime.datetime.now(datetime.timezone.utc)
+            raise HTTPException(
+                status_code=%[1]d,
+                detail=f"You've made all {limit} of your API calls this {period}%[2]s",
+                headers={"Retry-After": str(math.ceil(wait.total_seconds()))},
+            )
+    record_call(db, current_user.id)
+`, ir.QuotaExceededStatus, upgrade)
+	if bills {
+		b.WriteString(`    # Reported to the Stripe meter named by STRIPE_USAGE_METER, if any, once
+    # the response is sent
+    meter_name = os.environ.get('STRIPE_USAGE_METER')
+    subscription = latest_subscription(db, current_user.id) if meter_name else None
+    if subscription is not None and subscription.status in ACTIVE_STATUSES:
+        background_tasks.add_task(report_call, meter_name, subscription.stripe_customer_id)
+`)
+	}
+	fmt.Fprintf(&b, `    return current_user
+
+
+@router.get('/usage')
+def get_usage(db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+    """Calls this period: per the user's quota, else per the metering period."""
+    limit, period = quota_for(db, current_user) or (None, METER_PERIOD)
+    start = period_start(period)
+    return {"data": {
+        "period": period,
+        "used": calls_since(db, current_user.id, start),
+        "limit": limit,
+        "resetsAt": add_periods(start, period, 1).isoformat(),
+    }}
+
+
+@router.get('/usage/history')
+def get_usage_history(periods: int = Query(30, ge=1, le=365), db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+    """The user's calls in each of the last periods, oldest first, with none counted as 0."""
+    since = add_periods(period_start(METER_PERIOD), METER_PERIOD, 1 - periods)
+    records = (
+        db.query(models.UsageRecord.period_start, models.UsageRecord.calls)
+        .filter(models.UsageRecord.%[1]s == current_user.id, models.UsageRecord.period_start >= since)
+        .all()
+    )
+    calls = {}
+    for start, count in records:
+        calls[start.date()] = calls.get(start.date(), 0) + count
+    history = []
+    for i in range(periods):
+        start = add_periods(since, METER_PERIOD, i)
+        history.append({"start": start.isoformat(), "calls": calls.get(start.date(), 0)})
+    return {"data": history}
+`, fk)
+	return b.String()
+}
@@ -0,0 +1,269 @@
+package python
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/migrate"
+)
+
+// generateMigrations returns the app's migration history as Alembic
+// revisions, by path relative to the backend, applied with `alembic
+// upgrade head` (`human migrate`, and the Docker image on start). The
+// first is initial.py, revision 000000000000, as it was when builds
+// generated it empty; later revisions are numbered on from it.
+func generateMigrations(app *ir.Application) map[string]string {
+	history := migrate.History(app)
+	if len(history) == 0 {
+		return map[string]string{"alembic/versions/initial.py": generateRevision(app, &ir.Migration{Name: "0001_initial"}, 0)}
+	}
+	files := map[string]string{}
+	for i, m := range history {
+		name := m.Name + ".py"
+		if i == 0 {
+			name = "initial.py"
+		}
+		files[path.Join("alembic", "versions", name)] = generateRevision(app, m, i)
+	}
+	return files
+}
+
+// revisionID returns the Alembic revision of the i'th migration.
+func revisionID(i int) string {
+	return fmt.Sprintf("%012d", i)
+}
+
+// generateRevision produces the Alembic revision of the i'th migration,
+// its operations matching the tables models.py declares.
+func generateRevision(app *ir.Application, m *ir.Migration, i int) string {
+	title := strings.ReplaceAll(strings.TrimLeft(m.Name, "0123456789"), "_", " ")
+	down, downRepr := "", "None"
+	if i > 0 {
+		down = revisionID(i - 1)
+		downRepr = "'" + down + "'"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `"""%s
+
+Revision ID: %s
+Revises: %s
+
+Generated by Human compiler — do not edit
+"""
+from typing import Sequence, Union
+from alembic import op
+import sqlalchemy as sa
+
+revision: str = '%s'
+down_revision: Union[str, None] = %s
+branch_labels: Union[str, Sequence[str], None] = None
+depends_on: Union[str, Sequence[str], None] = None
+
+
+def upgrade() -> None:
+`, strings.TrimSpace(title), revisionID(i), down, revisionID(i), downRepr)
+	writeOperations(&b, app, m.Changes)
+	b.WriteString("\n\ndef downgrade() -> None:\n")
+	writeOperations(&b, app, migrate.Inverted(m))
+	return b.String()
+}
+
+// writeOperations writes the operations making changes, each under a
+// comment describing it.
+func writeOperations(b *strings.Builder, app *ir.Application, changes []*ir.SchemaChange) {
+	if len(changes) == 0 {
+		b.WriteString("    pass\n")
+		return
+	}
+	for i, c := range changes {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "    # %s\n", migrate.Describe(c))
+		table := toSnakeCase(c.Model)
+		switch c.Kind {
+		case ir.CreateModel:
+			writeCreateTable(b, app, c.Data)
+		case ir.DropModel:
+			fmt.Fprintf(b, "    op.drop_table('%s')\n", table)
+		case ir.AddField:
+			writeAddColumn(b, app, c.Model, c.Field)
+		case ir.DropField:
+			if c.From.Unique && !isJoinTable(app, c.Model) {
+				fmt.Fprintf(b, "    op.drop_index('%s', table_name='%s')\n", indexName(table, c.From.Name), table)
+			}
+			fmt.Fprintf(b, "    op.drop_column('%s', '%s')\n", table, toSnakeCase(c.From.Name))
+		case ir.RenameField:
+			fmt.Fprintf(b, "    op.alter_column('%s', '%s', new_column_name='%s')\n", table, toSnakeCase(c.From.Name), toSnakeCase(c.Field.Name))
+			renamed := *c.From
+			renamed.Name = c.Field.Name
+			if c.From.Unique && !isJoinTable(app, c.Model) {
+				fmt.Fprintf(b, "    op.drop_index('%s', table_name='%s')\n", indexName(table, c.From.Name), table)
+				renamed.Unique = false
+			}
+			writeAlterColumn(b, app, c.Model, &renamed, c.Field)
+		case ir.ChangeField:
+			writeAlterColumn(b, app, c.Model, c.From, c.Field)
+		case ir.AddRelation:
+			fk := toSnakeCase(c.Target) + "_id"
+			fmt.Fprintf(b, "    op.add_column('%s', sa.Column('%s', sa.String(), nullable=True))\n", table, fk)
+			fmt.Fprintf(b, "    op.create_foreign_key('%s_%s_fkey', '%s', '%s', ['%s'], ['id'])\n", table, fk, table, toSnakeCase(c.Target), fk)
+		case ir.DropRelation:
+			fmt.Fprintf(b, "    op.drop_column('%s', '%s_id')\n", table, toSnakeCase(c.Target))
+		}
+	}
+}
+
+// writeCreateTable writes the op creating a model's table or, for a join
+// model, its association table.
+func writeCreateTable(b *strings.Builder, app *ir.Application, model *ir.DataModel) {
+	table := toSnakeCase(model.Name)
+	fmt.Fprintf(b, "    op.create_table(\n        '%s',\n", table)
+	if isJoinTable(app, model.Name) {
+		// Association rows are keyed by the pair they link; their
+		// fields stay nullable, as in models.py.
+		for _, rel := range model.Relations {
+			if rel.Kind == "belongs_to" {
+				target := toSnakeCase(rel.Target)
+				fmt.Fprintf(b, "        sa.Column('%s_id', sa.String(), sa.ForeignKey('%s.id'), primary_key=True),\n", target, target)
+			}
+		}
+		for _, f := range model.Fields {
+			fmt.Fprintf(b, "        sa.Column('%s', %s),\n", toSnakeCase(f.Name), alembicType(app, f))
+		}
+		b.WriteString("    )\n")
+		return
+	}
+
+	b.WriteString("        sa.Column('id', sa.String(), nullable=False),\n")
+	var unique []*ir.DataField
+	for _, f := range migrate.Columns(model) {
+		fmt.Fprintf(b, "        sa.Column('%s', %s, nullable=%s),\n", toSnakeCase(f.Name), alembicType(app, f), pyBool(!f.Required))
+		if f.Unique {
+			unique = append(unique, f)
+		}
+	}
+	b.WriteString("        sa.Column('created_at', sa.DateTime(timezone=True), server_default=sa.func.now(), nullable=True),\n")
+	b.WriteString("        sa.Column('updated_at', sa.DateTime(timezone=True), nullable=True),\n")
+	for _, rel := range model.Relations {
+		if rel.Kind == "belongs_to" {
+			target := toSnakeCase(rel.Target)
+			fmt.Fprintf(b, "        sa.Column('%s_id', sa.String(), sa.ForeignKey('%s.id'), nullable=True),\n", target, target)
+		}
+	}
+	b.WriteString("        sa.PrimaryKeyConstraint('id'),\n    )\n")
+	fmt.Fprintf(b, "    op.create_index('%s', '%s', ['id'])\n", indexName(table, "id"), table)
+	for _, f := range unique {
+		fmt.Fprintf(b, "    op.create_index('%s', '%s', ['%s'], unique=True)\n", indexName(table, f.Name), table, toSnakeCase(f.Name))
+	}
+}
+
+// writeAddColumn writes the ops adding a field's column. A required column
+// is added with a placeholder value for the rows already in the table,
+// which new rows don't get.
+func writeAddColumn(b *strings.Builder, app *ir.Application, model string, f *ir.DataField) {
+	table, col := toSnakeCase(model), toSnakeCase(f.Name)
+	switch {
+	case isJoinTable(app, model):
+		fmt.Fprintf(b, "    op.add_column('%s', sa.Column('%s', %s))\n", table, col, alembicType(app, f))
+		return
+	case f.Required:
+		fmt.Fprintf(b, "    op.add_column('%s', sa.Column('%s', %s, nullable=False, server_default=sa.text(%q)))\n",
+			table, col, alembicType(app, f), migrate.Placeholder(f))
+		fmt.Fprintf(b, "    op.alter_column('%s', '%s', server_default=None)\n", table, col)
+	default:
+		fmt.Fprintf(b, "    op.add_column('%s', sa.Column('%s', %s, nullable=True))\n", table, col, alembicType(app, f))
+	}
+	if f.Unique {
+		fmt.Fprintf(b, "    op.create_index('%s', '%s', ['%s'], unique=True)\n", indexName(table, f.Name), table, col)
+	}
+}
+
+// writeAlterColumn writes the ops changing a column's type, nullability,
+// and unique index to a field's. Enum values and defaults aren't kept in
+// the database.
+func writeAlterColumn(b *strings.Builder, app *ir.Application, model string, from, to *ir.DataField) {
+	table, col := toSnakeCase(model), toSnakeCase(to.Name)
+	join := isJoinTable(app, model)
+	var args []string
+	if oldType, newType := alembicType(app, from), alembicType(app, to); oldType != newType {
+		args = append(args, "type_="+newType, "existing_type="+oldType,
+			fmt.Sprintf("postgresql_using='%s::%s'", col, pgCast(app, to)))
+	}
+	if !join && from.Required != to.Required {
+		args = append(args, "nullable="+pyBool(!to.Required))
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(b, "    op.alter_column('%s', '%s', %s)\n", table, col, strings.Join(args, ", "))
+	}
+	if join {
+		return
+	}
+	if to.Unique && !from.Unique {
+		fmt.Fprintf(b, "    op.create_index('%s', '%s', ['%s'], unique=True)\n", indexName(table, to.Name), table, col)
+	} else if from.Unique && !to.Unique {
+		fmt.Fprintf(b, "    op.drop_index('%s', table_name='%s')\n", indexName(table, to.Name), table)
+	}
+}
+
+// alembicType returns a field's column type as models.py declares it, in
+// SQLAlchemy's sa namespace. Encrypted fields are stored as text.
+func alembicType(app *ir.Application, f *ir.DataField) string {
+	if f.EncryptsAtRest() {
+		return "sa.Text()"
+	}
+	t := columnType(f, app)
+	if !strings.Contains(t, "(") {
+		t += "()"
+	}
+	return "sa." + t
+}
+
+// pgCast returns the PostgreSQL type a column is converted to when its
+// field's type changes.
+func pgCast(app *ir.Application, f *ir.DataField) string {
+	switch t := alembicType(app, f); t {
+	case "sa.Integer()":
+		return "integer"
+	case "sa.Float()":
+		return "double precision"
+	case "sa.Boolean()":
+		return "boolean"
+	case "sa.Date()":
+		return "date"
+	case "sa.DateTime()":
+		return "timestamp"
+	case "sa.DateTime(timezone=True)":
+		return "timestamptz"
+	case "sa.JSON()":
+		return "json"
+	case "sa.Text()":
+		return "text"
+	}
+	return "varchar"
+}
+
+// isJoinTable reports whether a model is stored as an association table.
+func isJoinTable(app *ir.Application, model string) bool {
+	for _, m := range app.Data {
+		if m.Name == model {
+			return isJoinModel(app, m)
+		}
+	}
+	return false
+}
+
+// indexName returns the name SQLAlchemy gives a column's index.
+func indexName(table, field string) string {
+	return "ix_" + table + "_" + toSnakeCase(field)
+}
+
+func pyBool(v bool) string {
+	if v {
+		return "True"
+	}
+	return "False"
+}
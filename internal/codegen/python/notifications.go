@@ -0,0 +1,127 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeNotifyCalls emits a notify() call for each in-app notification the
+// endpoint triggers. The recipient is the user named by a param (user_id,
+// assignee, ...), else the record's owner, else the current user.
+func writeNotifyCalls(sb *strings.Builder, api *ir.Endpoint, app *ir.Application, record string) {
+	for _, n := range app.Notifications {
+		if !n.Triggers(api) {
+			continue
+		}
+		recipient := ""
+		if param := n.RecipientParam(api); param != "" {
+			recipient = "payload." + toSnakeCase(param)
+		} else if record != "" && modelBelongsToUser(n.Model, app) {
+			recipient = record + ".user_id"
+		} else if api.Auth {
+			recipient = "current_user.id"
+		}
+		if recipient == "" {
+			sb.WriteString("    # TODO: no recipient for the in-app notification — require authentication\n")
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("    notifications.notify(db, %s, '%s')\n", recipient, n.Message))
+	}
+}
+
+// modelBelongsToUser reports whether a model has a belongs_to User relation.
+func modelBelongsToUser(modelName string, app *ir.Application) bool {
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, modelName) {
+			for _, r := range m.Relations {
+				if r.Kind == "belongs_to" && strings.EqualFold(r.Target, "User") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// generateNotifications produces notifications.py: notify() for storing an
+// in-app notification, and the current user's notifications, their unread
+// count, and marking them read. The frontend dropdown polls the unread count.
+func generateNotifications() string {
+	return `# Generated by Human compiler — do not edit
+import datetime
+
+from fastapi import APIRouter, Depends, HTTPException
+from sqlalchemy.orm import Session
+from typing import Any
+
+import models, auth
+from database import get_db
+
+router = APIRouter()
+
+
+def notify(db: Session, user_id: str, message: str) -> models.Notification:
+    notification = models.Notification(user_id=str(user_id), message=message)
+    db.add(notification)
+    db.commit()
+    db.refresh(notification)
+    return notification
+
+
+def serialize(notification: models.Notification) -> dict:
+    return {
+        "id": notification.id,
+        "message": notification.message,
+        "seen": notification.seen.isoformat() if notification.seen else None,
+        "createdAt": notification.created_at.isoformat() if notification.created_at else None,
+    }
+
+
+def unread(db: Session, user_id: str):
+    return db.query(models.Notification).filter(
+        models.Notification.user_id == user_id,
+        models.Notification.seen.is_(None),
+    )
+
+
+@router.get("/notifications")
+def list_notifications(db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+    items = (
+        db.query(models.Notification)
+        .filter(models.Notification.user_id == current_user.id)
+        .order_by(models.Notification.created_at.desc())
+        .limit(50)
+        .all()
+    )
+    return {"data": [serialize(n) for n in items]}
+
+
+@router.get("/notifications/unread-count")
+def unread_count(db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+    return {"data": {"count": unread(db, current_user.id).count()}}
+
+
+@router.post("/notifications/read-all")
+def read_all(db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+    count = unread(db, current_user.id).update({"seen": datetime.datetime.now(datetime.timezone.utc)}, synchronize_session=False)
+    db.commit()
+    return {"data": {"count": count}}
+
+
+@router.post("/notifications/{notification_id}/read")
+def read_one(notification_id: str, db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):
+    notification = db.query(models.Notification).filter(
+        models.Notification.id == notification_id,
+        models.Notification.user_id == current_user.id,
+    ).first()
+    if notification is None:
+        raise HTTPException(status_code=404, detail="Notification not found")
+    if notification.seen is None:
+        notification.seen = datetime.datetime.now(datetime.timezone.utc)
+        db.commit()
+        db.refresh(notification)
+    return {"data": serialize(notification)}
+`
+}
@@ -0,0 +1,51 @@
+package python
+
+// generateOpenAPIValidation produces openapi_validation.py, which checks
+// each API request against openapi.yaml, the copy of openapi/openapi.yaml
+// beside it, with openapi-core.
+func generateOpenAPIValidation() string {
+	return `"""Request validation against the app's OpenAPI document.
+
+Each API request openapi.yaml describes is checked against its parameters
+and body before the route runs, so handwritten changes to the routes can't
+drift from the declared contract unnoticed. A request that doesn't match
+gets a 400. Requests it doesn't describe pass through, and authentication
+is left to the routes.
+"""
+import os
+
+import yaml
+from fastapi import Request
+from fastapi.responses import JSONResponse
+from openapi_core import OpenAPI
+from openapi_core.contrib.starlette.requests import StarletteOpenAPIRequest
+from openapi_core.templating.paths.exceptions import PathError
+from openapi_core.validation.exceptions import ValidationError
+from openapi_core.validation.request.exceptions import SecurityValidationError
+
+with open(os.path.join(os.path.dirname(__file__), "openapi.yaml")) as f:
+    _spec = yaml.safe_load(f)
+# Requests are matched on their path alone, whichever host they were sent to
+_spec["servers"] = [{"url": "/"}]
+_openapi = OpenAPI.from_dict(_spec)
+
+
+async def validate_request(request: Request, call_next):
+    if not request.url.path.startswith("/api/"):
+        return await call_next(request)
+    body = await request.body()
+
+    # Let the route read the body again
+    async def receive():
+        return {"type": "http.request", "body": body, "more_body": False}
+
+    request._receive = receive
+    try:
+        _openapi.validate_request(StarletteOpenAPIRequest(request, body))
+    except (PathError, SecurityValidationError):
+        pass
+    except ValidationError as err:
+        return JSONResponse(status_code=400, content={"detail": str(err)})
+    return await call_next(request)
+`
+}
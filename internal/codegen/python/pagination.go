@@ -0,0 +1,136 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// pagedModel returns the model a paginated endpoint serves a page at a
+// time: the one its first query fetches all of. It returns "" when the
+// endpoint doesn't paginate.
+func pagedModel(api *ir.Endpoint) string {
+	if api.PageSize == 0 {
+		return ""
+	}
+	for _, step := range api.Steps {
+		if step.Type != "query" {
+			continue
+		}
+		model := inferModelFromAction(step.Text)
+		if model == "" {
+			continue
+		}
+		lower := strings.ToLower(step.Text)
+		if !strings.Contains(lower, " by ") && (strings.Contains(lower, "all") || strings.Contains(lower, "where")) {
+			return model
+		}
+		return ""
+	}
+	return ""
+}
+
+// hasPagedEndpoints reports whether any endpoint serves its list a page at
+// a time.
+func hasPagedEndpoints(app *ir.Application) bool {
+	for _, api := range app.APIs {
+		if pagedModel(api) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// listQueryParams returns the query parameters of a paginated endpoint's
+// signature beside cursor and limit: the page number, the field to sort
+// by and its order, ?q= when it searches, and one per filter.
+func listQueryParams(params *ir.ListParams) []string {
+	deps := []string{"page: Optional[int] = Query(None, ge=1)", "sort: Optional[str] = None", "order: str = 'asc'"}
+	if len(params.Search) > 0 {
+		deps = append(deps, "q: Optional[str] = None")
+	}
+	for _, f := range params.Filters {
+		typ := "str"
+		switch f.Type {
+		case "boolean":
+			typ = "bool"
+		case "number":
+			typ = "float"
+		}
+		if name := filterParam(f); name != f.Name {
+			deps = append(deps, fmt.Sprintf("%s: Optional[%s] = Query(None, alias='%s')", name, typ, f.Name))
+		} else {
+			deps = append(deps, fmt.Sprintf("%s: Optional[%s] = None", name, typ))
+		}
+	}
+	return deps
+}
+
+// filterParam returns the name of the parameter a list is filtered by f
+// in, clear of the endpoint's other parameters.
+func filterParam(f *ir.DataField) string {
+	name := toSnakeCase(f.Name)
+	switch name {
+	case "cursor", "limit", "page", "sort", "order", "q", "db", "current_user", "payload", "response":
+		return name + "_filter"
+	}
+	return name
+}
+
+// writePagedQuery narrows query to the filters and search given, orders
+// it, and narrows it to a page: the one ?page= numbers, or else the one
+// after the record the cursor names. One extra record is fetched to tell
+// whether another page follows. Records come newest first, or in the order
+// users dragged them into, with records never dragged last, unless ?sort=
+// orders them by one of the model's fields; sorted records are paged by
+// number, since a cursor only follows the default order.
+func writePagedQuery(sb *strings.Builder, model string, params *ir.ListParams, app *ir.Application) {
+	m := "models." + model
+	for _, f := range params.Filters {
+		name := filterParam(f)
+		fmt.Fprintf(sb, "    if %s is not None:\n", name)
+		fmt.Fprintf(sb, "        query = query.filter(%s.%s == %s)\n", m, toSnakeCase(f.Name), name)
+	}
+	if len(params.Search) > 0 {
+		var likes []string
+		for _, f := range params.Search {
+			likes = append(likes, fmt.Sprintf("%s.%s.ilike(f'%%{q}%%')", m, toSnakeCase(f.Name)))
+		}
+		sb.WriteString("    if q:\n")
+		fmt.Fprintf(sb, "        query = query.filter(or_(%s))\n", strings.Join(likes, ", "))
+	}
+	var columns []string
+	for _, f := range params.Sortable {
+		columns = append(columns, fmt.Sprintf("'%s': %s.%s", f.Name, m, toSnakeCase(f.Name)))
+	}
+	fmt.Fprintf(sb, "    sort_column = {%s}.get(sort or '')\n", strings.Join(columns, ", "))
+	sb.WriteString("    if sort_column is not None:\n")
+	sb.WriteString("        direction = desc if order == 'desc' else asc\n")
+	fmt.Fprintf(sb, "        query = query.order_by(direction(sort_column), direction(%s.id))\n", m)
+	sb.WriteString("    else:\n")
+	if r := ir.ReorderFor(app, model); r != nil {
+		pos := m + "." + toSnakeCase(r.Field)
+		fmt.Fprintf(sb, "        query = query.order_by(%s.asc().nulls_last(), %s.id)\n", pos, m)
+		sb.WriteString("    if sort_column is not None or page is not None:\n")
+		sb.WriteString("        query = query.offset(((page or 1) - 1) * limit)\n")
+		sb.WriteString("    elif cursor:\n")
+		fmt.Fprintf(sb, "        after = db.query(%s).filter(%s.id == cursor).first()\n", m, m)
+		fmt.Fprintf(sb, "        if after is not None and after.%s is None:\n", toSnakeCase(r.Field))
+		fmt.Fprintf(sb, "            query = query.filter(%s.is_(None), %s.id > after.id)\n", pos, m)
+		sb.WriteString("        elif after is not None:\n")
+		fmt.Fprintf(sb, "            query = query.filter(or_(%s > after.%s, and_(%s == after.%s, %s.id > after.id), %s.is_(None)))\n",
+			pos, toSnakeCase(r.Field), pos, toSnakeCase(r.Field), m, pos)
+	} else {
+		fmt.Fprintf(sb, "        query = query.order_by(%s.created_at.desc(), %s.id.desc())\n", m, m)
+		sb.WriteString("    if sort_column is not None or page is not None:\n")
+		sb.WriteString("        query = query.offset(((page or 1) - 1) * limit)\n")
+		sb.WriteString("    elif cursor:\n")
+		fmt.Fprintf(sb, "        after = db.query(%s).filter(%s.id == cursor).first()\n", m, m)
+		sb.WriteString("        if after is not None:\n")
+		fmt.Fprintf(sb, "            query = query.filter(tuple_(%s.created_at, %s.id) < (after.created_at, after.id))\n", m, m)
+	}
+	sb.WriteString("    items = query.limit(limit + 1).all()\n")
+	sb.WriteString("    next_cursor = items[limit - 1].id if len(items) > limit else None\n")
+	sb.WriteString("    items = items[:limit]\n")
+}
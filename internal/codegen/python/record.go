@@ -0,0 +1,87 @@
+package python
+
+import (
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateRecord produces record.py: with request recording on (`human run
+// --record`), every API request and the response it got is written to an
+// .http file that `human replay` can send again. It does nothing otherwise.
+func generateRecord() string {
+	return `"""Request recording — API requests written out to be replayed.
+
+Off unless ` + ir.RecordEnv + ` names a directory. Each API request is then
+written there to its own .http file in the VS Code REST Client format, with
+the response it got in the comments above it, so it can be sent again from
+the editor or with ` + "`human replay`" + `.
+"""
+import itertools
+import logging
+import os
+import re
+import time
+from datetime import datetime, timezone
+
+from fastapi import Request
+from fastapi.responses import Response
+
+logger = logging.getLogger(__name__)
+
+DIR = os.getenv("` + ir.RecordEnv + `", "")
+PORT = os.getenv("PORT", "8000")
+# Sent again on replay as they were, except these
+SKIPPED_HEADERS = {"host", "connection", "content-length", "transfer-encoding", "accept-encoding"}
+_seq = itertools.count(1)
+
+if DIR:
+    os.makedirs(DIR, exist_ok=True)
+    logger.warning("[record] Recording API requests to %s", DIR)
+
+
+async def record(request: Request, call_next):
+    if not DIR or not request.url.path.startswith("/api/"):
+        return await call_next(request)
+    recorded_at = datetime.now(timezone.utc)
+    started = time.monotonic()
+    body = await request.body()
+
+    # Let the route read the body again
+    async def receive():
+        return {"type": "http.request", "body": body, "more_body": False}
+
+    request._receive = receive
+    response = await call_next(request)
+    content = b"".join([chunk async for chunk in response.body_iterator])
+    elapsed = round((time.monotonic() - started) * 1000)
+
+    target = request.url.path + (f"?{request.url.query}" if request.url.query else "")
+    lines = [
+        f"@host = http://localhost:{PORT}",
+        "",
+        "###",
+        f"# Recorded: {recorded_at.isoformat(timespec='milliseconds').replace('+00:00', 'Z')}",
+        f"# Response: {response.status_code} ({elapsed} ms)",
+    ]
+    lines += [f"# {line}" for line in content.decode(errors="replace").splitlines() if line]
+    lines.append(f"{request.method} {{{{host}}}}{target}")
+    lines += [f"{name}: {value}" for name, value in request.headers.items() if name not in SKIPPED_HEADERS]
+    if body:
+        lines += ["", body.decode(errors="replace")]
+
+    stamp = recorded_at.strftime("%Y-%m-%dT%H-%M-%S-%f")[:-3]
+    slug = re.sub(r"[^a-z0-9]+", "-", request.url.path[len("/api/"):].lower()).strip("-") or "root"
+    name = f"{stamp}Z-{next(_seq):04d}-{request.method}-{slug}.http"
+    try:
+        with open(os.path.join(DIR, name), "w") as f:
+            f.write("\n".join(lines) + "\n")
+    except OSError as err:
+        logger.warning("[record] Could not record %s %s: %s", request.method, target, err)
+
+    return Response(
+        content=content,
+        status_code=response.status_code,
+        headers=dict(response.headers),
+        media_type=response.media_type,
+    )
+`
+}
@@ -0,0 +1,55 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateReorder produces reorder.py: for each list users can drag into
+// a new order, a PUT /api/reorder/<slug> taking {"ids"} in their new
+// order and numbering the model's position column from 0. A model that
+// belongs to a user only reorders the signed-in user's records.
+func generateReorder(app *ir.Application) string {
+	var b strings.Builder
+	authed := app.Auth != nil
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("from typing import Any, List\n\n")
+	b.WriteString("from fastapi import APIRouter, Depends\n")
+	b.WriteString("from pydantic import BaseModel\n")
+	b.WriteString("from sqlalchemy.orm import Session\n\n")
+	if authed {
+		b.WriteString("import models, auth\n")
+	} else {
+		b.WriteString("import models\n")
+	}
+	b.WriteString("from database import get_db\n")
+	b.WriteString("\nrouter = APIRouter()\n")
+	b.WriteString("\n\nclass ReorderPayload(BaseModel):\n")
+	b.WriteString("    ids: List[str]\n")
+
+	for _, r := range app.Reorders {
+		scoped := authed && modelBelongsToUser(r.Model, app) && !strings.EqualFold(r.Model, "User")
+		class := "models." + toPascalCase(r.Model)
+		fn := "reorder_" + strings.ReplaceAll(r.Slug, "-", "_")
+		fmt.Fprintf(&b, "\n\n@router.put('/reorder/%s')\n", r.Slug)
+		if authed {
+			fmt.Fprintf(&b, "def %s(payload: ReorderPayload, db: Session = Depends(get_db), current_user: Any = Depends(auth.get_current_user)):\n", fn)
+		} else {
+			fmt.Fprintf(&b, "def %s(payload: ReorderPayload, db: Session = Depends(get_db)):\n", fn)
+		}
+		fmt.Fprintf(&b, "    \"\"\"Drag-and-drop order of %s.\"\"\"\n", strings.ReplaceAll(r.Slug, "-", " "))
+		b.WriteString("    for position, record_id in enumerate(payload.ids):\n")
+		fmt.Fprintf(&b, "        query = db.query(%s).filter(%s.id == record_id)\n", class, class)
+		if scoped {
+			fmt.Fprintf(&b, "        query = query.filter(%s.user_id == current_user.id)\n", class)
+		}
+		fmt.Fprintf(&b, "        query.update({%s.%s: position})\n", class, toSnakeCase(r.Field))
+		b.WriteString("    db.commit()\n")
+		b.WriteString("    return {'data': payload.ids}\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,196 @@
+package python
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/barun-bash/human/internal/fixtures"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateSeed produces seed.py, run with `python seed.py` (`human seed`):
+// it merges the app's seed records — the same records as fixtures/ — into
+// the development database, belongs_to targets first, so running it again
+// leaves the records as they were generated. Join models are rows of their
+// association tables, replaced pair by pair; a join model that doesn't
+// belong to two models has no table and isn't seeded. Password fields are set to
+// the hash of ir.SeedPassword.
+func generateSeed(app *ir.Application) string {
+	set := fixtures.Seeded(app)
+	hashes, times := false, false
+	for _, model := range set.Models {
+		for _, f := range model.Fields {
+			hashes = hashes || strings.EqualFold(f.Name, "password")
+			times = times || f.Type == "date" || f.Type == "datetime"
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`# Generated by Human compiler — do not edit
+#
+# Seeds the development database with sample records, the same records as
+# fixtures/. Run with:
+#   python seed.py
+# Running it again resets the records to these values.
+`)
+	if hashes {
+		fmt.Fprintf(&b, "# Seeded users sign in with the password '%s'.\n", ir.SeedPassword)
+	}
+	if times {
+		b.WriteString("import datetime\n\n")
+	}
+	if hashes {
+		b.WriteString("import auth\n")
+	}
+	b.WriteString("import models\nfrom database import SessionLocal\n")
+	if hashes {
+		fmt.Fprintf(&b, "\nPASSWORD = auth.get_password_hash('%s')\n", ir.SeedPassword)
+	}
+
+	for _, model := range set.Models {
+		records := set.Records[model.Name]
+		join := isJoinModel(app, model)
+		if len(records) == 0 || join && len(seedJoinKeys(model)) != 2 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s = [\n", seedConstant(model))
+		seen := map[string]bool{}
+		for _, rec := range records {
+			items := seedItems(app, model, rec, join)
+			if join {
+				// Each pair is linked once: the pair is the table's key.
+				pair := strings.Join(items[:2], ", ")
+				if seen[pair] {
+					continue
+				}
+				seen[pair] = true
+			}
+			fmt.Fprintf(&b, "    {%s},\n", strings.Join(items, ", "))
+		}
+		b.WriteString("]\n")
+	}
+
+	b.WriteString("\n\ndef main() -> None:\n")
+	b.WriteString("    db = SessionLocal()\n")
+	b.WriteString("    try:\n")
+	for _, model := range set.Models {
+		join := isJoinModel(app, model)
+		if len(set.Records[model.Name]) == 0 || join && len(seedJoinKeys(model)) != 2 {
+			continue
+		}
+		name := seedConstant(model)
+		if join {
+			table := "models." + toSnakeCase(model.Name)
+			keys := seedJoinKeys(model)
+			fmt.Fprintf(&b, "        for data in %s:\n", name)
+			fmt.Fprintf(&b, "            db.execute(%s.delete().where(%s.c.%s == data['%s'], %s.c.%s == data['%s']))\n",
+				table, table, keys[0], keys[0], table, keys[1], keys[1])
+			fmt.Fprintf(&b, "            db.execute(%s.insert().values(**data))\n", table)
+		} else {
+			fmt.Fprintf(&b, "        for data in %s:\n", name)
+			fmt.Fprintf(&b, "            db.merge(models.%s(**data))\n", toPascalCase(model.Name))
+		}
+		b.WriteString("        db.commit()\n")
+		fmt.Fprintf(&b, "        print(f'Seeded {len(%s)} %s')\n", name, toSnakeCase(model.Name)+"s")
+	}
+	b.WriteString(`    finally:
+        db.close()
+
+
+if __name__ == '__main__':
+    main()
+`)
+	return b.String()
+}
+
+// seedConstant returns the name of the list holding a model's seed
+// records: "TaskTag" → "TASK_TAGS".
+func seedConstant(model *ir.DataModel) string {
+	return strings.ToUpper(toSnakeCase(model.Name)) + "S"
+}
+
+// seedJoinKeys returns the foreign key columns of a join model, sorted,
+// which together key its association table.
+func seedJoinKeys(model *ir.DataModel) []string {
+	var keys []string
+	for _, rel := range model.Relations {
+		if rel.Kind == "belongs_to" {
+			keys = append(keys, toSnakeCase(rel.Target)+"_id")
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// seedItems returns a fixture record as the items of a Python dict keyed
+// by column: the id (except for join models' association rows), foreign
+// keys, and each field.
+func seedItems(app *ir.Application, model *ir.DataModel, rec map[string]any, join bool) []string {
+	var items []string
+	if !join {
+		items = append(items, "'id': "+pyLiteral(rec["id"]))
+	}
+	var fks []string
+	for _, rel := range model.Relations {
+		if v, ok := rec[fixtures.ForeignKey(rel.Target)]; ok && rel.Kind == "belongs_to" {
+			fks = append(fks, fmt.Sprintf("'%s_id': %s", toSnakeCase(rel.Target), pyLiteral(v)))
+		}
+	}
+	if join {
+		// Association rows lead with the pair keying them, sorted.
+		sort.Strings(fks)
+	}
+	items = append(items, fks...)
+	for _, f := range model.Fields {
+		value := pyLiteral(rec[f.Name])
+		if strings.EqualFold(f.Name, "password") {
+			value = "PASSWORD"
+		} else if s, ok := rec[f.Name].(string); ok {
+			value = pyTimeLiteral(f.Type, s, ir.StoresUTC(app), value)
+		}
+		items = append(items, fmt.Sprintf("'%s': %s", toSnakeCase(f.Name), value))
+	}
+	return items
+}
+
+// pyTimeLiteral returns a date or datetime fixture value as a Python date
+// or datetime, which SQLAlchemy's Date and DateTime columns need; other
+// values are returned as they are.
+func pyTimeLiteral(fieldType, value string, utc bool, literal string) string {
+	switch fieldType {
+	case "date":
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			return fmt.Sprintf("datetime.date(%d, %d, %d)", t.Year(), t.Month(), t.Day())
+		}
+	case "datetime":
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			t = t.UTC()
+			tz := ""
+			if utc {
+				tz = ", tzinfo=datetime.timezone.utc"
+			}
+			return fmt.Sprintf("datetime.datetime(%d, %d, %d, %d, %d%s)", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), tz)
+		}
+	}
+	return literal
+}
+
+// pyLiteral returns a fixture value as a Python literal.
+func pyLiteral(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case string:
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(v) + "'"
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
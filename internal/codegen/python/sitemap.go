@@ -0,0 +1,101 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateSitemap produces sitemap.py: sitemap.xml, with the public pages
+// and a URL per record of each public model, and robots.txt, which keeps
+// crawlers out of the API and private pages. Both are served under /api;
+// the frontend's server proxies /sitemap.xml and /robots.txt to them.
+func generateSitemap(app *ir.Application) string {
+	sm := app.Sitemap
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	b.WriteString("import os\n")
+	b.WriteString("from xml.sax.saxutils import escape\n\n")
+	if len(sm.Records) > 0 {
+		b.WriteString("from fastapi import APIRouter, Depends, Request\n")
+		b.WriteString("from fastapi.responses import Response\n")
+		b.WriteString("from sqlalchemy.orm import Session\n\n")
+		b.WriteString("import models\n")
+		b.WriteString("from database import get_db\n")
+	} else {
+		b.WriteString("from fastapi import APIRouter, Request\n")
+		b.WriteString("from fastapi.responses import Response\n")
+	}
+	b.WriteString("\nrouter = APIRouter()\n\n")
+
+	pages := make([]string, len(sm.Pages))
+	for i, p := range sm.Pages {
+		pages[i] = "'" + p + "'"
+	}
+	fmt.Fprintf(&b, "PAGES = [%s]\n", strings.Join(pages, ", "))
+	b.WriteString("# A sitemap holds at most 50,000 URLs.\n")
+	b.WriteString("MAX_URLS = 50000\n")
+	b.WriteString("ROBOTS_RULES = [\n")
+	for _, line := range robotsRules(sm) {
+		fmt.Fprintf(&b, "    '%s',\n", line)
+	}
+	b.WriteString("]\n\n\n")
+
+	b.WriteString("def site_url(request: Request) -> str:\n")
+	b.WriteString("    \"\"\"The canonical origin: SITE_URL, else the declared production URL, else the request's host.\"\"\"\n")
+	if sm.URL != "" {
+		fmt.Fprintf(&b, "    return (os.getenv('SITE_URL') or '%s').rstrip('/')\n", sm.URL)
+	} else {
+		b.WriteString("    return (os.getenv('SITE_URL') or str(request.base_url)).rstrip('/')\n")
+	}
+
+	b.WriteString(`
+
+def url_entry(loc: str, lastmod=None) -> str:
+    mod = f'<lastmod>{lastmod.date().isoformat()}</lastmod>' if lastmod else ''
+    return f'  <url><loc>{escape(loc)}</loc>{mod}</url>'
+
+
+@router.get('/sitemap.xml')
+`)
+	if len(sm.Records) > 0 {
+		b.WriteString("def sitemap(request: Request, db: Session = Depends(get_db)):\n")
+	} else {
+		b.WriteString("def sitemap(request: Request):\n")
+	}
+	b.WriteString("    origin = site_url(request)\n")
+	b.WriteString("    urls = [url_entry(origin + path) for path in PAGES]\n")
+	for _, rec := range sm.Records {
+		fmt.Fprintf(&b, "    for record in db.query(models.%s).limit(MAX_URLS).all():\n", toPascalCase(rec.Model))
+		fmt.Fprintf(&b, "        urls.append(url_entry(f'{origin}%s{record.id}', record.updated_at))\n", rec.Link())
+	}
+	b.WriteString(`    body = '\n'.join([
+        '<?xml version="1.0" encoding="UTF-8"?>',
+        '<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">',
+        *urls[:MAX_URLS],
+        '</urlset>',
+        '',
+    ])
+    return Response(content=body, media_type='application/xml')
+
+
+@router.get('/robots.txt')
+def robots(request: Request):
+    lines = [*ROBOTS_RULES, '', f'Sitemap: {site_url(request)}/sitemap.xml', '']
+    return Response(content='\n'.join(lines), media_type='text/plain')
+`)
+
+	return b.String()
+}
+
+// robotsRules returns robots.txt's rules: every crawler stays out of the
+// API and the pages that need signing in.
+func robotsRules(sm *ir.Sitemap) []string {
+	lines := []string{"User-agent: *", "Disallow: /api/"}
+	for _, p := range sm.Private {
+		lines = append(lines, "Disallow: "+p)
+	}
+	return lines
+}
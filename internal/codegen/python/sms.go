@@ -0,0 +1,182 @@
+package python
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// twilioEnv returns the environment variable for the first credential whose
+// key mentions one of the keywords ("account sid" → TWILIO_ACCOUNT_SID).
+func twilioEnv(integ *ir.Integration, fallback string, keywords ...string) string {
+	keys := make([]string, 0, len(integ.Credentials))
+	for key := range integ.Credentials {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, kw := range keywords {
+			if strings.Contains(strings.ToLower(key), kw) {
+				return integ.Credentials[key]
+			}
+		}
+	}
+	return fallback
+}
+
+// generateSMSService produces services/sms_service.py: SMS and WhatsApp
+// messages through Twilio, retried per the app's error handlers. In sandbox
+// mode messages are recorded in an outbox instead of sent.
+func generateSMSService(integ *ir.Integration, app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	fmt.Fprintf(&b, "# Integration: %s (sms)\n\n", integ.Service)
+
+	b.WriteString("import json\n")
+	b.WriteString("import os\n")
+	b.WriteString("import time\n")
+	b.WriteString("from typing import Optional\n\n")
+	b.WriteString("from twilio.rest import Client\n\n")
+
+	sidEnv := twilioEnv(integ, "TWILIO_ACCOUNT_SID", "sid", "account")
+	tokenEnv := twilioEnv(integ, "TWILIO_AUTH_TOKEN", "token", "secret")
+	fromEnv := twilioEnv(integ, "TWILIO_FROM_NUMBER", "from", "number", "phone")
+
+	fmt.Fprintf(&b, "FROM_NUMBER = os.environ.get('%s', '%s')\n", fromEnv, integ.Config["sender_email"])
+	b.WriteString("WHATSAPP_FROM = os.environ.get('TWILIO_WHATSAPP_FROM', FROM_NUMBER)\n\n")
+
+	attempts, delayMs := ir.SMSRetryPolicy(app)
+	b.WriteString("# Retry policy, from the app's error handlers.\n")
+	fmt.Fprintf(&b, "ATTEMPTS = %d\n", attempts)
+	fmt.Fprintf(&b, "RETRY_DELAY_SECONDS = %g\n\n", float64(delayMs)/1000)
+
+	b.WriteString("# Twilio Content SIDs of the approved message templates.\n")
+	b.WriteString("SMS_TEMPLATES = {\n")
+	for _, tpl := range integ.Templates {
+		fmt.Fprintf(&b, "    '%s': os.environ.get('TWILIO_TEMPLATE_%s', ''),\n", tpl, strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(tpl)))
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString(`# In sandbox mode (TWILIO_SANDBOX=true) messages are recorded in outbox
+# instead of sent, so tests never text real phones.
+outbox: list = []
+
+_client: Optional[Client] = None
+
+
+def _get_client() -> Client:
+    global _client
+    if _client is None:
+`)
+	fmt.Fprintf(&b, "        _client = Client(os.environ.get('%s', ''), os.environ.get('%s', ''))\n", sidEnv, tokenEnv)
+	b.WriteString(`    return _client
+
+
+def _deliver(message: dict) -> None:
+    if os.environ.get('TWILIO_SANDBOX') == 'true':
+        outbox.append(message)
+        return
+
+    whatsapp = message['channel'] == 'whatsapp'
+    params = {
+        'from_': f'whatsapp:{WHATSAPP_FROM}' if whatsapp else FROM_NUMBER,
+        'to': f"whatsapp:{message['to']}" if whatsapp else message['to'],
+    }
+    if message.get('template'):
+        params['content_sid'] = SMS_TEMPLATES.get(message['template'], '')
+        params['content_variables'] = json.dumps(message.get('variables') or {})
+    else:
+        params['body'] = message['body']
+
+    for attempt in range(1, ATTEMPTS + 1):
+        try:
+            _get_client().messages.create(**params)
+            return
+        except Exception:
+            if attempt == ATTEMPTS:
+                raise
+            time.sleep(RETRY_DELAY_SECONDS)
+
+
+def send_sms(to: str, body: str) -> None:
+    _deliver({'channel': 'sms', 'to': to, 'body': body})
+
+
+def send_whatsapp(to: str, body: str) -> None:
+    _deliver({'channel': 'whatsapp', 'to': to, 'body': body})
+
+
+def send_template(channel: str, to: str, template: str, variables: Optional[dict] = None) -> None:
+    _deliver({'channel': channel, 'to': to, 'template': template, 'variables': variables or {}})
+`)
+
+	return b.String()
+}
+
+// generateSMSTests produces tests/test_sms_service.py, which exercises the
+// SMS service against its sandbox outbox.
+func generateSMSTests() string {
+	return `# Generated by Human compiler — do not edit
+import os
+
+os.environ['TWILIO_SANDBOX'] = 'true'
+
+from services import sms_service
+
+
+def setup_function():
+    sms_service.outbox.clear()
+
+
+def test_records_sms_instead_of_sending():
+    sms_service.send_sms('+15555550100', 'Your order has shipped')
+    assert sms_service.outbox == [{'channel': 'sms', 'to': '+15555550100', 'body': 'Your order has shipped'}]
+
+
+def test_records_whatsapp_messages():
+    sms_service.send_whatsapp('+15555550100', 'Hello')
+    assert sms_service.outbox[0]['channel'] == 'whatsapp'
+
+
+def test_records_template_messages():
+    sms_service.send_template('sms', '+15555550100', 'reminder', {'name': 'Ada'})
+    assert sms_service.outbox[0]['template'] == 'reminder'
+    assert sms_service.outbox[0]['variables'] == {'name': 'Ada'}
+`
+}
+
+// writeSMSCall emits the sms_service call for a "send SMS ..." step. The
+// recipient is the endpoint's phone param, else the payload's phone field.
+// The service is synchronous, like the route functions that call it.
+func writeSMSCall(sb *strings.Builder, step *ir.Action, api *ir.Endpoint) {
+	msg, _ := ir.ParseSMSStep(step.Text)
+	to := ""
+	if param := ir.PhoneParam(api); param != "" {
+		to = "payload." + toSnakeCase(param)
+	} else if len(api.Params) > 0 {
+		to = "getattr(payload, 'phone', '')"
+	}
+	if to == "" {
+		sb.WriteString("    # TODO: no recipient for the SMS — accept a phone param\n")
+		return
+	}
+	body := msg.Body
+	if body == "" {
+		body = "Action completed"
+	}
+	body = strings.ReplaceAll(body, "'", "\\'")
+	switch {
+	case msg.Template != "":
+		sb.WriteString("    from services.sms_service import send_template\n")
+		fmt.Fprintf(sb, "    send_template('%s', %s, '%s')\n", msg.Channel, to, strings.ReplaceAll(msg.Template, "'", "\\'"))
+	case msg.Channel == "whatsapp":
+		sb.WriteString("    from services.sms_service import send_whatsapp\n")
+		fmt.Fprintf(sb, "    send_whatsapp(%s, '%s')\n", to, body)
+	default:
+		sb.WriteString("    from services.sms_service import send_sms\n")
+		fmt.Fprintf(sb, "    send_sms(%s, '%s')\n", to, body)
+	}
+}
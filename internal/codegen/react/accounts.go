@@ -0,0 +1,292 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeProfileType writes the profile the account endpoints respond with:
+// the user without their password.
+func writeProfileType(b *strings.Builder, app *ir.Application) {
+	fmt.Fprintf(b, "\nexport type Profile = Omit<%s, 'password'>;\n", app.Accounts.Model)
+}
+
+// writeAccountFunction writes the client function of an endpoint the
+// accounts block added. A profile's fields are typed as the users' model
+// types them, with an optional field left empty sent as null.
+func writeAccountFunction(b *strings.Builder, ep *ir.Endpoint, app *ir.Application) {
+	fn := toCamelCase(ep.Name)
+	method := httpMethod(ep.Name)
+	path := apiPath(ep.Name)
+	switch ep.Account {
+	case ir.AccountProfile:
+		fmt.Fprintf(b, "export async function %s() {\n", fn)
+		fmt.Fprintf(b, "  return request<Profile>('%s', '%s');\n", method, path)
+	case ir.AccountUpdateProfile:
+		var fields []string
+		for _, f := range ir.ProfileFields(app) {
+			fields = append(fields, fmt.Sprintf("%s: %s", f.Name, profileFieldType(f)))
+		}
+		fmt.Fprintf(b, "export async function %s(params: { %s }) {\n", fn, strings.Join(fields, "; "))
+		fmt.Fprintf(b, "  return request<Profile>('%s', '%s', params);\n", method, path)
+	default:
+		var fields []string
+		for _, p := range ep.Params {
+			fields = append(fields, p.Name+": string")
+		}
+		fmt.Fprintf(b, "export async function %s(params: { %s }) {\n", fn, strings.Join(fields, "; "))
+		fmt.Fprintf(b, "  return request<void>('%s', '%s', params);\n", method, path)
+	}
+	b.WriteString("}\n")
+}
+
+// profileFieldType returns the type a profile field is sent as.
+func profileFieldType(f *ir.DataField) string {
+	t := tsType(f.Type)
+	if f.Type == "boolean" || f.Required {
+		return t
+	}
+	return t + " | null"
+}
+
+// profileFieldValue returns the expression reading a profile field from
+// the profile form's data.
+func profileFieldValue(f *ir.DataField) string {
+	get := fmt.Sprintf("fd.get('%s')", f.Name)
+	switch {
+	case f.Type == "boolean":
+		return fmt.Sprintf("fd.has('%s')", f.Name)
+	case tsType(f.Type) == "number" && f.Required:
+		return "Number(" + get + ")"
+	case tsType(f.Type) == "number":
+		return fmt.Sprintf("%s ? Number(%s) : null", get, get)
+	case f.Required:
+		return "String(" + get + " ?? '')"
+	}
+	return "String(" + get + " ?? '') || null"
+}
+
+// generateAccountPage produces the account page the accounts block added:
+// a form for the user's profile, one changing their password, and a
+// danger zone deleting their account. Deleting asks again in a dialog,
+// and only goes ahead once the user has typed DeleteConfirmation and
+// their password; they are then signed out.
+func generateAccountPage(page *ir.Page, app *ir.Application) string {
+	var b strings.Builder
+	ctx := &pageContext{app: app, appName: app.Name}
+	profile := ir.AccountEndpoint(app, ir.AccountProfile)
+	update := ir.AccountEndpoint(app, ir.AccountUpdateProfile)
+	password := ir.AccountEndpoint(app, ir.AccountChangePassword)
+	remove := ir.AccountEndpoint(app, ir.AccountDelete)
+	showsProfile := profile != nil && update != nil
+
+	var hooks, imports []string
+	if showsProfile {
+		hooks = append(hooks, "useState", "useEffect")
+		imports = append(imports, toCamelCase(profile.Name), toCamelCase(update.Name))
+	} else {
+		hooks = append(hooks, "useState")
+	}
+	hooks = append(hooks, "type FormEvent")
+	if password != nil {
+		imports = append(imports, toCamelCase(password.Name))
+	}
+	if remove != nil {
+		imports = append(imports, toCamelCase(remove.Name))
+	}
+	imports = append(imports, "errorMessage")
+	if showsProfile {
+		imports = append(imports, "type Profile")
+	}
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	fmt.Fprintf(&b, "import { %s } from 'react';\n", strings.Join(hooks, ", "))
+	if remove != nil {
+		b.WriteString("import { useNavigate } from 'react-router-dom';\n")
+		if app.Auth != nil {
+			b.WriteString("import { useAuth } from '../contexts/AuthContext';\n")
+		}
+	}
+	fmt.Fprintf(&b, "import { %s } from '../api/client';\n\n", strings.Join(imports, ", "))
+
+	fmt.Fprintf(&b, "export default function %sPage() {\n", page.Name)
+	if remove != nil {
+		b.WriteString("  const navigate = useNavigate();\n")
+		if app.Auth != nil {
+			b.WriteString("  const { logout } = useAuth();\n")
+		}
+	}
+
+	if showsProfile {
+		b.WriteString("  const [profile, setProfile] = useState<Profile | null>(null);\n")
+		b.WriteString("  const [loadError, setLoadError] = useState('');\n")
+		b.WriteString("  const [profileError, setProfileError] = useState('');\n")
+		b.WriteString("  const [profileSaved, setProfileSaved] = useState(false);\n\n")
+		b.WriteString("  useEffect(() => {\n")
+		fmt.Fprintf(&b, "    %s()\n", toCamelCase(profile.Name))
+		b.WriteString("      .then(res => setProfile(res.data))\n")
+		b.WriteString("      .catch(err => setLoadError(errorMessage(err, 'Could not load your profile')));\n")
+		b.WriteString("  }, []);\n\n")
+		b.WriteString("  async function saveProfile(ev: FormEvent<HTMLFormElement>) {\n")
+		b.WriteString("    ev.preventDefault();\n")
+		b.WriteString("    const fd = new FormData(ev.currentTarget);\n")
+		b.WriteString("    setProfileError('');\n")
+		b.WriteString("    setProfileSaved(false);\n")
+		b.WriteString("    try {\n")
+		fmt.Fprintf(&b, "      const res = await %s({\n", toCamelCase(update.Name))
+		for _, f := range ir.ProfileFields(app) {
+			fmt.Fprintf(&b, "        %s: %s,\n", f.Name, profileFieldValue(f))
+		}
+		b.WriteString("      });\n")
+		b.WriteString("      setProfile(res.data);\n")
+		b.WriteString("      setProfileSaved(true);\n")
+		b.WriteString("    } catch (err) {\n")
+		b.WriteString("      setProfileError(errorMessage(err, 'Could not save your profile'));\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n\n")
+	}
+
+	if password != nil {
+		b.WriteString("  const [passwordError, setPasswordError] = useState('');\n")
+		b.WriteString("  const [passwordChanged, setPasswordChanged] = useState(false);\n\n")
+		b.WriteString("  async function savePassword(ev: FormEvent<HTMLFormElement>) {\n")
+		b.WriteString("    ev.preventDefault();\n")
+		b.WriteString("    const form = ev.currentTarget;\n")
+		b.WriteString("    const fd = new FormData(form);\n")
+		b.WriteString("    setPasswordError('');\n")
+		b.WriteString("    setPasswordChanged(false);\n")
+		b.WriteString("    if (fd.get('new_password') !== fd.get('confirm_password')) {\n")
+		b.WriteString("      setPasswordError('The new passwords do not match');\n")
+		b.WriteString("      return;\n")
+		b.WriteString("    }\n")
+		b.WriteString("    try {\n")
+		fmt.Fprintf(&b, "      await %s({ current_password: String(fd.get('current_password') ?? ''), new_password: String(fd.get('new_password') ?? '') });\n", toCamelCase(password.Name))
+		b.WriteString("      form.reset();\n")
+		b.WriteString("      setPasswordChanged(true);\n")
+		b.WriteString("    } catch (err) {\n")
+		b.WriteString("      setPasswordError(errorMessage(err, 'Could not change your password'));\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n\n")
+	}
+
+	if remove != nil {
+		b.WriteString("  const [confirmingDelete, setConfirmingDelete] = useState(false);\n")
+		b.WriteString("  const [deleteConfirmation, setDeleteConfirmation] = useState('');\n")
+		b.WriteString("  const [deleteError, setDeleteError] = useState('');\n")
+		b.WriteString("  const [deleting, setDeleting] = useState(false);\n\n")
+		b.WriteString("  function cancelDelete() {\n")
+		b.WriteString("    setConfirmingDelete(false);\n")
+		b.WriteString("    setDeleteConfirmation('');\n")
+		b.WriteString("    setDeleteError('');\n")
+		b.WriteString("  }\n\n")
+		b.WriteString("  async function removeAccount(ev: FormEvent<HTMLFormElement>) {\n")
+		b.WriteString("    ev.preventDefault();\n")
+		b.WriteString("    const fd = new FormData(ev.currentTarget);\n")
+		b.WriteString("    setDeleteError('');\n")
+		b.WriteString("    setDeleting(true);\n")
+		b.WriteString("    try {\n")
+		fmt.Fprintf(&b, "      await %s({ password: String(fd.get('password') ?? '') });\n", toCamelCase(remove.Name))
+		if app.Auth != nil {
+			b.WriteString("      logout();\n")
+		} else {
+			b.WriteString("      localStorage.removeItem('token');\n")
+		}
+		b.WriteString("      navigate('/', { replace: true });\n")
+		b.WriteString("    } catch (err) {\n")
+		b.WriteString("      setDeleteError(errorMessage(err, 'Could not delete your account'));\n")
+		b.WriteString("      setDeleting(false);\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n\n")
+	}
+
+	b.WriteString("  return (\n")
+	fmt.Fprintf(&b, "    <div className=\"%s-page\">\n", toKebabCase(page.Name))
+	fmt.Fprintf(&b, "      <h1>%s</h1>\n", ir.FieldLabel(page.Name))
+
+	if showsProfile {
+		b.WriteString("      <section aria-labelledby=\"profile-title\">\n")
+		b.WriteString("        <h2 id=\"profile-title\">Profile</h2>\n")
+		b.WriteString("        {loadError && <p className=\"form-error\" role=\"alert\">{loadError}</p>}\n")
+		b.WriteString("        {profile && (\n")
+		b.WriteString("          <form key={profile.id} className=\"form\" aria-labelledby=\"profile-title\" onSubmit={saveProfile}>\n")
+		for _, f := range ir.ProfileFields(app) {
+			in := formInput{
+				id:        "profile-" + toKebabCase(toCamelCase(f.Name)),
+				name:      f.Name,
+				label:     ir.FieldLabel(f.Name),
+				inputType: inputType(f.Name, f),
+				required:  f.Required && f.Type != "boolean",
+				value:     editValue("profile", f),
+			}
+			if f.Type == "boolean" {
+				in.inputType = "checkbox"
+			}
+			writeFormFieldJSX(&b, "            ", in, ctx)
+		}
+		b.WriteString("            {profileError && <p className=\"form-error\" role=\"alert\">{profileError}</p>}\n")
+		b.WriteString("            {profileSaved && <p className=\"form-success\" role=\"status\">Your profile was saved</p>}\n")
+		b.WriteString("            <button type=\"submit\">Save profile</button>\n")
+		b.WriteString("          </form>\n")
+		b.WriteString("        )}\n")
+		b.WriteString("      </section>\n")
+	}
+
+	if password != nil {
+		b.WriteString("      <section aria-labelledby=\"password-title\">\n")
+		b.WriteString("        <h2 id=\"password-title\">Change password</h2>\n")
+		b.WriteString("        <form className=\"form\" aria-labelledby=\"password-title\" onSubmit={savePassword}>\n")
+		writePasswordFieldJSX(&b, "          ", "current-password", "current_password", "Current password", "current-password", false)
+		writePasswordFieldJSX(&b, "          ", "new-password", "new_password", "New password", "new-password", true)
+		writePasswordFieldJSX(&b, "          ", "confirm-password", "confirm_password", "Confirm new password", "new-password", true)
+		b.WriteString("          {passwordError && <p className=\"form-error\" role=\"alert\">{passwordError}</p>}\n")
+		b.WriteString("          {passwordChanged && <p className=\"form-success\" role=\"status\">Your password was changed</p>}\n")
+		b.WriteString("          <button type=\"submit\">Change password</button>\n")
+		b.WriteString("        </form>\n")
+		b.WriteString("      </section>\n")
+	}
+
+	if remove != nil {
+		b.WriteString("      <section className=\"danger-zone\" aria-labelledby=\"delete-title\">\n")
+		b.WriteString("        <h2 id=\"delete-title\">Delete account</h2>\n")
+		b.WriteString("        <p>Deleting your account removes it and everything in it for good.</p>\n")
+		b.WriteString("        <button type=\"button\" className=\"danger\" onClick={() => setConfirmingDelete(true)}>Delete account</button>\n")
+		b.WriteString("        {confirmingDelete && (\n")
+		b.WriteString("          <div className=\"confirm-dialog\" role=\"alertdialog\" aria-modal=\"true\" aria-labelledby=\"delete-confirm-title\" aria-describedby=\"delete-confirm-description\" onKeyDown={(ev) => ev.key === 'Escape' && cancelDelete()}>\n")
+		b.WriteString("            <h2 id=\"delete-confirm-title\">Delete your account?</h2>\n")
+		fmt.Fprintf(&b, "            <p id=\"delete-confirm-description\">This can't be undone. Type %s and enter your password to confirm.</p>\n", ir.DeleteConfirmation)
+		b.WriteString("            <form className=\"form\" aria-labelledby=\"delete-confirm-title\" onSubmit={removeAccount}>\n")
+		b.WriteString("              <div className=\"form-field\">\n")
+		fmt.Fprintf(&b, "                <label htmlFor=\"delete-confirmation\">Type %s to confirm</label>\n", ir.DeleteConfirmation)
+		b.WriteString("                <input id=\"delete-confirmation\" type=\"text\" autoComplete=\"off\" autoFocus value={deleteConfirmation} onChange={(ev) => setDeleteConfirmation(ev.target.value)} required />\n")
+		b.WriteString("              </div>\n")
+		writePasswordFieldJSX(&b, "              ", "delete-password", "password", "Password", "current-password", false)
+		b.WriteString("              {deleteError && <p className=\"form-error\" role=\"alert\">{deleteError}</p>}\n")
+		fmt.Fprintf(&b, "              <button type=\"submit\" className=\"danger\" disabled={deleteConfirmation !== '%s' || deleting}>Delete my account</button>\n", ir.DeleteConfirmation)
+		b.WriteString("              <button type=\"button\" onClick={cancelDelete}>Cancel</button>\n")
+		b.WriteString("            </form>\n")
+		b.WriteString("          </div>\n")
+		b.WriteString("        )}\n")
+		b.WriteString("      </section>\n")
+	}
+
+	b.WriteString("    </div>\n")
+	b.WriteString("  );\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// writePasswordFieldJSX renders a password input with its label, filled in
+// by password managers as autoComplete says. A new password must be
+// MinPasswordLength characters long.
+func writePasswordFieldJSX(b *strings.Builder, indent, id, name, label, autoComplete string, isNew bool) {
+	fmt.Fprintf(b, "%s<div className=\"form-field\">\n", indent)
+	fmt.Fprintf(b, "%s  <label htmlFor=\"%s\">%s</label>\n", indent, id, label)
+	minLength := ""
+	if isNew {
+		minLength = fmt.Sprintf(" minLength={%d}", ir.MinPasswordLength)
+	}
+	fmt.Fprintf(b, "%s  <input id=\"%s\" type=\"password\" name=\"%s\" autoComplete=\"%s\"%s required />\n", indent, id, name, autoComplete, minLength)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
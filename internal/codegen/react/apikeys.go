@@ -0,0 +1,154 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeAPIKeysClient appends the endpoints managing the signed-in user's
+// API keys to the API client. Creating a key answers with the key itself,
+// which is never shown again.
+func writeAPIKeysClient(b *strings.Builder) {
+	b.WriteString(`
+export interface ApiKey {
+  id: string;
+  name: string;
+  prefix: string;
+  scopes: string[];
+  lastUsedAt: string | null;
+  createdAt: string;
+}
+
+export async function listApiKeys() {
+  return request<ApiKey[]>('GET', '/api/keys');
+}
+
+export async function createApiKey(params: { name: string; scopes: string[] }) {
+  return request<ApiKey & { key: string }>('POST', '/api/keys', params);
+}
+
+export async function revokeApiKey(id: string) {
+  return request<void>('DELETE', ` + "`/api/keys/${encodeURIComponent(id)}`" + `);
+}
+`)
+}
+
+// generateAPIKeysPage produces the page users manage their API keys on:
+// their keys with when each was last used, a form creating a read or
+// read-and-write key that shows the new key once, and revoking a key.
+func generateAPIKeysPage(page *ir.Page, app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { useEffect, useState, type FormEvent } from 'react';\n")
+	b.WriteString("import { listApiKeys, createApiKey, revokeApiKey, errorMessage, type ApiKey } from '../api/client';\n\n")
+	locale := "undefined"
+	if l := ir.Locale(app); l != "" {
+		locale = "'" + l + "'"
+	}
+	fmt.Fprintf(&b, "const dateTime = new Intl.DateTimeFormat(%s, { dateStyle: 'medium', timeStyle: 'short' });\n\n", locale)
+
+	fmt.Fprintf(&b, "export default function %sPage() {\n", page.Name)
+	b.WriteString("  const [keys, setKeys] = useState<ApiKey[]>([]);\n")
+	b.WriteString("  const [loading, setLoading] = useState(true);\n")
+	b.WriteString("  const [error, setError] = useState('');\n")
+	b.WriteString("  const [created, setCreated] = useState<(ApiKey & { key: string }) | null>(null);\n")
+	b.WriteString("  const [creating, setCreating] = useState(false);\n\n")
+	b.WriteString("  useEffect(() => {\n")
+	b.WriteString("    listApiKeys()\n")
+	b.WriteString("      .then(res => setKeys(res.data))\n")
+	b.WriteString("      .catch(err => setError(errorMessage(err, 'Could not load your API keys')))\n")
+	b.WriteString("      .finally(() => setLoading(false));\n")
+	b.WriteString("  }, []);\n\n")
+
+	b.WriteString("  async function createKey(ev: FormEvent<HTMLFormElement>) {\n")
+	b.WriteString("    ev.preventDefault();\n")
+	b.WriteString("    const form = ev.currentTarget;\n")
+	b.WriteString("    const fd = new FormData(form);\n")
+	fmt.Fprintf(&b, "    const scopes = fd.get('write') ? ['%s', '%s'] : ['%s'];\n", ir.ScopeRead, ir.ScopeWrite, ir.ScopeRead)
+	b.WriteString("    setError('');\n")
+	b.WriteString("    setCreated(null);\n")
+	b.WriteString("    setCreating(true);\n")
+	b.WriteString("    try {\n")
+	b.WriteString("      const res = await createApiKey({ name: String(fd.get('name') ?? ''), scopes });\n")
+	b.WriteString("      form.reset();\n")
+	b.WriteString("      setCreated(res.data);\n")
+	b.WriteString("      setKeys(current => [res.data, ...current]);\n")
+	b.WriteString("    } catch (err) {\n")
+	b.WriteString("      setError(errorMessage(err, 'Could not create the API key'));\n")
+	b.WriteString("    } finally {\n")
+	b.WriteString("      setCreating(false);\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n\n")
+
+	b.WriteString("  async function revokeKey(apiKey: ApiKey) {\n")
+	b.WriteString("    if (!window.confirm(`Revoke ${apiKey.name}? Clients using it will stop working.`)) return;\n")
+	b.WriteString("    setError('');\n")
+	b.WriteString("    try {\n")
+	b.WriteString("      await revokeApiKey(apiKey.id);\n")
+	b.WriteString("      setKeys(current => current.filter(k => k.id !== apiKey.id));\n")
+	b.WriteString("      setCreated(current => (current?.id === apiKey.id ? null : current));\n")
+	b.WriteString("    } catch (err) {\n")
+	b.WriteString("      setError(errorMessage(err, 'Could not revoke the API key'));\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n\n")
+
+	b.WriteString("  return (\n")
+	fmt.Fprintf(&b, "    <div className=\"%s-page\">\n", toKebabCase(page.Name))
+	fmt.Fprintf(&b, "      <h1>%s</h1>\n", ir.FieldLabel(page.Name))
+	fmt.Fprintf(&b, "      <p>Scripts and other servers can call the API as you by sending a key in the <code>%s</code> header.</p>\n", ir.APIKeyHeader)
+	b.WriteString("      {error && <p className=\"form-error\" role=\"alert\">{error}</p>}\n")
+	b.WriteString("      <section aria-labelledby=\"create-key-title\">\n")
+	b.WriteString("        <h2 id=\"create-key-title\">Create a key</h2>\n")
+	b.WriteString("        <form className=\"form\" aria-labelledby=\"create-key-title\" onSubmit={createKey}>\n")
+	b.WriteString("          <div className=\"form-field\">\n")
+	b.WriteString("            <label htmlFor=\"key-name\">Name</label>\n")
+	b.WriteString("            <input id=\"key-name\" type=\"text\" name=\"name\" placeholder=\"Nightly export\" required />\n")
+	b.WriteString("          </div>\n")
+	b.WriteString("          <div className=\"form-field\">\n")
+	b.WriteString("            <label>\n")
+	b.WriteString("              <input type=\"checkbox\" name=\"write\" /> Can also create, change, and delete data\n")
+	b.WriteString("            </label>\n")
+	b.WriteString("          </div>\n")
+	b.WriteString("          {created && (\n")
+	b.WriteString("            <div className=\"form-success\" role=\"status\">\n")
+	b.WriteString("              <p>Copy the key for {created.name} now — it won't be shown again:</p>\n")
+	b.WriteString("              <input type=\"text\" readOnly value={created.key} aria-label=\"New API key\" onFocus={(ev) => ev.target.select()} />\n")
+	b.WriteString("            </div>\n")
+	b.WriteString("          )}\n")
+	b.WriteString("          <button type=\"submit\" disabled={creating}>{creating ? 'Creating…' : 'Create key'}</button>\n")
+	b.WriteString("        </form>\n")
+	b.WriteString("      </section>\n")
+	b.WriteString("      <section aria-labelledby=\"keys-title\">\n")
+	b.WriteString("        <h2 id=\"keys-title\">Your keys</h2>\n")
+	b.WriteString("        {loading ? (\n")
+	b.WriteString("          <p role=\"status\">Loading…</p>\n")
+	b.WriteString("        ) : keys.length === 0 ? (\n")
+	b.WriteString("          <p>You have no API keys yet.</p>\n")
+	b.WriteString("        ) : (\n")
+	b.WriteString("          <table>\n")
+	b.WriteString("            <thead>\n")
+	b.WriteString("              <tr><th scope=\"col\">Name</th><th scope=\"col\">Key</th><th scope=\"col\">Access</th><th scope=\"col\">Last used</th><th scope=\"col\">Created</th><th scope=\"col\" aria-label=\"Actions\" /></tr>\n")
+	b.WriteString("            </thead>\n")
+	b.WriteString("            <tbody>\n")
+	b.WriteString("              {keys.map(apiKey => (\n")
+	b.WriteString("                <tr key={apiKey.id}>\n")
+	b.WriteString("                  <td>{apiKey.name}</td>\n")
+	b.WriteString("                  <td><code>{apiKey.prefix}…</code></td>\n")
+	fmt.Fprintf(&b, "                  <td>{apiKey.scopes.includes('%s') ? 'Read and write' : 'Read only'}</td>\n", ir.ScopeWrite)
+	b.WriteString("                  <td>{apiKey.lastUsedAt ? dateTime.format(new Date(apiKey.lastUsedAt)) : 'Never'}</td>\n")
+	b.WriteString("                  <td>{dateTime.format(new Date(apiKey.createdAt))}</td>\n")
+	b.WriteString("                  <td><button type=\"button\" onClick={() => revokeKey(apiKey)}>Revoke</button></td>\n")
+	b.WriteString("                </tr>\n")
+	b.WriteString("              ))}\n")
+	b.WriteString("            </tbody>\n")
+	b.WriteString("          </table>\n")
+	b.WriteString("        )}\n")
+	b.WriteString("      </section>\n")
+	b.WriteString("    </div>\n")
+	b.WriteString("  );\n")
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeBillingClient appends the subscription billing endpoints to the API
+// client. Checkout and the billing portal answer with the Stripe page to
+// send the user to.
+func writeBillingClient(b *strings.Builder) {
+	b.WriteString(`
+export interface BillingSubscription {
+  plan: string | null;
+  subscription: { status: string } | null;
+}
+
+export async function getSubscription() {
+  return request<BillingSubscription>('GET', '/api/billing/subscription');
+}
+
+export async function startCheckout(plan: string) {
+  return request<{ url: string }>('POST', '/api/billing/checkout', { plan });
+}
+
+export async function openBillingPortal() {
+  return request<{ url: string }>('POST', '/api/billing/portal');
+}
+`)
+}
+
+// generatePricingPage produces the pricing page: a card for each plan with
+// its price, the signed-in user's current plan, subscribing to a paid plan
+// through Stripe Checkout, and managing a subscription in the billing
+// portal. Plans without a price are set up by contacting the team.
+func generatePricingPage(page *ir.Page, app *ir.Application) string {
+	var b strings.Builder
+	bill := app.Billing
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { useEffect, useState } from 'react';\n")
+	b.WriteString("import { Link, useSearchParams } from 'react-router-dom';\n")
+	if app.Auth != nil {
+		b.WriteString("import { useAuth } from '../contexts/AuthContext';\n")
+	}
+	b.WriteString("import { getSubscription, startCheckout, openBillingPortal, errorMessage } from '../api/client';\n\n")
+
+	b.WriteString("const PLANS: { name: string; price: number; interval: string | null; custom: boolean }[] = [\n")
+	for _, plan := range bill.Plans {
+		interval := "null"
+		if plan.Paid() {
+			interval = "'" + plan.Interval + "'"
+		}
+		fmt.Fprintf(&b, "  { name: '%s', price: %d, interval: %s, custom: %t },\n", plan.Name, plan.Price, interval, plan.Custom)
+	}
+	b.WriteString("];\n\n")
+	locale := "undefined"
+	if l := ir.Locale(app); l != "" {
+		locale = "'" + l + "'"
+	}
+	b.WriteString("// Prices are in cents\n")
+	fmt.Fprintf(&b, "const money = new Intl.NumberFormat(%s, { style: 'currency', currency: '%s' });\n\n", locale, strings.ToUpper(bill.Currency))
+
+	fmt.Fprintf(&b, "export default function %sPage() {\n", page.Name)
+	if app.Auth != nil {
+		b.WriteString("  const { isAuthenticated } = useAuth();\n")
+	} else {
+		b.WriteString("  const isAuthenticated = Boolean(localStorage.getItem('token'));\n")
+	}
+	b.WriteString("  const [params] = useSearchParams();\n")
+	b.WriteString("  const [current, setCurrent] = useState<string | null>(null);\n")
+	b.WriteString("  const [subscribed, setSubscribed] = useState(false);\n")
+	b.WriteString("  const [billingError, setBillingError] = useState('');\n")
+	b.WriteString("  const [pending, setPending] = useState('');\n\n")
+	b.WriteString("  useEffect(() => {\n")
+	b.WriteString("    if (!isAuthenticated) return;\n")
+	b.WriteString("    getSubscription()\n")
+	b.WriteString("      .then(res => {\n")
+	b.WriteString("        setCurrent(res.data.plan);\n")
+	b.WriteString("        setSubscribed(Boolean(res.data.subscription));\n")
+	b.WriteString("      })\n")
+	b.WriteString("      .catch(err => setBillingError(errorMessage(err, 'Could not load your plan')));\n")
+	b.WriteString("  }, [isAuthenticated]);\n\n")
+	b.WriteString("  // Checkout and the billing portal are Stripe's pages\n")
+	b.WriteString("  async function goTo(key: string, open: () => Promise<{ data: { url: string } }>, fallback: string) {\n")
+	b.WriteString("    setBillingError('');\n")
+	b.WriteString("    setPending(key);\n")
+	b.WriteString("    try {\n")
+	b.WriteString("      const res = await open();\n")
+	b.WriteString("      window.location.assign(res.data.url);\n")
+	b.WriteString("    } catch (err) {\n")
+	b.WriteString("      setBillingError(errorMessage(err, fallback));\n")
+	b.WriteString("      setPending('');\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n\n")
+
+	b.WriteString("  return (\n")
+	fmt.Fprintf(&b, "    <div className=\"%s-page\">\n", toKebabCase(page.Name))
+	fmt.Fprintf(&b, "      <h1>%s</h1>\n", ir.FieldLabel(page.Name))
+	b.WriteString("      {params.get('checkout') === 'success' && <p role=\"status\">Thanks for subscribing! Your plan will update in a moment.</p>}\n")
+	b.WriteString("      {billingError && <p className=\"form-error\" role=\"alert\">{billingError}</p>}\n")
+	b.WriteString("      <div className=\"plans\">\n")
+	b.WriteString("        {PLANS.map(plan => (\n")
+	b.WriteString("          <section key={plan.name} className={plan.name === current ? 'plan current' : 'plan'} aria-labelledby={`plan-${plan.name}`}>\n")
+	b.WriteString("            <h2 id={`plan-${plan.name}`}>{plan.name}</h2>\n")
+	b.WriteString("            <p className=\"price\">\n")
+	b.WriteString("              {plan.custom ? 'Custom pricing' : plan.price > 0 ? `${money.format(plan.price / 100)} / ${plan.interval}` : 'Free'}\n")
+	b.WriteString("            </p>\n")
+	b.WriteString("            {plan.name === current ? (\n")
+	b.WriteString("              <p className=\"badge\">Current plan</p>\n")
+	b.WriteString("            ) : plan.custom ? (\n")
+	b.WriteString("              <p>Contact us to set up this plan.</p>\n")
+	b.WriteString("            ) : !isAuthenticated ? (\n")
+	b.WriteString("              <Link to=\"/login\">Sign in to subscribe</Link>\n")
+	b.WriteString("            ) : plan.price > 0 && !subscribed ? (\n")
+	b.WriteString("              <button type=\"button\" disabled={pending !== ''} onClick={() => goTo(plan.name, () => startCheckout(plan.name), 'Could not start checkout')}>\n")
+	b.WriteString("                {pending === plan.name ? 'Redirecting…' : `Subscribe to ${plan.name}`}\n")
+	b.WriteString("              </button>\n")
+	b.WriteString("            ) : null}\n")
+	b.WriteString("          </section>\n")
+	b.WriteString("        ))}\n")
+	b.WriteString("      </div>\n")
+	b.WriteString("      {subscribed && (\n")
+	b.WriteString("        <button type=\"button\" disabled={pending !== ''} onClick={() => goTo('portal', openBillingPortal, 'Could not open the billing portal')}>\n")
+	b.WriteString("          {pending === 'portal' ? 'Redirecting…' : 'Manage billing'}\n")
+	b.WriteString("        </button>\n")
+	b.WriteString("      )}\n")
+	b.WriteString("    </div>\n")
+	b.WriteString("  );\n")
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package react
+
+import "strings"
+
+// writeCalendarClient appends the calendar feed URLs to the API client. The
+// feeds are fetched by the browser or a calendar app, not by request().
+func writeCalendarClient(b *strings.Builder) {
+	b.WriteString(`
+// calendarUrl returns a calendar's ICS feed, or one record's .ics download.
+export function calendarUrl(feed: string, id?: string): string {
+  const path = id ? ` + "`${feed}/${encodeURIComponent(id)}`" + ` : feed;
+  return new URL(` + "`${API_BASE_URL}/api/calendar/${path}.ics`" + `, window.location.href).href;
+}
+`)
+}
+
+// generateAddToCalendar produces src/components/AddToCalendar.tsx. Given a
+// record id it downloads that record's .ics file; without one it
+// subscribes to the whole feed through a webcal:// link.
+func generateAddToCalendar() string {
+	return `// Generated by Human compiler — do not edit
+
+import { calendarUrl } from '../api/client';
+
+interface AddToCalendarProps {
+  feed: string;
+  id?: string;
+}
+
+export default function AddToCalendar({ feed, id }: AddToCalendarProps) {
+  const url = calendarUrl(feed, id);
+  if (id) {
+    return (
+      <a className="add-to-calendar" href={url} download>
+        Add to calendar
+      </a>
+    );
+  }
+  return (
+    <a className="add-to-calendar" href={url.replace(/^https?:/, 'webcal:')}>
+      Subscribe to calendar
+    </a>
+  );
+}
+`
+}
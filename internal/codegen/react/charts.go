@@ -0,0 +1,173 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeChartClient appends the chart endpoint to the API client.
+func writeChartClient(b *strings.Builder) {
+	b.WriteString(`
+export interface ChartPoint {
+  label: string;
+  value: number;
+}
+
+// fetchChart loads a chart's points from its aggregate endpoint.
+export async function fetchChart(source: string) {
+  return request<ChartPoint[]>('GET', ` + "`/api/charts/${source}`" + `);
+}
+`)
+}
+
+// generateDataChart produces src/components/DataChart.tsx: a Recharts bar,
+// line, or pie chart of the points an aggregate endpoint returns, drawn in
+// the theme's primary color.
+func generateDataChart() string {
+	return `// Generated by Human compiler — do not edit
+
+import { useEffect, useState } from 'react';
+import {
+  Bar,
+  BarChart,
+  CartesianGrid,
+  Cell,
+  Line,
+  LineChart,
+  Pie,
+  PieChart,
+  ResponsiveContainer,
+  Tooltip,
+  XAxis,
+  YAxis,
+} from 'recharts';
+import { fetchChart, type ChartPoint } from '../api/client';
+
+interface DataChartProps {
+  kind: 'bar' | 'line' | 'pie';
+  title: string;
+  source: string;
+}
+
+const PALETTE = ['#6366f1', '#22c55e', '#f59e0b', '#ef4444', '#06b6d4', '#a855f7', '#ec4899', '#84cc16'];
+
+// The theme's primary color, for bars, lines, and the first slice.
+function primaryColor(): string {
+  return getComputedStyle(document.documentElement).getPropertyValue('--color-primary').trim() || PALETTE[0];
+}
+
+export default function DataChart({ kind, title, source }: DataChartProps) {
+  const [points, setPoints] = useState<ChartPoint[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    fetchChart(source)
+      .then((res) => {
+        if (res.error) {
+          setError(res.error);
+        } else {
+          setPoints(res.data ?? []);
+        }
+      })
+      .catch(() => setError('Could not load the chart'))
+      .finally(() => setLoading(false));
+  }, [source]);
+
+  const color = primaryColor();
+  let chart;
+  if (kind === 'line') {
+    chart = (
+      <LineChart data={points}>
+        <CartesianGrid strokeDasharray="3 3" />
+        <XAxis dataKey="label" />
+        <YAxis />
+        <Tooltip />
+        <Line type="monotone" dataKey="value" name={title} stroke={color} strokeWidth={2} />
+      </LineChart>
+    );
+  } else if (kind === 'pie') {
+    chart = (
+      <PieChart>
+        <Tooltip />
+        <Pie data={points} dataKey="value" nameKey="label" label>
+          {points.map((point, i) => (
+            <Cell key={point.label} fill={i === 0 ? color : PALETTE[i % PALETTE.length]} />
+          ))}
+        </Pie>
+      </PieChart>
+    );
+  } else {
+    chart = (
+      <BarChart data={points}>
+        <CartesianGrid strokeDasharray="3 3" />
+        <XAxis dataKey="label" />
+        <YAxis />
+        <Tooltip />
+        <Bar dataKey="value" name={title} fill={color} />
+      </BarChart>
+    );
+  }
+
+  return (
+    <figure className="chart">
+      <figcaption className="chart-title">{title}</figcaption>
+      {loading ? (
+        <p className="chart-status">Loading...</p>
+      ) : error ? (
+        <p className="chart-status" role="alert">{error}</p>
+      ) : points.length === 0 ? (
+        <p className="chart-status">No data yet</p>
+      ) : (
+        <ResponsiveContainer width="100%" height={300}>
+          {chart}
+        </ResponsiveContainer>
+      )}
+    </figure>
+  );
+}
+`
+}
+
+// chartAttr escapes a chart title for an attribute value.
+var chartAttr = strings.NewReplacer("&", "&amp;", "\"", "&quot;", "<", "&lt;", ">", "&gt;", "{", "&#123;", "}", "&#125;")
+
+// writeChartJSX emits the chart a "show a bar chart of ..." action draws.
+func writeChartJSX(b *strings.Builder, c *ir.Chart, indent string) {
+	fmt.Fprintf(b, "%s<DataChart kind=\"%s\" title=\"%s\" source=\"%s\" />\n", indent, c.Kind, chartAttr.Replace(c.Title), c.Slug)
+}
+
+// pageHasChart reports whether the page draws a chart from an aggregate
+// endpoint.
+func pageHasChart(page *ir.Page, app *ir.Application) bool {
+	for _, a := range page.Content {
+		if ir.ChartFor(app, a) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReports reports whether any endpoint serves an aggregate.
+func hasReports(app *ir.Application) bool {
+	for _, ep := range app.APIs {
+		if ep.Aggregate != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// writeReportType declares the rows report endpoints return.
+func writeReportType(b *strings.Builder) {
+	b.WriteString(`
+// ReportRow is one group of a report: its label and the count, sum,
+// average, minimum, or maximum of its records.
+export interface ReportRow {
+  label: string;
+  value: number;
+}
+`)
+}
@@ -20,6 +20,11 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 		}
 	}
 
+	tips, tipProp := componentTooltips(comp, app)
+	if len(tips) > 0 {
+		b.WriteString("import Tooltip from './Tooltip';\n")
+	}
+
 	b.WriteString("\n")
 
 	// Props interface
@@ -59,8 +64,9 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 		propsMap[p.Name] = p.Type
 	}
 	ctx := &pageContext{
-		app:   app,
-		props: propsMap,
+		app:      app,
+		props:    propsMap,
+		tooltips: tips,
 	}
 
 	// Return JSX
@@ -71,8 +77,17 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 		fmt.Fprintf(&b, "    <div className=\"%s\">\n", toKebabCase(comp.Name))
 	}
 
+	// Hovering anywhere over the component shows its tooltip
+	indent := "      "
+	if len(tips) > 0 {
+		fmt.Fprintf(&b, "%s<Tooltip %s>\n", indent, tooltipText(tips[0], tipProp))
+		indent += "  "
+	}
 	for _, a := range comp.Content {
-		writePageAction(&b, a, "      ", ctx)
+		writePageAction(&b, a, indent, ctx)
+	}
+	if len(tips) > 0 {
+		b.WriteString("      </Tooltip>\n")
 	}
 
 	b.WriteString("    </div>\n")
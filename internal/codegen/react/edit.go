@@ -0,0 +1,213 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeConflictError writes the error an update made to a record someone
+// else has since saved fails with, carrying the record as it is now.
+func writeConflictError(b *strings.Builder) {
+	b.WriteString(`
+export class ConflictError extends ApiError {
+  readonly current: unknown;
+
+  constructor(message: string, current: unknown) {
+    super(409, message);
+    this.name = 'ConflictError';
+    this.current = current;
+  }
+}
+`)
+}
+
+// writeVersionedUpdateFunction writes the client function of an update to
+// a record protected against conflicting edits: it takes the record's
+// fields as the model types them, and sends the version of the record the
+// changes were made to in an If-Match header.
+func writeVersionedUpdateFunction(b *strings.Builder, ep *ir.Endpoint, m *ir.DataModel) {
+	paramFields := make([]string, len(ep.Params))
+	for i, p := range ep.Params {
+		paramType := "string"
+		if f := m.FieldNamed(p.Name); f != nil && f.Type != "enum" && f.Type != "json" {
+			paramType = tsType(f.Type)
+		}
+		paramFields[i] = fmt.Sprintf("%s: %s", sanitizeParamName(p.Name), paramType)
+	}
+	fmt.Fprintf(b, "export async function %s(params: { %s }, version: number) {\n", toCamelCase(ep.Name), strings.Join(paramFields, "; "))
+	fmt.Fprintf(b, "  return request<%s>('%s', '%s', params as unknown as Record<string, unknown>, { 'If-Match': `\"${version}\"` });\n",
+		m.Name, httpMethod(ep.Name), apiPath(ep.Name))
+	b.WriteString("}\n")
+}
+
+// editImports returns what a page's edit form uses from the API client.
+func editImports(e *ir.EditForm) []string {
+	imports := []string{toCamelCase(e.Endpoint.Name)}
+	if e.Versioned {
+		imports = append(imports, "ConflictError")
+	}
+	return imports
+}
+
+// writeEditState declares the state of the form editing the record a
+// detail route's page shows, and the function saving its changes. Saved
+// changes replace the record shown. A versioned record's changes are made
+// to the version the form was filled in from; when someone else saved
+// first, the changes are held until the user keeps them, saving them over
+// the record as it is now, or uses the saved record instead.
+func writeEditState(b *strings.Builder, ctx *pageContext) {
+	e := ctx.edit
+	fn := toCamelCase(e.Endpoint.Name)
+	b.WriteString("\n  const [editError, setEditError] = useState('');\n")
+	if e.Versioned {
+		fmt.Fprintf(b, "  const [conflict, setConflict] = useState<{ current: %s; changes: Parameters<typeof %s>[0] } | null>(null);\n", e.Model.Name, fn)
+	}
+	label := strings.ToLower(ir.FieldLabel(e.Model.Name))
+	if e.Versioned {
+		fmt.Fprintf(b, "\n  async function save%s(changes: Parameters<typeof %s>[0], version: number) {\n", e.Model.Name, fn)
+	} else {
+		fmt.Fprintf(b, "\n  async function save%s(changes: Parameters<typeof %s>[0]) {\n", e.Model.Name, fn)
+	}
+	b.WriteString("    setEditError('');\n")
+	b.WriteString("    try {\n")
+	if e.Versioned {
+		fmt.Fprintf(b, "      const res = await %s(changes, version);\n", fn)
+		b.WriteString("      setConflict(null);\n")
+	} else {
+		fmt.Fprintf(b, "      const res = await %s(changes);\n", fn)
+	}
+	fmt.Fprintf(b, "      set%s(res.data);\n", e.Model.Name)
+	b.WriteString("    } catch (err) {\n")
+	if e.Versioned {
+		b.WriteString("      if (err instanceof ConflictError) {\n")
+		fmt.Fprintf(b, "        setConflict({ current: err.current as %s, changes });\n", e.Model.Name)
+		b.WriteString("      } else {\n")
+		fmt.Fprintf(b, "        setEditError(errorMessage(err, 'Could not save the %s'));\n", label)
+		b.WriteString("      }\n")
+	} else {
+		fmt.Fprintf(b, "      setEditError(errorMessage(err, 'Could not save the %s'));\n", label)
+	}
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+}
+
+// writeEditFormJSX renders the form editing the record a detail route's
+// page shows, filled in with it, and filled in again with the record as
+// saved. A versioned record's form is followed by the prompt shown when
+// someone else saved it first.
+func writeEditFormJSX(b *strings.Builder, indent string, ctx *pageContext) {
+	e := ctx.edit
+	v := recordVar(ctx)
+	label := strings.ToLower(ir.FieldLabel(e.Model.Name))
+	key := v + ".id"
+	if e.Versioned {
+		key = v + "." + ir.VersionColumn
+	}
+	fmt.Fprintf(b, "%s{%s && (\n", indent, v)
+	fmt.Fprintf(b, "%s  <form key={%s} className=\"form edit-form\" aria-label=\"Edit %s\" onSubmit={(ev) => {\n", indent, key, label)
+	fmt.Fprintf(b, "%s    ev.preventDefault();\n", indent)
+	fmt.Fprintf(b, "%s    const fd = new FormData(ev.currentTarget);\n", indent)
+	fmt.Fprintf(b, "%s    save%s({\n", indent, e.Model.Name)
+	for _, p := range e.Endpoint.Params {
+		name := sanitizeParamName(p.Name)
+		f := editField(e, p.Name)
+		switch {
+		case p.Name == e.Param:
+			fmt.Fprintf(b, "%s      %s: %s.id,\n", indent, name, v)
+		case f != nil && f.Type == "boolean" && e.Versioned:
+			fmt.Fprintf(b, "%s      %s: fd.has('%s'),\n", indent, name, name)
+		case f != nil && f.Type == "boolean":
+			fmt.Fprintf(b, "%s      %s: String(fd.has('%s')),\n", indent, name, name)
+		case f != nil && tsType(f.Type) == "number" && e.Versioned:
+			fmt.Fprintf(b, "%s      %s: Number(fd.get('%s')),\n", indent, name, name)
+		default:
+			fmt.Fprintf(b, "%s      %s: String(fd.get('%s') ?? ''),\n", indent, name, name)
+		}
+	}
+	if e.Versioned {
+		fmt.Fprintf(b, "%s    }, %s.%s);\n", indent, v, ir.VersionColumn)
+	} else {
+		fmt.Fprintf(b, "%s    });\n", indent)
+	}
+	fmt.Fprintf(b, "%s  }}>\n", indent)
+	formID := ctx.uniqueID(toKebabCase(e.Model.Name) + "-edit")
+	for _, f := range e.Fields {
+		in := formInput{
+			id:        formID + "-" + toKebabCase(toCamelCase(f.Name)),
+			name:      sanitizeParamName(paramFor(e, f)),
+			label:     ir.FieldLabel(f.Name),
+			inputType: inputType(f.Name, f),
+			required:  f.Required && f.Type != "boolean",
+			value:     editValue(v, f),
+		}
+		if f.Type == "boolean" {
+			in.inputType = "checkbox"
+		}
+		if in.inputType == "datetime-local" {
+			if local := localTimeValue(v+"."+f.Name, ctx); local != "" {
+				in.value = local
+			}
+		}
+		writeFormFieldJSX(b, indent+"    ", in, ctx)
+	}
+	fmt.Fprintf(b, "%s    {editError && <p className=\"form-error\" role=\"alert\">{editError}</p>}\n", indent)
+	fmt.Fprintf(b, "%s    <button type=\"submit\">Save</button>\n", indent)
+	fmt.Fprintf(b, "%s  </form>\n", indent)
+	fmt.Fprintf(b, "%s)}\n", indent)
+	if !e.Versioned {
+		return
+	}
+	fmt.Fprintf(b, "%s{conflict && (\n", indent)
+	fmt.Fprintf(b, "%s  <div className=\"conflict-dialog\" role=\"alertdialog\" aria-modal=\"true\" aria-labelledby=\"conflict-title\" aria-describedby=\"conflict-description\">\n", indent)
+	fmt.Fprintf(b, "%s    <h2 id=\"conflict-title\">This %s changed while you were editing</h2>\n", indent, label)
+	fmt.Fprintf(b, "%s    <p id=\"conflict-description\">Someone else saved the %s after you opened it. Keep your changes to save them over theirs, or use their version and discard yours.</p>\n", indent, label)
+	fmt.Fprintf(b, "%s    <button autoFocus onClick={() => save%s(conflict.changes, conflict.current.%s)}>Keep my changes</button>\n", indent, e.Model.Name, ir.VersionColumn)
+	fmt.Fprintf(b, "%s    <button onClick={() => { set%s(conflict.current); setConflict(null); }}>Use their version</button>\n", indent, e.Model.Name)
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s)}\n", indent)
+}
+
+// recordVar returns the variable holding the record a detail route's page
+// shows.
+func recordVar(ctx *pageContext) string {
+	m := ctx.record.Model
+	return strings.ToLower(m[:1]) + m[1:]
+}
+
+// editField returns the field of an edit form an endpoint parameter fills.
+func editField(e *ir.EditForm, param string) *ir.DataField {
+	for _, f := range e.Fields {
+		if paramFor(e, f) == param {
+			return f
+		}
+	}
+	return nil
+}
+
+// paramFor returns the endpoint parameter a field of an edit form fills.
+func paramFor(e *ir.EditForm, f *ir.DataField) string {
+	for _, p := range e.Endpoint.Params {
+		if strings.EqualFold(p.Name, f.Name) {
+			return p.Name
+		}
+	}
+	return f.Name
+}
+
+// editValue returns the expression an edit form's input for f starts
+// filled in with.
+func editValue(v string, f *ir.DataField) string {
+	ref := v + "." + f.Name
+	switch inputType(f.Name, f) {
+	case "date":
+		return ref + "?.slice(0, 10) ?? ''"
+	case "datetime-local":
+		return ref + "?.slice(0, 16) ?? ''"
+	}
+	if f.Type == "boolean" {
+		return ref
+	}
+	return ref + " ?? ''"
+}
@@ -0,0 +1,85 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+)
+
+// generateErrorBoundary produces src/components/ErrorBoundary.tsx: it
+// catches errors thrown while rendering the pages below it and shows what
+// went wrong with a button that renders them again.
+func generateErrorBoundary() string {
+	return `// Generated by Human compiler — do not edit
+
+import { Component, type ErrorInfo, type ReactNode } from 'react';
+
+interface ErrorBoundaryProps {
+  children: ReactNode;
+}
+
+interface ErrorBoundaryState {
+  error: Error | null;
+}
+
+export default class ErrorBoundary extends Component<ErrorBoundaryProps, ErrorBoundaryState> {
+  state: ErrorBoundaryState = { error: null };
+
+  static getDerivedStateFromError(error: Error): ErrorBoundaryState {
+    return { error };
+  }
+
+  componentDidCatch(error: Error, info: ErrorInfo) {
+    console.error(error, info.componentStack);
+  }
+
+  render() {
+    if (this.state.error) {
+      return (
+        <div className="error-state" role="alert">
+          <h1>Something went wrong</h1>
+          <p>{this.state.error.message}</p>
+          <button onClick={() => this.setState({ error: null })}>Try again</button>
+        </div>
+      );
+    }
+    return this.props.children;
+  }
+}
+`
+}
+
+// writeLoadErrorState declares why loading the page's data failed and the
+// attempt its effects re-run on, and the retry handler bumping it.
+func writeLoadErrorState(b *strings.Builder) {
+	b.WriteString("  const [loadError, setLoadError] = useState('');\n")
+	b.WriteString("  const [attempt, setAttempt] = useState(0);\n")
+}
+
+// writeRetry emits the handler the load error's button calls: it clears
+// the error and loads the page's data again.
+func writeRetry(b *strings.Builder, loading bool) {
+	b.WriteString("\n  function retry() {\n")
+	b.WriteString("    setLoadError('');\n")
+	if loading {
+		b.WriteString("    setLoading(true);\n")
+	}
+	b.WriteString("    setAttempt(n => n + 1);\n")
+	b.WriteString("  }\n")
+}
+
+// loadFailed returns the statement recording why a load failed: the
+// server's message, else the page's.
+func loadFailed(ctx *pageContext) string {
+	return fmt.Sprintf("setLoadError(errorMessage(err, '%s'))", strings.ReplaceAll(ctx.loadError, "'", "\\'"))
+}
+
+// writeLoadErrorJSX renders why loading the page's data failed, with a
+// button to try again.
+func writeLoadErrorJSX(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s{loadError && (\n", indent)
+	fmt.Fprintf(b, "%s  <div className=\"error-state\" role=\"alert\">\n", indent)
+	fmt.Fprintf(b, "%s    <p>{loadError}</p>\n", indent)
+	fmt.Fprintf(b, "%s    <button onClick={retry}>Try again</button>\n", indent)
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s)}\n", indent)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/codegen/themes"
 	"github.com/barun-bash/human/internal/ir"
 )
@@ -31,20 +32,43 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 
 	// Generate and write each file
 	files := map[string]string{
-		filepath.Join(outputDir, "index.html"):                  generateIndexHTML(app),
-		filepath.Join(outputDir, "src", "main.tsx"):             generateMainTsx(),
-		filepath.Join(outputDir, "src", "index.css"):            generateIndexCSS(app),
-		filepath.Join(outputDir, "src", "vite-env.d.ts"):        generateViteEnvDts(),
-		filepath.Join(outputDir, "src", "types", "models.ts"):   generateTypes(app),
-		filepath.Join(outputDir, "src", "api", "client.ts"):     generateAPIClient(app),
-		filepath.Join(outputDir, "src", "App.tsx"):               generateApp(app),
+		filepath.Join(outputDir, "index.html"):                generateIndexHTML(app),
+		filepath.Join(outputDir, "src", "main.tsx"):           generateMainTsx(app),
+		filepath.Join(outputDir, "src", "index.css"):          generateIndexCSS(app),
+		filepath.Join(outputDir, "src", "vite-env.d.ts"):      generateViteEnvDts(),
+		filepath.Join(outputDir, "src", "types", "models.ts"): generateTypes(app),
+		filepath.Join(outputDir, "src", "api", "client.ts"):   generateAPIClient(app),
+		filepath.Join(outputDir, "src", "App.tsx"):            generateApp(app),
+	}
+
+	// The API of the environment the build targets
+	if env := ir.TargetEnvironment(app); env != nil {
+		files[filepath.Join(outputDir, ".env.production")] = generateEnvProduction(env, app)
+	}
+
+	// Numbers shown in the theme's locale
+	if ir.Locale(app) != "" {
+		if err := os.MkdirAll(filepath.Join(outputDir, "src", "utils"), 0755); err != nil {
+			return fmt.Errorf("creating utils directory: %w", err)
+		}
+		files[filepath.Join(outputDir, "src", "utils", "format.ts")] = generateFormat(app)
 	}
 
 	// Generate page files
+	// With CSS modules, the app's styles are all scoped by its one module
+	styling := ir.Styling(app)
+	var moduleCSS []string
 	for _, page := range app.Pages {
 		name := page.Name + "Page"
 		path := filepath.Join(outputDir, "src", "pages", name+".tsx")
 		files[path] = generatePage(page, app)
+		if ir.ResponsiveFor(page) != nil {
+			if styling == ir.StylingCSSModules {
+				moduleCSS = append(moduleCSS, themes.ResponsiveCSS(app.Theme, pageLayout(page, app)))
+			} else {
+				files[filepath.Join(outputDir, "src", "pages", name+".css")] = generatePageStyles(page, app)
+			}
+		}
 	}
 
 	// Generate component files
@@ -62,6 +86,57 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		files[filepath.Join(outputDir, "src", "components", "ProtectedRoute.tsx")] = generateProtectedRoute()
 	}
 
+	// Generate experiment variant routing
+	if len(app.Experiments) > 0 {
+		files[filepath.Join(outputDir, "src", "experiments", "client.ts")] = generateExperimentsClient()
+		files[filepath.Join(outputDir, "src", "experiments", "ExperimentRoute.tsx")] = generateExperimentRoute()
+	}
+
+	// Notifications dropdown when in-app notifications are declared
+	if len(app.Notifications) > 0 {
+		files[filepath.Join(outputDir, "src", "components", "NotificationBell.tsx")] = generateNotificationBell()
+	}
+
+	// "Add to calendar" button when calendar feeds are declared
+	if len(app.Calendars) > 0 {
+		files[filepath.Join(outputDir, "src", "components", "AddToCalendar.tsx")] = generateAddToCalendar()
+	}
+
+	// Charts fed by the backend's aggregate endpoints
+	if len(app.Charts) > 0 {
+		files[filepath.Join(outputDir, "src", "components", "DataChart.tsx")] = generateDataChart()
+	}
+
+	// Drag-and-drop lists saved by the backend's reorder endpoints
+	if len(app.Reorders) > 0 {
+		files[filepath.Join(outputDir, "src", "components", "SortableList.tsx")] = generateSortableList()
+	}
+
+	files[filepath.Join(outputDir, "src", "components", "ErrorBoundary.tsx")] = generateErrorBoundary()
+
+	// Hover tooltips and slide-over detail panels
+	if ir.HasTooltips(app) {
+		files[filepath.Join(outputDir, "src", "components", "Tooltip.tsx")] = generateTooltip()
+	}
+	if ir.HasDetailPanels(app) {
+		files[filepath.Join(outputDir, "src", "components", "DetailPanel.tsx")] = generateDetailPanel()
+	}
+
+	// Skeleton screens shaped like the records they stand in for
+	if ir.HasSkeletons(app) {
+		files[filepath.Join(outputDir, "src", "components", "Skeleton.tsx")] = generateSkeleton(app)
+		if styling == ir.StylingCSSModules {
+			moduleCSS = append(moduleCSS, themes.SkeletonCSS(app.Theme))
+		} else {
+			files[filepath.Join(outputDir, "src", "components", "Skeleton.css")] = generateSkeletonCSS(app)
+		}
+	}
+
+	// Canonical links for web apps with public pages
+	if app.Sitemap != nil {
+		files[filepath.Join(outputDir, "src", "components", "CanonicalLink.tsx")] = generateCanonicalLink(app)
+	}
+
 	// Generate theme files
 	if app.Theme != nil {
 		themeFiles := themes.GenerateReactTheme(app.Theme)
@@ -70,13 +145,19 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		}
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
+	// Styles and tooling of the app's styling system
+	for relPath, content := range themes.StylingFiles(styling, "react", app.Theme) {
+		files[filepath.Join(outputDir, relPath)] = content
+	}
+	if styling == ir.StylingCSSModules {
+		module := filepath.Join(outputDir, "src", "styles", "app.module.css")
+		for _, css := range moduleCSS {
+			files[module] += "\n" + css
 		}
+		scopeClassNames(files, outputDir)
 	}
 
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 // generateIndexHTML produces the Vite-required index.html entry point.
@@ -100,21 +181,44 @@ func generateIndexHTML(app *ir.Application) string {
 `, title)
 }
 
-// generateMainTsx produces the React DOM entry point (src/main.tsx).
-func generateMainTsx() string {
-	return `// Generated by Human compiler — do not edit
+// generateMainTsx produces the React DOM entry point (src/main.tsx),
+// loading the styles of the app's styling system.
+func generateMainTsx(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString(`// Generated by Human compiler — do not edit
 
 import React from 'react'
 import ReactDOM from 'react-dom/client'
-import App from './App'
-import './index.css'
+`)
+	styling := ir.Styling(app)
+	if styling == ir.StylingStyledComponents {
+		b.WriteString("import { ThemeProvider } from 'styled-components'\n")
+	}
+	b.WriteString("import App from './App'\n")
+	b.WriteString("import './index.css'\n")
+	switch styling {
+	case ir.StylingTailwind:
+		b.WriteString("import './styles/tailwind.css'\n")
+	case ir.StylingVanillaExtract:
+		b.WriteString("import './styles/app.css'\n")
+	case ir.StylingStyledComponents:
+		b.WriteString("import GlobalStyle from './styles/GlobalStyle'\n")
+		b.WriteString("import { theme } from './styles/theme'\n")
+	}
 
-ReactDOM.createRoot(document.getElementById('root')!).render(
-  <React.StrictMode>
-    <App />
-  </React.StrictMode>
-)
-`
+	b.WriteString("\nReactDOM.createRoot(document.getElementById('root')!).render(\n")
+	b.WriteString("  <React.StrictMode>\n")
+	if styling == ir.StylingStyledComponents {
+		b.WriteString("    <ThemeProvider theme={theme}>\n")
+		b.WriteString("      <GlobalStyle />\n")
+		b.WriteString("      <App />\n")
+		b.WriteString("    </ThemeProvider>\n")
+	} else {
+		b.WriteString("    <App />\n")
+	}
+	b.WriteString("  </React.StrictMode>\n")
+	b.WriteString(")\n")
+	return b.String()
 }
 
 // generateViteEnvDts produces the Vite env type reference (src/vite-env.d.ts).
@@ -123,11 +227,12 @@ func generateViteEnvDts() string {
 }
 
 // generateIndexCSS produces base styles for src/index.css.
-// If the theme uses Tailwind, it includes Tailwind directives.
+// If the theme uses Tailwind, it includes Tailwind directives, unless the
+// app is styled using Tailwind, whose stylesheet has them.
 func generateIndexCSS(app *ir.Application) string {
 	useTailwind := false
 	if app.Theme != nil && app.Theme.DesignSystem != "" {
-		useTailwind = themes.NeedsTailwind(app.Theme.DesignSystem)
+		useTailwind = themes.NeedsTailwind(app.Theme.DesignSystem) && ir.Styling(app) != ir.StylingTailwind
 	}
 
 	var b strings.Builder
@@ -167,18 +272,6 @@ input, button, textarea, select {
 	return b.String()
 }
 
-// writeFile writes content to a file, creating parent directories if needed.
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
-}
-
 // tsType maps an IR field type to a TypeScript type.
 func tsType(irType string) string {
 	switch strings.ToLower(irType) {
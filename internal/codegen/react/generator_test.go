@@ -341,14 +341,14 @@ func TestGenerateApp(t *testing.T) {
 	if !strings.Contains(output, "import { BrowserRouter, Routes, Route } from 'react-router-dom'") {
 		t.Error("missing react-router-dom import")
 	}
-	if !strings.Contains(output, "import HomePage from './pages/HomePage'") {
-		t.Error("missing HomePage import")
+	if !strings.Contains(output, "const HomePage = lazy(() => import('./pages/HomePage'));") {
+		t.Error("missing lazy HomePage import")
 	}
-	if !strings.Contains(output, "import DashboardPage from './pages/DashboardPage'") {
-		t.Error("missing DashboardPage import")
+	if !strings.Contains(output, "const DashboardPage = lazy(() => import('./pages/DashboardPage'));") {
+		t.Error("missing lazy DashboardPage import")
 	}
-	if !strings.Contains(output, "import ProfilePage from './pages/ProfilePage'") {
-		t.Error("missing ProfilePage import")
+	if !strings.Contains(output, "const ProfilePage = lazy(() => import('./pages/ProfilePage'));") {
+		t.Error("missing lazy ProfilePage import")
 	}
 
 	// Check routes
@@ -364,6 +364,9 @@ func TestGenerateApp(t *testing.T) {
 	if !strings.Contains(output, "element={<HomePage />}") {
 		t.Error("missing HomePage element")
 	}
+	if !strings.Contains(output, "<Suspense fallback=") {
+		t.Error("routes should wait for their page's chunk in Suspense")
+	}
 }
 
 // ── Page Generator ──
@@ -488,7 +491,7 @@ func TestGeneratePageWithModel(t *testing.T) {
 	}
 
 	// Loop with typed fields
-	if !strings.Contains(output, "tasks.map((task)") {
+	if !strings.Contains(output, "visibleTasks.map((task)") {
 		t.Error("loop should use model-aware variable names")
 	}
 	if !strings.Contains(output, "task.title") {
@@ -736,7 +739,7 @@ func TestGenerateIndexHTMLDefaultTitle(t *testing.T) {
 }
 
 func TestGenerateMainTsx(t *testing.T) {
-	output := generateMainTsx()
+	output := generateMainTsx(&ir.Application{})
 
 	if !strings.Contains(output, "import React from 'react'") {
 		t.Error("missing React import")
@@ -978,8 +981,8 @@ func TestFullIntegration(t *testing.T) {
 	}
 	client := string(clientContent)
 	funcCount := strings.Count(client, "export async function ")
-	if funcCount != 9 {
-		t.Errorf("client.ts: expected 9 functions (8 endpoints + request helper), got %d", funcCount)
+	if funcCount != 10 {
+		t.Errorf("client.ts: expected 10 functions (8 endpoints + request helper + reorder), got %d", funcCount)
 	}
 
 	// Verify App.tsx has 3 routes
@@ -1036,7 +1039,7 @@ func TestFormSubmitCallsAPI(t *testing.T) {
 		t.Error("form should not contain TODO: submit when endpoint exists")
 	}
 	// Should import createTask
-	if !strings.Contains(output, "import { listTasks, createTask }") && !strings.Contains(output, "import { createTask") {
+	if !strings.Contains(output, "import { listTasks, createTask, errorMessage }") && !strings.Contains(output, "import { createTask") {
 		t.Error("should import createTask from API client")
 	}
 }
@@ -1378,3 +1381,1207 @@ func TestIsPublicPage(t *testing.T) {
 		}
 	}
 }
+
+func TestExperimentRoutes(t *testing.T) {
+	app := &ir.Application{
+		Name:  "Shop",
+		Pages: []*ir.Page{{Name: "Home"}, {Name: "Classic"}, {Name: "NewPricing"}},
+		Experiments: []*ir.Experiment{
+			{Name: "pricing-page", Audience: "visitors", Variants: []*ir.ExperimentVariant{{Page: "Classic", Weight: 50}, {Page: "newpricing", Weight: 50}}},
+		},
+	}
+
+	output := generateApp(app)
+	for _, want := range []string{
+		"import ExperimentRoute from './experiments/ExperimentRoute';",
+		"const pricingPageVariants = { Classic: ClassicPage, newpricing: NewPricingPage };",
+		`<Route path="/classic" element={<ExperimentRoute name="pricing-page" control="Classic" variants={pricingPageVariants} />} />`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("App.tsx missing %q", want)
+		}
+	}
+	if strings.Contains(output, "<ExperimentRoute name=\"pricing-page\" control=\"Home\"") {
+		t.Error("only the control page's route should serve the experiment")
+	}
+}
+
+func TestVariantsIdent(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"pricing-page", "pricingPageVariants"},
+		{"checkout", "checkoutVariants"},
+		{"2024-hero", "experiment2024HeroVariants"},
+	}
+	for _, tt := range tests {
+		if got := variantsIdent(&ir.Experiment{Name: tt.name}); got != tt.want {
+			t.Errorf("variantsIdent(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNotificationBellWired(t *testing.T) {
+	app := &ir.Application{
+		Name:          "TaskFlow",
+		Pages:         []*ir.Page{{Name: "Home"}},
+		Notifications: []*ir.Notification{{Model: "Task", Event: "assigned", Message: "Your task was assigned"}},
+	}
+
+	output := generateApp(app)
+	for _, want := range []string{"import NotificationBell from './components/NotificationBell';", "<NotificationBell />"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("App.tsx missing %q", want)
+		}
+	}
+
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"import type { Notification } from '../types/models';",
+		"export async function getUnreadNotificationCount()",
+		"export async function markAllNotificationsRead()",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q", want)
+		}
+	}
+
+	if strings.Contains(generateApp(&ir.Application{Pages: app.Pages}), "NotificationBell") {
+		t.Error("App.tsx should not render the bell without notifications")
+	}
+}
+
+func TestAddToCalendarWired(t *testing.T) {
+	app := &ir.Application{
+		Name: "EventHub",
+		Data: []*ir.DataModel{{Name: "Event", Fields: []*ir.DataField{
+			{Name: "name", Type: "text"},
+			{Name: "date", Type: "datetime"},
+		}}},
+		Calendars: []*ir.CalendarFeed{{Model: "Event", Slug: "events", Title: "name", Start: "date", Timezone: "UTC"}},
+	}
+	page := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all events"},
+		{Type: "loop", Text: "each event shows its name and date"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"import AddToCalendar from '../components/AddToCalendar';", `<AddToCalendar feed="events" id={event.id} />`, `<AddToCalendar feed="events" />`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.tsx missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateAPIClient(app), "export function calendarUrl(feed: string, id?: string): string") {
+		t.Error("client.ts should build calendar feed URLs")
+	}
+
+	app.Calendars = nil
+	if strings.Contains(generatePage(page, app), "AddToCalendar") {
+		t.Error("pages should not render the button without calendars")
+	}
+}
+
+func TestCanonicalLinkWired(t *testing.T) {
+	app := &ir.Application{
+		Name:    "Shop",
+		Pages:   []*ir.Page{{Name: "Home"}},
+		Sitemap: &ir.Sitemap{URL: "https://shop.example.com", Pages: []string{"/"}},
+	}
+
+	component := generateCanonicalLink(app)
+	for _, want := range []string{"import.meta.env.VITE_SITE_URL || 'https://shop.example.com'", "link.rel = 'canonical';"} {
+		if !strings.Contains(component, want) {
+			t.Errorf("CanonicalLink.tsx missing %q", want)
+		}
+	}
+
+	if !strings.Contains(generateApp(app), "<CanonicalLink />") {
+		t.Error("App.tsx should render the canonical link")
+	}
+	app.Sitemap = nil
+	if strings.Contains(generateApp(app), "CanonicalLink") {
+		t.Error("App.tsx should not render the canonical link without a sitemap")
+	}
+}
+
+func TestShowTemplate(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "User", Fields: []*ir.DataField{{Name: "name", Type: "text"}}}},
+		Pages: []*ir.Page{{Name: "Profile", Content: []*ir.Action{
+			{Type: "query", Text: "fetch all users"},
+			{Type: "display", Text: "show Hello", Template: "Hello, {user.name}! <3 {1}"},
+		}}},
+		Components: []*ir.Component{{Name: "UserCard", Props: []*ir.Prop{{Name: "user", Type: "User"}}, Content: []*ir.Action{
+			{Type: "display", Text: "show Signed in", Template: "Signed in as {user.name}"},
+		}}},
+	}
+
+	if page := generatePage(app.Pages[0], app); !strings.Contains(page, "<p>Hello, {users[0]?.name}! &lt;3 &#123;1&#125;</p>") {
+		t.Errorf("page should interpolate the first record and escape the text:\n%s", page)
+	}
+	if comp := generateComponent(app.Components[0], app); !strings.Contains(comp, "<p>Signed in as {user.name}</p>") {
+		t.Errorf("component should interpolate the prop:\n%s", comp)
+	}
+}
+
+func TestTablePage(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "User", Fields: []*ir.DataField{
+			{Name: "name", Type: "text"}, {Name: "email", Type: "email"}, {Name: "active", Type: "boolean"},
+		}}},
+		Pages: []*ir.Page{
+			{Name: "Users", Content: []*ir.Action{
+				{Type: "display", Text: "show users in a table sorted by name"},
+				{Type: "interact", Text: "clicking a user navigates to UserDetail"},
+			}},
+			{Name: "UserDetail"},
+		},
+	}
+
+	page := generatePage(app.Pages[0], app)
+	for _, want := range []string{"<th>Email</th>", "sortBy('name')", "visibleUsers.map((user)", "navigate(`/user-detail?id=${user.id}`)", "<td>{user.active ? 'Yes' : 'No'}</td>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("table page missing %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, ">user</button>") {
+		t.Error("the row click should not also render a button")
+	}
+}
+
+func TestChartWired(t *testing.T) {
+	app := &ir.Application{
+		Name: "Shop",
+		Data: []*ir.DataModel{{Name: "Product", Fields: []*ir.DataField{
+			{Name: "name", Type: "text"},
+			{Name: "category", Type: "text"},
+			{Name: "current_stock", Type: "number"},
+		}}},
+		Charts: []*ir.Chart{{Kind: "bar", Title: "Stock levels by category", Slug: "stock-levels-by-category", Aggregate: ir.Aggregate{Func: "sum", Field: "current_stock", Model: "Product", GroupBy: "category"}}},
+	}
+	page := &ir.Page{Name: "Dashboard", Content: []*ir.Action{
+		{Type: "display", Text: "show a bar chart of stock levels by category"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"import DataChart from '../components/DataChart';", `<DataChart kind="bar" title="Stock levels by category" source="stock-levels-by-category" />`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("DashboardPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateAPIClient(app), "export async function fetchChart(source: string)") {
+		t.Error("client.ts should fetch chart points")
+	}
+
+	app.Charts = nil
+	if strings.Contains(generatePage(page, app), "DataChart") {
+		t.Error("pages should not render a chart the IR did not collect")
+	}
+}
+
+func TestReportClientTyped(t *testing.T) {
+	app := &ir.Application{
+		Name: "Shop",
+		APIs: []*ir.Endpoint{{
+			Name:      "GetOrdersByStatus",
+			Aggregate: &ir.Aggregate{Func: "count", Model: "Order", GroupBy: "status"},
+			Cache:     60,
+		}},
+	}
+
+	output := generateAPIClient(app)
+	for _, want := range []string{"export interface ReportRow {", "request<ReportRow[]>('GET', '/api/orders-by-status')"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestReorderWired(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"},
+			{Name: "position", Type: "number"},
+		}}},
+		Reorders: []*ir.Reorder{{Model: "Task", Field: "position", Slug: "tasks"}},
+		Pages:    []*ir.Page{{Name: "Settings"}},
+	}
+	page := &ir.Page{Name: "Board", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of tasks"},
+		{Type: "loop", Text: "each task shows its title"},
+		{Type: "interact", Text: "dragging a task reorders the list"},
+		{Type: "interact", Text: "pressing slash focuses the search"},
+		{Type: "interact", Text: "pressing Ctrl+K navigates to Settings"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"<SortableList items={tasks} onReorder={handleReorder}>", "const res = await reorder('tasks', next.map((task) => task.id));", "ev.key.toLowerCase() === 'k' && (ev.ctrlKey || ev.metaKey)", "navigate('/settings');", "}, [navigate]);"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("BoardPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateAPIClient(app), "reorder(list: string, ids: string[])") {
+		t.Error("client.ts should save a dragged order")
+	}
+
+	app.Reorders = nil
+	if strings.Contains(generatePage(page, app), "SortableList") {
+		t.Error("pages should not render a draggable list the IR did not collect")
+	}
+}
+
+func TestInfiniteScrollWired(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Post", Fields: []*ir.DataField{{Name: "title", Type: "text"}}}},
+		APIs: []*ir.Endpoint{
+			{Name: "ListPosts", PageSize: 20, Steps: []*ir.Action{{Type: "query", Text: "fetch all posts"}}},
+			{Name: "CreatePost", Params: []*ir.Param{{Name: "title"}}},
+			{Name: "DeletePost", Params: []*ir.Param{{Name: "post_id"}}},
+		},
+	}
+	page := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of posts"},
+		{Type: "loop", Text: "each post shows its title"},
+		{Type: "interact", Text: "clicking New Post opens a form"},
+		{Type: "interact", Text: "clicking Remove deletes the post"},
+		{Type: "interact", Text: "scrolling to bottom loads more posts"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"const res = await listPosts(nextCursor);", "setNextCursor(res.pagination?.nextCursor ?? null);", "<div ref={sentinel} className=\"load-more\">", "setPosts(prev => [{ ...values, id: tempId } as unknown as Post, ...prev]);", "setPosts(prev => prev.filter(other => other.id !== tempId));", "const res = await deletePost({ post_id: post.id });", "onClick={() => handleDelete(post)}>Remove</button>"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.tsx missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateAPIClient(app), "export async function listPosts(cursor?: string, query: Record<string, string> = {})") {
+		t.Error("the API client should fetch the page after a cursor")
+	}
+
+	app.APIs[0].PageSize = 0
+	if strings.Contains(generatePage(page, app), "sentinel") {
+		t.Error("pages should not scroll a list whose endpoint does not paginate")
+	}
+}
+
+func TestListQueryInURL(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"},
+			{Name: "status", Type: "enum", EnumValues: []string{"todo", "done"}},
+		}}},
+		APIs: []*ir.Endpoint{
+			{Name: "ListTasks", PageSize: 20, Steps: []*ir.Action{{Type: "query", Text: "fetch all tasks"}}},
+		},
+	}
+	page := &ir.Page{Name: "Tasks", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of tasks"},
+		{Type: "loop", Text: "each task shows its title"},
+		{Type: "input", Text: "there is a search bar to search tasks by title"},
+		{Type: "input", Text: "there is a dropdown to filter by status"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"const [searchParams, setSearchParams] = useSearchParams();",
+		"listTasks(undefined, Object.fromEntries(searchParams))",
+		"}, [attempt, searchParams]);",
+		"value={search} onChange={(ev) => setParam('q', ev.target.value)}",
+		"value={statusFilter} onChange={(ev) => setParam('status', ev.target.value)}",
+		"<option value=\"todo\">Todo</option>",
+		"<nav className=\"pagination\" aria-label=\"Pagination\">",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TasksPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+
+	// A list loaded whole is narrowed in the browser
+	app.APIs[0].PageSize = 0
+	output = generatePage(page, app)
+	for _, want := range []string{
+		"const visibleTasks = tasks.filter((task) =>",
+		"(!statusFilter || String(task.status) === statusFilter)",
+		"visibleTasks.map((task)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TasksPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Pagination") {
+		t.Error("a list loaded whole has no pages")
+	}
+}
+
+func TestRememberedView(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"},
+			{Name: "status", Type: "enum", EnumValues: []string{"todo", "done"}},
+		}}},
+		APIs: []*ir.Endpoint{
+			{Name: "ListTasks", PageSize: 20, Steps: []*ir.Action{{Type: "query", Text: "fetch all tasks"}}},
+		},
+	}
+	page := &ir.Page{Name: "Tasks", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of tasks"},
+		{Type: "loop", Text: "each task shows its title"},
+		{Type: "input", Text: "there is a dropdown to filter by status"},
+		{Type: "configure", Text: "remember the user's filters when they come back"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { useState, useEffect, useRef } from 'react';",
+		"const [restoringView, setRestoringView] = useState(() => (!searchParams.toString() && sessionStorage.getItem(`view:${window.location.pathname}`)) || '');",
+		"    if (restoringView) return;\n    setLoading(true);",
+		"setSearchParams(new URLSearchParams(restoringView), { replace: true });",
+		"sessionStorage.setItem(`view:${window.location.pathname}`, searchParams.toString());",
+		"window.addEventListener('scroll', saveScroll, { passive: true });",
+		"window.scrollTo(0, savedScroll.current);",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TasksPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(output, "{/* remember the user's filters when they come back — restored from sessionStorage */}") {
+		t.Error("the remember statement should be marked as handled")
+	}
+
+	// Without a view in the URL, only the scroll is remembered
+	app.APIs[0].PageSize = 0
+	page.Content = append(page.Content[:2], page.Content[3])
+	output = generatePage(page, app)
+	if strings.Contains(output, "restoringView") || !strings.Contains(output, "savedScroll") {
+		t.Errorf("expected only the scroll to be remembered:\n%s", output)
+	}
+}
+
+func TestTooltipAndPanelWired(t *testing.T) {
+	user := &ir.DataModel{Name: "User", Fields: []*ir.DataField{
+		{Name: "name", Type: "text"},
+		{Name: "avatar", Type: "text"},
+		{Name: "password", Type: "text"},
+	}}
+	app := &ir.Application{
+		Data: []*ir.DataModel{user},
+		APIs: []*ir.Endpoint{{Name: "ListUsers"}},
+		Components: []*ir.Component{{Name: "MemberAvatar", Props: []*ir.Prop{{Name: "user", Type: "User"}}, Content: []*ir.Action{
+			{Type: "interact", Text: "hovering shows the user's name as a tooltip"},
+		}}},
+	}
+	page := &ir.Page{Name: "Team", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all users"},
+		{Type: "loop", Text: "each user shows their name and avatar"},
+		{Type: "interact", Text: "hovering over a user avatar shows their name"},
+		{Type: "interact", Text: "clicking a user opens a detail panel on the right"},
+	}}
+	app.Pages = []*ir.Page{page}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"import Tooltip from '../components/Tooltip';", "<Tooltip text={user.name}><span>{user.avatar}</span></Tooltip>", "onClick={() => setSelected(user)}", "<DetailPanel title=\"User\" side=\"right\" onClose={() => setSelected(null)}>", "<dd>{selected.avatar}</dd>", "— handled by the detail panel"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TeamPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "selected.password") {
+		t.Error("the detail panel should not show passwords")
+	}
+	if comp := generateComponent(app.Components[0], app); !strings.Contains(comp, "<Tooltip text={user.name}>") {
+		t.Errorf("MemberAvatar should show the user's name on hover:\n%s", comp)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Tooltip.tsx", "DetailPanel.tsx"} {
+		if _, err := os.Stat(filepath.Join(dir, "src", "components", name)); err != nil {
+			t.Errorf("expected %s to be generated: %v", name, err)
+		}
+	}
+}
+
+func TestDetailRouteWired(t *testing.T) {
+	post := &ir.DataModel{Name: "Post", Fields: []*ir.DataField{
+		{Name: "title", Type: "text"},
+		{Name: "published", Type: "boolean"},
+	}}
+	home := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of posts"},
+		{Type: "loop", Text: "each post shows its title"},
+		{Type: "interact", Text: "clicking a post navigates to PostDetail"},
+	}}
+	archive := &ir.Page{Name: "Archive", Content: []*ir.Action{
+		{Type: "display", Text: "show a table of posts"},
+		{Type: "interact", Text: "clicking a post navigates to PostDetail"},
+	}}
+	detail := &ir.Page{Name: "PostDetail", Content: []*ir.Action{
+		{Type: "configure", Text: "shows the Post with the id from the url"},
+	}}
+	app := &ir.Application{
+		Data:         []*ir.DataModel{post},
+		Pages:        []*ir.Page{home, archive, detail},
+		APIs:         []*ir.Endpoint{{Name: "ListPosts"}, {Name: "GetPost", Params: []*ir.Param{{Name: "post_id"}}}},
+		DetailRoutes: []*ir.DetailRoute{{Page: "PostDetail", Model: "Post", Param: "id", Slug: "posts", Endpoint: "GetPost", Arg: "post_id"}},
+	}
+
+	if output := generateApp(app); !strings.Contains(output, `<Route path="/posts/:id" element={<PostDetailPage />} />`) {
+		t.Errorf("App.tsx should route PostDetail by id:\n%s", output)
+	}
+	output := generatePage(home, app)
+	for _, want := range []string{"listPosts()", "onClick={() => navigate(`/posts/${post.id}`)}", "— handled by each item's link"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	if output := generatePage(archive, app); !strings.Contains(output, "navigate(`/posts/${post.id}`)") {
+		t.Errorf("ArchivePage.tsx rows should link to /posts/<id>:\n%s", output)
+	}
+	output = generatePage(detail, app)
+	for _, want := range []string{"const { id } = useParams();", "getPost({ post_id: id })", "<h1>{post.title}</h1>", "{post.published ? 'Yes' : 'No'}", "Post not found"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PostDetailPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestLoadErrorWired(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Post", Fields: []*ir.DataField{{Name: "title", Type: "text"}}}},
+		APIs: []*ir.Endpoint{{Name: "ListPosts"}},
+	}
+	page := &ir.Page{Name: "Feed", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all posts"},
+		{Type: "loop", Text: "each post shows its title"},
+		{Type: "condition", Text: "if there is an error, show Could not reach the journal"},
+	}}
+	app.Pages = []*ir.Page{page}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"import { listPosts, errorMessage } from '../api/client';", ".catch(err => { setLoadError(errorMessage(err, 'Could not reach the journal')); setLoading(false); });", "}, [attempt]);", "<button onClick={retry}>Try again</button>"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("FeedPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "{loadError && (") < strings.Index(output, "alert-error") {
+		t.Error("the load error should show where the page declares its error state")
+	}
+	if client := generateAPIClient(app); !strings.Contains(client, "throw new ApiError(res.status, json.error ?? res.statusText);") {
+		t.Errorf("the API client should throw an ApiError for failed requests:\n%s", client)
+	}
+	if root := generateApp(app); !strings.Contains(root, "<ErrorBoundary>") {
+		t.Errorf("App.tsx should wrap the routes in the error boundary:\n%s", root)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "components", "ErrorBoundary.tsx")); err != nil {
+		t.Errorf("expected ErrorBoundary.tsx to be generated: %v", err)
+	}
+}
+
+func TestSkeletonShapedLikeItems(t *testing.T) {
+	app := &ir.Application{
+		Theme: &ir.Theme{BorderRadius: "rounded"},
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"}, {Name: "status", Type: "text"}, {Name: "created", Type: "date"},
+		}}},
+		APIs: []*ir.Endpoint{{Name: "ListTasks"}},
+	}
+	list := &ir.Page{Name: "Board", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "condition", Text: "while loading, show a skeleton screen"},
+		{Type: "loop", Text: "each task shows its title, status and created"},
+	}}
+	table := &ir.Page{Name: "Archive", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "condition", Text: "while loading, show a skeleton screen"},
+		{Type: "display", Text: "show a table of tasks"},
+	}}
+	app.Pages = []*ir.Page{list, table}
+
+	output := generatePage(list, app)
+	for _, want := range []string{"import Skeleton from '../components/Skeleton';", `<div key={i} className="task-item">`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("BoardPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	heading := strings.Index(output, `<Skeleton shape="heading" />`)
+	badge := strings.Index(output, `<Skeleton shape="badge" />`)
+	date := strings.Index(output, `<Skeleton shape="date" />`)
+	if heading < 0 || badge < heading || date < badge {
+		t.Errorf("the skeleton should have a heading, badge, and date like each task:\n%s", output)
+	}
+	if strings.Contains(output, "skeleton-item") {
+		t.Errorf("the skeleton should not fall back to generic placeholders:\n%s", output)
+	}
+	if output := generatePage(table, app); !strings.Contains(output, `<td><Skeleton shape="heading" /></td>`) {
+		t.Errorf("the table's skeleton should have a placeholder per column:\n%s", output)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "src", "components", "Skeleton.css"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "var(--radius, 12px)") {
+		t.Errorf("the skeleton should be themed from the app's theme:\n%s", data)
+	}
+}
+
+func TestStylingCSSModules(t *testing.T) {
+	app := &ir.Application{
+		Config: &ir.BuildConfig{Frontend: "React", Styling: "CSS modules"},
+		Data: []*ir.DataModel{{Name: "Post", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"},
+		}}},
+		APIs: []*ir.Endpoint{{Name: "ListPosts"}},
+	}
+	page := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "configure", Text: "make the layout responsive for mobile"},
+		{Type: "query", Text: "fetch all posts"},
+		{Type: "condition", Text: "while loading, show a skeleton"},
+		{Type: "loop", Text: "each post shows its title"},
+	}}
+	app.Pages = []*ir.Page{page}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	read := func(path ...string) string {
+		data, err := os.ReadFile(filepath.Join(append([]string{dir}, path...)...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	output := read("src", "pages", "HomePage.tsx")
+	for _, want := range []string{"import { cx } from '../styles/cx';", "className={cx('home-page')}", "className={cx('post-item')}"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "import './HomePage.css';") || strings.Contains(output, `className="`) {
+		t.Errorf("the page's classes should all be scoped by the CSS module:\n%s", output)
+	}
+	if skeleton := read("src", "components", "Skeleton.tsx"); !strings.Contains(skeleton, "className={cx(`skeleton skeleton-${shape}`)}") {
+		t.Errorf("Skeleton.tsx should scope its classes:\n%s", skeleton)
+	}
+	module := read("src", "styles", "app.module.css")
+	for _, want := range []string{".error-state {", ".home-page .post-item {", ".skeleton-heading {"} {
+		if !strings.Contains(module, want) {
+			t.Errorf("app.module.css missing %q", want)
+		}
+	}
+	for _, path := range []string{"src/pages/HomePage.css", "src/components/Skeleton.css"} {
+		if _, err := os.Stat(filepath.Join(dir, path)); err == nil {
+			t.Errorf("%s should be in the CSS module", path)
+		}
+	}
+}
+
+func TestStylingMainTsx(t *testing.T) {
+	app := &ir.Application{Config: &ir.BuildConfig{Frontend: "React", Styling: "styled-components"}}
+	output := generateMainTsx(app)
+	for _, want := range []string{"import { ThemeProvider } from 'styled-components'", "<ThemeProvider theme={theme}>\n      <GlobalStyle />\n      <App />"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("main.tsx missing %q:\n%s", want, output)
+		}
+	}
+
+	app.Config.Styling = "Tailwind"
+	if output := generateMainTsx(app); !strings.Contains(output, "import './styles/tailwind.css'") {
+		t.Errorf("main.tsx should load the Tailwind stylesheet:\n%s", output)
+	}
+	app.Theme = &ir.Theme{DesignSystem: "shadcn"}
+	if css := generateIndexCSS(app); strings.Contains(css, "@tailwind") {
+		t.Errorf("index.css shouldn't repeat the Tailwind stylesheet's directives:\n%s", css)
+	}
+}
+
+func TestResponsivePage(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Post", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"}, {Name: "created", Type: "date"},
+		}}},
+		APIs: []*ir.Endpoint{{Name: "ListPosts"}},
+	}
+	page := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "configure", Text: "make the layout responsive for mobile and tablet"},
+		{Type: "display", Text: "show a navigation bar"},
+		{Type: "query", Text: "fetch all posts"},
+		{Type: "loop", Text: "each post shows its title and created"},
+	}}
+	app.Pages = []*ir.Page{page}
+
+	output := generatePage(page, app)
+	if strings.Contains(output, "TODO: make the layout responsive") {
+		t.Errorf("the responsive layout should be handled:\n%s", output)
+	}
+	for _, want := range []string{"import './HomePage.css';", "const [navOpen, setNavOpen] = useState(false);", `<button className="nav-toggle" aria-label="Menu" aria-expanded={navOpen}`, `<div className="nav-links">`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	css := generatePageStyles(page, app)
+	for _, want := range []string{".home-page .post-item {", "grid-template-columns: minmax(0, 2fr) auto;", "@media (max-width: 639px) {", ".home-page .navbar.open .nav-links {", "@container (max-width: 639px) {"} {
+		if !strings.Contains(css, want) {
+			t.Errorf("HomePage.css missing %q:\n%s", want, css)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "pages", "HomePage.css")); err != nil {
+		t.Errorf("expected HomePage.css: %v", err)
+	}
+	html, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil || !strings.Contains(string(html), `<meta name="viewport" content="width=device-width`) {
+		t.Errorf("index.html should set the viewport: %v\n%s", err, html)
+	}
+}
+
+func TestAccessibleForm(t *testing.T) {
+	app := &ir.Application{
+		Name: "TestApp",
+		Data: []*ir.DataModel{
+			{Name: "Task", Fields: []*ir.DataField{
+				{Name: "title", Type: "text", Required: true},
+				{Name: "due", Type: "date", Required: true},
+				{Name: "done", Type: "boolean", Required: true},
+			}},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "ListTasks", Steps: []*ir.Action{{Type: "query", Text: "fetch all Tasks"}}},
+			{Name: "CreateTask", Params: []*ir.Param{{Name: "title"}, {Name: "due"}, {Name: "done"}}},
+		},
+		Pages: []*ir.Page{
+			{Name: "Dashboard", Content: []*ir.Action{
+				{Type: "query", Text: "fetch all Tasks"},
+				{Type: "loop", Text: "each task shows its title"},
+				{Type: "interact", Text: "clicking Add opens a form"},
+				{Type: "condition", Text: "if creation succeeds, show \"Saved\""},
+				{Type: "condition", Text: "if there is an error, show the error"},
+			}},
+		},
+	}
+
+	output := generatePage(app.Pages[0], app)
+
+	for _, want := range []string{
+		// Each input is labelled and tracked while invalid
+		`<label htmlFor="task-form-title">Title</label>`,
+		`id="task-form-title"`,
+		`aria-invalid={fieldError?.id === 'task-form-title'}`,
+		`aria-describedby={fieldError?.id === 'task-form-title' ? 'task-form-title-error' : undefined}`,
+		`<p id="task-form-title-error" className="field-error">`,
+		`type="date"`,
+		"onInvalid={showInvalid}",
+		"type FormEvent, type InvalidEvent",
+		// The modal is a dialog, focused as it opens
+		`role="dialog"`,
+		`aria-modal="true"`,
+		`aria-labelledby="new-task-title"`,
+		`<h2 id="new-task-title">New Task</h2>`,
+		`aria-label="Close"`,
+		"ev.key === 'Escape' && setShowForm(false)",
+		"dialog.current?.querySelector<HTMLElement>('input, select, textarea')?.focus()",
+		"return () => opener?.focus();",
+		// Alerts are announced
+		`<div role="status">{success &&`,
+		`<div role="alert">{error &&`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("page should contain %q", want)
+		}
+	}
+	// A yes/no field may be left unchecked
+	done := output[strings.Index(output, `id="task-form-done"`):]
+	if strings.Contains(done[:strings.Index(done, "/>")], "required") {
+		t.Error("boolean field should not be required")
+	}
+	if strings.Count(output, "required\n") != 2 {
+		t.Errorf("title and due should be required, got %d required inputs", strings.Count(output, "required\n"))
+	}
+}
+
+func TestEditFormWired(t *testing.T) {
+	post := &ir.DataModel{Name: "Post", Versioned: true, Fields: []*ir.DataField{
+		{Name: "title", Type: "text", Required: true},
+		{Name: "published", Type: "boolean"},
+	}}
+	detail := &ir.Page{Name: "PostDetail", Content: []*ir.Action{
+		{Type: "configure", Text: "shows the Post with the id from the url"},
+		{Type: "input", Text: "there is a form to edit the post"},
+	}}
+	app := &ir.Application{
+		Data:  []*ir.DataModel{post},
+		Pages: []*ir.Page{detail},
+		APIs: []*ir.Endpoint{
+			{Name: "GetPost", Params: []*ir.Param{{Name: "post_id"}}},
+			{Name: "UpdatePost", Params: []*ir.Param{{Name: "post_id"}, {Name: "title"}, {Name: "published"}}},
+		},
+		DetailRoutes: []*ir.DetailRoute{{Page: "PostDetail", Model: "Post", Param: "id", Slug: "posts", Endpoint: "GetPost", Arg: "post_id"}},
+	}
+
+	output := generatePage(detail, app)
+	for _, want := range []string{
+		"import { getPost, ApiError, updatePost, ConflictError, errorMessage } from '../api/client';",
+		"<form key={post.version} className=\"form edit-form\" aria-label=\"Edit post\"",
+		"defaultValue={post.title ?? ''}",
+		"defaultChecked={post.published}",
+		"published: fd.has('published'),",
+		"}, post.version);",
+		"setConflict({ current: err.current as Post, changes });",
+		"role=\"alertdialog\" aria-modal=\"true\" aria-labelledby=\"conflict-title\" aria-describedby=\"conflict-description\"",
+		"<button autoFocus onClick={() => savePost(conflict.changes, conflict.current.version)}>Keep my changes</button>",
+		"setPost(conflict.current); setConflict(null);",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PostDetailPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"export class ConflictError extends ApiError {",
+		"throw new ConflictError(json.error ?? res.statusText, json.current);",
+		"export async function updatePost(params: { post_id: string; title: string; published: boolean }, version: number) {",
+		"{ 'If-Match': `\"${version}\"` }",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+	if !strings.Contains(generateTypes(app), "  version: number;\n") {
+		t.Error("the Post type should carry its version")
+	}
+
+	// Without the protection, the form just saves
+	post.Versioned = false
+	output = generatePage(detail, app)
+	if strings.Contains(output, "alertdialog") || !strings.Contains(output, "published: String(fd.has('published')),") {
+		t.Errorf("an unversioned post's form should save without a conflict prompt:\n%s", output)
+	}
+	if strings.Contains(generateAPIClient(app), "If-Match") {
+		t.Error("an unversioned update sends no If-Match")
+	}
+}
+
+func TestImportFormWired(t *testing.T) {
+	task := &ir.DataModel{Name: "Task", Fields: []*ir.DataField{
+		{Name: "title", Type: "text", Required: true},
+	}}
+	page := &ir.Page{Name: "Tasks", Content: []*ir.Action{
+		{Type: "input", Text: "there is a form to import tasks"},
+	}}
+	app := &ir.Application{
+		Data:  []*ir.DataModel{task},
+		Pages: []*ir.Page{page},
+		APIs: []*ir.Endpoint{{Name: "ImportTasks", Import: &ir.Import{
+			Model: "Task", Formats: []string{"csv", "json"}, Mode: ir.ImportAllOrNothing,
+		}}},
+	}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { importTasks, describeImport, type ImportSummary, errorMessage } from '../api/client';",
+		"setImportSummary(await importTasks(importFile, dryRun));",
+		"<form className=\"form import-form\" aria-label=\"Import tasks\"",
+		"<label htmlFor=\"task-import-file\">CSV or JSON file</label>",
+		"accept=\".csv,text/csv,.json,application/json\"",
+		"onClick={() => runImport(true)}>Check file</button>",
+		"<section className=\"import-summary\" aria-live=\"polite\">",
+		"<caption>Problems found</caption>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TasksPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "handleSubmit") {
+		t.Errorf("an import form uploads a file, it doesn't submit fields:\n%s", output)
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"export interface ImportSummary {",
+		"export async function importTasks(file: File, dryRun = false) {",
+		"return uploadImport('/api/import-tasks', file, dryRun);",
+		"if (!res.ok && !json.data) {",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+}
+
+func TestTimesInUserZone(t *testing.T) {
+	event := &ir.DataModel{Name: "Event", Fields: []*ir.DataField{
+		{Name: "title", Type: "text"},
+		{Name: "starts", Type: "datetime"},
+		{Name: "day", Type: "date"},
+	}}
+	home := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of events"},
+		{Type: "loop", Text: "each event shows its title and starts"},
+	}}
+	detail := &ir.Page{Name: "EventDetail", Content: []*ir.Action{
+		{Type: "configure", Text: "shows the Event with the id from the url"},
+	}}
+	app := &ir.Application{
+		Config:       &ir.BuildConfig{Times: "UTC and display in the user's timezone"},
+		Data:         []*ir.DataModel{event},
+		Pages:        []*ir.Page{home, detail},
+		APIs:         []*ir.Endpoint{{Name: "ListEvents"}, {Name: "GetEvent", Params: []*ir.Param{{Name: "event_id"}}}},
+		DetailRoutes: []*ir.DetailRoute{{Page: "EventDetail", Model: "Event", Param: "id", Slug: "events", Endpoint: "GetEvent", Arg: "event_id"}},
+	}
+
+	output := generatePage(home, app)
+	for _, want := range []string{
+		"import { listEvents, errorMessage, formatDateTime } from '../api/client';",
+		"<time dateTime={event.starts ?? undefined}>{formatDateTime(event.starts)}</time>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	output = generatePage(detail, app)
+	if want := "<dd><time dateTime={event.day ?? undefined}>{formatDate(event.day)}</time></dd>"; !strings.Contains(output, want) {
+		t.Errorf("EventDetailPage.tsx missing %q:\n%s", want, output)
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"export function formatDateTime(value: string | null | undefined): string {",
+		"timeZone: 'UTC'",
+		"body: body ? JSON.stringify(utcTimes(body)) : undefined,",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+
+	app.Config.Times = ""
+	if output := generatePage(home, app); strings.Contains(output, "formatDateTime") {
+		t.Errorf("times shouldn't be formatted without a time policy:\n%s", output)
+	}
+}
+
+func TestNumbersInLocale(t *testing.T) {
+	product := &ir.DataModel{Name: "Product", Fields: []*ir.DataField{
+		{Name: "name", Type: "text"},
+		{Name: "price", Type: "decimal"},
+		{Name: "view_count", Type: "number"},
+	}}
+	page := &ir.Page{Name: "Products", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of products"},
+		{Type: "loop", Text: "each product shows its name, price, and view_count"},
+	}}
+	app := &ir.Application{
+		Theme: &ir.Theme{Locale: "de-DE"},
+		Data:  []*ir.DataModel{product},
+		Pages: []*ir.Page{page},
+		APIs:  []*ir.Endpoint{{Name: "ListProducts"}},
+	}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { formatCompact, formatMoney } from '../utils/format';",
+		"<span className=\"number\">{formatMoney(product.price)}</span>",
+		"<span className=\"count\">{formatCompact(product.view_count)}</span>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("ProductsPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	format := generateFormat(app)
+	for _, want := range []string{
+		"const LOCALE = 'de-DE';",
+		"style: 'currency', currency: 'EUR'",
+		"export function formatPercent(value: number | string | null | undefined): string {",
+	} {
+		if !strings.Contains(format, want) {
+			t.Errorf("format.ts missing %q:\n%s", want, format)
+		}
+	}
+
+	app.Theme.Locale = ""
+	if output := generatePage(page, app); strings.Contains(output, "formatMoney") {
+		t.Errorf("numbers shouldn't be formatted without a locale:\n%s", output)
+	}
+}
+
+func TestAccountSettingsPage(t *testing.T) {
+	user := &ir.DataModel{Name: "User", Fields: []*ir.DataField{
+		{Name: "name", Type: "text", Required: true},
+		{Name: "email", Type: "email", Required: true, Unique: true},
+		{Name: "password", Type: "text", Required: true},
+		{Name: "bio", Type: "text"},
+	}}
+	page := &ir.Page{Name: "Settings"}
+	app := &ir.Application{
+		Data:     []*ir.DataModel{user},
+		Pages:    []*ir.Page{page},
+		Auth:     &ir.Auth{},
+		Accounts: &ir.Accounts{Model: "User", Page: "Settings", Profile: true, Password: true, Delete: true},
+		APIs: []*ir.Endpoint{
+			{Name: "GetProfile", Auth: true, Account: ir.AccountProfile},
+			{Name: "UpdateProfile", Auth: true, Account: ir.AccountUpdateProfile, Params: []*ir.Param{{Name: "name"}, {Name: "email"}, {Name: "bio"}}},
+			{Name: "ChangePassword", Auth: true, Account: ir.AccountChangePassword, Params: []*ir.Param{{Name: "current_password"}, {Name: "new_password"}}},
+			{Name: "DeleteAccount", Auth: true, Account: ir.AccountDelete, Params: []*ir.Param{{Name: "password"}}},
+		},
+	}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { getProfile, updateProfile, changePassword, deleteAccount, errorMessage, type Profile } from '../api/client';",
+		"const { logout } = useAuth();",
+		"<form key={profile.id} className=\"form\" aria-labelledby=\"profile-title\" onSubmit={saveProfile}>",
+		"bio: String(fd.get('bio') ?? '') || null,",
+		"<input id=\"new-password\" type=\"password\" name=\"new_password\" autoComplete=\"new-password\" minLength={8} required />",
+		"setPasswordError('The new passwords do not match');",
+		"role=\"alertdialog\"",
+		"disabled={deleteConfirmation !== 'DELETE' || deleting}",
+		"navigate('/', { replace: true });",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("SettingsPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"import type { User } from '../types/models';",
+		"export type Profile = Omit<User, 'password'>;",
+		"export async function updateProfile(params: { name: string; email: string; bio: string | null }) {",
+		"return request<void>('DELETE', '/api/account', params);",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+}
+
+func TestInvitationPages(t *testing.T) {
+	user := &ir.DataModel{Name: "User", Fields: []*ir.DataField{
+		{Name: "email", Type: "email", Required: true, Unique: true},
+		{Name: "password", Type: "text", Required: true},
+	}}
+	org := &ir.DataModel{Name: "Organization", Fields: []*ir.DataField{{Name: "name", Type: "text", Required: true}}}
+	team := &ir.Page{Name: "Team"}
+	accept := &ir.Page{Name: "AcceptInvite"}
+	app := &ir.Application{
+		Data:  []*ir.DataModel{user, org},
+		Pages: []*ir.Page{team, accept},
+		Auth:  &ir.Auth{},
+		Accounts: &ir.Accounts{Model: "User", Invite: &ir.Invitations{
+			Team: "Organization", Expires: 7, Roles: []string{"Admin", "Member"}, Role: "Member", Page: "Team", Accept: "AcceptInvite",
+		}},
+		APIs: []*ir.Endpoint{
+			{Name: "InviteTeammate", Auth: true, Account: ir.AccountInvite, Params: []*ir.Param{{Name: "email"}, {Name: "role"}}},
+			{Name: "GetInvitation", Account: ir.AccountInvitation},
+			{Name: "AcceptInvitation", Auth: true, Account: ir.AccountAcceptInvite, Params: []*ir.Param{{Name: "token"}}},
+		},
+	}
+
+	output := generatePage(team, app)
+	for _, want := range []string{
+		"import { inviteTeammate, errorMessage, type SentInvitation } from '../api/client';",
+		"<select id=\"invite-role\" name=\"role\" defaultValue=\"Member\">",
+		"<option value=\"Admin\">Admin</option>",
+		"<p>Share this link with {sent.email} to invite them:</p>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TeamPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	output = generatePage(accept, app)
+	for _, want := range []string{
+		"const token = params.get('token') ?? '';",
+		"const { isAuthenticated } = useAuth();",
+		"<p>Join <strong>{invitation.team.name}</strong> as {invitation.role}.</p>",
+		"await acceptInvitation({ token });",
+		"<Link to=\"/login\">Sign in</Link>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("AcceptInvitePage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"import type { Organization } from '../types/models';",
+		"  team: Organization;\n",
+		"return request<Invitation>('GET', `/api/invitation?token=${encodeURIComponent(token)}`);",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+	if strings.Contains(client, "export type Profile") {
+		t.Error("no profile without profile, password, or delete")
+	}
+	router := generateApp(app)
+	if !strings.Contains(router, "<Route path=\"/accept-invite\" element={<AcceptInvitePage />} />") {
+		t.Errorf("the accept page isn't guarded:\n%s", router)
+	}
+}
+
+func TestPricingPage(t *testing.T) {
+	pricing := &ir.Page{Name: "Pricing"}
+	app := &ir.Application{
+		Data:  []*ir.DataModel{{Name: "User"}},
+		Pages: []*ir.Page{pricing},
+		Auth:  &ir.Auth{},
+		Billing: &ir.Billing{
+			Model:    "User",
+			Currency: "usd",
+			Page:     "Pricing",
+			Plans: []*ir.Plan{
+				{Name: "Free"},
+				{Name: "Pro", Price: 1900, Interval: "month"},
+				{Name: "Enterprise", Custom: true},
+			},
+		},
+	}
+
+	output := generatePage(pricing, app)
+	for _, want := range []string{
+		"import { getSubscription, startCheckout, openBillingPortal, errorMessage } from '../api/client';",
+		"  { name: 'Pro', price: 1900, interval: 'month', custom: false },\n",
+		"  { name: 'Enterprise', price: 0, interval: null, custom: true },\n",
+		"const money = new Intl.NumberFormat(undefined, { style: 'currency', currency: 'USD' });",
+		"<p className=\"badge\">Current plan</p>",
+		"goTo(plan.name, () => startCheckout(plan.name), 'Could not start checkout')",
+		"'Manage billing'",
+		"<Link to=\"/login\">Sign in to subscribe</Link>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PricingPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"return request<BillingSubscription>('GET', '/api/billing/subscription');",
+		"return request<{ url: string }>('POST', '/api/billing/checkout', { plan });",
+		"return request<{ url: string }>('POST', '/api/billing/portal');",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+	router := generateApp(app)
+	if !strings.Contains(router, "<Route path=\"/pricing\" element={<PricingPage />} />") {
+		t.Errorf("the pricing page isn't public:\n%s", router)
+	}
+}
+
+func TestUsageDashboard(t *testing.T) {
+	usage := &ir.Page{Name: "Usage"}
+	app := &ir.Application{
+		Data:     []*ir.DataModel{{Name: "User"}, {Name: "UsageRecord"}},
+		Pages:    []*ir.Page{usage},
+		Auth:     &ir.Auth{},
+		Metering: &ir.Metering{Period: "week", Model: "User", Page: "Usage"},
+	}
+
+	output := generatePage(usage, app)
+	for _, want := range []string{
+		"import { getUsageSummary, getUsageHistory, errorMessage } from '../api/client';",
+		"const periodLabel = new Intl.DateTimeFormat(undefined, { dateStyle: 'medium', timeZone: 'UTC' });",
+		"<progress value={Math.min(usage.used, usage.limit)} max={usage.limit} aria-label=\"API calls used\" />",
+		"<p>Resets {resetTime.format(new Date(usage.resetsAt))}</p>",
+		"<h2 id=\"usage-history\">Calls per week</h2>",
+		"<tr><th scope=\"col\">Week of</th><th scope=\"col\">Calls</th></tr>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("UsagePage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Upgrade your plan") {
+		t.Errorf("an app without billing links to a pricing page:\n%s", output)
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"return request<UsageSummary>('GET', '/api/usage');",
+		"return request<UsagePeriod[]>('GET', `/api/usage/history?periods=${periods}`);",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+	router := generateApp(app)
+	if !strings.Contains(router, "element={<ProtectedRoute><UsagePage /></ProtectedRoute>}") {
+		t.Errorf("the usage page isn't protected:\n%s", router)
+	}
+}
+
+func TestAPIKeysPage(t *testing.T) {
+	keys := &ir.Page{Name: "ApiKeys"}
+	app := &ir.Application{
+		Data:  []*ir.DataModel{{Name: "User"}, {Name: "ApiKey"}},
+		Pages: []*ir.Page{keys},
+		Auth:  &ir.Auth{APIKeys: &ir.APIKeys{Model: "User", Page: "ApiKeys"}},
+	}
+
+	output := generatePage(keys, app)
+	for _, want := range []string{
+		"import { listApiKeys, createApiKey, revokeApiKey, errorMessage, type ApiKey } from '../api/client';",
+		"const scopes = fd.get('write') ? ['read', 'write'] : ['read'];",
+		"<input type=\"text\" readOnly value={created.key} aria-label=\"New API key\"",
+		"{apiKey.lastUsedAt ? dateTime.format(new Date(apiKey.lastUsedAt)) : 'Never'}",
+		"onClick={() => revokeKey(apiKey)}",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("ApiKeysPage.tsx missing %q:\n%s", want, output)
+		}
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"return request<ApiKey[]>('GET', '/api/keys');",
+		"return request<ApiKey & { key: string }>('POST', '/api/keys', params);",
+		"return request<void>('DELETE', `/api/keys/${encodeURIComponent(id)}`);",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+	router := generateApp(app)
+	if !strings.Contains(router, "element={<ProtectedRoute><ApiKeysPage /></ProtectedRoute>}") {
+		t.Errorf("the API keys page isn't protected:\n%s", router)
+	}
+}
+
+func TestTargetEnvironment(t *testing.T) {
+	app := &ir.Application{
+		Name:         "Shop",
+		Config:       &ir.BuildConfig{Frontend: "React", Deploy: "AWS", Env: "production"},
+		Environments: []*ir.Environment{{Name: "production", Config: map[string]string{"url": "shop example com"}}},
+	}
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, ".env.production"))
+	if err != nil || !strings.Contains(string(content), "VITE_API_URL=https://api.shop.example.com\n") {
+		t.Errorf("vite build should call the production API, got %q (%v)", content, err)
+	}
+
+	app.Config.Env = ""
+	dir = t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".env.production")); err == nil {
+		t.Error("a local build shouldn't write .env.production")
+	}
+}
@@ -0,0 +1,138 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeImportClient writes the summary an import responds with, the helper
+// uploading a file of records to one, and the sentence summing it up.
+func writeImportClient(b *strings.Builder) {
+	b.WriteString(`
+export interface ImportError {
+  row: number;
+  field?: string;
+  message: string;
+}
+
+export interface ImportSummary {
+  mode: 'all-or-nothing' | 'continue-on-error';
+  dryRun: boolean;
+  total: number;
+  imported: number;
+  failed: number;
+  errors: ImportError[];
+}
+
+// Uploads a CSV or JSON file of records to an import, only checking them on
+// a dry run. An all-or-nothing import refused for invalid records still
+// answers with its summary.
+async function uploadImport(path: string, file: File, dryRun: boolean): Promise<ImportSummary> {
+  const token = localStorage.getItem('token');
+  const headers: Record<string, string> = {
+    'Content-Type': /\.json$/i.test(file.name) || file.type.includes('json') ? 'application/json' : 'text/csv',
+  };
+  if (token) {
+    headers['Authorization'] = ` + "`Bearer ${token}`" + `;
+  }
+  const res = await fetch(` + "`${API_BASE_URL}${path}${dryRun ? '?dry_run=true' : ''}`" + `, {
+    method: 'POST',
+    headers,
+    body: await file.text(),
+  });
+  const json = await res.json().catch(() => ({}));
+  if (!res.ok && !json.data) {
+    throw new ApiError(res.status, json.error ?? res.statusText);
+  }
+  return json.data;
+}
+
+export function describeImport(summary: ImportSummary): string {
+  const { total, imported, failed } = summary;
+  if (summary.dryRun) {
+    return failed > 0
+      ? ` + "`${total - failed} of ${total} records are ready to import; ${failed} have problems`" + `
+      : ` + "`All ${total} records are ready to import`" + `;
+  }
+  if (summary.mode === 'all-or-nothing' && failed > 0) {
+    return ` + "`Nothing was imported: ${failed} of ${total} records have problems`" + `;
+  }
+  return failed > 0
+    ? ` + "`Imported ${imported} of ${total} records; ${failed} were skipped`" + `
+    : ` + "`Imported all ${total} records`" + `;
+}
+`)
+}
+
+// writeImportFunction writes the client function uploading a file to an
+// import endpoint.
+func writeImportFunction(b *strings.Builder, ep *ir.Endpoint) {
+	fmt.Fprintf(b, "export async function %s(file: File, dryRun = false) {\n", toCamelCase(ep.Name))
+	fmt.Fprintf(b, "  return uploadImport('%s', file, dryRun);\n", apiPath(ep.Name))
+	b.WriteString("}\n")
+}
+
+// importImports returns what a page's import form uses from the API client.
+func importImports(im *ir.ImportForm) []string {
+	return []string{toCamelCase(im.Endpoint.Name), "describeImport", "type ImportSummary"}
+}
+
+// writeImportState declares the state of a page's import form and the
+// function uploading its file, to check it or to import it.
+func writeImportState(b *strings.Builder, ctx *pageContext) {
+	im := ctx.importForm
+	b.WriteString("\n  const [importFile, setImportFile] = useState<File | null>(null);\n")
+	b.WriteString("  const [importing, setImporting] = useState(false);\n")
+	b.WriteString("  const [importError, setImportError] = useState('');\n")
+	b.WriteString("  const [importSummary, setImportSummary] = useState<ImportSummary | null>(null);\n")
+	b.WriteString("\n  async function runImport(dryRun: boolean) {\n")
+	b.WriteString("    if (!importFile) return;\n")
+	b.WriteString("    setImporting(true);\n")
+	b.WriteString("    setImportError('');\n")
+	b.WriteString("    try {\n")
+	fmt.Fprintf(b, "      setImportSummary(await %s(importFile, dryRun));\n", toCamelCase(im.Endpoint.Name))
+	b.WriteString("    } catch (err) {\n")
+	b.WriteString("      setImportSummary(null);\n")
+	b.WriteString("      setImportError(errorMessage(err, 'Could not import the file'));\n")
+	b.WriteString("    } finally {\n")
+	b.WriteString("      setImporting(false);\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+}
+
+// writeImportFormJSX renders a page's import form: a file input, a button
+// checking the file and one importing it, and the summary of the last
+// check or import with a table of the problems found, announced as it
+// changes.
+func writeImportFormJSX(b *strings.Builder, indent string, ctx *pageContext) {
+	im := ctx.importForm
+	label := strings.ToLower(ir.FieldLabel(im.Model.Name))
+	id := ctx.uniqueID(toKebabCase(im.Model.Name) + "-import-file")
+	fmt.Fprintf(b, "%s<form className=\"form import-form\" aria-label=\"Import %ss\" onSubmit={(ev) => { ev.preventDefault(); runImport(false); }}>\n", indent, label)
+	fmt.Fprintf(b, "%s  <div className=\"form-field\">\n", indent)
+	fmt.Fprintf(b, "%s    <label htmlFor=\"%s\">%s</label>\n", indent, id, im.Endpoint.Import.Describe())
+	fmt.Fprintf(b, "%s    <input id=\"%s\" type=\"file\" name=\"file\" accept=\"%s\" onChange={(ev) => { setImportFile(ev.currentTarget.files?.[0] ?? null); setImportSummary(null); }} />\n", indent, id, im.Endpoint.Import.Accepts())
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s  {importError && <p className=\"form-error\" role=\"alert\">{importError}</p>}\n", indent)
+	fmt.Fprintf(b, "%s  <button type=\"button\" disabled={!importFile || importing} onClick={() => runImport(true)}>Check file</button>\n", indent)
+	fmt.Fprintf(b, "%s  <button type=\"submit\" disabled={!importFile || importing}>Import</button>\n", indent)
+	fmt.Fprintf(b, "%s</form>\n", indent)
+	fmt.Fprintf(b, "%s<section className=\"import-summary\" aria-live=\"polite\">\n", indent)
+	fmt.Fprintf(b, "%s  {importSummary && <p>{describeImport(importSummary)}</p>}\n", indent)
+	fmt.Fprintf(b, "%s  {importSummary && importSummary.errors.length > 0 && (\n", indent)
+	fmt.Fprintf(b, "%s    <table>\n", indent)
+	fmt.Fprintf(b, "%s      <caption>Problems found</caption>\n", indent)
+	fmt.Fprintf(b, "%s      <thead>\n", indent)
+	fmt.Fprintf(b, "%s        <tr><th scope=\"col\">Row</th><th scope=\"col\">Field</th><th scope=\"col\">Problem</th></tr>\n", indent)
+	fmt.Fprintf(b, "%s      </thead>\n", indent)
+	fmt.Fprintf(b, "%s      <tbody>\n", indent)
+	fmt.Fprintf(b, "%s        {importSummary.errors.map((problem, i) => (\n", indent)
+	fmt.Fprintf(b, "%s          <tr key={i}><td>{problem.row}</td><td>{problem.field ?? ''}</td><td>{problem.message}</td></tr>\n", indent)
+	fmt.Fprintf(b, "%s        ))}\n", indent)
+	fmt.Fprintf(b, "%s      </tbody>\n", indent)
+	fmt.Fprintf(b, "%s    </table>\n", indent)
+	fmt.Fprintf(b, "%s  )}\n", indent)
+	fmt.Fprintf(b, "%s</section>\n", indent)
+}
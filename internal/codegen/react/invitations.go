@@ -0,0 +1,247 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeInvitationTypes writes the invitation a token is for, as the accept
+// page shows it, and what inviting a teammate responds with: the link to
+// share when the app doesn't email invitations itself.
+func writeInvitationTypes(b *strings.Builder, app *ir.Application) {
+	b.WriteString("\nexport interface Invitation {\n")
+	b.WriteString("  email: string;\n")
+	b.WriteString("  role: string;\n")
+	b.WriteString("  expires: string;\n")
+	fmt.Fprintf(b, "  team: %s;\n", app.Accounts.Invite.Team)
+	b.WriteString("}\n")
+	b.WriteString("\nexport interface SentInvitation {\n")
+	b.WriteString("  id: string;\n")
+	b.WriteString("  email: string;\n")
+	b.WriteString("  role: string;\n")
+	b.WriteString("  expires: string;\n")
+	b.WriteString("  link?: string;\n")
+	b.WriteString("}\n")
+}
+
+// writeInvitationFunction writes the client function of an invitation
+// endpoint.
+func writeInvitationFunction(b *strings.Builder, ep *ir.Endpoint) {
+	fn := toCamelCase(ep.Name)
+	method := httpMethod(ep.Name)
+	path := apiPath(ep.Name)
+	switch ep.Account {
+	case ir.AccountInvite:
+		fmt.Fprintf(b, "export async function %s(params: { email: string; role?: string }) {\n", fn)
+		fmt.Fprintf(b, "  return request<SentInvitation>('%s', '%s', params);\n", method, path)
+	case ir.AccountInvitation:
+		fmt.Fprintf(b, "export async function %s(token: string) {\n", fn)
+		fmt.Fprintf(b, "  return request<Invitation>('%s', `%s?token=${encodeURIComponent(token)}`);\n", method, path)
+	case ir.AccountAcceptInvite:
+		fmt.Fprintf(b, "export async function %s(params: { token: string }) {\n", fn)
+		fmt.Fprintf(b, "  return request<unknown>('%s', '%s', params);\n", method, path)
+	}
+	b.WriteString("}\n")
+}
+
+// invitesTeammates reports whether the app's accounts block lets users
+// invite teammates to a team it has a model for.
+func invitesTeammates(app *ir.Application) bool {
+	return ir.AccountModel(app) != nil && app.Accounts.Invite != nil && app.Accounts.Invite.Team != ""
+}
+
+// isAcceptInvitePage reports whether page is the page an invitation links
+// to. Whoever follows the link sees who it's for before signing in, so the
+// page isn't guarded.
+func isAcceptInvitePage(app *ir.Application, page *ir.Page) bool {
+	return ir.IsInvitePage(app, page) && page.Name == app.Accounts.Invite.Accept
+}
+
+// generateInvitePage produces one of the pages invitations added: the team
+// page, or the page an invitation links to.
+func generateInvitePage(page *ir.Page, app *ir.Application) string {
+	if isAcceptInvitePage(app, page) {
+		return generateAcceptInvitePage(page, app)
+	}
+	return generateTeamPage(page, app)
+}
+
+// generateTeamPage produces the page members invite teammates on: an email
+// and the role to invite them as. When the app doesn't email invitations,
+// the page shows the link for the inviter to share.
+func generateTeamPage(page *ir.Page, app *ir.Application) string {
+	var b strings.Builder
+	inv := app.Accounts.Invite
+	invite := ir.AccountEndpoint(app, ir.AccountInvite)
+	if !invitesTeammates(app) || invite == nil {
+		return generateEmptyInvitePage(page)
+	}
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { useState, type FormEvent } from 'react';\n")
+	fmt.Fprintf(&b, "import { %s, errorMessage, type SentInvitation } from '../api/client';\n\n", toCamelCase(invite.Name))
+
+	fmt.Fprintf(&b, "export default function %sPage() {\n", page.Name)
+	b.WriteString("  const [sent, setSent] = useState<SentInvitation | null>(null);\n")
+	b.WriteString("  const [inviteError, setInviteError] = useState('');\n")
+	b.WriteString("  const [sending, setSending] = useState(false);\n\n")
+	b.WriteString("  async function sendInvite(ev: FormEvent<HTMLFormElement>) {\n")
+	b.WriteString("    ev.preventDefault();\n")
+	b.WriteString("    const form = ev.currentTarget;\n")
+	b.WriteString("    const fd = new FormData(form);\n")
+	b.WriteString("    setInviteError('');\n")
+	b.WriteString("    setSent(null);\n")
+	b.WriteString("    setSending(true);\n")
+	b.WriteString("    try {\n")
+	fmt.Fprintf(&b, "      const res = await %s({ email: String(fd.get('email') ?? ''), role: String(fd.get('role') ?? '') || undefined });\n", toCamelCase(invite.Name))
+	b.WriteString("      form.reset();\n")
+	b.WriteString("      setSent(res.data);\n")
+	b.WriteString("    } catch (err) {\n")
+	b.WriteString("      setInviteError(errorMessage(err, 'Could not send the invitation'));\n")
+	b.WriteString("    } finally {\n")
+	b.WriteString("      setSending(false);\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n\n")
+
+	b.WriteString("  return (\n")
+	fmt.Fprintf(&b, "    <div className=\"%s-page\">\n", toKebabCase(page.Name))
+	fmt.Fprintf(&b, "      <h1>%s</h1>\n", ir.FieldLabel(page.Name))
+	b.WriteString("      <section aria-labelledby=\"invite-title\">\n")
+	b.WriteString("        <h2 id=\"invite-title\">Invite a teammate</h2>\n")
+	fmt.Fprintf(&b, "        <p>Invitations can be accepted for %d days.</p>\n", inv.Expires)
+	b.WriteString("        <form className=\"form\" aria-labelledby=\"invite-title\" onSubmit={sendInvite}>\n")
+	b.WriteString("          <div className=\"form-field\">\n")
+	b.WriteString("            <label htmlFor=\"invite-email\">Email</label>\n")
+	b.WriteString("            <input id=\"invite-email\" type=\"email\" name=\"email\" autoComplete=\"off\" required />\n")
+	b.WriteString("          </div>\n")
+	if len(inv.Roles) > 0 {
+		b.WriteString("          <div className=\"form-field\">\n")
+		b.WriteString("            <label htmlFor=\"invite-role\">Role</label>\n")
+		fmt.Fprintf(&b, "            <select id=\"invite-role\" name=\"role\" defaultValue=\"%s\">\n", inv.Role)
+		for _, role := range inv.Roles {
+			fmt.Fprintf(&b, "              <option value=\"%s\">%s</option>\n", role, ir.FieldLabel(role))
+		}
+		b.WriteString("            </select>\n")
+		b.WriteString("          </div>\n")
+	}
+	b.WriteString("          {inviteError && <p className=\"form-error\" role=\"alert\">{inviteError}</p>}\n")
+	b.WriteString("          {sent && (\n")
+	b.WriteString("            <div className=\"form-success\" role=\"status\">\n")
+	if ir.EmailIntegration(app) != nil {
+		b.WriteString("              <p>Invitation sent to {sent.email}</p>\n")
+	} else {
+		b.WriteString("              <p>Share this link with {sent.email} to invite them:</p>\n")
+		b.WriteString("              <input type=\"text\" readOnly value={sent.link ?? ''} aria-label=\"Invitation link\" onFocus={(ev) => ev.target.select()} />\n")
+	}
+	b.WriteString("            </div>\n")
+	b.WriteString("          )}\n")
+	b.WriteString("          <button type=\"submit\" disabled={sending}>Send invitation</button>\n")
+	b.WriteString("        </form>\n")
+	b.WriteString("      </section>\n")
+	b.WriteString("    </div>\n")
+	b.WriteString("  );\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// generateAcceptInvitePage produces the page an invitation links to. It
+// shows the team and role the token is for, and accepting it takes the
+// signed-in user to the home page as a member; visitors who aren't signed
+// in are asked to first.
+func generateAcceptInvitePage(page *ir.Page, app *ir.Application) string {
+	var b strings.Builder
+	inv := app.Accounts.Invite
+	lookup := ir.AccountEndpoint(app, ir.AccountInvitation)
+	accept := ir.AccountEndpoint(app, ir.AccountAcceptInvite)
+	if !invitesTeammates(app) || lookup == nil || accept == nil {
+		return generateEmptyInvitePage(page)
+	}
+	teamName := "team"
+	if m := findModel(app, inv.Team); m != nil && m.FieldNamed("name") != nil {
+		teamName = "name"
+	}
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { useState, useEffect } from 'react';\n")
+	b.WriteString("import { Link, useNavigate, useSearchParams } from 'react-router-dom';\n")
+	if app.Auth != nil {
+		b.WriteString("import { useAuth } from '../contexts/AuthContext';\n")
+	}
+	fmt.Fprintf(&b, "import { %s, %s, errorMessage, type Invitation } from '../api/client';\n\n", toCamelCase(lookup.Name), toCamelCase(accept.Name))
+
+	fmt.Fprintf(&b, "export default function %sPage() {\n", page.Name)
+	b.WriteString("  const [params] = useSearchParams();\n")
+	b.WriteString("  const token = params.get('token') ?? '';\n")
+	b.WriteString("  const navigate = useNavigate();\n")
+	if app.Auth != nil {
+		b.WriteString("  const { isAuthenticated } = useAuth();\n")
+	} else {
+		b.WriteString("  const isAuthenticated = Boolean(localStorage.getItem('token'));\n")
+	}
+	b.WriteString("  const [invitation, setInvitation] = useState<Invitation | null>(null);\n")
+	b.WriteString("  const [loadError, setLoadError] = useState('');\n")
+	b.WriteString("  const [acceptError, setAcceptError] = useState('');\n")
+	b.WriteString("  const [accepting, setAccepting] = useState(false);\n\n")
+	b.WriteString("  useEffect(() => {\n")
+	b.WriteString("    if (!token) {\n")
+	b.WriteString("      setLoadError('This invitation link is missing its token');\n")
+	b.WriteString("      return;\n")
+	b.WriteString("    }\n")
+	fmt.Fprintf(&b, "    %s(token)\n", toCamelCase(lookup.Name))
+	b.WriteString("      .then(res => setInvitation(res.data))\n")
+	b.WriteString("      .catch(err => setLoadError(errorMessage(err, 'Could not load the invitation')));\n")
+	b.WriteString("  }, [token]);\n\n")
+	b.WriteString("  async function acceptInvite() {\n")
+	b.WriteString("    setAcceptError('');\n")
+	b.WriteString("    setAccepting(true);\n")
+	b.WriteString("    try {\n")
+	fmt.Fprintf(&b, "      await %s({ token });\n", toCamelCase(accept.Name))
+	b.WriteString("      navigate('/', { replace: true });\n")
+	b.WriteString("    } catch (err) {\n")
+	b.WriteString("      setAcceptError(errorMessage(err, 'Could not accept the invitation'));\n")
+	b.WriteString("      setAccepting(false);\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n\n")
+
+	b.WriteString("  return (\n")
+	fmt.Fprintf(&b, "    <div className=\"%s-page\">\n", toKebabCase(page.Name))
+	b.WriteString("      <h1>You're invited</h1>\n")
+	b.WriteString("      {loadError && <p className=\"form-error\" role=\"alert\">{loadError}</p>}\n")
+	b.WriteString("      {invitation && (\n")
+	b.WriteString("        <section aria-live=\"polite\">\n")
+	if teamName == "name" {
+		b.WriteString("          <p>Join <strong>{invitation.team.name}</strong> as {invitation.role}.</p>\n")
+	} else {
+		fmt.Fprintf(&b, "          <p>Join the %s as {invitation.role}.</p>\n", strings.ToLower(ir.FieldLabel(inv.Team)))
+	}
+	b.WriteString("          <p>This invitation was sent to {invitation.email}.</p>\n")
+	b.WriteString("          {acceptError && <p className=\"form-error\" role=\"alert\">{acceptError}</p>}\n")
+	b.WriteString("          {isAuthenticated ? (\n")
+	b.WriteString("            <button type=\"button\" onClick={acceptInvite} disabled={accepting}>Accept invitation</button>\n")
+	b.WriteString("          ) : (\n")
+	b.WriteString("            <p><Link to=\"/login\">Sign in</Link> as {invitation.email} to accept.</p>\n")
+	b.WriteString("          )}\n")
+	b.WriteString("        </section>\n")
+	b.WriteString("      )}\n")
+	b.WriteString("    </div>\n")
+	b.WriteString("  );\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// generateEmptyInvitePage produces an invite page for invitations with no
+// team to join; the analyzer has warned about it.
+func generateEmptyInvitePage(page *ir.Page) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	fmt.Fprintf(&b, "export default function %sPage() {\n", page.Name)
+	b.WriteString("  return (\n")
+	fmt.Fprintf(&b, "    <div className=\"%s-page\">\n", toKebabCase(page.Name))
+	fmt.Fprintf(&b, "      <h1>%s</h1>\n", ir.FieldLabel(page.Name))
+	b.WriteString("    </div>\n")
+	b.WriteString("  );\n")
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// pageScrolls reports whether a page loads the next page of its list as
+// the user nears the end: it says "scrolling to bottom loads more posts"
+// of the model it lists, and the endpoint it lists them from paginates.
+func pageScrolls(page *ir.Page, app *ir.Application, modelName string, listEp *ir.Endpoint) bool {
+	if listEp == nil || listEp.PageSize == 0 || len(listEp.Params) > 0 {
+		return false
+	}
+	for _, a := range page.Content {
+		if ir.IsInfiniteScroll(a) {
+			if m := ir.ScrollModel(app, a); m != nil && m.Name == modelName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pageDelete returns the endpoint and button label of a page's
+// `clicking "Delete" deletes the task`, when the page lists tasks and a
+// delete endpoint takes just the task's id.
+func pageDelete(page *ir.Page, app *ir.Application, modelName string) (*ir.Endpoint, string) {
+	if modelName == "" {
+		return nil, ""
+	}
+	for _, a := range page.Content {
+		if !ir.IsDelete(a) || !strings.Contains(strings.ToLower(a.Text), strings.ToLower(modelName)) {
+			continue
+		}
+		ep := findDeleteEndpoint(app, modelName)
+		if ep == nil || len(ep.Params) != 1 || !strings.HasSuffix(strings.ToLower(ep.Params[0].Name), "id") {
+			return nil, ""
+		}
+		return ep, ir.DeleteLabel(a)
+	}
+	return nil, ""
+}
+
+// findDeleteEndpoint finds a delete-type API endpoint matching the model.
+func findDeleteEndpoint(app *ir.Application, modelName string) *ir.Endpoint {
+	lowerModel := strings.ToLower(modelName)
+	for _, ep := range app.APIs {
+		lower := strings.ToLower(ep.Name)
+		if strings.HasPrefix(lower, "delete") && strings.Contains(lower, lowerModel) {
+			return ep
+		}
+	}
+	return nil
+}
+
+// writeLoadMore emits the state and effect of an infinitely scrolling
+// list. An IntersectionObserver watches a sentinel below the list and
+// fetches the page after nextCursor when it comes into view; the effect
+// runs again after each page, so a short page that leaves the sentinel in
+// view loads the next one too. A failed fetch stops loading more.
+func writeLoadMore(b *strings.Builder, listEp *ir.Endpoint, ctx *pageContext) {
+	setter := "set" + capitalize(ctx.varName)
+	b.WriteString("\n  useEffect(() => {\n")
+	b.WriteString("    const el = sentinel.current;\n")
+	b.WriteString("    if (!el || !nextCursor || loadingMore) return;\n")
+	b.WriteString("    const observer = new IntersectionObserver(async ([entry]) => {\n")
+	b.WriteString("      if (!entry.isIntersecting) return;\n")
+	b.WriteString("      observer.disconnect();\n")
+	b.WriteString("      setLoadingMore(true);\n")
+	b.WriteString("      try {\n")
+	fmt.Fprintf(b, "        const res = await %s(nextCursor);\n", toCamelCase(listEp.Name))
+	fmt.Fprintf(b, "        %s(prev => [...prev, ...(res.data ?? [])]);\n", setter)
+	b.WriteString("        setNextCursor(res.pagination?.nextCursor ?? null);\n")
+	b.WriteString("      } catch {\n")
+	b.WriteString("        setNextCursor(null);\n")
+	b.WriteString("      } finally {\n")
+	b.WriteString("        setLoadingMore(false);\n")
+	b.WriteString("      }\n")
+	b.WriteString("    }, { rootMargin: '200px' });\n")
+	b.WriteString("    observer.observe(el);\n")
+	b.WriteString("    return () => observer.disconnect();\n")
+	b.WriteString("  }, [nextCursor, loadingMore]);\n")
+}
+
+// writeListEnd emits what follows the page's list: the sentinel loading
+// more of a scrolling list, or the controls paging a paginated one unless
+// the page places them itself.
+func writeListEnd(b *strings.Builder, indent string, page *ir.Page, ctx *pageContext) {
+	switch {
+	case ctx.scroll:
+		writeLoadMoreSentinel(b, indent)
+	case ctx.query != nil && ctx.query.Paged && !ir.ShowsPagination(page):
+		writePaginationJSX(b, indent)
+	}
+}
+
+// writeLoadMoreSentinel emits the element below a scrolling list whose
+// coming into view loads the next page.
+func writeLoadMoreSentinel(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<div ref={sentinel} className=\"load-more\">\n", indent)
+	fmt.Fprintf(b, "%s  {loadingMore && <div className=\"spinner\" />}\n", indent)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
+
+// writeDeleteHandler emits the page's handler for an item's Delete button:
+// it removes the item at once and puts the list back if deleting fails.
+func writeDeleteHandler(b *strings.Builder, ep *ir.Endpoint, ctx *pageContext) {
+	setter := "set" + capitalize(ctx.varName)
+	item := ctx.itemVar
+	fmt.Fprintf(b, "\n  async function handleDelete(%s: %s) {\n", item, ctx.modelName)
+	fmt.Fprintf(b, "    const previous = %s;\n", ctx.varName)
+	fmt.Fprintf(b, "    %s(prev => prev.filter(other => other.id !== %s.id));\n", setter, item)
+	b.WriteString("    try {\n")
+	fmt.Fprintf(b, "      const res = await %s({ %s: %s.id });\n", toCamelCase(ep.Name), sanitizeParamName(ep.Params[0].Name), item)
+	fmt.Fprintf(b, "      if (res.error) %s(previous);\n", setter)
+	b.WriteString("    } catch {\n")
+	fmt.Fprintf(b, "      %s(previous);\n", setter)
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+}
+
+// writeDeleteButton emits a list item's delete button. When clicking the
+// item opens a detail panel or another page, clicking the button doesn't.
+func writeDeleteButton(b *strings.Builder, indent string, ctx *pageContext) {
+	if ctx.panel != nil || ctx.itemPage != "" {
+		fmt.Fprintf(b, "%s<button className=\"delete-button\" onClick={(ev) => { ev.stopPropagation(); handleDelete(%s); }}>%s</button>\n", indent, ctx.itemVar, ctx.deleteLabel)
+		return
+	}
+	fmt.Fprintf(b, "%s<button className=\"delete-button\" onClick={() => handleDelete(%s)}>%s</button>\n", indent, ctx.itemVar, ctx.deleteLabel)
+}
+
+// writeOptimisticCreate emits the body of a create form's submit handler
+// that shows the new record in the list at once, under a temporary id,
+// swaps in the saved record when the API answers, and takes it out again
+// if saving fails. Paginated lists show their newest records first, so the
+// record goes at the top of those.
+func writeOptimisticCreate(b *strings.Builder, createFunc, indent string, ctx *pageContext) {
+	setter := "set" + capitalize(ctx.varName)
+	fmt.Fprintf(b, "%s  const form = ev.currentTarget;\n", indent)
+	fmt.Fprintf(b, "%s  const values = Object.fromEntries(new FormData(form));\n", indent)
+	fmt.Fprintf(b, "%s  const tempId = `temp-${Date.now()}`;\n", indent)
+	pending := fmt.Sprintf("{ ...values, id: tempId } as unknown as %s", ctx.modelName)
+	if ctx.newestFirst {
+		fmt.Fprintf(b, "%s  %s(prev => [%s, ...prev]);\n", indent, setter, pending)
+	} else {
+		fmt.Fprintf(b, "%s  %s(prev => [...prev, %s]);\n", indent, setter, pending)
+	}
+	if ctx.needsFormState {
+		fmt.Fprintf(b, "%s  setShowForm(false);\n", indent)
+	}
+	fmt.Fprintf(b, "%s  form.reset();\n", indent)
+	fmt.Fprintf(b, "%s  try {\n", indent)
+	fmt.Fprintf(b, "%s    const res = await %s(values);\n", indent, createFunc)
+	fmt.Fprintf(b, "%s    if (res.error) throw new Error(res.error);\n", indent)
+	fmt.Fprintf(b, "%s    %s(prev => prev.map(other => (other.id === tempId ? res.data : other)));\n", indent, setter)
+	if ctx.hasSuccessState {
+		fmt.Fprintf(b, "%s    setSuccess('Created successfully');\n", indent)
+	}
+	fmt.Fprintf(b, "%s  } catch (err) {\n", indent)
+	fmt.Fprintf(b, "%s    %s(prev => prev.filter(other => other.id !== tempId));\n", indent, setter)
+	if ctx.hasErrorState {
+		fmt.Fprintf(b, "%s    setError(err instanceof Error ? err.message : 'Failed to save');\n", indent)
+	}
+	fmt.Fprintf(b, "%s  }\n", indent)
+}
@@ -0,0 +1,137 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeUsageClient appends the usage endpoints to the API client: the
+// signed-in user's calls this period against their quota, and their calls
+// in each of the last periods.
+func writeUsageClient(b *strings.Builder) {
+	b.WriteString(`
+export interface UsageSummary {
+  period: string;
+  used: number;
+  limit: number | null;
+  resetsAt: string;
+}
+
+export interface UsagePeriod {
+  start: string;
+  calls: number;
+}
+
+export async function getUsageSummary() {
+  return request<UsageSummary>('GET', '/api/usage');
+}
+
+export async function getUsageHistory(periods = 30) {
+  return request<UsagePeriod[]>('GET', ` + "`/api/usage/history?periods=${periods}`" + `);
+}
+`)
+}
+
+// generateUsagePage produces the usage dashboard: the signed-in user's
+// calls this period against their quota and when it resets, and a bar for
+// each of the last periods. When the app bills, a user who has made all
+// their calls is pointed to the pricing page.
+func generateUsagePage(page *ir.Page, app *ir.Application) string {
+	var b strings.Builder
+	period := app.Metering.Period
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { useEffect, useState } from 'react';\n")
+	if ir.Bills(app) {
+		b.WriteString("import { Link } from 'react-router-dom';\n")
+	}
+	b.WriteString("import { getUsageSummary, getUsageHistory, errorMessage } from '../api/client';\n")
+	b.WriteString("import type { UsageSummary, UsagePeriod } from '../api/client';\n\n")
+
+	locale := "undefined"
+	if l := ir.Locale(app); l != "" {
+		locale = "'" + l + "'"
+	}
+	fmt.Fprintf(&b, "const count = new Intl.NumberFormat(%s);\n", locale)
+	fmt.Fprintf(&b, "const resetTime = new Intl.DateTimeFormat(%s, { dateStyle: 'medium', timeStyle: 'short' });\n", locale)
+	b.WriteString("// Periods start at midnight UTC, so they're labeled by their UTC date\n")
+	label, heading := "Day", "Calls per day"
+	switch period {
+	case "week":
+		label, heading = "Week of", "Calls per week"
+		fmt.Fprintf(&b, "const periodLabel = new Intl.DateTimeFormat(%s, { dateStyle: 'medium', timeZone: 'UTC' });\n\n", locale)
+	case "month":
+		label, heading = "Month", "Calls per month"
+		fmt.Fprintf(&b, "const periodLabel = new Intl.DateTimeFormat(%s, { month: 'long', year: 'numeric', timeZone: 'UTC' });\n\n", locale)
+	default:
+		fmt.Fprintf(&b, "const periodLabel = new Intl.DateTimeFormat(%s, { dateStyle: 'medium', timeZone: 'UTC' });\n\n", locale)
+	}
+
+	fmt.Fprintf(&b, "export default function %sPage() {\n", page.Name)
+	b.WriteString("  const [usage, setUsage] = useState<UsageSummary | null>(null);\n")
+	b.WriteString("  const [history, setHistory] = useState<UsagePeriod[]>([]);\n")
+	b.WriteString("  const [loading, setLoading] = useState(true);\n")
+	b.WriteString("  const [error, setError] = useState('');\n\n")
+	b.WriteString("  useEffect(() => {\n")
+	b.WriteString("    Promise.all([getUsageSummary(), getUsageHistory()])\n")
+	b.WriteString("      .then(([summary, periods]) => {\n")
+	b.WriteString("        setUsage(summary.data);\n")
+	b.WriteString("        setHistory(periods.data);\n")
+	b.WriteString("      })\n")
+	b.WriteString("      .catch(err => setError(errorMessage(err, 'Could not load your usage')))\n")
+	b.WriteString("      .finally(() => setLoading(false));\n")
+	b.WriteString("  }, []);\n\n")
+	b.WriteString("  // Bars are scaled to the busiest period\n")
+	b.WriteString("  const busiest = Math.max(1, ...history.map(p => p.calls));\n")
+	b.WriteString("  const reached = usage !== null && usage.limit !== null && usage.used >= usage.limit;\n\n")
+
+	b.WriteString("  return (\n")
+	fmt.Fprintf(&b, "    <div className=\"%s-page\">\n", toKebabCase(page.Name))
+	fmt.Fprintf(&b, "      <h1>%s</h1>\n", ir.FieldLabel(page.Name))
+	b.WriteString("      {loading && <p role=\"status\">Loading…</p>}\n")
+	b.WriteString("      {error && <p className=\"form-error\" role=\"alert\">{error}</p>}\n")
+	b.WriteString("      {usage && (\n")
+	b.WriteString("        <section className=\"usage-current\" aria-labelledby=\"usage-current\">\n")
+	b.WriteString("          <h2 id=\"usage-current\">This {usage.period}</h2>\n")
+	b.WriteString("          <p>\n")
+	b.WriteString("            {usage.limit === null\n")
+	b.WriteString("              ? `${count.format(usage.used)} API calls`\n")
+	b.WriteString("              : `${count.format(usage.used)} of ${count.format(usage.limit)} API calls`}\n")
+	b.WriteString("          </p>\n")
+	b.WriteString("          {usage.limit !== null && <progress value={Math.min(usage.used, usage.limit)} max={usage.limit} aria-label=\"API calls used\" />}\n")
+	b.WriteString("          <p>Resets {resetTime.format(new Date(usage.resetsAt))}</p>\n")
+	if ir.Bills(app) {
+		fmt.Fprintf(&b, "          {reached && <p role=\"alert\">You've made all your API calls this {usage.period}. <Link to=\"%s\">Upgrade your plan</Link> for more.</p>}\n", app.Billing.PagePath())
+	} else {
+		b.WriteString("          {reached && <p role=\"alert\">You've made all your API calls this {usage.period}.</p>}\n")
+	}
+	b.WriteString("        </section>\n")
+	b.WriteString("      )}\n")
+	b.WriteString("      {history.length > 0 && (\n")
+	b.WriteString("        <section className=\"usage-history\" aria-labelledby=\"usage-history\">\n")
+	fmt.Fprintf(&b, "          <h2 id=\"usage-history\">%s</h2>\n", heading)
+	b.WriteString("          <table>\n")
+	b.WriteString("            <thead>\n")
+	fmt.Fprintf(&b, "              <tr><th scope=\"col\">%s</th><th scope=\"col\">Calls</th></tr>\n", label)
+	b.WriteString("            </thead>\n")
+	b.WriteString("            <tbody>\n")
+	b.WriteString("              {[...history].reverse().map(p => (\n")
+	b.WriteString("                <tr key={p.start}>\n")
+	b.WriteString("                  <td>{periodLabel.format(new Date(p.start))}</td>\n")
+	b.WriteString("                  <td>\n")
+	b.WriteString("                    <span className=\"usage-bar\" style={{ width: `${(p.calls / busiest) * 100}%` }} aria-hidden=\"true\" />\n")
+	b.WriteString("                    {count.format(p.calls)}\n")
+	b.WriteString("                  </td>\n")
+	b.WriteString("                </tr>\n")
+	b.WriteString("              ))}\n")
+	b.WriteString("            </tbody>\n")
+	b.WriteString("          </table>\n")
+	b.WriteString("        </section>\n")
+	b.WriteString("      )}\n")
+	b.WriteString("    </div>\n")
+	b.WriteString("  );\n")
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,114 @@
+package react
+
+import "strings"
+
+// writeNotificationsClient appends the in-app notification endpoints to the
+// API client.
+func writeNotificationsClient(b *strings.Builder) {
+	b.WriteString(`
+export async function listNotifications() {
+  return request<Notification[]>('GET', '/api/notifications');
+}
+
+export async function getUnreadNotificationCount() {
+  return request<{ count: number }>('GET', '/api/notifications/unread-count');
+}
+
+export async function markNotificationRead(id: string) {
+  return request<Notification>('POST', ` + "`/api/notifications/${encodeURIComponent(id)}/read`" + `);
+}
+
+export async function markAllNotificationsRead() {
+  return request<{ count: number }>('POST', '/api/notifications/read-all');
+}
+`)
+}
+
+// generateNotificationBell produces src/components/NotificationBell.tsx: a
+// dropdown of the signed-in user's notifications. The unread badge polls
+// the backend every 30 seconds.
+func generateNotificationBell() string {
+	return `// Generated by Human compiler — do not edit
+
+import { useCallback, useEffect, useState } from 'react';
+import type { Notification } from '../types/models';
+import {
+  getUnreadNotificationCount,
+  listNotifications,
+  markAllNotificationsRead,
+  markNotificationRead,
+} from '../api/client';
+
+const POLL_INTERVAL_MS = 30_000;
+
+export default function NotificationBell() {
+  const [open, setOpen] = useState(false);
+  const [unread, setUnread] = useState(0);
+  const [notifications, setNotifications] = useState<Notification[]>([]);
+  const [signedIn, setSignedIn] = useState(() => !!localStorage.getItem('token'));
+
+  // Polling also notices signing in and out, since the token lives in localStorage.
+  const refreshCount = useCallback(() => {
+    const hasToken = !!localStorage.getItem('token');
+    setSignedIn(hasToken);
+    if (!hasToken) return;
+    getUnreadNotificationCount()
+      .then((res) => setUnread(res.data?.count ?? 0))
+      .catch(() => {});
+  }, []);
+
+  useEffect(() => {
+    refreshCount();
+    const timer = setInterval(refreshCount, POLL_INTERVAL_MS);
+    return () => clearInterval(timer);
+  }, [refreshCount]);
+
+  useEffect(() => {
+    if (!open) return;
+    listNotifications()
+      .then((res) => setNotifications(res.data ?? []))
+      .catch(() => {});
+  }, [open]);
+
+  if (!signedIn) return null;
+
+  const markRead = async (notification: Notification) => {
+    if (notification.seen) return;
+    await markNotificationRead(notification.id);
+    setNotifications((list) => list.map((n) => (n.id === notification.id ? { ...n, seen: new Date().toISOString() } : n)));
+    setUnread((count) => Math.max(0, count - 1));
+  };
+
+  const markAllRead = async () => {
+    await markAllNotificationsRead();
+    setNotifications((list) => list.map((n) => ({ ...n, seen: n.seen ?? new Date().toISOString() })));
+    setUnread(0);
+  };
+
+  return (
+    <div style={{ position: 'fixed', top: '1rem', right: '1rem', zIndex: 1000 }}>
+      <button type="button" aria-label="Notifications" aria-expanded={open} onClick={() => setOpen(!open)}>
+        🔔{unread > 0 && <span style={{ marginLeft: '0.25rem', fontWeight: 'bold' }}>{unread}</span>}
+      </button>
+      {open && (
+        <div role="menu" style={{ position: 'absolute', right: 0, marginTop: '0.5rem', width: '20rem', maxHeight: '24rem', overflowY: 'auto', background: 'white', border: '1px solid #e5e7eb', borderRadius: '0.5rem', boxShadow: '0 4px 12px rgba(0,0,0,0.1)' }}>
+          <div style={{ display: 'flex', justifyContent: 'space-between', padding: '0.5rem 0.75rem', borderBottom: '1px solid #e5e7eb' }}>
+            <strong>Notifications</strong>
+            {unread > 0 && <button type="button" onClick={markAllRead}>Mark all read</button>}
+          </div>
+          {notifications.length === 0 ? (
+            <p style={{ padding: '0.75rem', color: '#6b7280' }}>No notifications yet</p>
+          ) : (
+            notifications.map((n) => (
+              <div key={n.id} role="menuitem" onClick={() => markRead(n)} style={{ padding: '0.75rem', cursor: 'pointer', fontWeight: n.seen ? 'normal' : 'bold', borderBottom: '1px solid #f3f4f6' }}>
+                {n.message}
+              </div>
+            ))
+          )}
+        </div>
+      )}
+    </div>
+  );
+}
+`
+}
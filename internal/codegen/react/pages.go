@@ -19,10 +19,48 @@ type pageContext struct {
 	hasSuccessState bool              // whether setSuccess is available
 	hasErrorState   bool              // whether setError is available
 	needsFormState  bool              // whether setShowForm is available
+	table           *ir.Table         // the page's "show tasks in a table", if any
+	query           *ir.ListQuery     // the view of the list the page's URL holds, if any
+	reorder         *ir.Reorder       // the list the page lets users drag, if any
+	scroll          bool              // whether the list loads more records as the user nears its end
+	remember        bool              // whether the page restores its list as the user left it
+	newestFirst     bool              // whether new records go at the top of the list
+	deleteEp        *ir.Endpoint      // endpoint each item's delete button calls, if any
+	deleteLabel     string            // label of that button
+	tooltips        []*ir.Tooltip     // tooltips shown over the listed records or the component
+	panel           *ir.DetailPanel   // panel clicking a listed record opens, if any
+	itemPage        string            // page clicking a listed record navigates to, if any
+	itemLink        *ir.Action        // the interaction saying so
+	record          *ir.DetailRoute   // the record the page's URL names, if any
+	edit            *ir.EditForm      // the form editing that record, if any
+	importForm      *ir.ImportForm    // the form uploading a file of records to import, if any
+	timeHelpers     map[string]bool   // time helpers the page uses from the API client
+	formatHelpers   map[string]bool   // number helpers the page uses
+	loadError       string            // message shown when loading the page's data fails, if it loads any
+	itemFields      []string          // fields each listed record shows, shaping its loading skeleton
+	responsive      *ir.Responsive    // how the page adapts to narrower screens, if it declares so
+	fieldErrors     bool              // whether the page tracks the form input found invalid
+	ids             map[string]int    // element ids given out on the page, by base
 }
 
 // generatePage produces a React page component from an IR Page.
 func generatePage(page *ir.Page, app *ir.Application) string {
+	if ir.IsAccountPage(app, page) {
+		return generateAccountPage(page, app)
+	}
+	if ir.IsInvitePage(app, page) {
+		return generateInvitePage(page, app)
+	}
+	if ir.IsPricingPage(app, page) {
+		return generatePricingPage(page, app)
+	}
+	if ir.IsUsagePage(app, page) {
+		return generateUsagePage(page, app)
+	}
+	if ir.IsAPIKeysPage(app, page) {
+		return generateAPIKeysPage(page, app)
+	}
+
 	var b strings.Builder
 
 	b.WriteString("// Generated by Human compiler — do not edit\n\n")
@@ -54,9 +92,22 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			if strings.Contains(lower, "opens a form") || strings.Contains(lower, "open a form") {
 				needsFormState = true
 			}
+			if kb, ok := ir.ParseKeyBinding(a.Text); ok {
+				switch kb.Action {
+				case "open-form":
+					needsFormState = true
+				case "navigate":
+					needsNavigate = true
+				}
+			}
 		case "query":
 			needsDataState = true
 			needsEffect = true
+		case "display":
+			if ir.IsTable(a) {
+				needsDataState = true
+				needsEffect = true
+			}
 		case "loop":
 			needsDataState = true
 			if modelName != "" {
@@ -68,7 +119,7 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 				needsFormState = true
 			}
 			// Inline form that calls a create/login endpoint needs the import
-			if strings.Contains(lower, "form to") {
+			if strings.Contains(lower, "form to") && !ir.IsImportForm(a) {
 				needsCreateImport = true
 			}
 		case "condition":
@@ -97,35 +148,96 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 		hasSuccessState: needsSuccess,
 		hasErrorState:   needsError,
 		needsFormState:  needsFormState,
+		table:           ir.TableFor(app, page, modelName),
+		reorder:         pageReorder(page, app, modelName),
+		record:          ir.DetailRouteFor(app, page.Name),
+		edit:            ir.EditFormFor(app, page),
+		importForm:      ir.ImportFormFor(app, page),
+		responsive:      ir.ResponsiveFor(page),
+		fieldErrors:     needsFormState || needsCreateImport,
+	}
+	collapseNav := ctx.responsive != nil && ctx.responsive.Mobile && pageHasNavbar(page)
+	if ctx.table != nil && ctx.table.RowPage != "" {
+		needsNavigate = true
+	}
+	keyBindings := ir.PageKeyBindings(page)
+	var listEp *ir.Endpoint
+	if needsEffect && modelName != "" {
+		listEp = findListEndpoint(app, modelName)
+	}
+	ctx.scroll = pageScrolls(page, app, modelName, listEp)
+	if needsDataState {
+		ctx.query = ir.ListQueryFor(app, page, modelName, listEp)
+	}
+	ctx.newestFirst = ir.ListsNewestFirst(app, listEp, modelName)
+	ctx.remember = needsEffect && ir.RemembersView(page)
+	if needsDataState {
+		ctx.deleteEp, ctx.deleteLabel = pageDelete(page, app, modelName)
+		ctx.tooltips = pageTooltips(page, app, modelName)
+		ctx.panel = pagePanel(page, app, modelName)
+		if ctx.table == nil {
+			ctx.itemPage, ctx.itemLink = ir.ItemLinkFor(app, page, modelName)
+		}
+	}
+	if ctx.itemPage != "" {
+		needsNavigate = true
+	}
+	if needsEffect {
+		subject := varName
+		if subject == "" {
+			subject = "data"
+		}
+		ctx.loadError = ir.LoadErrorMessage(page, strings.ToLower(ir.FieldLabel(subject)))
+	} else if ctx.record != nil {
+		ctx.loadError = ir.LoadErrorMessage(page, "the "+strings.ToLower(ir.FieldLabel(ctx.record.Model)))
 	}
 
 	// Write imports (react-jsx transform — no React import needed)
-	needsUseState := needsDataState || needsAuth || needsFormState || needsSuccess || needsError
+	needsUseState := needsDataState || needsAuth || needsFormState || needsSuccess || needsError || ctx.record != nil || collapseNav || ctx.fieldErrors || ctx.importForm != nil
 	reactImports := []string{}
 	if needsUseState {
 		reactImports = append(reactImports, "useState")
 	}
-	if needsEffect {
+	if needsEffect || len(keyBindings) > 0 || ctx.record != nil || needsFormState {
 		reactImports = append(reactImports, "useEffect")
 	}
+	if ctx.scroll || needsFormState || ctx.remember {
+		reactImports = append(reactImports, "useRef")
+	}
+	if ctx.fieldErrors {
+		reactImports = append(reactImports, "type FormEvent", "type InvalidEvent")
+	}
 	if len(reactImports) > 0 {
 		fmt.Fprintf(&b, "import { %s } from 'react';\n", strings.Join(reactImports, ", "))
 	}
+	var routerImports []string
 	if needsNavigate {
-		b.WriteString("import { useNavigate } from 'react-router-dom';\n")
+		routerImports = append(routerImports, "useNavigate")
+	}
+	if ctx.record != nil {
+		routerImports = append(routerImports, "useParams")
+	}
+	if ctx.query != nil {
+		routerImports = append(routerImports, "useSearchParams")
+	}
+	if len(routerImports) > 0 {
+		fmt.Fprintf(&b, "import { %s } from 'react-router-dom';\n", strings.Join(routerImports, ", "))
 	}
 
 	// Import model type when we have typed data
+	var types []string
 	if modelName != "" {
-		fmt.Fprintf(&b, "import { %s } from '../types/models';\n", modelName)
+		types = append(types, modelName)
+	}
+	if ctx.record != nil && ctx.record.Model != modelName {
+		types = append(types, ctx.record.Model)
+	}
+	if len(types) > 0 {
+		fmt.Fprintf(&b, "import { %s } from '../types/models';\n", strings.Join(types, ", "))
 	}
 
 	// Import API client functions for data fetching and form submission
-	var listEp *ir.Endpoint
 	var createEp *ir.Endpoint
-	if needsEffect && modelName != "" {
-		listEp = findListEndpoint(app, modelName)
-	}
 	if (needsFormState || needsCreateImport) && modelName != "" {
 		createEp = findCreateEndpoint(app, modelName)
 	}
@@ -150,16 +262,56 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			apiImports = append(apiImports, fn)
 		}
 	}
+	if ctx.deleteEp != nil {
+		apiImports = append(apiImports, toCamelCase(ctx.deleteEp.Name))
+	}
+	if ctx.reorder != nil {
+		apiImports = append(apiImports, "reorder")
+	}
+	if ctx.record != nil {
+		apiImports = append(apiImports, toCamelCase(ctx.record.Endpoint), "ApiError")
+	}
+	if ctx.edit != nil {
+		apiImports = append(apiImports, editImports(ctx.edit)...)
+	}
+	if ctx.importForm != nil {
+		apiImports = append(apiImports, importImports(ctx.importForm)...)
+	}
+	if ctx.loadError != "" || ctx.edit != nil || ctx.importForm != nil {
+		apiImports = append(apiImports, "errorMessage")
+	}
+	if needsEffect && listEp == nil {
+		apiImports = append(apiImports, "request")
+	}
 	if len(apiImports) > 0 {
 		fmt.Fprintf(&b, "import { %s } from '../api/client';\n", strings.Join(apiImports, ", "))
-	} else if needsEffect {
-		b.WriteString("import { request } from '../api/client';\n")
 	}
 
 	// Component imports
 	for _, comp := range detectUsedComponents(page) {
 		fmt.Fprintf(&b, "import %s from '../components/%s';\n", comp, comp)
 	}
+	if pageLoopsOverCalendar(page, app, modelName) {
+		b.WriteString("import AddToCalendar from '../components/AddToCalendar';\n")
+	}
+	if pageHasChart(page, app) {
+		b.WriteString("import DataChart from '../components/DataChart';\n")
+	}
+	if ctx.reorder != nil {
+		b.WriteString("import SortableList from '../components/SortableList';\n")
+	}
+	if len(ctx.tooltips) > 0 {
+		b.WriteString("import Tooltip from '../components/Tooltip';\n")
+	}
+	if ctx.panel != nil {
+		b.WriteString("import DetailPanel from '../components/DetailPanel';\n")
+	}
+	if pageHasSkeleton(page) {
+		b.WriteString("import Skeleton from '../components/Skeleton';\n")
+	}
+	if ctx.responsive != nil && ir.Styling(app) != ir.StylingCSSModules {
+		fmt.Fprintf(&b, "import './%sPage.css';\n", page.Name)
+	}
 
 	b.WriteString("\n")
 
@@ -180,11 +332,29 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			b.WriteString("  const [data, setData] = useState<unknown[]>([]);\n")
 		}
 	}
+	if ctx.scroll {
+		b.WriteString("  const [nextCursor, setNextCursor] = useState<string | null>(null);\n")
+		b.WriteString("  const [loadingMore, setLoadingMore] = useState(false);\n")
+		b.WriteString("  const sentinel = useRef<HTMLDivElement>(null);\n")
+	}
+	if ctx.panel != nil {
+		fmt.Fprintf(&b, "  const [selected, setSelected] = useState<%s | null>(null);\n", modelName)
+	}
+	if ctx.query != nil {
+		writeListQueryState(&b, ctx)
+	}
 	if needsAuth {
 		b.WriteString("  const [isLoggedIn] = useState(!!localStorage.getItem('token'));\n")
 	}
+	if collapseNav {
+		b.WriteString("  const [navOpen, setNavOpen] = useState(false);\n")
+	}
 	if needsFormState {
 		b.WriteString("  const [showForm, setShowForm] = useState(false);\n")
+		b.WriteString("  const dialog = useRef<HTMLDivElement>(null);\n")
+	}
+	if ctx.fieldErrors {
+		writeFieldErrorState(&b)
 	}
 	if needsSuccess {
 		b.WriteString("  const [success, setSuccess] = useState('');\n")
@@ -192,6 +362,21 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	if needsError {
 		b.WriteString("  const [error, setError] = useState('');\n")
 	}
+	if ctx.loadError != "" {
+		writeLoadErrorState(&b)
+	}
+	if ctx.record != nil {
+		writeRecordState(&b, ctx)
+	}
+	if ctx.edit != nil {
+		writeEditState(&b, ctx)
+	}
+	if ctx.importForm != nil {
+		writeImportState(&b, ctx)
+	}
+	if ctx.remember {
+		writeRememberState(&b, ctx)
+	}
 
 	if needsEffect {
 		setterName := "setData"
@@ -199,21 +384,63 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			setterName = "set" + capitalize(varName)
 		}
 		b.WriteString("\n  useEffect(() => {\n")
-		if listEp != nil {
+		if listEp != nil && ctx.query != nil && ctx.query.Paged {
+			if ctx.remember {
+				b.WriteString("    if (restoringView) return;\n")
+			}
+			b.WriteString("    setLoading(true);\n")
+			fmt.Fprintf(&b, "    %s(undefined, %s)\n", toCamelCase(listEp.Name), listQueryArgs(ctx))
+			fmt.Fprintf(&b, "      .then(res => { %s(res.data ?? []); setHasNext(res.pagination?.nextCursor != null); setLoading(false); })\n", setterName)
+			fmt.Fprintf(&b, "      .catch(err => { %s; setLoading(false); });\n", loadFailed(ctx))
+		} else if listEp != nil && ctx.scroll {
+			fmt.Fprintf(&b, "    %s()\n", toCamelCase(listEp.Name))
+			fmt.Fprintf(&b, "      .then(res => { %s(res.data ?? []); setNextCursor(res.pagination?.nextCursor ?? null); setLoading(false); })\n", setterName)
+			fmt.Fprintf(&b, "      .catch(err => { %s; setLoading(false); });\n", loadFailed(ctx))
+		} else if listEp != nil {
 			fmt.Fprintf(&b, "    %s()\n", toCamelCase(listEp.Name))
 			fmt.Fprintf(&b, "      .then(res => { %s(res.data ?? []); setLoading(false); })\n", setterName)
-			b.WriteString("      .catch(() => setLoading(false));\n")
+			fmt.Fprintf(&b, "      .catch(err => { %s; setLoading(false); });\n", loadFailed(ctx))
 		} else {
 			b.WriteString("    // TODO: replace with a dedicated API endpoint\n")
 			fmt.Fprintf(&b, "    request('GET', '/api/%s')\n", toKebabCase(varName))
 			fmt.Fprintf(&b, "      .then(res => { %s(res.data ?? []); setLoading(false); })\n", setterName)
-			b.WriteString("      .catch(() => setLoading(false));\n")
+			fmt.Fprintf(&b, "      .catch(err => { %s; setLoading(false); });\n", loadFailed(ctx))
 		}
-		b.WriteString("  }, []);\n")
+		if ctx.query != nil && ctx.query.Paged {
+			deps := "attempt, searchParams"
+			if len(ctx.query.Sortable) > 0 {
+				deps += ", sortKey, sortDesc"
+			}
+			fmt.Fprintf(&b, "  }, [%s]);\n", deps)
+		} else {
+			b.WriteString("  }, [attempt]);\n")
+		}
+	}
+	if ctx.remember {
+		writeRememberEffects(&b, ctx)
+	}
+	if ctx.loadError != "" {
+		writeRetry(&b, needsDataState)
+	}
+	if ctx.scroll {
+		writeLoadMore(&b, listEp, ctx)
+	}
+	if ctx.deleteEp != nil {
+		writeDeleteHandler(&b, ctx.deleteEp, ctx)
+	}
+	if ctx.reorder != nil {
+		writeReorderHandler(&b, ctx.reorder, ctx)
+	}
+	if needsFormState {
+		writeDialogFocus(&b)
+	}
+	if len(keyBindings) > 0 {
+		writeKeyBindings(&b, keyBindings, ctx)
 	}
 
 	// Collect loop field names for the primary model
 	loopFields := collectLoopFields(page, ctx)
+	ctx.itemFields = loopFields
 
 	// Return JSX
 	b.WriteString("\n  return (\n")
@@ -224,9 +451,17 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	if needsDataState && !hasLoadingCondition {
 		b.WriteString("      {loading && <div className=\"loading-spinner\"><div className=\"spinner\" /></div>}\n")
 	}
+	// Pages declaring their error state show load errors there
+	if ctx.loadError != "" && !ctx.hasErrorState {
+		writeLoadErrorJSX(&b, "      ")
+	}
 
 	loopRendered := false
 	for _, a := range page.Content {
+		if ctx.table != nil && ctx.table.Covers(a) {
+			fmt.Fprintf(&b, "      {/* %s — rendered by the table */}\n", a.Text)
+			continue
+		}
 		if a.Type == "loop" && loopRendered {
 			// Skip duplicate loop actions — fields already merged into first loop
 			fmt.Fprintf(&b, "      {/* %s */}\n", a.Text)
@@ -235,41 +470,93 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 		if a.Type == "loop" {
 			loopRendered = true
 			writeLoopJSX(&b, a.Text, "      ", ctx, loopFields)
+			if ctx.table == nil {
+				writeListEnd(&b, "      ", page, ctx)
+			}
 			continue
 		}
 		writePageAction(&b, a, "      ", ctx)
+		if ctx.table != nil && a == ctx.table.Show {
+			writeListEnd(&b, "      ", page, ctx)
+		}
 	}
 
 	// Conditional form modal when showForm is toggled
 	if needsFormState {
 		b.WriteString("      {showForm && (\n")
 		b.WriteString("        <div className=\"modal-overlay\" onClick={() => setShowForm(false)}>\n")
-		b.WriteString("          <div className=\"modal\" onClick={(ev) => ev.stopPropagation()}>\n")
-		b.WriteString("            <button className=\"modal-close\" onClick={() => setShowForm(false)}>×</button>\n")
+		b.WriteString("          <div\n")
+		b.WriteString("            ref={dialog}\n")
+		b.WriteString("            className=\"modal\"\n")
+		b.WriteString("            role=\"dialog\"\n")
+		b.WriteString("            aria-modal=\"true\"\n")
+		title := ""
+		if modelName != "" {
+			title = ctx.uniqueID("new-" + toKebabCase(modelName) + "-title")
+			fmt.Fprintf(&b, "            aria-labelledby=\"%s\"\n", title)
+		} else {
+			b.WriteString("            aria-label=\"New\"\n")
+		}
+		b.WriteString("            onClick={(ev) => ev.stopPropagation()}\n")
+		b.WriteString("            onKeyDown={(ev) => ev.key === 'Escape' && setShowForm(false)}\n")
+		b.WriteString("          >\n")
+		b.WriteString("            <button type=\"button\" className=\"modal-close\" aria-label=\"Close\" onClick={() => setShowForm(false)}>×</button>\n")
 		if modelName != "" {
-			fmt.Fprintf(&b, "            <h2>New %s</h2>\n", modelName)
+			fmt.Fprintf(&b, "            <h2 id=\"%s\">New %s</h2>\n", title, modelName)
 		}
 		writeFormJSX(&b, "a form to create a "+modelName, "            ", ctx)
 		b.WriteString("          </div>\n")
 		b.WriteString("        </div>\n")
 		b.WriteString("      )}\n")
 	}
+	if ctx.panel != nil {
+		writePanelJSX(&b, "      ", ctx)
+	}
 
 	b.WriteString("    </div>\n")
 	b.WriteString("  );\n")
 	b.WriteString("}\n")
 
-	return b.String()
+	return withFormatImports(withTimeImports(b.String(), ctx), ctx)
 }
 
 // writePageAction maps an IR action to JSX elements.
 func writePageAction(b *strings.Builder, a *ir.Action, indent string, ctx *pageContext) {
+	if ctx.record != nil && ir.IsDetailRoute(a) {
+		writeRecordJSX(b, indent, ctx)
+		return
+	}
+	if ctx.edit != nil && a == ctx.edit.Form {
+		writeEditFormJSX(b, indent, ctx)
+		return
+	}
+	if ctx.importForm != nil && a == ctx.importForm.Form {
+		writeImportFormJSX(b, indent, ctx)
+		return
+	}
 	switch a.Type {
 	case "display":
+		if a.Template != "" {
+			writeTemplateJSX(b, a.Template, indent, ctx)
+			return
+		}
+		if ctx.table != nil && a == ctx.table.Show {
+			writeTableJSX(b, indent, ctx)
+			return
+		}
+		if c := ir.ChartFor(ctx.app, a); c != nil {
+			writeChartJSX(b, c, indent)
+			return
+		}
 		writeDisplayJSX(b, a.Text, indent, ctx)
 	case "input":
 		writeInputJSX(b, a.Text, indent, ctx)
 	case "interact":
+		if ir.IsReorder(a) || ir.IsKeyBinding(a) || ir.IsInfiniteScroll(a) || ir.IsDelete(a) && ctx.deleteEp != nil ||
+			tooltipWired(a, ctx) || panelWired(a, ctx) || a == ctx.itemLink {
+			writeInteractionNote(b, a, indent, ctx)
+			return
+		}
 		writeInteractJSX(b, a.Text, indent, ctx)
 	case "condition":
 		writeConditionJSX(b, a.Text, indent, ctx)
@@ -278,6 +565,14 @@ func writePageAction(b *strings.Builder, a *ir.Action, indent string, ctx *pageC
 	case "query":
 		// Queries handled by useEffect — no JSX needed
 	default:
+		if ctx.remember && ir.IsRememberView(a) {
+			fmt.Fprintf(b, "%s{/* %s — restored from sessionStorage */}\n", indent, a.Text)
+			return
+		}
+		if ctx.responsive != nil && ir.IsResponsive(a) {
+			fmt.Fprintf(b, "%s{/* %s — handled by the page's stylesheet */}\n", indent, a.Text)
+			return
+		}
 		fmt.Fprintf(b, "%s{/* TODO: %s */}\n", indent, a.Text)
 	}
 }
@@ -403,7 +698,19 @@ func writeDisplayJSX(b *strings.Builder, text string, indent string, ctx *pageCo
 	}
 
 	// Navigation / navbar
-	if strings.Contains(lower, "navigation") || strings.Contains(lower, "navbar") || strings.Contains(lower, "nav bar") || strings.Contains(lower, "menu bar") {
+	if isNavbar(lower) {
+		// On phones the links collapse behind a menu button
+		if ctx.responsive != nil && ctx.responsive.Mobile {
+			fmt.Fprintf(b, "%s<nav className={navOpen ? 'navbar open' : 'navbar'}>\n", indent)
+			fmt.Fprintf(b, "%s  <button className=\"nav-toggle\" aria-label=\"Menu\" aria-expanded={navOpen} onClick={() => setNavOpen(open => !open)}>&#9776;</button>\n", indent)
+			fmt.Fprintf(b, "%s  <div className=\"nav-links\">\n", indent)
+			fmt.Fprintf(b, "%s    <a href=\"/\">Home</a>\n", indent)
+			fmt.Fprintf(b, "%s    <a href=\"/about\">About</a>\n", indent)
+			fmt.Fprintf(b, "%s    <a href=\"/contact\">Contact</a>\n", indent)
+			fmt.Fprintf(b, "%s  </div>\n", indent)
+			fmt.Fprintf(b, "%s</nav>\n", indent)
+			return
+		}
 		fmt.Fprintf(b, "%s<nav className=\"navbar\">\n", indent)
 		fmt.Fprintf(b, "%s  <a href=\"/\">Home</a>\n", indent)
 		fmt.Fprintf(b, "%s  <a href=\"/about\">About</a>\n", indent)
@@ -427,8 +734,8 @@ func writeDisplayJSX(b *strings.Builder, text string, indent string, ctx *pageCo
 	// Modal / dialog / popup
 	if strings.Contains(lower, "modal") || strings.Contains(lower, "dialog") || strings.Contains(lower, "popup") {
 		fmt.Fprintf(b, "%s<div className=\"modal-overlay\">\n", indent)
-		fmt.Fprintf(b, "%s  <div className=\"modal\">\n", indent)
-		fmt.Fprintf(b, "%s    <button className=\"modal-close\">&times;</button>\n", indent)
+		fmt.Fprintf(b, "%s  <div className=\"modal\" role=\"dialog\" aria-modal=\"true\">\n", indent)
+		fmt.Fprintf(b, "%s    <button type=\"button\" className=\"modal-close\" aria-label=\"Close\">&times;</button>\n", indent)
 		fmt.Fprintf(b, "%s    <div className=\"modal-body\">{/* TODO: modal content */}</div>\n", indent)
 		fmt.Fprintf(b, "%s  </div>\n", indent)
 		fmt.Fprintf(b, "%s</div>\n", indent)
@@ -501,6 +808,10 @@ func writeDisplayJSX(b *strings.Builder, text string, indent string, ctx *pageCo
 
 	// Pagination
 	if strings.Contains(lower, "pagination") || strings.Contains(lower, "page controls") {
+		if ctx.query != nil && ctx.query.Paged {
+			writePaginationJSX(b, indent)
+			return
+		}
 		fmt.Fprintf(b, "%s<nav className=\"pagination\">\n", indent)
 		fmt.Fprintf(b, "%s  <button className=\"page-btn\" disabled>&laquo; Prev</button>\n", indent)
 		fmt.Fprintf(b, "%s  <span className=\"page-number\">1</span>\n", indent)
@@ -532,25 +843,76 @@ func writeDisplayJSX(b *strings.Builder, text string, indent string, ctx *pageCo
 	fmt.Fprintf(b, "%s<div className=\"%s\" />\n", indent, slugify(text))
 }
 
+// writeTemplateJSX renders show "..." text. References interpolate a
+// component's props, or a page's data from its first loaded record; React
+// escapes the values, and the text is escaped for JSX here.
+func writeTemplateJSX(b *strings.Builder, tmpl string, indent string, ctx *pageContext) {
+	var out strings.Builder
+	for _, part := range ir.ParseTemplate(tmpl) {
+		if part.Root == "" {
+			out.WriteString(jsxText.Replace(part.Text))
+			continue
+		}
+		fmt.Fprintf(&out, "{%s}", templateExpr(part, ctx))
+	}
+	fmt.Fprintf(b, "%s<p>%s</p>\n", indent, out.String())
+}
+
+var jsxText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "{", "&#123;", "}", "&#125;")
+
+// templateExpr resolves a template reference: "task.title" to a prop's
+// field, or to the page's first record.
+func templateExpr(ref ir.TemplatePart, ctx *pageContext) string {
+	for name, typ := range ctx.props {
+		if strings.EqualFold(name, ref.Root) {
+			return name + templateField(findModel(ctx.app, typ), ref.Field, ".")
+		}
+	}
+	return ctx.varName + "[0]" + templateField(findModel(ctx.app, ctx.modelName), ref.Field, "?.")
+}
+
+// templateField returns sep and the model's name for field, or nothing
+// without a field.
+func templateField(model *ir.DataModel, field, sep string) string {
+	if field == "" {
+		return ""
+	}
+	if model != nil {
+		if f := model.FieldNamed(field); f != nil {
+			return sep + f.Name
+		}
+	}
+	return sep + field
+}
+
 // ── Input JSX ──
 
 func writeInputJSX(b *strings.Builder, text string, indent string, ctx *pageContext) {
 	lower := strings.ToLower(text)
 
-	if strings.Contains(lower, "search") {
-		fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" className=\"search-input\" onChange={() => {/* TODO: filter */}} />\n", indent)
+	var filter *ir.DataField
+	if ctx.query != nil {
+		filter = ir.FilterField(ctx.query.Model, &ir.Action{Type: "input", Text: text})
+	}
+
+	if strings.Contains(lower, "search") && ctx.query != nil {
+		writeSearchJSX(b, indent)
+	} else if strings.Contains(lower, "search") {
+		fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" aria-label=\"Search\" className=\"search-input\" onChange={() => {/* TODO: filter */}} />\n", indent)
+	} else if filter != nil {
+		writeFilterJSX(b, indent, filter)
 	} else if strings.Contains(lower, "dropdown") || strings.Contains(lower, "select") {
-		label := "All"
+		label, name := "All", "Filter"
 		if strings.Contains(lower, "status") {
-			label = "All Statuses"
+			label, name = "All Statuses", "Filter by status"
 		} else if strings.Contains(lower, "priority") {
-			label = "All Priorities"
+			label, name = "All Priorities", "Filter by priority"
 		}
-		fmt.Fprintf(b, "%s<select className=\"filter-select\" onChange={() => {/* TODO: filter */}}>\n", indent)
+		fmt.Fprintf(b, "%s<select className=\"filter-select\" aria-label=\"%s\" onChange={() => {/* TODO: filter */}}>\n", indent, name)
 		fmt.Fprintf(b, "%s  <option value=\"\">%s</option>\n", indent, label)
 		fmt.Fprintf(b, "%s</select>\n", indent)
 	} else if strings.Contains(lower, "date") && (strings.Contains(lower, "picker") || strings.Contains(lower, "range")) {
-		fmt.Fprintf(b, "%s<input type=\"date\" className=\"date-filter\" onChange={() => {/* TODO: filter */}} />\n", indent)
+		fmt.Fprintf(b, "%s<input type=\"date\" className=\"date-filter\" aria-label=\"Filter by date\" onChange={() => {/* TODO: filter */}} />\n", indent)
 	} else if strings.Contains(lower, "floating button") || strings.Contains(lower, "fab") {
 		label := "+"
 		if strings.Contains(lower, "add") || strings.Contains(lower, "new") || strings.Contains(lower, "create") {
@@ -564,9 +926,10 @@ func writeInputJSX(b *strings.Builder, text string, indent string, ctx *pageCont
 		if strings.Contains(lower, "avatar") {
 			label = "Upload avatar"
 		}
+		id := ctx.uniqueID("file-upload")
 		fmt.Fprintf(b, "%s<div className=\"file-upload\">\n", indent)
-		fmt.Fprintf(b, "%s  <label>%s</label>\n", indent, label)
-		fmt.Fprintf(b, "%s  <input type=\"file\" accept=\"image/*\" onChange={(ev) => { const f = ev.target.files?.[0]; if (f) { const fd = new FormData(); fd.append('file', f); fetch('/api/upload', { method: 'POST', body: fd }); } }} />\n", indent)
+		fmt.Fprintf(b, "%s  <label htmlFor=\"%s\">%s</label>\n", indent, id, label)
+		fmt.Fprintf(b, "%s  <input id=\"%s\" type=\"file\" accept=\"image/*\" onChange={(ev) => { const f = ev.target.files?.[0]; if (f) { const fd = new FormData(); fd.append('file', f); fetch('/api/upload', { method: 'POST', body: fd }); } }} />\n", indent, id)
 		fmt.Fprintf(b, "%s</div>\n", indent)
 	} else if strings.Contains(lower, "button") {
 		label := extractQuotedText(text)
@@ -575,7 +938,7 @@ func writeInputJSX(b *strings.Builder, text string, indent string, ctx *pageCont
 		}
 		fmt.Fprintf(b, "%s<button className=\"btn\">%s</button>\n", indent, label)
 	} else {
-		fmt.Fprintf(b, "%s<input type=\"text\" placeholder=\"%s\" />\n", indent, text)
+		fmt.Fprintf(b, "%s<input type=\"text\" placeholder=\"%s\" aria-label=\"%s\" />\n", indent, text, inputLabel(text))
 	}
 }
 
@@ -600,6 +963,16 @@ func writeFormJSX(b *strings.Builder, text string, indent string, ctx *pageConte
 		}
 	}
 
+	model := formModel(lower, ctx)
+	formID := "form"
+	switch {
+	case isLogin:
+		formID = "login-form"
+	case model != nil:
+		formID = toKebabCase(model.Name) + "-form"
+	}
+	formID = ctx.uniqueID(formID)
+
 	if createEp != nil {
 		createFunc := toCamelCase(createEp.Name)
 		fmt.Fprintf(b, "%s<form className=\"form\" onSubmit={async (ev) => {\n", indent)
@@ -607,30 +980,30 @@ func writeFormJSX(b *strings.Builder, text string, indent string, ctx *pageConte
 		if ctx.hasErrorState {
 			fmt.Fprintf(b, "%s  setError('');\n", indent)
 		}
-		fmt.Fprintf(b, "%s  try {\n", indent)
-		fmt.Fprintf(b, "%s    const fd = new FormData(ev.currentTarget);\n", indent)
-		fmt.Fprintf(b, "%s    const res = await %s(Object.fromEntries(fd));\n", indent, createFunc)
-		if isLogin {
-			fmt.Fprintf(b, "%s    localStorage.setItem('token', res.token);\n", indent)
-			fmt.Fprintf(b, "%s    window.location.href = '/';\n", indent)
+		if !isLogin && ctx.varName != "" && ctx.varName != "data" {
+			writeOptimisticCreate(b, createFunc, indent, ctx)
 		} else {
-			if ctx.varName != "" && ctx.varName != "data" {
-				setterName := "set" + capitalize(ctx.varName)
-				fmt.Fprintf(b, "%s    %s(prev => [...prev, res.data]);\n", indent, setterName)
-			}
-			if ctx.needsFormState {
-				fmt.Fprintf(b, "%s    setShowForm(false);\n", indent)
+			fmt.Fprintf(b, "%s  try {\n", indent)
+			fmt.Fprintf(b, "%s    const fd = new FormData(ev.currentTarget);\n", indent)
+			fmt.Fprintf(b, "%s    const res = await %s(Object.fromEntries(fd));\n", indent, createFunc)
+			if isLogin {
+				fmt.Fprintf(b, "%s    localStorage.setItem('token', res.token);\n", indent)
+				fmt.Fprintf(b, "%s    window.location.href = '/';\n", indent)
+			} else {
+				if ctx.needsFormState {
+					fmt.Fprintf(b, "%s    setShowForm(false);\n", indent)
+				}
+				if ctx.hasSuccessState {
+					fmt.Fprintf(b, "%s    setSuccess('Created successfully');\n", indent)
+				}
+				fmt.Fprintf(b, "%s    ev.currentTarget.reset();\n", indent)
 			}
-			if ctx.hasSuccessState {
-				fmt.Fprintf(b, "%s    setSuccess('Created successfully');\n", indent)
+			fmt.Fprintf(b, "%s  } catch (err) {\n", indent)
+			if ctx.hasErrorState {
+				fmt.Fprintf(b, "%s    setError(err instanceof Error ? err.message : 'Failed to save');\n", indent)
 			}
-			fmt.Fprintf(b, "%s    ev.currentTarget.reset();\n", indent)
+			fmt.Fprintf(b, "%s  }\n", indent)
 		}
-		fmt.Fprintf(b, "%s  } catch (err) {\n", indent)
-		if ctx.hasErrorState {
-			fmt.Fprintf(b, "%s    setError(err instanceof Error ? err.message : 'Failed to save');\n", indent)
-		}
-		fmt.Fprintf(b, "%s  }\n", indent)
 		fmt.Fprintf(b, "%s}}>\n", indent)
 	} else if ctx.hasSuccessState && ctx.hasErrorState {
 		fmt.Fprintf(b, "%s<form className=\"form\" onSubmit={(ev) => { ev.preventDefault(); setError(''); setSuccess('Saved successfully') }}>\n", indent)
@@ -638,33 +1011,71 @@ func writeFormJSX(b *strings.Builder, text string, indent string, ctx *pageConte
 		fmt.Fprintf(b, "%s<form className=\"form\" onSubmit={(ev) => { ev.preventDefault(); }}>\n", indent)
 	}
 
-	for _, f := range fields {
-		inputType := "text"
-		fl := strings.ToLower(f)
-		if strings.Contains(fl, "email") {
-			inputType = "email"
-		} else if strings.Contains(fl, "password") {
-			inputType = "password"
-		} else if strings.Contains(fl, "date") {
-			inputType = "date"
-		} else if strings.Contains(fl, "number") || strings.Contains(fl, "count") {
-			inputType = "number"
-		}
-		fmt.Fprintf(b, "%s  <div className=\"form-field\">\n", indent)
-		fmt.Fprintf(b, "%s    <label>%s</label>\n", indent, capitalize(f))
-		fmt.Fprintf(b, "%s    <input type=\"%s\" name=\"%s\" placeholder=\"%s\" />\n", indent, inputType, toCamelCase(f), capitalize(f))
-		fmt.Fprintf(b, "%s  </div>\n", indent)
+	for _, in := range formInputs(formID, fields, isLogin, model) {
+		writeFormFieldJSX(b, indent+"  ", in, ctx)
 	}
 	fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
 	fmt.Fprintf(b, "%s</form>\n", indent)
 }
 
+// ── Table JSX ──
+
+// writeTableJSX renders the page's table: a header per column, sortable
+// ones clickable, and a row per record.
+func writeTableJSX(b *strings.Builder, indent string, ctx *pageContext) {
+	t := ctx.table
+	rows := rowsVar(ctx)
+	fmt.Fprintf(b, "%s<table className=\"data-table\">\n", indent)
+	fmt.Fprintf(b, "%s  <thead>\n", indent)
+	fmt.Fprintf(b, "%s    <tr>\n", indent)
+	for _, f := range t.Columns {
+		label := ir.FieldLabel(f.Name)
+		if t.IsSortable(f) {
+			fmt.Fprintf(b, "%s      <th className=\"sortable\" onClick={() => sortBy('%s')}>\n", indent, f.Name)
+			fmt.Fprintf(b, "%s        %s{sortKey === '%s' && (sortDesc ? ' ▼' : ' ▲')}\n", indent, label, f.Name)
+			fmt.Fprintf(b, "%s      </th>\n", indent)
+		} else {
+			fmt.Fprintf(b, "%s      <th>%s</th>\n", indent, label)
+		}
+	}
+	fmt.Fprintf(b, "%s    </tr>\n", indent)
+	fmt.Fprintf(b, "%s  </thead>\n", indent)
+	fmt.Fprintf(b, "%s  <tbody>\n", indent)
+	fmt.Fprintf(b, "%s    {%s.map((%s) => (\n", indent, rows, ctx.itemVar)
+	if t.RowPage != "" {
+		fmt.Fprintf(b, "%s      <tr key={%s.id} className=\"clickable\" onClick={() => navigate(%s)}>\n",
+			indent, ctx.itemVar, recordURL(t.RowPage, ctx.itemVar, ctx))
+	} else if ctx.panel != nil {
+		fmt.Fprintf(b, "%s      <tr key={%s.id} className=\"clickable\"%s>\n", indent, ctx.itemVar, panelClick(ctx.itemVar, ctx))
+	} else {
+		fmt.Fprintf(b, "%s      <tr key={%s.id}>\n", indent, ctx.itemVar)
+	}
+	for _, f := range t.Columns {
+		value := fmt.Sprintf("{%s.%s}", ctx.itemVar, f.Name)
+		if f.Type == "boolean" {
+			value = fmt.Sprintf("{%s.%s ? 'Yes' : 'No'}", ctx.itemVar, f.Name)
+		} else if t := timeJSX(ctx.itemVar+"."+f.Name, f, ctx); t != "" {
+			value = t
+		} else if n := numberJSX(ctx.itemVar+"."+f.Name, f, ctx); n != "" {
+			value = n
+		}
+		if tip := tooltipOn(f.Name, ctx); tip != nil {
+			value = fmt.Sprintf("<Tooltip %s>%s</Tooltip>", tooltipText(tip, ctx.itemVar), value)
+		}
+		fmt.Fprintf(b, "%s        <td>%s</td>\n", indent, value)
+	}
+	fmt.Fprintf(b, "%s      </tr>\n", indent)
+	fmt.Fprintf(b, "%s    ))}\n", indent)
+	fmt.Fprintf(b, "%s  </tbody>\n", indent)
+	fmt.Fprintf(b, "%s</table>\n", indent)
+}
+
 // ── Loop JSX ──
 
 func writeLoopJSX(b *strings.Builder, text string, indent string, ctx *pageContext, fields []string) {
 	lower := strings.ToLower(text)
 
-	dataVar := ctx.varName
+	dataVar := rowsVar(ctx)
 	if dataVar == "" {
 		dataVar = "data"
 	}
@@ -673,13 +1084,41 @@ func writeLoopJSX(b *strings.Builder, text string, indent string, ctx *pageConte
 		item = "item"
 	}
 
+	// A list users can drag renders its items through SortableList
+	open := func() string {
+		if ctx.reorder != nil {
+			fmt.Fprintf(b, "%s<SortableList items={%s} onReorder={handleReorder}>\n", indent, dataVar)
+			fmt.Fprintf(b, "%s  {(%s) => (\n", indent, item)
+			return indent + "  "
+		}
+		fmt.Fprintf(b, "%s{%s.map((%s) => (\n", indent, dataVar, item)
+		return indent
+	}
+	close := func() {
+		if ctx.reorder != nil {
+			fmt.Fprintf(b, "%s  )}\n", indent)
+			fmt.Fprintf(b, "%s</SortableList>\n", indent)
+			return
+		}
+		fmt.Fprintf(b, "%s))}\n", indent)
+	}
+
 	// "each X as a ComponentName" — render component
 	if strings.Contains(lower, " as a ") || strings.Contains(lower, " as ") {
 		compName := extractComponentRef(text)
 		if compName != "" {
-			fmt.Fprintf(b, "%s{%s.map((%s) => (\n", indent, dataVar, item)
-			fmt.Fprintf(b, "%s  <%s key={%s.id} %s={%s} />\n", indent, compName, item, item, item)
-			fmt.Fprintf(b, "%s))}\n", indent)
+			inner := open()
+			if ctx.deleteEp != nil || ctx.panel != nil || ctx.itemPage != "" {
+				fmt.Fprintf(b, "%s  <div key={%s.id} className=\"%s-item\"%s>\n", inner, item, toKebabCase(ctx.modelName), itemClick(item, ctx))
+				fmt.Fprintf(b, "%s    <%s %s={%s} />\n", inner, compName, item, item)
+				if ctx.deleteEp != nil {
+					writeDeleteButton(b, inner+"    ", ctx)
+				}
+				fmt.Fprintf(b, "%s  </div>\n", inner)
+			} else {
+				fmt.Fprintf(b, "%s  <%s key={%s.id} %s={%s} />\n", inner, compName, item, item, item)
+			}
+			close()
 			return
 		}
 	}
@@ -689,26 +1128,68 @@ func writeLoopJSX(b *strings.Builder, text string, indent string, ctx *pageConte
 		fields = extractLoopFields(text, ctx)
 	}
 
-	fmt.Fprintf(b, "%s{%s.map((%s) => (\n", indent, dataVar, item)
-	fmt.Fprintf(b, "%s  <div key={%s.id} className=\"%s-item\">\n", indent, item, toKebabCase(ctx.modelName))
+	inner := open()
+	fmt.Fprintf(b, "%s  <div key={%s.id} className=\"%s-item\"%s>\n", inner, item, toKebabCase(ctx.modelName), itemClick(item, ctx))
+	whole := tooltipOn("", ctx)
+	if whole != nil {
+		fmt.Fprintf(b, "%s    <Tooltip %s>\n", inner, tooltipText(whole, item))
+		inner += "  "
+	}
 	if len(fields) > 0 {
 		for _, f := range fields {
 			fieldExpr := item + "." + f
+			var el string
 			if f == "status" || f == "role" || f == "priority" {
-				fmt.Fprintf(b, "%s    <span className=\"badge\">{%s}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span className=\"badge\">{%s}</span>", fieldExpr)
 			} else if f == "title" || f == "name" {
-				fmt.Fprintf(b, "%s    <h3>{%s}</h3>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<h3>{%s}</h3>", fieldExpr)
+			} else if t := timeJSX(fieldExpr, modelField(f, ctx), ctx); t != "" {
+				el = t
+			} else if n := numberJSX(fieldExpr, modelField(f, ctx), ctx); n != "" {
+				el = fmt.Sprintf("<span className=\"%s\">%s</span>", numberClass(modelField(f, ctx)), n)
 			} else if strings.Contains(f, "date") || f == "due" || f == "created" {
-				fmt.Fprintf(b, "%s    <time>{%s}</time>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<time>{%s}</time>", fieldExpr)
 			} else {
-				fmt.Fprintf(b, "%s    <span>{%s}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span>{%s}</span>", fieldExpr)
 			}
+			if tip := tooltipOn(f, ctx); tip != nil {
+				el = fmt.Sprintf("<Tooltip %s>%s</Tooltip>", tooltipText(tip, item), el)
+			}
+			fmt.Fprintf(b, "%s    %s\n", inner, el)
 		}
 	} else {
-		fmt.Fprintf(b, "%s    <span>{JSON.stringify(%s)}</span>\n", indent, item)
+		fmt.Fprintf(b, "%s    <span>{JSON.stringify(%s)}</span>\n", inner, item)
+	}
+	if whole != nil {
+		inner = inner[:len(inner)-2]
+		fmt.Fprintf(b, "%s    </Tooltip>\n", inner)
+	}
+	cal := ir.CalendarFor(ctx.app, ctx.modelName)
+	if cal != nil {
+		fmt.Fprintf(b, "%s    <AddToCalendar feed=\"%s\" id={%s.id} />\n", inner, cal.Slug, item)
+	}
+	if ctx.deleteEp != nil {
+		writeDeleteButton(b, inner+"    ", ctx)
+	}
+	fmt.Fprintf(b, "%s  </div>\n", inner)
+	close()
+	if cal != nil {
+		fmt.Fprintf(b, "%s<AddToCalendar feed=\"%s\" />\n", indent, cal.Slug)
+	}
+}
+
+// pageLoopsOverCalendar reports whether the page lists records of a model
+// published as a calendar, so each one gets an "Add to calendar" button.
+func pageLoopsOverCalendar(page *ir.Page, app *ir.Application, modelName string) bool {
+	if ir.CalendarFor(app, modelName) == nil {
+		return false
+	}
+	for _, a := range page.Content {
+		if a.Type == "loop" {
+			return true
+		}
 	}
-	fmt.Fprintf(b, "%s  </div>\n", indent)
-	fmt.Fprintf(b, "%s))}\n", indent)
+	return false
 }
 
 // ── Condition JSX ──
@@ -723,6 +1204,10 @@ func writeConditionJSX(b *strings.Builder, text string, indent string, ctx *page
 
 	// Loading state
 	if strings.Contains(lower, "while loading") || strings.Contains(lower, "is loading") {
+		if strings.Contains(lower, "skeleton") {
+			writeSkeletonJSX(b, indent, ctx)
+			return
+		}
 		fmt.Fprintf(b, "%s{loading && (\n", indent)
 		fmt.Fprintf(b, "%s  <div className=\"loading-spinner\">\n", indent)
 		fmt.Fprintf(b, "%s    <div className=\"spinner\" />\n", indent)
@@ -776,13 +1261,16 @@ func writeConditionJSX(b *strings.Builder, text string, indent string, ctx *page
 		if message == "" {
 			message = "Success!"
 		}
-		fmt.Fprintf(b, "%s{success && <div className=\"alert alert-success\">{success || '%s'}</div>}\n", indent, message)
+		fmt.Fprintf(b, "%s<div role=\"status\">{success && <div className=\"alert alert-success\">{success || '%s'}</div>}</div>\n", indent, message)
 		return
 	}
 
 	// Error state
 	if strings.Contains(lower, "error") {
-		fmt.Fprintf(b, "%s{error && <div className=\"alert alert-error\">{error}</div>}\n", indent)
+		fmt.Fprintf(b, "%s<div role=\"alert\">{error && <div className=\"alert alert-error\">{error}</div>}</div>\n", indent)
+		if ctx.loadError != "" {
+			writeLoadErrorJSX(b, indent)
+		}
 		return
 	}
 
@@ -915,6 +1403,12 @@ func writeInteractJSX(b *strings.Builder, text string, indent string, ctx *pageC
 // detectPageModel finds the primary data model from query/loop actions.
 func detectPageModel(page *ir.Page, app *ir.Application) (modelName, varName, itemVar string) {
 	for _, a := range page.Content {
+		if ir.IsTable(a) {
+			if m := ir.TableModel(app, a); m != nil {
+				return m.Name, strings.ToLower(pluralize(m.Name)), strings.ToLower(m.Name)
+			}
+			continue
+		}
 		if a.Type == "query" || a.Type == "loop" {
 			for _, m := range app.Data {
 				lowerText := strings.ToLower(a.Text)
@@ -944,7 +1438,7 @@ func findListEndpoint(app *ir.Application, modelName string) *ir.Endpoint {
 	// Priority 2: "GetTasks"
 	for i := range app.APIs {
 		lower := strings.ToLower(app.APIs[i].Name)
-		if strings.HasPrefix(lower, "get") && strings.Contains(lower, lowerModel) {
+		if strings.HasPrefix(lower, "get") && strings.Contains(lower, lowerModel) && !ir.LoadsRecord(app, app.APIs[i]) {
 			return app.APIs[i]
 		}
 	}
@@ -1272,6 +1766,10 @@ func extractFormFields(lower string, ctx *pageContext) []string {
 						if f.Encrypted {
 							continue
 						}
+						// Dragging the list sets its position
+						if r := ir.ReorderFor(ctx.app, model.Name); r != nil && r.Field == f.Name {
+							continue
+						}
 						fields = append(fields, f.Name)
 					}
 					return fields
@@ -0,0 +1,243 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateTooltip produces src/components/Tooltip.tsx: a wrapper that shows
+// its text above whatever it wraps while it is hovered or focused. Escape
+// hides it without moving the pointer away.
+func generateTooltip() string {
+	return `// Generated by Human compiler — do not edit
+
+import { useId, useState, type ReactNode } from 'react';
+
+interface TooltipProps {
+  text: ReactNode;
+  children: ReactNode;
+}
+
+export default function Tooltip({ text, children }: TooltipProps) {
+  const [open, setOpen] = useState(false);
+  const id = useId();
+  const shown = open && text !== null && text !== undefined && text !== '';
+  return (
+    <span
+      className="tooltip-anchor"
+      style={{ position: 'relative', display: 'inline-block' }}
+      tabIndex={0}
+      aria-describedby={shown ? id : undefined}
+      onMouseEnter={() => setOpen(true)}
+      onMouseLeave={() => setOpen(false)}
+      onFocus={() => setOpen(true)}
+      onBlur={() => setOpen(false)}
+      onKeyDown={(ev) => ev.key === 'Escape' && setOpen(false)}
+    >
+      {children}
+      {shown && (
+        <span
+          id={id}
+          role="tooltip"
+          className="tooltip"
+          style={{
+            position: 'absolute',
+            bottom: '100%',
+            left: '50%',
+            transform: 'translateX(-50%)',
+            marginBottom: 6,
+            padding: '4px 8px',
+            borderRadius: 4,
+            background: '#1f2937',
+            color: '#fff',
+            fontSize: 12,
+            whiteSpace: 'nowrap',
+            pointerEvents: 'none',
+            zIndex: 1000,
+          }}
+        >
+          {text}
+        </span>
+      )}
+    </span>
+  );
+}
+`
+}
+
+// generateDetailPanel produces src/components/DetailPanel.tsx: a panel that
+// slides over the page from one side, closed by its close button, a click
+// outside it, or Escape.
+func generateDetailPanel() string {
+	return `// Generated by Human compiler — do not edit
+
+import { useEffect, type ReactNode } from 'react';
+
+interface DetailPanelProps {
+  title: string;
+  side?: 'left' | 'right';
+  onClose: () => void;
+  children: ReactNode;
+}
+
+export default function DetailPanel({ title, side = 'right', onClose, children }: DetailPanelProps) {
+  useEffect(() => {
+    function onKeyDown(ev: KeyboardEvent) {
+      if (ev.key === 'Escape') onClose();
+    }
+    document.addEventListener('keydown', onKeyDown);
+    return () => document.removeEventListener('keydown', onKeyDown);
+  }, [onClose]);
+
+  return (
+    <div
+      className="detail-panel-overlay"
+      style={{ position: 'fixed', inset: 0, background: 'rgba(0, 0, 0, 0.3)', zIndex: 1000 }}
+      onClick={onClose}
+    >
+      <aside
+        role="dialog"
+        aria-modal="true"
+        aria-label={title}
+        className="detail-panel"
+        style={{
+          position: 'fixed',
+          top: 0,
+          bottom: 0,
+          [side]: 0,
+          width: 'min(420px, 100%)',
+          overflowY: 'auto',
+          padding: 24,
+          background: '#fff',
+          boxShadow: '0 0 24px rgba(0, 0, 0, 0.2)',
+        }}
+        onClick={(ev) => ev.stopPropagation()}
+      >
+        <header style={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center', marginBottom: 16 }}>
+          <h2 style={{ margin: 0 }}>{title}</h2>
+          <button type="button" className="detail-panel-close" aria-label="Close" onClick={onClose}>×</button>
+        </header>
+        {children}
+      </aside>
+    </div>
+  );
+}
+`
+}
+
+// pageTooltips returns the tooltips a page shows over the records it
+// lists: "hovering over a task title shows its description".
+func pageTooltips(page *ir.Page, app *ir.Application, modelName string) []*ir.Tooltip {
+	if modelName == "" {
+		return nil
+	}
+	var tips []*ir.Tooltip
+	for _, a := range page.Content {
+		if t := ir.TooltipFor(app, a, modelName); t != nil && t.Model != nil && t.Model.Name == modelName {
+			tips = append(tips, t)
+		}
+	}
+	return tips
+}
+
+// componentTooltips returns the tooltip a component shows over itself, as
+// a one-element slice, and the prop holding the record it shows a field of.
+func componentTooltips(comp *ir.Component, app *ir.Application) ([]*ir.Tooltip, string) {
+	for _, p := range comp.Props {
+		if !isDataModel(p.Type, app) {
+			continue
+		}
+		for _, a := range comp.Content {
+			if t := ir.TooltipFor(app, a, p.Type); t != nil && t.Model != nil && t.Model.Name == p.Type {
+				return []*ir.Tooltip{t}, p.Name
+			}
+		}
+		return nil, ""
+	}
+	return nil, ""
+}
+
+// pagePanel returns the detail panel a page's "clicking a task opens a
+// detail panel" opens on the records it lists, or nil.
+func pagePanel(page *ir.Page, app *ir.Application, modelName string) *ir.DetailPanel {
+	if modelName == "" {
+		return nil
+	}
+	for _, a := range page.Content {
+		if p := ir.DetailPanelFor(app, a); p != nil && p.Model.Name == modelName {
+			return p
+		}
+	}
+	return nil
+}
+
+// tooltipWired reports whether a tooltip interaction is one the page or
+// component renders.
+func tooltipWired(a *ir.Action, ctx *pageContext) bool {
+	for _, w := range ctx.tooltips {
+		if t := ir.TooltipFor(ctx.app, a, w.Model.Name); t != nil && *t == *w {
+			return true
+		}
+	}
+	return false
+}
+
+// panelWired reports whether a detail panel interaction opens the page's
+// panel.
+func panelWired(a *ir.Action, ctx *pageContext) bool {
+	p := ir.DetailPanelFor(ctx.app, a)
+	return p != nil && ctx.panel != nil && p.Model == ctx.panel.Model
+}
+
+// tooltipOn returns the tooltip shown over a field of each listed record,
+// or over the whole record when field is "".
+func tooltipOn(field string, ctx *pageContext) *ir.Tooltip {
+	for _, t := range ctx.tooltips {
+		if t.Target == nil && field == "" || t.Target != nil && t.Target.Name == field {
+			return t
+		}
+	}
+	return nil
+}
+
+// tooltipText returns the JSX attribute giving a tooltip's text for the
+// record named item.
+func tooltipText(t *ir.Tooltip, item string) string {
+	if t.Field != nil {
+		return fmt.Sprintf("text={%s.%s}", item, t.Field.Name)
+	}
+	return fmt.Sprintf("text=\"%s\"", strings.ReplaceAll(t.Text, `"`, "&quot;"))
+}
+
+// panelClick returns the attributes making a listed record open the detail
+// panel when clicked or when Enter is pressed on it, if the page has one.
+func panelClick(item string, ctx *pageContext) string {
+	if ctx.panel == nil {
+		return ""
+	}
+	return fmt.Sprintf(" role=\"button\" tabIndex={0} onClick={() => setSelected(%s)} onKeyDown={(ev) => ev.key === 'Enter' && ev.target === ev.currentTarget && setSelected(%s)}", item, item)
+}
+
+// writePanelJSX renders the detail panel of the selected record, listing
+// the panel's fields.
+func writePanelJSX(b *strings.Builder, indent string, ctx *pageContext) {
+	p := ctx.panel
+	fmt.Fprintf(b, "%s{selected && (\n", indent)
+	fmt.Fprintf(b, "%s  <DetailPanel title=\"%s\" side=\"%s\" onClose={() => setSelected(null)}>\n", indent, ir.FieldLabel(p.Model.Name), p.Side)
+	fmt.Fprintf(b, "%s    <dl className=\"detail-fields\">\n", indent)
+	for _, f := range p.Fields {
+		fmt.Fprintf(b, "%s      <dt>%s</dt>\n", indent, ir.FieldLabel(f.Name))
+		if f.Type == "boolean" {
+			fmt.Fprintf(b, "%s      <dd>{selected.%s ? 'Yes' : 'No'}</dd>\n", indent, f.Name)
+		} else if t := timeJSX("selected."+f.Name, f, ctx); t != "" {
+			fmt.Fprintf(b, "%s      <dd>%s</dd>\n", indent, t)
+		} else {
+			fmt.Fprintf(b, "%s      <dd>{selected.%s}</dd>\n", indent, f.Name)
+		}
+	}
+	fmt.Fprintf(b, "%s    </dl>\n", indent)
+	fmt.Fprintf(b, "%s  </DetailPanel>\n", indent)
+	fmt.Fprintf(b, "%s)}\n", indent)
+}
@@ -0,0 +1,157 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeListQueryState declares the view of the page's list its URL holds:
+// the search, each filter, the sort, and the page, read from the query
+// string and written back to it, so the view can be shared, bookmarked,
+// and gone back to. Changing the search, a filter, or the sort goes back
+// to the first page. A list the browser narrows itself gets the records
+// matching the view.
+func writeListQueryState(b *strings.Builder, ctx *pageContext) {
+	q := ctx.query
+	b.WriteString("  const [searchParams, setSearchParams] = useSearchParams();\n")
+	if q.Search {
+		b.WriteString("  const search = searchParams.get('q') ?? '';\n")
+	}
+	for _, f := range q.Filters {
+		fmt.Fprintf(b, "  const %s = searchParams.get('%s') ?? '';\n", filterVar(f), f.Name)
+	}
+	if len(q.Sortable) > 0 {
+		var keys []string
+		for _, f := range q.Sortable {
+			keys = append(keys, "'"+f.Name+"'")
+		}
+		initial, desc := "null", "false"
+		if q.SortBy != nil {
+			initial = "'" + q.SortBy.Name + "'"
+			desc = fmt.Sprintf("!searchParams.has('sort') && %t", q.Desc)
+		}
+		fmt.Fprintf(b, "  const sortKey = ([%s] as const).find((key) => key === searchParams.get('sort')) ?? %s;\n", strings.Join(keys, ", "), initial)
+		fmt.Fprintf(b, "  const sortDesc = searchParams.has('order') ? searchParams.get('order') === 'desc' : %s;\n", desc)
+	}
+	if q.Paged {
+		b.WriteString("  const page = Math.max(Number(searchParams.get('page')) || 1, 1);\n")
+		b.WriteString("  const [hasNext, setHasNext] = useState(false);\n")
+	}
+	b.WriteString("  const setParam = (name: string, value: string) => {\n")
+	b.WriteString("    setSearchParams((prev) => {\n")
+	b.WriteString("      const next = new URLSearchParams(prev);\n")
+	b.WriteString("      if (value) next.set(name, value);\n")
+	b.WriteString("      else next.delete(name);\n")
+	b.WriteString("      if (name !== 'page') next.delete('page');\n")
+	b.WriteString("      return next;\n")
+	b.WriteString("    });\n")
+	b.WriteString("  };\n")
+	if len(q.Sortable) > 0 {
+		b.WriteString("  const sortBy = (key: string) => {\n")
+		b.WriteString("    setSearchParams((prev) => {\n")
+		b.WriteString("      const next = new URLSearchParams(prev);\n")
+		b.WriteString("      next.set('sort', key);\n")
+		b.WriteString("      next.set('order', key === sortKey && !sortDesc ? 'desc' : 'asc');\n")
+		b.WriteString("      next.delete('page');\n")
+		b.WriteString("      return next;\n")
+		b.WriteString("    });\n")
+		b.WriteString("  };\n")
+	}
+	if !q.Paged {
+		writeVisibleRows(b, ctx)
+	}
+}
+
+// writeVisibleRows declares the records of a list loaded whole that match
+// the search and filters, in the order picked.
+func writeVisibleRows(b *strings.Builder, ctx *pageContext) {
+	q, item := ctx.query, ctx.itemVar
+	var conds []string
+	for _, f := range q.Filters {
+		conds = append(conds, fmt.Sprintf("(!%s || String(%s.%s) === %s)", filterVar(f), item, f.Name, filterVar(f)))
+	}
+	if q.Search {
+		conds = append(conds, fmt.Sprintf("(!search || Object.values(%s).some((value) => String(value ?? '').toLowerCase().includes(search.toLowerCase())))", item))
+	}
+	rows := rowsVar(ctx)
+	if len(conds) > 0 {
+		fmt.Fprintf(b, "  const %s = %s.filter((%s) =>\n", rows, ctx.varName, item)
+		fmt.Fprintf(b, "    %s,\n", strings.Join(conds, " &&\n    "))
+		b.WriteString("  );\n")
+	} else {
+		fmt.Fprintf(b, "  const %s = [...%s];\n", rows, ctx.varName)
+	}
+	if len(q.Sortable) > 0 {
+		b.WriteString("  if (sortKey) {\n")
+		fmt.Fprintf(b, "    %s.sort((a, b) => {\n", rows)
+		b.WriteString("      const order = String(a[sortKey] ?? '').localeCompare(String(b[sortKey] ?? ''), undefined, { numeric: true });\n")
+		b.WriteString("      return sortDesc ? -order : order;\n")
+		b.WriteString("    });\n")
+		b.WriteString("  }\n")
+	}
+}
+
+// rowsVar returns the records the page's list renders: the ones matching
+// its view, or all it loaded when the API pages them.
+func rowsVar(ctx *pageContext) string {
+	if ctx.query == nil || ctx.query.Paged {
+		return ctx.varName
+	}
+	return "visible" + capitalize(ctx.varName)
+}
+
+// listQueryArgs returns the query a paginated list is fetched with: the
+// page's query string, with the sort it shows before the user picks one.
+func listQueryArgs(ctx *pageContext) string {
+	if len(ctx.query.Sortable) == 0 {
+		return "Object.fromEntries(searchParams)"
+	}
+	return "{ ...Object.fromEntries(searchParams), ...(sortKey ? { sort: sortKey, order: sortDesc ? 'desc' : 'asc' } : {}) }"
+}
+
+// filterVar returns the name of the value a list is filtered by f to.
+func filterVar(f *ir.DataField) string {
+	return toCamelCase(f.Name) + "Filter"
+}
+
+// filterOptions returns the values and labels of a filter's options.
+func filterOptions(f *ir.DataField) [][2]string {
+	if f.Type == "boolean" {
+		return [][2]string{{"true", "Yes"}, {"false", "No"}}
+	}
+	var opts [][2]string
+	for _, v := range f.EnumValues {
+		opts = append(opts, [2]string{v, capitalize(v)})
+	}
+	return opts
+}
+
+// writeSearchJSX renders the search input of a list, kept in the URL.
+func writeSearchJSX(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" aria-label=\"Search\" className=\"search-input\" value={search} onChange={(ev) => setParam('q', ev.target.value)} />\n", indent)
+}
+
+// writeFilterJSX renders the dropdown filtering a list by f, kept in the
+// URL.
+func writeFilterJSX(b *strings.Builder, indent string, f *ir.DataField) {
+	label := ir.FieldLabel(f.Name)
+	fmt.Fprintf(b, "%s<select className=\"filter-select\" aria-label=\"Filter by %s\" value={%s} onChange={(ev) => setParam('%s', ev.target.value)}>\n",
+		indent, strings.ToLower(label), filterVar(f), f.Name)
+	fmt.Fprintf(b, "%s  <option value=\"\">All</option>\n", indent)
+	for _, o := range filterOptions(f) {
+		fmt.Fprintf(b, "%s  <option value=\"%s\">%s</option>\n", indent, o[0], o[1])
+	}
+	fmt.Fprintf(b, "%s</select>\n", indent)
+}
+
+// writePaginationJSX renders the controls moving between the pages of a
+// paginated list, kept in the URL.
+func writePaginationJSX(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<nav className=\"pagination\" aria-label=\"Pagination\">\n", indent)
+	fmt.Fprintf(b, "%s  <button className=\"page-btn\" disabled={page <= 1} onClick={() => setParam('page', page > 2 ? String(page - 1) : '')}>&laquo; Prev</button>\n", indent)
+	fmt.Fprintf(b, "%s  <span className=\"page-number\" aria-current=\"page\">{page}</span>\n", indent)
+	fmt.Fprintf(b, "%s  <button className=\"page-btn\" disabled={!hasNext} onClick={() => setParam('page', String(page + 1))}>Next &raquo;</button>\n", indent)
+	fmt.Fprintf(b, "%s</nav>\n", indent)
+}
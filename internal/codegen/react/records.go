@@ -0,0 +1,78 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// recordURL returns the JS expression for the URL of a record on page:
+// `/posts/${post.id}` for a detail route, else the page with an id query.
+func recordURL(page, item string, ctx *pageContext) string {
+	prefix, field := ir.RecordLink(ctx.app, page)
+	return fmt.Sprintf("`%s${%s.%s}`", prefix, item, field)
+}
+
+// itemClick returns the attributes making a listed record open the detail
+// panel, or navigate to the page its list links to, when clicked or when
+// Enter is pressed on it.
+func itemClick(item string, ctx *pageContext) string {
+	if ctx.panel != nil || ctx.itemPage == "" {
+		return panelClick(item, ctx)
+	}
+	url := recordURL(ctx.itemPage, item, ctx)
+	return fmt.Sprintf(" role=\"link\" tabIndex={0} onClick={() => navigate(%s)} onKeyDown={(ev) => ev.key === 'Enter' && ev.target === ev.currentTarget && navigate(%s)}", url, url)
+}
+
+// writeRecordState declares the record a detail route's page shows and
+// loads it whenever the URL's value changes or the user retries: undefined
+// while loading, null when there is no such record.
+func writeRecordState(b *strings.Builder, ctx *pageContext) {
+	r := ctx.record
+	v := strings.ToLower(r.Model[:1]) + r.Model[1:]
+	fmt.Fprintf(b, "  const { %s } = useParams();\n", r.Param)
+	fmt.Fprintf(b, "  const [%s, set%s] = useState<%s | null | undefined>(undefined);\n", v, r.Model, r.Model)
+	b.WriteString("\n  useEffect(() => {\n")
+	fmt.Fprintf(b, "    if (!%s) return;\n", r.Param)
+	fmt.Fprintf(b, "    set%s(undefined);\n", r.Model)
+	fmt.Fprintf(b, "    %s({ %s: %s })\n", toCamelCase(r.Endpoint), r.Arg, r.Param)
+	fmt.Fprintf(b, "      .then(res => set%s(res.data ?? null))\n", r.Model)
+	fmt.Fprintf(b, "      .catch(err => (err instanceof ApiError && err.status === 404 ? set%s(null) : %s));\n", r.Model, loadFailed(ctx))
+	fmt.Fprintf(b, "  }, [%s, attempt]);\n", r.Param)
+}
+
+// writeRecordJSX renders the record a detail route's page shows: its title
+// as the heading and its other fields, or a not-found message.
+func writeRecordJSX(b *strings.Builder, indent string, ctx *pageContext) {
+	r := ctx.record
+	v := strings.ToLower(r.Model[:1]) + r.Model[1:]
+	m := findModel(ctx.app, r.Model)
+	fmt.Fprintf(b, "%s{%s === undefined && !loadError && <div className=\"loading-spinner\"><div className=\"spinner\" /></div>}\n", indent, v)
+	fmt.Fprintf(b, "%s{%s === null && <div className=\"empty-state\">%s not found</div>}\n", indent, v, ir.FieldLabel(r.Model))
+	fmt.Fprintf(b, "%s{%s && (\n", indent, v)
+	fmt.Fprintf(b, "%s  <article className=\"%s-record\">\n", indent, toKebabCase(r.Model))
+	title := ir.RecordTitle(m)
+	if title != nil {
+		fmt.Fprintf(b, "%s    <h1>{%s.%s}</h1>\n", indent, v, title.Name)
+	} else {
+		fmt.Fprintf(b, "%s    <h1>%s</h1>\n", indent, ir.FieldLabel(r.Model))
+	}
+	fmt.Fprintf(b, "%s    <dl className=\"detail-fields\">\n", indent)
+	for _, f := range ir.RecordFields(m) {
+		if f == title {
+			continue
+		}
+		fmt.Fprintf(b, "%s      <dt>%s</dt>\n", indent, ir.FieldLabel(f.Name))
+		if f.Type == "boolean" {
+			fmt.Fprintf(b, "%s      <dd>{%s.%s ? 'Yes' : 'No'}</dd>\n", indent, v, f.Name)
+		} else if t := timeJSX(v+"."+f.Name, f, ctx); t != "" {
+			fmt.Fprintf(b, "%s      <dd>%s</dd>\n", indent, t)
+		} else {
+			fmt.Fprintf(b, "%s      <dd>{%s.%s}</dd>\n", indent, v, f.Name)
+		}
+	}
+	fmt.Fprintf(b, "%s    </dl>\n", indent)
+	fmt.Fprintf(b, "%s  </article>\n", indent)
+	fmt.Fprintf(b, "%s)}\n", indent)
+}
@@ -0,0 +1,42 @@
+package react
+
+import "strings"
+
+// writeRememberState declares what a page remembering its list restores
+// when the user comes back: the query string they left it with, unless its
+// URL names a view of its own, and how far down they had scrolled. Both
+// are read as the page opens, before it saves anything over them.
+func writeRememberState(b *strings.Builder, ctx *pageContext) {
+	b.WriteString("  // Coming back to the page restores the list as the user left it\n")
+	if ctx.query != nil {
+		b.WriteString("  const [restoringView, setRestoringView] = useState(() => (!searchParams.toString() && sessionStorage.getItem(`view:${window.location.pathname}`)) || '');\n")
+	}
+	b.WriteString("  const savedScroll = useRef<number | null>(Number(sessionStorage.getItem(`scroll:${window.location.pathname}`)) || null);\n")
+}
+
+// writeRememberEffects emits the effects keeping the view of the page's
+// list in sessionStorage as it changes, and scrolling back down once the
+// list has loaded. A restored query string replaces the page's entry in
+// the history rather than adding one.
+func writeRememberEffects(b *strings.Builder, ctx *pageContext) {
+	if ctx.query != nil {
+		b.WriteString("\n  useEffect(() => {\n")
+		b.WriteString("    if (restoringView) {\n")
+		b.WriteString("      setSearchParams(new URLSearchParams(restoringView), { replace: true });\n")
+		b.WriteString("      setRestoringView('');\n")
+		b.WriteString("    } else {\n")
+		b.WriteString("      sessionStorage.setItem(`view:${window.location.pathname}`, searchParams.toString());\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }, [searchParams]);\n")
+	}
+	b.WriteString("\n  useEffect(() => {\n")
+	b.WriteString("    const saveScroll = () => sessionStorage.setItem(`scroll:${window.location.pathname}`, String(window.scrollY));\n")
+	b.WriteString("    window.addEventListener('scroll', saveScroll, { passive: true });\n")
+	b.WriteString("    return () => window.removeEventListener('scroll', saveScroll);\n")
+	b.WriteString("  }, []);\n")
+	b.WriteString("\n  useEffect(() => {\n")
+	b.WriteString("    if (loading || savedScroll.current === null) return;\n")
+	b.WriteString("    window.scrollTo(0, savedScroll.current);\n")
+	b.WriteString("    savedScroll.current = null;\n")
+	b.WriteString("  }, [loading]);\n")
+}
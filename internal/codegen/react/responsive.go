@@ -0,0 +1,49 @@
+package react
+
+import (
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/themes"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generatePageStyles produces the stylesheet of a page declaring a
+// responsive layout, imported by the page beside it.
+func generatePageStyles(page *ir.Page, app *ir.Application) string {
+	return "/* Generated by Human compiler — do not edit */\n\n" + themes.ResponsiveCSS(app.Theme, pageLayout(page, app))
+}
+
+// pageLayout returns what a responsive page's styles lay out.
+func pageLayout(page *ir.Page, app *ir.Application) themes.ResponsiveLayout {
+	modelName, _, _ := detectPageModel(page, app)
+	ctx := &pageContext{app: app, modelName: modelName}
+	l := themes.ResponsiveLayout{
+		Page:       toKebabCase(page.Name) + "-page",
+		Table:      ir.TableFor(app, page, modelName) != nil,
+		Nav:        pageHasNavbar(page),
+		Responsive: ir.ResponsiveFor(page),
+	}
+	if modelName != "" && !l.Table {
+		l.Item = toKebabCase(modelName) + "-item"
+		for _, f := range collectLoopFields(page, ctx) {
+			l.Shapes = append(l.Shapes, skeletonShape(f))
+		}
+	}
+	return l
+}
+
+// pageHasNavbar reports whether a page shows a navigation bar.
+func pageHasNavbar(page *ir.Page) bool {
+	for _, a := range page.Content {
+		if a.Type == "display" && isNavbar(strings.ToLower(a.Text)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNavbar reports whether display text shows a navigation bar.
+func isNavbar(lower string) bool {
+	return strings.Contains(lower, "navigation") || strings.Contains(lower, "navbar") ||
+		strings.Contains(lower, "nav bar") || strings.Contains(lower, "menu bar")
+}
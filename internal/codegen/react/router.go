@@ -8,7 +8,8 @@ import (
 	"github.com/barun-bash/human/internal/ir"
 )
 
-// generateApp produces App.tsx with React Router setup.
+// generateApp produces App.tsx with React Router setup. Each page is
+// loaded lazily, in a chunk of its own, the first time its route renders.
 // If a design system with a provider component is configured, the routes
 // are wrapped in the appropriate ThemeProvider.
 // If app.Auth is configured, routes are wrapped in AuthProvider and
@@ -19,7 +20,9 @@ func generateApp(app *ir.Application) string {
 	hasAuth := app.Auth != nil
 
 	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { lazy, Suspense } from 'react';\n")
 	b.WriteString("import { BrowserRouter, Routes, Route } from 'react-router-dom';\n")
+	b.WriteString("import ErrorBoundary from './components/ErrorBoundary';\n")
 
 	// Auth imports
 	if hasAuth {
@@ -53,10 +56,35 @@ func generateApp(app *ir.Application) string {
 		b.WriteString("import './styles/global.css';\n")
 	}
 
-	// Import each page
+	if len(app.Notifications) > 0 {
+		b.WriteString("import NotificationBell from './components/NotificationBell';\n")
+	}
+	if app.Sitemap != nil {
+		b.WriteString("import CanonicalLink from './components/CanonicalLink';\n")
+	}
+
+	if len(app.Experiments) > 0 {
+		b.WriteString("import ExperimentRoute from './experiments/ExperimentRoute';\n")
+	}
+
+	// Each page is split into its own chunk
+	b.WriteString("\n")
 	for _, page := range app.Pages {
 		name := page.Name + "Page"
-		fmt.Fprintf(&b, "import %s from './pages/%s';\n", name, name)
+		fmt.Fprintf(&b, "const %s = lazy(() => import('./pages/%s'));\n", name, name)
+	}
+
+	// Experiments: the control page's route renders the assigned variant
+	if len(app.Experiments) > 0 {
+		b.WriteString("\n")
+		for _, exp := range app.Experiments {
+			var variants []string
+			for _, v := range exp.Variants {
+				page := variantPage(app, v.Page)
+				variants = append(variants, fmt.Sprintf("%s: %sPage", v.Page, page))
+			}
+			fmt.Fprintf(&b, "const %s = { %s };\n", variantsIdent(exp), strings.Join(variants, ", "))
+		}
 	}
 
 	b.WriteString("\n")
@@ -87,12 +115,33 @@ func generateApp(app *ir.Application) string {
 	}
 
 	fmt.Fprintf(&b, "%s<BrowserRouter>\n", indent)
+	if app.Sitemap != nil {
+		fmt.Fprintf(&b, "%s  <CanonicalLink />\n", indent)
+	}
+	if len(app.Notifications) > 0 {
+		fmt.Fprintf(&b, "%s  <NotificationBell />\n", indent)
+	}
+	// Errors thrown while rendering a page show instead of a blank screen
+	fmt.Fprintf(&b, "%s  <ErrorBoundary>\n", indent)
+	indent += "  "
+	// A page's chunk shows the spinner while it loads
+	fmt.Fprintf(&b, "%s  <Suspense fallback={<div className=\"loading-spinner\"><div className=\"spinner\" /></div>}>\n", indent)
+	indent += "  "
 	fmt.Fprintf(&b, "%s  <Routes>\n", indent)
 
 	for _, page := range app.Pages {
 		name := page.Name + "Page"
 		path := routePath(page.Name)
-		if hasAuth && !isPublicPage(page.Name) {
+		if r := ir.DetailRouteFor(app, page.Name); r != nil {
+			path = r.Path()
+		}
+		if exp := controlExperiment(app, page.Name); exp != nil {
+			element := fmt.Sprintf("<ExperimentRoute name=\"%s\" control=\"%s\" variants={%s} />", exp.Name, exp.Variants[0].Page, variantsIdent(exp))
+			if hasAuth && !isPublicPage(page.Name) && !isAcceptInvitePage(app, page) && !ir.IsPricingPage(app, page) {
+				element = "<ProtectedRoute>" + element + "</ProtectedRoute>"
+			}
+			fmt.Fprintf(&b, "%s    <Route path=\"%s\" element={%s} />\n", indent, path, element)
+		} else if hasAuth && !isPublicPage(page.Name) && !isAcceptInvitePage(app, page) && !ir.IsPricingPage(app, page) {
 			fmt.Fprintf(&b, "%s    <Route path=\"%s\" element={<ProtectedRoute><%s /></ProtectedRoute>} />\n", indent, path, name)
 		} else {
 			fmt.Fprintf(&b, "%s    <Route path=\"%s\" element={<%s />} />\n", indent, path, name)
@@ -101,6 +150,10 @@ func generateApp(app *ir.Application) string {
 	fmt.Fprintf(&b, "%s    <Route path=\"*\" element={<div style={{ textAlign: 'center', padding: '4rem' }}><h1>404</h1><p>Page not found</p></div>} />\n", indent)
 
 	fmt.Fprintf(&b, "%s  </Routes>\n", indent)
+	indent = indent[:len(indent)-2]
+	fmt.Fprintf(&b, "%s  </Suspense>\n", indent)
+	indent = indent[:len(indent)-2]
+	fmt.Fprintf(&b, "%s  </ErrorBoundary>\n", indent)
 	fmt.Fprintf(&b, "%s</BrowserRouter>\n", indent)
 
 	// Close AuthProvider
@@ -0,0 +1,45 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateCanonicalLink produces src/components/CanonicalLink.tsx, which
+// keeps the page's <link rel="canonical"> on the site's origin as the
+// route changes. VITE_SITE_URL overrides the declared production URL; a
+// record's ?id stays part of the URL, as in the sitemap.
+func generateCanonicalLink(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { useEffect } from 'react';\n")
+	b.WriteString("import { useLocation } from 'react-router-dom';\n\n")
+	if app.Sitemap.URL != "" {
+		fmt.Fprintf(&b, "const SITE_URL = (import.meta.env.VITE_SITE_URL || '%s').replace(/\\/$/, '');\n", app.Sitemap.URL)
+	} else {
+		b.WriteString("const SITE_URL = (import.meta.env.VITE_SITE_URL || window.location.origin).replace(/\\/$/, '');\n")
+	}
+
+	b.WriteString(`
+export default function CanonicalLink() {
+  const { pathname, search } = useLocation();
+
+  useEffect(() => {
+    const id = new URLSearchParams(search).get('id');
+    let link = document.querySelector<HTMLLinkElement>('link[rel="canonical"]');
+    if (!link) {
+      link = document.createElement('link');
+      link.rel = 'canonical';
+      document.head.appendChild(link);
+    }
+    link.href = SITE_URL + pathname + (id ? ` + "`?id=${encodeURIComponent(id)}`" + ` : '');
+  }, [pathname, search]);
+
+  return null;
+}
+`)
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package react
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/themes"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateSkeleton produces src/components/Skeleton.tsx: a placeholder bar
+// shaped like the heading, line of text, date, or badge it stands in for.
+// Its stylesheet is beside it, unless the app's CSS module holds it.
+func generateSkeleton(app *ir.Application) string {
+	stylesheet := "import './Skeleton.css';\n\n"
+	if ir.Styling(app) == ir.StylingCSSModules {
+		stylesheet = ""
+	}
+	return `// Generated by Human compiler — do not edit
+
+` + stylesheet + `interface SkeletonProps {
+  shape?: 'heading' | 'text' | 'date' | 'badge';
+}
+
+export default function Skeleton({ shape = 'text' }: SkeletonProps) {
+  return <span className={` + "`skeleton skeleton-${shape}`" + `} aria-hidden="true" />;
+}
+`
+}
+
+// generateSkeletonCSS produces src/components/Skeleton.css, themed from the
+// app's theme.
+func generateSkeletonCSS(app *ir.Application) string {
+	return "/* Generated by Human compiler — do not edit */\n\n" + themes.SkeletonCSS(app.Theme)
+}
+
+// skeletonShape returns the Skeleton shape standing in for a listed field,
+// matching the element the list renders it as.
+func skeletonShape(field string) string {
+	switch {
+	case field == "status" || field == "role" || field == "priority":
+		return "badge"
+	case field == "title" || field == "name":
+		return "heading"
+	case strings.Contains(field, "date") || field == "due" || field == "created":
+		return "date"
+	}
+	return "text"
+}
+
+// writeSkeletonJSX renders the page's skeleton screen while it loads: rows
+// with a placeholder per column when it shows a table, else items shaped
+// like the ones it lists.
+func writeSkeletonJSX(b *strings.Builder, indent string, ctx *pageContext) {
+	fmt.Fprintf(b, "%s{loading && (\n", indent)
+	if ctx.table != nil {
+		fmt.Fprintf(b, "%s  <table className=\"data-table skeleton-screen\" aria-busy=\"true\">\n", indent)
+		fmt.Fprintf(b, "%s    <tbody>\n", indent)
+		fmt.Fprintf(b, "%s      {[0, 1, 2].map((i) => (\n", indent)
+		fmt.Fprintf(b, "%s        <tr key={i}>\n", indent)
+		for _, f := range ctx.table.Columns {
+			fmt.Fprintf(b, "%s          <td><Skeleton shape=\"%s\" /></td>\n", indent, skeletonShape(f.Name))
+		}
+		fmt.Fprintf(b, "%s        </tr>\n", indent)
+		fmt.Fprintf(b, "%s      ))}\n", indent)
+		fmt.Fprintf(b, "%s    </tbody>\n", indent)
+		fmt.Fprintf(b, "%s  </table>\n", indent)
+		fmt.Fprintf(b, "%s)}\n", indent)
+		return
+	}
+
+	item := "skeleton-item"
+	if ctx.modelName != "" {
+		item = toKebabCase(ctx.modelName) + "-item"
+	}
+	shapes := []string{"heading", "text"}
+	if len(ctx.itemFields) > 0 {
+		shapes = nil
+		for _, f := range ctx.itemFields {
+			shapes = append(shapes, skeletonShape(f))
+		}
+	}
+	fmt.Fprintf(b, "%s  <div className=\"skeleton-screen\" aria-busy=\"true\">\n", indent)
+	fmt.Fprintf(b, "%s    {[0, 1, 2].map((i) => (\n", indent)
+	fmt.Fprintf(b, "%s      <div key={i} className=\"%s\">\n", indent, item)
+	for _, s := range shapes {
+		fmt.Fprintf(b, "%s        <Skeleton shape=\"%s\" />\n", indent, s)
+	}
+	fmt.Fprintf(b, "%s      </div>\n", indent)
+	fmt.Fprintf(b, "%s    ))}\n", indent)
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s)}\n", indent)
+}
+
+// pageHasSkeleton reports whether a page shows a skeleton screen while it
+// loads.
+func pageHasSkeleton(page *ir.Page) bool {
+	for _, a := range page.Content {
+		if ir.IsSkeleton(a) {
+			return true
+		}
+	}
+	return false
+}
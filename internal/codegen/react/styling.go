@@ -0,0 +1,96 @@
+package react
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// scopeClassNames rewrites the class names of the app's components, written
+// plainly, to go through cx so that the ones its CSS module styles are
+// scoped by it. Each component using one imports cx.
+func scopeClassNames(files map[string]string, outputDir string) {
+	cxPath := filepath.Join(outputDir, "src", "styles", "cx")
+	for path, content := range files {
+		if !strings.HasSuffix(path, ".tsx") || !strings.Contains(content, "className=") {
+			continue
+		}
+		rel, err := filepath.Rel(filepath.Dir(path), cxPath)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if !strings.HasPrefix(rel, ".") {
+			rel = "./" + rel
+		}
+		files[path] = addImport(wrapClassNames(content), fmt.Sprintf("import { cx } from '%s';", rel))
+	}
+}
+
+// wrapClassNames wraps each className in a call to cx: a string becomes
+// cx('...') and an expression cx(...).
+func wrapClassNames(src string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(src, "className=")
+		if i < 0 {
+			b.WriteString(src)
+			return b.String()
+		}
+		i += len("className=")
+		b.WriteString(src[:i])
+		src = src[i:]
+		switch {
+		case strings.HasPrefix(src, "\""):
+			end := strings.Index(src[1:], "\"") + 1
+			fmt.Fprintf(&b, "{cx('%s')}", src[1:end])
+			src = src[end+1:]
+		case strings.HasPrefix(src, "{") && !strings.HasPrefix(src, "{cx("):
+			end := closingBrace(src)
+			fmt.Fprintf(&b, "{cx(%s)}", src[1:end])
+			src = src[end+1:]
+		}
+	}
+}
+
+// closingBrace returns the index of the brace closing the one src opens
+// with, or the last index when it's never closed.
+func closingBrace(src string) int {
+	depth := 0
+	for i, c := range src {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(src) - 1
+}
+
+// addImport adds an import after the ones a module starts with, or below
+// its header comment when it has none.
+func addImport(src, imp string) string {
+	lines := strings.Split(src, "\n")
+	at := 0
+	for i, line := range lines {
+		if strings.HasPrefix(line, "export ") || strings.HasPrefix(line, "function ") ||
+			strings.HasPrefix(line, "const ") || strings.HasPrefix(line, "interface ") {
+			break
+		}
+		if (strings.HasPrefix(line, "import ") || strings.HasPrefix(line, "} from ")) && strings.HasSuffix(line, ";") {
+			at = i + 1
+		}
+	}
+	if at == 0 {
+		// No imports yet: the first goes below the header comment
+		for at < len(lines) && strings.HasPrefix(lines[at], "//") {
+			at++
+		}
+		imp = "\n" + imp
+	}
+	return strings.Join(lines[:at], "\n") + "\n" + imp + "\n" + strings.Join(lines[at:], "\n")
+}
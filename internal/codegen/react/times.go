@@ -0,0 +1,119 @@
+package react
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeTimeClient writes the helpers showing stored times in the user's
+// locale and time zone, and the one sending times entered in the user's
+// time zone as UTC.
+func writeTimeClient(b *strings.Builder) {
+	b.WriteString(`
+// Times are stored in UTC and shown in the user's locale and time zone. A
+// date names a day, not a moment, so it is shown as the day it names.
+const dateFormat = new Intl.DateTimeFormat(undefined, { dateStyle: 'medium', timeZone: 'UTC' });
+const dateTimeFormat = new Intl.DateTimeFormat(undefined, { dateStyle: 'medium', timeStyle: 'short' });
+
+export function formatDate(value: string | null | undefined): string {
+  return value ? dateFormat.format(new Date(value)) : '';
+}
+
+export function formatDateTime(value: string | null | undefined): string {
+  return value ? dateTimeFormat.format(new Date(value)) : '';
+}
+
+// localDateTime returns a stored time as a datetime-local input's value, in
+// the user's time zone.
+export function localDateTime(value: string | null | undefined): string {
+  if (!value) return '';
+  const date = new Date(value);
+  return new Date(date.getTime() - date.getTimezoneOffset() * 60_000).toISOString().slice(0, 16);
+}
+
+// A datetime-local input's value: a time in the user's time zone, without
+// an offset.
+const LOCAL_TIME = /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}(:\d{2}(\.\d+)?)?$/;
+
+// utcTimes sends each time entered in the user's time zone as ISO 8601 in
+// UTC.
+function utcTimes(body: Record<string, unknown>): Record<string, unknown> {
+  return Object.fromEntries(
+    Object.entries(body).map(([key, value]) => [key, typeof value === 'string' && LOCAL_TIME.test(value) ? new Date(value).toISOString() : value]),
+  );
+}
+`)
+}
+
+// timeJSX returns the JSX showing a date or time field, formatted for the
+// user, or "" when f isn't a date or time or the app doesn't store times in
+// UTC.
+func timeJSX(expr string, f *ir.DataField, ctx *pageContext) string {
+	if f == nil || !ir.StoresUTC(ctx.app) {
+		return ""
+	}
+	var helper string
+	switch f.Type {
+	case "date":
+		helper = "formatDate"
+	case "datetime":
+		helper = "formatDateTime"
+	default:
+		return ""
+	}
+	ctx.useTimeHelper(helper)
+	return fmt.Sprintf("<time dateTime={%s ?? undefined}>{%s(%s)}</time>", expr, helper, expr)
+}
+
+// localTimeValue returns the value an edit form's datetime-local input for
+// ref starts with when the app stores times in UTC, or "".
+func localTimeValue(ref string, ctx *pageContext) string {
+	if !ir.StoresUTC(ctx.app) {
+		return ""
+	}
+	ctx.useTimeHelper("localDateTime")
+	return "localDateTime(" + ref + ")"
+}
+
+// useTimeHelper notes that the page uses a time helper from the API client.
+func (ctx *pageContext) useTimeHelper(name string) {
+	if ctx.timeHelpers == nil {
+		ctx.timeHelpers = map[string]bool{}
+	}
+	ctx.timeHelpers[name] = true
+}
+
+// withTimeImports adds the time helpers a page used to its imports from the
+// API client.
+func withTimeImports(out string, ctx *pageContext) string {
+	if len(ctx.timeHelpers) == 0 {
+		return out
+	}
+	var names []string
+	for name := range ctx.timeHelpers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	const client = " } from '../api/client';\n"
+	if strings.Contains(out, client) {
+		return strings.Replace(out, client, ", "+strings.Join(names, ", ")+client, 1)
+	}
+	return strings.Replace(out, "\nexport default function", fmt.Sprintf("import { %s } from '../api/client';\n\nexport default function", strings.Join(names, ", ")), 1)
+}
+
+// modelField returns the field of the page's model named name, or nil.
+func modelField(name string, ctx *pageContext) *ir.DataField {
+	m := findModel(ctx.app, ctx.modelName)
+	if m == nil {
+		return nil
+	}
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
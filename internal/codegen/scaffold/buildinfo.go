@@ -0,0 +1,20 @@
+package scaffold
+
+import (
+	"encoding/json"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateBuildInfo produces build-info.json: the app, the compiler version,
+// the IR hash, and when the code was generated, for tracing a deployed
+// instance back to the .human source that produced it. The backends send
+// the same in their X-Human-Build header.
+func generateBuildInfo(app *ir.Application) string {
+	info := struct {
+		App string `json:"app"`
+		*ir.BuildInfo
+	}{app.Name, app.Build}
+	out, _ := json.MarshalIndent(info, "", "  ")
+	return string(out) + "\n"
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -26,9 +27,19 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 	}
 
 	files := map[string]string{
-		filepath.Join(outputDir, "package.json"):   generateRootPackageJSON(app),
-		filepath.Join(outputDir, "README.md"):      generateReadme(app),
-		filepath.Join(outputDir, ".env.example"):   generateEnvExample(app),
+		filepath.Join(outputDir, "package.json"): generateRootPackageJSON(app),
+		filepath.Join(outputDir, "README.md"):    generateReadme(app),
+		filepath.Join(outputDir, ".env.example"): generateEnvExample(app),
+	}
+
+	// Build provenance, when built by the pipeline
+	if app.Build != nil {
+		files[filepath.Join(outputDir, "build-info.json")] = generateBuildInfo(app)
+	}
+
+	// Turborepo pipelines when frontend and backend share one JS workspace
+	if usesTurbo(app) {
+		files[filepath.Join(outputDir, "turbo.json")] = generateTurboJSON()
 	}
 
 	// React scaffold files (Vue/Angular/Svelte generators write their own)
@@ -57,10 +68,8 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 
 	// Python and Go backends don't need scaffold package.json/tsconfig
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
+	if err := codegen.WriteFiles(files, codegen.WriteOptionsFor(app)); err != nil {
+		return err
 	}
 
 	// start.sh needs executable permissions
@@ -72,17 +81,6 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 	return nil
 }
 
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
-}
-
 func writeExecutable(path, content string) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -121,17 +121,19 @@ func TestRootPackageJSON(t *testing.T) {
 		{"name", `"name": "taskflow"`},
 		{"workspaces node", `"node"`},
 		{"workspaces react", `"react"`},
-		{"dev script", `"dev": "concurrently`},
-		{"start script", `"start": "concurrently`},
-		{"build script", `"build":`},
-		{"test script", `"test":`},
+		{"dev script", `"dev": "turbo run dev"`},
+		{"start script", `"start": "turbo run start"`},
+		{"build script", `"build": "turbo run build"`},
+		{"test script", `"test": "turbo run test"`},
+		{"lint script", `"lint": "turbo run lint"`},
+		{"packageManager", `"packageManager": "npm@`},
 		{"db:migrate", `"db:migrate":`},
 		{"db:seed", `"db:seed":`},
 		{"db:studio", `"db:studio":`},
 		{"docker:dev", `"docker:dev": "docker compose up --build"`},
 		{"docker:start", `"docker:start": "docker compose up -d"`},
 		{"docker:stop", `"docker:stop": "docker compose down"`},
-		{"concurrently dep", `"concurrently": "^9.0.0"`},
+		{"turbo dep", `"turbo": "^2.3.0"`},
 	}
 
 	for _, c := range checks {
@@ -159,9 +161,9 @@ func TestRootPackageJSONVuePython(t *testing.T) {
 	if strings.Contains(output, "prisma") {
 		t.Error("vue+python root package.json: should not have Prisma scripts")
 	}
-	// Should not have concurrently (only 1 workspace)
-	if strings.Contains(output, "concurrently") {
-		t.Error("vue+python root package.json: should not have concurrently with single workspace")
+	// Should not use turbo (only 1 workspace)
+	if strings.Contains(output, "turbo") {
+		t.Error("vue+python root package.json: should not use turbo with single workspace")
 	}
 }
 
@@ -176,8 +178,8 @@ func TestRootPackageJSONGoBackend(t *testing.T) {
 	if strings.Contains(output, "prisma") {
 		t.Error("go backend root package.json: should not have Prisma scripts")
 	}
-	if strings.Contains(output, "concurrently") {
-		t.Error("go backend root package.json: should not have concurrently")
+	if strings.Contains(output, "turbo") {
+		t.Error("go backend root package.json: should not use turbo")
 	}
 }
 
@@ -196,17 +198,54 @@ func TestRootPackageJSONAngularNode(t *testing.T) {
 	if strings.Contains(output, `"react"`) {
 		t.Error("angular+node root package.json: should not have react workspace")
 	}
-	// Should have concurrently (2 workspaces)
-	if !strings.Contains(output, "concurrently") {
-		t.Error("angular+node root package.json: missing concurrently")
+	// Should use turbo (2 workspaces)
+	if !strings.Contains(output, `"turbo run build"`) {
+		t.Error("angular+node root package.json: missing turbo build script")
 	}
 	// Should have Prisma scripts (Node backend)
 	if !strings.Contains(output, "prisma") {
 		t.Error("angular+node root package.json: missing Prisma scripts")
 	}
-	// Scripts should reference angular workspace
-	if !strings.Contains(output, "workspace=angular") {
-		t.Error("angular+node root package.json: scripts should reference angular workspace")
+}
+
+// ── turbo.json ──
+
+func TestTurboJSON(t *testing.T) {
+	output := generateTurboJSON()
+
+	checks := []string{
+		`"$schema": "https://turbo.build/schema.json"`,
+		`"build": {`,
+		`"dependsOn": ["^build"]`,
+		`"outputs": ["dist/**", "build/**", ".svelte-kit/**"]`,
+		`"test": {`,
+		`"lint": {`,
+		`"persistent": true`,
+		`"cache": false`,
+	}
+	for _, c := range checks {
+		if !strings.Contains(output, c) {
+			t.Errorf("turbo.json: missing %q", c)
+		}
+	}
+}
+
+func TestUsesTurbo(t *testing.T) {
+	tests := []struct {
+		name string
+		app  *ir.Application
+		want bool
+	}{
+		{"react+node", testApp(), true},
+		{"angular+node", testAppAngularNode(), true},
+		{"vue+python", testAppVuePython(), false},
+		{"go only", testAppGoBackend(), false},
+		{"no config", &ir.Application{Name: "Bare"}, false},
+	}
+	for _, tt := range tests {
+		if got := usesTurbo(tt.app); got != tt.want {
+			t.Errorf("usesTurbo(%s) = %v, want %v", tt.name, got, tt.want)
+		}
 	}
 }
 
@@ -242,6 +281,7 @@ func TestNodePackageJSON(t *testing.T) {
 		{"dev script", `"dev": "ts-node src/server.ts"`},
 		{"build script", `"build": "tsc"`},
 		{"test script", `"test": "jest"`},
+		{"prisma seed", `"seed": "ts-node prisma/seed.ts"`},
 	}
 
 	for _, c := range checks {
@@ -297,6 +337,18 @@ func TestNodePackageJSONNoIntegrations(t *testing.T) {
 	}
 }
 
+func TestNodePackageJSONValidation(t *testing.T) {
+	app := testApp()
+	if strings.Contains(generateNodePackageJSON(app), "express-openapi-validator") {
+		t.Error("unexpected express-openapi-validator dependency without request validation")
+	}
+
+	app.Config.Validation = "the OpenAPI spec"
+	if !strings.Contains(generateNodePackageJSON(app), `"express-openapi-validator"`) {
+		t.Error("missing express-openapi-validator dependency with request validation")
+	}
+}
+
 // ── React package.json ──
 
 func TestReactPackageJSON(t *testing.T) {
@@ -475,6 +527,23 @@ func TestViteConfigCustomPort(t *testing.T) {
 	}
 }
 
+func TestViteConfigVanillaExtract(t *testing.T) {
+	app := testApp()
+	app.Config.Styling = "vanilla-extract"
+	output := generateViteConfig(app)
+
+	if !strings.Contains(output, "import { vanillaExtractPlugin } from '@vanilla-extract/vite-plugin'") ||
+		!strings.Contains(output, "plugins: [react(), vanillaExtractPlugin()]") {
+		t.Errorf("vite config should run the vanilla-extract plugin:\n%s", output)
+	}
+	pkg := generateReactPackageJSON(app)
+	for _, dep := range []string{"@vanilla-extract/css", "@vanilla-extract/vite-plugin"} {
+		if !strings.Contains(pkg, dep) {
+			t.Errorf("package.json: missing %s", dep)
+		}
+	}
+}
+
 // ── README ──
 
 func TestReadme(t *testing.T) {
@@ -810,6 +879,7 @@ func TestGenerateWritesFiles(t *testing.T) {
 		"react/tsconfig.json",
 		"react/vite.config.ts",
 		"react/jest.config.cjs",
+		"turbo.json",
 		"README.md",
 		".env.example",
 		"start.sh",
@@ -857,6 +927,7 @@ func TestGenerateVuePythonWritesFiles(t *testing.T) {
 		"node/package.json",
 		"node/tsconfig.json",
 		"node/jest.config.js",
+		"turbo.json",
 	}
 	for _, f := range unexpectedFiles {
 		path := filepath.Join(dir, f)
@@ -1021,7 +1092,7 @@ func TestFullIntegration(t *testing.T) {
 		}
 	}
 
-	// Root package.json: workspaces + concurrently
+	// Root package.json: workspaces + turbo
 	rootPkg, _ := os.ReadFile(filepath.Join(dir, "package.json"))
 	rootPkgStr := string(rootPkg)
 	if !strings.Contains(rootPkgStr, `"taskflow"`) {
@@ -1030,8 +1101,8 @@ func TestFullIntegration(t *testing.T) {
 	if !strings.Contains(rootPkgStr, `"workspaces"`) {
 		t.Error("root package.json: missing workspaces")
 	}
-	if !strings.Contains(rootPkgStr, `"concurrently"`) {
-		t.Error("root package.json: missing concurrently")
+	if !strings.Contains(rootPkgStr, `"turbo"`) {
+		t.Error("root package.json: missing turbo")
 	}
 
 	// Node package.json: express, prisma
@@ -1084,3 +1155,48 @@ func TestFullIntegration(t *testing.T) {
 
 	t.Logf("Generated %d scaffold files to %s", len(expectedFiles), dir)
 }
+
+// ── build-info.json ──
+
+func TestBuildInfo(t *testing.T) {
+	dir := t.TempDir()
+	app := testApp()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "build-info.json")); err == nil {
+		t.Error("build-info.json should only be written for a build")
+	}
+
+	app.Build = &ir.BuildInfo{Compiler: "0.4.0", IRHash: "sha256:abc", GeneratedAt: "2026-03-01T12:00:00Z"}
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "build-info.json"))
+	if err != nil {
+		t.Fatal("missing build-info.json")
+	}
+	want := `{
+  "app": "TaskFlow",
+  "compiler": "0.4.0",
+  "ir_hash": "sha256:abc",
+  "generated_at": "2026-03-01T12:00:00Z"
+}
+`
+	if string(content) != want {
+		t.Errorf("build-info.json =\n%s\nwant\n%s", content, want)
+	}
+}
+
+func TestViteConfigTargetEnvironment(t *testing.T) {
+	app := testApp()
+	app.Environments = []*ir.Environment{{Name: "production", Config: map[string]string{"url": "app example com"}}}
+	if strings.Contains(generateViteConfig(app), "allowedHosts") {
+		t.Error("a local build should leave Vite's hosts alone")
+	}
+	app.Config.Env = "production"
+	output := generateViteConfig(app)
+	if strings.Count(output, "allowedHosts: ['app.example.com'],") != 2 {
+		t.Errorf("the dev and preview servers should answer on the environment's host:\n%s", output)
+	}
+}
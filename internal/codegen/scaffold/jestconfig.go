@@ -24,6 +24,8 @@ func generateJestConfig() string {
 
 // generateReactJestConfig produces react/jest.config.cjs for component tests.
 // Uses jsdom environment since component tests render React components.
+// Stylesheets are mapped to identity-obj-proxy, so CSS module classes keep
+// their plain names.
 // Overrides tsconfig settings because the Vite-oriented tsconfig uses
 // ESNext/bundler modules which ts-jest cannot process.
 func generateReactJestConfig() string {
@@ -36,6 +38,9 @@ func generateReactJestConfig() string {
 	b.WriteString("  roots: ['<rootDir>/src'],\n")
 	b.WriteString("  setupFiles: ['<rootDir>/jest.setup.cjs'],\n")
 	b.WriteString("  moduleFileExtensions: ['ts', 'tsx', 'js', 'jsx', 'json'],\n")
+	b.WriteString("  moduleNameMapper: {\n")
+	b.WriteString("    '\\\\.css$': 'identity-obj-proxy',\n")
+	b.WriteString("  },\n")
 	b.WriteString("  transform: {\n")
 	b.WriteString("    '^.+\\\\.tsx?$': ['ts-jest', {\n")
 	b.WriteString("      tsconfig: {\n")
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/barun-bash/human/internal/codegen/grpc"
 	"github.com/barun-bash/human/internal/codegen/storybook"
 	"github.com/barun-bash/human/internal/codegen/themes"
 	"github.com/barun-bash/human/internal/ir"
@@ -17,47 +18,25 @@ func generateRootPackageJSON(app *ir.Application) string {
 	var b strings.Builder
 	name := appNameLower(app)
 
-	frontend := ""
 	backend := ""
 	deploy := ""
 	if app.Config != nil {
-		frontend = strings.ToLower(app.Config.Frontend)
 		backend = strings.ToLower(app.Config.Backend)
 		deploy = strings.ToLower(app.Config.Deploy)
 	}
 
-	// Determine which workspaces exist
-	var workspaces []string
-	if strings.Contains(backend, "node") {
-		workspaces = append(workspaces, "node")
-	}
-	if strings.Contains(frontend, "react") {
-		workspaces = append(workspaces, "react")
-	}
-	if strings.Contains(frontend, "vue") {
-		workspaces = append(workspaces, "vue")
-	}
-	if strings.Contains(frontend, "angular") {
-		workspaces = append(workspaces, "angular")
-	}
-	if strings.Contains(frontend, "svelte") {
-		workspaces = append(workspaces, "svelte")
-	}
-
-	// Determine frontend workspace name for scripts
-	frontendWS := ""
-	for _, ws := range []string{"react", "vue", "angular", "svelte"} {
-		if strings.Contains(frontend, ws) {
-			frontendWS = ws
-			break
-		}
-	}
+	workspaces := jsWorkspaces(app)
+	frontendWS := frontendWorkspace(app)
+	turbo := usesTurbo(app)
 
 	b.WriteString("{\n")
 	fmt.Fprintf(&b, "  \"name\": \"%s\",\n", name)
 	b.WriteString("  \"version\": \"0.1.0\",\n")
 	b.WriteString("  \"private\": true,\n")
 	fmt.Fprintf(&b, "  \"description\": \"%s — generated by Human compiler\",\n", app.Name)
+	if turbo {
+		fmt.Fprintf(&b, "  \"packageManager\": \"%s\",\n", turboPackageManager)
+	}
 
 	if len(workspaces) > 0 {
 		b.WriteString("  \"workspaces\": [\n")
@@ -75,12 +54,15 @@ func generateRootPackageJSON(app *ir.Application) string {
 
 	// Build dev/start/build/test scripts based on active workspaces
 	var scripts []string
-	if strings.Contains(backend, "node") && frontendWS != "" {
+	if turbo {
+		// Multi-package workspace: Turborepo runs each task across packages
+		// in dependency order with caching (see turbo.json).
 		scripts = append(scripts,
-			fmt.Sprintf("    \"dev\": \"concurrently \\\"npm run dev --workspace=node\\\" \\\"npm run dev --workspace=%s\\\"\"", frontendWS),
-			fmt.Sprintf("    \"start\": \"concurrently \\\"npm run start --workspace=node\\\" \\\"npm run start --workspace=%s\\\"\"", frontendWS),
-			fmt.Sprintf("    \"build\": \"npm run build --workspace=node && npm run build --workspace=%s\"", frontendWS),
-			fmt.Sprintf("    \"test\": \"npm run test --workspace=node && npm run test --workspace=%s\"", frontendWS),
+			"    \"dev\": \"turbo run dev\"",
+			"    \"start\": \"turbo run start\"",
+			"    \"build\": \"turbo run build\"",
+			"    \"test\": \"turbo run test\"",
+			"    \"lint\": \"turbo run lint\"",
 		)
 	} else if strings.Contains(backend, "node") {
 		scripts = append(scripts,
@@ -99,8 +81,14 @@ func generateRootPackageJSON(app *ir.Application) string {
 
 	// Prisma scripts only for Node backend
 	if strings.Contains(backend, "node") {
+		migrate := "    \"db:migrate\": \"cd node && npx prisma migrate deploy\""
+		if !ir.UsesPostgres(app) {
+			// Migrations are generated for PostgreSQL; other databases
+			// have the schema pushed.
+			migrate = "    \"db:migrate\": \"cd node && npx prisma db push\""
+		}
 		scripts = append(scripts,
-			"    \"db:migrate\": \"cd node && npx prisma migrate deploy\"",
+			migrate,
 			"    \"db:seed\": \"cd node && npx prisma db seed\"",
 			"    \"db:studio\": \"cd node && npx prisma studio\"",
 		)
@@ -124,11 +112,11 @@ func generateRootPackageJSON(app *ir.Application) string {
 	}
 	b.WriteString("  }")
 
-	// devDependencies only if we have multiple workspaces (need concurrently)
-	if len(workspaces) > 1 {
+	// devDependencies only if we have multiple workspaces (need turbo)
+	if turbo {
 		b.WriteString(",\n")
 		b.WriteString("  \"devDependencies\": {\n")
-		b.WriteString("    \"concurrently\": \"^9.0.0\"\n")
+		b.WriteString("    \"turbo\": \"^2.3.0\"\n")
 		b.WriteString("  }\n")
 	} else {
 		b.WriteString("\n")
@@ -167,6 +155,29 @@ func generateNodePackageJSON(app *ir.Application) string {
 		"typescript":          "^5.7.0",
 	}
 
+	if grpc.IsEnabled(app) {
+		deps["@grpc/grpc-js"] = "^1.12.0"
+		deps["@grpc/proto-loader"] = "^0.7.13"
+	}
+
+	if app.Database != nil && app.Database.ReadReplica {
+		deps["@prisma/extension-read-replicas"] = "^0.4.0"
+	}
+
+	if ir.UsesJobQueue(app) {
+		deps["bullmq"] = "^5.34.0"
+		deps["ioredis"] = "^5.4.0"
+	}
+
+	if len(app.Documents) > 0 {
+		deps["pdfkit"] = "^0.15.0"
+		devDeps["@types/pdfkit"] = "^0.13.5"
+	}
+
+	if ir.ValidatesRequests(app) {
+		deps["express-openapi-validator"] = "^5.3.0"
+	}
+
 	// Inject integration-specific dependencies
 	for _, integ := range app.Integrations {
 		integDeps, integDevDeps := integrationDependencies(integ.Type)
@@ -187,8 +198,23 @@ func generateNodePackageJSON(app *ir.Application) string {
 	b.WriteString("    \"start\": \"node dist/server.js\",\n")
 	b.WriteString("    \"dev\": \"ts-node src/server.ts\",\n")
 	b.WriteString("    \"build\": \"tsc\",\n")
+	if ir.UsesFieldEncryption(app) {
+		b.WriteString("    \"rotate-keys\": \"ts-node src/scripts/rotate-field-keys.ts\",\n")
+	}
+	if ir.Bills(app) {
+		b.WriteString("    \"sync-plans\": \"ts-node src/scripts/sync-plans.ts\",\n")
+	}
+	if ir.UsesJobQueue(app) {
+		b.WriteString("    \"worker\": \"node dist/worker.js\",\n")
+		b.WriteString("    \"dev:worker\": \"ts-node src/worker.ts\",\n")
+	}
 	b.WriteString("    \"test\": \"jest\"\n")
 	b.WriteString("  },\n")
+	if len(app.Data) > 0 {
+		b.WriteString("  \"prisma\": {\n")
+		b.WriteString("    \"seed\": \"ts-node prisma/seed.ts\"\n")
+		b.WriteString("  },\n")
+	}
 
 	writeSortedDepsInline := func(label string, m map[string]string) {
 		fmt.Fprintf(&b, "  \"%s\": {\n", label)
@@ -233,6 +259,8 @@ func integrationDependencies(integrationType string) (deps, devDeps map[string]s
 		deps["stripe"] = "^17.0.0"
 	case "messaging":
 		deps["@slack/webhook"] = "^7.0.0"
+	case "sms":
+		deps["twilio"] = "^5.3.0"
 	case "oauth":
 		deps["passport"] = "^0.7.0"
 		deps["passport-google-oauth20"] = "^2.0.0"
@@ -260,16 +288,17 @@ func generateReactPackageJSON(app *ir.Application) string {
 	}
 	devDeps := map[string]string{
 		"@testing-library/jest-dom": "^6.6.0",
-		"@testing-library/react":   "^16.1.0",
-		"@types/jest":              "^29.5.0",
-		"@types/react":             "^19.0.0",
-		"@types/react-dom":         "^19.0.0",
-		"@vitejs/plugin-react":     "^4.3.0",
-		"jest":                     "^29.7.0",
-		"jest-environment-jsdom":   "^29.7.0",
-		"ts-jest":                  "^29.2.0",
-		"typescript":               "^5.7.0",
-		"vite":                     "^6.0.0",
+		"@testing-library/react":    "^16.1.0",
+		"@types/jest":               "^29.5.0",
+		"@types/react":              "^19.0.0",
+		"@types/react-dom":          "^19.0.0",
+		"@vitejs/plugin-react":      "^4.3.0",
+		"identity-obj-proxy":        "^3.0.0",
+		"jest":                      "^29.7.0",
+		"jest-environment-jsdom":    "^29.7.0",
+		"ts-jest":                   "^29.2.0",
+		"typescript":                "^5.7.0",
+		"vite":                      "^6.0.0",
 	}
 
 	// Inject design system dependencies
@@ -300,6 +329,29 @@ func generateReactPackageJSON(app *ir.Application) string {
 		devDeps["postcss"] = "^8.4.0"
 	}
 
+	// Styling system packages (Tailwind, vanilla-extract, styled-components)
+	stylingDeps, stylingDevDeps := themes.StylingDependencies(ir.Styling(app))
+	for k, v := range stylingDeps {
+		deps[k] = v
+	}
+	for k, v := range stylingDevDeps {
+		devDeps[k] = v
+	}
+
+	// Self-hosted theme fonts (offline builds)
+	for k, v := range themes.FontDependencies(app.Theme) {
+		deps[k] = v
+	}
+
+	if len(app.Charts) > 0 {
+		deps["recharts"] = "^2.15.0"
+	}
+	if len(app.Reorders) > 0 {
+		deps["@dnd-kit/core"] = "^6.3.0"
+		deps["@dnd-kit/sortable"] = "^10.0.0"
+		deps["@dnd-kit/utilities"] = "^3.2.0"
+	}
+
 	// Storybook dependencies
 	for k, v := range storybook.DevDependencies("react") {
 		devDeps[k] = v
@@ -357,6 +409,27 @@ func generateVuePackageJSON(app *ir.Application) string {
 		devDeps["postcss"] = "^8.4.0"
 	}
 
+	// Styling system packages (Tailwind, vanilla-extract, styled-components)
+	stylingDeps, stylingDevDeps := themes.StylingDependencies(ir.Styling(app))
+	for k, v := range stylingDeps {
+		deps[k] = v
+	}
+	for k, v := range stylingDevDeps {
+		devDeps[k] = v
+	}
+
+	// Self-hosted theme fonts (offline builds)
+	for k, v := range themes.FontDependencies(app.Theme) {
+		deps[k] = v
+	}
+
+	if len(app.Charts) > 0 {
+		deps["chart.js"] = "^4.4.0"
+	}
+	if len(app.Reorders) > 0 {
+		deps["vuedraggable"] = "^4.1.0"
+	}
+
 	// Storybook dependencies
 	for k, v := range storybook.DevDependencies("vue") {
 		devDeps[k] = v
@@ -122,6 +122,18 @@ func generateReadme(app *ir.Application) string {
 		b.WriteString("```\n\n")
 	}
 
+	// Turborepo workspace — build/test/lint across all JS packages
+	if usesTurbo(app) {
+		b.WriteString("### Workspace tasks\n\n")
+		b.WriteString("The JavaScript packages form a single Turborepo workspace. ")
+		b.WriteString("Tasks run across every package with caching:\n\n")
+		b.WriteString("```bash\n")
+		b.WriteString("npm run build   # turbo run build\n")
+		b.WriteString("npm run test    # turbo run test\n")
+		b.WriteString("npm run lint    # turbo run lint\n")
+		b.WriteString("```\n\n")
+	}
+
 	// Option 2: Docker
 	b.WriteString("### Option 2: Docker\n\n")
 	b.WriteString("```bash\n")
@@ -133,6 +145,24 @@ func generateReadme(app *ir.Application) string {
 	}
 	b.WriteString("```\n\n")
 
+	// Backups from the database block's backup rules
+	if ir.BacksUpDatabase(app) {
+		backup := app.Database.Backup
+		b.WriteString("## Backups\n\n")
+		by := "`.github/workflows/backup.yml`"
+		if app.Config != nil && strings.Contains(strings.ToLower(app.Config.Deploy), "docker") {
+			by = "the `backup` service in docker-compose.yml and by " + by
+		}
+		fmt.Fprintf(&b, "The database is backed up %s UTC (`%s`) by %s.", backup.Description, backup.Cron(), by)
+		if backup.RetentionDays > 0 {
+			fmt.Fprintf(&b, " Backups are kept for %d days.", backup.RetentionDays)
+		}
+		b.WriteString("\n\n```bash\n")
+		b.WriteString("./backup/backup.sh                 # back up now\n")
+		b.WriteString("./backup/restore.sh latest         # restore the newest backup\n")
+		b.WriteString("```\n\n")
+	}
+
 	// Ports — adapt to stack
 	b.WriteString("## Ports\n\n")
 	b.WriteString("| Service | Port |\n")
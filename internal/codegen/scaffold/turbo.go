@@ -0,0 +1,93 @@
+package scaffold
+
+import (
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// turboPackageManager is pinned in the root package.json because Turborepo
+// requires the packageManager field to resolve the workspace graph.
+const turboPackageManager = "npm@10.9.0"
+
+// jsWorkspaces returns the npm workspace directories for the app's
+// JavaScript packages, backend first.
+func jsWorkspaces(app *ir.Application) []string {
+	frontend := ""
+	backend := ""
+	if app.Config != nil {
+		frontend = strings.ToLower(app.Config.Frontend)
+		backend = strings.ToLower(app.Config.Backend)
+	}
+
+	var workspaces []string
+	if strings.Contains(backend, "node") {
+		workspaces = append(workspaces, "node")
+	}
+	for _, ws := range []string{"react", "vue", "angular", "svelte"} {
+		if strings.Contains(frontend, ws) {
+			workspaces = append(workspaces, ws)
+		}
+	}
+	return workspaces
+}
+
+// frontendWorkspace returns the workspace name of the configured frontend
+// framework, or "" when the app has no JavaScript frontend.
+func frontendWorkspace(app *ir.Application) string {
+	if app.Config == nil {
+		return ""
+	}
+	frontend := strings.ToLower(app.Config.Frontend)
+	for _, ws := range []string{"react", "vue", "angular", "svelte"} {
+		if strings.Contains(frontend, ws) {
+			return ws
+		}
+	}
+	return ""
+}
+
+// usesTurbo reports whether the output is a multi-package JS workspace that
+// should be orchestrated by Turborepo rather than per-folder scripts.
+func usesTurbo(app *ir.Application) bool {
+	return len(jsWorkspaces(app)) > 1
+}
+
+// generateTurboJSON produces turbo.json with build/test/lint pipelines.
+// "^build" builds a workspace's own package dependencies first. The
+// generated workspaces don't depend on each other — each frontend has its
+// own copy of the models — so turbo builds them in parallel, caching each;
+// the "^build" only matters once a user adds a shared package. dev and
+// start are persistent and never cached.
+func generateTurboJSON() string {
+	var b strings.Builder
+
+	b.WriteString("{\n")
+	b.WriteString("  \"$schema\": \"https://turbo.build/schema.json\",\n")
+	b.WriteString("  \"globalDependencies\": [\".env\"],\n")
+	b.WriteString("  \"tasks\": {\n")
+	b.WriteString("    \"build\": {\n")
+	b.WriteString("      \"dependsOn\": [\"^build\"],\n")
+	b.WriteString("      \"outputs\": [\"dist/**\", \"build/**\", \".svelte-kit/**\"]\n")
+	b.WriteString("    },\n")
+	b.WriteString("    \"test\": {\n")
+	b.WriteString("      \"dependsOn\": [\"^build\"],\n")
+	b.WriteString("      \"outputs\": [\"coverage/**\"]\n")
+	b.WriteString("    },\n")
+	b.WriteString("    \"lint\": {\n")
+	b.WriteString("      \"outputs\": []\n")
+	b.WriteString("    },\n")
+	b.WriteString("    \"dev\": {\n")
+	b.WriteString("      \"cache\": false,\n")
+	b.WriteString("      \"persistent\": true\n")
+	b.WriteString("    },\n")
+	b.WriteString("    \"start\": {\n")
+	b.WriteString("      \"dependsOn\": [\"build\"],\n")
+	b.WriteString("      \"cache\": false,\n")
+	b.WriteString("      \"persistent\": true\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
@@ -8,7 +8,9 @@ import (
 )
 
 // generateViteConfig produces react/vite.config.ts with the React plugin
-// and an API proxy to the backend dev server.
+// and an API proxy to the backend dev server. Apps styled using
+// vanilla-extract get its plugin too, and a build targeting an environment
+// lets the dev and preview servers answer on its host.
 func generateViteConfig(app *ir.Application) string {
 	port := 3001
 	if app.Config != nil && app.Config.Ports.Backend > 0 {
@@ -19,17 +21,46 @@ func generateViteConfig(app *ir.Application) string {
 
 	b.WriteString("import { defineConfig } from 'vite'\n")
 	b.WriteString("import react from '@vitejs/plugin-react'\n")
+	vanillaExtract := ir.Styling(app) == ir.StylingVanillaExtract
+	if vanillaExtract {
+		b.WriteString("import { vanillaExtractPlugin } from '@vanilla-extract/vite-plugin'\n")
+	}
 	b.WriteString("\n")
 	b.WriteString("export default defineConfig({\n")
-	b.WriteString("  plugins: [react()],\n")
+	if vanillaExtract {
+		b.WriteString("  plugins: [react(), vanillaExtractPlugin()],\n")
+	} else {
+		b.WriteString("  plugins: [react()],\n")
+	}
+	// A build targeting an environment answers on its host
+	host := ""
+	if env := ir.TargetEnvironment(app); env != nil {
+		host = env.Host()
+	}
 	b.WriteString("  server: {\n")
+	if host != "" {
+		fmt.Fprintf(&b, "    allowedHosts: ['%s'],\n", host)
+	}
 	b.WriteString("    proxy: {\n")
 	b.WriteString("      '/api': {\n")
 	fmt.Fprintf(&b, "        target: 'http://localhost:%d',\n", port)
 	b.WriteString("        changeOrigin: true,\n")
 	b.WriteString("      },\n")
+	if app.Sitemap != nil {
+		// The backend builds sitemap.xml and robots.txt under /api.
+		for _, file := range []string{"sitemap.xml", "robots.txt"} {
+			fmt.Fprintf(&b, "      '/%s': {\n", file)
+			fmt.Fprintf(&b, "        target: 'http://localhost:%d',\n", port)
+			b.WriteString("        changeOrigin: true,\n")
+			b.WriteString("        rewrite: (path) => '/api' + path,\n")
+			b.WriteString("      },\n")
+		}
+	}
 	b.WriteString("    },\n")
 	b.WriteString("  },\n")
+	if host != "" {
+		fmt.Fprintf(&b, "  preview: {\n    allowedHosts: ['%s'],\n  },\n", host)
+	}
 	b.WriteString("})\n")
 
 	return b.String()
@@ -0,0 +1,394 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateGo produces a Go module using only net/http, with typed request
+// params and generic responses.
+func generateGo(app *ir.Application) map[string]string {
+	return map[string]string{
+		"go.mod":    goModFile(app),
+		"client.go": goClient(app),
+		"models.go": goModels(app),
+		"README.md": goReadme(app),
+	}
+}
+
+// goPackage returns the Go package name: "Task Flow" → "taskflow".
+func goPackage(app *ir.Application) string {
+	name := strings.Join(words(app.Name), "")
+	if name == "" {
+		return "client"
+	}
+	return name
+}
+
+// goModulePath returns the module path. It is a placeholder path the team
+// replaces with their repository before publishing.
+func goModulePath(app *ir.Application) string {
+	return "example.com/" + packageName(app)
+}
+
+func goModFile(app *ir.Application) string {
+	return fmt.Sprintf("module %s\n\ngo 1.21\n", goModulePath(app))
+}
+
+func goModels(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Code generated by Human compiler. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", goPackage(app))
+
+	b.WriteString("// Response is the envelope every endpoint returns.\n")
+	b.WriteString("type Response[T any] struct {\n")
+	b.WriteString("\tData       T           `json:\"data\"`\n")
+	b.WriteString("\tToken      string      `json:\"token,omitempty\"`\n")
+	b.WriteString("\tPagination *Pagination `json:\"pagination,omitempty\"`\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("// Pagination describes a page of list results.\n")
+	b.WriteString("type Pagination struct {\n")
+	b.WriteString("\tPage       int `json:\"page\"`\n")
+	b.WriteString("\tLimit      int `json:\"limit\"`\n")
+	b.WriteString("\tTotal      int `json:\"total\"`\n")
+	b.WriteString("\tTotalPages int `json:\"totalPages\"`\n")
+	b.WriteString("}\n")
+
+	for _, model := range app.Data {
+		fmt.Fprintf(&b, "\n// %s is the %s data model.\n", model.Name, model.Name)
+		fmt.Fprintf(&b, "type %s struct {\n", model.Name)
+		b.WriteString("\tID string `json:\"id\"`\n")
+		for _, f := range model.Fields {
+			fmt.Fprintf(&b, "\t%s %s `json:\"%s,omitempty\"`\n", goIdent(f.Name), goType(f.Type), f.Name)
+		}
+		for _, rel := range model.Relations {
+			if rel.Kind == "belongs_to" {
+				fmt.Fprintf(&b, "\t%sID string `json:\"%sId,omitempty\"`\n", toPascalCase(rel.Target), toCamelCase(rel.Target))
+			}
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, op := range operations(app) {
+		if len(op.Params) == 0 {
+			continue
+		}
+		name := toPascalCase(op.Endpoint.Name) + "Params"
+		fmt.Fprintf(&b, "\n// %s are the parameters for %s.\n", name, toPascalCase(op.Endpoint.Name))
+		fmt.Fprintf(&b, "type %s struct {\n", name)
+		for _, p := range op.Params {
+			fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", goIdent(p.Name), goType(p.Type), tsParamName(p.Name))
+		}
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+func goClient(app *ir.Application) string {
+	var b strings.Builder
+	ops := operations(app)
+
+	hasQuery := false
+	for _, op := range ops {
+		if op.Method == "GET" && len(op.Params) > 0 {
+			hasQuery = true
+		}
+	}
+
+	b.WriteString("// Code generated by Human compiler. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "// Package %s is a client for the %s API.\n", goPackage(app), appTitle(app))
+	fmt.Fprintf(&b, "package %s\n\n", goPackage(app))
+	b.WriteString("import (\n")
+	b.WriteString("\t\"bytes\"\n")
+	b.WriteString("\t\"context\"\n")
+	b.WriteString("\t\"encoding/json\"\n")
+	b.WriteString("\t\"errors\"\n")
+	b.WriteString("\t\"fmt\"\n")
+	b.WriteString("\t\"io\"\n")
+	b.WriteString("\t\"net/http\"\n")
+	if hasQuery {
+		b.WriteString("\t\"net/url\"\n")
+	}
+	b.WriteString("\t\"strconv\"\n")
+	b.WriteString("\t\"strings\"\n")
+	b.WriteString("\t\"sync\"\n")
+	b.WriteString("\t\"time\"\n")
+	b.WriteString(")\n\n")
+
+	b.WriteString(`// APIError is returned for non-2xx responses.
+type APIError struct {
+	Status  int
+	Message string
+	Body    []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.Status, e.Message)
+}
+
+// Client calls the API. Create one with New; it is safe for concurrent use.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	MaxRetries int           // retries for network errors, 429, and 5xx (default 2)
+	RetryDelay time.Duration // base delay for exponential backoff (default 300ms)
+
+	mu    sync.RWMutex
+	token string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithToken sets the bearer token sent on every request.
+func WithToken(token string) Option { return func(c *Client) { c.token = token } }
+
+// WithHTTPClient replaces the default http.Client.
+func WithHTTPClient(h *http.Client) Option { return func(c *Client) { c.HTTPClient = h } }
+
+// WithRetries sets the retry count and base backoff delay.
+func WithRetries(max int, delay time.Duration) Option {
+	return func(c *Client) { c.MaxRetries, c.RetryDelay = max, delay }
+}
+
+// New returns a client for the API at baseURL (e.g. "https://api.example.com").
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		MaxRetries: 2,
+		RetryDelay: 300 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SetToken sets (or clears, with "") the bearer token. Responses that carry
+// a token — sign-up and login — update it automatically.
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	c.token = token
+	c.mu.Unlock()
+}
+
+// Token returns the current bearer token.
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+`)
+
+	for _, op := range ops {
+		b.WriteString("\n")
+		writeGoMethod(&b, op)
+	}
+
+	b.WriteString(`
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusBadGateway ||
+		status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// do sends the request, retrying transient failures, and decodes the
+// response envelope into out.
+func do[T any](ctx context.Context, c *Client, method, path string, body any, out *Response[T]) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		canRetry := attempt < c.MaxRetries
+		req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		if token := c.Token(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			if canRetry && isIdempotent(method) && ctx.Err() == nil {
+				if err := sleep(ctx, c.RetryDelay<<attempt); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if canRetry && isRetryableStatus(resp.StatusCode) && (isIdempotent(method) || resp.StatusCode == http.StatusTooManyRequests) {
+			delay := c.RetryDelay << attempt
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+				delay = time.Duration(secs) * time.Second
+			}
+			if err := sleep(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			apiErr := &APIError{Status: resp.StatusCode, Message: http.StatusText(resp.StatusCode), Body: data}
+			var e struct {
+				Error string ` + "`json:\"error\"`" + `
+			}
+			if json.Unmarshal(data, &e) == nil && e.Error != "" {
+				apiErr.Message = e.Error
+			}
+			return apiErr
+		}
+
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, out); err != nil {
+				return fmt.Errorf("decoding response: %w", err)
+			}
+		}
+		if out.Token != "" {
+			c.SetToken(out.Token)
+		}
+		return nil
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// errNilParams guards against nil params pointers in generated methods.
+var errNilParams = errors.New("params must not be nil")
+`)
+
+	return b.String()
+}
+
+func writeGoMethod(b *strings.Builder, op operation) {
+	name := toPascalCase(op.Endpoint.Name)
+	result := goResultType(op)
+
+	doc := fmt.Sprintf("// %s calls %s %s", name, op.Method, op.Path)
+	if op.Endpoint.Auth {
+		doc += " (requires auth)"
+	}
+	b.WriteString(doc + ".\n")
+
+	if len(op.Params) == 0 {
+		fmt.Fprintf(b, "func (c *Client) %s(ctx context.Context) (*Response[%s], error) {\n", name, result)
+		fmt.Fprintf(b, "\tout := &Response[%s]{}\n", result)
+		fmt.Fprintf(b, "\treturn out, do(ctx, c, %q, %q, nil, out)\n", op.Method, op.Path)
+		b.WriteString("}\n")
+		return
+	}
+
+	fmt.Fprintf(b, "func (c *Client) %s(ctx context.Context, params *%sParams) (*Response[%s], error) {\n", name, name, result)
+	b.WriteString("\tif params == nil {\n")
+	b.WriteString("\t\treturn nil, errNilParams\n")
+	b.WriteString("\t}\n")
+	fmt.Fprintf(b, "\tout := &Response[%s]{}\n", result)
+	if op.Method == "GET" {
+		b.WriteString("\tq := url.Values{}\n")
+		for _, p := range op.Params {
+			fmt.Fprintf(b, "\tq.Set(%q, fmt.Sprint(params.%s))\n", tsParamName(p.Name), goIdent(p.Name))
+		}
+		fmt.Fprintf(b, "\treturn out, do(ctx, c, %q, %q+\"?\"+q.Encode(), nil, out)\n", op.Method, op.Path)
+	} else {
+		fmt.Fprintf(b, "\treturn out, do(ctx, c, %q, %q, params, out)\n", op.Method, op.Path)
+	}
+	b.WriteString("}\n")
+}
+
+func goResultType(op operation) string {
+	switch {
+	case op.Model == nil:
+		return "json.RawMessage"
+	case op.List:
+		return "[]" + op.Model.Name
+	}
+	return op.Model.Name
+}
+
+func goReadme(app *ir.Application) string {
+	var b strings.Builder
+	pkg := goPackage(app)
+
+	fmt.Fprintf(&b, "# %s\n\n", packageName(app))
+	fmt.Fprintf(&b, "Go client for the %s API. Generated by the Human compiler. Uses only the standard library.\n\n", appTitle(app))
+	b.WriteString("## Install\n\n")
+	fmt.Fprintf(&b, "Replace the placeholder module path `%s` in `go.mod` with your repository, push it, then:\n\n", goModulePath(app))
+	b.WriteString("```bash\n")
+	b.WriteString("go get <your-module-path>\n")
+	b.WriteString("```\n\n")
+	b.WriteString("## Usage\n\n")
+	b.WriteString("```go\n")
+	fmt.Fprintf(&b, "api := %s.New(\"http://localhost:3001\")\n", pkg)
+	if ops := operations(app); len(ops) > 0 {
+		op := ops[0]
+		args := "ctx"
+		if len(op.Params) > 0 {
+			args = fmt.Sprintf("ctx, &%s.%sParams{ /* ... */ }", pkg, toPascalCase(op.Endpoint.Name))
+		}
+		fmt.Fprintf(&b, "resp, err := api.%s(%s)\n", toPascalCase(op.Endpoint.Name), args)
+	}
+	b.WriteString("```\n\n")
+	writeReadmeNotes(&b, "`WithToken`", "`SetToken`", "`*APIError`")
+	writeReadmeEndpoints(&b, app, toPascalCase)
+
+	return b.String()
+}
+
+// goIdent returns an exported Go field name: "due date" → "DueDate",
+// "task_id" → "TaskID".
+func goIdent(name string) string {
+	ident := toPascalCase(name)
+	if strings.HasSuffix(ident, "Id") {
+		ident = ident[:len(ident)-2] + "ID"
+	}
+	if ident == "" {
+		return "Field"
+	}
+	return ident
+}
+
+func goType(irType string) string {
+	switch strings.ToLower(irType) {
+	case "number":
+		return "int"
+	case "decimal":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "json":
+		return "map[string]any"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,308 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generatePython produces a pip-installable package built on the standard
+// library (urllib), so installing the SDK pulls in no dependencies.
+func generatePython(app *ir.Application) map[string]string {
+	pkg := pyPackage(app)
+	return map[string]string{
+		"pyproject.toml":     pyProject(app),
+		pkg + "/__init__.py": pyInit(app),
+		pkg + "/client.py":   pyClient(app),
+		pkg + "/models.py":   pyModels(app),
+		pkg + "/py.typed":    "",
+		"README.md":          pyReadme(app),
+	}
+}
+
+// pyPackage returns the import name: "TaskFlow" → "taskflow_sdk".
+func pyPackage(app *ir.Application) string {
+	return strings.ReplaceAll(packageName(app), "-", "_")
+}
+
+func pyProject(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("[build-system]\n")
+	b.WriteString("requires = [\"setuptools>=68\"]\n")
+	b.WriteString("build-backend = \"setuptools.build_meta\"\n\n")
+	b.WriteString("[project]\n")
+	fmt.Fprintf(&b, "name = %q\n", packageName(app))
+	b.WriteString("version = \"0.1.0\"\n")
+	fmt.Fprintf(&b, "description = %q\n", "API client for "+appTitle(app))
+	b.WriteString("requires-python = \">=3.9\"\n")
+	b.WriteString("dependencies = []\n\n")
+	b.WriteString("[tool.setuptools.package-data]\n")
+	fmt.Fprintf(&b, "%s = [\"py.typed\"]\n", pyPackage(app))
+	return b.String()
+}
+
+func pyInit(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("# Generated by Human compiler — do not edit\n")
+	fmt.Fprintf(&b, "\"\"\"Python client for the %s API.\"\"\"\n\n", appTitle(app))
+	fmt.Fprintf(&b, "from .client import ApiError, %s\n", clientName(app))
+	b.WriteString("from .models import *  # noqa: F401,F403\n\n")
+	fmt.Fprintf(&b, "__all__ = [\"ApiError\", %q]\n", clientName(app))
+	return b.String()
+}
+
+// pyModels writes a TypedDict per data model.
+func pyModels(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("# Generated by Human compiler — do not edit\n\n")
+	b.WriteString("from typing import Any, Dict, TypedDict\n\n\n")
+
+	b.WriteString("class ApiResponse(TypedDict, total=False):\n")
+	b.WriteString("    data: Any\n")
+	b.WriteString("    token: str\n")
+	b.WriteString("    pagination: Dict[str, int]\n")
+
+	for _, model := range app.Data {
+		b.WriteString("\n\n")
+		// Functional syntax keeps field names like "due date" intact.
+		fmt.Fprintf(&b, "%s = TypedDict(\"%s\", {\n", model.Name, model.Name)
+		b.WriteString("    \"id\": str,\n")
+		for _, f := range model.Fields {
+			fmt.Fprintf(&b, "    %q: %s,\n", f.Name, pyType(f.Type))
+		}
+		for _, rel := range model.Relations {
+			if rel.Kind == "belongs_to" {
+				fmt.Fprintf(&b, "    %q: str,\n", toCamelCase(rel.Target)+"Id")
+			}
+		}
+		b.WriteString("}, total=False)\n")
+	}
+
+	b.WriteString("\n\n__all__ = [\"ApiResponse\"")
+	for _, model := range app.Data {
+		fmt.Fprintf(&b, ", %q", model.Name)
+	}
+	b.WriteString("]\n")
+	return b.String()
+}
+
+func pyClient(app *ir.Application) string {
+	var b strings.Builder
+	client := clientName(app)
+
+	b.WriteString("# Generated by Human compiler — do not edit\n\n")
+	b.WriteString(`import json
+import time
+import urllib.error
+import urllib.parse
+import urllib.request
+from typing import Any, Dict, Optional
+
+from .models import ApiResponse
+
+IDEMPOTENT = {"GET", "PUT", "DELETE"}
+RETRYABLE_STATUS = {429, 502, 503, 504}
+
+
+class ApiError(Exception):
+    """Raised for non-2xx responses."""
+
+    def __init__(self, status: int, message: str, body: Any = None):
+        super().__init__(f"{status}: {message}")
+        self.status = status
+        self.message = message
+        self.body = body
+
+
+`)
+
+	fmt.Fprintf(&b, "class %s:\n", client)
+	fmt.Fprintf(&b, "    \"\"\"Client for the %s API.\n\n", appTitle(app))
+	b.WriteString("    Responses that include a token (sign-up, login) update the client's\n")
+	b.WriteString("    bearer token automatically.\n")
+	b.WriteString("    \"\"\"\n\n")
+	b.WriteString(`    def __init__(
+        self,
+        base_url: str,
+        token: Optional[str] = None,
+        max_retries: int = 2,
+        retry_delay: float = 0.3,
+        timeout: float = 30.0,
+    ):
+        self.base_url = base_url.rstrip("/")
+        self.token = token
+        self.max_retries = max_retries
+        self.retry_delay = retry_delay
+        self.timeout = timeout
+
+    def set_token(self, token: Optional[str]) -> None:
+        """Set (or clear) the bearer token used for authenticated endpoints."""
+        self.token = token
+`)
+
+	for _, op := range operations(app) {
+		b.WriteString("\n")
+		writePyMethod(&b, op)
+	}
+
+	b.WriteString(`
+    def _request(self, method: str, path: str, params: Optional[Dict[str, Any]] = None) -> ApiResponse:
+        url = self.base_url + path
+        data = None
+        if params and method == "GET":
+            query = urllib.parse.urlencode({k: v for k, v in params.items() if v is not None})
+            if query:
+                url += "?" + query
+        elif params is not None:
+            data = json.dumps(params).encode("utf-8")
+
+        headers = {"Content-Type": "application/json", "Accept": "application/json"}
+        if self.token:
+            headers["Authorization"] = f"Bearer {self.token}"
+
+        attempt = 0
+        while True:
+            can_retry = attempt < self.max_retries
+            req = urllib.request.Request(url, data=data, headers=headers, method=method)
+            try:
+                with urllib.request.urlopen(req, timeout=self.timeout) as res:
+                    payload = _decode(res.read())
+            except urllib.error.HTTPError as err:
+                if can_retry and err.code in RETRYABLE_STATUS and (method in IDEMPOTENT or err.code == 429):
+                    time.sleep(_retry_after(err) or self.retry_delay * 2 ** attempt)
+                    attempt += 1
+                    continue
+                body = _decode(err.read())
+                message = body.get("error", err.reason) if isinstance(body, dict) else err.reason
+                raise ApiError(err.code, str(message), body) from None
+            except urllib.error.URLError:
+                if can_retry and method in IDEMPOTENT:
+                    time.sleep(self.retry_delay * 2 ** attempt)
+                    attempt += 1
+                    continue
+                raise
+
+            if isinstance(payload, dict) and isinstance(payload.get("token"), str):
+                self.token = payload["token"]
+            return payload
+
+
+def _decode(raw: bytes) -> Any:
+    if not raw:
+        return {}
+    try:
+        return json.loads(raw)
+    except ValueError:
+        return {"error": raw.decode("utf-8", "replace")}
+
+
+def _retry_after(err: urllib.error.HTTPError) -> float:
+    try:
+        return float(err.headers.get("Retry-After", 0))
+    except (TypeError, ValueError):
+        return 0.0
+`)
+
+	return b.String()
+}
+
+func writePyMethod(b *strings.Builder, op operation) {
+	name := pyIdent(toSnakeCase(op.Endpoint.Name))
+
+	var args []string
+	for _, p := range op.Params {
+		args = append(args, fmt.Sprintf("%s: %s", pyIdent(toSnakeCase(p.Name)), pyType(p.Type)))
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(b, "    def %s(self, *, %s) -> ApiResponse:\n", name, strings.Join(args, ", "))
+	} else {
+		fmt.Fprintf(b, "    def %s(self) -> ApiResponse:\n", name)
+	}
+
+	doc := fmt.Sprintf("%s %s", op.Method, op.Path)
+	if op.Endpoint.Auth {
+		doc += " (requires auth)"
+	}
+	if op.Model != nil {
+		if op.List {
+			doc += fmt.Sprintf(". data: List[%s]", op.Model.Name)
+		} else {
+			doc += fmt.Sprintf(". data: %s", op.Model.Name)
+		}
+	}
+	fmt.Fprintf(b, "        \"\"\"%s\"\"\"\n", doc)
+
+	if len(op.Params) == 0 {
+		fmt.Fprintf(b, "        return self._request(%q, %q)\n", op.Method, op.Path)
+		return
+	}
+	var pairs []string
+	for _, p := range op.Params {
+		pairs = append(pairs, fmt.Sprintf("%q: %s", tsParamName(p.Name), pyIdent(toSnakeCase(p.Name))))
+	}
+	fmt.Fprintf(b, "        return self._request(%q, %q, {%s})\n", op.Method, op.Path, strings.Join(pairs, ", "))
+}
+
+func pyReadme(app *ir.Application) string {
+	var b strings.Builder
+	client := clientName(app)
+
+	fmt.Fprintf(&b, "# %s\n\n", packageName(app))
+	fmt.Fprintf(&b, "Python client for the %s API. Generated by the Human compiler. No dependencies beyond the standard library.\n\n", appTitle(app))
+	b.WriteString("## Install\n\n")
+	b.WriteString("```bash\n")
+	b.WriteString("pip install .\n")
+	b.WriteString("```\n\n")
+	b.WriteString("## Usage\n\n")
+	b.WriteString("```python\n")
+	fmt.Fprintf(&b, "from %s import %s, ApiError\n\n", pyPackage(app), client)
+	fmt.Fprintf(&b, "api = %s(\"http://localhost:3001\")\n", client)
+	if ops := operations(app); len(ops) > 0 {
+		op := ops[0]
+		args := ""
+		if len(op.Params) > 0 {
+			args = "..."
+		}
+		fmt.Fprintf(&b, "resp = api.%s(%s)\n", pyIdent(toSnakeCase(op.Endpoint.Name)), args)
+		b.WriteString("print(resp[\"data\"])\n")
+	}
+	b.WriteString("```\n\n")
+	writeReadmeNotes(&b, "`token=`", "`set_token()`", "`ApiError`")
+	writeReadmeEndpoints(&b, app, func(name string) string { return pyIdent(toSnakeCase(name)) })
+
+	return b.String()
+}
+
+// pyKeywords are reserved words that cannot be used as argument names.
+var pyKeywords = map[string]bool{
+	"and": true, "as": true, "assert": true, "async": true, "await": true, "break": true,
+	"class": true, "continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true, "not": true,
+	"or": true, "pass": true, "raise": true, "return": true, "try": true, "while": true,
+	"with": true, "yield": true, "None": true, "True": true, "False": true,
+}
+
+// pyIdent appends an underscore to reserved words: "from" → "from_".
+func pyIdent(name string) string {
+	if pyKeywords[name] {
+		return name + "_"
+	}
+	return name
+}
+
+func pyType(irType string) string {
+	switch strings.ToLower(irType) {
+	case "number":
+		return "int"
+	case "decimal":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "json":
+		return "Dict[str, Any]"
+	default:
+		return "str"
+	}
+}
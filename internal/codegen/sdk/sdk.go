@@ -0,0 +1,290 @@
+// Package sdk generates standalone, installable API client packages for an
+// application's endpoints. Unlike the frontend API clients, an SDK has no
+// dependency on the generated app: other services install it to call the
+// API with typed methods, bearer-token auth, and automatic retries.
+package sdk
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Languages lists the supported SDK target languages.
+var Languages = []string{"typescript", "python", "go"}
+
+// NormalizeLanguage maps a user-supplied language name (including common
+// aliases like "ts" or "golang") to one of Languages, or "" if unsupported.
+func NormalizeLanguage(lang string) string {
+	switch strings.ToLower(strings.TrimSpace(lang)) {
+	case "typescript", "ts", "javascript", "js", "node":
+		return "typescript"
+	case "python", "py":
+		return "python"
+	case "go", "golang":
+		return "go"
+	}
+	return ""
+}
+
+// Generate writes the SDK package for lang into outputDir and returns the
+// number of files written.
+func Generate(app *ir.Application, lang, outputDir string) (int, error) {
+	var files map[string]string
+	switch NormalizeLanguage(lang) {
+	case "typescript":
+		files = generateTypeScript(app)
+	case "python":
+		files = generatePython(app)
+	case "go":
+		files = generateGo(app)
+	default:
+		return 0, fmt.Errorf("unsupported SDK language %q (supported: %s)", lang, strings.Join(Languages, ", "))
+	}
+
+	paths := make(map[string]string, len(files))
+	for rel, content := range files {
+		paths[filepath.Join(outputDir, rel)] = content
+	}
+	if err := codegen.WriteFiles(paths, codegen.WriteOptionsFor(app)); err != nil {
+		return 0, err
+	}
+	return len(files), nil
+}
+
+// ── Operations ──
+
+// operation is one endpoint as seen by an SDK: a method name, an HTTP route,
+// typed parameters, and the data model it returns.
+type operation struct {
+	Endpoint *ir.Endpoint
+	Method   string // GET, POST, PUT, DELETE
+	Path     string // /api/...
+	Params   []param
+	Model    *ir.DataModel // response model, nil when unknown
+	List     bool          // response is an array of Model
+}
+
+// param is an endpoint parameter with its inferred IR field type.
+type param struct {
+	Name string // as declared: "task_id", "due date"
+	Type string // IR field type: text, number, boolean, ...
+}
+
+// operations derives the SDK surface from the app's endpoints, in
+// declaration order. Methods and paths follow the Node backend's routing:
+// Get/List → GET, Delete → DELETE, Update → PUT, everything else POST.
+func operations(app *ir.Application) []operation {
+	var ops []operation
+	for _, ep := range app.APIs {
+		op := operation{
+			Endpoint: ep,
+			Method:   httpMethod(ep.Name),
+			Path:     apiPath(ep.Name),
+		}
+		noun := stripVerb(ep.Name)
+		op.Model = findModel(noun, app)
+		if op.Model != nil && op.Method == "GET" && op.Model.Name != noun {
+			op.List = true
+		}
+		for _, p := range ep.Params {
+			op.Params = append(op.Params, param{Name: p.Name, Type: paramType(p.Name, op.Model)})
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func httpMethod(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "get"), strings.HasPrefix(lower, "list"):
+		return "GET"
+	case strings.HasPrefix(lower, "delete"):
+		return "DELETE"
+	case strings.HasPrefix(lower, "update"):
+		return "PUT"
+	default:
+		return "POST"
+	}
+}
+
+func apiPath(name string) string {
+	return "/api/" + toKebabCase(stripVerb(name))
+}
+
+func stripVerb(name string) string {
+	for _, prefix := range []string{"Get", "List", "Create", "Update", "Delete"} {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return name[len(prefix):]
+		}
+	}
+	return name
+}
+
+// findModel matches a noun ("Tasks", "Task", "Categories") to a data model.
+func findModel(noun string, app *ir.Application) *ir.DataModel {
+	for _, candidate := range []string{noun, singularize(noun)} {
+		for _, m := range app.Data {
+			if strings.EqualFold(m.Name, candidate) {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+func singularize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+// paramType returns the IR type of the model field a parameter names, or
+// "text" when it names no field (ids, search terms, free-form inputs).
+func paramType(name string, model *ir.DataModel) string {
+	if model == nil {
+		return "text"
+	}
+	for _, f := range model.Fields {
+		if strings.EqualFold(f.Name, name) {
+			if f.Type == "" {
+				return "text"
+			}
+			return strings.ToLower(f.Type)
+		}
+	}
+	return "text"
+}
+
+// ── README ──
+
+// writeReadmeNotes documents auth and retry behavior, which is the same in
+// every language; the arguments name the language-specific API.
+func writeReadmeNotes(b *strings.Builder, tokenOption, setToken, errorType string) {
+	b.WriteString("## Authentication\n\n")
+	fmt.Fprintf(b, "Pass a bearer token with the %s option or call %s. ", tokenOption, setToken)
+	b.WriteString("Responses that include a `token` (sign-up, login) update the client's token automatically, ")
+	b.WriteString("so later calls to authenticated endpoints just work.\n\n")
+	b.WriteString("## Retries and errors\n\n")
+	b.WriteString("Network errors and `502`/`503`/`504` responses are retried with exponential backoff for ")
+	b.WriteString("idempotent requests (GET, PUT, DELETE). `429` responses are retried for every method, honoring `Retry-After`. ")
+	fmt.Fprintf(b, "Other non-2xx responses raise %s with the HTTP status and the API's error message.\n\n", errorType)
+}
+
+// writeReadmeEndpoints lists every endpoint with its client method name.
+func writeReadmeEndpoints(b *strings.Builder, app *ir.Application, methodName func(string) string) {
+	ops := operations(app)
+	if len(ops) == 0 {
+		return
+	}
+	b.WriteString("## Endpoints\n\n")
+	b.WriteString("| Method | Path | Client method | Auth |\n")
+	b.WriteString("|--------|------|---------------|------|\n")
+	for _, op := range ops {
+		auth := ""
+		if op.Endpoint.Auth {
+			auth = "required"
+		}
+		fmt.Fprintf(b, "| %s | `%s` | `%s` | %s |\n", op.Method, op.Path, methodName(op.Endpoint.Name), auth)
+	}
+}
+
+// ── Naming ──
+
+// appTitle returns the app name for prose, falling back to a generic name.
+func appTitle(app *ir.Application) string {
+	if app.Name == "" {
+		return "the application"
+	}
+	return app.Name
+}
+
+// packageName returns the kebab-case package name: "TaskFlow" → "taskflow-sdk".
+func packageName(app *ir.Application) string {
+	name := strings.ToLower(strings.Join(strings.Fields(app.Name), "-"))
+	if name == "" {
+		name = "app"
+	}
+	return name + "-sdk"
+}
+
+// clientName returns the client class/type name: "TaskFlow" → "TaskFlowClient".
+func clientName(app *ir.Application) string {
+	name := toPascalCase(app.Name)
+	if name == "" {
+		name = "Api"
+	}
+	return name + "Client"
+}
+
+// words splits an identifier on spaces, underscores, hyphens, and case
+// boundaries: "GetTasksByUser" → [get tasks by user], "due date" → [due date].
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	runes := []rune(s)
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, strings.ToLower(string(cur)))
+			cur = nil
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == ' ' || r == '_' || r == '-':
+			flush()
+		case unicode.IsUpper(r) && len(cur) > 0 &&
+			(!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return out
+}
+
+func toPascalCase(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+func toCamelCase(s string) string {
+	p := toPascalCase(s)
+	if p == "" {
+		return p
+	}
+	return strings.ToLower(p[:1]) + p[1:]
+}
+
+func toSnakeCase(s string) string {
+	return strings.Join(words(s), "_")
+}
+
+// toKebabCase matches the backend route naming exactly (every uppercase
+// letter starts a segment), so SDK paths always hit the generated routes.
+func toKebabCase(s string) string {
+	var result []rune
+	for i, r := range s {
+		if unicode.IsUpper(r) && i > 0 {
+			result = append(result, '-')
+		}
+		result = append(result, unicode.ToLower(r))
+	}
+	return string(result)
+}
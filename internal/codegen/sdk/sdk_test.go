@@ -0,0 +1,189 @@
+package sdk
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func testApp() *ir.Application {
+	return &ir.Application{
+		Name: "TaskFlow",
+		Data: []*ir.DataModel{
+			{
+				Name: "User",
+				Fields: []*ir.DataField{
+					{Name: "name", Type: "text", Required: true},
+					{Name: "email", Type: "email", Required: true},
+				},
+			},
+			{
+				Name: "Task",
+				Fields: []*ir.DataField{
+					{Name: "title", Type: "text", Required: true},
+					{Name: "status", Type: "enum", EnumValues: []string{"pending", "done"}},
+					{Name: "due date", Type: "date"},
+					{Name: "estimate", Type: "number"},
+				},
+				Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}},
+			},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "SignUp", Params: []*ir.Param{{Name: "name"}, {Name: "email"}, {Name: "password"}}},
+			{Name: "GetTasks", Auth: true, Params: []*ir.Param{{Name: "status"}}},
+			{Name: "CreateTask", Auth: true, Params: []*ir.Param{{Name: "title"}, {Name: "due date"}, {Name: "estimate"}}},
+			{Name: "DeleteTask", Auth: true, Params: []*ir.Param{{Name: "task_id"}}},
+			{Name: "GetProfile", Auth: true},
+		},
+	}
+}
+
+func TestOperations(t *testing.T) {
+	ops := operations(testApp())
+	tests := []struct {
+		method, path, model string
+		list                bool
+	}{
+		{"POST", "/api/sign-up", "", false},
+		{"GET", "/api/tasks", "Task", true},
+		{"POST", "/api/task", "Task", false},
+		{"DELETE", "/api/task", "Task", false},
+		{"GET", "/api/profile", "", false},
+	}
+	for i, tt := range tests {
+		op := ops[i]
+		if op.Method != tt.method || op.Path != tt.path || op.List != tt.list {
+			t.Errorf("op %d = %s %s list=%v, want %s %s list=%v", i, op.Method, op.Path, op.List, tt.method, tt.path, tt.list)
+		}
+		model := ""
+		if op.Model != nil {
+			model = op.Model.Name
+		}
+		if model != tt.model {
+			t.Errorf("op %d model = %q, want %q", i, model, tt.model)
+		}
+	}
+	if got := ops[2].Params[2].Type; got != "number" {
+		t.Errorf("estimate param type = %q, want number (from the Task field)", got)
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	for in, want := range map[string]string{"ts": "typescript", "TypeScript": "typescript", "py": "python", "golang": "go", "ruby": ""} {
+		if got := NormalizeLanguage(in); got != want {
+			t.Errorf("NormalizeLanguage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateUnsupported(t *testing.T) {
+	if _, err := Generate(testApp(), "ruby", t.TempDir()); err == nil {
+		t.Error("expected error for unsupported language")
+	}
+}
+
+func TestTypeScriptSDK(t *testing.T) {
+	files := generateTypeScript(testApp())
+	client := files["src/client.ts"]
+
+	for _, want := range []string{
+		"export class TaskFlowClient {",
+		"  signUp(params: SignUpParams): Promise<ApiResponse<unknown>> {",
+		"    return this.request<Task[]>('GET', '/api/tasks', params);",
+		"  getProfile(): Promise<ApiResponse<unknown>> {",
+		"headers['Authorization'] = `Bearer ${this.token}`",
+		"if (typeof payload?.token === 'string') {",
+		"RETRYABLE_STATUS.has(res.status)",
+		"Retry-After",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q", want)
+		}
+	}
+
+	types := files["src/types.ts"]
+	for _, want := range []string{
+		"status?: 'pending' | 'done';",
+		"'due date'?: string;",
+		"userId: string;",
+		"export interface CreateTaskParams {\n  title: string;\n  dueDate: string;\n  estimate: number;\n}",
+	} {
+		if !strings.Contains(types, want) {
+			t.Errorf("types.ts missing %q\n%s", want, types)
+		}
+	}
+
+	if !strings.Contains(files["package.json"], `"name": "taskflow-sdk"`) {
+		t.Error("package.json should name the package taskflow-sdk")
+	}
+}
+
+func TestPythonSDK(t *testing.T) {
+	files := generatePython(testApp())
+	client := files["taskflow_sdk/client.py"]
+	if client == "" {
+		t.Fatal("missing taskflow_sdk/client.py")
+	}
+
+	for _, want := range []string{
+		"class TaskFlowClient:",
+		"    def create_task(self, *, title: str, due_date: str, estimate: int) -> ApiResponse:",
+		`        return self._request("POST", "/api/task", {"title": title, "dueDate": due_date, "estimate": estimate})`,
+		"    def get_profile(self) -> ApiResponse:",
+		"class ApiError(Exception):",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.py missing %q", want)
+		}
+	}
+	if !strings.Contains(files["taskflow_sdk/models.py"], `Task = TypedDict("Task", {`) {
+		t.Error("models.py missing Task TypedDict")
+	}
+	if !strings.Contains(files["pyproject.toml"], `name = "taskflow-sdk"`) {
+		t.Error("pyproject.toml missing package name")
+	}
+}
+
+func TestGoSDKParses(t *testing.T) {
+	files := generateGo(testApp())
+	fset := token.NewFileSet()
+	for _, name := range []string{"client.go", "models.go"} {
+		if _, err := parser.ParseFile(fset, name, files[name], parser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v\n%s", name, err, files[name])
+		}
+	}
+
+	client := files["client.go"]
+	for _, want := range []string{
+		"package taskflow",
+		"func (c *Client) GetTasks(ctx context.Context, params *GetTasksParams) (*Response[[]Task], error) {",
+		`q.Set("status", fmt.Sprint(params.Status))`,
+		"func (c *Client) GetProfile(ctx context.Context) (*Response[json.RawMessage], error) {",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.go missing %q", want)
+		}
+	}
+	if !strings.Contains(files["models.go"], "\tTaskID string `json:\"task_id\"`") {
+		t.Errorf("models.go should map task_id to TaskID:\n%s", files["models.go"])
+	}
+}
+
+func TestGenerateWritesFiles(t *testing.T) {
+	dir := t.TempDir()
+	n, err := Generate(testApp(), "python", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(generatePython(testApp())) {
+		t.Errorf("Generate reported %d files", n)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "taskflow_sdk", "__init__.py")); err != nil {
+		t.Error("missing taskflow_sdk/__init__.py")
+	}
+}
@@ -0,0 +1,356 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateTypeScript produces an npm package with a fetch-based client.
+// It targets Node 18+ and browsers, and has no runtime dependencies.
+func generateTypeScript(app *ir.Application) map[string]string {
+	return map[string]string{
+		"package.json":  tsPackageJSON(app),
+		"tsconfig.json": tsConfig(),
+		"src/index.ts":  tsIndex(app),
+		"src/types.ts":  tsTypes(app),
+		"src/client.ts": tsClient(app),
+		"README.md":     tsReadme(app),
+	}
+}
+
+func tsPackageJSON(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	fmt.Fprintf(&b, "  \"name\": %q,\n", packageName(app))
+	b.WriteString("  \"version\": \"0.1.0\",\n")
+	fmt.Fprintf(&b, "  \"description\": %q,\n", "API client for "+appTitle(app))
+	b.WriteString("  \"main\": \"dist/index.js\",\n")
+	b.WriteString("  \"types\": \"dist/index.d.ts\",\n")
+	b.WriteString("  \"files\": [\"dist\"],\n")
+	b.WriteString("  \"scripts\": {\n")
+	b.WriteString("    \"build\": \"tsc\",\n")
+	b.WriteString("    \"prepare\": \"tsc\"\n")
+	b.WriteString("  },\n")
+	b.WriteString("  \"engines\": {\n")
+	b.WriteString("    \"node\": \">=18\"\n")
+	b.WriteString("  },\n")
+	b.WriteString("  \"devDependencies\": {\n")
+	b.WriteString("    \"typescript\": \"^5.6.0\"\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func tsConfig() string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	b.WriteString("  \"compilerOptions\": {\n")
+	b.WriteString("    \"target\": \"ES2020\",\n")
+	b.WriteString("    \"module\": \"commonjs\",\n")
+	b.WriteString("    \"lib\": [\"ES2020\", \"DOM\"],\n")
+	b.WriteString("    \"declaration\": true,\n")
+	b.WriteString("    \"outDir\": \"./dist\",\n")
+	b.WriteString("    \"rootDir\": \"./src\",\n")
+	b.WriteString("    \"strict\": true,\n")
+	b.WriteString("    \"skipLibCheck\": true\n")
+	b.WriteString("  },\n")
+	b.WriteString("  \"include\": [\"src\"]\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func tsIndex(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	fmt.Fprintf(&b, "export { %s, ApiError } from './client';\n", clientName(app))
+	b.WriteString("export type { ClientOptions } from './client';\n")
+	b.WriteString("export * from './types';\n")
+	return b.String()
+}
+
+// tsTypes writes an interface per data model and a params interface per
+// endpoint that takes parameters.
+func tsTypes(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+
+	b.WriteString("export interface ApiResponse<T> {\n")
+	b.WriteString("  data: T;\n")
+	b.WriteString("  token?: string;\n")
+	b.WriteString("  pagination?: { page: number; limit: number; total: number; totalPages: number };\n")
+	b.WriteString("}\n")
+
+	for _, model := range app.Data {
+		fmt.Fprintf(&b, "\nexport interface %s {\n", model.Name)
+		b.WriteString("  id: string;\n")
+		for _, f := range model.Fields {
+			optional := ""
+			if !f.Required {
+				optional = "?"
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsKey(f.Name), optional, tsFieldType(f))
+		}
+		for _, rel := range model.Relations {
+			if rel.Kind == "belongs_to" {
+				fmt.Fprintf(&b, "  %sId: string;\n", toCamelCase(rel.Target))
+			}
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, op := range operations(app) {
+		if len(op.Params) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\nexport interface %sParams {\n", toPascalCase(op.Endpoint.Name))
+		for _, p := range op.Params {
+			fmt.Fprintf(&b, "  %s: %s;\n", tsParamName(p.Name), tsType(p.Type))
+		}
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+func tsClient(app *ir.Application) string {
+	var b strings.Builder
+	client := clientName(app)
+	ops := operations(app)
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+
+	var imports []string
+	seen := map[string]bool{"ApiResponse": true}
+	imports = append(imports, "ApiResponse")
+	for _, op := range ops {
+		if len(op.Params) > 0 {
+			imports = append(imports, toPascalCase(op.Endpoint.Name)+"Params")
+		}
+		if op.Model != nil && !seen[op.Model.Name] {
+			seen[op.Model.Name] = true
+			imports = append(imports, op.Model.Name)
+		}
+	}
+	fmt.Fprintf(&b, "import type { %s } from './types';\n\n", strings.Join(imports, ", "))
+
+	b.WriteString(`export interface ClientOptions {
+  /** API origin, e.g. https://api.example.com (no trailing /api). */
+  baseUrl: string;
+  /** Bearer token sent on every request. Updated automatically by sign-up/login responses. */
+  token?: string;
+  /** Retries for network errors, 429, and 5xx responses. Default 2. */
+  maxRetries?: number;
+  /** Base delay for exponential backoff in milliseconds. Default 300. */
+  retryDelayMs?: number;
+  /** Custom fetch implementation (defaults to the global fetch). */
+  fetch?: typeof fetch;
+}
+
+/** Thrown for non-2xx responses. */
+export class ApiError extends Error {
+  constructor(
+    public readonly status: number,
+    message: string,
+    public readonly body?: unknown,
+  ) {
+    super(message);
+    this.name = 'ApiError';
+  }
+}
+
+const IDEMPOTENT = new Set(['GET', 'PUT', 'DELETE']);
+const RETRYABLE_STATUS = new Set([429, 502, 503, 504]);
+
+const sleep = (ms: number) => new Promise((resolve) => setTimeout(resolve, ms));
+
+`)
+
+	fmt.Fprintf(&b, "export class %s {\n", client)
+	b.WriteString(`  private readonly baseUrl: string;
+  private token?: string;
+  private readonly maxRetries: number;
+  private readonly retryDelayMs: number;
+  private readonly fetchImpl: typeof fetch;
+
+  constructor(options: ClientOptions) {
+    this.baseUrl = options.baseUrl.replace(/\/+$/, '');
+    this.token = options.token;
+    this.maxRetries = options.maxRetries ?? 2;
+    this.retryDelayMs = options.retryDelayMs ?? 300;
+    this.fetchImpl = options.fetch ?? globalThis.fetch.bind(globalThis);
+  }
+
+  /** Sets (or clears) the bearer token used for authenticated endpoints. */
+  setToken(token: string | undefined): void {
+    this.token = token;
+  }
+
+  /** Returns the current bearer token, if any. */
+  getToken(): string | undefined {
+    return this.token;
+  }
+`)
+
+	for _, op := range ops {
+		b.WriteString("\n")
+		writeTSMethod(&b, op)
+	}
+
+	b.WriteString(`
+  private async request<T>(
+    method: string,
+    path: string,
+    params?: object,
+  ): Promise<ApiResponse<T>> {
+    let url = this.baseUrl + path;
+    let body: string | undefined;
+    if (params && method === 'GET') {
+      const query = new URLSearchParams();
+      for (const [key, value] of Object.entries(params)) {
+        if (value !== undefined && value !== null) query.set(key, String(value));
+      }
+      const qs = query.toString();
+      if (qs) url += ` + "`?${qs}`" + `;
+    } else if (params) {
+      body = JSON.stringify(params);
+    }
+
+    const headers: Record<string, string> = { 'Content-Type': 'application/json' };
+    if (this.token) headers['Authorization'] = ` + "`Bearer ${this.token}`" + `;
+
+    for (let attempt = 0; ; attempt++) {
+      const canRetry = attempt < this.maxRetries;
+      let res: Response;
+      try {
+        res = await this.fetchImpl(url, { method, headers, body });
+      } catch (err) {
+        if (canRetry && IDEMPOTENT.has(method)) {
+          await sleep(this.retryDelayMs * 2 ** attempt);
+          continue;
+        }
+        throw err;
+      }
+
+      if (canRetry && RETRYABLE_STATUS.has(res.status) && (IDEMPOTENT.has(method) || res.status === 429)) {
+        const retryAfter = Number(res.headers.get('Retry-After'));
+        await sleep(retryAfter > 0 ? retryAfter * 1000 : this.retryDelayMs * 2 ** attempt);
+        continue;
+      }
+
+      const payload = await res.json().catch(() => ({}));
+      if (!res.ok) {
+        throw new ApiError(res.status, payload?.error ?? res.statusText, payload);
+      }
+      if (typeof payload?.token === 'string') {
+        this.token = payload.token;
+      }
+      return payload as ApiResponse<T>;
+    }
+  }
+}
+`)
+
+	return b.String()
+}
+
+func writeTSMethod(b *strings.Builder, op operation) {
+	name := toCamelCase(op.Endpoint.Name)
+	result := tsResultType(op)
+
+	doc := fmt.Sprintf("%s %s", op.Method, op.Path)
+	if op.Endpoint.Auth {
+		doc += " (requires auth)"
+	}
+	fmt.Fprintf(b, "  /** %s */\n", doc)
+	if len(op.Params) > 0 {
+		fmt.Fprintf(b, "  %s(params: %sParams): Promise<ApiResponse<%s>> {\n", name, toPascalCase(op.Endpoint.Name), result)
+		fmt.Fprintf(b, "    return this.request<%s>('%s', '%s', params);\n", result, op.Method, op.Path)
+	} else {
+		fmt.Fprintf(b, "  %s(): Promise<ApiResponse<%s>> {\n", name, result)
+		fmt.Fprintf(b, "    return this.request<%s>('%s', '%s');\n", result, op.Method, op.Path)
+	}
+	b.WriteString("  }\n")
+}
+
+func tsResultType(op operation) string {
+	switch {
+	case op.Model == nil:
+		return "unknown"
+	case op.List:
+		return op.Model.Name + "[]"
+	}
+	return op.Model.Name
+}
+
+func tsReadme(app *ir.Application) string {
+	var b strings.Builder
+	client := clientName(app)
+
+	fmt.Fprintf(&b, "# %s\n\n", packageName(app))
+	fmt.Fprintf(&b, "TypeScript client for the %s API. Generated by the Human compiler.\n\n", appTitle(app))
+	b.WriteString("## Install\n\n")
+	b.WriteString("```bash\n")
+	b.WriteString("npm install && npm run build\n")
+	fmt.Fprintf(&b, "npm pack   # produces %s-0.1.0.tgz for other services to install\n", packageName(app))
+	b.WriteString("```\n\n")
+	b.WriteString("## Usage\n\n")
+	b.WriteString("```ts\n")
+	fmt.Fprintf(&b, "import { %s, ApiError } from '%s';\n\n", client, packageName(app))
+	fmt.Fprintf(&b, "const api = new %s({ baseUrl: 'http://localhost:3001' });\n", client)
+	if ops := operations(app); len(ops) > 0 {
+		op := ops[0]
+		args := ""
+		if len(op.Params) > 0 {
+			args = "{ /* ... */ }"
+		}
+		fmt.Fprintf(&b, "const { data } = await api.%s(%s);\n", toCamelCase(op.Endpoint.Name), args)
+	}
+	b.WriteString("```\n\n")
+	writeReadmeNotes(&b, "`token`", "`setToken()`", "`ApiError`")
+	writeReadmeEndpoints(&b, app, toCamelCase)
+
+	return b.String()
+}
+
+// tsKey quotes a property name when it is not a valid identifier.
+func tsKey(name string) string {
+	if strings.ContainsAny(name, " -") {
+		return fmt.Sprintf("'%s'", name)
+	}
+	return name
+}
+
+// tsParamName matches the frontend clients' request body keys:
+// "due date" → "dueDate", "task_id" → "task_id".
+func tsParamName(name string) string {
+	if !strings.Contains(name, " ") {
+		return name
+	}
+	return toCamelCase(name)
+}
+
+func tsFieldType(f *ir.DataField) string {
+	if strings.EqualFold(f.Type, "enum") && len(f.EnumValues) > 0 {
+		vals := make([]string, len(f.EnumValues))
+		for i, v := range f.EnumValues {
+			vals[i] = fmt.Sprintf("'%s'", v)
+		}
+		return strings.Join(vals, " | ")
+	}
+	return tsType(f.Type)
+}
+
+func tsType(irType string) string {
+	switch strings.ToLower(irType) {
+	case "number", "decimal":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "json":
+		return "Record<string, unknown>"
+	default:
+		return "string"
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -72,23 +73,7 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		files[path] = generatePageStory(page, app, fw)
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func writeFile(path, content string) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", filepath.Dir(path), err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 func generateMainTs(fw string) string {
@@ -149,8 +134,8 @@ export default preview;
 // This is used by the scaffold generator to merge into the frontend package.json.
 func DevDependencies(fw string) map[string]string {
 	deps := map[string]string{
-		"@storybook/addon-essentials":    "^8.6.0",
-		"@storybook/addon-interactions":  "^8.6.0",
+		"@storybook/addon-essentials":   "^8.6.0",
+		"@storybook/addon-interactions": "^8.6.0",
 		"@storybook/blocks":             "^8.6.0",
 		"@storybook/test":               "^8.6.0",
 		"storybook":                     "^8.6.0",
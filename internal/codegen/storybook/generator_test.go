@@ -15,8 +15,8 @@ func TestInventory(t *testing.T) {
 	app := &ir.Application{
 		Components: []*ir.Component{
 			{
-				Name: "TaskCard",
-				Props: []*ir.Prop{{Name: "task", Type: "Task"}},
+				Name:    "TaskCard",
+				Props:   []*ir.Prop{{Name: "task", Type: "Task"}},
 				Content: []*ir.Action{{Type: "interact", Text: "click"}},
 			},
 		},
@@ -80,6 +80,122 @@ func TestGenerateMockData(t *testing.T) {
 	}
 }
 
+func TestGenerateMockDataCyclesEnums(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{
+			{
+				Name: "Task",
+				Fields: []*ir.DataField{
+					{Name: "status", Type: "enum", EnumValues: []string{"todo", "done"}},
+				},
+			},
+		},
+	}
+
+	out := generateMockData(app, "react")
+	if !strings.Contains(out, "export const taskStatusValues = ['todo', 'done'] as const;") {
+		t.Error("missing enum value list")
+	}
+	if !strings.Contains(out, "status: taskStatusValues[i % taskStatusValues.length]") {
+		t.Error("list factory should cycle through enum values")
+	}
+}
+
+func storyTestApp() *ir.Application {
+	return &ir.Application{
+		Data: []*ir.DataModel{
+			{
+				Name: "Task",
+				Fields: []*ir.DataField{
+					{Name: "title", Type: "text"},
+					{Name: "status", Type: "enum", EnumValues: []string{"todo", "in progress", "done"}},
+					{Name: "priority", Type: "enum", EnumValues: []string{"low", "high"}},
+				},
+			},
+		},
+	}
+}
+
+func TestComponentStoryControls(t *testing.T) {
+	app := storyTestApp()
+	comp := &ComponentMeta{
+		Name: "TaskCard",
+		Props: []*ir.Prop{
+			{Name: "task", Type: "Task"},
+			{Name: "compact", Type: "boolean"},
+			{Name: "count", Type: "number"},
+			{Name: "label", Type: "text"},
+		},
+	}
+
+	out := generateComponentStory(comp, app, "react")
+	checks := []string{
+		"task: { control: 'object' }",
+		"compact: { control: 'boolean' }",
+		"count: { control: 'number' }",
+		"label: { control: 'text' }",
+	}
+	for _, c := range checks {
+		if !strings.Contains(out, c) {
+			t.Errorf("component story missing control %q", c)
+		}
+	}
+}
+
+func TestComponentStoryEnumVariants(t *testing.T) {
+	app := storyTestApp()
+	comp := &ComponentMeta{
+		Name:  "TaskCard",
+		Props: []*ir.Prop{{Name: "task", Type: "Task"}},
+	}
+
+	out := generateComponentStory(comp, app, "react")
+	checks := []string{
+		"export const StatusTodo: Story",
+		"export const StatusInProgress: Story",
+		"task: mocks.mockTask({ status: 'in progress' })",
+		"export const StatusDone: Story",
+		"export const PriorityLow: Story",
+		"export const PriorityHigh: Story",
+	}
+	for _, c := range checks {
+		if !strings.Contains(out, c) {
+			t.Errorf("component story missing variant %q", c)
+		}
+	}
+	if strings.Contains(out, "Clicked") {
+		t.Error("component without click handler should not have an interaction test")
+	}
+}
+
+func TestComponentStoryInteractionTest(t *testing.T) {
+	app := storyTestApp()
+	comp := &ComponentMeta{
+		Name:     "TaskCard",
+		Props:    []*ir.Prop{{Name: "task", Type: "Task"}},
+		HasClick: true,
+	}
+
+	out := generateComponentStory(comp, app, "react")
+	checks := []string{
+		"import { expect, fn, userEvent } from '@storybook/test';",
+		"args: { onClick: fn() }",
+		"export const Clicked: Story",
+		"canvasElement.querySelector('.task-card')",
+		"await expect(args.onClick).toHaveBeenCalled();",
+	}
+	for _, c := range checks {
+		if !strings.Contains(out, c) {
+			t.Errorf("react interaction test missing %q", c)
+		}
+	}
+
+	svelte := generateComponentStory(comp, app, "svelte")
+	if !strings.Contains(svelte, "args: { onclick: fn() }") {
+		t.Error("svelte story should bind the lowercase onclick prop")
+	}
+}
+
 func TestFullIntegration(t *testing.T) {
 	_, thisFile, _, _ := runtime.Caller(0)
 	root := filepath.Join(filepath.Dir(thisFile), "..", "..", "..")
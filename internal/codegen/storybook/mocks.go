@@ -29,6 +29,22 @@ func generateMockData(app *ir.Application, fw string) string {
 	}
 
 	for _, model := range app.Data {
+		// Enum value lists let list factories cycle through every variant.
+		hasEnums := false
+		for _, field := range model.Fields {
+			if field.Type == "enum" && len(field.EnumValues) > 0 {
+				quoted := make([]string, len(field.EnumValues))
+				for i, v := range field.EnumValues {
+					quoted[i] = fmt.Sprintf("'%s'", v)
+				}
+				fmt.Fprintf(&b, "export const %s = [%s] as const;\n", enumValuesName(model, field), strings.Join(quoted, ", "))
+				hasEnums = true
+			}
+		}
+		if hasEnums {
+			b.WriteString("\n")
+		}
+
 		fmt.Fprintf(&b, "export const mock%s = (overrides?: Partial<%s>) => ({\n", model.Name, model.Name)
 		b.WriteString("  id: 'mock-id-123',\n")
 
@@ -48,8 +64,15 @@ func generateMockData(app *ir.Application, fw string) string {
 		b.WriteString("  ...overrides,\n")
 		b.WriteString("});\n\n")
 
+		overrides := []string{"id: `mock-id-${i}`"}
+		for _, field := range model.Fields {
+			if field.Type == "enum" && len(field.EnumValues) > 0 {
+				list := enumValuesName(model, field)
+				overrides = append(overrides, fmt.Sprintf("%s: %s[i %% %s.length]", field.Name, list, list))
+			}
+		}
 		fmt.Fprintf(&b, "export const mock%sList = (count: number = 3) => \n", model.Name)
-		fmt.Fprintf(&b, "  Array.from({ length: count }).map((_, i) => mock%s({ id: `mock-id-${i}` }));\n\n", model.Name)
+		fmt.Fprintf(&b, "  Array.from({ length: count }).map((_, i) => mock%s({ %s }));\n\n", model.Name, strings.Join(overrides, ", "))
 	}
 
 	return b.String()
@@ -75,6 +98,32 @@ func toCamelCase(s string) string {
 	return string(runes)
 }
 
+// enumValuesName returns the exported constant holding a field's enum
+// values, e.g. taskStatusValues.
+func enumValuesName(model *ir.DataModel, field *ir.DataField) string {
+	return toCamelCase(model.Name) + toPascalIdent(field.Name) + "Values"
+}
+
+// toPascalIdent converts an arbitrary label (enum value, field name) to a
+// PascalCase JavaScript identifier fragment: "in progress" → "InProgress".
+func toPascalIdent(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 func toKebabCase(s string) string {
 	var result []rune
 	for i, r := range s {
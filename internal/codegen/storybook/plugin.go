@@ -1,6 +1,8 @@
 package storybook
 
 import (
+	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/barun-bash/human/internal/codegen"
@@ -18,7 +20,7 @@ func (g Generator) Meta() codegen.PluginMeta {
 }
 
 // Enabled reports whether a supported frontend framework is configured.
-// Only react, vue, angular, and svelte are supported — must match resolveStorybookDir.
+// Only react, vue, angular, and svelte are supported — must match TargetDir.
 func (g Generator) Enabled(app *ir.Application) bool {
 	if app.Config == nil {
 		return false
@@ -34,5 +36,26 @@ func (g Generator) Enabled(app *ir.Application) bool {
 func (g Generator) StageName() string { return "Generating Storybook stories" }
 
 // OutputDir returns empty because Storybook writes into the frontend directory,
-// not a standalone subdirectory. The build pipeline resolves the actual directory.
+// not a standalone subdirectory, which TargetDir resolves.
 func (g Generator) OutputDir() string { return "" }
+
+// TargetDir returns the frontend's directory under outputDir.
+func (g Generator) TargetDir(app *ir.Application, outputDir string) (string, error) {
+	if app.Config == nil {
+		return "", fmt.Errorf("no frontend")
+	}
+	lower := strings.ToLower(app.Config.Frontend)
+	for _, framework := range []string{"react", "vue", "angular", "svelte"} {
+		if strings.Contains(lower, framework) {
+			return filepath.Join(outputDir, framework), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported frontend %q", app.Config.Frontend)
+}
+
+// CountFiles counts the Storybook configuration and stories in the
+// frontend directory.
+func (g Generator) CountFiles(dir string) int {
+	return codegen.CountFiles(filepath.Join(dir, ".storybook")) +
+		codegen.CountFiles(filepath.Join(dir, "src", "stories"))
+}
@@ -22,7 +22,7 @@ func generateComponentStory(comp *ComponentMeta, app *ir.Application, fw string)
 	b.WriteString(fmt.Sprintf("import type { Meta, StoryObj } from '%s';\n", frameworkStr))
 
 	if comp.HasClick {
-		b.WriteString("import { fn } from '@storybook/test';\n")
+		b.WriteString("import { expect, fn, userEvent } from '@storybook/test';\n")
 	}
 
 	if fw == "angular" {
@@ -70,17 +70,13 @@ func generateComponentStory(comp *ComponentMeta, app *ir.Application, fw string)
 	}
 
 	if comp.HasClick {
-		b.WriteString("  args: { onClick: fn() },\n")
+		fmt.Fprintf(&b, "  args: { %s: fn() },\n", clickArg(fw))
 	}
 
-	if hasArgTypes(comp) {
+	if len(comp.Props) > 0 {
 		b.WriteString("  argTypes: {\n")
 		for _, prop := range comp.Props {
-			if prop.Type == "enum" {
-				fmt.Fprintf(&b, "    %s: { control: 'select' },\n", prop.Name)
-			} else if prop.Type == "boolean" {
-				fmt.Fprintf(&b, "    %s: { control: 'boolean' },\n", prop.Name)
-			}
+			fmt.Fprintf(&b, "    %s: { control: '%s' },\n", prop.Name, argControl(prop, app))
 		}
 		b.WriteString("  },\n")
 	}
@@ -103,17 +99,105 @@ func generateComponentStory(comp *ComponentMeta, app *ir.Application, fw string)
 	}
 	b.WriteString("};\n")
 
+	// One story per enum value of each data-model prop (status badges,
+	// priorities, ...), so every visual variant is reviewable.
+	for _, v := range enumVariants(comp, app) {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "export const %s: Story = {\n", v.StoryName)
+		b.WriteString("  args: {\n")
+		b.WriteString("    ...Default.args,\n")
+		fmt.Fprintf(&b, "    %s: mocks.mock%s({ %s: '%s' }),\n", v.Prop, v.Model, v.Field, v.Value)
+		b.WriteString("  },\n")
+		b.WriteString("};\n")
+	}
+
+	// Interaction test: clicking the component root fires the click handler.
+	if comp.HasClick {
+		b.WriteString("\n")
+		b.WriteString("export const Clicked: Story = {\n")
+		b.WriteString("  args: { ...Default.args },\n")
+		b.WriteString("  play: async ({ args, canvasElement }) => {\n")
+		fmt.Fprintf(&b, "    const root = canvasElement.querySelector('.%s') as HTMLElement;\n", toKebabCase(comp.Name))
+		b.WriteString("    await userEvent.click(root);\n")
+		fmt.Fprintf(&b, "    await expect(args.%s).toHaveBeenCalled();\n", clickArg(fw))
+		b.WriteString("  },\n")
+		b.WriteString("};\n")
+	}
+
 	return b.String()
 }
 
-// hasArgTypes checks whether any prop needs a custom argType control.
-func hasArgTypes(comp *ComponentMeta) bool {
+// clickArg returns the arg name a framework uses for a component's click
+// handler: Svelte 5 takes a lowercase onclick prop, the others onClick.
+func clickArg(fw string) string {
+	if fw == "svelte" {
+		return "onclick"
+	}
+	return "onClick"
+}
+
+// argControl returns the Storybook control type for a prop.
+func argControl(prop *ir.Prop, app *ir.Application) string {
+	if isDataModel(prop.Type, app) {
+		return "object"
+	}
+	switch strings.ToLower(prop.Type) {
+	case "enum":
+		return "select"
+	case "boolean":
+		return "boolean"
+	case "number", "decimal":
+		return "number"
+	case "date", "datetime":
+		return "date"
+	default:
+		return "text"
+	}
+}
+
+// storyVariant is a single enum-driven story for a component.
+type storyVariant struct {
+	StoryName string // exported story name, e.g. StatusDone
+	Prop      string // component prop the mock is passed to
+	Model     string // data model backing the prop
+	Field     string // enum field being varied
+	Value     string // enum value for this story
+}
+
+// enumVariants returns a story variant for every enum value of every
+// data-model prop on the component. Story names are deduplicated.
+func enumVariants(comp *ComponentMeta, app *ir.Application) []storyVariant {
+	var variants []storyVariant
+	seen := map[string]bool{"Default": true, "Clicked": true}
 	for _, prop := range comp.Props {
-		if prop.Type == "enum" || prop.Type == "boolean" {
-			return true
+		model := findModel(prop.Type, app)
+		if model == nil {
+			continue
+		}
+		for _, field := range model.Fields {
+			if field.Type != "enum" {
+				continue
+			}
+			for _, val := range field.EnumValues {
+				name := toPascalIdent(field.Name) + toPascalIdent(val)
+				if len(comp.Props) > 1 {
+					name = toPascalIdent(prop.Name) + name
+				}
+				if seen[name] {
+					continue
+				}
+				seen[name] = true
+				variants = append(variants, storyVariant{
+					StoryName: name,
+					Prop:      prop.Name,
+					Model:     model.Name,
+					Field:     field.Name,
+					Value:     val,
+				})
+			}
 		}
 	}
-	return false
+	return variants
 }
 
 // defaultArgValue returns a sensible default arg literal for a prop type.
@@ -186,10 +270,14 @@ func generatePageStory(page *PageMeta, app *ir.Application, fw string) string {
 }
 
 func isDataModel(typeName string, app *ir.Application) bool {
+	return findModel(typeName, app) != nil
+}
+
+func findModel(typeName string, app *ir.Application) *ir.DataModel {
 	for _, m := range app.Data {
 		if m.Name == typeName {
-			return true
+			return m
 		}
 	}
-	return false
+	return nil
 }
@@ -0,0 +1,304 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeProfileType writes the profile the account endpoints respond with:
+// the user without their password.
+func writeProfileType(b *strings.Builder, app *ir.Application) {
+	fmt.Fprintf(b, "\nexport type Profile = Omit<%s, 'password'>;\n", app.Accounts.Model)
+}
+
+// writeAccountFunction writes the client function of an endpoint the
+// accounts block added. A profile's fields are typed as the users' model
+// types them, with an optional field left empty sent as null.
+func writeAccountFunction(b *strings.Builder, ep *ir.Endpoint, app *ir.Application) {
+	fn := toCamelCase(ep.Name)
+	method := httpMethod(ep.Name)
+	path := apiPath(ep.Name)
+	switch ep.Account {
+	case ir.AccountProfile:
+		fmt.Fprintf(b, "export async function %s(): Promise<ApiResponse<Profile>> {\n", fn)
+		fmt.Fprintf(b, "  return request<Profile>('%s', '%s');\n", method, path)
+	case ir.AccountUpdateProfile:
+		var fields []string
+		for _, f := range ir.ProfileFields(app) {
+			fields = append(fields, fmt.Sprintf("%s: %s", f.Name, profileFieldType(f)))
+		}
+		fmt.Fprintf(b, "export async function %s(params: { %s }): Promise<ApiResponse<Profile>> {\n", fn, strings.Join(fields, "; "))
+		fmt.Fprintf(b, "  return request<Profile>('%s', '%s', params);\n", method, path)
+	default:
+		var fields []string
+		for _, p := range ep.Params {
+			fields = append(fields, p.Name+": string")
+		}
+		fmt.Fprintf(b, "export async function %s(params: { %s }): Promise<ApiResponse<void>> {\n", fn, strings.Join(fields, "; "))
+		fmt.Fprintf(b, "  return request<void>('%s', '%s', params);\n", method, path)
+	}
+	b.WriteString("}\n")
+}
+
+// profileFieldType returns the type a profile field is sent as.
+func profileFieldType(f *ir.DataField) string {
+	t := tsType(f.Type)
+	if f.Type == "boolean" || f.Required {
+		return t
+	}
+	return t + " | null"
+}
+
+// profileDraftValue returns the expression sending a profile field from the
+// profile form's draft.
+func profileDraftValue(f *ir.DataField) string {
+	ref := "profileDraft." + f.Name
+	switch {
+	case f.Type == "boolean":
+		return ref
+	case tsType(f.Type) == "number" && f.Required:
+		return "Number(" + ref + ")"
+	case tsType(f.Type) == "number":
+		return fmt.Sprintf("String(%s) ? Number(%s) : null", ref, ref)
+	case f.Required:
+		return ref
+	}
+	return ref + " || null"
+}
+
+// generateAccountPage produces the account page the accounts block added:
+// a form for the user's profile, one changing their password, and a
+// danger zone deleting their account. Deleting asks again in a dialog,
+// and only goes ahead once the user has typed DeleteConfirmation and
+// their password; they are then signed out.
+func generateAccountPage(page *ir.Page, app *ir.Application) string {
+	var b strings.Builder
+	ctx := &pageContext{app: app}
+	profile := ir.AccountEndpoint(app, ir.AccountProfile)
+	update := ir.AccountEndpoint(app, ir.AccountUpdateProfile)
+	password := ir.AccountEndpoint(app, ir.AccountChangePassword)
+	remove := ir.AccountEndpoint(app, ir.AccountDelete)
+	showsProfile := profile != nil && update != nil
+	fields := ir.ProfileFields(app)
+
+	var imports []string
+	if showsProfile {
+		imports = append(imports, toCamelCase(profile.Name), toCamelCase(update.Name))
+	}
+	if password != nil {
+		imports = append(imports, toCamelCase(password.Name))
+	}
+	if remove != nil {
+		imports = append(imports, toCamelCase(remove.Name))
+	}
+	imports = append(imports, "errorMessage")
+	if showsProfile {
+		imports = append(imports, "type Profile")
+	}
+
+	b.WriteString("<!-- Generated by Human compiler — do not edit -->\n")
+	b.WriteString("<script lang=\"ts\">\n")
+	if remove != nil {
+		b.WriteString("  import { goto } from '$app/navigation';\n")
+		if app.Auth != nil {
+			b.WriteString("  import { auth } from '$lib/auth';\n")
+		}
+	}
+	fmt.Fprintf(&b, "  import { %s } from '$lib/api';\n", strings.Join(imports, ", "))
+
+	if showsProfile {
+		var initial, filled []string
+		for _, f := range fields {
+			initial = append(initial, fmt.Sprintf("%s: %s", f.Name, draftZero(f)))
+			filled = append(filled, fmt.Sprintf("%s: %s", f.Name, editValue("profile", f)))
+		}
+		b.WriteString("\n  let profile = $state<Profile | null>(null);\n")
+		fmt.Fprintf(&b, "  let profileDraft = $state({ %s });\n", strings.Join(initial, ", "))
+		b.WriteString("  let loadError = $state('');\n")
+		b.WriteString("  let profileError = $state('');\n")
+		b.WriteString("  let profileSaved = $state(false);\n")
+		b.WriteString("\n  $effect(() => {\n")
+		fmt.Fprintf(&b, "    %s()\n", toCamelCase(profile.Name))
+		b.WriteString("      .then(res => { profile = res.data; })\n")
+		b.WriteString("      .catch(err => { loadError = errorMessage(err, 'Could not load your profile'); });\n")
+		b.WriteString("  });\n")
+		b.WriteString("\n  $effect(() => {\n")
+		fmt.Fprintf(&b, "    if (profile) profileDraft = { %s };\n", strings.Join(filled, ", "))
+		b.WriteString("  });\n")
+		b.WriteString("\n  async function saveProfile(ev: Event) {\n")
+		b.WriteString("    ev.preventDefault();\n")
+		b.WriteString("    profileError = '';\n")
+		b.WriteString("    profileSaved = false;\n")
+		b.WriteString("    try {\n")
+		fmt.Fprintf(&b, "      const res = await %s({\n", toCamelCase(update.Name))
+		for _, f := range fields {
+			fmt.Fprintf(&b, "        %s: %s,\n", f.Name, profileDraftValue(f))
+		}
+		b.WriteString("      });\n")
+		b.WriteString("      profile = res.data;\n")
+		b.WriteString("      profileSaved = true;\n")
+		b.WriteString("    } catch (err) {\n")
+		b.WriteString("      profileError = errorMessage(err, 'Could not save your profile');\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n")
+	}
+
+	if password != nil {
+		b.WriteString("\n  let currentPassword = $state('');\n")
+		b.WriteString("  let newPassword = $state('');\n")
+		b.WriteString("  let confirmPassword = $state('');\n")
+		b.WriteString("  let passwordError = $state('');\n")
+		b.WriteString("  let passwordChanged = $state(false);\n")
+		b.WriteString("\n  async function savePassword(ev: Event) {\n")
+		b.WriteString("    ev.preventDefault();\n")
+		b.WriteString("    passwordError = '';\n")
+		b.WriteString("    passwordChanged = false;\n")
+		b.WriteString("    if (newPassword !== confirmPassword) {\n")
+		b.WriteString("      passwordError = 'The new passwords do not match';\n")
+		b.WriteString("      return;\n")
+		b.WriteString("    }\n")
+		b.WriteString("    try {\n")
+		fmt.Fprintf(&b, "      await %s({ current_password: currentPassword, new_password: newPassword });\n", toCamelCase(password.Name))
+		b.WriteString("      currentPassword = '';\n")
+		b.WriteString("      newPassword = '';\n")
+		b.WriteString("      confirmPassword = '';\n")
+		b.WriteString("      passwordChanged = true;\n")
+		b.WriteString("    } catch (err) {\n")
+		b.WriteString("      passwordError = errorMessage(err, 'Could not change your password');\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n")
+	}
+
+	if remove != nil {
+		b.WriteString("\n  let confirmingDelete = $state(false);\n")
+		b.WriteString("  let deleteConfirmation = $state('');\n")
+		b.WriteString("  let deletePassword = $state('');\n")
+		b.WriteString("  let deleteError = $state('');\n")
+		b.WriteString("  let deleting = $state(false);\n")
+		b.WriteString("\n  function cancelDelete() {\n")
+		b.WriteString("    confirmingDelete = false;\n")
+		b.WriteString("    deleteConfirmation = '';\n")
+		b.WriteString("    deletePassword = '';\n")
+		b.WriteString("    deleteError = '';\n")
+		b.WriteString("  }\n")
+		b.WriteString("\n  async function removeAccount(ev: Event) {\n")
+		b.WriteString("    ev.preventDefault();\n")
+		b.WriteString("    deleteError = '';\n")
+		b.WriteString("    deleting = true;\n")
+		b.WriteString("    try {\n")
+		fmt.Fprintf(&b, "      await %s({ password: deletePassword });\n", toCamelCase(remove.Name))
+		if app.Auth != nil {
+			b.WriteString("      auth.logout();\n")
+		} else {
+			b.WriteString("      localStorage.removeItem('token');\n")
+		}
+		b.WriteString("      goto('/', { replaceState: true });\n")
+		b.WriteString("    } catch (err) {\n")
+		b.WriteString("      deleteError = errorMessage(err, 'Could not delete your account');\n")
+		b.WriteString("      deleting = false;\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n")
+	}
+	b.WriteString("</script>\n\n")
+
+	fmt.Fprintf(&b, "<div class=\"%s-page\">\n", toKebabCase(page.Name))
+	fmt.Fprintf(&b, "  <h1>%s</h1>\n", ir.FieldLabel(page.Name))
+
+	if showsProfile {
+		b.WriteString("  <section aria-labelledby=\"profile-title\">\n")
+		b.WriteString("    <h2 id=\"profile-title\">Profile</h2>\n")
+		b.WriteString("    {#if loadError}\n")
+		b.WriteString("      <p class=\"form-error\" role=\"alert\">{loadError}</p>\n")
+		b.WriteString("    {/if}\n")
+		b.WriteString("    {#if profile}\n")
+		b.WriteString("      <form class=\"form\" aria-labelledby=\"profile-title\" onsubmit={saveProfile}>\n")
+		for _, f := range fields {
+			in := formInput{
+				id:        "profile-" + toKebabCase(toCamelCase(f.Name)),
+				name:      f.Name,
+				label:     ir.FieldLabel(f.Name),
+				inputType: inputType(f.Name, f),
+				required:  f.Required && f.Type != "boolean",
+				bind:      "profileDraft." + f.Name,
+			}
+			if f.Type == "boolean" {
+				in.inputType = "checkbox"
+			}
+			writeFormFieldSvelte(&b, "        ", in, ctx)
+		}
+		b.WriteString("        {#if profileError}\n")
+		b.WriteString("          <p class=\"form-error\" role=\"alert\">{profileError}</p>\n")
+		b.WriteString("        {/if}\n")
+		b.WriteString("        {#if profileSaved}\n")
+		b.WriteString("          <p class=\"form-success\" role=\"status\">Your profile was saved</p>\n")
+		b.WriteString("        {/if}\n")
+		b.WriteString("        <button type=\"submit\">Save profile</button>\n")
+		b.WriteString("      </form>\n")
+		b.WriteString("    {/if}\n")
+		b.WriteString("  </section>\n")
+	}
+
+	if password != nil {
+		b.WriteString("  <section aria-labelledby=\"password-title\">\n")
+		b.WriteString("    <h2 id=\"password-title\">Change password</h2>\n")
+		b.WriteString("    <form class=\"form\" aria-labelledby=\"password-title\" onsubmit={savePassword}>\n")
+		writePasswordFieldSvelte(&b, "      ", "current-password", "currentPassword", "Current password", "current-password", false)
+		writePasswordFieldSvelte(&b, "      ", "new-password", "newPassword", "New password", "new-password", true)
+		writePasswordFieldSvelte(&b, "      ", "confirm-password", "confirmPassword", "Confirm new password", "new-password", true)
+		b.WriteString("      {#if passwordError}\n")
+		b.WriteString("        <p class=\"form-error\" role=\"alert\">{passwordError}</p>\n")
+		b.WriteString("      {/if}\n")
+		b.WriteString("      {#if passwordChanged}\n")
+		b.WriteString("        <p class=\"form-success\" role=\"status\">Your password was changed</p>\n")
+		b.WriteString("      {/if}\n")
+		b.WriteString("      <button type=\"submit\">Change password</button>\n")
+		b.WriteString("    </form>\n")
+		b.WriteString("  </section>\n")
+	}
+
+	if remove != nil {
+		b.WriteString("  <section class=\"danger-zone\" aria-labelledby=\"delete-title\">\n")
+		b.WriteString("    <h2 id=\"delete-title\">Delete account</h2>\n")
+		b.WriteString("    <p>Deleting your account removes it and everything in it for good.</p>\n")
+		b.WriteString("    <button type=\"button\" class=\"danger\" onclick={() => (confirmingDelete = true)}>Delete account</button>\n")
+		b.WriteString("    {#if confirmingDelete}\n")
+		b.WriteString("      <div class=\"confirm-dialog\" role=\"alertdialog\" aria-modal=\"true\" aria-labelledby=\"delete-confirm-title\" aria-describedby=\"delete-confirm-description\" tabindex=\"-1\" onkeydown={(ev) => ev.key === 'Escape' && cancelDelete()}>\n")
+		b.WriteString("        <h2 id=\"delete-confirm-title\">Delete your account?</h2>\n")
+		fmt.Fprintf(&b, "        <p id=\"delete-confirm-description\">This can't be undone. Type %s and enter your password to confirm.</p>\n", ir.DeleteConfirmation)
+		b.WriteString("        <form class=\"form\" aria-labelledby=\"delete-confirm-title\" onsubmit={removeAccount}>\n")
+		b.WriteString("          <div class=\"form-field\">\n")
+		fmt.Fprintf(&b, "            <label for=\"delete-confirmation\">Type %s to confirm</label>\n", ir.DeleteConfirmation)
+		b.WriteString("            <!-- svelte-ignore a11y_autofocus -->\n")
+		b.WriteString("            <input id=\"delete-confirmation\" type=\"text\" autocomplete=\"off\" autofocus bind:value={deleteConfirmation} required />\n")
+		b.WriteString("          </div>\n")
+		writePasswordFieldSvelte(&b, "          ", "delete-password", "deletePassword", "Password", "current-password", false)
+		b.WriteString("          {#if deleteError}\n")
+		b.WriteString("            <p class=\"form-error\" role=\"alert\">{deleteError}</p>\n")
+		b.WriteString("          {/if}\n")
+		fmt.Fprintf(&b, "          <button type=\"submit\" class=\"danger\" disabled={deleteConfirmation !== '%s' || deleting}>Delete my account</button>\n", ir.DeleteConfirmation)
+		b.WriteString("          <button type=\"button\" onclick={cancelDelete}>Cancel</button>\n")
+		b.WriteString("        </form>\n")
+		b.WriteString("      </div>\n")
+		b.WriteString("    {/if}\n")
+		b.WriteString("  </section>\n")
+	}
+
+	b.WriteString("</div>\n")
+	return b.String()
+}
+
+// writePasswordFieldSvelte renders a password input with its label, bound
+// to bind and filled in by password managers as autocomplete says. A new
+// password must be MinPasswordLength characters long.
+func writePasswordFieldSvelte(b *strings.Builder, indent, id, bind, label, autocomplete string, isNew bool) {
+	fmt.Fprintf(b, "%s<div class=\"form-field\">\n", indent)
+	fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, id, label)
+	minLength := ""
+	if isNew {
+		minLength = fmt.Sprintf(" minlength=\"%d\"", ir.MinPasswordLength)
+	}
+	fmt.Fprintf(b, "%s  <input id=\"%s\" type=\"password\" autocomplete=\"%s\"%s bind:value={%s} required />\n", indent, id, autocomplete, minLength, bind)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
@@ -0,0 +1,36 @@
+package svelte
+
+import "strings"
+
+// writeCalendarClient appends the calendar feed URLs to the API client. The
+// feeds are fetched by the browser or a calendar app, not by request().
+func writeCalendarClient(b *strings.Builder) {
+	b.WriteString(`
+// calendarUrl returns a calendar's ICS feed, or one record's .ics download.
+export function calendarUrl(feed: string, id?: string): string {
+  const path = id ? ` + "`${feed}/${encodeURIComponent(id)}`" + ` : feed;
+  return new URL(` + "`${API_BASE_URL}/api/calendar/${path}.ics`" + `, window.location.href).href;
+}
+`)
+}
+
+// generateAddToCalendar produces src/lib/components/AddToCalendar.svelte.
+// Given a record id it downloads that record's .ics file; without one it
+// subscribes to the whole feed through a webcal:// link.
+func generateAddToCalendar() string {
+	return `<!-- Generated by Human compiler — do not edit -->
+<script lang="ts">
+  import { calendarUrl } from '$lib/api';
+
+  let { feed, id }: { feed: string; id?: string } = $props();
+
+  const url = $derived(calendarUrl(feed, id));
+</script>
+
+{#if id}
+  <a class="add-to-calendar" href={url} download>Add to calendar</a>
+{:else}
+  <a class="add-to-calendar" href={url.replace(/^https?:/, 'webcal:')}>Subscribe to calendar</a>
+{/if}
+`
+}
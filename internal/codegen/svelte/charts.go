@@ -0,0 +1,149 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeChartClient appends the chart endpoint to the API client.
+func writeChartClient(b *strings.Builder) {
+	b.WriteString(`
+export interface ChartPoint {
+  label: string;
+  value: number;
+}
+
+// fetchChart loads a chart's points from its aggregate endpoint.
+export async function fetchChart(source: string) {
+  return request<ChartPoint[]>('GET', ` + "`/api/charts/${source}`" + `);
+}
+`)
+}
+
+// generateDataChart produces src/lib/components/DataChart.svelte: a
+// Chart.js bar, line, or pie chart of the points an aggregate endpoint
+// returns, drawn in the theme's primary color.
+func generateDataChart() string {
+	return `<!-- Generated by Human compiler — do not edit -->
+<script lang="ts">
+  import { onMount } from 'svelte';
+  import { Chart, registerables } from 'chart.js';
+  import { fetchChart, type ChartPoint } from '$lib/api';
+
+  Chart.register(...registerables);
+
+  let { kind, title, source }: { kind: 'bar' | 'line' | 'pie'; title: string; source: string } = $props();
+
+  const PALETTE = ['#6366f1', '#22c55e', '#f59e0b', '#ef4444', '#06b6d4', '#a855f7', '#ec4899', '#84cc16'];
+
+  let canvas: HTMLCanvasElement | undefined = $state();
+  let points: ChartPoint[] = $state([]);
+  let loading = $state(true);
+  let error = $state('');
+
+  // The theme's primary color, for bars, lines, and the first slice.
+  function primaryColor(): string {
+    return getComputedStyle(document.documentElement).getPropertyValue('--color-primary').trim() || PALETTE[0];
+  }
+
+  onMount(async () => {
+    try {
+      const res = await fetchChart(source);
+      if (res.error) {
+        error = res.error;
+      } else {
+        points = res.data ?? [];
+      }
+    } catch {
+      error = 'Could not load the chart';
+    } finally {
+      loading = false;
+    }
+  });
+
+  $effect(() => {
+    if (!canvas || points.length === 0) return;
+    const color = primaryColor();
+    const colors = kind === 'pie'
+      ? points.map((_, i) => (i === 0 ? color : PALETTE[i % PALETTE.length]))
+      : color;
+    const chart = new Chart(canvas, {
+      type: kind,
+      data: {
+        labels: points.map((p) => p.label),
+        datasets: [{
+          label: title,
+          data: points.map((p) => p.value),
+          backgroundColor: colors,
+          borderColor: colors,
+        }],
+      },
+      options: {
+        responsive: true,
+        maintainAspectRatio: false,
+        plugins: { legend: { display: kind === 'pie' } },
+      },
+    });
+    return () => chart.destroy();
+  });
+</script>
+
+<figure class="chart">
+  <figcaption class="chart-title">{title}</figcaption>
+  {#if loading}
+    <p class="chart-status">Loading...</p>
+  {:else if error}
+    <p class="chart-status" role="alert">{error}</p>
+  {:else if points.length === 0}
+    <p class="chart-status">No data yet</p>
+  {:else}
+    <div class="chart-canvas" style="height: 300px">
+      <canvas bind:this={canvas}></canvas>
+    </div>
+  {/if}
+</figure>
+`
+}
+
+// chartAttr escapes a chart title for an attribute value.
+var chartAttr = strings.NewReplacer("&", "&amp;", "\"", "&quot;", "<", "&lt;", ">", "&gt;", "{", "&#123;", "}", "&#125;")
+
+// writeChartSvelte emits the chart a "show a bar chart of ..." action draws.
+func writeChartSvelte(b *strings.Builder, c *ir.Chart, indent string) {
+	fmt.Fprintf(b, "%s<DataChart kind=\"%s\" title=\"%s\" source=\"%s\" />\n", indent, c.Kind, chartAttr.Replace(c.Title), c.Slug)
+}
+
+// pageHasChart reports whether the page draws a chart from an aggregate
+// endpoint.
+func pageHasChart(page *ir.Page, app *ir.Application) bool {
+	for _, a := range page.Content {
+		if ir.ChartFor(app, a) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReports reports whether any endpoint serves an aggregate.
+func hasReports(app *ir.Application) bool {
+	for _, ep := range app.APIs {
+		if ep.Aggregate != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// writeReportType declares the rows report endpoints return.
+func writeReportType(b *strings.Builder) {
+	b.WriteString(`
+// ReportRow is one group of a report: its label and the count, sum,
+// average, minimum, or maximum of its records.
+export interface ReportRow {
+  label: string;
+  value: number;
+}
+`)
+}
@@ -17,11 +17,38 @@ type pageContext struct {
 	props           map[string]string // component props: name → type
 	hasSuccessState bool
 	hasErrorState   bool
-	isComponent     bool              // true when generating a component (not a page)
+	isComponent     bool // true when generating a component (not a page)
 	needsFormState  bool
+	table           *ir.Table       // the page's "show posts in a table", if any
+	query           *ir.ListQuery   // the view of the list the page's URL holds, if any
+	reorder         *ir.Reorder     // the list the page lets users drag, if any
+	scroll          bool            // whether the list loads more records as the user nears its end
+	remember        bool            // whether the page restores its list as the user left it
+	newestFirst     bool            // whether new records go at the top of the list
+	deleteEp        *ir.Endpoint    // endpoint each item's delete button calls, if any
+	deleteLabel     string          // label of that button
+	tooltips        []*ir.Tooltip   // tooltips shown over the listed records or the component
+	panel           *ir.DetailPanel // panel clicking a listed record opens, if any
+	itemPage        string          // page clicking a listed record navigates to, if any
+	itemLink        *ir.Action      // the interaction saying so
+	record          *ir.DetailRoute // the record the page's URL names, if any
+	edit            *ir.EditForm    // the form editing that record, if any
+	importForm      *ir.ImportForm  // the form uploading a file of records to import, if any
+	timeHelpers     map[string]bool // time helpers the page uses from the API client
+	formatHelpers   map[string]bool // number helpers the page uses
+	loadError       string          // message shown when loading the page's data fails, if it loads any
+	retry           string          // handler the load error's button calls
+	itemFields      []string        // fields each listed record shows, shaping its loading skeleton
+	responsive      *ir.Responsive  // how the page adapts to narrower screens, if it declares so
+	fieldErrors     bool            // whether the page tracks the form input found invalid
+	ids             map[string]int  // element ids given out on the page, by base
 }
 
 func generatePage(page *ir.Page, app *ir.Application) string {
+	if ir.IsAccountPage(app, page) {
+		return generateAccountPage(page, app)
+	}
+
 	var b strings.Builder
 
 	modelName, varName, itemVar := detectPageModel(page, app)
@@ -33,6 +60,7 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	needsFormState := false
 	needsSuccess := false
 	needsError := false
+	hasForm := false
 
 	for _, a := range page.Content {
 		lower := strings.ToLower(a.Text)
@@ -46,9 +74,22 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			if strings.Contains(lower, "opens a form") || strings.Contains(lower, "open a form") {
 				needsFormState = true
 			}
+			if kb, ok := ir.ParseKeyBinding(a.Text); ok {
+				switch kb.Action {
+				case "open-form":
+					needsFormState = true
+				case "navigate":
+					needsNavigate = true
+				}
+			}
 		case "query":
 			needsDataState = true
 			needsEffect = true
+		case "display":
+			if ir.IsTable(a) {
+				needsDataState = true
+				needsEffect = true
+			}
 		case "loop":
 			needsDataState = true
 			if modelName != "" {
@@ -58,6 +99,9 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			if strings.Contains(lower, "button") && (strings.Contains(lower, "create") || strings.Contains(lower, "new") || strings.Contains(lower, "add")) {
 				needsFormState = true
 			}
+			if strings.Contains(lower, "form") && !ir.IsImportForm(a) {
+				hasForm = true
+			}
 		case "condition":
 			if strings.Contains(lower, "logged in") {
 				needsAuth = true
@@ -79,25 +123,93 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 		hasSuccessState: needsSuccess,
 		hasErrorState:   needsError,
 		needsFormState:  needsFormState,
+		table:           ir.TableFor(app, page, modelName),
+		reorder:         pageReorder(page, app, modelName),
+		record:          ir.DetailRouteFor(app, page.Name),
+		edit:            ir.EditFormFor(app, page),
+		importForm:      ir.ImportFormFor(app, page),
+		responsive:      ir.ResponsiveFor(page),
+		fieldErrors:     needsFormState || hasForm,
+	}
+	if ctx.table != nil && ctx.table.RowPage != "" {
+		needsNavigate = true
+	}
+	keyBindings := ir.PageKeyBindings(page)
+	var listEp *ir.Endpoint
+	if needsEffect && modelName != "" {
+		listEp = findListEndpoint(app, modelName)
+	}
+	ctx.scroll = pageScrolls(page, app, modelName, listEp)
+	if needsDataState {
+		ctx.query = ir.ListQueryFor(app, page, modelName, listEp)
+	}
+	if ctx.query != nil {
+		needsNavigate = true
+	}
+	ctx.newestFirst = ir.ListsNewestFirst(app, listEp, modelName)
+	ctx.remember = needsEffect && ir.RemembersView(page)
+	if needsDataState {
+		ctx.deleteEp, ctx.deleteLabel = pageDelete(page, app, modelName)
+		ctx.tooltips = pageTooltips(page, app, modelName)
+		ctx.panel = pagePanel(page, app, modelName)
+		if ctx.table == nil {
+			ctx.itemPage, ctx.itemLink = ir.ItemLinkFor(app, page, modelName)
+		}
+	}
+	if ctx.itemPage != "" {
+		needsNavigate = true
+	}
+	if needsEffect {
+		subject := varName
+		if subject == "" {
+			subject = "data"
+		}
+		ctx.loadError = ir.LoadErrorMessage(page, strings.ToLower(ir.FieldLabel(subject)))
+		ctx.retry = "load"
+	}
+	if ctx.record != nil {
+		if ctx.loadError == "" {
+			ctx.loadError = ir.LoadErrorMessage(page, "the "+strings.ToLower(ir.FieldLabel(ctx.record.Model)))
+			ctx.retry = "load" + ctx.record.Model
+		} else {
+			ctx.retry += "(); load" + ctx.record.Model + "()"
+		}
 	}
 
 	// <script>
 	b.WriteString("<!-- Generated by Human compiler — do not edit -->\n")
 	b.WriteString("<script lang=\"ts\">\n")
 
-	if needsNavigate {
+	switch {
+	case needsNavigate && ctx.remember:
+		b.WriteString("  import { goto, beforeNavigate } from '$app/navigation';\n")
+	case needsNavigate:
 		b.WriteString("  import { goto } from '$app/navigation';\n")
+	case ctx.remember:
+		b.WriteString("  import { beforeNavigate } from '$app/navigation';\n")
+	}
+	if ctx.remember && ctx.query != nil && !ctx.query.Paged {
+		b.WriteString("  import { onMount } from 'svelte';\n")
 	}
+	if ctx.record != nil || ctx.query != nil {
+		b.WriteString("  import { page } from '$app/stores';\n")
+	}
+	if ctx.reorder != nil {
+		b.WriteString("  import { dndzone, TRIGGERS, type DndEvent } from 'svelte-dnd-action';\n")
+	}
+	var types []string
 	if modelName != "" {
-		fmt.Fprintf(&b, "  import type { %s } from '$lib/types';\n", modelName)
+		types = append(types, modelName)
+	}
+	if ctx.record != nil && ctx.record.Model != modelName {
+		types = append(types, ctx.record.Model)
+	}
+	if len(types) > 0 {
+		fmt.Fprintf(&b, "  import type { %s } from '$lib/types';\n", strings.Join(types, ", "))
 	}
 
 	// Import API client functions for data fetching and form submission
-	var listEp *ir.Endpoint
 	var createEp *ir.Endpoint
-	if needsEffect && modelName != "" {
-		listEp = findListEndpoint(app, modelName)
-	}
 	if needsFormState && modelName != "" {
 		createEp = findCreateEndpoint(app, modelName)
 	}
@@ -111,10 +223,29 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			apiImports = append(apiImports, fn)
 		}
 	}
+	if ctx.deleteEp != nil {
+		apiImports = append(apiImports, toCamelCase(ctx.deleteEp.Name))
+	}
+	if ctx.reorder != nil {
+		apiImports = append(apiImports, "reorder")
+	}
+	if ctx.record != nil {
+		apiImports = append(apiImports, toCamelCase(ctx.record.Endpoint), "ApiError")
+	}
+	if ctx.edit != nil {
+		apiImports = append(apiImports, editImports(ctx.edit)...)
+	}
+	if ctx.importForm != nil {
+		apiImports = append(apiImports, importImports(ctx.importForm)...)
+	}
+	if ctx.loadError != "" || ctx.edit != nil || ctx.importForm != nil {
+		apiImports = append(apiImports, "errorMessage")
+	}
+	if needsEffect && listEp == nil {
+		apiImports = append(apiImports, "request")
+	}
 	if len(apiImports) > 0 {
 		fmt.Fprintf(&b, "  import { %s } from '$lib/api';\n", strings.Join(apiImports, ", "))
-	} else if needsEffect {
-		b.WriteString("  import { request } from '$lib/api';\n")
 	}
 
 	// Component imports
@@ -138,6 +269,21 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	for comp := range usedComponents {
 		fmt.Fprintf(&b, "  import %s from '$lib/components/%s.svelte';\n", comp, comp)
 	}
+	if pageLoopsOverCalendar(page, app, modelName) {
+		b.WriteString("  import AddToCalendar from '$lib/components/AddToCalendar.svelte';\n")
+	}
+	if pageHasChart(page, app) {
+		b.WriteString("  import DataChart from '$lib/components/DataChart.svelte';\n")
+	}
+	if len(ctx.tooltips) > 0 {
+		b.WriteString("  import Tooltip from '$lib/components/Tooltip.svelte';\n")
+	}
+	if ctx.panel != nil {
+		b.WriteString("  import DetailPanel from '$lib/components/DetailPanel.svelte';\n")
+	}
+	if pageHasSkeleton(page) {
+		b.WriteString("  import Skeleton from '$lib/components/Skeleton.svelte';\n")
+	}
 
 	b.WriteString("\n")
 
@@ -150,18 +296,44 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			b.WriteString("  let data = $state<any[]>([]);\n")
 		}
 	}
+	if ctx.scroll {
+		b.WriteString("  let nextCursor = $state<string | null>(null);\n")
+		b.WriteString("  let loadingMore = $state(false);\n")
+		b.WriteString("  let sentinel = $state<HTMLDivElement>();\n")
+	}
+	if ctx.panel != nil {
+		fmt.Fprintf(&b, "  let selected = $state<%s | null>(null);\n", modelName)
+	}
+	if ctx.query != nil {
+		writeListQueryScript(&b, ctx)
+	}
 	if needsAuth {
 		b.WriteString("  let isLoggedIn = $state(!!localStorage.getItem('token'));\n")
 	}
 	if needsFormState {
 		b.WriteString("  let showForm = $state(false);\n")
 	}
+	if ctx.fieldErrors {
+		writeFieldErrorScript(&b)
+	}
 	if needsSuccess {
 		b.WriteString("  let success = $state('');\n")
 	}
 	if needsError {
 		b.WriteString("  let error = $state('');\n")
 	}
+	if ctx.loadError != "" {
+		b.WriteString("  let loadError = $state('');\n")
+	}
+	if ctx.record != nil {
+		writeRecordScript(&b, ctx)
+	}
+	if ctx.edit != nil {
+		writeEditScript(&b, ctx)
+	}
+	if ctx.importForm != nil {
+		writeImportScript(&b, ctx)
+	}
 
 	// Generate form field state and handleSubmit when create endpoint exists
 	if createEp != nil {
@@ -187,52 +359,63 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 		if ctx.hasErrorState {
 			b.WriteString("    error = '';\n")
 		}
-		b.WriteString("    try {\n")
-		b.WriteString("      const body = { ")
-		for i, f := range fields {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			fmt.Fprintf(&b, "%s", toCamelCase(f))
-		}
-		b.WriteString(" };\n")
-		fmt.Fprintf(&b, "      const res = await %s(body);\n", createFunc)
-		if isLogin {
-			b.WriteString("      localStorage.setItem('token', res.token);\n")
-			b.WriteString("      window.location.href = '/';\n")
+		if !isLogin && varName != "" && varName != "data" {
+			writeOptimisticCreate(&b, createFunc, fields, ctx)
 		} else {
-			if varName != "" && varName != "data" {
-				fmt.Fprintf(&b, "      %s = [...%s, res.data];\n", varName, varName)
+			b.WriteString("    try {\n")
+			b.WriteString("      const body = { ")
+			for i, f := range fields {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				fmt.Fprintf(&b, "%s", toCamelCase(f))
 			}
-			b.WriteString("      showForm = false;\n")
-			if ctx.hasSuccessState {
-				b.WriteString("      success = 'Created successfully';\n")
+			b.WriteString(" };\n")
+			fmt.Fprintf(&b, "      const res = await %s(body);\n", createFunc)
+			if isLogin {
+				b.WriteString("      localStorage.setItem('token', res.token);\n")
+				b.WriteString("      window.location.href = '/';\n")
+			} else {
+				b.WriteString("      showForm = false;\n")
+				if ctx.hasSuccessState {
+					b.WriteString("      success = 'Created successfully';\n")
+				}
+				// Reset form fields
+				for _, f := range fields {
+					fmt.Fprintf(&b, "      %s = '';\n", toCamelCase(f))
+				}
 			}
-			// Reset form fields
-			for _, f := range fields {
-				fmt.Fprintf(&b, "      %s = '';\n", toCamelCase(f))
+			b.WriteString("    } catch (err) {\n")
+			if ctx.hasErrorState {
+				b.WriteString("      error = err instanceof Error ? err.message : 'Something went wrong';\n")
+			} else {
+				b.WriteString("      console.error(err);\n")
 			}
+			b.WriteString("    }\n")
 		}
-		b.WriteString("    } catch (err) {\n")
-		if ctx.hasErrorState {
-			b.WriteString("      error = err instanceof Error ? err.message : 'Something went wrong';\n")
-		} else {
-			b.WriteString("      console.error(err);\n")
-		}
-		b.WriteString("    }\n")
 		b.WriteString("  }\n")
 	}
 
 	if needsEffect {
-		b.WriteString("\n  $effect(() => {\n")
-		if listEp != nil {
+		b.WriteString("\n  function load() {\n")
+		b.WriteString("    loading = true;\n")
+		b.WriteString("    loadError = '';\n")
+		if listEp != nil && ctx.query != nil && ctx.query.Paged {
+			fmt.Fprintf(&b, "    %s(undefined, listQuery)\n", toCamelCase(listEp.Name))
+			fmt.Fprintf(&b, "      .then(res => { %s = res.data ?? []; hasNext = res.pagination?.nextCursor != null; loading = false; })\n", varName)
+			fmt.Fprintf(&b, "      .catch(err => { %s; loading = false; });\n", loadFailed(ctx))
+		} else if listEp != nil && ctx.scroll {
+			fmt.Fprintf(&b, "    %s()\n", toCamelCase(listEp.Name))
+			fmt.Fprintf(&b, "      .then(res => { %s = res.data ?? []; nextCursor = res.pagination?.nextCursor ?? null; loading = false; })\n", varName)
+			fmt.Fprintf(&b, "      .catch(err => { %s; loading = false; });\n", loadFailed(ctx))
+		} else if listEp != nil {
 			fmt.Fprintf(&b, "    %s()\n", toCamelCase(listEp.Name))
 			if modelName != "" {
 				fmt.Fprintf(&b, "      .then(res => { %s = res.data ?? []; loading = false; })\n", varName)
 			} else {
 				b.WriteString("      .then(res => { data = res.data ?? []; loading = false; })\n")
 			}
-			b.WriteString("      .catch(() => loading = false);\n")
+			fmt.Fprintf(&b, "      .catch(err => { %s; loading = false; });\n", loadFailed(ctx))
 		} else {
 			apiPath := "/api/" + toKebabCase(varName)
 			b.WriteString("    // TODO: replace with a dedicated API endpoint\n")
@@ -242,48 +425,164 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			} else {
 				b.WriteString("      .then(res => { data = res.data ?? []; loading = false; })\n")
 			}
-			b.WriteString("      .catch(() => loading = false);\n")
+			fmt.Fprintf(&b, "      .catch(err => { %s; loading = false; });\n", loadFailed(ctx))
+		}
+		b.WriteString("  }\n")
+		if ctx.remember {
+			writeRememberLoad(&b, ctx)
+			writeRememberScript(&b, ctx)
+		} else {
+			b.WriteString("\n  $effect(() => {\n")
+			b.WriteString("    load();\n")
+			b.WriteString("  });\n")
 		}
-		b.WriteString("  });\n")
+	}
+	if ctx.scroll {
+		writeLoadMore(&b, listEp, ctx)
+	}
+	if ctx.deleteEp != nil {
+		writeDeleteHandler(&b, ctx.deleteEp, ctx)
+	}
+	if ctx.reorder != nil {
+		writeReorderHandlers(&b, ctx.reorder, ctx)
+	}
+	if needsFormState {
+		writeDialogFocus(&b)
+	}
+	if len(keyBindings) > 0 {
+		writeKeyBindings(&b, keyBindings, ctx)
 	}
 
 	b.WriteString("\n")
 	b.WriteString("</script>\n\n")
 
 	// Template
+	if len(keyBindings) > 0 {
+		b.WriteString("<svelte:window onkeydown={onKeyDown} />\n\n")
+	}
 	fmt.Fprintf(&b, "<div class=\"%s-page\">\n", toKebabCase(page.Name))
+	// Pages declaring their error state show load errors there
+	if ctx.loadError != "" && !ctx.hasErrorState {
+		writeLoadErrorSvelte(&b, "  ", ctx)
+	}
 
 	loopFields := collectLoopFields(page, ctx)
+	ctx.itemFields = loopFields
 	loopRendered := false
 	for _, a := range page.Content {
+		if ctx.table != nil && ctx.table.Covers(a) {
+			fmt.Fprintf(&b, "  <!-- %s — rendered by the table -->\n", a.Text)
+			continue
+		}
 		if a.Type == "loop" && loopRendered {
 			continue
 		}
 		if a.Type == "loop" {
 			loopRendered = true
 			writeLoopSvelte(&b, a.Text, "  ", ctx, loopFields)
+			if ctx.table == nil {
+				writeListEnd(&b, "  ", page, ctx)
+			}
 			continue
 		}
 		writeTemplateAction(&b, a, "  ", ctx)
+		if ctx.table != nil && a == ctx.table.Show {
+			writeListEnd(&b, "  ", page, ctx)
+		}
 	}
 
 	if needsFormState {
 		b.WriteString("  {#if showForm}\n")
 		b.WriteString("    <div class=\"modal-overlay\" onclick={() => showForm = false}>\n")
-		b.WriteString("      <!-- svelte-ignore a11y_click_events_have_key_events -->\n")
-		b.WriteString("      <div class=\"modal\" onclick={(e) => e.stopPropagation()}>\n")
-		b.WriteString("        <button class=\"modal-close\" onclick={() => showForm = false}>&times;</button>\n")
+		b.WriteString("      <div\n")
+		b.WriteString("        class=\"modal\"\n")
+		b.WriteString("        role=\"dialog\"\n")
+		b.WriteString("        aria-modal=\"true\"\n")
+		title := ""
+		if modelName != "" {
+			title = ctx.uniqueID("new-" + toKebabCase(modelName) + "-title")
+			fmt.Fprintf(&b, "        aria-labelledby=\"%s\"\n", title)
+		} else {
+			b.WriteString("        aria-label=\"New\"\n")
+		}
+		b.WriteString("        tabindex=\"-1\"\n")
+		b.WriteString("        use:focusDialog\n")
+		b.WriteString("        onclick={(e) => e.stopPropagation()}\n")
+		b.WriteString("        onkeydown={(e) => e.key === 'Escape' && (showForm = false)}\n")
+		b.WriteString("      >\n")
+		b.WriteString("        <button type=\"button\" class=\"modal-close\" aria-label=\"Close\" onclick={() => showForm = false}>&times;</button>\n")
 		if modelName != "" {
-			fmt.Fprintf(&b, "        <h2>New %s</h2>\n", modelName)
+			fmt.Fprintf(&b, "        <h2 id=\"%s\">New %s</h2>\n", title, modelName)
 		}
 		writeFormSvelte(&b, "a form to create a "+strings.ToLower(modelName), "        ", ctx)
 		b.WriteString("      </div>\n")
 		b.WriteString("    </div>\n")
 		b.WriteString("  {/if}\n")
 	}
+	if ctx.panel != nil {
+		writePanelSvelte(&b, "  ", ctx)
+	}
 
 	b.WriteString("</div>\n")
-	return b.String()
+	if ctx.responsive != nil {
+		b.WriteString("\n<style>\n")
+		b.WriteString(responsiveStyles(page, ctx))
+		b.WriteString("</style>\n")
+	}
+	return withFormatImports(withTimeImports(b.String(), ctx), ctx)
+}
+
+// ── Table ──
+
+// writeTableSvelte renders the page's table: a header per column, sortable
+// ones clickable, and a row per record.
+func writeTableSvelte(b *strings.Builder, indent string, ctx *pageContext) {
+	t := ctx.table
+	rows := rowsVar(ctx)
+	fmt.Fprintf(b, "%s<table class=\"data-table\">\n", indent)
+	fmt.Fprintf(b, "%s  <thead>\n", indent)
+	fmt.Fprintf(b, "%s    <tr>\n", indent)
+	for _, f := range t.Columns {
+		label := ir.FieldLabel(f.Name)
+		if t.IsSortable(f) {
+			fmt.Fprintf(b, "%s      <!-- svelte-ignore a11y_click_events_have_key_events, a11y_no_noninteractive_element_interactions -->\n", indent)
+			fmt.Fprintf(b, "%s      <th class=\"sortable\" onclick={() => sortBy('%s')}>\n", indent, f.Name)
+			fmt.Fprintf(b, "%s        %s{sortKey === '%s' ? (sortDesc ? ' ▼' : ' ▲') : ''}\n", indent, label, f.Name)
+			fmt.Fprintf(b, "%s      </th>\n", indent)
+		} else {
+			fmt.Fprintf(b, "%s      <th>%s</th>\n", indent, label)
+		}
+	}
+	fmt.Fprintf(b, "%s    </tr>\n", indent)
+	fmt.Fprintf(b, "%s  </thead>\n", indent)
+	fmt.Fprintf(b, "%s  <tbody>\n", indent)
+	fmt.Fprintf(b, "%s    {#each %s as %s (%s.id)}\n", indent, rows, ctx.itemVar, ctx.itemVar)
+	if t.RowPage != "" {
+		fmt.Fprintf(b, "%s      <!-- svelte-ignore a11y_click_events_have_key_events, a11y_no_noninteractive_element_interactions -->\n", indent)
+		fmt.Fprintf(b, "%s      <tr class=\"clickable\" onclick={() => goto(%s)}>\n", indent, recordURL(t.RowPage, ctx.itemVar, ctx))
+	} else if ctx.panel != nil {
+		fmt.Fprintf(b, "%s      <tr class=\"clickable\"%s>\n", indent, panelClick(ctx.itemVar, ctx))
+	} else {
+		fmt.Fprintf(b, "%s      <tr>\n", indent)
+	}
+	for _, f := range t.Columns {
+		value := fmt.Sprintf("{%s.%s}", ctx.itemVar, f.Name)
+		if f.Type == "boolean" {
+			value = fmt.Sprintf("{%s.%s ? 'Yes' : 'No'}", ctx.itemVar, f.Name)
+		} else if t := timeSvelte(ctx.itemVar+"."+f.Name, f, ctx); t != "" {
+			value = t
+		} else if n := numberSvelte(ctx.itemVar+"."+f.Name, f, ctx); n != "" {
+			value = n
+		}
+		if tip := tooltipOn(f.Name, ctx); tip != nil {
+			value = fmt.Sprintf("<Tooltip %s>%s</Tooltip>", tooltipText(tip, ctx.itemVar), value)
+		}
+		fmt.Fprintf(b, "%s        <td>%s</td>\n", indent, value)
+	}
+	fmt.Fprintf(b, "%s      </tr>\n", indent)
+	fmt.Fprintf(b, "%s    {/each}\n", indent)
+	fmt.Fprintf(b, "%s  </tbody>\n", indent)
+	fmt.Fprintf(b, "%s</table>\n", indent)
 }
 
 func generateComponent(comp *ir.Component, app *ir.Application) string {
@@ -300,6 +599,11 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 		}
 	}
 
+	tips, tipProp := componentTooltips(comp, app)
+	if len(tips) > 0 {
+		b.WriteString("  import Tooltip from '$lib/components/Tooltip.svelte';\n")
+	}
+
 	if hasDataModelImport {
 		models := []string{}
 		for _, prop := range comp.Props {
@@ -361,10 +665,20 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 		app:         app,
 		props:       propsMap,
 		isComponent: true,
+		tooltips:    tips,
 	}
 
+	// Hovering anywhere over the component shows its tooltip
+	indent := "  "
+	if len(tips) > 0 {
+		fmt.Fprintf(&b, "%s<Tooltip %s>\n", indent, tooltipText(tips[0], tipProp))
+		indent += "  "
+	}
 	for _, a := range comp.Content {
-		writeTemplateAction(&b, a, "  ", ctx)
+		writeTemplateAction(&b, a, indent, ctx)
+	}
+	if len(tips) > 0 {
+		b.WriteString("  </Tooltip>\n")
 	}
 
 	b.WriteString("</div>\n")
@@ -372,12 +686,41 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 }
 
 func writeTemplateAction(b *strings.Builder, a *ir.Action, indent string, ctx *pageContext) {
+	if ctx.record != nil && ir.IsDetailRoute(a) {
+		writeRecordSvelte(b, indent, ctx)
+		return
+	}
+	if ctx.edit != nil && a == ctx.edit.Form {
+		writeEditFormSvelte(b, indent, ctx)
+		return
+	}
+	if ctx.importForm != nil && a == ctx.importForm.Form {
+		writeImportFormSvelte(b, indent, ctx)
+		return
+	}
 	switch a.Type {
 	case "display":
+		if a.Template != "" {
+			writeTemplateSvelte(b, a.Template, indent, ctx)
+			return
+		}
+		if ctx.table != nil && a == ctx.table.Show {
+			writeTableSvelte(b, indent, ctx)
+			return
+		}
+		if c := ir.ChartFor(ctx.app, a); c != nil {
+			writeChartSvelte(b, c, indent)
+			return
+		}
 		writeDisplaySvelte(b, a.Text, indent, ctx)
 	case "input":
 		writeInputSvelte(b, a.Text, indent, ctx)
 	case "interact":
+		if ir.IsReorder(a) || ir.IsKeyBinding(a) || ir.IsInfiniteScroll(a) || ir.IsDelete(a) && ctx.deleteEp != nil ||
+			tooltipWired(a, ctx) || panelWired(a, ctx) || a == ctx.itemLink {
+			writeInteractionNote(b, a, indent, ctx)
+			return
+		}
 		writeInteractSvelte(b, a.Text, indent, ctx)
 	case "condition":
 		writeConditionSvelte(b, a.Text, indent, ctx)
@@ -386,6 +729,14 @@ func writeTemplateAction(b *strings.Builder, a *ir.Action, indent string, ctx *p
 	case "query":
 		// handled by $effect
 	default:
+		if ctx.remember && ir.IsRememberView(a) {
+			fmt.Fprintf(b, "%s<!-- %s — restored from sessionStorage -->\n", indent, a.Text)
+			return
+		}
+		if ctx.responsive != nil && ir.IsResponsive(a) {
+			fmt.Fprintf(b, "%s<!-- %s — handled by the page's styles -->\n", indent, a.Text)
+			return
+		}
 		fmt.Fprintf(b, "%s<!-- TODO: %s -->\n", indent, a.Text)
 	}
 }
@@ -403,6 +754,12 @@ func writeDisplaySvelte(b *strings.Builder, text string, indent string, ctx *pag
 	}
 	lower := strings.ToLower(cleaned)
 
+	// Pagination
+	if (strings.Contains(lower, "pagination") || strings.Contains(lower, "page controls")) && ctx.query != nil && ctx.query.Paged {
+		writePaginationSvelte(b, indent)
+		return
+	}
+
 	// Hero section
 	if strings.Contains(lower, "hero") {
 		appName := ""
@@ -581,35 +938,87 @@ func writeDisplaySvelte(b *strings.Builder, text string, indent string, ctx *pag
 	fmt.Fprintf(b, "%s<div class=\"%s\"></div>\n", indent, slugify(text))
 }
 
+// writeTemplateSvelte renders show "..." text. References interpolate a
+// component's props, or a page's data from its first loaded record;
+// Svelte escapes the values, and the text is escaped for the markup here.
+func writeTemplateSvelte(b *strings.Builder, tmpl string, indent string, ctx *pageContext) {
+	var out strings.Builder
+	for _, part := range ir.ParseTemplate(tmpl) {
+		if part.Root == "" {
+			out.WriteString(svelteText.Replace(part.Text))
+			continue
+		}
+		fmt.Fprintf(&out, "{%s}", templateExpr(part, ctx))
+	}
+	fmt.Fprintf(b, "%s<p>%s</p>\n", indent, out.String())
+}
+
+var svelteText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "{", "&#123;", "}", "&#125;")
+
+// templateExpr resolves a template reference: "task.title" to a prop's
+// field, or to the page's first record.
+func templateExpr(ref ir.TemplatePart, ctx *pageContext) string {
+	for name, typ := range ctx.props {
+		if strings.EqualFold(name, ref.Root) {
+			return name + templateField(findModel(ctx.app, typ), ref.Field, ".")
+		}
+	}
+	return ctx.varName + "[0]" + templateField(findModel(ctx.app, ctx.modelName), ref.Field, "?.")
+}
+
+// templateField returns sep and the model's name for field, or nothing
+// without a field.
+func templateField(model *ir.DataModel, field, sep string) string {
+	if field == "" {
+		return ""
+	}
+	if model != nil {
+		if f := model.FieldNamed(field); f != nil {
+			return sep + f.Name
+		}
+	}
+	return sep + field
+}
+
 // ── Input ──
 
 func writeInputSvelte(b *strings.Builder, text string, indent string, ctx *pageContext) {
 	lower := strings.ToLower(text)
 
+	if strings.Contains(lower, "search") && ctx.query != nil {
+		writeSearchSvelte(b, indent)
+		return
+	}
 	if strings.Contains(lower, "search") {
-		fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" class=\"search-input\" oninput={() => {/* TODO: filter */}} />\n", indent)
+		fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" aria-label=\"Search\" class=\"search-input\" oninput={() => {/* TODO: filter */}} />\n", indent)
 		return
 	}
+	if ctx.query != nil {
+		if f := ir.FilterField(ctx.query.Model, &ir.Action{Type: "input", Text: text}); f != nil {
+			writeFilterSvelte(b, indent, f)
+			return
+		}
+	}
 	if strings.Contains(lower, "dropdown") || strings.Contains(lower, "filter by") || strings.Contains(lower, "select") {
-		label := "All"
+		label, name := "All", "Filter"
 		fieldName := "filter"
 		if strings.Contains(lower, "status") {
-			label = "All Statuses"
+			label, name = "All Statuses", "Filter by status"
 			fieldName = "statusFilter"
 		} else if strings.Contains(lower, "priority") {
-			label = "All Priorities"
+			label, name = "All Priorities", "Filter by priority"
 			fieldName = "priorityFilter"
 		} else if strings.Contains(lower, "category") {
-			label = "Select Category"
+			label, name = "Select Category", "Category"
 			fieldName = "categoryFilter"
 		}
-		fmt.Fprintf(b, "%s<select class=\"filter-select\" bind:value={%s} onchange={() => {/* TODO: filter */}}>\n", indent, fieldName)
+		fmt.Fprintf(b, "%s<select class=\"filter-select\" aria-label=\"%s\" bind:value={%s} onchange={() => {/* TODO: filter */}}>\n", indent, name, fieldName)
 		fmt.Fprintf(b, "%s  <option value=\"\">%s</option>\n", indent, label)
 		fmt.Fprintf(b, "%s</select>\n", indent)
 		return
 	}
 	if strings.Contains(lower, "date") && (strings.Contains(lower, "picker") || strings.Contains(lower, "range")) {
-		fmt.Fprintf(b, "%s<input type=\"date\" class=\"date-filter\" onchange={() => {/* TODO: filter */}} />\n", indent)
+		fmt.Fprintf(b, "%s<input type=\"date\" class=\"date-filter\" aria-label=\"Filter by date\" onchange={() => {/* TODO: filter */}} />\n", indent)
 		return
 	}
 	if strings.Contains(lower, "button") && (strings.Contains(lower, "create") || strings.Contains(lower, "new") || strings.Contains(lower, "add")) {
@@ -636,9 +1045,10 @@ func writeInputSvelte(b *strings.Builder, text string, indent string, ctx *pageC
 		} else if strings.Contains(lower, "cover") || strings.Contains(lower, "image") {
 			label = "Upload image"
 		}
+		id := ctx.uniqueID("file-upload")
 		fmt.Fprintf(b, "%s<div class=\"file-upload\">\n", indent)
-		fmt.Fprintf(b, "%s  <label>%s</label>\n", indent, label)
-		fmt.Fprintf(b, "%s  <input type=\"file\" accept=\"image/*\" onchange={async (e) => {\n", indent)
+		fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, id, label)
+		fmt.Fprintf(b, "%s  <input id=\"%s\" type=\"file\" accept=\"image/*\" onchange={async (e) => {\n", indent, id)
 		fmt.Fprintf(b, "%s    const file = e.currentTarget.files?.[0];\n", indent)
 		fmt.Fprintf(b, "%s    if (!file) return;\n", indent)
 		fmt.Fprintf(b, "%s    const fd = new FormData();\n", indent)
@@ -657,7 +1067,7 @@ func writeInputSvelte(b *strings.Builder, text string, indent string, ctx *pageC
 	}
 	if strings.Contains(lower, "rich text") || strings.Contains(lower, "editor") {
 		fmt.Fprintf(b, "%s<div class=\"rich-text-editor\">\n", indent)
-		fmt.Fprintf(b, "%s  <textarea placeholder=\"Write your content...\" bind:value={content}></textarea>\n", indent)
+		fmt.Fprintf(b, "%s  <textarea placeholder=\"Write your content...\" aria-label=\"%s\" bind:value={content}></textarea>\n", indent, inputLabel(text))
 		fmt.Fprintf(b, "%s</div>\n", indent)
 		return
 	}
@@ -669,9 +1079,10 @@ func writeInputSvelte(b *strings.Builder, text string, indent string, ctx *pageC
 				break
 			}
 		}
+		id := ctx.uniqueID(toKebabCase(toCamelCase(fieldName)))
 		fmt.Fprintf(b, "%s<div class=\"form-field\">\n", indent)
-		fmt.Fprintf(b, "%s  <label>%s</label>\n", indent, capitalize(fieldName))
-		fmt.Fprintf(b, "%s  <input type=\"text\" placeholder=\"%s\" bind:value={%s} />\n", indent, fieldName, toCamelCase(fieldName))
+		fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, id, capitalize(fieldName))
+		fmt.Fprintf(b, "%s  <input id=\"%s\" type=\"text\" placeholder=\"%s\" bind:value={%s} />\n", indent, id, fieldName, toCamelCase(fieldName))
 		fmt.Fprintf(b, "%s</div>\n", indent)
 		return
 	}
@@ -683,7 +1094,7 @@ func writeInputSvelte(b *strings.Builder, text string, indent string, ctx *pageC
 		fmt.Fprintf(b, "%s<button class=\"btn\">%s</button>\n", indent, label)
 		return
 	}
-	fmt.Fprintf(b, "%s<input type=\"text\" placeholder=\"%s\" bind:value={%s} />\n", indent, text, toCamelCase(text))
+	fmt.Fprintf(b, "%s<input type=\"text\" placeholder=\"%s\" aria-label=\"%s\" bind:value={%s} />\n", indent, text, inputLabel(text), toCamelCase(text))
 }
 
 func writeFormSvelte(b *strings.Builder, text string, indent string, ctx *pageContext) {
@@ -703,28 +1114,19 @@ func writeFormSvelte(b *strings.Builder, text string, indent string, ctx *pageCo
 		}
 	}
 
+	model := formModel(lower, ctx)
+	formID := "form"
+	switch {
+	case isLogin:
+		formID = "login-form"
+	case model != nil:
+		formID = toKebabCase(model.Name) + "-form"
+	}
+	formID = ctx.uniqueID(formID)
+
 	if createEp != nil {
 		// Wired form: use handleSubmit from script block
 		fmt.Fprintf(b, "%s<form class=\"form\" onsubmit={handleSubmit}>\n", indent)
-		for _, f := range fields {
-			inputType := "text"
-			fl := strings.ToLower(f)
-			if strings.Contains(fl, "email") {
-				inputType = "email"
-			} else if strings.Contains(fl, "password") {
-				inputType = "password"
-			} else if strings.Contains(fl, "date") {
-				inputType = "date"
-			} else if strings.Contains(fl, "number") || strings.Contains(fl, "count") {
-				inputType = "number"
-			}
-			fmt.Fprintf(b, "%s  <div class=\"form-field\">\n", indent)
-			fmt.Fprintf(b, "%s    <label>%s</label>\n", indent, capitalize(f))
-			fmt.Fprintf(b, "%s    <input type=\"%s\" placeholder=\"%s\" bind:value={%s} />\n", indent, inputType, capitalize(f), toCamelCase(f))
-			fmt.Fprintf(b, "%s  </div>\n", indent)
-		}
-		fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
-		fmt.Fprintf(b, "%s</form>\n", indent)
 	} else {
 		// Fallback: no endpoint wired
 		onSubmit := "/* TODO: submit */"
@@ -734,32 +1136,18 @@ func writeFormSvelte(b *strings.Builder, text string, indent string, ctx *pageCo
 			onSubmit = "success = 'Saved successfully'"
 		}
 		fmt.Fprintf(b, "%s<form class=\"form\" onsubmit={(e) => { e.preventDefault(); %s }}>\n", indent, onSubmit)
-		for _, f := range fields {
-			inputType := "text"
-			fl := strings.ToLower(f)
-			if strings.Contains(fl, "email") {
-				inputType = "email"
-			} else if strings.Contains(fl, "password") {
-				inputType = "password"
-			} else if strings.Contains(fl, "date") {
-				inputType = "date"
-			} else if strings.Contains(fl, "number") || strings.Contains(fl, "count") {
-				inputType = "number"
-			}
-			fmt.Fprintf(b, "%s  <div class=\"form-field\">\n", indent)
-			fmt.Fprintf(b, "%s    <label>%s</label>\n", indent, capitalize(f))
-			fmt.Fprintf(b, "%s    <input type=\"%s\" name=\"%s\" placeholder=\"%s\" bind:value={%s} />\n", indent, inputType, toCamelCase(f), capitalize(f), toCamelCase(f))
-			fmt.Fprintf(b, "%s  </div>\n", indent)
-		}
-		fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
-		fmt.Fprintf(b, "%s</form>\n", indent)
 	}
+	for _, in := range formInputs(formID, fields, isLogin, model) {
+		writeFormFieldSvelte(b, indent+"  ", in, ctx)
+	}
+	fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
+	fmt.Fprintf(b, "%s</form>\n", indent)
 }
 
 // ── Loop ──
 
 func writeLoopSvelte(b *strings.Builder, text string, indent string, ctx *pageContext, fields []string) {
-	dataVar := ctx.varName
+	dataVar := rowsVar(ctx)
 	if dataVar == "" {
 		dataVar = "data"
 	}
@@ -768,6 +1156,25 @@ func writeLoopSvelte(b *strings.Builder, text string, indent string, ctx *pageCo
 		item = "item"
 	}
 
+	// A list users can drag is a svelte-dnd-action zone
+	open := func() string {
+		if ctx.reorder != nil {
+			fmt.Fprintf(b, "%s<div class=\"sortable-list\" use:dndzone={{ items: %s, flipDurationMs: 150 }} onconsider={considerOrder} onfinalize={saveOrder}>\n", indent, dataVar)
+			fmt.Fprintf(b, "%s  {#each %s as %s (%s.id)}\n", indent, dataVar, item, item)
+			return indent + "  "
+		}
+		fmt.Fprintf(b, "%s{#each %s as %s (%s.id)}\n", indent, dataVar, item, item)
+		return indent
+	}
+	close := func() {
+		if ctx.reorder != nil {
+			fmt.Fprintf(b, "%s  {/each}\n", indent)
+			fmt.Fprintf(b, "%s</div>\n", indent)
+			return
+		}
+		fmt.Fprintf(b, "%s{/each}\n", indent)
+	}
+
 	compRef := extractComponentRef(text)
 	if compRef == "" {
 		// Also check for PascalCase last word
@@ -781,9 +1188,18 @@ func writeLoopSvelte(b *strings.Builder, text string, indent string, ctx *pageCo
 	}
 
 	if compRef != "" {
-		fmt.Fprintf(b, "%s{#each %s as %s (%s.id)}\n", indent, dataVar, item, item)
-		fmt.Fprintf(b, "%s  <%s %s={%s} />\n", indent, compRef, item, item)
-		fmt.Fprintf(b, "%s{/each}\n", indent)
+		inner := open()
+		if ctx.deleteEp != nil || ctx.panel != nil || ctx.itemPage != "" {
+			fmt.Fprintf(b, "%s  <div class=\"%s-item\"%s>\n", inner, toKebabCase(ctx.modelName), itemClick(item, ctx))
+			fmt.Fprintf(b, "%s    <%s %s={%s} />\n", inner, compRef, item, item)
+			if ctx.deleteEp != nil {
+				writeDeleteButton(b, inner+"    ", item, ctx)
+			}
+			fmt.Fprintf(b, "%s  </div>\n", inner)
+		} else {
+			fmt.Fprintf(b, "%s  <%s %s={%s} />\n", inner, compRef, item, item)
+		}
+		close()
 		return
 	}
 
@@ -795,31 +1211,73 @@ func writeLoopSvelte(b *strings.Builder, text string, indent string, ctx *pageCo
 	if modelClass == "" {
 		modelClass = "item"
 	}
-	fmt.Fprintf(b, "%s{#each %s as %s (%s.id)}\n", indent, dataVar, item, item)
-	fmt.Fprintf(b, "%s  <div class=\"%s-item\">\n", indent, modelClass)
+	inner := open()
+	fmt.Fprintf(b, "%s  <div class=\"%s-item\"%s>\n", inner, modelClass, itemClick(item, ctx))
+	whole := tooltipOn("", ctx)
+	if whole != nil {
+		fmt.Fprintf(b, "%s    <Tooltip %s>\n", inner, tooltipText(whole, item))
+		inner += "  "
+	}
 	if len(fields) > 0 {
 		for _, f := range fields {
 			fieldExpr := item + "." + f
 			fl := strings.ToLower(f)
+			var el string
 			if fl == "status" || fl == "role" || fl == "priority" || fl == "category" {
-				fmt.Fprintf(b, "%s    <span class=\"badge\">{%s}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span class=\"badge\">{%s}</span>", fieldExpr)
 			} else if fl == "title" || fl == "name" {
-				fmt.Fprintf(b, "%s    <h3>{%s}</h3>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<h3>{%s}</h3>", fieldExpr)
+			} else if t := timeSvelte(fieldExpr, modelField(f, ctx), ctx); t != "" {
+				el = t
+			} else if n := numberSvelte(fieldExpr, modelField(f, ctx), ctx); n != "" {
+				el = fmt.Sprintf("<span class=\"%s\">%s</span>", numberClass(modelField(f, ctx)), n)
 			} else if strings.Contains(fl, "date") || fl == "due" || fl == "created" || strings.Contains(fl, "published") {
-				fmt.Fprintf(b, "%s    <time>{%s}</time>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<time>{%s}</time>", fieldExpr)
 			} else if strings.Contains(fl, "excerpt") {
-				fmt.Fprintf(b, "%s    <p>{%s}</p>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<p>{%s}</p>", fieldExpr)
 			} else if strings.Contains(fl, "count") || strings.Contains(fl, "view") {
-				fmt.Fprintf(b, "%s    <span class=\"count\">{%s}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span class=\"count\">{%s}</span>", fieldExpr)
 			} else {
-				fmt.Fprintf(b, "%s    <span>{%s}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span>{%s}</span>", fieldExpr)
+			}
+			if tip := tooltipOn(f, ctx); tip != nil {
+				el = fmt.Sprintf("<Tooltip %s>%s</Tooltip>", tooltipText(tip, item), el)
 			}
+			fmt.Fprintf(b, "%s    %s\n", inner, el)
 		}
 	} else {
-		fmt.Fprintf(b, "%s    <span>{JSON.stringify(%s)}</span>\n", indent, item)
+		fmt.Fprintf(b, "%s    <span>{JSON.stringify(%s)}</span>\n", inner, item)
+	}
+	if whole != nil {
+		inner = inner[:len(inner)-2]
+		fmt.Fprintf(b, "%s    </Tooltip>\n", inner)
+	}
+	cal := ir.CalendarFor(ctx.app, ctx.modelName)
+	if cal != nil {
+		fmt.Fprintf(b, "%s    <AddToCalendar feed=\"%s\" id={%s.id} />\n", inner, cal.Slug, item)
+	}
+	if ctx.deleteEp != nil {
+		writeDeleteButton(b, inner+"    ", item, ctx)
 	}
-	fmt.Fprintf(b, "%s  </div>\n", indent)
-	fmt.Fprintf(b, "%s{/each}\n", indent)
+	fmt.Fprintf(b, "%s  </div>\n", inner)
+	close()
+	if cal != nil {
+		fmt.Fprintf(b, "%s<AddToCalendar feed=\"%s\" />\n", indent, cal.Slug)
+	}
+}
+
+// pageLoopsOverCalendar reports whether the page lists records of a model
+// published as a calendar, so each one gets an "Add to calendar" button.
+func pageLoopsOverCalendar(page *ir.Page, app *ir.Application, modelName string) bool {
+	if ir.CalendarFor(app, modelName) == nil {
+		return false
+	}
+	for _, a := range page.Content {
+		if a.Type == "loop" {
+			return true
+		}
+	}
+	return false
 }
 
 // ── Condition ──
@@ -840,13 +1298,7 @@ func writeConditionSvelte(b *strings.Builder, text string, indent string, ctx *p
 	// Loading
 	if strings.Contains(lower, "while loading") || strings.Contains(lower, "is loading") {
 		if strings.Contains(lower, "skeleton") {
-			fmt.Fprintf(b, "%s{#if loading}\n", indent)
-			fmt.Fprintf(b, "%s  <div class=\"skeleton-screen\">\n", indent)
-			fmt.Fprintf(b, "%s    {#each [1,2,3] as i}\n", indent)
-			fmt.Fprintf(b, "%s      <div class=\"skeleton-item\"></div>\n", indent)
-			fmt.Fprintf(b, "%s    {/each}\n", indent)
-			fmt.Fprintf(b, "%s  </div>\n", indent)
-			fmt.Fprintf(b, "%s{/if}\n", indent)
+			writeSkeletonSvelte(b, indent, ctx)
 		} else {
 			fmt.Fprintf(b, "%s{#if loading}\n", indent)
 			fmt.Fprintf(b, "%s  <div class=\"loading-spinner\">\n", indent)
@@ -907,17 +1359,24 @@ func writeConditionSvelte(b *strings.Builder, text string, indent string, ctx *p
 		if message == "" {
 			message = "Success!"
 		}
-		fmt.Fprintf(b, "%s{#if success}\n", indent)
-		fmt.Fprintf(b, "%s  <div class=\"alert alert-success\">{success || '%s'}</div>\n", indent, message)
-		fmt.Fprintf(b, "%s{/if}\n", indent)
+		fmt.Fprintf(b, "%s<div role=\"status\">\n", indent)
+		fmt.Fprintf(b, "%s  {#if success}\n", indent)
+		fmt.Fprintf(b, "%s    <div class=\"alert alert-success\">{success || '%s'}</div>\n", indent, message)
+		fmt.Fprintf(b, "%s  {/if}\n", indent)
+		fmt.Fprintf(b, "%s</div>\n", indent)
 		return
 	}
 
 	// Error
 	if strings.Contains(lower, "error") {
-		fmt.Fprintf(b, "%s{#if error}\n", indent)
-		fmt.Fprintf(b, "%s  <div class=\"alert alert-error\">{error}</div>\n", indent)
-		fmt.Fprintf(b, "%s{/if}\n", indent)
+		fmt.Fprintf(b, "%s<div role=\"alert\">\n", indent)
+		fmt.Fprintf(b, "%s  {#if error}\n", indent)
+		fmt.Fprintf(b, "%s    <div class=\"alert alert-error\">{error}</div>\n", indent)
+		fmt.Fprintf(b, "%s  {/if}\n", indent)
+		fmt.Fprintf(b, "%s</div>\n", indent)
+		if ctx.loadError != "" {
+			writeLoadErrorSvelte(b, indent, ctx)
+		}
 		return
 	}
 
@@ -1059,6 +1518,12 @@ func writeInteractSvelte(b *strings.Builder, text string, indent string, ctx *pa
 
 func detectPageModel(page *ir.Page, app *ir.Application) (modelName, varName, itemVar string) {
 	for _, a := range page.Content {
+		if ir.IsTable(a) {
+			if m := ir.TableModel(app, a); m != nil {
+				return m.Name, strings.ToLower(pluralize(m.Name)), strings.ToLower(m.Name)
+			}
+			continue
+		}
 		if a.Type == "query" || a.Type == "loop" {
 			for _, m := range app.Data {
 				lowerText := strings.ToLower(a.Text)
@@ -1085,7 +1550,7 @@ func findListEndpoint(app *ir.Application, modelName string) *ir.Endpoint {
 	}
 	for i := range app.APIs {
 		lower := strings.ToLower(app.APIs[i].Name)
-		if strings.HasPrefix(lower, "get") && strings.Contains(lower, lowerModel) {
+		if strings.HasPrefix(lower, "get") && strings.Contains(lower, lowerModel) && !ir.LoadsRecord(app, app.APIs[i]) {
 			return app.APIs[i]
 		}
 	}
@@ -1377,6 +1842,10 @@ func extractFormFields(lower string, ctx *pageContext) []string {
 						if f.Encrypted {
 							continue
 						}
+						// Dragging the list sets its position
+						if r := ir.ReorderFor(ctx.app, model.Name); r != nil && r.Field == f.Name {
+							continue
+						}
 						fields = append(fields, f.Name)
 					}
 					return fields
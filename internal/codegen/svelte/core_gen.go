@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/barun-bash/human/internal/codegen/themes"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -30,6 +31,9 @@ func generateTypes(app *ir.Application) string {
 			fmt.Fprintf(&b, "  %s%s: %s;\n", f.Name, optional, fieldType)
 		}
 
+		if model.Versioned {
+			fmt.Fprintf(&b, "  %s: number;\n", ir.VersionColumn)
+		}
 		for _, rel := range model.Relations {
 			switch rel.Kind {
 			case "belongs_to":
@@ -47,44 +51,124 @@ func generateTypes(app *ir.Application) string {
 
 func generateApi(app *ir.Application) string {
 	var b strings.Builder
-	b.WriteString(`// Generated by Human compiler — do not edit
-
-export interface ApiResponse<T> {
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	var types []string
+	if len(app.Notifications) > 0 {
+		types = append(types, "Notification")
+	}
+	if ir.AccountModel(app) != nil {
+		types = append(types, app.Accounts.Model)
+	}
+	if len(types) > 0 {
+		fmt.Fprintf(&b, "import type { %s } from './types';\n\n", strings.Join(types, ", "))
+	}
+	b.WriteString(`export interface ApiResponse<T> {
   data: T;
   error?: string;
+  pagination?: { limit: number; nextCursor: string | null };
 }
 
+export class ApiError extends Error {
+  readonly status: number;
+
+  constructor(status: number, message: string) {
+    super(message);
+    this.name = 'ApiError';
+    this.status = status;
+  }
+}
+
+export function errorMessage(err: unknown, fallback: string): string {
+  return err instanceof ApiError && err.message ? err.message : fallback;
+}
+`)
+	if ir.ProtectsEdits(app) {
+		writeConflictError(&b)
+	}
+	if ir.StoresUTC(app) {
+		writeTimeClient(&b)
+	}
+	b.WriteString(`
 const API_BASE_URL = import.meta.env?.VITE_API_URL || '';
 
 export async function request<T>(
   method: string,
   path: string,
   body?: Record<string, unknown>,
-): Promise<ApiResponse<T>> {
+`)
+	if ir.ProtectsEdits(app) {
+		b.WriteString("  extraHeaders: Record<string, string> = {},\n")
+	}
+	b.WriteString(`): Promise<ApiResponse<T>> {
   let token: string | null = null;
   if (typeof localStorage !== 'undefined') {
     token = localStorage.getItem('token');
   }
   const headers: Record<string, string> = {
     'Content-Type': 'application/json',
-  };
+`)
+	if ir.ProtectsEdits(app) {
+		b.WriteString("    ...extraHeaders,\n")
+	}
+	b.WriteString(`  };
   if (token) {
     headers['Authorization'] = ` + "`Bearer ${token}`" + `;
   }
   const res = await fetch(` + "`${API_BASE_URL}${path}`" + `, {
     method,
     headers,
-    body: body ? JSON.stringify(body) : undefined,
-  });
-  return res.json();
+`)
+	if ir.StoresUTC(app) {
+		b.WriteString("    body: body ? JSON.stringify(utcTimes(body)) : undefined,\n")
+	} else {
+		b.WriteString("    body: body ? JSON.stringify(body) : undefined,\n")
+	}
+	b.WriteString(`  });
+  const json = await res.json().catch(() => ({}));
+  if (!res.ok) {
+`)
+	if ir.ProtectsEdits(app) {
+		b.WriteString("    if (res.status === 409 && json.current) {\n")
+		b.WriteString("      throw new ConflictError(json.error ?? res.statusText, json.current);\n")
+		b.WriteString("    }\n")
+	}
+	b.WriteString(`    throw new ApiError(res.status, json.error ?? res.statusText);
+  }
+  return json;
 }
 `)
 
+	if hasReports(app) {
+		writeReportType(&b)
+	}
+	if ir.HasImports(app) {
+		writeImportClient(&b)
+	}
+	if ir.AccountModel(app) != nil {
+		writeProfileType(&b, app)
+	}
+
 	for _, ep := range app.APIs {
 		b.WriteString("\n")
+		if m := ir.ConflictModel(app, ep); m != nil && len(ep.Params) > 0 {
+			writeVersionedUpdateFunction(&b, ep, m)
+			continue
+		}
+		if ep.Import != nil {
+			writeImportFunction(&b, ep)
+			continue
+		}
+		if ep.Account != "" && app.Accounts != nil {
+			writeAccountFunction(&b, ep, app)
+			continue
+		}
 		funcName := toCamelCase(ep.Name)
 		method := httpMethod(ep.Name)
 		path := apiPath(ep.Name)
+		response := "unknown"
+		if ep.Aggregate != nil {
+			response = "ReportRow[]"
+		}
 
 		if len(ep.Params) > 0 {
 			paramFields := make([]string, len(ep.Params))
@@ -93,20 +177,47 @@ export async function request<T>(
 				paramFields[i] = fmt.Sprintf("%s: string", paramName)
 			}
 			paramType := fmt.Sprintf("{ %s }", strings.Join(paramFields, "; "))
-			fmt.Fprintf(&b, "export async function %s(params: %s): Promise<ApiResponse<unknown>> {\n", funcName, paramType)
-			if method == "GET" {
+			if method == "GET" && ep.PageSize > 0 {
+				// Paginated lists take the nextCursor of the page before
+				fmt.Fprintf(&b, "export async function %s(params: %s, cursor?: string): Promise<ApiResponse<%s>> {\n", funcName, paramType, response)
+				b.WriteString("  const qs = new URLSearchParams(params as unknown as Record<string, string>);\n")
+				b.WriteString("  if (cursor) qs.set('cursor', cursor);\n")
+				fmt.Fprintf(&b, "  return request<%s>('%s', `%s?${qs}`);\n", response, method, path)
+			} else if method == "GET" {
+				fmt.Fprintf(&b, "export async function %s(params: %s): Promise<ApiResponse<%s>> {\n", funcName, paramType, response)
 				b.WriteString("  const qs = new URLSearchParams(params as unknown as Record<string, string>).toString();\n")
-				fmt.Fprintf(&b, "  return request<unknown>('%s', `%s?${qs}`);\n", method, path)
+				fmt.Fprintf(&b, "  return request<%s>('%s', `%s?${qs}`);\n", response, method, path)
 			} else {
-				fmt.Fprintf(&b, "  return request<unknown>('%s', '%s', params as unknown as Record<string, unknown>);\n", method, path)
+				fmt.Fprintf(&b, "export async function %s(params: %s): Promise<ApiResponse<%s>> {\n", funcName, paramType, response)
+				fmt.Fprintf(&b, "  return request<%s>('%s', '%s', params as unknown as Record<string, unknown>);\n", response, method, path)
 			}
+		} else if method == "GET" && ep.PageSize > 0 {
+			// and the page's search, filters, sort, and page
+			fmt.Fprintf(&b, "export async function %s(cursor?: string, query: Record<string, string> = {}): Promise<ApiResponse<%s>> {\n", funcName, response)
+			b.WriteString("  const qs = new URLSearchParams(query);\n")
+			b.WriteString("  if (cursor) qs.set('cursor', cursor);\n")
+			b.WriteString("  const search = qs.toString();\n")
+			fmt.Fprintf(&b, "  return request<%s>('%s', search ? `%s?${search}` : '%s');\n", response, method, path, path)
 		} else {
-			fmt.Fprintf(&b, "export async function %s(): Promise<ApiResponse<unknown>> {\n", funcName)
-			fmt.Fprintf(&b, "  return request<unknown>('%s', '%s');\n", method, path)
+			fmt.Fprintf(&b, "export async function %s(): Promise<ApiResponse<%s>> {\n", funcName, response)
+			fmt.Fprintf(&b, "  return request<%s>('%s', '%s');\n", response, method, path)
 		}
 		b.WriteString("}\n")
 	}
 
+	if len(app.Notifications) > 0 {
+		writeNotificationsClient(&b)
+	}
+	if len(app.Calendars) > 0 {
+		writeCalendarClient(&b)
+	}
+	if len(app.Charts) > 0 {
+		writeChartClient(&b)
+	}
+	if len(app.Reorders) > 0 {
+		writeReorderClient(&b)
+	}
+
 	return b.String()
 }
 
@@ -114,23 +225,78 @@ func generateLayout(app *ir.Application) string {
 	var b strings.Builder
 	b.WriteString("<!-- Generated by Human compiler — do not edit -->\n")
 	b.WriteString("<script lang=\"ts\">\n")
+	if ir.Styling(app) == ir.StylingTailwind {
+		b.WriteString("  import '../tailwind.css';\n\n")
+	}
+	if len(app.Notifications) > 0 {
+		b.WriteString("  import NotificationBell from '$lib/components/NotificationBell.svelte';\n\n")
+	}
+	if app.Sitemap != nil {
+		writeCanonicalScript(&b, app.Sitemap)
+	}
 	b.WriteString("  let { children } = $props();\n")
+	collapse := collapsesNav(app)
+	if collapse {
+		b.WriteString("  let navOpen = $state(false);\n")
+	}
 	b.WriteString("</script>\n\n")
 
-	b.WriteString("<nav>\n")
+	if app.Sitemap != nil {
+		b.WriteString("<svelte:head>\n")
+		b.WriteString("  <link rel=\"canonical\" href={canonicalUrl($page.url)} />\n")
+		b.WriteString("</svelte:head>\n\n")
+	}
+
+	if len(app.Notifications) > 0 {
+		b.WriteString("<NotificationBell />\n\n")
+	}
+
+	// On phones the links collapse behind a menu button, closing once one
+	// is followed
+	link := "  <a href=\"%s\">%s</a>\n"
+	if collapse {
+		b.WriteString("<nav class=\"navbar\" class:open={navOpen}>\n")
+		b.WriteString("  <button class=\"nav-toggle\" aria-label=\"Menu\" aria-expanded={navOpen} onclick={() => (navOpen = !navOpen)}>&#9776;</button>\n")
+		b.WriteString("  <div class=\"nav-links\">\n")
+		link = "    <a href=\"%s\" onclick={() => (navOpen = false)}>%s</a>\n"
+	} else {
+		b.WriteString("<nav>\n")
+	}
 	for _, page := range app.Pages {
+		if ir.DetailRouteFor(app, page.Name) != nil {
+			continue // reached from the records it shows
+		}
 		routePath := "/"
 		if strings.ToLower(page.Name) != "home" && strings.ToLower(page.Name) != "index" {
 			routePath = "/" + toKebabCase(page.Name)
 		}
-		fmt.Fprintf(&b, "  <a href=\"%s\">%s</a>\n", routePath, page.Name)
+		fmt.Fprintf(&b, link, routePath, page.Name)
+	}
+	if collapse {
+		b.WriteString("  </div>\n")
 	}
 	b.WriteString("</nav>\n\n")
 
 	b.WriteString("<main>\n")
-	b.WriteString("  {@render children()}\n")
+	// Errors thrown while rendering a page show instead of a blank screen
+	b.WriteString("  <svelte:boundary onerror={(error) => console.error(error)}>\n")
+	b.WriteString("    {@render children()}\n\n")
+	b.WriteString("    {#snippet failed(error, reset)}\n")
+	b.WriteString("      <div class=\"error-state\" role=\"alert\">\n")
+	b.WriteString("        <h1>Something went wrong</h1>\n")
+	b.WriteString("        <p>{error instanceof Error ? error.message : String(error)}</p>\n")
+	b.WriteString("        <button onclick={reset}>Try again</button>\n")
+	b.WriteString("      </div>\n")
+	b.WriteString("    {/snippet}\n")
+	b.WriteString("  </svelte:boundary>\n")
 	b.WriteString("</main>\n")
 
+	if collapse {
+		b.WriteString("\n<style>\n")
+		b.WriteString(themes.MobileNavCSS())
+		b.WriteString("</style>\n")
+	}
+
 	return b.String()
 }
 
@@ -146,3 +312,10 @@ func generateErrorPage() string {
 </div>
 `
 }
+
+// generateEnvProduction produces .env.production for a build targeting an
+// environment: `vite build` reads it, so the built frontend calls the
+// environment's API rather than the dev server's.
+func generateEnvProduction(env *ir.Environment, app *ir.Application) string {
+	return fmt.Sprintf("# Generated by Human compiler — do not edit\n# API URL of the %s environment, read by vite build\nVITE_API_URL=%s\n", env.Name, env.APIURL(app))
+}
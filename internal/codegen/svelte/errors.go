@@ -0,0 +1,27 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+)
+
+// loadFailed returns the statement recording why a load failed: the
+// server's message, else the page's.
+func loadFailed(ctx *pageContext) string {
+	return fmt.Sprintf("loadError = errorMessage(err, '%s')", strings.ReplaceAll(ctx.loadError, "'", "\\'"))
+}
+
+// writeLoadErrorSvelte renders why loading the page's data failed, with a
+// button loading it again.
+func writeLoadErrorSvelte(b *strings.Builder, indent string, ctx *pageContext) {
+	handler := ctx.retry
+	if strings.Contains(handler, "(") {
+		handler = "() => { " + handler + "; }"
+	}
+	fmt.Fprintf(b, "%s{#if loadError}\n", indent)
+	fmt.Fprintf(b, "%s  <div class=\"error-state\" role=\"alert\">\n", indent)
+	fmt.Fprintf(b, "%s    <p>{loadError}</p>\n", indent)
+	fmt.Fprintf(b, "%s    <button onclick={%s}>Try again</button>\n", indent, handler)
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s{/if}\n", indent)
+}
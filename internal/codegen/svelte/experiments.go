@@ -0,0 +1,117 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// controlExperiment returns the experiment whose control (first variant)
+// is the given page. The control page's route serves the experiment.
+func controlExperiment(app *ir.Application, pageName string) *ir.Experiment {
+	for _, exp := range app.Experiments {
+		if len(exp.Variants) > 0 && strings.EqualFold(exp.Variants[0].Page, pageName) {
+			return exp
+		}
+	}
+	return nil
+}
+
+// variantPage returns the declared name of a variant's page, matching
+// case-insensitively as the analyzer does.
+func variantPage(app *ir.Application, name string) string {
+	for _, page := range app.Pages {
+		if strings.EqualFold(page.Name, name) {
+			return page.Name
+		}
+	}
+	return name
+}
+
+// generateExperimentsClient produces src/lib/experiments.ts, which loads
+// the visitor's variant assignments and reports exposures to the backend.
+func generateExperimentsClient() string {
+	return `// Generated by Human compiler — do not edit
+
+const API_BASE_URL = import.meta.env?.VITE_API_URL || '';
+const VISITOR_KEY = 'hx_visitor';
+
+let assignments: Promise<Record<string, string>> | null = null;
+const exposed = new Set<string>();
+
+function visitorHeaders(): Record<string, string> {
+  const id = localStorage.getItem(VISITOR_KEY);
+  return id ? { 'X-Visitor-Id': id } : {};
+}
+
+// fetchAssignments loads the visitor's variants once per page load. The
+// backend does the bucketing; keeping its visitor id makes assignments
+// sticky even when cookies do not reach a cross-origin API.
+export function fetchAssignments(): Promise<Record<string, string>> {
+  if (!assignments) {
+    assignments = fetch(` + "`${API_BASE_URL}/api/experiments`" + `, { headers: visitorHeaders() })
+      .then((res) => res.json())
+      .then(({ data }) => {
+        localStorage.setItem(VISITOR_KEY, data.visitorId);
+        return data.assignments as Record<string, string>;
+      })
+      .catch(() => ({}));
+  }
+  return assignments;
+}
+
+export async function getVariant(experiment: string): Promise<string | undefined> {
+  return (await fetchAssignments())[experiment];
+}
+
+export function logExposure(experiment: string): void {
+  if (exposed.has(experiment)) return;
+  exposed.add(experiment);
+  fetch(` + "`${API_BASE_URL}/api/experiments/${encodeURIComponent(experiment)}/exposure`" + `, {
+    method: 'POST',
+    headers: visitorHeaders(),
+  }).catch(() => {});
+}
+`
+}
+
+// generateExperimentLoad produces the control page's +page.ts. SvelteKit
+// routes are files, so variants are served by redirecting from the control
+// page's path to the assigned variant's page. Server rendering is off for
+// the control page so visitors never see a flash of the wrong variant.
+func generateExperimentLoad(app *ir.Application, exp *ir.Experiment) string {
+	var b strings.Builder
+
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { redirect } from '@sveltejs/kit';\n")
+	b.WriteString("import { getVariant, logExposure } from '$lib/experiments';\n")
+	b.WriteString("import type { PageLoad } from './$types';\n\n")
+	b.WriteString("export const ssr = false;\n\n")
+
+	b.WriteString("const variantPaths: Record<string, string> = {\n")
+	for _, v := range exp.Variants[1:] {
+		fmt.Fprintf(&b, "  %s: '%s',\n", v.Page, pagePath(variantPage(app, v.Page)))
+	}
+	b.WriteString("};\n\n")
+
+	b.WriteString("export const load: PageLoad = async () => {\n")
+	fmt.Fprintf(&b, "  const variant = await getVariant('%s');\n", exp.Name)
+	b.WriteString("  if (!variant) return;\n")
+	fmt.Fprintf(&b, "  logExposure('%s');\n", exp.Name)
+	b.WriteString("  if (variantPaths[variant]) {\n")
+	b.WriteString("    throw redirect(307, variantPaths[variant]);\n")
+	b.WriteString("  }\n")
+	b.WriteString("};\n")
+
+	return b.String()
+}
+
+// pagePath returns the URL path SvelteKit serves a page at.
+func pagePath(name string) string {
+	lower := strings.ToLower(name)
+	if lower == "home" || lower == "index" {
+		return "/"
+	}
+	return "/" + toKebabCase(name)
+}
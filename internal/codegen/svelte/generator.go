@@ -7,6 +7,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/codegen/themes"
 	"github.com/barun-bash/human/internal/ir"
 )
@@ -25,18 +26,28 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 	}
 
 	files := map[string]string{
-		filepath.Join(outputDir, "package.json"):             generatePackageJson(app),
-		filepath.Join(outputDir, "svelte.config.js"):         generateSvelteConfig(),
-		filepath.Join(outputDir, "vite.config.ts"):           generateViteConfig(),
-		filepath.Join(outputDir, "tsconfig.json"):            generateTsConfig(),
-		filepath.Join(outputDir, "src", "app.html"):          generateAppHtml(app),
-		filepath.Join(outputDir, "src", "app.d.ts"):          generateAppDts(),
-		filepath.Join(outputDir, "src", "lib", "types.ts"):   generateTypes(app),
-		filepath.Join(outputDir, "src", "lib", "api.ts"):     generateApi(app),
+		filepath.Join(outputDir, "package.json"):                    generatePackageJson(app),
+		filepath.Join(outputDir, "svelte.config.js"):                generateSvelteConfig(),
+		filepath.Join(outputDir, "vite.config.ts"):                  generateViteConfig(app),
+		filepath.Join(outputDir, "tsconfig.json"):                   generateTsConfig(),
+		filepath.Join(outputDir, "src", "app.html"):                 generateAppHtml(app),
+		filepath.Join(outputDir, "src", "app.d.ts"):                 generateAppDts(),
+		filepath.Join(outputDir, "src", "lib", "types.ts"):          generateTypes(app),
+		filepath.Join(outputDir, "src", "lib", "api.ts"):            generateApi(app),
 		filepath.Join(outputDir, "src", "routes", "+layout.svelte"): generateLayout(app),
 		filepath.Join(outputDir, "src", "routes", "+error.svelte"):  generateErrorPage(),
 	}
 
+	// The API of the environment the build targets
+	if env := ir.TargetEnvironment(app); env != nil {
+		files[filepath.Join(outputDir, ".env.production")] = generateEnvProduction(env, app)
+	}
+
+	// Numbers shown in the theme's locale
+	if ir.Locale(app) != "" {
+		files[filepath.Join(outputDir, "src", "lib", "format.ts")] = generateFormat(app)
+	}
+
 	for _, page := range app.Pages {
 		name := toKebabCase(page.Name)
 		var path string
@@ -44,6 +55,9 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 			path = filepath.Join(outputDir, "src", "routes", "+page.svelte")
 		} else {
 			dir := filepath.Join(outputDir, "src", "routes", name)
+			if r := ir.DetailRouteFor(app, page.Name); r != nil {
+				dir = filepath.Join(outputDir, "src", "routes", r.Slug, "["+r.Param+"]")
+			}
 			if err := os.MkdirAll(dir, 0755); err != nil {
 				return fmt.Errorf("creating directory %s: %w", dir, err)
 			}
@@ -64,6 +78,46 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		files[filepath.Join(outputDir, "src", "routes", "+layout.ts")] = generateLayoutGuard(app)
 	}
 
+	// Generate experiment variant routing
+	if len(app.Experiments) > 0 {
+		files[filepath.Join(outputDir, "src", "lib", "experiments.ts")] = generateExperimentsClient()
+		for _, exp := range app.Experiments {
+			if len(exp.Variants) < 2 {
+				continue
+			}
+			dir := strings.TrimPrefix(pagePath(variantPage(app, exp.Variants[0].Page)), "/")
+			files[filepath.Join(outputDir, "src", "routes", filepath.FromSlash(dir), "+page.ts")] = generateExperimentLoad(app, exp)
+		}
+	}
+
+	// Generate the in-app notifications dropdown
+	if len(app.Notifications) > 0 {
+		files[filepath.Join(outputDir, "src", "lib", "components", "NotificationBell.svelte")] = generateNotificationBell()
+	}
+
+	// Generate the "Add to calendar" button
+	if len(app.Calendars) > 0 {
+		files[filepath.Join(outputDir, "src", "lib", "components", "AddToCalendar.svelte")] = generateAddToCalendar()
+	}
+
+	// Generate the chart component for aggregate endpoints
+	if len(app.Charts) > 0 {
+		files[filepath.Join(outputDir, "src", "lib", "components", "DataChart.svelte")] = generateDataChart()
+	}
+
+	// Hover tooltips and slide-over detail panels
+	if ir.HasTooltips(app) {
+		files[filepath.Join(outputDir, "src", "lib", "components", "Tooltip.svelte")] = generateTooltip()
+	}
+	if ir.HasDetailPanels(app) {
+		files[filepath.Join(outputDir, "src", "lib", "components", "DetailPanel.svelte")] = generateDetailPanel()
+	}
+
+	// Skeleton screens shaped like the records they stand in for
+	if ir.HasSkeletons(app) {
+		files[filepath.Join(outputDir, "src", "lib", "components", "Skeleton.svelte")] = generateSkeleton(app)
+	}
+
 	// Generate theme files
 	if app.Theme != nil {
 		themeFiles := themes.GenerateSvelteTheme(app.Theme)
@@ -72,24 +126,12 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		}
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
+	// Styles and tooling of the app's styling system
+	for relPath, content := range themes.StylingFiles(ir.Styling(app), "svelte", app.Theme) {
+		files[filepath.Join(outputDir, relPath)] = content
 	}
 
-	return nil
-}
-
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 func toCamelCase(s string) string {
@@ -0,0 +1,165 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeReorderClient appends the reorder endpoint to the API client.
+func writeReorderClient(b *strings.Builder) {
+	b.WriteString(`
+// reorder saves the order a list's records were dragged into.
+export async function reorder(list: string, ids: string[]) {
+  return request<string[]>('PUT', ` + "`/api/reorder/${list}`" + `, { ids });
+}
+`)
+}
+
+// pageReorder returns the reorderable list a page's "dragging a task
+// reorders the list" drags: the page's primary model, when the page lists
+// its records.
+func pageReorder(page *ir.Page, app *ir.Application, modelName string) *ir.Reorder {
+	r := ir.ReorderFor(app, modelName)
+	if r == nil {
+		return nil
+	}
+	dragged, listed := false, false
+	for _, a := range page.Content {
+		if ir.IsReorder(a) {
+			if m := ir.ReorderModel(app, a); m != nil && m.Name == r.Model {
+				dragged = true
+			}
+		}
+		if a.Type == "loop" {
+			listed = true
+		}
+	}
+	if dragged && listed {
+		return r
+	}
+	return nil
+}
+
+// writeReorderHandlers emits the page's handlers for a dragged list.
+// svelte-dnd-action reports the order while dragging, which the page shows
+// at once; saveOrder puts the order from before the drag back if saving
+// fails.
+func writeReorderHandlers(b *strings.Builder, r *ir.Reorder, ctx *pageContext) {
+	fmt.Fprintf(b, "\n  let previousOrder: %s[] = [];\n", ctx.modelName)
+	fmt.Fprintf(b, "  function considerOrder(ev: CustomEvent<DndEvent<%s>>) {\n", ctx.modelName)
+	fmt.Fprintf(b, "    if (ev.detail.info.trigger === TRIGGERS.DRAG_STARTED) previousOrder = %s;\n", ctx.varName)
+	fmt.Fprintf(b, "    %s = ev.detail.items;\n", ctx.varName)
+	b.WriteString("  }\n")
+	fmt.Fprintf(b, "  async function saveOrder(ev: CustomEvent<DndEvent<%s>>) {\n", ctx.modelName)
+	fmt.Fprintf(b, "    %s = ev.detail.items;\n", ctx.varName)
+	b.WriteString("    try {\n")
+	fmt.Fprintf(b, "      const res = await reorder('%s', %s.map((%s) => %s.id));\n", r.Slug, ctx.varName, ctx.itemVar, ctx.itemVar)
+	fmt.Fprintf(b, "      if (res.error) %s = previousOrder;\n", ctx.varName)
+	b.WriteString("    } catch {\n")
+	fmt.Fprintf(b, "      %s = previousOrder;\n", ctx.varName)
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+}
+
+// writeInteractionNote marks where a page's drag, key binding, infinite
+// scroll, delete, tooltip, detail panel, or item link was declared; the
+// list and the listeners implement it.
+func writeInteractionNote(b *strings.Builder, a *ir.Action, indent string, ctx *pageContext) {
+	_, parsed := ir.ParseKeyBinding(a.Text)
+	switch {
+	case ir.IsReorder(a) && ctx.reorder != nil:
+		fmt.Fprintf(b, "%s<!-- %s — handled by the draggable list -->\n", indent, a.Text)
+	case ir.IsKeyBinding(a) && parsed:
+		fmt.Fprintf(b, "%s<!-- %s — handled by the keydown listener -->\n", indent, a.Text)
+	case ir.IsInfiniteScroll(a) && ctx.scroll:
+		fmt.Fprintf(b, "%s<!-- %s — handled by the load-more sentinel -->\n", indent, a.Text)
+	case ir.IsDelete(a) && ctx.deleteEp != nil:
+		fmt.Fprintf(b, "%s<!-- %s — handled by each item's delete button -->\n", indent, a.Text)
+	case tooltipWired(a, ctx):
+		fmt.Fprintf(b, "%s<!-- %s — handled by the tooltip -->\n", indent, a.Text)
+	case panelWired(a, ctx):
+		fmt.Fprintf(b, "%s<!-- %s — handled by the detail panel -->\n", indent, a.Text)
+	case a == ctx.itemLink:
+		fmt.Fprintf(b, "%s<!-- %s — handled by each item's link -->\n", indent, a.Text)
+	default:
+		fmt.Fprintf(b, "%s<!-- TODO: %s -->\n", indent, a.Text)
+	}
+}
+
+// writeKeyBindings emits the handler for a page's keyboard shortcuts,
+// attached with <svelte:window onkeydown>. Plain keys are ignored while
+// the user is typing in a field; Ctrl also matches Cmd on a Mac.
+func writeKeyBindings(b *strings.Builder, bindings []*ir.KeyBinding, ctx *pageContext) {
+	b.WriteString("\n  function onKeyDown(ev: KeyboardEvent) {\n")
+	for _, kb := range bindings {
+		if isPlainKey(kb) {
+			b.WriteString("    const target = ev.target as HTMLElement;\n")
+			b.WriteString("    const typing = target.isContentEditable || ['INPUT', 'TEXTAREA', 'SELECT'].includes(target.tagName);\n")
+			break
+		}
+	}
+	for _, kb := range bindings {
+		fmt.Fprintf(b, "    if (%s) {\n", keyCondition(kb))
+		b.WriteString("      ev.preventDefault();\n")
+		fmt.Fprintf(b, "      %s;\n", keyAction(kb, ctx))
+		b.WriteString("      return;\n")
+		b.WriteString("    }\n")
+	}
+	b.WriteString("  }\n")
+}
+
+// keyCondition returns the JavaScript test matching a key binding's key
+// and modifiers on a KeyboardEvent named ev.
+func keyCondition(kb *ir.KeyBinding) string {
+	var parts []string
+	if isPlainKey(kb) {
+		parts = append(parts, "!typing")
+	}
+	if len([]rune(kb.Key)) == 1 && kb.Key != " " {
+		parts = append(parts, fmt.Sprintf("ev.key.toLowerCase() === '%s'", kb.Key))
+	} else {
+		parts = append(parts, fmt.Sprintf("ev.key === '%s'", kb.Key))
+	}
+	if kb.Ctrl {
+		parts = append(parts, "(ev.ctrlKey || ev.metaKey)")
+	} else {
+		parts = append(parts, "!ev.ctrlKey && !ev.metaKey")
+	}
+	if kb.Alt {
+		parts = append(parts, "ev.altKey")
+	} else {
+		parts = append(parts, "!ev.altKey")
+	}
+	// Symbols like ? already need Shift on most layouts.
+	if kb.Shift {
+		parts = append(parts, "ev.shiftKey")
+	}
+	return strings.Join(parts, " && ")
+}
+
+// isPlainKey reports whether a key binding would fire while typing: no Ctrl
+// or Alt, and not Escape, which should close things from anywhere.
+func isPlainKey(kb *ir.KeyBinding) bool {
+	return !kb.Ctrl && !kb.Alt && kb.Key != "Escape"
+}
+
+// keyAction returns the statement a key binding runs.
+func keyAction(kb *ir.KeyBinding, ctx *pageContext) string {
+	switch kb.Action {
+	case "close":
+		if ctx.needsFormState {
+			return "showForm = false"
+		}
+		return "document.querySelector<HTMLElement>('.modal-close')?.click()"
+	case "open-form":
+		return "showForm = true"
+	case "navigate":
+		return fmt.Sprintf("goto('%s')", pagePath(kb.Target))
+	case "focus-search":
+		return "document.querySelector<HTMLInputElement>('input[type=\"search\"]')?.focus()"
+	default: // submit
+		return "document.querySelector<HTMLFormElement>('form')?.requestSubmit()"
+	}
+}
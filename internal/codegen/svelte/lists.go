@@ -0,0 +1,172 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// pageScrolls reports whether a page loads the next page of its list as
+// the user nears the end: it says "scrolling to bottom loads more posts"
+// of the model it lists, and the endpoint it lists them from paginates.
+func pageScrolls(page *ir.Page, app *ir.Application, modelName string, listEp *ir.Endpoint) bool {
+	if listEp == nil || listEp.PageSize == 0 || len(listEp.Params) > 0 {
+		return false
+	}
+	for _, a := range page.Content {
+		if ir.IsInfiniteScroll(a) {
+			if m := ir.ScrollModel(app, a); m != nil && m.Name == modelName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pageDelete returns the endpoint and button label of a page's
+// `clicking "Delete" deletes the task`, when the page lists tasks and a
+// delete endpoint takes just the task's id.
+func pageDelete(page *ir.Page, app *ir.Application, modelName string) (*ir.Endpoint, string) {
+	if modelName == "" {
+		return nil, ""
+	}
+	for _, a := range page.Content {
+		if !ir.IsDelete(a) || !strings.Contains(strings.ToLower(a.Text), strings.ToLower(modelName)) {
+			continue
+		}
+		ep := findDeleteEndpoint(app, modelName)
+		if ep == nil || len(ep.Params) != 1 || !strings.HasSuffix(strings.ToLower(ep.Params[0].Name), "id") {
+			return nil, ""
+		}
+		return ep, ir.DeleteLabel(a)
+	}
+	return nil, ""
+}
+
+// findDeleteEndpoint finds a delete-type API endpoint matching the model.
+func findDeleteEndpoint(app *ir.Application, modelName string) *ir.Endpoint {
+	lowerModel := strings.ToLower(modelName)
+	for _, ep := range app.APIs {
+		lower := strings.ToLower(ep.Name)
+		if strings.HasPrefix(lower, "delete") && strings.Contains(lower, lowerModel) {
+			return ep
+		}
+	}
+	return nil
+}
+
+// writeLoadMore emits the effect of an infinitely scrolling list. An
+// IntersectionObserver watches a sentinel below the list and fetches the
+// page after nextCursor when it comes into view; the effect runs again
+// after each page, so a short page that leaves the sentinel in view loads
+// the next one too. A failed fetch stops loading more.
+func writeLoadMore(b *strings.Builder, listEp *ir.Endpoint, ctx *pageContext) {
+	b.WriteString("\n  $effect(() => {\n")
+	b.WriteString("    const el = sentinel;\n")
+	b.WriteString("    const cursor = nextCursor;\n")
+	b.WriteString("    if (!el || !cursor || loadingMore) return;\n")
+	b.WriteString("    const observer = new IntersectionObserver(async ([entry]) => {\n")
+	b.WriteString("      if (!entry.isIntersecting) return;\n")
+	b.WriteString("      observer.disconnect();\n")
+	b.WriteString("      loadingMore = true;\n")
+	b.WriteString("      try {\n")
+	fmt.Fprintf(b, "        const res = await %s(cursor);\n", toCamelCase(listEp.Name))
+	fmt.Fprintf(b, "        %s = [...%s, ...((res.data as %s[]) ?? [])];\n", ctx.varName, ctx.varName, ctx.modelName)
+	b.WriteString("        nextCursor = res.pagination?.nextCursor ?? null;\n")
+	b.WriteString("      } catch {\n")
+	b.WriteString("        nextCursor = null;\n")
+	b.WriteString("      } finally {\n")
+	b.WriteString("        loadingMore = false;\n")
+	b.WriteString("      }\n")
+	b.WriteString("    }, { rootMargin: '200px' });\n")
+	b.WriteString("    observer.observe(el);\n")
+	b.WriteString("    return () => observer.disconnect();\n")
+	b.WriteString("  });\n")
+}
+
+// writeListEnd emits what follows the page's list: the sentinel loading
+// more of a scrolling list, or the controls paging a paginated one unless
+// the page places them itself.
+func writeListEnd(b *strings.Builder, indent string, page *ir.Page, ctx *pageContext) {
+	switch {
+	case ctx.scroll:
+		writeLoadMoreSentinel(b, indent)
+	case ctx.query != nil && ctx.query.Paged && !ir.ShowsPagination(page):
+		writePaginationSvelte(b, indent)
+	}
+}
+
+// writeLoadMoreSentinel emits the element below a scrolling list whose
+// coming into view loads the next page.
+func writeLoadMoreSentinel(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<div bind:this={sentinel} class=\"load-more\">\n", indent)
+	fmt.Fprintf(b, "%s  {#if loadingMore}<div class=\"spinner\"></div>{/if}\n", indent)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
+
+// writeDeleteHandler emits the page's handler for an item's Delete button:
+// it removes the item at once and puts the list back if deleting fails.
+func writeDeleteHandler(b *strings.Builder, ep *ir.Endpoint, ctx *pageContext) {
+	item := ctx.itemVar
+	fmt.Fprintf(b, "\n  async function handleDelete(%s: %s) {\n", item, ctx.modelName)
+	fmt.Fprintf(b, "    const previous = %s;\n", ctx.varName)
+	fmt.Fprintf(b, "    %s = %s.filter((other) => other.id !== %s.id);\n", ctx.varName, ctx.varName, item)
+	b.WriteString("    try {\n")
+	fmt.Fprintf(b, "      const res = await %s({ %s: %s.id });\n", toCamelCase(ep.Name), toCamelCase(ep.Params[0].Name), item)
+	fmt.Fprintf(b, "      if (res.error) %s = previous;\n", ctx.varName)
+	b.WriteString("    } catch {\n")
+	fmt.Fprintf(b, "      %s = previous;\n", ctx.varName)
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+}
+
+// writeDeleteButton emits a list item's delete button. When clicking the
+// item opens a detail panel or another page, clicking the button doesn't.
+func writeDeleteButton(b *strings.Builder, indent, item string, ctx *pageContext) {
+	if ctx.panel != nil || ctx.itemPage != "" {
+		fmt.Fprintf(b, "%s<button class=\"delete-button\" onclick={(e) => { e.stopPropagation(); handleDelete(%s); }}>%s</button>\n", indent, item, ctx.deleteLabel)
+		return
+	}
+	fmt.Fprintf(b, "%s<button class=\"delete-button\" onclick={() => handleDelete(%s)}>%s</button>\n", indent, item, ctx.deleteLabel)
+}
+
+// writeOptimisticCreate emits the body of a create form's submit handler
+// that shows the new record in the list at once, under a temporary id,
+// swaps in the saved record when the API answers, and takes it out again
+// if saving fails. Paginated lists show their newest records first, so the
+// record goes at the top of those.
+func writeOptimisticCreate(b *strings.Builder, createFunc string, fields []string, ctx *pageContext) {
+	list := ctx.varName
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = toCamelCase(f)
+	}
+	fmt.Fprintf(b, "    const values = { %s };\n", strings.Join(names, ", "))
+	b.WriteString("    const tempId = `temp-${Date.now()}`;\n")
+	pending := fmt.Sprintf("{ ...values, id: tempId } as unknown as %s", ctx.modelName)
+	if ctx.newestFirst {
+		fmt.Fprintf(b, "    %s = [%s, ...%s];\n", list, pending, list)
+	} else {
+		fmt.Fprintf(b, "    %s = [...%s, %s];\n", list, list, pending)
+	}
+	b.WriteString("    showForm = false;\n")
+	for _, name := range names {
+		fmt.Fprintf(b, "    %s = '';\n", name)
+	}
+	b.WriteString("    try {\n")
+	fmt.Fprintf(b, "      const res = await %s(values);\n", createFunc)
+	b.WriteString("      if (res.error) throw new Error(res.error);\n")
+	fmt.Fprintf(b, "      %s = %s.map((other) => (other.id === tempId ? res.data as %s : other));\n", list, list, ctx.modelName)
+	if ctx.hasSuccessState {
+		b.WriteString("      success = 'Created successfully';\n")
+	}
+	b.WriteString("    } catch (err) {\n")
+	fmt.Fprintf(b, "      %s = %s.filter((other) => other.id !== tempId);\n", list, list)
+	if ctx.hasErrorState {
+		b.WriteString("      error = err instanceof Error ? err.message : 'Something went wrong';\n")
+	} else {
+		b.WriteString("      console.error(err);\n")
+	}
+	b.WriteString("    }\n")
+}
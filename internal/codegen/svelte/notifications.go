@@ -0,0 +1,120 @@
+package svelte
+
+import "strings"
+
+// writeNotificationsClient appends the in-app notification endpoints to the
+// API client.
+func writeNotificationsClient(b *strings.Builder) {
+	b.WriteString(`
+export async function listNotifications(): Promise<ApiResponse<Notification[]>> {
+  return request<Notification[]>('GET', '/api/notifications');
+}
+
+export async function getUnreadNotificationCount(): Promise<ApiResponse<{ count: number }>> {
+  return request<{ count: number }>('GET', '/api/notifications/unread-count');
+}
+
+export async function markNotificationRead(id: string): Promise<ApiResponse<Notification>> {
+  return request<Notification>('POST', ` + "`/api/notifications/${encodeURIComponent(id)}/read`" + `);
+}
+
+export async function markAllNotificationsRead(): Promise<ApiResponse<{ count: number }>> {
+  return request<{ count: number }>('POST', '/api/notifications/read-all');
+}
+`)
+}
+
+// generateNotificationBell produces src/lib/components/NotificationBell.svelte:
+// a dropdown of the signed-in user's notifications. The unread badge polls
+// the backend every 30 seconds once mounted in the browser.
+func generateNotificationBell() string {
+	return `<!-- Generated by Human compiler — do not edit -->
+<script lang="ts">
+  import { onMount } from 'svelte';
+  import type { Notification } from '$lib/types';
+  import {
+    getUnreadNotificationCount,
+    listNotifications,
+    markAllNotificationsRead,
+    markNotificationRead,
+  } from '$lib/api';
+
+  const POLL_INTERVAL_MS = 30_000;
+
+  let open = $state(false);
+  let unread = $state(0);
+  let notifications = $state<Notification[]>([]);
+  let signedIn = $state(false);
+
+  // Polling also notices signing in and out, since the token lives in localStorage.
+  function refreshCount() {
+    signedIn = !!localStorage.getItem('token');
+    if (!signedIn) return;
+    getUnreadNotificationCount()
+      .then((res) => (unread = res.data?.count ?? 0))
+      .catch(() => {});
+  }
+
+  onMount(() => {
+    refreshCount();
+    const timer = setInterval(refreshCount, POLL_INTERVAL_MS);
+    return () => clearInterval(timer);
+  });
+
+  function toggle() {
+    open = !open;
+    if (!open) return;
+    listNotifications()
+      .then((res) => (notifications = res.data ?? []))
+      .catch(() => {});
+  }
+
+  async function markRead(notification: Notification) {
+    if (notification.seen) return;
+    await markNotificationRead(notification.id);
+    notification.seen = new Date().toISOString();
+    unread = Math.max(0, unread - 1);
+  }
+
+  async function markAllRead() {
+    await markAllNotificationsRead();
+    const now = new Date().toISOString();
+    notifications.forEach((n) => (n.seen = n.seen ?? now));
+    unread = 0;
+  }
+</script>
+
+{#if signedIn}
+  <div class="bell">
+    <button type="button" aria-label="Notifications" aria-expanded={open} onclick={toggle}>
+      🔔{#if unread > 0}<span class="badge">{unread}</span>{/if}
+    </button>
+    {#if open}
+      <div class="menu" role="menu">
+        <div class="header">
+          <strong>Notifications</strong>
+          {#if unread > 0}<button type="button" onclick={markAllRead}>Mark all read</button>{/if}
+        </div>
+        {#each notifications as n (n.id)}
+          <div class="item" class:unread={!n.seen} role="menuitem" tabindex="-1" onclick={() => markRead(n)} onkeydown={(e) => e.key === 'Enter' && markRead(n)}>
+            {n.message}
+          </div>
+        {:else}
+          <p class="empty">No notifications yet</p>
+        {/each}
+      </div>
+    {/if}
+  </div>
+{/if}
+
+<style>
+  .bell { position: fixed; top: 1rem; right: 1rem; z-index: 1000; }
+  .badge { margin-left: 0.25rem; font-weight: bold; }
+  .menu { position: absolute; right: 0; margin-top: 0.5rem; width: 20rem; max-height: 24rem; overflow-y: auto; background: white; border: 1px solid #e5e7eb; border-radius: 0.5rem; box-shadow: 0 4px 12px rgba(0, 0, 0, 0.1); }
+  .header { display: flex; justify-content: space-between; padding: 0.5rem 0.75rem; border-bottom: 1px solid #e5e7eb; }
+  .item { padding: 0.75rem; cursor: pointer; border-bottom: 1px solid #f3f4f6; }
+  .item.unread { font-weight: bold; }
+  .empty { padding: 0.75rem; color: #6b7280; }
+</style>
+`
+}
@@ -0,0 +1,210 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateTooltip produces src/lib/components/Tooltip.svelte: a wrapper
+// that shows its text above its children while they are hovered or
+// focused. Escape hides it without moving the pointer away.
+func generateTooltip() string {
+	return `<!-- Generated by Human compiler — do not edit -->
+<script lang="ts">
+  import type { Snippet } from 'svelte';
+
+  let { text, children }: { text?: string | number | null; children: Snippet } = $props();
+
+  let open = $state(false);
+  const id = ` + "`tooltip-${Math.random().toString(36).slice(2, 9)}`" + `;
+  let shown = $derived(open && text !== null && text !== undefined && text !== '');
+</script>
+
+<!-- svelte-ignore a11y_no_noninteractive_tabindex, a11y_no_static_element_interactions -->
+<span
+  class="tooltip-anchor"
+  style="position: relative; display: inline-block"
+  tabindex="0"
+  aria-describedby={shown ? id : undefined}
+  onmouseenter={() => open = true}
+  onmouseleave={() => open = false}
+  onfocusin={() => open = true}
+  onfocusout={() => open = false}
+  onkeydown={(e) => { if (e.key === 'Escape') open = false; }}
+>
+  {@render children()}
+  {#if shown}
+    <span
+      {id}
+      role="tooltip"
+      class="tooltip"
+      style="position: absolute; bottom: 100%; left: 50%; transform: translateX(-50%); margin-bottom: 6px; padding: 4px 8px; border-radius: 4px; background: #1f2937; color: #fff; font-size: 12px; white-space: nowrap; pointer-events: none; z-index: 1000"
+    >{text}</span>
+  {/if}
+</span>
+`
+}
+
+// generateDetailPanel produces src/lib/components/DetailPanel.svelte: a
+// panel that slides over the page from one side, closed by its close
+// button, a click outside it, or Escape.
+func generateDetailPanel() string {
+	return `<!-- Generated by Human compiler — do not edit -->
+<script lang="ts">
+  import type { Snippet } from 'svelte';
+
+  let { title, side = 'right', onclose, children }: {
+    title: string;
+    side?: 'left' | 'right';
+    onclose: () => void;
+    children: Snippet;
+  } = $props();
+
+  function onKeyDown(e: KeyboardEvent) {
+    if (e.key === 'Escape') onclose();
+  }
+</script>
+
+<svelte:window onkeydown={onKeyDown} />
+
+<!-- svelte-ignore a11y_click_events_have_key_events, a11y_no_static_element_interactions -->
+<div
+  class="detail-panel-overlay"
+  style="position: fixed; inset: 0; background: rgba(0, 0, 0, 0.3); z-index: 1000"
+  onclick={onclose}
+>
+  <!-- svelte-ignore a11y_click_events_have_key_events, a11y_no_noninteractive_element_interactions -->
+  <aside
+    role="dialog"
+    aria-modal="true"
+    aria-label={title}
+    class="detail-panel"
+    style="position: fixed; top: 0; bottom: 0; {side}: 0; width: min(420px, 100%); overflow-y: auto; padding: 24px; background: #fff; box-shadow: 0 0 24px rgba(0, 0, 0, 0.2)"
+    onclick={(e) => e.stopPropagation()}
+  >
+    <header style="display: flex; justify-content: space-between; align-items: center; margin-bottom: 16px">
+      <h2 style="margin: 0">{title}</h2>
+      <button type="button" class="detail-panel-close" aria-label="Close" onclick={onclose}>&times;</button>
+    </header>
+    {@render children()}
+  </aside>
+</div>
+`
+}
+
+// pageTooltips returns the tooltips a page shows over the records it
+// lists: "hovering over a task title shows its description".
+func pageTooltips(page *ir.Page, app *ir.Application, modelName string) []*ir.Tooltip {
+	if modelName == "" {
+		return nil
+	}
+	var tips []*ir.Tooltip
+	for _, a := range page.Content {
+		if t := ir.TooltipFor(app, a, modelName); t != nil && t.Model != nil && t.Model.Name == modelName {
+			tips = append(tips, t)
+		}
+	}
+	return tips
+}
+
+// componentTooltips returns the tooltip a component shows over itself, as
+// a one-element slice, and the prop holding the record it shows a field of.
+func componentTooltips(comp *ir.Component, app *ir.Application) ([]*ir.Tooltip, string) {
+	for _, p := range comp.Props {
+		if !isDataModel(p.Type, app) {
+			continue
+		}
+		for _, a := range comp.Content {
+			if t := ir.TooltipFor(app, a, p.Type); t != nil && t.Model != nil && t.Model.Name == p.Type {
+				return []*ir.Tooltip{t}, p.Name
+			}
+		}
+		return nil, ""
+	}
+	return nil, ""
+}
+
+// pagePanel returns the detail panel a page's "clicking a task opens a
+// detail panel" opens on the records it lists, or nil.
+func pagePanel(page *ir.Page, app *ir.Application, modelName string) *ir.DetailPanel {
+	if modelName == "" {
+		return nil
+	}
+	for _, a := range page.Content {
+		if p := ir.DetailPanelFor(app, a); p != nil && p.Model.Name == modelName {
+			return p
+		}
+	}
+	return nil
+}
+
+// tooltipWired reports whether a tooltip interaction is one the page or
+// component renders.
+func tooltipWired(a *ir.Action, ctx *pageContext) bool {
+	for _, w := range ctx.tooltips {
+		if t := ir.TooltipFor(ctx.app, a, w.Model.Name); t != nil && *t == *w {
+			return true
+		}
+	}
+	return false
+}
+
+// panelWired reports whether a detail panel interaction opens the page's
+// panel.
+func panelWired(a *ir.Action, ctx *pageContext) bool {
+	p := ir.DetailPanelFor(ctx.app, a)
+	return p != nil && ctx.panel != nil && p.Model == ctx.panel.Model
+}
+
+// tooltipOn returns the tooltip shown over a field of each listed record,
+// or over the whole record when field is "".
+func tooltipOn(field string, ctx *pageContext) *ir.Tooltip {
+	for _, t := range ctx.tooltips {
+		if t.Target == nil && field == "" || t.Target != nil && t.Target.Name == field {
+			return t
+		}
+	}
+	return nil
+}
+
+// tooltipText returns the attribute giving a tooltip's text for the record
+// named item.
+func tooltipText(t *ir.Tooltip, item string) string {
+	if t.Field != nil {
+		return fmt.Sprintf("text={%s.%s}", item, t.Field.Name)
+	}
+	return fmt.Sprintf("text=\"%s\"", strings.ReplaceAll(t.Text, `"`, "&quot;"))
+}
+
+// panelClick returns the attributes making a listed record open the detail
+// panel when clicked or when Enter is pressed on it, if the page has one.
+func panelClick(item string, ctx *pageContext) string {
+	if ctx.panel == nil {
+		return ""
+	}
+	return fmt.Sprintf(" role=\"button\" tabindex=\"0\" onclick={() => selected = %s} onkeydown={(e) => { if (e.key === 'Enter' && e.target === e.currentTarget) selected = %s; }}", item, item)
+}
+
+// writePanelSvelte renders the detail panel of the selected record,
+// listing the panel's fields.
+func writePanelSvelte(b *strings.Builder, indent string, ctx *pageContext) {
+	p := ctx.panel
+	fmt.Fprintf(b, "%s{#if selected}\n", indent)
+	fmt.Fprintf(b, "%s  <DetailPanel title=\"%s\" side=\"%s\" onclose={() => selected = null}>\n", indent, ir.FieldLabel(p.Model.Name), p.Side)
+	fmt.Fprintf(b, "%s    <dl class=\"detail-fields\">\n", indent)
+	for _, f := range p.Fields {
+		fmt.Fprintf(b, "%s      <dt>%s</dt>\n", indent, ir.FieldLabel(f.Name))
+		if f.Type == "boolean" {
+			fmt.Fprintf(b, "%s      <dd>{selected.%s ? 'Yes' : 'No'}</dd>\n", indent, f.Name)
+		} else if t := timeSvelte("selected."+f.Name, f, ctx); t != "" {
+			fmt.Fprintf(b, "%s      <dd>%s</dd>\n", indent, t)
+		} else {
+			fmt.Fprintf(b, "%s      <dd>{selected.%s}</dd>\n", indent, f.Name)
+		}
+	}
+	fmt.Fprintf(b, "%s    </dl>\n", indent)
+	fmt.Fprintf(b, "%s  </DetailPanel>\n", indent)
+	fmt.Fprintf(b, "%s{/if}\n", indent)
+}
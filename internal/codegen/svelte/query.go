@@ -0,0 +1,171 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeListQueryScript declares the view of the page's list its URL holds:
+// the search, each filter, the sort, and the page, read from the page's
+// query string and written back to it, so the view can be shared,
+// bookmarked, and gone back to. Changing the search, a filter, or the sort
+// goes back to the first page. A list the browser narrows itself gets the
+// records matching the view.
+func writeListQueryScript(b *strings.Builder, ctx *pageContext) {
+	q := ctx.query
+	if q.Search {
+		b.WriteString("  const search = $derived($page.url.searchParams.get('q') ?? '');\n")
+	}
+	for _, f := range q.Filters {
+		fmt.Fprintf(b, "  const %s = $derived($page.url.searchParams.get('%s') ?? '');\n", filterVar(f), f.Name)
+	}
+	if len(q.Sortable) > 0 {
+		var keys []string
+		for _, f := range q.Sortable {
+			keys = append(keys, "'"+f.Name+"'")
+		}
+		initial, desc := "null", "false"
+		if q.SortBy != nil {
+			initial = "'" + q.SortBy.Name + "'"
+			desc = fmt.Sprintf("!$page.url.searchParams.has('sort') && %t", q.Desc)
+		}
+		fmt.Fprintf(b, "  const sortKey = $derived(([%s] as const).find((key) => key === $page.url.searchParams.get('sort')) ?? %s);\n", strings.Join(keys, ", "), initial)
+		fmt.Fprintf(b, "  const sortDesc = $derived($page.url.searchParams.has('order') ? $page.url.searchParams.get('order') === 'desc' : %s);\n", desc)
+	}
+	if q.Paged {
+		b.WriteString("  const pageNumber = $derived(Math.max(Number($page.url.searchParams.get('page')) || 1, 1));\n")
+		b.WriteString("  let hasNext = $state(false);\n")
+	}
+	b.WriteString("  function setParam(name: string, value: string) {\n")
+	b.WriteString("    const next = new URLSearchParams($page.url.searchParams);\n")
+	b.WriteString("    if (value) next.set(name, value);\n")
+	b.WriteString("    else next.delete(name);\n")
+	b.WriteString("    if (name !== 'page') next.delete('page');\n")
+	b.WriteString("    goto(`?${next}`, { keepFocus: true, noScroll: true });\n")
+	b.WriteString("  }\n")
+	if len(q.Sortable) > 0 {
+		b.WriteString("  function sortBy(key: string) {\n")
+		b.WriteString("    const next = new URLSearchParams($page.url.searchParams);\n")
+		b.WriteString("    next.set('sort', key);\n")
+		b.WriteString("    next.set('order', key === sortKey && !sortDesc ? 'desc' : 'asc');\n")
+		b.WriteString("    next.delete('page');\n")
+		b.WriteString("    goto(`?${next}`, { keepFocus: true, noScroll: true });\n")
+		b.WriteString("  }\n")
+	}
+	if q.Paged {
+		writeListQuery(b, q)
+	} else {
+		writeVisibleRows(b, ctx)
+	}
+}
+
+// writeListQuery declares the query a paginated list is fetched with: its
+// search, filters, sort, and page. Loading it again as it changes follows
+// from reading it in load.
+func writeListQuery(b *strings.Builder, q *ir.ListQuery) {
+	b.WriteString("  const listQuery = $derived.by(() => {\n")
+	b.WriteString("    const query: Record<string, string> = {};\n")
+	if q.Search {
+		b.WriteString("    if (search) query.q = search;\n")
+	}
+	for _, f := range q.Filters {
+		fmt.Fprintf(b, "    if (%s) query.%s = %s;\n", filterVar(f), f.Name, filterVar(f))
+	}
+	if len(q.Sortable) > 0 {
+		b.WriteString("    if (sortKey) {\n")
+		b.WriteString("      query.sort = sortKey;\n")
+		b.WriteString("      query.order = sortDesc ? 'desc' : 'asc';\n")
+		b.WriteString("    }\n")
+	}
+	b.WriteString("    if (pageNumber > 1) query.page = String(pageNumber);\n")
+	b.WriteString("    return query;\n")
+	b.WriteString("  });\n")
+}
+
+// writeVisibleRows declares the records of a list loaded whole that match
+// the search and filters, in the order picked.
+func writeVisibleRows(b *strings.Builder, ctx *pageContext) {
+	q, item := ctx.query, ctx.itemVar
+	var conds []string
+	for _, f := range q.Filters {
+		conds = append(conds, fmt.Sprintf("(!%s || String(%s.%s) === %s)", filterVar(f), item, f.Name, filterVar(f)))
+	}
+	if q.Search {
+		conds = append(conds, fmt.Sprintf("(!search || Object.values(%s).some((value) => String(value ?? '').toLowerCase().includes(search.toLowerCase())))", item))
+	}
+	fmt.Fprintf(b, "  const %s = $derived.by(() => {\n", rowsVar(ctx))
+	if len(conds) > 0 {
+		fmt.Fprintf(b, "    const rows = %s.filter((%s) =>\n", ctx.varName, item)
+		fmt.Fprintf(b, "      %s,\n", strings.Join(conds, " &&\n      "))
+		b.WriteString("    );\n")
+	} else {
+		fmt.Fprintf(b, "    const rows = [...%s];\n", ctx.varName)
+	}
+	if len(q.Sortable) > 0 {
+		b.WriteString("    const key = sortKey;\n")
+		b.WriteString("    if (key) {\n")
+		b.WriteString("      rows.sort((a, b) => {\n")
+		b.WriteString("        const order = String(a[key] ?? '').localeCompare(String(b[key] ?? ''), undefined, { numeric: true });\n")
+		b.WriteString("        return sortDesc ? -order : order;\n")
+		b.WriteString("      });\n")
+		b.WriteString("    }\n")
+	}
+	b.WriteString("    return rows;\n")
+	b.WriteString("  });\n")
+}
+
+// rowsVar returns the records the page's list renders: the ones matching
+// its view, or all it loaded when the API pages them.
+func rowsVar(ctx *pageContext) string {
+	if ctx.query == nil || ctx.query.Paged {
+		return ctx.varName
+	}
+	return "visible" + capitalize(ctx.varName)
+}
+
+// filterVar returns the name of the value a list is filtered by f to.
+func filterVar(f *ir.DataField) string {
+	return toCamelCase(f.Name) + "Filter"
+}
+
+// filterOptions returns the values and labels of a filter's options.
+func filterOptions(f *ir.DataField) [][2]string {
+	if f.Type == "boolean" {
+		return [][2]string{{"true", "Yes"}, {"false", "No"}}
+	}
+	var opts [][2]string
+	for _, v := range f.EnumValues {
+		opts = append(opts, [2]string{v, capitalize(v)})
+	}
+	return opts
+}
+
+// writeSearchSvelte renders the search input of a list, kept in the URL.
+func writeSearchSvelte(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" aria-label=\"Search\" class=\"search-input\" value={search} oninput={(ev) => setParam('q', ev.currentTarget.value)} />\n", indent)
+}
+
+// writeFilterSvelte renders the dropdown filtering a list by f, kept in
+// the URL.
+func writeFilterSvelte(b *strings.Builder, indent string, f *ir.DataField) {
+	label := ir.FieldLabel(f.Name)
+	fmt.Fprintf(b, "%s<select class=\"filter-select\" aria-label=\"Filter by %s\" value={%s} onchange={(ev) => setParam('%s', ev.currentTarget.value)}>\n",
+		indent, strings.ToLower(label), filterVar(f), f.Name)
+	fmt.Fprintf(b, "%s  <option value=\"\">All</option>\n", indent)
+	for _, o := range filterOptions(f) {
+		fmt.Fprintf(b, "%s  <option value=\"%s\">%s</option>\n", indent, o[0], o[1])
+	}
+	fmt.Fprintf(b, "%s</select>\n", indent)
+}
+
+// writePaginationSvelte renders the controls moving between the pages of a
+// paginated list, kept in the URL.
+func writePaginationSvelte(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<nav class=\"pagination\" aria-label=\"Pagination\">\n", indent)
+	fmt.Fprintf(b, "%s  <button class=\"page-btn\" disabled={pageNumber <= 1} onclick={() => setParam('page', pageNumber > 2 ? String(pageNumber - 1) : '')}>&laquo; Prev</button>\n", indent)
+	fmt.Fprintf(b, "%s  <span class=\"page-number\" aria-current=\"page\">{pageNumber}</span>\n", indent)
+	fmt.Fprintf(b, "%s  <button class=\"page-btn\" disabled={!hasNext} onclick={() => setParam('page', String(pageNumber + 1))}>Next &raquo;</button>\n", indent)
+	fmt.Fprintf(b, "%s</nav>\n", indent)
+}
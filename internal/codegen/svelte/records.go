@@ -0,0 +1,88 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// recordURL returns the expression for the URL of a record on page:
+// `/posts/${post.id}` for a detail route, else the page with an id query.
+func recordURL(page, item string, ctx *pageContext) string {
+	prefix, field := ir.RecordLink(ctx.app, page)
+	return fmt.Sprintf("`%s${%s.%s}`", prefix, item, field)
+}
+
+// itemClick returns the attributes making a listed record open the detail
+// panel, or navigate to the page its list links to, when clicked or when
+// Enter is pressed on it.
+func itemClick(item string, ctx *pageContext) string {
+	if ctx.panel != nil || ctx.itemPage == "" {
+		return panelClick(item, ctx)
+	}
+	url := recordURL(ctx.itemPage, item, ctx)
+	return fmt.Sprintf(" role=\"link\" tabindex=\"0\" onclick={() => goto(%s)} onkeydown={(e) => { if (e.key === 'Enter' && e.target === e.currentTarget) goto(%s); }}", url, url)
+}
+
+// writeRecordScript declares the record a detail route's page shows and
+// loads it whenever the URL's value changes: undefined while loading, null
+// when there is no such record.
+func writeRecordScript(b *strings.Builder, ctx *pageContext) {
+	r := ctx.record
+	v := strings.ToLower(r.Model[:1]) + r.Model[1:]
+	fmt.Fprintf(b, "  let %s = $state<%s | null | undefined>(undefined);\n", v, r.Model)
+	fmt.Fprintf(b, "\n  function load%s() {\n", r.Model)
+	fmt.Fprintf(b, "    const value = $page.params.%s;\n", r.Param)
+	fmt.Fprintf(b, "    %s = undefined;\n", v)
+	b.WriteString("    loadError = '';\n")
+	fmt.Fprintf(b, "    %s({ %s: value })\n", toCamelCase(r.Endpoint), r.Arg)
+	fmt.Fprintf(b, "      .then(res => { %s = (res.data as %s) ?? null; })\n", v, r.Model)
+	b.WriteString("      .catch(err => {\n")
+	fmt.Fprintf(b, "        if (err instanceof ApiError && err.status === 404) %s = null;\n", v)
+	fmt.Fprintf(b, "        else %s;\n", loadFailed(ctx))
+	b.WriteString("      });\n")
+	b.WriteString("  }\n")
+	b.WriteString("\n  $effect(() => {\n")
+	fmt.Fprintf(b, "    load%s();\n", r.Model)
+	b.WriteString("  });\n")
+}
+
+// writeRecordSvelte renders the record a detail route's page shows: its
+// title as the heading and its other fields, or a not-found message.
+func writeRecordSvelte(b *strings.Builder, indent string, ctx *pageContext) {
+	r := ctx.record
+	v := strings.ToLower(r.Model[:1]) + r.Model[1:]
+	m := findModel(ctx.app, r.Model)
+	fmt.Fprintf(b, "%s{#if %s === undefined && !loadError}\n", indent, v)
+	fmt.Fprintf(b, "%s  <div class=\"loading-spinner\">\n", indent)
+	fmt.Fprintf(b, "%s    <div class=\"spinner\"></div>\n", indent)
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s{:else if %s === null}\n", indent, v)
+	fmt.Fprintf(b, "%s  <div class=\"empty-state\">%s not found</div>\n", indent, ir.FieldLabel(r.Model))
+	fmt.Fprintf(b, "%s{:else}\n", indent)
+	fmt.Fprintf(b, "%s  <article class=\"%s-record\">\n", indent, toKebabCase(r.Model))
+	title := ir.RecordTitle(m)
+	if title != nil {
+		fmt.Fprintf(b, "%s    <h1>{%s.%s}</h1>\n", indent, v, title.Name)
+	} else {
+		fmt.Fprintf(b, "%s    <h1>%s</h1>\n", indent, ir.FieldLabel(r.Model))
+	}
+	fmt.Fprintf(b, "%s    <dl class=\"detail-fields\">\n", indent)
+	for _, f := range ir.RecordFields(m) {
+		if f == title {
+			continue
+		}
+		fmt.Fprintf(b, "%s      <dt>%s</dt>\n", indent, ir.FieldLabel(f.Name))
+		if f.Type == "boolean" {
+			fmt.Fprintf(b, "%s      <dd>{%s.%s ? 'Yes' : 'No'}</dd>\n", indent, v, f.Name)
+		} else if t := timeSvelte(v+"."+f.Name, f, ctx); t != "" {
+			fmt.Fprintf(b, "%s      <dd>%s</dd>\n", indent, t)
+		} else {
+			fmt.Fprintf(b, "%s      <dd>{%s.%s}</dd>\n", indent, v, f.Name)
+		}
+	}
+	fmt.Fprintf(b, "%s    </dl>\n", indent)
+	fmt.Fprintf(b, "%s  </article>\n", indent)
+	fmt.Fprintf(b, "%s{/if}\n", indent)
+}
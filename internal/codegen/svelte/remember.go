@@ -0,0 +1,64 @@
+package svelte
+
+import "strings"
+
+// writeRememberScript keeps the view of a page's list for when the user
+// comes back: leaving the page saves its query and how far down it was
+// scrolled to sessionStorage, and opening it restores them, the query
+// unless its URL names a view of its own, and the scroll once the list has
+// loaded. A restored query replaces the page's entry in the history
+// rather than adding one.
+func writeRememberScript(b *strings.Builder, ctx *pageContext) {
+	b.WriteString("\n  // Coming back to the page restores the list as the user left it\n")
+	b.WriteString("  beforeNavigate(({ from, to }) => {\n")
+	b.WriteString("    if (!from || from.url.pathname === to?.url.pathname) return;\n")
+	if ctx.query != nil {
+		b.WriteString("    sessionStorage.setItem(`view:${from.url.pathname}`, from.url.searchParams.toString());\n")
+	}
+	b.WriteString("    sessionStorage.setItem(`scroll:${from.url.pathname}`, String(window.scrollY));\n")
+	b.WriteString("  });\n")
+	if ctx.query != nil {
+		b.WriteString("\n  // Opens the page on the view of its list the user left, when its URL\n")
+		b.WriteString("  // doesn't name one; true when it goes there.\n")
+		b.WriteString("  function restoreView() {\n")
+		b.WriteString("    const saved = sessionStorage.getItem(`view:${$page.url.pathname}`);\n")
+		b.WriteString("    if ($page.url.search || !saved) return false;\n")
+		b.WriteString("    goto(`?${saved}`, { replaceState: true, keepFocus: true, noScroll: true });\n")
+		b.WriteString("    return true;\n")
+		b.WriteString("  }\n")
+	}
+	b.WriteString("\n  let scrollRestored = false;\n")
+	b.WriteString("  $effect(() => {\n")
+	b.WriteString("    if (loading || scrollRestored) return;\n")
+	b.WriteString("    scrollRestored = true;\n")
+	b.WriteString("    const y = Number(sessionStorage.getItem(`scroll:${location.pathname}`));\n")
+	b.WriteString("    if (y) window.scrollTo(0, y);\n")
+	b.WriteString("  });\n")
+}
+
+// writeRememberLoad emits the effect loading a remembered list. A paginated
+// list restoring its view waits to load until the page is on it; any other
+// list loads while the view is restored.
+func writeRememberLoad(b *strings.Builder, ctx *pageContext) {
+	if ctx.query == nil {
+		b.WriteString("\n  $effect(() => {\n")
+		b.WriteString("    load();\n")
+		b.WriteString("  });\n")
+		return
+	}
+	if !ctx.query.Paged {
+		b.WriteString("\n  onMount(restoreView);\n")
+		b.WriteString("  $effect(() => {\n")
+		b.WriteString("    load();\n")
+		b.WriteString("  });\n")
+		return
+	}
+	b.WriteString("\n  let viewRestored = false;\n")
+	b.WriteString("  $effect(() => {\n")
+	b.WriteString("    if (!viewRestored) {\n")
+	b.WriteString("      viewRestored = true;\n")
+	b.WriteString("      if (restoreView()) return;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    load();\n")
+	b.WriteString("  });\n")
+}
@@ -0,0 +1,34 @@
+package svelte
+
+import (
+	"github.com/barun-bash/human/internal/codegen/themes"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// responsiveStyles returns the styles of a page declaring a responsive
+// layout, laying out the records it lists or its table.
+func responsiveStyles(page *ir.Page, ctx *pageContext) string {
+	l := themes.ResponsiveLayout{
+		Page:       toKebabCase(page.Name) + "-page",
+		Table:      ctx.table != nil,
+		Responsive: ctx.responsive,
+	}
+	if ctx.modelName != "" && ctx.table == nil {
+		l.Item = toKebabCase(ctx.modelName) + "-item"
+		for _, f := range ctx.itemFields {
+			l.Shapes = append(l.Shapes, skeletonShape(f))
+		}
+	}
+	return themes.ResponsiveCSS(ctx.app.Theme, l)
+}
+
+// collapsesNav reports whether the layout's navigation collapses into a
+// menu on phones: whether any page adapts to them.
+func collapsesNav(app *ir.Application) bool {
+	for _, p := range app.Pages {
+		if r := ir.ResponsiveFor(p); r != nil && r.Mobile {
+			return true
+		}
+	}
+	return false
+}
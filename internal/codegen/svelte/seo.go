@@ -0,0 +1,24 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeCanonicalScript adds the layout's canonical URL: the site's origin
+// plus the current path. VITE_SITE_URL overrides the declared production
+// URL; a record's ?id stays part of the URL, as in the sitemap.
+func writeCanonicalScript(b *strings.Builder, sm *ir.Sitemap) {
+	b.WriteString("  import { page } from '$app/stores';\n\n")
+	b.WriteString("  function canonicalUrl(url: URL): string {\n")
+	if sm.URL != "" {
+		fmt.Fprintf(b, "    const origin = (import.meta.env.VITE_SITE_URL || '%s').replace(/\\/$/, '');\n", sm.URL)
+	} else {
+		b.WriteString("    const origin = (import.meta.env.VITE_SITE_URL || url.origin).replace(/\\/$/, '');\n")
+	}
+	b.WriteString("    const id = url.searchParams.get('id');\n")
+	b.WriteString("    return origin + url.pathname + (id ? `?id=${encodeURIComponent(id)}` : '');\n")
+	b.WriteString("  }\n\n")
+}
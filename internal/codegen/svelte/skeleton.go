@@ -0,0 +1,94 @@
+package svelte
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/themes"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateSkeleton produces src/lib/components/Skeleton.svelte: a
+// placeholder bar shaped like the heading, line of text, date, or badge it
+// stands in for, themed from the app's theme.
+func generateSkeleton(app *ir.Application) string {
+	return `<!-- Generated by Human compiler — do not edit -->
+<script lang="ts">
+  let { shape = 'text' }: { shape?: 'heading' | 'text' | 'date' | 'badge' } = $props();
+</script>
+
+<span class="skeleton skeleton-{shape}" aria-hidden="true"></span>
+
+<style>
+` + themes.SkeletonCSS(app.Theme) + `</style>
+`
+}
+
+// skeletonShape returns the Skeleton shape standing in for a listed field,
+// matching the element the list renders it as.
+func skeletonShape(field string) string {
+	switch {
+	case field == "status" || field == "role" || field == "priority":
+		return "badge"
+	case field == "title" || field == "name":
+		return "heading"
+	case strings.Contains(field, "date") || field == "due" || field == "created":
+		return "date"
+	}
+	return "text"
+}
+
+// writeSkeletonSvelte renders the page's skeleton screen while it loads:
+// rows with a placeholder per column when it shows a table, else items
+// shaped like the ones it lists.
+func writeSkeletonSvelte(b *strings.Builder, indent string, ctx *pageContext) {
+	fmt.Fprintf(b, "%s{#if loading}\n", indent)
+	if ctx.table != nil {
+		fmt.Fprintf(b, "%s  <table class=\"data-table skeleton-screen\" aria-busy=\"true\">\n", indent)
+		fmt.Fprintf(b, "%s    <tbody>\n", indent)
+		fmt.Fprintf(b, "%s      {#each [1, 2, 3] as i (i)}\n", indent)
+		fmt.Fprintf(b, "%s        <tr>\n", indent)
+		for _, f := range ctx.table.Columns {
+			fmt.Fprintf(b, "%s          <td><Skeleton shape=\"%s\" /></td>\n", indent, skeletonShape(f.Name))
+		}
+		fmt.Fprintf(b, "%s        </tr>\n", indent)
+		fmt.Fprintf(b, "%s      {/each}\n", indent)
+		fmt.Fprintf(b, "%s    </tbody>\n", indent)
+		fmt.Fprintf(b, "%s  </table>\n", indent)
+		fmt.Fprintf(b, "%s{/if}\n", indent)
+		return
+	}
+
+	modelClass := toKebabCase(ctx.modelName)
+	if modelClass == "" {
+		modelClass = "item"
+	}
+	shapes := []string{"heading", "text"}
+	if len(ctx.itemFields) > 0 {
+		shapes = nil
+		for _, f := range ctx.itemFields {
+			shapes = append(shapes, skeletonShape(f))
+		}
+	}
+	fmt.Fprintf(b, "%s  <div class=\"skeleton-screen\" aria-busy=\"true\">\n", indent)
+	fmt.Fprintf(b, "%s    {#each [1, 2, 3] as i (i)}\n", indent)
+	fmt.Fprintf(b, "%s      <div class=\"%s-item\">\n", indent, modelClass)
+	for _, s := range shapes {
+		fmt.Fprintf(b, "%s        <Skeleton shape=\"%s\" />\n", indent, s)
+	}
+	fmt.Fprintf(b, "%s      </div>\n", indent)
+	fmt.Fprintf(b, "%s    {/each}\n", indent)
+	fmt.Fprintf(b, "%s  </div>\n", indent)
+	fmt.Fprintf(b, "%s{/if}\n", indent)
+}
+
+// pageHasSkeleton reports whether a page shows a skeleton screen while it
+// loads.
+func pageHasSkeleton(page *ir.Page) bool {
+	for _, a := range page.Content {
+		if ir.IsSkeleton(a) {
+			return true
+		}
+	}
+	return false
+}
@@ -20,7 +20,7 @@ func generatePackageJson(app *ir.Application) string {
 		"@sveltejs/adapter-auto":       "^3.0.0",
 		"@sveltejs/kit":                "^2.0.0",
 		"@sveltejs/vite-plugin-svelte": "^4.0.0",
-		"svelte":                       "^5.0.0",
+		"svelte":                       "^5.3.0",
 		"svelte-check":                 "^4.0.0",
 		"tslib":                        "^2.4.1",
 		"typescript":                   "^5.0.0",
@@ -39,6 +39,27 @@ func generatePackageJson(app *ir.Application) string {
 		}
 	}
 
+	// Styling system packages
+	stylingDeps, stylingDevDeps := themes.StylingDependencies(ir.Styling(app))
+	for k, v := range stylingDeps {
+		deps[k] = v
+	}
+	for k, v := range stylingDevDeps {
+		devDeps[k] = v
+	}
+
+	// Self-hosted theme fonts (offline builds)
+	for k, v := range themes.FontDependencies(app.Theme) {
+		deps[k] = v
+	}
+
+	if len(app.Charts) > 0 {
+		deps["chart.js"] = "^4.4.0"
+	}
+	if len(app.Reorders) > 0 {
+		deps["svelte-dnd-action"] = "^0.9.50"
+	}
+
 	// Storybook dependencies
 	for k, v := range storybook.DevDependencies("svelte") {
 		devDeps[k] = v
@@ -89,12 +110,18 @@ export default config;
 `
 }
 
-func generateViteConfig() string {
+// generateViteConfig produces vite.config.ts. A build targeting an
+// environment lets the dev and preview servers answer on its host.
+func generateViteConfig(app *ir.Application) string {
+	hosts := ""
+	if env := ir.TargetEnvironment(app); env != nil {
+		hosts = fmt.Sprintf(",\n\tserver: { allowedHosts: ['%[1]s'] },\n\tpreview: { allowedHosts: ['%[1]s'] }", env.Host())
+	}
 	return `import { sveltekit } from '@sveltejs/kit/vite';
 import { defineConfig } from 'vite';
 
 export default defineConfig({
-	plugins: [sveltekit()]
+	plugins: [sveltekit()]` + hosts + `
 });
 `
 }
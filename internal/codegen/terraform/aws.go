@@ -36,7 +36,7 @@ func generateAWSECS(app *ir.Application) string {
 	// CloudWatch Log Group
 	b.WriteString("resource \"aws_cloudwatch_log_group\" \"app\" {\n")
 	b.WriteString(fmt.Sprintf("  name              = \"/ecs/%s-${var.environment}\"\n", name))
-	b.WriteString("  retention_in_days = 30\n")
+	fmt.Fprintf(&b, "  retention_in_days = %d\n", cloudWatchRetention(ir.LogRetentionDays(app)))
 	b.WriteString("}\n\n")
 
 	// IAM Role for ECS Task Execution
@@ -184,12 +184,75 @@ func generateAWSRDS(app *ir.Application) string {
 	b.WriteString("  db_subnet_group_name   = aws_db_subnet_group.main.name\n\n")
 	b.WriteString("  skip_final_snapshot = true\n")
 	b.WriteString("  multi_az            = var.environment == \"production\" ? true : false\n\n")
-	b.WriteString("  backup_retention_period = var.environment == \"production\" ? 7 : 1\n")
+	if profile := ir.Compliance(app); profile != nil {
+		// RDS keeps automated backups for at most 35 days; the pg_dump
+		// backups cover the rest of the profile's retention.
+		fmt.Fprintf(&b, "  backup_retention_period = %d\n", min(profile.BackupRetentionDays, 35))
+		b.WriteString("  storage_encrypted       = true\n")
+	} else {
+		b.WriteString("  backup_retention_period = var.environment == \"production\" ? 7 : 1\n")
+	}
 	b.WriteString("}\n")
 
 	return b.String()
 }
 
+// cloudWatchRetentionDays are the retention periods CloudWatch Logs accepts.
+var cloudWatchRetentionDays = []int{1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653}
+
+// cloudWatchRetention rounds days up to a retention CloudWatch accepts.
+func cloudWatchRetention(days int) int {
+	for _, d := range cloudWatchRetentionDays {
+		if d >= days {
+			return d
+		}
+	}
+	return cloudWatchRetentionDays[len(cloudWatchRetentionDays)-1]
+}
+
+// writeAccessLogBucket writes the S3 bucket the load balancer delivers its
+// access logs to, expiring them after the retention period.
+func writeAccessLogBucket(b *strings.Builder, name string, days int) {
+	b.WriteString("data \"aws_elb_service_account\" \"main\" {}\n\n")
+
+	b.WriteString("resource \"aws_s3_bucket\" \"access_logs\" {\n")
+	fmt.Fprintf(b, "  bucket = \"%s-access-logs-${var.environment}\"\n", name)
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"aws_s3_bucket_public_access_block\" \"access_logs\" {\n")
+	b.WriteString("  bucket                  = aws_s3_bucket.access_logs.id\n")
+	b.WriteString("  block_public_acls       = true\n")
+	b.WriteString("  block_public_policy     = true\n")
+	b.WriteString("  ignore_public_acls      = true\n")
+	b.WriteString("  restrict_public_buckets = true\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"aws_s3_bucket_lifecycle_configuration\" \"access_logs\" {\n")
+	b.WriteString("  bucket = aws_s3_bucket.access_logs.id\n\n")
+	b.WriteString("  rule {\n")
+	b.WriteString("    id     = \"expire\"\n")
+	b.WriteString("    status = \"Enabled\"\n")
+	b.WriteString("    filter {}\n\n")
+	b.WriteString("    expiration {\n")
+	fmt.Fprintf(b, "      days = %d\n", days)
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"aws_s3_bucket_policy\" \"access_logs\" {\n")
+	b.WriteString("  bucket = aws_s3_bucket.access_logs.id\n")
+	b.WriteString("  policy = jsonencode({\n")
+	b.WriteString("    Version = \"2012-10-17\"\n")
+	b.WriteString("    Statement = [{\n")
+	b.WriteString("      Effect    = \"Allow\"\n")
+	b.WriteString("      Principal = { AWS = data.aws_elb_service_account.main.arn }\n")
+	b.WriteString("      Action    = \"s3:PutObject\"\n")
+	b.WriteString("      Resource  = \"${aws_s3_bucket.access_logs.arn}/*\"\n")
+	b.WriteString("    }]\n")
+	b.WriteString("  })\n")
+	b.WriteString("}\n\n")
+}
+
 // ── AWS Networking (VPC, Subnets, ALB) ──
 
 func generateAWSNetworking(app *ir.Application) string {
@@ -329,7 +392,17 @@ func generateAWSNetworking(app *ir.Application) string {
 	b.WriteString("  load_balancer_type = \"application\"\n")
 	b.WriteString("  security_groups    = [aws_security_group.alb.id]\n")
 	b.WriteString("  subnets            = aws_subnet.public[*].id\n")
+	if ir.Compliance(app) != nil {
+		b.WriteString("\n  access_logs {\n")
+		b.WriteString("    bucket  = aws_s3_bucket.access_logs.id\n")
+		b.WriteString("    enabled = true\n")
+		b.WriteString("  }\n\n")
+		b.WriteString("  depends_on = [aws_s3_bucket_policy.access_logs]\n")
+	}
 	b.WriteString("}\n\n")
+	if ir.Compliance(app) != nil {
+		writeAccessLogBucket(&b, name, ir.LogRetentionDays(app))
+	}
 
 	b.WriteString("resource \"aws_lb_target_group\" \"app\" {\n")
 	b.WriteString(fmt.Sprintf("  name        = \"%s-${var.environment}\"\n", name))
@@ -349,9 +422,33 @@ func generateAWSNetworking(app *ir.Application) string {
 	b.WriteString("  load_balancer_arn = aws_lb.main.arn\n")
 	b.WriteString("  port             = 80\n")
 	b.WriteString("  protocol         = \"HTTP\"\n\n")
-	b.WriteString("  default_action {\n")
-	b.WriteString("    type             = \"forward\"\n")
-	b.WriteString("    target_group_arn = aws_lb_target_group.app.arn\n")
+	if !hasDomain(app) {
+		b.WriteString("  default_action {\n")
+		b.WriteString("    type             = \"forward\"\n")
+		b.WriteString("    target_group_arn = aws_lb_target_group.app.arn\n")
+		b.WriteString("  }\n")
+		b.WriteString("}\n")
+		return b.String()
+	}
+
+	// With a domain, plain HTTP redirects to the HTTPS listener in aws_dns.tf.
+	b.WriteString("  dynamic \"default_action\" {\n")
+	b.WriteString("    for_each = var.domain_name == \"\" ? [1] : []\n")
+	b.WriteString("    content {\n")
+	b.WriteString("      type             = \"forward\"\n")
+	b.WriteString("      target_group_arn = aws_lb_target_group.app.arn\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  dynamic \"default_action\" {\n")
+	b.WriteString("    for_each = var.domain_name == \"\" ? [] : [1]\n")
+	b.WriteString("    content {\n")
+	b.WriteString("      type = \"redirect\"\n\n")
+	b.WriteString("      redirect {\n")
+	b.WriteString("        port        = \"443\"\n")
+	b.WriteString("        protocol    = \"HTTPS\"\n")
+	b.WriteString("        status_code = \"HTTP_301\"\n")
+	b.WriteString("      }\n")
+	b.WriteString("    }\n")
 	b.WriteString("  }\n")
 	b.WriteString("}\n")
 
@@ -387,10 +484,51 @@ func generateAWSCDN(app *ir.Application) string {
 	b.WriteString("  signing_protocol                  = \"sigv4\"\n")
 	b.WriteString("}\n\n")
 
+	// Cache and response header policies
+	for _, p := range []struct{ id, ttl, cacheControl string }{
+		{"html", "0", ir.RevalidateCacheControl},
+		{"assets", "31536000", ir.ImmutableCacheControl},
+	} {
+		b.WriteString(fmt.Sprintf("resource \"aws_cloudfront_cache_policy\" \"%s\" {\n", p.id))
+		b.WriteString(fmt.Sprintf("  name        = \"%s-%s-${var.environment}\"\n", name, p.id))
+		b.WriteString("  min_ttl     = 0\n")
+		b.WriteString(fmt.Sprintf("  default_ttl = %s\n", p.ttl))
+		b.WriteString("  max_ttl     = 31536000\n\n")
+		b.WriteString("  parameters_in_cache_key_and_forwarded_to_origin {\n")
+		b.WriteString("    enable_accept_encoding_brotli = true\n")
+		b.WriteString("    enable_accept_encoding_gzip   = true\n\n")
+		b.WriteString("    cookies_config {\n")
+		b.WriteString("      cookie_behavior = \"none\"\n")
+		b.WriteString("    }\n")
+		b.WriteString("    headers_config {\n")
+		b.WriteString("      header_behavior = \"none\"\n")
+		b.WriteString("    }\n")
+		b.WriteString("    query_strings_config {\n")
+		b.WriteString("      query_string_behavior = \"none\"\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n")
+		b.WriteString("}\n\n")
+
+		b.WriteString(fmt.Sprintf("resource \"aws_cloudfront_response_headers_policy\" \"%s\" {\n", p.id))
+		b.WriteString(fmt.Sprintf("  name = \"%s-%s-${var.environment}\"\n\n", name, p.id))
+		b.WriteString("  custom_headers_config {\n")
+		b.WriteString("    items {\n")
+		b.WriteString("      header   = \"Cache-Control\"\n")
+		b.WriteString(fmt.Sprintf("      value    = \"%s\"\n", p.cacheControl))
+		b.WriteString("      override = true\n")
+		b.WriteString("    }\n")
+		b.WriteString("  }\n")
+		b.WriteString("}\n\n")
+	}
+
 	// CloudFront distribution
 	b.WriteString("resource \"aws_cloudfront_distribution\" \"frontend\" {\n")
 	b.WriteString("  enabled             = true\n")
-	b.WriteString("  default_root_object = \"index.html\"\n\n")
+	b.WriteString("  default_root_object = \"index.html\"\n")
+	if hasDomain(app) {
+		b.WriteString("  aliases             = var.domain_name == \"\" ? [] : [var.domain_name]\n")
+	}
+	b.WriteString("\n")
 
 	b.WriteString("  origin {\n")
 	b.WriteString("    domain_name              = aws_s3_bucket.frontend.bucket_regional_domain_name\n")
@@ -398,20 +536,19 @@ func generateAWSCDN(app *ir.Application) string {
 	b.WriteString("    origin_access_control_id = aws_cloudfront_origin_access_control.frontend.id\n")
 	b.WriteString("  }\n\n")
 
+	// index.html and other unhashed files are revalidated on every visit
 	b.WriteString("  default_cache_behavior {\n")
-	b.WriteString(fmt.Sprintf("    target_origin_id       = \"%s-s3\"\n", name))
-	b.WriteString("    viewer_protocol_policy = \"redirect-to-https\"\n")
-	b.WriteString("    allowed_methods        = [\"GET\", \"HEAD\"]\n")
-	b.WriteString("    cached_methods         = [\"GET\", \"HEAD\"]\n")
-	b.WriteString("    compress               = true\n\n")
-	b.WriteString("    forwarded_values {\n")
-	b.WriteString("      query_string = false\n")
-	b.WriteString("      cookies {\n")
-	b.WriteString("        forward = \"none\"\n")
-	b.WriteString("      }\n")
-	b.WriteString("    }\n")
+	writeCacheBehavior(&b, name, "html")
 	b.WriteString("  }\n\n")
 
+	// Content-hashed build output is cached for a year
+	for _, pattern := range ir.HashedAssetPaths(app) {
+		b.WriteString("  ordered_cache_behavior {\n")
+		b.WriteString(fmt.Sprintf("    path_pattern               = \"%s\"\n", pattern))
+		writeCacheBehavior(&b, name, "assets")
+		b.WriteString("  }\n\n")
+	}
+
 	// SPA fallback
 	b.WriteString("  custom_error_response {\n")
 	b.WriteString("    error_code         = 404\n")
@@ -426,7 +563,15 @@ func generateAWSCDN(app *ir.Application) string {
 	b.WriteString("  }\n\n")
 
 	b.WriteString("  viewer_certificate {\n")
-	b.WriteString("    cloudfront_default_certificate = true\n")
+	if hasDomain(app) {
+		// The domain's certificate and record are in aws_dns.tf.
+		b.WriteString("    cloudfront_default_certificate = var.domain_name == \"\"\n")
+		b.WriteString("    acm_certificate_arn            = var.domain_name == \"\" ? null : aws_acm_certificate_validation.cdn[0].certificate_arn\n")
+		b.WriteString("    ssl_support_method             = var.domain_name == \"\" ? null : \"sni-only\"\n")
+		b.WriteString("    minimum_protocol_version       = var.domain_name == \"\" ? null : \"TLSv1.2_2021\"\n")
+	} else {
+		b.WriteString("    cloudfront_default_certificate = true\n")
+	}
 	b.WriteString("  }\n")
 	b.WriteString("}\n\n")
 
@@ -451,3 +596,118 @@ func generateAWSCDN(app *ir.Application) string {
 
 	return b.String()
 }
+
+// writeCacheBehavior writes the body of a CloudFront cache behavior that
+// uses the given cache and response headers policies.
+func writeCacheBehavior(b *strings.Builder, name, policy string) {
+	b.WriteString(fmt.Sprintf("    target_origin_id           = \"%s-s3\"\n", name))
+	b.WriteString("    viewer_protocol_policy     = \"redirect-to-https\"\n")
+	b.WriteString("    allowed_methods            = [\"GET\", \"HEAD\"]\n")
+	b.WriteString("    cached_methods             = [\"GET\", \"HEAD\"]\n")
+	b.WriteString("    compress                   = true\n")
+	b.WriteString(fmt.Sprintf("    cache_policy_id            = aws_cloudfront_cache_policy.%s.id\n", policy))
+	b.WriteString(fmt.Sprintf("    response_headers_policy_id = aws_cloudfront_response_headers_policy.%s.id\n", policy))
+}
+
+// ── AWS Route 53 + ACM for the environment URL ──
+
+// generateAWSDNS points domain_name at the app with certificates that ACM
+// validates through Route 53. With a frontend, the domain serves
+// CloudFront and api.<domain> the load balancer; without one, the domain
+// serves the load balancer. Every resource is skipped when domain_name is
+// empty.
+func generateAWSDNS(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by Human compiler — AWS Route 53 + ACM\n\n")
+
+	apiDomain := "var.domain_name"
+	if hasFrontend(app) {
+		apiDomain = "\"api.${var.domain_name}\""
+	}
+	b.WriteString("locals {\n")
+	b.WriteString(fmt.Sprintf("  api_domain = %s\n", apiDomain))
+	b.WriteString("}\n\n")
+
+	b.WriteString("data \"aws_route53_zone\" \"main\" {\n")
+	b.WriteString(fmt.Sprintf("  count        = %s\n", domainCount))
+	b.WriteString("  name         = var.dns_zone\n")
+	b.WriteString("  private_zone = false\n")
+	b.WriteString("}\n\n")
+
+	// Load balancer certificate and HTTPS listener
+	writeACMCertificate(&b, "api", "local.api_domain", "")
+
+	b.WriteString("resource \"aws_lb_listener\" \"https\" {\n")
+	b.WriteString(fmt.Sprintf("  count             = %s\n", domainCount))
+	b.WriteString("  load_balancer_arn = aws_lb.main.arn\n")
+	b.WriteString("  port              = 443\n")
+	b.WriteString("  protocol          = \"HTTPS\"\n")
+	b.WriteString("  ssl_policy        = \"ELBSecurityPolicy-TLS13-1-2-2021-06\"\n")
+	b.WriteString("  certificate_arn   = aws_acm_certificate_validation.api[0].certificate_arn\n\n")
+	b.WriteString("  default_action {\n")
+	b.WriteString("    type             = \"forward\"\n")
+	b.WriteString("    target_group_arn = aws_lb_target_group.app.arn\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+
+	writeAliasRecord(&b, "api", "local.api_domain", "aws_lb.main.dns_name", "aws_lb.main.zone_id", true)
+
+	if hasFrontend(app) {
+		// CloudFront only uses certificates from us-east-1.
+		writeACMCertificate(&b, "cdn", "var.domain_name", "aws.us_east_1")
+		writeAliasRecord(&b, "cdn", "var.domain_name", "aws_cloudfront_distribution.frontend.domain_name", "aws_cloudfront_distribution.frontend.hosted_zone_id", false)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// writeACMCertificate writes a DNS-validated ACM certificate for domain,
+// its Route 53 validation records, and the validation that waits for it.
+func writeACMCertificate(b *strings.Builder, id, domain, provider string) {
+	b.WriteString(fmt.Sprintf("resource \"aws_acm_certificate\" \"%s\" {\n", id))
+	if provider != "" {
+		b.WriteString(fmt.Sprintf("  provider          = %s\n", provider))
+	}
+	b.WriteString(fmt.Sprintf("  count             = %s\n", domainCount))
+	b.WriteString(fmt.Sprintf("  domain_name       = %s\n", domain))
+	b.WriteString("  validation_method = \"DNS\"\n\n")
+	b.WriteString("  lifecycle {\n")
+	b.WriteString("    create_before_destroy = true\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(fmt.Sprintf("resource \"aws_route53_record\" \"%s_validation\" {\n", id))
+	b.WriteString(fmt.Sprintf("  for_each = { for o in flatten(aws_acm_certificate.%s[*].domain_validation_options) : o.domain_name => o }\n\n", id))
+	b.WriteString("  zone_id         = data.aws_route53_zone.main[0].zone_id\n")
+	b.WriteString("  name            = each.value.resource_record_name\n")
+	b.WriteString("  type            = each.value.resource_record_type\n")
+	b.WriteString("  records         = [each.value.resource_record_value]\n")
+	b.WriteString("  ttl             = 60\n")
+	b.WriteString("  allow_overwrite = true\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(fmt.Sprintf("resource \"aws_acm_certificate_validation\" \"%s\" {\n", id))
+	if provider != "" {
+		b.WriteString(fmt.Sprintf("  provider                = %s\n", provider))
+	}
+	b.WriteString(fmt.Sprintf("  count                   = %s\n", domainCount))
+	b.WriteString(fmt.Sprintf("  certificate_arn         = aws_acm_certificate.%s[0].arn\n", id))
+	b.WriteString(fmt.Sprintf("  validation_record_fqdns = [for r in aws_route53_record.%s_validation : r.fqdn]\n", id))
+	b.WriteString("}\n\n")
+}
+
+// writeAliasRecord writes a Route 53 alias A record for domain.
+func writeAliasRecord(b *strings.Builder, id, domain, target, zoneID string, health bool) {
+	b.WriteString(fmt.Sprintf("resource \"aws_route53_record\" \"%s\" {\n", id))
+	b.WriteString(fmt.Sprintf("  count   = %s\n", domainCount))
+	b.WriteString("  zone_id = data.aws_route53_zone.main[0].zone_id\n")
+	b.WriteString(fmt.Sprintf("  name    = %s\n", domain))
+	b.WriteString("  type    = \"A\"\n\n")
+	b.WriteString("  alias {\n")
+	b.WriteString(fmt.Sprintf("    name                   = %s\n", target))
+	b.WriteString(fmt.Sprintf("    zone_id                = %s\n", zoneID))
+	b.WriteString(fmt.Sprintf("    evaluate_target_health = %t\n", health))
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+}
@@ -98,6 +98,17 @@ func generateGCPCloudRun(app *ir.Application) string {
 	b.WriteString("  member   = \"allUsers\"\n")
 	b.WriteString("}\n")
 
+	// Cloud Run request and container logs land in the _Default bucket,
+	// which keeps them for 30 days unless told otherwise.
+	if days := ir.LogRetentionDays(app); days != ir.DefaultLogRetentionDays {
+		b.WriteString("\nresource \"google_logging_project_bucket_config\" \"default\" {\n")
+		b.WriteString("  project        = var.gcp_project_id\n")
+		b.WriteString("  location       = \"global\"\n")
+		b.WriteString("  bucket_id      = \"_Default\"\n")
+		fmt.Fprintf(&b, "  retention_days = %d\n", days)
+		b.WriteString("}\n")
+	}
+
 	return b.String()
 }
 
@@ -188,6 +199,176 @@ func generateGCPCDN(app *ir.Application) string {
 	b.WriteString("  bucket = google_storage_bucket.frontend.name\n")
 	b.WriteString("  role   = \"roles/storage.objectViewer\"\n")
 	b.WriteString("  member = \"allUsers\"\n")
+	b.WriteString("}\n\n")
+
+	// Cloud CDN backends over the same bucket: index.html and other
+	// unhashed files are revalidated by browsers, content-hashed build
+	// output is cached for a year.
+	b.WriteString("resource \"google_compute_backend_bucket\" \"frontend\" {\n")
+	b.WriteString(fmt.Sprintf("  name        = \"%s-frontend-${var.environment}\"\n", name))
+	b.WriteString("  bucket_name = google_storage_bucket.frontend.name\n")
+	b.WriteString("  enable_cdn  = true\n\n")
+	b.WriteString("  cdn_policy {\n")
+	b.WriteString("    cache_mode  = \"FORCE_CACHE_ALL\"\n")
+	b.WriteString("    default_ttl = 60\n")
+	b.WriteString("    client_ttl  = 0\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"google_compute_backend_bucket\" \"frontend_assets\" {\n")
+	b.WriteString(fmt.Sprintf("  name        = \"%s-frontend-assets-${var.environment}\"\n", name))
+	b.WriteString("  bucket_name = google_storage_bucket.frontend.name\n")
+	b.WriteString("  enable_cdn  = true\n\n")
+	b.WriteString("  cdn_policy {\n")
+	b.WriteString("    cache_mode  = \"FORCE_CACHE_ALL\"\n")
+	b.WriteString("    default_ttl = 31536000\n")
+	b.WriteString("    client_ttl  = 31536000\n")
+	b.WriteString("  }\n\n")
+	b.WriteString(fmt.Sprintf("  custom_response_headers = [\"Cache-Control: %s\"]\n", ir.ImmutableCacheControl))
+	b.WriteString("}\n\n")
+
+	// URL path rules only match prefixes, so Angular's root bundles stay
+	// on the revalidated backend; their hashed names still bust caches.
+	var paths []string
+	for _, pattern := range ir.HashedAssetPaths(app) {
+		if strings.HasPrefix(pattern, "/") {
+			paths = append(paths, fmt.Sprintf("%q", pattern))
+		}
+	}
+	b.WriteString("resource \"google_compute_url_map\" \"frontend\" {\n")
+	b.WriteString(fmt.Sprintf("  name            = \"%s-frontend-${var.environment}\"\n", name))
+	b.WriteString("  default_service = google_compute_backend_bucket.frontend.id\n\n")
+	b.WriteString("  host_rule {\n")
+	b.WriteString("    hosts        = [\"*\"]\n")
+	b.WriteString("    path_matcher = \"frontend\"\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  path_matcher {\n")
+	b.WriteString("    name            = \"frontend\"\n")
+	b.WriteString("    default_service = google_compute_backend_bucket.frontend.id\n\n")
+	b.WriteString("    path_rule {\n")
+	b.WriteString(fmt.Sprintf("      paths   = [%s]\n", strings.Join(paths, ", ")))
+	b.WriteString("      service = google_compute_backend_bucket.frontend_assets.id\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+
+	// With a domain, plain HTTP redirects to the HTTPS proxy in gcp_dns.tf.
+	urlMap := "google_compute_url_map.frontend.id"
+	if hasDomain(app) {
+		urlMap = "var.domain_name == \"\" ? google_compute_url_map.frontend.id : google_compute_url_map.https_redirect[0].id"
+	}
+	b.WriteString("resource \"google_compute_target_http_proxy\" \"frontend\" {\n")
+	b.WriteString(fmt.Sprintf("  name    = \"%s-frontend-${var.environment}\"\n", name))
+	b.WriteString(fmt.Sprintf("  url_map = %s\n", urlMap))
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"google_compute_global_address\" \"frontend\" {\n")
+	b.WriteString(fmt.Sprintf("  name = \"%s-frontend-${var.environment}\"\n", name))
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"google_compute_global_forwarding_rule\" \"frontend\" {\n")
+	b.WriteString(fmt.Sprintf("  name       = \"%s-frontend-${var.environment}\"\n", name))
+	b.WriteString("  target     = google_compute_target_http_proxy.frontend.id\n")
+	b.WriteString("  ip_address = google_compute_global_address.frontend.id\n")
+	b.WriteString("  port_range = \"80\"\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// ── GCP Cloud DNS + managed certificates for the environment URL ──
+
+// generateGCPDNS points domain_name at the app. With a frontend, the
+// domain serves the Cloud CDN load balancer, with a Google-managed
+// certificate, and api.<domain> is mapped to Cloud Run; without one, the
+// domain itself is mapped to Cloud Run, which provisions its certificate.
+// Every resource is skipped when domain_name is empty.
+func generateGCPDNS(app *ir.Application) string {
+	var b strings.Builder
+	name := appNameLower(app)
+
+	b.WriteString("# Generated by Human compiler — GCP Cloud DNS + managed certificates\n\n")
+
+	apiDomain := "var.domain_name"
+	if hasFrontend(app) {
+		apiDomain = "\"api.${var.domain_name}\""
+	}
+	b.WriteString("locals {\n")
+	b.WriteString(fmt.Sprintf("  api_domain = %s\n", apiDomain))
+	b.WriteString("}\n\n")
+
+	b.WriteString("data \"google_dns_managed_zone\" \"main\" {\n")
+	b.WriteString(fmt.Sprintf("  count = %s\n", domainCount))
+	b.WriteString("  name  = var.dns_zone\n")
+	b.WriteString("}\n\n")
+
+	// Cloud Run domain mapping. A subdomain resolves through a CNAME; an
+	// apex domain needs the A records listed in the mapping's status.
+	b.WriteString("resource \"google_cloud_run_domain_mapping\" \"api\" {\n")
+	b.WriteString(fmt.Sprintf("  count    = %s\n", domainCount))
+	b.WriteString("  name     = local.api_domain\n")
+	b.WriteString("  location = var.gcp_region\n\n")
+	b.WriteString("  metadata {\n")
+	b.WriteString("    namespace = var.gcp_project_id\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  spec {\n")
+	b.WriteString("    route_name = google_cloud_run_v2_service.backend.name\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"google_dns_record_set\" \"api\" {\n")
+	b.WriteString(fmt.Sprintf("  count        = %s\n", domainCount))
+	b.WriteString("  managed_zone = data.google_dns_managed_zone.main[0].name\n")
+	b.WriteString("  name         = \"${local.api_domain}.\"\n")
+	b.WriteString("  type         = \"CNAME\"\n")
+	b.WriteString("  ttl          = 300\n")
+	b.WriteString("  rrdatas      = [\"ghs.googlehosted.com.\"]\n")
+	b.WriteString("}\n")
+
+	if !hasFrontend(app) {
+		return b.String()
+	}
+	b.WriteString("\n")
+
+	b.WriteString("resource \"google_compute_managed_ssl_certificate\" \"frontend\" {\n")
+	b.WriteString(fmt.Sprintf("  count = %s\n", domainCount))
+	b.WriteString(fmt.Sprintf("  name  = \"%s-frontend-${var.environment}\"\n\n", name))
+	b.WriteString("  managed {\n")
+	b.WriteString("    domains = [var.domain_name]\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"google_compute_target_https_proxy\" \"frontend\" {\n")
+	b.WriteString(fmt.Sprintf("  count            = %s\n", domainCount))
+	b.WriteString(fmt.Sprintf("  name             = \"%s-frontend-${var.environment}\"\n", name))
+	b.WriteString("  url_map          = google_compute_url_map.frontend.id\n")
+	b.WriteString("  ssl_certificates = [google_compute_managed_ssl_certificate.frontend[0].id]\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"google_compute_global_forwarding_rule\" \"frontend_https\" {\n")
+	b.WriteString(fmt.Sprintf("  count      = %s\n", domainCount))
+	b.WriteString(fmt.Sprintf("  name       = \"%s-frontend-https-${var.environment}\"\n", name))
+	b.WriteString("  target     = google_compute_target_https_proxy.frontend[0].id\n")
+	b.WriteString("  ip_address = google_compute_global_address.frontend.id\n")
+	b.WriteString("  port_range = \"443\"\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"google_compute_url_map\" \"https_redirect\" {\n")
+	b.WriteString(fmt.Sprintf("  count = %s\n", domainCount))
+	b.WriteString(fmt.Sprintf("  name  = \"%s-https-redirect-${var.environment}\"\n\n", name))
+	b.WriteString("  default_url_redirect {\n")
+	b.WriteString("    https_redirect = true\n")
+	b.WriteString("    strip_query    = false\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("resource \"google_dns_record_set\" \"frontend\" {\n")
+	b.WriteString(fmt.Sprintf("  count        = %s\n", domainCount))
+	b.WriteString("  managed_zone = data.google_dns_managed_zone.main[0].name\n")
+	b.WriteString("  name         = \"${var.domain_name}.\"\n")
+	b.WriteString("  type         = \"A\"\n")
+	b.WriteString("  ttl          = 300\n")
+	b.WriteString("  rrdatas      = [google_compute_global_address.frontend.address]\n")
 	b.WriteString("}\n")
 
 	return b.String()
@@ -2,10 +2,10 @@ package terraform
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/ir"
 )
 
@@ -34,12 +34,18 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		if hasFrontend(app) {
 			files[filepath.Join(outputDir, "aws_cdn.tf")] = generateAWSCDN(app)
 		}
+		if hasDomain(app) {
+			files[filepath.Join(outputDir, "aws_dns.tf")] = generateAWSDNS(app)
+		}
 	case "gcp":
 		files[filepath.Join(outputDir, "gcp_cloudrun.tf")] = generateGCPCloudRun(app)
 		files[filepath.Join(outputDir, "gcp_cloudsql.tf")] = generateGCPCloudSQL(app)
 		if hasFrontend(app) {
 			files[filepath.Join(outputDir, "gcp_cdn.tf")] = generateGCPCDN(app)
 		}
+		if hasDomain(app) {
+			files[filepath.Join(outputDir, "gcp_dns.tf")] = generateGCPDNS(app)
+		}
 	default: // docker-prod
 		files[filepath.Join(outputDir, "docker_prod.tf")] = generateDockerProd(app)
 	}
@@ -50,24 +56,7 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		files[filepath.Join(outputDir, "envs", name+".tfvars")] = generateEnvTFVars(app, env, target)
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 // ── Stack Detection ──
@@ -127,6 +116,41 @@ func hasFrontend(app *ir.Application) bool {
 	return len(app.Pages) > 0
 }
 
+// hasDomain reports whether an environment declares a URL, so the
+// Terraform takes a domain_name to serve the app at over HTTPS.
+func hasDomain(app *ir.Application) bool {
+	for _, env := range app.Environments {
+		if env.Host() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// envDNSZone returns the dns_zone for an environment's domain: the zone's
+// name in Route 53, or in Cloud DNS, whose zone names can't contain dots,
+// its name with dashes.
+func envDNSZone(env *ir.Environment, target string) string {
+	zone := dnsZone(env.Host())
+	if target == "gcp" {
+		return strings.ReplaceAll(zone, ".", "-")
+	}
+	return zone
+}
+
+// domainCount is the count of resources that only exist with a domain.
+const domainCount = "var.domain_name != \"\" ? 1 : 0"
+
+// dnsZone guesses the DNS zone that holds host from its last two labels:
+// app.example.com is in example.com.
+func dnsZone(host string) string {
+	labels := strings.Split(host, ".")
+	if len(labels) <= 2 {
+		return host
+	}
+	return strings.Join(labels[len(labels)-2:], ".")
+}
+
 func hasDatabase(app *ir.Application) bool {
 	return isPostgres(app) || isMySQL(app)
 }
@@ -233,6 +257,13 @@ func generateMainTF(app *ir.Application, target string) string {
 		b.WriteString("    }\n")
 		b.WriteString("  }\n")
 		b.WriteString("}\n")
+		if hasDomain(app) && hasFrontend(app) {
+			// CloudFront certificates must be issued in us-east-1.
+			b.WriteString("\nprovider \"aws\" {\n")
+			b.WriteString("  alias  = \"us_east_1\"\n")
+			b.WriteString("  region = \"us-east-1\"\n")
+			b.WriteString("}\n")
+		}
 	case "gcp":
 		b.WriteString("provider \"google\" {\n")
 		b.WriteString("  project = var.gcp_project_id\n")
@@ -365,6 +396,24 @@ func generateVariablesTF(app *ir.Application, target string) string {
 		b.WriteString("}\n\n")
 	}
 
+	if hasDomain(app) && (target == "aws" || target == "gcp") {
+		zone := "Route 53 hosted zone"
+		if target == "gcp" {
+			zone = "Cloud DNS managed zone"
+		}
+		b.WriteString("variable \"domain_name\" {\n")
+		b.WriteString("  description = \"Host name to serve the app at over HTTPS (empty for none)\"\n")
+		b.WriteString("  type        = string\n")
+		b.WriteString("  default     = \"\"\n")
+		b.WriteString("}\n\n")
+
+		b.WriteString("variable \"dns_zone\" {\n")
+		b.WriteString(fmt.Sprintf("  description = \"%s that holds domain_name\"\n", zone))
+		b.WriteString("  type        = string\n")
+		b.WriteString("  default     = \"\"\n")
+		b.WriteString("}\n\n")
+	}
+
 	return b.String()
 }
 
@@ -414,6 +463,13 @@ func generateOutputsTF(app *ir.Application, target string) string {
 			b.WriteString("}\n\n")
 		}
 
+		if hasFrontend(app) {
+			b.WriteString("output \"cdn_ip_address\" {\n")
+			b.WriteString("  description = \"Cloud CDN load balancer IP address\"\n")
+			b.WriteString("  value       = google_compute_global_address.frontend.address\n")
+			b.WriteString("}\n\n")
+		}
+
 	default:
 		b.WriteString("output \"backend_url\" {\n")
 		b.WriteString("  description = \"Backend service URL\"\n")
@@ -421,6 +477,18 @@ func generateOutputsTF(app *ir.Application, target string) string {
 		b.WriteString("}\n\n")
 	}
 
+	if hasDomain(app) && (target == "aws" || target == "gcp") {
+		b.WriteString("output \"app_url\" {\n")
+		b.WriteString("  description = \"HTTPS URL of the app\"\n")
+		b.WriteString("  value       = var.domain_name == \"\" ? \"\" : \"https://${var.domain_name}\"\n")
+		b.WriteString("}\n\n")
+
+		b.WriteString("output \"api_url\" {\n")
+		b.WriteString("  description = \"HTTPS URL of the backend API\"\n")
+		b.WriteString("  value       = var.domain_name == \"\" ? \"\" : \"https://${local.api_domain}\"\n")
+		b.WriteString("}\n\n")
+	}
+
 	return b.String()
 }
 
@@ -459,6 +527,11 @@ func generateTFVarsExample(app *ir.Application, target string) string {
 		b.WriteString("db_password    = \"postgres\"\n")
 	}
 
+	if env := ir.PublicEnvironment(app); env != nil && (target == "aws" || target == "gcp") {
+		b.WriteString(fmt.Sprintf("\ndomain_name = \"%s\"\n", env.Host()))
+		b.WriteString(fmt.Sprintf("dns_zone    = \"%s\"\n", envDNSZone(env, target)))
+	}
+
 	return b.String()
 }
 
@@ -495,6 +568,11 @@ func generateEnvTFVars(app *ir.Application, env *ir.Environment, target string)
 		}
 	}
 
+	if host := env.Host(); host != "" && (target == "aws" || target == "gcp") {
+		b.WriteString(fmt.Sprintf("domain_name = \"%s\"\n", host))
+		b.WriteString(fmt.Sprintf("dns_zone    = \"%s\"\n", envDNSZone(env, target)))
+	}
+
 	// Production defaults
 	if strings.EqualFold(env.Name, "production") {
 		switch target {
@@ -141,6 +141,189 @@ func TestGenerateNoCDNWithoutFrontend(t *testing.T) {
 
 // ── Content tests ──
 
+func TestAWSCDNCaching(t *testing.T) {
+	content := generateAWSCDN(testApp())
+	for _, want := range []string{
+		"resource \"aws_cloudfront_cache_policy\" \"assets\"",
+		"  default_ttl = 31536000\n",
+		"value    = \"public, max-age=31536000, immutable\"",
+		"value    = \"no-cache\"",
+		"path_pattern               = \"/assets/*\"",
+		"cache_policy_id            = aws_cloudfront_cache_policy.html.id",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("aws_cdn.tf missing %q", want)
+		}
+	}
+	if strings.Contains(content, "forwarded_values") {
+		t.Error("cache behaviors should use cache policies, not forwarded_values")
+	}
+
+	app := testApp()
+	app.Config.Frontend = "Angular"
+	angular := generateAWSCDN(app)
+	for _, pattern := range []string{"*.js", "*.css", "/media/*"} {
+		if !strings.Contains(angular, "path_pattern               = \""+pattern+"\"") {
+			t.Errorf("Angular CDN missing a behavior for %s", pattern)
+		}
+	}
+}
+
+func TestGCPCDNCaching(t *testing.T) {
+	content := generateGCPCDN(testApp())
+	for _, want := range []string{
+		"resource \"google_compute_backend_bucket\" \"frontend_assets\"",
+		"enable_cdn  = true",
+		"custom_response_headers = [\"Cache-Control: public, max-age=31536000, immutable\"]",
+		"paths   = [\"/assets/*\"]",
+		"resource \"google_compute_global_forwarding_rule\" \"frontend\"",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("gcp_cdn.tf missing %q", want)
+		}
+	}
+}
+
+func withURLs(app *ir.Application) *ir.Application {
+	app.Environments[0].Config["url"] = "staging testapp example com"
+	app.Environments[1].Config["url"] = "testapp example com"
+	return app
+}
+
+func TestAWSDNS(t *testing.T) {
+	app := withURLs(testApp())
+	tmpDir := t.TempDir()
+	if err := (Generator{}).Generate(app, tmpDir); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	dns, err := os.ReadFile(filepath.Join(tmpDir, "aws_dns.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"api_domain = \"api.${var.domain_name}\"",
+		"data \"aws_route53_zone\" \"main\"",
+		"resource \"aws_acm_certificate\" \"cdn\" {\n  provider          = aws.us_east_1\n",
+		"validation_method = \"DNS\"",
+		"resource \"aws_lb_listener\" \"https\"",
+		"certificate_arn   = aws_acm_certificate_validation.api[0].certificate_arn",
+		"name                   = aws_cloudfront_distribution.frontend.domain_name",
+	} {
+		if !strings.Contains(string(dns), want) {
+			t.Errorf("aws_dns.tf missing %q", want)
+		}
+	}
+
+	tfvars, _ := os.ReadFile(filepath.Join(tmpDir, "envs", "staging.tfvars"))
+	if !strings.Contains(string(tfvars), "domain_name = \"staging.testapp.example.com\"\ndns_zone    = \"example.com\"\n") {
+		t.Errorf("staging.tfvars should set the domain and zone:\n%s", tfvars)
+	}
+
+	main, _ := os.ReadFile(filepath.Join(tmpDir, "main.tf"))
+	if !strings.Contains(string(main), "alias  = \"us_east_1\"") {
+		t.Error("main.tf should configure a us-east-1 provider for the CloudFront certificate")
+	}
+	cdn, _ := os.ReadFile(filepath.Join(tmpDir, "aws_cdn.tf"))
+	if !strings.Contains(string(cdn), "aliases             = var.domain_name == \"\" ? [] : [var.domain_name]") {
+		t.Error("the CloudFront distribution should serve the domain")
+	}
+	network, _ := os.ReadFile(filepath.Join(tmpDir, "aws_networking.tf"))
+	if !strings.Contains(string(network), "status_code = \"HTTP_301\"") {
+		t.Error("HTTP should redirect to HTTPS with a domain")
+	}
+}
+
+func TestGCPDNS(t *testing.T) {
+	app := withURLs(testApp())
+	app.Config.Deploy = "GCP"
+	tmpDir := t.TempDir()
+	if err := (Generator{}).Generate(app, tmpDir); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	dns, err := os.ReadFile(filepath.Join(tmpDir, "gcp_dns.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"resource \"google_cloud_run_domain_mapping\" \"api\"",
+		"rrdatas      = [\"ghs.googlehosted.com.\"]",
+		"domains = [var.domain_name]",
+		"port_range = \"443\"",
+		"rrdatas      = [google_compute_global_address.frontend.address]",
+	} {
+		if !strings.Contains(string(dns), want) {
+			t.Errorf("gcp_dns.tf missing %q", want)
+		}
+	}
+	tfvars, _ := os.ReadFile(filepath.Join(tmpDir, "envs", "production.tfvars"))
+	if !strings.Contains(string(tfvars), "dns_zone    = \"example-com\"") {
+		t.Errorf("Cloud DNS zone names use dashes:\n%s", tfvars)
+	}
+}
+
+func TestNoDNSWithoutURL(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := (Generator{}).Generate(testApp(), tmpDir); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "aws_dns.tf")); err == nil {
+		t.Error("aws_dns.tf should only be generated when an environment has a URL")
+	}
+	vars, _ := os.ReadFile(filepath.Join(tmpDir, "variables.tf"))
+	if strings.Contains(string(vars), "domain_name") {
+		t.Error("variables.tf should not declare domain_name without a URL")
+	}
+}
+
+func TestAWSCompliance(t *testing.T) {
+	app := testApp()
+	if ecs := generateAWSECS(app); !strings.Contains(ecs, "retention_in_days = 30\n") {
+		t.Error("logs should be kept 30 days by default")
+	}
+	if net := generateAWSNetworking(app); strings.Contains(net, "access_logs") {
+		t.Error("access logs should only be stored with a compliance profile")
+	}
+
+	app.Config.Compliance = "SOC2"
+	if ecs := generateAWSECS(app); !strings.Contains(ecs, "retention_in_days = 365\n") {
+		t.Error("SOC2 should keep logs for a year")
+	}
+	rds := generateAWSRDS(app)
+	for _, want := range []string{"backup_retention_period = 30\n", "storage_encrypted       = true\n"} {
+		if !strings.Contains(rds, want) {
+			t.Errorf("RDS missing %q", want)
+		}
+	}
+	net := generateAWSNetworking(app)
+	for _, want := range []string{
+		"    bucket  = aws_s3_bucket.access_logs.id\n    enabled = true\n",
+		"resource \"aws_s3_bucket_lifecycle_configuration\" \"access_logs\"",
+		"      days = 365\n",
+	} {
+		if !strings.Contains(net, want) {
+			t.Errorf("networking missing %q", want)
+		}
+	}
+
+	// CloudWatch accepts only certain periods; round up.
+	if got := cloudWatchRetention(100); got != 120 {
+		t.Errorf("cloudWatchRetention(100): got %d, want 120", got)
+	}
+}
+
+func TestGCPLogRetention(t *testing.T) {
+	app := testApp()
+	if strings.Contains(generateGCPCloudRun(app), "google_logging_project_bucket_config") {
+		t.Error("the default log bucket should be left alone without a compliance profile")
+	}
+	app.Config.Compliance = "SOC2"
+	if !strings.Contains(generateGCPCloudRun(app), "  retention_days = 365\n") {
+		t.Error("SOC2 should keep logs for a year")
+	}
+}
+
 func TestMainTFContainsAWSProvider(t *testing.T) {
 	app := testApp()
 	content := generateMainTF(app, "aws")
@@ -191,8 +191,15 @@ func GenerateCSSVariables(tokens map[string]string, theme *ir.Theme) string {
 
 	// Font imports must come first per CSS spec
 	if theme != nil && len(theme.Fonts) > 0 {
-		fonts := collectGoogleFonts(theme.Fonts)
-		if fonts != "" {
+		if theme.LocalFonts {
+			// Self-hosted from the @fontsource packages, bundled by the build.
+			for _, pkg := range fontPackages(theme.Fonts) {
+				for _, w := range fontWeights {
+					b.WriteString(fmt.Sprintf("@import '%s/%s.css';\n", pkg, w))
+				}
+			}
+			b.WriteString("\n")
+		} else if fonts := collectGoogleFonts(theme.Fonts); fonts != "" {
 			b.WriteString(fmt.Sprintf("@import url('https://fonts.googleapis.com/css2?%s&display=swap');\n\n", fonts))
 		}
 	}
@@ -531,15 +538,47 @@ func cssVarToJS(s string) string {
 	return strings.Join(parts, "")
 }
 
+// fontWeights are the weights loaded for each theme font.
+var fontWeights = []string{"400", "500", "600", "700"}
+
 func collectGoogleFonts(fonts map[string]string) string {
 	seen := make(map[string]bool)
 	var families []string
 	for _, font := range fonts {
 		if !seen[font] {
 			seen[font] = true
-			families = append(families, "family="+strings.ReplaceAll(font, " ", "+")+":wght@400;500;600;700")
+			families = append(families, "family="+strings.ReplaceAll(font, " ", "+")+":wght@"+strings.Join(fontWeights, ";"))
 		}
 	}
 	sort.Strings(families)
 	return strings.Join(families, "&")
 }
+
+// fontPackages returns the sorted @fontsource package names for the theme's
+// fonts: "Open Sans" → "@fontsource/open-sans".
+func fontPackages(fonts map[string]string) []string {
+	seen := make(map[string]bool)
+	var pkgs []string
+	for _, font := range fonts {
+		pkg := "@fontsource/" + strings.ToLower(strings.Join(strings.Fields(font), "-"))
+		if !seen[pkg] {
+			seen[pkg] = true
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	sort.Strings(pkgs)
+	return pkgs
+}
+
+// FontDependencies returns the npm packages that self-host the theme's
+// fonts, or nil when fonts come from Google Fonts.
+func FontDependencies(theme *ir.Theme) map[string]string {
+	if theme == nil || !theme.LocalFonts || len(theme.Fonts) == 0 {
+		return nil
+	}
+	deps := make(map[string]string)
+	for _, pkg := range fontPackages(theme.Fonts) {
+		deps[pkg] = "^5.0.0"
+	}
+	return deps
+}
@@ -0,0 +1,195 @@
+package themes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// ResponsiveLayout is what a responsive page's styles lay out.
+type ResponsiveLayout struct {
+	Page   string   // the page's root class, e.g. "home-page"
+	Item   string   // class of each record the page lists, e.g. "post-item"; empty when it lists none
+	Shapes []string // shape of each field a listed record shows: "heading", "text", "date", or "badge"
+	Table  bool     // whether the page shows a table
+	Nav    bool     // whether the page's navigation bar collapses into a menu on phones
+	*ir.Responsive
+}
+
+// ResponsiveCSS returns a responsive page's styles. The page is a container
+// its lists and tables query, so they adapt to the space the page gets as
+// well as to the screen: each listed record is a grid row with a column per
+// field, wrapping into two columns on tablets and stacking into one on
+// phones, where tables scroll sideways instead of overflowing. Spacing
+// comes from the theme and tightens as the screen narrows.
+func ResponsiveCSS(theme *ir.Theme, l ResponsiveLayout) string {
+	tokens := MergeTokens(themeSystem(theme), theme)
+	sm, md, lg := tokens["--spacing-sm"], tokens["--spacing-md"], tokens["--spacing-lg"]
+	page := "." + l.Page
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s {\n", page)
+	b.WriteString("  container-type: inline-size;\n")
+	b.WriteString("  max-width: 1200px;\n")
+	b.WriteString("  margin: 0 auto;\n")
+	fmt.Fprintf(&b, "  padding: var(--spacing-lg, %s);\n", lg)
+	b.WriteString("}\n")
+
+	item := ""
+	if l.Item != "" && len(l.Shapes) > 0 {
+		item = page + " ." + l.Item
+		var columns []string
+		for _, s := range l.Shapes {
+			columns = append(columns, gridColumn(s))
+		}
+		fmt.Fprintf(&b, "\n%s {\n", item)
+		b.WriteString("  display: grid;\n")
+		fmt.Fprintf(&b, "  grid-template-columns: %s;\n", strings.Join(columns, " "))
+		b.WriteString("  grid-auto-flow: column;\n")
+		b.WriteString("  align-items: center;\n")
+		fmt.Fprintf(&b, "  gap: var(--spacing-sm, %s) var(--spacing-md, %s);\n", sm, md)
+		fmt.Fprintf(&b, "  padding: var(--spacing-sm, %s) 0;\n", sm)
+		b.WriteString("}\n")
+	}
+	if l.Table {
+		fmt.Fprintf(&b, "\n%s .data-table {\n", page)
+		b.WriteString("  width: 100%;\n")
+		b.WriteString("  border-collapse: collapse;\n")
+		b.WriteString("}\n")
+	}
+	if l.Nav && l.Mobile {
+		b.WriteString("\n")
+		b.WriteString(navCSS(page + " "))
+	}
+
+	if l.Tablet {
+		fmt.Fprintf(&b, "\n@media (max-width: %dpx) {\n", ir.TabletBreakpoint-1)
+		fmt.Fprintf(&b, "  %s {\n", page)
+		fmt.Fprintf(&b, "    padding: var(--spacing-md, %s);\n", md)
+		b.WriteString("  }\n")
+		if item != "" {
+			fmt.Fprintf(&b, "\n  %s {\n", item)
+			b.WriteString("    grid-template-columns: minmax(0, 1fr) auto;\n")
+			b.WriteString("    grid-auto-flow: row;\n")
+			b.WriteString("  }\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	if l.Mobile {
+		fmt.Fprintf(&b, "\n@media (max-width: %dpx) {\n", ir.MobileBreakpoint-1)
+		fmt.Fprintf(&b, "  %s {\n", page)
+		fmt.Fprintf(&b, "    padding: var(--spacing-sm, %s);\n", sm)
+		b.WriteString("  }\n")
+		if l.Nav {
+			b.WriteString("\n")
+			b.WriteString(indentCSS(mobileNavCSS(page+" "), "  "))
+		}
+		b.WriteString("}\n")
+
+		if item != "" || l.Table {
+			fmt.Fprintf(&b, "\n@container (max-width: %dpx) {\n", ir.MobileBreakpoint-1)
+			if item != "" {
+				fmt.Fprintf(&b, "  %s {\n", item)
+				b.WriteString("    grid-template-columns: minmax(0, 1fr);\n")
+				b.WriteString("    grid-auto-flow: row;\n")
+				b.WriteString("  }\n")
+			}
+			if l.Table {
+				if item != "" {
+					b.WriteString("\n")
+				}
+				fmt.Fprintf(&b, "  %s .data-table {\n", page)
+				b.WriteString("    display: block;\n")
+				b.WriteString("    overflow-x: auto;\n")
+				b.WriteString("  }\n")
+			}
+			b.WriteString("}\n")
+		}
+	}
+	return b.String()
+}
+
+// MobileNavCSS returns the styles of a navigation bar that collapses into a
+// menu behind its .nav-toggle button on phones, showing its .nav-links
+// while the bar has the open class.
+func MobileNavCSS() string {
+	return navCSS("") + fmt.Sprintf("\n@media (max-width: %dpx) {\n", ir.MobileBreakpoint-1) +
+		indentCSS(mobileNavCSS(""), "  ") + "}\n"
+}
+
+// navCSS lays a navigation bar's links out in a row, its menu button
+// hidden.
+func navCSS(scope string) string {
+	return fmt.Sprintf(`%[1]s.navbar {
+  display: flex;
+  align-items: center;
+  gap: var(--spacing-md, 16px);
+}
+
+%[1]s.nav-links {
+  display: flex;
+  gap: var(--spacing-md, 16px);
+}
+
+%[1]s.nav-toggle {
+  display: none;
+}
+`, scope)
+}
+
+// mobileNavCSS shows a navigation bar's menu button and stacks its links
+// below it while the menu is open.
+func mobileNavCSS(scope string) string {
+	return fmt.Sprintf(`%[1]s.navbar {
+  flex-wrap: wrap;
+}
+
+%[1]s.nav-toggle {
+  display: block;
+}
+
+%[1]s.nav-links {
+  display: none;
+  flex-basis: 100%%;
+  flex-direction: column;
+}
+
+%[1]s.navbar.open .nav-links {
+  display: flex;
+}
+`, scope)
+}
+
+// gridColumn sizes the grid column of a listed field by its shape:
+// headings take the most room, text shares what's left, and dates and
+// badges fit their content.
+func gridColumn(shape string) string {
+	switch shape {
+	case "heading":
+		return "minmax(0, 2fr)"
+	case "text":
+		return "minmax(0, 1fr)"
+	}
+	return "auto"
+}
+
+// indentCSS indents every non-empty line of a block of rules.
+func indentCSS(css, indent string) string {
+	lines := strings.Split(strings.TrimSuffix(css, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// themeSystem returns the design system a theme is built on, if any.
+func themeSystem(theme *ir.Theme) string {
+	if theme == nil {
+		return ""
+	}
+	return theme.DesignSystem
+}
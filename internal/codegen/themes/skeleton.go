@@ -0,0 +1,58 @@
+package themes
+
+import (
+	"fmt"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// SkeletonCSS returns the rules for the placeholder bars loading skeletons
+// are built from: tinted from the theme's text and surface colors, rounded
+// like the rest of the theme, and pulsing unless the user prefers reduced
+// motion. The theme's values are fallbacks for its CSS variables, so dark
+// mode and runtime overrides still apply.
+func SkeletonCSS(theme *ir.Theme) string {
+	tokens := MergeTokens(themeSystem(theme), theme)
+
+	return fmt.Sprintf(`.skeleton {
+  display: block;
+  height: 1em;
+  margin: 0.25em 0;
+  border-radius: var(--radius, %s);
+  background: color-mix(in srgb, var(--color-text, %s) 12%%, var(--color-surface, %s));
+  animation: skeleton-pulse 1.5s ease-in-out infinite;
+}
+
+.skeleton-heading {
+  width: 60%%;
+  height: 1.25em;
+}
+
+.skeleton-text {
+  width: 100%%;
+}
+
+.skeleton-date {
+  width: 6em;
+}
+
+.skeleton-badge {
+  display: inline-block;
+  width: 4.5em;
+  height: 1.25em;
+  border-radius: 9999px;
+}
+
+@keyframes skeleton-pulse {
+  50%% {
+    opacity: 0.5;
+  }
+}
+
+@media (prefers-reduced-motion: reduce) {
+  .skeleton {
+    animation: none;
+  }
+}
+`, tokens["--radius"], tokenVal(tokens, "--color-text", "#111827"), tokenVal(tokens, "--color-surface", "#f9fafb"))
+}
@@ -0,0 +1,465 @@
+package themes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// styleRule styles one of the class names generated markup is written
+// with, as CSS declarations and as the Tailwind utilities they amount to.
+// Declaration values name theme tokens in braces, e.g. {color-primary}
+// for --color-primary.
+type styleRule struct {
+	selector string
+	css      []string
+	tailwind string
+}
+
+// baseRules are the styles of the class names the generators share:
+// loading, error, and empty states, alerts, badges, buttons, tables,
+// modals, form fields, and pagination.
+var baseRules = []styleRule{
+	{".loading-spinner", []string{
+		"display: flex",
+		"justify-content: center",
+		"padding: {spacing-lg}",
+	}, "flex justify-center p-lg"},
+	{".spinner", []string{
+		"width: 1.5rem",
+		"height: 1.5rem",
+		"border: 3px solid color-mix(in srgb, {color-primary} 20%, transparent)",
+		"border-top-color: {color-primary}",
+		"border-radius: 50%",
+		"animation: spin 0.8s linear infinite",
+	}, "h-6 w-6 animate-spin rounded-full border-[3px] border-primary/20 border-t-primary"},
+	{".error-state", []string{
+		"padding: {spacing-lg}",
+		"text-align: center",
+		"color: {color-error}",
+	}, "p-lg text-center text-error"},
+	{".empty-state", []string{
+		"padding: {spacing-lg}",
+		"text-align: center",
+		"color: color-mix(in srgb, {color-text} 60%, transparent)",
+	}, "p-lg text-center text-text/60"},
+	{".alert", []string{
+		"margin-bottom: {spacing-md}",
+		"padding: {spacing-sm} {spacing-md}",
+		"border-radius: {radius}",
+	}, "mb-md rounded px-md py-sm"},
+	{".alert-error", []string{
+		"background: color-mix(in srgb, {color-error} 10%, transparent)",
+		"color: {color-error}",
+	}, "bg-error/10 text-error"},
+	{".alert-success", []string{
+		"background: color-mix(in srgb, #16a34a 10%, transparent)",
+		"color: #15803d",
+	}, "bg-green-600/10 text-green-700"},
+	{".alert-info", []string{
+		"background: color-mix(in srgb, {color-primary} 10%, transparent)",
+		"color: {color-primary}",
+	}, "bg-primary/10 text-primary"},
+	{".badge", []string{
+		"display: inline-block",
+		"padding: 0.125rem {spacing-sm}",
+		"border-radius: 9999px",
+		"background: color-mix(in srgb, {color-primary} 10%, transparent)",
+		"color: {color-primary}",
+		"font-size: 0.75rem",
+		"font-weight: 600",
+	}, "inline-block rounded-full bg-primary/10 px-sm py-0.5 text-xs font-semibold text-primary"},
+	{".btn", []string{
+		"display: inline-flex",
+		"align-items: center",
+		"gap: {spacing-sm}",
+		"padding: {spacing-sm} {spacing-md}",
+		"border: none",
+		"border-radius: {radius}",
+		"background: {color-primary}",
+		"color: #ffffff",
+		"cursor: pointer",
+	}, "inline-flex cursor-pointer items-center gap-sm rounded border-0 bg-primary px-md py-sm text-white"},
+	{".btn:disabled", []string{
+		"opacity: 0.6",
+		"cursor: not-allowed",
+	}, "cursor-not-allowed opacity-60"},
+	{".data-table", []string{
+		"width: 100%",
+		"border-collapse: collapse",
+	}, "w-full border-collapse"},
+	{".data-table th, .data-table td", []string{
+		"padding: {spacing-sm} {spacing-md}",
+		"border-bottom: 1px solid color-mix(in srgb, {color-text} 10%, transparent)",
+		"text-align: left",
+	}, "border-b border-text/10 px-md py-sm text-left"},
+	{".data-table th", []string{
+		"background: {color-surface}",
+		"font-weight: 600",
+	}, "bg-surface font-semibold"},
+	{".clickable", []string{
+		"cursor: pointer",
+	}, "cursor-pointer"},
+	{".clickable:hover", []string{
+		"background: {color-surface}",
+	}, "bg-surface"},
+	{".text-danger", []string{
+		"color: {color-error}",
+	}, "text-error"},
+	{".modal-overlay", []string{
+		"position: fixed",
+		"inset: 0",
+		"display: flex",
+		"align-items: center",
+		"justify-content: center",
+		"background: rgba(0, 0, 0, 0.4)",
+	}, "fixed inset-0 flex items-center justify-center bg-black/40"},
+	{".modal", []string{
+		"width: 100%",
+		"max-width: 32rem",
+		"padding: {spacing-lg}",
+		"border-radius: {radius}",
+		"background: {color-background}",
+	}, "w-full max-w-lg rounded bg-background p-lg"},
+	{".modal-close", []string{
+		"float: right",
+		"border: none",
+		"background: none",
+		"font-size: 1.25rem",
+		"cursor: pointer",
+	}, "float-right cursor-pointer border-0 bg-transparent text-xl"},
+	{".form-field", []string{
+		"display: flex",
+		"flex-direction: column",
+		"gap: 0.25rem",
+		"margin-bottom: {spacing-md}",
+	}, "mb-md flex flex-col gap-1"},
+	{".field-error", []string{
+		"color: {color-error}",
+		"font-size: 0.875rem",
+	}, "text-sm text-error"},
+	{".pagination", []string{
+		"display: flex",
+		"align-items: center",
+		"justify-content: center",
+		"gap: {spacing-sm}",
+		"padding: {spacing-md} 0",
+	}, "flex items-center justify-center gap-sm py-md"},
+}
+
+// tokenRef matches a theme token named in a declaration value.
+var tokenRef = regexp.MustCompile(`\{([a-z-]+)\}`)
+
+// StylingFiles returns the files a frontend styled with a styling system
+// needs, keyed by path relative to the frontend's directory: the styles of
+// the class names its markup is written with, in that system, the theme's
+// tokens mapped into it, and its tooling config. Plain CSS needs none.
+// framework is "react", "vue", "angular", or "svelte".
+func StylingFiles(system, framework string, theme *ir.Theme) map[string]string {
+	tokens := MergeTokens(themeSystem(theme), theme)
+	files := make(map[string]string)
+	switch system {
+	case ir.StylingTailwind:
+		files["tailwind.config.js"] = GenerateTailwindConfig(theme, tokens, framework)
+		if framework != "angular" {
+			// Angular's builder runs Tailwind itself once it finds the config
+			files["postcss.config.js"] = generatePostCSSConfig()
+		}
+		files[TailwindStylesheet(framework)] = generateTailwindCSS()
+	case ir.StylingCSSModules:
+		files["src/styles/app.module.css"] = "/* Generated by Human compiler — do not edit */\n\n" + BaseCSS(theme)
+		files["src/styles/cx.ts"] = generateCX()
+	case ir.StylingVanillaExtract:
+		files["src/styles/theme.css.ts"] = generateVanillaTheme(tokens)
+		files["src/styles/app.css.ts"] = generateVanillaStyles()
+	case ir.StylingStyledComponents:
+		files["src/styles/theme.ts"] = generateStyledTheme(tokens)
+		files["src/styles/GlobalStyle.ts"] = generateGlobalStyle(theme, tokens)
+	}
+	return files
+}
+
+// TailwindStylesheet returns the path of the stylesheet holding a
+// framework's Tailwind directives, relative to the frontend's directory.
+func TailwindStylesheet(framework string) string {
+	switch framework {
+	case "vue":
+		return "src/assets/tailwind.css"
+	case "angular", "svelte":
+		return "src/tailwind.css"
+	}
+	return "src/styles/tailwind.css"
+}
+
+// StylingDependencies returns (deps, devDeps) for a styling system.
+func StylingDependencies(system string) (map[string]string, map[string]string) {
+	switch system {
+	case ir.StylingTailwind:
+		return nil, map[string]string{
+			"tailwindcss":  "^3.4.0",
+			"autoprefixer": "^10.4.0",
+			"postcss":      "^8.4.0",
+		}
+	case ir.StylingVanillaExtract:
+		return map[string]string{"@vanilla-extract/css": "^1.16.0"},
+			map[string]string{"@vanilla-extract/vite-plugin": "^4.0.0"}
+	case ir.StylingStyledComponents:
+		return map[string]string{"styled-components": "^6.1.0"}, nil
+	}
+	return nil, nil
+}
+
+// BaseCSS returns the styles of the class names generated markup is
+// written with, as plain CSS reading the theme's tokens.
+func BaseCSS(theme *ir.Theme) string {
+	tokens := MergeTokens(themeSystem(theme), theme)
+	var b strings.Builder
+	for i, r := range baseRules {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s {\n", r.selector)
+		for _, d := range r.css {
+			fmt.Fprintf(&b, "  %s;\n", cssValue(d, tokens))
+		}
+		b.WriteString("}\n")
+	}
+	b.WriteString("\n@keyframes spin {\n  to {\n    transform: rotate(360deg);\n  }\n}\n")
+	return b.String()
+}
+
+// cssValue writes the tokens a declaration names as custom properties,
+// falling back to the theme's values.
+func cssValue(decl string, tokens map[string]string) string {
+	return tokenRef.ReplaceAllStringFunc(decl, func(m string) string {
+		name := "--" + m[1:len(m)-1]
+		return fmt.Sprintf("var(%s, %s)", name, tokens[name])
+	})
+}
+
+// generatePostCSSConfig produces postcss.config.js, running Tailwind and
+// Autoprefixer over the frontend's stylesheets.
+func generatePostCSSConfig() string {
+	return `// Generated by Human compiler — do not edit
+
+export default {
+  plugins: {
+    tailwindcss: {},
+    autoprefixer: {},
+  },
+};
+`
+}
+
+// generateTailwindCSS produces the stylesheet with Tailwind's directives,
+// composing the class names generated markup is written with from
+// utilities in the components layer.
+func generateTailwindCSS() string {
+	var b strings.Builder
+	b.WriteString("/* Generated by Human compiler — do not edit */\n\n")
+	b.WriteString("@tailwind base;\n")
+	b.WriteString("@tailwind components;\n")
+	b.WriteString("@tailwind utilities;\n\n")
+	b.WriteString("@layer components {\n")
+	for i, r := range baseRules {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "  %s {\n", r.selector)
+		fmt.Fprintf(&b, "    @apply %s;\n", r.tailwind)
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// generateCX produces src/styles/cx.ts, which maps class names to the
+// scoped names the app's CSS module gives them.
+func generateCX() string {
+	return `// Generated by Human compiler — do not edit
+
+import styles from './app.module.css';
+
+// Markup is written with plain class names; the CSS module scopes the ones
+// it styles and the rest are kept as they are.
+export function cx(names: string): string {
+  return names
+    .split(' ')
+    .filter(Boolean)
+    .map((name) => styles[name] ?? name)
+    .join(' ');
+}
+`
+}
+
+// tokenGroup is the theme tokens sharing a prefix, e.g. the colors.
+type tokenGroup struct {
+	name   string   // "color", "spacing", "font"; "" for --radius
+	keys   []string // each token's name within its group
+	values []string
+}
+
+// groupTokens splits the theme's tokens into groups by prefix, in order.
+func groupTokens(tokens map[string]string) []tokenGroup {
+	var groups []tokenGroup
+	for _, name := range []string{"color", "spacing", "font"} {
+		g := tokenGroup{name: name}
+		var keys []string
+		for k := range tokens {
+			if strings.HasPrefix(k, "--"+name+"-") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			g.keys = append(g.keys, strings.TrimPrefix(k, "--"+name+"-"))
+			g.values = append(g.values, tokens[k])
+		}
+		if len(g.keys) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	return append(groups, tokenGroup{keys: []string{"radius"}, values: []string{tokens["--radius"]}})
+}
+
+// writeTokenObject writes the theme's tokens as a TypeScript object, each
+// value given by value(group, key, value).
+func writeTokenObject(b *strings.Builder, tokens map[string]string, value func(group, key, v string) string) {
+	for _, g := range groupTokens(tokens) {
+		if g.name == "" {
+			fmt.Fprintf(b, "  radius: %s,\n", value("", "radius", g.values[0]))
+			continue
+		}
+		fmt.Fprintf(b, "  %s: {\n", g.name)
+		for i, k := range g.keys {
+			fmt.Fprintf(b, "    '%s': %s,\n", k, value(g.name, k, g.values[i]))
+		}
+		b.WriteString("  },\n")
+	}
+}
+
+// tokenVar returns the custom property a grouped token is held in.
+func tokenVar(group, key string) string {
+	if group == "" {
+		return key
+	}
+	return group + "-" + key
+}
+
+// generateVanillaTheme produces src/styles/theme.css.ts: the theme's tokens
+// as a vanilla-extract theme, kept in the custom properties the rest of the
+// app's styles read.
+func generateVanillaTheme(tokens map[string]string) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { createGlobalTheme, createGlobalThemeContract } from '@vanilla-extract/css';\n\n")
+	b.WriteString("export const vars = createGlobalThemeContract({\n")
+	writeTokenObject(&b, tokens, func(group, key, _ string) string {
+		return "'" + tokenVar(group, key) + "'"
+	})
+	b.WriteString("});\n\n")
+	b.WriteString("createGlobalTheme(':root', vars, {\n")
+	writeTokenObject(&b, tokens, func(_, _, v string) string {
+		return "'" + strings.ReplaceAll(v, "'", "\\'") + "'"
+	})
+	b.WriteString("});\n")
+	return b.String()
+}
+
+// generateVanillaStyles produces src/styles/app.css.ts, styling the class
+// names generated markup is written with from the theme's vars.
+func generateVanillaStyles() string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { globalKeyframes, globalStyle } from '@vanilla-extract/css';\n")
+	b.WriteString("import { vars } from './theme.css';\n\n")
+	b.WriteString("globalKeyframes('spin', {\n  to: { transform: 'rotate(360deg)' },\n});\n")
+	for _, r := range baseRules {
+		fmt.Fprintf(&b, "\nglobalStyle('%s', {\n", r.selector)
+		for _, d := range r.css {
+			prop, value, _ := strings.Cut(d, ": ")
+			fmt.Fprintf(&b, "  %s: %s,\n", camelProp(prop), vanillaValue(value))
+		}
+		b.WriteString("});\n")
+	}
+	return b.String()
+}
+
+// vanillaValue writes a declaration value as TypeScript, reading the
+// tokens it names from the theme's vars.
+func vanillaValue(value string) string {
+	if m := tokenRef.FindStringSubmatch(value); m != nil && m[0] == value {
+		return tokenPath(m[1])
+	}
+	if !tokenRef.MatchString(value) {
+		return "'" + value + "'"
+	}
+	return "`" + tokenRef.ReplaceAllStringFunc(value, func(m string) string {
+		return "${" + tokenPath(m[1:len(m)-1]) + "}"
+	}) + "`"
+}
+
+// tokenPath returns where a token is found in the theme's vars, e.g.
+// vars.color.primary for color-primary.
+func tokenPath(token string) string {
+	group, key, ok := strings.Cut(token, "-")
+	if !ok {
+		return "vars." + token
+	}
+	return "vars." + group + "." + key
+}
+
+// camelProp returns a CSS property's name in camelCase.
+func camelProp(prop string) string {
+	parts := strings.Split(prop, "-")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// generateStyledTheme produces src/styles/theme.ts: the theme's tokens as
+// the styled-components theme.
+func generateStyledTheme(tokens map[string]string) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import 'styled-components';\n\n")
+	b.WriteString("export const theme = {\n")
+	writeTokenObject(&b, tokens, func(_, _, v string) string {
+		return "'" + strings.ReplaceAll(v, "'", "\\'") + "'"
+	})
+	b.WriteString("};\n\n")
+	b.WriteString("export type AppTheme = typeof theme;\n\n")
+	b.WriteString("declare module 'styled-components' {\n")
+	b.WriteString("  export interface DefaultTheme extends AppTheme {}\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// generateGlobalStyle produces src/styles/GlobalStyle.ts, holding the
+// styled-components theme in custom properties and styling the class names
+// generated markup is written with from them.
+func generateGlobalStyle(theme *ir.Theme, tokens map[string]string) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { createGlobalStyle } from 'styled-components';\n\n")
+	b.WriteString("const GlobalStyle = createGlobalStyle`\n")
+	b.WriteString("  :root {\n")
+	for _, g := range groupTokens(tokens) {
+		for _, k := range g.keys {
+			path := "theme.radius"
+			if g.name != "" {
+				path = fmt.Sprintf("theme.%s['%s']", g.name, k)
+			}
+			fmt.Fprintf(&b, "    --%s: ${({ theme }) => %s};\n", tokenVar(g.name, k), path)
+		}
+	}
+	b.WriteString("  }\n\n")
+	b.WriteString(indentCSS(BaseCSS(theme), "  "))
+	b.WriteString("`;\n\n")
+	b.WriteString("export default GlobalStyle;\n")
+	return b.String()
+}
@@ -209,6 +209,71 @@ func TestMergeTokensDefaults(t *testing.T) {
 	}
 }
 
+// ── SkeletonCSS ──
+
+func TestSkeletonCSS(t *testing.T) {
+	css := SkeletonCSS(&ir.Theme{
+		DesignSystem: "material",
+		Colors:       map[string]string{"surface": "#eeeeee"},
+		BorderRadius: "rounded",
+	})
+	for _, want := range []string{
+		"border-radius: var(--radius, 12px);",
+		"var(--color-text, #212121) 12%",
+		"var(--color-surface, #eeeeee)",
+		".skeleton-heading {",
+		".skeleton-badge {",
+		"@keyframes skeleton-pulse",
+		"prefers-reduced-motion",
+	} {
+		if !strings.Contains(css, want) {
+			t.Errorf("skeleton CSS missing %q:\n%s", want, css)
+		}
+	}
+
+	// No theme falls back to the neutral defaults
+	if css := SkeletonCSS(nil); !strings.Contains(css, "var(--radius, 6px)") || !strings.Contains(css, "var(--color-surface, #f9fafb)") {
+		t.Errorf("nil theme should use default tokens:\n%s", css)
+	}
+}
+
+// ── ResponsiveCSS ──
+
+func TestResponsiveCSS(t *testing.T) {
+	css := ResponsiveCSS(&ir.Theme{Spacing: "compact"}, ResponsiveLayout{
+		Page:       "home-page",
+		Item:       "post-item",
+		Shapes:     []string{"heading", "badge", "date"},
+		Responsive: &ir.Responsive{Mobile: true, Tablet: true},
+	})
+	for _, want := range []string{
+		".home-page {\n  container-type: inline-size;",
+		"padding: var(--spacing-lg, 12px);",
+		"grid-template-columns: minmax(0, 2fr) auto auto;",
+		"@media (max-width: 1023px) {",
+		"@media (max-width: 639px) {",
+		"@container (max-width: 639px) {\n  .home-page .post-item {\n    grid-template-columns: minmax(0, 1fr);",
+	} {
+		if !strings.Contains(css, want) {
+			t.Errorf("responsive CSS missing %q:\n%s", want, css)
+		}
+	}
+	if strings.Contains(css, ".navbar") || strings.Contains(css, ".data-table") {
+		t.Errorf("a page without a navigation bar or table shouldn't style them:\n%s", css)
+	}
+
+	// Phones only: no tablet breakpoint, and tables scroll sideways
+	css = ResponsiveCSS(nil, ResponsiveLayout{Page: "archive-page", Table: true, Nav: true, Responsive: &ir.Responsive{Mobile: true}})
+	if strings.Contains(css, "1023px") {
+		t.Errorf("a page adapting to phones only shouldn't have a tablet breakpoint:\n%s", css)
+	}
+	for _, want := range []string{".archive-page .data-table {\n    display: block;\n    overflow-x: auto;", ".archive-page .navbar.open .nav-links {"} {
+		if !strings.Contains(css, want) {
+			t.Errorf("responsive CSS missing %q:\n%s", want, css)
+		}
+	}
+}
+
 // ── GenerateReactTheme ──
 
 func TestGenerateReactThemeMaterial(t *testing.T) {
@@ -359,6 +424,36 @@ func TestGenerateCSSVariablesFontImport(t *testing.T) {
 	}
 }
 
+func TestGenerateCSSVariablesLocalFonts(t *testing.T) {
+	theme := &ir.Theme{
+		Fonts:      map[string]string{"body": "Open Sans", "headings": "Inter"},
+		LocalFonts: true,
+	}
+
+	output := GenerateCSSVariables(map[string]string{}, theme)
+
+	if strings.Contains(output, "fonts.googleapis.com") {
+		t.Error("local fonts should not link Google Fonts")
+	}
+	for _, want := range []string{"@import '@fontsource/open-sans/400.css';", "@import '@fontsource/inter/700.css';"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q in:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "@fontsource") > strings.Index(output, ":root") {
+		t.Error("font imports must come before :root")
+	}
+
+	deps := FontDependencies(theme)
+	if len(deps) != 2 || deps["@fontsource/open-sans"] == "" || deps["@fontsource/inter"] == "" {
+		t.Errorf("FontDependencies = %v, want open-sans and inter", deps)
+	}
+	theme.LocalFonts = false
+	if deps := FontDependencies(theme); deps != nil {
+		t.Errorf("FontDependencies without LocalFonts = %v, want nil", deps)
+	}
+}
+
 // ── GenerateTailwindConfig ──
 
 func TestGenerateTailwindConfig(t *testing.T) {
@@ -599,3 +694,56 @@ func TestCssVarToJS(t *testing.T) {
 		}
 	}
 }
+
+func TestStylingFiles(t *testing.T) {
+	theme := &ir.Theme{Colors: map[string]string{"primary": "#0ea5e9"}}
+
+	files := StylingFiles(ir.StylingTailwind, "svelte", theme)
+	if !strings.Contains(files["tailwind.config.js"], "'primary': '#0ea5e9'") {
+		t.Errorf("tailwind.config.js should map the theme's colors:\n%s", files["tailwind.config.js"])
+	}
+	if !strings.Contains(files["postcss.config.js"], "tailwindcss: {}") {
+		t.Errorf("missing postcss.config.js: %v", files)
+	}
+	css := files["src/tailwind.css"]
+	for _, want := range []string{"@tailwind base;", "@layer components {", ".data-table {\n    @apply w-full border-collapse;", ".error-state {\n    @apply p-lg text-center text-error;"} {
+		if !strings.Contains(css, want) {
+			t.Errorf("tailwind.css missing %q:\n%s", want, css)
+		}
+	}
+	if _, ok := StylingFiles(ir.StylingTailwind, "angular", theme)["postcss.config.js"]; ok {
+		t.Error("Angular's builder runs Tailwind without a PostCSS config")
+	}
+
+	files = StylingFiles(ir.StylingCSSModules, "react", theme)
+	if !strings.Contains(files["src/styles/app.module.css"], "background: var(--color-primary, #0ea5e9);") {
+		t.Errorf("app.module.css should read the theme's tokens:\n%s", files["src/styles/app.module.css"])
+	}
+	if !strings.Contains(files["src/styles/cx.ts"], "styles[name] ?? name") {
+		t.Errorf("missing cx.ts: %v", files)
+	}
+
+	files = StylingFiles(ir.StylingVanillaExtract, "react", theme)
+	for _, want := range []string{"'primary': 'color-primary',", "createGlobalTheme(':root', vars, {", "'primary': '#0ea5e9',"} {
+		if !strings.Contains(files["src/styles/theme.css.ts"], want) {
+			t.Errorf("theme.css.ts missing %q:\n%s", want, files["src/styles/theme.css.ts"])
+		}
+	}
+	for _, want := range []string{"borderTopColor: vars.color.primary,", "padding: `${vars.spacing.sm} ${vars.spacing.md}`,", "globalKeyframes('spin'"} {
+		if !strings.Contains(files["src/styles/app.css.ts"], want) {
+			t.Errorf("app.css.ts missing %q:\n%s", want, files["src/styles/app.css.ts"])
+		}
+	}
+
+	files = StylingFiles(ir.StylingStyledComponents, "react", theme)
+	if !strings.Contains(files["src/styles/theme.ts"], "'primary': '#0ea5e9',") {
+		t.Errorf("theme.ts should hold the theme's tokens:\n%s", files["src/styles/theme.ts"])
+	}
+	if !strings.Contains(files["src/styles/GlobalStyle.ts"], "--color-primary: ${({ theme }) => theme.color['primary']};") {
+		t.Errorf("GlobalStyle.ts should read the styled-components theme:\n%s", files["src/styles/GlobalStyle.ts"])
+	}
+
+	if len(StylingFiles("", "react", theme)) != 0 {
+		t.Error("plain CSS needs no styling files")
+	}
+}
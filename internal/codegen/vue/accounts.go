@@ -0,0 +1,298 @@
+package vue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeProfileType writes the profile the account endpoints respond with:
+// the user without their password.
+func writeProfileType(b *strings.Builder, app *ir.Application) {
+	fmt.Fprintf(b, "\nexport type Profile = Omit<%s, 'password'>;\n", app.Accounts.Model)
+}
+
+// writeAccountFunction writes the client function of an endpoint the
+// accounts block added. A profile's fields are typed as the users' model
+// types them, with an optional field left empty sent as null.
+func writeAccountFunction(b *strings.Builder, ep *ir.Endpoint, app *ir.Application) {
+	fn := toCamelCase(ep.Name)
+	method := httpMethod(ep.Name)
+	path := apiPath(ep.Name)
+	switch ep.Account {
+	case ir.AccountProfile:
+		fmt.Fprintf(b, "export async function %s() {\n", fn)
+		fmt.Fprintf(b, "  return request<Profile>('%s', '%s');\n", method, path)
+	case ir.AccountUpdateProfile:
+		var fields []string
+		for _, f := range ir.ProfileFields(app) {
+			fields = append(fields, fmt.Sprintf("%s: %s", f.Name, profileFieldType(f)))
+		}
+		fmt.Fprintf(b, "export async function %s(params: { %s }) {\n", fn, strings.Join(fields, "; "))
+		fmt.Fprintf(b, "  return request<Profile>('%s', '%s', params);\n", method, path)
+	default:
+		var fields []string
+		for _, p := range ep.Params {
+			fields = append(fields, p.Name+": string")
+		}
+		fmt.Fprintf(b, "export async function %s(params: { %s }) {\n", fn, strings.Join(fields, "; "))
+		fmt.Fprintf(b, "  return request<void>('%s', '%s', params);\n", method, path)
+	}
+	b.WriteString("}\n")
+}
+
+// profileFieldType returns the type a profile field is sent as.
+func profileFieldType(f *ir.DataField) string {
+	t := tsType(f.Type)
+	if f.Type == "boolean" || f.Required {
+		return t
+	}
+	return t + " | null"
+}
+
+// profileDraftValue returns the expression sending a profile field from the
+// profile form's draft.
+func profileDraftValue(f *ir.DataField) string {
+	ref := "profileDraft.value." + f.Name
+	switch {
+	case f.Type == "boolean":
+		return ref
+	case tsType(f.Type) == "number" && f.Required:
+		return "Number(" + ref + ")"
+	case tsType(f.Type) == "number":
+		return fmt.Sprintf("String(%s) ? Number(%s) : null", ref, ref)
+	case f.Required:
+		return ref
+	}
+	return ref + " || null"
+}
+
+// generateAccountPage produces the account page the accounts block added:
+// a form for the user's profile, one changing their password, and a
+// danger zone deleting their account. Deleting asks again in a dialog,
+// and only goes ahead once the user has typed DeleteConfirmation and
+// their password; they are then signed out.
+func generateAccountPage(page *ir.Page, app *ir.Application) string {
+	var b strings.Builder
+	ctx := &pageContext{app: app}
+	profile := ir.AccountEndpoint(app, ir.AccountProfile)
+	update := ir.AccountEndpoint(app, ir.AccountUpdateProfile)
+	password := ir.AccountEndpoint(app, ir.AccountChangePassword)
+	remove := ir.AccountEndpoint(app, ir.AccountDelete)
+	showsProfile := profile != nil && update != nil
+	fields := ir.ProfileFields(app)
+
+	vueImports := []string{"ref"}
+	var imports []string
+	if showsProfile {
+		vueImports = append(vueImports, "onMounted", "watch")
+		imports = append(imports, toCamelCase(profile.Name), toCamelCase(update.Name))
+	}
+	if password != nil {
+		imports = append(imports, toCamelCase(password.Name))
+	}
+	if remove != nil {
+		imports = append(imports, toCamelCase(remove.Name))
+	}
+	imports = append(imports, "errorMessage")
+	if showsProfile {
+		imports = append(imports, "type Profile")
+	}
+
+	b.WriteString("<!-- Generated by Human compiler — do not edit -->\n")
+	b.WriteString("<script setup lang=\"ts\">\n")
+	fmt.Fprintf(&b, "import { %s } from 'vue';\n", strings.Join(vueImports, ", "))
+	if remove != nil {
+		b.WriteString("import { useRouter } from 'vue-router';\n")
+		if app.Auth != nil {
+			b.WriteString("import { useAuth } from '../composables/useAuth';\n")
+		}
+	}
+	fmt.Fprintf(&b, "import { %s } from '../api/client';\n\n", strings.Join(imports, ", "))
+
+	if remove != nil {
+		b.WriteString("const router = useRouter();\n")
+		if app.Auth != nil {
+			b.WriteString("const { logout } = useAuth();\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if showsProfile {
+		var initial, filled []string
+		for _, f := range fields {
+			initial = append(initial, fmt.Sprintf("%s: %s", f.Name, draftZero(f)))
+			filled = append(filled, fmt.Sprintf("%s: %s", f.Name, editValue("value", f)))
+		}
+		b.WriteString("const profile = ref<Profile | null>(null);\n")
+		fmt.Fprintf(&b, "const profileDraft = ref({ %s });\n", strings.Join(initial, ", "))
+		b.WriteString("const loadError = ref('');\n")
+		b.WriteString("const profileError = ref('');\n")
+		b.WriteString("const profileSaved = ref(false);\n\n")
+		b.WriteString("watch(profile, (value) => {\n")
+		fmt.Fprintf(&b, "  if (value) profileDraft.value = { %s };\n", strings.Join(filled, ", "))
+		b.WriteString("});\n\n")
+		b.WriteString("onMounted(async () => {\n")
+		b.WriteString("  try {\n")
+		fmt.Fprintf(&b, "    profile.value = (await %s()).data;\n", toCamelCase(profile.Name))
+		b.WriteString("  } catch (err) {\n")
+		b.WriteString("    loadError.value = errorMessage(err, 'Could not load your profile');\n")
+		b.WriteString("  }\n")
+		b.WriteString("});\n\n")
+		b.WriteString("async function saveProfile() {\n")
+		b.WriteString("  profileError.value = '';\n")
+		b.WriteString("  profileSaved.value = false;\n")
+		b.WriteString("  try {\n")
+		fmt.Fprintf(&b, "    const res = await %s({\n", toCamelCase(update.Name))
+		for _, f := range fields {
+			fmt.Fprintf(&b, "      %s: %s,\n", f.Name, profileDraftValue(f))
+		}
+		b.WriteString("    });\n")
+		b.WriteString("    profile.value = res.data;\n")
+		b.WriteString("    profileSaved.value = true;\n")
+		b.WriteString("  } catch (err) {\n")
+		b.WriteString("    profileError.value = errorMessage(err, 'Could not save your profile');\n")
+		b.WriteString("  }\n")
+		b.WriteString("}\n\n")
+	}
+
+	if password != nil {
+		b.WriteString("const currentPassword = ref('');\n")
+		b.WriteString("const newPassword = ref('');\n")
+		b.WriteString("const confirmPassword = ref('');\n")
+		b.WriteString("const passwordError = ref('');\n")
+		b.WriteString("const passwordChanged = ref(false);\n\n")
+		b.WriteString("async function savePassword() {\n")
+		b.WriteString("  passwordError.value = '';\n")
+		b.WriteString("  passwordChanged.value = false;\n")
+		b.WriteString("  if (newPassword.value !== confirmPassword.value) {\n")
+		b.WriteString("    passwordError.value = 'The new passwords do not match';\n")
+		b.WriteString("    return;\n")
+		b.WriteString("  }\n")
+		b.WriteString("  try {\n")
+		fmt.Fprintf(&b, "    await %s({ current_password: currentPassword.value, new_password: newPassword.value });\n", toCamelCase(password.Name))
+		b.WriteString("    currentPassword.value = '';\n")
+		b.WriteString("    newPassword.value = '';\n")
+		b.WriteString("    confirmPassword.value = '';\n")
+		b.WriteString("    passwordChanged.value = true;\n")
+		b.WriteString("  } catch (err) {\n")
+		b.WriteString("    passwordError.value = errorMessage(err, 'Could not change your password');\n")
+		b.WriteString("  }\n")
+		b.WriteString("}\n\n")
+	}
+
+	if remove != nil {
+		b.WriteString("const confirmingDelete = ref(false);\n")
+		b.WriteString("const deleteConfirmation = ref('');\n")
+		b.WriteString("const deletePassword = ref('');\n")
+		b.WriteString("const deleteError = ref('');\n")
+		b.WriteString("const deleting = ref(false);\n\n")
+		b.WriteString("function cancelDelete() {\n")
+		b.WriteString("  confirmingDelete.value = false;\n")
+		b.WriteString("  deleteConfirmation.value = '';\n")
+		b.WriteString("  deletePassword.value = '';\n")
+		b.WriteString("  deleteError.value = '';\n")
+		b.WriteString("}\n\n")
+		b.WriteString("async function removeAccount() {\n")
+		b.WriteString("  deleteError.value = '';\n")
+		b.WriteString("  deleting.value = true;\n")
+		b.WriteString("  try {\n")
+		fmt.Fprintf(&b, "    await %s({ password: deletePassword.value });\n", toCamelCase(remove.Name))
+		if app.Auth != nil {
+			b.WriteString("    logout();\n")
+		} else {
+			b.WriteString("    localStorage.removeItem('token');\n")
+		}
+		b.WriteString("    router.replace('/');\n")
+		b.WriteString("  } catch (err) {\n")
+		b.WriteString("    deleteError.value = errorMessage(err, 'Could not delete your account');\n")
+		b.WriteString("    deleting.value = false;\n")
+		b.WriteString("  }\n")
+		b.WriteString("}\n")
+	}
+	b.WriteString("</script>\n\n")
+
+	b.WriteString("<template>\n")
+	fmt.Fprintf(&b, "  <div class=\"%s-page\">\n", toKebabCase(page.Name))
+	fmt.Fprintf(&b, "    <h1>%s</h1>\n", ir.FieldLabel(page.Name))
+
+	if showsProfile {
+		b.WriteString("    <section aria-labelledby=\"profile-title\">\n")
+		b.WriteString("      <h2 id=\"profile-title\">Profile</h2>\n")
+		b.WriteString("      <p v-if=\"loadError\" class=\"form-error\" role=\"alert\">{{ loadError }}</p>\n")
+		b.WriteString("      <form v-if=\"profile\" class=\"form\" aria-labelledby=\"profile-title\" @submit.prevent=\"saveProfile\">\n")
+		for _, f := range fields {
+			in := formInput{
+				id:        "profile-" + toKebabCase(toCamelCase(f.Name)),
+				name:      f.Name,
+				label:     ir.FieldLabel(f.Name),
+				inputType: inputType(f.Name, f),
+				required:  f.Required && f.Type != "boolean",
+			}
+			if f.Type == "boolean" {
+				in.inputType = "checkbox"
+			}
+			writeFormFieldVue(&b, "        ", in, "profileDraft."+f.Name, ctx)
+		}
+		b.WriteString("        <p v-if=\"profileError\" class=\"form-error\" role=\"alert\">{{ profileError }}</p>\n")
+		b.WriteString("        <p v-if=\"profileSaved\" class=\"form-success\" role=\"status\">Your profile was saved</p>\n")
+		b.WriteString("        <button type=\"submit\">Save profile</button>\n")
+		b.WriteString("      </form>\n")
+		b.WriteString("    </section>\n")
+	}
+
+	if password != nil {
+		b.WriteString("    <section aria-labelledby=\"password-title\">\n")
+		b.WriteString("      <h2 id=\"password-title\">Change password</h2>\n")
+		b.WriteString("      <form class=\"form\" aria-labelledby=\"password-title\" @submit.prevent=\"savePassword\">\n")
+		writePasswordFieldVue(&b, "        ", "current-password", "currentPassword", "Current password", "current-password", false)
+		writePasswordFieldVue(&b, "        ", "new-password", "newPassword", "New password", "new-password", true)
+		writePasswordFieldVue(&b, "        ", "confirm-password", "confirmPassword", "Confirm new password", "new-password", true)
+		b.WriteString("        <p v-if=\"passwordError\" class=\"form-error\" role=\"alert\">{{ passwordError }}</p>\n")
+		b.WriteString("        <p v-if=\"passwordChanged\" class=\"form-success\" role=\"status\">Your password was changed</p>\n")
+		b.WriteString("        <button type=\"submit\">Change password</button>\n")
+		b.WriteString("      </form>\n")
+		b.WriteString("    </section>\n")
+	}
+
+	if remove != nil {
+		b.WriteString("    <section class=\"danger-zone\" aria-labelledby=\"delete-title\">\n")
+		b.WriteString("      <h2 id=\"delete-title\">Delete account</h2>\n")
+		b.WriteString("      <p>Deleting your account removes it and everything in it for good.</p>\n")
+		b.WriteString("      <button type=\"button\" class=\"danger\" @click=\"confirmingDelete = true\">Delete account</button>\n")
+		b.WriteString("      <div v-if=\"confirmingDelete\" class=\"confirm-dialog\" role=\"alertdialog\" aria-modal=\"true\" aria-labelledby=\"delete-confirm-title\" aria-describedby=\"delete-confirm-description\" @keydown.esc=\"cancelDelete\">\n")
+		b.WriteString("        <h2 id=\"delete-confirm-title\">Delete your account?</h2>\n")
+		fmt.Fprintf(&b, "        <p id=\"delete-confirm-description\">This can't be undone. Type %s and enter your password to confirm.</p>\n", ir.DeleteConfirmation)
+		b.WriteString("        <form class=\"form\" aria-labelledby=\"delete-confirm-title\" @submit.prevent=\"removeAccount\">\n")
+		b.WriteString("          <div class=\"form-field\">\n")
+		fmt.Fprintf(&b, "            <label for=\"delete-confirmation\">Type %s to confirm</label>\n", ir.DeleteConfirmation)
+		b.WriteString("            <input id=\"delete-confirmation\" v-model=\"deleteConfirmation\" type=\"text\" autocomplete=\"off\" autofocus required />\n")
+		b.WriteString("          </div>\n")
+		writePasswordFieldVue(&b, "          ", "delete-password", "deletePassword", "Password", "current-password", false)
+		b.WriteString("          <p v-if=\"deleteError\" class=\"form-error\" role=\"alert\">{{ deleteError }}</p>\n")
+		fmt.Fprintf(&b, "          <button type=\"submit\" class=\"danger\" :disabled=\"deleteConfirmation !== '%s' || deleting\">Delete my account</button>\n", ir.DeleteConfirmation)
+		b.WriteString("          <button type=\"button\" @click=\"cancelDelete\">Cancel</button>\n")
+		b.WriteString("        </form>\n")
+		b.WriteString("      </div>\n")
+		b.WriteString("    </section>\n")
+	}
+
+	b.WriteString("  </div>\n")
+	b.WriteString("</template>\n")
+	return b.String()
+}
+
+// writePasswordFieldVue renders a password input with its label, bound to
+// model and filled in by password managers as autocomplete says. A new
+// password must be MinPasswordLength characters long.
+func writePasswordFieldVue(b *strings.Builder, indent, id, model, label, autocomplete string, isNew bool) {
+	fmt.Fprintf(b, "%s<div class=\"form-field\">\n", indent)
+	fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, id, label)
+	minLength := ""
+	if isNew {
+		minLength = fmt.Sprintf(" minlength=\"%d\"", ir.MinPasswordLength)
+	}
+	fmt.Fprintf(b, "%s  <input id=\"%s\" v-model=\"%s\" type=\"password\" autocomplete=\"%s\"%s required />\n", indent, id, model, autocomplete, minLength)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
@@ -11,41 +11,132 @@ func generateAPIClient(app *ir.Application) string {
 	var b strings.Builder
 
 	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	var types []string
+	if len(app.Notifications) > 0 {
+		types = append(types, "Notification")
+	}
+	if ir.AccountModel(app) != nil {
+		types = append(types, app.Accounts.Model)
+	}
+	if len(types) > 0 {
+		fmt.Fprintf(&b, "import type { %s } from '../types/models';\n\n", strings.Join(types, ", "))
+	}
 
 	b.WriteString("const API_BASE_URL = import.meta.env.VITE_API_URL || '';\n\n")
 	b.WriteString(`export interface ApiResponse<T> {
   data: T;
   error?: string;
+  pagination?: { limit: number; nextCursor: string | null };
+}
+
+export class ApiError extends Error {
+  readonly status: number;
+
+  constructor(status: number, message: string) {
+    super(message);
+    this.name = 'ApiError';
+    this.status = status;
+  }
+}
+
+export function errorMessage(err: unknown, fallback: string): string {
+  return err instanceof ApiError && err.message ? err.message : fallback;
 }
 `)
+	if ir.ProtectsEdits(app) {
+		writeConflictError(&b)
+	}
+	if ir.StoresUTC(app) {
+		writeTimeClient(&b)
+	}
 
+	// Failed requests throw an ApiError carrying the status and the
+	// server's error message
 	b.WriteString(`
 export async function request<T>(
   method: string,
   path: string,
   body?: Record<string, unknown>,
-): Promise<ApiResponse<T>> {
+`)
+	if ir.ProtectsEdits(app) {
+		b.WriteString("  extraHeaders: Record<string, string> = {},\n")
+	}
+	b.WriteString(`): Promise<ApiResponse<T>> {
   const token = localStorage.getItem('token');
   const headers: Record<string, string> = {
     'Content-Type': 'application/json',
-  };
+`)
+	if ir.ProtectsEdits(app) {
+		b.WriteString("    ...extraHeaders,\n")
+	}
+	b.WriteString(`  };
   if (token) {
     headers['Authorization'] = ` + "`Bearer ${token}`" + `;
   }
   const res = await fetch(` + "`${API_BASE_URL}${path}`" + `, {
     method,
     headers,
-    body: body ? JSON.stringify(body) : undefined,
-  });
-  return res.json();
+`)
+	if ir.StoresUTC(app) {
+		b.WriteString("    body: body ? JSON.stringify(utcTimes(body)) : undefined,\n")
+	} else {
+		b.WriteString("    body: body ? JSON.stringify(body) : undefined,\n")
+	}
+	b.WriteString(`  });
+  const json = await res.json().catch(() => ({}));
+  if (!res.ok) {
+`)
+	if ir.ProtectsEdits(app) {
+		b.WriteString("    if (res.status === 409 && json.current) {\n")
+		b.WriteString("      throw new ConflictError(json.error ?? res.statusText, json.current);\n")
+		b.WriteString("    }\n")
+	}
+	b.WriteString(`    throw new ApiError(res.status, json.error ?? res.statusText);
+  }
+  return json;
 }
 `)
 
+	if hasReports(app) {
+		writeReportType(&b)
+	}
+	if ir.HasImports(app) {
+		writeImportClient(&b)
+	}
+	if ir.AccountModel(app) != nil {
+		writeProfileType(&b, app)
+	}
+
 	for _, ep := range app.APIs {
 		b.WriteString("\n")
+		if m := ir.ConflictModel(app, ep); m != nil && len(ep.Params) > 0 {
+			writeVersionedUpdateFunction(&b, ep, m)
+			continue
+		}
+		if ep.Import != nil {
+			writeImportFunction(&b, ep)
+			continue
+		}
+		if ep.Account != "" && app.Accounts != nil {
+			writeAccountFunction(&b, ep, app)
+			continue
+		}
 		writeEndpointFunction(&b, ep)
 	}
 
+	if len(app.Notifications) > 0 {
+		writeNotificationsClient(&b)
+	}
+	if len(app.Calendars) > 0 {
+		writeCalendarClient(&b)
+	}
+	if len(app.Charts) > 0 {
+		writeChartClient(&b)
+	}
+	if len(app.Reorders) > 0 {
+		writeReorderClient(&b)
+	}
+
 	return b.String()
 }
 
@@ -53,6 +144,10 @@ func writeEndpointFunction(b *strings.Builder, ep *ir.Endpoint) {
 	funcName := toCamelCase(ep.Name)
 	method := httpMethod(ep.Name)
 	path := apiPath(ep.Name)
+	response := "unknown"
+	if ep.Aggregate != nil {
+		response = "ReportRow[]"
+	}
 
 	if len(ep.Params) > 0 {
 		paramFields := make([]string, len(ep.Params))
@@ -61,16 +156,30 @@ func writeEndpointFunction(b *strings.Builder, ep *ir.Endpoint) {
 		}
 		paramType := fmt.Sprintf("{ %s }", strings.Join(paramFields, "; "))
 
-		fmt.Fprintf(b, "export async function %s(params: %s) {\n", funcName, paramType)
-		if method == "GET" {
+		if method == "GET" && ep.PageSize > 0 {
+			// Paginated lists take the nextCursor of the page before
+			fmt.Fprintf(b, "export async function %s(params: %s, cursor?: string) {\n", funcName, paramType)
+			b.WriteString("  const qs = new URLSearchParams(params as unknown as Record<string, string>);\n")
+			b.WriteString("  if (cursor) qs.set('cursor', cursor);\n")
+			fmt.Fprintf(b, "  return request<%s>('%s', `%s?${qs}`);\n", response, method, path)
+		} else if method == "GET" {
+			fmt.Fprintf(b, "export async function %s(params: %s) {\n", funcName, paramType)
 			b.WriteString("  const qs = new URLSearchParams(params as unknown as Record<string, string>).toString();\n")
-			fmt.Fprintf(b, "  return request<unknown>('%s', `%s?${qs}`);\n", method, path)
+			fmt.Fprintf(b, "  return request<%s>('%s', `%s?${qs}`);\n", response, method, path)
 		} else {
-			fmt.Fprintf(b, "  return request<unknown>('%s', '%s', params as unknown as Record<string, unknown>);\n", method, path)
+			fmt.Fprintf(b, "export async function %s(params: %s) {\n", funcName, paramType)
+			fmt.Fprintf(b, "  return request<%s>('%s', '%s', params as unknown as Record<string, unknown>);\n", response, method, path)
 		}
+	} else if method == "GET" && ep.PageSize > 0 {
+		// and the page's search, filters, sort, and page
+		fmt.Fprintf(b, "export async function %s(cursor?: string, query: Record<string, string> = {}) {\n", funcName)
+		b.WriteString("  const qs = new URLSearchParams(query);\n")
+		b.WriteString("  if (cursor) qs.set('cursor', cursor);\n")
+		b.WriteString("  const search = qs.toString();\n")
+		fmt.Fprintf(b, "  return request<%s>('%s', search ? `%s?${search}` : '%s');\n", response, method, path, path)
 	} else {
 		fmt.Fprintf(b, "export async function %s() {\n", funcName)
-		fmt.Fprintf(b, "  return request<unknown>('%s', '%s');\n", method, path)
+		fmt.Fprintf(b, "  return request<%s>('%s', '%s');\n", response, method, path)
 	}
 	b.WriteString("}\n")
 }
@@ -81,3 +190,10 @@ func sanitizeParamName(name string) string {
 	}
 	return toCamelCase(name)
 }
+
+// generateEnvProduction produces .env.production for a build targeting an
+// environment: `vite build` reads it, so the built frontend calls the
+// environment's API rather than the dev server's.
+func generateEnvProduction(env *ir.Environment, app *ir.Application) string {
+	return fmt.Sprintf("# Generated by Human compiler — do not edit\n# API URL of the %s environment, read by vite build\nVITE_API_URL=%s\n", env.Name, env.APIURL(app))
+}
@@ -30,20 +30,39 @@ func generateApp(app *ir.Application) string {
 	if app.Theme != nil {
 		b.WriteString("import './assets/global.css';\n")
 	}
+	if ir.Styling(app) == ir.StylingTailwind {
+		b.WriteString("import './assets/tailwind.css';\n")
+	}
+
+	b.WriteString("import ErrorBoundary from './components/ErrorBoundary.vue';\n")
+	if len(app.Notifications) > 0 {
+		b.WriteString("import NotificationBell from './components/NotificationBell.vue';\n")
+	}
 
 	b.WriteString("</script>\n\n")
 
 	b.WriteString("<template>\n")
 
+	bell := ""
+	if len(app.Notifications) > 0 {
+		bell = "    <NotificationBell />\n"
+	}
+
 	// Wrap in design system root component if needed
 	switch systemID {
 	case "material":
 		b.WriteString("  <v-app>\n")
-		b.WriteString("    <router-view></router-view>\n")
+		b.WriteString(bell)
+		b.WriteString("    <ErrorBoundary>\n")
+		b.WriteString("      <router-view></router-view>\n")
+		b.WriteString("    </ErrorBoundary>\n")
 		b.WriteString("  </v-app>\n")
 	default:
 		b.WriteString("  <div id=\"app\">\n")
-		b.WriteString("    <router-view></router-view>\n")
+		b.WriteString(bell)
+		b.WriteString("    <ErrorBoundary>\n")
+		b.WriteString("      <router-view></router-view>\n")
+		b.WriteString("    </ErrorBoundary>\n")
 		b.WriteString("  </div>\n")
 	}
 
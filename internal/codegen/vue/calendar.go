@@ -0,0 +1,37 @@
+package vue
+
+import "strings"
+
+// writeCalendarClient appends the calendar feed URLs to the API client. The
+// feeds are fetched by the browser or a calendar app, not by request().
+func writeCalendarClient(b *strings.Builder) {
+	b.WriteString(`
+// calendarUrl returns a calendar's ICS feed, or one record's .ics download.
+export function calendarUrl(feed: string, id?: string): string {
+  const path = id ? ` + "`${feed}/${encodeURIComponent(id)}`" + ` : feed;
+  return new URL(` + "`${API_BASE_URL}/api/calendar/${path}.ics`" + `, window.location.href).href;
+}
+`)
+}
+
+// generateAddToCalendar produces src/components/AddToCalendar.vue. Given a
+// record id it downloads that record's .ics file; without one it
+// subscribes to the whole feed through a webcal:// link.
+func generateAddToCalendar() string {
+	return `<!-- Generated by Human compiler — do not edit -->
+<script setup lang="ts">
+import { computed } from 'vue';
+import { calendarUrl } from '../api/client';
+
+const props = defineProps<{ feed: string; id?: string }>();
+
+const url = computed(() => calendarUrl(props.feed, props.id));
+const subscribeUrl = computed(() => url.value.replace(/^https?:/, 'webcal:'));
+</script>
+
+<template>
+  <a v-if="id" class="add-to-calendar" :href="url" download>Add to calendar</a>
+  <a v-else class="add-to-calendar" :href="subscribeUrl">Subscribe to calendar</a>
+</template>
+`
+}
@@ -0,0 +1,152 @@
+package vue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeChartClient appends the chart endpoint to the API client.
+func writeChartClient(b *strings.Builder) {
+	b.WriteString(`
+export interface ChartPoint {
+  label: string;
+  value: number;
+}
+
+// fetchChart loads a chart's points from its aggregate endpoint.
+export async function fetchChart(source: string) {
+  return request<ChartPoint[]>('GET', ` + "`/api/charts/${source}`" + `);
+}
+`)
+}
+
+// generateDataChart produces src/components/DataChart.vue: a Chart.js bar,
+// line, or pie chart of the points an aggregate endpoint returns, drawn in
+// the theme's primary color.
+func generateDataChart() string {
+	return `<!-- Generated by Human compiler — do not edit -->
+<script setup lang="ts">
+import { nextTick, onBeforeUnmount, onMounted, ref } from 'vue';
+import { Chart, registerables } from 'chart.js';
+import { fetchChart, type ChartPoint } from '../api/client';
+
+Chart.register(...registerables);
+
+const props = defineProps<{ kind: 'bar' | 'line' | 'pie'; title: string; source: string }>();
+
+const PALETTE = ['#6366f1', '#22c55e', '#f59e0b', '#ef4444', '#06b6d4', '#a855f7', '#ec4899', '#84cc16'];
+
+const canvas = ref<HTMLCanvasElement | null>(null);
+const points = ref<ChartPoint[]>([]);
+const loading = ref(true);
+const error = ref('');
+let chart: Chart | null = null;
+
+// The theme's primary color, for bars, lines, and the first slice.
+function primaryColor(): string {
+  return getComputedStyle(document.documentElement).getPropertyValue('--color-primary').trim() || PALETTE[0];
+}
+
+function draw() {
+  if (!canvas.value) return;
+  const color = primaryColor();
+  const colors = props.kind === 'pie'
+    ? points.value.map((_, i) => (i === 0 ? color : PALETTE[i % PALETTE.length]))
+    : color;
+  chart = new Chart(canvas.value, {
+    type: props.kind,
+    data: {
+      labels: points.value.map((p) => p.label),
+      datasets: [{
+        label: props.title,
+        data: points.value.map((p) => p.value),
+        backgroundColor: colors,
+        borderColor: colors,
+      }],
+    },
+    options: {
+      responsive: true,
+      maintainAspectRatio: false,
+      plugins: { legend: { display: props.kind === 'pie' } },
+    },
+  });
+}
+
+onMounted(async () => {
+  try {
+    const res = await fetchChart(props.source);
+    if (res.error) {
+      error.value = res.error;
+    } else {
+      points.value = res.data ?? [];
+    }
+  } catch {
+    error.value = 'Could not load the chart';
+  } finally {
+    loading.value = false;
+  }
+  if (points.value.length > 0) {
+    await nextTick();
+    draw();
+  }
+});
+
+onBeforeUnmount(() => chart?.destroy());
+</script>
+
+<template>
+  <figure class="chart">
+    <figcaption class="chart-title">{{ title }}</figcaption>
+    <p v-if="loading" class="chart-status">Loading...</p>
+    <p v-else-if="error" class="chart-status" role="alert">{{ error }}</p>
+    <p v-else-if="points.length === 0" class="chart-status">No data yet</p>
+    <div v-show="!loading && !error && points.length > 0" class="chart-canvas" style="height: 300px">
+      <canvas ref="canvas"></canvas>
+    </div>
+  </figure>
+</template>
+`
+}
+
+// chartAttr escapes a chart title for an attribute value.
+var chartAttr = strings.NewReplacer("&", "&amp;", "\"", "&quot;", "<", "&lt;", ">", "&gt;", "{{", "&#123;&#123;")
+
+// writeChartVue emits the chart a "show a bar chart of ..." action draws.
+func writeChartVue(b *strings.Builder, c *ir.Chart, indent string) {
+	fmt.Fprintf(b, "%s<DataChart kind=\"%s\" title=\"%s\" source=\"%s\" />\n", indent, c.Kind, chartAttr.Replace(c.Title), c.Slug)
+}
+
+// pageHasChart reports whether the page draws a chart from an aggregate
+// endpoint.
+func pageHasChart(page *ir.Page, app *ir.Application) bool {
+	for _, a := range page.Content {
+		if ir.ChartFor(app, a) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReports reports whether any endpoint serves an aggregate.
+func hasReports(app *ir.Application) bool {
+	for _, ep := range app.APIs {
+		if ep.Aggregate != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// writeReportType declares the rows report endpoints return.
+func writeReportType(b *strings.Builder) {
+	b.WriteString(`
+// ReportRow is one group of a report: its label and the count, sum,
+// average, minimum, or maximum of its records.
+export interface ReportRow {
+  label: string;
+  value: number;
+}
+`)
+}
@@ -30,6 +30,10 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 		}
 		fmt.Fprintf(&b, "import type { %s } from '../types/models';\n", strings.Join(models, ", "))
 	}
+	tips, tipProp := componentTooltips(comp, app)
+	if len(tips) > 0 {
+		b.WriteString("import Tooltip from './Tooltip.vue';\n")
+	}
 
 	if len(comp.Props) > 0 {
 		b.WriteString("defineProps<{\n")
@@ -46,7 +50,7 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 		}
 		b.WriteString("}>();\n")
 	}
-	
+
 	if hasClickHandler(comp) {
 		b.WriteString("\ndefineEmits<{ (e: 'click'): void }>();\n")
 	}
@@ -66,12 +70,22 @@ func generateComponent(comp *ir.Component, app *ir.Application) string {
 		propsMap[p.Name] = p.Type
 	}
 	ctx := &pageContext{
-		app:   app,
-		props: propsMap,
+		app:      app,
+		props:    propsMap,
+		tooltips: tips,
 	}
 
+	// Hovering anywhere over the component shows its tooltip
+	indent := "    "
+	if len(tips) > 0 {
+		fmt.Fprintf(&b, "%s<Tooltip %s>\n", indent, tooltipText(tips[0], tipProp))
+		indent += "  "
+	}
 	for _, a := range comp.Content {
-		writePageActionVue(&b, a, "    ", ctx)
+		writePageActionVue(&b, a, indent, ctx)
+	}
+	if len(tips) > 0 {
+		b.WriteString("    </Tooltip>\n")
 	}
 
 	b.WriteString("  </div>\n")
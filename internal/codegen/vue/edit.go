@@ -0,0 +1,233 @@
+package vue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeConflictError writes the error an update made to a record someone
+// else has since saved fails with, carrying the record as it is now.
+func writeConflictError(b *strings.Builder) {
+	b.WriteString(`
+export class ConflictError extends ApiError {
+  readonly current: unknown;
+
+  constructor(message: string, current: unknown) {
+    super(409, message);
+    this.name = 'ConflictError';
+    this.current = current;
+  }
+}
+`)
+}
+
+// writeVersionedUpdateFunction writes the client function of an update to
+// a record protected against conflicting edits: it takes the record's
+// fields as the model types them, and sends the version of the record the
+// changes were made to in an If-Match header.
+func writeVersionedUpdateFunction(b *strings.Builder, ep *ir.Endpoint, m *ir.DataModel) {
+	paramFields := make([]string, len(ep.Params))
+	for i, p := range ep.Params {
+		paramType := "string"
+		if f := m.FieldNamed(p.Name); f != nil && f.Type != "enum" && f.Type != "json" {
+			paramType = tsType(f.Type)
+		}
+		paramFields[i] = fmt.Sprintf("%s: %s", sanitizeParamName(p.Name), paramType)
+	}
+	fmt.Fprintf(b, "export async function %s(params: { %s }, version: number) {\n", toCamelCase(ep.Name), strings.Join(paramFields, "; "))
+	fmt.Fprintf(b, "  return request<%s>('%s', '%s', params as unknown as Record<string, unknown>, { 'If-Match': `\"${version}\"` });\n",
+		m.Name, httpMethod(ep.Name), apiPath(ep.Name))
+	b.WriteString("}\n")
+}
+
+// editImports returns what a page's edit form uses from the API client.
+func editImports(e *ir.EditForm) []string {
+	imports := []string{toCamelCase(e.Endpoint.Name)}
+	if e.Versioned {
+		imports = append(imports, "ConflictError")
+	}
+	return imports
+}
+
+// writeEditScript declares the draft of the form editing the record a
+// detail route's page shows, filled in with the record whenever it loads
+// or is saved, and the functions saving it. A versioned record's changes
+// are made to the version the draft was filled in from; when someone else
+// saved first, the changes are held until the user keeps them, saving them
+// over the record as it is now, or uses the saved record instead.
+func writeEditScript(b *strings.Builder, ctx *pageContext) {
+	e := ctx.edit
+	v := recordVar(ctx)
+	fn := toCamelCase(e.Endpoint.Name)
+	draft := v + "Draft"
+	label := strings.ToLower(ir.FieldLabel(e.Model.Name))
+
+	var initial, filled []string
+	for _, f := range e.Fields {
+		initial = append(initial, fmt.Sprintf("%s: %s", f.Name, draftZero(f)))
+		value := editValue("value", f)
+		if inputType(f.Name, f) == "datetime-local" {
+			if local := localTimeValue("value."+f.Name, ctx); local != "" {
+				value = local
+			}
+		}
+		filled = append(filled, fmt.Sprintf("%s: %s", f.Name, value))
+	}
+	fmt.Fprintf(b, "\nconst %s = ref({ %s });\n", draft, strings.Join(initial, ", "))
+	b.WriteString("const editError = ref('');\n")
+	if e.Versioned {
+		fmt.Fprintf(b, "const conflict = ref<{ current: %s; changes: Parameters<typeof %s>[0] } | null>(null);\n", e.Model.Name, fn)
+	}
+	fmt.Fprintf(b, "\nwatch(%s, (value) => {\n", v)
+	fmt.Fprintf(b, "  if (value) %s.value = { %s };\n", draft, strings.Join(filled, ", "))
+	b.WriteString("});\n")
+
+	if e.Versioned {
+		fmt.Fprintf(b, "\nasync function save%s(changes: Parameters<typeof %s>[0], version: number) {\n", e.Model.Name, fn)
+	} else {
+		fmt.Fprintf(b, "\nasync function save%s(changes: Parameters<typeof %s>[0]) {\n", e.Model.Name, fn)
+	}
+	b.WriteString("  editError.value = '';\n")
+	b.WriteString("  try {\n")
+	if e.Versioned {
+		fmt.Fprintf(b, "    const res = await %s(changes, version);\n", fn)
+		b.WriteString("    conflict.value = null;\n")
+	} else {
+		fmt.Fprintf(b, "    const res = await %s(changes);\n", fn)
+	}
+	fmt.Fprintf(b, "    %s.value = res.data;\n", v)
+	b.WriteString("  } catch (err) {\n")
+	if e.Versioned {
+		b.WriteString("    if (err instanceof ConflictError) {\n")
+		fmt.Fprintf(b, "      conflict.value = { current: err.current as %s, changes };\n", e.Model.Name)
+		b.WriteString("    } else {\n")
+		fmt.Fprintf(b, "      editError.value = errorMessage(err, 'Could not save the %s');\n", label)
+		b.WriteString("    }\n")
+	} else {
+		fmt.Fprintf(b, "    editError.value = errorMessage(err, 'Could not save the %s');\n", label)
+	}
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+
+	fmt.Fprintf(b, "\nfunction submit%s() {\n", e.Model.Name)
+	fmt.Fprintf(b, "  if (!%s.value) return;\n", v)
+	fmt.Fprintf(b, "  save%s({\n", e.Model.Name)
+	for _, p := range e.Endpoint.Params {
+		name := sanitizeParamName(p.Name)
+		f := editField(e, p.Name)
+		switch {
+		case p.Name == e.Param:
+			fmt.Fprintf(b, "    %s: %s.value.id,\n", name, v)
+		case f == nil:
+			fmt.Fprintf(b, "    %s: '',\n", name)
+		case e.Versioned || tsType(f.Type) == "string":
+			fmt.Fprintf(b, "    %s: %s.value.%s,\n", name, draft, f.Name)
+		default:
+			fmt.Fprintf(b, "    %s: String(%s.value.%s),\n", name, draft, f.Name)
+		}
+	}
+	if e.Versioned {
+		fmt.Fprintf(b, "  }, %s.value.%s);\n", v, ir.VersionColumn)
+	} else {
+		b.WriteString("  });\n")
+	}
+	b.WriteString("}\n")
+
+	if e.Versioned {
+		fmt.Fprintf(b, "\nfunction useTheir%s() {\n", e.Model.Name)
+		b.WriteString("  if (!conflict.value) return;\n")
+		fmt.Fprintf(b, "  %s.value = conflict.value.current;\n", v)
+		b.WriteString("  conflict.value = null;\n")
+		b.WriteString("}\n")
+	}
+}
+
+// writeEditFormVue renders the form editing the record a detail route's
+// page shows, bound to its draft. A versioned record's form is followed by
+// the prompt shown when someone else saved it first.
+func writeEditFormVue(b *strings.Builder, indent string, ctx *pageContext) {
+	e := ctx.edit
+	v := recordVar(ctx)
+	label := strings.ToLower(ir.FieldLabel(e.Model.Name))
+	fmt.Fprintf(b, "%s<form v-if=\"%s\" class=\"form edit-form\" aria-label=\"Edit %s\" @submit.prevent=\"submit%s\">\n", indent, v, label, e.Model.Name)
+	formID := ctx.uniqueID(toKebabCase(e.Model.Name) + "-edit")
+	for _, f := range e.Fields {
+		in := formInput{
+			id:        formID + "-" + toKebabCase(toCamelCase(f.Name)),
+			name:      sanitizeParamName(paramFor(e, f)),
+			label:     ir.FieldLabel(f.Name),
+			inputType: inputType(f.Name, f),
+			required:  f.Required && f.Type != "boolean",
+		}
+		if f.Type == "boolean" {
+			in.inputType = "checkbox"
+		}
+		writeFormFieldVue(b, indent+"  ", in, v+"Draft."+f.Name, ctx)
+	}
+	fmt.Fprintf(b, "%s  <p v-if=\"editError\" class=\"form-error\" role=\"alert\">{{ editError }}</p>\n", indent)
+	fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
+	fmt.Fprintf(b, "%s</form>\n", indent)
+	if !e.Versioned {
+		return
+	}
+	fmt.Fprintf(b, "%s<div v-if=\"conflict\" class=\"conflict-dialog\" role=\"alertdialog\" aria-modal=\"true\" aria-labelledby=\"conflict-title\" aria-describedby=\"conflict-description\">\n", indent)
+	fmt.Fprintf(b, "%s  <h2 id=\"conflict-title\">This %s changed while you were editing</h2>\n", indent, label)
+	fmt.Fprintf(b, "%s  <p id=\"conflict-description\">Someone else saved the %s after you opened it. Keep your changes to save them over theirs, or use their version and discard yours.</p>\n", indent, label)
+	fmt.Fprintf(b, "%s  <button autofocus @click=\"save%s(conflict.changes, conflict.current.%s)\">Keep my changes</button>\n", indent, e.Model.Name, ir.VersionColumn)
+	fmt.Fprintf(b, "%s  <button @click=\"useTheir%s\">Use their version</button>\n", indent, e.Model.Name)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
+
+// recordVar returns the variable holding the record a detail route's page
+// shows.
+func recordVar(ctx *pageContext) string {
+	m := ctx.record.Model
+	return strings.ToLower(m[:1]) + m[1:]
+}
+
+// editField returns the field of an edit form an endpoint parameter fills.
+func editField(e *ir.EditForm, param string) *ir.DataField {
+	for _, f := range e.Fields {
+		if paramFor(e, f) == param {
+			return f
+		}
+	}
+	return nil
+}
+
+// paramFor returns the endpoint parameter a field of an edit form fills.
+func paramFor(e *ir.EditForm, f *ir.DataField) string {
+	for _, p := range e.Endpoint.Params {
+		if strings.EqualFold(p.Name, f.Name) {
+			return p.Name
+		}
+	}
+	return f.Name
+}
+
+// draftZero returns the value an edit form's draft holds for f before the
+// record loads.
+func draftZero(f *ir.DataField) string {
+	switch {
+	case f.Type == "boolean":
+		return "false"
+	case tsType(f.Type) == "number":
+		return "0"
+	}
+	return "''"
+}
+
+// editValue returns the expression filling an edit form's draft of f in
+// from the record v.
+func editValue(v string, f *ir.DataField) string {
+	ref := v + "." + f.Name
+	switch inputType(f.Name, f) {
+	case "date":
+		return ref + "?.slice(0, 10) ?? ''"
+	case "datetime-local":
+		return ref + "?.slice(0, 16) ?? ''"
+	}
+	return ref + " ?? " + draftZero(f)
+}
@@ -0,0 +1,49 @@
+package vue
+
+import (
+	"fmt"
+	"strings"
+)
+
+// generateErrorBoundary produces src/components/ErrorBoundary.vue: it
+// catches errors thrown by the pages below it and shows what went wrong
+// with a button that renders them again.
+func generateErrorBoundary() string {
+	return `<!-- Generated by Human compiler — do not edit -->
+<script setup lang="ts">
+import { onErrorCaptured, ref } from 'vue';
+
+const error = ref<Error | null>(null);
+
+onErrorCaptured((err) => {
+  console.error(err);
+  error.value = err instanceof Error ? err : new Error(String(err));
+  return false;
+});
+</script>
+
+<template>
+  <div v-if="error" class="error-state" role="alert">
+    <h1>Something went wrong</h1>
+    <p>{{ error.message }}</p>
+    <button @click="error = null">Try again</button>
+  </div>
+  <slot v-else />
+</template>
+`
+}
+
+// loadFailed returns the statement recording why a load failed: the
+// server's message, else the page's.
+func loadFailed(ctx *pageContext) string {
+	return fmt.Sprintf("loadError.value = errorMessage(err, '%s')", strings.ReplaceAll(ctx.loadError, "'", "\\'"))
+}
+
+// writeLoadErrorVue renders why loading the page's data failed, with a
+// button loading it again.
+func writeLoadErrorVue(b *strings.Builder, indent string, ctx *pageContext) {
+	fmt.Fprintf(b, "%s<div v-if=\"loadError\" class=\"error-state\" role=\"alert\">\n", indent)
+	fmt.Fprintf(b, "%s  <p>{{ loadError }}</p>\n", indent)
+	fmt.Fprintf(b, "%s  <button @click=\"%s\">Try again</button>\n", indent, ctx.retry)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
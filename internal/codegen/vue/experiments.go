@@ -0,0 +1,103 @@
+package vue
+
+import (
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// controlExperiment returns the experiment whose control (first variant)
+// is the given page. The control page's route serves the experiment.
+func controlExperiment(app *ir.Application, pageName string) *ir.Experiment {
+	for _, exp := range app.Experiments {
+		if len(exp.Variants) > 0 && strings.EqualFold(exp.Variants[0].Page, pageName) {
+			return exp
+		}
+	}
+	return nil
+}
+
+// variantPage returns the declared name of a variant's page, matching
+// case-insensitively as the analyzer does.
+func variantPage(app *ir.Application, name string) string {
+	for _, page := range app.Pages {
+		if strings.EqualFold(page.Name, name) {
+			return page.Name
+		}
+	}
+	return name
+}
+
+// generateExperimentsClient produces src/experiments/client.ts, which loads
+// the visitor's variant assignments and reports exposures to the backend.
+func generateExperimentsClient() string {
+	return `// Generated by Human compiler — do not edit
+
+const API_BASE_URL = import.meta.env.VITE_API_URL || '';
+const VISITOR_KEY = 'hx_visitor';
+
+let assignments: Promise<Record<string, string>> | null = null;
+const exposed = new Set<string>();
+
+function visitorHeaders(): Record<string, string> {
+  const id = localStorage.getItem(VISITOR_KEY);
+  return id ? { 'X-Visitor-Id': id } : {};
+}
+
+// fetchAssignments loads the visitor's variants once per page load. The
+// backend does the bucketing; keeping its visitor id makes assignments
+// sticky even when cookies do not reach a cross-origin API.
+export function fetchAssignments(): Promise<Record<string, string>> {
+  if (!assignments) {
+    assignments = fetch(` + "`${API_BASE_URL}/api/experiments`" + `, { headers: visitorHeaders() })
+      .then((res) => res.json())
+      .then(({ data }) => {
+        localStorage.setItem(VISITOR_KEY, data.visitorId);
+        return data.assignments as Record<string, string>;
+      })
+      .catch(() => ({}));
+  }
+  return assignments;
+}
+
+export async function getVariant(experiment: string): Promise<string | undefined> {
+  return (await fetchAssignments())[experiment];
+}
+
+export function logExposure(experiment: string): void {
+  if (exposed.has(experiment)) return;
+  exposed.add(experiment);
+  fetch(` + "`${API_BASE_URL}/api/experiments/${encodeURIComponent(experiment)}/exposure`" + `, {
+    method: 'POST',
+    headers: visitorHeaders(),
+  }).catch(() => {});
+}
+`
+}
+
+// generateExperimentRoute produces src/experiments/experimentRoute.ts. The
+// route component it builds renders the page for the visitor's assigned
+// variant; until the assignment arrives nothing renders, and if the backend
+// is unreachable the control page renders.
+func generateExperimentRoute() string {
+	return `// Generated by Human compiler — do not edit
+
+import { defineComponent, h, onMounted, ref, type Component } from 'vue';
+import { getVariant, logExposure } from './client';
+
+export function experimentRoute(name: string, control: string, variants: Record<string, Component>) {
+  return defineComponent({
+    name: 'ExperimentRoute',
+    setup() {
+      const variant = ref<string | null>(null);
+      onMounted(async () => {
+        const assigned = await getVariant(name);
+        variant.value = assigned && variants[assigned] ? assigned : control;
+        if (assigned) logExposure(name);
+      });
+      return () => (variant.value ? h(variants[variant.value]) : null);
+    },
+  });
+}
+`
+}
@@ -0,0 +1,167 @@
+package vue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// formInput is one labelled input of a generated form.
+type formInput struct {
+	id        string // unique on the page, e.g. "task-form-title"
+	name      string // the name it's submitted under
+	label     string
+	inputType string
+	required  bool
+}
+
+// formInputs returns the inputs of a form for its fields. A field is
+// required when the form's model declares it so, except a yes/no field,
+// which can be left empty; every field of a login form is required.
+func formInputs(formID string, fields []string, login bool, model *ir.DataModel) []formInput {
+	var inputs []formInput
+	for _, f := range fields {
+		in := formInput{
+			id:        formID + "-" + toKebabCase(toCamelCase(f)),
+			name:      toCamelCase(f),
+			label:     capitalize(f),
+			inputType: inputType(f, nil),
+			required:  login,
+		}
+		if model != nil {
+			if field := model.FieldNamed(f); field != nil {
+				in.inputType = inputType(f, field)
+				in.required = field.Required && field.Type != "boolean"
+			}
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
+
+// formModel returns the model a form creates or edits: the one it names,
+// "a form to create a Task", or else the page's.
+func formModel(lower string, ctx *pageContext) *ir.DataModel {
+	for _, marker := range []string{"form to update ", "form to create ", "form to edit "} {
+		if idx := strings.Index(lower, marker); idx != -1 {
+			rest := strings.TrimPrefix(strings.TrimPrefix(lower[idx+len(marker):], "a "), "an ")
+			if m := findModel(ctx.app, strings.TrimPrefix(strings.TrimSpace(rest), "new ")); m != nil {
+				return m
+			}
+		}
+	}
+	return findModel(ctx.app, ctx.modelName)
+}
+
+// inputType returns the type of the input a form field is entered in, by
+// the model's type for it when known or else by its name.
+func inputType(name string, field *ir.DataField) string {
+	if field != nil {
+		switch field.Type {
+		case "email", "date", "number":
+			return field.Type
+		case "decimal":
+			return "number"
+		case "datetime":
+			return "datetime-local"
+		}
+	}
+	fl := strings.ToLower(name)
+	switch {
+	case strings.Contains(fl, "email"):
+		return "email"
+	case strings.Contains(fl, "password"):
+		return "password"
+	case strings.Contains(fl, "date"):
+		return "date"
+	case strings.Contains(fl, "number") || strings.Contains(fl, "count"):
+		return "number"
+	}
+	return "text"
+}
+
+// inputLabel returns the accessible name of an input described by text:
+// what it's for, e.g. "Event name" for "there is a text input for event
+// name", or the text itself.
+func inputLabel(text string) string {
+	if i := strings.LastIndex(strings.ToLower(text), " for "); i >= 0 && i+5 < len(text) {
+		return capitalize(strings.TrimSpace(text[i+5:]))
+	}
+	return text
+}
+
+// uniqueID returns an id for an element of the page, numbering the ones
+// after the first that would share it.
+func (ctx *pageContext) uniqueID(base string) string {
+	if ctx.ids == nil {
+		ctx.ids = map[string]int{}
+	}
+	ctx.ids[base]++
+	if n := ctx.ids[base]; n > 1 {
+		return fmt.Sprintf("%s-%d", base, n)
+	}
+	return base
+}
+
+// writeFormFieldVue renders a form input with its label, bound to model
+// when given. On a page tracking invalid inputs, while its value is invalid
+// the input is marked so and described by the message below it.
+func writeFormFieldVue(b *strings.Builder, indent string, in formInput, model string, ctx *pageContext) {
+	invalid := fmt.Sprintf("fieldError?.id === '%s'", in.id)
+	fmt.Fprintf(b, "%s<div class=\"form-field\">\n", indent)
+	fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, in.id, in.label)
+	fmt.Fprintf(b, "%s  <input\n", indent)
+	fmt.Fprintf(b, "%s    id=\"%s\"\n", indent, in.id)
+	fmt.Fprintf(b, "%s    type=\"%s\"\n", indent, in.inputType)
+	fmt.Fprintf(b, "%s    name=\"%s\"\n", indent, in.name)
+	if model != "" {
+		fmt.Fprintf(b, "%s    v-model=\"%s\"\n", indent, model)
+	}
+	if in.inputType != "checkbox" {
+		fmt.Fprintf(b, "%s    placeholder=\"%s\"\n", indent, in.label)
+	}
+	if in.required {
+		fmt.Fprintf(b, "%s    required\n", indent)
+	}
+	if !ctx.fieldErrors {
+		fmt.Fprintf(b, "%s  />\n", indent)
+		fmt.Fprintf(b, "%s</div>\n", indent)
+		return
+	}
+	fmt.Fprintf(b, "%s    :aria-invalid=\"%s\"\n", indent, invalid)
+	fmt.Fprintf(b, "%s    :aria-describedby=\"%s ? '%s-error' : undefined\"\n", indent, invalid, in.id)
+	fmt.Fprintf(b, "%s    @invalid=\"showInvalid\"\n", indent)
+	fmt.Fprintf(b, "%s    @input=\"clearInvalid\"\n", indent)
+	fmt.Fprintf(b, "%s  />\n", indent)
+	fmt.Fprintf(b, "%s  <p v-if=\"%s\" id=\"%s-error\" class=\"field-error\">{{ fieldError.message }}</p>\n", indent, invalid, in.id)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
+
+// writeFieldErrorScript declares the state of a page with a form: the
+// input whose value the browser found invalid, first, and why.
+func writeFieldErrorScript(b *strings.Builder) {
+	b.WriteString("const fieldError = ref<{ id: string; message: string } | null>(null);\n")
+	b.WriteString("function showInvalid(ev: Event) {\n")
+	b.WriteString("  const { id, validationMessage } = ev.target as HTMLInputElement;\n")
+	b.WriteString("  fieldError.value ??= { id, message: validationMessage };\n")
+	b.WriteString("}\n")
+	b.WriteString("function clearInvalid(ev: Event) {\n")
+	b.WriteString("  if (fieldError.value?.id === (ev.target as HTMLInputElement).id) fieldError.value = null;\n")
+	b.WriteString("}\n")
+}
+
+// writeDialogFocus moves focus into the form dialog as it opens and back to
+// whatever opened it once it closes.
+func writeDialogFocus(b *strings.Builder) {
+	b.WriteString("\nlet opener: HTMLElement | null = null;\n")
+	b.WriteString("watch(showForm, async (open) => {\n")
+	b.WriteString("  if (!open) {\n")
+	b.WriteString("    opener?.focus();\n")
+	b.WriteString("    return;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  opener = document.activeElement as HTMLElement | null;\n")
+	b.WriteString("  await nextTick();\n")
+	b.WriteString("  dialog.value?.querySelector<HTMLElement>('input, select, textarea')?.focus();\n")
+	b.WriteString("});\n")
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/barun-bash/human/internal/codegen"
 	"github.com/barun-bash/human/internal/codegen/themes"
 	"github.com/barun-bash/human/internal/ir"
 )
@@ -29,14 +30,27 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 	}
 
 	files := map[string]string{
-		filepath.Join(outputDir, "index.html"):                 generateIndexHTML(app),
-		filepath.Join(outputDir, "vite.config.ts"):             generateViteConfig(),
-		filepath.Join(outputDir, "src", "main.ts"):             generateMainTs(),
-		filepath.Join(outputDir, "src", "vite-env.d.ts"):       generateViteEnvDts(),
-		filepath.Join(outputDir, "src", "types", "models.ts"):  generateTypes(app),
-		filepath.Join(outputDir, "src", "api", "client.ts"):    generateAPIClient(app),
-		filepath.Join(outputDir, "src", "router.ts"):           generateRouter(app),
-		filepath.Join(outputDir, "src", "App.vue"):             generateApp(app),
+		filepath.Join(outputDir, "index.html"):                generateIndexHTML(app),
+		filepath.Join(outputDir, "vite.config.ts"):            generateViteConfig(app),
+		filepath.Join(outputDir, "src", "main.ts"):            generateMainTs(),
+		filepath.Join(outputDir, "src", "vite-env.d.ts"):      generateViteEnvDts(),
+		filepath.Join(outputDir, "src", "types", "models.ts"): generateTypes(app),
+		filepath.Join(outputDir, "src", "api", "client.ts"):   generateAPIClient(app),
+		filepath.Join(outputDir, "src", "router.ts"):          generateRouter(app),
+		filepath.Join(outputDir, "src", "App.vue"):            generateApp(app),
+	}
+
+	// The API of the environment the build targets
+	if env := ir.TargetEnvironment(app); env != nil {
+		files[filepath.Join(outputDir, ".env.production")] = generateEnvProduction(env, app)
+	}
+
+	// Numbers shown in the theme's locale
+	if ir.Locale(app) != "" {
+		if err := os.MkdirAll(filepath.Join(outputDir, "src", "utils"), 0755); err != nil {
+			return fmt.Errorf("creating utils directory: %w", err)
+		}
+		files[filepath.Join(outputDir, "src", "utils", "format.ts")] = generateFormat(app)
 	}
 
 	for _, page := range app.Pages {
@@ -59,6 +73,42 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		files[filepath.Join(outputDir, "src", "composables", "useAuth.ts")] = generateAuthComposable(app)
 	}
 
+	// Generate experiment variant routing
+	if len(app.Experiments) > 0 {
+		files[filepath.Join(outputDir, "src", "experiments", "client.ts")] = generateExperimentsClient()
+		files[filepath.Join(outputDir, "src", "experiments", "experimentRoute.ts")] = generateExperimentRoute()
+	}
+
+	// Notifications dropdown when in-app notifications are declared
+	if len(app.Notifications) > 0 {
+		files[filepath.Join(outputDir, "src", "components", "NotificationBell.vue")] = generateNotificationBell()
+	}
+
+	// "Add to calendar" button when calendar feeds are declared
+	if len(app.Calendars) > 0 {
+		files[filepath.Join(outputDir, "src", "components", "AddToCalendar.vue")] = generateAddToCalendar()
+	}
+
+	// Charts backed by aggregate endpoints
+	if len(app.Charts) > 0 {
+		files[filepath.Join(outputDir, "src", "components", "DataChart.vue")] = generateDataChart()
+	}
+
+	files[filepath.Join(outputDir, "src", "components", "ErrorBoundary.vue")] = generateErrorBoundary()
+
+	// Hover tooltips and slide-over detail panels
+	if ir.HasTooltips(app) {
+		files[filepath.Join(outputDir, "src", "components", "Tooltip.vue")] = generateTooltip()
+	}
+	if ir.HasDetailPanels(app) {
+		files[filepath.Join(outputDir, "src", "components", "DetailPanel.vue")] = generateDetailPanel()
+	}
+
+	// Skeleton screens shaped like the records they stand in for
+	if ir.HasSkeletons(app) {
+		files[filepath.Join(outputDir, "src", "components", "Skeleton.vue")] = generateSkeleton(app)
+	}
+
 	// Generate theme files
 	if app.Theme != nil {
 		themeFiles := themes.GenerateVueTheme(app.Theme)
@@ -67,24 +117,12 @@ func (g Generator) Generate(app *ir.Application, outputDir string) error {
 		}
 	}
 
-	for path, content := range files {
-		if err := writeFile(path, content); err != nil {
-			return err
-		}
+	// Styles and tooling of the app's styling system
+	for relPath, content := range themes.StylingFiles(ir.Styling(app), "vue", app.Theme) {
+		files[filepath.Join(outputDir, relPath)] = content
 	}
 
-	return nil
-}
-
-func writeFile(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
-	return nil
+	return codegen.WriteFiles(files, codegen.WriteOptionsFor(app))
 }
 
 func tsType(irType string) string {
@@ -207,8 +245,14 @@ func generateIndexHTML(app *ir.Application) string {
 `, title)
 }
 
-// generateViteConfig produces vite.config.ts for the Vue project.
-func generateViteConfig() string {
+// generateViteConfig produces vite.config.ts for the Vue project. A build
+// targeting an environment lets the dev and preview servers answer on its
+// host.
+func generateViteConfig(app *ir.Application) string {
+	hosts := ""
+	if env := ir.TargetEnvironment(app); env != nil {
+		hosts = fmt.Sprintf("  server: { allowedHosts: ['%[1]s'] },\n  preview: { allowedHosts: ['%[1]s'] },\n", env.Host())
+	}
 	return `// Generated by Human compiler — do not edit
 
 import { defineConfig } from 'vite'
@@ -216,7 +260,7 @@ import vue from '@vitejs/plugin-vue'
 
 export default defineConfig({
   plugins: [vue()],
-})
+` + hosts + `})
 `
 }
 
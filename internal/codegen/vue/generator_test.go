@@ -269,8 +269,8 @@ func TestGenerateRouter(t *testing.T) {
 	if !strings.Contains(output, "import { createRouter, createWebHistory } from 'vue-router'") {
 		t.Error("missing vue-router import")
 	}
-	if !strings.Contains(output, "import HomePage from './pages/HomePage.vue'") {
-		t.Error("missing HomePage import")
+	if !strings.Contains(output, "const HomePage = () => import('./pages/HomePage.vue');") {
+		t.Error("missing lazy HomePage import")
 	}
 	if !strings.Contains(output, `path: '/'`) {
 		t.Error("missing Home route at /")
@@ -575,8 +575,8 @@ func TestFullIntegration(t *testing.T) {
 	}
 	client := string(clientContent)
 	funcCount := strings.Count(client, "export async function ")
-	if funcCount != 9 {
-		t.Errorf("client.ts: expected 9 functions (8 endpoints + request helper), got %d", funcCount)
+	if funcCount != 10 {
+		t.Errorf("client.ts: expected 10 functions (8 endpoints + request helper + reorder), got %d", funcCount)
 	}
 
 	routerContent, err := os.ReadFile(filepath.Join(dir, "src", "router.ts"))
@@ -594,3 +594,848 @@ func TestFullIntegration(t *testing.T) {
 
 	t.Logf("Generated %d files to %s", len(expectedFiles), dir)
 }
+
+func TestVueExperimentRoutes(t *testing.T) {
+	app := &ir.Application{
+		Name:  "Shop",
+		Pages: []*ir.Page{{Name: "Home"}, {Name: "Classic"}, {Name: "NewPricing"}},
+		Experiments: []*ir.Experiment{
+			{Name: "pricing-page", Audience: "visitors", Variants: []*ir.ExperimentVariant{{Page: "Classic", Weight: 50}, {Page: "NewPricing", Weight: 50}}},
+		},
+	}
+
+	output := generateRouter(app)
+	for _, want := range []string{
+		"import { experimentRoute } from './experiments/experimentRoute';",
+		"component: experimentRoute('pricing-page', 'Classic', { Classic: defineAsyncComponent(ClassicPage), NewPricing: defineAsyncComponent(NewPricingPage) })",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("router missing %q", want)
+		}
+	}
+}
+
+func TestNotificationBellWired(t *testing.T) {
+	app := &ir.Application{
+		Name:          "TaskFlow",
+		Pages:         []*ir.Page{{Name: "Home"}},
+		Notifications: []*ir.Notification{{Model: "Task", Event: "assigned", Message: "Your task was assigned"}},
+	}
+
+	output := generateApp(app)
+	for _, want := range []string{"import NotificationBell from './components/NotificationBell.vue'", "<NotificationBell />"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("App.vue missing %q", want)
+		}
+	}
+	if !strings.Contains(generateAPIClient(app), "export async function getUnreadNotificationCount()") {
+		t.Error("client.ts should fetch the unread count")
+	}
+}
+
+func TestAddToCalendarWired(t *testing.T) {
+	app := &ir.Application{
+		Name: "EventHub",
+		Data: []*ir.DataModel{{Name: "Event", Fields: []*ir.DataField{
+			{Name: "name", Type: "text"},
+			{Name: "date", Type: "datetime"},
+		}}},
+		Calendars: []*ir.CalendarFeed{{Model: "Event", Slug: "events", Title: "name", Start: "date", Timezone: "UTC"}},
+	}
+	page := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all events"},
+		{Type: "loop", Text: "each event shows its name and date"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"import AddToCalendar from '../components/AddToCalendar.vue';", `<AddToCalendar feed="events" :id="event.id" />`, `<AddToCalendar feed="events" />`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.vue missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateAPIClient(app), "export function calendarUrl(feed: string, id?: string): string") {
+		t.Error("client.ts should build calendar feed URLs")
+	}
+
+	app.Calendars = nil
+	if strings.Contains(generatePage(page, app), "AddToCalendar") {
+		t.Error("pages should not render the button without calendars")
+	}
+}
+
+func TestCanonicalLinkWired(t *testing.T) {
+	app := &ir.Application{
+		Name:    "Shop",
+		Pages:   []*ir.Page{{Name: "Home"}},
+		Sitemap: &ir.Sitemap{URL: "https://shop.example.com", Pages: []string{"/"}},
+	}
+
+	router := generateRouter(app)
+	for _, want := range []string{"import.meta.env.VITE_SITE_URL || 'https://shop.example.com'", "router.afterEach((to) => {"} {
+		if !strings.Contains(router, want) {
+			t.Errorf("router missing %q:\n%s", want, router)
+		}
+	}
+	app.Sitemap = nil
+	if strings.Contains(generateRouter(app), "canonical") {
+		t.Error("router should not set canonical links without a sitemap")
+	}
+}
+
+func TestShowTemplate(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "User", Fields: []*ir.DataField{{Name: "name", Type: "text"}}}},
+		Pages: []*ir.Page{{Name: "Profile", Content: []*ir.Action{
+			{Type: "query", Text: "fetch all users"},
+			{Type: "display", Text: "show Hello", Template: "Hello, {user.name}! <3 {1}"},
+		}}},
+		Components: []*ir.Component{{Name: "UserCard", Props: []*ir.Prop{{Name: "user", Type: "User"}}, Content: []*ir.Action{
+			{Type: "display", Text: "show Signed in", Template: "Signed in as {user.name}"},
+		}}},
+	}
+
+	if page := generatePage(app.Pages[0], app); !strings.Contains(page, "<p>Hello, {{ users[0]?.name }}! &lt;3 &#123;1&#125;</p>") {
+		t.Errorf("page should interpolate the first record and escape the text:\n%s", page)
+	}
+	if comp := generateComponent(app.Components[0], app); !strings.Contains(comp, "<p>Signed in as {{ user.name }}</p>") {
+		t.Errorf("component should interpolate the prop:\n%s", comp)
+	}
+}
+
+func TestTablePage(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "User", Fields: []*ir.DataField{
+			{Name: "name", Type: "text"}, {Name: "email", Type: "email"}, {Name: "active", Type: "boolean"},
+		}}},
+		Pages: []*ir.Page{
+			{Name: "Users", Content: []*ir.Action{
+				{Type: "display", Text: "show users in a table sorted by name"},
+				{Type: "interact", Text: "clicking a user navigates to UserDetail"},
+			}},
+			{Name: "UserDetail"},
+		},
+	}
+
+	page := generatePage(app.Pages[0], app)
+	for _, want := range []string{"<th>Email</th>", "@click=\"sortBy('name')\"", "v-for=\"user in visibleUsers\"", "router.push('/user-detail?id=' + user.id)", "<td>{{ user.active ? 'Yes' : 'No' }}</td>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("table page missing %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, ">user</button>") {
+		t.Error("the row click should not also render a button")
+	}
+}
+
+func TestChartWired(t *testing.T) {
+	app := &ir.Application{
+		Name: "Shop",
+		Data: []*ir.DataModel{{Name: "Product", Fields: []*ir.DataField{
+			{Name: "name", Type: "text"},
+			{Name: "category", Type: "text"},
+			{Name: "current_stock", Type: "number"},
+		}}},
+		Charts: []*ir.Chart{{Kind: "bar", Title: "Stock levels by category", Slug: "stock-levels-by-category", Aggregate: ir.Aggregate{Func: "sum", Field: "current_stock", Model: "Product", GroupBy: "category"}}},
+	}
+	page := &ir.Page{Name: "Dashboard", Content: []*ir.Action{
+		{Type: "display", Text: "show a bar chart of stock levels by category"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"import DataChart from '../components/DataChart.vue';", `<DataChart kind="bar" title="Stock levels by category" source="stock-levels-by-category" />`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("DashboardPage.vue missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateAPIClient(app), "export async function fetchChart(source: string)") {
+		t.Error("client.ts should fetch chart points")
+	}
+
+	app.Charts = nil
+	if strings.Contains(generatePage(page, app), "DataChart") {
+		t.Error("pages should not render a chart the IR did not collect")
+	}
+}
+
+func TestReorderWired(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"},
+			{Name: "position", Type: "number"},
+		}}},
+		Reorders: []*ir.Reorder{{Model: "Task", Field: "position", Slug: "tasks"}},
+		Pages:    []*ir.Page{{Name: "Settings"}},
+	}
+	page := &ir.Page{Name: "Board", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of tasks"},
+		{Type: "loop", Text: "each task shows its title"},
+		{Type: "interact", Text: "dragging a task reorders the list"},
+		{Type: "interact", Text: "pressing slash focuses the search"},
+		{Type: "interact", Text: "pressing Ctrl+K navigates to Settings"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"import draggable from 'vuedraggable';", `<draggable v-model="tasks" item-key="id" class="sortable-list" @start="rememberOrder" @end="saveOrder">`, "router.push('/settings');", "onMounted(() => document.addEventListener('keydown', onKeyDown));"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("BoardPage.vue missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateAPIClient(app), "reorder(list: string, ids: string[])") {
+		t.Error("client.ts should save a dragged order")
+	}
+
+	app.Reorders = nil
+	if strings.Contains(generatePage(page, app), "draggable") {
+		t.Error("pages should not render a draggable list the IR did not collect")
+	}
+}
+
+func TestInfiniteScrollWired(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Post", Fields: []*ir.DataField{{Name: "title", Type: "text"}}}},
+		APIs: []*ir.Endpoint{
+			{Name: "ListPosts", PageSize: 20, Steps: []*ir.Action{{Type: "query", Text: "fetch all posts"}}},
+			{Name: "CreatePost", Params: []*ir.Param{{Name: "title"}}},
+			{Name: "DeletePost", Params: []*ir.Param{{Name: "post_id"}}},
+		},
+	}
+	page := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of posts"},
+		{Type: "loop", Text: "each post shows its title"},
+		{Type: "interact", Text: "clicking New Post opens a form"},
+		{Type: "interact", Text: "clicking Remove deletes the post"},
+		{Type: "interact", Text: "scrolling to bottom loads more posts"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"const res = await listPosts(nextCursor.value);", "observeSentinel();", "onUnmounted(() => observer?.disconnect());", "<div ref=\"sentinel\" class=\"load-more\">", "posts.value = [{ ...values, id: tempId } as unknown as Post, ...posts.value];", "const res = await deletePost({ post_id: post.id });", "@click.stop=\"handleDelete(post)\">Remove</button>"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.vue missing %q:\n%s", want, output)
+		}
+	}
+
+	if !strings.Contains(generateAPIClient(app), "export async function listPosts(cursor?: string, query: Record<string, string> = {})") {
+		t.Error("the API client should fetch the page after a cursor")
+	}
+
+	app.APIs[0].PageSize = 0
+	if strings.Contains(generatePage(page, app), "sentinel") {
+		t.Error("pages should not scroll a list whose endpoint does not paginate")
+	}
+}
+
+func TestListQueryInURL(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"},
+			{Name: "status", Type: "enum", EnumValues: []string{"todo", "done"}},
+		}}},
+		APIs: []*ir.Endpoint{
+			{Name: "ListTasks", PageSize: 20, Steps: []*ir.Action{{Type: "query", Text: "fetch all tasks"}}},
+		},
+	}
+	page := &ir.Page{Name: "Tasks", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of tasks"},
+		{Type: "loop", Text: "each task shows its title"},
+		{Type: "input", Text: "there is a search bar to search tasks by title"},
+		{Type: "input", Text: "there is a dropdown to filter by status"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"const route = useRoute();",
+		"const statusFilter = computed(() => String(route.query.status ?? ''));",
+		"listTasks(undefined, listQuery.value)",
+		"watch(listQuery, load);",
+		":value=\"statusFilter\" @change=\"setParam('status', ($event.target as HTMLSelectElement).value)\"",
+		"<nav class=\"pagination\" aria-label=\"Pagination\">",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TasksPage.vue missing %q:\n%s", want, output)
+		}
+	}
+
+	// A list loaded whole is narrowed in the browser
+	app.APIs[0].PageSize = 0
+	output = generatePage(page, app)
+	for _, want := range []string{
+		"const visibleTasks = computed(() => {",
+		"(!statusFilter.value || String(task.status) === statusFilter.value)",
+		"v-for=\"task in visibleTasks\"",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TasksPage.vue missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Pagination") {
+		t.Error("a list loaded whole has no pages")
+	}
+}
+
+func TestRememberedView(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"},
+			{Name: "status", Type: "enum", EnumValues: []string{"todo", "done"}},
+		}}},
+		APIs: []*ir.Endpoint{
+			{Name: "ListTasks", PageSize: 20, Steps: []*ir.Action{{Type: "query", Text: "fetch all tasks"}}},
+		},
+	}
+	page := &ir.Page{Name: "Tasks", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of tasks"},
+		{Type: "loop", Text: "each task shows its title"},
+		{Type: "input", Text: "there is a dropdown to filter by status"},
+		{Type: "configure", Text: "remember the user's filters when they come back"},
+	}}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { useRouter, useRoute, onBeforeRouteLeave } from 'vue-router';",
+		"const restoringView = !Object.keys(route.query).length && !!savedView;",
+		"if (restoringView) router.replace({ query: Object.fromEntries(new URLSearchParams(savedView!)) });",
+		"onMounted(() => { if (!restoringView) load(); });",
+		"sessionStorage.setItem(`scroll:${location.pathname}`, String(window.scrollY));",
+		"nextTick(() => window.scrollTo(0, y));",
+		"<!-- remember the user's filters when they come back — restored from sessionStorage -->",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TasksPage.vue missing %q:\n%s", want, output)
+		}
+	}
+
+	// A list loaded whole loads while its view is restored
+	app.APIs[0].PageSize = 0
+	output = generatePage(page, app)
+	if !strings.Contains(output, "onMounted(load);") || !strings.Contains(output, "router.replace(") {
+		t.Errorf("expected the list to load as its view is restored:\n%s", output)
+	}
+}
+
+func TestTooltipAndPanelWired(t *testing.T) {
+	user := &ir.DataModel{Name: "User", Fields: []*ir.DataField{
+		{Name: "name", Type: "text"},
+		{Name: "avatar", Type: "text"},
+		{Name: "password", Type: "text"},
+	}}
+	app := &ir.Application{
+		Data: []*ir.DataModel{user},
+		APIs: []*ir.Endpoint{{Name: "ListUsers"}},
+		Components: []*ir.Component{{Name: "MemberAvatar", Props: []*ir.Prop{{Name: "user", Type: "User"}}, Content: []*ir.Action{
+			{Type: "interact", Text: "hovering shows the user's name as a tooltip"},
+		}}},
+	}
+	page := &ir.Page{Name: "Team", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all users"},
+		{Type: "loop", Text: "each user shows their name and avatar"},
+		{Type: "interact", Text: "hovering over a user avatar shows their name"},
+		{Type: "interact", Text: "clicking a user opens a detail panel on the right"},
+	}}
+	app.Pages = []*ir.Page{page}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"import Tooltip from '../components/Tooltip.vue';", "<Tooltip :text=\"user.name\"><span>{{ user.avatar }}</span></Tooltip>", "@click=\"selected = user\"", "<DetailPanel v-if=\"selected\" title=\"User\" side=\"right\" @close=\"selected = null\">", "<dd>{{ selected.avatar }}</dd>", "— handled by the detail panel"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TeamPage.vue missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "selected.password") {
+		t.Error("the detail panel should not show passwords")
+	}
+	if comp := generateComponent(app.Components[0], app); !strings.Contains(comp, "<Tooltip :text=\"user.name\">") {
+		t.Errorf("MemberAvatar should show the user's name on hover:\n%s", comp)
+	}
+
+	dir := t.TempDir()
+	g := Generator{}
+	if err := g.Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Tooltip.vue", "DetailPanel.vue"} {
+		if _, err := os.Stat(filepath.Join(dir, "src", "components", name)); os.IsNotExist(err) {
+			t.Errorf("expected %s to be generated", name)
+		}
+	}
+}
+
+func TestDetailRouteWired(t *testing.T) {
+	post := &ir.DataModel{Name: "Post", Fields: []*ir.DataField{
+		{Name: "title", Type: "text"},
+		{Name: "published", Type: "boolean"},
+	}}
+	home := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of posts"},
+		{Type: "loop", Text: "each post shows its title"},
+		{Type: "interact", Text: "clicking a post navigates to PostDetail"},
+	}}
+	archive := &ir.Page{Name: "Archive", Content: []*ir.Action{
+		{Type: "display", Text: "show a table of posts"},
+		{Type: "interact", Text: "clicking a post navigates to PostDetail"},
+	}}
+	detail := &ir.Page{Name: "PostDetail", Content: []*ir.Action{
+		{Type: "configure", Text: "shows the Post with the id from the url"},
+	}}
+	app := &ir.Application{
+		Data:         []*ir.DataModel{post},
+		Pages:        []*ir.Page{home, archive, detail},
+		APIs:         []*ir.Endpoint{{Name: "ListPosts"}, {Name: "GetPost", Params: []*ir.Param{{Name: "post_id"}}}},
+		DetailRoutes: []*ir.DetailRoute{{Page: "PostDetail", Model: "Post", Param: "id", Slug: "posts", Endpoint: "GetPost", Arg: "post_id"}},
+	}
+
+	if output := generateRouter(app); !strings.Contains(output, "{ path: '/posts/:id', name: 'PostDetailPage', component: PostDetailPage }") {
+		t.Errorf("router.ts should route PostDetail by id:\n%s", output)
+	}
+	output := generatePage(home, app)
+	for _, want := range []string{"listPosts()", `@click="router.push('/posts/' + post.id)"`, "— handled by each item's link"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.vue missing %q:\n%s", want, output)
+		}
+	}
+	if output := generatePage(archive, app); !strings.Contains(output, `router.push('/posts/' + post.id)`) {
+		t.Errorf("ArchivePage.vue rows should link to /posts/<id>:\n%s", output)
+	}
+	output = generatePage(detail, app)
+	for _, want := range []string{"const route = useRoute();", "watch(() => route.params.id,", "getPost({ post_id: value })", "<h1>{{ post.title }}</h1>", "Post not found"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PostDetailPage.vue missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestLoadErrorWired(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Post", Fields: []*ir.DataField{{Name: "title", Type: "text"}}}},
+		APIs: []*ir.Endpoint{{Name: "ListPosts"}},
+	}
+	page := &ir.Page{Name: "Feed", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all posts"},
+		{Type: "loop", Text: "each post shows its title"},
+		{Type: "condition", Text: "if there is an error, show Could not reach the journal"},
+	}}
+	app.Pages = []*ir.Page{page}
+
+	output := generatePage(page, app)
+	for _, want := range []string{"import { listPosts, errorMessage } from '../api/client';", "function load() {", ".catch(err => { loadError.value = errorMessage(err, 'Could not reach the journal'); loading.value = false; });", "onMounted(load);", `<button @click="load">Try again</button>`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("FeedPage.vue missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, `v-if="loadError"`) < strings.Index(output, "alert-error") {
+		t.Error("the load error should show where the page declares its error state")
+	}
+	if client := generateAPIClient(app); !strings.Contains(client, "throw new ApiError(res.status, json.error ?? res.statusText);") {
+		t.Errorf("the API client should throw an ApiError for failed requests:\n%s", client)
+	}
+	if root := generateApp(app); !strings.Contains(root, "<ErrorBoundary>") {
+		t.Errorf("App.vue should wrap the router view in the error boundary:\n%s", root)
+	}
+}
+
+func TestSkeletonShapedLikeItems(t *testing.T) {
+	app := &ir.Application{
+		Theme: &ir.Theme{BorderRadius: "rounded"},
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"}, {Name: "status", Type: "text"}, {Name: "created", Type: "date"},
+		}}},
+		APIs: []*ir.Endpoint{{Name: "ListTasks"}},
+	}
+	list := &ir.Page{Name: "Board", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "condition", Text: "while loading, show a skeleton screen"},
+		{Type: "loop", Text: "each task shows its title, status and created"},
+	}}
+	table := &ir.Page{Name: "Archive", Content: []*ir.Action{
+		{Type: "query", Text: "fetch all tasks"},
+		{Type: "condition", Text: "while loading, show a skeleton screen"},
+		{Type: "display", Text: "show a table of tasks"},
+	}}
+	app.Pages = []*ir.Page{list, table}
+
+	output := generatePage(list, app)
+	for _, want := range []string{"import Skeleton from '../components/Skeleton.vue';", `<div v-for="i in 3" :key="i" class="task-item">`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("BoardPage.vue missing %q:\n%s", want, output)
+		}
+	}
+	heading := strings.Index(output, `<Skeleton shape="heading" />`)
+	badge := strings.Index(output, `<Skeleton shape="badge" />`)
+	date := strings.Index(output, `<Skeleton shape="date" />`)
+	if heading < 0 || badge < heading || date < badge {
+		t.Errorf("the skeleton should have a heading, badge, and date like each task:\n%s", output)
+	}
+	if strings.Contains(output, "skeleton-item") {
+		t.Errorf("the skeleton should not fall back to generic placeholders:\n%s", output)
+	}
+	if output := generatePage(table, app); !strings.Contains(output, `<td><Skeleton shape="heading" /></td>`) {
+		t.Errorf("the table's skeleton should have a placeholder per column:\n%s", output)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "src", "components", "Skeleton.vue"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "var(--radius, 12px)") {
+		t.Errorf("the skeleton should be themed from the app's theme:\n%s", data)
+	}
+}
+
+func TestResponsivePage(t *testing.T) {
+	app := &ir.Application{
+		Data: []*ir.DataModel{{Name: "Post", Fields: []*ir.DataField{
+			{Name: "title", Type: "text"}, {Name: "created", Type: "date"},
+		}}},
+		APIs: []*ir.Endpoint{{Name: "ListPosts"}},
+	}
+	page := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "configure", Text: "make the layout responsive for mobile and tablet"},
+		{Type: "query", Text: "fetch all posts"},
+		{Type: "loop", Text: "each post shows its title and created"},
+	}}
+	app.Pages = []*ir.Page{page}
+
+	output := generatePage(page, app)
+	if strings.Contains(output, "TODO: make the layout responsive") {
+		t.Errorf("the responsive layout should be handled:\n%s", output)
+	}
+	for _, want := range []string{"<style scoped>", ".home-page .post-item {", "grid-template-columns: minmax(0, 2fr) auto;", "@container (max-width: 639px) {", "</style>"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.vue missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestStylingTailwind(t *testing.T) {
+	app := &ir.Application{Config: &ir.BuildConfig{Frontend: "Vue", Styling: "Tailwind"}}
+	if output := generateApp(app); !strings.Contains(output, "import './assets/tailwind.css';") {
+		t.Errorf("App.vue should load the Tailwind stylesheet:\n%s", output)
+	}
+
+	dir := t.TempDir()
+	if err := (Generator{}).Generate(app, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"tailwind.config.js", "postcss.config.js", "src/assets/tailwind.css"} {
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Errorf("expected %s: %v", path, err)
+		}
+	}
+}
+
+func TestAccessibleForm(t *testing.T) {
+	app := &ir.Application{
+		Name: "TestApp",
+		Data: []*ir.DataModel{
+			{Name: "Task", Fields: []*ir.DataField{
+				{Name: "title", Type: "text", Required: true},
+				{Name: "due", Type: "date", Required: true},
+				{Name: "done", Type: "boolean", Required: true},
+			}},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "ListTasks", Steps: []*ir.Action{{Type: "query", Text: "fetch all Tasks"}}},
+			{Name: "CreateTask", Params: []*ir.Param{{Name: "title"}, {Name: "due"}, {Name: "done"}}},
+		},
+		Pages: []*ir.Page{
+			{Name: "Dashboard", Content: []*ir.Action{
+				{Type: "query", Text: "fetch all Tasks"},
+				{Type: "loop", Text: "each task shows its title"},
+				{Type: "interact", Text: "clicking Add opens a form"},
+				{Type: "input", Text: "there is a form to create a Task"},
+				{Type: "condition", Text: "if creation succeeds, show \"Saved\""},
+				{Type: "condition", Text: "if there is an error, show the error"},
+			}},
+		},
+	}
+
+	output := generatePage(app.Pages[0], app)
+
+	for _, want := range []string{
+		// Each input is labelled and tracked while invalid
+		`<label for="task-form-title">Title</label>`,
+		`:aria-invalid="fieldError?.id === 'task-form-title'"`,
+		`<p v-if="fieldError?.id === 'task-form-title'" id="task-form-title-error" class="field-error">`,
+		`@invalid="showInvalid"`,
+		`type="date"`,
+		// The modal is a dialog, focused as it opens
+		`ref="dialog"`,
+		`role="dialog"`,
+		`aria-labelledby="new-task-title"`,
+		`@keydown.esc="showForm = false"`,
+		`aria-label="Close"`,
+		"dialog.value?.querySelector<HTMLElement>('input, select, textarea')?.focus();",
+		// Alerts are announced
+		`<div role="status">`,
+		`<div role="alert">`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("page should contain %q", want)
+		}
+	}
+}
+
+func TestEditFormWired(t *testing.T) {
+	post := &ir.DataModel{Name: "Post", Versioned: true, Fields: []*ir.DataField{
+		{Name: "title", Type: "text", Required: true},
+		{Name: "published", Type: "boolean"},
+	}}
+	detail := &ir.Page{Name: "PostDetail", Content: []*ir.Action{
+		{Type: "configure", Text: "shows the Post with the id from the url"},
+		{Type: "input", Text: "there is a form to edit the post"},
+	}}
+	app := &ir.Application{
+		Data:  []*ir.DataModel{post},
+		Pages: []*ir.Page{detail},
+		APIs: []*ir.Endpoint{
+			{Name: "GetPost", Params: []*ir.Param{{Name: "post_id"}}},
+			{Name: "UpdatePost", Params: []*ir.Param{{Name: "post_id"}, {Name: "title"}, {Name: "published"}}},
+		},
+		DetailRoutes: []*ir.DetailRoute{{Page: "PostDetail", Model: "Post", Param: "id", Slug: "posts", Endpoint: "GetPost", Arg: "post_id"}},
+	}
+
+	output := generatePage(detail, app)
+	for _, want := range []string{
+		"updatePost, ConflictError",
+		"const postDraft = ref({ title: '', published: false });",
+		"if (value) postDraft.value = { title: value.title ?? '', published: value.published ?? false };",
+		"<form v-if=\"post\" class=\"form edit-form\" aria-label=\"Edit post\" @submit.prevent=\"submitPost\">",
+		"v-model=\"postDraft.published\"",
+		"}, post.value.version);",
+		"conflict.value = { current: err.current as Post, changes };",
+		"role=\"alertdialog\" aria-modal=\"true\" aria-labelledby=\"conflict-title\" aria-describedby=\"conflict-description\"",
+		"<button autofocus @click=\"savePost(conflict.changes, conflict.current.version)\">Keep my changes</button>",
+		"<button @click=\"useTheirPost\">Use their version</button>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PostDetailPage.vue missing %q:\n%s", want, output)
+		}
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"export class ConflictError extends ApiError {",
+		"export async function updatePost(params: { post_id: string; title: string; published: boolean }, version: number) {",
+		"{ 'If-Match': `\"${version}\"` }",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+	if !strings.Contains(generateTypes(app), "  version: number;\n") {
+		t.Error("the Post type should carry its version")
+	}
+
+	// Without the protection, the form just saves
+	post.Versioned = false
+	output = generatePage(detail, app)
+	if strings.Contains(output, "alertdialog") || !strings.Contains(output, "published: String(postDraft.value.published),") {
+		t.Errorf("an unversioned post's form should save without a conflict prompt:\n%s", output)
+	}
+}
+
+func TestImportFormWired(t *testing.T) {
+	task := &ir.DataModel{Name: "Task", Fields: []*ir.DataField{
+		{Name: "title", Type: "text", Required: true},
+	}}
+	page := &ir.Page{Name: "Tasks", Content: []*ir.Action{
+		{Type: "input", Text: "there is a form to import tasks"},
+	}}
+	app := &ir.Application{
+		Data:  []*ir.DataModel{task},
+		Pages: []*ir.Page{page},
+		APIs: []*ir.Endpoint{{Name: "ImportTasks", Import: &ir.Import{
+			Model: "Task", Formats: []string{"csv", "json"}, Mode: ir.ImportAllOrNothing,
+		}}},
+	}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { importTasks, describeImport, type ImportSummary, errorMessage } from '../api/client';",
+		"<form class=\"form import-form\" aria-label=\"Import tasks\" @submit.prevent=\"runImport(false)\">",
+		"<label for=\"task-import-file\">CSV or JSON file</label>",
+		"@change=\"chooseImportFile\"",
+		"@click=\"runImport(true)\">Check file</button>",
+		"<section class=\"import-summary\" aria-live=\"polite\">",
+		"<caption>Problems found</caption>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TasksPage.vue missing %q:\n%s", want, output)
+		}
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"export interface ImportSummary {",
+		"export async function importTasks(file: File, dryRun = false) {",
+		"return uploadImport('/api/import-tasks', file, dryRun);",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+}
+
+func TestTimesInUserZone(t *testing.T) {
+	event := &ir.DataModel{Name: "Event", Fields: []*ir.DataField{
+		{Name: "title", Type: "text"},
+		{Name: "starts", Type: "datetime"},
+		{Name: "day", Type: "date"},
+	}}
+	home := &ir.Page{Name: "Home", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of events"},
+		{Type: "loop", Text: "each event shows its title and starts"},
+	}}
+	detail := &ir.Page{Name: "EventDetail", Content: []*ir.Action{
+		{Type: "configure", Text: "shows the Event with the id from the url"},
+	}}
+	app := &ir.Application{
+		Config:       &ir.BuildConfig{Times: "UTC and display in the user's timezone"},
+		Data:         []*ir.DataModel{event},
+		Pages:        []*ir.Page{home, detail},
+		APIs:         []*ir.Endpoint{{Name: "ListEvents"}, {Name: "GetEvent", Params: []*ir.Param{{Name: "event_id"}}}},
+		DetailRoutes: []*ir.DetailRoute{{Page: "EventDetail", Model: "Event", Param: "id", Slug: "events", Endpoint: "GetEvent", Arg: "event_id"}},
+	}
+
+	output := generatePage(home, app)
+	for _, want := range []string{
+		"import { listEvents, errorMessage, formatDateTime } from '../api/client';",
+		"<time :datetime=\"event.starts ?? undefined\">{{ formatDateTime(event.starts) }}</time>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HomePage.vue missing %q:\n%s", want, output)
+		}
+	}
+	output = generatePage(detail, app)
+	if want := "<dd><time :datetime=\"event.day ?? undefined\">{{ formatDate(event.day) }}</time></dd>"; !strings.Contains(output, want) {
+		t.Errorf("EventDetailPage.vue missing %q:\n%s", want, output)
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"export function formatDateTime(value: string | null | undefined): string {",
+		"timeZone: 'UTC'",
+		"body: body ? JSON.stringify(utcTimes(body)) : undefined,",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+
+	app.Config.Times = ""
+	if output := generatePage(home, app); strings.Contains(output, "formatDateTime") {
+		t.Errorf("times shouldn't be formatted without a time policy:\n%s", output)
+	}
+}
+
+func TestNumbersInLocale(t *testing.T) {
+	product := &ir.DataModel{Name: "Product", Fields: []*ir.DataField{
+		{Name: "name", Type: "text"},
+		{Name: "price", Type: "decimal"},
+		{Name: "view_count", Type: "number"},
+	}}
+	page := &ir.Page{Name: "Products", Content: []*ir.Action{
+		{Type: "display", Text: "show a list of products"},
+		{Type: "loop", Text: "each product shows its name, price, and view_count"},
+	}}
+	app := &ir.Application{
+		Theme: &ir.Theme{Locale: "de-DE"},
+		Data:  []*ir.DataModel{product},
+		Pages: []*ir.Page{page},
+		APIs:  []*ir.Endpoint{{Name: "ListProducts"}},
+	}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { formatCompact, formatMoney } from '../utils/format';",
+		"<span class=\"number\">{{ formatMoney(product.price) }}</span>",
+		"<span class=\"count\">{{ formatCompact(product.view_count) }}</span>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("ProductsPage.vue missing %q:\n%s", want, output)
+		}
+	}
+	format := generateFormat(app)
+	for _, want := range []string{
+		"const LOCALE = 'de-DE';",
+		"style: 'currency', currency: 'EUR'",
+		"export function formatPercent(value: number | string | null | undefined): string {",
+	} {
+		if !strings.Contains(format, want) {
+			t.Errorf("format.ts missing %q:\n%s", want, format)
+		}
+	}
+
+	app.Theme.Locale = ""
+	if output := generatePage(page, app); strings.Contains(output, "formatMoney") {
+		t.Errorf("numbers shouldn't be formatted without a locale:\n%s", output)
+	}
+}
+
+func TestAccountSettingsPage(t *testing.T) {
+	user := &ir.DataModel{Name: "User", Fields: []*ir.DataField{
+		{Name: "name", Type: "text", Required: true},
+		{Name: "email", Type: "email", Required: true, Unique: true},
+		{Name: "password", Type: "text", Required: true},
+		{Name: "bio", Type: "text"},
+	}}
+	page := &ir.Page{Name: "Settings"}
+	app := &ir.Application{
+		Data:     []*ir.DataModel{user},
+		Pages:    []*ir.Page{page},
+		Auth:     &ir.Auth{},
+		Accounts: &ir.Accounts{Model: "User", Page: "Settings", Profile: true, Password: true, Delete: true},
+		APIs: []*ir.Endpoint{
+			{Name: "GetProfile", Auth: true, Account: ir.AccountProfile},
+			{Name: "UpdateProfile", Auth: true, Account: ir.AccountUpdateProfile, Params: []*ir.Param{{Name: "name"}, {Name: "email"}, {Name: "bio"}}},
+			{Name: "ChangePassword", Auth: true, Account: ir.AccountChangePassword, Params: []*ir.Param{{Name: "current_password"}, {Name: "new_password"}}},
+			{Name: "DeleteAccount", Auth: true, Account: ir.AccountDelete, Params: []*ir.Param{{Name: "password"}}},
+		},
+	}
+
+	output := generatePage(page, app)
+	for _, want := range []string{
+		"import { getProfile, updateProfile, changePassword, deleteAccount, errorMessage, type Profile } from '../api/client';",
+		"const { logout } = useAuth();",
+		"const profileDraft = ref({ name: '', email: '', bio: '' });",
+		"bio: profileDraft.value.bio || null,",
+		"v-model=\"profileDraft.email\"",
+		"<input id=\"new-password\" v-model=\"newPassword\" type=\"password\" autocomplete=\"new-password\" minlength=\"8\" required />",
+		"@keydown.esc=\"cancelDelete\"",
+		":disabled=\"deleteConfirmation !== 'DELETE' || deleting\"",
+		"router.replace('/');",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("SettingsPage.vue missing %q:\n%s", want, output)
+		}
+	}
+	client := generateAPIClient(app)
+	for _, want := range []string{
+		"export type Profile = Omit<User, 'password'>;",
+		"export async function changePassword(params: { current_password: string; new_password: string }) {",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.ts missing %q:\n%s", want, client)
+		}
+	}
+}
+
+func TestTargetEnvironment(t *testing.T) {
+	app := &ir.Application{
+		Name:         "Shop",
+		Config:       &ir.BuildConfig{Frontend: "Vue", Deploy: "AWS", Env: "production"},
+		Environments: []*ir.Environment{{Name: "production", Config: map[string]string{"url": "shop example com"}}},
+	}
+	if got := generateEnvProduction(app.Environments[0], app); !strings.Contains(got, "VITE_API_URL=https://api.shop.example.com\n") {
+		t.Errorf("vite build should call the production API:\n%s", got)
+	}
+	if got := generateViteConfig(app); !strings.Contains(got, "  server: { allowedHosts: ['shop.example.com'] },\n  preview: { allowedHosts: ['shop.example.com'] },\n") {
+		t.Errorf("the dev and preview servers should answer on the environment's host:\n%s", got)
+	}
+	app.Config.Env = ""
+	if strings.Contains(generateViteConfig(app), "allowedHosts") {
+		t.Error("a local build should leave Vite's hosts alone")
+	}
+}
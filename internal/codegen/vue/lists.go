@@ -0,0 +1,165 @@
+package vue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// pageScrolls reports whether a page loads the next page of its list as
+// the user nears the end: it says "scrolling to bottom loads more posts"
+// of the model it lists, and the endpoint it lists them from paginates.
+func pageScrolls(page *ir.Page, app *ir.Application, modelName string, listEp *ir.Endpoint) bool {
+	if listEp == nil || listEp.PageSize == 0 || len(listEp.Params) > 0 {
+		return false
+	}
+	for _, a := range page.Content {
+		if ir.IsInfiniteScroll(a) {
+			if m := ir.ScrollModel(app, a); m != nil && m.Name == modelName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pageDelete returns the endpoint and button label of a page's
+// `clicking "Delete" deletes the task`, when the page lists tasks and a
+// delete endpoint takes just the task's id.
+func pageDelete(page *ir.Page, app *ir.Application, modelName string) (*ir.Endpoint, string) {
+	if modelName == "" {
+		return nil, ""
+	}
+	for _, a := range page.Content {
+		if !ir.IsDelete(a) || !strings.Contains(strings.ToLower(a.Text), strings.ToLower(modelName)) {
+			continue
+		}
+		ep := findDeleteEndpoint(app, modelName)
+		if ep == nil || len(ep.Params) != 1 || !strings.HasSuffix(strings.ToLower(ep.Params[0].Name), "id") {
+			return nil, ""
+		}
+		return ep, ir.DeleteLabel(a)
+	}
+	return nil, ""
+}
+
+// findDeleteEndpoint finds a delete-type API endpoint matching the model.
+func findDeleteEndpoint(app *ir.Application, modelName string) *ir.Endpoint {
+	lowerModel := strings.ToLower(modelName)
+	for _, ep := range app.APIs {
+		lower := strings.ToLower(ep.Name)
+		if strings.HasPrefix(lower, "delete") && strings.Contains(lower, lowerModel) {
+			return ep
+		}
+	}
+	return nil
+}
+
+// writeLoadMore emits the state and handlers of an infinitely scrolling
+// list. An IntersectionObserver watches a sentinel below the list and
+// fetches the page after nextCursor when it comes into view. The sentinel
+// is observed afresh after each page, so a short page that leaves it in
+// view loads the next one too. A failed fetch stops loading more.
+func writeLoadMore(b *strings.Builder, listEp *ir.Endpoint, ctx *pageContext) {
+	b.WriteString("\nlet observer: IntersectionObserver | undefined;\n")
+	b.WriteString("function observeSentinel() {\n")
+	b.WriteString("  observer?.disconnect();\n")
+	b.WriteString("  if (!sentinel.value || !nextCursor.value) return;\n")
+	b.WriteString("  observer = new IntersectionObserver(([entry]) => {\n")
+	b.WriteString("    if (entry.isIntersecting) loadMore();\n")
+	b.WriteString("  }, { rootMargin: '200px' });\n")
+	b.WriteString("  observer.observe(sentinel.value);\n")
+	b.WriteString("}\n")
+	b.WriteString("async function loadMore() {\n")
+	b.WriteString("  if (!nextCursor.value || loadingMore.value) return;\n")
+	b.WriteString("  observer?.disconnect();\n")
+	b.WriteString("  loadingMore.value = true;\n")
+	b.WriteString("  try {\n")
+	fmt.Fprintf(b, "    const res = await %s(nextCursor.value);\n", toCamelCase(listEp.Name))
+	fmt.Fprintf(b, "    %s.value = [...%s.value, ...(res.data ?? [])];\n", ctx.varName, ctx.varName)
+	b.WriteString("    nextCursor.value = res.pagination?.nextCursor ?? null;\n")
+	b.WriteString("  } catch {\n")
+	b.WriteString("    nextCursor.value = null;\n")
+	b.WriteString("  } finally {\n")
+	b.WriteString("    loadingMore.value = false;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  observeSentinel();\n")
+	b.WriteString("}\n")
+	b.WriteString("onUnmounted(() => observer?.disconnect());\n")
+}
+
+// writeListEnd emits what follows the page's list: the sentinel loading
+// more of a scrolling list, or the controls paging a paginated one unless
+// the page places them itself.
+func writeListEnd(b *strings.Builder, indent string, page *ir.Page, ctx *pageContext) {
+	switch {
+	case ctx.scroll:
+		writeLoadMoreSentinel(b, indent)
+	case ctx.query != nil && ctx.query.Paged && !ir.ShowsPagination(page):
+		writePaginationVue(b, indent)
+	}
+}
+
+// writeLoadMoreSentinel emits the element below a scrolling list whose
+// coming into view loads the next page.
+func writeLoadMoreSentinel(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<div ref=\"sentinel\" class=\"load-more\">\n", indent)
+	fmt.Fprintf(b, "%s  <div v-if=\"loadingMore\" class=\"spinner\"></div>\n", indent)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+}
+
+// writeDeleteHandler emits the page's handler for an item's Delete button:
+// it removes the item at once and puts the list back if deleting fails.
+func writeDeleteHandler(b *strings.Builder, ep *ir.Endpoint, ctx *pageContext) {
+	item := ctx.itemVar
+	fmt.Fprintf(b, "\nasync function handleDelete(%s: %s) {\n", item, ctx.modelName)
+	fmt.Fprintf(b, "  const previous = %s.value;\n", ctx.varName)
+	fmt.Fprintf(b, "  %s.value = %s.value.filter((other) => other.id !== %s.id);\n", ctx.varName, ctx.varName, item)
+	b.WriteString("  try {\n")
+	fmt.Fprintf(b, "    const res = await %s({ %s: %s.id });\n", toCamelCase(ep.Name), sanitizeParamName(ep.Params[0].Name), item)
+	fmt.Fprintf(b, "    if (res.error) %s.value = previous;\n", ctx.varName)
+	b.WriteString("  } catch {\n")
+	fmt.Fprintf(b, "    %s.value = previous;\n", ctx.varName)
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+}
+
+// writeDeleteButton emits a list item's delete button.
+func writeDeleteButton(b *strings.Builder, indent, item string, ctx *pageContext) {
+	fmt.Fprintf(b, "%s<button class=\"delete-button\" @click.stop=\"handleDelete(%s)\">%s</button>\n", indent, item, ctx.deleteLabel)
+}
+
+// writeOptimisticCreate emits the body of a create form's submit handler
+// that shows the new record in the list at once, under a temporary id,
+// swaps in the saved record when the API answers, and takes it out again
+// if saving fails. Paginated lists show their newest records first, so the
+// record goes at the top of those.
+func writeOptimisticCreate(b *strings.Builder, createFunc string, ctx *pageContext) {
+	list := ctx.varName
+	b.WriteString("  const values = { ...formData };\n")
+	b.WriteString("  const tempId = `temp-${Date.now()}`;\n")
+	pending := fmt.Sprintf("{ ...values, id: tempId } as unknown as %s", ctx.modelName)
+	if ctx.newestFirst {
+		fmt.Fprintf(b, "  %s.value = [%s, ...%s.value];\n", list, pending, list)
+	} else {
+		fmt.Fprintf(b, "  %s.value = [...%s.value, %s];\n", list, list, pending)
+	}
+	if ctx.needsFormState {
+		b.WriteString("  showForm.value = false;\n")
+	}
+	b.WriteString("  Object.keys(formData).forEach(k => formData[k] = '');\n")
+	b.WriteString("  try {\n")
+	fmt.Fprintf(b, "    const res = await %s(values);\n", createFunc)
+	b.WriteString("    if (res.error) throw new Error(res.error);\n")
+	fmt.Fprintf(b, "    %s.value = %s.value.map((other) => (other.id === tempId ? res.data as %s : other));\n", list, list, ctx.modelName)
+	if ctx.hasSuccessState {
+		b.WriteString("    success.value = 'Created successfully';\n")
+	}
+	b.WriteString("  } catch (err) {\n")
+	fmt.Fprintf(b, "    %s.value = %s.value.filter((other) => other.id !== tempId);\n", list, list)
+	if ctx.hasErrorState {
+		b.WriteString("    error.value = err instanceof Error ? err.message : 'Failed to save';\n")
+	}
+	b.WriteString("  }\n")
+}
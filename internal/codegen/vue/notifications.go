@@ -0,0 +1,122 @@
+package vue
+
+import "strings"
+
+// writeNotificationsClient appends the in-app notification endpoints to the
+// API client.
+func writeNotificationsClient(b *strings.Builder) {
+	b.WriteString(`
+export async function listNotifications() {
+  return request<Notification[]>('GET', '/api/notifications');
+}
+
+export async function getUnreadNotificationCount() {
+  return request<{ count: number }>('GET', '/api/notifications/unread-count');
+}
+
+export async function markNotificationRead(id: string) {
+  return request<Notification>('POST', ` + "`/api/notifications/${encodeURIComponent(id)}/read`" + `);
+}
+
+export async function markAllNotificationsRead() {
+  return request<{ count: number }>('POST', '/api/notifications/read-all');
+}
+`)
+}
+
+// generateNotificationBell produces src/components/NotificationBell.vue: a
+// dropdown of the signed-in user's notifications. The unread badge polls
+// the backend every 30 seconds.
+func generateNotificationBell() string {
+	return `<!-- Generated by Human compiler — do not edit -->
+<script setup lang="ts">
+import { onMounted, onUnmounted, ref, watch } from 'vue';
+import type { Notification } from '../types/models';
+import {
+  getUnreadNotificationCount,
+  listNotifications,
+  markAllNotificationsRead,
+  markNotificationRead,
+} from '../api/client';
+
+const POLL_INTERVAL_MS = 30_000;
+
+const open = ref(false);
+const unread = ref(0);
+const notifications = ref<Notification[]>([]);
+const signedIn = ref(!!localStorage.getItem('token'));
+let timer: ReturnType<typeof setInterval> | undefined;
+
+// Polling also notices signing in and out, since the token lives in localStorage.
+function refreshCount() {
+  signedIn.value = !!localStorage.getItem('token');
+  if (!signedIn.value) return;
+  getUnreadNotificationCount()
+    .then((res) => (unread.value = res.data?.count ?? 0))
+    .catch(() => {});
+}
+
+onMounted(() => {
+  refreshCount();
+  timer = setInterval(refreshCount, POLL_INTERVAL_MS);
+});
+onUnmounted(() => clearInterval(timer));
+
+watch(open, (isOpen) => {
+  if (!isOpen) return;
+  listNotifications()
+    .then((res) => (notifications.value = res.data ?? []))
+    .catch(() => {});
+});
+
+async function markRead(notification: Notification) {
+  if (notification.seen) return;
+  await markNotificationRead(notification.id);
+  notification.seen = new Date().toISOString();
+  unread.value = Math.max(0, unread.value - 1);
+}
+
+async function markAllRead() {
+  await markAllNotificationsRead();
+  for (const n of notifications.value) {
+    n.seen = n.seen ?? new Date().toISOString();
+  }
+  unread.value = 0;
+}
+</script>
+
+<template>
+  <div v-if="signedIn" class="notification-bell">
+    <button type="button" aria-label="Notifications" :aria-expanded="open" @click="open = !open">
+      🔔<span v-if="unread > 0" class="badge">{{ unread }}</span>
+    </button>
+    <div v-if="open" role="menu" class="dropdown">
+      <div class="header">
+        <strong>Notifications</strong>
+        <button v-if="unread > 0" type="button" @click="markAllRead">Mark all read</button>
+      </div>
+      <p v-if="notifications.length === 0" class="empty">No notifications yet</p>
+      <div
+        v-for="n in notifications"
+        :key="n.id"
+        role="menuitem"
+        :class="['item', { unread: !n.seen }]"
+        @click="markRead(n)"
+      >
+        {{ n.message }}
+      </div>
+    </div>
+  </div>
+</template>
+
+<style scoped>
+.notification-bell { position: fixed; top: 1rem; right: 1rem; z-index: 1000; }
+.badge { margin-left: 0.25rem; font-weight: bold; }
+.dropdown { position: absolute; right: 0; margin-top: 0.5rem; width: 20rem; max-height: 24rem; overflow-y: auto; background: white; border: 1px solid #e5e7eb; border-radius: 0.5rem; box-shadow: 0 4px 12px rgba(0, 0, 0, 0.1); }
+.header { display: flex; justify-content: space-between; padding: 0.5rem 0.75rem; border-bottom: 1px solid #e5e7eb; }
+.empty { padding: 0.75rem; color: #6b7280; }
+.item { padding: 0.75rem; cursor: pointer; border-bottom: 1px solid #f3f4f6; }
+.item.unread { font-weight: bold; }
+</style>
+`
+}
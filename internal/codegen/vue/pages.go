@@ -18,9 +18,36 @@ type pageContext struct {
 	hasSuccessState bool
 	hasErrorState   bool
 	needsFormState  bool
+	table           *ir.Table       // the page's "show posts in a table", if any
+	query           *ir.ListQuery   // the view of the list the page's URL holds, if any
+	reorder         *ir.Reorder     // the list the page lets users drag, if any
+	scroll          bool            // whether the list loads more records as the user nears its end
+	remember        bool            // whether the page restores its list as the user left it
+	newestFirst     bool            // whether new records go at the top of the list
+	deleteEp        *ir.Endpoint    // endpoint each item's delete button calls, if any
+	deleteLabel     string          // label of that button
+	tooltips        []*ir.Tooltip   // tooltips shown over the listed records or the component
+	panel           *ir.DetailPanel // panel clicking a listed record opens, if any
+	itemPage        string          // page clicking a listed record navigates to, if any
+	itemLink        *ir.Action      // the interaction saying so
+	record          *ir.DetailRoute // the record the page's URL names, if any
+	edit            *ir.EditForm    // the form editing that record, if any
+	importForm      *ir.ImportForm  // the form uploading a file of records to import, if any
+	timeHelpers     map[string]bool // time helpers the page uses from the API client
+	formatHelpers   map[string]bool // number helpers the page uses
+	loadError       string          // message shown when loading the page's data fails, if it loads any
+	retry           string          // handler the load error's button calls
+	itemFields      []string        // fields each listed record shows, shaping its loading skeleton
+	responsive      *ir.Responsive  // how the page adapts to narrower screens, if it declares so
+	fieldErrors     bool            // whether the page tracks the form input found invalid
+	ids             map[string]int  // element ids given out on the page, by base
 }
 
 func generatePage(page *ir.Page, app *ir.Application) string {
+	if ir.IsAccountPage(app, page) {
+		return generateAccountPage(page, app)
+	}
+
 	var b strings.Builder
 
 	modelName, varName, itemVar := detectPageModel(page, app)
@@ -32,6 +59,7 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	needsFormState := false
 	needsSuccess := false
 	needsError := false
+	hasForm := false
 
 	for _, a := range page.Content {
 		lower := strings.ToLower(a.Text)
@@ -45,9 +73,22 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			if strings.Contains(lower, "opens a form") || strings.Contains(lower, "open a form") {
 				needsFormState = true
 			}
+			if kb, ok := ir.ParseKeyBinding(a.Text); ok {
+				switch kb.Action {
+				case "open-form":
+					needsFormState = true
+				case "navigate":
+					needsNavigate = true
+				}
+			}
 		case "query":
 			needsDataState = true
 			needsEffect = true
+		case "display":
+			if ir.IsTable(a) {
+				needsDataState = true
+				needsEffect = true
+			}
 		case "loop":
 			needsDataState = true
 			if modelName != "" {
@@ -57,6 +98,9 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			if strings.Contains(lower, "button") && (strings.Contains(lower, "create") || strings.Contains(lower, "new") || strings.Contains(lower, "add")) {
 				needsFormState = true
 			}
+			if strings.Contains(lower, "form") && !ir.IsImportForm(a) {
+				hasForm = true
+			}
 		case "condition":
 			if strings.Contains(lower, "logged in") {
 				needsAuth = true
@@ -78,6 +122,58 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 		hasSuccessState: needsSuccess,
 		hasErrorState:   needsError,
 		needsFormState:  needsFormState,
+		table:           ir.TableFor(app, page, modelName),
+		reorder:         pageReorder(page, app, modelName),
+		record:          ir.DetailRouteFor(app, page.Name),
+		edit:            ir.EditFormFor(app, page),
+		importForm:      ir.ImportFormFor(app, page),
+		responsive:      ir.ResponsiveFor(page),
+		fieldErrors:     needsFormState || hasForm,
+	}
+	keyBindings := ir.PageKeyBindings(page)
+	if ctx.table != nil && ctx.table.RowPage != "" {
+		needsNavigate = true
+	}
+	var listEp *ir.Endpoint
+	if needsEffect && modelName != "" {
+		listEp = findListEndpoint(app, modelName)
+	}
+	ctx.scroll = pageScrolls(page, app, modelName, listEp)
+	if needsDataState {
+		ctx.query = ir.ListQueryFor(app, page, modelName, listEp)
+	}
+	if ctx.query != nil {
+		needsNavigate = true
+	}
+	paged := ctx.query != nil && ctx.query.Paged
+	ctx.newestFirst = ir.ListsNewestFirst(app, listEp, modelName)
+	ctx.remember = needsEffect && ir.RemembersView(page)
+	if needsDataState {
+		ctx.deleteEp, ctx.deleteLabel = pageDelete(page, app, modelName)
+		ctx.tooltips = pageTooltips(page, app, modelName)
+		ctx.panel = pagePanel(page, app, modelName)
+		if ctx.table == nil {
+			ctx.itemPage, ctx.itemLink = ir.ItemLinkFor(app, page, modelName)
+		}
+	}
+	if ctx.itemPage != "" {
+		needsNavigate = true
+	}
+	if needsEffect {
+		subject := varName
+		if subject == "" {
+			subject = "data"
+		}
+		ctx.loadError = ir.LoadErrorMessage(page, strings.ToLower(ir.FieldLabel(subject)))
+		ctx.retry = "load"
+	}
+	if ctx.record != nil {
+		if ctx.loadError == "" {
+			ctx.loadError = ir.LoadErrorMessage(page, "the "+strings.ToLower(ir.FieldLabel(ctx.record.Model)))
+			ctx.retry = "load" + ctx.record.Model
+		} else {
+			ctx.retry += "(); load" + ctx.record.Model + "()"
+		}
 	}
 
 	// <script setup>
@@ -85,31 +181,59 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	b.WriteString("<script setup lang=\"ts\">\n")
 
 	vueImports := []string{}
-	if needsDataState || needsAuth || needsFormState || needsSuccess || needsError {
+	if needsDataState || needsAuth || needsFormState || needsSuccess || needsError || ctx.record != nil || ctx.fieldErrors || ctx.importForm != nil {
 		vueImports = append(vueImports, "ref")
 	}
 	if needsFormState {
 		vueImports = append(vueImports, "reactive")
 	}
-	if needsEffect {
+	if ctx.query != nil {
+		vueImports = append(vueImports, "computed")
+	}
+	if needsEffect || len(keyBindings) > 0 {
 		vueImports = append(vueImports, "onMounted")
 	}
+	if len(keyBindings) > 0 || ctx.scroll {
+		vueImports = append(vueImports, "onUnmounted")
+	}
+	if ctx.record != nil || needsFormState || paged || ctx.remember {
+		vueImports = append(vueImports, "watch")
+	}
+	if needsFormState || ctx.remember {
+		vueImports = append(vueImports, "nextTick")
+	}
 	if len(vueImports) > 0 {
 		fmt.Fprintf(&b, "import { %s } from 'vue';\n", strings.Join(vueImports, ", "))
 	}
+	var routerImports []string
 	if needsNavigate {
-		b.WriteString("import { useRouter } from 'vue-router';\n")
+		routerImports = append(routerImports, "useRouter")
 	}
+	if ctx.record != nil || ctx.query != nil {
+		routerImports = append(routerImports, "useRoute")
+	}
+	if ctx.remember {
+		routerImports = append(routerImports, "onBeforeRouteLeave")
+	}
+	if len(routerImports) > 0 {
+		fmt.Fprintf(&b, "import { %s } from 'vue-router';\n", strings.Join(routerImports, ", "))
+	}
+	if ctx.reorder != nil {
+		b.WriteString("import draggable from 'vuedraggable';\n")
+	}
+	var types []string
 	if modelName != "" {
-		fmt.Fprintf(&b, "import type { %s } from '../types/models';\n", modelName)
+		types = append(types, modelName)
+	}
+	if ctx.record != nil && ctx.record.Model != modelName {
+		types = append(types, ctx.record.Model)
+	}
+	if len(types) > 0 {
+		fmt.Fprintf(&b, "import type { %s } from '../types/models';\n", strings.Join(types, ", "))
 	}
 
 	// Import API client functions for data fetching and form submission
-	var listEp *ir.Endpoint
 	var createEp *ir.Endpoint
-	if needsEffect && modelName != "" {
-		listEp = findListEndpoint(app, modelName)
-	}
 	if needsFormState && modelName != "" {
 		createEp = findCreateEndpoint(app, modelName)
 	}
@@ -123,22 +247,59 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			apiImports = append(apiImports, fn)
 		}
 	}
+	if ctx.deleteEp != nil {
+		apiImports = append(apiImports, toCamelCase(ctx.deleteEp.Name))
+	}
+	if ctx.reorder != nil {
+		apiImports = append(apiImports, "reorder")
+	}
+	if ctx.record != nil {
+		apiImports = append(apiImports, toCamelCase(ctx.record.Endpoint), "ApiError")
+	}
+	if ctx.edit != nil {
+		apiImports = append(apiImports, editImports(ctx.edit)...)
+	}
+	if ctx.importForm != nil {
+		apiImports = append(apiImports, importImports(ctx.importForm)...)
+	}
+	if ctx.loadError != "" || ctx.edit != nil || ctx.importForm != nil {
+		apiImports = append(apiImports, "errorMessage")
+	}
+	if needsEffect && listEp == nil {
+		apiImports = append(apiImports, "request")
+	}
 	if len(apiImports) > 0 {
 		fmt.Fprintf(&b, "import { %s } from '../api/client';\n", strings.Join(apiImports, ", "))
-	} else if needsEffect {
-		b.WriteString("import { request } from '../api/client';\n")
 	}
 
 	// Component imports
 	for _, comp := range detectUsedComponents(page) {
 		fmt.Fprintf(&b, "import %s from '../components/%s.vue';\n", comp, comp)
 	}
+	if pageLoopsOverCalendar(page, app, modelName) {
+		b.WriteString("import AddToCalendar from '../components/AddToCalendar.vue';\n")
+	}
+	if pageHasChart(page, app) {
+		b.WriteString("import DataChart from '../components/DataChart.vue';\n")
+	}
+	if len(ctx.tooltips) > 0 {
+		b.WriteString("import Tooltip from '../components/Tooltip.vue';\n")
+	}
+	if ctx.panel != nil {
+		b.WriteString("import DetailPanel from '../components/DetailPanel.vue';\n")
+	}
+	if pageHasSkeleton(page) {
+		b.WriteString("import Skeleton from '../components/Skeleton.vue';\n")
+	}
 
 	b.WriteString("\n")
 
 	if needsNavigate {
 		b.WriteString("const router = useRouter();\n")
 	}
+	if ctx.record != nil || ctx.query != nil {
+		b.WriteString("const route = useRoute();\n")
+	}
 	if needsAuth {
 		b.WriteString("const isLoggedIn = ref(!!localStorage.getItem('token'));\n")
 	}
@@ -150,8 +311,23 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			b.WriteString("const data = ref<unknown[]>([]);\n")
 		}
 	}
+	if ctx.scroll {
+		b.WriteString("const nextCursor = ref<string | null>(null);\n")
+		b.WriteString("const loadingMore = ref(false);\n")
+		b.WriteString("const sentinel = ref<HTMLElement | null>(null);\n")
+	}
+	if ctx.panel != nil {
+		fmt.Fprintf(&b, "const selected = ref<%s | null>(null);\n", modelName)
+	}
+	if ctx.query != nil {
+		writeListQueryScript(&b, ctx)
+	}
 	if needsFormState {
 		b.WriteString("const showForm = ref(false);\n")
+		b.WriteString("const dialog = ref<HTMLElement | null>(null);\n")
+	}
+	if ctx.fieldErrors {
+		writeFieldErrorScript(&b)
 	}
 	if needsSuccess {
 		b.WriteString("const success = ref('');\n")
@@ -159,6 +335,18 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	if needsError {
 		b.WriteString("const error = ref('');\n")
 	}
+	if ctx.loadError != "" {
+		b.WriteString("const loadError = ref('');\n")
+	}
+	if ctx.record != nil {
+		writeRecordScript(&b, ctx)
+	}
+	if ctx.edit != nil {
+		writeEditScript(&b, ctx)
+	}
+	if ctx.importForm != nil {
+		writeImportScript(&b, ctx)
+	}
 
 	// Generate form data and submit handler when create endpoint exists
 	if createEp != nil {
@@ -187,42 +375,53 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 		if ctx.hasErrorState {
 			b.WriteString("  error.value = '';\n")
 		}
-		b.WriteString("  try {\n")
-		fmt.Fprintf(&b, "    const res = await %s({ ...formData });\n", createFunc)
-		if isLogin {
-			b.WriteString("    localStorage.setItem('token', res.token);\n")
-			b.WriteString("    window.location.href = '/';\n")
+		if !isLogin && varName != "" && varName != "data" {
+			writeOptimisticCreate(&b, createFunc, ctx)
 		} else {
-			if varName != "" && varName != "data" {
-				fmt.Fprintf(&b, "    %s.value = [...%s.value, res.data];\n", varName, varName)
-			}
-			if needsFormState {
-				b.WriteString("    showForm.value = false;\n")
+			b.WriteString("  try {\n")
+			fmt.Fprintf(&b, "    const res = await %s({ ...formData });\n", createFunc)
+			if isLogin {
+				b.WriteString("    localStorage.setItem('token', res.token);\n")
+				b.WriteString("    window.location.href = '/';\n")
+			} else {
+				if needsFormState {
+					b.WriteString("    showForm.value = false;\n")
+				}
+				if ctx.hasSuccessState {
+					b.WriteString("    success.value = 'Created successfully';\n")
+				}
+				// Reset form
+				b.WriteString("    Object.keys(formData).forEach(k => formData[k] = '');\n")
 			}
-			if ctx.hasSuccessState {
-				b.WriteString("    success.value = 'Created successfully';\n")
+			b.WriteString("  } catch (err) {\n")
+			if ctx.hasErrorState {
+				b.WriteString("    error.value = err instanceof Error ? err.message : 'Failed to save';\n")
 			}
-			// Reset form
-			b.WriteString("    Object.keys(formData).forEach(k => formData[k] = '');\n")
+			b.WriteString("  }\n")
 		}
-		b.WriteString("  } catch (err) {\n")
-		if ctx.hasErrorState {
-			b.WriteString("    error.value = err instanceof Error ? err.message : 'Failed to save';\n")
-		}
-		b.WriteString("  }\n")
 		b.WriteString("}\n")
 	}
 
 	if needsEffect {
-		b.WriteString("\nonMounted(() => {\n")
-		if listEp != nil {
+		b.WriteString("\nfunction load() {\n")
+		b.WriteString("  loading.value = true;\n")
+		b.WriteString("  loadError.value = '';\n")
+		if listEp != nil && paged {
+			fmt.Fprintf(&b, "  %s(undefined, listQuery.value)\n", toCamelCase(listEp.Name))
+			fmt.Fprintf(&b, "    .then(res => { %s.value = res.data ?? []; hasNext.value = res.pagination?.nextCursor != null; loading.value = false; })\n", varName)
+			fmt.Fprintf(&b, "    .catch(err => { %s; loading.value = false; });\n", loadFailed(ctx))
+		} else if listEp != nil && ctx.scroll {
+			fmt.Fprintf(&b, "  %s()\n", toCamelCase(listEp.Name))
+			fmt.Fprintf(&b, "    .then(res => { %s.value = res.data ?? []; nextCursor.value = res.pagination?.nextCursor ?? null; loading.value = false; observeSentinel(); })\n", varName)
+			fmt.Fprintf(&b, "    .catch(err => { %s; loading.value = false; });\n", loadFailed(ctx))
+		} else if listEp != nil {
 			fmt.Fprintf(&b, "  %s()\n", toCamelCase(listEp.Name))
 			if modelName != "" {
 				fmt.Fprintf(&b, "    .then(res => { %s.value = res.data ?? []; loading.value = false; })\n", varName)
 			} else {
 				b.WriteString("    .then(res => { data.value = res.data ?? []; loading.value = false; })\n")
 			}
-			b.WriteString("    .catch(() => loading.value = false);\n")
+			fmt.Fprintf(&b, "    .catch(err => { %s; loading.value = false; });\n", loadFailed(ctx))
 		} else {
 			apiPath := "/api/" + toKebabCase(varName)
 			b.WriteString("  // TODO: replace with a dedicated API endpoint\n")
@@ -232,9 +431,36 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 			} else {
 				b.WriteString("    .then(res => { data.value = res.data ?? []; loading.value = false; })\n")
 			}
-			b.WriteString("    .catch(() => loading.value = false);\n")
+			fmt.Fprintf(&b, "    .catch(err => { %s; loading.value = false; });\n", loadFailed(ctx))
 		}
-		b.WriteString("});\n")
+		b.WriteString("}\n")
+		if ctx.remember && paged {
+			// Restoring the view loads the list as its query changes
+			b.WriteString("\nonMounted(() => { if (!restoringView) load(); });\n")
+		} else {
+			b.WriteString("\nonMounted(load);\n")
+		}
+		if listEp != nil && paged {
+			b.WriteString("watch(listQuery, load);\n")
+		}
+	}
+	if ctx.remember {
+		writeRememberScript(&b, ctx)
+	}
+	if ctx.scroll {
+		writeLoadMore(&b, listEp, ctx)
+	}
+	if ctx.deleteEp != nil {
+		writeDeleteHandler(&b, ctx.deleteEp, ctx)
+	}
+	if ctx.reorder != nil {
+		writeReorderHandlers(&b, ctx.reorder, ctx)
+	}
+	if needsFormState {
+		writeDialogFocus(&b)
+	}
+	if len(keyBindings) > 0 {
+		writeKeyBindings(&b, keyBindings, ctx)
 	}
 
 	b.WriteString("</script>\n\n")
@@ -242,46 +468,113 @@ func generatePage(page *ir.Page, app *ir.Application) string {
 	// <template>
 	b.WriteString("<template>\n")
 	fmt.Fprintf(&b, "  <div class=\"%s-page\">\n", toKebabCase(page.Name))
+	// Pages declaring their error state show load errors there
+	if ctx.loadError != "" && !ctx.hasErrorState {
+		writeLoadErrorVue(&b, "    ", ctx)
+	}
 
 	loopFields := collectLoopFields(page, ctx)
+	ctx.itemFields = loopFields
 	loopRendered := false
 	for _, a := range page.Content {
+		if ctx.table != nil && ctx.table.Covers(a) {
+			fmt.Fprintf(&b, "    <!-- %s — rendered by the table -->\n", a.Text)
+			continue
+		}
 		if a.Type == "loop" && loopRendered {
 			continue
 		}
 		if a.Type == "loop" {
 			loopRendered = true
 			writeLoopVue(&b, a.Text, "    ", ctx, loopFields)
+			if ctx.table == nil {
+				writeListEnd(&b, "    ", page, ctx)
+			}
 			continue
 		}
 		writePageActionVue(&b, a, "    ", ctx)
+		if ctx.table != nil && a == ctx.table.Show {
+			writeListEnd(&b, "    ", page, ctx)
+		}
 	}
 
 	if needsFormState {
 		b.WriteString("    <div v-if=\"showForm\" class=\"modal-overlay\" @click=\"showForm = false\">\n")
-		b.WriteString("      <div class=\"modal\" @click.stop>\n")
-		b.WriteString("        <button class=\"modal-close\" @click=\"showForm = false\">&times;</button>\n")
+		b.WriteString("      <div\n")
+		b.WriteString("        ref=\"dialog\"\n")
+		b.WriteString("        class=\"modal\"\n")
+		b.WriteString("        role=\"dialog\"\n")
+		b.WriteString("        aria-modal=\"true\"\n")
+		title := ""
+		if modelName != "" {
+			title = ctx.uniqueID("new-" + toKebabCase(modelName) + "-title")
+			fmt.Fprintf(&b, "        aria-labelledby=\"%s\"\n", title)
+		} else {
+			b.WriteString("        aria-label=\"New\"\n")
+		}
+		b.WriteString("        @click.stop\n")
+		b.WriteString("        @keydown.esc=\"showForm = false\"\n")
+		b.WriteString("      >\n")
+		b.WriteString("        <button type=\"button\" class=\"modal-close\" aria-label=\"Close\" @click=\"showForm = false\">&times;</button>\n")
 		if modelName != "" {
-			fmt.Fprintf(&b, "        <h2>New %s</h2>\n", modelName)
+			fmt.Fprintf(&b, "        <h2 id=\"%s\">New %s</h2>\n", title, modelName)
 		}
 		writeFormVue(&b, "a form to create a "+modelName, "        ", ctx)
 		b.WriteString("      </div>\n")
 		b.WriteString("    </div>\n")
 	}
+	if ctx.panel != nil {
+		writePanelVue(&b, "    ", ctx)
+	}
 
 	b.WriteString("  </div>\n")
 	b.WriteString("</template>\n")
 
-	return b.String()
+	if ctx.responsive != nil {
+		b.WriteString("\n<style scoped>\n")
+		b.WriteString(responsiveStyles(page, ctx))
+		b.WriteString("</style>\n")
+	}
+
+	return withFormatImports(withTimeImports(b.String(), ctx), ctx)
 }
 
 func writePageActionVue(b *strings.Builder, a *ir.Action, indent string, ctx *pageContext) {
+	if ctx.record != nil && ir.IsDetailRoute(a) {
+		writeRecordVue(b, indent, ctx)
+		return
+	}
+	if ctx.edit != nil && a == ctx.edit.Form {
+		writeEditFormVue(b, indent, ctx)
+		return
+	}
+	if ctx.importForm != nil && a == ctx.importForm.Form {
+		writeImportFormVue(b, indent, ctx)
+		return
+	}
 	switch a.Type {
 	case "display":
+		if a.Template != "" {
+			writeTemplateVue(b, a.Template, indent, ctx)
+			return
+		}
+		if ctx.table != nil && a == ctx.table.Show {
+			writeTableVue(b, indent, ctx)
+			return
+		}
+		if c := ir.ChartFor(ctx.app, a); c != nil {
+			writeChartVue(b, c, indent)
+			return
+		}
 		writeDisplayVue(b, a.Text, indent, ctx)
 	case "input":
 		writeInputVue(b, a.Text, indent, ctx)
 	case "interact":
+		if ir.IsReorder(a) || ir.IsKeyBinding(a) || ir.IsInfiniteScroll(a) || ir.IsDelete(a) && ctx.deleteEp != nil ||
+			tooltipWired(a, ctx) || panelWired(a, ctx) || a == ctx.itemLink {
+			writeInteractionNote(b, a, indent, ctx)
+			return
+		}
 		writeInteractVue(b, a.Text, indent, ctx)
 	case "condition":
 		writeConditionVue(b, a.Text, indent, ctx)
@@ -290,6 +583,14 @@ func writePageActionVue(b *strings.Builder, a *ir.Action, indent string, ctx *pa
 	case "query":
 		// handled by onMounted
 	default:
+		if ctx.remember && ir.IsRememberView(a) {
+			fmt.Fprintf(b, "%s<!-- %s — restored from sessionStorage -->\n", indent, a.Text)
+			return
+		}
+		if ctx.responsive != nil && ir.IsResponsive(a) {
+			fmt.Fprintf(b, "%s<!-- %s — handled by the page's styles -->\n", indent, a.Text)
+			return
+		}
 		fmt.Fprintf(b, "%s<!-- TODO: %s -->\n", indent, a.Text)
 	}
 }
@@ -463,6 +764,12 @@ func writeDisplayVue(b *strings.Builder, text string, indent string, ctx *pageCo
 		return
 	}
 
+	// Pagination
+	if (strings.Contains(lower, "pagination") || strings.Contains(lower, "page controls")) && ctx.query != nil && ctx.query.Paged {
+		writePaginationVue(b, indent)
+		return
+	}
+
 	// Sidebar
 	if strings.Contains(lower, "sidebar") {
 		fmt.Fprintf(b, "%s<aside class=\"sidebar\">\n", indent)
@@ -485,31 +792,84 @@ func writeDisplayVue(b *strings.Builder, text string, indent string, ctx *pageCo
 	fmt.Fprintf(b, "%s<div class=\"%s\"></div>\n", indent, slugify(text))
 }
 
+// writeTemplateVue renders show "..." text. References interpolate a
+// component's props, or a page's data from its first loaded record; Vue
+// escapes the values, and the text is escaped so braces aren't read as
+// interpolation.
+func writeTemplateVue(b *strings.Builder, tmpl string, indent string, ctx *pageContext) {
+	var out strings.Builder
+	for _, part := range ir.ParseTemplate(tmpl) {
+		if part.Root == "" {
+			out.WriteString(vueText.Replace(part.Text))
+			continue
+		}
+		fmt.Fprintf(&out, "{{ %s }}", templateExpr(part, ctx))
+	}
+	fmt.Fprintf(b, "%s<p>%s</p>\n", indent, out.String())
+}
+
+var vueText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "{", "&#123;", "}", "&#125;")
+
+// templateExpr resolves a template reference: "task.title" to a prop's
+// field, or to the page's first record.
+func templateExpr(ref ir.TemplatePart, ctx *pageContext) string {
+	for name, typ := range ctx.props {
+		if strings.EqualFold(name, ref.Root) {
+			return name + templateField(findModel(ctx.app, typ), ref.Field, ".")
+		}
+	}
+	return ctx.varName + "[0]" + templateField(findModel(ctx.app, ctx.modelName), ref.Field, "?.")
+}
+
+// templateField returns sep and the model's name for field, or nothing
+// without a field.
+func templateField(model *ir.DataModel, field, sep string) string {
+	if field == "" {
+		return ""
+	}
+	if model != nil {
+		if f := model.FieldNamed(field); f != nil {
+			return sep + f.Name
+		}
+	}
+	return sep + field
+}
+
 // ── Input ──
 
 func writeInputVue(b *strings.Builder, text string, indent string, ctx *pageContext) {
 	lower := strings.ToLower(text)
 
+	if strings.Contains(lower, "search") && ctx.query != nil {
+		writeSearchVue(b, indent)
+		return
+	}
 	if strings.Contains(lower, "search") {
-		fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" class=\"search-input\" @input=\"() => {/* TODO: filter */}\" />\n", indent)
+		fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" aria-label=\"Search\" class=\"search-input\" @input=\"() => {/* TODO: filter */}\" />\n", indent)
 		return
 	}
+	if ctx.query != nil {
+		if f := ir.FilterField(ctx.query.Model, &ir.Action{Type: "input", Text: text}); f != nil {
+			writeFilterVue(b, indent, f)
+			return
+		}
+	}
 	if strings.Contains(lower, "dropdown") || strings.Contains(lower, "filter by") || strings.Contains(lower, "select") {
-		label := "All"
+		label, name := "All", "Filter"
 		if strings.Contains(lower, "status") {
-			label = "All Statuses"
+			label, name = "All Statuses", "Filter by status"
 		} else if strings.Contains(lower, "priority") {
-			label = "All Priorities"
+			label, name = "All Priorities", "Filter by priority"
 		} else if strings.Contains(lower, "category") {
-			label = "Select Category"
+			label, name = "Select Category", "Category"
 		}
-		fmt.Fprintf(b, "%s<select class=\"filter-select\" @change=\"() => {/* TODO: filter */}\">\n", indent)
+		fmt.Fprintf(b, "%s<select class=\"filter-select\" aria-label=\"%s\" @change=\"() => {/* TODO: filter */}\">\n", indent, name)
 		fmt.Fprintf(b, "%s  <option value=\"\">%s</option>\n", indent, label)
 		fmt.Fprintf(b, "%s</select>\n", indent)
 		return
 	}
 	if strings.Contains(lower, "date") && (strings.Contains(lower, "picker") || strings.Contains(lower, "range")) {
-		fmt.Fprintf(b, "%s<input type=\"date\" class=\"date-filter\" @change=\"() => {/* TODO: filter */}\" />\n", indent)
+		fmt.Fprintf(b, "%s<input type=\"date\" class=\"date-filter\" aria-label=\"Filter by date\" @change=\"() => {/* TODO: filter */}\" />\n", indent)
 		return
 	}
 	if strings.Contains(lower, "button") && (strings.Contains(lower, "create") || strings.Contains(lower, "new") || strings.Contains(lower, "add")) {
@@ -536,9 +896,10 @@ func writeInputVue(b *strings.Builder, text string, indent string, ctx *pageCont
 		} else if strings.Contains(lower, "cover") || strings.Contains(lower, "image") {
 			label = "Upload image"
 		}
+		id := ctx.uniqueID("file-upload")
 		fmt.Fprintf(b, "%s<div class=\"file-upload\">\n", indent)
-		fmt.Fprintf(b, "%s  <label>%s</label>\n", indent, label)
-		fmt.Fprintf(b, "%s  <input type=\"file\" accept=\"image/*\" @change=\"(ev) => { const f = ev.target.files?.[0]; if (f) { const fd = new FormData(); fd.append('file', f); fetch('/api/upload', { method: 'POST', body: fd }); } }\" />\n", indent)
+		fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, id, label)
+		fmt.Fprintf(b, "%s  <input id=\"%s\" type=\"file\" accept=\"image/*\" @change=\"(ev) => { const f = ev.target.files?.[0]; if (f) { const fd = new FormData(); fd.append('file', f); fetch('/api/upload', { method: 'POST', body: fd }); } }\" />\n", indent, id)
 		fmt.Fprintf(b, "%s</div>\n", indent)
 		return
 	}
@@ -548,7 +909,7 @@ func writeInputVue(b *strings.Builder, text string, indent string, ctx *pageCont
 	}
 	if strings.Contains(lower, "rich text") || strings.Contains(lower, "editor") {
 		fmt.Fprintf(b, "%s<div class=\"rich-text-editor\">\n", indent)
-		fmt.Fprintf(b, "%s  <textarea placeholder=\"Write your content...\"></textarea>\n", indent)
+		fmt.Fprintf(b, "%s  <textarea placeholder=\"Write your content...\" aria-label=\"%s\"></textarea>\n", indent, inputLabel(text))
 		fmt.Fprintf(b, "%s</div>\n", indent)
 		return
 	}
@@ -560,9 +921,10 @@ func writeInputVue(b *strings.Builder, text string, indent string, ctx *pageCont
 				break
 			}
 		}
+		id := ctx.uniqueID(toKebabCase(toCamelCase(fieldName)))
 		fmt.Fprintf(b, "%s<div class=\"form-field\">\n", indent)
-		fmt.Fprintf(b, "%s  <label>%s</label>\n", indent, capitalize(fieldName))
-		fmt.Fprintf(b, "%s  <input type=\"text\" placeholder=\"%s\" />\n", indent, fieldName)
+		fmt.Fprintf(b, "%s  <label for=\"%s\">%s</label>\n", indent, id, capitalize(fieldName))
+		fmt.Fprintf(b, "%s  <input id=\"%s\" type=\"text\" placeholder=\"%s\" />\n", indent, id, fieldName)
 		fmt.Fprintf(b, "%s</div>\n", indent)
 		return
 	}
@@ -574,7 +936,7 @@ func writeInputVue(b *strings.Builder, text string, indent string, ctx *pageCont
 		fmt.Fprintf(b, "%s<button class=\"btn\">%s</button>\n", indent, label)
 		return
 	}
-	fmt.Fprintf(b, "%s<input type=\"text\" placeholder=\"%s\" />\n", indent, text)
+	fmt.Fprintf(b, "%s<input type=\"text\" placeholder=\"%s\" aria-label=\"%s\" />\n", indent, text, inputLabel(text))
 }
 
 func writeFormVue(b *strings.Builder, text string, indent string, ctx *pageContext) {
@@ -594,69 +956,93 @@ func writeFormVue(b *strings.Builder, text string, indent string, ctx *pageConte
 		}
 	}
 
-	if createEp != nil {
-		createFunc := toCamelCase(createEp.Name)
+	model := formModel(lower, ctx)
+	formID := "form"
+	switch {
+	case isLogin:
+		formID = "login-form"
+	case model != nil:
+		formID = toKebabCase(model.Name) + "-form"
+	}
+	formID = ctx.uniqueID(formID)
+
+	// A create endpoint's form is bound to formData, submitted by the
+	// page's handleSubmit
+	bound := createEp != nil
+	if bound {
 		fmt.Fprintf(b, "%s<form class=\"form\" @submit.prevent=\"handleSubmit\">\n", indent)
-		// Generate formData reactive object in script setup (done via import)
-		for _, f := range fields {
-			inputType := "text"
-			fl := strings.ToLower(f)
-			if strings.Contains(fl, "email") {
-				inputType = "email"
-			} else if strings.Contains(fl, "password") {
-				inputType = "password"
-			} else if strings.Contains(fl, "date") {
-				inputType = "date"
-			} else if strings.Contains(fl, "number") || strings.Contains(fl, "count") {
-				inputType = "number"
-			}
-			fmt.Fprintf(b, "%s  <div class=\"form-field\">\n", indent)
-			fmt.Fprintf(b, "%s    <label>%s</label>\n", indent, capitalize(f))
-			fmt.Fprintf(b, "%s    <input type=\"%s\" v-model=\"formData.%s\" placeholder=\"%s\" />\n", indent, inputType, toCamelCase(f), capitalize(f))
-			fmt.Fprintf(b, "%s  </div>\n", indent)
+	} else if ctx.hasSuccessState && ctx.hasErrorState {
+		fmt.Fprintf(b, "%s<form class=\"form\" @submit.prevent=\"error = ''; success = 'Saved successfully'\">\n", indent)
+	} else if ctx.hasSuccessState {
+		fmt.Fprintf(b, "%s<form class=\"form\" @submit.prevent=\"success = 'Saved successfully'\">\n", indent)
+	} else {
+		fmt.Fprintf(b, "%s<form class=\"form\" @submit.prevent>\n", indent)
+	}
+	for _, in := range formInputs(formID, fields, isLogin, model) {
+		binding := ""
+		if bound {
+			binding = "formData." + in.name
 		}
-		fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
-		fmt.Fprintf(b, "%s</form>\n", indent)
+		writeFormFieldVue(b, indent+"  ", in, binding, ctx)
+	}
+	fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
+	fmt.Fprintf(b, "%s</form>\n", indent)
+}
 
-		// We need to emit the script-level handleSubmit and formData.
-		// These are emitted as comments for now since they need to go in <script setup>
-		// The actual wiring is handled by the needsFormState + createEp variables
-		_ = createFunc
-		_ = isLogin
-	} else {
-		if ctx.hasSuccessState && ctx.hasErrorState {
-			fmt.Fprintf(b, "%s<form class=\"form\" @submit.prevent=\"error = ''; success = 'Saved successfully'\">\n", indent)
-		} else if ctx.hasSuccessState {
-			fmt.Fprintf(b, "%s<form class=\"form\" @submit.prevent=\"success = 'Saved successfully'\">\n", indent)
+// ── Table ──
+
+// writeTableVue renders the page's table: a header per column, sortable
+// ones clickable, and a row per record.
+func writeTableVue(b *strings.Builder, indent string, ctx *pageContext) {
+	t := ctx.table
+	rows := rowsVar(ctx)
+	fmt.Fprintf(b, "%s<table class=\"data-table\">\n", indent)
+	fmt.Fprintf(b, "%s  <thead>\n", indent)
+	fmt.Fprintf(b, "%s    <tr>\n", indent)
+	for _, f := range t.Columns {
+		label := ir.FieldLabel(f.Name)
+		if t.IsSortable(f) {
+			fmt.Fprintf(b, "%s      <th class=\"sortable\" @click=\"sortBy('%s')\">\n", indent, f.Name)
+			fmt.Fprintf(b, "%s        %s{{ sortKey === '%s' ? (sortDesc ? ' ▼' : ' ▲') : '' }}\n", indent, label, f.Name)
+			fmt.Fprintf(b, "%s      </th>\n", indent)
 		} else {
-			fmt.Fprintf(b, "%s<form class=\"form\" @submit.prevent>\n", indent)
+			fmt.Fprintf(b, "%s      <th>%s</th>\n", indent, label)
 		}
-		for _, f := range fields {
-			inputType := "text"
-			fl := strings.ToLower(f)
-			if strings.Contains(fl, "email") {
-				inputType = "email"
-			} else if strings.Contains(fl, "password") {
-				inputType = "password"
-			} else if strings.Contains(fl, "date") {
-				inputType = "date"
-			} else if strings.Contains(fl, "number") || strings.Contains(fl, "count") {
-				inputType = "number"
-			}
-			fmt.Fprintf(b, "%s  <div class=\"form-field\">\n", indent)
-			fmt.Fprintf(b, "%s    <label>%s</label>\n", indent, capitalize(f))
-			fmt.Fprintf(b, "%s    <input type=\"%s\" name=\"%s\" placeholder=\"%s\" />\n", indent, inputType, toCamelCase(f), capitalize(f))
-			fmt.Fprintf(b, "%s  </div>\n", indent)
+	}
+	fmt.Fprintf(b, "%s    </tr>\n", indent)
+	fmt.Fprintf(b, "%s  </thead>\n", indent)
+	fmt.Fprintf(b, "%s  <tbody>\n", indent)
+	if t.RowPage != "" {
+		fmt.Fprintf(b, "%s    <tr v-for=\"%s in %s\" :key=\"%s.id\" class=\"clickable\" @click=\"router.push(%s)\">\n",
+			indent, ctx.itemVar, rows, ctx.itemVar, recordURL(t.RowPage, ctx.itemVar, ctx))
+	} else if ctx.panel != nil {
+		fmt.Fprintf(b, "%s    <tr v-for=\"%s in %s\" :key=\"%s.id\" class=\"clickable\"%s>\n", indent, ctx.itemVar, rows, ctx.itemVar, panelClick(ctx.itemVar, ctx))
+	} else {
+		fmt.Fprintf(b, "%s    <tr v-for=\"%s in %s\" :key=\"%s.id\">\n", indent, ctx.itemVar, rows, ctx.itemVar)
+	}
+	for _, f := range t.Columns {
+		value := fmt.Sprintf("{{ %s.%s }}", ctx.itemVar, f.Name)
+		if f.Type == "boolean" {
+			value = fmt.Sprintf("{{ %s.%s ? 'Yes' : 'No' }}", ctx.itemVar, f.Name)
+		} else if t := timeVue(ctx.itemVar+"."+f.Name, f, ctx); t != "" {
+			value = t
+		} else if n := numberVue(ctx.itemVar+"."+f.Name, f, ctx); n != "" {
+			value = n
+		}
+		if tip := tooltipOn(f.Name, ctx); tip != nil {
+			value = fmt.Sprintf("<Tooltip %s>%s</Tooltip>", tooltipText(tip, ctx.itemVar), value)
 		}
-		fmt.Fprintf(b, "%s  <button type=\"submit\">Save</button>\n", indent)
-		fmt.Fprintf(b, "%s</form>\n", indent)
+		fmt.Fprintf(b, "%s      <td>%s</td>\n", indent, value)
 	}
+	fmt.Fprintf(b, "%s    </tr>\n", indent)
+	fmt.Fprintf(b, "%s  </tbody>\n", indent)
+	fmt.Fprintf(b, "%s</table>\n", indent)
 }
 
 // ── Loop ──
 
 func writeLoopVue(b *strings.Builder, text string, indent string, ctx *pageContext, fields []string) {
-	dataVar := ctx.varName
+	dataVar := rowsVar(ctx)
 	if dataVar == "" {
 		dataVar = "data"
 	}
@@ -665,11 +1051,36 @@ func writeLoopVue(b *strings.Builder, text string, indent string, ctx *pageConte
 		item = "item"
 	}
 
+	// A list users can drag renders its items through vuedraggable, whose
+	// v-model shows the new order at once
+	open := func(attrs string) string {
+		if ctx.reorder != nil {
+			fmt.Fprintf(b, "%s<draggable v-model=\"%s\" item-key=\"id\" class=\"sortable-list\" @start=\"rememberOrder\" @end=\"saveOrder\">\n", indent, dataVar)
+			fmt.Fprintf(b, "%s  <template #item=\"{ element: %s }\">\n", indent, item)
+			fmt.Fprintf(b, "%s    <div%s>\n", indent, attrs)
+			return indent + "    "
+		}
+		fmt.Fprintf(b, "%s<div v-for=\"%s in %s\" :key=\"%s.id\"%s>\n", indent, item, dataVar, item, attrs)
+		return indent
+	}
+	close := func() {
+		if ctx.reorder != nil {
+			fmt.Fprintf(b, "%s    </div>\n", indent)
+			fmt.Fprintf(b, "%s  </template>\n", indent)
+			fmt.Fprintf(b, "%s</draggable>\n", indent)
+			return
+		}
+		fmt.Fprintf(b, "%s</div>\n", indent)
+	}
+
 	compRef := extractComponentRef(text)
 	if compRef != "" {
-		fmt.Fprintf(b, "%s<div v-for=\"%s in %s\" :key=\"%s.id\">\n", indent, item, dataVar, item)
-		fmt.Fprintf(b, "%s  <%s :%s=\"%s\" @click=\"() => {}\" />\n", indent, compRef, item, item)
-		fmt.Fprintf(b, "%s</div>\n", indent)
+		inner := open(itemClick(item, ctx))
+		fmt.Fprintf(b, "%s  <%s :%s=\"%s\" @click=\"() => {}\" />\n", inner, compRef, item, item)
+		if ctx.deleteEp != nil {
+			writeDeleteButton(b, inner+"  ", item, ctx)
+		}
+		close()
 		return
 	}
 
@@ -681,29 +1092,71 @@ func writeLoopVue(b *strings.Builder, text string, indent string, ctx *pageConte
 	if modelClass == "" {
 		modelClass = "item"
 	}
-	fmt.Fprintf(b, "%s<div v-for=\"%s in %s\" :key=\"%s.id\" class=\"%s-item\">\n", indent, item, dataVar, item, modelClass)
+	inner := open(fmt.Sprintf(" class=\"%s-item\"%s", modelClass, itemClick(item, ctx)))
+	whole := tooltipOn("", ctx)
+	if whole != nil {
+		fmt.Fprintf(b, "%s  <Tooltip %s>\n", inner, tooltipText(whole, item))
+		inner += "  "
+	}
 	if len(fields) > 0 {
 		for _, f := range fields {
 			fieldExpr := item + "." + f
 			fl := strings.ToLower(f)
+			var el string
 			if fl == "status" || fl == "role" || fl == "priority" || fl == "category" {
-				fmt.Fprintf(b, "%s  <span class=\"badge\">{{ %s }}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span class=\"badge\">{{ %s }}</span>", fieldExpr)
 			} else if fl == "title" || fl == "name" {
-				fmt.Fprintf(b, "%s  <h3>{{ %s }}</h3>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<h3>{{ %s }}</h3>", fieldExpr)
+			} else if t := timeVue(fieldExpr, modelField(f, ctx), ctx); t != "" {
+				el = t
+			} else if n := numberVue(fieldExpr, modelField(f, ctx), ctx); n != "" {
+				el = fmt.Sprintf("<span class=\"%s\">%s</span>", numberClass(modelField(f, ctx)), n)
 			} else if strings.Contains(fl, "date") || fl == "due" || fl == "created" || strings.Contains(fl, "published") {
-				fmt.Fprintf(b, "%s  <time>{{ %s }}</time>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<time>{{ %s }}</time>", fieldExpr)
 			} else if strings.Contains(fl, "excerpt") {
-				fmt.Fprintf(b, "%s  <p>{{ %s }}</p>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<p>{{ %s }}</p>", fieldExpr)
 			} else if strings.Contains(fl, "count") || strings.Contains(fl, "view") {
-				fmt.Fprintf(b, "%s  <span class=\"count\">{{ %s }}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span class=\"count\">{{ %s }}</span>", fieldExpr)
 			} else {
-				fmt.Fprintf(b, "%s  <span>{{ %s }}</span>\n", indent, fieldExpr)
+				el = fmt.Sprintf("<span>{{ %s }}</span>", fieldExpr)
 			}
+			if tip := tooltipOn(f, ctx); tip != nil {
+				el = fmt.Sprintf("<Tooltip %s>%s</Tooltip>", tooltipText(tip, item), el)
+			}
+			fmt.Fprintf(b, "%s  %s\n", inner, el)
 		}
 	} else {
-		fmt.Fprintf(b, "%s  <span>{{ JSON.stringify(%s) }}</span>\n", indent, item)
+		fmt.Fprintf(b, "%s  <span>{{ JSON.stringify(%s) }}</span>\n", inner, item)
+	}
+	if whole != nil {
+		inner = inner[:len(inner)-2]
+		fmt.Fprintf(b, "%s  </Tooltip>\n", inner)
+	}
+	cal := ir.CalendarFor(ctx.app, ctx.modelName)
+	if cal != nil {
+		fmt.Fprintf(b, "%s  <AddToCalendar feed=\"%s\" :id=\"%s.id\" />\n", inner, cal.Slug, item)
+	}
+	if ctx.deleteEp != nil {
+		writeDeleteButton(b, inner+"  ", item, ctx)
+	}
+	close()
+	if cal != nil {
+		fmt.Fprintf(b, "%s<AddToCalendar feed=\"%s\" />\n", indent, cal.Slug)
+	}
+}
+
+// pageLoopsOverCalendar reports whether the page lists records of a model
+// published as a calendar, so each one gets an "Add to calendar" button.
+func pageLoopsOverCalendar(page *ir.Page, app *ir.Application, modelName string) bool {
+	if ir.CalendarFor(app, modelName) == nil {
+		return false
 	}
-	fmt.Fprintf(b, "%s</div>\n", indent)
+	for _, a := range page.Content {
+		if a.Type == "loop" {
+			return true
+		}
+	}
+	return false
 }
 
 // ── Condition ──
@@ -718,9 +1171,7 @@ func writeConditionVue(b *strings.Builder, text string, indent string, ctx *page
 	// Loading
 	if strings.Contains(lower, "while loading") || strings.Contains(lower, "is loading") {
 		if strings.Contains(lower, "skeleton") {
-			fmt.Fprintf(b, "%s<div v-if=\"loading\" class=\"skeleton-screen\">\n", indent)
-			fmt.Fprintf(b, "%s  <div class=\"skeleton-item\" v-for=\"i in 3\" :key=\"i\"></div>\n", indent)
-			fmt.Fprintf(b, "%s</div>\n", indent)
+			writeSkeletonVue(b, indent, ctx)
 		} else {
 			fmt.Fprintf(b, "%s<div v-if=\"loading\" class=\"loading-spinner\">\n", indent)
 			fmt.Fprintf(b, "%s  <div class=\"spinner\"></div>\n", indent)
@@ -780,13 +1231,16 @@ func writeConditionVue(b *strings.Builder, text string, indent string, ctx *page
 		if message == "" {
 			message = "Success!"
 		}
-		fmt.Fprintf(b, "%s<div v-if=\"success\" class=\"alert alert-success\">{{ success || '%s' }}</div>\n", indent, message)
+		fmt.Fprintf(b, "%s<div role=\"status\"><div v-if=\"success\" class=\"alert alert-success\">{{ success || '%s' }}</div></div>\n", indent, message)
 		return
 	}
 
 	// Error
 	if strings.Contains(lower, "error") {
-		fmt.Fprintf(b, "%s<div v-if=\"error\" class=\"alert alert-error\">{{ error }}</div>\n", indent)
+		fmt.Fprintf(b, "%s<div role=\"alert\"><div v-if=\"error\" class=\"alert alert-error\">{{ error }}</div></div>\n", indent)
+		if ctx.loadError != "" {
+			writeLoadErrorVue(b, indent, ctx)
+		}
 		return
 	}
 
@@ -928,6 +1382,12 @@ func writeInteractVue(b *strings.Builder, text string, indent string, ctx *pageC
 
 func detectPageModel(page *ir.Page, app *ir.Application) (modelName, varName, itemVar string) {
 	for _, a := range page.Content {
+		if ir.IsTable(a) {
+			if m := ir.TableModel(app, a); m != nil {
+				return m.Name, strings.ToLower(pluralize(m.Name)), strings.ToLower(m.Name)
+			}
+			continue
+		}
 		if a.Type == "query" || a.Type == "loop" {
 			for _, m := range app.Data {
 				lowerText := strings.ToLower(a.Text)
@@ -954,7 +1414,7 @@ func findListEndpoint(app *ir.Application, modelName string) *ir.Endpoint {
 	}
 	for i := range app.APIs {
 		lower := strings.ToLower(app.APIs[i].Name)
-		if strings.HasPrefix(lower, "get") && strings.Contains(lower, lowerModel) {
+		if strings.HasPrefix(lower, "get") && strings.Contains(lower, lowerModel) && !ir.LoadsRecord(app, app.APIs[i]) {
 			return app.APIs[i]
 		}
 	}
@@ -1255,6 +1715,10 @@ func extractFormFields(lower string, ctx *pageContext) []string {
 						if f.Encrypted {
 							continue
 						}
+						// Dragging the list sets its position
+						if r := ir.ReorderFor(ctx.app, model.Name); r != nil && r.Field == f.Name {
+							continue
+						}
 						fields = append(fields, f.Name)
 					}
 					return fields
@@ -0,0 +1,166 @@
+package vue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeListQueryScript declares the view of the page's list its URL holds:
+// the search, each filter, the sort, and the page, read from the route's
+// query and pushed back to it, so the view can be shared, bookmarked, and
+// gone back to. Changing the search, a filter, or the sort goes back to the
+// first page. A list the browser narrows itself gets the records matching
+// the view.
+func writeListQueryScript(b *strings.Builder, ctx *pageContext) {
+	q := ctx.query
+	if q.Search {
+		b.WriteString("const search = computed(() => String(route.query.q ?? ''));\n")
+	}
+	for _, f := range q.Filters {
+		fmt.Fprintf(b, "const %s = computed(() => String(route.query.%s ?? ''));\n", filterVar(f), f.Name)
+	}
+	if len(q.Sortable) > 0 {
+		var keys []string
+		for _, f := range q.Sortable {
+			keys = append(keys, "'"+f.Name+"'")
+		}
+		initial, desc := "null", "false"
+		if q.SortBy != nil {
+			initial = "'" + q.SortBy.Name + "'"
+			desc = fmt.Sprintf("!route.query.sort && %t", q.Desc)
+		}
+		fmt.Fprintf(b, "const sortKey = computed(() => ([%s] as const).find((key) => key === route.query.sort) ?? %s);\n", strings.Join(keys, ", "), initial)
+		fmt.Fprintf(b, "const sortDesc = computed(() => (route.query.order ? route.query.order === 'desc' : %s));\n", desc)
+	}
+	if q.Paged {
+		b.WriteString("const page = computed(() => Math.max(Number(route.query.page) || 1, 1));\n")
+		b.WriteString("const hasNext = ref(false);\n")
+	}
+	b.WriteString("function setParam(name: string, value: string) {\n")
+	b.WriteString("  const query = { ...route.query, [name]: value || undefined };\n")
+	b.WriteString("  if (name !== 'page') delete query.page;\n")
+	b.WriteString("  router.push({ query });\n")
+	b.WriteString("}\n")
+	if len(q.Sortable) > 0 {
+		b.WriteString("function sortBy(key: string) {\n")
+		b.WriteString("  const query = { ...route.query, sort: key, order: key === sortKey.value && !sortDesc.value ? 'desc' : 'asc' };\n")
+		b.WriteString("  delete query.page;\n")
+		b.WriteString("  router.push({ query });\n")
+		b.WriteString("}\n")
+	}
+	if q.Paged {
+		writeListQuery(b, q)
+	} else {
+		writeVisibleRows(b, ctx)
+	}
+}
+
+// writeListQuery declares the query a paginated list is fetched with: its
+// search, filters, sort, and page.
+func writeListQuery(b *strings.Builder, q *ir.ListQuery) {
+	b.WriteString("const listQuery = computed(() => {\n")
+	b.WriteString("  const query: Record<string, string> = {};\n")
+	if q.Search {
+		b.WriteString("  if (search.value) query.q = search.value;\n")
+	}
+	for _, f := range q.Filters {
+		fmt.Fprintf(b, "  if (%s.value) query.%s = %s.value;\n", filterVar(f), f.Name, filterVar(f))
+	}
+	if len(q.Sortable) > 0 {
+		b.WriteString("  if (sortKey.value) {\n")
+		b.WriteString("    query.sort = sortKey.value;\n")
+		b.WriteString("    query.order = sortDesc.value ? 'desc' : 'asc';\n")
+		b.WriteString("  }\n")
+	}
+	b.WriteString("  if (page.value > 1) query.page = String(page.value);\n")
+	b.WriteString("  return query;\n")
+	b.WriteString("});\n")
+}
+
+// writeVisibleRows declares the records of a list loaded whole that match
+// the search and filters, in the order picked.
+func writeVisibleRows(b *strings.Builder, ctx *pageContext) {
+	q, item := ctx.query, ctx.itemVar
+	var conds []string
+	for _, f := range q.Filters {
+		conds = append(conds, fmt.Sprintf("(!%s.value || String(%s.%s) === %s.value)", filterVar(f), item, f.Name, filterVar(f)))
+	}
+	if q.Search {
+		conds = append(conds, fmt.Sprintf("(!search.value || Object.values(%s).some((value) => String(value ?? '').toLowerCase().includes(search.value.toLowerCase())))", item))
+	}
+	fmt.Fprintf(b, "const %s = computed(() => {\n", rowsVar(ctx))
+	if len(conds) > 0 {
+		fmt.Fprintf(b, "  const rows = %s.value.filter((%s) =>\n", ctx.varName, item)
+		fmt.Fprintf(b, "    %s,\n", strings.Join(conds, " &&\n    "))
+		b.WriteString("  );\n")
+	} else {
+		fmt.Fprintf(b, "  const rows = [...%s.value];\n", ctx.varName)
+	}
+	if len(q.Sortable) > 0 {
+		b.WriteString("  const key = sortKey.value;\n")
+		b.WriteString("  if (key) {\n")
+		b.WriteString("    rows.sort((a, b) => {\n")
+		b.WriteString("      const order = String(a[key] ?? '').localeCompare(String(b[key] ?? ''), undefined, { numeric: true });\n")
+		b.WriteString("      return sortDesc.value ? -order : order;\n")
+		b.WriteString("    });\n")
+		b.WriteString("  }\n")
+	}
+	b.WriteString("  return rows;\n")
+	b.WriteString("});\n")
+}
+
+// rowsVar returns the records the page's list renders: the ones matching
+// its view, or all it loaded when the API pages them.
+func rowsVar(ctx *pageContext) string {
+	if ctx.query == nil || ctx.query.Paged {
+		return ctx.varName
+	}
+	return "visible" + capitalize(ctx.varName)
+}
+
+// filterVar returns the name of the value a list is filtered by f to.
+func filterVar(f *ir.DataField) string {
+	return toCamelCase(f.Name) + "Filter"
+}
+
+// filterOptions returns the values and labels of a filter's options.
+func filterOptions(f *ir.DataField) [][2]string {
+	if f.Type == "boolean" {
+		return [][2]string{{"true", "Yes"}, {"false", "No"}}
+	}
+	var opts [][2]string
+	for _, v := range f.EnumValues {
+		opts = append(opts, [2]string{v, capitalize(v)})
+	}
+	return opts
+}
+
+// writeSearchVue renders the search input of a list, kept in the URL.
+func writeSearchVue(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<input type=\"search\" placeholder=\"Search...\" aria-label=\"Search\" class=\"search-input\" :value=\"search\" @input=\"setParam('q', ($event.target as HTMLInputElement).value)\" />\n", indent)
+}
+
+// writeFilterVue renders the dropdown filtering a list by f, kept in the
+// URL.
+func writeFilterVue(b *strings.Builder, indent string, f *ir.DataField) {
+	label := ir.FieldLabel(f.Name)
+	fmt.Fprintf(b, "%s<select class=\"filter-select\" aria-label=\"Filter by %s\" :value=\"%s\" @change=\"setParam('%s', ($event.target as HTMLSelectElement).value)\">\n",
+		indent, strings.ToLower(label), filterVar(f), f.Name)
+	fmt.Fprintf(b, "%s  <option value=\"\">All</option>\n", indent)
+	for _, o := range filterOptions(f) {
+		fmt.Fprintf(b, "%s  <option value=\"%s\">%s</option>\n", indent, o[0], o[1])
+	}
+	fmt.Fprintf(b, "%s</select>\n", indent)
+}
+
+// writePaginationVue renders the controls moving between the pages of a
+// paginated list, kept in the URL.
+func writePaginationVue(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%s<nav class=\"pagination\" aria-label=\"Pagination\">\n", indent)
+	fmt.Fprintf(b, "%s  <button class=\"page-btn\" :disabled=\"page <= 1\" @click=\"setParam('page', page > 2 ? String(page - 1) : '')\">&laquo; Prev</button>\n", indent)
+	fmt.Fprintf(b, "%s  <span class=\"page-number\" aria-current=\"page\">{{ page }}</span>\n", indent)
+	fmt.Fprintf(b, "%s  <button class=\"page-btn\" :disabled=\"!hasNext\" @click=\"setParam('page', String(page + 1))\">Next &raquo;</button>\n", indent)
+	fmt.Fprintf(b, "%s</nav>\n", indent)
+}
@@ -0,0 +1,83 @@
+package vue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// recordURL returns the expression for the URL of a record on page:
+// '/posts/' + post.id for a detail route, else the page with an id query.
+func recordURL(page, item string, ctx *pageContext) string {
+	prefix, field := ir.RecordLink(ctx.app, page)
+	return fmt.Sprintf("'%s' + %s.%s", prefix, item, field)
+}
+
+// itemClick returns the attributes making a listed record open the detail
+// panel, or navigate to the page its list links to, when clicked or when
+// Enter is pressed on it.
+func itemClick(item string, ctx *pageContext) string {
+	if ctx.panel != nil || ctx.itemPage == "" {
+		return panelClick(item, ctx)
+	}
+	url := recordURL(ctx.itemPage, item, ctx)
+	return fmt.Sprintf(" role=\"link\" tabindex=\"0\" @click=\"router.push(%s)\" @keydown.enter.self=\"router.push(%s)\"", url, url)
+}
+
+// writeRecordScript declares the record a detail route's page shows and
+// loads it whenever the URL's value changes: undefined while loading, null
+// when there is no such record.
+func writeRecordScript(b *strings.Builder, ctx *pageContext) {
+	r := ctx.record
+	v := strings.ToLower(r.Model[:1]) + r.Model[1:]
+	fmt.Fprintf(b, "const %s = ref<%s | null | undefined>(undefined);\n", v, r.Model)
+	fmt.Fprintf(b, "\nfunction load%s() {\n", r.Model)
+	fmt.Fprintf(b, "  const value = route.params.%s;\n", r.Param)
+	b.WriteString("  if (typeof value !== 'string') return;\n")
+	fmt.Fprintf(b, "  %s.value = undefined;\n", v)
+	b.WriteString("  loadError.value = '';\n")
+	fmt.Fprintf(b, "  %s({ %s: value })\n", toCamelCase(r.Endpoint), r.Arg)
+	fmt.Fprintf(b, "    .then(res => { %s.value = (res.data as %s) ?? null; })\n", v, r.Model)
+	b.WriteString("    .catch(err => {\n")
+	fmt.Fprintf(b, "      if (err instanceof ApiError && err.status === 404) %s.value = null;\n", v)
+	fmt.Fprintf(b, "      else %s;\n", loadFailed(ctx))
+	b.WriteString("    });\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(b, "\nwatch(() => route.params.%s, load%s, { immediate: true });\n", r.Param, r.Model)
+}
+
+// writeRecordVue renders the record a detail route's page shows: its title
+// as the heading and its other fields, or a not-found message.
+func writeRecordVue(b *strings.Builder, indent string, ctx *pageContext) {
+	r := ctx.record
+	v := strings.ToLower(r.Model[:1]) + r.Model[1:]
+	m := findModel(ctx.app, r.Model)
+	fmt.Fprintf(b, "%s<div v-if=\"%s === undefined && !loadError\" class=\"loading-spinner\">\n", indent, v)
+	fmt.Fprintf(b, "%s  <div class=\"spinner\"></div>\n", indent)
+	fmt.Fprintf(b, "%s</div>\n", indent)
+	fmt.Fprintf(b, "%s<div v-else-if=\"%s === null\" class=\"empty-state\">%s not found</div>\n", indent, v, ir.FieldLabel(r.Model))
+	fmt.Fprintf(b, "%s<article v-else class=\"%s-record\">\n", indent, toKebabCase(r.Model))
+	title := ir.RecordTitle(m)
+	if title != nil {
+		fmt.Fprintf(b, "%s  <h1>{{ %s.%s }}</h1>\n", indent, v, title.Name)
+	} else {
+		fmt.Fprintf(b, "%s  <h1>%s</h1>\n", indent, ir.FieldLabel(r.Model))
+	}
+	fmt.Fprintf(b, "%s  <dl class=\"detail-fields\">\n", indent)
+	for _, f := range ir.RecordFields(m) {
+		if f == title {
+			continue
+		}
+		fmt.Fprintf(b, "%s    <dt>%s</dt>\n", indent, ir.FieldLabel(f.Name))
+		if f.Type == "boolean" {
+			fmt.Fprintf(b, "%s    <dd>{{ %s.%s ? 'Yes' : 'No' }}</dd>\n", indent, v, f.Name)
+		} else if t := timeVue(v+"."+f.Name, f, ctx); t != "" {
+			fmt.Fprintf(b, "%s    <dd>%s</dd>\n", indent, t)
+		} else {
+			fmt.Fprintf(b, "%s    <dd>{{ %s.%s }}</dd>\n", indent, v, f.Name)
+		}
+	}
+	fmt.Fprintf(b, "%s  </dl>\n", indent)
+	fmt.Fprintf(b, "%s</article>\n", indent)
+}
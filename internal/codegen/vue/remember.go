@@ -0,0 +1,31 @@
+package vue
+
+import "strings"
+
+// writeRememberScript keeps the view of a page's list for when the user
+// comes back: leaving the page saves its query and how far down it was
+// scrolled to sessionStorage, and opening it restores them, the query
+// unless its URL names a view of its own, and the scroll once the list has
+// loaded. A restored query replaces the page's entry in the history
+// rather than adding one.
+func writeRememberScript(b *strings.Builder, ctx *pageContext) {
+	b.WriteString("\n// Coming back to the page restores the list as the user left it\n")
+	if ctx.query != nil {
+		b.WriteString("const savedView = sessionStorage.getItem(`view:${location.pathname}`);\n")
+		b.WriteString("const restoringView = !Object.keys(route.query).length && !!savedView;\n")
+		b.WriteString("if (restoringView) router.replace({ query: Object.fromEntries(new URLSearchParams(savedView!)) });\n")
+	}
+	b.WriteString("let savedScroll = Number(sessionStorage.getItem(`scroll:${location.pathname}`));\n")
+	b.WriteString("onBeforeRouteLeave(() => {\n")
+	if ctx.query != nil {
+		b.WriteString("  sessionStorage.setItem(`view:${location.pathname}`, new URLSearchParams(route.query as Record<string, string>).toString());\n")
+	}
+	b.WriteString("  sessionStorage.setItem(`scroll:${location.pathname}`, String(window.scrollY));\n")
+	b.WriteString("});\n")
+	b.WriteString("watch(loading, (isLoading) => {\n")
+	b.WriteString("  if (isLoading || !savedScroll) return;\n")
+	b.WriteString("  const y = savedScroll;\n")
+	b.WriteString("  savedScroll = 0;\n")
+	b.WriteString("  nextTick(() => window.scrollTo(0, y));\n")
+	b.WriteString("});\n")
+}
@@ -13,25 +13,47 @@ func generateRouter(app *ir.Application) string {
 	hasAuth := app.Auth != nil
 
 	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	if len(app.Experiments) > 0 {
+		b.WriteString("import { defineAsyncComponent } from 'vue';\n")
+	}
 	b.WriteString("import { createRouter, createWebHistory } from 'vue-router';\n")
 
 	if hasAuth {
 		b.WriteString("import { useAuth } from './composables/useAuth';\n")
 	}
 
+	if len(app.Experiments) > 0 {
+		b.WriteString("import { experimentRoute } from './experiments/experimentRoute';\n")
+	}
+
+	// Each page is split into its own chunk, loaded the first time its
+	// route is visited
+	b.WriteString("\n")
 	for _, page := range app.Pages {
 		name := page.Name + "Page"
-		fmt.Fprintf(&b, "import %s from './pages/%s.vue';\n", name, name)
+		fmt.Fprintf(&b, "const %s = () => import('./pages/%s.vue');\n", name, name)
 	}
 
 	b.WriteString("\nconst routes = [\n")
 	for _, page := range app.Pages {
 		name := page.Name + "Page"
 		path := routePath(page.Name)
+		if r := ir.DetailRouteFor(app, page.Name); r != nil {
+			path = r.Path()
+		}
+		component := name
+		if exp := controlExperiment(app, page.Name); exp != nil {
+			// The control page's route renders the visitor's assigned variant
+			var variants []string
+			for _, v := range exp.Variants {
+				variants = append(variants, fmt.Sprintf("%s: defineAsyncComponent(%sPage)", v.Page, variantPage(app, v.Page)))
+			}
+			component = fmt.Sprintf("experimentRoute('%s', '%s', { %s })", exp.Name, exp.Variants[0].Page, strings.Join(variants, ", "))
+		}
 		if hasAuth && !isPublicPage(page.Name) {
-			fmt.Fprintf(&b, "  { path: '%s', name: '%s', component: %s, meta: { requiresAuth: true } },\n", path, name, name)
+			fmt.Fprintf(&b, "  { path: '%s', name: '%s', component: %s, meta: { requiresAuth: true } },\n", path, name, component)
 		} else {
-			fmt.Fprintf(&b, "  { path: '%s', name: '%s', component: %s },\n", path, name, name)
+			fmt.Fprintf(&b, "  { path: '%s', name: '%s', component: %s },\n", path, name, component)
 		}
 	}
 	b.WriteString("  { path: '/:pathMatch(.*)*', name: 'NotFound', component: { template: '<div style=\"text-align:center;padding:4rem\"><h1>404</h1><p>Page not found</p></div>' } },\n")
@@ -50,6 +72,10 @@ func generateRouter(app *ir.Application) string {
 		b.WriteString("});\n")
 	}
 
+	if app.Sitemap != nil {
+		writeCanonicalLink(&b, app.Sitemap)
+	}
+
 	return b.String()
 }
 
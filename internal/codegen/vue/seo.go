@@ -0,0 +1,33 @@
+package vue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writeCanonicalLink appends a router hook that keeps the page's
+// <link rel="canonical"> on the site's origin. VITE_SITE_URL overrides the
+// declared production URL; a record's ?id stays part of the URL, as in the
+// sitemap.
+func writeCanonicalLink(b *strings.Builder, sm *ir.Sitemap) {
+	b.WriteString("\n")
+	if sm.URL != "" {
+		fmt.Fprintf(b, "const SITE_URL = (import.meta.env.VITE_SITE_URL || '%s').replace(/\\/$/, '');\n", sm.URL)
+	} else {
+		b.WriteString("const SITE_URL = (import.meta.env.VITE_SITE_URL || window.location.origin).replace(/\\/$/, '');\n")
+	}
+	b.WriteString(`
+router.afterEach((to) => {
+  const id = typeof to.query.id === 'string' ? to.query.id : '';
+  let link = document.querySelector<HTMLLinkElement>('link[rel="canonical"]');
+  if (!link) {
+    link = document.createElement('link');
+    link.rel = 'canonical';
+    document.head.appendChild(link);
+  }
+  link.href = SITE_URL + to.path + (id ? ` + "`?id=${encodeURIComponent(id)}`" + ` : '');
+});
+`)
+}
@@ -43,6 +43,10 @@ func writeInterface(b *strings.Builder, model *ir.DataModel, app *ir.Application
 		fmt.Fprintf(b, "  %s%s: %s;\n", f.Name, optional, fieldType)
 	}
 
+	if model.Versioned {
+		fmt.Fprintf(b, "  %s: number;\n", ir.VersionColumn)
+	}
+
 	for _, rel := range model.Relations {
 		switch rel.Kind {
 		case "belongs_to":
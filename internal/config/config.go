@@ -5,12 +5,102 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 )
 
 // Config holds all project configuration loaded from .human/config.json.
 type Config struct {
-	LLM     *LLMConfig      `json:"llm,omitempty"`
-	Plugins []*PluginConfig  `json:"plugins,omitempty"`
+	LLM      *LLMConfig                `json:"llm,omitempty"`
+	Plugins  []*PluginConfig           `json:"plugins,omitempty"`
+	Commands map[string]*CommandConfig `json:"commands,omitempty"`
+	Hooks    *HooksConfig              `json:"hooks,omitempty"`
+	Offline  bool                      `json:"offline,omitempty"`      // see Offline
+	Fsync    bool                      `json:"fsync,omitempty"`        // see Fsync
+	Workers  int                       `json:"emit_workers,omitempty"` // see EmitWorkers
+	Signing  *SigningConfig            `json:"signing,omitempty"`
+	Quality  *QualityConfig            `json:"quality,omitempty"`
+}
+
+// OfflineMode is set by the --offline flag.
+var OfflineMode bool
+
+// Offline reports whether commands should avoid the network: set by
+// --offline, HUMAN_OFFLINE=1, or "offline": true in projectDir's
+// .human/config.json. Offline builds self-host fonts instead of linking a
+// CDN, and skip the npm dependency audit.
+func Offline(projectDir string) bool {
+	if OfflineMode || os.Getenv("HUMAN_OFFLINE") == "1" {
+		return true
+	}
+	cfg, err := Load(projectDir)
+	return err == nil && cfg.Offline
+}
+
+// Fsync reports whether the build should flush generated files to disk
+// before finishing: set by HUMAN_FSYNC=1 or "fsync": true in projectDir's
+// .human/config.json. It costs build time, so it's off unless the output
+// must survive the machine crashing right after a build.
+func Fsync(projectDir string) bool {
+	if os.Getenv("HUMAN_FSYNC") == "1" {
+		return true
+	}
+	cfg, err := Load(projectDir)
+	return err == nil && cfg.Fsync
+}
+
+// EmitWorkers returns how many generated files the build writes at once:
+// set by HUMAN_EMIT_WORKERS or "emit_workers" in projectDir's
+// .human/config.json. 0, when neither sets a positive count, leaves it to
+// the build's default.
+func EmitWorkers(projectDir string) int {
+	if n, err := strconv.Atoi(os.Getenv("HUMAN_EMIT_WORKERS")); err == nil && n > 0 {
+		return n
+	}
+	if cfg, err := Load(projectDir); err == nil && cfg.Workers > 0 {
+		return cfg.Workers
+	}
+	return 0
+}
+
+// HooksConfig lists shell commands run at points in the build lifecycle,
+// in order, from the project directory. A failing hook aborts the build or
+// deploy. Each hook gets the build context as HUMAN_* environment variables
+// and as JSON on stdin.
+type HooksConfig struct {
+	PreBuild     []string `json:"pre_build,omitempty"`     // before parsing
+	PostGenerate []string `json:"post_generate,omitempty"` // after code generation and quality checks
+	PreDeploy    []string `json:"pre_deploy,omitempty"`    // after the build, before deploying
+}
+
+// SigningConfig signs the integrity manifest written by each build. Tool
+// is "cosign" or "minisign". Key is the private key human build signs with
+// and PublicKey the key human verify --integrity checks the signature
+// against; either may be left out on machines that only build or only
+// verify. cosign reads the key password from COSIGN_PASSWORD, minisign
+// prompts for it.
+type SigningConfig struct {
+	Tool      string `json:"tool"`
+	Key       string `json:"key,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+}
+
+// QualityConfig sets the minimum quality scores, out of 100, a build must
+// reach: MinScore for the total, and Minimums by category ("tests",
+// "security", "lint", "a11y", "bundle"). Builds warn when one is unmet;
+// human build --ci fails.
+type QualityConfig struct {
+	MinScore int            `json:"min_score,omitempty"`
+	Minimums map[string]int `json:"minimums,omitempty"`
+}
+
+// CommandConfig defines a project command, run as `human <name>` (e.g.
+// "deploy:staging"). Its steps run in order through the shell and it stops
+// at the first one that fails; a step starting with "human" runs this
+// binary. Arguments after the command name are appended to the last step.
+// Built-in commands take precedence over project commands of the same name.
+type CommandConfig struct {
+	Description string   `json:"description,omitempty"`
+	Steps       []string `json:"steps"`
 }
 
 // PluginConfig holds per-plugin settings. The Name matches a CodeGenerator's
@@ -47,10 +137,10 @@ func (c *Config) PluginSettings(name string) map[string]string {
 
 // LLMConfig holds configuration for the LLM connector.
 type LLMConfig struct {
-	Provider    string  `json:"provider"`            // "anthropic", "openai", "ollama"
-	Model       string  `json:"model,omitempty"`     // e.g. "claude-sonnet-4-20250514"
-	APIKey      string  `json:"-"`                   // NEVER serialized — env vars only
-	BaseURL     string  `json:"base_url,omitempty"`  // override for Ollama/proxies
+	Provider    string  `json:"provider"`           // "anthropic", "openai", "ollama"
+	Model       string  `json:"model,omitempty"`    // e.g. "claude-sonnet-4-20250514"
+	APIKey      string  `json:"-"`                  // NEVER serialized — env vars only
+	BaseURL     string  `json:"base_url,omitempty"` // override for Ollama/proxies
 	MaxTokens   int     `json:"max_tokens,omitempty"`
 	Temperature float64 `json:"temperature,omitempty"`
 }
@@ -196,16 +286,16 @@ func DefaultLLMConfig(provider string) *LLMConfig {
 // GlobalConfig holds user-wide configuration stored at ~/.human/config.json.
 // Unlike project config, this persists API keys locally.
 type GlobalConfig struct {
-	LLM *GlobalLLMConfig  `json:"llm,omitempty"`
+	LLM *GlobalLLMConfig   `json:"llm,omitempty"`
 	MCP []*MCPServerConfig `json:"mcp,omitempty"`
 }
 
 // MCPServerConfig stores configuration for an external MCP server.
 type MCPServerConfig struct {
-	Name    string            `json:"name"`              // display name (e.g. "figma")
-	Command string            `json:"command"`           // executable (e.g. "npx")
-	Args    []string          `json:"args,omitempty"`    // command arguments
-	Env     map[string]string `json:"env,omitempty"`     // env vars (e.g. FIGMA_ACCESS_TOKEN)
+	Name    string            `json:"name"`           // display name (e.g. "figma")
+	Command string            `json:"command"`        // executable (e.g. "npx")
+	Args    []string          `json:"args,omitempty"` // command arguments
+	Env     map[string]string `json:"env,omitempty"`  // env vars (e.g. FIGMA_ACCESS_TOKEN)
 }
 
 // GlobalLLMConfig stores LLM credentials globally.
@@ -286,6 +376,11 @@ type GlobalSettings struct {
 	LatestVersion   string `json:"latest_version,omitempty"`    // e.g. "0.5.0"
 	InstallMethod   string `json:"install_method,omitempty"`    // "source", "go_install", "binary"
 	SourceDir       string `json:"source_dir,omitempty"`        // git clone path for source updates
+
+	// Workspace trust. Kept here rather than in the project's config so a
+	// repository can't mark itself trusted.
+	TrustedWorkspaces []string `json:"trusted_workspaces,omitempty"` // absolute paths; subdirectories are trusted too
+	CleanEnv          bool     `json:"clean_env,omitempty"`          // run npm, docker, terraform, and hooks with a scrubbed environment
 }
 
 // globalSettingsFile is the path relative to the user's home directory.
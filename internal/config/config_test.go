@@ -546,3 +546,107 @@ func searchSubstr(s, substr string) bool {
 	}
 	return false
 }
+
+func TestLoadCommands(t *testing.T) {
+	dir := t.TempDir()
+	humanDir := filepath.Join(dir, ".human")
+	if err := os.MkdirAll(humanDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	data := `{
+  "commands": {
+    "deploy:staging": {
+      "description": "Build and deploy to staging",
+      "steps": ["human build app.human", "human deploy --env staging"]
+    }
+  }
+}`
+	if err := os.WriteFile(filepath.Join(humanDir, "config.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cmd := cfg.Commands["deploy:staging"]
+	if cmd == nil {
+		t.Fatal("expected the deploy:staging command")
+	}
+	if len(cmd.Steps) != 2 || cmd.Steps[1] != "human deploy --env staging" {
+		t.Errorf("steps = %v", cmd.Steps)
+	}
+}
+
+func TestOffline(t *testing.T) {
+	t.Setenv("HUMAN_OFFLINE", "")
+	dir := t.TempDir()
+	if Offline(dir) {
+		t.Fatal("expected online by default")
+	}
+
+	t.Setenv("HUMAN_OFFLINE", "1")
+	if !Offline(dir) {
+		t.Error("expected HUMAN_OFFLINE=1 to enable offline mode")
+	}
+	t.Setenv("HUMAN_OFFLINE", "")
+
+	OfflineMode = true
+	if !Offline(dir) {
+		t.Error("expected --offline to enable offline mode")
+	}
+	OfflineMode = false
+
+	if err := Save(dir, &Config{Offline: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !Offline(dir) {
+		t.Error(`expected "offline": true in config.json to enable offline mode`)
+	}
+}
+
+func TestFsync(t *testing.T) {
+	t.Setenv("HUMAN_FSYNC", "")
+	dir := t.TempDir()
+	if Fsync(dir) {
+		t.Fatal("expected no fsync by default")
+	}
+
+	t.Setenv("HUMAN_FSYNC", "1")
+	if !Fsync(dir) {
+		t.Error("expected HUMAN_FSYNC=1 to enable fsync")
+	}
+	t.Setenv("HUMAN_FSYNC", "")
+
+	if err := Save(dir, &Config{Fsync: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !Fsync(dir) {
+		t.Error(`expected "fsync": true in config.json to enable fsync`)
+	}
+}
+
+func TestEmitWorkers(t *testing.T) {
+	t.Setenv("HUMAN_EMIT_WORKERS", "")
+	dir := t.TempDir()
+	if n := EmitWorkers(dir); n != 0 {
+		t.Fatalf("expected no worker count by default, got %d", n)
+	}
+
+	if err := Save(dir, &Config{Workers: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if n := EmitWorkers(dir); n != 4 {
+		t.Errorf(`expected "emit_workers": 4 in config.json to give 4, got %d`, n)
+	}
+
+	t.Setenv("HUMAN_EMIT_WORKERS", "32")
+	if n := EmitWorkers(dir); n != 32 {
+		t.Errorf("expected HUMAN_EMIT_WORKERS to override config.json, got %d", n)
+	}
+	t.Setenv("HUMAN_EMIT_WORKERS", "lots")
+	if n := EmitWorkers(dir); n != 4 {
+		t.Errorf("expected an invalid HUMAN_EMIT_WORKERS to be ignored, got %d", n)
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Defaults are the choices human init makes without asking: the app type
+// and the stack a new project is scaffolded with. A user keeps theirs in
+// ~/.config/human/defaults.json, and a team commits theirs to the
+// repository as .human/defaults.json, whose fields win. Fields left empty
+// fall back to the prompt's own default.
+type Defaults struct {
+	AppType      string `json:"app_type,omitempty"`      // "auth", "crud", "saas", ...
+	Platform     string `json:"platform,omitempty"`      // "web" or "mobile"
+	Frontend     string `json:"frontend,omitempty"`      // "React", "Vue", "Angular", "Svelte", "None"
+	DesignSystem string `json:"design_system,omitempty"` // e.g. "Shadcn", "Tailwind"
+	Backend      string `json:"backend,omitempty"`       // "Node", "Python", "Go"
+	Database     string `json:"database,omitempty"`      // "PostgreSQL", "MySQL", "SQLite"
+	Deploy       string `json:"deploy,omitempty"`        // "Docker", "AWS", "GCP", ...
+}
+
+// defaultsFile is the team defaults' path relative to a repository
+// directory.
+const defaultsFile = ".human/defaults.json"
+
+// UserDefaultsPath returns where the user's defaults are kept:
+// $XDG_CONFIG_HOME/human/defaults.json, or ~/.config/human/defaults.json.
+func UserDefaultsPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "human", "defaults.json")
+}
+
+// LoadDefaults returns the defaults for a project created in dir: the
+// user's, overridden field by field by the team's .human/defaults.json in
+// dir or the nearest directory above it that has one. Missing files give
+// empty defaults; a file that doesn't parse is an error, with the other's
+// defaults still returned.
+func LoadDefaults(dir string) (*Defaults, error) {
+	d := &Defaults{}
+	var firstErr error
+	if path := UserDefaultsPath(); path != "" {
+		if err := mergeDefaults(d, path); err != nil {
+			firstErr = err
+		}
+	}
+	if path := findTeamDefaults(dir); path != "" {
+		if err := mergeDefaults(d, path); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return d, firstErr
+}
+
+// findTeamDefaults returns the path of the .human/defaults.json in dir or
+// the nearest directory above it, or "" if there's none.
+func findTeamDefaults(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(abs, defaultsFile)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return ""
+		}
+		abs = parent
+	}
+}
+
+// mergeDefaults reads the defaults in path over d's.
+func mergeDefaults(d *Defaults, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	var file Defaults
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&d.AppType, file.AppType},
+		{&d.Platform, file.Platform},
+		{&d.Frontend, file.Frontend},
+		{&d.DesignSystem, file.DesignSystem},
+		{&d.Backend, file.Backend},
+		{&d.Database, file.Database},
+		{&d.Deploy, file.Deploy},
+	} {
+		if f.src != "" {
+			*f.dst = f.src
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeDefaults(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "services", "billing")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := LoadDefaults(sub)
+	if err != nil || *d != (Defaults{}) {
+		t.Fatalf("LoadDefaults with no files = %+v, %v; want empty", d, err)
+	}
+
+	writeDefaults(t, filepath.Join(xdg, "human", "defaults.json"), `{"frontend": "Vue", "backend": "Go", "deploy": "AWS"}`)
+	writeDefaults(t, filepath.Join(repo, ".human", "defaults.json"), `{"backend": "Python", "database": "MySQL"}`)
+
+	d, err = LoadDefaults(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Defaults{Frontend: "Vue", Backend: "Python", Database: "MySQL", Deploy: "AWS"}
+	if *d != want {
+		t.Errorf("LoadDefaults = %+v, want the team's over the user's: %+v", *d, want)
+	}
+}
+
+func TestLoadDefaults_BadFile(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	repo := t.TempDir()
+	writeDefaults(t, filepath.Join(xdg, "human", "defaults.json"), `{"backend": `)
+	writeDefaults(t, filepath.Join(repo, ".human", "defaults.json"), `{"database": "SQLite"}`)
+
+	d, err := LoadDefaults(repo)
+	if err == nil {
+		t.Error("expected an error for the malformed user defaults")
+	}
+	if d.Database != "SQLite" {
+		t.Errorf("team defaults lost: %+v", d)
+	}
+}
@@ -27,12 +27,18 @@ type CompilerError struct {
 
 // Format returns a single-line representation of this error
 // suitable for terminal output (without ANSI — the caller wraps with cli colors).
+// The line, when known, follows the file.
 func (e *CompilerError) Format() string {
 	var b strings.Builder
 
-	if e.File != "" {
+	switch {
+	case e.File != "" && e.Line > 0:
+		fmt.Fprintf(&b, "%s:%d — ", e.File, e.Line)
+	case e.File != "":
 		b.WriteString(e.File)
 		b.WriteString(" — ")
+	case e.Line > 0:
+		fmt.Fprintf(&b, "line %d: ", e.Line)
 	}
 
 	b.WriteString(e.Message)
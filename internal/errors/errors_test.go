@@ -95,6 +95,17 @@ func TestCompilerErrorFormat(t *testing.T) {
 	}
 }
 
+func TestCompilerErrorFormatLine(t *testing.T) {
+	e := &CompilerError{Code: "E001", Message: "expected ':' after data User", File: "app.human", Line: 3}
+	if got, want := e.Format(), "app.human:3 — expected ':' after data User [E001]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	e.File = ""
+	if got, want := e.Format(), "line 3: expected ':' after data User [E001]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
 func TestCompilerErrorsFormat(t *testing.T) {
 	ce := New("app.human")
 	ce.AddErrorWithSuggestion("E101", `API "CreateTask" references model "Userr" which does not exist`, `Did you mean "User"?`)
@@ -0,0 +1,156 @@
+package fixtures
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Word lists for realistic fake values. Kept small and deterministic — the
+// goal is plausible data for UI work, not statistical realism.
+var (
+	firstNames = []string{"Ava", "Liam", "Maya", "Noah", "Zara", "Ethan", "Priya", "Lucas", "Sofia", "Omar", "Chloe", "Mateo"}
+	lastNames  = []string{"Patel", "Nguyen", "Garcia", "Smith", "Kim", "Okafor", "Rossi", "Müller", "Silva", "Cohen", "Tanaka", "Brown"}
+	titleWords = []string{"Quarterly", "Launch", "Review", "Design", "Update", "Roadmap", "Budget", "Onboarding", "Research", "Migration", "Sprint", "Report"}
+	loremWords = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do", "eiusmod", "tempor", "incididunt", "labore", "magna", "aliqua"}
+	domains    = []string{"example.com", "example.org", "mail.test"}
+	cities     = []string{"Lisbon", "Toronto", "Nairobi", "Osaka", "Austin", "Berlin", "Melbourne", "Bogotá"}
+	companies  = []string{"Acme", "Globex", "Initech", "Umbrella", "Stark", "Wayne", "Hooli", "Vandelay"}
+)
+
+// baseTime anchors generated dates so output is reproducible for a given seed.
+var baseTime = time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+// Faker produces deterministic fake values from IR field definitions.
+type Faker struct {
+	rng *rand.Rand
+}
+
+// NewFaker returns a Faker seeded with seed. The same seed always yields the
+// same sequence of values.
+func NewFaker(seed int64) *Faker {
+	return &Faker{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Record builds one fake record for model with the given id. index is the
+// record's position, used to cycle enum values so every variant appears and
+// to keep unique fields distinct. Foreign keys are left to Build, which knows
+// which records exist.
+func (f *Faker) Record(model *ir.DataModel, id string, index int) map[string]any {
+	rec := map[string]any{"id": id}
+	for _, field := range model.Fields {
+		v := f.Value(field, index)
+		if s, ok := v.(string); ok && field.Unique && isFreeText(field) {
+			v = fmt.Sprintf("%s %d", s, index+1)
+		}
+		rec[field.Name] = v
+	}
+	return rec
+}
+
+// Value returns a fake value for a single field.
+func (f *Faker) Value(field *ir.DataField, index int) any {
+	name := strings.ToLower(field.Name)
+	switch strings.ToLower(field.Type) {
+	case "enum":
+		if len(field.EnumValues) == 0 {
+			return "unknown"
+		}
+		return field.EnumValues[index%len(field.EnumValues)]
+	case "email":
+		return f.email(index)
+	case "url":
+		return fmt.Sprintf("https://%s/%s", f.pick(domains), f.pick(loremWords))
+	case "image", "file":
+		return fmt.Sprintf("https://picsum.photos/seed/%d/400/300", f.rng.Intn(1000))
+	case "number":
+		switch {
+		case strings.Contains(name, "age"):
+			return 18 + f.rng.Intn(60)
+		case strings.Contains(name, "quantity"), strings.Contains(name, "stock"), strings.Contains(name, "count"):
+			return f.rng.Intn(200)
+		case strings.Contains(name, "rating"), strings.Contains(name, "score"):
+			return 1 + f.rng.Intn(5)
+		default:
+			return f.rng.Intn(1000)
+		}
+	case "decimal":
+		if strings.Contains(name, "price") || strings.Contains(name, "amount") || strings.Contains(name, "total") {
+			return float64(f.rng.Intn(50000)) / 100
+		}
+		return float64(f.rng.Intn(10000)) / 100
+	case "boolean":
+		return f.rng.Intn(2) == 0
+	case "date":
+		return baseTime.AddDate(0, 0, f.rng.Intn(120)-30).Format("2006-01-02")
+	case "datetime":
+		return baseTime.Add(time.Duration(f.rng.Intn(120*24)-30*24) * time.Hour).Format(time.RFC3339)
+	case "json":
+		return map[string]any{}
+	}
+
+	// text and anything else: pick by field name.
+	switch {
+	case name == "password" || field.Encrypted:
+		return "••••••••"
+	case name == "name" || name == "fullname" || strings.HasSuffix(name, " name"):
+		return f.fullName()
+	case strings.Contains(name, "title"), strings.Contains(name, "subject"):
+		return f.pick(titleWords) + " " + f.pick(titleWords)
+	case strings.Contains(name, "city"), strings.Contains(name, "location"):
+		return f.pick(cities)
+	case strings.Contains(name, "company"), strings.Contains(name, "organization"):
+		return f.pick(companies)
+	case strings.Contains(name, "phone"):
+		return fmt.Sprintf("+1-555-%04d", f.rng.Intn(10000))
+	case strings.Contains(name, "description"), strings.Contains(name, "bio"),
+		strings.Contains(name, "content"), strings.Contains(name, "body"), strings.Contains(name, "notes"):
+		return f.sentence(12)
+	default:
+		return f.sentence(3)
+	}
+}
+
+func (f *Faker) pick(words []string) string {
+	return words[f.rng.Intn(len(words))]
+}
+
+func (f *Faker) fullName() string {
+	return f.pick(firstNames) + " " + f.pick(lastNames)
+}
+
+// email embeds index so addresses never collide within a model, which keeps
+// fixtures valid against unique constraints.
+func (f *Faker) email(index int) string {
+	return fmt.Sprintf("%s.%s%d@%s",
+		strings.ToLower(f.pick(firstNames)), strings.ToLower(f.pick(lastNames)), index+1, domains[index%len(domains)])
+}
+
+func (f *Faker) sentence(words int) string {
+	parts := make([]string, words)
+	for i := range parts {
+		parts[i] = f.pick(loremWords)
+	}
+	s := strings.Join(parts, " ")
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// isFreeText reports whether a field holds arbitrary text that can take a
+// numeric suffix without becoming invalid.
+func isFreeText(field *ir.DataField) bool {
+	switch strings.ToLower(field.Type) {
+	case "", "text":
+		return true
+	}
+	return false
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
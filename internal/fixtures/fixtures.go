@@ -0,0 +1,162 @@
+// Package fixtures builds the canonical sample data for an application:
+// a deterministic set of records per data model whose foreign keys always
+// point at records that exist. The same set backs the generated fixture
+// files, the database seed, and the mock server, so every layer exercises
+// identical data.
+package fixtures
+
+import (
+	"fmt"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// DefaultCount is the number of records generated per model when
+// Options.Count is not set.
+const DefaultCount = 25
+
+// DefaultSeed is the faker seed used by generated fixture files and, by
+// default, the mock server, so both serve the same records.
+const DefaultSeed = 1
+
+// Options controls fixture generation.
+type Options struct {
+	Seed   int64          // faker seed; the same seed always yields the same set
+	Count  int            // records per model (default DefaultCount)
+	Counts map[string]int // records of the named models, overriding Count
+}
+
+// Set is the generated sample data for an application.
+type Set struct {
+	// Models lists the app's data models ordered so that every model comes
+	// after the models it belongs to. Inserting in this order satisfies
+	// foreign key constraints.
+	Models []*ir.DataModel
+
+	// Records maps model name to its records, in id order.
+	Records map[string][]map[string]any
+
+	index map[string]int // model name → position in app.Data
+}
+
+// Build generates the fixture set for app. Records of model M get ids
+// ID(M's position in app.Data, 1..Count), or 1..Counts[M] when set; each belongs_to relation gets a
+// <target>Id foreign key chosen from the target's records. Relations to
+// models that are not declared are skipped.
+//
+// Records are always generated in app.Data order, so a given seed and count
+// produce the same values no matter which consumer builds the set.
+func Build(app *ir.Application, opts Options) *Set {
+	if opts.Count <= 0 {
+		opts.Count = DefaultCount
+	}
+
+	s := &Set{
+		Records: make(map[string][]map[string]any),
+		index:   make(map[string]int),
+	}
+	for i, model := range app.Data {
+		s.index[model.Name] = i
+	}
+	s.Models = dependencyOrder(app.Data, s.index)
+
+	f := NewFaker(opts.Seed)
+	for _, model := range app.Data {
+		count := opts.count(model.Name)
+		records := make([]map[string]any, 0, count)
+		for i := 0; i < count; i++ {
+			rec := f.Record(model, s.ID(model.Name, i+1), i)
+			for _, rel := range model.Relations {
+				if rel.Kind != "belongs_to" {
+					continue
+				}
+				if _, ok := s.index[rel.Target]; !ok {
+					continue
+				}
+				pool := opts.count(rel.Target)
+				if rel.Target == model.Name {
+					// Self-references only point backwards (or at the
+					// record itself) so row-by-row inserts succeed.
+					pool = i + 1
+				}
+				rec[ForeignKey(rel.Target)] = s.ID(rel.Target, f.rng.Intn(pool)+1)
+			}
+			records = append(records, rec)
+		}
+		s.Records[model.Name] = records
+	}
+
+	return s
+}
+
+// Seeded builds the set the generated seed scripts insert into the
+// development database: DefaultSeed's records, as many of each model as
+// the app's seed lines ask for.
+func Seeded(app *ir.Application) *Set {
+	return Build(app, Options{Seed: DefaultSeed, Counts: ir.SeedCounts(app)})
+}
+
+// count returns how many records of the named model to generate.
+func (o Options) count(model string) int {
+	if n := o.Counts[model]; n > 0 {
+		return n
+	}
+	return o.Count
+}
+
+// ID returns the id of the nth record (1-based) of the named model. Ids are
+// UUID-shaped so they load into uuid primary key columns unchanged, and are
+// stable across builds so tests can reference them directly.
+func (s *Set) ID(model string, n int) string {
+	return fmt.Sprintf("%08d-0000-4000-8000-%012d", s.index[model]+1, n)
+}
+
+// ForeignKey returns the record key holding a belongs_to reference to
+// target, matching the generated TypeScript types: "User" → "userId".
+func ForeignKey(target string) string {
+	return lowerFirst(target) + "Id"
+}
+
+// dependencyOrder sorts models so that belongs_to targets precede the
+// models referencing them. Declaration order is kept otherwise; cycles are
+// broken by falling back to declaration order for the remaining models.
+func dependencyOrder(models []*ir.DataModel, index map[string]int) []*ir.DataModel {
+	placed := make(map[string]bool, len(models))
+	ordered := make([]*ir.DataModel, 0, len(models))
+
+	for len(ordered) < len(models) {
+		progressed := false
+		for _, model := range models {
+			if placed[model.Name] || !depsPlaced(model, placed, index) {
+				continue
+			}
+			placed[model.Name] = true
+			ordered = append(ordered, model)
+			progressed = true
+		}
+		if progressed {
+			continue
+		}
+		for _, model := range models {
+			if !placed[model.Name] {
+				placed[model.Name] = true
+				ordered = append(ordered, model)
+				break
+			}
+		}
+	}
+
+	return ordered
+}
+
+func depsPlaced(model *ir.DataModel, placed map[string]bool, index map[string]int) bool {
+	for _, rel := range model.Relations {
+		if rel.Kind != "belongs_to" || rel.Target == model.Name {
+			continue
+		}
+		if _, ok := index[rel.Target]; ok && !placed[rel.Target] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func testApp() *ir.Application {
+	return &ir.Application{
+		Data: []*ir.DataModel{
+			{
+				Name: "Comment",
+				Fields: []*ir.DataField{
+					{Name: "body", Type: "text"},
+				},
+				Relations: []*ir.Relation{
+					{Kind: "belongs_to", Target: "Task"},
+					{Kind: "belongs_to", Target: "User"},
+					{Kind: "belongs_to", Target: "Ghost"},
+				},
+			},
+			{
+				Name: "User",
+				Fields: []*ir.DataField{
+					{Name: "name", Type: "text"},
+					{Name: "email", Type: "email", Unique: true},
+					{Name: "handle", Type: "text", Unique: true},
+				},
+			},
+			{
+				Name: "Task",
+				Fields: []*ir.DataField{
+					{Name: "title", Type: "text"},
+					{Name: "status", Type: "enum", EnumValues: []string{"pending", "done"}},
+				},
+				Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}},
+			},
+		},
+	}
+}
+
+func TestBuildDeterministic(t *testing.T) {
+	a := Build(testApp(), Options{Seed: 42})
+	b := Build(testApp(), Options{Seed: 42})
+	for name, records := range a.Records {
+		for i, rec := range records {
+			for k, v := range rec {
+				if b.Records[name][i][k] != v {
+					t.Errorf("%s[%d].%s differs across same-seed builds: %v vs %v", name, i, k, v, b.Records[name][i][k])
+				}
+			}
+		}
+	}
+	if len(a.Records["Task"]) != DefaultCount {
+		t.Errorf("got %d tasks, want DefaultCount (%d)", len(a.Records["Task"]), DefaultCount)
+	}
+}
+
+func TestBuildReferentialIntegrity(t *testing.T) {
+	set := Build(testApp(), Options{Seed: 1, Count: 8})
+
+	ids := make(map[string]bool)
+	for _, records := range set.Records {
+		for _, rec := range records {
+			ids[rec["id"].(string)] = true
+		}
+	}
+
+	for _, name := range []string{"Comment", "Task"} {
+		for _, rec := range set.Records[name] {
+			fk, ok := rec["userId"].(string)
+			if !ok || !ids[fk] {
+				t.Errorf("%s %v has dangling userId %v", name, rec["id"], rec["userId"])
+			}
+		}
+	}
+	for _, rec := range set.Records["Comment"] {
+		if !ids[rec["taskId"].(string)] {
+			t.Errorf("comment %v has dangling taskId %v", rec["id"], rec["taskId"])
+		}
+		if _, ok := rec["ghostId"]; ok {
+			t.Error("relation to an undeclared model should be skipped")
+		}
+	}
+}
+
+func TestBuildDependencyOrder(t *testing.T) {
+	set := Build(testApp(), Options{Seed: 1})
+	var got []string
+	for _, m := range set.Models {
+		got = append(got, m.Name)
+	}
+	want := []string{"User", "Task", "Comment"}
+	for i := range want {
+		if i >= len(got) || got[i] != want[i] {
+			t.Fatalf("Models = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBuildUniqueFields(t *testing.T) {
+	set := Build(testApp(), Options{Seed: 1, Count: 50})
+	for _, key := range []string{"email", "handle"} {
+		seen := make(map[any]bool)
+		for _, rec := range set.Records["User"] {
+			if seen[rec[key]] {
+				t.Errorf("duplicate %s %v", key, rec[key])
+			}
+			seen[rec[key]] = true
+		}
+	}
+}
+
+func TestIDs(t *testing.T) {
+	set := Build(testApp(), Options{Seed: 1, Count: 3})
+	if got := set.Records["User"][2]["id"]; got != "00000002-0000-4000-8000-000000000003" {
+		t.Errorf("third user id = %v", got)
+	}
+	if got := ForeignKey("TaskTag"); got != "taskTagId" {
+		t.Errorf("ForeignKey(TaskTag) = %q, want taskTagId", got)
+	}
+}
+
+func TestEnumValuesCycle(t *testing.T) {
+	set := Build(testApp(), Options{Seed: 1, Count: 4})
+	want := []string{"pending", "done", "pending", "done"}
+	for i, rec := range set.Records["Task"] {
+		if rec["status"] != want[i] {
+			t.Errorf("task %d status = %v, want %s", i, rec["status"], want[i])
+		}
+	}
+}
+
+func TestBuildCounts(t *testing.T) {
+	set := Build(testApp(), Options{Seed: 1, Count: 4, Counts: map[string]int{"User": 2, "Comment": 9}})
+	for name, want := range map[string]int{"User": 2, "Task": 4, "Comment": 9} {
+		if got := len(set.Records[name]); got != want {
+			t.Errorf("got %d %s records, want %d", got, name, want)
+		}
+	}
+	for _, rec := range set.Records["Task"] {
+		if id := rec["userId"]; id != set.ID("User", 1) && id != set.ID("User", 2) {
+			t.Errorf("task %v belongs to user %v, which isn't seeded", rec["id"], id)
+		}
+	}
+
+	app := testApp()
+	app.Seeds = []*ir.Seed{{Model: "Task", Count: 3}}
+	if got := len(Seeded(app).Records["Task"]); got != 3 {
+		t.Errorf("Seeded made %d tasks, want the seed line's 3", got)
+	}
+}
@@ -2,6 +2,9 @@ package ir
 
 import (
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/barun-bash/human/internal/parser"
@@ -57,6 +60,11 @@ func Build(prog *parser.Program) (*Application, error) {
 		}
 	}
 
+	// Model lifecycle hooks
+	for _, h := range prog.ModelHooks {
+		app.Hooks = append(app.Hooks, buildModelHook(h))
+	}
+
 	// Theme
 	if prog.Theme != nil {
 		app.Theme = buildTheme(prog.Theme)
@@ -92,13 +100,126 @@ func Build(prog *parser.Program) (*Application, error) {
 		app.Architecture = buildArchitecture(prog.Architecture)
 	}
 
-	// Monitoring (from top-level statements)
+	// Monitoring, experiments, notifications, and calendars (from top-level statements)
 	for _, s := range prog.Statements {
 		if rule := buildMonitoringRule(s); rule != nil {
 			app.Monitoring = append(app.Monitoring, rule)
+		} else if exp := buildExperiment(s); exp != nil {
+			app.Experiments = append(app.Experiments, exp)
+		} else if n := buildNotification(s.Text, app); n != nil {
+			app.Notifications = append(app.Notifications, n)
+		} else if cal := buildCalendar(s.Text, app); cal != nil {
+			app.Calendars = append(app.Calendars, cal)
+		} else if m := buildMetering(s.Text); m != nil {
+			app.Metering = m
+		}
+	}
+
+	// "when a task is assigned: notify the user in the app" workflows
+	for _, wf := range app.Workflows {
+		for _, step := range wf.Steps {
+			if n := buildNotification(step.Text+" when "+wf.Trigger, app); n != nil {
+				app.Notifications = append(app.Notifications, n)
+			}
+		}
+	}
+	if len(app.Notifications) > 0 {
+		addNotificationModel(app)
+	}
+
+	// "generate a PDF invoice from the Order" steps
+	for _, api := range app.APIs {
+		for _, step := range api.Steps {
+			addDocument(app, step.Text)
+		}
+	}
+	for _, wf := range app.Workflows {
+		for _, step := range wf.Steps {
+			addDocument(app, step.Text)
+		}
+	}
+
+	// "show a bar chart of expenses by category" displays
+	for _, page := range app.Pages {
+		for _, a := range page.Content {
+			addChart(app, a)
 		}
 	}
 
+	// "dragging a task reorders the list" interactions
+	for _, page := range app.Pages {
+		for _, a := range page.Content {
+			addReorder(app, a)
+		}
+	}
+
+	// "sum the amount of Orders grouped by month" report endpoints
+	for _, ep := range app.APIs {
+		addAggregate(app, ep)
+	}
+
+	// "import tasks from a csv or json file" endpoints
+	for _, ep := range app.APIs {
+		addImport(app, ep)
+	}
+
+	// "scrolling to bottom loads more posts" interactions page the list
+	// endpoint they load from
+	for _, page := range app.Pages {
+		for _, a := range page.Content {
+			addInfiniteScroll(app, a)
+		}
+	}
+
+	// "shows the Post with the id from the url" pages route by the record
+	// they show
+	for _, page := range app.Pages {
+		addDetailRoute(app, page)
+	}
+
+	// "users can update their profile" accounts blocks add endpoints and
+	// an account page, before the sitemap leaves the page out
+	if prog.Accounts != nil {
+		app.Accounts = buildAccounts(prog.Accounts)
+		addAccounts(app)
+	}
+
+	// "plans: Free, Pro ($19/month)" blocks add plan and subscription
+	// models and a pricing page, which the sitemap lists
+	if prog.Plans != nil {
+		app.Billing = buildBilling(prog.Plans, app)
+		addBilling(app)
+	}
+
+	// "meter API calls per user per day" adds a usage model and dashboard,
+	// after billing so calls can be reported to Stripe
+	if app.Metering != nil {
+		addMetering(app)
+	}
+
+	// "external clients authenticate with API keys" adds a key model and
+	// the page managing keys
+	if app.Auth != nil && app.Auth.APIKeys != nil {
+		addAPIKeys(app)
+	}
+
+	// "seed 50 Users" lines, after every model is added so they can name
+	// any of them
+	for _, d := range prog.Seeds {
+		app.Seeds = append(app.Seeds, buildSeed(d, app))
+	}
+
+	// Sitemap and robots.txt for web apps with public pages
+	app.Sitemap = buildSitemap(app)
+
+	// "backup daily at 3am" database rules
+	if app.Database != nil {
+		app.Database.Backup = buildBackup(app)
+	}
+
+	// "compliance profile is SOC2" defaults
+	applyCompliance(app)
+
 	return app, nil
 }
 
@@ -118,6 +239,18 @@ func buildConfig(b *parser.BuildDeclaration) *BuildConfig {
 			cfg.Database = text[len("database using "):]
 		case strings.HasPrefix(lower, "deploy to "):
 			cfg.Deploy = text[len("deploy to "):]
+		case strings.HasPrefix(lower, "api style is "):
+			cfg.APIStyle = text[len("api style is "):]
+		case strings.HasPrefix(lower, "compliance profile is "):
+			cfg.Compliance = text[len("compliance profile is "):]
+		case strings.HasPrefix(lower, "styling using "):
+			cfg.Styling = text[len("styling using "):]
+		case strings.HasPrefix(lower, "bundle budget is "):
+			cfg.BundleBudget = text[len("bundle budget is "):]
+		case strings.HasPrefix(lower, "store all times in "):
+			cfg.Times = text[len("store all times in "):]
+		case strings.HasPrefix(lower, "validate requests against "):
+			cfg.Validation = text[len("validate requests against "):]
 		}
 	}
 	return cfg
@@ -126,7 +259,7 @@ func buildConfig(b *parser.BuildDeclaration) *BuildConfig {
 // ── Data Models ──
 
 func buildDataModel(d *parser.DataDeclaration) *DataModel {
-	model := &DataModel{Name: d.Name}
+	model := &DataModel{Name: d.Name, Versioned: d.Versioned}
 
 	for _, f := range d.Fields {
 		df := &DataField{
@@ -153,6 +286,8 @@ func buildDataModel(d *parser.DataDeclaration) *DataModel {
 				df.Unique = true
 			case "encrypted":
 				df.Encrypted = true
+			case "personal":
+				df.Personal = true
 			}
 		}
 
@@ -232,11 +367,30 @@ func buildEndpoint(a *parser.APIDeclaration) *Endpoint {
 			}
 		}
 		ep.Steps = append(ep.Steps, classifyAction(s))
+		if n, ok := pageSize(s.Text); ok {
+			ep.PageSize = n
+		}
 	}
 
 	return ep
 }
 
+// pageSize returns the records per page a "paginate with 20 per page" or
+// "paginate results, 50 per page" step asks for, DefaultPageSize when it
+// gives no number, and false for other steps.
+func pageSize(text string) (int, bool) {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) == 0 || words[0] != "paginate" {
+		return 0, false
+	}
+	for _, w := range words[1:] {
+		if n, err := strconv.Atoi(strings.Trim(w, ",")); err == nil && n > 0 {
+			return n, true
+		}
+	}
+	return DefaultPageSize, true
+}
+
 // parseValidation extracts a structured validation rule from a "check" statement.
 // Returns nil if the text cannot be parsed into a known pattern.
 func parseValidation(text string) *ValidationRule {
@@ -369,6 +523,20 @@ func buildPipeline(w *parser.WorkflowDeclaration) *Pipeline {
 	return p
 }
 
+// buildModelHook builds a hook from "before a Task is created, ...". An
+// event that isn't a model being created, updated, or deleted is kept
+// whole as the model, so the analyzer can report it.
+func buildModelHook(h *parser.ModelHookDeclaration) *ModelHook {
+	hook := &ModelHook{Model: h.Event, Timing: h.Timing}
+	if model, event, ok := parseHookEvent(h.Event); ok {
+		hook.Model, hook.Event = model, event
+	}
+	for _, s := range h.Statements {
+		hook.Steps = append(hook.Steps, classifyAction(s))
+	}
+	return hook
+}
+
 // ── Theme ──
 
 func buildTheme(t *parser.ThemeDeclaration) *Theme {
@@ -392,6 +560,14 @@ func buildTheme(t *parser.ThemeDeclaration) *Theme {
 		case strings.HasPrefix(lower, "border radius is "):
 			theme.BorderRadius = strings.TrimSpace(strings.ToLower(text[len("border radius is "):]))
 
+		// "locale is en-US"
+		case strings.HasPrefix(lower, "locale is "):
+			theme.Locale = strings.TrimSpace(text[len("locale is "):])
+
+		// "currency is EUR"
+		case strings.HasPrefix(lower, "currency is "):
+			theme.Currency = strings.TrimSpace(text[len("currency is "):])
+
 		// "spacing is comfortable"
 		case strings.HasPrefix(lower, "spacing is "):
 			theme.Spacing = strings.TrimSpace(strings.ToLower(text[len("spacing is "):]))
@@ -439,22 +615,22 @@ func normalizeDesignSystem(name string) string {
 	lower = strings.TrimSuffix(lower, " ui")
 
 	aliases := map[string]string{
-		"material":    "material",
-		"mui":         "material",
-		"material ui": "material",
-		"shadcn":      "shadcn",
-		"shadcn/ui":   "shadcn",
-		"ant":         "ant",
-		"ant design":  "ant",
-		"antd":        "ant",
-		"chakra":      "chakra",
-		"chakra ui":   "chakra",
-		"bootstrap":   "bootstrap",
-		"tailwind":    "tailwind",
-		"tailwindcss": "tailwind",
-		"tailwind css":"tailwind",
-		"untitled":    "untitled",
-		"untitled ui": "untitled",
+		"material":     "material",
+		"mui":          "material",
+		"material ui":  "material",
+		"shadcn":       "shadcn",
+		"shadcn/ui":    "shadcn",
+		"ant":          "ant",
+		"ant design":   "ant",
+		"antd":         "ant",
+		"chakra":       "chakra",
+		"chakra ui":    "chakra",
+		"bootstrap":    "bootstrap",
+		"tailwind":     "tailwind",
+		"tailwindcss":  "tailwind",
+		"tailwind css": "tailwind",
+		"untitled":     "untitled",
+		"untitled ui":  "untitled",
 	}
 
 	if id, ok := aliases[lower]; ok {
@@ -487,6 +663,8 @@ func buildAuth(a *parser.AuthenticationDeclaration) *Auth {
 		if strings.HasPrefix(lower, "method ") {
 			method := parseAuthMethod(s.Text[len("method "):])
 			auth.Methods = append(auth.Methods, method)
+		} else if apiKeysPattern.MatchString(lower) {
+			auth.APIKeys = &APIKeys{}
 		} else {
 			auth.Rules = append(auth.Rules, classifyAction(s))
 		}
@@ -495,6 +673,10 @@ func buildAuth(a *parser.AuthenticationDeclaration) *Auth {
 	return auth
 }
 
+// apiKeysPattern matches "external clients authenticate with API keys" and
+// "scripts can authenticate with api keys".
+var apiKeysPattern = regexp.MustCompile(`\bauthenticate with (?:an )?api keys?\b`)
+
 // parseAuthMethod parses "JWT tokens that expire in 7 days" or
 // "Google OAuth with redirect to /auth/google/callback".
 func parseAuthMethod(text string) *AuthMethod {
@@ -536,9 +718,17 @@ func buildDatabase(d *parser.DatabaseDeclaration) *DatabaseConfig {
 		lower := strings.ToLower(s.Text)
 
 		switch {
+		// "use a read replica for heavy queries"
+		case strings.HasPrefix(lower, "use ") && strings.Contains(lower, "replica"):
+			db.ReadReplica = true
+
 		case strings.HasPrefix(lower, "use "):
 			db.Engine = s.Text[len("use "):]
 
+		// "pool up to 20 connections"
+		case poolSizeRe.MatchString(lower):
+			db.PoolSize, _ = strconv.Atoi(poolSizeRe.FindStringSubmatch(lower)[1])
+
 		case strings.HasPrefix(lower, "index "):
 			if idx := parseIndex(s.Text[len("index "):]); idx != nil {
 				db.Indexes = append(db.Indexes, idx)
@@ -552,6 +742,9 @@ func buildDatabase(d *parser.DatabaseDeclaration) *DatabaseConfig {
 	return db
 }
 
+// poolSizeRe matches "pool up to 20 connections" and "connection pool of 20".
+var poolSizeRe = regexp.MustCompile(`\bpool (?:up to |of |at most )?(\d+)\b`)
+
 // parseIndex parses "User by email" or "Task by user and status".
 func parseIndex(text string) *Index {
 	parts := strings.SplitN(text, " by ", 2)
@@ -723,9 +916,10 @@ func classifyAction(s *parser.Statement) *Action {
 	// Display
 	case "show", "display", "render":
 		action.Type = "display"
+		action.Template = s.Quoted
 
 	// Interaction
-	case "clicking", "dragging", "scrolling", "hovering", "typing":
+	case "clicking", "dragging", "scrolling", "hovering", "typing", "pressing":
 		action.Type = "interact"
 
 	// Input elements
@@ -970,8 +1164,1267 @@ func buildMonitoringRule(s *parser.Statement) *MonitoringRule {
 	return nil
 }
 
+// ── Experiments ──
+
+// buildExperiment parses an experiment declaration:
+//
+//	experiment "pricing-page" splits visitors 50/50 between Classic and NewPricing pages
+//
+// The lexer drops the quotes and the slash, so the statement text arrives as
+// "experiment pricing-page splits visitors 50 50 between Classic and NewPricing pages".
+// Without percentages, traffic is split evenly.
+func buildExperiment(s *parser.Statement) *Experiment {
+	words := strings.FieldsFunc(s.Text, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '/' || r == '"'
+	})
+	if len(words) < 2 || !strings.EqualFold(words[0], "experiment") {
+		return nil
+	}
+
+	exp := &Experiment{Name: words[1], Audience: "visitors"}
+	var weights []int
+	i := 2
+	for ; i < len(words) && !strings.EqualFold(words[i], "between"); i++ {
+		w := strings.ToLower(words[i])
+		if n, err := parseInt(strings.TrimSuffix(w, "%")); err == nil {
+			weights = append(weights, n)
+		} else if i > 2 && strings.EqualFold(words[i-1], "splits") && w != "traffic" {
+			exp.Audience = w
+		}
+	}
+	for i++; i < len(words); i++ {
+		switch strings.ToLower(words[i]) {
+		case "and", "the", "page", "pages":
+			continue
+		}
+		exp.Variants = append(exp.Variants, &ExperimentVariant{Page: words[i]})
+	}
+
+	if len(weights) == 0 && len(exp.Variants) > 0 {
+		share := 100 / len(exp.Variants)
+		for _, v := range exp.Variants {
+			v.Weight = share
+		}
+		exp.Variants[0].Weight += 100 - share*len(exp.Variants)
+	}
+	for j, w := range weights {
+		if j < len(exp.Variants) {
+			exp.Variants[j].Weight = w
+		}
+	}
+	return exp
+}
+
+// ── Notifications ──
+
+// buildNotification parses an in-app notification rule:
+//
+//	notify the user in the app when their task is assigned
+//
+// Returns nil for statements that are not in-app notifications.
+func buildNotification(text string, app *Application) *Notification {
+	lower := strings.ToLower(text)
+	if !strings.HasPrefix(lower, "notify ") {
+		return nil
+	}
+	when := strings.Index(lower, " when ")
+	if when < 0 || !strings.Contains(lower[:when], " in the app") && !strings.Contains(lower[:when], " in-app") {
+		return nil
+	}
+
+	var model []string
+	event := ""
+	for _, w := range strings.Fields(text[when+len(" when "):]) {
+		lw := strings.ToLower(w)
+		if event == "" && len(model) > 0 && (lw == "is" || lw == "are" || lw == "was" || lw == "gets" || lw == "get") {
+			event = "?"
+			continue
+		}
+		if event == "?" {
+			event = strings.Trim(lw, ".,")
+			break
+		}
+		switch lw {
+		case "their", "a", "an", "the", "his", "her", "its", "any", "my", "your":
+			continue
+		}
+		model = append(model, w)
+	}
+	if len(model) == 0 || event == "" || event == "?" {
+		return nil
+	}
+
+	var name string
+	for _, w := range model {
+		name += strings.ToUpper(w[:1]) + w[1:]
+	}
+	noun := strings.ToLower(strings.Join(model, " "))
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, name) {
+			name = m.Name
+			break
+		}
+		if strings.EqualFold(m.Name+"s", name) {
+			name, noun = m.Name, strings.TrimSuffix(noun, "s")
+			break
+		}
+	}
+	return &Notification{
+		Model:   name,
+		Event:   event,
+		Message: fmt.Sprintf("Your %s was %s", noun, event),
+	}
+}
+
+// addNotificationModel declares the Notification data model that stores
+// in-app notifications, unless the app already declares one. Each
+// notification belongs to a User; "seen" is null until it has been read.
+func addNotificationModel(app *Application) {
+	var user *DataModel
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, "Notification") {
+			return
+		}
+		if strings.EqualFold(m.Name, "User") {
+			user = m
+		}
+	}
+
+	model := &DataModel{
+		Name: "Notification",
+		Fields: []*DataField{
+			{Name: "message", Type: "text", Required: true},
+			{Name: "seen", Type: "datetime"},
+		},
+	}
+	if user != nil {
+		model.Relations = []*Relation{{Kind: "belongs_to", Target: user.Name}}
+		user.Relations = append(user.Relations, &Relation{Kind: "has_many", Target: "Notification"})
+	}
+	app.Data = append(app.Data, model)
+}
+
+// ── Calendars ──
+
+// buildCalendar parses a calendar feed declaration:
+//
+//	users can add an Event to their calendar
+//	users can add an Event to their calendar in America/New_York
+//
+// The event's title, start, end, location, and description are inferred
+// from the model's fields. Returns nil for other statements.
+func buildCalendar(text string, app *Application) *CalendarFeed {
+	lower := strings.ToLower(text)
+	add := strings.Index(lower, " can add ")
+	to := strings.Index(lower, " calendar")
+	if add < 0 || to < add {
+		return nil
+	}
+	noun := lower[add+len(" can add ") : to]
+	cut := -1
+	for _, suffix := range []string{" to their", " to your", " to a", " to the", " to"} {
+		if strings.HasSuffix(noun, suffix) {
+			cut = len(noun) - len(suffix)
+			break
+		}
+	}
+	if cut < 0 {
+		return nil
+	}
+
+	var words []string
+	for _, w := range strings.Fields(noun[:cut]) {
+		switch w {
+		case "a", "an", "the", "any", "their":
+			continue
+		}
+		words = append(words, w)
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	name := ""
+	for _, w := range words {
+		name += strings.ToUpper(w[:1]) + w[1:]
+	}
+	var model *DataModel
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, name) || strings.EqualFold(m.Name+"s", name) {
+			model = m
+			name = m.Name
+			break
+		}
+	}
+
+	cal := &CalendarFeed{Model: name, Slug: calendarSlug(name), Timezone: "UTC"}
+	if tz := extractAfter(text[to:], " in "); tz != "" {
+		// IANA zones have no spaces; unquoted, the lexer splits them at "/".
+		cal.Timezone = strings.Join(strings.Fields(strings.Trim(tz, ".\"' ")), "/")
+	}
+	if model != nil {
+		inferCalendarFields(cal, model)
+	}
+	return cal
+}
+
+// inferCalendarFields picks the model fields a calendar event is built
+// from, by name: a "start" or "date" field for the start, an "end" field
+// for the end, and so on.
+func inferCalendarFields(cal *CalendarFeed, model *DataModel) {
+	isTime := func(f *DataField) bool { return f.Type == "date" || f.Type == "datetime" }
+	for _, f := range model.Fields {
+		name := strings.ToLower(f.Name)
+		switch {
+		case isTime(f) && (strings.Contains(name, "end") || strings.Contains(name, "finish")):
+			if cal.End == "" {
+				cal.End = f.Name
+			}
+		case isTime(f) && (strings.Contains(name, "start") || strings.Contains(name, "begin")):
+			cal.Start, cal.AllDay = f.Name, f.Type == "date"
+		case isTime(f) && cal.Start == "" && name != "created" && name != "updated" && !strings.Contains(name, "deadline"):
+			cal.Start, cal.AllDay = f.Name, f.Type == "date"
+		case cal.Title == "" && (name == "name" || name == "title" || name == "subject"):
+			cal.Title = f.Name
+		case cal.Location == "" && (strings.Contains(name, "location") || strings.Contains(name, "venue") || strings.Contains(name, "address") || name == "place"):
+			cal.Location = f.Name
+		case cal.Description == "" && (name == "description" || name == "details" || name == "summary" || name == "notes"):
+			cal.Description = f.Name
+		}
+	}
+	if cal.Title == "" {
+		for _, f := range model.Fields {
+			if f.Type == "text" {
+				cal.Title = f.Name
+				break
+			}
+		}
+	}
+}
+
+// calendarSlug returns the URL segment for a model's calendar:
+// "Event" → "events", "Class Session" → "class-sessions".
+func calendarSlug(model string) string {
+	var b strings.Builder
+	for i, r := range model {
+		if r >= 'A' && r <= 'Z' && i > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+	}
+	slug := strings.ToLower(b.String())
+	switch {
+	case strings.HasSuffix(slug, "y") && !strings.HasSuffix(slug, "ay") && !strings.HasSuffix(slug, "ey") && !strings.HasSuffix(slug, "oy"):
+		return strings.TrimSuffix(slug, "y") + "ies"
+	case strings.HasSuffix(slug, "s") || strings.HasSuffix(slug, "x") || strings.HasSuffix(slug, "ch") || strings.HasSuffix(slug, "sh"):
+		return slug + "es"
+	}
+	return slug + "s"
+}
+
+// ── Sitemap ──
+
+// buildSitemap lists a web app's public pages — every page without
+// authentication, else the home, landing, and sign-in pages — and, for a
+// public <Model>Detail page whose model holds no user data, a URL per
+// record. Sign-in pages stay out of the sitemap. It returns nil when no
+// page is public.
+func buildSitemap(app *Application) *Sitemap {
+	if app.Platform != "web" {
+		return nil
+	}
+	sm := &Sitemap{URL: siteURL(app)}
+	for _, page := range app.Pages {
+		lower := strings.ToLower(page.Name)
+		path := "/" + pageSlug(page.Name)
+		if lower == "home" {
+			path = "/"
+		}
+		if r := DetailRouteFor(app, page.Name); r != nil {
+			path = "/" + r.Slug + "/"
+		}
+		signIn := lower == "login" || lower == "signup" || lower == "sign-up" || lower == "register"
+		if app.Auth != nil && !signIn && lower != "home" && lower != "landing" {
+			sm.Private = append(sm.Private, path)
+			continue
+		}
+		if signIn {
+			continue
+		}
+		if r := DetailRouteFor(app, page.Name); r != nil {
+			// Only the records' own URLs exist under the route
+			if model := modelNamed(app, r.Model); model != nil && r.Param == "id" && !holdsUserData(model) {
+				sm.Records = append(sm.Records, &SitemapRecord{Model: model.Name, Path: "/" + r.Slug, Routed: true})
+			}
+			continue
+		}
+		sm.Pages = append(sm.Pages, path)
+		if model := detailModel(app, page.Name); model != nil && !holdsUserData(model) {
+			sm.Records = append(sm.Records, &SitemapRecord{Model: model.Name, Path: path})
+		}
+	}
+	if len(sm.Pages) == 0 {
+		return nil
+	}
+	return sm
+}
+
+// siteURL returns the site's origin from the production environment's
+// "url is shophub.example.com" line, else the first environment with a
+// URL.
+func siteURL(app *Application) string {
+	if env := PublicEnvironment(app); env != nil {
+		return env.URL()
+	}
+	return ""
+}
+
+// pageSlug turns a page name into its route segment: "ProductDetail" →
+// "product-detail".
+func pageSlug(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' && i > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(b.String())
+}
+
+// detailModel returns the model a "<Model>Detail" page shows, if any.
+func detailModel(app *Application, page string) *DataModel {
+	for _, suffix := range []string{"Details", "Detail"} {
+		name, ok := strings.CutSuffix(page, suffix)
+		if !ok || name == "" {
+			continue
+		}
+		for _, m := range app.Data {
+			if strings.EqualFold(m.Name, name) {
+				return m
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// holdsUserData reports whether a model's records are private: the User
+// model, models belonging to a user, and models with secret fields.
+func holdsUserData(model *DataModel) bool {
+	if strings.EqualFold(model.Name, "User") {
+		return true
+	}
+	for _, r := range model.Relations {
+		if r.Kind == "belongs_to" && strings.EqualFold(r.Target, "User") {
+			return true
+		}
+	}
+	for _, f := range model.Fields {
+		if f.Encrypted || strings.Contains(strings.ToLower(f.Name), "password") {
+			return true
+		}
+	}
+	return false
+}
+
+// ── Detail Routes ──
+
+// addDetailRoute routes a page showing the record its URL names ("shows
+// the Post with the id from the url", or "with the slug") at
+// /<models>/:<field>. The page loads the record through Get<Model>, which
+// is added — fetching the record by the URL's value — when the API has no
+// such endpoint.
+func addDetailRoute(app *Application, page *Page) {
+	for _, a := range page.Content {
+		if !IsDetailRoute(a) || DetailRouteFor(app, page.Name) != nil {
+			continue
+		}
+		lower := strings.ToLower(a.Text)
+		var m *DataModel
+		for _, word := range strings.Fields(lower[:strings.Index(lower, " from the url")]) {
+			if m = modelNamed(app, word); m != nil {
+				break
+			}
+		}
+		if m == nil {
+			continue
+		}
+		r := &DetailRoute{Page: page.Name, Model: m.Name, Param: "id", Slug: calendarSlug(m.Name)}
+		if field := extractBetween(lower, " with the ", " from the url"); field != "" {
+			if f := m.FieldFor(field); f != nil {
+				r.Param = f.Name
+			}
+		}
+		arg := r.Param
+		if arg == "id" {
+			arg = strings.ReplaceAll(pageSlug(strings.ReplaceAll(m.Name, " ", "")), "-", "_") + "_id"
+		}
+
+		var ep *Endpoint
+		for _, e := range app.APIs {
+			if strings.EqualFold(e.Name, "Get"+m.Name) {
+				ep = e
+			}
+		}
+		if ep == nil {
+			ep = &Endpoint{
+				Name:   "Get" + m.Name,
+				Params: []*Param{{Name: arg}},
+				Steps: []*Action{
+					{Type: "query", Text: fmt.Sprintf("fetch the %s by %s", m.Name, arg)},
+					{Type: "condition", Text: fmt.Sprintf("if %s does not exist, respond with %s not found", strings.ToLower(m.Name), m.Name)},
+					{Type: "respond", Text: "respond with the " + strings.ToLower(m.Name)},
+				},
+			}
+			app.APIs = append(app.APIs, ep)
+		}
+		r.Endpoint, r.Arg = ep.Name, arg
+		if !endpointAccepts(ep, arg) {
+			switch {
+			case endpointAccepts(ep, r.Param):
+				r.Arg = r.Param
+			case len(ep.Params) > 0:
+				r.Arg = ep.Params[0].Name
+			default:
+				ep.Params = append(ep.Params, &Param{Name: arg})
+			}
+		}
+		app.DetailRoutes = append(app.DetailRoutes, r)
+	}
+}
+
+// endpointAccepts reports whether an endpoint takes a parameter.
+func endpointAccepts(ep *Endpoint, name string) bool {
+	for _, p := range ep.Params {
+		if strings.EqualFold(p.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ── Documents ──
+
+// addDocument records the PDF a "generate a PDF invoice from the Order"
+// step renders, once per kind and model. The header, detail rows, line
+// items, and total are inferred from the model's fields.
+func addDocument(app *Application, text string) {
+	kind, name, ok := ParseDocumentStep(text)
+	if !ok || DocumentFor(app, text) != nil {
+		return
+	}
+	var model *DataModel
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, name) || strings.EqualFold(m.Name+"s", name) {
+			model = m
+			name = m.Name
+			break
+		}
+	}
+	kindName := ""
+	for _, w := range strings.Fields(kind) {
+		kindName += strings.ToUpper(w[:1]) + w[1:]
+	}
+	doc := &Document{Kind: kind, Model: name, Slug: calendarSlug(name + kindName)}
+	if model != nil {
+		inferDocumentFields(doc, model, app)
+	}
+	app.Documents = append(app.Documents, doc)
+}
+
+// addChart collects the chart a show action draws, once per slug. Charts
+// that can't be drawn are left to the analyzer to report.
+func addChart(app *Application, a *Action) {
+	if !IsChart(a) {
+		return
+	}
+	c, err := ParseChart(app, a.Text)
+	if err != nil {
+		return
+	}
+	for _, existing := range app.Charts {
+		if existing.Slug == c.Slug {
+			return
+		}
+	}
+	app.Charts = append(app.Charts, c)
+}
+
+// addReorder collects the list a drag interaction reorders, once per
+// model. Its order persists in the model's position field, which is added
+// when the model has none. Drags that name no model are left to the
+// analyzer to report.
+func addReorder(app *Application, a *Action) {
+	if !IsReorder(a) {
+		return
+	}
+	m := ReorderModel(app, a)
+	if m == nil || ReorderFor(app, m.Name) != nil {
+		return
+	}
+	f := positionField(m)
+	if f == nil {
+		f = &DataField{Name: "position", Type: "number"}
+		m.Fields = append(m.Fields, f)
+	}
+	app.Reorders = append(app.Reorders, &Reorder{Model: m.Name, Field: f.Name, Slug: calendarSlug(m.Name)})
+}
+
+// addInfiniteScroll pages the endpoint listing the records a "scrolling to
+// bottom loads more posts" interaction loads, when its API doesn't already.
+func addInfiniteScroll(app *Application, a *Action) {
+	if !IsInfiniteScroll(a) {
+		return
+	}
+	m := ScrollModel(app, a)
+	if m == nil {
+		return
+	}
+	if ep := ListEndpoint(app, m.Name); ep != nil && ep.PageSize == 0 {
+		ep.PageSize = DefaultPageSize
+	}
+}
+
+// addImport makes an endpoint an import when one of its steps imports a
+// model's records from a file. A file with an invalid record is imported
+// all or nothing unless a step says to skip invalid rows.
+func addImport(app *Application, ep *Endpoint) {
+	for _, step := range ep.Steps {
+		if ep.Import == nil && IsImport(step.Text) {
+			if m := ImportModel(app, step.Text); m != nil {
+				ep.Import = &Import{Model: m.Name, Formats: importFormats(step.Text), Mode: ImportAllOrNothing}
+			}
+		}
+	}
+	if ep.Import == nil {
+		return
+	}
+	for _, step := range ep.Steps {
+		if mode := importMode(step.Text); mode != "" {
+			ep.Import.Mode = mode
+		}
+	}
+}
+
+// buildAccounts reads what users can do with their own account.
+func buildAccounts(d *parser.AccountsDeclaration) *Accounts {
+	acc := &Accounts{}
+	for _, s := range d.Statements {
+		lower := strings.ToLower(s.Text)
+		known := false
+		if strings.Contains(lower, "profile") {
+			acc.Profile, known = true, true
+		}
+		if strings.Contains(lower, "password") {
+			acc.Password, known = true, true
+		}
+		if (strings.Contains(lower, "delete") || strings.Contains(lower, "close")) && strings.Contains(lower, "account") {
+			acc.Delete, known = true, true
+		}
+		if strings.Contains(lower, "invite") || strings.Contains(lower, "invitation") {
+			if acc.Invite == nil {
+				acc.Invite = &Invitations{Expires: DefaultInviteExpiry}
+			}
+			if m := inviteExpiryPattern.FindStringSubmatch(lower); m != nil {
+				n, _ := strconv.Atoi(m[1])
+				if strings.HasPrefix(m[2], "week") {
+					n *= 7
+				}
+				acc.Invite.Expires = max(n, 1)
+			}
+			if team := inviteTeam(s.Text); team != "" {
+				acc.Invite.Team = team
+			}
+			known = true
+		}
+		if !known {
+			acc.Unknown = append(acc.Unknown, strings.TrimSpace(s.Text))
+		}
+	}
+	return acc
+}
+
+// inviteExpiryPattern matches how long invitations last, as in
+// "invitations expire in 3 days".
+var inviteExpiryPattern = regexp.MustCompile(`expir\w* (?:in|after) (\d+) (days?|weeks?)`)
+
+// inviteTeam returns the team teammates are invited to: the word after
+// "to their", "to the", or "to", as in "invite teammates by email to their
+// Organization". Returns "" when the statement names none.
+func inviteTeam(text string) string {
+	words := strings.Fields(text)
+	for i := len(words) - 2; i >= 0; i-- {
+		if !strings.EqualFold(words[i], "to") {
+			continue
+		}
+		rest := words[i+1:]
+		if len(rest) > 1 && (strings.EqualFold(rest[0], "their") || strings.EqualFold(rest[0], "the") || strings.EqualFold(rest[0], "our")) {
+			rest = rest[1:]
+		}
+		return strings.Trim(rest[0], ".,:;'\"")
+	}
+	return ""
+}
+
+// addAccounts adds the endpoints doing what an accounts block lets users do
+// to the users' model — User, or else the first model with a password — and
+// the page they do it on: Settings, or AccountSettings when the app has a
+// Settings page of its own. Changing a password and deleting an account
+// both check the user's password, so they need a model that has one. An
+// endpoint the app declares itself is kept.
+func addAccounts(app *Application) {
+	acc := app.Accounts
+	var m *DataModel
+	if m = modelNamed(app, "user"); m == nil {
+		for _, dm := range app.Data {
+			if dm.FieldNamed("password") != nil {
+				m = dm
+				break
+			}
+		}
+	}
+	if m == nil {
+		return
+	}
+	acc.Model = m.Name
+	if acc.Invite != nil {
+		addInvitations(app, m)
+	}
+
+	add := func(ep *Endpoint) {
+		for _, existing := range app.APIs {
+			if strings.EqualFold(existing.Name, ep.Name) {
+				return
+			}
+		}
+		app.APIs = append(app.APIs, ep)
+	}
+	if acc.Profile {
+		add(&Endpoint{Name: "GetProfile", Auth: true, Account: AccountProfile})
+		update := &Endpoint{Name: "UpdateProfile", Auth: true, Account: AccountUpdateProfile}
+		for _, f := range ProfileFields(app) {
+			update.Params = append(update.Params, &Param{Name: f.Name})
+			switch {
+			case f.Type == "email":
+				update.Validation = append(update.Validation, &ValidationRule{Field: f.Name, Rule: "valid_email"})
+			case f.Required && (f.Type == "text" || f.Type == "url"):
+				update.Validation = append(update.Validation, &ValidationRule{Field: f.Name, Rule: "not_empty"})
+			}
+		}
+		add(update)
+	}
+	hasPassword := m.FieldNamed("password") != nil
+	if acc.Password && hasPassword {
+		add(&Endpoint{
+			Name:    "ChangePassword",
+			Auth:    true,
+			Account: AccountChangePassword,
+			Params:  []*Param{{Name: "current_password"}, {Name: "new_password"}},
+			Validation: []*ValidationRule{
+				{Field: "current_password", Rule: "not_empty"},
+				{Field: "new_password", Rule: "min_length", Value: strconv.Itoa(MinPasswordLength)},
+			},
+		})
+	}
+	if acc.Delete && hasPassword {
+		add(&Endpoint{
+			Name:       "DeleteAccount",
+			Auth:       true,
+			Account:    AccountDelete,
+			Params:     []*Param{{Name: "password"}},
+			Validation: []*ValidationRule{{Field: "password", Rule: "not_empty"}},
+		})
+	}
+
+	if !acc.Profile && !acc.Password && !acc.Delete {
+		return
+	}
+	acc.Page = freePageName(app, "Settings", "AccountSettings")
+	app.Pages = append(app.Pages, &Page{Name: acc.Page})
+}
+
+// freePageName returns name, or else when the app has a page of that name
+// already, alt.
+func freePageName(app *Application, name, alt string) string {
+	for _, page := range app.Pages {
+		if page.Name == name {
+			return alt
+		}
+	}
+	return name
+}
+
+// addInvitations adds what inviting teammates needs: an Invitation model
+// holding each pending invitation's email, secret token, role, and expiry,
+// a Membership model saying who is on which team with what role, the
+// endpoints inviting a teammate, looking an invitation up by its token,
+// and accepting it, and the pages doing each. Teammates are invited as one
+// of the app's policies, so the role they're given is one the policies
+// check; a "Member" policy, or else the last one, is the role they get
+// when the inviter doesn't choose. Models and endpoints the app declares
+// itself are kept.
+func addInvitations(app *Application, user *DataModel) {
+	inv := app.Accounts.Invite
+	team := modelNamed(app, inv.Team)
+	if team == nil {
+		inv.Missing, inv.Team = inv.Team, ""
+		return
+	}
+	inv.Team = team.Name
+
+	for _, pol := range app.Policies {
+		inv.Roles = append(inv.Roles, pol.Name)
+		if strings.EqualFold(pol.Name, "member") {
+			inv.Role = pol.Name
+		}
+	}
+	if inv.Role == "" && len(inv.Roles) > 0 {
+		inv.Role = inv.Roles[len(inv.Roles)-1]
+	}
+	if inv.Role == "" {
+		inv.Role = "member"
+	}
+
+	addModel := func(m *DataModel) {
+		if modelNamed(app, m.Name) != nil {
+			return
+		}
+		for _, rel := range m.Relations {
+			if target := modelNamed(app, rel.Target); target != nil {
+				target.Relations = append(target.Relations, &Relation{Kind: "has_many", Target: m.Name})
+			}
+		}
+		app.Data = append(app.Data, m)
+	}
+	addModel(&DataModel{
+		Name: InvitationModel,
+		Fields: []*DataField{
+			{Name: "email", Type: "email", Required: true},
+			{Name: "token", Type: "text", Required: true, Unique: true},
+			{Name: "role", Type: "text", Required: true},
+			{Name: "expires", Type: "datetime", Required: true},
+			{Name: "accepted", Type: "datetime"},
+		},
+		Relations: []*Relation{{Kind: "belongs_to", Target: team.Name}},
+	})
+	addModel(&DataModel{
+		Name:   MembershipModel,
+		Fields: []*DataField{{Name: "role", Type: "text", Required: true}},
+		Relations: []*Relation{
+			{Kind: "belongs_to", Target: user.Name},
+			{Kind: "belongs_to", Target: team.Name},
+		},
+	})
+
+	add := func(ep *Endpoint) {
+		for _, existing := range app.APIs {
+			if strings.EqualFold(existing.Name, ep.Name) {
+				return
+			}
+		}
+		app.APIs = append(app.APIs, ep)
+	}
+	add(&Endpoint{
+		Name:    "InviteTeammate",
+		Auth:    true,
+		Account: AccountInvite,
+		Params:  []*Param{{Name: "email"}, {Name: "role"}},
+		Validation: []*ValidationRule{
+			{Field: "email", Rule: "not_empty"},
+			{Field: "email", Rule: "valid_email"},
+		},
+	})
+	// The token is read from the query string: whoever holds the link
+	// can see who it's for before signing in
+	add(&Endpoint{Name: "GetInvitation", Account: AccountInvitation})
+	add(&Endpoint{
+		Name:       "AcceptInvitation",
+		Auth:       true,
+		Account:    AccountAcceptInvite,
+		Params:     []*Param{{Name: "token"}},
+		Validation: []*ValidationRule{{Field: "token", Rule: "not_empty"}},
+	})
+
+	inv.Page = freePageName(app, "Team", "TeamMembers")
+	inv.Accept = freePageName(app, "AcceptInvite", "AcceptInvitation")
+	app.Pages = append(app.Pages, &Page{Name: inv.Page}, &Page{Name: inv.Accept})
+}
+
+// planPattern matches one plan of a plans block once the lexer has dropped
+// its "$", "(", and "/": "Free", "Pro 19 month", "Team at 49.50 per year".
+// A plan's name is one or two words, and a price without an interval is
+// monthly.
+var planPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]*(?: [A-Za-z][A-Za-z0-9]*)??)(?:\s+(?:at|for))?(?:\s+(\d+(?:\.\d{1,2})?)(?:\s+(?:per|a|an|every))?(?:\s+(month|year|monthly|yearly|annually|mo|yr))?)?$`)
+
+// buildBilling reads the plans of a plans block, listed after its colon,
+// one per line, or both, and separated by commas. A plan without a price is
+// the free plan when it's named Free or is listed first, and otherwise a
+// custom plan sold by sales.
+func buildBilling(d *parser.PlansDeclaration, app *Application) *Billing {
+	b := &Billing{Currency: strings.ToLower(Currency(app))}
+	if b.Currency == "" {
+		b.Currency = "usd"
+	}
+	entries := strings.Split(d.Text, ",")
+	for _, s := range d.Statements {
+		entries = append(entries, strings.Split(s.Text, ",")...)
+	}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if lower := strings.ToLower(entry); strings.HasPrefix(lower, "and ") || strings.HasPrefix(lower, "or ") {
+			entry = strings.TrimSpace(entry[strings.Index(entry, " "):])
+		}
+		if entry == "" {
+			continue
+		}
+		m := planPattern.FindStringSubmatch(entry)
+		if m == nil {
+			b.Unknown = append(b.Unknown, entry)
+			continue
+		}
+		plan := &Plan{Name: m[1]}
+		if m[2] != "" {
+			dollars, _ := strconv.ParseFloat(m[2], 64)
+			plan.Price = int(math.Round(dollars * 100))
+			plan.Interval = "month"
+			switch m[3] {
+			case "year", "yearly", "annually", "yr":
+				plan.Interval = "year"
+			}
+		} else if !strings.EqualFold(plan.Name, "free") && len(b.Plans) > 0 {
+			plan.Custom = true
+		}
+		b.Plans = append(b.Plans, plan)
+	}
+	return b
+}
+
+// addBilling adds what selling subscriptions needs when the app has a User
+// model to subscribe and a payment integration to charge: a Plan model
+// holding each plan and the Stripe price it's bought at, a Subscription
+// model holding each user's subscription as Stripe's webhook reports it,
+// and the pricing page. An app declaring its own Plan or Subscription
+// model keeps it and isn't given billing. Each plan is gated by the policy named after it:
+// "Pro", "ProPlan", or "ProUser".
+func addBilling(app *Application) {
+	b := app.Billing
+	for _, plan := range b.Plans {
+		key := strings.ReplaceAll(plan.Name, " ", "")
+		for _, pol := range app.Policies {
+			for _, name := range []string{key, key + "Plan", key + "User"} {
+				if plan.Policy == "" && strings.EqualFold(pol.Name, name) {
+					plan.Policy = pol.Name
+				}
+			}
+		}
+	}
+
+	user := modelNamed(app, "user")
+	if user == nil || PaymentIntegration(app) == nil || len(b.Plans) == 0 ||
+		modelNamed(app, PlanModel) != nil || modelNamed(app, SubscriptionModel) != nil {
+		return
+	}
+	b.Model = user.Name
+
+	addModel := func(m *DataModel) {
+		for _, rel := range m.Relations {
+			if target := modelNamed(app, rel.Target); target != nil {
+				target.Relations = append(target.Relations, &Relation{Kind: "has_many", Target: m.Name})
+			}
+		}
+		app.Data = append(app.Data, m)
+	}
+	addModel(&DataModel{
+		Name: PlanModel,
+		Fields: []*DataField{
+			{Name: "name", Type: "text", Required: true, Unique: true},
+			{Name: "price", Type: "number", Required: true},
+			{Name: "interval", Type: "text"},
+			{Name: "position", Type: "number", Required: true},
+			{Name: "stripe_price_id", Type: "text"},
+		},
+	})
+	addModel(&DataModel{
+		Name: SubscriptionModel,
+		Fields: []*DataField{
+			{Name: "status", Type: "text", Required: true},
+			{Name: "stripe_customer_id", Type: "text", Required: true},
+			{Name: "stripe_subscription_id", Type: "text", Required: true, Unique: true},
+			{Name: "current_period_end", Type: "datetime"},
+		},
+		Relations: []*Relation{
+			{Kind: "belongs_to", Target: user.Name},
+			{Kind: "belongs_to", Target: PlanModel},
+		},
+	})
+
+	b.Page = freePageName(app, "Pricing", "Plans")
+	app.Pages = append(app.Pages, &Page{Name: b.Page})
+}
+
+// meteringPattern matches a "meter API calls per user per day" statement.
+var meteringPattern = regexp.MustCompile(`^meter (?:the )?(?:api )?(?:calls|requests)\b`)
+
+// buildMetering parses "meter API calls per user per day". Calls are
+// counted per day unless the statement says week or month.
+func buildMetering(text string) *Metering {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	if !meteringPattern.MatchString(lower) {
+		return nil
+	}
+	m := &Metering{Period: "day"}
+	for _, period := range []string{"week", "month"} {
+		if strings.HasSuffix(lower, " per "+period) || strings.HasSuffix(lower, " a "+period) || strings.HasSuffix(lower, " each "+period) {
+			m.Period = period
+		}
+	}
+	return m
+}
+
+// addMetering adds what metering needs when the app has a User model to
+// count calls for: a UsageRecord model holding a user's calls in each
+// period, and the usage dashboard. An app declaring its own UsageRecord
+// model keeps it and isn't metered.
+func addMetering(app *Application) {
+	user := modelNamed(app, "user")
+	if user == nil || modelNamed(app, UsageModel) != nil {
+		return
+	}
+	app.Metering.Model = user.Name
+	user.Relations = append(user.Relations, &Relation{Kind: "has_many", Target: UsageModel})
+	app.Data = append(app.Data, &DataModel{
+		Name: UsageModel,
+		Fields: []*DataField{
+			{Name: "period_start", Type: "datetime", Required: true},
+			{Name: "calls", Type: "number", Required: true},
+		},
+		Relations: []*Relation{
+			{Kind: "belongs_to", Target: user.Name},
+		},
+	})
+
+	app.Metering.Page = freePageName(app, "Usage", "ApiUsage")
+	app.Pages = append(app.Pages, &Page{Name: app.Metering.Page})
+}
+
+// addAPIKeys adds what API keys need when the app has a User model for
+// keys to belong to: an ApiKey model holding each key's name, the start of
+// the key to recognize it by, its hash, its scopes, and when it was last
+// used, and the page managing them. An app declaring its own ApiKey model
+// keeps it and issues no keys.
+func addAPIKeys(app *Application) {
+	user := modelNamed(app, "user")
+	if user == nil || modelNamed(app, APIKeyModel) != nil {
+		return
+	}
+	keys := app.Auth.APIKeys
+	keys.Model = user.Name
+	user.Relations = append(user.Relations, &Relation{Kind: "has_many", Target: APIKeyModel})
+	app.Data = append(app.Data, &DataModel{
+		Name: APIKeyModel,
+		Fields: []*DataField{
+			{Name: "name", Type: "text", Required: true},
+			{Name: "prefix", Type: "text", Required: true},
+			{Name: "key_hash", Type: "text", Required: true, Unique: true},
+			{Name: "scopes", Type: "text", Required: true},
+			{Name: "last_used_at", Type: "datetime"},
+		},
+		Relations: []*Relation{
+			{Kind: "belongs_to", Target: user.Name},
+		},
+	})
+
+	keys.Page = freePageName(app, "ApiKeys", "DeveloperKeys")
+	app.Pages = append(app.Pages, &Page{Name: keys.Page})
+}
+
+// defaultReportCache is how long clients may cache a report endpoint's
+// result when its API doesn't say.
+const defaultReportCache = 60
+
+// addAggregate makes an endpoint a report when one of its steps counts,
+// sums, or averages a model's records per group. A "cache for 5 minutes"
+// step sets how long clients may keep the result.
+func addAggregate(app *Application, ep *Endpoint) {
+	for _, step := range ep.Steps {
+		if ep.Aggregate == nil && IsAggregate(step.Text) {
+			if agg, err := ParseAggregate(app, step.Text); err == nil {
+				ep.Aggregate = agg
+			}
+		}
+		if secs := cacheSeconds(step.Text); secs > 0 {
+			ep.Cache = secs
+		}
+	}
+	if ep.Aggregate != nil && ep.Cache == 0 {
+		ep.Cache = defaultReportCache
+	}
+}
+
+// cacheSeconds returns the seconds "cache for 5 minutes" or "cache the
+// result for an hour" allows, or 0.
+func cacheSeconds(text string) int {
+	lower := strings.ToLower(text)
+	if !strings.HasPrefix(lower, "cache ") {
+		return 0
+	}
+	_, after, _ := strings.Cut(lower, " for ")
+	words := strings.Fields(after)
+	if len(words) == 0 {
+		return 0
+	}
+	n := 1
+	if len(words) > 1 {
+		if v, err := strconv.Atoi(words[0]); err == nil {
+			n = v
+		}
+		words = words[1:]
+	}
+	switch strings.TrimSuffix(words[0], "s") {
+	case "second":
+		return n
+	case "minute":
+		return n * 60
+	case "hour":
+		return n * 3600
+	case "day":
+		return n * 86400
+	}
+	return 0
+}
+
+// inferDocumentFields picks the fields a document shows: a "number",
+// "code", or "reference" field as the reference, a "total", "amount", or
+// "price" field as the total, the rest as detail rows, and the first
+// has-many model as line items. Secrets, files, and timestamps are left out.
+func inferDocumentFields(doc *Document, model *DataModel, app *Application) {
+	shown := func(f *DataField) bool {
+		name := strings.ToLower(f.Name)
+		return !f.Encrypted && name != "password" && f.Type != "file" && f.Type != "image" &&
+			name != "created" && name != "updated" && name != "createdat" && name != "updatedat"
+	}
+	isAmount := func(f *DataField) bool { return f.Type == "decimal" || f.Type == "number" }
+
+	for _, f := range model.Fields {
+		name := strings.ToLower(f.Name)
+		if f.Type == "text" && (strings.Contains(name, "number") || strings.Contains(name, "code") || strings.Contains(name, "reference")) {
+			doc.Reference = f.Name
+			break
+		}
+	}
+	for _, match := range []func(string) bool{
+		func(n string) bool { return n == "total" || n == "grand_total" },
+		func(n string) bool { return strings.Contains(n, "total") },
+		func(n string) bool { return strings.Contains(n, "amount") },
+		func(n string) bool { return strings.Contains(n, "price") },
+	} {
+		for _, f := range model.Fields {
+			if isAmount(f) && match(strings.ToLower(f.Name)) {
+				doc.Total = f.Name
+				break
+			}
+		}
+		if doc.Total != "" {
+			break
+		}
+	}
+	for _, f := range model.Fields {
+		if shown(f) && f.Name != doc.Reference && f.Name != doc.Total {
+			doc.Fields = append(doc.Fields, f.Name)
+		}
+	}
+
+	for _, rel := range model.Relations {
+		if rel.Kind != "has_many" {
+			continue
+		}
+		for _, m := range app.Data {
+			if m.Name != rel.Target {
+				continue
+			}
+			doc.Items = m.Name
+			for _, f := range m.Fields {
+				if shown(f) && len(doc.ItemFields) < 5 {
+					doc.ItemFields = append(doc.ItemFields, f.Name)
+				}
+			}
+		}
+		if doc.Items != "" {
+			break
+		}
+	}
+}
+
+// ── Backups ──
+
+var (
+	backupTimeRe      = regexp.MustCompile(`\bat (\d{1,2})(?::(\d{2}))? ?(am|pm)?\b`)
+	backupEveryRe     = regexp.MustCompile(`\bevery (\d+) hours?\b`)
+	backupRetentionRe = regexp.MustCompile(`\bfor (\d+) (day|week|month|year)s?\b`)
+	backupWeekdays    = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+)
+
+// buildBackup reads the database block's "backup ..." and "keep backups for
+// ..." rules. The first S3-compatible storage integration receives a copy
+// of each backup. It returns nil when no rule mentions backing up.
+func buildBackup(app *Application) *Backup {
+	var b *Backup
+	for _, rule := range app.Database.Rules {
+		lower := strings.ToLower(rule.Text)
+		switch {
+		case strings.HasPrefix(lower, "backup ") || strings.HasPrefix(lower, "back up "):
+			if b == nil {
+				b = &Backup{}
+			}
+			if b.Description == "" {
+				desc := strings.TrimPrefix(strings.TrimPrefix(lower, "backup "), "back up ")
+				desc = strings.TrimPrefix(desc, "the database ")
+				b.Description = joinTimes(desc)
+				b.Schedule, _ = ParseBackupSchedule(desc)
+			}
+		case strings.Contains(lower, "backups for "):
+			if b == nil {
+				b = &Backup{}
+			}
+			if m := backupRetentionRe.FindStringSubmatch(lower); m != nil {
+				n, _ := strconv.Atoi(m[1])
+				b.RetentionDays = n * map[string]int{"day": 1, "week": 7, "month": 30, "year": 365}[m[2]]
+			}
+		}
+	}
+	if b == nil || b.Description == "" {
+		return nil
+	}
+	b.Storage = backupStorage(app)
+	return b
+}
+
+// backupStorage returns the first S3-compatible storage integration, or "".
+func backupStorage(app *Application) string {
+	for _, integ := range app.Integrations {
+		svc := strings.ToLower(integ.Service)
+		if integ.Type == "storage" && (strings.Contains(svc, "s3") || strings.Contains(svc, "minio")) {
+			return integ.Service
+		}
+	}
+	return ""
+}
+
+// applyCompliance tightens defaults for the app's compliance profile.
+// Personal data is masked in logs, and encrypted at rest unless the field
+// is unique, since lookups by a unique field can't match ciphertext. A
+// PostgreSQL database without backup rules is backed up daily. What the
+// .human file says explicitly is kept; the quality engine reports where
+// it falls short of the profile.
+func applyCompliance(app *Application) {
+	profile := Compliance(app)
+	if profile == nil {
+		return
+	}
+	for _, m := range app.Data {
+		for _, f := range m.Fields {
+			if !f.IsPII() {
+				continue
+			}
+			f.Personal = true
+			if !f.Unique && f.encryptable() {
+				f.Encrypted = true
+			}
+		}
+	}
+	if !strings.Contains(strings.ToLower(DatabaseEngine(app)), "postgres") {
+		return
+	}
+	if app.Database == nil {
+		app.Database = &DatabaseConfig{}
+	}
+	if app.Database.Backup == nil {
+		app.Database.Backup = &Backup{
+			Schedule:      DefaultBackupSchedule,
+			Description:   "daily (" + profile.Name + " compliance profile)",
+			RetentionDays: profile.BackupRetentionDays,
+			Storage:       backupStorage(app),
+		}
+	}
+}
+
+// ParseBackupSchedule turns a backup rule's schedule — "daily at 3 am",
+// "every 6 hours", "weekly on sunday at 2: 00 pm", "monthly" — into a cron
+// expression. The lexer splits "3:30am" into "3: 30 am", so the time is
+// rejoined first. It reports false for schedules it doesn't understand.
+func ParseBackupSchedule(text string) (string, bool) {
+	s := joinTimes(strings.ToLower(text))
+
+	hour, minute := 0, 0
+	timed := false
+	switch {
+	case strings.Contains(s, "at midnight"):
+		timed = true
+	case strings.Contains(s, "at noon"):
+		hour, timed = 12, true
+	default:
+		if m := backupTimeRe.FindStringSubmatch(s); m != nil {
+			hour, _ = strconv.Atoi(m[1])
+			if m[2] != "" {
+				minute, _ = strconv.Atoi(m[2])
+			}
+			switch {
+			case m[3] == "pm" && hour < 12:
+				hour += 12
+			case m[3] == "am" && hour == 12:
+				hour = 0
+			}
+			if hour > 23 || minute > 59 {
+				return "", false
+			}
+			timed = true
+		}
+	}
+	at := fmt.Sprintf("%d %d", minute, hour)
+
+	if m := backupEveryRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if n < 1 || n > 24 {
+			return "", false
+		}
+		if n == 24 {
+			return at + " * * *", true
+		}
+		return fmt.Sprintf("%d */%d * * *", minute, n), true
+	}
+	if strings.Contains(s, "hourly") || strings.Contains(s, "every hour") {
+		return fmt.Sprintf("%d * * * *", minute), true
+	}
+	for i, day := range backupWeekdays {
+		if strings.Contains(s, day) {
+			return fmt.Sprintf("%s * * %d", at, i), true
+		}
+	}
+	if strings.Contains(s, "weekly") || strings.Contains(s, "every week") {
+		return at + " * * 0", true
+	}
+	if strings.Contains(s, "monthly") || strings.Contains(s, "every month") {
+		return at + " 1 * *", true
+	}
+	for _, daily := range []string{"daily", "nightly", "every day", "every night", "each day", "each night"} {
+		if strings.Contains(s, daily) {
+			return at + " * * *", true
+		}
+	}
+	if timed {
+		return at + " * * *", true
+	}
+	return "", false
+}
+
 // ── String helpers ──
 
+// joinTimes rejoins the times the lexer splits: "3: 30 am" → "3:30 am".
+func joinTimes(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, ": ", ":"), " :", ":")
+}
+
 // extractAfter returns the substring after the first occurrence of prefix.
 func extractAfter(s, prefix string) string {
 	idx := strings.Index(s, prefix)
@@ -994,3 +2447,26 @@ func extractBetween(s, start, end string) string {
 	}
 	return after[:eIdx]
 }
+
+// ── Seed Data ──
+
+var seedLine = regexp.MustCompile(`(?i)^(\d+)\s+(\S+)(?:\s+with\s+(.+))?$`)
+
+// buildSeed reads a seed line: a count, the model in the singular or
+// plural, and optionally what the records are like, e.g. "50 Users with
+// realistic names and emails". A line it can't read keeps only its text,
+// for the analyzer to report.
+func buildSeed(d *parser.SeedDeclaration, app *Application) *Seed {
+	seed := &Seed{Text: d.Text, Line: d.Line}
+	m := seedLine.FindStringSubmatch(strings.TrimSpace(d.Text))
+	if m == nil {
+		return seed
+	}
+	seed.Count, _ = strconv.Atoi(m[1])
+	seed.Model = m[2]
+	if model := modelNamed(app, m[2]); model != nil {
+		seed.Model = model.Name
+	}
+	seed.With = m[3]
+	return seed
+}
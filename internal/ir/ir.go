@@ -1,29 +1,57 @@
 package ir
 
-import "strings"
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/barun-bash/human/internal/parser"
+)
 
 // Application is the root IR node representing a complete application.
 // It is framework-agnostic and serializable — given only this IR,
 // any code generator can produce a working application.
 type Application struct {
-	Name          string          `json:"name"`
-	Platform      string          `json:"platform"`
-	Config        *BuildConfig    `json:"config,omitempty"`
-	Data          []*DataModel    `json:"data,omitempty"`
-	Pages         []*Page         `json:"pages,omitempty"`
-	Components    []*Component    `json:"components,omitempty"`
-	APIs          []*Endpoint     `json:"apis,omitempty"`
-	Policies      []*Policy       `json:"policies,omitempty"`
-	Workflows     []*Workflow     `json:"workflows,omitempty"`
-	Theme         *Theme          `json:"theme,omitempty"`
-	Auth          *Auth           `json:"auth,omitempty"`
-	Database      *DatabaseConfig `json:"database,omitempty"`
-	Integrations  []*Integration  `json:"integrations,omitempty"`
-	Environments  []*Environment  `json:"environments,omitempty"`
-	ErrorHandlers []*ErrorHandler  `json:"error_handlers,omitempty"`
-	Pipelines     []*Pipeline      `json:"pipelines,omitempty"`
-	Architecture  *Architecture    `json:"architecture,omitempty"`
+	Name          string            `json:"name"`
+	Platform      string            `json:"platform"`
+	Config        *BuildConfig      `json:"config,omitempty"`
+	Data          []*DataModel      `json:"data,omitempty"`
+	Pages         []*Page           `json:"pages,omitempty"`
+	Components    []*Component      `json:"components,omitempty"`
+	APIs          []*Endpoint       `json:"apis,omitempty"`
+	Policies      []*Policy         `json:"policies,omitempty"`
+	Workflows     []*Workflow       `json:"workflows,omitempty"`
+	Hooks         []*ModelHook      `json:"hooks,omitempty"`
+	Theme         *Theme            `json:"theme,omitempty"`
+	Auth          *Auth             `json:"auth,omitempty"`
+	Database      *DatabaseConfig   `json:"database,omitempty"`
+	Integrations  []*Integration    `json:"integrations,omitempty"`
+	Environments  []*Environment    `json:"environments,omitempty"`
+	ErrorHandlers []*ErrorHandler   `json:"error_handlers,omitempty"`
+	Pipelines     []*Pipeline       `json:"pipelines,omitempty"`
+	Architecture  *Architecture     `json:"architecture,omitempty"`
 	Monitoring    []*MonitoringRule `json:"monitoring,omitempty"`
+	Experiments   []*Experiment     `json:"experiments,omitempty"`
+	Notifications []*Notification   `json:"notifications,omitempty"`
+	Calendars     []*CalendarFeed   `json:"calendars,omitempty"`
+	Charts        []*Chart          `json:"charts,omitempty"`
+	Reorders      []*Reorder        `json:"reorders,omitempty"`
+	DetailRoutes  []*DetailRoute    `json:"detail_routes,omitempty"`
+	Documents     []*Document       `json:"documents,omitempty"`
+	Sitemap       *Sitemap          `json:"sitemap,omitempty"`
+	Accounts      *Accounts         `json:"accounts,omitempty"`
+	Billing       *Billing          `json:"billing,omitempty"`
+	Metering      *Metering         `json:"metering,omitempty"`
+	Seeds         []*Seed           `json:"seeds,omitempty"`
+	Migrations    []*Migration      `json:"migrations,omitempty"`
+
+	// Build is the build generating code from the IR, which is not part of
+	// it: set by the build pipeline, and left out of the IR's JSON.
+	Build *BuildInfo `json:"-"`
 }
 
 // ── Build Configuration ──
@@ -37,13 +65,241 @@ type PortConfig struct {
 
 // BuildConfig holds the target framework and deployment choices.
 type BuildConfig struct {
-	Frontend string     `json:"frontend,omitempty"` // e.g. "React with TypeScript"
-	Backend  string     `json:"backend,omitempty"`  // e.g. "Node with Express"
-	Database string     `json:"database,omitempty"` // e.g. "PostgreSQL"
-	Deploy   string     `json:"deploy,omitempty"`   // e.g. "Docker"
-	Ports    PortConfig `json:"ports,omitempty"`    // port configuration for services
+	Frontend     string     `json:"frontend,omitempty"`      // e.g. "React with TypeScript"
+	Backend      string     `json:"backend,omitempty"`       // e.g. "Node with Express"
+	Database     string     `json:"database,omitempty"`      // e.g. "PostgreSQL"
+	Deploy       string     `json:"deploy,omitempty"`        // e.g. "Docker"
+	APIStyle     string     `json:"api_style,omitempty"`     // e.g. "gRPC"; REST when empty
+	Compliance   string     `json:"compliance,omitempty"`    // e.g. "SOC2"; see ComplianceProfiles
+	Styling      string     `json:"styling,omitempty"`       // e.g. "Tailwind"; plain CSS when empty
+	BundleBudget string     `json:"bundle_budget,omitempty"` // e.g. "200 KB per route"; see BundleBudgetKB
+	Times        string     `json:"times,omitempty"`         // e.g. "UTC and display in the user's timezone"; see StoresUTC
+	Validation   string     `json:"validation,omitempty"`    // e.g. "the OpenAPI spec"; see ValidatesRequests
+	Ports        PortConfig `json:"ports,omitempty"`         // port configuration for services
+	Env          string     `json:"env,omitempty"`           // environment `human build --env` targets; see TargetEnvironment
+}
+
+// Cache-Control values for a built frontend: files with a content hash in
+// their name never change, while index.html must be revalidated so that a
+// deploy picks up the new hashes.
+const (
+	ImmutableCacheControl  = "public, max-age=31536000, immutable"
+	RevalidateCacheControl = "no-cache"
+)
+
+// HashedAssetPaths returns the URL path patterns, in CloudFront's syntax,
+// of the frontend build's content-hashed files. Vite writes them under
+// /assets/; Angular writes its bundles to the root and url() assets under
+// /media/, leaving src/assets unhashed.
+func HashedAssetPaths(app *Application) []string {
+	if app.Config != nil && strings.Contains(strings.ToLower(app.Config.Frontend), "angular") {
+		return []string{"*.js", "*.css", "/media/*"}
+	}
+	return []string{"/assets/*"}
+}
+
+// ── Compliance ──
+
+// ComplianceProfile is a preset of stricter generator defaults, chosen with
+// "compliance profile is SOC2" in the build block. Personal data is
+// encrypted at rest, requests that change data are audit-logged, a
+// PostgreSQL database is backed up, and logs are kept for the minimums
+// below. Profiles are a starting point for an audit, not a certification.
+type ComplianceProfile struct {
+	Name                string
+	LogRetentionDays    int // minimum for access and audit logs
+	BackupRetentionDays int // minimum for database backups
+}
+
+// ComplianceProfiles are the supported presets, keyed by name.
+var ComplianceProfiles = map[string]*ComplianceProfile{
+	"SOC2":  {Name: "SOC2", LogRetentionDays: 365, BackupRetentionDays: 30},
+	"HIPAA": {Name: "HIPAA", LogRetentionDays: 2192, BackupRetentionDays: 90},
+}
+
+// Compliance returns the app's compliance profile, or nil when it has none
+// or names one that doesn't exist. "soc 2" and "HIPAA-lite" are accepted.
+func Compliance(app *Application) *ComplianceProfile {
+	if app.Config == nil || app.Config.Compliance == "" {
+		return nil
+	}
+	name := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(app.Config.Compliance))
+	return ComplianceProfiles[strings.TrimSuffix(name, "LITE")]
+}
+
+// Styling systems a frontend's styles can be written in, chosen with
+// "styling using Tailwind" in the build block. Plain CSS when none is.
+const (
+	StylingTailwind         = "tailwind"
+	StylingCSSModules       = "css-modules"
+	StylingVanillaExtract   = "vanilla-extract"
+	StylingStyledComponents = "styled-components"
+)
+
+// stylingSystems maps styling names, letters only, to their system.
+var stylingSystems = map[string]string{
+	"css":              "",
+	"plaincss":         "",
+	"tailwind":         StylingTailwind,
+	"tailwindcss":      StylingTailwind,
+	"cssmodules":       StylingCSSModules,
+	"vanillaextract":   StylingVanillaExtract,
+	"styledcomponents": StylingStyledComponents,
+}
+
+// LookupStyling returns the styling system a name stands for, "" for plain
+// CSS, and whether the name is one at all. "CSS modules" and
+// "styled-components" are accepted.
+func LookupStyling(name string) (string, bool) {
+	key := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(name))
+	system, ok := stylingSystems[key]
+	return system, ok
+}
+
+// StylingSupported reports whether a frontend can be styled with a styling
+// system: plain CSS and Tailwind style any frontend, the others only React.
+func StylingSupported(system, frontend string) bool {
+	return system == "" || system == StylingTailwind || strings.Contains(strings.ToLower(frontend), "react")
+}
+
+// Styling returns the styling system the app's frontend is written in, or ""
+// for plain CSS, which is also what a frontend gets when the build names a
+// system that doesn't exist or that it can't use.
+func Styling(app *Application) string {
+	if app.Config == nil {
+		return ""
+	}
+	system, _ := LookupStyling(app.Config.Styling)
+	if !StylingSupported(system, app.Config.Frontend) {
+		return ""
+	}
+	return system
+}
+
+// DefaultBundleBudgetKB is how much generated code a frontend route may
+// load, in KB, when the build block doesn't say.
+const DefaultBundleBudgetKB = 250
+
+// ParseBundleBudget reads a bundle budget in KB: "200 KB per route", "200kb",
+// "1 MB per page", or a bare number of KB.
+func ParseBundleBudget(text string) (int, bool) {
+	fields := strings.Fields(strings.ToLower(text))
+	if n := len(fields); n >= 2 && fields[n-2] == "per" && (fields[n-1] == "route" || fields[n-1] == "page") {
+		fields = fields[:n-2]
+	}
+	size := strings.Join(fields, "")
+	scale := 1
+	switch {
+	case strings.HasSuffix(size, "kb"):
+		size = strings.TrimSuffix(size, "kb")
+	case strings.HasSuffix(size, "mb"):
+		size, scale = strings.TrimSuffix(size, "mb"), 1024
+	}
+	n, err := strconv.Atoi(size)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n * scale, true
+}
+
+// BundleBudgetKB returns how much generated code each of the app's frontend
+// routes may load, in KB: the budget set with "bundle budget is 200 KB per
+// route" in the build block, or the default when it sets none it can read.
+func BundleBudgetKB(app *Application) int {
+	if app.Config != nil {
+		if kb, ok := ParseBundleBudget(app.Config.BundleBudget); ok {
+			return kb
+		}
+	}
+	return DefaultBundleBudgetKB
+}
+
+// ParseTimePolicy reads the time policy of "store all times in UTC and
+// display in the user's timezone", given what follows "store all times in".
+// Times can only be stored in UTC, and are displayed in the time zone of
+// whoever is looking at them.
+func ParseTimePolicy(text string) bool {
+	lower := strings.ToLower(text)
+	store, display, _ := strings.Cut(lower, "display")
+	if !strings.Contains(store, "utc") {
+		return false
+	}
+	display = strings.TrimSpace(display)
+	return display == "" || strings.Contains(display, "user") || strings.Contains(display, "local") || strings.Contains(display, "their")
+}
+
+// StoresUTC reports whether the app stores all times in UTC and displays
+// them in the user's time zone. Every layer then handles times the same
+// way: time columns keep their time zone, the backend runs in UTC and
+// writes times as ISO 8601 with an offset, a time without an offset is
+// taken as UTC, and the frontend shows times with Intl in the user's
+// locale and time zone.
+func StoresUTC(app *Application) bool {
+	return app.Config != nil && ParseTimePolicy(app.Config.Times)
+}
+
+// ValidatesRequests reports whether the backend checks each API request
+// against the app's OpenAPI document before its route runs, as "validate
+// requests against the OpenAPI spec" in the build block asks. Only an app
+// with APIs has a document to check them against.
+func ValidatesRequests(app *Application) bool {
+	return app.Config != nil && app.Config.Validation != "" && len(app.APIs) > 0
+}
+
+// AuditsRequests reports whether the backend writes an audit log entry for
+// each request that changes data.
+func AuditsRequests(app *Application) bool {
+	return Compliance(app) != nil
+}
+
+// DefaultLogRetentionDays is how long logs are kept without a "keep logs
+// for" rule or a compliance profile.
+const DefaultLogRetentionDays = 30
+
+// LogRetentionDays returns how long access and audit logs are kept: the
+// "keep logs for" rule if there is one, else the compliance profile's
+// minimum, else DefaultLogRetentionDays.
+func LogRetentionDays(app *Application) int {
+	if days, ok := DeclaredLogRetentionDays(app); ok {
+		return days
+	}
+	if profile := Compliance(app); profile != nil {
+		return profile.LogRetentionDays
+	}
+	return DefaultLogRetentionDays
+}
+
+// DeclaredLogRetentionDays returns the retention from a "keep logs for 90
+// days" rule, if the app has one that can be read.
+func DeclaredLogRetentionDays(app *Application) (int, bool) {
+	for _, m := range app.Monitoring {
+		if m.Kind != "log" || m.Duration == "" {
+			continue
+		}
+		if days := durationDays(m.Duration); days > 0 {
+			return days, true
+		}
+	}
+	return 0, false
+}
+
+// durationDays converts "90 days", "6 months", or "1 year" to days, or 0.
+func durationDays(s string) int {
+	m := durationRe.FindStringSubmatch(strings.ToLower(s))
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n * map[string]int{"day": 1, "week": 7, "month": 30, "year": 365}[m[2]]
 }
 
+var durationRe = regexp.MustCompile(`(\d+)\s*(day|week|month|year)s?`)
+
 // ── Data Layer ──
 
 // DataModel represents a data entity with typed fields and relationships.
@@ -51,22 +307,118 @@ type DataModel struct {
 	Name      string       `json:"name"`
 	Fields    []*DataField `json:"fields,omitempty"`
 	Relations []*Relation  `json:"relations,omitempty"`
+	Versioned bool         `json:"versioned,omitempty"` // updates must name the version they read, from "protect against conflicting edits"
 }
 
 // DataField is a typed field within a data model.
 type DataField struct {
 	Name       string   `json:"name"`
-	Type       string   `json:"type"`                  // text, number, email, datetime, enum, etc.
+	Type       string   `json:"type"` // text, number, email, datetime, enum, etc.
 	Required   bool     `json:"required"`
 	Unique     bool     `json:"unique,omitempty"`
 	Encrypted  bool     `json:"encrypted,omitempty"`
+	Personal   bool     `json:"personal,omitempty"`    // masked in logs and error responses
 	EnumValues []string `json:"enum_values,omitempty"` // for enum fields
 	Default    string   `json:"default,omitempty"`
 }
 
+// EncryptsAtRest reports whether the field is stored encrypted with the
+// app's field encryption key. An encrypted password is hashed instead,
+// since it is only ever compared, and only text, email, and url fields
+// can hold ciphertext.
+func (f *DataField) EncryptsAtRest() bool {
+	return f.Encrypted && f.encryptable()
+}
+
+func (f *DataField) encryptable() bool {
+	if strings.EqualFold(f.Name, "password") {
+		return false
+	}
+	switch strings.ToLower(f.Type) {
+	case "text", "email", "url":
+		return true
+	}
+	return false
+}
+
+// piiNames are substrings of field names that hold personal or health
+// data. Fields of type email always do.
+var piiNames = []string{
+	"phone", "mobile", "address", "street", "postal", "zip", "birth", "dob",
+	"ssn", "social security", "passport", "license", "licence", "tax id", "taxid",
+	"national id", "nationalid", "medical", "diagnosis", "health", "insurance",
+}
+
+// IsPII reports whether the field holds personal data: it is marked
+// personal, or its type or name says so.
+func (f *DataField) IsPII() bool {
+	if f.Personal || strings.EqualFold(f.Type, "email") {
+		return true
+	}
+	lower := strings.ToLower(f.Name)
+	for _, name := range piiNames {
+		if strings.Contains(lower, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldNamed returns the field called name, ignoring case and underscores
+// ("createdAt" finds created_at), or nil.
+func (m *DataModel) FieldNamed(name string) *DataField {
+	norm := func(s string) string { return strings.ToLower(strings.ReplaceAll(s, "_", "")) }
+	for _, f := range m.Fields {
+		if norm(f.Name) == norm(name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// EncryptedFields returns the model's fields that are encrypted at rest.
+func (m *DataModel) EncryptedFields() []*DataField {
+	var fields []*DataField
+	for _, f := range m.Fields {
+		if f.EncryptsAtRest() {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// UsesFieldEncryption reports whether any data model has a field encrypted
+// at rest, so the backend needs a field encryption key.
+func UsesFieldEncryption(app *Application) bool {
+	for _, m := range app.Data {
+		if len(m.EncryptedFields()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalFieldNames returns the names of fields marked personal across
+// all data models, sorted and without duplicates. The backends mask these
+// in logs and error responses.
+func PersonalFieldNames(app *Application) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, m := range app.Data {
+		for _, f := range m.Fields {
+			if f.Personal && !seen[f.Name] {
+				seen[f.Name] = true
+				names = append(names, f.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Relation is a relationship between data models.
 type Relation struct {
-	Kind    string `json:"kind"`              // belongs_to, has_many, has_many_through
+	Kind    string `json:"kind"` // belongs_to, has_many, has_many_through
 	Target  string `json:"target"`
 	Through string `json:"through,omitempty"` // join model for many-to-many
 }
@@ -101,6 +453,11 @@ type Endpoint struct {
 	Params     []*Param          `json:"params,omitempty"`
 	Validation []*ValidationRule `json:"validation,omitempty"`
 	Steps      []*Action         `json:"steps,omitempty"`
+	Aggregate  *Aggregate        `json:"aggregate,omitempty"` // the report a "sum the amount of Orders grouped by month" step serves
+	Cache      int               `json:"cache,omitempty"`     // seconds clients may cache the report, from "cache for 5 minutes"
+	PageSize   int               `json:"page_size,omitempty"` // records per page of a paginated list, from "paginate with 20 per page"
+	Import     *Import           `json:"import,omitempty"`    // the records an "import tasks from a csv or json file" step reads
+	Account    string            `json:"account,omitempty"`   // what an endpoint added by the accounts block does; see AccountProfile
 }
 
 // Param is an API input parameter.
@@ -139,12 +496,208 @@ type Workflow struct {
 	Steps   []*Action `json:"steps,omitempty"`
 }
 
+// UsesJobQueue reports whether the app has workflows. They run as
+// background jobs: the backend queues them in Redis and a worker, started
+// from the backend's image, runs them.
+func UsesJobQueue(app *Application) bool {
+	return len(app.Workflows) > 0
+}
+
+// Job is one background job of a workflow. A workflow's steps before its
+// first "after <n> <unit>" step form its first job; each delay starts a
+// job the first one schedules that long after the trigger.
+type Job struct {
+	Name    string        // e.g. "post-is-submitted-for-review-after-48-hours"
+	Trigger string        // the workflow's trigger
+	Delay   time.Duration // zero for the first job
+	After   string        // the delay as written, e.g. "48 hours"
+	Steps   []*Action
+}
+
+var delayRe = regexp.MustCompile(`^after (\d+) (second|minute|hour|day|week)s?\b,?\s*`)
+
+var delayUnits = map[string]time.Duration{
+	"second": time.Second,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+}
+
+// WorkflowJobs splits a workflow into its jobs; the first has no delay.
+// Text after a delay's comma ("after 3 days, send ...") is the first step
+// of the delayed job. Delays that aren't a time span, such as "after all
+// retries", stay ordinary steps.
+func WorkflowJobs(wf *Workflow) []*Job {
+	name := JobName(wf.Trigger)
+	jobs := []*Job{{Name: name, Trigger: wf.Trigger}}
+	for _, step := range wf.Steps {
+		m := delayRe.FindStringSubmatch(strings.ToLower(step.Text))
+		if step.Type != "delay" || m == nil {
+			job := jobs[len(jobs)-1]
+			job.Steps = append(job.Steps, step)
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		after := m[1] + " " + m[2]
+		if n != 1 {
+			after += "s"
+		}
+		job := &Job{
+			Name:    name + "-after-" + strings.ReplaceAll(after, " ", "-"),
+			Trigger: wf.Trigger,
+			Delay:   time.Duration(n) * delayUnits[m[2]],
+			After:   after,
+		}
+		if rest := strings.TrimSpace(step.Text[len(m[0]):]); rest != "" {
+			kind, _, _ := strings.Cut(strings.ToLower(rest), " ")
+			job.Steps = append(job.Steps, classifyAction(&parser.Statement{Kind: kind, Text: rest}))
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// JobName turns a workflow trigger into a job name: "a post is submitted
+// for review" becomes "post-is-submitted-for-review".
+func JobName(trigger string) string {
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(trigger), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		if len(words) == 0 && (w == "a" || w == "an" || w == "the") {
+			continue
+		}
+		words = append(words, w)
+	}
+	if len(words) == 0 {
+		return "workflow"
+	}
+	return strings.Join(words, "-")
+}
+
 // Pipeline represents a CI/CD pipeline triggered by code events.
 type Pipeline struct {
 	Trigger string    `json:"trigger"`
 	Steps   []*Action `json:"steps,omitempty"`
 }
 
+// ── Model Hooks ──
+
+// ModelHook is a step list the database layer runs whenever a record of a
+// model is created, updated, or deleted, from "before a Task is created,
+// set its status to "pending"". Unlike a workflow it runs in the request,
+// for every write of the model, whichever endpoint makes it.
+type ModelHook struct {
+	Model  string    `json:"model"`
+	Timing string    `json:"timing"` // "before" or "after"
+	Event  string    `json:"event"`  // "create", "update", or "delete"
+	Steps  []*Action `json:"steps,omitempty"`
+}
+
+// HookStep is a model hook's step, resolved against the models. A step
+// that is neither form is generated as a TODO.
+type HookStep struct {
+	// "set its status to "pending"": the field set, and its value as
+	// written. Only a before hook can set a field, since an after hook runs
+	// once the record is written.
+	Field *DataField
+	Value string
+
+	// "remove their Sessions": the model whose records belonging to the
+	// hooked record are deleted. On delete they go before the record does,
+	// in an after hook too, since their foreign keys would block it.
+	Remove *DataModel
+}
+
+var (
+	hookEventRe  = regexp.MustCompile(`(?i)^(?:an? |the )?(\w+) is (created|updated|deleted)$`)
+	hookSetRe    = regexp.MustCompile(`(?i)^set (.+?) to (.+)$`)
+	hookRemoveRe = regexp.MustCompile(`(?i)^(?:remove|delete) (?:their|its|all of their|all of its|all their|all its) (\w+)$`)
+)
+
+// parseHookEvent splits a hook's event, "a Task is created", into its
+// model and its event, "create". It returns false for any other event.
+func parseHookEvent(event string) (model, kind string, ok bool) {
+	m := hookEventRe.FindStringSubmatch(strings.TrimSpace(event))
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], strings.TrimSuffix(strings.ToLower(m[2]), "d"), true
+}
+
+// HookModel returns the model a hook runs on, or nil when the app has none
+// by its name.
+func HookModel(app *Application, hook *ModelHook) *DataModel {
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, hook.Model) {
+			return m
+		}
+	}
+	return nil
+}
+
+// ResolveHookStep resolves a step of a hook on model m. It returns nil for
+// a step that doesn't set one of the model's fields in a before hook, or
+// remove records of a model that belongs to it on delete.
+func ResolveHookStep(app *Application, hook *ModelHook, m *DataModel, step *Action) *HookStep {
+	text := strings.TrimSpace(strings.TrimRight(step.Text, "."))
+	if sm := hookSetRe.FindStringSubmatch(text); sm != nil && hook.Timing == "before" && hook.Event != "delete" {
+		if f := m.FieldFor(sm[1]); f != nil {
+			return &HookStep{Field: f, Value: strings.TrimSpace(sm[2])}
+		}
+		return nil
+	}
+	if rm := hookRemoveRe.FindStringSubmatch(text); rm != nil && hook.Event == "delete" {
+		target := modelNamed(app, rm[1])
+		if target == nil {
+			return nil
+		}
+		for _, rel := range target.Relations {
+			if rel.Kind == "belongs_to" && strings.EqualFold(rel.Target, m.Name) {
+				return &HookStep{Remove: target}
+			}
+		}
+	}
+	return nil
+}
+
+// Quoted reports whether a set step's value is written as a string: it is
+// unless it is a number set on a number field or true or false set on a
+// boolean one.
+func (s *HookStep) Quoted() bool {
+	switch s.Field.Type {
+	case "number", "decimal":
+		_, err := strconv.ParseFloat(s.Value, 64)
+		return err != nil
+	case "boolean":
+		return s.Value != "true" && s.Value != "false"
+	}
+	return true
+}
+
+// HasModelHooks reports whether any hook runs on one of the app's models,
+// so the backends generate them.
+func HasModelHooks(app *Application) bool {
+	for _, h := range app.Hooks {
+		if h.Event != "" && HookModel(app, h) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// HooksOn returns the hooks that run on model m, in declaration order.
+func HooksOn(app *Application, m *DataModel) []*ModelHook {
+	var hooks []*ModelHook
+	for _, h := range app.Hooks {
+		if h.Event != "" && strings.EqualFold(h.Model, m.Name) {
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks
+}
+
 // ── Actions ──
 
 // Action represents a single step or statement in any block.
@@ -172,10 +725,44 @@ type Pipeline struct {
 //	retry      - retry logic
 //	configure  - configuration setting
 type Action struct {
-	Type   string `json:"type"`
-	Text   string `json:"text"`
-	Target string `json:"target,omitempty"` // entity or element being acted upon
-	Value  string `json:"value,omitempty"`  // value or destination
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Target   string `json:"target,omitempty"`   // entity or element being acted upon
+	Value    string `json:"value,omitempty"`    // value or destination
+	Template string `json:"template,omitempty"` // text of show "Hello, {user.name}!"
+}
+
+// TemplatePart is a piece of a show "..." template: literal Text, or a
+// reference to Root (a component prop or the page's data model) and
+// optionally one of its fields.
+type TemplatePart struct {
+	Text  string
+	Root  string
+	Field string
+}
+
+var templateRefRe = regexp.MustCompile(`\{\s*([A-Za-z_]\w*)(?:\.([A-Za-z_]\w*))?\s*\}`)
+
+// ParseTemplate splits a template into text and {root} or {root.field}
+// references. Braces around anything else are text.
+func ParseTemplate(s string) []TemplatePart {
+	var parts []TemplatePart
+	last := 0
+	for _, m := range templateRefRe.FindAllStringSubmatchIndex(s, -1) {
+		if m[0] > last {
+			parts = append(parts, TemplatePart{Text: s[last:m[0]]})
+		}
+		ref := TemplatePart{Root: s[m[2]:m[3]]}
+		if m[4] >= 0 {
+			ref.Field = s[m[4]:m[5]]
+		}
+		parts = append(parts, ref)
+		last = m[1]
+	}
+	if last < len(s) {
+		parts = append(parts, TemplatePart{Text: s[last:]})
+	}
+	return parts
 }
 
 // ── Theme ──
@@ -188,7 +775,90 @@ type Theme struct {
 	Spacing      string            `json:"spacing,omitempty"`       // compact, comfortable, spacious
 	BorderRadius string            `json:"border_radius,omitempty"` // sharp, smooth, rounded, pill
 	DarkMode     bool              `json:"dark_mode,omitempty"`
-	Options      map[string]string `json:"options,omitempty"` // other properties
+	Options      map[string]string `json:"options,omitempty"`     // other properties
+	LocalFonts   bool              `json:"local_fonts,omitempty"` // bundle fonts with the app instead of Google Fonts; set for offline builds
+	Locale       string            `json:"locale,omitempty"`      // BCP 47 tag numbers are formatted for, e.g. "en-US"
+	Currency     string            `json:"currency,omitempty"`    // ISO 4217 code money is shown in; else the locale's
+}
+
+// localeTag matches a BCP 47 language tag such as "en", "en-US", or
+// "zh-Hant-TW".
+var localeTag = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// ValidLocale reports whether tag is a BCP 47 language tag.
+func ValidLocale(tag string) bool {
+	return localeTag.MatchString(tag)
+}
+
+// Locale returns the locale the theme formats numbers for, or "" when it
+// declares none or one that isn't a language tag. Without one, numbers are
+// shown as the API returns them.
+func Locale(app *Application) string {
+	if app.Theme == nil || !ValidLocale(app.Theme.Locale) {
+		return ""
+	}
+	return app.Theme.Locale
+}
+
+// regionCurrencies maps a locale's region to the currency it uses.
+var regionCurrencies = map[string]string{
+	"US": "USD", "GB": "GBP", "CA": "CAD", "AU": "AUD", "NZ": "NZD",
+	"IN": "INR", "JP": "JPY", "CN": "CNY", "KR": "KRW", "SG": "SGD",
+	"HK": "HKD", "CH": "CHF", "SE": "SEK", "NO": "NOK", "DK": "DKK",
+	"PL": "PLN", "BR": "BRL", "MX": "MXN", "ZA": "ZAR", "AE": "AED",
+	"DE": "EUR", "FR": "EUR", "ES": "EUR", "IT": "EUR", "NL": "EUR",
+	"BE": "EUR", "AT": "EUR", "IE": "EUR", "PT": "EUR", "FI": "EUR",
+	"GR": "EUR",
+}
+
+// Currency returns the ISO 4217 code money fields are shown in: the
+// theme's "currency is", else the one the locale's region uses, or "" when
+// neither names one.
+func Currency(app *Application) string {
+	if app.Theme == nil {
+		return ""
+	}
+	if c := strings.ToUpper(strings.TrimSpace(app.Theme.Currency)); len(c) == 3 {
+		return c
+	}
+	parts := strings.Split(Locale(app), "-")
+	return regionCurrencies[strings.ToUpper(parts[len(parts)-1])]
+}
+
+// Number formats a numeric field is shown in.
+const (
+	NumberMoney   = "money"
+	NumberPercent = "percent"
+	NumberCompact = "compact"
+	NumberPlain   = "number"
+)
+
+// NumberFormat returns how a numeric field is shown, going by its name:
+// prices and amounts as money, percentages (holding whole percents, so 45
+// is 45%) as percentages, counts compactly (1.2K), and any other number
+// with the locale's grouping. It returns "" for a field that isn't a
+// number.
+func NumberFormat(f *DataField) string {
+	if f == nil || (f.Type != "number" && f.Type != "decimal") {
+		return ""
+	}
+	name := strings.ToLower(f.Name)
+	for _, w := range []string{"price", "cost", "amount", "salary", "fee", "balance", "revenue", "budget", "income", "expense", "payment", "subtotal"} {
+		if strings.Contains(name, w) {
+			return NumberMoney
+		}
+	}
+	for _, w := range []string{"percent", "pct"} {
+		if strings.Contains(name, w) {
+			return NumberPercent
+		}
+	}
+	for _, w := range []string{"count", "views", "followers", "likes", "downloads", "subscribers", "visits"} {
+		if strings.Contains(name, w) {
+			return NumberCompact
+		}
+	}
+	return NumberPlain
 }
 
 // ── Security ──
@@ -196,14 +866,15 @@ type Theme struct {
 // Auth holds authentication and security configuration.
 type Auth struct {
 	Methods []*AuthMethod `json:"methods,omitempty"`
-	Rules   []*Action     `json:"rules,omitempty"` // rate limiting, CORS, sanitization, etc.
+	Rules   []*Action     `json:"rules,omitempty"`    // rate limiting, CORS, sanitization, etc.
+	APIKeys *APIKeys      `json:"api_keys,omitempty"` // "external clients authenticate with API keys"
 }
 
 // AuthMethod is a specific authentication approach.
 type AuthMethod struct {
 	Type     string            `json:"type"`               // jwt, oauth
-	Provider string            `json:"provider,omitempty"`  // for OAuth: google, github, etc.
-	Config   map[string]string `json:"config,omitempty"`    // expiration, callback_url, etc.
+	Provider string            `json:"provider,omitempty"` // for OAuth: google, github, etc.
+	Config   map[string]string `json:"config,omitempty"`   // expiration, callback_url, etc.
 }
 
 // ── Database ──
@@ -213,6 +884,81 @@ type DatabaseConfig struct {
 	Engine  string    `json:"engine,omitempty"` // PostgreSQL, MySQL, etc.
 	Indexes []*Index  `json:"indexes,omitempty"`
 	Rules   []*Action `json:"rules,omitempty"` // backup, retention, startup tasks
+	Backup  *Backup   `json:"backup,omitempty"`
+
+	ReadReplica bool `json:"read_replica,omitempty"` // "use a read replica for heavy queries": reads go to DATABASE_REPLICA_URL
+	PoolSize    int  `json:"pool_size,omitempty"`    // "pool up to 20 connections": connections per backend process, pooled through PgBouncer
+}
+
+// Backup is the schedule and retention from a database block's "backup
+// daily at 3am" and "keep backups for 30 days" rules. Times are UTC.
+type Backup struct {
+	Schedule      string `json:"schedule,omitempty"`       // cron expression, e.g. "0 3 * * *"; empty when the rule isn't understood
+	Description   string `json:"description"`              // the rule as written, e.g. "daily at 3 am"
+	RetentionDays int    `json:"retention_days,omitempty"` // 0 keeps backups forever
+	Storage       string `json:"storage,omitempty"`        // S3 integration that receives a copy of each backup, e.g. "AWS S3"
+}
+
+// DefaultBackupSchedule is used when a backup rule's schedule isn't
+// understood: daily at 3am UTC.
+const DefaultBackupSchedule = "0 3 * * *"
+
+// BacksUpDatabase reports whether the app has backup rules for a PostgreSQL
+// database, the only engine backups are generated for.
+func BacksUpDatabase(app *Application) bool {
+	if app.Database == nil || app.Database.Backup == nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(DatabaseEngine(app)), "postgres")
+}
+
+// PoolsConnections reports whether the app pools connections to a
+// PostgreSQL database through PgBouncer.
+func PoolsConnections(app *Application) bool {
+	if app.Database == nil || app.Database.PoolSize == 0 {
+		return false
+	}
+	return strings.Contains(strings.ToLower(DatabaseEngine(app)), "postgres")
+}
+
+// UsesReadReplica reports whether the backend sends reads to a read replica.
+// MongoDB drivers pick replica set members themselves, so it has none.
+func UsesReadReplica(app *Application) bool {
+	return app.Database != nil && app.Database.ReadReplica && !UsesMongoDB(app)
+}
+
+// UsesMongoDB reports whether the app's database is MongoDB. Only the Node
+// backend stores data in it, through Prisma's MongoDB connector.
+func UsesMongoDB(app *Application) bool {
+	return strings.Contains(strings.ToLower(DatabaseEngine(app)), "mongo")
+}
+
+// UsesPostgres reports whether the app's database is PostgreSQL, which
+// it is unless it names MongoDB, MySQL, or SQLite. The backends generate
+// schema migrations for PostgreSQL.
+func UsesPostgres(app *Application) bool {
+	engine := strings.ToLower(DatabaseEngine(app))
+	return !strings.Contains(engine, "mongo") && !strings.Contains(engine, "mysql") && !strings.Contains(engine, "sqlite")
+}
+
+// DatabaseEngine returns the database engine, from the build block or the
+// database block's "use" statement.
+func DatabaseEngine(app *Application) string {
+	if app.Config != nil && app.Config.Database != "" {
+		return app.Config.Database
+	}
+	if app.Database != nil {
+		return app.Database.Engine
+	}
+	return ""
+}
+
+// Cron returns the backup's cron schedule, or DefaultBackupSchedule.
+func (b *Backup) Cron() string {
+	if b.Schedule == "" {
+		return DefaultBackupSchedule
+	}
+	return b.Schedule
 }
 
 // Index is a database index definition.
@@ -226,13 +972,35 @@ type Index struct {
 // Integration represents a third-party service connection.
 type Integration struct {
 	Service     string            `json:"service"`
-	Type        string            `json:"type,omitempty"`        // email, storage, payment, messaging, oauth
+	Type        string            `json:"type,omitempty"`        // email, storage, payment, messaging, sms, oauth, analytics
 	Credentials map[string]string `json:"credentials,omitempty"` // env var mappings
 	Config      map[string]string `json:"config,omitempty"`      // region, sender_email, bucket, webhook_endpoint, channel
-	Templates   []string          `json:"templates,omitempty"`   // email template names
+	Templates   []string          `json:"templates,omitempty"`   // email or SMS/WhatsApp template names
 	Purpose     string            `json:"purpose,omitempty"`
 }
 
+// EmailIntegration returns the integration the app sends email through,
+// or nil.
+func EmailIntegration(app *Application) *Integration {
+	for _, integ := range app.Integrations {
+		if integ.Type == "email" {
+			return integ
+		}
+	}
+	return nil
+}
+
+// PaymentIntegration returns the app's payment integration, e.g. Stripe,
+// or nil.
+func PaymentIntegration(app *Application) *Integration {
+	for _, integ := range app.Integrations {
+		if integ.Type == "payment" {
+			return integ
+		}
+	}
+	return nil
+}
+
 // InferIntegrationType returns the integration type based on service name.
 func InferIntegrationType(service string) string {
 	s := strings.ToLower(service)
@@ -248,8 +1016,13 @@ func InferIntegrationType(service string) string {
 		strings.Contains(s, "braintree") || strings.Contains(s, "square"):
 		return "payment"
 	case strings.Contains(s, "slack") || strings.Contains(s, "discord") ||
-		strings.Contains(s, "twilio") || strings.Contains(s, "telegram"):
+		strings.Contains(s, "telegram"):
 		return "messaging"
+	case strings.Contains(s, "twilio"):
+		return "sms"
+	case strings.Contains(s, "posthog") || strings.Contains(s, "segment") ||
+		strings.Contains(s, "mixpanel") || strings.Contains(s, "amplitude"):
+		return "analytics"
 	case strings.Contains(s, "google") || strings.Contains(s, "github") ||
 		strings.Contains(s, "facebook") || strings.Contains(s, "auth0") ||
 		strings.Contains(s, "okta"):
@@ -259,6 +1032,85 @@ func InferIntegrationType(service string) string {
 	}
 }
 
+// SMSMessage is a "send SMS ..." or "send WhatsApp ..." step.
+type SMSMessage struct {
+	Channel  string // sms, whatsapp
+	Template string // approved template name, when the step names one
+	Body     string // quoted message text, when the step has one
+}
+
+// ParseSMSStep reports whether a send step texts a phone. The parser drops
+// quotes, so the message is whatever follows the channel or "saying":
+//
+//	send an SMS "Your order has shipped"
+//	send a text message to the driver saying "Pickup is ready"
+//	send WhatsApp message with template "order-shipped"
+func ParseSMSStep(text string) (*SMSMessage, bool) {
+	lower := strings.ToLower(text)
+	msg := &SMSMessage{Channel: "sms"}
+	keyword := ""
+	for _, kw := range []string{"whatsapp message", "whatsapp", "text message", "sms"} {
+		if strings.Contains(lower, kw) {
+			keyword = kw
+			break
+		}
+	}
+	if keyword == "" {
+		return nil, false
+	}
+	if strings.HasPrefix(keyword, "whatsapp") {
+		msg.Channel = "whatsapp"
+	}
+
+	if idx := strings.Index(lower, "template "); idx != -1 {
+		msg.Template = strings.Trim(strings.TrimSpace(text[idx+len("template "):]), `"`)
+		return msg, true
+	}
+	rest := ""
+	if idx := strings.Index(lower, " saying "); idx != -1 {
+		rest = text[idx+len(" saying "):]
+	} else {
+		idx := strings.Index(lower, keyword) + len(keyword)
+		rest = text[idx:]
+		if restLower := strings.ToLower(strings.TrimSpace(rest)); strings.HasPrefix(restLower, "to ") {
+			rest = ""
+		}
+	}
+	msg.Body = strings.Trim(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ":")), `"`)
+	return msg, true
+}
+
+// PhoneParam returns the endpoint param holding the recipient's phone
+// number, or "" if there is none.
+func PhoneParam(ep *Endpoint) string {
+	for _, p := range ep.Params {
+		switch strings.ToLower(p.Name) {
+		case "phone", "phone_number", "phonenumber", "mobile", "to":
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// SMSRetryPolicy returns how many times to attempt an outgoing SMS or
+// WhatsApp message and the delay between attempts, taken from the retry
+// step of an error handler about messages ("if sending an SMS fails:
+// retry 3 times with 2 second delay"). Without one, a message is tried
+// 3 times one second apart.
+func SMSRetryPolicy(app *Application) (attempts, delayMs int) {
+	for _, eh := range app.ErrorHandlers {
+		cond := strings.ToLower(eh.Condition)
+		if !strings.Contains(cond, "sms") && !strings.Contains(cond, "whatsapp") &&
+			!strings.Contains(cond, "twilio") && !strings.Contains(cond, "text message") {
+			continue
+		}
+		if attempts, delayMs, ok := eh.RetryPolicy(); ok {
+			return attempts, delayMs
+		}
+	}
+	return 3, 1000
+}
+
 // ── Deployment ──
 
 // Environment represents a deployment environment.
@@ -268,6 +1120,114 @@ type Environment struct {
 	Rules  []*Action         `json:"rules,omitempty"`
 }
 
+// URL returns the URL from the environment's "url is app.example.com"
+// line, with https unless the line gives a scheme, or "" without one. The
+// parser keeps the URL as written; a url rebuilt from tokens has its host
+// split on the dots, so they are put back, and a port kept with its colon.
+func (e *Environment) URL() string {
+	raw := strings.TrimSpace(e.Config["url"])
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, " ") {
+		raw = strings.TrimSuffix(raw, "/")
+		if strings.Contains(raw, "://") {
+			return raw
+		}
+		return "https://" + raw
+	}
+
+	words := strings.Fields(raw)
+	scheme := "https"
+	if words[0] == "http:" || words[0] == "https:" {
+		scheme = strings.TrimSuffix(words[0], ":")
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(scheme + "://")
+	for i, w := range words {
+		if i > 0 && !strings.HasSuffix(words[i-1], ":") {
+			b.WriteByte('.')
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// Host returns the host name of the environment's URL, or "".
+func (e *Environment) Host() string {
+	u := e.URL()
+	if u == "" {
+		return ""
+	}
+	host := u[strings.Index(u, "://")+3:]
+	if i := strings.IndexAny(host, "/:"); i >= 0 {
+		host = host[:i]
+	}
+	return strings.ToLower(host)
+}
+
+// PublicEnvironment returns the production environment if it has a URL,
+// else the first environment that does, else nil.
+func PublicEnvironment(app *Application) *Environment {
+	var first *Environment
+	for _, env := range app.Environments {
+		if env.Host() == "" {
+			continue
+		}
+		if name := strings.ToLower(env.Name); name == "production" || name == "prod" {
+			return env
+		}
+		if first == nil {
+			first = env
+		}
+	}
+	return first
+}
+
+// TargetEnvironment returns the environment the build targets with
+// `human build --env`, or nil for a local build.
+func TargetEnvironment(app *Application) *Environment {
+	if app.Config == nil || app.Config.Env == "" {
+		return nil
+	}
+	for _, env := range app.Environments {
+		if strings.EqualFold(env.Name, app.Config.Env) {
+			return env
+		}
+	}
+	return nil
+}
+
+// APIURL returns the origin the environment serves the backend at. AWS and
+// GCP serve an app with a frontend at its URL and the API at api.<url>;
+// elsewhere the frontend proxies /api, so the API shares its URL.
+func (e *Environment) APIURL(app *Application) string {
+	u := e.URL()
+	if u == "" || app.Config == nil || (app.Config.Frontend == "" && len(app.Pages) == 0) {
+		return u
+	}
+	deploy := strings.ToLower(app.Config.Deploy)
+	if strings.Contains(deploy, "aws") || strings.Contains(deploy, "gcp") || strings.Contains(deploy, "google") {
+		i := strings.Index(u, "://") + 3
+		return u[:i] + "api." + u[i:]
+	}
+	return u
+}
+
+// AllowedOrigin returns the origin the backend accepts cross-origin
+// requests from: the URL of the environment the build targets, or "" for
+// a local build, which accepts any.
+func AllowedOrigin(app *Application) string {
+	if env := TargetEnvironment(app); env != nil {
+		return env.URL()
+	}
+	return ""
+}
+
 // ── Error Handling ──
 
 // ErrorHandler represents error recovery logic.
@@ -276,6 +1236,112 @@ type ErrorHandler struct {
 	Steps     []*Action `json:"steps,omitempty"`
 }
 
+// RetryPolicy returns how many times to attempt what failed and the delay
+// between attempts, from the handler's retry step ("retry 3 times with 2
+// second delay"). Parts the step leaves out are 3 attempts one second
+// apart; ok is false without a retry step.
+func (eh *ErrorHandler) RetryPolicy() (attempts, delayMs int, ok bool) {
+	attempts, delayMs = 3, 1000
+	for _, step := range eh.Steps {
+		if step.Type != "retry" {
+			continue
+		}
+		words := strings.Fields(strings.ToLower(step.Text))
+		for i, w := range words {
+			n, err := strconv.Atoi(w)
+			if err != nil || n <= 0 || i+1 >= len(words) {
+				continue
+			}
+			switch unit := words[i+1]; {
+			case strings.HasPrefix(unit, "time"):
+				attempts = n
+			case strings.HasPrefix(unit, "second"):
+				delayMs = n * 1000
+			case strings.HasPrefix(unit, "millisecond"), unit == "ms":
+				delayMs = n
+			}
+		}
+		return attempts, delayMs, true
+	}
+	return attempts, delayMs, false
+}
+
+// Environment variables the generated backends read chaos mode from, which
+// `human run --chaos` sets.
+const (
+	ChaosEnv        = "HUMAN_CHAOS"            // "1" turns chaos mode on
+	ChaosRateEnv    = "HUMAN_CHAOS_RATE"       // share of API requests hit, 0.1 by default
+	ChaosLatencyEnv = "HUMAN_CHAOS_LATENCY_MS" // most latency added to a request hit, 2000 by default
+)
+
+// RecordEnv is the environment variable the generated backends read the
+// directory to record API requests in from, which `human run --record`
+// sets to .human/debug. Each request and the response it got is written
+// there as an .http file (VS Code REST Client format) that `human replay`
+// sends again. Nothing is recorded without it.
+const RecordEnv = "HUMAN_RECORD_DIR"
+
+// ChaosFault is a failure chaos mode injects into API requests: the one an
+// error handler is declared for. A request hit fails some attempts in a
+// row; it goes through if it succeeds within the handler's attempts, and
+// otherwise is answered the way the handler says.
+type ChaosFault struct {
+	Condition string // the handler's condition, "database is unreachable"
+	Attempts  int    // from the retry step; 1 without one
+	DelayMs   int    // between attempts
+	Status    int    // the HTTP status a request still failing is answered with
+	Message   string // its error: the handler's "if still failing, respond with ...", or the status's
+	Alert     string // the handler's alert step, logged when a request still fails
+}
+
+// InjectsChaos reports whether the backends are generated with chaos mode:
+// apps that declare error handlers, whose failures it injects.
+func InjectsChaos(app *Application) bool {
+	return len(app.ErrorHandlers) > 0
+}
+
+// ChaosFaults returns the failure each error handler is declared for. The
+// status follows the condition's wording: validation failures are 400s,
+// something unreachable or unavailable a 503, a timeout a 504, and
+// anything else a 500.
+func ChaosFaults(app *Application) []ChaosFault {
+	var faults []ChaosFault
+	for _, eh := range app.ErrorHandlers {
+		f := ChaosFault{Condition: eh.Condition, Attempts: 1}
+		if attempts, delayMs, ok := eh.RetryPolicy(); ok {
+			f.Attempts, f.DelayMs = attempts, delayMs
+		}
+		cond := strings.ToLower(eh.Condition)
+		switch {
+		case strings.Contains(cond, "validation") || strings.Contains(cond, "invalid"):
+			f.Status, f.Message = 400, "The request is invalid."
+		case strings.Contains(cond, "time out") || strings.Contains(cond, "times out") ||
+			strings.Contains(cond, "timed out") || strings.Contains(cond, "timeout"):
+			f.Status, f.Message = 504, "The request timed out. Please try again."
+		case strings.Contains(cond, "unreachable") || strings.Contains(cond, "unavailable") ||
+			strings.Contains(cond, "down") || strings.Contains(cond, "offline") || strings.Contains(cond, "connect"):
+			f.Status, f.Message = 503, "Service temporarily unavailable. Please try again."
+		default:
+			f.Status, f.Message = 500, "An unexpected error occurred. Please try again later."
+		}
+		for _, step := range eh.Steps {
+			lower := strings.ToLower(step.Text)
+			switch {
+			case step.Type == "alert" && f.Alert == "":
+				f.Alert = step.Text
+			case step.Type == "condition" && strings.Contains(lower, "still fail"):
+				if i := strings.Index(lower, "respond with "); i >= 0 {
+					if msg := strings.TrimSpace(step.Text[i+len("respond with "):]); msg != "" {
+						f.Message = strings.ToUpper(msg[:1]) + msg[1:]
+					}
+				}
+			}
+		}
+		faults = append(faults, f)
+	}
+	return faults
+}
+
 // ── Architecture ──
 
 // Architecture describes the application's architectural style.
@@ -289,11 +1355,11 @@ type Architecture struct {
 // ServiceDef defines a microservice.
 type ServiceDef struct {
 	Name           string   `json:"name"`
-	Handles        string   `json:"handles,omitempty"`         // responsibility description
+	Handles        string   `json:"handles,omitempty"` // responsibility description
 	Port           int      `json:"port,omitempty"`
-	Models         []string `json:"models,omitempty"`          // data model names this service owns
+	Models         []string `json:"models,omitempty"` // data model names this service owns
 	HasOwnDatabase bool     `json:"has_own_database,omitempty"`
-	TalksTo        []string `json:"talks_to,omitempty"`        // other services it communicates with
+	TalksTo        []string `json:"talks_to,omitempty"` // other services it communicates with
 }
 
 // GatewayDef defines an API gateway for microservices.
@@ -313,3 +1379,2758 @@ type MonitoringRule struct {
 	Service   string `json:"service,omitempty"`   // log destination (e.g., "CloudWatch")
 	Duration  string `json:"duration,omitempty"`  // retention duration
 }
+
+// ── Experiments ──
+
+// Experiment is an A/B test that splits traffic between variant pages.
+// The first variant is the control; its page's route serves the experiment.
+type Experiment struct {
+	Name     string               `json:"name"`               // e.g. "pricing-page"
+	Audience string               `json:"audience,omitempty"` // who is bucketed, e.g. "visitors"
+	Variants []*ExperimentVariant `json:"variants"`
+}
+
+// ExperimentVariant is one arm of an experiment.
+type ExperimentVariant struct {
+	Page   string `json:"page"`   // page rendered for this variant
+	Weight int    `json:"weight"` // percentage of traffic, 0–100
+}
+
+// ── Notifications ──
+
+// Notification is an in-app notification rule: when a record event
+// happens, a Notification row is stored for the user and surfaced in the
+// frontend's notifications dropdown.
+type Notification struct {
+	Model   string `json:"model"`   // data model whose event triggers it, e.g. "Task"
+	Event   string `json:"event"`   // past-tense event, e.g. "assigned"
+	Message string `json:"message"` // text shown to the user
+}
+
+// Triggers reports whether an endpoint performs the notification's event:
+// its name names the model, and its name or one of its steps names the
+// event ("assigned" matches AssignTask).
+func (n *Notification) Triggers(ep *Endpoint) bool {
+	name := strings.ToLower(ep.Name)
+	if !strings.Contains(name, strings.ToLower(strings.ReplaceAll(n.Model, " ", ""))) {
+		return false
+	}
+	stem := eventStem(n.Event)
+	if strings.Contains(name, stem) {
+		return true
+	}
+	for _, step := range ep.Steps {
+		if step.Type != "send" && strings.Contains(strings.ToLower(step.Text), stem) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecipientParam returns the endpoint param naming the user to notify
+// (user_id, assignee, recipient_id, ...), or "" when the record's owner —
+// or failing that the current user — should be notified.
+func (n *Notification) RecipientParam(ep *Endpoint) string {
+	for _, p := range ep.Params {
+		switch strings.ToLower(strings.ReplaceAll(p.Name, " ", "_")) {
+		case "user_id", "userid", "assignee", "assignee_id", "recipient", "recipient_id":
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// eventStem reduces a past-tense event to the stem shared with its verb:
+// "assigned" → "assign", "created" → "creat", "shipped" → "ship".
+func eventStem(event string) string {
+	stem := strings.ToLower(event)
+	switch {
+	case strings.HasSuffix(stem, "ied"):
+		stem = stem[:len(stem)-3]
+	case strings.HasSuffix(stem, "ed"):
+		stem = stem[:len(stem)-2]
+		if n := len(stem); n > 2 && stem[n-1] == stem[n-2] && !strings.ContainsRune("aeiou", rune(stem[n-1])) {
+			stem = stem[:n-1]
+		}
+	}
+	return stem
+}
+
+// ── Calendars ──
+
+// CalendarFeed publishes a data model's records as calendar events: an ICS
+// feed of every record plus a per-record .ics download. Field names refer
+// to the model's fields; End, Location, and Description may be empty.
+type CalendarFeed struct {
+	Model       string `json:"model"`                 // e.g. "Event"
+	Slug        string `json:"slug"`                  // URL segment, e.g. "events"
+	Title       string `json:"title,omitempty"`       // field used as the event summary
+	Start       string `json:"start,omitempty"`       // date or datetime field the event starts at
+	End         string `json:"end,omitempty"`         // field the event ends at; events last an hour without one
+	AllDay      bool   `json:"all_day,omitempty"`     // start is a date, not a datetime
+	Location    string `json:"location,omitempty"`    // field used as the event location
+	Description string `json:"description,omitempty"` // field used as the event description
+	Timezone    string `json:"timezone"`              // IANA zone for times stored without one, default "UTC"
+}
+
+// Name returns the calendar's display name, from its slug:
+// "events" → "Events", "class-sessions" → "Class Sessions".
+func (c *CalendarFeed) Name() string {
+	words := strings.Split(c.Slug, "-")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// CalendarFor returns the calendar publishing a model's records, or nil.
+func CalendarFor(app *Application, model string) *CalendarFeed {
+	if app == nil || model == "" {
+		return nil
+	}
+	for _, cal := range app.Calendars {
+		if strings.EqualFold(cal.Model, model) {
+			return cal
+		}
+	}
+	return nil
+}
+
+// ── Tables ──
+
+// Table is a page's "show users in a table" or "show a table of users": the
+// records the page loads, a row each. Columns come from an "each row shows
+// ..." loop, or are all the model's fields, plus the field the rows are
+// sorted by. Columns named by a sort modifier
+// ("sorted by date descending", "support sorting by name or role") sort the
+// rows when their header is clicked, and a "clicking a user navigates to
+// UserDetail" interaction opens that page from a row.
+type Table struct {
+	Model    *DataModel
+	Columns  []*DataField
+	Sortable []*DataField // columns whose header sorts the rows
+	SortBy   *DataField   // initial order, from "sorted by"; nil keeps the API's order
+	Desc     bool
+	RowPage  string  // page a row click navigates to
+	Show     *Action // the show action the table renders
+
+	covers []*Action // the loops and row click the table renders instead
+}
+
+// IsTable reports whether a display action shows data in a table.
+func IsTable(a *Action) bool {
+	lower := strings.ToLower(a.Text)
+	return a.Type == "display" && a.Template == "" &&
+		(strings.Contains(lower, "in a table") || strings.Contains(lower, "table of"))
+}
+
+// TableModel returns the model a table shows: the first word of its text
+// naming one, singular or plural ("show a table of all users in the team"
+// shows users), or nil.
+func TableModel(app *Application, a *Action) *DataModel {
+	for _, word := range strings.Fields(strings.ToLower(a.Text)) {
+		if m := modelNamed(app, word); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// modelNamed returns the model a word of prose names, singular or plural
+// ("user", "users", "categories"), or nil.
+func modelNamed(app *Application, word string) *DataModel {
+	word = strings.ToLower(strings.Trim(word, ".,:;'\""))
+	for _, m := range app.Data {
+		name := strings.ToLower(m.Name)
+		if word == name || word == name+"s" || word == name+"es" ||
+			strings.HasSuffix(name, "y") && word == name[:len(name)-1]+"ies" {
+			return m
+		}
+	}
+	return nil
+}
+
+// TableFor returns the table a page shows the records of model in, or nil.
+func TableFor(app *Application, page *Page, model string) *Table {
+	if app == nil || page == nil {
+		return nil
+	}
+	var m *DataModel
+	for _, dm := range app.Data {
+		if strings.EqualFold(dm.Name, model) {
+			m = dm
+		}
+	}
+	if m == nil {
+		return nil
+	}
+	t := &Table{Model: m}
+	for _, a := range page.Content {
+		if IsTable(a) {
+			t.Show = a
+			break
+		}
+	}
+	if t.Show == nil {
+		return nil
+	}
+
+	var sortable []*DataField
+	for _, a := range page.Content {
+		lower := strings.ToLower(a.Text)
+		switch a.Type {
+		case "loop":
+			for _, marker := range []string{"shows its ", "shows the ", "shows "} {
+				if i := strings.Index(lower, marker); i >= 0 {
+					t.Columns = append(t.Columns, m.fieldsIn(lower[i+len(marker):])...)
+					t.covers = append(t.covers, a)
+					break
+				}
+			}
+		case "interact":
+			if target := rowClickTarget(app, m, lower); target != "" && t.RowPage == "" {
+				t.RowPage = target
+				t.covers = append(t.covers, a)
+			}
+		}
+		if i := strings.Index(lower, "sorting by "); i >= 0 {
+			sortable = append(sortable, m.fieldsIn(lower[i+len("sorting by "):])...)
+		}
+		if i := strings.Index(lower, "sorted by "); i >= 0 && t.SortBy == nil {
+			rest := lower[i+len("sorted by "):]
+			for _, dir := range []string{" descending", " desc", " newest first", " latest first", " most recent first", " highest first"} {
+				if j := strings.Index(rest, dir); j >= 0 {
+					rest, t.Desc = rest[:j], true
+				}
+			}
+			for _, dir := range []string{" ascending", " asc", " oldest first", " lowest first"} {
+				if j := strings.Index(rest, dir); j >= 0 {
+					rest = rest[:j]
+				}
+			}
+			if t.SortBy = m.FieldFor(rest); t.SortBy != nil {
+				sortable = append(sortable, t.SortBy)
+			}
+		}
+	}
+
+	if len(t.Columns) == 0 {
+		for _, f := range m.Fields {
+			if !strings.Contains(strings.ToLower(f.Name), "password") {
+				t.Columns = append(t.Columns, f)
+			}
+		}
+	}
+	if t.SortBy != nil {
+		t.Columns = append(t.Columns, t.SortBy) // rows sorted by a field show it
+	}
+	t.Columns = uniqueFields(t.Columns)
+	for _, f := range uniqueFields(sortable) {
+		for _, c := range t.Columns {
+			if c == f {
+				t.Sortable = append(t.Sortable, f)
+			}
+		}
+	}
+	return t
+}
+
+// Covers reports whether the table renders a, a loop naming its columns or
+// the interaction its rows navigate with, in place of the page.
+func (t *Table) Covers(a *Action) bool {
+	for _, c := range t.covers {
+		if c == a {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSortable reports whether clicking f's header sorts the table.
+func (t *Table) IsSortable(f *DataField) bool {
+	for _, s := range t.Sortable {
+		if s == f {
+			return true
+		}
+	}
+	return false
+}
+
+// rowClickTarget returns the page "clicking a user navigates to UserDetail"
+// (or "opens UserDetail") goes to, if the clicked thing is a row of m.
+func rowClickTarget(app *Application, m *DataModel, lower string) string {
+	name := strings.ToLower(m.Name)
+	clicked := false
+	for _, prefix := range []string{"clicking a ", "clicking an ", "clicking the ", "clicking "} {
+		if rest, ok := strings.CutPrefix(lower, prefix); ok {
+			word, _, _ := strings.Cut(rest, " ")
+			clicked = word == "row" || word == name || word == name+"s"
+			break
+		}
+	}
+	if !clicked {
+		return ""
+	}
+	for _, marker := range []string{"navigates to ", "opens "} {
+		if i := strings.Index(lower, marker); i >= 0 {
+			word, _, _ := strings.Cut(lower[i+len(marker):], " ")
+			for _, p := range app.Pages {
+				if strings.EqualFold(p.Name, strings.Trim(word, ".,")) {
+					return p.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// FieldFor resolves a field named in prose ("due date", "the user name",
+// "last active date") to one of the model's fields, or nil. A bare "date",
+// "time", or "timestamp" is the first date or datetime field.
+func (m *DataModel) FieldFor(phrase string) *DataField {
+	phrase = strings.TrimSpace(strings.ToLower(phrase))
+	for _, prefix := range []string{"the ", "its ", "their "} {
+		phrase = strings.TrimPrefix(phrase, prefix)
+	}
+	if phrase == "" {
+		return nil
+	}
+	if f := m.FieldNamed(strings.ReplaceAll(phrase, " ", "")); f != nil {
+		return f
+	}
+	for _, f := range m.Fields {
+		if strings.ToLower(f.Name+" "+f.Type) == phrase {
+			return f
+		}
+	}
+	squashed := strings.NewReplacer(" ", "", "_", "").Replace(phrase)
+	var best *DataField
+	for _, f := range m.Fields {
+		name := strings.ToLower(strings.ReplaceAll(f.Name, "_", ""))
+		if strings.Contains(squashed, name) && (best == nil || len(f.Name) > len(best.Name)) {
+			best = f
+		}
+	}
+	if best == nil && (phrase == "date" || phrase == "time" || phrase == "timestamp") {
+		for _, f := range m.Fields {
+			if f.Type == "date" || f.Type == "datetime" {
+				return f
+			}
+		}
+	}
+	return best
+}
+
+// fieldsIn resolves a list of fields in prose, "name, role, or last active
+// date", skipping names that aren't fields.
+func (m *DataModel) fieldsIn(list string) []*DataField {
+	list = strings.NewReplacer(" and ", ", ", " or ", ", ").Replace(list)
+	var fields []*DataField
+	for _, part := range strings.Split(list, ",") {
+		if f := m.FieldFor(part); f != nil {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func uniqueFields(fields []*DataField) []*DataField {
+	seen := map[*DataField]bool{}
+	var out []*DataField
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// ── Reordering and Keyboard Shortcuts ──
+
+// Reorder is a list a "dragging a task reorders the list" interaction lets
+// users rearrange. Backends serve PUT /api/reorder/<slug>, which numbers
+// Field from 0 in the order of the ids it is sent, and list the model's
+// records in that order.
+type Reorder struct {
+	Model string `json:"model"` // e.g. "Task"
+	Field string `json:"field"` // number field holding each record's position
+	Slug  string `json:"slug"`  // URL segment, e.g. "tasks"
+}
+
+// positionFields are the names of a number field that already orders a
+// model's records, so dragging them persists there.
+var positionFields = []string{"position", "order", "sort_order", "rank", "sequence"}
+
+// IsReorder reports whether an interaction lets users drag records into a
+// new order.
+func IsReorder(a *Action) bool {
+	lower := strings.ToLower(a.Text)
+	return a.Type == "interact" && strings.HasPrefix(lower, "dragging ") &&
+		(strings.Contains(lower, "reorder") || strings.Contains(lower, "rearrange"))
+}
+
+// ReorderModel returns the model whose records a "dragging a task reorders
+// the list" interaction drags: the first word after "dragging" naming one,
+// or nil.
+func ReorderModel(app *Application, a *Action) *DataModel {
+	lower := strings.ToLower(a.Text)
+	subject := strings.TrimPrefix(lower, "dragging ")
+	for _, verb := range []string{" reorder", " rearrange", " to "} {
+		if i := strings.Index(subject, verb); i >= 0 {
+			subject = subject[:i]
+		}
+	}
+	for _, word := range strings.Fields(subject) {
+		if m := modelNamed(app, word); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// ReorderFor returns the reorderable list of a model's records, or nil.
+func ReorderFor(app *Application, model string) *Reorder {
+	if app == nil || model == "" {
+		return nil
+	}
+	for _, r := range app.Reorders {
+		if strings.EqualFold(r.Model, model) {
+			return r
+		}
+	}
+	return nil
+}
+
+// positionField returns the number field that orders a model's records,
+// or nil.
+func positionField(m *DataModel) *DataField {
+	for _, name := range positionFields {
+		if f := m.FieldNamed(name); f != nil && f.Type == "number" {
+			return f
+		}
+	}
+	return nil
+}
+
+// KeyBinding is a page's "pressing Escape closes the modal": a key, with
+// any modifiers, and what it does.
+type KeyBinding struct {
+	Key    string // KeyboardEvent.key, e.g. "Escape", "k", "/"
+	Ctrl   bool   // Ctrl, or Cmd on a Mac
+	Shift  bool
+	Alt    bool
+	Action string // "close", "open-form", "navigate", "focus-search", or "submit"
+	Target string // page a "navigate" binding opens
+}
+
+// namedKeys maps the words a key may be written as to KeyboardEvent.key.
+// Symbols are written by name, since the lexer drops punctuation.
+var namedKeys = map[string]string{
+	"escape": "Escape", "esc": "Escape",
+	"enter": "Enter", "return": "Enter",
+	"space": " ", "spacebar": " ",
+	"tab": "Tab", "delete": "Delete", "backspace": "Backspace",
+	"up": "ArrowUp", "down": "ArrowDown", "left": "ArrowLeft", "right": "ArrowRight",
+	"slash": "/", "question": "?",
+}
+
+// keyModifiers maps modifier words to the KeyBinding flag they set.
+var keyModifiers = map[string]string{
+	"ctrl": "ctrl", "control": "ctrl", "cmd": "ctrl", "command": "ctrl", "meta": "ctrl",
+	"shift": "shift", "alt": "alt", "option": "alt",
+}
+
+// ParseKeyBinding reads a "pressing <key> <does something>" interaction:
+//
+//	pressing Escape closes the modal     → Escape closes the page's form modal
+//	pressing N opens the form            → n opens it
+//	pressing slash focuses the search    → / focuses the search box
+//	pressing Ctrl K navigates to Search  → Ctrl+K (Cmd+K on a Mac) opens Search
+//	pressing Ctrl Enter submits the form → Ctrl+Enter submits the page's form
+//
+// It reports false when the text names no key or an action it doesn't
+// understand.
+func ParseKeyBinding(text string) (*KeyBinding, bool) {
+	words := strings.Fields(strings.ToLower(strings.NewReplacer("+", " ", "-", " ").Replace(text)))
+	if len(words) < 3 || words[0] != "pressing" {
+		return nil, false
+	}
+	kb := &KeyBinding{}
+	i := 1
+	for i < len(words) && kb.Key == "" {
+		w := words[i]
+		i++
+		switch mod := keyModifiers[w]; {
+		case mod == "ctrl":
+			kb.Ctrl = true
+		case mod == "shift":
+			kb.Shift = true
+		case mod == "alt":
+			kb.Alt = true
+		case namedKeys[w] != "":
+			kb.Key = namedKeys[w]
+		case len([]rune(w)) == 1:
+			kb.Key = w
+		case w == "the":
+		default:
+			return nil, false
+		}
+	}
+	// "the up arrow", "the escape key", "question mark"
+	for i < len(words) && (words[i] == "arrow" || words[i] == "key" || words[i] == "mark") {
+		i++
+	}
+	if kb.Key == "" || i >= len(words) {
+		return nil, false
+	}
+	rest := strings.Join(words[i:], " ")
+	switch {
+	case strings.HasPrefix(rest, "closes") || strings.HasPrefix(rest, "dismisses") || strings.HasPrefix(rest, "hides"):
+		kb.Action = "close"
+	case strings.HasPrefix(rest, "opens") && strings.Contains(rest, "form"):
+		kb.Action = "open-form"
+	case strings.HasPrefix(rest, "focuses") || strings.HasPrefix(rest, "jumps to the search"):
+		kb.Action = "focus-search"
+	case strings.HasPrefix(rest, "submits") || strings.HasPrefix(rest, "saves"):
+		kb.Action = "submit"
+	case strings.HasPrefix(rest, "navigates to ") || strings.HasPrefix(rest, "goes to ") || strings.HasPrefix(rest, "opens "):
+		fields := strings.Fields(text)
+		kb.Action = "navigate"
+		kb.Target = strings.Trim(fields[len(fields)-1], ".,")
+	default:
+		return nil, false
+	}
+	return kb, true
+}
+
+// IsKeyBinding reports whether an action handles a key press.
+func IsKeyBinding(a *Action) bool {
+	return a.Type == "interact" && strings.HasPrefix(strings.ToLower(a.Text), "pressing ")
+}
+
+// PageKeyBindings returns the key bindings a page declares that can be
+// wired up.
+func PageKeyBindings(page *Page) []*KeyBinding {
+	var bindings []*KeyBinding
+	for _, a := range page.Content {
+		if !IsKeyBinding(a) {
+			continue
+		}
+		if kb, ok := ParseKeyBinding(a.Text); ok {
+			bindings = append(bindings, kb)
+		}
+	}
+	return bindings
+}
+
+// ── Pagination and List Updates ──
+
+// DefaultPageSize is how many records a paginated list returns at a time
+// when its API doesn't say.
+const DefaultPageSize = 20
+
+// IsInfiniteScroll reports whether an interaction loads the next page of a
+// list as the user nears its end: "scrolling to bottom loads more posts".
+func IsInfiniteScroll(a *Action) bool {
+	lower := strings.ToLower(a.Text)
+	return a.Type == "interact" && strings.HasPrefix(lower, "scrolling ") && strings.Contains(lower, " more")
+}
+
+// ScrollModel returns the model whose records a "scrolling to bottom loads
+// more posts" interaction loads: the first word after "more" naming one,
+// or nil.
+func ScrollModel(app *Application, a *Action) *DataModel {
+	lower := strings.ToLower(a.Text)
+	i := strings.Index(lower, " more")
+	if i < 0 {
+		return nil
+	}
+	for _, word := range strings.Fields(lower[i+len(" more"):]) {
+		if m := modelNamed(app, word); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// ListEndpoint returns the endpoint that lists a model's records: a List
+// endpoint naming the model, else a Get endpoint naming it, or nil.
+func ListEndpoint(app *Application, model string) *Endpoint {
+	if app == nil || model == "" {
+		return nil
+	}
+	lowerModel := strings.ToLower(model)
+	for _, prefix := range []string{"list", "get"} {
+		for _, ep := range app.APIs {
+			lower := strings.ToLower(ep.Name)
+			if strings.HasPrefix(lower, prefix) && strings.Contains(lower, lowerModel) {
+				return ep
+			}
+		}
+	}
+	return nil
+}
+
+// ListsNewestFirst reports whether an endpoint lists a model's records
+// newest first. Paginated lists do, so the first page holds the latest
+// records, unless users drag the records into an order of their own.
+func ListsNewestFirst(app *Application, ep *Endpoint, model string) bool {
+	return ep != nil && ep.PageSize > 0 && ReorderFor(app, model) == nil
+}
+
+// ── List Query State ──
+
+// ListQuery is the view of a list page its URL's query string holds, so a
+// view can be shared and bookmarked: ?q= for its search, ?<field>= for each
+// filter, ?sort= and ?order= for its table's order, and ?page= for the page
+// of a paginated list. A paginated list is searched, filtered, sorted, and
+// paged by its endpoint, which reads the same parameters (see ListParams);
+// any other list is loaded whole and narrowed in the browser.
+type ListQuery struct {
+	Model    *DataModel
+	Search   bool         // a search input narrows the list
+	Filters  []*DataField // enum and yes/no fields a dropdown narrows the list by
+	Sortable []*DataField // the table's sortable columns
+	SortBy   *DataField   // order before the user picks one; nil keeps the API's
+	Desc     bool
+	Paged    bool // the list's endpoint serves it a page at a time
+}
+
+// ListQueryFor returns the query state of a page listing model's records
+// from ep, or nil when the page neither searches, filters, sorts, nor pages
+// the list. A list that loads more as the user scrolls isn't numbered.
+func ListQueryFor(app *Application, page *Page, model string, ep *Endpoint) *ListQuery {
+	if app == nil || page == nil {
+		return nil
+	}
+	m := modelNamed(app, model)
+	if m == nil {
+		return nil
+	}
+	q := &ListQuery{Model: m, Paged: ep != nil && ep.PageSize > 0 && len(ep.Params) == 0}
+	for _, a := range page.Content {
+		switch {
+		case IsSearchInput(a):
+			q.Search = true
+		case a.Type == "input":
+			if f := FilterField(m, a); f != nil {
+				q.Filters = append(q.Filters, f)
+			}
+		case IsInfiniteScroll(a):
+			if sm := ScrollModel(app, a); sm == m {
+				q.Paged = false
+			}
+		}
+	}
+	q.Filters = uniqueFields(q.Filters)
+	if t := TableFor(app, page, m.Name); t != nil {
+		q.Sortable, q.SortBy, q.Desc = t.Sortable, t.SortBy, t.Desc
+	}
+	if !q.Search && len(q.Filters) == 0 && len(q.Sortable) == 0 && !q.Paged {
+		return nil
+	}
+	return q
+}
+
+// IsRememberView reports whether a page statement asks for its list to be
+// kept as the user left it: "remember the user's filters when they come
+// back".
+func IsRememberView(a *Action) bool {
+	return a.Type == "configure" && strings.HasPrefix(strings.ToLower(a.Text), "remember ")
+}
+
+// RemembersView reports whether a page restores the view of its list when
+// the user comes back to it: the search, filters, sort, and page its URL
+// held, and how far down the list they had scrolled. The view is kept for
+// the browser tab, so a URL naming a view of its own still opens on that.
+func RemembersView(page *Page) bool {
+	for _, a := range page.Content {
+		if IsRememberView(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSearchInput reports whether an input searches the page's list:
+// "there is a search bar that filters posts by title".
+func IsSearchInput(a *Action) bool {
+	return a.Type == "input" && strings.Contains(strings.ToLower(a.Text), "search")
+}
+
+// IsPagination reports whether a display places the controls paging the
+// page's list: "show pagination controls".
+func IsPagination(a *Action) bool {
+	lower := strings.ToLower(a.Text)
+	return a.Type == "display" && (strings.Contains(lower, "pagination") || strings.Contains(lower, "page controls"))
+}
+
+// ShowsPagination reports whether a page places its pagination controls
+// itself rather than below its list.
+func ShowsPagination(page *Page) bool {
+	for _, a := range page.Content {
+		if IsPagination(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterField returns the field of m a dropdown narrows a list by, "there
+// is a dropdown to filter by status", or nil. Only an enum or yes/no field
+// has the options a dropdown offers.
+func FilterField(m *DataModel, a *Action) *DataField {
+	lower := strings.ToLower(a.Text)
+	if a.Type != "input" || IsSearchInput(a) ||
+		!strings.Contains(lower, "dropdown") && !strings.Contains(lower, "select") && !strings.Contains(lower, "filter") {
+		return nil
+	}
+	for _, marker := range []string{"filter by ", "filters by ", "filtering by "} {
+		if i := strings.Index(lower, marker); i >= 0 {
+			for _, f := range m.fieldsIn(lower[i+len(marker):]) {
+				if f.Type == "enum" || f.Type == "boolean" {
+					return f
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ListParams are the query parameters a paginated list endpoint reads
+// beside ?limit= and ?cursor=: ?q= searches the fields of "support
+// searching by title or content", ?<field>= filters by each field of
+// "support filtering by status", ?sort= and ?order= order the records by
+// one of the model's fields, and ?page= picks a page by number. Records
+// sorted or picked by page number are paged by offset, since a cursor only
+// follows the endpoint's own order.
+type ListParams struct {
+	Model    *DataModel
+	Search   []*DataField
+	Filters  []*DataField
+	Sortable []*DataField
+
+	covers []*Action // the "support searching/filtering by" steps it serves
+}
+
+// ListParamsFor returns the query parameters a paginated list endpoint
+// reads, or nil when ep doesn't paginate model's records.
+func ListParamsFor(app *Application, ep *Endpoint, model string) *ListParams {
+	if app == nil || ep == nil || ep.PageSize == 0 {
+		return nil
+	}
+	m := modelNamed(app, model)
+	if m == nil {
+		return nil
+	}
+	p := &ListParams{Model: m}
+	for _, step := range ep.Steps {
+		lower := strings.ToLower(step.Text)
+		covered := false
+		if i := strings.Index(lower, "searching by "); i >= 0 {
+			for _, f := range m.fieldsIn(lower[i+len("searching by "):]) {
+				if (f.Type == "text" || f.Type == "email") && !f.EncryptsAtRest() {
+					p.Search, covered = append(p.Search, f), true
+				}
+			}
+		}
+		if i := strings.Index(lower, "filtering by "); i >= 0 {
+			for _, f := range m.fieldsIn(lower[i+len("filtering by "):]) {
+				if filterable(f) {
+					p.Filters, covered = append(p.Filters, f), true
+				}
+			}
+		}
+		if covered {
+			p.covers = append(p.covers, step)
+		}
+	}
+	p.Search, p.Filters = uniqueFields(p.Search), uniqueFields(p.Filters)
+	for _, f := range m.Fields {
+		if !f.EncryptsAtRest() && !strings.Contains(strings.ToLower(f.Name), "password") {
+			p.Sortable = append(p.Sortable, f)
+		}
+	}
+	return p
+}
+
+// filterable reports whether a list can be filtered by a field's value
+// given as a query parameter.
+func filterable(f *DataField) bool {
+	switch f.Type {
+	case "enum", "boolean", "text", "email", "number":
+		return !f.EncryptsAtRest()
+	}
+	return false
+}
+
+// Covers reports whether the endpoint serves step, a "support searching
+// by" or "support filtering by" naming fields it reads.
+func (p *ListParams) Covers(step *Action) bool {
+	for _, c := range p.covers {
+		if c == step {
+			return true
+		}
+	}
+	return false
+}
+
+// FiltersBy reports whether the endpoint filters its records by f.
+func (p *ListParams) FiltersBy(f *DataField) bool {
+	for _, g := range p.Filters {
+		if g == f {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDelete reports whether an interaction deletes a record:
+// `clicking "Delete" deletes the task`.
+func IsDelete(a *Action) bool {
+	lower := strings.ToLower(a.Text)
+	return a.Type == "interact" && strings.HasPrefix(lower, "clicking ") &&
+		(strings.Contains(lower, " deletes ") || strings.Contains(lower, " removes "))
+}
+
+// DeleteLabel returns the label of the button a delete interaction clicks:
+// "Remove" in `clicking the Remove button removes the task`. The parser
+// strips the quotes of `clicking "Delete" deletes the task`, leaving the
+// bare word. It returns "Delete" when no label is named.
+func DeleteLabel(a *Action) string {
+	lower := strings.ToLower(a.Text)
+	end := strings.Index(lower, " deletes ")
+	if end < 0 {
+		end = strings.Index(lower, " removes ")
+	}
+	start := len("clicking ")
+	if end < start {
+		return "Delete"
+	}
+	label := strings.Trim(strings.TrimSpace(a.Text[start:end]), `"'`)
+	if strings.HasPrefix(strings.ToLower(label), "the ") {
+		label = label[len("the "):]
+	}
+	if strings.HasSuffix(strings.ToLower(label), " button") {
+		label = label[:len(label)-len(" button")]
+	}
+	label = strings.Trim(label, `"'`)
+	if label == "" {
+		return "Delete"
+	}
+	return label
+}
+
+// ── Tooltips and Detail Panels ──
+
+// Tooltip is a "hovering over a user avatar shows their name" interaction:
+// hovering over or focusing Target, a field of Model's records or the whole
+// record when nil, shows the value of Field, or Text when Field is nil.
+type Tooltip struct {
+	Model  *DataModel
+	Target *DataField
+	Field  *DataField
+	Text   string
+}
+
+// IsTooltip reports whether an interaction shows a tooltip on hover.
+func IsTooltip(a *Action) bool {
+	lower := strings.ToLower(a.Text)
+	return a.Type == "interact" && strings.HasPrefix(lower, "hovering") && strings.Contains(lower, " shows ")
+}
+
+// TooltipFor parses a tooltip interaction of a page or component whose
+// records are of the named model. The hovered thing names the model
+// ("a user avatar", "the user's avatar") or is a field of the default one
+// ("the avatar"), or nothing at all in a component ("hovering shows the
+// user's name"). The tooltip shows a field ("their name", "the user's
+// name") or, failing that, the words themselves. It returns nil when a
+// field shown or hovered over can't be resolved.
+func TooltipFor(app *Application, a *Action, model string) *Tooltip {
+	if app == nil || !IsTooltip(a) {
+		return nil
+	}
+	lower := strings.ToLower(a.Text)
+	i := strings.Index(lower, " shows ")
+	t := &Tooltip{}
+
+	var subject []string
+	for _, word := range strings.Fields(lower[len("hovering"):i]) {
+		if word == "over" || word == "on" || word == "a" || word == "an" || word == "the" {
+			continue
+		}
+		if t.Model == nil {
+			if m := modelNamed(app, strings.TrimSuffix(word, "'s")); m != nil {
+				t.Model = m
+				continue
+			}
+		}
+		subject = append(subject, word)
+	}
+	if t.Model == nil && model != "" {
+		t.Model = modelNamed(app, model)
+	}
+	if len(subject) > 0 {
+		if t.Model == nil {
+			return nil
+		}
+		if t.Target = t.Model.FieldFor(strings.Join(subject, " ")); t.Target == nil {
+			return nil
+		}
+	}
+
+	shown := strings.TrimSpace(a.Text[i+len(" shows "):])
+	for _, suffix := range []string{" as a tooltip", " in a tooltip", " tooltip"} {
+		if strings.HasSuffix(strings.ToLower(shown), suffix) {
+			shown = shown[:len(shown)-len(suffix)]
+		}
+	}
+	words := strings.Fields(strings.ToLower(shown))
+	if len(words) > 1 && words[0] == "the" && strings.HasSuffix(words[1], "'s") {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	owner := words[0]
+	switch {
+	case owner == "their" || owner == "its" || owner == "his" || owner == "her":
+		if t.Model == nil {
+			return nil
+		}
+		if t.Field = t.Model.FieldFor(strings.Join(words[1:], " ")); t.Field == nil {
+			return nil
+		}
+		return t
+	case strings.HasSuffix(owner, "'s"):
+		if m := modelNamed(app, strings.TrimSuffix(owner, "'s")); m != nil && (t.Model == nil || t.Model == m) {
+			t.Model = m
+			if t.Field = m.FieldFor(strings.Join(words[1:], " ")); t.Field == nil {
+				return nil
+			}
+			return t
+		}
+	case owner == "the" && t.Model != nil:
+		if t.Field = t.Model.FieldNamed(strings.Join(words[1:], "")); t.Field != nil {
+			return t
+		}
+	}
+	if t.Target == nil && len(subject) > 0 {
+		return nil
+	}
+	t.Text = strings.Trim(shown, `"'`)
+	return t
+}
+
+// DetailPanel is a "clicking a transaction opens a detail panel on the
+// right" interaction: clicking one of Model's records slides a panel in
+// from Side listing Fields of it.
+type DetailPanel struct {
+	Model  *DataModel
+	Side   string // "right" or "left"
+	Fields []*DataField
+}
+
+// IsDetailPanel reports whether an interaction opens a record's details in
+// a panel.
+func IsDetailPanel(a *Action) bool {
+	lower := strings.ToLower(a.Text)
+	return a.Type == "interact" && strings.HasPrefix(lower, "clicking ") &&
+		strings.Contains(lower, " opens ") && strings.Contains(lower, "panel")
+}
+
+// DetailPanelFor parses a detail panel interaction: the model is the first
+// word naming one before "opens". The panel lists the fields named after
+// "with" ("with its amount and date"), or every field but passwords and
+// encrypted ones. It returns nil when no model is named.
+func DetailPanelFor(app *Application, a *Action) *DetailPanel {
+	if app == nil || !IsDetailPanel(a) {
+		return nil
+	}
+	lower := strings.ToLower(a.Text)
+	i := strings.Index(lower, " opens ")
+	p := &DetailPanel{Side: "right"}
+	for _, word := range strings.Fields(lower[len("clicking "):i]) {
+		if p.Model = modelNamed(app, word); p.Model != nil {
+			break
+		}
+	}
+	if p.Model == nil {
+		return nil
+	}
+	rest := lower[i:]
+	if strings.Contains(rest, " left") {
+		p.Side = "left"
+	}
+	if j := strings.Index(rest, " with "); j >= 0 {
+		p.Fields = p.Model.fieldsIn(rest[j+len(" with "):])
+	}
+	if len(p.Fields) == 0 {
+		p.Fields = RecordFields(p.Model)
+	}
+	p.Fields = uniqueFields(p.Fields)
+	return p
+}
+
+// HasTooltips reports whether any page or component shows a tooltip.
+func HasTooltips(app *Application) bool {
+	return anyInteraction(app, IsTooltip)
+}
+
+// HasDetailPanels reports whether any page opens a detail panel.
+func HasDetailPanels(app *Application) bool {
+	return anyInteraction(app, IsDetailPanel)
+}
+
+func anyInteraction(app *Application, match func(*Action) bool) bool {
+	for _, p := range app.Pages {
+		for _, a := range p.Content {
+			if match(a) {
+				return true
+			}
+		}
+	}
+	for _, c := range app.Components {
+		for _, a := range c.Content {
+			if match(a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ── Detail Routes ──
+
+// DetailRoute is a page showing the record its URL names: "page
+// PostDetail: shows the Post with the id from the url" routes /posts/:id to
+// PostDetail, which loads the post through Endpoint. Lists and tables that
+// navigate to the page open the clicked record's route.
+type DetailRoute struct {
+	Page     string `json:"page"`     // e.g. "PostDetail"
+	Model    string `json:"model"`    // e.g. "Post"
+	Param    string `json:"param"`    // field the URL carries, e.g. "id" or "slug"
+	Slug     string `json:"slug"`     // URL segment, e.g. "posts"
+	Endpoint string `json:"endpoint"` // API loading the record, e.g. "GetPost"
+	Arg      string `json:"arg"`      // the endpoint parameter the URL's value fills, e.g. "post_id"
+}
+
+// Path returns the route the page is served at: "/posts/:id".
+func (r *DetailRoute) Path() string {
+	return "/" + r.Slug + "/:" + r.Param
+}
+
+// IsDetailRoute reports whether a page statement shows the record named by
+// the page's URL.
+func IsDetailRoute(a *Action) bool {
+	return strings.Contains(strings.ToLower(a.Text), " from the url")
+}
+
+// DetailRouteFor returns the route of a page showing the record its URL
+// names, or nil.
+func DetailRouteFor(app *Application, page string) *DetailRoute {
+	if app == nil {
+		return nil
+	}
+	for _, r := range app.DetailRoutes {
+		if strings.EqualFold(r.Page, page) {
+			return r
+		}
+	}
+	return nil
+}
+
+// ── Conflicting Edits ──
+
+// VersionColumn is the column counting a versioned model's saved edits. It
+// starts at 1 and goes up by one with every update, so an update naming a
+// version other than the stored one was made to a copy someone else has
+// since changed.
+const VersionColumn = "version"
+
+// ProtectsEdits reports whether any model is protected against conflicting
+// edits.
+func ProtectsEdits(app *Application) bool {
+	for _, m := range app.Data {
+		if m.Versioned {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdatedModel returns the model an Update<Model> endpoint edits: the
+// longest model name its name starts with after "Update", so
+// UpdateOrderStatus edits Order. It returns nil for other endpoints.
+func UpdatedModel(app *Application, ep *Endpoint) *DataModel {
+	lower := strings.ToLower(ep.Name)
+	if !strings.HasPrefix(lower, "update") {
+		return nil
+	}
+	rest := lower[len("update"):]
+	var found *DataModel
+	for _, m := range app.Data {
+		if strings.HasPrefix(rest, strings.ToLower(m.Name)) && (found == nil || len(m.Name) > len(found.Name)) {
+			found = m
+		}
+	}
+	return found
+}
+
+// ConflictModel returns the model an endpoint updates when it is protected
+// against conflicting edits, or nil. Such an update must name the version
+// of the record it was made to, in an If-Match header or a version field,
+// and is refused with 409 Conflict and the current record when that
+// version is no longer the stored one.
+func ConflictModel(app *Application, ep *Endpoint) *DataModel {
+	if m := UpdatedModel(app, ep); m != nil && m.Versioned {
+		return m
+	}
+	return nil
+}
+
+// EditForm is a detail route page's "there is a form to edit the post": a
+// form filled in with the record the page shows, saving it through the
+// model's Update endpoint. A versioned record's form sends the version it
+// was filled in from, and asks the user whether to keep their changes or
+// take the saved ones when someone else saved first.
+type EditForm struct {
+	Form      *Action
+	Model     *DataModel
+	Endpoint  *Endpoint
+	Param     string       // the endpoint parameter the record's id fills, e.g. "post_id"
+	Fields    []*DataField // the record's fields the endpoint accepts
+	Versioned bool
+}
+
+// IsEditForm reports whether a page statement is a form editing a record.
+func IsEditForm(a *Action) bool {
+	lower := strings.ToLower(a.Text)
+	return (a.Type == "input" || a.Type == "display") &&
+		(strings.Contains(lower, "form to edit ") || strings.Contains(lower, "form to update "))
+}
+
+// EditFormFor returns the form editing the record a detail route's page
+// shows, or nil when the page has none or no Update endpoint takes the
+// record's fields.
+func EditFormFor(app *Application, page *Page) *EditForm {
+	r := DetailRouteFor(app, page.Name)
+	if r == nil {
+		return nil
+	}
+	m := modelNamed(app, r.Model)
+	if m == nil {
+		return nil
+	}
+	for _, a := range page.Content {
+		if !IsEditForm(a) || namesOtherModel(app, a.Text, m) {
+			continue
+		}
+		for _, ep := range app.APIs {
+			if UpdatedModel(app, ep) != m {
+				continue
+			}
+			e := &EditForm{Form: a, Model: m, Endpoint: ep, Param: recordParam(ep, m), Versioned: m.Versioned}
+			for _, f := range RecordFields(m) {
+				if endpointAccepts(ep, f.Name) && !isTimestampName(f.Name) {
+					e.Fields = append(e.Fields, f)
+				}
+			}
+			if len(e.Fields) > 0 {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// namesOtherModel reports whether text names a model other than m.
+func namesOtherModel(app *Application, text string, m *DataModel) bool {
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		if other := modelNamed(app, word); other != nil && other != m {
+			return true
+		}
+	}
+	return false
+}
+
+// recordParam returns the parameter of an endpoint naming the record of m
+// it acts on: "post_id", "id", or its first parameter.
+func recordParam(ep *Endpoint, m *DataModel) string {
+	arg := strings.ReplaceAll(pageSlug(strings.ReplaceAll(m.Name, " ", "")), "-", "_") + "_id"
+	for _, name := range []string{arg, "id"} {
+		if endpointAccepts(ep, name) {
+			return name
+		}
+	}
+	if len(ep.Params) > 0 {
+		return ep.Params[0].Name
+	}
+	return arg
+}
+
+// isTimestampName reports whether a field is a creation or update time every
+// layer keeps itself.
+func isTimestampName(name string) bool {
+	switch strings.ToLower(strings.ReplaceAll(name, "_", "")) {
+	case "created", "createdat", "updated", "updatedat":
+		return true
+	}
+	return false
+}
+
+// LoadsRecord reports whether an endpoint loads the record of a detail
+// route, rather than listing records.
+func LoadsRecord(app *Application, ep *Endpoint) bool {
+	for _, r := range app.DetailRoutes {
+		if r.Endpoint == ep.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordLink returns how to link to one record on a page: the URL up to the
+// record's value and the field supplying it. A detail route takes the value
+// as a path segment ("/posts/", "id"); other pages take an id query
+// ("/post-detail?id=", "id").
+func RecordLink(app *Application, page string) (prefix, field string) {
+	if r := DetailRouteFor(app, page); r != nil {
+		return "/" + r.Slug + "/", r.Param
+	}
+	return "/" + pageSlug(page) + "?id=", "id"
+}
+
+// ItemLinkFor returns the page a "clicking a post navigates to PostDetail"
+// interaction opens from the items a page lists of model, and the
+// interaction, or "" and nil.
+func ItemLinkFor(app *Application, page *Page, model string) (string, *Action) {
+	if app == nil || page == nil {
+		return "", nil
+	}
+	var m *DataModel
+	for _, dm := range app.Data {
+		if strings.EqualFold(dm.Name, model) {
+			m = dm
+		}
+	}
+	if m == nil {
+		return "", nil
+	}
+	for _, a := range page.Content {
+		if a.Type != "interact" {
+			continue
+		}
+		if target := rowClickTarget(app, m, strings.ToLower(a.Text)); target != "" {
+			return target, a
+		}
+	}
+	return "", nil
+}
+
+// RecordFields returns the fields a record's details show: all but
+// encrypted fields and passwords.
+func RecordFields(m *DataModel) []*DataField {
+	var fields []*DataField
+	for _, f := range m.Fields {
+		if !f.Encrypted && !strings.Contains(strings.ToLower(f.Name), "password") {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// RecordTitle returns the field a record's page is headed with — its name,
+// title, or label — or nil.
+func RecordTitle(m *DataModel) *DataField {
+	for _, name := range []string{"name", "title", "label"} {
+		if f := m.FieldNamed(name); f != nil && !f.Encrypted {
+			return f
+		}
+	}
+	return nil
+}
+
+// ── Imports ──
+
+// Import modes: what an import does with a file some of whose records are
+// invalid.
+const (
+	ImportAllOrNothing    = "all-or-nothing"    // saves none of the file's records
+	ImportContinueOnError = "continue-on-error" // saves the valid records and reports the rest
+)
+
+// MaxImportBytes is the size of the largest file an import accepts.
+const MaxImportBytes = 10 << 20
+
+// Import is an endpoint's "import tasks from a csv or json file": it reads
+// a model's records from a CSV file with a header row, or a JSON array of
+// objects, checks each against the model's fields, and saves them — or,
+// asked for a dry run, only checks them. It responds with a summary of the
+// import listing each problem by record and field.
+type Import struct {
+	Model   string   `json:"model"`
+	Formats []string `json:"formats"` // "csv", "json"
+	Mode    string   `json:"mode"`    // ImportAllOrNothing or ImportContinueOnError
+}
+
+// Accepts returns the file types an import form offers to upload, as an
+// <input accept> list.
+func (im *Import) Accepts() string {
+	var types []string
+	for _, f := range im.Formats {
+		switch f {
+		case "csv":
+			types = append(types, ".csv", "text/csv")
+		case "json":
+			types = append(types, ".json", "application/json")
+		}
+	}
+	return strings.Join(types, ",")
+}
+
+// Describe returns how an import form names the files it takes, e.g.
+// "CSV or JSON file".
+func (im *Import) Describe() string {
+	names := make([]string, len(im.Formats))
+	for i, f := range im.Formats {
+		names[i] = strings.ToUpper(f)
+	}
+	return strings.Join(names, " or ") + " file"
+}
+
+// IsImport reports whether an API step imports records from a file:
+// "import tasks from a csv or json file".
+func IsImport(text string) bool {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	return strings.HasPrefix(lower, "import ") && strings.Contains(lower, " from ")
+}
+
+// ImportModel returns the model whose records an import step reads, or nil.
+func ImportModel(app *Application, text string) *DataModel {
+	lower := strings.ToLower(text)
+	i := strings.Index(lower, " from ")
+	if i < 0 {
+		return nil
+	}
+	for _, word := range strings.Fields(lower[:i])[1:] {
+		if m := modelNamed(app, word); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// importFormats returns the formats an import step reads: those it names
+// after "from", or both.
+func importFormats(text string) []string {
+	lower := strings.ToLower(text)
+	from := lower[strings.Index(lower, " from "):]
+	var formats []string
+	for _, f := range []string{"csv", "json"} {
+		if strings.Contains(from, f) {
+			formats = append(formats, f)
+		}
+	}
+	if len(formats) == 0 {
+		return []string{"csv", "json"}
+	}
+	return formats
+}
+
+// importMode returns the mode an import step sets — "skip invalid rows and
+// import the rest", "import all rows or none" — or "".
+func importMode(text string) string {
+	lower := strings.ToLower(text)
+	for _, phrase := range []string{"skip invalid", "skip the invalid", "skip bad", "continue on error", "continue past", "import the valid"} {
+		if strings.Contains(lower, phrase) {
+			return ImportContinueOnError
+		}
+	}
+	for _, phrase := range []string{"all or nothing", "all-or-nothing", "or none"} {
+		if strings.Contains(lower, phrase) {
+			return ImportAllOrNothing
+		}
+	}
+	return ""
+}
+
+// ImportColumn is a column an import reads: a field of the model, or the id
+// of a record it belongs to.
+type ImportColumn struct {
+	Name     string     // e.g. "due_date" or "project_id"; files may write it "Due date" or "dueDate"
+	Field    *DataField // nil for the id of a record the model belongs to
+	Relation string     // the model that id names
+}
+
+// ImportColumns returns the columns an import of m reads: its fields, save
+// passwords and the creation and update times every layer keeps itself,
+// and the id of each record it belongs to. When the import requires
+// authentication, records of a model belonging to a user belong to the
+// user importing them instead.
+func ImportColumns(m *DataModel, auth bool) []*ImportColumn {
+	var cols []*ImportColumn
+	for _, f := range m.Fields {
+		if isTimestampName(f.Name) || strings.Contains(strings.ToLower(f.Name), "password") {
+			continue
+		}
+		cols = append(cols, &ImportColumn{Name: f.Name, Field: f})
+	}
+	for _, r := range m.Relations {
+		if r.Kind != "belongs_to" || auth && strings.EqualFold(r.Target, "User") {
+			continue
+		}
+		cols = append(cols, &ImportColumn{Name: strings.ToLower(r.Target) + "_id", Relation: r.Target})
+	}
+	return cols
+}
+
+// ImportEndpoint returns the endpoint importing a model's records, or nil.
+func ImportEndpoint(app *Application, model string) *Endpoint {
+	for _, ep := range app.APIs {
+		if ep.Import != nil && strings.EqualFold(ep.Import.Model, model) {
+			return ep
+		}
+	}
+	return nil
+}
+
+// HasImports reports whether any endpoint imports records.
+func HasImports(app *Application) bool {
+	for _, ep := range app.APIs {
+		if ep.Import != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportForm is a page's "there is a form to import tasks": it uploads a
+// file to the model's import endpoint, either to check it or to import it,
+// and shows the summary the endpoint responds with.
+type ImportForm struct {
+	Form     *Action
+	Model    *DataModel
+	Endpoint *Endpoint
+}
+
+// IsImportForm reports whether a page statement uploads a file of records
+// to import.
+func IsImportForm(a *Action) bool {
+	if a.Type == "loop" {
+		return false
+	}
+	lower := strings.ToLower(a.Text)
+	return strings.Contains(lower, "to import ") || strings.Contains(lower, "import form") || strings.Contains(lower, "import button")
+}
+
+// ImportFormModel returns the model whose records a page's import form
+// uploads, or nil.
+func ImportFormModel(app *Application, a *Action) *DataModel {
+	for _, word := range strings.Fields(a.Text) {
+		if m := modelNamed(app, word); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// ImportFormFor returns the first import form on a page whose model has an
+// import endpoint, or nil.
+func ImportFormFor(app *Application, page *Page) *ImportForm {
+	for _, a := range page.Content {
+		if !IsImportForm(a) {
+			continue
+		}
+		m := ImportFormModel(app, a)
+		if m == nil {
+			continue
+		}
+		if ep := ImportEndpoint(app, m.Name); ep != nil {
+			return &ImportForm{Form: a, Model: m, Endpoint: ep}
+		}
+	}
+	return nil
+}
+
+// ── Load Errors ──
+
+// IsErrorCondition reports whether a page statement declares its error
+// state: `if there is an error, show the error message`.
+func IsErrorCondition(a *Action) bool {
+	lower := strings.ToLower(a.Text)
+	return a.Type == "condition" && strings.Contains(lower, "error") &&
+		!strings.Contains(lower, "succeed") && !strings.Contains(lower, "success")
+}
+
+// LoadErrorMessage returns the message a page shows when loading what it
+// lists or shows fails and the server gives no reason: the one its error
+// state names (`if there is an error, show Could not reach the journal`),
+// else "Could not load <subject>".
+func LoadErrorMessage(page *Page, subject string) string {
+	for _, a := range page.Content {
+		if !IsErrorCondition(a) {
+			continue
+		}
+		idx := strings.Index(strings.ToLower(a.Text), " show ")
+		if idx < 0 {
+			break
+		}
+		msg := strings.Trim(strings.TrimSpace(a.Text[idx+len(" show "):]), `"'`)
+		lower := strings.ToLower(msg)
+		switch strings.TrimPrefix(strings.TrimPrefix(lower, "the "), "an ") {
+		case "", "error", "error message", "errors":
+		default:
+			return msg
+		}
+		break
+	}
+	return "Could not load " + subject
+}
+
+// ── Loading Skeletons ──
+
+// IsSkeleton reports whether a page statement shows a skeleton screen while
+// its data loads: `while loading, show a skeleton screen`.
+func IsSkeleton(a *Action) bool {
+	lower := strings.ToLower(a.Text)
+	return a.Type == "condition" && strings.Contains(lower, "skeleton") &&
+		(strings.Contains(lower, "while loading") || strings.Contains(lower, "is loading"))
+}
+
+// HasSkeletons reports whether any page shows a skeleton screen while its
+// data loads.
+func HasSkeletons(app *Application) bool {
+	for _, p := range app.Pages {
+		for _, a := range p.Content {
+			if IsSkeleton(a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ── Responsive Layouts ──
+
+// Widths, in pixels, below which a responsive page switches to its tablet
+// and mobile layouts.
+const (
+	TabletBreakpoint = 1024
+	MobileBreakpoint = 640
+)
+
+// Responsive is how a page declaring `make the layout responsive for mobile
+// and tablet` adapts to narrower screens. A page naming neither adapts to
+// both.
+type Responsive struct {
+	Mobile bool // one column and a collapsed menu below MobileBreakpoint
+	Tablet bool // fewer columns below TabletBreakpoint
+}
+
+// IsResponsive reports whether a page statement asks for a responsive
+// layout.
+func IsResponsive(a *Action) bool {
+	return strings.Contains(strings.ToLower(a.Text), "responsive")
+}
+
+// ResponsiveFor returns how a page adapts to narrower screens, or nil when
+// it doesn't declare a responsive layout.
+func ResponsiveFor(page *Page) *Responsive {
+	for _, a := range page.Content {
+		if !IsResponsive(a) {
+			continue
+		}
+		lower := strings.ToLower(a.Text)
+		r := &Responsive{
+			Mobile: strings.Contains(lower, "mobile") || strings.Contains(lower, "phone"),
+			Tablet: strings.Contains(lower, "tablet"),
+		}
+		if !r.Mobile && !r.Tablet {
+			r.Mobile, r.Tablet = true, true
+		}
+		return r
+	}
+	return nil
+}
+
+// HasResponsivePages reports whether any page declares a responsive layout.
+func HasResponsivePages(app *Application) bool {
+	for _, p := range app.Pages {
+		if ResponsiveFor(p) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ── Charts and Aggregates ──
+
+// Aggregate is a count, sum, average, minimum, or maximum of a model's
+// records per group. Records are grouped by a field, by the record they
+// belong to, or by the day, week, month, or year of a date.
+type Aggregate struct {
+	Func     string `json:"func"`               // "count", "sum", "avg", "min", or "max"
+	Field    string `json:"field,omitempty"`    // number field the function applies to; empty when counting
+	Model    string `json:"model"`              // e.g. "Expense"
+	GroupBy  string `json:"group_by,omitempty"` // field the records are grouped by
+	Relation string `json:"relation,omitempty"` // model the records belong to and are grouped by
+	Label    string `json:"label,omitempty"`    // field of the relation naming each group; its id without one
+	Period   string `json:"period,omitempty"`   // "day", "week", "month", or "year" for a time series
+	Date     string `json:"date,omitempty"`     // date field a time series buckets and Days counts back on; the creation time when empty
+	Days     int    `json:"days,omitempty"`     // only records from the last N days; 0 for all
+}
+
+// Chart is a page's "show a bar chart of expenses by category" or "show a
+// line chart of signups per week": a point for each group of an aggregate,
+// counting a model's records or summing a number field. Backends serve the
+// points from /api/charts/<slug>.
+type Chart struct {
+	Kind  string `json:"kind"`  // "bar", "line", or "pie"
+	Title string `json:"title"` // e.g. "Expenses by category"
+	Slug  string `json:"slug"`  // URL segment, e.g. "expenses-by-category"
+	Aggregate
+}
+
+// AggregateError explains why records can't be aggregated: the text names
+// no data model (Model is nil and Phrase is the text's subject), no number
+// field of Model for the function (NoField), or Phrase names no field or
+// relation of Model to group by. Phrase is empty when the text doesn't say
+// how to group the records.
+type AggregateError struct {
+	Model   *DataModel
+	Phrase  string
+	NoField bool
+}
+
+func (e *AggregateError) Error() string {
+	switch {
+	case e.Model == nil:
+		return fmt.Sprintf("%q names no data model", e.Phrase)
+	case e.NoField:
+		return fmt.Sprintf("no number field of %s to aggregate", e.Model.Name)
+	case e.Phrase == "":
+		return fmt.Sprintf("no grouping for %s records", e.Model.Name)
+	}
+	return fmt.Sprintf("%q is not a field or relation of %s", e.Phrase, e.Model.Name)
+}
+
+// IsChart reports whether a display action shows a chart of data.
+func IsChart(a *Action) bool {
+	return a.Type == "display" && a.Template == "" && strings.Contains(strings.ToLower(a.Text), "chart of ")
+}
+
+// groupingMarkers start the part of a chart or aggregate saying how its
+// records are grouped and how far back they go.
+var groupingMarkers = []string{" grouped by ", " by ", " per ", " each ", " over ", " in the last ", " for the last ", " during the last "}
+
+// ParseChart reads a chart from a show action's text:
+//
+//	show a bar chart of stock levels by category        → Products, current_stock summed by category
+//	show a line chart of signups per week               → Users counted per week
+//	show a line chart of workouts over the last 30 days → Workouts counted per day for 30 days
+//	show a pie chart of tasks by project                → Tasks counted by the Project they belong to
+//
+// The subject's last word naming a model is the model counted; a subject
+// naming only a number field ("stock levels") sums it. Time series and
+// "in the last 30 days" go by a "date" or "<model> date" field, else the
+// creation time. It returns an *AggregateError when the records can't be
+// found or grouped.
+func ParseChart(app *Application, text string) (*Chart, error) {
+	lower := strings.ToLower(strings.TrimRight(strings.TrimSpace(text), ". "))
+	i := strings.Index(lower, "chart of ")
+	if i < 0 {
+		return nil, &AggregateError{Phrase: text}
+	}
+	head, rest := lower[:i], strings.TrimSpace(lower[i+len("chart of "):])
+	if rest == "" {
+		return nil, &AggregateError{Phrase: rest}
+	}
+	c := &Chart{Title: strings.ToUpper(rest[:1]) + rest[1:], Slug: chartSlug(rest)}
+	switch {
+	case strings.Contains(head, "pie") || strings.Contains(head, "donut") || strings.Contains(head, "doughnut"):
+		c.Kind = "pie"
+	case strings.Contains(head, "line") || strings.Contains(head, "area"):
+		c.Kind = "line"
+	case strings.Contains(head, "bar") || strings.Contains(head, "column"):
+		c.Kind = "bar"
+	}
+
+	subject, grouping := splitGrouping(rest)
+	m, sum := chartModel(app, subject)
+	if m == nil {
+		return nil, &AggregateError{Phrase: subject}
+	}
+	c.Model, c.Func = m.Name, "count"
+	if sum != nil {
+		c.Func, c.Field = "sum", sum.Name
+	}
+	if err := groupRecords(app, m, &c.Aggregate, lower, grouping); err != nil {
+		return nil, err
+	}
+	if c.Kind == "" {
+		c.Kind = "bar"
+		if c.Period != "" {
+			c.Kind = "line"
+		}
+	}
+	return c, nil
+}
+
+// aggregateFuncs maps the verb starting an aggregate to its function.
+var aggregateFuncs = map[string]string{
+	"count": "count", "sum": "sum", "total": "sum", "average": "avg", "avg": "avg",
+	"minimum": "min", "min": "min", "lowest": "min", "maximum": "max", "max": "max", "highest": "max",
+}
+
+// IsAggregate reports whether an API step computes an aggregate: it starts
+// with count, sum, total, average, minimum, or maximum.
+func IsAggregate(text string) bool {
+	words := strings.Fields(strings.ToLower(text))
+	return len(words) > 1 && aggregateFuncs[words[0]] != ""
+}
+
+// ParseAggregate reads an aggregate from an API step:
+//
+//	sum the amount of Orders grouped by month for the last 12 months
+//	count the Users grouped by role
+//	average the rating of Reviews by product
+//
+// Grouping and time windows read as they do for charts. It returns an
+// *AggregateError when the records can't be found, aggregated, or grouped.
+func ParseAggregate(app *Application, text string) (*Aggregate, error) {
+	lower := strings.ToLower(strings.TrimRight(strings.TrimSpace(text), ". "))
+	verb, rest, _ := strings.Cut(lower, " ")
+	agg := &Aggregate{Func: aggregateFuncs[verb]}
+	if agg.Func == "" {
+		return nil, &AggregateError{Phrase: text}
+	}
+	for _, prefix := range []string{"up ", "the ", "number of ", "all "} {
+		rest = strings.TrimPrefix(rest, prefix)
+	}
+
+	subject, grouping := splitGrouping(rest)
+	m, field := chartModel(app, strings.Replace(subject, " of ", " ", 1))
+	if m == nil {
+		return nil, &AggregateError{Phrase: subject}
+	}
+	agg.Model = m.Name
+	if agg.Func != "count" {
+		if field == nil {
+			return nil, &AggregateError{Model: m, NoField: true}
+		}
+		agg.Field = field.Name
+	}
+	if err := groupRecords(app, m, agg, lower, grouping); err != nil {
+		return nil, err
+	}
+	return agg, nil
+}
+
+// splitGrouping splits a chart or aggregate's text into its subject and
+// the part saying how to group it: "expenses by category" → "expenses",
+// "by category".
+func splitGrouping(rest string) (subject, grouping string) {
+	cut := len(rest)
+	for _, marker := range groupingMarkers {
+		if j := strings.Index(rest, marker); j >= 0 && j < cut {
+			cut = j
+		}
+	}
+	return rest[:cut], strings.TrimSpace(rest[cut:])
+}
+
+// groupRecords reads how to group m's records from the grouping part of
+// the text: by a period, a relation, or a field. Windows with no other
+// grouping ("over the last 30 days") are time series, bucketed by day,
+// week, or month as the window grows.
+func groupRecords(app *Application, m *DataModel, agg *Aggregate, lower, grouping string) error {
+	agg.Days = chartDays(grouping)
+	agg.Period = chartPeriod(lower)
+	if agg.Period == "" {
+		g := grouping
+		for _, prefix := range []string{"grouped ", "by ", "per ", "each ", "the ", "their ", "its "} {
+			g = strings.TrimPrefix(g, prefix)
+		}
+		for _, window := range []string{"over ", "in the last ", "for the last ", "during the last "} {
+			if strings.HasPrefix(g, window) {
+				g = ""
+			} else if j := strings.Index(g, " "+window); j >= 0 {
+				g = g[:j]
+			}
+		}
+		switch {
+		case g == "" && (strings.HasPrefix(grouping, "over ") || agg.Days > 0):
+			switch {
+			case agg.Days > 0 && agg.Days <= 31:
+				agg.Period = "day"
+			case agg.Days > 0 && agg.Days <= 182:
+				agg.Period = "week"
+			default:
+				agg.Period = "month"
+			}
+		case g == "":
+			return &AggregateError{Model: m}
+		default:
+			if rel := m.belongsToNamed(g); rel != "" {
+				agg.Relation = rel
+				agg.Label = labelField(app, rel)
+				break
+			}
+			f := m.FieldFor(g)
+			if f == nil || f.Encrypted {
+				return &AggregateError{Model: m, Phrase: g}
+			}
+			if f.Type == "date" || f.Type == "datetime" {
+				agg.Period = "day"
+				if !isCreatedName(f.Name) {
+					agg.Date = f.Name
+				}
+			} else {
+				agg.GroupBy = f.Name
+			}
+		}
+	}
+	if (agg.Period != "" || agg.Days > 0) && agg.Date == "" {
+		agg.Date = chartDate(m)
+	}
+	return nil
+}
+
+// ChartFor returns the chart a show action draws, or nil.
+func ChartFor(app *Application, a *Action) *Chart {
+	if app == nil || !IsChart(a) {
+		return nil
+	}
+	c, err := ParseChart(app, a.Text)
+	if err != nil {
+		return nil
+	}
+	for _, existing := range app.Charts {
+		if existing.Slug == c.Slug {
+			return c
+		}
+	}
+	return nil
+}
+
+// chartModel returns the model a chart's subject counts — the last word
+// naming one, or User for signups — and the number field it sums, if any.
+// A subject naming only a number field sums it on the first model with one.
+func chartModel(app *Application, subject string) (*DataModel, *DataField) {
+	words := strings.Fields(subject)
+	for j := len(words) - 1; j >= 0; j-- {
+		if m := modelNamed(app, words[j]); m != nil {
+			others := append(append([]string{}, words[:j]...), words[j+1:]...)
+			return m, m.numberFieldIn(strings.Join(others, " "))
+		}
+	}
+	for _, signup := range []string{"signup", "sign-up", "sign up", "registration"} {
+		if strings.Contains(subject, signup) {
+			if m := modelNamed(app, "user"); m != nil {
+				return m, nil
+			}
+		}
+	}
+	for _, m := range app.Data {
+		if f := m.numberFieldIn(subject); f != nil {
+			return m, f
+		}
+	}
+	return nil, nil
+}
+
+// numberFieldIn returns the number or decimal field named in prose, by its
+// name or a word of it ("stock levels" → current_stock). A "total",
+// "revenue", or "sales" with no field named is the first decimal field.
+func (m *DataModel) numberFieldIn(phrase string) *DataField {
+	isNumber := func(f *DataField) bool {
+		return (f.Type == "number" || f.Type == "decimal") && !f.Encrypted
+	}
+	if f := m.FieldFor(phrase); f != nil && isNumber(f) {
+		return f
+	}
+	words := strings.Fields(phrase)
+	for _, f := range m.Fields {
+		if !isNumber(f) {
+			continue
+		}
+		for _, part := range strings.Fields(strings.ToLower(FieldLabel(f.Name))) {
+			for _, w := range words {
+				if len(part) > 3 && (w == part || w == part+"s") {
+					return f
+				}
+			}
+		}
+	}
+	for _, w := range words {
+		if w == "total" || w == "revenue" || w == "sales" {
+			for _, f := range m.Fields {
+				if f.Type == "decimal" && !f.Encrypted {
+					return f
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// belongsToNamed returns the model m belongs to that prose names
+// ("project", "team member" for TeamMember), or "".
+func (m *DataModel) belongsToNamed(phrase string) string {
+	for _, r := range m.Relations {
+		if r.Kind != "belongs_to" {
+			continue
+		}
+		spaced := strings.ToLower(FieldLabel(r.Target))
+		if phrase == spaced || phrase == spaced+"s" || phrase == strings.ToLower(r.Target) {
+			return r.Target
+		}
+	}
+	return ""
+}
+
+// labelField returns the field naming a model's records — its name, title,
+// label, username, or email, else its first text field — or "".
+func labelField(app *Application, model string) string {
+	for _, m := range app.Data {
+		if !strings.EqualFold(m.Name, model) {
+			continue
+		}
+		for _, name := range []string{"name", "title", "label", "username", "email"} {
+			if f := m.FieldNamed(name); f != nil && !f.Encrypted {
+				return f.Name
+			}
+		}
+		for _, f := range m.Fields {
+			if f.Type == "text" && !f.Encrypted {
+				return f.Name
+			}
+		}
+	}
+	return ""
+}
+
+// chartDate returns the date field a time series of m's records buckets by
+// default, "date" or "<model> date", or "" for their creation time.
+func chartDate(m *DataModel) string {
+	for _, name := range []string{"date", m.Name + "date"} {
+		if f := m.FieldNamed(name); f != nil && (f.Type == "date" || f.Type == "datetime") {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// chartPeriod returns the period prose groups a time series by: "per
+// week", "by month", "each day", "daily" → "week", "month", "day", "day".
+func chartPeriod(phrase string) string {
+	for _, p := range []struct{ period, adjective string }{
+		{"day", "daily"}, {"week", "weekly"}, {"month", "monthly"}, {"year", "yearly"},
+	} {
+		for _, marker := range []string{"per " + p.period, "by " + p.period, "each " + p.period, p.adjective} {
+			if j := strings.Index(phrase, marker); j >= 0 && (j+len(marker) == len(phrase) || phrase[j+len(marker)] == ' ') {
+				return p.period
+			}
+		}
+	}
+	return ""
+}
+
+// chartDays returns the days "in the last 30 days" or "over the last
+// month" covers, or 0.
+func chartDays(phrase string) int {
+	i := strings.Index(phrase, "last ")
+	if i < 0 {
+		return 0
+	}
+	words := strings.Fields(phrase[i+len("last "):])
+	n, unit := 1, ""
+	if len(words) > 1 {
+		if v, err := strconv.Atoi(words[0]); err == nil {
+			n, words = v, words[1:]
+		}
+	}
+	if len(words) > 0 {
+		unit = strings.TrimSuffix(words[0], "s")
+	}
+	switch unit {
+	case "day":
+		return n
+	case "week":
+		return n * 7
+	case "month":
+		return n * 30
+	case "year":
+		return n * 365
+	}
+	return 0
+}
+
+// chartSlug turns a chart's text into a URL segment:
+// "signups per week" → "signups-per-week".
+func chartSlug(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range text {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// isCreatedName reports whether a field holds the creation time backends
+// keep for every record: "created", "created_at", or "createdAt".
+func isCreatedName(name string) bool {
+	lower := strings.ToLower(strings.ReplaceAll(name, "_", ""))
+	return lower == "created" || lower == "createdat"
+}
+
+// ── Documents ──
+
+// Document is a PDF rendered from a record by a "generate a PDF invoice
+// from the Order" step. Field names refer to the model's fields; the
+// renderer shows the reference and date in the header, the detail fields
+// as label/value rows, the items as a table, and the total at the bottom.
+type Document struct {
+	Kind       string   `json:"kind"`                  // e.g. "invoice", "receipt", "packing slip"
+	Model      string   `json:"model"`                 // e.g. "Order"
+	Slug       string   `json:"slug"`                  // URL segment, e.g. "order-invoices"
+	Reference  string   `json:"reference,omitempty"`   // field identifying the record, e.g. "order_number"; the id without one
+	Fields     []string `json:"fields,omitempty"`      // fields shown as detail rows
+	Items      string   `json:"items,omitempty"`       // has-many model listed as line items, e.g. "OrderItem"
+	ItemFields []string `json:"item_fields,omitempty"` // item fields shown as table columns
+	Total      string   `json:"total,omitempty"`       // field shown as the total
+}
+
+// Title returns the document's heading: "invoice" → "Invoice",
+// "packing slip" → "Packing Slip".
+func (d *Document) Title() string {
+	words := strings.Fields(d.Kind)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// FieldLabel turns a field name into a label for a document:
+// "order_number" → "Order Number", "shippingCost" → "Shipping Cost".
+func FieldLabel(name string) string {
+	var words []string
+	var cur []rune
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			if len(cur) > 0 {
+				words = append(words, string(cur))
+			}
+			cur = nil
+			continue
+		case r >= 'A' && r <= 'Z' && len(cur) > 0:
+			words = append(words, string(cur))
+			cur = nil
+		}
+		cur = append(cur, r)
+	}
+	if len(cur) > 0 {
+		words = append(words, string(cur))
+	}
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// ParseDocumentStep reports whether a step generates a PDF, returning the
+// kind of document and the model named after "from" or "for":
+//
+//	generate a PDF invoice from the Order      → "invoice", "Order"
+//	generate a PDF receipt for the payment     → "receipt", "payment"
+//	generate a PDF from the Report             → "document", "Report"
+func ParseDocumentStep(text string) (kind, model string, ok bool) {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	if !strings.HasPrefix(lower, "generate ") || !strings.Contains(lower, "pdf") {
+		return "", "", false
+	}
+	words := strings.Fields(strings.Trim(text, ".\"' "))
+	from := -1
+	for i, w := range words {
+		if lw := strings.ToLower(w); i > 1 && (lw == "from" || lw == "for") {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return "", "", false
+	}
+	var kindWords []string
+	for _, w := range words[1:from] {
+		switch lw := strings.ToLower(w); lw {
+		case "a", "an", "the", "pdf":
+		default:
+			kindWords = append(kindWords, lw)
+		}
+	}
+	for _, w := range words[from+1:] {
+		switch strings.ToLower(w) {
+		case "a", "an", "the", "this", "each", "every":
+			continue
+		}
+		model = strings.Trim(w, ".,:;\"'")
+		break
+	}
+	if model == "" {
+		return "", "", false
+	}
+	kind = strings.Join(kindWords, " ")
+	if kind == "" {
+		kind = "document"
+	}
+	return kind, model, true
+}
+
+// DocumentFor returns the document a "generate a PDF ..." step renders,
+// or nil if the step renders none.
+func DocumentFor(app *Application, text string) *Document {
+	kind, model, ok := ParseDocumentStep(text)
+	if !ok || app == nil {
+		return nil
+	}
+	for _, doc := range app.Documents {
+		if doc.Kind == kind && (strings.EqualFold(doc.Model, model) || strings.EqualFold(doc.Model+"s", model)) {
+			return doc
+		}
+	}
+	return nil
+}
+
+// PDFStyle returns the theme's primary color as "#rrggbb" and the standard
+// PDF font family closest to its body font: Helvetica, Times, or Courier.
+// Without a theme, documents are dark gray in Helvetica.
+func PDFStyle(theme *Theme) (color, font string) {
+	color, font = "#1f2937", "Helvetica"
+	if theme == nil {
+		return color, font
+	}
+	if c := strings.ToLower(strings.TrimSpace(theme.Colors["primary"])); strings.HasPrefix(c, "#") {
+		switch len(c) {
+		case 7:
+			color = c
+		case 4:
+			color = "#" + string([]byte{c[1], c[1], c[2], c[2], c[3], c[3]})
+		}
+	}
+	family := strings.ToLower(theme.Fonts["body"])
+	switch {
+	case strings.Contains(family, "mono") || strings.Contains(family, "courier") || strings.Contains(family, "code"):
+		font = "Courier"
+	case strings.Contains(family, "sans"):
+		// "Open Sans", "sans-serif": keep Helvetica.
+	case strings.Contains(family, "serif") || strings.Contains(family, "times") || strings.Contains(family, "georgia") ||
+		strings.Contains(family, "garamond") || strings.Contains(family, "merriweather") || strings.Contains(family, "playfair") ||
+		strings.Contains(family, "lora") || strings.Contains(family, "baskerville"):
+		font = "Times"
+	}
+	return color, font
+}
+
+// ── Sitemap ──
+
+// Sitemap describes what search engines may crawl in a web app: its public
+// pages, a URL for each record of a public model, and the pages kept out.
+// Backends serve it as /sitemap.xml and /robots.txt, and frontends point
+// each page's canonical link at URL.
+type Sitemap struct {
+	URL     string           `json:"url,omitempty"`     // site origin, e.g. "https://shophub.example.com"; the request's host without one
+	Pages   []string         `json:"pages"`             // public page paths, e.g. "/", "/about"
+	Private []string         `json:"private,omitempty"` // page paths disallowed in robots.txt
+	Records []*SitemapRecord `json:"records,omitempty"` // dynamic routes for public models
+}
+
+// SitemapRecord lists one URL per record of a public model, on its detail
+// page: "/product-detail?id=<id>", or "/products/<id>" when the page takes
+// the id from its route.
+type SitemapRecord struct {
+	Model  string `json:"model"`            // e.g. "Product"
+	Path   string `json:"path"`             // detail page path, e.g. "/product-detail" or "/products"
+	Routed bool   `json:"routed,omitempty"` // whether the id is a path segment rather than a query
+}
+
+// Link returns a record's URL up to its id: "/product-detail?id=" or
+// "/products/".
+func (r *SitemapRecord) Link() string {
+	if r.Routed {
+		return r.Path + "/"
+	}
+	return r.Path + "?id="
+}
+
+// ── Accounts ──
+
+// Accounts is what users can do with their own account, from an
+// "accounts:" block:
+//
+//	accounts:
+//	  users can update their profile, change password, and delete their account
+//
+// Each adds endpoints acting on the signed-in user and a form on the
+// account page.
+type Accounts struct {
+	Model    string       `json:"model,omitempty"`    // the model users are stored in, e.g. "User"; empty when there's none
+	Page     string       `json:"page,omitempty"`     // the account page, e.g. "Settings"
+	Profile  bool         `json:"profile,omitempty"`  // "update their profile"
+	Password bool         `json:"password,omitempty"` // "change password"
+	Delete   bool         `json:"delete,omitempty"`   // "delete their account"
+	Invite   *Invitations `json:"invite,omitempty"`   // "invite teammates by email to their Organization"
+	Unknown  []string     `json:"unknown,omitempty"`  // statements that say none of these
+}
+
+// Invitations is how users invite teammates to their team, from an
+// accounts statement:
+//
+//	users can invite teammates by email to their Organization
+//	invitations expire in 3 days
+//
+// An invitation is emailed as a link to the accept page, and accepting it
+// makes the signed-in user a member of the team with the role they were
+// invited as.
+type Invitations struct {
+	Team    string   `json:"team"`              // the model teammates are members of, e.g. "Organization"
+	Expires int      `json:"expires"`           // days an invitation can be accepted in
+	Roles   []string `json:"roles,omitempty"`   // the roles teammates can be invited as: the app's policies
+	Role    string   `json:"role"`              // the role a teammate is invited as when none is chosen
+	Page    string   `json:"page,omitempty"`    // the page members invite teammates on, e.g. "Team"
+	Accept  string   `json:"accept,omitempty"`  // the page an invitation links to, e.g. "AcceptInvite"
+	Missing string   `json:"missing,omitempty"` // the team named when the app has no such model
+}
+
+// What an endpoint added by the accounts block does, in Endpoint.Account.
+const (
+	AccountProfile        = "profile"         // responds with the signed-in user's profile
+	AccountUpdateProfile  = "update_profile"  // changes the fields of their profile
+	AccountChangePassword = "change_password" // changes their password, given the current one
+	AccountDelete         = "delete"          // deletes their account, given their password
+	AccountInvite         = "invite"          // invites a teammate by email to the signed-in user's team
+	AccountInvitation     = "invitation"      // responds with the invitation a token is for
+	AccountAcceptInvite   = "accept_invite"   // makes the signed-in user a member, given an invitation's token
+)
+
+// The models invitations add: a team's pending invitations, and who is a
+// member of which team with what role.
+const (
+	InvitationModel = "Invitation"
+	MembershipModel = "Membership"
+)
+
+// DefaultInviteExpiry is how many days an invitation can be accepted in
+// when the accounts block doesn't say.
+const DefaultInviteExpiry = 7
+
+// MinPasswordLength is the fewest characters a new password may have.
+const MinPasswordLength = 8
+
+// DeleteConfirmation is what a user types to confirm deleting their
+// account.
+const DeleteConfirmation = "DELETE"
+
+// ProfileFields returns the fields of a user's profile: the fields of the
+// users' model but their password, which only a password change sets, their
+// role, which only admins set, and files, which are uploaded on their own.
+func ProfileFields(app *Application) []*DataField {
+	m := AccountModel(app)
+	if m == nil {
+		return nil
+	}
+	var fields []*DataField
+	for _, f := range m.Fields {
+		lower := strings.ToLower(f.Name)
+		if strings.Contains(lower, "password") || lower == "role" || f.Type == "file" || f.Type == "image" {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// AccountModel returns the model users are stored in when the app has an
+// accounts block, or nil.
+func AccountModel(app *Application) *DataModel {
+	if app.Accounts == nil || app.Accounts.Model == "" {
+		return nil
+	}
+	return modelNamed(app, app.Accounts.Model)
+}
+
+// AccountEndpoint returns the endpoint the accounts block added to do
+// what, one of AccountProfile, AccountUpdateProfile, AccountChangePassword,
+// and AccountDelete, or nil.
+func AccountEndpoint(app *Application, what string) *Endpoint {
+	for _, ep := range app.APIs {
+		if ep.Account == what {
+			return ep
+		}
+	}
+	return nil
+}
+
+// IsAccountPage reports whether page is the account page the accounts
+// block added.
+func IsAccountPage(app *Application, page *Page) bool {
+	return app.Accounts != nil && app.Accounts.Page != "" && page.Name == app.Accounts.Page && len(page.Content) == 0
+}
+
+// AcceptPath returns the path of the page an invitation links to, e.g.
+// "/accept-invite"; its token follows in the query string.
+func (inv *Invitations) AcceptPath() string {
+	return pagePath(inv.Accept)
+}
+
+// pagePath returns the route a page is served at: its name in kebab case,
+// e.g. "/accept-invite".
+func pagePath(name string) string {
+	var path []rune
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			path = append(path, '-')
+		}
+		path = append(path, unicode.ToLower(r))
+	}
+	return "/" + string(path)
+}
+
+// InviteSetsUserRole reports whether accepting an invitation also sets the
+// users' model's role field, the one the policies check, to the role the
+// teammate was invited as. Only a text role can hold any policy's name.
+func InviteSetsUserRole(app *Application) bool {
+	m := AccountModel(app)
+	if m == nil || app.Accounts.Invite == nil {
+		return false
+	}
+	f := m.FieldNamed("role")
+	return f != nil && f.Type == "text"
+}
+
+// IsInvitePage reports whether page is one of the pages invitations added:
+// the team page members invite teammates on, or the page an invitation
+// links to.
+func IsInvitePage(app *Application, page *Page) bool {
+	if app.Accounts == nil || app.Accounts.Invite == nil || len(page.Content) > 0 {
+		return false
+	}
+	inv := app.Accounts.Invite
+	return page.Name == inv.Page || page.Name == inv.Accept
+}
+
+// ── Billing ──
+
+// Billing is the subscription plans users can buy, from a "plans:" block:
+//
+//	plans: Free, Pro ($19/month), Enterprise
+//
+// Paid plans are bought through Stripe Checkout, and Stripe's webhook keeps
+// each user's subscription in step. A policy named after a plan, e.g.
+// "policy ProPlan:", gates what its subscribers can do.
+type Billing struct {
+	Plans    []*Plan  `json:"plans"`
+	Currency string   `json:"currency"`          // the ISO 4217 code prices are in, lowercase as Stripe takes it
+	Model    string   `json:"model,omitempty"`   // the model subscribers are stored in; empty when there's none or no payment integration
+	Page     string   `json:"page,omitempty"`    // the pricing page, e.g. "Pricing"
+	Unknown  []string `json:"unknown,omitempty"` // entries that aren't a plan
+}
+
+// Plan is one subscription plan: free, paid every month or year, or
+// custom, with no listed price, for plans sold by talking to sales.
+type Plan struct {
+	Name     string `json:"name"`
+	Price    int    `json:"price,omitempty"`    // in cents per interval; 0 for a free or custom plan
+	Interval string `json:"interval,omitempty"` // "month" or "year" for a paid plan
+	Custom   bool   `json:"custom,omitempty"`   // no listed price: subscribers contact sales
+	Policy   string `json:"policy,omitempty"`   // the policy gating what subscribers can do, e.g. "ProPlan"
+}
+
+// The models billing adds: the plans, with the Stripe price each paid one
+// is bought at, and each user's subscription to one.
+const (
+	PlanModel         = "Plan"
+	SubscriptionModel = "Subscription"
+)
+
+// PlanUpgradeStatus is the HTTP status of a request a user's plan doesn't
+// allow: 402 Payment Required, which the frontend answers with a link to
+// the pricing page.
+const PlanUpgradeStatus = 402
+
+// Bills reports whether the app sells subscriptions: it has plans, a
+// users' model to subscribe, and a payment integration to charge them.
+func Bills(app *Application) bool {
+	return app.Billing != nil && app.Billing.Model != "" && len(app.Billing.Plans) > 0
+}
+
+// Paid reports whether a plan is bought through Stripe.
+func (p *Plan) Paid() bool {
+	return p.Price > 0
+}
+
+// FreePlan returns the plan users are on until they subscribe to another,
+// or nil when every plan costs something.
+func (b *Billing) FreePlan() *Plan {
+	for _, p := range b.Plans {
+		if !p.Paid() && !p.Custom {
+			return p
+		}
+	}
+	return nil
+}
+
+// Gated reports whether any plan has a policy, so subscribers' requests
+// are checked against their plan.
+func (b *Billing) Gated() bool {
+	for _, p := range b.Plans {
+		if p.Policy != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// PagePath returns the path of the pricing page, e.g. "/pricing", which
+// Stripe Checkout and the billing portal return to.
+func (b *Billing) PagePath() string {
+	return pagePath(b.Page)
+}
+
+// IsPricingPage reports whether page is the pricing page billing added.
+func IsPricingPage(app *Application, page *Page) bool {
+	return Bills(app) && page.Name == app.Billing.Page && len(page.Content) == 0
+}
+
+// PlanLimit is a plan policy's "can create up to 3 projects per month":
+// how many records of a model a subscriber to the plan may create.
+type PlanLimit struct {
+	Plan   string // the plan, e.g. "Free"
+	Model  string // the model counted, e.g. "Project"; empty when there's no such model
+	Word   string // the model as the policy names it, singular and lowercase, e.g. "project"
+	Limit  int
+	Period string // "day", "week", "month", or "year"; empty for all time
+}
+
+// planLimitPattern matches a policy permission limiting how many records
+// a user creates.
+var planLimitPattern = regexp.MustCompile(`^create up to (\d+) (\w+)(?: per (day|week|month|year))?`)
+
+// PlanLimits returns the creation limits of the plans' policies.
+func PlanLimits(app *Application) []*PlanLimit {
+	if app.Billing == nil {
+		return nil
+	}
+	var limits []*PlanLimit
+	for _, plan := range app.Billing.Plans {
+		for _, pol := range app.Policies {
+			if pol.Name != plan.Policy {
+				continue
+			}
+			for _, perm := range pol.Permissions {
+				m := planLimitPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(perm.Text)))
+				if m == nil {
+					continue
+				}
+				n, _ := strconv.Atoi(m[1])
+				limit := &PlanLimit{Plan: plan.Name, Word: strings.TrimSuffix(m[2], "s"), Limit: n, Period: m[3]}
+				if model := modelNamed(app, m[2]); model != nil {
+					limit.Model, limit.Word = model.Name, strings.ToLower(model.Name)
+				}
+				limits = append(limits, limit)
+			}
+		}
+	}
+	return limits
+}
+
+// Counted reports whether a plan limit can be enforced: its model belongs
+// to the users' model, so a subscriber's records can be counted.
+func (l *PlanLimit) Counted(app *Application) bool {
+	m := modelNamed(app, l.Model)
+	if m == nil || app.Billing == nil {
+		return false
+	}
+	for _, rel := range m.Relations {
+		if rel.Kind == "belongs_to" && rel.Target == app.Billing.Model {
+			return true
+		}
+	}
+	return false
+}
+
+// ── Usage metering ──
+
+// Metering is the app's "meter API calls per user per day": each user's
+// calls to the APIs that require authentication are counted per day, week,
+// or month. A usage dashboard shows the counts, a policy's "make up to 1000
+// API calls per day" caps them, and when the app bills, each call is also
+// reported to a Stripe meter for metered prices.
+type Metering struct {
+	Period string `json:"period"`          // "day", "week", or "month": how calls are counted
+	Model  string `json:"model,omitempty"` // the users' model calls are counted for; empty when there's none
+	Page   string `json:"page,omitempty"`  // the usage dashboard, e.g. "Usage"
+}
+
+// UsageModel is the model metering adds: a user's calls in one period.
+const UsageModel = "UsageRecord"
+
+// QuotaExceededStatus is the HTTP status of a call past the user's quota:
+// 429 Too Many Requests, with a Retry-After header saying when the quota
+// resets.
+const QuotaExceededStatus = 429
+
+// Meters reports whether the app counts calls: it meters them and has a
+// users' model to count them for.
+func Meters(app *Application) bool {
+	return app.Metering != nil && app.Metering.Model != ""
+}
+
+// MetersEndpoint reports whether calls to an endpoint are counted: it
+// requires authentication, so the caller is known. The accounts block's
+// endpoints aren't, so a user past their quota can still manage their
+// account.
+func MetersEndpoint(app *Application, ep *Endpoint) bool {
+	return Meters(app) && ep.Auth && ep.Account == ""
+}
+
+// IsUsagePage reports whether page is the usage dashboard metering added.
+func IsUsagePage(app *Application, page *Page) bool {
+	return Meters(app) && page.Name == app.Metering.Page && len(page.Content) == 0
+}
+
+// UsageQuota is a policy's "make up to 1000 API calls per day": how many
+// calls a user it applies to may make in a period.
+type UsageQuota struct {
+	Policy string // the policy, e.g. "FreePlan"
+	Plan   string // the plan the policy gates, e.g. "Free"; empty for a role's policy
+	Limit  int
+	Period string // "day", "week", or "month"
+}
+
+// usageQuotaPattern matches a policy permission capping a user's calls.
+var usageQuotaPattern = regexp.MustCompile(`up to (\d+) (?:api )?(?:calls|requests)(?: (?:per|a|each) (day|week|month))?`)
+
+// UsageQuotas returns the call caps of the app's policies. A cap without
+// a period counts calls per the metering period.
+func UsageQuotas(app *Application) []*UsageQuota {
+	var quotas []*UsageQuota
+	for _, pol := range app.Policies {
+		for _, perm := range pol.Permissions {
+			m := usageQuotaPattern.FindStringSubmatch(strings.ToLower(perm.Text))
+			if m == nil {
+				continue
+			}
+			n, _ := strconv.Atoi(m[1])
+			q := &UsageQuota{Policy: pol.Name, Limit: n, Period: m[2]}
+			if q.Period == "" && app.Metering != nil {
+				q.Period = app.Metering.Period
+			}
+			if app.Billing != nil {
+				for _, plan := range app.Billing.Plans {
+					if plan.Policy == pol.Name {
+						q.Plan = plan.Name
+					}
+				}
+			}
+			quotas = append(quotas, q)
+			break
+		}
+	}
+	return quotas
+}
+
+// Enforced reports whether a quota is checked: calls are metered, its
+// period is made of whole metering periods, and its policy is found for a
+// user — from their plan when the app bills, else from their role.
+func (q *UsageQuota) Enforced(app *Application) bool {
+	if !Meters(app) || q.Period != app.Metering.Period && app.Metering.Period != "day" {
+		return false
+	}
+	if Bills(app) {
+		return q.Plan != ""
+	}
+	return QuotaByRole(app)
+}
+
+// QuotaByRole reports whether a user's quota comes from the policy named
+// after their role: the app doesn't bill, and its users' model has a role.
+func QuotaByRole(app *Application) bool {
+	if !Meters(app) || Bills(app) {
+		return false
+	}
+	m := modelNamed(app, app.Metering.Model)
+	return m != nil && m.FieldNamed("role") != nil
+}
+
+// ── API keys ──
+
+// APIKeys is the authentication block's "external clients authenticate
+// with API keys": besides signing in, users create keys that scripts and
+// other servers send in the X-API-Key header to call the APIs as them. A
+// key is shown once, when it's created, and stored only as its hash.
+type APIKeys struct {
+	Model string `json:"model,omitempty"` // the users' model keys belong to; empty when there's none
+	Page  string `json:"page,omitempty"`  // the page users manage their keys on, e.g. "ApiKeys"
+}
+
+// APIKeyModel is the model API keys add: a user's key, by its hash.
+const APIKeyModel = "ApiKey"
+
+// APIKeyHeader is the header a client sends its API key in.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyPrefix starts every API key, so a leaked one is recognizable.
+const APIKeyPrefix = "hk_"
+
+// The scopes an API key can have. A read key can only make GET requests;
+// a write key can make any.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// IssuesAPIKeys reports whether external clients can authenticate with API
+// keys: the app says so and has a users' model for keys to belong to.
+func IssuesAPIKeys(app *Application) bool {
+	return app.Auth != nil && app.Auth.APIKeys != nil && app.Auth.APIKeys.Model != ""
+}
+
+// IsAPIKeysPage reports whether page is the page API keys added for users
+// to manage their keys on.
+func IsAPIKeysPage(app *Application, page *Page) bool {
+	return IssuesAPIKeys(app) && page.Name == app.Auth.APIKeys.Page && len(page.Content) == 0
+}
+
+// APIKeyRole reports whether a key's user's role, the one the policies
+// check, can be read from their record: the users' model has a role field.
+func APIKeyRole(app *Application) bool {
+	if !IssuesAPIKeys(app) {
+		return false
+	}
+	m := modelNamed(app, app.Auth.APIKeys.Model)
+	return m != nil && m.FieldNamed("role") != nil
+}
+
+// ── Seed Data ──
+
+// Seed is how many sample records of a model the generated seed scripts
+// insert into the development database, from a "seed" line or a line of
+// a "seed:" block:
+//
+//	seed 50 Users with realistic names and emails
+//
+// Models without one are seeded with the fixtures' default count. The
+// "with" clause describes the records for the reader; their values come
+// from the fixture faker, by each field's name and type.
+type Seed struct {
+	Model string `json:"model,omitempty"` // the data model, e.g. "User"; empty when the line names none
+	Count int    `json:"count,omitempty"` // 0 when the line doesn't start with one
+	With  string `json:"with,omitempty"`  // e.g. "realistic names and emails"
+	Text  string `json:"text"`            // the line as written, e.g. "50 Users with realistic names and emails"
+	Line  int    `json:"line,omitempty"`
+}
+
+// SeedPassword is the password of every seeded record with a password
+// field, hashed by the seed scripts, so developers can sign in as any
+// seeded user. Development databases only.
+const SeedPassword = "password123"
+
+// SeedCounts returns how many records of each model the app's seed lines
+// ask for, by model name.
+func SeedCounts(app *Application) map[string]int {
+	counts := map[string]int{}
+	for _, s := range app.Seeds {
+		if s.Model != "" && s.Count > 0 {
+			counts[s.Model] = s.Count
+		}
+	}
+	return counts
+}
+
+// ── Migrations ──
+
+// Migration is a step in the history of the app's database schema: the
+// changes to its data models since the step before. The build records one
+// each time the data models change (see internal/migrate), and the
+// backends generate it as a migration of their own framework.
+type Migration struct {
+	Name    string          `json:"name"` // e.g. "0002_add_task_priority"
+	Changes []*SchemaChange `json:"changes"`
+}
+
+// Schema change kinds.
+const (
+	CreateModel  = "create_model"
+	DropModel    = "drop_model"
+	AddField     = "add_field"
+	DropField    = "drop_field"
+	RenameField  = "rename_field"
+	ChangeField  = "change_field"
+	AddRelation  = "add_relation"
+	DropRelation = "drop_relation"
+)
+
+// SchemaChange is one change to the data models. Dropped models and fields
+// keep their definitions, so a change can be reverted.
+type SchemaChange struct {
+	Kind   string     `json:"kind"`
+	Model  string     `json:"model"`
+	Data   *DataModel `json:"data,omitempty"`   // the model created or dropped
+	Field  *DataField `json:"field,omitempty"`  // the field added, or as it is now
+	From   *DataField `json:"from,omitempty"`   // the field dropped, or as it was
+	Target string     `json:"target,omitempty"` // the belongs_to target linked or unlinked
+}
+
+// ── Build Provenance ──
+
+// BuildHeader is the response header the generated backends send their
+// BuildInfo in, so a deployed instance says which build it came from.
+const BuildHeader = "X-Human-Build"
+
+// BuildInfo records which compiler generated an app's code, from which IR,
+// and when. The build writes it to build-info.json, and the generated
+// backends send it in the X-Human-Build header.
+type BuildInfo struct {
+	Compiler    string `json:"compiler"`     // compiler version, e.g. "0.4.0"
+	IRHash      string `json:"ir_hash"`      // "sha256:" and the digest of the IR's JSON; see Hash
+	GeneratedAt string `json:"generated_at"` // RFC 3339, in UTC
+
+	// Fsync has the generators flush the files they write to disk, and
+	// EmitWorkers sets how many they write at once (the default when 0);
+	// the build sets them from config.Fsync and config.EmitWorkers.
+	Fsync       bool `json:"-"`
+	EmitWorkers int  `json:"-"`
+}
+
+// NewBuildInfo returns the BuildInfo for generating app's code with the
+// given compiler version at the given time.
+func NewBuildInfo(app *Application, compiler string, at time.Time) (*BuildInfo, error) {
+	hash, err := Hash(app)
+	if err != nil {
+		return nil, err
+	}
+	return &BuildInfo{
+		Compiler:    compiler,
+		IRHash:      hash,
+		GeneratedAt: at.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// Header returns the X-Human-Build header value, e.g.
+// "human/0.4.0; ir=sha256:9f86d0…; generated=2026-03-01T12:00:00Z".
+func (b *BuildInfo) Header() string {
+	return fmt.Sprintf("human/%s; ir=%s; generated=%s", b.Compiler, b.IRHash, b.GeneratedAt)
+}
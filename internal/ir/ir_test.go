@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/barun-bash/human/internal/parser"
 )
@@ -36,7 +37,8 @@ build with:
   frontend using React with TypeScript
   backend using Node with Express
   database using PostgreSQL
-  deploy to Docker`
+  deploy to Docker
+  api style is gRPC`
 
 	app := mustBuild(t, source)
 
@@ -55,6 +57,9 @@ build with:
 	if app.Config.Deploy != "Docker" {
 		t.Errorf("deploy: got %q", app.Config.Deploy)
 	}
+	if app.Config.APIStyle != "gRPC" {
+		t.Errorf("api style: got %q", app.Config.APIStyle)
+	}
 }
 
 // ── Data Models ──
@@ -836,10 +841,14 @@ func TestInferIntegrationType(t *testing.T) {
 		{"PayPal", "payment"},
 		{"Slack", "messaging"},
 		{"Discord", "messaging"},
-		{"Twilio", "messaging"},
+		{"Twilio", "sms"},
 		{"Google", "oauth"},
 		{"GitHub", "oauth"},
 		{"Auth0", "oauth"},
+		{"PostHog", "analytics"},
+		{"Segment", "analytics"},
+		{"Mixpanel", "analytics"},
+		{"Amplitude", "analytics"},
 		{"AWS SES", "email"},
 		{"SES", "email"},
 		{"SessionManager", ""}, // should NOT match "ses"
@@ -879,6 +888,104 @@ func TestBuildEnvironment(t *testing.T) {
 	}
 }
 
+func TestEnvironmentURL(t *testing.T) {
+	app := mustBuild(t, `environment staging: url is staging.App.example.com
+environment production: url is app.example.com`)
+
+	staging := app.Environments[0]
+	if got := staging.URL(); got != "https://staging.App.example.com" {
+		t.Errorf("URL: got %q", got)
+	}
+	if got := staging.Host(); got != "staging.app.example.com" {
+		t.Errorf("Host: got %q", got)
+	}
+	if env := PublicEnvironment(app); env == nil || env.Name != "production" {
+		t.Errorf("PublicEnvironment should prefer production, got %+v", env)
+	}
+
+	app.Environments[1].Config = map[string]string{}
+	if env := PublicEnvironment(app); env != staging {
+		t.Errorf("PublicEnvironment should fall back to staging, got %+v", env)
+	}
+	if got := (&Environment{Config: map[string]string{}}).URL(); got != "" {
+		t.Errorf("URL without a url: got %q", got)
+	}
+}
+
+func TestEnvironmentURLAsWritten(t *testing.T) {
+	tests := []struct {
+		line string
+		url  string
+		host string
+	}{
+		{"url is https://staging.my-shop.io:8443", "https://staging.my-shop.io:8443", "staging.my-shop.io"},
+		{"url is http://localhost:3000/app/v1", "http://localhost:3000/app/v1", "localhost"},
+		{"url is app.example.com/shop", "https://app.example.com/shop", "app.example.com"},
+		{"url is app.example.com:8080", "https://app.example.com:8080", "app.example.com"},
+		{"url is my-shop.io/", "https://my-shop.io", "my-shop.io"},
+		{"url is api.my-cool-shop.co.uk  # the public site", "https://api.my-cool-shop.co.uk", "api.my-cool-shop.co.uk"},
+		{"URL is Shop.Example.com", "https://Shop.Example.com", "shop.example.com"},
+		{`url is "https://my-shop.io:8443/app"`, "https://my-shop.io:8443/app", "my-shop.io"},
+	}
+	for _, tt := range tests {
+		for _, source := range []string{
+			"environment production:\n  " + tt.line + "\n",
+			"environment production: " + tt.line + "\n",
+		} {
+			app := mustBuild(t, source)
+			env := app.Environments[0]
+			if got := env.URL(); got != tt.url {
+				t.Errorf("%q: URL got %q, want %q", source, got, tt.url)
+			}
+			if got := env.Host(); got != tt.host {
+				t.Errorf("%q: Host got %q, want %q", source, got, tt.host)
+			}
+		}
+	}
+}
+
+func TestEnvironmentURLFromWords(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"staging example com", "https://staging.example.com"},
+		{"https: staging my-shop io: 8443", "https://staging.my-shop.io:8443"},
+		{"http: localhost: 3000", "http://localhost:3000"},
+	}
+	for _, tt := range tests {
+		env := &Environment{Config: map[string]string{"url": tt.url}}
+		if got := env.URL(); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestTargetEnvironment(t *testing.T) {
+	app := mustBuild(t, `page Home:
+  show a heading
+environment production: url is app.example.com`)
+
+	if env := TargetEnvironment(app); env != nil || AllowedOrigin(app) != "" {
+		t.Errorf("a local build targets no environment, got %+v", env)
+	}
+	app.Config = &BuildConfig{Env: "Production", Deploy: "Docker"}
+	env := TargetEnvironment(app)
+	if env == nil || env.Name != "production" {
+		t.Fatalf("TargetEnvironment: got %+v", env)
+	}
+	if got := env.APIURL(app); got != "https://app.example.com" {
+		t.Errorf("APIURL behind the frontend's proxy: got %q", got)
+	}
+	app.Config.Deploy = "AWS"
+	if got := env.APIURL(app); got != "https://api.app.example.com" {
+		t.Errorf("APIURL on AWS: got %q", got)
+	}
+	if got := AllowedOrigin(app); got != "https://app.example.com" {
+		t.Errorf("AllowedOrigin: got %q", got)
+	}
+}
+
 // ── Error Handlers ──
 
 func TestBuildErrorHandler(t *testing.T) {
@@ -906,6 +1013,57 @@ func TestBuildErrorHandler(t *testing.T) {
 	}
 }
 
+// ── Experiments ──
+
+func TestBuildExperiment(t *testing.T) {
+	source := `experiment "pricing-page" splits visitors 50/50 between Classic and NewPricing pages
+experiment "onboarding" splits users between WelcomeA, WelcomeB, and WelcomeC pages`
+
+	app := mustBuild(t, source)
+
+	if len(app.Experiments) != 2 {
+		t.Fatalf("expected 2 experiments, got %d", len(app.Experiments))
+	}
+
+	exp := app.Experiments[0]
+	if exp.Name != "pricing-page" {
+		t.Errorf("name: got %q", exp.Name)
+	}
+	if exp.Audience != "visitors" {
+		t.Errorf("audience: got %q", exp.Audience)
+	}
+	if len(exp.Variants) != 2 {
+		t.Fatalf("expected 2 variants, got %d", len(exp.Variants))
+	}
+	if exp.Variants[0].Page != "Classic" || exp.Variants[0].Weight != 50 {
+		t.Errorf("variant 0: got %s %d%%", exp.Variants[0].Page, exp.Variants[0].Weight)
+	}
+	if exp.Variants[1].Page != "NewPricing" || exp.Variants[1].Weight != 50 {
+		t.Errorf("variant 1: got %s %d%%", exp.Variants[1].Page, exp.Variants[1].Weight)
+	}
+
+	// No percentages: split evenly, remainder to the control
+	even := app.Experiments[1]
+	if even.Audience != "users" {
+		t.Errorf("audience: got %q", even.Audience)
+	}
+	var weights []int
+	for _, v := range even.Variants {
+		weights = append(weights, v.Weight)
+	}
+	if len(weights) != 3 || weights[0] != 34 || weights[1] != 33 || weights[2] != 33 {
+		t.Errorf("weights: got %v, want [34 33 33]", weights)
+	}
+	if even.Variants[2].Page != "WelcomeC" {
+		t.Errorf("variant 2: got %q", even.Variants[2].Page)
+	}
+
+	// Experiments are not monitoring rules
+	if len(app.Monitoring) != 0 {
+		t.Errorf("expected no monitoring rules, got %d", len(app.Monitoring))
+	}
+}
+
 // ── Action Classification ──
 
 func TestClassifyAction(t *testing.T) {
@@ -1053,6 +1211,89 @@ func TestToYAMLEmptyCollections(t *testing.T) {
 	}
 }
 
+func TestFromYAMLRoundTrip(t *testing.T) {
+	_, thisFile, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(thisFile), "..", "..")
+	files, _ := filepath.Glob(filepath.Join(root, "examples", "*", "app.human"))
+	if len(files) == 0 {
+		t.Fatal("no examples found")
+	}
+
+	for _, file := range files {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prog, err := parser.Parse(string(source))
+		if err != nil {
+			continue // examples with errors are covered elsewhere
+		}
+		app, err := Build(prog)
+		if err != nil {
+			continue
+		}
+		yaml, err := ToYAML(app)
+		if err != nil {
+			t.Fatalf("%s: ToYAML: %v", file, err)
+		}
+		back, err := FromYAML(yaml)
+		if err != nil {
+			t.Fatalf("%s: FromYAML: %v", file, err)
+		}
+		want, _ := ToJSON(app)
+		got, _ := ToJSON(back)
+		if string(got) != string(want) {
+			t.Errorf("%s: IR changed reading it back from YAML", filepath.Base(filepath.Dir(file)))
+		}
+	}
+}
+
+func TestFromYAML(t *testing.T) {
+	app, err := FromYAML(`name: Shop
+platform: web
+config:
+  ports:
+    backend: 3001
+data:
+  - name: Order
+    fields:
+      - name: total
+        type: number
+        required: true
+      - name: status
+        type: enum
+        required: false
+        enum_values:
+          - placed
+          - "true"
+          - "key: value"
+integrations:
+  - service: Stripe
+    credentials:
+      api key: STRIPE_KEY
+    config: {}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.Name != "Shop" || app.Config.Ports.Backend != 3001 {
+		t.Errorf("got name %q, backend port %d", app.Name, app.Config.Ports.Backend)
+	}
+	if len(app.Data) != 1 || len(app.Data[0].Fields) != 2 || !app.Data[0].Fields[0].Required {
+		t.Fatalf("data = %+v", app.Data)
+	}
+	if got := strings.Join(app.Data[0].Fields[1].EnumValues, ","); got != "placed,true,key: value" {
+		t.Errorf("enum values = %s", got)
+	}
+	if app.Integrations[0].Credentials["api key"] != "STRIPE_KEY" {
+		t.Errorf("credentials = %v", app.Integrations[0].Credentials)
+	}
+
+	if _, err := FromYAML("name: Shop\n    platform: web\n"); err == nil {
+		t.Error("expected an error for unexpected indentation")
+	}
+}
+
 func TestYAMLKeyOrdering(t *testing.T) {
 	app := &Application{
 		Name:     "OrderTest",
@@ -1426,3 +1667,2115 @@ func TestFullIntegration(t *testing.T) {
 	// Log the YAML output for manual inspection
 	t.Logf("YAML output length: %d bytes", len(yaml))
 }
+
+func TestBuildNotification(t *testing.T) {
+	source := `data User:
+  has a name which is text
+
+data Task:
+  belongs to a User
+  has a title which is text
+
+api AssignTask:
+  requires authentication
+  accepts task_id and user_id
+  update the Task with the assignee
+  respond with the updated task
+
+api UpdateTask:
+  requires authentication
+  accepts task_id and title
+  update the Task with the given fields
+  respond with the updated task
+
+when a task is completed:
+  notify the owner in the app
+
+notify the user in the app when their task is assigned`
+
+	app := mustBuild(t, source)
+
+	if len(app.Notifications) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(app.Notifications))
+	}
+	n := app.Notifications[0]
+	if n.Model != "Task" || n.Event != "assigned" {
+		t.Errorf("got %s %s, want Task assigned", n.Model, n.Event)
+	}
+	if n.Message != "Your task was assigned" {
+		t.Errorf("message: got %q", n.Message)
+	}
+	if wf := app.Notifications[1]; wf.Model != "Task" || wf.Event != "completed" {
+		t.Errorf("workflow notification: got %s %s, want Task completed", wf.Model, wf.Event)
+	}
+
+	if !n.Triggers(app.APIs[0]) {
+		t.Error("AssignTask should trigger the assigned notification")
+	}
+	if n.Triggers(app.APIs[1]) {
+		t.Error("UpdateTask should not trigger the assigned notification")
+	}
+	if got := n.RecipientParam(app.APIs[0]); got != "user_id" {
+		t.Errorf("recipient param: got %q, want user_id", got)
+	}
+
+	// The Notification model is declared and linked to User
+	var model *DataModel
+	for _, m := range app.Data {
+		if m.Name == "Notification" {
+			model = m
+		}
+	}
+	if model == nil {
+		t.Fatal("expected a Notification data model")
+	}
+	if len(model.Relations) != 1 || model.Relations[0].Target != "User" {
+		t.Errorf("Notification should belong to User, got %+v", model.Relations)
+	}
+	user := app.Data[0]
+	if last := user.Relations[len(user.Relations)-1]; last.Kind != "has_many" || last.Target != "Notification" {
+		t.Errorf("User should have many Notifications, got %+v", last)
+	}
+}
+
+func TestEventStem(t *testing.T) {
+	tests := map[string]string{
+		"assigned":  "assign",
+		"created":   "creat",
+		"shipped":   "ship",
+		"copied":    "cop",
+		"published": "publish",
+	}
+	for event, want := range tests {
+		if got := eventStem(event); got != want {
+			t.Errorf("eventStem(%q) = %q, want %q", event, got, want)
+		}
+	}
+}
+
+func TestParseSMSStep(t *testing.T) {
+	tests := []struct {
+		text string
+		want *SMSMessage
+	}{
+		{`send an SMS "Your order has shipped"`, &SMSMessage{Channel: "sms", Body: "Your order has shipped"}},
+		{`send a text message to the driver`, &SMSMessage{Channel: "sms"}},
+		{`send a text message to the driver saying Pickup is ready`, &SMSMessage{Channel: "sms", Body: "Pickup is ready"}},
+		{`send an SMS Your order has shipped`, &SMSMessage{Channel: "sms", Body: "Your order has shipped"}},
+		{`send WhatsApp message with template "order-shipped"`, &SMSMessage{Channel: "whatsapp", Template: "order-shipped"}},
+		{`send welcome email with template "welcome"`, nil},
+	}
+	for _, tt := range tests {
+		got, ok := ParseSMSStep(tt.text)
+		if tt.want == nil {
+			if ok {
+				t.Errorf("ParseSMSStep(%q) matched, want no match", tt.text)
+			}
+			continue
+		}
+		if !ok || *got != *tt.want {
+			t.Errorf("ParseSMSStep(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSMSRetryPolicy(t *testing.T) {
+	app := mustBuild(t, `if sending an SMS fails:
+  retry 5 times with 2 second delay
+
+if database is unreachable:
+  retry 2 times with 1 second delay`)
+
+	if attempts, delayMs := SMSRetryPolicy(app); attempts != 5 || delayMs != 2000 {
+		t.Errorf("got %d attempts %dms apart, want 5 attempts 2000ms apart", attempts, delayMs)
+	}
+	if attempts, delayMs := SMSRetryPolicy(&Application{}); attempts != 3 || delayMs != 1000 {
+		t.Errorf("default: got %d attempts %dms apart, want 3 attempts 1000ms apart", attempts, delayMs)
+	}
+}
+
+func TestChaosFaults(t *testing.T) {
+	app := mustBuild(t, `if database is unreachable:
+  retry 3 times with 2 second delay
+  if still failing, respond with "service temporarily unavailable"
+  alert the engineering team via Slack
+
+if an api request fails validation:
+  respond with a clear message explaining what is wrong
+
+if payment provider times out:
+  retry 2 times with 500 millisecond delay
+
+if an upload fails:
+  log the error`)
+
+	if !InjectsChaos(app) {
+		t.Fatal("expected InjectsChaos with error handlers")
+	}
+	if InjectsChaos(&Application{}) {
+		t.Error("expected no chaos mode without error handlers")
+	}
+	want := []ChaosFault{
+		{Condition: "database is unreachable", Attempts: 3, DelayMs: 2000, Status: 503, Message: "Service temporarily unavailable", Alert: "alert the engineering team via Slack"},
+		{Condition: "an api request fails validation", Attempts: 1, Status: 400, Message: "The request is invalid."},
+		{Condition: "payment provider times out", Attempts: 2, DelayMs: 500, Status: 504, Message: "The request timed out. Please try again."},
+		{Condition: "an upload fails", Attempts: 1, Status: 500, Message: "An unexpected error occurred. Please try again later."},
+	}
+	got := ChaosFaults(app)
+	if len(got) != len(want) {
+		t.Fatalf("got %d faults, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("fault %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBuildCalendar(t *testing.T) {
+	source := `data Event:
+  has a name which is text
+  has a description which is text
+  has a location which is text
+  has a starts_at which is datetime
+  has an ends_at which is datetime
+  has a created which is datetime
+
+data Holiday:
+  has a title which is text
+  has a day which is date
+
+users can add an Event to their calendar in "America/New_York"
+anyone can add Holidays to a calendar in UTC`
+
+	app := mustBuild(t, source)
+	if len(app.Calendars) != 2 {
+		t.Fatalf("expected 2 calendars, got %d", len(app.Calendars))
+	}
+
+	events := app.Calendars[0]
+	want := CalendarFeed{
+		Model: "Event", Slug: "events", Title: "name", Start: "starts_at", End: "ends_at",
+		Location: "location", Description: "description", Timezone: "America/New_York",
+	}
+	if *events != want {
+		t.Errorf("events calendar = %+v, want %+v", *events, want)
+	}
+
+	holidays := app.Calendars[1]
+	if holidays.Model != "Holiday" || holidays.Slug != "holidays" || holidays.Start != "day" || !holidays.AllDay {
+		t.Errorf("unexpected holidays calendar: %+v", *holidays)
+	}
+	if holidays.Timezone != "UTC" || holidays.Title != "title" {
+		t.Errorf("unexpected holidays defaults: %+v", *holidays)
+	}
+}
+
+func TestBuildCalendarIgnoresOtherStatements(t *testing.T) {
+	app := mustBuild(t, `data Event:
+  has a name which is text
+
+users can add a comment to the event`)
+	if len(app.Calendars) != 0 {
+		t.Errorf("expected no calendars, got %+v", app.Calendars[0])
+	}
+}
+
+func TestBuildCalendarUnquotedTimezone(t *testing.T) {
+	app := mustBuild(t, `data Event:
+  has a name which is text
+  has a date which is date
+
+users can add an Event to their calendar in America/New_York`)
+	if len(app.Calendars) != 1 || app.Calendars[0].Timezone != "America/New_York" {
+		t.Fatalf("expected timezone America/New_York, got %+v", app.Calendars)
+	}
+}
+
+func TestBuildDocument(t *testing.T) {
+	source := `data Order:
+  has an order_number which is unique text
+  has a status which is text
+  has a subtotal which is decimal
+  has a total which is decimal
+  has a created datetime
+  has many OrderItem
+
+data OrderItem:
+  belongs to an Order
+  has a quantity which is number
+  has a unit_price which is decimal
+
+api CreateOrder:
+  accepts items
+  create an Order with the given fields
+  generate a PDF invoice from the Order
+  respond with the created order
+
+when an order is delivered:
+  generate a PDF invoice from the order
+  generate a PDF packing slip for the Order`
+
+	app := mustBuild(t, source)
+	if len(app.Documents) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(app.Documents))
+	}
+
+	invoice := app.Documents[0]
+	if invoice.Kind != "invoice" || invoice.Model != "Order" || invoice.Slug != "order-invoices" || invoice.Title() != "Invoice" {
+		t.Errorf("unexpected invoice: %+v", *invoice)
+	}
+	if invoice.Reference != "order_number" || invoice.Total != "total" {
+		t.Errorf("expected reference order_number and total total, got %q and %q", invoice.Reference, invoice.Total)
+	}
+	if got := strings.Join(invoice.Fields, ","); got != "status,subtotal" {
+		t.Errorf("fields = %s, want status,subtotal", got)
+	}
+	if invoice.Items != "OrderItem" || strings.Join(invoice.ItemFields, ",") != "quantity,unit_price" {
+		t.Errorf("unexpected items: %s %v", invoice.Items, invoice.ItemFields)
+	}
+
+	slip := app.Documents[1]
+	if slip.Kind != "packing slip" || slip.Slug != "order-packing-slips" || slip.Title() != "Packing Slip" {
+		t.Errorf("unexpected packing slip: %+v", *slip)
+	}
+	if DocumentFor(app, "generate a PDF invoice from the Order") != invoice {
+		t.Error("expected DocumentFor to find the invoice")
+	}
+}
+
+func TestParseDocumentStep(t *testing.T) {
+	tests := []struct {
+		text, kind, model string
+		ok                bool
+	}{
+		{"generate a PDF invoice from the Order", "invoice", "Order", true},
+		{"generate a PDF receipt for the payment", "receipt", "payment", true},
+		{"generate a PDF from the Report", "document", "Report", true},
+		{"generate slug from name", "", "", false},
+		{"generate unique order number", "", "", false},
+	}
+	for _, tt := range tests {
+		kind, model, ok := ParseDocumentStep(tt.text)
+		if ok != tt.ok || kind != tt.kind || model != tt.model {
+			t.Errorf("ParseDocumentStep(%q) = %q, %q, %v; want %q, %q, %v", tt.text, kind, model, ok, tt.kind, tt.model, tt.ok)
+		}
+	}
+}
+
+func TestPDFStyle(t *testing.T) {
+	color, font := PDFStyle(nil)
+	if color != "#1f2937" || font != "Helvetica" {
+		t.Errorf("default style = %s %s", color, font)
+	}
+	theme := &Theme{Colors: map[string]string{"primary": "#1A1A2E"}, Fonts: map[string]string{"body": "Merriweather"}}
+	if color, font = PDFStyle(theme); color != "#1a1a2e" || font != "Times" {
+		t.Errorf("themed style = %s %s", color, font)
+	}
+	theme.Fonts["body"] = "Open Sans"
+	if _, font = PDFStyle(theme); font != "Helvetica" {
+		t.Errorf("Open Sans should map to Helvetica, got %s", font)
+	}
+}
+
+func TestFieldLabel(t *testing.T) {
+	for in, want := range map[string]string{"order_number": "Order Number", "shippingCost": "Shipping Cost", "total": "Total"} {
+		if got := FieldLabel(in); got != want {
+			t.Errorf("FieldLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuildSitemap(t *testing.T) {
+	source := `app Shop is a web application
+
+data Product:
+  has a name which is text
+
+data Order:
+  belongs to a User
+  has a total which is decimal
+
+data User:
+  has a name which is text
+
+page Home:
+  show a heading
+
+page ProductDetail:
+  show the product name
+
+page OrderDetail:
+  show the order total
+
+page Login:
+  there is a form to log in
+
+environment staging:
+  url is staging.shop.example.com
+
+environment production:
+  url is shop.example.com`
+
+	app := mustBuild(t, source)
+	sm := app.Sitemap
+	if sm == nil {
+		t.Fatal("expected a sitemap")
+	}
+	if sm.URL != "https://shop.example.com" {
+		t.Errorf("URL = %q, want the production environment's", sm.URL)
+	}
+	if got := strings.Join(sm.Pages, ","); got != "/,/product-detail,/order-detail" {
+		t.Errorf("pages = %s", got)
+	}
+	if len(sm.Private) != 0 {
+		t.Errorf("without authentication no page is private, got %v", sm.Private)
+	}
+	if len(sm.Records) != 1 || sm.Records[0].Model != "Product" || sm.Records[0].Path != "/product-detail" {
+		t.Errorf("expected Product records only, got %+v", sm.Records)
+	}
+}
+
+func TestBuildSitemapWithAuth(t *testing.T) {
+	source := `app Shop is a web application
+
+data Product:
+  has a name which is text
+
+page Home:
+  show a heading
+
+page ProductDetail:
+  show the product name
+
+page SignUp:
+  there is a form to sign up
+
+authentication:
+  method JWT tokens that expire in 7 days`
+
+	app := mustBuild(t, source)
+	sm := app.Sitemap
+	if sm == nil {
+		t.Fatal("expected a sitemap")
+	}
+	if sm.URL != "" {
+		t.Errorf("expected no URL without environments, got %q", sm.URL)
+	}
+	if got := strings.Join(sm.Pages, ","); got != "/" {
+		t.Errorf("pages = %s, want /", got)
+	}
+	if got := strings.Join(sm.Private, ","); got != "/product-detail" {
+		t.Errorf("private = %s, want /product-detail", got)
+	}
+	if len(sm.Records) != 0 {
+		t.Errorf("protected detail pages should list no records, got %+v", sm.Records)
+	}
+}
+
+func TestBuildSitemapNonWeb(t *testing.T) {
+	app := mustBuild(t, "app Shop is a mobile application\n\npage Home:\n  show a heading")
+	if app.Sitemap != nil {
+		t.Error("only web apps get a sitemap")
+	}
+}
+
+func TestEncryptsAtRest(t *testing.T) {
+	app := mustBuild(t, `app Clinic is a web application
+
+data Patient:
+  has a password which is encrypted text
+  has a ssn which is encrypted text
+  has an age which is encrypted number
+  has a name which is text`)
+
+	patient := app.Data[0]
+	got := patient.EncryptedFields()
+	if len(got) != 1 || got[0].Name != "ssn" {
+		t.Fatalf("expected only ssn to be encrypted at rest, got %v", got)
+	}
+	if !UsesFieldEncryption(app) {
+		t.Error("UsesFieldEncryption should be true")
+	}
+
+	plain := mustBuild(t, "app Shop is a web application\n\ndata User:\n  has a password which is encrypted text")
+	if UsesFieldEncryption(plain) {
+		t.Error("passwords are hashed, not encrypted at rest")
+	}
+}
+
+func TestParseBackupSchedule(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"daily at 3 am", "0 3 * * *"},
+		{"daily at 3: 30 am", "30 3 * * *"},
+		{"every night at midnight", "0 0 * * *"},
+		{"nightly", "0 0 * * *"},
+		{"at 11 pm", "0 23 * * *"},
+		{"every 6 hours", "0 */6 * * *"},
+		{"hourly", "0 * * * *"},
+		{"weekly on sunday at 2: 00 pm", "0 14 * * 0"},
+		{"every friday at 1 am", "0 1 * * 5"},
+		{"weekly", "0 0 * * 0"},
+		{"monthly at 4 am", "0 4 1 * *"},
+	}
+	for _, tt := range tests {
+		got, ok := ParseBackupSchedule(tt.text)
+		if !ok || got != tt.want {
+			t.Errorf("ParseBackupSchedule(%q) = %q, %v; want %q", tt.text, got, ok, tt.want)
+		}
+	}
+
+	for _, text := range []string{"sometimes", "daily at 25 am", "every 0 hours"} {
+		if got, ok := ParseBackupSchedule(text); ok {
+			t.Errorf("ParseBackupSchedule(%q) = %q, want not understood", text, got)
+		}
+	}
+}
+
+func TestBuildBackup(t *testing.T) {
+	app := mustBuild(t, `app Shop is a web application
+
+data Product:
+  has a name which is text
+
+database:
+  use PostgreSQL
+  backup daily at 3am
+  keep backups for 4 weeks
+
+integrate with AWS S3:
+  api key from environment variable AWS_ACCESS_KEY
+  secret from environment variable AWS_SECRET_KEY`)
+
+	b := app.Database.Backup
+	if b == nil {
+		t.Fatal("expected a backup policy")
+	}
+	if b.Schedule != "0 3 * * *" {
+		t.Errorf("Schedule: got %q, want %q", b.Schedule, "0 3 * * *")
+	}
+	if b.RetentionDays != 28 {
+		t.Errorf("RetentionDays: got %d, want 28", b.RetentionDays)
+	}
+	if b.Storage != "AWS S3" {
+		t.Errorf("Storage: got %q, want %q", b.Storage, "AWS S3")
+	}
+	if len(app.Database.Rules) != 2 {
+		t.Errorf("backup rules should stay in Rules, got %d", len(app.Database.Rules))
+	}
+
+	none := mustBuild(t, "app Shop is a web application\n\ndatabase:\n  use PostgreSQL")
+	if none.Database.Backup != nil {
+		t.Error("no backup rule should mean no backup policy")
+	}
+}
+
+func TestPersonalFields(t *testing.T) {
+	app := mustBuild(t, `app Clinic is a web application
+
+data Patient:
+  has a name which is text
+  has an email which is unique personal email
+  has a phone which is personal text
+
+data Doctor:
+  has a phone which is personal text`)
+
+	if !app.Data[0].Fields[1].Personal || app.Data[0].Fields[0].Personal {
+		t.Error("only fields marked personal should be personal")
+	}
+	if got := PersonalFieldNames(app); strings.Join(got, ",") != "email,phone" {
+		t.Errorf("PersonalFieldNames: got %v", got)
+	}
+	if app.Data[0].Fields[2].Encrypted {
+		t.Error("personal alone shouldn't encrypt a field")
+	}
+}
+
+func TestComplianceProfile(t *testing.T) {
+	app := mustBuild(t, `app Clinic is a web application
+
+data Patient:
+  has a name which is text
+  has an email which is unique email
+  has a phone which is text
+  has a birthday which is date
+
+build with:
+  database using PostgreSQL
+  compliance profile is soc 2`)
+
+	profile := Compliance(app)
+	if profile == nil || profile.Name != "SOC2" {
+		t.Fatalf("Compliance: got %+v", profile)
+	}
+	if !AuditsRequests(app) {
+		t.Error("a compliance profile should turn on audit logging")
+	}
+	encrypted := map[string]bool{}
+	for _, f := range app.Data[0].Fields {
+		encrypted[f.Name] = f.EncryptsAtRest()
+	}
+	want := map[string]bool{"name": false, "email": false, "phone": true, "birthday": false}
+	for name, w := range want {
+		if encrypted[name] != w {
+			t.Errorf("%s encrypted: got %v, want %v", name, encrypted[name], w)
+		}
+	}
+	if got := PersonalFieldNames(app); strings.Join(got, ",") != "birthday,email,phone" {
+		t.Errorf("personal data should be masked, got %v", got)
+	}
+	b := app.Database.Backup
+	if b == nil || b.Schedule != DefaultBackupSchedule || b.RetentionDays != 30 {
+		t.Errorf("expected a default daily backup kept 30 days, got %+v", b)
+	}
+	if got := LogRetentionDays(app); got != 365 {
+		t.Errorf("LogRetentionDays: got %d, want 365", got)
+	}
+
+	declared := mustBuild(t, "app Clinic is a web application\n\nbuild with:\n  compliance profile is HIPAA-lite\n\nkeep logs for 90 days")
+	if got := LogRetentionDays(declared); got != 90 {
+		t.Errorf("a declared retention should win, got %d", got)
+	}
+	if got := Compliance(declared); got == nil || got.Name != "HIPAA" {
+		t.Errorf("HIPAA-lite: got %+v", got)
+	}
+
+	none := mustBuild(t, "app Clinic is a web application\n\ndata Patient:\n  has a phone which is text")
+	if Compliance(none) != nil || none.Data[0].Fields[0].Encrypted || LogRetentionDays(none) != DefaultLogRetentionDays {
+		t.Error("without a profile nothing should change")
+	}
+}
+
+func TestStyling(t *testing.T) {
+	app := mustBuild(t, "app Blog is a web application\n\nbuild with:\n  frontend using React\n  styling using CSS modules")
+	if app.Config.Styling != "CSS modules" {
+		t.Fatalf("Styling: got %q", app.Config.Styling)
+	}
+	if got := Styling(app); got != StylingCSSModules {
+		t.Errorf("Styling(app): got %q, want %q", got, StylingCSSModules)
+	}
+
+	for name, want := range map[string]string{
+		"Tailwind":          StylingTailwind,
+		"Tailwind CSS":      StylingTailwind,
+		"vanilla-extract":   StylingVanillaExtract,
+		"styled components": StylingStyledComponents,
+		"plain CSS":         "",
+	} {
+		if got, ok := LookupStyling(name); !ok || got != want {
+			t.Errorf("LookupStyling(%q): got %q, %v; want %q", name, got, ok, want)
+		}
+	}
+	if _, ok := LookupStyling("Sass"); ok {
+		t.Error("Sass is not a styling system")
+	}
+
+	app.Config.Frontend = "Vue"
+	if got := Styling(app); got != "" {
+		t.Errorf("CSS modules style React only, got %q for Vue", got)
+	}
+	app.Config.Styling = "Tailwind"
+	if got := Styling(app); got != StylingTailwind {
+		t.Errorf("Tailwind styles any frontend, got %q for Vue", got)
+	}
+}
+
+func TestBuildReplicaAndPool(t *testing.T) {
+	app := mustBuild(t, `app Shop is a web application
+
+database:
+  use PostgreSQL
+  use a read replica for heavy queries
+  pool up to 20 connections`)
+
+	db := app.Database
+	if db.Engine != "PostgreSQL" {
+		t.Errorf("Engine: got %q, want %q — the replica rule shouldn't replace it", db.Engine, "PostgreSQL")
+	}
+	if !db.ReadReplica {
+		t.Error("expected ReadReplica")
+	}
+	if db.PoolSize != 20 {
+		t.Errorf("PoolSize: got %d, want 20", db.PoolSize)
+	}
+	if !PoolsConnections(app) || !UsesReadReplica(app) {
+		t.Error("expected PoolsConnections and UsesReadReplica")
+	}
+
+	app.Config = &BuildConfig{Database: "MySQL"}
+	if PoolsConnections(app) {
+		t.Error("PgBouncer is only generated for PostgreSQL")
+	}
+	if UsesMongoDB(app) {
+		t.Error("MySQL isn't MongoDB")
+	}
+
+	app.Config = &BuildConfig{Database: "MongoDB"}
+	if !UsesMongoDB(app) {
+		t.Error("expected UsesMongoDB")
+	}
+	if UsesReadReplica(app) {
+		t.Error("MongoDB drivers read from the replica set themselves")
+	}
+}
+
+func TestWorkflowJobs(t *testing.T) {
+	app := mustBuild(t, `app Blog is a web application
+
+when a post is submitted for review:
+  notify all admins via email
+  after 48 hours, if still in review,
+    send reminder email to admins
+
+when a user signs up:
+  send welcome email
+  after 3 days, send email with template "getting-started"
+  after all retries fail, alert the admin`)
+
+	if !UsesJobQueue(app) {
+		t.Fatal("an app with workflows should use the job queue")
+	}
+	jobs := WorkflowJobs(app.Workflows[0])
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2", len(jobs))
+	}
+	if jobs[0].Name != "post-is-submitted-for-review" || jobs[0].Delay != 0 || len(jobs[0].Steps) != 1 {
+		t.Errorf("first job: %+v", jobs[0])
+	}
+	later := jobs[1]
+	if later.Name != "post-is-submitted-for-review-after-48-hours" || later.Delay != 48*time.Hour || later.After != "48 hours" {
+		t.Errorf("delayed job: %+v", later)
+	}
+	if len(later.Steps) == 0 || later.Steps[0].Text != "if still in review," {
+		t.Errorf("delayed job should start with the text after the delay: %+v", later.Steps)
+	}
+
+	jobs = WorkflowJobs(app.Workflows[1])
+	if len(jobs) != 2 || jobs[1].Delay != 72*time.Hour {
+		t.Fatalf("got %+v, want a job 3 days later", jobs)
+	}
+	if n := len(jobs[1].Steps); n != 2 || jobs[1].Steps[1].Text != "after all retries fail, alert the admin" {
+		t.Errorf("a delay that isn't a time span should stay a step: %+v", jobs[1].Steps)
+	}
+
+	if UsesJobQueue(mustBuild(t, "app Blog is a web application")) {
+		t.Error("an app without workflows needs no job queue")
+	}
+}
+
+func TestShowTemplate(t *testing.T) {
+	app := mustBuild(t, `app Greeter is a web application
+
+page Profile:
+  show "Hello, {user.name}! {1} {user}"
+  show a list of users`)
+
+	actions := app.Pages[0].Content
+	if actions[0].Template != "Hello, {user.name}! {1} {user}" {
+		t.Errorf("Template: got %q", actions[0].Template)
+	}
+	if actions[1].Template != "" {
+		t.Errorf("show without a string should have no template: %q", actions[1].Template)
+	}
+
+	parts := ParseTemplate(actions[0].Template)
+	want := []TemplatePart{
+		{Text: "Hello, "},
+		{Root: "user", Field: "name"},
+		{Text: "! {1} "},
+		{Root: "user"},
+	}
+	if len(parts) != len(want) {
+		t.Fatalf("got %+v, want %+v", parts, want)
+	}
+	for i := range want {
+		if parts[i] != want[i] {
+			t.Errorf("part %d: got %+v, want %+v", i, parts[i], want[i])
+		}
+	}
+
+	m := &DataModel{Fields: []*DataField{{Name: "created_at"}}}
+	if m.FieldNamed("createdAt") == nil || m.FieldNamed("created") != nil {
+		t.Error("FieldNamed should ignore case and underscores only")
+	}
+}
+
+func TestTableFor(t *testing.T) {
+	app := mustBuild(t, `app Team is a web application
+
+data Team:
+  has a name which is text
+
+data User:
+  has a name which is text
+  has an email which is email
+  has a password which is text
+  has a role which is text
+  has an optional last_active which is datetime
+
+page Users:
+  show a table of all users in the team sorted by name descending
+  each row shows the user name, email, and last active date
+  support sorting by role or last active date
+  clicking a user navigates to UserDetail
+  clicking a user opens a detail panel
+
+page UserDetail:
+  show the user's name
+
+page Plain:
+  show users in a table`)
+
+	users := app.Pages[0]
+	if m := TableModel(app, users.Content[0]); m == nil || m.Name != "User" {
+		t.Fatalf("TableModel: got %v, want User", m)
+	}
+	table := TableFor(app, users, "User")
+	if table == nil || table.Show != users.Content[0] {
+		t.Fatal("expected the table shown by the first action")
+	}
+	var cols []string
+	for _, f := range table.Columns {
+		cols = append(cols, f.Name)
+	}
+	if got := strings.Join(cols, ","); got != "name,email,last_active" {
+		t.Errorf("columns: got %s", got)
+	}
+	if table.SortBy == nil || table.SortBy.Name != "name" || !table.Desc {
+		t.Errorf("sort: got %v desc=%v, want name descending", table.SortBy, table.Desc)
+	}
+	var sortable []string
+	for _, f := range table.Sortable {
+		sortable = append(sortable, f.Name)
+	}
+	if got := strings.Join(sortable, ","); got != "name,last_active" {
+		t.Errorf("sortable: got %s (role is not a column)", got)
+	}
+	if table.RowPage != "UserDetail" {
+		t.Errorf("RowPage: got %q", table.RowPage)
+	}
+	if !table.Covers(users.Content[1]) || !table.Covers(users.Content[3]) || table.Covers(users.Content[4]) {
+		t.Error("the table should cover the row loop and the navigating click only")
+	}
+
+	plain := TableFor(app, app.Pages[2], "User")
+	if plain == nil || len(plain.Columns) != 4 || len(plain.Sortable) != 0 || plain.RowPage != "" {
+		t.Errorf("a bare table shows every field but the password, unsorted: %+v", plain)
+	}
+	if TableFor(app, app.Pages[1], "User") != nil {
+		t.Error("a page without a table has none")
+	}
+}
+
+func TestParseChart(t *testing.T) {
+	app := mustBuild(t, `app Shop is a web application
+
+data User:
+  has a name which is text
+  has an email which is email
+
+data Project:
+  has a title which is text
+
+data Product:
+  has a name which is text
+  has a category which is text
+  has a current_stock which is number
+
+data Expense:
+  belongs to a User
+  belongs to a Project
+  has an amount which is decimal
+  has a category which is text
+  has a date which is date
+
+page Dashboard:
+  show a bar chart of stock levels by category
+  show a line chart of signups per week
+  show a pie chart of total expenses by project
+  show a line chart of expenses over the last 30 days
+  show a line chart of signups per week
+  show a bar chart of expenses by team member`)
+
+	tests := []struct {
+		text string
+		want Chart
+	}{
+		{"show a bar chart of stock levels by category",
+			Chart{Kind: "bar", Title: "Stock levels by category", Slug: "stock-levels-by-category", Aggregate: Aggregate{Func: "sum", Field: "current_stock", Model: "Product", GroupBy: "category"}}},
+		{"show a line chart of signups per week",
+			Chart{Kind: "line", Title: "Signups per week", Slug: "signups-per-week", Aggregate: Aggregate{Func: "count", Model: "User", Period: "week"}}},
+		{"show a pie chart of total expenses by project",
+			Chart{Kind: "pie", Title: "Total expenses by project", Slug: "total-expenses-by-project", Aggregate: Aggregate{Func: "sum", Field: "amount", Model: "Expense", Relation: "Project", Label: "title"}}},
+		{"show a line chart of expenses over the last 30 days",
+			Chart{Kind: "line", Title: "Expenses over the last 30 days", Slug: "expenses-over-the-last-30-days", Aggregate: Aggregate{Func: "count", Model: "Expense", Period: "day", Date: "date", Days: 30}}},
+		{"show a bar chart of expenses by category in the last 7 days",
+			Chart{Kind: "bar", Title: "Expenses by category in the last 7 days", Slug: "expenses-by-category-in-the-last-7-days", Aggregate: Aggregate{Func: "count", Model: "Expense", GroupBy: "category", Date: "date", Days: 7}}},
+		{"show the monthly chart of users",
+			Chart{Kind: "line", Title: "Users", Slug: "users", Aggregate: Aggregate{Func: "count", Model: "User", Period: "month"}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseChart(app, tt.text)
+		if err != nil {
+			t.Errorf("%q: %v", tt.text, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("%q:\ngot  %+v\nwant %+v", tt.text, *got, tt.want)
+		}
+	}
+
+	if len(app.Charts) != 4 {
+		t.Errorf("expected 4 charts, one per slug, got %d", len(app.Charts))
+	}
+	if c := ChartFor(app, app.Pages[0].Content[0]); c == nil || c.Slug != "stock-levels-by-category" {
+		t.Errorf("ChartFor: got %v", c)
+	}
+
+	_, err := ParseChart(app, "show a bar chart of expenses by team member")
+	if ce, ok := err.(*AggregateError); !ok || ce.Model == nil || ce.Model.Name != "Expense" || ce.Phrase != "team member" {
+		t.Errorf("ungroupable chart: got %v", err)
+	}
+	_, err = ParseChart(app, "show a bar chart of the weather by city")
+	if ce, ok := err.(*AggregateError); !ok || ce.Model != nil || ce.Phrase != "the weather" {
+		t.Errorf("chart of no model: got %v", err)
+	}
+	_, err = ParseChart(app, "show a bar chart of expenses")
+	if ce, ok := err.(*AggregateError); !ok || ce.Model == nil || ce.Phrase != "" {
+		t.Errorf("chart without a grouping: got %v", err)
+	}
+}
+
+func TestParseAggregate(t *testing.T) {
+	app := mustBuild(t, `app Shop is a web application
+
+data User:
+  has a name which is text
+
+data Order:
+  belongs to a User
+  has an amount which is decimal
+  has a status which is text
+
+api MonthlyRevenue: sum the amount of Orders grouped by month for the last 12 months
+
+api GetOrdersByStatus:
+  count the Orders grouped by status
+  cache for 5 minutes
+
+api CreateOrder:
+  accepts amount
+  create an Order with the given fields
+  respond with the created order`)
+
+	tests := []struct {
+		text string
+		want Aggregate
+	}{
+		{"sum the amount of Orders grouped by month for the last 12 months",
+			Aggregate{Func: "sum", Field: "amount", Model: "Order", Period: "month", Days: 360}},
+		{"count the Orders grouped by status",
+			Aggregate{Func: "count", Model: "Order", GroupBy: "status"}},
+		{"average the amount of orders by user",
+			Aggregate{Func: "avg", Field: "amount", Model: "Order", Relation: "User", Label: "name"}},
+		{"highest amount of Orders per week",
+			Aggregate{Func: "max", Field: "amount", Model: "Order", Period: "week"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseAggregate(app, tt.text)
+		if err != nil {
+			t.Errorf("%q: %v", tt.text, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("%q:\ngot  %+v\nwant %+v", tt.text, *got, tt.want)
+		}
+	}
+
+	if ep := app.APIs[0]; ep.Aggregate == nil || ep.Aggregate.Period != "month" || ep.Cache != 60 {
+		t.Errorf("MonthlyRevenue: got %+v, cache %d", ep.Aggregate, ep.Cache)
+	}
+	if ep := app.APIs[1]; ep.Aggregate == nil || ep.Cache != 300 {
+		t.Errorf("GetOrdersByStatus: got %+v, cache %d", ep.Aggregate, ep.Cache)
+	}
+	if app.APIs[2].Aggregate != nil {
+		t.Error("CreateOrder is not a report")
+	}
+
+	_, err := ParseAggregate(app, "sum the Orders by status")
+	if ae, ok := err.(*AggregateError); !ok || !ae.NoField {
+		t.Errorf("expected a missing number field, got %v", err)
+	}
+	_, err = ParseAggregate(app, "count the invoices by status")
+	if ae, ok := err.(*AggregateError); !ok || ae.Model != nil {
+		t.Errorf("expected an unknown model, got %v", err)
+	}
+}
+
+func TestReorder(t *testing.T) {
+	app := mustBuild(t, `app Board is a web application
+
+data Task:
+  has a title which is text
+
+data Column:
+  has a name which is text
+  has a rank which is number
+
+page Home:
+  show a list of tasks
+  for each task, show its title
+  dragging a task reorders the list
+  dragging a column to rearrange the board
+  dragging the weather reorders the list`)
+
+	if len(app.Reorders) != 2 {
+		t.Fatalf("expected 2 reorderable lists, got %+v", app.Reorders)
+	}
+	if r := ReorderFor(app, "Task"); r == nil || *r != (Reorder{Model: "Task", Field: "position", Slug: "tasks"}) {
+		t.Errorf("Task reorder: got %+v", r)
+	}
+	if f := app.Data[0].FieldNamed("position"); f == nil || f.Type != "number" || f.Required {
+		t.Errorf("expected an optional position field on Task, got %+v", f)
+	}
+	if r := ReorderFor(app, "Column"); r == nil || r.Field != "rank" {
+		t.Errorf("Column should reorder by its rank field, got %+v", r)
+	}
+	if app.Data[1].FieldNamed("position") != nil {
+		t.Error("Column already has a position field and should not get another")
+	}
+	if m := ReorderModel(app, app.Pages[0].Content[4]); m != nil {
+		t.Errorf("dragging the weather names no model, got %s", m.Name)
+	}
+}
+
+func TestParseKeyBinding(t *testing.T) {
+	tests := []struct {
+		text string
+		want KeyBinding
+	}{
+		{"pressing Escape closes the modal", KeyBinding{Key: "Escape", Action: "close"}},
+		{"pressing the escape key dismisses the dialog", KeyBinding{Key: "Escape", Action: "close"}},
+		{"pressing N opens the form", KeyBinding{Key: "n", Action: "open-form"}},
+		{"pressing slash focuses the search", KeyBinding{Key: "/", Action: "focus-search"}},
+		{"pressing question mark opens Help", KeyBinding{Key: "?", Action: "navigate", Target: "Help"}},
+		{"pressing Ctrl K navigates to Search", KeyBinding{Key: "k", Ctrl: true, Action: "navigate", Target: "Search"}},
+		{"pressing Cmd+Enter submits the form", KeyBinding{Key: "Enter", Ctrl: true, Action: "submit"}},
+		{"pressing Shift Alt the up arrow goes to Dashboard", KeyBinding{Key: "ArrowUp", Shift: true, Alt: true, Action: "navigate", Target: "Dashboard"}},
+	}
+	for _, tt := range tests {
+		got, ok := ParseKeyBinding(tt.text)
+		if !ok {
+			t.Errorf("%q: not parsed", tt.text)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("%q:\ngot  %+v\nwant %+v", tt.text, *got, tt.want)
+		}
+	}
+
+	for _, text := range []string{
+		"pressing Escape",
+		"pressing Hyper closes the modal",
+		"pressing Escape sings a song",
+		"clicking Save closes the modal",
+	} {
+		if kb, ok := ParseKeyBinding(text); ok {
+			t.Errorf("%q: expected no binding, got %+v", text, kb)
+		}
+	}
+
+	page := &Page{Name: "Home", Content: []*Action{
+		{Type: "interact", Text: "pressing Escape closes the modal"},
+		{Type: "interact", Text: "pressing Escape sings a song"},
+		{Type: "interact", Text: "clicking Save saves the task"},
+	}}
+	if got := PageKeyBindings(page); len(got) != 1 || got[0].Key != "Escape" {
+		t.Errorf("PageKeyBindings: got %+v", got)
+	}
+}
+
+func TestInfiniteScroll(t *testing.T) {
+	app := mustBuild(t, `app Feed is a web application
+
+data Post:
+  has a title which is text
+
+data Comment:
+  has a body which is text
+
+data Tag:
+  has a name which is text
+
+page Home:
+  show a list of posts
+  for each post, show its title
+  scrolling to bottom loads more posts
+  scrolling down loads more weather
+  clicking "Remove" deletes the post
+  clicking the Trash button removes the post
+  clicking Save saves the post
+
+api ListPosts:
+  fetch all posts
+  respond with posts
+
+api ListComments:
+  fetch all comments
+  paginate with 50 per page
+  respond with comments
+
+api ListTags:
+  fetch all tags
+  respond with tags`)
+
+	if ep := app.APIs[0]; ep.PageSize != DefaultPageSize {
+		t.Errorf("ListPosts: a scrolling page should paginate it, got page size %d", ep.PageSize)
+	}
+	if ep := app.APIs[1]; ep.PageSize != 50 {
+		t.Errorf("ListComments: expected 50 per page, got %d", ep.PageSize)
+	}
+	if ep := app.APIs[2]; ep.PageSize != 0 {
+		t.Errorf("ListTags: nothing pages it, got page size %d", ep.PageSize)
+	}
+	if !ListsNewestFirst(app, app.APIs[0], "Post") || ListsNewestFirst(app, app.APIs[2], "Tag") {
+		t.Error("paginated lists, and only those, should list newest first")
+	}
+
+	content := app.Pages[0].Content
+	if m := ScrollModel(app, content[2]); m == nil || m.Name != "Post" {
+		t.Errorf("expected scrolling to load posts, got %+v", m)
+	}
+	if m := ScrollModel(app, content[3]); m != nil {
+		t.Errorf("the weather names no model, got %s", m.Name)
+	}
+	if !IsDelete(content[4]) || !IsDelete(content[5]) || IsDelete(content[6]) {
+		t.Error("IsDelete: expected the two delete clicks only")
+	}
+	if got := DeleteLabel(content[4]); got != "Remove" {
+		t.Errorf("DeleteLabel: got %q, want Remove", got)
+	}
+	if got := DeleteLabel(content[5]); got != "Trash" {
+		t.Errorf("DeleteLabel: got %q, want Trash", got)
+	}
+}
+
+func TestTooltipsAndDetailPanels(t *testing.T) {
+	app := mustBuild(t, `app Ledger is a web application
+
+data User:
+  has a name which is text
+  has an avatar which is text
+  has a password which is text
+
+data Transaction:
+  has an amount which is number
+  has a memo which is text
+  has a note which is encrypted text
+  has a date which is date
+
+page Home:
+  show a list of transactions
+  for each transaction, show its amount and memo
+  hovering over a user avatar shows their name
+  hovering over the memo shows the transaction's date
+  hovering over the help icon shows "Need help?"
+  hovering over the amount shows Excludes fees
+  clicking a transaction opens a detail panel on the right
+  clicking a user opens a detail panel on the left with their name and avatar
+  clicking the logo opens a panel
+
+component MemberAvatar:
+  accepts user as User
+  hovering shows the user's name as a tooltip`)
+
+	content := app.Pages[0].Content
+	tip := TooltipFor(app, content[2], "Transaction")
+	if tip == nil || tip.Model.Name != "User" || tip.Target == nil || tip.Target.Name != "avatar" || tip.Field == nil || tip.Field.Name != "name" {
+		t.Fatalf("expected the user's avatar to show their name, got %+v", tip)
+	}
+	tip = TooltipFor(app, content[3], "Transaction")
+	if tip == nil || tip.Model.Name != "Transaction" || tip.Target.Name != "memo" || tip.Field == nil || tip.Field.Name != "date" {
+		t.Errorf("expected the memo to show the transaction's date, got %+v", tip)
+	}
+	if tip = TooltipFor(app, content[4], "Transaction"); tip != nil {
+		t.Errorf("the help icon is no field, got %+v", tip)
+	}
+	tip = TooltipFor(app, content[5], "Transaction")
+	if tip == nil || tip.Target.Name != "amount" || tip.Field != nil || tip.Text != "Excludes fees" {
+		t.Errorf("expected fixed text over the amount, got %+v", tip)
+	}
+	tip = TooltipFor(app, app.Components[0].Content[0], "User")
+	if tip == nil || tip.Target != nil || tip.Field == nil || tip.Field.Name != "name" {
+		t.Errorf("expected the component to show the user's name, got %+v", tip)
+	}
+
+	if !IsDetailPanel(content[6]) || IsDetailPanel(content[2]) {
+		t.Error("IsDetailPanel: expected the panel click only")
+	}
+	p := DetailPanelFor(app, content[6])
+	if p == nil || p.Model.Name != "Transaction" || p.Side != "right" {
+		t.Fatalf("expected a transaction panel on the right, got %+v", p)
+	}
+	var names []string
+	for _, f := range p.Fields {
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, ","); got != "amount,memo,date" {
+		t.Errorf("expected every field but the encrypted one, got %s", got)
+	}
+	p = DetailPanelFor(app, content[7])
+	if p == nil || p.Side != "left" || len(p.Fields) != 2 || p.Fields[1].Name != "avatar" {
+		t.Errorf("expected a user panel on the left with name and avatar, got %+v", p)
+	}
+	if p = DetailPanelFor(app, content[8]); p != nil {
+		t.Errorf("the logo names no model, got %+v", p)
+	}
+	if !HasTooltips(app) || !HasDetailPanels(app) {
+		t.Error("expected the app to use tooltips and detail panels")
+	}
+}
+
+func TestDetailRoutes(t *testing.T) {
+	app := mustBuild(t, `app Journal is a web application
+
+data Post:
+  has a title which is text
+  has a slug which is text
+
+data Author:
+  has a name which is text
+
+page Home:
+  show a list of posts
+  each post shows its title
+  clicking a post navigates to PostDetail
+
+page PostDetail:
+  shows the Post with the id from the url
+
+page AuthorPage:
+  show the author with the name from the url
+
+api GetAuthor:
+  accepts name
+  fetch the Author by name
+  respond with the author`)
+
+	r := DetailRouteFor(app, "PostDetail")
+	if r == nil || *r != (DetailRoute{Page: "PostDetail", Model: "Post", Param: "id", Slug: "posts", Endpoint: "GetPost", Arg: "post_id"}) {
+		t.Fatalf("PostDetail route: got %+v", r)
+	}
+	if r.Path() != "/posts/:id" {
+		t.Errorf("expected /posts/:id, got %s", r.Path())
+	}
+	ep := app.APIs[len(app.APIs)-1]
+	if ep.Name != "GetPost" || len(ep.Params) != 1 || ep.Params[0].Name != "post_id" || ep.Steps[0].Text != "fetch the Post by post_id" {
+		t.Errorf("expected a GetPost endpoint fetching the post by post_id, got %+v", ep)
+	}
+	if r := DetailRouteFor(app, "AuthorPage"); r == nil || r.Param != "name" || r.Endpoint != "GetAuthor" || r.Arg != "name" || r.Path() != "/authors/:name" {
+		t.Errorf("AuthorPage route: got %+v", r)
+	}
+	if len(app.APIs) != 2 {
+		t.Errorf("GetAuthor already exists and should not be added again, got %d endpoints", len(app.APIs))
+	}
+
+	if page, a := ItemLinkFor(app, app.Pages[0], "Post"); page != "PostDetail" || a != app.Pages[0].Content[2] {
+		t.Errorf("expected Home's posts to link to PostDetail, got %q", page)
+	}
+	if prefix, field := RecordLink(app, "PostDetail"); prefix != "/posts/" || field != "id" {
+		t.Errorf("expected records linked at /posts/<id>, got %s<%s>", prefix, field)
+	}
+	if prefix, _ := RecordLink(app, "Home"); prefix != "/home?id=" {
+		t.Errorf("pages without a route take an id query, got %s", prefix)
+	}
+	if !LoadsRecord(app, ep) {
+		t.Error("GetPost loads PostDetail's record")
+	}
+}
+
+func TestLoadErrorMessage(t *testing.T) {
+	tests := []struct {
+		content []*Action
+		want    string
+	}{
+		{nil, "Could not load posts"},
+		{[]*Action{{Type: "condition", Text: "if there is an error, show the error message"}}, "Could not load posts"},
+		{[]*Action{{Type: "condition", Text: "if there is an error, show Could not reach the journal"}}, "Could not reach the journal"},
+		{[]*Action{{Type: "condition", Text: "if the save succeeds, show No errors"}}, "Could not load posts"},
+	}
+	for _, tt := range tests {
+		if got := LoadErrorMessage(&Page{Name: "Feed", Content: tt.content}, "posts"); got != tt.want {
+			t.Errorf("LoadErrorMessage(%v) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestHasSkeletons(t *testing.T) {
+	app := &Application{Pages: []*Page{{Name: "Feed", Content: []*Action{
+		{Type: "condition", Text: "while loading, show a spinner"},
+	}}}}
+	if HasSkeletons(app) {
+		t.Error("a spinner is not a skeleton screen")
+	}
+	app.Pages[0].Content = append(app.Pages[0].Content, &Action{Type: "condition", Text: "while loading, show a skeleton screen"})
+	if !HasSkeletons(app) {
+		t.Error("expected the skeleton screen to be found")
+	}
+}
+
+func TestResponsiveFor(t *testing.T) {
+	tests := []struct {
+		text string
+		want *Responsive
+	}{
+		{"show a list of posts", nil},
+		{"make the layout responsive for mobile and tablet", &Responsive{Mobile: true, Tablet: true}},
+		{"make the layout responsive for mobile", &Responsive{Mobile: true}},
+		{"make the layout responsive for tablets", &Responsive{Tablet: true}},
+		{"make the layout responsive", &Responsive{Mobile: true, Tablet: true}},
+	}
+	for _, tt := range tests {
+		page := &Page{Name: "Home", Content: []*Action{{Type: "configure", Text: tt.text}}}
+		got := ResponsiveFor(page)
+		if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+			t.Errorf("ResponsiveFor(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+		if HasResponsivePages(&Application{Pages: []*Page{page}}) != (tt.want != nil) {
+			t.Errorf("HasResponsivePages with %q should be %v", tt.text, tt.want != nil)
+		}
+	}
+}
+
+func TestBundleBudget(t *testing.T) {
+	app := mustBuild(t, "app Blog is a web application\n\nbuild with:\n  frontend using React\n  bundle budget is 200 KB per route")
+	if app.Config.BundleBudget != "200 KB per route" {
+		t.Fatalf("BundleBudget: got %q", app.Config.BundleBudget)
+	}
+	if got := BundleBudgetKB(app); got != 200 {
+		t.Errorf("BundleBudgetKB: got %d, want 200", got)
+	}
+
+	for text, want := range map[string]int{
+		"150kb":           150,
+		"300 KB":          300,
+		"80":              80,
+		"1 MB per page":   1024,
+		"120 kb per page": 120,
+	} {
+		if got, ok := ParseBundleBudget(text); !ok || got != want {
+			t.Errorf("ParseBundleBudget(%q): got %d, %v; want %d", text, got, ok, want)
+		}
+	}
+	for _, text := range []string{"small", "0 KB", "-5 KB", ""} {
+		if _, ok := ParseBundleBudget(text); ok {
+			t.Errorf("ParseBundleBudget(%q) should fail", text)
+		}
+	}
+
+	app.Config.BundleBudget = "small"
+	if got := BundleBudgetKB(app); got != DefaultBundleBudgetKB {
+		t.Errorf("an unreadable budget should fall back to the default, got %d", got)
+	}
+}
+
+func TestListQueryFor(t *testing.T) {
+	app := mustBuild(t, `app Tasks is a web application
+
+data Task:
+  has a title which is text
+  has a status which is either "todo" or "done"
+  has a done which is boolean
+  has an encrypted note which is text
+
+page Tasks:
+  show a table of tasks
+  each row shows the title and status
+  support sorting by title or status
+  there is a search bar to search tasks by title
+  there is a dropdown to filter by status
+
+page Feed:
+  show a list of tasks
+  each task shows its title
+  scrolling to bottom loads more tasks
+
+page Plain:
+  show a list of tasks
+  each task shows its title
+
+api ListTasks:
+  fetch all tasks
+  support filtering by status and done
+  support searching by title and note
+  paginate with 20 per page
+  respond with tasks`)
+
+	ep := app.APIs[0]
+	q := ListQueryFor(app, app.Pages[0], "Task", ep)
+	if q == nil || !q.Search || !q.Paged {
+		t.Fatalf("expected a searched, paged list, got %+v", q)
+	}
+	if len(q.Filters) != 1 || q.Filters[0].Name != "status" {
+		t.Errorf("filters: got %v, want status", q.Filters)
+	}
+	if len(q.Sortable) != 2 {
+		t.Errorf("sortable: got %d columns, want title and status", len(q.Sortable))
+	}
+	if q := ListQueryFor(app, app.Pages[0], "Task", nil); q == nil || q.Paged {
+		t.Error("a list loaded whole should be narrowed in the browser")
+	}
+	if q := ListQueryFor(app, app.Pages[1], "Task", ep); q != nil {
+		t.Errorf("a scrolling list isn't numbered, got %+v", q)
+	}
+	if q := ListQueryFor(app, app.Pages[2], "Task", ep); q == nil || !q.Paged || q.Search {
+		t.Errorf("a paginated list should be paged, got %+v", q)
+	}
+	if q := ListQueryFor(app, app.Pages[2], "Task", nil); q != nil {
+		t.Errorf("a plain list needs no query state, got %+v", q)
+	}
+
+	if RemembersView(app.Pages[0]) {
+		t.Error("a page without a remember statement shouldn't restore its list")
+	}
+	app.Pages[0].Content = append(app.Pages[0].Content, &Action{Type: "configure", Text: "remember the user's filters when they come back"})
+	if !RemembersView(app.Pages[0]) {
+		t.Error("expected the page to restore its list")
+	}
+
+	p := ListParamsFor(app, ep, "Task")
+	if p == nil {
+		t.Fatal("expected the list endpoint's params")
+	}
+	if len(p.Search) != 1 || p.Search[0].Name != "title" {
+		t.Errorf("search: got %v, want title (an encrypted field can't be searched)", p.Search)
+	}
+	if len(p.Filters) != 2 || !p.FiltersBy(p.Model.FieldNamed("done")) {
+		t.Errorf("filters: got %v, want status and done", p.Filters)
+	}
+	for _, f := range p.Sortable {
+		if f.Name == "note" {
+			t.Error("an encrypted field can't be sorted by")
+		}
+	}
+	if !p.Covers(ep.Steps[1]) || !p.Covers(ep.Steps[2]) || p.Covers(ep.Steps[0]) {
+		t.Error("the params should cover the filtering and searching steps only")
+	}
+	app.APIs[0].PageSize = 0
+	if ListParamsFor(app, ep, "Task") != nil {
+		t.Error("an endpoint that doesn't paginate reads no list params")
+	}
+}
+
+func TestEditFormFor(t *testing.T) {
+	app := mustBuild(t, `app Journal is a web application
+
+data Post:
+  has a title which is text
+  has a published which is boolean
+  has a created which is datetime
+  protect against conflicting edits
+
+data Comment:
+  has a body which is text
+
+page PostDetail:
+  shows the Post with the id from the url
+  there is a form to edit the post
+
+page Home:
+  show a list of posts
+  there is a form to edit the post
+
+api UpdatePost:
+  accepts post_id, title, published, and created
+  fetch the Post by post_id
+  update the post with the given fields
+  respond with the updated post
+
+api UpdateComment:
+  accepts comment_id and body
+  update the comment with the given fields
+  respond with the updated comment`)
+
+	if !ProtectsEdits(app) {
+		t.Error("expected the app to protect edits")
+	}
+	if m := ConflictModel(app, app.APIs[0]); m == nil || m.Name != "Post" {
+		t.Errorf("UpdatePost should be version-checked, got %v", m)
+	}
+	if m := ConflictModel(app, app.APIs[1]); m != nil {
+		t.Errorf("Comment isn't versioned, got %v", m)
+	}
+	if m := UpdatedModel(app, app.APIs[1]); m == nil || m.Name != "Comment" {
+		t.Errorf("UpdateComment edits Comment, got %v", m)
+	}
+
+	e := EditFormFor(app, app.Pages[0])
+	if e == nil {
+		t.Fatal("expected PostDetail to edit the post")
+	}
+	if e.Endpoint.Name != "UpdatePost" || e.Param != "post_id" || !e.Versioned || e.Form != app.Pages[0].Content[1] {
+		t.Errorf("got %+v", e)
+	}
+	var names []string
+	for _, f := range e.Fields {
+		names = append(names, f.Name)
+	}
+	if strings.Join(names, ",") != "title,published" {
+		t.Errorf("fields: got %v, want title and published (the creation time keeps itself)", names)
+	}
+	if EditFormFor(app, app.Pages[1]) != nil {
+		t.Error("a page without a detail route edits no record")
+	}
+}
+
+func TestImportFormFor(t *testing.T) {
+	app := mustBuild(t, `app Tracker is a web application
+
+data User:
+  has a name which is text
+  has a password which is text
+
+data Project:
+  has a name which is text
+
+data Task:
+  belongs to a User
+  belongs to a Project
+  has a title which is text
+  has a done which is boolean
+  has a created which is datetime
+
+page Tasks:
+  show a list of tasks
+  there is a form to import tasks
+
+page Projects:
+  show a list of projects
+  there is a form to import projects
+
+api ImportTasks:
+  requires authentication
+  import tasks from a csv file
+  skip invalid rows and import the rest
+
+api ImportProjects:
+  import projects from a json file
+
+authentication:
+  method JWT tokens that expire in 7 days`)
+
+	im := app.APIs[0].Import
+	if im == nil {
+		t.Fatal("expected ImportTasks to import")
+	}
+	if im.Model != "Task" || im.Mode != ImportContinueOnError || strings.Join(im.Formats, ",") != "csv" {
+		t.Errorf("got %+v", im)
+	}
+	if im.Accepts() != ".csv,text/csv" || im.Describe() != "CSV file" {
+		t.Errorf("accepts %q, describes %q", im.Accepts(), im.Describe())
+	}
+	if p := app.APIs[1].Import; p == nil || p.Mode != ImportAllOrNothing || strings.Join(p.Formats, ",") != "json" {
+		t.Errorf("ImportProjects: got %+v, want an all-or-nothing JSON import", p)
+	}
+
+	var names []string
+	for _, col := range ImportColumns(app.Data[2], true) {
+		names = append(names, col.Name)
+	}
+	if strings.Join(names, ",") != "title,done,project_id" {
+		t.Errorf("columns: got %v, want title, done, and project_id (the importing user owns each task)", names)
+	}
+	names = nil
+	for _, col := range ImportColumns(app.Data[0], false) {
+		names = append(names, col.Name)
+	}
+	if strings.Join(names, ",") != "name" {
+		t.Errorf("columns: got %v, passwords aren't imported", names)
+	}
+
+	f := ImportFormFor(app, app.Pages[0])
+	if f == nil {
+		t.Fatal("expected Tasks to have an import form")
+	}
+	if f.Model.Name != "Task" || f.Endpoint.Name != "ImportTasks" || f.Form != app.Pages[0].Content[1] {
+		t.Errorf("got %+v", f)
+	}
+	if !HasImports(app) {
+		t.Error("expected the app to have imports")
+	}
+}
+
+func TestStoresUTC(t *testing.T) {
+	app := mustBuild(t, `app Planner is a web application
+
+data Event:
+  has a starts which is datetime
+
+build with:
+  backend using Python with FastAPI
+  store all times in UTC and display in the user's timezone`)
+
+	if app.Config.Times != "UTC and display in the user's timezone" {
+		t.Errorf("got %q", app.Config.Times)
+	}
+	if !StoresUTC(app) {
+		t.Error("expected the app to store times in UTC")
+	}
+	for text, want := range map[string]bool{
+		"UTC":                               true,
+		"utc and display in local time":     true,
+		"UTC and display in their timezone": true,
+		"local time":                        false,
+		"UTC and display in Europe/Berlin":  false,
+	} {
+		if got := ParseTimePolicy(text); got != want {
+			t.Errorf("ParseTimePolicy(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestValidatesRequests(t *testing.T) {
+	app := mustBuild(t, `app Shop is a web application
+
+data Order:
+  has a quantity which is number
+
+api CreateOrder:
+  accepts quantity
+  create an Order with the given fields
+  respond with the created order
+
+build with:
+  backend using Node with Express
+  validate requests against the OpenAPI spec`)
+
+	if app.Config.Validation != "the OpenAPI spec" {
+		t.Errorf("got %q", app.Config.Validation)
+	}
+	if !ValidatesRequests(app) {
+		t.Error("expected the backend to validate requests")
+	}
+
+	app.APIs = nil
+	if ValidatesRequests(app) {
+		t.Error("an app without APIs has nothing to validate")
+	}
+}
+
+func TestLocaleAndNumberFormat(t *testing.T) {
+	app := mustBuild(t, `app Shop is a web application
+
+data Product:
+  has a name which is text
+  has a price which is decimal
+  has a discount_percent which is number
+  has a view_count which is number
+  has a stock which is number
+
+theme:
+  locale is de-DE
+
+build with:
+  frontend using React with TypeScript`)
+
+	if got := Locale(app); got != "de-DE" {
+		t.Errorf("Locale = %q, want de-DE", got)
+	}
+	if got := Currency(app); got != "EUR" {
+		t.Errorf("Currency = %q, want EUR from the locale's region", got)
+	}
+	app.Theme.Currency = "chf"
+	if got := Currency(app); got != "CHF" {
+		t.Errorf("Currency = %q, want the theme's CHF", got)
+	}
+	app.Theme.Locale = "German please"
+	if got := Locale(app); got != "" {
+		t.Errorf("Locale = %q, want none for a tag that isn't BCP 47", got)
+	}
+
+	want := map[string]string{"name": "", "price": NumberMoney, "discount_percent": NumberPercent, "view_count": NumberCompact, "stock": NumberPlain}
+	for _, f := range app.Data[0].Fields {
+		if got := NumberFormat(f); got != want[f.Name] {
+			t.Errorf("NumberFormat(%s) = %q, want %q", f.Name, got, want[f.Name])
+		}
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	app := mustBuild(t, `app Notes is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+  has an optional bio which is text
+  has a role which is either "member" or "admin"
+
+page Settings:
+  show a heading "Settings"
+
+accounts:
+  users can update their profile, change password, and delete their account
+  users can export their data
+
+authentication:
+  method JWT tokens that expire in 7 days`)
+
+	acc := app.Accounts
+	if acc == nil {
+		t.Fatal("expected the accounts block")
+	}
+	if acc.Model != "User" || !acc.Profile || !acc.Password || !acc.Delete {
+		t.Errorf("got %+v", acc)
+	}
+	if strings.Join(acc.Unknown, ",") != "users can export their data" {
+		t.Errorf("unknown: got %v", acc.Unknown)
+	}
+
+	var names []string
+	for _, f := range ProfileFields(app) {
+		names = append(names, f.Name)
+	}
+	if strings.Join(names, ",") != "name,email,bio" {
+		t.Errorf("profile fields: got %v, want name, email, and bio (not the password or role)", names)
+	}
+
+	for what, name := range map[string]string{
+		AccountProfile:        "GetProfile",
+		AccountUpdateProfile:  "UpdateProfile",
+		AccountChangePassword: "ChangePassword",
+		AccountDelete:         "DeleteAccount",
+	} {
+		ep := AccountEndpoint(app, what)
+		if ep == nil || ep.Name != name || !ep.Auth {
+			t.Errorf("%s: got %+v, want %s requiring authentication", what, ep, name)
+		}
+	}
+	if ep := AccountEndpoint(app, AccountChangePassword); len(ep.Validation) != 2 || ep.Validation[1].Rule != "min_length" || ep.Validation[1].Value != "8" {
+		t.Errorf("a new password is at least 8 characters: got %+v", ep.Validation)
+	}
+
+	if acc.Page != "AccountSettings" {
+		t.Errorf("page: got %q, want AccountSettings (the app has a Settings page)", acc.Page)
+	}
+	page := app.Pages[len(app.Pages)-1]
+	if !IsAccountPage(app, page) || IsAccountPage(app, app.Pages[0]) {
+		t.Errorf("only %s is the account page", acc.Page)
+	}
+}
+
+func TestInvitations(t *testing.T) {
+	app := mustBuild(t, `app Teams is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+  has a password which is text
+
+data Organization:
+  has a name which is text
+
+accounts:
+  users can invite teammates by email to their Organization
+  invitations expire in 3 days
+
+policy Admin:
+  can invite teammates
+
+policy Member:
+  can view tasks
+
+authentication:
+  method JWT tokens that expire in 7 days`)
+
+	inv := app.Accounts.Invite
+	if inv == nil {
+		t.Fatal("expected invitations")
+	}
+	if inv.Team != "Organization" || inv.Expires != 3 {
+		t.Errorf("got %+v, want invitations to Organization expiring in 3 days", inv)
+	}
+	if strings.Join(inv.Roles, ",") != "Admin,Member" || inv.Role != "Member" {
+		t.Errorf("roles: got %v defaulting to %q, want the policies defaulting to Member", inv.Roles, inv.Role)
+	}
+	if app.Accounts.Page != "" {
+		t.Errorf("no account page without profile, password, or delete: got %q", app.Accounts.Page)
+	}
+	if inv.Page != "Team" || inv.Accept != "AcceptInvite" || inv.AcceptPath() != "/accept-invite" {
+		t.Errorf("pages: got %q and %q (%s)", inv.Page, inv.Accept, inv.AcceptPath())
+	}
+	for _, page := range app.Pages {
+		if !IsInvitePage(app, page) {
+			t.Errorf("%s isn't an invite page", page.Name)
+		}
+	}
+
+	invitation := modelNamed(app, InvitationModel)
+	if invitation == nil || invitation.FieldNamed("token") == nil || !invitation.FieldNamed("token").Unique {
+		t.Fatalf("expected an Invitation model with a unique token, got %+v", invitation)
+	}
+	membership := modelNamed(app, MembershipModel)
+	if membership == nil || len(membership.Relations) != 2 {
+		t.Fatalf("expected a Membership belonging to a User and an Organization, got %+v", membership)
+	}
+	org := modelNamed(app, "Organization")
+	if len(org.Relations) != 2 {
+		t.Errorf("Organization should have many invitations and memberships, got %+v", org.Relations)
+	}
+
+	for what, name := range map[string]string{
+		AccountInvite:       "InviteTeammate",
+		AccountInvitation:   "GetInvitation",
+		AccountAcceptInvite: "AcceptInvitation",
+	} {
+		ep := AccountEndpoint(app, what)
+		if ep == nil || ep.Name != name {
+			t.Errorf("%s: got %+v, want %s", what, ep, name)
+		}
+	}
+	if AccountEndpoint(app, AccountInvitation).Auth {
+		t.Error("an invitation can be looked up before signing in")
+	}
+
+	app = mustBuild(t, `app Teams is a web application
+
+data User:
+  has an email which is unique email
+  has a password which is text
+
+accounts:
+  users can invite teammates to their Workspace`)
+	if inv := app.Accounts.Invite; inv.Team != "" || inv.Missing != "Workspace" || inv.Expires != DefaultInviteExpiry {
+		t.Errorf("got %+v, want a missing Workspace", inv)
+	}
+	if AccountEndpoint(app, AccountInvite) != nil {
+		t.Error("no endpoints without a team")
+	}
+}
+
+func TestBilling(t *testing.T) {
+	app := mustBuild(t, `app Boards is a web application
+
+data User:
+  has an email which is unique email
+  has a password which is text
+
+data Project:
+  belongs to a User
+  has a name which is text
+
+plans: Free, Pro ($19/month), Team at $190 per year
+  Enterprise
+  Startup for a limited time
+
+policy FreePlan:
+  can create up to 3 projects
+
+policy Pro:
+  can create unlimited projects
+
+integrate with Stripe:
+  api key from environment variable STRIPE_SECRET_KEY`)
+
+	b := app.Billing
+	if b == nil {
+		t.Fatal("expected billing")
+	}
+	want := []Plan{
+		{Name: "Free", Policy: "FreePlan"},
+		{Name: "Pro", Price: 1900, Interval: "month", Policy: "Pro"},
+		{Name: "Team", Price: 19000, Interval: "year"},
+		{Name: "Enterprise", Custom: true},
+	}
+	if len(b.Plans) != len(want) {
+		t.Fatalf("got %d plans, want %d", len(b.Plans), len(want))
+	}
+	for i, p := range b.Plans {
+		if *p != want[i] {
+			t.Errorf("plan %d: got %+v, want %+v", i, *p, want[i])
+		}
+	}
+	if b.Currency != "usd" || len(b.Unknown) != 1 {
+		t.Errorf("got currency %q and unknown %v", b.Currency, b.Unknown)
+	}
+	if free := b.FreePlan(); free == nil || free.Name != "Free" || !b.Gated() {
+		t.Errorf("expected the gated Free plan, got %+v", free)
+	}
+
+	if !Bills(app) || b.Model != "User" || b.Page != "Pricing" {
+		t.Fatalf("expected billing users on the Pricing page, got %+v", b)
+	}
+	if !IsPricingPage(app, app.Pages[len(app.Pages)-1]) {
+		t.Error("expected the pricing page")
+	}
+	sub := modelNamed(app, SubscriptionModel)
+	if sub == nil || !sub.FieldNamed("stripe_subscription_id").Unique || len(sub.Relations) != 2 {
+		t.Fatalf("expected a Subscription of a User to a Plan, got %+v", sub)
+	}
+	if plan := modelNamed(app, PlanModel); plan == nil || plan.FieldNamed("stripe_price_id") == nil {
+		t.Fatalf("expected a Plan with its Stripe price, got %+v", plan)
+	}
+
+	// Without a payment integration there's nothing to buy plans with
+	app = mustBuild(t, `data User:
+  has an email which is email
+
+plans: Free, Pro ($19/month)`)
+	if Bills(app) || modelNamed(app, PlanModel) != nil || len(app.Pages) != 0 {
+		t.Errorf("expected no billing without a payment integration, got %+v", app.Billing)
+	}
+}
+
+func TestMetering(t *testing.T) {
+	app := mustBuild(t, `app Forecast is a web application
+
+data User:
+  has an email which is unique email
+  has a role which is text
+
+api GetForecast:
+  requires authentication
+  respond with the forecast
+
+api Ping:
+  respond with ok
+
+policy Member:
+  can make up to 1000 API calls per day
+
+policy Partner:
+  can make up to 50000 requests per week
+
+meter API calls per user per day`)
+
+	m := app.Metering
+	if !Meters(app) || m.Period != "day" || m.Model != "User" || m.Page != "Usage" {
+		t.Fatalf("expected daily metering of users, got %+v", m)
+	}
+	if !IsUsagePage(app, app.Pages[len(app.Pages)-1]) {
+		t.Error("expected the usage dashboard")
+	}
+	usage := modelNamed(app, UsageModel)
+	if usage == nil || usage.FieldNamed("calls") == nil || len(usage.Relations) != 1 {
+		t.Fatalf("expected a UsageRecord of a User, got %+v", usage)
+	}
+	if !MetersEndpoint(app, app.APIs[0]) || MetersEndpoint(app, app.APIs[1]) {
+		t.Error("expected only the authenticated endpoint to be metered")
+	}
+
+	quotas := UsageQuotas(app)
+	if len(quotas) != 2 {
+		t.Fatalf("got %d quotas, want 2", len(quotas))
+	}
+	if q := quotas[0]; q.Policy != "Member" || q.Limit != 1000 || q.Period != "day" || !q.Enforced(app) {
+		t.Errorf("got %+v", q)
+	}
+	if q := quotas[1]; q.Limit != 50000 || q.Period != "week" || !q.Enforced(app) {
+		t.Errorf("a weekly quota counts daily records, got %+v", q)
+	}
+	if !QuotaByRole(app) {
+		t.Error("expected quotas by role")
+	}
+
+	// Monthly records can't be counted per week
+	app = mustBuild(t, `data User:
+  has an email which is email
+
+policy Member:
+  can make up to 100 calls per week
+
+meter requests per user per month`)
+	if app.Metering.Period != "month" || UsageQuotas(app)[0].Enforced(app) {
+		t.Errorf("expected a monthly meter not enforcing a weekly quota, got %+v", app.Metering)
+	}
+
+	// Without a User model there's no one to count calls for
+	app = mustBuild(t, `meter API calls per user per day`)
+	if app.Metering == nil || Meters(app) || len(app.Pages) != 0 {
+		t.Errorf("expected no metering without a User model, got %+v", app.Metering)
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	app := mustBuild(t, `app Forecast is a web application
+
+data User:
+  has an email which is unique email
+  has a role which is text
+
+api GetForecast:
+  requires authentication
+  respond with the forecast
+
+authentication:
+  method JWT tokens that expire in 7 days
+  external clients authenticate with API keys`)
+
+	keys := app.Auth.APIKeys
+	if !IssuesAPIKeys(app) || keys.Model != "User" || keys.Page != "ApiKeys" {
+		t.Fatalf("expected API keys of users, got %+v", keys)
+	}
+	if len(app.Auth.Rules) != 0 || len(app.Auth.Methods) != 1 {
+		t.Errorf("the API keys statement isn't a rule or method, got %+v", app.Auth)
+	}
+	if !IsAPIKeysPage(app, app.Pages[len(app.Pages)-1]) {
+		t.Error("expected the API keys page")
+	}
+	key := modelNamed(app, APIKeyModel)
+	if key == nil || key.FieldNamed("key_hash") == nil || !key.FieldNamed("key_hash").Unique || len(key.Relations) != 1 {
+		t.Fatalf("expected an ApiKey of a User by its unique hash, got %+v", key)
+	}
+	if !APIKeyRole(app) {
+		t.Error("expected the key's user's role to be read")
+	}
+
+	// An app's own ApiKey model is kept, and no keys are issued
+	app = mustBuild(t, `data User:
+  has an email which is email
+
+data ApiKey:
+  has a token which is text
+
+authentication:
+  external clients authenticate with API keys`)
+	if app.Auth.APIKeys == nil || IssuesAPIKeys(app) || len(app.Pages) != 0 {
+		t.Errorf("expected no API keys beside the app's own model, got %+v", app.Auth.APIKeys)
+	}
+}
+
+func TestModelHooks(t *testing.T) {
+	app := mustBuild(t, `data User:
+  has an email which is email
+
+data Session:
+  belongs to a User
+
+data Task:
+  has a status which is text
+  has a priority which is number
+  has a done which is boolean
+
+before a Task is created, set its status to "pending"
+before a Task is updated:
+  set its priority to 2
+  set its done to false
+  notify the owner
+after a User is deleted, remove their Sessions
+after the cart is emptied, log it`)
+
+	if len(app.Hooks) != 4 {
+		t.Fatalf("expected 4 hooks, got %d", len(app.Hooks))
+	}
+	created := app.Hooks[0]
+	if created.Model != "Task" || created.Timing != "before" || created.Event != "create" {
+		t.Errorf("expected before Task create, got %+v", created)
+	}
+	task := HookModel(app, created)
+	step := ResolveHookStep(app, created, task, created.Steps[0])
+	if step == nil || step.Field.Name != "status" || step.Value != "pending" || !step.Quoted() {
+		t.Errorf("expected status set to the string pending, got %+v", step)
+	}
+
+	updated := app.Hooks[1]
+	if step := ResolveHookStep(app, updated, task, updated.Steps[0]); step == nil || step.Value != "2" || step.Quoted() {
+		t.Errorf("expected priority set to the number 2, got %+v", step)
+	}
+	if step := ResolveHookStep(app, updated, task, updated.Steps[1]); step == nil || step.Quoted() {
+		t.Errorf("expected done set to the boolean false, got %+v", step)
+	}
+	if step := ResolveHookStep(app, updated, task, updated.Steps[2]); step != nil {
+		t.Errorf("expected notify to be left for a TODO, got %+v", step)
+	}
+
+	deleted := app.Hooks[2]
+	step = ResolveHookStep(app, deleted, HookModel(app, deleted), deleted.Steps[0])
+	if step == nil || step.Remove == nil || step.Remove.Name != "Session" {
+		t.Errorf("expected the user's sessions removed, got %+v", step)
+	}
+	if len(HooksOn(app, task)) != 2 || !HasModelHooks(app) {
+		t.Errorf("expected 2 hooks on Task")
+	}
+
+	// An event that isn't a model write is kept for the analyzer
+	if other := app.Hooks[3]; other.Event != "" || other.Model != "the cart is emptied" {
+		t.Errorf("expected an unparsed event, got %+v", other)
+	}
+}
+
+func TestSeeds(t *testing.T) {
+	app := mustBuild(t, `data User:
+  has an email which is email
+
+data Task:
+  belongs to a User
+
+seed 50 Users with realistic names and emails
+seed:
+  20 task
+  5 Gadgets
+  a few Tasks`)
+
+	if len(app.Seeds) != 4 {
+		t.Fatalf("expected 4 seeds, got %d", len(app.Seeds))
+	}
+	if s := app.Seeds[0]; s.Model != "User" || s.Count != 50 || s.With != "realistic names and emails" {
+		t.Errorf("expected 50 users with realistic names and emails, got %+v", s)
+	}
+	if s := app.Seeds[1]; s.Model != "Task" || s.Count != 20 {
+		t.Errorf("expected 20 tasks, got %+v", s)
+	}
+	// Lines the analyzer warns about keep what they say
+	if s := app.Seeds[2]; s.Model != "Gadgets" || s.Count != 5 {
+		t.Errorf("expected the unknown model kept as written, got %+v", s)
+	}
+	if s := app.Seeds[3]; s.Count != 0 || s.Text != "a few Tasks" {
+		t.Errorf("expected a line without a count kept as text, got %+v", s)
+	}
+
+	counts := SeedCounts(app)
+	if len(counts) != 3 || counts["User"] != 50 || counts["Task"] != 20 {
+		t.Errorf("unexpected seed counts %v", counts)
+	}
+}
+
+func TestBuildInfo(t *testing.T) {
+	source := `app Shop is a web application
+
+data Order:
+  has a total which is number`
+	app := mustBuild(t, source)
+	at := time.Date(2026, 3, 1, 13, 0, 0, 0, time.FixedZone("CET", 3600))
+	info, err := NewBuildInfo(app, "0.4.0", at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(info.IRHash, "sha256:") || len(info.IRHash) != len("sha256:")+64 {
+		t.Errorf("IRHash = %s", info.IRHash)
+	}
+	if info.GeneratedAt != "2026-03-01T12:00:00Z" {
+		t.Errorf("GeneratedAt = %s, want UTC", info.GeneratedAt)
+	}
+	if want := "human/0.4.0; ir=" + info.IRHash + "; generated=2026-03-01T12:00:00Z"; info.Header() != want {
+		t.Errorf("Header() = %s, want %s", info.Header(), want)
+	}
+
+	// The build isn't part of the IR, so recording it doesn't change the hash
+	app.Build = info
+	if hash, _ := Hash(app); hash != info.IRHash {
+		t.Error("the build should be left out of the IR hash")
+	}
+
+	// Comments and layout don't change the IR
+	if hash, _ := Hash(mustBuild(t, "# The shop\n"+source+"\n\n")); hash != info.IRHash {
+		t.Error("sources building the same IR should hash the same")
+	}
+	if hash, _ := Hash(mustBuild(t, source+"\n  has a note which is text")); hash == info.IRHash {
+		t.Error("a different IR should hash differently")
+	}
+}
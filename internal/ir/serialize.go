@@ -2,9 +2,12 @@ package ir
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -22,6 +25,18 @@ func FromJSON(data []byte) (*Application, error) {
 	return app, nil
 }
 
+// Hash returns "sha256:" and the hex SHA-256 digest of the IR's JSON, which
+// identifies the application: .human sources differing only in comments or
+// layout hash the same.
+func Hash(app *Application) (string, error) {
+	data, err := json.Marshal(app)
+	if err != nil {
+		return "", fmt.Errorf("ir: JSON marshal failed: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
 // ToYAML serializes the IR Application to YAML format.
 // Uses a zero-dependency approach: JSON round-trip then YAML formatting.
 func ToYAML(app *Application) (string, error) {
@@ -42,6 +57,163 @@ func ToYAML(app *Application) (string, error) {
 	return buf.String(), nil
 }
 
+// FromYAML deserializes an IR Application from YAML written by ToYAML, such
+// as a build's .human/intent/<name>.yaml. It reads the block-style subset
+// ToYAML writes, not YAML in general.
+func FromYAML(data string) (*Application, error) {
+	var lines []yamlLine
+	for i, text := range strings.Split(data, "\n") {
+		content := strings.TrimLeft(text, " ")
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		lines = append(lines, yamlLine{num: i + 1, indent: len(text) - len(content), text: strings.TrimRight(content, " ")})
+	}
+	p := &yamlParser{lines: lines}
+	var v interface{}
+	if len(lines) > 0 {
+		var err error
+		if v, err = p.block(lines[0].indent); err != nil {
+			return nil, err
+		}
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("ir: invalid YAML at line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("ir: JSON marshal failed: %w", err)
+	}
+	return FromJSON(jsonBytes)
+}
+
+// yamlLine is a non-blank line of YAML, without its indentation.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block reads the map or list whose lines start at indent.
+func (p *yamlParser) block(indent int) (interface{}, error) {
+	if t := p.lines[p.pos].text; t == "-" || strings.HasPrefix(t, "- ") {
+		return p.list(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) mapping(indent int) (interface{}, error) {
+	m := map[string]interface{}{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		key, value, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("ir: invalid YAML at line %d: expected \"key: value\", got %q", line.num, line.text)
+		}
+		p.pos++
+		if value != "" {
+			m[key] = yamlScalar(value)
+			continue
+		}
+		child, err := p.child(indent)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = child
+	}
+	return m, nil
+}
+
+func (p *yamlParser) list(indent int) (interface{}, error) {
+	items := []interface{}{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		if line.text != "-" && !strings.HasPrefix(line.text, "- ") {
+			return nil, fmt.Errorf("ir: invalid YAML at line %d: expected a list item, got %q", line.num, line.text)
+		}
+		rest := strings.TrimPrefix(strings.TrimPrefix(line.text, "-"), " ")
+		if rest == "" {
+			p.pos++
+			child, err := p.child(indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, child)
+			continue
+		}
+		// "- key: value" starts a map whose keys line up after the dash
+		if _, value, ok := splitYAMLKey(rest); ok && (value != "" || p.deeper(p.pos+1, indent+2)) {
+			p.lines[p.pos] = yamlLine{num: line.num, indent: indent + 2, text: rest}
+			item, err := p.mapping(indent + 2)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+		p.pos++
+		items = append(items, yamlScalar(rest))
+	}
+	return items, nil
+}
+
+// child reads the block nested under a key or dash at indent, or null if
+// nothing is nested there.
+func (p *yamlParser) child(indent int) (interface{}, error) {
+	if !p.deeper(p.pos, indent) {
+		return nil, nil
+	}
+	return p.block(p.lines[p.pos].indent)
+}
+
+// deeper reports whether line i is indented past indent.
+func (p *yamlParser) deeper(i, indent int) bool {
+	return i < len(p.lines) && p.lines[i].indent > indent
+}
+
+// splitYAMLKey splits "key: value" or "key:" into its key and value.
+// Quoted strings are values, not keys.
+func splitYAMLKey(text string) (key, value string, ok bool) {
+	if strings.HasPrefix(text, `"`) {
+		return "", "", false
+	}
+	if strings.HasSuffix(text, ":") && !strings.Contains(text[:len(text)-1], ": ") {
+		return text[:len(text)-1], "", true
+	}
+	key, value, ok = strings.Cut(text, ": ")
+	return key, value, ok
+}
+
+// yamlScalar reads a scalar written by writeYAML.
+func yamlScalar(s string) interface{} {
+	switch s {
+	case "null", "~":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	case "{}":
+		return map[string]interface{}{}
+	case "[]":
+		return []interface{}{}
+	}
+	if strings.HasPrefix(s, `"`) {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+	}
+	if looksLikeNumber(s) {
+		return json.Number(s)
+	}
+	return s
+}
+
 // writeYAML recursively formats a generic value as YAML.
 func writeYAML(buf *strings.Builder, v interface{}, indent int) {
 	switch val := v.(type) {
@@ -0,0 +1,167 @@
+package irdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Checklist lists what moving an app from old to cur takes besides
+// rebuilding it: data to migrate, clients to update, and access to review.
+func Checklist(old, cur *ir.Application) []string {
+	var items []string
+	add := func(format string, args ...any) {
+		items = append(items, fmt.Sprintf(format, args...))
+	}
+
+	// Data
+	oldModels := map[string]*ir.DataModel{}
+	for _, m := range old.Data {
+		oldModels[m.Name] = m
+	}
+	curModels := map[string]bool{}
+	for _, m := range cur.Data {
+		curModels[m.Name] = true
+		prev, ok := oldModels[m.Name]
+		if !ok {
+			add("Run a database migration creating the %s table", m.Name)
+			continue
+		}
+		for _, f := range m.Fields {
+			pf := prev.FieldNamed(f.Name)
+			if pf == nil {
+				if f.Required && f.Default == "" {
+					add("Backfill %s.%s for existing records: it's required and has no default", m.Name, f.Name)
+				} else {
+					add("Run a database migration adding %s.%s", m.Name, f.Name)
+				}
+				continue
+			}
+			migrateField(add, m.Name, pf, f)
+		}
+		for _, pf := range prev.Fields {
+			if m.FieldNamed(pf.Name) == nil {
+				add("Copy out any %s.%s data to keep before the migration drops the column", m.Name, pf.Name)
+			}
+		}
+		for _, line := range diffLines(relations(prev), relations(m)) {
+			if strings.HasPrefix(line, "+ belongs to ") {
+				add("Link existing %s records to a %s", m.Name, strings.TrimPrefix(line, "+ belongs to "))
+			}
+		}
+	}
+	for _, m := range old.Data {
+		if !curModels[m.Name] {
+			add("Export or archive the %s table's data before the migration drops it", m.Name)
+		}
+	}
+
+	// Clients
+	oldAPIs := map[string]*ir.Endpoint{}
+	for _, e := range old.APIs {
+		oldAPIs[e.Name] = e
+	}
+	curAPIs := map[string]bool{}
+	for _, e := range cur.APIs {
+		curAPIs[e.Name] = true
+		prev, ok := oldAPIs[e.Name]
+		if !ok {
+			continue
+		}
+		if e.Auth && !prev.Auth {
+			add("Update clients of %s to send an auth token: it now requires one", e.Name)
+		}
+		for _, line := range diffLines(validations(prev), validations(e)) {
+			if strings.HasPrefix(line, "+ ") {
+				add("Check clients of %s pass the new validation: %s", e.Name, strings.TrimPrefix(line, "+ "))
+			}
+		}
+		for _, line := range diffLines(params(prev), params(e)) {
+			if strings.HasPrefix(line, "- ") {
+				add("Update clients of %s that send %s: it's no longer accepted", e.Name, strings.TrimPrefix(line, "- param "))
+			}
+		}
+	}
+	for _, e := range old.APIs {
+		if !curAPIs[e.Name] {
+			add("Update or retire clients calling %s: it's removed", e.Name)
+		}
+	}
+	curPages := map[string]bool{}
+	for _, p := range cur.Pages {
+		curPages[p.Name] = true
+	}
+	for _, p := range old.Pages {
+		if !curPages[p.Name] {
+			add("Redirect links and bookmarks to the %s page: it's removed", p.Name)
+		}
+	}
+
+	// Access
+	for _, c := range diffNamed("Policies", old.Policies, cur.Policies, func(p *ir.Policy) string { return p.Name }, policyDetails) {
+		switch c.Kind {
+		case Added:
+			add("Assign the %s role to the users who should have it", c.Name)
+		case Removed:
+			add("Move users with the %s role to another role: it's removed", c.Name)
+		default:
+			add("Review what the %s role can now do: %s", c.Name, strings.Join(c.Details, "; "))
+		}
+	}
+
+	// Settings
+	for _, c := range diffSettings(old, cur) {
+		switch c.Name {
+		case "config":
+			for _, d := range c.Details {
+				add("Plan the switch in the build config (%s)", strings.TrimPrefix(d, "~ "))
+			}
+		case "environments", "database", "integrations":
+			add("Update deployed configuration and secrets for the %s changes", c.Name)
+		}
+	}
+	return items
+}
+
+// migrateField adds the steps migrating a field's existing values takes.
+func migrateField(add func(string, ...any), model string, old, cur *ir.DataField) {
+	name := model + "." + cur.Name
+	if old.Type != cur.Type {
+		if cur.Type == "enum" {
+			add("Convert existing %s values from %s to one of: %s", name, old.Type, strings.Join(cur.EnumValues, ", "))
+		} else {
+			add("Convert existing %s values from %s to %s", name, old.Type, cur.Type)
+		}
+	} else if removed := missing(old.EnumValues, cur.EnumValues); len(removed) > 0 {
+		add("Move %s records off the removed values: %s", name, strings.Join(removed, ", "))
+	}
+	if cur.Required && !old.Required && cur.Default == "" {
+		add("Fill in %s where it's empty: it's now required", name)
+	}
+	if cur.Unique && !old.Unique {
+		add("Resolve duplicate %s values before the unique constraint is added", name)
+	}
+	if cur.Encrypted != old.Encrypted {
+		if cur.Encrypted {
+			add("Encrypt existing %s values", name)
+		} else {
+			add("Decrypt existing %s values", name)
+		}
+	}
+}
+
+// missing returns the values in old that aren't in cur.
+func missing(old, cur []string) []string {
+	in := map[string]bool{}
+	for _, v := range cur {
+		in[v] = true
+	}
+	var out []string
+	for _, v := range old {
+		if !in[v] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,372 @@
+// Package irdiff compares two versions of an application's IR: the models,
+// fields, endpoints, pages, policies, and workflows added, removed, or
+// changed between them, and any other part of the IR that changed.
+package irdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Kind is how an item changed.
+type Kind int
+
+const (
+	Added Kind = iota
+	Removed
+	Changed
+)
+
+// Symbol returns "+", "-", or "~".
+func (k Kind) Symbol() string {
+	switch k {
+	case Added:
+		return "+"
+	case Removed:
+		return "-"
+	}
+	return "~"
+}
+
+// Change is an item added, removed, or changed.
+type Change struct {
+	Section string // "Models", "Endpoints", "Pages", "Policies", "Workflows", or "Settings"
+	Kind    Kind
+	Name    string
+	Details []string // what changed in a changed item, each starting with its symbol
+}
+
+// Sections lists the sections changes are grouped in, in order.
+var Sections = []string{"Models", "Endpoints", "Pages", "Policies", "Workflows", "Settings"}
+
+// Diff lists what changed from old to cur, by section and then in the order
+// items appear: cur's order, with removed items after.
+func Diff(old, cur *ir.Application) []Change {
+	var changes []Change
+	changes = append(changes, diffNamed("Models", old.Data, cur.Data, func(m *ir.DataModel) string { return m.Name }, modelDetails)...)
+	changes = append(changes, diffNamed("Endpoints", old.APIs, cur.APIs, func(e *ir.Endpoint) string { return e.Name }, endpointDetails)...)
+	changes = append(changes, diffNamed("Pages", old.Pages, cur.Pages, func(p *ir.Page) string { return p.Name }, pageDetails)...)
+	changes = append(changes, diffNamed("Policies", old.Policies, cur.Policies, func(p *ir.Policy) string { return p.Name }, policyDetails)...)
+	changes = append(changes, diffNamed("Workflows", old.Workflows, cur.Workflows, func(w *ir.Workflow) string { return w.Trigger }, workflowDetails)...)
+	changes = append(changes, diffSettings(old, cur)...)
+	return changes
+}
+
+// diffNamed compares two lists of items by name. An item in both whose
+// details differ is changed; details lists what changed in it.
+func diffNamed[T any](section string, old, cur []T, name func(T) string, details func(old, cur T) []string) []Change {
+	var changes []Change
+	before := map[string]T{}
+	for _, item := range old {
+		before[name(item)] = item
+	}
+	seen := map[string]bool{}
+	for _, item := range cur {
+		n := name(item)
+		seen[n] = true
+		prev, ok := before[n]
+		if !ok {
+			changes = append(changes, Change{Section: section, Kind: Added, Name: n})
+			continue
+		}
+		if d := details(prev, item); len(d) > 0 {
+			changes = append(changes, Change{Section: section, Kind: Changed, Name: n, Details: d})
+		}
+	}
+	for _, item := range old {
+		if n := name(item); !seen[n] {
+			seen[n] = true
+			changes = append(changes, Change{Section: section, Kind: Removed, Name: n})
+		}
+	}
+	return changes
+}
+
+func modelDetails(old, cur *ir.DataModel) []string {
+	var d []string
+	for _, c := range diffNamed("", old.Fields, cur.Fields, func(f *ir.DataField) string { return f.Name }, fieldDetails) {
+		switch c.Kind {
+		case Added:
+			d = append(d, fmt.Sprintf("+ field %s (%s)", c.Name, describeField(cur.FieldNamed(c.Name))))
+		case Removed:
+			d = append(d, "- field "+c.Name)
+		default:
+			d = append(d, fmt.Sprintf("~ field %s: %s", c.Name, strings.Join(c.Details, ", ")))
+		}
+	}
+	d = append(d, diffLines(relations(old), relations(cur))...)
+	if old.Versioned != cur.Versioned {
+		d = append(d, "~ "+toggled(cur.Versioned, "protected against conflicting edits"))
+	}
+	return d
+}
+
+func relations(m *ir.DataModel) []string {
+	var lines []string
+	for _, r := range m.Relations {
+		line := strings.ReplaceAll(r.Kind, "_", " ") + " " + r.Target
+		if r.Through != "" {
+			line += " through " + r.Through
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fieldDetails lists how a field changed, e.g. "text → enum", "now unique".
+func fieldDetails(old, cur *ir.DataField) []string {
+	var d []string
+	if old.Type != cur.Type {
+		d = append(d, old.Type+" → "+cur.Type)
+	}
+	for _, flag := range []struct {
+		name     string
+		old, cur bool
+	}{
+		{"required", old.Required, cur.Required},
+		{"unique", old.Unique, cur.Unique},
+		{"encrypted", old.Encrypted, cur.Encrypted},
+		{"personal", old.Personal, cur.Personal},
+	} {
+		if flag.old != flag.cur {
+			d = append(d, toggled(flag.cur, flag.name))
+		}
+	}
+	if a, b := strings.Join(old.EnumValues, ", "), strings.Join(cur.EnumValues, ", "); a != b {
+		d = append(d, fmt.Sprintf("values %s → %s", orNone(a), orNone(b)))
+	}
+	if old.Default != cur.Default {
+		d = append(d, fmt.Sprintf("default %s → %s", orNone(old.Default), orNone(cur.Default)))
+	}
+	return d
+}
+
+func describeField(f *ir.DataField) string {
+	parts := []string{f.Type}
+	if f.Required {
+		parts = append(parts, "required")
+	}
+	if f.Unique {
+		parts = append(parts, "unique")
+	}
+	if f.Encrypted {
+		parts = append(parts, "encrypted")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func endpointDetails(old, cur *ir.Endpoint) []string {
+	var d []string
+	if old.Auth != cur.Auth {
+		d = append(d, "~ "+toggled(cur.Auth, "requires auth"))
+	}
+	d = append(d, diffLines(params(old), params(cur))...)
+	d = append(d, diffLines(validations(old), validations(cur))...)
+	d = append(d, diffLines(actionTexts(old.Steps), actionTexts(cur.Steps))...)
+	if old.PageSize != cur.PageSize {
+		d = append(d, fmt.Sprintf("~ page size %d → %d", old.PageSize, cur.PageSize))
+	}
+	if old.Cache != cur.Cache {
+		d = append(d, fmt.Sprintf("~ cached for %ds → %ds", old.Cache, cur.Cache))
+	}
+	if len(d) == 0 && !sameJSON(old, cur) {
+		d = append(d, "~ changed")
+	}
+	return d
+}
+
+func params(e *ir.Endpoint) []string {
+	var lines []string
+	for _, p := range e.Params {
+		lines = append(lines, "param "+p.Name)
+	}
+	return lines
+}
+
+func validations(e *ir.Endpoint) []string {
+	var lines []string
+	for _, v := range e.Validation {
+		line := "check " + v.Field + " " + strings.ReplaceAll(v.Rule, "_", " ")
+		if v.Value != "" {
+			line += " " + v.Value
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func pageDetails(old, cur *ir.Page) []string {
+	return diffLines(actionTexts(old.Content), actionTexts(cur.Content))
+}
+
+func policyDetails(old, cur *ir.Policy) []string {
+	return append(
+		diffLines(ruleTexts("can", old.Permissions), ruleTexts("can", cur.Permissions)),
+		diffLines(ruleTexts("cannot", old.Restrictions), ruleTexts("cannot", cur.Restrictions))...,
+	)
+}
+
+func ruleTexts(verb string, rules []*ir.PolicyRule) []string {
+	var lines []string
+	for _, r := range rules {
+		lines = append(lines, verb+" "+r.Text)
+	}
+	return lines
+}
+
+func workflowDetails(old, cur *ir.Workflow) []string {
+	return diffLines(actionTexts(old.Steps), actionTexts(cur.Steps))
+}
+
+func actionTexts(actions []*ir.Action) []string {
+	var lines []string
+	for _, a := range actions {
+		lines = append(lines, a.Text)
+	}
+	return lines
+}
+
+// diffLines lists the lines only in old ("- ") and only in cur ("+ "),
+// counting repeated lines.
+func diffLines(old, cur []string) []string {
+	count := map[string]int{}
+	for _, l := range old {
+		count[l]++
+	}
+	var added []string
+	for _, l := range cur {
+		if count[l] > 0 {
+			count[l]--
+		} else {
+			added = append(added, "+ "+l)
+		}
+	}
+	var removed []string
+	for _, l := range old {
+		if count[l] > 0 {
+			count[l]--
+			removed = append(removed, "- "+l)
+		}
+	}
+	return append(added, removed...)
+}
+
+// handledKeys are the parts of the IR compared item by item; the rest are
+// compared as wholes, as settings.
+var handledKeys = map[string]bool{
+	"data": true, "apis": true, "pages": true, "policies": true, "workflows": true,
+}
+
+// diffSettings compares the rest of the IR, one top-level key at a time:
+// the build config, theme, auth, integrations, and so on.
+func diffSettings(old, cur *ir.Application) []Change {
+	a, b := topLevel(old), topLevel(cur)
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []Change
+	for _, k := range keys {
+		if handledKeys[k] {
+			continue
+		}
+		va, inOld := a[k]
+		vb, inCur := b[k]
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Section: "Settings", Kind: Added, Name: k})
+		case !inCur:
+			changes = append(changes, Change{Section: "Settings", Kind: Removed, Name: k})
+		case string(va) != string(vb):
+			changes = append(changes, Change{Section: "Settings", Kind: Changed, Name: k, Details: settingDetails(va, vb)})
+		}
+	}
+	return changes
+}
+
+func topLevel(app *ir.Application) map[string]json.RawMessage {
+	data, _ := json.Marshal(app)
+	var m map[string]json.RawMessage
+	json.Unmarshal(data, &m)
+	return m
+}
+
+// settingDetails lists how a changed setting changed: its old and new
+// value when it is a plain value, like the app's name, or its changed keys
+// when it is an object, like the build config.
+func settingDetails(old, cur json.RawMessage) []string {
+	var va, vb any
+	json.Unmarshal(old, &va)
+	json.Unmarshal(cur, &vb)
+	a, okA := va.(map[string]any)
+	b, okB := vb.(map[string]any)
+	if !okA || !okB {
+		if _, list := va.([]any); list {
+			return nil
+		}
+		return []string{fmt.Sprintf("~ %s → %s", encode(va), encode(vb))}
+	}
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var d []string
+	for _, k := range keys {
+		va, vb := encode(a[k]), encode(b[k])
+		if va != vb {
+			d = append(d, fmt.Sprintf("~ %s: %s → %s", k, va, vb))
+		}
+	}
+	return d
+}
+
+func encode(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		return v
+	}
+	out, _ := json.Marshal(v)
+	if s := string(out); len(s) <= 60 {
+		return s
+	}
+	return "…"
+}
+
+func sameJSON(a, b any) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return string(ja) == string(jb)
+}
+
+func toggled(on bool, what string) string {
+	if on {
+		return "now " + what
+	}
+	return "no longer " + what
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
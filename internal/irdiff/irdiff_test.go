@@ -0,0 +1,136 @@
+package irdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/parser"
+)
+
+func build(t *testing.T, source string) *ir.Application {
+	t.Helper()
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatalf("IR build error: %v", err)
+	}
+	return app
+}
+
+const before = `app Shop is a web application
+
+data Order:
+  has a total which is number
+  has a status which is text
+  has a notes which is text
+
+data Legacy:
+  has a name which is text
+
+api GetOrders:
+  fetch all orders
+  respond with orders
+
+api CreateOrder:
+  accepts total
+  check that total is not empty
+  create an Order with the given fields
+  respond with the created order
+
+policy Customer:
+  can view their own orders
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+const after = `app Shop is a web application
+
+data Order:
+  has a total which is number
+  has a status which is either "placed" or "shipped"
+  has a discount which is number
+
+data Coupon:
+  has a code which is text
+
+api GetOrders:
+  fetch all orders
+  respond with orders
+
+api CreateOrder:
+  requires authentication
+  accepts total and discount
+  check that total is not empty
+  create an Order with the given fields
+  respond with the created order
+
+policy Customer:
+  can view their own orders
+  cannot delete orders
+
+build with:
+  backend using Python with FastAPI
+  database using PostgreSQL`
+
+func TestDiff(t *testing.T) {
+	changes := Diff(build(t, before), build(t, after))
+
+	var got []string
+	for _, c := range changes {
+		line := c.Section + " " + c.Kind.Symbol() + " " + c.Name
+		if len(c.Details) > 0 {
+			line += ": " + strings.Join(c.Details, "; ")
+		}
+		got = append(got, line)
+	}
+	want := []string{
+		"Models ~ Order: ~ field status: text → enum, values (none) → placed, shipped; + field discount (number, required); - field notes",
+		"Models + Coupon",
+		"Models - Legacy",
+		"Endpoints ~ CreateOrder: ~ now requires auth; + param discount",
+		"Policies ~ Customer: + cannot delete orders",
+		"Settings ~ config: ~ backend: Node with Express → Python with FastAPI",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	if changes := Diff(build(t, before), build(t, "# Same app, reformatted\n"+before+"\n")); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := diffLines([]string{"a", "b", "b"}, []string{"b", "c", "a"})
+	if want := "+ c,- b"; strings.Join(got, ",") != want {
+		t.Errorf("diffLines = %v, want %s", got, want)
+	}
+}
+
+func TestChecklist(t *testing.T) {
+	got := Checklist(build(t, before), build(t, after))
+	want := []string{
+		"Convert existing Order.status values from text to one of: placed, shipped",
+		"Backfill Order.discount for existing records: it's required and has no default",
+		"Copy out any Order.notes data to keep before the migration drops the column",
+		"Run a database migration creating the Coupon table",
+		"Export or archive the Legacy table's data before the migration drops it",
+		"Update clients of CreateOrder to send an auth token: it now requires one",
+		"Review what the Customer role can now do: + cannot delete orders",
+		"Plan the switch in the build config (backend: Node with Express → Python with FastAPI)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Checklist =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if got := Checklist(build(t, before), build(t, before)); len(got) != 0 {
+		t.Errorf("expected an empty checklist, got %v", got)
+	}
+}
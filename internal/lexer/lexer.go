@@ -18,13 +18,27 @@ type Lexer struct {
 	// Indentation tracking
 	indentStack []int // stack of indentation levels
 	atLineStart bool  // true when we're at the beginning of a new line
+
+	errors []*Error // syntax errors, in source order
+}
+
+// Error is a syntax error found while tokenizing, such as an unterminated
+// string.
+type Error struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("lexer error at line %d, column %d: %s", e.Line, e.Column, e.Message)
 }
 
 // New creates a new Lexer for the given source code.
 func New(source string) *Lexer {
 	return &Lexer{
 		source:      source,
-		tokens:      make([]Token, 0, 256),
+		tokens:      make([]Token, 0, len(source)/6+16), // specs run about 7 bytes a token
 		line:        1,
 		column:      1,
 		indentStack: []int{0},
@@ -43,7 +57,10 @@ func (l *Lexer) Tokenize() ([]Token, error) {
 
 		l.start = l.current
 		if err := l.scanToken(); err != nil {
-			return nil, err
+			// Drop the rest of the line and carry on, so one pass reports
+			// every error.
+			l.errors = append(l.errors, err.(*Error))
+			l.skipToLineEnd()
 		}
 	}
 
@@ -54,9 +71,27 @@ func (l *Lexer) Tokenize() ([]Token, error) {
 	}
 
 	l.emit(TOKEN_EOF, "")
+	if len(l.errors) > 0 {
+		return l.tokens, l.errors[0]
+	}
 	return l.tokens, nil
 }
 
+// Errors returns every syntax error Tokenize found. Tokenize returns the
+// first, with the tokens of the lines around them.
+func (l *Lexer) Errors() []*Error {
+	return l.errors
+}
+
+// skipToLineEnd advances to the end of the current line, leaving the
+// newline for the next token.
+func (l *Lexer) skipToLineEnd() {
+	for !l.isAtEnd() && l.peekRune() != '\n' {
+		l.advance()
+	}
+	l.start = l.current
+}
+
 // processLineStart handles the beginning of a new line: blank line skipping,
 // comment-only lines, section headers, and indentation changes.
 func (l *Lexer) processLineStart() {
@@ -236,6 +271,7 @@ func (l *Lexer) scanToken() error {
 
 // scanString scans a double-quoted string literal.
 func (l *Lexer) scanString() error {
+	column := l.column
 	l.advance() // consume opening "
 
 	for !l.isAtEnd() {
@@ -255,12 +291,12 @@ func (l *Lexer) scanString() error {
 			continue
 		}
 		if r == '\n' {
-			return l.errorf("unterminated string starting at line %d, column %d", l.line, l.column)
+			break
 		}
 		l.advance()
 	}
 
-	return l.errorf("unterminated string starting at line %d", l.line)
+	return &Error{Line: l.line, Column: column, Message: "unterminated string: add the closing \""}
 }
 
 // scanNumber scans an integer or decimal number.
@@ -518,12 +554,6 @@ func (l *Lexer) emit(tokenType TokenType, literal string) {
 	l.start = l.current
 }
 
-// errorf returns a formatted error with the current position.
-func (l *Lexer) errorf(format string, args ...interface{}) error {
-	return fmt.Errorf("lexer error at line %d, column %d: %s",
-		l.line, l.column, fmt.Sprintf(format, args...))
-}
-
 // ── Character classification helpers ──
 
 func isAlpha(r rune) bool {
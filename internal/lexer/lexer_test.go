@@ -230,6 +230,28 @@ func TestUnterminatedString(t *testing.T) {
 	}
 }
 
+func TestUnterminatedStringsAllReported(t *testing.T) {
+	l := New("show \"one\nshow two\nshow \"three\n")
+	tokens, err := l.Tokenize()
+	if err == nil {
+		t.Fatal("expected error for unterminated string")
+	}
+	errs := l.Errors()
+	if len(errs) != 2 || errs[0].Line != 1 || errs[0].Column != 6 || errs[1].Line != 3 {
+		t.Fatalf("expected errors at 1:6 and line 3, got %v", errs)
+	}
+	// The line between still tokenizes.
+	found := false
+	for _, tok := range tokens {
+		if tok.Literal == "two" && tok.Line == 2 {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected the line after an error to be tokenized")
+	}
+}
+
 func TestIntegerNumber(t *testing.T) {
 	tokens := mustTokenize(t, "42")
 	expectToken(t, tokens, 0, TOKEN_NUMBER_LIT, "42")
@@ -1130,3 +1152,36 @@ func checkTokenTypes(t *testing.T, tokens []Token, expected []TokenType) {
 		}
 	}
 }
+
+func TestLookupKeyword(t *testing.T) {
+	longest := 0
+	for kw := range keywords {
+		longest = max(longest, len(kw))
+	}
+	if longest != maxKeywordLen {
+		t.Errorf("maxKeywordLen is %d, but the longest keyword has %d bytes", maxKeywordLen, longest)
+	}
+	tests := map[string]TokenType{
+		"data":            TOKEN_DATA,
+		"Data":            TOKEN_DATA,
+		"AUTHENTICATION":  TOKEN_AUTHENTICATION,
+		"Task":            TOKEN_IDENTIFIER,
+		"authentications": TOKEN_IDENTIFIER,
+		"Ünique":          TOKEN_IDENTIFIER,
+	}
+	for word, want := range tests {
+		if got := LookupKeyword(word); got != want {
+			t.Errorf("LookupKeyword(%q) = %v, want %v", word, got, want)
+		}
+	}
+}
+
+func BenchmarkLookupKeyword(b *testing.B) {
+	words := []string{"show", "Task", "the", "ShoppingCart", "authentication", "Delete"}
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, w := range words {
+			LookupKeyword(w)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package lexer
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 // TokenType represents the type of a lexical token.
@@ -20,11 +21,11 @@ const (
 	TOKEN_COMMENT                  // # comment text
 
 	// Literal tokens
-	TOKEN_STRING_LIT  // "hello world"
-	TOKEN_NUMBER_LIT  // 42, 3.14, 500
-	TOKEN_COLOR_LIT   // #6C5CE7, #ABC
-	TOKEN_IDENTIFIER  // user_name, Dashboard, etc.
-	TOKEN_POSSESSIVE  // 's (as in user's)
+	TOKEN_STRING_LIT // "hello world"
+	TOKEN_NUMBER_LIT // 42, 3.14, 500
+	TOKEN_COLOR_LIT  // #6C5CE7, #ABC
+	TOKEN_IDENTIFIER // user_name, Dashboard, etc.
+	TOKEN_POSSESSIVE // 's (as in user's)
 
 	// ── Declaration Keywords ──
 
@@ -109,32 +110,32 @@ const (
 
 	// ── Connector Keywords ──
 
-	TOKEN_IS    // is
-	TOKEN_ARE   // are
-	TOKEN_HAS   // has
-	TOKEN_WITH  // with
-	TOKEN_FROM  // from
-	TOKEN_TO    // to
-	TOKEN_IN    // in
-	TOKEN_ON    // on
-	TOKEN_FOR   // for
-	TOKEN_BY    // by
-	TOKEN_AS    // as
-	TOKEN_AND   // and
-	TOKEN_OR    // or
-	TOKEN_NOT   // not
-	TOKEN_THE   // the
-	TOKEN_A     // a
-	TOKEN_AN    // an
-	TOKEN_WHICH // which
-	TOKEN_THAT  // that
+	TOKEN_IS     // is
+	TOKEN_ARE    // are
+	TOKEN_HAS    // has
+	TOKEN_WITH   // with
+	TOKEN_FROM   // from
+	TOKEN_TO     // to
+	TOKEN_IN     // in
+	TOKEN_ON     // on
+	TOKEN_FOR    // for
+	TOKEN_BY     // by
+	TOKEN_AS     // as
+	TOKEN_AND    // and
+	TOKEN_OR     // or
+	TOKEN_NOT    // not
+	TOKEN_THE    // the
+	TOKEN_A      // a
+	TOKEN_AN     // an
+	TOKEN_WHICH  // which
+	TOKEN_THAT   // that
 	TOKEN_EITHER // either
-	TOKEN_OF    // of
-	TOKEN_ITS   // its
-	TOKEN_THEIR // their
-	TOKEN_USING // using
-	TOKEN_PER   // per
-	TOKEN_AT    // at
+	TOKEN_OF     // of
+	TOKEN_ITS    // its
+	TOKEN_THEIR  // their
+	TOKEN_USING  // using
+	TOKEN_PER    // per
+	TOKEN_AT     // at
 
 	// ── Modifier Keywords ──
 
@@ -311,32 +312,32 @@ var tokenNames = map[TokenType]string{
 	TOKEN_EVERY:  "every",
 
 	// Connectors
-	TOKEN_IS:    "is",
-	TOKEN_ARE:   "are",
-	TOKEN_HAS:   "has",
-	TOKEN_WITH:  "with",
-	TOKEN_FROM:  "from",
-	TOKEN_TO:    "to",
-	TOKEN_IN:    "in",
-	TOKEN_ON:    "on",
-	TOKEN_FOR:   "for",
-	TOKEN_BY:    "by",
-	TOKEN_AS:    "as",
-	TOKEN_AND:   "and",
-	TOKEN_OR:    "or",
-	TOKEN_NOT:   "not",
-	TOKEN_THE:   "the",
-	TOKEN_A:     "a",
-	TOKEN_AN:    "an",
-	TOKEN_WHICH: "which",
-	TOKEN_THAT:  "that",
+	TOKEN_IS:     "is",
+	TOKEN_ARE:    "are",
+	TOKEN_HAS:    "has",
+	TOKEN_WITH:   "with",
+	TOKEN_FROM:   "from",
+	TOKEN_TO:     "to",
+	TOKEN_IN:     "in",
+	TOKEN_ON:     "on",
+	TOKEN_FOR:    "for",
+	TOKEN_BY:     "by",
+	TOKEN_AS:     "as",
+	TOKEN_AND:    "and",
+	TOKEN_OR:     "or",
+	TOKEN_NOT:    "not",
+	TOKEN_THE:    "the",
+	TOKEN_A:      "a",
+	TOKEN_AN:     "an",
+	TOKEN_WHICH:  "which",
+	TOKEN_THAT:   "that",
 	TOKEN_EITHER: "either",
-	TOKEN_OF:    "of",
-	TOKEN_ITS:   "its",
-	TOKEN_THEIR: "their",
-	TOKEN_USING: "using",
-	TOKEN_PER:   "per",
-	TOKEN_AT:    "at",
+	TOKEN_OF:     "of",
+	TOKEN_ITS:    "its",
+	TOKEN_THEIR:  "their",
+	TOKEN_USING:  "using",
+	TOKEN_PER:    "per",
+	TOKEN_AT:     "at",
 
 	// Modifiers
 	TOKEN_REQUIRES:  "requires",
@@ -524,32 +525,32 @@ var keywords = map[string]TokenType{
 	"every":  TOKEN_EVERY,
 
 	// Connectors
-	"is":    TOKEN_IS,
-	"are":   TOKEN_ARE,
-	"has":   TOKEN_HAS,
-	"with":  TOKEN_WITH,
-	"from":  TOKEN_FROM,
-	"to":    TOKEN_TO,
-	"in":    TOKEN_IN,
-	"on":    TOKEN_ON,
-	"for":   TOKEN_FOR,
-	"by":    TOKEN_BY,
-	"as":    TOKEN_AS,
-	"and":   TOKEN_AND,
-	"or":    TOKEN_OR,
-	"not":   TOKEN_NOT,
-	"the":   TOKEN_THE,
-	"a":     TOKEN_A,
-	"an":    TOKEN_AN,
-	"which": TOKEN_WHICH,
-	"that":  TOKEN_THAT,
+	"is":     TOKEN_IS,
+	"are":    TOKEN_ARE,
+	"has":    TOKEN_HAS,
+	"with":   TOKEN_WITH,
+	"from":   TOKEN_FROM,
+	"to":     TOKEN_TO,
+	"in":     TOKEN_IN,
+	"on":     TOKEN_ON,
+	"for":    TOKEN_FOR,
+	"by":     TOKEN_BY,
+	"as":     TOKEN_AS,
+	"and":    TOKEN_AND,
+	"or":     TOKEN_OR,
+	"not":    TOKEN_NOT,
+	"the":    TOKEN_THE,
+	"a":      TOKEN_A,
+	"an":     TOKEN_AN,
+	"which":  TOKEN_WHICH,
+	"that":   TOKEN_THAT,
 	"either": TOKEN_EITHER,
-	"of":    TOKEN_OF,
-	"its":   TOKEN_ITS,
-	"their": TOKEN_THEIR,
-	"using": TOKEN_USING,
-	"per":   TOKEN_PER,
-	"at":    TOKEN_AT,
+	"of":     TOKEN_OF,
+	"its":    TOKEN_ITS,
+	"their":  TOKEN_THEIR,
+	"using":  TOKEN_USING,
+	"per":    TOKEN_PER,
+	"at":     TOKEN_AT,
 
 	// Modifiers
 	"requires":  TOKEN_REQUIRES,
@@ -625,8 +626,50 @@ var keywords = map[string]TokenType{
 // or TOKEN_IDENTIFIER if the word is not a keyword.
 // Matching is case-insensitive.
 func LookupKeyword(word string) TokenType {
+	// Lowercase ASCII words on the stack: looking up string(buf[:n])
+	// doesn't allocate, where strings.ToLower would for every capitalized
+	// name in the file.
+	var buf [maxKeywordLen]byte
+	if len(word) > len(buf) {
+		if isASCII(word) {
+			return TOKEN_IDENTIFIER // longer than any keyword
+		}
+	} else if lowerASCII(buf[:], word) {
+		if tok, ok := keywords[string(buf[:len(word)])]; ok {
+			return tok
+		}
+		return TOKEN_IDENTIFIER
+	}
 	if tok, ok := keywords[strings.ToLower(word)]; ok {
 		return tok
 	}
 	return TOKEN_IDENTIFIER
 }
+
+// maxKeywordLen is the length of the longest keyword, "authentication".
+const maxKeywordLen = 14
+
+// lowerASCII writes word in lowercase to dst, reporting false, having
+// written part of it, when word isn't ASCII.
+func lowerASCII(dst []byte, word string) bool {
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		if c >= utf8.RuneSelf {
+			return false
+		}
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		dst[i] = c
+	}
+	return true
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
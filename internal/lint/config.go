@@ -0,0 +1,120 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigFile is the lint configuration's name. human lint reads the one in
+// the project's directory or the nearest directory above it.
+const ConfigFile = ".humanlint.yaml"
+
+// Config sets the severity of rules, turning them off with "off":
+//
+//	rules:
+//	  model-timestamps: off
+//	  endpoint-validation: error
+//
+// Rules it doesn't name keep their default severity.
+type Config struct {
+	Path  string              // the file it was read from, if any
+	Rules map[string]Severity // by rule ID
+}
+
+// SeverityOf returns the severity cfg gives rule, or the rule's default.
+func (cfg *Config) SeverityOf(rule Rule) Severity {
+	if cfg != nil {
+		if s, ok := cfg.Rules[rule.ID]; ok {
+			return s
+		}
+	}
+	return rule.Severity
+}
+
+// LoadConfig reads the .humanlint.yaml in dir or the nearest directory
+// above it. With none, every rule runs at its default severity.
+func LoadConfig(dir string) (*Config, error) {
+	path := findConfig(dir)
+	if path == "" {
+		return &Config{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	cfg, err := ParseConfig(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Path = path
+	return cfg, nil
+}
+
+// findConfig returns the path of the .humanlint.yaml in dir or the nearest
+// directory above it, or "".
+func findConfig(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(abs, ConfigFile)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return ""
+		}
+		abs = parent
+	}
+}
+
+// ParseConfig reads a .humanlint.yaml: a "rules:" map from rule IDs to
+// severities. Comments and blank lines are skipped; unknown rules and
+// severities are errors, so a typo doesn't silently leave a rule on.
+func ParseConfig(data string) (*Config, error) {
+	known := map[string]bool{}
+	for _, rule := range Rules() {
+		known[rule.ID] = true
+	}
+
+	cfg := &Config{Rules: map[string]Severity{}}
+	inRules := false
+	for i, line := range strings.Split(data, "\n") {
+		if j := strings.Index(line, "#"); j >= 0 {
+			line = line[:j]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\", got %q", i+1, strings.TrimSpace(line))
+		}
+		key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"'`)
+
+		// Top-level keys start at the margin; rules are indented under "rules:".
+		if line[0] != ' ' && line[0] != '\t' {
+			if key != "rules" || value != "" {
+				return nil, fmt.Errorf("line %d: unknown setting %q (want rules:)", i+1, key)
+			}
+			inRules = true
+			continue
+		}
+		if !inRules {
+			return nil, fmt.Errorf("line %d: %q is indented outside rules:", i+1, key)
+		}
+		if !known[key] {
+			return nil, fmt.Errorf("line %d: unknown rule %q (see human lint --rules)", i+1, key)
+		}
+		severity, err := ParseSeverity(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", i+1, key, err)
+		}
+		cfg.Rules[key] = severity
+	}
+	return cfg, nil
+}
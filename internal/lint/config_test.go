@@ -0,0 +1,80 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig(`# team lint settings
+rules:
+  model-timestamps: off   # we keep no timestamps
+  endpoint-validation: "error"
+  page-empty-state: warn
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Severity{
+		"model-timestamps":    SeverityOff,
+		"endpoint-validation": SeverityError,
+		"page-empty-state":    SeverityWarning,
+	}
+	if len(cfg.Rules) != len(want) {
+		t.Fatalf("rules = %v, want %v", cfg.Rules, want)
+	}
+	for id, s := range want {
+		if cfg.Rules[id] != s {
+			t.Errorf("%s = %q, want %q", id, cfg.Rules[id], s)
+		}
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	for _, tt := range []struct{ data, want string }{
+		{"rules:\n  model-timestamp: off\n", `unknown rule "model-timestamp"`},
+		{"rules:\n  model-timestamps: loud\n", `unknown severity "loud"`},
+		{"extends: strict\n", `unknown setting "extends"`},
+		{"  model-timestamps: off\n", "indented outside rules:"},
+		{"rules:\n  model-timestamps\n", `expected "key: value"`},
+	} {
+		_, err := ParseConfig(tt.data)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseConfig(%q) error = %v, want %q", tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "specs")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(sub)
+	if err != nil || cfg.Path != "" || len(cfg.Rules) != 0 {
+		t.Fatalf("LoadConfig with no file = %+v, %v; want defaults", cfg, err)
+	}
+
+	path := filepath.Join(repo, ConfigFile)
+	if err := os.WriteFile(path, []byte("rules:\n  unused-policy: error\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadConfig(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Path != path || cfg.Rules["unused-policy"] != SeverityError {
+		t.Errorf("LoadConfig = %+v, want the parent directory's %s", cfg, ConfigFile)
+	}
+
+	if err := os.WriteFile(path, []byte("rules:\n  unused-policy: never\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(sub); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("error = %v, want one naming %s", err, path)
+	}
+}
@@ -0,0 +1,94 @@
+// Package lint checks a .human app's IR against style and best-practice
+// rules. Unlike check, which rejects specs the compiler can't build, lint
+// flags specs that build but are likely missing something: a model without
+// timestamps, an endpoint that accepts anything, a list page with nothing
+// to show when it's empty. Which rules run, and how severe their findings
+// are, comes from a project's .humanlint.yaml.
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Severity is how much a rule's findings matter. Findings of SeverityError
+// fail human lint; SeverityOff turns a rule off.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	SeverityOff     Severity = "off"
+)
+
+// ParseSeverity reads a severity as written in .humanlint.yaml. "warn" is
+// taken for "warning".
+func ParseSeverity(s string) (Severity, error) {
+	switch s {
+	case "error", "warning", "info", "off":
+		return Severity(s), nil
+	case "warn":
+		return SeverityWarning, nil
+	}
+	return "", fmt.Errorf("unknown severity %q (want error, warning, info, or off)", s)
+}
+
+// Rule is one lint rule.
+type Rule struct {
+	ID          string   // e.g. "model-timestamps", as named in .humanlint.yaml
+	Description string   // one line, shown by human lint --rules
+	Severity    Severity // when .humanlint.yaml doesn't set one
+	check       func(app *ir.Application) []Finding
+}
+
+// Finding is something a rule flagged.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Target   string // the model, endpoint, page, policy, or integration
+	Message  string
+}
+
+// Rules returns every rule, in the order human lint reports them.
+func Rules() []Rule {
+	return []Rule{
+		{ID: "model-timestamps", Description: "Data models should keep when records are created or updated", Severity: SeverityInfo, check: checkModelTimestamps},
+		{ID: "endpoint-validation", Description: "Endpoints that take input should check it", Severity: SeverityWarning, check: checkEndpointValidation},
+		{ID: "page-empty-state", Description: "Pages showing a list should say what shows when it's empty", Severity: SeverityWarning, check: checkPageEmptyState},
+		{ID: "unused-policy", Description: "Policies should be given to someone: a role, a plan, or an invitation", Severity: SeverityWarning, check: checkUnusedPolicies},
+		{ID: "integration-credentials", Description: "Integrations should read their credentials from env vars", Severity: SeverityError, check: checkIntegrationCredentials},
+	}
+}
+
+// Run checks app against every rule cfg doesn't turn off. A nil cfg runs
+// every rule at its default severity. Findings are in rule order, then by
+// target.
+func Run(app *ir.Application, cfg *Config) []Finding {
+	var findings []Finding
+	for _, rule := range Rules() {
+		severity := cfg.SeverityOf(rule)
+		if severity == SeverityOff {
+			continue
+		}
+		found := rule.check(app)
+		sort.SliceStable(found, func(i, j int) bool { return found[i].Target < found[j].Target })
+		for _, f := range found {
+			f.Rule, f.Severity = rule.ID, severity
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// HasErrors reports whether any finding is an error.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
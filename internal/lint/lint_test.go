@@ -0,0 +1,95 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func lintApp() *ir.Application {
+	return &ir.Application{
+		Data: []*ir.DataModel{
+			{Name: "User", Fields: []*ir.DataField{
+				{Name: "role", Type: "enum", EnumValues: []string{"admin", "member"}},
+				{Name: "created_at", Type: "datetime"},
+			}},
+			{Name: "Tag", Fields: []*ir.DataField{{Name: "label", Type: "text"}}},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "CreateTag", Params: []*ir.Param{{Name: "label"}}},
+			{Name: "CreateUser", Params: []*ir.Param{{Name: "email"}}, Validation: []*ir.ValidationRule{{Field: "email", Rule: "valid_email"}}},
+			{Name: "ListTags"},
+		},
+		Pages: []*ir.Page{
+			{Name: "Tags", Content: []*ir.Action{{Text: "show a list of tags"}}},
+			{Name: "Users", Content: []*ir.Action{{Text: "show a list of users"}, {Text: "if no users match, show \"Nobody yet\""}}},
+		},
+		Policies: []*ir.Policy{{Name: "Admin"}, {Name: "Member"}, {Name: "Auditor"}},
+		Integrations: []*ir.Integration{
+			{Service: "SendGrid", Credentials: map[string]string{"api key": "SENDGRID_API_KEY"}},
+			{Service: "AWS S3"},
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	findings := Run(lintApp(), nil)
+
+	want := []struct {
+		rule, target string
+		severity     Severity
+	}{
+		{"model-timestamps", "Tag", SeverityInfo},
+		{"endpoint-validation", "CreateTag", SeverityWarning},
+		{"page-empty-state", "Tags", SeverityWarning},
+		{"unused-policy", "Auditor", SeverityWarning},
+		{"integration-credentials", "AWS S3", SeverityError},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("got %d findings, want %d: %+v", len(findings), len(want), findings)
+	}
+	for i, w := range want {
+		f := findings[i]
+		if f.Rule != w.rule || f.Target != w.target || f.Severity != w.severity {
+			t.Errorf("finding %d = %s %s (%s), want %s %s (%s)", i, f.Rule, f.Target, f.Severity, w.rule, w.target, w.severity)
+		}
+	}
+	if !HasErrors(findings) {
+		t.Error("the missing credentials should be an error")
+	}
+}
+
+func TestRunConfig(t *testing.T) {
+	cfg := &Config{Rules: map[string]Severity{
+		"model-timestamps":        SeverityOff,
+		"integration-credentials": SeverityWarning,
+	}}
+	findings := Run(lintApp(), cfg)
+	for _, f := range findings {
+		if f.Rule == "model-timestamps" {
+			t.Error("a rule turned off should not run")
+		}
+		if f.Rule == "integration-credentials" && f.Severity != SeverityWarning {
+			t.Errorf("integration-credentials severity = %s, want warning", f.Severity)
+		}
+	}
+	if HasErrors(findings) {
+		t.Error("no rule is an error any more")
+	}
+}
+
+func TestUnusedPolicyMentions(t *testing.T) {
+	app := &ir.Application{
+		Policies:  []*ir.Policy{{Name: "Editor"}, {Name: "ProPlan"}},
+		APIs:      []*ir.Endpoint{{Name: "Publish", Steps: []*ir.Action{{Text: "only an editor can publish"}}}},
+		Billing:   &ir.Billing{Plans: []*ir.Plan{{Name: "Pro", Policy: "ProPlan"}}},
+		Workflows: []*ir.Workflow{{Trigger: "an editorial is posted"}},
+	}
+	if findings := checkUnusedPolicies(app); len(findings) != 0 {
+		t.Errorf("policies named by a step and a plan are used: %+v", findings)
+	}
+	app.APIs = nil
+	if findings := checkUnusedPolicies(app); len(findings) != 1 || findings[0].Target != "Editor" {
+		t.Errorf("\"editorial\" should not count as mentioning Editor: %+v", findings)
+	}
+}
@@ -0,0 +1,182 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// checkModelTimestamps flags models with no field saying when a record was
+// created or last updated.
+func checkModelTimestamps(app *ir.Application) []Finding {
+	var findings []Finding
+	for _, model := range app.Data {
+		if hasTimestamp(model) {
+			continue
+		}
+		findings = append(findings, Finding{
+			Target:  model.Name,
+			Message: fmt.Sprintf("Data model '%s' has no created or updated timestamp", model.Name),
+		})
+	}
+	return findings
+}
+
+// hasTimestamp reports whether model has a "created" or "updated" field,
+// in any of its spellings.
+func hasTimestamp(model *ir.DataModel) bool {
+	for _, name := range []string{"created", "created_at", "updated", "updated_at"} {
+		if model.FieldNamed(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEndpointValidation flags endpoints that accept parameters without a
+// single "check that" rule.
+func checkEndpointValidation(app *ir.Application) []Finding {
+	var findings []Finding
+	for _, ep := range app.APIs {
+		if len(ep.Params) == 0 || len(ep.Validation) > 0 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Target:  ep.Name,
+			Message: fmt.Sprintf("API '%s' accepts %d parameter(s) but has no validation rules", ep.Name, len(ep.Params)),
+		})
+	}
+	return findings
+}
+
+// checkPageEmptyState flags pages that show a list but never say what to
+// show when there's nothing in it ("if no tasks match, show ...").
+func checkPageEmptyState(app *ir.Application) []Finding {
+	var findings []Finding
+	for _, page := range app.Pages {
+		lists, empty := false, false
+		for _, action := range page.Content {
+			lower := strings.ToLower(action.Text)
+			if strings.Contains(lower, "list of") || strings.Contains(lower, "table of") || strings.Contains(lower, "grid of") {
+				lists = true
+			}
+			if strings.Contains(lower, "if no ") || strings.Contains(lower, "if there are no") {
+				empty = true
+			}
+		}
+		if lists && !empty {
+			findings = append(findings, Finding{
+				Target:  page.Name,
+				Message: fmt.Sprintf("Page '%s' shows a list but has no empty state", page.Name),
+			})
+		}
+	}
+	return findings
+}
+
+// checkUnusedPolicies flags policies nothing gives to a user: no field's
+// roles, billing plan, or invitation name them, and no endpoint, page, or
+// workflow mentions them.
+func checkUnusedPolicies(app *ir.Application) []Finding {
+	var findings []Finding
+	for _, pol := range app.Policies {
+		if policyUsed(app, pol.Name) {
+			continue
+		}
+		findings = append(findings, Finding{
+			Target:  pol.Name,
+			Message: fmt.Sprintf("Policy '%s' is never referenced: no role, plan, or invitation gives it to a user", pol.Name),
+		})
+	}
+	return findings
+}
+
+// policyUsed reports whether anything in app refers to the policy name.
+func policyUsed(app *ir.Application, name string) bool {
+	lower := strings.ToLower(name)
+	for _, model := range app.Data {
+		for _, f := range model.Fields {
+			for _, v := range f.EnumValues {
+				if strings.EqualFold(v, name) {
+					return true
+				}
+			}
+		}
+	}
+	if app.Billing != nil {
+		for _, plan := range app.Billing.Plans {
+			if strings.EqualFold(plan.Policy, name) {
+				return true
+			}
+		}
+	}
+	if app.Accounts != nil && app.Accounts.Invite != nil {
+		for _, role := range app.Accounts.Invite.Roles {
+			if strings.EqualFold(role, name) {
+				return true
+			}
+		}
+	}
+
+	mentions := func(actions []*ir.Action) bool {
+		for _, a := range actions {
+			if containsWord(strings.ToLower(a.Text), lower) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, ep := range app.APIs {
+		if mentions(ep.Steps) {
+			return true
+		}
+	}
+	for _, page := range app.Pages {
+		if mentions(page.Content) {
+			return true
+		}
+	}
+	for _, wf := range app.Workflows {
+		if containsWord(strings.ToLower(wf.Trigger), lower) || mentions(wf.Steps) {
+			return true
+		}
+	}
+	return app.Auth != nil && mentions(app.Auth.Rules)
+}
+
+// containsWord reports whether word appears in text on its own, not as
+// part of a longer word.
+func containsWord(text, word string) bool {
+	for _, w := range strings.FieldsFunc(text, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_')
+	}) {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIntegrationCredentials flags integrations with no env var to read
+// their credentials from, which leaves them unconfigurable outside the
+// generated code.
+func checkIntegrationCredentials(app *ir.Application) []Finding {
+	var findings []Finding
+	for _, integ := range app.Integrations {
+		if len(integ.Credentials) > 0 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Target:  integ.Service,
+			Message: fmt.Sprintf("Integration '%s' has no credentials: add \"api key from environment variable %s_API_KEY\"", integ.Service, envPrefix(integ.Service)),
+		})
+	}
+	return findings
+}
+
+// envPrefix turns a service name into an env var prefix: "AWS S3" →
+// "AWS_S3".
+func envPrefix(service string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(service), "_"))
+}
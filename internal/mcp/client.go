@@ -74,7 +74,7 @@ func (c *Client) Connect(ctx context.Context) error {
 	initResult, err := c.call(ctx, "initialize", InitializeParams{
 		ProtocolVersion: protocolVersion,
 		Capabilities:    map[string]any{},
-		ClientInfo:      ClientInfo{Name: "human-repl", Version: serverVersion},
+		ClientInfo:      ClientInfo{Name: "human-repl", Version: serverVersion()},
 	})
 	if err != nil {
 		c.Close()
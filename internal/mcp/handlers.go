@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/barun-bash/human/internal/analyzer"
 	"github.com/barun-bash/human/internal/build"
+	cerr "github.com/barun-bash/human/internal/errors"
 	"github.com/barun-bash/human/internal/ir"
 	"github.com/barun-bash/human/internal/parser"
 )
@@ -28,30 +30,9 @@ func (s *Server) handleBuild(args json.RawMessage) *CallToolResult {
 		return toolError("'source' is required.")
 	}
 
-	// Parse
-	prog, err := parser.Parse(params.Source)
-	if err != nil {
-		return toolError("Parse error: " + err.Error())
-	}
-
-	// Build IR
-	app, err := ir.Build(prog)
-	if err != nil {
-		return toolError("IR build error: " + err.Error())
-	}
-
-	// Semantic analysis
-	errs := analyzer.Analyze(app, "input.human")
-	if errs.HasErrors() {
-		var diags []string
-		for _, e := range errs.All() {
-			d := fmt.Sprintf("[%s] %s", e.Code, e.Message)
-			if e.Suggestion != "" {
-				d += " — suggestion: " + e.Suggestion
-			}
-			diags = append(diags, d)
-		}
-		return toolError("Validation errors:\n" + strings.Join(diags, "\n"))
+	app, errs, failed := compileSource(params.Source)
+	if failed != nil {
+		return failed
 	}
 
 	// Determine output directory
@@ -62,22 +43,9 @@ func (s *Server) handleBuild(args json.RawMessage) *CallToolResult {
 			return toolError("Failed to create temp directory: " + err.Error())
 		}
 		outputDir = tmp
-
-		// Track for human_read_file and cleanup
-		s.mu.Lock()
-		oldDir := s.lastBuildDir
-		s.lastBuildDir = outputDir
-		s.mu.Unlock()
-
-		// Clean up previous temp build
-		if oldDir != "" {
-			os.RemoveAll(oldDir)
-		}
-	} else {
-		s.mu.Lock()
-		s.lastBuildDir = outputDir
-		s.mu.Unlock()
 	}
+	// Track for human_read_file and cleanup
+	s.setBuildDir(outputDir)
 
 	// Run generators (skips quality.PrintSummary — that writes to stdout)
 	results, qResult, _, err := build.RunGenerators(app, outputDir)
@@ -431,3 +399,208 @@ func toolError(text string) *CallToolResult {
 		IsError: true,
 	}
 }
+
+// Default and largest total size of the file contents human_generate_project
+// returns.
+const (
+	defaultProjectBytes = 100 * 1024
+	maxProjectBytes     = 1024 * 1024
+)
+
+// handleGenerateProject runs the build, or the named generators, in a
+// temporary directory and returns the generated file tree plus the
+// contents of the files asked for, so an agent can inspect the output
+// without access to the host's filesystem.
+func (s *Server) handleGenerateProject(args json.RawMessage) *CallToolResult {
+	var params struct {
+		Source     string   `json:"source"`
+		Generators []string `json:"generators"`
+		Files      []string `json:"files"`
+		MaxBytes   int      `json:"max_bytes"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return toolError("Invalid arguments: " + err.Error())
+	}
+	if params.Source == "" {
+		return toolError("'source' is required.")
+	}
+	budget := params.MaxBytes
+	if budget <= 0 {
+		budget = defaultProjectBytes
+	} else if budget > maxProjectBytes {
+		budget = maxProjectBytes
+	}
+
+	reg := build.DefaultRegistry()
+	if len(params.Generators) > 0 {
+		var err error
+		if reg, err = build.RegistryOf(params.Generators); err != nil {
+			return toolError(err.Error())
+		}
+	}
+
+	app, errs, failed := compileSource(params.Source)
+	if failed != nil {
+		return failed
+	}
+
+	outputDir, err := os.MkdirTemp("", "human-mcp-build-*")
+	if err != nil {
+		return toolError("Failed to create temp directory: " + err.Error())
+	}
+	s.setBuildDir(outputDir)
+
+	results, _, _, err := build.RunGeneratorsWithRegistry(reg, app, outputDir, nil)
+	if err != nil {
+		return toolError("Build failed: " + err.Error())
+	}
+
+	files, err := projectFiles(outputDir)
+	if err != nil {
+		return toolError("Reading build output: " + err.Error())
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Generated %s: %d files\n", app.Name, len(files))
+	ran := map[string]bool{}
+	for _, r := range results {
+		ran[r.Name] = true
+		fmt.Fprintf(&sb, "  %-14s %d files\n", r.Name, r.Files)
+	}
+	for _, name := range params.Generators {
+		if name = strings.ToLower(strings.TrimSpace(name)); !ran[name] {
+			fmt.Fprintf(&sb, "  %-14s skipped — not enabled by this app's build block\n", name)
+		}
+	}
+	if errs.HasWarnings() {
+		sb.WriteString("\nWarnings:\n")
+		for _, w := range errs.Warnings() {
+			fmt.Fprintf(&sb, "  [%s] %s\n", w.Code, w.Message)
+		}
+	}
+
+	sb.WriteString("\nFiles:\n")
+	for _, f := range files {
+		fmt.Fprintf(&sb, "  %s (%d bytes)\n", f.path, f.size)
+	}
+
+	var omitted []string
+	for _, f := range files {
+		if !matchesAny(f.path, params.Files) {
+			continue
+		}
+		if budget <= 0 {
+			omitted = append(omitted, f.path)
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(f.path)))
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n--- %s ---\n", f.path)
+		if len(content) > budget {
+			fmt.Fprintf(&sb, "%s\n[Truncated at %d of %d bytes]\n", content[:budget], budget, len(content))
+			budget = 0
+			continue
+		}
+		sb.Write(content)
+		if len(content) > 0 && content[len(content)-1] != '\n' {
+			sb.WriteString("\n")
+		}
+		budget -= len(content)
+	}
+	if len(omitted) > 0 {
+		fmt.Fprintf(&sb, "\n[%d more matching files omitted — over max_bytes. Read them with human_read_file.]\n", len(omitted))
+	}
+
+	return toolText(sb.String())
+}
+
+// compileSource parses and analyzes .human source. When it doesn't
+// compile, the returned result reports why.
+func compileSource(source string) (*ir.Application, *cerr.CompilerErrors, *CallToolResult) {
+	prog, err := parser.Parse(source)
+	if err != nil {
+		return nil, nil, toolError("Parse error: " + err.Error())
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		return nil, nil, toolError("IR build error: " + err.Error())
+	}
+	errs := analyzer.Analyze(app, "input.human")
+	if errs.HasErrors() {
+		var diags []string
+		for _, e := range errs.All() {
+			d := fmt.Sprintf("[%s] %s", e.Code, e.Message)
+			if e.Suggestion != "" {
+				d += " — suggestion: " + e.Suggestion
+			}
+			diags = append(diags, d)
+		}
+		return nil, nil, toolError("Validation errors:\n" + strings.Join(diags, "\n"))
+	}
+	return app, errs, nil
+}
+
+// setBuildDir records dir as the build human_read_file reads from,
+// removing the previous temporary build.
+func (s *Server) setBuildDir(dir string) {
+	s.mu.Lock()
+	old := s.lastBuildDir
+	s.lastBuildDir = dir
+	s.mu.Unlock()
+	if old != "" && old != dir && strings.HasPrefix(filepath.Base(old), "human-mcp-build-") {
+		os.RemoveAll(old)
+	}
+}
+
+// projectFile is a generated file: its slash-separated path relative to
+// the output directory, and its size.
+type projectFile struct {
+	path string
+	size int64
+}
+
+// projectFiles lists the files under dir, sorted by path.
+func projectFiles(dir string) ([]projectFile, error) {
+	var files []projectFile
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, projectFile{path: filepath.ToSlash(rel), size: info.Size()})
+		return nil
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, err
+}
+
+// matchesAny reports whether a generated file is one of those asked for:
+// a pattern matches its path exactly, as a glob, or as a directory it is
+// under. A pattern without a slash also matches its name, so "*.prisma"
+// finds the schema wherever it is.
+func matchesAny(file string, patterns []string) bool {
+	for _, p := range patterns {
+		p = strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(p)), "./")
+		switch {
+		case p == "":
+			continue
+		case p == file, strings.HasPrefix(file, strings.TrimSuffix(p, "/")+"/"):
+			return true
+		}
+		if ok, _ := path.Match(p, file); ok {
+			return true
+		}
+		if !strings.Contains(p, "/") {
+			if ok, _ := path.Match(p, path.Base(file)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AllPrompts returns the prompt templates the server offers: multi-step
+// workflows an IDE agent can run with the tools, beyond looking up the
+// spec and examples.
+func AllPrompts() []Prompt {
+	return []Prompt{
+		{
+			Name:        "scaffold_crud_app",
+			Title:       "Scaffold a CRUD app",
+			Description: "Write a complete .human app with data models, pages, and create/read/update/delete APIs for the given entities, then validate and build it.",
+			Arguments: []PromptArgument{
+				{Name: "app", Description: "What the app is for, e.g. 'a reading list for book clubs'.", Required: true},
+				{Name: "entities", Description: "The records it manages and their fields, e.g. 'Book with title, author, rating; Club with name'.", Required: true},
+				{Name: "stack", Description: "Optional frontend, backend, and database, e.g. 'Vue, Python, PostgreSQL'. Defaults to React, Node, and PostgreSQL."},
+			},
+		},
+		{
+			Name:        "add_feature",
+			Title:       "Add a feature",
+			Description: "Extend an existing .human app with a new feature, keeping the rest of the app unchanged.",
+			Arguments: []PromptArgument{
+				{Name: "source", Description: "The current .human source.", Required: true},
+				{Name: "feature", Description: "The feature to add, in plain English.", Required: true},
+			},
+		},
+		{
+			Name:        "fix_errors",
+			Title:       "Fix validation errors",
+			Description: "Validate .human source and fix every error and warning the compiler reports.",
+			Arguments: []PromptArgument{
+				{Name: "source", Description: "The .human source to fix.", Required: true},
+			},
+		},
+	}
+}
+
+// handlePromptsList returns all available prompts.
+func (s *Server) handlePromptsList(req *Request) *Response {
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  PromptsListResult{Prompts: AllPrompts()},
+	}
+}
+
+// handlePromptsGet renders a prompt with the client's arguments.
+func (s *Server) handlePromptsGet(req *Request) *Response {
+	var params GetPromptParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return rpcError(req, ErrCodeParams, "invalid prompts/get params: "+err.Error())
+	}
+
+	var prompt *Prompt
+	for _, p := range AllPrompts() {
+		if p.Name == params.Name {
+			prompt = &p
+			break
+		}
+	}
+	if prompt == nil {
+		return rpcError(req, ErrCodeParams, fmt.Sprintf("unknown prompt: %s", params.Name))
+	}
+	for _, arg := range prompt.Arguments {
+		if arg.Required && strings.TrimSpace(params.Arguments[arg.Name]) == "" {
+			return rpcError(req, ErrCodeParams, fmt.Sprintf("prompt %s requires the argument %q", prompt.Name, arg.Name))
+		}
+	}
+
+	var text string
+	switch prompt.Name {
+	case "scaffold_crud_app":
+		text = s.scaffoldPrompt(params.Arguments)
+	case "add_feature":
+		text = addFeaturePrompt(params.Arguments)
+	case "fix_errors":
+		text = s.fixErrorsPrompt(params.Arguments)
+	}
+
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: GetPromptResult{
+			Description: prompt.Description,
+			Messages: []PromptMessage{
+				{Role: "user", Content: ContentItem{Type: "text", Text: text}},
+			},
+		},
+	}
+}
+
+// scaffoldPrompt asks for a new app managing the given entities.
+func (s *Server) scaffoldPrompt(args map[string]string) string {
+	stack := strings.TrimSpace(args["stack"])
+	if stack == "" {
+		stack = "React with TypeScript, Node with Express, and PostgreSQL"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Write a Human (.human) app: %s.\n\n", strings.TrimSpace(args["app"]))
+	fmt.Fprintf(&b, "It manages these records: %s.\n\n", strings.TrimSpace(args["entities"]))
+	b.WriteString("The app should have:\n")
+	b.WriteString("- a `data` block for each record, with a type for every field and the relationships between them\n")
+	b.WriteString("- a page listing each record, and a page to view and edit one, with forms to create and update\n")
+	b.WriteString("- APIs to create, list, get, update, and delete each record, checking required fields\n")
+	b.WriteString("- sign up and log in, with a policy saying who may change which records\n")
+	fmt.Fprintf(&b, "- a `build with` block using %s\n\n", stack)
+	b.WriteString(s.referenceHint())
+	b.WriteString("Then call human_validate and fix everything it reports until the source is clean, ")
+	b.WriteString("and call human_build to generate the code. Reply with the final source.")
+	return b.String()
+}
+
+// addFeaturePrompt asks for a feature to be added to existing source.
+func addFeaturePrompt(args map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Add this feature to the Human app below: %s.\n\n", strings.TrimSpace(args["feature"]))
+	b.WriteString("Change only what the feature needs — new data fields, pages, APIs, policies, or workflows — and keep everything else as it is. ")
+	b.WriteString("Read the human://spec resource (or call human_spec) for syntax you are unsure of. ")
+	b.WriteString("Call human_validate on the result and fix anything it reports, then reply with the complete updated source.\n\n")
+	b.WriteString("```\n" + strings.TrimRight(args["source"], "\n") + "\n```\n")
+	return b.String()
+}
+
+// fixErrorsPrompt validates the source up front, so the agent starts from
+// the compiler's diagnostics rather than a tool call to get them.
+func (s *Server) fixErrorsPrompt(args map[string]string) string {
+	raw, _ := json.Marshal(map[string]string{"source": args["source"]})
+	report := s.handleValidate(raw).Content[0].Text
+
+	var b strings.Builder
+	b.WriteString("Fix this Human app so it compiles cleanly.\n\n")
+	b.WriteString("```\n" + strings.TrimRight(args["source"], "\n") + "\n```\n\n")
+	b.WriteString("The compiler reports:\n\n")
+	b.WriteString(strings.TrimRight(report, "\n") + "\n\n")
+	b.WriteString("Fix the cause of each diagnostic, following its suggestion where there is one, without changing what the app does. ")
+	b.WriteString("Call human_validate again until it finds no issues, then reply with the complete fixed source.")
+	return b.String()
+}
+
+// referenceHint points the agent at the spec and an example to model the
+// app on.
+func (s *Server) referenceHint() string {
+	example := "taskflow"
+	if _, ok := s.examples[example]; !ok {
+		example = ""
+		for name := range s.examples {
+			if example == "" || name < example {
+				example = name
+			}
+		}
+	}
+	if example == "" {
+		return "Read the human://spec resource (or call human_spec) for the syntax.\n\n"
+	}
+	return fmt.Sprintf("Read the human://spec resource (or call human_spec) for the syntax, and model the app on the %s%s example (or call human_examples).\n\n", examplesPrefix, example)
+}
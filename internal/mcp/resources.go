@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	specURI        = "human://spec"
+	examplesPrefix = "human://examples/"
+)
+
+// resources returns the documents the server exposes: the language spec
+// and each example app, so clients can attach them as context without a
+// tool call.
+func (s *Server) resources() []Resource {
+	var out []Resource
+	if s.spec != "" {
+		out = append(out, Resource{
+			URI:         specURI,
+			Name:        "LANGUAGE_SPEC.md",
+			Title:       "Human language specification",
+			Description: "The grammar, keywords, and syntax rules of the Human language.",
+			MimeType:    "text/markdown",
+		})
+	}
+
+	names := make([]string, 0, len(s.examples))
+	for name := range s.examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out = append(out, Resource{
+			URI:         examplesPrefix + name,
+			Name:        name + ".human",
+			Title:       "Example: " + name,
+			Description: exampleSummary(name, s.examples[name]),
+			MimeType:    "text/plain",
+		})
+	}
+	return out
+}
+
+// readResource returns the text of the resource at uri.
+func (s *Server) readResource(uri string) (ResourceContents, bool) {
+	if uri == specURI && s.spec != "" {
+		return ResourceContents{URI: uri, MimeType: "text/markdown", Text: s.spec}, true
+	}
+	if name, ok := strings.CutPrefix(uri, examplesPrefix); ok {
+		if source, ok := s.examples[name]; ok {
+			return ResourceContents{URI: uri, MimeType: "text/plain", Text: source}, true
+		}
+	}
+	return ResourceContents{}, false
+}
+
+// handleResourcesList returns all available resources.
+func (s *Server) handleResourcesList(req *Request) *Response {
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  ResourcesListResult{Resources: s.resources()},
+	}
+}
+
+// handleResourcesRead returns the contents of one resource.
+func (s *Server) handleResourcesRead(req *Request) *Response {
+	var params ReadResourceParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return rpcError(req, ErrCodeParams, "invalid resources/read params: "+err.Error())
+	}
+	contents, ok := s.readResource(params.URI)
+	if !ok {
+		return rpcError(req, ErrCodeNotFound, fmt.Sprintf("resource not found: %s", params.URI))
+	}
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  ReadResourceResult{Contents: []ResourceContents{contents}},
+	}
+}
+
+// exampleSummary describes an example by its first line, as
+// human_examples lists them.
+func exampleSummary(name, source string) string {
+	first, _, _ := strings.Cut(source, "\n")
+	if first = strings.TrimSpace(first); first != "" {
+		return first
+	}
+	return name
+}
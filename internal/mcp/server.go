@@ -6,15 +6,39 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 	"sync"
+
+	"github.com/barun-bash/human/internal/version"
 )
 
 const (
-	protocolVersion = "2025-03-26"
+	protocolVersion = "2025-06-18"
 	serverName      = "human-compiler"
-	serverVersion   = "0.4.0"
+	serverTitle     = "Human Compiler"
 )
 
+// protocolVersions are the MCP revisions the server speaks, newest first.
+var protocolVersions = []string{protocolVersion, "2025-03-26", "2024-11-05"}
+
+// serverVersion is the compiler's release version, which the server
+// reports so clients can tell which tools and language features it has.
+func serverVersion() string {
+	return strings.TrimPrefix(version.Version, "v")
+}
+
+// negotiateVersion returns the protocol version to answer a client with:
+// the version it asked for when the server speaks it, else the newest,
+// which the client may then disconnect from if it cannot speak it.
+func negotiateVersion(requested string) string {
+	for _, v := range protocolVersions {
+		if v == requested {
+			return v
+		}
+	}
+	return protocolVersion
+}
+
 // Server is an MCP server that exposes the Human compiler as tools.
 type Server struct {
 	transport    *Transport
@@ -72,30 +96,46 @@ func (s *Server) dispatch(req *Request) *Response {
 		return s.handleToolsList(req)
 	case "tools/call":
 		return s.handleToolsCall(req)
+	case "resources/list":
+		return s.handleResourcesList(req)
+	case "resources/read":
+		return s.handleResourcesRead(req)
+	case "prompts/list":
+		return s.handlePromptsList(req)
+	case "prompts/get":
+		return s.handlePromptsGet(req)
 	case "ping":
 		return s.handlePing(req)
 	default:
-		return &Response{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error:   &RPCError{Code: ErrCodeMethodNot, Message: fmt.Sprintf("unknown method: %s", req.Method)},
-		}
+		return rpcError(req, ErrCodeMethodNot, fmt.Sprintf("unknown method: %s", req.Method))
 	}
 }
 
-// handleInitialize responds to the MCP initialize handshake.
+// handleInitialize responds to the MCP initialize handshake, agreeing on
+// a protocol version and advertising the server's tools, resources, and
+// prompts.
 func (s *Server) handleInitialize(req *Request) *Response {
+	var params InitializeParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return rpcError(req, ErrCodeParams, "invalid initialize params: "+err.Error())
+		}
+	}
+
 	return &Response{
 		JSONRPC: "2.0",
 		ID:      req.ID,
 		Result: InitializeResult{
-			ProtocolVersion: protocolVersion,
+			ProtocolVersion: negotiateVersion(params.ProtocolVersion),
 			Capabilities: ServerCapabilities{
-				Tools: &ToolsCapability{},
+				Tools:     &ToolsCapability{},
+				Resources: &ResourcesCapability{},
+				Prompts:   &PromptsCapability{},
 			},
 			ServerInfo: ServerInfo{
 				Name:    serverName,
-				Version: serverVersion,
+				Title:   serverTitle,
+				Version: serverVersion(),
 			},
 		},
 	}
@@ -114,11 +154,7 @@ func (s *Server) handleToolsList(req *Request) *Response {
 func (s *Server) handleToolsCall(req *Request) *Response {
 	var params CallToolParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return &Response{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error:   &RPCError{Code: ErrCodeInvalidReq, Message: "invalid tools/call params: " + err.Error()},
-		}
+		return rpcError(req, ErrCodeInvalidReq, "invalid tools/call params: "+err.Error())
 	}
 
 	// Dispatch with panic recovery
@@ -153,6 +189,8 @@ func (s *Server) callToolSafe(name string, args json.RawMessage) (result *CallTo
 		return s.handleSpec(args)
 	case "human_read_file":
 		return s.handleReadFile(args)
+	case "human_generate_project":
+		return s.handleGenerateProject(args)
 	default:
 		return toolError(fmt.Sprintf("Unknown tool: %s", name))
 	}
@@ -167,6 +205,15 @@ func (s *Server) handlePing(req *Request) *Response {
 	}
 }
 
+// rpcError returns a JSON-RPC error response to req.
+func rpcError(req *Request, code int, message string) *Response {
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Error:   &RPCError{Code: code, Message: message},
+	}
+}
+
 // cleanup removes any temporary build directories.
 func (s *Server) cleanup() {
 	s.mu.Lock()
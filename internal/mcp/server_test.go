@@ -87,8 +87,8 @@ func TestToolsList(t *testing.T) {
 		t.Fatalf("failed to parse result: %v", err)
 	}
 
-	if len(result.Tools) != 6 {
-		t.Errorf("expected 6 tools, got %d", len(result.Tools))
+	if len(result.Tools) != 7 {
+		t.Errorf("expected 7 tools, got %d", len(result.Tools))
 	}
 
 	names := make(map[string]bool)
@@ -96,7 +96,7 @@ func TestToolsList(t *testing.T) {
 		names[tool.Name] = true
 	}
 
-	expected := []string{"human_build", "human_validate", "human_ir", "human_examples", "human_spec", "human_read_file"}
+	expected := []string{"human_build", "human_validate", "human_ir", "human_examples", "human_spec", "human_read_file", "human_generate_project"}
 	for _, name := range expected {
 		if !names[name] {
 			t.Errorf("missing tool: %s", name)
@@ -336,6 +336,284 @@ func TestNotificationsInitializedNoResponse(t *testing.T) {
 	}
 }
 
+func TestInitializeNegotiatesVersion(t *testing.T) {
+	tests := []struct {
+		requested, want string
+	}{
+		{"2025-06-18", "2025-06-18"},
+		{"2025-03-26", "2025-03-26"},
+		{"2024-11-05", "2024-11-05"},
+		{"2099-01-01", protocolVersion},
+	}
+	for _, tt := range tests {
+		responses := runRequests(t, "", nil,
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+tt.requested+`","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`,
+		)
+		resultBytes, _ := json.Marshal(responses[0].Result)
+		var result InitializeResult
+		json.Unmarshal(resultBytes, &result)
+
+		if result.ProtocolVersion != tt.want {
+			t.Errorf("requested %s: protocol version = %q, want %q", tt.requested, result.ProtocolVersion, tt.want)
+		}
+		if result.Capabilities.Resources == nil || result.Capabilities.Prompts == nil {
+			t.Errorf("expected resources and prompts capabilities, got %+v", result.Capabilities)
+		}
+		if result.ServerInfo.Version != serverVersion() {
+			t.Errorf("server version = %q, want %q", result.ServerInfo.Version, serverVersion())
+		}
+	}
+}
+
+func TestToolAnnotations(t *testing.T) {
+	for _, tool := range AllTools() {
+		if tool.Title == "" || tool.Annotations == nil {
+			t.Errorf("%s: expected a title and annotations", tool.Name)
+			continue
+		}
+		if readOnly := tool.Name != "human_build" && tool.Name != "human_generate_project"; tool.Annotations.ReadOnlyHint != readOnly {
+			t.Errorf("%s: readOnlyHint = %v, want %v", tool.Name, tool.Annotations.ReadOnlyHint, readOnly)
+		}
+	}
+}
+
+func TestResourcesList(t *testing.T) {
+	examples := map[string]string{
+		"taskflow": "app TaskFlow is a web application",
+		"blog":     "app Blog is a web application",
+	}
+	responses := runRequests(t, "test spec", examples,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list","params":{}}`,
+	)
+
+	resultBytes, _ := json.Marshal(responses[0].Result)
+	var result ResourcesListResult
+	json.Unmarshal(resultBytes, &result)
+
+	var uris []string
+	for _, r := range result.Resources {
+		uris = append(uris, r.URI)
+	}
+	want := []string{"human://spec", "human://examples/blog", "human://examples/taskflow"}
+	if strings.Join(uris, " ") != strings.Join(want, " ") {
+		t.Errorf("resources = %v, want %v", uris, want)
+	}
+	if result.Resources[2].Description != "app TaskFlow is a web application" {
+		t.Errorf("example description = %q", result.Resources[2].Description)
+	}
+}
+
+func TestResourcesRead(t *testing.T) {
+	examples := map[string]string{"taskflow": "app TaskFlow is a web application"}
+	responses := runRequests(t, "test spec", examples,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"human://examples/taskflow"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"human://spec"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"human://examples/missing"}}`,
+	)
+
+	for i, want := range []string{"app TaskFlow is a web application", "test spec"} {
+		resultBytes, _ := json.Marshal(responses[i].Result)
+		var result ReadResourceResult
+		json.Unmarshal(resultBytes, &result)
+		if len(result.Contents) != 1 || result.Contents[0].Text != want {
+			t.Errorf("response %d: contents = %+v, want %q", i, result.Contents, want)
+		}
+	}
+	if responses[2].Error == nil || responses[2].Error.Code != ErrCodeNotFound {
+		t.Errorf("expected a not-found error for a missing resource, got %+v", responses[2].Error)
+	}
+}
+
+// getPrompt runs prompts/get and returns the text of its message, or the
+// RPC error.
+func getPrompt(t *testing.T, examples map[string]string, name string, args map[string]string) (string, *RPCError) {
+	t.Helper()
+	params, _ := json.Marshal(GetPromptParams{Name: name, Arguments: args})
+	responses := runRequests(t, "test spec", examples,
+		`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":`+string(params)+`}`,
+	)
+	if responses[0].Error != nil {
+		return "", responses[0].Error
+	}
+	resultBytes, _ := json.Marshal(responses[0].Result)
+	var result GetPromptResult
+	json.Unmarshal(resultBytes, &result)
+	if len(result.Messages) != 1 || result.Messages[0].Role != "user" {
+		t.Fatalf("expected one user message, got %+v", result.Messages)
+	}
+	return result.Messages[0].Content.Text, nil
+}
+
+func TestPromptsList(t *testing.T) {
+	responses := runRequests(t, "", nil,
+		`{"jsonrpc":"2.0","id":1,"method":"prompts/list","params":{}}`,
+	)
+	resultBytes, _ := json.Marshal(responses[0].Result)
+	var result PromptsListResult
+	json.Unmarshal(resultBytes, &result)
+
+	names := map[string]bool{}
+	for _, p := range result.Prompts {
+		names[p.Name] = true
+	}
+	for _, name := range []string{"scaffold_crud_app", "add_feature", "fix_errors"} {
+		if !names[name] {
+			t.Errorf("missing prompt: %s", name)
+		}
+	}
+}
+
+func TestScaffoldPrompt(t *testing.T) {
+	text, rpcErr := getPrompt(t, map[string]string{"taskflow": "app TaskFlow"}, "scaffold_crud_app", map[string]string{
+		"app":      "a reading list for book clubs",
+		"entities": "Book with title, author; Club with name",
+	})
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	for _, want := range []string{
+		"a reading list for book clubs",
+		"Book with title, author; Club with name",
+		"React with TypeScript, Node with Express, and PostgreSQL",
+		"human://examples/taskflow",
+		"human_validate",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("prompt missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestPromptRequiresArguments(t *testing.T) {
+	_, rpcErr := getPrompt(t, nil, "scaffold_crud_app", map[string]string{"app": "a blog"})
+	if rpcErr == nil || rpcErr.Code != ErrCodeParams || !strings.Contains(rpcErr.Message, "entities") {
+		t.Errorf("expected a missing-argument error naming entities, got %+v", rpcErr)
+	}
+	_, rpcErr = getPrompt(t, nil, "write_novel", nil)
+	if rpcErr == nil || rpcErr.Code != ErrCodeParams {
+		t.Errorf("expected an unknown-prompt error, got %+v", rpcErr)
+	}
+}
+
+func TestFixErrorsPromptIncludesDiagnostics(t *testing.T) {
+	source := "app Test is a web application\n\npage Home:\n  show a list of widgets\n"
+	text, rpcErr := getPrompt(t, nil, "fix_errors", map[string]string{"source": source})
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	if !strings.Contains(text, "page Home:") || !strings.Contains(text, "[W201]") {
+		t.Errorf("expected the source and its diagnostics in the prompt:\n%s", text)
+	}
+}
+
+// callTool runs one tools/call on a fresh server and returns its result.
+func callTool(t *testing.T, name string, args any, more ...string) []CallToolResult {
+	t.Helper()
+	raw, _ := json.Marshal(args)
+	requests := append([]string{`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"` + name + `","arguments":` + string(raw) + `}}`}, more...)
+	var results []CallToolResult
+	for _, resp := range runRequests(t, "", nil, requests...) {
+		resultBytes, _ := json.Marshal(resp.Result)
+		var result CallToolResult
+		json.Unmarshal(resultBytes, &result)
+		results = append(results, result)
+	}
+	return results
+}
+
+const projectSource = `app Notes is a web application
+
+data Note:
+  title is text, required
+  body is text
+
+build with:
+  backend using Node with Express
+  database using PostgreSQL`
+
+func TestGenerateProject(t *testing.T) {
+	results := callTool(t, "human_generate_project", map[string]any{
+		"source":     projectSource,
+		"generators": []string{"postgres", "react"},
+		"files":      []string{"*.sql"},
+	}, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"human_read_file","arguments":{"path":"postgres"}}}`)
+
+	result := results[0]
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", result.Content[0].Text)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"Generated Notes:",
+		"postgres",
+		"react          skipped — not enabled by this app's build block",
+		"postgres/migrations/",
+		"--- postgres/migrations/",
+		"CREATE TABLE",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "node/src/") {
+		t.Errorf("only the named generators should run:\n%s", text)
+	}
+
+	// human_read_file reads from the same build.
+	if results[1].IsError || !strings.Contains(results[1].Content[0].Text, "migrations/") {
+		t.Errorf("human_read_file should list the generated project: %s", results[1].Content[0].Text)
+	}
+}
+
+func TestGenerateProjectCapsContents(t *testing.T) {
+	results := callTool(t, "human_generate_project", map[string]any{
+		"source":     projectSource,
+		"generators": []string{"postgres"},
+		"files":      []string{"postgres"},
+		"max_bytes":  40,
+	})
+	text := results[0].Content[0].Text
+	if !strings.Contains(text, "[Truncated at 40 of ") {
+		t.Errorf("expected the first file truncated at max_bytes:\n%s", text)
+	}
+	if !strings.Contains(text, "more matching files omitted") {
+		t.Errorf("expected the remaining files omitted:\n%s", text)
+	}
+}
+
+func TestGenerateProjectErrors(t *testing.T) {
+	results := callTool(t, "human_generate_project", map[string]any{"source": projectSource, "generators": []string{"cobol"}})
+	if !results[0].IsError || !strings.Contains(results[0].Content[0].Text, `unknown generator "cobol"`) {
+		t.Errorf("expected an unknown-generator error, got: %s", results[0].Content[0].Text)
+	}
+	results = callTool(t, "human_generate_project", map[string]any{})
+	if !results[0].IsError {
+		t.Error("expected an error without source")
+	}
+}
+
+func TestMatchesAny(t *testing.T) {
+	tests := []struct {
+		file     string
+		patterns []string
+		want     bool
+	}{
+		{"node/src/index.ts", []string{"node/src/index.ts"}, true},
+		{"node/src/index.ts", []string{"./node/src/"}, true},
+		{"node/src/index.ts", []string{"node"}, true},
+		{"node/src/index.ts", []string{"node/src/*.ts"}, true},
+		{"node/prisma/schema.prisma", []string{"*.prisma"}, true},
+		{"node/src/index.ts", []string{"nod"}, false},
+		{"node/src/index.ts", []string{"react/*"}, false},
+		{"node/src/index.ts", nil, false},
+	}
+	for _, tt := range tests {
+		if got := matchesAny(tt.file, tt.patterns); got != tt.want {
+			t.Errorf("matchesAny(%q, %q) = %v, want %v", tt.file, tt.patterns, got, tt.want)
+		}
+	}
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -1,10 +1,15 @@
 package mcp
 
-// AllTools returns the tool definitions for all 6 MCP tools.
+// readOnly annotates the tools that only read: everything but the two
+// that write generated code to disk.
+var readOnly = &ToolAnnotations{ReadOnlyHint: true, IdempotentHint: true}
+
+// AllTools returns the tool definitions for all 7 MCP tools.
 func AllTools() []Tool {
 	return []Tool{
 		{
 			Name:        "human_build",
+			Title:       "Build a Human app",
 			Description: "Compile a .human source file into production-ready code. Runs the full pipeline: parse, IR, analyze, and code generation. Returns a file manifest and key file contents.",
 			InputSchema: map[string]any{
 				"type": "object",
@@ -20,9 +25,11 @@ func AllTools() []Tool {
 				},
 				"required": []string{"source"},
 			},
+			Annotations: &ToolAnnotations{IdempotentHint: true},
 		},
 		{
 			Name:        "human_validate",
+			Title:       "Validate Human source",
 			Description: "Validate a .human source file without generating code. Runs parse and semantic analysis, returning structured diagnostics (errors, warnings, suggestions).",
 			InputSchema: map[string]any{
 				"type": "object",
@@ -34,9 +41,11 @@ func AllTools() []Tool {
 				},
 				"required": []string{"source"},
 			},
+			Annotations: readOnly,
 		},
 		{
 			Name:        "human_ir",
+			Title:       "Show the Intent IR",
 			Description: "Parse a .human source file and return the Intent IR as YAML. Useful for inspecting the intermediate representation before code generation.",
 			InputSchema: map[string]any{
 				"type": "object",
@@ -48,9 +57,11 @@ func AllTools() []Tool {
 				},
 				"required": []string{"source"},
 			},
+			Annotations: readOnly,
 		},
 		{
 			Name:        "human_examples",
+			Title:       "Browse examples",
 			Description: "List available example .human applications, or return the source of a specific example. Examples demonstrate language features and best practices.",
 			InputSchema: map[string]any{
 				"type": "object",
@@ -61,17 +72,21 @@ func AllTools() []Tool {
 					},
 				},
 			},
+			Annotations: readOnly,
 		},
 		{
 			Name:        "human_spec",
+			Title:       "Read the language spec",
 			Description: "Return the complete Human language specification (LANGUAGE_SPEC.md). Use this to understand the grammar, keywords, and syntax rules.",
 			InputSchema: map[string]any{
 				"type":       "object",
 				"properties": map[string]any{},
 			},
+			Annotations: readOnly,
 		},
 		{
 			Name:        "human_read_file",
+			Title:       "Read a generated file",
 			Description: "Read a file from the last build output. Use after human_build to inspect individual generated files.",
 			InputSchema: map[string]any{
 				"type": "object",
@@ -83,6 +98,37 @@ func AllTools() []Tool {
 				},
 				"required": []string{"path"},
 			},
+			Annotations: readOnly,
+		},
+		{
+			Name:        "human_generate_project",
+			Title:       "Generate a project",
+			Description: "Run the build — or only the named generators — in a temporary directory and return the generated file tree with each file's size, plus the contents of the files asked for, capped at max_bytes. Use it to inspect generated code and iterate on the .human source; human_read_file reads any other file from the same build.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"source": map[string]any{
+						"type":        "string",
+						"description": "The .human source code to compile.",
+					},
+					"generators": map[string]any{
+						"type":        "array",
+						"items":       map[string]any{"type": "string"},
+						"description": "Optional generators to run (e.g. ['react', 'node', 'postgres']). If omitted, runs every generator the build block enables.",
+					},
+					"files": map[string]any{
+						"type":        "array",
+						"items":       map[string]any{"type": "string"},
+						"description": "Files whose contents to return: paths (e.g. 'node/src/index.ts'), directories (e.g. 'react/src/pages'), or globs (e.g. '*.prisma', 'node/src/routes/*.ts'). If omitted, returns only the file tree.",
+					},
+					"max_bytes": map[string]any{
+						"type":        "integer",
+						"description": "Cap on the total size of the returned file contents. Defaults to 100000; at most 1048576.",
+					},
+				},
+				"required": []string{"source"},
+			},
+			Annotations: &ToolAnnotations{IdempotentHint: true},
 		},
 	}
 }
@@ -14,10 +14,10 @@ type Request struct {
 
 // Response is a JSON-RPC 2.0 response message.
 type Response struct {
-	JSONRPC string     `json:"jsonrpc"`
+	JSONRPC string          `json:"jsonrpc"`
 	ID      json.RawMessage `json:"id"`
-	Result  any        `json:"result,omitempty"`
-	Error   *RPCError  `json:"error,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
 }
 
 // RPCError is a JSON-RPC 2.0 error object.
@@ -31,7 +31,11 @@ const (
 	ErrCodeParse      = -32700
 	ErrCodeInvalidReq = -32600
 	ErrCodeMethodNot  = -32601
+	ErrCodeParams     = -32602
 	ErrCodeInternal   = -32603
+
+	// ErrCodeNotFound is MCP's code for a resource that does not exist.
+	ErrCodeNotFound = -32002
 )
 
 // ── MCP Protocol Types ──
@@ -58,7 +62,9 @@ type InitializeResult struct {
 
 // ServerCapabilities advertises what the server supports.
 type ServerCapabilities struct {
-	Tools *ToolsCapability `json:"tools,omitempty"`
+	Tools     *ToolsCapability     `json:"tools,omitempty"`
+	Resources *ResourcesCapability `json:"resources,omitempty"`
+	Prompts   *PromptsCapability   `json:"prompts,omitempty"`
 }
 
 // ToolsCapability indicates tool support.
@@ -66,17 +72,40 @@ type ToolsCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
 
+// ResourcesCapability indicates resource support.
+type ResourcesCapability struct {
+	Subscribe   bool `json:"subscribe,omitempty"`
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// PromptsCapability indicates prompt support.
+type PromptsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
 // ServerInfo identifies the MCP server.
 type ServerInfo struct {
 	Name    string `json:"name"`
+	Title   string `json:"title,omitempty"`
 	Version string `json:"version"`
 }
 
 // Tool describes an MCP tool.
 type Tool struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	InputSchema any    `json:"inputSchema"`
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description"`
+	InputSchema any              `json:"inputSchema"`
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are hints about a tool's behavior, so clients can decide
+// which calls need the user's confirmation.
+type ToolAnnotations struct {
+	ReadOnlyHint    bool `json:"readOnlyHint"`
+	DestructiveHint bool `json:"destructiveHint"`
+	IdempotentHint  bool `json:"idempotentHint"`
+	OpenWorldHint   bool `json:"openWorldHint"`
 }
 
 // ToolsListResult is returned by tools/list.
@@ -101,3 +130,76 @@ type ContentItem struct {
 	Type string `json:"type"` // "text"
 	Text string `json:"text"`
 }
+
+// ── Resources ──
+
+// Resource describes a document the server can read.
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourcesListResult is returned by resources/list.
+type ResourcesListResult struct {
+	Resources []Resource `json:"resources"`
+}
+
+// ReadResourceParams is sent by the client in resources/read.
+type ReadResourceParams struct {
+	URI string `json:"uri"`
+}
+
+// ReadResourceResult is returned by resources/read.
+type ReadResourceResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+// ResourceContents is the text of a resource.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// ── Prompts ──
+
+// Prompt describes a prompt template the client can offer its user.
+type Prompt struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument is an argument a prompt takes.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptsListResult is returned by prompts/list.
+type PromptsListResult struct {
+	Prompts []Prompt `json:"prompts"`
+}
+
+// GetPromptParams is sent by the client in prompts/get.
+type GetPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments"`
+}
+
+// GetPromptResult is returned by prompts/get.
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// PromptMessage is a message of a prompt.
+type PromptMessage struct {
+	Role    string      `json:"role"` // "user" or "assistant"
+	Content ContentItem `json:"content"`
+}
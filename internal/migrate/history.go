@@ -0,0 +1,81 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Dir is where a project keeps its migration history, one JSON file per
+// migration. Unlike the rest of .human it belongs in version control: the
+// databases built from the app have had these migrations.
+var Dir = filepath.Join(".human", "migrations")
+
+// migrationFile matches the files of a history: "0002_add_task_priority.json".
+var migrationFile = regexp.MustCompile(`^\d{4}_\w+\.json$`)
+
+// Load reads the migration history in dir, oldest first. A directory that
+// doesn't exist holds no history.
+func Load(dir string) ([]*ir.Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && migrationFile.MatchString(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var history []*ir.Migration
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		var m ir.Migration
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		history = append(history, &m)
+	}
+	return history, nil
+}
+
+// Record adds the migration taking the history in dir to app's data models,
+// if they changed, and sets app.Migrations to the history. It returns the
+// migration added, or nil.
+func Record(dir string, app *ir.Application) (*ir.Migration, error) {
+	history, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	m := Next(history, app.Data)
+	if m != nil {
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding migration %s: %w", m.Name, err)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("creating %s: %w", dir, err)
+		}
+		path := filepath.Join(dir, m.Name+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", path, err)
+		}
+		history = append(history, m)
+	}
+	app.Migrations = history
+	return m, nil
+}
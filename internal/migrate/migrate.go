@@ -0,0 +1,384 @@
+// Package migrate plans an app's database migrations. The build keeps the
+// history of its schema, one ir.Migration per change to the data models,
+// and the backends generate each as a migration of their own framework:
+// Prisma Migrate, Alembic, or golang-migrate.
+package migrate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Diff returns the changes taking the data models old to cur: new models
+// first, belongs_to targets before the models linking to them, then
+// changes to models in both, then dropped models, linking models first.
+//
+// A model that loses one field and gains another of the same type had the
+// field renamed; any other field that goes away is dropped, with its
+// values.
+func Diff(old, cur []*ir.DataModel) []*ir.SchemaChange {
+	oldModels, curModels := map[string]*ir.DataModel{}, map[string]bool{}
+	for _, m := range old {
+		oldModels[m.Name] = m
+	}
+	var creates, changes, drops []*ir.SchemaChange
+	for _, m := range ordered(cur) {
+		curModels[m.Name] = true
+		prev, ok := oldModels[m.Name]
+		if !ok {
+			creates = append(creates, &ir.SchemaChange{Kind: ir.CreateModel, Model: m.Name, Data: m})
+			continue
+		}
+		changes = append(changes, diffModel(prev, m)...)
+	}
+	dropped := ordered(old)
+	slices.Reverse(dropped)
+	for _, m := range dropped {
+		if !curModels[m.Name] {
+			drops = append(drops, &ir.SchemaChange{Kind: ir.DropModel, Model: m.Name, Data: m})
+		}
+	}
+	return append(append(creates, changes...), drops...)
+}
+
+// diffModel returns the changes to a model's fields and belongs_to links.
+func diffModel(prev, cur *ir.DataModel) []*ir.SchemaChange {
+	oldCols, curCols := Columns(prev), Columns(cur)
+	var added, dropped, changes []*ir.SchemaChange
+	for _, f := range curCols {
+		pf := fieldNamed(oldCols, f.Name)
+		switch {
+		case pf == nil:
+			added = append(added, &ir.SchemaChange{Kind: ir.AddField, Model: cur.Name, Field: f})
+		case !sameField(pf, f):
+			changes = append(changes, &ir.SchemaChange{Kind: ir.ChangeField, Model: cur.Name, Field: f, From: pf})
+		}
+	}
+	for _, pf := range oldCols {
+		if fieldNamed(curCols, pf.Name) == nil {
+			dropped = append(dropped, &ir.SchemaChange{Kind: ir.DropField, Model: cur.Name, From: pf})
+		}
+	}
+	if len(added) == 1 && len(dropped) == 1 && sameType(dropped[0].From, added[0].Field) {
+		added[0].Kind, added[0].From = ir.RenameField, dropped[0].From
+		changes = append(added, changes...)
+	} else {
+		changes = append(append(added, changes...), dropped...)
+	}
+
+	oldTargets, curTargets := belongsTo(prev), belongsTo(cur)
+	for _, target := range curTargets {
+		if !slices.Contains(oldTargets, target) {
+			changes = append(changes, &ir.SchemaChange{Kind: ir.AddRelation, Model: cur.Name, Target: target})
+		}
+	}
+	for _, target := range oldTargets {
+		if !slices.Contains(curTargets, target) {
+			changes = append(changes, &ir.SchemaChange{Kind: ir.DropRelation, Model: cur.Name, Target: target})
+		}
+	}
+	return changes
+}
+
+// Columns returns the fields of a model that the database stores: its
+// fields and, for a versioned model, the version counter.
+func Columns(m *ir.DataModel) []*ir.DataField {
+	cols := slices.Clone(m.Fields)
+	if m.Versioned && fieldNamed(cols, ir.VersionColumn) == nil {
+		cols = append(cols, &ir.DataField{Name: ir.VersionColumn, Type: "number", Required: true, Default: "1"})
+	}
+	return cols
+}
+
+// belongsTo returns the models a model belongs to, in declaration order.
+func belongsTo(m *ir.DataModel) []string {
+	var targets []string
+	for _, rel := range m.Relations {
+		if rel.Kind == "belongs_to" {
+			targets = append(targets, rel.Target)
+		}
+	}
+	return targets
+}
+
+func fieldNamed(fields []*ir.DataField, name string) *ir.DataField {
+	for _, f := range fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// sameType reports whether two fields hold the same values.
+func sameType(a, b *ir.DataField) bool {
+	return a.Type == b.Type && slices.Equal(a.EnumValues, b.EnumValues)
+}
+
+// sameField reports whether two fields' columns are alike.
+func sameField(a, b *ir.DataField) bool {
+	return sameType(a, b) && a.Required == b.Required && a.Unique == b.Unique && a.Default == b.Default
+}
+
+// ordered returns models with the ones they belong to before them, as far
+// as links allow, and otherwise in declaration order.
+func ordered(models []*ir.DataModel) []*ir.DataModel {
+	names := map[string]*ir.DataModel{}
+	for _, m := range models {
+		names[m.Name] = m
+	}
+	var out []*ir.DataModel
+	seen := map[string]bool{}
+	var visit func(m *ir.DataModel)
+	visit = func(m *ir.DataModel) {
+		if seen[m.Name] {
+			return
+		}
+		seen[m.Name] = true
+		for _, target := range belongsTo(m) {
+			if t, ok := names[target]; ok {
+				visit(t)
+			}
+		}
+		out = append(out, m)
+	}
+	for _, m := range models {
+		visit(m)
+	}
+	return out
+}
+
+// Schema returns the data models a history of migrations leaves.
+func Schema(history []*ir.Migration) []*ir.DataModel {
+	var models []*ir.DataModel
+	index := func(name string) int {
+		return slices.IndexFunc(models, func(m *ir.DataModel) bool { return m.Name == name })
+	}
+	for _, mig := range history {
+		for _, c := range mig.Changes {
+			if c.Kind == ir.CreateModel {
+				// The version counter is kept as a field, so later
+				// changes to it apply like any other field's.
+				models = append(models, &ir.DataModel{
+					Name:      c.Data.Name,
+					Fields:    Columns(c.Data),
+					Relations: slices.Clone(c.Data.Relations),
+				})
+				continue
+			}
+			i := index(c.Model)
+			if i < 0 {
+				continue
+			}
+			m := *models[i]
+			switch c.Kind {
+			case ir.DropModel:
+				models = slices.Delete(models, i, i+1)
+				continue
+			case ir.AddField:
+				m.Fields = append(slices.Clone(m.Fields), c.Field)
+			case ir.DropField:
+				m.Fields = slices.DeleteFunc(slices.Clone(m.Fields), func(f *ir.DataField) bool { return f.Name == c.From.Name })
+			case ir.RenameField, ir.ChangeField:
+				m.Fields = slices.Clone(m.Fields)
+				for j, f := range m.Fields {
+					if f.Name == c.From.Name {
+						m.Fields[j] = c.Field
+					}
+				}
+			case ir.AddRelation:
+				m.Relations = append(slices.Clone(m.Relations), &ir.Relation{Kind: "belongs_to", Target: c.Target})
+			case ir.DropRelation:
+				m.Relations = slices.DeleteFunc(slices.Clone(m.Relations), func(r *ir.Relation) bool {
+					return r.Kind == "belongs_to" && r.Target == c.Target
+				})
+			}
+			models[i] = &m
+		}
+	}
+	return models
+}
+
+// Next returns the migration taking a history to the data models cur, or
+// nil when they have nothing to change. The first migration is named
+// initial; later ones after what they change.
+func Next(history []*ir.Migration, cur []*ir.DataModel) *ir.Migration {
+	changes := Diff(Schema(history), cur)
+	if len(changes) == 0 {
+		return nil
+	}
+	name := "initial"
+	if len(history) > 0 {
+		name = changeName(changes)
+	}
+	return &ir.Migration{Name: fmt.Sprintf("%04d_%s", len(history)+1, name), Changes: changes}
+}
+
+// History returns an app's migrations: the ones the build recorded or,
+// for an app built without its history, one creating its data models.
+func History(app *ir.Application) []*ir.Migration {
+	if len(app.Migrations) > 0 || len(app.Data) == 0 {
+		return app.Migrations
+	}
+	return []*ir.Migration{Next(nil, app.Data)}
+}
+
+// changeName names a migration after its changes: "add_task_priority",
+// or "update_task" when it makes several to one model.
+func changeName(changes []*ir.SchemaChange) string {
+	if len(changes) == 1 {
+		c := changes[0]
+		model := snake(c.Model)
+		switch c.Kind {
+		case ir.CreateModel:
+			return "create_" + model
+		case ir.DropModel:
+			return "drop_" + model
+		case ir.AddField:
+			return "add_" + model + "_" + snake(c.Field.Name)
+		case ir.DropField:
+			return "drop_" + model + "_" + snake(c.From.Name)
+		case ir.RenameField:
+			return "rename_" + model + "_" + snake(c.From.Name) + "_to_" + snake(c.Field.Name)
+		case ir.ChangeField:
+			return "change_" + model + "_" + snake(c.Field.Name)
+		case ir.AddRelation:
+			return "link_" + model + "_to_" + snake(c.Target)
+		case ir.DropRelation:
+			return "unlink_" + model + "_from_" + snake(c.Target)
+		}
+	}
+	for _, c := range changes[1:] {
+		if c.Model != changes[0].Model {
+			return "update_schema"
+		}
+	}
+	return "update_" + snake(changes[0].Model)
+}
+
+// Describe returns a change as a line of a migration plan, e.g.
+// "Rename Task.title to name".
+func Describe(c *ir.SchemaChange) string {
+	switch c.Kind {
+	case ir.CreateModel:
+		return "Create " + c.Model
+	case ir.DropModel:
+		return "Drop " + c.Model
+	case ir.AddField:
+		return fmt.Sprintf("Add %s.%s", c.Model, c.Field.Name)
+	case ir.DropField:
+		return fmt.Sprintf("Drop %s.%s", c.Model, c.From.Name)
+	case ir.RenameField:
+		return fmt.Sprintf("Rename %s.%s to %s", c.Model, c.From.Name, c.Field.Name)
+	case ir.ChangeField:
+		var what []string
+		if c.From.Type != c.Field.Type {
+			what = append(what, c.From.Type+" → "+c.Field.Type)
+		} else if !slices.Equal(c.From.EnumValues, c.Field.EnumValues) {
+			what = append(what, "values "+strings.Join(c.Field.EnumValues, ", "))
+		}
+		if c.Field.Required != c.From.Required {
+			what = append(what, map[bool]string{true: "required", false: "optional"}[c.Field.Required])
+		}
+		if c.Field.Unique != c.From.Unique {
+			what = append(what, map[bool]string{true: "unique", false: "not unique"}[c.Field.Unique])
+		}
+		if c.Field.Default != c.From.Default {
+			if c.Field.Default == "" {
+				what = append(what, "no default")
+			} else {
+				what = append(what, fmt.Sprintf("default %q", c.Field.Default))
+			}
+		}
+		return fmt.Sprintf("Change %s.%s: %s", c.Model, c.Field.Name, strings.Join(what, ", "))
+	case ir.AddRelation:
+		return fmt.Sprintf("Link %s to %s", c.Model, c.Target)
+	case ir.DropRelation:
+		return fmt.Sprintf("Unlink %s from %s", c.Model, c.Target)
+	}
+	return c.Kind + " " + c.Model
+}
+
+// Warning returns what applying a change risks for the data already in
+// the database, or "" when it keeps every value.
+func Warning(c *ir.SchemaChange) string {
+	switch c.Kind {
+	case ir.DropModel:
+		return fmt.Sprintf("drops the %s table and its records", c.Model)
+	case ir.DropField:
+		return fmt.Sprintf("drops %s.%s and its values", c.Model, c.From.Name)
+	case ir.DropRelation:
+		return fmt.Sprintf("drops the links from %s records to %s", c.Model, c.Target)
+	case ir.AddRelation:
+		return fmt.Sprintf("existing %s records aren't linked to a %s", c.Model, c.Target)
+	case ir.ChangeField:
+		if c.From.Type != c.Field.Type {
+			return fmt.Sprintf("converts %s.%s from %s to %s; values that don't convert fail the migration", c.Model, c.Field.Name, c.From.Type, c.Field.Type)
+		}
+		for _, v := range c.From.EnumValues {
+			if !slices.Contains(c.Field.EnumValues, v) {
+				return fmt.Sprintf("removes %s.%s's value %s; records holding it fail the migration", c.Model, c.Field.Name, v)
+			}
+		}
+		if c.Field.Required && !c.From.Required {
+			return fmt.Sprintf("makes %s.%s required; records without one fail the migration", c.Model, c.Field.Name)
+		}
+	}
+	return ""
+}
+
+// Invert returns the change undoing c.
+func Invert(c *ir.SchemaChange) *ir.SchemaChange {
+	inv := *c
+	switch c.Kind {
+	case ir.CreateModel:
+		inv.Kind = ir.DropModel
+	case ir.DropModel:
+		inv.Kind = ir.CreateModel
+	case ir.AddField:
+		inv.Kind, inv.Field, inv.From = ir.DropField, nil, c.Field
+	case ir.DropField:
+		inv.Kind, inv.Field, inv.From = ir.AddField, c.From, nil
+	case ir.RenameField, ir.ChangeField:
+		inv.Field, inv.From = c.From, c.Field
+	case ir.AddRelation:
+		inv.Kind = ir.DropRelation
+	case ir.DropRelation:
+		inv.Kind = ir.AddRelation
+	}
+	return &inv
+}
+
+// Inverted returns the changes undoing a migration, in the order to make
+// them.
+func Inverted(m *ir.Migration) []*ir.SchemaChange {
+	changes := make([]*ir.SchemaChange, len(m.Changes))
+	for i, c := range m.Changes {
+		changes[len(changes)-1-i] = Invert(c)
+	}
+	return changes
+}
+
+// snake converts a model or field name to snake_case: "TaskTag" →
+// "task_tag", "due date" → "due_date".
+func snake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == ' ' || r == '-':
+			b.WriteByte('_')
+		case unicode.IsUpper(r):
+			if i > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
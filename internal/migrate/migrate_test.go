@@ -0,0 +1,128 @@
+package migrate
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func taskModels() []*ir.DataModel {
+	return []*ir.DataModel{
+		{Name: "Task", Fields: []*ir.DataField{
+			{Name: "title", Type: "text", Required: true},
+			{Name: "status", Type: "enum", EnumValues: []string{"open", "done"}},
+		}, Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}}},
+		{Name: "User", Fields: []*ir.DataField{
+			{Name: "email", Type: "email", Required: true, Unique: true},
+		}},
+	}
+}
+
+func describe(changes []*ir.SchemaChange) []string {
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, Describe(c))
+	}
+	return lines
+}
+
+func TestDiffCreatesLinkedModelsFirst(t *testing.T) {
+	got := strings.Join(describe(Diff(nil, taskModels())), "; ")
+	if got != "Create User; Create Task" {
+		t.Errorf("Diff = %s", got)
+	}
+}
+
+func TestDiffFieldChanges(t *testing.T) {
+	cur := taskModels()
+	cur[0].Fields = []*ir.DataField{
+		{Name: "name", Type: "text", Required: true},
+		{Name: "status", Type: "enum", EnumValues: []string{"open", "doing", "done"}, Required: true},
+	}
+	cur[0].Relations = nil
+	cur = cur[:1]
+
+	got := describe(Diff(taskModels(), cur))
+	want := []string{
+		"Rename Task.title to name",
+		"Change Task.status: values open, doing, done, required",
+		"Unlink Task from User",
+		"Drop User",
+	}
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Errorf("Diff =\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+func TestDiffKeepsTwoNewFieldsApart(t *testing.T) {
+	cur := taskModels()
+	cur[1].Fields = []*ir.DataField{
+		{Name: "login", Type: "email"},
+		{Name: "backup", Type: "email"},
+	}
+	got := strings.Join(describe(Diff(taskModels(), cur)), "; ")
+	if strings.Contains(got, "Rename") {
+		t.Errorf("expected no rename when two fields are added: %s", got)
+	}
+}
+
+func TestNextReplaysHistory(t *testing.T) {
+	first := Next(nil, taskModels())
+	if first == nil || first.Name != "0001_initial" {
+		t.Fatalf("Next(nil) = %+v", first)
+	}
+	history := []*ir.Migration{first}
+	if m := Next(history, taskModels()); m != nil {
+		t.Errorf("expected no migration for unchanged models, got %s", m.Name)
+	}
+
+	cur := taskModels()
+	cur[0].Fields = append(cur[0].Fields, &ir.DataField{Name: "priority", Type: "number"})
+	second := Next(history, cur)
+	if second == nil || second.Name != "0002_add_task_priority" {
+		t.Fatalf("Next = %+v", second)
+	}
+	if m := Next(append(history, second), cur); m != nil {
+		t.Errorf("expected the replayed history to match, got %s", m.Name)
+	}
+}
+
+func TestWarning(t *testing.T) {
+	cur := taskModels()
+	cur[1].Fields = nil
+	changes := Diff(taskModels(), cur)
+	if len(changes) != 1 || Warning(changes[0]) != "drops User.email and its values" {
+		t.Errorf("Warning = %q", Warning(changes[0]))
+	}
+	if w := Warning(Invert(changes[0])); w != "" {
+		t.Errorf("expected adding an optional field to be safe, got %q", w)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "migrations")
+	app := &ir.Application{Data: taskModels()}
+	m, err := Record(dir, app)
+	if err != nil || m == nil || m.Name != "0001_initial" {
+		t.Fatalf("Record = %+v, %v", m, err)
+	}
+
+	app = &ir.Application{Data: taskModels()}
+	if m, err := Record(dir, app); err != nil || m != nil {
+		t.Errorf("expected nothing recorded for an unchanged app, got %+v, %v", m, err)
+	}
+	if len(app.Migrations) != 1 {
+		t.Errorf("expected the loaded history on the app, got %d migrations", len(app.Migrations))
+	}
+
+	app.Data[1].Fields[0].Name = "login"
+	if m, _ := Record(dir, app); m == nil || m.Name != "0002_rename_user_email_to_login" {
+		t.Errorf("Record = %+v", m)
+	}
+	history, err := Load(dir)
+	if err != nil || len(history) != 2 || history[1].Changes[0].Kind != ir.RenameField {
+		t.Errorf("Load = %+v, %v", history, err)
+	}
+}
@@ -0,0 +1,296 @@
+package migrate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Dialect is how a backend's ORM lays out its tables in PostgreSQL: their
+// names, column types, and constraints. Up and Down render a migration as
+// the SQL building the same tables the ORM expects.
+type Dialect struct {
+	Table      func(model string) string  // a model's table
+	Column     func(field string) string  // a field's column
+	ForeignKey func(target string) string // the column linking to a belongs_to target
+	Type       func(f *ir.DataField) string
+
+	// EnumType names the native enum type of a model's enum field; nil
+	// keeps enum values as text.
+	EnumType func(model, field string) string
+
+	// Constraint names a table's primary key ("pkey"), or a column's
+	// unique index ("key") or foreign key ("fkey").
+	Constraint func(table, column, kind string) string
+
+	// Skip reports whether a field has no column of its own, such as a
+	// created field the ORM's timestamps stand for. Nil skips none.
+	Skip func(f *ir.DataField) bool
+
+	ID         string   // the id column's type, e.g. "UUID DEFAULT gen_random_uuid()"
+	Reference  string   // a foreign key column's type, e.g. "TEXT NOT NULL"
+	Timestamps []string // columns every table ends with
+}
+
+// Up returns the SQL applying a migration.
+func (d Dialect) Up(m *ir.Migration) string {
+	return d.render(m.Changes)
+}
+
+// Down returns the SQL reverting a migration.
+func (d Dialect) Down(m *ir.Migration) string {
+	return d.render(Inverted(m))
+}
+
+// render returns the SQL making changes, one block per change. Foreign
+// keys come last, once every table they link exists.
+func (d Dialect) render(changes []*ir.SchemaChange) string {
+	var b, fks strings.Builder
+	for i, c := range changes {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "-- %s\n", Describe(c))
+		switch c.Kind {
+		case ir.CreateModel:
+			d.createTable(&b, &fks, c.Data)
+		case ir.DropModel:
+			fmt.Fprintf(&b, "DROP TABLE %s;\n", quote(d.Table(c.Model)))
+			for _, f := range Columns(c.Data) {
+				if d.native(f) {
+					fmt.Fprintf(&b, "DROP TYPE %s;\n", quote(d.EnumType(c.Model, f.Name)))
+				}
+			}
+		case ir.AddField:
+			d.addColumn(&b, c.Model, c.Field)
+		case ir.DropField:
+			if !d.skip(c.From) {
+				fmt.Fprintf(&b, "ALTER TABLE %s DROP COLUMN %s;\n", quote(d.Table(c.Model)), quote(d.Column(c.From.Name)))
+				if d.native(c.From) {
+					fmt.Fprintf(&b, "DROP TYPE %s;\n", quote(d.EnumType(c.Model, c.From.Name)))
+				}
+			}
+		case ir.RenameField:
+			d.renameColumn(&b, c.Model, c.From, c.Field)
+		case ir.ChangeField:
+			d.alterColumn(&b, c.Model, c.From, c.Field)
+		case ir.AddRelation:
+			table := quote(d.Table(c.Model))
+			fmt.Fprintf(&b, "ALTER TABLE %s ADD COLUMN %s %s;\n", table, quote(d.ForeignKey(c.Target)), d.Reference)
+			d.foreignKey(&fks, c.Model, c.Target)
+		case ir.DropRelation:
+			fmt.Fprintf(&b, "ALTER TABLE %s DROP COLUMN %s;\n", quote(d.Table(c.Model)), quote(d.ForeignKey(c.Target)))
+		}
+	}
+	if fks.Len() > 0 {
+		b.WriteString("\n-- Foreign keys\n")
+		b.WriteString(fks.String())
+	}
+	return b.String()
+}
+
+func (d Dialect) createTable(b, fks *strings.Builder, m *ir.DataModel) {
+	table := d.Table(m.Name)
+	var cols []string
+	cols = append(cols, quote("id")+" "+d.ID)
+	var unique []*ir.DataField
+	for _, f := range Columns(m) {
+		if d.skip(f) {
+			continue
+		}
+		if d.native(f) {
+			d.createEnum(b, m.Name, f)
+		}
+		cols = append(cols, d.column(m.Name, f))
+		if f.Unique {
+			unique = append(unique, f)
+		}
+	}
+	for _, target := range belongsTo(m) {
+		cols = append(cols, quote(d.ForeignKey(target))+" "+d.Reference)
+		d.foreignKey(fks, m.Name, target)
+	}
+	cols = append(cols, d.Timestamps...)
+	cols = append(cols, fmt.Sprintf("CONSTRAINT %s PRIMARY KEY (%s)", quote(d.Constraint(table, "id", "pkey")), quote("id")))
+	fmt.Fprintf(b, "CREATE TABLE %s (\n    %s\n);\n", quote(table), strings.Join(cols, ",\n    "))
+	for _, f := range unique {
+		d.uniqueIndex(b, m.Name, f.Name)
+	}
+}
+
+func (d Dialect) addColumn(b *strings.Builder, model string, f *ir.DataField) {
+	if d.skip(f) {
+		return
+	}
+	table, col := quote(d.Table(model)), quote(d.Column(f.Name))
+	if d.native(f) {
+		d.createEnum(b, model, f)
+	}
+	if f.Required && f.Default == "" {
+		// Existing rows need a value: the column is added with a
+		// placeholder one, which new rows don't get.
+		fmt.Fprintf(b, "ALTER TABLE %s ADD COLUMN %s %s NOT NULL DEFAULT %s;\n", table, col, d.columnType(model, f), Placeholder(f))
+		fmt.Fprintf(b, "ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;\n", table, col)
+	} else {
+		fmt.Fprintf(b, "ALTER TABLE %s ADD COLUMN %s;\n", table, d.column(model, f))
+	}
+	if f.Unique {
+		d.uniqueIndex(b, model, f.Name)
+	}
+}
+
+func (d Dialect) renameColumn(b *strings.Builder, model string, from, to *ir.DataField) {
+	if d.skip(from) || d.skip(to) {
+		return
+	}
+	table := d.Table(model)
+	fmt.Fprintf(b, "ALTER TABLE %s RENAME COLUMN %s TO %s;\n", quote(table), quote(d.Column(from.Name)), quote(d.Column(to.Name)))
+	if from.Unique {
+		fmt.Fprintf(b, "ALTER INDEX %s RENAME TO %s;\n",
+			quote(d.Constraint(table, d.Column(from.Name), "key")), quote(d.Constraint(table, d.Column(to.Name), "key")))
+	}
+	if d.native(from) {
+		fmt.Fprintf(b, "ALTER TYPE %s RENAME TO %s;\n", quote(d.EnumType(model, from.Name)), quote(d.EnumType(model, to.Name)))
+	}
+	renamed := *from
+	renamed.Name = to.Name
+	d.alterColumn(b, model, &renamed, to)
+}
+
+func (d Dialect) alterColumn(b *strings.Builder, model string, from, to *ir.DataField) {
+	if d.skip(to) {
+		return
+	}
+	table, col := quote(d.Table(model)), quote(d.Column(to.Name))
+	alter := func(format string, args ...any) {
+		fmt.Fprintf(b, "ALTER TABLE %s ALTER COLUMN %s %s;\n", table, col, fmt.Sprintf(format, args...))
+	}
+	retyped := d.columnType(model, from) != d.columnType(model, to) || d.native(to) && !slices.Equal(from.EnumValues, to.EnumValues)
+	if retyped {
+		// A default of the old type may not convert.
+		if from.Default != "" {
+			alter("DROP DEFAULT")
+		}
+		enum := ""
+		if d.native(from) {
+			enum = d.EnumType(model, from.Name)
+			if d.native(to) {
+				// The new values replace the old type's.
+				fmt.Fprintf(b, "ALTER TYPE %s RENAME TO %s;\n", quote(enum), quote(enum+"_old"))
+				enum += "_old"
+			}
+		}
+		if d.native(to) {
+			d.createEnum(b, model, to)
+		}
+		typ := d.columnType(model, to)
+		if d.native(from) || d.native(to) {
+			alter("TYPE %s USING (%s::text::%s)", typ, col, typ)
+		} else {
+			alter("TYPE %s USING %s::%s", typ, col, typ)
+		}
+		if enum != "" {
+			fmt.Fprintf(b, "DROP TYPE %s;\n", quote(enum))
+		}
+	}
+	if to.Required != from.Required {
+		alter("%s", map[bool]string{true: "SET NOT NULL", false: "DROP NOT NULL"}[to.Required])
+	}
+	if to.Default != from.Default || retyped && to.Default != "" {
+		if to.Default == "" {
+			alter("DROP DEFAULT")
+		} else {
+			alter("SET DEFAULT %s", literal(to.Default))
+		}
+	}
+	if to.Unique && !from.Unique {
+		d.uniqueIndex(b, model, to.Name)
+	} else if from.Unique && !to.Unique {
+		fmt.Fprintf(b, "DROP INDEX %s;\n", quote(d.Constraint(d.Table(model), d.Column(to.Name), "key")))
+	}
+}
+
+// column returns a field's column definition.
+func (d Dialect) column(model string, f *ir.DataField) string {
+	def := quote(d.Column(f.Name)) + " " + d.columnType(model, f)
+	if f.Required {
+		def += " NOT NULL"
+	}
+	if f.Default != "" {
+		def += " DEFAULT " + literal(f.Default)
+	}
+	return def
+}
+
+func (d Dialect) columnType(model string, f *ir.DataField) string {
+	if d.native(f) {
+		return quote(d.EnumType(model, f.Name))
+	}
+	return d.Type(f)
+}
+
+func (d Dialect) createEnum(b *strings.Builder, model string, f *ir.DataField) {
+	values := make([]string, len(f.EnumValues))
+	for i, v := range f.EnumValues {
+		values[i] = literal(v)
+	}
+	fmt.Fprintf(b, "CREATE TYPE %s AS ENUM (%s);\n", quote(d.EnumType(model, f.Name)), strings.Join(values, ", "))
+}
+
+func (d Dialect) uniqueIndex(b *strings.Builder, model, field string) {
+	table, col := d.Table(model), d.Column(field)
+	fmt.Fprintf(b, "CREATE UNIQUE INDEX %s ON %s (%s);\n", quote(d.Constraint(table, col, "key")), quote(table), quote(col))
+}
+
+func (d Dialect) foreignKey(b *strings.Builder, model, target string) {
+	table, col := d.Table(model), d.ForeignKey(target)
+	fmt.Fprintf(b, "ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s);\n",
+		quote(table), quote(d.Constraint(table, col, "fkey")), quote(col), quote(d.Table(target)), quote("id"))
+}
+
+// native reports whether a field's column has a native enum type.
+func (d Dialect) native(f *ir.DataField) bool {
+	return d.EnumType != nil && f.Type == "enum" && len(f.EnumValues) > 0
+}
+
+func (d Dialect) skip(f *ir.DataField) bool {
+	return d.Skip != nil && d.Skip(f)
+}
+
+// Placeholder returns, as SQL, the value a required column is added with
+// for the rows already in the table: the field's default, or an empty
+// value of its type.
+func Placeholder(f *ir.DataField) string {
+	if f.Default != "" {
+		return literal(f.Default)
+	}
+	switch f.Type {
+	case "number", "decimal":
+		return "0"
+	case "boolean":
+		return "false"
+	case "date":
+		return "CURRENT_DATE"
+	case "datetime":
+		return "CURRENT_TIMESTAMP"
+	case "json":
+		return "'{}'"
+	case "enum":
+		if len(f.EnumValues) > 0 {
+			return literal(f.EnumValues[0])
+		}
+	}
+	return "''"
+}
+
+// quote returns a PostgreSQL identifier, quoted.
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// literal returns a PostgreSQL string literal.
+func literal(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,78 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+var testDialect = Dialect{
+	Table:      func(model string) string { return model },
+	Column:     func(field string) string { return field },
+	ForeignKey: func(target string) string { return strings.ToLower(target) + "Id" },
+	Type:       func(f *ir.DataField) string { return "TEXT" },
+	EnumType:   func(model, field string) string { return model + "_" + field },
+	Constraint: func(table, column, kind string) string { return table + "_" + column + "_" + kind },
+	ID:         "TEXT NOT NULL",
+	Reference:  "TEXT",
+}
+
+func TestUpCreatesTables(t *testing.T) {
+	sql := testDialect.Up(Next(nil, taskModels()))
+	for _, want := range []string{
+		"-- Create User\nCREATE TABLE \"User\" (\n    \"id\" TEXT NOT NULL,\n    \"email\" TEXT NOT NULL,",
+		"CREATE UNIQUE INDEX \"User_email_key\" ON \"User\" (\"email\");",
+		"CREATE TYPE \"Task_status\" AS ENUM ('open', 'done');",
+		"\"userId\" TEXT,\n    CONSTRAINT \"Task_id_pkey\" PRIMARY KEY (\"id\")",
+		"-- Foreign keys\nALTER TABLE \"Task\" ADD CONSTRAINT \"Task_userId_fkey\" FOREIGN KEY (\"userId\") REFERENCES \"User\" (\"id\");",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("Up missing %q:\n%s", want, sql)
+		}
+	}
+}
+
+func TestUpAndDownAlterColumns(t *testing.T) {
+	cur := taskModels()
+	cur[0].Fields = []*ir.DataField{
+		{Name: "title", Type: "text", Required: true},
+		{Name: "status", Type: "enum", EnumValues: []string{"open", "doing", "done"}},
+		{Name: "note", Type: "text", Required: true},
+	}
+	m := &ir.Migration{Name: "0002_update_task", Changes: Diff(taskModels(), cur)}
+
+	up := testDialect.Up(m)
+	for _, want := range []string{
+		"ALTER TYPE \"Task_status\" RENAME TO \"Task_status_old\";",
+		"ALTER TABLE \"Task\" ALTER COLUMN \"status\" TYPE \"Task_status\" USING (\"status\"::text::\"Task_status\");",
+		"DROP TYPE \"Task_status_old\";",
+		"ALTER TABLE \"Task\" ADD COLUMN \"note\" TEXT NOT NULL DEFAULT '';\nALTER TABLE \"Task\" ALTER COLUMN \"note\" DROP DEFAULT;",
+	} {
+		if !strings.Contains(up, want) {
+			t.Errorf("Up missing %q:\n%s", want, up)
+		}
+	}
+
+	down := testDialect.Down(m)
+	if !strings.HasPrefix(down, "-- Change Task.status: values open, done\n") ||
+		!strings.HasSuffix(down, "-- Drop Task.note\nALTER TABLE \"Task\" DROP COLUMN \"note\";\n") {
+		t.Errorf("expected Down to undo the changes in reverse:\n%s", down)
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	for _, tt := range []struct {
+		field *ir.DataField
+		want  string
+	}{
+		{&ir.DataField{Type: "number"}, "0"},
+		{&ir.DataField{Type: "boolean"}, "false"},
+		{&ir.DataField{Type: "text", Default: "it's"}, "'it''s'"},
+		{&ir.DataField{Type: "enum", EnumValues: []string{"low", "high"}}, "'low'"},
+	} {
+		if got := Placeholder(tt.field); got != tt.want {
+			t.Errorf("Placeholder(%+v) = %s, want %s", tt.field, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,574 @@
+// Package mock serves a fake version of an application's API surface,
+// derived from the Intent IR, so frontend work can proceed before the
+// backend and database exist.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/barun-bash/human/internal/fixtures"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Options configures a mock server.
+type Options struct {
+	Seed    int64 // faker seed; the same seed serves the same data
+	Records int   // records generated per data model (default: the app's seed lines, or fixtures.DefaultCount)
+}
+
+// Route is a single mock endpoint inferred from an IR endpoint.
+type Route struct {
+	Method   string
+	Path     string
+	Kind     string // list, get, create, update, delete, auth, other
+	Endpoint *ir.Endpoint
+	Model    *ir.DataModel // nil when no data model could be inferred
+
+	PageSize  int      // list: default page size from "paginate with N per page"
+	Filters   []string // list: field names from "support filtering by X"
+	Search    []string // list: field names from "support searching by X"
+	SortField string   // list: field name from "sort by X"
+}
+
+// Server holds the in-memory dataset and routes for one application.
+type Server struct {
+	app    *ir.Application
+	routes []*Route
+
+	mu       sync.Mutex
+	fixtures *fixtures.Set
+	store    map[string][]map[string]any // model name → records
+	nextID   map[string]int
+}
+
+// New builds a mock server for app, loading the shared fixture set for
+// every data model up front. With the default seed and record count the
+// server returns exactly the records in the generated fixtures/ files.
+func New(app *ir.Application, opts Options) *Server {
+	set := fixtures.Options{Seed: opts.Seed, Count: opts.Records}
+	if opts.Records <= 0 {
+		set.Counts = ir.SeedCounts(app)
+	}
+	s := &Server{
+		app:      app,
+		fixtures: fixtures.Build(app, set),
+		store:    make(map[string][]map[string]any),
+		nextID:   make(map[string]int),
+	}
+
+	for _, model := range app.Data {
+		s.store[model.Name] = s.fixtures.Records[model.Name]
+		s.nextID[model.Name] = len(s.store[model.Name]) + 1
+	}
+
+	s.routes = InferRoutes(app)
+	return s
+}
+
+// InferRoutes returns the route of each of app's endpoints, in declaration
+// order, as the generated API clients call them.
+func InferRoutes(app *ir.Application) []*Route {
+	var routes []*Route
+	for _, ep := range app.APIs {
+		routes = append(routes, inferRoute(ep, app))
+	}
+	return routes
+}
+
+// Routes returns the inferred routes in declaration order.
+func (s *Server) Routes() []*Route {
+	return s.routes
+}
+
+// Handler returns an http.Handler serving every route under /api with
+// permissive CORS headers for local frontend dev servers.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		for _, route := range s.routes {
+			if route.Method == r.Method && route.Path == strings.TrimSuffix(r.URL.Path, "/") {
+				s.serve(w, r, route)
+				return
+			}
+		}
+		writeJSON(w, http.StatusNotFound, map[string]any{
+			"error": fmt.Sprintf("no mock route for %s %s", r.Method, r.URL.Path),
+		})
+	})
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, route *Route) {
+	if route.Endpoint.Auth && r.Header.Get("Authorization") == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "Authentication required"})
+		return
+	}
+
+	params := requestParams(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch route.Kind {
+	case "auth":
+		user := params
+		if route.Model != nil {
+			if recs := s.store[route.Model.Name]; len(recs) > 0 {
+				user = merge(recs[0], params)
+			}
+		}
+		delete(user, "password")
+		writeJSON(w, http.StatusOK, map[string]any{"data": user, "token": "mock-token"})
+
+	case "list":
+		s.serveList(w, r, route)
+
+	case "get":
+		if rec := s.find(route, params); rec != nil {
+			writeJSON(w, http.StatusOK, map[string]any{"data": rec})
+			return
+		}
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "Not found"})
+
+	case "create":
+		rec := params
+		if route.Model != nil {
+			name := route.Model.Name
+			rec = merge(map[string]any{"id": s.fixtures.ID(name, s.nextID[name])}, params)
+			s.nextID[name]++
+			s.store[name] = append(s.store[name], rec)
+		}
+		writeJSON(w, http.StatusCreated, map[string]any{"data": rec})
+
+	case "update":
+		rec := s.find(route, params)
+		if rec == nil {
+			writeJSON(w, http.StatusNotFound, map[string]any{"error": "Not found"})
+			return
+		}
+		for k, v := range params {
+			if k != "id" && !isIDParam(k) {
+				rec[k] = v
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": rec})
+
+	case "delete":
+		if route.Model != nil {
+			if id := lookupID(params); id != "" {
+				recs := s.store[route.Model.Name]
+				for i, rec := range recs {
+					if rec["id"] == id {
+						s.store[route.Model.Name] = append(recs[:i], recs[i+1:]...)
+						break
+					}
+				}
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"message": "deleted"}})
+
+	default:
+		writeJSON(w, http.StatusOK, map[string]any{"data": params})
+	}
+}
+
+// serveList applies filters, search, sort, and pagination to a model's
+// records. Query params: any filterable field name, search (or q), page,
+// limit (or per_page), and cursor, the id of the record the page follows.
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request, route *Route) {
+	if route.Model == nil {
+		writeJSON(w, http.StatusOK, map[string]any{"data": []any{}})
+		return
+	}
+	q := r.URL.Query()
+
+	var out []map[string]any
+	for _, rec := range s.store[route.Model.Name] {
+		if matchesFilters(rec, route, q) && matchesSearch(rec, route, q) {
+			out = append(out, rec)
+		}
+	}
+
+	if route.SortField != "" {
+		sort.SliceStable(out, func(i, j int) bool {
+			return fmt.Sprint(out[i][route.SortField]) < fmt.Sprint(out[j][route.SortField])
+		})
+	}
+
+	limit := route.PageSize
+	if limit <= 0 {
+		limit = 20
+	}
+	if v := firstNonEmpty(q.Get("limit"), q.Get("per_page"), q.Get("pageSize")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	page := 1
+	if n, err := strconv.Atoi(q.Get("page")); err == nil && n > 0 {
+		page = n
+	}
+
+	total := len(out)
+	start := (page - 1) * limit
+	if cursor := q.Get("cursor"); cursor != "" {
+		start = total
+		for i, rec := range out {
+			if fmt.Sprint(rec["id"]) == cursor {
+				start = i + 1
+				break
+			}
+		}
+	}
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	var nextCursor any
+	if end < total {
+		nextCursor = out[end-1]["id"]
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": out[start:end],
+		"pagination": map[string]any{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + limit - 1) / limit,
+			"nextCursor": nextCursor,
+		},
+	})
+}
+
+func matchesFilters(rec map[string]any, route *Route, q map[string][]string) bool {
+	fields := route.Filters
+	if len(fields) == 0 && route.Model != nil {
+		// No declared filters: allow filtering by any field.
+		for _, f := range route.Model.Fields {
+			fields = append(fields, f.Name)
+		}
+	}
+	for _, field := range fields {
+		vals, ok := q[field]
+		if !ok || len(vals) == 0 || vals[0] == "" {
+			continue
+		}
+		if !strings.EqualFold(fmt.Sprint(rec[field]), vals[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesSearch(rec map[string]any, route *Route, q map[string][]string) bool {
+	term := ""
+	for _, key := range []string{"search", "q"} {
+		if vals := q[key]; len(vals) > 0 && vals[0] != "" {
+			term = strings.ToLower(vals[0])
+		}
+	}
+	if term == "" {
+		return true
+	}
+	fields := route.Search
+	if len(fields) == 0 && route.Model != nil {
+		for _, f := range route.Model.Fields {
+			if f.Type == "text" || f.Type == "email" {
+				fields = append(fields, f.Name)
+			}
+		}
+	}
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(fmt.Sprint(rec[field])), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the record addressed by the request's id param, or the first
+// record when the endpoint takes no id (e.g. GetProfile).
+func (s *Server) find(route *Route, params map[string]any) map[string]any {
+	if route.Model == nil {
+		return nil
+	}
+	recs := s.store[route.Model.Name]
+	id := lookupID(params)
+	if id == "" {
+		if len(recs) > 0 {
+			return recs[0]
+		}
+		return nil
+	}
+	for _, rec := range recs {
+		if rec["id"] == id {
+			return rec
+		}
+	}
+	return nil
+}
+
+// ── Route inference ──
+
+var (
+	paginateRe = regexp.MustCompile(`(?i)paginate with (\d+)`)
+	filterRe   = regexp.MustCompile(`(?i)filter(?:ing)? by (.+)`)
+	searchRe   = regexp.MustCompile(`(?i)search(?:ing)? by (.+)`)
+	sortRe     = regexp.MustCompile(`(?i)^sort(?:ed)? by (.+)`)
+)
+
+// inferRoute derives method, path, and behavior for an endpoint using the
+// same naming conventions as the generated frontends' API clients.
+func inferRoute(ep *ir.Endpoint, app *ir.Application) *Route {
+	route := &Route{
+		Method:   httpMethod(ep.Name),
+		Path:     apiPath(ep.Name),
+		Endpoint: ep,
+	}
+
+	lower := strings.ToLower(ep.Name)
+	noun := stripVerb(ep.Name)
+
+	switch {
+	case strings.Contains(lower, "signup") || strings.Contains(lower, "register") ||
+		strings.Contains(lower, "login") || strings.Contains(lower, "signin"):
+		route.Kind = "auth"
+		route.Model = findModel("User", app)
+		return route
+	case strings.HasPrefix(lower, "create"):
+		route.Kind = "create"
+	case strings.HasPrefix(lower, "update"):
+		route.Kind = "update"
+	case strings.HasPrefix(lower, "delete"):
+		route.Kind = "delete"
+	case route.Method == http.MethodGet:
+		route.Kind = "get"
+		if noun != singularize(noun) && !hasIDParam(ep) {
+			route.Kind = "list"
+		}
+	default:
+		route.Kind = "other"
+	}
+
+	route.Model = findModel(singularize(noun), app)
+	if route.Model == nil {
+		route.Model = modelFromSteps(ep, app)
+	}
+
+	for _, step := range ep.Steps {
+		if m := paginateRe.FindStringSubmatch(step.Text); m != nil {
+			route.PageSize, _ = strconv.Atoi(m[1])
+		}
+		if route.Model == nil {
+			continue
+		}
+		if m := filterRe.FindStringSubmatch(step.Text); m != nil {
+			route.Filters = append(route.Filters, matchFields(route.Model, m[1])...)
+		}
+		if m := searchRe.FindStringSubmatch(step.Text); m != nil {
+			route.Search = append(route.Search, matchFields(route.Model, m[1])...)
+		}
+		if m := sortRe.FindStringSubmatch(step.Text); m != nil {
+			if fields := matchFields(route.Model, m[1]); len(fields) > 0 {
+				route.SortField = fields[0]
+			}
+		}
+	}
+	return route
+}
+
+// httpMethod mirrors the frontend API clients' method inference.
+func httpMethod(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "get"), strings.HasPrefix(lower, "list"),
+		strings.HasPrefix(lower, "search"), strings.HasPrefix(lower, "fetch"):
+		return http.MethodGet
+	case strings.HasPrefix(lower, "delete"):
+		return http.MethodDelete
+	case strings.HasPrefix(lower, "update"):
+		return http.MethodPut
+	default:
+		return http.MethodPost
+	}
+}
+
+// apiPath mirrors the frontend API clients' path inference:
+// "GetTasks" → "/api/tasks", "SignUp" → "/api/sign-up".
+func apiPath(name string) string {
+	return "/api/" + toKebabCase(stripVerb(name))
+}
+
+func stripVerb(name string) string {
+	for _, prefix := range []string{"Get", "List", "Search", "Fetch", "Create", "Update", "Delete"} {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return name[len(prefix):]
+		}
+	}
+	return name
+}
+
+func toKebabCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r + ('a' - 'A'))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func singularize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "ss"):
+		return s
+	case strings.HasSuffix(s, "s") && len(s) > 1:
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+func findModel(name string, app *ir.Application) *ir.DataModel {
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, name) {
+			return m
+		}
+	}
+	return nil
+}
+
+// modelFromSteps finds the first data model mentioned in the endpoint's
+// steps, e.g. "fetch the current user" → User.
+func modelFromSteps(ep *ir.Endpoint, app *ir.Application) *ir.DataModel {
+	for _, step := range ep.Steps {
+		for _, word := range strings.Fields(strings.ToLower(step.Text)) {
+			if m := findModel(singularize(word), app); m != nil {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// matchFields maps a phrase like "status and priority" or "due date" to
+// model field names. A field matches when its name equals a word of the
+// phrase or the phrase starts with it.
+func matchFields(model *ir.DataModel, phrase string) []string {
+	phrase = strings.ToLower(strings.TrimSpace(phrase))
+	var out []string
+	for _, f := range model.Fields {
+		name := strings.ToLower(f.Name)
+		if phrase == name || strings.HasPrefix(phrase, name+" ") {
+			out = append(out, f.Name)
+			continue
+		}
+		for _, word := range strings.FieldsFunc(phrase, func(r rune) bool { return r == ' ' || r == ',' }) {
+			if word == name {
+				out = append(out, f.Name)
+				break
+			}
+		}
+	}
+	return out
+}
+
+func hasIDParam(ep *ir.Endpoint) bool {
+	for _, p := range ep.Params {
+		if isIDParam(p.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func isIDParam(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "id" || strings.HasSuffix(lower, "_id") || strings.HasSuffix(lower, " id") || strings.HasSuffix(name, "Id")
+}
+
+func lookupID(params map[string]any) string {
+	if v, ok := params["id"]; ok {
+		return fmt.Sprint(v)
+	}
+	for k, v := range params {
+		if isIDParam(k) {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// ── HTTP helpers ──
+
+// requestParams merges query-string values and a JSON body into one map.
+func requestParams(r *http.Request) map[string]any {
+	params := make(map[string]any)
+	for k, v := range r.URL.Query() {
+		if len(v) > 0 {
+			params[k] = v[0]
+		}
+	}
+	if r.Body != nil {
+		data, _ := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		var body map[string]any
+		if json.Unmarshal(data, &body) == nil {
+			for k, v := range body {
+				params[k] = v
+			}
+		}
+	}
+	return params
+}
+
+func merge(base, overrides map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(overrides))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		out[k] = v
+	}
+	return out
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
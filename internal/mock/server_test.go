@@ -0,0 +1,256 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/fixtures"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func testApp() *ir.Application {
+	return &ir.Application{
+		Name: "TaskFlow",
+		Data: []*ir.DataModel{
+			{
+				Name: "User",
+				Fields: []*ir.DataField{
+					{Name: "name", Type: "text"},
+					{Name: "email", Type: "email"},
+					{Name: "password", Type: "text", Encrypted: true},
+				},
+			},
+			{
+				Name: "Task",
+				Fields: []*ir.DataField{
+					{Name: "title", Type: "text"},
+					{Name: "status", Type: "enum", EnumValues: []string{"pending", "done"}},
+					{Name: "due", Type: "date"},
+				},
+				Relations: []*ir.Relation{{Kind: "belongs_to", Target: "User"}},
+			},
+		},
+		APIs: []*ir.Endpoint{
+			{Name: "SignUp", Params: []*ir.Param{{Name: "name"}, {Name: "email"}, {Name: "password"}}},
+			{Name: "GetTasks", Auth: true, Steps: []*ir.Action{
+				{Type: "query", Text: "fetch all tasks for the current user"},
+				{Type: "query", Text: "sort by due date"},
+				{Type: "query", Text: "support filtering by status"},
+				{Type: "query", Text: "support searching by title"},
+				{Type: "query", Text: "paginate with 10 per page"},
+			}},
+			{Name: "CreateTask", Auth: true, Params: []*ir.Param{{Name: "title"}, {Name: "status"}}},
+			{Name: "UpdateTask", Auth: true, Params: []*ir.Param{{Name: "task_id"}, {Name: "title"}}},
+			{Name: "DeleteTask", Auth: true, Params: []*ir.Param{{Name: "task_id"}}},
+			{Name: "GetProfile", Auth: true, Steps: []*ir.Action{{Type: "query", Text: "fetch the current user"}}},
+		},
+	}
+}
+
+type listResponse struct {
+	Data       []map[string]any `json:"data"`
+	Pagination struct {
+		Page       int    `json:"page"`
+		Limit      int    `json:"limit"`
+		Total      int    `json:"total"`
+		TotalPages int    `json:"totalPages"`
+		NextCursor string `json:"nextCursor"`
+	} `json:"pagination"`
+}
+
+func do(t *testing.T, h http.Handler, method, path, body string, auth bool) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if auth {
+		req.Header.Set("Authorization", "Bearer mock-token")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestInferRoutes(t *testing.T) {
+	s := New(testApp(), Options{Seed: 1})
+
+	tests := []struct {
+		method, path, kind, model string
+	}{
+		{"POST", "/api/sign-up", "auth", "User"},
+		{"GET", "/api/tasks", "list", "Task"},
+		{"POST", "/api/task", "create", "Task"},
+		{"PUT", "/api/task", "update", "Task"},
+		{"DELETE", "/api/task", "delete", "Task"},
+		{"GET", "/api/profile", "get", "User"},
+	}
+	routes := s.Routes()
+	if len(routes) != len(tests) {
+		t.Fatalf("got %d routes, want %d", len(routes), len(tests))
+	}
+	for i, tt := range tests {
+		r := routes[i]
+		if r.Method != tt.method || r.Path != tt.path || r.Kind != tt.kind {
+			t.Errorf("route %d = %s %s (%s), want %s %s (%s)", i, r.Method, r.Path, r.Kind, tt.method, tt.path, tt.kind)
+		}
+		if r.Model == nil || r.Model.Name != tt.model {
+			t.Errorf("route %d model = %v, want %s", i, r.Model, tt.model)
+		}
+	}
+
+	list := routes[1]
+	if list.PageSize != 10 {
+		t.Errorf("PageSize = %d, want 10", list.PageSize)
+	}
+	if len(list.Filters) != 1 || list.Filters[0] != "status" {
+		t.Errorf("Filters = %v, want [status]", list.Filters)
+	}
+	if len(list.Search) != 1 || list.Search[0] != "title" {
+		t.Errorf("Search = %v, want [title]", list.Search)
+	}
+	if list.SortField != "due" {
+		t.Errorf("SortField = %q, want due", list.SortField)
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	h := New(testApp(), Options{Seed: 1, Records: 25}).Handler()
+
+	rec := do(t, h, "GET", "/api/tasks?page=3", "", true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var resp listResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data) != 5 {
+		t.Errorf("page 3 has %d records, want 5", len(resp.Data))
+	}
+	if resp.Pagination.Total != 25 || resp.Pagination.TotalPages != 3 || resp.Pagination.Limit != 10 {
+		t.Errorf("pagination = %+v", resp.Pagination)
+	}
+
+	rec = do(t, h, "GET", "/api/tasks?limit=50", "", true)
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Data) != 25 {
+		t.Errorf("limit=50 returned %d records, want 25", len(resp.Data))
+	}
+}
+
+func TestListCursor(t *testing.T) {
+	h := New(testApp(), Options{Seed: 1, Records: 25}).Handler()
+
+	seen := map[any]bool{}
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		path := "/api/tasks"
+		if cursor != "" {
+			path += "?cursor=" + cursor
+		}
+		var resp listResponse
+		json.Unmarshal(do(t, h, "GET", path, "", true).Body.Bytes(), &resp)
+		for _, rec := range resp.Data {
+			if seen[rec["id"]] {
+				t.Errorf("record %v returned twice", rec["id"])
+			}
+			seen[rec["id"]] = true
+		}
+		cursor = resp.Pagination.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+	if len(seen) != 25 {
+		t.Errorf("cursor pages returned %d records, want 25", len(seen))
+	}
+}
+
+func TestListFiltering(t *testing.T) {
+	h := New(testApp(), Options{Seed: 1, Records: 10}).Handler()
+
+	rec := do(t, h, "GET", "/api/tasks?status=done", "", true)
+	var resp listResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Pagination.Total != 5 {
+		t.Errorf("status=done matched %d records, want 5", resp.Pagination.Total)
+	}
+	for _, r := range resp.Data {
+		if r["status"] != "done" {
+			t.Errorf("filtered record has status %v", r["status"])
+		}
+	}
+}
+
+func TestAuthRequired(t *testing.T) {
+	h := New(testApp(), Options{Seed: 1}).Handler()
+
+	if rec := do(t, h, "GET", "/api/tasks", "", false); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want 401", rec.Code)
+	}
+	rec := do(t, h, "POST", "/api/sign-up", `{"name":"Ada","email":"ada@example.com","password":"secret"}`, false)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("sign-up status = %d, want 200", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `"token":"mock-token"`) {
+		t.Error("sign-up response missing token")
+	}
+	if strings.Contains(body, "secret") || strings.Contains(body, `"password"`) {
+		t.Error("sign-up response leaked password")
+	}
+}
+
+func TestCreateUpdateDelete(t *testing.T) {
+	h := New(testApp(), Options{Seed: 1, Records: 3}).Handler()
+
+	rec := do(t, h, "POST", "/api/task", `{"title":"Write docs","status":"pending"}`, true)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want 201", rec.Code)
+	}
+	const newID = "00000002-0000-4000-8000-000000000004"
+	if !strings.Contains(rec.Body.String(), `"id":"`+newID+`"`) {
+		t.Errorf("created record should get the next fixture id: %s", rec.Body.String())
+	}
+
+	rec = do(t, h, "PUT", "/api/task", `{"task_id":"`+newID+`","title":"Write better docs"}`, true)
+	if !strings.Contains(rec.Body.String(), "Write better docs") {
+		t.Errorf("update not applied: %s", rec.Body.String())
+	}
+
+	do(t, h, "DELETE", "/api/task?task_id="+newID, "", true)
+	var resp listResponse
+	json.Unmarshal(do(t, h, "GET", "/api/tasks", "", true).Body.Bytes(), &resp)
+	if resp.Pagination.Total != 3 {
+		t.Errorf("after delete total = %d, want 3", resp.Pagination.Total)
+	}
+}
+
+func TestServesSharedFixtures(t *testing.T) {
+	app := testApp()
+	h := New(app, Options{Seed: fixtures.DefaultSeed}).Handler()
+	want := fixtures.Build(app, fixtures.Options{Seed: fixtures.DefaultSeed}).Records["Task"][0]
+
+	var resp listResponse
+	json.Unmarshal(do(t, h, "GET", "/api/tasks?limit=100", "", true).Body.Bytes(), &resp)
+	for _, r := range resp.Data {
+		if r["id"] == want["id"] {
+			if r["title"] != want["title"] || r["userId"] != want["userId"] {
+				t.Errorf("served record %v differs from fixture %v", r, want)
+			}
+			return
+		}
+	}
+	t.Errorf("fixture record %v not served", want["id"])
+}
+
+func TestUnknownRoute(t *testing.T) {
+	h := New(testApp(), Options{Seed: 1}).Handler()
+	if rec := do(t, h, "GET", "/api/nope", "", true); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown route status = %d, want 404", rec.Code)
+	}
+}
@@ -9,6 +9,7 @@ type Program struct {
 	APIs           []*APIDeclaration
 	Policies       []*PolicyDeclaration
 	Workflows      []*WorkflowDeclaration
+	ModelHooks     []*ModelHookDeclaration
 	Theme          *ThemeDeclaration
 	Authentication *AuthenticationDeclaration
 	Database       *DatabaseDeclaration
@@ -17,6 +18,10 @@ type Program struct {
 	ErrorHandlers  []*ErrorHandlerDeclaration
 	Build          *BuildDeclaration
 	Architecture   *ArchitectureDeclaration
+	Accounts       *AccountsDeclaration
+	Plans          *PlansDeclaration
+	Seeds          []*SeedDeclaration
+	Imports        []*ImportDeclaration
 	Sections       []string     // section header names in order
 	Statements     []*Statement // top-level statements not in any block
 }
@@ -35,10 +40,12 @@ type AppDeclaration struct {
 //	  has a name which is text
 //	  belongs to a Team
 //	  has many Post
+//	  protect against conflicting edits
 type DataDeclaration struct {
 	Name          string
 	Fields        []*Field
 	Relationships []*Relationship
+	Versioned     bool // "protect against conflicting edits"
 	Line          int
 	File          string
 }
@@ -52,7 +59,7 @@ type DataDeclaration struct {
 type Field struct {
 	Name       string   // field name, e.g. "name", "email"
 	Type       string   // type keyword, e.g. "text", "email", "datetime"
-	Modifiers  []string // "optional", "unique", "encrypted"
+	Modifiers  []string // "optional", "unique", "encrypted", "personal"
 	EnumValues []string // for "either" fields: ["user", "admin"]
 	Default    string   // default value (from "defaults to")
 	Line       int
@@ -143,6 +150,21 @@ type WorkflowDeclaration struct {
 	File       string
 }
 
+// ModelHookDeclaration represents a lifecycle hook on a data model, run
+// by the database layer whenever a record is created, updated, or deleted.
+// The steps follow a comma on the same line, or form an indented block.
+//
+//	before a Task is created, set its status to "pending"
+//	after a User is deleted:
+//	  remove their Sessions
+type ModelHookDeclaration struct {
+	Timing     string // "before" or "after"
+	Event      string // "a Task is created"
+	Statements []*Statement
+	Line       int
+	File       string
+}
+
 // ThemeDeclaration represents visual theme configuration.
 //
 //	theme:
@@ -165,6 +187,51 @@ type AuthenticationDeclaration struct {
 	File       string
 }
 
+// AccountsDeclaration represents what users can do with their own account.
+//
+//	accounts:
+//	  users can update their profile, change password, and delete their account
+type AccountsDeclaration struct {
+	Statements []*Statement
+	Line       int
+	File       string
+}
+
+// PlansDeclaration represents the subscription plans users can buy,
+// listed after the colon or one per line.
+//
+//	plans: Free, Pro ($19/month), Enterprise
+type PlansDeclaration struct {
+	Text       string       // the plans listed after the colon
+	Statements []*Statement // plans listed one per line
+	Line       int
+	File       string
+}
+
+// SeedDeclaration represents how many sample records of a model the
+// development database is seeded with: one per "seed" line, or per line
+// of a "seed:" block.
+//
+//	seed 50 Users with realistic names and emails
+//	seed:
+//	  200 Tasks
+type SeedDeclaration struct {
+	Text string // e.g. "50 Users with realistic names and emails"
+	Line int
+	File string
+}
+
+// ImportDeclaration represents another .human file the program pulls in,
+// by a path relative to the file importing it.
+//
+//	import "models/data.human"
+//	include pages.human
+type ImportDeclaration struct {
+	Path string
+	Line int
+	File string
+}
+
 // DatabaseDeclaration represents database configuration.
 //
 //	database:
@@ -239,7 +306,8 @@ type ArchitectureDeclaration struct {
 // Statement represents a single line of structured English within a block.
 // The Kind field identifies the leading keyword for quick categorization.
 type Statement struct {
-	Kind string // lowercase first keyword: "show", "clicking", "if", "check", etc.
-	Text string // the full reconstructed text of the statement
-	Line int
+	Kind   string // lowercase first keyword: "show", "clicking", "if", "check", etc.
+	Text   string // the full reconstructed text of the statement
+	Quoted string // the string of a keyword followed only by a string: show "Hello"
+	Line   int
 }
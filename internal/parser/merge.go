@@ -79,27 +79,117 @@ func DiscoverFiles(path string) ([]string, error) {
 
 // ParseFiles parses each file and returns the resulting programs.
 // The File field is set on every declaration in each program.
-// Fails fast on the first parse error, including the filename in the message.
+// Every file is parsed even when one has syntax errors; they are returned
+// together as *Errors, each naming its file.
 func ParseFiles(files []string) ([]*Program, error) {
 	programs := make([]*Program, 0, len(files))
+	var syntax Errors
 
 	for _, file := range files {
-		source, err := os.ReadFile(file)
+		prog, err := parseFile(file, &syntax)
 		if err != nil {
-			return nil, fmt.Errorf("reading %s: %w", file, err)
+			return nil, err
 		}
+		programs = append(programs, prog)
+	}
 
-		prog, err := Parse(string(source))
-		if err != nil {
-			return nil, fmt.Errorf("parse error in %s: %w", file, err)
+	if len(syntax.Diagnostics) > 0 {
+		return nil, &syntax
+	}
+	return programs, nil
+}
+
+// parseFile parses one file, tagging its declarations with it and adding
+// its syntax errors to syntax. The error is for a file that can't be read.
+func parseFile(file string, syntax *Errors) (*Program, error) {
+	source, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file, err)
+	}
+
+	prog, err := Parse(string(source))
+	if perr, ok := err.(*Errors); ok {
+		for _, d := range perr.Diagnostics {
+			d.File = file
 		}
+		syntax.Diagnostics = append(syntax.Diagnostics, perr.Diagnostics...)
+	}
+
+	// Tag every declaration with its source file.
+	tagFile(prog, file)
+	return prog, nil
+}
+
+// ParseProject parses the project's files and every file they import,
+// following imports from the imported files too. Import paths are relative
+// to the importing file and must stay under root, the project's directory;
+// an import reaching outside it is an error. A file imported more than
+// once, or also discovered beside app.human, is parsed once. Returns the
+// programs and their files in the order parsed: the given files, then
+// imports. Syntax errors in any of them are returned together as *Errors.
+func ParseProject(root string, files []string) ([]*Program, []string, error) {
+	var programs []*Program
+	var parsed []string
+	seen := map[string]bool{}
+	queue := append([]string(nil), files...)
+	for _, f := range files {
+		seen[cleanPath(f)] = true
+	}
+
+	var syntax Errors
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
 
-		// Tag every declaration with its source file.
-		tagFile(prog, file)
+		prog, err := parseFile(file, &syntax)
+		if err != nil {
+			return nil, nil, err
+		}
 		programs = append(programs, prog)
+		parsed = append(parsed, file)
+
+		for _, imp := range prog.Imports {
+			path := imp.Path
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(filepath.Dir(file), path)
+			}
+			path = filepath.Clean(path)
+			if !within(root, path) {
+				return nil, nil, fmt.Errorf("%s line %d: imported file %s is outside the project directory", file, imp.Line, imp.Path)
+			}
+			if seen[cleanPath(path)] {
+				continue
+			}
+			if _, err := os.Stat(path); err != nil {
+				return nil, nil, fmt.Errorf("%s line %d: imported file %s not found", file, imp.Line, imp.Path)
+			}
+			seen[cleanPath(path)] = true
+			queue = append(queue, path)
+		}
 	}
 
-	return programs, nil
+	if len(syntax.Diagnostics) > 0 {
+		return nil, nil, &syntax
+	}
+	return programs, parsed, nil
+}
+
+// within reports whether path is root or a file under it.
+func within(root, path string) bool {
+	rel, err := filepath.Rel(cleanPath(root), cleanPath(path))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// cleanPath returns the absolute form of path, so one file reached by two
+// paths is recognized.
+func cleanPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
 }
 
 // tagFile sets the File field on all declarations in a program.
@@ -125,6 +215,9 @@ func tagFile(prog *Program, file string) {
 	for _, d := range prog.Workflows {
 		d.File = file
 	}
+	for _, d := range prog.ModelHooks {
+		d.File = file
+	}
 	if prog.Theme != nil {
 		prog.Theme.File = file
 	}
@@ -149,11 +242,23 @@ func tagFile(prog *Program, file string) {
 	if prog.Architecture != nil {
 		prog.Architecture.File = file
 	}
+	if prog.Accounts != nil {
+		prog.Accounts.File = file
+	}
+	if prog.Plans != nil {
+		prog.Plans.File = file
+	}
+	for _, d := range prog.Seeds {
+		d.File = file
+	}
+	for _, d := range prog.Imports {
+		d.File = file
+	}
 }
 
 // MergePrograms combines multiple parsed programs into a single program.
-// Singleton declarations (App, Theme, Authentication, Database, Build, Architecture)
-// use the first non-nil value; duplicates produce an error with both filenames.
+// Singleton declarations (App, Theme, Authentication, Database, Build, Architecture,
+// Accounts, Plans) use the first non-nil value; duplicates produce an error with both filenames.
 // Slice declarations are appended in file order.
 func MergePrograms(programs []*Program) (*Program, error) {
 	if len(programs) == 0 {
@@ -220,6 +325,24 @@ func MergePrograms(programs []*Program) (*Program, error) {
 			merged.Architecture = prog.Architecture
 		}
 
+		// Singleton: Accounts
+		if prog.Accounts != nil {
+			if merged.Accounts != nil {
+				return nil, fmt.Errorf("duplicate accounts declaration: %s (line %d) and %s (line %d)",
+					merged.Accounts.File, merged.Accounts.Line, prog.Accounts.File, prog.Accounts.Line)
+			}
+			merged.Accounts = prog.Accounts
+		}
+
+		// Singleton: Plans
+		if prog.Plans != nil {
+			if merged.Plans != nil {
+				return nil, fmt.Errorf("duplicate plans declaration: %s (line %d) and %s (line %d)",
+					merged.Plans.File, merged.Plans.Line, prog.Plans.File, prog.Plans.Line)
+			}
+			merged.Plans = prog.Plans
+		}
+
 		// Slices: append in file order
 		merged.Data = append(merged.Data, prog.Data...)
 		merged.Pages = append(merged.Pages, prog.Pages...)
@@ -227,9 +350,12 @@ func MergePrograms(programs []*Program) (*Program, error) {
 		merged.APIs = append(merged.APIs, prog.APIs...)
 		merged.Policies = append(merged.Policies, prog.Policies...)
 		merged.Workflows = append(merged.Workflows, prog.Workflows...)
+		merged.ModelHooks = append(merged.ModelHooks, prog.ModelHooks...)
 		merged.Integrations = append(merged.Integrations, prog.Integrations...)
 		merged.Environments = append(merged.Environments, prog.Environments...)
 		merged.ErrorHandlers = append(merged.ErrorHandlers, prog.ErrorHandlers...)
+		merged.Seeds = append(merged.Seeds, prog.Seeds...)
+		merged.Imports = append(merged.Imports, prog.Imports...)
 
 		// Sections and Statements: append in order
 		merged.Sections = append(merged.Sections, prog.Sections...)
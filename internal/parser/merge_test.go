@@ -173,6 +173,90 @@ func TestParseFiles_ParseErrorIncludesFilename(t *testing.T) {
 	}
 }
 
+func TestParseFiles_ReportsErrorsInEveryFile(t *testing.T) {
+	dir := setupTestDir(t, map[string]string{
+		"app.human":    "app MyApp is a web application\n\ndata User\n  has a name\n",
+		"pages.human":  "page Home:\n  show a heading \"Hi\n",
+		"models.human": "data Task:\n  has a title\n",
+	})
+	files, _ := DiscoverFiles(dir)
+	_, err := ParseFiles(files)
+	syntax, ok := err.(*Errors)
+	if !ok {
+		t.Fatalf("expected *Errors, got %v", err)
+	}
+	if len(syntax.Diagnostics) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(syntax.Diagnostics), err)
+	}
+	if d := syntax.Diagnostics[0]; filepath.Base(d.File) != "app.human" || d.Line != 3 || d.Code != "E001" {
+		t.Errorf("first error: got %s", d.Format())
+	}
+	if d := syntax.Diagnostics[1]; filepath.Base(d.File) != "pages.human" || d.Line != 2 || d.Code != "E002" {
+		t.Errorf("second error: got %s", d.Format())
+	}
+}
+
+// ── ParseProject ──
+
+func TestParseProject_FollowsImports(t *testing.T) {
+	dir := setupTestDir(t, map[string]string{
+		"app.human":   "app MyApp is a web application\nimport \"models/data.human\"\ninclude pages.human\n",
+		"pages.human": "page Home:\n  show a greeting\n",
+	})
+	if err := os.Mkdir(filepath.Join(dir, "models"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := "data User:\n  has a name which is text\nimport \"../pages.human\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "models", "data.human"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	programs, files, err := ParseProject(dir, []string{filepath.Join(dir, "app.human")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// pages.human is imported twice but parsed once
+	if len(programs) != 3 || len(files) != 3 {
+		t.Fatalf("expected 3 files, got %v", files)
+	}
+	merged, err := MergePrograms(programs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged.Data) != 1 || len(merged.Pages) != 1 {
+		t.Errorf("expected 1 data model and 1 page, got %d and %d", len(merged.Data), len(merged.Pages))
+	}
+	if merged.Data[0].File != filepath.Join(dir, "models", "data.human") {
+		t.Errorf("expected data File in models/, got %s", merged.Data[0].File)
+	}
+}
+
+func TestParseProject_MissingImport(t *testing.T) {
+	dir := setupTestDir(t, map[string]string{
+		"app.human": "app MyApp is a web application\ninclude missing.human\n",
+	})
+	_, _, err := ParseProject(dir, []string{filepath.Join(dir, "app.human")})
+	if err == nil {
+		t.Fatal("expected an error for a missing import")
+	}
+	if !contains(err.Error(), "missing.human") || !contains(err.Error(), "line 2") {
+		t.Errorf("expected the file and line in the error, got: %s", err.Error())
+	}
+}
+
+func TestParseProject_ImportOutsideRoot(t *testing.T) {
+	outside := setupTestDir(t, map[string]string{"secret.human": "data Secret:\n  has a key which is text\n"})
+	for _, imp := range []string{filepath.Join(outside, "secret.human"), "../secret.human"} {
+		dir := setupTestDir(t, map[string]string{
+			"app.human": "app MyApp is a web application\nimport \"" + imp + "\"\n",
+		})
+		_, _, err := ParseProject(dir, []string{filepath.Join(dir, "app.human")})
+		if err == nil || !contains(err.Error(), "outside the project directory") {
+			t.Errorf("import %q: expected an outside the project error, got %v", imp, err)
+		}
+	}
+}
+
 // ── MergePrograms ──
 
 func TestMergePrograms_SinglePassthrough(t *testing.T) {
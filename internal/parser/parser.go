@@ -2,8 +2,10 @@ package parser
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	cerr "github.com/barun-bash/human/internal/errors"
 	"github.com/barun-bash/human/internal/lexer"
 )
 
@@ -12,19 +14,29 @@ import (
 // program may still contain successfully parsed declarations.
 func Parse(source string) (*Program, error) {
 	lex := lexer.New(source)
-	tokens, err := lex.Tokenize()
-	if err != nil {
-		return nil, fmt.Errorf("lexer error: %w", err)
+	tokens, _ := lex.Tokenize()
+	p := &parser{tokens: tokens, lines: strings.Split(source, "\n")}
+	for _, e := range lex.Errors() {
+		p.errors = append(p.errors, &cerr.CompilerError{
+			Code: "E002", Message: e.Message, Severity: cerr.SeverityError, Line: e.Line, Column: e.Column,
+		})
 	}
-	return ParseTokens(tokens)
+	return p.finish()
 }
 
 // ParseTokens parses a pre-built token stream into an AST.
 func ParseTokens(tokens []lexer.Token) (*Program, error) {
 	p := &parser{tokens: tokens}
+	return p.finish()
+}
+
+// finish parses the token stream, returning every syntax error found as
+// *Errors.
+func (p *parser) finish() (*Program, error) {
 	prog := p.parse()
 	if len(p.errors) > 0 {
-		return prog, fmt.Errorf("parse errors:\n  %s", strings.Join(p.errors, "\n  "))
+		sortDiagnostics(p.errors)
+		return prog, &Errors{Diagnostics: p.errors}
 	}
 	return prog, nil
 }
@@ -32,8 +44,9 @@ func ParseTokens(tokens []lexer.Token) (*Program, error) {
 // parser holds the state for a single parse run.
 type parser struct {
 	tokens []lexer.Token
+	lines  []string // the source's lines, when parsing source rather than tokens
 	pos    int
-	errors []string
+	errors []*cerr.CompilerError
 }
 
 // ── Public parse entry point ──
@@ -85,6 +98,11 @@ func (p *parser) parse() *Program {
 				prog.Workflows = append(prog.Workflows, decl)
 			}
 
+		case lexer.TOKEN_BEFORE, lexer.TOKEN_AFTER:
+			if decl := p.parseModelHook(); decl != nil {
+				prog.ModelHooks = append(prog.ModelHooks, decl)
+			}
+
 		case lexer.TOKEN_THEME:
 			if decl := p.parseThemeDeclaration(); decl != nil {
 				prog.Theme = decl
@@ -134,6 +152,24 @@ func (p *parser) parse() *Program {
 			}
 
 		default:
+			if p.isAccountsBlock() {
+				prog.Accounts = p.parseAccountsDeclaration()
+				break
+			}
+			if p.isPlansBlock() {
+				prog.Plans = p.parsePlansDeclaration()
+				break
+			}
+			if p.isSeedStatement() {
+				prog.Seeds = append(prog.Seeds, p.parseSeedDeclarations()...)
+				break
+			}
+			if p.isImportStatement() {
+				if decl := p.parseImportDeclaration(); decl != nil {
+					prog.Imports = append(prog.Imports, decl)
+				}
+				break
+			}
 			// Top-level statement (source control, repository, track, alert, etc.)
 			stmt := p.parseTopLevelStatement()
 			if stmt != nil {
@@ -184,8 +220,7 @@ func (p *parser) parseDataDeclaration() *DataDeclaration {
 	decl := &DataDeclaration{Name: name, Line: line}
 
 	if !p.match(lexer.TOKEN_COLON) {
-		p.addError(fmt.Sprintf("line %d: expected ':' after data %s", line, name))
-		p.synchronize()
+		p.missingColon("data", name, line)
 		return decl
 	}
 	p.skipNewlines()
@@ -207,7 +242,11 @@ func (p *parser) parseDataDeclaration() *DataDeclaration {
 		case lexer.TOKEN_BELONGS:
 			p.parseDataBelongs(decl)
 		default:
-			p.skipRestOfLine()
+			// protect against conflicting edits
+			text := strings.ToLower(p.collectRestOfLine())
+			if strings.HasPrefix(text, "protect against conflicting ") {
+				decl.Versioned = true
+			}
 		}
 		if p.pos == startPos {
 			p.advance()
@@ -257,8 +296,8 @@ func (p *parser) parseDataHas(decl *DataDeclaration) {
 	if p.match(lexer.TOKEN_WHICH) {
 		p.match(lexer.TOKEN_IS) // consume IS
 
-		// Check for modifiers: unique, encrypted
-		for p.check(lexer.TOKEN_UNIQUE) || p.check(lexer.TOKEN_ENCRYPTED) {
+		// Check for modifiers: unique, encrypted, personal
+		for p.check(lexer.TOKEN_UNIQUE) || p.check(lexer.TOKEN_ENCRYPTED) || p.isPersonalModifier() {
 			field.Modifiers = append(field.Modifiers, strings.ToLower(p.advance().Literal))
 		}
 
@@ -325,6 +364,10 @@ func (p *parser) parsePageDeclaration() *PageDeclaration {
 
 	name := p.advanceLiteral()
 	decl := &PageDeclaration{Name: name, Line: line}
+	if !p.check(lexer.TOKEN_COLON) {
+		p.missingColon("page", name, line)
+		return decl
+	}
 	decl.Statements = p.parseIndentedBody()
 	return decl
 }
@@ -338,8 +381,7 @@ func (p *parser) parseComponentDeclaration() *ComponentDeclaration {
 	decl := &ComponentDeclaration{Name: name, Line: line}
 
 	if !p.match(lexer.TOKEN_COLON) {
-		p.addError(fmt.Sprintf("line %d: expected ':' after component %s", line, name))
-		p.synchronize()
+		p.missingColon("component", name, line)
 		return decl
 	}
 	p.skipNewlines()
@@ -380,10 +422,16 @@ func (p *parser) parseAPIDeclaration() *APIDeclaration {
 	decl := &APIDeclaration{Name: name, Line: line}
 
 	if !p.match(lexer.TOKEN_COLON) {
-		p.addError(fmt.Sprintf("line %d: expected ':' after api %s", line, name))
-		p.synchronize()
+		p.missingColon("api", name, line)
 		return decl
 	}
+	// A one-step API may follow the colon on the same line:
+	//   api MonthlyRevenue: sum the amount of Orders grouped by month
+	if !p.check(lexer.TOKEN_NEWLINE) {
+		if stmt := p.parseBodyStatement(); stmt != nil {
+			decl.Statements = append(decl.Statements, stmt)
+		}
+	}
 	p.skipNewlines()
 	if !p.match(lexer.TOKEN_INDENT) {
 		return decl
@@ -437,8 +485,7 @@ func (p *parser) parsePolicyDeclaration() *PolicyDeclaration {
 	decl := &PolicyDeclaration{Name: name, Line: line}
 
 	if !p.match(lexer.TOKEN_COLON) {
-		p.addError(fmt.Sprintf("line %d: expected ':' after policy %s", line, name))
-		p.synchronize()
+		p.missingColon("policy", name, line)
 		return decl
 	}
 	p.skipNewlines()
@@ -493,6 +540,31 @@ func (p *parser) parseWorkflowDeclaration() *WorkflowDeclaration {
 	return decl
 }
 
+// parseModelHook parses: before|after <event>, <step>
+// or: before|after <event>: <body>
+func (p *parser) parseModelHook() *ModelHookDeclaration {
+	line := p.peek().Line
+	timing := strings.ToLower(p.advance().Literal) // consume BEFORE or AFTER
+
+	var parts []string
+	for !p.isAtEnd() &&
+		!p.check(lexer.TOKEN_COMMA) &&
+		!p.check(lexer.TOKEN_COLON) &&
+		!p.check(lexer.TOKEN_NEWLINE) &&
+		!p.check(lexer.TOKEN_EOF) {
+		parts = append(parts, p.advance().Literal)
+	}
+	decl := &ModelHookDeclaration{Timing: timing, Event: strings.Join(parts, " "), Line: line}
+	if p.match(lexer.TOKEN_COMMA) {
+		if stmt := p.parseBodyStatement(); stmt != nil {
+			decl.Statements = append(decl.Statements, stmt)
+		}
+		return decl
+	}
+	decl.Statements = p.parseIndentedBody()
+	return decl
+}
+
 // parseThemeDeclaration parses theme properties.
 func (p *parser) parseThemeDeclaration() *ThemeDeclaration {
 	line := p.peek().Line
@@ -513,6 +585,80 @@ func (p *parser) parseAuthenticationDeclaration() *AuthenticationDeclaration {
 	return decl
 }
 
+// parseAccountsDeclaration parses what users can do with their own account.
+func (p *parser) parseAccountsDeclaration() *AccountsDeclaration {
+	line := p.peek().Line
+	p.advance() // consume "accounts"
+
+	decl := &AccountsDeclaration{Line: line}
+	decl.Statements = p.parseIndentedBody()
+	return decl
+}
+
+// parsePlansDeclaration parses the subscription plans, listed after the
+// colon, one per indented line, or both.
+func (p *parser) parsePlansDeclaration() *PlansDeclaration {
+	line := p.peek().Line
+	p.advance() // consume "plans"
+	p.advance() // consume ":"
+
+	decl := &PlansDeclaration{Line: line}
+	decl.Text = p.collectRestOfLine()
+	decl.Statements = p.parseIndentedLines()
+	return decl
+}
+
+// parseSeedDeclarations parses a "seed" line, or a "seed:" block with a
+// model to seed on each line, e.g. "50 Users".
+func (p *parser) parseSeedDeclarations() []*SeedDeclaration {
+	line := p.peek().Line
+	p.advance() // consume "seed"
+
+	if !p.match(lexer.TOKEN_COLON) {
+		return []*SeedDeclaration{{Text: p.collectRestOfLine(), Line: line}}
+	}
+	var decls []*SeedDeclaration
+	if text := p.collectRestOfLine(); text != "" {
+		decls = append(decls, &SeedDeclaration{Text: text, Line: line})
+	}
+	for _, s := range p.parseIndentedLines() {
+		decls = append(decls, &SeedDeclaration{Text: s.Text, Line: s.Line})
+	}
+	return decls
+}
+
+// parseImportDeclaration parses: import "<path>" or include <name>.human.
+// A path with directories must be quoted, since a bare one doesn't survive
+// the lexer; a bare name gets the .human extension.
+func (p *parser) parseImportDeclaration() *ImportDeclaration {
+	line := p.peek().Line
+	keyword := strings.ToLower(p.advance().Literal) // consume "import" or "include"
+
+	decl := &ImportDeclaration{Line: line}
+	if p.check(lexer.TOKEN_STRING_LIT) {
+		decl.Path = p.advance().Literal
+	} else {
+		var words []string
+		for !p.isAtEnd() && !p.check(lexer.TOKEN_NEWLINE) && !p.check(lexer.TOKEN_DEDENT) {
+			words = append(words, p.advance().Literal)
+		}
+		if len(words) == 2 && strings.EqualFold(words[1], "human") {
+			words = words[:1]
+		}
+		if len(words) != 1 {
+			p.addError("E003", line, fmt.Sprintf("expected a file after %s, e.g. %s \"models/data.human\"", keyword, keyword))
+			return nil
+		}
+		decl.Path = words[0] + ".human"
+	}
+	p.skipRestOfLine()
+	if !strings.HasSuffix(decl.Path, ".human") {
+		p.addError("E003", line, fmt.Sprintf("%s %q: only .human files can be imported", keyword, decl.Path))
+		return nil
+	}
+	return decl
+}
+
 // parseDatabaseDeclaration parses database configuration.
 func (p *parser) parseDatabaseDeclaration() *DatabaseDeclaration {
 	line := p.peek().Line
@@ -536,17 +682,64 @@ func (p *parser) parseIntegrationDeclaration() *IntegrationDeclaration {
 	return decl
 }
 
-// parseEnvironmentDeclaration parses: environment <name>: <body>
+// parseEnvironmentDeclaration parses: environment <name>: [statement] <body>
 func (p *parser) parseEnvironmentDeclaration() *EnvironmentDeclaration {
 	line := p.peek().Line
 	p.advance() // consume ENVIRONMENT
 
 	name := p.advanceLiteral()
 	decl := &EnvironmentDeclaration{Name: name, Line: line}
-	decl.Statements = p.parseIndentedBody()
+
+	// "environment production: url is app.example.com" puts a statement on
+	// the header line, and an indented body may still follow it.
+	if !p.match(lexer.TOKEN_COLON) {
+		p.skipRestOfLine()
+		return decl
+	}
+	if !p.check(lexer.TOKEN_COMMENT) {
+		if stmt := p.parseBodyStatement(); stmt != nil {
+			decl.Statements = append(decl.Statements, stmt)
+		}
+	}
+	decl.Statements = append(decl.Statements, p.parseIndentedLines()...)
+
+	// The lexer drops the dots, slashes, and colons a URL is made of, so
+	// the url line keeps the URL as written.
+	for _, stmt := range decl.Statements {
+		if stmt.Kind != "url" {
+			continue
+		}
+		if raw := p.rawValue(stmt.Line, "url is "); raw != "" {
+			stmt.Text = "url is " + raw
+		}
+	}
 	return decl
 }
 
+// rawValue returns the text after prefix on a source line, as written and
+// without a trailing comment. It returns "" when parsing tokens, when the
+// line has no prefix, or when the text is a string, which its token keeps
+// whole.
+func (p *parser) rawValue(line int, prefix string) string {
+	if line < 1 || line > len(p.lines) {
+		return ""
+	}
+	text := strings.TrimRight(p.lines[line-1], "\r")
+	i := strings.Index(strings.ToLower(text), prefix)
+	if i < 0 {
+		return ""
+	}
+	text = text[i+len(prefix):]
+	if j := strings.Index(text, " #"); j >= 0 {
+		text = text[:j]
+	}
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, `"`) {
+		return ""
+	}
+	return text
+}
+
 // parseBuildDeclaration parses build target configuration.
 func (p *parser) parseBuildDeclaration() *BuildDeclaration {
 	line := p.peek().Line
@@ -636,6 +829,12 @@ func (p *parser) parseIndentedBody() []*Statement {
 		p.skipRestOfLine()
 		return nil
 	}
+	return p.parseIndentedLines()
+}
+
+// parseIndentedLines parses the indented block that follows a block's
+// header line.
+func (p *parser) parseIndentedLines() []*Statement {
 	p.skipNewlines()
 
 	if !p.match(lexer.TOKEN_INDENT) {
@@ -693,11 +892,17 @@ func (p *parser) parseBodyStatement() *Statement {
 	}
 	line := p.peek().Line
 	kind := strings.ToLower(p.peek().Literal)
+	start := p.pos
 	text := p.collectRestOfLine()
 	if text == "" {
 		return nil
 	}
-	return &Statement{Kind: kind, Text: text, Line: line}
+	stmt := &Statement{Kind: kind, Text: text, Line: line}
+	// The text drops the quotes, so keep a lone string whole.
+	if p.pos-start == 2 && p.tokens[start+1].Type == lexer.TOKEN_STRING_LIT {
+		stmt.Quoted = p.tokens[start+1].Literal
+	}
+	return stmt
 }
 
 // parseParamList parses a comma/and-separated list of parameter names.
@@ -743,44 +948,55 @@ func (p *parser) collectParamName() string {
 
 // collectRestOfLine collects all tokens until end-of-line, joining their literals.
 func (p *parser) collectRestOfLine() string {
-	var parts []string
+	var b strings.Builder
+	b.Grow(lineSizeHint)
+	words := 0
 	for !p.isAtEnd() &&
 		!p.check(lexer.TOKEN_NEWLINE) &&
 		!p.check(lexer.TOKEN_DEDENT) &&
 		!p.check(lexer.TOKEN_EOF) {
 		tok := p.advance()
 		// Attach possessive and comma directly to previous word
-		if tok.Type == lexer.TOKEN_POSSESSIVE && len(parts) > 0 {
-			parts[len(parts)-1] += tok.Literal
-		} else if tok.Type == lexer.TOKEN_COMMA && len(parts) > 0 {
-			parts[len(parts)-1] += tok.Literal
-		} else if tok.Type == lexer.TOKEN_COLON && len(parts) > 0 {
-			parts[len(parts)-1] += tok.Literal
-		} else {
-			parts = append(parts, tok.Literal)
-		}
+		attach := tok.Type == lexer.TOKEN_POSSESSIVE || tok.Type == lexer.TOKEN_COMMA || tok.Type == lexer.TOKEN_COLON
+		writeWord(&b, tok.Literal, attach, &words)
 	}
-	return strings.Join(parts, " ")
+	return b.String()
 }
 
 // collectUntilColon collects token literals until a COLON is found.
 // The colon is NOT consumed — callers use parseIndentedBody which expects it.
 func (p *parser) collectUntilColon() string {
-	var parts []string
+	var b strings.Builder
+	b.Grow(lineSizeHint)
+	words := 0
 	for !p.isAtEnd() &&
 		!p.check(lexer.TOKEN_COLON) &&
 		!p.check(lexer.TOKEN_NEWLINE) &&
 		!p.check(lexer.TOKEN_EOF) {
 		tok := p.advance()
-		if tok.Type == lexer.TOKEN_POSSESSIVE && len(parts) > 0 {
-			parts[len(parts)-1] += tok.Literal
-		} else if tok.Type == lexer.TOKEN_COMMA && len(parts) > 0 {
-			parts[len(parts)-1] += tok.Literal
-		} else {
-			parts = append(parts, tok.Literal)
-		}
+		attach := tok.Type == lexer.TOKEN_POSSESSIVE || tok.Type == lexer.TOKEN_COMMA
+		writeWord(&b, tok.Literal, attach, &words)
 	}
-	return strings.Join(parts, " ")
+	return b.String()
+}
+
+// lineSizeHint is the buffer a collected line starts with, enough for
+// most statements.
+const lineSizeHint = 64
+
+// writeWord appends a token's literal to a line being collected, after a
+// space unless it attaches to the word before. Building the line in one
+// buffer keeps long files from allocating a string per word.
+func writeWord(b *strings.Builder, lit string, attach bool, words *int) {
+	if attach && *words > 0 {
+		b.WriteString(lit)
+		return
+	}
+	if *words > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(lit)
+	*words++
 }
 
 // ── Token movement ──
@@ -832,6 +1048,67 @@ func (p *parser) isAtEnd() bool {
 	return p.pos >= len(p.tokens) || p.peek().Type == lexer.TOKEN_EOF
 }
 
+// isPersonalModifier reports whether the current token is the "personal"
+// field modifier. "personal" isn't a keyword, so it only counts when a
+// type or another modifier follows it: "which is personal" alone names a
+// type.
+func (p *parser) isPersonalModifier() bool {
+	if !strings.EqualFold(p.peek().Literal, "personal") {
+		return false
+	}
+	p.pos++
+	defer func() { p.pos-- }()
+	return p.isTypeKeyword() || p.check(lexer.TOKEN_IDENTIFIER) || p.check(lexer.TOKEN_EITHER) ||
+		p.check(lexer.TOKEN_UNIQUE) || p.check(lexer.TOKEN_ENCRYPTED)
+}
+
+// isAccountsBlock reports whether the current token opens an "accounts:"
+// block. "accounts" isn't a keyword, so it only counts at the start of a
+// line and followed by a colon.
+func (p *parser) isAccountsBlock() bool {
+	if !strings.EqualFold(p.peek().Literal, "accounts") {
+		return false
+	}
+	p.pos++
+	defer func() { p.pos-- }()
+	return p.check(lexer.TOKEN_COLON)
+}
+
+// isPlansBlock reports whether the current token opens a "plans:" block.
+// Like "accounts", "plans" only counts at the start of a line and followed
+// by a colon.
+func (p *parser) isPlansBlock() bool {
+	if !strings.EqualFold(p.peek().Literal, "plans") {
+		return false
+	}
+	p.pos++
+	defer func() { p.pos-- }()
+	return p.check(lexer.TOKEN_COLON)
+}
+
+// isSeedStatement reports whether the current line seeds sample data:
+// "seed" at the start of a line, followed by a colon or a count.
+func (p *parser) isSeedStatement() bool {
+	if !strings.EqualFold(p.peek().Literal, "seed") {
+		return false
+	}
+	p.pos++
+	defer func() { p.pos-- }()
+	return p.check(lexer.TOKEN_COLON) || p.check(lexer.TOKEN_NUMBER_LIT)
+}
+
+// isImportStatement reports whether the current line imports another file:
+// "import" or "include" at the start of a line, followed by the file.
+func (p *parser) isImportStatement() bool {
+	lit := strings.ToLower(p.peek().Literal)
+	if lit != "import" && lit != "include" {
+		return false
+	}
+	p.pos++
+	defer func() { p.pos-- }()
+	return !p.isAtEnd() && !p.check(lexer.TOKEN_NEWLINE) && !p.check(lexer.TOKEN_COLON)
+}
+
 // isTypeKeyword returns true if the current token is a type keyword.
 func (p *parser) isTypeKeyword() bool {
 	switch p.peek().Type {
@@ -877,28 +1154,69 @@ func (p *parser) skipRestOfLine() {
 
 // ── Error handling ──
 
-func (p *parser) addError(msg string) {
-	p.errors = append(p.errors, msg)
+// addError records a syntax error on a line.
+func (p *parser) addError(code string, line int, msg string) {
+	p.errors = append(p.errors, &cerr.CompilerError{
+		Code: code, Message: msg, Severity: cerr.SeverityError, Line: line,
+	})
 }
 
-// synchronize skips tokens until the next top-level declaration start.
+// missingColon records a block header missing its colon, and skips the
+// block.
+func (p *parser) missingColon(keyword, name string, line int) {
+	p.errors = append(p.errors, &cerr.CompilerError{
+		Code:       "E001",
+		Message:    fmt.Sprintf("expected ':' after %s %s", keyword, name),
+		Severity:   cerr.SeverityError,
+		Line:       line,
+		Suggestion: fmt.Sprintf("%s %s:", keyword, name),
+	})
+	p.synchronize()
+}
+
+// synchronize skips the rest of a malformed block: its header line and
+// any lines indented under it, stopping at the next line that starts at
+// the left margin, where the next block begins.
 func (p *parser) synchronize() {
+	depth := 0
+	lineStart := false
 	for !p.isAtEnd() {
-		// If we hit a newline, check next token
-		if p.peek().Type == lexer.TOKEN_NEWLINE {
-			p.advance()
-		}
 		switch p.peek().Type {
-		case lexer.TOKEN_APP, lexer.TOKEN_DATA, lexer.TOKEN_PAGE,
-			lexer.TOKEN_COMPONENT, lexer.TOKEN_API, lexer.TOKEN_POLICY,
-			lexer.TOKEN_WHEN, lexer.TOKEN_THEME, lexer.TOKEN_AUTHENTICATION,
-			lexer.TOKEN_DATABASE, lexer.TOKEN_INTEGRATE, lexer.TOKEN_ENVIRONMENT,
-			lexer.TOKEN_BUILD, lexer.TOKEN_IF, lexer.TOKEN_SOURCE,
-			lexer.TOKEN_REPOSITORY, lexer.TOKEN_BRANCHES,
-			lexer.TOKEN_SECTION_HEADER, lexer.TOKEN_EOF:
-			return
+		case lexer.TOKEN_INDENT:
+			depth++
+			lineStart = true
+		case lexer.TOKEN_DEDENT:
+			depth--
+			lineStart = true
+		case lexer.TOKEN_NEWLINE:
+			lineStart = true
+		case lexer.TOKEN_COMMENT:
 		default:
-			p.advance()
+			if lineStart && depth <= 0 {
+				return
+			}
+			lineStart = false
 		}
+		p.advance()
+	}
+}
+
+// Errors is every syntax error a parse found, as compiler diagnostics in
+// source order.
+type Errors struct {
+	Diagnostics []*cerr.CompilerError
+}
+
+func (e *Errors) Error() string {
+	lines := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		lines[i] = d.Format()
 	}
+	return "parse errors:\n  " + strings.Join(lines, "\n  ")
+}
+
+// sortDiagnostics orders a file's diagnostics by line: the lexer's come
+// first, however far into the file they are.
+func sortDiagnostics(diags []*cerr.CompilerError) {
+	sort.SliceStable(diags, func(i, j int) bool { return diags[i].Line < diags[j].Line })
 }
@@ -1,10 +1,13 @@
 package parser_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/barun-bash/human/internal/analyzer"
 	"github.com/barun-bash/human/internal/ir"
 	"github.com/barun-bash/human/internal/lexer"
 	"github.com/barun-bash/human/internal/parser"
@@ -59,3 +62,73 @@ func BenchmarkIRBuild(b *testing.B) {
 		}
 	}
 }
+
+// largeSource returns a spec of about 5,000 lines: the ecommerce example
+// with its blocks repeated under new names, the size at which check
+// latency shows in watch mode.
+func largeSource(b *testing.B) string {
+	b.Helper()
+	source := loadSource(b, "ecommerce")
+	body := source[strings.Index(source, "\ndata "):]
+	if i := strings.Index(body, "\nbuild with"); i > 0 {
+		body = body[:i]
+	}
+	var s strings.Builder
+	s.WriteString(source)
+	for i := 0; s.Len() < 12*len(source); i++ {
+		prefix := fmt.Sprintf("X%d", i)
+		s.WriteString(strings.NewReplacer(
+			"data ", "data "+prefix,
+			"page ", "page "+prefix,
+			"api ", "api "+prefix,
+			"component ", "component "+prefix,
+		).Replace(body))
+	}
+	return s.String()
+}
+
+func BenchmarkLexLarge(b *testing.B) {
+	source := largeSource(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		lex := lexer.New(source)
+		if _, err := lex.Tokenize(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseLarge(b *testing.B) {
+	source := largeSource(b)
+	tokens, err := lexer.New(source).Tokenize()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := parser.ParseTokens(tokens); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCheckLarge measures what human check and each watch-mode
+// rebuild start with: parsing, building the IR, and analyzing it.
+func BenchmarkCheckLarge(b *testing.B) {
+	source := largeSource(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		prog, err := parser.Parse(source)
+		if err != nil {
+			b.Fatal(err)
+		}
+		app, err := ir.Build(prog)
+		if err != nil {
+			b.Fatal(err)
+		}
+		analyzer.Analyze(app, "app.human")
+	}
+}
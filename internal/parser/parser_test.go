@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -94,6 +95,29 @@ func TestParseDataSimple(t *testing.T) {
 	}
 }
 
+func TestParseDataPersonal(t *testing.T) {
+	source := `data Patient:
+  has an email which is unique personal email
+  has a phone which is personal text
+  has a kind which is personal`
+	prog := mustParse(t, source)
+
+	fields := prog.Data[0].Fields
+	if len(fields) != 3 {
+		t.Fatalf("expected 3 fields, got %d", len(fields))
+	}
+	if f := fields[0]; f.Type != "email" || !hasModifier(f, "unique") || !hasModifier(f, "personal") {
+		t.Errorf("email: got type %q, modifiers %v", f.Type, f.Modifiers)
+	}
+	if f := fields[1]; f.Type != "text" || !hasModifier(f, "personal") {
+		t.Errorf("phone: got type %q, modifiers %v", f.Type, f.Modifiers)
+	}
+	// Without a type after it, "personal" is the type.
+	if f := fields[2]; f.Type != "personal" || len(f.Modifiers) != 0 {
+		t.Errorf("kind: got type %q, modifiers %v", f.Type, f.Modifiers)
+	}
+}
+
 func TestParseDataEnum(t *testing.T) {
 	source := `data Task:
   has a status which is either "todo" or "in_progress" or "done"
@@ -134,6 +158,26 @@ func TestParseDataOptional(t *testing.T) {
 	}
 }
 
+func TestParseDataVersioned(t *testing.T) {
+	source := `data Post:
+  has a title which is text
+  protect against conflicting edits
+
+data Comment:
+  has a body which is text`
+	prog := mustParse(t, source)
+
+	if !prog.Data[0].Versioned {
+		t.Error("expected Post to be protected against conflicting edits")
+	}
+	if len(prog.Data[0].Fields) != 1 {
+		t.Errorf("expected 1 field, got %d", len(prog.Data[0].Fields))
+	}
+	if prog.Data[1].Versioned {
+		t.Error("Comment should not be versioned")
+	}
+}
+
 func TestParseDataShorthand(t *testing.T) {
 	source := `data User:
   has a created datetime`
@@ -299,6 +343,24 @@ func TestParseAPIDeclaration(t *testing.T) {
 	}
 }
 
+func TestParseAPIInlineStatement(t *testing.T) {
+	source := `api MonthlyRevenue: sum the amount of Orders grouped by month
+api OrdersByStatus:
+  requires authentication
+  count the Orders grouped by status`
+	prog := mustParse(t, source)
+
+	if len(prog.APIs) != 2 {
+		t.Fatalf("expected 2 APIs, got %d", len(prog.APIs))
+	}
+	if got := prog.APIs[0].Statements; len(got) != 1 || got[0].Text != "sum the amount of Orders grouped by month" {
+		t.Errorf("expected the step after the colon, got %+v", got)
+	}
+	if api := prog.APIs[1]; !api.Auth || len(api.Statements) != 1 {
+		t.Errorf("expected an authenticated API with 1 step, got %+v", api)
+	}
+}
+
 func TestParseAPINoAuth(t *testing.T) {
 	source := `api SignUp:
   accepts name, email, and password
@@ -482,6 +544,111 @@ func TestParseAuthenticationDeclaration(t *testing.T) {
 	}
 }
 
+// ── Accounts Declaration ──
+
+func TestParseAccountsDeclaration(t *testing.T) {
+	source := `accounts:
+  users can update their profile, change password, and delete their account
+
+track accounts created per day`
+	prog := mustParse(t, source)
+
+	if prog.Accounts == nil {
+		t.Fatal("expected Accounts declaration")
+	}
+	if len(prog.Accounts.Statements) != 1 {
+		t.Fatalf("expected 1 accounts statement, got %d", len(prog.Accounts.Statements))
+	}
+	if got := prog.Accounts.Statements[0].Text; !strings.Contains(got, "delete their account") {
+		t.Errorf("expected the accounts statement, got %q", got)
+	}
+	// "accounts" without a colon is just a word
+	if len(prog.Statements) != 1 {
+		t.Errorf("expected 1 top-level statement, got %d", len(prog.Statements))
+	}
+}
+
+// ── Plans Declaration ──
+
+func TestParsePlansDeclaration(t *testing.T) {
+	source := `plans: Free, Pro ($19/month)
+  Enterprise
+
+track plans bought per day`
+	prog := mustParse(t, source)
+
+	if prog.Plans == nil {
+		t.Fatal("expected Plans declaration")
+	}
+	if got := prog.Plans.Text; !strings.HasPrefix(got, "Free, Pro 19") {
+		t.Errorf("expected the plans after the colon, got %q", got)
+	}
+	if len(prog.Plans.Statements) != 1 || prog.Plans.Statements[0].Text != "Enterprise" {
+		t.Errorf("expected the plan on its own line, got %v", prog.Plans.Statements)
+	}
+	// "plans" without a colon is just a word
+	if len(prog.Statements) != 1 {
+		t.Errorf("expected 1 top-level statement, got %d", len(prog.Statements))
+	}
+}
+
+func TestParseSeedDeclarations(t *testing.T) {
+	source := `seed 50 Users with realistic names and emails
+
+seed: 20 Projects
+  100 Tasks
+
+seed the garden`
+	prog := mustParse(t, source)
+
+	want := []string{"50 Users with realistic names and emails", "20 Projects", "100 Tasks"}
+	if len(prog.Seeds) != len(want) {
+		t.Fatalf("expected %d seed lines, got %d", len(want), len(prog.Seeds))
+	}
+	for i, w := range want {
+		if prog.Seeds[i].Text != w {
+			t.Errorf("seed %d: expected %q, got %q", i, w, prog.Seeds[i].Text)
+		}
+	}
+	// "seed" without a count or colon is just a word
+	if len(prog.Statements) != 1 {
+		t.Errorf("expected 1 top-level statement, got %d", len(prog.Statements))
+	}
+}
+
+// ── Imports ──
+
+func TestParseImportStatements(t *testing.T) {
+	source := `app TaskFlow is a web application
+import "models/data.human"
+include pages.human`
+	prog := mustParse(t, source)
+
+	if len(prog.Imports) != 2 {
+		t.Fatalf("expected 2 imports, got %d", len(prog.Imports))
+	}
+	if prog.Imports[0].Path != "models/data.human" {
+		t.Errorf("expected quoted path, got %q", prog.Imports[0].Path)
+	}
+	if prog.Imports[1].Path != "pages.human" {
+		t.Errorf("expected bare file name, got %q", prog.Imports[1].Path)
+	}
+	if prog.Imports[1].Line != 3 {
+		t.Errorf("expected line 3, got %d", prog.Imports[1].Line)
+	}
+}
+
+func TestParseImportErrors(t *testing.T) {
+	if _, err := Parse(`import "styles.css"`); err == nil || !strings.Contains(err.Error(), ".human") {
+		t.Errorf("expected an error for a non-.human import, got %v", err)
+	}
+	// "include" followed by a colon is not an import
+	prog := mustParse(t, "include:\n  the footer")
+	if len(prog.Imports) != 0 {
+		t.Errorf("expected no imports, got %d", len(prog.Imports))
+	}
+}
+
 // ── Database Declaration ──
 
 func TestParseDatabaseDeclaration(t *testing.T) {
@@ -552,6 +719,25 @@ func TestParseEnvironmentDeclaration(t *testing.T) {
 	}
 }
 
+func TestParseEnvironmentInlineStatement(t *testing.T) {
+	source := `environment production: url is app.example.com
+  requires manual approval for deployment
+
+environment staging: url is staging.example.com`
+	prog := mustParse(t, source)
+
+	if len(prog.Environments) != 2 {
+		t.Fatalf("expected 2 environments, got %d", len(prog.Environments))
+	}
+	prod := prog.Environments[0]
+	if len(prod.Statements) != 2 || prod.Statements[0].Text != "url is app.example.com" {
+		t.Fatalf("expected the header statement and the body, got %+v", prod.Statements)
+	}
+	if staging := prog.Environments[1]; len(staging.Statements) != 1 || staging.Statements[0].Kind != "url" {
+		t.Errorf("expected a single url statement, got %+v", staging.Statements)
+	}
+}
+
 // ── Error Handler ──
 
 func TestParseErrorHandler(t *testing.T) {
@@ -641,6 +827,31 @@ policy FreeUser:
 	}
 }
 
+// ── Model Hooks ──
+
+func TestParseModelHooks(t *testing.T) {
+	source := `before a Task is created, set its status to "pending"
+after a User is deleted:
+  remove their Sessions
+  log the deletion`
+	prog := mustParse(t, source)
+
+	if len(prog.ModelHooks) != 2 {
+		t.Fatalf("expected 2 model hooks, got %d", len(prog.ModelHooks))
+	}
+	h := prog.ModelHooks[0]
+	if h.Timing != "before" || h.Event != "a Task is created" {
+		t.Errorf("expected before 'a Task is created', got %s %q", h.Timing, h.Event)
+	}
+	if len(h.Statements) != 1 || h.Statements[0].Text != "set its status to pending" {
+		t.Errorf("expected the step after the comma, got %+v", h.Statements)
+	}
+	h = prog.ModelHooks[1]
+	if h.Timing != "after" || h.Event != "a User is deleted" || len(h.Statements) != 2 {
+		t.Errorf("expected an after hook with 2 steps, got %s %q %d", h.Timing, h.Event, len(h.Statements))
+	}
+}
+
 // ── Top-Level Statements ──
 
 func TestParseTopLevelStatements(t *testing.T) {
@@ -904,3 +1115,47 @@ func searchSubstring(s, sub string) bool {
 	}
 	return false
 }
+
+func TestParseRecoversAtNextBlock(t *testing.T) {
+	source := `app Demo is a web application
+
+data User
+  has a name which is text
+  if the name is empty, show an error
+
+page Home
+  show a heading "Hi"
+
+component Card:
+  show a title "Oops
+
+api ListUsers:
+  fetch all users
+`
+	prog, err := Parse(source)
+	syntax, ok := err.(*Errors)
+	if !ok {
+		t.Fatalf("expected *Errors, got %v", err)
+	}
+	var got []string
+	for _, d := range syntax.Diagnostics {
+		got = append(got, d.Format())
+	}
+	want := []string{
+		"line 3: expected ':' after data User [E001]",
+		"line 7: expected ':' after page Home [E001]",
+		"line 11: unterminated string: add the closing \" [E002]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if syntax.Diagnostics[0].Suggestion != "data User:" {
+		t.Errorf("suggestion: got %q", syntax.Diagnostics[0].Suggestion)
+	}
+	if len(prog.ErrorHandlers) != 0 || len(prog.Statements) != 0 {
+		t.Errorf("the malformed blocks' bodies should be skipped, got %d handlers and %d statements", len(prog.ErrorHandlers), len(prog.Statements))
+	}
+	if len(prog.Components) != 1 || len(prog.APIs) != 1 || prog.APIs[0].Name != "ListUsers" {
+		t.Errorf("the blocks after the errors should still parse: %d components, %d APIs", len(prog.Components), len(prog.APIs))
+	}
+}
@@ -12,6 +12,12 @@ import (
 	"github.com/barun-bash/human/internal/ir"
 )
 
+// Configurable is implemented by external generators, which take their
+// settings from the project config.
+type Configurable interface {
+	SetSettings(settings map[string]string)
+}
+
 // ExternalGenerator adapts an external plugin binary to the CodeGenerator
 // interface. It communicates with the plugin via CLI subcommands and JSON.
 type ExternalGenerator struct {
@@ -0,0 +1,220 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/version"
+)
+
+// PathPrefix names the generator binaries discovered on PATH: human-gen-rails
+// is the "rails" generator.
+const PathPrefix = "human-gen-"
+
+// ProtocolVersion is the version of the JSON protocol PATH generators speak.
+const ProtocolVersion = 1
+
+// GenerateRequest is what a PATH generator reads from stdin, as JSON.
+type GenerateRequest struct {
+	Protocol        int               `json:"protocol"`
+	CompilerVersion string            `json:"compiler_version"`
+	Generator       string            `json:"generator"`
+	Settings        map[string]string `json:"settings,omitempty"`
+	App             *ir.Application   `json:"app"`
+}
+
+// GenerateResponse is what a PATH generator writes to stdout, as JSON: the
+// files to write, or an error.
+type GenerateResponse struct {
+	Files []GeneratedFile `json:"files"`
+	Error string          `json:"error,omitempty"`
+}
+
+// GeneratedFile is a file in a GenerateResponse. Path is relative to the
+// generator's output directory, with forward slashes.
+type GeneratedFile struct {
+	Path       string `json:"path"`
+	Content    string `json:"content"`
+	Executable bool   `json:"executable,omitempty"`
+}
+
+// PathGenerator adapts a human-gen-<name> binary on PATH to the
+// CodeGenerator interface. Like a protoc plugin, the binary reads a
+// GenerateRequest on stdin and writes a GenerateResponse on stdout; the
+// compiler writes the files, into the output's <name> directory.
+type PathGenerator struct {
+	name     string
+	binary   string
+	settings map[string]string
+}
+
+// NewPathGenerator creates a PathGenerator for the binary at path, named
+// after it.
+func NewPathGenerator(path string) *PathGenerator {
+	name := strings.ToLower(strings.TrimPrefix(filepath.Base(path), PathPrefix))
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, ".exe")
+	}
+	return &PathGenerator{name: name, binary: path}
+}
+
+// DiscoverPath returns a generator for each human-gen-<name> binary on
+// PATH, sorted by name. A name found in several PATH directories is the
+// first one's, as the shell would run it.
+func DiscoverPath() []codegen.CodeGenerator {
+	seen := map[string]bool{}
+	var found []*PathGenerator
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), PathPrefix) || !executable(filepath.Join(dir, entry.Name())) {
+				continue
+			}
+			g := NewPathGenerator(filepath.Join(dir, entry.Name()))
+			if g.name == "" || seen[g.name] {
+				continue
+			}
+			seen[g.name] = true
+			found = append(found, g)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].name < found[j].name })
+
+	generators := make([]codegen.CodeGenerator, len(found))
+	for i, g := range found {
+		generators[i] = g
+	}
+	return generators
+}
+
+// executable reports whether path is a file the current user can run.
+func executable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(path), ".exe")
+	}
+	return info.Mode()&0111 != 0
+}
+
+// Meta returns the generator's metadata. PATH generators don't describe
+// themselves; their binary is their description.
+func (g *PathGenerator) Meta() codegen.PluginMeta {
+	return codegen.PluginMeta{
+		Name:        g.name,
+		Description: filepath.Base(g.binary),
+	}
+}
+
+// Enabled reports whether the app's build config names the generator, as
+// "backend using Rails" names human-gen-rails. A project's config can
+// also enable it.
+func (g *PathGenerator) Enabled(app *ir.Application) bool {
+	if app.Config == nil {
+		return false
+	}
+	for _, target := range []string{app.Config.Frontend, app.Config.Backend, app.Config.Database, app.Config.Deploy} {
+		for _, word := range strings.FieldsFunc(strings.ToLower(target), func(r rune) bool {
+			return r == ' ' || r == ',' || r == '/'
+		}) {
+			if word == g.name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StageName returns the display name for progress reporting.
+func (g *PathGenerator) StageName() string {
+	return fmt.Sprintf("Running generator: %s", g.name)
+}
+
+// OutputDir returns the subdirectory name for this generator's output.
+func (g *PathGenerator) OutputDir() string {
+	return g.name
+}
+
+// SetSettings injects configuration settings from the project config.
+func (g *PathGenerator) SetSettings(settings map[string]string) {
+	g.settings = settings
+}
+
+// BinaryPath returns the path to the generator binary.
+func (g *PathGenerator) BinaryPath() string {
+	return g.binary
+}
+
+// Generate sends the IR to the binary and writes the files it responds
+// with under outputDir.
+func (g *PathGenerator) Generate(app *ir.Application, outputDir string) error {
+	req, err := json.Marshal(GenerateRequest{
+		Protocol:        ProtocolVersion,
+		CompilerVersion: strings.TrimPrefix(version.Version, "v"),
+		Generator:       g.name,
+		Settings:        g.settings,
+		App:             app,
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling IR for generator %s: %w", g.name, err)
+	}
+
+	cmd := exec.Command(g.binary)
+	cmd.Stdin = bytes.NewReader(req)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg == "" {
+			errMsg = err.Error()
+		}
+		return fmt.Errorf("generator %s failed: %s", g.name, errMsg)
+	}
+
+	var resp GenerateResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return fmt.Errorf("generator %s: reading its response: %w", g.name, err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("generator %s failed: %s", g.name, resp.Error)
+	}
+	return writeGeneratedFiles(outputDir, resp.Files)
+}
+
+// writeGeneratedFiles writes a generator's files under dir, refusing paths
+// that would land outside it.
+func writeGeneratedFiles(dir string, files []GeneratedFile) error {
+	for _, f := range files {
+		rel := filepath.FromSlash(f.Path)
+		if !filepath.IsLocal(rel) {
+			return fmt.Errorf("generated file path %q is outside the output directory", f.Path)
+		}
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("creating directory for %s: %w", f.Path, err)
+		}
+		mode := os.FileMode(0644)
+		if f.Executable {
+			mode = 0755
+		}
+		if err := os.WriteFile(path, []byte(f.Content), mode); err != nil {
+			return fmt.Errorf("writing %s: %w", f.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// writePathGenerator writes a human-gen-<name> script to dir that consumes
+// its request and prints response.
+func writePathGenerator(t *testing.T, dir, name, response string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("PATH generator scripts need a POSIX shell")
+	}
+	path := filepath.Join(dir, PathPrefix+name)
+	script := "#!/bin/sh\ncat > /dev/null\necho '" + response + "'\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiscoverPath(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writePathGenerator(t, first, "rails", `{}`)
+	writePathGenerator(t, second, "rails", `{}`)
+	writePathGenerator(t, second, "laravel", `{}`)
+	os.WriteFile(filepath.Join(second, PathPrefix+"notes"), []byte("not executable"), 0644)
+	t.Setenv("PATH", first+string(os.PathListSeparator)+second)
+
+	found := DiscoverPath()
+	var names []string
+	for _, g := range found {
+		names = append(names, g.Meta().Name)
+	}
+	if strings.Join(names, ",") != "laravel,rails" {
+		t.Fatalf("DiscoverPath() = %v, want [laravel rails]", names)
+	}
+	if got := found[1].(*PathGenerator).BinaryPath(); filepath.Dir(got) != first {
+		t.Errorf("expected the first rails on PATH, got %s", got)
+	}
+}
+
+func TestPathGeneratorEnabled(t *testing.T) {
+	g := NewPathGenerator("/usr/local/bin/human-gen-rails")
+	for backend, want := range map[string]bool{
+		"Rails":             true,
+		"Ruby with Rails":   true,
+		"Node with Express": false,
+		"Railsway":          false,
+	} {
+		app := &ir.Application{Config: &ir.BuildConfig{Backend: backend}}
+		if got := g.Enabled(app); got != want {
+			t.Errorf("Enabled(backend %q) = %v, want %v", backend, got, want)
+		}
+	}
+	if g.Enabled(&ir.Application{}) {
+		t.Error("expected a generator to be disabled without a build config")
+	}
+}
+
+func TestPathGeneratorGenerate(t *testing.T) {
+	bin := writePathGenerator(t, t.TempDir(), "mock",
+		`{"files":[{"path":"app/README.md","content":"hi"},{"path":"bin/start","content":"#!/bin/sh","executable":true}]}`)
+	out := t.TempDir()
+	if err := NewPathGenerator(bin).Generate(&ir.Application{Name: "Test"}, out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "app", "README.md"))
+	if err != nil || string(data) != "hi" {
+		t.Errorf("app/README.md = %q, %v", data, err)
+	}
+	if info, err := os.Stat(filepath.Join(out, "bin", "start")); err != nil || info.Mode()&0100 == 0 {
+		t.Errorf("expected bin/start to be executable: %v", err)
+	}
+}
+
+func TestPathGeneratorErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, tc := range map[string]struct{ response, want string }{
+		"failing": {`{"error":"unsupported database"}`, "unsupported database"},
+		"escape":  {`{"files":[{"path":"../outside","content":"x"}]}`, "outside the output directory"},
+		"garbled": {`not json`, "reading its response"},
+	} {
+		bin := writePathGenerator(t, dir, name, tc.response)
+		err := NewPathGenerator(bin).Generate(&ir.Application{}, t.TempDir())
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: Generate error = %v, want %q", name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package quality
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+var (
+	// imgTagRe matches an <img> tag written on one line, as the
+	// generators write them.
+	imgTagRe = regexp.MustCompile(`<img\b[^\n]*?/?>`)
+	// htmlTagRe matches the <html> tag of an HTML shell.
+	htmlTagRe = regexp.MustCompile(`<html\b[^>]*>`)
+	// clickableRe matches an element with no role of its own that handles
+	// clicks: onClick in React, @click in Vue, on:click in Svelte, and
+	// (click) in Angular.
+	clickableRe = regexp.MustCompile(`<(div|span|li|tr|td)\b[^\n]*?(onClick|@click|on:click|\(click\))=`)
+	// altAttrRe matches the alt attribute, bound or not.
+	altAttrRe = regexp.MustCompile(`(^|[\s:\[])alt\]?=`)
+)
+
+// a11ySources are the extensions of the frontend files checked.
+var a11ySources = map[string]bool{".tsx": true, ".jsx": true, ".vue": true, ".svelte": true, ".html": true, ".ts": true}
+
+// checkAccessibility reads the generated frontend for markup screen
+// readers and keyboard users can't use: images without alt text, a page
+// without a language, and clickable elements that aren't buttons or links
+// and say nothing of what they are. Findings are by file.
+func checkAccessibility(app *ir.Application, outputDir string) []Finding {
+	if app.Config == nil || app.Config.Frontend == "" {
+		return nil
+	}
+	fe := strings.ToLower(app.Config.Frontend)
+	var dir string
+	for _, name := range []string{"react", "vue", "svelte", "angular"} {
+		if strings.Contains(fe, name) {
+			dir = name
+			break
+		}
+	}
+	if dir == "" {
+		return nil
+	}
+
+	var findings []Finding
+	filepath.WalkDir(filepath.Join(outputDir, dir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if name := d.Name(); name == "node_modules" || name == "dist" || name == "__tests__" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !a11ySources[filepath.Ext(path)] || strings.HasSuffix(path, ".d.ts") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(outputDir, path)
+		findings = append(findings, checkAccessibilityOf(filepath.ToSlash(rel), string(data))...)
+		return nil
+	})
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Target < findings[j].Target })
+	return findings
+}
+
+// checkAccessibilityOf checks one frontend file, named target.
+func checkAccessibilityOf(target, source string) []Finding {
+	var findings []Finding
+	noAlt := 0
+	for _, tag := range imgTagRe.FindAllString(source, -1) {
+		if !altAttrRe.MatchString(tag) {
+			noAlt++
+		}
+	}
+	if noAlt > 0 {
+		findings = append(findings, Finding{
+			Severity: "warning",
+			Category: "a11y",
+			Message:  fmt.Sprintf("%d image(s) without alt text; screen readers read out the file name", noAlt),
+			Target:   target,
+		})
+	}
+
+	if m := htmlTagRe.FindString(source); m != "" && !strings.Contains(m, "lang=") {
+		findings = append(findings, Finding{
+			Severity: "warning",
+			Category: "a11y",
+			Message:  "The <html> tag has no lang attribute; screen readers can't tell which language to read the page in",
+			Target:   target,
+		})
+	}
+
+	unlabeled := 0
+	for _, line := range strings.Split(source, "\n") {
+		if clickableRe.MatchString(line) && !strings.Contains(line, "role=") {
+			unlabeled++
+		}
+	}
+	if unlabeled > 0 {
+		findings = append(findings, Finding{
+			Severity: "info",
+			Category: "a11y",
+			Message:  fmt.Sprintf("%d clickable element(s) without a role; keyboard and screen reader users can't tell they do anything", unlabeled),
+			Target:   target,
+		})
+	}
+	return findings
+}
+
+// renderAccessibilitySection produces the build report's accessibility
+// section.
+func renderAccessibilitySection(findings []Finding) string {
+	var b strings.Builder
+	b.WriteString("## Accessibility\n\n")
+	if len(findings) == 0 {
+		b.WriteString("No accessibility issues found.\n\n")
+		return b.String()
+	}
+	b.WriteString("| Severity | File | Message |\n")
+	b.WriteString("|----------|------|---------|\n")
+	for _, f := range findings {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", f.Severity, f.Target, f.Message)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
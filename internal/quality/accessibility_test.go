@@ -0,0 +1,51 @@
+package quality
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func TestCheckAccessibility(t *testing.T) {
+	dir := t.TempDir()
+	app := &ir.Application{Config: &ir.BuildConfig{Frontend: "React"}}
+	writeTestFile(t, filepath.Join(dir, "react", "index.html"), "<!doctype html>\n<html lang=\"en\">\n")
+	writeTestFile(t, filepath.Join(dir, "react", "src", "pages", "HomePage.tsx"),
+		"<img src={logo} alt=\"Logo\" />\n"+
+			"<img src={user.avatar} />\n"+
+			"<div className=\"card\" onClick={open} role=\"button\" tabIndex={0}>\n"+
+			"<tr key={t.id} onClick={() => navigate(t)}>\n")
+	writeTestFile(t, filepath.Join(dir, "react", "node_modules", "x", "index.html"), "<html><img src=\"a\"></html>\n")
+
+	findings := checkAccessibility(app, dir)
+	if len(findings) != 2 {
+		t.Fatalf("expected an image and a clickable row, got %+v", findings)
+	}
+	for _, f := range findings {
+		if f.Target != "react/src/pages/HomePage.tsx" || f.Category != "a11y" {
+			t.Errorf("unexpected finding: %+v", f)
+		}
+	}
+	if !strings.Contains(findings[0].Message, "1 image") || findings[0].Severity != "warning" {
+		t.Errorf("expected the image without alt text, got %+v", findings[0])
+	}
+	if !strings.Contains(findings[1].Message, "1 clickable") || findings[1].Severity != "info" {
+		t.Errorf("expected the clickable row, got %+v", findings[1])
+	}
+}
+
+func TestCheckAccessibilityOf(t *testing.T) {
+	if f := checkAccessibilityOf("index.html", "<html>\n<body></body>"); len(f) != 1 || !strings.Contains(f[0].Message, "lang") {
+		t.Errorf("expected the missing lang, got %+v", f)
+	}
+	vue := "<img :alt=\"post.title\" :src=\"post.cover\" />\n<li @click=\"open(post)\">\n"
+	if f := checkAccessibilityOf("Post.vue", vue); len(f) != 1 || f[0].Severity != "info" {
+		t.Errorf("expected the bound alt to count and the clickable li, got %+v", f)
+	}
+	angular := "<img [alt]=\"name\" [src]=\"url\">\n<div (click)=\"go()\" role=\"link\">\n"
+	if f := checkAccessibilityOf("app.component.html", angular); len(f) != 0 {
+		t.Errorf("expected no findings, got %+v", f)
+	}
+}
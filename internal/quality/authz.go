@@ -0,0 +1,322 @@
+package quality
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// AuthzMatrix is the access-control matrix: what each policy role may do
+// on each endpoint, as the generated authorization middleware decides it.
+type AuthzMatrix struct {
+	Roles     []string         `json:"roles"`
+	Endpoints []*AuthzEndpoint `json:"endpoints"`
+}
+
+// AuthzEndpoint is one row of the matrix.
+type AuthzEndpoint struct {
+	Name   string                `json:"name"`
+	Auth   bool                  `json:"auth"`
+	Action string                `json:"action,omitempty"` // create, view, edit, delete
+	Model  string                `json:"model,omitempty"`
+	Access map[string]*AuthzCell `json:"access"` // keyed by role
+}
+
+// AuthzCell is one role's access to one endpoint.
+type AuthzCell struct {
+	// Decision is "public" (no sign-in), "authenticated" (any signed-in
+	// user — no policy check applies), "allow", "deny", or "default-allow"
+	// (the role's policy has no rule for the action, so the middleware
+	// lets it through).
+	Decision  string `json:"decision"`
+	Scope     string `json:"scope,omitempty"`     // own, any, all
+	Quota     string `json:"quota,omitempty"`     // e.g. "50 per month"
+	Condition string `json:"condition,omitempty"` // e.g. "completed"
+	Rule      string `json:"rule,omitempty"`      // the policy rule that decided
+}
+
+// authzRule is a policy rule parsed the way the backend generators parse
+// it for their authorization middleware.
+type authzRule struct {
+	action, model, scope, period, condition string
+	limit                                   int
+	text                                    string
+}
+
+// buildAuthzMatrix derives the matrix from the app's policies and
+// endpoints. Like the generated middleware, restrictions take precedence
+// over permissions, and endpoints whose action or model can't be inferred
+// from their name are only checked for sign-in.
+func buildAuthzMatrix(app *ir.Application) *AuthzMatrix {
+	m := &AuthzMatrix{Roles: []string{}, Endpoints: []*AuthzEndpoint{}}
+	for _, pol := range app.Policies {
+		m.Roles = append(m.Roles, pol.Name)
+	}
+
+	for _, ep := range app.APIs {
+		row := &AuthzEndpoint{
+			Name:   ep.Name,
+			Auth:   ep.Auth,
+			Action: inferAuthzAction(ep.Name),
+			Model:  inferAuthzModel(ep.Name),
+			Access: map[string]*AuthzCell{},
+		}
+		m.Endpoints = append(m.Endpoints, row)
+
+		for _, pol := range app.Policies {
+			switch {
+			case !ep.Auth:
+				row.Access[pol.Name] = &AuthzCell{Decision: "public"}
+			case row.Action == "" || row.Model == "":
+				row.Access[pol.Name] = &AuthzCell{Decision: "authenticated"}
+			default:
+				row.Access[pol.Name] = decideAuthz(pol, row.Action, row.Model)
+			}
+		}
+	}
+	return m
+}
+
+// decideAuthz applies one role's policy to an action on a model.
+func decideAuthz(pol *ir.Policy, action, model string) *AuthzCell {
+	for _, r := range pol.Restrictions {
+		if rule := parseAuthzRule(r.Text); rule.action == action && rule.model == model {
+			return &AuthzCell{Decision: "deny", Condition: rule.condition, Rule: rule.text}
+		}
+	}
+	for _, p := range pol.Permissions {
+		if rule := parseAuthzRule(p.Text); rule.action == action && rule.model == model {
+			cell := &AuthzCell{Decision: "allow", Scope: rule.scope, Condition: rule.condition, Rule: rule.text}
+			if rule.limit > 0 {
+				cell.Quota = strconv.Itoa(rule.limit)
+				if rule.period != "" {
+					cell.Quota += " per " + rule.period
+				}
+			}
+			return cell
+		}
+	}
+	return &AuthzCell{Decision: "default-allow"}
+}
+
+// parseAuthzRule extracts the action, model, scope, quota, and condition
+// from a policy rule's text.
+func parseAuthzRule(text string) authzRule {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	words := strings.Fields(lower)
+	rule := authzRule{text: text}
+	if len(words) == 0 {
+		return rule
+	}
+	rule.action = words[0]
+
+	switch {
+	case strings.Contains(lower, "only their own"), strings.Contains(lower, "any of their own"):
+		rule.scope = "own"
+	case hasWord(words, "any"):
+		rule.scope = "any"
+	case hasWord(words, "all"):
+		rule.scope = "all"
+	}
+
+	// "up to N <model> per <period>"
+	if idx := strings.Index(lower, "up to "); idx >= 0 {
+		rest := strings.Fields(lower[idx+6:])
+		if len(rest) >= 1 {
+			rule.limit, _ = strconv.Atoi(rest[0])
+		}
+		if len(rest) >= 2 && rest[1] != "per" {
+			rule.model = singularizeModel(rest[1])
+		}
+		if p := strings.Index(lower, " per "); p >= 0 {
+			if f := strings.Fields(lower[p+5:]); len(f) > 0 {
+				rule.period = f[0]
+			}
+		}
+		return rule
+	}
+
+	// "unlimited <model>"
+	if idx := strings.Index(lower, "unlimited "); idx >= 0 {
+		if f := strings.Fields(lower[idx+10:]); len(f) > 0 {
+			rule.model = singularizeModel(f[0])
+		}
+		return rule
+	}
+
+	conditions := []string{"completed", "active", "archived", "pending", "draft", "published", "expired"}
+	for _, c := range conditions {
+		if hasWord(words, c) {
+			rule.condition = c
+			break
+		}
+	}
+
+	// The noun after a scope word: "all users", "any task", "own tasks".
+	articles := map[string]bool{"the": true, "a": true, "an": true, "of": true}
+	for _, anchor := range []string{"all", "any", "own"} {
+		for i, w := range words {
+			if w != anchor || i+1 >= len(words) {
+				continue
+			}
+			next := words[i+1]
+			if articles[next] && i+2 < len(words) {
+				next = words[i+2]
+			}
+			if next != rule.action && next != "of" && next != "their" {
+				rule.model = singularizeModel(next)
+				return rule
+			}
+		}
+	}
+
+	// Otherwise the last significant word.
+	skip := map[string]bool{
+		"only": true, "their": true, "own": true, "any": true, "all": true,
+		"of": true, "the": true, "a": true, "an": true, "and": true,
+		"system": true, "up": true, "to": true, "per": true, "unlimited": true,
+		"that": true, "which": true, "where": true, "are": true, "is": true,
+	}
+	for _, c := range conditions {
+		skip[c] = true
+	}
+	for i := len(words) - 1; i >= 1; i-- {
+		if !skip[words[i]] && words[i] != rule.action {
+			rule.model = singularizeModel(words[i])
+			break
+		}
+	}
+	if rule.model == "" && hasWord(words, "data") {
+		rule.model = "data"
+	}
+	return rule
+}
+
+// inferAuthzAction derives the policy action an endpoint is checked for.
+func inferAuthzAction(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "create"):
+		return "create"
+	case strings.HasPrefix(lower, "get"), strings.HasPrefix(lower, "list"), strings.HasPrefix(lower, "fetch"):
+		return "view"
+	case strings.HasPrefix(lower, "update"), strings.HasPrefix(lower, "edit"):
+		return "edit"
+	case strings.HasPrefix(lower, "delete"), strings.HasPrefix(lower, "remove"):
+		return "delete"
+	}
+	return ""
+}
+
+// inferAuthzModel derives the model an endpoint acts on from its name.
+func inferAuthzModel(name string) string {
+	for _, prefix := range []string{"Create", "Get", "List", "Fetch", "Update", "Edit", "Delete", "Remove"} {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return singularizeModel(strings.ToLower(name[len(prefix):]))
+		}
+	}
+	return ""
+}
+
+func singularizeModel(word string) string {
+	switch {
+	case word == "data" || word == "analytics":
+		return word
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ses") || strings.HasSuffix(word, "xes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && len(word) > 1:
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func hasWord(words []string, target string) bool {
+	for _, w := range words {
+		if w == target {
+			return true
+		}
+	}
+	return false
+}
+
+// renderAuthzMatrixJSON returns the matrix as authz-matrix.json.
+func renderAuthzMatrixJSON(m *AuthzMatrix) (string, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// writeAuthzMatrix appends the matrix to the security report.
+func writeAuthzMatrix(b *strings.Builder, m *AuthzMatrix) {
+	if len(m.Endpoints) == 0 {
+		return
+	}
+	b.WriteString("## Authorization Matrix\n\n")
+	if len(m.Roles) == 0 {
+		b.WriteString("No policies are defined, so every endpoint that requires sign-in is open to any signed-in user.\n\n")
+	}
+
+	b.WriteString("| Endpoint | Checks |")
+	for _, role := range m.Roles {
+		fmt.Fprintf(b, " %s |", role)
+	}
+	b.WriteString("\n|----------|--------|")
+	for range m.Roles {
+		b.WriteString("------|")
+	}
+	b.WriteString("\n")
+
+	for _, ep := range m.Endpoints {
+		checks := "public"
+		switch {
+		case ep.Auth && ep.Action != "" && ep.Model != "" && len(m.Roles) > 0:
+			checks = ep.Action + " " + ep.Model
+		case ep.Auth:
+			checks = "sign-in"
+		}
+		fmt.Fprintf(b, "| %s | %s |", ep.Name, checks)
+		for _, role := range m.Roles {
+			fmt.Fprintf(b, " %s |", authzCellLabel(ep.Access[role]))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString("*allow (no rule)*: the role's policy says nothing about this action, so the middleware allows it. The same matrix is in `authz-matrix.json`.\n\n")
+}
+
+func authzCellLabel(c *AuthzCell) string {
+	var label string
+	var notes []string
+	switch c.Decision {
+	case "public":
+		return "public"
+	case "authenticated":
+		return "signed in"
+	case "default-allow":
+		return "*allow (no rule)*"
+	case "deny":
+		label = "**deny**"
+	default:
+		label = "allow"
+		if c.Scope != "" {
+			notes = append(notes, c.Scope)
+		}
+		if c.Quota != "" {
+			notes = append(notes, "quota "+c.Quota)
+		}
+	}
+	if c.Condition != "" {
+		notes = append(notes, c.Condition)
+	}
+	if len(notes) > 0 {
+		label += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return label
+}
@@ -0,0 +1,92 @@
+package quality
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func authzApp() *ir.Application {
+	return &ir.Application{
+		APIs: []*ir.Endpoint{
+			{Name: "Login"},
+			{Name: "CreateTask", Auth: true},
+			{Name: "DeleteTask", Auth: true},
+			{Name: "GetTasks", Auth: true},
+			{Name: "Search", Auth: true},
+		},
+		Policies: []*ir.Policy{
+			{
+				Name: "FreeUser",
+				Permissions: []*ir.PolicyRule{
+					{Text: "create up to 50 tasks per month"},
+					{Text: "view only their own tasks"},
+				},
+				Restrictions: []*ir.PolicyRule{{Text: "delete completed tasks"}},
+			},
+			{
+				Name:        "Admin",
+				Permissions: []*ir.PolicyRule{{Text: "delete any task"}},
+			},
+		},
+	}
+}
+
+func TestBuildAuthzMatrix(t *testing.T) {
+	m := buildAuthzMatrix(authzApp())
+	if strings.Join(m.Roles, ",") != "FreeUser,Admin" {
+		t.Fatalf("roles: %v", m.Roles)
+	}
+
+	tests := []struct {
+		endpoint, role string
+		want           AuthzCell
+	}{
+		{"Login", "FreeUser", AuthzCell{Decision: "public"}},
+		{"CreateTask", "FreeUser", AuthzCell{Decision: "allow", Quota: "50 per month", Rule: "create up to 50 tasks per month"}},
+		{"CreateTask", "Admin", AuthzCell{Decision: "default-allow"}},
+		{"DeleteTask", "FreeUser", AuthzCell{Decision: "deny", Condition: "completed", Rule: "delete completed tasks"}},
+		{"DeleteTask", "Admin", AuthzCell{Decision: "allow", Scope: "any", Rule: "delete any task"}},
+		{"GetTasks", "FreeUser", AuthzCell{Decision: "allow", Scope: "own", Rule: "view only their own tasks"}},
+		{"Search", "Admin", AuthzCell{Decision: "authenticated"}},
+	}
+	rows := map[string]*AuthzEndpoint{}
+	for _, ep := range m.Endpoints {
+		rows[ep.Name] = ep
+	}
+	for _, tt := range tests {
+		got := rows[tt.endpoint].Access[tt.role]
+		if got == nil || *got != tt.want {
+			t.Errorf("%s × %s: got %+v, want %+v", tt.endpoint, tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestSecurityReportAuthzMatrix(t *testing.T) {
+	report := renderSecurityReport(authzApp(), nil)
+	for _, want := range []string{
+		"## Authorization Matrix",
+		"| Endpoint | Checks | FreeUser | Admin |",
+		"| CreateTask | create task | allow (quota 50 per month) | *allow (no rule)* |",
+		"| DeleteTask | delete task | **deny** (completed) | allow (any) |",
+		"| Search | sign-in | signed in | signed in |",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("security report missing %q:\n%s", want, report)
+		}
+	}
+
+	data, err := renderAuthzMatrixJSON(buildAuthzMatrix(authzApp()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m AuthzMatrix
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		t.Fatalf("authz-matrix.json is not valid JSON: %v", err)
+	}
+	if len(m.Endpoints) != 5 || m.Endpoints[1].Access["FreeUser"].Quota != "50 per month" {
+		t.Errorf("unexpected JSON matrix: %s", data)
+	}
+}
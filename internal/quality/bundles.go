@@ -0,0 +1,202 @@
+package quality
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// staticImportRe matches the module of a static import or re-export:
+// import x from '...', import '...', export { x } from '...'.
+var staticImportRe = regexp.MustCompile(`(?:\bfrom|^\s*import|@import)\s+['"]([^'"]+)['"]`)
+
+// dynamicImportRe matches the module of a dynamic import, which is split
+// into a chunk of its own.
+var dynamicImportRe = regexp.MustCompile(`\bimport\(\s*['"]([^'"]+)['"]\s*\)`)
+
+// importExtensions are tried in turn on an import written without one.
+var importExtensions = []string{"", ".ts", ".tsx", ".js", ".vue", ".svelte", "/index.ts", "/index.tsx", "/index.js"}
+
+// bundleFrontend is where a frontend's app shell starts and which file
+// lazy-loads its pages.
+type bundleFrontend struct {
+	entry  string
+	router string
+}
+
+var bundleFrontends = map[string]bundleFrontend{
+	"react":   {entry: filepath.Join("react", "src", "main.tsx"), router: filepath.Join("react", "src", "App.tsx")},
+	"vue":     {entry: filepath.Join("vue", "src", "main.ts"), router: filepath.Join("vue", "src", "router.ts")},
+	"angular": {entry: filepath.Join("angular", "src", "main.ts"), router: filepath.Join("angular", "src", "app", "app.routes.ts")},
+}
+
+// checkBundleBudget measures how much generated code each route of the
+// frontend loads — the app shell and everything the route's page imports —
+// against the app's bundle budget. Third-party packages aren't counted:
+// the budget keeps the generated templates from growing unnoticed.
+func checkBundleBudget(app *ir.Application, outputDir string) []PerformanceFinding {
+	return bundleBudgetFindings(app, routeSizes(app, outputDir))
+}
+
+// routeSize is how much generated code a route loads.
+type routeSize struct {
+	target string // the route's page, relative to the output directory
+	kb     int
+}
+
+// routeSizes measures the generated code each route of the frontend loads,
+// in route order. It's empty without a generated frontend.
+func routeSizes(app *ir.Application, outputDir string) []routeSize {
+	if app.Config == nil || app.Config.Frontend == "" {
+		return nil
+	}
+	fe := strings.ToLower(app.Config.Frontend)
+	var shell []string
+	var routes []string
+	switch {
+	case strings.Contains(fe, "svelte"):
+		shell, routes = svelteRoutes(outputDir)
+	default:
+		for _, name := range []string{"react", "vue", "angular"} {
+			if strings.Contains(fe, name) {
+				f := bundleFrontends[name]
+				shell = []string{filepath.Join(outputDir, f.entry)}
+				routes = lazyRoutes(filepath.Join(outputDir, f.router))
+				break
+			}
+		}
+	}
+	if len(routes) == 0 {
+		return nil
+	}
+
+	shellFiles := map[string]bool{}
+	for _, s := range shell {
+		collectImports(s, outputDir, shellFiles)
+	}
+
+	var sizes []routeSize
+	for _, route := range routes {
+		files := map[string]bool{}
+		for f := range shellFiles {
+			files[f] = true
+		}
+		collectImports(route, outputDir, files)
+		target, _ := filepath.Rel(outputDir, route)
+		sizes = append(sizes, routeSize{target: filepath.ToSlash(target), kb: bundleKB(files)})
+	}
+	return sizes
+}
+
+// bundleBudgetFindings reports the routes over the app's bundle budget.
+func bundleBudgetFindings(app *ir.Application, sizes []routeSize) []PerformanceFinding {
+	budget := ir.BundleBudgetKB(app)
+	var findings []PerformanceFinding
+	for _, r := range sizes {
+		if r.kb <= budget {
+			continue
+		}
+		findings = append(findings, PerformanceFinding{
+			Kind:     "bundle-budget",
+			Severity: "warning",
+			Target:   r.target,
+			Message:  fmt.Sprintf("The route loads %d KB of generated code, over the %d KB budget", r.kb, budget),
+			Fix:      fmt.Sprintf("Split the page into smaller pages or components, or raise the budget: bundle budget is %d KB per route", r.kb),
+		})
+	}
+	return findings
+}
+
+// lazyRoutes returns the modules a router file loads lazily, one per route.
+func lazyRoutes(router string) []string {
+	data, err := os.ReadFile(router)
+	if err != nil {
+		return nil
+	}
+	var routes []string
+	seen := map[string]bool{}
+	for _, m := range dynamicImportRe.FindAllStringSubmatch(string(data), -1) {
+		if path := resolveImport(router, m[1], ""); path != "" && !seen[path] {
+			seen[path] = true
+			routes = append(routes, path)
+		}
+	}
+	return routes
+}
+
+// svelteRoutes returns the layout every SvelteKit route shares and the
+// page of each route, which SvelteKit splits into a chunk of its own.
+func svelteRoutes(outputDir string) (shell, routes []string) {
+	dir := filepath.Join(outputDir, "svelte", "src", "routes")
+	layout := filepath.Join(dir, "+layout.svelte")
+	if _, err := os.Stat(layout); err == nil {
+		shell = append(shell, layout)
+	}
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && d.Name() == "+page.svelte" {
+			routes = append(routes, path)
+		}
+		return nil
+	})
+	sort.Strings(routes)
+	return shell, routes
+}
+
+// collectImports adds a module and everything it imports statically,
+// transitively, to files. Dynamic imports are left out: they load later,
+// in chunks of their own.
+func collectImports(path, outputDir string, files map[string]bool) {
+	if files[path] {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	files[path] = true
+	for _, line := range strings.Split(string(data), "\n") {
+		for _, m := range staticImportRe.FindAllStringSubmatch(line, -1) {
+			if dep := resolveImport(path, m[1], outputDir); dep != "" {
+				collectImports(dep, outputDir, files)
+			}
+		}
+	}
+}
+
+// resolveImport returns the generated file an import from a module refers
+// to, or "" for a package or a file that doesn't exist. SvelteKit's $lib
+// alias resolves under outputDir when given.
+func resolveImport(from, spec, outputDir string) string {
+	var base string
+	switch {
+	case strings.HasPrefix(spec, "./") || strings.HasPrefix(spec, "../"):
+		base = filepath.Join(filepath.Dir(from), spec)
+	case strings.HasPrefix(spec, "$lib/") && outputDir != "":
+		base = filepath.Join(outputDir, "svelte", "src", "lib", strings.TrimPrefix(spec, "$lib/"))
+	default:
+		return ""
+	}
+	for _, ext := range importExtensions {
+		if info, err := os.Stat(base + ext); err == nil && !info.IsDir() {
+			return base + ext
+		}
+	}
+	return ""
+}
+
+// bundleKB returns the size of files in KB, rounded up.
+func bundleKB(files map[string]bool) int {
+	var size int64
+	for f := range files {
+		if info, err := os.Stat(f); err == nil {
+			size += info.Size()
+		}
+	}
+	return int((size + 1023) / 1024)
+}
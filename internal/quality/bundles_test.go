@@ -0,0 +1,61 @@
+package quality
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func TestCheckBundleBudget(t *testing.T) {
+	dir := t.TempDir()
+	app := &ir.Application{Config: &ir.BuildConfig{Frontend: "React", BundleBudget: "2 KB per route"}}
+	src := filepath.Join(dir, "react", "src")
+
+	// Not generated yet: nothing to check.
+	if findings := checkBundleBudget(app, dir); len(findings) != 0 {
+		t.Errorf("expected no findings without a frontend, got %+v", findings)
+	}
+
+	writeTestFile(t, filepath.Join(src, "main.tsx"), "import App from './App';\nimport './index.css';\n")
+	writeTestFile(t, filepath.Join(src, "index.css"), strings.Repeat("a", 512))
+	writeTestFile(t, filepath.Join(src, "App.tsx"), "import { lazy } from 'react';\n"+
+		"const HomePage = lazy(() => import('./pages/HomePage'));\n"+
+		"const ReportsPage = lazy(() => import('./pages/ReportsPage'));\n")
+	writeTestFile(t, filepath.Join(src, "pages", "HomePage.tsx"), "import Card from '../components/Card';\n")
+	writeTestFile(t, filepath.Join(src, "pages", "ReportsPage.tsx"), "import Chart from '../components/Chart';\n"+
+		"const Export = lazy(() => import('../components/Export'));\n")
+	writeTestFile(t, filepath.Join(src, "components", "Card.tsx"), strings.Repeat("c", 512))
+	writeTestFile(t, filepath.Join(src, "components", "Chart.tsx"), "import Card from './Card';\n"+strings.Repeat("c", 2048))
+	writeTestFile(t, filepath.Join(src, "components", "Export.tsx"), strings.Repeat("e", 8192))
+
+	findings := checkBundleBudget(app, dir)
+	if len(findings) != 1 {
+		t.Fatalf("expected the reports route alone over budget, got %+v", findings)
+	}
+	f := findings[0]
+	if f.Kind != "bundle-budget" || f.Target != "react/src/pages/ReportsPage.tsx" || !strings.Contains(f.Message, "4 KB") {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+
+	app.Config.BundleBudget = "10 KB"
+	if findings := checkBundleBudget(app, dir); len(findings) != 0 {
+		t.Errorf("expected every route within 10 KB, got %+v", findings)
+	}
+}
+
+func TestCheckBundleBudget_Svelte(t *testing.T) {
+	dir := t.TempDir()
+	app := &ir.Application{Config: &ir.BuildConfig{Frontend: "Svelte", BundleBudget: "1 KB"}}
+	routes := filepath.Join(dir, "svelte", "src", "routes")
+	writeTestFile(t, filepath.Join(routes, "+layout.svelte"), "<slot />\n")
+	writeTestFile(t, filepath.Join(routes, "+page.svelte"), "<h1>Home</h1>\n")
+	writeTestFile(t, filepath.Join(routes, "reports", "+page.svelte"), "<script>\n  import Chart from '$lib/components/Chart.svelte';\n</script>\n")
+	writeTestFile(t, filepath.Join(dir, "svelte", "src", "lib", "components", "Chart.svelte"), strings.Repeat("c", 2048))
+
+	findings := checkBundleBudget(app, dir)
+	if len(findings) != 1 || findings[0].Target != "svelte/src/routes/reports/+page.svelte" {
+		t.Errorf("expected the reports route over budget, got %+v", findings)
+	}
+}
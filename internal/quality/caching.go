@@ -0,0 +1,123 @@
+package quality
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// viteFileNamesRe matches Rollup output filename options in a Vite config.
+var viteFileNamesRe = regexp.MustCompile(`(entryFileNames|chunkFileNames|assetFileNames)\s*:\s*['"\x60]([^'"\x60]*)['"\x60]`)
+
+// viteAssetsDirRe matches a build.assetsDir override in a Vite config.
+var viteAssetsDirRe = regexp.MustCompile(`assetsDir\s*:\s*['"\x60]([^'"\x60]*)['"\x60]`)
+
+// checkCacheBusting verifies that the frontend build gives its bundles
+// content-hashed filenames where the nginx config and CDN expect them.
+// Those paths are cached for a year, so an unhashed bundle there would
+// keep serving stale code after a deploy.
+func checkCacheBusting(app *ir.Application, outputDir string) []PerformanceFinding {
+	if app.Config == nil || app.Config.Frontend == "" {
+		return nil
+	}
+	fe := strings.ToLower(app.Config.Frontend)
+	if strings.Contains(fe, "angular") {
+		return checkAngularHashing(filepath.Join(outputDir, "angular", "angular.json"))
+	}
+	for _, dir := range []string{"react", "vue", "svelte"} {
+		if strings.Contains(fe, dir) {
+			return checkViteHashing(app, filepath.Join(outputDir, dir))
+		}
+	}
+	return nil
+}
+
+// checkAngularHashing reads angular.json's build options, since Angular
+// only hashes filenames when outputHashing is "all".
+func checkAngularHashing(path string) []PerformanceFinding {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var workspace struct {
+		Projects map[string]struct {
+			Architect struct {
+				Build struct {
+					Options struct {
+						OutputHashing string `json:"outputHashing"`
+					} `json:"options"`
+				} `json:"build"`
+			} `json:"architect"`
+		} `json:"projects"`
+	}
+	if err := json.Unmarshal(data, &workspace); err != nil {
+		return nil
+	}
+
+	var findings []PerformanceFinding
+	for name, project := range workspace.Projects {
+		hashing := project.Architect.Build.Options.OutputHashing
+		if hashing == "all" {
+			continue
+		}
+		if hashing == "" {
+			hashing = "none"
+		}
+		findings = append(findings, PerformanceFinding{
+			Kind:     "cache-busting",
+			Severity: "warning",
+			Target:   "angular.json",
+			Message:  fmt.Sprintf("Project %s builds with outputHashing %q — cached bundles would go stale after a deploy", name, hashing),
+			Fix:      `Set "outputHashing": "all" in the build options`,
+		})
+	}
+	return findings
+}
+
+// checkViteHashing reads the Vite config, if there is one yet. Vite hashes
+// every bundle under assets/ unless the config says otherwise.
+func checkViteHashing(app *ir.Application, dir string) []PerformanceFinding {
+	var data []byte
+	var name string
+	for _, candidate := range []string{"vite.config.ts", "vite.config.js"} {
+		if b, err := os.ReadFile(filepath.Join(dir, candidate)); err == nil {
+			data, name = b, candidate
+			break
+		}
+	}
+	if data == nil {
+		return nil
+	}
+
+	var findings []PerformanceFinding
+	for _, m := range viteFileNamesRe.FindAllStringSubmatch(string(data), -1) {
+		if strings.Contains(m[2], "[hash]") {
+			continue
+		}
+		findings = append(findings, PerformanceFinding{
+			Kind:     "cache-busting",
+			Severity: "warning",
+			Target:   name,
+			Message:  fmt.Sprintf("%s %q has no [hash] — cached bundles would go stale after a deploy", m[1], m[2]),
+			Fix:      fmt.Sprintf("Include [hash] in %s, e.g. 'assets/[name]-[hash].js'", m[1]),
+		})
+	}
+	if m := viteAssetsDirRe.FindStringSubmatch(string(data)); m != nil {
+		want := strings.Trim(strings.TrimSuffix(ir.HashedAssetPaths(app)[0], "*"), "/")
+		if strings.Trim(m[1], "/") != want {
+			findings = append(findings, PerformanceFinding{
+				Kind:     "cache-busting",
+				Severity: "warning",
+				Target:   name,
+				Message:  fmt.Sprintf("assetsDir %q moves hashed bundles out of /%s/, which the nginx config and CDN cache for a year", m[1], want),
+				Fix:      "Remove the assetsDir override",
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,73 @@
+package quality
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckCacheBusting_Angular(t *testing.T) {
+	dir := t.TempDir()
+	app := &ir.Application{Config: &ir.BuildConfig{Frontend: "Angular"}}
+	path := filepath.Join(dir, "angular", "angular.json")
+
+	writeTestFile(t, path, `{"projects": {"app": {"architect": {"build": {"options": {"outputPath": "dist/app"}}}}}}`)
+	findings := checkCacheBusting(app, dir)
+	if len(findings) != 1 || findings[0].Kind != "cache-busting" || !strings.Contains(findings[0].Message, `"none"`) {
+		t.Fatalf("expected a finding for unhashed Angular output, got %+v", findings)
+	}
+
+	writeTestFile(t, path, `{"projects": {"app": {"architect": {"build": {"options": {"outputHashing": "all"}}}}}}`)
+	if findings := checkCacheBusting(app, dir); len(findings) != 0 {
+		t.Errorf("expected no findings with outputHashing all, got %+v", findings)
+	}
+}
+
+func TestCheckCacheBusting_Vite(t *testing.T) {
+	dir := t.TempDir()
+	app := &ir.Application{Config: &ir.BuildConfig{Frontend: "Vue"}}
+
+	// No config yet: Vite's defaults hash everything.
+	if findings := checkCacheBusting(app, dir); len(findings) != 0 {
+		t.Errorf("expected no findings without a Vite config, got %+v", findings)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "vue", "vite.config.ts"), `export default defineConfig({
+  build: {
+    assetsDir: 'static',
+    rollupOptions: {
+      output: {
+        entryFileNames: 'assets/[name].js',
+        chunkFileNames: 'assets/[name]-[hash].js',
+      },
+    },
+  },
+})
+`)
+	findings := checkCacheBusting(app, dir)
+	if len(findings) != 2 {
+		t.Fatalf("expected findings for entryFileNames and assetsDir, got %+v", findings)
+	}
+	if !strings.Contains(findings[0].Message, "entryFileNames") || !strings.Contains(findings[1].Message, "/assets/") {
+		t.Errorf("unexpected findings: %+v", findings)
+	}
+}
+
+func TestCheckCacheBusting_NoFrontend(t *testing.T) {
+	if findings := checkCacheBusting(&ir.Application{}, t.TempDir()); findings != nil {
+		t.Errorf("expected no findings without a frontend, got %+v", findings)
+	}
+}
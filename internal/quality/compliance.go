@@ -0,0 +1,246 @@
+package quality
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// ComplianceCheck is one requirement of the app's compliance profile and
+// whether the .human file meets it.
+type ComplianceCheck struct {
+	Requirement string
+	Status      string // "pass", "warn", or "fail"
+	Detail      string
+	Fix         string // what to change in the .human file; empty on pass
+}
+
+// CheckCompliance checks the app against its compliance profile. It
+// returns nil when the app has no profile.
+func CheckCompliance(app *ir.Application) []ComplianceCheck {
+	profile := ir.Compliance(app)
+	if profile == nil {
+		return nil
+	}
+	return []ComplianceCheck{
+		complianceAuthentication(app),
+		complianceAccessControl(app),
+		complianceEncryptionAtRest(app),
+		complianceEncryptionInTransit(app),
+		complianceAuditLogging(app),
+		complianceLogRetention(app, profile),
+		complianceBackups(app, profile),
+		complianceSecrets(app),
+	}
+}
+
+// ComplianceFailed reports whether any check failed.
+func ComplianceFailed(checks []ComplianceCheck) bool {
+	for _, c := range checks {
+		if c.Status == "fail" {
+			return true
+		}
+	}
+	return false
+}
+
+func complianceAuthentication(app *ir.Application) ComplianceCheck {
+	c := ComplianceCheck{Requirement: "Authentication"}
+	var open []string
+	for _, f := range checkMissingAuth(app) {
+		open = append(open, f.Target)
+	}
+	if len(open) > 0 {
+		c.Status = "fail"
+		c.Detail = "These endpoints change data without sign-in: " + strings.Join(open, ", ")
+		c.Fix = "Add \"requires authentication\" to each endpoint"
+		return c
+	}
+	c.Status = "pass"
+	c.Detail = "Every endpoint that changes data requires sign-in"
+	return c
+}
+
+func complianceAccessControl(app *ir.Application) ComplianceCheck {
+	c := ComplianceCheck{Requirement: "Access control"}
+	authed := false
+	for _, ep := range app.APIs {
+		if ep.Auth {
+			authed = true
+			break
+		}
+	}
+	if authed && len(app.Policies) == 0 {
+		c.Status = "fail"
+		c.Detail = "No policies are defined, so any signed-in user can call every endpoint"
+		c.Fix = "Add a policy for each role saying what it can and cannot do"
+		return c
+	}
+	c.Status = "pass"
+	c.Detail = "Policies restrict what each role can do"
+	if !authed {
+		c.Detail = "No endpoints require sign-in"
+	}
+	return c
+}
+
+func complianceEncryptionAtRest(app *ir.Application) ComplianceCheck {
+	c := ComplianceCheck{Requirement: "Encryption at rest"}
+	var encrypted, unique, unencryptable []string
+	for _, m := range app.Data {
+		for _, f := range m.Fields {
+			if !f.IsPII() {
+				continue
+			}
+			name := m.Name + "." + f.Name
+			switch {
+			case f.EncryptsAtRest():
+				encrypted = append(encrypted, name)
+			case f.Unique:
+				unique = append(unique, name)
+			default:
+				unencryptable = append(unencryptable, name)
+			}
+		}
+	}
+	switch {
+	case len(unencryptable) > 0:
+		c.Status = "fail"
+		c.Detail = "Personal data that isn't text can't be encrypted at rest: " + strings.Join(unencryptable, ", ")
+		c.Fix = "Store these fields as text so they can be encrypted"
+	case len(unique) > 0:
+		c.Status = "warn"
+		c.Detail = "Unique fields are looked up by value, so they rely on the database's storage encryption: " + strings.Join(unique, ", ")
+		c.Fix = "Deploy to a database with encrypted storage"
+	case len(encrypted) > 0:
+		c.Status = "pass"
+		c.Detail = "Personal data is encrypted: " + strings.Join(encrypted, ", ")
+	default:
+		c.Status = "pass"
+		c.Detail = "No fields hold personal data"
+	}
+	return c
+}
+
+func complianceEncryptionInTransit(app *ir.Application) ComplianceCheck {
+	c := ComplianceCheck{Requirement: "Encryption in transit"}
+	env := ir.PublicEnvironment(app)
+	if env == nil {
+		c.Status = "fail"
+		c.Detail = "No environment has a URL, so no TLS certificate is issued"
+		c.Fix = "Give the production environment a URL, e.g. \"url is app.example.com\""
+		return c
+	}
+	c.Status = "pass"
+	c.Detail = "HTTPS on " + env.Host()
+	return c
+}
+
+func complianceAuditLogging(app *ir.Application) ComplianceCheck {
+	c := ComplianceCheck{Requirement: "Audit logging"}
+	backend := ""
+	if app.Config != nil {
+		backend = strings.ToLower(app.Config.Backend)
+	}
+	switch {
+	case strings.Contains(backend, "node"), strings.Contains(backend, "python"), codegen.MatchesGoBackend(backend):
+		c.Status = "pass"
+		c.Detail = "Requests that change data are logged with the user who made them"
+	default:
+		c.Status = "fail"
+		c.Detail = "Audit logging is generated for Node, Python, and Go backends only"
+		c.Fix = "Choose a Node, Python, or Go backend"
+	}
+	return c
+}
+
+func complianceLogRetention(app *ir.Application, profile *ir.ComplianceProfile) ComplianceCheck {
+	c := ComplianceCheck{Requirement: "Log retention"}
+	days := ir.LogRetentionDays(app)
+	if declared, ok := ir.DeclaredLogRetentionDays(app); ok && declared < profile.LogRetentionDays {
+		c.Status = "fail"
+		c.Detail = fmt.Sprintf("Logs are kept for %d days; %s requires %d", declared, profile.Name, profile.LogRetentionDays)
+		c.Fix = fmt.Sprintf("Change the monitoring rule to \"keep logs for %d days\"", profile.LogRetentionDays)
+		return c
+	}
+	if app.Config != nil && strings.Contains(strings.ToLower(app.Config.Deploy), "docker") {
+		c.Status = "warn"
+		c.Detail = "Docker keeps container logs only as long as the containers exist"
+		c.Fix = fmt.Sprintf("Ship logs to a store that keeps them for %d days, or deploy to AWS or GCP", days)
+		return c
+	}
+	c.Status = "pass"
+	c.Detail = fmt.Sprintf("Access and audit logs are kept for %d days", days)
+	return c
+}
+
+func complianceBackups(app *ir.Application, profile *ir.ComplianceProfile) ComplianceCheck {
+	c := ComplianceCheck{Requirement: "Backups"}
+	if len(app.Data) == 0 {
+		c.Status = "pass"
+		c.Detail = "The app stores no data"
+		return c
+	}
+	if !ir.BacksUpDatabase(app) {
+		c.Status = "fail"
+		c.Detail = "Backups are generated for PostgreSQL databases only"
+		c.Fix = "Use \"database is PostgreSQL\""
+		return c
+	}
+	backup := app.Database.Backup
+	if backup.RetentionDays > 0 && backup.RetentionDays < profile.BackupRetentionDays {
+		c.Status = "fail"
+		c.Detail = fmt.Sprintf("Backups are kept for %d days; %s requires %d", backup.RetentionDays, profile.Name, profile.BackupRetentionDays)
+		c.Fix = fmt.Sprintf("Change the backup rule to \"keep backups for %d days\"", profile.BackupRetentionDays)
+		return c
+	}
+	c.Status = "pass"
+	c.Detail = "Database backed up " + backup.Description
+	return c
+}
+
+func complianceSecrets(app *ir.Application) ComplianceCheck {
+	c := ComplianceCheck{Requirement: "Secrets"}
+	var found []string
+	for _, f := range append(checkHardcodedSecrets(app), checkSecretPatterns(app)...) {
+		if f.Severity == "critical" {
+			found = append(found, f.Target)
+		}
+	}
+	if len(found) > 0 {
+		c.Status = "fail"
+		c.Detail = "Secrets are written in the .human file: " + strings.Join(found, ", ")
+		c.Fix = "Read them from environment variables instead"
+		return c
+	}
+	c.Status = "pass"
+	c.Detail = "No secrets in the .human file"
+	return c
+}
+
+// writeCompliance appends the compliance checks to the security report.
+func writeCompliance(b *strings.Builder, app *ir.Application) {
+	checks := CheckCompliance(app)
+	if checks == nil {
+		return
+	}
+	fmt.Fprintf(b, "## Compliance (%s)\n\n", ir.Compliance(app).Name)
+	b.WriteString("| Requirement | Status | Detail | Fix |\n")
+	b.WriteString("|-------------|--------|--------|-----|\n")
+	for _, c := range checks {
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n", c.Requirement, complianceStatusLabel(c.Status), c.Detail, c.Fix)
+	}
+	b.WriteString("\n")
+}
+
+func complianceStatusLabel(status string) string {
+	switch status {
+	case "fail":
+		return "❌ FAIL"
+	case "warn":
+		return "⚠ WARNING"
+	}
+	return "✅ PASS"
+}
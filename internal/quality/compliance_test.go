@@ -0,0 +1,106 @@
+package quality
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func complianceApp() *ir.Application {
+	app := authzApp()
+	app.Config = &ir.BuildConfig{Backend: "Node with Express", Database: "PostgreSQL", Deploy: "AWS", Compliance: "SOC2"}
+	app.Data = []*ir.DataModel{{Name: "Patient", Fields: []*ir.DataField{
+		{Name: "name", Type: "text"},
+		{Name: "phone", Type: "text", Encrypted: true},
+	}}}
+	app.Database = &ir.DatabaseConfig{Engine: "PostgreSQL", Backup: &ir.Backup{Description: "daily", RetentionDays: 30}}
+	app.Environments = []*ir.Environment{{Name: "production", Config: map[string]string{"url": "clinic.example.com"}}}
+	return app
+}
+
+func complianceStatus(checks []ComplianceCheck) map[string]string {
+	status := map[string]string{}
+	for _, c := range checks {
+		status[c.Requirement] = c.Status
+	}
+	return status
+}
+
+func TestCheckCompliancePasses(t *testing.T) {
+	checks := CheckCompliance(complianceApp())
+	for _, c := range checks {
+		if c.Status != "pass" {
+			t.Errorf("%s: got %s (%s)", c.Requirement, c.Status, c.Detail)
+		}
+	}
+	if ComplianceFailed(checks) {
+		t.Error("a compliant app should not fail")
+	}
+
+	app := complianceApp()
+	app.Config.Compliance = ""
+	if CheckCompliance(app) != nil {
+		t.Error("no profile should mean no checks")
+	}
+}
+
+func TestCheckComplianceFailures(t *testing.T) {
+	app := complianceApp()
+	app.APIs[0].Name = "ArchivePatient"
+	app.Data[0].Fields = append(app.Data[0].Fields,
+		&ir.DataField{Name: "email", Type: "email", Unique: true},
+		&ir.DataField{Name: "birth date", Type: "date"},
+	)
+	app.Database.Backup.RetentionDays = 7
+	app.Monitoring = []*ir.MonitoringRule{{Kind: "log", Duration: "90 days"}}
+	app.Environments = nil
+
+	checks := CheckCompliance(app)
+	if !ComplianceFailed(checks) {
+		t.Fatal("expected failures")
+	}
+	want := map[string]string{
+		"Authentication":        "fail",
+		"Access control":        "pass",
+		"Encryption at rest":    "fail",
+		"Encryption in transit": "fail",
+		"Log retention":         "fail",
+		"Backups":               "fail",
+	}
+	got := complianceStatus(checks)
+	for req, status := range want {
+		if got[req] != status {
+			t.Errorf("%s: got %s, want %s", req, got[req], status)
+		}
+	}
+
+	report := renderSecurityReport(app, nil)
+	for _, s := range []string{
+		"## Compliance (SOC2)",
+		"| Backups | ❌ FAIL | Backups are kept for 7 days; SOC2 requires 30 | Change the backup rule to \"keep backups for 30 days\" |",
+		"| Encryption at rest | ❌ FAIL | Personal data that isn't text can't be encrypted at rest: Patient.birth date |",
+	} {
+		if !strings.Contains(report, s) {
+			t.Errorf("security report missing %q", s)
+		}
+	}
+}
+
+func TestCheckComplianceWarnings(t *testing.T) {
+	app := complianceApp()
+	app.Config.Deploy = "Docker"
+	app.Data[0].Fields[1].Unique = true
+	app.Data[0].Fields[1].Encrypted = false
+
+	got := complianceStatus(CheckCompliance(app))
+	if got["Encryption at rest"] != "warn" || got["Log retention"] != "warn" {
+		t.Errorf("expected warnings, got %v", got)
+	}
+	app.Policies = nil
+	app.Config.Backend = "Ruby on Rails"
+	got = complianceStatus(CheckCompliance(app))
+	if got["Access control"] != "fail" || got["Audit logging"] != "fail" {
+		t.Errorf("expected failures, got %v", got)
+	}
+}
@@ -0,0 +1,435 @@
+package quality
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/barun-bash/human/internal/codegen"
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Contract is the API every backend built from an app must serve, whatever
+// language it's generated in: a list of HTTP requests derived from the
+// endpoints' parameters, validation rules, and auth flags, each with the
+// status it must answer. Running the same contract against the Node,
+// Python, and Go builds of an app shows where they behave differently.
+type Contract struct {
+	App   string          `json:"app"`
+	Cases []*ContractCase `json:"cases"`
+}
+
+// ContractCase is one request of a contract. Body values may contain
+// {{email}}, {{password}}, and {{future_date}}, filled in when the
+// contract runs so that each run signs up a new user.
+type ContractCase struct {
+	ID       string            `json:"id"`
+	Endpoint string            `json:"endpoint"`
+	Method   string            `json:"method"`
+	Path     string            `json:"path"`
+	Auth     bool              `json:"auth,omitempty"`  // sent with the signed-in user's token
+	Body     map[string]string `json:"body,omitempty"`  // the query string of a GET
+	Expect   string            `json:"expect"`          // "2xx", "<500", or an exact status
+	Token    bool              `json:"token,omitempty"` // the response carries the token later cases send
+}
+
+// ContractOutcome is how a backend answered one contract case.
+type ContractOutcome struct {
+	ID      string `json:"id"`
+	Status  int    `json:"status,omitempty"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// ContractResult is one run of a contract against a backend.
+type ContractResult struct {
+	Backend  string             `json:"backend"`
+	BaseURL  string             `json:"base_url"`
+	Ran      string             `json:"ran"`
+	Outcomes []*ContractOutcome `json:"outcomes"`
+}
+
+// Failed returns the cases the backend didn't answer as the contract says.
+func (r *ContractResult) Failed() []*ContractOutcome {
+	var failed []*ContractOutcome
+	for _, o := range r.Outcomes {
+		if !o.Passed && !o.Skipped {
+			failed = append(failed, o)
+		}
+	}
+	return failed
+}
+
+// Outcome returns the backend's outcome for a case, or nil if the run
+// didn't include it.
+func (r *ContractResult) Outcome(id string) *ContractOutcome {
+	for _, o := range r.Outcomes {
+		if o.ID == id {
+			return o
+		}
+	}
+	return nil
+}
+
+// generateContract derives the app's API contract: signing up and logging
+// in come first, so the cases after them can send the user's token. Each
+// endpoint must accept a valid request without a server error, answer 401
+// without a token if it requires auth, and answer 400 to a request that
+// breaks one of its validation rules. Rules that depend on stored data,
+// like unique, are left out, and so are file imports.
+func generateContract(app *ir.Application) *Contract {
+	c := &Contract{App: app.Name}
+	signUp := findEndpoint(app, "SignUp")
+	login := findEndpoint(app, "Login")
+
+	var ordered []*ir.Endpoint
+	for _, ep := range []*ir.Endpoint{signUp, login} {
+		if ep != nil {
+			ordered = append(ordered, ep)
+		}
+	}
+	for _, ep := range app.APIs {
+		if ep != signUp && ep != login && ep.Import == nil {
+			ordered = append(ordered, ep)
+		}
+	}
+
+	for _, ep := range ordered {
+		method := strings.ToUpper(httpMethod(ep.Name))
+		path := apiPath(ep.Name)
+		valid := map[string]string{}
+		for _, p := range ep.Params {
+			name := contractParamName(p.Name)
+			valid[name] = contractValue(ep, name)
+		}
+		newCase := func(desc, expect string, body map[string]string) *ContractCase {
+			return &ContractCase{
+				ID:       ep.Name + ": " + desc,
+				Endpoint: ep.Name,
+				Method:   method,
+				Path:     path,
+				Auth:     ep.Auth,
+				Body:     body,
+				Expect:   expect,
+			}
+		}
+
+		if ep.Auth {
+			cc := newCase("requires a token", "401", valid)
+			cc.Auth = false
+			c.Cases = append(c.Cases, cc)
+		}
+		switch ep {
+		case signUp:
+			cc := newCase("signs up a new user", "2xx", valid)
+			cc.Token = login == nil
+			c.Cases = append(c.Cases, cc)
+		case login:
+			cc := newCase("logs in and returns a token", "2xx", valid)
+			cc.Token = true
+			c.Cases = append(c.Cases, cc)
+		default:
+			c.Cases = append(c.Cases, newCase("accepts a valid request", "<500", valid))
+		}
+		for _, v := range ep.Validation {
+			name := contractParamName(v.Field)
+			invalid, ok := contractInvalidValue(v)
+			if _, isParam := valid[name]; !ok || !isParam {
+				continue
+			}
+			body := map[string]string{}
+			for k, val := range valid {
+				body[k] = val
+			}
+			body[name] = invalid
+			// "should reject empty title" → "rejects empty title"
+			verb, rest, _ := strings.Cut(strings.TrimPrefix(validationTestDesc(v), "should "), " ")
+			c.Cases = append(c.Cases, newCase(verb+"s "+rest, "400", body))
+		}
+	}
+	return c
+}
+
+// contractParamName returns the name a request sends a parameter under,
+// the one the frontends and the Node backend use: "due date" → "dueDate".
+func contractParamName(name string) string {
+	words := strings.Fields(name)
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	return strings.Join(words, "")
+}
+
+// contractValue returns a value for an endpoint parameter that passes its
+// validation rules.
+func contractValue(ep *ir.Endpoint, param string) string {
+	lower := strings.ToLower(param)
+	minLen, maxLen := 0, 0
+	for _, v := range ep.Validation {
+		if contractParamName(v.Field) != param {
+			continue
+		}
+		switch v.Rule {
+		case "valid_email":
+			lower = "email"
+		case "future_date":
+			return "{{future_date}}"
+		case "min_length":
+			minLen, _ = strconv.Atoi(v.Value)
+		case "max_length":
+			maxLen, _ = strconv.Atoi(v.Value)
+		}
+	}
+	switch {
+	case strings.Contains(lower, "email"):
+		return "{{email}}"
+	case strings.Contains(lower, "password"):
+		return "{{password}}"
+	case strings.Contains(lower, "date") || strings.HasPrefix(lower, "due"):
+		return "{{future_date}}"
+	}
+	value := "Contract " + param
+	if len(value) < minLen {
+		value += strings.Repeat("x", minLen-len(value))
+	}
+	if maxLen > 0 && len(value) > maxLen {
+		value = value[:maxLen]
+	}
+	return value
+}
+
+// contractInvalidValue returns a value that breaks a validation rule, or
+// false for rules a single request can't break.
+func contractInvalidValue(v *ir.ValidationRule) (string, bool) {
+	n, _ := strconv.Atoi(v.Value)
+	switch v.Rule {
+	case "not_empty":
+		return "", true
+	case "valid_email":
+		return "not-an-email", true
+	case "min_length":
+		if n < 2 {
+			return "", n == 1
+		}
+		return strings.Repeat("x", n-1), true
+	case "max_length":
+		return strings.Repeat("x", n+1), n > 0
+	case "future_date":
+		return "2020-01-01", true
+	}
+	return "", false
+}
+
+// ContractBackend names the backend an app builds: "node", "python", or
+// "go".
+func ContractBackend(app *ir.Application) string {
+	if app.Config == nil {
+		return "node"
+	}
+	lower := strings.ToLower(app.Config.Backend)
+	switch {
+	case strings.Contains(lower, "python") || strings.Contains(lower, "fastapi"):
+		return "python"
+	case codegen.MatchesGoBackend(app.Config.Backend):
+		return "go"
+	}
+	return "node"
+}
+
+// ContractBaseURL returns the URL the backend is served at when the app
+// runs under docker-compose, which the contract runs against.
+func ContractBaseURL(app *ir.Application) string {
+	return "http://localhost:" + docker.BackendPort(app)
+}
+
+// LoadContract reads the contract a build wrote to outputDir.
+func LoadContract(outputDir string) (*Contract, error) {
+	data, err := os.ReadFile(filepath.Join(outputDir, "contract", "contract.json"))
+	if err != nil {
+		return nil, fmt.Errorf("no API contract in %s (it's generated for apps with APIs)", outputDir)
+	}
+	var c Contract
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("reading contract.json: %w", err)
+	}
+	return &c, nil
+}
+
+// RunContract sends the contract's requests, in order, to the backend at
+// baseURL and checks each status against the contract. Cases that need a
+// token are skipped when no earlier case returned one.
+func RunContract(c *Contract, backend, baseURL string, client *http.Client) *ContractResult {
+	now := time.Now()
+	fill := strings.NewReplacer(
+		"{{email}}", fmt.Sprintf("contract-%s@example.com", strconv.FormatInt(now.UnixNano(), 36)),
+		"{{password}}", "Contract-Password-1",
+		"{{future_date}}", now.AddDate(1, 0, 0).Format("2006-01-02"),
+	)
+	result := &ContractResult{Backend: backend, BaseURL: baseURL, Ran: now.UTC().Format(time.RFC3339)}
+	token := ""
+	for _, cc := range c.Cases {
+		o := &ContractOutcome{ID: cc.ID}
+		result.Outcomes = append(result.Outcomes, o)
+		if cc.Auth && token == "" {
+			o.Skipped = true
+			o.Detail = "no token: signing in failed or the app has no login"
+			continue
+		}
+
+		req, err := contractRequest(cc, strings.TrimRight(baseURL, "/"), fill)
+		if err != nil {
+			o.Detail = err.Error()
+			continue
+		}
+		if cc.Auth {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			o.Detail = err.Error()
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+
+		o.Status = resp.StatusCode
+		o.Passed = statusMatches(cc.Expect, resp.StatusCode)
+		if !o.Passed {
+			o.Detail = fmt.Sprintf("expected %s, got %d", cc.Expect, resp.StatusCode)
+		}
+		if cc.Token && o.Passed {
+			var payload struct {
+				Token string `json:"token"`
+			}
+			json.Unmarshal(body, &payload)
+			if payload.Token == "" {
+				o.Passed = false
+				o.Detail = "the response has no token"
+			}
+			token = payload.Token
+		}
+	}
+	return result
+}
+
+// contractRequest builds a case's request: a GET sends its parameters in
+// the query string, other methods as a JSON body.
+func contractRequest(cc *ContractCase, baseURL string, fill *strings.Replacer) (*http.Request, error) {
+	body := map[string]string{}
+	for k, v := range cc.Body {
+		body[k] = fill.Replace(v)
+	}
+	if cc.Method == http.MethodGet {
+		query := url.Values{}
+		for k, v := range body {
+			query.Set(k, v)
+		}
+		target := baseURL + cc.Path
+		if len(query) > 0 {
+			target += "?" + query.Encode()
+		}
+		return http.NewRequest(cc.Method, target, nil)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(cc.Method, baseURL+cc.Path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// statusMatches reports whether status meets a case's expectation.
+func statusMatches(expect string, status int) bool {
+	switch expect {
+	case "2xx":
+		return status >= 200 && status < 300
+	case "<500":
+		return status < 500
+	}
+	want, err := strconv.Atoi(expect)
+	return err == nil && status == want
+}
+
+// SaveContractResult writes a backend's latest contract run to
+// dir/<backend>.json, replacing its previous one.
+func SaveContractResult(dir string, r *ContractResult) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, r.Backend+".json"), append(data, '\n'), 0644)
+}
+
+// LoadContractResults reads the latest contract run of each backend saved
+// in dir, sorted by backend.
+func LoadContractResults(dir string) []*ContractResult {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	sort.Strings(matches)
+	var results []*ContractResult
+	for _, m := range matches {
+		data, err := os.ReadFile(m)
+		if err != nil {
+			continue
+		}
+		var r ContractResult
+		if json.Unmarshal(data, &r) == nil && r.Backend != "" {
+			results = append(results, &r)
+		}
+	}
+	return results
+}
+
+// ContractDivergent reports whether the backends that ran a case answered
+// it with different statuses.
+func ContractDivergent(id string, results []*ContractResult) bool {
+	status := -1
+	for _, r := range results {
+		o := r.Outcome(id)
+		if o == nil || o.Skipped {
+			continue
+		}
+		if status >= 0 && o.Status != status {
+			return true
+		}
+		status = o.Status
+	}
+	return false
+}
+
+// writeContract writes the contract to dir/contract.json and returns its
+// case count.
+func writeContract(app *ir.Application, dir string) (int, error) {
+	if len(app.APIs) == 0 {
+		return 0, nil
+	}
+	c := generateContract(app)
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "contract.json"), append(data, '\n'), 0644); err != nil {
+		return 0, err
+	}
+	return len(c.Cases), nil
+}
@@ -0,0 +1,184 @@
+package quality
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func contractApp() *ir.Application {
+	return &ir.Application{
+		Name: "TaskFlow",
+		APIs: []*ir.Endpoint{
+			{Name: "CreateTask", Auth: true,
+				Params: []*ir.Param{{Name: "title"}, {Name: "due date"}},
+				Validation: []*ir.ValidationRule{
+					{Field: "title", Rule: "not_empty"},
+					{Field: "title", Rule: "max_length", Value: "20"},
+					{Field: "title", Rule: "unique"},
+					{Field: "due date", Rule: "future_date"},
+				}},
+			{Name: "Login", Params: []*ir.Param{{Name: "email"}, {Name: "password"}}},
+			{Name: "SignUp", Params: []*ir.Param{{Name: "email"}, {Name: "password"}},
+				Validation: []*ir.ValidationRule{{Field: "email", Rule: "valid_email"}}},
+		},
+	}
+}
+
+func TestGenerateContract(t *testing.T) {
+	c := generateContract(contractApp())
+
+	var ids []string
+	for _, cc := range c.Cases {
+		ids = append(ids, cc.ID)
+	}
+	want := []string{
+		"SignUp: signs up a new user",
+		"SignUp: rejects invalid email",
+		"Login: logs in and returns a token",
+		"CreateTask: requires a token",
+		"CreateTask: accepts a valid request",
+		"CreateTask: rejects empty title",
+		"CreateTask: rejects title longer than 20 characters",
+		"CreateTask: rejects past due date",
+	}
+	if strings.Join(ids, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("cases:\n%s\nwant:\n%s", strings.Join(ids, "\n"), strings.Join(want, "\n"))
+	}
+
+	login, noToken, valid, tooLong := c.Cases[2], c.Cases[3], c.Cases[4], c.Cases[6]
+	if !login.Token || login.Method != "POST" || login.Path != "/api/login" {
+		t.Errorf("login case = %+v", login)
+	}
+	if noToken.Auth || noToken.Expect != "401" {
+		t.Errorf("requires-a-token case = %+v", noToken)
+	}
+	if !valid.Auth || valid.Expect != "<500" || valid.Body["dueDate"] != "{{future_date}}" || valid.Body["title"] != "Contract title" {
+		t.Errorf("valid case = %+v", valid)
+	}
+	if tooLong.Expect != "400" || len(tooLong.Body["title"]) != 21 || tooLong.Body["dueDate"] != "{{future_date}}" {
+		t.Errorf("max_length case = %+v", tooLong)
+	}
+}
+
+func TestRunContract(t *testing.T) {
+	var created map[string]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/sign-up", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if !strings.Contains(body["email"], "@") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("POST /api/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"token": "t0ken"}`))
+	})
+	mux.HandleFunc("POST /api/task", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0ken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&created)
+		// Validates only that the title isn't empty, and answers 422.
+		if created["title"] == "" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := generateContract(contractApp())
+	result := RunContract(c, "node", server.URL, server.Client())
+
+	failed := map[string]int{}
+	for _, o := range result.Failed() {
+		failed[o.ID] = o.Status
+	}
+	want := map[string]int{
+		"CreateTask: rejects empty title":                     422,
+		"CreateTask: rejects title longer than 20 characters": 200,
+		"CreateTask: rejects past due date":                   200,
+	}
+	if len(failed) != len(want) {
+		t.Errorf("failed = %v, want %v", failed, want)
+	}
+	for id, status := range want {
+		if failed[id] != status {
+			t.Errorf("%s: status %d, want %d", id, failed[id], status)
+		}
+	}
+	if strings.Contains(created["dueDate"], "{{") || !strings.HasPrefix(created["dueDate"], "20") {
+		t.Errorf("placeholders not filled in: %v", created)
+	}
+}
+
+func TestRunContract_NoTokenSkipsAuthCases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	result := RunContract(generateContract(contractApp()), "go", server.URL, server.Client())
+	if o := result.Outcome("Login: logs in and returns a token"); o.Passed || o.Detail != "the response has no token" {
+		t.Errorf("login outcome = %+v", o)
+	}
+	if o := result.Outcome("CreateTask: accepts a valid request"); !o.Skipped {
+		t.Errorf("expected the authenticated case to be skipped, got %+v", o)
+	}
+	if o := result.Outcome("CreateTask: requires a token"); o.Passed || o.Status != 200 {
+		t.Errorf("requires-a-token outcome = %+v", o)
+	}
+}
+
+func TestStatusMatches(t *testing.T) {
+	for _, tc := range []struct {
+		expect string
+		status int
+		want   bool
+	}{
+		{"2xx", 201, true},
+		{"2xx", 302, false},
+		{"<500", 404, true},
+		{"<500", 500, false},
+		{"400", 400, true},
+		{"400", 422, false},
+	} {
+		if got := statusMatches(tc.expect, tc.status); got != tc.want {
+			t.Errorf("statusMatches(%q, %d) = %v", tc.expect, tc.status, got)
+		}
+	}
+}
+
+func TestContractResults(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "contract")
+	for _, r := range []*ContractResult{
+		{Backend: "python", Outcomes: []*ContractOutcome{{ID: "a", Status: 422}, {ID: "b", Status: 401, Passed: true}}},
+		{Backend: "node", Outcomes: []*ContractOutcome{{ID: "a", Status: 400, Passed: true}, {ID: "b", Status: 401, Passed: true}}},
+	} {
+		if err := SaveContractResult(dir, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := LoadContractResults(dir)
+	if len(results) != 2 || results[0].Backend != "node" || results[1].Backend != "python" {
+		t.Fatalf("results = %v", results)
+	}
+	if !ContractDivergent("a", results) {
+		t.Error("expected case a to differ between backends")
+	}
+	if ContractDivergent("b", results) {
+		t.Error("case b answered the same by both backends")
+	}
+	if failed := results[1].Failed(); len(failed) != 1 || failed[0].ID != "a" {
+		t.Errorf("python failed = %v", failed)
+	}
+}
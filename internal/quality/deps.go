@@ -1,21 +1,30 @@
 package quality
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/barun-bash/human/internal/config"
 )
 
+// npmTimeout bounds each npm call in the dependency scan, so a machine
+// without network access skips the scan instead of stalling the build.
+var npmTimeout = 90 * time.Second
+
 // VulnerabilityReport holds the results of a dependency vulnerability scan.
 type VulnerabilityReport struct {
-	Total    int
-	Critical int
-	High     int
-	Moderate int
-	Low      int
+	Total      int
+	Critical   int
+	High       int
+	Moderate   int
+	Low        int
 	Advisories []Advisory
 }
 
@@ -43,8 +52,8 @@ type npmAuditOutput struct {
 }
 
 type npmVuln struct {
-	Name     string `json:"name"`
-	Severity string `json:"severity"`
+	Name     string            `json:"name"`
+	Severity string            `json:"severity"`
 	Via      []json.RawMessage `json:"via"`
 }
 
@@ -55,8 +64,14 @@ type npmViaAdvisory struct {
 }
 
 // ScanDependencies runs npm audit against the generated package.json.
-// Returns nil gracefully if npm is not installed or no package.json exists.
+// Returns nil gracefully if npm is not installed, no package.json exists,
+// the build is offline, or the registry can't be reached in time.
 func ScanDependencies(outputDir string) (*VulnerabilityReport, error) {
+	// npm audit queries the registry.
+	if config.Offline(".") {
+		return nil, nil
+	}
+
 	// Check for npm
 	npmPath, err := exec.LookPath("npm")
 	if err != nil {
@@ -71,7 +86,9 @@ func ScanDependencies(outputDir string) (*VulnerabilityReport, error) {
 	}
 
 	// Generate package-lock.json (needed for npm audit)
-	lockCmd := exec.Command(npmPath, "install", "--package-lock-only", "--prefix", pkgDir)
+	ctx, cancel := context.WithTimeout(context.Background(), npmTimeout)
+	defer cancel()
+	lockCmd := exec.CommandContext(ctx, npmPath, "install", "--package-lock-only", "--prefix", pkgDir)
 	lockCmd.Stderr = nil
 	lockCmd.Stdout = nil
 	_ = lockCmd.Run() // best-effort; audit may still work
@@ -83,8 +100,16 @@ func ScanDependencies(outputDir string) (*VulnerabilityReport, error) {
 	}()
 
 	// Run npm audit
-	auditCmd := exec.Command(npmPath, "audit", "--json", "--prefix", pkgDir)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, nil // registry unreachable, skip gracefully
+	}
+	auditCtx, auditCancel := context.WithTimeout(context.Background(), npmTimeout)
+	defer auditCancel()
+	auditCmd := exec.CommandContext(auditCtx, npmPath, "audit", "--json", "--prefix", pkgDir)
 	output, err := auditCmd.Output()
+	if errors.Is(auditCtx.Err(), context.DeadlineExceeded) {
+		return nil, nil
+	}
 
 	// npm audit returns non-zero exit code when vulnerabilities are found,
 	// but still produces valid JSON. Only fail on truly empty output.
@@ -147,7 +172,7 @@ func renderDependencyAudit(report *VulnerabilityReport) string {
 	b.WriteString("Generated by Human compiler quality engine.\n\n")
 
 	if report == nil {
-		b.WriteString("Dependency scanning was skipped (npm not available or no package.json).\n")
+		b.WriteString("Dependency scanning was skipped (npm not available, no package.json, or offline).\n")
 		return b.String()
 	}
 
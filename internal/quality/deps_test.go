@@ -1,6 +1,8 @@
 package quality
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -203,3 +205,22 @@ func TestRenderDependencySection_Nil(t *testing.T) {
 		t.Error("expected skip message for nil report")
 	}
 }
+
+func TestScanDependencies_OfflineSkips(t *testing.T) {
+	t.Setenv("HUMAN_OFFLINE", "1")
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "node"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "node", "package.json"), []byte(`{"name":"app"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := ScanDependencies(dir)
+	if err != nil || report != nil {
+		t.Fatalf("offline scan = %v, %v; want skipped (nil, nil)", report, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "node", "package-lock.json")); err == nil {
+		t.Error("offline scan should not run npm")
+	}
+}
@@ -0,0 +1,540 @@
+package quality
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/barun-bash/human/internal/codegen/docker"
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// generateE2ETests creates Playwright specs for each page in e2eDir, with
+// the Playwright config and helpers they share. The specs drive the app
+// in a browser as docker-compose runs it: each page loads, its links
+// navigate, its forms submit, and its lists render items and their empty
+// state. Pages reached by id (detail routes) are left out, since there's
+// no record to visit. Returns (fileCount, testCount, error).
+func generateE2ETests(app *ir.Application, e2eDir string) (int, int, error) {
+	if !hasWebFrontend(app) || len(app.Pages) == 0 {
+		return 0, 0, nil
+	}
+	if err := os.MkdirAll(e2eDir, 0755); err != nil {
+		return 0, 0, err
+	}
+
+	files := map[string]string{
+		"package.json":         generateE2EPackageJSON(app),
+		"playwright.config.ts": generatePlaywrightConfig(app),
+		"helpers.ts":           generateE2EHelpers(app),
+	}
+	totalTests := 0
+	for _, page := range app.Pages {
+		if ir.DetailRouteFor(app, page.Name) != nil {
+			continue
+		}
+		content, testCount := generatePageE2E(page, app)
+		files[toKebabCase(page.Name)+".spec.ts"] = content
+		totalTests += testCount
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(e2eDir, name), []byte(content), 0644); err != nil {
+			return 0, 0, err
+		}
+	}
+	return len(files) - 3, totalTests, nil
+}
+
+// hasWebFrontend reports whether the app has a frontend a browser can
+// test.
+func hasWebFrontend(app *ir.Application) bool {
+	if app.Config == nil {
+		return false
+	}
+	fe := strings.ToLower(app.Config.Frontend)
+	for _, name := range []string{"react", "vue", "svelte", "angular"} {
+		if strings.Contains(fe, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// E2EBaseURL returns the URL the frontend is served at when the app runs
+// under docker-compose, which the end-to-end tests visit.
+func E2EBaseURL(app *ir.Application) string {
+	return "http://localhost:" + docker.FrontendPort(app)
+}
+
+func generateE2EPackageJSON(app *ir.Application) string {
+	return fmt.Sprintf(`{
+  "name": "%s-e2e",
+  "private": true,
+  "scripts": {
+    "test": "playwright test"
+  },
+  "devDependencies": {
+    "@playwright/test": "^1.48.0"
+  }
+}
+`, toKebabCase(strings.ReplaceAll(app.Name, " ", "")))
+}
+
+func generatePlaywrightConfig(app *ir.Application) string {
+	return fmt.Sprintf(`// Generated by Human compiler — do not edit
+
+import { defineConfig, devices } from '@playwright/test';
+
+// The app as docker-compose runs it. Set E2E_BASE_URL to test another
+// deployment; human test --e2e sets it after booting the stack itself.
+const baseURL = process.env.E2E_BASE_URL || '%s';
+
+export default defineConfig({
+  testDir: '.',
+  timeout: 30_000,
+  retries: process.env.CI ? 1 : 0,
+  reporter: [['list'], ['html', { open: 'never' }]],
+  use: {
+    baseURL,
+    trace: 'retain-on-failure',
+  },
+  projects: [{ name: 'chromium', use: { ...devices['Desktop Chrome'] } }],
+  webServer: process.env.E2E_BASE_URL ? undefined : {
+    command: 'docker compose -f ../docker-compose.yml up --build',
+    url: baseURL,
+    reuseExistingServer: true,
+    timeout: 300_000,
+  },
+});
+`, E2EBaseURL(app))
+}
+
+// generateE2EHelpers writes the specs' shared helpers: fillForm, and,
+// when the app has sign-up and login endpoints, signIn.
+func generateE2EHelpers(app *ir.Application) string {
+	var b strings.Builder
+	b.WriteString("// Generated by Human compiler — do not edit\n\n")
+	b.WriteString("import { expect, type Locator, type Page } from '@playwright/test';\n\n")
+
+	b.WriteString(`// fillForm fills each visible field of a form with a valid value for its
+// type, and returns the values entered by field name.
+export async function fillForm(form: Locator): Promise<Record<string, string>> {
+  const values: Record<string, string> = {};
+  const suffix = Date.now().toString(36);
+  for (const field of await form.locator('input, textarea, select').all()) {
+    if (!(await field.isVisible()) || !(await field.isEditable())) continue;
+    const name = (await field.getAttribute('name')) || (await field.getAttribute('id')) || 'field';
+    if ((await field.evaluate((el) => el.tagName)) === 'SELECT') {
+      const options = await field.locator('option').evaluateAll((opts) =>
+        opts.map((o) => (o as HTMLOptionElement).value).filter(Boolean));
+      if (options.length > 0) {
+        await field.selectOption(options[0]);
+        values[name] = options[0];
+      }
+      continue;
+    }
+    let value: string;
+    switch ((await field.getAttribute('type')) || 'text') {
+      case 'checkbox':
+      case 'radio':
+        await field.check();
+        continue;
+      case 'file':
+      case 'hidden':
+      case 'submit':
+      case 'button':
+        continue;
+      case 'email': value = ` + "`e2e-${suffix}@example.com`" + `; break;
+      case 'password': value = 'e2e-Password-1'; break;
+      case 'number': value = '1'; break;
+      case 'date': value = '2030-01-01'; break;
+      case 'datetime-local': value = '2030-01-01T09:00'; break;
+      case 'url': value = 'https://example.com'; break;
+      default: value = ` + "`E2E ${name} ${suffix}`" + `;
+    }
+    await field.fill(value);
+    values[name] = value;
+  }
+  return values;
+}
+`)
+
+	signUp, login := findEndpoint(app, "SignUp"), findEndpoint(app, "Login")
+	if signUp == nil || login == nil {
+		return b.String()
+	}
+	b.WriteString("\n// signIn signs up a new user through the API and stores its token where\n")
+	b.WriteString("// the frontend keeps it, so protected pages render.\n")
+	b.WriteString("export async function signIn(page: Page): Promise<void> {\n")
+	b.WriteString("  const email = `e2e-${Date.now().toString(36)}@example.com`;\n")
+	b.WriteString("  const password = 'e2e-Password-1';\n")
+	fmt.Fprintf(&b, "  const signUp = await page.request.post('%s', { data: { %s } });\n", apiPath(signUp.Name), e2eCredentials(signUp))
+	b.WriteString("  expect(signUp.status()).toBeLessThan(400);\n")
+	fmt.Fprintf(&b, "  const login = await page.request.post('%s', { data: { %s } });\n", apiPath(login.Name), e2eCredentials(login))
+	b.WriteString("  expect(login.ok()).toBeTruthy();\n")
+	b.WriteString("  const { token } = await login.json();\n")
+	b.WriteString("  await page.addInitScript((t) => localStorage.setItem('token', t), token);\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// e2eCredentials writes an auth endpoint's request body, using signIn's
+// email and password variables.
+func e2eCredentials(ep *ir.Endpoint) string {
+	var fields []string
+	for _, p := range ep.Params {
+		name := sanitizeParamName(p.Name)
+		switch lower := strings.ToLower(name); {
+		case lower == "email":
+			fields = append(fields, "email")
+		case lower == "password":
+			fields = append(fields, "password")
+		default:
+			fields = append(fields, fmt.Sprintf("%s: 'E2E %s'", name, name))
+		}
+	}
+	return strings.Join(fields, ", ")
+}
+
+// generatePageE2E produces the Playwright spec for a single page.
+// Returns the file content and the number of tests.
+func generatePageE2E(page *ir.Page, app *ir.Application) (string, int) {
+	var b strings.Builder
+	testCount := 0
+	path := pageRoute(page.Name)
+	canSignIn := findEndpoint(app, "SignUp") != nil && findEndpoint(app, "Login") != nil
+	protected := isProtectedPage(app, page)
+
+	fmt.Fprintf(&b, "test.describe('%s page', () => {\n", escapeJSString(page.Name))
+
+	// Without a way to sign in, a protected page can only be checked to
+	// send visitors to the login page.
+	if protected && !canSignIn {
+		b.WriteString("  test('redirects visitors to log in', async ({ page }) => {\n")
+		fmt.Fprintf(&b, "    await page.goto('%s');\n", path)
+		b.WriteString("    await expect(page).toHaveURL(/\\/login$/);\n")
+		b.WriteString("  });\n")
+		b.WriteString("});\n")
+		return e2eSpecHeader(b.String()) + b.String(), 1
+	}
+
+	b.WriteString("  test.beforeEach(async ({ page }) => {\n")
+	if protected {
+		b.WriteString("    await signIn(page);\n")
+	}
+	fmt.Fprintf(&b, "    await page.goto('%s');\n", path)
+	b.WriteString("  });\n\n")
+
+	b.WriteString("  test('loads', async ({ page }) => {\n")
+	fmt.Fprintf(&b, "    await expect(page).toHaveURL(%s);\n", urlPattern(path))
+	b.WriteString("    await expect(page.getByText('Page not found')).toHaveCount(0);\n")
+	b.WriteString("  });\n")
+	testCount++
+
+	model := pageModel(page, app)
+	list := listEndpoint(app, model)
+	formOpened, listed := false, false
+	for _, action := range page.Content {
+		lower := strings.ToLower(action.Text)
+		switch action.Type {
+		case "interact":
+			if !strings.Contains(lower, "click") {
+				continue
+			}
+			label := e2eButtonLabel(action.Text)
+			if target := navTarget(app, action.Text); target != nil && label != "" {
+				targetPath := pageRoute(target.Name)
+				fmt.Fprintf(&b, "\n  test('clicking %s navigates to %s', async ({ page }) => {\n", escapeJSString(label), target.Name)
+				if isProtectedPage(app, target) && canSignIn && !protected {
+					b.WriteString("    await signIn(page);\n")
+					b.WriteString("    await page.reload();\n")
+				}
+				b.WriteString("    // The button with the click handler is rendered after any shown one.\n")
+				fmt.Fprintf(&b, "    await page.getByRole('button', { name: /%s/i }).last().click();\n", escapeRegex(label))
+				if isProtectedPage(app, target) && !canSignIn {
+					b.WriteString("    await expect(page).toHaveURL(/\\/login$/);\n")
+				} else {
+					fmt.Fprintf(&b, "    await expect(page).toHaveURL(%s);\n", urlPattern(targetPath))
+				}
+				b.WriteString("  });\n")
+				testCount++
+			} else if (strings.Contains(lower, "opens a form") || strings.Contains(lower, "open a form")) && label != "" {
+				formOpened = true
+				fmt.Fprintf(&b, "\n  test('clicking %s opens a form that submits', async ({ page }) => {\n", escapeJSString(label))
+				fmt.Fprintf(&b, "    await page.getByRole('button', { name: /%s/i }).last().click();\n", escapeRegex(label))
+				b.WriteString("    const form = page.locator('form').last();\n")
+				b.WriteString("    await expect(form).toBeVisible();\n")
+				field := shownTextField(page, model)
+				if field != "" {
+					b.WriteString("    const values = await fillForm(form);\n")
+				} else {
+					b.WriteString("    await fillForm(form);\n")
+				}
+				b.WriteString("    await form.locator('[type=submit]').click();\n")
+				if field != "" {
+					b.WriteString("    // The new record shows in the list.\n")
+					fmt.Fprintf(&b, "    await expect(page.getByText(values['%s'])).toBeVisible();\n", field)
+				} else {
+					b.WriteString("    await expect(page.locator('.field-error')).toHaveCount(0);\n")
+				}
+				b.WriteString("  });\n")
+				testCount++
+			}
+
+		case "input":
+			if !strings.Contains(lower, "form") || formOpened {
+				continue
+			}
+			formOpened = true
+			b.WriteString("\n  test('submits the form', async ({ page }) => {\n")
+			b.WriteString("    const form = page.locator('form').first();\n")
+			b.WriteString("    await fillForm(form);\n")
+			b.WriteString("    await form.locator('[type=submit]').click();\n")
+			b.WriteString("    await expect(page.locator('.field-error')).toHaveCount(0);\n")
+			if hasSuccessCondition(page) {
+				b.WriteString("    await expect(page.getByRole('status')).not.toBeEmpty();\n")
+			}
+			b.WriteString("  });\n")
+			testCount++
+
+		case "loop":
+			field := shownTextField(page, model)
+			if list == nil || field == "" || listed {
+				continue
+			}
+			listed = true
+			fmt.Fprintf(&b, "\n  test('lists each %s', async ({ page }) => {\n", strings.ToLower(model.Name))
+			writeListRoute(&b, list, fakeRecord(model))
+			b.WriteString("    await page.reload();\n")
+			fmt.Fprintf(&b, "    await expect(page.getByText('E2E %s')).toBeVisible();\n", field)
+			b.WriteString("  });\n")
+			testCount++
+
+		case "condition":
+			if list == nil || !strings.Contains(lower, "no ") || !(strings.Contains(lower, "match") || strings.Contains(lower, "found")) {
+				continue
+			}
+			b.WriteString("\n  test('shows the empty state', async ({ page }) => {\n")
+			writeListRoute(&b, list, "")
+			b.WriteString("    await page.reload();\n")
+			if quoted := extractQuoted(action.Text); quoted != "" {
+				fmt.Fprintf(&b, "    await expect(page.getByText('%s')).toBeVisible();\n", escapeJSString(quoted))
+			} else {
+				b.WriteString("    await expect(page.locator('.empty-state')).toBeVisible();\n")
+			}
+			b.WriteString("  });\n")
+			testCount++
+		}
+	}
+
+	b.WriteString("});\n")
+	return e2eSpecHeader(b.String()) + b.String(), testCount
+}
+
+// e2eSpecHeader writes a spec's imports, with the helpers its tests use.
+func e2eSpecHeader(tests string) string {
+	var helpers []string
+	for _, name := range []string{"fillForm", "signIn"} {
+		if strings.Contains(tests, name+"(") {
+			helpers = append(helpers, name)
+		}
+	}
+	header := "// Generated by Human compiler — do not edit\n\n"
+	header += "import { test, expect } from '@playwright/test';\n"
+	if len(helpers) > 0 {
+		header += fmt.Sprintf("import { %s } from './helpers';\n", strings.Join(helpers, ", "))
+	}
+	return header + "\n"
+}
+
+// writeListRoute answers the page's list request with record, or with
+// no records when record is "", so a test controls what the list shows.
+func writeListRoute(b *strings.Builder, list *ir.Endpoint, record string) {
+	fmt.Fprintf(b, "    await page.route((url) => url.pathname === '%s', (route) =>\n", apiPath(list.Name))
+	b.WriteString("      route.request().method() === 'GET'\n")
+	fmt.Fprintf(b, "        ? route.fulfill({ json: { data: [%s], pagination: { nextCursor: null } } })\n", record)
+	b.WriteString("        : route.continue());\n")
+}
+
+// pageRoute returns the path a page is served at: "/" for Home, its name
+// in kebab case otherwise.
+func pageRoute(name string) string {
+	if strings.EqualFold(name, "home") {
+		return "/"
+	}
+	return "/" + toKebabCase(name)
+}
+
+// urlPattern returns a regex literal matching a URL that ends at path.
+func urlPattern(path string) string {
+	if path == "/" {
+		return `/\/$/`
+	}
+	return "/" + strings.ReplaceAll(path, "/", `\/`) + "$/"
+}
+
+// isProtectedPage reports whether visitors must sign in to see a page, as
+// the frontends' routers decide.
+func isProtectedPage(app *ir.Application, page *ir.Page) bool {
+	if app.Auth == nil || ir.IsPricingPage(app, page) {
+		return false
+	}
+	if ir.IsInvitePage(app, page) && page.Name == app.Accounts.Invite.Accept {
+		return false
+	}
+	switch strings.ToLower(page.Name) {
+	case "home", "login", "signup", "sign-up", "register", "landing":
+		return false
+	}
+	return true
+}
+
+// navTarget returns the page a click navigates to, if it names one.
+func navTarget(app *ir.Application, text string) *ir.Page {
+	lower := strings.ToLower(text)
+	for _, marker := range []string{"navigates to ", "navigate to ", "go to ", "goes to "} {
+		idx := strings.Index(lower, marker)
+		if idx == -1 {
+			continue
+		}
+		name := strings.Fields(text[idx+len(marker):])
+		if len(name) == 0 {
+			return nil
+		}
+		for _, p := range app.Pages {
+			if strings.EqualFold(p.Name, strings.Trim(name[0], ".,")) {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// e2eButtonLabel returns the label of the button a click action is on:
+// its quoted text, or the words between "clicking the" and the verb.
+func e2eButtonLabel(text string) string {
+	if q := extractQuoted(text); q != "" {
+		return q
+	}
+	lower := strings.ToLower(text)
+	idx := strings.Index(lower, "clicking ")
+	if idx == -1 {
+		return ""
+	}
+	var words []string
+	for _, w := range strings.Fields(text[idx+len("clicking "):]) {
+		wl := strings.ToLower(w)
+		if wl == "the" && len(words) == 0 {
+			continue
+		}
+		if wl == "button" || wl == "link" || wl == "opens" || wl == "navigates" || wl == "updates" || wl == "triggers" {
+			break
+		}
+		words = append(words, w)
+	}
+	// "clicking a task" is an item in a list, not a button.
+	if len(words) == 0 || strings.EqualFold(words[0], "a") {
+		return ""
+	}
+	return strings.Join(words, " ")
+}
+
+// extractQuoted returns the first double-quoted text in s.
+func extractQuoted(s string) string {
+	start := strings.Index(s, "\"")
+	if start == -1 {
+		return ""
+	}
+	end := strings.Index(s[start+1:], "\"")
+	if end == -1 {
+		return ""
+	}
+	return s[start+1 : start+1+end]
+}
+
+// pageModel returns the data model a page lists, named in its loop or
+// query.
+func pageModel(page *ir.Page, app *ir.Application) *ir.DataModel {
+	for _, a := range page.Content {
+		if a.Type != "loop" && a.Type != "query" {
+			continue
+		}
+		lower := strings.ToLower(a.Text)
+		for _, m := range app.Data {
+			if strings.Contains(lower, strings.ToLower(m.Name)) {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// listEndpoint returns the endpoint that lists a model, e.g. GetTasks.
+func listEndpoint(app *ir.Application, model *ir.DataModel) *ir.Endpoint {
+	if model == nil {
+		return nil
+	}
+	for _, plural := range []string{model.Name + "s", model.Name + "es", strings.TrimSuffix(model.Name, "y") + "ies"} {
+		if ep := findEndpoint(app, "Get"+plural); ep != nil {
+			return ep
+		}
+	}
+	return nil
+}
+
+// shownTextField returns the first text field of model that the page's
+// loop shows, e.g. "title" for "each task shows its title and status".
+func shownTextField(page *ir.Page, model *ir.DataModel) string {
+	if model == nil {
+		return ""
+	}
+	for _, a := range page.Content {
+		if a.Type != "loop" {
+			continue
+		}
+		lower := strings.ToLower(a.Text)
+		for _, f := range model.Fields {
+			if f.Type == "text" && strings.Contains(lower, strings.ToLower(f.Name)) {
+				return sanitizeParamName(f.Name)
+			}
+		}
+	}
+	return ""
+}
+
+// fakeRecord writes a record of model as the list endpoint returns it,
+// with text fields set to "E2E <field>".
+func fakeRecord(model *ir.DataModel) string {
+	parts := []string{"id: 'e2e-1'"}
+	for _, f := range model.Fields {
+		name := sanitizeParamName(f.Name)
+		var value string
+		switch {
+		case len(f.EnumValues) > 0:
+			value = fmt.Sprintf("'%s'", escapeJSString(f.EnumValues[0]))
+		case f.Type == "number" || f.Type == "decimal":
+			value = "1"
+		case f.Type == "boolean":
+			value = "true"
+		case f.Type == "date" || f.Type == "datetime":
+			value = "'2030-01-01T00:00:00.000Z'"
+		default:
+			value = fmt.Sprintf("'E2E %s'", name)
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", name, value))
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
+
+// hasSuccessCondition reports whether a page shows a message when a save
+// succeeds.
+func hasSuccessCondition(page *ir.Page) bool {
+	for _, a := range page.Content {
+		if a.Type == "condition" && strings.Contains(strings.ToLower(a.Text), "succeed") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package quality
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func TestGenerateE2ETests(t *testing.T) {
+	app := exampleApp(t)
+	dir := t.TempDir()
+
+	files, count, err := generateE2ETests(app, dir)
+	if err != nil {
+		t.Fatalf("generateE2ETests: %v", err)
+	}
+	if files != len(app.Pages) {
+		t.Errorf("expected a spec per page (%d), got %d", len(app.Pages), files)
+	}
+	if count <= files {
+		t.Errorf("expected more tests than pages, got %d", count)
+	}
+
+	for _, name := range []string{"package.json", "playwright.config.ts", "helpers.ts", "dashboard.spec.ts"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("missing %s", name)
+		}
+	}
+	config, _ := os.ReadFile(filepath.Join(dir, "playwright.config.ts"))
+	if !strings.Contains(string(config), "docker compose -f ../docker-compose.yml up --build") {
+		t.Errorf("playwright.config.ts doesn't boot the docker-compose stack:\n%s", config)
+	}
+	helpers, _ := os.ReadFile(filepath.Join(dir, "helpers.ts"))
+	if !strings.Contains(string(helpers), "page.request.post('/api/sign-up'") {
+		t.Errorf("helpers.ts missing signIn through the sign-up endpoint:\n%s", helpers)
+	}
+}
+
+func TestGenerateE2ETests_NoWebFrontend(t *testing.T) {
+	app := &ir.Application{
+		Config: &ir.BuildConfig{Backend: "Node with Express"},
+		Pages:  []*ir.Page{{Name: "Home"}},
+	}
+	dir := filepath.Join(t.TempDir(), "e2e")
+	files, _, err := generateE2ETests(app, dir)
+	if err != nil || files != 0 {
+		t.Fatalf("generateE2ETests = %d, %v; want no specs", files, err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("e2e directory written for an app without a web frontend")
+	}
+}
+
+func TestGeneratePageE2E(t *testing.T) {
+	app := &ir.Application{
+		Auth: &ir.Auth{},
+		Data: []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{{Name: "title", Type: "text"}}}},
+		APIs: []*ir.Endpoint{{Name: "SignUp"}, {Name: "Login"}, {Name: "GetTasks"}},
+		Pages: []*ir.Page{
+			{Name: "Home", Content: []*ir.Action{
+				{Type: "interact", Text: "clicking the Get Started button navigates to Dashboard"},
+			}},
+			{Name: "Dashboard", Content: []*ir.Action{
+				{Type: "loop", Text: "each task shows its title"},
+				{Type: "condition", Text: "if no tasks match, show \"Nothing yet\""},
+				{Type: "interact", Text: "clicking the add button opens a form to create a Task"},
+			}},
+		},
+	}
+
+	home, count := generatePageE2E(app.Pages[0], app)
+	if count != 2 {
+		t.Errorf("Home: expected 2 tests, got %d", count)
+	}
+	for _, want := range []string{
+		"import { signIn } from './helpers';",
+		"    await page.goto('/');",
+		"name: /Get Started/i }).last().click();",
+		`toHaveURL(/\/dashboard$/);`,
+	} {
+		if !strings.Contains(home, want) {
+			t.Errorf("Home spec missing %q:\n%s", want, home)
+		}
+	}
+
+	dashboard, count := generatePageE2E(app.Pages[1], app)
+	if count != 4 {
+		t.Errorf("Dashboard: expected 4 tests, got %d", count)
+	}
+	for _, want := range []string{
+		"import { fillForm, signIn } from './helpers';",
+		"    await signIn(page);\n    await page.goto('/dashboard');",
+		"url.pathname === '/api/tasks'",
+		"data: [{ id: 'e2e-1', title: 'E2E title' }]",
+		"await expect(page.getByText('Nothing yet')).toBeVisible();",
+		"name: /add/i }).last().click();",
+		"await expect(page.getByText(values['title'])).toBeVisible();",
+	} {
+		if !strings.Contains(dashboard, want) {
+			t.Errorf("Dashboard spec missing %q:\n%s", want, dashboard)
+		}
+	}
+}
+
+func TestGeneratePageE2E_ProtectedWithoutSignIn(t *testing.T) {
+	app := &ir.Application{Auth: &ir.Auth{}, Pages: []*ir.Page{{Name: "Settings"}}}
+	content, count := generatePageE2E(app.Pages[0], app)
+	if count != 1 || !strings.Contains(content, `toHaveURL(/\/login$/)`) {
+		t.Errorf("expected only a redirect-to-login test, got %d:\n%s", count, content)
+	}
+	if strings.Contains(content, "./helpers") {
+		t.Errorf("spec imports helpers it doesn't use:\n%s", content)
+	}
+}
@@ -11,9 +11,9 @@ import (
 
 // edgeCase represents a single edge case test payload.
 type edgeCase struct {
-	Label   string // test description
-	Value   string // JS literal to send
-	Expect  int    // expected HTTP status (400 for invalid, 200/201 for valid)
+	Label  string // test description
+	Value  string // JS literal to send
+	Expect int    // expected HTTP status (400 for invalid, 200/201 for valid)
 }
 
 // generateEdgeTests creates edge case validation test files for data models
@@ -62,7 +62,9 @@ func generateModelEdgeTests(model *ir.DataModel, app *ir.Application) (string, i
 	b.WriteString("  const mockPrisma: Record<string, any> = {};\n")
 	b.WriteString("  return {\n")
 	b.WriteString("    PrismaClient: jest.fn(() => new Proxy(mockPrisma, {\n")
-	b.WriteString("      get: (_target, prop) => {\n")
+	b.WriteString("      get: (_target, prop, receiver) => {\n")
+	// Client extensions (field encryption, read replicas) wrap the mock.
+	b.WriteString("        if (prop === '$extends') return () => receiver;\n")
 	b.WriteString("        if (typeof prop === 'string' && prop !== 'then') {\n")
 	b.WriteString("          if (!mockPrisma[prop]) {\n")
 	b.WriteString("            mockPrisma[prop] = {\n")
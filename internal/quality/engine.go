@@ -1,6 +1,7 @@
 package quality
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,25 +9,33 @@ import (
 	"sync"
 	"unicode"
 
+	"github.com/barun-bash/human/internal/cli"
 	"github.com/barun-bash/human/internal/ir"
 )
 
 // Result holds the output of the quality engine.
 type Result struct {
-	TestFiles            int
-	TestCount            int
-	SecurityFindings     []Finding
-	LintWarnings         []Warning
-	ComponentTestFiles   int
-	ComponentTestCount   int
-	EdgeTestFiles        int
-	EdgeTestCount        int
-	IntegrationTestCount int
-	Coverage             *CoverageReport
-	VulnerabilityReport  *VulnerabilityReport
-	DuplicationFindings  []DuplicationFinding
-	PerformanceFindings  []PerformanceFinding
-	SecurityTestCount    int
+	TestFiles             int
+	TestCount             int
+	SecurityFindings      []Finding
+	LintWarnings          []Warning
+	ComponentTestFiles    int
+	ComponentTestCount    int
+	EdgeTestFiles         int
+	EdgeTestCount         int
+	IntegrationTestCount  int
+	E2ETestFiles          int
+	E2ETestCount          int
+	ContractCaseCount     int
+	Coverage              *CoverageReport
+	VulnerabilityReport   *VulnerabilityReport
+	DuplicationFindings   []DuplicationFinding
+	PerformanceFindings   []PerformanceFinding
+	SecurityTestCount     int
+	TypeMismatches        []TypeMismatch
+	AccessibilityFindings []Finding
+	LargestRouteKB        int // generated code the largest route loads
+	Score                 *Score
 }
 
 // Finding is a security audit finding.
@@ -47,6 +56,17 @@ type Warning struct {
 // Run executes the full quality engine against the IR and writes output files.
 // Test generation, security, and lint stages run in parallel where possible.
 func Run(app *ir.Application, outputDir string) (*Result, error) {
+	return RunWithProgress(app, outputDir, nil)
+}
+
+// RunWithProgress is like Run but calls progress as the engine moves on to
+// each group of checks.
+func RunWithProgress(app *ir.Application, outputDir string, progress func(step string)) (*Result, error) {
+	report := func(step string) {
+		if progress != nil {
+			progress(step)
+		}
+	}
 	result := &Result{}
 	testDir := filepath.Join(outputDir, "node", "src", "__tests__")
 
@@ -68,6 +88,7 @@ func Run(app *ir.Application, outputDir string) (*Result, error) {
 	}
 
 	// Group 1: Generate all test types in parallel (they write to separate files).
+	report("generating tests")
 	var mu sync.Mutex
 	var firstErr error
 	var wg sync.WaitGroup
@@ -80,7 +101,7 @@ func Run(app *ir.Application, outputDir string) (*Result, error) {
 		mu.Unlock()
 	}
 
-	wg.Add(4)
+	wg.Add(6)
 	go func() {
 		defer wg.Done()
 		testFiles, testCount, err := generateTests(app, testDir)
@@ -128,14 +149,39 @@ func Run(app *ir.Application, outputDir string) (*Result, error) {
 		result.IntegrationTestCount = integCount
 		mu.Unlock()
 	}()
+	go func() {
+		defer wg.Done()
+		e2eFiles, e2eCount, err := generateE2ETests(app, filepath.Join(outputDir, "e2e"))
+		if err != nil {
+			setErr(fmt.Errorf("end-to-end test generation: %w", err))
+			return
+		}
+		mu.Lock()
+		result.E2ETestFiles = e2eFiles
+		result.E2ETestCount = e2eCount
+		mu.Unlock()
+	}()
+	go func() {
+		defer wg.Done()
+		contractCount, err := writeContract(app, filepath.Join(outputDir, "contract"))
+		if err != nil {
+			setErr(fmt.Errorf("contract generation: %w", err))
+			return
+		}
+		mu.Lock()
+		result.ContractCaseCount = contractCount
+		mu.Unlock()
+	}()
 	wg.Wait()
 
 	if firstErr != nil {
 		return nil, firstErr
 	}
 
-	// Group 2: Security, lint, duplication, and performance in parallel (read-only on app).
-	wg.Add(4)
+	// Group 2: Security, lint, duplication, performance, and type safety in
+	// parallel (read-only on app).
+	report("security, lint, and performance checks")
+	wg.Add(6)
 	go func() {
 		defer wg.Done()
 		findings := checkSecurity(app)
@@ -144,6 +190,14 @@ func Run(app *ir.Application, outputDir string) (*Result, error) {
 			setErr(fmt.Errorf("security report: %w", err))
 			return
 		}
+		matrix, err := renderAuthzMatrixJSON(buildAuthzMatrix(app))
+		if err == nil {
+			err = writeFile(filepath.Join(outputDir, "authz-matrix.json"), matrix)
+		}
+		if err != nil {
+			setErr(fmt.Errorf("authorization matrix: %w", err))
+			return
+		}
 		mu.Lock()
 		result.SecurityFindings = findings
 		mu.Unlock()
@@ -169,7 +223,10 @@ func Run(app *ir.Application, outputDir string) (*Result, error) {
 	}()
 	go func() {
 		defer wg.Done()
-		findings := checkPerformance(app)
+		findings := append(checkPerformance(app), checkCacheBusting(app, outputDir)...)
+		findings = append(findings, checkViewport(app, outputDir)...)
+		routes := routeSizes(app, outputDir)
+		findings = append(findings, bundleBudgetFindings(app, routes)...)
 		perfReport := renderPerformanceReport(findings)
 		if err := writeFile(filepath.Join(outputDir, "performance-report.md"), perfReport); err != nil {
 			setErr(fmt.Errorf("performance report: %w", err))
@@ -177,6 +234,27 @@ func Run(app *ir.Application, outputDir string) (*Result, error) {
 		}
 		mu.Lock()
 		result.PerformanceFindings = findings
+		for _, r := range routes {
+			result.LargestRouteKB = max(result.LargestRouteKB, r.kb)
+		}
+		mu.Unlock()
+	}()
+	go func() {
+		defer wg.Done()
+		findings := checkAccessibility(app, outputDir)
+		mu.Lock()
+		result.AccessibilityFindings = findings
+		mu.Unlock()
+	}()
+	go func() {
+		defer wg.Done()
+		mismatches, layers := checkTypeSafety(app, outputDir)
+		if err := writeFile(filepath.Join(outputDir, "type-safety-report.md"), renderTypeSafetyReport(app, mismatches, layers)); err != nil {
+			setErr(fmt.Errorf("type safety report: %w", err))
+			return
+		}
+		mu.Lock()
+		result.TypeMismatches = mismatches
 		mu.Unlock()
 	}()
 	wg.Wait()
@@ -185,14 +263,29 @@ func Run(app *ir.Application, outputDir string) (*Result, error) {
 		return nil, firstErr
 	}
 
+	// Layers that disagree about the data models would fail where they
+	// meet at runtime, so they fail the build instead.
+	if n := len(result.TypeMismatches); n > 0 {
+		return nil, fmt.Errorf("type safety: %d mismatches between frontend types, backend DTOs, and database schema (see type-safety-report.md): %s", n, result.TypeMismatches[0].Message)
+	}
+
+	// A route over its bundle budget fails the build, so that the generated
+	// frontend can't grow past it unnoticed.
+	for _, f := range result.PerformanceFindings {
+		if f.Kind == "bundle-budget" {
+			return nil, fmt.Errorf("bundle budget: %s: %s (see performance-report.md)", f.Target, f.Message)
+		}
+	}
+
 	// Group 3: Sequential — coverage, dependency scan, and summary depend on prior results.
 	result.Coverage = calculateCoverage(app, result)
 
 	// Dependency vulnerability scan (needs package.json from test gen).
+	report("scanning dependencies")
 	vulnReport, err := ScanDependencies(outputDir)
 	if err != nil {
 		// Log warning but don't fail the build
-		fmt.Printf("  warning: dependency scan: %v\n", err)
+		cli.Printf("  warning: dependency scan: %v\n", err)
 	}
 	result.VulnerabilityReport = vulnReport
 	if err := writeFile(filepath.Join(outputDir, "dependency-audit.md"), renderDependencyAudit(vulnReport)); err != nil {
@@ -200,6 +293,7 @@ func Run(app *ir.Application, outputDir string) (*Result, error) {
 	}
 
 	// QA test plan (read-only on app).
+	report("writing reports")
 	testPlan := generateTestPlan(app)
 	if err := writeFile(filepath.Join(outputDir, "qa-test-plan.md"), testPlan); err != nil {
 		return nil, fmt.Errorf("QA test plan: %w", err)
@@ -215,6 +309,11 @@ func Run(app *ir.Application, outputDir string) (*Result, error) {
 		return nil, fmt.Errorf("traceability matrix: %w", err)
 	}
 
+	// Threat model (read-only on app).
+	if err := writeFile(filepath.Join(outputDir, "threat-model.md"), generateThreatModel(app)); err != nil {
+		return nil, fmt.Errorf("threat model: %w", err)
+	}
+
 	// Security test script (runtime probes).
 	secScript, secTestCount := generateSecurityTests(app)
 	if secTestCount > 0 {
@@ -228,6 +327,15 @@ func Run(app *ir.Application, outputDir string) (*Result, error) {
 	}
 	result.SecurityTestCount = secTestCount
 
+	result.Score = ScoreResult(app, result)
+	scoreJSON, err := json.MarshalIndent(result.Score, "", "  ")
+	if err == nil {
+		err = writeFile(filepath.Join(outputDir, "quality-score.json"), string(scoreJSON)+"\n")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("quality score: %w", err)
+	}
+
 	summary := renderBuildSummary(app, outputDir, result)
 	if err := writeFile(filepath.Join(outputDir, "build-report.md"), summary); err != nil {
 		return nil, fmt.Errorf("build summary: %w", err)
@@ -283,6 +391,12 @@ func PrintSummary(result *Result) {
 		parts = append(parts, fmt.Sprintf("%d dependency vulnerabilities (%d high, %d moderate)",
 			result.VulnerabilityReport.Total, result.VulnerabilityReport.High, result.VulnerabilityReport.Moderate))
 	}
+	if result.E2ETestCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d end-to-end tests", result.E2ETestCount))
+	}
+	if result.ContractCaseCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d API contract cases", result.ContractCaseCount))
+	}
 	if result.SecurityTestCount > 0 {
 		parts = append(parts, fmt.Sprintf("%d security probes", result.SecurityTestCount))
 	}
@@ -292,7 +406,19 @@ func PrintSummary(result *Result) {
 		parts = append(parts, "no issues")
 	}
 
-	fmt.Printf("  quality:      %s\n", strings.Join(parts, ", "))
+	cli.Printf("  quality:      %s\n", strings.Join(parts, ", "))
+	if result.Score != nil {
+		cli.Printf("  score:        %d/100 (%s)\n", result.Score.Total, scoreBreakdown(result.Score))
+	}
+}
+
+// scoreBreakdown lists a score's categories, e.g. "tests 82, security 95".
+func scoreBreakdown(s *Score) string {
+	var parts []string
+	for _, c := range s.Categories {
+		parts = append(parts, fmt.Sprintf("%s %d", c.Name, c.Score))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func writeFile(path, content string) error {
@@ -24,7 +24,9 @@ func generateIntegrationTests(app *ir.Application, testDir string) (int, error)
 	b.WriteString("  const mockPrisma: Record<string, any> = {};\n")
 	b.WriteString("  return {\n")
 	b.WriteString("    PrismaClient: jest.fn(() => new Proxy(mockPrisma, {\n")
-	b.WriteString("      get: (_target, prop) => {\n")
+	b.WriteString("      get: (_target, prop, receiver) => {\n")
+	// Client extensions (field encryption, read replicas) wrap the mock.
+	b.WriteString("        if (prop === '$extends') return () => receiver;\n")
 	b.WriteString("        if (typeof prop === 'string' && prop !== 'then') {\n")
 	b.WriteString("          if (!mockPrisma[prop]) {\n")
 	b.WriteString("            mockPrisma[prop] = {\n")
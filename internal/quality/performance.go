@@ -9,7 +9,7 @@ import (
 
 // PerformanceFinding represents a detected performance anti-pattern in the IR.
 type PerformanceFinding struct {
-	Kind     string // "n-plus-one", "missing-pagination", "missing-index", "large-payload"
+	Kind     string // "n-plus-one", "missing-pagination", "missing-index", "large-payload", "cache-busting", "viewport", "bundle-budget"
 	Severity string // "warning", "info"
 	Target   string
 	Message  string
@@ -86,7 +86,7 @@ func checkMissingPagination(app *ir.Application) []PerformanceFinding {
 
 	for _, ep := range app.APIs {
 		hasFetchAll := false
-		hasPaginate := false
+		hasPaginate := ep.PageSize > 0
 
 		for _, step := range ep.Steps {
 			lower := strings.ToLower(step.Text)
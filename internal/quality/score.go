@@ -0,0 +1,225 @@
+package quality
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Score categories, in the order they're reported.
+const (
+	ScoreTests    = "tests"
+	ScoreSecurity = "security"
+	ScoreLint     = "lint"
+	ScoreA11y     = "a11y"
+	ScoreBundle   = "bundle"
+)
+
+// scoreWeights weigh the categories in the total.
+var scoreWeights = map[string]int{
+	ScoreTests:    30,
+	ScoreSecurity: 30,
+	ScoreLint:     15,
+	ScoreA11y:     15,
+	ScoreBundle:   10,
+}
+
+// ScoreCategories returns the score categories, in report order.
+func ScoreCategories() []string {
+	return []string{ScoreTests, ScoreSecurity, ScoreLint, ScoreA11y, ScoreBundle}
+}
+
+// Score is the quality of a build, out of 100: the weighted average of
+// its categories' scores. Categories that don't apply, like a11y for an
+// app without a frontend, are left out.
+type Score struct {
+	Total      int             `json:"total"`
+	Categories []CategoryScore `json:"categories"`
+}
+
+// CategoryScore is one category's score, out of 100.
+type CategoryScore struct {
+	Name   string `json:"name"`
+	Score  int    `json:"score"`
+	Weight int    `json:"weight"`
+	Detail string `json:"detail"`
+}
+
+// Category returns the named category's score, or nil if it doesn't
+// apply.
+func (s *Score) Category(name string) *CategoryScore {
+	for i := range s.Categories {
+		if s.Categories[i].Name == name {
+			return &s.Categories[i]
+		}
+	}
+	return nil
+}
+
+// ScoreResult scores a quality engine result:
+//
+//   - tests: the test coverage
+//   - security: 100, less 20 per critical finding, 5 per warning, 10 per
+//     high dependency vulnerability, and 2 per moderate one
+//   - lint: 100, less 5 per lint warning
+//   - a11y: 100, less 10 per accessibility warning and 2 per note
+//   - bundle: 100 up to half the bundle budget for the largest route,
+//     falling to 50 at the budget
+func ScoreResult(app *ir.Application, result *Result) *Score {
+	s := &Score{}
+	add := func(name string, score int, detail string) {
+		s.Categories = append(s.Categories, CategoryScore{Name: name, Score: max(0, min(100, score)), Weight: scoreWeights[name], Detail: detail})
+	}
+
+	totalTests := result.TestCount + result.ComponentTestCount + result.EdgeTestCount + result.IntegrationTestCount
+	if cov := result.Coverage; cov != nil && cov.EndpointsTotal+cov.PagesTotal+cov.FieldsTotal > 0 {
+		add(ScoreTests, int(cov.Overall+0.5), fmt.Sprintf("%d tests, %.0f%% coverage", totalTests, cov.Overall))
+	}
+
+	criticals, warnings := 0, 0
+	for _, f := range result.SecurityFindings {
+		switch f.Severity {
+		case "critical":
+			criticals++
+		case "warning":
+			warnings++
+		}
+	}
+	security := 100 - 20*criticals - 5*warnings
+	detail := fmt.Sprintf("%d critical, %d warnings", criticals, warnings)
+	if v := result.VulnerabilityReport; v != nil && v.Total > 0 {
+		security -= 10*v.High + 2*v.Moderate
+		detail += fmt.Sprintf(", %d vulnerable dependencies", v.Total)
+	}
+	add(ScoreSecurity, security, detail)
+
+	add(ScoreLint, 100-5*len(result.LintWarnings), fmt.Sprintf("%d lint warnings", len(result.LintWarnings)))
+
+	if app.Config != nil && app.Config.Frontend != "" {
+		a11yWarnings, notes := 0, 0
+		for _, f := range result.AccessibilityFindings {
+			if f.Severity == "warning" {
+				a11yWarnings++
+			} else {
+				notes++
+			}
+		}
+		add(ScoreA11y, 100-10*a11yWarnings-2*notes, fmt.Sprintf("%d warnings, %d notes", a11yWarnings, notes))
+	}
+
+	if result.LargestRouteKB > 0 {
+		budget := ir.BundleBudgetKB(app)
+		bundle := 100
+		if half := float64(budget) / 2; float64(result.LargestRouteKB) > half {
+			bundle = int(100 - 50*(float64(result.LargestRouteKB)-half)/half + 0.5)
+		}
+		add(ScoreBundle, bundle, fmt.Sprintf("largest route %d KB of %d KB budget", result.LargestRouteKB, budget))
+	}
+
+	weighted, weights := 0, 0
+	for _, c := range s.Categories {
+		weighted += c.Score * c.Weight
+		weights += c.Weight
+	}
+	if weights > 0 {
+		s.Total = (weighted + weights/2) / weights
+	}
+	return s
+}
+
+// UnmetGates returns a message for each minimum the score falls short
+// of: minTotal for the total, and minimums by category. A minimum of 0,
+// or for a category that doesn't apply, always passes.
+func UnmetGates(s *Score, minTotal int, minimums map[string]int) []string {
+	var unmet []string
+	if s.Total < minTotal {
+		unmet = append(unmet, fmt.Sprintf("quality score %d is below the minimum of %d", s.Total, minTotal))
+	}
+	for _, name := range ScoreCategories() {
+		c := s.Category(name)
+		if c != nil && c.Score < minimums[name] {
+			unmet = append(unmet, fmt.Sprintf("%s score %d is below the minimum of %d (%s)", name, c.Score, minimums[name], c.Detail))
+		}
+	}
+	return unmet
+}
+
+// HistoryEntry is a build's score in the score history.
+type HistoryEntry struct {
+	BuiltAt string         `json:"built_at"`
+	App     string         `json:"app"`
+	Total   int            `json:"total"`
+	Scores  map[string]int `json:"scores"`
+}
+
+// AppendHistory appends a build's score to the history at path, a JSON
+// Lines file, creating it if need be.
+func AppendHistory(path string, entry HistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory for %s: %w", path, err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadHistory reads the score history at path, oldest first. A missing
+// file is an empty history; lines that aren't entries are skipped.
+func ReadHistory(path string) ([]HistoryEntry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e HistoryEntry
+		if line := strings.TrimSpace(scanner.Text()); line == "" || json.Unmarshal([]byte(line), &e) != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// NewHistoryEntry returns the history entry for a build's score.
+func NewHistoryEntry(app *ir.Application, s *Score, builtAt string) HistoryEntry {
+	entry := HistoryEntry{BuiltAt: builtAt, App: app.Name, Total: s.Total, Scores: map[string]int{}}
+	for _, c := range s.Categories {
+		entry.Scores[c.Name] = c.Score
+	}
+	return entry
+}
+
+// renderScoreSection produces the build report's quality score section.
+func renderScoreSection(s *Score) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Quality Score: %d/100\n\n", s.Total)
+	b.WriteString("| Category | Score | Weight | Detail |\n")
+	b.WriteString("|----------|-------|--------|--------|\n")
+	for _, c := range s.Categories {
+		fmt.Fprintf(&b, "| %s | %d | %d | %s |\n", c.Name, c.Score, c.Weight, c.Detail)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
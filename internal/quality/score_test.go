@@ -0,0 +1,91 @@
+package quality
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func TestScoreResult(t *testing.T) {
+	app := &ir.Application{Name: "Shop", Config: &ir.BuildConfig{Frontend: "React", BundleBudget: "100 KB"}}
+	result := &Result{
+		TestCount:        40,
+		Coverage:         &CoverageReport{EndpointsTotal: 10, Overall: 80},
+		SecurityFindings: []Finding{{Severity: "critical"}, {Severity: "warning"}, {Severity: "info"}},
+		LintWarnings:     []Warning{{}, {}},
+		AccessibilityFindings: []Finding{
+			{Severity: "warning", Category: "a11y"},
+			{Severity: "info", Category: "a11y"},
+		},
+		LargestRouteKB: 75,
+	}
+	s := ScoreResult(app, result)
+
+	want := map[string]int{ScoreTests: 80, ScoreSecurity: 75, ScoreLint: 90, ScoreA11y: 88, ScoreBundle: 75}
+	for name, score := range want {
+		if c := s.Category(name); c == nil || c.Score != score {
+			t.Errorf("%s = %+v, want %d", name, c, score)
+		}
+	}
+	// (80*30 + 75*30 + 90*15 + 88*15 + 75*10) / 100
+	if s.Total != 81 {
+		t.Errorf("Total = %d, want 81", s.Total)
+	}
+}
+
+func TestScoreResultLeavesOutCategoriesThatDontApply(t *testing.T) {
+	app := &ir.Application{Name: "API", Config: &ir.BuildConfig{Backend: "Node"}}
+	s := ScoreResult(app, &Result{Coverage: &CoverageReport{}})
+	if len(s.Categories) != 2 || s.Category(ScoreSecurity) == nil || s.Category(ScoreLint) == nil {
+		t.Errorf("Categories = %+v, want only security and lint", s.Categories)
+	}
+	if s.Total != 100 {
+		t.Errorf("Total = %d, want 100", s.Total)
+	}
+
+	// Findings past 100 points floor at 0.
+	many := make([]Finding, 10)
+	for i := range many {
+		many[i].Severity = "critical"
+	}
+	if s := ScoreResult(app, &Result{SecurityFindings: many}); s.Category(ScoreSecurity).Score != 0 {
+		t.Errorf("security = %d, want 0", s.Category(ScoreSecurity).Score)
+	}
+}
+
+func TestUnmetGates(t *testing.T) {
+	s := &Score{Total: 72, Categories: []CategoryScore{
+		{Name: ScoreTests, Score: 60, Detail: "60% coverage"},
+		{Name: ScoreSecurity, Score: 95},
+	}}
+	unmet := UnmetGates(s, 80, map[string]int{ScoreTests: 70, ScoreSecurity: 90, ScoreA11y: 90})
+	if len(unmet) != 2 || !strings.Contains(unmet[0], "72 is below the minimum of 80") || !strings.Contains(unmet[1], "tests score 60") {
+		t.Errorf("UnmetGates = %q", unmet)
+	}
+	if unmet := UnmetGates(s, 0, nil); len(unmet) != 0 {
+		t.Errorf("expected no gates without minimums, got %q", unmet)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".human", "quality-history.jsonl")
+	if entries, err := ReadHistory(path); err != nil || len(entries) != 0 {
+		t.Fatalf("ReadHistory of a missing file = %v, %v", entries, err)
+	}
+	app := &ir.Application{Name: "Shop"}
+	for i, total := range []int{70, 85} {
+		s := &Score{Total: total, Categories: []CategoryScore{{Name: ScoreTests, Score: total}}}
+		if err := AppendHistory(path, NewHistoryEntry(app, s, "2026-01-0"+string(rune('1'+i))+"T00:00:00Z")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := ReadHistory(path)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("ReadHistory = %v, %v", entries, err)
+	}
+	if e := entries[1]; e.Total != 85 || e.App != "Shop" || e.Scores[ScoreTests] != 85 || e.BuiltAt != "2026-01-02T00:00:00Z" {
+		t.Errorf("last entry = %+v", e)
+	}
+}
@@ -322,17 +322,18 @@ func renderSecurityReport(app *ir.Application, findings []Finding) string {
 	fmt.Fprintf(&b, "**Summary:** %d critical, %d warnings, %d info\n\n", criticals, warnings, infos)
 
 	if len(findings) == 0 {
-		b.WriteString("No security issues found.\n")
-		return b.String()
-	}
-
-	b.WriteString("## Findings\n\n")
-	b.WriteString("| Severity | Category | Target | Message |\n")
-	b.WriteString("|----------|----------|--------|---------|\n")
-	for _, f := range findings {
-		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", f.Severity, f.Category, f.Target, f.Message)
+		b.WriteString("No security issues found.\n\n")
+	} else {
+		b.WriteString("## Findings\n\n")
+		b.WriteString("| Severity | Category | Target | Message |\n")
+		b.WriteString("|----------|----------|--------|---------|\n")
+		for _, f := range findings {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", f.Severity, f.Category, f.Target, f.Message)
+		}
+		b.WriteString("\n")
 	}
-	b.WriteString("\n")
 
+	writeAuthzMatrix(&b, buildAuthzMatrix(app))
+	writeCompliance(&b, app)
 	return b.String()
 }
@@ -2,6 +2,7 @@ package quality
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/barun-bash/human/internal/ir"
@@ -409,13 +410,20 @@ func shellEscapeSingleQuote(s string) string {
 	return strings.ReplaceAll(s, "'", `'\''`)
 }
 
-// curlJSONBody builds a JSON object string from a field map.
+// curlJSONBody builds a JSON object string from a field map, with its keys
+// sorted so the script is the same every build.
 func curlJSONBody(fields map[string]string) string {
 	if len(fields) == 0 {
 		return "{}"
 	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
 	parts := make([]string, 0, len(fields))
-	for k, v := range fields {
+	for _, k := range keys {
+		v := fields[k]
 		escaped := strings.ReplaceAll(v, `\`, `\\`)
 		escaped = strings.ReplaceAll(escaped, `"`, `\"`)
 		parts = append(parts, fmt.Sprintf(`"%s":"%s"`, k, escaped))
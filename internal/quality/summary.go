@@ -15,8 +15,12 @@ func renderBuildSummary(app *ir.Application, outputDir string, result *Result) s
 	b.WriteString("# Build Report\n\n")
 	b.WriteString("Generated by Human compiler.\n\n")
 
-	// Timestamp
-	fmt.Fprintf(&b, "**Built at:** %s\n\n", time.Now().UTC().Format(time.RFC3339))
+	// Timestamp, the build's own when it has one (build-info.json)
+	builtAt := time.Now().UTC().Format(time.RFC3339)
+	if app.Build != nil {
+		builtAt = app.Build.GeneratedAt
+	}
+	fmt.Fprintf(&b, "**Built at:** %s\n\n", builtAt)
 
 	// Application info
 	b.WriteString("## Application\n\n")
@@ -79,6 +83,12 @@ func renderBuildSummary(app *ir.Application, outputDir string, result *Result) s
 	fmt.Fprintf(&b, "| Component Tests | %d |\n", result.ComponentTestCount)
 	fmt.Fprintf(&b, "| Edge Case Tests | %d |\n", result.EdgeTestCount)
 	fmt.Fprintf(&b, "| Integration Tests | %d |\n", result.IntegrationTestCount)
+	if result.E2ETestCount > 0 {
+		fmt.Fprintf(&b, "| End-to-End Tests (Playwright) | %d |\n", result.E2ETestCount)
+	}
+	if result.ContractCaseCount > 0 {
+		fmt.Fprintf(&b, "| API Contract Cases | %d |\n", result.ContractCaseCount)
+	}
 	if result.SecurityTestCount > 0 {
 		fmt.Fprintf(&b, "| Security Probes | %d |\n", result.SecurityTestCount)
 	}
@@ -89,6 +99,10 @@ func renderBuildSummary(app *ir.Application, outputDir string, result *Result) s
 	fmt.Fprintf(&b, "| Lint Warnings | %d |\n", len(result.LintWarnings))
 	b.WriteString("\n")
 
+	if result.Score != nil {
+		b.WriteString(renderScoreSection(result.Score))
+	}
+
 	// Coverage section
 	if result.Coverage != nil {
 		b.WriteString(renderCoverageSection(result.Coverage))
@@ -103,6 +117,9 @@ func renderBuildSummary(app *ir.Application, outputDir string, result *Result) s
 	// Performance section
 	b.WriteString(renderPerformanceSection(result.PerformanceFindings))
 
+	// Accessibility section
+	b.WriteString(renderAccessibilitySection(result.AccessibilityFindings))
+
 	// Security probes section
 	if result.SecurityTestCount > 0 {
 		b.WriteString(renderSecurityTestSection(result.SecurityTestCount))
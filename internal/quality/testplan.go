@@ -104,6 +104,14 @@ func writePageTests(b *strings.Builder, page *ir.Page) {
 	b.WriteString("- [ ] Desktop (1920x1080) renders correctly\n")
 	b.WriteString("- [ ] Tablet (768x1024) renders correctly\n")
 	b.WriteString("- [ ] Mobile (375x667) renders correctly\n")
+	if r := ir.ResponsiveFor(page); r != nil {
+		if r.Tablet {
+			fmt.Fprintf(b, "- [ ] Below %dpx wide the layout narrows without scrolling sideways\n", ir.TabletBreakpoint)
+		}
+		if r.Mobile {
+			fmt.Fprintf(b, "- [ ] Below %dpx wide the content stacks into one column\n", ir.MobileBreakpoint)
+		}
+	}
 	b.WriteString("\n")
 }
 
@@ -0,0 +1,390 @@
+package quality
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// LineHits is line coverage from a test run: the number of times each
+// line ran, by file. Files are slash paths relative to the output
+// directory, like "node/src/routes/create-task.ts".
+type LineHits map[string]map[int]int
+
+// Merge adds other's hits to h.
+func (h LineHits) Merge(other LineHits) {
+	for file, lines := range other {
+		if h[file] == nil {
+			h[file] = map[int]int{}
+		}
+		for line, n := range lines {
+			h[file][line] += n
+		}
+	}
+}
+
+// ConstructCoverage is how much of one endpoint's or page's generated code
+// the tests ran.
+type ConstructCoverage struct {
+	Kind    string `json:"kind"` // "endpoint" or "page"
+	Name    string `json:"name"`
+	Stack   string `json:"stack"`
+	File    string `json:"file"`
+	Lines   int    `json:"lines"`
+	Covered int    `json:"covered"`
+}
+
+// TestCoverage is the merged coverage of a `human test` run, keyed by the
+// .human constructs the code was generated from.
+type TestCoverage struct {
+	Stacks     []string             `json:"stacks"`
+	Constructs []*ConstructCoverage `json:"constructs"`
+}
+
+// Exercised counts the constructs of a kind that the tests ran any of,
+// and the total of that kind.
+func (c *TestCoverage) Exercised(kind string) (exercised, total int) {
+	for _, cc := range c.Constructs {
+		if cc.Kind != kind {
+			continue
+		}
+		total++
+		if cc.Covered > 0 {
+			exercised++
+		}
+	}
+	return exercised, total
+}
+
+// Untested returns the endpoints no generated test reached.
+func (c *TestCoverage) Untested() []*ConstructCoverage {
+	var out []*ConstructCoverage
+	for _, cc := range c.Constructs {
+		if cc.Kind == "endpoint" && cc.Covered == 0 {
+			out = append(out, cc)
+		}
+	}
+	return out
+}
+
+// ── Coverage formats ──
+
+// istanbulFile is a file's entry in Jest's coverage-final.json.
+type istanbulFile struct {
+	StatementMap map[string]istanbulRange `json:"statementMap"`
+	FnMap        map[string]struct {
+		Loc istanbulRange `json:"loc"`
+	} `json:"fnMap"`
+	S map[string]int `json:"s"`
+}
+
+type istanbulRange struct {
+	Start istanbulPos `json:"start"`
+	End   istanbulPos `json:"end"`
+}
+
+type istanbulPos struct {
+	Line   int  `json:"line"`
+	Column *int `json:"column"`
+}
+
+func (p istanbulPos) before(q istanbulPos) bool {
+	if p.Line != q.Line {
+		return p.Line < q.Line
+	}
+	return p.Column != nil && q.Column != nil && *p.Column < *q.Column
+}
+
+// ParseJestCoverage reads a Jest (Istanbul) coverage-final.json for the
+// stack in stackDir. Only statements inside functions count: a module's
+// top-level code runs as soon as a test imports it, so counting it would
+// mark every route a test file touched as covered.
+func ParseJestCoverage(data []byte, outputDir, stackDir string) (LineHits, error) {
+	var report map[string]istanbulFile
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("parsing Jest coverage: %w", err)
+	}
+	hits := LineHits{}
+	for path, f := range report {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(stackDir, path)
+		}
+		rel, err := filepath.Rel(outputDir, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		lines := map[int]int{}
+		for id, stmt := range f.StatementMap {
+			inFunction := false
+			for _, fn := range f.FnMap {
+				if fn.Loc.Start.before(stmt.Start) && !fn.Loc.End.before(stmt.End) {
+					inFunction = true
+					break
+				}
+			}
+			if !inFunction {
+				continue
+			}
+			if n, seen := lines[stmt.Start.Line]; !seen || f.S[id] > n {
+				lines[stmt.Start.Line] = f.S[id]
+			}
+		}
+		hits[filepath.ToSlash(rel)] = lines
+	}
+	return hits, nil
+}
+
+// ParseCoveragePy reads a coverage.py JSON report (`coverage json`) for
+// the stack generated into stack, whose paths are relative to it.
+func ParseCoveragePy(data []byte, stack string) (LineHits, error) {
+	var report struct {
+		Files map[string]struct {
+			ExecutedLines []int `json:"executed_lines"`
+			MissingLines  []int `json:"missing_lines"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("parsing coverage.py report: %w", err)
+	}
+	hits := LineHits{}
+	for path, f := range report.Files {
+		lines := map[int]int{}
+		for _, l := range f.MissingLines {
+			lines[l] = 0
+		}
+		for _, l := range f.ExecutedLines {
+			lines[l] = 1
+		}
+		hits[stack+"/"+filepath.ToSlash(path)] = lines
+	}
+	return hits, nil
+}
+
+// ParseGoCoverProfile reads a `go test -coverprofile` profile for the Go
+// module generated into stack. Each block counts at the line it starts
+// on, since a block runs from its first statement.
+func ParseGoCoverProfile(data []byte, module, stack string) (LineHits, error) {
+	hits := LineHits{}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		// file.go:startLine.startCol,endLine.endCol numStmts count
+		colon := strings.LastIndex(line, ":")
+		var fields []string
+		if colon >= 0 {
+			fields = strings.Fields(line[colon+1:])
+		}
+		if len(fields) != 3 || !strings.Contains(fields[0], ".") {
+			return nil, fmt.Errorf("parsing Go cover profile: malformed line %q", line)
+		}
+		file := strings.TrimPrefix(strings.TrimPrefix(line[:colon], module), "/")
+		start, err := strconv.Atoi(fields[0][:strings.Index(fields[0], ".")])
+		if err != nil {
+			return nil, fmt.Errorf("parsing Go cover profile: %w", err)
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("parsing Go cover profile: %w", err)
+		}
+		key := stack + "/" + file
+		if hits[key] == nil {
+			hits[key] = map[int]int{}
+		}
+		hits[key][start] += count
+	}
+	return hits, scanner.Err()
+}
+
+// ── Mapping to constructs ──
+
+// MapTestCoverage finds each endpoint's and page's code in the stacks
+// that ran and counts the lines of it the tests covered. Node routes and
+// React pages are a file each; Python and Go endpoints are a function in a
+// shared file, read from outputDir to find where each starts and ends.
+func MapTestCoverage(app *ir.Application, outputDir string, stacks []string, hits LineHits) *TestCoverage {
+	cov := &TestCoverage{Stacks: stacks}
+	ran := map[string]bool{}
+	for _, s := range stacks {
+		ran[s] = true
+	}
+
+	for _, api := range app.APIs {
+		if ran["node"] {
+			cov.add("endpoint", api.Name, "node", "node/src/routes/"+toKebabCase(api.Name)+".ts", nil, hits)
+		}
+		if ran["python"] {
+			span := functionSpan(outputDir, "python/routes.py", func(l string) bool {
+				return strings.HasPrefix(strings.TrimPrefix(l, "async "), "def "+pySnakeCase(api.Name)+"(")
+			}, pythonBodyEnds)
+			cov.add("endpoint", api.Name, "python", "python/routes.py", span, hits)
+		}
+		if ran["go"] {
+			span := functionSpan(outputDir, "go/handlers/handlers.go", func(l string) bool {
+				return strings.HasPrefix(l, "func "+goPascalCase(api.Name)+"(")
+			}, func(l string) bool { return l == "}" })
+			cov.add("endpoint", api.Name, "go", "go/handlers/handlers.go", span, hits)
+		}
+	}
+	if ran["react"] {
+		for _, page := range app.Pages {
+			cov.add("page", page.Name, "react", "react/src/pages/"+page.Name+"Page.tsx", nil, hits)
+		}
+	}
+	return cov
+}
+
+// add records a construct's coverage: the lines within span, or, with no
+// span, the whole file. Code the coverage tool never saw — an untested file
+// Jest didn't load, say — has no lines, and is counted as not covered.
+func (c *TestCoverage) add(kind, name, stack, file string, span []int, hits LineHits) {
+	cc := &ConstructCoverage{Kind: kind, Name: name, Stack: stack, File: file}
+	lines := hits[file]
+	if span != nil {
+		for line, n := range lines {
+			if line > span[0] && line <= span[1] {
+				cc.Lines++
+				if n > 0 {
+					cc.Covered++
+				}
+			}
+		}
+	} else {
+		for _, n := range lines {
+			cc.Lines++
+			if n > 0 {
+				cc.Covered++
+			}
+		}
+	}
+	c.Constructs = append(c.Constructs, cc)
+}
+
+// functionSpan returns the first and last lines of the function in file
+// whose definition line matches starts, up to the line that ends matches;
+// it returns an empty span when the function isn't found.
+func functionSpan(outputDir, file string, starts, ends func(string) bool) []int {
+	data, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(file)))
+	if err != nil {
+		return []int{0, 0}
+	}
+	lines := strings.Split(string(data), "\n")
+	for i, l := range lines {
+		if !starts(l) {
+			continue
+		}
+		for j := i + 1; j < len(lines); j++ {
+			if ends(lines[j]) {
+				return []int{i + 1, j}
+			}
+		}
+		return []int{i + 1, len(lines)}
+	}
+	return []int{0, 0}
+}
+
+// pythonBodyEnds reports whether a line is past a top-level function's
+// body: the next top-level statement or decorator.
+func pythonBodyEnds(l string) bool {
+	return l != "" && l[0] != ' ' && l[0] != '\t' && l[0] != '#'
+}
+
+// pySnakeCase names a Python route handler the way the Python generator
+// does.
+func pySnakeCase(s string) string {
+	var result []rune
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 && s[i-1] != ' ' && s[i-1] != '_' && s[i-1] != '-' {
+				result = append(result, '_')
+			}
+			result = append(result, unicode.ToLower(r))
+		} else if r == ' ' || r == '-' {
+			result = append(result, '_')
+		} else {
+			result = append(result, r)
+		}
+	}
+	return string(result)
+}
+
+// goPascalCase names a Go handler the way the Go generator does for the
+// PascalCase endpoint names the parser produces.
+func goPascalCase(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// ── Reporting ──
+
+// RenderTestCoverageSection produces the build report's section on what
+// the last `human test` run exercised.
+func RenderTestCoverageSection(cov *TestCoverage) string {
+	var b strings.Builder
+	b.WriteString("## Test Run Coverage\n\n")
+	fmt.Fprintf(&b, "From the last `human test` run (%s).\n\n", strings.Join(cov.Stacks, ", "))
+
+	constructs := append([]*ConstructCoverage(nil), cov.Constructs...)
+	sort.SliceStable(constructs, func(i, j int) bool { return constructs[i].Kind < constructs[j].Kind })
+	b.WriteString("| Construct | Stack | Lines covered |\n")
+	b.WriteString("|-----------|-------|---------------|\n")
+	for _, cc := range constructs {
+		fmt.Fprintf(&b, "| %s %s | %s | %d/%d (%.0f%%) |\n", cc.Kind, cc.Name, cc.Stack, cc.Covered, cc.Lines, pct(cc.Covered, cc.Lines))
+	}
+
+	untested := cov.Untested()
+	if len(untested) > 0 {
+		b.WriteString("\n**Endpoints with no test coverage:**\n\n")
+		for _, cc := range untested {
+			fmt.Fprintf(&b, "- %s (%s, `%s`)\n", cc.Name, cc.Stack, cc.File)
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// WriteTestCoverage saves a test run's coverage as test-coverage.json in
+// outputDir and puts its section into build-report.md, replacing the one
+// from the previous run.
+func WriteTestCoverage(outputDir string, cov *TestCoverage) error {
+	data, err := json.MarshalIndent(cov, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding test coverage: %w", err)
+	}
+	if err := writeFile(filepath.Join(outputDir, "test-coverage.json"), string(data)+"\n"); err != nil {
+		return err
+	}
+
+	reportPath := filepath.Join(outputDir, "build-report.md")
+	existing, err := os.ReadFile(reportPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", reportPath, err)
+	}
+	report := string(existing)
+	if i := strings.Index(report, "## Test Run Coverage\n"); i >= 0 {
+		rest := report[i+len("## Test Run Coverage\n"):]
+		end := len(report)
+		if j := strings.Index(rest, "\n## "); j >= 0 {
+			end = i + len("## Test Run Coverage\n") + j + 1
+		}
+		report = report[:i] + report[end:]
+	}
+	if report != "" && !strings.HasSuffix(report, "\n\n") {
+		report = strings.TrimRight(report, "\n") + "\n\n"
+	}
+	return writeFile(reportPath, report+RenderTestCoverageSection(cov))
+}
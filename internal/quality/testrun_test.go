@@ -0,0 +1,137 @@
+package quality
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func TestParseJestCoverage(t *testing.T) {
+	// Line 1 is an import, run on load; lines 3 and 4 are inside the
+	// handler, and only 3 ran.
+	data := `{
+  "/out/node/src/routes/create-task.ts": {
+    "statementMap": {
+      "0": {"start": {"line": 1, "column": 0}, "end": {"line": 1, "column": 30}},
+      "1": {"start": {"line": 3, "column": 2}, "end": {"line": 3, "column": 20}},
+      "2": {"start": {"line": 4, "column": 2}, "end": {"line": 4, "column": 20}}
+    },
+    "fnMap": {"0": {"name": "(anonymous_0)", "loc": {"start": {"line": 2, "column": 30}, "end": {"line": 5, "column": 1}}}},
+    "s": {"0": 1, "1": 2, "2": 0}
+  },
+  "/elsewhere/lib.ts": {"statementMap": {}, "fnMap": {}, "s": {}}
+}`
+	hits, err := ParseJestCoverage([]byte(data), "/out", "/out/node")
+	if err != nil {
+		t.Fatalf("ParseJestCoverage: %v", err)
+	}
+	lines := hits["node/src/routes/create-task.ts"]
+	if len(hits) != 1 || len(lines) != 2 || lines[3] != 2 || lines[4] != 0 {
+		t.Errorf("hits = %v", hits)
+	}
+}
+
+func TestParseCoveragePy(t *testing.T) {
+	data := `{"files": {"routes.py": {"executed_lines": [1, 5], "missing_lines": [6]}}}`
+	hits, err := ParseCoveragePy([]byte(data), "python")
+	if err != nil {
+		t.Fatalf("ParseCoveragePy: %v", err)
+	}
+	lines := hits["python/routes.py"]
+	if lines[5] != 1 || lines[6] != 0 || len(lines) != 3 {
+		t.Errorf("hits = %v", hits)
+	}
+}
+
+func TestParseGoCoverProfile(t *testing.T) {
+	data := "mode: set\nexample.com/app/handlers/handlers.go:12.45,14.3 2 1\nexample.com/app/handlers/handlers.go:15.2,17.3 1 0\n"
+	hits, err := ParseGoCoverProfile([]byte(data), "example.com/app", "go")
+	if err != nil {
+		t.Fatalf("ParseGoCoverProfile: %v", err)
+	}
+	lines := hits["go/handlers/handlers.go"]
+	if lines[12] != 1 || lines[15] != 0 || len(lines) != 2 {
+		t.Errorf("hits = %v", hits)
+	}
+	if _, err := ParseGoCoverProfile([]byte("garbage\n"), "", "go"); err == nil {
+		t.Error("expected an error for a malformed profile")
+	}
+}
+
+func TestMapTestCoverage(t *testing.T) {
+	dir := t.TempDir()
+	routes := "router = APIRouter()\n\n@router.get('/tasks')\ndef get_tasks(db: Session = Depends(get_db)):\n    tasks = db.query(Task).all()\n    return tasks\n\n@router.post('/tasks')\ndef create_task(payload: CreateTaskRequest):\n    task = Task(**payload.dict())\n    return task\n"
+	if err := os.MkdirAll(filepath.Join(dir, "python"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "python", "routes.py"), []byte(routes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := &ir.Application{
+		APIs:  []*ir.Endpoint{{Name: "GetTasks"}, {Name: "CreateTask"}},
+		Pages: []*ir.Page{{Name: "Home"}},
+	}
+	hits := LineHits{
+		"node/src/routes/get-tasks.ts":   {3: 1, 4: 1},
+		"node/src/routes/create-task.ts": {3: 0},
+		"python/routes.py":               {5: 1, 6: 1, 10: 0, 11: 0},
+		"react/src/pages/HomePage.tsx":   {8: 1, 9: 0},
+	}
+	cov := MapTestCoverage(app, dir, []string{"node", "python", "react"}, hits)
+
+	got := map[string]string{}
+	for _, cc := range cov.Constructs {
+		got[cc.Stack+" "+cc.Name] = fmt.Sprintf("%d/%d", cc.Covered, cc.Lines)
+	}
+	want := map[string]string{
+		"node GetTasks":     "2/2",
+		"node CreateTask":   "0/1",
+		"python GetTasks":   "2/2",
+		"python CreateTask": "0/2",
+		"react Home":        "1/2",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s covered %s, want %s", k, got[k], v)
+		}
+	}
+	if ex, total := cov.Exercised("endpoint"); ex != 2 || total != 4 {
+		t.Errorf("Exercised(endpoint) = %d/%d, want 2/4", ex, total)
+	}
+	if untested := cov.Untested(); len(untested) != 2 || untested[0].Name != "CreateTask" {
+		t.Errorf("Untested = %v", untested)
+	}
+}
+
+func TestWriteTestCoverage(t *testing.T) {
+	dir := t.TempDir()
+	report := "# Build Report\n\n## Test Run Coverage\n\nold\n\n## Files\n\nlist\n"
+	if err := os.WriteFile(filepath.Join(dir, "build-report.md"), []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cov := &TestCoverage{Stacks: []string{"node"}, Constructs: []*ConstructCoverage{
+		{Kind: "endpoint", Name: "CreateTask", Stack: "node", File: "node/src/routes/create-task.ts", Lines: 4},
+	}}
+	if err := WriteTestCoverage(dir, cov); err != nil {
+		t.Fatalf("WriteTestCoverage: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "build-report.md"))
+	got := string(data)
+	if strings.Contains(got, "old") || strings.Count(got, "## Test Run Coverage") != 1 {
+		t.Errorf("previous run's section not replaced:\n%s", got)
+	}
+	if !strings.Contains(got, "## Files\n\nlist\n") {
+		t.Errorf("other sections lost:\n%s", got)
+	}
+	if !strings.Contains(got, "- CreateTask (node, `node/src/routes/create-task.ts`)") {
+		t.Errorf("untested endpoint not flagged:\n%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test-coverage.json")); err != nil {
+		t.Error("test-coverage.json not written")
+	}
+}
@@ -42,14 +42,25 @@ func generateEndpointTests(ep *ir.Endpoint, app *ir.Application) (string, int) {
 	b.WriteString("// Generated by Human compiler — do not edit\n\n")
 	b.WriteString("jest.mock('@prisma/client', () => {\n")
 	b.WriteString("  const mockPrisma: Record<string, any> = {};\n")
+	if len(app.Data) > 0 {
+		// Reads return the shared fixture records (keyed by Prisma delegate
+		// name) so tests see the same data as the mock server and the seed.
+		b.WriteString("  const { fixtures } = jest.requireActual('../../../fixtures');\n")
+	}
 	b.WriteString("  return {\n")
 	b.WriteString("    PrismaClient: jest.fn(() => new Proxy(mockPrisma, {\n")
-	b.WriteString("      get: (_target, prop) => {\n")
+	b.WriteString("      get: (_target, prop, receiver) => {\n")
+	// Client extensions (field encryption, read replicas) wrap the mock.
+	b.WriteString("        if (prop === '$extends') return () => receiver;\n")
 	b.WriteString("        if (typeof prop === 'string' && prop !== 'then') {\n")
 	b.WriteString("          if (!mockPrisma[prop]) {\n")
 	b.WriteString("            mockPrisma[prop] = {\n")
 	b.WriteString("              create: jest.fn().mockResolvedValue({ id: '1' }),\n")
-	b.WriteString("              findMany: jest.fn().mockResolvedValue([]),\n")
+	if len(app.Data) > 0 {
+		b.WriteString("              findMany: jest.fn().mockResolvedValue(fixtures[prop] ?? []),\n")
+	} else {
+		b.WriteString("              findMany: jest.fn().mockResolvedValue([]),\n")
+	}
 	b.WriteString("              findUnique: jest.fn().mockResolvedValue(null),\n")
 	b.WriteString("              update: jest.fn().mockResolvedValue({ id: '1' }),\n")
 	b.WriteString("              delete: jest.fn().mockResolvedValue({ id: '1' }),\n")
@@ -0,0 +1,382 @@
+package quality
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Threat is one STRIDE row of the threat model.
+type Threat struct {
+	Category   string // Spoofing, Tampering, Repudiation, Information disclosure, Denial of service, Elevation of privilege
+	Threat     string
+	Mitigation string
+	Status     string // "present", "partial", "missing", "review", "n/a"
+}
+
+// generateThreatModel produces threat-model.md: a STRIDE table for each
+// endpoint and integration, seeded with what the IR says about
+// authentication, validation, authorization, and encryption. It is a
+// starting point for a security review, not a verdict — rows the IR
+// can't decide are marked "review".
+func generateThreatModel(app *ir.Application) string {
+	var b strings.Builder
+
+	b.WriteString("# Threat Model\n\n")
+	fmt.Fprintf(&b, "Application: **%s**\n\n", app.Name)
+	b.WriteString("Generated by Human compiler quality engine from the IR. Review each row, add what the IR can't know (deployment, operations, people), and record your decisions.\n\n")
+
+	writeTrustBoundaries(&b, app)
+
+	matrix := buildAuthzMatrix(app)
+	missing := 0
+	var sections strings.Builder
+	for i, ep := range app.APIs {
+		threats := endpointThreats(app, ep, matrix.Endpoints[i])
+		missing += countMissing(threats)
+		fmt.Fprintf(&sections, "### %s\n\n", ep.Name)
+		fmt.Fprintf(&sections, "**Data handled:** %s  \n", endpointData(app, ep))
+		boundary := "Internet → API (public)"
+		if ep.Auth {
+			boundary = "Internet → API (signed-in users)"
+		}
+		fmt.Fprintf(&sections, "**Trust boundary:** %s\n\n", boundary)
+		writeThreatTable(&sections, threats)
+	}
+	var integrations strings.Builder
+	for _, integ := range app.Integrations {
+		threats := integrationThreats(integ)
+		missing += countMissing(threats)
+		fmt.Fprintf(&integrations, "### %s\n\n", integ.Service)
+		fmt.Fprintf(&integrations, "**Data handled:** %s  \n", integrationData(integ))
+		fmt.Fprintf(&integrations, "**Trust boundary:** API → %s (third party)\n\n", integ.Service)
+		writeThreatTable(&integrations, threats)
+	}
+
+	fmt.Fprintf(&b, "**Summary:** %d endpoints, %d integrations, %d missing mitigations\n\n", len(app.APIs), len(app.Integrations), missing)
+	if len(app.APIs) > 0 {
+		b.WriteString("## Endpoints\n\n")
+		b.WriteString(sections.String())
+	}
+	if len(app.Integrations) > 0 {
+		b.WriteString("## Integrations\n\n")
+		b.WriteString(integrations.String())
+	}
+	return b.String()
+}
+
+// writeTrustBoundaries lists where data crosses from one party to another.
+func writeTrustBoundaries(b *strings.Builder, app *ir.Application) {
+	transport := "review — no environment URL, so TLS depends on how the app is exposed"
+	if env := ir.PublicEnvironment(app); env != nil {
+		transport = "HTTPS at " + env.Host()
+	}
+
+	b.WriteString("## Trust Boundaries\n\n")
+	b.WriteString("| Boundary | Crossing | Protection |\n")
+	b.WriteString("|----------|----------|------------|\n")
+	if app.Config != nil && app.Config.Frontend != "" {
+		fmt.Fprintf(b, "| Internet → Frontend | Browser loads the %s app | %s |\n", app.Config.Frontend, transport)
+	}
+	if len(app.APIs) > 0 {
+		fmt.Fprintf(b, "| Internet → API | %d endpoints | %s |\n", len(app.APIs), transport)
+	}
+	if engine := ir.DatabaseEngine(app); engine != "" && len(app.Data) > 0 {
+		protection := "private network"
+		if ir.UsesFieldEncryption(app) {
+			protection += "; encrypted fields at rest"
+		}
+		fmt.Fprintf(b, "| API → Database | %s, %d models | %s |\n", engine, len(app.Data), protection)
+	}
+	for _, integ := range app.Integrations {
+		kind := integ.Type
+		if kind == "" {
+			kind = "third-party"
+		}
+		fmt.Fprintf(b, "| API → %s | %s service | credentials from environment variables: %s |\n", integ.Service, kind, yesNo(credentialsFromEnv(integ)))
+	}
+	b.WriteString("\n")
+}
+
+// endpointThreats fills in the STRIDE rows for one endpoint.
+func endpointThreats(app *ir.Application, ep *ir.Endpoint, authz *AuthzEndpoint) []Threat {
+	lower := strings.ToLower(ep.Name)
+	isSignIn := lower == "signup" || lower == "login"
+	rateLimited := authRuleMentions(app, "rate limit")
+
+	spoofing := Threat{Category: "Spoofing", Threat: "A caller acts as another user"}
+	switch {
+	case ep.Auth:
+		spoofing.Mitigation, spoofing.Status = authMethods(app)+" authentication required", "present"
+	case isSignIn:
+		spoofing.Threat = "Credential stuffing or account enumeration"
+		spoofing.Mitigation, spoofing.Status = "rate limiting on sign-in", statusIf(rateLimited, "present", "missing")
+	case httpMethod(ep.Name) == "get":
+		spoofing.Mitigation, spoofing.Status = "public read — confirm nothing here is private", "review"
+	default:
+		spoofing.Mitigation, spoofing.Status = "no authentication on an endpoint that changes data", "missing"
+	}
+
+	tampering := Threat{Category: "Tampering", Threat: "Malformed or malicious input"}
+	var validated, unvalidated []string
+	for _, p := range ep.Params {
+		if hasValidation(ep, p.Name) {
+			validated = append(validated, p.Name)
+		} else {
+			unvalidated = append(unvalidated, p.Name)
+		}
+	}
+	switch {
+	case len(ep.Params) == 0:
+		tampering.Mitigation, tampering.Status = "no input", "n/a"
+	case len(unvalidated) == 0:
+		tampering.Mitigation, tampering.Status = "validated: "+strings.Join(validated, ", "), "present"
+	case len(validated) == 0:
+		tampering.Mitigation, tampering.Status = "no validation rules", "missing"
+	default:
+		tampering.Mitigation, tampering.Status = "unvalidated: "+strings.Join(unvalidated, ", "), "partial"
+	}
+
+	repudiation := Threat{Category: "Repudiation", Threat: "A user denies making a change", Mitigation: "no audit log records who called it", Status: "review"}
+	if ep.Auth && httpMethod(ep.Name) != "get" {
+		repudiation.Status = "missing"
+	}
+
+	disclosure := Threat{Category: "Information disclosure", Threat: "Responses or storage expose data to the wrong party"}
+	var protections []string
+	if scopes := ownScopedRoles(authz); len(scopes) > 0 {
+		protections = append(protections, "scoped to own records for "+strings.Join(scopes, ", "))
+	}
+	if model := findModel(app, authz.Model); model != nil {
+		for _, f := range model.EncryptedFields() {
+			protections = append(protections, f.Name+" encrypted at rest")
+		}
+	}
+	if env := ir.PublicEnvironment(app); env != nil {
+		protections = append(protections, "HTTPS")
+	}
+	if len(protections) > 0 {
+		disclosure.Mitigation, disclosure.Status = strings.Join(protections, "; "), "present"
+	} else {
+		disclosure.Mitigation, disclosure.Status = "nothing in the IR limits what the response returns", "review"
+	}
+
+	dos := Threat{Category: "Denial of service", Threat: "The endpoint is flooded with requests"}
+	dos.Mitigation, dos.Status = "rate limiting", statusIf(rateLimited, "present", "missing")
+
+	elevation := Threat{Category: "Elevation of privilege", Threat: "A role performs an action its policy forbids"}
+	switch {
+	case !ep.Auth:
+		elevation.Mitigation, elevation.Status = "public endpoint", "n/a"
+	case len(app.Policies) == 0:
+		elevation.Mitigation, elevation.Status = "no policies — any signed-in user may call it", "missing"
+	case authz.Action == "" || authz.Model == "":
+		elevation.Mitigation, elevation.Status = "the action can't be inferred from the endpoint name, so only sign-in is checked", "missing"
+	default:
+		elevation.Mitigation, elevation.Status = fmt.Sprintf("policies checked for %s %s", authz.Action, authz.Model), "present"
+		if roles := defaultAllowRoles(authz); len(roles) > 0 {
+			elevation.Mitigation += fmt.Sprintf(" (no rule for %s, allowed by default)", strings.Join(roles, ", "))
+			elevation.Status = "review"
+		}
+	}
+
+	return []Threat{spoofing, tampering, repudiation, disclosure, dos, elevation}
+}
+
+// integrationThreats fills in the STRIDE rows that apply to a third-party
+// service the backend calls.
+func integrationThreats(integ *ir.Integration) []Threat {
+	spoofing := Threat{Category: "Spoofing", Threat: "Stolen credentials let someone act as the app"}
+	switch {
+	case len(integ.Credentials) == 0:
+		spoofing.Mitigation, spoofing.Status = "no credentials declared", "review"
+	case credentialsFromEnv(integ):
+		spoofing.Mitigation, spoofing.Status = "credentials read from environment variables", "present"
+	default:
+		spoofing.Mitigation, spoofing.Status = "credentials are not environment variable references", "missing"
+	}
+
+	tampering := Threat{Category: "Tampering", Threat: "Forged callbacks from the service", Mitigation: "no webhooks", Status: "n/a"}
+	if hook := integ.Config["webhook_endpoint"]; hook != "" {
+		tampering.Mitigation, tampering.Status = "verify the provider's signature on "+hook, "review"
+	}
+
+	disclosure := Threat{Category: "Information disclosure", Threat: "Data sent to " + integ.Service + " leaves your control", Mitigation: "confirm what is shared and the provider's data processing terms", Status: "review"}
+	dos := Threat{Category: "Denial of service", Threat: "A provider outage or quota limit", Mitigation: "confirm timeouts and fallbacks", Status: "review"}
+
+	return []Threat{spoofing, tampering, disclosure, dos}
+}
+
+func writeThreatTable(b *strings.Builder, threats []Threat) {
+	b.WriteString("| STRIDE | Threat | Mitigation | Status |\n")
+	b.WriteString("|--------|--------|------------|--------|\n")
+	for _, t := range threats {
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n", t.Category, t.Threat, t.Mitigation, t.Status)
+	}
+	b.WriteString("\n")
+}
+
+func countMissing(threats []Threat) int {
+	n := 0
+	for _, t := range threats {
+		if t.Status == "missing" {
+			n++
+		}
+	}
+	return n
+}
+
+// endpointData describes the input an endpoint takes and the model it
+// acts on, calling out sensitive fields.
+func endpointData(app *ir.Application, ep *ir.Endpoint) string {
+	var parts []string
+	if len(ep.Params) > 0 {
+		names := make([]string, len(ep.Params))
+		for i, p := range ep.Params {
+			names[i] = p.Name
+		}
+		parts = append(parts, "input "+strings.Join(names, ", "))
+	}
+	if model := findModel(app, inferAuthzModel(ep.Name)); model != nil {
+		desc := model.Name + " records"
+		if sensitive := sensitiveFields(model); len(sensitive) > 0 {
+			desc += " (sensitive: " + strings.Join(sensitive, ", ") + ")"
+		}
+		parts = append(parts, desc)
+	}
+	if len(parts) == 0 {
+		return "none declared"
+	}
+	return strings.Join(parts, "; ")
+}
+
+func integrationData(integ *ir.Integration) string {
+	var parts []string
+	if integ.Type != "" {
+		parts = append(parts, integ.Type)
+	}
+	if integ.Purpose != "" {
+		parts = append(parts, integ.Purpose)
+	}
+	if len(integ.Templates) > 0 {
+		parts = append(parts, "templates "+strings.Join(integ.Templates, ", "))
+	}
+	if len(parts) == 0 {
+		return "not declared"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// sensitiveFields lists fields holding credentials, contact details, or
+// anything marked encrypted.
+func sensitiveFields(model *ir.DataModel) []string {
+	var fields []string
+	for _, f := range model.Fields {
+		lower := strings.ToLower(f.Name)
+		switch {
+		case lower == "password":
+			fields = append(fields, f.Name+" (hashed)")
+		case f.EncryptsAtRest():
+			fields = append(fields, f.Name+" (encrypted)")
+		case f.Encrypted, f.Type == "email", strings.Contains(lower, "phone"), strings.Contains(lower, "address"):
+			fields = append(fields, f.Name)
+		}
+	}
+	return fields
+}
+
+func findModel(app *ir.Application, name string) *ir.DataModel {
+	if name == "" {
+		return nil
+	}
+	for _, m := range app.Data {
+		if strings.EqualFold(m.Name, name) {
+			return m
+		}
+	}
+	return nil
+}
+
+func hasValidation(ep *ir.Endpoint, field string) bool {
+	for _, v := range ep.Validation {
+		if strings.EqualFold(v.Field, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func ownScopedRoles(authz *AuthzEndpoint) []string {
+	var roles []string
+	for role, cell := range authz.Access {
+		if cell.Decision == "allow" && cell.Scope == "own" {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+func defaultAllowRoles(authz *AuthzEndpoint) []string {
+	var roles []string
+	for role, cell := range authz.Access {
+		if cell.Decision == "default-allow" {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// authMethods names the app's authentication methods, e.g. "JWT".
+func authMethods(app *ir.Application) string {
+	if app.Auth == nil || len(app.Auth.Methods) == 0 {
+		return "Token"
+	}
+	var names []string
+	for _, m := range app.Auth.Methods {
+		name := strings.ToUpper(m.Type)
+		if m.Provider != "" {
+			name = m.Provider + " " + m.Type
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, " or ")
+}
+
+func authRuleMentions(app *ir.Application, phrase string) bool {
+	if app.Auth == nil {
+		return false
+	}
+	for _, rule := range app.Auth.Rules {
+		if strings.Contains(strings.ToLower(rule.Text), phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+func credentialsFromEnv(integ *ir.Integration) bool {
+	if len(integ.Credentials) == 0 {
+		return false
+	}
+	for _, envVar := range integ.Credentials {
+		if !strings.HasPrefix(envVar, "$") && !isEnvVarName(envVar) {
+			return false
+		}
+	}
+	return true
+}
+
+func statusIf(ok bool, yes, no string) string {
+	if ok {
+		return yes
+	}
+	return no
+}
+
+func yesNo(ok bool) string {
+	return statusIf(ok, "yes", "no")
+}
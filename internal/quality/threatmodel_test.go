@@ -0,0 +1,45 @@
+package quality
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func TestGenerateThreatModel(t *testing.T) {
+	app := authzApp()
+	app.Name = "TaskFlow"
+	app.Data = []*ir.DataModel{{Name: "Task", Fields: []*ir.DataField{
+		{Name: "title", Type: "text"},
+		{Name: "notes", Type: "text", Encrypted: true},
+	}}}
+	app.APIs[1].Params = []*ir.Param{{Name: "title"}, {Name: "notes"}}
+	app.APIs[1].Validation = []*ir.ValidationRule{{Field: "title", Rule: "not_empty"}}
+	app.APIs = append(app.APIs, &ir.Endpoint{Name: "ArchiveTask"})
+	app.Integrations = []*ir.Integration{{
+		Service:     "Stripe",
+		Type:        "payment",
+		Credentials: map[string]string{"api key": "STRIPE_KEY"},
+		Config:      map[string]string{"webhook_endpoint": "/webhooks/stripe"},
+	}}
+
+	doc := generateThreatModel(app)
+	for _, want := range []string{
+		"| API → Stripe | payment service | credentials from environment variables: yes |",
+		"### CreateTask\n\n**Data handled:** input title, notes; Task records (sensitive: notes (encrypted))",
+		"| Tampering | Malformed or malicious input | unvalidated: notes | partial |",
+		"| Information disclosure | Responses or storage expose data to the wrong party | notes encrypted at rest | present |",
+		"| Elevation of privilege | A role performs an action its policy forbids | policies checked for create task (no rule for Admin, allowed by default) | review |",
+		"| Spoofing | A caller acts as another user | no authentication on an endpoint that changes data | missing |",
+		"| Elevation of privilege | A role performs an action its policy forbids | the action can't be inferred from the endpoint name, so only sign-in is checked | missing |",
+		"| Tampering | Forged callbacks from the service | verify the provider's signature on /webhooks/stripe | review |",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("threat model missing %q", want)
+		}
+	}
+	if strings.Contains(doc, "HTTPS") {
+		t.Error("HTTPS should only be claimed with an environment URL")
+	}
+}
@@ -0,0 +1,503 @@
+package quality
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// TypeMismatch is a disagreement between generated layers about a data
+// model: a model or field one layer declares and another lacks, or enum
+// values that drift between them.
+type TypeMismatch struct {
+	Kind    string // "missing-model", "missing-field", "enum-drift"
+	Model   string
+	Field   string
+	Message string
+	Layers  []LayerState // what each layer declares, for reconciliation
+}
+
+// LayerState is what one generated file declares for a mismatched model
+// or field: "present", "missing", or the enum values it allows.
+type LayerState struct {
+	Layer string // "frontend types", "backend DTOs", "database schema"
+	File  string // relative to the output directory
+	State string
+}
+
+// typeLayer is one generated file's view of the data models: for each
+// model, its fields and the values of those that are enums. Names are
+// normalized so "dueDate", "due_date", and "DueDate" agree.
+type typeLayer struct {
+	Layer  string
+	File   string
+	Tables bool // models are keyed by plural table name
+	Models map[string]map[string][]string
+}
+
+// typeLayerFiles lists the files that declare the data models, by layer.
+// Only those the build generated are read.
+var typeLayerFiles = []struct {
+	layer, file string
+	read        func(string) map[string]map[string][]string
+}{
+	{"frontend types", "react/src/types/models.ts", readTSTypes},
+	{"frontend types", "vue/src/types/models.ts", readTSTypes},
+	{"frontend types", "svelte/src/lib/types.ts", readTSTypes},
+	{"frontend types", "angular/src/app/models/types.ts", readTSTypes},
+	{"backend DTOs", "python/schemas.py", readPydanticSchemas},
+	{"backend DTOs", "go/models/models.go", readGoModels},
+	{"database schema", "node/prisma/schema.prisma", readPrismaSchema},
+	{"database schema", "python/models.py", readSQLAlchemyModels},
+	{"database schema", "postgres/migrations/001_initial.sql", readSQLMigration},
+}
+
+// checkTypeSafety cross-checks the generated frontend types, backend DTOs,
+// and database schema against the data models the .human file declares.
+// Every layer is generated from the same IR, so any mismatch means a
+// generator has drifted out of step, and the app would fail at runtime
+// where the layers meet.
+func checkTypeSafety(app *ir.Application, outputDir string) ([]TypeMismatch, []*typeLayer) {
+	var layers []*typeLayer
+	for _, f := range typeLayerFiles {
+		data, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(f.file)))
+		if err != nil {
+			continue
+		}
+		layers = append(layers, &typeLayer{
+			Layer:  f.layer,
+			File:   f.file,
+			Tables: strings.HasSuffix(f.file, ".sql"),
+			Models: f.read(string(data)),
+		})
+	}
+	if len(layers) < 2 {
+		return nil, layers
+	}
+
+	var mismatches []TypeMismatch
+	for _, model := range app.Data {
+		var missing []string
+		for _, l := range layers {
+			if l.model(model.Name) == nil {
+				missing = append(missing, l.File)
+			}
+		}
+		if len(missing) > 0 {
+			mismatches = append(mismatches, TypeMismatch{
+				Kind:    "missing-model",
+				Model:   model.Name,
+				Message: fmt.Sprintf("%s is missing from %s", model.Name, strings.Join(missing, ", ")),
+				Layers:  layerStates(layers, func(l *typeLayer) string { return presence(l.model(model.Name) != nil) }),
+			})
+			continue
+		}
+
+		fields := model.Fields
+		if model.Versioned {
+			// every layer carries the version conflicting edits are checked against
+			fields = append(fields[:len(fields):len(fields)], &ir.DataField{Name: ir.VersionColumn, Type: "number"})
+		}
+		for _, field := range fields {
+			if isTimestampField(field.Name) {
+				continue // every layer keeps its own timestamps
+			}
+			key := normalizeTypeName(field.Name)
+			var lacking, having []string
+			for _, l := range layers {
+				if _, ok := l.model(model.Name)[key]; ok {
+					having = append(having, l.File)
+				} else {
+					lacking = append(lacking, l.File)
+				}
+			}
+			if len(lacking) > 0 {
+				msg := fmt.Sprintf("%s.%s is missing from %s", model.Name, field.Name, strings.Join(lacking, ", "))
+				if len(having) > 0 {
+					msg = fmt.Sprintf("%s.%s is in %s but missing from %s", model.Name, field.Name, strings.Join(having, ", "), strings.Join(lacking, ", "))
+				}
+				mismatches = append(mismatches, TypeMismatch{
+					Kind:    "missing-field",
+					Model:   model.Name,
+					Field:   field.Name,
+					Message: msg,
+					Layers: layerStates(layers, func(l *typeLayer) string {
+						_, ok := l.model(model.Name)[key]
+						return presence(ok)
+					}),
+				})
+				continue
+			}
+
+			if field.Type != "enum" || len(field.EnumValues) == 0 {
+				continue
+			}
+			var drifted []string
+			for _, l := range layers {
+				values := l.model(model.Name)[key]
+				if values != nil && !sameValues(values, field.EnumValues) {
+					drifted = append(drifted, l.File)
+				}
+			}
+			if len(drifted) > 0 {
+				mismatches = append(mismatches, TypeMismatch{
+					Kind:    "enum-drift",
+					Model:   model.Name,
+					Field:   field.Name,
+					Message: fmt.Sprintf("%s.%s allows different values in %s than the .human file declares (%s)", model.Name, field.Name, strings.Join(drifted, ", "), strings.Join(field.EnumValues, ", ")),
+					Layers: layerStates(layers, func(l *typeLayer) string {
+						values := l.model(model.Name)[key]
+						if values == nil {
+							return "any text"
+						}
+						return strings.Join(values, ", ")
+					}),
+				})
+			}
+		}
+	}
+	return mismatches, layers
+}
+
+// model returns the fields a layer declares for a model, or nil. SQL
+// tables are named for the plural of the model.
+func (l *typeLayer) model(name string) map[string][]string {
+	key := normalizeTypeName(name)
+	if !l.Tables {
+		return l.Models[key]
+	}
+	for _, table := range []string{key + "s", key + "es", strings.TrimSuffix(key, "y") + "ies", key} {
+		if fields, ok := l.Models[table]; ok {
+			return fields
+		}
+	}
+	return nil
+}
+
+func layerStates(layers []*typeLayer, state func(*typeLayer) string) []LayerState {
+	var states []LayerState
+	for _, l := range layers {
+		states = append(states, LayerState{Layer: l.Layer, File: l.File, State: state(l)})
+	}
+	return states
+}
+
+func presence(ok bool) string {
+	if ok {
+		return "present"
+	}
+	return "missing"
+}
+
+// normalizeTypeName folds the casing conventions of each layer together.
+func normalizeTypeName(name string) string {
+	return strings.NewReplacer("_", "", " ", "").Replace(strings.ToLower(name))
+}
+
+func isTimestampField(name string) bool {
+	switch normalizeTypeName(name) {
+	case "created", "createdat", "updated", "updatedat":
+		return true
+	}
+	return false
+}
+
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ── Layer readers ──
+
+var (
+	tsInterfaceRe = regexp.MustCompile(`^export interface (\w+) \{`)
+	tsFieldRe     = regexp.MustCompile(`^\s+(\w+)\??: (.+);$`)
+	quotedRe      = regexp.MustCompile(`["']([^"']*)["']`)
+
+	pyClassRe   = regexp.MustCompile(`^class (\w+?)(Create|Response)\(BaseModel\):`)
+	pyFieldRe   = regexp.MustCompile(`^    (\w+): `)
+	pyTableRe   = regexp.MustCompile(`^class (\w+)\(Base\):`)
+	pyColumnRe  = regexp.MustCompile(`^    (\w+) = Column\(`)
+	pyJoinRe    = regexp.MustCompile(`^(\w+) = Table\(`)
+	pyJoinColRe = regexp.MustCompile(`^    Column\('(\w+)'`)
+	goStructRe  = regexp.MustCompile(`^type (\w+) struct \{`)
+	goFieldRe   = regexp.MustCompile(`^\t(\w+)\s+\S+`)
+	prismaRe    = regexp.MustCompile(`^(model|enum) (\w+) \{`)
+	prismaColRe = regexp.MustCompile(`^\s+(\w+)(?:\s+(\w+))?`)
+	sqlEnumRe   = regexp.MustCompile(`^CREATE TYPE (\w+) AS ENUM \((.*)\);`)
+	sqlTableRe  = regexp.MustCompile(`^CREATE TABLE (?:IF NOT EXISTS )?(\w+) \(`)
+	sqlColRe    = regexp.MustCompile(`^\s+(\w+) (\w+)`)
+)
+
+// readTSTypes reads the interfaces of a TypeScript types file. A union of
+// string literals is an enum.
+func readTSTypes(src string) map[string]map[string][]string {
+	models := map[string]map[string][]string{}
+	var fields map[string][]string
+	for _, line := range strings.Split(src, "\n") {
+		if m := tsInterfaceRe.FindStringSubmatch(line); m != nil {
+			fields = map[string][]string{}
+			models[normalizeTypeName(m[1])] = fields
+			continue
+		}
+		if strings.HasPrefix(line, "}") {
+			fields = nil
+			continue
+		}
+		if m := tsFieldRe.FindStringSubmatch(line); m != nil && fields != nil {
+			fields[normalizeTypeName(m[1])] = literalUnion(m[2])
+		}
+	}
+	return models
+}
+
+// literalUnion returns the values of a union of string literals, such as
+// "admin" | "member", or nil for any other type.
+func literalUnion(typ string) []string {
+	var values []string
+	for _, part := range strings.Split(typ, "|") {
+		m := quotedRe.FindStringSubmatch(strings.TrimSpace(part))
+		if m == nil || len(m[0]) != len(strings.TrimSpace(part)) {
+			return nil
+		}
+		values = append(values, m[1])
+	}
+	return values
+}
+
+// readPydanticSchemas reads a model's Create and Response schemas together,
+// since passwords are accepted but never returned.
+func readPydanticSchemas(src string) map[string]map[string][]string {
+	models := map[string]map[string][]string{}
+	var fields map[string][]string
+	for _, line := range strings.Split(src, "\n") {
+		if m := pyClassRe.FindStringSubmatch(line); m != nil {
+			key := normalizeTypeName(m[1])
+			if models[key] == nil {
+				models[key] = map[string][]string{}
+			}
+			fields = models[key]
+			continue
+		}
+		if strings.HasPrefix(line, "class ") {
+			fields = nil
+			continue
+		}
+		if m := pyFieldRe.FindStringSubmatch(line); m != nil && fields != nil {
+			fields[normalizeTypeName(m[1])] = nil
+		}
+	}
+	return models
+}
+
+// readSQLAlchemyModels reads the columns of each SQLAlchemy model, and of
+// the association tables that stand in for join models.
+func readSQLAlchemyModels(src string) map[string]map[string][]string {
+	models := map[string]map[string][]string{}
+	var fields map[string][]string
+	for _, line := range strings.Split(src, "\n") {
+		m := pyTableRe.FindStringSubmatch(line)
+		if m == nil {
+			m = pyJoinRe.FindStringSubmatch(line)
+		}
+		if m != nil {
+			fields = map[string][]string{}
+			models[normalizeTypeName(m[1])] = fields
+			continue
+		}
+		if strings.HasPrefix(line, "class ") || strings.HasPrefix(line, ")") {
+			fields = nil
+			continue
+		}
+		if m := pyColumnRe.FindStringSubmatch(line); m != nil && fields != nil {
+			fields[normalizeTypeName(m[1])] = nil
+		} else if m := pyJoinColRe.FindStringSubmatch(line); m != nil && fields != nil {
+			fields[normalizeTypeName(m[1])] = nil
+		}
+	}
+	return models
+}
+
+// readGoModels reads the fields of each Go model struct.
+func readGoModels(src string) map[string]map[string][]string {
+	models := map[string]map[string][]string{}
+	var fields map[string][]string
+	for _, line := range strings.Split(src, "\n") {
+		if m := goStructRe.FindStringSubmatch(line); m != nil {
+			fields = map[string][]string{}
+			models[normalizeTypeName(m[1])] = fields
+			continue
+		}
+		if strings.HasPrefix(line, "}") {
+			fields = nil
+			continue
+		}
+		if m := goFieldRe.FindStringSubmatch(line); m != nil && fields != nil {
+			fields[normalizeTypeName(m[1])] = nil
+		}
+	}
+	return models
+}
+
+// readPrismaSchema reads the models of a Prisma schema, resolving fields
+// typed by an enum block to that enum's values.
+func readPrismaSchema(src string) map[string]map[string][]string {
+	models := map[string]map[string][]string{}
+	enums := map[string][]string{}
+	fieldTypes := map[string]map[string]string{}
+	var fields map[string][]string
+	var types map[string]string
+	var enum string
+	for _, line := range strings.Split(src, "\n") {
+		if m := prismaRe.FindStringSubmatch(line); m != nil {
+			fields, types, enum = nil, nil, ""
+			if m[1] == "enum" {
+				enum = m[2]
+				enums[enum] = []string{}
+			} else {
+				fields, types = map[string][]string{}, map[string]string{}
+				models[normalizeTypeName(m[2])] = fields
+				fieldTypes[normalizeTypeName(m[2])] = types
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "}") {
+			fields, types, enum = nil, nil, ""
+			continue
+		}
+		m := prismaColRe.FindStringSubmatch(line)
+		if m == nil || strings.HasPrefix(strings.TrimSpace(line), "@@") {
+			continue
+		}
+		switch {
+		case enum != "":
+			enums[enum] = append(enums[enum], m[1])
+		case fields != nil:
+			fields[normalizeTypeName(m[1])] = nil
+			types[normalizeTypeName(m[1])] = m[2]
+		}
+	}
+	for model, types := range fieldTypes {
+		for field, typ := range types {
+			if values, ok := enums[typ]; ok {
+				models[model][field] = values
+			}
+		}
+	}
+	return models
+}
+
+// readSQLMigration reads the tables of a SQL migration, keyed by table
+// name, resolving columns typed by an enum to its values.
+func readSQLMigration(src string) map[string]map[string][]string {
+	models := map[string]map[string][]string{}
+	enums := map[string][]string{}
+	var fields map[string][]string
+	for _, line := range strings.Split(src, "\n") {
+		if m := sqlEnumRe.FindStringSubmatch(line); m != nil {
+			var values []string
+			for _, v := range quotedRe.FindAllStringSubmatch(m[2], -1) {
+				values = append(values, v[1])
+			}
+			enums[strings.ToLower(m[1])] = values
+			continue
+		}
+		if m := sqlTableRe.FindStringSubmatch(line); m != nil {
+			fields = map[string][]string{}
+			models[normalizeTypeName(m[1])] = fields
+			continue
+		}
+		if strings.HasPrefix(line, ")") {
+			fields = nil
+			continue
+		}
+		if m := sqlColRe.FindStringSubmatch(line); m != nil && fields != nil {
+			fields[normalizeTypeName(m[1])] = enums[strings.ToLower(m[2])]
+		}
+	}
+	return models
+}
+
+// ── Report ──
+
+// renderTypeSafetyReport produces type-safety-report.md: the layers checked
+// and, for each mismatch, what every layer declares beside what the .human
+// file does, so the drifting layer stands out.
+func renderTypeSafetyReport(app *ir.Application, mismatches []TypeMismatch, layers []*typeLayer) string {
+	var b strings.Builder
+
+	b.WriteString("# Type Safety Report\n\n")
+	b.WriteString("Generated by Human compiler quality engine.\n\n")
+
+	if len(layers) < 2 {
+		b.WriteString("Fewer than two layers declare the data models, so there is nothing to cross-check.\n")
+		return b.String()
+	}
+
+	b.WriteString("## Layers Checked\n\n")
+	b.WriteString("| Layer | File |\n")
+	b.WriteString("|-------|------|\n")
+	for _, l := range layers {
+		fmt.Fprintf(&b, "| %s | `%s` |\n", l.Layer, l.File)
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "**Summary:** %d mismatches\n\n", len(mismatches))
+	if len(mismatches) == 0 {
+		b.WriteString("Every layer agrees with the data models the .human file declares.\n")
+		return b.String()
+	}
+
+	b.WriteString("## Reconciliation\n\n")
+	for _, m := range mismatches {
+		target := m.Model
+		if m.Field != "" {
+			target += "." + m.Field
+		}
+		fmt.Fprintf(&b, "### %s (%s)\n\n", target, m.Kind)
+		fmt.Fprintf(&b, "%s.\n\n", m.Message)
+		b.WriteString("| Layer | File | Declares |\n")
+		b.WriteString("|-------|------|----------|\n")
+		fmt.Fprintf(&b, "| source | `data %s` | %s |\n", m.Model, declaredState(app, m))
+		for _, s := range m.Layers {
+			fmt.Fprintf(&b, "| %s | `%s` | %s |\n", s.Layer, s.File, s.State)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("Every layer is generated from the .human file, so it is the source of truth: ")
+	b.WriteString("rebuild to regenerate the layers, and if a mismatch persists, report it — the generator for the disagreeing file is out of step.\n")
+	return b.String()
+}
+
+// declaredState is what the .human file declares for a mismatch.
+func declaredState(app *ir.Application, m TypeMismatch) string {
+	if m.Kind != "enum-drift" {
+		return "present"
+	}
+	for _, model := range app.Data {
+		if model.Name != m.Model {
+			continue
+		}
+		for _, f := range model.Fields {
+			if f.Name == m.Field {
+				return strings.Join(f.EnumValues, ", ")
+			}
+		}
+	}
+	return "present"
+}
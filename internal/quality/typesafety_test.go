@@ -0,0 +1,121 @@
+package quality
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func typeSafetyApp() *ir.Application {
+	return &ir.Application{Data: []*ir.DataModel{{
+		Name: "Task",
+		Fields: []*ir.DataField{
+			{Name: "title", Type: "text", Required: true},
+			{Name: "due_date", Type: "date"},
+			{Name: "status", Type: "enum", EnumValues: []string{"pending", "done"}},
+		},
+	}}}
+}
+
+const taskTypes = `export interface Task {
+  id: string;
+  title: string;
+  dueDate?: string;
+  status: "pending" | "done";
+}
+`
+
+const taskPrisma = `model Task {
+  id        String   @id @default(cuid())
+  title     String
+  dueDate   DateTime?
+  status    TaskStatus
+  createdAt DateTime @default(now())
+}
+
+enum TaskStatus {
+  pending
+  done
+}
+`
+
+func TestCheckTypeSafety_Agreeing(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "react", "src", "types", "models.ts"), taskTypes)
+	writeTestFile(t, filepath.Join(dir, "node", "prisma", "schema.prisma"), taskPrisma)
+	writeTestFile(t, filepath.Join(dir, "postgres", "migrations", "001_initial.sql"), `CREATE TYPE task_status AS ENUM ('done', 'pending');
+
+CREATE TABLE tasks (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  title TEXT NOT NULL,
+  due_date DATE,
+  status task_status NOT NULL
+);
+`)
+
+	mismatches, layers := checkTypeSafety(typeSafetyApp(), dir)
+	if len(layers) != 3 {
+		t.Fatalf("expected 3 layers, got %d", len(layers))
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("expected no mismatches, got %+v", mismatches)
+	}
+}
+
+func TestCheckTypeSafety_Drift(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "react", "src", "types", "models.ts"), strings.Replace(taskTypes, "  dueDate?: string;\n", "", 1))
+	writeTestFile(t, filepath.Join(dir, "node", "prisma", "schema.prisma"), strings.Replace(taskPrisma, "  done\n", "  done\n  archived\n", 1))
+
+	app := typeSafetyApp()
+	mismatches, layers := checkTypeSafety(app, dir)
+	if len(mismatches) != 2 {
+		t.Fatalf("expected 2 mismatches, got %+v", mismatches)
+	}
+	if m := mismatches[0]; m.Kind != "missing-field" || m.Message != "Task.due_date is in node/prisma/schema.prisma but missing from react/src/types/models.ts" {
+		t.Errorf("unexpected first mismatch: %+v", m)
+	}
+	if m := mismatches[1]; m.Kind != "enum-drift" || m.Field != "status" || !strings.Contains(m.Message, "node/prisma/schema.prisma") {
+		t.Errorf("unexpected second mismatch: %+v", m)
+	}
+
+	report := renderTypeSafetyReport(app, mismatches, layers)
+	for _, want := range []string{
+		"**Summary:** 2 mismatches",
+		"### Task.status (enum-drift)",
+		"| source | `data Task` | pending, done |",
+		"| database schema | `node/prisma/schema.prisma` | pending, done, archived |",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestCheckTypeSafety_MissingModel(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "svelte", "src", "lib", "types.ts"), taskTypes)
+	writeTestFile(t, filepath.Join(dir, "python", "models.py"), "class User(Base):\n    id = Column(String, primary_key=True)\n")
+
+	mismatches, _ := checkTypeSafety(typeSafetyApp(), dir)
+	if len(mismatches) != 1 || mismatches[0].Kind != "missing-model" || mismatches[0].Message != "Task is missing from python/models.py" {
+		t.Errorf("expected Task missing from models.py, got %+v", mismatches)
+	}
+}
+
+func TestRunFailsOnTypeMismatch(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "react", "src", "types", "models.ts"), taskTypes)
+	writeTestFile(t, filepath.Join(dir, "node", "prisma", "schema.prisma"), strings.Replace(taskPrisma, "  title     String\n", "", 1))
+
+	_, err := Run(typeSafetyApp(), dir)
+	if err == nil || !strings.Contains(err.Error(), "Task.title is in react/src/types/models.ts but missing from node/prisma/schema.prisma") {
+		t.Fatalf("expected the build to fail on the missing field, got %v", err)
+	}
+	if report, _ := os.ReadFile(filepath.Join(dir, "type-safety-report.md")); !strings.Contains(string(report), "## Reconciliation") {
+		t.Errorf("expected a reconciliation report, got:\n%s", report)
+	}
+}
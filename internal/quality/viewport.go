@@ -0,0 +1,83 @@
+package quality
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// viewportMetaRe matches a viewport meta tag, capturing its content.
+var viewportMetaRe = regexp.MustCompile(`<meta\s+name=["']viewport["']\s+content=["']([^"']*)["']`)
+
+// checkViewport verifies that the frontend's HTML shell sets a viewport
+// that follows the device's width and lets users zoom. Without one, phones
+// render the app at desktop width, zoomed out, and responsive layouts never
+// reach their breakpoints.
+func checkViewport(app *ir.Application, outputDir string) []PerformanceFinding {
+	if app.Config == nil || app.Config.Frontend == "" {
+		return nil
+	}
+	shells := map[string]string{
+		"react":   filepath.Join("react", "index.html"),
+		"vue":     filepath.Join("vue", "index.html"),
+		"svelte":  filepath.Join("svelte", "src", "app.html"),
+		"angular": filepath.Join("angular", "src", "index.html"),
+	}
+	fe := strings.ToLower(app.Config.Frontend)
+	for _, name := range []string{"react", "vue", "svelte", "angular"} {
+		if strings.Contains(fe, name) {
+			return checkViewportMeta(app, outputDir, shells[name])
+		}
+	}
+	return nil
+}
+
+// checkViewportMeta reads the viewport meta tag of an HTML shell, if it
+// has been generated yet.
+func checkViewportMeta(app *ir.Application, outputDir, shell string) []PerformanceFinding {
+	data, err := os.ReadFile(filepath.Join(outputDir, shell))
+	if err != nil {
+		return nil
+	}
+	severity := "info"
+	if ir.HasResponsivePages(app) {
+		severity = "warning"
+	}
+
+	m := viewportMetaRe.FindStringSubmatch(string(data))
+	if m == nil {
+		return []PerformanceFinding{{
+			Kind:     "viewport",
+			Severity: severity,
+			Target:   shell,
+			Message:  "No viewport meta tag — phones render the app at desktop width, zoomed out",
+			Fix:      `Add <meta name="viewport" content="width=device-width, initial-scale=1"> to the head`,
+		}}
+	}
+
+	var findings []PerformanceFinding
+	content := strings.ReplaceAll(strings.ToLower(m[1]), " ", "")
+	if !strings.Contains(content, "width=device-width") {
+		findings = append(findings, PerformanceFinding{
+			Kind:     "viewport",
+			Severity: severity,
+			Target:   shell,
+			Message:  "The viewport doesn't follow the device's width — responsive breakpoints never apply on phones",
+			Fix:      "Set width=device-width in the viewport meta tag",
+		})
+	}
+	if strings.Contains(content, "user-scalable=no") || strings.Contains(content, "user-scalable=0") ||
+		strings.Contains(content, "maximum-scale=1,") || strings.HasSuffix(content, "maximum-scale=1") {
+		findings = append(findings, PerformanceFinding{
+			Kind:     "viewport",
+			Severity: "warning",
+			Target:   shell,
+			Message:  "The viewport stops users zooming in",
+			Fix:      "Remove user-scalable=no and maximum-scale from the viewport meta tag",
+		})
+	}
+	return findings
+}
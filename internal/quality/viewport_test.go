@@ -0,0 +1,53 @@
+package quality
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+func TestCheckViewport(t *testing.T) {
+	dir := t.TempDir()
+	app := &ir.Application{
+		Config: &ir.BuildConfig{Frontend: "React"},
+		Pages: []*ir.Page{{Name: "Home", Content: []*ir.Action{
+			{Type: "configure", Text: "make the layout responsive for mobile and tablet"},
+		}}},
+	}
+	path := filepath.Join(dir, "react", "index.html")
+
+	// Not generated yet: nothing to check.
+	if findings := checkViewport(app, dir); len(findings) != 0 {
+		t.Errorf("expected no findings without an index.html, got %+v", findings)
+	}
+
+	writeTestFile(t, path, `<html><head><title>App</title></head></html>`)
+	findings := checkViewport(app, dir)
+	if len(findings) != 1 || findings[0].Kind != "viewport" || findings[0].Severity != "warning" {
+		t.Fatalf("expected a warning for a missing viewport on a responsive app, got %+v", findings)
+	}
+
+	writeTestFile(t, path, `<meta name="viewport" content="width=1024, user-scalable=no" />`)
+	findings = checkViewport(app, dir)
+	if len(findings) != 2 || !strings.Contains(findings[0].Message, "device's width") || !strings.Contains(findings[1].Message, "zooming") {
+		t.Errorf("expected fixed-width and no-zoom findings, got %+v", findings)
+	}
+
+	writeTestFile(t, path, `<meta name="viewport" content="width=device-width, initial-scale=1.0" />`)
+	if findings := checkViewport(app, dir); len(findings) != 0 {
+		t.Errorf("expected no findings for a device-width viewport, got %+v", findings)
+	}
+}
+
+func TestCheckViewport_NotResponsive(t *testing.T) {
+	dir := t.TempDir()
+	app := &ir.Application{Config: &ir.BuildConfig{Frontend: "Svelte"}}
+	writeTestFile(t, filepath.Join(dir, "svelte", "src", "app.html"), `<html><head></head></html>`)
+
+	findings := checkViewport(app, dir)
+	if len(findings) != 1 || findings[0].Severity != "info" {
+		t.Errorf("expected an info finding when no page is responsive, got %+v", findings)
+	}
+}
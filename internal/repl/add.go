@@ -70,23 +70,16 @@ func cmdAdd(r *REPL, args []string) {
 		fmt.Fprintln(r.out, cli.Warn(fmt.Sprintf("Syntax issue: %s", result.ParseError)))
 	}
 
-	showDiff(r, string(source), result.Code)
-
 	_, yesFlag := extractYesFlag(args)
-	if r.shouldAutoAccept(yesFlag) {
-		fmt.Fprintln(r.out, cli.Muted("  Auto-applying changes..."))
-	} else {
-		fmt.Fprintf(r.out, "Apply changes? (y/n): ")
-		answer, ok := r.scanLine()
-		if !ok || !isYes(answer) {
-			fmt.Fprintln(r.out, cli.Info("No changes made."))
-			return
-		}
+	newSource, ok := reviewChange(r, "Apply changes?", string(source), result.Code, r.shouldAutoAccept(yesFlag))
+	if !ok {
+		fmt.Fprintln(r.out, cli.Info("No changes made."))
+		return
 	}
 
 	backupFile(r.projectFile)
 
-	if err := os.WriteFile(r.projectFile, []byte(result.Code), 0644); err != nil {
+	if err := os.WriteFile(r.projectFile, []byte(newSource), 0644); err != nil {
 		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
 		return
 	}
@@ -7,7 +7,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/barun-bash/human/internal/build"
 	"github.com/barun-bash/human/internal/cli"
 	"github.com/barun-bash/human/internal/cmdutil"
 	"github.com/barun-bash/human/internal/config"
@@ -38,7 +37,7 @@ func (r *REPL) registerCommands() {
 		{
 			Name:        "/new",
 			Description: "Create a new Human project",
-			Usage:       "/new [name]",
+			Usage:       "/new [--yes] [name]",
 			Handler:     cmdNew,
 		},
 		{
@@ -204,6 +203,12 @@ func (r *REPL) registerCommands() {
 			Handler:     cmdSyntax,
 			Complete:    completeSyntax,
 		},
+		{
+			Name:        "/snippet",
+			Description: "Print a ready-to-paste block for a pattern",
+			Usage:       "/snippet [pattern-id]",
+			Handler:     cmdSnippet,
+		},
 		{
 			Name:        "/split",
 			Description: "Split project into concern-based multi-file layout",
@@ -319,17 +324,20 @@ func cmdOpen(r *REPL, args []string) {
 func cmdNew(r *REPL, args []string) {
 	name := ""
 	multi := false
+	yes := false
 	for _, arg := range args {
 		switch arg {
 		case "--multi", "-m":
 			multi = true
+		case "--yes", "-y":
+			yes = true
 		default:
 			if !strings.HasPrefix(arg, "-") && name == "" {
 				name = arg
 			}
 		}
 	}
-	outPath, err := cmdutil.InitProject(name, multi, r.in, r.out)
+	outPath, err := cmdutil.InitProject(name, multi, yes, r.in, r.out)
 	if err != nil {
 		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
 		return
@@ -405,32 +413,8 @@ func cmdBuild(r *REPL, args []string) {
 		}
 	}
 
-	// Pre-plan build stages for progress display.
-	planResult, planErr := cmdutil.ParseAndAnalyze(r.projectFile)
-	var progressBox *cli.ProgressBox
-	if planErr == nil && planResult.App != nil {
-		stages := build.PlanStages(planResult.App)
-		title := fmt.Sprintf("Building %s", r.projectName)
-		progressBox = cli.NewProgressBox(r.out, title, stages)
-		progressBox.Start()
-	}
-
-	progress := func(stage string) {
-		if progressBox != nil {
-			progressBox.Update(stage)
-		} else {
-			fmt.Fprintf(r.out, "  %s %s...\n", cli.Accent("\u25b6"), stage)
-		}
-	}
-	if _, _, _, _, err := cmdutil.FullBuildWithProgress(r.projectFile, progress); err != nil {
-		if progressBox != nil {
-			progressBox.Finish()
-		}
+	if _, _, _, _, err := cmdutil.FullBuildWithProgressBox(r.projectFile, "", r.out); err != nil {
 		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
-		return
-	}
-	if progressBox != nil {
-		progressBox.Finish()
 	}
 }
 
@@ -475,13 +459,28 @@ func cmdDeploy(r *REPL, args []string) {
 		fmt.Fprintln(r.errOut, cli.Error("Only Docker deploy is supported from the REPL. Use the CLI for Terraform/AWS/GCP."))
 		return
 	}
+	if !r.requireTrust("deploy it") {
+		return
+	}
 
-	if _, _, _, _, err := cmdutil.FullBuild(r.projectFile); err != nil {
+	if _, _, _, _, err := cmdutil.FullBuild(r.projectFile, ""); err != nil {
 		fmt.Fprintln(r.errOut, cli.Error(fmt.Sprintf("Build failed: %v", err)))
 		return
 	}
 
 	outputDir := filepath.Join(".human", "output")
+	hook := cmdutil.HookContext{
+		Hook:      cmdutil.HookPreDeploy,
+		Source:    r.projectFile,
+		IRPath:    cmdutil.IntentPath(r.projectFile),
+		OutputDir: outputDir,
+		Target:    deployTarget,
+		DryRun:    dryRun,
+	}
+	if err := cmdutil.RunHooks(".", hook); err != nil {
+		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
+		return
+	}
 	if err := cmdutil.DeployDocker(result.App, outputDir, dryRun); err != nil {
 		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
 	}
@@ -527,6 +526,9 @@ func cmdRun(r *REPL, args []string) {
 	outputDir := filepath.Join(".human", "output")
 	startSh := filepath.Join(outputDir, "start.sh")
 	pkgJSON := filepath.Join(outputDir, "package.json")
+	if !r.requireTrust("run the generated app") {
+		return
+	}
 
 	if _, err := os.Stat(startSh); err == nil {
 		fmt.Fprintln(r.out, cli.Info("Starting application via start.sh..."))
@@ -553,6 +555,9 @@ func cmdTest(r *REPL, args []string) {
 		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
 		return
 	}
+	if !r.requireTrust("run the generated tests") {
+		return
+	}
 	fmt.Fprintln(r.out, cli.Info("Running tests..."))
 	if err := cmdutil.RunCommandSilent(outputDir, "npm", "test"); err != nil {
 		fmt.Fprintln(r.errOut, cli.Error(fmt.Sprintf("Tests failed: %v", err)))
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -100,27 +99,19 @@ func editOnce(r *REPL, connector *llm.Connector, llmCfg *config.LLMConfig, instr
 		fmt.Fprintln(r.out, cli.Warn(fmt.Sprintf("Syntax issue: %s", result.ParseError)))
 	}
 
-	// Show diff.
+	// Show the diff and accept it whole or hunk by hunk.
 	fmt.Fprintln(r.out)
-	showDiff(r, currentSource, result.Code)
-
-	// Accept?
-	if autoAccept {
-		fmt.Fprintln(r.out, cli.Muted("  Auto-applying changes..."))
-	} else {
-		fmt.Fprintf(r.out, "Apply changes? (y/n): ")
-		answer, ok := r.scanLine()
-		if !ok || !isYes(answer) {
-			fmt.Fprintln(r.out, cli.Info("Changes discarded."))
-			return currentSource, false, history
-		}
+	newSource, ok := reviewChange(r, "Apply changes?", currentSource, result.Code, autoAccept)
+	if !ok {
+		fmt.Fprintln(r.out, cli.Info("Changes discarded."))
+		return currentSource, false, history
 	}
 
 	// Create backup before applying.
 	backupFile(r.projectFile)
 
 	// Write to disk.
-	if err := os.WriteFile(r.projectFile, []byte(result.Code), 0644); err != nil {
+	if err := os.WriteFile(r.projectFile, []byte(newSource), 0644); err != nil {
 		fmt.Fprintln(r.errOut, cli.Error(fmt.Sprintf("Could not write file: %v", err)))
 		return currentSource, false, history
 	}
@@ -136,7 +127,7 @@ func editOnce(r *REPL, connector *llm.Connector, llmCfg *config.LLMConfig, instr
 		llm.Message{Role: llm.RoleAssistant, Content: result.RawResponse},
 	)
 
-	return result.Code, true, newHistory
+	return newSource, true, newHistory
 }
 
 // editInteractive runs a blocking sub-REPL loop for multi-turn editing.
@@ -245,103 +236,17 @@ func backupFile(projectFile string) {
 	_ = os.WriteFile(backupPath(projectFile), data, 0644)
 }
 
-// ── Diff display ──
-
-// showDiff displays the differences between old and new source.
-// Uses system `diff -u` for a unified diff, falls back to a line-count summary.
-func showDiff(r *REPL, oldSrc, newSrc string) {
-	if oldSrc == newSrc {
-		fmt.Fprintln(r.out, cli.Muted("  No changes."))
-		return
-	}
-
-	// Try system diff.
-	diffOutput, err := systemDiff(oldSrc, newSrc)
-	if err == nil && strings.TrimSpace(diffOutput) != "" {
-		fmt.Fprintln(r.out, cli.Heading("Changes"))
-		fmt.Fprintln(r.out, diffOutput)
-		return
-	}
-
-	// Fallback: line-count summary.
-	oldLines := strings.Split(oldSrc, "\n")
-	newLines := strings.Split(newSrc, "\n")
-	added, removed := diffSummary(oldLines, newLines)
-	fmt.Fprintf(r.out, "  %s lines, %s lines (total: %d → %d)\n",
-		cli.Success(fmt.Sprintf("+%d", added)),
-		cli.Error(fmt.Sprintf("-%d", removed)),
-		len(oldLines), len(newLines))
-}
-
-// systemDiff runs `diff -u` on two strings via temp files.
-func systemDiff(oldSrc, newSrc string) (string, error) {
-	tmpOld, err := os.CreateTemp("", "human-edit-old-*.human")
-	if err != nil {
-		return "", err
-	}
-	defer os.Remove(tmpOld.Name())
-
-	tmpNew, err := os.CreateTemp("", "human-edit-new-*.human")
-	if err != nil {
-		return "", err
-	}
-	defer os.Remove(tmpNew.Name())
-
-	if _, err := tmpOld.WriteString(oldSrc); err != nil {
-		return "", err
-	}
-	tmpOld.Close()
-
-	if _, err := tmpNew.WriteString(newSrc); err != nil {
-		return "", err
-	}
-	tmpNew.Close()
-
-	cmd := exec.Command("diff", "-u", "--label", "before", "--label", "after", tmpOld.Name(), tmpNew.Name())
-	out, err := cmd.CombinedOutput()
-
-	// diff exits with code 1 when files differ — that's not an error for us.
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			return string(out), nil
-		}
-		return "", err
-	}
-
-	return string(out), nil
-}
-
-// diffSummary counts added and removed lines using bag-of-lines comparison.
-func diffSummary(oldLines, newLines []string) (added, removed int) {
-	oldBag := make(map[string]int, len(oldLines))
-	for _, line := range oldLines {
-		oldBag[line]++
-	}
-
-	newBag := make(map[string]int, len(newLines))
-	for _, line := range newLines {
-		newBag[line]++
-	}
-
-	for line, count := range newBag {
-		if oldCount, ok := oldBag[line]; ok {
-			if count > oldCount {
-				added += count - oldCount
-			}
-		} else {
-			added += count
-		}
-	}
+// ── Diff review ──
 
-	for line, count := range oldBag {
-		if newCount, ok := newBag[line]; ok {
-			if count > newCount {
-				removed += count - newCount
-			}
-		} else {
-			removed += count
-		}
+// reviewChange shows an LLM's change to the loaded file as a colored diff
+// and asks whether to apply it, whole or hunk by hunk. With autoAccept it
+// shows the diff and takes all of it. Returns the source to write and
+// whether anything was accepted.
+func reviewChange(r *REPL, question, oldSrc, newSrc string, autoAccept bool) (string, bool) {
+	if autoAccept {
+		cli.PrintDiff(r.out, oldSrc, newSrc)
+		fmt.Fprintln(r.out, cli.Muted("  Auto-applying changes..."))
+		return newSrc, true
 	}
-
-	return added, removed
+	return cli.ReviewDiff(r.out, r.scanLine, question, oldSrc, newSrc)
 }
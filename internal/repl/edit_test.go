@@ -284,57 +284,49 @@ func TestBackupFile(t *testing.T) {
 	}
 }
 
-// ── Diff helper tests ──
+// ── Diff review tests ──
 
-func TestDiffSummary_NoChanges(t *testing.T) {
-	lines := []string{"a", "b", "c"}
-	added, removed := diffSummary(lines, lines)
-	if added != 0 || removed != 0 {
-		t.Errorf("diffSummary(same, same) = +%d/-%d, want +0/-0", added, removed)
-	}
-}
-
-func TestDiffSummary_AddedLines(t *testing.T) {
-	old := []string{"a", "b"}
-	new := []string{"a", "b", "c", "d"}
-	added, removed := diffSummary(old, new)
-	if added != 2 {
-		t.Errorf("added = %d, want 2", added)
+func TestReviewChange_NoChanges(t *testing.T) {
+	cli.ColorEnabled = false
+	r, out, _ := newTestREPL("")
+	if _, ok := reviewChange(r, "Apply changes?", "same", "same", false); ok {
+		t.Error("an unchanged source should not count as accepted")
 	}
-	if removed != 0 {
-		t.Errorf("removed = %d, want 0", removed)
+	if !strings.Contains(out.String(), "No changes") {
+		t.Errorf("expected 'No changes', got: %s", out.String())
 	}
 }
 
-func TestDiffSummary_RemovedLines(t *testing.T) {
-	old := []string{"a", "b", "c"}
-	new := []string{"a"}
-	added, removed := diffSummary(old, new)
-	if added != 0 {
-		t.Errorf("added = %d, want 0", added)
+func TestReviewChange_PickHunks(t *testing.T) {
+	cli.ColorEnabled = false
+	oldSrc := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	newSrc := "A\nb\nc\nd\ne\nf\ng\nh\ni\nj\nK\n"
+	r, out, _ := newTestREPL("p\nn\ny\n")
+	got, ok := reviewChange(r, "Apply changes?", oldSrc, newSrc, false)
+	if !ok {
+		t.Fatal("the second hunk was accepted")
 	}
-	if removed != 2 {
-		t.Errorf("removed = %d, want 2", removed)
+	if want := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nK\n"; got != want {
+		t.Errorf("source = %q, want only the second hunk applied: %q", got, want)
 	}
-}
-
-func TestDiffSummary_MixedChanges(t *testing.T) {
-	old := []string{"a", "b", "c"}
-	new := []string{"a", "d", "e"}
-	added, removed := diffSummary(old, new)
-	if added != 2 {
-		t.Errorf("added = %d, want 2", added)
+	for _, want := range []string{"-a\n+A\n", "-k\n+K\n", "Apply hunk 2/2?", "1 of 2 hunks accepted"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
 	}
-	if removed != 2 {
-		t.Errorf("removed = %d, want 2", removed)
+	if strings.Contains(out.String(), "\n e\n") {
+		t.Error("the diff should show the change, not reprint the whole file")
 	}
 }
 
-func TestShowDiff_NoChanges(t *testing.T) {
+func TestReviewChange_AutoAccept(t *testing.T) {
 	cli.ColorEnabled = false
 	r, out, _ := newTestREPL("")
-	showDiff(r, "same", "same")
-	if !strings.Contains(out.String(), "No changes") {
-		t.Errorf("expected 'No changes', got: %s", out.String())
+	got, ok := reviewChange(r, "Apply changes?", "a\n", "b\n", true)
+	if !ok || got != "b\n" {
+		t.Errorf("reviewChange = %q, %v; want the new source", got, ok)
+	}
+	if !strings.Contains(out.String(), "-a\n+b\n") {
+		t.Errorf("the diff should still be shown:\n%s", out.String())
 	}
 }
@@ -2,6 +2,7 @@ package repl
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/barun-bash/human/internal/cli"
@@ -27,6 +28,10 @@ func cmdFix(r *REPL, args []string) {
 		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
 		return
 	}
+	if _, files, err = parser.ParseProject(filepath.Dir(files[0]), files); err != nil {
+		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
+		return
+	}
 
 	result, err := fixer.Analyze(files)
 	if err != nil {
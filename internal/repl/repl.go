@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/cmdutil"
 	"github.com/barun-bash/human/internal/config"
 	"github.com/barun-bash/human/internal/llm/prompts"
 	"github.com/barun-bash/human/internal/mcp"
@@ -18,17 +19,17 @@ import (
 
 // REPL is the interactive Human compiler shell.
 type REPL struct {
-	projectFile string
-	projectName string
-	version     string
-	in          io.Reader
-	out         io.Writer
-	errOut      io.Writer
-	scanner     *bufio.Scanner          // used for scanLine() sub-prompts
-	rl          *readline.Instance      // nil when stdin is not a terminal
-	history     *History
-	commands    map[string]*Command
-	aliases     map[string]string
+	projectFile     string
+	projectName     string
+	version         string
+	in              io.Reader
+	out             io.Writer
+	errOut          io.Writer
+	scanner         *bufio.Scanner     // used for scanLine() sub-prompts
+	rl              *readline.Instance // nil when stdin is not a terminal
+	history         *History
+	commands        map[string]*Command
+	aliases         map[string]string
 	running         bool
 	settings        *config.GlobalSettings
 	lastSuggestions []prompts.Suggestion   // cached from last /suggest, cleared on source change
@@ -95,6 +96,7 @@ func (r *REPL) Run() {
 	r.printBanner()
 	r.showUpdateNotification()
 	r.running = true
+	cmdutil.TrustPrompt = r.confirm
 
 	if r.rl != nil && r.rl.IsTTY() {
 		r.runReadline()
@@ -369,3 +371,20 @@ func (r *REPL) requireProject() bool {
 	}
 	return true
 }
+
+// requireTrust checks that the working directory is a trusted workspace
+// before action runs the project's code, asking the user if it is not.
+func (r *REPL) requireTrust(action string) bool {
+	if err := cmdutil.RequireTrust(".", action); err != nil {
+		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
+		return false
+	}
+	return true
+}
+
+// confirm asks a yes/no question on the REPL's input.
+func (r *REPL) confirm(question string) bool {
+	fmt.Fprintf(r.out, "%s (y/n): ", question)
+	answer, ok := r.scanLine()
+	return ok && isYes(answer)
+}
@@ -69,25 +69,17 @@ func cmdRewrite(r *REPL, args []string) {
 		fmt.Fprintln(r.out, cli.Warn(fmt.Sprintf("Syntax issue: %s", result.ParseError)))
 	}
 
-	// Show diff.
-	showDiff(r, string(source), result.Code)
-
-	// Accept/reject.
+	// Show the diff and accept it whole or hunk by hunk.
 	_, yesFlag := extractYesFlag(args)
-	if r.shouldAutoAccept(yesFlag) {
-		fmt.Fprintln(r.out, cli.Muted("  Auto-applying rewrite..."))
-	} else {
-		fmt.Fprintf(r.out, "Apply rewrite? (y/n): ")
-		answer, ok := r.scanLine()
-		if !ok || !isYes(answer) {
-			fmt.Fprintln(r.out, cli.Info("Rewrite discarded."))
-			return
-		}
+	newSource, ok := reviewChange(r, "Apply rewrite?", string(source), result.Code, r.shouldAutoAccept(yesFlag))
+	if !ok {
+		fmt.Fprintln(r.out, cli.Info("Rewrite discarded."))
+		return
 	}
 
 	backupFile(r.projectFile)
 
-	if err := os.WriteFile(r.projectFile, []byte(result.Code), 0644); err != nil {
+	if err := os.WriteFile(r.projectFile, []byte(newSource), 0644); err != nil {
 		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
 		return
 	}
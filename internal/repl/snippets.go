@@ -0,0 +1,425 @@
+package repl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/barun-bash/human/internal/analyzer"
+	"github.com/barun-bash/human/internal/build"
+	"github.com/barun-bash/human/internal/cli"
+	"github.com/barun-bash/human/internal/cmdutil"
+	cerr "github.com/barun-bash/human/internal/errors"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/parser"
+)
+
+// snippetSession is the state of human repl: the .human lines accepted so
+// far, the IR built from them, and the diagnostics already shown.
+type snippetSession struct {
+	r        *REPL
+	lines    []string
+	app      *ir.Application // nil until the lines build
+	reported map[string]bool // diagnostics shown, by their formatted text
+	counts   string          // what the lines declare, as last shown
+}
+
+// RunSnippets runs human repl. Each line typed is added to a .human source
+// that is parsed and analyzed straight away; a line that doesn't parse is
+// dropped with its error, so the source always parses. Indented lines
+// continue the block above them. Lines starting with ":" are commands:
+// :ir and :yaml show the IR, :gen previews what a generator emits for it.
+func (r *REPL) RunSnippets() {
+	s := &snippetSession{r: r, reported: map[string]bool{}}
+	r.history = NewHistoryWithPath(snippetHistoryPath())
+	r.running = true
+
+	fmt.Fprintln(r.out, cli.Heading(fmt.Sprintf("Human %s — snippet REPL", r.version)))
+	fmt.Fprintln(r.out, cli.Muted("Type .human lines; indent to continue a block. :help lists commands, :quit exits."))
+	fmt.Fprintln(r.out)
+
+	for r.running {
+		line, ok := s.readLine()
+		if !ok {
+			fmt.Fprintln(r.out)
+			fmt.Fprintln(r.out, "Goodbye.")
+			break
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		r.history.Add(line)
+		if strings.HasPrefix(trimmed, ":") {
+			s.command(trimmed)
+			continue
+		}
+		s.add(strings.TrimRight(line, " \t\r"))
+	}
+	r.history.Save()
+}
+
+// snippetHistoryPath keeps human repl's history apart from the shell's, so
+// recalling a line brings back .human rather than slash commands.
+func snippetHistoryPath() string {
+	path := historyPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "repl_history")
+}
+
+// readLine reads one line with its indentation. The prompt changes inside
+// a block, where the next line is likely to be indented.
+func (s *snippetSession) readLine() (string, bool) {
+	prompt := "human> "
+	if n := len(s.lines); n > 0 {
+		last := s.lines[n-1]
+		if strings.HasSuffix(last, ":") || strings.HasPrefix(last, " ") || strings.HasPrefix(last, "\t") {
+			prompt = "   ... "
+		}
+	}
+	if cli.ColorEnabled {
+		prompt = cli.Accent(strings.TrimRight(prompt, " ")) + " "
+	}
+
+	r := s.r
+	if r.rl != nil && r.rl.IsTTY() {
+		r.rl.SetPrompt(prompt)
+		r.rl.SetHistory(r.history.Entries())
+		line, err := r.rl.ReadLine()
+		return line, err == nil
+	}
+	fmt.Fprint(r.out, prompt)
+	if !r.scanner.Scan() {
+		return "", false
+	}
+	return r.scanner.Text(), true
+}
+
+// source returns the accepted lines as a .human source.
+func (s *snippetSession) source() string {
+	if len(s.lines) == 0 {
+		return ""
+	}
+	return strings.Join(s.lines, "\n") + "\n"
+}
+
+// add parses the source with line added, keeping the line only if it parses.
+func (s *snippetSession) add(line string) {
+	lines := append(s.lines[:len(s.lines):len(s.lines)], line)
+	prog, err := parser.Parse(strings.Join(lines, "\n") + "\n")
+	if err != nil {
+		fmt.Fprintln(s.r.errOut, cli.Error(err.Error()))
+		fmt.Fprintln(s.r.out, cli.Muted("  Line dropped."))
+		return
+	}
+	s.lines = lines
+	s.check(prog)
+}
+
+// check builds and analyzes prog, showing the diagnostics that weren't
+// shown for an earlier line, then what the source declares if that changed.
+func (s *snippetSession) check(prog *parser.Program) {
+	out := s.r.out
+	app, err := ir.Build(prog)
+	if err != nil {
+		s.app = nil
+		fmt.Fprintln(s.r.errOut, cli.Error(err.Error()))
+		return
+	}
+	s.app = app
+
+	errs := analyzer.Analyze(app, "")
+	shown := map[string]bool{}
+	for _, d := range append(errs.Warnings(), errs.Errors()...) {
+		key := d.Format()
+		shown[key] = true
+		if !s.reported[key] {
+			s.printDiagnostic(d)
+		}
+	}
+	s.reported = shown
+
+	if errs.HasErrors() {
+		fmt.Fprintln(out, cli.Muted(fmt.Sprintf("  %d error(s) so far", len(errs.Errors()))))
+		return
+	}
+	if counts := cmdutil.ProgramCounts(prog); counts != s.counts {
+		s.counts = counts
+		if counts != "" {
+			fmt.Fprintln(out, cli.Muted("  "+counts))
+		}
+	}
+}
+
+func (s *snippetSession) printDiagnostic(d *cerr.CompilerError) {
+	w := s.r.errOut
+	if d.Severity == cerr.SeverityWarning {
+		fmt.Fprintln(w, cli.Warn(d.Format()))
+	} else {
+		fmt.Fprintln(w, cli.Error(d.Format()))
+	}
+	if d.Suggestion != "" {
+		fmt.Fprintf(w, "  suggestion: %s\n", d.Suggestion)
+	}
+}
+
+// command runs a ":" command.
+func (s *snippetSession) command(line string) {
+	fields := strings.Fields(line)
+	name, args := strings.ToLower(fields[0]), fields[1:]
+	out := s.r.out
+
+	switch name {
+	case ":help", ":h", ":?":
+		s.printHelp()
+	case ":quit", ":q", ":exit":
+		fmt.Fprintln(out, "Goodbye.")
+		s.r.running = false
+	case ":ir", ":json":
+		if s.requireApp() {
+			data, err := ir.ToJSON(s.app)
+			if err != nil {
+				fmt.Fprintln(s.r.errOut, cli.Error(err.Error()))
+				return
+			}
+			fmt.Fprintln(out, string(data))
+		}
+	case ":yaml":
+		if s.requireApp() {
+			yaml, err := ir.ToYAML(s.app)
+			if err != nil {
+				fmt.Fprintln(s.r.errOut, cli.Error(err.Error()))
+				return
+			}
+			fmt.Fprintln(out, strings.TrimRight(yaml, "\n"))
+		}
+	case ":gen":
+		s.gen(args)
+	case ":source", ":show":
+		if len(s.lines) == 0 {
+			fmt.Fprintln(out, cli.Muted("  Nothing typed yet."))
+			return
+		}
+		for i, l := range s.lines {
+			fmt.Fprintf(out, "%s  %s\n", cli.Muted(fmt.Sprintf("%3d", i+1)), l)
+		}
+	case ":undo":
+		if len(s.lines) == 0 {
+			fmt.Fprintln(out, cli.Muted("  Nothing to undo."))
+			return
+		}
+		fmt.Fprintln(out, cli.Muted("  Removed: "+strings.TrimSpace(s.lines[len(s.lines)-1])))
+		s.reload(s.lines[:len(s.lines)-1])
+	case ":reset", ":clear":
+		s.reported, s.counts = map[string]bool{}, ""
+		s.reload(nil)
+		fmt.Fprintln(out, cli.Muted("  Cleared."))
+	case ":load":
+		if len(args) != 1 {
+			fmt.Fprintln(s.r.errOut, cli.Error("Usage: :load <file.human>"))
+			return
+		}
+		s.load(args[0])
+	case ":save":
+		if len(args) != 1 {
+			fmt.Fprintln(s.r.errOut, cli.Error("Usage: :save <file.human>"))
+			return
+		}
+		if err := os.WriteFile(args[0], []byte(s.source()), 0644); err != nil {
+			fmt.Fprintln(s.r.errOut, cli.Error(err.Error()))
+			return
+		}
+		fmt.Fprintln(out, cli.Success(fmt.Sprintf("Saved %d line(s) to %s", len(s.lines), args[0])))
+	default:
+		fmt.Fprintln(s.r.errOut, cli.Error(fmt.Sprintf("Unknown command %s — :help lists them.", fields[0])))
+	}
+}
+
+func (s *snippetSession) printHelp() {
+	out := s.r.out
+	fmt.Fprintln(out, cli.Heading("Commands"))
+	for _, c := range [][2]string{
+		{":ir", "Show the IR built from the lines so far, as JSON"},
+		{":yaml", "Show the IR as YAML"},
+		{":gen <generator> [kind] [name]", "Preview a generator's output, e.g. :gen react page Home"},
+		{":source", "Show the lines typed so far"},
+		{":undo", "Remove the last line"},
+		{":reset", "Start over"},
+		{":load <file>", "Replace the lines with a .human file"},
+		{":save <file>", "Write the lines to a .human file"},
+		{":quit", "Exit"},
+	} {
+		fmt.Fprintf(out, "  %-32s %s\n", cli.Accent(c[0]), c[1])
+	}
+}
+
+func (s *snippetSession) requireApp() bool {
+	if s.app == nil {
+		fmt.Fprintln(s.r.out, cli.Muted("  Nothing built yet — type some .human first."))
+		return false
+	}
+	return true
+}
+
+// reload replaces the lines and rebuilds the IR from them.
+func (s *snippetSession) reload(lines []string) {
+	s.lines, s.app = lines, nil
+	if len(lines) == 0 {
+		return
+	}
+	prog, err := parser.Parse(s.source())
+	if err != nil {
+		fmt.Fprintln(s.r.errOut, cli.Error(err.Error()))
+		return
+	}
+	s.check(prog)
+}
+
+// load replaces the lines with a file's, if the file parses.
+func (s *snippetSession) load(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(s.r.errOut, cli.Error(err.Error()))
+		return
+	}
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+	if _, err := parser.Parse(string(data)); err != nil {
+		fmt.Fprintln(s.r.errOut, cli.Error(fmt.Sprintf("%s: %v", path, err)))
+		return
+	}
+	fmt.Fprintln(s.r.out, cli.Muted(fmt.Sprintf("  Loaded %d line(s) from %s", len(lines), path)))
+	s.reload(lines)
+}
+
+// gen runs a generator on the IR in a scratch directory and prints the
+// files it wrote for name: those named after it, or failing that those
+// mentioning it. A kind such as "page" or "model" narrows them to files
+// whose path contains it. Without a name, or with no file for it, it lists
+// everything the generator wrote.
+func (s *snippetSession) gen(args []string) {
+	out, errOut := s.r.out, s.r.errOut
+	reg := build.DefaultRegistry()
+	if len(args) == 0 {
+		fmt.Fprintln(errOut, cli.Error("Usage: :gen <generator> [kind] [name]"))
+		fmt.Fprintln(out, cli.Muted("  Generators: "+strings.Join(reg.Names(), ", ")))
+		return
+	}
+	g := reg.Get(strings.ToLower(args[0]))
+	if g == nil {
+		fmt.Fprintln(errOut, cli.Error(fmt.Sprintf("Unknown generator %q", args[0])))
+		fmt.Fprintln(out, cli.Muted("  Generators: "+strings.Join(reg.Names(), ", ")))
+		return
+	}
+	if !s.requireApp() {
+		return
+	}
+	var kind, name string
+	switch len(args) {
+	case 1:
+	case 2:
+		name = args[1]
+	default:
+		kind, name = strings.ToLower(args[1]), args[2]
+	}
+
+	dir, err := os.MkdirTemp("", "human-repl-")
+	if err != nil {
+		fmt.Fprintln(errOut, cli.Error(err.Error()))
+		return
+	}
+	defer os.RemoveAll(dir)
+	if err := generateSafely(g.Generate, s.app, dir); err != nil {
+		fmt.Fprintln(errOut, cli.Error(fmt.Sprintf("%s: %v", g.Meta().Name, err)))
+		return
+	}
+
+	files := generatedFiles(dir)
+	if len(files) == 0 {
+		fmt.Fprintln(out, cli.Muted(fmt.Sprintf("  %s wrote nothing for these lines.", g.Meta().Name)))
+		return
+	}
+	matches := matchGenerated(dir, files, kind, name)
+	if len(matches) == 0 {
+		if name != "" {
+			fmt.Fprintln(out, cli.Muted(fmt.Sprintf("  No %s file for %s. It wrote:", g.Meta().Name, name)))
+		}
+		for _, f := range files {
+			fmt.Fprintln(out, "  "+f)
+		}
+		return
+	}
+	for _, f := range matches {
+		data, err := os.ReadFile(filepath.Join(dir, f))
+		if err != nil {
+			continue
+		}
+		fmt.Fprintln(out, cli.Heading("── "+f))
+		fmt.Fprintln(out, strings.TrimRight(string(data), "\n"))
+		fmt.Fprintln(out)
+	}
+}
+
+// generateSafely runs generate, turning a panic into an error: snippets are
+// often missing parts a generator takes for granted in a whole app.
+func generateSafely(generate func(*ir.Application, string) error, app *ir.Application, dir string) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("generator failed on this snippet: %v", p)
+		}
+	}()
+	return generate(app, dir)
+}
+
+// generatedFiles returns the files under dir, relative to it and sorted.
+func generatedFiles(dir string) []string {
+	var files []string
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if rel, err := filepath.Rel(dir, path); err == nil {
+				files = append(files, filepath.ToSlash(rel))
+			}
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files
+}
+
+// matchGenerated picks the files shown for kind and name; see gen.
+func matchGenerated(dir string, files []string, kind, name string) []string {
+	if name == "" {
+		return nil
+	}
+	lower := strings.ToLower(name)
+	var matches []string
+	for _, f := range files {
+		if strings.Contains(strings.ToLower(filepath.Base(f)), lower) {
+			matches = append(matches, f)
+		}
+	}
+	if len(matches) == 0 {
+		for _, f := range files {
+			data, err := os.ReadFile(filepath.Join(dir, f))
+			if err == nil && strings.Contains(string(data), name) {
+				matches = append(matches, f)
+			}
+		}
+	}
+	if kind == "" {
+		return matches
+	}
+	var ofKind []string
+	for _, f := range matches {
+		if strings.Contains(strings.ToLower(f), kind) {
+			ofKind = append(ofKind, f)
+		}
+	}
+	if len(ofKind) == 0 {
+		return matches
+	}
+	return ofKind
+}
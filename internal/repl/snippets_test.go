@@ -0,0 +1,149 @@
+package repl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runSnippets(t *testing.T, input string) (string, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	r, out, errOut := newTestREPL(input)
+	r.RunSnippets()
+	return out.String(), errOut.String()
+}
+
+func TestSnippets_LinesBuildTheIR(t *testing.T) {
+	out, _ := runSnippets(t, strings.Join([]string{
+		"data User:",
+		"  has a name which is text",
+		"  has an email which is unique email",
+		":ir",
+		":quit",
+	}, "\n")+"\n")
+
+	if !strings.Contains(out, "1 data model") {
+		t.Errorf("expected a summary of what the lines declare, got:\n%s", out)
+	}
+	for _, want := range []string{`"name": "User"`, `"name": "email"`, `"unique": true`} {
+		if !strings.Contains(out, want) {
+			t.Errorf(":ir output missing %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestSnippets_YAML(t *testing.T) {
+	out, _ := runSnippets(t, "data Task:\n  has a title which is text\n:yaml\n")
+	if !strings.Contains(out, "- name: Task") {
+		t.Errorf("expected :yaml to show the Task model, got:\n%s", out)
+	}
+}
+
+func TestSnippets_NothingBuiltYet(t *testing.T) {
+	out, _ := runSnippets(t, ":ir\n:gen react\n")
+	if strings.Count(out, "Nothing built yet") != 2 {
+		t.Errorf("expected :ir and :gen to say nothing is built, got:\n%s", out)
+	}
+}
+
+func TestSnippets_WarningsShownOnce(t *testing.T) {
+	_, errOut := runSnippets(t, "data User:\n  has a name which is text\n")
+	if n := strings.Count(errOut, "W201"); n != 1 {
+		t.Errorf("expected the missing build targets warning once, got %d:\n%s", n, errOut)
+	}
+}
+
+func TestSnippets_UndoAndSource(t *testing.T) {
+	out, _ := runSnippets(t, strings.Join([]string{
+		"data User:",
+		"  has a name which is text",
+		"page Home:",
+		":undo",
+		":source",
+	}, "\n")+"\n")
+
+	if !strings.Contains(out, "Removed: page Home:") {
+		t.Errorf("expected :undo to name the removed line, got:\n%s", out)
+	}
+	if !strings.Contains(out, "2    has a name which is text") {
+		t.Errorf("expected :source to number the lines, got:\n%s", out)
+	}
+	if strings.Contains(out, "3  page Home:") {
+		t.Errorf("expected the undone line to be gone from :source, got:\n%s", out)
+	}
+}
+
+func TestSnippets_GenPreviewsMatchingFile(t *testing.T) {
+	out, _ := runSnippets(t, strings.Join([]string{
+		"data User:",
+		"  has a name which is text",
+		"page Home:",
+		"  show a list of users",
+		":gen react page Home",
+		":gen react model User",
+	}, "\n")+"\n")
+
+	if !strings.Contains(out, "── src/pages/HomePage.tsx") || !strings.Contains(out, "export default function HomePage") {
+		t.Errorf("expected :gen react page Home to print HomePage.tsx, got:\n%s", out)
+	}
+	if !strings.Contains(out, "── src/types/models.ts") || !strings.Contains(out, "export interface User") {
+		t.Errorf("expected :gen react model User to print models.ts, got:\n%s", out)
+	}
+	if strings.Contains(out, "── src/App.tsx") {
+		t.Errorf("expected only the files for the page and model, got:\n%s", out)
+	}
+}
+
+func TestSnippets_GenListsFilesWithoutName(t *testing.T) {
+	out, _ := runSnippets(t, "data User:\n  has a name which is text\n:gen postgres\n")
+	if !strings.Contains(out, "migrations/001_initial.sql") {
+		t.Errorf("expected :gen postgres to list its files, got:\n%s", out)
+	}
+}
+
+func TestSnippets_GenUnknownGenerator(t *testing.T) {
+	out, errOut := runSnippets(t, ":gen cobol\n")
+	if !strings.Contains(errOut, `Unknown generator "cobol"`) {
+		t.Errorf("expected an unknown generator error, got:\n%s", errOut)
+	}
+	if !strings.Contains(out, "react") {
+		t.Errorf("expected the generators to be listed, got:\n%s", out)
+	}
+}
+
+func TestSnippets_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.human")
+	runSnippets(t, "data User:\n  has a name which is text\n:save "+path+"\n")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "data User:\n  has a name which is text\n" {
+		t.Errorf("saved %q", data)
+	}
+
+	out, _ := runSnippets(t, ":load "+path+"\n:source\n")
+	if !strings.Contains(out, "Loaded 2 line(s)") || !strings.Contains(out, "1  data User:") {
+		t.Errorf("expected :load to replace the lines with the file's, got:\n%s", out)
+	}
+}
+
+func TestSnippets_UnknownCommand(t *testing.T) {
+	_, errOut := runSnippets(t, ":frobnicate\n")
+	if !strings.Contains(errOut, "Unknown command :frobnicate") {
+		t.Errorf("expected an unknown command error, got:\n%s", errOut)
+	}
+}
+
+func TestSnippets_LineThatDoesNotParseIsDropped(t *testing.T) {
+	out, errOut := runSnippets(t, "data User:\n\"unterminated\n:source\n")
+	if !strings.Contains(errOut, "unterminated string") || !strings.Contains(out, "Line dropped.") {
+		t.Errorf("expected the parse error and the line dropped, got:\n%s\n%s", out, errOut)
+	}
+	if strings.Contains(out, `2  "unterminated`) {
+		t.Errorf("expected the dropped line to be left out of :source, got:\n%s", out)
+	}
+}
@@ -1,8 +1,10 @@
 package repl
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/barun-bash/human/internal/cli"
 	"github.com/barun-bash/human/internal/cmdutil"
 )
 
@@ -22,3 +24,9 @@ func cmdSyntax(r *REPL, args []string) {
 
 	cmdutil.RunSyntax(r.out, section, search)
 }
+
+func cmdSnippet(r *REPL, args []string) {
+	if err := cmdutil.RunSnippet(r.out, strings.Join(args, " ")); err != nil {
+		fmt.Fprintln(r.errOut, cli.Error(err.Error()))
+	}
+}
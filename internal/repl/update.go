@@ -18,8 +18,8 @@ import (
 
 // UpdateInfo holds the result of a background version check.
 type UpdateInfo struct {
-	Available     bool
-	LatestVersion string
+	Available      bool
+	LatestVersion  string
 	CurrentVersion string
 }
 
@@ -32,7 +32,11 @@ const updateCheckInterval = 24 * time.Hour
 
 // checkUpdateBackground starts a goroutine that checks for a newer version.
 // Results are stored in r.updateInfo; r.updateDone is closed when complete.
+// Offline sessions skip the check.
 func (r *REPL) checkUpdateBackground() {
+	if config.Offline(".") {
+		return
+	}
 	r.updateDone = make(chan struct{})
 
 	go func() {
@@ -287,6 +291,9 @@ func updateViaGoInstall(r *REPL) {
 func showManualUpdateInstructions(r *REPL, latest string) {
 	fmt.Fprintln(r.out, cli.Info("To update, choose one of:"))
 	fmt.Fprintln(r.out)
+	fmt.Fprintln(r.out, "  Release binary (checksum-verified):")
+	fmt.Fprintln(r.out, "    human self-update")
+	fmt.Fprintln(r.out)
 	fmt.Fprintln(r.out, "  From source:")
 	fmt.Fprintln(r.out, "    git clone https://github.com/barun-bash/human && cd human")
 	fmt.Fprintln(r.out, "    make build && make install")
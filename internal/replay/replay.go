@@ -0,0 +1,367 @@
+// Package replay sends API requests recorded as .http files again and
+// compares each response with the recorded one. The files are in the VS
+// Code REST Client format the generated backends record requests in with
+// `human run --record`:
+//
+//	@host = http://localhost:3001
+//
+//	###
+//	# Recorded: 2024-01-01T12:00:00.000Z
+//	# Response: 200 (12 ms)
+//	# {"data":{"id":"c1","title":"Write docs"}}
+//	POST {{host}}/api/task
+//	content-type: application/json
+//
+//	{"title": "Write docs"}
+//
+// Files written by hand or by the REST Client work too; requests without
+// a "# Response:" comment are sent without being compared.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Request is a recorded API request, and the response it got.
+type Request struct {
+	File     string
+	Method   string
+	URL      string // may hold {{variables}}
+	Header   [][2]string
+	Body     string
+	Status   int    // recorded response status, 0 when not recorded
+	Response string // recorded response body
+}
+
+var (
+	variableLine = regexp.MustCompile(`^@([A-Za-z0-9_.-]+)\s*=\s*(.*)$`)
+	responseLine = regexp.MustCompile(`^#\s*Response:\s*(\d{3})`)
+	requestLine  = regexp.MustCompile(`^(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\s+(\S+)(\s+HTTP/[\d.]+)?$`)
+	placeholder  = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+)
+
+// Parse reads the requests in an .http file, with the file's variables
+// substituted into their URLs, headers, and bodies.
+func Parse(name, content string) ([]*Request, error) {
+	vars := map[string]string{}
+	var requests []*Request
+	var cur *Request
+	var body []string
+	inResponse, inBody := false, false
+
+	finish := func() {
+		if cur == nil {
+			return
+		}
+		cur.Body = strings.TrimRight(strings.Join(body, "\n"), "\n")
+		requests = append(requests, cur)
+		cur, body, inBody = nil, nil, false
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var status int
+	var response []string
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case strings.HasPrefix(text, "###"):
+			finish()
+			status, response, inResponse = 0, nil, false
+		case inBody:
+			body = append(body, text)
+		case cur != nil:
+			if strings.TrimSpace(text) == "" {
+				inBody = true
+				continue
+			}
+			if strings.HasPrefix(text, "#") || strings.HasPrefix(text, "//") {
+				continue
+			}
+			key, value, ok := strings.Cut(text, ":")
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: expected a header, got %q", name, line, text)
+			}
+			cur.Header = append(cur.Header, [2]string{strings.TrimSpace(key), strings.TrimSpace(value)})
+		case variableLine.MatchString(text):
+			m := variableLine.FindStringSubmatch(text)
+			vars[m[1]] = strings.TrimSpace(m[2])
+		case responseLine.MatchString(text):
+			status, _ = strconv.Atoi(responseLine.FindStringSubmatch(text)[1])
+			inResponse = true
+		case strings.HasPrefix(text, "#") || strings.HasPrefix(text, "//"):
+			if inResponse {
+				response = append(response, strings.TrimPrefix(strings.TrimPrefix(text, "#"), " "))
+			}
+		case requestLine.MatchString(text):
+			m := requestLine.FindStringSubmatch(text)
+			cur = &Request{File: name, Method: m[1], URL: m[2], Status: status, Response: strings.Join(response, "\n")}
+		case strings.TrimSpace(text) == "":
+		default:
+			return nil, fmt.Errorf("%s:%d: expected a request line like \"GET {{host}}/api/tasks\", got %q", name, line, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	finish()
+
+	for _, r := range requests {
+		r.URL = substitute(r.URL, vars)
+		r.Body = substitute(r.Body, vars)
+		for i := range r.Header {
+			r.Header[i][1] = substitute(r.Header[i][1], vars)
+		}
+	}
+	return requests, nil
+}
+
+func substitute(s string, vars map[string]string) string {
+	return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		if v, ok := vars[placeholder.FindStringSubmatch(m)[1]]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// Load reads the requests in an .http file, or in every .http file in a
+// directory, in name order, which is the order they were recorded in.
+func Load(path string) ([]*Request, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.http"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	var requests []*Request
+	for _, f := range files {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		rs, err := Parse(f, string(content))
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, rs...)
+	}
+	return requests, nil
+}
+
+// Result is the response a replayed request got.
+type Result struct {
+	Request *Request
+	Status  int
+	Body    string
+	Diffs   []string // how the body differs from the recorded one
+	Err     error
+}
+
+// Matches reports whether the request got the response it was recorded
+// with, or was sent without error when none was recorded.
+func (r *Result) Matches() bool {
+	if r.Err != nil {
+		return false
+	}
+	if r.Request.Status == 0 {
+		return true
+	}
+	return r.Status == r.Request.Status && len(r.Diffs) == 0
+}
+
+// Send sends a request again and compares the response with the recorded
+// one. With a host, the request goes there instead of the host it was
+// recorded against.
+func Send(client *http.Client, req *Request, host string) *Result {
+	res := &Result{Request: req}
+	url := req.URL
+	if host != "" {
+		url = strings.TrimRight(host, "/") + pathOf(url)
+	}
+	if placeholder.MatchString(url) {
+		res.Err = fmt.Errorf("%s has an undefined variable; pass --host", url)
+		return res
+	}
+
+	httpReq, err := http.NewRequest(req.Method, url, strings.NewReader(req.Body))
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	for _, h := range req.Header {
+		httpReq.Header.Add(h[0], h[1])
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Status = resp.StatusCode
+	res.Body = string(body)
+	if req.Status != 0 {
+		res.Diffs = Compare(req.Response, res.Body)
+	}
+	return res
+}
+
+// pathOf returns the path and query of a URL, with or without its origin.
+func pathOf(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		rest := url[i+3:]
+		if j := strings.Index(rest, "/"); j >= 0 {
+			return rest[j:]
+		}
+		return "/"
+	}
+	if strings.HasPrefix(url, "{{") {
+		if _, rest, ok := strings.Cut(url, "}}"); ok {
+			return rest
+		}
+	}
+	return url
+}
+
+// maxDiffs caps the differences reported for one response.
+const maxDiffs = 10
+
+// Compare lists how a response body differs from the recorded one. JSON
+// bodies are compared value by value, leaving out ids, foreign keys,
+// timestamps, tokens, and cursors, which differ every time a request is
+// made; other bodies are compared as text.
+func Compare(recorded, got string) []string {
+	var want, have any
+	if json.Unmarshal([]byte(recorded), &want) != nil || json.Unmarshal([]byte(got), &have) != nil {
+		if strings.TrimSpace(recorded) == strings.TrimSpace(got) {
+			return nil
+		}
+		return []string{fmt.Sprintf("body: %s → %s", abbreviate(recorded), abbreviate(got))}
+	}
+	var diffs []string
+	compareValues("", want, have, &diffs)
+	return diffs
+}
+
+func compareValues(path string, want, have any, diffs *[]string) {
+	if len(*diffs) >= maxDiffs {
+		return
+	}
+	switch w := want.(type) {
+	case map[string]any:
+		h, ok := have.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(w)+len(h))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		for k := range h {
+			if _, ok := w[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if volatile(k) {
+				continue
+			}
+			wv, inWant := w[k]
+			hv, inHave := h[k]
+			switch {
+			case !inWant:
+				addDiff(diffs, "%s: added %s", join(path, k), encode(hv))
+			case !inHave:
+				addDiff(diffs, "%s: removed (was %s)", join(path, k), encode(wv))
+			default:
+				compareValues(join(path, k), wv, hv, diffs)
+			}
+		}
+		return
+	case []any:
+		h, ok := have.([]any)
+		if !ok {
+			break
+		}
+		if len(w) != len(h) {
+			addDiff(diffs, "%s: %d items → %d", orRoot(path), len(w), len(h))
+			return
+		}
+		for i := range w {
+			compareValues(fmt.Sprintf("%s[%d]", path, i), w[i], h[i], diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(want, have) {
+		addDiff(diffs, "%s: %s → %s", orRoot(path), encode(want), encode(have))
+	}
+}
+
+// volatile reports whether a field's value differs every time a request is
+// made, so it is left out of comparisons.
+func volatile(key string) bool {
+	switch key {
+	case "id", "_id", "token", "nextCursor", "next_cursor", "createdAt", "updatedAt", "created_at", "updated_at":
+		return true
+	}
+	return strings.HasSuffix(key, "Id") || strings.HasSuffix(key, "_id")
+}
+
+func addDiff(diffs *[]string, format string, args ...any) {
+	if len(*diffs) < maxDiffs {
+		*diffs = append(*diffs, fmt.Sprintf(format, args...))
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func orRoot(path string) string {
+	if path == "" {
+		return "body"
+	}
+	return path
+}
+
+func encode(v any) string {
+	out, _ := json.Marshal(v)
+	return abbreviate(string(out))
+}
+
+func abbreviate(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 80 {
+		return s[:77] + "..."
+	}
+	return s
+}
@@ -0,0 +1,160 @@
+package replay
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const recorded = `@host = http://localhost:3001
+
+###
+# Recorded: 2024-01-01T12:00:00.000Z
+# Response: 201 (12 ms)
+# {"data":{"id":"c1","title":"Write docs","createdAt":"2024-01-01T12:00:00.000Z"}}
+POST {{host}}/api/task
+content-type: application/json
+authorization: Bearer {{token}}
+
+{"title": "Write docs"}
+
+###
+GET {{host}}/api/tasks?page=2
+`
+
+func TestParse(t *testing.T) {
+	requests, err := Parse("task.http", recorded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+
+	post := requests[0]
+	if post.Method != "POST" || post.URL != "http://localhost:3001/api/task" {
+		t.Errorf("request line = %s %s", post.Method, post.URL)
+	}
+	if post.Status != 201 || !strings.HasPrefix(post.Response, `{"data":{"id":"c1"`) {
+		t.Errorf("recorded response = %d %s", post.Status, post.Response)
+	}
+	if len(post.Header) != 2 || post.Header[0] != [2]string{"content-type", "application/json"} {
+		t.Errorf("headers = %v", post.Header)
+	}
+	if post.Header[1][1] != "Bearer {{token}}" {
+		t.Errorf("undefined variables should be left as they are, got %q", post.Header[1][1])
+	}
+	if post.Body != `{"title": "Write docs"}` {
+		t.Errorf("body = %q", post.Body)
+	}
+
+	get := requests[1]
+	if get.Status != 0 || get.Response != "" || get.Body != "" {
+		t.Errorf("the second request should not inherit the first one's response: %+v", get)
+	}
+	if get.URL != "http://localhost:3001/api/tasks?page=2" {
+		t.Errorf("url = %s", get.URL)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse("bad.http", "###\nfetch the tasks\n")
+	if err == nil || !strings.Contains(err.Error(), "bad.http:2:") {
+		t.Errorf("expected an error pointing at bad.http:2, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"2-second.http": "GET http://localhost/api/b\n",
+		"1-first.http":  "GET http://localhost/api/a\n",
+		"notes.txt":     "not a request",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	requests, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 || requests[0].URL != "http://localhost/api/a" {
+		t.Errorf("expected both .http files in name order, got %d", len(requests))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	if diffs := Compare(
+		`{"data":{"id":"a","userId":"u1","title":"Docs","createdAt":"yesterday"}}`,
+		`{"data":{"id":"b","userId":"u2","title":"Docs","createdAt":"today"}}`,
+	); len(diffs) != 0 {
+		t.Errorf("ids, foreign keys, and timestamps should be ignored, got %v", diffs)
+	}
+
+	diffs := Compare(
+		`{"data":[{"title":"Docs","done":false}],"total":1}`,
+		`{"data":[{"title":"Tests","done":false}],"total":1,"page":1}`,
+	)
+	want := []string{
+		`data[0].title: "Docs" → "Tests"`,
+		`page: added 1`,
+	}
+	if strings.Join(diffs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diffs = %q, want %q", diffs, want)
+	}
+
+	if diffs := Compare(`{"data":[1,2]}`, `{"data":[1]}`); len(diffs) != 1 || diffs[0] != "data: 2 items → 1" {
+		t.Errorf("diffs = %q", diffs)
+	}
+	if diffs := Compare("OK", "OK\n"); len(diffs) != 0 {
+		t.Errorf("text bodies should be compared without surrounding space, got %q", diffs)
+	}
+}
+
+func TestSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("content-type") != "application/json" || string(body) != `{"title": "Write docs"}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"data":{"id":"c2","title":"Write docs","createdAt":"2024-01-02T09:00:00.000Z"}}`)
+	}))
+	defer server.Close()
+
+	requests, err := Parse("task.http", recorded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := Send(server.Client(), requests[0], server.URL)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if !res.Matches() {
+		t.Errorf("expected the response to match, got %d %v", res.Status, res.Diffs)
+	}
+
+	requests[0].Response = `{"data":{"title":"Write the docs"}}`
+	if res := Send(server.Client(), requests[0], server.URL); res.Matches() {
+		t.Error("a different title should not match")
+	}
+}
+
+func TestPathOf(t *testing.T) {
+	for in, want := range map[string]string{
+		"http://localhost:3001/api/tasks?page=2": "/api/tasks?page=2",
+		"http://localhost:3001":                  "/",
+		"{{host}}/api/tasks":                     "/api/tasks",
+		"/api/tasks":                             "/api/tasks",
+	} {
+		if got := pathOf(in); got != want {
+			t.Errorf("pathOf(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
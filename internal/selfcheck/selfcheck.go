@@ -0,0 +1,300 @@
+// Package selfcheck compiles the Human code the compiler ships with — the
+// syntax patterns' examples, the snippets, and the example apps — through
+// its own parser and analyzer. The syntax reference, LLM prompts, MCP
+// resources, and docs are built from this code, so a check that fails here
+// means one of them shows code the compiler rejects.
+package selfcheck
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/barun-bash/human/internal/analyzer"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/parser"
+	"github.com/barun-bash/human/internal/syntax"
+)
+
+// Problem is a diagnostic from compiling shipped code.
+type Problem struct {
+	Source  string // what was compiled, e.g. `pattern "has many <Model>"`
+	Message string
+}
+
+func (p Problem) String() string {
+	return p.Source + ": " + p.Message
+}
+
+// Check compiles source, a complete .human file, returning its syntax
+// errors, or the analyzer's errors. Warnings aren't problems.
+func Check(name, source string) []Problem {
+	return checkFiles(name, []string{source})
+}
+
+// checkFiles compiles the files of a project, merged, as the build does.
+func checkFiles(name string, sources []string) []Problem {
+	var problems []Problem
+	var programs []*parser.Program
+	for _, source := range sources {
+		prog, err := parser.Parse(source)
+		if syntaxErrs, ok := err.(*parser.Errors); ok {
+			for _, d := range syntaxErrs.Diagnostics {
+				problems = append(problems, Problem{name, d.Format()})
+			}
+			continue
+		}
+		if err != nil {
+			problems = append(problems, Problem{name, err.Error()})
+			continue
+		}
+		programs = append(programs, prog)
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	prog, err := parser.MergePrograms(programs)
+	if err != nil {
+		return []Problem{{name, err.Error()}}
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		return []Problem{{name, err.Error()}}
+	}
+	for _, e := range analyzer.Analyze(app, "").Errors() {
+		problems = append(problems, Problem{name, e.Format()})
+	}
+	return problems
+}
+
+// CheckExamples checks the example apps in fsys, returning their problems
+// and how many there are. The .human files in a directory are one app,
+// named by the directory, with app.human first; a file at the root is one
+// app, named by the file.
+func CheckExamples(fsys fs.FS) ([]Problem, int, error) {
+	var names []string
+	files := map[string][]string{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".human" {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		name := path.Dir(p)
+		if name == "." {
+			name = strings.TrimSuffix(p, ".human")
+		}
+		if _, ok := files[name]; !ok {
+			names = append(names, name)
+		}
+		if path.Base(p) == "app.human" {
+			files[name] = append([]string{string(data)}, files[name]...)
+		} else {
+			files[name] = append(files[name], string(data))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var problems []Problem
+	for _, name := range names {
+		problems = append(problems, checkFiles("example "+name, files[name])...)
+	}
+	return problems, len(names), nil
+}
+
+// CheckPatterns checks every pattern's example, written in the block its
+// category belongs in, in an app declaring what the examples refer to,
+// returning their problems and how many were checked. A pattern without
+// an example is checked by its template, unless it has placeholders.
+func CheckPatterns() ([]Problem, int) {
+	var problems []Problem
+	count := 0
+	for _, p := range syntax.AllPatterns() {
+		name := fmt.Sprintf("pattern %q", p.Template)
+		example := p.Example
+		if example == "" {
+			example = p.Template
+		}
+		if strings.Contains(example, "<") {
+			continue
+		}
+		count++
+		problems = append(problems, Check(name, inContext(p.Category, example))...)
+	}
+	return problems, count
+}
+
+// CheckSnippets checks every multi-line snippet, its placeholders filled
+// in, returning their problems and how many were checked. Single-line
+// snippets are pattern templates, checked by way of their examples.
+func CheckSnippets() ([]Problem, int) {
+	var problems []Problem
+	count := 0
+	for _, s := range syntax.Snippets() {
+		if !strings.Contains(s.Code, "\n") {
+			continue
+		}
+		count++
+		problems = append(problems, Check("snippet "+s.ID, inContext("", fillPlaceholders(s.Code)))...)
+	}
+	return problems, count
+}
+
+var placeholder = regexp.MustCompile(`<[^>]+>`)
+
+// fieldNames fill a snippet's <field>s, in turn. Post has the first.
+var fieldNames = []string{"title", "summary", "rank", "note", "label"}
+
+// fillPlaceholders replaces a snippet's <placeholders> with names the
+// context app declares.
+func fillPlaceholders(code string) string {
+	fields := 0
+	return placeholder.ReplaceAllStringFunc(code, func(p string) string {
+		switch strings.Trim(p, "<>") {
+		case "HTTP_METHOD":
+			return "GET"
+		case "number", "limit", "port":
+			return "10"
+		case "field":
+			fields++
+			return fieldNames[(fields-1)%len(fieldNames)]
+		case "data", "item":
+			return "posts"
+		case "Data", "Service":
+			return "Post"
+		case "Page":
+			return "Home"
+		}
+		return "Example"
+	})
+}
+
+// blocks are the blocks a category's statements are written in, and
+// bodies a statement each category's block headers are followed by.
+var (
+	blocks = map[syntax.Category]string{
+		syntax.CatData:         "data Post:",
+		syntax.CatPages:        "page Example:",
+		syntax.CatComponents:   "component Example:",
+		syntax.CatEvents:       "page Example:",
+		syntax.CatStyling:      "page Example:",
+		syntax.CatForms:        "page Example:",
+		syntax.CatAPIs:         "api Example:",
+		syntax.CatSecurity:     "authentication:",
+		syntax.CatPolicies:     "policy Example:",
+		syntax.CatDatabase:     "database:",
+		syntax.CatWorkflows:    "when a user signs up:",
+		syntax.CatIntegrations: "integrate with Example:",
+		syntax.CatArchitecture: "architecture: microservices\n  service Example:",
+		syntax.CatDevOps:       "when code is pushed to main:",
+		syntax.CatTheme:        "theme:",
+		syntax.CatBuild:        "build with:",
+		syntax.CatConditional:  "page Example:",
+		syntax.CatErrors:       "if database is unreachable:",
+	}
+	bodies = map[syntax.Category]string{
+		syntax.CatData:         "has a title which is text",
+		syntax.CatPages:        "show a list of posts",
+		syntax.CatComponents:   "accepts post as Post",
+		syntax.CatEvents:       "send notification to the user",
+		syntax.CatAPIs:         "respond with the posts",
+		syntax.CatSecurity:     "passwords are hashed with bcrypt",
+		syntax.CatPolicies:     "can view all posts",
+		syntax.CatDatabase:     "use PostgreSQL",
+		syntax.CatWorkflows:    "send notification to the user",
+		syntax.CatIntegrations: "use for notifications",
+		syntax.CatArchitecture: "handles posts",
+		syntax.CatDevOps:       "run all tests",
+		syntax.CatTheme:        "primary color is #6C5CE7",
+		syntax.CatBuild:        "frontend using React",
+		syntax.CatErrors:       "retry 3 times with 1 second delay",
+	}
+)
+
+// contextBlocks declare the app, data, pages, and authentication the
+// examples refer to.
+var contextBlocks = []string{
+	"app Example is a web application",
+	`data User:
+  has a name which is text
+  has an email which is unique email
+  has many Post`,
+	`data Post:
+  belongs to a User
+  has a title which is text`,
+	`data Tag:
+  has a name which is text`,
+	`data PostTag:
+  belongs to a Post
+  belongs to a Tag`,
+	`data Task:
+  belongs to a User
+  has a title which is text
+  has a status which is text`,
+	`data Order:
+  belongs to a User
+  has a total which is decimal`,
+	`page Home:
+  show a list of posts`,
+	`page Classic:
+  show "Classic pricing"`,
+	`page NewPricing:
+  show "New pricing"`,
+	`authentication:
+  method JWT tokens that expire in 7 days`,
+}
+
+// inContext returns code, a pattern's example, among contextBlocks,
+// leaving out the one it declares itself. A statement is indented into
+// its category's block, added to the context's block of that name if
+// there is one; a block header gets a body.
+func inContext(cat syntax.Category, code string) string {
+	first, _, _ := strings.Cut(code, "\n")
+	var b strings.Builder
+	for _, block := range contextBlocks {
+		header, _, _ := strings.Cut(block, "\n")
+		switch {
+		case header == first:
+			continue
+		case header == blocks[cat] && !strings.HasSuffix(code, ":") && !isTopLevel(code):
+			block += "\n  " + code
+			code = ""
+		}
+		b.WriteString(block + "\n\n")
+	}
+	switch {
+	case code == "":
+	case strings.Contains(code, "\n"):
+		b.WriteString(code)
+	case strings.HasSuffix(code, ":"):
+		b.WriteString(code + "\n  " + bodies[cat])
+	case blocks[cat] != "" && !isTopLevel(code):
+		header := blocks[cat]
+		last := header[strings.LastIndex(header, "\n")+1:]
+		indent := len(last) - len(strings.TrimLeft(last, " ")) + 2
+		b.WriteString(header + "\n" + strings.Repeat(" ", indent) + code)
+	default:
+		b.WriteString(code)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// isTopLevel reports whether code is a statement written outside any
+// block.
+func isTopLevel(code string) bool {
+	for _, prefix := range []string{"app ", "── ", "before ", "after a ", "seed ", "experiment "} {
+		if strings.HasPrefix(code, prefix) {
+			return true
+		}
+	}
+	return false
+}
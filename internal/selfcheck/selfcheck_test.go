@@ -0,0 +1,86 @@
+package selfcheck
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/barun-bash/human/internal/syntax"
+)
+
+func TestPatternExamplesCompile(t *testing.T) {
+	problems, count := CheckPatterns()
+	for _, p := range problems {
+		t.Error(p)
+	}
+	if count < len(syntax.AllPatterns())/2 {
+		t.Errorf("checked %d pattern examples, want most of %d", count, len(syntax.AllPatterns()))
+	}
+}
+
+func TestSnippetsCompile(t *testing.T) {
+	problems, count := CheckSnippets()
+	for _, p := range problems {
+		t.Error(p)
+	}
+	if count == 0 {
+		t.Error("checked no snippets")
+	}
+}
+
+func TestExamplesCompile(t *testing.T) {
+	problems, count, err := CheckExamples(os.DirFS("../../examples"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Error(p)
+	}
+	if count < 10 {
+		t.Errorf("checked %d examples, want every app in examples/", count)
+	}
+}
+
+func TestCheckReportsProblems(t *testing.T) {
+	problems := Check("bad", "app Bad is a web application\n\ndata Post:\n  belongs to a Widget\n")
+	if len(problems) != 1 || problems[0].Source != "bad" || !strings.Contains(problems[0].Message, "Widget") {
+		t.Errorf("Check = %v, want the unknown Widget", problems)
+	}
+	if problems := Check("bad", "data Post\n  has a title which is text\n"); len(problems) == 0 {
+		t.Error("expected a syntax error for the missing colon")
+	}
+}
+
+func TestCheckExamplesMergesDirectories(t *testing.T) {
+	fsys := fstest.MapFS{
+		"multi/app.human":  {Data: []byte("app Multi is a web application\n\nbuild with:\n  frontend using React\n  database using PostgreSQL\n")},
+		"multi/data.human": {Data: []byte("data Post:\n  has a title which is text\n")},
+		"multi/ui.human":   {Data: []byte("page Home:\n  show a list of posts\n")},
+		"single.human":     {Data: []byte("app Single is a web application\n\ndata Post:\n  belongs to a Widget\n")},
+	}
+	problems, count, err := CheckExamples(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("count = %d, want 2 apps", count)
+	}
+	if len(problems) != 1 || problems[0].Source != "example single" {
+		t.Errorf("problems = %v, want only single's", problems)
+	}
+}
+
+func TestInContext(t *testing.T) {
+	source := inContext(syntax.CatData, "has many Tag through PostTag")
+	if !strings.Contains(source, "data Post:\n  belongs to a User\n  has a title which is text\n  has many Tag through PostTag\n") {
+		t.Errorf("expected the statement in the context's Post:\n%s", source)
+	}
+	source = inContext(syntax.CatData, "data User:")
+	if strings.Count(source, "data User:") != 1 || !strings.Contains(source, "data User:\n  has a title") {
+		t.Errorf("expected the example's User to replace the context's:\n%s", source)
+	}
+	if source := inContext(syntax.CatArchitecture, "owns User"); !strings.Contains(source, "  service Example:\n    owns User\n") {
+		t.Errorf("expected the statement indented into the service:\n%s", source)
+	}
+}
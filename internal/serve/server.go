@@ -0,0 +1,412 @@
+// Package serve exposes the compiler as an HTTP API (human serve), so web
+// frontends, internal platforms, and CI jobs can check and build .human
+// sources without installing the CLI.
+package serve
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/barun-bash/human/internal/analyzer"
+	"github.com/barun-bash/human/internal/build"
+	cerr "github.com/barun-bash/human/internal/errors"
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/llm"
+	"github.com/barun-bash/human/internal/parser"
+	"github.com/barun-bash/human/internal/version"
+)
+
+// maxRequestBytes caps request bodies; .human sources are small.
+const maxRequestBytes = 1 << 20
+
+// Options configures a build server.
+type Options struct {
+	Token  string         // bearer token required on every /v1 request
+	Ask    *llm.Connector // nil turns /v1/ask off
+	Logger *log.Logger    // request log; nil discards it
+}
+
+// NewToken returns a random bearer token, for servers started without one.
+func NewToken() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Server answers check, build, and ask requests.
+type Server struct {
+	opts Options
+
+	// Generators aren't written to run concurrently, so builds take turns.
+	buildMu sync.Mutex
+}
+
+// New returns a server for opts.
+func New(opts Options) *Server {
+	if opts.Logger == nil {
+		opts.Logger = log.New(io.Discard, "", 0)
+	}
+	return &Server{opts: opts}
+}
+
+// Handler returns the API:
+//
+//	GET  /health     liveness, no token needed
+//	POST /v1/check   diagnostics and the Intent IR as JSON
+//	POST /v1/build   the generated code as a zip
+//	POST /v1/ask     .human source generated from a description
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.Handle("/v1/check", s.authorized(http.MethodPost, s.handleCheck))
+	mux.Handle("/v1/build", s.authorized(http.MethodPost, s.handleBuild))
+	mux.Handle("/v1/ask", s.authorized(http.MethodPost, s.handleAsk))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		mux.ServeHTTP(rec, r)
+		s.opts.Logger.Printf("%s %s %d %s", r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// statusRecorder remembers the response status for the request log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// authorized wraps next so it only runs for method with the server's
+// bearer token.
+func (s *Server) authorized(method string, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || s.opts.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("use %s", method))
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBytes)
+		next(w, r)
+	})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok", "version": version.Info()})
+}
+
+// SourceRequest is the body of /v1/check and /v1/build: either a single
+// file as Source, or a multi-file project as Files (file name → content).
+type SourceRequest struct {
+	Source string            `json:"source,omitempty"`
+	Files  map[string]string `json:"files,omitempty"`
+}
+
+// Diagnostic is a compiler error or warning.
+type Diagnostic struct {
+	Severity   string `json:"severity"` // "error", "warning", or "hint"
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message"`
+	File       string `json:"file,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// CheckResponse is the body /v1/check returns, and /v1/build when the
+// sources have errors. IR is left out when the sources don't parse.
+type CheckResponse struct {
+	Valid       bool            `json:"valid"`
+	Diagnostics []Diagnostic    `json:"diagnostics"`
+	IR          json.RawMessage `json:"ir,omitempty"`
+}
+
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	app, resp, status := s.compile(w, r)
+	if app == nil && resp == nil {
+		return
+	}
+	writeJSON(w, status, resp)
+}
+
+func (s *Server) handleBuild(w http.ResponseWriter, r *http.Request) {
+	app, resp, status := s.compile(w, r)
+	if app == nil && resp == nil {
+		return
+	}
+	if !resp.Valid {
+		writeJSON(w, status, resp)
+		return
+	}
+
+	// The build's project is a fresh directory, so it runs with the default
+	// config rather than the server's, and without the server's plugins.
+	projectDir, err := os.MkdirTemp("", "human-serve-build-*")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer os.RemoveAll(projectDir)
+	outputDir := filepath.Join(projectDir, "output")
+
+	// Without a terminal to prompt on, human build uses these ports too.
+	if app.Config == nil {
+		app.Config = &ir.BuildConfig{}
+	}
+	if app.Config.Ports == (ir.PortConfig{}) {
+		app.Config.Ports = ir.PortConfig{Frontend: 3000, Backend: 3001, Database: 5432}
+	}
+
+	s.buildMu.Lock()
+	_, _, _, err = build.RunGeneratorsIsolated(app, outputDir, projectDir)
+	s.buildMu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "build failed: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", zipName(app.Name)))
+	if err := writeZip(w, outputDir); err != nil {
+		s.opts.Logger.Printf("writing build zip: %v", err)
+	}
+}
+
+// compile parses and analyzes the request's sources. On a bad request it
+// writes the error and returns nils; otherwise it returns the IR (nil when
+// the sources don't parse), the check response, and its status code.
+func (s *Server) compile(w http.ResponseWriter, r *http.Request) (*ir.Application, *CheckResponse, int) {
+	var req SourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return nil, nil, 0
+	}
+	files := req.Files
+	if req.Source != "" {
+		if len(files) > 0 {
+			writeError(w, http.StatusBadRequest, "send either source or files, not both")
+			return nil, nil, 0
+		}
+		files = map[string]string{"app.human": req.Source}
+	}
+	if len(files) == 0 {
+		writeError(w, http.StatusBadRequest, "'source' or 'files' is required")
+		return nil, nil, 0
+	}
+
+	dir, err := os.MkdirTemp("", "human-serve-src-*")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, nil, 0
+	}
+	defer os.RemoveAll(dir)
+	var paths []string
+	for name, content := range files {
+		if name != filepath.Base(name) || !strings.HasSuffix(name, ".human") {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid file name %q: use a plain name ending in .human", name))
+			return nil, nil, 0
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return nil, nil, 0
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// Messages name the temporary files; clients know them by base name.
+	trim := func(msg string) string {
+		return strings.ReplaceAll(msg, dir+string(filepath.Separator), "")
+	}
+	failed := func(err error) (*ir.Application, *CheckResponse, int) {
+		return nil, &CheckResponse{
+			Diagnostics: []Diagnostic{{Severity: "error", Message: trim(err.Error())}},
+		}, http.StatusUnprocessableEntity
+	}
+
+	programs, _, err := parser.ParseProject(dir, paths)
+	if syntax, ok := err.(*parser.Errors); ok {
+		resp := &CheckResponse{Diagnostics: []Diagnostic{}}
+		for _, e := range syntax.Diagnostics {
+			resp.Diagnostics = append(resp.Diagnostics, diagnostic(e, trim))
+		}
+		return nil, resp, http.StatusUnprocessableEntity
+	}
+	if err != nil {
+		return failed(err)
+	}
+	prog := programs[0]
+	if len(programs) > 1 {
+		if prog, err = parser.MergePrograms(programs); err != nil {
+			return failed(err)
+		}
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		return failed(fmt.Errorf("IR build error: %w", err))
+	}
+	errs := analyzer.Analyze(app, paths[0])
+
+	resp := &CheckResponse{Valid: !errs.HasErrors(), Diagnostics: []Diagnostic{}}
+	for _, e := range errs.All() {
+		resp.Diagnostics = append(resp.Diagnostics, diagnostic(e, trim))
+	}
+	if data, err := ir.ToJSON(app); err == nil {
+		resp.IR = data
+	}
+	status := http.StatusOK
+	if !resp.Valid {
+		status = http.StatusUnprocessableEntity
+	}
+	return app, resp, status
+}
+
+// diagnostic converts a compiler diagnostic for a response, trimming the
+// temporary directory from its message.
+func diagnostic(e *cerr.CompilerError, trim func(string) string) Diagnostic {
+	return Diagnostic{
+		Severity:   severityName(e.Severity),
+		Code:       e.Code,
+		Message:    trim(e.Message),
+		File:       filepath.Base(e.File),
+		Line:       e.Line,
+		Suggestion: e.Suggestion,
+	}
+}
+
+func severityName(s cerr.Severity) string {
+	switch s {
+	case cerr.SeverityWarning:
+		return "warning"
+	case cerr.SeverityHint:
+		return "hint"
+	}
+	return "error"
+}
+
+// AskRequest is the body of /v1/ask.
+type AskRequest struct {
+	Description string `json:"description"`
+}
+
+// AskResponse is the body /v1/ask returns.
+type AskResponse struct {
+	Source     string `json:"source"`
+	Valid      bool   `json:"valid"`
+	ParseError string `json:"parse_error,omitempty"`
+}
+
+func (s *Server) handleAsk(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Ask == nil {
+		writeError(w, http.StatusServiceUnavailable, "no LLM provider is configured on this server")
+		return
+	}
+	var req AskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Description) == "" {
+		writeError(w, http.StatusBadRequest, "'description' is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	defer cancel()
+	result, err := s.opts.Ask.Ask(ctx, req.Description)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, AskResponse{Source: result.Code, Valid: result.Valid, ParseError: result.ParseError})
+}
+
+// zipName is the download name for an app's build output.
+func zipName(app string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r == ' ':
+			return '-'
+		}
+		return -1
+	}, app)
+	if name == "" {
+		name = "app"
+	}
+	return strings.ToLower(name) + ".zip"
+}
+
+// writeZip writes every file under dir to w as a zip archive.
+func writeZip(w io.Writer, dir string) error {
+	zw := zip.NewWriter(w)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		header.Method = zip.Deflate
+		dst, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(dst, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]any{"error": msg})
+}
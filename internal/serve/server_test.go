@@ -0,0 +1,256 @@
+package serve
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/config"
+)
+
+const testToken = "secret"
+
+const testSource = `app Clinic is a web application
+
+data Patient:
+  has a name which is text
+
+api CreatePatient:
+  accepts name
+  create a Patient with the given name
+  respond with the created patient
+
+page Home:
+  show a list of patients
+
+build with:
+  frontend using React with TypeScript
+  backend using Node with Express
+  database using PostgreSQL
+`
+
+func post(t *testing.T, h http.Handler, path, token string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	h := New(Options{Token: testToken}).Handler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("/health = %d, want 200 without a token", rec.Code)
+	}
+
+	for _, token := range []string{"", "wrong"} {
+		if rec := post(t, h, "/v1/check", token, SourceRequest{Source: testSource}); rec.Code != http.StatusUnauthorized {
+			t.Errorf("token %q: status %d, want 401", token, rec.Code)
+		}
+	}
+
+	// A server without a token accepts no one.
+	open := New(Options{}).Handler()
+	if rec := post(t, open, "/v1/check", "", SourceRequest{Source: testSource}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("tokenless server: status %d, want 401", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/check", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /v1/check = %d, want 405", rec.Code)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	h := New(Options{Token: testToken}).Handler()
+
+	rec := post(t, h, "/v1/check", testToken, SourceRequest{Source: testSource})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var resp CheckResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Valid {
+		t.Errorf("valid source reported invalid: %+v", resp.Diagnostics)
+	}
+	var app struct{ Name string }
+	if err := json.Unmarshal(resp.IR, &app); err != nil || app.Name != "Clinic" {
+		t.Errorf("IR name = %q (%v), want Clinic", app.Name, err)
+	}
+
+	// An undefined model is an analyzer error.
+	broken := strings.Replace(testSource, "create a Patient", "create a Doctor", 1)
+	rec = post(t, h, "/v1/check", testToken, SourceRequest{Files: map[string]string{"clinic.human": broken}})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("broken source: status %d, want 422", rec.Code)
+	}
+	resp = CheckResponse{}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Valid || len(resp.Diagnostics) == 0 {
+		t.Fatalf("broken source: %+v, want errors", resp)
+	}
+	for _, d := range resp.Diagnostics {
+		if strings.Contains(d.Message, "human-serve-src") || strings.Contains(d.File, "/") {
+			t.Errorf("diagnostic names a temporary path: %+v", d)
+		}
+	}
+
+	// Syntax errors come back together, each with its line.
+	rec = post(t, h, "/v1/check", testToken, SourceRequest{Source: "app Clinic is a web application\n\ndata Patient\n  has a name\n\npage Home\n  show a heading\n"})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("syntax errors: status %d, want 422", rec.Code)
+	}
+	resp = CheckResponse{}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Diagnostics) != 2 || resp.Diagnostics[0].Code != "E001" || resp.Diagnostics[0].Line != 3 || resp.Diagnostics[1].Line != 6 {
+		t.Errorf("syntax errors: %+v, want E001 on lines 3 and 6", resp.Diagnostics)
+	}
+
+	rec = post(t, h, "/v1/check", testToken, SourceRequest{Files: map[string]string{"../app.human": testSource}})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("path in file name: status %d, want 400", rec.Code)
+	}
+}
+
+func TestCheckRejectsImportsOutsideTheUpload(t *testing.T) {
+	h := New(Options{Token: testToken}).Handler()
+
+	secret := filepath.Join(t.TempDir(), "secret.human")
+	if err := os.WriteFile(secret, []byte("data Secret:\n  has a key which is text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, imp := range []string{secret, "../secret.human", "models/../../secret.human"} {
+		src := testSource + "\nimport \"" + imp + "\"\n"
+		rec := post(t, h, "/v1/check", testToken, SourceRequest{Source: src})
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("import %q: status %d, want 422", imp, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "Secret") {
+			t.Errorf("import %q: response includes the imported model: %s", imp, rec.Body)
+		}
+		if !strings.Contains(rec.Body.String(), "outside the project directory") {
+			t.Errorf("import %q: %s, want an outside the project error", imp, rec.Body)
+		}
+	}
+
+	// Imports between uploaded files still resolve.
+	rec := post(t, h, "/v1/check", testToken, SourceRequest{Files: map[string]string{
+		"app.human":    testSource + "\nimport \"models.human\"\n",
+		"models.human": "data Doctor:\n  has a name which is text\n",
+	}})
+	if rec.Code != http.StatusOK {
+		t.Errorf("import between uploaded files: status %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestBuildReturnsZip(t *testing.T) {
+	h := New(Options{Token: testToken}).Handler()
+
+	rec := post(t, h, "/v1/build", testToken, SourceRequest{Source: testSource})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("Content-Type = %q, want application/zip", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "clinic.zip") {
+		t.Errorf("Content-Disposition = %q, want clinic.zip", cd)
+	}
+
+	body, _ := io.ReadAll(rec.Body)
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, f := range zr.File {
+		names[f.Name] = true
+	}
+	for _, want := range []string{"node/package.json", "react/package.json"} {
+		if !names[want] {
+			t.Errorf("zip is missing %s", want)
+		}
+	}
+
+	rec = post(t, h, "/v1/build", testToken, SourceRequest{Source: "data:\n"})
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"valid":false`) {
+		t.Errorf("unparseable source: status %d, body %s", rec.Code, rec.Body)
+	}
+}
+
+func TestAskWithoutProvider(t *testing.T) {
+	h := New(Options{Token: testToken}).Handler()
+	rec := post(t, h, "/v1/ask", testToken, AskRequest{Description: "a todo app"})
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status %d, want 503", rec.Code)
+	}
+}
+
+func TestBuildIgnoresServerPluginsAndConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake plugin is a shell script")
+	}
+	h := New(Options{Token: testToken}).Handler()
+
+	// The server's working directory turns React off and a plugin on, and
+	// the plugin, on its PATH, leaves a marker when it runs.
+	t.Chdir(t.TempDir())
+	off, on := false, true
+	cfg := &config.Config{Plugins: []*config.PluginConfig{{Name: "react", Enabled: &off}, {Name: "evil", Enabled: &on}}}
+	if err := config.Save(".", cfg); err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	marker := filepath.Join(bin, "ran")
+	script := "#!/bin/sh\ntouch " + marker + "\necho '{\"files\":[{\"path\":\"x.txt\",\"content\":\"x\"}]}'\n"
+	if err := os.WriteFile(filepath.Join(bin, "human-gen-evil"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	rec := post(t, h, "/v1/build", testToken, SourceRequest{Source: testSource})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, f := range zr.File {
+		names[f.Name] = true
+	}
+	if !names["react/src/App.tsx"] {
+		t.Error("the server's config.json turned off the React generator for a served build")
+	}
+	if names["evil/x.txt"] {
+		t.Error("a served build ran a plugin from the server's PATH")
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("a served build ran a plugin from the server's PATH")
+	}
+}
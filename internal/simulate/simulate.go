@@ -0,0 +1,407 @@
+// Package simulate walks a workflow's steps against the IR without running
+// any code: the emails and messages it would send, the records it would
+// create, change, or delete, the policies it would assign, and the
+// integrations it would call, with what the IR can't back up flagged.
+package simulate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/barun-bash/human/internal/ir"
+)
+
+// Effect kinds, in the order a simulation's summary lists them.
+const (
+	Email       = "email"
+	Message     = "message"
+	Notify      = "notification"
+	Create      = "create"
+	Update      = "update"
+	Delete      = "delete"
+	Policy      = "policy"
+	Integration = "integration"
+	Other       = "other"
+)
+
+// Step is one workflow step and what it would do.
+type Step struct {
+	Text    string
+	After   string // the delay before the step's job runs, e.g. "48 hours"
+	Kind    string
+	Detail  string // what the step does, e.g. "creates a Comment record"
+	Target  string // the model, policy, template, or channel it acts on
+	Service string // the integration it calls, if any
+	Problem string // why the IR can't carry the step out as written
+	Starts  string // a workflow the step triggers, e.g. "a comment is created"
+}
+
+// Simulation is a workflow's dry run.
+type Simulation struct {
+	Trigger string
+	Model   *ir.DataModel // the model the trigger is about, if any
+	Steps   []Step
+}
+
+// Problems returns the steps the IR can't carry out as written.
+func (s *Simulation) Problems() []Step {
+	var out []Step
+	for _, st := range s.Steps {
+		if st.Problem != "" {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// Effects returns the distinct targets of the steps of a kind, in order.
+func (s *Simulation) Effects(kind string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, st := range s.Steps {
+		if st.Kind == kind && st.Target != "" && st.Problem == "" && !seen[st.Target] {
+			seen[st.Target] = true
+			out = append(out, st.Target)
+		}
+	}
+	return out
+}
+
+// Services returns the integrations the workflow calls, in order.
+func (s *Simulation) Services() []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, st := range s.Steps {
+		if st.Service != "" && !seen[st.Service] {
+			seen[st.Service] = true
+			out = append(out, st.Service)
+		}
+	}
+	return out
+}
+
+// Find returns the workflow whose trigger best matches event, e.g. "a user
+// signs up" or "user signs up". An event matching no trigger, or several
+// equally, is an error naming the triggers.
+func Find(app *ir.Application, event string) (*ir.Workflow, error) {
+	if len(app.Workflows) == 0 {
+		return nil, fmt.Errorf("the app has no workflows")
+	}
+	want := ir.JobName(strings.TrimPrefix(strings.TrimSpace(event), "when "))
+	wantWords := strings.Split(want, "-")
+
+	var best []*ir.Workflow
+	bestScore := 0
+	for _, wf := range app.Workflows {
+		name := ir.JobName(wf.Trigger)
+		if name == want {
+			return wf, nil
+		}
+		score := 0
+		words := map[string]bool{}
+		for _, w := range strings.Split(name, "-") {
+			words[w] = true
+		}
+		for _, w := range wantWords {
+			if words[w] {
+				score++
+			}
+		}
+		switch {
+		case score > bestScore:
+			best, bestScore = []*ir.Workflow{wf}, score
+		case score == bestScore && score > 0:
+			best = append(best, wf)
+		}
+	}
+	// Most of the words asked for must match.
+	if len(best) == 1 && bestScore*2 > len(wantWords) {
+		return best[0], nil
+	}
+	if len(best) > 1 && bestScore*2 > len(wantWords) {
+		return nil, fmt.Errorf("%q matches more than one workflow:\n%s", event, strings.Join(triggers(best), "\n"))
+	}
+	return nil, fmt.Errorf("no workflow matches %q. The app's workflows:\n%s", event, strings.Join(triggers(app.Workflows), "\n"))
+}
+
+func triggers(wfs []*ir.Workflow) []string {
+	var out []string
+	for _, wf := range wfs {
+		out = append(out, "  when "+wf.Trigger)
+	}
+	return out
+}
+
+// Run simulates wf against app.
+func Run(app *ir.Application, wf *ir.Workflow) *Simulation {
+	sim := &Simulation{Trigger: wf.Trigger, Model: findModel(app, wf.Trigger)}
+	for _, job := range ir.WorkflowJobs(wf) {
+		for _, action := range job.Steps {
+			st := simulateStep(app, sim.Model, action)
+			st.After = job.After
+			sim.Steps = append(sim.Steps, st)
+		}
+	}
+	return sim
+}
+
+var (
+	// Quotes around values are gone from step text by the time it's in the
+	// IR: `with template "welcome"` is "with template welcome".
+	templatePattern = regexp.MustCompile(`(?i)\btemplate\s+"?([\w.-]+)"?`)
+	valuePattern    = regexp.MustCompile(`(?i)\bwith\s+(\w+)\s+"?([\w.-]+)"?`)
+	smsPattern      = regexp.MustCompile(`(?i)\b(sms|text message|whatsapp)\b`)
+	recipientAfter  = regexp.MustCompile(`(?i)\b(?:notify|to)\s+(.+?)(?:\s+(?:via|with|about|that|when|if)\b|,|$)`)
+)
+
+func simulateStep(app *ir.Application, model *ir.DataModel, action *ir.Action) Step {
+	text := action.Text
+	lower := strings.ToLower(text)
+	st := Step{Text: text, Kind: Other}
+	integ := mentionedIntegration(app, lower)
+	if integ != nil {
+		st.Service = integ.Service
+	}
+
+	switch {
+	case action.Type == "send" || action.Type == "alert" || strings.HasPrefix(lower, "notify "):
+		recipient := ""
+		if m := recipientAfter.FindStringSubmatch(text); m != nil {
+			recipient = " to " + m[1]
+		}
+		switch {
+		case hasWord(lower, "email"):
+			st.Kind = Email
+			st.Target = "email" + recipient
+			template := ""
+			if m := templatePattern.FindStringSubmatch(text); m != nil {
+				template = m[1]
+				st.Target = template + " template"
+			}
+			via := integrationOfType(app, "email")
+			if via == nil {
+				st.Problem = "sends email, but no email integration is declared"
+				break
+			}
+			st.Service = via.Service
+			st.Detail = fmt.Sprintf("sends an email%s via %s", recipient, via.Service)
+			if template != "" {
+				st.Detail += " using the " + template + " template"
+				if len(via.Templates) > 0 && !contains(via.Templates, template) {
+					st.Problem = fmt.Sprintf("%s has no template %q", via.Service, template)
+				}
+			}
+		case smsPattern.MatchString(lower):
+			st.Kind = Message
+			st.Target = "SMS"
+			via := integrationOfType(app, "sms")
+			if via == nil {
+				st.Problem = "sends an SMS, but no SMS integration is declared"
+				break
+			}
+			st.Service = via.Service
+			st.Detail = fmt.Sprintf("sends a text message%s via %s", recipient, via.Service)
+		case integ != nil && integ.Type == "messaging" || hasWord(lower, "slack"):
+			st.Kind = Message
+			via := integ
+			if via == nil || via.Type != "messaging" {
+				via = integrationOfType(app, "messaging")
+			}
+			if via == nil {
+				st.Target = "Slack"
+				st.Problem = "posts to Slack, but no messaging integration is declared"
+				break
+			}
+			st.Service = via.Service
+			st.Target = via.Service
+			st.Detail = fmt.Sprintf("posts a message%s to %s", recipient, via.Service)
+		default:
+			st.Kind = Notify
+			st.Target = strings.TrimPrefix(recipient, " to ")
+			if st.Target == "" {
+				st.Target = "the user"
+			}
+			st.Detail = "sends an in-app notification to " + st.Target
+		}
+
+	case action.Type == "create" || action.Type == "update" || action.Type == "delete":
+		st.Kind = action.Type
+		m := findModel(app, text)
+		if m == nil {
+			m = model
+		}
+		if m == nil {
+			st.Problem = "names no data model to " + action.Type
+			break
+		}
+		st.Target = m.Name
+		verb := map[string]string{Create: "creates a", Update: "updates the", Delete: "deletes the"}[action.Type]
+		st.Detail = fmt.Sprintf("%s %s record", verb, m.Name)
+		if v := valuePattern.FindStringSubmatch(text); v != nil && m.FieldNamed(v[1]) != nil {
+			st.Problem = checkValue(m.FieldNamed(v[1]), m.Name, v[2])
+			if st.Problem == "" {
+				st.Detail += fmt.Sprintf(" with %s %q", v[1], v[2])
+			}
+		}
+		if action.Type == Create {
+			st.Starts = startedBy(app, st.Target, "created")
+		}
+
+	case action.Type == "assign" && hasWord(lower, "policy") || action.Type == "assign" && hasWord(lower, "role"):
+		st.Kind = Policy
+		p := findPolicy(app, text)
+		if p == nil {
+			st.Problem = "assigns a policy the app doesn't declare"
+			break
+		}
+		st.Target = p.Name
+		st.Detail = fmt.Sprintf("assigns the %s policy (%d permission%s, %d restriction%s)",
+			p.Name, len(p.Permissions), plural(len(p.Permissions)), len(p.Restrictions), plural(len(p.Restrictions)))
+
+	case integ != nil:
+		st.Kind = Integration
+		st.Target = integ.Service
+		st.Detail = "calls " + integ.Service
+		if integ.Purpose != "" {
+			st.Detail += ", used for " + integ.Purpose
+		}
+
+	case action.Type == "condition":
+		cond, _, _ := strings.Cut(lower, ",")
+		st.Detail = "checks whether " + strings.TrimSpace(strings.TrimPrefix(cond, "if "))
+	case action.Type == "log":
+		st.Detail = "logs it"
+	case action.Type == "retry":
+		st.Detail = "retries on failure"
+	default:
+		st.Detail = "left to the generated code's TODO"
+	}
+	return st
+}
+
+// checkValue checks a value a step sets on a model's field is one it
+// allows.
+func checkValue(f *ir.DataField, model, value string) string {
+	if f.Type == "enum" && !contains(f.EnumValues, value) {
+		return fmt.Sprintf("%s.%s can't be %q: it's one of %s", model, f.Name, value, strings.Join(f.EnumValues, ", "))
+	}
+	return ""
+}
+
+// startedBy returns the trigger of a workflow that runs when a model's
+// record is created, e.g. "a comment is created".
+func startedBy(app *ir.Application, model, verb string) string {
+	for _, wf := range app.Workflows {
+		if m := findModel(app, wf.Trigger); m != nil && m.Name == model && hasWord(strings.ToLower(wf.Trigger), verb) {
+			return wf.Trigger
+		}
+	}
+	return ""
+}
+
+// findModel returns the model text names, preferring the longest name;
+// plurals count ("tasks" names Task).
+func findModel(app *ir.Application, text string) *ir.DataModel {
+	tokens := tokenize(text)
+	var best *ir.DataModel
+	bestLen := 0
+	for _, m := range app.Data {
+		mw := tokenize(splitCamel(m.Name))
+		for i := 0; i+len(mw) <= len(tokens); i++ {
+			if matches(tokens[i:i+len(mw)], mw) && len(mw) > bestLen {
+				best, bestLen = m, len(mw)
+			}
+		}
+	}
+	return best
+}
+
+func findPolicy(app *ir.Application, text string) *ir.Policy {
+	tokens := tokenize(text)
+	for _, p := range app.Policies {
+		for _, t := range tokens {
+			if t == strings.ToLower(p.Name) {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// mentionedIntegration returns the integration text names by service.
+func mentionedIntegration(app *ir.Application, lower string) *ir.Integration {
+	for _, integ := range app.Integrations {
+		if hasWord(lower, strings.ToLower(integ.Service)) {
+			return integ
+		}
+	}
+	return nil
+}
+
+func integrationOfType(app *ir.Application, kind string) *ir.Integration {
+	for _, integ := range app.Integrations {
+		if integ.Type == kind {
+			return integ
+		}
+	}
+	return nil
+}
+
+func matches(tokens, words []string) bool {
+	for i, w := range words {
+		t := tokens[i]
+		if t == w {
+			continue
+		}
+		if i == len(words)-1 && (t == w+"s" || t == w+"es" || strings.HasSuffix(w, "y") && t == w[:len(w)-1]+"ies") {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func hasWord(lower, word string) bool {
+	for _, t := range tokenize(lower) {
+		if t == word {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// splitCamel splits "OrderItem" into "Order Item".
+func splitCamel(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,124 @@
+package simulate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/ir"
+	"github.com/barun-bash/human/internal/parser"
+)
+
+const source = `app Shop is a web application
+
+data User:
+  has a name which is text
+  has an email which is unique email
+
+data Order:
+  belongs to a User
+  has a status which is either "placed" or "shipped"
+
+policy Customer:
+  can view their own orders
+  cannot delete orders
+
+when a user signs up:
+  create their account
+  assign Customer policy
+  send welcome email with template "welcome"
+  after 3 days, send a reminder email to the user
+
+when an order is placed:
+  create an Order with status "paid"
+  notify the team on Slack
+  assign Admin policy
+
+when an order is created:
+  log it
+
+integrate with SendGrid:
+  api key from environment variable SENDGRID_API_KEY
+  use for sending transactional emails
+`
+
+func loadApp(t *testing.T) *ir.Application {
+	t.Helper()
+	prog, err := parser.Parse(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := ir.Build(prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestFind(t *testing.T) {
+	app := loadApp(t)
+	for _, event := range []string{"a user signs up", "when a user signs up", "User signs up", "user sign up"} {
+		wf, err := Find(app, event)
+		if err != nil || wf.Trigger != "a user signs up" {
+			t.Errorf("Find(%q) = %v, %v", event, wf, err)
+		}
+	}
+	if _, err := Find(app, "an invoice is paid"); err == nil || !strings.Contains(err.Error(), "when an order is placed") {
+		t.Errorf("expected an error listing the workflows, got %v", err)
+	}
+	if _, err := Find(app, "order"); err == nil || !strings.Contains(err.Error(), "more than one") {
+		t.Errorf("expected an ambiguous match, got %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	app := loadApp(t)
+	wf, _ := Find(app, "a user signs up")
+	sim := Run(app, wf)
+
+	if sim.Model == nil || sim.Model.Name != "User" {
+		t.Fatalf("the trigger is about a User, got %v", sim.Model)
+	}
+	if len(sim.Steps) != 4 {
+		t.Fatalf("got %d steps, want 4", len(sim.Steps))
+	}
+	if p := sim.Problems(); len(p) != 0 {
+		t.Errorf("unexpected problems: %+v", p)
+	}
+	if got := sim.Effects(Create); len(got) != 1 || got[0] != "User" {
+		t.Errorf("records created = %v, want the trigger's User", got)
+	}
+	if got := sim.Effects(Policy); len(got) != 1 || got[0] != "Customer" {
+		t.Errorf("policies assigned = %v", got)
+	}
+	if st := sim.Steps[2]; st.Kind != Email || st.Service != "SendGrid" || !strings.Contains(st.Detail, "welcome template") {
+		t.Errorf("welcome email step = %+v", st)
+	}
+	if st := sim.Steps[3]; st.After != "3 days" || st.Kind != Email {
+		t.Errorf("the reminder should be sent after 3 days, got %+v", st)
+	}
+	if got := sim.Services(); len(got) != 1 || got[0] != "SendGrid" {
+		t.Errorf("integrations = %v", got)
+	}
+}
+
+func TestRunProblems(t *testing.T) {
+	app := loadApp(t)
+	wf, _ := Find(app, "an order is placed")
+	sim := Run(app, wf)
+
+	var problems []string
+	for _, st := range sim.Problems() {
+		problems = append(problems, st.Problem)
+	}
+	want := []string{
+		`Order.status can't be "paid": it's one of placed, shipped`,
+		"posts to Slack, but no messaging integration is declared",
+		"assigns a policy the app doesn't declare",
+	}
+	if strings.Join(problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("problems = %q, want %q", problems, want)
+	}
+	if st := sim.Steps[0]; st.Starts != "an order is created" {
+		t.Errorf("creating an Order should start its workflow, got %q", st.Starts)
+	}
+}
@@ -27,8 +27,8 @@ const (
 
 // Pattern represents a single syntax pattern in the Human language.
 type Pattern struct {
-	Template    string   // "show a list of <data>"
-	Description string   // "Renders a collection of data items"
+	Template    string // "show a list of <data>"
+	Description string // "Renders a collection of data items"
 	Category    Category
 	Tags        []string // search tags
 	Example     string   // full usage example
@@ -319,6 +319,13 @@ var allPatterns = []Pattern{
 		Tags:        []string{"show", "text", "static", "content"},
 		Example:     `show "Welcome to TaskFlow"`,
 	},
+	{
+		Template:    `experiment "<name>" splits visitors <a>/<b> between <Page> and <Page> pages`,
+		Description: "A/B test variant pages with sticky bucketing and exposure logging",
+		Category:    CatPages,
+		Tags:        []string{"experiment", "a/b", "ab test", "split", "variant"},
+		Example:     `experiment "pricing-page" splits visitors 50/50 between Classic and NewPricing pages`,
+	},
 
 	// ── Components ──
 	{
@@ -461,6 +468,13 @@ var allPatterns = []Pattern{
 		Tags:        []string{"search", "filter", "bar", "find"},
 		Example:     "there is a search bar that filters transactions by title",
 	},
+	{
+		Template:    "remember the user's filters when they come back",
+		Description: "Restore a list's search, filters, sort, and scroll position",
+		Category:    CatForms,
+		Tags:        []string{"remember", "filters", "scroll", "restore", "back"},
+		Example:     "remember the user's filters when they come back",
+	},
 	{
 		Template:    "there is a dropdown to select <options>",
 		Description: "Add a dropdown/select input",
@@ -732,6 +746,35 @@ var allPatterns = []Pattern{
 		Tags:        []string{"backup", "retention", "keep", "policy"},
 		Example:     "keep backups for 30 days",
 	},
+	{
+		Template:    "use a read replica for heavy queries",
+		Description: "Send reads to a read replica",
+		Category:    CatDatabase,
+		Tags:        []string{"replica", "read", "scaling", "queries"},
+		Example:     "use a read replica for heavy queries",
+	},
+	{
+		Template:    "pool up to <number> connections",
+		Description: "Cap and pool database connections with PgBouncer",
+		Category:    CatDatabase,
+		Tags:        []string{"pool", "connections", "pgbouncer", "scaling"},
+		Example:     "pool up to 20 connections",
+	},
+	{
+		Template:    "seed <number> <Data> with <description>",
+		Description: "Fill the development database with sample records",
+		Category:    CatDatabase,
+		Tags:        []string{"seed", "sample", "fixtures", "faker", "development"},
+		Example:     "seed 50 Users with realistic names and emails",
+		Related:     []string{"seed:"},
+	},
+	{
+		Template:    "seed:",
+		Description: "Seed several data models, one count per line",
+		Category:    CatDatabase,
+		Tags:        []string{"seed", "sample", "fixtures", "block"},
+		Example:     "seed:",
+	},
 
 	// ── Workflows ──
 	{
@@ -749,6 +792,21 @@ var allPatterns = []Pattern{
 		Tags:        []string{"after", "delay", "schedule", "timer"},
 		Example:     `after 3 days, send email with template "getting-started"`,
 	},
+	{
+		Template:    "before a <Data> is <created|updated>, set its <field> to <value>",
+		Description: "Set a field whenever a record is written, whichever API writes it",
+		Category:    CatWorkflows,
+		Tags:        []string{"before", "hook", "lifecycle", "default", "model"},
+		Example:     `before a Task is created, set its status to "pending"`,
+		Related:     []string{"after a <Data> is deleted, remove their <Data>"},
+	},
+	{
+		Template:    "after a <Data> is deleted, remove their <Data>",
+		Description: "Delete the records belonging to a record when it is deleted",
+		Category:    CatWorkflows,
+		Tags:        []string{"after", "hook", "lifecycle", "cascade", "delete"},
+		Example:     "after a User is deleted, remove their Sessions",
+	},
 	{
 		Template:    "notify all <audience> of <event>",
 		Description: "Send notifications to a group",
@@ -756,6 +814,35 @@ var allPatterns = []Pattern{
 		Tags:        []string{"notify", "notification", "alert", "audience"},
 		Example:     "notify all followers of the author",
 	},
+	{
+		Template:    "notify the user in the app when their <Data> is <event>",
+		Description: "Store an in-app notification and show it in a notifications dropdown",
+		Category:    CatWorkflows,
+		Tags:        []string{"notify", "notification", "in-app", "unread", "bell"},
+		Example:     "notify the user in the app when their task is assigned",
+	},
+	{
+		Template:    "<who> can add a <Data> to their calendar",
+		Description: "Serve records as an ICS calendar feed with Add to calendar links",
+		Category:    CatWorkflows,
+		Tags:        []string{"calendar", "ics", "ical", "event", "subscribe"},
+		Example:     "users can add an Event to their calendar",
+	},
+	{
+		Template:    "generate a PDF <kind> from the <Data>",
+		Description: "Render a record as a themed PDF with a download endpoint",
+		Category:    CatWorkflows,
+		Tags:        []string{"pdf", "invoice", "receipt", "document"},
+		Example:     "generate a PDF invoice from the Order",
+	},
+	{
+		Template:    `send an SMS "<message>"`,
+		Description: "Text the endpoint's phone param through Twilio (or WhatsApp)",
+		Category:    CatWorkflows,
+		Tags:        []string{"sms", "text message", "whatsapp", "twilio", "phone"},
+		Example:     `send an SMS "Your order has shipped"`,
+		Related:     []string{"integrate with <Service>:"},
+	},
 	{
 		Template:    "assign <policy> policy",
 		Description: "Assign a policy/role in a workflow",
@@ -1053,9 +1140,37 @@ var allPatterns = []Pattern{
 		Template:    "deploy to <platform>",
 		Description: "Set the deployment platform",
 		Category:    CatBuild,
-		Tags:        []string{"deploy", "docker", "aws", "gcp", "vercel"},
+		Tags:        []string{"deploy", "docker", "aws", "gcp", "vercel", "netlify", "fly"},
 		Example:     "deploy to Docker",
 	},
+	{
+		Template:    "api style is <style>",
+		Description: "Serve the API as REST (default) or gRPC with a REST gateway",
+		Category:    CatBuild,
+		Tags:        []string{"api", "grpc", "protobuf", "rest", "gateway"},
+		Example:     "api style is gRPC",
+	},
+	{
+		Template:    "styling using <system>",
+		Description: "Write the frontend's styles with Tailwind, CSS modules, vanilla-extract, or styled-components",
+		Category:    CatBuild,
+		Tags:        []string{"styling", "css", "tailwind", "modules", "styled-components", "vanilla-extract"},
+		Example:     "styling using Tailwind",
+	},
+	{
+		Template:    "bundle budget is <n> KB per route",
+		Description: "Fail the build when a frontend route loads more generated code than this",
+		Category:    CatBuild,
+		Tags:        []string{"bundle", "budget", "size", "performance", "lazy", "code splitting"},
+		Example:     "bundle budget is 200 KB per route",
+	},
+	{
+		Template:    "validate requests against the OpenAPI spec",
+		Description: "Have the backend reject API requests that don't match openapi/openapi.yaml",
+		Category:    CatBuild,
+		Tags:        []string{"openapi", "validation", "validate", "contract", "schema", "middleware"},
+		Example:     "validate requests against the OpenAPI spec",
+	},
 
 	// ── Conditional ──
 	{
@@ -0,0 +1,300 @@
+package syntax
+
+import (
+	"sort"
+	"strings"
+
+	cerr "github.com/barun-bash/human/internal/errors"
+)
+
+// Snippet is a ready-to-paste block of Human code, with <placeholders> for
+// the names and values to fill in.
+type Snippet struct {
+	ID          string // "integrate-stripe"
+	Description string
+	Tags        []string
+	Code        string
+}
+
+// blocks are the full blocks of the patterns that open one, by template.
+// Other patterns' snippets are their template.
+var blocks = map[string]string{
+	"app <Name> is a <platform> application": `app <Name> is a web application
+
+build with:
+  frontend using React with TypeScript
+  backend using Node with Express
+  database using PostgreSQL`,
+	"data <Name>:": `data <Name>:
+  belongs to a <Data>
+  has a <field> which is text
+  has an optional <field> which is number
+  has a <field> which is either "<value>" or "<value>"
+  has a created datetime`,
+	"page <Name>:": `page <Name>:
+  show a list of <data>
+  each <item> shows its <field> and <field>
+  there is a search bar that filters <data> by <field>
+  clicking a <item> navigates to <Page>
+  if no <data> match, show "<message>"
+  while loading, show a spinner`,
+	"component <Name>:": `component <Name>:
+  accepts <prop> as <Data>
+  show the <field> in bold
+  show the <field> as a colored badge
+  clicking the card triggers on_click`,
+	"api <Name>:": `api <Name>:
+  requires authentication
+  accepts <field> and <field>
+  check that <field> is not empty
+  create a <Data> with the given fields
+  respond with the created <data>`,
+	"authentication:": `authentication:
+  method JWT tokens that expire in 7 days
+  passwords are hashed with bcrypt
+  rate limit all endpoints to 100 requests per minute per user
+  sanitize all text inputs against XSS`,
+	"policy <Name>:": `policy <Name>:
+  can create up to <limit> <data> per month
+  can view only their own <data>
+  can edit only their own <data>
+  cannot delete <data>`,
+	"database:": `database:
+  use PostgreSQL
+  index <Data> by <field>
+  backup daily at 3am
+  keep backups for 30 days`,
+	"seed:": `seed:
+  <number> <Data>
+  <number> <Data>`,
+	"when <event>:": `when <event>:
+  send <notification> to the user
+  after <delay>, send email with template "<template>"`,
+	"when a <Data> is <action>:": `when a <Data> is <action>:
+  send notification to the <Data> owner
+  log the <action> for analytics`,
+	"integrate with <Service>:": `integrate with <Service>:
+  api key from environment variable <VAR>
+  use for <purpose>`,
+	`integrate with custom api "<Name>":`: `integrate with custom api "<Name>":
+  base url from environment variable <VAR>
+  authentication using api key in header "<Header>"
+
+  endpoint <Name>:
+    method GET to /<path>/{<param>}
+    returns <field> as text and <field> as number`,
+	"endpoint <Name>:": `endpoint <Name>:
+  method <HTTP_METHOD> to /<path>
+  sends <field> and <field>
+  returns <field> as text`,
+	"architecture: microservices": `architecture: microservices
+  service <Name>:
+    handles <responsibilities>
+    owns <Data>
+    runs on port 3001
+    has its own database
+    talks to <Service>
+
+  gateway:
+    routes /api/<path> to <Name>
+
+  message broker using RabbitMQ`,
+	"service <Name>:": `service <Name>:
+  handles <responsibilities>
+  owns <Data>
+  runs on port <port>
+  has its own database
+  talks to <Service> to <purpose>`,
+	"gateway:": `gateway:
+  routes /api/<path> to <Service>
+  routes /api/<path> to <Service>`,
+	"when code is pushed to <branch>:": `when code is pushed to <branch>:
+  run all tests
+  check code formatting
+  check for security vulnerabilities
+  deploy to <environment>
+  if health checks fail, rollback automatically`,
+	"environment <name>:": `environment <name>:
+  url is <domain>`,
+	"theme:": `theme:
+  primary color is <color>
+  secondary color is <color>
+  font is <font> for body and <font> for headings
+  border radius is smooth
+  dark mode is supported
+  spacing is comfortable`,
+	"build with:": `build with:
+  frontend using <framework> with TypeScript
+  backend using <language> with <framework>
+  database using PostgreSQL
+  deploy to Docker`,
+	"if <service> is unreachable:": `if <service> is unreachable:
+  retry 3 times with 1 second delay
+  if still failing, respond with "<message>"
+  alert the <team> via Slack`,
+	"if an api request fails validation:": `if an api request fails validation:
+  respond with a clear message explaining what is wrong
+  log the attempt for analytics
+  do not reveal internal details`,
+}
+
+// integrationSnippets are the blocks integrating the services the
+// generators know, with the environment variables they read.
+var integrationSnippets = []Snippet{
+	{
+		ID:          "integrate-stripe",
+		Description: "Take payments with Stripe, with its webhook",
+		Tags:        []string{"stripe", "payment", "billing", "checkout", "webhook"},
+		Code: `integrate with Stripe:
+  api key from environment variable STRIPE_SECRET_KEY
+  webhook secret from environment variable STRIPE_WEBHOOK_SECRET
+  webhook endpoint is "/api/webhooks/stripe"
+  use for <purpose>`,
+	},
+	{
+		ID:          "integrate-sendgrid",
+		Description: "Send email with SendGrid",
+		Tags:        []string{"sendgrid", "email", "mail", "transactional"},
+		Code: `integrate with SendGrid:
+  api key from environment variable SENDGRID_API_KEY
+  sender email is "<address>"
+  template "<template>"
+  use for sending transactional emails`,
+	},
+	{
+		ID:          "integrate-aws-s3",
+		Description: "Store uploaded files in AWS S3",
+		Tags:        []string{"s3", "aws", "storage", "upload", "files"},
+		Code: `integrate with AWS S3:
+  api key from environment variable AWS_ACCESS_KEY
+  secret from environment variable AWS_SECRET_KEY
+  region is "<region>"
+  bucket is "<bucket>"
+  use for storing <files>`,
+	},
+	{
+		ID:          "integrate-cloudinary",
+		Description: "Store and transform images with Cloudinary",
+		Tags:        []string{"cloudinary", "storage", "images", "upload"},
+		Code: `integrate with Cloudinary:
+  api key from environment variable CLOUDINARY_API_KEY
+  secret from environment variable CLOUDINARY_SECRET
+  use for storing <files>`,
+	},
+	{
+		ID:          "integrate-slack",
+		Description: "Post notifications to a Slack channel",
+		Tags:        []string{"slack", "messaging", "notifications", "alerts"},
+		Code: `integrate with Slack:
+  api key from environment variable SLACK_WEBHOOK_URL
+  channel is "#<channel>"
+  use for team notifications and alerts`,
+	},
+	{
+		ID:          "integrate-twilio",
+		Description: "Send SMS and WhatsApp messages with Twilio",
+		Tags:        []string{"twilio", "sms", "whatsapp", "text", "phone"},
+		Code: `integrate with Twilio:
+  account sid from environment variable TWILIO_ACCOUNT_SID
+  auth token from environment variable TWILIO_AUTH_TOKEN
+  sender is "<phone number>"
+  template "<template>"`,
+	},
+	{
+		ID:          "integrate-posthog",
+		Description: "Track product analytics with PostHog",
+		Tags:        []string{"posthog", "analytics", "events", "tracking"},
+		Code: `integrate with PostHog:
+  api key from environment variable POSTHOG_API_KEY
+  use for product analytics`,
+	},
+}
+
+// Snippets returns a snippet for every pattern, then the integrations'.
+func Snippets() []Snippet {
+	snippets := make([]Snippet, 0, len(allPatterns)+len(integrationSnippets))
+	for _, p := range allPatterns {
+		code, ok := blocks[p.Template]
+		if !ok {
+			code = p.Template
+		}
+		snippets = append(snippets, Snippet{ID: PatternID(p), Description: p.Description, Tags: p.Tags, Code: code})
+	}
+	return append(snippets, integrationSnippets...)
+}
+
+// PatternID returns a pattern's snippet ID: its template's words, in
+// lowercase and joined by dashes, e.g. "data-name" for "data <Name>:".
+func PatternID(p Pattern) string {
+	return strings.Join(idWords(p.Template), "-")
+}
+
+// LookupSnippet returns the snippet with the given ID or, failing that,
+// the snippets whose IDs and tags best match the words of query, best
+// first. exact reports whether the first is the snippet named.
+func LookupSnippet(query string) (matches []Snippet, exact bool) {
+	words := idWords(query)
+	id := strings.Join(words, "-")
+	all := Snippets()
+	for _, s := range all {
+		if s.ID == id {
+			return []Snippet{s}, true
+		}
+	}
+	if len(words) == 0 {
+		return nil, false
+	}
+
+	var results []scoredSnippet
+	for _, s := range all {
+		if score := scoreSnippet(s, words); score >= 0.5 {
+			results = append(results, scoredSnippet{s, score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+	for _, r := range results {
+		matches = append(matches, r.snippet)
+	}
+	return matches, false
+}
+
+type scoredSnippet struct {
+	snippet Snippet
+	score   float64
+}
+
+// scoreSnippet scores how well a snippet's ID words and tags match the
+// words of a query, averaging each word's best match: 1.0 for a word of
+// the ID or a tag, 0.8 for one inside them, 0.6 for a near miss.
+func scoreSnippet(s Snippet, words []string) float64 {
+	keys := strings.Split(s.ID, "-")
+	for _, tag := range s.Tags {
+		keys = append(keys, idWords(tag)...)
+	}
+	total := 0.0
+	for _, w := range words {
+		best := 0.0
+		for _, k := range keys {
+			switch {
+			case k == w:
+				best = 1.0
+			case strings.Contains(k, w) && len(w) > 2 && best < 0.8:
+				best = 0.8
+			case cerr.Similarity(w, k) > 0.7 && best < 0.6:
+				best = 0.6
+			}
+		}
+		total += best
+	}
+	return total / float64(len(words))
+}
+
+// idWords returns the lowercase words of s, dropping punctuation and the
+// angle brackets around placeholders.
+func idWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
@@ -0,0 +1,85 @@
+package syntax
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/barun-bash/human/internal/parser"
+)
+
+func TestSnippetIDsAreUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, s := range Snippets() {
+		if s.ID == "" || seen[s.ID] {
+			t.Errorf("snippet ID %q is empty or duplicated (%q)", s.ID, s.Code)
+		}
+		seen[s.ID] = true
+	}
+}
+
+func TestBlocksMatchPatterns(t *testing.T) {
+	templates := make(map[string]bool)
+	for _, p := range AllPatterns() {
+		templates[p.Template] = true
+	}
+	for template := range blocks {
+		if !templates[template] {
+			t.Errorf("block for unknown template %q", template)
+		}
+	}
+}
+
+func TestBlockSnippetsParse(t *testing.T) {
+	placeholder := regexp.MustCompile(`<[^>]+>`)
+	for _, s := range Snippets() {
+		if !strings.Contains(s.Code, "\n") {
+			continue
+		}
+		code := placeholder.ReplaceAllStringFunc(s.Code, func(p string) string {
+			if p == "<HTTP_METHOD>" {
+				return "GET"
+			}
+			return "Item"
+		})
+		if strings.HasPrefix(code, "endpoint ") || strings.HasPrefix(code, "service ") || strings.HasPrefix(code, "gateway:") {
+			// These nest inside an integration or architecture block.
+			continue
+		}
+		if _, err := parser.Parse(code + "\n"); err != nil {
+			t.Errorf("snippet %s doesn't parse: %v\n%s", s.ID, err, code)
+		}
+	}
+}
+
+func TestLookupSnippetExact(t *testing.T) {
+	matches, exact := LookupSnippet("integrate-stripe")
+	if !exact || len(matches) != 1 || !strings.Contains(matches[0].Code, "STRIPE_WEBHOOK_SECRET") {
+		t.Errorf("LookupSnippet(integrate-stripe) = %v, %v", matches, exact)
+	}
+	if matches, exact := LookupSnippet("data <Name>:"); !exact || matches[0].ID != "data-name" {
+		t.Errorf("expected a template to look up its pattern, got %v", matches)
+	}
+}
+
+func TestLookupSnippetFuzzy(t *testing.T) {
+	for query, want := range map[string]string{
+		"stripe":          "integrate-stripe",
+		"payments-stripe": "integrate-stripe",
+		"sendgird":        "integrate-sendgrid",
+		"slack-alerts":    "integrate-slack",
+		"policy":          "policy-name",
+	} {
+		matches, exact := LookupSnippet(query)
+		if exact || len(matches) == 0 || matches[0].ID != want {
+			var ids []string
+			for _, m := range matches {
+				ids = append(ids, m.ID)
+			}
+			t.Errorf("LookupSnippet(%q) = %v, want %s first", query, ids, want)
+		}
+	}
+	if matches, _ := LookupSnippet("kubernetes-helm-chart"); len(matches) != 0 {
+		t.Errorf("expected no matches, got %d", len(matches))
+	}
+}
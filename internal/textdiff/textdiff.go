@@ -0,0 +1,224 @@
+// Package textdiff computes line diffs between two versions of a .human
+// source, split into hunks that can be accepted or rejected one at a time.
+// It serves the prompts asking whether to apply an LLM's change.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is what a diff line does: keep, remove, or add a line.
+type Op byte
+
+const (
+	Equal  Op = ' '
+	Delete Op = '-'
+	Insert Op = '+'
+)
+
+// Line is one line of a diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Hunk is a run of changes with the unchanged lines around them.
+type Hunk struct {
+	OldStart, OldLines int // 1-based, as in a unified diff header
+	NewStart, NewLines int
+	Lines              []Line
+
+	oldPos int // index of the hunk's first old line
+}
+
+// Header returns the hunk's unified diff header, e.g. "@@ -3,7 +3,8 @@".
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", span(h.OldStart, h.OldLines), span(h.NewStart, h.NewLines))
+}
+
+// span formats a range of a unified diff header. An empty range names the
+// line before it.
+func span(start, n int) string {
+	if n == 1 {
+		return fmt.Sprint(start)
+	}
+	if n == 0 {
+		start--
+	}
+	return fmt.Sprintf("%d,%d", start, n)
+}
+
+// Added and Removed count the hunk's inserted and deleted lines.
+func (h Hunk) Added() int   { return h.count(Insert) }
+func (h Hunk) Removed() int { return h.count(Delete) }
+
+func (h Hunk) count(op Op) int {
+	n := 0
+	for _, l := range h.Lines {
+		if l.Op == op {
+			n++
+		}
+	}
+	return n
+}
+
+// maxCells bounds the table the diff fills in. Past it, the differing
+// middle of the sources is shown as removed and re-added whole.
+const maxCells = 16 << 20
+
+// Diff returns the lines of the edit turning oldSrc into newSrc.
+func Diff(oldSrc, newSrc string) []Line {
+	a, b := split(oldSrc), split(newSrc)
+
+	// Lines the sources start and end with are kept as they are.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+
+	var lines []Line
+	for _, t := range a[:pre] {
+		lines = append(lines, Line{Equal, t})
+	}
+	lines = append(lines, middle(a[pre:len(a)-suf], b[pre:len(b)-suf])...)
+	for _, t := range a[len(a)-suf:] {
+		lines = append(lines, Line{Equal, t})
+	}
+	return lines
+}
+
+// middle diffs the lines between the common prefix and suffix by their
+// longest common subsequence.
+func middle(a, b []string) []Line {
+	var lines []Line
+	if len(a)*len(b) > maxCells {
+		for _, t := range a {
+			lines = append(lines, Line{Delete, t})
+		}
+		for _, t := range b {
+			lines = append(lines, Line{Insert, t})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Equal, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Delete, a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Insert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Delete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Insert, b[j]})
+	}
+	return lines
+}
+
+// Hunks groups the changes turning oldSrc into newSrc into hunks, each
+// with up to context unchanged lines on either side. Changes closer than
+// twice context share a hunk. Identical sources have none.
+func Hunks(oldSrc, newSrc string, context int) []Hunk {
+	lines := Diff(oldSrc, newSrc)
+
+	// oldAt[k] and newAt[k] are the indexes of line k in each source, or
+	// of the next line for a line the source doesn't have.
+	oldAt := make([]int, len(lines)+1)
+	newAt := make([]int, len(lines)+1)
+	for k, l := range lines {
+		oldAt[k+1], newAt[k+1] = oldAt[k], newAt[k]
+		if l.Op != Insert {
+			oldAt[k+1]++
+		}
+		if l.Op != Delete {
+			newAt[k+1]++
+		}
+	}
+
+	var hunks []Hunk
+	for k := 0; k < len(lines); {
+		if lines[k].Op == Equal {
+			k++
+			continue
+		}
+		start := max(k-context, 0)
+		// Extend over changes until context*2 unchanged lines separate them.
+		end, equal := k, 0
+		for end < len(lines) && (lines[end].Op != Equal || equal < 2*context) {
+			if lines[end].Op == Equal {
+				equal++
+			} else {
+				equal = 0
+			}
+			end++
+		}
+		// Trim the trailing unchanged lines back to context.
+		end -= max(equal-context, 0)
+
+		h := Hunk{
+			OldStart: oldAt[start] + 1, OldLines: oldAt[end] - oldAt[start],
+			NewStart: newAt[start] + 1, NewLines: newAt[end] - newAt[start],
+			Lines:  lines[start:end],
+			oldPos: oldAt[start],
+		}
+		hunks = append(hunks, h)
+		k = end
+	}
+	return hunks
+}
+
+// Apply returns oldSrc with the hunks accepted[i] is true for applied and
+// the rest left out. hunks must be Hunks of oldSrc, in order.
+func Apply(oldSrc string, hunks []Hunk, accepted []bool) string {
+	a := split(oldSrc)
+	var out []string
+	pos := 0
+	for i, h := range hunks {
+		out = append(out, a[pos:h.oldPos]...)
+		for _, l := range h.Lines {
+			if l.Op == Equal || (l.Op == Insert) == accepted[i] {
+				out = append(out, l.Text)
+			}
+		}
+		pos = h.oldPos + h.OldLines
+	}
+	out = append(out, a[pos:]...)
+	return strings.Join(out, "\n")
+}
+
+// split splits src into lines. A trailing newline leaves an empty last
+// line, so joining the lines with "\n" gives src back.
+func split(src string) []string {
+	return strings.Split(src, "\n")
+}
@@ -0,0 +1,90 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func render(lines []Line) string {
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(string(l.Op) + l.Text + "\n")
+	}
+	return b.String()
+}
+
+func TestDiff(t *testing.T) {
+	got := render(Diff("a\nb\nc\nd", "a\nc\nx\nd"))
+	want := " a\n-b\n c\n+x\n d\n"
+	if got != want {
+		t.Errorf("Diff =\n%s\nwant\n%s", got, want)
+	}
+	if got := render(Diff("same\n", "same\n")); got != " same\n \n" {
+		t.Errorf("identical sources should be all unchanged lines, got %q", got)
+	}
+}
+
+func TestHunks(t *testing.T) {
+	var old []string
+	for i := 1; i <= 20; i++ {
+		old = append(old, string(rune('a'+i-1)))
+	}
+	cur := append([]string{}, old...)
+	cur[1] = "B"                                                    // line 2
+	cur = append(cur[:15], append([]string{"new"}, cur[15:]...)...) // after line 15
+
+	hunks := Hunks(strings.Join(old, "\n"), strings.Join(cur, "\n"), 3)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2: %+v", len(hunks), hunks)
+	}
+	if h := hunks[0].Header(); h != "@@ -1,5 +1,5 @@" {
+		t.Errorf("first header = %q", h)
+	}
+	if h := hunks[1].Header(); h != "@@ -13,6 +13,7 @@" {
+		t.Errorf("second header = %q", h)
+	}
+	if hunks[0].Added() != 1 || hunks[0].Removed() != 1 || hunks[1].Added() != 1 || hunks[1].Removed() != 0 {
+		t.Errorf("counts: +%d -%d, +%d -%d", hunks[0].Added(), hunks[0].Removed(), hunks[1].Added(), hunks[1].Removed())
+	}
+
+	// Changes closer than twice the context share a hunk.
+	if hunks := Hunks("a\nb\nc\nd\ne", "A\nb\nc\nd\nE", 3); len(hunks) != 1 {
+		t.Errorf("got %d hunks for nearby changes, want 1", len(hunks))
+	}
+	if hunks := Hunks("x\n", "x\n", 3); len(hunks) != 0 {
+		t.Errorf("identical sources have no hunks, got %d", len(hunks))
+	}
+}
+
+func TestApply(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	cur := "A\nb\nc\nd\ne\nf\ng\nh\ni\n"
+	hunks := Hunks(old, cur, 1)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(hunks))
+	}
+	for _, tt := range []struct {
+		accepted []bool
+		want     string
+	}{
+		{[]bool{true, true}, cur},
+		{[]bool{false, false}, old},
+		{[]bool{true, false}, "A\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"},
+		{[]bool{false, true}, "a\nb\nc\nd\ne\nf\ng\nh\ni\n"},
+	} {
+		if got := Apply(old, hunks, tt.accepted); got != tt.want {
+			t.Errorf("Apply(%v) = %q, want %q", tt.accepted, got, tt.want)
+		}
+	}
+}
+
+func TestApplyInsertAtStart(t *testing.T) {
+	old, cur := "b\nc", "a\nb\nc"
+	hunks := Hunks(old, cur, 0)
+	if len(hunks) != 1 || hunks[0].Header() != "@@ -0,0 +1 @@" {
+		t.Fatalf("hunks = %+v", hunks)
+	}
+	if got := Apply(old, hunks, []bool{true}); got != cur {
+		t.Errorf("Apply = %q, want %q", got, cur)
+	}
+}
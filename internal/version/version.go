@@ -9,7 +9,7 @@ import (
 // Version, CommitSHA, and BuildDate are set via ldflags at build time.
 // Example: go build -ldflags "-X .../version.Version=1.0.0 -X .../version.CommitSHA=abc1234 -X .../version.BuildDate=2026-02-26"
 var (
-	Version  = "0.4.0"
+	Version   = "0.4.0"
 	CommitSHA = "dev"
 	BuildDate = "unknown"
 )
@@ -92,6 +92,62 @@ func IsNewerThan(latest, current string) bool {
 	return l.Compare(c) > 0
 }
 
+// IsNewerRelease is like IsNewerThan but also orders pre-releases:
+// "1.0.0-rc.2" is newer than "1.0.0-rc.1", and "1.0.0" is newer than both.
+func IsNewerRelease(latest, current string) bool {
+	l, err := Parse(latest)
+	if err != nil {
+		return false
+	}
+	c, err := Parse(current)
+	if err != nil {
+		return false
+	}
+	if cmp := l.Compare(c); cmp != 0 {
+		return cmp > 0
+	}
+	return comparePre(preRelease(latest), preRelease(current)) > 0
+}
+
+// preRelease returns the pre-release part of a version ("rc.1" in
+// "1.0.0-rc.1"), without any build metadata.
+func preRelease(s string) string {
+	_, pre, _ := strings.Cut(s, "-")
+	pre, _, _ = strings.Cut(pre, "+")
+	return pre
+}
+
+// comparePre compares pre-release identifiers by semver precedence. An empty
+// pre-release (a final release) sorts after any pre-release.
+func comparePre(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return cmpInt(an, bn)
+			}
+		case aErr == nil: // numeric identifiers sort before alphanumeric
+			return -1
+		case bErr == nil:
+			return 1
+		case as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return cmpInt(len(as), len(bs))
+}
+
 func cmpInt(a, b int) int {
 	if a < b {
 		return -1
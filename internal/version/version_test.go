@@ -91,6 +91,31 @@ func TestIsNewerThan(t *testing.T) {
 	}
 }
 
+func TestIsNewerRelease(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"0.5.0", "0.4.0", true},
+		{"0.4.0", "0.4.0", false},
+		{"1.0.0-rc.2", "1.0.0-rc.1", true},
+		{"1.0.0-rc.10", "1.0.0-rc.9", true},
+		{"1.0.0", "1.0.0-rc.3", true},
+		{"1.0.0-rc.1", "1.0.0", false},
+		{"1.0.0-rc.1", "1.0.0-beta.4", true},
+		{"1.0.0-beta", "1.0.0-beta.1", false},
+		{"0.9.0", "1.0.0-rc.1", false},
+		{"bad", "0.4.0", false},
+	}
+
+	for _, tt := range tests {
+		got := IsNewerRelease(tt.latest, tt.current)
+		if got != tt.want {
+			t.Errorf("IsNewerRelease(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
 func TestInfo_Dev(t *testing.T) {
 	// Save and restore package-level vars.
 	origSHA, origDate := CommitSHA, BuildDate